@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+)
+
+// APIKeyService calls the /api_keys endpoints.
+type APIKeyService struct {
+	c *Client
+}
+
+// List returns one page of the API keys matching filter.
+func (s *APIKeyService) List(ctx context.Context, filter APIKeyFilter) (*Page[APIKey], error) {
+	filter.PaginationRequestDTO = withPage(filter.PaginationRequestDTO)
+	return call[Page[APIKey]](ctx, s.c, http.MethodGet, "/api/v1/api_keys/", encodeQuery(filter), nil)
+}
+
+// All iterates over every API key matching filter, from filter.Page on.
+func (s *APIKeyService) All(ctx context.Context, filter APIKeyFilter) iter.Seq2[APIKey, error] {
+	return pages(ctx, filter.PaginationRequestDTO, func(ctx context.Context, p Pagination) (*Page[APIKey], error) {
+		filter.PaginationRequestDTO = p
+		return s.List(ctx, filter)
+	})
+}
+
+// Get returns an API key.
+func (s *APIKeyService) Get(ctx context.Context, apiKeyUUID uuid.UUID) (*APIKey, error) {
+	return call[APIKey](ctx, s.c, http.MethodGet, "/api/v1/api_keys/"+apiKeyUUID.String(), nil, nil)
+}
+
+// Create creates an API key. The key itself is only returned here.
+func (s *APIKeyService) Create(ctx context.Context, req APIKeyCreateRequest) (*APIKeyCreated, error) {
+	return call[APIKeyCreated](ctx, s.c, http.MethodPost, "/api/v1/api_keys/", nil, req)
+}
+
+// Update updates an API key.
+func (s *APIKeyService) Update(ctx context.Context, apiKeyUUID uuid.UUID, req APIKeyUpdateRequest) (*APIKey, error) {
+	return call[APIKey](ctx, s.c, http.MethodPut, "/api/v1/api_keys/"+apiKeyUUID.String(), nil, req)
+}
+
+// Delete deletes an API key.
+func (s *APIKeyService) Delete(ctx context.Context, apiKeyUUID uuid.UUID) (*APIKey, error) {
+	return call[APIKey](ctx, s.c, http.MethodDelete, "/api/v1/api_keys/"+apiKeyUUID.String(), nil, nil)
+}
+
+// SetStatus sets an API key's status: "active", "inactive" or "revoked".
+func (s *APIKeyService) SetStatus(ctx context.Context, apiKeyUUID uuid.UUID, status string) (*APIKey, error) {
+	return call[APIKey](ctx, s.c, http.MethodPut, "/api/v1/api_keys/"+apiKeyUUID.String()+"/status", nil,
+		dto.APIKeyStatusUpdateDTO{Status: status})
+}
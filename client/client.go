@@ -0,0 +1,182 @@
+// Package client is the Go SDK of the maintainerd auth REST API. It wraps the
+// management endpoints of the internal port with typed methods, attaches and
+// refreshes access tokens, and iterates over paginated lists:
+//
+//	c, err := client.New("https://auth-internal.example.com",
+//		client.WithTokenSource(client.StaticToken(os.Getenv("AUTH_PAT"))))
+//	if err != nil {
+//		return err
+//	}
+//	for user, err := range c.Users.All(ctx, client.UserFilter{}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(user.Username)
+//	}
+//
+// Request and response types are aliases of the server's DTOs, so they always
+// match the API of the same release.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds each request of a client created without
+// WithHTTPClient.
+const DefaultTimeout = 30 * time.Second
+
+// Client calls the REST API. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	tokens     TokenSource
+	userAgent  string
+
+	Users   *UserService
+	Roles   *RoleService
+	Clients *ClientService
+	APIKeys *APIKeyService
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sends requests with hc instead of a client with
+// DefaultTimeout.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithTokenSource authenticates every request with the access tokens of ts.
+func WithTokenSource(ts TokenSource) Option {
+	return func(c *Client) { c.tokens = ts }
+}
+
+// WithUserAgent sets the User-Agent header of every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// New creates a client of the API served at baseURL, the internal port's
+// address, e.g. "https://auth-internal.example.com".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: must be an absolute http(s) URL", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		userAgent:  "maintainerd-auth-go",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.Users = &UserService{c: c}
+	c.Roles = &RoleService{c: c}
+	c.Clients = &ClientService{c: c}
+	c.APIKeys = &APIKeyService{c: c}
+	return c, nil
+}
+
+// envelope is the body of every successful management response.
+type envelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Message string          `json:"message"`
+}
+
+// call sends an authenticated JSON request to path and decodes the data of
+// the response envelope into a new T. T is struct{} for responses without
+// data.
+func call[T any](ctx context.Context, c *Client, method, path string, query url.Values, body any) (*T, error) {
+	return callAs[T](ctx, c, true, method, path, query, body)
+}
+
+// callAs is call, sending the access token only when authenticate is set.
+func callAs[T any](ctx context.Context, c *Client, authenticate bool, method, path string, query url.Values, body any) (*T, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("encode request: %w", err)
+		}
+	}
+
+	res, err := c.send(ctx, method, path, query, payload, authenticate, true)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var env envelope
+	if err := json.NewDecoder(res.Body).Decode(&env); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	out := new(T)
+	if len(env.Data) > 0 && string(env.Data) != "null" {
+		if err := json.Unmarshal(env.Data, out); err != nil {
+			return nil, fmt.Errorf("decode response data: %w", err)
+		}
+	}
+	return out, nil
+}
+
+// send sends a request and returns the response when it succeeded. A 401 is
+// retried once with a fresh token when the token source can be invalidated.
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, payload []byte, authenticate, retry bool) (*http.Response, error) {
+	u := c.baseURL.JoinPath(path)
+	u.RawQuery = query.Encode()
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if authenticate && c.tokens != nil {
+		token, err := c.tokens.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get access token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	if res.StatusCode < 300 {
+		return res, nil
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusUnauthorized && authenticate && retry {
+		if inv, ok := c.tokens.(invalidator); ok {
+			inv.Invalidate()
+			return c.send(ctx, method, path, query, payload, true, false)
+		}
+	}
+	return nil, decodeError(res)
+}
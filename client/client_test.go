@@ -0,0 +1,222 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeData(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "data": data, "message": "ok"})
+}
+
+func TestNew(t *testing.T) {
+	for _, baseURL := range []string{"", "auth.example.com", "ftp://auth.example.com", "http://"} {
+		_, err := New(baseURL)
+		assert.Error(t, err, baseURL)
+	}
+
+	c, err := New("https://auth.example.com/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://auth.example.com", c.baseURL.String())
+	assert.NotNil(t, c.Users)
+	assert.NotNil(t, c.APIKeys)
+}
+
+func TestClient_Get(t *testing.T) {
+	userUUID := uuid.New()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/v1/users/"+userUUID.String(), r.URL.Path)
+		assert.Equal(t, "Bearer pat-123", r.Header.Get("Authorization"))
+		assert.Equal(t, "svc/1.0", r.Header.Get("User-Agent"))
+		writeData(w, http.StatusOK, map[string]any{"user_id": userUUID, "username": "alice"})
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL, WithTokenSource(StaticToken("pat-123")), WithUserAgent("svc/1.0"))
+	require.NoError(t, err)
+	user, err := c.Users.Get(context.Background(), userUUID)
+	require.NoError(t, err)
+	assert.Equal(t, userUUID, user.UserUUID)
+	assert.Equal(t, "alice", user.Username)
+}
+
+func TestClient_SendsBody(t *testing.T) {
+	roleUUID := uuid.New()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/v1/roles/"+roleUUID.String()+"/status", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]string{"status": "inactive"}, body)
+		writeData(w, http.StatusOK, map[string]any{"role_id": roleUUID, "status": "inactive"})
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+	role, err := c.Roles.SetStatus(context.Background(), roleUUID, "inactive")
+	require.NoError(t, err)
+	assert.Equal(t, "inactive", role.Status)
+}
+
+func TestClient_Error(t *testing.T) {
+	t.Run("problem", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"type":"about:blank","title":"Not Found","status":404,"detail":"User not found","code":"USR-3003","name":"user_not_found","success":false}`))
+		}))
+		defer srv.Close()
+
+		c, err := New(srv.URL)
+		require.NoError(t, err)
+		_, err = c.Users.Get(context.Background(), uuid.New())
+		require.Error(t, err)
+		assert.True(t, IsNotFound(err))
+
+		var apiErr *Error
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, "USR-3003", apiErr.Code)
+		assert.Equal(t, "user_not_found", apiErr.Name)
+		assert.Equal(t, "maintainerd auth: 404 USR-3003: User not found", err.Error())
+	})
+
+	t.Run("not json", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+		}))
+		defer srv.Close()
+
+		c, err := New(srv.URL)
+		require.NoError(t, err)
+		_, err = c.Roles.Get(context.Background(), uuid.New())
+		assert.EqualError(t, err, "maintainerd auth: 502: Bad Gateway")
+	})
+}
+
+type countingSource struct {
+	issued      int
+	invalidated int
+}
+
+func (s *countingSource) Token(context.Context) (*Token, error) {
+	s.issued++
+	return &Token{AccessToken: fmt.Sprintf("token-%d", s.issued)}, nil
+}
+
+func (s *countingSource) Invalidate() { s.invalidated++ }
+
+func TestClient_RetriesUnauthorized(t *testing.T) {
+	t.Run("retried once with a fresh token", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer token-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			writeData(w, http.StatusOK, map[string]any{"username": "alice"})
+		}))
+		defer srv.Close()
+
+		ts := &countingSource{}
+		c, err := New(srv.URL, WithTokenSource(ts))
+		require.NoError(t, err)
+		_, err = c.Users.Get(context.Background(), uuid.New())
+		require.NoError(t, err)
+		assert.Equal(t, 2, ts.issued)
+		assert.Equal(t, 1, ts.invalidated)
+	})
+
+	t.Run("not retried twice", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer srv.Close()
+
+		ts := &countingSource{}
+		c, err := New(srv.URL, WithTokenSource(ts))
+		require.NoError(t, err)
+		_, err = c.Users.Get(context.Background(), uuid.New())
+		var apiErr *Error
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+		assert.Equal(t, 2, ts.issued)
+	})
+}
+
+func TestClient_All(t *testing.T) {
+	var pagesServed []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		pagesServed = append(pagesServed, q.Get("page"))
+		assert.Equal(t, "2", q.Get("limit"))
+		assert.Equal(t, []string{"active", "pending"}, q["status"])
+
+		rows := map[string][]map[string]any{
+			"1": {{"username": "a"}, {"username": "b"}},
+			"2": {{"username": "c"}},
+		}[q.Get("page")]
+		writeData(w, http.StatusOK, map[string]any{"rows": rows, "total": 3, "page": 1, "limit": 2, "total_pages": 2})
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+	filter := UserFilter{Status: []string{"active", "pending"}, PaginationRequestDTO: Pagination{Limit: 2}}
+
+	var names []string
+	for user, err := range c.Users.All(context.Background(), filter) {
+		require.NoError(t, err)
+		names = append(names, user.Username)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, names)
+	assert.Equal(t, []string{"1", "2"}, pagesServed)
+
+	t.Run("stops on break", func(t *testing.T) {
+		pagesServed = nil
+		for range c.Users.All(context.Background(), filter) {
+			break
+		}
+		assert.Equal(t, []string{"1"}, pagesServed)
+	})
+}
+
+func TestEncodeQuery(t *testing.T) {
+	isDefault := false
+	q := encodeQuery(ClientFilter{
+		Name:       ptr.Ptr("web"),
+		ClientType: []string{"spa", "native"},
+		IsDefault:  &isDefault,
+		PaginationRequestDTO: Pagination{
+			Page:      3,
+			Limit:     10,
+			SortOrder: "desc",
+		},
+	})
+	assert.Equal(t, "web", q.Get("name"))
+	assert.Equal(t, []string{"spa", "native"}, q["client_type"])
+	assert.Equal(t, "false", q.Get("is_default"))
+	assert.Equal(t, "3", q.Get("page"))
+	assert.Equal(t, "10", q.Get("limit"))
+	assert.Equal(t, "desc", q.Get("sort_order"))
+	assert.NotContains(t, q, "display_name")
+	assert.NotContains(t, q, "is_system")
+	assert.NotContains(t, q, "sort_by")
+
+	q = encodeQuery(UserFilter{IncludeSubtenants: true})
+	assert.Equal(t, "true", q.Get("include_subtenants"))
+	assert.NotContains(t, q, "Cursor")
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// ClientService calls the /clients endpoints, which manage auth clients.
+type ClientService struct {
+	c *Client
+}
+
+// List returns one page of the auth clients matching filter.
+func (s *ClientService) List(ctx context.Context, filter ClientFilter) (*Page[AuthClient], error) {
+	filter.PaginationRequestDTO = withPage(filter.PaginationRequestDTO)
+	return call[Page[AuthClient]](ctx, s.c, http.MethodGet, "/api/v1/clients/", encodeQuery(filter), nil)
+}
+
+// All iterates over every auth client matching filter, from filter.Page on.
+func (s *ClientService) All(ctx context.Context, filter ClientFilter) iter.Seq2[AuthClient, error] {
+	return pages(ctx, filter.PaginationRequestDTO, func(ctx context.Context, p Pagination) (*Page[AuthClient], error) {
+		filter.PaginationRequestDTO = p
+		return s.List(ctx, filter)
+	})
+}
+
+// Get returns an auth client.
+func (s *ClientService) Get(ctx context.Context, clientUUID uuid.UUID) (*AuthClient, error) {
+	return call[AuthClient](ctx, s.c, http.MethodGet, "/api/v1/clients/"+clientUUID.String(), nil, nil)
+}
+
+// Create creates an auth client.
+func (s *ClientService) Create(ctx context.Context, req ClientCreateRequest) (*AuthClient, error) {
+	return call[AuthClient](ctx, s.c, http.MethodPost, "/api/v1/clients/", nil, req)
+}
+
+// Update updates an auth client.
+func (s *ClientService) Update(ctx context.Context, clientUUID uuid.UUID, req ClientUpdateRequest) (*AuthClient, error) {
+	return call[AuthClient](ctx, s.c, http.MethodPut, "/api/v1/clients/"+clientUUID.String(), nil, req)
+}
+
+// Delete deletes an auth client.
+func (s *ClientService) Delete(ctx context.Context, clientUUID uuid.UUID) (*AuthClient, error) {
+	return call[AuthClient](ctx, s.c, http.MethodDelete, "/api/v1/clients/"+clientUUID.String(), nil, nil)
+}
+
+// ToggleStatus switches an auth client between active and inactive.
+func (s *ClientService) ToggleStatus(ctx context.Context, clientUUID uuid.UUID) (*AuthClient, error) {
+	return call[AuthClient](ctx, s.c, http.MethodPut, "/api/v1/clients/"+clientUUID.String()+"/status", nil, nil)
+}
+
+// GetSecret returns an auth client's secret, masked.
+func (s *ClientService) GetSecret(ctx context.Context, clientUUID uuid.UUID) (*ClientSecret, error) {
+	return call[ClientSecret](ctx, s.c, http.MethodGet, "/api/v1/clients/"+clientUUID.String()+"/secret", nil, nil)
+}
+
+// RotateSecret issues a new secret for an auth client. The previous secret
+// keeps working for req.GracePeriodSeconds.
+func (s *ClientService) RotateSecret(ctx context.Context, clientUUID uuid.UUID, req ClientRotateSecretRequest) (*ClientSecretRotation, error) {
+	return call[ClientSecretRotation](ctx, s.c, http.MethodPost, "/api/v1/clients/"+clientUUID.String()+"/rotate-secret", nil, req)
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxErrorBody bounds the error body read from a response.
+const maxErrorBody = 64 << 10
+
+// Error is a failed API call. Management endpoints answer with an RFC 7807
+// problem; the OAuth2 endpoints with an RFC 6749 error, whose error and
+// error_description become Code and Detail.
+//
+// Branch on Code or Name, never on Detail:
+//
+//	var apiErr *client.Error
+//	if errors.As(err, &apiErr) && apiErr.Code == "USR-3001" { ... }
+type Error struct {
+	StatusCode int
+	Type       string
+	Title      string
+	Detail     string
+	Code       string
+	Name       string
+	Details    json.RawMessage
+}
+
+func (e *Error) Error() string {
+	msg := e.Detail
+	if msg == "" {
+		msg = e.Title
+	}
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.Code != "" {
+		return fmt.Sprintf("maintainerd auth: %d %s: %s", e.StatusCode, e.Code, msg)
+	}
+	return fmt.Sprintf("maintainerd auth: %d: %s", e.StatusCode, msg)
+}
+
+// IsNotFound reports whether err is an API error with status 404.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// decodeError reads the error of a failed response.
+func decodeError(res *http.Response) error {
+	var body struct {
+		Type             string          `json:"type"`
+		Title            string          `json:"title"`
+		Detail           string          `json:"detail"`
+		Code             string          `json:"code"`
+		Name             string          `json:"name"`
+		Details          json.RawMessage `json:"details"`
+		Error            string          `json:"error"`
+		ErrorDescription string          `json:"error_description"`
+	}
+	// A body that is not JSON still yields an error with the status
+	_ = json.NewDecoder(io.LimitReader(res.Body, maxErrorBody)).Decode(&body)
+
+	apiErr := &Error{
+		StatusCode: res.StatusCode,
+		Type:       body.Type,
+		Title:      body.Title,
+		Detail:     body.Detail,
+		Code:       body.Code,
+		Name:       body.Name,
+		Details:    body.Details,
+	}
+	if apiErr.Code == "" && body.Error != "" {
+		// OAuth2 error response
+		apiErr.Code = body.Error
+		apiErr.Detail = body.ErrorDescription
+	}
+	if apiErr.Detail == "" {
+		apiErr.Detail = body.Error
+	}
+	return apiErr
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// DefaultPageSize is the page size of list calls whose filter sets no limit.
+const DefaultPageSize = 50
+
+// encodeQuery converts a filter DTO to query parameters named by its json
+// tags, the names the list handlers parse. Nil pointers and zero values are
+// left out, slices become repeated parameters and embedded structs, such as
+// Pagination, are flattened.
+func encodeQuery(filter any) url.Values {
+	q := url.Values{}
+	encodeStruct(q, reflect.ValueOf(filter))
+	return q
+}
+
+func encodeStruct(q url.Values, v reflect.Value) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		if field.Anonymous {
+			encodeStruct(q, fv)
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		for fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				break
+			}
+			fv = fv.Elem()
+		}
+		switch {
+		case fv.Kind() == reflect.Pointer:
+			// nil
+		case fv.Kind() == reflect.Slice:
+			for j := range fv.Len() {
+				q.Add(name, fmt.Sprint(fv.Index(j).Interface()))
+			}
+		case field.Type.Kind() == reflect.Pointer:
+			// A set pointer is sent even when zero, e.g. is_default=false.
+			q.Set(name, fmt.Sprint(fv.Interface()))
+		case !fv.IsZero():
+			q.Set(name, fmt.Sprint(fv.Interface()))
+		}
+	}
+}
+
+// withPage returns p with the defaults the list endpoints require: the
+// first page and DefaultPageSize.
+func withPage(p Pagination) Pagination {
+	if p.Page < 1 {
+		p.Page = 1
+	}
+	if p.Limit < 1 {
+		p.Limit = DefaultPageSize
+	}
+	return p
+}
+
+// pages iterates over the rows of every page from p.Page on, fetching each
+// page with list. Iteration stops after the last page or the first error.
+func pages[T any](ctx context.Context, p Pagination, list func(ctx context.Context, p Pagination) (*Page[T], error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		p = withPage(p)
+		for {
+			page, err := list(ctx, p)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, row := range page.Rows {
+				if !yield(row, nil) {
+					return
+				}
+			}
+			if len(page.Rows) == 0 || p.Page >= page.TotalPages {
+				return
+			}
+			p.Page++
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+)
+
+// RoleService calls the /roles endpoints.
+type RoleService struct {
+	c *Client
+}
+
+// List returns one page of the roles matching filter.
+func (s *RoleService) List(ctx context.Context, filter RoleFilter) (*Page[Role], error) {
+	filter.PaginationRequestDTO = withPage(filter.PaginationRequestDTO)
+	return call[Page[Role]](ctx, s.c, http.MethodGet, "/api/v1/roles/", encodeQuery(filter), nil)
+}
+
+// All iterates over every role matching filter, from filter.Page on.
+func (s *RoleService) All(ctx context.Context, filter RoleFilter) iter.Seq2[Role, error] {
+	return pages(ctx, filter.PaginationRequestDTO, func(ctx context.Context, p Pagination) (*Page[Role], error) {
+		filter.PaginationRequestDTO = p
+		return s.List(ctx, filter)
+	})
+}
+
+// Get returns a role.
+func (s *RoleService) Get(ctx context.Context, roleUUID uuid.UUID) (*Role, error) {
+	return call[Role](ctx, s.c, http.MethodGet, "/api/v1/roles/"+roleUUID.String(), nil, nil)
+}
+
+// Create creates a role.
+func (s *RoleService) Create(ctx context.Context, req RoleRequest) (*Role, error) {
+	return call[Role](ctx, s.c, http.MethodPost, "/api/v1/roles/", nil, req)
+}
+
+// Update updates a role.
+func (s *RoleService) Update(ctx context.Context, roleUUID uuid.UUID, req RoleRequest) (*Role, error) {
+	return call[Role](ctx, s.c, http.MethodPut, "/api/v1/roles/"+roleUUID.String(), nil, req)
+}
+
+// Delete deletes a role.
+func (s *RoleService) Delete(ctx context.Context, roleUUID uuid.UUID) (*Role, error) {
+	return call[Role](ctx, s.c, http.MethodDelete, "/api/v1/roles/"+roleUUID.String(), nil, nil)
+}
+
+// SetStatus sets a role's status, "active" or "inactive".
+func (s *RoleService) SetStatus(ctx context.Context, roleUUID uuid.UUID, status string) (*Role, error) {
+	return call[Role](ctx, s.c, http.MethodPut, "/api/v1/roles/"+roleUUID.String()+"/status", nil,
+		statusRequest{Status: status})
+}
+
+// AddPermissions adds permissions to a role.
+func (s *RoleService) AddPermissions(ctx context.Context, roleUUID uuid.UUID, permissionUUIDs ...uuid.UUID) (*Role, error) {
+	return call[Role](ctx, s.c, http.MethodPost, "/api/v1/roles/"+roleUUID.String()+"/permissions", nil,
+		dto.RoleAddPermissionsRequestDTO{Permissions: permissionUUIDs})
+}
+
+// RemovePermission removes a permission from a role.
+func (s *RoleService) RemovePermission(ctx context.Context, roleUUID, permissionUUID uuid.UUID) (*Role, error) {
+	return call[Role](ctx, s.c, http.MethodDelete, "/api/v1/roles/"+roleUUID.String()+"/permissions/"+permissionUUID.String(), nil, nil)
+}
@@ -0,0 +1,191 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/maintainerd/auth/internal/dto"
+)
+
+// expiryLeeway is how long before its expiry a token is replaced, so it does
+// not expire in flight.
+const expiryLeeway = 30 * time.Second
+
+// Token is an access token and, when the grant issued one, its refresh token.
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	// Expiry is when the access token expires; zero for tokens that do not,
+	// such as personal access tokens.
+	Expiry time.Time
+}
+
+// Valid reports whether the access token is set and not about to expire.
+func (t *Token) Valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || time.Now().Add(expiryLeeway).Before(t.Expiry)
+}
+
+// TokenSource supplies the access token of each request.
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// invalidator is implemented by token sources that can replace a token the
+// API rejected before it expired, e.g. after a logout or key rotation.
+type invalidator interface {
+	Invalidate()
+}
+
+// StaticToken returns a source of a fixed token, such as a personal access
+// token. It is never refreshed.
+func StaticToken(accessToken string) TokenSource {
+	return staticToken{token: &Token{AccessToken: accessToken}}
+}
+
+type staticToken struct {
+	token *Token
+}
+
+func (s staticToken) Token(context.Context) (*Token, error) {
+	return s.token, nil
+}
+
+// cachingTokenSource reuses a token until it is about to expire, then
+// fetches the next one. fetch is passed the previous token, if any.
+type cachingTokenSource struct {
+	mu    sync.Mutex
+	token *Token
+	fetch func(ctx context.Context, previous *Token) (*Token, error)
+}
+
+func (s *cachingTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.Valid() {
+		return s.token, nil
+	}
+	token, err := s.fetch(ctx, s.token)
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
+
+// Invalidate drops the access token but keeps the refresh token.
+func (s *cachingTokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != nil {
+		s.token = &Token{RefreshToken: s.token.RefreshToken}
+	}
+}
+
+// WithPasswordLogin authenticates as a user, logging in with username and
+// password through POST /login and again whenever the access token is about
+// to expire. clientID selects the auth client; empty for the default one.
+// Users who must complete MFA cannot log in this way.
+func WithPasswordLogin(username, password, clientID string) Option {
+	return func(c *Client) {
+		c.tokens = &cachingTokenSource{fetch: func(ctx context.Context, _ *Token) (*Token, error) {
+			return c.login(ctx, username, password, clientID)
+		}}
+	}
+}
+
+func (c *Client) login(ctx context.Context, username, password, clientID string) (*Token, error) {
+	query := url.Values{}
+	if clientID != "" {
+		query.Set("client_id", clientID)
+	}
+	res, err := callAs[dto.LoginResponseDTO](ctx, c, false, http.MethodPost, "/api/v1/login", query,
+		dto.LoginRequestDTO{Username: username, Password: password})
+	if err != nil {
+		return nil, fmt.Errorf("login: %w", err)
+	}
+	if res.AccessToken == "" {
+		return nil, errors.New("login: no access token issued, the account requires another factor")
+	}
+	return &Token{
+		AccessToken:  res.AccessToken,
+		RefreshToken: res.RefreshToken,
+		Expiry:       expiry(res.ExpiresIn),
+	}, nil
+}
+
+// RefreshTokenSource returns a source that redeems refreshToken at the
+// OAuth2 token endpoint tokenURL, e.g.
+// "https://auth.example.com/api/v1/oauth/token" on the public port. Refresh
+// tokens are rotated, so the source keeps the one issued with each access
+// token. clientSecret is empty for public clients; hc is the HTTP client, or
+// nil for one with DefaultTimeout.
+func RefreshTokenSource(tokenURL, clientID, clientSecret, refreshToken string, hc *http.Client) TokenSource {
+	if hc == nil {
+		hc = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &cachingTokenSource{
+		token: &Token{RefreshToken: refreshToken},
+		fetch: func(ctx context.Context, previous *Token) (*Token, error) {
+			return refresh(ctx, hc, tokenURL, clientID, clientSecret, previous.RefreshToken)
+		},
+	}
+}
+
+func refresh(ctx context.Context, hc *http.Client, tokenURL, clientID, clientSecret, refreshToken string) (*Token, error) {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {clientID},
+	}
+	if clientSecret != "" {
+		form.Set("client_secret", clientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("build refresh request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("refresh token: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("refresh token: %w", decodeError(res))
+	}
+
+	var body dto.OAuthTokenResponseDTO
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("refresh token: decode response: %w", err)
+	}
+	token := &Token{
+		AccessToken:  body.AccessToken,
+		RefreshToken: body.RefreshToken,
+		Expiry:       expiry(body.ExpiresIn),
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	return token, nil
+}
+
+// expiry converts a lifetime in seconds to an expiry time; zero when the
+// lifetime is unknown.
+func expiry(expiresIn int64) time.Time {
+	if expiresIn <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(expiresIn) * time.Second)
+}
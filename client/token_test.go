@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToken_Valid(t *testing.T) {
+	var nilToken *Token
+	assert.False(t, nilToken.Valid())
+	assert.False(t, (&Token{RefreshToken: "r"}).Valid())
+	assert.True(t, (&Token{AccessToken: "a"}).Valid())
+	assert.True(t, (&Token{AccessToken: "a", Expiry: time.Now().Add(time.Hour)}).Valid())
+	assert.False(t, (&Token{AccessToken: "a", Expiry: time.Now().Add(10 * time.Second)}).Valid())
+}
+
+func TestWithPasswordLogin(t *testing.T) {
+	logins := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/login":
+			logins++
+			assert.Empty(t, r.Header.Get("Authorization"))
+			assert.Equal(t, "web", r.URL.Query().Get("client_id"))
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "admin", body["username"])
+			assert.Equal(t, "secret", body["password"])
+			writeData(w, http.StatusOK, map[string]any{"access_token": "at", "refresh_token": "rt", "expires_in": 3600})
+		default:
+			assert.Equal(t, "Bearer at", r.Header.Get("Authorization"))
+			writeData(w, http.StatusOK, map[string]any{"username": "alice"})
+		}
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL, WithPasswordLogin("admin", "secret", "web"))
+	require.NoError(t, err)
+	for range 2 {
+		_, err = c.Users.Get(context.Background(), uuid.New())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, logins, "the token is reused until it expires")
+
+	t.Run("rejected", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"status":401,"title":"Unauthorized","detail":"Invalid credentials","code":"AUTH-1001"}`))
+		}))
+		defer srv.Close()
+
+		c, err := New(srv.URL, WithPasswordLogin("admin", "wrong", ""))
+		require.NoError(t, err)
+		_, err = c.Users.Get(context.Background(), uuid.New())
+		var apiErr *Error
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, "AUTH-1001", apiErr.Code)
+	})
+}
+
+func TestRefreshTokenSource(t *testing.T) {
+	var refreshTokens []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "svc", r.PostForm.Get("client_id"))
+		assert.Equal(t, "shh", r.PostForm.Get("client_secret"))
+		refreshTokens = append(refreshTokens, r.PostForm.Get("refresh_token"))
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("refresh_token") == "revoked" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"Refresh token revoked"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"at-` + r.PostForm.Get("refresh_token") + `","token_type":"Bearer","expires_in":3600,"refresh_token":"rt2"}`))
+	}))
+	defer srv.Close()
+
+	ts := RefreshTokenSource(srv.URL, "svc", "shh", "rt1", nil)
+	token, err := ts.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "at-rt1", token.AccessToken)
+	assert.Equal(t, "rt2", token.RefreshToken)
+
+	token, err = ts.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "at-rt1", token.AccessToken, "a valid token is reused")
+
+	ts.(invalidator).Invalidate()
+	token, err = ts.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "at-rt2", token.AccessToken, "the rotated refresh token is redeemed")
+	assert.Equal(t, []string{"rt1", "rt2"}, refreshTokens)
+
+	t.Run("oauth error", func(t *testing.T) {
+		_, err := RefreshTokenSource(srv.URL, "svc", "shh", "revoked", nil).Token(context.Background())
+		var apiErr *Error
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+		assert.Equal(t, "invalid_grant", apiErr.Code)
+		assert.Equal(t, "Refresh token revoked", apiErr.Detail)
+	})
+}
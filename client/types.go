@@ -0,0 +1,51 @@
+package client
+
+import "github.com/maintainerd/auth/internal/dto"
+
+// Pagination selects a page of a list and its order. It is embedded in every
+// filter; Page and Limit default to 1 and DefaultPageSize.
+type Pagination = dto.PaginationRequestDTO
+
+// Page is one page of a list.
+type Page[T any] = dto.PaginatedResponseDTO[T]
+
+// Users.
+type (
+	User              = dto.UserResponseDTO
+	UserFilter        = dto.UserFilterDTO
+	UserCreateRequest = dto.UserCreateRequestDTO
+	UserUpdateRequest = dto.UserUpdateRequestDTO
+)
+
+// Roles.
+type (
+	Role        = dto.RoleResponseDTO
+	RoleFilter  = dto.RoleFilterDTO
+	RoleRequest = dto.RoleCreateOrUpdateRequestDTO
+)
+
+// Auth clients. AuthClient is the API resource, not to be confused with
+// Client, the SDK itself.
+type (
+	AuthClient                = dto.ClientResponseDTO
+	ClientFilter              = dto.ClientFilterDTO
+	ClientCreateRequest       = dto.ClientCreateRequestDTO
+	ClientUpdateRequest       = dto.ClientUpdateRequestDTO
+	ClientSecret              = dto.ClientSecretResponseDTO
+	ClientRotateSecretRequest = dto.ClientRotateSecretRequestDTO
+	ClientSecretRotation      = dto.ClientSecretRotationResponseDTO
+)
+
+// API keys.
+type (
+	APIKey              = dto.APIKeyResponseDTO
+	APIKeyFilter        = dto.APIKeyGetRequestDTO
+	APIKeyCreateRequest = dto.APIKeyCreateRequestDTO
+	APIKeyCreated       = dto.APIKeyCreateResponseDTO
+	APIKeyUpdateRequest = dto.APIKeyUpdateRequestDTO
+)
+
+// statusRequest is the body of the status endpoints.
+type statusRequest struct {
+	Status string `json:"status"`
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+)
+
+// UserService calls the /users endpoints.
+type UserService struct {
+	c *Client
+}
+
+// List returns one page of the users matching filter.
+func (s *UserService) List(ctx context.Context, filter UserFilter) (*Page[User], error) {
+	filter.PaginationRequestDTO = withPage(filter.PaginationRequestDTO)
+	return call[Page[User]](ctx, s.c, http.MethodGet, "/api/v1/users/", encodeQuery(filter), nil)
+}
+
+// All iterates over every user matching filter, from filter.Page on.
+func (s *UserService) All(ctx context.Context, filter UserFilter) iter.Seq2[User, error] {
+	return pages(ctx, filter.PaginationRequestDTO, func(ctx context.Context, p Pagination) (*Page[User], error) {
+		filter.PaginationRequestDTO = p
+		return s.List(ctx, filter)
+	})
+}
+
+// Get returns a user.
+func (s *UserService) Get(ctx context.Context, userUUID uuid.UUID) (*User, error) {
+	return call[User](ctx, s.c, http.MethodGet, "/api/v1/users/"+userUUID.String(), nil, nil)
+}
+
+// Create creates a user.
+func (s *UserService) Create(ctx context.Context, req UserCreateRequest) (*User, error) {
+	return call[User](ctx, s.c, http.MethodPost, "/api/v1/users/", nil, req)
+}
+
+// Update updates a user.
+func (s *UserService) Update(ctx context.Context, userUUID uuid.UUID, req UserUpdateRequest) (*User, error) {
+	return call[User](ctx, s.c, http.MethodPut, "/api/v1/users/"+userUUID.String(), nil, req)
+}
+
+// Delete soft-deletes a user.
+func (s *UserService) Delete(ctx context.Context, userUUID uuid.UUID) (*User, error) {
+	return call[User](ctx, s.c, http.MethodDelete, "/api/v1/users/"+userUUID.String(), nil, nil)
+}
+
+// SetStatus sets a user's status, e.g. "active" or "suspended".
+func (s *UserService) SetStatus(ctx context.Context, userUUID uuid.UUID, status string) (*User, error) {
+	return call[User](ctx, s.c, http.MethodPatch, "/api/v1/users/"+userUUID.String()+"/status", nil,
+		dto.UserSetStatusRequestDTO{Status: status})
+}
+
+// AssignRoles assigns roles to a user.
+func (s *UserService) AssignRoles(ctx context.Context, userUUID uuid.UUID, roleUUIDs ...uuid.UUID) (*User, error) {
+	return call[User](ctx, s.c, http.MethodPost, "/api/v1/users/"+userUUID.String()+"/roles", nil,
+		dto.UserAssignRolesRequestDTO{RoleUUIDs: roleUUIDs})
+}
+
+// RemoveRole removes a role from a user.
+func (s *UserService) RemoveRole(ctx context.Context, userUUID, roleUUID uuid.UUID) (*User, error) {
+	return call[User](ctx, s.c, http.MethodDelete, "/api/v1/users/"+userUUID.String()+"/roles/"+roleUUID.String(), nil, nil)
+}
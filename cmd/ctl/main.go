@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"github.com/maintainerd/auth/internal/app"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/ctl"
+	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/logging"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+func main() {
+	// Logs go to stderr so command output, like exported users, stays clean
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	logging.Init(slog.NewTextHandler(os.Stderr, nil))
+
+	if err := ctl.NewRootCommand(open).Execute(); err != nil {
+		slog.Error("Command failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+// open loads the configuration, connects to the database and Redis and wires
+// the services the same way the server does.
+func open() (*ctl.Env, func(), error) {
+	if err := config.Init(); err != nil {
+		return nil, nil, err
+	}
+	if err := jwt.InitJWTKeys(); err != nil {
+		return nil, nil, err
+	}
+
+	db, err := config.InitDB()
+	if err != nil {
+		return nil, nil, err
+	}
+	closeDB := func() {
+		if err := config.CloseDB(db); err != nil {
+			slog.Error("Database close error", "error", err)
+		}
+	}
+
+	redisClient, err := config.NewRedisClient()
+	if err != nil {
+		closeDB()
+		return nil, nil, err
+	}
+	closeAll := func() {
+		if err := redisClient.Close(); err != nil {
+			slog.Error("Redis close error", "error", err)
+		}
+		closeDB()
+	}
+
+	var profileEncryptor *crypto.FieldEncryptor
+	if config.ProfileEncryptionEnabled {
+		if profileEncryptor, err = crypto.NewFieldEncryptor(config.ProfileEncryptionKey); err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+	}
+
+	signingKeys := service.SigningKeyConfig{
+		RotationInterval: config.JWTKeyRotationInterval,
+		RetireAfter:      config.JWTKeyRetireAfter,
+	}
+	if config.JWTKeyRotationEnabled {
+		if signingKeys.Encryptor, err = crypto.NewFieldEncryptor(config.JWTKeyEncryptionKey); err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+	}
+
+	dataKeys := service.DataKeyConfig{
+		RotationInterval: config.ColumnEncryptionRotationInterval,
+	}
+	if config.ColumnEncryptionEnabled {
+		if dataKeys.Wrapper, err = config.NewColumnKeyWrapper(); err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+	}
+
+	// Breached password checks, GeoIP, sessions, the event stream and
+	// profile picture storage are not used by any command.
+	application := app.NewApp(db, redisClient, profileEncryptor, service.AuthzAuditConfig{
+		AllowSampleRate: config.AuthzAuditAllowSampleRate,
+		DenySampleRate:  config.AuthzAuditDenySampleRate,
+		MaxPerSecond:    config.AuthzAuditMaxPerSecond,
+	}, nil, nil, nil, nil, nil, signingKeys, dataKeys, service.ProfilePictureConfig{})
+
+	// Writes made by commands drop the lookups the servers have cached
+	if err := db.Use(cache.NewInvalidationPlugin(application.Cache)); err != nil {
+		closeAll()
+		return nil, nil, err
+	}
+
+	// Tokens issued by commands are signed with the stored active key
+	if application.SigningKeyService != nil {
+		if err := application.SigningKeyService.Load(context.Background()); err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+	}
+
+	// Secrets written by commands are sealed with the active data key
+	if application.DataKeyService != nil {
+		if err := application.DataKeyService.Load(context.Background()); err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+		crypto.SetColumnKeyring(application.DataKeyService.Keyring())
+	}
+
+	return &ctl.Env{
+		DB:         db,
+		Setup:      application.SetupService,
+		Tenant:     application.TenantService,
+		User:       application.UserService,
+		SigningKey: application.SigningKeyService,
+		DataKey:    application.DataKeyService,
+	}, closeAll, nil
+}
@@ -4,21 +4,36 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/maintainerd/auth/internal/app"
+	"github.com/maintainerd/auth/internal/cache"
 	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/ctl"
+	"github.com/maintainerd/auth/internal/eventstream"
+	"github.com/maintainerd/auth/internal/geoip"
 	grpcserver "github.com/maintainerd/auth/internal/grpc/server"
 	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/logging"
 	restserver "github.com/maintainerd/auth/internal/rest/server"
 	"github.com/maintainerd/auth/internal/runner"
 	"github.com/maintainerd/auth/internal/security"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/maintainerd/auth/internal/session"
+	"github.com/maintainerd/auth/internal/siem"
+	"github.com/maintainerd/auth/internal/storage"
 	"github.com/maintainerd/auth/internal/telemetry"
 )
 
 func main() {
 	// Configure structured JSON logging for container environments
 	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	// Component loggers (auth, db, webhooks) filter by their own runtime level
+	logging.Init(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
 
 	// ⚙️ Load configurations
 	if err := config.Init(); err != nil {
@@ -26,19 +41,42 @@ func main() {
 		os.Exit(1)
 	}
 
+	// 🗄️ "server migrate ..." manages the schema and exits without serving
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		migrate := ctl.NewMigrateCommand(func() (*ctl.Env, func(), error) {
+			db, err := config.InitDB()
+			if err != nil {
+				return nil, nil, err
+			}
+			return &ctl.Env{DB: db}, func() {
+				if err := config.CloseDB(db); err != nil {
+					slog.Error("Database close error", "error", err)
+				}
+			}, nil
+		})
+		migrate.SilenceUsage = true
+		migrate.SetArgs(os.Args[2:])
+		if err := migrate.Execute(); err != nil {
+			slog.Error("Migrate command failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// ⚙️ Initialise OpenTelemetry tracing (safe no-op when OTEL_ENABLED != true)
 	otelShutdown, err := telemetry.Init(context.Background())
 	if err != nil {
 		slog.Error("OpenTelemetry initialization failed", "error", err)
 		os.Exit(1)
 	}
-	defer func() {
+	shutdownTelemetry := func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		if err := otelShutdown(ctx); err != nil {
 			slog.Error("OpenTelemetry shutdown error", "error", err)
 		}
-	}()
+	}
+	defer shutdownTelemetry()
 
 	// ⚙️ Parse RSA keys (required for token signing)
 	if err := jwt.InitJWTKeys(); err != nil {
@@ -69,28 +107,343 @@ func main() {
 		os.Exit(1)
 	}
 
+	// ⚙️ Profile field encryption (nil keeps sensitive fields in plaintext)
+	var profileEncryptor *crypto.FieldEncryptor
+	if config.ProfileEncryptionEnabled {
+		if profileEncryptor, err = crypto.NewFieldEncryptor(config.ProfileEncryptionKey); err != nil {
+			slog.Error("Profile encryption initialization failed", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// ⚙️ Breached password lookups (tenants opt in via feature flag)
+	var breachChecker security.BreachedPasswordChecker
+	if config.BreachedPasswordCheckEnabled {
+		breachChecker = security.NewHIBPChecker(config.BreachedPasswordAPIURL, config.BreachedPasswordThreshold, config.BreachedPasswordTimeout)
+	}
+
+	// ⚙️ GeoIP lookups for auth events, security events and login records
+	// (no database path leaves them unlocated)
+	var geoReader *geoip.Reader
+	var geoLocator security.GeoLocator
+	if config.GeoIPDatabasePath != "" {
+		if geoReader, err = geoip.Open(config.GeoIPDatabasePath, config.GeoIPASNDatabasePath); err != nil {
+			slog.Error("GeoIP initialization failed", "error", err)
+			os.Exit(1)
+		}
+		geoLocator = geoip.NewCache(geoReader, config.GeoIPCacheSize, config.GeoIPCacheTTL)
+		security.InitGeoLocator(geoLocator)
+	}
+
+	// ⚙️ Hosted page sessions (Redis, or stateless signed cookies)
+	hostedSessions, err := session.New(config.HostedSessionStore, config.HostedSessionKeys, redisClient, session.Options{
+		TTL:      config.HostedSessionTTL,
+		SameSite: config.HostedSessionSameSite,
+	})
+	if err != nil {
+		slog.Error("Hosted session store initialization failed", "error", err)
+		os.Exit(1)
+	}
+
+	// ⚙️ Browser sessions for first-party web apps (Redis, opt-in)
+	var browserSessions session.Store
+	if config.BrowserSessionsEnabled {
+		browserSessions = session.NewRedisStore(redisClient, session.Options{
+			Name:     session.BrowserName,
+			TTL:      config.BrowserSessionIdleTimeout,
+			SameSite: config.BrowserSessionSameSite,
+		})
+	}
+
+	// ⚙️ Event stream export to Kafka or NATS (nil disables it)
+	var eventStream *eventstream.Exporter
+	if config.EventStreamProvider != "" {
+		eventStream, err = eventstream.New(eventstream.Config{
+			Provider:      config.EventStreamProvider,
+			Brokers:       config.EventStreamBrokers,
+			Topic:         config.EventStreamTopic,
+			Types:         config.EventStreamEventTypes,
+			Username:      config.EventStreamUsername,
+			Password:      string(config.EventStreamPassword),
+			SASLMechanism: config.EventStreamSASLMechanism,
+			TLS:           config.EventStreamTLS,
+			Timeout:       config.EventStreamTimeout,
+		})
+		if err != nil {
+			slog.Error("Event stream initialization failed", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// ⚙️ Signing key rotation (a nil encryptor signs with the configured key only)
+	signingKeys := service.SigningKeyConfig{
+		RotationInterval: config.JWTKeyRotationInterval,
+		RetireAfter:      config.JWTKeyRetireAfter,
+	}
+	if config.JWTKeyRotationEnabled {
+		if signingKeys.Encryptor, err = crypto.NewFieldEncryptor(config.JWTKeyEncryptionKey); err != nil {
+			slog.Error("Signing key encryption initialization failed", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// ⚙️ Column encryption (a nil wrapper keeps sensitive columns in plaintext)
+	dataKeys := service.DataKeyConfig{
+		RotationInterval: config.ColumnEncryptionRotationInterval,
+	}
+	if config.ColumnEncryptionEnabled {
+		if dataKeys.Wrapper, err = config.NewColumnKeyWrapper(); err != nil {
+			slog.Error("Column encryption initialization failed", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// ⚙️ Profile picture storage (a nil store disables uploads)
+	profilePictures := service.ProfilePictureConfig{
+		MaxDimension: config.ProfilePictureMaxDimension,
+		URLTTL:       config.ProfilePictureURLTTL,
+	}
+	if config.StorageProvider != "" {
+		profilePictures.Store, err = storage.New(context.Background(), storage.Config{
+			Provider:        config.StorageProvider,
+			LocalDir:        config.StorageLocalDir,
+			LocalURL:        config.AppPublicHostname + "/api/v1/media",
+			Bucket:          config.StorageBucket,
+			Region:          config.StorageRegion,
+			Endpoint:        config.StorageEndpoint,
+			PathStyle:       config.StoragePathStyle,
+			AccessKeyID:     config.StorageAccessKeyID,
+			SecretAccessKey: string(config.StorageSecretAccessKey),
+		})
+		if err != nil {
+			slog.Error("Storage initialization failed", "error", err)
+			os.Exit(1)
+		}
+	}
+
 	// ⚙️ App wiring (handlers, services, etc.)
-	application := app.NewApp(db, redisClient)
+	application := app.NewApp(db, redisClient, profileEncryptor, service.AuthzAuditConfig{
+		AllowSampleRate: config.AuthzAuditAllowSampleRate,
+		DenySampleRate:  config.AuthzAuditDenySampleRate,
+		MaxPerSecond:    config.AuthzAuditMaxPerSecond,
+	}, breachChecker, geoLocator, hostedSessions, browserSessions, eventStream, signingKeys, dataKeys, profilePictures)
+
+	// 🛡️ Security event forwarding to a SIEM (nil leaves events in the logs only)
+	var siemForwarder *siem.Forwarder
+	if config.SIEMProvider != "" {
+		siemForwarder, err = siem.New(siem.Config{
+			Provider:      config.SIEMProvider,
+			Endpoint:      config.SIEMEndpoint,
+			SyslogNetwork: config.SIEMSyslogNetwork,
+			Token:         string(config.SIEMToken),
+			SplunkIndex:   config.SIEMSplunkIndex,
+			Version:       config.AppVersion,
+			MinSeverity:   config.SIEMMinSeverity,
+			BatchSize:     config.SIEMBatchSize,
+			FlushInterval: config.SIEMFlushInterval,
+			MaxAttempts:   config.SIEMMaxAttempts,
+			Timeout:       config.SIEMTimeout,
+		}, application.SIEMForwardingService)
+		if err != nil {
+			slog.Error("SIEM forwarding initialization failed", "error", err)
+			os.Exit(1)
+		}
+		security.InitSecurityEventForwarder(siemForwarder)
+	}
+
+	// 🧹 Writes to clients, identity providers and tenants drop the cached
+	// lookups built from them
+	if err := db.Use(cache.NewInvalidationPlugin(application.Cache)); err != nil {
+		slog.Error("Cache invalidation initialization failed", "error", err)
+		os.Exit(1)
+	}
+
+	// 🔐 Sensitive columns are sealed with the active data key from here on;
+	// values written before are re-encrypted by the data key runner.
+	if application.DataKeyService != nil {
+		if err := application.DataKeyService.Load(context.Background()); err != nil {
+			slog.Error("Failed to load data keys", "error", err)
+			os.Exit(1)
+		}
+		crypto.SetColumnKeyring(application.DataKeyService.Keyring())
+	}
+
+	// 🔑 Stored signing keys replace the configured key; tokens signed with
+	// a key another instance rotated to trigger a reload.
+	if application.SigningKeyService != nil {
+		if err := application.SigningKeyService.Load(context.Background()); err != nil {
+			slog.Error("Failed to load signing keys", "error", err)
+			os.Exit(1)
+		}
+		jwt.SetKeyReloader(func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return application.SigningKeyService.Load(ctx)
+		})
+	}
 
-	// Create a cancellable context for background workers.
-	// It is cancelled after the REST servers have drained so that background
-	// goroutines also shut down gracefully when an OS signal is received.
-	bgCtx, cancelBG := context.WithCancel(context.Background())
+	// Cancelled on SIGINT/SIGTERM; every server and background worker
+	// watches this context and drains when it is done.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var wg sync.WaitGroup
 
 	// 🗑️ Auth event retention runner (background)
-	go runner.StartRetentionRunner(bgCtx, application.AuthEventService, runner.DefaultRetentionPeriod, runner.DefaultRetentionInterval)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runner.StartRetentionRunner(ctx, application.AuditArchiveService, runner.DefaultRetentionInterval)
+	}()
+
+	// 🧹 Deleted user purge runner (background)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runner.StartUserPurgeRunner(ctx, application.UserService, runner.DefaultUserPurgeInterval)
+	}()
+
+	// 🔑 API key usage runner (background) — writes buffered request counts
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runner.StartAPIKeyUsageRunner(ctx, application.APIKeyService, runner.DefaultAPIKeyUsageInterval)
+	}()
+
+	// 📊 Tenant usage runner (background) — writes metered usage for reports
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runner.StartUsageRunner(ctx, application.UsageService, runner.DefaultUsageInterval)
+	}()
+
+	// 📣 Event relay runner (background) — publishes domain events to the event bus
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runner.StartEventRelayRunner(ctx, application.EventRelayService, runner.DefaultEventRelayInterval)
+	}()
+
+	// 📡 Event stream relay runner (background) — exports domain events to the broker
+	if application.EventStreamRelayService != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runner.StartEventRelayRunner(ctx, application.EventStreamRelayService, runner.DefaultEventRelayInterval)
+		}()
+	}
+
+	// 📤 Webhook dispatch runner (background)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runner.StartWebhookDispatchRunner(ctx, application.WebhookDeliveryService, runner.DefaultWebhookDispatchInterval)
+	}()
+
+	// 🔄 Signing key rotation runner (background) — rotates, retires and reloads keys
+	if application.SigningKeyService != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runner.StartSigningKeyRunner(ctx, application.SigningKeyService, runner.DefaultSigningKeyInterval)
+		}()
+	}
+
+	// 🔐 Data key rotation runner (background) — rotates, reloads and re-encrypts
+	if application.DataKeyService != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runner.StartDataKeyRunner(ctx, application.DataKeyService, runner.DefaultDataKeyInterval)
+		}()
+	}
+
+	// 📊 Queue metrics runner (background) — samples queue depth and age for /metrics
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runner.StartQueueMetricsRunner(ctx, application.QueueHealthService, runner.DefaultQueueMetricsInterval)
+	}()
+
+	// 🛡️ SIEM forwarder (background) — sends security events in batches
+	if siemForwarder != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			siemForwarder.Run(ctx)
+		}()
+	}
+
+	// ♻️ Config reload on SIGHUP — applies the reloadable settings of the config file
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				changed, err := config.Reload()
+				if err != nil {
+					slog.Error("Config reload failed, keeping the current settings", "error", err)
+					continue
+				}
+				slog.Info("Config reloaded", "changed", changed)
+			}
+		}
+	}()
 
 	// 🚀 gRPC server (background) — errors are logged; they don't affect REST.
+	wg.Add(1)
 	go func() {
-		if err := grpcserver.StartGRPCServer(bgCtx, application); err != nil {
+		defer wg.Done()
+		if err := grpcserver.StartGRPCServer(ctx, application, config.GRPCShutdownTimeout); err != nil {
 			slog.Error("gRPC server error", "error", err)
 		}
 	}()
 
-	// 🚀 REST servers — blocks until OS signal then drains.
-	restserver.StartRESTServer(application)
+	// 🚀 REST servers — blocks until OS signal (or listener failure) then drains.
+	restErr := restserver.StartRESTServer(ctx, application, config.HTTPShutdownTimeout)
+	if restErr != nil {
+		slog.Error("REST server shutdown with error", "error", restErr)
+	}
+
+	// Make sure gRPC and background workers stop even when REST exited on its
+	// own, then wait for them before releasing shared resources.
+	stop()
+	wg.Wait()
 
-	// Cancel the background context after REST has drained so gRPC and
-	// retention runner also shut down.
-	cancelBG()
+	// 🔌 Close shared connections only after nothing can use them anymore.
+	if eventStream != nil {
+		if err := eventStream.Close(); err != nil {
+			slog.Error("Event stream close error", "error", err)
+		}
+	}
+	if siemForwarder != nil {
+		if err := siemForwarder.Close(); err != nil {
+			slog.Error("SIEM forwarder close error", "error", err)
+		}
+	}
+	if geoReader != nil {
+		if err := geoReader.Close(); err != nil {
+			slog.Error("GeoIP database close error", "error", err)
+		}
+	}
+	if err := redisClient.Close(); err != nil {
+		slog.Error("Redis close error", "error", err)
+	} else {
+		slog.Info("Redis connection closed")
+	}
+	if err := config.CloseDB(db); err != nil {
+		slog.Error("Database close error", "error", err)
+	}
+
+	if restErr != nil {
+		shutdownTelemetry()
+		os.Exit(1)
+	}
+	slog.Info("Shutdown complete")
 }
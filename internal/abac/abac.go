@@ -0,0 +1,198 @@
+// Package abac evaluates the attribute-based conditions of policy
+// statements. A condition is a CEL expression over the caller (subject), the
+// route being called (resource) and the request itself; a statement only
+// applies when its condition evaluates to true.
+//
+// Example conditions:
+//
+//	resource.params.user_uuid == subject.id
+//	!request.ip.startsWith("10.")
+//	"admin" in subject.roles
+//	request.time.getHours("UTC") >= 18
+package abac
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+)
+
+const (
+	// MaxConditionLength caps the length of a condition expression.
+	MaxConditionLength = 1000
+
+	// conditionCostLimit caps the work a single condition evaluation may do,
+	// so that a pathological expression cannot stall a request.
+	conditionCostLimit = 10000
+
+	// maxCachedPrograms caps the number of compiled conditions kept in
+	// memory. The cache is reset when it fills up.
+	maxCachedPrograms = 1000
+)
+
+// Subject holds the attributes of the caller, available to conditions as
+// subject.<field>.
+type Subject struct {
+	// ID is the user's UUID, empty for callers without a user.
+	ID string
+	// TenantID is the UUID of the tenant the request is made in.
+	TenantID      string
+	Roles         []string
+	EmailVerified bool
+	// AuthMethod is how the caller authenticated: "jwt",
+	// "personal_access_token" or "api_key".
+	AuthMethod string
+}
+
+// Resource holds the attributes of the route being called, available as
+// resource.<field>.
+type Resource struct {
+	// Route is the route pattern, such as "/api/v1/users/{user_uuid}".
+	Route string
+	// Params are the route's URL parameters by name.
+	Params map[string]string
+}
+
+// Request holds the attributes of the HTTP request, available as
+// request.<field>.
+type Request struct {
+	Method string
+	Path   string
+	IP     string
+	Time   time.Time
+}
+
+// Input is everything a condition can refer to.
+type Input struct {
+	Subject  Subject
+	Resource Resource
+	Request  Request
+}
+
+// activation returns the CEL variables for in.
+func (in Input) activation() map[string]any {
+	roles := in.Subject.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+	params := in.Resource.Params
+	if params == nil {
+		params = map[string]string{}
+	}
+	return map[string]any{
+		"subject": map[string]any{
+			"id":             in.Subject.ID,
+			"tenant_id":      in.Subject.TenantID,
+			"roles":          roles,
+			"email_verified": in.Subject.EmailVerified,
+			"auth_method":    in.Subject.AuthMethod,
+		},
+		"resource": map[string]any{
+			"route":  in.Resource.Route,
+			"params": params,
+		},
+		"request": map[string]any{
+			"method": in.Request.Method,
+			"path":   in.Request.Path,
+			"ip":     in.Request.IP,
+			"time":   in.Request.Time,
+		},
+	}
+}
+
+var (
+	envOnce sync.Once
+	env     *cel.Env
+	envErr  error
+
+	programsMu sync.RWMutex
+	programs   = map[string]cel.Program{}
+)
+
+// conditionEnv returns the CEL environment shared by all conditions.
+func conditionEnv() (*cel.Env, error) {
+	envOnce.Do(func() {
+		attrs := cel.MapType(cel.StringType, cel.DynType)
+		env, envErr = cel.NewEnv(
+			cel.Variable("subject", attrs),
+			cel.Variable("resource", attrs),
+			cel.Variable("request", attrs),
+		)
+	})
+	return env, envErr
+}
+
+// compile parses and type-checks expr and builds a program for it.
+func compile(expr string) (cel.Program, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, errors.New("condition is empty")
+	}
+	if len(expr) > MaxConditionLength {
+		return nil, fmt.Errorf("condition must not exceed %d characters", MaxConditionLength)
+	}
+
+	e, err := conditionEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := e.Compile(expr)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("condition must evaluate to a bool, not %s", ast.OutputType())
+	}
+	return e.Program(ast, cel.CostLimit(conditionCostLimit))
+}
+
+// program returns the compiled program for expr, compiling it on first use.
+func program(expr string) (cel.Program, error) {
+	programsMu.RLock()
+	prg, ok := programs[expr]
+	programsMu.RUnlock()
+	if ok {
+		return prg, nil
+	}
+
+	prg, err := compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	programsMu.Lock()
+	if len(programs) >= maxCachedPrograms {
+		programs = map[string]cel.Program{}
+	}
+	programs[expr] = prg
+	programsMu.Unlock()
+	return prg, nil
+}
+
+// ValidateCondition reports whether expr is a valid condition: a CEL
+// expression over subject, resource and request that yields a bool.
+func ValidateCondition(expr string) error {
+	_, err := program(expr)
+	return err
+}
+
+// EvaluateCondition evaluates expr against in. An expression that fails to
+// compile or evaluate, or yields something other than a bool, is an error.
+func EvaluateCondition(expr string, in Input) (bool, error) {
+	prg, err := program(expr)
+	if err != nil {
+		return false, err
+	}
+	out, _, err := prg.Eval(in.activation())
+	if err != nil {
+		return false, err
+	}
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("condition evaluated to %s, not bool", out.Type().TypeName())
+	}
+	return result, nil
+}
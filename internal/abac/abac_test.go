@@ -0,0 +1,158 @@
+package abac
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testInput() Input {
+	return Input{
+		Subject: Subject{
+			ID:            "4a1e",
+			TenantID:      "t-1",
+			Roles:         []string{"editor"},
+			EmailVerified: true,
+			AuthMethod:    "jwt",
+		},
+		Resource: Resource{
+			Route:  "/api/v1/users/{user_uuid}",
+			Params: map[string]string{"user_uuid": "4a1e"},
+		},
+		Request: Request{
+			Method: "DELETE",
+			Path:   "/api/v1/users/4a1e",
+			IP:     "10.0.0.7",
+			Time:   time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestValidateCondition(t *testing.T) {
+	valid := []string{
+		`resource.params.user_uuid == subject.id`,
+		`"admin" in subject.roles`,
+		`request.ip.startsWith("10.")`,
+		`request.time.getHours("UTC") >= 18`,
+	}
+	for _, expr := range valid {
+		assert.NoError(t, ValidateCondition(expr), expr)
+	}
+
+	invalid := []string{
+		"",
+		"   ",
+		`subject.id ==`,
+		`unknown.id == "x"`,
+		`"not a bool"`,
+		strings.Repeat("a", MaxConditionLength+1),
+	}
+	for _, expr := range invalid {
+		assert.Error(t, ValidateCondition(expr), expr)
+	}
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	in := testInput()
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`resource.params.user_uuid == subject.id`, true},
+		{`"admin" in subject.roles`, false},
+		{`request.ip.startsWith("10.") && request.method == "DELETE"`, true},
+		{`request.time.getHours("UTC") >= 18`, true},
+		{`subject.email_verified && subject.auth_method == "personal_access_token"`, false},
+	}
+	for _, tt := range tests {
+		got, err := EvaluateCondition(tt.expr, in)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.want, got, tt.expr)
+	}
+
+	t.Run("missing key", func(t *testing.T) {
+		_, err := EvaluateCondition(`resource.params.role_uuid == "x"`, in)
+		assert.Error(t, err)
+	})
+
+	t.Run("dynamic non-bool result", func(t *testing.T) {
+		_, err := EvaluateCondition(`resource.params.user_uuid`, in)
+		assert.Error(t, err)
+	})
+
+	t.Run("empty attributes", func(t *testing.T) {
+		got, err := EvaluateCondition(`size(subject.roles) == 0 && size(resource.params) == 0`, Input{})
+		require.NoError(t, err)
+		assert.True(t, got)
+	})
+}
+
+func TestFindDeny(t *testing.T) {
+	policyUUID := uuid.New()
+	policies := []Policy{{
+		UUID: policyUUID,
+		Name: "guard",
+		Statements: []Statement{
+			{Effect: EffectAllow, Action: []string{"user:*"}},
+			{Effect: EffectDeny, Action: []string{"user:delete"}, Condition: `resource.params.user_uuid == subject.id`},
+			{Effect: EffectDeny, Action: []string{"role:*"}},
+			{Effect: EffectDeny, Action: []string{"client:*"}, Condition: `resource.params.missing == "x"`},
+		},
+	}}
+	in := testInput()
+
+	m := FindDeny(policies, "user:delete", in)
+	require.NotNil(t, m)
+	assert.Equal(t, policyUUID, m.PolicyUUID)
+	assert.Equal(t, "guard", m.PolicyName)
+	assert.Equal(t, 1, m.StatementIndex)
+	assert.NoError(t, m.Err)
+
+	other := in
+	other.Resource.Params = map[string]string{"user_uuid": "someone-else"}
+	assert.Nil(t, FindDeny(policies, "user:delete", other), "condition does not hold")
+
+	assert.Nil(t, FindDeny(policies, "user:read", in), "allow statements never deny")
+
+	m = FindDeny(policies, "role:update", in)
+	require.NotNil(t, m, "unconditional deny")
+	assert.Equal(t, 2, m.StatementIndex)
+
+	m = FindDeny(policies, "client:read", in)
+	require.NotNil(t, m, "a failing condition denies")
+	assert.Error(t, m.Err)
+}
+
+func TestParseStatements(t *testing.T) {
+	statements, err := ParseStatements([]byte(`{"version":"v1","statement":[{"effect":"deny","action":["user:*"],"resource":["auth:*"],"condition":"true"}]}`))
+	require.NoError(t, err)
+	require.Len(t, statements, 1)
+	assert.Equal(t, EffectDeny, statements[0].Effect)
+	assert.Equal(t, "true", statements[0].Condition)
+
+	_, err = ParseStatements([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		value    string
+		want     bool
+	}{
+		{[]string{"*"}, "user:update", true},
+		{[]string{"user:update"}, "user:update", true},
+		{[]string{"user:*"}, "user:update", true},
+		{[]string{"user:*"}, "role:update", false},
+		{[]string{"billing:*"}, "billing:invoices", true},
+		{[]string{"role:read", "user:*"}, "user:delete", true},
+		{nil, "user:update", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MatchPattern(tt.patterns, tt.value), "%v %s", tt.patterns, tt.value)
+	}
+}
@@ -0,0 +1,93 @@
+package abac
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Effects a statement can have.
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// Statement is one rule of a policy document.
+type Statement struct {
+	Effect    string   `json:"effect"`
+	Action    []string `json:"action"`
+	Resource  []string `json:"resource"`
+	Condition string   `json:"condition,omitempty"`
+}
+
+// Policy is an active policy with its parsed statements.
+type Policy struct {
+	UUID       uuid.UUID
+	Name       string
+	Statements []Statement
+}
+
+// ParseStatements extracts the statements of a policy document.
+func ParseStatements(document []byte) ([]Statement, error) {
+	var doc struct {
+		Statement []Statement `json:"statement"`
+	}
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, err
+	}
+	return doc.Statement, nil
+}
+
+// Match is a statement that applied to a request.
+type Match struct {
+	PolicyUUID     uuid.UUID
+	PolicyName     string
+	StatementIndex int
+	// Err is set when the statement's condition could not be evaluated. The
+	// statement is then treated as applying, so that a broken condition on
+	// a deny statement fails closed.
+	Err error
+}
+
+// FindDeny returns the first deny statement of policies that covers action
+// and whose condition, if any, holds for in. It returns nil when no statement
+// denies the action. Statements are matched on action alone; their resource
+// patterns name services and APIs, not routes.
+func FindDeny(policies []Policy, action string, in Input) *Match {
+	for _, p := range policies {
+		for i, st := range p.Statements {
+			if st.Effect != EffectDeny || !MatchPattern(st.Action, action) {
+				continue
+			}
+			m := &Match{PolicyUUID: p.UUID, PolicyName: p.Name, StatementIndex: i}
+			if st.Condition == "" {
+				return m
+			}
+			ok, err := EvaluateCondition(st.Condition, in)
+			if err != nil {
+				m.Err = err
+				return m
+			}
+			if ok {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+// MatchPattern reports whether value matches one of the policy patterns.
+// "*" matches everything and a trailing "*" matches any suffix, so "user:*"
+// covers "user:create".
+func MatchPattern(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == value {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
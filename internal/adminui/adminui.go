@@ -0,0 +1,58 @@
+// Package adminui serves the optional admin console from assets embedded in
+// the binary, so small deployments can manage tenants, users and clients
+// without running a separate frontend.
+//
+// The compiled single-page app is copied into the dist directory before
+// `go build`. A placeholder index.html is committed so the package always
+// builds.
+package adminui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+//go:embed all:dist
+var assets embed.FS
+
+// Handler serves the embedded admin UI. Mount it behind http.StripPrefix so
+// request paths are relative to the UI root.
+func Handler() http.Handler {
+	// "dist" is embedded above, so Sub cannot fail.
+	dist, _ := fs.Sub(assets, "dist")
+	return newHandler(dist)
+}
+
+// newHandler serves files from fsys. Paths without a file extension that do
+// not match a file are client-side routes and receive index.html; missing
+// assets (paths with an extension) return 404 so broken bundles are visible.
+func newHandler(fsys fs.FS) http.Handler {
+	fileServer := http.FileServerFS(fsys)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+
+		if name != "" {
+			if _, err := fs.Stat(fsys, name); err != nil {
+				if path.Ext(name) != "" {
+					http.NotFound(w, r)
+					return
+				}
+				name = ""
+			}
+		}
+
+		if name == "" || name == "index.html" {
+			// The shell must be revalidated so a new release is picked up;
+			// hashed assets can be cached normally.
+			w.Header().Set("Cache-Control", "no-cache")
+			r = r.Clone(r.Context())
+			r.URL.Path = "/"
+		}
+
+		fileServer.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,72 @@
+package adminui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"index.html":         {Data: []byte("<html>shell</html>")},
+		"assets/app-1a2b.js": {Data: []byte("console.log('app')")},
+	}
+}
+
+func get(t *testing.T, h http.Handler, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, target, nil))
+	return rr
+}
+
+func TestHandler(t *testing.T) {
+	h := newHandler(testFS())
+
+	t.Run("root serves index without caching", func(t *testing.T) {
+		rr := get(t, h, "/")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "shell")
+		assert.Equal(t, "no-cache", rr.Header().Get("Cache-Control"))
+	})
+
+	t.Run("index.html served directly without redirect", func(t *testing.T) {
+		rr := get(t, h, "/index.html")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "shell")
+	})
+
+	t.Run("asset served", func(t *testing.T) {
+		rr := get(t, h, "/assets/app-1a2b.js")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "console.log")
+		assert.Empty(t, rr.Header().Get("Cache-Control"))
+	})
+
+	t.Run("client-side route falls back to index", func(t *testing.T) {
+		rr := get(t, h, "/tenants/123/users")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "shell")
+	})
+
+	t.Run("missing asset → 404", func(t *testing.T) {
+		rr := get(t, h, "/assets/missing.js")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("path traversal stays inside the UI root", func(t *testing.T) {
+		rr := get(t, h, "/../../etc/passwd")
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "shell")
+	})
+}
+
+func TestHandler_EmbeddedPlaceholder(t *testing.T) {
+	rr := get(t, Handler(), "/")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "<html")
+}
@@ -2,7 +2,13 @@ package app
 
 import (
 	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/eventbus"
+	"github.com/maintainerd/auth/internal/eventstream"
+	"github.com/maintainerd/auth/internal/security"
 	"github.com/maintainerd/auth/internal/service"
+	"github.com/maintainerd/auth/internal/session"
+	"github.com/maintainerd/auth/internal/storage"
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
@@ -11,41 +17,92 @@ type App struct {
 	DB          *gorm.DB
 	RedisClient *redis.Client
 	Cache       *cache.Cache
+	// HostedSessions holds browser state for the hosted login pages.
+	HostedSessions session.Store
+	// BrowserSessions holds the sessions first-party web apps sign in with;
+	// nil unless browser sessions are enabled.
+	BrowserSessions session.Store
+	// EventBus carries committed domain events to their subscribers.
+	EventBus eventbus.Bus
+	// Storage keeps uploaded profile pictures and auth event archives; nil
+	// unless a storage provider is configured.
+	Storage storage.Store
 	// Services
-	ServiceService           service.ServiceService
-	APIService               service.APIService
-	PermissionService        service.PermissionService
-	PolicyService            service.PolicyService
-	TenantService            service.TenantService
-	TenantMemberService      service.TenantMemberService
-	IdentityProviderService  service.IdentityProviderService
-	ClientService            service.ClientService
-	RoleService              service.RoleService
-	UserService              service.UserService
-	RegisterService          service.RegisterService
-	LoginService             service.LoginService
-	ProfileService           service.ProfileService
-	UserSettingService       service.UserSettingService
-	InviteService            service.InviteService
-	ForgotPasswordService    service.ForgotPasswordService
-	ResetPasswordService     service.ResetPasswordService
-	SetupService             service.SetupService
-	SignupFlowService        service.SignupFlowService
-	APIKeyService            service.APIKeyService
-	SecuritySettingService   service.SecuritySettingService
-	IPRestrictionRuleService service.IPRestrictionRuleService
-	EmailTemplateService     service.EmailTemplateService
-	SMSTemplateService       service.SMSTemplateService
-	LoginTemplateService     service.LoginTemplateService
-	BrandingService          service.BrandingService
-	TenantSettingService     service.TenantSettingService
-	EmailConfigService       service.EmailConfigService
-	SMSConfigService         service.SMSConfigService
-	WebhookEndpointService   service.WebhookEndpointService
-	AuthEventService         service.AuthEventService
-	OAuthAuthorizeService    service.OAuthAuthorizeService
-	OAuthTokenService        service.OAuthTokenService
-	OAuthConsentService      service.OAuthConsentService
+	ServiceService             service.ServiceService
+	APIService                 service.APIService
+	PermissionService          service.PermissionService
+	PermissionGroupService     service.PermissionGroupService
+	PolicyService              service.PolicyService
+	PolicyEnforcementService   service.PolicyEnforcementService
+	TenantService              service.TenantService
+	TenantProvisioner          service.TenantProvisioner
+	TenantMemberService        service.TenantMemberService
+	IdentityProviderService    service.IdentityProviderService
+	ClientService              service.ClientService
+	RoleService                service.RoleService
+	GroupService               service.GroupService
+	ScopedRoleService          service.ScopedRoleService
+	UserService                service.UserService
+	UserImportService          service.UserImportService
+	RegisterService            service.RegisterService
+	LoginService               service.LoginService
+	FederatedLoginService      service.FederatedLoginService
+	HomeRealmService           service.HomeRealmService
+	ProfileService             service.ProfileService
+	UserSettingService         service.UserSettingService
+	InviteService              service.InviteService
+	ForgotPasswordService      service.ForgotPasswordService
+	ResetPasswordService       service.ResetPasswordService
+	SetupService               service.SetupService
+	SignupFlowService          service.SignupFlowService
+	APIKeyService              service.APIKeyService
+	PersonalAccessTokenService service.PersonalAccessTokenService
+	TrustedDeviceService       service.TrustedDeviceService
+	AccountIdentityService     service.AccountIdentityService
+	PermissionResolver         service.PermissionResolver
+	SecuritySettingService     service.SecuritySettingService
+	IPRestrictionRuleService   service.IPRestrictionRuleService
+	EmailTemplateService       service.EmailTemplateService
+	SMSTemplateService         service.SMSTemplateService
+	LoginTemplateService       service.LoginTemplateService
+	BrandingService            service.BrandingService
+	TenantSettingService       service.TenantSettingService
+	EmailConfigService         service.EmailConfigService
+	SMSConfigService           service.SMSConfigService
+	WebhookEndpointService     service.WebhookEndpointService
+	WebhookDeliveryService     service.WebhookDeliveryService
+	LoginHookService           service.LoginHookService
+	PolicyDocumentService      service.PolicyDocumentService
+	ParentalConsentService     service.ParentalConsentService
+	AccountRecoveryService     service.AccountRecoveryService
+	SecurityHoldService        service.SecurityHoldService
+	AuthEventService           service.AuthEventService
+	AuditArchiveService        service.AuditArchiveService
+	SIEMForwardingService      service.SIEMForwardingService
+	NotificationService        service.NotificationService
+	EventService               service.EventService
+	EventRelayService          service.EventRelayService
+	EventStreamRelayService    service.EventRelayService // nil unless event stream export is enabled
+	AuthzAuditService          service.AuthzAuditService
+	AuthzSimulationService     service.AuthzSimulationService
+	OAuthAuthorizeService      service.OAuthAuthorizeService
+	OAuthTokenService          service.OAuthTokenService
+	OAuthConsentService        service.OAuthConsentService
+	OAuthLogoutService         service.OAuthLogoutService
+	OnboardingService          service.OnboardingService
+	SelfServiceTenantService   service.SelfServiceTenantService
+	ImpersonationService       service.ImpersonationService
+	StepUpService              service.StepUpService
+	UsageService               service.UsageService
+	PlanService                service.PlanService
+	FeatureFlagService         service.FeatureFlagService
+	DebugService               service.DebugService
+	QueueHealthService         service.QueueHealthService
+	MigrationService           service.MigrationService
+	ConfigService              service.ConfigService
+	TokenValidationService     service.TokenValidationService
+	SigningKeyService          service.SigningKeyService // nil unless JWT key rotation is enabled
+	DataKeyService             service.DataKeyService    // nil unless column encryption is enabled
 }
 
 // NewApp wires the full dependency graph in two focused steps:
@@ -53,49 +110,101 @@ type App struct {
 //  2. initServices — every service, consuming repos
 //
 // Handler creation is delegated to transport packages (rest, grpcserver).
-func NewApp(db *gorm.DB, redisClient *redis.Client) *App {
-	r := initRepos(db)
+// profileEncryptor may be nil to store sensitive profile fields in plaintext.
+// browserSessions may be nil to disable cookie-based browser sessions.
+// eventStream may be nil to disable exporting domain events to a broker.
+// signingKeys.Encryptor may be nil to sign with the configured key only,
+// without storing or rotating signing keys.
+// dataKeys.Wrapper may be nil to store sensitive columns in plaintext.
+func NewApp(db *gorm.DB, redisClient *redis.Client, profileEncryptor *crypto.FieldEncryptor, authzAudit service.AuthzAuditConfig, breachChecker security.BreachedPasswordChecker, geoLocator security.GeoLocator, hostedSessions, browserSessions session.Store, eventStream *eventstream.Exporter, signingKeys service.SigningKeyConfig, dataKeys service.DataKeyConfig, profilePictures service.ProfilePictureConfig) *App {
 	appCache := cache.New(redisClient)
-	s := initServices(db, r, appCache)
+	r := initRepos(db, profileEncryptor, appCache)
+	s := initServices(db, r, appCache, authzAudit, breachChecker, geoLocator, eventStream, signingKeys, dataKeys, profilePictures)
 
 	return &App{
 		DB:          db,
 		RedisClient: redisClient,
 		Cache:       appCache,
+		// Hosted page and browser sessions
+		HostedSessions:  hostedSessions,
+		BrowserSessions: browserSessions,
+		EventBus:        s.eventBus,
+		Storage:         profilePictures.Store,
 		// Services
-		ServiceService:           s.serviceService,
-		APIService:               s.apiService,
-		PermissionService:        s.permissionService,
-		PolicyService:            s.policyService,
-		TenantService:            s.tenantService,
-		TenantMemberService:      s.tenantMemberService,
-		IdentityProviderService:  s.idpService,
-		ClientService:            s.clientService,
-		RoleService:              s.roleService,
-		UserService:              s.userService,
-		RegisterService:          s.registerService,
-		LoginService:             s.loginService,
-		ProfileService:           s.profileService,
-		UserSettingService:       s.userSettingService,
-		InviteService:            s.inviteService,
-		ForgotPasswordService:    s.forgotPasswordService,
-		ResetPasswordService:     s.resetPasswordService,
-		SetupService:             s.setupService,
-		SignupFlowService:        s.signupFlowService,
-		APIKeyService:            s.apiKeyService,
-		SecuritySettingService:   s.securitySettingService,
-		IPRestrictionRuleService: s.ipRestrictionRuleService,
-		EmailTemplateService:     s.emailTemplateService,
-		SMSTemplateService:       s.smsTemplateService,
-		LoginTemplateService:     s.loginTemplateService,
-		BrandingService:          s.brandingService,
-		TenantSettingService:     s.tenantSettingService,
-		EmailConfigService:       s.emailConfigService,
-		SMSConfigService:         s.smsConfigService,
-		WebhookEndpointService:   s.webhookEndpointService,
-		AuthEventService:         s.authEventService,
-		OAuthAuthorizeService:    s.oauthAuthorizeService,
-		OAuthTokenService:        s.oauthTokenService,
-		OAuthConsentService:      s.oauthConsentService,
+		ServiceService:             s.serviceService,
+		APIService:                 s.apiService,
+		PermissionService:          s.permissionService,
+		PermissionGroupService:     s.permissionGroupService,
+		PolicyService:              s.policyService,
+		PolicyEnforcementService:   s.policyEnforcementService,
+		TenantService:              s.tenantService,
+		TenantProvisioner:          s.tenantProvisioner,
+		TenantMemberService:        s.tenantMemberService,
+		IdentityProviderService:    s.idpService,
+		ClientService:              s.clientService,
+		RoleService:                s.roleService,
+		GroupService:               s.groupService,
+		ScopedRoleService:          s.scopedRoleService,
+		UserService:                s.userService,
+		UserImportService:          s.userImportService,
+		RegisterService:            s.registerService,
+		LoginService:               s.loginService,
+		FederatedLoginService:      s.federatedLoginService,
+		HomeRealmService:           s.homeRealmService,
+		ProfileService:             s.profileService,
+		UserSettingService:         s.userSettingService,
+		InviteService:              s.inviteService,
+		ForgotPasswordService:      s.forgotPasswordService,
+		ResetPasswordService:       s.resetPasswordService,
+		SetupService:               s.setupService,
+		SignupFlowService:          s.signupFlowService,
+		APIKeyService:              s.apiKeyService,
+		PersonalAccessTokenService: s.personalAccessTokenService,
+		TrustedDeviceService:       s.trustedDeviceService,
+		AccountIdentityService:     s.accountIdentityService,
+		PermissionResolver:         s.permissionResolver,
+		SecuritySettingService:     s.securitySettingService,
+		IPRestrictionRuleService:   s.ipRestrictionRuleService,
+		EmailTemplateService:       s.emailTemplateService,
+		SMSTemplateService:         s.smsTemplateService,
+		LoginTemplateService:       s.loginTemplateService,
+		BrandingService:            s.brandingService,
+		TenantSettingService:       s.tenantSettingService,
+		EmailConfigService:         s.emailConfigService,
+		SMSConfigService:           s.smsConfigService,
+		WebhookEndpointService:     s.webhookEndpointService,
+		WebhookDeliveryService:     s.webhookDeliveryService,
+		LoginHookService:           s.loginHookService,
+		PolicyDocumentService:      s.policyDocumentService,
+		ParentalConsentService:     s.parentalConsentService,
+		AccountRecoveryService:     s.accountRecoveryService,
+		SecurityHoldService:        s.securityHoldService,
+		AuthEventService:           s.authEventService,
+		AuditArchiveService:        s.auditArchiveService,
+		SIEMForwardingService:      s.siemForwardingService,
+		NotificationService:        s.notificationService,
+		EventService:               s.eventService,
+		EventRelayService:          s.eventRelayService,
+		EventStreamRelayService:    s.eventStreamRelayService,
+		AuthzAuditService:          s.authzAuditService,
+		AuthzSimulationService:     s.authzSimulationService,
+		OAuthAuthorizeService:      s.oauthAuthorizeService,
+		OAuthTokenService:          s.oauthTokenService,
+		OAuthConsentService:        s.oauthConsentService,
+		OAuthLogoutService:         s.oauthLogoutService,
+		OnboardingService:          s.onboardingService,
+		SelfServiceTenantService:   s.selfServiceTenantService,
+		ImpersonationService:       s.impersonationService,
+		StepUpService:              s.stepUpService,
+		UsageService:               s.usageService,
+		PlanService:                s.planService,
+		FeatureFlagService:         s.featureFlagService,
+		DebugService:               s.debugService,
+		QueueHealthService:         s.queueHealthService,
+		MigrationService:           s.migrationService,
+		ConfigService:              s.configService,
+		TokenValidationService:     s.tokenValidationService,
+		SigningKeyService:          s.signingKeyService,
+		DataKeyService:             s.dataKeyService,
 	}
 }
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/crypto"
 	"github.com/maintainerd/auth/internal/repository"
 	"gorm.io/gorm"
 )
@@ -14,6 +16,7 @@ type repos struct {
 	permissionRepo            repository.PermissionRepository
 	tenantRepo                repository.TenantRepository
 	tenantMemberRepo          repository.TenantMemberRepository
+	tenantDeprovisionRepo     repository.TenantDeprovisionRepository
 	userPoolRepo              repository.UserPoolRepository
 	idpRepo                   repository.IdentityProviderRepository
 	roleRepo                  repository.RoleRepository
@@ -25,6 +28,12 @@ type repos struct {
 	userRepo                  repository.UserRepository
 	userIdentityRepo          repository.UserIdentityRepository
 	userRoleRepo              repository.UserRoleRepository
+	groupRepo                 repository.GroupRepository
+	scopedUserRoleRepo        repository.ScopedUserRoleRepository
+	loginFingerprintRepo      repository.LoginFingerprintRepository
+	userImportJobRepo         repository.UserImportJobRepository
+	signingKeyRepo            repository.SigningKeyRepository
+	dataKeyRepo               repository.DataKeyRepository
 	userTokenRepo             repository.UserTokenRepository
 	profileRepo               repository.ProfileRepository
 	userSettingRepo           repository.UserSettingRepository
@@ -33,12 +42,19 @@ type repos struct {
 	smsTemplateRepo           repository.SMSTemplateRepository
 	loginTemplateRepo         repository.LoginTemplateRepository
 	policyRepo                repository.PolicyRepository
+	permissionGroupRepo       repository.PermissionGroupRepository
 	servicePolicyRepo         repository.ServicePolicyRepository
 	apiKeyRepo                repository.APIKeyRepository
 	apiKeyAPIRepo             repository.APIKeyAPIRepository
 	apiKeyPermissionRepo      repository.APIKeyPermissionRepository
+	personalAccessTokenRepo   repository.PersonalAccessTokenRepository
+	trustedDeviceRepo         repository.TrustedDeviceRepository
+	tenantUsageRepo           repository.TenantUsageRepository
+	planRepo                  repository.PlanRepository
+	featureFlagRepo           repository.FeatureFlagRepository
 	signupFlowRepo            repository.SignupFlowRepository
 	signupFlowRoleRepo        repository.SignupFlowRoleRepository
+	signupFlowSignupRepo      repository.SignupFlowSignupRepository
 	securitySettingRepo       repository.SecuritySettingRepository
 	securitySettingsAuditRepo repository.SecuritySettingsAuditRepository
 	ipRestrictionRuleRepo     repository.IPRestrictionRuleRepository
@@ -47,46 +63,72 @@ type repos struct {
 	emailConfigRepo           repository.EmailConfigRepository
 	smsConfigRepo             repository.SMSConfigRepository
 	webhookEndpointRepo       repository.WebhookEndpointRepository
+	webhookDeliveryRepo       repository.WebhookDeliveryRepository
+	loginHookRepo             repository.LoginHookRepository
 	authEventRepo             repository.AuthEventRepository
+	auditArchiveRunRepo       repository.AuditArchiveRunRepository
+	notificationSettingRepo   repository.NotificationSettingRepository
+	notificationLogRepo       repository.NotificationLogRepository
+	eventRepo                 repository.EventRepository
+	eventRelayCursorRepo      repository.EventRelayCursorRepository
 	oauthAuthCodeRepo         repository.OAuthAuthorizationCodeRepository
 	oauthRefreshTokenRepo     repository.OAuthRefreshTokenRepository
 	oauthConsentGrantRepo     repository.OAuthConsentGrantRepository
 	oauthConsentChallengeRepo repository.OAuthConsentChallengeRepository
+	policyDocumentRepo        repository.PolicyDocumentRepository
+	parentalConsentRepo       repository.ParentalConsentRepository
+	recoveryCodeRepo          repository.RecoveryCodeRepository
 }
 
-func initRepos(db *gorm.DB) *repos {
+// initRepos builds every repository bound to db. Client and tenant lookups
+// are read through appCache.
+func initRepos(db *gorm.DB, profileEncryptor *crypto.FieldEncryptor, appCache *cache.Cache) *repos {
 	return &repos{
 		serviceRepo:               repository.NewServiceRepository(db),
 		tenantServiceRepo:         repository.NewTenantServiceRepository(db),
 		apiRepo:                   repository.NewAPIRepository(db),
 		permissionRepo:            repository.NewPermissionRepository(db),
-		tenantRepo:                repository.NewTenantRepository(db),
+		tenantRepo:                repository.NewCachedTenantRepository(repository.NewTenantRepository(db), appCache),
 		tenantMemberRepo:          repository.NewTenantMemberRepository(db),
+		tenantDeprovisionRepo:     repository.NewTenantDeprovisionRepository(db),
 		userPoolRepo:              repository.NewUserPoolRepository(db),
 		idpRepo:                   repository.NewIdentityProviderRepository(db),
 		roleRepo:                  repository.NewRoleRepository(db),
 		rolePermissionRepo:        repository.NewRolePermissionRepository(db),
-		clientRepo:                repository.NewClientRepository(db),
+		clientRepo:                repository.NewCachedClientRepository(repository.NewClientRepository(db), appCache),
 		clientPermissionRepo:      repository.NewClientPermissionRepository(db),
 		clientAPIRepo:             repository.NewClientAPIRepository(db),
 		clientURIRepo:             repository.NewClientURIRepository(db),
 		userRepo:                  repository.NewUserRepository(db),
 		userIdentityRepo:          repository.NewUserIdentityRepository(db),
 		userRoleRepo:              repository.NewUserRoleRepository(db),
+		groupRepo:                 repository.NewGroupRepository(db),
+		scopedUserRoleRepo:        repository.NewScopedUserRoleRepository(db),
+		loginFingerprintRepo:      repository.NewLoginFingerprintRepository(db),
+		userImportJobRepo:         repository.NewUserImportJobRepository(db),
+		signingKeyRepo:            repository.NewSigningKeyRepository(db),
+		dataKeyRepo:               repository.NewDataKeyRepository(db),
 		userTokenRepo:             repository.NewUserTokenRepository(db),
-		profileRepo:               repository.NewProfileRepository(db),
+		profileRepo:               repository.NewProfileRepository(db, profileEncryptor),
 		userSettingRepo:           repository.NewUserSettingRepository(db),
 		inviteRepo:                repository.NewInviteRepository(db),
 		emailTemplateRepo:         repository.NewEmailTemplateRepository(db),
 		smsTemplateRepo:           repository.NewSMSTemplateRepository(db),
 		loginTemplateRepo:         repository.NewLoginTemplateRepository(db),
 		policyRepo:                repository.NewPolicyRepository(db),
+		permissionGroupRepo:       repository.NewPermissionGroupRepository(db),
 		servicePolicyRepo:         repository.NewServicePolicyRepository(db),
 		apiKeyRepo:                repository.NewAPIKeyRepository(db),
 		apiKeyAPIRepo:             repository.NewAPIKeyAPIRepository(db),
 		apiKeyPermissionRepo:      repository.NewAPIKeyPermissionRepository(db),
+		personalAccessTokenRepo:   repository.NewPersonalAccessTokenRepository(db),
+		trustedDeviceRepo:         repository.NewTrustedDeviceRepository(db),
+		tenantUsageRepo:           repository.NewTenantUsageRepository(db),
+		planRepo:                  repository.NewPlanRepository(db),
+		featureFlagRepo:           repository.NewFeatureFlagRepository(db),
 		signupFlowRepo:            repository.NewSignupFlowRepository(db),
 		signupFlowRoleRepo:        repository.NewSignupFlowRoleRepository(db),
+		signupFlowSignupRepo:      repository.NewSignupFlowSignupRepository(db),
 		securitySettingRepo:       repository.NewSecuritySettingRepository(db),
 		securitySettingsAuditRepo: repository.NewSecuritySettingsAuditRepository(db),
 		ipRestrictionRuleRepo:     repository.NewIPRestrictionRuleRepository(db),
@@ -95,10 +137,20 @@ func initRepos(db *gorm.DB) *repos {
 		emailConfigRepo:           repository.NewEmailConfigRepository(db),
 		smsConfigRepo:             repository.NewSMSConfigRepository(db),
 		webhookEndpointRepo:       repository.NewWebhookEndpointRepository(db),
+		webhookDeliveryRepo:       repository.NewWebhookDeliveryRepository(db),
+		loginHookRepo:             repository.NewLoginHookRepository(db),
 		authEventRepo:             repository.NewAuthEventRepository(db),
+		auditArchiveRunRepo:       repository.NewAuditArchiveRunRepository(db),
+		notificationSettingRepo:   repository.NewNotificationSettingRepository(db),
+		notificationLogRepo:       repository.NewNotificationLogRepository(db),
+		eventRepo:                 repository.NewEventRepository(db),
+		eventRelayCursorRepo:      repository.NewEventRelayCursorRepository(db),
 		oauthAuthCodeRepo:         repository.NewOAuthAuthorizationCodeRepository(db),
 		oauthRefreshTokenRepo:     repository.NewOAuthRefreshTokenRepository(db),
 		oauthConsentGrantRepo:     repository.NewOAuthConsentGrantRepository(db),
 		oauthConsentChallengeRepo: repository.NewOAuthConsentChallengeRepository(db),
+		policyDocumentRepo:        repository.NewPolicyDocumentRepository(db),
+		parentalConsentRepo:       repository.NewParentalConsentRepository(db),
+		recoveryCodeRepo:          repository.NewRecoveryCodeRepository(db),
 	}
 }
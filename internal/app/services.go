@@ -2,87 +2,229 @@ package app
 
 import (
 	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/claims"
+	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/eventbus"
+	"github.com/maintainerd/auth/internal/eventstream"
+	"github.com/maintainerd/auth/internal/federation"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/security"
 	"github.com/maintainerd/auth/internal/service"
+	"github.com/maintainerd/auth/internal/signupflow"
 	"gorm.io/gorm"
 )
 
 // svcs holds every service instance. Private to the app package.
 type svcs struct {
-	serviceService           service.ServiceService
-	apiService               service.APIService
-	permissionService        service.PermissionService
-	tenantService            service.TenantService
-	tenantMemberService      service.TenantMemberService
-	idpService               service.IdentityProviderService
-	clientService            service.ClientService
-	roleService              service.RoleService
-	userService              service.UserService
-	registerService          service.RegisterService
-	loginService             service.LoginService
-	profileService           service.ProfileService
-	userSettingService       service.UserSettingService
-	inviteService            service.InviteService
-	forgotPasswordService    service.ForgotPasswordService
-	resetPasswordService     service.ResetPasswordService
-	setupService             service.SetupService
-	signupFlowService        service.SignupFlowService
-	policyService            service.PolicyService
-	apiKeyService            service.APIKeyService
-	securitySettingService   service.SecuritySettingService
-	ipRestrictionRuleService service.IPRestrictionRuleService
-	emailTemplateService     service.EmailTemplateService
-	smsTemplateService       service.SMSTemplateService
-	loginTemplateService     service.LoginTemplateService
-	brandingService          service.BrandingService
-	tenantSettingService     service.TenantSettingService
-	emailConfigService       service.EmailConfigService
-	smsConfigService         service.SMSConfigService
-	webhookEndpointService   service.WebhookEndpointService
-	authEventService         service.AuthEventService
-	oauthAuthorizeService    service.OAuthAuthorizeService
-	oauthTokenService        service.OAuthTokenService
-	oauthConsentService      service.OAuthConsentService
+	eventBus                   eventbus.Bus
+	serviceService             service.ServiceService
+	apiService                 service.APIService
+	permissionService          service.PermissionService
+	permissionGroupService     service.PermissionGroupService
+	tenantService              service.TenantService
+	tenantProvisioner          service.TenantProvisioner
+	tenantMemberService        service.TenantMemberService
+	idpService                 service.IdentityProviderService
+	clientService              service.ClientService
+	roleService                service.RoleService
+	groupService               service.GroupService
+	scopedRoleService          service.ScopedRoleService
+	userService                service.UserService
+	userImportService          service.UserImportService
+	registerService            service.RegisterService
+	loginService               service.LoginService
+	federatedLoginService      service.FederatedLoginService
+	homeRealmService           service.HomeRealmService
+	profileService             service.ProfileService
+	userSettingService         service.UserSettingService
+	inviteService              service.InviteService
+	forgotPasswordService      service.ForgotPasswordService
+	resetPasswordService       service.ResetPasswordService
+	setupService               service.SetupService
+	signupFlowService          service.SignupFlowService
+	policyService              service.PolicyService
+	policyEnforcementService   service.PolicyEnforcementService
+	apiKeyService              service.APIKeyService
+	personalAccessTokenService service.PersonalAccessTokenService
+	trustedDeviceService       service.TrustedDeviceService
+	accountIdentityService     service.AccountIdentityService
+	permissionResolver         service.PermissionResolver
+	securitySettingService     service.SecuritySettingService
+	ipRestrictionRuleService   service.IPRestrictionRuleService
+	emailTemplateService       service.EmailTemplateService
+	smsTemplateService         service.SMSTemplateService
+	loginTemplateService       service.LoginTemplateService
+	brandingService            service.BrandingService
+	tenantSettingService       service.TenantSettingService
+	emailConfigService         service.EmailConfigService
+	smsConfigService           service.SMSConfigService
+	webhookEndpointService     service.WebhookEndpointService
+	webhookDeliveryService     service.WebhookDeliveryService
+	loginHookService           service.LoginHookService
+	policyDocumentService      service.PolicyDocumentService
+	parentalConsentService     service.ParentalConsentService
+	accountRecoveryService     service.AccountRecoveryService
+	securityHoldService        service.SecurityHoldService
+	authEventService           service.AuthEventService
+	auditArchiveService        service.AuditArchiveService
+	siemForwardingService      service.SIEMForwardingService
+	notificationService        service.NotificationService
+	eventService               service.EventService
+	eventRelayService          service.EventRelayService
+	eventStreamRelayService    service.EventRelayService
+	authzAuditService          service.AuthzAuditService
+	authzSimulationService     service.AuthzSimulationService
+	oauthAuthorizeService      service.OAuthAuthorizeService
+	oauthTokenService          service.OAuthTokenService
+	oauthConsentService        service.OAuthConsentService
+	oauthLogoutService         service.OAuthLogoutService
+	onboardingService          service.OnboardingService
+	selfServiceTenantService   service.SelfServiceTenantService
+	impersonationService       service.ImpersonationService
+	stepUpService              service.StepUpService
+	usageService               service.UsageService
+	planService                service.PlanService
+	featureFlagService         service.FeatureFlagService
+	debugService               service.DebugService
+	queueHealthService         service.QueueHealthService
+	migrationService           service.MigrationService
+	configService              service.ConfigService
+	tokenValidationService     service.TokenValidationService
+	signingKeyService          service.SigningKeyService
+	dataKeyService             service.DataKeyService
 }
 
-func initServices(db *gorm.DB, r *repos, appCache *cache.Cache) *svcs {
+func initServices(db *gorm.DB, r *repos, appCache *cache.Cache, authzAudit service.AuthzAuditConfig, breachChecker security.BreachedPasswordChecker, geoLocator security.GeoLocator, eventStream *eventstream.Exporter, signingKeys service.SigningKeyConfig, dataKeys service.DataKeyConfig, profilePictures service.ProfilePictureConfig) *svcs {
 	// Create authEventService first — it is injected into other services that
 	// need structured audit logging.
-	authEventSvc := service.NewAuthEventService(r.authEventRepo)
+	authEventSvc := service.NewAuthEventService(r.authEventRepo, geoLocator)
+	loginHookSvc := service.NewLoginHookService(r.loginHookRepo, authEventSvc)
+	notificationSvc := service.NewNotificationService(r.notificationSettingRepo, r.notificationLogRepo, r.userRepo, r.emailTemplateRepo)
+	trustedDeviceSvc := service.NewTrustedDeviceService(r.trustedDeviceRepo, r.securitySettingRepo, authEventSvc)
+	securityHoldSvc := service.NewSecurityHoldService(db, r.userRepo, r.userTokenRepo, r.oauthRefreshTokenRepo, r.eventRepo, notificationSvc, authEventSvc, appCache)
+	loginAnomalySvc := service.NewLoginAnomalyService(r.loginFingerprintRepo, r.securitySettingRepo, trustedDeviceSvc, notificationSvc, authEventSvc, securityHoldSvc, geoLocator)
+	bruteForceSvc := service.NewBruteForceService(r.ipRestrictionRuleRepo, r.securitySettingRepo, authEventSvc)
+	// Shared so the claims cache and circuit breakers span all token paths.
+	claimsEnricher := claims.NewEnricher(nil)
+	captchaVerifier := signupflow.NewCaptchaVerifier(nil)
+	// Shared so upstream providers' key sets are cached across requests.
+	idTokenVerifier := federation.NewVerifier(nil)
+
+	// Domain events recorded by the services reach these subscribers through
+	// the event relay once their transaction commits.
+	eventBus := eventbus.NewInProcess()
+	webhookDeliverySvc := service.NewWebhookDeliveryService(db, r.webhookEndpointRepo, r.webhookDeliveryRepo, r.authEventRepo)
+	eventBus.Subscribe("audit_log", []string{"*"}, service.LogDomainEvent)
+	eventBus.Subscribe("webhooks", model.EventTypes, webhookDeliverySvc.HandleEvent)
+	permissionResolver := service.NewPermissionResolver(r.userRepo, r.scopedUserRoleRepo, r.groupRepo, appCache)
+	eventBus.Subscribe("permission_cache", service.PermissionResolverEventTypes, permissionResolver.HandleEvent)
+	eventBus.Subscribe("client_lookup_cache", service.ClientLookupEventTypes, service.NewClientLookupInvalidator(appCache))
+
+	// The broker export runs on its own bus and relay so that a broker
+	// outage does not hold back webhooks or the audit log.
+	var eventStreamRelaySvc service.EventRelayService
+	relays := []string{service.EventRelayBus}
+	if eventStream != nil {
+		relays = append(relays, service.EventRelayStream)
+		streamBus := eventbus.NewInProcess()
+		streamBus.Subscribe("event_stream", eventStream.Types(), eventStream.Handle)
+		eventStreamRelaySvc = service.NewEventRelayService(service.EventRelayStream, db, r.eventRepo, r.eventRelayCursorRepo, streamBus)
+	}
+
+	authzAuditSvc := service.NewAuthzAuditService(authEventSvc, authzAudit)
+	tenantProvisioner := service.NewTenantProvisioner(db, r.tenantRepo, r.userRepo, r.userPoolRepo, r.clientRepo, r.tenantDeprovisionRepo)
+	// Consulted by the services that create users and clients or configure
+	// features of a tenant.
+	planSvc := service.NewPlanService(db, r.planRepo, r.tenantRepo, r.userIdentityRepo, r.clientRepo)
+
+	// Signing keys are only stored and rotated when they can be encrypted.
+	var signingKeySvc service.SigningKeyService
+	if signingKeys.Encryptor != nil {
+		signingKeySvc = service.NewSigningKeyService(db, r.signingKeyRepo, signingKeys)
+	}
+
+	// Columns are only encrypted when the data keys can be wrapped.
+	var dataKeySvc service.DataKeyService
+	if dataKeys.Wrapper != nil {
+		dataKeySvc = service.NewDataKeyService(db, r.dataKeyRepo, dataKeys)
+	}
 
 	return &svcs{
-		serviceService:           service.NewServiceService(db, r.serviceRepo, r.tenantServiceRepo, r.apiRepo, r.servicePolicyRepo, r.policyRepo),
-		apiService:               service.NewAPIService(db, r.apiRepo, r.serviceRepo, r.tenantServiceRepo),
-		permissionService:        service.NewPermissionService(db, r.permissionRepo, r.apiRepo, r.roleRepo, r.clientRepo, appCache),
-		tenantService:            service.NewTenantService(db, r.tenantRepo),
-		tenantMemberService:      service.NewTenantMemberService(db, r.tenantMemberRepo, r.userRepo, r.tenantRepo),
-		idpService:               service.NewIdentityProviderService(db, r.idpRepo, r.tenantRepo, r.userRepo),
-		clientService:            service.NewClientService(db, r.clientRepo, r.clientURIRepo, r.idpRepo, r.permissionRepo, r.clientPermissionRepo, r.clientAPIRepo, r.apiRepo, r.userRepo, r.tenantRepo),
-		roleService:              service.NewRoleService(db, r.roleRepo, r.permissionRepo, r.rolePermissionRepo, r.userRepo, r.tenantRepo, appCache),
-		userService:              service.NewUserService(db, r.userRepo, r.userIdentityRepo, r.userRoleRepo, r.roleRepo, r.tenantRepo, r.idpRepo, r.clientRepo, r.userPoolRepo, appCache),
-		registerService:          service.NewRegistrationService(db, r.clientRepo, r.userRepo, r.userRoleRepo, r.userTokenRepo, r.userIdentityRepo, r.roleRepo, r.inviteRepo, r.idpRepo),
-		loginService:             service.NewLoginService(db, r.clientRepo, r.userRepo, r.userTokenRepo, r.userIdentityRepo, r.idpRepo, authEventSvc),
-		profileService:           service.NewProfileService(db, r.profileRepo, r.userRepo),
-		userSettingService:       service.NewUserSettingService(db, r.userSettingRepo, r.userRepo),
-		inviteService:            service.NewInviteService(db, r.inviteRepo, r.clientRepo, r.roleRepo, r.emailTemplateRepo),
-		forgotPasswordService:    service.NewForgotPasswordService(db, r.userRepo, r.userTokenRepo, r.clientRepo, r.emailTemplateRepo),
-		resetPasswordService:     service.NewResetPasswordService(db, r.userRepo, r.userTokenRepo, r.clientRepo),
-		setupService:             service.NewSetupService(db, r.userRepo, r.tenantRepo, r.tenantMemberRepo, r.clientRepo, r.idpRepo, r.roleRepo, r.userRoleRepo, r.userTokenRepo, r.userIdentityRepo, r.profileRepo),
-		signupFlowService:        service.NewSignupFlowService(db, r.signupFlowRepo, r.signupFlowRoleRepo, r.roleRepo, r.clientRepo),
-		policyService:            service.NewPolicyService(db, r.policyRepo, r.serviceRepo, r.apiRepo),
-		apiKeyService:            service.NewAPIKeyService(db, r.apiKeyRepo, r.apiKeyAPIRepo, r.apiKeyPermissionRepo, r.apiRepo, r.userRepo, r.permissionRepo),
-		securitySettingService:   service.NewSecuritySettingService(db, r.securitySettingRepo, r.securitySettingsAuditRepo),
-		ipRestrictionRuleService: service.NewIPRestrictionRuleService(db, r.ipRestrictionRuleRepo),
-		emailTemplateService:     service.NewEmailTemplateService(db, r.emailTemplateRepo),
-		smsTemplateService:       service.NewSMSTemplateService(db, r.smsTemplateRepo),
-		loginTemplateService:     service.NewLoginTemplateService(r.loginTemplateRepo),
-		brandingService:          service.NewBrandingService(r.brandingRepo),
-		tenantSettingService:     service.NewTenantSettingService(r.tenantSettingRepo),
-		emailConfigService:       service.NewEmailConfigService(r.emailConfigRepo),
-		smsConfigService:         service.NewSMSConfigService(r.smsConfigRepo),
-		webhookEndpointService:   service.NewWebhookEndpointService(r.webhookEndpointRepo),
-		authEventService:         authEventSvc,
-		oauthAuthorizeService:    service.NewOAuthAuthorizeService(db, r.clientRepo, r.clientURIRepo, r.oauthAuthCodeRepo, r.oauthConsentGrantRepo, r.oauthConsentChallengeRepo, authEventSvc),
-		oauthTokenService:        service.NewOAuthTokenService(db, r.clientRepo, r.oauthAuthCodeRepo, r.oauthRefreshTokenRepo, r.userRepo, r.userIdentityRepo, authEventSvc),
-		oauthConsentService:      service.NewOAuthConsentService(r.oauthConsentGrantRepo),
+		eventBus:                   eventBus,
+		serviceService:             service.NewServiceService(db, r.serviceRepo, r.tenantServiceRepo, r.apiRepo, r.servicePolicyRepo, r.policyRepo),
+		apiService:                 service.NewAPIService(db, r.apiRepo, r.serviceRepo, r.tenantServiceRepo),
+		permissionService:          service.NewPermissionService(db, r.permissionRepo, r.apiRepo, r.roleRepo, r.clientRepo, appCache),
+		permissionGroupService:     service.NewPermissionGroupService(db, r.permissionGroupRepo, r.permissionRepo),
+		tenantService:              service.NewTenantService(db, r.tenantRepo, tenantProvisioner),
+		tenantProvisioner:          tenantProvisioner,
+		tenantMemberService:        service.NewTenantMemberService(db, r.tenantMemberRepo, r.userRepo, r.tenantRepo),
+		idpService:                 service.NewIdentityProviderService(db, r.idpRepo, r.tenantRepo, r.userRepo),
+		clientService:              service.NewClientService(db, r.clientRepo, r.clientURIRepo, r.idpRepo, r.permissionRepo, r.clientPermissionRepo, r.clientAPIRepo, r.apiRepo, r.userRepo, r.tenantRepo, r.eventRepo, r.authEventRepo, planSvc),
+		roleService:                service.NewRoleService(db, r.roleRepo, r.permissionRepo, r.rolePermissionRepo, r.userRepo, r.tenantRepo, r.eventRepo, appCache),
+		groupService:               service.NewGroupService(db, r.groupRepo, r.userRepo, r.roleRepo, r.tenantRepo, r.eventRepo, appCache),
+		scopedRoleService:          service.NewScopedRoleService(db, r.scopedUserRoleRepo, r.userRepo, r.roleRepo, r.tenantRepo, r.clientRepo, r.groupRepo, r.eventRepo, appCache),
+		userService:                service.NewUserService(db, r.userRepo, r.userIdentityRepo, r.userRoleRepo, r.roleRepo, r.tenantRepo, r.idpRepo, r.clientRepo, r.userPoolRepo, r.tenantSettingRepo, r.securitySettingRepo, r.userTokenRepo, r.oauthRefreshTokenRepo, r.emailTemplateRepo, r.eventRepo, authEventSvc, breachChecker, appCache, planSvc),
+		userImportService:          service.NewUserImportService(db, r.userImportJobRepo, r.userRepo, r.userIdentityRepo, r.userRoleRepo, r.roleRepo, r.clientRepo, r.tenantSettingRepo, r.securitySettingRepo, r.eventRepo),
+		registerService:            service.NewRegistrationService(db, r.clientRepo, r.userRepo, r.userRoleRepo, r.userTokenRepo, r.userIdentityRepo, r.roleRepo, r.inviteRepo, r.idpRepo, r.eventRepo, r.tenantSettingRepo, r.securitySettingRepo, r.signupFlowRepo, r.signupFlowRoleRepo, r.signupFlowSignupRepo, r.emailTemplateRepo, breachChecker, captchaVerifier, loginHookSvc, claimsEnricher, planSvc, r.policyDocumentRepo, r.parentalConsentRepo, r.profileRepo),
+		loginService:               service.NewLoginService(db, r.clientRepo, r.userRepo, r.userTokenRepo, r.userIdentityRepo, r.idpRepo, r.tenantSettingRepo, r.securitySettingRepo, authEventSvc, loginHookSvc, claimsEnricher, loginAnomalySvc, bruteForceSvc, r.policyDocumentRepo),
+		federatedLoginService:      service.NewFederatedLoginService(db, r.clientRepo, r.userRepo, r.userIdentityRepo, r.roleRepo, r.userRoleRepo, r.eventRepo, idTokenVerifier, authEventSvc, loginHookSvc, claimsEnricher, loginAnomalySvc),
+		homeRealmService:           service.NewHomeRealmService(r.clientRepo, r.idpRepo),
+		profileService:             service.NewProfileService(db, r.profileRepo, r.userRepo, profilePictures),
+		userSettingService:         service.NewUserSettingService(db, r.userSettingRepo, r.userRepo, r.tenantSettingRepo),
+		inviteService:              service.NewInviteService(db, r.inviteRepo, r.clientRepo, r.roleRepo, r.emailTemplateRepo),
+		forgotPasswordService:      service.NewForgotPasswordService(db, r.userRepo, r.userTokenRepo, r.clientRepo, r.emailTemplateRepo, r.tenantSettingRepo),
+		resetPasswordService:       service.NewResetPasswordService(db, r.userRepo, r.userTokenRepo, r.clientRepo, r.tenantSettingRepo, r.securitySettingRepo, breachChecker),
+		setupService:               service.NewSetupService(db, r.userRepo, r.tenantRepo, r.tenantMemberRepo, r.clientRepo, r.idpRepo, r.roleRepo, r.userRoleRepo, r.userTokenRepo, r.userIdentityRepo, r.profileRepo),
+		signupFlowService:          service.NewSignupFlowService(db, r.signupFlowRepo, r.signupFlowRoleRepo, r.roleRepo, r.clientRepo),
+		policyService:              service.NewPolicyService(db, r.policyRepo, r.serviceRepo, r.apiRepo),
+		policyEnforcementService:   service.NewPolicyEnforcementService(r.policyRepo),
+		apiKeyService:              service.NewAPIKeyService(db, r.apiKeyRepo, r.apiKeyAPIRepo, r.apiKeyPermissionRepo, r.apiRepo, r.userRepo, r.permissionRepo, r.eventRepo, appCache),
+		personalAccessTokenService: service.NewPersonalAccessTokenService(r.personalAccessTokenRepo),
+		trustedDeviceService:       trustedDeviceSvc,
+		accountIdentityService:     service.NewAccountIdentityService(r.userIdentityRepo, r.clientRepo, idTokenVerifier, authEventSvc),
+		permissionResolver:         permissionResolver,
+		securitySettingService:     service.NewSecuritySettingService(db, r.securitySettingRepo, r.securitySettingsAuditRepo, planSvc),
+		ipRestrictionRuleService:   service.NewIPRestrictionRuleService(db, r.ipRestrictionRuleRepo),
+		emailTemplateService:       service.NewEmailTemplateService(db, r.emailTemplateRepo),
+		smsTemplateService:         service.NewSMSTemplateService(db, r.smsTemplateRepo),
+		loginTemplateService:       service.NewLoginTemplateService(r.loginTemplateRepo, r.clientRepo, r.brandingRepo),
+		brandingService:            service.NewBrandingService(r.brandingRepo),
+		tenantSettingService:       service.NewTenantSettingService(r.tenantSettingRepo),
+		emailConfigService:         service.NewEmailConfigService(r.emailConfigRepo),
+		smsConfigService:           service.NewSMSConfigService(r.smsConfigRepo),
+		webhookEndpointService:     service.NewWebhookEndpointService(r.webhookEndpointRepo, r.eventRepo, r.authEventRepo),
+		webhookDeliveryService:     webhookDeliverySvc,
+		loginHookService:           loginHookSvc,
+		policyDocumentService:      service.NewPolicyDocumentService(r.policyDocumentRepo, r.clientRepo, r.userRepo),
+		parentalConsentService:     service.NewParentalConsentService(db, r.parentalConsentRepo, r.userRepo, r.eventRepo, authEventSvc),
+		accountRecoveryService:     service.NewAccountRecoveryService(db, r.userRepo, r.userTokenRepo, r.recoveryCodeRepo, r.clientRepo, r.oauthRefreshTokenRepo, r.emailTemplateRepo, r.tenantSettingRepo, r.securitySettingRepo, r.eventRepo, breachChecker, authEventSvc, appCache),
+		securityHoldService:        securityHoldSvc,
+		authEventService:           authEventSvc,
+		auditArchiveService:        service.NewAuditArchiveService(r.authEventRepo, r.auditArchiveRunRepo, r.tenantRepo, r.tenantSettingRepo, profilePictures.Store),
+		siemForwardingService:      service.NewSIEMForwardingService(r.clientRepo, r.tenantSettingRepo),
+		notificationService:        notificationSvc,
+		eventService:               service.NewEventService(r.eventRepo),
+		eventRelayService:          service.NewEventRelayService(service.EventRelayBus, db, r.eventRepo, r.eventRelayCursorRepo, eventBus),
+		eventStreamRelayService:    eventStreamRelaySvc,
+		authzAuditService:          authzAuditSvc,
+		authzSimulationService:     service.NewAuthzSimulationService(r.userRepo, r.roleRepo, r.policyRepo),
+		oauthAuthorizeService:      service.NewOAuthAuthorizeService(db, r.clientRepo, r.clientURIRepo, r.oauthAuthCodeRepo, r.oauthConsentGrantRepo, r.oauthConsentChallengeRepo, authEventSvc),
+		oauthTokenService:          service.NewOAuthTokenService(db, r.clientRepo, r.apiRepo, r.clientAPIRepo, r.clientPermissionRepo, r.oauthAuthCodeRepo, r.oauthRefreshTokenRepo, r.userRepo, r.userIdentityRepo, r.securitySettingRepo, authEventSvc, claimsEnricher),
+		oauthConsentService:        service.NewOAuthConsentService(db, r.oauthConsentGrantRepo, r.oauthRefreshTokenRepo),
+		oauthLogoutService:         service.NewOAuthLogoutService(r.clientRepo, r.oauthRefreshTokenRepo, r.userRepo, r.userIdentityRepo, authEventSvc),
+		onboardingService:          service.NewOnboardingService(db, r.tenantRepo, r.idpRepo, r.clientRepo, r.roleRepo, r.userRepo, r.userIdentityRepo, r.userRoleRepo, r.tenantMemberRepo, r.emailConfigRepo, r.signupFlowRepo, r.signupFlowRoleRepo, r.brandingRepo, r.securitySettingRepo),
+		selfServiceTenantService:   service.NewSelfServiceTenantService(db, r.tenantRepo, r.tenantMemberRepo, tenantProvisioner),
+		impersonationService:       service.NewImpersonationService(db, r.clientRepo, r.userRepo, r.userIdentityRepo, r.authEventRepo),
+		stepUpService:              service.NewStepUpService(appCache, authEventSvc, config.StepUpMaxAge),
+		usageService:               service.NewUsageService(appCache, r.tenantUsageRepo, r.tenantSettingRepo, r.tenantRepo),
+		planService:                planSvc,
+		featureFlagService:         service.NewFeatureFlagService(db, r.featureFlagRepo, r.tenantRepo, appCache),
+		debugService:               service.NewDebugService(r.tenantRepo, r.clientRepo, authEventSvc),
+		queueHealthService:         service.NewQueueHealthService(r.webhookDeliveryRepo, r.eventRepo, r.eventRelayCursorRepo, relays),
+		migrationService:           service.NewMigrationService(db),
+		configService:              service.NewConfigService(config.Reload, authEventSvc),
+		tokenValidationService:     service.NewTokenValidationService(r.userRepo, r.tenantRepo, authzAuditSvc),
+		signingKeyService:          signingKeySvc,
+		dataKeyService:             dataKeySvc,
 	}
 }
@@ -4,12 +4,14 @@
 // below. The REST handler layer uses [resp.HandleServiceError] to translate
 // these into the correct HTTP status codes automatically:
 //
-//	NotFoundError     → 404
-//	ConflictError     → 409
-//	ForbiddenError    → 403
-//	UnauthorizedError → 401
-//	ValidationError   → 400
-//	InternalError     → 500 (logged server-side; generic message sent to client)
+//	NotFoundError        → 404
+//	ConflictError        → 409
+//	PreconditionError    → 412
+//	ForbiddenError       → 403
+//	UnauthorizedError    → 401
+//	ValidationError      → 400
+//	TooManyRequestsError → 429
+//	InternalError        → 500 (logged server-side; generic message sent to client)
 //
 // Usage in a service:
 //
@@ -63,6 +65,21 @@ func (e *ConflictError) Error() string {
 	return e.Reason
 }
 
+// ---------------------------------------------------------------------------
+// PreconditionError
+// ---------------------------------------------------------------------------
+
+// PreconditionError indicates a conditional request whose precondition no
+// longer holds, e.g. an If-Match ETag naming a version of the record that has
+// since been changed.
+type PreconditionError struct {
+	Reason string
+}
+
+func (e *PreconditionError) Error() string {
+	return e.Reason
+}
+
 // ---------------------------------------------------------------------------
 // ForbiddenError
 // ---------------------------------------------------------------------------
@@ -109,6 +126,20 @@ func (e *ValidationError) Error() string {
 	return e.Reason
 }
 
+// ---------------------------------------------------------------------------
+// TooManyRequestsError
+// ---------------------------------------------------------------------------
+
+// TooManyRequestsError indicates the caller has used up a limit, such as a
+// tenant quota, and must wait before trying again.
+type TooManyRequestsError struct {
+	Reason string
+}
+
+func (e *TooManyRequestsError) Error() string {
+	return e.Reason
+}
+
 // ---------------------------------------------------------------------------
 // InternalError
 // ---------------------------------------------------------------------------
@@ -160,6 +191,13 @@ func NewConflict(reason string) *ConflictError {
 	return &ConflictError{Reason: reason}
 }
 
+// NewPrecondition creates a [PreconditionError] with the given reason.
+//
+//	apperror.NewPrecondition("role was changed by another request")
+func NewPrecondition(reason string) *PreconditionError {
+	return &PreconditionError{Reason: reason}
+}
+
 // NewForbidden creates a [ForbiddenError] with the given reason.
 //
 //	apperror.NewForbidden("profile does not belong to user")
@@ -182,6 +220,13 @@ func NewValidation(reason string) *ValidationError {
 	return &ValidationError{Reason: reason}
 }
 
+// NewTooManyRequests creates a [TooManyRequestsError] with the given reason.
+//
+//	apperror.NewTooManyRequests("monthly token quota exceeded")
+func NewTooManyRequests(reason string) *TooManyRequestsError {
+	return &TooManyRequestsError{Reason: reason}
+}
+
 // NewInternal creates an [InternalError] that wraps an underlying error with context.
 // The underlying error is preserved for [errors.Unwrap] and server-side logging.
 //
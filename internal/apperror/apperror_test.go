@@ -31,6 +31,14 @@ func TestConflictError(t *testing.T) {
 	assert.True(t, errors.As(err, &target))
 }
 
+func TestPreconditionError(t *testing.T) {
+	err := NewPrecondition("role was changed by another request")
+	assert.Equal(t, "role was changed by another request", err.Error())
+
+	var target *PreconditionError
+	assert.True(t, errors.As(err, &target))
+}
+
 func TestForbiddenError(t *testing.T) {
 	err := NewForbidden("access denied: user does not have access to this tenant")
 	assert.Equal(t, "access denied: user does not have access to this tenant", err.Error())
@@ -62,6 +70,14 @@ func TestValidationError(t *testing.T) {
 	assert.True(t, errors.As(err, &target))
 }
 
+func TestTooManyRequestsError(t *testing.T) {
+	err := NewTooManyRequests("monthly token quota exceeded")
+	assert.Equal(t, "monthly token quota exceeded", err.Error())
+
+	var target *TooManyRequestsError
+	assert.True(t, errors.As(err, &target))
+}
+
 func TestInternalError(t *testing.T) {
 	t.Run("with wrapped error", func(t *testing.T) {
 		inner := fmt.Errorf("connection refused")
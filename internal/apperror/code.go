@@ -0,0 +1,226 @@
+package apperror
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+)
+
+// Code is a stable, machine-readable error identifier such as "AUTH-1001".
+// Codes are never reused or renumbered, so SDKs and UIs can branch on them
+// instead of parsing the English message. Every code must be registered in
+// [registry].
+//
+// Prefixes group codes by area:
+//
+//	GEN  generic errors derived from the HTTP status
+//	AUTH authentication and authorization
+//	KEY  API keys
+//	TEN  tenants
+//	USR  users and accounts
+type Code string
+
+// Generic codes, used when no more specific code applies.
+const (
+	CodeBadRequest         Code = "GEN-0400"
+	CodeValidationFailed   Code = "GEN-1000"
+	CodeUnauthorized       Code = "GEN-0401"
+	CodeForbidden          Code = "GEN-0403"
+	CodeNotFound           Code = "GEN-0404"
+	CodeConflict           Code = "GEN-0409"
+	CodePreconditionFailed Code = "GEN-0412"
+	CodeTooManyRequests    Code = "GEN-0429"
+	CodeInternal           Code = "GEN-0500"
+	CodeServiceUnavailable Code = "GEN-0503"
+)
+
+// Authentication and authorization codes.
+const (
+	CodeInvalidCredentials       Code = "AUTH-1001"
+	CodeAccountInactive          Code = "AUTH-1002"
+	CodeMissingCredentials       Code = "AUTH-1003"
+	CodeInvalidToken             Code = "AUTH-1004"
+	CodeInsufficientPermissions  Code = "AUTH-1005"
+	CodeInvalidInvite            Code = "AUTH-1006"
+	CodeInvalidCSRFToken         Code = "AUTH-1007"
+	CodeStepUpRequired           Code = "AUTH-1008"
+	CodePolicyAcceptanceRequired Code = "AUTH-1009"
+	CodeAccountSecurityHold      Code = "AUTH-1010"
+	CodeIPBlocked                Code = "AUTH-1011"
+	CodeSessionLimitReached      Code = "AUTH-1012"
+	CodeSessionIdleTimeout       Code = "AUTH-1013"
+	CodeSessionLifetimeExceeded  Code = "AUTH-1014"
+)
+
+// API key codes.
+const (
+	CodeInvalidAPIKey     Code = "KEY-4001"
+	CodeAPIKeyRateLimited Code = "KEY-4002"
+)
+
+// Tenant codes.
+const (
+	CodeTenantNotFound        Code = "TEN-2001"
+	CodeSystemTenantProtected Code = "TEN-2002"
+	CodeTenantHasChildren     Code = "TEN-2003"
+	CodeTenantHierarchyCycle  Code = "TEN-2004"
+	CodeTenantQuotaExceeded   Code = "TEN-2005"
+	CodePlanLimitReached      Code = "TEN-2006"
+	CodePlanFeatureDisabled   Code = "TEN-2007"
+)
+
+// User codes.
+const (
+	CodeUserAlreadyExists Code = "USR-3001"
+	CodeUsernameTaken     Code = "USR-3002"
+	CodeUserNotFound      Code = "USR-3003"
+)
+
+// TypeBase prefixes a code to form the URI of its entry in the error code
+// registry. It is the problem "type" of API errors and the error_uri of
+// OAuth errors that carry a code.
+const TypeBase = "/api/v1/errors/"
+
+// CodeInfo describes a registered code. It is what the error code registry
+// endpoint returns.
+type CodeInfo struct {
+	// Code is the stable identifier, e.g. "AUTH-1001".
+	Code Code `json:"code"`
+	// Name is a snake_case alias for the code, e.g. "invalid_credentials".
+	Name string `json:"name"`
+	// Status is the HTTP status the code is returned with.
+	Status int `json:"status"`
+	// Title is the short, fixed summary used as the problem title.
+	Title string `json:"title"`
+}
+
+// registry lists every code the API can return.
+var registry = []CodeInfo{
+	{CodeBadRequest, "bad_request", http.StatusBadRequest, "Bad request"},
+	{CodeValidationFailed, "validation_failed", http.StatusBadRequest, "Validation failed"},
+	{CodeUnauthorized, "unauthorized", http.StatusUnauthorized, "Unauthorized"},
+	{CodeForbidden, "forbidden", http.StatusForbidden, "Forbidden"},
+	{CodeNotFound, "not_found", http.StatusNotFound, "Not found"},
+	{CodeConflict, "conflict", http.StatusConflict, "Conflict"},
+	{CodePreconditionFailed, "precondition_failed", http.StatusPreconditionFailed, "Precondition failed"},
+	{CodeTooManyRequests, "too_many_requests", http.StatusTooManyRequests, "Too many requests"},
+	{CodeInternal, "internal_error", http.StatusInternalServerError, "Internal server error"},
+	{CodeServiceUnavailable, "service_unavailable", http.StatusServiceUnavailable, "Service unavailable"},
+
+	{CodeInvalidCredentials, "invalid_credentials", http.StatusUnauthorized, "Invalid credentials"},
+	{CodeAccountInactive, "account_inactive", http.StatusUnauthorized, "Account is not active"},
+	{CodeMissingCredentials, "missing_credentials", http.StatusUnauthorized, "No credentials provided"},
+	{CodeInvalidToken, "invalid_token", http.StatusUnauthorized, "Invalid or expired token"},
+	{CodeInsufficientPermissions, "insufficient_permissions", http.StatusForbidden, "Insufficient permissions"},
+	{CodeInvalidInvite, "invalid_invite", http.StatusUnauthorized, "Invalid or expired invite"},
+	{CodeInvalidCSRFToken, "invalid_csrf_token", http.StatusForbidden, "Invalid or missing CSRF token"},
+	{CodeStepUpRequired, "step_up_required", http.StatusUnauthorized, "Re-authentication required"},
+	{CodePolicyAcceptanceRequired, "policy_acceptance_required", http.StatusForbidden, "Policy acceptance required"},
+	{CodeAccountSecurityHold, "account_security_hold", http.StatusUnauthorized, "Account is on security hold"},
+	{CodeIPBlocked, "ip_blocked", http.StatusForbidden, "Sign in from this address is blocked"},
+	{CodeSessionLimitReached, "session_limit_reached", http.StatusBadRequest, "Concurrent session limit reached"},
+	{CodeSessionIdleTimeout, "session_idle_timeout", http.StatusBadRequest, "Session expired due to inactivity"},
+	{CodeSessionLifetimeExceeded, "session_lifetime_exceeded", http.StatusBadRequest, "Session lifetime exceeded, sign in again"},
+
+	{CodeInvalidAPIKey, "invalid_api_key", http.StatusUnauthorized, "Invalid API key"},
+	{CodeAPIKeyRateLimited, "api_key_rate_limited", http.StatusTooManyRequests, "API key rate limit exceeded"},
+
+	{CodeTenantNotFound, "tenant_not_found", http.StatusNotFound, "Tenant not found"},
+	{CodeSystemTenantProtected, "system_tenant_protected", http.StatusBadRequest, "System tenant cannot be changed"},
+	{CodeTenantHasChildren, "tenant_has_children", http.StatusConflict, "Tenant has child tenants"},
+	{CodeTenantHierarchyCycle, "tenant_hierarchy_cycle", http.StatusBadRequest, "Tenant cannot be moved below itself"},
+	{CodeTenantQuotaExceeded, "tenant_quota_exceeded", http.StatusTooManyRequests, "Tenant quota exceeded"},
+	{CodePlanLimitReached, "plan_limit_reached", http.StatusForbidden, "Plan limit reached"},
+	{CodePlanFeatureDisabled, "plan_feature_disabled", http.StatusForbidden, "Feature not included in plan"},
+
+	{CodeUserAlreadyExists, "user_already_exists", http.StatusConflict, "User already exists"},
+	{CodeUsernameTaken, "username_taken", http.StatusConflict, "Username already taken"},
+	{CodeUserNotFound, "user_not_found", http.StatusNotFound, "User not found"},
+}
+
+// registryByCode indexes registry for Lookup.
+var registryByCode = func() map[Code]CodeInfo {
+	m := make(map[Code]CodeInfo, len(registry))
+	for _, info := range registry {
+		m[info.Code] = info
+	}
+	return m
+}()
+
+// Codes returns every registered code, sorted by code.
+func Codes() []CodeInfo {
+	out := make([]CodeInfo, len(registry))
+	copy(out, registry)
+	sort.Slice(out, func(i, j int) bool { return out[i].Code < out[j].Code })
+	return out
+}
+
+// Lookup returns the registration of code.
+func Lookup(code Code) (CodeInfo, bool) {
+	info, ok := registryByCode[code]
+	return info, ok
+}
+
+// CodeForStatus returns the generic code for an HTTP status. Statuses without
+// a generic code map to CodeBadRequest (4xx) or CodeInternal (5xx).
+func CodeForStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusPreconditionFailed:
+		return CodePreconditionFailed
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	}
+	if status >= 500 {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// ---------------------------------------------------------------------------
+// CodedError
+// ---------------------------------------------------------------------------
+
+// CodedError attaches a registered [Code] to a typed error. The HTTP status
+// still comes from the wrapped error, so errors.As on the typed errors keeps
+// working.
+//
+//	return nil, apperror.WithCode(apperror.CodeInvalidCredentials, apperror.NewUnauthorized("invalid credentials"))
+type CodedError struct {
+	Code Code
+	Err  error
+}
+
+func (e *CodedError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped typed error.
+func (e *CodedError) Unwrap() error {
+	return e.Err
+}
+
+// WithCode wraps err with code.
+func WithCode(code Code, err error) *CodedError {
+	return &CodedError{Code: code, Err: err}
+}
+
+// CodeOf returns the code attached to err with [WithCode], if any.
+func CodeOf(err error) (Code, bool) {
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return coded.Code, true
+	}
+	return "", false
+}
@@ -0,0 +1,81 @@
+package apperror
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	codePattern := regexp.MustCompile(`^(GEN|AUTH|KEY|TEN|USR)-\d{4}$`)
+	namePattern := regexp.MustCompile(`^[a-z]+(_[a-z]+)*$`)
+	names := map[string]bool{}
+
+	for _, info := range Codes() {
+		assert.Regexp(t, codePattern, string(info.Code))
+		assert.Regexp(t, namePattern, info.Name)
+		assert.False(t, names[info.Name], "duplicate name %s", info.Name)
+		names[info.Name] = true
+		assert.NotEmpty(t, http.StatusText(info.Status), info.Code)
+		assert.NotEmpty(t, info.Title, info.Code)
+	}
+	assert.Len(t, registryByCode, len(registry), "duplicate code")
+}
+
+func TestCodes_Sorted(t *testing.T) {
+	codes := Codes()
+	for i := 1; i < len(codes); i++ {
+		assert.Less(t, codes[i-1].Code, codes[i].Code)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	info, ok := Lookup(CodeInvalidCredentials)
+	require.True(t, ok)
+	assert.Equal(t, "invalid_credentials", info.Name)
+	assert.Equal(t, http.StatusUnauthorized, info.Status)
+
+	_, ok = Lookup("NOPE-0000")
+	assert.False(t, ok)
+}
+
+func TestCodeForStatus(t *testing.T) {
+	cases := map[int]Code{
+		http.StatusBadRequest:            CodeBadRequest,
+		http.StatusUnauthorized:          CodeUnauthorized,
+		http.StatusForbidden:             CodeForbidden,
+		http.StatusNotFound:              CodeNotFound,
+		http.StatusConflict:              CodeConflict,
+		http.StatusPreconditionFailed:    CodePreconditionFailed,
+		http.StatusTooManyRequests:       CodeTooManyRequests,
+		http.StatusServiceUnavailable:    CodeServiceUnavailable,
+		http.StatusInternalServerError:   CodeInternal,
+		http.StatusBadGateway:            CodeInternal,
+		http.StatusRequestEntityTooLarge: CodeBadRequest,
+	}
+	for status, want := range cases {
+		code := CodeForStatus(status)
+		assert.Equal(t, want, code, status)
+		_, ok := Lookup(code)
+		assert.True(t, ok, code)
+	}
+}
+
+func TestWithCode(t *testing.T) {
+	err := WithCode(CodeInvalidCredentials, NewUnauthorized("invalid credentials"))
+	assert.Equal(t, "invalid credentials", err.Error())
+
+	var unauthorized *UnauthorizedError
+	assert.True(t, errors.As(err, &unauthorized))
+
+	code, ok := CodeOf(err)
+	assert.True(t, ok)
+	assert.Equal(t, CodeInvalidCredentials, code)
+
+	_, ok = CodeOf(NewUnauthorized("x"))
+	assert.False(t, ok)
+}
@@ -37,6 +37,13 @@ func (e *OAuthError) WriteJSON(w http.ResponseWriter) {
 	_ = json.NewEncoder(w).Encode(e)
 }
 
+// WithCode points the error's error_uri at the registry entry of code, so
+// clients can tell apart errors that share an OAuth error code.
+func (e *OAuthError) WithCode(code Code) *OAuthError {
+	e.URI = TypeBase + string(code)
+	return e
+}
+
 // RedirectURI builds the redirect URI with error parameters appended as query
 // parameters per RFC 6749 §4.1.2.1.
 func (e *OAuthError) RedirectURI(redirectURI, state string) string {
@@ -166,3 +173,23 @@ func NewOAuthConsentRequired(description string) *OAuthError {
 		StatusCode:  http.StatusForbidden,
 	}
 }
+
+// NewOAuthTooManyRequests creates an error when the client's tenant has used
+// up a quota. The client may retry once the quota resets.
+func NewOAuthTooManyRequests(description string) *OAuthError {
+	return &OAuthError{
+		Code:        "temporarily_unavailable",
+		Description: description,
+		StatusCode:  http.StatusTooManyRequests,
+	}
+}
+
+// NewOAuthInvalidTarget creates an error when the requested audience is not
+// a resource the client may obtain a token for (RFC 8707 §2).
+func NewOAuthInvalidTarget(description string) *OAuthError {
+	return &OAuthError{
+		Code:        "invalid_target",
+		Description: description,
+		StatusCode:  http.StatusBadRequest,
+	}
+}
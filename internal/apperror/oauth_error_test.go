@@ -57,6 +57,16 @@ func TestOAuthError_WriteJSON(t *testing.T) {
 		NewOAuthServerError("unexpected").WriteJSON(w)
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 	})
+
+	t.Run("code sets error_uri", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewOAuthInvalidGrant("too many sessions").WithCode(CodeSessionLimitReached).WriteJSON(w)
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		assert.Equal(t, "invalid_grant", body["error"])
+		assert.Equal(t, "/api/v1/errors/AUTH-1012", body["error_uri"])
+	})
 }
 
 func TestOAuthError_RedirectURI(t *testing.T) {
@@ -122,6 +132,8 @@ func TestOAuthErrorConstructors(t *testing.T) {
 		{"InvalidClient", NewOAuthInvalidClient, "invalid_client", 401},
 		{"LoginRequired", NewOAuthLoginRequired, "login_required", 401},
 		{"ConsentRequired", NewOAuthConsentRequired, "consent_required", 403},
+		{"TooManyRequests", NewOAuthTooManyRequests, "temporarily_unavailable", 429},
+		{"InvalidTarget", NewOAuthInvalidTarget, "invalid_target", 400},
 	}
 
 	for _, tc := range cases {
@@ -0,0 +1,201 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	// apiKeyRatePrefix is the key prefix for per-key request counters.
+	apiKeyRatePrefix = "api_key_rate:"
+
+	// apiKeyUsageKey is the hash of request counts and last-use times not yet
+	// written to the database. Fields are "<api_key_id>" (count) and
+	// "<api_key_id>:last" (unix seconds).
+	apiKeyUsageKey = "api_key_usage"
+
+	// apiKeyUsageFlushPrefix is the key prefix a flush moves the usage hash to
+	// so that requests counted during the flush start a fresh hash.
+	apiKeyUsageFlushPrefix = "api_key_usage:flush:"
+
+	// APIKeyRateWindow is the window an API key's rate limit applies to.
+	APIKeyRateWindow = time.Minute
+)
+
+// APIKeyUsage is the number of requests made with an API key since the last
+// flush and the time of the latest one.
+type APIKeyUsage struct {
+	APIKeyID   int64
+	Count      int64
+	LastUsedAt time.Time
+}
+
+// APIKeyUsageStore is the subset of Cache that the API key service uses to
+// move request counts from Redis to the database.
+type APIKeyUsageStore interface {
+	// TakeAPIKeyUsage returns and clears the usage recorded since the last
+	// call.
+	TakeAPIKeyUsage(ctx context.Context) ([]APIKeyUsage, error)
+	// RestoreAPIKeyUsage adds back usage that could not be written.
+	RestoreAPIKeyUsage(ctx context.Context, usage []APIKeyUsage) error
+}
+
+// Compile-time check that *Cache satisfies APIKeyUsageStore.
+var _ APIKeyUsageStore = (*Cache)(nil)
+
+// AllowAPIKeyRequest counts a request against an API key's limit of limit
+// requests per APIKeyRateWindow. It reports whether the request is within the
+// limit and, when it is not, how long until the window resets.
+func (c *Cache) AllowAPIKeyRequest(ctx context.Context, apiKeyUUID uuid.UUID, limit int, now time.Time) (bool, time.Duration, error) {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.allow_api_key_request")
+	defer span.End()
+	span.SetAttributes(attribute.String("api_key.uuid", apiKeyUUID.String()))
+
+	windowStart := now.Truncate(APIKeyRateWindow)
+	key := apiKeyRatePrefix + apiKeyUUID.String() + ":" + strconv.FormatInt(windowStart.Unix(), 10)
+
+	pipe := c.rdb.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, APIKeyRateWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "rate counter failed")
+		return false, 0, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	if incr.Val() > int64(limit) {
+		return false, windowStart.Add(APIKeyRateWindow).Sub(now), nil
+	}
+	return true, 0, nil
+}
+
+// RecordAPIKeyUse counts a request made with an API key at the given time.
+func (c *Cache) RecordAPIKeyUse(ctx context.Context, apiKeyID int64, at time.Time) error {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.record_api_key_use")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("api_key.id", apiKeyID))
+
+	field := strconv.FormatInt(apiKeyID, 10)
+	pipe := c.rdb.TxPipeline()
+	pipe.HIncrBy(ctx, apiKeyUsageKey, field, 1)
+	pipe.HSet(ctx, apiKeyUsageKey, field+":last", at.Unix())
+	if _, err := pipe.Exec(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "record usage failed")
+		return err
+	}
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// TakeAPIKeyUsage atomically moves the usage hash aside and returns its
+// contents, so that concurrent flushes never read the same counts twice.
+func (c *Cache) TakeAPIKeyUsage(ctx context.Context) ([]APIKeyUsage, error) {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.take_api_key_usage")
+	defer span.End()
+
+	flushKey := apiKeyUsageFlushPrefix + uuid.NewString()
+	if err := c.rdb.Rename(ctx, apiKeyUsageKey, flushKey).Err(); err != nil {
+		if isNoSuchKey(err) {
+			span.SetStatus(codes.Ok, "")
+			return nil, nil
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "rename usage failed")
+		return nil, err
+	}
+
+	fields, err := c.rdb.HGetAll(ctx, flushKey).Result()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "read usage failed")
+		return nil, err
+	}
+	_ = c.rdb.Del(ctx, flushKey).Err()
+
+	usage := parseAPIKeyUsage(fields)
+	span.SetAttributes(attribute.Int("api_key.count", len(usage)))
+	span.SetStatus(codes.Ok, "")
+	return usage, nil
+}
+
+// RestoreAPIKeyUsage adds usage back to the hash, keeping the later of the
+// restored and any newly recorded last-use time.
+func (c *Cache) RestoreAPIKeyUsage(ctx context.Context, usage []APIKeyUsage) error {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.restore_api_key_usage")
+	defer span.End()
+
+	if len(usage) == 0 {
+		span.SetStatus(codes.Ok, "")
+		return nil
+	}
+
+	pipe := c.rdb.TxPipeline()
+	for _, u := range usage {
+		field := strconv.FormatInt(u.APIKeyID, 10)
+		pipe.HIncrBy(ctx, apiKeyUsageKey, field, u.Count)
+		// HSETNX keeps a newer time recorded since the take.
+		pipe.HSetNX(ctx, apiKeyUsageKey, field+":last", u.LastUsedAt.Unix())
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "restore usage failed")
+		return err
+	}
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// parseAPIKeyUsage converts the fields of a usage hash, skipping malformed
+// entries.
+func parseAPIKeyUsage(fields map[string]string) []APIKeyUsage {
+	byID := make(map[int64]*APIKeyUsage)
+	get := func(id int64) *APIKeyUsage {
+		u, ok := byID[id]
+		if !ok {
+			u = &APIKeyUsage{APIKeyID: id}
+			byID[id] = u
+		}
+		return u
+	}
+
+	for field, value := range fields {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		if idPart, ok := strings.CutSuffix(field, ":last"); ok {
+			if id, err := strconv.ParseInt(idPart, 10, 64); err == nil {
+				get(id).LastUsedAt = time.Unix(n, 0).UTC()
+			}
+			continue
+		}
+		if id, err := strconv.ParseInt(field, 10, 64); err == nil {
+			get(id).Count = n
+		}
+	}
+
+	usage := make([]APIKeyUsage, 0, len(byID))
+	for _, u := range byID {
+		if u.Count > 0 {
+			usage = append(usage, *u)
+		}
+	}
+	return usage
+}
+
+// isNoSuchKey reports whether err is Redis' reply to renaming a missing key.
+func isNoSuchKey(err error) bool {
+	var redisErr redis.Error
+	return errors.As(err, &redisErr) && strings.Contains(redisErr.Error(), "no such key")
+}
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// AllowAPIKeyRequest
+// ---------------------------------------------------------------------------
+
+func TestAllowAPIKeyRequest(t *testing.T) {
+	c, _ := newTestCache(t)
+	ctx := context.Background()
+	keyUUID := uuid.New()
+	now := time.Date(2026, 1, 1, 12, 0, 15, 0, time.UTC)
+
+	for i := 0; i < 2; i++ {
+		ok, _, err := c.AllowAPIKeyRequest(ctx, keyUUID, 2, now)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+
+	ok, retryAfter, err := c.AllowAPIKeyRequest(ctx, keyUUID, 2, now)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 45*time.Second, retryAfter)
+
+	// A new window starts a fresh count.
+	ok, _, err = c.AllowAPIKeyRequest(ctx, keyUUID, 2, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Other keys are counted separately.
+	ok, _, err = c.AllowAPIKeyRequest(ctx, uuid.New(), 2, now)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestAllowAPIKeyRequest_RedisDown(t *testing.T) {
+	c, mr := newTestCache(t)
+	mr.Close()
+
+	_, _, err := c.AllowAPIKeyRequest(context.Background(), uuid.New(), 1, time.Now())
+	assert.Error(t, err)
+}
+
+// ---------------------------------------------------------------------------
+// RecordAPIKeyUse / TakeAPIKeyUsage / RestoreAPIKeyUsage
+// ---------------------------------------------------------------------------
+
+func TestTakeAPIKeyUsage(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+	first := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	last := first.Add(time.Minute)
+
+	require.NoError(t, c.RecordAPIKeyUse(ctx, 1, first))
+	require.NoError(t, c.RecordAPIKeyUse(ctx, 1, last))
+	require.NoError(t, c.RecordAPIKeyUse(ctx, 2, first))
+
+	usage, err := c.TakeAPIKeyUsage(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []APIKeyUsage{
+		{APIKeyID: 1, Count: 2, LastUsedAt: last},
+		{APIKeyID: 2, Count: 1, LastUsedAt: first},
+	}, usage)
+	assert.Empty(t, mr.Keys())
+
+	usage, err = c.TakeAPIKeyUsage(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+}
+
+func TestRestoreAPIKeyUsage(t *testing.T) {
+	c, _ := newTestCache(t)
+	ctx := context.Background()
+	old := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	newer := old.Add(time.Hour)
+
+	require.NoError(t, c.RecordAPIKeyUse(ctx, 1, newer))
+	require.NoError(t, c.RestoreAPIKeyUsage(ctx, []APIKeyUsage{
+		{APIKeyID: 1, Count: 3, LastUsedAt: old},
+		{APIKeyID: 2, Count: 1, LastUsedAt: old},
+	}))
+	require.NoError(t, c.RestoreAPIKeyUsage(ctx, nil))
+
+	usage, err := c.TakeAPIKeyUsage(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []APIKeyUsage{
+		{APIKeyID: 1, Count: 4, LastUsedAt: newer},
+		{APIKeyID: 2, Count: 1, LastUsedAt: old},
+	}, usage)
+}
+
+func TestParseAPIKeyUsage_SkipsMalformed(t *testing.T) {
+	usage := parseAPIKeyUsage(map[string]string{
+		"1":      "2",
+		"1:last": "100",
+		"x":      "1",
+		"2":      "nan",
+		"3:last": "100",
+	})
+	assert.Equal(t, []APIKeyUsage{{APIKeyID: 1, Count: 2, LastUsedAt: time.Unix(100, 0).UTC()}}, usage)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/otel"
@@ -123,13 +124,15 @@ func (c *Cache) InvalidateUserAll(ctx context.Context, sub string) {
 	span.SetStatus(codes.Ok, "")
 }
 
-// InvalidateAllUsers removes every user-context cache entry. Use this when a
-// change potentially affects many users (e.g. role permission updates).
+// InvalidateAllUsers removes every user-context cache entry and every cached
+// effective permission set. Use this when a change potentially affects many
+// users (e.g. role permission updates).
 func (c *Cache) InvalidateAllUsers(ctx context.Context) {
 	_, span := otel.Tracer("cache").Start(ctx, "cache.invalidate_all_users")
 	defer span.End()
 
 	c.deleteByPattern(ctx, userContextPrefix+"*")
+	c.deleteByPattern(ctx, userPermissionsPrefix+"*")
 	span.SetStatus(codes.Ok, "")
 }
 
@@ -165,6 +168,9 @@ type Invalidator interface {
 	InvalidateUserAll(ctx context.Context, sub string)
 	// InvalidateAllUsers removes every user-context cache entry.
 	InvalidateAllUsers(ctx context.Context)
+	// InvalidateUserPermissions removes the cached effective permission set
+	// of the given user.
+	InvalidateUserPermissions(ctx context.Context, userUUID uuid.UUID)
 }
 
 // Compile-time check that *Cache satisfies Invalidator.
@@ -174,9 +180,10 @@ var _ Invalidator = (*Cache)(nil)
 // disabled.
 type NopInvalidator struct{}
 
-func (NopInvalidator) InvalidateUser(context.Context, string, string) {}
-func (NopInvalidator) InvalidateUserAll(context.Context, string)      {}
-func (NopInvalidator) InvalidateAllUsers(context.Context)             {}
+func (NopInvalidator) InvalidateUser(context.Context, string, string)       {}
+func (NopInvalidator) InvalidateUserAll(context.Context, string)            {}
+func (NopInvalidator) InvalidateAllUsers(context.Context)                   {}
+func (NopInvalidator) InvalidateUserPermissions(context.Context, uuid.UUID) {}
 
 // Compile-time check.
 var _ Invalidator = NopInvalidator{}
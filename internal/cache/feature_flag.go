@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	// featureFlagPrefix is the key prefix for cached feature flags, keyed
+	// by flag key.
+	featureFlagPrefix = "feature_flag:"
+
+	// FeatureFlagTTL is how long a feature flag stays in cache. Changes made
+	// through the service invalidate it sooner.
+	FeatureFlagTTL = 5 * time.Minute
+)
+
+// FeatureFlag is the data stored in the feature flag cache. A flag that does
+// not exist is cached too, with Exists false, so that checks of unknown keys
+// do not reach the database.
+type FeatureFlag struct {
+	Exists            bool           `json:"exists"`
+	Enabled           bool           `json:"enabled"`
+	RolloutPercentage int            `json:"rollout_percentage"`
+	Overrides         map[int64]bool `json:"overrides,omitempty"`
+}
+
+// FeatureFlagStore is the subset of Cache that the feature flag service
+// caches flags with.
+type FeatureFlagStore interface {
+	// GetFeatureFlag returns the cached flag, or nil on a miss.
+	GetFeatureFlag(ctx context.Context, key string) *FeatureFlag
+	// SetFeatureFlag caches the flag with FeatureFlagTTL.
+	SetFeatureFlag(ctx context.Context, key string, flag *FeatureFlag)
+	// InvalidateFeatureFlag removes the cached flag.
+	InvalidateFeatureFlag(ctx context.Context, key string)
+}
+
+// Compile-time check that *Cache satisfies FeatureFlagStore.
+var _ FeatureFlagStore = (*Cache)(nil)
+
+// GetFeatureFlag implements FeatureFlagStore.
+func (c *Cache) GetFeatureFlag(ctx context.Context, key string) *FeatureFlag {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.get_feature_flag")
+	defer span.End()
+	span.SetAttributes(attribute.String("feature_flag.key", key))
+
+	raw, err := c.rdb.Get(ctx, featureFlagPrefix+key).Result()
+	if err != nil {
+		span.SetStatus(codes.Error, "cache miss")
+		return nil
+	}
+	var flag FeatureFlag
+	if err := json.Unmarshal([]byte(raw), &flag); err != nil {
+		span.SetStatus(codes.Error, "deserialize failed")
+		return nil
+	}
+	span.SetStatus(codes.Ok, "")
+	return &flag
+}
+
+// SetFeatureFlag implements FeatureFlagStore.
+func (c *Cache) SetFeatureFlag(ctx context.Context, key string, flag *FeatureFlag) {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.set_feature_flag")
+	defer span.End()
+	span.SetAttributes(attribute.String("feature_flag.key", key))
+
+	data, err := json.Marshal(flag)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "serialize failed")
+		return
+	}
+	_ = c.rdb.Set(ctx, featureFlagPrefix+key, data, FeatureFlagTTL).Err()
+	span.SetStatus(codes.Ok, "")
+}
+
+// InvalidateFeatureFlag implements FeatureFlagStore.
+func (c *Cache) InvalidateFeatureFlag(ctx context.Context, key string) {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.invalidate_feature_flag")
+	defer span.End()
+	span.SetAttributes(attribute.String("feature_flag.key", key))
+
+	_ = c.rdb.Del(ctx, featureFlagPrefix+key).Err()
+	span.SetStatus(codes.Ok, "")
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// GetFeatureFlag / SetFeatureFlag / InvalidateFeatureFlag
+// ---------------------------------------------------------------------------
+
+func TestFeatureFlag(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+
+	assert.Nil(t, c.GetFeatureFlag(ctx, "new-token-format"), "miss")
+
+	c.SetFeatureFlag(ctx, "new-token-format", &FeatureFlag{
+		Exists:            true,
+		Enabled:           true,
+		RolloutPercentage: 25,
+		Overrides:         map[int64]bool{7: false},
+	})
+	got := c.GetFeatureFlag(ctx, "new-token-format")
+	require.NotNil(t, got)
+	assert.True(t, got.Exists)
+	assert.Equal(t, 25, got.RolloutPercentage)
+	assert.Equal(t, map[int64]bool{7: false}, got.Overrides)
+	assert.Equal(t, FeatureFlagTTL, mr.TTL(featureFlagPrefix+"new-token-format"))
+
+	c.InvalidateFeatureFlag(ctx, "new-token-format")
+	assert.Nil(t, c.GetFeatureFlag(ctx, "new-token-format"), "invalidated")
+}
+
+func TestGetFeatureFlag_InvalidValue(t *testing.T) {
+	c, mr := newTestCache(t)
+	require.NoError(t, mr.Set(featureFlagPrefix+"bad", "{not json"))
+
+	assert.Nil(t, c.GetFeatureFlag(context.Background(), "bad"))
+}
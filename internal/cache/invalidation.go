@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// InvalidationPlugin is a gorm.Plugin that drops cached client and tenant
+// lookups whenever a row they are built from is created, updated or deleted,
+// whichever code path or process made the write.
+//
+// Writes are rare next to lookups, so a write to a table drops every lookup
+// built from it rather than working out which rows the statement touched.
+// Inside a transaction the callback runs before commit; a lookup made in
+// between can cache the old row until its TTL expires, which is why the
+// lookup TTLs are short and client.* domain events invalidate again once
+// delivered.
+type InvalidationPlugin struct {
+	cache *Cache
+}
+
+// NewInvalidationPlugin returns an InvalidationPlugin ready to pass to
+// gorm.DB.Use.
+func NewInvalidationPlugin(c *Cache) *InvalidationPlugin {
+	return &InvalidationPlugin{cache: c}
+}
+
+// Name implements gorm.Plugin.
+func (p *InvalidationPlugin) Name() string {
+	return "cache_invalidation"
+}
+
+// Initialize implements gorm.Plugin by registering an after callback on
+// create, update and delete.
+func (p *InvalidationPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().After("gorm:create").Register("cache:invalidate_create", p.invalidate); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("cache:invalidate_update", p.invalidate); err != nil {
+		return err
+	}
+	return cb.Delete().After("gorm:delete").Register("cache:invalidate_delete", p.invalidate)
+}
+
+func (p *InvalidationPlugin) invalidate(db *gorm.DB) {
+	if db.Error != nil || db.RowsAffected == 0 {
+		return
+	}
+
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Client lookups embed the identity provider and its tenant.
+	switch db.Statement.Table {
+	case "clients", "identity_providers":
+		p.cache.InvalidateAllClientLookups(ctx)
+	case "tenants":
+		p.cache.InvalidateAllTenants(ctx)
+		p.cache.InvalidateAllClientLookups(ctx)
+	}
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newInvalidationDB(t *testing.T, c *Cache) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{SkipDefaultTransaction: true})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(NewInvalidationPlugin(c)))
+	return db, mock
+}
+
+func TestInvalidationPlugin(t *testing.T) {
+	assert.Equal(t, "cache_invalidation", NewInvalidationPlugin(nil).Name())
+	ctx := context.Background()
+	tenant := &model.Tenant{TenantUUID: uuid.New(), Identifier: "acme"}
+
+	t.Run("tenant writes drop tenant and client lookups", func(t *testing.T) {
+		c, _ := newTestCache(t)
+		db, mock := newInvalidationDB(t, c)
+		c.SetTenant(ctx, tenant)
+		c.SetClientLookup(ctx, "client-1", "default", &model.Client{ClientID: 1})
+
+		mock.ExpectExec(`UPDATE "tenants"`).WillReturnResult(sqlmock.NewResult(0, 1))
+		require.NoError(t, db.Model(&model.Tenant{}).Where("tenant_uuid = ?", tenant.TenantUUID).Update("status", "inactive").Error)
+
+		assert.Nil(t, c.GetTenantByUUID(ctx, tenant.TenantUUID))
+		assert.Nil(t, c.GetClientLookup(ctx, "client-1", "default"))
+	})
+
+	t.Run("client writes drop client lookups only", func(t *testing.T) {
+		c, _ := newTestCache(t)
+		db, mock := newInvalidationDB(t, c)
+		c.SetTenant(ctx, tenant)
+		c.SetClientLookup(ctx, "client-1", "default", &model.Client{ClientID: 1})
+
+		mock.ExpectExec(`DELETE FROM "clients"`).WillReturnResult(sqlmock.NewResult(0, 1))
+		require.NoError(t, db.Where("client_id = ?", 1).Delete(&model.Client{}).Error)
+
+		assert.Nil(t, c.GetClientLookup(ctx, "client-1", "default"))
+		assert.NotNil(t, c.GetTenantByUUID(ctx, tenant.TenantUUID))
+	})
+
+	t.Run("writes that change nothing keep the cache", func(t *testing.T) {
+		c, _ := newTestCache(t)
+		db, mock := newInvalidationDB(t, c)
+		c.SetTenant(ctx, tenant)
+
+		mock.ExpectExec(`UPDATE "tenants"`).WillReturnResult(sqlmock.NewResult(0, 0))
+		require.NoError(t, db.Model(&model.Tenant{}).Where("tenant_uuid = ?", uuid.New()).Update("status", "inactive").Error)
+
+		assert.NotNil(t, c.GetTenantByUUID(ctx, tenant.TenantUUID))
+	})
+}
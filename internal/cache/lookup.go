@@ -0,0 +1,204 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	// clientLookupPrefix is the key prefix for cached client lookups, keyed
+	// by OAuth client_id and identity provider identifier.
+	clientLookupPrefix = "client_lookup:"
+
+	// tenantUUIDPrefix and tenantIdentifierPrefix are the key prefixes for
+	// cached tenant lookups.
+	tenantUUIDPrefix       = "tenant:uuid:"
+	tenantIdentifierPrefix = "tenant:identifier:"
+
+	// ClientLookupTTL is how long a client lookup stays in cache. Writes to
+	// clients, identity providers and tenants invalidate it sooner.
+	ClientLookupTTL = 5 * time.Minute
+
+	// TenantLookupTTL is how long a tenant lookup stays in cache. Writes to
+	// tenants invalidate it sooner.
+	TenantLookupTTL = 5 * time.Minute
+)
+
+// ClientLookupStore is the subset of Cache that the cached client repository
+// uses to serve lookups by client_id and identity provider.
+type ClientLookupStore interface {
+	// GetClientLookup returns the cached client, or nil on a miss.
+	GetClientLookup(ctx context.Context, clientID, providerIdentifier string) *model.Client
+	// SetClientLookup caches the client with ClientLookupTTL. Its secrets
+	// are never written to Redis.
+	SetClientLookup(ctx context.Context, clientID, providerIdentifier string, client *model.Client)
+	// InvalidateClientLookups removes the cached lookups of one client_id.
+	InvalidateClientLookups(ctx context.Context, clientID string)
+	// InvalidateAllClientLookups removes every cached client lookup.
+	InvalidateAllClientLookups(ctx context.Context)
+}
+
+// TenantLookupStore is the subset of Cache that the cached tenant repository
+// uses to serve lookups by UUID and identifier.
+type TenantLookupStore interface {
+	// GetTenantByUUID returns the cached tenant, or nil on a miss.
+	GetTenantByUUID(ctx context.Context, tenantUUID uuid.UUID) *model.Tenant
+	// GetTenantByIdentifier returns the cached tenant, or nil on a miss.
+	GetTenantByIdentifier(ctx context.Context, identifier string) *model.Tenant
+	// SetTenant caches the tenant under its UUID and identifier with
+	// TenantLookupTTL.
+	SetTenant(ctx context.Context, tenant *model.Tenant)
+	// InvalidateAllTenants removes every cached tenant lookup.
+	InvalidateAllTenants(ctx context.Context)
+}
+
+// Compile-time checks that *Cache satisfies the lookup stores.
+var (
+	_ ClientLookupStore = (*Cache)(nil)
+	_ TenantLookupStore = (*Cache)(nil)
+)
+
+// clientLookupKey builds the Redis key for a client lookup.
+func clientLookupKey(clientID, providerIdentifier string) string {
+	return clientLookupPrefix + clientID + ":" + providerIdentifier
+}
+
+// GetClientLookup retrieves a cached client lookup. Returns nil when the key
+// does not exist or cannot be deserialized (cache miss).
+func (c *Cache) GetClientLookup(ctx context.Context, clientID, providerIdentifier string) *model.Client {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.get_client_lookup")
+	defer span.End()
+	span.SetAttributes(attribute.String("client_id", clientID))
+
+	var client model.Client
+	if !c.getJSON(ctx, clientLookupKey(clientID, providerIdentifier), &client) {
+		span.SetStatus(codes.Error, "cache miss")
+		return nil
+	}
+	span.SetStatus(codes.Ok, "")
+	return &client
+}
+
+// SetClientLookup caches a client lookup with the default TTL. The client's
+// current and previous secrets are cleared on the cached copy; callers of
+// the lookup never need them.
+func (c *Cache) SetClientLookup(ctx context.Context, clientID, providerIdentifier string, client *model.Client) {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.set_client_lookup")
+	defer span.End()
+	span.SetAttributes(attribute.String("client_id", clientID))
+
+	stripped := *client
+	stripped.Secret = nil
+	stripped.PreviousSecret = nil
+	if err := c.setJSON(ctx, clientLookupKey(clientID, providerIdentifier), &stripped, ClientLookupTTL); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "serialize failed")
+		return
+	}
+	span.SetStatus(codes.Ok, "")
+}
+
+// InvalidateClientLookups removes the cached lookups of one client_id across
+// identity providers.
+func (c *Cache) InvalidateClientLookups(ctx context.Context, clientID string) {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.invalidate_client_lookups")
+	defer span.End()
+	span.SetAttributes(attribute.String("client_id", clientID))
+
+	c.deleteByPattern(ctx, clientLookupPrefix+clientID+":*")
+	span.SetStatus(codes.Ok, "")
+}
+
+// InvalidateAllClientLookups removes every cached client lookup.
+func (c *Cache) InvalidateAllClientLookups(ctx context.Context) {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.invalidate_all_client_lookups")
+	defer span.End()
+
+	c.deleteByPattern(ctx, clientLookupPrefix+"*")
+	span.SetStatus(codes.Ok, "")
+}
+
+// GetTenantByUUID retrieves a cached tenant by UUID. Returns nil on a miss.
+func (c *Cache) GetTenantByUUID(ctx context.Context, tenantUUID uuid.UUID) *model.Tenant {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.get_tenant_by_uuid")
+	defer span.End()
+	span.SetAttributes(attribute.String("tenant.uuid", tenantUUID.String()))
+
+	var tenant model.Tenant
+	if !c.getJSON(ctx, tenantUUIDPrefix+tenantUUID.String(), &tenant) {
+		span.SetStatus(codes.Error, "cache miss")
+		return nil
+	}
+	span.SetStatus(codes.Ok, "")
+	return &tenant
+}
+
+// GetTenantByIdentifier retrieves a cached tenant by identifier. Returns nil
+// on a miss.
+func (c *Cache) GetTenantByIdentifier(ctx context.Context, identifier string) *model.Tenant {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.get_tenant_by_identifier")
+	defer span.End()
+	span.SetAttributes(attribute.String("tenant.identifier", identifier))
+
+	var tenant model.Tenant
+	if !c.getJSON(ctx, tenantIdentifierPrefix+identifier, &tenant) {
+		span.SetStatus(codes.Error, "cache miss")
+		return nil
+	}
+	span.SetStatus(codes.Ok, "")
+	return &tenant
+}
+
+// SetTenant caches a tenant under both its UUID and its identifier with the
+// default TTL.
+func (c *Cache) SetTenant(ctx context.Context, tenant *model.Tenant) {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.set_tenant")
+	defer span.End()
+	span.SetAttributes(attribute.String("tenant.uuid", tenant.TenantUUID.String()))
+
+	if err := c.setJSON(ctx, tenantUUIDPrefix+tenant.TenantUUID.String(), tenant, TenantLookupTTL); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "serialize failed")
+		return
+	}
+	if tenant.Identifier != "" {
+		_ = c.setJSON(ctx, tenantIdentifierPrefix+tenant.Identifier, tenant, TenantLookupTTL)
+	}
+	span.SetStatus(codes.Ok, "")
+}
+
+// InvalidateAllTenants removes every cached tenant lookup.
+func (c *Cache) InvalidateAllTenants(ctx context.Context) {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.invalidate_all_tenants")
+	defer span.End()
+
+	c.deleteByPattern(ctx, tenantUUIDPrefix+"*")
+	c.deleteByPattern(ctx, tenantIdentifierPrefix+"*")
+	span.SetStatus(codes.Ok, "")
+}
+
+// getJSON reads key into dst. It reports false on a miss or when the value
+// cannot be deserialized.
+func (c *Cache) getJSON(ctx context.Context, key string, dst any) bool {
+	raw, err := c.rdb.Get(ctx, key).Result()
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal([]byte(raw), dst) == nil
+}
+
+// setJSON writes v to key with ttl.
+func (c *Cache) setJSON(ctx context.Context, key string, v any, ttl time.Duration) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.rdb.Set(ctx, key, data, ttl).Err()
+}
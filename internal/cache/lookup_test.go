@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// Client lookups
+// ---------------------------------------------------------------------------
+
+func TestSetAndGetClientLookup(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+	secret := "s3cret"
+	previous := "old-s3cret"
+	client := &model.Client{
+		ClientID:       7,
+		ClientUUID:     uuid.New(),
+		Name:           "web",
+		Secret:         &secret,
+		PreviousSecret: &previous,
+		IdentityProvider: &model.IdentityProvider{
+			Identifier: "default",
+			Tenant:     &model.Tenant{Identifier: "acme"},
+		},
+	}
+
+	c.SetClientLookup(ctx, "client-1", "default", client)
+
+	got := c.GetClientLookup(ctx, "client-1", "default")
+	require.NotNil(t, got)
+	assert.Equal(t, client.ClientUUID, got.ClientUUID)
+	assert.Equal(t, "acme", got.IdentityProvider.Tenant.Identifier)
+	assert.Nil(t, got.Secret)
+	assert.Nil(t, got.PreviousSecret)
+	assert.Equal(t, "s3cret", *client.Secret, "the caller's client must not be modified")
+	raw, err := mr.Get(clientLookupKey("client-1", "default"))
+	require.NoError(t, err)
+	assert.NotContains(t, raw, "s3cret")
+	assert.Equal(t, ClientLookupTTL, mr.TTL(clientLookupKey("client-1", "default")))
+}
+
+func TestGetClientLookup_MissAndCorruptData(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+
+	assert.Nil(t, c.GetClientLookup(ctx, "client-1", ""))
+
+	require.NoError(t, mr.Set(clientLookupKey("client-1", ""), "{not json"))
+	assert.Nil(t, c.GetClientLookup(ctx, "client-1", ""))
+}
+
+func TestInvalidateClientLookups(t *testing.T) {
+	c, _ := newTestCache(t)
+	ctx := context.Background()
+
+	c.SetClientLookup(ctx, "client-1", "default", &model.Client{ClientID: 1})
+	c.SetClientLookup(ctx, "client-1", "", &model.Client{ClientID: 1})
+	c.SetClientLookup(ctx, "client-2", "default", &model.Client{ClientID: 2})
+
+	c.InvalidateClientLookups(ctx, "client-1")
+	assert.Nil(t, c.GetClientLookup(ctx, "client-1", "default"))
+	assert.Nil(t, c.GetClientLookup(ctx, "client-1", ""))
+	assert.NotNil(t, c.GetClientLookup(ctx, "client-2", "default"))
+
+	c.InvalidateAllClientLookups(ctx)
+	assert.Nil(t, c.GetClientLookup(ctx, "client-2", "default"))
+}
+
+// ---------------------------------------------------------------------------
+// Tenant lookups
+// ---------------------------------------------------------------------------
+
+func TestSetAndGetTenant(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+	tenant := &model.Tenant{TenantID: 3, TenantUUID: uuid.New(), Identifier: "acme", AncestorPath: "/1/"}
+
+	c.SetTenant(ctx, tenant)
+
+	byUUID := c.GetTenantByUUID(ctx, tenant.TenantUUID)
+	require.NotNil(t, byUUID)
+	assert.Equal(t, int64(3), byUUID.TenantID)
+	assert.Equal(t, "/1/", byUUID.AncestorPath)
+
+	byIdentifier := c.GetTenantByIdentifier(ctx, "acme")
+	require.NotNil(t, byIdentifier)
+	assert.Equal(t, tenant.TenantUUID, byIdentifier.TenantUUID)
+	assert.Equal(t, TenantLookupTTL, mr.TTL(tenantUUIDPrefix+tenant.TenantUUID.String()))
+}
+
+func TestGetTenant_MissAndCorruptData(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+	tenantUUID := uuid.New()
+
+	assert.Nil(t, c.GetTenantByUUID(ctx, tenantUUID))
+	assert.Nil(t, c.GetTenantByIdentifier(ctx, "acme"))
+
+	require.NoError(t, mr.Set(tenantUUIDPrefix+tenantUUID.String(), "{not json"))
+	assert.Nil(t, c.GetTenantByUUID(ctx, tenantUUID))
+}
+
+func TestInvalidateAllTenants(t *testing.T) {
+	c, _ := newTestCache(t)
+	ctx := context.Background()
+	tenant := &model.Tenant{TenantUUID: uuid.New(), Identifier: "acme"}
+	c.SetTenant(ctx, tenant)
+
+	c.InvalidateAllTenants(ctx)
+
+	assert.Nil(t, c.GetTenantByUUID(ctx, tenant.TenantUUID))
+	assert.Nil(t, c.GetTenantByIdentifier(ctx, "acme"))
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	// userPermissionsPrefix is the key prefix for cached effective
+	// permission sets, keyed by user UUID.
+	userPermissionsPrefix = "permissions:"
+
+	// UserPermissionsTTL is how long an effective permission set stays in
+	// cache. Changes to roles and permissions invalidate it sooner.
+	UserPermissionsTTL = 10 * time.Minute
+)
+
+// TenantPermissions is the set of permissions a user holds through the roles
+// of one tenant.
+type TenantPermissions struct {
+	TenantUUID  uuid.UUID `json:"tenant_uuid"`
+	Permissions []string  `json:"permissions"`
+}
+
+// ResourcePermissions is the set of permissions a user holds on one client or
+// group through scoped role assignments.
+type ResourcePermissions struct {
+	ResourceType string    `json:"resource_type"`
+	ResourceUUID uuid.UUID `json:"resource_uuid"`
+	Permissions  []string  `json:"permissions"`
+}
+
+// UserPermissions is the data stored in the effective-permission cache.
+type UserPermissions struct {
+	Tenants   []TenantPermissions   `json:"tenants"`
+	Resources []ResourcePermissions `json:"resources,omitempty"`
+}
+
+// UserPermissionStore is the subset of Cache that the permission resolver
+// uses to cache effective permission sets.
+type UserPermissionStore interface {
+	// GetUserPermissions returns the cached set, or nil on a miss.
+	GetUserPermissions(ctx context.Context, userUUID uuid.UUID) *UserPermissions
+	// SetUserPermissions caches the set with UserPermissionsTTL.
+	SetUserPermissions(ctx context.Context, userUUID uuid.UUID, perms *UserPermissions)
+	// InvalidateUserPermissions removes the cached set of one user.
+	InvalidateUserPermissions(ctx context.Context, userUUID uuid.UUID)
+	// InvalidateAllUserPermissions removes every cached set.
+	InvalidateAllUserPermissions(ctx context.Context)
+}
+
+// Compile-time check that *Cache satisfies UserPermissionStore.
+var _ UserPermissionStore = (*Cache)(nil)
+
+// userPermissionsKey builds the Redis key for a user's permission set.
+func userPermissionsKey(userUUID uuid.UUID) string {
+	return userPermissionsPrefix + userUUID.String()
+}
+
+// GetUserPermissions retrieves a cached effective permission set. Returns nil
+// when the key does not exist or cannot be deserialized (cache miss).
+func (c *Cache) GetUserPermissions(ctx context.Context, userUUID uuid.UUID) *UserPermissions {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.get_user_permissions")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", userUUID.String()))
+
+	raw, err := c.rdb.Get(ctx, userPermissionsKey(userUUID)).Result()
+	if err != nil {
+		span.SetStatus(codes.Error, "cache miss")
+		return nil
+	}
+	var perms UserPermissions
+	if err := json.Unmarshal([]byte(raw), &perms); err != nil {
+		span.SetStatus(codes.Error, "deserialize failed")
+		return nil
+	}
+	span.SetStatus(codes.Ok, "")
+	return &perms
+}
+
+// SetUserPermissions caches an effective permission set with the default TTL.
+func (c *Cache) SetUserPermissions(ctx context.Context, userUUID uuid.UUID, perms *UserPermissions) {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.set_user_permissions")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", userUUID.String()))
+
+	data, err := json.Marshal(perms)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "serialize failed")
+		return
+	}
+	_ = c.rdb.Set(ctx, userPermissionsKey(userUUID), data, UserPermissionsTTL).Err()
+	span.SetStatus(codes.Ok, "")
+}
+
+// InvalidateUserPermissions removes the cached permission set of one user.
+func (c *Cache) InvalidateUserPermissions(ctx context.Context, userUUID uuid.UUID) {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.invalidate_user_permissions")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", userUUID.String()))
+
+	_ = c.rdb.Del(ctx, userPermissionsKey(userUUID)).Err()
+	span.SetStatus(codes.Ok, "")
+}
+
+// InvalidateAllUserPermissions removes every cached permission set.
+func (c *Cache) InvalidateAllUserPermissions(ctx context.Context) {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.invalidate_all_user_permissions")
+	defer span.End()
+
+	c.deleteByPattern(ctx, userPermissionsPrefix+"*")
+	span.SetStatus(codes.Ok, "")
+}
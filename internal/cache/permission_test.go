@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// GetUserPermissions / SetUserPermissions
+// ---------------------------------------------------------------------------
+
+func TestSetAndGetUserPermissions(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+	userUUID := uuid.New()
+	tenantUUID := uuid.New()
+
+	c.SetUserPermissions(ctx, userUUID, &UserPermissions{Tenants: []TenantPermissions{
+		{TenantUUID: tenantUUID, Permissions: []string{"user:read"}},
+	}})
+
+	got := c.GetUserPermissions(ctx, userUUID)
+	require.NotNil(t, got)
+	require.Len(t, got.Tenants, 1)
+	assert.Equal(t, tenantUUID, got.Tenants[0].TenantUUID)
+	assert.Equal(t, []string{"user:read"}, got.Tenants[0].Permissions)
+	assert.Equal(t, UserPermissionsTTL, mr.TTL(userPermissionsKey(userUUID)))
+}
+
+func TestGetUserPermissions_MissAndCorruptData(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+	userUUID := uuid.New()
+
+	assert.Nil(t, c.GetUserPermissions(ctx, userUUID))
+
+	require.NoError(t, mr.Set(userPermissionsKey(userUUID), "not-json"))
+	assert.Nil(t, c.GetUserPermissions(ctx, userUUID))
+}
+
+// ---------------------------------------------------------------------------
+// Invalidation
+// ---------------------------------------------------------------------------
+
+func TestInvalidateUserPermissions(t *testing.T) {
+	c, _ := newTestCache(t)
+	ctx := context.Background()
+	alice, bob := uuid.New(), uuid.New()
+	c.SetUserPermissions(ctx, alice, &UserPermissions{})
+	c.SetUserPermissions(ctx, bob, &UserPermissions{})
+
+	c.InvalidateUserPermissions(ctx, alice)
+
+	assert.Nil(t, c.GetUserPermissions(ctx, alice))
+	assert.NotNil(t, c.GetUserPermissions(ctx, bob), "other user should remain")
+}
+
+func TestInvalidateAllUserPermissions(t *testing.T) {
+	c, _ := newTestCache(t)
+	ctx := context.Background()
+	alice := uuid.New()
+	c.SetUserPermissions(ctx, alice, &UserPermissions{})
+	c.SetUserContext(ctx, "sub1", "client1", &UserContext{})
+
+	c.InvalidateAllUserPermissions(ctx)
+
+	assert.Nil(t, c.GetUserPermissions(ctx, alice))
+	assert.NotNil(t, c.GetUserContext(ctx, "sub1", "client1"), "user context should remain")
+}
+
+func TestInvalidateAllUsers_ClearsPermissions(t *testing.T) {
+	c, _ := newTestCache(t)
+	ctx := context.Background()
+	alice := uuid.New()
+	c.SetUserPermissions(ctx, alice, &UserPermissions{})
+
+	c.InvalidateAllUsers(ctx)
+
+	assert.Nil(t, c.GetUserPermissions(ctx, alice))
+}
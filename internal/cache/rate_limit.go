@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// requestRatePrefix is the key prefix for request token buckets.
+const requestRatePrefix = "request_rate:"
+
+// Principal types a request rate limit applies to.
+const (
+	RateLimitPrincipalUser   = "user"
+	RateLimitPrincipalClient = "client"
+	RateLimitPrincipalAPIKey = "api_key"
+	RateLimitPrincipalIP     = "ip"
+)
+
+// RateLimitPrincipals lists every principal type, in the order requests are
+// matched against them.
+var RateLimitPrincipals = []string{
+	RateLimitPrincipalAPIKey,
+	RateLimitPrincipalClient,
+	RateLimitPrincipalUser,
+	RateLimitPrincipalIP,
+}
+
+// RateLimit is a token bucket refilled with Requests tokens per Period and
+// holding at most Burst tokens.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// RateLimitResult is the outcome of counting a request against a RateLimit.
+type RateLimitResult struct {
+	Allowed bool
+	// Limit is the bucket size.
+	Limit int
+	// Remaining is the number of whole tokens left after this request.
+	Remaining int
+	// RetryAfter is how long until the next token, when the request was
+	// rejected.
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+}
+
+// tokenBucketScript refills the bucket for the time elapsed since it was last
+// touched and takes one token if there is one. It returns whether a token was
+// taken and the tokens left, as a string so that fractions survive.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local ttl = tonumber(ARGV[4])
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+  tokens = burst
+  ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], ttl)
+return {allowed, tostring(tokens)}
+`)
+
+// AllowRequest takes a token from the bucket identified by key, which is
+// scoped by the caller (e.g. "<group>:<principal>:<id>"). Buckets start full
+// and expire once they would have refilled.
+func (c *Cache) AllowRequest(ctx context.Context, key string, limit RateLimit, now time.Time) (RateLimitResult, error) {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.allow_request")
+	defer span.End()
+	span.SetAttributes(attribute.String("rate_limit.key", key))
+
+	// Tokens per millisecond
+	rate := float64(limit.Requests) / float64(limit.Period.Milliseconds())
+	burst := float64(limit.Burst)
+	ttl := int64(math.Ceil(burst / rate))
+
+	res, err := tokenBucketScript.Run(ctx, c.rdb, []string{requestRatePrefix + key},
+		rate, limit.Burst, now.UnixMilli(), ttl).Slice()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "token bucket failed")
+		return RateLimitResult{}, err
+	}
+
+	allowed, _ := res[0].(int64)
+	tokensStr, _ := res[1].(string)
+	tokens, _ := strconv.ParseFloat(tokensStr, 64)
+
+	result := RateLimitResult{
+		Allowed:   allowed == 1,
+		Limit:     limit.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration(math.Ceil((burst-tokens)/rate)) * time.Millisecond,
+	}
+	if !result.Allowed {
+		result.RetryAfter = time.Duration(math.Ceil((1-tokens)/rate)) * time.Millisecond
+	}
+
+	span.SetAttributes(attribute.Bool("rate_limit.allowed", result.Allowed))
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowRequest(t *testing.T) {
+	c, _ := newTestCache(t)
+	ctx := context.Background()
+	limit := RateLimit{Requests: 2, Period: time.Minute, Burst: 3}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// The bucket starts full.
+	for want := 2; want >= 0; want-- {
+		res, err := c.AllowRequest(ctx, "auth:ip:10.0.0.1", limit, now)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 3, res.Limit)
+		assert.Equal(t, want, res.Remaining)
+	}
+
+	res, err := c.AllowRequest(ctx, "auth:ip:10.0.0.1", limit, now)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	// Two tokens a minute: one every 30s, three in 90s.
+	assert.Equal(t, 30*time.Second, res.RetryAfter)
+	assert.Equal(t, 90*time.Second, res.Reset)
+
+	// Tokens come back at the refill rate.
+	res, err = c.AllowRequest(ctx, "auth:ip:10.0.0.1", limit, now.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+
+	// Other keys have their own bucket.
+	res, err = c.AllowRequest(ctx, "auth:ip:10.0.0.2", limit, now)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, res.Remaining)
+}
+
+func TestAllowRequest_RefillCapsAtBurst(t *testing.T) {
+	c, _ := newTestCache(t)
+	ctx := context.Background()
+	limit := RateLimit{Requests: 1, Period: time.Second, Burst: 2}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	_, err := c.AllowRequest(ctx, "k", limit, now)
+	require.NoError(t, err)
+
+	res, err := c.AllowRequest(ctx, "k", limit, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+}
+
+func TestAllowRequest_Expires(t *testing.T) {
+	c, mr := newTestCache(t)
+	limit := RateLimit{Requests: 10, Period: time.Second, Burst: 10}
+
+	_, err := c.AllowRequest(context.Background(), "k", limit, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, mr.TTL(requestRatePrefix+"k"))
+}
+
+func TestAllowRequest_RedisDown(t *testing.T) {
+	c, mr := newTestCache(t)
+	mr.Close()
+
+	_, err := c.AllowRequest(context.Background(), "k", RateLimit{Requests: 1, Period: time.Second, Burst: 1}, time.Now())
+	assert.Error(t, err)
+}
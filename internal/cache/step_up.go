@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// stepUpPrefix is the key prefix for the time a credential last
+// re-authenticated.
+const stepUpPrefix = "step_up:"
+
+// StepUpStore is the subset of Cache that the step-up service remembers
+// re-authentications with.
+type StepUpStore interface {
+	// RecordStepUp remembers that credential re-authenticated at at, for
+	// ttl.
+	RecordStepUp(ctx context.Context, credential string, at time.Time, ttl time.Duration) error
+	// StepUpAt returns when credential last re-authenticated, or the zero
+	// time when it has not within the ttl it was recorded with.
+	StepUpAt(ctx context.Context, credential string) (time.Time, error)
+}
+
+// Compile-time check that *Cache satisfies StepUpStore.
+var _ StepUpStore = (*Cache)(nil)
+
+// RecordStepUp implements StepUpStore.
+func (c *Cache) RecordStepUp(ctx context.Context, credential string, at time.Time, ttl time.Duration) error {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.record_step_up")
+	defer span.End()
+
+	if err := c.rdb.Set(ctx, stepUpPrefix+credential, at.Unix(), ttl).Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "set failed")
+		return err
+	}
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// StepUpAt implements StepUpStore.
+func (c *Cache) StepUpAt(ctx context.Context, credential string) (time.Time, error) {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.step_up_at")
+	defer span.End()
+
+	val, err := c.rdb.Get(ctx, stepUpPrefix+credential).Result()
+	if errors.Is(err, redis.Nil) {
+		span.SetStatus(codes.Ok, "")
+		return time.Time{}, nil
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get failed")
+		return time.Time{}, err
+	}
+	unix, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid value")
+		return time.Time{}, err
+	}
+	span.SetStatus(codes.Ok, "")
+	return time.Unix(unix, 0), nil
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// RecordStepUp / StepUpAt
+// ---------------------------------------------------------------------------
+
+func TestStepUp(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	got, err := c.StepUpAt(ctx, "user:sid:1")
+	require.NoError(t, err)
+	assert.True(t, got.IsZero(), "nothing recorded")
+
+	require.NoError(t, c.RecordStepUp(ctx, "user:sid:1", at, 5*time.Minute))
+	got, err = c.StepUpAt(ctx, "user:sid:1")
+	require.NoError(t, err)
+	assert.True(t, at.Equal(got))
+
+	other, err := c.StepUpAt(ctx, "user:sid:2")
+	require.NoError(t, err)
+	assert.True(t, other.IsZero(), "recorded per credential")
+
+	mr.FastForward(5 * time.Minute)
+	got, err = c.StepUpAt(ctx, "user:sid:1")
+	require.NoError(t, err)
+	assert.True(t, got.IsZero(), "expired")
+}
+
+func TestStepUpAt_InvalidValue(t *testing.T) {
+	c, mr := newTestCache(t)
+	require.NoError(t, mr.Set(stepUpPrefix+"bad", "not-a-time"))
+
+	_, err := c.StepUpAt(context.Background(), "bad")
+	assert.Error(t, err)
+}
@@ -0,0 +1,252 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	// usagePeriodPrefix is the key prefix for the monthly counts quotas are
+	// checked against.
+	usagePeriodPrefix = "usage_period:"
+
+	// usagePendingKey is the hash of usage not yet written to the database.
+	// Fields are "<tenant_id>:<api_key_id>:<metric>:<YYYY-MM-DD>".
+	usagePendingKey = "usage_pending"
+
+	// usagePendingFlushPrefix is the key prefix a flush moves the pending
+	// hash to so that usage counted during the flush starts a fresh hash.
+	usagePendingFlushPrefix = "usage_pending:flush:"
+
+	// usagePeriodTTL outlives the longest month, so that a month's count is
+	// kept until the month is over.
+	usagePeriodTTL = 32 * 24 * time.Hour
+
+	// usageDayLayout formats the day of a pending usage field.
+	usageDayLayout = "2006-01-02"
+)
+
+// Metrics tenant usage is counted by.
+const (
+	UsageMetricRequests     = "requests"
+	UsageMetricTokensIssued = "tokens_issued"
+)
+
+// UsageCount is the usage of one metric by a tenant on a day (UTC). APIKeyID
+// is the API key the usage was made with, or 0.
+type UsageCount struct {
+	TenantID int64
+	APIKeyID int64
+	Metric   string
+	Day      time.Time
+	Count    int64
+}
+
+// UsageStore is the subset of Cache that the usage service meters tenants
+// with. Usage is counted twice: per calendar month (UTC), for quotas, and in
+// a pending hash that is moved to the database.
+type UsageStore interface {
+	// CountUsage counts one unit of metric for tenantID, and for apiKeyID
+	// when it is not 0, unless that would take the tenant's count for the
+	// month of at past tenantQuota or the key's past apiKeyQuota. A quota of 0
+	// is unlimited. It reports whether the unit was counted.
+	CountUsage(ctx context.Context, tenantID, apiKeyID int64, metric string, tenantQuota, apiKeyQuota int64, at time.Time) (bool, error)
+	// PeriodUsage returns the tenant's count of metric for the month of at.
+	PeriodUsage(ctx context.Context, tenantID int64, metric string, at time.Time) (int64, error)
+	// TakeUsage returns and clears the usage counted since the last call.
+	TakeUsage(ctx context.Context) ([]UsageCount, error)
+	// RestoreUsage adds back usage that could not be written.
+	RestoreUsage(ctx context.Context, usage []UsageCount) error
+}
+
+// Compile-time check that *Cache satisfies UsageStore.
+var _ UsageStore = (*Cache)(nil)
+
+// countUsageScript checks the monthly counts of the tenant, KEYS[1], and of
+// the API key, KEYS[3] when given, against their quotas and, when both are
+// below, increments them and the pending usage field in KEYS[2]. Rejected
+// units are not counted, so that raising a quota takes effect at once.
+var countUsageScript = redis.NewScript(`
+local tenant_quota = tonumber(ARGV[1])
+local key_quota = tonumber(ARGV[2])
+local ttl = tonumber(ARGV[3])
+
+if tenant_quota > 0 and tonumber(redis.call('GET', KEYS[1]) or '0') >= tenant_quota then
+  return 0
+end
+if KEYS[3] and key_quota > 0 and tonumber(redis.call('GET', KEYS[3]) or '0') >= key_quota then
+  return 0
+end
+
+redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ttl)
+if KEYS[3] then
+  redis.call('INCR', KEYS[3])
+  redis.call('PEXPIRE', KEYS[3], ttl)
+end
+redis.call('HINCRBY', KEYS[2], ARGV[4], 1)
+return 1
+`)
+
+// CountUsage implements UsageStore.
+func (c *Cache) CountUsage(ctx context.Context, tenantID, apiKeyID int64, metric string, tenantQuota, apiKeyQuota int64, at time.Time) (bool, error) {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.count_usage")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int64("tenant.id", tenantID),
+		attribute.Int64("api_key.id", apiKeyID),
+		attribute.String("usage.metric", metric),
+	)
+
+	at = at.UTC()
+	keys := []string{usagePeriodKey(tenantID, metric, at), usagePendingKey}
+	if apiKeyID != 0 {
+		keys = append(keys, usagePeriodKey(tenantID, "api_key:"+strconv.FormatInt(apiKeyID, 10)+":"+metric, at))
+	}
+	field := usagePendingField(UsageCount{TenantID: tenantID, APIKeyID: apiKeyID, Metric: metric, Day: at})
+
+	counted, err := countUsageScript.Run(ctx, c.rdb, keys,
+		tenantQuota, apiKeyQuota, usagePeriodTTL.Milliseconds(), field).Int()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "count usage failed")
+		return false, err
+	}
+
+	span.SetAttributes(attribute.Bool("usage.counted", counted == 1))
+	span.SetStatus(codes.Ok, "")
+	return counted == 1, nil
+}
+
+// PeriodUsage implements UsageStore.
+func (c *Cache) PeriodUsage(ctx context.Context, tenantID int64, metric string, at time.Time) (int64, error) {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.period_usage")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID), attribute.String("usage.metric", metric))
+
+	n, err := c.rdb.Get(ctx, usagePeriodKey(tenantID, metric, at.UTC())).Int64()
+	if errors.Is(err, redis.Nil) {
+		span.SetStatus(codes.Ok, "")
+		return 0, nil
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get failed")
+		return 0, err
+	}
+	span.SetStatus(codes.Ok, "")
+	return n, nil
+}
+
+// TakeUsage atomically moves the pending hash aside and returns its
+// contents, so that concurrent flushes never read the same counts twice.
+func (c *Cache) TakeUsage(ctx context.Context) ([]UsageCount, error) {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.take_usage")
+	defer span.End()
+
+	flushKey := usagePendingFlushPrefix + uuid.NewString()
+	if err := c.rdb.Rename(ctx, usagePendingKey, flushKey).Err(); err != nil {
+		if isNoSuchKey(err) {
+			span.SetStatus(codes.Ok, "")
+			return nil, nil
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "rename usage failed")
+		return nil, err
+	}
+
+	fields, err := c.rdb.HGetAll(ctx, flushKey).Result()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "read usage failed")
+		return nil, err
+	}
+	_ = c.rdb.Del(ctx, flushKey).Err()
+
+	usage := parseUsage(fields)
+	span.SetAttributes(attribute.Int("usage.count", len(usage)))
+	span.SetStatus(codes.Ok, "")
+	return usage, nil
+}
+
+// RestoreUsage adds usage back to the pending hash. The monthly counts are
+// left alone; they never left Redis.
+func (c *Cache) RestoreUsage(ctx context.Context, usage []UsageCount) error {
+	_, span := otel.Tracer("cache").Start(ctx, "cache.restore_usage")
+	defer span.End()
+
+	if len(usage) == 0 {
+		span.SetStatus(codes.Ok, "")
+		return nil
+	}
+
+	pipe := c.rdb.TxPipeline()
+	for _, u := range usage {
+		pipe.HIncrBy(ctx, usagePendingKey, usagePendingField(u), u.Count)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "restore usage failed")
+		return err
+	}
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// usagePeriodKey is the key of the tenant's count of metric for the month
+// of at, which must be in UTC. API key counts use "api_key:<id>:<metric>"
+// as metric.
+func usagePeriodKey(tenantID int64, metric string, at time.Time) string {
+	return usagePeriodPrefix + strconv.FormatInt(tenantID, 10) + ":" + metric + ":" + at.Format("2006-01")
+}
+
+// usagePendingField is the field of u in the pending usage hash. Only the
+// day of u.Day is kept.
+func usagePendingField(u UsageCount) string {
+	return strconv.FormatInt(u.TenantID, 10) + ":" + strconv.FormatInt(u.APIKeyID, 10) + ":" + u.Metric + ":" + u.Day.Format(usageDayLayout)
+}
+
+// parseUsage converts the fields of a pending usage hash, skipping malformed
+// entries.
+func parseUsage(fields map[string]string) []UsageCount {
+	usage := make([]UsageCount, 0, len(fields))
+	for field, value := range fields {
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || count <= 0 {
+			continue
+		}
+		parts := strings.Split(field, ":")
+		if len(parts) != 4 {
+			continue
+		}
+		tenantID, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		apiKeyID, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		day, err := time.Parse(usageDayLayout, parts[3])
+		if err != nil {
+			continue
+		}
+		usage = append(usage, UsageCount{
+			TenantID: tenantID,
+			APIKeyID: apiKeyID,
+			Metric:   parts[2],
+			Day:      day,
+			Count:    count,
+		})
+	}
+	return usage
+}
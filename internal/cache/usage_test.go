@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// CountUsage / PeriodUsage
+// ---------------------------------------------------------------------------
+
+func TestCountUsage(t *testing.T) {
+	c, _ := newTestCache(t)
+	ctx := context.Background()
+	now := time.Date(2026, 1, 31, 23, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 2; i++ {
+		ok, err := c.CountUsage(ctx, 1, 0, UsageMetricRequests, 2, 0, now)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+
+	// The third request is over the tenant quota and is not counted.
+	ok, err := c.CountUsage(ctx, 1, 0, UsageMetricRequests, 2, 0, now)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	n, err := c.PeriodUsage(ctx, 1, UsageMetricRequests, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	// A new month starts a fresh count.
+	ok, err = c.CountUsage(ctx, 1, 0, UsageMetricRequests, 2, 0, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Other metrics and tenants are counted separately, and 0 is unlimited.
+	ok, err = c.CountUsage(ctx, 1, 0, UsageMetricTokensIssued, 0, 0, now)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = c.CountUsage(ctx, 2, 0, UsageMetricRequests, 2, 0, now)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestCountUsage_APIKeyQuota(t *testing.T) {
+	c, _ := newTestCache(t)
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	ok, err := c.CountUsage(ctx, 1, 7, UsageMetricRequests, 0, 1, now)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = c.CountUsage(ctx, 1, 7, UsageMetricRequests, 0, 1, now)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// Other keys and requests without a key still pass.
+	ok, err = c.CountUsage(ctx, 1, 8, UsageMetricRequests, 0, 1, now)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = c.CountUsage(ctx, 1, 0, UsageMetricRequests, 0, 1, now)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Key usage counts towards the tenant.
+	n, err := c.PeriodUsage(ctx, 1, UsageMetricRequests, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+}
+
+func TestCountUsage_RedisDown(t *testing.T) {
+	c, mr := newTestCache(t)
+	mr.Close()
+
+	_, err := c.CountUsage(context.Background(), 1, 0, UsageMetricRequests, 0, 0, time.Now())
+	assert.Error(t, err)
+	_, err = c.PeriodUsage(context.Background(), 1, UsageMetricRequests, time.Now())
+	assert.Error(t, err)
+}
+
+func TestPeriodUsage_None(t *testing.T) {
+	c, _ := newTestCache(t)
+
+	n, err := c.PeriodUsage(context.Background(), 1, UsageMetricRequests, time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+// ---------------------------------------------------------------------------
+// TakeUsage / RestoreUsage
+// ---------------------------------------------------------------------------
+
+func TestTakeUsage(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := day.Add(12 * time.Hour)
+
+	for _, apiKeyID := range []int64{0, 0, 7} {
+		_, err := c.CountUsage(ctx, 1, apiKeyID, UsageMetricRequests, 0, 0, now)
+		require.NoError(t, err)
+	}
+	_, err := c.CountUsage(ctx, 1, 0, UsageMetricTokensIssued, 0, 0, now)
+	require.NoError(t, err)
+
+	usage, err := c.TakeUsage(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []UsageCount{
+		{TenantID: 1, APIKeyID: 0, Metric: UsageMetricRequests, Day: day, Count: 2},
+		{TenantID: 1, APIKeyID: 7, Metric: UsageMetricRequests, Day: day, Count: 1},
+		{TenantID: 1, APIKeyID: 0, Metric: UsageMetricTokensIssued, Day: day, Count: 1},
+	}, usage)
+	assert.False(t, mr.Exists(usagePendingKey))
+
+	// The monthly counts stay for quota checks.
+	n, err := c.PeriodUsage(ctx, 1, UsageMetricRequests, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+
+	usage, err = c.TakeUsage(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+}
+
+func TestRestoreUsage(t *testing.T) {
+	c, _ := newTestCache(t)
+	ctx := context.Background()
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := c.CountUsage(ctx, 1, 0, UsageMetricRequests, 0, 0, day)
+	require.NoError(t, err)
+	require.NoError(t, c.RestoreUsage(ctx, []UsageCount{
+		{TenantID: 1, Metric: UsageMetricRequests, Day: day, Count: 4},
+		{TenantID: 2, APIKeyID: 3, Metric: UsageMetricTokensIssued, Day: day, Count: 1},
+	}))
+	require.NoError(t, c.RestoreUsage(ctx, nil))
+
+	usage, err := c.TakeUsage(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []UsageCount{
+		{TenantID: 1, Metric: UsageMetricRequests, Day: day, Count: 5},
+		{TenantID: 2, APIKeyID: 3, Metric: UsageMetricTokensIssued, Day: day, Count: 1},
+	}, usage)
+}
+
+func TestParseUsage_SkipsMalformed(t *testing.T) {
+	usage := parseUsage(map[string]string{
+		"1:0:requests:2026-01-01": "3",
+		"1:0:requests":            "1",
+		"x:0:requests:2026-01-01": "1",
+		"1:y:requests:2026-01-01": "1",
+		"1:0:requests:yesterday":  "1",
+		"1:0:tokens:2026-01-01":   "nan",
+		"2:0:tokens:2026-01-01":   "0",
+	})
+	assert.Equal(t, []UsageCount{
+		{TenantID: 1, Metric: UsageMetricRequests, Day: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Count: 3},
+	}, usage)
+}
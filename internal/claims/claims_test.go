@@ -0,0 +1,242 @@
+package claims
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// Source
+// ---------------------------------------------------------------------------
+
+func TestSourceFromConfig(t *testing.T) {
+	t.Run("empty config", func(t *testing.T) {
+		src, err := SourceFromConfig(nil)
+		require.NoError(t, err)
+		assert.Nil(t, src)
+	})
+
+	t.Run("no claims source", func(t *testing.T) {
+		src, err := SourceFromConfig([]byte(`{"theme":"dark"}`))
+		require.NoError(t, err)
+		assert.Nil(t, src)
+	})
+
+	t.Run("non-object config", func(t *testing.T) {
+		src, err := SourceFromConfig([]byte(`[]`))
+		require.NoError(t, err)
+		assert.Nil(t, src)
+	})
+
+	t.Run("valid source", func(t *testing.T) {
+		src, err := SourceFromConfig([]byte(`{"claims_enrichment":{"url":"https://crm.internal/claims","timeout_ms":300,"cache_ttl_seconds":60,"required":true}}`))
+		require.NoError(t, err)
+		require.NotNil(t, src)
+		assert.Equal(t, 300*time.Millisecond, src.Timeout())
+		assert.Equal(t, time.Minute, src.CacheTTL())
+		assert.True(t, src.Required)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		src, err := SourceFromConfig([]byte(`{"claims_enrichment":{"url":"http://crm.internal"}}`))
+		require.NoError(t, err)
+		assert.Equal(t, DefaultTimeout, src.Timeout())
+		assert.Equal(t, DefaultCacheTTL, src.CacheTTL())
+	})
+
+	invalid := map[string]string{
+		"malformed":     `{"claims_enrichment":"https://crm.internal"}`,
+		"missing url":   `{"claims_enrichment":{}}`,
+		"relative url":  `{"claims_enrichment":{"url":"/claims"}}`,
+		"bad scheme":    `{"claims_enrichment":{"url":"ftp://crm.internal"}}`,
+		"timeout low":   `{"claims_enrichment":{"url":"https://crm.internal","timeout_ms":10}}`,
+		"timeout high":  `{"claims_enrichment":{"url":"https://crm.internal","timeout_ms":5000}}`,
+		"cache too big": `{"claims_enrichment":{"url":"https://crm.internal","cache_ttl_seconds":7200}}`,
+	}
+	for name, config := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := SourceFromConfig([]byte(config))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSource_CacheDisabled(t *testing.T) {
+	assert.Zero(t, Source{CacheTTLSeconds: -1}.CacheTTL())
+}
+
+// ---------------------------------------------------------------------------
+// Enricher
+// ---------------------------------------------------------------------------
+
+func claimsServer(t *testing.T, status int, body string) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var req Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "sub-1", req.Subject)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+var testRequest = Request{Subject: "sub-1", UserUUID: "u-1", ClientID: "web"}
+
+func TestEnricher_FetchAndCache(t *testing.T) {
+	srv, calls := claimsServer(t, http.StatusOK, `{"claims":{"tier":"gold"}}`)
+	e := NewEnricher(nil)
+	src := Source{URL: srv.URL}
+
+	claims, outcome, err := e.Fetch(context.Background(), src, testRequest)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeFetched, outcome)
+	assert.Equal(t, map[string]any{"tier": "gold"}, claims)
+
+	claims, outcome, err = e.Fetch(context.Background(), src, testRequest)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeCached, outcome)
+	assert.Equal(t, "gold", claims["tier"])
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+
+	other := testRequest
+	other.ClientID = "mobile"
+	_, outcome, err = e.Fetch(context.Background(), src, other)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeFetched, outcome)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestEnricher_CacheExpiry(t *testing.T) {
+	srv, calls := claimsServer(t, http.StatusOK, `{"claims":{"tier":"gold"}}`)
+	e := NewEnricher(nil)
+	now := time.Now()
+	e.now = func() time.Time { return now }
+	src := Source{URL: srv.URL, CacheTTLSeconds: 10}
+
+	_, _, err := e.Fetch(context.Background(), src, testRequest)
+	require.NoError(t, err)
+	now = now.Add(11 * time.Second)
+	_, outcome, err := e.Fetch(context.Background(), src, testRequest)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeFetched, outcome)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestEnricher_CacheDisabled(t *testing.T) {
+	srv, calls := claimsServer(t, http.StatusOK, `{"claims":{}}`)
+	e := NewEnricher(nil)
+	src := Source{URL: srv.URL, CacheTTLSeconds: -1}
+
+	for i := 0; i < 3; i++ {
+		_, outcome, err := e.Fetch(context.Background(), src, testRequest)
+		require.NoError(t, err)
+		assert.Equal(t, OutcomeFetched, outcome)
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+func TestEnricher_Errors(t *testing.T) {
+	t.Run("non-2xx", func(t *testing.T) {
+		srv, _ := claimsServer(t, http.StatusBadGateway, ``)
+		_, outcome, err := NewEnricher(nil).Fetch(context.Background(), Source{URL: srv.URL}, testRequest)
+		require.Error(t, err)
+		assert.Equal(t, OutcomeError, outcome)
+	})
+
+	t.Run("malformed body", func(t *testing.T) {
+		srv, _ := claimsServer(t, http.StatusOK, `not json`)
+		_, _, err := NewEnricher(nil).Fetch(context.Background(), Source{URL: srv.URL}, testRequest)
+		require.Error(t, err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}))
+		defer srv.Close()
+
+		start := time.Now()
+		_, outcome, err := NewEnricher(nil).Fetch(context.Background(), Source{URL: srv.URL, TimeoutMs: 50}, testRequest)
+		require.Error(t, err)
+		assert.Equal(t, OutcomeError, outcome)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+}
+
+func TestEnricher_CircuitBreaker(t *testing.T) {
+	srv, calls := claimsServer(t, http.StatusInternalServerError, ``)
+	e := NewEnricher(nil)
+	now := time.Now()
+	e.now = func() time.Time { return now }
+	src := Source{URL: srv.URL}
+
+	for i := 0; i < breakerThreshold; i++ {
+		_, outcome, err := e.Fetch(context.Background(), src, testRequest)
+		require.Error(t, err)
+		assert.Equal(t, OutcomeError, outcome)
+	}
+
+	_, outcome, err := e.Fetch(context.Background(), src, testRequest)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, OutcomeCircuitOpen, outcome)
+	assert.Equal(t, int32(breakerThreshold), atomic.LoadInt32(calls))
+
+	// After the cooldown one trial call is let through; it fails, so the
+	// breaker stays open.
+	now = now.Add(breakerCooldown)
+	_, outcome, _ = e.Fetch(context.Background(), src, testRequest)
+	assert.Equal(t, OutcomeError, outcome)
+	_, outcome, _ = e.Fetch(context.Background(), src, testRequest)
+	assert.Equal(t, OutcomeCircuitOpen, outcome)
+	assert.Equal(t, int32(breakerThreshold+1), atomic.LoadInt32(calls))
+}
+
+func TestEnricher_CircuitBreakerRecovers(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"claims":{"tier":"gold"}}`))
+	}))
+	defer srv.Close()
+
+	e := NewEnricher(nil)
+	now := time.Now()
+	e.now = func() time.Time { return now }
+	src := Source{URL: srv.URL, CacheTTLSeconds: -1}
+
+	for i := 0; i < breakerThreshold; i++ {
+		_, _, _ = e.Fetch(context.Background(), src, testRequest)
+	}
+	fail.Store(false)
+	now = now.Add(breakerCooldown)
+
+	claims, outcome, err := e.Fetch(context.Background(), src, testRequest)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeFetched, outcome)
+	assert.Equal(t, "gold", claims["tier"])
+
+	_, outcome, err = e.Fetch(context.Background(), src, testRequest)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeFetched, outcome)
+}
@@ -0,0 +1,220 @@
+package claims
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Breaker defaults: after breakerThreshold consecutive failures an endpoint
+// is skipped for breakerCooldown, then a single trial call is let through.
+const (
+	breakerThreshold = 5
+	breakerCooldown  = 30 * time.Second
+	maxResponseBytes = 64 << 10
+	maxCacheEntries  = 10000
+)
+
+// Outcomes reported by Fetch, also used as metric labels.
+const (
+	OutcomeFetched     = "fetched"
+	OutcomeCached      = "cached"
+	OutcomeError       = "error"
+	OutcomeCircuitOpen = "circuit_open"
+)
+
+// ErrCircuitOpen is returned while an endpoint's breaker is open.
+var ErrCircuitOpen = errors.New("claims endpoint circuit open")
+
+// Request is the JSON body sent to a claims endpoint.
+type Request struct {
+	Subject  string `json:"sub"`
+	UserUUID string `json:"user_uuid"`
+	Email    string `json:"email,omitempty"`
+	ClientID string `json:"client_id"`
+	Scope    string `json:"scope,omitempty"`
+}
+
+// response is the JSON body expected from a claims endpoint.
+type response struct {
+	Claims map[string]any `json:"claims"`
+}
+
+// Fetcher fetches extra claims for a token request.
+type Fetcher interface {
+	// Fetch returns the claims for req from src together with the outcome.
+	Fetch(ctx context.Context, src Source, req Request) (map[string]any, string, error)
+}
+
+// Enricher is the default Fetcher. It is safe for concurrent use.
+type Enricher struct {
+	client *http.Client
+	now    func() time.Time
+
+	mu       sync.Mutex
+	cache    map[string]cacheEntry
+	breakers map[string]*breaker
+}
+
+type cacheEntry struct {
+	claims    map[string]any
+	expiresAt time.Time
+}
+
+type breaker struct {
+	failures  int
+	openUntil time.Time
+}
+
+// NewEnricher creates an Enricher using client for outbound calls. A nil
+// client uses a default client; per-call timeouts come from the Source.
+func NewEnricher(client *http.Client) *Enricher {
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &Enricher{
+		client:   client,
+		now:      time.Now,
+		cache:    make(map[string]cacheEntry),
+		breakers: make(map[string]*breaker),
+	}
+}
+
+// Fetch implements Fetcher.
+func (e *Enricher) Fetch(ctx context.Context, src Source, req Request) (map[string]any, string, error) {
+	key := src.URL + "\x00" + req.ClientID + "\x00" + req.Subject
+	ttl := src.CacheTTL()
+
+	if ttl > 0 {
+		if claims, ok := e.cached(key); ok {
+			return claims, OutcomeCached, nil
+		}
+	}
+
+	if !e.allow(src.URL) {
+		return nil, OutcomeCircuitOpen, ErrCircuitOpen
+	}
+
+	claims, err := e.call(ctx, src, req)
+	e.record(src.URL, err == nil)
+	if err != nil {
+		return nil, OutcomeError, err
+	}
+
+	if ttl > 0 {
+		e.store(key, claims, ttl)
+	}
+	return claims, OutcomeFetched, nil
+}
+
+func (e *Enricher) call(ctx context.Context, src Source, req Request) (map[string]any, error) {
+	ctx, cancel := context.WithTimeout(ctx, src.Timeout())
+	defer cancel()
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, src.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	res, err := e.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("claims endpoint returned status %d", res.StatusCode)
+	}
+
+	var out response
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxResponseBytes)).Decode(&out); err != nil {
+		return nil, fmt.Errorf("invalid claims response: %w", err)
+	}
+	return out.Claims, nil
+}
+
+func (e *Enricher) cached(key string) (map[string]any, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	entry, ok := e.cache[key]
+	if !ok {
+		return nil, false
+	}
+	if !e.now().Before(entry.expiresAt) {
+		delete(e.cache, key)
+		return nil, false
+	}
+	return entry.claims, true
+}
+
+func (e *Enricher) store(key string, claims map[string]any, ttl time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	if len(e.cache) >= maxCacheEntries {
+		for k, v := range e.cache {
+			if !now.Before(v.expiresAt) {
+				delete(e.cache, k)
+			}
+		}
+		if len(e.cache) >= maxCacheEntries {
+			// Still full of live entries: start over rather than grow
+			// without bound.
+			e.cache = make(map[string]cacheEntry)
+		}
+	}
+	e.cache[key] = cacheEntry{claims: claims, expiresAt: now.Add(ttl)}
+}
+
+// allow reports whether a call to url may proceed. Once the cooldown has
+// passed the breaker is half-open: one call is let through and the open
+// window is pushed forward until that call reports back.
+func (e *Enricher) allow(url string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	b := e.breakers[url]
+	if b == nil || b.failures < breakerThreshold {
+		return true
+	}
+	now := e.now()
+	if now.Before(b.openUntil) {
+		return false
+	}
+	b.openUntil = now.Add(breakerCooldown)
+	return true
+}
+
+func (e *Enricher) record(url string, success bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if success {
+		delete(e.breakers, url)
+		return
+	}
+
+	b := e.breakers[url]
+	if b == nil {
+		b = &breaker{}
+		e.breakers[url] = b
+	}
+	b.failures++
+	if b.failures >= breakerThreshold {
+		b.openUntil = e.now().Add(breakerCooldown)
+	}
+}
@@ -0,0 +1,107 @@
+// Package claims enriches access tokens with claims fetched from an
+// external, tenant-operated endpoint at token issuance time. Each auth client
+// opts in through the "claims_enrichment" key of its config. Lookups are
+// cached per user and client, bounded by a strict timeout, and guarded by a
+// per-endpoint circuit breaker so a slow or failing endpoint cannot stall
+// logins.
+package claims
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ConfigKey is the auth client config key holding the claims source.
+const ConfigKey = "claims_enrichment"
+
+// Timeout and cache bounds for a claims source.
+const (
+	DefaultTimeout  = 500 * time.Millisecond
+	MinTimeout      = 50 * time.Millisecond
+	MaxTimeout      = 2 * time.Second
+	DefaultCacheTTL = 5 * time.Minute
+	MaxCacheTTL     = time.Hour
+)
+
+// Source is the claims endpoint configured on an auth client.
+type Source struct {
+	// URL receives a POST with the Request as JSON body.
+	URL string `json:"url"`
+	// TimeoutMs bounds the whole call, defaulting to DefaultTimeout.
+	TimeoutMs int `json:"timeout_ms,omitempty"`
+	// CacheTTLSeconds controls how long a response is reused for the same
+	// user and client. Zero uses DefaultCacheTTL; a negative value disables
+	// caching.
+	CacheTTLSeconds int `json:"cache_ttl_seconds,omitempty"`
+	// Required fails token issuance when claims cannot be fetched. By
+	// default tokens are issued without the extra claims.
+	Required bool `json:"required,omitempty"`
+}
+
+// Timeout returns the effective call timeout.
+func (s Source) Timeout() time.Duration {
+	if s.TimeoutMs == 0 {
+		return DefaultTimeout
+	}
+	return time.Duration(s.TimeoutMs) * time.Millisecond
+}
+
+// CacheTTL returns the effective cache lifetime; zero means no caching.
+func (s Source) CacheTTL() time.Duration {
+	switch {
+	case s.CacheTTLSeconds < 0:
+		return 0
+	case s.CacheTTLSeconds == 0:
+		return DefaultCacheTTL
+	default:
+		return time.Duration(s.CacheTTLSeconds) * time.Second
+	}
+}
+
+// Validate checks the source definition.
+func (s Source) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("claims_enrichment.url must be an absolute http(s) URL")
+	}
+	if s.TimeoutMs != 0 {
+		if t := s.Timeout(); t < MinTimeout || t > MaxTimeout {
+			return fmt.Errorf("claims_enrichment.timeout_ms must be between %d and %d",
+				MinTimeout.Milliseconds(), MaxTimeout.Milliseconds())
+		}
+	}
+	if s.CacheTTL() > MaxCacheTTL {
+		return fmt.Errorf("claims_enrichment.cache_ttl_seconds must not exceed %d", int(MaxCacheTTL.Seconds()))
+	}
+	return nil
+}
+
+// SourceFromConfig extracts the claims source from an auth client's JSON
+// config. It returns nil when the client has none.
+func SourceFromConfig(config []byte) (*Source, error) {
+	if len(config) == 0 {
+		return nil, nil
+	}
+
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal(config, &wrapper); err != nil {
+		// Non-object configs predate claims enrichment; treat as unset.
+		return nil, nil
+	}
+	raw, ok := wrapper[ConfigKey]
+	if !ok || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var src Source
+	if err := json.Unmarshal(raw, &src); err != nil {
+		return nil, fmt.Errorf("invalid claims_enrichment: %w", err)
+	}
+	if err := src.Validate(); err != nil {
+		return nil, err
+	}
+	return &src, nil
+}
@@ -1,11 +1,24 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/eventstream"
+	"github.com/maintainerd/auth/internal/security"
+	"github.com/maintainerd/auth/internal/session"
+	"github.com/maintainerd/auth/internal/siem"
+	"github.com/maintainerd/auth/internal/storage"
 )
 
 var (
@@ -34,24 +47,185 @@ var (
 	DBName     string
 	DBSSLMode  string
 
-	// Email Config
-	SMTPHost      string
-	SMTPPort      int
-	SMTPUser      string
-	SMTPPass      string
-	SMTPFromEmail string
-	SMTPFromName  string
-	EmailLogo     string
+	// DB Pool Config
+	DBMaxOpenConns       int           // Maximum open connections; 0 means unlimited
+	DBMaxIdleConns       int           // Maximum idle connections kept in the pool
+	DBConnMaxLifetime    time.Duration // Age at which a connection is closed and replaced
+	DBConnMaxIdleTime    time.Duration // Time an idle connection is kept before it is closed
+	DBSlowQueryThreshold time.Duration // Statements slower than this are logged at warn
+
+	// DB Read Replica Config
+	DBReadReplicaHosts     []string      // "host" or "host:port" of each read replica; empty keeps every query on the primary
+	DBReadReplicaLagWindow time.Duration // Reads of a table this instance wrote within the window stay on the primary
+
+	// Email Config — the SMTP settings are reloadable, see Current
+	EmailLogo string
+
+	// Profile Encryption Config
+	ProfileEncryptionEnabled bool   // Enables field-level encryption of sensitive profile fields
+	ProfileEncryptionKey     []byte // 32-byte master key; nil when encryption is disabled
+
+	// JWT Key Rotation Config
+	JWTKeyRotationEnabled  bool          // Stores signing keys in the database and rotates them
+	JWTKeyEncryptionKey    []byte        // 32-byte key encrypting stored private keys; nil when rotation is disabled
+	JWTKeyRotationInterval time.Duration // Age at which the active key is rotated; 0 rotates on demand only
+	JWTKeyRetireAfter      time.Duration // Time a rotated key keeps verifying tokens before it is retired
+
+	// Column Encryption Config
+	ColumnEncryptionEnabled           bool          // Encrypts sensitive columns with envelope encryption
+	ColumnEncryptionKeyWrapper        string        // "local", "aws_kms" or "vault_transit"
+	ColumnEncryptionKEK               []byte        // 32-byte key-encryption key of the local wrapper
+	ColumnEncryptionKEKRotating       bool          // Loads the previous local key-encryption key while the keys are rewrapped
+	ColumnEncryptionPreviousKEK       []byte        // Replaced local key-encryption key; nil unless ColumnEncryptionKEKRotating
+	ColumnEncryptionKMSKeyID          string        // AWS KMS key ID, ARN or alias of the aws_kms wrapper
+	ColumnEncryptionVaultTransitMount string        // Vault transit mount of the vault_transit wrapper
+	ColumnEncryptionVaultTransitKey   string        // Vault transit key name of the vault_transit wrapper
+	ColumnEncryptionRotationInterval  time.Duration // Age at which the active data key is rotated
+
+	// Shutdown Config
+	HTTPShutdownTimeout time.Duration // Time allowed for REST servers to drain in-flight requests
+	GRPCShutdownTimeout time.Duration // Time allowed for gRPC to drain before a forced stop
+
+	// Authorization Audit Sampling Config
+	AuthzAuditAllowSampleRate float64 // Fraction of allowed permission checks written to the audit log (0-1)
+	AuthzAuditDenySampleRate  float64 // Fraction of denied permission checks written to the audit log (0-1)
+	AuthzAuditMaxPerSecond    int     // Per-outcome cap on sampled decisions per second; 0 disables the cap
+
+	// Breached Password Check Config
+	BreachedPasswordCheckEnabled bool          // Makes the breach lookup available to tenants that enable the feature flag
+	BreachedPasswordAPIURL       string        // HaveIBeenPwned-compatible range endpoint; the hash prefix is appended
+	BreachedPasswordThreshold    int           // Minimum breach count at which a password is rejected
+	BreachedPasswordTimeout      time.Duration // Per-lookup HTTP timeout
+
+	// GeoIP Config
+	GeoIPDatabasePath    string        // MaxMind City or Country database (.mmdb); empty disables GeoIP lookups
+	GeoIPASNDatabasePath string        // Optional MaxMind ASN database adding the network operator
+	GeoIPCacheSize       int           // Maximum number of cached lookups; 0 disables the cache
+	GeoIPCacheTTL        time.Duration // Time a cached lookup is reused
+
+	// Admin UI Config
+	AdminUIEnabled bool // Serves the embedded admin console under /admin on the internal port
+
+	// API Docs Config
+	APIDocsSwaggerUIURL string // Base URL of the swagger-ui-dist assets loaded by /docs on the internal port
+
+	// Step-Up Config
+	StepUpMaxAge time.Duration // How long a password re-entry satisfies step-up protected admin actions; 0 disables step-up
+
+	// Self-Service Tenant Config
+	SelfServiceTenantsEnabled bool // Lets signed-in users create and own tenants via POST /tenants/self-service
+
+	// Hosted Page Session Config
+	HostedSessionStore    string        // "redis" (server-side) or "cookie" (stateless, sealed in the cookie)
+	HostedSessionKeys     [][]byte      // Cookie store keys, current key first; nil for the redis store
+	HostedSessionSameSite http.SameSite // SameSite attribute of the session cookie
+	HostedSessionTTL      time.Duration // Idle lifetime of a hosted page session
+
+	// Browser Session Config
+	BrowserSessionsEnabled    bool          // Mounts /session so first-party web apps can sign in with a session cookie instead of bearer tokens
+	BrowserSessionSameSite    http.SameSite // SameSite attribute of the browser session cookie
+	BrowserSessionIdleTimeout time.Duration // Sliding lifetime; every request extends the session by this much
+	BrowserSessionMaxLifetime time.Duration // Absolute lifetime from sign-in
+
+	// Event Stream Config
+	EventStreamProvider      string        // "kafka" or "nats"; empty disables the export
+	EventStreamBrokers       []string      // Kafka bootstrap addresses or NATS server URLs
+	EventStreamTopic         string        // Topic or subject template; {aggregate} and {type} are expanded
+	EventStreamEventTypes    []string      // Exported event types ("*", "user.*", "role.created", ...)
+	EventStreamUsername      string        // SASL (Kafka) or user (NATS) name; empty disables authentication
+	EventStreamPassword      []byte        // Loaded via the secret provider when a username is set
+	EventStreamSASLMechanism string        // Kafka SASL mechanism: plain, scram-sha-256 or scram-sha-512
+	EventStreamTLS           bool          // Connects to the brokers over TLS
+	EventStreamTimeout       time.Duration // Per-message publish timeout
+
+	// SIEM Config
+	SIEMProvider      string        // "syslog", "splunk" or "https"; empty disables security event forwarding
+	SIEMEndpoint      string        // host:port for syslog, the full URL for splunk and https
+	SIEMSyslogNetwork string        // Syslog transport: udp, tcp or tcp+tls
+	SIEMToken         []byte        // Splunk HEC token or https bearer token, loaded via the secret provider for those providers
+	SIEMSplunkIndex   string        // Splunk index; empty uses the token's default index
+	SIEMMinSeverity   string        // Lowest forwarded severity: LOW, MEDIUM or HIGH
+	SIEMBatchSize     int           // Most events sent in one batch
+	SIEMFlushInterval time.Duration // Longest an event waits for its batch to fill up
+	SIEMMaxAttempts   int           // Sends of a batch before its events are dropped
+	SIEMTimeout       time.Duration // Per-send timeout
+
+	// Object Storage Config
+	StorageProvider        string // "local", "s3" or "gcs"; empty disables profile picture uploads
+	StorageLocalDir        string // Directory of the local provider
+	StorageBucket          string // S3 or GCS bucket
+	StorageRegion          string // S3 region; GCS uses "auto"
+	StorageEndpoint        string // S3-compatible endpoint (MinIO, ...); empty uses AWS or GCS
+	StoragePathStyle       bool   // Addresses the bucket in the URL path instead of the host name
+	StorageAccessKeyID     string // Static access key (an HMAC key for GCS); empty uses the default AWS credential chain
+	StorageSecretAccessKey []byte // Loaded via the secret provider when an access key ID is set
+
+	// Profile Picture Config
+	ProfilePictureMaxBytes     int           // Largest accepted upload
+	ProfilePictureMaxDimension int           // Pictures are scaled down to fit this many pixels per side
+	ProfilePictureURLTTL       time.Duration // Lifetime of the signed picture URLs in profile responses
 )
 
-// Init loads all configuration from environment variables (and an optional .env file).
-// It returns an error for any missing required variable so that main() can decide
-// how to handle the failure — nothing in this package calls os.Exit.
+const (
+	DefaultDBMaxOpenConns       = 25
+	DefaultDBMaxIdleConns       = 10
+	DefaultDBConnMaxLifetime    = 30 * time.Minute
+	DefaultDBConnMaxIdleTime    = 5 * time.Minute
+	DefaultDBSlowQueryThreshold = 200 * time.Millisecond
+
+	DefaultDBReadReplicaLagWindow = 2 * time.Second
+
+	DefaultHTTPShutdownTimeout = 30 * time.Second
+	DefaultGRPCShutdownTimeout = 15 * time.Second
+
+	DefaultAuthzAuditAllowSampleRate = 0.0
+	DefaultAuthzAuditDenySampleRate  = 1.0
+	DefaultAuthzAuditMaxPerSecond    = 20
+
+	DefaultBreachedPasswordThreshold = 1
+	DefaultBreachedPasswordTimeout   = 3 * time.Second
+
+	DefaultGeoIPCacheSize = 10000
+	DefaultGeoIPCacheTTL  = time.Hour
+
+	DefaultHostedSessionTTL = session.DefaultTTL
+
+	DefaultJWTKeyRotationInterval = 90 * 24 * time.Hour
+	DefaultJWTKeyRetireAfter      = 24 * time.Hour
+
+	DefaultColumnEncryptionRotationInterval = 90 * 24 * time.Hour
+
+	DefaultSelfServiceTenantLimit = 3
+
+	DefaultAPIDocsSwaggerUIURL = "https://cdn.jsdelivr.net/npm/swagger-ui-dist@5"
+
+	DefaultStepUpMaxAge = 5 * time.Minute
+
+	DefaultStorageLocalDir = "data/storage"
+
+	DefaultProfilePictureMaxBytes     = 5 << 20
+	DefaultProfilePictureMaxDimension = 512
+	DefaultProfilePictureURLTTL       = time.Hour
+
+	DefaultBrowserSessionIdleTimeout = 30 * time.Minute
+	DefaultBrowserSessionMaxLifetime = 12 * time.Hour
+
+	// DefaultRateLimitPolicies applies when RATE_LIMIT_POLICIES is not set.
+	DefaultRateLimitPolicies = "auth.ip=60/m,default.ip=600/m,default.user=1200/m,default.client=2400/m,default.api_key=2400/m"
+)
+
+// Init loads all configuration from environment variables (and an optional .env file)
+// and the optional config file named by CONFIG_FILE; environment variables take
+// precedence over the file. It returns an error for any missing required setting so
+// that main() can decide how to handle the failure — nothing in this package calls os.Exit.
 func Init() error {
 	// Load environment variables first (best-effort; not required in production)
 	if err := godotenv.Load(); err != nil {
 		slog.Warn(".env file not found, relying on environment variables")
 	}
+	if _, err := loadConfigFile(); err != nil {
+		return err
+	}
 
 	// Secret management provider (optional with defaults)
 	SecretProvider = GetEnvOrDefault("SECRET_PROVIDER", "env")
@@ -65,6 +239,10 @@ func Init() error {
 		return fmt.Errorf("failed to initialize secret manager: %w", err)
 	}
 
+	if err := validateRequired(); err != nil {
+		return err
+	}
+
 	// App Config
 	var err error
 	if AppVersion, err = GetEnv("APP_VERSION"); err != nil {
@@ -113,27 +291,459 @@ func Init() error {
 	}
 	DBSSLMode = GetEnvOrDefault("DB_SSLMODE", "disable")
 
-	// Email Config
-	if SMTPHost, err = GetEnv("SMTP_HOST"); err != nil {
+	// DB Pool Config — the defaults suit a single instance against a
+	// database allowing 100 connections; lower DB_MAX_OPEN_CONNS as
+	// instances are added.
+	if DBMaxOpenConns, err = GetEnvIntOrDefault("DB_MAX_OPEN_CONNS", DefaultDBMaxOpenConns); err != nil {
 		return err
 	}
-	smtpPortStr, err := GetEnv("SMTP_PORT")
-	if err != nil {
+	if DBMaxOpenConns < 0 {
+		return fmt.Errorf("invalid DB_MAX_OPEN_CONNS %d: must not be negative", DBMaxOpenConns)
+	}
+	if DBMaxIdleConns, err = GetEnvIntOrDefault("DB_MAX_IDLE_CONNS", DefaultDBMaxIdleConns); err != nil {
 		return err
 	}
-	SMTPPort, err = strconv.Atoi(smtpPortStr)
-	if err != nil {
-		return fmt.Errorf("invalid SMTP_PORT %q: %w", smtpPortStr, err)
+	if DBMaxIdleConns < 0 {
+		return fmt.Errorf("invalid DB_MAX_IDLE_CONNS %d: must not be negative", DBMaxIdleConns)
+	}
+	if DBMaxOpenConns > 0 && DBMaxIdleConns > DBMaxOpenConns {
+		return fmt.Errorf("invalid DB_MAX_IDLE_CONNS %d: must not exceed DB_MAX_OPEN_CONNS %d", DBMaxIdleConns, DBMaxOpenConns)
+	}
+	if DBConnMaxLifetime, err = GetEnvDurationOrDefault("DB_CONN_MAX_LIFETIME", DefaultDBConnMaxLifetime); err != nil {
+		return err
+	}
+	if DBConnMaxIdleTime, err = GetEnvDurationOrDefault("DB_CONN_MAX_IDLE_TIME", DefaultDBConnMaxIdleTime); err != nil {
+		return err
 	}
-	if SMTPUser, err = GetEnv("SMTP_USER"); err != nil {
+	if DBSlowQueryThreshold, err = GetEnvDurationOrDefault("DB_SLOW_QUERY_THRESHOLD", DefaultDBSlowQueryThreshold); err != nil {
 		return err
 	}
-	if SMTPPass, err = GetEnv("SMTP_PASS"); err != nil {
+
+	// DB Read Replica Config — replicas share the primary's credentials,
+	// database name and SSL mode.
+	DBReadReplicaHosts = splitList(GetEnvOrDefault("DB_READ_REPLICA_HOSTS", ""))
+	if DBReadReplicaLagWindow, err = GetEnvDurationOrDefault("DB_READ_REPLICA_LAG_WINDOW", DefaultDBReadReplicaLagWindow); err != nil {
+		return err
+	}
+
+	// Email, self-service tenant limit and rate limit config — reloadable
+	reloadable, err := loadReloadable()
+	if err != nil {
 		return err
 	}
-	SMTPFromEmail = GetEnvOrDefault("SMTP_FROM_EMAIL", "noreply@maintainerd.com")
-	SMTPFromName = GetEnvOrDefault("SMTP_FROM_NAME", "Maintainerd")
 	EmailLogo = GetEnvOrDefault("EMAIL_LOGO_URL", "https://avatars.githubusercontent.com/u/215448978?s=400&u=f6f4016d81d3ef54ea34cd9cf3028a8ca1183afc&v=4")
 
+	// Profile Encryption Config — the key is loaded via the configured secret
+	// provider so it can be held in a KMS-backed store.
+	if ProfileEncryptionEnabled, err = GetEnvBoolOrDefault("PROFILE_ENCRYPTION_ENABLED", false); err != nil {
+		return err
+	}
+	ProfileEncryptionKey = nil
+	if ProfileEncryptionEnabled {
+		if ProfileEncryptionKey, err = loadSecret("PROFILE_ENCRYPTION_KEY"); err != nil {
+			return fmt.Errorf("failed to load profile encryption key: %w", err)
+		}
+		if len(ProfileEncryptionKey) != crypto.FieldKeySize {
+			return fmt.Errorf("PROFILE_ENCRYPTION_KEY must be %d bytes, got %d", crypto.FieldKeySize, len(ProfileEncryptionKey))
+		}
+	}
+
+	// JWT Key Rotation Config — the key encrypting stored private keys is
+	// loaded via the configured secret provider. Rotated keys must outlive
+	// the tokens they signed, so they are retired after a grace period.
+	if JWTKeyRotationEnabled, err = GetEnvBoolOrDefault("JWT_KEY_ROTATION_ENABLED", false); err != nil {
+		return err
+	}
+	JWTKeyEncryptionKey = nil
+	if JWTKeyRotationEnabled {
+		if JWTKeyEncryptionKey, err = loadSecret("JWT_KEY_ENCRYPTION_KEY"); err != nil {
+			return fmt.Errorf("failed to load JWT key encryption key: %w", err)
+		}
+		if len(JWTKeyEncryptionKey) != crypto.FieldKeySize {
+			return fmt.Errorf("JWT_KEY_ENCRYPTION_KEY must be %d bytes, got %d", crypto.FieldKeySize, len(JWTKeyEncryptionKey))
+		}
+	}
+	if JWTKeyRotationInterval, err = GetEnvDurationOrDefault("JWT_KEY_ROTATION_INTERVAL", DefaultJWTKeyRotationInterval); err != nil {
+		return err
+	}
+	if JWTKeyRotationInterval < 0 {
+		return fmt.Errorf("invalid JWT_KEY_ROTATION_INTERVAL %s: must not be negative", JWTKeyRotationInterval)
+	}
+	if JWTKeyRetireAfter, err = GetEnvDurationOrDefault("JWT_KEY_RETIRE_AFTER", DefaultJWTKeyRetireAfter); err != nil {
+		return err
+	}
+	if JWTKeyRetireAfter <= 0 {
+		return fmt.Errorf("invalid JWT_KEY_RETIRE_AFTER %s: must be positive", JWTKeyRetireAfter)
+	}
+
+	// Column Encryption Config — data keys are wrapped by a local KEK loaded
+	// via the configured secret provider, by AWS KMS or by Vault transit.
+	if ColumnEncryptionEnabled, err = GetEnvBoolOrDefault("COLUMN_ENCRYPTION_ENABLED", false); err != nil {
+		return err
+	}
+	ColumnEncryptionKeyWrapper = GetEnvOrDefault("COLUMN_ENCRYPTION_KEY_WRAPPER", "local")
+	ColumnEncryptionKMSKeyID = GetEnvOrDefault("COLUMN_ENCRYPTION_KMS_KEY_ID", "")
+	ColumnEncryptionVaultTransitMount = GetEnvOrDefault("COLUMN_ENCRYPTION_VAULT_TRANSIT_MOUNT", "transit")
+	ColumnEncryptionVaultTransitKey = GetEnvOrDefault("COLUMN_ENCRYPTION_VAULT_TRANSIT_KEY", "maintainerd-auth")
+	if ColumnEncryptionKEKRotating, err = GetEnvBoolOrDefault("COLUMN_ENCRYPTION_KEK_ROTATING", false); err != nil {
+		return err
+	}
+	ColumnEncryptionKEK, ColumnEncryptionPreviousKEK = nil, nil
+	if ColumnEncryptionEnabled {
+		switch ColumnEncryptionKeyWrapper {
+		case "local":
+			if ColumnEncryptionKEK, err = loadSecret("COLUMN_ENCRYPTION_KEK"); err != nil {
+				return fmt.Errorf("failed to load column encryption key-encryption key: %w", err)
+			}
+			if len(ColumnEncryptionKEK) != crypto.FieldKeySize {
+				return fmt.Errorf("COLUMN_ENCRYPTION_KEK must be %d bytes, got %d", crypto.FieldKeySize, len(ColumnEncryptionKEK))
+			}
+			// Set while the KEK is replaced, until the data keys are rewrapped
+			if ColumnEncryptionKEKRotating {
+				if ColumnEncryptionPreviousKEK, err = loadSecret("COLUMN_ENCRYPTION_PREVIOUS_KEK"); err != nil {
+					return fmt.Errorf("failed to load previous column encryption key-encryption key: %w", err)
+				}
+				if len(ColumnEncryptionPreviousKEK) != crypto.FieldKeySize {
+					return fmt.Errorf("COLUMN_ENCRYPTION_PREVIOUS_KEK must be %d bytes, got %d", crypto.FieldKeySize, len(ColumnEncryptionPreviousKEK))
+				}
+			}
+		case "aws_kms":
+			if ColumnEncryptionKMSKeyID == "" {
+				return fmt.Errorf("COLUMN_ENCRYPTION_KMS_KEY_ID is required for the aws_kms key wrapper")
+			}
+		case "vault_transit":
+		default:
+			return fmt.Errorf("invalid COLUMN_ENCRYPTION_KEY_WRAPPER %q: must be local, aws_kms or vault_transit", ColumnEncryptionKeyWrapper)
+		}
+	}
+	if ColumnEncryptionRotationInterval, err = GetEnvDurationOrDefault("COLUMN_ENCRYPTION_ROTATION_INTERVAL", DefaultColumnEncryptionRotationInterval); err != nil {
+		return err
+	}
+	if ColumnEncryptionRotationInterval < 0 {
+		return fmt.Errorf("invalid COLUMN_ENCRYPTION_ROTATION_INTERVAL %s: must not be negative", ColumnEncryptionRotationInterval)
+	}
+
+	// Shutdown Config
+	if HTTPShutdownTimeout, err = GetEnvDurationOrDefault("HTTP_SHUTDOWN_TIMEOUT", DefaultHTTPShutdownTimeout); err != nil {
+		return err
+	}
+	if GRPCShutdownTimeout, err = GetEnvDurationOrDefault("GRPC_SHUTDOWN_TIMEOUT", DefaultGRPCShutdownTimeout); err != nil {
+		return err
+	}
+
+	// Authorization Audit Sampling Config — denials are sampled in full by
+	// default since they are what "why was I denied?" tickets are about.
+	if AuthzAuditAllowSampleRate, err = GetEnvRatioOrDefault("AUTHZ_AUDIT_ALLOW_SAMPLE_RATE", DefaultAuthzAuditAllowSampleRate); err != nil {
+		return err
+	}
+	if AuthzAuditDenySampleRate, err = GetEnvRatioOrDefault("AUTHZ_AUDIT_DENY_SAMPLE_RATE", DefaultAuthzAuditDenySampleRate); err != nil {
+		return err
+	}
+	if AuthzAuditMaxPerSecond, err = GetEnvIntOrDefault("AUTHZ_AUDIT_MAX_PER_SECOND", DefaultAuthzAuditMaxPerSecond); err != nil {
+		return err
+	}
+
+	// Breached Password Check Config — tenants still opt in individually via
+	// the breached_password_check feature flag.
+	if BreachedPasswordCheckEnabled, err = GetEnvBoolOrDefault("BREACHED_PASSWORD_CHECK_ENABLED", true); err != nil {
+		return err
+	}
+	BreachedPasswordAPIURL = GetEnvOrDefault("BREACHED_PASSWORD_API_URL", security.DefaultHIBPRangeURL)
+	if BreachedPasswordThreshold, err = GetEnvIntOrDefault("BREACHED_PASSWORD_THRESHOLD", DefaultBreachedPasswordThreshold); err != nil {
+		return err
+	}
+	if BreachedPasswordThreshold < 1 {
+		return fmt.Errorf("invalid BREACHED_PASSWORD_THRESHOLD %d: must be at least 1", BreachedPasswordThreshold)
+	}
+	if BreachedPasswordTimeout, err = GetEnvDurationOrDefault("BREACHED_PASSWORD_TIMEOUT", DefaultBreachedPasswordTimeout); err != nil {
+		return err
+	}
+
+	// GeoIP Config — lookups are off until a database path is set.
+	GeoIPDatabasePath = GetEnvOrDefault("GEOIP_DATABASE_PATH", "")
+	GeoIPASNDatabasePath = GetEnvOrDefault("GEOIP_ASN_DATABASE_PATH", "")
+	if GeoIPCacheSize, err = GetEnvIntOrDefault("GEOIP_CACHE_SIZE", DefaultGeoIPCacheSize); err != nil {
+		return err
+	}
+	if GeoIPCacheSize < 0 {
+		return fmt.Errorf("invalid GEOIP_CACHE_SIZE %d: must not be negative", GeoIPCacheSize)
+	}
+	if GeoIPCacheTTL, err = GetEnvDurationOrDefault("GEOIP_CACHE_TTL", DefaultGeoIPCacheTTL); err != nil {
+		return err
+	}
+
+	// Admin UI Config
+	if AdminUIEnabled, err = GetEnvBoolOrDefault("ADMIN_UI_ENABLED", false); err != nil {
+		return err
+	}
+
+	// API Docs Config — the Swagger UI assets are not embedded, so /docs
+	// loads them from this origin, which its CSP allows.
+	APIDocsSwaggerUIURL = strings.TrimSuffix(GetEnvOrDefault("API_DOCS_SWAGGER_UI_URL", DefaultAPIDocsSwaggerUIURL), "/")
+	if u, err := url.Parse(APIDocsSwaggerUIURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid API_DOCS_SWAGGER_UI_URL %q: must be an absolute http(s) URL", APIDocsSwaggerUIURL)
+	}
+
+	// Step-Up Config
+	if StepUpMaxAge, err = GetEnvDurationOrDefault("STEP_UP_MAX_AGE", DefaultStepUpMaxAge); err != nil {
+		return err
+	}
+	if StepUpMaxAge < 0 {
+		return fmt.Errorf("invalid STEP_UP_MAX_AGE %s: must not be negative", StepUpMaxAge)
+	}
+
+	// Self-Service Tenant Config
+	if SelfServiceTenantsEnabled, err = GetEnvBoolOrDefault("SELF_SERVICE_TENANTS_ENABLED", false); err != nil {
+		return err
+	}
+
+	// Hosted Page Session Config — the cookie store keys are loaded via the
+	// configured secret provider and only when that store is selected.
+	HostedSessionStore = GetEnvOrDefault("HOSTED_SESSION_STORE", session.StoreRedis)
+	HostedSessionKeys = nil
+	switch HostedSessionStore {
+	case session.StoreRedis:
+	case session.StoreCookie:
+		rawKeys, err := loadSecret("HOSTED_SESSION_KEYS")
+		if err != nil {
+			return fmt.Errorf("failed to load hosted session keys: %w", err)
+		}
+		if HostedSessionKeys, err = parseSessionKeys(string(rawKeys)); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid HOSTED_SESSION_STORE %q: must be %s or %s", HostedSessionStore, session.StoreRedis, session.StoreCookie)
+	}
+	if HostedSessionSameSite, err = session.ParseSameSite(GetEnvOrDefault("HOSTED_SESSION_SAMESITE", "lax")); err != nil {
+		return fmt.Errorf("invalid HOSTED_SESSION_SAMESITE: %w", err)
+	}
+	if HostedSessionTTL, err = GetEnvDurationOrDefault("HOSTED_SESSION_TTL", DefaultHostedSessionTTL); err != nil {
+		return err
+	}
+
+	// Browser Session Config — always backed by Redis.
+	if BrowserSessionsEnabled, err = GetEnvBoolOrDefault("BROWSER_SESSIONS_ENABLED", false); err != nil {
+		return err
+	}
+	if BrowserSessionSameSite, err = session.ParseSameSite(GetEnvOrDefault("BROWSER_SESSION_SAMESITE", "lax")); err != nil {
+		return fmt.Errorf("invalid BROWSER_SESSION_SAMESITE: %w", err)
+	}
+	if BrowserSessionIdleTimeout, err = GetEnvDurationOrDefault("BROWSER_SESSION_IDLE_TIMEOUT", DefaultBrowserSessionIdleTimeout); err != nil {
+		return err
+	}
+	if BrowserSessionMaxLifetime, err = GetEnvDurationOrDefault("BROWSER_SESSION_MAX_LIFETIME", DefaultBrowserSessionMaxLifetime); err != nil {
+		return err
+	}
+	if BrowserSessionIdleTimeout <= 0 || BrowserSessionMaxLifetime < BrowserSessionIdleTimeout {
+		return fmt.Errorf("invalid BROWSER_SESSION_IDLE_TIMEOUT %s: must be positive and not exceed BROWSER_SESSION_MAX_LIFETIME %s", BrowserSessionIdleTimeout, BrowserSessionMaxLifetime)
+	}
+
+	// Event Stream Config — only validated when a provider is selected.
+	EventStreamProvider = GetEnvOrDefault("EVENT_STREAM_PROVIDER", "")
+	EventStreamBrokers = splitList(GetEnvOrDefault("EVENT_STREAM_BROKERS", ""))
+	EventStreamTopic = GetEnvOrDefault("EVENT_STREAM_TOPIC", eventstream.DefaultTopic)
+	EventStreamEventTypes = splitList(GetEnvOrDefault("EVENT_STREAM_EVENT_TYPES", "*"))
+	EventStreamUsername = GetEnvOrDefault("EVENT_STREAM_USERNAME", "")
+	EventStreamPassword = nil
+	EventStreamSASLMechanism = GetEnvOrDefault("EVENT_STREAM_SASL_MECHANISM", "")
+	if EventStreamTLS, err = GetEnvBoolOrDefault("EVENT_STREAM_TLS", false); err != nil {
+		return err
+	}
+	if EventStreamTimeout, err = GetEnvDurationOrDefault("EVENT_STREAM_TIMEOUT", eventstream.DefaultTimeout); err != nil {
+		return err
+	}
+	switch EventStreamProvider {
+	case "":
+	case eventstream.ProviderKafka, eventstream.ProviderNATS:
+		if len(EventStreamBrokers) == 0 {
+			return fmt.Errorf("EVENT_STREAM_BROKERS is required when EVENT_STREAM_PROVIDER is %s", EventStreamProvider)
+		}
+		if EventStreamUsername != "" {
+			if EventStreamPassword, err = loadSecret("EVENT_STREAM_PASSWORD"); err != nil {
+				return fmt.Errorf("failed to load event stream password: %w", err)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid EVENT_STREAM_PROVIDER %q: must be %s or %s", EventStreamProvider, eventstream.ProviderKafka, eventstream.ProviderNATS)
+	}
+
+	// SIEM Config — only validated when a provider is selected.
+	SIEMProvider = GetEnvOrDefault("SIEM_PROVIDER", "")
+	SIEMEndpoint = GetEnvOrDefault("SIEM_ENDPOINT", "")
+	SIEMSyslogNetwork = GetEnvOrDefault("SIEM_SYSLOG_NETWORK", siem.SyslogTCP)
+	SIEMToken = nil
+	SIEMSplunkIndex = GetEnvOrDefault("SIEM_SPLUNK_INDEX", "")
+	SIEMMinSeverity = strings.ToUpper(GetEnvOrDefault("SIEM_MIN_SEVERITY", siem.DefaultMinSeverity))
+	if SIEMBatchSize, err = GetEnvIntOrDefault("SIEM_BATCH_SIZE", siem.DefaultBatchSize); err != nil {
+		return err
+	}
+	if SIEMFlushInterval, err = GetEnvDurationOrDefault("SIEM_FLUSH_INTERVAL", siem.DefaultFlushInterval); err != nil {
+		return err
+	}
+	if SIEMMaxAttempts, err = GetEnvIntOrDefault("SIEM_MAX_ATTEMPTS", siem.DefaultMaxAttempts); err != nil {
+		return err
+	}
+	if SIEMTimeout, err = GetEnvDurationOrDefault("SIEM_TIMEOUT", siem.DefaultTimeout); err != nil {
+		return err
+	}
+	switch SIEMProvider {
+	case "":
+	case siem.ProviderSyslog, siem.ProviderSplunk, siem.ProviderHTTPS:
+		if SIEMEndpoint == "" {
+			return fmt.Errorf("SIEM_ENDPOINT is required when SIEM_PROVIDER is %s", SIEMProvider)
+		}
+		if SIEMProvider == siem.ProviderSyslog {
+			if SIEMSyslogNetwork != siem.SyslogUDP && SIEMSyslogNetwork != siem.SyslogTCP && SIEMSyslogNetwork != siem.SyslogTLS {
+				return fmt.Errorf("invalid SIEM_SYSLOG_NETWORK %q: must be %s, %s or %s", SIEMSyslogNetwork, siem.SyslogUDP, siem.SyslogTCP, siem.SyslogTLS)
+			}
+		} else if u, err := url.Parse(SIEMEndpoint); err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("invalid SIEM_ENDPOINT %q: must be an https URL when SIEM_PROVIDER is %s", SIEMEndpoint, SIEMProvider)
+		}
+		if SIEMProvider != siem.ProviderSyslog {
+			if SIEMToken, err = loadSecret("SIEM_TOKEN"); err != nil {
+				return fmt.Errorf("failed to load SIEM token: %w", err)
+			}
+		}
+		if !siem.ValidSeverity(SIEMMinSeverity) {
+			return fmt.Errorf("invalid SIEM_MIN_SEVERITY %q: must be %s, %s or %s", SIEMMinSeverity, siem.SeverityLow, siem.SeverityMedium, siem.SeverityHigh)
+		}
+		if SIEMBatchSize <= 0 || SIEMMaxAttempts <= 0 || SIEMFlushInterval <= 0 || SIEMTimeout <= 0 {
+			return fmt.Errorf("invalid SIEM batching: SIEM_BATCH_SIZE, SIEM_MAX_ATTEMPTS, SIEM_FLUSH_INTERVAL and SIEM_TIMEOUT must be positive")
+		}
+	default:
+		return fmt.Errorf("invalid SIEM_PROVIDER %q: must be %s, %s or %s", SIEMProvider, siem.ProviderSyslog, siem.ProviderSplunk, siem.ProviderHTTPS)
+	}
+
+	// Object Storage Config — only validated when a provider is selected.
+	StorageProvider = GetEnvOrDefault("STORAGE_PROVIDER", "")
+	StorageLocalDir = GetEnvOrDefault("STORAGE_LOCAL_DIR", DefaultStorageLocalDir)
+	StorageBucket = GetEnvOrDefault("STORAGE_BUCKET", "")
+	StorageRegion = GetEnvOrDefault("STORAGE_REGION", "")
+	StorageEndpoint = GetEnvOrDefault("STORAGE_ENDPOINT", "")
+	StorageAccessKeyID = GetEnvOrDefault("STORAGE_ACCESS_KEY_ID", "")
+	StorageSecretAccessKey = nil
+	if StoragePathStyle, err = GetEnvBoolOrDefault("STORAGE_PATH_STYLE", false); err != nil {
+		return err
+	}
+	switch StorageProvider {
+	case "", storage.ProviderLocal:
+	case storage.ProviderS3, storage.ProviderGCS:
+		if StorageBucket == "" {
+			return fmt.Errorf("STORAGE_BUCKET is required when STORAGE_PROVIDER is %s", StorageProvider)
+		}
+		if StorageProvider == storage.ProviderS3 && StorageRegion == "" {
+			return fmt.Errorf("STORAGE_REGION is required when STORAGE_PROVIDER is %s", StorageProvider)
+		}
+		if StorageProvider == storage.ProviderGCS && StorageAccessKeyID == "" {
+			return fmt.Errorf("STORAGE_ACCESS_KEY_ID is required when STORAGE_PROVIDER is %s", StorageProvider)
+		}
+		if StorageAccessKeyID != "" {
+			if StorageSecretAccessKey, err = loadSecret("STORAGE_SECRET_ACCESS_KEY"); err != nil {
+				return fmt.Errorf("failed to load storage secret access key: %w", err)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid STORAGE_PROVIDER %q: must be %s, %s or %s", StorageProvider, storage.ProviderLocal, storage.ProviderS3, storage.ProviderGCS)
+	}
+
+	// Profile Picture Config
+	if ProfilePictureMaxBytes, err = GetEnvIntOrDefault("PROFILE_PICTURE_MAX_BYTES", DefaultProfilePictureMaxBytes); err != nil {
+		return err
+	}
+	if ProfilePictureMaxBytes <= 0 {
+		return fmt.Errorf("invalid PROFILE_PICTURE_MAX_BYTES %d: must be positive", ProfilePictureMaxBytes)
+	}
+	if ProfilePictureMaxDimension, err = GetEnvIntOrDefault("PROFILE_PICTURE_MAX_DIMENSION", DefaultProfilePictureMaxDimension); err != nil {
+		return err
+	}
+	if ProfilePictureMaxDimension < 16 || ProfilePictureMaxDimension > 4096 {
+		return fmt.Errorf("invalid PROFILE_PICTURE_MAX_DIMENSION %d: must be between 16 and 4096", ProfilePictureMaxDimension)
+	}
+	if ProfilePictureURLTTL, err = GetEnvDurationOrDefault("PROFILE_PICTURE_URL_TTL", DefaultProfilePictureURLTTL); err != nil {
+		return err
+	}
+
+	SetCurrent(reloadable)
+	warnUnusedFileSettings()
 	return nil
 }
+
+// rateLimitPeriods maps the period suffixes of a rate limit policy to their
+// duration.
+var rateLimitPeriods = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+}
+
+// parseRateLimitPolicies parses a comma-separated list of
+// "<group>.<principal>=<requests>/<s|m|h>[:<burst>]" policies. The burst
+// defaults to the number of requests.
+func parseRateLimitPolicies(raw string) (map[string]cache.RateLimit, error) {
+	policies := make(map[string]cache.RateLimit)
+	for _, entry := range splitList(raw) {
+		name, spec, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_POLICIES entry %q: expected <group>.<principal>=<requests>/<period>", entry)
+		}
+		group, principal, ok := strings.Cut(strings.TrimSpace(name), ".")
+		if !ok || group == "" || !slices.Contains(cache.RateLimitPrincipals, principal) {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_POLICIES entry %q: name must be <group>.<%s>", entry, strings.Join(cache.RateLimitPrincipals, "|"))
+		}
+
+		spec, burstStr, hasBurst := strings.Cut(strings.TrimSpace(spec), ":")
+		requestsStr, periodStr, _ := strings.Cut(spec, "/")
+		requests, err := strconv.Atoi(requestsStr)
+		if err != nil || requests < 1 {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_POLICIES entry %q: requests must be a positive integer", entry)
+		}
+		period, ok := rateLimitPeriods[periodStr]
+		if !ok {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_POLICIES entry %q: period must be s, m or h", entry)
+		}
+		burst := requests
+		if hasBurst {
+			if burst, err = strconv.Atoi(burstStr); err != nil || burst < 1 {
+				return nil, fmt.Errorf("invalid RATE_LIMIT_POLICIES entry %q: burst must be a positive integer", entry)
+			}
+		}
+
+		policies[group+"."+principal] = cache.RateLimit{Requests: requests, Period: period, Burst: burst}
+	}
+	return policies, nil
+}
+
+// splitList splits a comma-separated value, dropping blank entries.
+func splitList(raw string) []string {
+	var items []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			items = append(items, part)
+		}
+	}
+	return items
+}
+
+// parseSessionKeys decodes a comma-separated list of base64 keys, current key
+// first. Each key must decode to session.KeySize bytes.
+func parseSessionKeys(raw string) ([][]byte, error) {
+	var keys [][]byte
+	for i, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, err := base64.StdEncoding.DecodeString(part)
+		if err != nil {
+			return nil, fmt.Errorf("HOSTED_SESSION_KEYS entry %d is not valid base64: %w", i, err)
+		}
+		if len(key) != session.KeySize {
+			return nil, fmt.Errorf("HOSTED_SESSION_KEYS entry %d must be %d bytes, got %d", i, session.KeySize, len(key))
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("HOSTED_SESSION_KEYS must contain at least one key")
+	}
+	return keys, nil
+}
@@ -1,8 +1,19 @@
 package config
 
 import (
+	"encoding/base64"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/eventstream"
+	"github.com/maintainerd/auth/internal/security"
+	"github.com/maintainerd/auth/internal/session"
+	"github.com/maintainerd/auth/internal/siem"
+	"github.com/maintainerd/auth/internal/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -49,13 +60,84 @@ func TestInit(t *testing.T) {
 		origDBPass := DBPassword
 		origDBName := DBName
 		origDBSSL := DBSSLMode
-		origSMTPHost := SMTPHost
-		origSMTPPort := SMTPPort
-		origSMTPUser := SMTPUser
-		origSMTPPass := SMTPPass
-		origSMTPFromEmail := SMTPFromEmail
-		origSMTPFromName := SMTPFromName
 		origEmailLogo := EmailLogo
+		origHTTPShutdown := HTTPShutdownTimeout
+		origGRPCShutdown := GRPCShutdownTimeout
+		origProfileEncEnabled := ProfileEncryptionEnabled
+		origProfileEncKey := ProfileEncryptionKey
+		origJWTKeyRotation := JWTKeyRotationEnabled
+		origJWTKeyEncKey := JWTKeyEncryptionKey
+		origJWTKeyInterval := JWTKeyRotationInterval
+		origJWTKeyRetire := JWTKeyRetireAfter
+		origColumnEncEnabled := ColumnEncryptionEnabled
+		origColumnEncWrapper := ColumnEncryptionKeyWrapper
+		origColumnEncKEK := ColumnEncryptionKEK
+		origColumnEncKEKRotating := ColumnEncryptionKEKRotating
+		origColumnEncPreviousKEK := ColumnEncryptionPreviousKEK
+		origColumnEncKMSKeyID := ColumnEncryptionKMSKeyID
+		origColumnEncTransitMount := ColumnEncryptionVaultTransitMount
+		origColumnEncTransitKey := ColumnEncryptionVaultTransitKey
+		origColumnEncInterval := ColumnEncryptionRotationInterval
+		origAuthzAllowRate := AuthzAuditAllowSampleRate
+		origAuthzDenyRate := AuthzAuditDenySampleRate
+		origAuthzMaxPerSecond := AuthzAuditMaxPerSecond
+		origBreachEnabled := BreachedPasswordCheckEnabled
+		origBreachURL := BreachedPasswordAPIURL
+		origBreachThreshold := BreachedPasswordThreshold
+		origBreachTimeout := BreachedPasswordTimeout
+		origGeoIPPath := GeoIPDatabasePath
+		origGeoIPASNPath := GeoIPASNDatabasePath
+		origGeoIPCacheSize := GeoIPCacheSize
+		origDBMaxOpen := DBMaxOpenConns
+		origDBMaxIdle := DBMaxIdleConns
+		origDBLifetime := DBConnMaxLifetime
+		origDBIdleTime := DBConnMaxIdleTime
+		origDBSlowQuery := DBSlowQueryThreshold
+		origDBReplicaHosts := DBReadReplicaHosts
+		origDBReplicaLag := DBReadReplicaLagWindow
+		origGeoIPCacheTTL := GeoIPCacheTTL
+		origAdminUIEnabled := AdminUIEnabled
+		origSwaggerUIURL := APIDocsSwaggerUIURL
+		origStepUpMaxAge := StepUpMaxAge
+		origSelfServiceTenants := SelfServiceTenantsEnabled
+		origSessionStore := HostedSessionStore
+		origSessionKeys := HostedSessionKeys
+		origSessionSameSite := HostedSessionSameSite
+		origSessionTTL := HostedSessionTTL
+		origBrowserSessions := BrowserSessionsEnabled
+		origBrowserSameSite := BrowserSessionSameSite
+		origBrowserIdle := BrowserSessionIdleTimeout
+		origBrowserMax := BrowserSessionMaxLifetime
+		origStreamProvider := EventStreamProvider
+		origStreamBrokers := EventStreamBrokers
+		origStreamTopic := EventStreamTopic
+		origStreamTypes := EventStreamEventTypes
+		origStreamUser := EventStreamUsername
+		origStreamPass := EventStreamPassword
+		origStreamSASL := EventStreamSASLMechanism
+		origStreamTLS := EventStreamTLS
+		origStreamTimeout := EventStreamTimeout
+		origSIEMProvider := SIEMProvider
+		origSIEMEndpoint := SIEMEndpoint
+		origSIEMNetwork := SIEMSyslogNetwork
+		origSIEMToken := SIEMToken
+		origSIEMIndex := SIEMSplunkIndex
+		origSIEMSeverity := SIEMMinSeverity
+		origSIEMBatch := SIEMBatchSize
+		origSIEMFlush := SIEMFlushInterval
+		origSIEMAttempts := SIEMMaxAttempts
+		origSIEMTimeout := SIEMTimeout
+		origStorageProvider := StorageProvider
+		origStorageDir := StorageLocalDir
+		origStorageBucket := StorageBucket
+		origStorageRegion := StorageRegion
+		origStorageEndpoint := StorageEndpoint
+		origStoragePathStyle := StoragePathStyle
+		origStorageKeyID := StorageAccessKeyID
+		origStorageSecret := StorageSecretAccessKey
+		origPictureMaxBytes := ProfilePictureMaxBytes
+		origPictureMaxDim := ProfilePictureMaxDimension
+		origPictureURLTTL := ProfilePictureURLTTL
 		t.Cleanup(func() {
 			activeSecretManager = origSM
 			SecretProvider = origProvider
@@ -73,13 +155,84 @@ func TestInit(t *testing.T) {
 			DBPassword = origDBPass
 			DBName = origDBName
 			DBSSLMode = origDBSSL
-			SMTPHost = origSMTPHost
-			SMTPPort = origSMTPPort
-			SMTPUser = origSMTPUser
-			SMTPPass = origSMTPPass
-			SMTPFromEmail = origSMTPFromEmail
-			SMTPFromName = origSMTPFromName
 			EmailLogo = origEmailLogo
+			HTTPShutdownTimeout = origHTTPShutdown
+			GRPCShutdownTimeout = origGRPCShutdown
+			ProfileEncryptionEnabled = origProfileEncEnabled
+			ProfileEncryptionKey = origProfileEncKey
+			JWTKeyRotationEnabled = origJWTKeyRotation
+			JWTKeyEncryptionKey = origJWTKeyEncKey
+			JWTKeyRotationInterval = origJWTKeyInterval
+			JWTKeyRetireAfter = origJWTKeyRetire
+			ColumnEncryptionEnabled = origColumnEncEnabled
+			ColumnEncryptionKeyWrapper = origColumnEncWrapper
+			ColumnEncryptionKEK = origColumnEncKEK
+			ColumnEncryptionKEKRotating = origColumnEncKEKRotating
+			ColumnEncryptionPreviousKEK = origColumnEncPreviousKEK
+			ColumnEncryptionKMSKeyID = origColumnEncKMSKeyID
+			ColumnEncryptionVaultTransitMount = origColumnEncTransitMount
+			ColumnEncryptionVaultTransitKey = origColumnEncTransitKey
+			ColumnEncryptionRotationInterval = origColumnEncInterval
+			AuthzAuditAllowSampleRate = origAuthzAllowRate
+			AuthzAuditDenySampleRate = origAuthzDenyRate
+			AuthzAuditMaxPerSecond = origAuthzMaxPerSecond
+			BreachedPasswordCheckEnabled = origBreachEnabled
+			BreachedPasswordAPIURL = origBreachURL
+			BreachedPasswordThreshold = origBreachThreshold
+			BreachedPasswordTimeout = origBreachTimeout
+			GeoIPDatabasePath = origGeoIPPath
+			GeoIPASNDatabasePath = origGeoIPASNPath
+			GeoIPCacheSize = origGeoIPCacheSize
+			DBMaxOpenConns = origDBMaxOpen
+			DBMaxIdleConns = origDBMaxIdle
+			DBConnMaxLifetime = origDBLifetime
+			DBConnMaxIdleTime = origDBIdleTime
+			DBSlowQueryThreshold = origDBSlowQuery
+			DBReadReplicaHosts = origDBReplicaHosts
+			DBReadReplicaLagWindow = origDBReplicaLag
+			GeoIPCacheTTL = origGeoIPCacheTTL
+			AdminUIEnabled = origAdminUIEnabled
+			APIDocsSwaggerUIURL = origSwaggerUIURL
+			StepUpMaxAge = origStepUpMaxAge
+			SelfServiceTenantsEnabled = origSelfServiceTenants
+			HostedSessionStore = origSessionStore
+			HostedSessionKeys = origSessionKeys
+			HostedSessionSameSite = origSessionSameSite
+			HostedSessionTTL = origSessionTTL
+			BrowserSessionsEnabled = origBrowserSessions
+			BrowserSessionSameSite = origBrowserSameSite
+			BrowserSessionIdleTimeout = origBrowserIdle
+			BrowserSessionMaxLifetime = origBrowserMax
+			EventStreamProvider = origStreamProvider
+			EventStreamBrokers = origStreamBrokers
+			EventStreamTopic = origStreamTopic
+			EventStreamEventTypes = origStreamTypes
+			EventStreamUsername = origStreamUser
+			EventStreamPassword = origStreamPass
+			EventStreamSASLMechanism = origStreamSASL
+			EventStreamTLS = origStreamTLS
+			EventStreamTimeout = origStreamTimeout
+			SIEMProvider = origSIEMProvider
+			SIEMEndpoint = origSIEMEndpoint
+			SIEMSyslogNetwork = origSIEMNetwork
+			SIEMToken = origSIEMToken
+			SIEMSplunkIndex = origSIEMIndex
+			SIEMMinSeverity = origSIEMSeverity
+			SIEMBatchSize = origSIEMBatch
+			SIEMFlushInterval = origSIEMFlush
+			SIEMMaxAttempts = origSIEMAttempts
+			SIEMTimeout = origSIEMTimeout
+			StorageProvider = origStorageProvider
+			StorageLocalDir = origStorageDir
+			StorageBucket = origStorageBucket
+			StorageRegion = origStorageRegion
+			StorageEndpoint = origStorageEndpoint
+			StoragePathStyle = origStoragePathStyle
+			StorageAccessKeyID = origStorageKeyID
+			StorageSecretAccessKey = origStorageSecret
+			ProfilePictureMaxBytes = origPictureMaxBytes
+			ProfilePictureMaxDimension = origPictureMaxDim
+			ProfilePictureURLTTL = origPictureURLTTL
 		})
 	}
 
@@ -103,10 +256,810 @@ func TestInit(t *testing.T) {
 		assert.Equal(t, "pass", DBPassword)
 		assert.Equal(t, "authdb", DBName)
 		assert.Equal(t, "disable", DBSSLMode)
-		assert.Equal(t, "smtp.example.com", SMTPHost)
-		assert.Equal(t, 587, SMTPPort)
-		assert.Equal(t, "user", SMTPUser)
-		assert.Equal(t, "pass", SMTPPass)
+		assert.Equal(t, DefaultDBMaxOpenConns, DBMaxOpenConns)
+		assert.Equal(t, DefaultDBMaxIdleConns, DBMaxIdleConns)
+		assert.Equal(t, DefaultDBConnMaxLifetime, DBConnMaxLifetime)
+		assert.Equal(t, DefaultDBConnMaxIdleTime, DBConnMaxIdleTime)
+		assert.Equal(t, DefaultDBSlowQueryThreshold, DBSlowQueryThreshold)
+		assert.Empty(t, DBReadReplicaHosts)
+		assert.Equal(t, DefaultDBReadReplicaLagWindow, DBReadReplicaLagWindow)
+		assert.Equal(t, "smtp.example.com", Current().SMTP.Host)
+		assert.Equal(t, 587, Current().SMTP.Port)
+		assert.Equal(t, "user", Current().SMTP.User)
+		assert.Equal(t, "pass", Current().SMTP.Pass)
+		assert.Equal(t, DefaultHTTPShutdownTimeout, HTTPShutdownTimeout)
+		assert.Equal(t, DefaultGRPCShutdownTimeout, GRPCShutdownTimeout)
+		assert.False(t, ProfileEncryptionEnabled)
+		assert.Nil(t, ProfileEncryptionKey)
+		assert.False(t, JWTKeyRotationEnabled)
+		assert.Nil(t, JWTKeyEncryptionKey)
+		assert.Equal(t, DefaultJWTKeyRotationInterval, JWTKeyRotationInterval)
+		assert.Equal(t, DefaultJWTKeyRetireAfter, JWTKeyRetireAfter)
+		assert.False(t, ColumnEncryptionEnabled)
+		assert.Equal(t, "local", ColumnEncryptionKeyWrapper)
+		assert.Nil(t, ColumnEncryptionKEK)
+		assert.Equal(t, DefaultColumnEncryptionRotationInterval, ColumnEncryptionRotationInterval)
+		assert.Equal(t, DefaultAuthzAuditAllowSampleRate, AuthzAuditAllowSampleRate)
+		assert.Equal(t, DefaultAuthzAuditDenySampleRate, AuthzAuditDenySampleRate)
+		assert.Equal(t, DefaultAuthzAuditMaxPerSecond, AuthzAuditMaxPerSecond)
+		assert.True(t, BreachedPasswordCheckEnabled)
+		assert.Equal(t, security.DefaultHIBPRangeURL, BreachedPasswordAPIURL)
+		assert.Equal(t, DefaultBreachedPasswordThreshold, BreachedPasswordThreshold)
+		assert.Equal(t, DefaultBreachedPasswordTimeout, BreachedPasswordTimeout)
+		assert.Empty(t, GeoIPDatabasePath)
+		assert.Empty(t, GeoIPASNDatabasePath)
+		assert.Equal(t, DefaultGeoIPCacheSize, GeoIPCacheSize)
+		assert.Equal(t, DefaultGeoIPCacheTTL, GeoIPCacheTTL)
+		assert.False(t, AdminUIEnabled)
+		assert.Equal(t, DefaultAPIDocsSwaggerUIURL, APIDocsSwaggerUIURL)
+		assert.Equal(t, DefaultStepUpMaxAge, StepUpMaxAge)
+		assert.False(t, SelfServiceTenantsEnabled)
+		assert.Equal(t, DefaultSelfServiceTenantLimit, Current().SelfServiceTenantLimit)
+		assert.Equal(t, session.StoreRedis, HostedSessionStore)
+		assert.Nil(t, HostedSessionKeys)
+		assert.Equal(t, http.SameSiteLaxMode, HostedSessionSameSite)
+		assert.Equal(t, DefaultHostedSessionTTL, HostedSessionTTL)
+		assert.False(t, BrowserSessionsEnabled)
+		assert.Equal(t, http.SameSiteLaxMode, BrowserSessionSameSite)
+		assert.Equal(t, DefaultBrowserSessionIdleTimeout, BrowserSessionIdleTimeout)
+		assert.Equal(t, DefaultBrowserSessionMaxLifetime, BrowserSessionMaxLifetime)
+	})
+
+	t.Run("profile encryption enabled", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("PROFILE_ENCRYPTION_ENABLED", "true")
+		t.Setenv("PROFILE_ENCRYPTION_KEY", "base64:AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=")
+
+		require.NoError(t, Init())
+		assert.True(t, ProfileEncryptionEnabled)
+		assert.Len(t, ProfileEncryptionKey, crypto.FieldKeySize)
+	})
+
+	t.Run("profile encryption key wrong size", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("PROFILE_ENCRYPTION_ENABLED", "true")
+		t.Setenv("PROFILE_ENCRYPTION_KEY", "too-short")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "PROFILE_ENCRYPTION_KEY must be 32 bytes")
+	})
+
+	t.Run("invalid PROFILE_ENCRYPTION_ENABLED", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("PROFILE_ENCRYPTION_ENABLED", "maybe")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "PROFILE_ENCRYPTION_ENABLED")
+	})
+
+	t.Run("JWT key rotation enabled", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("JWT_KEY_ROTATION_ENABLED", "true")
+		t.Setenv("JWT_KEY_ENCRYPTION_KEY", "base64:AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=")
+		t.Setenv("JWT_KEY_ROTATION_INTERVAL", "720h")
+		t.Setenv("JWT_KEY_RETIRE_AFTER", "2h")
+
+		require.NoError(t, Init())
+		assert.True(t, JWTKeyRotationEnabled)
+		assert.Len(t, JWTKeyEncryptionKey, crypto.FieldKeySize)
+		assert.Equal(t, 720*time.Hour, JWTKeyRotationInterval)
+		assert.Equal(t, 2*time.Hour, JWTKeyRetireAfter)
+	})
+
+	t.Run("JWT key encryption key wrong size", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("JWT_KEY_ROTATION_ENABLED", "true")
+		t.Setenv("JWT_KEY_ENCRYPTION_KEY", "too-short")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "JWT_KEY_ENCRYPTION_KEY must be 32 bytes")
+	})
+
+	t.Run("invalid JWT key rotation durations", func(t *testing.T) {
+		for env, value := range map[string]string{
+			"JWT_KEY_ROTATION_INTERVAL": "-1h",
+			"JWT_KEY_RETIRE_AFTER":      "0s",
+		} {
+			t.Run(env, func(t *testing.T) {
+				saveGlobals(t)
+				setRequiredEnv(t)
+				t.Setenv(env, value)
+
+				err := Init()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), env)
+			})
+		}
+	})
+
+	t.Run("column encryption with a local key", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("COLUMN_ENCRYPTION_ENABLED", "true")
+		t.Setenv("COLUMN_ENCRYPTION_KEK", "base64:AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=")
+		t.Setenv("COLUMN_ENCRYPTION_ROTATION_INTERVAL", "720h")
+
+		require.NoError(t, Init())
+		assert.True(t, ColumnEncryptionEnabled)
+		assert.Len(t, ColumnEncryptionKEK, crypto.FieldKeySize)
+		assert.Nil(t, ColumnEncryptionPreviousKEK)
+		assert.Equal(t, 720*time.Hour, ColumnEncryptionRotationInterval)
+	})
+
+	t.Run("column encryption with a rotating local key", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("COLUMN_ENCRYPTION_ENABLED", "true")
+		t.Setenv("COLUMN_ENCRYPTION_KEK", "base64:AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=")
+		t.Setenv("COLUMN_ENCRYPTION_KEK_ROTATING", "true")
+		t.Setenv("COLUMN_ENCRYPTION_PREVIOUS_KEK", "base64:HwECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=")
+
+		require.NoError(t, Init())
+		assert.Len(t, ColumnEncryptionPreviousKEK, crypto.FieldKeySize)
+		assert.NotEqual(t, ColumnEncryptionKEK, ColumnEncryptionPreviousKEK)
+	})
+
+	t.Run("column encryption errors", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			env  map[string]string
+			want string
+		}{
+			"short KEK": {
+				env:  map[string]string{"COLUMN_ENCRYPTION_KEK": "too-short"},
+				want: "COLUMN_ENCRYPTION_KEK must be 32 bytes",
+			},
+			"short previous KEK": {
+				env: map[string]string{
+					"COLUMN_ENCRYPTION_KEK":          "base64:AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=",
+					"COLUMN_ENCRYPTION_KEK_ROTATING": "true",
+					"COLUMN_ENCRYPTION_PREVIOUS_KEK": "too-short",
+				},
+				want: "COLUMN_ENCRYPTION_PREVIOUS_KEK must be 32 bytes",
+			},
+			"KMS without a key": {
+				env:  map[string]string{"COLUMN_ENCRYPTION_KEY_WRAPPER": "aws_kms"},
+				want: "COLUMN_ENCRYPTION_KMS_KEY_ID",
+			},
+			"unknown wrapper": {
+				env:  map[string]string{"COLUMN_ENCRYPTION_KEY_WRAPPER": "rot13"},
+				want: "COLUMN_ENCRYPTION_KEY_WRAPPER",
+			},
+			"negative interval": {
+				env: map[string]string{
+					"COLUMN_ENCRYPTION_KEY_WRAPPER":       "vault_transit",
+					"COLUMN_ENCRYPTION_ROTATION_INTERVAL": "-1h",
+				},
+				want: "COLUMN_ENCRYPTION_ROTATION_INTERVAL",
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				saveGlobals(t)
+				setRequiredEnv(t)
+				t.Setenv("COLUMN_ENCRYPTION_ENABLED", "true")
+				for k, v := range tc.env {
+					t.Setenv(k, v)
+				}
+
+				err := Init()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.want)
+			})
+		}
+	})
+
+	t.Run("custom shutdown timeouts", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("HTTP_SHUTDOWN_TIMEOUT", "1m")
+		t.Setenv("GRPC_SHUTDOWN_TIMEOUT", "5s")
+
+		require.NoError(t, Init())
+		assert.Equal(t, time.Minute, HTTPShutdownTimeout)
+		assert.Equal(t, 5*time.Second, GRPCShutdownTimeout)
+	})
+
+	t.Run("invalid shutdown timeout", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("HTTP_SHUTDOWN_TIMEOUT", "forever")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HTTP_SHUTDOWN_TIMEOUT")
+	})
+
+	t.Run("custom authz audit sampling", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("AUTHZ_AUDIT_ALLOW_SAMPLE_RATE", "0.01")
+		t.Setenv("AUTHZ_AUDIT_DENY_SAMPLE_RATE", "0.5")
+		t.Setenv("AUTHZ_AUDIT_MAX_PER_SECOND", "100")
+
+		require.NoError(t, Init())
+		assert.Equal(t, 0.01, AuthzAuditAllowSampleRate)
+		assert.Equal(t, 0.5, AuthzAuditDenySampleRate)
+		assert.Equal(t, 100, AuthzAuditMaxPerSecond)
+	})
+
+	t.Run("invalid authz audit sample rate", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("AUTHZ_AUDIT_DENY_SAMPLE_RATE", "2")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "AUTHZ_AUDIT_DENY_SAMPLE_RATE")
+	})
+
+	t.Run("invalid authz audit rate cap", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("AUTHZ_AUDIT_MAX_PER_SECOND", "lots")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "AUTHZ_AUDIT_MAX_PER_SECOND")
+	})
+
+	t.Run("custom breached password check", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("BREACHED_PASSWORD_CHECK_ENABLED", "false")
+		t.Setenv("BREACHED_PASSWORD_API_URL", "https://hibp.internal/range/")
+		t.Setenv("BREACHED_PASSWORD_THRESHOLD", "10")
+		t.Setenv("BREACHED_PASSWORD_TIMEOUT", "500ms")
+
+		require.NoError(t, Init())
+		assert.False(t, BreachedPasswordCheckEnabled)
+		assert.Equal(t, "https://hibp.internal/range/", BreachedPasswordAPIURL)
+		assert.Equal(t, 10, BreachedPasswordThreshold)
+		assert.Equal(t, 500*time.Millisecond, BreachedPasswordTimeout)
+	})
+
+	t.Run("breached password threshold below 1", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("BREACHED_PASSWORD_THRESHOLD", "0")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "BREACHED_PASSWORD_THRESHOLD")
+	})
+
+	t.Run("custom geoip", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("GEOIP_DATABASE_PATH", "/var/lib/geoip/GeoLite2-City.mmdb")
+		t.Setenv("GEOIP_ASN_DATABASE_PATH", "/var/lib/geoip/GeoLite2-ASN.mmdb")
+		t.Setenv("GEOIP_CACHE_SIZE", "0")
+		t.Setenv("GEOIP_CACHE_TTL", "10m")
+
+		require.NoError(t, Init())
+		assert.Equal(t, "/var/lib/geoip/GeoLite2-City.mmdb", GeoIPDatabasePath)
+		assert.Equal(t, "/var/lib/geoip/GeoLite2-ASN.mmdb", GeoIPASNDatabasePath)
+		assert.Equal(t, 0, GeoIPCacheSize)
+		assert.Equal(t, 10*time.Minute, GeoIPCacheTTL)
+	})
+
+	t.Run("db pool settings", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("DB_MAX_OPEN_CONNS", "50")
+		t.Setenv("DB_MAX_IDLE_CONNS", "20")
+		t.Setenv("DB_CONN_MAX_LIFETIME", "1h")
+		t.Setenv("DB_CONN_MAX_IDLE_TIME", "90s")
+		t.Setenv("DB_SLOW_QUERY_THRESHOLD", "500ms")
+
+		require.NoError(t, Init())
+		assert.Equal(t, 50, DBMaxOpenConns)
+		assert.Equal(t, 20, DBMaxIdleConns)
+		assert.Equal(t, time.Hour, DBConnMaxLifetime)
+		assert.Equal(t, 90*time.Second, DBConnMaxIdleTime)
+		assert.Equal(t, 500*time.Millisecond, DBSlowQueryThreshold)
+	})
+
+	t.Run("db read replicas", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("DB_READ_REPLICA_HOSTS", "replica-1, replica-2:6432")
+		t.Setenv("DB_READ_REPLICA_LAG_WINDOW", "5s")
+
+		require.NoError(t, Init())
+		assert.Equal(t, []string{"replica-1", "replica-2:6432"}, DBReadReplicaHosts)
+		assert.Equal(t, 5*time.Second, DBReadReplicaLagWindow)
+	})
+
+	t.Run("invalid db pool settings", func(t *testing.T) {
+		for name, env := range map[string]map[string]string{
+			"DB_MAX_OPEN_CONNS":          {"DB_MAX_OPEN_CONNS": "-1"},
+			"DB_MAX_IDLE_CONNS":          {"DB_MAX_OPEN_CONNS": "5", "DB_MAX_IDLE_CONNS": "10"},
+			"DB_CONN_MAX_LIFETIME":       {"DB_CONN_MAX_LIFETIME": "-1m"},
+			"DB_CONN_MAX_IDLE_TIME":      {"DB_CONN_MAX_IDLE_TIME": "-1m"},
+			"DB_SLOW_QUERY_THRESHOLD":    {"DB_SLOW_QUERY_THRESHOLD": "-1s"},
+			"DB_READ_REPLICA_LAG_WINDOW": {"DB_READ_REPLICA_LAG_WINDOW": "soon"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				saveGlobals(t)
+				setRequiredEnv(t)
+				for k, v := range env {
+					t.Setenv(k, v)
+				}
+
+				err := Init()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), name)
+			})
+		}
+	})
+
+	t.Run("negative geoip cache size", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("GEOIP_CACHE_SIZE", "-1")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "GEOIP_CACHE_SIZE")
+	})
+
+	t.Run("self-service tenants enabled", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("SELF_SERVICE_TENANTS_ENABLED", "true")
+		t.Setenv("SELF_SERVICE_TENANT_LIMIT", "0")
+
+		require.NoError(t, Init())
+		assert.True(t, SelfServiceTenantsEnabled)
+		assert.Equal(t, 0, Current().SelfServiceTenantLimit)
+	})
+
+	t.Run("negative self-service tenant limit", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("SELF_SERVICE_TENANT_LIMIT", "-1")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "SELF_SERVICE_TENANT_LIMIT")
+	})
+
+	t.Run("admin UI enabled", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("ADMIN_UI_ENABLED", "true")
+
+		require.NoError(t, Init())
+		assert.True(t, AdminUIEnabled)
+	})
+
+	t.Run("invalid ADMIN_UI_ENABLED", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("ADMIN_UI_ENABLED", "sometimes")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ADMIN_UI_ENABLED")
+	})
+
+	t.Run("custom Swagger UI URL", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("API_DOCS_SWAGGER_UI_URL", "https://assets.example.com/swagger-ui/")
+
+		require.NoError(t, Init())
+		assert.Equal(t, "https://assets.example.com/swagger-ui", APIDocsSwaggerUIURL)
+	})
+
+	t.Run("relative API_DOCS_SWAGGER_UI_URL", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("API_DOCS_SWAGGER_UI_URL", "/swagger-ui")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "API_DOCS_SWAGGER_UI_URL")
+	})
+
+	t.Run("step-up max age", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("STEP_UP_MAX_AGE", "15m")
+
+		require.NoError(t, Init())
+		assert.Equal(t, 15*time.Minute, StepUpMaxAge)
+	})
+
+	t.Run("negative STEP_UP_MAX_AGE", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("STEP_UP_MAX_AGE", "-1m")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "STEP_UP_MAX_AGE")
+	})
+
+	t.Run("cookie session store", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		current := strings.Repeat("a", session.KeySize)
+		previous := strings.Repeat("b", session.KeySize)
+		t.Setenv("HOSTED_SESSION_STORE", "cookie")
+		t.Setenv("HOSTED_SESSION_KEYS", base64.StdEncoding.EncodeToString([]byte(current))+", "+
+			base64.StdEncoding.EncodeToString([]byte(previous)))
+		t.Setenv("HOSTED_SESSION_SAMESITE", "strict")
+		t.Setenv("HOSTED_SESSION_TTL", "10m")
+
+		require.NoError(t, Init())
+		assert.Equal(t, session.StoreCookie, HostedSessionStore)
+		assert.Equal(t, [][]byte{[]byte(current), []byte(previous)}, HostedSessionKeys)
+		assert.Equal(t, http.SameSiteStrictMode, HostedSessionSameSite)
+		assert.Equal(t, 10*time.Minute, HostedSessionTTL)
+	})
+
+	t.Run("cookie session store without keys", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("HOSTED_SESSION_STORE", "cookie")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "hosted session keys")
+	})
+
+	t.Run("cookie session key wrong size", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("HOSTED_SESSION_STORE", "cookie")
+		t.Setenv("HOSTED_SESSION_KEYS", base64.StdEncoding.EncodeToString([]byte("short")))
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HOSTED_SESSION_KEYS")
+	})
+
+	t.Run("invalid HOSTED_SESSION_STORE", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("HOSTED_SESSION_STORE", "memcached")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HOSTED_SESSION_STORE")
+	})
+
+	t.Run("invalid HOSTED_SESSION_SAMESITE", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("HOSTED_SESSION_SAMESITE", "sometimes")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HOSTED_SESSION_SAMESITE")
+	})
+
+	t.Run("browser sessions", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("BROWSER_SESSIONS_ENABLED", "true")
+		t.Setenv("BROWSER_SESSION_SAMESITE", "strict")
+		t.Setenv("BROWSER_SESSION_IDLE_TIMEOUT", "15m")
+		t.Setenv("BROWSER_SESSION_MAX_LIFETIME", "8h")
+
+		require.NoError(t, Init())
+		assert.True(t, BrowserSessionsEnabled)
+		assert.Equal(t, http.SameSiteStrictMode, BrowserSessionSameSite)
+		assert.Equal(t, 15*time.Minute, BrowserSessionIdleTimeout)
+		assert.Equal(t, 8*time.Hour, BrowserSessionMaxLifetime)
+	})
+
+	t.Run("browser session idle timeout above max lifetime", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("BROWSER_SESSION_IDLE_TIMEOUT", "2h")
+		t.Setenv("BROWSER_SESSION_MAX_LIFETIME", "1h")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "BROWSER_SESSION_IDLE_TIMEOUT")
+	})
+
+	t.Run("invalid BROWSER_SESSION_SAMESITE", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("BROWSER_SESSION_SAMESITE", "sometimes")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "BROWSER_SESSION_SAMESITE")
+	})
+
+	t.Run("event stream disabled by default", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+
+		require.NoError(t, Init())
+		assert.Empty(t, EventStreamProvider)
+		assert.Equal(t, eventstream.DefaultTopic, EventStreamTopic)
+		assert.Equal(t, []string{"*"}, EventStreamEventTypes)
+		assert.Equal(t, eventstream.DefaultTimeout, EventStreamTimeout)
+	})
+
+	t.Run("kafka event stream", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("EVENT_STREAM_PROVIDER", "kafka")
+		t.Setenv("EVENT_STREAM_BROKERS", "kafka-1:9092, kafka-2:9092,")
+		t.Setenv("EVENT_STREAM_TOPIC", "auth.{type}")
+		t.Setenv("EVENT_STREAM_EVENT_TYPES", "user.*,role.created")
+		t.Setenv("EVENT_STREAM_USERNAME", "auth")
+		t.Setenv("EVENT_STREAM_PASSWORD", "s3cret")
+		t.Setenv("EVENT_STREAM_SASL_MECHANISM", "scram-sha-512")
+		t.Setenv("EVENT_STREAM_TLS", "true")
+		t.Setenv("EVENT_STREAM_TIMEOUT", "2s")
+
+		require.NoError(t, Init())
+		assert.Equal(t, eventstream.ProviderKafka, EventStreamProvider)
+		assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, EventStreamBrokers)
+		assert.Equal(t, "auth.{type}", EventStreamTopic)
+		assert.Equal(t, []string{"user.*", "role.created"}, EventStreamEventTypes)
+		assert.Equal(t, "auth", EventStreamUsername)
+		assert.Equal(t, []byte("s3cret"), EventStreamPassword)
+		assert.Equal(t, "scram-sha-512", EventStreamSASLMechanism)
+		assert.True(t, EventStreamTLS)
+		assert.Equal(t, 2*time.Second, EventStreamTimeout)
+	})
+
+	t.Run("event stream without brokers", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("EVENT_STREAM_PROVIDER", "nats")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "EVENT_STREAM_BROKERS")
+	})
+
+	t.Run("event stream username without password", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("EVENT_STREAM_PROVIDER", "nats")
+		t.Setenv("EVENT_STREAM_BROKERS", "nats://nats:4222")
+		t.Setenv("EVENT_STREAM_USERNAME", "auth")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "event stream password")
+	})
+
+	t.Run("invalid EVENT_STREAM_PROVIDER", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("EVENT_STREAM_PROVIDER", "rabbitmq")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "EVENT_STREAM_PROVIDER")
+	})
+
+	t.Run("siem disabled by default", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+
+		require.NoError(t, Init())
+		assert.Empty(t, SIEMProvider)
+		assert.Equal(t, siem.SyslogTCP, SIEMSyslogNetwork)
+		assert.Equal(t, siem.DefaultMinSeverity, SIEMMinSeverity)
+		assert.Equal(t, siem.DefaultBatchSize, SIEMBatchSize)
+		assert.Equal(t, siem.DefaultFlushInterval, SIEMFlushInterval)
+		assert.Equal(t, siem.DefaultMaxAttempts, SIEMMaxAttempts)
+		assert.Equal(t, siem.DefaultTimeout, SIEMTimeout)
+		assert.Nil(t, SIEMToken)
+	})
+
+	t.Run("syslog siem", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("SIEM_PROVIDER", "syslog")
+		t.Setenv("SIEM_ENDPOINT", "siem.internal:6514")
+		t.Setenv("SIEM_SYSLOG_NETWORK", "tcp+tls")
+		t.Setenv("SIEM_MIN_SEVERITY", "high")
+		t.Setenv("SIEM_BATCH_SIZE", "50")
+		t.Setenv("SIEM_FLUSH_INTERVAL", "2s")
+
+		require.NoError(t, Init())
+		assert.Equal(t, siem.ProviderSyslog, SIEMProvider)
+		assert.Equal(t, "siem.internal:6514", SIEMEndpoint)
+		assert.Equal(t, siem.SyslogTLS, SIEMSyslogNetwork)
+		assert.Equal(t, siem.SeverityHigh, SIEMMinSeverity)
+		assert.Equal(t, 50, SIEMBatchSize)
+		assert.Equal(t, 2*time.Second, SIEMFlushInterval)
+		assert.Nil(t, SIEMToken)
+	})
+
+	t.Run("splunk siem", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("SIEM_PROVIDER", "splunk")
+		t.Setenv("SIEM_ENDPOINT", "https://splunk.internal:8088/services/collector/event")
+		t.Setenv("SIEM_TOKEN", "hec-token")
+		t.Setenv("SIEM_SPLUNK_INDEX", "security")
+
+		require.NoError(t, Init())
+		assert.Equal(t, siem.ProviderSplunk, SIEMProvider)
+		assert.Equal(t, []byte("hec-token"), SIEMToken)
+		assert.Equal(t, "security", SIEMSplunkIndex)
+	})
+
+	t.Run("invalid siem settings", func(t *testing.T) {
+		tests := []struct {
+			name string
+			env  map[string]string
+			want string
+		}{
+			{"provider", map[string]string{"SIEM_PROVIDER": "kafka"}, "SIEM_PROVIDER"},
+			{"missing endpoint", map[string]string{"SIEM_PROVIDER": "syslog"}, "SIEM_ENDPOINT"},
+			{"syslog network", map[string]string{"SIEM_PROVIDER": "syslog", "SIEM_ENDPOINT": "siem:514", "SIEM_SYSLOG_NETWORK": "quic"}, "SIEM_SYSLOG_NETWORK"},
+			{"plain http endpoint", map[string]string{"SIEM_PROVIDER": "https", "SIEM_ENDPOINT": "http://siem.internal/events", "SIEM_TOKEN": "t"}, "SIEM_ENDPOINT"},
+			{"severity", map[string]string{"SIEM_PROVIDER": "syslog", "SIEM_ENDPOINT": "siem:514", "SIEM_MIN_SEVERITY": "WARN"}, "SIEM_MIN_SEVERITY"},
+			{"batch size", map[string]string{"SIEM_PROVIDER": "syslog", "SIEM_ENDPOINT": "siem:514", "SIEM_BATCH_SIZE": "0"}, "SIEM_BATCH_SIZE"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				saveGlobals(t)
+				setRequiredEnv(t)
+				for k, v := range tt.env {
+					t.Setenv(k, v)
+				}
+
+				err := Init()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.want)
+			})
+		}
+	})
+
+	t.Run("storage disabled by default", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+
+		require.NoError(t, Init())
+		assert.Empty(t, StorageProvider)
+		assert.Equal(t, DefaultStorageLocalDir, StorageLocalDir)
+		assert.Equal(t, DefaultProfilePictureMaxBytes, ProfilePictureMaxBytes)
+		assert.Equal(t, DefaultProfilePictureMaxDimension, ProfilePictureMaxDimension)
+		assert.Equal(t, DefaultProfilePictureURLTTL, ProfilePictureURLTTL)
+	})
+
+	t.Run("s3 storage", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("STORAGE_PROVIDER", "s3")
+		t.Setenv("STORAGE_BUCKET", "avatars")
+		t.Setenv("STORAGE_REGION", "eu-west-1")
+		t.Setenv("STORAGE_ENDPOINT", "http://minio:9000")
+		t.Setenv("STORAGE_PATH_STYLE", "true")
+		t.Setenv("STORAGE_ACCESS_KEY_ID", "minio")
+		t.Setenv("STORAGE_SECRET_ACCESS_KEY", "minio-secret")
+		t.Setenv("PROFILE_PICTURE_MAX_BYTES", "1048576")
+		t.Setenv("PROFILE_PICTURE_MAX_DIMENSION", "256")
+		t.Setenv("PROFILE_PICTURE_URL_TTL", "10m")
+
+		require.NoError(t, Init())
+		assert.Equal(t, storage.ProviderS3, StorageProvider)
+		assert.Equal(t, "avatars", StorageBucket)
+		assert.Equal(t, "eu-west-1", StorageRegion)
+		assert.Equal(t, "http://minio:9000", StorageEndpoint)
+		assert.True(t, StoragePathStyle)
+		assert.Equal(t, "minio", StorageAccessKeyID)
+		assert.Equal(t, []byte("minio-secret"), StorageSecretAccessKey)
+		assert.Equal(t, 1<<20, ProfilePictureMaxBytes)
+		assert.Equal(t, 256, ProfilePictureMaxDimension)
+		assert.Equal(t, 10*time.Minute, ProfilePictureURLTTL)
+	})
+
+	t.Run("storage misconfiguration", func(t *testing.T) {
+		cases := []struct {
+			name string
+			env  map[string]string
+			want string
+		}{
+			{"unknown provider", map[string]string{"STORAGE_PROVIDER": "ftp"}, "STORAGE_PROVIDER"},
+			{"s3 without bucket", map[string]string{"STORAGE_PROVIDER": "s3", "STORAGE_REGION": "us-east-1"}, "STORAGE_BUCKET"},
+			{"s3 without region", map[string]string{"STORAGE_PROVIDER": "s3", "STORAGE_BUCKET": "avatars"}, "STORAGE_REGION"},
+			{"gcs without HMAC key", map[string]string{"STORAGE_PROVIDER": "gcs", "STORAGE_BUCKET": "avatars"}, "STORAGE_ACCESS_KEY_ID"},
+			{"access key without secret", map[string]string{"STORAGE_PROVIDER": "gcs", "STORAGE_BUCKET": "avatars", "STORAGE_ACCESS_KEY_ID": "GOOG1"}, "storage secret access key"},
+			{"non-positive max bytes", map[string]string{"PROFILE_PICTURE_MAX_BYTES": "0"}, "PROFILE_PICTURE_MAX_BYTES"},
+			{"max dimension out of range", map[string]string{"PROFILE_PICTURE_MAX_DIMENSION": "8"}, "PROFILE_PICTURE_MAX_DIMENSION"},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				saveGlobals(t)
+				setRequiredEnv(t)
+				for k, v := range tc.env {
+					t.Setenv(k, v)
+				}
+
+				err := Init()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.want)
+			})
+		}
+	})
+
+	t.Run("default rate limit policies", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+
+		require.NoError(t, Init())
+		assert.True(t, Current().RateLimit.Enabled)
+		assert.Equal(t, cache.RateLimit{Requests: 60, Period: time.Minute, Burst: 60}, Current().RateLimit.Policies["auth.ip"])
+		assert.Len(t, Current().RateLimit.Policies, 5)
+	})
+
+	t.Run("custom rate limit policies", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("RATE_LIMIT_POLICIES", "auth.ip=5/s:20, management.user=1000/h")
+
+		require.NoError(t, Init())
+		assert.Equal(t, map[string]cache.RateLimit{
+			"auth.ip":         {Requests: 5, Period: time.Second, Burst: 20},
+			"management.user": {Requests: 1000, Period: time.Hour, Burst: 1000},
+		}, Current().RateLimit.Policies)
+	})
+
+	t.Run("rate limiting disabled", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("RATE_LIMIT_ENABLED", "false")
+		t.Setenv("RATE_LIMIT_POLICIES", "not a policy")
+
+		require.NoError(t, Init())
+		assert.False(t, Current().RateLimit.Enabled)
+		assert.Nil(t, Current().RateLimit.Policies)
+	})
+
+	t.Run("invalid RATE_LIMIT_POLICIES", func(t *testing.T) {
+		for _, policies := range []string{
+			"auth.ip",
+			"auth=10/m",
+			"auth.robot=10/m",
+			".ip=10/m",
+			"auth.ip=0/m",
+			"auth.ip=ten/m",
+			"auth.ip=10/d",
+			"auth.ip=10",
+			"auth.ip=10/m:0",
+			"auth.ip=10/m:x",
+		} {
+			saveGlobals(t)
+			setRequiredEnv(t)
+			t.Setenv("RATE_LIMIT_POLICIES", policies)
+
+			err := Init()
+			require.Error(t, err, policies)
+			assert.Contains(t, err.Error(), "RATE_LIMIT_POLICIES", policies)
+		}
+	})
+
+	t.Run("invalid RATE_LIMIT_ENABLED", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("RATE_LIMIT_ENABLED", "maybe")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "RATE_LIMIT_ENABLED")
 	})
 
 	t.Run("invalid secret provider", func(t *testing.T) {
@@ -130,6 +1083,39 @@ func TestInit(t *testing.T) {
 		assert.Contains(t, err.Error(), "failed to initialize secret manager")
 	})
 
+	t.Run("reports every missing setting", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("DB_HOST", "")
+		t.Setenv("SMTP_USER", "")
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "DB_HOST, SMTP_USER")
+	})
+
+	t.Run("settings from the config file", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv("DB_HOST", "")
+		t.Setenv("SMTP_PORT", "2525")
+		t.Setenv(ConfigFileEnv, writeConfigFile(t, "db:\n  host: db.internal\nsmtp:\n  port: 587\n"))
+
+		require.NoError(t, Init())
+		assert.Equal(t, "db.internal", DBHost)
+		assert.Equal(t, 2525, Current().SMTP.Port, "the environment overrides the file")
+	})
+
+	t.Run("invalid config file", func(t *testing.T) {
+		saveGlobals(t)
+		setRequiredEnv(t)
+		t.Setenv(ConfigFileEnv, writeConfigFile(t, "db: [unclosed\n"))
+
+		err := Init()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid config file")
+	})
+
 	t.Run("missing APP_VERSION", func(t *testing.T) {
 		saveGlobals(t)
 		setRequiredEnv(t)
@@ -307,8 +1293,8 @@ func TestInit(t *testing.T) {
 		err := Init()
 		require.NoError(t, err)
 
-		assert.Equal(t, "noreply@maintainerd.com", SMTPFromEmail)
-		assert.Equal(t, "Maintainerd", SMTPFromName)
+		assert.Equal(t, "noreply@maintainerd.com", Current().SMTP.FromEmail)
+		assert.Equal(t, "Maintainerd", Current().SMTP.FromName)
 		assert.NotEmpty(t, EmailLogo)
 	})
 }
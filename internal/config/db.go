@@ -1,9 +1,15 @@
 package config
 
 import (
+	"database/sql"
 	"fmt"
 	"log/slog"
+	"net"
+	"time"
 
+	"github.com/maintainerd/auth/internal/database/replica"
+	"github.com/maintainerd/auth/internal/logging"
+	"github.com/maintainerd/auth/internal/metrics"
 	"github.com/uptrace/opentelemetry-go-extra/otelgorm"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -13,7 +19,12 @@ import (
 // with any error. It no longer calls os.Exit so that main() can decide how to
 // handle initialization failures.
 func InitDB() (*gorm.DB, error) {
-	db, err := gorm.Open(postgres.Open(GetDBConnectionString()), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(GetDBConnectionString()), &gorm.Config{
+		Logger: logging.NewGormLogger(DBSlowQueryThreshold),
+		// PostgreSQL keeps microseconds, so the timestamps GORM sets match
+		// what is read back; ETags are derived from updated_at
+		NowFunc: func() time.Time { return time.Now().Local().Truncate(time.Microsecond) },
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -22,13 +33,109 @@ func InitDB() (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to register otelgorm plugin: %w", err)
 	}
 
-	slog.Info("Database connected")
+	if err := db.Use(metrics.NewGormPlugin()); err != nil {
+		return nil, fmt.Errorf("failed to register metrics plugin: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database handle: %w", err)
+	}
+	ConfigurePool(sqlDB)
+	if err := metrics.RegisterDBStats(sqlDB, DBName); err != nil {
+		return nil, fmt.Errorf("failed to register pool metrics: %w", err)
+	}
+
+	if len(DBReadReplicaHosts) > 0 {
+		replicas, err := openReplicas()
+		if err != nil {
+			return nil, err
+		}
+		if err := db.Use(replica.NewPlugin(replicas, DBReadReplicaLagWindow)); err != nil {
+			return nil, fmt.Errorf("failed to register replica plugin: %w", err)
+		}
+	}
+
+	slog.Info("Database connected",
+		"max_open_conns", DBMaxOpenConns,
+		"max_idle_conns", DBMaxIdleConns,
+		"conn_max_lifetime", DBConnMaxLifetime.String(),
+		"conn_max_idle_time", DBConnMaxIdleTime.String(),
+		"read_replicas", len(DBReadReplicaHosts),
+	)
 	return db, nil
 }
 
+// ConfigurePool applies the DB pool settings to sqlDB.
+func ConfigurePool(sqlDB *sql.DB) {
+	sqlDB.SetMaxOpenConns(DBMaxOpenConns)
+	sqlDB.SetMaxIdleConns(DBMaxIdleConns)
+	sqlDB.SetConnMaxLifetime(DBConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(DBConnMaxIdleTime)
+}
+
+// replicaPools are the read replica pools opened by InitDB, closed by
+// CloseDB.
+var replicaPools []*sql.DB
+
+// openReplicas opens a pool per DBReadReplicaHosts entry with the primary's
+// pool settings and exports its metrics labelled "<db>_replica_<n>".
+func openReplicas() ([]gorm.ConnPool, error) {
+	pools := make([]gorm.ConnPool, 0, len(DBReadReplicaHosts))
+	for i, entry := range DBReadReplicaHosts {
+		host, port := entry, DBPort
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			host, port = h, p
+		}
+		replicaDB, err := gorm.Open(postgres.Open(connectionString(host, port)), &gorm.Config{
+			Logger: logging.NewGormLogger(DBSlowQueryThreshold),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to read replica %s: %w", entry, err)
+		}
+		sqlDB, err := replicaDB.DB()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get read replica handle: %w", err)
+		}
+		ConfigurePool(sqlDB)
+		if err := metrics.RegisterDBStats(sqlDB, fmt.Sprintf("%s_replica_%d", DBName, i+1)); err != nil {
+			return nil, fmt.Errorf("failed to register read replica pool metrics: %w", err)
+		}
+		replicaPools = append(replicaPools, sqlDB)
+		pools = append(pools, sqlDB)
+	}
+	return pools, nil
+}
+
+// CloseDB closes the connection pool underlying db and those of the read
+// replicas. It is called during graceful shutdown once every server has
+// stopped using the database.
+func CloseDB(db *gorm.DB) error {
+	for _, pool := range replicaPools {
+		_ = pool.Close()
+	}
+	replicaPools = nil
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database handle: %w", err)
+	}
+	if err := sqlDB.Close(); err != nil {
+		return fmt.Errorf("failed to close database: %w", err)
+	}
+	slog.Info("Database connection closed")
+	return nil
+}
+
 func GetDBConnectionString() string {
+	return connectionString(DBHost, DBPort)
+}
+
+// connectionString builds the DSN of the server at host and port with the
+// configured credentials, database name and SSL mode.
+func connectionString(host, port string) string {
 	return fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		DBHost, DBPort, DBUser, DBPassword, DBName, DBSSLMode,
+		host, port, DBUser, DBPassword, DBName, DBSSLMode,
 	)
 }
@@ -1,9 +1,16 @@
 package config
 
 import (
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 func TestGetDBConnectionString(t *testing.T) {
@@ -31,4 +38,54 @@ func TestGetDBConnectionString(t *testing.T) {
 
 	got := GetDBConnectionString()
 	assert.Equal(t, "host=db.example.com port=5432 user=admin password=s3cret dbname=mydb sslmode=require", got)
+	assert.Equal(t, "host=replica.example.com port=6432 user=admin password=s3cret dbname=mydb sslmode=require", connectionString("replica.example.com", "6432"))
+}
+
+func TestConfigurePool(t *testing.T) {
+	origOpen, origIdle := DBMaxOpenConns, DBMaxIdleConns
+	origLifetime, origIdleTime := DBConnMaxLifetime, DBConnMaxIdleTime
+	t.Cleanup(func() {
+		DBMaxOpenConns, DBMaxIdleConns = origOpen, origIdle
+		DBConnMaxLifetime, DBConnMaxIdleTime = origLifetime, origIdleTime
+	})
+
+	sqlDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	DBMaxOpenConns = 7
+	DBMaxIdleConns = 3
+	DBConnMaxLifetime = time.Hour
+	DBConnMaxIdleTime = time.Minute
+	ConfigurePool(sqlDB)
+
+	assert.Equal(t, 7, sqlDB.Stats().MaxOpenConnections)
+}
+
+func TestCloseDB(t *testing.T) {
+	open := func(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+		t.Helper()
+		sqlDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+			Logger: logger.Default.LogMode(logger.Silent),
+		})
+		require.NoError(t, err)
+		return db, mock
+	}
+
+	t.Run("closes the pool", func(t *testing.T) {
+		db, mock := open(t)
+		mock.ExpectClose()
+		require.NoError(t, CloseDB(db))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("returns close error", func(t *testing.T) {
+		db, mock := open(t)
+		mock.ExpectClose().WillReturnError(errors.New("boom"))
+		err := CloseDB(db)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to close database")
+	})
 }
@@ -2,24 +2,97 @@ package config
 
 import (
 	"fmt"
-	"os"
+	"strconv"
+	"time"
 )
 
-// GetEnv returns the value of the environment variable identified by key.
-// It returns an error if the variable is not set or empty, allowing callers
-// to decide how to handle the failure instead of calling os.Exit.
+// GetEnv returns the value of the setting identified by key, taken from the
+// environment variable or, when that is unset, the config file. It returns
+// an error if the setting is not set or empty, allowing callers to decide how
+// to handle the failure instead of calling os.Exit. The other helpers read
+// settings the same way.
 func GetEnv(key string) (string, error) {
-	val := os.Getenv(key)
+	val := lookupSetting(key)
 	if val == "" {
-		return "", fmt.Errorf("required environment variable %q is not set", key)
+		return "", fmt.Errorf("required setting %q is not set in the environment or the config file", key)
 	}
 	return val, nil
 }
 
 func GetEnvOrDefault(key, defaultVal string) string {
-	val := os.Getenv(key)
+	val := lookupSetting(key)
 	if val == "" {
 		return defaultVal
 	}
 	return val
 }
+
+// GetEnvDurationOrDefault parses the environment variable identified by key as
+// a Go duration (e.g. "30s", "1m"). It returns defaultVal when the variable is
+// unset and an error when the value is malformed or not positive.
+func GetEnvDurationOrDefault(key string, defaultVal time.Duration) (time.Duration, error) {
+	val := lookupSetting(key)
+	if val == "" {
+		return defaultVal, nil
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", key, val, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be positive", key, val)
+	}
+	return d, nil
+}
+
+// GetEnvBoolOrDefault parses the environment variable identified by key as a
+// boolean ("true", "false", "1", "0", ...). It returns defaultVal when the
+// variable is unset and an error when the value is malformed.
+func GetEnvBoolOrDefault(key string, defaultVal bool) (bool, error) {
+	val := lookupSetting(key)
+	if val == "" {
+		return defaultVal, nil
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q: must be a boolean", key, val)
+	}
+	return b, nil
+}
+
+// GetEnvRatioOrDefault parses the environment variable identified by key as a
+// fraction between 0 and 1 inclusive (e.g. "0.25"). It returns defaultVal when
+// the variable is unset and an error when the value is malformed or out of
+// range.
+func GetEnvRatioOrDefault(key string, defaultVal float64) (float64, error) {
+	val := lookupSetting(key)
+	if val == "" {
+		return defaultVal, nil
+	}
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", key, val, err)
+	}
+	if f < 0 || f > 1 {
+		return 0, fmt.Errorf("invalid %s %q: must be between 0 and 1", key, val)
+	}
+	return f, nil
+}
+
+// GetEnvIntOrDefault parses the environment variable identified by key as a
+// non-negative integer. It returns defaultVal when the variable is unset and
+// an error when the value is malformed or negative.
+func GetEnvIntOrDefault(key string, defaultVal int) (int, error) {
+	val := lookupSetting(key)
+	if val == "" {
+		return defaultVal, nil
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", key, val, err)
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must not be negative", key, val)
+	}
+	return n, nil
+}
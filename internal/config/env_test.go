@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -45,3 +46,112 @@ func TestGetEnvOrDefault(t *testing.T) {
 		assert.Equal(t, "fallback", GetEnvOrDefault("TEST_ENV_OR_DEFAULT_EMPTY", "fallback"))
 	})
 }
+
+func TestGetEnvDurationOrDefault(t *testing.T) {
+	t.Run("returns parsed value when set", func(t *testing.T) {
+		t.Setenv("TEST_ENV_DURATION", "45s")
+		d, err := GetEnvDurationOrDefault("TEST_ENV_DURATION", time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, 45*time.Second, d)
+	})
+
+	t.Run("returns default when not set", func(t *testing.T) {
+		d, err := GetEnvDurationOrDefault("TEST_ENV_DURATION_MISSING", 5*time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Second, d)
+	})
+
+	t.Run("returns error when malformed", func(t *testing.T) {
+		t.Setenv("TEST_ENV_DURATION_BAD", "soon")
+		_, err := GetEnvDurationOrDefault("TEST_ENV_DURATION_BAD", time.Second)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TEST_ENV_DURATION_BAD")
+	})
+
+	t.Run("returns error when not positive", func(t *testing.T) {
+		t.Setenv("TEST_ENV_DURATION_ZERO", "0s")
+		_, err := GetEnvDurationOrDefault("TEST_ENV_DURATION_ZERO", time.Second)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must be positive")
+	})
+}
+
+func TestGetEnvBoolOrDefault(t *testing.T) {
+	t.Run("returns parsed value when set", func(t *testing.T) {
+		t.Setenv("TEST_ENV_BOOL", "true")
+		b, err := GetEnvBoolOrDefault("TEST_ENV_BOOL", false)
+		require.NoError(t, err)
+		assert.True(t, b)
+	})
+
+	t.Run("returns default when not set", func(t *testing.T) {
+		b, err := GetEnvBoolOrDefault("TEST_ENV_BOOL_MISSING", true)
+		require.NoError(t, err)
+		assert.True(t, b)
+	})
+
+	t.Run("returns error when malformed", func(t *testing.T) {
+		t.Setenv("TEST_ENV_BOOL_BAD", "sometimes")
+		_, err := GetEnvBoolOrDefault("TEST_ENV_BOOL_BAD", false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TEST_ENV_BOOL_BAD")
+	})
+}
+
+func TestGetEnvRatioOrDefault(t *testing.T) {
+	t.Run("returns parsed value when set", func(t *testing.T) {
+		t.Setenv("TEST_ENV_RATIO", "0.25")
+		f, err := GetEnvRatioOrDefault("TEST_ENV_RATIO", 1)
+		require.NoError(t, err)
+		assert.Equal(t, 0.25, f)
+	})
+
+	t.Run("returns default when not set", func(t *testing.T) {
+		f, err := GetEnvRatioOrDefault("TEST_ENV_RATIO_MISSING", 0.5)
+		require.NoError(t, err)
+		assert.Equal(t, 0.5, f)
+	})
+
+	t.Run("returns error when malformed", func(t *testing.T) {
+		t.Setenv("TEST_ENV_RATIO_BAD", "half")
+		_, err := GetEnvRatioOrDefault("TEST_ENV_RATIO_BAD", 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TEST_ENV_RATIO_BAD")
+	})
+
+	t.Run("returns error when out of range", func(t *testing.T) {
+		t.Setenv("TEST_ENV_RATIO_RANGE", "1.5")
+		_, err := GetEnvRatioOrDefault("TEST_ENV_RATIO_RANGE", 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "between 0 and 1")
+	})
+}
+
+func TestGetEnvIntOrDefault(t *testing.T) {
+	t.Run("returns parsed value when set", func(t *testing.T) {
+		t.Setenv("TEST_ENV_INT", "42")
+		n, err := GetEnvIntOrDefault("TEST_ENV_INT", 1)
+		require.NoError(t, err)
+		assert.Equal(t, 42, n)
+	})
+
+	t.Run("returns default when not set", func(t *testing.T) {
+		n, err := GetEnvIntOrDefault("TEST_ENV_INT_MISSING", 7)
+		require.NoError(t, err)
+		assert.Equal(t, 7, n)
+	})
+
+	t.Run("returns error when malformed", func(t *testing.T) {
+		t.Setenv("TEST_ENV_INT_BAD", "many")
+		_, err := GetEnvIntOrDefault("TEST_ENV_INT_BAD", 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TEST_ENV_INT_BAD")
+	})
+
+	t.Run("returns error when negative", func(t *testing.T) {
+		t.Setenv("TEST_ENV_INT_NEG", "-1")
+		_, err := GetEnvIntOrDefault("TEST_ENV_INT_NEG", 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must not be negative")
+	})
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileEnv names the environment variable holding the path of the
+// optional YAML config file.
+const ConfigFileEnv = "CONFIG_FILE"
+
+var (
+	fileMu sync.RWMutex
+	// fileSettings holds the settings of the config file by name; nil when
+	// no file is configured.
+	fileSettings map[string]string
+	// requestedSettings records the names looked up since the file was
+	// loaded, to report file settings nothing reads.
+	requestedSettings map[string]bool
+)
+
+// lookupSetting returns the value of the setting name: the environment
+// variable when it is set, the config file value otherwise, or "".
+func lookupSetting(name string) string {
+	if val := os.Getenv(name); val != "" {
+		return val
+	}
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	if requestedSettings != nil {
+		requestedSettings[name] = true
+	}
+	return fileSettings[name]
+}
+
+// loadConfigFile reads the file named by CONFIG_FILE, when set, and makes
+// its settings available to lookupSetting. It returns the settings that were
+// in effect before, so a failed reload can restore them.
+func loadConfigFile() (map[string]string, error) {
+	var settings map[string]string
+	if path := os.Getenv(ConfigFileEnv); path != "" {
+		var err error
+		if settings, err = readConfigFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	prev := fileSettings
+	fileSettings = settings
+	requestedSettings = map[string]bool{}
+	return prev, nil
+}
+
+// restoreConfigFile puts back settings returned by loadConfigFile.
+func restoreConfigFile(settings map[string]string) {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	fileSettings = settings
+}
+
+// warnUnusedFileSettings logs the config file settings no lookup asked for,
+// which are usually misspelled or belong to a disabled feature.
+func warnUnusedFileSettings() {
+	fileMu.RLock()
+	defer fileMu.RUnlock()
+	var unused []string
+	for name := range fileSettings {
+		if !requestedSettings[name] {
+			unused = append(unused, name)
+		}
+	}
+	if len(unused) > 0 {
+		slices.Sort(unused)
+		slog.Warn("Config file settings are not used", "settings", unused)
+	}
+}
+
+// readConfigFile parses a YAML config file into settings named like the
+// environment variables: nested keys are joined with underscores and upper
+// cased, so
+//
+//	db:
+//	  host: localhost
+//
+// sets DB_HOST. Lists become comma-separated values.
+func readConfigFile(path string) (map[string]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	settings := map[string]string{}
+	if err := flattenSettings("", doc, settings); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return settings, nil
+}
+
+func flattenSettings(prefix string, node map[string]any, settings map[string]string) error {
+	for key, value := range node {
+		name := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		if child, ok := value.(map[string]any); ok {
+			if err := flattenSettings(name, child, settings); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var s string
+		if items, ok := value.([]any); ok {
+			parts := make([]string, len(items))
+			for i, item := range items {
+				part, err := settingValue(item)
+				if err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+				parts[i] = part
+			}
+			s = strings.Join(parts, ",")
+		} else {
+			var err error
+			if s, err = settingValue(value); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+
+		if _, dup := settings[name]; dup {
+			return fmt.Errorf("%s is set more than once", name)
+		}
+		settings[name] = s
+	}
+	return nil
+}
+
+// settingValue formats a scalar YAML value the way it would be written in
+// an environment variable.
+func settingValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool, int, int64, uint64, float64:
+		return fmt.Sprint(v), nil
+	case time.Time:
+		return v.Format(time.RFC3339), nil
+	}
+	return "", fmt.Errorf("unsupported value %v: settings must be scalars or lists of scalars", value)
+}
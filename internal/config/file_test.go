@@ -0,0 +1,138 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFile writes a YAML config file to a temporary directory and
+// returns its path.
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// useConfigFile points CONFIG_FILE at a file with content and restores the
+// loaded settings after the test.
+func useConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	origFile, origRequested, origReloadable := fileSettings, requestedSettings, current.Load()
+	t.Cleanup(func() {
+		fileSettings, requestedSettings = origFile, origRequested
+		current.Store(origReloadable)
+	})
+	path := writeConfigFile(t, content)
+	t.Setenv(ConfigFileEnv, path)
+	return path
+}
+
+func TestReadConfigFile(t *testing.T) {
+	t.Run("flattens nested keys", func(t *testing.T) {
+		settings, err := readConfigFile(writeConfigFile(t, `
+app:
+  version: 1.2.0
+db:
+  host: localhost
+  port: 5432
+rate-limit:
+  enabled: false
+event_stream:
+  brokers: [kafka-1:9092, kafka-2:9092]
+breached_password:
+  threshold: 0.5
+smtp_from_name:
+`))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"APP_VERSION":                 "1.2.0",
+			"DB_HOST":                     "localhost",
+			"DB_PORT":                     "5432",
+			"RATE_LIMIT_ENABLED":          "false",
+			"EVENT_STREAM_BROKERS":        "kafka-1:9092,kafka-2:9092",
+			"BREACHED_PASSWORD_THRESHOLD": "0.5",
+			"SMTP_FROM_NAME":              "",
+		}, settings)
+	})
+
+	t.Run("setting repeated through nesting", func(t *testing.T) {
+		_, err := readConfigFile(writeConfigFile(t, "db_host: a\ndb:\n  host: b\n"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "DB_HOST is set more than once")
+	})
+
+	t.Run("nested list", func(t *testing.T) {
+		_, err := readConfigFile(writeConfigFile(t, "event_stream:\n  brokers:\n    - host: a\n"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "EVENT_STREAM_BROKERS")
+	})
+
+	t.Run("invalid YAML", func(t *testing.T) {
+		_, err := readConfigFile(writeConfigFile(t, "db: [unclosed\n"))
+		assert.ErrorContains(t, err, "invalid config file")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := readConfigFile(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.ErrorContains(t, err, "failed to read config file")
+	})
+}
+
+func TestLookupSetting(t *testing.T) {
+	useConfigFile(t, "test_lookup:\n  file: from-file\n  both: from-file\n")
+	_, err := loadConfigFile()
+	require.NoError(t, err)
+	t.Setenv("TEST_LOOKUP_BOTH", "from-env")
+
+	assert.Equal(t, "from-file", lookupSetting("TEST_LOOKUP_FILE"))
+	assert.Equal(t, "from-env", lookupSetting("TEST_LOOKUP_BOTH"))
+	assert.Empty(t, lookupSetting("TEST_LOOKUP_MISSING"))
+	assert.True(t, requestedSettings["TEST_LOOKUP_FILE"])
+}
+
+func TestReload(t *testing.T) {
+	const base = "smtp:\n  host: smtp.example.com\n  port: 587\n  user: user\n  pass: pass\n"
+
+	t.Run("reports changed settings", func(t *testing.T) {
+		path := useConfigFile(t, base)
+		_, err := Reload()
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(path, []byte(base+"  from_name: Acme\nself_service_tenant_limit: 10\n"), 0o600))
+		changed, err := Reload()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"SELF_SERVICE_TENANT_LIMIT", "SMTP_FROM_NAME"}, changed)
+		assert.Equal(t, "Acme", Current().SMTP.FromName)
+		assert.Equal(t, 10, Current().SelfServiceTenantLimit)
+
+		changed, err = Reload()
+		require.NoError(t, err)
+		assert.Empty(t, changed)
+	})
+
+	t.Run("invalid settings change nothing", func(t *testing.T) {
+		path := useConfigFile(t, base)
+		_, err := Reload()
+		require.NoError(t, err)
+		before := Current()
+
+		require.NoError(t, os.WriteFile(path, []byte(base+"rate_limit:\n  policies: nonsense\n"), 0o600))
+		_, err = Reload()
+		require.Error(t, err)
+		assert.Same(t, before, Current())
+		assert.Equal(t, "smtp.example.com", lookupSetting("SMTP_HOST"))
+		assert.Empty(t, lookupSetting("RATE_LIMIT_POLICIES"))
+	})
+
+	t.Run("unreadable file", func(t *testing.T) {
+		path := useConfigFile(t, base)
+		require.NoError(t, os.Remove(path))
+		_, err := Reload()
+		assert.ErrorContains(t, err, "failed to read config file")
+	})
+}
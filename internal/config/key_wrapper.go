@@ -0,0 +1,157 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/maintainerd/auth/internal/crypto"
+)
+
+// NewColumnKeyWrapper returns the KeyWrapper column encryption data keys are
+// wrapped with, selected by COLUMN_ENCRYPTION_KEY_WRAPPER:
+//
+//	local         – COLUMN_ENCRYPTION_KEK, loaded via the secret provider
+//	aws_kms       – the AWS KMS key COLUMN_ENCRYPTION_KMS_KEY_ID
+//	vault_transit – the Vault transit key COLUMN_ENCRYPTION_VAULT_TRANSIT_KEY
+//
+// Call it after Init, and only when ColumnEncryptionEnabled is set.
+func NewColumnKeyWrapper() (crypto.KeyWrapper, error) {
+	switch ColumnEncryptionKeyWrapper {
+	case "local":
+		slog.Info("Column encryption key wrapper: local key")
+		var previous [][]byte
+		if ColumnEncryptionPreviousKEK != nil {
+			previous = append(previous, ColumnEncryptionPreviousKEK)
+		}
+		return crypto.NewLocalKeyWrapper(ColumnEncryptionKEK, previous...)
+
+	case "aws_kms":
+		region := GetEnvOrDefault("AWS_REGION", "us-east-1")
+		slog.Info("Column encryption key wrapper: AWS KMS", "region", region, "key_id", ColumnEncryptionKMSKeyID)
+		return newAWSKMSKeyWrapper(region, ColumnEncryptionKMSKeyID)
+
+	case "vault_transit":
+		address := GetEnvOrDefault("VAULT_ADDR", "http://localhost:8200")
+		slog.Info("Column encryption key wrapper: Vault transit", "address", address,
+			"mount", ColumnEncryptionVaultTransitMount, "key", ColumnEncryptionVaultTransitKey)
+		return newVaultTransitKeyWrapper(address, lookupSetting("VAULT_TOKEN"), ColumnEncryptionVaultTransitMount, ColumnEncryptionVaultTransitKey)
+
+	default:
+		return nil, fmt.Errorf("unknown COLUMN_ENCRYPTION_KEY_WRAPPER %q", ColumnEncryptionKeyWrapper)
+	}
+}
+
+// ─────────────────────────────────── AWS KMS key wrapper ───────────────────
+//
+// Data keys are encrypted with the KMS key; the wrapped key is the base64
+// ciphertext blob, which names the key that decrypts it.
+
+// awsKMSClient abstracts the AWS KMS API for testability.
+type awsKMSClient interface {
+	Encrypt(ctx context.Context, params *kms.EncryptInput, optFns ...func(*kms.Options)) (*kms.EncryptOutput, error)
+	Decrypt(ctx context.Context, params *kms.DecryptInput, optFns ...func(*kms.Options)) (*kms.DecryptOutput, error)
+}
+
+type awsKMSKeyWrapper struct {
+	keyID  string
+	client awsKMSClient
+}
+
+func newAWSKMSKeyWrapper(region, keyID string) (*awsKMSKeyWrapper, error) {
+	cfg, err := awsLoadDefaultConfig(context.Background(), awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("AWS KMS: failed to load AWS config: %w", err)
+	}
+	return &awsKMSKeyWrapper{keyID: keyID, client: kms.NewFromConfig(cfg)}, nil
+}
+
+func (a *awsKMSKeyWrapper) WrapKey(ctx context.Context, key []byte) (string, error) {
+	out, err := a.client.Encrypt(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(a.keyID),
+		Plaintext: key,
+	})
+	if err != nil {
+		return "", fmt.Errorf("AWS KMS: failed to encrypt with %q: %w", a.keyID, err)
+	}
+	return base64.StdEncoding.EncodeToString(out.CiphertextBlob), nil
+}
+
+func (a *awsKMSKeyWrapper) UnwrapKey(ctx context.Context, wrapped string) ([]byte, error) {
+	blob, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("AWS KMS: invalid wrapped key: %w", err)
+	}
+	// The key is not pinned, so keys wrapped before the KMS key changed
+	// still unwrap until they are rewrapped.
+	out, err := a.client.Decrypt(ctx, &kms.DecryptInput{CiphertextBlob: blob})
+	if err != nil {
+		return nil, fmt.Errorf("AWS KMS: failed to decrypt: %w", err)
+	}
+	return out.Plaintext, nil
+}
+
+// ─────────────────────────────────── Vault transit key wrapper ─────────────
+//
+// Data keys are encrypted with a transit key; the wrapped key is the
+// "vault:v<N>:..." ciphertext. Rotating the transit key keeps older versions
+// decrypting, and Rewrap re-encrypts with the latest version.
+
+// vaultLogicalWriter abstracts the Vault logical write API for testability.
+type vaultLogicalWriter interface {
+	WriteWithContext(ctx context.Context, path string, data map[string]any) (*vaultapi.Secret, error)
+}
+
+type vaultTransitKeyWrapper struct {
+	mount   string
+	key     string
+	logical vaultLogicalWriter
+}
+
+func newVaultTransitKeyWrapper(address, token, mount, key string) (*vaultTransitKeyWrapper, error) {
+	client, err := newVaultClient(address, token)
+	if err != nil {
+		return nil, err
+	}
+	return &vaultTransitKeyWrapper{mount: mount, key: key, logical: client.Logical()}, nil
+}
+
+// write calls a transit endpoint and returns a string field of its response.
+func (v *vaultTransitKeyWrapper) write(ctx context.Context, op string, data map[string]any, field string) (string, error) {
+	path := fmt.Sprintf("%s/%s/%s", v.mount, op, v.key)
+	secret, err := v.logical.WriteWithContext(ctx, path, data)
+	if err != nil {
+		return "", fmt.Errorf("Vault transit: failed to write %q: %w", path, err)
+	}
+	if secret == nil || secret.Data == nil {
+		return "", fmt.Errorf("Vault transit: %q returned no data", path)
+	}
+	value, ok := secret.Data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("Vault transit: %q returned no %s", path, field)
+	}
+	return value, nil
+}
+
+func (v *vaultTransitKeyWrapper) WrapKey(ctx context.Context, key []byte) (string, error) {
+	return v.write(ctx, "encrypt", map[string]any{
+		"plaintext": base64.StdEncoding.EncodeToString(key),
+	}, "ciphertext")
+}
+
+func (v *vaultTransitKeyWrapper) UnwrapKey(ctx context.Context, wrapped string) ([]byte, error) {
+	encoded, err := v.write(ctx, "decrypt", map[string]any{"ciphertext": wrapped}, "plaintext")
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("Vault transit: invalid plaintext: %w", err)
+	}
+	return key, nil
+}
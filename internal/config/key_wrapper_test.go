@@ -0,0 +1,212 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maintainerd/auth/internal/crypto"
+)
+
+// saveColumnEncryptionGlobals restores the column encryption settings when
+// the test ends.
+func saveColumnEncryptionGlobals(t *testing.T) {
+	t.Helper()
+	wrapper, kek, previous := ColumnEncryptionKeyWrapper, ColumnEncryptionKEK, ColumnEncryptionPreviousKEK
+	kmsKeyID, mount, key := ColumnEncryptionKMSKeyID, ColumnEncryptionVaultTransitMount, ColumnEncryptionVaultTransitKey
+	t.Cleanup(func() {
+		ColumnEncryptionKeyWrapper, ColumnEncryptionKEK, ColumnEncryptionPreviousKEK = wrapper, kek, previous
+		ColumnEncryptionKMSKeyID, ColumnEncryptionVaultTransitMount, ColumnEncryptionVaultTransitKey = kmsKeyID, mount, key
+	})
+}
+
+func TestNewColumnKeyWrapper(t *testing.T) {
+	ctx := context.Background()
+	dataKey := bytes.Repeat([]byte{9}, crypto.DataKeySize)
+
+	t.Run("local with previous KEK", func(t *testing.T) {
+		saveColumnEncryptionGlobals(t)
+		ColumnEncryptionKeyWrapper = "local"
+		ColumnEncryptionKEK = bytes.Repeat([]byte{1}, crypto.FieldKeySize)
+		old, err := NewColumnKeyWrapper()
+		require.NoError(t, err)
+		wrapped, err := old.WrapKey(ctx, dataKey)
+		require.NoError(t, err)
+
+		ColumnEncryptionPreviousKEK = ColumnEncryptionKEK
+		ColumnEncryptionKEK = bytes.Repeat([]byte{2}, crypto.FieldKeySize)
+		w, err := NewColumnKeyWrapper()
+		require.NoError(t, err)
+		got, err := w.UnwrapKey(ctx, wrapped)
+		require.NoError(t, err)
+		assert.Equal(t, dataKey, got)
+	})
+
+	t.Run("aws_kms config load error", func(t *testing.T) {
+		saveColumnEncryptionGlobals(t)
+		orig := awsLoadDefaultConfig
+		t.Cleanup(func() { awsLoadDefaultConfig = orig })
+		awsLoadDefaultConfig = func(_ context.Context, _ ...func(*awsconfig.LoadOptions) error) (aws.Config, error) {
+			return aws.Config{}, fmt.Errorf("mock config error")
+		}
+		ColumnEncryptionKeyWrapper = "aws_kms"
+
+		_, err := NewColumnKeyWrapper()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load AWS config")
+	})
+
+	t.Run("vault_transit", func(t *testing.T) {
+		saveColumnEncryptionGlobals(t)
+		srv := newTransitServer(t)
+		t.Setenv("VAULT_ADDR", srv.URL)
+		t.Setenv("VAULT_TOKEN", "token")
+		ColumnEncryptionKeyWrapper = "vault_transit"
+		ColumnEncryptionVaultTransitMount, ColumnEncryptionVaultTransitKey = "transit", "auth"
+
+		w, err := NewColumnKeyWrapper()
+		require.NoError(t, err)
+		wrapped, err := w.WrapKey(ctx, dataKey)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(wrapped, "vault:v1:"))
+		got, err := w.UnwrapKey(ctx, wrapped)
+		require.NoError(t, err)
+		assert.Equal(t, dataKey, got)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		saveColumnEncryptionGlobals(t)
+		ColumnEncryptionKeyWrapper = "rot13"
+		_, err := NewColumnKeyWrapper()
+		assert.Error(t, err)
+	})
+}
+
+// ─── AWS KMS ─────────────────────────────────────────────────────────────
+
+type mockKMSClient struct {
+	encryptFn func(*kms.EncryptInput) (*kms.EncryptOutput, error)
+	decryptFn func(*kms.DecryptInput) (*kms.DecryptOutput, error)
+}
+
+func (m *mockKMSClient) Encrypt(_ context.Context, in *kms.EncryptInput, _ ...func(*kms.Options)) (*kms.EncryptOutput, error) {
+	return m.encryptFn(in)
+}
+
+func (m *mockKMSClient) Decrypt(_ context.Context, in *kms.DecryptInput, _ ...func(*kms.Options)) (*kms.DecryptOutput, error) {
+	return m.decryptFn(in)
+}
+
+func TestAWSKMSKeyWrapper(t *testing.T) {
+	ctx := context.Background()
+	dataKey := bytes.Repeat([]byte{9}, crypto.DataKeySize)
+
+	// The fake KMS "encrypts" by prefixing the key ID
+	client := &mockKMSClient{
+		encryptFn: func(in *kms.EncryptInput) (*kms.EncryptOutput, error) {
+			return &kms.EncryptOutput{CiphertextBlob: append([]byte(*in.KeyId+":"), in.Plaintext...)}, nil
+		},
+		decryptFn: func(in *kms.DecryptInput) (*kms.DecryptOutput, error) {
+			_, plaintext, ok := bytes.Cut(in.CiphertextBlob, []byte(":"))
+			if !ok {
+				return nil, fmt.Errorf("InvalidCiphertextException")
+			}
+			return &kms.DecryptOutput{Plaintext: plaintext}, nil
+		},
+	}
+	w := &awsKMSKeyWrapper{keyID: "alias/auth", client: client}
+
+	t.Run("round trip", func(t *testing.T) {
+		wrapped, err := w.WrapKey(ctx, dataKey)
+		require.NoError(t, err)
+		got, err := w.UnwrapKey(ctx, wrapped)
+		require.NoError(t, err)
+		assert.Equal(t, dataKey, got)
+	})
+
+	t.Run("invalid base64", func(t *testing.T) {
+		_, err := w.UnwrapKey(ctx, "!!!")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid wrapped key")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		failing := &awsKMSKeyWrapper{keyID: "alias/auth", client: &mockKMSClient{
+			encryptFn: func(*kms.EncryptInput) (*kms.EncryptOutput, error) { return nil, fmt.Errorf("AccessDenied") },
+			decryptFn: func(*kms.DecryptInput) (*kms.DecryptOutput, error) { return nil, fmt.Errorf("AccessDenied") },
+		}}
+		_, err := failing.WrapKey(ctx, dataKey)
+		assert.ErrorContains(t, err, "AWS KMS: failed to encrypt")
+		_, err = failing.UnwrapKey(ctx, "AAAA")
+		assert.ErrorContains(t, err, "AWS KMS: failed to decrypt")
+	})
+}
+
+// ─── Vault transit ───────────────────────────────────────────────────────
+
+// newTransitServer fakes the Vault transit encrypt and decrypt endpoints of
+// the "auth" key: the ciphertext is the base64 plaintext behind "vault:v1:".
+func newTransitServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		var data map[string]any
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/auth":
+			data = map[string]any{"ciphertext": "vault:v1:" + body["plaintext"]}
+		case "/v1/transit/decrypt/auth":
+			data = map[string]any{"plaintext": strings.TrimPrefix(body["ciphertext"], "vault:v1:")}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":["no handler for route"]}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestVaultTransitKeyWrapper(t *testing.T) {
+	ctx := context.Background()
+	srv := newTransitServer(t)
+
+	t.Run("unknown key", func(t *testing.T) {
+		w, err := newVaultTransitKeyWrapper(srv.URL, "token", "transit", "missing")
+		require.NoError(t, err)
+		_, err = w.WrapKey(ctx, []byte("key"))
+		assert.ErrorContains(t, err, `failed to write "transit/encrypt/missing"`)
+	})
+
+	t.Run("invalid plaintext", func(t *testing.T) {
+		w, err := newVaultTransitKeyWrapper(srv.URL, "token", "transit", "auth")
+		require.NoError(t, err)
+		_, err = w.UnwrapKey(ctx, "vault:v1:!!!")
+		assert.ErrorContains(t, err, "invalid plaintext")
+	})
+
+	t.Run("missing field", func(t *testing.T) {
+		w, err := newVaultTransitKeyWrapper(srv.URL, "token", "transit", "auth")
+		require.NoError(t, err)
+		_, err = w.write(ctx, "encrypt", map[string]any{}, "signature")
+		assert.ErrorContains(t, err, "returned no signature")
+	})
+
+	t.Run("approle login failure", func(t *testing.T) {
+		_, err := newVaultTransitKeyWrapper(srv.URL, "", "transit", "auth")
+		assert.ErrorContains(t, err, "AppRole login failed")
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/maintainerd/auth/internal/metrics"
 	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
@@ -36,5 +37,7 @@ func NewRedisClient() (*redis.Client, error) {
 		return nil, fmt.Errorf("failed to register redisotel tracing: %w", err)
 	}
 
+	rdb.AddHook(metrics.NewRedisHook())
+
 	return rdb, nil
 }
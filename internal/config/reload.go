@@ -0,0 +1,172 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/maintainerd/auth/internal/cache"
+)
+
+// Reloadable holds the settings that can change while the server runs. Init
+// loads them and Reload replaces them; every other setting keeps its startup
+// value until a restart.
+type Reloadable struct {
+	SMTP                   SMTPConfig
+	RateLimit              RateLimitConfig
+	SelfServiceTenantLimit int // Maximum number of tenants a user may own through self-service; 0 disables the cap
+
+	// values holds the raw value of every reloadable setting, to report
+	// what a reload changed.
+	values map[string]string
+}
+
+// SMTPConfig is the mail server emails are sent through.
+type SMTPConfig struct {
+	Host      string
+	Port      int
+	User      string
+	Pass      string
+	FromEmail string
+	FromName  string
+}
+
+// RateLimitConfig holds the REST request rate limits.
+type RateLimitConfig struct {
+	Enabled  bool                       // Rate limits REST requests with Redis-backed token buckets
+	Policies map[string]cache.RateLimit // Keyed by "<group>.<principal>"; nil when rate limiting is disabled
+}
+
+// reloadableSettings are the names of the settings in Reloadable.
+var reloadableSettings = []string{
+	"SMTP_HOST", "SMTP_PORT", "SMTP_USER", "SMTP_PASS", "SMTP_FROM_EMAIL", "SMTP_FROM_NAME",
+	"RATE_LIMIT_ENABLED", "RATE_LIMIT_POLICIES",
+	"SELF_SERVICE_TENANT_LIMIT",
+}
+
+var (
+	current  atomic.Pointer[Reloadable]
+	reloadMu sync.Mutex
+)
+
+// Current returns the reloadable settings in effect. Reload replaces the
+// value rather than modifying it, so it may be kept for the length of one
+// operation but must not be modified.
+func Current() *Reloadable {
+	if r := current.Load(); r != nil {
+		return r
+	}
+	return &Reloadable{}
+}
+
+// SetCurrent replaces the reloadable settings. Tests use it to configure
+// them.
+func SetCurrent(r *Reloadable) {
+	current.Store(r)
+}
+
+// Reload reads the config file again and replaces the reloadable settings.
+// Environment variables still take precedence over the file. It returns the
+// names of the settings that changed, sorted; on error nothing changes.
+func Reload() ([]string, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	prevFile, err := loadConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	next, err := loadReloadable()
+	if err != nil {
+		restoreConfigFile(prevFile)
+		return nil, err
+	}
+
+	prev := Current()
+	var changed []string
+	for _, name := range reloadableSettings {
+		if prev.values[name] != next.values[name] {
+			changed = append(changed, name)
+		}
+	}
+	slices.Sort(changed)
+
+	current.Store(next)
+	return changed, nil
+}
+
+// loadReloadable reads and validates the reloadable settings.
+func loadReloadable() (*Reloadable, error) {
+	r := &Reloadable{values: make(map[string]string, len(reloadableSettings))}
+	for _, name := range reloadableSettings {
+		r.values[name] = lookupSetting(name)
+	}
+
+	// Email Config
+	var err error
+	if r.SMTP.Host, err = GetEnv("SMTP_HOST"); err != nil {
+		return nil, err
+	}
+	smtpPortStr, err := GetEnv("SMTP_PORT")
+	if err != nil {
+		return nil, err
+	}
+	r.SMTP.Port, err = strconv.Atoi(smtpPortStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_PORT %q: %w", smtpPortStr, err)
+	}
+	if r.SMTP.User, err = GetEnv("SMTP_USER"); err != nil {
+		return nil, err
+	}
+	if r.SMTP.Pass, err = GetEnv("SMTP_PASS"); err != nil {
+		return nil, err
+	}
+	r.SMTP.FromEmail = GetEnvOrDefault("SMTP_FROM_EMAIL", "noreply@maintainerd.com")
+	r.SMTP.FromName = GetEnvOrDefault("SMTP_FROM_NAME", "Maintainerd")
+
+	// Self-Service Tenant Config
+	if r.SelfServiceTenantLimit, err = GetEnvIntOrDefault("SELF_SERVICE_TENANT_LIMIT", DefaultSelfServiceTenantLimit); err != nil {
+		return nil, err
+	}
+	if r.SelfServiceTenantLimit < 0 {
+		return nil, fmt.Errorf("invalid SELF_SERVICE_TENANT_LIMIT %d: must not be negative", r.SelfServiceTenantLimit)
+	}
+
+	// Request Rate Limit Config — policies are only parsed when enabled.
+	if r.RateLimit.Enabled, err = GetEnvBoolOrDefault("RATE_LIMIT_ENABLED", true); err != nil {
+		return nil, err
+	}
+	if r.RateLimit.Enabled {
+		if r.RateLimit.Policies, err = parseRateLimitPolicies(GetEnvOrDefault("RATE_LIMIT_POLICIES", DefaultRateLimitPolicies)); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// requiredSettings must be set in the environment or the config file. The
+// JWT keys are required too but are loaded through the secret provider.
+var requiredSettings = []string{
+	"APP_VERSION", "APP_PUBLIC_HOSTNAME", "APP_PRIVATE_HOSTNAME",
+	"ACCOUNT_HOSTNAME", "AUTH_HOSTNAME",
+	"DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME",
+	"SMTP_HOST", "SMTP_PORT", "SMTP_USER", "SMTP_PASS",
+}
+
+// validateRequired reports every missing required setting at once.
+func validateRequired() error {
+	var missing []string
+	for _, name := range requiredSettings {
+		if lookupSetting(name) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required settings %s: set them in the environment or the config file", strings.Join(missing, ", "))
+	}
+	return nil
+}
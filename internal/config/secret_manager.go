@@ -27,7 +27,7 @@ const secretFetchTimeout = 10 * time.Second
 type envSecretManager struct{}
 
 func (e *envSecretManager) GetSecret(key string) ([]byte, error) {
-	value := os.Getenv(key)
+	value := lookupSetting(key)
 	if value == "" {
 		return nil, fmt.Errorf("environment variable %q is not set", key)
 	}
@@ -123,7 +123,7 @@ func newSecretManager() (SecretManager, error) {
 
 	case "vault":
 		address := GetEnvOrDefault("VAULT_ADDR", "http://localhost:8200")
-		token := os.Getenv("VAULT_TOKEN")
+		token := lookupSetting("VAULT_TOKEN")
 		mount := GetEnvOrDefault("VAULT_MOUNT", "secret")
 		slog.Info("Secret provider: HashiCorp Vault", "address", address, "mount", mount, "prefix", SecretPrefix)
 		return newVaultSecretManager(address, token, SecretPrefix, mount)
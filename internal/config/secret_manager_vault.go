@@ -43,6 +43,23 @@ type vaultSecretManager struct {
 }
 
 func newVaultSecretManager(address, token, prefix, mount string) (*vaultSecretManager, error) {
+	client, err := newVaultClient(address, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if mount == "" {
+		mount = "secret"
+	}
+
+	field := GetEnvOrDefault("VAULT_SECRET_FIELD", "value")
+
+	return &vaultSecretManager{prefix: prefix, mount: mount, field: field, kv: client.KVv2(mount)}, nil
+}
+
+// newVaultClient creates a Vault client authenticated with token, or with
+// AppRole credentials when token is empty.
+func newVaultClient(address, token string) (*vaultapi.Client, error) {
 	cfg := vaultapi.DefaultConfig()
 	cfg.Address = address
 
@@ -59,14 +76,7 @@ func newVaultSecretManager(address, token, prefix, mount string) (*vaultSecretMa
 			return nil, fmt.Errorf("Vault: AppRole login failed (set VAULT_TOKEN or VAULT_ROLE_ID+VAULT_SECRET_ID): %w", err)
 		}
 	}
-
-	if mount == "" {
-		mount = "secret"
-	}
-
-	field := GetEnvOrDefault("VAULT_SECRET_FIELD", "value")
-
-	return &vaultSecretManager{prefix: prefix, mount: mount, field: field, kv: client.KVv2(mount)}, nil
+	return client, nil
 }
 
 // vaultAppRoleLogin authenticates with Vault using AppRole credentials.
@@ -3,6 +3,7 @@ package cookie
 import (
 	"net/http"
 	"reflect"
+	"time"
 )
 
 // SetAuthCookies sets authentication tokens as secure HTTP-only cookies
@@ -141,3 +142,18 @@ func ClearAuthCookies(w http.ResponseWriter) {
 	}
 	http.SetCookie(w, clearCookie)
 }
+
+// SetTrustedDeviceCookie remembers the browser as a trusted device until
+// expiresAt. Unlike the auth cookies it outlives sign out.
+func SetTrustedDeviceCookie(w http.ResponseWriter, token string, expiresAt time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "trusted_device",
+		Value:    token,
+		Path:     "/",
+		Expires:  expiresAt,
+		MaxAge:   int(time.Until(expiresAt).Seconds()),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
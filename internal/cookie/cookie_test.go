@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -125,3 +126,16 @@ func TestClearAuthCookies(t *testing.T) {
 	assert.Equal(t, "/auth/refresh", rt.Path)
 }
 
+func TestSetTrustedDeviceCookie(t *testing.T) {
+	rr := httptest.NewRecorder()
+	SetTrustedDeviceCookie(rr, "td_token", time.Now().Add(24*time.Hour))
+
+	c := findCookie(t, rr, "trusted_device")
+	require.NotNil(t, c)
+	assert.Equal(t, "td_token", c.Value)
+	assert.Equal(t, "/", c.Path)
+	assert.InDelta(t, 24*60*60, c.MaxAge, 5)
+	assert.True(t, c.HttpOnly)
+	assert.True(t, c.Secure)
+	assert.Equal(t, http.SameSiteStrictMode, c.SameSite)
+}
@@ -0,0 +1,99 @@
+package crypto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"gorm.io/gorm/schema"
+)
+
+// ColumnSerializer is the name of the GORM serializer that encrypts a column
+// with the column keyring. Tag string and *string fields with
+// `gorm:"serializer:encrypted"`.
+const ColumnSerializer = "encrypted"
+
+var columnKeyring atomic.Pointer[Keyring]
+
+func init() {
+	schema.RegisterSerializer(ColumnSerializer, columnSerializer{})
+}
+
+// SetColumnKeyring installs the keyring encrypted columns are sealed with.
+// Without one, or before it has an active key, values are written in
+// plaintext; reads always decrypt.
+func SetColumnKeyring(k *Keyring) {
+	columnKeyring.Store(k)
+}
+
+// ColumnName returns the encryption context of a column.
+func ColumnName(table, column string) string {
+	return table + "." + column
+}
+
+// EncryptColumn seals a value of the column the way the serializer does. It
+// is for updates that bypass the serializer, like GORM updates from a map.
+func EncryptColumn(table, column, value string) (string, error) {
+	k := columnKeyring.Load()
+	if value == "" || k == nil || k.ActiveKeyID() == "" {
+		return value, nil
+	}
+	return k.Encrypt(ColumnName(table, column), value)
+}
+
+// DecryptColumn opens a stored value of the column.
+func DecryptColumn(table, column, value string) (string, error) {
+	if _, ok := EnvelopeKeyID(value); !ok {
+		return value, nil
+	}
+	k := columnKeyring.Load()
+	if k == nil {
+		return "", errors.New("column is encrypted but column encryption is not configured")
+	}
+	return k.Decrypt(ColumnName(table, column), value)
+}
+
+// columnSerializer implements schema.SerializerInterface. Empty strings are
+// stored as they are, so checks for an unset value keep working.
+type columnSerializer struct{}
+
+func (columnSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue any) error {
+	var stored string
+	switch v := dbValue.(type) {
+	case nil:
+		return field.Set(ctx, dst, nil)
+	case string:
+		stored = v
+	case []byte:
+		stored = string(v)
+	default:
+		return fmt.Errorf("unsupported value %T for encrypted column %s", dbValue, field.DBName)
+	}
+
+	value, err := DecryptColumn(field.Schema.Table, field.DBName, stored)
+	if err != nil {
+		return err
+	}
+	if field.FieldType.Kind() == reflect.Ptr {
+		return field.Set(ctx, dst, &value)
+	}
+	return field.Set(ctx, dst, value)
+}
+
+func (columnSerializer) Value(_ context.Context, field *schema.Field, _ reflect.Value, fieldValue any) (any, error) {
+	var value string
+	switch v := fieldValue.(type) {
+	case string:
+		value = v
+	case *string:
+		if v == nil {
+			return nil, nil
+		}
+		value = *v
+	default:
+		return nil, fmt.Errorf("unsupported type %T for encrypted column %s", fieldValue, field.DBName)
+	}
+	return EncryptColumn(field.Schema.Table, field.DBName, value)
+}
@@ -0,0 +1,138 @@
+package crypto
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type encryptedRow struct {
+	ID       int64   `gorm:"column:id;primaryKey"`
+	Secret   string  `gorm:"column:secret;serializer:encrypted"`
+	Optional *string `gorm:"column:optional;serializer:encrypted"`
+}
+
+func (encryptedRow) TableName() string { return "encrypted_rows" }
+
+// useColumnKeyring installs k as the column keyring for the test.
+func useColumnKeyring(t *testing.T, k *Keyring) {
+	t.Helper()
+	orig := columnKeyring.Load()
+	t.Cleanup(func() { columnKeyring.Store(orig) })
+	SetColumnKeyring(k)
+}
+
+func newColumnTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return db, mock
+}
+
+// ciphertextOf matches an envelope ciphertext and records it.
+type ciphertextOf struct {
+	got *string
+}
+
+func (c ciphertextOf) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	if !ok || !strings.HasPrefix(s, envelopeCiphertextPrefix) {
+		return false
+	}
+	*c.got = s
+	return true
+}
+
+func TestColumnSerializer(t *testing.T) {
+	k := testKeyring(t, nil, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, DataKeySize)})
+	useColumnKeyring(t, k)
+	db, mock := newColumnTestDB(t)
+
+	var stored string
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "encrypted_rows"`).
+		WithArgs(ciphertextOf{got: &stored}, nil, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+	require.NoError(t, db.Create(&encryptedRow{ID: 1, Secret: "s3cret"}).Error)
+
+	mock.ExpectQuery(`SELECT`).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "secret", "optional"}).AddRow(1, stored, "legacy"))
+	var row encryptedRow
+	require.NoError(t, db.First(&row).Error)
+	assert.Equal(t, "s3cret", row.Secret)
+	require.NotNil(t, row.Optional)
+	assert.Equal(t, "legacy", *row.Optional, "plaintext rows stay readable")
+
+	mock.ExpectQuery(`SELECT`).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "secret", "optional"}).AddRow(2, "", nil))
+	row = encryptedRow{}
+	require.NoError(t, db.First(&row).Error)
+	assert.Empty(t, row.Secret)
+	assert.Nil(t, row.Optional)
+
+	var updated string
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "encrypted_rows" SET "secret"=\$1`).
+		WithArgs(ciphertextOf{got: &updated}, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, db.Model(&encryptedRow{}).Where("id = ?", 1).Updates(&encryptedRow{Secret: "rotated"}).Error)
+	assert.NotEqual(t, stored, updated)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestColumnSerializer_WithoutKeyring(t *testing.T) {
+	useColumnKeyring(t, nil)
+	db, mock := newColumnTestDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "encrypted_rows"`).
+		WithArgs("plain", "also", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+	optional := "also"
+	require.NoError(t, db.Create(&encryptedRow{ID: 1, Secret: "plain", Optional: &optional}).Error)
+
+	mock.ExpectQuery(`SELECT`).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "secret", "optional"}).AddRow(1, envelopeCiphertextPrefix+"k1:AAAA", nil))
+	var row encryptedRow
+	assert.ErrorContains(t, db.First(&row).Error, "column encryption is not configured")
+}
+
+func TestEncryptColumn(t *testing.T) {
+	t.Run("plaintext without an active key", func(t *testing.T) {
+		useColumnKeyring(t, NewKeyring(nil))
+		got, err := EncryptColumn("webhook_endpoints", "secret_encrypted", "value")
+		require.NoError(t, err)
+		assert.Equal(t, "value", got)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		useColumnKeyring(t, testKeyring(t, nil, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, DataKeySize)}))
+		got, err := EncryptColumn("webhook_endpoints", "secret_encrypted", "value")
+		require.NoError(t, err)
+		assert.NotEqual(t, "value", got)
+
+		pt, err := DecryptColumn("webhook_endpoints", "secret_encrypted", got)
+		require.NoError(t, err)
+		assert.Equal(t, "value", pt)
+
+		empty, err := EncryptColumn("webhook_endpoints", "secret_encrypted", "")
+		require.NoError(t, err)
+		assert.Empty(t, empty)
+	})
+}
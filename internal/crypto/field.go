@@ -0,0 +1,104 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FieldKeySize is the required length in bytes of the master key passed to
+// NewFieldEncryptor.
+const FieldKeySize = 32
+
+// fieldCiphertextPrefix marks values produced by FieldEncryptor.Encrypt and
+// identifies the format version so the scheme can evolve without a rewrite.
+const fieldCiphertextPrefix = "v1:"
+
+// FieldEncryptor provides application-layer encryption for individual
+// database columns. Values are sealed with AES-256-GCM using the column name
+// as additional data, so a ciphertext cannot be moved to a different column.
+// Deterministic blind indexes (HMAC-SHA256) allow exact-match lookups on
+// encrypted columns without revealing the plaintext.
+//
+// Encryption and indexing use separate keys derived from the master key with
+// HKDF, so an index leak does not weaken the ciphertexts.
+type FieldEncryptor struct {
+	aead     cipher.AEAD
+	indexKey []byte
+}
+
+// NewFieldEncryptor creates a FieldEncryptor from a 32-byte master key.
+func NewFieldEncryptor(masterKey []byte) (*FieldEncryptor, error) {
+	if len(masterKey) != FieldKeySize {
+		return nil, fmt.Errorf("field encryption key must be %d bytes, got %d", FieldKeySize, len(masterKey))
+	}
+
+	encKey, err := hkdf.Key(sha256.New, masterKey, nil, "maintainerd-field-encryption", 32)
+	if err != nil {
+		return nil, fmt.Errorf("derive encryption key: %w", err)
+	}
+	indexKey, err := hkdf.Key(sha256.New, masterKey, nil, "maintainerd-field-blind-index", 32)
+	if err != nil {
+		return nil, fmt.Errorf("derive blind index key: %w", err)
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FieldEncryptor{aead: aead, indexKey: indexKey}, nil
+}
+
+// Encrypt seals plaintext for the named column and returns a printable,
+// versioned ciphertext.
+func (e *FieldEncryptor) Encrypt(field, plaintext string) (string, error) {
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("crypto/rand failure: %w", err)
+	}
+	sealed := e.aead.Seal(nonce, nonce, []byte(plaintext), []byte(field))
+	return fieldCiphertextPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a ciphertext produced by Encrypt for the same column.
+func (e *FieldEncryptor) Decrypt(field, ciphertext string) (string, error) {
+	if !strings.HasPrefix(ciphertext, fieldCiphertextPrefix) {
+		return "", errors.New("unsupported field ciphertext format")
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(ciphertext, fieldCiphertextPrefix))
+	if err != nil {
+		return "", fmt.Errorf("decode field ciphertext: %w", err)
+	}
+	nonceSize := e.aead.NonceSize()
+	if len(raw) < nonceSize {
+		return "", errors.New("field ciphertext too short")
+	}
+	plaintext, err := e.aead.Open(nil, raw[:nonceSize], raw[nonceSize:], []byte(field))
+	if err != nil {
+		return "", fmt.Errorf("decrypt field %q: %w", field, err)
+	}
+	return string(plaintext), nil
+}
+
+// BlindIndex returns a deterministic, hex-encoded keyed hash of value for the
+// named column. Callers must normalise value consistently on write and lookup.
+func (e *FieldEncryptor) BlindIndex(field, value string) string {
+	mac := hmac.New(sha256.New, e.indexKey)
+	mac.Write([]byte(field))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
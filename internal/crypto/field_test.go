@@ -0,0 +1,88 @@
+package crypto
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFieldEncryptor(t *testing.T, seed byte) *FieldEncryptor {
+	t.Helper()
+	e, err := NewFieldEncryptor(bytes.Repeat([]byte{seed}, FieldKeySize))
+	require.NoError(t, err)
+	return e
+}
+
+func TestNewFieldEncryptor_InvalidKeySize(t *testing.T) {
+	for _, n := range []int{0, 16, 31, 33, 64} {
+		_, err := NewFieldEncryptor(make([]byte, n))
+		require.Error(t, err, "key size %d", n)
+	}
+}
+
+func TestFieldEncryptor_RoundTrip(t *testing.T) {
+	e := testFieldEncryptor(t, 1)
+
+	ct, err := e.Encrypt("phone", "+15551234567")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(ct, fieldCiphertextPrefix))
+	assert.NotContains(t, ct, "5551234567")
+
+	pt, err := e.Decrypt("phone", ct)
+	require.NoError(t, err)
+	assert.Equal(t, "+15551234567", pt)
+}
+
+func TestFieldEncryptor_EncryptIsRandomised(t *testing.T) {
+	e := testFieldEncryptor(t, 1)
+	a, err := e.Encrypt("phone", "same")
+	require.NoError(t, err)
+	b, err := e.Encrypt("phone", "same")
+	require.NoError(t, err)
+	assert.NotEqual(t, a, b)
+}
+
+func TestFieldEncryptor_DecryptFailures(t *testing.T) {
+	e := testFieldEncryptor(t, 1)
+	ct, err := e.Encrypt("phone", "+15551234567")
+	require.NoError(t, err)
+
+	t.Run("wrong field", func(t *testing.T) {
+		_, err := e.Decrypt("address", ct)
+		require.Error(t, err)
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		_, err := testFieldEncryptor(t, 2).Decrypt("phone", ct)
+		require.Error(t, err)
+	})
+
+	t.Run("missing prefix", func(t *testing.T) {
+		_, err := e.Decrypt("phone", "plaintext")
+		require.Error(t, err)
+	})
+
+	t.Run("bad base64", func(t *testing.T) {
+		_, err := e.Decrypt("phone", fieldCiphertextPrefix+"!!!")
+		require.Error(t, err)
+	})
+
+	t.Run("too short", func(t *testing.T) {
+		_, err := e.Decrypt("phone", fieldCiphertextPrefix+"AAAA")
+		require.Error(t, err)
+	})
+}
+
+func TestFieldEncryptor_BlindIndex(t *testing.T) {
+	e := testFieldEncryptor(t, 1)
+
+	a := e.BlindIndex("phone", "+15551234567")
+	assert.Len(t, a, 64)
+	assert.Equal(t, a, e.BlindIndex("phone", "+15551234567"), "must be deterministic")
+	assert.NotEqual(t, a, e.BlindIndex("phone", "+15551234568"))
+	assert.NotEqual(t, a, e.BlindIndex("birthdate", "+15551234567"), "must be scoped per field")
+	assert.NotEqual(t, a, testFieldEncryptor(t, 2).BlindIndex("phone", "+15551234567"), "must depend on the key")
+}
@@ -0,0 +1,58 @@
+package crypto
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+)
+
+// KeyWrapper encrypts data keys with a key-encryption key (KEK) that never
+// leaves it: a local key, a KMS key or a Vault transit key. Wrapped keys are
+// printable so they can be stored in a text column.
+type KeyWrapper interface {
+	WrapKey(ctx context.Context, key []byte) (string, error)
+	UnwrapKey(ctx context.Context, wrapped string) ([]byte, error)
+}
+
+// localKeyWrapperField is the encryption context of locally wrapped keys.
+const localKeyWrapperField = "data_keys.wrapped_key"
+
+// localKeyWrapper wraps data keys with a KEK held in memory.
+type localKeyWrapper struct {
+	current  *FieldEncryptor
+	previous []*FieldEncryptor
+}
+
+// NewLocalKeyWrapper creates a KeyWrapper from a 32-byte KEK. Keys are
+// wrapped with kek and unwrapped with kek or any of the previous KEKs, so
+// the KEK can be replaced and the data keys rewrapped afterwards.
+func NewLocalKeyWrapper(kek []byte, previous ...[]byte) (KeyWrapper, error) {
+	current, err := NewFieldEncryptor(kek)
+	if err != nil {
+		return nil, err
+	}
+	w := &localKeyWrapper{current: current}
+	for _, key := range previous {
+		prev, err := NewFieldEncryptor(key)
+		if err != nil {
+			return nil, err
+		}
+		w.previous = append(w.previous, prev)
+	}
+	return w, nil
+}
+
+func (w *localKeyWrapper) WrapKey(_ context.Context, key []byte) (string, error) {
+	return w.current.Encrypt(localKeyWrapperField, base64.StdEncoding.EncodeToString(key))
+}
+
+func (w *localKeyWrapper) UnwrapKey(_ context.Context, wrapped string) ([]byte, error) {
+	encoded, err := w.current.Decrypt(localKeyWrapperField, wrapped)
+	for i := 0; err != nil && i < len(w.previous); i++ {
+		encoded, err = w.previous[i].Decrypt(localKeyWrapperField, wrapped)
+	}
+	if err != nil {
+		return nil, errors.New("data key is not wrapped by the configured key-encryption key")
+	}
+	return base64.StdEncoding.DecodeString(encoded)
+}
@@ -0,0 +1,186 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DataKeySize is the length in bytes of the data keys a Keyring encrypts
+// with.
+const DataKeySize = 32
+
+// envelopeCiphertextPrefix marks values produced by Keyring.Encrypt. The
+// data key ID follows, so a value names the key that opens it.
+const envelopeCiphertextPrefix = "ev1:"
+
+// ErrNoActiveDataKey is returned by Keyring.Encrypt before a data key is
+// installed.
+var ErrNoActiveDataKey = errors.New("no active data key")
+
+// Keyring encrypts individual database columns with envelope encryption:
+// values are sealed with AES-256-GCM under a data key, and the data keys are
+// stored wrapped by a KeyWrapper. The column name is the additional data, so
+// a ciphertext cannot be moved to a different column.
+//
+// Values are encrypted with the active data key. Previous data keys keep
+// decrypting the values they sealed until those are re-encrypted.
+type Keyring struct {
+	mu     sync.RWMutex
+	active string
+	keys   map[string]cipher.AEAD
+	fetch  func(keyID string) ([]byte, error)
+}
+
+// NewKeyring creates an empty Keyring. fetch, when not nil, is called for a
+// key ID that is not installed, so values sealed under a key created by
+// another instance stay readable before the keys are reloaded.
+func NewKeyring(fetch func(keyID string) ([]byte, error)) *Keyring {
+	return &Keyring{keys: map[string]cipher.AEAD{}, fetch: fetch}
+}
+
+// GenerateDataKey returns a new random data key.
+func GenerateDataKey() ([]byte, error) {
+	key := make([]byte, DataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("crypto/rand failure: %w", err)
+	}
+	return key, nil
+}
+
+// SetKeys replaces the installed data keys, keyed by ID, and makes active
+// the key new values are encrypted with.
+func (k *Keyring) SetKeys(active string, keys map[string][]byte) error {
+	if _, ok := keys[active]; !ok {
+		return fmt.Errorf("active data key %q is not among the keys", active)
+	}
+	aeads := make(map[string]cipher.AEAD, len(keys))
+	for id, key := range keys {
+		aead, err := newDataKeyAEAD(key)
+		if err != nil {
+			return fmt.Errorf("data key %q: %w", id, err)
+		}
+		aeads[id] = aead
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.active, k.keys = active, aeads
+	return nil
+}
+
+// ActiveKeyID returns the ID of the key new values are encrypted with, or ""
+// before keys are installed.
+func (k *Keyring) ActiveKeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.active
+}
+
+// Encrypt seals plaintext for the named column with the active data key.
+func (k *Keyring) Encrypt(field, plaintext string) (string, error) {
+	k.mu.RLock()
+	active, aead := k.active, k.keys[k.active]
+	k.mu.RUnlock()
+	if aead == nil {
+		return "", ErrNoActiveDataKey
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("crypto/rand failure: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(field))
+	return envelopeCiphertextPrefix + active + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value sealed by Encrypt for the same column. Values that
+// are not envelope ciphertexts are returned unchanged, so rows written
+// before encryption was enabled stay readable until they are re-encrypted.
+func (k *Keyring) Decrypt(field, value string) (string, error) {
+	keyID, encoded, ok := splitEnvelope(value)
+	if !ok {
+		return value, nil
+	}
+	aead, err := k.key(keyID)
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("decode column ciphertext: %w", err)
+	}
+	nonceSize := aead.NonceSize()
+	if len(raw) < nonceSize {
+		return "", errors.New("column ciphertext too short")
+	}
+	plaintext, err := aead.Open(nil, raw[:nonceSize], raw[nonceSize:], []byte(field))
+	if err != nil {
+		return "", fmt.Errorf("decrypt column %q: %w", field, err)
+	}
+	return string(plaintext), nil
+}
+
+// key returns the installed key with the ID, fetching it when missing.
+func (k *Keyring) key(keyID string) (cipher.AEAD, error) {
+	k.mu.RLock()
+	aead := k.keys[keyID]
+	k.mu.RUnlock()
+	if aead != nil {
+		return aead, nil
+	}
+	if k.fetch == nil {
+		return nil, fmt.Errorf("unknown data key %q", keyID)
+	}
+
+	key, err := k.fetch(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch data key %q: %w", keyID, err)
+	}
+	if aead, err = newDataKeyAEAD(key); err != nil {
+		return nil, fmt.Errorf("data key %q: %w", keyID, err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[keyID] = aead
+	return aead, nil
+}
+
+// EnvelopeKeyID returns the ID of the data key that sealed value, and false
+// when value is not an envelope ciphertext.
+func EnvelopeKeyID(value string) (string, bool) {
+	keyID, _, ok := splitEnvelope(value)
+	return keyID, ok
+}
+
+// EnvelopePrefix returns the prefix of the values sealed under the data key
+// with the ID, for finding values that are not.
+func EnvelopePrefix(keyID string) string {
+	return envelopeCiphertextPrefix + keyID + ":"
+}
+
+func splitEnvelope(value string) (keyID, encoded string, ok bool) {
+	rest, found := strings.CutPrefix(value, envelopeCiphertextPrefix)
+	if !found {
+		return "", "", false
+	}
+	return strings.Cut(rest, ":")
+}
+
+func newDataKeyAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != DataKeySize {
+		return nil, fmt.Errorf("data key must be %d bytes, got %d", DataKeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,167 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKeyring(t *testing.T, fetch func(string) ([]byte, error), active string, keys map[string][]byte) *Keyring {
+	t.Helper()
+	k := NewKeyring(fetch)
+	require.NoError(t, k.SetKeys(active, keys))
+	return k
+}
+
+func TestKeyring_RoundTrip(t *testing.T) {
+	k := testKeyring(t, nil, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, DataKeySize)})
+
+	ct, err := k.Encrypt("clients.secret", "s3cret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(ct, envelopeCiphertextPrefix+"k1:"))
+	assert.NotContains(t, ct, "s3cret")
+
+	keyID, ok := EnvelopeKeyID(ct)
+	assert.True(t, ok)
+	assert.Equal(t, "k1", keyID)
+
+	pt, err := k.Decrypt("clients.secret", ct)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", pt)
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	k1, k2 := bytes.Repeat([]byte{1}, DataKeySize), bytes.Repeat([]byte{2}, DataKeySize)
+	k := testKeyring(t, nil, "k1", map[string][]byte{"k1": k1})
+	old, err := k.Encrypt("clients.secret", "old")
+	require.NoError(t, err)
+
+	require.NoError(t, k.SetKeys("k2", map[string][]byte{"k1": k1, "k2": k2}))
+	assert.Equal(t, "k2", k.ActiveKeyID())
+	fresh, err := k.Encrypt("clients.secret", "new")
+	require.NoError(t, err)
+	keyID, _ := EnvelopeKeyID(fresh)
+	assert.Equal(t, "k2", keyID)
+
+	pt, err := k.Decrypt("clients.secret", old)
+	require.NoError(t, err)
+	assert.Equal(t, "old", pt, "previous keys keep decrypting")
+}
+
+func TestKeyring_FetchesUnknownKeys(t *testing.T) {
+	k2 := bytes.Repeat([]byte{2}, DataKeySize)
+	other := testKeyring(t, nil, "k2", map[string][]byte{"k2": k2})
+	ct, err := other.Encrypt("clients.secret", "value")
+	require.NoError(t, err)
+
+	var fetched []string
+	k := testKeyring(t, func(id string) ([]byte, error) {
+		fetched = append(fetched, id)
+		if id == "k2" {
+			return k2, nil
+		}
+		return nil, errors.New("not found")
+	}, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, DataKeySize)})
+
+	for range 2 {
+		pt, err := k.Decrypt("clients.secret", ct)
+		require.NoError(t, err)
+		assert.Equal(t, "value", pt)
+	}
+	assert.Equal(t, []string{"k2"}, fetched, "fetched keys are kept")
+
+	_, err = k.Decrypt("clients.secret", envelopeCiphertextPrefix+"k9:AAAA")
+	assert.ErrorContains(t, err, "fetch data key")
+}
+
+func TestKeyring_Failures(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, DataKeySize)
+
+	t.Run("no active key", func(t *testing.T) {
+		_, err := NewKeyring(nil).Encrypt("clients.secret", "value")
+		assert.ErrorIs(t, err, ErrNoActiveDataKey)
+	})
+
+	t.Run("active key missing", func(t *testing.T) {
+		assert.Error(t, NewKeyring(nil).SetKeys("k2", map[string][]byte{"k1": key}))
+	})
+
+	t.Run("invalid key size", func(t *testing.T) {
+		assert.Error(t, NewKeyring(nil).SetKeys("k1", map[string][]byte{"k1": key[:16]}))
+	})
+
+	t.Run("plaintext passes through", func(t *testing.T) {
+		pt, err := NewKeyring(nil).Decrypt("clients.secret", "legacy")
+		require.NoError(t, err)
+		assert.Equal(t, "legacy", pt)
+	})
+
+	k := testKeyring(t, nil, "k1", map[string][]byte{"k1": key})
+	ct, err := k.Encrypt("clients.secret", "value")
+	require.NoError(t, err)
+
+	t.Run("wrong column", func(t *testing.T) {
+		_, err := k.Decrypt("login_hooks.secret_encrypted", ct)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := NewKeyring(nil).Decrypt("clients.secret", ct)
+		assert.ErrorContains(t, err, `unknown data key "k1"`)
+	})
+
+	t.Run("bad base64", func(t *testing.T) {
+		_, err := k.Decrypt("clients.secret", envelopeCiphertextPrefix+"k1:!!!")
+		assert.Error(t, err)
+	})
+
+	t.Run("too short", func(t *testing.T) {
+		_, err := k.Decrypt("clients.secret", envelopeCiphertextPrefix+"k1:AAAA")
+		assert.Error(t, err)
+	})
+}
+
+func TestLocalKeyWrapper(t *testing.T) {
+	ctx := context.Background()
+	oldKEK, newKEK := bytes.Repeat([]byte{1}, FieldKeySize), bytes.Repeat([]byte{2}, FieldKeySize)
+	dataKey, err := GenerateDataKey()
+	require.NoError(t, err)
+
+	old, err := NewLocalKeyWrapper(oldKEK)
+	require.NoError(t, err)
+	wrapped, err := old.WrapKey(ctx, dataKey)
+	require.NoError(t, err)
+
+	t.Run("round trip", func(t *testing.T) {
+		got, err := old.UnwrapKey(ctx, wrapped)
+		require.NoError(t, err)
+		assert.Equal(t, dataKey, got)
+	})
+
+	t.Run("previous KEK", func(t *testing.T) {
+		rotated, err := NewLocalKeyWrapper(newKEK, oldKEK)
+		require.NoError(t, err)
+		got, err := rotated.UnwrapKey(ctx, wrapped)
+		require.NoError(t, err)
+		assert.Equal(t, dataKey, got)
+	})
+
+	t.Run("wrong KEK", func(t *testing.T) {
+		other, err := NewLocalKeyWrapper(newKEK)
+		require.NoError(t, err)
+		_, err = other.UnwrapKey(ctx, wrapped)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid KEK", func(t *testing.T) {
+		_, err := NewLocalKeyWrapper(newKEK[:16])
+		assert.Error(t, err)
+		_, err = NewLocalKeyWrapper(newKEK, oldKEK[:16])
+		assert.Error(t, err)
+	})
+}
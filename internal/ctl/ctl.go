@@ -0,0 +1,73 @@
+// Package ctl implements auth-ctl, the administrative command
+// line. Its commands run against the configured database through the service
+// layer, for bootstrap and incident response without a running server or
+// direct SQL.
+package ctl
+
+import (
+	"context"
+
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// Env holds what the commands run against.
+type Env struct {
+	DB         *gorm.DB
+	Setup      service.SetupService
+	Tenant     service.TenantService
+	User       service.UserService
+	SigningKey service.SigningKeyService // nil when key rotation is disabled
+	DataKey    service.DataKeyService    // nil when column encryption is disabled
+}
+
+// Opener connects to the database and wires the services. It is called once
+// per command, only by the commands that need it; close releases what it
+// opened.
+type Opener func() (env *Env, close func(), err error)
+
+// NewRootCommand returns the auth-ctl command with every
+// subcommand.
+func NewRootCommand(open Opener) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "auth-ctl",
+		Short:         "Administer a maintainerd auth deployment",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.AddCommand(
+		newCreateTenantCommand(open),
+		newCreateAdminUserCommand(open),
+		newRotateKeysCommand(open),
+		newRotateDataKeyCommand(open),
+		newRewrapDataKeysCommand(open),
+		newReencryptColumnsCommand(open),
+		newSeedCommand(open),
+		NewMigrateCommand(open),
+		newExportUsersCommand(open),
+		newRevokeSessionsCommand(open),
+	)
+	return root
+}
+
+// withEnv returns a RunE that opens the environment, runs fn and closes it.
+func withEnv(open Opener, fn func(cmd *cobra.Command, args []string, env *Env) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		env, closeEnv, err := open()
+		if err != nil {
+			return err
+		}
+		defer closeEnv()
+		return fn(cmd, args, env)
+	}
+}
+
+// resolveTenant returns the tenant with identifier, or the system tenant when
+// identifier is empty.
+func resolveTenant(ctx context.Context, tenants service.TenantService, identifier string) (*service.TenantServiceDataResult, error) {
+	if identifier == "" {
+		return tenants.GetSystem(ctx)
+	}
+	return tenants.GetByIdentifier(ctx, identifier)
+}
@@ -0,0 +1,298 @@
+package ctl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/runner"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// ---- mockSetupService ----
+
+type mockSetupService struct {
+	service.SetupService
+	tenantSetup    bool
+	createTenantFn func(dto.CreateTenantRequestDTO) (*dto.CreateTenantResponseDTO, error)
+	createAdminFn  func(dto.CreateAdminRequestDTO) (*dto.CreateAdminResponseDTO, error)
+}
+
+func (m *mockSetupService) GetSetupStatus(context.Context) (*dto.SetupStatusResponseDTO, error) {
+	return &dto.SetupStatusResponseDTO{IsTenantSetup: m.tenantSetup}, nil
+}
+func (m *mockSetupService) CreateTenant(_ context.Context, req dto.CreateTenantRequestDTO) (*dto.CreateTenantResponseDTO, error) {
+	return m.createTenantFn(req)
+}
+func (m *mockSetupService) CreateAdmin(_ context.Context, req dto.CreateAdminRequestDTO) (*dto.CreateAdminResponseDTO, error) {
+	return m.createAdminFn(req)
+}
+
+// ---- mockTenantService ----
+
+type mockTenantService struct {
+	service.TenantService
+	system   *service.TenantServiceDataResult
+	createFn func(name, displayName, description, status string, isPublic bool, parent *uuid.UUID) (*service.TenantServiceDataResult, error)
+}
+
+func (m *mockTenantService) GetSystem(context.Context) (*service.TenantServiceDataResult, error) {
+	return m.system, nil
+}
+func (m *mockTenantService) GetByIdentifier(_ context.Context, identifier string) (*service.TenantServiceDataResult, error) {
+	if identifier == "acme" {
+		return &service.TenantServiceDataResult{TenantID: 2, Identifier: "acme"}, nil
+	}
+	return nil, errors.New("tenant not found")
+}
+func (m *mockTenantService) Create(_ context.Context, name, displayName, description, status string, isPublic bool, parent *uuid.UUID) (*service.TenantServiceDataResult, error) {
+	return m.createFn(name, displayName, description, status, isPublic, parent)
+}
+
+// ---- mockUserService ----
+
+type mockUserService struct {
+	service.UserService
+	exportFn         func(service.UserServiceGetFilter, func(service.UserServiceDataResult) error) error
+	revokeSessionsFn func(uuid.UUID, int64) (int64, error)
+}
+
+func (m *mockUserService) Export(_ context.Context, f service.UserServiceGetFilter, fn func(service.UserServiceDataResult) error) error {
+	return m.exportFn(f, fn)
+}
+func (m *mockUserService) RevokeSessions(_ context.Context, id uuid.UUID, tid int64) (int64, error) {
+	return m.revokeSessionsFn(id, tid)
+}
+
+// ---- mockSigningKeyService ----
+
+type mockSigningKeyService struct {
+	service.SigningKeyService
+}
+
+func (m *mockSigningKeyService) Rotate(context.Context) (*dto.SigningKeyResponseDTO, error) {
+	return &dto.SigningKeyResponseDTO{KeyID: "kid-2"}, nil
+}
+
+// ---- mockDataKeyService ----
+
+type mockDataKeyService struct {
+	service.DataKeyService
+}
+
+func (m *mockDataKeyService) Rotate(context.Context) (*dto.DataKeyResponseDTO, error) {
+	return &dto.DataKeyResponseDTO{KeyID: "dk-2"}, nil
+}
+
+func (m *mockDataKeyService) Rewrap(context.Context) (*dto.DataKeyRewrapResponseDTO, error) {
+	return &dto.DataKeyRewrapResponseDTO{Rewrapped: 2}, nil
+}
+
+func (m *mockDataKeyService) Reencrypt(context.Context) (*dto.DataKeyReencryptResponseDTO, error) {
+	return &dto.DataKeyReencryptResponseDTO{ActiveKeyID: "dk-2", Reencrypted: 5}, nil
+}
+
+// runCommand runs the root command against env and returns what it wrote.
+func runCommand(t *testing.T, env *Env, stdin string, args ...string) (string, error) {
+	t.Helper()
+	var opened, closed int
+	root := NewRootCommand(func() (*Env, func(), error) {
+		opened++
+		return env, func() { closed++ }, nil
+	})
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(io.Discard)
+	root.SetIn(strings.NewReader(stdin))
+	root.SetArgs(args)
+	err := root.Execute()
+	assert.Equal(t, opened, closed, "every opened environment is closed")
+	return out.String(), err
+}
+
+func newTestEnv() *Env {
+	return &Env{
+		Setup:  &mockSetupService{},
+		Tenant: &mockTenantService{system: &service.TenantServiceDataResult{TenantID: 1, IsSystem: true}},
+		User:   &mockUserService{},
+	}
+}
+
+func TestCreateTenant(t *testing.T) {
+	t.Run("creates the system tenant on a fresh install", func(t *testing.T) {
+		env := newTestEnv()
+		var got dto.CreateTenantRequestDTO
+		env.Setup.(*mockSetupService).createTenantFn = func(req dto.CreateTenantRequestDTO) (*dto.CreateTenantResponseDTO, error) {
+			got = req
+			return &dto.CreateTenantResponseDTO{Tenant: dto.TenantResponseDTO{Name: req.Name, Identifier: "sys"}}, nil
+		}
+
+		out, err := runCommand(t, env, "", "create-tenant", "--name", "main", "--display-name", "Main")
+		require.NoError(t, err)
+		assert.Equal(t, "main", got.Name)
+		assert.Nil(t, got.Description)
+		assert.Contains(t, out, "created system tenant main")
+	})
+
+	t.Run("creates a tenant under a parent", func(t *testing.T) {
+		env := newTestEnv()
+		env.Setup.(*mockSetupService).tenantSetup = true
+		parent := uuid.New()
+		env.Tenant.(*mockTenantService).createFn = func(name, displayName, description, status string, isPublic bool, p *uuid.UUID) (*service.TenantServiceDataResult, error) {
+			assert.Equal(t, "acme", name)
+			assert.Equal(t, "Acme", description)
+			assert.True(t, isPublic)
+			require.NotNil(t, p)
+			assert.Equal(t, parent, *p)
+			return &service.TenantServiceDataResult{Name: name, Identifier: "abc"}, nil
+		}
+
+		out, err := runCommand(t, env, "", "create-tenant", "--name", "acme", "--display-name", "Acme Inc",
+			"--description", "Acme", "--public", "--parent", parent.String())
+		require.NoError(t, err)
+		assert.Contains(t, out, "created tenant acme")
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		_, err := runCommand(t, newTestEnv(), "", "create-tenant", "--name", "a!", "--display-name", "Acme")
+		assert.Error(t, err)
+	})
+
+	t.Run("missing flags", func(t *testing.T) {
+		_, err := runCommand(t, newTestEnv(), "", "create-tenant", "--name", "acme")
+		assert.Error(t, err)
+	})
+}
+
+func TestCreateAdminUser(t *testing.T) {
+	t.Run("reads the password from stdin", func(t *testing.T) {
+		env := newTestEnv()
+		var got dto.CreateAdminRequestDTO
+		env.Setup.(*mockSetupService).createAdminFn = func(req dto.CreateAdminRequestDTO) (*dto.CreateAdminResponseDTO, error) {
+			got = req
+			return &dto.CreateAdminResponseDTO{User: dto.UserResponseDTO{Username: req.Username}}, nil
+		}
+
+		out, err := runCommand(t, env, "s3cret-pass\n", "create-admin-user",
+			"--username", "admin", "--fullname", "Admin", "--email", "admin@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cret-pass", got.Password)
+		assert.Contains(t, out, "created admin user admin")
+	})
+
+	t.Run("no password", func(t *testing.T) {
+		_, err := runCommand(t, newTestEnv(), "", "create-admin-user",
+			"--username", "admin", "--fullname", "Admin", "--email", "admin@example.com")
+		assert.EqualError(t, err, "no password on stdin")
+	})
+}
+
+func TestRotateKeys(t *testing.T) {
+	t.Run("rotates the signing key", func(t *testing.T) {
+		env := newTestEnv()
+		env.SigningKey = &mockSigningKeyService{}
+		out, err := runCommand(t, env, "", "rotate-keys")
+		require.NoError(t, err)
+		assert.Contains(t, out, "kid-2")
+	})
+
+	t.Run("rotation disabled", func(t *testing.T) {
+		_, err := runCommand(t, newTestEnv(), "", "rotate-keys")
+		assert.ErrorContains(t, err, "JWT_KEY_ROTATION_ENABLED")
+	})
+}
+
+func TestDataKeyCommands(t *testing.T) {
+	for command, want := range map[string]string{
+		"rotate-data-key":   "rotated data key: dk-2",
+		"rewrap-data-keys":  "rewrapped 2 data keys",
+		"reencrypt-columns": "re-encrypted 5 values with data key dk-2",
+	} {
+		t.Run(command, func(t *testing.T) {
+			env := newTestEnv()
+			env.DataKey = &mockDataKeyService{}
+			out, err := runCommand(t, env, "", command)
+			require.NoError(t, err)
+			assert.Contains(t, out, want)
+
+			_, err = runCommand(t, newTestEnv(), "", command)
+			assert.ErrorContains(t, err, "COLUMN_ENCRYPTION_ENABLED")
+		})
+	}
+}
+
+func TestExportUsers(t *testing.T) {
+	env := newTestEnv()
+	env.User.(*mockUserService).exportFn = func(f service.UserServiceGetFilter, fn func(service.UserServiceDataResult) error) error {
+		assert.Equal(t, int64(2), f.TenantID)
+		assert.Equal(t, []string{"active"}, f.Status)
+		if err := fn(service.UserServiceDataResult{Username: "jane", Status: "active"}); err != nil {
+			return err
+		}
+		return fn(service.UserServiceDataResult{Username: "john", Status: "active"})
+	}
+
+	out, err := runCommand(t, env, "", "export-users", "--tenant", "acme", "--status", "active")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"username":"jane"`)
+	assert.Contains(t, lines[0], `"metadata":null`)
+	assert.Contains(t, lines[1], `"username":"john"`)
+}
+
+func TestRevokeSessions(t *testing.T) {
+	t.Run("revokes in the system tenant by default", func(t *testing.T) {
+		env := newTestEnv()
+		userUUID := uuid.New()
+		env.User.(*mockUserService).revokeSessionsFn = func(id uuid.UUID, tid int64) (int64, error) {
+			assert.Equal(t, userUUID, id)
+			assert.Equal(t, int64(1), tid)
+			return 2, nil
+		}
+
+		out, err := runCommand(t, env, "", "revoke-sessions", userUUID.String())
+		require.NoError(t, err)
+		assert.Contains(t, out, "revoked 2 sessions")
+	})
+
+	t.Run("invalid user id", func(t *testing.T) {
+		_, err := runCommand(t, newTestEnv(), "", "revoke-sessions", "nope")
+		assert.EqualError(t, err, "invalid user id: nope")
+	})
+
+	t.Run("unknown tenant", func(t *testing.T) {
+		_, err := runCommand(t, newTestEnv(), "", "revoke-sessions", uuid.NewString(), "--tenant", "other")
+		assert.Error(t, err)
+	})
+}
+
+func TestOpenError(t *testing.T) {
+	root := NewRootCommand(func() (*Env, func(), error) { return nil, nil, errors.New("no database") })
+	root.SetOut(io.Discard)
+	root.SetArgs([]string{"seed"})
+	assert.EqualError(t, root.Execute(), "no database")
+}
+
+func TestSeed(t *testing.T) {
+	orig := runner.RunSeeders
+	defer func() { runner.RunSeeders = orig }()
+	var called bool
+	runner.RunSeeders = func(*gorm.DB, string) error {
+		called = true
+		return nil
+	}
+
+	out, err := runCommand(t, newTestEnv(), "", "seed")
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.Contains(t, out, "seeded")
+}
@@ -0,0 +1,117 @@
+package ctl
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+// newRotateKeysCommand returns rotate-keys, which replaces the active token
+// signing key like POST /admin/signing-keys/rotate.
+func newRotateKeysCommand(open Opener) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate-keys",
+		Short: "Rotate the token signing key",
+		Args:  cobra.NoArgs,
+		RunE: withEnv(open, func(cmd *cobra.Command, _ []string, env *Env) error {
+			if env.SigningKey == nil {
+				return errors.New("signing key rotation is disabled: set JWT_KEY_ROTATION_ENABLED")
+			}
+			key, err := env.SigningKey.Rotate(cmd.Context())
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "rotated signing key: kid %s\n", key.KeyID)
+			return nil
+		}),
+	}
+}
+
+// errColumnEncryptionDisabled is returned by the data key commands when
+// column encryption is not enabled.
+var errColumnEncryptionDisabled = errors.New("column encryption is disabled: set COLUMN_ENCRYPTION_ENABLED")
+
+// newRotateDataKeyCommand returns rotate-data-key, which replaces the active
+// column encryption data key like POST /admin/data-keys/rotate.
+func newRotateDataKeyCommand(open Opener) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate-data-key",
+		Short: "Rotate the column encryption data key",
+		Args:  cobra.NoArgs,
+		RunE: withEnv(open, func(cmd *cobra.Command, _ []string, env *Env) error {
+			if env.DataKey == nil {
+				return errColumnEncryptionDisabled
+			}
+			key, err := env.DataKey.Rotate(cmd.Context())
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "rotated data key: %s\n", key.KeyID)
+			return nil
+		}),
+	}
+}
+
+// newRewrapDataKeysCommand returns rewrap-data-keys, which wraps the data
+// keys with the current key-encryption key like POST /admin/data-keys/rewrap.
+func newRewrapDataKeysCommand(open Opener) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rewrap-data-keys",
+		Short: "Wrap the data keys with the current key-encryption key",
+		Args:  cobra.NoArgs,
+		RunE: withEnv(open, func(cmd *cobra.Command, _ []string, env *Env) error {
+			if env.DataKey == nil {
+				return errColumnEncryptionDisabled
+			}
+			result, err := env.DataKey.Rewrap(cmd.Context())
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "rewrapped %d data keys\n", result.Rewrapped)
+			return nil
+		}),
+	}
+}
+
+// newReencryptColumnsCommand returns reencrypt-columns, which seals the
+// stored values not sealed with the active data key like
+// POST /admin/data-keys/reencrypt.
+func newReencryptColumnsCommand(open Opener) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reencrypt-columns",
+		Short: "Re-encrypt sensitive columns with the active data key",
+		Args:  cobra.NoArgs,
+		RunE: withEnv(open, func(cmd *cobra.Command, _ []string, env *Env) error {
+			if env.DataKey == nil {
+				return errColumnEncryptionDisabled
+			}
+			result, err := env.DataKey.Reencrypt(cmd.Context())
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "re-encrypted %d values with data key %s\n", result.Reencrypted, result.ActiveKeyID)
+			return nil
+		}),
+	}
+}
+
+// newSeedCommand returns seed, which seeds the service and the system
+// tenant's default resources. Seeders skip what already exists, so it is
+// safe to run again after an upgrade.
+func newSeedCommand(open Opener) *cobra.Command {
+	return &cobra.Command{
+		Use:   "seed",
+		Short: "Seed the default resources of the system tenant",
+		Args:  cobra.NoArgs,
+		RunE: withEnv(open, func(cmd *cobra.Command, _ []string, env *Env) error {
+			if err := runner.RunSeeders(env.DB, config.AppVersion); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "seeded the system tenant")
+			return nil
+		}),
+	}
+}
@@ -0,0 +1,85 @@
+package ctl
+
+import (
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/maintainerd/auth/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+// NewMigrateCommand returns the migrate command: "up" applies every pending
+// migration, "down" reverts the last steps (default 1) and "status" lists
+// every migration with the time it was applied. The server binary runs it
+// too, as "server migrate". Only Env.DB is used.
+func NewMigrateCommand(open Opener) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply, revert or list schema migrations",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "up",
+		Short: "Apply every pending migration",
+		Args:  cobra.NoArgs,
+		RunE: withEnv(open, func(cmd *cobra.Command, _ []string, env *Env) error {
+			applied, err := runner.ApplyMigrations(env.DB)
+			for _, version := range applied {
+				fmt.Fprintln(cmd.OutOrStdout(), "applied", version)
+			}
+			if err == nil && len(applied) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "no pending migrations")
+			}
+			return err
+		}),
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "down [steps]",
+		Short: "Revert the last applied migrations, newest first",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			steps := 1
+			if len(args) > 0 {
+				n, err := strconv.Atoi(args[0])
+				if err != nil || n < 1 {
+					return fmt.Errorf("steps must be a positive number: %s", args[0])
+				}
+				steps = n
+			}
+			return withEnv(open, func(cmd *cobra.Command, _ []string, env *Env) error {
+				reverted, err := runner.RollbackMigrations(env.DB, steps)
+				for _, version := range reverted {
+					fmt.Fprintln(cmd.OutOrStdout(), "reverted", version)
+				}
+				return err
+			})(cmd, args)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "List every migration with the time it was applied",
+		Args:  cobra.NoArgs,
+		RunE: withEnv(open, func(cmd *cobra.Command, _ []string, env *Env) error {
+			states, err := runner.MigrationStatus(env.DB)
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "VERSION\tAPPLIED AT")
+			for _, state := range states {
+				appliedAt := "pending"
+				if state.AppliedAt != nil {
+					appliedAt = state.AppliedAt.UTC().Format(time.RFC3339)
+				}
+				fmt.Fprintf(w, "%s\t%s\n", state.Version, appliedAt)
+			}
+			return w.Flush()
+		}),
+	})
+
+	return cmd
+}
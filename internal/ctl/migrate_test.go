@@ -0,0 +1,73 @@
+package ctl
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maintainerd/auth/internal/runner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestMigrate(t *testing.T) {
+	origApply, origRollback, origStatus := runner.ApplyMigrations, runner.RollbackMigrations, runner.MigrationStatus
+	t.Cleanup(func() {
+		runner.ApplyMigrations, runner.RollbackMigrations, runner.MigrationStatus = origApply, origRollback, origStatus
+	})
+
+	t.Run("up", func(t *testing.T) {
+		runner.ApplyMigrations = func(*gorm.DB) ([]string, error) { return []string{"075_a", "076_b"}, nil }
+		out, err := runCommand(t, newTestEnv(), "", "migrate", "up")
+		require.NoError(t, err)
+		assert.Equal(t, "applied 075_a\napplied 076_b\n", out)
+	})
+
+	t.Run("up with nothing pending", func(t *testing.T) {
+		runner.ApplyMigrations = func(*gorm.DB) ([]string, error) { return nil, nil }
+		out, err := runCommand(t, newTestEnv(), "", "migrate", "up")
+		require.NoError(t, err)
+		assert.Equal(t, "no pending migrations\n", out)
+	})
+
+	t.Run("up stops at a failing migration", func(t *testing.T) {
+		runner.ApplyMigrations = func(*gorm.DB) ([]string, error) { return []string{"075_a"}, errors.New("boom") }
+		out, err := runCommand(t, newTestEnv(), "", "migrate", "up")
+		assert.EqualError(t, err, "boom")
+		assert.Equal(t, "applied 075_a\n", out)
+	})
+
+	t.Run("down defaults to one step", func(t *testing.T) {
+		var got int
+		runner.RollbackMigrations = func(_ *gorm.DB, steps int) ([]string, error) {
+			got = steps
+			return []string{"076_b"}, nil
+		}
+		out, err := runCommand(t, newTestEnv(), "", "migrate", "down")
+		require.NoError(t, err)
+		assert.Equal(t, 1, got)
+		assert.Equal(t, "reverted 076_b\n", out)
+
+		_, err = runCommand(t, newTestEnv(), "", "migrate", "down", "3")
+		require.NoError(t, err)
+		assert.Equal(t, 3, got)
+	})
+
+	t.Run("down with invalid steps", func(t *testing.T) {
+		_, err := runCommand(t, newTestEnv(), "", "migrate", "down", "0")
+		assert.EqualError(t, err, "steps must be a positive number: 0")
+	})
+
+	t.Run("status", func(t *testing.T) {
+		appliedAt := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+		runner.MigrationStatus = func(*gorm.DB) ([]runner.MigrationState, error) {
+			return []runner.MigrationState{{Version: "075_a", AppliedAt: &appliedAt}, {Version: "076_b"}}, nil
+		}
+		out, err := runCommand(t, newTestEnv(), "", "migrate", "status")
+		require.NoError(t, err)
+		assert.Contains(t, out, "075_a")
+		assert.Contains(t, out, "2026-10-01T08:00:00Z")
+		assert.Contains(t, out, "076_b    pending")
+	})
+}
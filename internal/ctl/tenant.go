@@ -0,0 +1,76 @@
+package ctl
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/spf13/cobra"
+)
+
+// newCreateTenantCommand returns create-tenant. The first tenant is created
+// by the setup flow, which makes it the system tenant and seeds it; later
+// tenants are created like POST /tenants, optionally under a parent.
+func newCreateTenantCommand(open Opener) *cobra.Command {
+	var req dto.CreateTenantRequestDTO
+	var description, parent string
+	var public bool
+
+	cmd := &cobra.Command{
+		Use:   "create-tenant",
+		Short: "Create a tenant, or the system tenant on a fresh install",
+		Args:  cobra.NoArgs,
+		RunE: withEnv(open, func(cmd *cobra.Command, _ []string, env *Env) error {
+			req.Description = ptr.PtrOrNil(description)
+			if err := req.Validate(); err != nil {
+				return err
+			}
+			var parentUUID *uuid.UUID
+			if parent != "" {
+				id, err := uuid.Parse(parent)
+				if err != nil {
+					return fmt.Errorf("invalid parent tenant id: %s", parent)
+				}
+				parentUUID = &id
+			}
+
+			ctx := cmd.Context()
+			status, err := env.Setup.GetSetupStatus(ctx)
+			if err != nil {
+				return err
+			}
+
+			if !status.IsTenantSetup {
+				if parentUUID != nil {
+					return errors.New("the system tenant cannot have a parent")
+				}
+				created, err := env.Setup.CreateTenant(ctx, req)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "created system tenant %s: id %s, identifier %s\n", created.Tenant.Name, created.Tenant.TenantUUID, created.Tenant.Identifier)
+				return nil
+			}
+
+			created, err := env.Tenant.Create(ctx, req.Name, req.DisplayName, description, model.StatusActive, public, parentUUID)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "created tenant %s: id %s, identifier %s\n", created.Name, created.TenantUUID, created.Identifier)
+			return nil
+		}),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&req.Name, "name", "", "tenant name (required)")
+	flags.StringVar(&req.DisplayName, "display-name", "", "tenant display name (required)")
+	flags.StringVar(&description, "description", "", "tenant description")
+	flags.StringVar(&parent, "parent", "", "id of the parent tenant")
+	flags.BoolVar(&public, "public", false, "make the tenant public")
+	_ = cmd.MarkFlagRequired("name")
+	_ = cmd.MarkFlagRequired("display-name")
+	return cmd
+}
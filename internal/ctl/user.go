@@ -0,0 +1,199 @@
+package ctl
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/spf13/cobra"
+)
+
+// newCreateAdminUserCommand returns create-admin-user, which creates the
+// first admin of the system tenant like the setup flow. The password is read
+// from the first line of stdin so it stays out of the shell history.
+func newCreateAdminUserCommand(open Opener) *cobra.Command {
+	var req dto.CreateAdminRequestDTO
+
+	cmd := &cobra.Command{
+		Use:   "create-admin-user",
+		Short: "Create the first admin user, reading the password from stdin",
+		Args:  cobra.NoArgs,
+		RunE: withEnv(open, func(cmd *cobra.Command, _ []string, env *Env) error {
+			password, err := readPassword(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			req.Password = password
+			if err := req.Validate(); err != nil {
+				return err
+			}
+
+			created, err := env.Setup.CreateAdmin(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "created admin user %s: id %s\n", created.User.Username, created.User.UserUUID)
+			return nil
+		}),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&req.Username, "username", "", "username (required)")
+	flags.StringVar(&req.Fullname, "fullname", "", "full name (required)")
+	flags.StringVar(&req.Email, "email", "", "email address (required)")
+	_ = cmd.MarkFlagRequired("username")
+	_ = cmd.MarkFlagRequired("fullname")
+	_ = cmd.MarkFlagRequired("email")
+	return cmd
+}
+
+// readPassword reads the first line of r without its line ending.
+func readPassword(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("no password on stdin")
+	}
+	return password, nil
+}
+
+// exportedUser is a user as export-users writes it, with the columns of the
+// REST export in the same order.
+type exportedUser struct {
+	UserID             string          `json:"user_id"`
+	Username           string          `json:"username"`
+	Fullname           string          `json:"fullname"`
+	Email              string          `json:"email"`
+	Phone              string          `json:"phone"`
+	Status             string          `json:"status"`
+	IsEmailVerified    bool            `json:"is_email_verified"`
+	IsPhoneVerified    bool            `json:"is_phone_verified"`
+	IsProfileCompleted bool            `json:"is_profile_completed"`
+	IsAccountCompleted bool            `json:"is_account_completed"`
+	Metadata           json.RawMessage `json:"metadata"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+	DeletedAt          *time.Time      `json:"deleted_at"`
+}
+
+// newExportUsersCommand returns export-users, which writes the users of a
+// tenant as NDJSON, one user per line.
+func newExportUsersCommand(open Opener) *cobra.Command {
+	var tenantIdentifier, output string
+	var statuses []string
+
+	cmd := &cobra.Command{
+		Use:   "export-users",
+		Short: "Export the users of a tenant as NDJSON",
+		Args:  cobra.NoArgs,
+		RunE: withEnv(open, func(cmd *cobra.Command, _ []string, env *Env) error {
+			ctx := cmd.Context()
+			tenant, err := resolveTenant(ctx, env.Tenant, tenantIdentifier)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if output != "" {
+				f, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				out = f
+			}
+			buf := bufio.NewWriter(out)
+			enc := json.NewEncoder(buf)
+
+			var count int
+			err = env.User.Export(ctx, service.UserServiceGetFilter{
+				TenantID: tenant.TenantID,
+				Status:   statuses,
+			}, func(u service.UserServiceDataResult) error {
+				count++
+				var metadata json.RawMessage
+				if len(u.Metadata) > 0 {
+					metadata = json.RawMessage(u.Metadata)
+				}
+				return enc.Encode(exportedUser{
+					UserID:             u.UserUUID.String(),
+					Username:           u.Username,
+					Fullname:           u.Fullname,
+					Email:              u.Email,
+					Phone:              u.Phone,
+					Status:             u.Status,
+					IsEmailVerified:    u.IsEmailVerified,
+					IsPhoneVerified:    u.IsPhoneVerified,
+					IsProfileCompleted: u.IsProfileCompleted,
+					IsAccountCompleted: u.IsAccountCompleted,
+					Metadata:           metadata,
+					CreatedAt:          u.CreatedAt,
+					UpdatedAt:          u.UpdatedAt,
+					DeletedAt:          u.DeletedAt,
+				})
+			})
+			if err == nil {
+				err = buf.Flush()
+			}
+			if err != nil {
+				return err
+			}
+			if output != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "exported %d users to %s\n", count, output)
+			}
+			return nil
+		}),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&tenantIdentifier, "tenant", "", "tenant identifier (default: the system tenant)")
+	flags.StringVarP(&output, "output", "o", "", "file to write to (default: stdout)")
+	flags.StringSliceVar(&statuses, "status", nil, "only export users with these statuses")
+	return cmd
+}
+
+// newRevokeSessionsCommand returns revoke-sessions, which signs a user out
+// of every session by revoking their refresh tokens. Access tokens already
+// issued stay valid until they expire.
+func newRevokeSessionsCommand(open Opener) *cobra.Command {
+	var tenantIdentifier string
+
+	cmd := &cobra.Command{
+		Use:   "revoke-sessions <user-id>",
+		Short: "Sign a user out of every session",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			userUUID, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid user id: %s", args[0])
+			}
+			return withEnv(open, func(cmd *cobra.Command, _ []string, env *Env) error {
+				ctx := cmd.Context()
+				tenant, err := resolveTenant(ctx, env.Tenant, tenantIdentifier)
+				if err != nil {
+					return err
+				}
+				revoked, err := env.User.RevokeSessions(ctx, userUUID, tenant.TenantID)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "revoked %d sessions of user %s\n", revoked, userUUID)
+				return nil
+			})(cmd, args)
+		},
+	}
+
+	cmd.Flags().StringVar(&tenantIdentifier, "tenant", "", "tenant identifier (default: the system tenant)")
+	return cmd
+}
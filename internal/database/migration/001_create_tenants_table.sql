@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateTenantTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS tenants (
     tenant_id				SERIAL PRIMARY KEY,
@@ -31,6 +24,6 @@ CREATE INDEX IF NOT EXISTS idx_tenants_is_public ON tenants (is_public);
 CREATE INDEX IF NOT EXISTS idx_tenants_is_system ON tenants (is_system);
 CREATE INDEX IF NOT EXISTS idx_tenants_metadata ON tenants USING GIN (metadata);
 CREATE INDEX IF NOT EXISTS idx_tenants_created_at ON tenants (created_at);
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS tenants;
@@ -1,14 +1,9 @@
-package migration
+-- Creates the user_pools table. A user pool is the isolation boundary for
+-- users, roles, clients, and settings within a single tenant deployment. Each
+-- tenant can have one or more user pools, each representing an independent
+-- application's user namespace — analogous to AWS Cognito User Pools.
 
-import "gorm.io/gorm"
-
-// CreateUserPoolTable creates the user_pools table.
-// A user pool is the isolation boundary for users, roles, clients, and
-// settings within a single tenant deployment. Each tenant can have one or
-// more user pools, each representing an independent application's user
-// namespace — analogous to AWS Cognito User Pools.
-func CreateUserPoolTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 CREATE TABLE IF NOT EXISTS user_pools (
     user_pool_id   BIGSERIAL PRIMARY KEY,
     user_pool_uuid UUID        NOT NULL UNIQUE DEFAULT gen_random_uuid(),
@@ -37,6 +32,6 @@ CREATE INDEX IF NOT EXISTS idx_user_pools_is_default ON user_pools (tenant_id, i
     WHERE is_default = TRUE;
 CREATE INDEX IF NOT EXISTS idx_user_pools_deleted_at ON user_pools (deleted_at)
     WHERE deleted_at IS NULL;
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS user_pools;
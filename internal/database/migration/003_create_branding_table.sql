@@ -1,13 +1,7 @@
-package migration
+-- Creates the branding table for tenant-level UI customisation consumed by
+-- auth-console (port 8080).
 
-import (
-	"gorm.io/gorm"
-)
-
-// CreateBrandingTable creates the branding table for tenant-level UI
-// customisation consumed by auth-console (port 8080).
-func CreateBrandingTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS branding (
     branding_id     SERIAL PRIMARY KEY,
@@ -30,6 +24,7 @@ CREATE TABLE IF NOT EXISTS branding (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -40,12 +35,12 @@ BEGIN
             REFERENCES tenants(tenant_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- CREATE INDEXES
 CREATE INDEX IF NOT EXISTS idx_branding_uuid ON branding (branding_uuid);
 CREATE INDEX IF NOT EXISTS idx_branding_tenant_id ON branding (tenant_id);
 CREATE INDEX IF NOT EXISTS idx_branding_created_at ON branding (created_at);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS branding;
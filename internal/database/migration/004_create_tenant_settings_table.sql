@@ -1,13 +1,7 @@
-package migration
+-- Creates the tenant_settings table for tenant-level operational configuration
+-- (rate limits, audit, maintenance, feature flags).
 
-import (
-	"gorm.io/gorm"
-)
-
-// CreateTenantSettingsTable creates the tenant_settings table for tenant-level
-// operational configuration (rate limits, audit, maintenance, feature flags).
-func CreateTenantSettingsTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS tenant_settings (
     tenant_setting_id   SERIAL PRIMARY KEY,
@@ -22,6 +16,7 @@ CREATE TABLE IF NOT EXISTS tenant_settings (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -32,12 +27,12 @@ BEGIN
             REFERENCES tenants(tenant_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- CREATE INDEXES
 CREATE INDEX IF NOT EXISTS idx_tenant_settings_uuid ON tenant_settings (tenant_setting_uuid);
 CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_settings_tenant_id ON tenant_settings (tenant_id);
 CREATE INDEX IF NOT EXISTS idx_tenant_settings_created_at ON tenant_settings (created_at);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS tenant_settings;
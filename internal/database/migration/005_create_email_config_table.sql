@@ -1,13 +1,7 @@
-package migration
+-- Creates the email_config table for tenant-level SMTP/SES/SendGrid delivery
+-- configuration.
 
-import (
-	"gorm.io/gorm"
-)
-
-// CreateEmailConfigTable creates the email_config table for tenant-level
-// SMTP/SES/SendGrid delivery configuration.
-func CreateEmailConfigTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS email_config (
     email_config_id     SERIAL PRIMARY KEY,
@@ -30,6 +24,7 @@ CREATE TABLE IF NOT EXISTS email_config (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -61,13 +56,13 @@ BEGIN
             ADD CONSTRAINT chk_email_config_provider CHECK (provider IN ('smtp', 'ses', 'sendgrid', 'mailgun', 'postmark', 'resend'));
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- CREATE INDEXES
 CREATE INDEX IF NOT EXISTS idx_email_config_uuid ON email_config (email_config_uuid);
 CREATE INDEX IF NOT EXISTS idx_email_config_tenant_id ON email_config (tenant_id);
 CREATE INDEX IF NOT EXISTS idx_email_config_status ON email_config (status);
 CREATE INDEX IF NOT EXISTS idx_email_config_created_at ON email_config (created_at);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS email_config;
@@ -1,13 +1,7 @@
-package migration
+-- Creates the sms_config table for tenant-level SMS delivery configuration
+-- (Twilio, SNS, Vonage, etc.).
 
-import (
-	"gorm.io/gorm"
-)
-
-// CreateSMSConfigTable creates the sms_config table for tenant-level
-// SMS delivery configuration (Twilio, SNS, Vonage, etc.).
-func CreateSMSConfigTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS sms_config (
     sms_config_id           SERIAL PRIMARY KEY,
@@ -26,6 +20,7 @@ CREATE TABLE IF NOT EXISTS sms_config (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -50,13 +45,13 @@ BEGIN
             ADD CONSTRAINT chk_sms_config_provider CHECK (provider IN ('twilio', 'sns', 'vonage', 'messagebird'));
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- CREATE INDEXES
 CREATE INDEX IF NOT EXISTS idx_sms_config_uuid ON sms_config (sms_config_uuid);
 CREATE INDEX IF NOT EXISTS idx_sms_config_tenant_id ON sms_config (tenant_id);
 CREATE INDEX IF NOT EXISTS idx_sms_config_status ON sms_config (status);
 CREATE INDEX IF NOT EXISTS idx_sms_config_created_at ON sms_config (created_at);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS sms_config;
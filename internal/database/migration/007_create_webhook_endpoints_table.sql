@@ -1,13 +1,7 @@
-package migration
+-- Creates the webhook_endpoints table for tenant-level outbound event
+-- notification subscriptions.
 
-import (
-	"gorm.io/gorm"
-)
-
-// CreateWebhookEndpointsTable creates the webhook_endpoints table for
-// tenant-level outbound event notification subscriptions.
-func CreateWebhookEndpointsTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS webhook_endpoints (
     webhook_endpoint_id     SERIAL PRIMARY KEY,
@@ -27,6 +21,7 @@ CREATE TABLE IF NOT EXISTS webhook_endpoints (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -44,13 +39,13 @@ BEGIN
             ADD CONSTRAINT chk_webhook_endpoints_status CHECK (status IN ('active', 'inactive'));
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- CREATE INDEXES
 CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_uuid ON webhook_endpoints (webhook_endpoint_uuid);
 CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_tenant_id ON webhook_endpoints (tenant_id);
 CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_status ON webhook_endpoints (status);
 CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_created_at ON webhook_endpoints (created_at);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS webhook_endpoints;
@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateServiceTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS services (
     service_id      SERIAL PRIMARY KEY,
@@ -29,6 +22,7 @@ CREATE INDEX IF NOT EXISTS idx_services_is_system ON services (is_system);
 CREATE INDEX IF NOT EXISTS idx_services_created_at ON services (created_at);
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -38,6 +32,7 @@ BEGIN
             CHECK (status IN ('active', 'maintenance', 'deprecated', 'inactive'));
     END IF;
 END$$;
-`
-	return db.Exec(sql).Error
-}
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE IF EXISTS services;
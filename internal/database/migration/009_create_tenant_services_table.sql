@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateTenantServicesTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS tenant_services (
     tenant_service_id   	SERIAL PRIMARY KEY,
@@ -16,6 +9,7 @@ CREATE TABLE IF NOT EXISTS tenant_services (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -34,12 +28,12 @@ BEGIN
             REFERENCES services(service_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_tenant_services_tenant_id ON tenant_services (tenant_id);
 CREATE INDEX IF NOT EXISTS idx_tenant_services_service_id ON tenant_services (service_id);
 CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_services_unique ON tenant_services (tenant_id, service_id);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS tenant_services;
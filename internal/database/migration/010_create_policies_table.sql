@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreatePoliciesTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS policies (
     policy_id       SERIAL PRIMARY KEY,
@@ -22,6 +15,7 @@ CREATE TABLE IF NOT EXISTS policies (
 );
 
 -- ADD CONSTRAINTS (safe)
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -32,6 +26,7 @@ BEGIN
             REFERENCES tenants(tenant_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_policies_policy_uuid ON policies (policy_uuid);
@@ -42,7 +37,6 @@ CREATE INDEX IF NOT EXISTS idx_policies_version ON policies (version);
 CREATE INDEX IF NOT EXISTS idx_policies_status ON policies (status);
 CREATE INDEX IF NOT EXISTS idx_policies_is_system ON policies (is_system);
 CREATE INDEX IF NOT EXISTS idx_policies_created_at ON policies (created_at);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS policies;
@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateServicePoliciesTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS service_policies (
     service_policy_id   	SERIAL PRIMARY KEY,
@@ -16,6 +9,7 @@ CREATE TABLE IF NOT EXISTS service_policies (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -34,12 +28,12 @@ BEGIN
             REFERENCES policies(policy_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_service_policies_uuid ON service_policies (service_policy_uuid);
 CREATE INDEX IF NOT EXISTS idx_service_policies_service_id ON service_policies (service_id);
 CREATE INDEX IF NOT EXISTS idx_service_policies_policy_id ON service_policies (policy_id);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS service_policies;
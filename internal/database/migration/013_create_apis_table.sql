@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateAPITable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS apis (
     api_id					SERIAL PRIMARY KEY,
@@ -24,6 +17,7 @@ CREATE TABLE IF NOT EXISTS apis (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -42,6 +36,7 @@ BEGIN
             REFERENCES tenants(tenant_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_apis_api_uuid ON apis (api_uuid);
@@ -54,6 +49,6 @@ CREATE INDEX IF NOT EXISTS idx_apis_tenant_id ON apis (tenant_id);
 CREATE INDEX IF NOT EXISTS idx_apis_status ON apis (status);
 CREATE INDEX IF NOT EXISTS idx_apis_is_system ON apis (is_system);
 CREATE INDEX IF NOT EXISTS idx_apis_created_at ON apis (created_at);
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS apis;
@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreatePermissionTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS permissions (
     permission_id       SERIAL PRIMARY KEY,
@@ -22,6 +15,7 @@ CREATE TABLE IF NOT EXISTS permissions (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -40,6 +34,7 @@ BEGIN
             REFERENCES apis(api_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_permissions_uuid ON permissions (permission_uuid);
@@ -50,6 +45,6 @@ CREATE INDEX IF NOT EXISTS idx_permissions_status ON permissions (status);
 CREATE INDEX IF NOT EXISTS idx_permissions_is_default ON permissions (is_default);
 CREATE INDEX IF NOT EXISTS idx_permissions_is_system ON permissions (is_system);
 CREATE INDEX IF NOT EXISTS idx_permissions_created_at ON permissions (created_at);
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS permissions;
@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateApiPermissionTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS api_permissions (
     api_permission_id   	SERIAL PRIMARY KEY,
@@ -16,6 +9,7 @@ CREATE TABLE IF NOT EXISTS api_permissions (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -34,12 +28,12 @@ BEGIN
             REFERENCES permissions(permission_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_api_permissions_uuid ON api_permissions (api_permission_uuid);
 CREATE INDEX IF NOT EXISTS idx_api_permissions_api_id ON api_permissions (api_id);
 CREATE INDEX IF NOT EXISTS idx_api_permissions_permission_id ON api_permissions (permission_id);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS api_permissions;
@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateIdentityProviderTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS identity_providers (
     identity_provider_id    SERIAL PRIMARY KEY,
@@ -25,6 +18,7 @@ CREATE TABLE IF NOT EXISTS identity_providers (
 );
 
 -- ADD CONSTRAINTS (safe)
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -35,9 +29,11 @@ BEGIN
             REFERENCES tenants(tenant_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD CHECK CONSTRAINTS FOR DATA INTEGRITY
 
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -48,7 +44,9 @@ BEGIN
             CHECK (provider_type IN ('identity', 'social'));
     END IF;
 END$$;
+-- +goose StatementEnd
 
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -59,6 +57,7 @@ BEGIN
             CHECK (status IN ('active', 'inactive'));
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_identity_providers_uuid ON identity_providers (identity_provider_uuid);
@@ -72,6 +71,6 @@ CREATE INDEX IF NOT EXISTS idx_identity_providers_is_default ON identity_provide
 CREATE INDEX IF NOT EXISTS idx_identity_providers_is_system ON identity_providers (is_system);
 CREATE INDEX IF NOT EXISTS idx_identity_providers_tenant_id ON identity_providers (tenant_id);
 CREATE INDEX IF NOT EXISTS idx_identity_providers_created_at ON identity_providers (created_at);
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS identity_providers;
@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateClientTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS clients (
     client_id          SERIAL PRIMARY KEY,
@@ -41,6 +34,7 @@ CREATE TABLE IF NOT EXISTS clients (
 );
 
 -- ADD CONSTRAINTS (safe)
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -51,7 +45,9 @@ BEGIN
             REFERENCES tenants(tenant_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -62,6 +58,7 @@ BEGIN
             REFERENCES identity_providers(identity_provider_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 -- Composite indexes for common query patterns
@@ -78,6 +75,6 @@ CREATE INDEX IF NOT EXISTS idx_clients_created_at ON clients (created_at);
 
 -- OAuth indexes
 CREATE INDEX IF NOT EXISTS idx_clients_grant_types ON clients USING GIN (grant_types);
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS clients;
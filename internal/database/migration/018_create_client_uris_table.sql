@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateClientURIsTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS client_uris (
     client_uri_id   SERIAL PRIMARY KEY,
@@ -19,6 +12,7 @@ CREATE TABLE IF NOT EXISTS client_uris (
 );
 
 -- ADD CONSTRAINTS (safe)
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -44,6 +38,7 @@ BEGIN
             ADD CONSTRAINT chk_client_uris_type CHECK (type IN ('redirect-uri', 'origin-uri', 'logout-uri', 'login-uri', 'cors-origin-uri'));
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_client_uris_uuid 
@@ -58,6 +53,6 @@ CREATE INDEX IF NOT EXISTS idx_client_uris_type
     ON client_uris (type);
 CREATE INDEX IF NOT EXISTS idx_client_uris_client_id_type 
     ON client_uris (client_id, type);
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS client_uris;
@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateClientAPIsTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS client_apis (
     client_api_id   		SERIAL PRIMARY KEY,
@@ -16,6 +9,7 @@ CREATE TABLE IF NOT EXISTS client_apis (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -42,12 +36,12 @@ BEGIN
             ADD CONSTRAINT uq_client_apis_client_api UNIQUE (client_id, api_id);
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_client_apis_uuid ON client_apis (client_api_uuid);
 CREATE INDEX IF NOT EXISTS idx_client_apis_client_id ON client_apis (client_id);
 CREATE INDEX IF NOT EXISTS idx_client_apis_api_id ON client_apis (api_id);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS client_apis;
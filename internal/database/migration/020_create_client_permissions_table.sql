@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateClientPermissionTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS client_permissions (
     client_permission_id   	SERIAL PRIMARY KEY,
@@ -16,6 +9,7 @@ CREATE TABLE IF NOT EXISTS client_permissions (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -42,12 +36,12 @@ BEGIN
             ADD CONSTRAINT uq_client_permissions_api_permission UNIQUE (client_api_id, permission_id);
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_client_permissions_uuid ON client_permissions (client_permission_uuid);
 CREATE INDEX IF NOT EXISTS idx_client_permissions_client_api_id ON client_permissions (client_api_id);
 CREATE INDEX IF NOT EXISTS idx_client_permissions_permission_id ON client_permissions (permission_id);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS client_permissions;
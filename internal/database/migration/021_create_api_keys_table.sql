@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateAPIKeysTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS api_keys (
     api_key_id              SERIAL PRIMARY KEY,
@@ -24,6 +17,7 @@ CREATE TABLE IF NOT EXISTS api_keys (
 );
 
 -- ADD CONSTRAINTS (safe)
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -34,6 +28,7 @@ BEGIN
             REFERENCES tenants(tenant_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_api_keys_uuid ON api_keys (api_key_uuid);
@@ -44,7 +39,6 @@ CREATE INDEX IF NOT EXISTS idx_api_keys_key_prefix ON api_keys (key_prefix);
 CREATE INDEX IF NOT EXISTS idx_api_keys_status ON api_keys (status);
 CREATE INDEX IF NOT EXISTS idx_api_keys_expires_at ON api_keys (expires_at);
 CREATE INDEX IF NOT EXISTS idx_api_keys_created_at ON api_keys (created_at);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS api_keys;
@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateAPIKeyAPITable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS api_key_apis (
     api_key_api_id   		SERIAL PRIMARY KEY,
@@ -16,6 +9,7 @@ CREATE TABLE IF NOT EXISTS api_key_apis (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -42,12 +36,12 @@ BEGIN
             ADD CONSTRAINT uq_api_key_apis_key_api UNIQUE (api_key_id, api_id);
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_api_key_apis_uuid ON api_key_apis (api_key_api_uuid);
 CREATE INDEX IF NOT EXISTS idx_api_key_apis_api_key_id ON api_key_apis (api_key_id);
 CREATE INDEX IF NOT EXISTS idx_api_key_apis_api_id ON api_key_apis (api_id);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS api_key_apis;
@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateAPIKeyPermissionsTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS api_key_permissions (
     api_key_permission_id   SERIAL PRIMARY KEY,
@@ -16,6 +9,7 @@ CREATE TABLE IF NOT EXISTS api_key_permissions (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -42,13 +36,13 @@ BEGIN
             ADD CONSTRAINT uq_api_key_permissions_api_key_api_permission UNIQUE (api_key_api_id, permission_id);
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_api_key_permissions_uuid ON api_key_permissions (api_key_permission_uuid);
 CREATE INDEX IF NOT EXISTS idx_api_key_permissions_api_key_api_id ON api_key_permissions (api_key_api_id);
 CREATE INDEX IF NOT EXISTS idx_api_key_permissions_permission_id ON api_key_permissions (permission_id);
 CREATE INDEX IF NOT EXISTS idx_api_key_permissions_created_at ON api_key_permissions (created_at);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS api_key_permissions;
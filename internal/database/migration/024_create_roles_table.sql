@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateRoleTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS roles (
     role_id             SERIAL PRIMARY KEY,
@@ -21,6 +14,7 @@ CREATE TABLE IF NOT EXISTS roles (
 );
 
 -- ADD CONSTRAINTS (safe)
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -38,6 +32,7 @@ BEGIN
             ADD CONSTRAINT uq_roles_tenant_id_name UNIQUE (tenant_id, name);
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_roles_role_uuid ON roles (role_uuid);
@@ -48,6 +43,6 @@ CREATE INDEX IF NOT EXISTS idx_roles_is_default ON roles (is_default);
 CREATE INDEX IF NOT EXISTS idx_roles_is_system ON roles (is_system);
 CREATE INDEX IF NOT EXISTS idx_roles_tenant_id ON roles (tenant_id);
 CREATE INDEX IF NOT EXISTS idx_roles_created_at ON roles (created_at);
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS roles;
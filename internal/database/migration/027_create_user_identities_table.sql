@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateUserIdentityTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS user_identities (
     user_identity_id    SERIAL PRIMARY KEY,
@@ -21,6 +14,7 @@ CREATE TABLE IF NOT EXISTS user_identities (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -47,6 +41,7 @@ BEGIN
             REFERENCES tenants(tenant_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_user_identities_uuid ON user_identities (user_identity_uuid);
@@ -56,6 +51,6 @@ CREATE INDEX IF NOT EXISTS idx_user_identities_tenant_id ON user_identities (ten
 CREATE INDEX IF NOT EXISTS idx_user_identities_sub ON user_identities (sub);
 CREATE INDEX IF NOT EXISTS idx_user_identities_provider ON user_identities (provider);
 CREATE INDEX IF NOT EXISTS idx_user_identities_created_at ON user_identities (created_at);
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS user_identities;
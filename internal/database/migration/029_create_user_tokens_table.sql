@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateUserTokenTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS user_tokens (
     user_token_id				SERIAL PRIMARY KEY,
@@ -22,6 +15,7 @@ CREATE TABLE IF NOT EXISTS user_tokens (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -32,6 +26,7 @@ BEGIN
             REFERENCES users(user_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_user_tokens_uuid ON user_tokens (user_token_uuid);
@@ -39,6 +34,6 @@ CREATE INDEX IF NOT EXISTS idx_user_tokens_user_id ON user_tokens (user_id);
 CREATE INDEX IF NOT EXISTS idx_user_tokens_token_type ON user_tokens (token_type);
 CREATE INDEX IF NOT EXISTS idx_user_tokens_token ON user_tokens (token);
 CREATE INDEX IF NOT EXISTS idx_user_tokens_created_at ON user_tokens (created_at);
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS user_tokens;
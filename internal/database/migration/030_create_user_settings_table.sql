@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateUserSettingsTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS user_settings (
     user_setting_id         SERIAL PRIMARY KEY,
@@ -32,6 +25,7 @@ CREATE TABLE IF NOT EXISTS user_settings (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -42,6 +36,7 @@ BEGIN
             REFERENCES users(user_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_user_settings_uuid ON user_settings (user_setting_uuid);
@@ -51,6 +46,7 @@ CREATE INDEX IF NOT EXISTS idx_user_settings_profile_visibility ON user_settings
 CREATE INDEX IF NOT EXISTS idx_user_settings_created_at ON user_settings (created_at);
 
 -- ADD CHECK CONSTRAINTS FOR DATA INTEGRITY
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -61,7 +57,9 @@ BEGIN
             CHECK (preferred_contact_method IN ('email', 'phone', 'sms'));
     END IF;
 END$$;
+-- +goose StatementEnd
 
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -72,6 +70,7 @@ BEGIN
             CHECK (profile_visibility IN ('public', 'private', 'friends'));
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD COMMENTS FOR DOCUMENTATION
 COMMENT ON COLUMN user_settings.social_links IS 'JSON object containing social media links and profiles';
@@ -80,6 +79,6 @@ COMMENT ON COLUMN user_settings.profile_visibility IS 'Profile visibility settin
 COMMENT ON COLUMN user_settings.timezone IS 'User timezone (e.g., America/New_York, Europe/London)';
 COMMENT ON COLUMN user_settings.preferred_language IS 'ISO 639-1 language code (e.g., en, es, fr)';
 COMMENT ON COLUMN user_settings.locale IS 'Locale code (e.g., en_US, es_ES, fr_FR)';
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS user_settings;
@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateProfileTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS profiles (
     profile_id      SERIAL PRIMARY KEY,
@@ -41,6 +34,7 @@ CREATE TABLE IF NOT EXISTS profiles (
 );
 
 -- ADD CONSTRAINTS (safe)
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -51,8 +45,10 @@ BEGIN
             REFERENCES users(user_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD CHECK CONSTRAINTS FOR DATA INTEGRITY
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -63,6 +59,7 @@ BEGIN
             CHECK (gender IN ('male', 'female', 'other', 'prefer_not_to_say'));
     END IF;
 END$$;
+-- +goose StatementEnd
 
 
 
@@ -74,6 +71,6 @@ CREATE INDEX IF NOT EXISTS idx_profiles_last_name ON profiles (last_name);
 CREATE INDEX IF NOT EXISTS idx_profiles_email ON profiles (email);
 CREATE INDEX IF NOT EXISTS idx_profiles_display_name ON profiles (display_name);
 CREATE INDEX IF NOT EXISTS idx_profiles_created_at ON profiles (created_at);
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS profiles;
@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateTenantMembersTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS tenant_members (
     tenant_member_id   SERIAL PRIMARY KEY,
@@ -18,6 +11,7 @@ CREATE TABLE IF NOT EXISTS tenant_members (
 );
 
 -- ADD CHECK CONSTRAINT FOR ROLE
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -28,8 +22,10 @@ BEGIN
             CHECK (role IN ('owner', 'member'));
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD CONSTRAINTS (safe)
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -40,7 +36,9 @@ BEGIN
             REFERENCES tenants(tenant_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -51,8 +49,10 @@ BEGIN
             REFERENCES users(user_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD UNIQUE CONSTRAINT
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -62,12 +62,13 @@ BEGIN
             ADD CONSTRAINT tenant_member_unique UNIQUE (tenant_id, user_id);
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_tenant_members_uuid ON tenant_members (tenant_member_uuid);
 CREATE INDEX IF NOT EXISTS idx_tenant_members_tenant_id ON tenant_members (tenant_id);
 CREATE INDEX IF NOT EXISTS idx_tenant_members_user_id ON tenant_members (user_id);
 CREATE INDEX IF NOT EXISTS idx_tenant_members_created_at ON tenant_members (created_at);
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS tenant_members;
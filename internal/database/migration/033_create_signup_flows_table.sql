@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateSignupFlowTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS signup_flows (
     signup_flow_id   	SERIAL PRIMARY KEY,
@@ -22,6 +15,7 @@ CREATE TABLE IF NOT EXISTS signup_flows (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -40,6 +34,7 @@ BEGIN
             REFERENCES clients(client_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_signup_flow_uuid ON signup_flows (signup_flow_uuid);
@@ -49,6 +44,6 @@ CREATE INDEX IF NOT EXISTS idx_signup_flow_identifier ON signup_flows (identifie
 CREATE INDEX IF NOT EXISTS idx_signup_flow_status ON signup_flows (status);
 CREATE INDEX IF NOT EXISTS idx_signup_flow_client_id ON signup_flows (client_id);
 CREATE INDEX IF NOT EXISTS idx_signup_flow_created_at ON signup_flows (created_at);
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS signup_flows;
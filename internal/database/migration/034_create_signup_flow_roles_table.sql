@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateSignupFlowRoleTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS signup_flow_roles (
     signup_flow_role_id			SERIAL PRIMARY KEY,
@@ -16,6 +9,7 @@ CREATE TABLE IF NOT EXISTS signup_flow_roles (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -34,11 +28,12 @@ BEGIN
             REFERENCES roles(role_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_signup_flow_roles_uuid ON signup_flow_roles (signup_flow_role_uuid);
 CREATE INDEX IF NOT EXISTS idx_signup_flow_roles_signup_flow_id ON signup_flow_roles (signup_flow_id);
 CREATE INDEX IF NOT EXISTS idx_signup_flow_roles_role_id ON signup_flow_roles (role_id);
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS signup_flow_roles;
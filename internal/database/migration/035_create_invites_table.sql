@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateInvitesTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS invites (
     invite_id           SERIAL PRIMARY KEY,
@@ -23,6 +16,7 @@ CREATE TABLE IF NOT EXISTS invites (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -49,6 +43,7 @@ BEGIN
             REFERENCES users(user_id) ON DELETE SET NULL;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_invites_uuid ON invites (invite_uuid);
@@ -59,6 +54,6 @@ CREATE INDEX IF NOT EXISTS idx_invites_invited_by_user_id ON invites (invited_by
 CREATE INDEX IF NOT EXISTS idx_invites_token ON invites (invite_token);
 CREATE INDEX IF NOT EXISTS idx_invites_status ON invites (status);
 CREATE INDEX IF NOT EXISTS idx_invites_created_at ON invites (created_at);
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS invites;
@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateInviteRolesTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS invite_roles (
     invite_role_id			SERIAL PRIMARY KEY,
@@ -15,6 +8,7 @@ CREATE TABLE IF NOT EXISTS invite_roles (
     created_at					TIMESTAMPTZ DEFAULT now()
 );
 
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -33,11 +27,12 @@ BEGIN
             REFERENCES roles(role_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_invite_roles_invite_role_uuid ON invite_roles (invite_role_uuid);
 CREATE INDEX IF NOT EXISTS idx_invite_roles_invite_id ON invite_roles (invite_id);
 CREATE INDEX IF NOT EXISTS idx_invite_roles_role_id ON invite_roles (role_id);
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS invite_roles;
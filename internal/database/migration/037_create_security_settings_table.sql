@@ -1,14 +1,8 @@
-package migration
+-- Creates the security_settings table scoped to a user pool. Each pool gets
+-- exactly one row that holds JSONB configs for MFA, passwords, sessions, threat
+-- detection, lockout, registration, and tokens.
 
-import (
-	"gorm.io/gorm"
-)
-
-// CreateSecuritySettingsTable creates the security_settings table scoped to a
-// user pool. Each pool gets exactly one row that holds JSONB configs for MFA,
-// passwords, sessions, threat detection, lockout, registration, and tokens.
-func CreateSecuritySettingsTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS security_settings (
     security_setting_id     SERIAL PRIMARY KEY,
@@ -29,6 +23,7 @@ CREATE TABLE IF NOT EXISTS security_settings (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -55,13 +50,13 @@ BEGIN
             REFERENCES users(user_id) ON DELETE SET NULL;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- CREATE INDEXES
 CREATE INDEX IF NOT EXISTS idx_security_settings_uuid ON security_settings (security_setting_uuid);
 CREATE INDEX IF NOT EXISTS idx_security_settings_user_pool_id ON security_settings (user_pool_id);
 CREATE INDEX IF NOT EXISTS idx_security_settings_version ON security_settings (version);
 CREATE INDEX IF NOT EXISTS idx_security_settings_created_at ON security_settings (created_at);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS security_settings;
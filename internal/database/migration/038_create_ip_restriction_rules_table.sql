@@ -1,13 +1,7 @@
-package migration
+-- Creates the ip_restriction_rules table and its associated indexes and
+-- constraints.
 
-import (
-	"gorm.io/gorm"
-)
-
-// CreateIPRestrictionRulesTable creates the ip_restriction_rules table and its
-// associated indexes and constraints.
-func CreateIPRestrictionRulesTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS ip_restriction_rules (
     ip_restriction_rule_id   SERIAL PRIMARY KEY,
@@ -24,6 +18,7 @@ CREATE TABLE IF NOT EXISTS ip_restriction_rules (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -64,6 +59,7 @@ BEGIN
             REFERENCES users(user_id) ON DELETE SET NULL;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- CREATE INDEXES
 CREATE INDEX IF NOT EXISTS idx_ip_restriction_rules_uuid ON ip_restriction_rules (ip_restriction_rule_uuid);
@@ -72,7 +68,6 @@ CREATE INDEX IF NOT EXISTS idx_ip_restriction_rules_type ON ip_restriction_rules
 CREATE INDEX IF NOT EXISTS idx_ip_restriction_rules_status ON ip_restriction_rules (status);
 CREATE INDEX IF NOT EXISTS idx_ip_restriction_rules_ip_address ON ip_restriction_rules (ip_address);
 CREATE INDEX IF NOT EXISTS idx_ip_restriction_rules_created_at ON ip_restriction_rules (created_at);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS ip_restriction_rules;
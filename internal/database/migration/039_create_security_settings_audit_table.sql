@@ -1,13 +1,7 @@
-package migration
+-- Creates the security_settings_audit table for tracking changes to pool-level
+-- security configuration.
 
-import (
-	"gorm.io/gorm"
-)
-
-// CreateSecuritySettingsAuditTable creates the security_settings_audit table
-// for tracking changes to pool-level security configuration.
-func CreateSecuritySettingsAuditTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS security_settings_audit (
     security_settings_audit_id   SERIAL PRIMARY KEY,
@@ -25,6 +19,7 @@ CREATE TABLE IF NOT EXISTS security_settings_audit (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -51,6 +46,7 @@ BEGIN
             REFERENCES users(user_id) ON DELETE SET NULL;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- CREATE INDEXES
 CREATE INDEX IF NOT EXISTS idx_security_settings_audit_uuid ON security_settings_audit (security_settings_audit_uuid);
@@ -59,7 +55,6 @@ CREATE INDEX IF NOT EXISTS idx_security_settings_audit_security_setting_id ON se
 CREATE INDEX IF NOT EXISTS idx_security_settings_audit_change_type ON security_settings_audit (change_type);
 CREATE INDEX IF NOT EXISTS idx_security_settings_audit_created_by ON security_settings_audit (created_by);
 CREATE INDEX IF NOT EXISTS idx_security_settings_audit_created_at ON security_settings_audit (created_at);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS security_settings_audit;
@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateLoginTemplatesTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS login_templates (
     login_template_id   SERIAL PRIMARY KEY,
@@ -23,6 +16,7 @@ CREATE TABLE IF NOT EXISTS login_templates (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -47,6 +41,7 @@ BEGIN
             ADD CONSTRAINT chk_login_templates_template CHECK (template IN ('modern', 'classic', 'minimal', 'corporate', 'creative', 'custom'));
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- CREATE INDEXES
 CREATE INDEX IF NOT EXISTS idx_login_templates_uuid ON login_templates (login_template_uuid);
@@ -56,7 +51,6 @@ CREATE INDEX IF NOT EXISTS idx_login_templates_status ON login_templates (status
 CREATE INDEX IF NOT EXISTS idx_login_templates_is_default ON login_templates (is_default);
 CREATE INDEX IF NOT EXISTS idx_login_templates_is_system ON login_templates (is_system);
 CREATE INDEX IF NOT EXISTS idx_login_templates_created_at ON login_templates (created_at);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS login_templates;
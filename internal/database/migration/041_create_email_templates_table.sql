@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateEmailTemplatesTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS email_templates (
     email_template_id     SERIAL PRIMARY KEY,
@@ -24,6 +17,7 @@ CREATE TABLE IF NOT EXISTS email_templates (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -34,6 +28,7 @@ BEGIN
             REFERENCES tenants(tenant_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- CREATE INDEXES
 CREATE INDEX IF NOT EXISTS idx_email_templates_uuid ON email_templates (email_template_uuid);
@@ -43,7 +38,6 @@ CREATE INDEX IF NOT EXISTS idx_email_templates_status ON email_templates (status
 CREATE INDEX IF NOT EXISTS idx_email_templates_is_default ON email_templates (is_default);
 CREATE INDEX IF NOT EXISTS idx_email_templates_is_system ON email_templates (is_system);
 CREATE INDEX IF NOT EXISTS idx_email_templates_created_at ON email_templates (created_at);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS email_templates;
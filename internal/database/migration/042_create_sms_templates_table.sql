@@ -1,11 +1,4 @@
-package migration
-
-import (
-	"gorm.io/gorm"
-)
-
-func CreateSMSTemplatesTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS sms_templates (
     sms_template_id   SERIAL PRIMARY KEY,
@@ -24,6 +17,7 @@ CREATE TABLE IF NOT EXISTS sms_templates (
 );
 
 -- ADD CONSTRAINTS
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -33,8 +27,10 @@ BEGIN
             ADD CONSTRAINT chk_sms_templates_status CHECK (status IN ('active', 'inactive'));
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD FOREIGN KEY CONSTRAINT
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -45,6 +41,7 @@ BEGIN
             FOREIGN KEY (tenant_id) REFERENCES tenants(tenant_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- CREATE INDEXES
 CREATE INDEX IF NOT EXISTS idx_sms_templates_uuid ON sms_templates (sms_template_uuid);
@@ -55,7 +52,6 @@ CREATE INDEX IF NOT EXISTS idx_sms_templates_sender_id ON sms_templates (sender_
 CREATE INDEX IF NOT EXISTS idx_sms_templates_is_default ON sms_templates (is_default);
 CREATE INDEX IF NOT EXISTS idx_sms_templates_is_system ON sms_templates (is_system);
 CREATE INDEX IF NOT EXISTS idx_sms_templates_created_at ON sms_templates (created_at);
-`
 
-	return db.Exec(sql).Error
-}
+-- +goose Down
+DROP TABLE IF EXISTS sms_templates;
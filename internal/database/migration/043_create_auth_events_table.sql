@@ -1,14 +1,8 @@
-package migration
+-- Creates the auth_events table which stores security events following the
+-- OWASP Logging Vocabulary standard. This replaces the former auth_logs table
+-- with a standards-compliant schema.
 
-import (
-	"gorm.io/gorm"
-)
-
-// CreateAuthEventsTable creates the auth_events table which stores security
-// events following the OWASP Logging Vocabulary standard. This replaces the
-// former auth_logs table with a standards-compliant schema.
-func CreateAuthEventsTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS auth_events (
     auth_event_id     BIGSERIAL     PRIMARY KEY,
@@ -49,6 +43,7 @@ CREATE TABLE IF NOT EXISTS auth_events (
 );
 
 -- ADD CONSTRAINTS (FOREIGN KEYS)
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -75,6 +70,7 @@ BEGIN
             REFERENCES users(user_id) ON DELETE SET NULL;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- PRIMARY QUERY PATTERN INDEXES
 CREATE INDEX IF NOT EXISTS idx_auth_events_tenant_created ON auth_events (tenant_id, created_at DESC);
@@ -87,6 +83,6 @@ CREATE INDEX IF NOT EXISTS idx_auth_events_failures ON auth_events (result, crea
     WHERE result = 'failure';
 CREATE INDEX IF NOT EXISTS idx_auth_events_critical ON auth_events (severity, created_at DESC)
     WHERE severity IN ('WARN', 'CRITICAL');
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS auth_events;
@@ -1,14 +1,8 @@
-package migration
+-- Creates the oauth_authorization_codes table which stores pending
+-- authorization codes. Codes are short-lived (10 minutes), single-use, and
+-- bound to a PKCE challenge.
 
-import (
-	"gorm.io/gorm"
-)
-
-// CreateOAuthAuthorizationCodesTable creates the oauth_authorization_codes
-// table which stores pending authorization codes. Codes are short-lived
-// (10 minutes), single-use, and bound to a PKCE challenge.
-func CreateOAuthAuthorizationCodesTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS oauth_authorization_codes (
     oauth_authorization_code_id   BIGSERIAL     PRIMARY KEY,
@@ -32,6 +26,7 @@ CREATE TABLE IF NOT EXISTS oauth_authorization_codes (
 );
 
 -- ADD CONSTRAINTS (FOREIGN KEYS)
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -58,11 +53,12 @@ BEGIN
             REFERENCES tenants(tenant_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_oauth_auth_codes_code_hash ON oauth_authorization_codes (code_hash);
 CREATE INDEX IF NOT EXISTS idx_oauth_auth_codes_expires ON oauth_authorization_codes (expires_at);
 CREATE INDEX IF NOT EXISTS idx_oauth_auth_codes_client_user ON oauth_authorization_codes (client_id, user_id);
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS oauth_authorization_codes;
@@ -1,13 +1,7 @@
-package migration
+-- Creates the oauth_refresh_tokens table which stores refresh tokens with
+-- family tracking for rotation and reuse detection.
 
-import (
-	"gorm.io/gorm"
-)
-
-// CreateOAuthRefreshTokensTable creates the oauth_refresh_tokens table which
-// stores refresh tokens with family tracking for rotation and reuse detection.
-func CreateOAuthRefreshTokensTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS oauth_refresh_tokens (
     oauth_refresh_token_id   BIGSERIAL     PRIMARY KEY,
@@ -31,6 +25,7 @@ CREATE TABLE IF NOT EXISTS oauth_refresh_tokens (
 );
 
 -- ADD CONSTRAINTS (FOREIGN KEYS)
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -57,6 +52,7 @@ BEGIN
             REFERENCES tenants(tenant_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_oauth_refresh_token_hash ON oauth_refresh_tokens (token_hash);
@@ -64,6 +60,6 @@ CREATE INDEX IF NOT EXISTS idx_oauth_refresh_family ON oauth_refresh_tokens (fam
 CREATE INDEX IF NOT EXISTS idx_oauth_refresh_user_client ON oauth_refresh_tokens (user_id, client_id);
 CREATE INDEX IF NOT EXISTS idx_oauth_refresh_expires ON oauth_refresh_tokens (expires_at);
 CREATE INDEX IF NOT EXISTS idx_oauth_refresh_revoked ON oauth_refresh_tokens (is_revoked) WHERE is_revoked = FALSE;
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS oauth_refresh_tokens;
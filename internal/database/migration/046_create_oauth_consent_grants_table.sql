@@ -1,14 +1,8 @@
-package migration
+-- Creates the oauth_consent_grants table which stores user consent decisions
+-- per client. Each user-client pair has at most one row tracking the space-
+-- delimited scopes the user has approved.
 
-import (
-	"gorm.io/gorm"
-)
-
-// CreateOAuthConsentGrantsTable creates the oauth_consent_grants table which
-// stores user consent decisions per client. Each user-client pair has at most
-// one row tracking the space-delimited scopes the user has approved.
-func CreateOAuthConsentGrantsTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS oauth_consent_grants (
     oauth_consent_grant_id   BIGSERIAL     PRIMARY KEY,
@@ -24,6 +18,7 @@ CREATE TABLE IF NOT EXISTS oauth_consent_grants (
 );
 
 -- ADD CONSTRAINTS (FOREIGN KEYS)
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -50,10 +45,11 @@ BEGIN
             REFERENCES tenants(tenant_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_oauth_consent_grants_user ON oauth_consent_grants (user_id);
 CREATE INDEX IF NOT EXISTS idx_oauth_consent_grants_client ON oauth_consent_grants (client_id);
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS oauth_consent_grants;
@@ -1,15 +1,9 @@
-package migration
+-- Creates the oauth_consent_challenges table which stores pending consent
+-- challenges. Created when the authorization endpoint determines consent is
+-- needed, consumed when the frontend submits the user's decision. Short-lived
+-- (10 minutes).
 
-import (
-	"gorm.io/gorm"
-)
-
-// CreateOAuthConsentChallengesTable creates the oauth_consent_challenges table
-// which stores pending consent challenges. Created when the authorization
-// endpoint determines consent is needed, consumed when the frontend submits
-// the user's decision. Short-lived (10 minutes).
-func CreateOAuthConsentChallengesTable(db *gorm.DB) error {
-	sql := `
+-- +goose Up
 -- CREATE TABLE
 CREATE TABLE IF NOT EXISTS oauth_consent_challenges (
     oauth_consent_challenge_id   BIGSERIAL     PRIMARY KEY,
@@ -31,6 +25,7 @@ CREATE TABLE IF NOT EXISTS oauth_consent_challenges (
 );
 
 -- ADD CONSTRAINTS (FOREIGN KEYS)
+-- +goose StatementBegin
 DO $$
 BEGIN
     IF NOT EXISTS (
@@ -57,10 +52,11 @@ BEGIN
             REFERENCES tenants(tenant_id) ON DELETE CASCADE;
     END IF;
 END$$;
+-- +goose StatementEnd
 
 -- ADD INDEXES
 CREATE INDEX IF NOT EXISTS idx_oauth_consent_challenges_uuid ON oauth_consent_challenges (oauth_consent_challenge_uuid);
 CREATE INDEX IF NOT EXISTS idx_oauth_consent_challenges_expires ON oauth_consent_challenges (expires_at);
-`
-	return db.Exec(sql).Error
-}
+
+-- +goose Down
+DROP TABLE IF EXISTS oauth_consent_challenges;
@@ -0,0 +1,68 @@
+-- Adds the deprecation and sunset lifecycle columns to the clients and apis
+-- tables. A deprecated record keeps issuing tokens with warning headers until
+-- sunset_at, after which issuance is refused. Usage of deprecated records is
+-- counted so admins can see who still depends on them.
+
+-- +goose Up
+-- ALTER TABLES
+ALTER TABLE clients
+    ADD COLUMN IF NOT EXISTS deprecated_at          TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS sunset_at              TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS deprecation_notice     TEXT,
+    ADD COLUMN IF NOT EXISTS deprecated_usage_count BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_deprecated_use_at TIMESTAMPTZ;
+
+ALTER TABLE apis
+    ADD COLUMN IF NOT EXISTS deprecated_at          TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS sunset_at              TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS deprecation_notice     TEXT,
+    ADD COLUMN IF NOT EXISTS deprecated_usage_count BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_deprecated_use_at TIMESTAMPTZ;
+
+-- ADD CONSTRAINTS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'chk_clients_sunset_requires_deprecation'
+    ) THEN
+        ALTER TABLE clients
+            ADD CONSTRAINT chk_clients_sunset_requires_deprecation
+            CHECK (sunset_at IS NULL OR deprecated_at IS NOT NULL);
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'chk_apis_sunset_requires_deprecation'
+    ) THEN
+        ALTER TABLE apis
+            ADD CONSTRAINT chk_apis_sunset_requires_deprecation
+            CHECK (sunset_at IS NULL OR deprecated_at IS NOT NULL);
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- ADD INDEXES
+CREATE INDEX IF NOT EXISTS idx_clients_deprecated_at ON clients (deprecated_at) WHERE deprecated_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_apis_deprecated_at ON apis (deprecated_at) WHERE deprecated_at IS NOT NULL;
+
+-- +goose Down
+-- DROP INDEXES
+DROP INDEX IF EXISTS idx_clients_deprecated_at;
+DROP INDEX IF EXISTS idx_apis_deprecated_at;
+
+-- ALTER TABLES
+ALTER TABLE clients
+    DROP CONSTRAINT IF EXISTS chk_clients_sunset_requires_deprecation,
+    DROP COLUMN IF EXISTS deprecated_at,
+    DROP COLUMN IF EXISTS sunset_at,
+    DROP COLUMN IF EXISTS deprecation_notice,
+    DROP COLUMN IF EXISTS deprecated_usage_count,
+    DROP COLUMN IF EXISTS last_deprecated_use_at;
+
+ALTER TABLE apis
+    DROP CONSTRAINT IF EXISTS chk_apis_sunset_requires_deprecation,
+    DROP COLUMN IF EXISTS deprecated_at,
+    DROP COLUMN IF EXISTS sunset_at,
+    DROP COLUMN IF EXISTS deprecation_notice,
+    DROP COLUMN IF EXISTS deprecated_usage_count,
+    DROP COLUMN IF EXISTS last_deprecated_use_at;
@@ -0,0 +1,13 @@
+-- Adds the user_metadata_schema column to tenant_settings. It holds a tenant-
+-- defined JSON Schema that user metadata must satisfy; an empty object leaves
+-- metadata unconstrained.
+
+-- +goose Up
+-- ALTER TABLES
+ALTER TABLE tenant_settings
+    ADD COLUMN IF NOT EXISTS user_metadata_schema JSONB NOT NULL DEFAULT '{}';
+
+-- +goose Down
+-- ALTER TABLES
+ALTER TABLE tenant_settings
+    DROP COLUMN IF EXISTS user_metadata_schema;
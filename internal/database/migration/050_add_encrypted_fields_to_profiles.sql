@@ -0,0 +1,29 @@
+-- Adds ciphertext and blind index columns for the sensitive profile fields
+-- (birthdate, phone, address). When a tenant enables profile encryption the
+-- plaintext columns are left NULL and the values are stored here instead.
+
+-- +goose Up
+-- ALTER TABLES
+ALTER TABLE profiles
+    ADD COLUMN IF NOT EXISTS birthdate_encrypted TEXT,
+    ADD COLUMN IF NOT EXISTS phone_encrypted     TEXT,
+    ADD COLUMN IF NOT EXISTS address_encrypted   TEXT,
+    ADD COLUMN IF NOT EXISTS birthdate_bidx      VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS phone_bidx          VARCHAR(64);
+
+-- ADD INDEXES
+CREATE INDEX IF NOT EXISTS idx_profiles_birthdate_bidx ON profiles (birthdate_bidx) WHERE birthdate_bidx IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_profiles_phone_bidx ON profiles (phone_bidx) WHERE phone_bidx IS NOT NULL;
+
+-- +goose Down
+-- DROP INDEXES
+DROP INDEX IF EXISTS idx_profiles_birthdate_bidx;
+DROP INDEX IF EXISTS idx_profiles_phone_bidx;
+
+-- ALTER TABLES
+ALTER TABLE profiles
+    DROP COLUMN IF EXISTS birthdate_encrypted,
+    DROP COLUMN IF EXISTS phone_encrypted,
+    DROP COLUMN IF EXISTS address_encrypted,
+    DROP COLUMN IF EXISTS birthdate_bidx,
+    DROP COLUMN IF EXISTS phone_bidx;
@@ -0,0 +1,45 @@
+-- Creates the events table, an append-only feed of user, role and client
+-- lifecycle events. event_id is a monotonically increasing sequence that
+-- downstream consumers use as a resumable cursor.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS events (
+    event_id          BIGSERIAL     PRIMARY KEY,
+    event_uuid        UUID          NOT NULL DEFAULT gen_random_uuid() UNIQUE,
+    tenant_id         BIGINT        NOT NULL,
+    event_type        VARCHAR(60)   NOT NULL,
+    schema_version    INTEGER       NOT NULL DEFAULT 1,
+    aggregate_type    VARCHAR(30)   NOT NULL,
+    aggregate_uuid    UUID          NOT NULL,
+    payload           JSONB         NOT NULL DEFAULT '{}',
+
+    -- WHEN  (immutable — no updated_at)
+    occurred_at       TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+
+    -- CONSTRAINTS
+    CONSTRAINT chk_events_aggregate_type CHECK (aggregate_type IN (
+        'user', 'role', 'client'
+    ))
+);
+
+-- ADD CONSTRAINTS (FOREIGN KEYS)
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_events_tenant_id'
+    ) THEN
+        ALTER TABLE events
+            ADD CONSTRAINT fk_events_tenant_id FOREIGN KEY (tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE CASCADE;
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- ADD INDEXES
+CREATE INDEX IF NOT EXISTS idx_events_tenant_cursor ON events (tenant_id, event_id);
+CREATE INDEX IF NOT EXISTS idx_events_aggregate ON events (aggregate_type, aggregate_uuid, event_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS events;
@@ -0,0 +1,72 @@
+-- Creates the login_hooks table for tenant-defined custom actions run before
+-- login, after login and before registration.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS login_hooks (
+    login_hook_id       SERIAL PRIMARY KEY,
+    login_hook_uuid     UUID NOT NULL UNIQUE,
+    tenant_id           INTEGER NOT NULL,
+    name                VARCHAR(100) NOT NULL,
+    trigger             VARCHAR(30) NOT NULL,
+    type                VARCHAR(20) NOT NULL,
+    url                 TEXT,
+    secret_encrypted    TEXT,
+    rule                JSONB,
+    timeout_ms          INTEGER NOT NULL DEFAULT 2000,
+    failure_policy      VARCHAR(20) NOT NULL DEFAULT 'fail_closed',
+    priority            INTEGER NOT NULL DEFAULT 0,
+    status              VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at          TIMESTAMPTZ DEFAULT now(),
+    updated_at          TIMESTAMPTZ DEFAULT now()
+);
+
+-- ADD CONSTRAINTS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_login_hooks_tenant_id'
+    ) THEN
+        ALTER TABLE login_hooks
+            ADD CONSTRAINT fk_login_hooks_tenant_id FOREIGN KEY (tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'chk_login_hooks_trigger'
+    ) THEN
+        ALTER TABLE login_hooks
+            ADD CONSTRAINT chk_login_hooks_trigger CHECK (trigger IN ('pre_login', 'post_login', 'pre_registration'));
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'chk_login_hooks_type'
+    ) THEN
+        ALTER TABLE login_hooks
+            ADD CONSTRAINT chk_login_hooks_type CHECK (type IN ('webhook', 'rule'));
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'chk_login_hooks_failure_policy'
+    ) THEN
+        ALTER TABLE login_hooks
+            ADD CONSTRAINT chk_login_hooks_failure_policy CHECK (failure_policy IN ('fail_open', 'fail_closed'));
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'chk_login_hooks_status'
+    ) THEN
+        ALTER TABLE login_hooks
+            ADD CONSTRAINT chk_login_hooks_status CHECK (status IN ('active', 'inactive'));
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- CREATE INDEXES
+CREATE INDEX IF NOT EXISTS idx_login_hooks_uuid ON login_hooks (login_hook_uuid);
+CREATE INDEX IF NOT EXISTS idx_login_hooks_tenant_trigger ON login_hooks (tenant_id, trigger, status, priority);
+CREATE INDEX IF NOT EXISTS idx_login_hooks_created_at ON login_hooks (created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS login_hooks;
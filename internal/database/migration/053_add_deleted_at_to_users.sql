@@ -0,0 +1,20 @@
+-- Adds the deleted_at column to users. A deleted user keeps its row, with
+-- status 'deleted', until the purge job removes it once the tenant's retention
+-- period has passed.
+
+-- +goose Up
+-- ALTER TABLES
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- ADD INDEXES
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at)
+    WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+-- DROP INDEXES
+DROP INDEX IF EXISTS idx_users_deleted_at;
+
+-- ALTER TABLES
+ALTER TABLE users
+    DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,74 @@
+-- Creates the webhook_deliveries table that queues events for outbound webhook
+-- endpoints and records each delivery attempt, and adds the dispatcher's event
+-- log positions to webhook_endpoints.
+
+-- +goose Up
+-- ALTER TABLES
+ALTER TABLE webhook_endpoints
+    ADD COLUMN IF NOT EXISTS last_event_id BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_auth_event_id BIGINT NOT NULL DEFAULT 0;
+
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    webhook_delivery_id     BIGSERIAL PRIMARY KEY,
+    webhook_delivery_uuid   UUID NOT NULL UNIQUE,
+    tenant_id               INTEGER NOT NULL,
+    webhook_endpoint_id     INTEGER NOT NULL,
+    event_uuid              UUID NOT NULL,
+    event_type              VARCHAR(60) NOT NULL,
+    payload                 JSONB NOT NULL,
+    status                  VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts                INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at         TIMESTAMPTZ,
+    last_attempt_at         TIMESTAMPTZ,
+    last_status_code        INTEGER,
+    last_error              TEXT,
+    last_duration_ms        BIGINT,
+    delivered_at            TIMESTAMPTZ,
+    created_at              TIMESTAMPTZ DEFAULT now(),
+    updated_at              TIMESTAMPTZ DEFAULT now()
+);
+
+-- ADD CONSTRAINTS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_webhook_deliveries_tenant_id'
+    ) THEN
+        ALTER TABLE webhook_deliveries
+            ADD CONSTRAINT fk_webhook_deliveries_tenant_id FOREIGN KEY (tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_webhook_deliveries_webhook_endpoint_id'
+    ) THEN
+        ALTER TABLE webhook_deliveries
+            ADD CONSTRAINT fk_webhook_deliveries_webhook_endpoint_id FOREIGN KEY (webhook_endpoint_id)
+            REFERENCES webhook_endpoints(webhook_endpoint_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'chk_webhook_deliveries_status'
+    ) THEN
+        ALTER TABLE webhook_deliveries
+            ADD CONSTRAINT chk_webhook_deliveries_status CHECK (status IN ('pending', 'succeeded', 'failed'));
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- CREATE INDEXES
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint_event ON webhook_deliveries (webhook_endpoint_id, event_uuid);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at)
+    WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint_created ON webhook_deliveries (webhook_endpoint_id, created_at DESC);
+
+-- +goose Down
+-- DROP TABLE
+DROP TABLE IF EXISTS webhook_deliveries;
+
+-- ALTER TABLES
+ALTER TABLE webhook_endpoints
+    DROP COLUMN IF EXISTS last_event_id,
+    DROP COLUMN IF EXISTS last_auth_event_id;
@@ -0,0 +1,16 @@
+-- Adds the columns that let a webhook endpoint keep signing with its previous
+-- secret for a grace period after the secret is rotated.
+
+-- +goose Up
+-- ALTER TABLES
+ALTER TABLE webhook_endpoints
+    ADD COLUMN IF NOT EXISTS previous_secret_encrypted TEXT,
+    ADD COLUMN IF NOT EXISTS previous_secret_expires_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS secret_rotated_at TIMESTAMPTZ;
+
+-- +goose Down
+-- ALTER TABLES
+ALTER TABLE webhook_endpoints
+    DROP COLUMN IF EXISTS previous_secret_encrypted,
+    DROP COLUMN IF EXISTS previous_secret_expires_at,
+    DROP COLUMN IF EXISTS secret_rotated_at;
@@ -0,0 +1,19 @@
+-- Creates the event_relay_cursors table, which records how far each event relay
+-- has published the events table to the event bus. A relay locks its row while
+-- it publishes, so only one instance relays at a time.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS event_relay_cursors (
+    event_relay_cursor_id     BIGSERIAL     PRIMARY KEY,
+    event_relay_cursor_uuid   UUID          NOT NULL DEFAULT gen_random_uuid() UNIQUE,
+    name                      VARCHAR(100)  NOT NULL UNIQUE,
+    last_event_id             BIGINT        NOT NULL DEFAULT 0,
+
+    -- WHEN
+    created_at                TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at                TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS event_relay_cursors;
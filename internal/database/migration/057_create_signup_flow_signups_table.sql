@@ -0,0 +1,45 @@
+-- Creates the signup_flow_signups table, which records every account created
+-- through a signup flow so that the flow's caps can be enforced. Rows are kept
+-- when the user is deleted.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS signup_flow_signups (
+    signup_flow_signup_id     BIGSERIAL     PRIMARY KEY,
+    signup_flow_signup_uuid   UUID          NOT NULL UNIQUE,
+    signup_flow_id            INTEGER       NOT NULL,
+    user_id                   INTEGER,
+    is_invite                 BOOLEAN       NOT NULL DEFAULT FALSE,
+
+    -- WHEN
+    created_at                TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+-- ADD CONSTRAINTS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_signup_flow_signups_signup_flow_id'
+    ) THEN
+        ALTER TABLE signup_flow_signups
+            ADD CONSTRAINT fk_signup_flow_signups_signup_flow_id FOREIGN KEY (signup_flow_id)
+            REFERENCES signup_flows(signup_flow_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_signup_flow_signups_user_id'
+    ) THEN
+        ALTER TABLE signup_flow_signups
+            ADD CONSTRAINT fk_signup_flow_signups_user_id FOREIGN KEY (user_id)
+            REFERENCES users(user_id) ON DELETE SET NULL;
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- ADD INDEXES
+CREATE INDEX IF NOT EXISTS idx_signup_flow_signups_signup_flow_id_created_at ON signup_flow_signups (signup_flow_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_signup_flow_signups_user_id ON signup_flow_signups (user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS signup_flow_signups;
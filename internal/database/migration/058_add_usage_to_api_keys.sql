@@ -0,0 +1,14 @@
+-- Adds usage tracking to api_keys. Requests are counted in Redis and added to
+-- usage_count, together with last_used_at, by the usage flush job.
+
+-- +goose Up
+-- ALTER TABLES
+ALTER TABLE api_keys
+    ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS usage_count BIGINT NOT NULL DEFAULT 0;
+
+-- +goose Down
+-- ALTER TABLES
+ALTER TABLE api_keys
+    DROP COLUMN IF EXISTS last_used_at,
+    DROP COLUMN IF EXISTS usage_count;
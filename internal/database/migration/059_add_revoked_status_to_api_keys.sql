@@ -0,0 +1,14 @@
+-- Allows the terminal "revoked" status on api_keys. A revoked key can no longer
+-- be updated or reactivated.
+
+-- +goose Up
+-- ALTER CONSTRAINTS
+ALTER TABLE api_keys DROP CONSTRAINT IF EXISTS api_keys_status_check;
+ALTER TABLE api_keys
+    ADD CONSTRAINT api_keys_status_check CHECK (status IN ('active', 'inactive', 'revoked'));
+
+-- +goose Down
+-- ALTER CONSTRAINTS
+ALTER TABLE api_keys DROP CONSTRAINT IF EXISTS api_keys_status_check;
+ALTER TABLE api_keys
+    ADD CONSTRAINT api_keys_status_check CHECK (status IN ('active', 'inactive'));
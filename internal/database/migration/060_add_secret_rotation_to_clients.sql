@@ -0,0 +1,16 @@
+-- Adds the columns that let an auth client keep authenticating with its
+-- previous secret for a grace period after the secret is rotated.
+
+-- +goose Up
+-- ALTER TABLES
+ALTER TABLE clients
+    ADD COLUMN IF NOT EXISTS previous_secret TEXT,
+    ADD COLUMN IF NOT EXISTS previous_secret_expires_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS secret_rotated_at TIMESTAMPTZ;
+
+-- +goose Down
+-- ALTER TABLES
+ALTER TABLE clients
+    DROP COLUMN IF EXISTS previous_secret,
+    DROP COLUMN IF EXISTS previous_secret_expires_at,
+    DROP COLUMN IF EXISTS secret_rotated_at;
@@ -0,0 +1,52 @@
+-- Creates the personal_access_tokens table. Each token belongs to one user and
+-- carries the subset of that user's permissions it may exercise. Only a hash of
+-- the token is stored.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+    personal_access_token_id     BIGSERIAL      PRIMARY KEY,
+    personal_access_token_uuid   UUID           NOT NULL UNIQUE,
+    tenant_id                    INTEGER        NOT NULL,
+    user_id                      INTEGER        NOT NULL,
+    name                         VARCHAR(100)   NOT NULL,
+    token_hash                   TEXT           NOT NULL UNIQUE,
+    token_prefix                 VARCHAR(20)    NOT NULL,
+    scopes                       TEXT[]         NOT NULL DEFAULT '{}',
+    expires_at                   TIMESTAMPTZ,
+    last_used_at                 TIMESTAMPTZ,
+    revoked_at                   TIMESTAMPTZ,
+
+    -- WHEN
+    created_at                   TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    updated_at                   TIMESTAMPTZ    NOT NULL DEFAULT NOW()
+);
+
+-- ADD CONSTRAINTS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_personal_access_tokens_tenant_id'
+    ) THEN
+        ALTER TABLE personal_access_tokens
+            ADD CONSTRAINT fk_personal_access_tokens_tenant_id FOREIGN KEY (tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_personal_access_tokens_user_id'
+    ) THEN
+        ALTER TABLE personal_access_tokens
+            ADD CONSTRAINT fk_personal_access_tokens_user_id FOREIGN KEY (user_id)
+            REFERENCES users(user_id) ON DELETE CASCADE;
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- ADD INDEXES
+CREATE INDEX IF NOT EXISTS idx_personal_access_tokens_user_id ON personal_access_tokens (user_id);
+CREATE INDEX IF NOT EXISTS idx_personal_access_tokens_tenant_id ON personal_access_tokens (tenant_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS personal_access_tokens;
@@ -0,0 +1,68 @@
+-- Creates the permission_groups table and its permission_group_permissions join
+-- table. A permission group is a named bundle of permissions that can be
+-- assigned to roles, clients and API keys in one call.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS permission_groups (
+    permission_group_id      BIGSERIAL      PRIMARY KEY,
+    permission_group_uuid    UUID           NOT NULL UNIQUE,
+    tenant_id                INTEGER        NOT NULL,
+    name                     VARCHAR(100)   NOT NULL,
+    description              TEXT           NOT NULL DEFAULT '',
+
+    -- WHEN
+    created_at               TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    updated_at               TIMESTAMPTZ    NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS permission_group_permissions (
+    permission_group_id      BIGINT         NOT NULL,
+    permission_id            INTEGER        NOT NULL,
+    created_at               TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (permission_group_id, permission_id)
+);
+
+-- ADD CONSTRAINTS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_permission_groups_tenant_id'
+    ) THEN
+        ALTER TABLE permission_groups
+            ADD CONSTRAINT fk_permission_groups_tenant_id FOREIGN KEY (tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'uq_permission_groups_tenant_name'
+    ) THEN
+        ALTER TABLE permission_groups
+            ADD CONSTRAINT uq_permission_groups_tenant_name UNIQUE (tenant_id, name);
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_permission_group_permissions_group_id'
+    ) THEN
+        ALTER TABLE permission_group_permissions
+            ADD CONSTRAINT fk_permission_group_permissions_group_id FOREIGN KEY (permission_group_id)
+            REFERENCES permission_groups(permission_group_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_permission_group_permissions_permission_id'
+    ) THEN
+        ALTER TABLE permission_group_permissions
+            ADD CONSTRAINT fk_permission_group_permissions_permission_id FOREIGN KEY (permission_id)
+            REFERENCES permissions(permission_id) ON DELETE CASCADE;
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- ADD INDEXES
+CREATE INDEX IF NOT EXISTS idx_permission_groups_tenant_id ON permission_groups (tenant_id);
+CREATE INDEX IF NOT EXISTS idx_permission_group_permissions_permission_id ON permission_group_permissions (permission_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS permission_group_permissions, permission_groups;
@@ -0,0 +1,61 @@
+-- Creates the user_import_jobs table that tracks bulk user imports: their
+-- progress while they run and the row-level report once they finish.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS user_import_jobs (
+    user_import_job_id      BIGSERIAL PRIMARY KEY,
+    user_import_job_uuid    UUID NOT NULL UNIQUE,
+    tenant_id               INTEGER NOT NULL,
+    created_by              INTEGER,
+    format                  VARCHAR(10) NOT NULL,
+    dry_run                 BOOLEAN NOT NULL DEFAULT FALSE,
+    status                  VARCHAR(20) NOT NULL DEFAULT 'pending',
+    total_rows              INTEGER NOT NULL DEFAULT 0,
+    processed_rows          INTEGER NOT NULL DEFAULT 0,
+    imported_rows           INTEGER NOT NULL DEFAULT 0,
+    duplicate_rows          INTEGER NOT NULL DEFAULT 0,
+    invalid_rows            INTEGER NOT NULL DEFAULT 0,
+    failed_rows             INTEGER NOT NULL DEFAULT 0,
+    row_errors              JSONB NOT NULL DEFAULT '[]'::jsonb,
+    error                   TEXT,
+    started_at              TIMESTAMPTZ,
+    completed_at            TIMESTAMPTZ,
+    created_at              TIMESTAMPTZ DEFAULT now(),
+    updated_at              TIMESTAMPTZ DEFAULT now()
+);
+
+-- ADD CONSTRAINTS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_user_import_jobs_tenant_id'
+    ) THEN
+        ALTER TABLE user_import_jobs
+            ADD CONSTRAINT fk_user_import_jobs_tenant_id FOREIGN KEY (tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_user_import_jobs_created_by'
+    ) THEN
+        ALTER TABLE user_import_jobs
+            ADD CONSTRAINT fk_user_import_jobs_created_by FOREIGN KEY (created_by)
+            REFERENCES users(user_id) ON DELETE SET NULL;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'chk_user_import_jobs_status'
+    ) THEN
+        ALTER TABLE user_import_jobs
+            ADD CONSTRAINT chk_user_import_jobs_status CHECK (status IN ('pending', 'running', 'completed', 'failed'));
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- CREATE INDEXES
+CREATE INDEX IF NOT EXISTS idx_user_import_jobs_tenant_created ON user_import_jobs (tenant_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_import_jobs;
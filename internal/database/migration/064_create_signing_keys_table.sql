@@ -0,0 +1,40 @@
+-- Creates the signing_keys table that holds the RSA keys JWTs are signed and
+-- verified with. The partial unique index keeps a single active key, so two
+-- instances rotating at once cannot both succeed.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS signing_keys (
+    signing_key_id          BIGSERIAL PRIMARY KEY,
+    signing_key_uuid        UUID NOT NULL UNIQUE,
+    key_id                  VARCHAR(100) NOT NULL UNIQUE,
+    algorithm               VARCHAR(10) NOT NULL DEFAULT 'RS256',
+    public_key              TEXT NOT NULL,
+    private_key             TEXT,
+    status                  VARCHAR(20) NOT NULL DEFAULT 'active',
+    activated_at            TIMESTAMPTZ NOT NULL DEFAULT now(),
+    rotated_at              TIMESTAMPTZ,
+    retired_at              TIMESTAMPTZ,
+    created_at              TIMESTAMPTZ DEFAULT now(),
+    updated_at              TIMESTAMPTZ DEFAULT now()
+);
+
+-- ADD CONSTRAINTS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'chk_signing_keys_status'
+    ) THEN
+        ALTER TABLE signing_keys
+            ADD CONSTRAINT chk_signing_keys_status CHECK (status IN ('active', 'previous', 'retired'));
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- CREATE INDEXES
+CREATE UNIQUE INDEX IF NOT EXISTS idx_signing_keys_active ON signing_keys (status) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_signing_keys_status_rotated ON signing_keys (status, rotated_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS signing_keys;
@@ -0,0 +1,63 @@
+-- Creates the tenant_deprovisions table that holds requests to archive or
+-- delete a tenant until they are confirmed. The tenant reference is cleared
+-- rather than cascaded so completed deletions stay on record.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS tenant_deprovisions (
+    tenant_deprovision_id   BIGSERIAL PRIMARY KEY,
+    tenant_deprovision_uuid UUID NOT NULL UNIQUE,
+    tenant_id               INTEGER,
+    tenant_uuid             UUID NOT NULL,
+    tenant_name             VARCHAR(255) NOT NULL,
+    mode                    VARCHAR(10) NOT NULL,
+    token_hash              VARCHAR(64) NOT NULL,
+    status                  VARCHAR(20) NOT NULL DEFAULT 'pending',
+    requested_by            INTEGER,
+    expires_at              TIMESTAMPTZ NOT NULL,
+    completed_at            TIMESTAMPTZ,
+    created_at              TIMESTAMPTZ DEFAULT now(),
+    updated_at              TIMESTAMPTZ DEFAULT now()
+);
+
+-- ADD CONSTRAINTS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_tenant_deprovisions_tenant_id'
+    ) THEN
+        ALTER TABLE tenant_deprovisions
+            ADD CONSTRAINT fk_tenant_deprovisions_tenant_id FOREIGN KEY (tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE SET NULL;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_tenant_deprovisions_requested_by'
+    ) THEN
+        ALTER TABLE tenant_deprovisions
+            ADD CONSTRAINT fk_tenant_deprovisions_requested_by FOREIGN KEY (requested_by)
+            REFERENCES users(user_id) ON DELETE SET NULL;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'chk_tenant_deprovisions_mode'
+    ) THEN
+        ALTER TABLE tenant_deprovisions
+            ADD CONSTRAINT chk_tenant_deprovisions_mode CHECK (mode IN ('archive', 'delete'));
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'chk_tenant_deprovisions_status'
+    ) THEN
+        ALTER TABLE tenant_deprovisions
+            ADD CONSTRAINT chk_tenant_deprovisions_status CHECK (status IN ('pending', 'completed', 'superseded'));
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- CREATE INDEXES
+CREATE INDEX IF NOT EXISTS idx_tenant_deprovisions_tenant_status ON tenant_deprovisions (tenant_id, status);
+
+-- +goose Down
+DROP TABLE IF EXISTS tenant_deprovisions;
@@ -0,0 +1,53 @@
+-- Lets tenants be nested under a parent tenant and roles be inherited by the
+-- tenants below theirs. ancestor_path lists the IDs of a tenant's ancestors,
+-- root first, as "/1/5/", so subtree and ancestry checks need no recursive
+-- queries. A tenant with child tenants cannot be deleted.
+
+-- +goose Up
+-- ALTER TABLES
+ALTER TABLE tenants
+    ADD COLUMN IF NOT EXISTS parent_tenant_id INTEGER,
+    ADD COLUMN IF NOT EXISTS ancestor_path TEXT NOT NULL DEFAULT '/';
+
+ALTER TABLE roles
+    ADD COLUMN IF NOT EXISTS is_inheritable BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- ADD CONSTRAINTS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_tenants_parent_tenant_id'
+    ) THEN
+        ALTER TABLE tenants
+            ADD CONSTRAINT fk_tenants_parent_tenant_id FOREIGN KEY (parent_tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE RESTRICT;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'chk_tenants_parent_not_self'
+    ) THEN
+        ALTER TABLE tenants
+            ADD CONSTRAINT chk_tenants_parent_not_self CHECK (parent_tenant_id <> tenant_id);
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- CREATE INDEXES
+CREATE INDEX IF NOT EXISTS idx_tenants_parent_tenant_id ON tenants (parent_tenant_id);
+CREATE INDEX IF NOT EXISTS idx_tenants_ancestor_path ON tenants (ancestor_path text_pattern_ops);
+
+-- +goose Down
+-- DROP INDEXES
+DROP INDEX IF EXISTS idx_tenants_parent_tenant_id;
+DROP INDEX IF EXISTS idx_tenants_ancestor_path;
+
+-- ALTER TABLES
+ALTER TABLE tenants
+    DROP CONSTRAINT IF EXISTS fk_tenants_parent_tenant_id,
+    DROP CONSTRAINT IF EXISTS chk_tenants_parent_not_self,
+    DROP COLUMN IF EXISTS parent_tenant_id,
+    DROP COLUMN IF EXISTS ancestor_path;
+
+ALTER TABLE roles
+    DROP COLUMN IF EXISTS is_inheritable;
@@ -0,0 +1,104 @@
+-- Creates the groups table with its group_members and group_roles join tables.
+-- Every member of a group holds the roles attached to it. It also widens the
+-- events aggregate type check to the aggregates that record events.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS groups (
+    group_id                 BIGSERIAL      PRIMARY KEY,
+    group_uuid               UUID           NOT NULL UNIQUE,
+    tenant_id                INTEGER        NOT NULL,
+    name                     VARCHAR(100)   NOT NULL,
+    description              TEXT           NOT NULL DEFAULT '',
+
+    -- WHEN
+    created_at               TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    updated_at               TIMESTAMPTZ    NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS group_members (
+    group_id                 BIGINT         NOT NULL,
+    user_id                  INTEGER        NOT NULL,
+    created_at               TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS group_roles (
+    group_id                 BIGINT         NOT NULL,
+    role_id                  INTEGER        NOT NULL,
+    created_at               TIMESTAMPTZ    NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (group_id, role_id)
+);
+
+-- ADD CONSTRAINTS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_groups_tenant_id'
+    ) THEN
+        ALTER TABLE groups
+            ADD CONSTRAINT fk_groups_tenant_id FOREIGN KEY (tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'uq_groups_tenant_name'
+    ) THEN
+        ALTER TABLE groups
+            ADD CONSTRAINT uq_groups_tenant_name UNIQUE (tenant_id, name);
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_group_members_group_id'
+    ) THEN
+        ALTER TABLE group_members
+            ADD CONSTRAINT fk_group_members_group_id FOREIGN KEY (group_id)
+            REFERENCES groups(group_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_group_members_user_id'
+    ) THEN
+        ALTER TABLE group_members
+            ADD CONSTRAINT fk_group_members_user_id FOREIGN KEY (user_id)
+            REFERENCES users(user_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_group_roles_group_id'
+    ) THEN
+        ALTER TABLE group_roles
+            ADD CONSTRAINT fk_group_roles_group_id FOREIGN KEY (group_id)
+            REFERENCES groups(group_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_group_roles_role_id'
+    ) THEN
+        ALTER TABLE group_roles
+            ADD CONSTRAINT fk_group_roles_role_id FOREIGN KEY (role_id)
+            REFERENCES roles(role_id) ON DELETE CASCADE;
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS chk_events_aggregate_type;
+ALTER TABLE events ADD CONSTRAINT chk_events_aggregate_type CHECK (aggregate_type IN (
+    'user', 'role', 'client', 'api_key', 'signup_flow', 'group'
+));
+
+-- ADD INDEXES
+CREATE INDEX IF NOT EXISTS idx_groups_tenant_id ON groups (tenant_id);
+CREATE INDEX IF NOT EXISTS idx_group_members_user_id ON group_members (user_id);
+CREATE INDEX IF NOT EXISTS idx_group_roles_role_id ON group_roles (role_id);
+
+-- +goose Down
+-- DROP TABLES
+DROP TABLE IF EXISTS group_roles, group_members, groups;
+
+-- ALTER CONSTRAINTS
+ALTER TABLE events DROP CONSTRAINT IF EXISTS chk_events_aggregate_type;
+ALTER TABLE events ADD CONSTRAINT chk_events_aggregate_type CHECK (aggregate_type IN (
+    'user', 'role', 'client'
+));
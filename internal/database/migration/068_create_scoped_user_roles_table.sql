@@ -0,0 +1,74 @@
+-- Creates the scoped_user_roles table. A scoped assignment grants a role's
+-- permissions on a single client or group only, for delegated administration.
+-- Exactly one resource column is set.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS scoped_user_roles (
+    scoped_user_role_id      BIGSERIAL      PRIMARY KEY,
+    scoped_user_role_uuid    UUID           NOT NULL UNIQUE,
+    user_id                  INTEGER        NOT NULL,
+    role_id                  INTEGER        NOT NULL,
+
+    -- RESOURCE
+    client_id                INTEGER,
+    group_id                 BIGINT,
+
+    -- WHEN
+    created_at               TIMESTAMPTZ    NOT NULL DEFAULT NOW()
+);
+
+-- ADD CONSTRAINTS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_scoped_user_roles_user_id'
+    ) THEN
+        ALTER TABLE scoped_user_roles
+            ADD CONSTRAINT fk_scoped_user_roles_user_id FOREIGN KEY (user_id)
+            REFERENCES users(user_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_scoped_user_roles_role_id'
+    ) THEN
+        ALTER TABLE scoped_user_roles
+            ADD CONSTRAINT fk_scoped_user_roles_role_id FOREIGN KEY (role_id)
+            REFERENCES roles(role_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_scoped_user_roles_client_id'
+    ) THEN
+        ALTER TABLE scoped_user_roles
+            ADD CONSTRAINT fk_scoped_user_roles_client_id FOREIGN KEY (client_id)
+            REFERENCES clients(client_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_scoped_user_roles_group_id'
+    ) THEN
+        ALTER TABLE scoped_user_roles
+            ADD CONSTRAINT fk_scoped_user_roles_group_id FOREIGN KEY (group_id)
+            REFERENCES groups(group_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'chk_scoped_user_roles_resource'
+    ) THEN
+        ALTER TABLE scoped_user_roles
+            ADD CONSTRAINT chk_scoped_user_roles_resource CHECK (num_nonnulls(client_id, group_id) = 1);
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- ADD INDEXES
+CREATE UNIQUE INDEX IF NOT EXISTS uq_scoped_user_roles_client ON scoped_user_roles (user_id, role_id, client_id) WHERE client_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_scoped_user_roles_group ON scoped_user_roles (user_id, role_id, group_id) WHERE group_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_scoped_user_roles_role_id ON scoped_user_roles (role_id);
+CREATE INDEX IF NOT EXISTS idx_scoped_user_roles_client_id ON scoped_user_roles (client_id);
+CREATE INDEX IF NOT EXISTS idx_scoped_user_roles_group_id ON scoped_user_roles (group_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS scoped_user_roles;
@@ -0,0 +1,55 @@
+-- Creates the login_fingerprints table. It holds one row for each device a user
+-- has signed in to a tenant from, used to detect new devices and impossible
+-- travel.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS login_fingerprints (
+    login_fingerprint_id      BIGSERIAL           PRIMARY KEY,
+    login_fingerprint_uuid    UUID                NOT NULL UNIQUE,
+    user_id                   INTEGER             NOT NULL,
+    tenant_id                 INTEGER             NOT NULL,
+    device_hash               VARCHAR(64)         NOT NULL,
+    user_agent                TEXT,
+
+    -- LATEST SIGN IN
+    ip_address                VARCHAR(45),
+    country                   VARCHAR(2),
+    city                      VARCHAR(255),
+    latitude                  DOUBLE PRECISION,
+    longitude                 DOUBLE PRECISION,
+    login_count               INTEGER             NOT NULL DEFAULT 1,
+
+    -- WHEN
+    first_seen_at             TIMESTAMPTZ         NOT NULL DEFAULT NOW(),
+    last_seen_at              TIMESTAMPTZ         NOT NULL DEFAULT NOW()
+);
+
+-- ADD CONSTRAINTS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_login_fingerprints_user_id'
+    ) THEN
+        ALTER TABLE login_fingerprints
+            ADD CONSTRAINT fk_login_fingerprints_user_id FOREIGN KEY (user_id)
+            REFERENCES users(user_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_login_fingerprints_tenant_id'
+    ) THEN
+        ALTER TABLE login_fingerprints
+            ADD CONSTRAINT fk_login_fingerprints_tenant_id FOREIGN KEY (tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE CASCADE;
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- ADD INDEXES
+CREATE UNIQUE INDEX IF NOT EXISTS uq_login_fingerprints_device ON login_fingerprints (user_id, tenant_id, device_hash);
+CREATE INDEX IF NOT EXISTS idx_login_fingerprints_last_seen ON login_fingerprints (user_id, tenant_id, last_seen_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS login_fingerprints;
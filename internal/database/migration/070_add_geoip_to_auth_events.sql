@@ -0,0 +1,27 @@
+-- Adds where the client IP was located to auth_events, and the network it
+-- belongs to to auth_events and login_fingerprints. The columns stay empty
+-- while no GeoIP database is configured.
+
+-- +goose Up
+-- ALTER TABLES
+ALTER TABLE auth_events
+    ADD COLUMN IF NOT EXISTS country VARCHAR(2),
+    ADD COLUMN IF NOT EXISTS city VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS asn BIGINT,
+    ADD COLUMN IF NOT EXISTS as_organization VARCHAR(255);
+
+ALTER TABLE login_fingerprints
+    ADD COLUMN IF NOT EXISTS asn BIGINT,
+    ADD COLUMN IF NOT EXISTS as_organization VARCHAR(255);
+
+-- +goose Down
+-- ALTER TABLES
+ALTER TABLE auth_events
+    DROP COLUMN IF EXISTS country,
+    DROP COLUMN IF EXISTS city,
+    DROP COLUMN IF EXISTS asn,
+    DROP COLUMN IF EXISTS as_organization;
+
+ALTER TABLE login_fingerprints
+    DROP COLUMN IF EXISTS asn,
+    DROP COLUMN IF EXISTS as_organization;
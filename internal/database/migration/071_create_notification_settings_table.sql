@@ -0,0 +1,51 @@
+-- Creates the notification_settings table. It holds each user's delivery
+-- channel preferences per tenant. Users without a row get the defaults: email
+-- and in-app on, SMS off.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS notification_settings (
+    notification_setting_id   BIGSERIAL           PRIMARY KEY,
+    notification_setting_uuid UUID                NOT NULL UNIQUE,
+    user_id                   INTEGER             NOT NULL,
+    tenant_id                 INTEGER             NOT NULL,
+
+    -- CHANNELS
+    email_enabled             BOOLEAN             NOT NULL DEFAULT TRUE,
+    sms_enabled               BOOLEAN             NOT NULL DEFAULT FALSE,
+    in_app_enabled            BOOLEAN             NOT NULL DEFAULT TRUE,
+
+    -- OPTED OUT NOTIFICATION TYPES
+    muted_types               JSONB               NOT NULL DEFAULT '[]',
+
+    created_at                TIMESTAMPTZ         NOT NULL DEFAULT NOW(),
+    updated_at                TIMESTAMPTZ         NOT NULL DEFAULT NOW()
+);
+
+-- ADD CONSTRAINTS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_notification_settings_user_id'
+    ) THEN
+        ALTER TABLE notification_settings
+            ADD CONSTRAINT fk_notification_settings_user_id FOREIGN KEY (user_id)
+            REFERENCES users(user_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_notification_settings_tenant_id'
+    ) THEN
+        ALTER TABLE notification_settings
+            ADD CONSTRAINT fk_notification_settings_tenant_id FOREIGN KEY (tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE CASCADE;
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- ADD INDEXES
+CREATE UNIQUE INDEX IF NOT EXISTS uq_notification_settings_user_tenant ON notification_settings (user_id, tenant_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS notification_settings;
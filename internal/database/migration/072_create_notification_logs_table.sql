@@ -0,0 +1,57 @@
+-- Creates the notification_logs table. It holds one row for each notification
+-- delivered, or attempted, on one channel. In-app notifications are listed from
+-- it too.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS notification_logs (
+    notification_log_id       BIGSERIAL           PRIMARY KEY,
+    notification_log_uuid     UUID                NOT NULL UNIQUE,
+    tenant_id                 INTEGER             NOT NULL,
+    user_id                   INTEGER             NOT NULL,
+
+    -- WHAT
+    notification_type         VARCHAR(100)        NOT NULL,
+    channel                   VARCHAR(20)         NOT NULL,
+    recipient                 VARCHAR(255),
+    title                     VARCHAR(255),
+    message                   TEXT,
+
+    -- OUTCOME
+    status                    VARCHAR(20)         NOT NULL,
+    error_reason              VARCHAR(255),
+
+    created_at                TIMESTAMPTZ         NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_notification_logs_channel CHECK (channel IN ('email', 'sms', 'in_app')),
+    CONSTRAINT chk_notification_logs_status CHECK (status IN ('sent', 'failed', 'skipped'))
+);
+
+-- ADD CONSTRAINTS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_notification_logs_user_id'
+    ) THEN
+        ALTER TABLE notification_logs
+            ADD CONSTRAINT fk_notification_logs_user_id FOREIGN KEY (user_id)
+            REFERENCES users(user_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_notification_logs_tenant_id'
+    ) THEN
+        ALTER TABLE notification_logs
+            ADD CONSTRAINT fk_notification_logs_tenant_id FOREIGN KEY (tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE CASCADE;
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- ADD INDEXES
+CREATE INDEX IF NOT EXISTS idx_notification_logs_user_created ON notification_logs (tenant_id, user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notification_logs_tenant_created ON notification_logs (tenant_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS notification_logs;
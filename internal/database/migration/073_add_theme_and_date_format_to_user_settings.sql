@@ -0,0 +1,20 @@
+-- Adds the theme and date format preferences to user_settings, and the tenant-
+-- level defaults of the preferences to tenant_settings.
+
+-- +goose Up
+-- ALTER TABLES
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS theme VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS date_format VARCHAR(20);
+
+ALTER TABLE tenant_settings
+    ADD COLUMN IF NOT EXISTS user_setting_defaults JSONB NOT NULL DEFAULT '{}';
+
+-- +goose Down
+-- ALTER TABLES
+ALTER TABLE user_settings
+    DROP COLUMN IF EXISTS theme,
+    DROP COLUMN IF EXISTS date_format;
+
+ALTER TABLE tenant_settings
+    DROP COLUMN IF EXISTS user_setting_defaults;
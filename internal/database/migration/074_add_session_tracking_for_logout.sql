@@ -0,0 +1,47 @@
+-- Records the login session (the sid claim) that authorization codes, consent
+-- challenges and refresh tokens belong to, so that ending the session can
+-- revoke them and notify every client of it. It also allows the back-channel
+-- and front-channel logout URI types on client_uris.
+
+-- +goose Up
+-- ALTER TABLES
+ALTER TABLE oauth_authorization_codes
+    ADD COLUMN IF NOT EXISTS session_id VARCHAR(64);
+
+ALTER TABLE oauth_consent_challenges
+    ADD COLUMN IF NOT EXISTS session_id VARCHAR(64);
+
+ALTER TABLE oauth_refresh_tokens
+    ADD COLUMN IF NOT EXISTS session_id VARCHAR(64);
+
+ALTER TABLE client_uris
+    ALTER COLUMN type TYPE VARCHAR(40);
+
+-- ALTER CONSTRAINTS
+ALTER TABLE client_uris DROP CONSTRAINT IF EXISTS chk_client_uris_type;
+ALTER TABLE client_uris
+    ADD CONSTRAINT chk_client_uris_type CHECK (type IN ('redirect-uri', 'origin-uri', 'logout-uri', 'login-uri', 'cors-origin-uri', 'backchannel-logout-uri', 'frontchannel-logout-uri'));
+
+-- ADD INDEXES
+CREATE INDEX IF NOT EXISTS idx_oauth_refresh_session ON oauth_refresh_tokens (session_id);
+
+-- +goose Down
+-- DROP INDEXES
+DROP INDEX IF EXISTS idx_oauth_refresh_session;
+
+-- ALTER TABLES
+ALTER TABLE oauth_authorization_codes
+    DROP COLUMN IF EXISTS session_id;
+
+ALTER TABLE oauth_consent_challenges
+    DROP COLUMN IF EXISTS session_id;
+
+ALTER TABLE oauth_refresh_tokens
+    DROP COLUMN IF EXISTS session_id;
+
+-- ALTER CONSTRAINTS
+ALTER TABLE client_uris DROP CONSTRAINT IF EXISTS chk_client_uris_type;
+ALTER TABLE client_uris
+    ALTER COLUMN type TYPE VARCHAR(20);
+ALTER TABLE client_uris
+    ADD CONSTRAINT chk_client_uris_type CHECK (type IN ('redirect-uri', 'origin-uri', 'logout-uri', 'login-uri', 'cors-origin-uri'));
@@ -0,0 +1,52 @@
+-- Creates the trusted_devices table. It holds the devices users chose to
+-- remember after a second factor, so later logins from them skip step-up
+-- authentication. Only a hash of each device token is stored.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS trusted_devices (
+    trusted_device_id         BIGSERIAL           PRIMARY KEY,
+    trusted_device_uuid       UUID                NOT NULL UNIQUE,
+    tenant_id                 INTEGER             NOT NULL,
+    user_id                   INTEGER             NOT NULL,
+    name                      VARCHAR(100),
+    token_hash                VARCHAR(64)         NOT NULL UNIQUE,
+
+    -- WHERE IT WAS TRUSTED FROM
+    user_agent                TEXT,
+    ip_address                VARCHAR(45),
+
+    expires_at                TIMESTAMPTZ         NOT NULL,
+    last_used_at              TIMESTAMPTZ,
+    revoked_at                TIMESTAMPTZ,
+    created_at                TIMESTAMPTZ         NOT NULL DEFAULT NOW(),
+    updated_at                TIMESTAMPTZ         NOT NULL DEFAULT NOW()
+);
+
+-- ADD CONSTRAINTS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_trusted_devices_user_id'
+    ) THEN
+        ALTER TABLE trusted_devices
+            ADD CONSTRAINT fk_trusted_devices_user_id FOREIGN KEY (user_id)
+            REFERENCES users(user_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_trusted_devices_tenant_id'
+    ) THEN
+        ALTER TABLE trusted_devices
+            ADD CONSTRAINT fk_trusted_devices_tenant_id FOREIGN KEY (tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE CASCADE;
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- ADD INDEXES
+CREATE INDEX IF NOT EXISTS idx_trusted_devices_user_expires ON trusted_devices (user_id, expires_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS trusted_devices;
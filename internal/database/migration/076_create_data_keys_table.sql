@@ -0,0 +1,36 @@
+-- Creates the data_keys table that holds the wrapped data keys sensitive
+-- columns are encrypted with. The partial unique index keeps a single active
+-- key, so two instances rotating at once cannot both succeed.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS data_keys (
+    data_key_id             BIGSERIAL PRIMARY KEY,
+    data_key_uuid           UUID NOT NULL UNIQUE,
+    key_id                  VARCHAR(100) NOT NULL UNIQUE,
+    wrapped_key             TEXT NOT NULL,
+    status                  VARCHAR(20) NOT NULL DEFAULT 'active',
+    activated_at            TIMESTAMPTZ NOT NULL DEFAULT now(),
+    rotated_at              TIMESTAMPTZ,
+    created_at              TIMESTAMPTZ DEFAULT now(),
+    updated_at              TIMESTAMPTZ DEFAULT now()
+);
+
+-- ADD CONSTRAINTS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'chk_data_keys_status'
+    ) THEN
+        ALTER TABLE data_keys
+            ADD CONSTRAINT chk_data_keys_status CHECK (status IN ('active', 'previous'));
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- CREATE INDEXES
+CREATE UNIQUE INDEX IF NOT EXISTS idx_data_keys_active ON data_keys (status) WHERE status = 'active';
+
+-- +goose Down
+DROP TABLE IF EXISTS data_keys;
@@ -0,0 +1,37 @@
+-- Creates the tenant_usage table that holds the daily usage of each tenant, and
+-- of each of its API keys, for billing. api_key_id is 0 for usage without an
+-- API key and is not a foreign key, so that usage outlives deleted keys.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS tenant_usage (
+    tenant_usage_id         BIGSERIAL PRIMARY KEY,
+    tenant_usage_uuid       UUID NOT NULL UNIQUE,
+    tenant_id               BIGINT NOT NULL,
+    api_key_id              BIGINT NOT NULL DEFAULT 0,
+    usage_date              DATE NOT NULL,
+    metric                  VARCHAR(50) NOT NULL,
+    count                   BIGINT NOT NULL DEFAULT 0,
+    created_at              TIMESTAMPTZ DEFAULT now(),
+    updated_at              TIMESTAMPTZ DEFAULT now()
+);
+
+-- ADD FOREIGN KEYS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_tenant_usage_tenant_id'
+    ) THEN
+        ALTER TABLE tenant_usage
+            ADD CONSTRAINT fk_tenant_usage_tenant_id FOREIGN KEY (tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE CASCADE;
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- CREATE INDEXES
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_usage_day ON tenant_usage (tenant_id, usage_date, metric, api_key_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS tenant_usage;
@@ -0,0 +1,58 @@
+-- Creates the plans table that holds the billing tiers tenants are subscribed
+-- to, and adds the plan of each tenant. Plans in use cannot be deleted; the
+-- partial unique index keeps a single default plan.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS plans (
+    plan_id                 BIGSERIAL PRIMARY KEY,
+    plan_uuid               UUID NOT NULL UNIQUE,
+    name                    VARCHAR(100) NOT NULL UNIQUE,
+    display_name            VARCHAR(255),
+    description             TEXT,
+    features                JSONB NOT NULL DEFAULT '{}',
+    max_users               BIGINT NOT NULL DEFAULT 0,
+    max_clients             BIGINT NOT NULL DEFAULT 0,
+    is_default              BOOLEAN NOT NULL DEFAULT FALSE,
+    status                  VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at              TIMESTAMPTZ DEFAULT now(),
+    updated_at              TIMESTAMPTZ DEFAULT now()
+);
+
+-- ALTER TABLES
+ALTER TABLE tenants
+    ADD COLUMN IF NOT EXISTS plan_id BIGINT;
+
+-- ADD FOREIGN KEYS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_tenants_plan_id'
+    ) THEN
+        ALTER TABLE tenants
+            ADD CONSTRAINT fk_tenants_plan_id FOREIGN KEY (plan_id)
+            REFERENCES plans(plan_id) ON DELETE RESTRICT;
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- ADD CONSTRAINTS
+ALTER TABLE plans DROP CONSTRAINT IF EXISTS chk_plans_status;
+ALTER TABLE plans
+    ADD CONSTRAINT chk_plans_status CHECK (status IN ('active', 'inactive'));
+
+-- CREATE INDEXES
+CREATE UNIQUE INDEX IF NOT EXISTS idx_plans_default ON plans (is_default) WHERE is_default;
+CREATE INDEX IF NOT EXISTS idx_tenants_plan_id ON tenants (plan_id);
+
+-- +goose Down
+-- DROP INDEXES
+DROP INDEX IF EXISTS idx_tenants_plan_id;
+
+-- ALTER TABLES
+ALTER TABLE tenants
+    DROP COLUMN IF EXISTS plan_id;
+
+-- DROP TABLES
+DROP TABLE IF EXISTS plans;
@@ -0,0 +1,58 @@
+-- Creates the feature_flags table and the per-tenant overrides of each flag.
+-- Overrides are removed with their flag or tenant.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS feature_flags (
+    feature_flag_id         BIGSERIAL PRIMARY KEY,
+    feature_flag_uuid       UUID NOT NULL UNIQUE,
+    key                     VARCHAR(100) NOT NULL UNIQUE,
+    description             TEXT,
+    enabled                 BOOLEAN NOT NULL DEFAULT FALSE,
+    rollout_percentage      INTEGER NOT NULL DEFAULT 100,
+    created_at              TIMESTAMPTZ DEFAULT now(),
+    updated_at              TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS feature_flag_overrides (
+    feature_flag_override_id    BIGSERIAL PRIMARY KEY,
+    feature_flag_id             BIGINT NOT NULL,
+    tenant_id                   BIGINT NOT NULL,
+    enabled                     BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at                  TIMESTAMPTZ DEFAULT now(),
+    updated_at                  TIMESTAMPTZ DEFAULT now()
+);
+
+-- ADD FOREIGN KEYS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_feature_flag_overrides_feature_flag_id'
+    ) THEN
+        ALTER TABLE feature_flag_overrides
+            ADD CONSTRAINT fk_feature_flag_overrides_feature_flag_id FOREIGN KEY (feature_flag_id)
+            REFERENCES feature_flags(feature_flag_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_feature_flag_overrides_tenant_id'
+    ) THEN
+        ALTER TABLE feature_flag_overrides
+            ADD CONSTRAINT fk_feature_flag_overrides_tenant_id FOREIGN KEY (tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE CASCADE;
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- ADD CONSTRAINTS
+ALTER TABLE feature_flags DROP CONSTRAINT IF EXISTS chk_feature_flags_rollout_percentage;
+ALTER TABLE feature_flags
+    ADD CONSTRAINT chk_feature_flags_rollout_percentage CHECK (rollout_percentage BETWEEN 0 AND 100);
+
+-- CREATE INDEXES
+CREATE UNIQUE INDEX IF NOT EXISTS idx_feature_flag_overrides_flag_tenant ON feature_flag_overrides (feature_flag_id, tenant_id);
+CREATE INDEX IF NOT EXISTS idx_feature_flag_overrides_tenant_id ON feature_flag_overrides (tenant_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS feature_flag_overrides, feature_flags;
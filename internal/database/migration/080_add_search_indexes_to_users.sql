@@ -0,0 +1,24 @@
+-- Enables pg_trgm and indexes users for the fuzzy search of GET /users/search:
+-- a trigram index per searched column, which also serves substring matches, and
+-- a full-text index over the name fields.
+
+-- +goose Up
+-- EXTENSIONS
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- ADD INDEXES
+CREATE INDEX IF NOT EXISTS idx_users_username_trgm ON users USING GIN (lower(username) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_fullname_trgm ON users USING GIN (lower(fullname) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_email_trgm ON users USING GIN (lower(email) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_phone_trgm ON users USING GIN (phone gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_search_tsv ON users USING GIN (
+    to_tsvector('simple', coalesce(fullname, '') || ' ' || coalesce(username, '') || ' ' || coalesce(email, ''))
+);
+
+-- +goose Down
+-- DROP INDEXES
+DROP INDEX IF EXISTS idx_users_search_tsv;
+DROP INDEX IF EXISTS idx_users_phone_trgm;
+DROP INDEX IF EXISTS idx_users_email_trgm;
+DROP INDEX IF EXISTS idx_users_fullname_trgm;
+DROP INDEX IF EXISTS idx_users_username_trgm;
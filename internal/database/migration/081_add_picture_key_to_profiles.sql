@@ -0,0 +1,12 @@
+-- Records the storage key of an uploaded profile picture. Responses turn it
+-- into a signed URL in place of profile_url.
+
+-- +goose Up
+-- ALTER TABLES
+ALTER TABLE profiles
+    ADD COLUMN IF NOT EXISTS picture_key VARCHAR(512);
+
+-- +goose Down
+-- ALTER TABLES
+ALTER TABLE profiles
+    DROP COLUMN IF EXISTS picture_key;
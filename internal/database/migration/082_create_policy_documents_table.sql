@@ -0,0 +1,82 @@
+-- Creates the versioned policy documents of each tenant and the record of which
+-- user accepted which version. Acceptances are kept while their document exists
+-- and removed with their user.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS policy_documents (
+    policy_document_id      BIGSERIAL PRIMARY KEY,
+    policy_document_uuid    UUID NOT NULL UNIQUE,
+    tenant_id               BIGINT NOT NULL,
+    type                    VARCHAR(30) NOT NULL,
+    version                 VARCHAR(50) NOT NULL,
+    title                   VARCHAR(255) NOT NULL,
+    url                     TEXT NOT NULL,
+    require_acceptance      BOOLEAN NOT NULL DEFAULT TRUE,
+    published_at            TIMESTAMPTZ,
+    created_at              TIMESTAMPTZ DEFAULT now(),
+    updated_at              TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS policy_acceptances (
+    policy_acceptance_id    BIGSERIAL PRIMARY KEY,
+    policy_document_id      BIGINT NOT NULL,
+    user_id                 BIGINT NOT NULL,
+    tenant_id               BIGINT NOT NULL,
+    ip_address              VARCHAR(45),
+    user_agent              TEXT,
+    accepted_at             TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- ADD FOREIGN KEYS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_policy_documents_tenant_id'
+    ) THEN
+        ALTER TABLE policy_documents
+            ADD CONSTRAINT fk_policy_documents_tenant_id FOREIGN KEY (tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_policy_acceptances_policy_document_id'
+    ) THEN
+        ALTER TABLE policy_acceptances
+            ADD CONSTRAINT fk_policy_acceptances_policy_document_id FOREIGN KEY (policy_document_id)
+            REFERENCES policy_documents(policy_document_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_policy_acceptances_user_id'
+    ) THEN
+        ALTER TABLE policy_acceptances
+            ADD CONSTRAINT fk_policy_acceptances_user_id FOREIGN KEY (user_id)
+            REFERENCES users(user_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_policy_acceptances_tenant_id'
+    ) THEN
+        ALTER TABLE policy_acceptances
+            ADD CONSTRAINT fk_policy_acceptances_tenant_id FOREIGN KEY (tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE CASCADE;
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- ADD CONSTRAINTS
+ALTER TABLE policy_documents DROP CONSTRAINT IF EXISTS chk_policy_documents_type;
+ALTER TABLE policy_documents
+    ADD CONSTRAINT chk_policy_documents_type CHECK (type IN ('terms_of_service', 'privacy_policy'));
+
+-- CREATE INDEXES
+CREATE UNIQUE INDEX IF NOT EXISTS idx_policy_documents_tenant_type_version ON policy_documents (tenant_id, type, version);
+CREATE INDEX IF NOT EXISTS idx_policy_documents_current ON policy_documents (tenant_id, type, published_at DESC) WHERE published_at IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_policy_acceptances_document_user ON policy_acceptances (policy_document_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_policy_acceptances_user_id ON policy_acceptances (user_id);
+CREATE INDEX IF NOT EXISTS idx_policy_acceptances_tenant_accepted_at ON policy_acceptances (tenant_id, accepted_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS policy_acceptances, policy_documents;
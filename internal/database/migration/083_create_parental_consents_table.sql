@@ -0,0 +1,54 @@
+-- Creates the guardian consent requests of under-age users. Requests are
+-- removed with their user.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS parental_consents (
+    parental_consent_id     BIGSERIAL PRIMARY KEY,
+    parental_consent_uuid   UUID NOT NULL UNIQUE,
+    tenant_id               BIGINT NOT NULL,
+    user_id                 BIGINT NOT NULL,
+    guardian_email          VARCHAR(255) NOT NULL,
+    token                   VARCHAR(64) NOT NULL,
+    status                  VARCHAR(20) NOT NULL DEFAULT 'pending',
+    expires_at              TIMESTAMPTZ NOT NULL,
+    decided_at              TIMESTAMPTZ,
+    ip_address              VARCHAR(45),
+    user_agent              TEXT,
+    created_at              TIMESTAMPTZ DEFAULT now(),
+    updated_at              TIMESTAMPTZ DEFAULT now()
+);
+
+-- ADD FOREIGN KEYS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_parental_consents_tenant_id'
+    ) THEN
+        ALTER TABLE parental_consents
+            ADD CONSTRAINT fk_parental_consents_tenant_id FOREIGN KEY (tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_parental_consents_user_id'
+    ) THEN
+        ALTER TABLE parental_consents
+            ADD CONSTRAINT fk_parental_consents_user_id FOREIGN KEY (user_id)
+            REFERENCES users(user_id) ON DELETE CASCADE;
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- ADD CONSTRAINTS
+ALTER TABLE parental_consents DROP CONSTRAINT IF EXISTS chk_parental_consents_status;
+ALTER TABLE parental_consents
+    ADD CONSTRAINT chk_parental_consents_status CHECK (status IN ('pending', 'approved', 'denied'));
+
+-- CREATE INDEXES
+CREATE UNIQUE INDEX IF NOT EXISTS idx_parental_consents_token ON parental_consents (token);
+CREATE INDEX IF NOT EXISTS idx_parental_consents_user_id ON parental_consents (user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS parental_consents;
@@ -0,0 +1,44 @@
+-- Adds the secondary recovery email of users and the recovery_codes table of
+-- their single-use backup codes. Only hashes of the codes are stored; codes are
+-- removed with their user.
+
+-- +goose Up
+-- ALTER TABLES
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS recovery_email VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS is_recovery_email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    recovery_code_id        BIGSERIAL PRIMARY KEY,
+    recovery_code_uuid      UUID NOT NULL UNIQUE,
+    user_id                 BIGINT NOT NULL,
+    code_hash               VARCHAR(64) NOT NULL,
+    used_at                 TIMESTAMPTZ,
+    created_at              TIMESTAMPTZ DEFAULT now()
+);
+
+-- ADD FOREIGN KEYS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_recovery_codes_user_id'
+    ) THEN
+        ALTER TABLE recovery_codes
+            ADD CONSTRAINT fk_recovery_codes_user_id FOREIGN KEY (user_id)
+            REFERENCES users(user_id) ON DELETE CASCADE;
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- CREATE INDEXES
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id_code_hash ON recovery_codes (user_id, code_hash);
+
+-- +goose Down
+DROP TABLE IF EXISTS recovery_codes;
+
+-- ALTER TABLES
+ALTER TABLE users
+    DROP COLUMN IF EXISTS recovery_email,
+    DROP COLUMN IF EXISTS is_recovery_email_verified;
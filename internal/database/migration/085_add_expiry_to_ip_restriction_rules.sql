@@ -0,0 +1,20 @@
+-- Records where an IP restriction rule came from and when it stops applying.
+-- Brute-force detection adds automatic rules that expire; rules created through
+-- the API are manual and never do.
+
+-- +goose Up
+-- ALTER TABLES
+ALTER TABLE ip_restriction_rules
+    ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'manual',
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+-- CREATE INDEXES
+CREATE INDEX IF NOT EXISTS idx_ip_restriction_rules_tenant_id_status ON ip_restriction_rules (tenant_id, status);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_ip_restriction_rules_tenant_id_status;
+
+-- ALTER TABLES
+ALTER TABLE ip_restriction_rules
+    DROP COLUMN IF EXISTS source,
+    DROP COLUMN IF EXISTS expires_at;
@@ -0,0 +1,55 @@
+-- Creates the audit_archive_runs table that records every pass of the auth
+-- event retention job that archives a tenant's expired events to object
+-- storage.
+
+-- +goose Up
+-- CREATE TABLE
+CREATE TABLE IF NOT EXISTS audit_archive_runs (
+    audit_archive_run_id    BIGSERIAL PRIMARY KEY,
+    audit_archive_run_uuid  UUID NOT NULL UNIQUE,
+    tenant_id               BIGINT NOT NULL,
+    trigger_type            VARCHAR(20) NOT NULL,
+    status                  VARCHAR(20) NOT NULL DEFAULT 'running',
+    cutoff                  TIMESTAMPTZ NOT NULL,
+    event_count             BIGINT NOT NULL DEFAULT 0,
+    object_key              TEXT,
+    size_bytes              BIGINT NOT NULL DEFAULT 0,
+    error                   TEXT,
+    completed_at            TIMESTAMPTZ,
+    created_at              TIMESTAMPTZ DEFAULT now(),
+    updated_at              TIMESTAMPTZ DEFAULT now()
+);
+
+-- ADD CONSTRAINTS
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_audit_archive_runs_tenant_id'
+    ) THEN
+        ALTER TABLE audit_archive_runs
+            ADD CONSTRAINT fk_audit_archive_runs_tenant_id FOREIGN KEY (tenant_id)
+            REFERENCES tenants(tenant_id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'chk_audit_archive_runs_status'
+    ) THEN
+        ALTER TABLE audit_archive_runs
+            ADD CONSTRAINT chk_audit_archive_runs_status CHECK (status IN ('running', 'completed', 'failed'));
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'chk_audit_archive_runs_trigger'
+    ) THEN
+        ALTER TABLE audit_archive_runs
+            ADD CONSTRAINT chk_audit_archive_runs_trigger CHECK (trigger_type IN ('scheduled', 'manual'));
+    END IF;
+END$$;
+-- +goose StatementEnd
+
+-- CREATE INDEXES
+CREATE INDEX IF NOT EXISTS idx_audit_archive_runs_tenant_created ON audit_archive_runs (tenant_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS audit_archive_runs;
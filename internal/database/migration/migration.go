@@ -0,0 +1,11 @@
+// Package migration holds the database migrations, embedded in the binary
+// and applied with goose by the migration runner.
+package migration
+
+import "embed"
+
+// FS holds the migrations. Each file is named <version>_<description>.sql
+// and has a "-- +goose Up" and a "-- +goose Down" section.
+//
+//go:embed *.sql
+var FS embed.FS
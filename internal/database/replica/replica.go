@@ -0,0 +1,106 @@
+// Package replica routes marked read-only queries to Postgres read replicas
+// while every write, every transaction and every unmarked query stays on the
+// primary.
+//
+// Repositories mark a query with Read. Since replicas lag the primary, a
+// marked query on a table this process wrote within the lag window also
+// stays on the primary, so a handler that updates a row and reads it back
+// sees its own write.
+package replica
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// readKey is the gorm setting that marks a query as safe for a replica.
+const readKey = "replica:read"
+
+// Read marks the queries run on the returned db as safe to serve from a read
+// replica. It has no effect without a Plugin or inside a transaction.
+func Read(db *gorm.DB) *gorm.DB {
+	return db.Set(readKey, true)
+}
+
+// Plugin is a gorm.Plugin that sends queries marked with Read to one of its
+// replica pools, round robin.
+type Plugin struct {
+	replicas  []gorm.ConnPool
+	lagWindow time.Duration
+	next      atomic.Uint64
+
+	mu      sync.Mutex
+	written map[string]time.Time
+	now     func() time.Time
+}
+
+// NewPlugin returns a Plugin ready to pass to gorm.DB.Use. A marked query on
+// a table written through the same gorm.DB less than lagWindow ago stays on
+// the primary. With no replicas every query stays on the primary.
+func NewPlugin(replicas []gorm.ConnPool, lagWindow time.Duration) *Plugin {
+	return &Plugin{
+		replicas:  replicas,
+		lagWindow: lagWindow,
+		written:   make(map[string]time.Time),
+		now:       time.Now,
+	}
+}
+
+// Name implements gorm.Plugin.
+func (p *Plugin) Name() string {
+	return "replica"
+}
+
+// Initialize implements gorm.Plugin by registering a before callback on the
+// query and row processors and an after callback on writes.
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Query().Before("gorm:query").Register("replica:route_query", p.route); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("replica:route_row", p.route); err != nil {
+		return err
+	}
+	if err := cb.Create().After("gorm:create").Register("replica:track_create", p.track); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("replica:track_update", p.track); err != nil {
+		return err
+	}
+	return cb.Delete().After("gorm:delete").Register("replica:track_delete", p.track)
+}
+
+func (p *Plugin) route(db *gorm.DB) {
+	if len(p.replicas) == 0 || db.Error != nil {
+		return
+	}
+	if marked, ok := db.Get(readKey); !ok || marked != true {
+		return
+	}
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		return
+	}
+	if p.recentlyWritten(db.Statement.Table) {
+		return
+	}
+	db.Statement.ConnPool = p.replicas[(p.next.Add(1)-1)%uint64(len(p.replicas))]
+}
+
+func (p *Plugin) track(db *gorm.DB) {
+	if len(p.replicas) == 0 || db.Error != nil || db.Statement.Table == "" {
+		return
+	}
+	p.mu.Lock()
+	p.written[db.Statement.Table] = p.now()
+	p.mu.Unlock()
+}
+
+func (p *Plugin) recentlyWritten(table string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	at, ok := p.written[table]
+	return ok && p.now().Sub(at) < p.lagWindow
+}
@@ -0,0 +1,122 @@
+package replica
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type replicaWidget struct {
+	ID   int64
+	Name string
+}
+
+func newMock(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger:                 logger.Default.LogMode(logger.Silent),
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	return db, mock
+}
+
+// newRouted returns a primary with the plugin routing to one replica.
+func newRouted(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, sqlmock.Sqlmock, *Plugin) {
+	t.Helper()
+	primary, primaryMock := newMock(t)
+	replicaDB, replicaMock := newMock(t)
+	pool, err := replicaDB.DB()
+	require.NoError(t, err)
+
+	plugin := NewPlugin([]gorm.ConnPool{pool}, time.Second)
+	require.NoError(t, primary.Use(plugin))
+	return primary, primaryMock, replicaMock, plugin
+}
+
+func widgetRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a")
+}
+
+func TestPlugin(t *testing.T) {
+	assert.Equal(t, "replica", NewPlugin(nil, 0).Name())
+
+	t.Run("marked queries go to the replica", func(t *testing.T) {
+		db, primaryMock, replicaMock, _ := newRouted(t)
+		replicaMock.ExpectQuery(`SELECT`).WillReturnRows(widgetRows())
+		replicaMock.ExpectQuery(`SELECT count`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		var widgets []replicaWidget
+		require.NoError(t, Read(db).Find(&widgets).Error)
+		var count int64
+		require.NoError(t, Read(db).Model(&replicaWidget{}).Count(&count).Error)
+
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+
+	t.Run("unmarked queries stay on the primary", func(t *testing.T) {
+		db, primaryMock, replicaMock, _ := newRouted(t)
+		primaryMock.ExpectQuery(`SELECT`).WillReturnRows(widgetRows())
+
+		var widgets []replicaWidget
+		require.NoError(t, db.Find(&widgets).Error)
+
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+	})
+
+	t.Run("transactions stay on the primary", func(t *testing.T) {
+		db, primaryMock, replicaMock, _ := newRouted(t)
+		primaryMock.ExpectBegin()
+		primaryMock.ExpectQuery(`SELECT`).WillReturnRows(widgetRows())
+		primaryMock.ExpectCommit()
+
+		require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+			var widgets []replicaWidget
+			return Read(tx).Find(&widgets).Error
+		}))
+
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+	})
+
+	t.Run("recently written tables stay on the primary", func(t *testing.T) {
+		db, primaryMock, replicaMock, plugin := newRouted(t)
+		now := time.Now()
+		plugin.now = func() time.Time { return now }
+
+		primaryMock.ExpectExec(`UPDATE "replica_widgets"`).WillReturnResult(sqlmock.NewResult(0, 1))
+		require.NoError(t, db.Model(&replicaWidget{}).Where("id = ?", 1).Update("name", "b").Error)
+
+		primaryMock.ExpectQuery(`SELECT`).WillReturnRows(widgetRows())
+		var widgets []replicaWidget
+		require.NoError(t, Read(db).Find(&widgets).Error)
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+
+		now = now.Add(2 * time.Second)
+		replicaMock.ExpectQuery(`SELECT`).WillReturnRows(widgetRows())
+		require.NoError(t, Read(db).Find(&widgets).Error)
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+	})
+
+	t.Run("without replicas marked queries stay on the primary", func(t *testing.T) {
+		db, mock := newMock(t)
+		require.NoError(t, db.Use(NewPlugin(nil, time.Second)))
+		mock.ExpectQuery(`SELECT`).WillReturnRows(widgetRows())
+
+		var widgets []replicaWidget
+		require.NoError(t, Read(db).Find(&widgets).Error)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
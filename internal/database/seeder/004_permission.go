@@ -52,11 +52,19 @@ func SeedPermissions(db *gorm.DB, tenantID, apiID int64) error {
 		newPermission("account:mfa:enroll:self", "Enroll in MFA (TOTP/WebAuthn)", tenantID, apiID),
 		newPermission("account:mfa:disable:self", "Disable MFA", tenantID, apiID),
 		newPermission("account:mfa:verify:self", "Verify MFA challenge", tenantID, apiID),
+		newPermission("account:recovery:read:self", "View own recovery codes and recovery email", tenantID, apiID),
+		newPermission("account:recovery:update:self", "Manage own recovery codes and recovery email", tenantID, apiID),
 
 		// Authentication
 		newPermission("account:auth:logout:self", "Logout from current session", tenantID, apiID),
 		newPermission("account:auth:refresh-token:self", "Refresh JWT using refresh token", tenantID, apiID),
 		newPermission("account:session:terminate:self", "End own active sessions", tenantID, apiID),
+		newPermission("account:device:read:self", "List own trusted devices", tenantID, apiID),
+		newPermission("account:device:trust:self", "Trust the current device", tenantID, apiID),
+		newPermission("account:device:revoke:self", "Forget own trusted devices", tenantID, apiID),
+		newPermission("account:identity:read:self", "List own linked identities", tenantID, apiID),
+		newPermission("account:identity:link:self", "Link an external identity", tenantID, apiID),
+		newPermission("account:identity:unlink:self", "Unlink an external identity", tenantID, apiID),
 
 		// Token Permissions
 		newPermission("account:token:create:self", "Create API or personal access token", tenantID, apiID),
@@ -86,6 +94,14 @@ func SeedPermissions(db *gorm.DB, tenantID, apiID int64) error {
 		newPermission("tenant:update", "Update tenant", tenantID, apiID),
 		newPermission("tenant:delete", "Delete tenant", tenantID, apiID),
 
+		// Plans
+		newPermission("plan:read", "Read billing plans", tenantID, apiID),
+		newPermission("plan:manage", "Manage billing plans and tenant subscriptions", tenantID, apiID),
+
+		// Feature Flags
+		newPermission("feature-flag:read", "Read feature flags", tenantID, apiID),
+		newPermission("feature-flag:manage", "Manage feature flags and tenant overrides", tenantID, apiID),
+
 		// SERVICE LEVEL ACCESS
 		// Services
 		newPermission("service:read", "Read services", tenantID, apiID),
@@ -107,12 +123,27 @@ func SeedPermissions(db *gorm.DB, tenantID, apiID int64) error {
 		newPermission("permission:update", "Update permission", tenantID, apiID),
 		newPermission("permission:delete", "Delete permission", tenantID, apiID),
 
+		// Permission Groups
+		newPermission("permission-group:read", "Read permission groups", tenantID, apiID),
+		newPermission("permission-group:create", "Create permission group", tenantID, apiID),
+		newPermission("permission-group:update", "Update permission group and its permissions", tenantID, apiID),
+		newPermission("permission-group:delete", "Delete permission group", tenantID, apiID),
+
+		// Groups
+		newPermission("group:read", "Read user groups and their members", tenantID, apiID),
+		newPermission("group:create", "Create user group", tenantID, apiID),
+		newPermission("group:update", "Update user group, its members and its roles", tenantID, apiID),
+		newPermission("group:delete", "Delete user group", tenantID, apiID),
+
 		// Policies
 		newPermission("policy:read", "Read policies", tenantID, apiID),
 		newPermission("policy:create", "Create policy", tenantID, apiID),
 		newPermission("policy:update", "Update policy", tenantID, apiID),
 		newPermission("policy:delete", "Delete policy", tenantID, apiID),
 
+		// Authorization
+		newPermission("authz:simulate", "Simulate access decisions for a user or set of roles", tenantID, apiID),
+
 		// USER LEVEL ACCESS
 		// Roles
 		newPermission("role:read", "Read roles", tenantID, apiID),
@@ -162,6 +193,8 @@ func SeedPermissions(db *gorm.DB, tenantID, apiID int64) error {
 		// User Administration
 		newPermission("user:read", "Read users", tenantID, apiID),
 		newPermission("user:create", "Create user", tenantID, apiID),
+		newPermission("user:import", "Bulk import users", tenantID, apiID),
+		newPermission("user:export", "Bulk export users", tenantID, apiID),
 		newPermission("user:update", "Update user", tenantID, apiID),
 		newPermission("user:delete", "Delete user", tenantID, apiID),
 		newPermission("user:disable", "Disable user", tenantID, apiID),
@@ -174,6 +207,9 @@ func SeedPermissions(db *gorm.DB, tenantID, apiID int64) error {
 		newPermission("auth_event:read", "Read auth events", tenantID, apiID),
 		newPermission("auth_event:delete", "Delete auth events (retention)", tenantID, apiID),
 
+		// Lifecycle Events (feed for downstream read models)
+		newPermission("event:read", "Read user, role and client lifecycle event feed", tenantID, apiID),
+
 		// Signup Flows
 		newPermission("signup-flow:read", "Read signup flows", tenantID, apiID),
 		newPermission("signup-flow:create", "Create signup flow", tenantID, apiID),
@@ -230,6 +266,19 @@ func SeedPermissions(db *gorm.DB, tenantID, apiID int64) error {
 		newPermission("webhook-endpoint:update", "Update webhook endpoint", tenantID, apiID),
 		newPermission("webhook-endpoint:delete", "Delete webhook endpoint", tenantID, apiID),
 
+		// Login Hooks
+		newPermission("login-hook:read", "Read login hooks", tenantID, apiID),
+		newPermission("login-hook:create", "Create login hook", tenantID, apiID),
+		newPermission("login-hook:update", "Update login hook", tenantID, apiID),
+		newPermission("login-hook:delete", "Delete login hook", tenantID, apiID),
+
+		// Policy Documents
+		newPermission("policy-document:read", "Read policy documents and their acceptances", tenantID, apiID),
+		newPermission("policy-document:create", "Create policy document", tenantID, apiID),
+		newPermission("policy-document:update", "Update draft policy document", tenantID, apiID),
+		newPermission("policy-document:delete", "Delete draft policy document", tenantID, apiID),
+		newPermission("policy-document:publish", "Publish policy document", tenantID, apiID),
+
 		// OTHER PERMISSIONS
 		// Email
 		newPermission("email:read-config", "View email delivery config", tenantID, apiID),
@@ -271,6 +320,8 @@ func SeedPermissions(db *gorm.DB, tenantID, apiID int64) error {
 		newPermission("audit:export", "Export logs for compliance", tenantID, apiID),
 		newPermission("system:health-check", "System health metrics", tenantID, apiID),
 		newPermission("system:metrics", "Service-level metrics", tenantID, apiID),
+		newPermission("system:admin-ui", "Open the embedded admin console", tenantID, apiID),
+		newPermission("system:api-docs", "Read the OpenAPI document and API explorer", tenantID, apiID),
 		newPermission("system:trace-events", "Debug/trace-level logs (dev only)", tenantID, apiID),
 
 		// Security Policies
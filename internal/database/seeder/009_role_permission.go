@@ -9,77 +9,61 @@ import (
 	"gorm.io/gorm"
 )
 
-func SeedRolePermissions(db *gorm.DB, roles map[string]model.Role) error {
-	// Get all permissions
-	var permissions []model.Permission
-	if err := db.Find(&permissions).Error; err != nil {
-		return fmt.Errorf("failed to fetch permissions: %w", err)
-	}
+// RegisteredRolePermissions are the account permissions of the registered
+// role every user is assigned.
+var RegisteredRolePermissions = []string{
+	// Account permissions
+	"account:request-verify-email:self",
+	"account:verify-email:self",
+	"account:request-verify-phone:self",
+	"account:verify-phone:self",
+	"account:change-password:self",
+	"account:mfa:enroll:self",
+	"account:mfa:disable:self",
+	"account:mfa:verify:self",
+	"account:recovery:read:self",
+	"account:recovery:update:self",
+	// Authentication
+	"account:auth:logout:self",
+	"account:auth:refresh-token:self",
+	"account:session:terminate:self",
+	"account:device:read:self",
+	"account:device:trust:self",
+	"account:device:revoke:self",
+	"account:identity:read:self",
+	"account:identity:link:self",
+	"account:identity:unlink:self",
+	// Token permissions
+	"account:token:create:self",
+	"account:token:read:self",
+	"account:token:revoke:self",
+	// User data permissions
+	"account:user:read:self",
+	"account:user:update:self",
+	"account:user:delete:self",
+	"account:user:disable:self",
+	// Profile permissions
+	"account:profile:read:self",
+	"account:profile:update:self",
+	"account:profile:delete:self",
+	// Activity logs
+	"account:audit:read:self",
+	// Notification permissions
+	"notification:read-settings",
+	"notification:update-settings",
+	"notification:read-log:self",
+	// Settings permissions
+	"settings:read:self",
+	"settings:update:self",
+}
 
+func SeedRolePermissions(db *gorm.DB, roles map[string]model.Role) error {
 	// Assign account permissions to registered role
 	registeredRole, exists := roles["registered"]
 	if exists {
-		accountPermissions := []string{
-			// Account permissions
-			"account:request-verify-email:self",
-			"account:verify-email:self",
-			"account:request-verify-phone:self",
-			"account:verify-phone:self",
-			"account:change-password:self",
-			"account:mfa:enroll:self",
-			"account:mfa:disable:self",
-			"account:mfa:verify:self",
-			// Authentication
-			"account:auth:logout:self",
-			"account:auth:refresh-token:self",
-			"account:session:terminate:self",
-			// Token permissions
-			"account:token:create:self",
-			"account:token:read:self",
-			"account:token:revoke:self",
-			// User data permissions
-			"account:user:read:self",
-			"account:user:update:self",
-			"account:user:delete:self",
-			"account:user:disable:self",
-			// Profile permissions
-			"account:profile:read:self",
-			"account:profile:update:self",
-			"account:profile:delete:self",
-			// Activity logs
-			"account:audit:read:self",
-		}
-
-		for _, permission := range permissions {
-			// Check if this permission should be assigned to registered role
-			if !slices.Contains(accountPermissions, permission.Name) {
-				continue
-			}
-
-			var existing model.RolePermission
-			err := db.
-				Where("role_id = ? AND permission_id = ?", registeredRole.RoleID, permission.PermissionID).
-				First(&existing).Error
-
-			if err == nil {
-				// Permission already assigned
-				continue
-			}
-
-			if err != gorm.ErrRecordNotFound {
-				return fmt.Errorf("error checking role permission %q for registered: %w", permission.Name, err)
-			}
-
-			rolePermission := model.RolePermission{
-				RoleID:       registeredRole.RoleID,
-				PermissionID: permission.PermissionID,
-			}
-
-			if err := db.Create(&rolePermission).Error; err != nil {
-				return fmt.Errorf("failed to assign permission %q to role %q: %w", permission.Name, registeredRole.Name, err)
-			}
+		if err := AssignRegisteredRolePermissions(db, registeredRole); err != nil {
+			return err
 		}
-
 		slog.Info("Account permissions assigned to registered role")
 	} else {
 		slog.Warn("Registered role not found, skipping account permission assignment")
@@ -91,11 +75,45 @@ func SeedRolePermissions(db *gorm.DB, roles map[string]model.Role) error {
 		slog.Warn("Super-admin role not found, skipping permission assignment")
 		return nil
 	}
+	if err := AssignAdminRolePermissions(db, superAdminRole); err != nil {
+		return err
+	}
+
+	slog.Info("All permissions assigned to super-admin role")
+	return nil
+}
+
+// AssignRegisteredRolePermissions grants role the RegisteredRolePermissions
+// it does not hold yet.
+func AssignRegisteredRolePermissions(db *gorm.DB, role model.Role) error {
+	return assignRolePermissions(db, role, func(name string) bool {
+		return slices.Contains(RegisteredRolePermissions, name)
+	})
+}
+
+// AssignAdminRolePermissions grants role every permission it does not hold
+// yet, as the super-admin role has.
+func AssignAdminRolePermissions(db *gorm.DB, role model.Role) error {
+	return assignRolePermissions(db, role, func(string) bool { return true })
+}
+
+// assignRolePermissions grants role the permissions whose name include
+// accepts, skipping those it already holds.
+func assignRolePermissions(db *gorm.DB, role model.Role, include func(name string) bool) error {
+	// Get all permissions
+	var permissions []model.Permission
+	if err := db.Find(&permissions).Error; err != nil {
+		return fmt.Errorf("failed to fetch permissions: %w", err)
+	}
 
 	for _, permission := range permissions {
+		if !include(permission.Name) {
+			continue
+		}
+
 		var existing model.RolePermission
 		err := db.
-			Where("role_id = ? AND permission_id = ?", superAdminRole.RoleID, permission.PermissionID).
+			Where("role_id = ? AND permission_id = ?", role.RoleID, permission.PermissionID).
 			First(&existing).Error
 
 		if err == nil {
@@ -104,19 +122,17 @@ func SeedRolePermissions(db *gorm.DB, roles map[string]model.Role) error {
 		}
 
 		if err != gorm.ErrRecordNotFound {
-			return fmt.Errorf("error checking role permission %q for super-admin: %w", permission.Name, err)
+			return fmt.Errorf("error checking role permission %q for %s: %w", permission.Name, role.Name, err)
 		}
 
 		rolePermission := model.RolePermission{
-			RoleID:       superAdminRole.RoleID,
+			RoleID:       role.RoleID,
 			PermissionID: permission.PermissionID,
 		}
 
 		if err := db.Create(&rolePermission).Error; err != nil {
-			return fmt.Errorf("failed to assign permission %q to role %q: %w", permission.Name, superAdminRole.Name, err)
+			return fmt.Errorf("failed to assign permission %q to role %q: %w", permission.Name, role.Name, err)
 		}
 	}
-
-	slog.Info("All permissions assigned to super-admin role")
 	return nil
 }
@@ -24,6 +24,76 @@ func SeedEmailTemplates(db *gorm.DB, tenantID int64) error {
 			emailtemplate.ForgotPasswordEmailHTML,
 			emailtemplate.ForgotPasswordEmailPlain,
 		),
+		newEmailTemplate(
+			tenantID,
+			"internal:user:email:verification",
+			"Verify Your Email",
+			emailtemplate.EmailVerificationEmailHTML,
+			emailtemplate.EmailVerificationEmailPlain,
+		),
+		newEmailTemplate(
+			tenantID,
+			"internal:user:account:deletion",
+			"Your Account Is Scheduled for Deletion",
+			emailtemplate.AccountDeletionEmailHTML,
+			emailtemplate.AccountDeletionEmailPlain,
+		),
+		newEmailTemplate(
+			tenantID,
+			"internal:user:account:disabled",
+			"Your Account Is Disabled",
+			emailtemplate.AccountDisabledEmailHTML,
+			emailtemplate.AccountDisabledEmailPlain,
+		),
+		newEmailTemplate(
+			tenantID,
+			"internal:user:password:changed",
+			"Your Password Was Changed",
+			emailtemplate.PasswordChangedEmailHTML,
+			emailtemplate.PasswordChangedEmailPlain,
+		),
+		newEmailTemplate(
+			tenantID,
+			"internal:user:login:new_device",
+			"New Sign In to Your Account",
+			emailtemplate.NewDeviceLoginEmailHTML,
+			emailtemplate.NewDeviceLoginEmailPlain,
+		),
+		newEmailTemplate(
+			tenantID,
+			"internal:user:parental:consent",
+			"Your Consent Is Needed",
+			emailtemplate.ParentalConsentEmailHTML,
+			emailtemplate.ParentalConsentEmailPlain,
+		),
+		newEmailTemplate(
+			tenantID,
+			"internal:user:recovery:email:verification",
+			"Verify Your Recovery Email",
+			emailtemplate.RecoveryEmailVerificationEmailHTML,
+			emailtemplate.RecoveryEmailVerificationEmailPlain,
+		),
+		newEmailTemplate(
+			tenantID,
+			"internal:user:account:recovery",
+			"Recover Your Account",
+			emailtemplate.AccountRecoveryEmailHTML,
+			emailtemplate.AccountRecoveryEmailPlain,
+		),
+		newEmailTemplate(
+			tenantID,
+			"internal:user:account:recovered",
+			"Your Account Was Recovered",
+			emailtemplate.AccountRecoveredEmailHTML,
+			emailtemplate.AccountRecoveredEmailPlain,
+		),
+		newEmailTemplate(
+			tenantID,
+			"internal:user:account:security_hold",
+			"Your Account Is On Hold",
+			emailtemplate.SecurityHoldEmailHTML,
+			emailtemplate.SecurityHoldEmailPlain,
+		),
 	}
 
 	for _, t := range templates {
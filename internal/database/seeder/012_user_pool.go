@@ -0,0 +1,53 @@
+package seeder
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// SeedUserPool creates the system user pool of a tenant, which holds the
+// tenant's security settings.
+func SeedUserPool(db *gorm.DB, tenantID int64) (*model.UserPool, error) {
+	var existing model.UserPool
+	err := db.
+		Where("tenant_id = ? AND is_system = ? AND deleted_at IS NULL", tenantID, true).
+		First(&existing).Error
+
+	if err == nil {
+		slog.Info("System user pool already exists, skipping", "id", existing.UserPoolID)
+		return &existing, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		slog.Error("Error checking system user pool", "error", err)
+		return nil, err
+	}
+
+	poolIdentifier, err := crypto.GenerateIdentifier(12)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate identifier: %w", err)
+	}
+
+	pool := model.UserPool{
+		TenantID:    tenantID,
+		Name:        "default",
+		DisplayName: "Default User Pool",
+		Identifier:  fmt.Sprintf("pool-%s", poolIdentifier),
+		IsDefault:   true,
+		IsSystem:    true,
+		Status:      model.StatusActive,
+		Metadata:    datatypes.JSON([]byte("{}")),
+	}
+
+	if err := db.Create(&pool).Error; err != nil {
+		slog.Error("Failed to seed system user pool", "error", err)
+		return nil, err
+	}
+
+	slog.Info("System user pool seeded", "id", pool.UserPoolID)
+	return &pool, nil
+}
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
+)
+
+// AccountDeletionResponseDTO describes an account scheduled for deletion.
+type AccountDeletionResponseDTO struct {
+	UserUUID     uuid.UUID `json:"user_id"`
+	ScheduledFor time.Time `json:"scheduled_for"`
+}
+
+// AccountDeletionCancelRequestDTO is the body of a request to cancel a
+// scheduled account deletion, carrying the token from the confirmation
+// email.
+type AccountDeletionCancelRequestDTO struct {
+	Token string `json:"token"`
+}
+
+func (r AccountDeletionCancelRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Token,
+			validation.Required.Error("Token is required"),
+			validation.Length(64, 64).Error("Token must be 64 characters"),
+			is.Hexadecimal.Error("Token must be hexadecimal"),
+		),
+	)
+}
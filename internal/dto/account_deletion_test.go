@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountDeletionCancelRequestDTO_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		r := AccountDeletionCancelRequestDTO{Token: strings.Repeat("ab", 32)}
+		assert.NoError(t, r.Validate())
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		assert.Error(t, AccountDeletionCancelRequestDTO{}.Validate())
+	})
+
+	t.Run("wrong length", func(t *testing.T) {
+		r := AccountDeletionCancelRequestDTO{Token: "abcd"}
+		assert.Error(t, r.Validate())
+	})
+
+	t.Run("not hexadecimal", func(t *testing.T) {
+		r := AccountDeletionCancelRequestDTO{Token: strings.Repeat("zz", 32)}
+		assert.Error(t, r.Validate())
+	})
+}
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+// AccountIdentityLinkRequestDTO is the body of a request to link an external
+// identity to the caller's account. IDToken is an ID token the upstream
+// provider issued to the auth client identified by ClientUUID; Password
+// re-authenticates the caller.
+type AccountIdentityLinkRequestDTO struct {
+	ClientUUID string `json:"client_id"`
+	IDToken    string `json:"id_token"`
+	Password   string `json:"password"`
+}
+
+func (r AccountIdentityLinkRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ClientUUID,
+			validation.Required.Error("Client ID is required"),
+			is.UUID.Error("Client ID must be a valid UUID"),
+		),
+		validation.Field(&r.IDToken,
+			validation.Required.Error("ID token is required"),
+			validation.Length(1, 16384).Error("ID token must not exceed 16384 characters"),
+		),
+		validation.Field(&r.Password,
+			validation.Required.Error("Password is required"),
+		),
+	)
+}
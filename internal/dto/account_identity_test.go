@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountIdentityLinkRequestDTO_Validate(t *testing.T) {
+	valid := AccountIdentityLinkRequestDTO{
+		ClientUUID: "6fa459ea-ee8a-3ca4-894e-db77e160355e",
+		IDToken:    "eyJhbGciOiJSUzI1NiJ9.e30.c2ln",
+		Password:   "Secret-123",
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, valid.Validate())
+	})
+
+	t.Run("invalid client id", func(t *testing.T) {
+		r := valid
+		r.ClientUUID = "google"
+		assert.Error(t, r.Validate())
+	})
+
+	t.Run("missing id token", func(t *testing.T) {
+		r := valid
+		r.IDToken = ""
+		assert.Error(t, r.Validate())
+	})
+
+	t.Run("oversized id token", func(t *testing.T) {
+		r := valid
+		r.IDToken = strings.Repeat("a", 16385)
+		assert.Error(t, r.Validate())
+	})
+
+	t.Run("missing password", func(t *testing.T) {
+		r := valid
+		r.Password = ""
+		assert.Error(t, r.Validate())
+	})
+}
@@ -0,0 +1,26 @@
+package dto
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// AccountChangePasswordRequestDTO is the body of a request to change the
+// caller's own password. RefreshToken identifies the session the request is
+// made from, which stays signed in; it may instead come from the
+// refresh_token cookie.
+type AccountChangePasswordRequestDTO struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+	RefreshToken    string `json:"refresh_token"`
+}
+
+func (r AccountChangePasswordRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.CurrentPassword,
+			validation.Required.Error("Current password is required"),
+		),
+		validation.Field(&r.NewPassword,
+			validation.Required.Error("New password is required"),
+		),
+	)
+}
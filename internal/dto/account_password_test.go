@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountChangePasswordRequestDTO_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		r := AccountChangePasswordRequestDTO{CurrentPassword: "Old-Secret1", NewPassword: "New-Secret2"}
+		assert.NoError(t, r.Validate())
+	})
+
+	t.Run("missing current password", func(t *testing.T) {
+		r := AccountChangePasswordRequestDTO{NewPassword: "New-Secret2"}
+		assert.Error(t, r.Validate())
+	})
+
+	t.Run("missing new password", func(t *testing.T) {
+		r := AccountChangePasswordRequestDTO{CurrentPassword: "Old-Secret1"}
+		assert.Error(t, r.Validate())
+	})
+}
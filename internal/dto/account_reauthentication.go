@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// AccountReauthenticateRequestDTO is the body of a request to re-enter the
+// caller's password before a step-up protected action.
+type AccountReauthenticateRequestDTO struct {
+	Password string `json:"password"`
+}
+
+func (r AccountReauthenticateRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Password,
+			validation.Required.Error("Password is required"),
+		),
+	)
+}
+
+// AccountReauthenticateResponseDTO tells until when the caller's credential
+// passes step-up.
+type AccountReauthenticateResponseDTO struct {
+	ReauthenticatedAt time.Time `json:"reauthenticated_at"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
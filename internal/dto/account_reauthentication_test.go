@@ -0,0 +1,12 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountReauthenticateRequestDTO_Validate(t *testing.T) {
+	assert.NoError(t, AccountReauthenticateRequestDTO{Password: "Secret-123"}.Validate())
+	assert.Error(t, AccountReauthenticateRequestDTO{}.Validate())
+}
@@ -0,0 +1,129 @@
+package dto
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/maintainerd/auth/internal/security"
+)
+
+// AccountRecoveryCodesRequestDTO is the body of a request for a new set of
+// recovery codes. The current password confirms it is the user asking.
+type AccountRecoveryCodesRequestDTO struct {
+	Password string `json:"password"`
+}
+
+func (r AccountRecoveryCodesRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Password,
+			validation.Required.Error("Password is required"),
+		),
+	)
+}
+
+// AccountRecoveryEmailRequestDTO is the body of a request to set the
+// recovery email.
+type AccountRecoveryEmailRequestDTO struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (r *AccountRecoveryEmailRequestDTO) Validate() error {
+	r.Email = security.SanitizeInput(r.Email)
+
+	return validation.ValidateStruct(r,
+		validation.Field(&r.Email,
+			validation.Required.Error("Email is required"),
+			is.Email.Error("Email must be a valid email address"),
+			validation.Length(1, 255).Error("Email must not exceed 255 characters"),
+		),
+		validation.Field(&r.Password,
+			validation.Required.Error("Password is required"),
+		),
+	)
+}
+
+// AccountRecoveryEmailVerifyRequestDTO carries the code emailed to a new
+// recovery email.
+type AccountRecoveryEmailVerifyRequestDTO struct {
+	Code string `json:"code"`
+}
+
+func (r AccountRecoveryEmailVerifyRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Code,
+			validation.Required.Error("Code is required"),
+			validation.Length(6, 6).Error("Code must be 6 digits"),
+			is.Digit.Error("Code must be 6 digits"),
+		),
+	)
+}
+
+// AccountRecoveryStartRequestDTO is the body of a request to start an
+// account recovery.
+type AccountRecoveryStartRequestDTO struct {
+	Email string `json:"email"`
+}
+
+func (r *AccountRecoveryStartRequestDTO) Validate() error {
+	r.Email = security.SanitizeInput(r.Email)
+
+	return validation.ValidateStruct(r,
+		validation.Field(&r.Email,
+			validation.Required.Error("Email is required"),
+			is.Email.Error("Email must be a valid email address"),
+			validation.Length(1, 255).Error("Email must not exceed 255 characters"),
+		),
+	)
+}
+
+// AccountRecoveryRequestDTO is the body of a request to recover an account:
+// one of the user's recovery codes, the code emailed to the recovery email,
+// and the new password.
+type AccountRecoveryRequestDTO struct {
+	Email        string `json:"email"`
+	RecoveryCode string `json:"recovery_code"`
+	Code         string `json:"code"`
+	NewPassword  string `json:"new_password"`
+}
+
+func (r *AccountRecoveryRequestDTO) Validate() error {
+	r.Email = security.SanitizeInput(r.Email)
+
+	return validation.ValidateStruct(r,
+		validation.Field(&r.Email,
+			validation.Required.Error("Email is required"),
+			is.Email.Error("Email must be a valid email address"),
+			validation.Length(1, 255).Error("Email must not exceed 255 characters"),
+		),
+		validation.Field(&r.RecoveryCode,
+			validation.Required.Error("Recovery code is required"),
+			validation.Length(1, 20).Error("Recovery code must not exceed 20 characters"),
+		),
+		validation.Field(&r.Code,
+			validation.Required.Error("Code is required"),
+			validation.Length(6, 6).Error("Code must be 6 digits"),
+			is.Digit.Error("Code must be 6 digits"),
+		),
+		validation.Field(&r.NewPassword,
+			validation.Required.Error("New password is required"),
+		),
+	)
+}
+
+// AccountRecoveryResponseDTO describes the user's recovery factors.
+type AccountRecoveryResponseDTO struct {
+	RecoveryCodesRemaining int64   `json:"recovery_codes_remaining"`
+	RecoveryEmail          *string `json:"recovery_email"`
+	RecoveryEmailVerified  bool    `json:"recovery_email_verified"`
+}
+
+// AccountRecoveryCodesResponseDTO carries a new set of recovery codes. They
+// are shown this once.
+type AccountRecoveryCodesResponseDTO struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// AccountRecoveredResponseDTO is returned once an account is recovered.
+type AccountRecoveredResponseDTO struct {
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountRecoveryEmailRequestDTO_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		r := AccountRecoveryEmailRequestDTO{Email: "backup@example.com", Password: "Secret-123"}
+		assert.NoError(t, r.Validate())
+	})
+	t.Run("invalid email", func(t *testing.T) {
+		r := AccountRecoveryEmailRequestDTO{Email: "not-an-email", Password: "Secret-123"}
+		assert.Error(t, r.Validate())
+	})
+	t.Run("missing password", func(t *testing.T) {
+		r := AccountRecoveryEmailRequestDTO{Email: "backup@example.com"}
+		assert.Error(t, r.Validate())
+	})
+}
+
+func TestAccountRecoveryEmailVerifyRequestDTO_Validate(t *testing.T) {
+	assert.NoError(t, AccountRecoveryEmailVerifyRequestDTO{Code: "123456"}.Validate())
+	assert.Error(t, AccountRecoveryEmailVerifyRequestDTO{Code: "12345"}.Validate())
+	assert.Error(t, AccountRecoveryEmailVerifyRequestDTO{Code: "12a456"}.Validate())
+}
+
+func TestAccountRecoveryRequestDTO_Validate(t *testing.T) {
+	valid := func() AccountRecoveryRequestDTO {
+		return AccountRecoveryRequestDTO{
+			Email:        "jane@example.com",
+			RecoveryCode: "abcde-fghij",
+			Code:         "123456",
+			NewPassword:  "N3w-Passw0rd!",
+		}
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		r := valid()
+		assert.NoError(t, r.Validate())
+	})
+	t.Run("missing recovery code", func(t *testing.T) {
+		r := valid()
+		r.RecoveryCode = ""
+		assert.Error(t, r.Validate())
+	})
+	t.Run("malformed email code", func(t *testing.T) {
+		r := valid()
+		r.Code = "abcdef"
+		assert.Error(t, r.Validate())
+	})
+	t.Run("missing new password", func(t *testing.T) {
+		r := valid()
+		r.NewPassword = ""
+		assert.Error(t, r.Validate())
+	})
+}
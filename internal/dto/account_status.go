@@ -0,0 +1,22 @@
+package dto
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+// AccountReactivateRequestDTO is the body of a request to reactivate a
+// disabled account, carrying the token from the confirmation email.
+type AccountReactivateRequestDTO struct {
+	Token string `json:"token"`
+}
+
+func (r AccountReactivateRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Token,
+			validation.Required.Error("Token is required"),
+			validation.Length(64, 64).Error("Token must be 64 characters"),
+			is.Hexadecimal.Error("Token must be hexadecimal"),
+		),
+	)
+}
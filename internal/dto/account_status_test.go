@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountReactivateRequestDTO_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		r := AccountReactivateRequestDTO{Token: strings.Repeat("cd", 32)}
+		assert.NoError(t, r.Validate())
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		assert.Error(t, AccountReactivateRequestDTO{}.Validate())
+	})
+
+	t.Run("wrong length", func(t *testing.T) {
+		r := AccountReactivateRequestDTO{Token: strings.Repeat("cd", 31)}
+		assert.Error(t, r.Validate())
+	})
+
+	t.Run("not hexadecimal", func(t *testing.T) {
+		r := AccountReactivateRequestDTO{Token: strings.Repeat("xy", 32)}
+		assert.Error(t, r.Validate())
+	})
+}
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"regexp"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -12,19 +13,24 @@ import (
 
 // API output structure
 type APIResponseDTO struct {
-	APIUUID     uuid.UUID           `json:"api_id"`
-	Name        string              `json:"name"`
-	DisplayName string              `json:"display_name"`
-	Description string              `json:"description"`
-	APIType     string              `json:"api_type"`
-	Identifier  string              `json:"identifier"`
-	Service     *ServiceResponseDTO `json:"service,omitempty"`
-	Status      string              `json:"status"`
-	IsSystem    bool                `json:"is_system"`
-	CreatedAt   time.Time           `json:"created_at"`
-	UpdatedAt   time.Time           `json:"updated_at"`
+	APIUUID     uuid.UUID               `json:"api_id"`
+	Name        string                  `json:"name"`
+	DisplayName string                  `json:"display_name"`
+	Description string                  `json:"description"`
+	APIType     string                  `json:"api_type"`
+	Identifier  string                  `json:"identifier"`
+	Service     *ServiceResponseDTO     `json:"service,omitempty"`
+	Status      string                  `json:"status"`
+	IsSystem    bool                    `json:"is_system"`
+	Deprecation *DeprecationResponseDTO `json:"deprecation,omitempty"`
+	CreatedAt   time.Time               `json:"created_at"`
+	UpdatedAt   time.Time               `json:"updated_at"`
 }
 
+// apiIdentifierPattern accepts an http(s) URI such as
+// https://billing.example.com/v1 or a lowercase slug such as billing-api.
+var apiIdentifierPattern = regexp.MustCompile(`^(https?://[A-Za-z0-9.-]+(:[0-9]+)?(/[A-Za-z0-9._~/-]*)?|[a-z0-9][a-z0-9._:-]*)$`)
+
 // Create API request DTO
 type APICreateRequestDTO struct {
 	Name        string `json:"name"`
@@ -33,6 +39,9 @@ type APICreateRequestDTO struct {
 	APIType     string `json:"api_type"`
 	Status      string `json:"status"`
 	ServiceUUID string `json:"service_id"`
+	// Identifier is the audience clients request tokens for. It cannot be
+	// changed later; one is generated when empty.
+	Identifier string `json:"identifier,omitempty"`
 }
 
 // Validation
@@ -63,6 +72,10 @@ func (r APICreateRequestDTO) Validate() error {
 			validation.Required.Error("Service ID is required"),
 			is.UUID.Error("Service ID must be a valid UUID"),
 		),
+		validation.Field(&r.Identifier,
+			validation.Length(3, 255).Error("Identifier must be between 3 and 255 characters"),
+			validation.Match(apiIdentifierPattern).Error("Identifier must be an http(s) URI or a lowercase slug"),
+		),
 	)
 }
 
@@ -79,7 +79,7 @@ func (r APIKeyStatusUpdateDTO) Validate() error {
 	return validation.ValidateStruct(&r,
 		validation.Field(&r.Status,
 			validation.Required.Error("Status is required"),
-			validation.In(model.StatusActive, model.StatusInactive).Error("Status must be either 'active' or 'inactive'"),
+			validation.In(model.StatusActive, model.StatusInactive, model.StatusRevoked).Error("Status must be 'active', 'inactive' or 'revoked'"),
 		),
 	)
 }
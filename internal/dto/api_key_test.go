@@ -79,6 +79,7 @@ func TestAPIKeyGetRequestDto_Validate(t *testing.T) {
 func TestAPIKeyStatusUpdateDto_Validate(t *testing.T) {
 	assert.NoError(t, APIKeyStatusUpdateDTO{Status: model.StatusActive}.Validate())
 	assert.NoError(t, APIKeyStatusUpdateDTO{Status: model.StatusInactive}.Validate())
+	assert.NoError(t, APIKeyStatusUpdateDTO{Status: model.StatusRevoked}.Validate())
 	require.Error(t, APIKeyStatusUpdateDTO{Status: ""}.Validate())
 	require.Error(t, APIKeyStatusUpdateDTO{Status: "bad"}.Validate())
 }
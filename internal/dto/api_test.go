@@ -67,6 +67,19 @@ func TestAPICreateRequestDto_Validate(t *testing.T) {
 		d.ServiceUUID = "not-a-uuid"
 		require.Error(t, d.Validate())
 	})
+
+	t.Run("identifier", func(t *testing.T) {
+		for _, identifier := range []string{"https://billing.example.com", "https://api.example.com:8443/v1", "billing-api", "urn:billing"} {
+			d := validAPICreate()
+			d.Identifier = identifier
+			assert.NoError(t, d.Validate(), identifier)
+		}
+		for _, identifier := range []string{"ab", "Billing API", "ftp://billing.example.com", "https://billing.example.com?x=1", "-billing"} {
+			d := validAPICreate()
+			d.Identifier = identifier
+			assert.Error(t, d.Validate(), identifier)
+		}
+	})
 }
 
 func TestAPIUpdateRequestDto_Validate(t *testing.T) {
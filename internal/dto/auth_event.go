@@ -16,6 +16,8 @@ type AuthEventFilterDTO struct {
 	DateFrom  *string `json:"date_from"`
 	DateTo    *string `json:"date_to"`
 	PaginationRequestDTO
+	// Cursor, when set, replaces page/limit.
+	Cursor *CursorPaginationRequestDTO `json:"-"`
 }
 
 // Validate validates the filter parameters.
@@ -51,38 +53,64 @@ func (f AuthEventFilterDTO) Validate() error {
 			validation.Length(1, 60).Error("EventType cannot exceed 60 characters"),
 		),
 		validation.Field(&f.Page,
+			validation.Skip.When(f.Cursor != nil),
 			validation.Required.Error("Page is required"),
 			validation.Min(1).Error("Page must be greater than 0"),
 		),
 		validation.Field(&f.Limit,
+			validation.Skip.When(f.Cursor != nil),
 			validation.Required.Error("Limit is required"),
 			validation.Min(1).Error("Limit must be greater than 0"),
 			validation.Max(100).Error("Limit cannot exceed 100"),
 		),
 		validation.Field(&f.SortBy,
+			validation.Skip.When(f.Cursor != nil),
 			validation.Length(0, 50).Error("SortBy cannot exceed 50 characters"),
 		),
 		validation.Field(&f.SortOrder,
+			validation.Skip.When(f.Cursor != nil),
 			validation.In(SortOrderAsc, SortOrderDesc).Error("Order must be either 'asc' or 'desc'"),
 		),
+		validation.Field(&f.Cursor),
 	)
 }
 
 // AuthEventResponseDTO is the API response for a single auth event.
 type AuthEventResponseDTO struct {
-	AuthEventID  string          `json:"auth_event_id"`
-	TenantID     int64           `json:"tenant_id"`
-	ActorUserID  *int64          `json:"actor_user_id,omitempty"`
-	TargetUserID *int64          `json:"target_user_id,omitempty"`
-	IPAddress    string          `json:"ip_address"`
-	UserAgent    *string         `json:"user_agent,omitempty"`
-	Category     string          `json:"category"`
-	EventType    string          `json:"event_type"`
-	Severity     string          `json:"severity"`
-	Result       string          `json:"result"`
-	Description  *string         `json:"description,omitempty"`
-	ErrorReason  *string         `json:"error_reason,omitempty"`
-	TraceID      *string         `json:"trace_id,omitempty"`
-	Metadata     *map[string]any `json:"metadata,omitempty"`
-	CreatedAt    time.Time       `json:"created_at"`
+	AuthEventID    string          `json:"auth_event_id"`
+	TenantID       int64           `json:"tenant_id"`
+	ActorUserID    *int64          `json:"actor_user_id,omitempty"`
+	TargetUserID   *int64          `json:"target_user_id,omitempty"`
+	IPAddress      string          `json:"ip_address"`
+	UserAgent      *string         `json:"user_agent,omitempty"`
+	Country        *string         `json:"country,omitempty"`
+	City           *string         `json:"city,omitempty"`
+	ASN            *int64          `json:"asn,omitempty"`
+	ASOrganization *string         `json:"as_organization,omitempty"`
+	Category       string          `json:"category"`
+	EventType      string          `json:"event_type"`
+	Severity       string          `json:"severity"`
+	Result         string          `json:"result"`
+	Description    *string         `json:"description,omitempty"`
+	ErrorReason    *string         `json:"error_reason,omitempty"`
+	TraceID        *string         `json:"trace_id,omitempty"`
+	Metadata       *map[string]any `json:"metadata,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// AuditArchiveRunResponseDTO is the API response for a run that archived
+// expired auth events to object storage. DownloadURL is only set when a
+// single run is fetched and expires after a few minutes.
+type AuditArchiveRunResponseDTO struct {
+	AuditArchiveRunID string     `json:"audit_archive_run_id"`
+	Trigger           string     `json:"trigger"`
+	Status            string     `json:"status"`
+	Cutoff            time.Time  `json:"cutoff"`
+	EventCount        int64      `json:"event_count"`
+	ObjectKey         *string    `json:"object_key,omitempty"`
+	SizeBytes         int64      `json:"size_bytes"`
+	Error             *string    `json:"error,omitempty"`
+	DownloadURL       *string    `json:"download_url,omitempty"`
+	CompletedAt       *time.Time `json:"completed_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
 }
@@ -0,0 +1,92 @@
+package dto
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+)
+
+// AuthzSimulateCheckDTO is one action, and optionally the resource it
+// targets, to evaluate in a simulation.
+type AuthzSimulateCheckDTO struct {
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+}
+
+// Validate validates a simulated check.
+func (c AuthzSimulateCheckDTO) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Action,
+			validation.Required.Error("Action is required"),
+			validation.Length(1, 255).Error("Action must be between 1 and 255 characters"),
+		),
+		validation.Field(&c.Resource,
+			validation.Length(0, 255).Error("Resource must not exceed 255 characters"),
+		),
+	)
+}
+
+// AuthzSimulateRequestDTO is the request body for simulating access
+// decisions. The subject is the user's roles, the listed roles, or both.
+type AuthzSimulateRequestDTO struct {
+	UserUUID  *uuid.UUID              `json:"user_id"`
+	RoleUUIDs []uuid.UUID             `json:"role_ids"`
+	Checks    []AuthzSimulateCheckDTO `json:"checks"`
+}
+
+// Validate validates the simulation request.
+func (r AuthzSimulateRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.RoleUUIDs,
+			validation.When(r.UserUUID == nil, validation.Required.Error("User ID or role IDs are required")),
+			validation.Length(0, 50).Error("Role IDs must not exceed 50 entries"),
+		),
+		validation.Field(&r.Checks,
+			validation.Required.Error("Checks are required"),
+			validation.Length(1, 100).Error("Checks must contain between 1 and 100 entries"),
+		),
+	)
+}
+
+// AuthzSimulationRoleResponseDTO is a role evaluated by a simulation. Source
+// is "user" for roles the user holds, "group" for roles they hold through a
+// group and "hypothetical" for listed roles.
+type AuthzSimulationRoleResponseDTO struct {
+	RoleID string `json:"role_id"`
+	Name   string `json:"name"`
+	Source string `json:"source"`
+}
+
+// AuthzSimulationPolicyResponseDTO is a policy statement that matched a
+// simulated check.
+type AuthzSimulationPolicyResponseDTO struct {
+	PolicyID       string `json:"policy_id"`
+	Name           string `json:"name"`
+	Version        string `json:"version"`
+	Effect         string `json:"effect"`
+	StatementIndex int    `json:"statement_index"`
+	Conditional    bool   `json:"conditional"`
+}
+
+// AuthzSimulationDecisionResponseDTO is the outcome of one simulated check.
+type AuthzSimulationDecisionResponseDTO struct {
+	Action          string                             `json:"action"`
+	Resource        string                             `json:"resource,omitempty"`
+	Decision        string                             `json:"decision"`
+	Reason          string                             `json:"reason"`
+	GrantingRoles   []AuthzSimulationRoleResponseDTO   `json:"granting_roles"`
+	MatchedPolicies []AuthzSimulationPolicyResponseDTO `json:"matched_policies"`
+}
+
+// AuthzSimulationResponseDTO is the result of a simulation.
+type AuthzSimulationResponseDTO struct {
+	Roles     []AuthzSimulationRoleResponseDTO     `json:"roles"`
+	Decisions []AuthzSimulationDecisionResponseDTO `json:"decisions"`
+}
+
+// AuthzRoutePermissionResponseDTO is the permission requirement of one route.
+// Any one of Permissions grants access.
+type AuthzRoutePermissionResponseDTO struct {
+	Method      string   `json:"method"`
+	Pattern     string   `json:"pattern"`
+	Permissions []string `json:"permissions"`
+}
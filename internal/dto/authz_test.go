@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthzSimulateRequestDTO_Validate(t *testing.T) {
+	userID := uuid.New()
+	check := []AuthzSimulateCheckDTO{{Action: "user:create"}}
+
+	assert.NoError(t, AuthzSimulateRequestDTO{UserUUID: &userID, Checks: check}.Validate())
+	assert.NoError(t, AuthzSimulateRequestDTO{RoleUUIDs: []uuid.UUID{uuid.New()}, Checks: check}.Validate())
+	assert.NoError(t, AuthzSimulateRequestDTO{UserUUID: &userID, RoleUUIDs: []uuid.UUID{uuid.New()}, Checks: []AuthzSimulateCheckDTO{{Action: "user:create", Resource: "auth:*"}}}.Validate())
+
+	assert.Error(t, AuthzSimulateRequestDTO{Checks: check}.Validate(), "subject is required")
+	assert.Error(t, AuthzSimulateRequestDTO{UserUUID: &userID}.Validate(), "checks are required")
+	assert.Error(t, AuthzSimulateRequestDTO{UserUUID: &userID, Checks: []AuthzSimulateCheckDTO{{}}}.Validate(), "action is required")
+	assert.Error(t, AuthzSimulateRequestDTO{UserUUID: &userID, Checks: []AuthzSimulateCheckDTO{{Action: "a", Resource: strings.Repeat("r", 256)}}}.Validate())
+	assert.Error(t, AuthzSimulateRequestDTO{UserUUID: &userID, Checks: make([]AuthzSimulateCheckDTO, 101)}.Validate())
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// BrowserSessionResponseDTO describes a browser session. The session itself
+// travels in an HttpOnly cookie; CSRFToken must be sent in the X-CSRF-Token
+// header of every state-changing request.
+type BrowserSessionResponseDTO struct {
+	UserID    string    `json:"user_id"`
+	ClientID  string    `json:"client_id"`
+	CSRFToken string    `json:"csrf_token"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// StepUpRequired is set when the sign-in looked suspicious and the
+	// tenant requires step-up authentication for such logins.
+	StepUpRequired bool                   `json:"step_up_required,omitempty"`
+	User           *BrowserSessionUserDTO `json:"user,omitempty"`
+}
+
+// BrowserSessionUserDTO is the signed-in user returned by GET /session/me.
+type BrowserSessionUserDTO struct {
+	UserID          string `json:"user_id"`
+	Username        string `json:"username"`
+	Fullname        string `json:"fullname"`
+	Email           string `json:"email"`
+	IsEmailVerified bool   `json:"is_email_verified"`
+	Status          string `json:"status"`
+}
@@ -11,9 +11,53 @@ import (
 	"github.com/maintainerd/auth/internal/model"
 )
 
+// ClientSecretResponseDTO returns an auth client's credentials. The secret
+// is masked unless it was revealed, which Masked reports.
 type ClientSecretResponseDTO struct {
 	ClientID     string  `json:"client_id"`
 	ClientSecret *string `json:"client_secret"`
+	Masked       bool    `json:"masked"`
+}
+
+// ClientRevealSecretRequestDTO is the body of a request to reveal an auth
+// client's secret. Password re-authenticates the caller.
+type ClientRevealSecretRequestDTO struct {
+	Password string `json:"password"`
+}
+
+// Validate validates the auth client secret reveal request.
+func (r ClientRevealSecretRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Password,
+			validation.Required.Error("Password is required"),
+		),
+	)
+}
+
+// ClientRotateSecretRequestDTO is the optional request body for rotating an
+// auth client's secret. GracePeriodSeconds is how long the previous secret
+// keeps authenticating; it defaults to 24 hours and 0 revokes the previous
+// secret immediately.
+type ClientRotateSecretRequestDTO struct {
+	GracePeriodSeconds *int `json:"grace_period_seconds"`
+}
+
+// Validate validates the auth client secret rotation request.
+func (r ClientRotateSecretRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.GracePeriodSeconds,
+			validation.When(r.GracePeriodSeconds != nil, validation.Min(0).Error("Grace period must be at least 0 seconds"), validation.Max(2592000).Error("Grace period must not exceed 30 days")),
+		),
+	)
+}
+
+// ClientSecretRotationResponseDTO returns the new secret of a rotated auth
+// client together with the end of the previous secret's grace period.
+type ClientSecretRotationResponseDTO struct {
+	ClientID                string     `json:"client_id"`
+	ClientSecret            string     `json:"client_secret"`
+	SecretRotatedAt         time.Time  `json:"secret_rotated_at"`
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at"`
 }
 
 type ClientURIResponseDTO struct {
@@ -49,8 +93,11 @@ type ClientResponseDTO struct {
 	Status           string                       `json:"status"`
 	IsDefault        bool                         `json:"is_default"`
 	IsSystem         bool                         `json:"is_system"`
-	CreatedAt        time.Time                    `json:"created_at"`
-	UpdatedAt        time.Time                    `json:"updated_at"`
+	Deprecation      *DeprecationResponseDTO      `json:"deprecation,omitempty"`
+	// ClientSecret is only present in the response that created the client.
+	ClientSecret *string   `json:"client_secret,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 // Create auth client request DTO
@@ -149,7 +196,7 @@ func (r ClientURICreateOrUpdateRequestDTO) Validate() error {
 		),
 		validation.Field(&r.Type,
 			validation.Required.Error("Type is required"),
-			validation.In(model.ClientURITypeRedirect, model.ClientURITypeOrigin, model.ClientURITypeLogout, model.ClientURITypeLogin, model.ClientURITypeCORSOrigin).Error("Type must be one of: redirect-uri, origin-uri, logout-uri, login-uri, cors-origin-uri"),
+			validation.In(model.ClientURITypeRedirect, model.ClientURITypeOrigin, model.ClientURITypeLogout, model.ClientURITypeLogin, model.ClientURITypeCORSOrigin, model.ClientURITypeBackChannelLogout, model.ClientURITypeFrontChannelLogout).Error("Type must be one of: redirect-uri, origin-uri, logout-uri, login-uri, cors-origin-uri, backchannel-logout-uri, frontchannel-logout-uri"),
 		),
 	)
 }
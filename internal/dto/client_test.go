@@ -137,3 +137,31 @@ func TestAddClientAPIPermissionsRequestDto_Validate(t *testing.T) {
 	require.Error(t, AddClientAPIPermissionsRequestDTO{}.Validate())
 }
 
+func TestClientRotateSecretRequestDTO_Validate(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+
+	t.Run("empty body", func(t *testing.T) {
+		assert.NoError(t, ClientRotateSecretRequestDTO{}.Validate())
+	})
+
+	t.Run("zero grace", func(t *testing.T) {
+		assert.NoError(t, ClientRotateSecretRequestDTO{GracePeriodSeconds: intPtr(0)}.Validate())
+	})
+
+	t.Run("thirty days", func(t *testing.T) {
+		assert.NoError(t, ClientRotateSecretRequestDTO{GracePeriodSeconds: intPtr(2592000)}.Validate())
+	})
+
+	t.Run("negative grace", func(t *testing.T) {
+		require.Error(t, ClientRotateSecretRequestDTO{GracePeriodSeconds: intPtr(-1)}.Validate())
+	})
+
+	t.Run("grace too long", func(t *testing.T) {
+		require.Error(t, ClientRotateSecretRequestDTO{GracePeriodSeconds: intPtr(2592001)}.Validate())
+	})
+}
+
+func TestClientRevealSecretRequestDTO_Validate(t *testing.T) {
+	assert.NoError(t, ClientRevealSecretRequestDTO{Password: "hunter2"}.Validate())
+	require.Error(t, ClientRevealSecretRequestDTO{}.Validate())
+}
@@ -0,0 +1,7 @@
+package dto
+
+// ConfigReloadResponseDTO lists the settings a config reload changed, by
+// name only.
+type ConfigReloadResponseDTO struct {
+	Changed []string `json:"changed"`
+}
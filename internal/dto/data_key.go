@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// DataKeyResponseDTO describes a column encryption data key. Key material is
+// never returned, wrapped or not.
+type DataKeyResponseDTO struct {
+	DataKeyID   string     `json:"data_key_id"`
+	KeyID       string     `json:"key_id"`
+	Status      string     `json:"status"`
+	ActivatedAt time.Time  `json:"activated_at"`
+	RotatedAt   *time.Time `json:"rotated_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// DataKeyRewrapResponseDTO reports how many data keys were rewrapped with
+// the current key-encryption key.
+type DataKeyRewrapResponseDTO struct {
+	Rewrapped int64 `json:"rewrapped"`
+}
+
+// DataKeyReencryptResponseDTO reports how many stored values were
+// re-encrypted with the active data key, in total and by column.
+type DataKeyReencryptResponseDTO struct {
+	ActiveKeyID string           `json:"active_key_id"`
+	Reencrypted int64            `json:"reencrypted"`
+	Columns     map[string]int64 `json:"columns"`
+}
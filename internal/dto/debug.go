@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+// LogLevelResponseDTO is the current log level of a component. ChangedBy is
+// the UUID of the admin who last changed it; both change fields are omitted
+// while the default is in effect.
+type LogLevelResponseDTO struct {
+	Component string     `json:"component"`
+	Level     string     `json:"level"`
+	ChangedBy string     `json:"changed_by,omitempty"`
+	ChangedAt *time.Time `json:"changed_at,omitempty"`
+}
+
+// SetLogLevelRequestDTO is the request body for changing a component's log
+// level.
+type SetLogLevelRequestDTO struct {
+	Level string `json:"level"`
+}
+
+// Validate validates the log level request.
+func (r SetLogLevelRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Level,
+			validation.Required.Error("Level is required"),
+			validation.In("debug", "info", "warn", "error").Error("Level must be 'debug', 'info', 'warn' or 'error'"),
+		),
+	)
+}
+
+// BodyTraceResponseDTO is an active body trace rule. Value is the tenant UUID
+// or client identifier that requests are matched on.
+type BodyTraceResponseDTO struct {
+	BodyTraceID string    `json:"body_trace_id"`
+	Target      string    `json:"target"`
+	Value       string    `json:"value"`
+	EnabledBy   string    `json:"enabled_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// BodyTraceRequestDTO is the request body for enabling body tracing. Exactly
+// one of TenantUUID and ClientUUID is required.
+type BodyTraceRequestDTO struct {
+	TenantUUID      *string `json:"tenant_id"`
+	ClientUUID      *string `json:"client_id"`
+	DurationMinutes int     `json:"duration_minutes"`
+}
+
+// Validate validates the body trace request.
+func (r BodyTraceRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.TenantUUID,
+			validation.When(r.ClientUUID == nil, validation.Required.Error("Tenant ID or client ID is required")),
+			validation.When(r.ClientUUID != nil, validation.Nil.Error("Only one of tenant ID and client ID can be set")),
+			is.UUID.Error("Tenant ID must be a valid UUID"),
+		),
+		validation.Field(&r.ClientUUID,
+			is.UUID.Error("Client ID must be a valid UUID"),
+		),
+		validation.Field(&r.DurationMinutes,
+			validation.Required.Error("Duration is required"),
+			validation.Min(1).Error("Duration must be between 1 and 60 minutes"),
+			validation.Max(60).Error("Duration must be between 1 and 60 minutes"),
+		),
+	)
+}
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetLogLevelRequestDTO_Validate(t *testing.T) {
+	assert.NoError(t, SetLogLevelRequestDTO{Level: "debug"}.Validate())
+	assert.Error(t, SetLogLevelRequestDTO{}.Validate())
+	assert.Error(t, SetLogLevelRequestDTO{Level: "trace"}.Validate())
+}
+
+func TestBodyTraceRequestDTO_Validate(t *testing.T) {
+	id := uuid.NewString()
+	bad := "not-a-uuid"
+
+	assert.NoError(t, BodyTraceRequestDTO{TenantUUID: &id, DurationMinutes: 15}.Validate())
+	assert.NoError(t, BodyTraceRequestDTO{ClientUUID: &id, DurationMinutes: 60}.Validate())
+
+	assert.Error(t, BodyTraceRequestDTO{DurationMinutes: 15}.Validate())
+	assert.Error(t, BodyTraceRequestDTO{TenantUUID: &id, ClientUUID: &id, DurationMinutes: 15}.Validate())
+	assert.Error(t, BodyTraceRequestDTO{TenantUUID: &bad, DurationMinutes: 15}.Validate())
+	assert.Error(t, BodyTraceRequestDTO{ClientUUID: &bad, DurationMinutes: 15}.Validate())
+	assert.Error(t, BodyTraceRequestDTO{TenantUUID: &id}.Validate())
+	assert.Error(t, BodyTraceRequestDTO{TenantUUID: &id, DurationMinutes: 61}.Validate())
+}
@@ -0,0 +1,22 @@
+package dto
+
+// DeleteImpactEntryResponseDTO is the number of rows of a table a delete
+// removes or changes. Constraint names the foreign key the rows are reached
+// through and is empty for the deleted rows themselves.
+type DeleteImpactEntryResponseDTO struct {
+	Table      string `json:"table"`
+	Constraint string `json:"constraint,omitempty"`
+	Action     string `json:"action"`
+	Rows       int64  `json:"rows"`
+}
+
+// DeleteImpactResponseDTO is the report of a destructive request made with
+// ?dry_run=true. Nothing was changed.
+type DeleteImpactResponseDTO struct {
+	DryRun              bool                           `json:"dry_run"`
+	WouldSucceed        bool                           `json:"would_succeed"`
+	RowsAffected        int64                          `json:"rows_affected"`
+	Impact              []DeleteImpactEntryResponseDTO `json:"impact"`
+	BlockingConstraints []string                       `json:"blocking_constraints"`
+	Events              []string                       `json:"events"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// DeprecationRequestDTO marks a client or API as deprecated. SunsetAt is an
+// RFC 3339 timestamp after which token issuance is refused; when omitted the
+// record stays usable with warnings indefinitely.
+type DeprecationRequestDTO struct {
+	SunsetAt *time.Time `json:"sunset_at"`
+	Notice   *string    `json:"notice"`
+}
+
+// Validate validates the deprecation request.
+func (r DeprecationRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Notice,
+			validation.NilOrNotEmpty.Error("Notice must not be empty"),
+			validation.Length(0, 500).Error("Notice must not exceed 500 characters"),
+		),
+	)
+}
+
+// DeprecationResponseDTO is the JSON representation of a deprecated client or
+// API, including how often it has been used since deprecation.
+type DeprecationResponseDTO struct {
+	DeprecatedAt time.Time  `json:"deprecated_at"`
+	SunsetAt     *time.Time `json:"sunset_at,omitempty"`
+	Notice       *string    `json:"notice,omitempty"`
+	UsageCount   int64      `json:"usage_count"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+}
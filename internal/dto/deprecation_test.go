@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecationRequestDTO_Validate(t *testing.T) {
+	empty := ""
+	long := strings.Repeat("a", 501)
+	notice := "Use the v2 client instead"
+
+	assert.NoError(t, DeprecationRequestDTO{}.Validate())
+	assert.NoError(t, DeprecationRequestDTO{Notice: &notice}.Validate())
+	assert.Error(t, DeprecationRequestDTO{Notice: &empty}.Validate())
+	assert.Error(t, DeprecationRequestDTO{Notice: &long}.Validate())
+}
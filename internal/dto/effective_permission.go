@@ -0,0 +1,27 @@
+package dto
+
+import "github.com/google/uuid"
+
+// TenantPermissionsResponseDTO is the set of permissions a user holds through
+// the roles of one tenant.
+type TenantPermissionsResponseDTO struct {
+	TenantUUID  uuid.UUID `json:"tenant_id"`
+	Permissions []string  `json:"permissions"`
+}
+
+// ResourcePermissionsResponseDTO is the set of permissions a user holds on
+// one client or group through scoped role assignments.
+type ResourcePermissionsResponseDTO struct {
+	ResourceType string    `json:"resource_type"`
+	ResourceUUID uuid.UUID `json:"resource_id"`
+	Permissions  []string  `json:"permissions"`
+}
+
+// EffectivePermissionsResponseDTO is a user's effective permission set: the
+// union across all tenants, the breakdown per tenant and the permissions
+// limited to single resources, which are not part of the union.
+type EffectivePermissionsResponseDTO struct {
+	Permissions []string                         `json:"permissions"`
+	Tenants     []TenantPermissionsResponseDTO   `json:"tenants"`
+	Resources   []ResourcePermissionsResponseDTO `json:"resources"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+// ErrorCodeResponseDTO is an entry of the error code registry. Code is the
+// stable identifier returned in the "code" member of every error response.
+type ErrorCodeResponseDTO struct {
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Status int    `json:"status"`
+	Title  string `json:"title"`
+}
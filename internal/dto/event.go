@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/maintainerd/auth/internal/model"
+)
+
+// Event feed page size bounds.
+const (
+	EventFeedDefaultLimit = 100
+	EventFeedMaxLimit     = 500
+)
+
+// EventFeedFilterDTO holds query parameters for reading the lifecycle event
+// feed.
+type EventFeedFilterDTO struct {
+	Cursor string   `json:"cursor"`
+	Types  []string `json:"types"`
+	Limit  int      `json:"limit"`
+}
+
+// Validate validates the feed parameters.
+func (f EventFeedFilterDTO) Validate() error {
+	eventTypes := make([]any, len(model.EventTypes))
+	for i, t := range model.EventTypes {
+		eventTypes[i] = t
+	}
+
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.Cursor,
+			validation.Length(0, 64).Error("Cursor cannot exceed 64 characters"),
+		),
+		validation.Field(&f.Types,
+			validation.When(len(f.Types) > 0,
+				validation.Each(validation.In(eventTypes...).Error("Type must be one of: "+strings.Join(model.EventTypes, ", "))),
+			),
+		),
+		validation.Field(&f.Limit,
+			validation.Required.Error("Limit is required"),
+			validation.Min(1).Error("Limit must be greater than 0"),
+			validation.Max(EventFeedMaxLimit).Error("Limit cannot exceed 500"),
+		),
+	)
+}
+
+// EventResponseDTO is the API response for a single lifecycle event.
+type EventResponseDTO struct {
+	EventID       string          `json:"event_id"`
+	Sequence      int64           `json:"sequence"`
+	EventType     string          `json:"event_type"`
+	SchemaVersion int             `json:"schema_version"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+// EventFeedResponseDTO is a page of the lifecycle event feed. Clients persist
+// NextCursor and pass it back to resume; HasMore reports whether another page
+// is immediately available.
+type EventFeedResponseDTO struct {
+	Events     []EventResponseDTO `json:"events"`
+	NextCursor string             `json:"next_cursor"`
+	HasMore    bool               `json:"has_more"`
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventFeedFilterDTO_Validate(t *testing.T) {
+	t.Run("valid minimal", func(t *testing.T) {
+		assert.NoError(t, EventFeedFilterDTO{Limit: 1}.Validate())
+	})
+
+	t.Run("valid full", func(t *testing.T) {
+		f := EventFeedFilterDTO{
+			Cursor: "ZTE6NDI",
+			Types:  model.EventTypes,
+			Limit:  EventFeedMaxLimit,
+		}
+		assert.NoError(t, f.Validate())
+	})
+
+	t.Run("missing limit", func(t *testing.T) {
+		err := EventFeedFilterDTO{}.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Limit is required")
+	})
+
+	t.Run("limit too large", func(t *testing.T) {
+		err := EventFeedFilterDTO{Limit: EventFeedMaxLimit + 1}.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot exceed 500")
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		err := EventFeedFilterDTO{Limit: 10, Types: []string{model.EventTypeUserCreated, "user.exploded"}}.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Type must be one of")
+	})
+
+	t.Run("cursor too long", func(t *testing.T) {
+		err := EventFeedFilterDTO{Limit: 10, Cursor: strings.Repeat("a", 65)}.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Cursor cannot exceed")
+	})
+}
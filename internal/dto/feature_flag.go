@@ -0,0 +1,94 @@
+package dto
+
+import (
+	"regexp"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// featureFlagKeyPattern matches flag keys such as "token.jwt-v2".
+var featureFlagKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// FeatureFlagOverrideResponseDTO is the state of a feature flag for one
+// tenant.
+type FeatureFlagOverrideResponseDTO struct {
+	TenantID   string `json:"tenant_id"`
+	TenantName string `json:"tenant_name"`
+	Enabled    bool   `json:"enabled"`
+}
+
+// FeatureFlagResponseDTO is the JSON representation of a feature flag.
+type FeatureFlagResponseDTO struct {
+	FeatureFlagID     string                           `json:"feature_flag_id"`
+	Key               string                           `json:"key"`
+	Description       string                           `json:"description"`
+	Enabled           bool                             `json:"enabled"`
+	RolloutPercentage int                              `json:"rollout_percentage"`
+	Overrides         []FeatureFlagOverrideResponseDTO `json:"overrides"`
+	CreatedAt         time.Time                        `json:"created_at"`
+	UpdatedAt         time.Time                        `json:"updated_at"`
+}
+
+// FeatureFlagCreateRequestDTO is the request body for creating a feature
+// flag. RolloutPercentage defaults to 100.
+type FeatureFlagCreateRequestDTO struct {
+	Key               string `json:"key"`
+	Description       string `json:"description"`
+	Enabled           bool   `json:"enabled"`
+	RolloutPercentage *int   `json:"rollout_percentage,omitempty"`
+}
+
+// Validate validates the create feature flag request.
+func (r FeatureFlagCreateRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Key,
+			validation.Required.Error("Key is required"),
+			validation.Length(1, 100).Error("Key must not exceed 100 characters"),
+			validation.Match(featureFlagKeyPattern).Error("Key must contain only lowercase letters, numbers, dots, underscores and hyphens"),
+		),
+		validation.Field(&r.Description,
+			validation.Length(0, 1000).Error("Description must not exceed 1000 characters"),
+		),
+		validation.Field(&r.RolloutPercentage,
+			validation.Min(0).Error("Rollout percentage must be between 0 and 100"),
+			validation.Max(100).Error("Rollout percentage must be between 0 and 100"),
+		),
+	)
+}
+
+// FeatureFlagUpdateRequestDTO is the request body for updating a feature
+// flag. The key cannot change. RolloutPercentage defaults to 100.
+type FeatureFlagUpdateRequestDTO struct {
+	Description       string `json:"description"`
+	Enabled           bool   `json:"enabled"`
+	RolloutPercentage *int   `json:"rollout_percentage,omitempty"`
+}
+
+// Validate validates the update feature flag request.
+func (r FeatureFlagUpdateRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Description,
+			validation.Length(0, 1000).Error("Description must not exceed 1000 characters"),
+		),
+		validation.Field(&r.RolloutPercentage,
+			validation.Min(0).Error("Rollout percentage must be between 0 and 100"),
+			validation.Max(100).Error("Rollout percentage must be between 0 and 100"),
+		),
+	)
+}
+
+// FeatureFlagOverrideRequestDTO is the request body for turning a feature
+// flag on or off for one tenant.
+type FeatureFlagOverrideRequestDTO struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// Validate validates the override request.
+func (r FeatureFlagOverrideRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Enabled,
+			validation.NotNil.Error("Enabled is required"),
+		),
+	)
+}
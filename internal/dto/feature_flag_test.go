@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureFlagCreateRequestDTO_Validate(t *testing.T) {
+	assert.NoError(t, FeatureFlagCreateRequestDTO{Key: "token.jwt-v2"}.Validate())
+	assert.NoError(t, FeatureFlagCreateRequestDTO{Key: "new_flow", RolloutPercentage: intPtr(0)}.Validate())
+
+	require.Error(t, FeatureFlagCreateRequestDTO{}.Validate(), "missing key")
+	require.Error(t, FeatureFlagCreateRequestDTO{Key: "New Flow"}.Validate(), "invalid key")
+	require.Error(t, FeatureFlagCreateRequestDTO{Key: "new-flow", RolloutPercentage: intPtr(101)}.Validate(), "rollout over 100")
+	require.Error(t, FeatureFlagCreateRequestDTO{Key: "new-flow", RolloutPercentage: intPtr(-1)}.Validate(), "negative rollout")
+}
+
+func TestFeatureFlagUpdateRequestDTO_Validate(t *testing.T) {
+	assert.NoError(t, FeatureFlagUpdateRequestDTO{Enabled: true, RolloutPercentage: intPtr(50)}.Validate())
+	require.Error(t, FeatureFlagUpdateRequestDTO{RolloutPercentage: intPtr(200)}.Validate())
+}
+
+func TestFeatureFlagOverrideRequestDTO_Validate(t *testing.T) {
+	disabled := false
+	assert.NoError(t, FeatureFlagOverrideRequestDTO{Enabled: &disabled}.Validate())
+	require.Error(t, FeatureFlagOverrideRequestDTO{}.Validate())
+}
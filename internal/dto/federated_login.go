@@ -0,0 +1,20 @@
+package dto
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// FederatedLoginRequestDTO is the body of a sign in with an ID token issued
+// by the upstream OpenID Connect provider of an auth client.
+type FederatedLoginRequestDTO struct {
+	IDToken string `json:"id_token"`
+}
+
+func (r FederatedLoginRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.IDToken,
+			validation.Required.Error("ID token is required"),
+			validation.Length(1, 16384).Error("ID token must not exceed 16384 characters"),
+		),
+	)
+}
@@ -0,0 +1,14 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFederatedLoginRequestDTO_Validate(t *testing.T) {
+	assert.NoError(t, FederatedLoginRequestDTO{IDToken: "eyJhbGciOiJSUzI1NiJ9.e30.c2ln"}.Validate())
+	assert.Error(t, FederatedLoginRequestDTO{}.Validate())
+	assert.Error(t, FederatedLoginRequestDTO{IDToken: strings.Repeat("a", 16385)}.Validate())
+}
@@ -0,0 +1,109 @@
+package dto
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
+)
+
+// MaxGroupMembersPerRequest caps how many users one request may add to a
+// group. Larger groups are filled over several requests.
+const MaxGroupMembersPerRequest = 1000
+
+// GroupResponseDTO describes a user group and the roles its members hold.
+type GroupResponseDTO struct {
+	GroupUUID   uuid.UUID         `json:"group_id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Roles       []RoleResponseDTO `json:"roles"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// GroupCreateRequestDTO is the body of a request to create a group. Members
+// and roles are added separately.
+type GroupCreateRequestDTO struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (r GroupCreateRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Name,
+			validation.Required.Error("Name is required"),
+			validation.RuneLength(3, 100).Error("Name must be between 3 and 100 characters"),
+		),
+		validation.Field(&r.Description,
+			validation.RuneLength(0, 255).Error("Description must be at most 255 characters"),
+		),
+	)
+}
+
+// GroupUpdateRequestDTO is the body of a request to rename or describe a
+// group.
+type GroupUpdateRequestDTO struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (r GroupUpdateRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Name,
+			validation.Required.Error("Name is required"),
+			validation.RuneLength(3, 100).Error("Name must be between 3 and 100 characters"),
+		),
+		validation.Field(&r.Description,
+			validation.RuneLength(0, 255).Error("Description must be at most 255 characters"),
+		),
+	)
+}
+
+// GroupAddMembersRequestDTO is the body of a request to add users to a
+// group.
+type GroupAddMembersRequestDTO struct {
+	Users []uuid.UUID `json:"users"`
+}
+
+func (r GroupAddMembersRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Users,
+			validation.Required.Error("User UUIDs are required"),
+			validation.Length(1, MaxGroupMembersPerRequest).Error("At most 1000 users can be added at once"),
+			validation.Each(is.UUID.Error("Invalid UUID provided")),
+		),
+	)
+}
+
+// GroupAddRolesRequestDTO is the body of a request to attach roles to a
+// group.
+type GroupAddRolesRequestDTO struct {
+	Roles []uuid.UUID `json:"roles"`
+}
+
+func (r GroupAddRolesRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Roles,
+			validation.Required.Error("Role UUIDs are required"),
+			validation.Each(is.UUID.Error("Invalid UUID provided")),
+		),
+	)
+}
+
+// GroupFilterDTO holds the query parameters for listing groups.
+type GroupFilterDTO struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	// UserUUID limits the list to the groups the user is a member of.
+	UserUUID *uuid.UUID `json:"user_id"`
+
+	// Pagination and sorting
+	PaginationRequestDTO
+}
+
+func (f GroupFilterDTO) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.PaginationRequestDTO),
+	)
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupCreateRequestDTO_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		d := GroupCreateRequestDTO{Name: "support", Description: "Support agents"}
+		assert.NoError(t, d.Validate())
+	})
+
+	t.Run("name too short", func(t *testing.T) {
+		require.Error(t, GroupCreateRequestDTO{Name: "ab"}.Validate())
+	})
+
+	t.Run("description too long", func(t *testing.T) {
+		d := GroupCreateRequestDTO{Name: "support", Description: strings.Repeat("a", 256)}
+		require.Error(t, d.Validate())
+	})
+}
+
+func TestGroupUpdateRequestDTO_Validate(t *testing.T) {
+	assert.NoError(t, GroupUpdateRequestDTO{Name: "support"}.Validate())
+	require.Error(t, GroupUpdateRequestDTO{}.Validate())
+}
+
+func TestGroupAddMembersRequestDTO_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, GroupAddMembersRequestDTO{Users: []uuid.UUID{uuid.New()}}.Validate())
+	})
+
+	t.Run("missing users", func(t *testing.T) {
+		require.Error(t, GroupAddMembersRequestDTO{}.Validate())
+	})
+
+	t.Run("too many users", func(t *testing.T) {
+		users := make([]uuid.UUID, MaxGroupMembersPerRequest+1)
+		for i := range users {
+			users[i] = uuid.New()
+		}
+		require.Error(t, GroupAddMembersRequestDTO{Users: users}.Validate())
+	})
+}
+
+func TestGroupAddRolesRequestDTO_Validate(t *testing.T) {
+	assert.NoError(t, GroupAddRolesRequestDTO{Roles: []uuid.UUID{uuid.New()}}.Validate())
+	require.Error(t, GroupAddRolesRequestDTO{}.Validate())
+}
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/maintainerd/auth/internal/security"
+)
+
+// Login methods returned by home-realm discovery.
+const (
+	// LoginMethodPassword signs in with username and password at POST /login.
+	LoginMethodPassword = "password"
+	// LoginMethodFederated signs in at the upstream provider and then at
+	// POST /login/federated.
+	LoginMethodFederated = "federated"
+)
+
+// HomeRealmRequestDTO is the body of a home-realm discovery request.
+type HomeRealmRequestDTO struct {
+	Email string `json:"email"`
+}
+
+func (r *HomeRealmRequestDTO) Validate() error {
+	// Sanitize inputs first
+	r.Email = security.SanitizeInput(r.Email)
+
+	return validation.ValidateStruct(r,
+		validation.Field(&r.Email,
+			validation.Required.Error("Email is required"),
+			is.EmailFormat.Error("Email must be a valid email address"),
+			validation.Length(1, 255).Error("Email must not exceed 255 characters"),
+		),
+	)
+}
+
+// HomeRealmResponseDTO names the identity provider and login method a user
+// signs in with.
+type HomeRealmResponseDTO struct {
+	Method      string `json:"method"`
+	ProviderID  string `json:"provider_id"`
+	Provider    string `json:"provider"`
+	DisplayName string `json:"display_name"`
+	// Issuer is the upstream provider to sign in at, set for the federated
+	// method.
+	Issuer string `json:"issuer,omitempty"`
+}
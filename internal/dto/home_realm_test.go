@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHomeRealmRequestDTO_Validate(t *testing.T) {
+	valid := HomeRealmRequestDTO{Email: "jane@example.com"}
+	assert.NoError(t, valid.Validate())
+
+	for name, email := range map[string]string{
+		"empty":     "",
+		"not email": "jane",
+		"too long":  strings.Repeat("a", 250) + "@example.com",
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := HomeRealmRequestDTO{Email: email}
+			assert.Error(t, r.Validate())
+		})
+	}
+}
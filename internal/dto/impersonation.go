@@ -0,0 +1,28 @@
+package dto
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+)
+
+// ImpersonateRequestDTO is the request body for impersonating a user. The
+// reason is recorded in the audit log.
+type ImpersonateRequestDTO struct {
+	Reason string `json:"reason"`
+}
+
+func (dto ImpersonateRequestDTO) Validate() error {
+	return validation.ValidateStruct(&dto,
+		validation.Field(&dto.Reason, validation.Required.Error("Reason is required"), validation.Length(3, 500).Error("Reason must be between 3 and 500 characters")),
+	)
+}
+
+// ImpersonationResponseDTO carries a short-lived access token acting as the
+// impersonated user. No refresh token is issued.
+type ImpersonationResponseDTO struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	ExpiresIn   int64     `json:"expires_in"`
+	IssuedAt    int64     `json:"issued_at"`
+	UserUUID    uuid.UUID `json:"user_id"`
+}
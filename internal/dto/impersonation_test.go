@@ -0,0 +1,15 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImpersonateRequestDTO_Validate(t *testing.T) {
+	assert.NoError(t, ImpersonateRequestDTO{Reason: "support ticket 42"}.Validate())
+	assert.Error(t, ImpersonateRequestDTO{}.Validate())
+	assert.Error(t, ImpersonateRequestDTO{Reason: "ok"}.Validate())
+	assert.Error(t, ImpersonateRequestDTO{Reason: strings.Repeat("x", 501)}.Validate())
+}
@@ -12,13 +12,15 @@ import (
 // IPRestrictionRuleResponseDTO is the JSON representation of an IP restriction
 // rule.
 type IPRestrictionRuleResponseDTO struct {
-	IPRestrictionRuleID string    `json:"ip_restriction_rule_id"`
-	Description         string    `json:"description"`
-	Type                string    `json:"type"`
-	IPAddress           string    `json:"ip_address"`
-	Status              string    `json:"status"`
-	CreatedAt           time.Time `json:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at"`
+	IPRestrictionRuleID string     `json:"ip_restriction_rule_id"`
+	Description         string     `json:"description"`
+	Type                string     `json:"type"`
+	IPAddress           string     `json:"ip_address"`
+	Status              string     `json:"status"`
+	Source              string     `json:"source"`
+	ExpiresAt           *time.Time `json:"expires_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
 }
 
 // IPRestrictionRuleCreateRequestDTO is the request body for creating an IP
@@ -3,6 +3,7 @@ package dto
 import (
 	"net/url"
 
+	"github.com/google/uuid"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 		"github.com/maintainerd/auth/internal/signedurl"
 	"github.com/maintainerd/auth/internal/security"
@@ -12,6 +13,9 @@ import (
 type LoginRequestDTO struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	// AcceptedPolicies lists the policy documents the user accepts with
+	// this request, by UUID.
+	AcceptedPolicies []uuid.UUID `json:"accepted_policies,omitempty"`
 }
 
 func (r *LoginRequestDTO) Validate() error {
@@ -28,6 +32,9 @@ func (r *LoginRequestDTO) Validate() error {
 			validation.Required.Error("Password is required"),
 			validation.Length(1, 128).Error("Password must not exceed 128 characters"),
 		),
+		validation.Field(&r.AcceptedPolicies,
+			validation.Length(0, maxAcceptedPolicies).Error("Too many accepted policies"),
+		),
 	)
 }
 
@@ -71,4 +78,7 @@ type LoginResponseDTO struct {
 	ExpiresIn    int64  `json:"expires_in"`
 	TokenType    string `json:"token_type"`
 	IssuedAt     int64  `json:"issued_at"`
+	// StepUpRequired is set when the login looked suspicious and the
+	// tenant requires step-up authentication for such logins.
+	StepUpRequired bool `json:"step_up_required,omitempty"`
 }
@@ -0,0 +1,119 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+
+	"github.com/maintainerd/auth/internal/model"
+)
+
+// LoginHookResponseDTO is the JSON representation of a login hook. The
+// webhook secret is write-only; HasSecret reports whether one is set.
+type LoginHookResponseDTO struct {
+	LoginHookID   string    `json:"login_hook_id"`
+	Name          string    `json:"name"`
+	Trigger       string    `json:"trigger"`
+	Type          string    `json:"type"`
+	URL           string    `json:"url,omitempty"`
+	HasSecret     bool      `json:"has_secret"`
+	Rule          any       `json:"rule,omitempty"`
+	TimeoutMs     int       `json:"timeout_ms"`
+	FailurePolicy string    `json:"failure_policy"`
+	Priority      int       `json:"priority"`
+	Status        string    `json:"status"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// LoginHookRequestDTO is the request body for creating or replacing a login
+// hook. Webhook hooks require URL; rule hooks require Rule.
+type LoginHookRequestDTO struct {
+	Name          string          `json:"name"`
+	Trigger       string          `json:"trigger"`
+	Type          string          `json:"type"`
+	URL           string          `json:"url"`
+	Secret        string          `json:"secret"`
+	Rule          json.RawMessage `json:"rule"`
+	TimeoutMs     *int            `json:"timeout_ms"`
+	FailurePolicy *string         `json:"failure_policy,omitempty"`
+	Priority      *int            `json:"priority"`
+	Status        *string         `json:"status,omitempty"`
+}
+
+// Validate validates the login hook request.
+func (r LoginHookRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Name,
+			validation.Required.Error("Name is required"),
+			validation.Length(1, 100).Error("Name must be between 1 and 100 characters"),
+		),
+		validation.Field(&r.Trigger,
+			validation.Required.Error("Trigger is required"),
+			validation.In(model.LoginHookTriggerPreLogin, model.LoginHookTriggerPostLogin, model.LoginHookTriggerPreRegistration).
+				Error("Trigger must be 'pre_login', 'post_login' or 'pre_registration'"),
+		),
+		validation.Field(&r.Type,
+			validation.Required.Error("Type is required"),
+			validation.In(model.LoginHookTypeWebhook, model.LoginHookTypeRule).Error("Type must be 'webhook' or 'rule'"),
+		),
+		validation.Field(&r.URL,
+			validation.When(r.Type == model.LoginHookTypeWebhook, validation.Required.Error("URL is required for webhook hooks")),
+			is.URL.Error("URL must be a valid URL"),
+		),
+		validation.Field(&r.Secret,
+			validation.Length(0, 255).Error("Secret must not exceed 255 characters"),
+		),
+		validation.Field(&r.Rule,
+			validation.When(r.Type == model.LoginHookTypeRule, validation.Required.Error("Rule is required for rule hooks")),
+		),
+		validation.Field(&r.TimeoutMs,
+			validation.When(r.TimeoutMs != nil, validation.Min(100).Error("Timeout must be at least 100 ms"), validation.Max(10000).Error("Timeout must not exceed 10000 ms")),
+		),
+		validation.Field(&r.FailurePolicy,
+			validation.When(r.FailurePolicy != nil, validation.In(model.LoginHookFailOpen, model.LoginHookFailClosed).Error("Failure policy must be 'fail_open' or 'fail_closed'")),
+		),
+		validation.Field(&r.Priority,
+			validation.When(r.Priority != nil, validation.Min(0).Error("Priority must be at least 0"), validation.Max(1000).Error("Priority must not exceed 1000")),
+		),
+		validation.Field(&r.Status,
+			validation.When(r.Status != nil, validation.In(model.StatusActive, model.StatusInactive).Error("Status must be 'active' or 'inactive'")),
+		),
+	)
+}
+
+// LoginHookUpdateStatusRequestDTO is the request body for updating login hook
+// status.
+type LoginHookUpdateStatusRequestDTO struct {
+	Status string `json:"status"`
+}
+
+// Validate validates the login hook status update request.
+func (r LoginHookUpdateStatusRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Status,
+			validation.Required.Error("Status is required"),
+			validation.In(model.StatusActive, model.StatusInactive).Error("Status must be 'active' or 'inactive'"),
+		),
+	)
+}
+
+// LoginHookFilterDTO holds filter parameters for listing login hooks.
+type LoginHookFilterDTO struct {
+	Trigger []string `json:"trigger"`
+	Status  []string `json:"status"`
+	PaginationRequestDTO
+}
+
+// Validate validates the login hook filter.
+func (f LoginHookFilterDTO) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.Trigger,
+			validation.Each(validation.In(model.LoginHookTriggerPreLogin, model.LoginHookTriggerPostLogin, model.LoginHookTriggerPreRegistration).
+				Error("Trigger must be 'pre_login', 'post_login' or 'pre_registration'")),
+		),
+		validation.Field(&f.PaginationRequestDTO),
+	)
+}
@@ -0,0 +1,167 @@
+package dto
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maintainerd/auth/internal/model"
+)
+
+// ---------------------------------------------------------------------------
+// Create / Update
+// ---------------------------------------------------------------------------
+
+func validWebhookLoginHook() LoginHookRequestDTO {
+	return LoginHookRequestDTO{
+		Name:    "Risk check",
+		Trigger: model.LoginHookTriggerPreLogin,
+		Type:    model.LoginHookTypeWebhook,
+		URL:     "https://hooks.example.com/login",
+		Secret:  "s3cret",
+	}
+}
+
+func validRuleLoginHook() LoginHookRequestDTO {
+	return LoginHookRequestDTO{
+		Name:    "Tier claim",
+		Trigger: model.LoginHookTriggerPostLogin,
+		Type:    model.LoginHookTypeRule,
+		Rule:    json.RawMessage(`{"action":"allow","claims":{"tier":"gold"}}`),
+	}
+}
+
+func TestLoginHookRequestDTO_Validate(t *testing.T) {
+	t.Run("valid webhook", func(t *testing.T) {
+		assert.NoError(t, validWebhookLoginHook().Validate())
+	})
+
+	t.Run("valid rule", func(t *testing.T) {
+		assert.NoError(t, validRuleLoginHook().Validate())
+	})
+
+	t.Run("valid with optional fields", func(t *testing.T) {
+		d := validWebhookLoginHook()
+		timeout := 500
+		priority := 10
+		policy := model.LoginHookFailOpen
+		status := model.StatusInactive
+		d.TimeoutMs = &timeout
+		d.Priority = &priority
+		d.FailurePolicy = &policy
+		d.Status = &status
+		assert.NoError(t, d.Validate())
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		d := validWebhookLoginHook()
+		d.Name = ""
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("name too long", func(t *testing.T) {
+		d := validWebhookLoginHook()
+		d.Name = strings.Repeat("a", 101)
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("invalid trigger", func(t *testing.T) {
+		d := validWebhookLoginHook()
+		d.Trigger = "post_logout"
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		d := validWebhookLoginHook()
+		d.Type = "script"
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("webhook without url", func(t *testing.T) {
+		d := validWebhookLoginHook()
+		d.URL = ""
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		d := validWebhookLoginHook()
+		d.URL = "not-a-url"
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("rule without rule", func(t *testing.T) {
+		d := validRuleLoginHook()
+		d.Rule = nil
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("timeout too low", func(t *testing.T) {
+		d := validWebhookLoginHook()
+		v := 99
+		d.TimeoutMs = &v
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("timeout too high", func(t *testing.T) {
+		d := validWebhookLoginHook()
+		v := 10001
+		d.TimeoutMs = &v
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("invalid failure policy", func(t *testing.T) {
+		d := validWebhookLoginHook()
+		v := "retry"
+		d.FailurePolicy = &v
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("negative priority", func(t *testing.T) {
+		d := validWebhookLoginHook()
+		v := -1
+		d.Priority = &v
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		d := validWebhookLoginHook()
+		v := "deleted"
+		d.Status = &v
+		require.Error(t, d.Validate())
+	})
+}
+
+// ---------------------------------------------------------------------------
+// UpdateStatus
+// ---------------------------------------------------------------------------
+
+func TestLoginHookUpdateStatusRequestDTO_Validate(t *testing.T) {
+	assert.NoError(t, LoginHookUpdateStatusRequestDTO{Status: model.StatusActive}.Validate())
+	require.Error(t, LoginHookUpdateStatusRequestDTO{}.Validate())
+	require.Error(t, LoginHookUpdateStatusRequestDTO{Status: "pending"}.Validate())
+}
+
+// ---------------------------------------------------------------------------
+// Filter
+// ---------------------------------------------------------------------------
+
+func TestLoginHookFilterDTO_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		f := LoginHookFilterDTO{
+			Trigger:              []string{model.LoginHookTriggerPreLogin},
+			PaginationRequestDTO: PaginationRequestDTO{Page: 1, Limit: 10},
+		}
+		assert.NoError(t, f.Validate())
+	})
+
+	t.Run("invalid trigger", func(t *testing.T) {
+		f := LoginHookFilterDTO{
+			Trigger:              []string{"sometimes"},
+			PaginationRequestDTO: PaginationRequestDTO{Page: 1, Limit: 10},
+		}
+		require.Error(t, f.Validate())
+	})
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+// MigrationResponseDTO is a known schema migration. applied_at is omitted
+// while it is pending.
+type MigrationResponseDTO struct {
+	Version   string     `json:"version"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// MigrationStatusResponseDTO lists every known migration in order.
+type MigrationStatusResponseDTO struct {
+	Migrations []MigrationResponseDTO `json:"migrations"`
+	Pending    int                    `json:"pending"`
+}
+
+// MigrationApplyResponseDTO lists the migrations an apply request applied.
+type MigrationApplyResponseDTO struct {
+	Applied []string `json:"applied"`
+}
@@ -0,0 +1,118 @@
+package dto
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/maintainerd/auth/internal/model"
+)
+
+// NotificationSettingUpdateRequestDTO changes the caller's notification
+// preferences. Omitted fields are left as they are; an empty muted_types
+// list unmutes everything.
+type NotificationSettingUpdateRequestDTO struct {
+	EmailEnabled *bool    `json:"email_enabled"`
+	SMSEnabled   *bool    `json:"sms_enabled"`
+	InAppEnabled *bool    `json:"in_app_enabled"`
+	MutedTypes   []string `json:"muted_types"`
+}
+
+func (r NotificationSettingUpdateRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.MutedTypes,
+			validation.Length(0, 50).Error("Muted types cannot have more than 50 entries"),
+			validation.Each(
+				validation.Required.Error("Muted type cannot be empty"),
+				validation.Length(1, 100).Error("Muted type cannot exceed 100 characters"),
+			),
+		),
+	)
+}
+
+// NotificationSettingResponseDTO is the caller's notification preferences.
+type NotificationSettingResponseDTO struct {
+	EmailEnabled bool     `json:"email_enabled"`
+	SMSEnabled   bool     `json:"sms_enabled"`
+	InAppEnabled bool     `json:"in_app_enabled"`
+	MutedTypes   []string `json:"muted_types"`
+}
+
+// NotificationLogFilterDTO holds query parameters for listing notification
+// deliveries. UserID is only honoured on the admin endpoint.
+type NotificationLogFilterDTO struct {
+	UserID           *string `json:"user_id"`
+	NotificationType *string `json:"notification_type"`
+	Channel          *string `json:"channel"`
+	Status           *string `json:"status"`
+	DateFrom         *string `json:"date_from"`
+	DateTo           *string `json:"date_to"`
+	PaginationRequestDTO
+}
+
+// Validate validates the filter parameters.
+func (f NotificationLogFilterDTO) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.UserID,
+			validation.NilOrNotEmpty,
+			is.UUID.Error("User ID must be a valid UUID"),
+		),
+		validation.Field(&f.NotificationType,
+			validation.NilOrNotEmpty,
+			validation.Length(1, 100).Error("Notification type cannot exceed 100 characters"),
+		),
+		validation.Field(&f.Channel,
+			validation.NilOrNotEmpty,
+			validation.In(
+				model.NotificationChannelEmail,
+				model.NotificationChannelSMS,
+				model.NotificationChannelInApp,
+			).Error("Channel must be one of: email, sms, in_app"),
+		),
+		validation.Field(&f.Status,
+			validation.NilOrNotEmpty,
+			validation.In(
+				model.NotificationStatusSent,
+				model.NotificationStatusFailed,
+				model.NotificationStatusSkipped,
+			).Error("Status must be one of: sent, failed, skipped"),
+		),
+		validation.Field(&f.DateFrom,
+			validation.NilOrNotEmpty,
+			validation.Date(time.RFC3339).Error("Date from must be an RFC 3339 timestamp"),
+		),
+		validation.Field(&f.DateTo,
+			validation.NilOrNotEmpty,
+			validation.Date(time.RFC3339).Error("Date to must be an RFC 3339 timestamp"),
+		),
+		validation.Field(&f.Page,
+			validation.Required.Error("Page is required"),
+			validation.Min(1).Error("Page must be greater than 0"),
+		),
+		validation.Field(&f.Limit,
+			validation.Required.Error("Limit is required"),
+			validation.Min(1).Error("Limit must be greater than 0"),
+			validation.Max(100).Error("Limit cannot exceed 100"),
+		),
+		validation.Field(&f.SortBy,
+			validation.Length(0, 50).Error("SortBy cannot exceed 50 characters"),
+		),
+		validation.Field(&f.SortOrder,
+			validation.In(SortOrderAsc, SortOrderDesc).Error("Order must be either 'asc' or 'desc'"),
+		),
+	)
+}
+
+// NotificationLogResponseDTO is one notification delivery.
+type NotificationLogResponseDTO struct {
+	NotificationLogID string    `json:"notification_log_id"`
+	UserID            string    `json:"user_id"`
+	NotificationType  string    `json:"notification_type"`
+	Channel           string    `json:"channel"`
+	Recipient         *string   `json:"recipient,omitempty"`
+	Title             *string   `json:"title,omitempty"`
+	Message           *string   `json:"message,omitempty"`
+	Status            string    `json:"status"`
+	ErrorReason       *string   `json:"error_reason,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
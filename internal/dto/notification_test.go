@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationSettingUpdateRequestDTO_Validate(t *testing.T) {
+	enabled := true
+
+	t.Run("empty", func(t *testing.T) {
+		assert.NoError(t, NotificationSettingUpdateRequestDTO{}.Validate())
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		muted := []string{"security.new_device"}
+		assert.NoError(t, NotificationSettingUpdateRequestDTO{
+			EmailEnabled: &enabled,
+			MutedTypes:   muted,
+		}.Validate())
+	})
+
+	t.Run("empty muted type", func(t *testing.T) {
+		muted := []string{""}
+		assert.Error(t, NotificationSettingUpdateRequestDTO{MutedTypes: muted}.Validate())
+	})
+
+	t.Run("muted type too long", func(t *testing.T) {
+		muted := []string{strings.Repeat("a", 101)}
+		assert.Error(t, NotificationSettingUpdateRequestDTO{MutedTypes: muted}.Validate())
+	})
+
+	t.Run("too many muted types", func(t *testing.T) {
+		muted := make([]string, 51)
+		for i := range muted {
+			muted[i] = "type"
+		}
+		assert.Error(t, NotificationSettingUpdateRequestDTO{MutedTypes: muted}.Validate())
+	})
+}
+
+func TestNotificationLogFilterDTO_Validate(t *testing.T) {
+	valid := func() NotificationLogFilterDTO {
+		return NotificationLogFilterDTO{PaginationRequestDTO: PaginationRequestDTO{Page: 1, Limit: 10}}
+	}
+	str := func(s string) *string { return &s }
+
+	t.Run("valid minimal", func(t *testing.T) {
+		assert.NoError(t, valid().Validate())
+	})
+
+	t.Run("valid full", func(t *testing.T) {
+		f := valid()
+		f.UserID = str("7f1c6f56-5c2b-4ef3-9d0a-1d2f3c4b5a69")
+		f.NotificationType = str("security.new_device")
+		f.Channel = str("in_app")
+		f.Status = str("skipped")
+		f.DateFrom = str("2026-01-01T00:00:00Z")
+		f.DateTo = str("2026-02-01T00:00:00Z")
+		f.SortOrder = SortOrderAsc
+		assert.NoError(t, f.Validate())
+	})
+
+	t.Run("invalid fields", func(t *testing.T) {
+		for name, mutate := range map[string]func(*NotificationLogFilterDTO){
+			"user id":   func(f *NotificationLogFilterDTO) { f.UserID = str("not-a-uuid") },
+			"channel":   func(f *NotificationLogFilterDTO) { f.Channel = str("push") },
+			"status":    func(f *NotificationLogFilterDTO) { f.Status = str("queued") },
+			"date from": func(f *NotificationLogFilterDTO) { f.DateFrom = str("2026-01-01") },
+			"date to":   func(f *NotificationLogFilterDTO) { f.DateTo = str("yesterday") },
+			"limit":     func(f *NotificationLogFilterDTO) { f.Limit = 101 },
+			"page":      func(f *NotificationLogFilterDTO) { f.Page = 0 },
+			"order":     func(f *NotificationLogFilterDTO) { f.SortOrder = "up" },
+		} {
+			f := valid()
+			mutate(&f)
+			assert.Error(t, f.Validate(), name)
+		}
+	})
+}
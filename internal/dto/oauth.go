@@ -23,6 +23,9 @@ type OAuthAuthorizeRequestDTO struct {
 	Nonce               string `json:"nonce"`
 	CodeChallenge       string `json:"code_challenge"`
 	CodeChallengeMethod string `json:"code_challenge_method"`
+	// SessionID is the login session of the authenticated user, taken from
+	// the sid claim of their access token rather than from the request.
+	SessionID string `json:"-"`
 }
 
 // Validate sanitises inputs and checks required OAuth parameters.
@@ -140,6 +143,9 @@ type OAuthTokenRequestDTO struct {
 	CodeVerifier string `json:"code_verifier"`
 	RefreshToken string `json:"refresh_token"`
 	Scope        string `json:"scope"`
+	// Audience is the identifier of the API a client_credentials token is
+	// requested for.
+	Audience string `json:"audience"`
 	// Client credentials (from body when token_endpoint_auth_method=client_secret_post)
 	ClientID     string `json:"client_id"`
 	ClientSecret string `json:"client_secret"`
@@ -153,6 +159,7 @@ func (r *OAuthTokenRequestDTO) Validate() error {
 	r.CodeVerifier = security.SanitizeInput(r.CodeVerifier)
 	r.RefreshToken = security.SanitizeInput(r.RefreshToken)
 	r.Scope = security.SanitizeInput(r.Scope)
+	r.Audience = security.SanitizeInput(r.Audience)
 	r.ClientID = security.SanitizeInput(r.ClientID)
 	r.ClientSecret = security.SanitizeInput(r.ClientSecret)
 
@@ -252,6 +259,46 @@ type OAuthIntrospectResponseDTO struct {
 	Jti       string `json:"jti,omitempty"`
 }
 
+// ──────────────────────────────────────────────────────────────────────────────
+// End Session Endpoint (OIDC RP-Initiated Logout 1.0)
+// ──────────────────────────────────────────────────────────────────────────────
+
+// OAuthEndSessionRequestDTO captures the query parameters or form-encoded
+// body of the GET/POST /oauth/end_session endpoint.
+type OAuthEndSessionRequestDTO struct {
+	IDTokenHint           string `json:"id_token_hint"`
+	ClientID              string `json:"client_id"`
+	PostLogoutRedirectURI string `json:"post_logout_redirect_uri"`
+	State                 string `json:"state"`
+}
+
+// Validate sanitises inputs. All parameters are optional, but a
+// post_logout_redirect_uri can only be honoured for a known client.
+func (r *OAuthEndSessionRequestDTO) Validate() error {
+	r.IDTokenHint = security.SanitizeInput(r.IDTokenHint)
+	r.ClientID = security.SanitizeInput(r.ClientID)
+	r.PostLogoutRedirectURI = security.SanitizeInput(r.PostLogoutRedirectURI)
+	r.State = security.SanitizeInput(r.State)
+
+	return validation.ValidateStruct(r,
+		validation.Field(&r.IDTokenHint,
+			validation.Length(0, 8192).Error("id_token_hint must not exceed 8192 characters"),
+		),
+		validation.Field(&r.ClientID,
+			validation.Length(0, 255).Error("client_id must not exceed 255 characters"),
+		),
+		validation.Field(&r.PostLogoutRedirectURI,
+			validation.When(r.IDTokenHint == "" && r.ClientID == "",
+				validation.Empty.Error("post_logout_redirect_uri requires id_token_hint or client_id"),
+			),
+			validation.Length(0, 2048).Error("post_logout_redirect_uri must not exceed 2048 characters"),
+		),
+		validation.Field(&r.State,
+			validation.Length(0, 512).Error("state must not exceed 512 characters"),
+		),
+	)
+}
+
 // ──────────────────────────────────────────────────────────────────────────────
 // Discovery / Well-Known (RFC 8414)
 // ──────────────────────────────────────────────────────────────────────────────
@@ -273,6 +320,11 @@ type OAuthDiscoveryResponseDTO struct {
 	IDTokenSignAlgValues  []string `json:"id_token_signing_alg_values_supported"`
 	TokenEndpointAuth     []string `json:"token_endpoint_auth_methods_supported"`
 	CodeChallengeMethods  []string `json:"code_challenge_methods_supported"`
+	EndSessionEndpoint    string   `json:"end_session_endpoint"`
+	FrontchannelLogout    bool     `json:"frontchannel_logout_supported"`
+	FrontchannelLogoutSID bool     `json:"frontchannel_logout_session_supported"`
+	BackchannelLogout     bool     `json:"backchannel_logout_supported"`
+	BackchannelLogoutSID  bool     `json:"backchannel_logout_session_supported"`
 }
 
 // ──────────────────────────────────────────────────────────────────────────────
@@ -351,6 +403,16 @@ type OAuthConsentDecisionResult struct {
 	RedirectURI string
 }
 
+// OAuthEndSessionResult is the internal result from the logout service.
+type OAuthEndSessionResult struct {
+	// RedirectURI is the validated post_logout_redirect_uri with state
+	// appended; empty when the user stays on the signed-out page.
+	RedirectURI string
+	// FrontChannelLogoutURIs are loaded in hidden iframes of the signed-out
+	// page so the clients of the session can clear their own cookies.
+	FrontChannelLogoutURIs []string
+}
+
 // OAuthTokenResult is the internal result from the token service.
 type OAuthTokenResult struct {
 	AccessToken  string
@@ -359,6 +421,16 @@ type OAuthTokenResult struct {
 	RefreshToken string
 	IDToken      string
 	Scope        string
+	Deprecation  *OAuthDeprecationNotice
+}
+
+// OAuthDeprecationNotice describes the deprecation state of the client, or of
+// an API linked to it, that obtained a token. The token handler turns it into
+// Deprecation, Sunset and Warning response headers.
+type OAuthDeprecationNotice struct {
+	DeprecatedAt time.Time
+	SunsetAt     *time.Time
+	Message      string
 }
 
 // OAuthTokenIssuedAt is used internally to track when a token was issued.
@@ -242,3 +242,29 @@ func TestOAuthIntrospectRequestDTO_Validate(t *testing.T) {
 		assert.Contains(t, err.Error(), "token_type_hint")
 	})
 }
+
+func TestOAuthEndSessionRequestDTO_Validate(t *testing.T) {
+	t.Run("valid without parameters", func(t *testing.T) {
+		r := OAuthEndSessionRequestDTO{}
+		require.NoError(t, r.Validate())
+	})
+
+	t.Run("valid with client and redirect", func(t *testing.T) {
+		r := OAuthEndSessionRequestDTO{ClientID: "my-client", PostLogoutRedirectURI: "https://app.example.com/signed-out", State: "xyz"}
+		require.NoError(t, r.Validate())
+	})
+
+	t.Run("redirect without client", func(t *testing.T) {
+		r := OAuthEndSessionRequestDTO{PostLogoutRedirectURI: "https://app.example.com/signed-out"}
+		err := r.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "post_logout_redirect_uri")
+	})
+
+	t.Run("state too long", func(t *testing.T) {
+		r := OAuthEndSessionRequestDTO{State: strings.Repeat("s", 513)}
+		err := r.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "state")
+	})
+}
@@ -0,0 +1,145 @@
+package dto
+
+import (
+	"regexp"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+
+	"github.com/maintainerd/auth/internal/model"
+)
+
+// OnboardingClientRequestDTO customises the client provisioned during
+// onboarding. Every field is optional.
+type OnboardingClientRequestDTO struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	ClientType  string `json:"client_type"`
+	Domain      string `json:"domain"`
+}
+
+// Validate validates the onboarding client options.
+func (r OnboardingClientRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Name,
+			validation.Length(0, 100).Error("Client name must not exceed 100 characters"),
+		),
+		validation.Field(&r.DisplayName,
+			validation.Length(0, 100).Error("Client display name must not exceed 100 characters"),
+		),
+		validation.Field(&r.ClientType,
+			validation.When(r.ClientType != "",
+				validation.In(model.ClientTypeTraditional, model.ClientTypeSPA, model.ClientTypeMobile, model.ClientTypeM2M).Error("Invalid client type"),
+			),
+		),
+		validation.Field(&r.Domain,
+			validation.When(r.Domain != "", is.Domain.Error("Domain must be a valid domain name")),
+		),
+	)
+}
+
+// OnboardingAdminRequestDTO describes the initial tenant administrator.
+type OnboardingAdminRequestDTO struct {
+	RoleName string `json:"role_name"`
+	Username string `json:"username"`
+	Fullname string `json:"fullname"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// Validate validates the onboarding admin options.
+func (r OnboardingAdminRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.RoleName,
+			validation.Length(0, 100).Error("Role name must not exceed 100 characters"),
+		),
+		validation.Field(&r.Username,
+			validation.Required.Error("Username is required"),
+			validation.Length(3, 50).Error("Username must be between 3 and 50 characters"),
+			validation.Match(regexp.MustCompile(`^[a-zA-Z0-9_\-\.@]+$`)).Error("Username contains invalid characters"),
+		),
+		validation.Field(&r.Fullname,
+			validation.Length(0, 255).Error("Fullname must not exceed 255 characters"),
+		),
+		validation.Field(&r.Email,
+			validation.Required.Error("Email is required"),
+			is.Email.Error("Invalid email format"),
+			validation.Length(0, 100).Error("Email must not exceed 100 characters"),
+		),
+		validation.Field(&r.Password,
+			validation.Required.Error("Password is required"),
+			validation.Length(8, 100).Error("Password must be between 8 and 100 characters"),
+		),
+	)
+}
+
+// OnboardingSignupFlowRequestDTO customises the starter signup flow.
+type OnboardingSignupFlowRequestDTO struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Config      map[string]any `json:"config"`
+}
+
+// Validate validates the onboarding signup flow options.
+func (r OnboardingSignupFlowRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Name,
+			validation.Length(0, 100).Error("Signup flow name must not exceed 100 characters"),
+		),
+		validation.Field(&r.Description,
+			validation.Length(0, 500).Error("Signup flow description must not exceed 500 characters"),
+		),
+	)
+}
+
+// OnboardingRequestDTO is the request body for onboarding a tenant. The email
+// and signup flow sections are optional; when omitted the corresponding
+// checklist steps remain pending.
+type OnboardingRequestDTO struct {
+	Client     OnboardingClientRequestDTO      `json:"client"`
+	Admin      OnboardingAdminRequestDTO       `json:"admin"`
+	Email      *EmailConfigUpdateRequestDTO    `json:"email,omitempty"`
+	SignupFlow *OnboardingSignupFlowRequestDTO `json:"signup_flow,omitempty"`
+}
+
+// Validate validates the onboarding request.
+func (r OnboardingRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Client),
+		validation.Field(&r.Admin),
+		validation.Field(&r.Email),
+		validation.Field(&r.SignupFlow),
+	)
+}
+
+// OnboardingChecklistItemResponseDTO is a single onboarding checklist step.
+type OnboardingChecklistItemResponseDTO struct {
+	Key       string `json:"key"`
+	Title     string `json:"title"`
+	Completed bool   `json:"completed"`
+}
+
+// OnboardingChecklistResponseDTO is the JSON representation of a tenant's
+// onboarding checklist.
+type OnboardingChecklistResponseDTO struct {
+	TenantID   string                               `json:"tenant_id"`
+	Steps      []OnboardingChecklistItemResponseDTO `json:"steps"`
+	Remaining  int                                  `json:"remaining"`
+	IsComplete bool                                 `json:"is_complete"`
+}
+
+// OnboardingResponseDTO is the JSON representation of a completed onboarding
+// run. The client secret is only returned here, once.
+type OnboardingResponseDTO struct {
+	TenantID           string                         `json:"tenant_id"`
+	IdentityProviderID string                         `json:"identity_provider_id"`
+	ClientID           string                         `json:"client_id"`
+	ClientIdentifier   string                         `json:"client_identifier"`
+	ClientSecret       *string                        `json:"client_secret,omitempty"`
+	AdminRoleID        string                         `json:"admin_role_id"`
+	DefaultRoleID      string                         `json:"default_role_id"`
+	AdminUserID        string                         `json:"admin_user_id"`
+	EmailConfigID      *string                        `json:"email_config_id,omitempty"`
+	SignupFlowID       *string                        `json:"signup_flow_id,omitempty"`
+	Checklist          OnboardingChecklistResponseDTO `json:"checklist"`
+}
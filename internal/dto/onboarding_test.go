@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maintainerd/auth/internal/model"
+)
+
+func validOnboarding() OnboardingRequestDTO {
+	return OnboardingRequestDTO{
+		Admin: OnboardingAdminRequestDTO{
+			Username: "owner",
+			Email:    "owner@example.com",
+			Password: "Sup3rSecret!",
+		},
+	}
+}
+
+func TestOnboardingRequestDTO_Validate(t *testing.T) {
+	t.Run("valid minimal", func(t *testing.T) {
+		assert.NoError(t, validOnboarding().Validate())
+	})
+
+	t.Run("valid with all sections", func(t *testing.T) {
+		d := validOnboarding()
+		d.Client = OnboardingClientRequestDTO{
+			Name:        "web",
+			DisplayName: "Web App",
+			ClientType:  model.ClientTypeTraditional,
+			Domain:      "app.example.com",
+		}
+		d.Admin.RoleName = "owner"
+		d.Email = &EmailConfigUpdateRequestDTO{Provider: "smtp", FromAddress: "noreply@example.com"}
+		d.SignupFlow = &OnboardingSignupFlowRequestDTO{Name: "starter", Config: map[string]any{"require_email": true}}
+		assert.NoError(t, d.Validate())
+	})
+
+	t.Run("missing admin username", func(t *testing.T) {
+		d := validOnboarding()
+		d.Admin.Username = ""
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("invalid admin username", func(t *testing.T) {
+		d := validOnboarding()
+		d.Admin.Username = "bad name!"
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("invalid admin email", func(t *testing.T) {
+		d := validOnboarding()
+		d.Admin.Email = "not-an-email"
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("short admin password", func(t *testing.T) {
+		d := validOnboarding()
+		d.Admin.Password = "short"
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("invalid client type", func(t *testing.T) {
+		d := validOnboarding()
+		d.Client.ClientType = "desktop"
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("invalid client domain", func(t *testing.T) {
+		d := validOnboarding()
+		d.Client.Domain = "not a domain"
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("client name too long", func(t *testing.T) {
+		d := validOnboarding()
+		d.Client.Name = strings.Repeat("a", 101)
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("invalid email section", func(t *testing.T) {
+		d := validOnboarding()
+		d.Email = &EmailConfigUpdateRequestDTO{Provider: "carrier-pigeon"}
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("signup flow description too long", func(t *testing.T) {
+		d := validOnboarding()
+		d.SignupFlow = &OnboardingSignupFlowRequestDTO{Description: strings.Repeat("a", 501)}
+		require.Error(t, d.Validate())
+	})
+}
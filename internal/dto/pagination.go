@@ -43,6 +43,36 @@ func (p PaginationRequestDTO) Validate() error {
 	)
 }
 
+// CursorPaginationRequestDTO holds keyset pagination parameters, the
+// alternative to page/limit for large lists. An empty Cursor requests the
+// first page. Pages follow creation order, so SortBy cannot be used.
+type CursorPaginationRequestDTO struct {
+	Cursor    string `json:"cursor"`
+	Limit     int    `json:"limit"`
+	SortBy    string `json:"sort_by"`
+	SortOrder string `json:"sort_order"`
+}
+
+// Validate validates the cursor pagination request
+func (p CursorPaginationRequestDTO) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.Cursor,
+			validation.Length(0, 64).Error("Cursor cannot exceed 64 characters"),
+		),
+		validation.Field(&p.Limit,
+			validation.Required.Error("Limit is required"),
+			validation.Min(1).Error("Limit must be greater than 0"),
+			validation.Max(100).Error("Limit cannot exceed 100"),
+		),
+		validation.Field(&p.SortBy,
+			validation.Empty.Error("SortBy cannot be used with cursor pagination"),
+		),
+		validation.Field(&p.SortOrder,
+			validation.In(SortOrderAsc, SortOrderDesc).Error("Order must be either 'asc' or 'desc'"),
+		),
+	)
+}
+
 // Generic paginated response
 type PaginatedResponseDTO[T any] struct {
 	Rows       []T   `json:"rows"`
@@ -51,3 +81,12 @@ type PaginatedResponseDTO[T any] struct {
 	Limit      int   `json:"limit"`
 	TotalPages int   `json:"total_pages"`
 }
+
+// CursorPaginatedResponseDTO is a keyset page. Pass NextCursor as cursor to
+// fetch the next page; it is omitted on the last page.
+type CursorPaginatedResponseDTO[T any] struct {
+	Rows       []T    `json:"rows"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
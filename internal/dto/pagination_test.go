@@ -101,3 +101,13 @@ func TestPaginatedResponseDto_Fields(t *testing.T) {
 	assert.Len(t, resp.Rows, 2)
 	assert.Equal(t, int64(2), resp.Total)
 }
+
+func TestCursorPaginationRequestDto_Validate(t *testing.T) {
+	assert.NoError(t, CursorPaginationRequestDTO{Limit: 10}.Validate())
+	assert.NoError(t, CursorPaginationRequestDTO{Cursor: "bDE6NDI", Limit: 100, SortOrder: SortOrderAsc}.Validate())
+	assert.Error(t, CursorPaginationRequestDTO{}.Validate())
+	assert.Error(t, CursorPaginationRequestDTO{Limit: 101}.Validate())
+	assert.Error(t, CursorPaginationRequestDTO{Limit: 10, SortBy: "username"}.Validate())
+	assert.Error(t, CursorPaginationRequestDTO{Limit: 10, SortOrder: "random"}.Validate())
+	assert.Error(t, CursorPaginationRequestDTO{Limit: 10, Cursor: string(make([]byte, 65))}.Validate())
+}
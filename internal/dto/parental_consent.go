@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+// ParentalConsentRequestDTO carries the token from the email sent to the
+// guardian of an under-age user.
+type ParentalConsentRequestDTO struct {
+	Token string `json:"token"`
+}
+
+func (r ParentalConsentRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Token,
+			validation.Required.Error("Token is required"),
+			validation.Length(64, 64).Error("Token must be 64 characters"),
+			is.Hexadecimal.Error("Token must be hexadecimal"),
+		),
+	)
+}
+
+// ParentalConsentResponseDTO describes a consent request to the guardian.
+type ParentalConsentResponseDTO struct {
+	ParentalConsentID string     `json:"parental_consent_id"`
+	Username          string     `json:"username"`
+	Status            string     `json:"status"`
+	ExpiresAt         time.Time  `json:"expires_at"`
+	DecidedAt         *time.Time `json:"decided_at,omitempty"`
+}
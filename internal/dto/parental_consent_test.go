@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParentalConsentRequestDTO_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, ParentalConsentRequestDTO{Token: strings.Repeat("a1", 32)}.Validate())
+	})
+	t.Run("missing token", func(t *testing.T) {
+		assert.Error(t, ParentalConsentRequestDTO{}.Validate())
+	})
+	t.Run("wrong length", func(t *testing.T) {
+		assert.Error(t, ParentalConsentRequestDTO{Token: strings.Repeat("a", 63)}.Validate())
+	})
+	t.Run("not hexadecimal", func(t *testing.T) {
+		assert.Error(t, ParentalConsentRequestDTO{Token: strings.Repeat("z", 64)}.Validate())
+	})
+}
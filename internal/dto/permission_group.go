@@ -0,0 +1,121 @@
+package dto
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
+)
+
+// MaxPermissionGroupsPerAssignment caps how many groups one assignment
+// request may expand.
+const MaxPermissionGroupsPerAssignment = 20
+
+// PermissionGroupResponseDTO describes a permission group and the
+// permissions it bundles.
+type PermissionGroupResponseDTO struct {
+	PermissionGroupUUID uuid.UUID               `json:"permission_group_id"`
+	Name                string                  `json:"name"`
+	Description         string                  `json:"description"`
+	Permissions         []PermissionResponseDTO `json:"permissions"`
+	CreatedAt           time.Time               `json:"created_at"`
+	UpdatedAt           time.Time               `json:"updated_at"`
+}
+
+// PermissionGroupCreateRequestDTO is the body of a request to create a
+// permission group. Permissions may be empty and added later.
+type PermissionGroupCreateRequestDTO struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Permissions []uuid.UUID `json:"permissions"`
+}
+
+func (r PermissionGroupCreateRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Name,
+			validation.Required.Error("Name is required"),
+			validation.RuneLength(3, 100).Error("Name must be between 3 and 100 characters"),
+		),
+		validation.Field(&r.Description,
+			validation.RuneLength(0, 255).Error("Description must be at most 255 characters"),
+		),
+		validation.Field(&r.Permissions,
+			validation.Each(is.UUID.Error("Invalid UUID provided")),
+		),
+	)
+}
+
+// PermissionGroupUpdateRequestDTO is the body of a request to rename or
+// describe a permission group. Its permissions are changed separately.
+type PermissionGroupUpdateRequestDTO struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (r PermissionGroupUpdateRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Name,
+			validation.Required.Error("Name is required"),
+			validation.RuneLength(3, 100).Error("Name must be between 3 and 100 characters"),
+		),
+		validation.Field(&r.Description,
+			validation.RuneLength(0, 255).Error("Description must be at most 255 characters"),
+		),
+	)
+}
+
+// PermissionGroupAddPermissionsRequestDTO is the body of a request to add
+// permissions to a group.
+type PermissionGroupAddPermissionsRequestDTO struct {
+	Permissions []uuid.UUID `json:"permissions"`
+}
+
+func (r PermissionGroupAddPermissionsRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Permissions,
+			validation.Required.Error("Permission UUIDs are required"),
+			validation.Each(is.UUID.Error("Invalid UUID provided")),
+		),
+	)
+}
+
+// PermissionGroupAssignRequestDTO is the body of a request to assign
+// permission groups to a role, client API or API key API. The groups are
+// expanded into their permissions, which are then assigned as if listed
+// one by one.
+type PermissionGroupAssignRequestDTO struct {
+	PermissionGroups []uuid.UUID `json:"permission_groups"`
+}
+
+func (r PermissionGroupAssignRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.PermissionGroups,
+			validation.Required.Error("Permission group UUIDs are required"),
+			validation.Length(1, MaxPermissionGroupsPerAssignment).Error("At most 20 permission groups can be assigned at once"),
+			validation.Each(is.UUID.Error("Invalid UUID provided")),
+		),
+	)
+}
+
+// PermissionGroupAssignResponseDTO reports the permissions an assignment
+// expanded to.
+type PermissionGroupAssignResponseDTO struct {
+	Permissions []uuid.UUID `json:"permissions"`
+}
+
+// PermissionGroupFilterDTO holds the query parameters for listing permission
+// groups.
+type PermissionGroupFilterDTO struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+
+	// Pagination and sorting
+	PaginationRequestDTO
+}
+
+func (f PermissionGroupFilterDTO) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.PaginationRequestDTO),
+	)
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissionGroupCreateRequestDTO_Validate(t *testing.T) {
+	t.Run("valid without permissions", func(t *testing.T) {
+		d := PermissionGroupCreateRequestDTO{Name: "read-only"}
+		assert.NoError(t, d.Validate())
+	})
+
+	t.Run("valid with permissions", func(t *testing.T) {
+		d := PermissionGroupCreateRequestDTO{Name: "user-admin", Description: "Manage users", Permissions: []uuid.UUID{uuid.New()}}
+		assert.NoError(t, d.Validate())
+	})
+
+	t.Run("name too short", func(t *testing.T) {
+		d := PermissionGroupCreateRequestDTO{Name: "ab"}
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("description too long", func(t *testing.T) {
+		d := PermissionGroupCreateRequestDTO{Name: "read-only", Description: strings.Repeat("a", 256)}
+		require.Error(t, d.Validate())
+	})
+}
+
+func TestPermissionGroupUpdateRequestDTO_Validate(t *testing.T) {
+	assert.NoError(t, PermissionGroupUpdateRequestDTO{Name: "read-only"}.Validate())
+	require.Error(t, PermissionGroupUpdateRequestDTO{}.Validate())
+}
+
+func TestPermissionGroupAddPermissionsRequestDTO_Validate(t *testing.T) {
+	assert.NoError(t, PermissionGroupAddPermissionsRequestDTO{Permissions: []uuid.UUID{uuid.New()}}.Validate())
+	require.Error(t, PermissionGroupAddPermissionsRequestDTO{}.Validate())
+}
+
+func TestPermissionGroupAssignRequestDTO_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		d := PermissionGroupAssignRequestDTO{PermissionGroups: []uuid.UUID{uuid.New()}}
+		assert.NoError(t, d.Validate())
+	})
+
+	t.Run("missing groups", func(t *testing.T) {
+		require.Error(t, PermissionGroupAssignRequestDTO{}.Validate())
+	})
+
+	t.Run("too many groups", func(t *testing.T) {
+		groups := make([]uuid.UUID, MaxPermissionGroupsPerAssignment+1)
+		for i := range groups {
+			groups[i] = uuid.New()
+		}
+		require.Error(t, PermissionGroupAssignRequestDTO{PermissionGroups: groups}.Validate())
+	})
+}
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// PersonalAccessTokenCreateRequestDTO is the body of a request to mint a
+// personal access token. Scopes are permission names the token may use and
+// must be a subset of the caller's own permissions.
+type PersonalAccessTokenCreateRequestDTO struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (r PersonalAccessTokenCreateRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Name,
+			validation.Required.Error("Name is required"),
+			validation.RuneLength(1, 100).Error("Name must be between 1 and 100 characters"),
+		),
+		validation.Field(&r.Scopes,
+			validation.Required.Error("At least one scope is required"),
+			validation.Length(1, 100).Error("At most 100 scopes are allowed"),
+			validation.Each(
+				validation.Required.Error("Scope must not be empty"),
+				validation.RuneLength(1, 255).Error("Scope must be at most 255 characters"),
+			),
+		),
+	)
+}
+
+// PersonalAccessTokenResponseDTO describes a personal access token. The
+// token itself is never returned after creation; TokenPrefix identifies it.
+type PersonalAccessTokenResponseDTO struct {
+	PersonalAccessTokenID string     `json:"personal_access_token_id"`
+	Name                  string     `json:"name"`
+	TokenPrefix           string     `json:"token_prefix"`
+	Scopes                []string   `json:"scopes"`
+	ExpiresAt             *time.Time `json:"expires_at"`
+	LastUsedAt            *time.Time `json:"last_used_at"`
+	RevokedAt             *time.Time `json:"revoked_at"`
+	CreatedAt             time.Time  `json:"created_at"`
+}
+
+// PersonalAccessTokenCreateResponseDTO is returned once, when a token is
+// created, and is the only response that carries the plain-text token.
+type PersonalAccessTokenCreateResponseDTO struct {
+	PersonalAccessTokenResponseDTO
+	Token string `json:"token"`
+}
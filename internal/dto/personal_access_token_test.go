@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersonalAccessTokenCreateRequestDTO_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		d := PersonalAccessTokenCreateRequestDTO{Name: "ci", Scopes: []string{"user:read"}}
+		assert.NoError(t, d.Validate())
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		d := PersonalAccessTokenCreateRequestDTO{Scopes: []string{"user:read"}}
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("name too long", func(t *testing.T) {
+		d := PersonalAccessTokenCreateRequestDTO{Name: strings.Repeat("a", 101), Scopes: []string{"user:read"}}
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("missing scopes", func(t *testing.T) {
+		d := PersonalAccessTokenCreateRequestDTO{Name: "ci"}
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("empty scope", func(t *testing.T) {
+		d := PersonalAccessTokenCreateRequestDTO{Name: "ci", Scopes: []string{"user:read", ""}}
+		require.Error(t, d.Validate())
+	})
+}
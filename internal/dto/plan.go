@@ -0,0 +1,108 @@
+package dto
+
+import (
+	"regexp"
+	"slices"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+
+	"github.com/maintainerd/auth/internal/model"
+)
+
+// planNamePattern matches plan names such as "free" or "pro-2026".
+var planNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// PlanResponseDTO is the JSON representation of a plan. MaxUsers and
+// MaxClients of 0 are unlimited.
+type PlanResponseDTO struct {
+	PlanID      string          `json:"plan_id"`
+	Name        string          `json:"name"`
+	DisplayName string          `json:"display_name"`
+	Description string          `json:"description"`
+	Features    map[string]bool `json:"features"`
+	MaxUsers    int64           `json:"max_users"`
+	MaxClients  int64           `json:"max_clients"`
+	IsDefault   bool            `json:"is_default"`
+	Status      string          `json:"status"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// PlanRequestDTO is the request body for creating or replacing a plan.
+// Features not listed are not included in the plan.
+type PlanRequestDTO struct {
+	Name        string          `json:"name"`
+	DisplayName string          `json:"display_name"`
+	Description string          `json:"description"`
+	Features    map[string]bool `json:"features"`
+	MaxUsers    int64           `json:"max_users"`
+	MaxClients  int64           `json:"max_clients"`
+	IsDefault   bool            `json:"is_default"`
+	Status      *string         `json:"status,omitempty"`
+}
+
+// Validate validates the plan request.
+func (r PlanRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Name,
+			validation.Required.Error("Name is required"),
+			validation.Length(1, 100).Error("Name must not exceed 100 characters"),
+			validation.Match(planNamePattern).Error("Name must contain only lowercase letters, numbers, underscores and hyphens"),
+		),
+		validation.Field(&r.DisplayName,
+			validation.Length(0, 255).Error("Display name must not exceed 255 characters"),
+		),
+		validation.Field(&r.Description,
+			validation.Length(0, 1000).Error("Description must not exceed 1000 characters"),
+		),
+		validation.Field(&r.Features,
+			validation.By(func(any) error {
+				for feature := range r.Features {
+					if !slices.Contains(model.PlanFeatures, feature) {
+						return validation.NewError("validation_plan_feature", "Unknown feature "+feature)
+					}
+				}
+				return nil
+			}),
+		),
+		validation.Field(&r.MaxUsers,
+			validation.Min(int64(0)).Error("Max users must not be negative"),
+		),
+		validation.Field(&r.MaxClients,
+			validation.Min(int64(0)).Error("Max clients must not be negative"),
+		),
+		validation.Field(&r.Status,
+			validation.NilOrNotEmpty,
+			validation.In(model.StatusActive, model.StatusInactive).Error("Status must be 'active' or 'inactive'"),
+		),
+	)
+}
+
+// TenantPlanResponseDTO is the plan a tenant is subscribed to, with the
+// users and clients counted against it. Plan is null when the tenant is not
+// limited; Inherited is true when it is on the default plan.
+type TenantPlanResponseDTO struct {
+	TenantID  string           `json:"tenant_id"`
+	Plan      *PlanResponseDTO `json:"plan"`
+	Inherited bool             `json:"inherited"`
+	Users     int64            `json:"users"`
+	Clients   int64            `json:"clients"`
+}
+
+// TenantPlanSetRequestDTO is the request body for subscribing a tenant to a
+// plan. A null PlanID puts the tenant on the default plan.
+type TenantPlanSetRequestDTO struct {
+	PlanID *string `json:"plan_id"`
+}
+
+// Validate validates the tenant plan request.
+func (r TenantPlanSetRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.PlanID,
+			validation.NilOrNotEmpty,
+			is.UUID.Error("Plan ID must be a valid UUID"),
+		),
+	)
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+)
+
+func validPlanRequest() PlanRequestDTO {
+	return PlanRequestDTO{
+		Name:     "pro",
+		Features: map[string]bool{model.PlanFeatureMFA: true},
+		MaxUsers: 100,
+	}
+}
+
+func TestPlanRequestDTO_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, validPlanRequest().Validate())
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		d := validPlanRequest()
+		d.Name = ""
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		d := validPlanRequest()
+		d.Name = "Pro Plan"
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("unknown feature", func(t *testing.T) {
+		d := validPlanRequest()
+		d.Features = map[string]bool{"sso": true}
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("negative limit", func(t *testing.T) {
+		d := validPlanRequest()
+		d.MaxClients = -1
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		d := validPlanRequest()
+		d.Status = ptr.Ptr("archived")
+		require.Error(t, d.Validate())
+	})
+}
+
+func TestTenantPlanSetRequestDTO_Validate(t *testing.T) {
+	assert.NoError(t, TenantPlanSetRequestDTO{}.Validate())
+	assert.NoError(t, TenantPlanSetRequestDTO{PlanID: ptr.Ptr("7c9e6679-7425-40de-944b-e07fc1f90ae7")}.Validate())
+	require.Error(t, TenantPlanSetRequestDTO{PlanID: ptr.Ptr("pro")}.Validate())
+}
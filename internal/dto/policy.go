@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"gorm.io/datatypes"
 
+	"github.com/maintainerd/auth/internal/abac"
 	"github.com/maintainerd/auth/internal/model"
 )
 
@@ -27,6 +28,12 @@ var policyNamePattern = regexp.MustCompile(`^[a-z0-9_:/\\-]+$`)
 //       "effect": "allow",
 //       "action": ["user:*", "role:create"],
 //       "resource": ["auth:*", "account:profile"]
+//     },
+//     {
+//       "effect": "deny",
+//       "action": ["user:delete"],
+//       "resource": ["auth:*"],
+//       "condition": "resource.params.user_uuid == subject.id"
 //     }
 //   ]
 // }
@@ -39,6 +46,11 @@ var policyNamePattern = regexp.MustCompile(`^[a-z0-9_:/\\-]+$`)
 // - Service and all APIs: "auth:*"
 // - Service and specific API: "auth:login"
 //
+// Condition format:
+// - Optional CEL expression over subject, resource and request that must
+//   yield a bool (see package abac). A statement only applies when its
+//   condition holds.
+//
 // Note: Action and resource values are not validated against existing
 // permissions/services. Invalid values will simply result in no access.
 
@@ -63,9 +75,10 @@ func (p PolicyDocument) Validate() error {
 
 // PolicyStatement represents a single statement in a policy
 type PolicyStatement struct {
-	Effect   string   `json:"effect"`   // "allow" or "deny"
-	Action   []string `json:"action"`   // e.g., ["user:*", "role:create"]
-	Resource []string `json:"resource"` // e.g., ["auth:*", "account:profile"]
+	Effect    string   `json:"effect"`              // "allow" or "deny"
+	Action    []string `json:"action"`              // e.g., ["user:*", "role:create"]
+	Resource  []string `json:"resource"`            // e.g., ["auth:*", "account:profile"]
+	Condition string   `json:"condition,omitempty"` // e.g., "resource.params.user_uuid == subject.id"
 }
 
 // Validate validates the PolicyStatement structure using ozzo-validation
@@ -83,9 +96,24 @@ func (s PolicyStatement) Validate() error {
 			validation.Required.Error("Statement must contain at least one resource"),
 			validation.Length(1, 0).Error("Statement must contain at least one resource"),
 		),
+		validation.Field(&s.Condition,
+			validation.By(validatePolicyCondition),
+		),
 	)
 }
 
+// validatePolicyCondition checks that a non-empty condition compiles
+func validatePolicyCondition(value any) error {
+	condition, _ := value.(string)
+	if condition == "" {
+		return nil
+	}
+	if err := abac.ValidateCondition(condition); err != nil {
+		return validation.NewError("validation_error", "Statement condition is invalid: "+err.Error())
+	}
+	return nil
+}
+
 // Policy output structure for listing (without document)
 type PolicyResponseDTO struct {
 	PolicyUUID  uuid.UUID `json:"policy_id"`
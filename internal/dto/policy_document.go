@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+
+	"github.com/maintainerd/auth/internal/model"
+)
+
+// maxAcceptedPolicies bounds the accepted_policies list of login and
+// registration requests.
+const maxAcceptedPolicies = 10
+
+// PolicyDocumentResponseDTO is the JSON representation of a policy document
+// version. Drafts have no published_at.
+type PolicyDocumentResponseDTO struct {
+	PolicyDocumentID  string     `json:"policy_document_id"`
+	Type              string     `json:"type"`
+	Version           string     `json:"version"`
+	Title             string     `json:"title"`
+	URL               string     `json:"url"`
+	RequireAcceptance bool       `json:"require_acceptance"`
+	PublishedAt       *time.Time `json:"published_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// PolicyAcceptanceResponseDTO records a user's acceptance of a policy
+// document version.
+type PolicyAcceptanceResponseDTO struct {
+	PolicyDocument PolicyDocumentResponseDTO `json:"policy_document"`
+	UserID         string                    `json:"user_id"`
+	Username       string                    `json:"username"`
+	Email          string                    `json:"email,omitempty"`
+	IPAddress      *string                   `json:"ip_address,omitempty"`
+	UserAgent      *string                   `json:"user_agent,omitempty"`
+	AcceptedAt     time.Time                 `json:"accepted_at"`
+}
+
+// PolicyDocumentRequestDTO is the request body for creating or replacing a
+// draft policy document.
+type PolicyDocumentRequestDTO struct {
+	Type              string `json:"type"`
+	Version           string `json:"version"`
+	Title             string `json:"title"`
+	URL               string `json:"url"`
+	RequireAcceptance *bool  `json:"require_acceptance,omitempty"`
+}
+
+// Validate validates the policy document request.
+func (r PolicyDocumentRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Type,
+			validation.Required.Error("Type is required"),
+			validation.In(model.PolicyDocumentTypeTermsOfService, model.PolicyDocumentTypePrivacyPolicy).
+				Error("Type must be 'terms_of_service' or 'privacy_policy'"),
+		),
+		validation.Field(&r.Version,
+			validation.Required.Error("Version is required"),
+			validation.Length(1, 50).Error("Version must be between 1 and 50 characters"),
+		),
+		validation.Field(&r.Title,
+			validation.Required.Error("Title is required"),
+			validation.Length(1, 255).Error("Title must be between 1 and 255 characters"),
+		),
+		validation.Field(&r.URL,
+			validation.Required.Error("URL is required"),
+			validation.Length(1, 2048).Error("URL must not exceed 2048 characters"),
+			is.URL.Error("URL must be a valid URL"),
+		),
+	)
+}
+
+// PolicyDocumentFilterDTO holds filter parameters for listing policy
+// documents.
+type PolicyDocumentFilterDTO struct {
+	Type      []string `json:"type"`
+	Published *bool    `json:"published"`
+	PaginationRequestDTO
+}
+
+// Validate validates the policy document filter.
+func (f PolicyDocumentFilterDTO) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.Type,
+			validation.Each(validation.In(model.PolicyDocumentTypeTermsOfService, model.PolicyDocumentTypePrivacyPolicy).
+				Error("Type must be 'terms_of_service' or 'privacy_policy'")),
+		),
+		validation.Field(&f.PaginationRequestDTO),
+	)
+}
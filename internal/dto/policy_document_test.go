@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/maintainerd/auth/internal/model"
+)
+
+func validPolicyDocument() PolicyDocumentRequestDTO {
+	return PolicyDocumentRequestDTO{
+		Type:    model.PolicyDocumentTypeTermsOfService,
+		Version: "2026-10",
+		Title:   "Terms of Service",
+		URL:     "https://example.com/terms",
+	}
+}
+
+func TestPolicyDocumentRequestDTO_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, validPolicyDocument().Validate())
+	})
+	t.Run("invalid type", func(t *testing.T) {
+		r := validPolicyDocument()
+		r.Type = "cookie_policy"
+		assert.Error(t, r.Validate())
+	})
+	t.Run("missing version", func(t *testing.T) {
+		r := validPolicyDocument()
+		r.Version = ""
+		assert.Error(t, r.Validate())
+	})
+	t.Run("version too long", func(t *testing.T) {
+		r := validPolicyDocument()
+		r.Version = strings.Repeat("v", 51)
+		assert.Error(t, r.Validate())
+	})
+	t.Run("invalid url", func(t *testing.T) {
+		r := validPolicyDocument()
+		r.URL = "not a url"
+		assert.Error(t, r.Validate())
+	})
+}
+
+func TestPolicyDocumentFilterDTO_Validate(t *testing.T) {
+	page := PaginationRequestDTO{Page: 1, Limit: 10}
+	assert.NoError(t, PolicyDocumentFilterDTO{Type: []string{model.PolicyDocumentTypePrivacyPolicy}, PaginationRequestDTO: page}.Validate())
+	assert.Error(t, PolicyDocumentFilterDTO{Type: []string{"eula"}, PaginationRequestDTO: page}.Validate())
+}
+
+func TestAcceptedPolicies_Validate(t *testing.T) {
+	tooMany := make([]uuid.UUID, maxAcceptedPolicies+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.New()
+	}
+
+	login := LoginRequestDTO{Username: "jane", Password: "secret", AcceptedPolicies: []uuid.UUID{uuid.New()}}
+	assert.NoError(t, login.Validate())
+	login.AcceptedPolicies = tooMany
+	assert.Error(t, login.Validate())
+}
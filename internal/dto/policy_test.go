@@ -59,6 +59,23 @@ func TestPolicyStatement_Validate(t *testing.T) {
 		s := PolicyStatement{Effect: model.PolicyEffectAllow, Action: []string{"user:*"}}
 		require.Error(t, s.Validate())
 	})
+
+	t.Run("valid condition", func(t *testing.T) {
+		s := PolicyStatement{Effect: model.PolicyEffectDeny, Action: []string{"user:delete"}, Resource: []string{"auth:*"}, Condition: "resource.params.user_uuid == subject.id"}
+		assert.NoError(t, s.Validate())
+	})
+
+	t.Run("invalid condition", func(t *testing.T) {
+		s := PolicyStatement{Effect: model.PolicyEffectDeny, Action: []string{"user:delete"}, Resource: []string{"auth:*"}, Condition: "subject.id =="}
+		err := s.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "condition is invalid")
+	})
+
+	t.Run("invalid condition in document", func(t *testing.T) {
+		err := validatePolicyDocumentStructure(datatypes.JSON(`{"version":"v1","statement":[{"effect":"deny","action":["user:*"],"resource":["auth:*"],"condition":"1 + 1"}]}`))
+		require.Error(t, err)
+	})
 }
 
 func TestPolicyCreateRequestDto_Validate(t *testing.T) {
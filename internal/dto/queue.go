@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+// WorkerHealthResponseDTO is the status of a background worker since this
+// instance started. Alerting is set once the worker has failed
+// consecutive_failures times in a row past the code-level threshold.
+type WorkerHealthResponseDTO struct {
+	Worker              string     `json:"worker"`
+	Runs                int64      `json:"runs"`
+	Failures            int64      `json:"failures"`
+	ConsecutiveFailures int64      `json:"consecutive_failures"`
+	ItemsProcessed      int64      `json:"items_processed"`
+	LastRunAt           *time.Time `json:"last_run_at,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	Alerting            bool       `json:"alerting"`
+}
+
+// QueueThresholdResponseDTO is the alert threshold of a queue. A zero field
+// is not checked.
+type QueueThresholdResponseDTO struct {
+	MaxDepth            int64 `json:"max_depth"`
+	MaxOldestAgeSeconds int64 `json:"max_oldest_age_seconds"`
+}
+
+// QueueStatusResponseDTO is the backlog of a queue when it was sampled.
+type QueueStatusResponseDTO struct {
+	Queue            string                    `json:"queue"`
+	Depth            int64                     `json:"depth"`
+	OldestAt         *time.Time                `json:"oldest_at,omitempty"`
+	OldestAgeSeconds int64                     `json:"oldest_age_seconds"`
+	Threshold        QueueThresholdResponseDTO `json:"threshold"`
+	Alerting         bool                      `json:"alerting"`
+}
+
+// QueueHealthResponseDTO reports every background worker and queue. Alerting
+// is set when any of them is.
+type QueueHealthResponseDTO struct {
+	Alerting bool                      `json:"alerting"`
+	Workers  []WorkerHealthResponseDTO `json:"workers"`
+	Queues   []QueueStatusResponseDTO  `json:"queues"`
+}
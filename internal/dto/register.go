@@ -4,7 +4,9 @@ import (
 	"errors"
 	"net/url"
 
+	"github.com/google/uuid"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/maintainerd/auth/internal/valid"
 	"github.com/maintainerd/auth/internal/signedurl"
 	"github.com/maintainerd/auth/internal/security"
@@ -17,6 +19,18 @@ type RegisterRequestDTO struct {
 	Email    *string `json:"email,omitempty"`
 	Phone    *string `json:"phone,omitempty"`
 	Password string  `json:"password"`
+	// CaptchaToken is the solved CAPTCHA token, required when the client's
+	// signup flow enables a CAPTCHA.
+	CaptchaToken string `json:"captcha_token,omitempty"`
+	// AcceptedPolicies lists the policy documents the user accepts with
+	// the sign-up, by UUID.
+	AcceptedPolicies []uuid.UUID `json:"accepted_policies,omitempty"`
+	// Birthdate is stored on the user's profile and required when the
+	// signup flow has an age gate.
+	Birthdate *string `json:"birthdate,omitempty"` // YYYY-MM-DD format
+	// GuardianEmail receives the consent request for users under the signup
+	// flow's minimum age.
+	GuardianEmail *string `json:"guardian_email,omitempty"`
 }
 
 func (r *RegisterRequestDTO) Validate() error {
@@ -30,6 +44,9 @@ func (r *RegisterRequestDTO) Validate() error {
 	if r.Phone != nil {
 		*r.Phone = security.SanitizeInput(*r.Phone)
 	}
+	if r.GuardianEmail != nil {
+		*r.GuardianEmail = security.SanitizeInput(*r.GuardianEmail)
+	}
 
 	return validation.ValidateStruct(r,
 		validation.Field(&r.Username,
@@ -68,6 +85,28 @@ func (r *RegisterRequestDTO) Validate() error {
 			validation.Required.Error("Password is required"),
 			validation.Length(8, 128).Error("Password must be between 8 and 128 characters"),
 		),
+		validation.Field(&r.CaptchaToken,
+			validation.Length(0, 4096).Error("Captcha token must not exceed 4096 characters"),
+		),
+		validation.Field(&r.AcceptedPolicies,
+			validation.Length(0, maxAcceptedPolicies).Error("Too many accepted policies"),
+		),
+		validation.Field(&r.Birthdate,
+			validation.NilOrNotEmpty,
+			validation.By(validateDateFormat),
+		),
+		validation.Field(&r.GuardianEmail,
+			validation.When(r.GuardianEmail != nil,
+				validation.By(func(value interface{}) error {
+					if email := value.(*string); email != nil && *email != "" {
+						if !valid.IsValidEmail(*email) {
+							return errors.New("guardian email must be a valid email address")
+						}
+					}
+					return nil
+				}),
+			),
+		),
 	)
 }
 
@@ -90,12 +129,16 @@ func (r *RegisterRequestDTO) ValidateForRegistration() error {
 type RegisterQueryDTO struct {
 	ClientID   string `json:"client_id"`
 	ProviderID string `json:"provider_id"`
+	// SignupFlow optionally selects one of the client's signup flows by
+	// identifier. Empty uses the client's active flow, if any.
+	SignupFlow string `json:"signup_flow,omitempty"`
 }
 
 func (q *RegisterQueryDTO) Validate() error {
 	// Sanitize inputs first
 	q.ClientID = security.SanitizeInput(q.ClientID)
 	q.ProviderID = security.SanitizeInput(q.ProviderID)
+	q.SignupFlow = security.SanitizeInput(q.SignupFlow)
 
 	return validation.ValidateStruct(q,
 		validation.Field(&q.ClientID,
@@ -106,6 +149,9 @@ func (q *RegisterQueryDTO) Validate() error {
 			validation.Required.Error("Provider ID is required"),
 			validation.Length(1, 255).Error("Provider ID must not exceed 255 characters"),
 		),
+		validation.Field(&q.SignupFlow,
+			validation.Length(0, 255).Error("Signup flow must not exceed 255 characters"),
+		),
 	)
 }
 
@@ -159,12 +205,47 @@ func (q *RegisterInviteQueryDTO) ValidateSignedURL(values url.Values) error {
 	return nil
 }
 
-// RegisterResponseDTO is the response structure for registration operations
+// RegisterResponseDTO is the response structure for registration operations.
+// When the signup flow requires email verification no tokens are issued and
+// VerificationRequired is set instead.
 type RegisterResponseDTO struct {
-	AccessToken  string `json:"access_token"`
-	IDToken      string `json:"id_token"`
-	RefreshToken string `json:"refresh_token,omitempty"`
-	ExpiresIn    int64  `json:"expires_in"`
-	TokenType    string `json:"token_type"`
-	IssuedAt     int64  `json:"issued_at"`
+	AccessToken          string `json:"access_token,omitempty"`
+	IDToken              string `json:"id_token,omitempty"`
+	RefreshToken         string `json:"refresh_token,omitempty"`
+	ExpiresIn            int64  `json:"expires_in,omitempty"`
+	TokenType            string `json:"token_type,omitempty"`
+	IssuedAt             int64  `json:"issued_at,omitempty"`
+	VerificationRequired bool   `json:"verification_required,omitempty"`
+	// ParentalConsentRequired marks an under-age account that stays inactive
+	// until the guardian approves it.
+	ParentalConsentRequired bool `json:"parental_consent_required,omitempty"`
+}
+
+// VerifyEmailRequestDTO confirms the email verification code sent after a
+// registration whose signup flow requires email verification.
+type VerifyEmailRequestDTO struct {
+	Email string `json:"email"`
+	Code  string `json:"code"`
+}
+
+func (r *VerifyEmailRequestDTO) Validate() error {
+	r.Email = security.SanitizeInput(r.Email)
+	r.Code = security.SanitizeInput(r.Code)
+
+	return validation.ValidateStruct(r,
+		validation.Field(&r.Email,
+			validation.Required.Error("Email is required"),
+			validation.By(func(value interface{}) error {
+				if !valid.IsValidEmail(value.(string)) {
+					return errors.New("email must be a valid email address")
+				}
+				return nil
+			}),
+		),
+		validation.Field(&r.Code,
+			validation.Required.Error("Code is required"),
+			validation.Length(6, 6).Error("Code must be 6 digits"),
+			is.Digit.Error("Code must be 6 digits"),
+		),
+	)
 }
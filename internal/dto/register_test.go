@@ -2,6 +2,7 @@ package dto
 
 import (
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,6 +28,11 @@ func TestRegisterRequestDto_Validate(t *testing.T) {
 			dto:     RegisterRequestDTO{Username: "johndoe", Fullname: "John Doe", Password: "SecurePass1!", Email: strPtr("john@example.com")},
 			wantErr: false,
 		},
+		{
+			name:    "captcha token too long",
+			dto:     RegisterRequestDTO{Username: "johndoe", Fullname: "John Doe", Password: "SecurePass1!", CaptchaToken: strings.Repeat("t", 4097)},
+			wantErr: true,
+		},
 		{
 			name:    "missing username",
 			dto:     RegisterRequestDTO{Fullname: "John Doe", Password: "SecurePass1!"},
@@ -76,6 +82,34 @@ func TestRegisterQueryDto_Validate(t *testing.T) {
 		{name: "valid", dto: RegisterQueryDTO{ClientID: "c1", ProviderID: "p1"}, wantErr: false},
 		{name: "missing client_id", dto: RegisterQueryDTO{ProviderID: "p1"}, wantErr: true},
 		{name: "missing provider_id", dto: RegisterQueryDTO{ClientID: "c1"}, wantErr: true},
+		{name: "valid with signup flow", dto: RegisterQueryDTO{ClientID: "c1", ProviderID: "p1", SignupFlow: "b2b"}, wantErr: false},
+		{name: "signup flow too long", dto: RegisterQueryDTO{ClientID: "c1", ProviderID: "p1", SignupFlow: strings.Repeat("a", 256)}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := tc.dto
+			err := d.Validate()
+			if tc.wantErr {
+				require.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVerifyEmailRequestDto_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		dto     VerifyEmailRequestDTO
+		wantErr bool
+	}{
+		{name: "valid", dto: VerifyEmailRequestDTO{Email: "a@b.com", Code: "123456"}, wantErr: false},
+		{name: "missing email", dto: VerifyEmailRequestDTO{Code: "123456"}, wantErr: true},
+		{name: "invalid email", dto: VerifyEmailRequestDTO{Email: "nope", Code: "123456"}, wantErr: true},
+		{name: "missing code", dto: VerifyEmailRequestDTO{Email: "a@b.com"}, wantErr: true},
+		{name: "short code", dto: VerifyEmailRequestDTO{Email: "a@b.com", Code: "123"}, wantErr: true},
+		{name: "non-digit code", dto: VerifyEmailRequestDTO{Email: "a@b.com", Code: "12a456"}, wantErr: true},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -135,6 +169,31 @@ func TestRegisterRequestDto_Validate_WithPhone(t *testing.T) {
 	})
 }
 
+func TestRegisterRequestDto_Validate_AgeGate(t *testing.T) {
+	base := func() RegisterRequestDTO {
+		return RegisterRequestDTO{Username: "johndoe", Fullname: "John Doe", Password: "SecurePass1!"}
+	}
+
+	t.Run("valid birthdate and guardian", func(t *testing.T) {
+		d := base()
+		d.Birthdate = strPtr("2015-04-01")
+		d.GuardianEmail = strPtr("parent@example.com")
+		assert.NoError(t, d.Validate())
+	})
+
+	t.Run("invalid birthdate", func(t *testing.T) {
+		d := base()
+		d.Birthdate = strPtr("01/04/2015")
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("invalid guardian email", func(t *testing.T) {
+		d := base()
+		d.GuardianEmail = strPtr("not-an-email")
+		require.Error(t, d.Validate())
+	})
+}
+
 func TestRegisterRequestDto_ValidateForRegistration(t *testing.T) {
 	t.Run("valid passes strength check", func(t *testing.T) {
 		d := RegisterRequestDTO{Username: "johndoe", Fullname: "John Doe", Password: "SecurePass1!"}
@@ -12,15 +12,17 @@ import (
 
 // Role output structure
 type RoleResponseDTO struct {
-	RoleUUID    uuid.UUID                `json:"role_id"`
-	Name        string                   `json:"name"`
-	Description string                   `json:"description"`
-	Permissions *[]PermissionResponseDTO `json:"permissions,omitempty"`
-	IsDefault   bool                     `json:"is_default"`
-	IsSystem    bool                     `json:"is_system"`
-	Status      string                   `json:"status"`
-	CreatedAt   time.Time                `json:"created_at"`
-	UpdatedAt   time.Time                `json:"updated_at"`
+	RoleUUID      uuid.UUID                `json:"role_id"`
+	Name          string                   `json:"name"`
+	Description   string                   `json:"description"`
+	Permissions   *[]PermissionResponseDTO `json:"permissions,omitempty"`
+	IsDefault     bool                     `json:"is_default"`
+	IsSystem      bool                     `json:"is_system"`
+	IsInheritable bool                     `json:"is_inheritable"`
+	IsInherited   bool                     `json:"is_inherited,omitempty"`
+	Status        string                   `json:"status"`
+	CreatedAt     time.Time                `json:"created_at"`
+	UpdatedAt     time.Time                `json:"updated_at"`
 }
 
 // Create or update role request dto
@@ -68,6 +70,8 @@ type RoleFilterDTO struct {
 	IsDefault   *bool   `json:"is_default"`
 	IsSystem    *bool   `json:"is_system"`
 	Status      *string `json:"status"`
+	// IncludeInherited adds the inheritable roles of the tenant's ancestors.
+	IncludeInherited bool `json:"include_inherited,omitempty"`
 
 	// Pagination and sorting
 	PaginationRequestDTO
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+)
+
+// ScopedRoleResponseDTO describes a role assigned to a user for a single
+// client or group.
+type ScopedRoleResponseDTO struct {
+	ScopedRoleUUID uuid.UUID       `json:"scoped_role_id"`
+	Role           RoleResponseDTO `json:"role"`
+	ResourceType   string          `json:"resource_type"`
+	ResourceUUID   uuid.UUID       `json:"resource_id"`
+	ResourceName   string          `json:"resource_name"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// ScopedRoleAssignRequestDTO is the body of a request to assign a role to a
+// user for one client or group.
+type ScopedRoleAssignRequestDTO struct {
+	RoleUUID     string `json:"role_id"`
+	ResourceType string `json:"resource_type"`
+	ResourceUUID string `json:"resource_id"`
+}
+
+func (r ScopedRoleAssignRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.RoleUUID,
+			validation.Required.Error("Role ID is required"),
+			is.UUID.Error("Role ID must be a valid UUID"),
+		),
+		validation.Field(&r.ResourceType,
+			validation.Required.Error("Resource type is required"),
+			validation.In(model.ScopedRoleResourceClient, model.ScopedRoleResourceGroup).Error("Resource type must be client or group"),
+		),
+		validation.Field(&r.ResourceUUID,
+			validation.Required.Error("Resource ID is required"),
+			is.UUID.Error("Resource ID must be a valid UUID"),
+		),
+	)
+}
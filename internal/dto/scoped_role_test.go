@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopedRoleAssignRequestDTO_Validate(t *testing.T) {
+	valid := ScopedRoleAssignRequestDTO{
+		RoleUUID:     uuid.NewString(),
+		ResourceType: "client",
+		ResourceUUID: uuid.NewString(),
+	}
+
+	t.Run("valid client scope", func(t *testing.T) {
+		assert.NoError(t, valid.Validate())
+	})
+
+	t.Run("valid group scope", func(t *testing.T) {
+		r := valid
+		r.ResourceType = "group"
+		assert.NoError(t, r.Validate())
+	})
+
+	t.Run("missing role", func(t *testing.T) {
+		r := valid
+		r.RoleUUID = ""
+		assert.Error(t, r.Validate())
+	})
+
+	t.Run("invalid role UUID", func(t *testing.T) {
+		r := valid
+		r.RoleUUID = "not-a-uuid"
+		assert.Error(t, r.Validate())
+	})
+
+	t.Run("unsupported resource type", func(t *testing.T) {
+		r := valid
+		r.ResourceType = "tenant"
+		assert.Error(t, r.Validate())
+	})
+
+	t.Run("missing resource", func(t *testing.T) {
+		r := valid
+		r.ResourceUUID = ""
+		assert.Error(t, r.Validate())
+	})
+}
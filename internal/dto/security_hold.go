@@ -0,0 +1,17 @@
+package dto
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// SecurityHoldRequestDTO is the request body for putting a user on security
+// hold. The reason is recorded in the audit log and not shown to the user.
+type SecurityHoldRequestDTO struct {
+	Reason string `json:"reason"`
+}
+
+func (dto SecurityHoldRequestDTO) Validate() error {
+	return validation.ValidateStruct(&dto,
+		validation.Field(&dto.Reason, validation.Required.Error("Reason is required"), validation.Length(3, 500).Error("Reason must be between 3 and 500 characters")),
+	)
+}
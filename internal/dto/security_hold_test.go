@@ -0,0 +1,15 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityHoldRequestDTO_Validate(t *testing.T) {
+	assert.NoError(t, SecurityHoldRequestDTO{Reason: "credentials found in a paste"}.Validate())
+	assert.Error(t, SecurityHoldRequestDTO{}.Validate())
+	assert.Error(t, SecurityHoldRequestDTO{Reason: "ok"}.Validate())
+	assert.Error(t, SecurityHoldRequestDTO{Reason: strings.Repeat("x", 501)}.Validate())
+}
@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+// SigningKeyResponseDTO describes a JWT signing key. Key material is never
+// returned; the public keys are published in the JWKS.
+type SigningKeyResponseDTO struct {
+	SigningKeyID string     `json:"signing_key_id"`
+	KeyID        string     `json:"kid"`
+	Algorithm    string     `json:"algorithm"`
+	Status       string     `json:"status"`
+	ActivatedAt  time.Time  `json:"activated_at"`
+	RotatedAt    *time.Time `json:"rotated_at"`
+	RetiredAt    *time.Time `json:"retired_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/google/uuid"
 
 	"github.com/maintainerd/auth/internal/model"
@@ -22,18 +23,22 @@ type TenantResponseDTO struct {
 	Status      string    `json:"status"`
 	IsPublic    bool      `json:"is_public"`
 	IsSystem    bool      `json:"is_system"`
-	Metadata    any       `json:"metadata,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	// ParentTenantUUID is left out for top-level tenants.
+	ParentTenantUUID *uuid.UUID `json:"parent_tenant_id,omitempty"`
+	Metadata         any        `json:"metadata,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
-// Create Tenant request DTO
+// Create Tenant request DTO. ParentTenantUUID places the tenant under an
+// existing tenant.
 type TenantCreateRequestDTO struct {
-	Name        string `json:"name"`
-	DisplayName string `json:"display_name"`
-	Description string `json:"description"`
-	Status      string `json:"status"`
-	IsPublic    bool   `json:"is_public"`
+	Name             string  `json:"name"`
+	DisplayName      string  `json:"display_name"`
+	Description      string  `json:"description"`
+	Status           string  `json:"status"`
+	IsPublic         bool    `json:"is_public"`
+	ParentTenantUUID *string `json:"parent_tenant_id"`
 }
 
 // Validation
@@ -55,6 +60,11 @@ func (r TenantCreateRequestDTO) Validate() error {
 		validation.Field(&r.IsPublic,
 			validation.In(true, false).Error("Is public is required"),
 		),
+		validation.Field(&r.ParentTenantUUID,
+			validation.When(r.ParentTenantUUID != nil,
+				is.UUID.Error("Parent tenant ID must be a valid UUID"),
+			),
+		),
 	)
 }
 
@@ -89,6 +99,96 @@ func (r TenantUpdateRequestDTO) Validate() error {
 	)
 }
 
+// Set Tenant parent request DTO. A null ParentTenantUUID moves the tenant to
+// the top level.
+type TenantSetParentRequestDTO struct {
+	ParentTenantUUID *string `json:"parent_tenant_id"`
+}
+
+// Validation
+func (r TenantSetParentRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ParentTenantUUID,
+			validation.When(r.ParentTenantUUID != nil,
+				is.UUID.Error("Parent tenant ID must be a valid UUID"),
+			),
+		),
+	)
+}
+
+// Self-service tenant request DTO. The tenant is always created active and
+// private.
+type TenantSelfServiceRequestDTO struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Description string `json:"description"`
+}
+
+// Validation
+func (r TenantSelfServiceRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Name,
+			validation.Required.Error("Name is required"),
+			validation.Length(3, 50).Error("Name must be between 3 and 50 characters"),
+			validation.Match(tenantNamePattern).Error("Name must contain only lowercase letters, numbers, and hyphens"),
+		),
+		validation.Field(&r.DisplayName,
+			validation.Length(0, 100).Error("Display name must not exceed 100 characters"),
+		),
+		validation.Field(&r.Description,
+			validation.Length(0, 200).Error("Description must not exceed 200 characters"),
+		),
+	)
+}
+
+// Tenant deprovision request DTO
+type TenantDeprovisionRequestDTO struct {
+	Mode string `json:"mode"`
+}
+
+// Validation
+func (r TenantDeprovisionRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Mode,
+			validation.Required.Error("Mode is required"),
+			validation.In(model.TenantDeprovisionModeArchive, model.TenantDeprovisionModeDelete).Error("Mode must be archive or delete"),
+		),
+	)
+}
+
+// Tenant deprovision confirmation DTO
+type TenantDeprovisionConfirmRequestDTO struct {
+	ConfirmationToken string `json:"confirmation_token"`
+}
+
+// Validation
+func (r TenantDeprovisionConfirmRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ConfirmationToken,
+			validation.Required.Error("Confirmation token is required"),
+		),
+	)
+}
+
+// Tenant deprovision request output structure. Impact is the dry run of a
+// delete and is left out when archiving.
+type TenantDeprovisionRequestResponseDTO struct {
+	DeprovisionUUID   uuid.UUID                `json:"deprovision_id"`
+	TenantUUID        uuid.UUID                `json:"tenant_id"`
+	Mode              string                   `json:"mode"`
+	ConfirmationToken string                   `json:"confirmation_token"`
+	ExpiresAt         time.Time                `json:"expires_at"`
+	Impact            *DeleteImpactResponseDTO `json:"impact,omitempty"`
+}
+
+// Tenant deprovision output structure
+type TenantDeprovisionResponseDTO struct {
+	DeprovisionUUID uuid.UUID `json:"deprovision_id"`
+	TenantUUID      uuid.UUID `json:"tenant_id"`
+	Mode            string    `json:"mode"`
+	CompletedAt     time.Time `json:"completed_at"`
+}
+
 // API listing / filter DTO
 type TenantFilterDTO struct {
 	Name        *string  `json:"name"`
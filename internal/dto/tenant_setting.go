@@ -18,3 +18,44 @@ func (r TenantSettingUpdateConfigRequestDTO) Validate() error {
 	}
 	return nil
 }
+
+// TenantSettingUserMetadataSchemaRequestDTO is the request body for replacing
+// the tenant's user metadata JSON Schema. An empty object clears the schema.
+type TenantSettingUserMetadataSchemaRequestDTO map[string]any
+
+// Validate ensures a JSON object was supplied.
+func (r TenantSettingUserMetadataSchemaRequestDTO) Validate() error {
+	if r == nil {
+		return validation.NewError("validation_error", "Schema must be a JSON object")
+	}
+	return nil
+}
+
+// TenantSettingUserSettingDefaultsRequestDTO is the request body for
+// replacing the preferences applied to users who have not chosen their own.
+// Empty fields fall back to the built-in defaults.
+type TenantSettingUserSettingDefaultsRequestDTO struct {
+	Theme             string `json:"theme"`
+	PreferredLanguage string `json:"preferred_language"`
+	Locale            string `json:"locale"`
+	Timezone          string `json:"timezone"`
+	DateFormat        string `json:"date_format"`
+}
+
+// Validate validates the defaults with the rules of user settings.
+func (r TenantSettingUserSettingDefaultsRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Theme, validTheme),
+		validation.Field(&r.PreferredLanguage,
+			validation.RuneLength(2, 10).Error("Preferred language must be 2-10 characters"),
+		),
+		validation.Field(&r.Locale,
+			validation.RuneLength(2, 10).Error("Locale must be 2-10 characters"),
+		),
+		validation.Field(&r.Timezone,
+			validation.RuneLength(0, 50).Error("Timezone must be at most 50 characters"),
+			validTimezone,
+		),
+		validation.Field(&r.DateFormat, validDateFormat),
+	)
+}
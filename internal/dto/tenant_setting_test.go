@@ -3,6 +3,7 @@ package dto
 import (
 	"testing"
 
+	"github.com/maintainerd/auth/internal/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -23,3 +24,49 @@ func TestTenantSettingUpdateConfigRequestDTO_Validate(t *testing.T) {
 		require.Error(t, d.Validate())
 	})
 }
+
+func TestTenantSettingUserMetadataSchemaRequestDTO_Validate(t *testing.T) {
+	t.Run("schema accepted", func(t *testing.T) {
+		d := TenantSettingUserMetadataSchemaRequestDTO{"type": "object"}
+		assert.NoError(t, d.Validate())
+	})
+
+	t.Run("empty map clears schema", func(t *testing.T) {
+		d := TenantSettingUserMetadataSchemaRequestDTO{}
+		assert.NoError(t, d.Validate())
+	})
+
+	t.Run("nil map rejected", func(t *testing.T) {
+		var d TenantSettingUserMetadataSchemaRequestDTO
+		require.Error(t, d.Validate())
+	})
+}
+
+func TestTenantSettingUserSettingDefaultsRequestDTO_Validate(t *testing.T) {
+	t.Run("empty clears defaults", func(t *testing.T) {
+		assert.NoError(t, TenantSettingUserSettingDefaultsRequestDTO{}.Validate())
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		d := TenantSettingUserSettingDefaultsRequestDTO{
+			Theme:             model.ThemeLight,
+			PreferredLanguage: "de",
+			Locale:            "de_DE",
+			Timezone:          "Europe/Berlin",
+			DateFormat:        model.DateFormatDMYDot,
+		}
+		assert.NoError(t, d.Validate())
+	})
+
+	t.Run("invalid fields", func(t *testing.T) {
+		for name, d := range map[string]TenantSettingUserSettingDefaultsRequestDTO{
+			"theme":       {Theme: "neon"},
+			"language":    {PreferredLanguage: "d"},
+			"locale":      {Locale: "de_DE_berlin"},
+			"timezone":    {Timezone: "Mars/Olympus_Mons"},
+			"date format": {DateFormat: "YY/M/D"},
+		} {
+			assert.Error(t, d.Validate(), name)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -70,6 +71,20 @@ func TestTenantCreateRequestDto_Validate(t *testing.T) {
 		d.Status = model.StatusSuspended
 		assert.NoError(t, d.Validate())
 	})
+
+	t.Run("valid parent tenant", func(t *testing.T) {
+		d := validTenantCreate()
+		parent := uuid.NewString()
+		d.ParentTenantUUID = &parent
+		assert.NoError(t, d.Validate())
+	})
+
+	t.Run("invalid parent tenant", func(t *testing.T) {
+		d := validTenantCreate()
+		parent := "not-a-uuid"
+		d.ParentTenantUUID = &parent
+		require.Error(t, d.Validate())
+	})
 }
 
 func TestTenantUpdateRequestDto_Validate(t *testing.T) {
@@ -84,6 +99,41 @@ func TestTenantUpdateRequestDto_Validate(t *testing.T) {
 	require.Error(t, d.Validate())
 }
 
+func TestTenantSetParentRequestDto_Validate(t *testing.T) {
+	parent := uuid.NewString()
+	invalid := "not-a-uuid"
+	assert.NoError(t, TenantSetParentRequestDTO{ParentTenantUUID: &parent}.Validate())
+	assert.NoError(t, TenantSetParentRequestDTO{}.Validate(), "null moves the tenant to the top level")
+	require.Error(t, TenantSetParentRequestDTO{ParentTenantUUID: &invalid}.Validate())
+}
+
+func TestTenantSelfServiceRequestDto_Validate(t *testing.T) {
+	t.Run("valid without optional fields", func(t *testing.T) {
+		assert.NoError(t, TenantSelfServiceRequestDTO{Name: "acme-corp"}.Validate())
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		require.Error(t, TenantSelfServiceRequestDTO{Name: "Acme Corp"}.Validate())
+	})
+
+	t.Run("description too long", func(t *testing.T) {
+		d := TenantSelfServiceRequestDTO{Name: "acme-corp", Description: strings.Repeat("a", 201)}
+		require.Error(t, d.Validate())
+	})
+}
+
+func TestTenantDeprovisionRequestDto_Validate(t *testing.T) {
+	assert.NoError(t, TenantDeprovisionRequestDTO{Mode: "archive"}.Validate())
+	assert.NoError(t, TenantDeprovisionRequestDTO{Mode: "delete"}.Validate())
+	require.Error(t, TenantDeprovisionRequestDTO{}.Validate())
+	require.Error(t, TenantDeprovisionRequestDTO{Mode: "purge"}.Validate())
+}
+
+func TestTenantDeprovisionConfirmRequestDto_Validate(t *testing.T) {
+	assert.NoError(t, TenantDeprovisionConfirmRequestDTO{ConfirmationToken: "token"}.Validate())
+	require.Error(t, TenantDeprovisionConfirmRequestDTO{}.Validate())
+}
+
 func TestTenantFilterDto_Validate(t *testing.T) {
 	t.Run("valid with pagination", func(t *testing.T) {
 		f := TenantFilterDTO{PaginationRequestDTO: validPagination()}
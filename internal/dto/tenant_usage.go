@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// TenantUsageMaxDays bounds the number of days a usage report covers.
+const TenantUsageMaxDays = 366
+
+// TenantUsageFilterDTO holds the query parameters of a tenant usage report.
+// Both days are inclusive and in YYYY-MM-DD format (UTC).
+type TenantUsageFilterDTO struct {
+	From *string `json:"from"`
+	To   *string `json:"to"`
+}
+
+// Validate validates the filter parameters.
+func (f TenantUsageFilterDTO) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.From,
+			validation.NilOrNotEmpty,
+			validation.Date(dateLayout).Error("From must be in YYYY-MM-DD format"),
+		),
+		validation.Field(&f.To,
+			validation.NilOrNotEmpty,
+			validation.Date(dateLayout).Error("To must be in YYYY-MM-DD format"),
+			validation.By(func(any) error {
+				if f.From == nil || f.To == nil {
+					return nil
+				}
+				from, fromErr := time.Parse(dateLayout, *f.From)
+				to, toErr := time.Parse(dateLayout, *f.To)
+				if fromErr != nil || toErr != nil {
+					return nil
+				}
+				if to.Before(from) {
+					return validation.NewError("validation_date_range", "To must not be before From")
+				}
+				if to.Sub(from) >= TenantUsageMaxDays*24*time.Hour {
+					return validation.NewError("validation_date_range", "The report cannot cover more than 366 days")
+				}
+				return nil
+			}),
+		),
+	)
+}
+
+// TenantUsageQuotasResponseDTO are the monthly quotas of a tenant. Null is
+// unlimited.
+type TenantUsageQuotasResponseDTO struct {
+	MonthlyRequests       *int64 `json:"monthly_request_quota"`
+	MonthlyTokens         *int64 `json:"monthly_token_quota"`
+	APIKeyMonthlyRequests *int64 `json:"api_key_monthly_request_quota"`
+}
+
+// TenantUsagePeriodResponseDTO is the live usage of a tenant in the current
+// calendar month, as counted against its quotas.
+type TenantUsagePeriodResponseDTO struct {
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	Requests     int64     `json:"requests"`
+	TokensIssued int64     `json:"tokens_issued"`
+}
+
+// TenantUsageDayResponseDTO is the usage of a tenant on a day.
+type TenantUsageDayResponseDTO struct {
+	Date         Date  `json:"date"`
+	Requests     int64 `json:"requests"`
+	TokensIssued int64 `json:"tokens_issued"`
+}
+
+// TenantUsageAPIKeyResponseDTO is the usage of a tenant made with one API
+// key. APIKeyUUID and Name are null once the key is deleted.
+type TenantUsageAPIKeyResponseDTO struct {
+	APIKeyUUID *string `json:"api_key_id"`
+	Name       *string `json:"name"`
+	Requests   int64   `json:"requests"`
+}
+
+// TenantUsageResponseDTO is the usage report of a tenant.
+type TenantUsageResponseDTO struct {
+	TenantID      string                         `json:"tenant_id"`
+	From          Date                           `json:"from"`
+	To            Date                           `json:"to"`
+	Requests      int64                          `json:"requests"`
+	TokensIssued  int64                          `json:"tokens_issued"`
+	Days          []TenantUsageDayResponseDTO    `json:"days"`
+	APIKeys       []TenantUsageAPIKeyResponseDTO `json:"api_keys"`
+	Quotas        TenantUsageQuotasResponseDTO   `json:"quotas"`
+	CurrentPeriod TenantUsagePeriodResponseDTO   `json:"current_period"`
+}
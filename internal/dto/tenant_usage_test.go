@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/maintainerd/auth/internal/ptr"
+)
+
+func TestTenantUsageFilterDTO_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  TenantUsageFilterDTO
+		wantErr bool
+	}{
+		{"empty", TenantUsageFilterDTO{}, false},
+		{"range", TenantUsageFilterDTO{From: ptr.Ptr("2026-01-01"), To: ptr.Ptr("2026-01-31")}, false},
+		{"single day", TenantUsageFilterDTO{From: ptr.Ptr("2026-01-01"), To: ptr.Ptr("2026-01-01")}, false},
+		{"from only", TenantUsageFilterDTO{From: ptr.Ptr("2026-01-01")}, false},
+		{"a full leap year", TenantUsageFilterDTO{From: ptr.Ptr("2028-01-01"), To: ptr.Ptr("2028-12-31")}, false},
+		{"bad from", TenantUsageFilterDTO{From: ptr.Ptr("01/01/2026")}, true},
+		{"bad to", TenantUsageFilterDTO{To: ptr.Ptr("2026-01-01T00:00:00Z")}, true},
+		{"to before from", TenantUsageFilterDTO{From: ptr.Ptr("2026-02-01"), To: ptr.Ptr("2026-01-31")}, true},
+		{"too long", TenantUsageFilterDTO{From: ptr.Ptr("2026-01-01"), To: ptr.Ptr("2027-01-02")}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
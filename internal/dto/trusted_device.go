@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// TrustedDeviceCreateRequestDTO is the body of a request to remember the
+// calling device. Name is an optional label for the device list.
+type TrustedDeviceCreateRequestDTO struct {
+	Name string `json:"name"`
+}
+
+func (r TrustedDeviceCreateRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Name,
+			validation.RuneLength(0, 100).Error("Name must be at most 100 characters"),
+		),
+	)
+}
+
+// TrustedDeviceResponseDTO describes a trusted device. Current is set for the
+// device making the request.
+type TrustedDeviceResponseDTO struct {
+	TrustedDeviceID string     `json:"trusted_device_id"`
+	Name            string     `json:"name"`
+	UserAgent       *string    `json:"user_agent"`
+	IPAddress       *string    `json:"ip_address"`
+	Current         bool       `json:"current"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	LastUsedAt      *time.Time `json:"last_used_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// TrustedDeviceCreateResponseDTO is returned once, when a device is trusted,
+// and is the only response that carries the device token.
+type TrustedDeviceCreateResponseDTO struct {
+	TrustedDeviceResponseDTO
+	Token string `json:"token"`
+}
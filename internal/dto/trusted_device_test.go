@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustedDeviceCreateRequestDTO_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, TrustedDeviceCreateRequestDTO{Name: "Work laptop"}.Validate())
+	})
+
+	t.Run("name is optional", func(t *testing.T) {
+		assert.NoError(t, TrustedDeviceCreateRequestDTO{}.Validate())
+	})
+
+	t.Run("name too long", func(t *testing.T) {
+		require.Error(t, TrustedDeviceCreateRequestDTO{Name: strings.Repeat("a", 101)}.Validate())
+	})
+}
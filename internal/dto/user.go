@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"slices"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -27,6 +28,12 @@ type UserResponseDTO struct {
 	Tenant             *TenantResponseDTO `json:"tenant,omitempty"`
 	CreatedAt          time.Time          `json:"created_at"`
 	UpdatedAt          time.Time          `json:"updated_at"`
+	DeletedAt          *time.Time         `json:"deleted_at,omitempty"`
+
+	// Identities and Roles are only set on list rows that asked for them
+	// with include.
+	Identities *[]UserIdentityResponseDTO `json:"identities,omitempty"`
+	Roles      *[]RoleResponseDTO         `json:"roles,omitempty"`
 }
 
 type UserIdentityResponseDTO struct {
@@ -112,9 +119,14 @@ type UserFilterDTO struct {
 	RoleUUID     *string  `json:"role_id,omitempty"`
 	UserPoolUUID *string  `json:"user_pool_id,omitempty"`
 	ClientUUID   *string  `json:"client_id,omitempty"`
+	Include      []string `json:"include,omitempty"`
+	// IncludeSubtenants also lists the users of the tenants below the
+	// caller's tenant.
+	IncludeSubtenants bool `json:"include_subtenants,omitempty"`
 
-	// Pagination and sorting
+	// Pagination and sorting. Cursor, when set, replaces page/limit.
 	PaginationRequestDTO
+	Cursor *CursorPaginationRequestDTO `json:"-"`
 }
 
 // Validate validates the user filter DTO.
@@ -122,7 +134,7 @@ func (f UserFilterDTO) Validate() error {
 	return validation.ValidateStruct(&f,
 		validation.Field(&f.Status,
 			validation.When(len(f.Status) > 0,
-				validation.Each(validation.In(model.StatusActive, model.StatusInactive).Error("Status must be 'active' or 'inactive'")),
+				validation.Each(validation.In(model.StatusActive, model.StatusInactive, model.StatusPending, model.StatusSuspended, model.StatusDisabled, model.StatusSecurityHold, model.StatusDeleted).Error("Status must be 'active', 'inactive', 'pending', 'suspended', 'disabled', 'security_hold' or 'deleted'")),
 			),
 		),
 		validation.Field(&f.TenantUUID,
@@ -145,10 +157,62 @@ func (f UserFilterDTO) Validate() error {
 				is.UUID.Error("Client ID must be a valid UUID"),
 			),
 		),
-		validation.Field(&f.PaginationRequestDTO),
+		validation.Field(&f.Include,
+			validation.Each(validation.In(UserIncludeIdentities, UserIncludeRoles).Error("Include must be 'identities' or 'roles'")),
+		),
+		validation.Field(&f.PaginationRequestDTO, validation.Skip.When(f.Cursor != nil)),
+		validation.Field(&f.Cursor),
+	)
+}
+
+// Relations a user list can include with the include query parameter.
+const (
+	UserIncludeIdentities = "identities"
+	UserIncludeRoles      = "roles"
+)
+
+// Includes reports whether the filter asks to include relation.
+func (f UserFilterDTO) Includes(relation string) bool {
+	return slices.Contains(f.Include, relation)
+}
+
+// UserSearchDTO is the query of GET /users/search. Results are ranked by
+// relevance, so unlike UserFilterDTO it takes no sort; page and limit default
+// to the first 20 matches.
+type UserSearchDTO struct {
+	Query             string   `json:"q"`
+	Status            []string `json:"status,omitempty"`
+	Include           []string `json:"include,omitempty"`
+	IncludeSubtenants bool     `json:"include_subtenants,omitempty"`
+	Page              int      `json:"page"`
+	Limit             int      `json:"limit"`
+}
+
+// Validate validates the user search DTO.
+func (f UserSearchDTO) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.Query,
+			validation.Required.Error("Search query is required"),
+			validation.RuneLength(2, 100).Error("Search query must be between 2 and 100 characters"),
+		),
+		validation.Field(&f.Status,
+			validation.When(len(f.Status) > 0,
+				validation.Each(validation.In(model.StatusActive, model.StatusInactive, model.StatusPending, model.StatusSuspended, model.StatusDisabled, model.StatusSecurityHold, model.StatusDeleted).Error("Status must be 'active', 'inactive', 'pending', 'suspended', 'disabled', 'security_hold' or 'deleted'")),
+			),
+		),
+		validation.Field(&f.Include,
+			validation.Each(validation.In(UserIncludeIdentities, UserIncludeRoles).Error("Include must be 'identities' or 'roles'")),
+		),
+		validation.Field(&f.Page, validation.Min(0).Error("Page must not be negative")),
+		validation.Field(&f.Limit, validation.Min(0).Error("Limit must not be negative"), validation.Max(100).Error("Limit cannot exceed 100")),
 	)
 }
 
+// Includes reports whether the search asked to include the relation.
+func (f UserSearchDTO) Includes(relation string) bool {
+	return slices.Contains(f.Include, relation)
+}
+
 // User role filter structure
 type UserRoleFilterDTO struct {
 	Name        *string `json:"name,omitempty"`
@@ -178,3 +242,123 @@ func (r UserIdentityFilterDTO) Validate() error {
 		validation.Field(&r.PaginationRequestDTO),
 	)
 }
+
+// User delta page size bounds.
+const (
+	UserDeltaDefaultLimit = 100
+	UserDeltaMaxLimit     = 500
+)
+
+// UserDeltaFilterDTO holds query parameters for the differential user sync.
+type UserDeltaFilterDTO struct {
+	Since string `json:"since"`
+	Limit int    `json:"limit"`
+}
+
+// Validate validates the delta parameters.
+func (f UserDeltaFilterDTO) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.Since,
+			validation.Length(0, 64).Error("Cursor cannot exceed 64 characters"),
+		),
+		validation.Field(&f.Limit,
+			validation.Required.Error("Limit is required"),
+			validation.Min(1).Error("Limit must be greater than 0"),
+			validation.Max(UserDeltaMaxLimit).Error("Limit cannot exceed 500"),
+		),
+	)
+}
+
+// UserDeltaEntryDTO is one changed user. Tombstones have Deleted set, carry
+// DeletedAt and omit User.
+type UserDeltaEntryDTO struct {
+	UserUUID  uuid.UUID        `json:"user_id"`
+	Deleted   bool             `json:"deleted"`
+	DeletedAt *time.Time       `json:"deleted_at,omitempty"`
+	User      *UserResponseDTO `json:"user,omitempty"`
+}
+
+// UserDeltaResponseDTO is a page of user changes. Clients persist NextCursor
+// and pass it back as since; HasMore reports whether another page is
+// immediately available.
+type UserDeltaResponseDTO struct {
+	Users      []UserDeltaEntryDTO `json:"users"`
+	NextCursor string              `json:"next_cursor"`
+	HasMore    bool                `json:"has_more"`
+}
+
+// User export formats.
+const (
+	UserExportFormatCSV    = "csv"
+	UserExportFormatNDJSON = "ndjson"
+)
+
+// UserExportColumns are the columns a user export may contain, in the order
+// they are exported when no columns are selected.
+var UserExportColumns = []string{
+	"user_id", "username", "fullname", "email", "phone", "status",
+	"is_email_verified", "is_phone_verified", "is_profile_completed", "is_account_completed",
+	"metadata", "created_at", "updated_at", "deleted_at",
+}
+
+// UserExportFilterDTO holds query parameters for the user export. The
+// filters are those of the user list; Columns selects and orders the
+// exported columns.
+type UserExportFilterDTO struct {
+	Username     *string  `json:"username,omitempty"`
+	Email        *string  `json:"email,omitempty"`
+	Phone        *string  `json:"phone,omitempty"`
+	Status       []string `json:"status,omitempty"`
+	RoleUUID     *string  `json:"role_id,omitempty"`
+	UserPoolUUID *string  `json:"user_pool_id,omitempty"`
+	ClientUUID   *string  `json:"client_id,omitempty"`
+	Format       string   `json:"format"`
+	Columns      []string `json:"columns,omitempty"`
+}
+
+// Validate validates the export parameters.
+func (f UserExportFilterDTO) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.Status,
+			validation.When(len(f.Status) > 0,
+				validation.Each(validation.In(model.StatusActive, model.StatusInactive, model.StatusPending, model.StatusSuspended, model.StatusDisabled, model.StatusSecurityHold, model.StatusDeleted).Error("Status must be 'active', 'inactive', 'pending', 'suspended', 'disabled', 'security_hold' or 'deleted'")),
+			),
+		),
+		validation.Field(&f.RoleUUID,
+			validation.When(f.RoleUUID != nil,
+				is.UUID.Error("Role ID must be a valid UUID"),
+			),
+		),
+		validation.Field(&f.UserPoolUUID,
+			validation.When(f.UserPoolUUID != nil,
+				is.UUID.Error("User pool ID must be a valid UUID"),
+			),
+		),
+		validation.Field(&f.ClientUUID,
+			validation.When(f.ClientUUID != nil,
+				is.UUID.Error("Client ID must be a valid UUID"),
+			),
+		),
+		validation.Field(&f.Format,
+			validation.Required.Error("Format is required"),
+			validation.In(UserExportFormatCSV, UserExportFormatNDJSON).Error("Format must be 'csv' or 'ndjson'"),
+		),
+		validation.Field(&f.Columns, validation.By(validateUserExportColumns)),
+	)
+}
+
+// validateUserExportColumns rejects unknown and repeated export columns.
+func validateUserExportColumns(value any) error {
+	columns, _ := value.([]string)
+	seen := make(map[string]struct{}, len(columns))
+	for _, c := range columns {
+		if !slices.Contains(UserExportColumns, c) {
+			return validation.NewError("validation_error", "Unknown export column '"+c+"'")
+		}
+		if _, ok := seen[c]; ok {
+			return validation.NewError("validation_error", "Export column '"+c+"' is repeated")
+		}
+		seen[c] = struct{}{}
+	}
+	return nil
+}
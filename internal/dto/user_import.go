@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+
+	"github.com/maintainerd/auth/internal/model"
+)
+
+// UserImportColumns are the columns an import file may contain. In a CSV
+// file they are named by the header row; the JSON form uses the same keys.
+var UserImportColumns = []string{"username", "fullname", "email", "phone", "password", "password_hash", "status", "metadata"}
+
+// UserImportRowDTO is one user of an import file. Either a plaintext
+// password or a bcrypt/argon2id password_hash may be given, or neither for
+// users who will set a password through password reset. Status defaults to
+// active.
+type UserImportRowDTO struct {
+	Username     string         `json:"username"`
+	Fullname     string         `json:"fullname"`
+	Email        string         `json:"email,omitempty"`
+	Phone        string         `json:"phone,omitempty"`
+	Password     string         `json:"password,omitempty"`
+	PasswordHash string         `json:"password_hash,omitempty"`
+	Status       string         `json:"status,omitempty"`
+	Metadata     datatypes.JSON `json:"metadata,omitempty"`
+}
+
+func (dto UserImportRowDTO) Validate() error {
+	return validation.ValidateStruct(&dto,
+		validation.Field(&dto.Username, validation.Required, validation.Length(3, 50)),
+		validation.Field(&dto.Fullname, validation.Required, validation.Length(1, 255)),
+		validation.Field(&dto.Email, is.Email),
+		validation.Field(&dto.Phone, validation.Length(10, 20)),
+		validation.Field(&dto.Password, validation.Length(8, 100)),
+		validation.Field(&dto.PasswordHash,
+			validation.When(dto.Password != "", validation.Empty.Error("must not be set together with password")),
+			validation.Length(0, 255),
+		),
+		validation.Field(&dto.Status, validation.In(model.StatusActive, model.StatusInactive, model.StatusPending, model.StatusSuspended)),
+	)
+}
+
+// UserImportRequestDTO is the body of a JSON import.
+type UserImportRequestDTO struct {
+	Users []UserImportRowDTO `json:"users"`
+}
+
+type UserImportRowErrorResponseDTO struct {
+	Row      int    `json:"row"`
+	Username string `json:"username,omitempty"`
+	Reason   string `json:"reason"`
+	Message  string `json:"message"`
+}
+
+// UserImportResponseDTO describes an import: its progress while it runs
+// and the rows that were not imported.
+type UserImportResponseDTO struct {
+	UserImportUUID uuid.UUID                       `json:"import_id"`
+	Format         string                          `json:"format"`
+	DryRun         bool                            `json:"dry_run"`
+	Status         string                          `json:"status"`
+	TotalRows      int                             `json:"total_rows"`
+	ProcessedRows  int                             `json:"processed_rows"`
+	ImportedRows   int                             `json:"imported_rows"`
+	DuplicateRows  int                             `json:"duplicate_rows"`
+	InvalidRows    int                             `json:"invalid_rows"`
+	FailedRows     int                             `json:"failed_rows"`
+	Errors         []UserImportRowErrorResponseDTO `json:"errors"`
+	Error          *string                         `json:"error,omitempty"`
+	StartedAt      *time.Time                      `json:"started_at,omitempty"`
+	CompletedAt    *time.Time                      `json:"completed_at,omitempty"`
+	CreatedAt      time.Time                       `json:"created_at"`
+}
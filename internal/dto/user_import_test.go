@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserImportRowDTO_Validate(t *testing.T) {
+	valid := UserImportRowDTO{Username: "alice", Fullname: "Alice Doe", Email: "alice@example.com"}
+
+	tests := []struct {
+		name    string
+		mutate  func(*UserImportRowDTO)
+		wantErr bool
+	}{
+		{"valid without password", func(*UserImportRowDTO) {}, false},
+		{"valid with password", func(d *UserImportRowDTO) { d.Password = "Secret#123" }, false},
+		{"valid with hash", func(d *UserImportRowDTO) { d.PasswordHash = "$2a$10$abc" }, false},
+		{"valid with status", func(d *UserImportRowDTO) { d.Status = "pending" }, false},
+		{"missing username", func(d *UserImportRowDTO) { d.Username = "" }, true},
+		{"short username", func(d *UserImportRowDTO) { d.Username = "ab" }, true},
+		{"missing fullname", func(d *UserImportRowDTO) { d.Fullname = "" }, true},
+		{"bad email", func(d *UserImportRowDTO) { d.Email = "not-an-email" }, true},
+		{"short phone", func(d *UserImportRowDTO) { d.Phone = "123" }, true},
+		{"short password", func(d *UserImportRowDTO) { d.Password = "short" }, true},
+		{"password and hash", func(d *UserImportRowDTO) {
+			d.Password = "Secret#123"
+			d.PasswordHash = "$2a$10$abc"
+		}, true},
+		{"bad status", func(d *UserImportRowDTO) { d.Status = "deleted" }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := valid
+			tt.mutate(&row)
+			err := row.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -2,7 +2,11 @@ package dto
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
+	// Timezones are checked against the IANA database, which the runtime
+	// image does not ship
+	_ "time/tzdata"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
@@ -10,12 +14,37 @@ import (
 	"github.com/maintainerd/auth/internal/model"
 )
 
+// Rules shared by user settings and the tenant's user setting defaults.
+var (
+	validTimezone = validation.By(func(value any) error {
+		value, _ = validation.Indirect(value)
+		tz, _ := value.(string)
+		if tz == "" {
+			return nil
+		}
+		if _, err := time.LoadLocation(tz); err != nil {
+			return errors.New("Timezone must be an IANA time zone such as Europe/Berlin")
+		}
+		return nil
+	})
+	validTheme = validation.In(
+		model.ThemeLight, model.ThemeDark, model.ThemeSystem,
+	).Error("Theme must be light, dark, or system")
+	validDateFormat = validation.In(
+		model.DateFormatISO, model.DateFormatDMY, model.DateFormatMDY, model.DateFormatDMYDot,
+	).Error("Date format must be YYYY-MM-DD, DD/MM/YYYY, MM/DD/YYYY, or DD.MM.YYYY")
+)
+
 type UserSettingRequestDTO struct {
 	// Internationalization
 	Timezone          *string `json:"timezone,omitempty"`
 	PreferredLanguage *string `json:"preferred_language,omitempty"`
 	Locale            *string `json:"locale,omitempty"`
 
+	// Display
+	Theme      *string `json:"theme,omitempty"`
+	DateFormat *string `json:"date_format,omitempty"`
+
 	// Social Media & External Links
 	SocialLinks map[string]string `json:"social_links,omitempty"`
 
@@ -42,6 +71,7 @@ func (r UserSettingRequestDTO) Validate() error {
 		validation.Field(&r.Timezone,
 			validation.NilOrNotEmpty,
 			validation.RuneLength(0, 50).Error("Timezone must be at most 50 characters"),
+			validTimezone,
 		),
 		validation.Field(&r.PreferredLanguage,
 			validation.NilOrNotEmpty,
@@ -52,6 +82,16 @@ func (r UserSettingRequestDTO) Validate() error {
 			validation.RuneLength(2, 10).Error("Locale must be 2-10 characters"),
 		),
 
+		// Display
+		validation.Field(&r.Theme,
+			validation.NilOrNotEmpty,
+			validTheme,
+		),
+		validation.Field(&r.DateFormat,
+			validation.NilOrNotEmpty,
+			validDateFormat,
+		),
+
 		// Communication Preferences
 		validation.Field(&r.PreferredContactMethod,
 			validation.NilOrNotEmpty,
@@ -93,6 +133,10 @@ type UserSettingResponseDTO struct {
 	PreferredLanguage *string `json:"preferred_language,omitempty"`
 	Locale            *string `json:"locale,omitempty"`
 
+	// Display
+	Theme      *string `json:"theme,omitempty"`
+	DateFormat *string `json:"date_format,omitempty"`
+
 	// Social Media & External Links
 	SocialLinks map[string]any `json:"social_links,omitempty"`
 
@@ -85,6 +85,30 @@ func TestUserSettingRequestDto_Validate(t *testing.T) {
 		d := UserSettingRequestDTO{EmergencyContactName: strPtr(string(make([]byte, 201)))}
 		require.Error(t, d.Validate())
 	})
+
+	t.Run("valid display preferences", func(t *testing.T) {
+		d := UserSettingRequestDTO{
+			Theme:      strPtr(model.ThemeDark),
+			DateFormat: strPtr(model.DateFormatDMYDot),
+			Timezone:   strPtr("Asia/Tokyo"),
+		}
+		assert.NoError(t, d.Validate())
+	})
+
+	t.Run("unknown timezone", func(t *testing.T) {
+		d := UserSettingRequestDTO{Timezone: strPtr("Mars/Olympus_Mons")}
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("invalid theme", func(t *testing.T) {
+		d := UserSettingRequestDTO{Theme: strPtr("neon")}
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("invalid date format", func(t *testing.T) {
+		d := UserSettingRequestDTO{DateFormat: strPtr("YY/M/D")}
+		require.Error(t, d.Validate())
+	})
 }
 
 func TestNewUserSettingResponseDTO(t *testing.T) {
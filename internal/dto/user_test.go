@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -123,6 +124,11 @@ func TestUserFilterDto_Validate(t *testing.T) {
 		assert.NoError(t, f.Validate())
 	})
 
+	t.Run("multiple statuses", func(t *testing.T) {
+		f := UserFilterDTO{PaginationRequestDTO: validPagination(), Status: []string{"active", "pending", "suspended"}}
+		assert.NoError(t, f.Validate())
+	})
+
 	t.Run("invalid status", func(t *testing.T) {
 		f := UserFilterDTO{PaginationRequestDTO: validPagination(), Status: []string{"unknown"}}
 		require.Error(t, f.Validate())
@@ -139,6 +145,16 @@ func TestUserFilterDto_Validate(t *testing.T) {
 		f := UserFilterDTO{PaginationRequestDTO: validPagination(), RoleUUID: &s}
 		require.Error(t, f.Validate())
 	})
+
+	t.Run("include", func(t *testing.T) {
+		f := UserFilterDTO{PaginationRequestDTO: validPagination(), Include: []string{UserIncludeIdentities, UserIncludeRoles}}
+		require.NoError(t, f.Validate())
+		assert.True(t, f.Includes(UserIncludeRoles))
+		assert.False(t, UserFilterDTO{}.Includes(UserIncludeRoles))
+
+		f.Include = []string{"sessions"}
+		require.Error(t, f.Validate())
+	})
 }
 
 func TestUserRoleFilterDto_Validate(t *testing.T) {
@@ -151,3 +167,43 @@ func TestUserIdentityFilterDto_Validate(t *testing.T) {
 	assert.NoError(t, f.Validate())
 }
 
+
+func TestUserSearchDto_Validate(t *testing.T) {
+	assert.NoError(t, UserSearchDTO{Query: "al"}.Validate())
+	assert.NoError(t, UserSearchDTO{Query: "alice", Status: []string{"active"}, Include: []string{UserIncludeRoles}, Limit: 100}.Validate())
+	assert.Error(t, UserSearchDTO{}.Validate())
+	assert.Error(t, UserSearchDTO{Query: "a"}.Validate())
+	assert.Error(t, UserSearchDTO{Query: strings.Repeat("a", 101)}.Validate())
+	assert.Error(t, UserSearchDTO{Query: "alice", Status: []string{"bogus"}}.Validate())
+	assert.Error(t, UserSearchDTO{Query: "alice", Include: []string{"groups"}}.Validate())
+	assert.Error(t, UserSearchDTO{Query: "alice", Page: -1}.Validate())
+	assert.Error(t, UserSearchDTO{Query: "alice", Limit: 101}.Validate())
+	assert.True(t, UserSearchDTO{Include: []string{UserIncludeIdentities}}.Includes(UserIncludeIdentities))
+}
+
+func TestUserDeltaFilterDto_Validate(t *testing.T) {
+	assert.NoError(t, UserDeltaFilterDTO{Limit: UserDeltaDefaultLimit}.Validate())
+	assert.NoError(t, UserDeltaFilterDTO{Since: "ZTE6MTA0Mg", Limit: 500}.Validate())
+	assert.Error(t, UserDeltaFilterDTO{Limit: 0}.Validate())
+	assert.Error(t, UserDeltaFilterDTO{Limit: 501}.Validate())
+	assert.Error(t, UserDeltaFilterDTO{Since: strings.Repeat("a", 65), Limit: 10}.Validate())
+}
+
+func TestUserExportFilterDto_Validate(t *testing.T) {
+	assert.NoError(t, UserExportFilterDTO{Format: UserExportFormatCSV}.Validate())
+	assert.NoError(t, UserExportFilterDTO{Format: UserExportFormatNDJSON, Columns: []string{"email", "user_id"}, Status: []string{"active"}}.Validate())
+	assert.Error(t, UserExportFilterDTO{}.Validate())
+	assert.Error(t, UserExportFilterDTO{Format: "xml"}.Validate())
+	assert.Error(t, UserExportFilterDTO{Format: UserExportFormatCSV, Columns: []string{"password"}}.Validate())
+	assert.Error(t, UserExportFilterDTO{Format: UserExportFormatCSV, Columns: []string{"email", "email"}}.Validate())
+	assert.Error(t, UserExportFilterDTO{Format: UserExportFormatCSV, Status: []string{"unknown"}}.Validate())
+	bad := "bad"
+	assert.Error(t, UserExportFilterDTO{Format: UserExportFormatCSV, RoleUUID: &bad}.Validate())
+}
+
+func TestUserFilterDto_Validate_Cursor(t *testing.T) {
+	// Cursor pagination replaces page/limit, which may then be absent.
+	assert.NoError(t, UserFilterDTO{Cursor: &CursorPaginationRequestDTO{Limit: 10}}.Validate())
+	assert.Error(t, UserFilterDTO{Cursor: &CursorPaginationRequestDTO{}}.Validate())
+	assert.Error(t, UserFilterDTO{}.Validate())
+}
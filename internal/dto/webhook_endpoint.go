@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -10,17 +12,34 @@ import (
 )
 
 // WebhookEndpointResponseDTO is the JSON representation of a webhook endpoint.
+// Secret is only present when the server generated a signing secret, on
+// create or rotation; it cannot be read back later.
 type WebhookEndpointResponseDTO struct {
-	WebhookEndpointID string    `json:"webhook_endpoint_id"`
-	URL               string    `json:"url"`
-	Events            any       `json:"events"`
-	MaxRetries        int       `json:"max_retries"`
-	TimeoutSeconds    int       `json:"timeout_seconds"`
-	Status            string    `json:"status"`
-	Description       string    `json:"description"`
-	LastTriggeredAt   *string   `json:"last_triggered_at"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	WebhookEndpointID       string     `json:"webhook_endpoint_id"`
+	URL                     string     `json:"url"`
+	Events                  any        `json:"events"`
+	MaxRetries              int        `json:"max_retries"`
+	TimeoutSeconds          int        `json:"timeout_seconds"`
+	Status                  string     `json:"status"`
+	Description             string     `json:"description"`
+	HasSecret               bool       `json:"has_secret"`
+	Secret                  *string    `json:"secret,omitempty"`
+	SecretRotatedAt         *time.Time `json:"secret_rotated_at"`
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at"`
+	LastTriggeredAt         *string    `json:"last_triggered_at"`
+	CreatedAt               time.Time  `json:"created_at"`
+	UpdatedAt               time.Time  `json:"updated_at"`
+}
+
+// webhookEventTypeValues returns the event names an endpoint may subscribe
+// to as validation.In arguments.
+func webhookEventTypeValues() []any {
+	names := model.WebhookEventTypes()
+	values := make([]any, len(names))
+	for i, name := range names {
+		values[i] = name
+	}
+	return values
 }
 
 // WebhookEndpointCreateRequestDTO is the request body for creating a webhook
@@ -44,7 +63,10 @@ func (r WebhookEndpointCreateRequestDTO) Validate() error {
 		),
 		validation.Field(&r.Events,
 			validation.Required.Error("Events list is required"),
-			validation.Each(validation.Length(1, 100).Error("Event name must be between 1 and 100 characters")),
+			validation.Each(
+				validation.Length(1, 100).Error("Event name must be between 1 and 100 characters"),
+				validation.In(webhookEventTypeValues()...).Error("Event must be one of: "+strings.Join(model.WebhookEventTypes(), ", ")),
+			),
 		),
 		validation.Field(&r.MaxRetries,
 			validation.When(r.MaxRetries != nil, validation.Min(0).Error("Max retries must be at least 0"), validation.Max(10).Error("Max retries must not exceed 10")),
@@ -82,7 +104,10 @@ func (r WebhookEndpointUpdateRequestDTO) Validate() error {
 		),
 		validation.Field(&r.Events,
 			validation.Required.Error("Events list is required"),
-			validation.Each(validation.Length(1, 100).Error("Event name must be between 1 and 100 characters")),
+			validation.Each(
+				validation.Length(1, 100).Error("Event name must be between 1 and 100 characters"),
+				validation.In(webhookEventTypeValues()...).Error("Event must be one of: "+strings.Join(model.WebhookEventTypes(), ", ")),
+			),
 		),
 		validation.Field(&r.MaxRetries,
 			validation.When(r.MaxRetries != nil, validation.Min(0).Error("Max retries must be at least 0"), validation.Max(10).Error("Max retries must not exceed 10")),
@@ -115,6 +140,23 @@ func (r WebhookEndpointUpdateStatusRequestDTO) Validate() error {
 	)
 }
 
+// WebhookEndpointRotateSecretRequestDTO is the optional request body for
+// rotating a webhook endpoint's signing secret. GracePeriodSeconds is how long
+// the previous secret keeps signing deliveries; it defaults to 24 hours and
+// 0 revokes the previous secret immediately.
+type WebhookEndpointRotateSecretRequestDTO struct {
+	GracePeriodSeconds *int `json:"grace_period_seconds"`
+}
+
+// Validate validates the webhook endpoint secret rotation request.
+func (r WebhookEndpointRotateSecretRequestDTO) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.GracePeriodSeconds,
+			validation.When(r.GracePeriodSeconds != nil, validation.Min(0).Error("Grace period must be at least 0 seconds"), validation.Max(604800).Error("Grace period must not exceed 7 days")),
+		),
+	)
+}
+
 // WebhookEndpointFilterDTO holds filter parameters for listing webhook
 // endpoints.
 type WebhookEndpointFilterDTO struct {
@@ -128,3 +170,47 @@ func (f WebhookEndpointFilterDTO) Validate() error {
 		validation.Field(&f.PaginationRequestDTO),
 	)
 }
+
+// WebhookDeliveryResponseDTO is the JSON representation of a webhook
+// delivery. Payload is only included when a single delivery is fetched.
+type WebhookDeliveryResponseDTO struct {
+	WebhookDeliveryID string          `json:"webhook_delivery_id"`
+	EventID           string          `json:"event_id"`
+	EventType         string          `json:"event_type"`
+	Status            string          `json:"status"`
+	Attempts          int             `json:"attempts"`
+	NextAttemptAt     *time.Time      `json:"next_attempt_at"`
+	LastAttemptAt     *time.Time      `json:"last_attempt_at"`
+	LastStatusCode    *int            `json:"last_status_code"`
+	LastError         *string         `json:"last_error"`
+	LastDurationMs    *int64          `json:"last_duration_ms"`
+	DeliveredAt       *time.Time      `json:"delivered_at"`
+	Payload           json.RawMessage `json:"payload,omitempty"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+// WebhookDeliveryFilterDTO holds filter parameters for listing the
+// deliveries of a webhook endpoint.
+type WebhookDeliveryFilterDTO struct {
+	Status    []string `json:"status"`
+	EventType []string `json:"event_type"`
+	PaginationRequestDTO
+}
+
+// Validate validates the webhook delivery filter.
+func (f WebhookDeliveryFilterDTO) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.Status,
+			validation.Each(validation.In(
+				model.WebhookDeliveryStatusPending,
+				model.WebhookDeliveryStatusSucceeded,
+				model.WebhookDeliveryStatusFailed,
+			).Error("Status must be 'pending', 'succeeded' or 'failed'")),
+		),
+		validation.Field(&f.EventType,
+			validation.Each(validation.Length(1, 60).Error("Event type must be between 1 and 60 characters")),
+		),
+		validation.Field(&f.PaginationRequestDTO),
+	)
+}
@@ -69,6 +69,18 @@ func TestWebhookEndpointCreateRequestDTO_Validate(t *testing.T) {
 		require.Error(t, d.Validate())
 	})
 
+	t.Run("auth events and wildcard", func(t *testing.T) {
+		d := validWebhookCreate()
+		d.Events = []string{"login.failed", "token.revoked", model.WebhookEventAll}
+		assert.NoError(t, d.Validate())
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		d := validWebhookCreate()
+		d.Events = []string{"user.exploded"}
+		require.Error(t, d.Validate())
+	})
+
 	t.Run("max_retries too high", func(t *testing.T) {
 		d := validWebhookCreate()
 		v := 11
@@ -204,6 +216,30 @@ func TestWebhookEndpointUpdateStatusRequestDTO_Validate(t *testing.T) {
 // Filter
 // ---------------------------------------------------------------------------
 
+func TestWebhookEndpointRotateSecretRequestDTO_Validate(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+
+	t.Run("empty body", func(t *testing.T) {
+		assert.NoError(t, WebhookEndpointRotateSecretRequestDTO{}.Validate())
+	})
+
+	t.Run("zero grace", func(t *testing.T) {
+		assert.NoError(t, WebhookEndpointRotateSecretRequestDTO{GracePeriodSeconds: intPtr(0)}.Validate())
+	})
+
+	t.Run("seven days", func(t *testing.T) {
+		assert.NoError(t, WebhookEndpointRotateSecretRequestDTO{GracePeriodSeconds: intPtr(604800)}.Validate())
+	})
+
+	t.Run("negative grace", func(t *testing.T) {
+		require.Error(t, WebhookEndpointRotateSecretRequestDTO{GracePeriodSeconds: intPtr(-1)}.Validate())
+	})
+
+	t.Run("grace too long", func(t *testing.T) {
+		require.Error(t, WebhookEndpointRotateSecretRequestDTO{GracePeriodSeconds: intPtr(604801)}.Validate())
+	})
+}
+
 func TestWebhookEndpointFilterDTO_Validate(t *testing.T) {
 	t.Run("valid", func(t *testing.T) {
 		d := WebhookEndpointFilterDTO{
@@ -225,3 +261,30 @@ func TestWebhookEndpointFilterDTO_Validate(t *testing.T) {
 		require.Error(t, d.Validate())
 	})
 }
+
+// ---------------------------------------------------------------------------
+// Delivery filter
+// ---------------------------------------------------------------------------
+
+func TestWebhookDeliveryFilterDTO_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		d := WebhookDeliveryFilterDTO{
+			Status:               []string{model.WebhookDeliveryStatusFailed},
+			EventType:            []string{"user.created"},
+			PaginationRequestDTO: PaginationRequestDTO{Page: 1, Limit: 10},
+		}
+		assert.NoError(t, d.Validate())
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		d := WebhookDeliveryFilterDTO{
+			Status:               []string{"delivered"},
+			PaginationRequestDTO: PaginationRequestDTO{Page: 1, Limit: 10},
+		}
+		require.Error(t, d.Validate())
+	})
+
+	t.Run("missing pagination", func(t *testing.T) {
+		require.Error(t, WebhookDeliveryFilterDTO{}.Validate())
+	})
+}
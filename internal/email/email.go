@@ -16,7 +16,7 @@ import (
 // SendEmailParams holds the parameters for sending an email.
 type SendEmailParams struct {
 	To        string
-	From      string // Optional, if empty use config.SMTPFrom
+	From      string // Optional, if empty use the configured SMTP sender
 	Subject   string
 	BodyHTML  string
 	BodyPlain string // Optional plain text fallback
@@ -30,16 +30,18 @@ func sendEmail(ctx context.Context, params SendEmailParams) error {
 	_, span := otel.Tracer("email").Start(ctx, "smtp.send")
 	defer span.End()
 
+	// Read once so a config reload mid-send cannot mix two servers
+	smtp := config.Current().SMTP
 	span.SetAttributes(
-		attribute.String("smtp.host", config.SMTPHost),
-		attribute.Int("smtp.port", config.SMTPPort),
+		attribute.String("smtp.host", smtp.Host),
+		attribute.Int("smtp.port", smtp.Port),
 		attribute.String("email.to", params.To),
 		attribute.String("email.subject", params.Subject),
 	)
 
 	from := params.From
 	if from == "" {
-		from = gomail.NewMessage().FormatAddress(config.SMTPFromEmail, config.SMTPFromName)
+		from = gomail.NewMessage().FormatAddress(smtp.FromEmail, smtp.FromName)
 	}
 
 	m := gomail.NewMessage()
@@ -54,10 +56,10 @@ func sendEmail(ctx context.Context, params SendEmailParams) error {
 		m.SetBody("text/html", params.BodyHTML)
 	}
 
-	d := gomail.NewDialer(config.SMTPHost, config.SMTPPort, config.SMTPUser, config.SMTPPass)
+	d := gomail.NewDialer(smtp.Host, smtp.Port, smtp.User, smtp.Pass)
 	d.TLSConfig = &tls.Config{
 		MinVersion: tls.VersionTLS12,
-		ServerName: config.SMTPHost,
+		ServerName: smtp.Host,
 	}
 	if err := d.DialAndSend(m); err != nil {
 		span.RecordError(err)
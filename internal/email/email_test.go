@@ -13,28 +13,15 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-// setSMTPConfig sets up config fields and restores them after the test.
+// setSMTPConfig sets up the SMTP config and restores it after the test.
 func setSMTPConfig(t *testing.T, host string, port int, user, pass, fromEmail, fromName string) {
 	t.Helper()
-	origHost, origPort := config.SMTPHost, config.SMTPPort
-	origUser, origPass := config.SMTPUser, config.SMTPPass
-	origFrom, origName := config.SMTPFromEmail, config.SMTPFromName
-
-	config.SMTPHost = host
-	config.SMTPPort = port
-	config.SMTPUser = user
-	config.SMTPPass = pass
-	config.SMTPFromEmail = fromEmail
-	config.SMTPFromName = fromName
-
-	t.Cleanup(func() {
-		config.SMTPHost = origHost
-		config.SMTPPort = origPort
-		config.SMTPUser = origUser
-		config.SMTPPass = origPass
-		config.SMTPFromEmail = origFrom
-		config.SMTPFromName = origName
-	})
+	orig := config.Current()
+	t.Cleanup(func() { config.SetCurrent(orig) })
+
+	next := *orig
+	next.SMTP = config.SMTPConfig{Host: host, Port: port, User: user, Pass: pass, FromEmail: fromEmail, FromName: fromName}
+	config.SetCurrent(&next)
 }
 
 func TestSendEmail_FailsWhenSMTPUnreachable(t *testing.T) {
@@ -0,0 +1,117 @@
+// Package eventbus carries domain events from the services that emit them to
+// the subscribers that react to them. Services record events in the events
+// table inside their own transaction; a relay publishes committed events to
+// the bus, so subscribers only ever see changes that actually happened.
+//
+// Delivery is at least once: a batch that fails is published again, so every
+// subscriber must tolerate seeing an event twice. Event.ID is stable across
+// redeliveries and is the key to deduplicate on.
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is a committed domain event as published on the bus.
+type Event struct {
+	ID            uuid.UUID
+	Sequence      int64
+	TenantID      int64
+	TenantUUID    uuid.UUID
+	Type          string
+	SchemaVersion int
+	AggregateType string
+	AggregateUUID uuid.UUID
+	Payload       json.RawMessage
+	OccurredAt    time.Time
+}
+
+// Handler reacts to one event. A returned error makes the relay publish the
+// event again later.
+type Handler func(ctx context.Context, e Event) error
+
+// Bus fans events out to subscribers. The in-process implementation is the
+// default; a broker-backed implementation can satisfy the same interface.
+type Bus interface {
+	// Publish hands e to every subscriber whose types match it and returns
+	// the joined errors of the subscribers that failed.
+	Publish(ctx context.Context, e Event) error
+	// Subscribe registers h under name for the given event types. A type
+	// is either an exact event type, "*" for every event, or a prefix
+	// ending in ".*" such as "user.*".
+	Subscribe(name string, types []string, h Handler)
+}
+
+// Matches reports whether eventType is selected by one of patterns.
+func Matches(patterns []string, eventType string) bool {
+	for _, p := range patterns {
+		if p == "*" || p == eventType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(p, "*"); ok && strings.HasSuffix(prefix, ".") && strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+type subscription struct {
+	name    string
+	types   []string
+	handler Handler
+}
+
+// InProcess is a synchronous Bus that calls subscribers in the order they
+// subscribed, within the publisher's goroutine.
+type InProcess struct {
+	mu   sync.RWMutex
+	subs []subscription
+}
+
+// NewInProcess creates an InProcess bus with no subscribers.
+func NewInProcess() *InProcess {
+	return &InProcess{}
+}
+
+// Subscribe implements Bus.
+func (b *InProcess) Subscribe(name string, types []string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, subscription{name: name, types: types, handler: h})
+}
+
+// Publish implements Bus. Every matching subscriber is called even when an
+// earlier one fails; a panicking subscriber is reported as an error.
+func (b *InProcess) Publish(ctx context.Context, e Event) error {
+	b.mu.RLock()
+	subs := b.subs
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, s := range subs {
+		if !Matches(s.types, e.Type) {
+			continue
+		}
+		if err := call(ctx, s.handler, e); err != nil {
+			errs = append(errs, fmt.Errorf("subscriber %s: %w", s.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func call(ctx context.Context, h Handler, e Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return h(ctx, e)
+}
@@ -0,0 +1,71 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		patterns  []string
+		eventType string
+		want      bool
+	}{
+		{[]string{"*"}, "user.created", true},
+		{[]string{"user.created"}, "user.created", true},
+		{[]string{"user.*"}, "user.roles_added", true},
+		{[]string{"user.*"}, "role.created", false},
+		{[]string{"user*"}, "user.created", false},
+		{[]string{"role.created", "client.*"}, "client.deleted", true},
+		{nil, "user.created", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Matches(tt.patterns, tt.eventType), "%v %s", tt.patterns, tt.eventType)
+	}
+}
+
+func TestInProcess_Publish(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fans out to matching subscribers in order", func(t *testing.T) {
+		bus := NewInProcess()
+		var calls []string
+		record := func(name string) Handler {
+			return func(_ context.Context, e Event) error {
+				calls = append(calls, name+":"+e.Type)
+				return nil
+			}
+		}
+		bus.Subscribe("all", []string{"*"}, record("all"))
+		bus.Subscribe("users", []string{"user.*"}, record("users"))
+		bus.Subscribe("roles", []string{"role.created"}, record("roles"))
+
+		require.NoError(t, bus.Publish(ctx, Event{Type: "user.created"}))
+		assert.Equal(t, []string{"all:user.created", "users:user.created"}, calls)
+	})
+
+	t.Run("failure does not stop other subscribers", func(t *testing.T) {
+		bus := NewInProcess()
+		called := false
+		bus.Subscribe("broken", []string{"*"}, func(context.Context, Event) error { return errors.New("boom") })
+		bus.Subscribe("panics", []string{"*"}, func(context.Context, Event) error { panic("bad") })
+		bus.Subscribe("ok", []string{"*"}, func(context.Context, Event) error {
+			called = true
+			return nil
+		})
+
+		err := bus.Publish(ctx, Event{Type: "role.deleted"})
+		require.Error(t, err)
+		assert.True(t, called)
+		assert.Contains(t, err.Error(), "subscriber broken: boom")
+		assert.Contains(t, err.Error(), "subscriber panics: panic: bad")
+	})
+
+	t.Run("no subscribers", func(t *testing.T) {
+		assert.NoError(t, NewInProcess().Publish(ctx, Event{Type: "user.created"}))
+	})
+}
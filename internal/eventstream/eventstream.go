@@ -0,0 +1,223 @@
+// Package eventstream exports domain events from the event bus to a message
+// broker (Kafka or NATS JetStream), so that data platforms can consume them
+// without polling the REST API.
+//
+// Every message carries a JSON Envelope. EnvelopeVersion changes only when
+// the envelope itself changes shape; the payload has its own schema_version,
+// shared with the lifecycle event feed.
+package eventstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/eventbus"
+)
+
+// Supported providers.
+const (
+	ProviderKafka = "kafka"
+	ProviderNATS  = "nats"
+)
+
+// Supported Kafka SASL mechanisms. PLAIN is used when a username is set and
+// no mechanism is.
+const (
+	SASLPlain       = "plain"
+	SASLScramSHA256 = "scram-sha-256"
+	SASLScramSHA512 = "scram-sha-512"
+)
+
+const (
+	// EnvelopeVersion is the version of the Envelope format.
+	EnvelopeVersion = 1
+	// DefaultTopic routes each aggregate (user, role, client) to its own
+	// topic.
+	DefaultTopic = "maintainerd.auth.{aggregate}"
+	// DefaultTimeout bounds a single publish.
+	DefaultTimeout = 10 * time.Second
+)
+
+// Message headers set on every published event.
+const (
+	HeaderEventID         = "event-id"
+	HeaderEventType       = "event-type"
+	HeaderSchemaVersion   = "schema-version"
+	HeaderEnvelopeVersion = "envelope-version"
+	HeaderContentType     = "content-type"
+)
+
+// Envelope is the JSON value of every message. Field names match the
+// lifecycle event feed.
+type Envelope struct {
+	EnvelopeVersion int             `json:"envelope_version"`
+	EventID         uuid.UUID       `json:"event_id"`
+	Sequence        int64           `json:"sequence"`
+	EventType       string          `json:"event_type"`
+	SchemaVersion   int             `json:"schema_version"`
+	TenantID        uuid.UUID       `json:"tenant_id"`
+	AggregateType   string          `json:"aggregate_type"`
+	AggregateID     uuid.UUID       `json:"aggregate_id"`
+	Payload         json.RawMessage `json:"payload"`
+	OccurredAt      time.Time       `json:"occurred_at"`
+}
+
+// NewEnvelope maps a bus event to its envelope.
+func NewEnvelope(e eventbus.Event) Envelope {
+	payload := e.Payload
+	if len(payload) == 0 {
+		payload = json.RawMessage(`{}`)
+	}
+	return Envelope{
+		EnvelopeVersion: EnvelopeVersion,
+		EventID:         e.ID,
+		Sequence:        e.Sequence,
+		EventType:       e.Type,
+		SchemaVersion:   e.SchemaVersion,
+		TenantID:        e.TenantUUID,
+		AggregateType:   e.AggregateType,
+		AggregateID:     e.AggregateUUID,
+		Payload:         payload,
+		OccurredAt:      e.OccurredAt,
+	}
+}
+
+// Message is one event ready to be sent to a broker.
+type Message struct {
+	// Topic is the Kafka topic or NATS subject.
+	Topic string
+	// Key is the aggregate ID, so that Kafka keeps the events of one
+	// entity in order on a single partition.
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+}
+
+// Publisher sends messages to a broker. Publish returns once the broker has
+// acknowledged the message.
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+	Close() error
+}
+
+// Config selects and configures the broker.
+type Config struct {
+	Provider string
+	// Brokers are Kafka bootstrap addresses (host:port) or NATS server URLs.
+	Brokers []string
+	// Topic is the topic or subject template. {aggregate} and {type} are
+	// replaced with the event's aggregate and event type.
+	Topic string
+	// Types selects the exported events, as accepted by eventbus.Matches.
+	Types []string
+	// Username and Password authenticate with SASL (Kafka) or user
+	// credentials (NATS).
+	Username      string
+	Password      string
+	SASLMechanism string
+	TLS           bool
+	Timeout       time.Duration
+}
+
+// Exporter is an event bus subscriber that publishes events to a broker.
+type Exporter struct {
+	publisher Publisher
+	topic     string
+	types     []string
+	timeout   time.Duration
+}
+
+// New connects to the broker described by cfg.
+func New(cfg Config) (*Exporter, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, fmt.Errorf("event stream: no brokers configured")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	var (
+		publisher Publisher
+		err       error
+	)
+	switch cfg.Provider {
+	case ProviderKafka:
+		publisher, err = newKafkaPublisher(cfg)
+	case ProviderNATS:
+		publisher, err = newNATSPublisher(cfg)
+	default:
+		return nil, fmt.Errorf("event stream: unsupported provider %q", cfg.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewExporter(publisher, cfg.Topic, cfg.Types, cfg.Timeout), nil
+}
+
+// NewExporter creates an Exporter on top of an existing publisher. An empty
+// topic uses DefaultTopic and empty types export every event.
+func NewExporter(publisher Publisher, topic string, types []string, timeout time.Duration) *Exporter {
+	if topic == "" {
+		topic = DefaultTopic
+	}
+	if len(types) == 0 {
+		types = []string{"*"}
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Exporter{publisher: publisher, topic: topic, types: types, timeout: timeout}
+}
+
+// Types returns the event types the exporter subscribes to.
+func (x *Exporter) Types() []string {
+	return x.types
+}
+
+// Handle publishes e. It implements eventbus.Handler.
+func (x *Exporter) Handle(ctx context.Context, e eventbus.Event) error {
+	msg, err := x.message(e)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, x.timeout)
+	defer cancel()
+	if err := x.publisher.Publish(ctx, msg); err != nil {
+		return fmt.Errorf("publish %s to %s: %w", e.Type, msg.Topic, err)
+	}
+	return nil
+}
+
+// Close releases the broker connection.
+func (x *Exporter) Close() error {
+	return x.publisher.Close()
+}
+
+func (x *Exporter) message(e eventbus.Event) (Message, error) {
+	value, err := json.Marshal(NewEnvelope(e))
+	if err != nil {
+		return Message{}, fmt.Errorf("encode event envelope: %w", err)
+	}
+	return Message{
+		Topic: Topic(x.topic, e),
+		Key:   []byte(e.AggregateUUID.String()),
+		Value: value,
+		Headers: map[string]string{
+			HeaderEventID:         e.ID.String(),
+			HeaderEventType:       e.Type,
+			HeaderSchemaVersion:   strconv.Itoa(e.SchemaVersion),
+			HeaderEnvelopeVersion: strconv.Itoa(EnvelopeVersion),
+			HeaderContentType:     "application/json",
+		},
+	}, nil
+}
+
+// Topic expands a topic template for e.
+func Topic(template string, e eventbus.Event) string {
+	return strings.NewReplacer("{aggregate}", e.AggregateType, "{type}", e.Type).Replace(template)
+}
@@ -0,0 +1,152 @@
+package eventstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/eventbus"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePublisher struct {
+	msgs        []Message
+	deadlineSet bool
+	err         error
+	closed      bool
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, msg Message) error {
+	_, p.deadlineSet = ctx.Deadline()
+	if p.err != nil {
+		return p.err
+	}
+	p.msgs = append(p.msgs, msg)
+	return nil
+}
+
+func (p *fakePublisher) Close() error {
+	p.closed = true
+	return nil
+}
+
+func testEvent() eventbus.Event {
+	return eventbus.Event{
+		ID:            uuid.MustParse("7f1c2d6e-0b7a-4c43-9a53-3c1e2f6f9d10"),
+		Sequence:      42,
+		TenantID:      7,
+		TenantUUID:    uuid.MustParse("2b0d6a4e-5a1f-4d0c-8c55-9f2d4c0e1a22"),
+		Type:          "user.created",
+		SchemaVersion: 1,
+		AggregateType: "user",
+		AggregateUUID: uuid.MustParse("c3a8e0f4-6d2b-4b1e-a7f9-1e5d3c2b4a66"),
+		Payload:       json.RawMessage(`{"email":"jane@example.com"}`),
+		OccurredAt:    time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestExporter_Handle(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("publishes the envelope", func(t *testing.T) {
+		pub := &fakePublisher{}
+		x := NewExporter(pub, "", nil, 0)
+		e := testEvent()
+
+		require.NoError(t, x.Handle(ctx, e))
+		require.Len(t, pub.msgs, 1)
+		msg := pub.msgs[0]
+		assert.True(t, pub.deadlineSet)
+		assert.Equal(t, "maintainerd.auth.user", msg.Topic)
+		assert.Equal(t, []byte(e.AggregateUUID.String()), msg.Key)
+		assert.Equal(t, map[string]string{
+			HeaderEventID:         e.ID.String(),
+			HeaderEventType:       "user.created",
+			HeaderSchemaVersion:   "1",
+			HeaderEnvelopeVersion: "1",
+			HeaderContentType:     "application/json",
+		}, msg.Headers)
+		assert.JSONEq(t, `{
+			"envelope_version": 1,
+			"event_id": "7f1c2d6e-0b7a-4c43-9a53-3c1e2f6f9d10",
+			"sequence": 42,
+			"event_type": "user.created",
+			"schema_version": 1,
+			"tenant_id": "2b0d6a4e-5a1f-4d0c-8c55-9f2d4c0e1a22",
+			"aggregate_type": "user",
+			"aggregate_id": "c3a8e0f4-6d2b-4b1e-a7f9-1e5d3c2b4a66",
+			"payload": {"email": "jane@example.com"},
+			"occurred_at": "2026-03-01T12:00:00Z"
+		}`, string(msg.Value))
+	})
+
+	t.Run("empty payload is an object", func(t *testing.T) {
+		pub := &fakePublisher{}
+		e := testEvent()
+		e.Payload = nil
+
+		require.NoError(t, NewExporter(pub, "", nil, 0).Handle(ctx, e))
+		var env Envelope
+		require.NoError(t, json.Unmarshal(pub.msgs[0].Value, &env))
+		assert.JSONEq(t, `{}`, string(env.Payload))
+	})
+
+	t.Run("publish error", func(t *testing.T) {
+		pub := &fakePublisher{err: errors.New("leader not available")}
+
+		err := NewExporter(pub, "auth.{type}", nil, time.Second).Handle(ctx, testEvent())
+		require.Error(t, err)
+		assert.ErrorIs(t, err, pub.err)
+		assert.Contains(t, err.Error(), "publish user.created to auth.user.created")
+	})
+}
+
+func TestNewExporter_Defaults(t *testing.T) {
+	pub := &fakePublisher{}
+	x := NewExporter(pub, "", nil, 0)
+	assert.Equal(t, DefaultTopic, x.topic)
+	assert.Equal(t, []string{"*"}, x.Types())
+	assert.Equal(t, DefaultTimeout, x.timeout)
+
+	require.NoError(t, x.Close())
+	assert.True(t, pub.closed)
+}
+
+func TestTopic(t *testing.T) {
+	e := testEvent()
+	assert.Equal(t, "maintainerd.auth.user", Topic(DefaultTopic, e))
+	assert.Equal(t, "auth.user.created", Topic("auth.{type}", e))
+	assert.Equal(t, "auth-events", Topic("auth-events", e))
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	_, err := New(Config{Provider: ProviderKafka})
+	assert.ErrorContains(t, err, "no brokers")
+
+	_, err = New(Config{Provider: "rabbitmq", Brokers: []string{"localhost:5672"}})
+	assert.ErrorContains(t, err, "unsupported provider")
+
+	_, err = New(Config{Provider: ProviderKafka, Brokers: []string{"localhost:9092"}, Username: "auth", SASLMechanism: "gssapi"})
+	assert.ErrorContains(t, err, "unsupported SASL mechanism")
+}
+
+func TestKafkaSASLMechanism(t *testing.T) {
+	m, err := kafkaSASLMechanism(Config{})
+	require.NoError(t, err)
+	assert.Nil(t, m)
+
+	m, err = kafkaSASLMechanism(Config{Username: "auth", Password: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, plain.Mechanism{Username: "auth", Password: "secret"}, m)
+
+	for _, name := range []string{SASLScramSHA256, SASLScramSHA512} {
+		m, err = kafkaSASLMechanism(Config{Username: "auth", Password: "secret", SASLMechanism: name})
+		require.NoError(t, err)
+		assert.NotNil(t, m, name)
+	}
+}
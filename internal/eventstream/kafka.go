@@ -0,0 +1,75 @@
+package eventstream
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+// kafkaPublisher writes each message synchronously and waits for every
+// in-sync replica to acknowledge it. Topics are not created automatically.
+type kafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+func newKafkaPublisher(cfg Config) (*kafkaPublisher, error) {
+	mechanism, err := kafkaSASLMechanism(cfg)
+	if err != nil {
+		return nil, err
+	}
+	transport := &kafka.Transport{
+		DialTimeout: cfg.Timeout,
+		SASL:        mechanism,
+	}
+	if cfg.TLS {
+		transport.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return &kafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(cfg.Brokers...),
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			BatchTimeout: 10 * time.Millisecond,
+			WriteTimeout: cfg.Timeout,
+			Transport:    transport,
+		},
+	}, nil
+}
+
+func kafkaSASLMechanism(cfg Config) (sasl.Mechanism, error) {
+	if cfg.Username == "" {
+		return nil, nil
+	}
+	switch cfg.SASLMechanism {
+	case "", SASLPlain:
+		return plain.Mechanism{Username: cfg.Username, Password: cfg.Password}, nil
+	case SASLScramSHA256:
+		return scram.Mechanism(scram.SHA256, cfg.Username, cfg.Password)
+	case SASLScramSHA512:
+		return scram.Mechanism(scram.SHA512, cfg.Username, cfg.Password)
+	}
+	return nil, fmt.Errorf("event stream: unsupported SASL mechanism %q", cfg.SASLMechanism)
+}
+
+func (p *kafkaPublisher) Publish(ctx context.Context, msg Message) error {
+	headers := make([]kafka.Header, 0, len(msg.Headers))
+	for k, v := range msg.Headers {
+		headers = append(headers, kafka.Header{Key: k, Value: []byte(v)})
+	}
+	return p.writer.WriteMessages(ctx, kafka.Message{
+		Topic:   msg.Topic,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	})
+}
+
+func (p *kafkaPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,58 @@
+package eventstream
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// natsPublisher publishes to JetStream and waits for the stream to store
+// each message. The subjects must be covered by a stream; the event ID is
+// sent as the message ID so that JetStream drops redelivered events within
+// its duplicate window.
+type natsPublisher struct {
+	conn *nats.Conn
+	js   jetstream.JetStream
+}
+
+func newNATSPublisher(cfg Config) (*natsPublisher, error) {
+	opts := []nats.Option{
+		nats.Name("maintainerd-auth"),
+		nats.Timeout(cfg.Timeout),
+	}
+	if cfg.Username != "" {
+		opts = append(opts, nats.UserInfo(cfg.Username, cfg.Password))
+	}
+	if cfg.TLS {
+		opts = append(opts, nats.Secure(&tls.Config{MinVersion: tls.VersionTLS12}))
+	}
+
+	conn, err := nats.Connect(strings.Join(cfg.Brokers, ","), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("event stream: connect to NATS: %w", err)
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("event stream: open JetStream: %w", err)
+	}
+	return &natsPublisher{conn: conn, js: js}, nil
+}
+
+func (p *natsPublisher) Publish(ctx context.Context, msg Message) error {
+	m := nats.NewMsg(msg.Topic)
+	m.Data = msg.Value
+	for k, v := range msg.Headers {
+		m.Header.Set(k, v)
+	}
+	_, err := p.js.PublishMsg(ctx, m, jetstream.WithMsgID(msg.Headers[HeaderEventID]))
+	return err
+}
+
+func (p *natsPublisher) Close() error {
+	return p.conn.Drain()
+}
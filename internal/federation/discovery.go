@@ -0,0 +1,79 @@
+package federation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ConfigKeyDomains lists the email domains whose users sign in through an
+// identity provider (home-realm discovery).
+const ConfigKeyDomains = "domains"
+
+// Realm is the home realm of an identity provider: the email domains of the
+// users it signs in.
+type Realm struct {
+	// Issuer is the upstream provider the users are sent to.
+	Issuer string
+	// Domains are lowercase and unique.
+	Domains []string
+}
+
+// RealmFromConfig reads the home realm of an identity provider config. It
+// returns nil for a config without domains and an error for invalid domains
+// or domains on a provider that is not federated.
+func RealmFromConfig(raw []byte) (*Realm, error) {
+	var cfg struct {
+		Issuer  string   `json:"issuer"`
+		JWKSURI string   `json:"jwks_uri"`
+		Domains []string `json:"domains"`
+	}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return nil, fmt.Errorf("invalid home realm domains: %w", err)
+		}
+	}
+	if len(cfg.Domains) == 0 {
+		return nil, nil
+	}
+	if cfg.Issuer == "" || cfg.JWKSURI == "" {
+		return nil, errors.New("home realm domains require an issuer and jwks_uri")
+	}
+
+	realm := &Realm{Issuer: cfg.Issuer}
+	for _, domain := range cfg.Domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if err := validateDomain(domain); err != nil {
+			return nil, err
+		}
+		if !slices.Contains(realm.Domains, domain) {
+			realm.Domains = append(realm.Domains, domain)
+		}
+	}
+	return realm, nil
+}
+
+// Matches reports whether email belongs to one of the realm's domains.
+// Subdomains do not match.
+func (r *Realm) Matches(email string) bool {
+	domain := EmailDomain(email)
+	return domain != "" && slices.Contains(r.Domains, domain)
+}
+
+// EmailDomain returns the lowercase domain of email, or "" when it has none.
+func EmailDomain(email string) string {
+	at := strings.LastIndexByte(email, '@')
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(email[at+1:]))
+}
+
+func validateDomain(domain string) error {
+	if domain == "" || strings.ContainsAny(domain, "@ ") {
+		return fmt.Errorf("domain %q is not a domain", domain)
+	}
+	return nil
+}
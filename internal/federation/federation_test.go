@@ -0,0 +1,394 @@
+package federation
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// Provider
+// ---------------------------------------------------------------------------
+
+func TestProviderFromConfig(t *testing.T) {
+	t.Run("federated provider", func(t *testing.T) {
+		p, err := ProviderFromConfig([]byte(`{"issuer":"https://accounts.example.com","jwks_uri":"https://accounts.example.com/jwks"}`), "client-1")
+		require.NoError(t, err)
+		assert.Equal(t, &Provider{Issuer: "https://accounts.example.com", JWKSURI: "https://accounts.example.com/jwks", ClientID: "client-1"}, p)
+	})
+
+	t.Run("not federated", func(t *testing.T) {
+		for _, raw := range []string{"", `{}`, `{"issuer":"https://accounts.example.com"}`} {
+			_, err := ProviderFromConfig([]byte(raw), "client-1")
+			assert.ErrorIs(t, err, ErrNotFederated, raw)
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := ProviderFromConfig([]byte(`[`), "client-1")
+		assert.Error(t, err)
+	})
+
+	t.Run("no client identifier", func(t *testing.T) {
+		_, err := ProviderFromConfig([]byte(`{"issuer":"https://a.example.com","jwks_uri":"https://a.example.com/jwks"}`), "")
+		assert.Error(t, err)
+	})
+
+	t.Run("plain http", func(t *testing.T) {
+		_, err := ProviderFromConfig([]byte(`{"issuer":"https://a.example.com","jwks_uri":"http://a.example.com/jwks"}`), "client-1")
+		assert.EqualError(t, err, "jwks_uri must be an absolute https URL")
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Verifier
+// ---------------------------------------------------------------------------
+
+// upstream is a fake OpenID Connect provider serving a key set over TLS.
+type upstream struct {
+	server  *httptest.Server
+	rsaKey  *rsa.PrivateKey
+	ecKey   *ecdsa.PrivateKey
+	fetches atomic.Int32
+	status  atomic.Int32
+}
+
+func newUpstream(t *testing.T) *upstream {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	u := &upstream{rsaKey: rsaKey, ecKey: ecKey}
+	u.status.Store(http.StatusOK)
+	u.server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		u.fetches.Add(1)
+		if code := int(u.status.Load()); code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+		b64 := func(i *big.Int) string { return base64.RawURLEncoding.EncodeToString(i.Bytes()) }
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{
+			{"kty": "RSA", "use": "sig", "kid": "rsa-1", "n": b64(rsaKey.N), "e": b64(big.NewInt(int64(rsaKey.E)))},
+			{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": b64(ecKey.X), "y": b64(ecKey.Y)},
+			{"kty": "oct", "kid": "hmac", "k": "c2VjcmV0"},
+		}})
+	}))
+	t.Cleanup(u.server.Close)
+	return u
+}
+
+func (u *upstream) provider() Provider {
+	return Provider{Issuer: "https://accounts.example.com", JWKSURI: u.server.URL + "/jwks", ClientID: "client-1"}
+}
+
+func (u *upstream) sign(t *testing.T, method jwt.SigningMethod, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	var key any = u.rsaKey
+	if _, ok := method.(*jwt.SigningMethodECDSA); ok {
+		key = u.ecKey
+	}
+	raw, err := token.SignedString(key)
+	require.NoError(t, err)
+	return raw
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            "https://accounts.example.com",
+		"aud":            "client-1",
+		"sub":            "upstream-user-1",
+		"email":          "jane@example.com",
+		"email_verified": "true",
+		"name":           "Jane",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	u := newUpstream(t)
+	v := NewVerifier(u.server.Client())
+	ctx := context.Background()
+
+	t.Run("RSA token", func(t *testing.T) {
+		id, err := v.Verify(ctx, u.provider(), u.sign(t, jwt.SigningMethodRS256, "rsa-1", validClaims()))
+		require.NoError(t, err)
+		assert.Equal(t, "upstream-user-1", id.Subject)
+		assert.Equal(t, "jane@example.com", id.Email)
+		assert.True(t, id.EmailVerified)
+		assert.Equal(t, "Jane", id.Name)
+		assert.Equal(t, "client-1", id.Claims["aud"])
+	})
+
+	t.Run("EC token", func(t *testing.T) {
+		_, err := v.Verify(ctx, u.provider(), u.sign(t, jwt.SigningMethodES256, "ec-1", validClaims()))
+		require.NoError(t, err)
+	})
+
+	t.Run("key set is cached", func(t *testing.T) {
+		before := u.fetches.Load()
+		_, err := v.Verify(ctx, u.provider(), u.sign(t, jwt.SigningMethodRS256, "rsa-1", validClaims()))
+		require.NoError(t, err)
+		assert.Equal(t, before, u.fetches.Load())
+	})
+
+	invalid := map[string]func() string{
+		"wrong issuer": func() string {
+			c := validClaims()
+			c["iss"] = "https://evil.example.com"
+			return u.sign(t, jwt.SigningMethodRS256, "rsa-1", c)
+		},
+		"wrong audience": func() string {
+			c := validClaims()
+			c["aud"] = "client-2"
+			return u.sign(t, jwt.SigningMethodRS256, "rsa-1", c)
+		},
+		"expired": func() string {
+			c := validClaims()
+			c["exp"] = time.Now().Add(-time.Hour).Unix()
+			return u.sign(t, jwt.SigningMethodRS256, "rsa-1", c)
+		},
+		"no expiry": func() string {
+			c := validClaims()
+			delete(c, "exp")
+			return u.sign(t, jwt.SigningMethodRS256, "rsa-1", c)
+		},
+		"no subject": func() string {
+			c := validClaims()
+			delete(c, "sub")
+			return u.sign(t, jwt.SigningMethodRS256, "rsa-1", c)
+		},
+		"key of another type": func() string {
+			return u.sign(t, jwt.SigningMethodES256, "rsa-1", validClaims())
+		},
+		"unknown key": func() string {
+			return u.sign(t, jwt.SigningMethodRS256, "rsa-2", validClaims())
+		},
+		"hmac": func() string {
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+			token.Header["kid"] = "hmac"
+			raw, err := token.SignedString([]byte("secret"))
+			require.NoError(t, err)
+			return raw
+		},
+		"garbage": func() string { return "not-a-token" },
+	}
+	for name, token := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := v.Verify(ctx, u.provider(), token())
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+
+	t.Run("invalid provider", func(t *testing.T) {
+		p := u.provider()
+		p.JWKSURI = "http://accounts.example.com/jwks"
+		_, err := v.Verify(ctx, p, u.sign(t, jwt.SigningMethodRS256, "rsa-1", validClaims()))
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, ErrInvalidToken))
+	})
+}
+
+func TestVerifier_KeySetUnavailable(t *testing.T) {
+	u := newUpstream(t)
+	v := NewVerifier(u.server.Client())
+	now := time.Now()
+	v.now = func() time.Time { return now }
+	ctx := context.Background()
+	token := u.sign(t, jwt.SigningMethodRS256, "rsa-1", validClaims())
+
+	t.Run("first fetch fails", func(t *testing.T) {
+		u.status.Store(http.StatusServiceUnavailable)
+		_, err := v.Verify(ctx, u.provider(), token)
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, ErrInvalidToken))
+	})
+
+	t.Run("stale set is used while the provider is down", func(t *testing.T) {
+		u.status.Store(http.StatusOK)
+		_, err := v.Verify(ctx, u.provider(), token)
+		require.NoError(t, err)
+
+		u.status.Store(http.StatusServiceUnavailable)
+		now = now.Add(2 * keySetTTL)
+		_, err = v.Verify(ctx, u.provider(), u.sign(t, jwt.SigningMethodRS256, "rsa-1", jwt.MapClaims{
+			"iss": "https://accounts.example.com", "aud": "client-1", "sub": "s", "exp": now.Add(time.Hour).Unix(),
+		}))
+		require.NoError(t, err)
+	})
+
+	t.Run("unknown keys are refetched at most once a minute", func(t *testing.T) {
+		u.status.Store(http.StatusOK)
+		claims := jwt.MapClaims{"iss": "https://accounts.example.com", "aud": "client-1", "sub": "s", "exp": now.Add(time.Hour).Unix()}
+		_, err := v.Verify(ctx, u.provider(), u.sign(t, jwt.SigningMethodRS256, "rsa-9", claims))
+		require.ErrorIs(t, err, ErrInvalidToken)
+
+		before := u.fetches.Load()
+		_, err = v.Verify(ctx, u.provider(), u.sign(t, jwt.SigningMethodRS256, "rsa-9", claims))
+		require.ErrorIs(t, err, ErrInvalidToken)
+		assert.Equal(t, before, u.fetches.Load())
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Provisioning
+// ---------------------------------------------------------------------------
+
+func TestRulesFromConfig(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		for _, raw := range []string{"", `{}`, `{"issuer":"https://a.example.com"}`} {
+			rules, err := RulesFromConfig([]byte(raw))
+			require.NoError(t, err, raw)
+			assert.Equal(t, ProvisioningModeExistingOnly, rules.Mode)
+			assert.Equal(t, AttributeMapping{Email: "email", Name: "name", Groups: "groups"}, rules.AttributeMapping)
+		}
+	})
+
+	t.Run("configured rules", func(t *testing.T) {
+		rules, err := RulesFromConfig([]byte(`{"provisioning":{
+			"mode":"auto_create",
+			"allowed_domains":[" Example.COM "],
+			"attribute_mapping":{"email":"upn","groups":"roles"},
+			"group_roles":{"admins":["admin"]}
+		}}`))
+		require.NoError(t, err)
+		assert.Equal(t, ProvisioningModeAutoCreate, rules.Mode)
+		assert.Equal(t, []string{"example.com"}, rules.AllowedDomains)
+		assert.Equal(t, AttributeMapping{Email: "upn", Name: "name", Groups: "roles"}, rules.AttributeMapping)
+		assert.Equal(t, map[string][]string{"admins": {"admin"}}, rules.GroupRoles)
+	})
+
+	invalid := map[string]string{
+		"not json":        `{"provisioning":[]}`,
+		"unknown mode":    `{"provisioning":{"mode":"always"}}`,
+		"email as domain": `{"provisioning":{"allowed_domains":["a@example.com"]}}`,
+		"empty domain":    `{"provisioning":{"allowed_domains":[""]}}`,
+		"empty group":     `{"provisioning":{"group_roles":{"":["admin"]}}}`,
+		"empty role":      `{"provisioning":{"group_roles":{"admins":[""]}}}`,
+	}
+	for name, raw := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := RulesFromConfig([]byte(raw))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestProvisioningRules_Evaluate(t *testing.T) {
+	identity := func(claims map[string]any) *Identity {
+		email, _ := claims["email"].(string)
+		return &Identity{Subject: "s", Email: email, EmailVerified: claims["email_verified"] == true, Claims: claims}
+	}
+
+	t.Run("standard claims", func(t *testing.T) {
+		rules, err := RulesFromConfig([]byte(`{"provisioning":{"group_roles":{"admins":["admin","editor"],"staff":["editor"]}}}`))
+		require.NoError(t, err)
+		attrs, err := rules.Evaluate(identity(map[string]any{
+			"email": "jane@example.com", "email_verified": true, "name": "Jane",
+			"groups": []any{"staff", "admins", "other"},
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, &Attributes{
+			Email:         "jane@example.com",
+			EmailVerified: true,
+			Name:          "Jane",
+			Groups:        []string{"staff", "admins", "other"},
+			Roles:         []string{"editor", "admin"},
+		}, attrs)
+	})
+
+	t.Run("mapped claims", func(t *testing.T) {
+		rules, err := RulesFromConfig([]byte(`{"provisioning":{"attribute_mapping":{"email":"upn","name":"display_name","groups":"role"},"group_roles":{"staff":["editor"]}}}`))
+		require.NoError(t, err)
+		attrs, err := rules.Evaluate(identity(map[string]any{
+			"email": "other@example.com", "email_verified": true,
+			"upn": "jane@corp.example.com", "display_name": "Jane", "role": "staff",
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, "jane@corp.example.com", attrs.Email)
+		assert.False(t, attrs.EmailVerified, "a mapped email carries no verification flag")
+		assert.Equal(t, "Jane", attrs.Name)
+		assert.Equal(t, []string{"editor"}, attrs.Roles)
+	})
+
+	t.Run("domain allowlist", func(t *testing.T) {
+		rules, err := RulesFromConfig([]byte(`{"provisioning":{"allowed_domains":["example.com"]}}`))
+		require.NoError(t, err)
+		_, err = rules.Evaluate(identity(map[string]any{"email": "jane@EXAMPLE.com"}))
+		assert.NoError(t, err)
+		_, err = rules.Evaluate(identity(map[string]any{"email": "jane@sub.example.com"}))
+		assert.ErrorIs(t, err, ErrDomainNotAllowed)
+		_, err = rules.Evaluate(identity(map[string]any{"email": "jane"}))
+		assert.ErrorIs(t, err, ErrDomainNotAllowed)
+	})
+
+	t.Run("no email", func(t *testing.T) {
+		rules, err := RulesFromConfig(nil)
+		require.NoError(t, err)
+		_, err = rules.Evaluate(identity(map[string]any{"name": "Jane"}))
+		assert.ErrorIs(t, err, ErrEmailMissing)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Home-realm discovery
+// ---------------------------------------------------------------------------
+
+func TestRealmFromConfig(t *testing.T) {
+	t.Run("no domains", func(t *testing.T) {
+		for _, raw := range []string{"", `{}`, `{"issuer":"https://a.example.com","domains":[]}`} {
+			realm, err := RealmFromConfig([]byte(raw))
+			require.NoError(t, err, raw)
+			assert.Nil(t, realm, raw)
+		}
+	})
+
+	t.Run("configured domains", func(t *testing.T) {
+		realm, err := RealmFromConfig([]byte(`{
+			"issuer":"https://a.example.com","jwks_uri":"https://a.example.com/keys",
+			"domains":[" Example.COM ","example.com","corp.example.com"]
+		}`))
+		require.NoError(t, err)
+		assert.Equal(t, &Realm{Issuer: "https://a.example.com", Domains: []string{"example.com", "corp.example.com"}}, realm)
+	})
+
+	invalid := map[string]string{
+		"not json":        `{"domains":"example.com"}`,
+		"not federated":   `{"domains":["example.com"]}`,
+		"email as domain": `{"issuer":"https://a.example.com","jwks_uri":"https://a.example.com/keys","domains":["a@example.com"]}`,
+		"empty domain":    `{"issuer":"https://a.example.com","jwks_uri":"https://a.example.com/keys","domains":[" "]}`,
+	}
+	for name, raw := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := RealmFromConfig([]byte(raw))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRealm_Matches(t *testing.T) {
+	realm := &Realm{Domains: []string{"example.com"}}
+	assert.True(t, realm.Matches("jane@example.com"))
+	assert.True(t, realm.Matches("Jane@EXAMPLE.com"))
+	assert.False(t, realm.Matches("jane@sub.example.com"))
+	assert.False(t, realm.Matches("jane@example.org"))
+	assert.False(t, realm.Matches("jane"))
+}
@@ -0,0 +1,77 @@
+// Package federation verifies identities asserted by upstream OpenID Connect
+// providers. An identity provider opts in through the "issuer" and
+// "jwks_uri" keys of its config; the audience is the identifier of the auth
+// client registered under it. ID tokens are checked against the provider's
+// published signing keys, which are cached per JWKS URI. The "provisioning"
+// key holds the rules applied on the first sign in of an upstream account,
+// and the "domains" key the email domains routed to the provider by
+// home-realm discovery.
+package federation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// Config keys read from an identity provider's config.
+const (
+	ConfigKeyIssuer  = "issuer"
+	ConfigKeyJWKSURI = "jwks_uri"
+)
+
+// ErrNotFederated is returned for an identity provider without an issuer
+// and JWKS URI.
+var ErrNotFederated = errors.New("identity provider is not an OpenID Connect provider")
+
+// Provider is an upstream OpenID Connect provider as seen by one auth client.
+type Provider struct {
+	// Issuer must equal the iss claim of its ID tokens.
+	Issuer string
+	// JWKSURI serves the keys its ID tokens are signed with.
+	JWKSURI string
+	// ClientID is the client the ID tokens must be issued to.
+	ClientID string
+}
+
+// ProviderFromConfig builds the Provider of an identity provider config for
+// the auth client identified by clientID. It returns ErrNotFederated when the
+// config has no issuer or JWKS URI.
+func ProviderFromConfig(raw []byte, clientID string) (*Provider, error) {
+	var cfg map[string]any
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return nil, fmt.Errorf("invalid identity provider config: %w", err)
+		}
+	}
+	issuer, _ := cfg[ConfigKeyIssuer].(string)
+	jwksURI, _ := cfg[ConfigKeyJWKSURI].(string)
+	if issuer == "" || jwksURI == "" {
+		return nil, ErrNotFederated
+	}
+	if clientID == "" {
+		return nil, errors.New("auth client has no identifier")
+	}
+
+	p := &Provider{Issuer: issuer, JWKSURI: jwksURI, ClientID: clientID}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate checks that the issuer and JWKS URI are absolute HTTPS URLs.
+func (p Provider) Validate() error {
+	fields := []struct{ key, value string }{
+		{ConfigKeyIssuer, p.Issuer},
+		{ConfigKeyJWKSURI, p.JWKSURI},
+	}
+	for _, f := range fields {
+		u, err := url.Parse(f.value)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("%s must be an absolute https URL", f.key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package federation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ConfigKeyProvisioning holds the just-in-time provisioning rules of an
+// identity provider's config.
+const ConfigKeyProvisioning = "provisioning"
+
+// Provisioning modes, deciding what happens on the first sign in of an
+// upstream account that is not linked yet.
+const (
+	// ProvisioningModeExistingOnly links the upstream account to the local
+	// account with the same verified email and rejects it when there is none.
+	ProvisioningModeExistingOnly = "existing_only"
+	// ProvisioningModeAutoCreate also creates a local account when there is
+	// none.
+	ProvisioningModeAutoCreate = "auto_create"
+)
+
+// Default claims read by the attribute mapping.
+const (
+	defaultEmailClaim  = "email"
+	defaultNameClaim   = "name"
+	defaultGroupsClaim = "groups"
+)
+
+var (
+	// ErrDomainNotAllowed is returned for an email outside the allowed
+	// domains of the provider.
+	ErrDomainNotAllowed = errors.New("email domain is not allowed")
+	// ErrEmailMissing is returned when the mapped email claim is empty.
+	ErrEmailMissing = errors.New("identity has no email")
+)
+
+// AttributeMapping names the ID token claims local attributes are read from.
+// Empty names fall back to the standard claims.
+type AttributeMapping struct {
+	Email  string `json:"email,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Groups string `json:"groups,omitempty"`
+}
+
+// ProvisioningRules are the just-in-time provisioning rules of an identity
+// provider.
+type ProvisioningRules struct {
+	Mode             string              `json:"mode"`
+	AllowedDomains   []string            `json:"allowed_domains,omitempty"`
+	AttributeMapping AttributeMapping    `json:"attribute_mapping"`
+	GroupRoles       map[string][]string `json:"group_roles,omitempty"`
+}
+
+// Attributes are the local attributes of an upstream identity after the
+// rules were applied.
+type Attributes struct {
+	Email         string
+	EmailVerified bool
+	Name          string
+	Groups        []string
+	// Roles are the names of the tenant roles the groups map to, in the
+	// order of the groups and without duplicates.
+	Roles []string
+}
+
+// RulesFromConfig reads the provisioning rules of an identity provider
+// config. A config without rules gets the defaults: existing accounts only,
+// any domain and the standard claims.
+func RulesFromConfig(raw []byte) (*ProvisioningRules, error) {
+	var cfg struct {
+		Provisioning *ProvisioningRules `json:"provisioning"`
+	}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return nil, fmt.Errorf("invalid provisioning rules: %w", err)
+		}
+	}
+
+	rules := cfg.Provisioning
+	if rules == nil {
+		rules = &ProvisioningRules{}
+	}
+	if rules.Mode == "" {
+		rules.Mode = ProvisioningModeExistingOnly
+	}
+	if rules.AttributeMapping.Email == "" {
+		rules.AttributeMapping.Email = defaultEmailClaim
+	}
+	if rules.AttributeMapping.Name == "" {
+		rules.AttributeMapping.Name = defaultNameClaim
+	}
+	if rules.AttributeMapping.Groups == "" {
+		rules.AttributeMapping.Groups = defaultGroupsClaim
+	}
+	for i, domain := range rules.AllowedDomains {
+		rules.AllowedDomains[i] = strings.ToLower(strings.TrimSpace(domain))
+	}
+
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Validate checks the mode, the allowed domains and the group role mappings.
+func (r *ProvisioningRules) Validate() error {
+	if r.Mode != ProvisioningModeExistingOnly && r.Mode != ProvisioningModeAutoCreate {
+		return fmt.Errorf("provisioning mode must be %s or %s", ProvisioningModeExistingOnly, ProvisioningModeAutoCreate)
+	}
+	for _, domain := range r.AllowedDomains {
+		if err := validateDomain(domain); err != nil {
+			return fmt.Errorf("allowed %w", err)
+		}
+	}
+	for group, roles := range r.GroupRoles {
+		if group == "" {
+			return errors.New("group role mappings need a group name")
+		}
+		if slices.Contains(roles, "") {
+			return fmt.Errorf("roles of group %q must not be empty", group)
+		}
+	}
+	return nil
+}
+
+// Evaluate maps an upstream identity to local attributes. It returns
+// ErrEmailMissing when the identity has no email and ErrDomainNotAllowed when
+// its domain is not allowed.
+func (r *ProvisioningRules) Evaluate(id *Identity) (*Attributes, error) {
+	email := strings.TrimSpace(stringClaim(id.Claims, r.AttributeMapping.Email))
+	if email == "" {
+		return nil, ErrEmailMissing
+	}
+	if len(r.AllowedDomains) > 0 && !slices.Contains(r.AllowedDomains, EmailDomain(email)) {
+		return nil, ErrDomainNotAllowed
+	}
+
+	attrs := &Attributes{
+		Email:  email,
+		Name:   stringClaim(id.Claims, r.AttributeMapping.Name),
+		Groups: stringsClaim(id.Claims, r.AttributeMapping.Groups),
+	}
+	// Only the standard email claim comes with a verification flag
+	if r.AttributeMapping.Email == defaultEmailClaim {
+		attrs.EmailVerified = id.EmailVerified
+	}
+	for _, group := range attrs.Groups {
+		for _, role := range r.GroupRoles[group] {
+			if !slices.Contains(attrs.Roles, role) {
+				attrs.Roles = append(attrs.Roles, role)
+			}
+		}
+	}
+	return attrs, nil
+}
+
+func stringClaim(claims map[string]any, name string) string {
+	s, _ := claims[name].(string)
+	return s
+}
+
+// stringsClaim reads a claim holding a list of strings or a single string.
+func stringsClaim(claims map[string]any, name string) []string {
+	switch v := claims[name].(type) {
+	case string:
+		if v != "" {
+			return []string{v}
+		}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
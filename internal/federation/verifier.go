@@ -0,0 +1,329 @@
+package federation
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWKS fetching bounds. Keys are refetched after keySetTTL, or early when a
+// token names an unknown key, but never more often than refreshInterval.
+const (
+	fetchTimeout     = 5 * time.Second
+	keySetTTL        = time.Hour
+	refreshInterval  = time.Minute
+	maxKeySetBytes   = 256 << 10
+	clockSkewLeeway  = time.Minute
+	maxCachedKeySets = 1000
+)
+
+// ErrInvalidToken is returned for an ID token that is malformed, expired,
+// wrongly signed, or not issued by the provider to its client.
+var ErrInvalidToken = errors.New("invalid upstream ID token")
+
+// Identity is the user an upstream provider vouched for.
+type Identity struct {
+	// Subject is the provider's stable identifier of the user.
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	// Claims holds every claim of the ID token.
+	Claims map[string]any
+}
+
+// IDTokenVerifier verifies ID tokens of upstream providers.
+type IDTokenVerifier interface {
+	// Verify checks rawIDToken against p and returns the identity it
+	// asserts. Token problems are reported as ErrInvalidToken; other errors
+	// mean the provider's keys could not be loaded.
+	Verify(ctx context.Context, p Provider, rawIDToken string) (*Identity, error)
+}
+
+// Verifier is the default IDTokenVerifier. It is safe for concurrent use.
+type Verifier struct {
+	client *http.Client
+	now    func() time.Time
+
+	mu       sync.Mutex
+	keySets  map[string]*keySet
+	inflight map[string]*sync.Mutex
+}
+
+type keySet struct {
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// NewVerifier creates a Verifier using client to fetch key sets. A nil
+// client uses a default client.
+func NewVerifier(client *http.Client) *Verifier {
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &Verifier{
+		client:   client,
+		now:      time.Now,
+		keySets:  make(map[string]*keySet),
+		inflight: make(map[string]*sync.Mutex),
+	}
+}
+
+// Verify implements IDTokenVerifier.
+func (v *Verifier) Verify(ctx context.Context, p Provider, rawIDToken string) (*Identity, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	var keyErr error
+	keyFunc := func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := v.key(ctx, p.JWKSURI, kid)
+		if err != nil {
+			keyErr = err
+		}
+		return key, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, keyFunc,
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(p.Issuer),
+		jwt.WithAudience(p.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockSkewLeeway),
+		jwt.WithTimeFunc(v.now),
+	)
+	// Only a key set that could not be loaded is not the token's fault
+	if keyErr != nil && !errors.Is(keyErr, ErrInvalidToken) {
+		return nil, keyErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrInvalidToken)
+	}
+	email, _ := claims["email"].(string)
+	name, _ := claims["name"].(string)
+	// Some providers send email_verified as a string
+	verified := claims["email_verified"] == true || claims["email_verified"] == "true"
+
+	return &Identity{
+		Subject:       sub,
+		Email:         email,
+		EmailVerified: verified,
+		Name:          name,
+		Claims:        claims,
+	}, nil
+}
+
+// key returns the key kid of the key set at jwksURI, refetching the set
+// when it is stale or does not have the key.
+func (v *Verifier) key(ctx context.Context, jwksURI, kid string) (crypto.PublicKey, error) {
+	if key, ok := v.cachedKey(jwksURI, kid, false); ok {
+		return key, nil
+	}
+
+	// One fetch per key set at a time; waiters see its result
+	lock := v.fetchLock(jwksURI)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if key, ok := v.cachedKey(jwksURI, kid, false); ok {
+		return key, nil
+	}
+	if v.recentlyFetched(jwksURI) {
+		if key, ok := v.cachedKey(jwksURI, kid, true); ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidToken, kid)
+	}
+
+	keys, err := v.fetch(ctx, jwksURI)
+	if err != nil {
+		// A stale set still verifies tokens while the provider is down
+		if key, ok := v.cachedKey(jwksURI, kid, true); ok {
+			return key, nil
+		}
+		return nil, err
+	}
+	v.store(jwksURI, keys)
+
+	if key, ok := v.cachedKey(jwksURI, kid, true); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidToken, kid)
+}
+
+// cachedKey looks kid up in the cached set of jwksURI. A stale set is only
+// used when allowStale is set. An empty kid matches the only key of a set.
+func (v *Verifier) cachedKey(jwksURI, kid string, allowStale bool) (crypto.PublicKey, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	set := v.keySets[jwksURI]
+	if set == nil || (!allowStale && !v.now().Before(set.fetchedAt.Add(keySetTTL))) {
+		return nil, false
+	}
+	if kid == "" && len(set.keys) == 1 {
+		for _, key := range set.keys {
+			return key, true
+		}
+	}
+	key, ok := set.keys[kid]
+	return key, ok
+}
+
+func (v *Verifier) recentlyFetched(jwksURI string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	set := v.keySets[jwksURI]
+	return set != nil && v.now().Before(set.fetchedAt.Add(refreshInterval))
+}
+
+func (v *Verifier) fetchLock(jwksURI string) *sync.Mutex {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	lock := v.inflight[jwksURI]
+	if lock == nil {
+		lock = &sync.Mutex{}
+		v.inflight[jwksURI] = lock
+	}
+	return lock
+}
+
+func (v *Verifier) store(jwksURI string, keys map[string]crypto.PublicKey) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if _, ok := v.keySets[jwksURI]; !ok && len(v.keySets) >= maxCachedKeySets {
+		// Full of other providers' keys: start over rather than grow
+		// without bound.
+		v.keySets = make(map[string]*keySet)
+	}
+	v.keySets[jwksURI] = &keySet{keys: keys, fetchedAt: v.now()}
+}
+
+// jwk is a JSON Web Key (RFC 7517) of an RSA or EC public key.
+type jwk struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (v *Verifier) fetch(ctx context.Context, jwksURI string) (map[string]crypto.PublicKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch key set: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch key set: status %d", res.StatusCode)
+	}
+
+	var body struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxKeySetBytes)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid key set: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(body.Keys))
+	for _, k := range body.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		// Keys of unsupported types are skipped, not fatal
+		if key, err := k.publicKey(); err == nil {
+			keys[k.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		// ECDH rejects points that are not on the curve
+		if _, err := key.ECDH(); err != nil {
+			return nil, err
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,102 @@
+package geoip
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/maintainerd/auth/internal/security"
+)
+
+// cacheEntry is a cached lookup. A nil location records that the address is
+// unknown, so it is not looked up again either.
+type cacheEntry struct {
+	location  *security.GeoLocation
+	expiresAt time.Time
+}
+
+// Cache remembers recent lookups of another GeoLocator. Most requests come
+// from addresses seen moments before, and each lookup walks the database
+// tree. Failed lookups are not cached.
+type Cache struct {
+	locator security.GeoLocator
+	size    int
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// NewCache wraps locator with a cache of at most size lookups, each reused
+// for ttl. A size or ttl of zero returns locator itself.
+func NewCache(locator security.GeoLocator, size int, ttl time.Duration) security.GeoLocator {
+	if size <= 0 || ttl <= 0 {
+		return locator
+	}
+	return &Cache{
+		locator: locator,
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cacheEntry, size),
+	}
+}
+
+// Locate returns the cached location of ip, looking it up on a miss.
+func (c *Cache) Locate(ctx context.Context, ip string) (*security.GeoLocation, error) {
+	if location, ok := c.cached(ip); ok {
+		return location, nil
+	}
+	location, err := c.locator.Locate(ctx, ip)
+	if err != nil {
+		return nil, err
+	}
+	c.store(ip, location)
+	return location, nil
+}
+
+func (c *Cache) cached(ip string) (*security.GeoLocation, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[ip]
+	if !ok {
+		return nil, false
+	}
+	if c.now().After(entry.expiresAt) {
+		delete(c.entries, ip)
+		return nil, false
+	}
+	return copyLocation(entry.location), true
+}
+
+func (c *Cache) store(ip string, location *security.GeoLocation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.entries) >= c.size {
+		for k, v := range c.entries {
+			if now.After(v.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		// Still full of live entries: drop an arbitrary one rather than
+		// tracking recency on every hit
+		if len(c.entries) >= c.size {
+			for k := range c.entries {
+				delete(c.entries, k)
+				break
+			}
+		}
+	}
+	c.entries[ip] = cacheEntry{location: copyLocation(location), expiresAt: now.Add(c.ttl)}
+}
+
+// copyLocation keeps callers from changing cached entries.
+func copyLocation(location *security.GeoLocation) *security.GeoLocation {
+	if location == nil {
+		return nil
+	}
+	cp := *location
+	return &cp
+}
@@ -0,0 +1,106 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maintainerd/auth/internal/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingLocator struct {
+	calls     map[string]int
+	locations map[string]*security.GeoLocation
+	err       error
+}
+
+func (l *countingLocator) Locate(_ context.Context, ip string) (*security.GeoLocation, error) {
+	if l.calls == nil {
+		l.calls = map[string]int{}
+	}
+	l.calls[ip]++
+	if l.err != nil {
+		return nil, l.err
+	}
+	return l.locations[ip], nil
+}
+
+func TestNewCache_Disabled(t *testing.T) {
+	locator := &countingLocator{}
+	assert.Same(t, locator, NewCache(locator, 0, time.Hour))
+	assert.Same(t, locator, NewCache(locator, 10, 0))
+}
+
+func TestCache_Locate(t *testing.T) {
+	ctx := context.Background()
+	london := &security.GeoLocation{Country: "GB", City: "London"}
+
+	t.Run("reuses lookups until they expire", func(t *testing.T) {
+		locator := &countingLocator{locations: map[string]*security.GeoLocation{knownIP: london}}
+		cache := NewCache(locator, 10, time.Minute).(*Cache)
+		now := time.Now()
+		cache.now = func() time.Time { return now }
+
+		for range 3 {
+			location, err := cache.Locate(ctx, knownIP)
+			require.NoError(t, err)
+			assert.Equal(t, london, location)
+		}
+		assert.Equal(t, 1, locator.calls[knownIP])
+
+		now = now.Add(2 * time.Minute)
+		_, err := cache.Locate(ctx, knownIP)
+		require.NoError(t, err)
+		assert.Equal(t, 2, locator.calls[knownIP])
+	})
+
+	t.Run("caches unknown addresses", func(t *testing.T) {
+		locator := &countingLocator{}
+		cache := NewCache(locator, 10, time.Minute)
+
+		for range 2 {
+			location, err := cache.Locate(ctx, unknownIP)
+			require.NoError(t, err)
+			assert.Nil(t, location)
+		}
+		assert.Equal(t, 1, locator.calls[unknownIP])
+	})
+
+	t.Run("does not cache failures", func(t *testing.T) {
+		locator := &countingLocator{err: errors.New("database closed")}
+		cache := NewCache(locator, 10, time.Minute)
+
+		for range 2 {
+			_, err := cache.Locate(ctx, knownIP)
+			assert.Error(t, err)
+		}
+		assert.Equal(t, 2, locator.calls[knownIP])
+	})
+
+	t.Run("stays within its size", func(t *testing.T) {
+		locator := &countingLocator{}
+		cache := NewCache(locator, 2, time.Minute).(*Cache)
+
+		for _, ip := range []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"} {
+			_, err := cache.Locate(ctx, ip)
+			require.NoError(t, err)
+		}
+		assert.Len(t, cache.entries, 2)
+	})
+
+	t.Run("callers cannot change cached entries", func(t *testing.T) {
+		locator := &countingLocator{locations: map[string]*security.GeoLocation{knownIP: london}}
+		cache := NewCache(locator, 10, time.Minute)
+
+		first, err := cache.Locate(ctx, knownIP)
+		require.NoError(t, err)
+		first.City = "Paris"
+
+		second, err := cache.Locate(ctx, knownIP)
+		require.NoError(t, err)
+		assert.Equal(t, "London", second.City)
+	})
+}
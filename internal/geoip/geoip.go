@@ -0,0 +1,117 @@
+// Package geoip resolves IP addresses to locations and networks from MaxMind
+// databases (GeoLite2 or GeoIP2, City or Country, plus the optional ASN
+// database). It implements security.GeoLocator, which enriches auth events,
+// security events and login records.
+package geoip
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/maintainerd/auth/internal/security"
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// cityRecord holds the fields read from a City or Country database. Country
+// databases have no city or location, which then stay empty.
+type cityRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Location struct {
+		Latitude  float64 `maxminddb:"latitude"`
+		Longitude float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+}
+
+// asnRecord holds the fields read from an ASN database.
+type asnRecord struct {
+	Number       uint   `maxminddb:"autonomous_system_number"`
+	Organization string `maxminddb:"autonomous_system_organization"`
+}
+
+// Reader looks addresses up in a City or Country database and, when one is
+// opened, an ASN database. It is safe for concurrent use.
+type Reader struct {
+	city *maxminddb.Reader
+	asn  *maxminddb.Reader
+}
+
+// Open opens the databases at cityPath and asnPath. asnPath may be empty,
+// in which case locations carry no network.
+func Open(cityPath, asnPath string) (*Reader, error) {
+	city, err := maxminddb.Open(cityPath)
+	if err != nil {
+		return nil, fmt.Errorf("open geoip database: %w", err)
+	}
+	r := &Reader{city: city}
+	if asnPath != "" {
+		if r.asn, err = maxminddb.Open(asnPath); err != nil {
+			_ = city.Close()
+			return nil, fmt.Errorf("open geoip asn database: %w", err)
+		}
+	}
+	return r, nil
+}
+
+// Locate returns where ip is. It returns nil for addresses that are not
+// public or not in any database, and for values that are not IP addresses.
+func (r *Reader) Locate(_ context.Context, ip string) (*security.GeoLocation, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil || !isPublic(addr) {
+		return nil, nil
+	}
+
+	var location security.GeoLocation
+	var record cityRecord
+	_, found, err := r.city.LookupNetwork(addr, &record)
+	if err != nil {
+		return nil, fmt.Errorf("geoip lookup: %w", err)
+	}
+	if found {
+		location.Country = record.Country.ISOCode
+		location.City = record.City.Names["en"]
+		location.Latitude = record.Location.Latitude
+		location.Longitude = record.Location.Longitude
+	}
+
+	if r.asn != nil {
+		var asn asnRecord
+		_, asnFound, err := r.asn.LookupNetwork(addr, &asn)
+		if err != nil {
+			return nil, fmt.Errorf("geoip asn lookup: %w", err)
+		}
+		if asnFound {
+			location.ASN = asn.Number
+			location.ASOrganization = asn.Organization
+			found = true
+		}
+	}
+
+	if !found {
+		return nil, nil
+	}
+	return &location, nil
+}
+
+// Close releases the databases.
+func (r *Reader) Close() error {
+	err := r.city.Close()
+	if r.asn != nil {
+		if asnErr := r.asn.Close(); err == nil {
+			err = asnErr
+		}
+	}
+	return err
+}
+
+// isPublic reports whether addr can be located. Private, loopback and
+// link-local addresses are never in the databases.
+func isPublic(addr net.IP) bool {
+	return !addr.IsPrivate() && !addr.IsLoopback() && !addr.IsLinkLocalUnicast() &&
+		!addr.IsUnspecified() && !addr.IsMulticast()
+}
@@ -0,0 +1,164 @@
+package geoip
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Addresses in the first half of the IPv4 space are found in test databases,
+// those in the second half are not.
+const (
+	knownIP   = "81.2.69.142"
+	unknownIP = "175.16.199.1"
+)
+
+// writeTestDB writes an IPv4 MaxMind DB with a single node: addresses whose
+// first bit is 0 map to record, all others are not found.
+func writeTestDB(t *testing.T, record map[string]any) string {
+	t.Helper()
+	const nodeCount = 1
+
+	var db bytes.Buffer
+	// Node 0 in 24-bit records. A record above the node count points into
+	// the data section, offset by the node count and the 16-byte separator.
+	db.Write([]byte{0, 0, nodeCount + 16, 0, 0, nodeCount})
+	db.Write(make([]byte, 16))
+	encode(&db, record)
+	db.WriteString("\xAB\xCD\xEFMaxMind.com")
+	encode(&db, map[string]any{
+		"node_count":  uint(nodeCount),
+		"record_size": uint(24),
+		"ip_version":  uint(4),
+	})
+
+	path := filepath.Join(t.TempDir(), "test.mmdb")
+	require.NoError(t, os.WriteFile(path, db.Bytes(), 0o600))
+	return path
+}
+
+// encode writes v in the MaxMind DB data format. Only the types the tests
+// need are supported.
+func encode(buf *bytes.Buffer, v any) {
+	switch v := v.(type) {
+	case string:
+		if len(v) < 29 {
+			buf.WriteByte(2<<5 | byte(len(v)))
+		} else {
+			buf.Write([]byte{2<<5 | 29, byte(len(v) - 29)})
+		}
+		buf.WriteString(v)
+	case float64:
+		buf.WriteByte(3<<5 | 8)
+		_ = binary.Write(buf, binary.BigEndian, math.Float64bits(v))
+	case uint:
+		buf.WriteByte(6<<5 | 4)
+		_ = binary.Write(buf, binary.BigEndian, uint32(v))
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf.WriteByte(7<<5 | byte(len(v)))
+		for _, k := range keys {
+			encode(buf, k)
+			encode(buf, v[k])
+		}
+	default:
+		panic("unsupported type")
+	}
+}
+
+func cityDB(t *testing.T) string {
+	return writeTestDB(t, map[string]any{
+		"country":  map[string]any{"iso_code": "GB"},
+		"city":     map[string]any{"names": map[string]any{"en": "London"}},
+		"location": map[string]any{"latitude": 51.5142, "longitude": -0.0931},
+	})
+}
+
+func asnDB(t *testing.T) string {
+	return writeTestDB(t, map[string]any{
+		"autonomous_system_number":       uint(20712),
+		"autonomous_system_organization": "Andrews & Arnold Ltd",
+	})
+}
+
+func TestReader_Locate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("city and asn", func(t *testing.T) {
+		r, err := Open(cityDB(t), asnDB(t))
+		require.NoError(t, err)
+		defer r.Close()
+
+		location, err := r.Locate(ctx, knownIP)
+		require.NoError(t, err)
+		assert.Equal(t, &security.GeoLocation{
+			Country:        "GB",
+			City:           "London",
+			Latitude:       51.5142,
+			Longitude:      -0.0931,
+			ASN:            20712,
+			ASOrganization: "Andrews & Arnold Ltd",
+		}, location)
+	})
+
+	t.Run("without asn database", func(t *testing.T) {
+		r, err := Open(cityDB(t), "")
+		require.NoError(t, err)
+		defer r.Close()
+
+		location, err := r.Locate(ctx, knownIP)
+		require.NoError(t, err)
+		require.NotNil(t, location)
+		assert.Equal(t, "London", location.City)
+		assert.Zero(t, location.ASN)
+	})
+
+	t.Run("country database", func(t *testing.T) {
+		r, err := Open(writeTestDB(t, map[string]any{"country": map[string]any{"iso_code": "GB"}}), "")
+		require.NoError(t, err)
+		defer r.Close()
+
+		location, err := r.Locate(ctx, knownIP)
+		require.NoError(t, err)
+		require.NotNil(t, location)
+		assert.Equal(t, "GB", location.Country)
+		assert.False(t, location.HasCoordinates())
+	})
+
+	t.Run("unknown addresses", func(t *testing.T) {
+		r, err := Open(cityDB(t), asnDB(t))
+		require.NoError(t, err)
+		defer r.Close()
+
+		for _, ip := range []string{unknownIP, "10.0.0.1", "127.0.0.1", "", "not-an-ip"} {
+			location, err := r.Locate(ctx, ip)
+			assert.NoError(t, err, ip)
+			assert.Nil(t, location, ip)
+		}
+	})
+}
+
+func TestOpen(t *testing.T) {
+	t.Run("missing database", func(t *testing.T) {
+		_, err := Open(filepath.Join(t.TempDir(), "missing.mmdb"), "")
+		assert.ErrorContains(t, err, "open geoip database")
+	})
+
+	t.Run("missing asn database", func(t *testing.T) {
+		_, err := Open(cityDB(t), filepath.Join(t.TempDir(), "missing.mmdb"))
+		assert.ErrorContains(t, err, "open geoip asn database")
+	})
+}
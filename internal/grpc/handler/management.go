@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	authv1 "github.com/maintainerd/auth/internal/gen/go/maintainerd/auth"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/datatypes"
+)
+
+// Defaults for a list RPC whose PageRequest leaves page or limit unset.
+const (
+	defaultPage  = 1
+	defaultLimit = 10
+)
+
+// callerFromContext returns the caller resolved by GRPCAuthInterceptor. Like
+// the REST handlers, every management RPC is scoped to the caller's tenant.
+func callerFromContext(ctx context.Context) (*model.Tenant, *model.User, error) {
+	auth := middleware.AuthFromContext(ctx)
+	if auth.Tenant == nil {
+		return nil, nil, status.Error(codes.Unauthenticated, "tenant not found in context")
+	}
+	if auth.User == nil {
+		return nil, nil, status.Error(codes.Unauthenticated, "user not found in context")
+	}
+	return auth.Tenant, auth.User, nil
+}
+
+// parseID parses a UUID request field.
+func parseID(value, field string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "%s must be a valid UUID", field)
+	}
+	return id, nil
+}
+
+// parseIDs parses a repeated UUID request field.
+func parseIDs(values []string, field string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, len(values))
+	for i, v := range values {
+		id, err := parseID(v, field)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// invalidArgument reports a failed DTO validation.
+func invalidArgument(err error) error {
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+// toPaginationDTO converts a PageRequest, applying the default page and
+// limit REST clients have to send explicitly.
+func toPaginationDTO(p *authv1.PageRequest) dto.PaginationRequestDTO {
+	out := dto.PaginationRequestDTO{
+		Page:      int(p.GetPage()),
+		Limit:     int(p.GetLimit()),
+		SortBy:    p.GetSortBy(),
+		SortOrder: p.GetSortOrder(),
+	}
+	if out.Page == 0 {
+		out.Page = defaultPage
+	}
+	if out.Limit == 0 {
+		out.Limit = defaultLimit
+	}
+	return out
+}
+
+func toPageInfo(total int64, page, limit, totalPages int) *authv1.PageInfo {
+	return &authv1.PageInfo{
+		Total:      total,
+		Page:       int32(page),
+		Limit:      int32(limit),
+		TotalPages: int32(totalPages),
+	}
+}
+
+// toTimestamp converts t, leaving the zero time unset.
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// toStruct converts JSON object metadata. Metadata that is not a JSON
+// object is left out.
+func toStruct(data datatypes.JSON) *structpb.Struct {
+	if len(data) == 0 {
+		return nil
+	}
+	out := &structpb.Struct{}
+	if err := protojson.Unmarshal(data, out); err != nil {
+		return nil
+	}
+	return out
+}
+
+// fromStruct converts request metadata to JSON; nil stays nil.
+func fromStruct(s *structpb.Struct) (datatypes.JSON, error) {
+	if s == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(s.AsMap())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "metadata is not valid JSON")
+	}
+	return datatypes.JSON(data), nil
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	authv1 "github.com/maintainerd/auth/internal/gen/go/maintainerd/auth"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"gorm.io/datatypes"
+)
+
+var (
+	testTenant = &model.Tenant{TenantID: 7, TenantUUID: uuid.New()}
+	testCaller = &model.User{UserID: 1, UserUUID: uuid.New()}
+)
+
+// callerContext returns a context carrying the caller GRPCAuthInterceptor
+// would have resolved.
+func callerContext() context.Context {
+	return middleware.ContextWithAuth(context.Background(), &middleware.AuthContext{Tenant: testTenant, User: testCaller})
+}
+
+func TestCallerFromContext(t *testing.T) {
+	_, _, err := callerFromContext(context.Background())
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, _, err = callerFromContext(middleware.ContextWithAuth(context.Background(), &middleware.AuthContext{Tenant: testTenant}))
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	tenant, user, err := callerFromContext(callerContext())
+	require.NoError(t, err)
+	assert.Same(t, testTenant, tenant)
+	assert.Same(t, testCaller, user)
+}
+
+func TestToPaginationDTO(t *testing.T) {
+	p := toPaginationDTO(nil)
+	assert.Equal(t, defaultPage, p.Page)
+	assert.Equal(t, defaultLimit, p.Limit)
+
+	p = toPaginationDTO(&authv1.PageRequest{Page: 3, Limit: 50, SortBy: "name", SortOrder: "desc"})
+	assert.Equal(t, 3, p.Page)
+	assert.Equal(t, 50, p.Limit)
+	assert.Equal(t, "name", p.SortBy)
+	assert.Equal(t, "desc", p.SortOrder)
+}
+
+func TestMetadataConversion(t *testing.T) {
+	s, err := structpb.NewStruct(map[string]any{"team": "core", "level": 2})
+	require.NoError(t, err)
+
+	data, err := fromStruct(s)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"team":"core","level":2}`, string(data))
+	assert.Equal(t, s.AsMap(), toStruct(data).AsMap())
+
+	data, err = fromStruct(nil)
+	require.NoError(t, err)
+	assert.Nil(t, data)
+	assert.Nil(t, toStruct(nil))
+	assert.Nil(t, toStruct(datatypes.JSON(`["not","an","object"]`)))
+}
+
+func TestParseIDs(t *testing.T) {
+	id := uuid.New()
+	ids, err := parseIDs([]string{id.String()}, "role_ids")
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{id}, ids)
+
+	_, err = parseIDs([]string{id.String(), "nope"}, "role_ids")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, err.Error(), "role_ids")
+}
+
+func TestStatusFromError(t *testing.T) {
+	cases := []struct {
+		err  error
+		code codes.Code
+	}{
+		{apperror.NewNotFoundWithReason("user not found"), codes.NotFound},
+		{apperror.NewValidation("bad input"), codes.InvalidArgument},
+		{apperror.NewConflict("role already exists"), codes.AlreadyExists},
+		{apperror.NewForbidden("system role"), codes.PermissionDenied},
+		{apperror.NewUnauthorized("no session"), codes.Unauthenticated},
+		{errors.New("db down"), codes.Internal},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.code, status.Code(statusFromError(tc.err)), tc.err.Error())
+	}
+}
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/maintainerd/auth/internal/dto"
+	authv1 "github.com/maintainerd/auth/internal/gen/go/maintainerd/auth"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// PermissionHandler serves PermissionService, the gRPC counterpart of the
+// REST /permissions API. Every RPC is scoped to the caller's tenant.
+// Permissions created here are never system permissions.
+type PermissionHandler struct {
+	authv1.UnimplementedPermissionServiceServer
+	permissionService service.PermissionService
+}
+
+// NewPermissionHandler creates a new PermissionHandler.
+func NewPermissionHandler(permissionService service.PermissionService) *PermissionHandler {
+	return &PermissionHandler{permissionService: permissionService}
+}
+
+// ListPermissions returns a page of the tenant's permissions.
+func (h *PermissionHandler) ListPermissions(ctx context.Context, req *authv1.ListPermissionsRequest) (*authv1.ListPermissionsResponse, error) {
+	tenant, _, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := dto.PermissionFilterDTO{
+		Name:                 req.Name,
+		Description:          req.Description,
+		APIUUID:              req.ApiId,
+		RoleUUID:             req.RoleId,
+		ClientUUID:           req.ClientId,
+		Status:               req.Status,
+		IsDefault:            req.IsDefault,
+		IsSystem:             req.IsSystem,
+		PaginationRequestDTO: toPaginationDTO(req.GetPage()),
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	result, err := h.permissionService.Get(ctx, service.PermissionServiceGetFilter{
+		TenantID:    tenant.TenantID,
+		Name:        filter.Name,
+		Description: filter.Description,
+		APIUUID:     filter.APIUUID,
+		RoleUUID:    filter.RoleUUID,
+		ClientUUID:  filter.ClientUUID,
+		Status:      filter.Status,
+		IsDefault:   filter.IsDefault,
+		IsSystem:    filter.IsSystem,
+		Page:        filter.Page,
+		Limit:       filter.Limit,
+		SortBy:      filter.SortBy,
+		SortOrder:   filter.SortOrder,
+	})
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	permissions := make([]*authv1.Permission, len(result.Data))
+	for i, p := range result.Data {
+		permissions[i] = toPermissionProto(p)
+	}
+	return &authv1.ListPermissionsResponse{
+		Permissions: permissions,
+		Page:        toPageInfo(result.Total, result.Page, result.Limit, result.TotalPages),
+	}, nil
+}
+
+// GetPermission returns one of the tenant's permissions.
+func (h *PermissionHandler) GetPermission(ctx context.Context, req *authv1.GetPermissionRequest) (*authv1.Permission, error) {
+	tenant, _, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permissionUUID, err := parseID(req.GetPermissionId(), "permission_id")
+	if err != nil {
+		return nil, err
+	}
+
+	permission, err := h.permissionService.GetByUUID(ctx, permissionUUID, tenant.TenantID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toPermissionProto(*permission), nil
+}
+
+// CreatePermission creates a permission for one of the tenant's APIs.
+func (h *PermissionHandler) CreatePermission(ctx context.Context, req *authv1.CreatePermissionRequest) (*authv1.Permission, error) {
+	tenant, _, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	in := dto.PermissionCreateRequestDTO{
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+		Status:      req.GetStatus(),
+		APIUUID:     req.GetApiId(),
+	}
+	if err := in.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	permission, err := h.permissionService.Create(ctx, tenant.TenantID, in.Name, in.Description, in.Status, false, in.APIUUID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toPermissionProto(*permission), nil
+}
+
+// UpdatePermission updates one of the tenant's permissions.
+func (h *PermissionHandler) UpdatePermission(ctx context.Context, req *authv1.UpdatePermissionRequest) (*authv1.Permission, error) {
+	tenant, _, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permissionUUID, err := parseID(req.GetPermissionId(), "permission_id")
+	if err != nil {
+		return nil, err
+	}
+
+	in := dto.PermissionUpdateRequestDTO{
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+		Status:      req.GetStatus(),
+	}
+	if err := in.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	permission, err := h.permissionService.Update(ctx, permissionUUID, tenant.TenantID, in.Name, in.Description, in.Status)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toPermissionProto(*permission), nil
+}
+
+// SetPermissionStatus activates or deactivates one of the tenant's
+// permissions.
+func (h *PermissionHandler) SetPermissionStatus(ctx context.Context, req *authv1.SetPermissionStatusRequest) (*authv1.Permission, error) {
+	tenant, _, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permissionUUID, err := parseID(req.GetPermissionId(), "permission_id")
+	if err != nil {
+		return nil, err
+	}
+
+	in := dto.PermissionStatusUpdateDTO{Status: req.GetStatus()}
+	if err := in.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	permission, err := h.permissionService.SetStatus(ctx, permissionUUID, tenant.TenantID, in.Status)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toPermissionProto(*permission), nil
+}
+
+// DeletePermission deletes one of the tenant's permissions.
+func (h *PermissionHandler) DeletePermission(ctx context.Context, req *authv1.DeletePermissionRequest) (*authv1.Permission, error) {
+	tenant, _, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permissionUUID, err := parseID(req.GetPermissionId(), "permission_id")
+	if err != nil {
+		return nil, err
+	}
+
+	permission, err := h.permissionService.DeleteByUUID(ctx, permissionUUID, tenant.TenantID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toPermissionProto(*permission), nil
+}
+
+// toPermissionProto converts a service result to a Permission message.
+func toPermissionProto(p service.PermissionServiceDataResult) *authv1.Permission {
+	out := &authv1.Permission{
+		PermissionId: p.PermissionUUID.String(),
+		Name:         p.Name,
+		Description:  p.Description,
+		Status:       p.Status,
+		IsDefault:    p.IsDefault,
+		IsSystem:     p.IsSystem,
+		CreatedAt:    toTimestamp(p.CreatedAt),
+		UpdatedAt:    toTimestamp(p.UpdatedAt),
+	}
+	if p.API != nil {
+		out.ApiId = p.API.APIUUID.String()
+	}
+	return out
+}
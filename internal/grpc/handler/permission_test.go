@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	authv1 "github.com/maintainerd/auth/internal/gen/go/maintainerd/auth"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mockPermissionService implements the PermissionService methods the gRPC
+// handler calls; the embedded interface panics on anything else.
+type mockPermissionService struct {
+	service.PermissionService
+	getFn       func(service.PermissionServiceGetFilter) (*service.PermissionServiceGetResult, error)
+	getByUUIDFn func(uuid.UUID, int64) (*service.PermissionServiceDataResult, error)
+	createFn    func(tenantID int64, name, description, status string, isSystem bool, apiUUID string) (*service.PermissionServiceDataResult, error)
+	updateFn    func(permissionUUID uuid.UUID, tenantID int64, name, description, status string) (*service.PermissionServiceDataResult, error)
+	setStatusFn func(uuid.UUID, int64, string) (*service.PermissionServiceDataResult, error)
+	deleteFn    func(uuid.UUID, int64) (*service.PermissionServiceDataResult, error)
+}
+
+func (m *mockPermissionService) Get(_ context.Context, f service.PermissionServiceGetFilter) (*service.PermissionServiceGetResult, error) {
+	return m.getFn(f)
+}
+
+func (m *mockPermissionService) GetByUUID(_ context.Context, id uuid.UUID, tenantID int64) (*service.PermissionServiceDataResult, error) {
+	return m.getByUUIDFn(id, tenantID)
+}
+
+func (m *mockPermissionService) Create(_ context.Context, tenantID int64, name, description, status string, isSystem bool, apiUUID string) (*service.PermissionServiceDataResult, error) {
+	return m.createFn(tenantID, name, description, status, isSystem, apiUUID)
+}
+
+func (m *mockPermissionService) Update(_ context.Context, id uuid.UUID, tenantID int64, name, description, status string) (*service.PermissionServiceDataResult, error) {
+	return m.updateFn(id, tenantID, name, description, status)
+}
+
+func (m *mockPermissionService) SetStatus(_ context.Context, id uuid.UUID, tenantID int64, status string) (*service.PermissionServiceDataResult, error) {
+	return m.setStatusFn(id, tenantID, status)
+}
+
+func (m *mockPermissionService) DeleteByUUID(_ context.Context, id uuid.UUID, tenantID int64) (*service.PermissionServiceDataResult, error) {
+	return m.deleteFn(id, tenantID)
+}
+
+func TestPermissionHandler_ListAndGet(t *testing.T) {
+	permissionUUID := uuid.New()
+	roleID := uuid.NewString()
+
+	h := NewPermissionHandler(&mockPermissionService{
+		getFn: func(f service.PermissionServiceGetFilter) (*service.PermissionServiceGetResult, error) {
+			assert.Equal(t, testTenant.TenantID, f.TenantID)
+			assert.Equal(t, &roleID, f.RoleUUID)
+			return &service.PermissionServiceGetResult{
+				Data:  []service.PermissionServiceDataResult{{PermissionUUID: permissionUUID, Name: "post:read"}},
+				Total: 1, Page: 1, Limit: 10, TotalPages: 1,
+			}, nil
+		},
+		getByUUIDFn: func(uuid.UUID, int64) (*service.PermissionServiceDataResult, error) {
+			return nil, apperror.NewNotFoundWithReason("permission not found")
+		},
+	})
+
+	list, err := h.ListPermissions(callerContext(), &authv1.ListPermissionsRequest{RoleId: &roleID})
+	require.NoError(t, err)
+	require.Len(t, list.Permissions, 1)
+	assert.Equal(t, permissionUUID.String(), list.Permissions[0].PermissionId)
+	assert.Empty(t, list.Permissions[0].ApiId)
+
+	_, err = h.GetPermission(callerContext(), &authv1.GetPermissionRequest{PermissionId: permissionUUID.String()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = h.ListPermissions(context.Background(), &authv1.ListPermissionsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestPermissionHandler_Create(t *testing.T) {
+	apiUUID := uuid.NewString()
+	h := NewPermissionHandler(&mockPermissionService{
+		createFn: func(tenantID int64, name, _, _ string, isSystem bool, api string) (*service.PermissionServiceDataResult, error) {
+			assert.Equal(t, testTenant.TenantID, tenantID)
+			assert.Equal(t, "post:read", name)
+			assert.False(t, isSystem)
+			assert.Equal(t, apiUUID, api)
+			return &service.PermissionServiceDataResult{PermissionUUID: uuid.New(), Name: name}, nil
+		},
+	})
+
+	resp, err := h.CreatePermission(callerContext(), &authv1.CreatePermissionRequest{
+		Name: "post:read", Description: "Read posts", Status: "active", ApiId: apiUUID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "post:read", resp.Name)
+
+	_, err = h.CreatePermission(callerContext(), &authv1.CreatePermissionRequest{Name: "post:read"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestPermissionHandler_UpdateStatusDelete(t *testing.T) {
+	permissionUUID := uuid.New()
+	h := NewPermissionHandler(&mockPermissionService{
+		updateFn: func(id uuid.UUID, tenantID int64, name, _, _ string) (*service.PermissionServiceDataResult, error) {
+			assert.Equal(t, permissionUUID, id)
+			return &service.PermissionServiceDataResult{PermissionUUID: id, Name: name}, nil
+		},
+		setStatusFn: func(id uuid.UUID, _ int64, st string) (*service.PermissionServiceDataResult, error) {
+			return &service.PermissionServiceDataResult{PermissionUUID: id, Status: st}, nil
+		},
+		deleteFn: func(uuid.UUID, int64) (*service.PermissionServiceDataResult, error) {
+			return nil, apperror.NewValidation("cannot delete system permission")
+		},
+	})
+
+	_, err := h.UpdatePermission(callerContext(), &authv1.UpdatePermissionRequest{
+		PermissionId: permissionUUID.String(), Name: "post:edit", Description: "Edit posts", Status: "active",
+	})
+	require.NoError(t, err)
+
+	_, err = h.UpdatePermission(callerContext(), &authv1.UpdatePermissionRequest{PermissionId: "nope"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	resp, err := h.SetPermissionStatus(callerContext(), &authv1.SetPermissionStatusRequest{PermissionId: permissionUUID.String(), Status: "inactive"})
+	require.NoError(t, err)
+	assert.Equal(t, "inactive", resp.Status)
+
+	_, err = h.SetPermissionStatus(callerContext(), &authv1.SetPermissionStatusRequest{PermissionId: permissionUUID.String(), Status: "deleted"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = h.DeletePermission(callerContext(), &authv1.DeletePermissionRequest{PermissionId: permissionUUID.String()})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,288 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/maintainerd/auth/internal/dto"
+	authv1 "github.com/maintainerd/auth/internal/gen/go/maintainerd/auth"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RoleHandler serves RoleService, the gRPC counterpart of the REST /roles
+// API. Every RPC is scoped to the caller's tenant. As over REST, roles
+// created or updated here are never default or system roles.
+type RoleHandler struct {
+	authv1.UnimplementedRoleServiceServer
+	roleService service.RoleService
+}
+
+// NewRoleHandler creates a new RoleHandler.
+func NewRoleHandler(roleService service.RoleService) *RoleHandler {
+	return &RoleHandler{roleService: roleService}
+}
+
+// ListRoles returns a page of the tenant's roles.
+func (h *RoleHandler) ListRoles(ctx context.Context, req *authv1.ListRolesRequest) (*authv1.ListRolesResponse, error) {
+	tenant, _, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := dto.RoleFilterDTO{
+		Name:                 req.Name,
+		Description:          req.Description,
+		IsDefault:            req.IsDefault,
+		IsSystem:             req.IsSystem,
+		Status:               req.Status,
+		PaginationRequestDTO: toPaginationDTO(req.GetPage()),
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	result, err := h.roleService.Get(ctx, service.RoleServiceGetFilter{
+		Name:        filter.Name,
+		Description: filter.Description,
+		IsDefault:   filter.IsDefault,
+		IsSystem:    filter.IsSystem,
+		Status:      filter.Status,
+		TenantID:    tenant.TenantID,
+		Page:        filter.Page,
+		Limit:       filter.Limit,
+		SortBy:      filter.SortBy,
+		SortOrder:   filter.SortOrder,
+	})
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	roles := make([]*authv1.Role, len(result.Data))
+	for i, r := range result.Data {
+		roles[i] = toRoleProto(r)
+	}
+	return &authv1.ListRolesResponse{
+		Roles: roles,
+		Page:  toPageInfo(result.Total, result.Page, result.Limit, result.TotalPages),
+	}, nil
+}
+
+// GetRole returns one of the tenant's roles.
+func (h *RoleHandler) GetRole(ctx context.Context, req *authv1.GetRoleRequest) (*authv1.Role, error) {
+	tenant, _, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roleUUID, err := parseID(req.GetRoleId(), "role_id")
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := h.roleService.GetByUUID(ctx, roleUUID, tenant.TenantID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toRoleProto(*role), nil
+}
+
+// CreateRole creates a role in the tenant.
+func (h *RoleHandler) CreateRole(ctx context.Context, req *authv1.CreateRoleRequest) (*authv1.Role, error) {
+	tenant, caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	in := dto.RoleCreateOrUpdateRequestDTO{
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+		Status:      req.GetStatus(),
+	}
+	if err := in.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	role, err := h.roleService.Create(ctx, in.Name, in.Description, false, false, in.Status, tenant.TenantUUID.String(), caller.UserUUID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toRoleProto(*role), nil
+}
+
+// UpdateRole updates one of the tenant's roles.
+func (h *RoleHandler) UpdateRole(ctx context.Context, req *authv1.UpdateRoleRequest) (*authv1.Role, error) {
+	tenant, caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roleUUID, err := parseID(req.GetRoleId(), "role_id")
+	if err != nil {
+		return nil, err
+	}
+
+	in := dto.RoleCreateOrUpdateRequestDTO{
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+		Status:      req.GetStatus(),
+	}
+	if err := in.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	role, err := h.roleService.Update(ctx, roleUUID, tenant.TenantID, in.Name, in.Description, false, false, in.Status, caller.UserUUID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toRoleProto(*role), nil
+}
+
+// SetRoleStatus activates or deactivates one of the tenant's roles.
+func (h *RoleHandler) SetRoleStatus(ctx context.Context, req *authv1.SetRoleStatusRequest) (*authv1.Role, error) {
+	tenant, caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roleUUID, err := parseID(req.GetRoleId(), "role_id")
+	if err != nil {
+		return nil, err
+	}
+	if req.GetStatus() != model.StatusActive && req.GetStatus() != model.StatusInactive {
+		return nil, status.Error(codes.InvalidArgument, "status must be 'active' or 'inactive'")
+	}
+
+	role, err := h.roleService.SetStatusByUUID(ctx, roleUUID, tenant.TenantID, req.GetStatus(), caller.UserUUID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toRoleProto(*role), nil
+}
+
+// DeleteRole deletes one of the tenant's roles together with its
+// assignments and grants.
+func (h *RoleHandler) DeleteRole(ctx context.Context, req *authv1.DeleteRoleRequest) (*authv1.Role, error) {
+	tenant, caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roleUUID, err := parseID(req.GetRoleId(), "role_id")
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := h.roleService.DeleteByUUID(ctx, roleUUID, tenant.TenantID, caller.UserUUID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toRoleProto(*role), nil
+}
+
+// ListRolePermissions returns a page of the permissions granted to one of
+// the tenant's roles.
+func (h *RoleHandler) ListRolePermissions(ctx context.Context, req *authv1.ListRolePermissionsRequest) (*authv1.ListPermissionsResponse, error) {
+	tenant, _, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roleUUID, err := parseID(req.GetRoleId(), "role_id")
+	if err != nil {
+		return nil, err
+	}
+
+	page := toPaginationDTO(req.GetPage())
+	if err := page.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	result, err := h.roleService.GetRolePermissions(ctx, service.RoleServiceGetPermissionsFilter{
+		RoleUUID:  roleUUID,
+		Status:    req.Status,
+		TenantID:  tenant.TenantID,
+		Page:      page.Page,
+		Limit:     page.Limit,
+		SortBy:    page.SortBy,
+		SortOrder: page.SortOrder,
+	})
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	permissions := make([]*authv1.Permission, len(result.Data))
+	for i, p := range result.Data {
+		permissions[i] = toPermissionProto(p)
+	}
+	return &authv1.ListPermissionsResponse{
+		Permissions: permissions,
+		Page:        toPageInfo(result.Total, result.Page, result.Limit, result.TotalPages),
+	}, nil
+}
+
+// AddRolePermissions grants permissions to one of the tenant's roles.
+func (h *RoleHandler) AddRolePermissions(ctx context.Context, req *authv1.AddRolePermissionsRequest) (*authv1.Role, error) {
+	tenant, caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roleUUID, err := parseID(req.GetRoleId(), "role_id")
+	if err != nil {
+		return nil, err
+	}
+	permissionUUIDs, err := parseIDs(req.GetPermissionIds(), "permission_ids")
+	if err != nil {
+		return nil, err
+	}
+
+	in := dto.RoleAddPermissionsRequestDTO{Permissions: permissionUUIDs}
+	if err := in.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	role, err := h.roleService.AddRolePermissions(ctx, roleUUID, tenant.TenantID, in.Permissions, caller.UserUUID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toRoleProto(*role), nil
+}
+
+// RemoveRolePermission revokes a permission from one of the tenant's roles.
+func (h *RoleHandler) RemoveRolePermission(ctx context.Context, req *authv1.RemoveRolePermissionRequest) (*authv1.Role, error) {
+	tenant, caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roleUUID, err := parseID(req.GetRoleId(), "role_id")
+	if err != nil {
+		return nil, err
+	}
+	permissionUUID, err := parseID(req.GetPermissionId(), "permission_id")
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := h.roleService.RemoveRolePermissions(ctx, roleUUID, tenant.TenantID, permissionUUID, caller.UserUUID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toRoleProto(*role), nil
+}
+
+// toRoleProto converts a service result to a Role message.
+func toRoleProto(r service.RoleServiceDataResult) *authv1.Role {
+	out := &authv1.Role{
+		RoleId:      r.RoleUUID.String(),
+		Name:        r.Name,
+		Description: r.Description,
+		IsDefault:   r.IsDefault,
+		IsSystem:    r.IsSystem,
+		Status:      r.Status,
+		CreatedAt:   toTimestamp(r.CreatedAt),
+		UpdatedAt:   toTimestamp(r.UpdatedAt),
+	}
+	if r.Permissions != nil {
+		out.Permissions = make([]*authv1.Permission, len(*r.Permissions))
+		for i, p := range *r.Permissions {
+			out.Permissions[i] = toPermissionProto(p)
+		}
+	}
+	return out
+}
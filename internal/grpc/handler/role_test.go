@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	authv1 "github.com/maintainerd/auth/internal/gen/go/maintainerd/auth"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mockRoleService implements the RoleService methods the gRPC handler
+// calls; the embedded interface panics on anything else.
+type mockRoleService struct {
+	service.RoleService
+	getFn               func(service.RoleServiceGetFilter) (*service.RoleServiceGetResult, error)
+	getByUUIDFn         func(uuid.UUID, int64) (*service.RoleServiceDataResult, error)
+	getPermissionsFn    func(service.RoleServiceGetPermissionsFilter) (*service.RoleServiceGetPermissionsResult, error)
+	createFn            func(name, description string, isDefault, isSystem bool, status, tenantUUID string, actor uuid.UUID) (*service.RoleServiceDataResult, error)
+	updateFn            func(roleUUID uuid.UUID, tenantID int64, name, description string, isDefault, isSystem bool, status string, actor uuid.UUID) (*service.RoleServiceDataResult, error)
+	setStatusFn         func(uuid.UUID, int64, string, uuid.UUID) (*service.RoleServiceDataResult, error)
+	deleteFn            func(uuid.UUID, int64, uuid.UUID) (*service.RoleServiceDataResult, error)
+	addPermissionsFn    func(uuid.UUID, int64, []uuid.UUID, uuid.UUID) (*service.RoleServiceDataResult, error)
+	removePermissionsFn func(uuid.UUID, int64, uuid.UUID, uuid.UUID) (*service.RoleServiceDataResult, error)
+}
+
+func (m *mockRoleService) Get(_ context.Context, f service.RoleServiceGetFilter) (*service.RoleServiceGetResult, error) {
+	return m.getFn(f)
+}
+
+func (m *mockRoleService) GetByUUID(_ context.Context, id uuid.UUID, tenantID int64) (*service.RoleServiceDataResult, error) {
+	return m.getByUUIDFn(id, tenantID)
+}
+
+func (m *mockRoleService) GetRolePermissions(_ context.Context, f service.RoleServiceGetPermissionsFilter) (*service.RoleServiceGetPermissionsResult, error) {
+	return m.getPermissionsFn(f)
+}
+
+func (m *mockRoleService) Create(_ context.Context, name, description string, isDefault, isSystem bool, status, tenantUUID string, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
+	return m.createFn(name, description, isDefault, isSystem, status, tenantUUID, actor)
+}
+
+func (m *mockRoleService) Update(_ context.Context, roleUUID uuid.UUID, tenantID int64, name, description string, isDefault, isSystem bool, status string, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
+	return m.updateFn(roleUUID, tenantID, name, description, isDefault, isSystem, status, actor)
+}
+
+func (m *mockRoleService) SetStatusByUUID(_ context.Context, id uuid.UUID, tenantID int64, status string, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
+	return m.setStatusFn(id, tenantID, status, actor)
+}
+
+func (m *mockRoleService) DeleteByUUID(_ context.Context, id uuid.UUID, tenantID int64, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
+	return m.deleteFn(id, tenantID, actor)
+}
+
+func (m *mockRoleService) AddRolePermissions(_ context.Context, id uuid.UUID, tenantID int64, permissionUUIDs []uuid.UUID, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
+	return m.addPermissionsFn(id, tenantID, permissionUUIDs, actor)
+}
+
+func (m *mockRoleService) RemoveRolePermissions(_ context.Context, id uuid.UUID, tenantID int64, permissionUUID uuid.UUID, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
+	return m.removePermissionsFn(id, tenantID, permissionUUID, actor)
+}
+
+func TestRoleHandler_ListAndGet(t *testing.T) {
+	roleUUID := uuid.New()
+	active := "active"
+
+	h := NewRoleHandler(&mockRoleService{
+		getFn: func(f service.RoleServiceGetFilter) (*service.RoleServiceGetResult, error) {
+			assert.Equal(t, testTenant.TenantID, f.TenantID)
+			assert.Equal(t, &active, f.Status)
+			return &service.RoleServiceGetResult{
+				Data:  []service.RoleServiceDataResult{{RoleUUID: roleUUID, Name: "editor"}},
+				Total: 1, Page: 1, Limit: 10, TotalPages: 1,
+			}, nil
+		},
+		getByUUIDFn: func(id uuid.UUID, tenantID int64) (*service.RoleServiceDataResult, error) {
+			assert.Equal(t, testTenant.TenantID, tenantID)
+			return &service.RoleServiceDataResult{
+				RoleUUID:    id,
+				Name:        "editor",
+				Permissions: &[]service.PermissionServiceDataResult{{PermissionUUID: uuid.New(), Name: "post:write"}},
+			}, nil
+		},
+	})
+
+	list, err := h.ListRoles(callerContext(), &authv1.ListRolesRequest{Status: &active})
+	require.NoError(t, err)
+	require.Len(t, list.Roles, 1)
+	assert.Equal(t, roleUUID.String(), list.Roles[0].RoleId)
+
+	bogus := "bogus"
+	_, err = h.ListRoles(callerContext(), &authv1.ListRolesRequest{Status: &bogus})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	role, err := h.GetRole(callerContext(), &authv1.GetRoleRequest{RoleId: roleUUID.String()})
+	require.NoError(t, err)
+	require.Len(t, role.Permissions, 1)
+	assert.Equal(t, "post:write", role.Permissions[0].Name)
+
+	_, err = h.GetRole(context.Background(), &authv1.GetRoleRequest{RoleId: roleUUID.String()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestRoleHandler_CreateAndUpdate(t *testing.T) {
+	roleUUID := uuid.New()
+	h := NewRoleHandler(&mockRoleService{
+		createFn: func(name, _ string, isDefault, isSystem bool, _, tenantUUID string, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
+			assert.Equal(t, "editor", name)
+			assert.False(t, isDefault)
+			assert.False(t, isSystem)
+			assert.Equal(t, testTenant.TenantUUID.String(), tenantUUID)
+			assert.Equal(t, testCaller.UserUUID, actor)
+			return &service.RoleServiceDataResult{RoleUUID: roleUUID, Name: name}, nil
+		},
+		updateFn: func(id uuid.UUID, tenantID int64, _, _ string, isDefault, isSystem bool, _ string, _ uuid.UUID) (*service.RoleServiceDataResult, error) {
+			assert.False(t, isDefault)
+			assert.False(t, isSystem)
+			return nil, apperror.NewValidation("cannot update system role")
+		},
+	})
+
+	req := &authv1.CreateRoleRequest{Name: "editor", Description: "Edits content", Status: "active"}
+	role, err := h.CreateRole(callerContext(), req)
+	require.NoError(t, err)
+	assert.Equal(t, roleUUID.String(), role.RoleId)
+
+	_, err = h.CreateRole(callerContext(), &authv1.CreateRoleRequest{Name: "x"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = h.UpdateRole(callerContext(), &authv1.UpdateRoleRequest{RoleId: roleUUID.String(), Name: "editor", Description: "Edits content", Status: "active"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRoleHandler_StatusAndDelete(t *testing.T) {
+	roleUUID := uuid.New()
+	h := NewRoleHandler(&mockRoleService{
+		setStatusFn: func(id uuid.UUID, _ int64, st string, _ uuid.UUID) (*service.RoleServiceDataResult, error) {
+			return &service.RoleServiceDataResult{RoleUUID: id, Status: st}, nil
+		},
+		deleteFn: func(id uuid.UUID, tenantID int64, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
+			assert.Equal(t, testCaller.UserUUID, actor)
+			return &service.RoleServiceDataResult{RoleUUID: id}, nil
+		},
+	})
+
+	role, err := h.SetRoleStatus(callerContext(), &authv1.SetRoleStatusRequest{RoleId: roleUUID.String(), Status: "inactive"})
+	require.NoError(t, err)
+	assert.Equal(t, "inactive", role.Status)
+
+	_, err = h.SetRoleStatus(callerContext(), &authv1.SetRoleStatusRequest{RoleId: roleUUID.String(), Status: "pending"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = h.DeleteRole(callerContext(), &authv1.DeleteRoleRequest{RoleId: roleUUID.String()})
+	require.NoError(t, err)
+
+	_, err = h.DeleteRole(callerContext(), &authv1.DeleteRoleRequest{RoleId: "nope"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRoleHandler_Permissions(t *testing.T) {
+	roleUUID := uuid.New()
+	permissionUUID := uuid.New()
+	apiUUID := uuid.New()
+
+	h := NewRoleHandler(&mockRoleService{
+		getPermissionsFn: func(f service.RoleServiceGetPermissionsFilter) (*service.RoleServiceGetPermissionsResult, error) {
+			assert.Equal(t, roleUUID, f.RoleUUID)
+			assert.Equal(t, testTenant.TenantID, f.TenantID)
+			assert.Equal(t, 2, f.Page)
+			return &service.RoleServiceGetPermissionsResult{
+				Data: []service.PermissionServiceDataResult{{
+					PermissionUUID: permissionUUID,
+					Name:           "post:write",
+					API:            &service.APIServiceDataResult{APIUUID: apiUUID},
+				}},
+				Total: 11, Page: 2, Limit: 10, TotalPages: 2,
+			}, nil
+		},
+		addPermissionsFn: func(id uuid.UUID, _ int64, permissionUUIDs []uuid.UUID, _ uuid.UUID) (*service.RoleServiceDataResult, error) {
+			assert.Equal(t, []uuid.UUID{permissionUUID}, permissionUUIDs)
+			return &service.RoleServiceDataResult{RoleUUID: id}, nil
+		},
+		removePermissionsFn: func(id uuid.UUID, _ int64, p uuid.UUID, _ uuid.UUID) (*service.RoleServiceDataResult, error) {
+			assert.Equal(t, permissionUUID, p)
+			return &service.RoleServiceDataResult{RoleUUID: id}, nil
+		},
+	})
+
+	list, err := h.ListRolePermissions(callerContext(), &authv1.ListRolePermissionsRequest{
+		RoleId: roleUUID.String(), Page: &authv1.PageRequest{Page: 2},
+	})
+	require.NoError(t, err)
+	require.Len(t, list.Permissions, 1)
+	assert.Equal(t, apiUUID.String(), list.Permissions[0].ApiId)
+	assert.Equal(t, int32(2), list.Page.TotalPages)
+
+	_, err = h.ListRolePermissions(callerContext(), &authv1.ListRolePermissionsRequest{
+		RoleId: roleUUID.String(), Page: &authv1.PageRequest{SortOrder: "sideways"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = h.AddRolePermissions(callerContext(), &authv1.AddRolePermissionsRequest{RoleId: roleUUID.String(), PermissionIds: []string{permissionUUID.String()}})
+	require.NoError(t, err)
+
+	_, err = h.AddRolePermissions(callerContext(), &authv1.AddRolePermissionsRequest{RoleId: roleUUID.String()})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = h.RemoveRolePermission(callerContext(), &authv1.RemoveRolePermissionRequest{RoleId: roleUUID.String(), PermissionId: permissionUUID.String()})
+	require.NoError(t, err)
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	authv1 "github.com/maintainerd/auth/internal/gen/go/maintainerd/auth"
+	"github.com/maintainerd/auth/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TokenHandler serves TokenService, which lets sibling services validate
+// access tokens and check permissions without parsing JWTs themselves.
+type TokenHandler struct {
+	authv1.UnimplementedTokenServiceServer
+	tokenValidationService service.TokenValidationService
+}
+
+// NewTokenHandler creates a new TokenHandler.
+func NewTokenHandler(tokenValidationService service.TokenValidationService) *TokenHandler {
+	return &TokenHandler{tokenValidationService: tokenValidationService}
+}
+
+// ValidateToken verifies an access token and returns its claims, roles and
+// permissions.
+func (h *TokenHandler) ValidateToken(ctx context.Context, req *authv1.ValidateTokenRequest) (*authv1.ValidateTokenResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	result, err := h.tokenValidationService.ValidateToken(ctx, req.GetToken())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	if !result.Valid {
+		return &authv1.ValidateTokenResponse{Reason: result.Reason}, nil
+	}
+
+	out := &authv1.ValidateTokenResponse{
+		Valid:       true,
+		Subject:     result.Subject,
+		Issuer:      result.Issuer,
+		Audience:    result.Audience,
+		ClientId:    result.ClientID,
+		ProviderId:  result.ProviderID,
+		TokenId:     result.TokenID,
+		Scope:       result.Scope,
+		Actor:       result.Actor,
+		Roles:       result.Roles,
+		Permissions: result.Permissions,
+	}
+	if !result.IssuedAt.IsZero() {
+		out.IssuedAt = timestamppb.New(result.IssuedAt)
+	}
+	if !result.ExpiresAt.IsZero() {
+		out.ExpiresAt = timestamppb.New(result.ExpiresAt)
+	}
+	if result.TenantUUID != nil {
+		out.TenantId = result.TenantUUID.String()
+	}
+	return out, nil
+}
+
+// CheckPermission reports whether a user holds a permission.
+func (h *TokenHandler) CheckPermission(ctx context.Context, req *authv1.CheckPermissionRequest) (*authv1.CheckPermissionResponse, error) {
+	userUUID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "user_id must be a valid UUID")
+	}
+	if req.GetPermission() == "" {
+		return nil, status.Error(codes.InvalidArgument, "permission is required")
+	}
+	var tenantUUID *uuid.UUID
+	if req.GetTenantId() != "" {
+		id, err := uuid.Parse(req.GetTenantId())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "tenant_id must be a valid UUID")
+		}
+		tenantUUID = &id
+	}
+
+	result, err := h.tokenValidationService.CheckPermission(ctx, userUUID, tenantUUID, req.GetPermission())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &authv1.CheckPermissionResponse{Allowed: result.Allowed, Reason: result.Reason}, nil
+}
+
+// statusFromError maps a service error to a gRPC status. Internal errors are
+// logged and reported without their cause.
+func statusFromError(err error) error {
+	var notFound *apperror.NotFoundError
+	var validation *apperror.ValidationError
+	var conflict *apperror.ConflictError
+	var forbidden *apperror.ForbiddenError
+	var unauthorized *apperror.UnauthorizedError
+	switch {
+	case errors.As(err, &notFound):
+		return status.Error(codes.NotFound, notFound.Error())
+	case errors.As(err, &validation):
+		return status.Error(codes.InvalidArgument, validation.Error())
+	case errors.As(err, &conflict):
+		return status.Error(codes.AlreadyExists, conflict.Error())
+	case errors.As(err, &forbidden):
+		return status.Error(codes.PermissionDenied, forbidden.Error())
+	case errors.As(err, &unauthorized):
+		return status.Error(codes.Unauthenticated, unauthorized.Error())
+	default:
+		slog.Error("grpc: request failed", "error", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	authv1 "github.com/maintainerd/auth/internal/gen/go/maintainerd/auth"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockTokenValidationService struct {
+	validateFn func(token string) (*service.TokenValidationResult, error)
+	checkFn    func(userUUID uuid.UUID, tenantUUID *uuid.UUID, permission string) (*service.PermissionCheckResult, error)
+}
+
+func (m *mockTokenValidationService) ValidateToken(_ context.Context, token string) (*service.TokenValidationResult, error) {
+	return m.validateFn(token)
+}
+
+func (m *mockTokenValidationService) CheckPermission(_ context.Context, userUUID uuid.UUID, tenantUUID *uuid.UUID, permission string) (*service.PermissionCheckResult, error) {
+	return m.checkFn(userUUID, tenantUUID, permission)
+}
+
+func TestTokenHandler_ValidateToken(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("missing token", func(t *testing.T) {
+		_, err := NewTokenHandler(&mockTokenValidationService{}).ValidateToken(ctx, &authv1.ValidateTokenRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		tenantUUID := uuid.New()
+		exp := time.Now().Add(time.Hour)
+		h := NewTokenHandler(&mockTokenValidationService{
+			validateFn: func(token string) (*service.TokenValidationResult, error) {
+				assert.Equal(t, "abc", token)
+				return &service.TokenValidationResult{
+					Valid: true, Subject: "user-1", ClientID: "web", ExpiresAt: exp,
+					TenantUUID: &tenantUUID, Permissions: []string{"post:read"},
+				}, nil
+			},
+		})
+		resp, err := h.ValidateToken(ctx, &authv1.ValidateTokenRequest{Token: "abc"})
+		require.NoError(t, err)
+		assert.True(t, resp.Valid)
+		assert.Equal(t, "user-1", resp.Subject)
+		assert.Equal(t, tenantUUID.String(), resp.TenantId)
+		assert.Equal(t, exp.Unix(), resp.ExpiresAt.AsTime().Unix())
+		assert.Nil(t, resp.IssuedAt)
+		assert.Equal(t, []string{"post:read"}, resp.Permissions)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		h := NewTokenHandler(&mockTokenValidationService{
+			validateFn: func(string) (*service.TokenValidationResult, error) {
+				return &service.TokenValidationResult{Reason: service.TokenReasonInvalidToken}, nil
+			},
+		})
+		resp, err := h.ValidateToken(ctx, &authv1.ValidateTokenRequest{Token: "abc"})
+		require.NoError(t, err)
+		assert.False(t, resp.Valid)
+		assert.Equal(t, service.TokenReasonInvalidToken, resp.Reason)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewTokenHandler(&mockTokenValidationService{
+			validateFn: func(string) (*service.TokenValidationResult, error) {
+				return nil, apperror.NewInternal("failed to load token user", errors.New("db down"))
+			},
+		})
+		_, err := h.ValidateToken(ctx, &authv1.ValidateTokenRequest{Token: "abc"})
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.NotContains(t, err.Error(), "db down")
+	})
+}
+
+func TestTokenHandler_CheckPermission(t *testing.T) {
+	ctx := context.Background()
+	userUUID := uuid.New()
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		h := NewTokenHandler(&mockTokenValidationService{})
+		for _, req := range []*authv1.CheckPermissionRequest{
+			{UserId: "nope", Permission: "post:read"},
+			{UserId: userUUID.String()},
+			{UserId: userUUID.String(), Permission: "post:read", TenantId: "nope"},
+		} {
+			_, err := h.CheckPermission(ctx, req)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		}
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		tenantUUID := uuid.New()
+		h := NewTokenHandler(&mockTokenValidationService{
+			checkFn: func(u uuid.UUID, tID *uuid.UUID, permission string) (*service.PermissionCheckResult, error) {
+				assert.Equal(t, userUUID, u)
+				require.NotNil(t, tID)
+				assert.Equal(t, tenantUUID, *tID)
+				assert.Equal(t, "post:read", permission)
+				return &service.PermissionCheckResult{Allowed: true, Reason: "permission_granted"}, nil
+			},
+		})
+		resp, err := h.CheckPermission(ctx, &authv1.CheckPermissionRequest{
+			UserId: userUUID.String(), Permission: "post:read", TenantId: tenantUUID.String(),
+		})
+		require.NoError(t, err)
+		assert.True(t, resp.Allowed)
+		assert.Equal(t, "permission_granted", resp.Reason)
+	})
+
+	t.Run("tenant not found", func(t *testing.T) {
+		h := NewTokenHandler(&mockTokenValidationService{
+			checkFn: func(uuid.UUID, *uuid.UUID, string) (*service.PermissionCheckResult, error) {
+				return nil, apperror.NewNotFoundWithReason("tenant not found")
+			},
+		})
+		_, err := h.CheckPermission(ctx, &authv1.CheckPermissionRequest{UserId: userUUID.String(), Permission: "post:read"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
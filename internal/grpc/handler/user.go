@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/maintainerd/auth/internal/dto"
+	authv1 "github.com/maintainerd/auth/internal/gen/go/maintainerd/auth"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// UserHandler serves UserService, the gRPC counterpart of the REST /users
+// API. Every RPC is scoped to the caller's tenant and validates its input
+// with the same DTO rules as REST.
+type UserHandler struct {
+	authv1.UnimplementedUserServiceServer
+	userService service.UserService
+}
+
+// NewUserHandler creates a new UserHandler.
+func NewUserHandler(userService service.UserService) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+// ListUsers returns a page of the tenant's users.
+func (h *UserHandler) ListUsers(ctx context.Context, req *authv1.ListUsersRequest) (*authv1.ListUsersResponse, error) {
+	tenant, _, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := dto.UserFilterDTO{
+		Username:             req.Username,
+		Email:                req.Email,
+		Phone:                req.Phone,
+		Status:               req.GetStatus(),
+		RoleUUID:             req.RoleId,
+		UserPoolUUID:         req.UserPoolId,
+		ClientUUID:           req.ClientId,
+		PaginationRequestDTO: toPaginationDTO(req.GetPage()),
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	result, err := h.userService.Get(ctx, service.UserServiceGetFilter{
+		Username:     filter.Username,
+		Email:        filter.Email,
+		Phone:        filter.Phone,
+		Status:       filter.Status,
+		TenantID:     tenant.TenantID,
+		RoleUUID:     filter.RoleUUID,
+		UserPoolUUID: filter.UserPoolUUID,
+		ClientUUID:   filter.ClientUUID,
+		Page:         filter.Page,
+		Limit:        filter.Limit,
+		SortBy:       filter.SortBy,
+		SortOrder:    filter.SortOrder,
+	})
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	users := make([]*authv1.User, len(result.Data))
+	for i, u := range result.Data {
+		users[i] = toUserProto(u)
+	}
+	return &authv1.ListUsersResponse{
+		Users: users,
+		Page:  toPageInfo(result.Total, result.Page, result.Limit, result.TotalPages),
+	}, nil
+}
+
+// GetUser returns one of the tenant's users.
+func (h *UserHandler) GetUser(ctx context.Context, req *authv1.GetUserRequest) (*authv1.User, error) {
+	tenant, _, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userUUID, err := parseID(req.GetUserId(), "user_id")
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := h.userService.GetByUUID(ctx, userUUID, tenant.TenantID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toUserProto(*user), nil
+}
+
+// CreateUser creates a user in the tenant.
+func (h *UserHandler) CreateUser(ctx context.Context, req *authv1.CreateUserRequest) (*authv1.User, error) {
+	tenant, caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := fromStruct(req.GetMetadata())
+	if err != nil {
+		return nil, err
+	}
+
+	in := dto.UserCreateRequestDTO{
+		Username:   req.GetUsername(),
+		Fullname:   req.GetFullname(),
+		Email:      req.Email,
+		Phone:      req.Phone,
+		Password:   req.GetPassword(),
+		Status:     req.GetStatus(),
+		Metadata:   metadata,
+		TenantUUID: tenant.TenantUUID.String(),
+	}
+	if err := in.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	user, err := h.userService.Create(ctx, in.Username, in.Fullname, in.Email, in.Phone, in.Password, in.Status, in.Metadata, in.TenantUUID, caller.UserUUID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toUserProto(*user), nil
+}
+
+// UpdateUser updates one of the tenant's users.
+func (h *UserHandler) UpdateUser(ctx context.Context, req *authv1.UpdateUserRequest) (*authv1.User, error) {
+	tenant, caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userUUID, err := parseID(req.GetUserId(), "user_id")
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := fromStruct(req.GetMetadata())
+	if err != nil {
+		return nil, err
+	}
+
+	in := dto.UserUpdateRequestDTO{
+		Username: req.GetUsername(),
+		Fullname: req.GetFullname(),
+		Email:    req.Email,
+		Phone:    req.Phone,
+		Status:   req.GetStatus(),
+		Metadata: metadata,
+	}
+	if err := in.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	user, err := h.userService.Update(ctx, userUUID, tenant.TenantID, in.Username, in.Fullname, in.Email, in.Phone, in.Status, in.Metadata, caller.UserUUID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toUserProto(*user), nil
+}
+
+// SetUserStatus changes the status of one of the tenant's users.
+func (h *UserHandler) SetUserStatus(ctx context.Context, req *authv1.SetUserStatusRequest) (*authv1.User, error) {
+	tenant, caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userUUID, err := parseID(req.GetUserId(), "user_id")
+	if err != nil {
+		return nil, err
+	}
+
+	in := dto.UserSetStatusRequestDTO{Status: req.GetStatus()}
+	if err := in.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	user, err := h.userService.SetStatus(ctx, userUUID, tenant.TenantID, in.Status, caller.UserUUID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toUserProto(*user), nil
+}
+
+// DeleteUser soft-deletes one of the tenant's users.
+func (h *UserHandler) DeleteUser(ctx context.Context, req *authv1.DeleteUserRequest) (*authv1.User, error) {
+	tenant, caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userUUID, err := parseID(req.GetUserId(), "user_id")
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := h.userService.DeleteByUUID(ctx, userUUID, tenant.TenantID, caller.UserUUID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toUserProto(*user), nil
+}
+
+// ListUserRoles returns the roles assigned to one of the tenant's users.
+func (h *UserHandler) ListUserRoles(ctx context.Context, req *authv1.ListUserRolesRequest) (*authv1.ListUserRolesResponse, error) {
+	tenant, _, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userUUID, err := parseID(req.GetUserId(), "user_id")
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure the user belongs to the tenant before listing its roles.
+	user, err := h.userService.GetByUUID(ctx, userUUID, tenant.TenantID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	roles, err := h.userService.GetUserRoles(ctx, user.UserUUID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	out := make([]*authv1.Role, len(roles))
+	for i, r := range roles {
+		out[i] = toRoleProto(r)
+	}
+	return &authv1.ListUserRolesResponse{Roles: out}, nil
+}
+
+// AssignUserRoles assigns roles to one of the tenant's users.
+func (h *UserHandler) AssignUserRoles(ctx context.Context, req *authv1.AssignUserRolesRequest) (*authv1.User, error) {
+	tenant, _, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userUUID, err := parseID(req.GetUserId(), "user_id")
+	if err != nil {
+		return nil, err
+	}
+	roleUUIDs, err := parseIDs(req.GetRoleIds(), "role_ids")
+	if err != nil {
+		return nil, err
+	}
+
+	in := dto.UserAssignRolesRequestDTO{RoleUUIDs: roleUUIDs}
+	if err := in.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	user, err := h.userService.AssignUserRoles(ctx, userUUID, in.RoleUUIDs, tenant.TenantID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toUserProto(*user), nil
+}
+
+// RemoveUserRole removes a role from one of the tenant's users.
+func (h *UserHandler) RemoveUserRole(ctx context.Context, req *authv1.RemoveUserRoleRequest) (*authv1.User, error) {
+	tenant, _, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userUUID, err := parseID(req.GetUserId(), "user_id")
+	if err != nil {
+		return nil, err
+	}
+	roleUUID, err := parseID(req.GetRoleId(), "role_id")
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := h.userService.RemoveUserRole(ctx, userUUID, roleUUID, tenant.TenantID)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return toUserProto(*user), nil
+}
+
+// toUserProto converts a service result to a User message.
+func toUserProto(u service.UserServiceDataResult) *authv1.User {
+	out := &authv1.User{
+		UserId:             u.UserUUID.String(),
+		Username:           u.Username,
+		Fullname:           u.Fullname,
+		Email:              u.Email,
+		Phone:              u.Phone,
+		IsEmailVerified:    u.IsEmailVerified,
+		IsPhoneVerified:    u.IsPhoneVerified,
+		IsProfileCompleted: u.IsProfileCompleted,
+		IsAccountCompleted: u.IsAccountCompleted,
+		Status:             u.Status,
+		Metadata:           toStruct(u.Metadata),
+		CreatedAt:          toTimestamp(u.CreatedAt),
+		UpdatedAt:          toTimestamp(u.UpdatedAt),
+	}
+	if u.DeletedAt != nil {
+		out.DeletedAt = toTimestamp(*u.DeletedAt)
+	}
+	return out
+}
@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	authv1 "github.com/maintainerd/auth/internal/gen/go/maintainerd/auth"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"gorm.io/datatypes"
+)
+
+// mockUserService implements the UserService methods the gRPC handler
+// calls; the embedded interface panics on anything else.
+type mockUserService struct {
+	service.UserService
+	getFn         func(service.UserServiceGetFilter) (*service.UserServiceGetResult, error)
+	getByUUIDFn   func(uuid.UUID, int64) (*service.UserServiceDataResult, error)
+	createFn      func(username, fullname string, email, phone *string, password, status string, metadata datatypes.JSON, tenantUUID string, creator uuid.UUID) (*service.UserServiceDataResult, error)
+	updateFn      func(userUUID uuid.UUID, tenantID int64, username, fullname string, email, phone *string, status string, metadata datatypes.JSON, updater uuid.UUID) (*service.UserServiceDataResult, error)
+	setStatusFn   func(uuid.UUID, int64, string, uuid.UUID) (*service.UserServiceDataResult, error)
+	deleteFn      func(uuid.UUID, int64, uuid.UUID) (*service.UserServiceDataResult, error)
+	getRolesFn    func(uuid.UUID) ([]service.RoleServiceDataResult, error)
+	assignRolesFn func(uuid.UUID, []uuid.UUID, int64) (*service.UserServiceDataResult, error)
+	removeRoleFn  func(uuid.UUID, uuid.UUID, int64) (*service.UserServiceDataResult, error)
+}
+
+func (m *mockUserService) Get(_ context.Context, f service.UserServiceGetFilter) (*service.UserServiceGetResult, error) {
+	return m.getFn(f)
+}
+
+func (m *mockUserService) GetByUUID(_ context.Context, id uuid.UUID, tenantID int64) (*service.UserServiceDataResult, error) {
+	return m.getByUUIDFn(id, tenantID)
+}
+
+func (m *mockUserService) Create(_ context.Context, username, fullname string, email, phone *string, password, status string, metadata datatypes.JSON, tenantUUID string, creator uuid.UUID) (*service.UserServiceDataResult, error) {
+	return m.createFn(username, fullname, email, phone, password, status, metadata, tenantUUID, creator)
+}
+
+func (m *mockUserService) Update(_ context.Context, userUUID uuid.UUID, tenantID int64, username, fullname string, email, phone *string, status string, metadata datatypes.JSON, updater uuid.UUID) (*service.UserServiceDataResult, error) {
+	return m.updateFn(userUUID, tenantID, username, fullname, email, phone, status, metadata, updater)
+}
+
+func (m *mockUserService) SetStatus(_ context.Context, id uuid.UUID, tenantID int64, status string, updater uuid.UUID) (*service.UserServiceDataResult, error) {
+	return m.setStatusFn(id, tenantID, status, updater)
+}
+
+func (m *mockUserService) DeleteByUUID(_ context.Context, id uuid.UUID, tenantID int64, deleter uuid.UUID) (*service.UserServiceDataResult, error) {
+	return m.deleteFn(id, tenantID, deleter)
+}
+
+func (m *mockUserService) GetUserRoles(_ context.Context, id uuid.UUID) ([]service.RoleServiceDataResult, error) {
+	return m.getRolesFn(id)
+}
+
+func (m *mockUserService) AssignUserRoles(_ context.Context, id uuid.UUID, roleUUIDs []uuid.UUID, tenantID int64) (*service.UserServiceDataResult, error) {
+	return m.assignRolesFn(id, roleUUIDs, tenantID)
+}
+
+func (m *mockUserService) RemoveUserRole(_ context.Context, id, roleUUID uuid.UUID, tenantID int64) (*service.UserServiceDataResult, error) {
+	return m.removeRoleFn(id, roleUUID, tenantID)
+}
+
+func testUserResult(id uuid.UUID) *service.UserServiceDataResult {
+	return &service.UserServiceDataResult{
+		UserUUID:  id,
+		Username:  "jdoe",
+		Fullname:  "John Doe",
+		Email:     "jdoe@example.com",
+		Status:    "active",
+		Metadata:  datatypes.JSON(`{"team":"core"}`),
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestUserHandler_ListUsers(t *testing.T) {
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := NewUserHandler(&mockUserService{}).ListUsers(context.Background(), &authv1.ListUsersRequest{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		_, err := NewUserHandler(&mockUserService{}).ListUsers(callerContext(), &authv1.ListUsersRequest{Status: []string{"bogus"}})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("tenant scoped", func(t *testing.T) {
+		username := "jd"
+		h := NewUserHandler(&mockUserService{getFn: func(f service.UserServiceGetFilter) (*service.UserServiceGetResult, error) {
+			assert.Equal(t, testTenant.TenantID, f.TenantID)
+			assert.Equal(t, &username, f.Username)
+			assert.Equal(t, 1, f.Page)
+			assert.Equal(t, 10, f.Limit)
+			return &service.UserServiceGetResult{
+				Data:  []service.UserServiceDataResult{*testUserResult(uuid.New())},
+				Total: 1, Page: 1, Limit: 10, TotalPages: 1,
+			}, nil
+		}})
+		resp, err := h.ListUsers(callerContext(), &authv1.ListUsersRequest{Username: &username})
+		require.NoError(t, err)
+		require.Len(t, resp.Users, 1)
+		assert.Equal(t, "jdoe", resp.Users[0].Username)
+		assert.Equal(t, "core", resp.Users[0].Metadata.AsMap()["team"])
+		assert.Equal(t, int64(1), resp.Page.Total)
+	})
+}
+
+func TestUserHandler_GetUser(t *testing.T) {
+	id := uuid.New()
+
+	t.Run("invalid id", func(t *testing.T) {
+		_, err := NewUserHandler(&mockUserService{}).GetUser(callerContext(), &authv1.GetUserRequest{UserId: "nope"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("other tenant", func(t *testing.T) {
+		h := NewUserHandler(&mockUserService{getByUUIDFn: func(uuid.UUID, int64) (*service.UserServiceDataResult, error) {
+			return nil, apperror.NewNotFoundWithReason("user not found")
+		}})
+		_, err := h.GetUser(callerContext(), &authv1.GetUserRequest{UserId: id.String()})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("found", func(t *testing.T) {
+		h := NewUserHandler(&mockUserService{getByUUIDFn: func(u uuid.UUID, tenantID int64) (*service.UserServiceDataResult, error) {
+			assert.Equal(t, id, u)
+			assert.Equal(t, testTenant.TenantID, tenantID)
+			return testUserResult(id), nil
+		}})
+		resp, err := h.GetUser(callerContext(), &authv1.GetUserRequest{UserId: id.String()})
+		require.NoError(t, err)
+		assert.Equal(t, id.String(), resp.UserId)
+		assert.Nil(t, resp.DeletedAt)
+	})
+}
+
+func TestUserHandler_CreateUser(t *testing.T) {
+	t.Run("validation", func(t *testing.T) {
+		_, err := NewUserHandler(&mockUserService{}).CreateUser(callerContext(), &authv1.CreateUserRequest{Username: "jd"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("created in caller tenant", func(t *testing.T) {
+		email := "jdoe@example.com"
+		metadata, err := structpb.NewStruct(map[string]any{"team": "core"})
+		require.NoError(t, err)
+
+		h := NewUserHandler(&mockUserService{createFn: func(username, fullname string, e, _ *string, password, st string, md datatypes.JSON, tenantUUID string, creator uuid.UUID) (*service.UserServiceDataResult, error) {
+			assert.Equal(t, "jdoe", username)
+			assert.Equal(t, &email, e)
+			assert.Equal(t, "s3cret-pass", password)
+			assert.JSONEq(t, `{"team":"core"}`, string(md))
+			assert.Equal(t, testTenant.TenantUUID.String(), tenantUUID)
+			assert.Equal(t, testCaller.UserUUID, creator)
+			return testUserResult(uuid.New()), nil
+		}})
+		resp, err := h.CreateUser(callerContext(), &authv1.CreateUserRequest{
+			Username: "jdoe", Fullname: "John Doe", Email: &email,
+			Password: "s3cret-pass", Status: "active", Metadata: metadata,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "jdoe", resp.Username)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		h := NewUserHandler(&mockUserService{createFn: func(string, string, *string, *string, string, string, datatypes.JSON, string, uuid.UUID) (*service.UserServiceDataResult, error) {
+			return nil, apperror.NewConflict("username already exists")
+		}})
+		_, err := h.CreateUser(callerContext(), &authv1.CreateUserRequest{
+			Username: "jdoe", Fullname: "John Doe", Password: "s3cret-pass", Status: "active",
+		})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+}
+
+func TestUserHandler_UpdateAndStatus(t *testing.T) {
+	id := uuid.New()
+
+	h := NewUserHandler(&mockUserService{
+		updateFn: func(u uuid.UUID, tenantID int64, username, _ string, _, _ *string, _ string, _ datatypes.JSON, updater uuid.UUID) (*service.UserServiceDataResult, error) {
+			assert.Equal(t, id, u)
+			assert.Equal(t, testTenant.TenantID, tenantID)
+			assert.Equal(t, "jdoe2", username)
+			assert.Equal(t, testCaller.UserUUID, updater)
+			return testUserResult(id), nil
+		},
+		setStatusFn: func(u uuid.UUID, tenantID int64, st string, updater uuid.UUID) (*service.UserServiceDataResult, error) {
+			assert.Equal(t, "suspended", st)
+			return testUserResult(id), nil
+		},
+	})
+
+	_, err := h.UpdateUser(callerContext(), &authv1.UpdateUserRequest{UserId: id.String(), Username: "jdoe2", Fullname: "John", Status: "active"})
+	require.NoError(t, err)
+
+	_, err = h.UpdateUser(callerContext(), &authv1.UpdateUserRequest{UserId: id.String()})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = h.SetUserStatus(callerContext(), &authv1.SetUserStatusRequest{UserId: id.String(), Status: "suspended"})
+	require.NoError(t, err)
+
+	_, err = h.SetUserStatus(callerContext(), &authv1.SetUserStatusRequest{UserId: id.String(), Status: "gone"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestUserHandler_DeleteUser(t *testing.T) {
+	id := uuid.New()
+	deletedAt := time.Now()
+	h := NewUserHandler(&mockUserService{deleteFn: func(u uuid.UUID, tenantID int64, deleter uuid.UUID) (*service.UserServiceDataResult, error) {
+		assert.Equal(t, testCaller.UserUUID, deleter)
+		res := testUserResult(u)
+		res.DeletedAt = &deletedAt
+		return res, nil
+	}})
+	resp, err := h.DeleteUser(callerContext(), &authv1.DeleteUserRequest{UserId: id.String()})
+	require.NoError(t, err)
+	assert.Equal(t, deletedAt.Unix(), resp.DeletedAt.AsTime().Unix())
+}
+
+func TestUserHandler_Roles(t *testing.T) {
+	id := uuid.New()
+	roleUUID := uuid.New()
+
+	t.Run("list checks tenant first", func(t *testing.T) {
+		h := NewUserHandler(&mockUserService{
+			getByUUIDFn: func(uuid.UUID, int64) (*service.UserServiceDataResult, error) {
+				return nil, apperror.NewNotFoundWithReason("user not found")
+			},
+			getRolesFn: func(uuid.UUID) ([]service.RoleServiceDataResult, error) {
+				t.Fatal("roles of another tenant's user must not be loaded")
+				return nil, nil
+			},
+		})
+		_, err := h.ListUserRoles(callerContext(), &authv1.ListUserRolesRequest{UserId: id.String()})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		h := NewUserHandler(&mockUserService{
+			getByUUIDFn: func(u uuid.UUID, _ int64) (*service.UserServiceDataResult, error) { return testUserResult(u), nil },
+			getRolesFn: func(uuid.UUID) ([]service.RoleServiceDataResult, error) {
+				return []service.RoleServiceDataResult{{RoleUUID: roleUUID, Name: "editor"}}, nil
+			},
+		})
+		resp, err := h.ListUserRoles(callerContext(), &authv1.ListUserRolesRequest{UserId: id.String()})
+		require.NoError(t, err)
+		require.Len(t, resp.Roles, 1)
+		assert.Equal(t, roleUUID.String(), resp.Roles[0].RoleId)
+	})
+
+	t.Run("assign", func(t *testing.T) {
+		h := NewUserHandler(&mockUserService{assignRolesFn: func(u uuid.UUID, roles []uuid.UUID, tenantID int64) (*service.UserServiceDataResult, error) {
+			assert.Equal(t, []uuid.UUID{roleUUID}, roles)
+			assert.Equal(t, testTenant.TenantID, tenantID)
+			return testUserResult(u), nil
+		}})
+		_, err := h.AssignUserRoles(callerContext(), &authv1.AssignUserRolesRequest{UserId: id.String(), RoleIds: []string{roleUUID.String()}})
+		require.NoError(t, err)
+
+		_, err = h.AssignUserRoles(callerContext(), &authv1.AssignUserRolesRequest{UserId: id.String()})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = h.AssignUserRoles(callerContext(), &authv1.AssignUserRolesRequest{UserId: id.String(), RoleIds: []string{"nope"}})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		h := NewUserHandler(&mockUserService{removeRoleFn: func(u, r uuid.UUID, tenantID int64) (*service.UserServiceDataResult, error) {
+			assert.Equal(t, roleUUID, r)
+			return testUserResult(u), nil
+		}})
+		_, err := h.RemoveUserRole(callerContext(), &authv1.RemoveUserRoleRequest{UserId: id.String(), RoleId: roleUUID.String()})
+		require.NoError(t, err)
+
+		_, err = h.RemoveUserRole(callerContext(), &authv1.RemoveUserRoleRequest{UserId: id.String(), RoleId: "nope"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
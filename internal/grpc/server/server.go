@@ -5,41 +5,115 @@ import (
 	"fmt"
 	"log/slog"
 	"net"
+	"time"
 
 	"github.com/maintainerd/auth/internal/app"
 	authv1 "github.com/maintainerd/auth/internal/gen/go/maintainerd/auth"
 	"github.com/maintainerd/auth/internal/grpc/handler"
+	"github.com/maintainerd/auth/internal/metrics"
+	"github.com/maintainerd/auth/internal/middleware"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 )
 
+// managementPermissions lists the permissions each management RPC requires,
+// matching the REST route it mirrors. RPCs not listed here are not
+// authenticated by GRPCAuthInterceptor.
+var managementPermissions = map[string][]string{
+	authv1.UserService_ListUsers_FullMethodName:       {"user:read"},
+	authv1.UserService_GetUser_FullMethodName:         {"user:read"},
+	authv1.UserService_CreateUser_FullMethodName:      {"user:create"},
+	authv1.UserService_UpdateUser_FullMethodName:      {"user:update"},
+	authv1.UserService_SetUserStatus_FullMethodName:   {"user:update"},
+	authv1.UserService_DeleteUser_FullMethodName:      {"user:delete"},
+	authv1.UserService_ListUserRoles_FullMethodName:   {"user:read"},
+	authv1.UserService_AssignUserRoles_FullMethodName: {"user:create"},
+	authv1.UserService_RemoveUserRole_FullMethodName:  {"user:create"},
+
+	authv1.RoleService_ListRoles_FullMethodName:            {"role:read"},
+	authv1.RoleService_GetRole_FullMethodName:              {"role:read"},
+	authv1.RoleService_CreateRole_FullMethodName:           {"role:create"},
+	authv1.RoleService_UpdateRole_FullMethodName:           {"role:update"},
+	authv1.RoleService_SetRoleStatus_FullMethodName:        {"role:update"},
+	authv1.RoleService_DeleteRole_FullMethodName:           {"role:delete"},
+	authv1.RoleService_ListRolePermissions_FullMethodName:  {"role:read"},
+	authv1.RoleService_AddRolePermissions_FullMethodName:   {"role:permission:create"},
+	authv1.RoleService_RemoveRolePermission_FullMethodName: {"role:permission:delete"},
+
+	authv1.PermissionService_ListPermissions_FullMethodName:     {"permission:read"},
+	authv1.PermissionService_GetPermission_FullMethodName:       {"permission:read"},
+	authv1.PermissionService_CreatePermission_FullMethodName:    {"permission:create"},
+	authv1.PermissionService_UpdatePermission_FullMethodName:    {"permission:update"},
+	authv1.PermissionService_SetPermissionStatus_FullMethodName: {"permission:update"},
+	authv1.PermissionService_DeletePermission_FullMethodName:    {"permission:delete"},
+}
+
 // StartGRPCServer binds to :50051 and serves until ctx is cancelled, at which
-// point it drains in-flight RPCs via GracefulStop. It returns an error for any
-// fatal startup failure so that main() can handle it appropriately instead of
-// calling os.Exit inside a library function.
-func StartGRPCServer(ctx context.Context, application *app.App) error {
+// point it drains in-flight RPCs via GracefulStop. If draining takes longer
+// than shutdownTimeout the server is stopped forcefully. It returns only once
+// the server has fully stopped, and returns an error for any fatal startup
+// failure so that main() can handle it instead of calling os.Exit inside a
+// library function.
+func StartGRPCServer(ctx context.Context, application *app.App, shutdownTimeout time.Duration) error {
 	lis, err := net.Listen("tcp", ":50051")
 	if err != nil {
 		return fmt.Errorf("gRPC failed to listen on :50051: %w", err)
 	}
 
 	seederHandler := handler.NewSeederHandler(application.RegisterService)
+	tokenHandler := handler.NewTokenHandler(application.TokenValidationService)
+	userHandler := handler.NewUserHandler(application.UserService)
+	roleHandler := handler.NewRoleHandler(application.RoleService)
+	permissionHandler := handler.NewPermissionHandler(application.PermissionService)
 
 	s := grpc.NewServer(
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			metrics.UnaryServerInterceptor(),
+			middleware.GRPCAuthInterceptor(application.UserService, application.Cache, application.AuthzAuditService, managementPermissions),
+		),
 	)
+
 	authv1.RegisterSeederServiceServer(s, seederHandler)
+	authv1.RegisterTokenServiceServer(s, tokenHandler)
+	authv1.RegisterUserServiceServer(s, userHandler)
+	authv1.RegisterRoleServiceServer(s, roleHandler)
+	authv1.RegisterPermissionServiceServer(s, permissionHandler)
 
-	// Stop the server when the context is cancelled (e.g. after REST servers drain).
+	// Stop the server when the context is cancelled (e.g. on SIGTERM).
+	stopped := make(chan struct{})
 	go func() {
+		defer close(stopped)
 		<-ctx.Done()
-		slog.Info("gRPC shutdown signal received, draining connections...")
-		s.GracefulStop()
+		slog.Info("gRPC shutdown signal received, draining connections...", "timeout", shutdownTimeout.String())
+		gracefulStop(s, shutdownTimeout)
 	}()
 
 	slog.Info("gRPC server starting", "addr", ":50051")
 	if err := s.Serve(lis); err != nil {
 		return fmt.Errorf("gRPC server failed: %w", err)
 	}
+
+	// Serve returns as soon as GracefulStop begins; wait for the drain.
+	<-stopped
+	slog.Info("gRPC server stopped cleanly")
 	return nil
 }
+
+// gracefulStop drains in-flight RPCs, falling back to a hard Stop once
+// timeout elapses so a stuck stream cannot block process exit.
+func gracefulStop(s *grpc.Server, timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		slog.Warn("gRPC graceful stop timed out, forcing shutdown")
+		s.Stop()
+		<-done
+	}
+}
@@ -0,0 +1,46 @@
+// Package hook evaluates tenant-defined login flow hooks. A hook is either an
+// embedded rule evaluated in-process or an HTTP webhook. Both receive the same
+// Payload and produce a Result that can deny the flow or contribute claims.
+package hook
+
+// Payload is the context a hook runs against. It is sent as the JSON body of
+// webhook hooks and is the document rule conditions are evaluated on.
+type Payload struct {
+	Trigger string  `json:"trigger"`
+	User    User    `json:"user"`
+	Client  Client  `json:"client"`
+	Request Request `json:"request"`
+}
+
+// User describes the user being authenticated or registered. UUID and Status
+// are empty for pre-registration hooks because the user does not exist yet.
+type User struct {
+	UUID          string `json:"uuid,omitempty"`
+	Username      string `json:"username"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified bool   `json:"email_verified"`
+	Phone         string `json:"phone,omitempty"`
+	PhoneVerified bool   `json:"phone_verified"`
+	Status        string `json:"status,omitempty"`
+}
+
+// Client identifies the auth client the flow was started from.
+type Client struct {
+	ClientID string `json:"client_id"`
+	Name     string `json:"name"`
+}
+
+// Request carries details of the originating HTTP request.
+type Request struct {
+	IPAddress string `json:"ip_address"`
+	UserAgent string `json:"user_agent"`
+}
+
+// Result is the outcome of a single hook. When Allow is false the flow is
+// stopped and Message is shown to the user. Claims are only honoured for
+// post-login hooks.
+type Result struct {
+	Allow   bool           `json:"allow"`
+	Message string         `json:"message,omitempty"`
+	Claims  map[string]any `json:"claims,omitempty"`
+}
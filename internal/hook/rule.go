@@ -0,0 +1,211 @@
+package hook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Rule match modes.
+const (
+	MatchAll = "all"
+	MatchAny = "any"
+)
+
+// Rule actions.
+const (
+	ActionAllow = "allow"
+	ActionDeny  = "deny"
+)
+
+// Condition operators. String comparisons are case-insensitive so that rules
+// on email domains behave as users expect.
+const (
+	OpEquals     = "eq"
+	OpNotEquals  = "neq"
+	OpIn         = "in"
+	OpNotIn      = "not_in"
+	OpContains   = "contains"
+	OpStartsWith = "starts_with"
+	OpEndsWith   = "ends_with"
+	OpExists     = "exists"
+	OpNotExists  = "not_exists"
+)
+
+// Condition tests one payload field. Field is a dot-separated path into the
+// JSON form of Payload, for example "user.email" or "request.ip_address".
+type Condition struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    any    `json:"value,omitempty"`
+}
+
+// Rule is an embedded hook. When its conditions match, Action decides the
+// outcome: deny stops the flow with Message, allow contributes Claims. A rule
+// whose conditions do not match always allows. A rule without conditions
+// always matches.
+type Rule struct {
+	Match      string         `json:"match,omitempty"`
+	Conditions []Condition    `json:"conditions"`
+	Action     string         `json:"action"`
+	Message    string         `json:"message,omitempty"`
+	Claims     map[string]any `json:"claims,omitempty"`
+}
+
+// ParseRule decodes and validates a rule definition.
+func ParseRule(data []byte) (*Rule, error) {
+	var rule Rule
+	if err := json.Unmarshal(data, &rule); err != nil {
+		return nil, fmt.Errorf("invalid rule: %w", err)
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// Validate reports whether the rule is well formed.
+func (r Rule) Validate() error {
+	if r.Match != "" && r.Match != MatchAll && r.Match != MatchAny {
+		return fmt.Errorf("invalid rule: match must be %q or %q", MatchAll, MatchAny)
+	}
+	switch r.Action {
+	case ActionDeny:
+		if len(r.Claims) > 0 {
+			return errors.New("invalid rule: claims cannot be set on a deny rule")
+		}
+	case ActionAllow:
+	default:
+		return fmt.Errorf("invalid rule: action must be %q or %q", ActionAllow, ActionDeny)
+	}
+	for i, c := range r.Conditions {
+		if strings.TrimSpace(c.Field) == "" {
+			return fmt.Errorf("invalid rule: condition %d has no field", i)
+		}
+		switch c.Operator {
+		case OpEquals, OpNotEquals, OpContains, OpStartsWith, OpEndsWith:
+			if _, ok := scalarString(c.Value); !ok {
+				return fmt.Errorf("invalid rule: condition %d needs a scalar value", i)
+			}
+		case OpIn, OpNotIn:
+			if _, ok := c.Value.([]any); !ok {
+				return fmt.Errorf("invalid rule: condition %d needs a list value", i)
+			}
+		case OpExists, OpNotExists:
+		default:
+			return fmt.Errorf("invalid rule: condition %d has unknown operator %q", i, c.Operator)
+		}
+	}
+	return nil
+}
+
+// Evaluate runs the rule against payload.
+func (r Rule) Evaluate(payload Payload) Result {
+	if !r.matches(payloadDocument(payload)) {
+		return Result{Allow: true}
+	}
+	if r.Action == ActionDeny {
+		return Result{Allow: false, Message: r.Message}
+	}
+	return Result{Allow: true, Claims: r.Claims}
+}
+
+func (r Rule) matches(doc map[string]any) bool {
+	if len(r.Conditions) == 0 {
+		return true
+	}
+	anyMode := r.Match == MatchAny
+	for _, c := range r.Conditions {
+		ok := c.matches(doc)
+		if anyMode && ok {
+			return true
+		}
+		if !anyMode && !ok {
+			return false
+		}
+	}
+	return !anyMode
+}
+
+func (c Condition) matches(doc map[string]any) bool {
+	raw, found := lookup(doc, c.Field)
+	switch c.Operator {
+	case OpExists:
+		return found && raw != nil && raw != ""
+	case OpNotExists:
+		return !found || raw == nil || raw == ""
+	}
+
+	actual, ok := scalarString(raw)
+	if !found || !ok {
+		// Missing fields only satisfy negative operators.
+		return c.Operator == OpNotEquals || c.Operator == OpNotIn
+	}
+	actual = strings.ToLower(actual)
+
+	switch c.Operator {
+	case OpIn, OpNotIn:
+		list, _ := c.Value.([]any)
+		in := false
+		for _, item := range list {
+			if s, ok := scalarString(item); ok && strings.ToLower(s) == actual {
+				in = true
+				break
+			}
+		}
+		return in == (c.Operator == OpIn)
+	}
+
+	expected, _ := scalarString(c.Value)
+	expected = strings.ToLower(expected)
+	switch c.Operator {
+	case OpEquals:
+		return actual == expected
+	case OpNotEquals:
+		return actual != expected
+	case OpContains:
+		return strings.Contains(actual, expected)
+	case OpStartsWith:
+		return strings.HasPrefix(actual, expected)
+	case OpEndsWith:
+		return strings.HasSuffix(actual, expected)
+	}
+	return false
+}
+
+// payloadDocument converts payload to the generic JSON form that condition
+// fields address.
+func payloadDocument(payload Payload) map[string]any {
+	// Marshalling a struct of strings and bools cannot fail.
+	data, _ := json.Marshal(payload)
+	var doc map[string]any
+	_ = json.Unmarshal(data, &doc)
+	return doc
+}
+
+func lookup(doc map[string]any, field string) (any, bool) {
+	var current any = doc
+	for _, part := range strings.Split(field, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// scalarString renders strings, numbers and booleans for comparison.
+func scalarString(v any) (string, bool) {
+	switch t := v.(type) {
+	case string:
+		return t, true
+	case bool, float64, int, int64:
+		return fmt.Sprint(t), true
+	default:
+		return "", false
+	}
+}
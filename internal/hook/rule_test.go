@@ -0,0 +1,132 @@
+package hook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPayload() Payload {
+	return Payload{
+		Trigger: "pre_login",
+		User: User{
+			UUID:          "5f0c",
+			Username:      "alice",
+			Email:         "Alice@Example.com",
+			EmailVerified: true,
+			Status:        "active",
+		},
+		Client:  Client{ClientID: "web", Name: "Web"},
+		Request: Request{IPAddress: "10.0.0.1", UserAgent: "curl"},
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	t.Run("valid rule", func(t *testing.T) {
+		rule, err := ParseRule([]byte(`{"conditions":[{"field":"user.email","operator":"ends_with","value":"@example.com"}],"action":"deny","message":"no"}`))
+		require.NoError(t, err)
+		assert.Equal(t, ActionDeny, rule.Action)
+		require.Len(t, rule.Conditions, 1)
+	})
+
+	t.Run("malformed JSON", func(t *testing.T) {
+		_, err := ParseRule([]byte(`{`))
+		require.Error(t, err)
+	})
+
+	cases := map[string]string{
+		"unknown action":          `{"action":"maybe"}`,
+		"unknown match":           `{"action":"allow","match":"some"}`,
+		"claims on deny":          `{"action":"deny","claims":{"tier":"gold"}}`,
+		"missing field":           `{"action":"allow","conditions":[{"operator":"exists"}]}`,
+		"unknown operator":        `{"action":"allow","conditions":[{"field":"user.email","operator":"like","value":"x"}]}`,
+		"scalar operator on list": `{"action":"allow","conditions":[{"field":"user.email","operator":"eq","value":["x"]}]}`,
+		"list operator on scalar": `{"action":"allow","conditions":[{"field":"user.email","operator":"in","value":"x"}]}`,
+	}
+	for name, def := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseRule([]byte(def))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid rule")
+		})
+	}
+}
+
+func TestRule_Evaluate(t *testing.T) {
+	payload := testPayload()
+
+	t.Run("deny when conditions match", func(t *testing.T) {
+		rule := Rule{
+			Conditions: []Condition{{Field: "user.email", Operator: OpEndsWith, Value: "@example.com"}},
+			Action:     ActionDeny,
+			Message:    "Example staff must use SSO",
+		}
+		res := rule.Evaluate(payload)
+		assert.False(t, res.Allow)
+		assert.Equal(t, "Example staff must use SSO", res.Message)
+	})
+
+	t.Run("allow when conditions do not match", func(t *testing.T) {
+		rule := Rule{
+			Conditions: []Condition{{Field: "client.client_id", Operator: OpEquals, Value: "mobile"}},
+			Action:     ActionDeny,
+		}
+		assert.Equal(t, Result{Allow: true}, rule.Evaluate(payload))
+	})
+
+	t.Run("allow contributes claims", func(t *testing.T) {
+		rule := Rule{Action: ActionAllow, Claims: map[string]any{"tier": "gold"}}
+		res := rule.Evaluate(payload)
+		assert.True(t, res.Allow)
+		assert.Equal(t, map[string]any{"tier": "gold"}, res.Claims)
+	})
+
+	t.Run("all requires every condition", func(t *testing.T) {
+		rule := Rule{
+			Conditions: []Condition{
+				{Field: "user.email_verified", Operator: OpEquals, Value: true},
+				{Field: "request.ip_address", Operator: OpStartsWith, Value: "192.168."},
+			},
+			Action: ActionDeny,
+		}
+		assert.True(t, rule.Evaluate(payload).Allow)
+	})
+
+	t.Run("any requires one condition", func(t *testing.T) {
+		rule := Rule{
+			Match: MatchAny,
+			Conditions: []Condition{
+				{Field: "user.email_verified", Operator: OpEquals, Value: false},
+				{Field: "request.ip_address", Operator: OpStartsWith, Value: "10."},
+			},
+			Action: ActionDeny,
+		}
+		assert.False(t, rule.Evaluate(payload).Allow)
+	})
+
+	operators := []struct {
+		name string
+		cond Condition
+		want bool
+	}{
+		{"eq is case-insensitive", Condition{Field: "user.email", Operator: OpEquals, Value: "alice@example.com"}, true},
+		{"neq", Condition{Field: "user.username", Operator: OpNotEquals, Value: "bob"}, true},
+		{"neq on missing field", Condition{Field: "user.nickname", Operator: OpNotEquals, Value: "bob"}, true},
+		{"eq on missing field", Condition{Field: "user.nickname", Operator: OpEquals, Value: "bob"}, false},
+		{"in", Condition{Field: "client.client_id", Operator: OpIn, Value: []any{"web", "cli"}}, true},
+		{"not_in", Condition{Field: "client.client_id", Operator: OpNotIn, Value: []any{"web", "cli"}}, false},
+		{"contains", Condition{Field: "request.user_agent", Operator: OpContains, Value: "CURL"}, true},
+		{"exists", Condition{Field: "user.uuid", Operator: OpExists}, true},
+		{"exists on empty", Condition{Field: "user.phone", Operator: OpExists}, false},
+		{"not_exists", Condition{Field: "user.phone", Operator: OpNotExists}, true},
+		{"path through scalar", Condition{Field: "user.email.domain", Operator: OpExists}, false},
+		{"object is not comparable", Condition{Field: "user", Operator: OpEquals, Value: "x"}, false},
+	}
+	for _, tc := range operators {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := Rule{Conditions: []Condition{tc.cond}, Action: ActionDeny}
+			assert.Equal(t, tc.want, !rule.Evaluate(payload).Allow)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maintainerd/auth/internal/logging"
+)
+
+// Headers sent with every webhook call. The signature is only sent when the
+// hook has a secret.
+const (
+	HeaderTimestamp = "X-Maintainerd-Timestamp"
+	HeaderSignature = "X-Maintainerd-Signature"
+)
+
+// maxResponseBytes bounds how much of a webhook response is read.
+const maxResponseBytes = 64 << 10
+
+// webhookResponse is the body a webhook returns. A missing allow field is
+// treated as allow so that enrichment-only webhooks can omit it.
+type webhookResponse struct {
+	Allow   *bool          `json:"allow"`
+	Message string         `json:"message"`
+	Claims  map[string]any `json:"claims"`
+}
+
+// Sign returns the signature header value for body sent at timestamp:
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>".
+// Receivers should recompute it and reject stale timestamps.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// DefaultSignatureTolerance is how far a signed timestamp may drift from the
+// receiver's clock before Verify rejects the request as a possible replay.
+const DefaultSignatureTolerance = 5 * time.Minute
+
+// Signature verification errors returned by Verify.
+var (
+	ErrSignatureTimestamp = errors.New("webhook signature timestamp missing or outside tolerance")
+	ErrSignatureMismatch  = errors.New("webhook signature does not match")
+)
+
+// SignAll signs body with every secret and returns the signatures separated
+// by spaces. During a secret rotation the sender signs with both the new and
+// the previous secret so that receivers holding either one can verify.
+func SignAll(secrets []string, timestamp int64, body []byte) string {
+	sigs := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		sigs = append(sigs, Sign(secret, timestamp, body))
+	}
+	return strings.Join(sigs, " ")
+}
+
+// Verify checks the timestamp and signature headers of a received webhook
+// against body. The timestamp must be within tolerance of now, and any one
+// of the space separated signatures must match secret.
+func Verify(secret, timestampHeader, signatureHeader string, body []byte, tolerance time.Duration, now time.Time) error {
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return ErrSignatureTimestamp
+	}
+	if drift := now.Sub(time.Unix(timestamp, 0)); drift > tolerance || drift < -tolerance {
+		return ErrSignatureTimestamp
+	}
+
+	expected := []byte(Sign(secret, timestamp, body))
+	for _, sig := range strings.Fields(signatureHeader) {
+		if hmac.Equal([]byte(sig), expected) {
+			return nil
+		}
+	}
+	return ErrSignatureMismatch
+}
+
+// CallWebhook POSTs payload to url and decodes the hook result. Any transport
+// error, non-2xx status or undecodable body is returned as an error so the
+// caller can apply the hook's failure policy. The timeout bounds the whole
+// call, including reading the response.
+func CallWebhook(ctx context.Context, client *http.Client, url, secret string, timeout time.Duration, payload Payload) (Result, error) {
+	start := time.Now()
+	result, err := callWebhook(ctx, client, url, secret, timeout, payload)
+
+	logger := logging.Logger(logging.ComponentWebhooks)
+	attrs := []any{"url", url, "trigger", payload.Trigger, "duration_ms", time.Since(start).Milliseconds()}
+	if err != nil {
+		logger.WarnContext(ctx, "webhook call failed", append(attrs, "error", err)...)
+		return Result{}, err
+	}
+	logger.DebugContext(ctx, "webhook called", append(attrs, "allow", result.Allow)...)
+	return result, nil
+}
+
+func callWebhook(ctx context.Context, client *http.Client, url, secret string, timeout time.Duration, payload Payload) (Result, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return Result{}, fmt.Errorf("encode hook payload: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return Result{}, fmt.Errorf("build hook request: %w", err)
+	}
+	now := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "maintainerd-auth")
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now, 10))
+	if secret != "" {
+		req.Header.Set(HeaderSignature, Sign(secret, now, body))
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("hook request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return Result{}, fmt.Errorf("hook request failed: unexpected status %d", res.StatusCode)
+	}
+
+	var decoded webhookResponse
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxResponseBytes)).Decode(&decoded); err != nil {
+		return Result{}, fmt.Errorf("decode hook response: %w", err)
+	}
+
+	return Result{
+		Allow:   decoded.Allow == nil || *decoded.Allow,
+		Message: decoded.Message,
+		Claims:  decoded.Claims,
+	}, nil
+}
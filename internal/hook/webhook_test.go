@@ -0,0 +1,157 @@
+package hook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newHookServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSign(t *testing.T) {
+	a := Sign("secret", 1700000000, []byte(`{}`))
+	assert.Regexp(t, "^sha256=[0-9a-f]{64}$", a)
+	assert.Equal(t, a, Sign("secret", 1700000000, []byte(`{}`)))
+	assert.NotEqual(t, a, Sign("secret", 1700000001, []byte(`{}`)))
+	assert.NotEqual(t, a, Sign("other", 1700000000, []byte(`{}`)))
+}
+
+func TestSignAll(t *testing.T) {
+	body := []byte(`{}`)
+	assert.Equal(t, Sign("new", 1700000000, body), SignAll([]string{"new"}, 1700000000, body))
+	assert.Equal(t,
+		Sign("new", 1700000000, body)+" "+Sign("old", 1700000000, body),
+		SignAll([]string{"new", "old"}, 1700000000, body))
+	assert.Empty(t, SignAll(nil, 1700000000, body))
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	now := time.Unix(1700000000, 0)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	rotated := SignAll([]string{"new", "old"}, now.Unix(), body)
+
+	tests := []struct {
+		name   string
+		secret string
+		ts     string
+		sig    string
+		body   []byte
+		now    time.Time
+		want   error
+	}{
+		{"valid", "new", ts, Sign("new", now.Unix(), body), body, now, nil},
+		{"previous secret during rotation", "old", ts, rotated, body, now, nil},
+		{"new secret during rotation", "new", ts, rotated, body, now, nil},
+		{"within tolerance", "new", ts, Sign("new", now.Unix(), body), body, now.Add(4 * time.Minute), nil},
+		{"stale timestamp", "new", ts, Sign("new", now.Unix(), body), body, now.Add(6 * time.Minute), ErrSignatureTimestamp},
+		{"future timestamp", "new", ts, Sign("new", now.Unix(), body), body, now.Add(-6 * time.Minute), ErrSignatureTimestamp},
+		{"missing timestamp", "new", "", Sign("new", now.Unix(), body), body, now, ErrSignatureTimestamp},
+		{"wrong secret", "other", ts, rotated, body, now, ErrSignatureMismatch},
+		{"tampered body", "new", ts, Sign("new", now.Unix(), body), []byte(`{"id":"2"}`), now, ErrSignatureMismatch},
+		{"missing signature", "new", ts, "", body, now, ErrSignatureMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.ts, tt.sig, tt.body, DefaultSignatureTolerance, tt.now)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestCallWebhook(t *testing.T) {
+	ctx := context.Background()
+	payload := testPayload()
+
+	t.Run("sends signed payload and decodes result", func(t *testing.T) {
+		srv := newHookServer(t, func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			ts, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+			require.NoError(t, err)
+			assert.Equal(t, Sign("s3cret", ts, body), r.Header.Get(HeaderSignature))
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+			var got Payload
+			require.NoError(t, json.Unmarshal(body, &got))
+			assert.Equal(t, payload, got)
+
+			_, _ = w.Write([]byte(`{"allow":true,"claims":{"tier":"gold"}}`))
+		})
+
+		res, err := CallWebhook(ctx, srv.Client(), srv.URL, "s3cret", time.Second, payload)
+		require.NoError(t, err)
+		assert.True(t, res.Allow)
+		assert.Equal(t, map[string]any{"tier": "gold"}, res.Claims)
+	})
+
+	t.Run("no signature without secret", func(t *testing.T) {
+		srv := newHookServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Empty(t, r.Header.Get(HeaderSignature))
+			_, _ = w.Write([]byte(`{}`))
+		})
+
+		res, err := CallWebhook(ctx, srv.Client(), srv.URL, "", time.Second, payload)
+		require.NoError(t, err)
+		assert.True(t, res.Allow, "missing allow means allow")
+	})
+
+	t.Run("deny with message", func(t *testing.T) {
+		srv := newHookServer(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"allow":false,"message":"Account under review"}`))
+		})
+
+		res, err := CallWebhook(ctx, srv.Client(), srv.URL, "", time.Second, payload)
+		require.NoError(t, err)
+		assert.False(t, res.Allow)
+		assert.Equal(t, "Account under review", res.Message)
+	})
+
+	t.Run("non-2xx status is an error", func(t *testing.T) {
+		srv := newHookServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		})
+
+		_, err := CallWebhook(ctx, srv.Client(), srv.URL, "", time.Second, payload)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "502")
+	})
+
+	t.Run("undecodable body is an error", func(t *testing.T) {
+		srv := newHookServer(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`not json`))
+		})
+
+		_, err := CallWebhook(ctx, srv.Client(), srv.URL, "", time.Second, payload)
+		require.Error(t, err)
+	})
+
+	t.Run("timeout is an error", func(t *testing.T) {
+		srv := newHookServer(t, func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		})
+
+		_, err := CallWebhook(ctx, srv.Client(), srv.URL, "", 20*time.Millisecond, payload)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid URL", func(t *testing.T) {
+		_, err := CallWebhook(ctx, http.DefaultClient, "://bad", "", time.Second, payload)
+		require.Error(t, err)
+	})
+}
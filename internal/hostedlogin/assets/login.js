@@ -0,0 +1,70 @@
+(function () {
+  "use strict";
+
+  var form = document.getElementById("login-form");
+  var errorBox = document.getElementById("login-error");
+  var button = form.querySelector("button[type=submit]");
+
+  function showError(message) {
+    errorBox.textContent = message || "Sign in failed. Please try again.";
+    errorBox.hidden = false;
+  }
+
+  function request(url, options) {
+    options.credentials = "same-origin";
+    return fetch(url, options).then(function (res) {
+      return res.json().catch(function () { return {}; }).then(function (body) {
+        if (!res.ok) {
+          throw new Error(body.message || body.error_description || body.error);
+        }
+        return body.data || {};
+      });
+    });
+  }
+
+  function continueAuthorize() {
+    var authorizeURL = form.dataset.authorizeUrl;
+    if (!authorizeURL) {
+      form.hidden = true;
+      showError("You are signed in. You can close this window.");
+      errorBox.classList.add("success");
+      return Promise.resolve();
+    }
+
+    return request(authorizeURL, { method: "GET" }).then(function (data) {
+      if (data.redirect_uri) {
+        window.location.assign(data.redirect_uri);
+        return;
+      }
+      if (data.consent_challenge && form.dataset.consentUrl) {
+        var consent = new URL(form.dataset.consentUrl, window.location.href);
+        consent.searchParams.set("consent_challenge", data.consent_challenge);
+        window.location.assign(consent.toString());
+        return;
+      }
+      throw new Error("This application requires consent, which cannot be given here.");
+    });
+  }
+
+  form.addEventListener("submit", function (event) {
+    event.preventDefault();
+    errorBox.hidden = true;
+    button.disabled = true;
+
+    request(form.dataset.loginUrl, {
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "X-Token-Delivery": "cookie",
+        "X-CSRF-Token": form.dataset.csrfToken
+      },
+      body: JSON.stringify({
+        username: form.elements.username.value,
+        password: form.elements.password.value
+      })
+    })
+      .then(continueAuthorize)
+      .catch(function (err) { showError(err.message); })
+      .then(function () { button.disabled = false; });
+  });
+})();
@@ -0,0 +1,28 @@
+(function () {
+  "use strict";
+
+  // The page loads the front-channel logout URIs of the session's clients in
+  // hidden iframes. Once they have loaded, or after a timeout for clients
+  // that never answer, the user continues to the post-logout redirect URI.
+  var page = document.getElementById("logout");
+  var redirectURL = page.dataset.redirectUrl;
+  if (!redirectURL) {
+    return;
+  }
+
+  var done = false;
+  function proceed() {
+    if (done) {
+      return;
+    }
+    done = true;
+    window.location.assign(redirectURL);
+  }
+
+  if (document.readyState === "complete") {
+    proceed();
+  } else {
+    window.addEventListener("load", proceed);
+  }
+  window.setTimeout(proceed, 5000);
+})();
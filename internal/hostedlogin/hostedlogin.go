@@ -0,0 +1,189 @@
+// Package hostedlogin renders the tenant-branded login page served to end
+// users on the identity port. The page posts credentials to the public login
+// endpoint with cookie token delivery and, when started from an OAuth2
+// authorization request, continues the authorize flow with the new session.
+// It also renders the signed-out page of the OIDC end_session endpoint.
+//
+// Scripts and styles are served from embedded assets so the page works under
+// the default Content-Security-Policy (script-src 'self').
+package hostedlogin
+
+import (
+	"embed"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+)
+
+//go:embed templates
+var templates embed.FS
+
+//go:embed assets
+var assets embed.FS
+
+var (
+	pageTemplate   = template.Must(template.ParseFS(templates, "templates/login.html"))
+	logoutTemplate = template.Must(template.ParseFS(templates, "templates/logout.html"))
+)
+
+// Page is the data the login template is rendered with. Zero-value text
+// fields fall back to defaults in Render.
+type Page struct {
+	// Style is the login template style (modern, classic, minimal, ...).
+	Style string
+
+	Title             string
+	Subtitle          string
+	CompanyName       string
+	LogoURL           string
+	FaviconURL        string
+	PrimaryColor      string
+	BackgroundColor   string
+	FontFamily        string
+	CustomCSS         string
+	UsernameLabel     string
+	PasswordLabel     string
+	SubmitLabel       string
+	SupportURL        string
+	PrivacyPolicyURL  string
+	TermsOfServiceURL string
+
+	// AssetsURL is the base URL the page's script and stylesheet are served
+	// from, ending in a slash.
+	AssetsURL string
+	// LoginURL is the public login endpoint including client_id and
+	// provider_id.
+	LoginURL string
+	// AuthorizeURL is the OAuth2 authorize endpoint including the original
+	// request parameters. Empty when the page was not opened from an
+	// authorization request.
+	AuthorizeURL string
+	// ConsentURL receives the consent_challenge query parameter when the
+	// authorize step requires consent.
+	ConsentURL string
+	// CSRFToken is sent in the X-CSRF-Token header of the login request.
+	// Empty when the page is served without a hosted session.
+	CSRFToken string
+}
+
+// LogoutPage is the data the signed-out page is rendered with.
+type LogoutPage struct {
+	// AssetsURL is the base URL the page's script and stylesheet are served
+	// from, ending in a slash.
+	AssetsURL string
+	// FrontChannelLogoutURIs are loaded in hidden iframes, so that each
+	// client of the ended session can clear its own state.
+	FrontChannelLogoutURIs []string
+	// RedirectURI is where the user is sent once the iframes have loaded.
+	// Empty to stay on the page.
+	RedirectURI string
+}
+
+// Defaults applied by Render to empty Page fields.
+const (
+	defaultStyle         = "modern"
+	defaultTitle         = "Sign in"
+	defaultUsernameLabel = "Username or email"
+	defaultPasswordLabel = "Password"
+	defaultSubmitLabel   = "Sign in"
+	defaultPrimaryColor  = "#2563eb"
+	defaultBackground    = "#f8fafc"
+)
+
+// styles lists the template styles that have a stylesheet variant. Unknown
+// styles (including "custom") render with the default style and rely on the
+// tenant's custom CSS.
+var styles = map[string]bool{
+	"modern":    true,
+	"classic":   true,
+	"minimal":   true,
+	"corporate": true,
+	"creative":  true,
+}
+
+var (
+	colorPattern = regexp.MustCompile(`^(#[0-9a-fA-F]{3,8}|[a-zA-Z]{3,20})$`)
+	fontPattern  = regexp.MustCompile(`^[a-zA-Z0-9 ,'"-]{1,100}$`)
+)
+
+// Render writes the login page for p to w.
+func Render(w io.Writer, p Page) error {
+	if !styles[p.Style] {
+		p.Style = defaultStyle
+	}
+	p.Title = orDefault(p.Title, defaultTitle)
+	p.UsernameLabel = orDefault(p.UsernameLabel, defaultUsernameLabel)
+	p.PasswordLabel = orDefault(p.PasswordLabel, defaultPasswordLabel)
+	p.SubmitLabel = orDefault(p.SubmitLabel, defaultSubmitLabel)
+
+	return pageTemplate.Execute(w, struct {
+		Page
+		Theme template.CSS
+	}{
+		Page:  p,
+		Theme: theme(p),
+	})
+}
+
+// RenderLogout writes the signed-out page for p to w.
+func RenderLogout(w io.Writer, p LogoutPage) error {
+	return logoutTemplate.Execute(w, struct {
+		LogoutPage
+		Theme template.CSS
+	}{
+		LogoutPage: p,
+		Theme:      theme(Page{}),
+	})
+}
+
+// Assets serves the page's script and stylesheet by file name, so it can be
+// mounted under any prefix. Unknown names return 404.
+func Assets() http.Handler {
+	// "assets" is embedded above, so Sub cannot fail.
+	sub, _ := fs.Sub(assets, "assets")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := path.Base(r.URL.Path)
+		if _, err := fs.Stat(sub, name); err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFileFS(w, r, sub, name)
+	})
+}
+
+// theme builds the page's CSS custom properties and appends the tenant's
+// custom CSS. Values that do not look like colours or font names are
+// dropped, and "<" is escaped so custom CSS cannot close the style element.
+func theme(p Page) template.CSS {
+	var b strings.Builder
+	b.WriteString(":root{")
+	b.WriteString("--primary:" + safe(colorPattern, p.PrimaryColor, defaultPrimaryColor) + ";")
+	b.WriteString("--background:" + safe(colorPattern, p.BackgroundColor, defaultBackground) + ";")
+	if font := safe(fontPattern, p.FontFamily, ""); font != "" {
+		b.WriteString("--font:" + font + ";")
+	}
+	b.WriteString("}")
+	if p.CustomCSS != "" {
+		b.WriteString("\n")
+		b.WriteString(strings.ReplaceAll(p.CustomCSS, "<", `\3c `))
+	}
+	return template.CSS(b.String())
+}
+
+func safe(pattern *regexp.Regexp, value, fallback string) string {
+	if value != "" && pattern.MatchString(value) {
+		return value
+	}
+	return fallback
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,113 @@
+package hostedlogin
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func render(t *testing.T, p Page) string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, p))
+	return buf.String()
+}
+
+func TestRender_Defaults(t *testing.T) {
+	html := render(t, Page{
+		AssetsURL: "/api/v1/login/assets/",
+		LoginURL:  "/api/v1/login?client_id=web&provider_id=idp",
+		CSRFToken: "csrf-token",
+	})
+
+	assert.Contains(t, html, `<body class="style-modern">`)
+	assert.Contains(t, html, "<h1>Sign in</h1>")
+	assert.Contains(t, html, "Username or email")
+	assert.Contains(t, html, `src="/api/v1/login/assets/login.js"`)
+	assert.Contains(t, html, `data-login-url="/api/v1/login?client_id=web&amp;provider_id=idp"`)
+	assert.Contains(t, html, `data-csrf-token="csrf-token"`)
+	assert.Contains(t, html, "--primary:"+defaultPrimaryColor)
+	assert.NotContains(t, html, "<footer>")
+}
+
+func TestRender_Branding(t *testing.T) {
+	html := render(t, Page{
+		Style:        "corporate",
+		Title:        "Welcome back",
+		CompanyName:  "Acme",
+		LogoURL:      "https://cdn.example.com/logo.png",
+		PrimaryColor: "#ff0000",
+		FontFamily:   "Inter, sans-serif",
+		SubmitLabel:  "Continue",
+		SupportURL:   "https://help.example.com",
+	})
+
+	assert.Contains(t, html, `<body class="style-corporate">`)
+	assert.Contains(t, html, "<h1>Welcome back</h1>")
+	assert.Contains(t, html, `src="https://cdn.example.com/logo.png"`)
+	assert.Contains(t, html, "--primary:#ff0000")
+	assert.Contains(t, html, "--font:Inter, sans-serif")
+	assert.Contains(t, html, ">Continue</button>")
+	assert.Contains(t, html, `href="https://help.example.com"`)
+}
+
+func TestRender_UnknownStyleFallsBack(t *testing.T) {
+	html := render(t, Page{Style: "custom"})
+	assert.Contains(t, html, `<body class="style-modern">`)
+}
+
+func TestRender_Escaping(t *testing.T) {
+	html := render(t, Page{
+		Title:        `<script>alert(1)</script>`,
+		LogoURL:      "javascript:alert(1)",
+		PrimaryColor: "red;}body{display:none",
+		FontFamily:   "x;}</style><script>",
+		CustomCSS:    `.card{color:red}</style><script>alert(1)</script>`,
+	})
+
+	assert.NotContains(t, html, "<script>alert(1)</script>")
+	assert.NotContains(t, html, "javascript:alert")
+	assert.NotContains(t, html, "display:none")
+	assert.Contains(t, html, "--primary:"+defaultPrimaryColor)
+	assert.NotContains(t, html, "--font:")
+	assert.Contains(t, html, `.card{color:red}\3c /style>`)
+}
+
+func TestRenderLogout(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, RenderLogout(&buf, LogoutPage{
+		AssetsURL:              "/api/v1/login/assets/",
+		FrontChannelLogoutURIs: []string{"https://app.example.com/logout?iss=https%3A%2F%2Fauth.example.com&sid=s1"},
+		RedirectURI:            "https://app.example.com/bye?state=x",
+	}))
+	html := buf.String()
+
+	assert.Contains(t, html, "<h1>Signed out</h1>")
+	assert.Contains(t, html, `src="/api/v1/login/assets/logout.js"`)
+	assert.Contains(t, html, `<iframe class="frontchannel" src="https://app.example.com/logout?iss=https%3A%2F%2Fauth.example.com&amp;sid=s1"`)
+	assert.Contains(t, html, `data-redirect-url="https://app.example.com/bye?state=x"`)
+
+	buf.Reset()
+	require.NoError(t, RenderLogout(&buf, LogoutPage{AssetsURL: "/api/v1/login/assets/"}))
+	assert.NotContains(t, buf.String(), "<iframe")
+	assert.NotContains(t, buf.String(), "Continue")
+}
+
+func TestAssets(t *testing.T) {
+	h := Assets()
+
+	for _, name := range []string{"login.js", "login.css", "logout.js"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/login/assets/"+name, nil))
+		assert.Equal(t, http.StatusOK, w.Code, name)
+		assert.NotEmpty(t, w.Body.String(), name)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/login/assets/missing.js", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,131 @@
+// Package imaging validates and normalizes user-supplied images.
+//
+// Every image is decoded and encoded again, which drops EXIF and other
+// metadata and guarantees that what is stored is a plain image of the
+// declared type, whatever bytes were uploaded.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+)
+
+// Content types of normalized images.
+const (
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+)
+
+// MaxPixels bounds the decoded size of an image so that a small, highly
+// compressed file cannot exhaust memory.
+const MaxPixels = 40_000_000
+
+// jpegQuality is the quality of re-encoded JPEG images.
+const jpegQuality = 85
+
+var (
+	// ErrUnsupportedFormat is returned for anything but JPEG, PNG and GIF.
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	// ErrTooLarge is returned when the image exceeds MaxPixels.
+	ErrTooLarge = errors.New("image dimensions are too large")
+)
+
+// Image is a normalized image.
+type Image struct {
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Normalize decodes data and re-encodes it, scaled down to fit maxDim pixels
+// on its longer side. JPEG stays JPEG; PNG and GIF, which may be transparent,
+// become PNG. Animated GIFs keep their first frame.
+func Normalize(data []byte, maxDim int) (*Image, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	if format != "jpeg" && format != "png" && format != "gif" {
+		return nil, ErrUnsupportedFormat
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode %s image: %w", format, err)
+	}
+	dst := Fit(src, maxDim)
+
+	var buf bytes.Buffer
+	out := &Image{Width: dst.Bounds().Dx(), Height: dst.Bounds().Dy()}
+	if format == "jpeg" {
+		out.ContentType = ContentTypeJPEG
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality})
+	} else {
+		out.ContentType = ContentTypePNG
+		err = png.Encode(&buf, dst)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode image: %w", err)
+	}
+	out.Data = buf.Bytes()
+	return out, nil
+}
+
+// Fit scales src down with an area-averaging filter so that neither side
+// exceeds maxDim, keeping the aspect ratio. Smaller images are returned
+// as they are.
+func Fit(src image.Image, maxDim int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if maxDim <= 0 || (w <= maxDim && h <= maxDim) {
+		return src
+	}
+
+	dw, dh := maxDim, maxDim
+	if w > h {
+		dh = max(1, h*maxDim/w)
+	} else {
+		dw = max(1, w*maxDim/h)
+	}
+
+	// Averaging premultiplied RGBA keeps transparent pixels from bleeding
+	// their color into opaque neighbours.
+	rgba := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*h/dh, max((y+1)*h/dh, y*h/dh+1)
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*w/dw, max((x+1)*w/dw, x*w/dw+1)
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					bl += uint64(p[2])
+					a += uint64(p[3])
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(bl / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,83 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func solid(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestNormalize(t *testing.T) {
+	t.Run("png is scaled down and stays png", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, solid(400, 200, color.RGBA{R: 255, A: 255})))
+
+		img, err := Normalize(buf.Bytes(), 100)
+		require.NoError(t, err)
+		assert.Equal(t, ContentTypePNG, img.ContentType)
+		assert.Equal(t, 100, img.Width)
+		assert.Equal(t, 50, img.Height)
+
+		decoded, err := png.Decode(bytes.NewReader(img.Data))
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 100, 50), decoded.Bounds())
+		r, g, b, a := decoded.At(10, 10).RGBA()
+		assert.Equal(t, []uint32{0xffff, 0, 0, 0xffff}, []uint32{r, g, b, a})
+	})
+
+	t.Run("jpeg stays jpeg", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, jpeg.Encode(&buf, solid(64, 64, color.White), nil))
+
+		img, err := Normalize(buf.Bytes(), 512)
+		require.NoError(t, err)
+		assert.Equal(t, ContentTypeJPEG, img.ContentType)
+		assert.Equal(t, 64, img.Width)
+		_, err = jpeg.Decode(bytes.NewReader(img.Data))
+		assert.NoError(t, err)
+	})
+
+	t.Run("gif becomes png", func(t *testing.T) {
+		var buf bytes.Buffer
+		pal := image.NewPaletted(image.Rect(0, 0, 8, 8), color.Palette{color.Black, color.White})
+		require.NoError(t, gif.Encode(&buf, pal, nil))
+
+		img, err := Normalize(buf.Bytes(), 512)
+		require.NoError(t, err)
+		assert.Equal(t, ContentTypePNG, img.ContentType)
+	})
+
+	t.Run("garbage is rejected", func(t *testing.T) {
+		_, err := Normalize([]byte("<svg xmlns='http://www.w3.org/2000/svg'/>"), 512)
+		assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	})
+
+	t.Run("oversized dimensions are rejected before decoding", func(t *testing.T) {
+		// A GIF header declaring a 65535x65535 screen.
+		header := []byte("GIF89a\xff\xff\xff\xff\x00\x00\x00;")
+		_, err := Normalize(header, 512)
+		assert.ErrorIs(t, err, ErrTooLarge)
+	})
+}
+
+func TestFit(t *testing.T) {
+	src := solid(10, 30, color.White)
+	assert.Same(t, src, Fit(src, 64), "smaller images are returned as is")
+	assert.Equal(t, image.Rect(0, 0, 3, 9), Fit(src, 9).Bounds())
+}
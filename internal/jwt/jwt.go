@@ -25,6 +25,14 @@ const (
 	IDTokenTTL      = 1 * time.Hour      // ID tokens for user info
 	RefreshTokenTTL = 7 * 24 * time.Hour // 7 days max for refresh tokens
 
+	// Impersonation tokens are never refreshed and live shorter than regular
+	// access tokens
+	ImpersonationTokenTTL = 5 * time.Minute
+
+	// Logout tokens only need to survive delivery (OIDC Back-Channel Logout
+	// 1.0 §2.4)
+	LogoutTokenTTL = 2 * time.Minute
+
 	// Security parameters
 	MinKeySize = 2048 // Minimum RSA key size (ISO27001 A.10.1.1)
 	JTILength  = 32   // JTI entropy length
@@ -38,15 +46,13 @@ func GenerateSecureID() string {
 	return hex.EncodeToString(bytes)
 }
 
-var (
-	privateKey *rsa.PrivateKey
-	publicKey  *rsa.PublicKey
-)
-
-// GetPublicKey returns the parsed RSA public key used for JWT verification.
-// Returns nil if InitJWTKeys has not been called.
+// GetPublicKey returns the public key of the current signing key.
+// Returns nil if no keys have been installed.
 func GetPublicKey() *rsa.PublicKey {
-	return publicKey
+	if key, ok := SigningKey(); ok {
+		return key.PublicKey
+	}
+	return nil
 }
 
 // generateSecureJTI creates a cryptographically secure unique token identifier
@@ -69,9 +75,10 @@ func validateKeyStrength(key *rsa.PrivateKey) error {
 	return nil
 }
 
+// InitJWTKeys installs the key pair from JWT_PRIVATE_KEY and JWT_PUBLIC_KEY
+// as the signing key, identified by JWT_KEY_ID. When signing keys are
+// rotated, the keys from the key store replace it once they are loaded.
 func InitJWTKeys() error {
-	var err error
-
 	// Validate environment variables are not empty
 	if len(config.JWTPrivateKey) == 0 {
 		return errors.New("JWT_PRIVATE_KEY environment variable is required")
@@ -81,7 +88,7 @@ func InitJWTKeys() error {
 	}
 
 	// Parse private key with security validation
-	privateKey, err = jwtlib.ParseRSAPrivateKeyFromPEM(config.JWTPrivateKey)
+	privateKey, err := jwtlib.ParseRSAPrivateKeyFromPEM(config.JWTPrivateKey)
 	if err != nil {
 		return fmt.Errorf("failed to parse private key: %w", err)
 	}
@@ -92,24 +99,23 @@ func InitJWTKeys() error {
 	}
 
 	// Parse public key
-	publicKey, err = jwtlib.ParseRSAPublicKeyFromPEM(config.JWTPublicKey)
+	publicKey, err := jwtlib.ParseRSAPublicKeyFromPEM(config.JWTPublicKey)
 	if err != nil {
 		return fmt.Errorf("failed to parse public key: %w", err)
 	}
 
-	// Validate key pair consistency
-	if privateKey.PublicKey.N.Cmp(publicKey.N) != 0 || privateKey.PublicKey.E != publicKey.E {
-		return errors.New("private and public keys do not form a valid key pair")
-	}
-
-	return nil
+	return SetKeys(Key{
+		ID:         config.GetEnvOrDefault("JWT_KEY_ID", DefaultKeyID),
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+	})
 }
 
-// ResetJWTKeys clears the cached JWT signing keys.
+// ResetJWTKeys clears the installed JWT keys and key reloader.
 // Intended for testing only.
 func ResetJWTKeys() {
-	privateKey = nil
-	publicKey = nil
+	currentKeys.Store(nil)
+	SetKeyReloader(nil)
 }
 
 func GenerateAccessToken(
@@ -119,6 +125,37 @@ func GenerateAccessToken(
 	audience string,
 	clientID string,
 	providerID string,
+) (string, error) {
+	return GenerateAccessTokenWithClaims(userId, scope, issuer, audience, clientID, providerID, nil)
+}
+
+// GenerateAccessTokenWithClaims is GenerateAccessToken with additional
+// custom claims. Custom claims never replace the standard claims set by this
+// package; colliding keys are ignored.
+func GenerateAccessTokenWithClaims(
+	userId string,
+	scope string,
+	issuer string,
+	audience string,
+	clientID string,
+	providerID string,
+	extraClaims map[string]any,
+) (string, error) {
+	return GenerateAccessTokenWithTTL(userId, scope, issuer, audience, clientID, providerID, AccessTokenTTL, extraClaims)
+}
+
+// GenerateAccessTokenWithTTL is GenerateAccessTokenWithClaims with a custom
+// lifetime. A ttl outside (0, AccessTokenTTL] is rejected so callers can only
+// shorten tokens.
+func GenerateAccessTokenWithTTL(
+	userId string,
+	scope string,
+	issuer string,
+	audience string,
+	clientID string,
+	providerID string,
+	ttl time.Duration,
+	extraClaims map[string]any,
 ) (string, error) {
 	_, span := otel.Tracer("jwt").Start(context.Background(), "jwt.generate_access_token")
 	defer span.End()
@@ -149,6 +186,10 @@ func GenerateAccessToken(
 		span.SetStatus(codes.Error, "invalid input")
 		return "", errors.New("providerID cannot be empty")
 	}
+	if ttl <= 0 || ttl > AccessTokenTTL {
+		span.SetStatus(codes.Error, "invalid input")
+		return "", fmt.Errorf("ttl must be between 0 and %s", AccessTokenTTL)
+	}
 
 	// Generate secure JTI (ISO27001 A.10.1.1)
 	jti := generateSecureJTI()
@@ -160,9 +201,9 @@ func GenerateAccessToken(
 		"aud": audience,
 		"iss": issuer,
 		"iat": jwtlib.NewNumericDate(now),
-		"exp": jwtlib.NewNumericDate(now.Add(AccessTokenTTL)), // Short-lived tokens
-		"nbf": jwtlib.NewNumericDate(now),                     // Not before
-		"jti": jti,                                            // Secure unique identifier
+		"exp": jwtlib.NewNumericDate(now.Add(ttl)), // Short-lived tokens
+		"nbf": jwtlib.NewNumericDate(now),          // Not before
+		"jti": jti,                                 // Secure unique identifier
 
 		// OAuth2 claims
 		"scope":      scope,
@@ -173,6 +214,12 @@ func GenerateAccessToken(
 		"provider_id": providerID,
 	}
 
+	for key, value := range extraClaims {
+		if _, reserved := claims[key]; !reserved {
+			claims[key] = value
+		}
+	}
+
 	tok, err := generateToken(claims)
 	if err != nil {
 		span.RecordError(err)
@@ -202,6 +249,13 @@ type UserProfile struct {
 var GenerateIDToken = generateIDToken
 
 func generateIDToken(userUUID, issuer, clientID, providerID string, profile *UserProfile, nonce string) (string, error) {
+	return GenerateIDTokenWithClaims(userUUID, issuer, clientID, providerID, profile, nonce, nil)
+}
+
+// GenerateIDTokenWithClaims is GenerateIDToken with additional custom claims,
+// such as the session ID (sid). Custom claims never replace the standard or
+// profile claims set by this package; colliding keys are ignored.
+func GenerateIDTokenWithClaims(userUUID, issuer, clientID, providerID string, profile *UserProfile, nonce string, extraClaims map[string]any) (string, error) {
 	_, span := otel.Tracer("jwt").Start(context.Background(), "jwt.generate_id_token")
 	defer span.End()
 	span.SetAttributes(
@@ -289,6 +343,12 @@ func generateIDToken(userUUID, issuer, clientID, providerID string, profile *Use
 		}
 	}
 
+	for key, value := range extraClaims {
+		if _, reserved := claims[key]; !reserved {
+			claims[key] = value
+		}
+	}
+
 	tok, err := generateToken(claims)
 	if err != nil {
 		span.RecordError(err)
@@ -357,15 +417,83 @@ func generateRefreshToken(userUUID, issuer, clientID, providerID string) (string
 	return tok, nil
 }
 
+// BackChannelLogoutEvent is the event a logout token carries (OIDC
+// Back-Channel Logout 1.0 §2.4).
+const BackChannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// GenerateLogoutToken creates the logout token sent to a client's
+// back-channel logout URI when the user's session with it ends. It names the
+// user (sub), the session (sid) or both; at least one is required. Logout
+// tokens never carry a nonce, so they cannot be mistaken for ID tokens.
+func GenerateLogoutToken(sub, issuer, clientID, sessionID string) (string, error) {
+	if strings.TrimSpace(sub) == "" && strings.TrimSpace(sessionID) == "" {
+		return "", errors.New("sub or sessionID is required")
+	}
+	if strings.TrimSpace(issuer) == "" {
+		return "", errors.New("issuer cannot be empty")
+	}
+	if strings.TrimSpace(clientID) == "" {
+		return "", errors.New("clientID cannot be empty")
+	}
+
+	now := time.Now()
+	claims := jwtlib.MapClaims{
+		"aud":    clientID,
+		"iss":    issuer,
+		"iat":    jwtlib.NewNumericDate(now),
+		"exp":    jwtlib.NewNumericDate(now.Add(LogoutTokenTTL)),
+		"jti":    generateSecureJTI(),
+		"events": map[string]any{BackChannelLogoutEvent: map[string]any{}},
+	}
+	if sub != "" {
+		claims["sub"] = sub
+	}
+	if sessionID != "" {
+		claims["sid"] = sessionID
+	}
+
+	return signToken(claims, "logout+jwt", []string{"aud", "iss", "iat", "exp", "jti"})
+}
+
+// ParseIDTokenHint verifies an ID token this server issued and returns its
+// claims. Unlike ValidateToken it accepts expired tokens, because relying
+// parties pass the ID token of the session they end as id_token_hint long
+// after it expired (OIDC RP-Initiated Logout 1.0 §2).
+func ParseIDTokenHint(tokenString string) (jwtlib.MapClaims, error) {
+	if strings.TrimSpace(tokenString) == "" {
+		return nil, errors.New("token cannot be empty")
+	}
+
+	token, err := jwtlib.Parse(tokenString, verificationKey, jwtlib.WithoutClaimsValidation())
+	if err != nil {
+		return nil, fmt.Errorf("token parsing failed: %w", err)
+	}
+
+	claims := token.Claims.(jwtlib.MapClaims)
+	if tokenType, _ := claims["token_type"].(string); tokenType != "id_token" {
+		return nil, errors.New("token is not an ID token")
+	}
+	if err := validateTokenClaims(claims); err != nil {
+		return nil, fmt.Errorf("token validation failed: %w", err)
+	}
+	return claims, nil
+}
+
 // generateToken creates a JWT with enhanced security validation
 // Complies with SOC2 CC6.1 and ISO27001 A.10.1.1
 func generateToken(claims jwtlib.MapClaims) (string, error) {
-	if privateKey == nil {
+	return signToken(claims, "", []string{"sub", "aud", "iss", "iat", "exp", "jti"})
+}
+
+// signToken signs claims with the current signing key after checking that
+// the required claims are present. A non-empty typ sets the typ header.
+func signToken(claims jwtlib.MapClaims, typ string, requiredClaims []string) (string, error) {
+	signing, ok := SigningKey()
+	if !ok {
 		return "", errors.New("private key not initialized - call InitJWTKeys() first")
 	}
 
 	// Validate required claims are present
-	requiredClaims := []string{"sub", "aud", "iss", "iat", "exp", "jti"}
 	for _, claim := range requiredClaims {
 		if _, exists := claims[claim]; !exists {
 			return "", fmt.Errorf("required claim '%s' is missing", claim)
@@ -375,10 +503,13 @@ func generateToken(claims jwtlib.MapClaims) (string, error) {
 	// Use RS256 for asymmetric signing (more secure than HS256)
 	token := jwtlib.NewWithClaims(jwtlib.SigningMethodRS256, claims)
 
-	// Add key ID header for key rotation support (configurable via JWT_KEY_ID env var)
-	token.Header["kid"] = config.GetEnvOrDefault("JWT_KEY_ID", "maintainerd-auth-key-1")
+	// Key ID header tells verifiers which key of the JWKS signed the token
+	token.Header["kid"] = signing.ID
+	if typ != "" {
+		token.Header["typ"] = typ
+	}
 
-	return token.SignedString(privateKey)
+	return token.SignedString(signing.PrivateKey)
 }
 
 // ValidateToken performs comprehensive JWT validation
@@ -387,7 +518,7 @@ func ValidateToken(tokenString string) (jwtlib.MapClaims, error) {
 	_, span := otel.Tracer("jwt").Start(context.Background(), "jwt.validate_token")
 	defer span.End()
 
-	if publicKey == nil {
+	if _, ok := SigningKey(); !ok {
 		err := errors.New("public key not initialized - call InitJWTKeys() first")
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "validate token failed")
@@ -401,24 +532,7 @@ func ValidateToken(tokenString string) (jwtlib.MapClaims, error) {
 	}
 
 	// Parse and validate token
-	token, err := jwtlib.Parse(tokenString, func(t *jwtlib.Token) (interface{}, error) {
-		// Validate signing method (prevent algorithm confusion attacks)
-		if method, ok := t.Method.(*jwtlib.SigningMethodRSA); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
-		} else if method != jwtlib.SigningMethodRS256 {
-			return nil, fmt.Errorf("unexpected RSA signing method: %v", method.Alg())
-		}
-
-		// Validate key ID if present (for key rotation)
-		if kid, exists := t.Header["kid"]; exists {
-			expectedKID := config.GetEnvOrDefault("JWT_KEY_ID", "maintainerd-auth-key-1")
-			if kid != expectedKID {
-				return nil, fmt.Errorf("unknown key ID: %v", kid)
-			}
-		}
-
-		return publicKey, nil
-	})
+	token, err := jwtlib.Parse(tokenString, verificationKey)
 
 	if err != nil {
 		span.RecordError(err)
@@ -441,6 +555,35 @@ func ValidateToken(tokenString string) (jwtlib.MapClaims, error) {
 	return claims, nil
 }
 
+// verificationKey returns the public key a token is verified with. It is the
+// jwtlib.Keyfunc of ValidateToken and ParseIDTokenHint.
+func verificationKey(t *jwtlib.Token) (interface{}, error) {
+	// Validate signing method (prevent algorithm confusion attacks)
+	if method, ok := t.Method.(*jwtlib.SigningMethodRSA); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+	} else if method != jwtlib.SigningMethodRS256 {
+		return nil, fmt.Errorf("unexpected RSA signing method: %v", method.Alg())
+	}
+
+	// Tokens name the key that signed them; any key that has not been
+	// retired verifies them. Tokens without a key ID predate rotation
+	// and are verified against the signing key.
+	if kid, exists := t.Header["kid"]; exists {
+		id, _ := kid.(string)
+		key, found := findVerificationKey(id)
+		if !found {
+			return nil, fmt.Errorf("unknown key ID: %v", kid)
+		}
+		return key.PublicKey, nil
+	}
+
+	signing, ok := SigningKey()
+	if !ok {
+		return nil, errors.New("public key not initialized - call InitJWTKeys() first")
+	}
+	return signing.PublicKey, nil
+}
+
 // validateTokenClaims performs additional security validations on JWT claims
 func validateTokenClaims(claims jwtlib.MapClaims) error {
 	// Validate required claims exist
@@ -39,6 +39,14 @@ func initTestJWTKeys(t *testing.T) {
 	require.NoError(t, InitJWTKeys())
 }
 
+// testSigningKey returns the private key of the installed signing key.
+func testSigningKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, ok := SigningKey()
+	require.True(t, ok)
+	return key.PrivateKey
+}
+
 // ---------------------------------------------------------------------------
 // GenerateAccessToken
 // ---------------------------------------------------------------------------
@@ -136,9 +144,7 @@ func TestValidateToken_TamperedToken(t *testing.T) {
 
 func TestValidateToken_NilPublicKey(t *testing.T) {
 	initTestJWTKeys(t)
-	saved := publicKey
-	publicKey = nil
-	t.Cleanup(func() { publicKey = saved })
+	ResetJWTKeys()
 
 	_, err := ValidateToken("any.token.string")
 	require.Error(t, err)
@@ -258,6 +264,37 @@ func TestGenerateAccessToken_EmptyProviderID(t *testing.T) {
 	assert.Contains(t, err.Error(), "providerID")
 }
 
+func TestGenerateAccessTokenWithClaims(t *testing.T) {
+	initTestJWTKeys(t)
+	tok, err := GenerateAccessTokenWithClaims("user-uuid", "read", "https://auth.example.com", "myapp", "client-1", "provider-1",
+		map[string]any{"tier": "gold", "sub": "attacker", "scope": "admin"})
+	require.NoError(t, err)
+
+	claims, err := ValidateToken(tok)
+	require.NoError(t, err)
+	assert.Equal(t, "gold", claims["tier"])
+	assert.Equal(t, "user-uuid", claims["sub"], "custom claims must not replace standard claims")
+	assert.Equal(t, "read", claims["scope"])
+}
+
+func TestGenerateAccessTokenWithTTL(t *testing.T) {
+	initTestJWTKeys(t)
+	tok, err := GenerateAccessTokenWithTTL("user-uuid", "read", "https://auth.example.com", "myapp", "client-1", "provider-1",
+		ImpersonationTokenTTL, nil)
+	require.NoError(t, err)
+
+	claims, err := ValidateToken(tok)
+	require.NoError(t, err)
+	exp, err := claims.GetExpirationTime()
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(ImpersonationTokenTTL), exp.Time, 5*time.Second)
+
+	for _, ttl := range []time.Duration{0, -time.Minute, AccessTokenTTL + time.Second} {
+		_, err := GenerateAccessTokenWithTTL("user-uuid", "read", "https://auth.example.com", "myapp", "client-1", "provider-1", ttl, nil)
+		assert.Error(t, err, ttl)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // GenerateIDToken — validation branches
 // ---------------------------------------------------------------------------
@@ -362,9 +399,7 @@ func TestInitJWTKeys_WeakPrivateKey(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestGenerateToken_NilPrivateKey(t *testing.T) {
-	saved := privateKey
-	privateKey = nil
-	t.Cleanup(func() { privateKey = saved })
+	ResetJWTKeys()
 
 	claims := jwtlib.MapClaims{
 		"sub": "user-uuid", "aud": "myapp", "iss": "https://auth.example.com",
@@ -399,8 +434,9 @@ func TestValidateToken_KIDMismatch(t *testing.T) {
 	tok, err := GenerateAccessToken("user-uuid", "read", "https://auth.example.com", "myapp", "client-1", "provider-1")
 	require.NoError(t, err)
 
-	// Now tell ValidateToken to expect a different KID
+	// Replace the keys with a different key ID, retiring the first key
 	t.Setenv("JWT_KEY_ID", "rotated-key-2")
+	initTestJWTKeys(t)
 	_, err = ValidateToken(tok)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unknown key ID")
@@ -444,7 +480,7 @@ func TestValidateToken_WrongRSAVariant(t *testing.T) {
 		"iat": jwtlib.NewNumericDate(time.Now()),
 		"exp": jwtlib.NewNumericDate(time.Now().Add(time.Hour)),
 		"jti": "test-jti",
-	}).SignedString(privateKey)
+	}).SignedString(testSigningKey(t))
 	require.NoError(t, err)
 
 	_, err = ValidateToken(rs384Tok)
@@ -564,10 +600,109 @@ func TestValidateToken_MissingJTIInSignedToken(t *testing.T) {
 	}
 	token := jwtlib.NewWithClaims(jwtlib.SigningMethodRS256, claims)
 	token.Header["kid"] = "maintainerd-auth-key-1"
-	tokenString, err := token.SignedString(privateKey)
+	tokenString, err := token.SignedString(testSigningKey(t))
 	require.NoError(t, err)
 
 	_, err = ValidateToken(tokenString)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "jti")
 }
+
+// ---------------------------------------------------------------------------
+// OIDC logout — session IDs, logout tokens and ID token hints
+// ---------------------------------------------------------------------------
+
+func TestGenerateIDTokenWithClaims(t *testing.T) {
+	initTestJWTKeys(t)
+	tok, err := GenerateIDTokenWithClaims("user-uuid", "https://auth.example.com", "client-1", "provider-1", nil, "",
+		map[string]any{"sid": "session-1", "sub": "attacker"})
+	require.NoError(t, err)
+
+	claims, err := ValidateToken(tok)
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", claims["sid"])
+	assert.Equal(t, "user-uuid", claims["sub"], "custom claims must not replace standard claims")
+}
+
+func TestGenerateLogoutToken(t *testing.T) {
+	initTestJWTKeys(t)
+	tok, err := GenerateLogoutToken("user-uuid", "https://auth.example.com", "client-1", "session-1")
+	require.NoError(t, err)
+
+	parsed, err := jwtlib.Parse(tok, verificationKey)
+	require.NoError(t, err)
+	assert.Equal(t, "logout+jwt", parsed.Header["typ"])
+	claims := parsed.Claims.(jwtlib.MapClaims)
+	assert.Equal(t, "user-uuid", claims["sub"])
+	assert.Equal(t, "session-1", claims["sid"])
+	assert.Equal(t, "client-1", claims["aud"])
+	assert.NotContains(t, claims, "nonce")
+	assert.Contains(t, claims["events"], BackChannelLogoutEvent)
+
+	// A logout token naming only the session has no subject.
+	tok, err = GenerateLogoutToken("", "https://auth.example.com", "client-1", "session-1")
+	require.NoError(t, err)
+	parsed, err = jwtlib.Parse(tok, verificationKey)
+	require.NoError(t, err)
+	assert.NotContains(t, parsed.Claims.(jwtlib.MapClaims), "sub")
+
+	_, err = GenerateLogoutToken("", "https://auth.example.com", "client-1", "")
+	assert.Error(t, err)
+	_, err = GenerateLogoutToken("user-uuid", "", "client-1", "")
+	assert.Error(t, err)
+	_, err = GenerateLogoutToken("user-uuid", "https://auth.example.com", "", "")
+	assert.Error(t, err)
+}
+
+func TestParseIDTokenHint(t *testing.T) {
+	initTestJWTKeys(t)
+
+	t.Run("valid id token", func(t *testing.T) {
+		tok, err := GenerateIDTokenWithClaims("user-uuid", "https://auth.example.com", "client-1", "provider-1", nil, "",
+			map[string]any{"sid": "session-1"})
+		require.NoError(t, err)
+
+		claims, err := ParseIDTokenHint(tok)
+		require.NoError(t, err)
+		assert.Equal(t, "session-1", claims["sid"])
+	})
+
+	t.Run("expired id token", func(t *testing.T) {
+		past := time.Now().Add(-24 * time.Hour)
+		tok, err := generateToken(jwtlib.MapClaims{
+			"sub":        "user-uuid",
+			"aud":        "client-1",
+			"iss":        "https://auth.example.com",
+			"iat":        jwtlib.NewNumericDate(past),
+			"exp":        jwtlib.NewNumericDate(past.Add(time.Hour)),
+			"jti":        generateSecureJTI(),
+			"token_type": "id_token",
+		})
+		require.NoError(t, err)
+
+		_, err = ValidateToken(tok)
+		require.Error(t, err)
+		claims, err := ParseIDTokenHint(tok)
+		require.NoError(t, err)
+		assert.Equal(t, "user-uuid", claims["sub"])
+	})
+
+	t.Run("access token", func(t *testing.T) {
+		tok, err := GenerateAccessToken("user-uuid", "", "https://auth.example.com", "api", "client-1", "provider-1")
+		require.NoError(t, err)
+		_, err = ParseIDTokenHint(tok)
+		assert.Error(t, err)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		tok, err := GenerateIDToken("user-uuid", "https://auth.example.com", "client-1", "provider-1", nil, "")
+		require.NoError(t, err)
+		_, err = ParseIDTokenHint(tok[:len(tok)-4] + "AAAA")
+		assert.Error(t, err)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, err := ParseIDTokenHint(" ")
+		assert.Error(t, err)
+	})
+}
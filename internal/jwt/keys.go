@@ -0,0 +1,185 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultKeyID is the key ID of the key loaded by InitJWTKeys when
+// JWT_KEY_ID is not set.
+const DefaultKeyID = "maintainerd-auth-key-1"
+
+// keyReloadInterval limits how often a token with an unknown key ID can
+// trigger a key reload, so that forged key IDs cannot flood the database.
+const keyReloadInterval = 10 * time.Second
+
+// Key is an RSA key pair. ID is set as the "kid" header of the tokens the
+// key signs and published in the JWKS. PrivateKey is only needed for the
+// signing key.
+type Key struct {
+	ID         string
+	PrivateKey *rsa.PrivateKey
+	PublicKey  *rsa.PublicKey
+}
+
+// keyring is the signing key and every key tokens are verified against,
+// the signing key first. A keyring is never modified once installed.
+type keyring struct {
+	signing Key
+	keys    []Key
+}
+
+func (k *keyring) find(id string) (Key, bool) {
+	for _, key := range k.keys {
+		if key.ID == id {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+var (
+	currentKeys atomic.Pointer[keyring]
+
+	reloadMu   sync.Mutex
+	reloader   func() error
+	lastReload time.Time
+)
+
+// SetKeys installs the key new tokens are signed with and the keys that
+// tokens signed earlier are still verified against. It replaces the keys
+// installed before.
+func SetKeys(signing Key, verification ...Key) error {
+	if signing.PrivateKey == nil {
+		return errors.New("signing key has no private key")
+	}
+	if signing.PublicKey == nil {
+		signing.PublicKey = &signing.PrivateKey.PublicKey
+	}
+
+	ring := &keyring{signing: signing, keys: []Key{signing}}
+	seen := map[string]struct{}{}
+	for _, key := range append([]Key{signing}, verification...) {
+		if key.ID == "" {
+			return errors.New("key ID cannot be empty")
+		}
+		if _, dup := seen[key.ID]; dup {
+			return fmt.Errorf("duplicate key ID %q", key.ID)
+		}
+		seen[key.ID] = struct{}{}
+		if key.PublicKey == nil {
+			return fmt.Errorf("key %q has no public key", key.ID)
+		}
+		if key.PublicKey.Size()*8 < MinKeySize {
+			return fmt.Errorf("key %q: RSA key size %d bits is below minimum required %d bits", key.ID, key.PublicKey.Size()*8, MinKeySize)
+		}
+	}
+	if !signing.PrivateKey.PublicKey.Equal(signing.PublicKey) {
+		return errors.New("private and public keys do not form a valid key pair")
+	}
+	for _, key := range verification {
+		ring.keys = append(ring.keys, Key{ID: key.ID, PublicKey: key.PublicKey})
+	}
+
+	currentKeys.Store(ring)
+	return nil
+}
+
+// SigningKeyID returns the key ID new tokens are signed with, or "" when no
+// keys are installed.
+func SigningKeyID() string {
+	if ring := currentKeys.Load(); ring != nil {
+		return ring.signing.ID
+	}
+	return ""
+}
+
+// SigningKey returns the key new tokens are signed with.
+func SigningKey() (Key, bool) {
+	if ring := currentKeys.Load(); ring != nil {
+		return ring.signing, true
+	}
+	return Key{}, false
+}
+
+// VerificationKeys returns the public keys tokens are verified against, the
+// signing key first. Private keys are left out.
+func VerificationKeys() []Key {
+	ring := currentKeys.Load()
+	if ring == nil {
+		return nil
+	}
+	keys := make([]Key, len(ring.keys))
+	for i, key := range ring.keys {
+		keys[i] = Key{ID: key.ID, PublicKey: key.PublicKey}
+	}
+	return keys
+}
+
+// SetKeyReloader registers a function that reinstalls the keys from their
+// store. It is called when a token names a key ID that is not installed, so
+// that a key rotated by another instance is picked up before its tokens are
+// rejected. Pass nil to remove it.
+func SetKeyReloader(reload func() error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	reloader = reload
+	lastReload = time.Time{}
+}
+
+// findVerificationKey looks up a key by ID, reloading the keys once when it
+// is unknown.
+func findVerificationKey(id string) (Key, bool) {
+	if ring := currentKeys.Load(); ring != nil {
+		if key, ok := ring.find(id); ok {
+			return key, true
+		}
+	}
+
+	reloadMu.Lock()
+	if reloader == nil || time.Since(lastReload) < keyReloadInterval {
+		reloadMu.Unlock()
+		return Key{}, false
+	}
+	lastReload = time.Now()
+	reload := reloader
+	reloadMu.Unlock()
+
+	if err := reload(); err != nil {
+		return Key{}, false
+	}
+	if ring := currentKeys.Load(); ring != nil {
+		return ring.find(id)
+	}
+	return Key{}, false
+}
+
+// GenerateKey creates a new RSA key pair of MinKeySize bits.
+func GenerateKey(id string) (Key, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, MinKeySize)
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{ID: id, PrivateKey: privateKey, PublicKey: &privateKey.PublicKey}, nil
+}
+
+// EncodePrivateKey returns the PKCS #1 PEM encoding of an RSA private key.
+func EncodePrivateKey(key *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+// EncodePublicKey returns the PKIX PEM encoding of an RSA public key.
+func EncodePublicKey(key *rsa.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
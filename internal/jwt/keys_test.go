@@ -0,0 +1,168 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generateTestKey(t *testing.T, id string) Key {
+	t.Helper()
+	key, err := GenerateKey(id)
+	require.NoError(t, err)
+	return key
+}
+
+func signTestAccessToken(t *testing.T) string {
+	t.Helper()
+	tok, err := GenerateAccessToken("user-uuid", "read", "https://auth.example.com", "myapp", "client-1", "provider-1")
+	require.NoError(t, err)
+	return tok
+}
+
+// ---------------------------------------------------------------------------
+// SetKeys
+// ---------------------------------------------------------------------------
+
+func TestSetKeys_SignsWithSigningKey(t *testing.T) {
+	t.Cleanup(ResetJWTKeys)
+	current := generateTestKey(t, "key-2")
+	previous := generateTestKey(t, "key-1")
+	require.NoError(t, SetKeys(current, previous))
+
+	assert.Equal(t, "key-2", SigningKeyID())
+	tok := signTestAccessToken(t)
+	parsed, _, err := jwtlib.NewParser().ParseUnverified(tok, jwtlib.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "key-2", parsed.Header["kid"])
+
+	keys := VerificationKeys()
+	require.Len(t, keys, 2)
+	assert.Equal(t, "key-2", keys[0].ID)
+	assert.Equal(t, "key-1", keys[1].ID)
+	for _, key := range keys {
+		assert.Nil(t, key.PrivateKey, "verification keys must not expose private keys")
+		assert.NotNil(t, key.PublicKey)
+	}
+}
+
+func TestSetKeys_VerifiesPreviousKeys(t *testing.T) {
+	t.Cleanup(ResetJWTKeys)
+	first := generateTestKey(t, "key-1")
+	second := generateTestKey(t, "key-2")
+
+	require.NoError(t, SetKeys(first))
+	tok := signTestAccessToken(t)
+
+	// Rotated: the first key still verifies
+	require.NoError(t, SetKeys(second, first))
+	_, err := ValidateToken(tok)
+	require.NoError(t, err)
+
+	// Retired: the first key is gone
+	require.NoError(t, SetKeys(second))
+	_, err = ValidateToken(tok)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown key ID")
+}
+
+func TestSetKeys_Invalid(t *testing.T) {
+	t.Cleanup(ResetJWTKeys)
+	key := generateTestKey(t, "key-1")
+	other := generateTestKey(t, "key-2")
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		signing      Key
+		verification []Key
+		wantErr      string
+	}{
+		{"no private key", Key{ID: "key-1", PublicKey: key.PublicKey}, nil, "no private key"},
+		{"empty key ID", Key{PrivateKey: key.PrivateKey}, nil, "key ID cannot be empty"},
+		{"duplicate key ID", key, []Key{{ID: "key-1", PublicKey: other.PublicKey}}, "duplicate key ID"},
+		{"verification key without public key", key, []Key{{ID: "key-2"}}, "has no public key"},
+		{"weak key", Key{ID: "weak", PrivateKey: weak}, nil, "below minimum"},
+		{"mismatched pair", Key{ID: "key-1", PrivateKey: key.PrivateKey, PublicKey: other.PublicKey}, nil, "valid key pair"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SetKeys(tt.signing, tt.verification...)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// SetKeyReloader
+// ---------------------------------------------------------------------------
+
+func TestValidateToken_ReloadsUnknownKey(t *testing.T) {
+	t.Cleanup(ResetJWTKeys)
+	first := generateTestKey(t, "key-1")
+	second := generateTestKey(t, "key-2")
+
+	// Another instance rotated to the second key and signed a token with it
+	require.NoError(t, SetKeys(second, first))
+	tok := signTestAccessToken(t)
+	require.NoError(t, SetKeys(first))
+
+	reloads := 0
+	SetKeyReloader(func() error {
+		reloads++
+		return SetKeys(second, first)
+	})
+
+	_, err := ValidateToken(tok)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reloads)
+}
+
+func TestValidateToken_ReloadIsRateLimited(t *testing.T) {
+	t.Cleanup(ResetJWTKeys)
+	require.NoError(t, SetKeys(generateTestKey(t, "key-1")))
+	forged := generateTestKey(t, "forged")
+
+	reloads := 0
+	SetKeyReloader(func() error {
+		reloads++
+		return errors.New("nothing new")
+	})
+
+	token := jwtlib.NewWithClaims(jwtlib.SigningMethodRS256, jwtlib.MapClaims{"sub": "x"})
+	token.Header["kid"] = "forged"
+	tok, err := token.SignedString(forged.PrivateKey)
+	require.NoError(t, err)
+
+	for range 3 {
+		_, err = ValidateToken(tok)
+		require.Error(t, err)
+	}
+	assert.Equal(t, 1, reloads)
+}
+
+// ---------------------------------------------------------------------------
+// GenerateKey / encoding
+// ---------------------------------------------------------------------------
+
+func TestGenerateKey_EncodeRoundTrip(t *testing.T) {
+	key := generateTestKey(t, "key-1")
+	assert.Equal(t, MinKeySize, key.PrivateKey.Size()*8)
+
+	privateKey, err := jwtlib.ParseRSAPrivateKeyFromPEM(EncodePrivateKey(key.PrivateKey))
+	require.NoError(t, err)
+	assert.True(t, privateKey.Equal(key.PrivateKey))
+
+	publicPEM, err := EncodePublicKey(key.PublicKey)
+	require.NoError(t, err)
+	publicKey, err := jwtlib.ParseRSAPublicKeyFromPEM(publicPEM)
+	require.NoError(t, err)
+	assert.True(t, publicKey.Equal(key.PublicKey))
+}
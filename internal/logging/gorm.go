@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// GormLogger routes GORM's logs to the db component. At debug every
+// statement is logged; at warn and above only slow and failed statements.
+// Record-not-found errors are expected by the repositories and not logged.
+type GormLogger struct {
+	// SlowThreshold is the duration above which a statement is logged at
+	// warn. Zero disables the slow query log.
+	SlowThreshold time.Duration
+}
+
+// NewGormLogger returns the GORM logger of the db component, logging
+// statements slower than slowThreshold at warn.
+func NewGormLogger(slowThreshold time.Duration) gormlogger.Interface {
+	return GormLogger{SlowThreshold: slowThreshold}
+}
+
+// LogMode is a no-op: the level is controlled by the db component.
+func (l GormLogger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+func (GormLogger) Info(ctx context.Context, msg string, args ...any) {
+	Logger(ComponentDB).InfoContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (GormLogger) Warn(ctx context.Context, msg string, args ...any) {
+	Logger(ComponentDB).WarnContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (GormLogger) Error(ctx context.Context, msg string, args ...any) {
+	Logger(ComponentDB).ErrorContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+// Trace logs one executed statement.
+func (l GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	logger := Logger(ComponentDB)
+	elapsed := time.Since(begin)
+
+	var level slog.Level
+	var msg string
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		level, msg = slog.LevelError, "query failed"
+	case l.SlowThreshold > 0 && elapsed > l.SlowThreshold:
+		level, msg = slog.LevelWarn, "slow query"
+	default:
+		level, msg = slog.LevelDebug, "query"
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []any{"sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds()}
+	if level == slog.LevelError {
+		attrs = append(attrs, "error", err)
+	}
+	logger.Log(ctx, level, msg, attrs...)
+}
@@ -0,0 +1,162 @@
+// Package logging holds the runtime-adjustable logging state of the server:
+// one log level per component and the temporary request/response body trace
+// rules. The state lives in process memory, so it applies to a single
+// instance and resets to the defaults on restart.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Components whose log level can be changed at runtime.
+const (
+	ComponentAuth     = "auth"
+	ComponentDB       = "db"
+	ComponentEvents   = "events"
+	ComponentWebhooks = "webhooks"
+)
+
+// DefaultLevel is the level of every component at startup.
+const DefaultLevel = slog.LevelInfo
+
+// ComponentLevel describes the current level of a component and the last
+// change made to it. ChangedBy is empty while the default is in effect.
+type ComponentLevel struct {
+	Component string
+	Level     slog.Level
+	ChangedBy string
+	ChangedAt *time.Time
+}
+
+type component struct {
+	level     slog.LevelVar
+	changedBy string
+	changedAt *time.Time
+}
+
+var (
+	mu         sync.RWMutex
+	base       slog.Handler
+	components = newComponents()
+)
+
+func newComponents() map[string]*component {
+	m := make(map[string]*component)
+	for _, name := range []string{ComponentAuth, ComponentDB, ComponentEvents, ComponentWebhooks} {
+		c := &component{}
+		c.level.Set(DefaultLevel)
+		m[name] = c
+	}
+	return m
+}
+
+// Init sets the handler that component loggers write to. It must accept every
+// level; filtering is done per component. Until Init is called the loggers
+// write to slog's default handler.
+func Init(h slog.Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+	base = h
+}
+
+// Logger returns the logger of component. Records below the component's
+// current level are dropped and every record carries a "component" attribute.
+// Unknown components log at DefaultLevel.
+func Logger(name string) *slog.Logger {
+	mu.RLock()
+	h := base
+	c := components[name]
+	mu.RUnlock()
+	if h == nil {
+		h = slog.Default().Handler()
+	}
+
+	var level slog.Leveler = DefaultLevel
+	if c != nil {
+		level = &c.level
+	}
+	return slog.New(&levelHandler{
+		Handler: h.WithAttrs([]slog.Attr{slog.String("component", name)}),
+		level:   level,
+	})
+}
+
+// ParseLevel maps "debug", "info", "warn" or "error" to a slog level.
+func ParseLevel(value string) (slog.Level, error) {
+	switch strings.ToLower(value) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("invalid log level %q: must be debug, info, warn or error", value)
+}
+
+// IsComponent reports whether name is a known component.
+func IsComponent(name string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := components[name]
+	return ok
+}
+
+// SetLevel changes the level of component and records who changed it.
+func SetLevel(name string, level slog.Level, changedBy string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	c, ok := components[name]
+	if !ok {
+		return fmt.Errorf("unknown log component %q", name)
+	}
+	now := time.Now().UTC()
+	c.level.Set(level)
+	c.changedBy = changedBy
+	c.changedAt = &now
+	return nil
+}
+
+// Levels returns the current level of every component, sorted by name.
+func Levels() []ComponentLevel {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]ComponentLevel, 0, len(components))
+	for name, c := range components {
+		out = append(out, ComponentLevel{
+			Component: name,
+			Level:     c.level.Level(),
+			ChangedBy: c.changedBy,
+			ChangedAt: c.changedAt,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Component < out[j].Component })
+	return out
+}
+
+// levelHandler filters records by a level that can change while loggers
+// built on it are in use.
+type levelHandler struct {
+	slog.Handler
+	level slog.Leveler
+}
+
+func (h *levelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// captureOutput points the component loggers at a buffer for the test.
+func captureOutput(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	Init(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	t.Cleanup(func() { Init(nil) })
+	return &buf
+}
+
+// resetLevel restores the default level of component after the test.
+func resetLevel(t *testing.T, component string) {
+	t.Helper()
+	t.Cleanup(func() { _ = SetLevel(component, DefaultLevel, "") })
+}
+
+func TestParseLevel(t *testing.T) {
+	for in, want := range map[string]slog.Level{
+		"debug": slog.LevelDebug,
+		"INFO":  slog.LevelInfo,
+		"warn":  slog.LevelWarn,
+		"error": slog.LevelError,
+	} {
+		got, err := ParseLevel(in)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := ParseLevel("trace")
+	assert.Error(t, err)
+}
+
+func TestLogger_FiltersByComponentLevel(t *testing.T) {
+	buf := captureOutput(t)
+	resetLevel(t, ComponentAuth)
+
+	logger := Logger(ComponentAuth)
+	logger.Debug("hidden")
+	assert.Empty(t, buf.String())
+
+	require.NoError(t, SetLevel(ComponentAuth, slog.LevelDebug, "admin-uuid"))
+	// Loggers created before the change follow it
+	logger.Debug("shown")
+	assert.Contains(t, buf.String(), `"msg":"shown"`)
+	assert.Contains(t, buf.String(), `"component":"auth"`)
+
+	// Other components keep their own level
+	buf.Reset()
+	Logger(ComponentWebhooks).Debug("hidden")
+	assert.Empty(t, buf.String())
+}
+
+func TestSetLevel(t *testing.T) {
+	resetLevel(t, ComponentWebhooks)
+
+	assert.Error(t, SetLevel("cache", slog.LevelDebug, "admin-uuid"))
+	assert.False(t, IsComponent("cache"))
+	assert.True(t, IsComponent(ComponentWebhooks))
+
+	require.NoError(t, SetLevel(ComponentWebhooks, slog.LevelWarn, "admin-uuid"))
+	levels := Levels()
+	require.Len(t, levels, 4)
+	assert.Equal(t, []string{ComponentAuth, ComponentDB, ComponentEvents, ComponentWebhooks},
+		[]string{levels[0].Component, levels[1].Component, levels[2].Component, levels[3].Component})
+	assert.Equal(t, slog.LevelWarn, levels[3].Level)
+	assert.Equal(t, "admin-uuid", levels[3].ChangedBy)
+	assert.NotNil(t, levels[3].ChangedAt)
+}
+
+func TestGormLogger_Trace(t *testing.T) {
+	buf := captureOutput(t)
+	resetLevel(t, ComponentDB)
+	l := NewGormLogger(200 * time.Millisecond)
+	ctx := context.Background()
+	query := func() (string, int64) { return "SELECT 1", 1 }
+
+	l.Trace(ctx, time.Now(), query, nil)
+	l.Trace(ctx, time.Now(), query, gorm.ErrRecordNotFound)
+	assert.Empty(t, buf.String(), "routine queries are debug only")
+
+	l.Trace(ctx, time.Now().Add(-time.Second), query, nil)
+	assert.Contains(t, buf.String(), `"msg":"slow query"`)
+	assert.Contains(t, buf.String(), `"sql":"SELECT 1"`)
+
+	buf.Reset()
+	NewGormLogger(0).Trace(ctx, time.Now().Add(-time.Second), query, nil)
+	assert.Empty(t, buf.String(), "a zero threshold disables the slow query log")
+
+	buf.Reset()
+	l.Trace(ctx, time.Now(), query, errors.New("connection reset"))
+	assert.Contains(t, buf.String(), `"msg":"query failed"`)
+	assert.Contains(t, buf.String(), "connection reset")
+
+	buf.Reset()
+	require.NoError(t, SetLevel(ComponentDB, slog.LevelDebug, "admin-uuid"))
+	l.Trace(ctx, time.Now(), query, nil)
+	assert.Contains(t, buf.String(), `"sql":"SELECT 1"`)
+}
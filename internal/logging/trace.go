@@ -0,0 +1,197 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Body trace targets.
+const (
+	TraceTargetTenant = "tenant"
+	TraceTargetClient = "client"
+)
+
+// Bounds on the lifetime of a body trace rule.
+const (
+	MinTraceDuration = time.Minute
+	MaxTraceDuration = time.Hour
+)
+
+// MaxTraceBodyBytes bounds how much of a request or response body is kept
+// for tracing. Longer bodies are logged by size only.
+const MaxTraceBodyBytes = 16 << 10
+
+// TraceRule enables body tracing for the requests of one tenant or client
+// until ExpiresAt. Target is TraceTargetTenant with the tenant UUID as Value,
+// or TraceTargetClient with the client identifier as Value.
+type TraceRule struct {
+	TraceUUID uuid.UUID
+	Target    string
+	Value     string
+	EnabledBy string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+var (
+	traceMu sync.RWMutex
+	traces  = map[uuid.UUID]TraceRule{}
+	now     = time.Now
+)
+
+// AddTrace enables body tracing for target/value during d and records who
+// enabled it. d must be between MinTraceDuration and MaxTraceDuration.
+func AddTrace(target, value string, d time.Duration, enabledBy string) (TraceRule, error) {
+	if target != TraceTargetTenant && target != TraceTargetClient {
+		return TraceRule{}, fmt.Errorf("invalid trace target %q: must be %s or %s", target, TraceTargetTenant, TraceTargetClient)
+	}
+	if value == "" {
+		return TraceRule{}, fmt.Errorf("trace %s is required", target)
+	}
+	if d < MinTraceDuration || d > MaxTraceDuration {
+		return TraceRule{}, fmt.Errorf("trace duration must be between %s and %s", MinTraceDuration, MaxTraceDuration)
+	}
+
+	created := now().UTC()
+	rule := TraceRule{
+		TraceUUID: uuid.New(),
+		Target:    target,
+		Value:     value,
+		EnabledBy: enabledBy,
+		CreatedAt: created,
+		ExpiresAt: created.Add(d),
+	}
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	pruneLocked()
+	traces[rule.TraceUUID] = rule
+	return rule, nil
+}
+
+// RemoveTrace disables a trace rule before it expires. It returns the removed
+// rule and false when no active rule has that ID.
+func RemoveTrace(id uuid.UUID) (TraceRule, bool) {
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	pruneLocked()
+	rule, ok := traces[id]
+	delete(traces, id)
+	return rule, ok
+}
+
+// Traces returns the active trace rules, oldest first.
+func Traces() []TraceRule {
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	pruneLocked()
+	out := make([]TraceRule, 0, len(traces))
+	for _, rule := range traces {
+		out = append(out, rule)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// TracingActive reports whether any trace rule is in effect. Callers use it
+// to skip capturing bodies when nothing is being traced.
+func TracingActive() bool {
+	traceMu.RLock()
+	defer traceMu.RUnlock()
+	t := now()
+	for _, rule := range traces {
+		if t.Before(rule.ExpiresAt) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchTrace returns the active rule that covers a request of tenantUUID
+// through clientID. Either may be empty when unknown.
+func MatchTrace(tenantUUID, clientID string) (TraceRule, bool) {
+	traceMu.RLock()
+	defer traceMu.RUnlock()
+	t := now()
+	for _, rule := range traces {
+		if !t.Before(rule.ExpiresAt) {
+			continue
+		}
+		if (rule.Target == TraceTargetTenant && tenantUUID != "" && rule.Value == tenantUUID) ||
+			(rule.Target == TraceTargetClient && clientID != "" && rule.Value == clientID) {
+			return rule, true
+		}
+	}
+	return TraceRule{}, false
+}
+
+// pruneLocked drops expired rules. Callers hold traceMu for writing.
+func pruneLocked() {
+	t := now()
+	for id, rule := range traces {
+		if !t.Before(rule.ExpiresAt) {
+			delete(traces, id)
+		}
+	}
+}
+
+// redactedKeys are the substrings of JSON keys whose values are never traced.
+var redactedKeys = []string{
+	"password", "secret", "token", "otp", "code", "key", "authorization", "cookie", "credential",
+}
+
+// RedactBody prepares a captured body for logging. JSON bodies are returned
+// with credential-like fields replaced by "[REDACTED]"; empty bodies are
+// returned as ""; other bodies, and bodies cut at MaxTraceBodyBytes, are
+// replaced by a size note because they cannot be redacted reliably.
+func RedactBody(body []byte, truncated bool) string {
+	if len(bytes.TrimSpace(body)) == 0 && !truncated {
+		return ""
+	}
+	if truncated {
+		return fmt.Sprintf("[body over %d bytes omitted]", MaxTraceBodyBytes)
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Sprintf("[non-JSON body of %d bytes omitted]", len(body))
+	}
+	out, err := json.Marshal(redact(v))
+	if err != nil {
+		return fmt.Sprintf("[body of %d bytes omitted]", len(body))
+	}
+	return string(out)
+}
+
+func redact(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			if sensitiveKey(k) {
+				t[k] = "[REDACTED]"
+				continue
+			}
+			t[k] = redact(val)
+		}
+	case []any:
+		for i, val := range t {
+			t[i] = redact(val)
+		}
+	}
+	return v
+}
+
+func sensitiveKey(k string) bool {
+	k = strings.ToLower(k)
+	for _, s := range redactedKeys {
+		if strings.Contains(k, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addTrace adds a rule and removes it after the test.
+func addTrace(t *testing.T, target, value string, d time.Duration) TraceRule {
+	t.Helper()
+	rule, err := AddTrace(target, value, d, "admin-uuid")
+	require.NoError(t, err)
+	t.Cleanup(func() { RemoveTrace(rule.TraceUUID) })
+	return rule
+}
+
+func TestAddTrace_Validation(t *testing.T) {
+	_, err := AddTrace("user", "x", time.Minute, "admin-uuid")
+	assert.Error(t, err)
+	_, err = AddTrace(TraceTargetClient, "", time.Minute, "admin-uuid")
+	assert.Error(t, err)
+	_, err = AddTrace(TraceTargetClient, "web", 30*time.Second, "admin-uuid")
+	assert.Error(t, err)
+	_, err = AddTrace(TraceTargetClient, "web", MaxTraceDuration+time.Second, "admin-uuid")
+	assert.Error(t, err)
+}
+
+func TestMatchTrace(t *testing.T) {
+	tenant := uuid.NewString()
+	tenantRule := addTrace(t, TraceTargetTenant, tenant, time.Minute)
+	clientRule := addTrace(t, TraceTargetClient, "web", time.Minute)
+	assert.True(t, TracingActive())
+
+	got, ok := MatchTrace(tenant, "")
+	require.True(t, ok)
+	assert.Equal(t, tenantRule, got)
+	assert.Equal(t, "admin-uuid", got.EnabledBy)
+
+	got, ok = MatchTrace("", "web")
+	require.True(t, ok)
+	assert.Equal(t, clientRule, got)
+
+	_, ok = MatchTrace(uuid.NewString(), "mobile")
+	assert.False(t, ok)
+	_, ok = MatchTrace("", "")
+	assert.False(t, ok)
+}
+
+func TestTrace_Expiry(t *testing.T) {
+	rule := addTrace(t, TraceTargetClient, "expiring", time.Minute)
+
+	orig := now
+	t.Cleanup(func() { now = orig })
+	now = func() time.Time { return rule.ExpiresAt }
+
+	_, ok := MatchTrace("", "expiring")
+	assert.False(t, ok)
+	assert.NotContains(t, Traces(), rule)
+	_, ok = RemoveTrace(rule.TraceUUID)
+	assert.False(t, ok, "expired rules are dropped")
+}
+
+func TestRemoveTrace(t *testing.T) {
+	rule := addTrace(t, TraceTargetClient, "removed", time.Minute)
+	removed, ok := RemoveTrace(rule.TraceUUID)
+	require.True(t, ok)
+	assert.Equal(t, rule, removed)
+	_, ok = MatchTrace("", "removed")
+	assert.False(t, ok)
+}
+
+func TestRedactBody(t *testing.T) {
+	got := RedactBody([]byte(`{"username":"jane","password":"hunter2","nested":{"ClientSecret":"s"},"items":[{"access_token":"t"}]}`), false)
+	assert.JSONEq(t, `{"username":"jane","password":"[REDACTED]","nested":{"ClientSecret":"[REDACTED]"},"items":[{"access_token":"[REDACTED]"}]}`, got)
+
+	assert.Equal(t, "", RedactBody(nil, false))
+	assert.Equal(t, "[non-JSON body of 17 bytes omitted]", RedactBody([]byte("password=hunter2!"), false))
+	got = RedactBody([]byte(strings.Repeat("a", 10)), true)
+	assert.Contains(t, got, "omitted")
+	assert.NotContains(t, got, "aaa")
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type metricsWidget struct {
+	ID   int64
+	Name string
+}
+
+func newMetricsDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(NewGormPlugin()))
+	return db, mock
+}
+
+func TestGormPlugin(t *testing.T) {
+	assert.Equal(t, "metrics", NewGormPlugin().Name())
+
+	t.Run("records successful queries", func(t *testing.T) {
+		db, mock := newMetricsDB(t)
+		mock.ExpectQuery(`SELECT`).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+
+		var w []metricsWidget
+		require.NoError(t, db.Find(&w).Error)
+
+		assert.Positive(t, testutil.CollectAndCount(DBQueryDuration))
+	})
+
+	t.Run("counts failures but not record not found", func(t *testing.T) {
+		db, mock := newMetricsDB(t)
+		counter := DBQueryErrorsTotal.WithLabelValues("query", "metrics_widgets")
+		before := testutil.ToFloat64(counter)
+
+		mock.ExpectQuery(`SELECT`).WillReturnError(errors.New("boom"))
+		var w []metricsWidget
+		require.Error(t, db.Find(&w).Error)
+
+		mock.ExpectQuery(`SELECT`).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+		var one metricsWidget
+		require.ErrorIs(t, db.First(&one).Error, gorm.ErrRecordNotFound)
+
+		assert.Equal(t, before+1, testutil.ToFloat64(counter))
+	})
+}
+
+func TestRegisterDBStats(t *testing.T) {
+	sqlDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	sqlDB.SetMaxOpenConns(9)
+
+	require.NoError(t, RegisterDBStats(sqlDB, "stats_test"))
+	require.NoError(t, RegisterDBStats(sqlDB, "stats_test"), "registering again is a no-op")
+
+	families, err := Registry.Gather()
+	require.NoError(t, err)
+	var found bool
+	for _, f := range families {
+		if f.GetName() != "go_sql_max_open_connections" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "db_name" && l.GetValue() == "stats_test" {
+					found = true
+					assert.Equal(t, float64(9), m.GetGauge().GetValue())
+				}
+			}
+		}
+	}
+	assert.True(t, found, "pool metrics are exported")
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+
+	okCounter := GRPCRequestsTotal.WithLabelValues(info.FullMethod, codes.OK.String())
+	errCounter := GRPCRequestsTotal.WithLabelValues(info.FullMethod, codes.NotFound.String())
+	okBefore, errBefore := testutil.ToFloat64(okCounter), testutil.ToFloat64(errCounter)
+
+	resp, err := interceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return "ok", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	_, err = interceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return nil, status.Error(codes.NotFound, "missing")
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	assert.Equal(t, okBefore+1, testutil.ToFloat64(okCounter))
+	assert.Equal(t, errBefore+1, testutil.ToFloat64(errCounter))
+}
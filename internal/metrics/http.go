@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// unmatchedRoute labels requests that did not match any route, keeping the
+// route label bounded when clients probe arbitrary paths.
+const unmatchedRoute = "unmatched"
+
+// HTTPMiddleware records request count and latency for every request served
+// by the named server. The route label is the chi route pattern (e.g.
+// /api/v1/users/{user_uuid}) rather than the raw path, so label cardinality
+// stays bounded.
+func HTTPMiddleware(server string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			route := unmatchedRoute
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				if pattern := rctx.RoutePattern(); pattern != "" {
+					route = pattern
+				}
+			}
+
+			HTTPRequestsTotal.WithLabelValues(server, r.Method, route, strconv.Itoa(status)).Inc()
+			HTTPRequestDuration.WithLabelValues(server, r.Method, route).Observe(time.Since(start).Seconds())
+		})
+	}
+}
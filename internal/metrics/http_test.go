@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(HTTPMiddleware("test"))
+	r.Get("/users/{user_uuid}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	r.Get("/implicit", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	t.Run("uses the route pattern and status", func(t *testing.T) {
+		counter := HTTPRequestsTotal.WithLabelValues("test", http.MethodGet, "/users/{user_uuid}", "201")
+		before := testutil.ToFloat64(counter)
+
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/abc", nil))
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/def", nil))
+
+		assert.Equal(t, before+2, testutil.ToFloat64(counter))
+	})
+
+	t.Run("implicit 200", func(t *testing.T) {
+		counter := HTTPRequestsTotal.WithLabelValues("test", http.MethodGet, "/implicit", "200")
+		before := testutil.ToFloat64(counter)
+
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/implicit", nil))
+
+		assert.Equal(t, before+1, testutil.ToFloat64(counter))
+	})
+
+	t.Run("unmatched paths share one label", func(t *testing.T) {
+		counter := HTTPRequestsTotal.WithLabelValues("test", http.MethodGet, unmatchedRoute, "404")
+		before := testutil.ToFloat64(counter)
+
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nope/1", nil))
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nope/2", nil))
+
+		assert.Equal(t, before+2, testutil.ToFloat64(counter))
+	})
+}
@@ -0,0 +1,245 @@
+// Package metrics exposes Prometheus instrumentation for the REST and gRPC
+// servers, database and Redis clients, authentication flows, and background
+// workers and queues.
+//
+// All collectors are registered on a dedicated Registry rather than the
+// global default so that tests and embedded tools do not share state with
+// third-party libraries.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "maintainerd_auth"
+
+// Label values shared by the authentication counters.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Reasons security events are dropped by the SIEM forwarder.
+const (
+	SIEMDropQueueFull    = "queue_full"
+	SIEMDropSendFailed   = "send_failed"
+	SIEMDropTenantLookup = "tenant_lookup"
+)
+
+// Registry holds every collector exported on /metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequestsTotal counts REST requests by server, method, route pattern
+	// and status code.
+	HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Total number of HTTP requests handled.",
+	}, []string{"server", "method", "route", "status"})
+
+	// HTTPRequestDuration observes REST request latency.
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"server", "method", "route"})
+
+	// GRPCRequestsTotal counts unary gRPC calls by full method and status code.
+	GRPCRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "requests_total",
+		Help:      "Total number of gRPC requests handled.",
+	}, []string{"method", "code"})
+
+	// GRPCRequestDuration observes unary gRPC call latency.
+	GRPCRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "gRPC request latency in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	// DBQueryDuration observes database statement latency by operation and table.
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Database query latency in seconds.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	// DBQueryErrorsTotal counts failed database statements, excluding record
+	// not found.
+	DBQueryErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_errors_total",
+		Help:      "Total number of failed database queries.",
+	}, []string{"operation", "table"})
+
+	// RedisCommandDuration observes Redis command latency by command name.
+	RedisCommandDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "redis",
+		Name:      "command_duration_seconds",
+		Help:      "Redis command latency in seconds.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5},
+	}, []string{"command"})
+
+	// RedisCommandErrorsTotal counts failed Redis commands, excluding nil replies.
+	RedisCommandErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "redis",
+		Name:      "command_errors_total",
+		Help:      "Total number of failed Redis commands.",
+	}, []string{"command"})
+
+	// LoginsTotal counts password login attempts by result.
+	LoginsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "logins_total",
+		Help:      "Total number of login attempts by result.",
+	}, []string{"result"})
+
+	// TokensIssuedTotal counts OAuth token endpoint calls by grant type and
+	// result. The result is "success" or the OAuth error code.
+	TokensIssuedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "token_requests_total",
+		Help:      "Total number of OAuth token requests by grant type and result.",
+	}, []string{"grant_type", "result"})
+
+	// TokenIssuanceDuration observes OAuth token endpoint latency by grant type.
+	TokenIssuanceDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "token_issuance_duration_seconds",
+		Help:      "OAuth token issuance latency in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"grant_type"})
+
+	// RateLimitHitsTotal counts requests rejected by a rate limiter, by scope.
+	RateLimitHitsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "rate_limit_hits_total",
+		Help:      "Total number of requests rejected by rate limiting.",
+	}, []string{"scope"})
+
+	// AuthzAuditDroppedTotal counts sampled authorization decisions that were
+	// not written to the audit log because the per-second cap was reached.
+	AuthzAuditDroppedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "authz_audit_dropped_total",
+		Help:      "Total number of sampled authorization decisions dropped by the audit rate cap.",
+	}, []string{"decision"})
+
+	// ClaimsEnrichmentTotal counts external claims lookups at token issuance
+	// by outcome (fetched, cached, error, circuit_open).
+	ClaimsEnrichmentTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "claims_enrichment_total",
+		Help:      "Total number of external claims lookups by outcome.",
+	}, []string{"result"})
+
+	// SIEMEventsDroppedTotal counts security events the SIEM forwarder
+	// dropped instead of delivering, by reason.
+	SIEMEventsDroppedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "siem",
+		Name:      "events_dropped_total",
+		Help:      "Total number of security events dropped by the SIEM forwarder.",
+	}, []string{"reason"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestsTotal,
+		HTTPRequestDuration,
+		GRPCRequestsTotal,
+		GRPCRequestDuration,
+		DBQueryDuration,
+		DBQueryErrorsTotal,
+		RedisCommandDuration,
+		RedisCommandErrorsTotal,
+		LoginsTotal,
+		TokensIssuedTotal,
+		TokenIssuanceDuration,
+		RateLimitHitsTotal,
+		AuthzAuditDroppedTotal,
+		ClaimsEnrichmentTotal,
+		SIEMEventsDroppedTotal,
+		WorkerRunsTotal,
+		WorkerRunDuration,
+		WorkerItemsTotal,
+		WorkerLastSuccess,
+		QueueDepth,
+		QueueOldestAge,
+		QueueAlerting,
+	)
+}
+
+// Handler returns the HTTP handler that serves the Prometheus text exposition
+// format for Registry.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveLogin records the outcome of a login attempt.
+func ObserveLogin(success bool) {
+	LoginsTotal.WithLabelValues(resultLabel(success)).Inc()
+}
+
+// ObserveTokenIssuance records an OAuth token request. result is "success" or
+// the OAuth error code returned to the client.
+func ObserveTokenIssuance(grantType, result string, elapsed time.Duration) {
+	TokensIssuedTotal.WithLabelValues(grantType, result).Inc()
+	TokenIssuanceDuration.WithLabelValues(grantType).Observe(elapsed.Seconds())
+}
+
+// ObserveRateLimitHit records a request rejected by the rate limiter for the
+// given scope (e.g. "login", "password_reset").
+func ObserveRateLimitHit(scope string) {
+	RateLimitHitsTotal.WithLabelValues(scope).Inc()
+}
+
+// ObserveAuthzAuditDrop records a sampled authorization decision that was
+// dropped by the audit rate cap. decision is "allow" or "deny".
+func ObserveAuthzAuditDrop(decision string) {
+	AuthzAuditDroppedTotal.WithLabelValues(decision).Inc()
+}
+
+// ObserveClaimsEnrichment records the outcome of an external claims lookup.
+func ObserveClaimsEnrichment(outcome string) {
+	ClaimsEnrichmentTotal.WithLabelValues(outcome).Inc()
+}
+
+// ObserveSIEMDrop records count security events dropped by the SIEM
+// forwarder for reason.
+func ObserveSIEMDrop(reason string, count int) {
+	SIEMEventsDroppedTotal.WithLabelValues(reason).Add(float64(count))
+}
+
+func resultLabel(success bool) string {
+	if success {
+		return ResultSuccess
+	}
+	return ResultFailure
+}
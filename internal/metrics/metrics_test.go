@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveLogin(t *testing.T) {
+	success := testutil.ToFloat64(LoginsTotal.WithLabelValues(ResultSuccess))
+	failure := testutil.ToFloat64(LoginsTotal.WithLabelValues(ResultFailure))
+
+	ObserveLogin(true)
+	ObserveLogin(false)
+	ObserveLogin(false)
+
+	assert.Equal(t, success+1, testutil.ToFloat64(LoginsTotal.WithLabelValues(ResultSuccess)))
+	assert.Equal(t, failure+2, testutil.ToFloat64(LoginsTotal.WithLabelValues(ResultFailure)))
+}
+
+func TestObserveTokenIssuance(t *testing.T) {
+	before := testutil.ToFloat64(TokensIssuedTotal.WithLabelValues("client_credentials", "invalid_client"))
+
+	ObserveTokenIssuance("client_credentials", "invalid_client", 5*time.Millisecond)
+
+	assert.Equal(t, before+1, testutil.ToFloat64(TokensIssuedTotal.WithLabelValues("client_credentials", "invalid_client")))
+	assert.Positive(t, testutil.CollectAndCount(TokenIssuanceDuration, namespace+"_auth_token_issuance_duration_seconds"))
+}
+
+func TestObserveRateLimitHit(t *testing.T) {
+	before := testutil.ToFloat64(RateLimitHitsTotal.WithLabelValues("login"))
+	ObserveRateLimitHit("login")
+	assert.Equal(t, before+1, testutil.ToFloat64(RateLimitHitsTotal.WithLabelValues("login")))
+}
+
+func TestObserveAuthzAuditDrop(t *testing.T) {
+	before := testutil.ToFloat64(AuthzAuditDroppedTotal.WithLabelValues("deny"))
+	ObserveAuthzAuditDrop("deny")
+	assert.Equal(t, before+1, testutil.ToFloat64(AuthzAuditDroppedTotal.WithLabelValues("deny")))
+}
+
+func TestObserveSIEMDrop(t *testing.T) {
+	before := testutil.ToFloat64(SIEMEventsDroppedTotal.WithLabelValues(SIEMDropSendFailed))
+	ObserveSIEMDrop(SIEMDropSendFailed, 3)
+	assert.Equal(t, before+3, testutil.ToFloat64(SIEMEventsDroppedTotal.WithLabelValues(SIEMDropSendFailed)))
+}
+
+func TestObserveClaimsEnrichment(t *testing.T) {
+	before := testutil.ToFloat64(ClaimsEnrichmentTotal.WithLabelValues("cached"))
+	ObserveClaimsEnrichment("cached")
+	assert.Equal(t, before+1, testutil.ToFloat64(ClaimsEnrichmentTotal.WithLabelValues("cached")))
+}
+
+func TestHandler_ServesExposition(t *testing.T) {
+	ObserveLogin(true)
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "maintainerd_auth_auth_logins_total")
+	assert.Contains(t, body, "go_goroutines")
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisHook is a redis.Hook that records the latency and errors of every
+// command, including those sent in pipelines.
+type RedisHook struct{}
+
+// NewRedisHook returns a RedisHook ready to pass to redis.Client.AddHook.
+func NewRedisHook() *RedisHook {
+	return &RedisHook{}
+}
+
+// DialHook implements redis.Hook.
+func (h *RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+// ProcessHook implements redis.Hook.
+func (h *RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		observeRedis(cmd, time.Since(start))
+		return err
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook. The pipeline latency is
+// attributed to every command it contains.
+func (h *RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		elapsed := time.Since(start)
+		for _, cmd := range cmds {
+			observeRedis(cmd, elapsed)
+		}
+		return err
+	}
+}
+
+func observeRedis(cmd redis.Cmder, elapsed time.Duration) {
+	name := cmd.Name()
+	RedisCommandDuration.WithLabelValues(name).Observe(elapsed.Seconds())
+	if err := cmd.Err(); err != nil && !errors.Is(err, redis.Nil) {
+		RedisCommandErrorsTotal.WithLabelValues(name).Inc()
+	}
+}
@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisHook(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	rdb.AddHook(NewRedisHook())
+	ctx := context.Background()
+
+	errCounter := RedisCommandErrorsTotal.WithLabelValues("get")
+	errBefore := testutil.ToFloat64(errCounter)
+
+	require.NoError(t, rdb.Set(ctx, "k", "v", 0).Err())
+	assert.ErrorIs(t, rdb.Get(ctx, "missing").Err(), redis.Nil)
+
+	_, err := rdb.Pipelined(ctx, func(p redis.Pipeliner) error {
+		p.Incr(ctx, "n")
+		p.Get(ctx, "k")
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Positive(t, testutil.CollectAndCount(RedisCommandDuration))
+	assert.Equal(t, errBefore, testutil.ToFloat64(errCounter), "redis.Nil is not an error")
+}
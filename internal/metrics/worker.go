@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// WorkerRunsTotal counts background worker runs by worker and result.
+	WorkerRunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "worker",
+		Name:      "runs_total",
+		Help:      "Total number of background worker runs by result.",
+	}, []string{"worker", "result"})
+
+	// WorkerRunDuration observes background worker run latency.
+	WorkerRunDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "worker",
+		Name:      "run_duration_seconds",
+		Help:      "Background worker run latency in seconds.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"worker"})
+
+	// WorkerItemsTotal counts the items (events, deliveries, rows) processed
+	// by background workers.
+	WorkerItemsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "worker",
+		Name:      "items_processed_total",
+		Help:      "Total number of items processed by background workers.",
+	}, []string{"worker"})
+
+	// WorkerLastSuccess is the Unix time of the last successful run of each
+	// background worker.
+	WorkerLastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "worker",
+		Name:      "last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful background worker run.",
+	}, []string{"worker"})
+
+	// QueueDepth is the number of items waiting in each queue.
+	QueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "depth",
+		Help:      "Number of items waiting in the queue.",
+	}, []string{"queue"})
+
+	// QueueOldestAge is the age of the oldest item waiting in each queue.
+	QueueOldestAge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "oldest_item_age_seconds",
+		Help:      "Age in seconds of the oldest item waiting in the queue.",
+	}, []string{"queue"})
+
+	// QueueAlerting is 1 while a queue exceeds one of its alert thresholds.
+	QueueAlerting = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "alerting",
+		Help:      "1 while the queue exceeds an alert threshold, 0 otherwise.",
+	}, []string{"queue"})
+)
+
+// WorkerStatus summarises the runs of a background worker since the process
+// started.
+type WorkerStatus struct {
+	Worker              string
+	Runs                int64
+	Failures            int64
+	ConsecutiveFailures int64
+	Items               int64
+	LastRunAt           time.Time
+	LastSuccessAt       time.Time
+	LastError           string
+}
+
+var (
+	workersMu sync.Mutex
+	workers   = map[string]*WorkerStatus{}
+)
+
+// ObserveWorkerRun records one run of a background worker that processed
+// items and ended with err.
+func ObserveWorkerRun(worker string, items int64, err error, elapsed time.Duration) {
+	now := time.Now()
+	success := err == nil
+
+	WorkerRunsTotal.WithLabelValues(worker, resultLabel(success)).Inc()
+	WorkerRunDuration.WithLabelValues(worker).Observe(elapsed.Seconds())
+	if items > 0 {
+		WorkerItemsTotal.WithLabelValues(worker).Add(float64(items))
+	}
+	if success {
+		WorkerLastSuccess.WithLabelValues(worker).Set(float64(now.Unix()))
+	}
+
+	workersMu.Lock()
+	defer workersMu.Unlock()
+	st, ok := workers[worker]
+	if !ok {
+		st = &WorkerStatus{Worker: worker}
+		workers[worker] = st
+	}
+	st.Runs++
+	st.Items += items
+	st.LastRunAt = now
+	if success {
+		st.ConsecutiveFailures = 0
+		st.LastSuccessAt = now
+		return
+	}
+	st.Failures++
+	st.ConsecutiveFailures++
+	st.LastError = err.Error()
+}
+
+// Workers returns the status of every worker that has run, ordered by name.
+func Workers() []WorkerStatus {
+	workersMu.Lock()
+	defer workersMu.Unlock()
+
+	out := make([]WorkerStatus, 0, len(workers))
+	for _, st := range workers {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Worker < out[j].Worker })
+	return out
+}
+
+// SetQueue records the depth, oldest item age and alert state of a queue.
+func SetQueue(queue string, depth int64, oldestAge time.Duration, alerting bool) {
+	QueueDepth.WithLabelValues(queue).Set(float64(depth))
+	QueueOldestAge.WithLabelValues(queue).Set(oldestAge.Seconds())
+	alert := 0.0
+	if alerting {
+		alert = 1
+	}
+	QueueAlerting.WithLabelValues(queue).Set(alert)
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findWorker(t *testing.T, name string) WorkerStatus {
+	t.Helper()
+	for _, st := range Workers() {
+		if st.Worker == name {
+			return st
+		}
+	}
+	t.Fatalf("worker %q not found", name)
+	return WorkerStatus{}
+}
+
+func TestObserveWorkerRun(t *testing.T) {
+	const worker = "test_worker"
+
+	ObserveWorkerRun(worker, 3, nil, 10*time.Millisecond)
+	st := findWorker(t, worker)
+	assert.Equal(t, int64(1), st.Runs)
+	assert.Equal(t, int64(3), st.Items)
+	assert.Zero(t, st.Failures)
+	assert.False(t, st.LastSuccessAt.IsZero())
+	assert.Equal(t, float64(1), testutil.ToFloat64(WorkerRunsTotal.WithLabelValues(worker, ResultSuccess)))
+	assert.Equal(t, float64(3), testutil.ToFloat64(WorkerItemsTotal.WithLabelValues(worker)))
+	assert.Positive(t, testutil.ToFloat64(WorkerLastSuccess.WithLabelValues(worker)))
+
+	ObserveWorkerRun(worker, 0, errors.New("db down"), time.Millisecond)
+	ObserveWorkerRun(worker, 0, errors.New("db still down"), time.Millisecond)
+	st = findWorker(t, worker)
+	assert.Equal(t, int64(3), st.Runs)
+	assert.Equal(t, int64(2), st.Failures)
+	assert.Equal(t, int64(2), st.ConsecutiveFailures)
+	assert.Equal(t, "db still down", st.LastError)
+	assert.Equal(t, float64(2), testutil.ToFloat64(WorkerRunsTotal.WithLabelValues(worker, ResultFailure)))
+
+	ObserveWorkerRun(worker, 1, nil, time.Millisecond)
+	st = findWorker(t, worker)
+	assert.Zero(t, st.ConsecutiveFailures)
+	assert.Equal(t, int64(2), st.Failures)
+}
+
+func TestWorkers_Sorted(t *testing.T) {
+	ObserveWorkerRun("zz_worker", 0, nil, 0)
+	ObserveWorkerRun("aa_worker", 0, nil, 0)
+
+	list := Workers()
+	require.NotEmpty(t, list)
+	for i := 1; i < len(list); i++ {
+		assert.Less(t, list[i-1].Worker, list[i].Worker)
+	}
+}
+
+func TestSetQueue(t *testing.T) {
+	SetQueue("test_queue", 12, 90*time.Second, true)
+	assert.Equal(t, float64(12), testutil.ToFloat64(QueueDepth.WithLabelValues("test_queue")))
+	assert.Equal(t, float64(90), testutil.ToFloat64(QueueOldestAge.WithLabelValues("test_queue")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(QueueAlerting.WithLabelValues("test_queue")))
+
+	SetQueue("test_queue", 0, 0, false)
+	assert.Zero(t, testutil.ToFloat64(QueueDepth.WithLabelValues("test_queue")))
+	assert.Zero(t, testutil.ToFloat64(QueueAlerting.WithLabelValues("test_queue")))
+}
@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/model"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+)
+
+// APIKeyHeader is the request header that carries a plain-text API key.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyValidator is the minimal interface required by APIKeyAuthMiddleware
+// to resolve a plain-text API key. It returns an *apperror.UnauthorizedError
+// when the key is unknown, inactive or expired.
+type APIKeyValidator interface {
+	ValidateAPIKey(ctx context.Context, apiKey string) (*model.APIKey, error)
+}
+
+// apiKeyPrincipalKey is the unexported context key type for APIKeyPrincipal.
+type apiKeyPrincipalKey struct{}
+
+// APIKeyPrincipal is the API key a request was authenticated with. It is set
+// by APIKeyAuthMiddleware and retrieved via APIKeyFromRequest.
+type APIKeyPrincipal struct {
+	APIKeyUUID  uuid.UUID
+	TenantID    int64
+	Name        string
+	Permissions []string
+}
+
+// HasPermission reports whether the key was granted the named permission on
+// any of its APIs.
+func (p *APIKeyPrincipal) HasPermission(name string) bool {
+	for _, perm := range p.Permissions {
+		if perm == name {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyFromRequest returns the APIKeyPrincipal stored in the request context
+// by APIKeyAuthMiddleware, or nil when the request did not carry an API key.
+func APIKeyFromRequest(r *http.Request) *APIKeyPrincipal {
+	return APIKeyFromContext(r.Context())
+}
+
+// APIKeyFromContext returns the APIKeyPrincipal stored in ctx, or nil.
+func APIKeyFromContext(ctx context.Context) *APIKeyPrincipal {
+	p, _ := ctx.Value(apiKeyPrincipalKey{}).(*APIKeyPrincipal)
+	return p
+}
+
+// ContextWithAPIKey returns a copy of ctx carrying p. It is intended for use
+// in tests.
+func ContextWithAPIKey(ctx context.Context, p *APIKeyPrincipal) context.Context {
+	return context.WithValue(ctx, apiKeyPrincipalKey{}, p)
+}
+
+// APIKeyAuthMiddleware authenticates requests bearing an X-API-Key header.
+// Valid keys are rate limited to their RateLimit requests per minute, counted
+// against the request quotas of their tenant and for usage tracking, and
+// stored in the request context as an APIKeyPrincipal together with an
+// AuthContext holding the key's tenant, so JWTAuthMiddleware and
+// UserContextMiddleware pass the request through. The AuthContext has no
+// user: handlers that act as a user answer 401.
+// Requests without the header pass through untouched so the route can fall
+// back to another authentication method.
+func APIKeyAuthMiddleware(validator APIKeyValidator, appCache *cache.Cache) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			plainKey := r.Header.Get(APIKeyHeader)
+			if plainKey == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			key, err := validator.ValidateAPIKey(ctx, plainKey)
+			if err != nil {
+				var unauthorized *apperror.UnauthorizedError
+				if errors.As(err, &unauthorized) {
+					resp.ErrorWithCode(w, apperror.CodeInvalidAPIKey, "Invalid API key")
+					return
+				}
+				slog.ErrorContext(ctx, "api key: validation failed", "error", err)
+				resp.Error(w, http.StatusInternalServerError, "Failed to validate API key")
+				return
+			}
+
+			now := time.Now()
+			if key.RateLimit != nil && *key.RateLimit > 0 {
+				allowed, retryAfter, err := appCache.AllowAPIKeyRequest(ctx, key.APIKeyUUID, *key.RateLimit, now)
+				switch {
+				case err != nil:
+					// Fail open: an unavailable Redis must not take down
+					// every API key client.
+					slog.WarnContext(ctx, "api key: rate limit check failed", "error", err)
+				case !allowed:
+					w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+					resp.ErrorWithCode(w, apperror.CodeAPIKeyRateLimited, "API key rate limit exceeded")
+					return
+				}
+			}
+
+			if !meterRequest(w, r, key.TenantID, key.APIKeyID) {
+				return
+			}
+
+			if err := appCache.RecordAPIKeyUse(ctx, key.APIKeyID, now); err != nil {
+				slog.WarnContext(ctx, "api key: failed to record usage", "error", err)
+			}
+
+			ctx = context.WithValue(ctx, authKey{}, &AuthContext{Tenant: key.Tenant})
+			ctx = context.WithValue(ctx, apiKeyPrincipalKey{}, newAPIKeyPrincipal(key))
+			traceTenant(ctx, key.Tenant, "")
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// newAPIKeyPrincipal flattens the permissions granted on each of the key's
+// APIs into a single list of permission names.
+func newAPIKeyPrincipal(key *model.APIKey) *APIKeyPrincipal {
+	p := &APIKeyPrincipal{
+		APIKeyUUID: key.APIKeyUUID,
+		TenantID:   key.TenantID,
+		Name:       key.Name,
+	}
+	seen := make(map[string]bool)
+	for _, api := range key.APIKeyAPIs {
+		for _, perm := range api.Permissions {
+			if perm.Permission == nil || seen[perm.Permission.Name] {
+				continue
+			}
+			seen[perm.Permission.Name] = true
+			p.Permissions = append(p.Permissions, perm.Permission.Name)
+		}
+	}
+	return p
+}
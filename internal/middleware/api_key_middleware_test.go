@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubAPIKeyValidator struct {
+	key *model.APIKey
+	err error
+}
+
+func (s *stubAPIKeyValidator) ValidateAPIKey(_ context.Context, _ string) (*model.APIKey, error) {
+	return s.key, s.err
+}
+
+func testAPIKey(rateLimit *int) *model.APIKey {
+	read := &model.Permission{Name: "orders:read"}
+	return &model.APIKey{
+		APIKeyID:   7,
+		APIKeyUUID: uuid.New(),
+		TenantID:   3,
+		Name:       "billing",
+		RateLimit:  rateLimit,
+		APIKeyAPIs: []model.APIKeyAPI{
+			{Permissions: []model.APIKeyPermission{{Permission: read}, {Permission: &model.Permission{Name: "orders:write"}}}},
+			{Permissions: []model.APIKeyPermission{{Permission: read}, {}}},
+		},
+	}
+}
+
+func serveAPIKey(t *testing.T, v APIKeyValidator, c *cache.Cache, key string) (*httptest.ResponseRecorder, *APIKeyPrincipal) {
+	t.Helper()
+	var principal *APIKeyPrincipal
+	h := APIKeyAuthMiddleware(v, c)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal = APIKeyFromRequest(r)
+		w.WriteHeader(http.StatusOK)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if key != "" {
+		req.Header.Set(APIKeyHeader, key)
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr, principal
+}
+
+func TestAPIKeyAuthMiddleware_NoHeaderPassesThrough(t *testing.T) {
+	rr, principal := serveAPIKey(t, &stubAPIKeyValidator{err: errors.New("must not be called")}, newFakeCache(), "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Nil(t, principal)
+}
+
+func TestAPIKeyAuthMiddleware_InvalidKey(t *testing.T) {
+	rr, _ := serveAPIKey(t, &stubAPIKeyValidator{err: apperror.NewUnauthorized("invalid api key")}, newFakeCache(), "ak_bad")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr, _ = serveAPIKey(t, &stubAPIKeyValidator{err: errors.New("db down")}, newFakeCache(), "ak_bad")
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
+
+func TestAPIKeyAuthMiddleware_InjectsPrincipalAndRecordsUsage(t *testing.T) {
+	_, cli := newMiniredisClient(t)
+	c := cache.New(cli)
+	key := testAPIKey(nil)
+
+	rr, principal := serveAPIKey(t, &stubAPIKeyValidator{key: key}, c, "ak_good")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NotNil(t, principal)
+	assert.Equal(t, key.APIKeyUUID, principal.APIKeyUUID)
+	assert.Equal(t, int64(3), principal.TenantID)
+	assert.Equal(t, []string{"orders:read", "orders:write"}, principal.Permissions)
+	assert.True(t, principal.HasPermission("orders:write"))
+	assert.False(t, principal.HasPermission("orders:delete"))
+
+	usage, err := c.TakeAPIKeyUsage(context.Background())
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	assert.Equal(t, int64(7), usage[0].APIKeyID)
+	assert.Equal(t, int64(1), usage[0].Count)
+}
+
+func TestAPIKeyAuthMiddleware_RateLimit(t *testing.T) {
+	_, cli := newMiniredisClient(t)
+	c := cache.New(cli)
+	limit := 1
+	v := &stubAPIKeyValidator{key: testAPIKey(&limit)}
+
+	rr, _ := serveAPIKey(t, v, c, "ak_good")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr, principal := serveAPIKey(t, v, c, "ak_good")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+	assert.Nil(t, principal)
+}
+
+func TestAPIKeyAuthMiddleware_RedisDownFailsOpen(t *testing.T) {
+	limit := 1
+	rr, principal := serveAPIKey(t, &stubAPIKeyValidator{key: testAPIKey(&limit)}, newFakeCache(), "ak_good")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotNil(t, principal)
+}
+
+func TestAPIKeyFromContext(t *testing.T) {
+	assert.Nil(t, APIKeyFromContext(context.Background()))
+	p := &APIKeyPrincipal{Name: "x"}
+	assert.Same(t, p, APIKeyFromContext(ContextWithAPIKey(context.Background(), p)))
+}
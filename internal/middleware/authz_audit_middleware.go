@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+)
+
+// Transports on which an authorization decision can be made.
+const (
+	AuthzTransportREST = "rest"
+	AuthzTransportGRPC = "grpc"
+)
+
+// Reasons attached to an authorization decision. They explain which branch
+// of the permission check produced the outcome.
+const (
+	AuthzReasonPermissionGranted    = "permission_granted"
+	AuthzReasonNoMatchingPermission = "no_matching_permission"
+	AuthzReasonPolicyDenied         = "policy_denied"
+)
+
+// AuthzDecision captures the inputs and outcome of a single permission check
+// so a sampled copy can be written to the audit log.
+type AuthzDecision struct {
+	TenantID            int64
+	ActorUserID         int64
+	IPAddress           string
+	UserAgent           string
+	Transport           string
+	Resource            string
+	Allowed             bool
+	Reason              string
+	RequiredPermissions []string
+	MatchedPermission   string
+	EvaluatedRoles      []string
+}
+
+// AuthzAuditor is the minimal interface required to record authorization
+// decisions. Implementations decide whether a decision is sampled and must
+// never block or fail the request.
+type AuthzAuditor interface {
+	Record(ctx context.Context, decision AuthzDecision)
+}
+
+// authzAuditorKey is the unexported context key type for the AuthzAuditor.
+type authzAuditorKey struct{}
+
+// AuthzAuditMiddleware makes auditor available to PermissionMiddleware for
+// every request on the router. Routes without it skip decision auditing.
+func AuthzAuditMiddleware(auditor AuthzAuditor) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), authzAuditorKey{}, auditor)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// authzAuditorFromContext returns the auditor stored by AuthzAuditMiddleware,
+// or nil when none is configured.
+func authzAuditorFromContext(ctx context.Context) AuthzAuditor {
+	if a, ok := ctx.Value(authzAuditorKey{}).(AuthzAuditor); ok {
+		return a
+	}
+	return nil
+}
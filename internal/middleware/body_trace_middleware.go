@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/maintainerd/auth/internal/logging"
+	"github.com/maintainerd/auth/internal/rest/response"
+)
+
+// traceScopeKey is the context key of the request's traceScope.
+type traceScopeKey struct{}
+
+// traceScope identifies the tenant and client a request belongs to. The
+// tenant is only known once UserContextMiddleware has run, which is after
+// BodyTraceMiddleware has wrapped the request, so it is filled in through
+// this shared holder.
+type traceScope struct {
+	tenantUUID string
+	clientID   string
+}
+
+// setTraceScope records the tenant and client of the request for
+// BodyTraceMiddleware. It is a no-op when the middleware is not installed.
+func setTraceScope(ctx context.Context, tenantUUID, clientID string) {
+	if s, ok := ctx.Value(traceScopeKey{}).(*traceScope); ok {
+		s.tenantUUID = tenantUUID
+		if clientID != "" {
+			s.clientID = clientID
+		}
+	}
+}
+
+// boundedBuffer keeps the first MaxTraceBodyBytes written to it and notes
+// whether anything was dropped.
+type boundedBuffer struct {
+	bytes.Buffer
+	truncated bool
+}
+
+func (b *boundedBuffer) keep(p []byte) {
+	if room := logging.MaxTraceBodyBytes - b.Len(); room < len(p) {
+		b.truncated = true
+		if room <= 0 {
+			return
+		}
+		p = p[:room]
+	}
+	b.Write(p)
+}
+
+// bodyRecorder copies the response body into a bounded buffer while writing
+// it to the client.
+type bodyRecorder struct {
+	http.ResponseWriter
+	status int
+	body   boundedBuffer
+}
+
+func (r *bodyRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *bodyRecorder) Write(p []byte) (int, error) {
+	r.body.keep(p)
+	return r.ResponseWriter.Write(p)
+}
+
+// Unwrap allows middleware that probe for optional interfaces to reach the
+// underlying ResponseWriter.
+func (r *bodyRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// BodyTraceMiddleware logs the redacted request and response bodies of
+// requests covered by an active body trace rule (see logging.AddTrace). When
+// no rule is active requests pass through untouched. Public requests are
+// matched by their client_id query parameter; authenticated requests also by
+// the tenant and client resolved by UserContextMiddleware. It must be
+// registered after LoggingMiddleware so that trace entries carry the
+// request_id.
+func BodyTraceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !logging.TracingActive() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var reqBody boundedBuffer
+		if r.Body != nil {
+			captured, err := io.ReadAll(io.LimitReader(r.Body, logging.MaxTraceBodyBytes+1))
+			reqBody.keep(captured)
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(captured), r.Body), r.Body}
+			if err != nil {
+				reqBody.truncated = true
+			}
+		}
+
+		scope := &traceScope{clientID: r.URL.Query().Get("client_id")}
+		ctx := context.WithValue(r.Context(), traceScopeKey{}, scope)
+		rec := &bodyRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		rule, ok := logging.MatchTrace(scope.tenantUUID, scope.clientID)
+		if !ok {
+			return
+		}
+		response.LoggerFromContext(ctx).Info("body trace",
+			"trace_uuid", rule.TraceUUID.String(),
+			"trace_target", rule.Target,
+			"trace_enabled_by", rule.EnabledBy,
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"request_body", logging.RedactBody(reqBody.Bytes(), reqBody.truncated),
+			"response_body", logging.RedactBody(rec.body.Bytes(), rec.body.truncated),
+		)
+	})
+}
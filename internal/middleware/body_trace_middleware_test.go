@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maintainerd/auth/internal/logging"
+	"github.com/maintainerd/auth/internal/model"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+)
+
+// enableTrace adds a body trace rule for the duration of the test.
+func enableTrace(t *testing.T, target, value string) logging.TraceRule {
+	t.Helper()
+	rule, err := logging.AddTrace(target, value, time.Minute, "admin-uuid")
+	require.NoError(t, err)
+	t.Cleanup(func() { logging.RemoveTrace(rule.TraceUUID) })
+	return rule
+}
+
+// serveTraced runs req through BodyTraceMiddleware with a request logger
+// writing to the returned builder. next echoes the request body.
+func serveTraced(t *testing.T, req *http.Request, scope func(r *http.Request)) (*httptest.ResponseRecorder, *strings.Builder) {
+	t.Helper()
+	var buf strings.Builder
+	req = req.WithContext(resp.WithLogger(req.Context(), slog.New(slog.NewJSONHandler(&buf, nil))))
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if scope != nil {
+			scope(r)
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"echo":` + string(body) + `,"access_token":"xyz789"}`)) //nolint:errcheck
+	})
+	rr := httptest.NewRecorder()
+	BodyTraceMiddleware(next).ServeHTTP(rr, req)
+	return rr, &buf
+}
+
+func TestBodyTraceMiddleware_ClientQueryParam(t *testing.T) {
+	rule := enableTrace(t, logging.TraceTargetClient, "web")
+
+	req := httptest.NewRequest(http.MethodPost, "/login?client_id=web", strings.NewReader(`{"username":"jane","password":"hunter2"}`))
+	rr, buf := serveTraced(t, req, nil)
+
+	// The handler still sees the full body
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"password":"hunter2"`)
+
+	out := buf.String()
+	assert.Contains(t, out, `"msg":"body trace"`)
+	assert.Contains(t, out, rule.TraceUUID.String())
+	assert.Contains(t, out, `"trace_enabled_by":"admin-uuid"`)
+	assert.Contains(t, out, `"status":201`)
+	assert.Contains(t, out, `jane`)
+	assert.NotContains(t, out, "hunter2")
+	assert.NotContains(t, out, "xyz789")
+}
+
+func TestBodyTraceMiddleware_TenantFromUserContext(t *testing.T) {
+	tenant := &model.Tenant{TenantUUID: uuid.New()}
+	enableTrace(t, logging.TraceTargetTenant, tenant.TenantUUID.String())
+
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"x"}`))
+	_, buf := serveTraced(t, req, func(r *http.Request) { traceTenant(r.Context(), tenant, "admin-console") })
+	assert.Contains(t, buf.String(), `"msg":"body trace"`)
+
+	// Requests of other tenants are not traced
+	req = httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"x"}`))
+	_, buf = serveTraced(t, req, func(r *http.Request) {
+		traceTenant(r.Context(), &model.Tenant{TenantUUID: uuid.New()}, "admin-console")
+	})
+	assert.Empty(t, buf.String())
+}
+
+func TestBodyTraceMiddleware_NoActiveRule(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/login?client_id=web", strings.NewReader(`{"a":1}`))
+	rr, buf := serveTraced(t, req, nil)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"echo":{"a":1}`)
+	assert.Empty(t, buf.String())
+}
+
+func TestBodyTraceMiddleware_LargeBody(t *testing.T) {
+	enableTrace(t, logging.TraceTargetClient, "web")
+
+	large := `"` + strings.Repeat("a", logging.MaxTraceBodyBytes) + `"`
+	req := httptest.NewRequest(http.MethodPost, "/login?client_id=web", strings.NewReader(large))
+	rr, buf := serveTraced(t, req, nil)
+
+	assert.Contains(t, rr.Body.String(), large, "the handler must receive the whole body")
+	assert.Contains(t, buf.String(), "omitted")
+	assert.NotContains(t, buf.String(), "aaaa")
+}
@@ -0,0 +1,233 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/security"
+	"github.com/maintainerd/auth/internal/session"
+)
+
+// Browser session values.
+const (
+	browserSessionUserUUID   = "user_uuid"
+	browserSessionClientID   = "client_id"
+	browserSessionProviderID = "provider_id"
+	browserSessionSessionID  = "sid"
+	browserSessionCSRFToken  = "csrf_token"
+	browserSessionCreatedAt  = "created_at"
+	browserSessionExpiresAt  = "expires_at"
+	browserSessionSeenAt     = "seen_at"
+)
+
+// browserSessionTouchInterval is how often a busy session is saved again to
+// slide its expiry, so that not every request writes to Redis.
+const browserSessionTouchInterval = time.Minute
+
+// browserSessionKey is the unexported context key type for BrowserSession.
+type browserSessionKey struct{}
+
+// BrowserSession is the cookie session a request was authenticated with. It
+// is set by BrowserSessionMiddleware and retrieved via
+// BrowserSessionFromRequest.
+type BrowserSession struct {
+	UserUUID   uuid.UUID
+	ClientID   string
+	ProviderID string
+	// SessionID is the login session (the OIDC "sid" claim) the browser
+	// session was started in. Ending the login session through the OIDC
+	// end_session endpoint signs the user out of every client of it.
+	SessionID string
+	// CSRFToken must accompany every state-changing request of the session.
+	CSRFToken string
+	CreatedAt time.Time
+	// ExpiresAt is the end of the session's absolute lifetime. The session
+	// ends earlier when it is idle for longer than the store's TTL.
+	ExpiresAt time.Time
+
+	sess   *session.Session
+	seenAt time.Time
+}
+
+// BrowserSessionFromRequest returns the BrowserSession stored in the request
+// context, or nil when the request was not authenticated with a browser
+// session.
+func BrowserSessionFromRequest(r *http.Request) *BrowserSession {
+	s, _ := r.Context().Value(browserSessionKey{}).(*BrowserSession)
+	return s
+}
+
+// WithBrowserSession returns a shallow copy of r with s stored in its
+// context. It is intended for use in tests.
+func WithBrowserSession(r *http.Request, s *BrowserSession) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), browserSessionKey{}, s))
+}
+
+// StartBrowserSession signs the user in to a new browser session that ends
+// after lifetime at the latest, and writes its cookie. sessionID is the
+// login session the user signed in with. Any session the browser already
+// had is replaced under a new ID, preventing session fixation.
+func StartBrowserSession(w http.ResponseWriter, r *http.Request, store session.Store, lifetime time.Duration, userUUID uuid.UUID, clientID, providerID, sessionID string) (*BrowserSession, error) {
+	sess, err := store.Load(r)
+	if err != nil {
+		return nil, err
+	}
+	token, err := security.GenerateCSRFToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sess.Renew()
+	sess.Values = map[string]string{
+		browserSessionUserUUID:   userUUID.String(),
+		browserSessionClientID:   clientID,
+		browserSessionProviderID: providerID,
+		browserSessionSessionID:  sessionID,
+		browserSessionCSRFToken:  token,
+		browserSessionCreatedAt:  strconv.FormatInt(now.Unix(), 10),
+		browserSessionExpiresAt:  strconv.FormatInt(now.Add(lifetime).Unix(), 10),
+		browserSessionSeenAt:     strconv.FormatInt(now.Unix(), 10),
+	}
+	if err := store.Save(r.Context(), w, sess); err != nil {
+		return nil, err
+	}
+	return toBrowserSession(sess)
+}
+
+// EndBrowserSession deletes the request's browser session, if any, and
+// expires its cookie.
+func EndBrowserSession(w http.ResponseWriter, r *http.Request, store session.Store) error {
+	if bs := BrowserSessionFromRequest(r); bs != nil && bs.sess != nil {
+		return store.Destroy(r.Context(), w, bs.sess)
+	}
+	sess, err := store.Load(r)
+	if err != nil {
+		return err
+	}
+	return store.Destroy(r.Context(), w, sess)
+}
+
+// BrowserSessionMiddleware authenticates requests with the browser session
+// cookie of first-party web apps. Like PersonalAccessTokenMiddleware, it
+// stores the session owner's JWTClaims, so JWTAuthMiddleware passes the
+// request through and UserContextMiddleware resolves the user as for a
+// token.
+//
+// State-changing requests must send the session's CSRF token in the
+// X-CSRF-Token header, or they are rejected with 403. Sessions slide: a
+// request extends the session by the store's TTL, saving it at most once a
+// minute, until the absolute lifetime set at sign-in is reached.
+//
+// Requests with an Authorization header or API key, without a valid session
+// or when store is nil pass through unauthenticated.
+func BrowserSessionMiddleware(store session.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if store == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "" || r.Header.Get(APIKeyHeader) != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if _, err := r.Cookie(session.BrowserName); err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			logger := resp.LoggerFromContext(ctx)
+
+			sess, err := store.Load(r)
+			if err != nil {
+				logger.Error("browser session: load failed", "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+			bs, err := toBrowserSession(sess)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			now := time.Now()
+			if !now.Before(bs.ExpiresAt) {
+				if err := store.Destroy(ctx, w, sess); err != nil {
+					logger.Warn("browser session: destroy expired session failed", "error", err)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			safe := r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions
+			if !safe && subtle.ConstantTimeCompare([]byte(r.Header.Get(CSRFHeader)), []byte(bs.CSRFToken)) != 1 {
+				logger.Warn("browser session: csrf token mismatch", "method", r.Method, "path", r.URL.Path)
+				resp.ErrorWithCode(w, apperror.CodeInvalidCSRFToken, "Invalid or missing CSRF token")
+				return
+			}
+
+			if now.Sub(bs.seenAt) >= browserSessionTouchInterval {
+				sess.Set(browserSessionSeenAt, strconv.FormatInt(now.Unix(), 10))
+				if err := store.Save(ctx, w, sess); err != nil {
+					logger.Warn("browser session: extend failed", "error", err)
+				}
+			}
+
+			ctx = context.WithValue(ctx, jwtKey{}, &JWTClaims{
+				Sub:        bs.UserUUID.String(),
+				UserUUID:   bs.UserUUID,
+				ClientID:   bs.ClientID,
+				ProviderID: bs.ProviderID,
+				SessionID:  bs.SessionID,
+			})
+			ctx = context.WithValue(ctx, browserSessionKey{}, bs)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// toBrowserSession reads a browser session out of sess. It fails for empty
+// sessions and sessions that were not started by StartBrowserSession.
+func toBrowserSession(sess *session.Session) (*BrowserSession, error) {
+	userUUID, err := uuid.Parse(sess.Get(browserSessionUserUUID))
+	if err != nil {
+		return nil, err
+	}
+	createdAt, err := strconv.ParseInt(sess.Get(browserSessionCreatedAt), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt, err := strconv.ParseInt(sess.Get(browserSessionExpiresAt), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	seenAt, err := strconv.ParseInt(sess.Get(browserSessionSeenAt), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if sess.Get(browserSessionCSRFToken) == "" {
+		return nil, errors.New("browser session has no csrf token")
+	}
+
+	return &BrowserSession{
+		UserUUID:   userUUID,
+		ClientID:   sess.Get(browserSessionClientID),
+		ProviderID: sess.Get(browserSessionProviderID),
+		SessionID:  sess.Get(browserSessionSessionID),
+		CSRFToken:  sess.Get(browserSessionCSRFToken),
+		CreatedAt:  time.Unix(createdAt, 0),
+		ExpiresAt:  time.Unix(expiresAt, 0),
+		sess:       sess,
+		seenAt:     time.Unix(seenAt, 0),
+	}, nil
+}
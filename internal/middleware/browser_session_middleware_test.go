@@ -0,0 +1,227 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBrowserSessionStore(t *testing.T) session.Store {
+	t.Helper()
+	_, cli := newMiniredisClient(t)
+	return session.NewRedisStore(cli, session.Options{Name: session.BrowserName, TTL: 30 * time.Minute})
+}
+
+// startTestBrowserSession signs a user in and returns the session and its
+// cookie.
+func startTestBrowserSession(t *testing.T, store session.Store, lifetime time.Duration) (*BrowserSession, *http.Cookie) {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	bs, err := StartBrowserSession(rr, httptest.NewRequest(http.MethodPost, "/session", nil), store, lifetime, uuid.New(), "web", "idp", "sid-1")
+	require.NoError(t, err)
+	cookies := rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	return bs, cookies[0]
+}
+
+// browserSessionProbe records the claims and session it was called with.
+type browserSessionProbe struct {
+	claims  *JWTClaims
+	session *BrowserSession
+}
+
+func (p *browserSessionProbe) handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.claims = JWTClaimsFromRequest(r)
+		p.session = BrowserSessionFromRequest(r)
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestStartBrowserSession(t *testing.T) {
+	store := newBrowserSessionStore(t)
+	bs, ck := startTestBrowserSession(t, store, time.Hour)
+
+	assert.Equal(t, session.BrowserName, ck.Name)
+	assert.True(t, ck.HttpOnly)
+	assert.True(t, ck.Secure)
+	assert.Equal(t, "web", bs.ClientID)
+	assert.Equal(t, "sid-1", bs.SessionID)
+	assert.Len(t, bs.CSRFToken, 64)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), bs.ExpiresAt, 2*time.Second)
+
+	// Signing in again issues a new session ID.
+	req := httptest.NewRequest(http.MethodPost, "/session", nil)
+	req.AddCookie(ck)
+	rr := httptest.NewRecorder()
+	_, err := StartBrowserSession(rr, req, store, time.Hour, uuid.New(), "web", "idp", "sid-2")
+	require.NoError(t, err)
+	assert.NotEqual(t, ck.Value, rr.Result().Cookies()[0].Value)
+}
+
+func TestBrowserSessionMiddleware_Authenticates(t *testing.T) {
+	store := newBrowserSessionStore(t)
+	bs, ck := startTestBrowserSession(t, store, time.Hour)
+	probe := &browserSessionProbe{}
+	h := BrowserSessionMiddleware(store)(JWTAuthMiddleware(probe.handler()))
+
+	req := httptest.NewRequest(http.MethodGet, "/profile", nil)
+	req.AddCookie(ck)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NotNil(t, probe.claims)
+	assert.Equal(t, bs.UserUUID, probe.claims.UserUUID)
+	assert.Equal(t, bs.UserUUID.String(), probe.claims.Sub)
+	assert.Equal(t, "web", probe.claims.ClientID)
+	assert.Equal(t, "idp", probe.claims.ProviderID)
+	assert.Equal(t, "sid-1", probe.claims.SessionID)
+	require.NotNil(t, probe.session)
+	assert.Equal(t, bs.CSRFToken, probe.session.CSRFToken)
+	// Seen less than a minute ago: not saved again.
+	assert.Empty(t, rr.Result().Cookies())
+}
+
+func TestBrowserSessionMiddleware_CSRF(t *testing.T) {
+	store := newBrowserSessionStore(t)
+	bs, ck := startTestBrowserSession(t, store, time.Hour)
+	h := BrowserSessionMiddleware(store)(okHandler())
+
+	cases := []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"matching token", bs.CSRFToken, http.StatusOK},
+		{"missing token", "", http.StatusForbidden},
+		{"wrong token", "forged", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/account/password", nil)
+			req.AddCookie(ck)
+			if tc.token != "" {
+				req.Header.Set(CSRFHeader, tc.token)
+			}
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+			assert.Equal(t, tc.status, rr.Code)
+		})
+	}
+}
+
+func TestBrowserSessionMiddleware_Slides(t *testing.T) {
+	store := newBrowserSessionStore(t)
+	_, ck := startTestBrowserSession(t, store, time.Hour)
+
+	// Age the session's last activity past the touch interval.
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(ck)
+	sess, err := store.Load(req)
+	require.NoError(t, err)
+	sess.Set(browserSessionSeenAt, strconv.FormatInt(time.Now().Add(-5*time.Minute).Unix(), 10))
+	require.NoError(t, store.Save(context.Background(), httptest.NewRecorder(), sess))
+
+	rr := httptest.NewRecorder()
+	BrowserSessionMiddleware(store)(okHandler()).ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	cookies := rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, ck.Value, cookies[0].Value)
+	assert.Equal(t, int((30 * time.Minute).Seconds()), cookies[0].MaxAge)
+}
+
+func TestBrowserSessionMiddleware_Expired(t *testing.T) {
+	store := newBrowserSessionStore(t)
+	_, ck := startTestBrowserSession(t, store, -time.Minute)
+	probe := &browserSessionProbe{}
+
+	req := httptest.NewRequest(http.MethodGet, "/profile", nil)
+	req.AddCookie(ck)
+	rr := httptest.NewRecorder()
+	BrowserSessionMiddleware(store)(probe.handler()).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Nil(t, probe.session)
+	cookies := rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, -1, cookies[0].MaxAge)
+}
+
+func TestBrowserSessionMiddleware_PassesThrough(t *testing.T) {
+	store := newBrowserSessionStore(t)
+	_, ck := startTestBrowserSession(t, store, time.Hour)
+
+	cases := []struct {
+		name  string
+		setup func(r *http.Request)
+	}{
+		{"no cookie", func(r *http.Request) {}},
+		{"unknown session", func(r *http.Request) { r.AddCookie(&http.Cookie{Name: session.BrowserName, Value: "unknown"}) }},
+		{"bearer token wins", func(r *http.Request) { r.AddCookie(ck); r.Header.Set("Authorization", "Bearer abc") }},
+		{"api key wins", func(r *http.Request) { r.AddCookie(ck); r.Header.Set(APIKeyHeader, "ak_secret") }},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			probe := &browserSessionProbe{}
+			req := httptest.NewRequest(http.MethodPost, "/profile", nil)
+			tc.setup(req)
+			rr := httptest.NewRecorder()
+			BrowserSessionMiddleware(store)(probe.handler()).ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Nil(t, probe.session)
+			assert.Nil(t, probe.claims)
+		})
+	}
+
+	t.Run("nil store", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/profile", nil)
+		req.AddCookie(ck)
+		rr := httptest.NewRecorder()
+		BrowserSessionMiddleware(nil)(okHandler()).ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+}
+
+func TestEndBrowserSession(t *testing.T) {
+	store := newBrowserSessionStore(t)
+	_, ck := startTestBrowserSession(t, store, time.Hour)
+
+	var rr *httptest.ResponseRecorder
+	h := BrowserSessionMiddleware(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, EndBrowserSession(w, r, store))
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/session", nil)
+	req.AddCookie(ck)
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	require.Equal(t, -1, rr.Result().Cookies()[0].MaxAge)
+
+	// The session is gone.
+	probe := &browserSessionProbe{}
+	rr = httptest.NewRecorder()
+	BrowserSessionMiddleware(store)(probe.handler()).ServeHTTP(rr, req)
+	assert.Nil(t, probe.session)
+}
+
+func TestCSRFMiddleware_SkipsBrowserSessions(t *testing.T) {
+	store := newTestSessionStore(t)
+	h := CSRFMiddleware(store)(okHandler())
+	_, ck := issueCSRFToken(t, h)
+
+	req := httptest.NewRequest(http.MethodPost, "/oauth/consent", nil)
+	req.AddCookie(ck)
+	req = WithBrowserSession(req, &BrowserSession{UserUUID: uuid.New()})
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/security"
+	"github.com/maintainerd/auth/internal/session"
+)
+
+// CSRFHeader carries the CSRF token on state-changing requests, and returns
+// it on safe requests so that scripts can read it.
+const CSRFHeader = "X-CSRF-Token"
+
+// csrfSessionKey is the hosted session value holding the CSRF token.
+const csrfSessionKey = "csrf_token"
+
+// hostedSessionKey is the unexported context key type for the hosted page
+// session loaded by CSRFMiddleware.
+type hostedSessionKey struct{}
+
+// HostedSessionFromRequest returns the hosted page session loaded by
+// CSRFMiddleware, or nil when the request did not pass through it. Handlers
+// should change and save this session rather than load their own copy, so
+// that the CSRF token is kept.
+func HostedSessionFromRequest(r *http.Request) *session.Session {
+	s, _ := r.Context().Value(hostedSessionKey{}).(*session.Session)
+	return s
+}
+
+// CSRFTokenFromRequest returns the CSRF token of the request's hosted page
+// session, or "" when there is none.
+func CSRFTokenFromRequest(r *http.Request) string {
+	if s := HostedSessionFromRequest(r); s != nil {
+		return s.Get(csrfSessionKey)
+	}
+	return ""
+}
+
+// CSRFMiddleware protects the cookie-based hosted pages with synchronizer
+// tokens kept in the hosted page session.
+//
+// Safe requests (GET, HEAD, OPTIONS) get a token, which is created and saved
+// with the session on first use and returned in the X-CSRF-Token response
+// header. State-changing requests of the hosted pages must send it back in
+// the X-CSRF-Token request header, or they are rejected with 403. A request
+// is taken to come from the hosted pages when its session holds a token, it
+// carries one of this service's cookies or it asks for its tokens in
+// cookies, so a first POST without a session is rejected too. Other
+// requests, such as an API client's code exchange, neither read nor set
+// browser state and pass, as do requests authenticated with an
+// Authorization header or API key, which browsers never attach on their
+// own, and requests of a browser session, whose token
+// BrowserSessionMiddleware has checked.
+//
+// A nil store disables the middleware.
+func CSRFMiddleware(sessions session.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if sessions == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "" || r.Header.Get(APIKeyHeader) != "" || BrowserSessionFromRequest(r) != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			logger := resp.LoggerFromContext(ctx)
+			safe := r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions
+
+			sess, err := sessions.Load(r)
+			if err != nil {
+				logger.Error("csrf: load hosted session failed", "error", err)
+				if safe {
+					next.ServeHTTP(w, r)
+					return
+				}
+				// The token cannot be checked, so the request cannot be
+				// trusted.
+				resp.Error(w, http.StatusServiceUnavailable, "Session store unavailable")
+				return
+			}
+
+			token := sess.Get(csrfSessionKey)
+			if !safe {
+				if (token != "" || usesBrowserCredentials(r)) && !validCSRFToken(r, token) {
+					logger.Warn("csrf: token mismatch", "method", r.Method, "path", r.URL.Path)
+					resp.ErrorWithCode(w, apperror.CodeInvalidCSRFToken, "Invalid or missing CSRF token")
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, hostedSessionKey{}, sess)))
+				return
+			}
+
+			if token == "" {
+				if token, err = security.GenerateCSRFToken(); err != nil {
+					logger.Error("csrf: generate token failed", "error", err)
+					next.ServeHTTP(w, r)
+					return
+				}
+				sess.Set(csrfSessionKey, token)
+				if err := sessions.Save(ctx, w, sess); err != nil {
+					logger.Error("csrf: save hosted session failed", "error", err)
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			w.Header().Set(CSRFHeader, token)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, hostedSessionKey{}, sess)))
+		})
+	}
+}
+
+// browserCookieNames are the cookies this service sets in browsers. Cookies
+// of other origins sharing the domain, such as load balancer affinity
+// cookies, say nothing about who sent the request.
+var browserCookieNames = []string{
+	session.DefaultName,
+	session.BrowserName,
+	"access_token",
+	"id_token",
+	"refresh_token",
+	"trusted_device",
+}
+
+// usesBrowserCredentials reports whether r carries one of this service's
+// cookies or asks for its tokens to be set as cookies, as the hosted pages
+// do.
+func usesBrowserCredentials(r *http.Request) bool {
+	if r.Header.Get("X-Token-Delivery") == "cookie" {
+		return true
+	}
+	for _, name := range browserCookieNames {
+		if _, err := r.Cookie(name); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// validCSRFToken reports whether r sends back the session's token, which
+// must have been issued.
+func validCSRFToken(r *http.Request, token string) bool {
+	return token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(CSRFHeader)), []byte(token)) == 1
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSessionStore(t *testing.T) session.Store {
+	t.Helper()
+	store, err := session.NewCookieStore([][]byte{bytes.Repeat([]byte{3}, session.KeySize)}, session.Options{})
+	require.NoError(t, err)
+	return store
+}
+
+// failingSessionStore fails every Load, as a store whose backend is down.
+type failingSessionStore struct{ session.Store }
+
+func (failingSessionStore) Load(*http.Request) (*session.Session, error) {
+	return nil, errors.New("redis down")
+}
+
+// issueCSRFToken runs a GET through the middleware and returns the token and
+// the session cookie it was saved in.
+func issueCSRFToken(t *testing.T, h http.Handler) (string, *http.Cookie) {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/login", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	token := rr.Header().Get(CSRFHeader)
+	require.NotEmpty(t, token)
+	cookies := rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	return token, cookies[0]
+}
+
+func TestCSRFMiddleware_IssuesToken(t *testing.T) {
+	var seen string
+	h := CSRFMiddleware(newTestSessionStore(t))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = CSRFTokenFromRequest(r)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	token, ck := issueCSRFToken(t, h)
+	assert.Equal(t, token, seen)
+
+	// The token is kept for the life of the session.
+	req := httptest.NewRequest(http.MethodGet, "/login", nil)
+	req.AddCookie(ck)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	assert.Equal(t, token, rr.Header().Get(CSRFHeader))
+	assert.Empty(t, rr.Result().Cookies())
+}
+
+// sessionWithoutToken returns the cookie of a hosted session that holds no
+// CSRF token, as one a handler saved before any GET.
+func sessionWithoutToken(t *testing.T) *http.Cookie {
+	t.Helper()
+	store := newTestSessionStore(t)
+	sess, err := store.Load(httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+	sess.Set("client_id", "app")
+	rr := httptest.NewRecorder()
+	require.NoError(t, store.Save(context.Background(), rr, sess))
+	cookies := rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	return cookies[0]
+}
+
+func TestCSRFMiddleware_UnsafeRequests(t *testing.T) {
+	h := CSRFMiddleware(newTestSessionStore(t))(okHandler())
+	token, ck := issueCSRFToken(t, h)
+
+	cases := []struct {
+		name   string
+		setup  func(r *http.Request)
+		status int
+	}{
+		{"matching token", func(r *http.Request) { r.AddCookie(ck); r.Header.Set(CSRFHeader, token) }, http.StatusOK},
+		{"missing token", func(r *http.Request) { r.AddCookie(ck) }, http.StatusForbidden},
+		{"wrong token", func(r *http.Request) { r.AddCookie(ck); r.Header.Set(CSRFHeader, "forged") }, http.StatusForbidden},
+		{"no session, API client", func(r *http.Request) {}, http.StatusOK},
+		{"no session, unrelated cookie", func(r *http.Request) { r.AddCookie(&http.Cookie{Name: "AWSALB", Value: "affinity"}) }, http.StatusOK},
+		{"no session, cookie delivery", func(r *http.Request) { r.Header.Set("X-Token-Delivery", "cookie") }, http.StatusForbidden},
+		{"no session, token cookie", func(r *http.Request) {
+			r.AddCookie(&http.Cookie{Name: "access_token", Value: "jwt"})
+			r.Header.Set(CSRFHeader, "forged")
+		}, http.StatusForbidden},
+		{"session without token", func(r *http.Request) { r.AddCookie(sessionWithoutToken(t)) }, http.StatusForbidden},
+		{"bearer token", func(r *http.Request) { r.AddCookie(ck); r.Header.Set("Authorization", "Bearer abc") }, http.StatusOK},
+		{"api key", func(r *http.Request) { r.AddCookie(ck); r.Header.Set(APIKeyHeader, "ak_secret") }, http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/login", nil)
+			tc.setup(req)
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+			assert.Equal(t, tc.status, rr.Code)
+			if tc.status == http.StatusForbidden {
+				assert.Contains(t, rr.Body.String(), "AUTH-1007")
+			}
+		})
+	}
+}
+
+func TestCSRFMiddleware_StoreDown(t *testing.T) {
+	h := CSRFMiddleware(failingSessionStore{})(okHandler())
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/login", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get(CSRFHeader))
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/login", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+}
+
+func TestCSRFMiddleware_NilStore(t *testing.T) {
+	rr := httptest.NewRecorder()
+	CSRFMiddleware(nil)(okHandler()).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/login", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestHostedSessionFromRequest_Missing(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, HostedSessionFromRequest(req))
+	assert.Empty(t, CSRFTokenFromRequest(req))
+}
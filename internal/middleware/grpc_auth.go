@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/jwt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCAuthInterceptor authenticates the RPCs listed in permissions the way
+// JWTAuthMiddleware, UserContextMiddleware and PermissionMiddleware do for
+// REST: the access token in the "authorization" metadata ("Bearer <token>")
+// is validated, the user and their tenant are resolved, and the user must
+// hold at least one of the permissions listed for the RPC. permissions is
+// keyed by full method name ("/package.Service/Method"). RPCs that are not
+// listed are passed through unauthenticated. Decisions are sampled into
+// auditor when it is set.
+func GRPCAuthInterceptor(
+	userProvider UserContextProvider,
+	appCache *cache.Cache,
+	auditor AuthzAuditor,
+	permissions map[string][]string,
+) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (any, error) {
+		required, ok := permissions[info.FullMethod]
+		if !ok {
+			return next(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		token := bearerToken(md)
+		if token == "" {
+			return nil, status.Error(codes.Unauthenticated, "no valid authentication found")
+		}
+
+		rawClaims, err := jwt.ValidateToken(token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+		}
+		sub, _ := rawClaims["sub"].(string)
+		userUUID, err := uuid.Parse(sub)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid user UUID format")
+		}
+		claims := newJWTClaims(rawClaims, userUUID)
+
+		auth, err := resolveAuthContext(ctx, userProvider, appCache, claims.Sub, claims.ClientID)
+		if err != nil {
+			slog.Error("grpc: failed to load user", "error", err)
+			return nil, status.Error(codes.Internal, "failed to load user")
+		}
+		if auth == nil {
+			return nil, status.Error(codes.Unauthenticated, "user not found")
+		}
+
+		matched, allowed := effectivePermissions(ctx, auth, nil, nil).Match(required)
+		recordAuthzDecision(ctx, auditor, auth, AuthzDecision{
+			IPAddress:           peerIP(ctx),
+			UserAgent:           firstMetadata(md, "user-agent"),
+			Transport:           AuthzTransportGRPC,
+			Resource:            info.FullMethod,
+			RequiredPermissions: required,
+			MatchedPermission:   matched,
+		}, allowed)
+		if !allowed {
+			return nil, status.Error(codes.PermissionDenied, "insufficient permissions")
+		}
+
+		ctx = context.WithValue(ctx, jwtKey{}, claims)
+		return next(context.WithValue(ctx, authKey{}, auth), req)
+	}
+}
+
+// bearerToken returns the token of a "Bearer <token>" authorization entry.
+func bearerToken(md metadata.MD) string {
+	parts := strings.SplitN(firstMetadata(md, "authorization"), " ", 2)
+	if len(parts) == 2 && strings.EqualFold(parts[0], "bearer") {
+		return strings.TrimSpace(parts[1])
+	}
+	return ""
+}
+
+// firstMetadata returns the first value of key, or "" when it is absent.
+func firstMetadata(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// peerIP returns the IP address of the RPC's caller, or "" when unknown.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCAuthInterceptor(t *testing.T) {
+	initTestJWTKeys(t)
+
+	const method = "/maintainerd.auth.v1.UserService/ListUsers"
+	clientID := "web"
+	userUUID := uuid.New()
+	token, err := jwt.GenerateAccessToken(userUUID.String(), "openid", "https://auth.example.com", "api", "web", "idp")
+	require.NoError(t, err)
+
+	tenant := &model.Tenant{TenantID: 7, TenantUUID: uuid.New()}
+	user := &model.User{
+		UserID:   42,
+		UserUUID: userUUID,
+		Roles:    []model.Role{{Name: "viewer", Permissions: []model.Permission{{Name: "user:read"}}}},
+		UserIdentities: []model.UserIdentity{{
+			Client: &model.Client{Identifier: &clientID},
+			Tenant: tenant,
+		}},
+	}
+	provider := &mockContextProvider{findFn: func(sub, clientID string) (*model.User, error) {
+		assert.Equal(t, userUUID.String(), sub)
+		assert.Equal(t, "web", clientID)
+		return user, nil
+	}}
+	permissions := map[string][]string{method: {"user:read"}}
+
+	call := func(ctx context.Context, provider UserContextProvider, auditor AuthzAuditor, fullMethod string) (*AuthContext, error) {
+		var got *AuthContext
+		interceptor := GRPCAuthInterceptor(provider, newFakeCache(), auditor, permissions)
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: fullMethod}, func(ctx context.Context, _ any) (any, error) {
+			got = AuthFromContext(ctx)
+			return nil, nil
+		})
+		return got, err
+	}
+	withToken := func(value string) context.Context {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", value, "user-agent", "svc/1.0"))
+		return peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.5"), Port: 5000}})
+	}
+
+	t.Run("authorized", func(t *testing.T) {
+		auditor := &recordingAuditor{}
+		auth, err := call(withToken("Bearer "+token), provider, auditor, method)
+		require.NoError(t, err)
+		require.NotNil(t, auth.Tenant)
+		assert.Equal(t, int64(7), auth.Tenant.TenantID)
+		assert.Equal(t, int64(42), auth.User.UserID)
+
+		require.Len(t, auditor.decisions, 1)
+		d := auditor.decisions[0]
+		assert.True(t, d.Allowed)
+		assert.Equal(t, AuthzTransportGRPC, d.Transport)
+		assert.Equal(t, method, d.Resource)
+		assert.Equal(t, "10.0.0.5", d.IPAddress)
+		assert.Equal(t, "svc/1.0", d.UserAgent)
+		assert.Equal(t, "user:read", d.MatchedPermission)
+	})
+
+	t.Run("unlisted method passes through", func(t *testing.T) {
+		auth, err := call(context.Background(), provider, nil, "/maintainerd.auth.v1.TokenService/ValidateToken")
+		require.NoError(t, err)
+		assert.Nil(t, auth.User)
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		_, err := call(context.Background(), provider, nil, method)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("wrong scheme", func(t *testing.T) {
+		_, err := call(withToken("Basic "+token), provider, nil, method)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		_, err := call(withToken("Bearer not.a.jwt"), provider, nil, method)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		_, err := call(withToken("Bearer "+token), &mockContextProvider{}, nil, method)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("user lookup fails", func(t *testing.T) {
+		_, err := call(withToken("Bearer "+token), &mockContextProvider{findFn: func(string, string) (*model.User, error) {
+			return nil, errors.New("db down")
+		}}, nil, method)
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("missing permission", func(t *testing.T) {
+		auditor := &recordingAuditor{}
+		interceptor := GRPCAuthInterceptor(provider, newFakeCache(), auditor, map[string][]string{method: {"user:delete"}})
+		_, err := interceptor(withToken("Bearer "+token), nil, &grpc.UnaryServerInfo{FullMethod: method}, func(context.Context, any) (any, error) {
+			t.Fatal("handler must not run")
+			return nil, nil
+		})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		require.Len(t, auditor.decisions, 1)
+		assert.False(t, auditor.decisions[0].Allowed)
+	})
+}
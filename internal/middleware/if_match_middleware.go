@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ifMatchContextKey is the context key IfMatchMiddleware stores the If-Match
+// header under.
+type ifMatchContextKey struct{}
+
+// ETag returns the entity tag of a record version. Records are versioned by
+// their updated_at, which the database keeps with microsecond precision.
+func ETag(updatedAt time.Time) string {
+	return `"` + strconv.FormatInt(updatedAt.UnixMicro(), 36) + `"`
+}
+
+// IfMatchMiddleware stores the If-Match header of the request in its context,
+// so services can refuse to overwrite a record that changed since the client
+// read it. See IfMatchSatisfied.
+func IfMatchMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ifMatch := strings.TrimSpace(r.Header.Get("If-Match")); ifMatch != "" {
+			r = r.WithContext(context.WithValue(r.Context(), ifMatchContextKey{}, ifMatch))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// IfMatchSatisfied reports whether the If-Match precondition stored in ctx
+// holds for the record version updatedAt. It holds when the request sent no
+// If-Match header, sent "*", or listed the record's current ETag. Weak tags
+// never match, as If-Match uses the strong comparison of RFC 9110.
+func IfMatchSatisfied(ctx context.Context, updatedAt time.Time) bool {
+	ifMatch, ok := ctx.Value(ifMatchContextKey{}).(string)
+	if !ok {
+		return true
+	}
+	if ifMatch == "*" {
+		return true
+	}
+	current := ETag(updatedAt)
+	for tag := range strings.SplitSeq(ifMatch, ",") {
+		if strings.TrimSpace(tag) == current {
+			return true
+		}
+	}
+	return false
+}
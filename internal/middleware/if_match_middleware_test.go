@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETag(t *testing.T) {
+	at := time.Date(2026, 10, 18, 9, 0, 0, 123456789, time.UTC)
+	assert.Equal(t, ETag(at.Truncate(time.Microsecond)), ETag(at))
+	assert.NotEqual(t, ETag(at), ETag(at.Add(time.Microsecond)))
+	assert.Equal(t, ETag(at), ETag(at.In(time.FixedZone("UTC+8", 8*3600))))
+}
+
+func TestIfMatchMiddleware(t *testing.T) {
+	version := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	changed := version.Add(time.Second)
+
+	satisfied := func(ifMatch string, updatedAt time.Time) bool {
+		var got bool
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = IfMatchSatisfied(r.Context(), updatedAt)
+		})
+		req := httptest.NewRequest(http.MethodPut, "/", nil)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		IfMatchMiddleware(next).ServeHTTP(httptest.NewRecorder(), req)
+		return got
+	}
+
+	assert.True(t, satisfied("", changed), "no precondition")
+	assert.True(t, satisfied("*", changed))
+	assert.True(t, satisfied(ETag(version), version))
+	assert.True(t, satisfied(`"other", `+ETag(version), version))
+	assert.False(t, satisfied(ETag(version), changed))
+	assert.False(t, satisfied("W/"+ETag(version), version), "weak tags never match")
+	assert.True(t, IfMatchSatisfied(context.Background(), changed), "outside the middleware")
+}
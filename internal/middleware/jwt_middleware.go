@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
 	"github.com/maintainerd/auth/internal/jwt"
 	resp "github.com/maintainerd/auth/internal/rest/response"
 )
@@ -26,6 +27,12 @@ type JWTClaims struct {
 	JTI        string
 	ClientID   string
 	ProviderID string
+	// Actor is the sub of the admin acting through an impersonation token
+	// (the RFC 8693 "act" claim); empty for regular tokens.
+	Actor string
+	// SessionID is the login session the token was issued in (the OIDC
+	// "sid" claim); empty for tokens issued outside a login session.
+	SessionID string
 }
 
 // JWTClaimsFromRequest returns the JWTClaims stored in the request context
@@ -43,8 +50,15 @@ func WithJWTClaims(r *http.Request, claims *JWTClaims) *http.Request {
 
 // JWTAuthMiddleware validates the Bearer token (or access_token cookie) and
 // stores the parsed JWT claims in the request context for downstream use.
+// Requests already authenticated by PersonalAccessTokenMiddleware,
+// APIKeyAuthMiddleware or BrowserSessionMiddleware pass through.
 func JWTAuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if PersonalAccessTokenFromRequest(r) != nil || APIKeyFromRequest(r) != nil || BrowserSessionFromRequest(r) != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		// Get authorization header first
 		authHeader := r.Header.Get("Authorization")
 		var token string
@@ -63,14 +77,14 @@ func JWTAuthMiddleware(next http.Handler) http.Handler {
 		}
 
 		if token == "" {
-			resp.Error(w, http.StatusUnauthorized, "No valid authentication found")
+			resp.ErrorWithCode(w, apperror.CodeMissingCredentials, "No valid authentication found")
 			return
 		}
 
 		// Validate token
 		rawClaims, err := jwt.ValidateToken(token)
 		if err != nil {
-			resp.Error(w, http.StatusUnauthorized, "Invalid or expired token", err.Error())
+			resp.ErrorWithCode(w, apperror.CodeInvalidToken, "Invalid or expired token", err.Error())
 			return
 		}
 
@@ -84,28 +98,42 @@ func JWTAuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		scope, _ := rawClaims["scope"].(string)
-		aud, _ := rawClaims["aud"].(string)
-		iss, _ := rawClaims["iss"].(string)
-		jti, _ := rawClaims["jti"].(string)
-		clientID, _ := rawClaims["client_id"].(string)
-		providerID, _ := rawClaims["provider_id"].(string)
-
-		claims := &JWTClaims{
-			Sub:        sub,
-			UserUUID:   userUUID,
-			Scope:      scope,
-			Audience:   aud,
-			Issuer:     iss,
-			JTI:        jti,
-			ClientID:   clientID,
-			ProviderID: providerID,
-		}
+		claims := newJWTClaims(rawClaims, userUUID)
 
 		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), jwtKey{}, claims)))
 	})
 }
 
+// newJWTClaims copies the claims downstream middleware uses out of a
+// validated token.
+func newJWTClaims(rawClaims map[string]any, userUUID uuid.UUID) *JWTClaims {
+	sub, _ := rawClaims["sub"].(string)
+	scope, _ := rawClaims["scope"].(string)
+	aud, _ := rawClaims["aud"].(string)
+	iss, _ := rawClaims["iss"].(string)
+	jti, _ := rawClaims["jti"].(string)
+	clientID, _ := rawClaims["client_id"].(string)
+	providerID, _ := rawClaims["provider_id"].(string)
+	sessionID, _ := rawClaims["sid"].(string)
+	var actor string
+	if act, ok := rawClaims["act"].(map[string]any); ok {
+		actor, _ = act["sub"].(string)
+	}
+
+	return &JWTClaims{
+		Sub:        sub,
+		UserUUID:   userUUID,
+		Scope:      scope,
+		Audience:   aud,
+		Issuer:     iss,
+		JTI:        jti,
+		ClientID:   clientID,
+		ProviderID: providerID,
+		Actor:      actor,
+		SessionID:  sessionID,
+	}
+}
+
 // GetClientIDFromContext extracts the client_id from the JWT claims stored in
 // the request context. Returns an empty string when claims are absent.
 func GetClientIDFromContext(r *http.Request) string {
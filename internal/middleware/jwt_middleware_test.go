@@ -126,6 +126,51 @@ func TestJWTAuthMiddleware_ContextValues(t *testing.T) {
 	assert.Equal(t, "provider-1", capturedProviderID)
 }
 
+func TestJWTAuthMiddleware_Actor(t *testing.T) {
+	initTestJWTKeys(t)
+
+	adminSub := uuid.New().String()
+	token, err := jwt.GenerateAccessTokenWithClaims(
+		uuid.New().String(), "openid", "https://auth.example.com",
+		"https://api.example.com", "my-client", "provider-1",
+		map[string]any{"act": map[string]any{"sub": adminSub}},
+	)
+	require.NoError(t, err)
+
+	var claims *JWTClaims
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims = JWTClaimsFromRequest(r)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	JWTAuthMiddleware(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NotNil(t, claims)
+	assert.Equal(t, adminSub, claims.Actor)
+}
+
+func TestJWTAuthMiddleware_SessionID(t *testing.T) {
+	initTestJWTKeys(t)
+
+	token, err := jwt.GenerateAccessTokenWithClaims(
+		uuid.New().String(), "openid", "https://auth.example.com",
+		"https://api.example.com", "my-client", "provider-1",
+		map[string]any{"sid": "session-1"},
+	)
+	require.NoError(t, err)
+
+	var claims *JWTClaims
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims = JWTClaimsFromRequest(r)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	JWTAuthMiddleware(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NotNil(t, claims)
+	assert.Equal(t, "session-1", claims.SessionID)
+}
+
 func TestGetClientIDFromContext(t *testing.T) {
 	t.Run("present → returns value", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
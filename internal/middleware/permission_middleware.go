@@ -1,50 +1,373 @@
 package middleware
 
 import (
+	"context"
+	"log/slog"
 	"net/http"
+	"sort"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
 	"github.com/maintainerd/auth/internal/model"
 	resp "github.com/maintainerd/auth/internal/rest/response"
 )
 
-// PermissionMiddleware ensures the user has at least one of the required permissions
-func PermissionMiddleware(requiredPermissions []string) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			auth := AuthFromRequest(r)
-			if auth.User == nil {
-				resp.Error(w, http.StatusUnauthorized, "User not found in context")
-				return
+// PermissionSet is a set of permission names.
+type PermissionSet map[string]struct{}
+
+// Has reports whether the set contains the named permission.
+func (s PermissionSet) Has(name string) bool {
+	_, ok := s[name]
+	return ok
+}
+
+// Match returns the first of required that is in the set.
+func (s PermissionSet) Match(required []string) (string, bool) {
+	for _, rp := range required {
+		if s.Has(rp) {
+			return rp, true
+		}
+	}
+	return "", false
+}
+
+// Names returns the permissions in the set, sorted.
+func (s PermissionSet) Names() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s PermissionSet) add(name string) {
+	if name != "" {
+		s[name] = struct{}{}
+	}
+}
+
+// EffectivePermissions returns the permissions the caller of r may use: those
+// of the user's roles, limited to the permissions granted to the client the
+// request came through when it has any, and those of the API key it carries,
+// narrowed to the token's scopes when it was made with a personal access
+// token. The user and client come from the AuthContext, which
+// UserContextMiddleware serves from the Redis user-context cache. The user's
+// role permissions come from the UserPermissionResolver installed by
+// PermissionResolverMiddleware, if any, together with the permissions of roles
+// scoped to the client, group or group member the route names (see
+// ResourcePermissionResolver).
+func EffectivePermissions(r *http.Request) PermissionSet {
+	return EffectivePermissionsFromContext(r.Context())
+}
+
+// EffectivePermissionsFromContext is EffectivePermissions for a request
+// context, for use outside HTTP handlers.
+func EffectivePermissionsFromContext(ctx context.Context) PermissionSet {
+	pat, _ := ctx.Value(patPrincipalKey{}).(*PersonalAccessTokenPrincipal)
+	return effectivePermissions(ctx, AuthFromContext(ctx), APIKeyFromContext(ctx), pat)
+}
+
+// effectivePermissions collects the user's permissions, capped by those
+// granted to the client, adds the API key's and restricts them to pat's
+// scopes, if any.
+func effectivePermissions(ctx context.Context, auth *AuthContext, apiKey *APIKeyPrincipal, pat *PersonalAccessTokenPrincipal) PermissionSet {
+	set := resolvedUserPermissions(ctx, auth.User)
+	for name := range resolvedResourcePermissions(ctx, auth.User) {
+		set.add(name)
+	}
+	if ceiling := clientPermissions(auth.Client); ceiling != nil {
+		for name := range set {
+			if !ceiling.Has(name) {
+				delete(set, name)
 			}
+		}
+	}
+	if apiKey != nil {
+		for _, name := range apiKey.Permissions {
+			set.add(name)
+		}
+	}
+	if pat != nil {
+		for name := range set {
+			if !pat.Allows(name) {
+				delete(set, name)
+			}
+		}
+	}
+	return set
+}
 
-			// Check user permission
-			if !hasAnyPermission(auth.User, requiredPermissions) {
-				resp.Error(w, http.StatusForbidden, "Insufficient permissions")
-				return
+// clientPermissions returns the permissions granted to the client through
+// its APIs, or nil when it has none, in which case the client does not limit
+// the user.
+func clientPermissions(client *model.Client) PermissionSet {
+	if client == nil || client.ClientAPIs == nil {
+		return nil
+	}
+	var set PermissionSet
+	for _, clientAPI := range *client.ClientAPIs {
+		for _, perm := range clientAPI.Permissions {
+			if perm.Permission != nil && perm.Permission.Name != "" {
+				if set == nil {
+					set = PermissionSet{}
+				}
+				set.add(perm.Permission.Name)
 			}
+		}
+	}
+	return set
+}
+
+// resolvedUserPermissions returns the permissions of the user's roles from
+// the context's resolver. Without a resolver, or when it fails, they are
+// taken from the roles loaded on user.
+func resolvedUserPermissions(ctx context.Context, user *model.User) PermissionSet {
+	resolver := permissionResolverFromContext(ctx)
+	if resolver == nil || user == nil {
+		return userPermissions(user)
+	}
 
-			next.ServeHTTP(w, r)
-		})
+	names, err := resolver.UserPermissions(ctx, user)
+	if err != nil {
+		slog.WarnContext(ctx, "permission resolver failed, using loaded roles", "error", err)
+		return userPermissions(user)
+	}
+	set := PermissionSet{}
+	for _, name := range names {
+		set.add(name)
 	}
+	return set
 }
 
-// hasAnyPermission checks if the user has at least one of the required permissions
-func hasAnyPermission(user *model.User, required []string) bool {
-	userPerms := make(map[string]bool)
+// scopedResourceParams maps the URL parameters that name a client or group to
+// the resource type of the role assignments scoped to it.
+var scopedResourceParams = []struct {
+	param        string
+	resourceType string
+}{
+	{"client_uuid", model.ScopedRoleResourceClient},
+	{"group_uuid", model.ScopedRoleResourceGroup},
+}
+
+// resolvedResourcePermissions returns the permissions the user holds through
+// scoped role assignments on the resources named by the route's URL
+// parameters: the client, the group, and the user when they are a member of a
+// group the assignment is scoped to. They are only known when the context's
+// resolver is a ResourcePermissionResolver; lookups that fail grant nothing.
+func resolvedResourcePermissions(ctx context.Context, user *model.User) PermissionSet {
+	set := PermissionSet{}
+	resolver, ok := permissionResolverFromContext(ctx).(ResourcePermissionResolver)
+	rctx := chi.RouteContext(ctx)
+	if !ok || user == nil || rctx == nil {
+		return set
+	}
+
+	for _, p := range scopedResourceParams {
+		resourceUUID, err := uuid.Parse(rctx.URLParam(p.param))
+		if err != nil {
+			continue
+		}
+		names, err := resolver.ResourcePermissions(ctx, user, p.resourceType, resourceUUID)
+		if err != nil {
+			slog.WarnContext(ctx, "resource permission lookup failed", "resource_type", p.resourceType, "error", err)
+			continue
+		}
+		for _, name := range names {
+			set.add(name)
+		}
+	}
+
+	if memberUUID, err := uuid.Parse(rctx.URLParam("user_uuid")); err == nil {
+		names, err := resolver.MemberPermissions(ctx, user, memberUUID)
+		if err != nil {
+			slog.WarnContext(ctx, "member permission lookup failed", "error", err)
+			return set
+		}
+		for _, name := range names {
+			set.add(name)
+		}
+	}
+	return set
+}
 
-	// Collect user permissions
+// userPermissions collects the permissions of the user's roles. A nil user
+// has none.
+func userPermissions(user *model.User) PermissionSet {
+	set := PermissionSet{}
+	if user == nil {
+		return set
+	}
 	for _, role := range user.Roles {
 		for _, perm := range role.Permissions {
-			userPerms[perm.Name] = true
+			set.add(perm.Name)
 		}
 	}
+	return set
+}
 
-	// Check if any required permission is present
-	for _, rp := range required {
-		if userPerms[rp] {
-			return true
+// HasPermission reports whether the caller of r holds the named permission.
+// It does not write a response or record an authorization decision.
+func HasPermission(r *http.Request, name string) bool {
+	return EffectivePermissions(r).Has(name)
+}
+
+// Authorize checks that the caller of r holds at least one of required and
+// that no deny statement of the tenant's policies (see PolicyMiddleware)
+// applies to it. It records the decision like PermissionMiddleware and, when
+// the check fails, writes the same 401 or 403 response and returns false.
+// Handlers use it for object-level checks that depend on the loaded resource:
+//
+//	if !middleware.Authorize(w, r, "user:update") {
+//		return
+//	}
+func Authorize(w http.ResponseWriter, r *http.Request, required ...string) bool {
+	auth := AuthFromRequest(r)
+	if auth.User == nil && APIKeyFromRequest(r) == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return false
+	}
+
+	matched, ok := EffectivePermissions(r).Match(required)
+	if !ok {
+		auditAuthzDecision(r, auth, required, matched, "", false)
+		resp.ErrorWithCode(w, apperror.CodeInsufficientPermissions, "Insufficient permissions")
+		return false
+	}
+
+	// A deny statement of the tenant's policies overrides the permission
+	if enforcer := policyEnforcerFromContext(r.Context()); enforcer != nil && auth.Tenant != nil {
+		deny, err := enforcer.FindDeny(r.Context(), auth.Tenant.TenantID, matched, policyInput(r, auth))
+		if err != nil {
+			slog.ErrorContext(r.Context(), "policy evaluation failed", "error", err)
+			resp.Error(w, http.StatusInternalServerError, "Failed to evaluate policies")
+			return false
+		}
+		if deny != nil {
+			if deny.Err != nil {
+				slog.WarnContext(r.Context(), "policy condition failed, statement applied",
+					"policy_id", deny.PolicyUUID.String(), "statement_index", deny.StatementIndex, "error", deny.Err)
+			}
+			auditAuthzDecision(r, auth, required, matched, AuthzReasonPolicyDenied, false)
+			resp.ErrorWithCode(w, apperror.CodeInsufficientPermissions, "Denied by policy")
+			return false
+		}
+	}
+
+	auditAuthzDecision(r, auth, required, matched, "", true)
+	return true
+}
+
+// permissionGuard is the handler PermissionMiddleware puts in front of a
+// route. Its type lets RoutePermissions find the permissions a route declares.
+type permissionGuard struct {
+	required []string
+	next     http.Handler
+}
+
+func (g *permissionGuard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if Authorize(w, r, g.required...) {
+		g.next.ServeHTTP(w, r)
+	}
+}
+
+// PermissionMiddleware ensures the caller has at least one of the required
+// permissions among their effective permissions (see EffectivePermissions)
+// and that the tenant's policies do not deny it (see Authorize).
+// The requirement is recorded on the route and reported by RoutePermissions.
+func PermissionMiddleware(requiredPermissions []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return &permissionGuard{required: requiredPermissions, next: next}
+	}
+}
+
+// RoutePermission is the permission requirement a route declares through
+// PermissionMiddleware. Any one of Permissions grants access.
+type RoutePermission struct {
+	Method      string
+	Pattern     string
+	Permissions []string
+}
+
+// RoutePermissions lists the routes of routes that are guarded by
+// PermissionMiddleware, sorted by pattern and method. Routes without a
+// permission check are left out.
+func RoutePermissions(routes chi.Routes) ([]RoutePermission, error) {
+	var result []RoutePermission
+	probe := http.NotFoundHandler()
+	err := chi.Walk(routes, func(method, pattern string, _ http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		// Building a middleware has no side effects, so each one is applied
+		// to a placeholder to see whether it is a permission guard.
+		for _, mw := range middlewares {
+			if g, ok := mw(probe).(*permissionGuard); ok {
+				result = append(result, RoutePermission{Method: method, Pattern: pattern, Permissions: g.required})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Pattern != result[j].Pattern {
+			return result[i].Pattern < result[j].Pattern
+		}
+		return result[i].Method < result[j].Method
+	})
+	return result, nil
+}
+
+// hasAnyPermission checks if the user has at least one of the required permissions
+func hasAnyPermission(user *model.User, required []string) bool {
+	_, ok := matchPermission(user, required)
+	return ok
+}
+
+// matchPermission returns the first required permission held by the user
+func matchPermission(user *model.User, required []string) (string, bool) {
+	return userPermissions(user).Match(required)
+}
+
+// auditAuthzDecision hands the permission check to the request's auditor, if
+// any. Requests without a resolved tenant cannot be attributed and are skipped.
+// An empty reason is derived from allowed.
+func auditAuthzDecision(r *http.Request, auth *AuthContext, required []string, matched, reason string, allowed bool) {
+	recordAuthzDecision(r.Context(), authzAuditorFromContext(r.Context()), auth, AuthzDecision{
+		IPAddress:           extractClientIP(r),
+		UserAgent:           r.UserAgent(),
+		Transport:           AuthzTransportREST,
+		Resource:            r.Method + " " + r.URL.Path,
+		RequiredPermissions: required,
+		MatchedPermission:   matched,
+		Reason:              reason,
+	}, allowed)
+}
+
+// recordAuthzDecision completes decision with the caller's tenant, identity
+// and roles and records it. Decisions without an auditor, a resolved tenant
+// or a user are skipped.
+func recordAuthzDecision(ctx context.Context, auditor AuthzAuditor, auth *AuthContext, decision AuthzDecision, allowed bool) {
+	if auditor == nil || auth.Tenant == nil || auth.User == nil {
+		return
+	}
+
+	roles := make([]string, len(auth.User.Roles))
+	for i, role := range auth.User.Roles {
+		roles[i] = role.Name
+	}
+
+	if decision.Reason == "" {
+		decision.Reason = AuthzReasonNoMatchingPermission
+		if allowed {
+			decision.Reason = AuthzReasonPermissionGranted
 		}
 	}
+	decision.TenantID = auth.Tenant.TenantID
+	decision.ActorUserID = auth.User.UserID
+	decision.Allowed = allowed
+	decision.EvaluatedRoles = roles
 
-	return false
+	auditor.Record(ctx, decision)
 }
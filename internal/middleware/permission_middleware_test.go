@@ -1,12 +1,15 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // userWithPermissions returns a minimal User fixture with the given permission names.
@@ -106,3 +109,190 @@ func TestHasAnyPermission(t *testing.T) {
 		assert.True(t, hasAnyPermission(user, []string{"admin"}))
 	})
 }
+
+// recordingAuditor captures every decision handed to it.
+type recordingAuditor struct {
+	decisions []AuthzDecision
+}
+
+func (a *recordingAuditor) Record(_ context.Context, d AuthzDecision) {
+	a.decisions = append(a.decisions, d)
+}
+
+func TestPermissionMiddleware_AuthzAudit(t *testing.T) {
+	tenant := &model.Tenant{TenantID: 7}
+	user := &model.User{
+		UserID: 42,
+		Roles: []model.Role{
+			{Name: "viewer", Permissions: []model.Permission{{Name: "user:read"}}},
+			{Name: "support"},
+		},
+	}
+
+	serve := func(auditor AuthzAuditor, auth *AuthContext, required []string) int {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/users/abc", nil)
+		req.Header.Set("User-Agent", "test-agent")
+		req = WithAuthContext(req, auth)
+		rr := httptest.NewRecorder()
+		h := PermissionMiddleware(required)(okHandler())
+		if auditor != nil {
+			h = AuthzAuditMiddleware(auditor)(h)
+		}
+		h.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	t.Run("deny records inputs", func(t *testing.T) {
+		a := &recordingAuditor{}
+		code := serve(a, &AuthContext{User: user, Tenant: tenant}, []string{"user:delete", "user:admin"})
+		assert.Equal(t, http.StatusForbidden, code)
+		require.Len(t, a.decisions, 1)
+		d := a.decisions[0]
+		assert.False(t, d.Allowed)
+		assert.Equal(t, AuthzReasonNoMatchingPermission, d.Reason)
+		assert.Equal(t, int64(7), d.TenantID)
+		assert.Equal(t, int64(42), d.ActorUserID)
+		assert.Equal(t, AuthzTransportREST, d.Transport)
+		assert.Equal(t, "DELETE /api/v1/users/abc", d.Resource)
+		assert.Equal(t, "test-agent", d.UserAgent)
+		assert.Equal(t, []string{"user:delete", "user:admin"}, d.RequiredPermissions)
+		assert.Empty(t, d.MatchedPermission)
+		assert.Equal(t, []string{"viewer", "support"}, d.EvaluatedRoles)
+	})
+
+	t.Run("allow records matched permission", func(t *testing.T) {
+		a := &recordingAuditor{}
+		code := serve(a, &AuthContext{User: user, Tenant: tenant}, []string{"user:admin", "user:read"})
+		assert.Equal(t, http.StatusOK, code)
+		require.Len(t, a.decisions, 1)
+		assert.True(t, a.decisions[0].Allowed)
+		assert.Equal(t, AuthzReasonPermissionGranted, a.decisions[0].Reason)
+		assert.Equal(t, "user:read", a.decisions[0].MatchedPermission)
+	})
+
+	t.Run("no tenant → not recorded", func(t *testing.T) {
+		a := &recordingAuditor{}
+		code := serve(a, &AuthContext{User: user}, []string{"user:read"})
+		assert.Equal(t, http.StatusOK, code)
+		assert.Empty(t, a.decisions)
+	})
+
+	t.Run("no user → not recorded", func(t *testing.T) {
+		a := &recordingAuditor{}
+		code := serve(a, &AuthContext{Tenant: tenant}, []string{"user:read"})
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.Empty(t, a.decisions)
+	})
+
+	t.Run("no auditor configured", func(t *testing.T) {
+		code := serve(nil, &AuthContext{User: user, Tenant: tenant}, []string{"user:read"})
+		assert.Equal(t, http.StatusOK, code)
+	})
+}
+
+func TestMatchPermission(t *testing.T) {
+	matched, ok := matchPermission(userWithPermissions("b", "c"), []string{"a", "c", "b"})
+	assert.True(t, ok)
+	assert.Equal(t, "c", matched)
+
+	matched, ok = matchPermission(userWithPermissions("b"), []string{"a"})
+	assert.False(t, ok)
+	assert.Empty(t, matched)
+}
+
+func TestEffectivePermissions(t *testing.T) {
+	client := &model.Client{ClientAPIs: &[]model.ClientAPI{{
+		Permissions: []model.ClientPermission{{Permission: &model.Permission{Name: "user:read"}}, {Permission: &model.Permission{Name: "client:perm"}}, {}},
+	}}}
+	auth := &AuthContext{User: userWithPermissions("user:read", "user:update"), Client: client}
+	apiKey := &APIKeyPrincipal{Permissions: []string{"orders:read"}}
+
+	t.Run("client caps the user, API key adds", func(t *testing.T) {
+		set := effectivePermissions(context.Background(), auth, apiKey, nil)
+		assert.Equal(t, []string{"orders:read", "user:read"}, set.Names())
+	})
+
+	t.Run("client without permissions does not cap", func(t *testing.T) {
+		for _, c := range []*model.Client{{}, {ClientAPIs: &[]model.ClientAPI{{}}}} {
+			set := effectivePermissions(context.Background(), &AuthContext{User: auth.User, Client: c}, nil, nil)
+			assert.Equal(t, []string{"user:read", "user:update"}, set.Names())
+		}
+	})
+
+	t.Run("personal access token narrows to scopes", func(t *testing.T) {
+		set := effectivePermissions(context.Background(), &AuthContext{User: auth.User}, nil, &PersonalAccessTokenPrincipal{Scopes: []string{"user:read", "user:delete"}})
+		assert.Equal(t, []string{"user:read"}, set.Names())
+	})
+
+	t.Run("from request context", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = WithAuthContext(req, &AuthContext{User: userWithPermissions("user:read")})
+		req = req.WithContext(ContextWithAPIKey(req.Context(), apiKey))
+		assert.True(t, HasPermission(req, "user:read"))
+		assert.True(t, HasPermission(req, "orders:read"))
+		assert.False(t, HasPermission(req, "user:update"))
+	})
+
+	t.Run("no principal", func(t *testing.T) {
+		assert.Empty(t, EffectivePermissions(httptest.NewRequest(http.MethodGet, "/", nil)))
+	})
+}
+
+func TestPermissionMiddleware_APIKeyOnly(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(ContextWithAPIKey(req.Context(), &APIKeyPrincipal{Permissions: []string{"orders:read"}}))
+
+	rr := httptest.NewRecorder()
+	PermissionMiddleware([]string{"orders:read"})(okHandler()).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	PermissionMiddleware([]string{"orders:write"})(okHandler()).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestAuthorize(t *testing.T) {
+	t.Run("allowed writes nothing", func(t *testing.T) {
+		req := WithAuthContext(httptest.NewRequest(http.MethodGet, "/", nil), &AuthContext{User: userWithPermissions("user:update")})
+		rr := httptest.NewRecorder()
+		assert.True(t, Authorize(rr, req, "user:delete", "user:update"))
+		assert.Zero(t, rr.Body.Len())
+	})
+
+	t.Run("denied writes 403", func(t *testing.T) {
+		req := WithAuthContext(httptest.NewRequest(http.MethodGet, "/", nil), &AuthContext{User: userWithPermissions("user:read")})
+		rr := httptest.NewRecorder()
+		assert.False(t, Authorize(rr, req, "user:update"))
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		assert.Contains(t, rr.Body.String(), "AUTH-1005")
+	})
+
+	t.Run("no principal writes 401", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		assert.False(t, Authorize(rr, httptest.NewRequest(http.MethodGet, "/", nil), "user:read"))
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
+func TestRoutePermissions(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(SecurityHeadersMiddleware)
+	r.Get("/health", okHandler().ServeHTTP)
+	r.Route("/users", func(r chi.Router) {
+		r.Use(JWTAuthMiddleware)
+		r.With(PermissionMiddleware([]string{"user:read"})).
+			Get("/", okHandler().ServeHTTP)
+		r.With(DenyPersonalAccessTokenMiddleware, PermissionMiddleware([]string{"user:create", "user:admin"})).
+			Post("/", okHandler().ServeHTTP)
+		r.With(PermissionMiddleware([]string{"user:delete"})).
+			Delete("/{user_uuid}", okHandler().ServeHTTP)
+	})
+
+	routes, err := RoutePermissions(r)
+	require.NoError(t, err)
+	assert.Equal(t, []RoutePermission{
+		{Method: http.MethodGet, Pattern: "/users/", Permissions: []string{"user:read"}},
+		{Method: http.MethodPost, Pattern: "/users/", Permissions: []string{"user:create", "user:admin"}},
+		{Method: http.MethodDelete, Pattern: "/users/{user_uuid}", Permissions: []string{"user:delete"}},
+	}, routes)
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+)
+
+// UserPermissionResolver is the minimal interface required to look up the
+// permissions of a user's roles from a cache instead of the loaded roles.
+type UserPermissionResolver interface {
+	UserPermissions(ctx context.Context, user *model.User) ([]string, error)
+}
+
+// ResourcePermissionResolver is a UserPermissionResolver that also knows the
+// permissions a user holds through role assignments scoped to a single client
+// or group. PermissionMiddleware and Authorize add them when the route names
+// that resource, or a member of that group.
+type ResourcePermissionResolver interface {
+	UserPermissionResolver
+	ResourcePermissions(ctx context.Context, user *model.User, resourceType string, resourceUUID uuid.UUID) ([]string, error)
+	MemberPermissions(ctx context.Context, user *model.User, memberUUID uuid.UUID) ([]string, error)
+}
+
+// permissionResolverKey is the unexported context key type for the
+// UserPermissionResolver.
+type permissionResolverKey struct{}
+
+// PermissionResolverMiddleware makes resolver available to
+// PermissionMiddleware and Authorize for every request on the router. Routes
+// without it use the roles loaded with the user.
+func PermissionResolverMiddleware(resolver UserPermissionResolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), permissionResolverKey{}, resolver)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// permissionResolverFromContext returns the resolver stored by
+// PermissionResolverMiddleware, or nil when none is configured.
+func permissionResolverFromContext(ctx context.Context) UserPermissionResolver {
+	if r, ok := ctx.Value(permissionResolverKey{}).(UserPermissionResolver); ok {
+		return r
+	}
+	return nil
+}
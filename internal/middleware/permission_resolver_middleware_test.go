@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubPermissionResolver struct {
+	names []string
+	err   error
+	calls int
+}
+
+func (s *stubPermissionResolver) UserPermissions(context.Context, *model.User) ([]string, error) {
+	s.calls++
+	return s.names, s.err
+}
+
+// servePermission runs a request for user through PermissionResolverMiddleware
+// and PermissionMiddleware and returns the status code.
+func servePermission(resolver UserPermissionResolver, user *model.User, required ...string) int {
+	h := PermissionResolverMiddleware(resolver)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = WithAuthContext(r, &AuthContext{User: user})
+		PermissionMiddleware(required)(okHandler()).ServeHTTP(w, r)
+	}))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rr.Code
+}
+
+func TestPermissionResolverMiddleware(t *testing.T) {
+	user := userWithPermissions("user:read")
+
+	t.Run("resolved permissions replace the loaded roles", func(t *testing.T) {
+		resolver := &stubPermissionResolver{names: []string{"role:read"}}
+		assert.Equal(t, http.StatusOK, servePermission(resolver, user, "role:read"))
+		assert.Equal(t, http.StatusForbidden, servePermission(resolver, user, "user:read"))
+		assert.Equal(t, 2, resolver.calls)
+	})
+
+	t.Run("resolver failure falls back to the loaded roles", func(t *testing.T) {
+		resolver := &stubPermissionResolver{err: errors.New("redis down")}
+		assert.Equal(t, http.StatusOK, servePermission(resolver, user, "user:read"))
+	})
+
+	t.Run("not consulted without a user", func(t *testing.T) {
+		resolver := &stubPermissionResolver{names: []string{"role:read"}}
+		assert.Equal(t, http.StatusUnauthorized, servePermission(resolver, nil, "role:read"))
+		assert.Zero(t, resolver.calls)
+	})
+}
+
+type stubResourcePermissionResolver struct {
+	stubPermissionResolver
+	resources map[string][]string
+	members   map[uuid.UUID][]string
+}
+
+func (s *stubResourcePermissionResolver) ResourcePermissions(_ context.Context, _ *model.User, resourceType string, resourceUUID uuid.UUID) ([]string, error) {
+	return s.resources[resourceType+":"+resourceUUID.String()], nil
+}
+
+func (s *stubResourcePermissionResolver) MemberPermissions(_ context.Context, _ *model.User, memberUUID uuid.UUID) ([]string, error) {
+	return s.members[memberUUID], nil
+}
+
+// serveScopedPermission runs a GET request for path through a router whose
+// routes name a client, a group or a user and returns the status code.
+func serveScopedPermission(resolver UserPermissionResolver, user *model.User, path string, required ...string) int {
+	r := chi.NewRouter()
+	r.Use(PermissionResolverMiddleware(resolver))
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, WithAuthContext(r, &AuthContext{User: user}))
+		})
+	})
+	guard := PermissionMiddleware(required)
+	r.With(guard).Get("/clients/{client_uuid}", okHandler().ServeHTTP)
+	r.With(guard).Get("/groups/{group_uuid}", okHandler().ServeHTTP)
+	r.With(guard).Get("/users/{user_uuid}", okHandler().ServeHTTP)
+	r.With(guard).Get("/users", okHandler().ServeHTTP)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+	return rr.Code
+}
+
+func TestPermissionMiddleware_ScopedRoles(t *testing.T) {
+	user := &model.User{UserUUID: uuid.New()}
+	client, otherClient := uuid.New(), uuid.New()
+	group, member, outsider := uuid.New(), uuid.New(), uuid.New()
+	resolver := &stubResourcePermissionResolver{
+		resources: map[string][]string{
+			model.ScopedRoleResourceClient + ":" + client.String(): {"client:update"},
+			model.ScopedRoleResourceGroup + ":" + group.String():   {"group:read"},
+		},
+		members: map[uuid.UUID][]string{member: {"user:update"}},
+	}
+
+	t.Run("client scope applies to that client only", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serveScopedPermission(resolver, user, "/clients/"+client.String(), "client:update"))
+		assert.Equal(t, http.StatusForbidden, serveScopedPermission(resolver, user, "/clients/"+otherClient.String(), "client:update"))
+	})
+
+	t.Run("resource type must match", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serveScopedPermission(resolver, user, "/groups/"+client.String(), "client:update"))
+		assert.Equal(t, http.StatusOK, serveScopedPermission(resolver, user, "/groups/"+group.String(), "group:read"))
+	})
+
+	t.Run("group scope applies to its members", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serveScopedPermission(resolver, user, "/users/"+member.String(), "user:update"))
+		assert.Equal(t, http.StatusForbidden, serveScopedPermission(resolver, user, "/users/"+outsider.String(), "user:update"))
+	})
+
+	t.Run("routes without a resource are not granted", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serveScopedPermission(resolver, user, "/users", "user:update"))
+	})
+
+	t.Run("plain resolvers grant no scoped permissions", func(t *testing.T) {
+		plain := &stubPermissionResolver{}
+		assert.Equal(t, http.StatusForbidden, serveScopedPermission(plain, user, "/clients/"+client.String(), "client:update"))
+	})
+}
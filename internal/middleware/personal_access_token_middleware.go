@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+)
+
+// PersonalAccessTokenValidator is the minimal interface required by
+// PersonalAccessTokenMiddleware to resolve a plain-text personal access
+// token. It returns an *apperror.UnauthorizedError when the token is unknown,
+// revoked or expired, or its owner is no longer active.
+type PersonalAccessTokenValidator interface {
+	ValidatePersonalAccessToken(ctx context.Context, token string) (*model.PersonalAccessToken, error)
+}
+
+// patPrincipalKey is the unexported context key type for
+// PersonalAccessTokenPrincipal.
+type patPrincipalKey struct{}
+
+// PersonalAccessTokenPrincipal is the personal access token a request was
+// authenticated with. It is set by PersonalAccessTokenMiddleware and
+// retrieved via PersonalAccessTokenFromRequest.
+type PersonalAccessTokenPrincipal struct {
+	TokenUUID uuid.UUID
+	Scopes    []string
+}
+
+// Allows reports whether the token was scoped to the named permission.
+func (p *PersonalAccessTokenPrincipal) Allows(permission string) bool {
+	for _, scope := range p.Scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// PersonalAccessTokenFromRequest returns the PersonalAccessTokenPrincipal
+// stored in the request context, or nil when the request was not made with a
+// personal access token.
+func PersonalAccessTokenFromRequest(r *http.Request) *PersonalAccessTokenPrincipal {
+	p, _ := r.Context().Value(patPrincipalKey{}).(*PersonalAccessTokenPrincipal)
+	return p
+}
+
+// WithPersonalAccessToken returns a shallow copy of r with p stored in its
+// context. It is intended for use in tests.
+func WithPersonalAccessToken(r *http.Request, p *PersonalAccessTokenPrincipal) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), patPrincipalKey{}, p))
+}
+
+// PersonalAccessTokenMiddleware authenticates requests whose Bearer token is
+// a personal access token. It stores the token owner's JWTClaims and
+// AuthContext, so JWTAuthMiddleware and UserContextMiddleware pass the request
+// through, and a PersonalAccessTokenPrincipal that PermissionMiddleware uses
+// to limit the owner's permissions to the token's scopes, and counts the
+// request against the request quota of the token's tenant. Requests with any
+// other credentials pass through untouched.
+func PersonalAccessTokenMiddleware(validator PersonalAccessTokenValidator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerPersonalAccessToken(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			pat, err := validator.ValidatePersonalAccessToken(ctx, token)
+			if err != nil {
+				var unauthorized *apperror.UnauthorizedError
+				if errors.As(err, &unauthorized) {
+					resp.ErrorWithCode(w, apperror.CodeInvalidToken, "Invalid or expired token")
+					return
+				}
+				slog.ErrorContext(ctx, "personal access token: validation failed", "error", err)
+				resp.Error(w, http.StatusInternalServerError, "Failed to validate personal access token")
+				return
+			}
+
+			userUUID := pat.User.UserUUID
+			ctx = context.WithValue(ctx, jwtKey{}, &JWTClaims{
+				Sub:      userUUID.String(),
+				UserUUID: userUUID,
+			})
+			ctx = context.WithValue(ctx, authKey{}, &AuthContext{
+				User:   pat.User,
+				Tenant: pat.Tenant,
+			})
+			ctx = context.WithValue(ctx, patPrincipalKey{}, &PersonalAccessTokenPrincipal{
+				TokenUUID: pat.PersonalAccessTokenUUID,
+				Scopes:    pat.Scopes,
+			})
+			traceTenant(ctx, pat.Tenant, "")
+
+			r = r.WithContext(ctx)
+			if pat.Tenant != nil && !meterRequest(w, r, pat.Tenant.TenantID, 0) {
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// DenyPersonalAccessTokenMiddleware rejects requests authenticated by a
+// personal access token. It guards authenticated routes that have no
+// PermissionMiddleware, which would otherwise ignore the token's scopes.
+func DenyPersonalAccessTokenMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if PersonalAccessTokenFromRequest(r) != nil {
+			resp.ErrorWithCode(w, apperror.CodeInsufficientPermissions, "Personal access tokens cannot be used for this endpoint")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bearerPersonalAccessToken returns the Bearer token of r when it is a
+// personal access token.
+func bearerPersonalAccessToken(r *http.Request) (string, bool) {
+	parts := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		return "", false
+	}
+	if !strings.HasPrefix(parts[1], model.PersonalAccessTokenPrefix) {
+		return "", false
+	}
+	return parts[1], true
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubPATValidator struct {
+	pat   *model.PersonalAccessToken
+	err   error
+	token string
+}
+
+func (s *stubPATValidator) ValidatePersonalAccessToken(_ context.Context, token string) (*model.PersonalAccessToken, error) {
+	s.token = token
+	return s.pat, s.err
+}
+
+func testPAT(scopes ...string) *model.PersonalAccessToken {
+	user := userWithPermissions("user:read", "user:delete")
+	user.UserUUID = uuid.New()
+	return &model.PersonalAccessToken{
+		PersonalAccessTokenUUID: uuid.New(),
+		User:                    user,
+		Tenant:                  &model.Tenant{TenantID: 3},
+		Scopes:                  scopes,
+	}
+}
+
+// servePAT runs a request through the middleware chain a PAT-enabled route
+// uses: PAT authentication, JWT authentication, user context and a
+// permission check.
+func servePAT(t *testing.T, v PersonalAccessTokenValidator, authorization string, required ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	provider := &mockContextProvider{findFn: func(string, string) (*model.User, error) {
+		t.Fatal("user must not be loaded for a personal access token")
+		return nil, nil
+	}}
+	chain := PersonalAccessTokenMiddleware(v)(
+		JWTAuthMiddleware(
+			UserContextMiddleware(provider, newFakeCache())(
+				PermissionMiddleware(required)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				})))))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", authorization)
+	rr := httptest.NewRecorder()
+	chain.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestPersonalAccessTokenMiddleware_OtherCredentialsPassThrough(t *testing.T) {
+	v := &stubPATValidator{err: errors.New("must not be called")}
+	var called bool
+	h := PersonalAccessTokenMiddleware(v)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		assert.Nil(t, PersonalAccessTokenFromRequest(r))
+		assert.Nil(t, JWTClaimsFromRequest(r))
+	}))
+
+	for _, header := range []string{"", "Bearer eyJhbGciOi", "Basic pat_abc"} {
+		called = false
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", header)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		assert.True(t, called, header)
+	}
+	assert.Empty(t, v.token)
+}
+
+func TestPersonalAccessTokenMiddleware_InvalidToken(t *testing.T) {
+	rr := servePAT(t, &stubPATValidator{err: apperror.NewUnauthorized("revoked")}, "Bearer pat_bad", "user:read")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), string(apperror.CodeInvalidToken))
+
+	rr = servePAT(t, &stubPATValidator{err: errors.New("db down")}, "Bearer pat_bad", "user:read")
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
+
+func TestPersonalAccessTokenMiddleware_ScopesLimitPermissions(t *testing.T) {
+	v := &stubPATValidator{pat: testPAT("user:read")}
+
+	rr := servePAT(t, v, "Bearer pat_good", "user:read")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "pat_good", v.token)
+
+	// Held by the owner but not granted to the token
+	rr = servePAT(t, v, "Bearer pat_good", "user:delete")
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = servePAT(t, v, "Bearer pat_good", "user:delete", "user:read")
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestPersonalAccessTokenMiddleware_Context(t *testing.T) {
+	pat := testPAT("user:read")
+	h := PersonalAccessTokenMiddleware(&stubPATValidator{pat: pat})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal := PersonalAccessTokenFromRequest(r)
+		require.NotNil(t, principal)
+		assert.Equal(t, pat.PersonalAccessTokenUUID, principal.TokenUUID)
+		assert.True(t, principal.Allows("user:read"))
+		assert.False(t, principal.Allows("user:delete"))
+
+		claims := JWTClaimsFromRequest(r)
+		require.NotNil(t, claims)
+		assert.Equal(t, pat.User.UserUUID, claims.UserUUID)
+		assert.Equal(t, pat.User.UserUUID.String(), claims.Sub)
+
+		auth := AuthFromRequest(r)
+		assert.Same(t, pat.User, auth.User)
+		assert.Same(t, pat.Tenant, auth.Tenant)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "bearer pat_good")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestDenyPersonalAccessTokenMiddleware(t *testing.T) {
+	h := DenyPersonalAccessTokenMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	req := WithPersonalAccessToken(httptest.NewRequest(http.MethodPost, "/", nil), &PersonalAccessTokenPrincipal{})
+	h.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/abac"
+)
+
+// Authentication methods reported to policy conditions as
+// subject.auth_method.
+const (
+	PolicyAuthMethodJWT                 = "jwt"
+	PolicyAuthMethodPersonalAccessToken = "personal_access_token"
+	PolicyAuthMethodAPIKey              = "api_key"
+)
+
+// PolicyEnforcer is the minimal interface required to apply a tenant's
+// attribute-based policies to a request its permissions allow.
+type PolicyEnforcer interface {
+	// FindDeny returns the deny statement that applies to action, or nil.
+	FindDeny(ctx context.Context, tenantID int64, action string, in abac.Input) (*abac.Match, error)
+}
+
+// policyEnforcerKey is the unexported context key type for the
+// PolicyEnforcer.
+type policyEnforcerKey struct{}
+
+// PolicyMiddleware makes enforcer available to PermissionMiddleware and
+// Authorize for every request on the router. Routes without it are decided on
+// permissions alone.
+func PolicyMiddleware(enforcer PolicyEnforcer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), policyEnforcerKey{}, enforcer)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// policyEnforcerFromContext returns the enforcer stored by PolicyMiddleware,
+// or nil when none is configured.
+func policyEnforcerFromContext(ctx context.Context) PolicyEnforcer {
+	if e, ok := ctx.Value(policyEnforcerKey{}).(PolicyEnforcer); ok {
+		return e
+	}
+	return nil
+}
+
+// policyInput collects the attributes of r that policy conditions can use.
+func policyInput(r *http.Request, auth *AuthContext) abac.Input {
+	var in abac.Input
+
+	in.Subject.AuthMethod = PolicyAuthMethodAPIKey
+	if auth.User != nil {
+		in.Subject.ID = auth.User.UserUUID.String()
+		in.Subject.EmailVerified = auth.User.IsEmailVerified
+		in.Subject.Roles = make([]string, len(auth.User.Roles))
+		for i, role := range auth.User.Roles {
+			in.Subject.Roles[i] = role.Name
+		}
+		in.Subject.AuthMethod = PolicyAuthMethodJWT
+		if PersonalAccessTokenFromRequest(r) != nil {
+			in.Subject.AuthMethod = PolicyAuthMethodPersonalAccessToken
+		}
+	}
+	if auth.Tenant != nil {
+		in.Subject.TenantID = auth.Tenant.TenantUUID.String()
+	}
+
+	in.Resource.Params = map[string]string{}
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		in.Resource.Route = rctx.RoutePattern()
+		for i, key := range rctx.URLParams.Keys {
+			in.Resource.Params[key] = rctx.URLParams.Values[i]
+		}
+	}
+
+	in.Request = abac.Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		IP:     extractClientIP(r),
+		Time:   time.Now().UTC(),
+	}
+	return in
+}
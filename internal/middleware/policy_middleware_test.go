@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/abac"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubPolicyEnforcer struct {
+	match *abac.Match
+	err   error
+
+	calls    int
+	tenantID int64
+	action   string
+	input    abac.Input
+}
+
+func (s *stubPolicyEnforcer) FindDeny(_ context.Context, tenantID int64, action string, in abac.Input) (*abac.Match, error) {
+	s.calls++
+	s.tenantID, s.action, s.input = tenantID, action, in
+	return s.match, s.err
+}
+
+// servePolicy routes a DELETE /users/{user_uuid} request guarded by
+// PermissionMiddleware through PolicyMiddleware and an auditor.
+func servePolicy(enforcer PolicyEnforcer, auditor AuthzAuditor, auth *AuthContext, pat *PersonalAccessTokenPrincipal) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	r.Use(PolicyMiddleware(enforcer), AuthzAuditMiddleware(auditor))
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			req = WithAuthContext(req, auth)
+			if pat != nil {
+				req = WithPersonalAccessToken(req, pat)
+			}
+			next.ServeHTTP(w, req)
+		})
+	})
+	r.With(PermissionMiddleware([]string{"user:admin", "user:delete"})).Delete("/users/{user_uuid}", okHandler().ServeHTTP)
+
+	req := httptest.NewRequest(http.MethodDelete, "/users/abc", nil)
+	req.RemoteAddr = "10.0.0.7:5000"
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestPolicyMiddleware(t *testing.T) {
+	tenant := &model.Tenant{TenantID: 7, TenantUUID: uuid.New()}
+	user := userWithPermissions("user:delete")
+	user.UserID = 42
+	user.UserUUID = uuid.New()
+	user.IsEmailVerified = true
+	user.Roles[0].Name = "editor"
+	auth := &AuthContext{User: user, Tenant: tenant}
+
+	t.Run("not denied", func(t *testing.T) {
+		e := &stubPolicyEnforcer{}
+		a := &recordingAuditor{}
+		rr := servePolicy(e, a, auth, nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		assert.Equal(t, int64(7), e.tenantID)
+		assert.Equal(t, "user:delete", e.action, "policies see the matched permission")
+		assert.Equal(t, user.UserUUID.String(), e.input.Subject.ID)
+		assert.Equal(t, tenant.TenantUUID.String(), e.input.Subject.TenantID)
+		assert.Equal(t, []string{"editor"}, e.input.Subject.Roles)
+		assert.True(t, e.input.Subject.EmailVerified)
+		assert.Equal(t, PolicyAuthMethodJWT, e.input.Subject.AuthMethod)
+		assert.Equal(t, "/users/{user_uuid}", e.input.Resource.Route)
+		assert.Equal(t, map[string]string{"user_uuid": "abc"}, e.input.Resource.Params)
+		assert.Equal(t, http.MethodDelete, e.input.Request.Method)
+		assert.Equal(t, "/users/abc", e.input.Request.Path)
+		assert.Equal(t, "10.0.0.7", e.input.Request.IP)
+		assert.False(t, e.input.Request.Time.IsZero())
+
+		require.Len(t, a.decisions, 1)
+		assert.Equal(t, AuthzReasonPermissionGranted, a.decisions[0].Reason)
+	})
+
+	t.Run("denied by policy", func(t *testing.T) {
+		e := &stubPolicyEnforcer{match: &abac.Match{PolicyUUID: uuid.New()}}
+		a := &recordingAuditor{}
+		rr := servePolicy(e, a, auth, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		assert.Contains(t, rr.Body.String(), "Denied by policy")
+
+		require.Len(t, a.decisions, 1)
+		assert.False(t, a.decisions[0].Allowed)
+		assert.Equal(t, AuthzReasonPolicyDenied, a.decisions[0].Reason)
+		assert.Equal(t, "user:delete", a.decisions[0].MatchedPermission)
+	})
+
+	t.Run("condition error still denies", func(t *testing.T) {
+		e := &stubPolicyEnforcer{match: &abac.Match{Err: errors.New("no such key")}}
+		rr := servePolicy(e, &recordingAuditor{}, auth, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("evaluation failure", func(t *testing.T) {
+		e := &stubPolicyEnforcer{err: errors.New("db down")}
+		rr := servePolicy(e, &recordingAuditor{}, auth, nil)
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+
+	t.Run("personal access token", func(t *testing.T) {
+		e := &stubPolicyEnforcer{}
+		rr := servePolicy(e, &recordingAuditor{}, auth, &PersonalAccessTokenPrincipal{Scopes: []string{"user:delete"}})
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, PolicyAuthMethodPersonalAccessToken, e.input.Subject.AuthMethod)
+	})
+
+	t.Run("not consulted without permission or tenant", func(t *testing.T) {
+		e := &stubPolicyEnforcer{}
+		rr := servePolicy(e, &recordingAuditor{}, &AuthContext{User: userWithPermissions("user:read"), Tenant: tenant}, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+
+		rr = servePolicy(e, &recordingAuditor{}, &AuthContext{User: user}, nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Zero(t, e.calls)
+	})
+}
@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/metrics"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+)
+
+// Route groups requests are rate limited by. A policy named
+// "default.<principal>" applies to groups without their own policy for the
+// principal type.
+const (
+	RateLimitGroupAuth       = "auth"
+	RateLimitGroupAccount    = "account"
+	RateLimitGroupOAuth      = "oauth"
+	RateLimitGroupManagement = "management"
+	RateLimitGroupDefault    = "default"
+)
+
+// RateLimiter is the subset of Cache that RateLimitMiddleware counts requests
+// with.
+type RateLimiter interface {
+	AllowRequest(ctx context.Context, key string, limit cache.RateLimit, now time.Time) (cache.RateLimitResult, error)
+}
+
+// Compile-time check that *cache.Cache satisfies RateLimiter.
+var _ RateLimiter = (*cache.Cache)(nil)
+
+// RateLimitMiddleware limits the requests of each principal to the route
+// group with a token bucket per principal. The principal is the API key of
+// an X-API-Key header, the user or client of a personal access token,
+// browser session or valid JWT, and otherwise the client IP. policies
+// returns the policies in effect, keyed by "<group>.<principal type>", and is
+// called on every request so that reloaded policies apply at once;
+// principals without a policy are not limited.
+//
+// Every limited response carries X-RateLimit-Limit, X-RateLimit-Remaining
+// and X-RateLimit-Reset (seconds until the bucket is full), and rejected
+// requests get 429 with Retry-After. Requests pass when the limiter fails so
+// that an unavailable Redis does not take down the API.
+func RateLimitMiddleware(limiter RateLimiter, group string, policies func() map[string]cache.RateLimit) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current := policies()
+			if len(current) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, id := rateLimitPrincipal(r)
+			limit, ok := current[group+"."+principal]
+			if !ok {
+				limit, ok = current[RateLimitGroupDefault+"."+principal]
+			}
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			result, err := limiter.AllowRequest(ctx, group+":"+principal+":"+id, limit, time.Now())
+			if err != nil {
+				slog.WarnContext(ctx, "rate limit: check failed", "group", group, "principal", principal, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+			if !result.Allowed {
+				metrics.ObserveRateLimitHit("http_" + group)
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+				resp.ErrorWithCode(w, apperror.CodeTooManyRequests, "Rate limit exceeded")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitPrincipal returns the principal type and identifier requests of r
+// are counted against. API keys are identified by their hash, so that the
+// key never reaches Redis.
+func rateLimitPrincipal(r *http.Request) (string, string) {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		sum := sha256.Sum256([]byte(key))
+		return cache.RateLimitPrincipalAPIKey, hex.EncodeToString(sum[:])
+	}
+
+	if pat := PersonalAccessTokenFromRequest(r); pat != nil {
+		return cache.RateLimitPrincipalUser, "pat:" + pat.TokenUUID.String()
+	}
+
+	if bs := BrowserSessionFromRequest(r); bs != nil {
+		return cache.RateLimitPrincipalUser, bs.UserUUID.String()
+	}
+
+	// Only signed tokens are trusted, so a forged subject cannot spend
+	// another principal's budget.
+	if token := requestToken(r); token != "" {
+		if claims, err := jwt.ValidateToken(token); err == nil {
+			sub, _ := claims["sub"].(string)
+			// Client credentials tokens are issued to the client itself
+			if clientID, _ := claims["client_id"].(string); clientID != "" && clientID == sub {
+				return cache.RateLimitPrincipalClient, clientID
+			}
+			return cache.RateLimitPrincipalUser, sub
+		}
+	}
+
+	ip := ClientIPFromContext(r.Context())
+	if ip == "" {
+		ip = extractClientIP(r)
+	}
+	return cache.RateLimitPrincipalIP, ip
+}
+
+// requestToken returns the Bearer token of r, or the access_token cookie
+// when there is no Authorization header, as JWTAuthMiddleware reads them.
+func requestToken(r *http.Request) string {
+	if authHeader := r.Header.Get("Authorization"); authHeader != "" {
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
+			return parts[1]
+		}
+		return ""
+	}
+	if cookie, err := r.Cookie("access_token"); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// ceilSeconds rounds d up to whole seconds.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,248 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubRateLimiter records the keys it is asked about and answers with result
+// or err.
+type stubRateLimiter struct {
+	keys   []string
+	limits []cache.RateLimit
+	result cache.RateLimitResult
+	err    error
+}
+
+func (s *stubRateLimiter) AllowRequest(_ context.Context, key string, limit cache.RateLimit, _ time.Time) (cache.RateLimitResult, error) {
+	s.keys = append(s.keys, key)
+	s.limits = append(s.limits, limit)
+	return s.result, s.err
+}
+
+var testRateLimitPolicies = map[string]cache.RateLimit{
+	"auth.ip":         {Requests: 10, Period: time.Minute, Burst: 10},
+	"default.ip":      {Requests: 100, Period: time.Minute, Burst: 100},
+	"default.user":    {Requests: 200, Period: time.Minute, Burst: 200},
+	"default.client":  {Requests: 300, Period: time.Minute, Burst: 300},
+	"default.api_key": {Requests: 400, Period: time.Minute, Burst: 400},
+	"management.user": {Requests: 50, Period: time.Minute, Burst: 50},
+}
+
+func serveRateLimited(limiter RateLimiter, group string, policies map[string]cache.RateLimit, req *http.Request) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	RateLimitMiddleware(limiter, group, func() map[string]cache.RateLimit { return policies })(okHandler()).ServeHTTP(rr, req)
+	return rr
+}
+
+func TestRateLimitMiddleware_Principals(t *testing.T) {
+	initTestJWTKeys(t)
+	userUUID := uuid.New().String()
+	userToken, err := jwt.GenerateAccessToken(userUUID, "", "https://auth.example.com", "api", "my-client", "provider-1")
+	require.NoError(t, err)
+	clientToken, err := jwt.GenerateAccessToken("my-client", "", "https://auth.example.com", "api", "my-client", "provider-1")
+	require.NoError(t, err)
+	patUUID := uuid.New()
+	sum := sha256.Sum256([]byte("ak_secret"))
+	apiKeyHash := hex.EncodeToString(sum[:])
+
+	cases := []struct {
+		name    string
+		group   string
+		setup   func(r *http.Request) *http.Request
+		wantKey string
+		want    cache.RateLimit
+	}{
+		{
+			name:    "ip with a group policy",
+			group:   RateLimitGroupAuth,
+			setup:   func(r *http.Request) *http.Request { return r },
+			wantKey: "auth:ip:192.0.2.1",
+			want:    testRateLimitPolicies["auth.ip"],
+		},
+		{
+			name:    "ip falls back to the default policy",
+			group:   RateLimitGroupAccount,
+			setup:   func(r *http.Request) *http.Request { return r },
+			wantKey: "account:ip:192.0.2.1",
+			want:    testRateLimitPolicies["default.ip"],
+		},
+		{
+			name:  "user token",
+			group: RateLimitGroupManagement,
+			setup: func(r *http.Request) *http.Request {
+				r.Header.Set("Authorization", "Bearer "+userToken)
+				return r
+			},
+			wantKey: "management:user:" + userUUID,
+			want:    testRateLimitPolicies["management.user"],
+		},
+		{
+			name:  "access token cookie",
+			group: RateLimitGroupAccount,
+			setup: func(r *http.Request) *http.Request {
+				r.AddCookie(&http.Cookie{Name: "access_token", Value: userToken})
+				return r
+			},
+			wantKey: "account:user:" + userUUID,
+			want:    testRateLimitPolicies["default.user"],
+		},
+		{
+			name:  "client credentials token",
+			group: RateLimitGroupManagement,
+			setup: func(r *http.Request) *http.Request {
+				r.Header.Set("Authorization", "Bearer "+clientToken)
+				return r
+			},
+			wantKey: "management:client:my-client",
+			want:    testRateLimitPolicies["default.client"],
+		},
+		{
+			name:  "forged token counts against the ip",
+			group: RateLimitGroupManagement,
+			setup: func(r *http.Request) *http.Request {
+				r.Header.Set("Authorization", "Bearer not.a.jwt")
+				return r
+			},
+			wantKey: "management:ip:192.0.2.1",
+			want:    testRateLimitPolicies["default.ip"],
+		},
+		{
+			name:  "personal access token",
+			group: RateLimitGroupManagement,
+			setup: func(r *http.Request) *http.Request {
+				return WithPersonalAccessToken(r, &PersonalAccessTokenPrincipal{TokenUUID: patUUID})
+			},
+			wantKey: "management:user:pat:" + patUUID.String(),
+			want:    testRateLimitPolicies["management.user"],
+		},
+		{
+			name:  "browser session",
+			group: RateLimitGroupAccount,
+			setup: func(r *http.Request) *http.Request {
+				return WithBrowserSession(r, &BrowserSession{UserUUID: patUUID})
+			},
+			wantKey: "account:user:" + patUUID.String(),
+			want:    testRateLimitPolicies["default.user"],
+		},
+		{
+			name:  "api key",
+			group: RateLimitGroupManagement,
+			setup: func(r *http.Request) *http.Request {
+				r.Header.Set(APIKeyHeader, "ak_secret")
+				r.Header.Set("Authorization", "Bearer "+userToken)
+				return r
+			},
+			wantKey: "management:api_key:" + apiKeyHash,
+			want:    testRateLimitPolicies["default.api_key"],
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			limiter := &stubRateLimiter{result: cache.RateLimitResult{Allowed: true, Limit: 10, Remaining: 9}}
+			req := tc.setup(httptest.NewRequest(http.MethodGet, "/", nil))
+			req.RemoteAddr = "192.0.2.1:1234"
+
+			rr := serveRateLimited(limiter, tc.group, testRateLimitPolicies, req)
+			assert.Equal(t, http.StatusOK, rr.Code)
+			require.Len(t, limiter.keys, 1)
+			assert.Equal(t, tc.wantKey, limiter.keys[0])
+			assert.Equal(t, tc.want, limiter.limits[0])
+		})
+	}
+}
+
+func TestRateLimitMiddleware_Headers(t *testing.T) {
+	limiter := &stubRateLimiter{result: cache.RateLimitResult{Allowed: true, Limit: 10, Remaining: 7, Reset: 1500 * time.Millisecond}}
+	rr := serveRateLimited(limiter, RateLimitGroupAuth, testRateLimitPolicies, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "10", rr.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "7", rr.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "2", rr.Header().Get("X-RateLimit-Reset"))
+	assert.Empty(t, rr.Header().Get("Retry-After"))
+}
+
+func TestRateLimitMiddleware_Rejects(t *testing.T) {
+	limiter := &stubRateLimiter{result: cache.RateLimitResult{Limit: 10, RetryAfter: 5200 * time.Millisecond, Reset: time.Minute}}
+	rr := serveRateLimited(limiter, RateLimitGroupAuth, testRateLimitPolicies, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "6", rr.Header().Get("Retry-After"))
+	assert.Equal(t, "0", rr.Header().Get("X-RateLimit-Remaining"))
+	assert.Contains(t, rr.Body.String(), "GEN-0429")
+}
+
+func TestRateLimitMiddleware_FailsOpen(t *testing.T) {
+	limiter := &stubRateLimiter{err: errors.New("redis down")}
+	rr := serveRateLimited(limiter, RateLimitGroupAuth, testRateLimitPolicies, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("X-RateLimit-Limit"))
+}
+
+func TestRateLimitMiddleware_Unlimited(t *testing.T) {
+	t.Run("no policies", func(t *testing.T) {
+		limiter := &stubRateLimiter{}
+		rr := serveRateLimited(limiter, RateLimitGroupAuth, nil, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, limiter.keys)
+	})
+
+	t.Run("no policy for the principal", func(t *testing.T) {
+		limiter := &stubRateLimiter{}
+		policies := map[string]cache.RateLimit{"auth.user": {Requests: 1, Period: time.Second, Burst: 1}}
+		rr := serveRateLimited(limiter, RateLimitGroupAuth, policies, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, limiter.keys)
+	})
+}
+
+func TestRateLimitMiddleware_ReadsPoliciesPerRequest(t *testing.T) {
+	limiter := &stubRateLimiter{result: cache.RateLimitResult{Allowed: true, Limit: 10}}
+	var policies map[string]cache.RateLimit
+	h := RateLimitMiddleware(limiter, RateLimitGroupAuth, func() map[string]cache.RateLimit { return policies })(okHandler())
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Empty(t, limiter.keys)
+
+	policies = testRateLimitPolicies
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Len(t, limiter.keys, 1)
+}
+
+func TestRateLimitMiddleware_Redis(t *testing.T) {
+	_, cli := newMiniredisClient(t)
+	policies := map[string]cache.RateLimit{"auth.ip": {Requests: 2, Period: time.Hour, Burst: 2}}
+	h := RateLimitMiddleware(cache.New(cli), RateLimitGroupAuth, func() map[string]cache.RateLimit { return policies })(okHandler())
+
+	codes := make([]int, 3)
+	for i := range codes {
+		req := httptest.NewRequest(http.MethodPost, "/login", nil)
+		req.RemoteAddr = "198.51.100.7:4000"
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		codes[i] = rr.Code
+	}
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+
+	// Another client is not affected.
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	req.RemoteAddr = "198.51.100.8:4000"
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -22,8 +22,13 @@ const (
 	RequestIDKey     SecurityContextKey = "request_id"
 	SessionIDKey     SecurityContextKey = "session_id"
 	SecurityEventKey SecurityContextKey = "security_event"
+	TrustedDeviceKey SecurityContextKey = "trusted_device"
 )
 
+// TrustedDeviceHeader carries the trusted device token of clients that do
+// not keep the trusted_device cookie, such as mobile apps.
+const TrustedDeviceHeader = "X-Trusted-Device"
+
 // SecurityHeadersMiddleware adds security headers for SOC2/ISO27001 compliance
 // Complies with SOC2 CC6.1 and ISO27001 A.13.2.1
 func SecurityHeadersMiddleware(next http.Handler) http.Handler {
@@ -75,6 +80,9 @@ func SecurityContextMiddleware(next http.Handler) http.Handler {
 		ctx := context.WithValue(r.Context(), ClientIPKey, clientIP)
 		ctx = context.WithValue(ctx, UserAgentKey, userAgent)
 		ctx = context.WithValue(ctx, RequestIDKey, requestID)
+		if token := trustedDeviceToken(r); token != "" {
+			ctx = context.WithValue(ctx, TrustedDeviceKey, token)
+		}
 
 		// Log security event for monitoring
 		security.LogSecurityEvent(security.SecurityEvent{
@@ -221,6 +229,28 @@ func UserAgentFromContext(ctx context.Context) string {
 	return ""
 }
 
+// TrustedDeviceTokenFromContext returns the trusted device token of the
+// request stored in ctx by SecurityContextMiddleware. Returns an empty string
+// when the request named no trusted device.
+func TrustedDeviceTokenFromContext(ctx context.Context) string {
+	if v, ok := ctx.Value(TrustedDeviceKey).(string); ok {
+		return v
+	}
+	return ""
+}
+
+// trustedDeviceToken returns the trusted device token of the request, from
+// the X-Trusted-Device header or else the trusted_device cookie.
+func trustedDeviceToken(r *http.Request) string {
+	if token := strings.TrimSpace(r.Header.Get(TrustedDeviceHeader)); token != "" {
+		return token
+	}
+	if c, err := r.Cookie("trusted_device"); err == nil {
+		return c.Value
+	}
+	return ""
+}
+
 // isProduction checks if running in production environment
 func isProduction() bool {
 	return config.GetEnvOrDefault("ENV", "development") == "production"
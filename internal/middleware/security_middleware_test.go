@@ -47,6 +47,31 @@ func TestSecurityContextMiddleware(t *testing.T) {
 	assert.NotEmpty(t, rr.Header().Get("X-Request-ID"))
 }
 
+func TestSecurityContextMiddleware_TrustedDevice(t *testing.T) {
+	var got string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = TrustedDeviceTokenFromContext(r.Context())
+	})
+
+	t.Run("cookie", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: "trusted_device", Value: "td_cookie"})
+		SecurityContextMiddleware(next).ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal(t, "td_cookie", got)
+	})
+	t.Run("header wins over cookie", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: "trusted_device", Value: "td_cookie"})
+		req.Header.Set(TrustedDeviceHeader, "td_header")
+		SecurityContextMiddleware(next).ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal(t, "td_header", got)
+	})
+	t.Run("none", func(t *testing.T) {
+		SecurityContextMiddleware(next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "", got)
+	})
+}
+
 func TestRequestSizeLimitMiddleware(t *testing.T) {
 	t.Run("within limit → passes", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/", nil)
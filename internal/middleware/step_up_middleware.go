@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+)
+
+// ReauthenticatePath is where a step-up challenge is answered.
+const ReauthenticatePath = "/api/v1/account/reauthenticate"
+
+// StepUpVerifier decides whether the caller re-authenticated recently enough
+// for a step-up protected action and records the decision in the audit log.
+type StepUpVerifier interface {
+	// StepUpMaxAge is how long a re-authentication satisfies step-up; 0
+	// disables step-up.
+	StepUpMaxAge() time.Duration
+	// VerifyStepUp reports whether the credential of auth, identified by
+	// credential, re-authenticated within StepUpMaxAge. action names the
+	// protected request, e.g. "DELETE /api/v1/clients/...".
+	VerifyStepUp(ctx context.Context, auth *AuthContext, credential, action string) (bool, error)
+}
+
+// stepUpVerifierKey is the unexported context key type for the
+// StepUpVerifier.
+type stepUpVerifierKey struct{}
+
+// StepUpVerifierMiddleware makes verifier available to RequireStepUp for
+// every request on the router.
+func StepUpVerifierMiddleware(verifier StepUpVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), stepUpVerifierKey{}, verifier)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireStepUp guards a high-risk action behind a recent re-authentication
+// of the caller's credential. It must run after UserContextMiddleware.
+// Without one, the request is answered with 401, code AUTH-1008 and an RFC
+// 9470 challenge:
+//
+//	WWW-Authenticate: Bearer error="insufficient_user_authentication", max_age=300
+//
+// The client re-authenticates at ReauthenticatePath with the same credential
+// and retries. Requests fail closed when no verifier is configured or the
+// verifier fails.
+func RequireStepUp(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		verifier, _ := ctx.Value(stepUpVerifierKey{}).(StepUpVerifier)
+		if verifier == nil {
+			slog.ErrorContext(ctx, "step-up: no verifier configured", "path", r.URL.Path)
+			resp.ErrorWithCode(w, apperror.CodeServiceUnavailable, "Step-up verification is unavailable")
+			return
+		}
+		maxAge := verifier.StepUpMaxAge()
+		if maxAge <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		auth := AuthFromRequest(r)
+		if auth.User == nil {
+			resp.ErrorWithCode(w, apperror.CodeMissingCredentials, "Authentication required")
+			return
+		}
+
+		ok, err := verifier.VerifyStepUp(ctx, auth, StepUpCredential(r), r.Method+" "+r.URL.Path)
+		if err != nil {
+			slog.ErrorContext(ctx, "step-up: verification failed", "error", err)
+			resp.ErrorWithCode(w, apperror.CodeServiceUnavailable, "Step-up verification is unavailable")
+			return
+		}
+		if !ok {
+			seconds := ceilSeconds(maxAge)
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+				`Bearer error="insufficient_user_authentication", error_description="A recent re-authentication is required", max_age=%d`, seconds))
+			resp.ErrorWithCode(w, apperror.CodeStepUpRequired, "Re-authenticate to perform this action", map[string]any{
+				"max_age":        seconds,
+				"reauthenticate": ReauthenticatePath,
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// StepUpCredential identifies the credential of r that a re-authentication
+// applies to: the login session of a JWT or browser session, so that
+// refreshed tokens keep it, and otherwise the JWT itself or the personal
+// access token. It is empty for requests without one, which can never pass
+// step-up.
+func StepUpCredential(r *http.Request) string {
+	if pat := PersonalAccessTokenFromRequest(r); pat != nil {
+		return "pat:" + pat.TokenUUID.String()
+	}
+	if bs := BrowserSessionFromRequest(r); bs != nil {
+		if bs.SessionID != "" {
+			return "sid:" + bs.SessionID
+		}
+		return ""
+	}
+	if claims := JWTClaimsFromRequest(r); claims != nil {
+		switch {
+		case claims.SessionID != "":
+			return "sid:" + claims.SessionID
+		case claims.JTI != "":
+			return "jti:" + claims.JTI
+		}
+	}
+	return ""
+}
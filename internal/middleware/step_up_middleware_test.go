@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubStepUpVerifier struct {
+	maxAge     time.Duration
+	ok         bool
+	err        error
+	credential string
+	action     string
+}
+
+func (s *stubStepUpVerifier) StepUpMaxAge() time.Duration { return s.maxAge }
+
+func (s *stubStepUpVerifier) VerifyStepUp(_ context.Context, _ *AuthContext, credential, action string) (bool, error) {
+	s.credential, s.action = credential, action
+	return s.ok, s.err
+}
+
+func TestRequireStepUp(t *testing.T) {
+	serve := func(verifier StepUpVerifier, withUser bool) *httptest.ResponseRecorder {
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/clients/abc", nil)
+		req = WithJWTClaims(req, &JWTClaims{SessionID: "sid-1", JTI: "jti-1"})
+		if withUser {
+			req = WithAuthContext(req, &AuthContext{User: &model.User{UserID: 1}})
+		}
+		h := RequireStepUp(next)
+		if verifier != nil {
+			h = StepUpVerifierMiddleware(verifier)(h)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("recent re-authentication passes", func(t *testing.T) {
+		v := &stubStepUpVerifier{maxAge: 5 * time.Minute, ok: true}
+		assert.Equal(t, http.StatusNoContent, serve(v, true).Code)
+		assert.Equal(t, "sid:sid-1", v.credential)
+		assert.Equal(t, "DELETE /api/v1/clients/abc", v.action)
+	})
+
+	t.Run("challenge without one", func(t *testing.T) {
+		w := serve(&stubStepUpVerifier{maxAge: 5 * time.Minute}, true)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, `Bearer error="insufficient_user_authentication", error_description="A recent re-authentication is required", max_age=300`,
+			w.Header().Get("WWW-Authenticate"))
+
+		var body struct {
+			Code    string         `json:"code"`
+			Details map[string]any `json:"details"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		assert.Equal(t, "AUTH-1008", body.Code)
+		assert.Equal(t, float64(300), body.Details["max_age"])
+		assert.Equal(t, ReauthenticatePath, body.Details["reauthenticate"])
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, serve(&stubStepUpVerifier{}, true).Code)
+	})
+
+	t.Run("no user", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, serve(&stubStepUpVerifier{maxAge: time.Minute, ok: true}, false).Code)
+	})
+
+	t.Run("fails closed", func(t *testing.T) {
+		assert.Equal(t, http.StatusServiceUnavailable, serve(&stubStepUpVerifier{maxAge: time.Minute, err: errors.New("redis down")}, true).Code)
+		assert.Equal(t, http.StatusServiceUnavailable, serve(nil, true).Code)
+	})
+}
+
+func TestStepUpCredential(t *testing.T) {
+	patUUID := uuid.New()
+	req := func() *http.Request { return httptest.NewRequest(http.MethodPost, "/", nil) }
+
+	assert.Equal(t, "", StepUpCredential(req()))
+	assert.Equal(t, "pat:"+patUUID.String(), StepUpCredential(WithPersonalAccessToken(req(), &PersonalAccessTokenPrincipal{TokenUUID: patUUID})))
+	assert.Equal(t, "sid:s1", StepUpCredential(WithBrowserSession(req(), &BrowserSession{SessionID: "s1"})))
+	assert.Equal(t, "", StepUpCredential(WithBrowserSession(req(), &BrowserSession{})))
+	assert.Equal(t, "sid:s2", StepUpCredential(WithJWTClaims(req(), &JWTClaims{SessionID: "s2", JTI: "j"})))
+	assert.Equal(t, "jti:j", StepUpCredential(WithJWTClaims(req(), &JWTClaims{JTI: "j"})))
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+)
+
+// TenantAncestryProvider is the minimal interface required by
+// TenantScopeMiddleware to look up the ancestors of a tenant in the tenant
+// hierarchy.
+type TenantAncestryProvider interface {
+	GetAncestorIDs(ctx context.Context, tenantUUID uuid.UUID) ([]int64, error)
+}
+
+// CanAccessTenant reports whether the caller in auth may act on the tenant
+// identified by tenantUUID, whose ancestors have ancestorIDs. Callers may act
+// on the tenant their token was issued for, on every tenant they have an
+// identity in, and on every tenant below those. Callers whose token or
+// identity belongs to a system tenant may act on any tenant.
+func CanAccessTenant(auth *AuthContext, tenantUUID uuid.UUID, ancestorIDs ...int64) bool {
+	grants := func(tenant *model.Tenant) bool {
+		return tenant.IsSystem || tenant.TenantUUID == tenantUUID || slices.Contains(ancestorIDs, tenant.TenantID)
+	}
+
+	if auth.Tenant != nil && grants(auth.Tenant) {
+		return true
+	}
+	if auth.User == nil {
+		return false
+	}
+	for _, identity := range auth.User.UserIdentities {
+		if identity.Tenant == nil {
+			continue
+		}
+		if grants(identity.Tenant) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccessTenantTree is CanAccessTenant with the tenant's ancestors looked up
+// through provider. The lookup is skipped when the caller may act on the
+// tenant directly.
+func CanAccessTenantTree(ctx context.Context, provider TenantAncestryProvider, auth *AuthContext, tenantUUID uuid.UUID) (bool, error) {
+	if CanAccessTenant(auth, tenantUUID) {
+		return true, nil
+	}
+	ancestorIDs, err := provider.GetAncestorIDs(ctx, tenantUUID)
+	if err != nil {
+		return false, err
+	}
+	return len(ancestorIDs) > 0 && CanAccessTenant(auth, tenantUUID, ancestorIDs...), nil
+}
+
+// AccessibleTenantIDs returns the IDs of the tenants the caller in auth may act
+// on directly; CanAccessTenant also lets the caller act on the tenants below
+// them. all is true when the caller may act on every tenant, in which case ids
+// is nil.
+func AccessibleTenantIDs(auth *AuthContext) (ids []int64, all bool) {
+	ids = []int64{}
+	seen := make(map[int64]struct{})
+	add := func(id int64) {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+
+	if auth.Tenant != nil {
+		if auth.Tenant.IsSystem {
+			return nil, true
+		}
+		add(auth.Tenant.TenantID)
+	}
+	if auth.User != nil {
+		for _, identity := range auth.User.UserIdentities {
+			if identity.Tenant == nil {
+				continue
+			}
+			if identity.Tenant.IsSystem {
+				return nil, true
+			}
+			add(identity.Tenant.TenantID)
+		}
+	}
+	return ids, false
+}
+
+// TenantScopeMiddleware confines a request to the tenant in its {tenant_uuid}
+// route parameter. It responds 400 when the parameter is not a UUID and 403
+// when CanAccessTenantTree does not let the caller act on the tenant. chi only
+// resolves the parameter once the route has matched, so the middleware must be
+// added with r.With or inside a sub-router mounted below the parameter.
+func TenantScopeMiddleware(provider TenantAncestryProvider) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenantUUID, err := uuid.Parse(chi.URLParam(r, "tenant_uuid"))
+			if err != nil {
+				resp.Error(w, http.StatusBadRequest, "Invalid tenant UUID")
+				return
+			}
+
+			auth := AuthFromRequest(r)
+			if auth.User == nil {
+				resp.Error(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+
+			allowed, err := CanAccessTenantTree(r.Context(), provider, auth, tenantUUID)
+			if err != nil {
+				resp.HandleServiceError(w, r, "Failed to verify tenant access", err)
+				return
+			}
+			if !allowed {
+				resp.Error(w, http.StatusForbidden, "Access denied", "You do not have access to this tenant")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	scopeTenantA = &model.Tenant{TenantID: 1, TenantUUID: uuid.MustParse("00000000-0000-0000-0000-00000000000a")}
+	scopeTenantB = &model.Tenant{TenantID: 2, TenantUUID: uuid.MustParse("00000000-0000-0000-0000-00000000000b")}
+	scopeSystem  = &model.Tenant{TenantID: 3, TenantUUID: uuid.MustParse("00000000-0000-0000-0000-00000000000c"), IsSystem: true}
+	// scopeChildA is a child tenant of scopeTenantA.
+	scopeChildA = &model.Tenant{TenantID: 4, TenantUUID: uuid.MustParse("00000000-0000-0000-0000-00000000000d"), AncestorPath: "/1/"}
+)
+
+// mockTenantAncestry serves the ancestors of the scope fixtures.
+type mockTenantAncestry struct {
+	err   error
+	calls int
+}
+
+func (m *mockTenantAncestry) GetAncestorIDs(_ context.Context, tenantUUID uuid.UUID) ([]int64, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, tenant := range []*model.Tenant{scopeTenantA, scopeTenantB, scopeSystem, scopeChildA} {
+		if tenant.TenantUUID == tenantUUID {
+			return tenant.AncestorIDs(), nil
+		}
+	}
+	return nil, apperror.NewNotFound("tenant")
+}
+
+// userInTenants returns a user with one identity in each of tenants.
+func userInTenants(tenants ...*model.Tenant) *model.User {
+	user := &model.User{UserID: 1}
+	for _, tenant := range tenants {
+		user.UserIdentities = append(user.UserIdentities, model.UserIdentity{TenantID: tenant.TenantID, Tenant: tenant})
+	}
+	return user
+}
+
+func TestCanAccessTenant(t *testing.T) {
+	cases := []struct {
+		name      string
+		auth      *AuthContext
+		tenant    uuid.UUID
+		ancestors []int64
+		want      bool
+	}{
+		{
+			name:   "empty context",
+			auth:   &AuthContext{},
+			tenant: scopeTenantA.TenantUUID,
+			want:   false,
+		},
+		{
+			name:   "token tenant",
+			auth:   &AuthContext{User: userInTenants(), Tenant: scopeTenantA},
+			tenant: scopeTenantA.TenantUUID,
+			want:   true,
+		},
+		{
+			name:   "other tenant than the token's",
+			auth:   &AuthContext{User: userInTenants(), Tenant: scopeTenantA},
+			tenant: scopeTenantB.TenantUUID,
+			want:   false,
+		},
+		{
+			name:   "identity in the tenant",
+			auth:   &AuthContext{User: userInTenants(scopeTenantA, scopeTenantB), Tenant: scopeTenantA},
+			tenant: scopeTenantB.TenantUUID,
+			want:   true,
+		},
+		{
+			name:   "identity without a loaded tenant is ignored",
+			auth:   &AuthContext{User: &model.User{UserIdentities: []model.UserIdentity{{TenantID: 2}}}},
+			tenant: scopeTenantB.TenantUUID,
+			want:   false,
+		},
+		{
+			name:   "identity in a system tenant",
+			auth:   &AuthContext{User: userInTenants(scopeSystem), Tenant: scopeTenantA},
+			tenant: scopeTenantB.TenantUUID,
+			want:   true,
+		},
+		{
+			name:   "token issued for a system tenant",
+			auth:   &AuthContext{User: userInTenants(), Tenant: scopeSystem},
+			tenant: scopeTenantB.TenantUUID,
+			want:   true,
+		},
+		{
+			name:      "token tenant is an ancestor",
+			auth:      &AuthContext{User: userInTenants(), Tenant: scopeTenantA},
+			tenant:    scopeChildA.TenantUUID,
+			ancestors: []int64{1},
+			want:      true,
+		},
+		{
+			name:      "identity in an ancestor",
+			auth:      &AuthContext{User: userInTenants(scopeTenantA), Tenant: scopeTenantB},
+			tenant:    scopeChildA.TenantUUID,
+			ancestors: []int64{1},
+			want:      true,
+		},
+		{
+			name:      "unrelated ancestors",
+			auth:      &AuthContext{User: userInTenants(scopeTenantB), Tenant: scopeTenantB},
+			tenant:    scopeChildA.TenantUUID,
+			ancestors: []int64{1},
+			want:      false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, CanAccessTenant(tc.auth, tc.tenant, tc.ancestors...))
+		})
+	}
+}
+
+func TestCanAccessTenantTree(t *testing.T) {
+	t.Run("direct access skips the lookup", func(t *testing.T) {
+		provider := &mockTenantAncestry{}
+		ok, err := CanAccessTenantTree(context.Background(), provider, &AuthContext{Tenant: scopeTenantA}, scopeTenantA.TenantUUID)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Zero(t, provider.calls)
+	})
+
+	t.Run("access through an ancestor", func(t *testing.T) {
+		ok, err := CanAccessTenantTree(context.Background(), &mockTenantAncestry{}, &AuthContext{Tenant: scopeTenantA}, scopeChildA.TenantUUID)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("parent of the caller's tenant", func(t *testing.T) {
+		ok, err := CanAccessTenantTree(context.Background(), &mockTenantAncestry{}, &AuthContext{Tenant: scopeChildA}, scopeTenantA.TenantUUID)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("lookup error", func(t *testing.T) {
+		_, err := CanAccessTenantTree(context.Background(), &mockTenantAncestry{err: errors.New("db down")}, &AuthContext{Tenant: scopeTenantB}, scopeChildA.TenantUUID)
+		assert.Error(t, err)
+	})
+}
+
+func TestAccessibleTenantIDs(t *testing.T) {
+	t.Run("token and identity tenants", func(t *testing.T) {
+		ids, all := AccessibleTenantIDs(&AuthContext{User: userInTenants(scopeTenantA, scopeTenantB), Tenant: scopeTenantA})
+		assert.False(t, all)
+		assert.Equal(t, []int64{1, 2}, ids)
+	})
+
+	t.Run("no tenants", func(t *testing.T) {
+		ids, all := AccessibleTenantIDs(&AuthContext{})
+		assert.False(t, all)
+		assert.NotNil(t, ids)
+		assert.Empty(t, ids)
+	})
+
+	t.Run("system identity", func(t *testing.T) {
+		ids, all := AccessibleTenantIDs(&AuthContext{User: userInTenants(scopeTenantA, scopeSystem), Tenant: scopeTenantA})
+		assert.True(t, all)
+		assert.Nil(t, ids)
+	})
+
+	t.Run("system token", func(t *testing.T) {
+		_, all := AccessibleTenantIDs(&AuthContext{Tenant: scopeSystem})
+		assert.True(t, all)
+	})
+}
+
+func TestTenantScopeMiddleware(t *testing.T) {
+	cases := []struct {
+		name       string
+		auth       *AuthContext
+		param      string
+		wantStatus int
+	}{
+		{
+			name:       "invalid tenant UUID",
+			auth:       &AuthContext{User: userInTenants(scopeTenantA), Tenant: scopeTenantA},
+			param:      "not-a-uuid",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "no user",
+			auth:       &AuthContext{},
+			param:      scopeTenantA.TenantUUID.String(),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "other tenant",
+			auth:       &AuthContext{User: userInTenants(scopeTenantA), Tenant: scopeTenantA},
+			param:      scopeTenantB.TenantUUID.String(),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "own tenant",
+			auth:       &AuthContext{User: userInTenants(scopeTenantA), Tenant: scopeTenantA},
+			param:      scopeTenantA.TenantUUID.String(),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "system administrator",
+			auth:       &AuthContext{User: userInTenants(scopeSystem), Tenant: scopeSystem},
+			param:      scopeTenantB.TenantUUID.String(),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "child of own tenant",
+			auth:       &AuthContext{User: userInTenants(scopeTenantA), Tenant: scopeTenantA},
+			param:      scopeChildA.TenantUUID.String(),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "unknown tenant",
+			auth:       &AuthContext{User: userInTenants(scopeTenantA), Tenant: scopeTenantA},
+			param:      uuid.New().String(),
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Use(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					next.ServeHTTP(w, WithAuthContext(req, tc.auth))
+				})
+			})
+			r.With(TenantScopeMiddleware(&mockTenantAncestry{})).Get("/tenants/{tenant_uuid}", func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tenants/"+tc.param, nil))
+			assert.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestTenantScopeMiddleware_SubRouter(t *testing.T) {
+	// The parameter is resolved by the parent router before the sub-router's
+	// middleware runs.
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(w, WithAuthContext(req, &AuthContext{User: userInTenants(scopeTenantA), Tenant: scopeTenantA}))
+		})
+	})
+	r.Route("/tenants/{tenant_uuid}/members", func(r chi.Router) {
+		r.Use(TenantScopeMiddleware(&mockTenantAncestry{}))
+		r.Get("/", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tenants/"+scopeTenantA.TenantUUID.String()+"/members", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tenants/"+scopeTenantB.TenantUUID.String()+"/members", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
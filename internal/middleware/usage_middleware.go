@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+)
+
+// UsageMeter counts the usage of tenants against their monthly quotas.
+type UsageMeter interface {
+	// MeterRequest counts a request of tenantID, made with the API key
+	// apiKeyID when it is not 0, and reports whether it is within the
+	// tenant's and the key's monthly request quotas.
+	MeterRequest(ctx context.Context, tenantID, apiKeyID int64) (bool, error)
+	// MeterTokenIssued counts an access token issued for tenantID and
+	// reports whether it is within the tenant's monthly token quota.
+	MeterTokenIssued(ctx context.Context, tenantID int64) (bool, error)
+}
+
+// usageScopeKey is the context key of the request's usageScope.
+type usageScopeKey struct{}
+
+// usageScope carries the UsageMeter of a request. The tenant is only known
+// once the request is authenticated, which is after UsageMeterMiddleware has
+// run, so the authentication middleware meter the request and note it here
+// so that it is counted once.
+type usageScope struct {
+	meter   UsageMeter
+	metered bool
+}
+
+// UsageMeterMiddleware makes meter available to the authentication
+// middleware, which count each request against the quotas of its tenant, and
+// to services issuing access tokens (see MeterTokenIssued).
+func UsageMeterMiddleware(meter UsageMeter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), usageScopeKey{}, &usageScope{meter: meter})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// meterRequest counts r against the monthly request quota of tenantID, and
+// of apiKeyID when it is not 0, unless r was counted already. Over quota, it
+// answers 429 with code TEN-2005 and returns false. Requests pass when no
+// meter is installed or the meter fails, so that an unavailable Redis does
+// not take down the API.
+func meterRequest(w http.ResponseWriter, r *http.Request, tenantID, apiKeyID int64) bool {
+	ctx := r.Context()
+	scope, _ := ctx.Value(usageScopeKey{}).(*usageScope)
+	if scope == nil || scope.meter == nil || scope.metered || tenantID == 0 {
+		return true
+	}
+	scope.metered = true
+
+	ok, err := scope.meter.MeterRequest(ctx, tenantID, apiKeyID)
+	if err != nil {
+		slog.WarnContext(ctx, "usage: request metering failed", "tenant_id", tenantID, "error", err)
+		return true
+	}
+	if !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(UntilQuotaReset(time.Now()))))
+		resp.ErrorWithCode(w, apperror.CodeTenantQuotaExceeded, "Monthly request quota exceeded")
+		return false
+	}
+	return true
+}
+
+// MeterTokenIssued counts an access token issued for tenantID with the
+// UsageMeter of ctx and reports whether it is within the tenant's monthly
+// token quota. It reports true when ctx has no meter or the meter fails.
+func MeterTokenIssued(ctx context.Context, tenantID int64) bool {
+	scope, _ := ctx.Value(usageScopeKey{}).(*usageScope)
+	if scope == nil || scope.meter == nil {
+		return true
+	}
+	ok, err := scope.meter.MeterTokenIssued(ctx, tenantID)
+	if err != nil {
+		slog.WarnContext(ctx, "usage: token metering failed", "tenant_id", tenantID, "error", err)
+		return true
+	}
+	return ok
+}
+
+// UntilQuotaReset is how long after now monthly quotas start over, at the
+// beginning of the next calendar month (UTC).
+func UntilQuotaReset(now time.Time) time.Duration {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC).Sub(now)
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubUsageMeter struct {
+	ok       bool
+	err      error
+	requests int
+	tokens   int
+	tenantID int64
+	apiKeyID int64
+}
+
+func (s *stubUsageMeter) MeterRequest(_ context.Context, tenantID, apiKeyID int64) (bool, error) {
+	s.requests++
+	s.tenantID, s.apiKeyID = tenantID, apiKeyID
+	return s.ok, s.err
+}
+
+func (s *stubUsageMeter) MeterTokenIssued(_ context.Context, tenantID int64) (bool, error) {
+	s.tokens++
+	s.tenantID = tenantID
+	return s.ok, s.err
+}
+
+// serveMetered runs a request through UsageMeterMiddleware, when meter is
+// not nil, and a handler metering it as tenant 3 with API key 7 twice.
+func serveMetered(meter UsageMeter) *httptest.ResponseRecorder {
+	var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !meterRequest(w, r, 3, 7) || !meterRequest(w, r, 3, 7) {
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	if meter != nil {
+		h = UsageMeterMiddleware(meter)(h)
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rr
+}
+
+func TestMeterRequest(t *testing.T) {
+	t.Run("within quota counts once", func(t *testing.T) {
+		meter := &stubUsageMeter{ok: true}
+		assert.Equal(t, http.StatusNoContent, serveMetered(meter).Code)
+		assert.Equal(t, 1, meter.requests)
+		assert.Equal(t, int64(3), meter.tenantID)
+		assert.Equal(t, int64(7), meter.apiKeyID)
+	})
+
+	t.Run("over quota", func(t *testing.T) {
+		rr := serveMetered(&stubUsageMeter{})
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		assert.Equal(t, "TEN-2005", body["code"])
+	})
+
+	t.Run("meter failure passes", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, serveMetered(&stubUsageMeter{err: errors.New("redis down")}).Code)
+	})
+
+	t.Run("no meter passes", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, serveMetered(nil).Code)
+	})
+
+	t.Run("requests without a tenant are not counted", func(t *testing.T) {
+		meter := &stubUsageMeter{}
+		h := UsageMeterMiddleware(meter)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.True(t, meterRequest(w, r, 0, 0))
+		}))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Zero(t, meter.requests)
+	})
+}
+
+func TestMeterRequest_PersonalAccessToken(t *testing.T) {
+	meter := &stubUsageMeter{}
+	h := UsageMeterMiddleware(meter)(PersonalAccessTokenMiddleware(&stubPATValidator{pat: testPAT("user:read")})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("over-quota request must not reach the handler")
+		})))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer pat_abc")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, 1, meter.requests)
+	assert.Equal(t, int64(3), meter.tenantID)
+	assert.Zero(t, meter.apiKeyID)
+}
+
+func TestMeterTokenIssued(t *testing.T) {
+	ctxWith := func(meter UsageMeter) context.Context {
+		var ctx context.Context
+		UsageMeterMiddleware(meter)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			ctx = r.Context()
+		})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+		return ctx
+	}
+
+	assert.True(t, MeterTokenIssued(context.Background(), 3))
+
+	meter := &stubUsageMeter{ok: true}
+	assert.True(t, MeterTokenIssued(ctxWith(meter), 3))
+	assert.Equal(t, 1, meter.tokens)
+	assert.Equal(t, int64(3), meter.tenantID)
+
+	assert.False(t, MeterTokenIssued(ctxWith(&stubUsageMeter{}), 3))
+	assert.True(t, MeterTokenIssued(ctxWith(&stubUsageMeter{err: errors.New("redis down")}), 3))
+}
+
+func TestUntilQuotaReset(t *testing.T) {
+	assert.Equal(t, time.Hour, UntilQuotaReset(time.Date(2026, 12, 31, 23, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 31*24*time.Hour, UntilQuotaReset(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
@@ -35,12 +35,24 @@ type AuthContext struct {
 // UserContextMiddleware. It never returns nil — fields inside the struct may
 // be nil when the middleware has not populated them.
 func AuthFromRequest(r *http.Request) *AuthContext {
-	if auth, ok := r.Context().Value(authKey{}).(*AuthContext); ok {
+	return AuthFromContext(r.Context())
+}
+
+// AuthFromContext returns the AuthContext stored in ctx by
+// UserContextMiddleware or GRPCAuthInterceptor. It never returns nil.
+func AuthFromContext(ctx context.Context) *AuthContext {
+	if auth, ok := ctx.Value(authKey{}).(*AuthContext); ok {
 		return auth
 	}
 	return &AuthContext{}
 }
 
+// ContextWithAuth returns a copy of ctx carrying auth. It is intended for use
+// in tests.
+func ContextWithAuth(ctx context.Context, auth *AuthContext) context.Context {
+	return context.WithValue(ctx, authKey{}, auth)
+}
+
 // WithAuthContext returns a shallow copy of r with the given AuthContext stored
 // in its context. It is intended for use in tests.
 func WithAuthContext(r *http.Request, auth *AuthContext) *http.Request {
@@ -50,70 +62,104 @@ func WithAuthContext(r *http.Request, auth *AuthContext) *http.Request {
 // UserContextMiddleware resolves the authenticated user, tenant, provider, and
 // client from the JWT claims already stored by JWTAuthMiddleware, populates an
 // AuthContext, and stores it in the request context for downstream handlers.
+// The request is counted against the request quota of the user's tenant.
+// Requests authenticated by PersonalAccessTokenMiddleware or
+// APIKeyAuthMiddleware already carry an AuthContext and pass through.
 func UserContextMiddleware(
 	userProvider UserContextProvider,
 	appCache *cache.Cache,
 ) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if PersonalAccessTokenFromRequest(r) != nil || APIKeyFromRequest(r) != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			var sub, clientID string
 			if c := JWTClaimsFromRequest(r); c != nil {
 				sub, clientID = c.Sub, c.ClientID
 			}
 
 			ctx := r.Context()
-
-			// Try cache first
-			if uc := appCache.GetUserContext(ctx, sub, clientID); uc != nil {
-				auth := &AuthContext{
-					User:     uc.User,
-					Tenant:   uc.Tenant,
-					Provider: uc.Provider,
-					Client:   uc.Client,
-				}
-				next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, authKey{}, auth)))
-				return
-			}
-
-			// Cache miss — load from database
-			user, err := userProvider.FindBySubAndClientID(ctx, sub, clientID)
+			auth, err := resolveAuthContext(ctx, userProvider, appCache, sub, clientID)
 			if err != nil {
 				resp.Error(w, http.StatusInternalServerError, "Failed to load user from database")
 				return
 			}
-			if user == nil {
+			if auth == nil {
 				resp.Error(w, http.StatusUnauthorized, "User not found")
 				return
 			}
-
-			// Extract tenant from user identities matching the current client.
-			var tenant *model.Tenant
-			var provider *model.IdentityProvider
-			var client *model.Client
-
-			for _, identity := range user.UserIdentities {
-				if identity.Client != nil && identity.Client.Identifier != nil && *identity.Client.Identifier == clientID {
-					if identity.Tenant != nil {
-						tenant = identity.Tenant
-					}
-				}
-			}
-
-			// Write through to cache
-			appCache.SetUserContext(ctx, sub, clientID, &cache.UserContext{
-				User:     user,
-				Tenant:   tenant,
-				Provider: provider,
-				Client:   client,
-			})
-
-			auth := &AuthContext{
-				User:     user,
-				Tenant:   tenant,
-				Provider: provider,
-				Client:   client,
+			if auth.Tenant != nil && !meterRequest(w, r, auth.Tenant.TenantID, 0) {
+				return
 			}
 			next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, authKey{}, auth)))
 		})
 	}
 }
+
+// resolveAuthContext loads the user a token was issued to, from the cache or
+// the database, together with the tenant, client and identity provider of the
+// user's identity for the token's client. It returns nil when the user does not exist.
+func resolveAuthContext(ctx context.Context, userProvider UserContextProvider, appCache *cache.Cache, sub, clientID string) (*AuthContext, error) {
+	// Try cache first
+	if uc := appCache.GetUserContext(ctx, sub, clientID); uc != nil {
+		traceTenant(ctx, uc.Tenant, clientID)
+		return &AuthContext{
+			User:     uc.User,
+			Tenant:   uc.Tenant,
+			Provider: uc.Provider,
+			Client:   uc.Client,
+		}, nil
+	}
+
+	// Cache miss — load from database
+	user, err := userProvider.FindBySubAndClientID(ctx, sub, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, nil
+	}
+
+	// Extract the tenant, client and provider of the identity for the current client.
+	var tenant *model.Tenant
+	var provider *model.IdentityProvider
+	var client *model.Client
+
+	for _, identity := range user.UserIdentities {
+		if identity.Client != nil && identity.Client.Identifier != nil && *identity.Client.Identifier == clientID {
+			if identity.Tenant != nil {
+				tenant = identity.Tenant
+			}
+			client = identity.Client
+			provider = identity.Client.IdentityProvider
+		}
+	}
+
+	// Write through to cache
+	appCache.SetUserContext(ctx, sub, clientID, &cache.UserContext{
+		User:     user,
+		Tenant:   tenant,
+		Provider: provider,
+		Client:   client,
+	})
+
+	traceTenant(ctx, tenant, clientID)
+	return &AuthContext{
+		User:     user,
+		Tenant:   tenant,
+		Provider: provider,
+		Client:   client,
+	}, nil
+}
+
+// traceTenant hands the resolved tenant and client to BodyTraceMiddleware.
+func traceTenant(ctx context.Context, tenant *model.Tenant, clientID string) {
+	var tenantUUID string
+	if tenant != nil {
+		tenantUUID = tenant.TenantUUID.String()
+	}
+	setTraceScope(ctx, tenantUUID, clientID)
+}
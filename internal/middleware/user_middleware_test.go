@@ -197,3 +197,41 @@ func TestUserContextMiddleware_IdentityFiltering(t *testing.T) {
 	require.NotNil(t, capturedTenant)
 	assert.Equal(t, tenantUUID, capturedTenant.TenantUUID)
 }
+
+// TestUserContextMiddleware_ClientPermissions checks that the permissions
+// granted to the token's client, loaded with the user, cap the user's own.
+func TestUserContextMiddleware_ClientPermissions(t *testing.T) {
+	const sub = "user-sub-client"
+	const clientID = "capped-client"
+
+	cID := clientID
+	otherID := "other-client"
+	user := userWithPermissions("user:read", "user:update")
+	user.UserIdentities = []model.UserIdentity{
+		{Client: &model.Client{Identifier: &otherID}},
+		{Client: &model.Client{Identifier: &cID, ClientAPIs: &[]model.ClientAPI{{
+			Permissions: []model.ClientPermission{{Permission: &model.Permission{Name: "user:read"}}},
+		}}}},
+	}
+
+	_, redisCli := newMiniredisClient(t)
+	repo := &mockContextProvider{findFn: func(_, _ string) (*model.User, error) { return user, nil }}
+	handler := UserContextMiddleware(repo, cache.New(redisCli))
+
+	serve := func(required string) int {
+		req := withJWTContext(httptest.NewRequest(http.MethodGet, "/", nil), sub, clientID)
+		rr := httptest.NewRecorder()
+		handler(PermissionMiddleware([]string{required})(okHandler())).ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve("user:read"))
+	assert.Equal(t, http.StatusForbidden, serve("user:update"), "not granted to the client")
+
+	// Later requests are served from the cache, which keeps the client.
+	repo.findFn = func(_, _ string) (*model.User, error) {
+		t.Fatal("user should come from the cache")
+		return nil, nil
+	}
+	assert.Equal(t, http.StatusForbidden, serve("user:update"))
+}
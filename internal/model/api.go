@@ -22,6 +22,9 @@ type API struct {
 	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
 
+	// Lifecycle
+	Deprecation `gorm:"embedded"`
+
 	// Relationships
 	Service *Service `gorm:"foreignKey:ServiceID;references:ServiceID"`
 }
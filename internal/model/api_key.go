@@ -19,12 +19,15 @@ type APIKey struct {
 	Config      datatypes.JSON `gorm:"column:config"`
 	ExpiresAt   *time.Time     `gorm:"column:expires_at"`
 
-	RateLimit *int      `gorm:"column:rate_limit"`
-	Status    string    `gorm:"column:status;default:'active'"`
-	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+	RateLimit  *int       `gorm:"column:rate_limit"`
+	Status     string     `gorm:"column:status;default:'active'"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at"`
+	UsageCount int64      `gorm:"column:usage_count;default:0"`
+	CreatedAt  time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at;autoUpdateTime"`
 
 	// Relationships
+	Tenant     *Tenant     `gorm:"foreignKey:TenantID;references:TenantID"`
 	APIKeyAPIs []APIKeyAPI `gorm:"foreignKey:APIKeyID;references:APIKeyID"`
 }
 
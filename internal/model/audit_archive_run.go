@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Audit archive run status constants (AuditArchiveRun.Status).
+const (
+	AuditArchiveStatusRunning   = "running"
+	AuditArchiveStatusCompleted = "completed"
+	AuditArchiveStatusFailed    = "failed"
+)
+
+// Audit archive run trigger constants (AuditArchiveRun.Trigger).
+const (
+	AuditArchiveTriggerScheduled = "scheduled"
+	AuditArchiveTriggerManual    = "manual"
+)
+
+// AuditArchiveRun is one pass of the auth event retention job over a tenant
+// that archives its events. Events older than Cutoff are written to
+// ObjectKey in the archive store and then deleted; a failed run deletes
+// nothing.
+type AuditArchiveRun struct {
+	AuditArchiveRunID   int64      `gorm:"column:audit_archive_run_id;primaryKey"`
+	AuditArchiveRunUUID uuid.UUID  `gorm:"column:audit_archive_run_uuid;unique"`
+	TenantID            int64      `gorm:"column:tenant_id;not null"`
+	Trigger             string     `gorm:"column:trigger_type;not null"`
+	Status              string     `gorm:"column:status;default:'running'"`
+	Cutoff              time.Time  `gorm:"column:cutoff;not null"`
+	EventCount          int64      `gorm:"column:event_count"`
+	ObjectKey           *string    `gorm:"column:object_key"`
+	SizeBytes           int64      `gorm:"column:size_bytes"`
+	Error               *string    `gorm:"column:error"`
+	CompletedAt         *time.Time `gorm:"column:completed_at"`
+	CreatedAt           time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt           time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (AuditArchiveRun) TableName() string {
+	return "audit_archive_runs"
+}
+
+func (r *AuditArchiveRun) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.AuditArchiveRunUUID == uuid.Nil {
+		r.AuditArchiveRunUUID = uuid.New()
+	}
+	if r.Status == "" {
+		r.Status = AuditArchiveStatusRunning
+	}
+	return
+}
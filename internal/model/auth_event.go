@@ -44,6 +44,9 @@ const (
 	AuthEventTypeTokenReuse            = "authn_token_reuse"
 	AuthEventTypeTokenDelete           = "authn_token_delete"
 	AuthEventTypeImpossibleTravel      = "authn_impossible_travel"
+	AuthEventTypeNewDevice             = "authn_new_device"
+	AuthEventTypeDeviceTrusted         = "authn_device_trusted"
+	AuthEventTypeDeviceRevoked         = "authn_device_revoked"
 	AuthEventTypeOAuthAuthorize        = "authn_oauth_authorize"
 	AuthEventTypeOAuthConsent          = "authn_oauth_consent"
 	AuthEventTypeOAuthConsentDeny      = "authn_oauth_consent_deny"
@@ -52,13 +55,32 @@ const (
 	AuthEventTypeOAuthTokenRevoke      = "authn_oauth_token_revoke"
 	AuthEventTypeOAuthClientAuth       = "authn_oauth_client_auth"
 	AuthEventTypeOAuthClientAuthFail   = "authn_oauth_client_auth_fail"
+	AuthEventTypeOAuthDeprecatedUse    = "authn_oauth_deprecated_use"
+	AuthEventTypeOAuthSunsetBlocked    = "authn_oauth_sunset_blocked"
+	AuthEventTypeHookExecuted          = "authn_hook_executed"
+	AuthEventTypeHookFail              = "authn_hook_fail"
+	AuthEventTypeImpersonate           = "authn_impersonate"
+	AuthEventTypeReauthenticate        = "authn_reauthenticate"
+	AuthEventTypeReauthenticateFail    = "authn_reauthenticate_fail"
+	AuthEventTypeRecoveryCodesIssued   = "authn_recovery_codes_issued"
+	AuthEventTypeRecoveryEmailChange   = "authn_recovery_email_change"
+	AuthEventTypeRecoveryStart         = "authn_recovery_start"
+	AuthEventTypeRecoverySuccess       = "authn_recovery_success"
+	AuthEventTypeRecoveryFail          = "authn_recovery_fail"
+	AuthEventTypeIPBlocked             = "authn_ip_blocked"
 )
 
 // OWASP Logging Vocabulary event type constants for the AUTHZ category.
 const (
+	AuthEventTypeAuthzAllow  = "authz_allow"
 	AuthEventTypeAuthzFail   = "authz_fail"
 	AuthEventTypeAuthzChange = "authz_change"
 	AuthEventTypeAuthzAdmin  = "authz_admin"
+
+	AuthEventTypeSecretReveal = "authz_secret_reveal"
+
+	AuthEventTypeStepUpGranted  = "authz_step_up_granted"
+	AuthEventTypeStepUpRequired = "authz_step_up_required"
 )
 
 // OWASP Logging Vocabulary event type constants for the SESSION category.
@@ -67,6 +89,7 @@ const (
 	AuthEventTypeSessionRenewed        = "session_renewed"
 	AuthEventTypeSessionExpired        = "session_expired"
 	AuthEventTypeSessionUseAfterExpire = "session_use_after_expire"
+	AuthEventTypeSessionLimitReached   = "session_limit_reached"
 )
 
 // OWASP Logging Vocabulary event type constants for the USER category.
@@ -75,6 +98,15 @@ const (
 	AuthEventTypeUserUpdated  = "user_updated"
 	AuthEventTypeUserArchived = "user_archived"
 	AuthEventTypeUserDeleted  = "user_deleted"
+	AuthEventTypeUserRestored = "user_restored"
+	AuthEventTypeUserDisabled = "user_disabled"
+	AuthEventTypeUserEnabled  = "user_enabled"
+
+	AuthEventTypeUserSecurityHold        = "user_security_hold"
+	AuthEventTypeUserSecurityHoldCleared = "user_security_hold_cleared"
+
+	AuthEventTypeUserIdentityLinked   = "user_identity_linked"
+	AuthEventTypeUserIdentityUnlinked = "user_identity_unlinked"
 )
 
 // OWASP Logging Vocabulary event type constants for the PRIVILEGE category.
@@ -84,30 +116,36 @@ const (
 
 // OWASP Logging Vocabulary event type constants for the SYSTEM category.
 const (
-	AuthEventTypeSystemStartup  = "sys_startup"
-	AuthEventTypeSystemShutdown = "sys_shutdown"
-	AuthEventTypeSystemCrash    = "sys_crash"
+	AuthEventTypeSystemStartup      = "sys_startup"
+	AuthEventTypeSystemShutdown     = "sys_shutdown"
+	AuthEventTypeSystemCrash        = "sys_crash"
+	AuthEventTypeSystemDebug        = "sys_debug_mode"
+	AuthEventTypeSystemConfigReload = "sys_config_reload"
 )
 
 // AuthEvent represents a security event stored in the auth_events table.
 // Events are immutable (append-only) following OWASP tamper-protection guidance.
 type AuthEvent struct {
-	AuthEventID   int64          `gorm:"column:auth_event_id;primaryKey;autoIncrement"`
-	AuthEventUUID uuid.UUID      `gorm:"column:auth_event_uuid;type:uuid;uniqueIndex;not null"`
-	TenantID      int64          `gorm:"column:tenant_id;not null"`
-	ActorUserID   *int64         `gorm:"column:actor_user_id"`
-	TargetUserID  *int64         `gorm:"column:target_user_id"`
-	IPAddress     string         `gorm:"column:ip_address;type:varchar(45);not null"`
-	UserAgent     *string        `gorm:"column:user_agent;type:text"`
-	Category      string         `gorm:"column:category;type:varchar(20);not null"`
-	EventType     string         `gorm:"column:event_type;type:varchar(60);not null"`
-	Severity      string         `gorm:"column:severity;type:varchar(10);not null;default:INFO"`
-	Result        string         `gorm:"column:result;type:varchar(10);not null"`
-	Description   *string        `gorm:"column:description;type:text"`
-	ErrorReason   *string        `gorm:"column:error_reason;type:varchar(255)"`
-	TraceID       *string        `gorm:"column:trace_id;type:varchar(32)"`
-	Metadata      datatypes.JSON `gorm:"column:metadata;type:jsonb;default:'{}'"`
-	CreatedAt     time.Time      `gorm:"column:created_at;autoCreateTime;not null"`
+	AuthEventID    int64          `gorm:"column:auth_event_id;primaryKey;autoIncrement"`
+	AuthEventUUID  uuid.UUID      `gorm:"column:auth_event_uuid;type:uuid;uniqueIndex;not null"`
+	TenantID       int64          `gorm:"column:tenant_id;not null"`
+	ActorUserID    *int64         `gorm:"column:actor_user_id"`
+	TargetUserID   *int64         `gorm:"column:target_user_id"`
+	IPAddress      string         `gorm:"column:ip_address;type:varchar(45);not null"`
+	UserAgent      *string        `gorm:"column:user_agent;type:text"`
+	Country        *string        `gorm:"column:country;type:varchar(2)"`
+	City           *string        `gorm:"column:city;type:varchar(255)"`
+	ASN            *int64         `gorm:"column:asn"`
+	ASOrganization *string        `gorm:"column:as_organization;type:varchar(255)"`
+	Category       string         `gorm:"column:category;type:varchar(20);not null"`
+	EventType      string         `gorm:"column:event_type;type:varchar(60);not null"`
+	Severity       string         `gorm:"column:severity;type:varchar(10);not null;default:INFO"`
+	Result         string         `gorm:"column:result;type:varchar(10);not null"`
+	Description    *string        `gorm:"column:description;type:text"`
+	ErrorReason    *string        `gorm:"column:error_reason;type:varchar(255)"`
+	TraceID        *string        `gorm:"column:trace_id;type:varchar(32)"`
+	Metadata       datatypes.JSON `gorm:"column:metadata;type:jsonb;default:'{}'"`
+	CreatedAt      time.Time      `gorm:"column:created_at;autoCreateTime;not null"`
 
 	// Relationships
 	Tenant     *Tenant `gorm:"foreignKey:TenantID;references:TenantID"`
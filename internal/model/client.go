@@ -1,6 +1,7 @@
 package model
 
 import (
+	"crypto/subtle"
 	"time"
 
 	"github.com/google/uuid"
@@ -40,7 +41,7 @@ type Client struct {
 	ClientType         string         `gorm:"column:client_type"`
 	Domain             *string        `gorm:"column:domain"`
 	Identifier         *string        `gorm:"column:identifier"`
-	Secret             *string        `gorm:"column:secret"`
+	Secret             *string        `gorm:"column:secret;serializer:encrypted"`
 	Config             datatypes.JSON `gorm:"column:config"`
 	Status             string         `gorm:"column:status;default:'inactive'"`
 	IsDefault          bool           `gorm:"column:is_default;default:false"`
@@ -56,6 +57,15 @@ type Client struct {
 	RefreshTokenTTL         *int           `gorm:"column:refresh_token_ttl"`
 	RequireConsent          bool           `gorm:"column:require_consent;default:true"`
 
+	// Secret rotation. After a rotation PreviousSecret still authenticates
+	// the client until PreviousSecretExpiresAt.
+	PreviousSecret          *string    `gorm:"column:previous_secret;serializer:encrypted"`
+	PreviousSecretExpiresAt *time.Time `gorm:"column:previous_secret_expires_at"`
+	SecretRotatedAt         *time.Time `gorm:"column:secret_rotated_at"`
+
+	// Lifecycle
+	Deprecation `gorm:"embedded"`
+
 	// Relationships
 	IdentityProvider *IdentityProvider `gorm:"foreignKey:IdentityProviderID;references:IdentityProviderID"`
 	ClientURIs       *[]ClientURI      `gorm:"foreignKey:ClientID;references:ClientID"`
@@ -72,3 +82,18 @@ func (ac *Client) BeforeCreate(tx *gorm.DB) (err error) {
 	}
 	return
 }
+
+// MatchesSecret reports whether secret is the client's current secret, or
+// its previous secret while the rotation grace period is still running at
+// now. Comparisons are constant time.
+func (ac *Client) MatchesSecret(secret string, now time.Time) bool {
+	if secret == "" {
+		return false
+	}
+	if ac.Secret != nil && subtle.ConstantTimeCompare([]byte(secret), []byte(*ac.Secret)) == 1 {
+		return true
+	}
+	return ac.PreviousSecret != nil &&
+		ac.PreviousSecretExpiresAt != nil && now.Before(*ac.PreviousSecretExpiresAt) &&
+		subtle.ConstantTimeCompare([]byte(secret), []byte(*ac.PreviousSecret)) == 1
+}
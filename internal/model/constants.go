@@ -10,6 +10,25 @@ const (
 	StatusPending   = "pending"
 	StatusSuspended = "suspended"
 
+	// User-specific statuses (User.Status). Deleted users are kept until the
+	// tenant's retention period ends, then purged. Disabled users turned off
+	// their own account; they keep their data and can reactivate it.
+	StatusDeleted  = "deleted"
+	StatusDisabled = "disabled"
+
+	// StatusPendingConsent marks an under-age user whose account waits for
+	// a guardian to approve it (User.Status).
+	StatusPendingConsent = "pending_consent"
+
+	// StatusSecurityHold marks a user whose account looks compromised
+	// (User.Status). Sign in is refused until the user recovers the account
+	// or an admin reviews and re-enables it.
+	StatusSecurityHold = "security_hold"
+
+	// Tenant-specific statuses (Tenant.Status). Archived tenants keep their
+	// data but are private and their clients are disabled.
+	StatusArchived = "archived"
+
 	// Service-specific statuses
 	StatusMaintenance = "maintenance"
 	StatusDeprecated  = "deprecated"
@@ -26,6 +45,120 @@ const (
 	// Token types (UserToken.TokenType)
 	TokenTypeEmailVerification = "user:email:verification"
 	TokenTypePasswordReset     = "user:password:reset"
+	TokenTypeAccountDeletion   = "user:account:deletion"
+	TokenTypeAccountReactivate = "user:account:reactivation"
+	TokenTypeRecoveryEmail     = "user:recovery-email:verification"
+	TokenTypeAccountRecovery   = "user:account:recovery"
+
+	// User metadata namespace reserved for system-managed keys (User.Metadata).
+	// Top-level keys equal to the namespace or prefixed with it followed by a
+	// dot cannot be written through the admin API.
+	UserMetadataProtectedNamespace = "mdauth"
+
+	// Tenant feature flag (TenantSetting.FeatureFlags) that stores sensitive
+	// profile fields encrypted. Has no effect unless a profile encryption key
+	// is configured.
+	FeatureFlagProfileEncryption = "profile_encryption"
+
+	// Tenant feature flag (TenantSetting.FeatureFlags) that rejects passwords
+	// found in known data breaches during registration and password changes.
+	// Has no effect unless a breached password checker is configured.
+	FeatureFlagBreachedPasswordCheck = "breached_password_check"
+
+	// Tenant feature flag (TenantSetting.FeatureFlags) that makes login,
+	// password reset and registration answer alike whether or not an
+	// account exists.
+	FeatureFlagEnumerationSafe = "enumeration_safe"
+
+	// Tenant feature flag (TenantSetting.FeatureFlags) that forwards the
+	// tenant's security events to the SIEM. Has no effect unless a SIEM is
+	// configured.
+	FeatureFlagSIEMForwarding = "siem_forwarding"
+
+	// Tenant audit setting (TenantSetting.AuditConfig) holding how many days a
+	// deleted user is kept, and can be restored, before it is purged.
+	AuditConfigDeletedUserRetentionDays = "deleted_user_retention_days"
+
+	// Tenant audit setting (TenantSetting.AuditConfig) holding how many days
+	// an account deleted by its owner can still be recovered before it is
+	// purged. Takes the place of deleted_user_retention_days for such users.
+	AuditConfigAccountDeletionGraceDays = "account_deletion_grace_days"
+
+	// Tenant audit setting (TenantSetting.AuditConfig) holding how many days
+	// auth events are kept before the retention job removes them.
+	AuditConfigAuthEventRetentionDays = "auth_event_retention_days"
+
+	// Tenant audit setting (TenantSetting.AuditConfig) that makes the
+	// retention job archive expired auth events to object storage before
+	// deleting them.
+	AuditConfigAuthEventArchive = "auth_event_archive"
+
+	// Tenant rate limit settings (TenantSetting.RateLimitConfig) capping the
+	// requests, and the access tokens issued, per calendar month (UTC), in
+	// total and per API key. Missing or 0 is unlimited.
+	RateLimitConfigMonthlyRequestQuota       = "monthly_request_quota"
+	RateLimitConfigMonthlyTokenQuota         = "monthly_token_quota"
+	RateLimitConfigAPIKeyMonthlyRequestQuota = "api_key_monthly_request_quota"
+
+	// Security password settings (SecuritySetting.PasswordConfig) choosing
+	// the algorithm, "bcrypt" or "argon2id", and cost new password hashes are
+	// computed with. Existing hashes are upgraded on the next sign in.
+	PasswordConfigHashAlgorithm      = "hash_algorithm"
+	PasswordConfigBcryptCost         = "bcrypt_cost"
+	PasswordConfigArgon2idMemoryKiB  = "argon2id_memory_kib"
+	PasswordConfigArgon2idIterations = "argon2id_iterations"
+	PasswordConfigArgon2idThreads    = "argon2id_parallelism"
+
+	// Security password settings (SecuritySetting.PasswordConfig) making up
+	// the password policy new passwords must meet. Unset fields keep the
+	// defaults of security.DefaultPasswordPolicy.
+	PasswordConfigMinLength        = "min_length"
+	PasswordConfigMaxLength        = "max_length"
+	PasswordConfigRequireUppercase = "require_uppercase"
+	PasswordConfigRequireLowercase = "require_lowercase"
+	PasswordConfigRequireNumber    = "require_number"
+	PasswordConfigRequireSymbol    = "require_symbol"
+
+	// Security threat settings (SecuritySetting.ThreatConfig) for login
+	// anomaly detection. New devices are emailed to the user, logins that
+	// would need travel faster than max_travel_speed_kmh are flagged, and
+	// either can require step-up authentication. Impossible travel can
+	// instead put the account on security hold.
+	ThreatConfigNewDeviceNotification          = "new_device_notification_enabled"
+	ThreatConfigImpossibleTravel               = "impossible_travel_detection_enabled"
+	ThreatConfigMaxTravelSpeedKmh              = "max_travel_speed_kmh"
+	ThreatConfigRiskBasedStepUp                = "risk_based_step_up_enabled"
+	ThreatConfigSecurityHoldOnImpossibleTravel = "security_hold_on_impossible_travel_enabled"
+
+	// Security threat settings (SecuritySetting.ThreatConfig) for brute-force
+	// detection. Failed logins are counted per /24 subnet and, for credential
+	// stuffing, as distinct usernames per IP address; crossing either
+	// threshold blocks the subnet or address for ip_block_duration_minutes.
+	ThreatConfigBruteForceDetection         = "brute_force_detection_enabled"
+	ThreatConfigSubnetFailureThreshold      = "subnet_failure_threshold"
+	ThreatConfigCredentialStuffingThreshold = "credential_stuffing_username_threshold"
+	ThreatConfigIPBlockDurationMinutes      = "ip_block_duration_minutes"
+
+	// MFA settings (SecuritySetting.MFAConfig). Users may remember a device
+	// for trusted_device_period_days; remembering devices is off unless it
+	// is positive.
+	MFAConfigTrustedDevicePeriodDays = "trusted_device_period_days"
+
+	// Session settings (SecuritySetting.SessionConfig). A user may hold up
+	// to max_concurrent_sessions OAuth sessions, 0 for no limit. A sign-in
+	// beyond the limit is handled by session_limit_policy. A session ends
+	// when it is not refreshed for idle_timeout_minutes or once it is
+	// absolute_timeout_hours old; unset or 0 disables either timeout.
+	SessionConfigMaxConcurrentSessions = "max_concurrent_sessions"
+	SessionConfigLimitPolicy           = "session_limit_policy"
+	SessionConfigIdleTimeoutMinutes    = "idle_timeout_minutes"
+	SessionConfigAbsoluteTimeoutHours  = "absolute_timeout_hours"
+
+	// Session limit policies (SessionConfigLimitPolicy). reject_new refuses
+	// the sign-in; terminate_oldest ends the user's oldest session to make
+	// room for it.
+	SessionLimitPolicyRejectNew       = "reject_new"
+	SessionLimitPolicyTerminateOldest = "terminate_oldest"
 
 	// Role names (Role.Name) — system-defined roles
 	RoleSuperAdmin = "super-admin"
@@ -38,11 +171,13 @@ const (
 	ClientTypeM2M         = "m2m"
 
 	// Client URI types (ClientURI.Type)
-	ClientURITypeRedirect   = "redirect-uri"
-	ClientURITypeOrigin     = "origin-uri"
-	ClientURITypeLogout     = "logout-uri"
-	ClientURITypeLogin      = "login-uri"
-	ClientURITypeCORSOrigin = "cors-origin-uri"
+	ClientURITypeRedirect           = "redirect-uri"
+	ClientURITypeOrigin             = "origin-uri"
+	ClientURITypeLogout             = "logout-uri"
+	ClientURITypeLogin              = "login-uri"
+	ClientURITypeCORSOrigin         = "cors-origin-uri"
+	ClientURITypeBackChannelLogout  = "backchannel-logout-uri"
+	ClientURITypeFrontChannelLogout = "frontchannel-logout-uri"
 
 	// API types (API.APIType)
 	APITypeRest      = "rest"
@@ -64,6 +199,17 @@ const (
 	ContactMethodPhone = "phone"
 	ContactMethodSMS   = "sms"
 
+	// Themes (UserSetting.Theme)
+	ThemeLight  = "light"
+	ThemeDark   = "dark"
+	ThemeSystem = "system"
+
+	// Date formats (UserSetting.DateFormat)
+	DateFormatISO    = "YYYY-MM-DD"
+	DateFormatDMY    = "DD/MM/YYYY"
+	DateFormatMDY    = "MM/DD/YYYY"
+	DateFormatDMYDot = "DD.MM.YYYY"
+
 	// Identity provider names (UserIdentity.Provider)
 	ProviderDefault = "default"
 
@@ -89,6 +235,11 @@ const (
 	IPRuleTypeWhitelist = "whitelist"
 	IPRuleTypeBlacklist = "blacklist"
 
+	// IP restriction rule sources (IPRestrictionRule.Source). Automatic rules
+	// are temporary blocks placed by brute-force detection.
+	IPRuleSourceManual    = "manual"
+	IPRuleSourceAutomatic = "automatic"
+
 	// Login template styles (LoginTemplate.Template)
 	LoginTemplateModern    = "modern"
 	LoginTemplateClassic   = "classic"
@@ -100,4 +251,18 @@ const (
 	// Policy statement effects (PolicyStatement.Effect)
 	PolicyEffectAllow = "allow"
 	PolicyEffectDeny  = "deny"
+
+	// Login hook triggers (LoginHook.Trigger)
+	LoginHookTriggerPreLogin        = "pre_login"
+	LoginHookTriggerPostLogin       = "post_login"
+	LoginHookTriggerPreRegistration = "pre_registration"
+
+	// Login hook implementations (LoginHook.Type)
+	LoginHookTypeWebhook = "webhook"
+	LoginHookTypeRule    = "rule"
+
+	// Login hook failure policies (LoginHook.FailurePolicy) applied when a
+	// webhook times out or returns an invalid response
+	LoginHookFailOpen   = "fail_open"
+	LoginHookFailClosed = "fail_closed"
 )
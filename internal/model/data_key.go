@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	// Registers the "encrypted" serializer sensitive columns are tagged with.
+	_ "github.com/maintainerd/auth/internal/crypto"
+)
+
+// Data key status constants (DataKey.Status).
+const (
+	DataKeyStatusActive   = "active"
+	DataKeyStatusPrevious = "previous"
+)
+
+// DataKey is an AES key sensitive columns are encrypted with. WrappedKey
+// holds the key encrypted by the key-encryption key, so the table alone does
+// not reveal it. Exactly one key is active and encrypts new values; previous
+// keys keep decrypting the values they sealed.
+type DataKey struct {
+	DataKeyID   int64      `gorm:"column:data_key_id;primaryKey"`
+	DataKeyUUID uuid.UUID  `gorm:"column:data_key_uuid;unique"`
+	KeyID       string     `gorm:"column:key_id;unique"`
+	WrappedKey  string     `gorm:"column:wrapped_key"`
+	Status      string     `gorm:"column:status;default:'active'"`
+	ActivatedAt time.Time  `gorm:"column:activated_at"`
+	RotatedAt   *time.Time `gorm:"column:rotated_at"`
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (DataKey) TableName() string {
+	return "data_keys"
+}
+
+func (k *DataKey) BeforeCreate(tx *gorm.DB) (err error) {
+	if k.DataKeyUUID == uuid.Nil {
+		k.DataKeyUUID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Deprecation holds the deprecation and sunset lifecycle columns shared by
+// clients and APIs. Once DeprecatedAt is set, token issuance continues but
+// callers are warned; once SunsetAt has passed, issuance is refused.
+type Deprecation struct {
+	DeprecatedAt         *time.Time `gorm:"column:deprecated_at"`
+	SunsetAt             *time.Time `gorm:"column:sunset_at"`
+	DeprecationNotice    *string    `gorm:"column:deprecation_notice"`
+	DeprecatedUsageCount int64      `gorm:"column:deprecated_usage_count;default:0"`
+	LastDeprecatedUseAt  *time.Time `gorm:"column:last_deprecated_use_at"`
+}
+
+// IsDeprecated reports whether the record has been marked as deprecated.
+func (d Deprecation) IsDeprecated() bool {
+	return d.DeprecatedAt != nil
+}
+
+// IsSunset reports whether the record is deprecated and its sunset date has
+// been reached at the given time.
+func (d Deprecation) IsSunset(now time.Time) bool {
+	return d.DeprecatedAt != nil && d.SunsetAt != nil && !now.Before(*d.SunsetAt)
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// EventSchemaVersion is the payload schema version written with new events.
+// Bump it whenever an event payload changes shape so consumers can branch on
+// the version they receive.
+const EventSchemaVersion = 1
+
+// Event aggregate types (Event.AggregateType).
+const (
+	EventAggregateUser   = "user"
+	EventAggregateRole   = "role"
+	EventAggregateClient = "client"
+
+	EventAggregateSignupFlow = "signup_flow"
+	EventAggregateGroup      = "group"
+)
+
+// Lifecycle event types (Event.EventType). Entity events carry a full snapshot
+// of the aggregate; relationship events carry only the UUIDs that changed.
+const (
+	EventTypeUserCreated      = "user.created"
+	EventTypeUserUpdated      = "user.updated"
+	EventTypeUserDeleted      = "user.deleted"
+	EventTypeUserRestored     = "user.restored"
+	EventTypeUserPurged       = "user.purged"
+	EventTypeUserRolesAdded   = "user.roles_added"
+	EventTypeUserRolesRemoved = "user.roles_removed"
+
+	EventTypeUserScopedRoleAdded   = "user.scoped_role_added"
+	EventTypeUserScopedRoleRemoved = "user.scoped_role_removed"
+
+	EventTypeRoleCreated            = "role.created"
+	EventTypeRoleUpdated            = "role.updated"
+	EventTypeRoleDeleted            = "role.deleted"
+	EventTypeRolePermissionsAdded   = "role.permissions_added"
+	EventTypeRolePermissionsRemoved = "role.permissions_removed"
+
+	EventTypeClientCreated = "client.created"
+	EventTypeClientUpdated = "client.updated"
+	EventTypeClientDeleted = "client.deleted"
+
+	EventTypeClientSecretRotated = "client.secret_rotated"
+
+	EventTypeAPIKeyCreated = "api_key.created"
+	EventTypeAPIKeyUpdated = "api_key.updated"
+	EventTypeAPIKeyRevoked = "api_key.revoked"
+	EventTypeAPIKeyDeleted = "api_key.deleted"
+
+	EventTypeGroupCreated        = "group.created"
+	EventTypeGroupUpdated        = "group.updated"
+	EventTypeGroupDeleted        = "group.deleted"
+	EventTypeGroupMembersAdded   = "group.members_added"
+	EventTypeGroupMembersRemoved = "group.members_removed"
+	EventTypeGroupRolesAdded     = "group.roles_added"
+	EventTypeGroupRolesRemoved   = "group.roles_removed"
+
+	EventTypeSignupFlowCapWarning = "signup_flow.cap_warning"
+	EventTypeSignupFlowCapReached = "signup_flow.cap_reached"
+)
+
+// EventTypes lists every lifecycle event type in the feed.
+var EventTypes = []string{
+	EventTypeUserCreated, EventTypeUserUpdated, EventTypeUserDeleted,
+	EventTypeUserRestored, EventTypeUserPurged,
+	EventTypeUserRolesAdded, EventTypeUserRolesRemoved,
+	EventTypeUserScopedRoleAdded, EventTypeUserScopedRoleRemoved,
+	EventTypeRoleCreated, EventTypeRoleUpdated, EventTypeRoleDeleted,
+	EventTypeRolePermissionsAdded, EventTypeRolePermissionsRemoved,
+	EventTypeClientCreated, EventTypeClientUpdated, EventTypeClientDeleted,
+	EventTypeClientSecretRotated,
+	EventTypeAPIKeyCreated, EventTypeAPIKeyUpdated, EventTypeAPIKeyRevoked,
+	EventTypeAPIKeyDeleted,
+	EventTypeGroupCreated, EventTypeGroupUpdated, EventTypeGroupDeleted,
+	EventTypeGroupMembersAdded, EventTypeGroupMembersRemoved,
+	EventTypeGroupRolesAdded, EventTypeGroupRolesRemoved,
+	EventTypeSignupFlowCapWarning, EventTypeSignupFlowCapReached,
+}
+
+// Event is an append-only lifecycle record used to feed downstream read
+// models. EventID is a monotonically increasing sequence and doubles as the
+// feed cursor.
+type Event struct {
+	EventID       int64          `gorm:"column:event_id;primaryKey;autoIncrement"`
+	EventUUID     uuid.UUID      `gorm:"column:event_uuid;type:uuid;uniqueIndex;not null"`
+	TenantID      int64          `gorm:"column:tenant_id;not null"`
+	EventType     string         `gorm:"column:event_type;type:varchar(60);not null"`
+	SchemaVersion int            `gorm:"column:schema_version;not null;default:1"`
+	AggregateType string         `gorm:"column:aggregate_type;type:varchar(30);not null"`
+	AggregateUUID uuid.UUID      `gorm:"column:aggregate_uuid;type:uuid;not null"`
+	Payload       datatypes.JSON `gorm:"column:payload;type:jsonb;default:'{}'"`
+	OccurredAt    time.Time      `gorm:"column:occurred_at;autoCreateTime;not null"`
+
+	// Relationships
+	Tenant *Tenant `gorm:"foreignKey:TenantID;references:TenantID"`
+}
+
+// TableName returns the database table name for GORM.
+func (Event) TableName() string {
+	return "events"
+}
+
+// BeforeCreate generates a UUID if one is not already set.
+func (e *Event) BeforeCreate(_ *gorm.DB) error {
+	if e.EventUUID == uuid.Nil {
+		e.EventUUID = uuid.New()
+	}
+	return nil
+}
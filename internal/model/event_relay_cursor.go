@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EventRelayCursor is the durable position of an event relay in the events
+// table. LastEventID is the highest event published to the event bus.
+type EventRelayCursor struct {
+	EventRelayCursorID   int64     `gorm:"column:event_relay_cursor_id;primaryKey;autoIncrement"`
+	EventRelayCursorUUID uuid.UUID `gorm:"column:event_relay_cursor_uuid;type:uuid;uniqueIndex;not null"`
+	Name                 string    `gorm:"column:name;type:varchar(100);uniqueIndex;not null"`
+	LastEventID          int64     `gorm:"column:last_event_id;not null;default:0"`
+	CreatedAt            time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt            time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName returns the database table name for GORM.
+func (EventRelayCursor) TableName() string {
+	return "event_relay_cursors"
+}
+
+// BeforeCreate generates a UUID if one is not already set.
+func (c *EventRelayCursor) BeforeCreate(_ *gorm.DB) error {
+	if c.EventRelayCursorUUID == uuid.Nil {
+		c.EventRelayCursorUUID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FeatureFlag gates an internal feature so it can be rolled out gradually
+// without a redeploy. An enabled flag is on for RolloutPercentage percent of
+// tenants, picked by a stable hash of the key and tenant. Overrides turn it
+// on or off for single tenants regardless of the rollout.
+type FeatureFlag struct {
+	FeatureFlagID     int64     `gorm:"column:feature_flag_id;primaryKey"`
+	FeatureFlagUUID   uuid.UUID `gorm:"column:feature_flag_uuid;unique"`
+	Key               string    `gorm:"column:key;unique;not null"`
+	Description       string    `gorm:"column:description"`
+	Enabled           bool      `gorm:"column:enabled;default:false"`
+	RolloutPercentage int       `gorm:"column:rollout_percentage;not null"`
+	CreatedAt         time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt         time.Time `gorm:"column:updated_at;autoUpdateTime"`
+
+	// Relationships
+	Overrides []FeatureFlagOverride `gorm:"foreignKey:FeatureFlagID;references:FeatureFlagID"`
+}
+
+func (FeatureFlag) TableName() string {
+	return "feature_flags"
+}
+
+func (f *FeatureFlag) BeforeCreate(tx *gorm.DB) (err error) {
+	if f.FeatureFlagUUID == uuid.Nil {
+		f.FeatureFlagUUID = uuid.New()
+	}
+	return
+}
+
+// FeatureFlagOverride turns a feature flag on or off for one tenant.
+type FeatureFlagOverride struct {
+	FeatureFlagOverrideID int64     `gorm:"column:feature_flag_override_id;primaryKey"`
+	FeatureFlagID         int64     `gorm:"column:feature_flag_id;not null"`
+	TenantID              int64     `gorm:"column:tenant_id;not null"`
+	Enabled               bool      `gorm:"column:enabled;default:false"`
+	CreatedAt             time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt             time.Time `gorm:"column:updated_at;autoUpdateTime"`
+
+	// Relationships
+	Tenant *Tenant `gorm:"foreignKey:TenantID;references:TenantID"`
+}
+
+func (FeatureFlagOverride) TableName() string {
+	return "feature_flag_overrides"
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Group is a named set of users in a tenant, such as "support" or
+// "engineering". Every member holds the roles attached to the group, so a
+// role can be granted to many users by adding them to a group instead of
+// assigning it to each of them.
+type Group struct {
+	GroupID     int64     `gorm:"column:group_id;primaryKey"`
+	GroupUUID   uuid.UUID `gorm:"column:group_uuid;unique"`
+	TenantID    int64     `gorm:"column:tenant_id;not null"`
+	Name        string    `gorm:"column:name"`
+	Description string    `gorm:"column:description"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
+
+	// Relationships
+	Roles []Role `gorm:"many2many:group_roles;joinForeignKey:GroupID;joinReferences:RoleID"`
+}
+
+func (Group) TableName() string {
+	return "groups"
+}
+
+func (g *Group) BeforeCreate(tx *gorm.DB) (err error) {
+	if g.GroupUUID == uuid.Nil {
+		g.GroupUUID = uuid.New()
+	}
+	return
+}
+
+// GroupMember is a row of the group_members join table.
+type GroupMember struct {
+	GroupID   int64     `gorm:"column:group_id;primaryKey"`
+	UserID    int64     `gorm:"column:user_id;primaryKey"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (GroupMember) TableName() string {
+	return "group_members"
+}
+
+// GroupRole is a row of the group_roles join table.
+type GroupRole struct {
+	GroupID   int64     `gorm:"column:group_id;primaryKey"`
+	RoleID    int64     `gorm:"column:role_id;primaryKey"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (GroupRole) TableName() string {
+	return "group_roles"
+}
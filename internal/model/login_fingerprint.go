@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LoginFingerprint is a device a user has signed in to a tenant from, with
+// where the latest sign in from it came from. Logins are compared against
+// these to spot new devices and impossible travel.
+type LoginFingerprint struct {
+	LoginFingerprintID   int64     `gorm:"column:login_fingerprint_id;primaryKey"`
+	LoginFingerprintUUID uuid.UUID `gorm:"column:login_fingerprint_uuid;unique"`
+	UserID               int64     `gorm:"column:user_id;not null"`
+	TenantID             int64     `gorm:"column:tenant_id;not null"`
+	DeviceHash           string    `gorm:"column:device_hash;not null"`
+	UserAgent            *string   `gorm:"column:user_agent"`
+	IPAddress            string    `gorm:"column:ip_address"`
+	Country              *string   `gorm:"column:country"`
+	City                 *string   `gorm:"column:city"`
+	Latitude             *float64  `gorm:"column:latitude"`
+	Longitude            *float64  `gorm:"column:longitude"`
+	ASN                  *int64    `gorm:"column:asn"`
+	ASOrganization       *string   `gorm:"column:as_organization"`
+	LoginCount           int       `gorm:"column:login_count;default:1"`
+	FirstSeenAt          time.Time `gorm:"column:first_seen_at"`
+	LastSeenAt           time.Time `gorm:"column:last_seen_at"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID;references:UserID"`
+}
+
+func (LoginFingerprint) TableName() string {
+	return "login_fingerprints"
+}
+
+func (lf *LoginFingerprint) BeforeCreate(tx *gorm.DB) (err error) {
+	if lf.LoginFingerprintUUID == uuid.Nil {
+		lf.LoginFingerprintUUID = uuid.New()
+	}
+	return
+}
+
+// HasLocation reports whether the latest sign in from the device was located.
+func (lf *LoginFingerprint) HasLocation() bool {
+	return lf.Latitude != nil && lf.Longitude != nil
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// LoginHook is a tenant-defined custom action run at a point in the login or
+// registration flow. Webhook hooks call URL; rule hooks evaluate Rule
+// in-process. Hooks for the same trigger run in ascending Priority order.
+type LoginHook struct {
+	LoginHookID     int64          `gorm:"column:login_hook_id;primaryKey;autoIncrement" json:"login_hook_id"`
+	LoginHookUUID   uuid.UUID      `gorm:"column:login_hook_uuid;type:uuid;uniqueIndex;not null" json:"login_hook_uuid"`
+	TenantID        int64          `gorm:"column:tenant_id;not null" json:"tenant_id"`
+	Name            string         `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	Trigger         string         `gorm:"column:trigger;type:varchar(30);not null" json:"trigger"`
+	Type            string         `gorm:"column:type;type:varchar(20);not null" json:"type"`
+	URL             string         `gorm:"column:url;type:text" json:"url"`
+	SecretEncrypted string         `gorm:"column:secret_encrypted;type:text;serializer:encrypted" json:"-"`
+	Rule            datatypes.JSON `gorm:"column:rule;type:jsonb" json:"rule"`
+	TimeoutMs       int            `gorm:"column:timeout_ms;not null;default:2000" json:"timeout_ms"`
+	FailurePolicy   string         `gorm:"column:failure_policy;type:varchar(20);not null;default:'fail_closed'" json:"failure_policy"`
+	Priority        int            `gorm:"column:priority;not null;default:0" json:"priority"`
+	Status          string         `gorm:"column:status;type:varchar(20);not null;default:'active'" json:"status"`
+	CreatedAt       time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Tenant *Tenant `gorm:"foreignKey:TenantID;references:TenantID"`
+}
+
+// TableName returns the database table name for LoginHook.
+func (LoginHook) TableName() string {
+	return "login_hooks"
+}
+
+// BeforeCreate sets a new UUID on the LoginHook before it is inserted into the
+// database if one has not already been assigned.
+func (lh *LoginHook) BeforeCreate(tx *gorm.DB) error {
+	if lh.LoginHookUUID == uuid.Nil {
+		lh.LoginHookUUID = uuid.New()
+	}
+	return nil
+}
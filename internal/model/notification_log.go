@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Notification delivery channels.
+const (
+	NotificationChannelEmail = "email"
+	NotificationChannelSMS   = "sms"
+	NotificationChannelInApp = "in_app"
+)
+
+// Notification delivery statuses. Skipped deliveries were wanted by the
+// user but could not be attempted, such as SMS without a provider.
+const (
+	NotificationStatusSent    = "sent"
+	NotificationStatusFailed  = "failed"
+	NotificationStatusSkipped = "skipped"
+)
+
+// Notification types sent by the service itself.
+const (
+	NotificationTypeNewDevice    = "security.new_device"
+	NotificationTypeSecurityHold = "security.hold"
+)
+
+// NotificationLog is one notification delivered, or attempted, to a user on
+// one channel. In-app notifications are listed from these rows.
+type NotificationLog struct {
+	NotificationLogID   int64     `gorm:"column:notification_log_id;primaryKey"`
+	NotificationLogUUID uuid.UUID `gorm:"column:notification_log_uuid;unique"`
+	TenantID            int64     `gorm:"column:tenant_id;not null"`
+	UserID              int64     `gorm:"column:user_id;not null"`
+	NotificationType    string    `gorm:"column:notification_type;not null"`
+	Channel             string    `gorm:"column:channel;not null"`
+	Recipient           *string   `gorm:"column:recipient"`
+	Title               *string   `gorm:"column:title"`
+	Message             *string   `gorm:"column:message"`
+	Status              string    `gorm:"column:status;not null"`
+	ErrorReason         *string   `gorm:"column:error_reason"`
+	CreatedAt           time.Time `gorm:"column:created_at;autoCreateTime"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID;references:UserID"`
+}
+
+func (NotificationLog) TableName() string {
+	return "notification_logs"
+}
+
+func (nl *NotificationLog) BeforeCreate(tx *gorm.DB) (err error) {
+	if nl.NotificationLogUUID == uuid.Nil {
+		nl.NotificationLogUUID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// NotificationSetting holds a user's notification preferences in a tenant:
+// which channels are delivered to, and which notification types the user
+// opted out of. Users without a row get DefaultNotificationSetting.
+type NotificationSetting struct {
+	NotificationSettingID   int64          `gorm:"column:notification_setting_id;primaryKey"`
+	NotificationSettingUUID uuid.UUID      `gorm:"column:notification_setting_uuid;unique"`
+	UserID                  int64          `gorm:"column:user_id;not null"`
+	TenantID                int64          `gorm:"column:tenant_id;not null"`
+	EmailEnabled            bool           `gorm:"column:email_enabled"`
+	SMSEnabled              bool           `gorm:"column:sms_enabled"`
+	InAppEnabled            bool           `gorm:"column:in_app_enabled"`
+	MutedTypes              datatypes.JSON `gorm:"column:muted_types;type:jsonb"`
+	CreatedAt               time.Time      `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt               time.Time      `gorm:"column:updated_at;autoUpdateTime"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID;references:UserID"`
+}
+
+func (NotificationSetting) TableName() string {
+	return "notification_settings"
+}
+
+func (ns *NotificationSetting) BeforeCreate(tx *gorm.DB) (err error) {
+	if ns.NotificationSettingUUID == uuid.Nil {
+		ns.NotificationSettingUUID = uuid.New()
+	}
+	return
+}
+
+// DefaultNotificationSetting returns the preferences of a user who has not
+// changed them: email and in-app on, SMS off, nothing muted.
+func DefaultNotificationSetting(userID, tenantID int64) *NotificationSetting {
+	return &NotificationSetting{
+		UserID:       userID,
+		TenantID:     tenantID,
+		EmailEnabled: true,
+		InAppEnabled: true,
+		MutedTypes:   datatypes.JSON("[]"),
+	}
+}
+
+// ChannelEnabled reports whether the user receives notifications on the
+// channel.
+func (ns *NotificationSetting) ChannelEnabled(channel string) bool {
+	switch channel {
+	case NotificationChannelEmail:
+		return ns.EmailEnabled
+	case NotificationChannelSMS:
+		return ns.SMSEnabled
+	case NotificationChannelInApp:
+		return ns.InAppEnabled
+	}
+	return false
+}
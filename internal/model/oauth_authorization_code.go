@@ -21,6 +21,7 @@ type OAuthAuthorizationCode struct {
 	Scope                      string     `gorm:"column:scope;not null;default:''"`
 	State                      *string    `gorm:"column:state"`
 	Nonce                      *string    `gorm:"column:nonce"`
+	SessionID                  *string    `gorm:"column:session_id"`
 	CodeChallenge              string     `gorm:"column:code_challenge;not null"`
 	CodeChallengeMethod        string     `gorm:"column:code_challenge_method;not null;default:'S256'"`
 	IsUsed                     bool       `gorm:"column:is_used;not null;default:false"`
@@ -21,6 +21,7 @@ type OAuthConsentChallenge struct {
 	Scope                     string    `gorm:"column:scope;not null;default:''"`
 	State                     *string   `gorm:"column:state"`
 	Nonce                     *string   `gorm:"column:nonce"`
+	SessionID                 *string   `gorm:"column:session_id"`
 	CodeChallenge             string    `gorm:"column:code_challenge;not null"`
 	CodeChallengeMethod       string    `gorm:"column:code_challenge_method;not null;default:'S256'"`
 	ResponseType              string    `gorm:"column:response_type;not null;default:'code'"`
@@ -20,6 +20,7 @@ type OAuthRefreshToken struct {
 	UserID                int64      `gorm:"column:user_id;not null"`
 	TenantID              int64      `gorm:"column:tenant_id;not null"`
 	Scope                 string     `gorm:"column:scope;not null;default:''"`
+	SessionID             *string    `gorm:"column:session_id"`
 	IsRevoked             bool       `gorm:"column:is_revoked;not null;default:false"`
 	RevokedAt             *time.Time `gorm:"column:revoked_at"`
 	ExpiresAt             time.Time  `gorm:"column:expires_at;not null"`
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Parental consent decisions (ParentalConsent.Status). Pending requests use
+// StatusPending.
+const (
+	ParentalConsentApproved = "approved"
+	ParentalConsentDenied   = "denied"
+)
+
+// ParentalConsent is the request sent to the guardian of an under-age user
+// who registered through a signup flow with an age gate. The user's account
+// stays inactive until the guardian approves it with the emailed token.
+type ParentalConsent struct {
+	ParentalConsentID   int64      `gorm:"column:parental_consent_id;primaryKey"`
+	ParentalConsentUUID uuid.UUID  `gorm:"column:parental_consent_uuid;unique;not null"`
+	TenantID            int64      `gorm:"column:tenant_id;not null"`
+	UserID              int64      `gorm:"column:user_id;not null"`
+	GuardianEmail       string     `gorm:"column:guardian_email;not null"`
+	Token               string     `gorm:"column:token;not null"` // hashed
+	Status              string     `gorm:"column:status;not null"`
+	ExpiresAt           time.Time  `gorm:"column:expires_at;not null"`
+	DecidedAt           *time.Time `gorm:"column:decided_at"`
+	IPAddress           *string    `gorm:"column:ip_address"`
+	UserAgent           *string    `gorm:"column:user_agent"`
+	CreatedAt           time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt           time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID;references:UserID"`
+}
+
+func (ParentalConsent) TableName() string {
+	return "parental_consents"
+}
+
+func (c *ParentalConsent) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ParentalConsentUUID == uuid.Nil {
+		c.ParentalConsentUUID = uuid.New()
+	}
+	if c.Status == "" {
+		c.Status = StatusPending
+	}
+	return
+}
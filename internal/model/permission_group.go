@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PermissionGroup is a named bundle of permissions, such as "user-admin" or
+// "read-only", that can be assigned to roles, clients and API keys in one
+// call. Assigning a group copies its permissions; later changes to the
+// group do not affect earlier assignments.
+type PermissionGroup struct {
+	PermissionGroupID   int64     `gorm:"column:permission_group_id;primaryKey"`
+	PermissionGroupUUID uuid.UUID `gorm:"column:permission_group_uuid;unique"`
+	TenantID            int64     `gorm:"column:tenant_id;not null"`
+	Name                string    `gorm:"column:name"`
+	Description         string    `gorm:"column:description"`
+	CreatedAt           time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt           time.Time `gorm:"column:updated_at;autoUpdateTime"`
+
+	// Relationships
+	Permissions []Permission `gorm:"many2many:permission_group_permissions;joinForeignKey:PermissionGroupID;joinReferences:PermissionID"`
+}
+
+func (PermissionGroup) TableName() string {
+	return "permission_groups"
+}
+
+func (g *PermissionGroup) BeforeCreate(tx *gorm.DB) (err error) {
+	if g.PermissionGroupUUID == uuid.Nil {
+		g.PermissionGroupUUID = uuid.New()
+	}
+	return
+}
+
+// PermissionGroupPermission is a row of the permission_group_permissions
+// join table.
+type PermissionGroupPermission struct {
+	PermissionGroupID int64     `gorm:"column:permission_group_id;primaryKey"`
+	PermissionID      int64     `gorm:"column:permission_id;primaryKey"`
+	CreatedAt         time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (PermissionGroupPermission) TableName() string {
+	return "permission_group_permissions"
+}
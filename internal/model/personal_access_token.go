@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// PersonalAccessTokenPrefix starts every personal access token, so they can
+// be told apart from JWTs in an Authorization header.
+const PersonalAccessTokenPrefix = "pat_"
+
+type PersonalAccessToken struct {
+	PersonalAccessTokenID   int64          `gorm:"column:personal_access_token_id;primaryKey"`
+	PersonalAccessTokenUUID uuid.UUID      `gorm:"column:personal_access_token_uuid;unique"`
+	TenantID                int64          `gorm:"column:tenant_id;not null"`
+	UserID                  int64          `gorm:"column:user_id;not null"`
+	Name                    string         `gorm:"column:name"`
+	TokenHash               string         `gorm:"column:token_hash;unique"`
+	TokenPrefix             string         `gorm:"column:token_prefix"`
+	Scopes                  pq.StringArray `gorm:"column:scopes;type:text[]"`
+	ExpiresAt               *time.Time     `gorm:"column:expires_at"`
+	LastUsedAt              *time.Time     `gorm:"column:last_used_at"`
+	RevokedAt               *time.Time     `gorm:"column:revoked_at"`
+	CreatedAt               time.Time      `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt               time.Time      `gorm:"column:updated_at;autoUpdateTime"`
+
+	// Relationships
+	User   *User   `gorm:"foreignKey:UserID;references:UserID"`
+	Tenant *Tenant `gorm:"foreignKey:TenantID;references:TenantID"`
+}
+
+func (PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+func (pat *PersonalAccessToken) BeforeCreate(tx *gorm.DB) (err error) {
+	if pat.PersonalAccessTokenUUID == uuid.Nil {
+		pat.PersonalAccessTokenUUID = uuid.New()
+	}
+	return
+}
+
+// IsActive reports whether the token has been neither revoked nor has
+// expired at now.
+func (pat *PersonalAccessToken) IsActive(now time.Time) bool {
+	if pat.RevokedAt != nil {
+		return false
+	}
+	return pat.ExpiresAt == nil || now.Before(*pat.ExpiresAt)
+}
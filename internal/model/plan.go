@@ -0,0 +1,59 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Plan features (Plan.Features). A feature is available on a plan only when
+// its flag is true.
+const (
+	// PlanFeatureMFA lets tenants configure multi-factor authentication.
+	PlanFeatureMFA = "mfa"
+)
+
+// Plan is a billing tier that tenants are subscribed to. It caps the users
+// and clients of its tenants and gates features. MaxUsers and MaxClients of
+// 0 are unlimited. Tenants without a plan are on the default plan, or are
+// unrestricted when no plan is the default.
+type Plan struct {
+	PlanID      int64          `gorm:"column:plan_id;primaryKey"`
+	PlanUUID    uuid.UUID      `gorm:"column:plan_uuid;unique"`
+	Name        string         `gorm:"column:name;unique;not null"`
+	DisplayName string         `gorm:"column:display_name"`
+	Description string         `gorm:"column:description"`
+	Features    datatypes.JSON `gorm:"column:features;type:jsonb;default:'{}'"`
+	MaxUsers    int64          `gorm:"column:max_users;not null;default:0"`
+	MaxClients  int64          `gorm:"column:max_clients;not null;default:0"`
+	IsDefault   bool           `gorm:"column:is_default;default:false"`
+	Status      string         `gorm:"column:status;default:'active'"`
+	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (Plan) TableName() string {
+	return "plans"
+}
+
+func (p *Plan) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.PlanUUID == uuid.Nil {
+		p.PlanUUID = uuid.New()
+	}
+	return
+}
+
+// HasFeature reports whether the plan's feature flag is set.
+func (p *Plan) HasFeature(feature string) bool {
+	var features map[string]bool
+	if err := json.Unmarshal(p.Features, &features); err != nil {
+		return false
+	}
+	return features[feature]
+}
+
+// PlanFeatures are the features a plan can include.
+var PlanFeatures = []string{PlanFeatureMFA}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Types of policy documents.
+const (
+	PolicyDocumentTypeTermsOfService = "terms_of_service"
+	PolicyDocumentTypePrivacyPolicy  = "privacy_policy"
+)
+
+// PolicyDocument is one version of a tenant's terms of service or privacy
+// policy. Versions are drafts until they are published and cannot change
+// afterwards. The most recently published version of each type is the
+// current one; when it requires acceptance, users must accept it to log in
+// or register.
+type PolicyDocument struct {
+	PolicyDocumentID   int64      `gorm:"column:policy_document_id;primaryKey"`
+	PolicyDocumentUUID uuid.UUID  `gorm:"column:policy_document_uuid;unique;not null"`
+	TenantID           int64      `gorm:"column:tenant_id;not null"`
+	Type               string     `gorm:"column:type;not null"`
+	Version            string     `gorm:"column:version;not null"`
+	Title              string     `gorm:"column:title;not null"`
+	URL                string     `gorm:"column:url;not null"`
+	RequireAcceptance  bool       `gorm:"column:require_acceptance"`
+	PublishedAt        *time.Time `gorm:"column:published_at"`
+	CreatedAt          time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt          time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (PolicyDocument) TableName() string {
+	return "policy_documents"
+}
+
+func (d *PolicyDocument) BeforeCreate(tx *gorm.DB) (err error) {
+	if d.PolicyDocumentUUID == uuid.Nil {
+		d.PolicyDocumentUUID = uuid.New()
+	}
+	return
+}
+
+// PolicyAcceptance records that a user accepted a policy document version,
+// with where the acceptance came from.
+type PolicyAcceptance struct {
+	PolicyAcceptanceID int64     `gorm:"column:policy_acceptance_id;primaryKey"`
+	PolicyDocumentID   int64     `gorm:"column:policy_document_id;not null"`
+	UserID             int64     `gorm:"column:user_id;not null"`
+	TenantID           int64     `gorm:"column:tenant_id;not null"`
+	IPAddress          *string   `gorm:"column:ip_address"`
+	UserAgent          *string   `gorm:"column:user_agent"`
+	AcceptedAt         time.Time `gorm:"column:accepted_at;autoCreateTime"`
+
+	// Relationships
+	PolicyDocument *PolicyDocument `gorm:"foreignKey:PolicyDocumentID;references:PolicyDocumentID"`
+	User           *User           `gorm:"foreignKey:UserID;references:UserID"`
+}
+
+func (PolicyAcceptance) TableName() string {
+	return "policy_acceptances"
+}
@@ -33,6 +33,15 @@ type Profile struct {
 	Email   *string `gorm:"column:email"`
 	Address *string `gorm:"column:address"`
 
+	// Encrypted Personal Information — used instead of Birthdate, Phone and
+	// Address when the tenant enables profile encryption. Blind indexes allow
+	// exact-match lookups without decrypting.
+	BirthdateEncrypted  *string `gorm:"column:birthdate_encrypted"`
+	PhoneEncrypted      *string `gorm:"column:phone_encrypted"`
+	AddressEncrypted    *string `gorm:"column:address_encrypted"`
+	BirthdateBlindIndex *string `gorm:"column:birthdate_bidx"`
+	PhoneBlindIndex     *string `gorm:"column:phone_bidx"`
+
 	// Location Information
 	City    *string `gorm:"column:city"`    // Current city
 	Country *string `gorm:"column:country"` // ISO 3166-1 alpha-2 code (US, PH, etc.)
@@ -43,6 +52,7 @@ type Profile struct {
 
 	// Media & Assets (auth-centric)
 	ProfileURL *string `gorm:"column:profile_url"` // User profile picture
+	PictureKey *string `gorm:"column:picture_key"` // Storage key of an uploaded picture; takes precedence over ProfileURL
 
 	// Extended data
 	Metadata datatypes.JSON `gorm:"column:metadata;type:jsonb;default:'{}'"`
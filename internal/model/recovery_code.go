@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecoveryCodeCount is how many backup codes a user is issued at a time.
+const RecoveryCodeCount = 10
+
+// RecoveryCode is a single-use backup code that stands in for a second factor
+// during account recovery. Only a hash of the code is stored.
+type RecoveryCode struct {
+	RecoveryCodeID   int64      `gorm:"column:recovery_code_id;primaryKey"`
+	RecoveryCodeUUID uuid.UUID  `gorm:"column:recovery_code_uuid;unique"`
+	UserID           int64      `gorm:"column:user_id;not null"`
+	CodeHash         string     `gorm:"column:code_hash;not null"`
+	UsedAt           *time.Time `gorm:"column:used_at"`
+	CreatedAt        time.Time  `gorm:"column:created_at;autoCreateTime"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID;references:UserID"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
+
+func (rc *RecoveryCode) BeforeCreate(tx *gorm.DB) (err error) {
+	if rc.RecoveryCodeUUID == uuid.Nil {
+		rc.RecoveryCodeUUID = uuid.New()
+	}
+	return
+}
@@ -16,8 +16,10 @@ type Role struct {
 	Status      string    `gorm:"column:status;type:varchar(16);default:'inactive'"`
 	IsDefault   bool      `gorm:"column:is_default;default:false"`
 	IsSystem    bool      `gorm:"column:is_system;default:false"`
-	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
+	// IsInheritable makes the role assignable in the tenants below its own.
+	IsInheritable bool      `gorm:"column:is_inheritable;default:false"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime"`
 
 	// Relationships
 	Tenant      *Tenant      `gorm:"foreignKey:TenantID;references:TenantID"`
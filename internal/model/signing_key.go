@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Signing key status constants (SigningKey.Status).
+const (
+	SigningKeyStatusActive   = "active"
+	SigningKeyStatusPrevious = "previous"
+	SigningKeyStatusRetired  = "retired"
+)
+
+// SigningKey is an RSA key pair used to sign JWTs. Exactly one key is active
+// and signs new tokens; previous keys were rotated out but still verify the
+// tokens they signed until they are retired. PrivateKey holds the encrypted
+// PEM encoding and is cleared once the key is retired.
+type SigningKey struct {
+	SigningKeyID   int64      `gorm:"column:signing_key_id;primaryKey"`
+	SigningKeyUUID uuid.UUID  `gorm:"column:signing_key_uuid;unique"`
+	KeyID          string     `gorm:"column:key_id;unique"`
+	Algorithm      string     `gorm:"column:algorithm;default:'RS256'"`
+	PublicKey      string     `gorm:"column:public_key"`
+	PrivateKey     *string    `gorm:"column:private_key"`
+	Status         string     `gorm:"column:status;default:'active'"`
+	ActivatedAt    time.Time  `gorm:"column:activated_at"`
+	RotatedAt      *time.Time `gorm:"column:rotated_at"`
+	RetiredAt      *time.Time `gorm:"column:retired_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (SigningKey) TableName() string {
+	return "signing_keys"
+}
+
+func (k *SigningKey) BeforeCreate(tx *gorm.DB) (err error) {
+	if k.SigningKeyUUID == uuid.Nil {
+		k.SigningKeyUUID = uuid.New()
+	}
+	return
+}
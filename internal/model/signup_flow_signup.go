@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SignupFlowSignup records one account created through a signup flow. The
+// rows are what signup caps count, so they outlive the user they point to.
+type SignupFlowSignup struct {
+	SignupFlowSignupID   int64     `gorm:"column:signup_flow_signup_id;primaryKey;autoIncrement"`
+	SignupFlowSignupUUID uuid.UUID `gorm:"column:signup_flow_signup_uuid;type:uuid;uniqueIndex;not null"`
+	SignupFlowID         int64     `gorm:"column:signup_flow_id;not null"`
+	UserID               *int64    `gorm:"column:user_id"`
+	IsInvite             bool      `gorm:"column:is_invite;not null;default:false"`
+	CreatedAt            time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+// TableName returns the database table name for GORM.
+func (SignupFlowSignup) TableName() string {
+	return "signup_flow_signups"
+}
+
+// BeforeCreate generates a UUID if one is not already set.
+func (s *SignupFlowSignup) BeforeCreate(_ *gorm.DB) error {
+	if s.SignupFlowSignupUUID == uuid.Nil {
+		s.SignupFlowSignupUUID = uuid.New()
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,10 +21,20 @@ type Tenant struct {
 	IsPublic    bool           `gorm:"column:is_public;default:false"`
 	IsSystem    bool           `gorm:"column:is_system;default:false"`
 	Metadata    datatypes.JSON `gorm:"column:metadata;type:jsonb;default:'{}'"`
-	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime"`
+	// ParentTenantID is nil for top-level tenants. AncestorPath lists the IDs
+	// of the tenant's ancestors, root first, as "/1/5/"; it is "/" for
+	// top-level tenants.
+	ParentTenantID *int64 `gorm:"column:parent_tenant_id"`
+	AncestorPath   string `gorm:"column:ancestor_path;default:'/'"`
+	// PlanID is the plan the tenant is subscribed to, or nil for the
+	// default plan.
+	PlanID    *int64    `gorm:"column:plan_id"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
 
 	// Relationships
+	ParentTenant      *Tenant             `gorm:"foreignKey:ParentTenantID;references:TenantID"`
+	Plan              *Plan               `gorm:"foreignKey:PlanID;references:PlanID"`
 	Services          []Service           `gorm:"many2many:tenant_services;joinForeignKey:TenantID;joinReferences:ServiceID"`
 	IdentityProviders []*IdentityProvider `gorm:"foreignKey:TenantID;references:TenantID"`
 	Roles             []*Role             `gorm:"foreignKey:TenantID;references:TenantID"`
@@ -39,3 +51,29 @@ func (t *Tenant) BeforeCreate(tx *gorm.DB) (err error) {
 	}
 	return
 }
+
+// ChildAncestorPath returns the AncestorPath of the tenant's direct children.
+func (t *Tenant) ChildAncestorPath() string {
+	path := t.AncestorPath
+	if path == "" {
+		path = "/"
+	}
+	return path + strconv.FormatInt(t.TenantID, 10) + "/"
+}
+
+// HasAncestor reports whether the tenant with tenantID is above t in the
+// tenant hierarchy.
+func (t *Tenant) HasAncestor(tenantID int64) bool {
+	return strings.Contains(t.AncestorPath, "/"+strconv.FormatInt(tenantID, 10)+"/")
+}
+
+// AncestorIDs returns the IDs of the tenant's ancestors, root first.
+func (t *Tenant) AncestorIDs() []int64 {
+	var ids []int64
+	for _, part := range strings.Split(strings.Trim(t.AncestorPath, "/"), "/") {
+		if id, err := strconv.ParseInt(part, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
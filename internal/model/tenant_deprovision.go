@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tenant deprovision modes (TenantDeprovision.Mode).
+const (
+	TenantDeprovisionModeArchive = "archive"
+	TenantDeprovisionModeDelete  = "delete"
+)
+
+// Tenant deprovision statuses (TenantDeprovision.Status).
+const (
+	TenantDeprovisionStatusPending    = "pending"
+	TenantDeprovisionStatusCompleted  = "completed"
+	TenantDeprovisionStatusSuperseded = "superseded"
+)
+
+// TenantDeprovision is a request to archive or delete a tenant. It only takes
+// effect once confirmed with the one-time token issued when it was made; the
+// token is stored hashed. The tenant's UUID and name are copied so the record
+// still identifies the tenant after it has been deleted.
+type TenantDeprovision struct {
+	TenantDeprovisionID   int64      `gorm:"column:tenant_deprovision_id;primaryKey"`
+	TenantDeprovisionUUID uuid.UUID  `gorm:"column:tenant_deprovision_uuid;unique"`
+	TenantID              *int64     `gorm:"column:tenant_id"`
+	TenantUUID            uuid.UUID  `gorm:"column:tenant_uuid"`
+	TenantName            string     `gorm:"column:tenant_name"`
+	Mode                  string     `gorm:"column:mode"`
+	TokenHash             string     `gorm:"column:token_hash"`
+	Status                string     `gorm:"column:status;default:'pending'"`
+	RequestedBy           *int64     `gorm:"column:requested_by"`
+	ExpiresAt             time.Time  `gorm:"column:expires_at"`
+	CompletedAt           *time.Time `gorm:"column:completed_at"`
+	CreatedAt             time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt             time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (TenantDeprovision) TableName() string {
+	return "tenant_deprovisions"
+}
+
+func (d *TenantDeprovision) BeforeCreate(tx *gorm.DB) (err error) {
+	if d.TenantDeprovisionUUID == uuid.Nil {
+		d.TenantDeprovisionUUID = uuid.New()
+	}
+	return
+}
@@ -11,20 +11,32 @@ import (
 // TenantSetting holds tenant-level operational configuration such as rate
 // limits, audit settings, maintenance windows, and feature flags.
 type TenantSetting struct {
-	TenantSettingID   int64          `gorm:"column:tenant_setting_id;primaryKey;autoIncrement" json:"tenant_setting_id"`
-	TenantSettingUUID uuid.UUID      `gorm:"column:tenant_setting_uuid;type:uuid;uniqueIndex;not null" json:"tenant_setting_uuid"`
-	TenantID          int64          `gorm:"column:tenant_id;not null" json:"tenant_id"`
-	RateLimitConfig   datatypes.JSON `gorm:"column:rate_limit_config;type:jsonb;default:'{}'" json:"rate_limit_config"`
-	AuditConfig       datatypes.JSON `gorm:"column:audit_config;type:jsonb;default:'{}'" json:"audit_config"`
-	MaintenanceConfig datatypes.JSON `gorm:"column:maintenance_config;type:jsonb;default:'{}'" json:"maintenance_config"`
-	FeatureFlags      datatypes.JSON `gorm:"column:feature_flags;type:jsonb;default:'{}'" json:"feature_flags"`
-	CreatedAt         time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
-	UpdatedAt         time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	TenantSettingID     int64          `gorm:"column:tenant_setting_id;primaryKey;autoIncrement" json:"tenant_setting_id"`
+	TenantSettingUUID   uuid.UUID      `gorm:"column:tenant_setting_uuid;type:uuid;uniqueIndex;not null" json:"tenant_setting_uuid"`
+	TenantID            int64          `gorm:"column:tenant_id;not null" json:"tenant_id"`
+	RateLimitConfig     datatypes.JSON `gorm:"column:rate_limit_config;type:jsonb;default:'{}'" json:"rate_limit_config"`
+	AuditConfig         datatypes.JSON `gorm:"column:audit_config;type:jsonb;default:'{}'" json:"audit_config"`
+	MaintenanceConfig   datatypes.JSON `gorm:"column:maintenance_config;type:jsonb;default:'{}'" json:"maintenance_config"`
+	FeatureFlags        datatypes.JSON `gorm:"column:feature_flags;type:jsonb;default:'{}'" json:"feature_flags"`
+	UserMetadataSchema  datatypes.JSON `gorm:"column:user_metadata_schema;type:jsonb;default:'{}'" json:"user_metadata_schema"`
+	UserSettingDefaults datatypes.JSON `gorm:"column:user_setting_defaults;type:jsonb;default:'{}'" json:"user_setting_defaults"`
+	CreatedAt           time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
 
 	// Relationships
 	Tenant *Tenant `gorm:"foreignKey:TenantID;references:TenantID"`
 }
 
+// UserSettingDefaults holds the preferences of the tenant's users who have
+// not chosen their own. It is stored in TenantSetting.UserSettingDefaults.
+type UserSettingDefaults struct {
+	Theme             string `json:"theme,omitempty"`
+	PreferredLanguage string `json:"preferred_language,omitempty"`
+	Locale            string `json:"locale,omitempty"`
+	Timezone          string `json:"timezone,omitempty"`
+	DateFormat        string `json:"date_format,omitempty"`
+}
+
 // TableName returns the database table name for TenantSetting.
 func (TenantSetting) TableName() string {
 	return "tenant_settings"
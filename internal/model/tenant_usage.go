@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TenantUsage is the usage of one metric by a tenant on a day (UTC), as
+// metered for quotas and billing. APIKeyID is the API key the usage was made
+// with, or 0. It is not a foreign key, so that usage outlives deleted keys.
+type TenantUsage struct {
+	TenantUsageID   int64     `gorm:"column:tenant_usage_id;primaryKey"`
+	TenantUsageUUID uuid.UUID `gorm:"column:tenant_usage_uuid;unique"`
+	TenantID        int64     `gorm:"column:tenant_id;not null"`
+	APIKeyID        int64     `gorm:"column:api_key_id;not null;default:0"`
+	UsageDate       time.Time `gorm:"column:usage_date;type:date;not null"`
+	Metric          string    `gorm:"column:metric;not null"`
+	Count           int64     `gorm:"column:count;not null;default:0"`
+	CreatedAt       time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt       time.Time `gorm:"column:updated_at;autoUpdateTime"`
+
+	// Relationships
+	APIKey *APIKey `gorm:"foreignKey:APIKeyID;references:APIKeyID"`
+}
+
+func (TenantUsage) TableName() string {
+	return "tenant_usage"
+}
+
+func (tu *TenantUsage) BeforeCreate(tx *gorm.DB) (err error) {
+	if tu.TenantUsageUUID == uuid.Nil {
+		tu.TenantUsageUUID = uuid.New()
+	}
+	return
+}
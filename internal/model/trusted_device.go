@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TrustedDevicePrefix starts every trusted device token.
+const TrustedDevicePrefix = "td_"
+
+// TrustedDevice is a device a user chose to remember after passing a second
+// factor. Logins presenting its token skip step-up authentication until it
+// expires or is revoked.
+type TrustedDevice struct {
+	TrustedDeviceID   int64      `gorm:"column:trusted_device_id;primaryKey"`
+	TrustedDeviceUUID uuid.UUID  `gorm:"column:trusted_device_uuid;unique"`
+	TenantID          int64      `gorm:"column:tenant_id;not null"`
+	UserID            int64      `gorm:"column:user_id;not null"`
+	Name              string     `gorm:"column:name"`
+	TokenHash         string     `gorm:"column:token_hash;unique"`
+	UserAgent         *string    `gorm:"column:user_agent"`
+	IPAddress         *string    `gorm:"column:ip_address"`
+	ExpiresAt         time.Time  `gorm:"column:expires_at;not null"`
+	LastUsedAt        *time.Time `gorm:"column:last_used_at"`
+	RevokedAt         *time.Time `gorm:"column:revoked_at"`
+	CreatedAt         time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+
+	// Relationships
+	User   *User   `gorm:"foreignKey:UserID;references:UserID"`
+	Tenant *Tenant `gorm:"foreignKey:TenantID;references:TenantID"`
+}
+
+func (TrustedDevice) TableName() string {
+	return "trusted_devices"
+}
+
+func (td *TrustedDevice) BeforeCreate(tx *gorm.DB) (err error) {
+	if td.TrustedDeviceUUID == uuid.Nil {
+		td.TrustedDeviceUUID = uuid.New()
+	}
+	return
+}
+
+// IsActive reports whether the device has been neither revoked nor has
+// expired at now.
+func (td *TrustedDevice) IsActive(now time.Time) bool {
+	return td.RevokedAt == nil && now.Before(td.ExpiresAt)
+}
@@ -24,6 +24,11 @@ type User struct {
 	Metadata           datatypes.JSON `gorm:"column:metadata;type:jsonb;default:'{}'"`
 	CreatedAt          time.Time      `gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt          time.Time      `gorm:"column:updated_at;autoUpdateTime"`
+	DeletedAt          *time.Time     `gorm:"column:deleted_at"`
+
+	// Account recovery
+	RecoveryEmail           *string `gorm:"column:recovery_email"`
+	IsRecoveryEmailVerified bool    `gorm:"column:is_recovery_email_verified;default:false"`
 
 	// Relationships
 	UserIdentities []UserIdentity `gorm:"foreignKey:UserID;references:UserID;constraint:OnDelete:CASCADE"`
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// User import job status constants (UserImportJob.Status).
+const (
+	UserImportStatusPending   = "pending"
+	UserImportStatusRunning   = "running"
+	UserImportStatusCompleted = "completed"
+	UserImportStatusFailed    = "failed"
+)
+
+// User import file format constants (UserImportJob.Format).
+const (
+	UserImportFormatCSV  = "csv"
+	UserImportFormatJSON = "json"
+)
+
+// UserImportJob is one bulk user import. Counters are updated after every
+// chunk so progress can be polled while the job runs; RowErrors holds the
+// rows that were not imported and why.
+type UserImportJob struct {
+	UserImportJobID   int64          `gorm:"column:user_import_job_id;primaryKey"`
+	UserImportJobUUID uuid.UUID      `gorm:"column:user_import_job_uuid;unique"`
+	TenantID          int64          `gorm:"column:tenant_id;not null"`
+	CreatedBy         *int64         `gorm:"column:created_by"`
+	Format            string         `gorm:"column:format"`
+	DryRun            bool           `gorm:"column:dry_run"`
+	Status            string         `gorm:"column:status;default:'pending'"`
+	TotalRows         int            `gorm:"column:total_rows"`
+	ProcessedRows     int            `gorm:"column:processed_rows"`
+	ImportedRows      int            `gorm:"column:imported_rows"`
+	DuplicateRows     int            `gorm:"column:duplicate_rows"`
+	InvalidRows       int            `gorm:"column:invalid_rows"`
+	FailedRows        int            `gorm:"column:failed_rows"`
+	RowErrors         datatypes.JSON `gorm:"column:row_errors;type:jsonb;default:'[]'"`
+	Error             *string        `gorm:"column:error"`
+	StartedAt         *time.Time     `gorm:"column:started_at"`
+	CompletedAt       *time.Time     `gorm:"column:completed_at"`
+	CreatedAt         time.Time      `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt         time.Time      `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (UserImportJob) TableName() string {
+	return "user_import_jobs"
+}
+
+func (j *UserImportJob) BeforeCreate(tx *gorm.DB) (err error) {
+	if j.UserImportJobUUID == uuid.Nil {
+		j.UserImportJobUUID = uuid.New()
+	}
+	return
+}
@@ -17,6 +17,8 @@ type UserSetting struct {
 	Timezone          *string `gorm:"column:timezone"`
 	PreferredLanguage *string `gorm:"column:preferred_language"` // ISO 639-1 code (en, es, fr, etc.)
 	Locale            *string `gorm:"column:locale"`             // Locale code (en_US, es_ES, etc.)
+	Theme             *string `gorm:"column:theme"`              // 'light', 'dark', 'system'
+	DateFormat        *string `gorm:"column:date_format"`        // 'YYYY-MM-DD', 'DD/MM/YYYY', etc.
 
 	// Social Media & External Links
 	SocialLinks datatypes.JSON `gorm:"column:social_links"` // JSON object for flexible social media links
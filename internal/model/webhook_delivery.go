@@ -0,0 +1,88 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Webhook delivery status constants (WebhookDelivery.Status).
+const (
+	WebhookDeliveryStatusPending   = "pending"
+	WebhookDeliveryStatusSucceeded = "succeeded"
+	WebhookDeliveryStatusFailed    = "failed"
+)
+
+// WebhookEventAll subscribes an endpoint to every webhook event type.
+const WebhookEventAll = "*"
+
+// WebhookAuthEventTypes maps the webhook event names that are sourced from
+// the auth event log to the auth event types they cover. Lifecycle events
+// (EventTypes) are delivered under their own names.
+var WebhookAuthEventTypes = map[string][]string{
+	"login.succeeded":   {AuthEventTypeLoginSuccess, AuthEventTypeLoginSuccessAfterFail},
+	"login.failed":      {AuthEventTypeLoginFail, AuthEventTypeLoginFailMax},
+	"login.locked":      {AuthEventTypeLoginLock},
+	"password.changed":  {AuthEventTypePasswordChange},
+	"token.revoked":     {AuthEventTypeTokenRevoked, AuthEventTypeOAuthTokenRevoke},
+	"token.reused":      {AuthEventTypeTokenReuse},
+	"user.impersonated": {AuthEventTypeImpersonate},
+}
+
+// WebhookEventTypes returns every event name an endpoint may subscribe to:
+// WebhookEventAll, the lifecycle event types and the auth event names in
+// alphabetical order.
+func WebhookEventTypes() []string {
+	authNames := make([]string, 0, len(WebhookAuthEventTypes))
+	for name := range WebhookAuthEventTypes {
+		authNames = append(authNames, name)
+	}
+	sort.Strings(authNames)
+
+	names := append([]string{WebhookEventAll}, EventTypes...)
+	return append(names, authNames...)
+}
+
+// WebhookDelivery is one event queued for delivery to a webhook endpoint.
+// The payload is rendered when the event is queued so every attempt sends
+// the same body. Attempts counts the requests made so far; the Last* fields
+// describe the most recent one.
+type WebhookDelivery struct {
+	WebhookDeliveryID   int64          `gorm:"column:webhook_delivery_id;primaryKey;autoIncrement"`
+	WebhookDeliveryUUID uuid.UUID      `gorm:"column:webhook_delivery_uuid;type:uuid;uniqueIndex;not null"`
+	TenantID            int64          `gorm:"column:tenant_id;not null"`
+	WebhookEndpointID   int64          `gorm:"column:webhook_endpoint_id;not null"`
+	EventUUID           uuid.UUID      `gorm:"column:event_uuid;type:uuid;not null"`
+	EventType           string         `gorm:"column:event_type;type:varchar(60);not null"`
+	Payload             datatypes.JSON `gorm:"column:payload;type:jsonb;not null"`
+	Status              string         `gorm:"column:status;type:varchar(20);not null;default:'pending'"`
+	Attempts            int            `gorm:"column:attempts;not null;default:0"`
+	NextAttemptAt       *time.Time     `gorm:"column:next_attempt_at"`
+	LastAttemptAt       *time.Time     `gorm:"column:last_attempt_at"`
+	LastStatusCode      *int           `gorm:"column:last_status_code"`
+	LastError           *string        `gorm:"column:last_error;type:text"`
+	LastDurationMs      *int64         `gorm:"column:last_duration_ms"`
+	DeliveredAt         *time.Time     `gorm:"column:delivered_at"`
+	CreatedAt           time.Time      `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt           time.Time      `gorm:"column:updated_at;autoUpdateTime"`
+
+	// Relationships
+	Tenant          *Tenant          `gorm:"foreignKey:TenantID;references:TenantID"`
+	WebhookEndpoint *WebhookEndpoint `gorm:"foreignKey:WebhookEndpointID;references:WebhookEndpointID"`
+}
+
+// TableName returns the database table name for GORM.
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// BeforeCreate generates a UUID if one is not already set.
+func (wd *WebhookDelivery) BeforeCreate(_ *gorm.DB) error {
+	if wd.WebhookDeliveryUUID == uuid.Nil {
+		wd.WebhookDeliveryUUID = uuid.New()
+	}
+	return nil
+}
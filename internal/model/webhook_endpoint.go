@@ -10,22 +10,31 @@ import (
 
 // WebhookEndpoint represents an outbound event notification subscription
 // belonging to a tenant. Multiple endpoints may exist per tenant, each
-// subscribing to a different set of events.
+// subscribing to a different set of events. Only lifecycle events after
+// LastEventID are delivered to the endpoint, and LastAuthEventID is the
+// dispatcher's position in the auth event log.
+// After a secret rotation PreviousSecretEncrypted is still used to sign
+// deliveries until PreviousSecretExpiresAt.
 type WebhookEndpoint struct {
-	WebhookEndpointID   int64          `gorm:"column:webhook_endpoint_id;primaryKey;autoIncrement" json:"webhook_endpoint_id"`
-	WebhookEndpointUUID uuid.UUID      `gorm:"column:webhook_endpoint_uuid;type:uuid;uniqueIndex;not null" json:"webhook_endpoint_uuid"`
-	TenantID            int64          `gorm:"column:tenant_id;not null" json:"tenant_id"`
-	URL                 string         `gorm:"column:url;type:text;not null" json:"url"`
-	SecretEncrypted     string         `gorm:"column:secret_encrypted;type:text" json:"-"`
-	Events              datatypes.JSON `gorm:"column:events;type:jsonb;default:'[]'" json:"events"`
-	MaxRetries          int            `gorm:"column:max_retries;not null;default:3" json:"max_retries"`
-	TimeoutSeconds      int            `gorm:"column:timeout_seconds;not null;default:30" json:"timeout_seconds"`
-	Status              string         `gorm:"column:status;type:varchar(20);not null;default:'active'" json:"status"`
-	Description         string         `gorm:"column:description;type:text" json:"description"`
-	Metadata            datatypes.JSON `gorm:"column:metadata;type:jsonb;default:'{}'" json:"metadata"`
-	LastTriggeredAt     *time.Time     `gorm:"column:last_triggered_at" json:"last_triggered_at"`
-	CreatedAt           time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
-	UpdatedAt           time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	WebhookEndpointID       int64          `gorm:"column:webhook_endpoint_id;primaryKey;autoIncrement" json:"webhook_endpoint_id"`
+	WebhookEndpointUUID     uuid.UUID      `gorm:"column:webhook_endpoint_uuid;type:uuid;uniqueIndex;not null" json:"webhook_endpoint_uuid"`
+	TenantID                int64          `gorm:"column:tenant_id;not null" json:"tenant_id"`
+	URL                     string         `gorm:"column:url;type:text;not null" json:"url"`
+	SecretEncrypted         string         `gorm:"column:secret_encrypted;type:text;serializer:encrypted" json:"-"`
+	PreviousSecretEncrypted string         `gorm:"column:previous_secret_encrypted;type:text;serializer:encrypted" json:"-"`
+	PreviousSecretExpiresAt *time.Time     `gorm:"column:previous_secret_expires_at" json:"previous_secret_expires_at"`
+	SecretRotatedAt         *time.Time     `gorm:"column:secret_rotated_at" json:"secret_rotated_at"`
+	Events                  datatypes.JSON `gorm:"column:events;type:jsonb;default:'[]'" json:"events"`
+	MaxRetries              int            `gorm:"column:max_retries;not null;default:3" json:"max_retries"`
+	TimeoutSeconds          int            `gorm:"column:timeout_seconds;not null;default:30" json:"timeout_seconds"`
+	Status                  string         `gorm:"column:status;type:varchar(20);not null;default:'active'" json:"status"`
+	Description             string         `gorm:"column:description;type:text" json:"description"`
+	Metadata                datatypes.JSON `gorm:"column:metadata;type:jsonb;default:'{}'" json:"metadata"`
+	LastTriggeredAt         *time.Time     `gorm:"column:last_triggered_at" json:"last_triggered_at"`
+	LastEventID             int64          `gorm:"column:last_event_id;not null;default:0" json:"-"`
+	LastAuthEventID         int64          `gorm:"column:last_auth_event_id;not null;default:0" json:"-"`
+	CreatedAt               time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt               time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
 
 	// Relationships
 	Tenant *Tenant `gorm:"foreignKey:TenantID;references:TenantID"`
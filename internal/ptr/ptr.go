@@ -15,6 +15,14 @@ func Ptr(s string) *string {
 	return &s
 }
 
+// Deref returns the string s points to, or "" if s is nil.
+func Deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
 // TimePtr returns a pointer to the given time value.
 func TimePtr(t time.Time) *time.Time {
 	return &t
@@ -32,6 +32,15 @@ func TestPtr(t *testing.T) {
 	})
 }
 
+func TestDeref(t *testing.T) {
+	t.Run("returns empty string for nil", func(t *testing.T) {
+		assert.Equal(t, "", ptr.Deref(nil))
+	})
+	t.Run("returns pointed-to value", func(t *testing.T) {
+		assert.Equal(t, "hello", ptr.Deref(ptr.Ptr("hello")))
+	})
+}
+
 func TestTimePtr(t *testing.T) {
 	now := time.Now()
 	tp := ptr.TimePtr(now)
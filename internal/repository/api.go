@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/model"
@@ -33,6 +34,8 @@ type APIRepository interface {
 	SetStatusByUUID(apiUUID uuid.UUID, tenantID int64, status string) error
 	CountByServiceID(serviceID int64, tenantID int64) (int64, error)
 	DeleteByUUIDAndTenantID(apiUUID uuid.UUID, tenantID int64) error
+	FindDeprecatedByClientID(clientID int64) ([]model.API, error)
+	RecordDeprecatedUsage(apiIDs []int64) error
 }
 
 type apiRepository struct {
@@ -94,7 +97,7 @@ func (r *apiRepository) FindByIdentifier(identifier string, tenantID int64) (*mo
 }
 
 func (r *apiRepository) FindPaginated(filter APIRepositoryGetFilter) (*PaginationResult[model.API], error) {
-	query := r.DB().Model(&model.API{})
+	query := r.ReadDB().Model(&model.API{})
 
 	// Filter by tenant_id
 	query = query.Where("tenant_id = ?", filter.TenantID)
@@ -174,3 +177,28 @@ func (r *apiRepository) CountByServiceID(serviceID int64, tenantID int64) (int64
 func (r *apiRepository) DeleteByUUIDAndTenantID(apiUUID uuid.UUID, tenantID int64) error {
 	return r.DB().Where("api_uuid = ? AND tenant_id = ?", apiUUID, tenantID).Delete(&model.API{}).Error
 }
+
+// RecordDeprecatedUsage increments the deprecated usage counter of every
+// given API and stamps the time of last use.
+func (r *apiRepository) RecordDeprecatedUsage(apiIDs []int64) error {
+	if len(apiIDs) == 0 {
+		return nil
+	}
+	return r.DB().Model(&model.API{}).
+		Where("api_id IN ?", apiIDs).
+		UpdateColumns(map[string]any{
+			"deprecated_usage_count": gorm.Expr("deprecated_usage_count + 1"),
+			"last_deprecated_use_at": time.Now(),
+		}).Error
+}
+
+// FindDeprecatedByClientID returns every deprecated API linked to the given
+// client through client_apis.
+func (r *apiRepository) FindDeprecatedByClientID(clientID int64) ([]model.API, error) {
+	var apis []model.API
+	err := r.DB().
+		Joins("JOIN client_apis ON client_apis.api_id = apis.api_id").
+		Where("client_apis.client_id = ? AND apis.deprecated_at IS NOT NULL", clientID).
+		Find(&apis).Error
+	return apis, err
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/maintainerd/auth/internal/model"
 	"gorm.io/gorm"
@@ -23,9 +24,11 @@ type APIKeyRepository interface {
 	WithTx(tx *gorm.DB) APIKeyRepository
 	FindByUUIDAndTenantID(uuid string, tenantID int64) (*model.APIKey, error)
 	FindByKeyHash(keyHash string) (*model.APIKey, error)
+	FindByKeyHashWithPermissions(keyHash string) (*model.APIKey, error)
 	FindByKeyPrefix(keyPrefix string) (*model.APIKey, error)
 	DeleteByUUIDAndTenantID(uuid string, tenantID int64) error
 	FindPaginated(filter APIKeyRepositoryGetFilter) (*PaginationResult[model.APIKey], error)
+	AddUsage(apiKeyID int64, count int64, lastUsedAt time.Time) error
 }
 
 type apiKeyRepository struct {
@@ -69,6 +72,36 @@ func (r *apiKeyRepository) FindByKeyHash(keyHash string) (*model.APIKey, error)
 	return &apiKey, nil
 }
 
+// FindByKeyHashWithPermissions finds an API key by hash together with its
+// tenant, the APIs it may call and the permissions it holds on each.
+func (r *apiKeyRepository) FindByKeyHashWithPermissions(keyHash string) (*model.APIKey, error) {
+	var apiKey model.APIKey
+	err := r.DB().
+		Preload("Tenant").
+		Preload("APIKeyAPIs.API").
+		Preload("APIKeyAPIs.Permissions.Permission").
+		Where("key_hash = ?", keyHash).
+		First(&apiKey).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+// AddUsage adds count requests to an API key's usage and moves last_used_at
+// forward to lastUsedAt unless it is already later.
+func (r *apiKeyRepository) AddUsage(apiKeyID int64, count int64, lastUsedAt time.Time) error {
+	return r.DB().Model(&model.APIKey{}).
+		Where("api_key_id = ?", apiKeyID).
+		UpdateColumns(map[string]any{
+			"usage_count":  gorm.Expr("usage_count + ?", count),
+			"last_used_at": gorm.Expr("GREATEST(COALESCE(last_used_at, ?), ?)", lastUsedAt, lastUsedAt),
+		}).Error
+}
+
 func (r *apiKeyRepository) DeleteByUUIDAndTenantID(uuid string, tenantID int64) error {
 	result := r.DB().Where("api_key_uuid = ? AND tenant_id = ?", uuid, tenantID).Delete(&model.APIKey{})
 	if result.Error != nil {
@@ -92,7 +125,7 @@ func (r *apiKeyRepository) FindByKeyPrefix(keyPrefix string) (*model.APIKey, err
 }
 
 func (r *apiKeyRepository) FindPaginated(filter APIKeyRepositoryGetFilter) (*PaginationResult[model.APIKey], error) {
-	query := r.DB().Model(&model.APIKey{})
+	query := r.ReadDB().Model(&model.APIKey{})
 
 	// Always filter by tenant
 	query = query.Where("tenant_id = ?", filter.TenantID)
@@ -17,6 +17,7 @@ type APIKeyAPIRepository interface {
 	FindByAPIKeyUUIDAndAPIUUID(apiKeyUUID uuid.UUID, apiUUID uuid.UUID) (*model.APIKeyAPI, error)
 	RemoveByAPIKeyAndAPI(apiKeyID int64, apiID int64) error
 	RemoveByAPIKeyUUIDAndAPIUUID(apiKeyUUID uuid.UUID, apiUUID uuid.UUID) error
+	DeleteByAPIKeyID(apiKeyID int64) error
 }
 
 type apiKeyAPIRepository struct {
@@ -149,3 +150,7 @@ func (r *apiKeyAPIRepository) RemoveByAPIKeyUUIDAndAPIUUID(apiKeyUUID uuid.UUID,
 	// Delete by ID (more reliable than complex JOINs in DELETE)
 	return r.DB().Delete(&model.APIKeyAPI{}, apiKeyAPI.APIKeyAPIID).Error
 }
+
+func (r *apiKeyAPIRepository) DeleteByAPIKeyID(apiKeyID int64) error {
+	return r.DB().Where("api_key_id = ?", apiKeyID).Delete(&model.APIKeyAPI{}).Error
+}
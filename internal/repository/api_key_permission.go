@@ -13,6 +13,7 @@ type APIKeyPermissionRepository interface {
 	FindByAPIKeyAPIAndPermission(apiKeyAPIID int64, permissionID int64) (*model.APIKeyPermission, error)
 	RemoveByAPIKeyAPIAndPermission(apiKeyAPIID int64, permissionID int64) error
 	FindByAPIKeyAPIID(apiKeyAPIID int64) ([]model.APIKeyPermission, error)
+	DeleteByAPIKeyID(apiKeyID int64) error
 }
 
 type apiKeyPermissionRepository struct {
@@ -53,3 +54,9 @@ func (r *apiKeyPermissionRepository) FindByAPIKeyAPIID(apiKeyAPIID int64) ([]mod
 	}
 	return apiKeyPermissions, nil
 }
+
+func (r *apiKeyPermissionRepository) DeleteByAPIKeyID(apiKeyID int64) error {
+	return r.DB().
+		Where("api_key_api_id IN (SELECT api_key_api_id FROM api_key_apis WHERE api_key_id = ?)", apiKeyID).
+		Delete(&model.APIKeyPermission{}).Error
+}
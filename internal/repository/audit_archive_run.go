@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+)
+
+type AuditArchiveRunRepository interface {
+	BaseRepositoryMethods[model.AuditArchiveRun]
+	WithTx(tx *gorm.DB) AuditArchiveRunRepository
+	FindByUUIDAndTenantID(uuid string, tenantID int64) (*model.AuditArchiveRun, error)
+	FindPaginatedByTenantID(tenantID int64, page, limit int) (*PaginationResult[model.AuditArchiveRun], error)
+	FindRunningByTenantID(tenantID int64, startedAfter time.Time) (*model.AuditArchiveRun, error)
+}
+
+type auditArchiveRunRepository struct {
+	*BaseRepository[model.AuditArchiveRun]
+}
+
+func NewAuditArchiveRunRepository(db *gorm.DB) AuditArchiveRunRepository {
+	return &auditArchiveRunRepository{
+		BaseRepository: NewBaseRepository[model.AuditArchiveRun](db, "audit_archive_run_uuid", "audit_archive_run_id"),
+	}
+}
+
+func (r *auditArchiveRunRepository) WithTx(tx *gorm.DB) AuditArchiveRunRepository {
+	return &auditArchiveRunRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+func (r *auditArchiveRunRepository) FindByUUIDAndTenantID(uuid string, tenantID int64) (*model.AuditArchiveRun, error) {
+	var run model.AuditArchiveRun
+	err := r.DB().Where("audit_archive_run_uuid = ? AND tenant_id = ?", uuid, tenantID).First(&run).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &run, nil
+}
+
+// FindPaginatedByTenantID returns a page of a tenant's archive runs, newest
+// first.
+func (r *auditArchiveRunRepository) FindPaginatedByTenantID(tenantID int64, page, limit int) (*PaginationResult[model.AuditArchiveRun], error) {
+	query := r.ReadDB().Model(&model.AuditArchiveRun{}).Where("tenant_id = ?", tenantID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page, limit = normalizePagination(page, limit)
+	var runs []model.AuditArchiveRun
+	if err := query.Order("created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&runs).Error; err != nil {
+		return nil, err
+	}
+
+	return &PaginationResult[model.AuditArchiveRun]{
+		Data:       runs,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, nil
+}
+
+// FindRunningByTenantID returns a run of the tenant that is still running
+// and started after startedAfter. Older running runs are treated as
+// abandoned.
+func (r *auditArchiveRunRepository) FindRunningByTenantID(tenantID int64, startedAfter time.Time) (*model.AuditArchiveRun, error) {
+	var run model.AuditArchiveRun
+	err := r.DB().
+		Where("tenant_id = ? AND status = ? AND created_at > ?", tenantID, model.AuditArchiveStatusRunning, startedAfter).
+		First(&run).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &run, nil
+}
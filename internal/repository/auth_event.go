@@ -26,15 +26,31 @@ type AuthEventRepositoryGetFilter struct {
 	Limit        int
 }
 
+// AuthEventRepositoryFeedFilter selects a window of a tenant's auth events
+// in ascending AuthEventID order.
+type AuthEventRepositoryFeedFilter struct {
+	TenantID int64
+	AfterID  int64
+	Types    []string
+	Before   *time.Time
+	Limit    int
+}
+
 // AuthEventRepository defines persistence operations for auth events.
 type AuthEventRepository interface {
 	BaseRepositoryMethods[model.AuthEvent]
 	WithTx(tx *gorm.DB) AuthEventRepository
 	FindPaginated(filter AuthEventRepositoryGetFilter) (*PaginationResult[model.AuthEvent], error)
+	// FindCursorPaginated returns a keyset page of the auth events matching
+	// the filter, ordered by auth_event_id.
+	FindCursorPaginated(filter AuthEventRepositoryGetFilter, page CursorPage) (*CursorPaginationResult[model.AuthEvent], error)
 	FindByUUIDAndTenantID(uuid string, tenantID int64) (*model.AuthEvent, error)
 	FindByDateRange(tenantID int64, from, to time.Time) ([]model.AuthEvent, error)
 	DeleteOlderThan(cutoff time.Time) (int64, error)
+	DeleteByTenantOlderThan(tenantID int64, cutoff time.Time, maxID int64) (int64, error)
 	CountByEventType(eventType string, tenantID int64) (int64, error)
+	FindFeed(filter AuthEventRepositoryFeedFilter) ([]model.AuthEvent, error)
+	LatestID(tenantID int64) (int64, error)
 }
 
 type authEventRepository struct {
@@ -57,7 +73,43 @@ func (r *authEventRepository) WithTx(tx *gorm.DB) AuthEventRepository {
 
 // FindPaginated returns a page of auth events filtered by the supplied criteria.
 func (r *authEventRepository) FindPaginated(filter AuthEventRepositoryGetFilter) (*PaginationResult[model.AuthEvent], error) {
-	query := r.DB().Model(&model.AuthEvent{})
+	query := applyAuthEventFilter(r.ReadDB().Model(&model.AuthEvent{}), filter)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	query = query.Order(sanitizeOrder(filter.SortBy, filter.SortOrder, "created_at DESC"))
+
+	filter.Page, filter.Limit = normalizePagination(filter.Page, filter.Limit)
+	offset := (filter.Page - 1) * filter.Limit
+
+	var events []model.AuthEvent
+	if err := query.Offset(offset).Limit(filter.Limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(filter.Limit) - 1) / int64(filter.Limit))
+	return &PaginationResult[model.AuthEvent]{
+		Data:       events,
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// FindCursorPaginated returns a keyset page of auth events filtered by the
+// supplied criteria.
+func (r *authEventRepository) FindCursorPaginated(filter AuthEventRepositoryGetFilter, page CursorPage) (*CursorPaginationResult[model.AuthEvent], error) {
+	query := applyAuthEventFilter(r.DB().Model(&model.AuthEvent{}), filter)
+	return paginateByCursor[model.AuthEvent](query, "auth_event_id", page)
+}
+
+// applyAuthEventFilter adds the conditions of an auth event list filter to
+// query.
+func applyAuthEventFilter(query *gorm.DB, filter AuthEventRepositoryGetFilter) *gorm.DB {
 
 	if filter.TenantID != nil {
 		query = query.Where("tenant_id = ?", *filter.TenantID)
@@ -86,30 +138,7 @@ func (r *authEventRepository) FindPaginated(filter AuthEventRepositoryGetFilter)
 	if filter.DateTo != nil {
 		query = query.Where("created_at <= ?", *filter.DateTo)
 	}
-
-	var total int64
-	if err := query.Count(&total).Error; err != nil {
-		return nil, err
-	}
-
-	query = query.Order(sanitizeOrder(filter.SortBy, filter.SortOrder, "created_at DESC"))
-
-	filter.Page, filter.Limit = normalizePagination(filter.Page, filter.Limit)
-	offset := (filter.Page - 1) * filter.Limit
-
-	var events []model.AuthEvent
-	if err := query.Offset(offset).Limit(filter.Limit).Find(&events).Error; err != nil {
-		return nil, err
-	}
-
-	totalPages := int((total + int64(filter.Limit) - 1) / int64(filter.Limit))
-	return &PaginationResult[model.AuthEvent]{
-		Data:       events,
-		Total:      total,
-		Page:       filter.Page,
-		Limit:      filter.Limit,
-		TotalPages: totalPages,
-	}, nil
+	return query
 }
 
 // FindByUUIDAndTenantID retrieves a single auth event by UUID scoped to a tenant.
@@ -145,6 +174,18 @@ func (r *authEventRepository) DeleteOlderThan(cutoff time.Time) (int64, error) {
 	return result.RowsAffected, result.Error
 }
 
+// DeleteByTenantOlderThan removes a tenant's auth events older than the
+// cutoff and returns the count deleted. A positive maxID keeps events with a
+// larger ID, so events that were not archived are never deleted.
+func (r *authEventRepository) DeleteByTenantOlderThan(tenantID int64, cutoff time.Time, maxID int64) (int64, error) {
+	query := r.DB().Where("tenant_id = ? AND created_at < ?", tenantID, cutoff)
+	if maxID > 0 {
+		query = query.Where("auth_event_id <= ?", maxID)
+	}
+	result := query.Delete(&model.AuthEvent{})
+	return result.RowsAffected, result.Error
+}
+
 // CountByEventType returns the number of events matching the event type within a tenant.
 func (r *authEventRepository) CountByEventType(eventType string, tenantID int64) (int64, error) {
 	var count int64
@@ -154,3 +195,38 @@ func (r *authEventRepository) CountByEventType(eventType string, tenantID int64)
 		Count(&count).Error
 	return count, err
 }
+
+// FindFeed returns up to filter.Limit auth events for a tenant with an
+// AuthEventID greater than filter.AfterID, oldest first, with the actor and
+// target users preloaded.
+func (r *authEventRepository) FindFeed(filter AuthEventRepositoryFeedFilter) ([]model.AuthEvent, error) {
+	query := r.DB().Preload("ActorUser").Preload("TargetUser").
+		Where("tenant_id = ? AND auth_event_id > ?", filter.TenantID, filter.AfterID)
+
+	if len(filter.Types) > 0 {
+		query = query.Where("event_type IN ?", filter.Types)
+	}
+	if filter.Before != nil {
+		query = query.Where("created_at < ?", *filter.Before)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var events []model.AuthEvent
+	if err := query.Order("auth_event_id ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// LatestID returns the highest AuthEventID recorded for a tenant, or 0 when
+// the tenant has no auth events.
+func (r *authEventRepository) LatestID(tenantID int64) (int64, error) {
+	var id int64
+	err := r.DB().Model(&model.AuthEvent{}).
+		Where("tenant_id = ?", tenantID).
+		Select("COALESCE(MAX(auth_event_id), 0)").
+		Scan(&id).Error
+	return id, err
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/maintainerd/auth/internal/database/replica"
 	"gorm.io/gorm"
 )
 
@@ -17,6 +18,8 @@ var allowedSortColumns = map[string]struct{}{
 	"is_system": {}, "is_active": {}, "type": {}, "version": {},
 	"priority": {}, "provider_name": {}, "client_id": {},
 	"category": {}, "severity": {}, "result": {}, "error_reason": {},
+	"description": {}, "provider": {}, "sub": {}, "channel": {},
+	"notification_type": {}, "published_at": {},
 }
 
 // normalizePagination clamps page and limit to safe positive values.
@@ -31,6 +34,32 @@ func normalizePagination(page, limit int) (int, int) {
 	return page, limit
 }
 
+// paginateByCursor reads a keyset page of query ordered by idColumn. Unlike
+// offset pagination its cost does not grow with the page number. One extra
+// row is read to tell whether another page follows.
+func paginateByCursor[T any](query *gorm.DB, idColumn string, page CursorPage) (*CursorPaginationResult[T], error) {
+	_, limit := normalizePagination(1, page.Limit)
+
+	order, cmp := idColumn+" DESC", idColumn+" < ?"
+	if page.Ascending {
+		order, cmp = idColumn+" ASC", idColumn+" > ?"
+	}
+	if page.AfterID > 0 {
+		query = query.Where(cmp, page.AfterID)
+	}
+
+	var rows []T
+	if err := query.Order(order).Limit(limit + 1).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	return &CursorPaginationResult[T]{Data: rows, Limit: limit, HasMore: hasMore}, nil
+}
+
 // sanitizeOrder validates sortBy against the allowlist and returns a safe
 // ORDER BY expression. Falls back to defaultCol (e.g. "created_at DESC") if
 // sortBy is empty or not in the allowlist.
@@ -100,6 +129,14 @@ func (r *BaseRepository[T]) DB() *gorm.DB {
 	return r.db
 }
 
+// ReadDB returns the underlying *gorm.DB with its queries marked as safe to
+// serve from a read replica. Use it for reads that tolerate replication lag;
+// reads that decide a write belong in the write's transaction, which always
+// runs on the primary.
+func (r *BaseRepository[T]) ReadDB() *gorm.DB {
+	return replica.Read(r.db)
+}
+
 // Create a new record and return it with populated fields (e.g., auto-generated ID)
 func (r *BaseRepository[T]) Create(entity *T) (*T, error) {
 	if err := r.db.Create(entity).Error; err != nil {
@@ -130,9 +167,10 @@ func (r *BaseRepository[T]) FindAll(preloads ...string) ([]T, error) {
 }
 
 // FindByUUID with optional preloads. Returns nil, nil when the record does not exist.
+// Outside a transaction it may be served by a read replica.
 func (r *BaseRepository[T]) FindByUUID(uuid any, preloads ...string) (*T, error) {
 	var entity T
-	query := r.db.Model(new(T))
+	query := r.ReadDB().Model(new(T))
 	for _, preload := range preloads {
 		query = query.Preload(preload)
 	}
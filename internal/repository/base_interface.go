@@ -43,3 +43,20 @@ type PaginationResult[T any] struct {
 	Limit      int
 	TotalPages int
 }
+
+// CursorPage selects a keyset page: up to Limit rows whose ID comes after
+// AfterID in ID order, newest first unless Ascending. An AfterID of 0 starts
+// at the first row.
+type CursorPage struct {
+	AfterID   int64
+	Limit     int
+	Ascending bool
+}
+
+// CursorPaginationResult holds a keyset page. HasMore reports whether rows
+// follow the last one.
+type CursorPaginationResult[T any] struct {
+	Data    []T
+	Limit   int
+	HasMore bool
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+)
+
+// cachedClientRepository serves FindByClientIDAndIdentityProvider, the
+// lookup every login, registration and authorize request starts with, from
+// a read-through cache. Every other method goes straight to the database.
+type cachedClientRepository struct {
+	ClientRepository
+	store cache.ClientLookupStore
+}
+
+// NewCachedClientRepository wraps inner with a read-through cache for client
+// lookups by client_id and identity provider. Misses and "not found" results
+// are not cached.
+func NewCachedClientRepository(inner ClientRepository, store cache.ClientLookupStore) ClientRepository {
+	return &cachedClientRepository{ClientRepository: inner, store: store}
+}
+
+// WithTx keeps the cache in front of the transaction: the auth flows that
+// run the lookup inside a transaction only read the client.
+func (r *cachedClientRepository) WithTx(tx *gorm.DB) ClientRepository {
+	return &cachedClientRepository{ClientRepository: r.ClientRepository.WithTx(tx), store: r.store}
+}
+
+func (r *cachedClientRepository) FindByClientIDAndIdentityProvider(clientID, identityProviderIdentifier string) (*model.Client, error) {
+	ctx := context.Background()
+	if client := r.store.GetClientLookup(ctx, clientID, identityProviderIdentifier); client != nil {
+		return client, nil
+	}
+
+	client, err := r.ClientRepository.FindByClientIDAndIdentityProvider(clientID, identityProviderIdentifier)
+	if err != nil || client == nil {
+		return client, err
+	}
+	r.store.SetClientLookup(ctx, clientID, identityProviderIdentifier, client)
+	return client, nil
+}
+
+// cachedTenantRepository serves tenant lookups by UUID and identifier, made
+// by the tenant scope middleware and token validation on every request, from
+// a read-through cache.
+type cachedTenantRepository struct {
+	TenantRepository
+	store cache.TenantLookupStore
+}
+
+// NewCachedTenantRepository wraps inner with a read-through cache for tenant
+// lookups by UUID (without preloads) and by identifier.
+func NewCachedTenantRepository(inner TenantRepository, store cache.TenantLookupStore) TenantRepository {
+	return &cachedTenantRepository{TenantRepository: inner, store: store}
+}
+
+// WithTx returns the uncached repository: transactions read the tenant to
+// change it or its subtree and must see the current row.
+func (r *cachedTenantRepository) WithTx(tx *gorm.DB) TenantRepository {
+	return r.TenantRepository.WithTx(tx)
+}
+
+func (r *cachedTenantRepository) FindByUUID(id any, preloads ...string) (*model.Tenant, error) {
+	tenantUUID, ok := tenantLookupUUID(id)
+	if !ok || len(preloads) > 0 {
+		return r.TenantRepository.FindByUUID(id, preloads...)
+	}
+
+	ctx := context.Background()
+	if tenant := r.store.GetTenantByUUID(ctx, tenantUUID); tenant != nil {
+		return tenant, nil
+	}
+
+	tenant, err := r.TenantRepository.FindByUUID(id)
+	if err != nil || tenant == nil {
+		return tenant, err
+	}
+	r.store.SetTenant(ctx, tenant)
+	return tenant, nil
+}
+
+func (r *cachedTenantRepository) FindByIdentifier(identifier string) (*model.Tenant, error) {
+	ctx := context.Background()
+	if tenant := r.store.GetTenantByIdentifier(ctx, identifier); tenant != nil {
+		return tenant, nil
+	}
+
+	tenant, err := r.TenantRepository.FindByIdentifier(identifier)
+	if err != nil || tenant == nil {
+		return tenant, err
+	}
+	r.store.SetTenant(ctx, tenant)
+	return tenant, nil
+}
+
+// tenantLookupUUID returns the UUID FindByUUID was called with, when it is
+// one the cache can key on.
+func tenantLookupUUID(id any) (uuid.UUID, bool) {
+	switch v := id.(type) {
+	case uuid.UUID:
+		return v, true
+	case string:
+		parsed, err := uuid.Parse(v)
+		return parsed, err == nil
+	}
+	return uuid.Nil, false
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/model"
@@ -31,12 +32,18 @@ type ClientRepository interface {
 	FindByNameAndTenantID(name string, tenantID int64) (*model.Client, error)
 	FindByClientID(clientID string, tenantID int64) (*model.Client, error)
 	FindAllByTenantID(tenantID int64) ([]model.Client, error)
+	CountByTenantID(tenantID int64) (int64, error)
 	FindSystem() (*model.Client, error)
 	FindDefaultByTenantID(tenantID int64) (*model.Client, error)
 	FindPaginated(filter ClientRepositoryGetFilter) (*PaginationResult[model.Client], error)
 	SetStatusByUUID(clientUUID uuid.UUID, tenantID int64, status string) error
+	SetStatusByTenantID(tenantID int64, status string) error
+	UnsetDefaultByTenantID(tenantID int64) error
 	FindByClientIDAndIdentityProvider(clientID, identityProviderIdentifier string) (*model.Client, error)
+	FindActiveByIdentifier(identifier string) (*model.Client, error)
+	FindByIDsWithURIs(clientIDs []int64) ([]model.Client, error)
 	DeleteByUUIDAndTenantID(clientUUID uuid.UUID, tenantID int64) error
+	RecordDeprecatedUsage(clientID int64) error
 }
 
 type clientRepository struct {
@@ -108,6 +115,12 @@ func (r *clientRepository) FindAllByTenantID(tenantID int64) ([]model.Client, er
 	return clients, err
 }
 
+func (r *clientRepository) CountByTenantID(tenantID int64) (int64, error) {
+	var count int64
+	err := r.DB().Model(&model.Client{}).Where("tenant_id = ?", tenantID).Count(&count).Error
+	return count, err
+}
+
 // FindSystem returns the active system client belonging to the system tenant
 // (is_system = true on both tenant and client). The seeded system client is the
 // auth-console SPA used to bootstrap the management surface on port 8080.
@@ -168,7 +181,7 @@ func (r *clientRepository) FindDefaultByTenantID(tenantID int64) (*model.Client,
 }
 
 func (r *clientRepository) FindPaginated(filter ClientRepositoryGetFilter) (*PaginationResult[model.Client], error) {
-	query := r.DB().Model(&model.Client{}).Where("tenant_id = ?", filter.TenantID)
+	query := r.ReadDB().Model(&model.Client{}).Where("tenant_id = ?", filter.TenantID)
 
 	// Filters with LIKE
 	if filter.Name != nil {
@@ -234,6 +247,18 @@ func (r *clientRepository) SetStatusByUUID(clientUUID uuid.UUID, tenantID int64,
 		Update("status", status).Error
 }
 
+func (r *clientRepository) SetStatusByTenantID(tenantID int64, status string) error {
+	return r.DB().Model(&model.Client{}).
+		Where("tenant_id = ?", tenantID).
+		Update("status", status).Error
+}
+
+func (r *clientRepository) UnsetDefaultByTenantID(tenantID int64) error {
+	return r.DB().Model(&model.Client{}).
+		Where("tenant_id = ? AND is_default = ?", tenantID, true).
+		Update("is_default", false).Error
+}
+
 func (r *clientRepository) FindByClientIDAndIdentityProvider(clientID, identityProviderIdentifier string) (*model.Client, error) {
 	var client model.Client
 
@@ -254,6 +279,38 @@ func (r *clientRepository) FindByClientIDAndIdentityProvider(clientID, identityP
 	return &client, nil
 }
 
+// FindActiveByIdentifier returns the active client with the given OAuth
+// client_id across all tenants, with its identity provider and URIs, or nil
+// when there is none.
+func (r *clientRepository) FindActiveByIdentifier(identifier string) (*model.Client, error) {
+	var client model.Client
+
+	err := r.DB().
+		Preload("IdentityProvider").
+		Preload("ClientURIs").
+		Where("identifier = ? AND status = ?", identifier, model.StatusActive).
+		First(&client).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &client, nil
+}
+
+// FindByIDsWithURIs returns the clients with the given internal IDs,
+// whatever their status, with their URIs.
+func (r *clientRepository) FindByIDsWithURIs(clientIDs []int64) ([]model.Client, error) {
+	var clients []model.Client
+	if len(clientIDs) == 0 {
+		return clients, nil
+	}
+	err := r.DB().Preload("ClientURIs").Where("client_id IN ?", clientIDs).Find(&clients).Error
+	return clients, err
+}
+
 func (r *clientRepository) DeleteByUUIDAndTenantID(clientUUID uuid.UUID, tenantID int64) error {
 	result := r.DB().Where("client_uuid = ? AND tenant_id = ?", clientUUID, tenantID).Delete(&model.Client{})
 	if result.Error != nil {
@@ -264,3 +321,15 @@ func (r *clientRepository) DeleteByUUIDAndTenantID(clientUUID uuid.UUID, tenantI
 	}
 	return nil
 }
+
+// RecordDeprecatedUsage increments the deprecated usage counter of a client
+// and stamps the time of last use. The increment is done in SQL so concurrent
+// token requests are not lost.
+func (r *clientRepository) RecordDeprecatedUsage(clientID int64) error {
+	return r.DB().Model(&model.Client{}).
+		Where("client_id = ?", clientID).
+		UpdateColumns(map[string]any{
+			"deprecated_usage_count": gorm.Expr("deprecated_usage_count + 1"),
+			"last_deprecated_use_at": time.Now(),
+		}).Error
+}
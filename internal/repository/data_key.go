@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EncryptedColumn names a column encrypted with the data keys and the
+// primary key of its table.
+type EncryptedColumn struct {
+	Table    string
+	IDColumn string
+	Column   string
+}
+
+// EncryptedValue is a stored value of an EncryptedColumn.
+type EncryptedValue struct {
+	ID    int64  `gorm:"column:id"`
+	Value string `gorm:"column:value"`
+}
+
+type DataKeyRepository interface {
+	BaseRepositoryMethods[model.DataKey]
+	WithTx(tx *gorm.DB) DataKeyRepository
+	FindByKeyID(keyID string) (*model.DataKey, error)
+	FindActiveForUpdate() (*model.DataKey, error)
+	FindAllOrdered() ([]model.DataKey, error)
+	FindValuesNotSealedWith(col EncryptedColumn, prefix string, afterID int64, limit int) ([]EncryptedValue, error)
+	ReplaceValue(col EncryptedColumn, id int64, old, value string) (bool, error)
+}
+
+type dataKeyRepository struct {
+	*BaseRepository[model.DataKey]
+}
+
+func NewDataKeyRepository(db *gorm.DB) DataKeyRepository {
+	return &dataKeyRepository{
+		BaseRepository: NewBaseRepository[model.DataKey](db, "data_key_uuid", "data_key_id"),
+	}
+}
+
+func (r *dataKeyRepository) WithTx(tx *gorm.DB) DataKeyRepository {
+	return &dataKeyRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+func (r *dataKeyRepository) FindByKeyID(keyID string) (*model.DataKey, error) {
+	var key model.DataKey
+	err := r.DB().Where("key_id = ?", keyID).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindActiveForUpdate locks the active key until the surrounding transaction
+// ends, so that concurrent rotations replace it one at a time.
+func (r *dataKeyRepository) FindActiveForUpdate() (*model.DataKey, error) {
+	var key model.DataKey
+	err := r.DB().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("status = ?", model.DataKeyStatusActive).
+		First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindAllOrdered returns every key, newest first.
+func (r *dataKeyRepository) FindAllOrdered() ([]model.DataKey, error) {
+	var keys []model.DataKey
+	err := r.DB().Order("activated_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// FindValuesNotSealedWith returns up to limit non-empty values of the column
+// that do not start with prefix, in primary key order after afterID. The
+// values are returned as stored, without decrypting them.
+func (r *dataKeyRepository) FindValuesNotSealedWith(col EncryptedColumn, prefix string, afterID int64, limit int) ([]EncryptedValue, error) {
+	id, column := clause.Column{Name: col.IDColumn}, clause.Column{Name: col.Column}
+	var values []EncryptedValue
+	err := r.DB().Table(col.Table).
+		Select("? AS id, ? AS value", id, column).
+		Where("? > ? AND ? <> '' AND ? NOT LIKE ?", id, afterID, column, column, prefix+"%").
+		Order(clause.OrderByColumn{Column: id}).
+		Limit(limit).
+		Scan(&values).Error
+	return values, err
+}
+
+// ReplaceValue sets the column of a row to value if it still holds old, so a
+// value changed since it was read is left alone. It reports whether the row
+// was updated.
+func (r *dataKeyRepository) ReplaceValue(col EncryptedColumn, id int64, old, value string) (bool, error) {
+	result := r.DB().Table(col.Table).
+		Where("? = ? AND ? = ?", clause.Column{Name: col.IDColumn}, id, clause.Column{Name: col.Column}, old).
+		UpdateColumn(col.Column, value)
+	return result.RowsAffected == 1, result.Error
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Actions a delete has on a row, as reported in DeleteImpactEntry.Action.
+const (
+	DeleteImpactDelete     = "delete"
+	DeleteImpactCascade    = "cascade"
+	DeleteImpactSetNull    = "set_null"
+	DeleteImpactSetDefault = "set_default"
+	DeleteImpactRestrict   = "restrict"
+)
+
+// deleteImpactMaxDepth bounds how far cascades are followed.
+const deleteImpactMaxDepth = 6
+
+// DeleteImpactEntry is the number of rows of a table a delete removes or
+// changes through one foreign key. Constraint is empty for the deleted rows
+// themselves.
+type DeleteImpactEntry struct {
+	Table      string
+	Constraint string
+	Action     string
+	Rows       int64
+}
+
+// DeleteImpact lists every row a delete reaches through foreign keys,
+// parents before children.
+type DeleteImpact struct {
+	Entries []DeleteImpactEntry
+}
+
+// Blocking returns the entries whose foreign key refuses the delete.
+func (d *DeleteImpact) Blocking() []DeleteImpactEntry {
+	var out []DeleteImpactEntry
+	for _, e := range d.Entries {
+		if e.Action == DeleteImpactRestrict {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// DeleteImpactRepository measures the effect of a delete from the foreign
+// keys in the database catalog, without changing any row.
+type DeleteImpactRepository interface {
+	WithTx(tx *gorm.DB) DeleteImpactRepository
+	Analyze(table, column string, value any) (*DeleteImpact, error)
+}
+
+type deleteImpactRepository struct {
+	db *gorm.DB
+}
+
+// NewDeleteImpactRepository creates a new DeleteImpactRepository backed by
+// the given database connection.
+func NewDeleteImpactRepository(db *gorm.DB) DeleteImpactRepository {
+	return &deleteImpactRepository{db: db}
+}
+
+// WithTx returns a copy of the repository bound to the supplied transaction.
+func (r *deleteImpactRepository) WithTx(tx *gorm.DB) DeleteImpactRepository {
+	return &deleteImpactRepository{db: tx}
+}
+
+// foreignKey is a single-column foreign key referencing a table.
+type foreignKey struct {
+	Constraint   string
+	ChildTable   string
+	ChildColumn  string
+	ParentColumn string
+	OnDelete     string
+}
+
+// Analyze counts the rows of table where column equals value, then follows
+// every single-column foreign key that references them: cascading keys are
+// followed further, SET NULL and SET DEFAULT keys are counted, and NO ACTION
+// and RESTRICT keys with matching rows are reported as restrict.
+func (r *deleteImpactRepository) Analyze(table, column string, value any) (*DeleteImpact, error) {
+	var rows int64
+	err := r.db.Table(table).Where(quoteIdent(column)+" = ?", value).Count(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	impact := &DeleteImpact{Entries: []DeleteImpactEntry{{Table: table, Action: DeleteImpactDelete, Rows: rows}}}
+	if rows == 0 {
+		return impact, nil
+	}
+
+	where := fmt.Sprintf("%s = ?", quoteIdent(column))
+	err = r.follow(impact, table, where, []any{value}, map[string]bool{table: true}, 1)
+	if err != nil {
+		return nil, err
+	}
+	return impact, nil
+}
+
+// follow adds the rows that reference the rows of table matching where.
+// path holds the tables on the current cascade chain so that cycles end.
+func (r *deleteImpactRepository) follow(impact *DeleteImpact, table, where string, args []any, path map[string]bool, depth int) error {
+	if depth > deleteImpactMaxDepth {
+		return nil
+	}
+
+	keys, err := r.foreignKeys(table)
+	if err != nil {
+		return err
+	}
+	for _, fk := range keys {
+		childWhere := fmt.Sprintf("%s IN (SELECT %s FROM %s WHERE %s)",
+			quoteIdent(fk.ChildColumn), quoteIdent(fk.ParentColumn), quoteIdent(table), where)
+
+		var rows int64
+		if err := r.db.Table(fk.ChildTable).Where(childWhere, args...).Count(&rows).Error; err != nil {
+			return err
+		}
+		if rows == 0 {
+			continue
+		}
+		impact.Entries = append(impact.Entries, DeleteImpactEntry{
+			Table:      fk.ChildTable,
+			Constraint: fk.Constraint,
+			Action:     fk.OnDelete,
+			Rows:       rows,
+		})
+
+		if fk.OnDelete == DeleteImpactCascade && !path[fk.ChildTable] {
+			path[fk.ChildTable] = true
+			if err := r.follow(impact, fk.ChildTable, childWhere, args, path, depth+1); err != nil {
+				return err
+			}
+			delete(path, fk.ChildTable)
+		}
+	}
+	return nil
+}
+
+// foreignKeys returns the single-column foreign keys that reference table.
+func (r *deleteImpactRepository) foreignKeys(table string) ([]foreignKey, error) {
+	var keys []foreignKey
+	err := r.db.Raw(`
+SELECT c.conname AS "constraint",
+       child.relname AS child_table,
+       ca.attname AS child_column,
+       pa.attname AS parent_column,
+       CASE c.confdeltype
+           WHEN 'c' THEN ?
+           WHEN 'n' THEN ?
+           WHEN 'd' THEN ?
+           ELSE ?
+       END AS on_delete
+FROM pg_constraint c
+JOIN pg_class child ON child.oid = c.conrelid
+JOIN pg_class parent ON parent.oid = c.confrelid
+JOIN pg_attribute ca ON ca.attrelid = c.conrelid AND ca.attnum = c.conkey[1]
+JOIN pg_attribute pa ON pa.attrelid = c.confrelid AND pa.attnum = c.confkey[1]
+WHERE c.contype = 'f'
+  AND parent.relname = ?
+  AND parent.relnamespace = to_regnamespace(current_schema())
+  AND cardinality(c.conkey) = 1
+ORDER BY child.relname, c.conname`,
+		DeleteImpactCascade, DeleteImpactSetNull, DeleteImpactSetDefault, DeleteImpactRestrict, table,
+	).Scan(&keys).Error
+	return keys, err
+}
+
+// quoteIdent quotes a Postgres identifier.
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
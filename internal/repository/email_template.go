@@ -73,7 +73,7 @@ func (r *emailTemplateRepository) FindByName(name string) (*model.EmailTemplate,
 
 // FindPaginated retrieves paginated email templates with filtering
 func (r *emailTemplateRepository) FindPaginated(filter EmailTemplateRepositoryGetFilter) (*PaginationResult[model.EmailTemplate], error) {
-	query := r.DB().Model(&model.EmailTemplate{})
+	query := r.ReadDB().Model(&model.EmailTemplate{})
 
 	// Apply filters
 	if filter.Name != nil && *filter.Name != "" {
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+)
+
+// EventRepositoryFeedFilter selects a window of the lifecycle event feed.
+// Events are always returned in ascending EventID order.
+type EventRepositoryFeedFilter struct {
+	TenantID int64
+	AfterID  int64
+	Types    []string
+	Before   *time.Time
+	Limit    int
+}
+
+// Backlog is the number of items waiting in a queue and when the oldest of
+// them was created. OldestAt is nil when the queue is empty.
+type Backlog struct {
+	Depth    int64
+	OldestAt *time.Time
+}
+
+// EventRepository defines persistence operations for lifecycle events.
+type EventRepository interface {
+	BaseRepositoryMethods[model.Event]
+	WithTx(tx *gorm.DB) EventRepository
+	FindFeed(filter EventRepositoryFeedFilter) ([]model.Event, error)
+	FindAfter(afterID int64, before time.Time, limit int) ([]model.Event, error)
+	LatestID(tenantID int64) (int64, error)
+	BacklogAfter(afterID int64) (*Backlog, error)
+}
+
+type eventRepository struct {
+	*BaseRepository[model.Event]
+}
+
+// NewEventRepository creates a new EventRepository backed by the supplied DB.
+func NewEventRepository(db *gorm.DB) EventRepository {
+	return &eventRepository{
+		BaseRepository: NewBaseRepository[model.Event](db, "event_uuid", "event_id"),
+	}
+}
+
+// WithTx returns a copy of the repository bound to the given transaction.
+func (r *eventRepository) WithTx(tx *gorm.DB) EventRepository {
+	return &eventRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+// FindFeed returns up to filter.Limit events for a tenant with an EventID
+// greater than filter.AfterID, oldest first.
+func (r *eventRepository) FindFeed(filter EventRepositoryFeedFilter) ([]model.Event, error) {
+	query := r.DB().
+		Where("tenant_id = ? AND event_id > ?", filter.TenantID, filter.AfterID)
+
+	if len(filter.Types) > 0 {
+		query = query.Where("event_type IN ?", filter.Types)
+	}
+	if filter.Before != nil {
+		query = query.Where("occurred_at < ?", *filter.Before)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var events []model.Event
+	if err := query.Order("event_id ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// FindAfter returns up to limit events of every tenant with an EventID
+// greater than afterID that occurred before the given time, oldest first.
+// The tenant is preloaded.
+func (r *eventRepository) FindAfter(afterID int64, before time.Time, limit int) ([]model.Event, error) {
+	var events []model.Event
+	err := r.DB().
+		Preload("Tenant").
+		Where("event_id > ? AND occurred_at < ?", afterID, before).
+		Order("event_id ASC").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// LatestID returns the highest EventID recorded for a tenant, or 0 when the
+// tenant has no events.
+func (r *eventRepository) LatestID(tenantID int64) (int64, error) {
+	var id int64
+	err := r.DB().Model(&model.Event{}).
+		Where("tenant_id = ?", tenantID).
+		Select("COALESCE(MAX(event_id), 0)").
+		Scan(&id).Error
+	return id, err
+}
+
+// BacklogAfter returns how many events of every tenant have an EventID
+// greater than afterID, and when the oldest of them occurred.
+func (r *eventRepository) BacklogAfter(afterID int64) (*Backlog, error) {
+	var b Backlog
+	err := r.DB().Model(&model.Event{}).
+		Where("event_id > ?", afterID).
+		Select("COUNT(*) AS depth, MIN(occurred_at) AS oldest_at").
+		Scan(&b).Error
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
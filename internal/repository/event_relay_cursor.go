@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EventRelayCursorRepository defines persistence operations for event relay
+// cursors.
+type EventRelayCursorRepository interface {
+	BaseRepositoryMethods[model.EventRelayCursor]
+	WithTx(tx *gorm.DB) EventRelayCursorRepository
+	FindByName(name string) (*model.EventRelayCursor, error)
+	Lock(name string) (*model.EventRelayCursor, error)
+	Advance(eventRelayCursorID, lastEventID int64) error
+}
+
+type eventRelayCursorRepository struct {
+	*BaseRepository[model.EventRelayCursor]
+}
+
+// NewEventRelayCursorRepository creates a new EventRelayCursorRepository
+// backed by the given database connection.
+func NewEventRelayCursorRepository(db *gorm.DB) EventRelayCursorRepository {
+	return &eventRelayCursorRepository{
+		BaseRepository: NewBaseRepository[model.EventRelayCursor](db, "event_relay_cursor_uuid", "event_relay_cursor_id"),
+	}
+}
+
+// WithTx returns a copy of the repository bound to the supplied transaction.
+func (r *eventRelayCursorRepository) WithTx(tx *gorm.DB) EventRelayCursorRepository {
+	return &eventRelayCursorRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+// FindByName retrieves the named cursor without locking it. Returns nil, nil
+// when the relay has not run yet.
+func (r *eventRelayCursorRepository) FindByName(name string) (*model.EventRelayCursor, error) {
+	var cursor model.EventRelayCursor
+	err := r.DB().Where("name = ?", name).First(&cursor).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// Lock creates the named cursor if it does not exist yet and locks it until
+// the surrounding transaction ends. Returns nil, nil when another
+// transaction holds the lock. Must be called inside a transaction.
+func (r *eventRelayCursorRepository) Lock(name string) (*model.EventRelayCursor, error) {
+	err := r.DB().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoNothing: true,
+	}).Create(&model.EventRelayCursor{Name: name}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var cursors []model.EventRelayCursor
+	err = r.DB().Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("name = ?", name).
+		Limit(1).
+		Find(&cursors).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(cursors) == 0 {
+		return nil, nil
+	}
+	return &cursors[0], nil
+}
+
+// Advance moves a cursor to lastEventID.
+func (r *eventRelayCursorRepository) Advance(eventRelayCursorID, lastEventID int64) error {
+	return r.DB().Model(&model.EventRelayCursor{}).
+		Where("event_relay_cursor_id = ?", eventRelayCursorID).
+		Update("last_event_id", lastEventID).Error
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FeatureFlagRepository defines persistence operations for feature flags
+// and their per-tenant overrides.
+type FeatureFlagRepository interface {
+	BaseRepositoryMethods[model.FeatureFlag]
+	WithTx(tx *gorm.DB) FeatureFlagRepository
+	// FindAllOrdered returns every flag by key, with its overrides and
+	// their tenants.
+	FindAllOrdered() ([]model.FeatureFlag, error)
+	// FindByKey returns the flag with its overrides, or nil when there is
+	// none.
+	FindByKey(key string) (*model.FeatureFlag, error)
+	// SetOverride creates or replaces the override of a tenant.
+	SetOverride(flagID int64, tenantID int64, enabled bool) error
+	// DeleteOverride removes the override of a tenant and reports whether
+	// there was one.
+	DeleteOverride(flagID int64, tenantID int64) (bool, error)
+}
+
+type featureFlagRepository struct {
+	*BaseRepository[model.FeatureFlag]
+}
+
+// NewFeatureFlagRepository creates a new FeatureFlagRepository backed by the
+// given database connection.
+func NewFeatureFlagRepository(db *gorm.DB) FeatureFlagRepository {
+	return &featureFlagRepository{
+		BaseRepository: NewBaseRepository[model.FeatureFlag](db, "feature_flag_uuid", "feature_flag_id"),
+	}
+}
+
+// WithTx returns a copy of the repository bound to the supplied transaction.
+func (r *featureFlagRepository) WithTx(tx *gorm.DB) FeatureFlagRepository {
+	return &featureFlagRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+func (r *featureFlagRepository) FindAllOrdered() ([]model.FeatureFlag, error) {
+	var flags []model.FeatureFlag
+	err := r.DB().Preload("Overrides.Tenant").Order("key ASC").Find(&flags).Error
+	return flags, err
+}
+
+func (r *featureFlagRepository) FindByKey(key string) (*model.FeatureFlag, error) {
+	var flag model.FeatureFlag
+	err := r.DB().Preload("Overrides").Where("key = ?", key).First(&flag).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
+
+func (r *featureFlagRepository) SetOverride(flagID int64, tenantID int64, enabled bool) error {
+	return r.DB().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "feature_flag_id"}, {Name: "tenant_id"}},
+		DoUpdates: clause.Assignments(map[string]any{"enabled": enabled, "updated_at": gorm.Expr("now()")}),
+	}).Create(&model.FeatureFlagOverride{
+		FeatureFlagID: flagID,
+		TenantID:      tenantID,
+		Enabled:       enabled,
+	}).Error
+}
+
+func (r *featureFlagRepository) DeleteOverride(flagID int64, tenantID int64) (bool, error) {
+	result := r.DB().
+		Where("feature_flag_id = ? AND tenant_id = ?", flagID, tenantID).
+		Delete(&model.FeatureFlagOverride{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+)
+
+type GroupRepositoryGetFilter struct {
+	TenantID    int64
+	Name        *string
+	Description *string
+	// UserID limits the result to the groups the user is a member of.
+	UserID    *int64
+	Page      int
+	Limit     int
+	SortBy    string
+	SortOrder string
+}
+
+type GroupRepository interface {
+	BaseRepositoryMethods[model.Group]
+	WithTx(tx *gorm.DB) GroupRepository
+	FindByUUIDAndTenantID(groupUUID uuid.UUID, tenantID int64, preloads ...string) (*model.Group, error)
+	FindByName(name string, tenantID int64) (*model.Group, error)
+	FindPaginated(filter GroupRepositoryGetFilter) (*PaginationResult[model.Group], error)
+	// FindMembersPaginated returns a page of the group's members, ordered by
+	// when they were added.
+	FindMembersPaginated(groupID int64, page int, limit int) (*PaginationResult[model.User], error)
+	// AddMembers adds users to a group and returns the IDs of those that were
+	// not members yet.
+	AddMembers(groupID int64, userIDs []int64) ([]int64, error)
+	RemoveMember(groupID int64, userID int64) (bool, error)
+	// AddRoles attaches roles to a group and returns the IDs of those that
+	// were not attached yet.
+	AddRoles(groupID int64, roleIDs []int64) ([]int64, error)
+	RemoveRole(groupID int64, roleID int64) (bool, error)
+	DeleteByUUIDAndTenantID(groupUUID uuid.UUID, tenantID int64) error
+	// FindUUIDsByMember returns those of groupUUIDs that the user is a member
+	// of.
+	FindUUIDsByMember(userUUID uuid.UUID, groupUUIDs []uuid.UUID) ([]uuid.UUID, error)
+}
+
+type groupRepository struct {
+	*BaseRepository[model.Group]
+}
+
+func NewGroupRepository(db *gorm.DB) GroupRepository {
+	return &groupRepository{
+		BaseRepository: NewBaseRepository[model.Group](db, "group_uuid", "group_id"),
+	}
+}
+
+func (r *groupRepository) WithTx(tx *gorm.DB) GroupRepository {
+	return &groupRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+func (r *groupRepository) FindByUUIDAndTenantID(groupUUID uuid.UUID, tenantID int64, preloads ...string) (*model.Group, error) {
+	var group model.Group
+	query := r.DB().Model(&model.Group{})
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+	err := query.Where("group_uuid = ? AND tenant_id = ?", groupUUID, tenantID).First(&group).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+func (r *groupRepository) FindByName(name string, tenantID int64) (*model.Group, error) {
+	var group model.Group
+	err := r.DB().Where("name = ? AND tenant_id = ?", name, tenantID).First(&group).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+func (r *groupRepository) FindPaginated(filter GroupRepositoryGetFilter) (*PaginationResult[model.Group], error) {
+	query := r.ReadDB().Model(&model.Group{}).Where("tenant_id = ?", filter.TenantID)
+
+	// Apply filters
+	if filter.Name != nil {
+		query = query.Where("name ILIKE ?", "%"+*filter.Name+"%")
+	}
+	if filter.Description != nil {
+		query = query.Where("description ILIKE ?", "%"+*filter.Description+"%")
+	}
+	if filter.UserID != nil {
+		query = query.Where("EXISTS (SELECT 1 FROM group_members gm WHERE gm.group_id = groups.group_id AND gm.user_id = ?)", *filter.UserID)
+	}
+
+	// Count total records
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	// Apply sorting — protected against SQL injection via allowlist
+	query = query.Order(sanitizeOrder(filter.SortBy, filter.SortOrder, "created_at DESC"))
+
+	// Pagination
+	filter.Page, filter.Limit = normalizePagination(filter.Page, filter.Limit)
+	offset := (filter.Page - 1) * filter.Limit
+	var groups []model.Group
+	if err := query.Preload("Roles").Limit(filter.Limit).Offset(offset).Find(&groups).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(filter.Limit) - 1) / int64(filter.Limit))
+
+	return &PaginationResult[model.Group]{
+		Data:       groups,
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (r *groupRepository) FindMembersPaginated(groupID int64, page int, limit int) (*PaginationResult[model.User], error) {
+	query := r.DB().
+		Model(&model.User{}).
+		Joins("JOIN group_members gm ON gm.user_id = users.user_id").
+		Where("gm.group_id = ?", groupID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page, limit = normalizePagination(page, limit)
+	offset := (page - 1) * limit
+	var users []model.User
+	if err := query.Order("gm.created_at, users.user_id").Limit(limit).Offset(offset).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	return &PaginationResult[model.User]{
+		Data:       users,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (r *groupRepository) AddMembers(groupID int64, userIDs []int64) ([]int64, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	var existing []int64
+	if err := r.DB().Model(&model.GroupMember{}).
+		Where("group_id = ? AND user_id IN ?", groupID, userIDs).
+		Pluck("user_id", &existing).Error; err != nil {
+		return nil, err
+	}
+
+	added := newIDs(userIDs, existing)
+	if len(added) == 0 {
+		return nil, nil
+	}
+	rows := make([]model.GroupMember, len(added))
+	for i, userID := range added {
+		rows[i] = model.GroupMember{GroupID: groupID, UserID: userID}
+	}
+	if err := r.DB().CreateInBatches(&rows, 500).Error; err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// RemoveMember removes a user from a group and reports whether the user was
+// a member.
+func (r *groupRepository) RemoveMember(groupID int64, userID int64) (bool, error) {
+	result := r.DB().
+		Where("group_id = ? AND user_id = ?", groupID, userID).
+		Delete(&model.GroupMember{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *groupRepository) AddRoles(groupID int64, roleIDs []int64) ([]int64, error) {
+	if len(roleIDs) == 0 {
+		return nil, nil
+	}
+	var existing []int64
+	if err := r.DB().Model(&model.GroupRole{}).
+		Where("group_id = ? AND role_id IN ?", groupID, roleIDs).
+		Pluck("role_id", &existing).Error; err != nil {
+		return nil, err
+	}
+
+	added := newIDs(roleIDs, existing)
+	if len(added) == 0 {
+		return nil, nil
+	}
+	rows := make([]model.GroupRole, len(added))
+	for i, roleID := range added {
+		rows[i] = model.GroupRole{GroupID: groupID, RoleID: roleID}
+	}
+	if err := r.DB().Create(&rows).Error; err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// RemoveRole detaches a role from a group and reports whether the group had
+// it.
+func (r *groupRepository) RemoveRole(groupID int64, roleID int64) (bool, error) {
+	result := r.DB().
+		Where("group_id = ? AND role_id = ?", groupID, roleID).
+		Delete(&model.GroupRole{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *groupRepository) DeleteByUUIDAndTenantID(groupUUID uuid.UUID, tenantID int64) error {
+	return r.DB().Where("group_uuid = ? AND tenant_id = ?", groupUUID, tenantID).Delete(&model.Group{}).Error
+}
+
+func (r *groupRepository) FindUUIDsByMember(userUUID uuid.UUID, groupUUIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if len(groupUUIDs) == 0 {
+		return nil, nil
+	}
+	var found []uuid.UUID
+	err := r.DB().
+		Table("groups g").
+		Joins("JOIN group_members gm ON gm.group_id = g.group_id").
+		Joins("JOIN users u ON u.user_id = gm.user_id").
+		Where("u.user_uuid = ? AND g.group_uuid IN ?", userUUID, groupUUIDs).
+		Pluck("g.group_uuid", &found).Error
+	return found, err
+}
+
+// newIDs returns the distinct ids that are not in existing, in order.
+func newIDs(ids []int64, existing []int64) []int64 {
+	seen := make(map[int64]struct{}, len(existing)+len(ids))
+	for _, id := range existing {
+		seen[id] = struct{}{}
+	}
+	out := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out
+}
@@ -29,6 +29,7 @@ type IdentityProviderRepository interface {
 	FindByName(name string, tenantID int64) (*model.IdentityProvider, error)
 	FindByIdentifier(identifier string) (*model.IdentityProvider, error)
 	FindDefaultByTenantID(tenantID int64) (*model.IdentityProvider, error)
+	FindActiveByTenantID(tenantID int64) ([]model.IdentityProvider, error)
 	FindPaginated(filter IdentityProviderRepositoryGetFilter) (*PaginationResult[model.IdentityProvider], error)
 }
 
@@ -88,8 +89,19 @@ func (r *identityProviderRepository) FindDefaultByTenantID(tenantID int64) (*mod
 	return &provider, err
 }
 
+// FindActiveByTenantID returns the active identity providers of a tenant,
+// oldest first.
+func (r *identityProviderRepository) FindActiveByTenantID(tenantID int64) ([]model.IdentityProvider, error) {
+	var providers []model.IdentityProvider
+	err := r.DB().
+		Where("tenant_id = ? AND status = ?", tenantID, model.StatusActive).
+		Order("created_at ASC").
+		Find(&providers).Error
+	return providers, err
+}
+
 func (r *identityProviderRepository) FindPaginated(filter IdentityProviderRepositoryGetFilter) (*PaginationResult[model.IdentityProvider], error) {
-	query := r.DB().Model(&model.IdentityProvider{})
+	query := r.ReadDB().Model(&model.IdentityProvider{})
 
 	// Filters with LIKE
 	if filter.Name != nil {
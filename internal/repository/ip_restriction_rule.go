@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/maintainerd/auth/internal/model"
 	"gorm.io/gorm"
 )
@@ -29,6 +31,7 @@ type IPRestrictionRuleRepository interface {
 	FindByTenantID(tenantID int64) ([]model.IPRestrictionRule, error)
 	FindByTenantIDAndStatus(tenantID int64, status string) ([]model.IPRestrictionRule, error)
 	FindByTenantIDAndType(tenantID int64, ruleType string) ([]model.IPRestrictionRule, error)
+	FindActiveDenyByTenantID(tenantID int64, now time.Time) ([]model.IPRestrictionRule, error)
 	FindPaginated(filter IPRestrictionRuleRepositoryGetFilter) (*PaginationResult[model.IPRestrictionRule], error)
 }
 
@@ -83,10 +86,24 @@ func (r *ipRestrictionRuleRepository) FindByTenantIDAndType(tenantID int64, rule
 	return rules, nil
 }
 
+// FindActiveDenyByTenantID returns the active deny and blacklist rules of a
+// tenant that have not expired at now.
+func (r *ipRestrictionRuleRepository) FindActiveDenyByTenantID(tenantID int64, now time.Time) ([]model.IPRestrictionRule, error) {
+	var rules []model.IPRestrictionRule
+	err := r.DB().
+		Where("tenant_id = ? AND status = ? AND type IN ?", tenantID, model.StatusActive, []string{model.IPRuleTypeDeny, model.IPRuleTypeBlacklist}).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
 // FindPaginated returns a paginated, filtered, and sorted list of IP
 // restriction rules.
 func (r *ipRestrictionRuleRepository) FindPaginated(filter IPRestrictionRuleRepositoryGetFilter) (*PaginationResult[model.IPRestrictionRule], error) {
-	query := r.DB().Model(&model.IPRestrictionRule{})
+	query := r.ReadDB().Model(&model.IPRestrictionRule{})
 
 	// Apply filters
 	if filter.TenantID != nil {
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+)
+
+type LoginFingerprintRepository interface {
+	BaseRepositoryMethods[model.LoginFingerprint]
+	WithTx(tx *gorm.DB) LoginFingerprintRepository
+	// FindByDevice returns the user's fingerprint of the device in the
+	// tenant, if the user has signed in from it before.
+	FindByDevice(userID int64, tenantID int64, deviceHash string) (*model.LoginFingerprint, error)
+	// FindLatest returns the user's most recently seen fingerprint in the
+	// tenant.
+	FindLatest(userID int64, tenantID int64) (*model.LoginFingerprint, error)
+}
+
+type loginFingerprintRepository struct {
+	*BaseRepository[model.LoginFingerprint]
+}
+
+func NewLoginFingerprintRepository(db *gorm.DB) LoginFingerprintRepository {
+	return &loginFingerprintRepository{
+		BaseRepository: NewBaseRepository[model.LoginFingerprint](db, "login_fingerprint_uuid", "login_fingerprint_id"),
+	}
+}
+
+func (r *loginFingerprintRepository) WithTx(tx *gorm.DB) LoginFingerprintRepository {
+	return &loginFingerprintRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+func (r *loginFingerprintRepository) FindByDevice(userID int64, tenantID int64, deviceHash string) (*model.LoginFingerprint, error) {
+	var fingerprint model.LoginFingerprint
+	err := r.DB().
+		Where("user_id = ? AND tenant_id = ? AND device_hash = ?", userID, tenantID, deviceHash).
+		First(&fingerprint).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &fingerprint, nil
+}
+
+func (r *loginFingerprintRepository) FindLatest(userID int64, tenantID int64) (*model.LoginFingerprint, error) {
+	var fingerprint model.LoginFingerprint
+	err := r.DB().
+		Where("user_id = ? AND tenant_id = ?", userID, tenantID).
+		Order("last_seen_at DESC").
+		First(&fingerprint).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &fingerprint, nil
+}
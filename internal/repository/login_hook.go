@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+)
+
+// LoginHookRepositoryGetFilter holds query parameters for paginated login
+// hook lookups.
+type LoginHookRepositoryGetFilter struct {
+	TenantID  *int64
+	Trigger   []string
+	Status    []string
+	Page      int
+	Limit     int
+	SortBy    string
+	SortOrder string
+}
+
+// LoginHookRepository defines persistence operations for the login_hooks
+// entity.
+type LoginHookRepository interface {
+	BaseRepositoryMethods[model.LoginHook]
+	WithTx(tx *gorm.DB) LoginHookRepository
+	FindByUUIDAndTenantID(loginHookUUID uuid.UUID, tenantID int64) (*model.LoginHook, error)
+	FindActiveByTrigger(tenantID int64, trigger string) ([]model.LoginHook, error)
+	FindPaginated(filter LoginHookRepositoryGetFilter) (*PaginationResult[model.LoginHook], error)
+}
+
+type loginHookRepository struct {
+	*BaseRepository[model.LoginHook]
+}
+
+// NewLoginHookRepository creates a new LoginHookRepository backed by the given
+// database connection.
+func NewLoginHookRepository(db *gorm.DB) LoginHookRepository {
+	return &loginHookRepository{
+		BaseRepository: NewBaseRepository[model.LoginHook](db, "login_hook_uuid", "login_hook_id"),
+	}
+}
+
+// WithTx returns a copy of the repository bound to the supplied transaction.
+func (r *loginHookRepository) WithTx(tx *gorm.DB) LoginHookRepository {
+	return &loginHookRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+// FindByUUIDAndTenantID retrieves a single login hook by UUID scoped to a
+// tenant. Returns nil, nil when no record exists.
+func (r *loginHookRepository) FindByUUIDAndTenantID(loginHookUUID uuid.UUID, tenantID int64) (*model.LoginHook, error) {
+	var hook model.LoginHook
+	err := r.DB().Where("login_hook_uuid = ? AND tenant_id = ?", loginHookUUID, tenantID).First(&hook).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &hook, nil
+}
+
+// FindActiveByTrigger retrieves the active hooks a tenant has registered for
+// trigger, in execution order.
+func (r *loginHookRepository) FindActiveByTrigger(tenantID int64, trigger string) ([]model.LoginHook, error) {
+	var hooks []model.LoginHook
+	err := r.DB().
+		Where("tenant_id = ? AND trigger = ? AND status = ?", tenantID, trigger, model.StatusActive).
+		Order("priority ASC, login_hook_id ASC").
+		Find(&hooks).Error
+	if err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
+
+// FindPaginated retrieves paginated login hooks with filtering.
+func (r *loginHookRepository) FindPaginated(filter LoginHookRepositoryGetFilter) (*PaginationResult[model.LoginHook], error) {
+	query := r.ReadDB().Model(&model.LoginHook{})
+
+	if filter.TenantID != nil {
+		query = query.Where("tenant_id = ?", *filter.TenantID)
+	}
+	if len(filter.Trigger) > 0 {
+		query = query.Where("trigger IN ?", filter.Trigger)
+	}
+	if len(filter.Status) > 0 {
+		query = query.Where("status IN ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	query = query.Order(sanitizeOrder(filter.SortBy, filter.SortOrder, "created_at DESC"))
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 10
+	}
+	offset := (filter.Page - 1) * filter.Limit
+
+	var hooks []model.LoginHook
+	if err := query.Offset(offset).Limit(filter.Limit).Find(&hooks).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / filter.Limit
+	if int(total)%filter.Limit > 0 {
+		totalPages++
+	}
+
+	return &PaginationResult[model.LoginHook]{
+		Data:       hooks,
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
@@ -25,6 +25,7 @@ type LoginTemplateRepository interface {
 	BaseRepositoryMethods[model.LoginTemplate]
 	FindByUUIDAndTenantID(loginTemplateUUID uuid.UUID, tenantID int64, preloads ...string) (*model.LoginTemplate, error)
 	FindByName(name string) (*model.LoginTemplate, error)
+	FindDefaultByTenantID(tenantID int64) (*model.LoginTemplate, error)
 	FindPaginated(filter LoginTemplateRepositoryGetFilter) (*PaginationResult[model.LoginTemplate], error)
 }
 
@@ -57,6 +58,23 @@ func (r *loginTemplateRepository) FindByUUIDAndTenantID(loginTemplateUUID uuid.U
 	return &template, nil
 }
 
+// FindDefaultByTenantID retrieves the tenant's active default login template.
+// Returns nil, nil when the tenant has none.
+func (r *loginTemplateRepository) FindDefaultByTenantID(tenantID int64) (*model.LoginTemplate, error) {
+	var template model.LoginTemplate
+	err := r.DB().
+		Where("tenant_id = ? AND is_default = ? AND status = ?", tenantID, true, model.StatusActive).
+		Order("updated_at DESC").
+		First(&template).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
 // FindByName retrieves an active login template by its name
 func (r *loginTemplateRepository) FindByName(name string) (*model.LoginTemplate, error) {
 	var template model.LoginTemplate
@@ -74,7 +92,7 @@ func (r *loginTemplateRepository) FindByName(name string) (*model.LoginTemplate,
 
 // FindPaginated retrieves paginated login templates with filtering
 func (r *loginTemplateRepository) FindPaginated(filter LoginTemplateRepositoryGetFilter) (*PaginationResult[model.LoginTemplate], error) {
-	query := r.DB().Model(&model.LoginTemplate{})
+	query := r.ReadDB().Model(&model.LoginTemplate{})
 
 	// Apply filters
 	if filter.Name != nil && *filter.Name != "" {
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+)
+
+// NotificationLogRepositoryGetFilter holds filter, sort, and pagination
+// options for paginated notification log queries.
+type NotificationLogRepositoryGetFilter struct {
+	TenantID         int64
+	UserID           *int64
+	NotificationType *string
+	Channel          *string
+	Status           *string
+	DateFrom         *time.Time
+	DateTo           *time.Time
+	SortBy           string
+	SortOrder        string
+	Page             int
+	Limit            int
+}
+
+type NotificationLogRepository interface {
+	BaseRepositoryMethods[model.NotificationLog]
+	WithTx(tx *gorm.DB) NotificationLogRepository
+	FindPaginated(filter NotificationLogRepositoryGetFilter) (*PaginationResult[model.NotificationLog], error)
+}
+
+type notificationLogRepository struct {
+	*BaseRepository[model.NotificationLog]
+}
+
+func NewNotificationLogRepository(db *gorm.DB) NotificationLogRepository {
+	return &notificationLogRepository{
+		BaseRepository: NewBaseRepository[model.NotificationLog](db, "notification_log_uuid", "notification_log_id"),
+	}
+}
+
+func (r *notificationLogRepository) WithTx(tx *gorm.DB) NotificationLogRepository {
+	return &notificationLogRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+// FindPaginated returns a page of a tenant's notification logs, newest
+// first unless another order is asked for.
+func (r *notificationLogRepository) FindPaginated(filter NotificationLogRepositoryGetFilter) (*PaginationResult[model.NotificationLog], error) {
+	query := r.ReadDB().Model(&model.NotificationLog{}).Where("tenant_id = ?", filter.TenantID)
+
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.NotificationType != nil && *filter.NotificationType != "" {
+		query = query.Where("notification_type = ?", *filter.NotificationType)
+	}
+	if filter.Channel != nil && *filter.Channel != "" {
+		query = query.Where("channel = ?", *filter.Channel)
+	}
+	if filter.Status != nil && *filter.Status != "" {
+		query = query.Where("status = ?", *filter.Status)
+	}
+	if filter.DateFrom != nil {
+		query = query.Where("created_at >= ?", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		query = query.Where("created_at <= ?", *filter.DateTo)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	query = query.Order(sanitizeOrder(filter.SortBy, filter.SortOrder, "created_at DESC"))
+
+	filter.Page, filter.Limit = normalizePagination(filter.Page, filter.Limit)
+	offset := (filter.Page - 1) * filter.Limit
+
+	var logs []model.NotificationLog
+	if err := query.Preload("User").Offset(offset).Limit(filter.Limit).Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(filter.Limit) - 1) / int64(filter.Limit))
+	return &PaginationResult[model.NotificationLog]{
+		Data:       logs,
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
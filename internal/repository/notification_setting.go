@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+)
+
+type NotificationSettingRepository interface {
+	BaseRepositoryMethods[model.NotificationSetting]
+	WithTx(tx *gorm.DB) NotificationSettingRepository
+	// FindByUserAndTenant returns the user's notification preferences in the
+	// tenant, or nil when the user has not changed them.
+	FindByUserAndTenant(userID int64, tenantID int64) (*model.NotificationSetting, error)
+}
+
+type notificationSettingRepository struct {
+	*BaseRepository[model.NotificationSetting]
+}
+
+func NewNotificationSettingRepository(db *gorm.DB) NotificationSettingRepository {
+	return &notificationSettingRepository{
+		BaseRepository: NewBaseRepository[model.NotificationSetting](db, "notification_setting_uuid", "notification_setting_id"),
+	}
+}
+
+func (r *notificationSettingRepository) WithTx(tx *gorm.DB) NotificationSettingRepository {
+	return &notificationSettingRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+func (r *notificationSettingRepository) FindByUserAndTenant(userID int64, tenantID int64) (*model.NotificationSetting, error) {
+	var setting model.NotificationSetting
+	err := r.DB().
+		Where("user_id = ? AND tenant_id = ?", userID, tenantID).
+		First(&setting).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &setting, nil
+}
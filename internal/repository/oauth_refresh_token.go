@@ -9,6 +9,16 @@ import (
 	"gorm.io/gorm"
 )
 
+// OAuthSession is a sign-in that holds active refresh tokens: the tokens
+// issued in one login session, or one token family issued outside a login
+// session. Exactly one of SessionID and FamilyID is set. StartedAt is when
+// its first refresh token was issued.
+type OAuthSession struct {
+	SessionID *string
+	FamilyID  *uuid.UUID
+	StartedAt time.Time
+}
+
 // OAuthRefreshTokenRepository defines data access operations for OAuth refresh
 // tokens with family-based rotation tracking.
 type OAuthRefreshTokenRepository interface {
@@ -16,10 +26,15 @@ type OAuthRefreshTokenRepository interface {
 	WithTx(tx *gorm.DB) OAuthRefreshTokenRepository
 	FindByTokenHash(tokenHash string) (*model.OAuthRefreshToken, error)
 	FindActiveByUserAndClient(userID, clientID int64) ([]model.OAuthRefreshToken, error)
+	FindBySessionID(sessionID string) ([]model.OAuthRefreshToken, error)
+	FindActiveSessionsByUserID(userID int64) ([]OAuthSession, error)
+	FindSessionStartedAt(familyID uuid.UUID, sessionID *string) (time.Time, error)
 	RevokeByID(tokenID int64) error
 	RevokeByFamily(familyID uuid.UUID) (int64, error)
 	RevokeByUserAndClient(userID, clientID int64) (int64, error)
+	RevokeBySessionID(sessionID string) (int64, error)
 	RevokeByUserID(userID int64) (int64, error)
+	RevokeByUserIDExceptFamily(userID int64, familyID uuid.UUID) (int64, error)
 	UpdateLastUsed(tokenID int64) error
 	DeleteExpired(before time.Time) (int64, error)
 	CountByUserAndClient(userID, clientID int64) (int64, error)
@@ -70,6 +85,48 @@ func (r *oauthRefreshTokenRepository) FindActiveByUserAndClient(userID, clientID
 	return tokens, err
 }
 
+// FindBySessionID returns every refresh token issued in a login session,
+// including revoked and expired ones, so that all clients the session
+// signed in to can be notified when it ends.
+func (r *oauthRefreshTokenRepository) FindBySessionID(sessionID string) ([]model.OAuthRefreshToken, error) {
+	var tokens []model.OAuthRefreshToken
+	err := r.DB().Where("session_id = ?", sessionID).Find(&tokens).Error
+	return tokens, err
+}
+
+// FindActiveSessionsByUserID returns the sessions of a user that hold a
+// non-revoked, non-expired refresh token, oldest first.
+func (r *oauthRefreshTokenRepository) FindActiveSessionsByUserID(userID int64) ([]OAuthSession, error) {
+	var sessions []OAuthSession
+	err := r.DB().Raw(`
+SELECT session_id,
+       CASE WHEN session_id IS NULL THEN family_id END AS family_id,
+       MIN(created_at) AS started_at
+FROM oauth_refresh_tokens
+WHERE user_id = ? AND family_id IN (
+    SELECT family_id FROM oauth_refresh_tokens
+    WHERE user_id = ? AND is_revoked = false AND expires_at > ?
+)
+GROUP BY 1, 2
+ORDER BY started_at`,
+		userID, userID, time.Now(),
+	).Scan(&sessions).Error
+	return sessions, err
+}
+
+// FindSessionStartedAt returns when the session of a refresh token started:
+// when the first token of its login session was issued, or of its family
+// when it was issued outside a login session.
+func (r *oauthRefreshTokenRepository) FindSessionStartedAt(familyID uuid.UUID, sessionID *string) (time.Time, error) {
+	var startedAt time.Time
+	query := r.DB().Model(&model.OAuthRefreshToken{}).Select("MIN(created_at)").Where("family_id = ?", familyID)
+	if sessionID != nil {
+		query = query.Or("session_id = ?", *sessionID)
+	}
+	err := query.Scan(&startedAt).Error
+	return startedAt, err
+}
+
 // RevokeByID revokes a single refresh token.
 func (r *oauthRefreshTokenRepository) RevokeByID(tokenID int64) error {
 	now := time.Now()
@@ -108,6 +165,19 @@ func (r *oauthRefreshTokenRepository) RevokeByUserAndClient(userID, clientID int
 	return result.RowsAffected, result.Error
 }
 
+// RevokeBySessionID revokes all refresh tokens issued in a login session.
+// Returns the number of tokens revoked.
+func (r *oauthRefreshTokenRepository) RevokeBySessionID(sessionID string) (int64, error) {
+	now := time.Now()
+	result := r.DB().Model(&model.OAuthRefreshToken{}).
+		Where("session_id = ? AND is_revoked = false", sessionID).
+		Updates(map[string]any{
+			"is_revoked": true,
+			"revoked_at": now,
+		})
+	return result.RowsAffected, result.Error
+}
+
 // RevokeByUserID revokes all refresh tokens for a user across all clients.
 // Returns the number of tokens revoked.
 func (r *oauthRefreshTokenRepository) RevokeByUserID(userID int64) (int64, error) {
@@ -121,6 +191,20 @@ func (r *oauthRefreshTokenRepository) RevokeByUserID(userID int64) (int64, error
 	return result.RowsAffected, result.Error
 }
 
+// RevokeByUserIDExceptFamily revokes all refresh tokens for a user across all
+// clients except those of one family, the session the user is acting from.
+// Returns the number of tokens revoked.
+func (r *oauthRefreshTokenRepository) RevokeByUserIDExceptFamily(userID int64, familyID uuid.UUID) (int64, error) {
+	now := time.Now()
+	result := r.DB().Model(&model.OAuthRefreshToken{}).
+		Where("user_id = ? AND family_id <> ? AND is_revoked = false", userID, familyID).
+		Updates(map[string]any{
+			"is_revoked": true,
+			"revoked_at": now,
+		})
+	return result.RowsAffected, result.Error
+}
+
 // UpdateLastUsed records when a refresh token was last used at token exchange.
 func (r *oauthRefreshTokenRepository) UpdateLastUsed(tokenID int64) error {
 	return r.DB().Model(&model.OAuthRefreshToken{}).
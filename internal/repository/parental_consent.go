@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+)
+
+// ParentalConsentRepository defines persistence operations for guardian
+// consent requests.
+type ParentalConsentRepository interface {
+	BaseRepositoryMethods[model.ParentalConsent]
+	WithTx(tx *gorm.DB) ParentalConsentRepository
+	// FindPendingByToken returns the undecided, unexpired request with the
+	// hashed token, with its user, or nil.
+	FindPendingByToken(token string) (*model.ParentalConsent, error)
+	// FindPendingByUserID returns the user's undecided request, or nil. An
+	// expired request is still returned: the account keeps waiting for a
+	// consent that can no longer be given.
+	FindPendingByUserID(userID int64) (*model.ParentalConsent, error)
+	// Decide records the guardian's decision on a pending request.
+	Decide(parentalConsentID int64, status string, decidedAt time.Time, ipAddress, userAgent *string) error
+}
+
+type parentalConsentRepository struct {
+	*BaseRepository[model.ParentalConsent]
+}
+
+// NewParentalConsentRepository creates a new ParentalConsentRepository
+// backed by the given database connection.
+func NewParentalConsentRepository(db *gorm.DB) ParentalConsentRepository {
+	return &parentalConsentRepository{
+		BaseRepository: NewBaseRepository[model.ParentalConsent](db, "parental_consent_uuid", "parental_consent_id"),
+	}
+}
+
+// WithTx returns a copy of the repository bound to the supplied transaction.
+func (r *parentalConsentRepository) WithTx(tx *gorm.DB) ParentalConsentRepository {
+	return &parentalConsentRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+func (r *parentalConsentRepository) FindPendingByToken(token string) (*model.ParentalConsent, error) {
+	var consent model.ParentalConsent
+	err := r.DB().
+		Preload("User").
+		Where("token = ? AND status = ? AND expires_at > ?", token, model.StatusPending, time.Now()).
+		First(&consent).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &consent, nil
+}
+
+func (r *parentalConsentRepository) FindPendingByUserID(userID int64) (*model.ParentalConsent, error) {
+	var consent model.ParentalConsent
+	err := r.DB().
+		Where("user_id = ? AND status = ?", userID, model.StatusPending).
+		Order("created_at DESC").
+		First(&consent).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &consent, nil
+}
+
+func (r *parentalConsentRepository) Decide(parentalConsentID int64, status string, decidedAt time.Time, ipAddress, userAgent *string) error {
+	return r.DB().Model(&model.ParentalConsent{}).
+		Where("parental_consent_id = ? AND status = ?", parentalConsentID, model.StatusPending).
+		Updates(map[string]any{
+			"status":     status,
+			"decided_at": decidedAt,
+			"ip_address": ipAddress,
+			"user_agent": userAgent,
+		}).Error
+}
@@ -81,7 +81,7 @@ func (r *permissionRepository) FindByName(name string, tenantID int64) (*model.P
 }
 
 func (r *permissionRepository) FindPaginated(filter PermissionRepositoryGetFilter) (*PaginationResult[model.Permission], error) {
-	query := r.DB().Model(&model.Permission{}).Where("tenant_id = ?", filter.TenantID)
+	query := r.ReadDB().Model(&model.Permission{}).Where("tenant_id = ?", filter.TenantID)
 
 	// Filters with LIKE
 	if filter.Name != nil {
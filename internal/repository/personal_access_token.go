@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+)
+
+type PersonalAccessTokenRepository interface {
+	BaseRepositoryMethods[model.PersonalAccessToken]
+	WithTx(tx *gorm.DB) PersonalAccessTokenRepository
+	FindByUserID(userID int64) ([]model.PersonalAccessToken, error)
+	FindByUUIDAndUserID(uuid string, userID int64) (*model.PersonalAccessToken, error)
+	FindByTokenHash(tokenHash string) (*model.PersonalAccessToken, error)
+	CountActiveByUserID(userID int64, now time.Time) (int64, error)
+	Revoke(personalAccessTokenID int64, revokedAt time.Time) error
+	TouchLastUsed(personalAccessTokenID int64, usedAt time.Time, minInterval time.Duration) error
+}
+
+type personalAccessTokenRepository struct {
+	*BaseRepository[model.PersonalAccessToken]
+}
+
+func NewPersonalAccessTokenRepository(db *gorm.DB) PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{
+		BaseRepository: NewBaseRepository[model.PersonalAccessToken](db, "personal_access_token_uuid", "personal_access_token_id"),
+	}
+}
+
+func (r *personalAccessTokenRepository) WithTx(tx *gorm.DB) PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+func (r *personalAccessTokenRepository) FindByUserID(userID int64) ([]model.PersonalAccessToken, error) {
+	var tokens []model.PersonalAccessToken
+	err := r.DB().
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *personalAccessTokenRepository) FindByUUIDAndUserID(uuid string, userID int64) (*model.PersonalAccessToken, error) {
+	var token model.PersonalAccessToken
+	err := r.DB().Where("personal_access_token_uuid = ? AND user_id = ?", uuid, userID).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindByTokenHash finds a token by hash together with its owner's roles and
+// permissions and its tenant, which is everything needed to authenticate a
+// request made with it.
+func (r *personalAccessTokenRepository) FindByTokenHash(tokenHash string) (*model.PersonalAccessToken, error) {
+	var token model.PersonalAccessToken
+	err := r.DB().
+		Preload("User.Roles.Permissions").
+		Preload("Tenant").
+		Where("token_hash = ?", tokenHash).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// CountActiveByUserID counts the user's tokens that are neither revoked nor
+// expired at now.
+func (r *personalAccessTokenRepository) CountActiveByUserID(userID int64, now time.Time) (int64, error) {
+	var count int64
+	err := r.DB().Model(&model.PersonalAccessToken{}).
+		Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", userID, now).
+		Count(&count).Error
+	return count, err
+}
+
+// Revoke sets revoked_at on a token that has not been revoked yet.
+func (r *personalAccessTokenRepository) Revoke(personalAccessTokenID int64, revokedAt time.Time) error {
+	return r.DB().Model(&model.PersonalAccessToken{}).
+		Where("personal_access_token_id = ? AND revoked_at IS NULL", personalAccessTokenID).
+		UpdateColumns(map[string]any{
+			"revoked_at": revokedAt,
+			"updated_at": revokedAt,
+		}).Error
+}
+
+// TouchLastUsed moves last_used_at to usedAt unless it was already updated
+// within minInterval, so a busy token does not write on every request.
+func (r *personalAccessTokenRepository) TouchLastUsed(personalAccessTokenID int64, usedAt time.Time, minInterval time.Duration) error {
+	return r.DB().Model(&model.PersonalAccessToken{}).
+		Where("personal_access_token_id = ? AND (last_used_at IS NULL OR last_used_at < ?)", personalAccessTokenID, usedAt.Add(-minInterval)).
+		UpdateColumn("last_used_at", usedAt).Error
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+)
+
+// PlanRepository defines persistence operations for billing plans.
+type PlanRepository interface {
+	BaseRepositoryMethods[model.Plan]
+	WithTx(tx *gorm.DB) PlanRepository
+	// FindAllOrdered returns every plan, the default first, then by name.
+	FindAllOrdered() ([]model.Plan, error)
+	FindByName(name string) (*model.Plan, error)
+	// FindDefault returns the plan of tenants without one, or nil when no
+	// plan is the default.
+	FindDefault() (*model.Plan, error)
+	UnsetDefault() error
+	// CountTenants returns the number of tenants subscribed to the plan.
+	CountTenants(planID int64) (int64, error)
+}
+
+type planRepository struct {
+	*BaseRepository[model.Plan]
+}
+
+// NewPlanRepository creates a new PlanRepository backed by the given
+// database connection.
+func NewPlanRepository(db *gorm.DB) PlanRepository {
+	return &planRepository{
+		BaseRepository: NewBaseRepository[model.Plan](db, "plan_uuid", "plan_id"),
+	}
+}
+
+// WithTx returns a copy of the repository bound to the supplied transaction.
+func (r *planRepository) WithTx(tx *gorm.DB) PlanRepository {
+	return &planRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+func (r *planRepository) FindAllOrdered() ([]model.Plan, error) {
+	var plans []model.Plan
+	err := r.DB().Order("is_default DESC, name ASC").Find(&plans).Error
+	return plans, err
+}
+
+func (r *planRepository) FindByName(name string) (*model.Plan, error) {
+	var plan model.Plan
+	err := r.DB().Where("name = ?", name).First(&plan).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *planRepository) FindDefault() (*model.Plan, error) {
+	var plan model.Plan
+	err := r.DB().Where("is_default = ?", true).First(&plan).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *planRepository) UnsetDefault() error {
+	return r.DB().Model(&model.Plan{}).Where("is_default = ?", true).Update("is_default", false).Error
+}
+
+func (r *planRepository) CountTenants(planID int64) (int64, error) {
+	var count int64
+	err := r.DB().Model(&model.Tenant{}).Where("plan_id = ?", planID).Count(&count).Error
+	return count, err
+}
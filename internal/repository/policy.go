@@ -29,6 +29,7 @@ type PolicyRepository interface {
 	FindByName(policyName string, tenantID int64) (*model.Policy, error)
 	FindByNameAndVersion(policyName string, version string, tenantID int64) (*model.Policy, error)
 	FindSystemPolicies(tenantID int64) ([]model.Policy, error)
+	FindActiveByTenantID(tenantID int64) ([]model.Policy, error)
 	FindPaginated(filter PolicyRepositoryGetFilter) (*PaginationResult[model.Policy], error)
 	SetStatusByUUID(policyUUID uuid.UUID, tenantID int64, status string) error
 	SetSystemStatusByUUID(policyUUID uuid.UUID, tenantID int64, isSystem bool) error
@@ -99,6 +100,15 @@ func (r *policyRepository) FindSystemPolicies(tenantID int64) ([]model.Policy, e
 	return policies, err
 }
 
+func (r *policyRepository) FindActiveByTenantID(tenantID int64) ([]model.Policy, error) {
+	var policies []model.Policy
+	err := r.DB().
+		Where("tenant_id = ? AND status = ?", tenantID, model.StatusActive).
+		Order("policy_id").
+		Find(&policies).Error
+	return policies, err
+}
+
 func (r *policyRepository) SetStatusByUUID(policyUUID uuid.UUID, tenantID int64, status string) error {
 	return r.DB().Model(&model.Policy{}).
 		Where("policy_uuid = ? AND tenant_id = ?", policyUUID, tenantID).
@@ -112,7 +122,7 @@ func (r *policyRepository) SetSystemStatusByUUID(policyUUID uuid.UUID, tenantID
 }
 
 func (r *policyRepository) FindPaginated(filter PolicyRepositoryGetFilter) (*PaginationResult[model.Policy], error) {
-	query := r.DB().Model(&model.Policy{})
+	query := r.ReadDB().Model(&model.Policy{})
 
 	// Filter by tenant_id
 	query = query.Where("tenant_id = ?", filter.TenantID)
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PolicyDocumentRepositoryGetFilter holds query parameters for paginated
+// policy document lookups.
+type PolicyDocumentRepositoryGetFilter struct {
+	TenantID  int64
+	Type      []string
+	Published *bool
+	Page      int
+	Limit     int
+	SortBy    string
+	SortOrder string
+}
+
+// PolicyAcceptanceRepositoryGetFilter holds query parameters for paginated
+// policy acceptance lookups. At least one of PolicyDocumentID and UserID is
+// set by callers.
+type PolicyAcceptanceRepositoryGetFilter struct {
+	TenantID         int64
+	PolicyDocumentID *int64
+	UserID           *int64
+	Page             int
+	Limit            int
+}
+
+// PolicyDocumentRepository defines persistence operations for policy
+// documents and their acceptances.
+type PolicyDocumentRepository interface {
+	BaseRepositoryMethods[model.PolicyDocument]
+	WithTx(tx *gorm.DB) PolicyDocumentRepository
+	// FindByUUIDAndTenantID returns the document, or nil when the tenant has
+	// none with that UUID.
+	FindByUUIDAndTenantID(policyDocumentUUID uuid.UUID, tenantID int64) (*model.PolicyDocument, error)
+	// FindByTypeAndVersion returns the tenant's document of the type with
+	// that version, or nil.
+	FindByTypeAndVersion(tenantID int64, docType, version string) (*model.PolicyDocument, error)
+	FindPaginated(filter PolicyDocumentRepositoryGetFilter) (*PaginationResult[model.PolicyDocument], error)
+	// FindCurrent returns the most recently published version of each
+	// document type of the tenant.
+	FindCurrent(tenantID int64) ([]model.PolicyDocument, error)
+	// FindAcceptedDocumentIDs returns which of documentIDs the user has
+	// accepted.
+	FindAcceptedDocumentIDs(userID int64, documentIDs []int64) ([]int64, error)
+	// Accept records the acceptances, keeping the first one when a user
+	// accepts a version again.
+	Accept(acceptances []model.PolicyAcceptance) error
+	// FindAcceptancesPaginated returns acceptances, newest first, with their
+	// document and user.
+	FindAcceptancesPaginated(filter PolicyAcceptanceRepositoryGetFilter) (*PaginationResult[model.PolicyAcceptance], error)
+}
+
+type policyDocumentRepository struct {
+	*BaseRepository[model.PolicyDocument]
+}
+
+// NewPolicyDocumentRepository creates a new PolicyDocumentRepository backed
+// by the given database connection.
+func NewPolicyDocumentRepository(db *gorm.DB) PolicyDocumentRepository {
+	return &policyDocumentRepository{
+		BaseRepository: NewBaseRepository[model.PolicyDocument](db, "policy_document_uuid", "policy_document_id"),
+	}
+}
+
+// WithTx returns a copy of the repository bound to the supplied transaction.
+func (r *policyDocumentRepository) WithTx(tx *gorm.DB) PolicyDocumentRepository {
+	return &policyDocumentRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+func (r *policyDocumentRepository) FindByUUIDAndTenantID(policyDocumentUUID uuid.UUID, tenantID int64) (*model.PolicyDocument, error) {
+	var doc model.PolicyDocument
+	err := r.DB().Where("policy_document_uuid = ? AND tenant_id = ?", policyDocumentUUID, tenantID).First(&doc).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (r *policyDocumentRepository) FindByTypeAndVersion(tenantID int64, docType, version string) (*model.PolicyDocument, error) {
+	var doc model.PolicyDocument
+	err := r.DB().Where("tenant_id = ? AND type = ? AND version = ?", tenantID, docType, version).First(&doc).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (r *policyDocumentRepository) FindPaginated(filter PolicyDocumentRepositoryGetFilter) (*PaginationResult[model.PolicyDocument], error) {
+	query := r.ReadDB().Model(&model.PolicyDocument{}).Where("tenant_id = ?", filter.TenantID)
+
+	if len(filter.Type) > 0 {
+		query = query.Where("type IN ?", filter.Type)
+	}
+	if filter.Published != nil {
+		if *filter.Published {
+			query = query.Where("published_at IS NOT NULL")
+		} else {
+			query = query.Where("published_at IS NULL")
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page, limit := normalizePagination(filter.Page, filter.Limit)
+
+	var docs []model.PolicyDocument
+	err := query.
+		Order(sanitizeOrder(filter.SortBy, filter.SortOrder, "created_at DESC")).
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&docs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaginationResult[model.PolicyDocument]{
+		Data:       docs,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, nil
+}
+
+func (r *policyDocumentRepository) FindCurrent(tenantID int64) ([]model.PolicyDocument, error) {
+	var docs []model.PolicyDocument
+	err := r.DB().
+		Raw(`SELECT DISTINCT ON (type) * FROM policy_documents
+			WHERE tenant_id = ? AND published_at IS NOT NULL
+			ORDER BY type, published_at DESC, policy_document_id DESC`, tenantID).
+		Scan(&docs).Error
+	return docs, err
+}
+
+func (r *policyDocumentRepository) FindAcceptedDocumentIDs(userID int64, documentIDs []int64) ([]int64, error) {
+	if len(documentIDs) == 0 {
+		return nil, nil
+	}
+	var ids []int64
+	err := r.DB().Model(&model.PolicyAcceptance{}).
+		Where("user_id = ? AND policy_document_id IN ?", userID, documentIDs).
+		Pluck("policy_document_id", &ids).Error
+	return ids, err
+}
+
+func (r *policyDocumentRepository) Accept(acceptances []model.PolicyAcceptance) error {
+	if len(acceptances) == 0 {
+		return nil
+	}
+	return r.DB().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "policy_document_id"}, {Name: "user_id"}},
+		DoNothing: true,
+	}).Create(&acceptances).Error
+}
+
+func (r *policyDocumentRepository) FindAcceptancesPaginated(filter PolicyAcceptanceRepositoryGetFilter) (*PaginationResult[model.PolicyAcceptance], error) {
+	query := r.ReadDB().Model(&model.PolicyAcceptance{}).Where("tenant_id = ?", filter.TenantID)
+
+	if filter.PolicyDocumentID != nil {
+		query = query.Where("policy_document_id = ?", *filter.PolicyDocumentID)
+	}
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page, limit := normalizePagination(filter.Page, filter.Limit)
+
+	var acceptances []model.PolicyAcceptance
+	err := query.
+		Preload("PolicyDocument").
+		Preload("User").
+		Order("accepted_at DESC, policy_acceptance_id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&acceptances).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaginationResult[model.PolicyAcceptance]{
+		Data:       acceptances,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, nil
+}
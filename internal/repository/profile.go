@@ -2,12 +2,26 @@ package repository
 
 import (
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
+	"github.com/maintainerd/auth/internal/crypto"
 	"github.com/maintainerd/auth/internal/model"
 	"gorm.io/gorm"
 )
 
+// Encryption context and blind index scope for each sensitive profile column.
+const (
+	profileFieldBirthdate = "profiles.birthdate"
+	profileFieldPhone     = "profiles.phone"
+	profileFieldAddress   = "profiles.address"
+)
+
+// profileBirthdateLayout is the plaintext format birthdates are encrypted and
+// indexed in.
+const profileBirthdateLayout = "2006-01-02"
+
 type ProfileRepositoryGetFilter struct {
 	UserID    int64
 	FirstName *string
@@ -34,20 +48,105 @@ type ProfileRepository interface {
 	UnsetDefaultProfiles(userID int64) error
 }
 
+// profileRepository transparently encrypts birthdate, phone and address when
+// an encryptor is configured and the profile owner belongs to a tenant with
+// the profile_encryption feature flag enabled. Reads always decrypt, so
+// toggling the flag never makes existing rows unreadable.
 type profileRepository struct {
 	*BaseRepository[model.Profile]
+	encryptor *crypto.FieldEncryptor
 }
 
-func NewProfileRepository(db *gorm.DB) ProfileRepository {
+// NewProfileRepository creates a ProfileRepository. encryptor may be nil, in
+// which case sensitive fields are stored in plaintext.
+func NewProfileRepository(db *gorm.DB, encryptor *crypto.FieldEncryptor) ProfileRepository {
 	return &profileRepository{
 		BaseRepository: NewBaseRepository[model.Profile](db, "profile_uuid", "profile_id"),
+		encryptor:      encryptor,
 	}
 }
 
 func (r *profileRepository) WithTx(tx *gorm.DB) ProfileRepository {
 	return &profileRepository{
 		BaseRepository: r.BaseRepository.WithTx(tx),
+		encryptor:      r.encryptor,
+	}
+}
+
+func (r *profileRepository) Create(entity *model.Profile) (*model.Profile, error) {
+	restore, err := r.seal(entity)
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+	return r.BaseRepository.Create(entity)
+}
+
+func (r *profileRepository) CreateOrUpdate(entity *model.Profile) (*model.Profile, error) {
+	restore, err := r.seal(entity)
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+	return r.BaseRepository.CreateOrUpdate(entity)
+}
+
+func (r *profileRepository) FindAll(preloads ...string) ([]model.Profile, error) {
+	profiles, err := r.BaseRepository.FindAll(preloads...)
+	if err != nil {
+		return nil, err
+	}
+	return profiles, r.openAll(profiles)
+}
+
+func (r *profileRepository) FindByUUID(uuid any, preloads ...string) (*model.Profile, error) {
+	return r.openOne(r.BaseRepository.FindByUUID(uuid, preloads...))
+}
+
+func (r *profileRepository) FindByUUIDs(uuids []string, preloads ...string) ([]model.Profile, error) {
+	profiles, err := r.BaseRepository.FindByUUIDs(uuids, preloads...)
+	if err != nil {
+		return nil, err
+	}
+	return profiles, r.openAll(profiles)
+}
+
+func (r *profileRepository) FindByID(id any, preloads ...string) (*model.Profile, error) {
+	return r.openOne(r.BaseRepository.FindByID(id, preloads...))
+}
+
+// UpdateByUUID encrypts updatedData when it is a profile struct. Map updates
+// are written as-is and must not carry sensitive columns.
+func (r *profileRepository) UpdateByUUID(uuid any, updatedData any) (*model.Profile, error) {
+	if p, ok := updatedData.(*model.Profile); ok {
+		restore, err := r.seal(p)
+		if err != nil {
+			return nil, err
+		}
+		defer restore()
+	}
+	return r.openOne(r.BaseRepository.UpdateByUUID(uuid, updatedData))
+}
+
+// UpdateByID encrypts updatedData when it is a profile struct. Map updates are
+// written as-is and must not carry sensitive columns.
+func (r *profileRepository) UpdateByID(id any, updatedData any) (*model.Profile, error) {
+	if p, ok := updatedData.(*model.Profile); ok {
+		restore, err := r.seal(p)
+		if err != nil {
+			return nil, err
+		}
+		defer restore()
 	}
+	return r.openOne(r.BaseRepository.UpdateByID(id, updatedData))
+}
+
+func (r *profileRepository) Paginate(conditions map[string]any, page int, limit int, preloads ...string) (*PaginationResult[model.Profile], error) {
+	result, err := r.BaseRepository.Paginate(conditions, page, limit, preloads...)
+	if err != nil {
+		return nil, err
+	}
+	return result, r.openAll(result.Data)
 }
 
 func (r *profileRepository) FindByUserID(userID int64) (*model.Profile, error) {
@@ -59,6 +158,9 @@ func (r *profileRepository) FindByUserID(userID int64) (*model.Profile, error) {
 		}
 		return nil, err
 	}
+	if err := r.open(&profile); err != nil {
+		return nil, err
+	}
 	return &profile, nil
 }
 
@@ -71,6 +173,9 @@ func (r *profileRepository) FindDefaultByUserID(userID int64) (*model.Profile, e
 		}
 		return nil, err
 	}
+	if err := r.open(&profile); err != nil {
+		return nil, err
+	}
 	return &profile, nil
 }
 
@@ -91,7 +196,13 @@ func (r *profileRepository) FindAllByUserID(filter ProfileRepositoryGetFilter) (
 		query = query.Where("LOWER(email) LIKE ?", "%"+strings.ToLower(*filter.Email)+"%")
 	}
 	if filter.Phone != nil && *filter.Phone != "" {
-		query = query.Where("phone LIKE ?", "%"+*filter.Phone+"%")
+		if r.encryptor != nil {
+			// Encrypted phones only support exact matches via the blind index
+			bidx := r.encryptor.BlindIndex(profileFieldPhone, normalizeProfilePhone(*filter.Phone))
+			query = query.Where("(phone LIKE ? OR phone_bidx = ?)", "%"+*filter.Phone+"%", bidx)
+		} else {
+			query = query.Where("phone LIKE ?", "%"+*filter.Phone+"%")
+		}
 	}
 	if filter.City != nil && *filter.City != "" {
 		query = query.Where("LOWER(city) LIKE ?", "%"+strings.ToLower(*filter.City)+"%")
@@ -123,6 +234,10 @@ func (r *profileRepository) FindAllByUserID(filter ProfileRepositoryGetFilter) (
 		return nil, err
 	}
 
+	if err := r.openAll(profiles); err != nil {
+		return nil, err
+	}
+
 	totalPages := int((total + int64(filter.Limit) - 1) / int64(filter.Limit))
 	return &PaginationResult[model.Profile]{
 		Data:       profiles,
@@ -134,9 +249,25 @@ func (r *profileRepository) FindAllByUserID(filter ProfileRepositoryGetFilter) (
 }
 
 func (r *profileRepository) UpdateByUserID(userID int64, updatedProfile *model.Profile) error {
+	restore, err := r.seal(updatedProfile)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	if err := r.DB().Model(&model.Profile{}).
+		Where("user_id = ?", userID).
+		Updates(updatedProfile).Error; err != nil {
+		return err
+	}
+	if r.encryptor == nil {
+		return nil
+	}
+	// Updates skips nil fields, so write the sensitive columns explicitly to
+	// clear whichever representation (plaintext or encrypted) is now unused.
 	return r.DB().Model(&model.Profile{}).
 		Where("user_id = ?", userID).
-		Updates(updatedProfile).Error
+		Updates(sensitiveProfileColumns(updatedProfile)).Error
 }
 
 func (r *profileRepository) DeleteByUserID(userID int64) error {
@@ -147,3 +278,158 @@ func (r *profileRepository) UnsetDefaultProfiles(userID int64) error {
 		Where("user_id = ? AND is_default = ?", userID, true).
 		Update("is_default", false).Error
 }
+
+// encryptionEnabledForUser reports whether any tenant the user belongs to has
+// the profile encryption feature flag enabled.
+func (r *profileRepository) encryptionEnabledForUser(userID int64) (bool, error) {
+	var enabled bool
+	err := r.DB().Raw(`
+SELECT EXISTS (
+    SELECT 1
+    FROM user_identities ui
+    JOIN tenant_settings ts ON ts.tenant_id = ui.tenant_id
+    WHERE ui.user_id = ? AND ts.feature_flags ->> ? = 'true'
+)`, userID, model.FeatureFlagProfileEncryption).Scan(&enabled).Error
+	return enabled, err
+}
+
+// seal prepares p for writing. When encryption applies, the sensitive fields
+// are moved into their encrypted columns and blind indexes are computed;
+// otherwise any encrypted columns are cleared. The returned func restores the
+// plaintext fields on p so callers keep seeing decrypted values.
+func (r *profileRepository) seal(p *model.Profile) (func(), error) {
+	noop := func() {}
+	if r.encryptor == nil {
+		return noop, nil
+	}
+
+	enabled, err := r.encryptionEnabledForUser(p.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	p.BirthdateEncrypted, p.PhoneEncrypted, p.AddressEncrypted = nil, nil, nil
+	p.BirthdateBlindIndex, p.PhoneBlindIndex = nil, nil
+	if !enabled {
+		return noop, nil
+	}
+
+	birthdate, phone, address := p.Birthdate, p.Phone, p.Address
+	if birthdate != nil {
+		value := birthdate.Format(profileBirthdateLayout)
+		if p.BirthdateEncrypted, err = r.encrypt(profileFieldBirthdate, value); err != nil {
+			return nil, err
+		}
+		bidx := r.encryptor.BlindIndex(profileFieldBirthdate, value)
+		p.BirthdateBlindIndex = &bidx
+	}
+	if phone != nil {
+		if p.PhoneEncrypted, err = r.encrypt(profileFieldPhone, *phone); err != nil {
+			return nil, err
+		}
+		bidx := r.encryptor.BlindIndex(profileFieldPhone, normalizeProfilePhone(*phone))
+		p.PhoneBlindIndex = &bidx
+	}
+	if address != nil {
+		if p.AddressEncrypted, err = r.encrypt(profileFieldAddress, *address); err != nil {
+			return nil, err
+		}
+	}
+	p.Birthdate, p.Phone, p.Address = nil, nil, nil
+
+	return func() {
+		p.Birthdate, p.Phone, p.Address = birthdate, phone, address
+	}, nil
+}
+
+func (r *profileRepository) encrypt(field, value string) (*string, error) {
+	ct, err := r.encryptor.Encrypt(field, value)
+	if err != nil {
+		return nil, err
+	}
+	return &ct, nil
+}
+
+// open decrypts any encrypted sensitive fields on p into their plaintext
+// counterparts.
+func (r *profileRepository) open(p *model.Profile) error {
+	if p.BirthdateEncrypted == nil && p.PhoneEncrypted == nil && p.AddressEncrypted == nil {
+		return nil
+	}
+	if r.encryptor == nil {
+		return errors.New("profile has encrypted fields but no profile encryption key is configured")
+	}
+
+	if p.BirthdateEncrypted != nil {
+		value, err := r.encryptor.Decrypt(profileFieldBirthdate, *p.BirthdateEncrypted)
+		if err != nil {
+			return err
+		}
+		birthdate, err := time.Parse(profileBirthdateLayout, value)
+		if err != nil {
+			return fmt.Errorf("decrypted birthdate is malformed: %w", err)
+		}
+		p.Birthdate = &birthdate
+	}
+	if p.PhoneEncrypted != nil {
+		value, err := r.encryptor.Decrypt(profileFieldPhone, *p.PhoneEncrypted)
+		if err != nil {
+			return err
+		}
+		p.Phone = &value
+	}
+	if p.AddressEncrypted != nil {
+		value, err := r.encryptor.Decrypt(profileFieldAddress, *p.AddressEncrypted)
+		if err != nil {
+			return err
+		}
+		p.Address = &value
+	}
+	return nil
+}
+
+func (r *profileRepository) openOne(p *model.Profile, err error) (*model.Profile, error) {
+	if err != nil || p == nil {
+		return p, err
+	}
+	if err := r.open(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *profileRepository) openAll(profiles []model.Profile) error {
+	for i := range profiles {
+		if err := r.open(&profiles[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sensitiveProfileColumns returns every plaintext and encrypted sensitive
+// column of p, including nil values, for use with Updates.
+func sensitiveProfileColumns(p *model.Profile) map[string]any {
+	return map[string]any{
+		"birthdate":           p.Birthdate,
+		"phone":               p.Phone,
+		"address":             p.Address,
+		"birthdate_encrypted": p.BirthdateEncrypted,
+		"phone_encrypted":     p.PhoneEncrypted,
+		"address_encrypted":   p.AddressEncrypted,
+		"birthdate_bidx":      p.BirthdateBlindIndex,
+		"phone_bidx":          p.PhoneBlindIndex,
+	}
+}
+
+// normalizeProfilePhone strips formatting so blind indexes match regardless
+// of spacing or punctuation. A leading "+" is kept.
+func normalizeProfilePhone(phone string) string {
+	var b strings.Builder
+	for i, c := range strings.TrimSpace(phone) {
+		if (c >= '0' && c <= '9') || (c == '+' && i == 0) {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
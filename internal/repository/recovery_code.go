@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+)
+
+// RecoveryCodeRepository defines persistence operations for users' backup
+// recovery codes.
+type RecoveryCodeRepository interface {
+	BaseRepositoryMethods[model.RecoveryCode]
+	WithTx(tx *gorm.DB) RecoveryCodeRepository
+	// ReplaceByUserID deletes the user's codes, used or not, and stores the
+	// hashed codes in their place.
+	ReplaceByUserID(userID int64, codeHashes []string) error
+	// CountUnusedByUserID returns how many of the user's codes are left.
+	CountUnusedByUserID(userID int64) (int64, error)
+	// Consume marks the user's unused code with the hash as used and reports
+	// whether there was one. A code is consumed at most once, even by
+	// concurrent calls.
+	Consume(userID int64, codeHash string, usedAt time.Time) (bool, error)
+}
+
+type recoveryCodeRepository struct {
+	*BaseRepository[model.RecoveryCode]
+}
+
+// NewRecoveryCodeRepository creates a new RecoveryCodeRepository backed by
+// the given database connection.
+func NewRecoveryCodeRepository(db *gorm.DB) RecoveryCodeRepository {
+	return &recoveryCodeRepository{
+		BaseRepository: NewBaseRepository[model.RecoveryCode](db, "recovery_code_uuid", "recovery_code_id"),
+	}
+}
+
+// WithTx returns a copy of the repository bound to the supplied transaction.
+func (r *recoveryCodeRepository) WithTx(tx *gorm.DB) RecoveryCodeRepository {
+	return &recoveryCodeRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+func (r *recoveryCodeRepository) ReplaceByUserID(userID int64, codeHashes []string) error {
+	if err := r.DB().Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]model.RecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, model.RecoveryCode{UserID: userID, CodeHash: hash})
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return r.DB().Create(&codes).Error
+}
+
+func (r *recoveryCodeRepository) CountUnusedByUserID(userID int64) (int64, error) {
+	var count int64
+	err := r.DB().Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *recoveryCodeRepository) Consume(userID int64, codeHash string, usedAt time.Time) (bool, error) {
+	result := r.DB().Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -15,10 +15,14 @@ type RoleRepositoryGetFilter struct {
 	IsSystem    *bool
 	Status      *string
 	TenantID    int64
-	Page        int
-	Limit       int
-	SortBy      string
-	SortOrder   string
+	// IncludeInherited adds the inheritable roles of the tenant's ancestors.
+	IncludeInherited bool
+	// UserID, when set, limits the roles to those assigned to the user.
+	UserID    *int64
+	Page      int
+	Limit     int
+	SortBy    string
+	SortOrder string
 }
 
 type RoleRepositoryGetPermissionsFilter struct {
@@ -39,6 +43,7 @@ type RoleRepository interface {
 	GetPermissionsByRoleUUID(filter RoleRepositoryGetPermissionsFilter) (*PaginationResult[model.Permission], error)
 	SetStatusByUUID(roleUUID uuid.UUID, status string) error
 	SetDefaultStatusByUUID(roleUUID uuid.UUID, isDefault bool) error
+	UnsetDefaultByTenantID(tenantID int64) error
 	SetSystemStatusByUUID(roleUUID uuid.UUID, isSystem bool) error
 	FindRegisteredRoleForSetup(tenantID int64) (*model.Role, error)
 	FindSuperAdminRoleForSetup(tenantID int64) (*model.Role, error)
@@ -87,10 +92,15 @@ func (r *roleRepository) FindAllByTenantID(tenantID int64) ([]model.Role, error)
 }
 
 func (r *roleRepository) FindPaginated(filter RoleRepositoryGetFilter) (*PaginationResult[model.Role], error) {
-	query := r.DB().Model(&model.Role{})
+	query := r.ReadDB().Model(&model.Role{})
 
 	// Always filter
-	query = query.Where("tenant_id = ?", filter.TenantID)
+	if filter.IncludeInherited {
+		query = query.Where("tenant_id = ? OR (is_inheritable = true AND (SELECT ancestor_path FROM tenants WHERE tenants.tenant_id = ?) LIKE '%/' || roles.tenant_id || '/%')",
+			filter.TenantID, filter.TenantID)
+	} else {
+		query = query.Where("tenant_id = ?", filter.TenantID)
+	}
 
 	// Filters with LIKE
 	if filter.Name != nil {
@@ -110,6 +120,9 @@ func (r *roleRepository) FindPaginated(filter RoleRepositoryGetFilter) (*Paginat
 	if filter.Status != nil {
 		query = query.Where("status = ?", *filter.Status)
 	}
+	if filter.UserID != nil {
+		query = query.Where("role_id IN (?)", r.DB().Table("user_roles").Select("role_id").Where("user_id = ?", *filter.UserID))
+	}
 
 	// Sorting — protected against SQL injection via allowlist
 	query = query.Order(sanitizeOrder(filter.SortBy, filter.SortOrder, "created_at DESC"))
@@ -151,6 +164,12 @@ func (r *roleRepository) SetDefaultStatusByUUID(roleUUID uuid.UUID, isDefault bo
 		Update("is_default", isDefault).Error
 }
 
+func (r *roleRepository) UnsetDefaultByTenantID(tenantID int64) error {
+	return r.DB().Model(&model.Role{}).
+		Where("tenant_id = ? AND is_default = ?", tenantID, true).
+		Update("is_default", false).Error
+}
+
 func (r *roleRepository) SetSystemStatusByUUID(roleUUID uuid.UUID, isSystem bool) error {
 	return r.DB().Model(&model.Role{}).
 		Where("role_uuid = ?", roleUUID).
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+)
+
+// ScopedPermissionGrant is a permission a user holds on a single client or
+// group through a scoped role assignment.
+type ScopedPermissionGrant struct {
+	ResourceType   string    `gorm:"column:resource_type"`
+	ResourceUUID   uuid.UUID `gorm:"column:resource_uuid"`
+	PermissionName string    `gorm:"column:permission_name"`
+}
+
+type ScopedUserRoleRepository interface {
+	BaseRepositoryMethods[model.ScopedUserRole]
+	WithTx(tx *gorm.DB) ScopedUserRoleRepository
+	// FindByUserID returns the user's scoped assignments with their role and
+	// resource, oldest first.
+	FindByUserID(userID int64) ([]model.ScopedUserRole, error)
+	FindByUUIDAndUserID(scopedUserRoleUUID uuid.UUID, userID int64) (*model.ScopedUserRole, error)
+	// FindExisting returns the assignment of the role to the user on the
+	// client or group, exactly one of which is set, if there is one.
+	FindExisting(userID int64, roleID int64, clientID *int64, groupID *int64) (*model.ScopedUserRole, error)
+	// FindPermissionGrants returns the distinct permissions of the user's
+	// scoped assignments with the resource each applies to, in one query.
+	FindPermissionGrants(userID int64) ([]ScopedPermissionGrant, error)
+}
+
+type scopedUserRoleRepository struct {
+	*BaseRepository[model.ScopedUserRole]
+}
+
+func NewScopedUserRoleRepository(db *gorm.DB) ScopedUserRoleRepository {
+	return &scopedUserRoleRepository{
+		BaseRepository: NewBaseRepository[model.ScopedUserRole](db, "scoped_user_role_uuid", "scoped_user_role_id"),
+	}
+}
+
+func (r *scopedUserRoleRepository) WithTx(tx *gorm.DB) ScopedUserRoleRepository {
+	return &scopedUserRoleRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+func (r *scopedUserRoleRepository) FindByUserID(userID int64) ([]model.ScopedUserRole, error) {
+	var assignments []model.ScopedUserRole
+	err := r.DB().
+		Preload("Role").
+		Preload("Client").
+		Preload("Group").
+		Where("user_id = ?", userID).
+		Order("created_at, scoped_user_role_id").
+		Find(&assignments).Error
+	return assignments, err
+}
+
+func (r *scopedUserRoleRepository) FindByUUIDAndUserID(scopedUserRoleUUID uuid.UUID, userID int64) (*model.ScopedUserRole, error) {
+	var assignment model.ScopedUserRole
+	err := r.DB().
+		Preload("Role").
+		Preload("Client").
+		Preload("Group").
+		Where("scoped_user_role_uuid = ? AND user_id = ?", scopedUserRoleUUID, userID).
+		First(&assignment).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &assignment, nil
+}
+
+func (r *scopedUserRoleRepository) FindExisting(userID int64, roleID int64, clientID *int64, groupID *int64) (*model.ScopedUserRole, error) {
+	query := r.DB().Where("user_id = ? AND role_id = ?", userID, roleID)
+	if clientID != nil {
+		query = query.Where("client_id = ?", *clientID)
+	} else {
+		query = query.Where("group_id = ?", groupID)
+	}
+
+	var assignment model.ScopedUserRole
+	if err := query.First(&assignment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &assignment, nil
+}
+
+func (r *scopedUserRoleRepository) FindPermissionGrants(userID int64) ([]ScopedPermissionGrant, error) {
+	var grants []ScopedPermissionGrant
+	err := r.DB().
+		Table("scoped_user_roles sur").
+		Select("DISTINCT CASE WHEN sur.group_id IS NULL THEN ? ELSE ? END AS resource_type, "+
+			"COALESCE(c.client_uuid, g.group_uuid) AS resource_uuid, p.name AS permission_name",
+			model.ScopedRoleResourceClient, model.ScopedRoleResourceGroup).
+		Joins("LEFT JOIN clients c ON c.client_id = sur.client_id").
+		Joins("LEFT JOIN groups g ON g.group_id = sur.group_id").
+		Joins("JOIN role_permissions rp ON rp.role_id = sur.role_id").
+		Joins("JOIN permissions p ON p.permission_id = rp.permission_id").
+		Where("sur.user_id = ?", userID).
+		Order("resource_type, resource_uuid, permission_name").
+		Scan(&grants).Error
+	return grants, err
+}
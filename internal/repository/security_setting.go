@@ -55,7 +55,7 @@ func (r *securitySettingRepository) FindByUserPoolID(tenantID int64) (*model.Sec
 }
 
 func (r *securitySettingRepository) FindPaginated(filter SecuritySettingRepositoryGetFilter) (*PaginationResult[model.SecuritySetting], error) {
-	query := r.DB().Model(&model.SecuritySetting{})
+	query := r.ReadDB().Model(&model.SecuritySetting{})
 
 	// Apply filters
 	if filter.UserPoolID != nil {
@@ -59,7 +59,7 @@ func (r *securitySettingsAuditRepository) FindByUserPoolID(tenantID int64) ([]mo
 }
 
 func (r *securitySettingsAuditRepository) FindPaginated(filter SecuritySettingsAuditRepositoryGetFilter) (*PaginationResult[model.SecuritySettingsAudit], error) {
-	query := r.DB().Model(&model.SecuritySettingsAudit{})
+	query := r.ReadDB().Model(&model.SecuritySettingsAudit{})
 
 	// Apply filters
 	if filter.UserPoolID != nil {
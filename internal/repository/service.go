@@ -87,7 +87,7 @@ func (r *serviceRepository) FindByTenantID(tenantID int64) ([]model.Service, err
 }
 
 func (r *serviceRepository) FindPaginated(filter ServiceRepositoryGetFilter) (*PaginationResult[model.Service], error) {
-	query := r.DB().Model(&model.Service{})
+	query := r.ReadDB().Model(&model.Service{})
 
 	// Filters with LIKE
 	if filter.Name != nil {
@@ -77,7 +77,7 @@ func (r *servicePolicyRepository) FindServicesByPolicyID(policyID int64) ([]mode
 }
 
 func (r *servicePolicyRepository) FindPaginated(filter ServicePolicyRepositoryGetFilter) (*PaginationResult[model.ServicePolicy], error) {
-	query := r.DB().Model(&model.ServicePolicy{})
+	query := r.ReadDB().Model(&model.ServicePolicy{})
 
 	// Apply filters
 	if filter.ServiceID != nil {
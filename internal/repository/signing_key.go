@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SigningKeyRepository interface {
+	BaseRepositoryMethods[model.SigningKey]
+	WithTx(tx *gorm.DB) SigningKeyRepository
+	FindActive() (*model.SigningKey, error)
+	FindActiveForUpdate() (*model.SigningKey, error)
+	FindUnretired() ([]model.SigningKey, error)
+	FindAllOrdered() ([]model.SigningKey, error)
+	RetirePreviousRotatedBefore(cutoff time.Time) (int64, error)
+}
+
+type signingKeyRepository struct {
+	*BaseRepository[model.SigningKey]
+}
+
+func NewSigningKeyRepository(db *gorm.DB) SigningKeyRepository {
+	return &signingKeyRepository{
+		BaseRepository: NewBaseRepository[model.SigningKey](db, "signing_key_uuid", "signing_key_id"),
+	}
+}
+
+func (r *signingKeyRepository) WithTx(tx *gorm.DB) SigningKeyRepository {
+	return &signingKeyRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+func (r *signingKeyRepository) FindActive() (*model.SigningKey, error) {
+	return r.findActive(r.DB())
+}
+
+// FindActiveForUpdate locks the active key until the surrounding transaction
+// ends, so that concurrent rotations replace it one at a time.
+func (r *signingKeyRepository) FindActiveForUpdate() (*model.SigningKey, error) {
+	return r.findActive(r.DB().Clauses(clause.Locking{Strength: "UPDATE"}))
+}
+
+func (r *signingKeyRepository) findActive(db *gorm.DB) (*model.SigningKey, error) {
+	var key model.SigningKey
+	err := db.Where("status = ?", model.SigningKeyStatusActive).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindUnretired returns the active key and the previous keys that still
+// verify tokens, newest first.
+func (r *signingKeyRepository) FindUnretired() ([]model.SigningKey, error) {
+	var keys []model.SigningKey
+	err := r.DB().
+		Where("status IN ?", []string{model.SigningKeyStatusActive, model.SigningKeyStatusPrevious}).
+		Order("activated_at DESC").
+		Find(&keys).Error
+	return keys, err
+}
+
+// FindAllOrdered returns every key, newest first.
+func (r *signingKeyRepository) FindAllOrdered() ([]model.SigningKey, error) {
+	var keys []model.SigningKey
+	err := r.DB().Order("activated_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// RetirePreviousRotatedBefore retires the previous keys rotated out before
+// cutoff and drops their private keys.
+func (r *signingKeyRepository) RetirePreviousRotatedBefore(cutoff time.Time) (int64, error) {
+	result := r.DB().Model(&model.SigningKey{}).
+		Where("status = ? AND rotated_at < ?", model.SigningKeyStatusPrevious, cutoff).
+		Updates(map[string]any{
+			"status":      model.SigningKeyStatusRetired,
+			"retired_at":  time.Now(),
+			"private_key": nil,
+		})
+	return result.RowsAffected, result.Error
+}
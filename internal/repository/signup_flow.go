@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SignupFlowRepositoryGetFilter struct {
@@ -27,7 +28,10 @@ type SignupFlowRepository interface {
 	FindPaginated(filter SignupFlowRepositoryGetFilter) (*PaginationResult[model.SignupFlow], error)
 	FindByUUIDAndTenantID(signupFlowUUID uuid.UUID, tenantID int64, preloads ...string) (*model.SignupFlow, error)
 	FindByIdentifierAndClientID(identifier string, clientID int64) (*model.SignupFlow, error)
+	FindActiveByClientID(clientID int64) (*model.SignupFlow, error)
+	LockByID(signupFlowID int64) error
 	FindByName(name string) (*model.SignupFlow, error)
+	FindByNameAndTenantID(name string, tenantID int64) (*model.SignupFlow, error)
 }
 
 type signupFlowRepository struct {
@@ -50,7 +54,7 @@ func (r *signupFlowRepository) FindPaginated(filter SignupFlowRepositoryGetFilte
 	var signupFlows []model.SignupFlow
 	var total int64
 
-	query := r.DB().Model(&model.SignupFlow{})
+	query := r.ReadDB().Model(&model.SignupFlow{})
 
 	// Apply filters
 	if filter.Name != nil && *filter.Name != "" {
@@ -113,6 +117,33 @@ func (r *signupFlowRepository) FindByIdentifierAndClientID(identifier string, cl
 	return &signupFlow, nil
 }
 
+// FindActiveByClientID returns the client's oldest active signup flow, which
+// applies to registrations that do not name a flow.
+func (r *signupFlowRepository) FindActiveByClientID(clientID int64) (*model.SignupFlow, error) {
+	var signupFlow model.SignupFlow
+	err := r.DB().
+		Where("client_id = ? AND status = ?", clientID, model.StatusActive).
+		Order("created_at ASC").
+		First(&signupFlow).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &signupFlow, nil
+}
+
+// LockByID locks the flow row until the surrounding transaction ends, so
+// that concurrent registrations through the flow are counted one at a time.
+func (r *signupFlowRepository) LockByID(signupFlowID int64) error {
+	var signupFlow model.SignupFlow
+	return r.DB().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("signup_flow_id").
+		Where("signup_flow_id = ?", signupFlowID).
+		Take(&signupFlow).Error
+}
+
 func (r *signupFlowRepository) FindByUUIDAndTenantID(signupFlowUUID uuid.UUID, tenantID int64, preloads ...string) (*model.SignupFlow, error) {
 	var signupFlow model.SignupFlow
 	query := r.DB().Where("signup_flow_uuid = ? AND tenant_id = ?", signupFlowUUID, tenantID)
@@ -142,3 +173,17 @@ func (r *signupFlowRepository) FindByName(name string) (*model.SignupFlow, error
 	}
 	return &signupFlow, nil
 }
+
+// FindByNameAndTenantID returns the signup flow with the given name within
+// the tenant.
+func (r *signupFlowRepository) FindByNameAndTenantID(name string, tenantID int64) (*model.SignupFlow, error) {
+	var signupFlow model.SignupFlow
+	err := r.DB().Where("name = ? AND tenant_id = ?", name, tenantID).First(&signupFlow).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &signupFlow, nil
+}
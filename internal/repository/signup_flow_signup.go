@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+)
+
+// SignupFlowSignupRepository defines persistence operations for the accounts
+// created through signup flows.
+type SignupFlowSignupRepository interface {
+	BaseRepositoryMethods[model.SignupFlowSignup]
+	WithTx(tx *gorm.DB) SignupFlowSignupRepository
+	CountBySignupFlowID(signupFlowID int64, since time.Time) (int64, error)
+}
+
+type signupFlowSignupRepository struct {
+	*BaseRepository[model.SignupFlowSignup]
+}
+
+// NewSignupFlowSignupRepository creates a new SignupFlowSignupRepository
+// backed by the given database connection.
+func NewSignupFlowSignupRepository(db *gorm.DB) SignupFlowSignupRepository {
+	return &signupFlowSignupRepository{
+		BaseRepository: NewBaseRepository[model.SignupFlowSignup](db, "signup_flow_signup_uuid", "signup_flow_signup_id"),
+	}
+}
+
+// WithTx returns a copy of the repository bound to the supplied transaction.
+func (r *signupFlowSignupRepository) WithTx(tx *gorm.DB) SignupFlowSignupRepository {
+	return &signupFlowSignupRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+// CountBySignupFlowID counts the flow's signups created at or after since. A
+// zero since counts every signup.
+func (r *signupFlowSignupRepository) CountBySignupFlowID(signupFlowID int64, since time.Time) (int64, error) {
+	var count int64
+	query := r.DB().Model(&model.SignupFlowSignup{}).Where("signup_flow_id = ?", signupFlowID)
+	if !since.IsZero() {
+		query = query.Where("created_at >= ?", since)
+	}
+	err := query.Count(&count).Error
+	return count, err
+}
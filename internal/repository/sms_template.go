@@ -69,7 +69,7 @@ func (r *smsTemplateRepository) FindByUUIDAndTenantID(uuid string, tenantID int6
 
 // FindPaginated retrieves paginated SMS templates with filtering
 func (r *smsTemplateRepository) FindPaginated(filter SMSTemplateRepositoryGetFilter) (*PaginationResult[model.SMSTemplate], error) {
-	query := r.DB().Model(&model.SMSTemplate{})
+	query := r.ReadDB().Model(&model.SMSTemplate{})
 
 	// Apply filters
 	if filter.TenantID != nil {
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"errors"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/model"
@@ -16,10 +18,17 @@ type TenantRepositoryGetFilter struct {
 	Status      []string
 	IsPublic    *bool
 	IsSystem    *bool
-	Page        int
-	Limit       int
-	SortBy      string
-	SortOrder   string
+	// TenantIDs restricts the results to these tenants when not nil.
+	// IncludeDescendants widens it to the tenants below them too.
+	TenantIDs          []int64
+	IncludeDescendants bool
+	// ParentTenantID restricts the results to the direct children of this
+	// tenant when not nil.
+	ParentTenantID *int64
+	Page           int
+	Limit          int
+	SortBy         string
+	SortOrder      string
 }
 
 type TenantRepository interface {
@@ -31,6 +40,11 @@ type TenantRepository interface {
 	FindPaginated(filter TenantRepositoryGetFilter) (*PaginationResult[model.Tenant], error)
 	SetStatusByUUID(tenantUUID uuid.UUID, status string) error
 	SetSystemStatusByUUID(tenantUUID uuid.UUID, isSystem bool) error
+	// SetPlanByUUID subscribes the tenant to the plan with planID, or to
+	// the default plan when it is nil.
+	SetPlanByUUID(tenantUUID uuid.UUID, planID *int64) error
+	CountChildren(tenantID int64) (int64, error)
+	MoveSubtree(tenant *model.Tenant, parentTenantID *int64, ancestorPath string) error
 }
 
 type tenantRepository struct {
@@ -88,7 +102,7 @@ func (r *tenantRepository) FindSystem() (*model.Tenant, error) {
 }
 
 func (r *tenantRepository) FindPaginated(filter TenantRepositoryGetFilter) (*PaginationResult[model.Tenant], error) {
-	query := r.DB().Model(&model.Tenant{})
+	query := r.ReadDB().Model(&model.Tenant{})
 
 	// Filters with LIKE
 	if filter.Name != nil {
@@ -114,6 +128,16 @@ func (r *tenantRepository) FindPaginated(filter TenantRepositoryGetFilter) (*Pag
 	if filter.IsSystem != nil {
 		query = query.Where("is_system = ?", *filter.IsSystem)
 	}
+	if filter.TenantIDs != nil {
+		if filter.IncludeDescendants {
+			query = query.Where("tenant_id IN (?)", tenantSubtreeQuery(r.DB(), filter.TenantIDs))
+		} else {
+			query = query.Where("tenant_id IN ?", filter.TenantIDs)
+		}
+	}
+	if filter.ParentTenantID != nil {
+		query = query.Where("parent_tenant_id = ?", *filter.ParentTenantID)
+	}
 
 	// Sorting — protected against SQL injection via allowlist
 	query = query.Order(sanitizeOrder(filter.SortBy, filter.SortOrder, "created_at DESC"))
@@ -133,7 +157,7 @@ func (r *tenantRepository) FindPaginated(filter TenantRepositoryGetFilter) (*Pag
 	}
 	offset := (filter.Page - 1) * filter.Limit
 	var tenants []model.Tenant
-	if err := query.Limit(filter.Limit).Offset(offset).Find(&tenants).Error; err != nil {
+	if err := query.Preload("ParentTenant").Limit(filter.Limit).Offset(offset).Find(&tenants).Error; err != nil {
 		return nil, err
 	}
 
@@ -155,3 +179,48 @@ func (r *tenantRepository) SetStatusByUUID(tenantUUID uuid.UUID, status string)
 func (r *tenantRepository) SetSystemStatusByUUID(tenantUUID uuid.UUID, isSystem bool) error {
 	return r.DB().Model(&model.Tenant{}).Where("tenant_uuid = ?", tenantUUID).Update("is_system", isSystem).Error
 }
+
+func (r *tenantRepository) SetPlanByUUID(tenantUUID uuid.UUID, planID *int64) error {
+	return r.DB().Model(&model.Tenant{}).Where("tenant_uuid = ?", tenantUUID).Update("plan_id", planID).Error
+}
+
+func (r *tenantRepository) CountChildren(tenantID int64) (int64, error) {
+	var count int64
+	err := r.DB().Model(&model.Tenant{}).Where("parent_tenant_id = ?", tenantID).Count(&count).Error
+	return count, err
+}
+
+// MoveSubtree places tenant under the tenant with parentTenantID, or at the
+// top level when it is nil, and rewrites the ancestor paths of the tenant and
+// every tenant below it. ancestorPath is the tenant's new AncestorPath.
+func (r *tenantRepository) MoveSubtree(tenant *model.Tenant, parentTenantID *int64, ancestorPath string) error {
+	oldPath := tenant.AncestorPath
+	descendants := tenant.ChildAncestorPath()
+
+	err := r.DB().Model(&model.Tenant{}).
+		Where("tenant_id = ?", tenant.TenantID).
+		Updates(map[string]any{"parent_tenant_id": parentTenantID, "ancestor_path": ancestorPath}).Error
+	if err != nil {
+		return err
+	}
+
+	// Descendant paths all start with the tenant's old path, which is swapped
+	// for the new one.
+	return r.DB().Model(&model.Tenant{}).
+		Where("ancestor_path LIKE ?", descendants+"%").
+		Update("ancestor_path", gorm.Expr("? || substr(ancestor_path, ?)", ancestorPath, len(oldPath)+1)).Error
+}
+
+// tenantSubtreeQuery selects the IDs of the given tenants and of every tenant
+// below them.
+func tenantSubtreeQuery(db *gorm.DB, tenantIDs []int64) *gorm.DB {
+	conditions := []string{"tenant_id IN ?"}
+	args := []any{tenantIDs}
+	for _, id := range tenantIDs {
+		conditions = append(conditions, "ancestor_path LIKE ?")
+		args = append(args, "%/"+strconv.FormatInt(id, 10)+"/%")
+	}
+	return db.Session(&gorm.Session{NewDB: true}).Model(&model.Tenant{}).
+		Select("tenant_id").
+		Where(strings.Join(conditions, " OR "), args...)
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TenantDeprovisionRepository interface {
+	BaseRepositoryMethods[model.TenantDeprovision]
+	WithTx(tx *gorm.DB) TenantDeprovisionRepository
+	// FindPendingByTokenHash locks and returns the pending request for the
+	// tenant whose confirmation token hashes to tokenHash.
+	FindPendingByTokenHash(tenantID int64, tokenHash string) (*model.TenantDeprovision, error)
+	// SupersedePending marks every pending request for the tenant superseded
+	// so only the newest confirmation token can be used.
+	SupersedePending(tenantID int64) error
+}
+
+type tenantDeprovisionRepository struct {
+	*BaseRepository[model.TenantDeprovision]
+}
+
+func NewTenantDeprovisionRepository(db *gorm.DB) TenantDeprovisionRepository {
+	return &tenantDeprovisionRepository{
+		BaseRepository: NewBaseRepository[model.TenantDeprovision](db, "tenant_deprovision_uuid", "tenant_deprovision_id"),
+	}
+}
+
+func (r *tenantDeprovisionRepository) WithTx(tx *gorm.DB) TenantDeprovisionRepository {
+	return &tenantDeprovisionRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+func (r *tenantDeprovisionRepository) FindPendingByTokenHash(tenantID int64, tokenHash string) (*model.TenantDeprovision, error) {
+	var req model.TenantDeprovision
+	err := r.DB().
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tenant_id = ? AND token_hash = ? AND status = ?", tenantID, tokenHash, model.TenantDeprovisionStatusPending).
+		First(&req).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (r *tenantDeprovisionRepository) SupersedePending(tenantID int64) error {
+	return r.DB().Model(&model.TenantDeprovision{}).
+		Where("tenant_id = ? AND status = ?", tenantID, model.TenantDeprovisionStatusPending).
+		Update("status", model.TenantDeprovisionStatusSuperseded).Error
+}
@@ -12,6 +12,7 @@ type TenantMemberRepository interface {
 	BaseRepositoryMethods[model.TenantMember]
 	WithTx(tx *gorm.DB) TenantMemberRepository
 	FindByTenantMemberUUID(uuid uuid.UUID) (*model.TenantMember, error)
+	FindByUUIDAndTenantID(tenantMemberUUID uuid.UUID, tenantID int64) (*model.TenantMember, error)
 	FindByTenantAndUser(tenantID int64, userID int64) (*model.TenantMember, error)
 	FindAllByTenant(tenantID int64) ([]model.TenantMember, error)
 	FindAllByUser(userID int64) ([]model.TenantMember, error)
@@ -45,6 +46,18 @@ func (r *tenantMemberRepository) FindByTenantMemberUUID(uuid uuid.UUID) (*model.
 	return &tu, nil
 }
 
+func (r *tenantMemberRepository) FindByUUIDAndTenantID(tenantMemberUUID uuid.UUID, tenantID int64) (*model.TenantMember, error) {
+	var tu model.TenantMember
+	err := r.DB().Where("tenant_member_uuid = ? AND tenant_id = ?", tenantMemberUUID, tenantID).First(&tu).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tu, nil
+}
+
 func (r *tenantMemberRepository) FindByTenantAndUser(tenantID int64, userID int64) (*model.TenantMember, error) {
 	var tu model.TenantMember
 	err := r.DB().Where("tenant_id = ? AND user_id = ?", tenantID, userID).First(&tu).Error
@@ -52,7 +52,7 @@ func (r *tenantServiceRepository) DeleteByTenantAndService(tenantID int64, servi
 }
 
 func (r *tenantServiceRepository) FindPaginated(filter TenantServiceRepositoryGetFilter) (*PaginationResult[model.TenantService], error) {
-	query := r.DB().Model(&model.TenantService{})
+	query := r.ReadDB().Model(&model.TenantService{})
 
 	// Filters
 	if filter.TenantID != nil {
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TenantUsageRepository defines persistence operations for the daily usage
+// of tenants.
+type TenantUsageRepository interface {
+	BaseRepositoryMethods[model.TenantUsage]
+	WithTx(tx *gorm.DB) TenantUsageRepository
+	AddUsage(usage *model.TenantUsage) error
+	FindByTenantIDAndDateRange(tenantID int64, from, to time.Time) ([]model.TenantUsage, error)
+}
+
+type tenantUsageRepository struct {
+	*BaseRepository[model.TenantUsage]
+}
+
+// NewTenantUsageRepository creates a new TenantUsageRepository backed by the
+// given database connection.
+func NewTenantUsageRepository(db *gorm.DB) TenantUsageRepository {
+	return &tenantUsageRepository{
+		BaseRepository: NewBaseRepository[model.TenantUsage](db, "tenant_usage_uuid", "tenant_usage_id"),
+	}
+}
+
+// WithTx returns a copy of the repository bound to the supplied transaction.
+func (r *tenantUsageRepository) WithTx(tx *gorm.DB) TenantUsageRepository {
+	return &tenantUsageRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+// AddUsage adds usage.Count to the row of the same tenant, API key, day and
+// metric, creating it when there is none.
+func (r *tenantUsageRepository) AddUsage(usage *model.TenantUsage) error {
+	return r.DB().Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "tenant_id"}, {Name: "usage_date"}, {Name: "metric"}, {Name: "api_key_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"count":      gorm.Expr("tenant_usage.count + EXCLUDED.count"),
+			"updated_at": gorm.Expr("now()"),
+		}),
+	}).Create(usage).Error
+}
+
+// FindByTenantIDAndDateRange returns the tenant's usage from from to to,
+// both inclusive, oldest first, with the API key of each row when it still
+// exists.
+func (r *tenantUsageRepository) FindByTenantIDAndDateRange(tenantID int64, from, to time.Time) ([]model.TenantUsage, error) {
+	var usage []model.TenantUsage
+	err := r.DB().
+		Preload("APIKey").
+		Where("tenant_id = ? AND usage_date BETWEEN ? AND ?", tenantID, from.Format(time.DateOnly), to.Format(time.DateOnly)).
+		Order("usage_date ASC, metric ASC, api_key_id ASC").
+		Find(&usage).Error
+	return usage, err
+}
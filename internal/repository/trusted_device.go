@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+)
+
+type TrustedDeviceRepository interface {
+	BaseRepositoryMethods[model.TrustedDevice]
+	WithTx(tx *gorm.DB) TrustedDeviceRepository
+	FindActiveByUserID(userID int64, now time.Time) ([]model.TrustedDevice, error)
+	FindByUUIDAndUserID(uuid string, userID int64) (*model.TrustedDevice, error)
+	FindByTokenHash(tokenHash string) (*model.TrustedDevice, error)
+	Revoke(trustedDeviceID int64, revokedAt time.Time) error
+	RevokeByUserID(userID int64, revokedAt time.Time) (int64, error)
+	TouchLastUsed(trustedDeviceID int64, usedAt time.Time) error
+}
+
+type trustedDeviceRepository struct {
+	*BaseRepository[model.TrustedDevice]
+}
+
+func NewTrustedDeviceRepository(db *gorm.DB) TrustedDeviceRepository {
+	return &trustedDeviceRepository{
+		BaseRepository: NewBaseRepository[model.TrustedDevice](db, "trusted_device_uuid", "trusted_device_id"),
+	}
+}
+
+func (r *trustedDeviceRepository) WithTx(tx *gorm.DB) TrustedDeviceRepository {
+	return &trustedDeviceRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+// FindActiveByUserID returns the user's devices that are neither revoked nor
+// expired at now, most recently used first.
+func (r *trustedDeviceRepository) FindActiveByUserID(userID int64, now time.Time) ([]model.TrustedDevice, error) {
+	var devices []model.TrustedDevice
+	err := r.DB().
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("COALESCE(last_used_at, created_at) DESC").
+		Find(&devices).Error
+	return devices, err
+}
+
+func (r *trustedDeviceRepository) FindByUUIDAndUserID(uuid string, userID int64) (*model.TrustedDevice, error) {
+	var device model.TrustedDevice
+	err := r.DB().Where("trusted_device_uuid = ? AND user_id = ?", uuid, userID).First(&device).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &device, nil
+}
+
+func (r *trustedDeviceRepository) FindByTokenHash(tokenHash string) (*model.TrustedDevice, error) {
+	var device model.TrustedDevice
+	err := r.DB().Where("token_hash = ?", tokenHash).First(&device).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &device, nil
+}
+
+// Revoke sets revoked_at on a device that has not been revoked yet.
+func (r *trustedDeviceRepository) Revoke(trustedDeviceID int64, revokedAt time.Time) error {
+	return r.DB().Model(&model.TrustedDevice{}).
+		Where("trusted_device_id = ? AND revoked_at IS NULL", trustedDeviceID).
+		UpdateColumns(map[string]any{
+			"revoked_at": revokedAt,
+			"updated_at": revokedAt,
+		}).Error
+}
+
+// RevokeByUserID revokes every unrevoked device of the user and returns how
+// many there were.
+func (r *trustedDeviceRepository) RevokeByUserID(userID int64, revokedAt time.Time) (int64, error) {
+	result := r.DB().Model(&model.TrustedDevice{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		UpdateColumns(map[string]any{
+			"revoked_at": revokedAt,
+			"updated_at": revokedAt,
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *trustedDeviceRepository) TouchLastUsed(trustedDeviceID int64, usedAt time.Time) error {
+	return r.DB().Model(&model.TrustedDevice{}).
+		Where("trusted_device_id = ?", trustedDeviceID).
+		UpdateColumn("last_used_at", usedAt).Error
+}
@@ -2,10 +2,13 @@ package repository
 
 import (
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepositoryGetFilter struct {
@@ -17,10 +20,24 @@ type UserRepositoryGetFilter struct {
 	RoleID     *int64
 	ClientID   *int64
 	UserPoolID *int64
-	Page       int
-	Limit      int
-	SortBy     string
-	SortOrder  string
+	// IncludeSubtenants widens TenantID to the tenants below it.
+	IncludeSubtenants bool
+	Page              int
+	Limit             int
+	SortBy            string
+	SortOrder         string
+
+	// IncludeIdentities and IncludeRoles preload the identities (with their
+	// client) and roles of the listed users. Each relation is loaded for the
+	// whole page in one query, scoped to TenantID when it is set.
+	IncludeIdentities bool
+	IncludeRoles      bool
+}
+
+// PermissionGrant is a permission a user holds through a role of a tenant.
+type PermissionGrant struct {
+	TenantUUID     uuid.UUID `gorm:"column:tenant_uuid"`
+	PermissionName string    `gorm:"column:permission_name"`
 }
 
 type UserRepository interface {
@@ -35,10 +52,44 @@ type UserRepository interface {
 	FindByPhone(phone string) (*model.User, error)
 	FindSuperAdmin() (*model.User, error)
 	FindRoles(userID int64) ([]model.Role, error)
+	// FindGroupRoles returns the roles the user holds through the groups
+	// they are a member of.
+	FindGroupRoles(userID int64, preloads ...string) ([]model.Role, error)
+	// FindPermissionGrants returns the distinct permissions of every role
+	// the user holds, assigned directly or through a group, with the tenant
+	// each role belongs to, in one query.
+	FindPermissionGrants(userID int64) ([]PermissionGrant, error)
 	FindBySubAndClientID(sub string, clientID string) (*model.User, error)
 	FindPaginated(filter UserRepositoryGetFilter) (*PaginationResult[model.User], error)
+	// FindCursorPaginated returns a keyset page of the users matching the
+	// filter, ordered by user_id. Page, Limit and sorting of the filter are
+	// ignored in favour of page.
+	FindCursorPaginated(filter UserRepositoryGetFilter, page CursorPage) (*CursorPaginationResult[model.User], error)
+	// Search returns a page of the users matching the filter whose username,
+	// fullname, email or phone fuzzily matches term, most relevant first.
+	// Sorting of the filter is ignored.
+	Search(term string, filter UserRepositoryGetFilter) (*PaginationResult[model.User], error)
+	// FindFilteredAfterID returns up to limit users matching the filter with a
+	// user_id above afterUserID, ordered by user_id, for keyset paging over
+	// large result sets. Page, Limit and sorting of the filter are ignored.
+	FindFilteredAfterID(filter UserRepositoryGetFilter, afterUserID int64, limit int) ([]model.User, error)
 	SetEmailVerified(userUUID uuid.UUID, verified bool) error
 	SetStatus(userUUID uuid.UUID, status string) error
+	// SoftDelete marks the user deleted at deletedAt; Restore reverses it.
+	SoftDelete(userUUID uuid.UUID, deletedAt time.Time) error
+	Restore(userUUID uuid.UUID, status string) error
+	// FindDeletedBefore returns up to limit users deleted before cutoff with
+	// a user_id above afterUserID, ordered by user_id, for keyset paging.
+	FindDeletedBefore(cutoff time.Time, afterUserID int64, limit int) ([]model.User, error)
+	// FindByUsernamesOrEmails returns the users, deleted ones included, whose
+	// username or email is in the given lists, for bulk duplicate checks.
+	FindByUsernamesOrEmails(usernames []string, emails []string) ([]model.User, error)
+	// CreateBatch inserts users in one statement and sets their IDs.
+	CreateBatch(users []model.User) error
+	// DeleteExclusiveToTenant hard deletes the users that have an identity or
+	// membership in the tenant and in no other tenant, returning how many
+	// were removed.
+	DeleteExclusiveToTenant(tenantID int64) (int64, error)
 }
 
 type userRepository struct {
@@ -154,6 +205,43 @@ func (r *userRepository) FindRoles(userID int64) ([]model.Role, error) {
 	return roles, err
 }
 
+func (r *userRepository) FindGroupRoles(userID int64, preloads ...string) ([]model.Role, error) {
+	var roles []model.Role
+	query := r.DB().Model(&model.Role{})
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+	err := query.
+		Where("roles.role_id IN (?)", groupRoleIDsQuery(r.DB(), userID)).
+		Order("roles.role_id").
+		Find(&roles).Error
+	return roles, err
+}
+
+func (r *userRepository) FindPermissionGrants(userID int64) ([]PermissionGrant, error) {
+	var grants []PermissionGrant
+	err := r.DB().
+		Table("roles ro").
+		Select("DISTINCT t.tenant_uuid, p.name AS permission_name").
+		Joins("JOIN tenants t ON t.tenant_id = ro.tenant_id").
+		Joins("JOIN role_permissions rp ON rp.role_id = ro.role_id").
+		Joins("JOIN permissions p ON p.permission_id = rp.permission_id").
+		Where("ro.role_id IN (SELECT ur.role_id FROM user_roles ur WHERE ur.user_id = ?) OR ro.role_id IN (?)", userID, groupRoleIDsQuery(r.DB(), userID)).
+		Order("t.tenant_uuid, permission_name").
+		Scan(&grants).Error
+	return grants, err
+}
+
+// groupRoleIDsQuery selects the IDs of the roles attached to the groups the
+// user is a member of.
+func groupRoleIDsQuery(db *gorm.DB, userID int64) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Table("group_roles gr").
+		Select("gr.role_id").
+		Joins("JOIN group_members gm ON gm.group_id = gr.group_id").
+		Where("gm.user_id = ?", userID)
+}
+
 func (r *userRepository) FindBySubAndClientID(sub string, clientID string) (*model.User, error) {
 	var user model.User
 	err := r.DB().
@@ -161,10 +249,11 @@ func (r *userRepository) FindBySubAndClientID(sub string, clientID string) (*mod
 		Preload("UserIdentities.Client.IdentityProvider.Tenant").
 		Preload("UserIdentities.Client.IdentityProvider").
 		Preload("UserIdentities.Client").
+		Preload("UserIdentities.Client.ClientAPIs.Permissions.Permission").
 		Preload("Roles.Permissions").
 		Joins("JOIN user_identities ON users.user_id = user_identities.user_id").
 		Joins("JOIN clients ON user_identities.client_id = clients.client_id").
-		Where("user_identities.sub = ? AND clients.client_id = ?", sub, clientID).
+		Where("user_identities.sub = ? AND clients.client_id = ? AND users.deleted_at IS NULL", sub, clientID).
 		First(&user).Error
 
 	if err != nil {
@@ -188,16 +277,202 @@ func (r *userRepository) SetStatus(userUUID uuid.UUID, status string) error {
 		Update("status", status).Error
 }
 
+func (r *userRepository) SoftDelete(userUUID uuid.UUID, deletedAt time.Time) error {
+	return r.DB().Model(&model.User{}).
+		Where("user_uuid = ?", userUUID).
+		Updates(map[string]any{"status": model.StatusDeleted, "deleted_at": deletedAt}).Error
+}
+
+func (r *userRepository) Restore(userUUID uuid.UUID, status string) error {
+	return r.DB().Model(&model.User{}).
+		Where("user_uuid = ?", userUUID).
+		Updates(map[string]any{"status": status, "deleted_at": nil}).Error
+}
+
+func (r *userRepository) FindDeletedBefore(cutoff time.Time, afterUserID int64, limit int) ([]model.User, error) {
+	var users []model.User
+	err := r.DB().
+		Preload("UserIdentities").
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND user_id > ?", cutoff, afterUserID).
+		Order("user_id ASC").
+		Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) FindByUsernamesOrEmails(usernames []string, emails []string) ([]model.User, error) {
+	var users []model.User
+	if len(usernames) == 0 && len(emails) == 0 {
+		return users, nil
+	}
+
+	query := r.DB().Select("user_id", "user_uuid", "username", "email")
+	switch {
+	case len(usernames) > 0 && len(emails) > 0:
+		query = query.Where("username IN ? OR email IN ?", usernames, emails)
+	case len(usernames) > 0:
+		query = query.Where("username IN ?", usernames)
+	default:
+		query = query.Where("email IN ?", emails)
+	}
+	err := query.Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) CreateBatch(users []model.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+	return r.DB().Create(&users).Error
+}
+
 func (r *userRepository) FindPaginated(filter UserRepositoryGetFilter) (*PaginationResult[model.User], error) {
 	var users []model.User
 	var total int64
 
-	query := r.DB().Model(&model.User{})
+	query := applyUserFilter(r.ReadDB().Model(&model.User{}), filter)
+
+	// Count total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	// Apply sorting — protected against SQL injection via allowlist
+	query = query.Order(sanitizeOrderPrefixed("users.", filter.SortBy, filter.SortOrder, "users.created_at DESC"))
+
+	// Apply pagination
+	filter.Page, filter.Limit = normalizePagination(filter.Page, filter.Limit)
+	offset := (filter.Page - 1) * filter.Limit
+	query = preloadUserRelations(query, filter)
+	if err := query.Offset(offset).Limit(filter.Limit).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(filter.Limit) - 1) / int64(filter.Limit))
+
+	return &PaginationResult[model.User]{
+		Data:       users,
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (r *userRepository) FindCursorPaginated(filter UserRepositoryGetFilter, page CursorPage) (*CursorPaginationResult[model.User], error) {
+	// Made distinct per user for the same reason as FindFilteredAfterID.
+	query := applyUserFilter(r.DB().Model(&model.User{}), filter).
+		Select("DISTINCT ON (users.user_id) users.*")
+	query = preloadUserRelations(query, filter)
+	return paginateByCursor[model.User](query, "users.user_id", page)
+}
+
+func (r *userRepository) FindFilteredAfterID(filter UserRepositoryGetFilter, afterUserID int64, limit int) ([]model.User, error) {
+	var users []model.User
+	// A user with several identities in the tenant joins once per identity,
+	// so rows are made distinct per user.
+	err := applyUserFilter(r.DB().Model(&model.User{}), filter).
+		Select("DISTINCT ON (users.user_id) users.*").
+		Where("users.user_id > ?", afterUserID).
+		Order("users.user_id ASC").
+		Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+// userSearchDocument is the full-text document of a user, matching the
+// expression of idx_users_search_tsv.
+const userSearchDocument = "to_tsvector('simple', coalesce(users.fullname, '') || ' ' || coalesce(users.username, '') || ' ' || coalesce(users.email, ''))"
+
+func (r *userRepository) Search(term string, filter UserRepositoryGetFilter) (*PaginationResult[model.User], error) {
+	var users []model.User
+	var total int64
+
+	term = strings.ToLower(strings.TrimSpace(term))
+	args := map[string]any{"term": term, "pattern": "%" + escapeLike(term) + "%"}
+
+	// Trigram similarity catches typos, word similarity a part of a full
+	// name, LIKE substrings too short to be similar, and the full-text match
+	// words in any order. Every condition is served by an index of migration
+	// 080.
+	match := "lower(users.username) % @term OR lower(users.fullname) % @term OR @term <% lower(users.fullname) OR lower(users.email) % @term" +
+		" OR lower(users.username) LIKE @pattern OR lower(users.fullname) LIKE @pattern OR lower(users.email) LIKE @pattern" +
+		" OR " + userSearchDocument + " @@ plainto_tsquery('simple', @term)"
+	// Phone numbers are matched on their digits so "555 0100" finds
+	// "+15550100".
+	if digits := onlyDigits(term); len(digits) >= 3 {
+		match += " OR users.phone LIKE @phone"
+		args["phone"] = "%" + digits + "%"
+	}
+
+	// The filter is applied in a subquery so users joined once per identity
+	// are not counted twice.
+	scope := applyUserFilter(r.ReadDB().Model(&model.User{}).Select("users.user_id"), filter)
+	query := r.ReadDB().Model(&model.User{}).
+		Where("users.user_id IN (?)", scope).
+		Where("("+match+")", args)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	rank := "GREATEST(similarity(lower(users.username), @term), similarity(lower(users.fullname), @term)," +
+		" word_similarity(@term, lower(users.fullname)), similarity(lower(users.email), @term))" +
+		" + ts_rank(" + userSearchDocument + ", plainto_tsquery('simple', @term))"
+	query = query.Clauses(clause.OrderBy{Expression: clause.NamedExpr{
+		SQL:  rank + " DESC, users.user_id ASC",
+		Vars: []any{args},
+	}})
+
+	filter.Page, filter.Limit = normalizePagination(filter.Page, filter.Limit)
+	offset := (filter.Page - 1) * filter.Limit
+	query = preloadUserRelations(query, filter)
+	if err := query.Offset(offset).Limit(filter.Limit).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(filter.Limit) - 1) / int64(filter.Limit))
+
+	return &PaginationResult[model.User]{
+		Data:       users,
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// onlyDigits returns the decimal digits of s.
+func onlyDigits(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// applyUserFilter adds the conditions of a user list filter to query.
+func applyUserFilter(query *gorm.DB, filter UserRepositoryGetFilter) *gorm.DB {
+	// A subtree search matches users with an identity in any tenant of the
+	// subtree. EXISTS keeps users with identities in several of them from
+	// being listed more than once.
+	subtree := filter.TenantID != nil && filter.IncludeSubtenants
+	if subtree {
+		query = query.Where("EXISTS (SELECT 1 FROM user_identities ui WHERE ui.user_id = users.user_id AND ui.tenant_id IN (?))",
+			tenantSubtreeQuery(query, []int64{*filter.TenantID}))
+	}
 
 	// Filter by user_identities fields (tenant, client) — join once to avoid duplicates.
-	if filter.TenantID != nil || filter.ClientID != nil {
+	if (filter.TenantID != nil && !subtree) || filter.ClientID != nil {
 		query = query.Joins("JOIN user_identities ON users.user_id = user_identities.user_id")
-		if filter.TenantID != nil {
+		if filter.TenantID != nil && !subtree {
 			query = query.Where("user_identities.tenant_id = ?", *filter.TenantID)
 		}
 		if filter.ClientID != nil {
@@ -216,35 +491,50 @@ func (r *userRepository) FindPaginated(filter UserRepositoryGetFilter) (*Paginat
 	if filter.Phone != nil {
 		query = query.Where("users.phone ILIKE ?", "%"+*filter.Phone+"%")
 	}
+	// Deleted users are only listed when asked for by status
 	if len(filter.Status) > 0 {
 		query = query.Where("users.status IN ?", filter.Status)
+	} else {
+		query = query.Where("users.deleted_at IS NULL")
 	}
 	if filter.RoleID != nil {
 		query = query.Joins("JOIN user_roles ON users.user_id = user_roles.user_id").Where("user_roles.role_id = ?", *filter.RoleID)
 	}
+	return query
+}
 
-	// Count total records
-	if err := query.Count(&total).Error; err != nil {
-		return nil, err
+// preloadUserRelations adds the relations requested by the filter as
+// preloads. GORM runs one query per relation with the IDs of the whole page,
+// so a page costs the same number of queries whatever its size.
+func preloadUserRelations(query *gorm.DB, filter UserRepositoryGetFilter) *gorm.DB {
+	if filter.IncludeIdentities {
+		if filter.TenantID != nil && filter.IncludeSubtenants {
+			query = query.Preload("UserIdentities", "tenant_id IN (?)", tenantSubtreeQuery(query, []int64{*filter.TenantID}))
+		} else if filter.TenantID != nil {
+			query = query.Preload("UserIdentities", "tenant_id = ?", *filter.TenantID)
+		} else {
+			query = query.Preload("UserIdentities")
+		}
+		query = query.Preload("UserIdentities.Client")
 	}
-
-	// Apply sorting — protected against SQL injection via allowlist
-	query = query.Order(sanitizeOrderPrefixed("users.", filter.SortBy, filter.SortOrder, "users.created_at DESC"))
-
-	// Apply pagination
-	filter.Page, filter.Limit = normalizePagination(filter.Page, filter.Limit)
-	offset := (filter.Page - 1) * filter.Limit
-	if err := query.Offset(offset).Limit(filter.Limit).Find(&users).Error; err != nil {
-		return nil, err
+	if filter.IncludeRoles {
+		if filter.TenantID != nil && filter.IncludeSubtenants {
+			query = query.Preload("Roles", "roles.tenant_id IN (?)", tenantSubtreeQuery(query, []int64{*filter.TenantID}))
+		} else if filter.TenantID != nil {
+			query = query.Preload("Roles", "roles.tenant_id = ?", *filter.TenantID)
+		} else {
+			query = query.Preload("Roles")
+		}
 	}
+	return query
+}
 
-	totalPages := int((total + int64(filter.Limit) - 1) / int64(filter.Limit))
-
-	return &PaginationResult[model.User]{
-		Data:       users,
-		Total:      total,
-		Page:       filter.Page,
-		Limit:      filter.Limit,
-		TotalPages: totalPages,
-	}, nil
+func (r *userRepository) DeleteExclusiveToTenant(tenantID int64) (int64, error) {
+	result := r.DB().
+		Where(`(EXISTS (SELECT 1 FROM user_identities ui WHERE ui.user_id = users.user_id AND ui.tenant_id = ?)
+			OR EXISTS (SELECT 1 FROM tenant_members tm WHERE tm.user_id = users.user_id AND tm.tenant_id = ?))`, tenantID, tenantID).
+		Where("NOT EXISTS (SELECT 1 FROM user_identities ui WHERE ui.user_id = users.user_id AND ui.tenant_id <> ?)", tenantID).
+		Where("NOT EXISTS (SELECT 1 FROM tenant_members tm WHERE tm.user_id = users.user_id AND tm.tenant_id <> ?)", tenantID).
+		Delete(&model.User{})
+	return result.RowsAffected, result.Error
 }
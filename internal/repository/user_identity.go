@@ -7,14 +7,37 @@ import (
 	"gorm.io/gorm"
 )
 
+type UserIdentityRepositoryGetFilter struct {
+	UserID    int64
+	TenantID  int64
+	Provider  *string
+	Page      int
+	Limit     int
+	SortBy    string
+	SortOrder string
+}
+
 type UserIdentityRepository interface {
 	BaseRepositoryMethods[model.UserIdentity]
 	WithTx(tx *gorm.DB) UserIdentityRepository
-	FindByUserID(userID int64) ([]model.UserIdentity, error)
+	FindByUserID(userID int64, preloads ...string) ([]model.UserIdentity, error)
+	// CountUsersByTenantID returns the number of users with an identity in
+	// the tenant, not counting deleted users.
+	CountUsersByTenantID(tenantID int64) (int64, error)
+	// FindPaginated returns a page of the user's identities in the tenant,
+	// with their client.
+	FindPaginated(filter UserIdentityRepositoryGetFilter) (*PaginationResult[model.UserIdentity], error)
 	FindByUserIDAndClientID(userID int64, clientID int64) (*model.UserIdentity, error)
+	// FindByUUIDAndUserID returns the user's identity with its client, or
+	// nil when it does not exist or belongs to someone else.
+	FindByUUIDAndUserID(identityUUID string, userID int64) (*model.UserIdentity, error)
+	// FindByClientIDAndSub returns the identity of the subject at the
+	// client, or nil when there is none.
+	FindByClientIDAndSub(clientID int64, sub string) (*model.UserIdentity, error)
 	FindByProviderAndUserID(providerName string, providerUserID string) (*model.UserIdentity, error)
 	FindByEmail(email string) ([]model.UserIdentity, error)
 	DeleteByUserID(userID int64) error
+	CreateBatch(identities []model.UserIdentity) error
 }
 
 type userIdentityRepository struct {
@@ -33,12 +56,60 @@ func (r *userIdentityRepository) WithTx(tx *gorm.DB) UserIdentityRepository {
 	}
 }
 
-func (r *userIdentityRepository) FindByUserID(userID int64) ([]model.UserIdentity, error) {
+func (r *userIdentityRepository) FindByUserID(userID int64, preloads ...string) ([]model.UserIdentity, error) {
 	var identities []model.UserIdentity
-	err := r.DB().Where("user_id = ?", userID).Find(&identities).Error
+	query := r.DB()
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+	err := query.Where("user_id = ?", userID).Find(&identities).Error
 	return identities, err
 }
 
+func (r *userIdentityRepository) CountUsersByTenantID(tenantID int64) (int64, error) {
+	var count int64
+	err := r.DB().Model(&model.UserIdentity{}).
+		Joins("JOIN users ON users.user_id = user_identities.user_id").
+		Where("user_identities.tenant_id = ? AND users.status <> ?", tenantID, model.StatusDeleted).
+		Distinct("user_identities.user_id").
+		Count(&count).Error
+	return count, err
+}
+
+func (r *userIdentityRepository) FindPaginated(filter UserIdentityRepositoryGetFilter) (*PaginationResult[model.UserIdentity], error) {
+	query := r.ReadDB().Model(&model.UserIdentity{}).
+		Where("user_id = ? AND tenant_id = ?", filter.UserID, filter.TenantID)
+
+	if filter.Provider != nil {
+		query = query.Where("provider ILIKE ?", "%"+*filter.Provider+"%")
+	}
+
+	// Sorting — protected against SQL injection via allowlist
+	query = query.Order(sanitizeOrder(filter.SortBy, filter.SortOrder, "created_at DESC"))
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	filter.Page, filter.Limit = normalizePagination(filter.Page, filter.Limit)
+	offset := (filter.Page - 1) * filter.Limit
+	var identities []model.UserIdentity
+	if err := query.Preload("Client").Limit(filter.Limit).Offset(offset).Find(&identities).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(filter.Limit) - 1) / int64(filter.Limit))
+
+	return &PaginationResult[model.UserIdentity]{
+		Data:       identities,
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
 func (r *userIdentityRepository) FindByUserIDAndClientID(userID int64, clientID int64) (*model.UserIdentity, error) {
 	var identity model.UserIdentity
 	err := r.DB().Where("user_id = ? AND client_id = ?", userID, clientID).First(&identity).Error
@@ -51,6 +122,32 @@ func (r *userIdentityRepository) FindByUserIDAndClientID(userID int64, clientID
 	return &identity, nil
 }
 
+func (r *userIdentityRepository) FindByUUIDAndUserID(identityUUID string, userID int64) (*model.UserIdentity, error) {
+	var identity model.UserIdentity
+	err := r.DB().Preload("Client").
+		Where("user_identity_uuid = ? AND user_id = ?", identityUUID, userID).
+		First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *userIdentityRepository) FindByClientIDAndSub(clientID int64, sub string) (*model.UserIdentity, error) {
+	var identity model.UserIdentity
+	err := r.DB().Where("client_id = ? AND sub = ?", clientID, sub).First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
 func (r *userIdentityRepository) FindByProviderAndUserID(providerName string, providerUserID string) (*model.UserIdentity, error) {
 	var ui model.UserIdentity
 	err := r.DB().
@@ -74,3 +171,10 @@ func (r *userIdentityRepository) FindByEmail(email string) ([]model.UserIdentity
 func (r *userIdentityRepository) DeleteByUserID(userID int64) error {
 	return r.DB().Where("user_id = ?", userID).Delete(&model.UserIdentity{}).Error
 }
+
+func (r *userIdentityRepository) CreateBatch(identities []model.UserIdentity) error {
+	if len(identities) == 0 {
+		return nil
+	}
+	return r.DB().Create(&identities).Error
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+)
+
+type UserImportJobRepository interface {
+	BaseRepositoryMethods[model.UserImportJob]
+	WithTx(tx *gorm.DB) UserImportJobRepository
+	FindByUUIDAndTenantID(uuid string, tenantID int64) (*model.UserImportJob, error)
+}
+
+type userImportJobRepository struct {
+	*BaseRepository[model.UserImportJob]
+}
+
+func NewUserImportJobRepository(db *gorm.DB) UserImportJobRepository {
+	return &userImportJobRepository{
+		BaseRepository: NewBaseRepository[model.UserImportJob](db, "user_import_job_uuid", "user_import_job_id"),
+	}
+}
+
+func (r *userImportJobRepository) WithTx(tx *gorm.DB) UserImportJobRepository {
+	return &userImportJobRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+func (r *userImportJobRepository) FindByUUIDAndTenantID(uuid string, tenantID int64) (*model.UserImportJob, error) {
+	var job model.UserImportJob
+	err := r.DB().Where("user_import_job_uuid = ? AND tenant_id = ?", uuid, tenantID).First(&job).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
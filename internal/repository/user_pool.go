@@ -17,6 +17,7 @@ type UserPoolRepository interface {
 	FindDefault(tenantID int64) (*model.UserPool, error)
 	FindSystem(tenantID int64) (*model.UserPool, error)
 	FindAllByTenantID(tenantID int64) ([]model.UserPool, error)
+	DeleteByTenantID(tenantID int64) error
 }
 
 type userPoolRepository struct {
@@ -90,3 +91,9 @@ func (r *userPoolRepository) FindAllByTenantID(tenantID int64) ([]model.UserPool
 		Find(&pools).Error
 	return pools, err
 }
+
+// DeleteByTenantID removes every user pool of a tenant, deleted ones
+// included, along with their security settings.
+func (r *userPoolRepository) DeleteByTenantID(tenantID int64) error {
+	return r.DB().Where("tenant_id = ?", tenantID).Delete(&model.UserPool{}).Error
+}
@@ -15,6 +15,7 @@ type UserRoleRepository interface {
 	FindDefaultRolesByUserID(userID int64) ([]model.UserRole, error)
 	DeleteByUserID(userID int64) error
 	DeleteByUserIDAndRoleID(userID int64, roleID int64) error
+	CreateBatch(userRoles []model.UserRole) error
 }
 
 type userRoleRepository struct {
@@ -70,3 +71,10 @@ func (r *userRoleRepository) DeleteByUserIDAndRoleID(userID int64, roleID int64)
 		Where("user_id = ? AND role_id = ?", userID, roleID).
 		Delete(&model.UserRole{}).Error
 }
+
+func (r *userRoleRepository) CreateBatch(userRoles []model.UserRole) error {
+	if len(userRoles) == 0 {
+		return nil
+	}
+	return r.DB().Create(&userRoles).Error
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,6 +15,8 @@ type UserTokenRepository interface {
 	FindByUserID(userID int64) ([]model.UserToken, error)
 	FindActiveTokensByUserID(userID int64) ([]model.UserToken, error)
 	FindByUserIDAndTokenType(userID int64, tokenType string) ([]model.UserToken, error)
+	FindActiveByTokenAndType(token, tokenType string) (*model.UserToken, error)
+	FindUnrevokedByUserIDsAndTokenType(userIDs []int64, tokenType string) ([]model.UserToken, error)
 	RevokeByUUID(tokenUUID uuid.UUID) error
 	RevokeAllByUserID(userID int64) error
 	DeleteByUserID(userID int64) error
@@ -58,6 +61,35 @@ func (r *userTokenRepository) FindByUserIDAndTokenType(userID int64, tokenType s
 	return tokens, err
 }
 
+// FindActiveByTokenAndType returns the non-revoked, unexpired token of the
+// type with the given value. Returns nil, nil when there is none.
+func (r *userTokenRepository) FindActiveByTokenAndType(token, tokenType string) (*model.UserToken, error) {
+	var userToken model.UserToken
+	err := r.DB().
+		Where("token = ? AND token_type = ? AND is_revoked = false AND (expires_at IS NULL OR expires_at > ?)", token, tokenType, time.Now()).
+		First(&userToken).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &userToken, nil
+}
+
+// FindUnrevokedByUserIDsAndTokenType returns the non-revoked tokens of the type
+// held by any of the users, expired or not.
+func (r *userTokenRepository) FindUnrevokedByUserIDsAndTokenType(userIDs []int64, tokenType string) ([]model.UserToken, error) {
+	var tokens []model.UserToken
+	if len(userIDs) == 0 {
+		return tokens, nil
+	}
+	err := r.DB().
+		Where("user_id IN ? AND token_type = ? AND is_revoked = false", userIDs, tokenType).
+		Find(&tokens).Error
+	return tokens, err
+}
+
 func (r *userTokenRepository) RevokeByUUID(tokenUUID uuid.UUID) error {
 	return r.DB().Model(&model.UserToken{}).
 		Where("user_token_uuid = ?", tokenUUID).
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookDeliveryRepositoryGetFilter holds query parameters for paginated
+// webhook delivery lookups.
+type WebhookDeliveryRepositoryGetFilter struct {
+	TenantID          int64
+	WebhookEndpointID int64
+	Status            []string
+	EventType         []string
+	Page              int
+	Limit             int
+}
+
+// WebhookDeliveryRepository defines persistence operations for the
+// webhook_deliveries entity.
+type WebhookDeliveryRepository interface {
+	BaseRepositoryMethods[model.WebhookDelivery]
+	WithTx(tx *gorm.DB) WebhookDeliveryRepository
+	CreateBatch(deliveries []model.WebhookDelivery) error
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error)
+	FindByUUIDAndEndpointID(webhookDeliveryUUID uuid.UUID, webhookEndpointID int64) (*model.WebhookDelivery, error)
+	FindPaginated(filter WebhookDeliveryRepositoryGetFilter) (*PaginationResult[model.WebhookDelivery], error)
+	PendingBacklog() (*Backlog, error)
+}
+
+type webhookDeliveryRepository struct {
+	*BaseRepository[model.WebhookDelivery]
+}
+
+// NewWebhookDeliveryRepository creates a new WebhookDeliveryRepository backed
+// by the given database connection.
+func NewWebhookDeliveryRepository(db *gorm.DB) WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{
+		BaseRepository: NewBaseRepository[model.WebhookDelivery](db, "webhook_delivery_uuid", "webhook_delivery_id"),
+	}
+}
+
+// WithTx returns a copy of the repository bound to the supplied transaction.
+func (r *webhookDeliveryRepository) WithTx(tx *gorm.DB) WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+// CreateBatch inserts deliveries, skipping any event already queued for the
+// same endpoint so that overlapping dispatcher runs never deliver twice.
+func (r *webhookDeliveryRepository) CreateBatch(deliveries []model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.DB().Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+}
+
+// ClaimDue locks up to limit pending deliveries whose next attempt is due
+// and pushes their next attempt lease into the future, so that concurrent
+// dispatchers never pick the same delivery. The endpoint is preloaded.
+func (r *webhookDeliveryRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error) {
+	var ids []int64
+	err := r.DB().Raw(`
+UPDATE webhook_deliveries SET next_attempt_at = ?, updated_at = ?
+WHERE webhook_delivery_id IN (
+    SELECT webhook_delivery_id FROM webhook_deliveries
+    WHERE status = ? AND next_attempt_at <= ?
+    ORDER BY next_attempt_at, webhook_delivery_id
+    LIMIT ?
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING webhook_delivery_id`,
+		now.Add(lease), now, model.WebhookDeliveryStatusPending, now, limit,
+	).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var deliveries []model.WebhookDelivery
+	err = r.DB().Preload("WebhookEndpoint").
+		Where("webhook_delivery_id IN ?", ids).
+		Order("webhook_delivery_id ASC").
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// FindByUUIDAndEndpointID retrieves a single delivery of an endpoint.
+// Returns nil, nil when no record exists.
+func (r *webhookDeliveryRepository) FindByUUIDAndEndpointID(webhookDeliveryUUID uuid.UUID, webhookEndpointID int64) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	err := r.DB().Where("webhook_delivery_uuid = ? AND webhook_endpoint_id = ?", webhookDeliveryUUID, webhookEndpointID).First(&delivery).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// FindPaginated retrieves an endpoint's deliveries, newest first.
+func (r *webhookDeliveryRepository) FindPaginated(filter WebhookDeliveryRepositoryGetFilter) (*PaginationResult[model.WebhookDelivery], error) {
+	query := r.ReadDB().Model(&model.WebhookDelivery{}).
+		Where("tenant_id = ? AND webhook_endpoint_id = ?", filter.TenantID, filter.WebhookEndpointID)
+
+	if len(filter.Status) > 0 {
+		query = query.Where("status IN ?", filter.Status)
+	}
+	if len(filter.EventType) > 0 {
+		query = query.Where("event_type IN ?", filter.EventType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	filter.Page, filter.Limit = normalizePagination(filter.Page, filter.Limit)
+	offset := (filter.Page - 1) * filter.Limit
+
+	var deliveries []model.WebhookDelivery
+	err := query.Order("created_at DESC, webhook_delivery_id DESC").
+		Offset(offset).Limit(filter.Limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / filter.Limit
+	if int(total)%filter.Limit > 0 {
+		totalPages++
+	}
+
+	return &PaginationResult[model.WebhookDelivery]{
+		Data:       deliveries,
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// PendingBacklog returns how many deliveries of every tenant are pending,
+// including those waiting out a retry backoff, and when the oldest of them
+// was queued.
+func (r *webhookDeliveryRepository) PendingBacklog() (*Backlog, error) {
+	var b Backlog
+	err := r.DB().Model(&model.WebhookDelivery{}).
+		Where("status = ?", model.WebhookDeliveryStatusPending).
+		Select("COUNT(*) AS depth, MIN(created_at) AS oldest_at").
+		Scan(&b).Error
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
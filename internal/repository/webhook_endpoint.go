@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/model"
@@ -27,6 +28,9 @@ type WebhookEndpointRepository interface {
 	FindByTenantID(tenantID int64) ([]model.WebhookEndpoint, error)
 	FindByUUIDAndTenantID(webhookEndpointUUID uuid.UUID, tenantID int64) (*model.WebhookEndpoint, error)
 	FindPaginated(filter WebhookEndpointRepositoryGetFilter) (*PaginationResult[model.WebhookEndpoint], error)
+	FindActive() ([]model.WebhookEndpoint, error)
+	UpdateCursors(webhookEndpointID, lastEventID, lastAuthEventID int64) error
+	MarkTriggered(webhookEndpointID int64, at time.Time) error
 }
 
 type webhookEndpointRepository struct {
@@ -74,7 +78,7 @@ func (r *webhookEndpointRepository) FindByUUIDAndTenantID(webhookEndpointUUID uu
 
 // FindPaginated retrieves paginated webhook endpoints with filtering.
 func (r *webhookEndpointRepository) FindPaginated(filter WebhookEndpointRepositoryGetFilter) (*PaginationResult[model.WebhookEndpoint], error) {
-	query := r.DB().Model(&model.WebhookEndpoint{})
+	query := r.ReadDB().Model(&model.WebhookEndpoint{})
 
 	if filter.TenantID != nil {
 		query = query.Where("tenant_id = ?", *filter.TenantID)
@@ -116,3 +120,33 @@ func (r *webhookEndpointRepository) FindPaginated(filter WebhookEndpointReposito
 		TotalPages: totalPages,
 	}, nil
 }
+
+// FindActive retrieves the active webhook endpoints of every tenant.
+func (r *webhookEndpointRepository) FindActive() ([]model.WebhookEndpoint, error) {
+	var endpoints []model.WebhookEndpoint
+	err := r.DB().Where("status = ?", model.StatusActive).
+		Order("webhook_endpoint_id ASC").
+		Find(&endpoints).Error
+	if err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// UpdateCursors stores the dispatcher's positions in the lifecycle and auth
+// event logs for an endpoint.
+func (r *webhookEndpointRepository) UpdateCursors(webhookEndpointID, lastEventID, lastAuthEventID int64) error {
+	return r.DB().Model(&model.WebhookEndpoint{}).
+		Where("webhook_endpoint_id = ?", webhookEndpointID).
+		Updates(map[string]any{
+			"last_event_id":      lastEventID,
+			"last_auth_event_id": lastAuthEventID,
+		}).Error
+}
+
+// MarkTriggered records when a delivery was last attempted for an endpoint.
+func (r *webhookEndpointRepository) MarkTriggered(webhookEndpointID int64, at time.Time) error {
+	return r.DB().Model(&model.WebhookEndpoint{}).
+		Where("webhook_endpoint_id = ?", webhookEndpointID).
+		UpdateColumn("last_triggered_at", at).Error
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AccountDeletionHandler lets users delete their own account, and cancel the
+// deletion during the grace period.
+type AccountDeletionHandler struct {
+	userService service.UserService
+}
+
+// NewAccountDeletionHandler creates a new AccountDeletionHandler.
+func NewAccountDeletionHandler(userService service.UserService) *AccountDeletionHandler {
+	return &AccountDeletionHandler{userService: userService}
+}
+
+// Delete schedules the deletion of the authenticated user's account. Sign in
+// stops at once and a confirmation email with a cancel link is sent.
+//
+// DELETE /account
+func (h *AccountDeletionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	deletion, err := h.userService.ScheduleSelfDeletion(r.Context(), auth.User.UserUUID, auth.Tenant.TenantID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to delete account", err)
+		return
+	}
+
+	resp.Success(w, dto.AccountDeletionResponseDTO{
+		UserUUID:     deletion.UserUUID,
+		ScheduledFor: deletion.ScheduledFor,
+	}, "Account scheduled for deletion")
+}
+
+// Cancel restores an account scheduled for deletion. It is public, since the
+// account can no longer sign in; the emailed token authorizes the request.
+//
+// POST /account/deletion/cancel
+func (h *AccountDeletionHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	var req dto.AccountDeletionCancelRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	user, err := h.userService.CancelSelfDeletion(r.Context(), req.Token)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to cancel account deletion", err)
+		return
+	}
+
+	resp.Success(w, toUserResponseDTO(*user), "Account deletion cancelled")
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountDeletionHandler_Delete(t *testing.T) {
+	t.Run("no user", func(t *testing.T) {
+		h := NewAccountDeletionHandler(&mockUserService{})
+		w := httptest.NewRecorder()
+		h.Delete(w, withTenant(httptest.NewRequest(http.MethodDelete, "/", nil)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("no tenant", func(t *testing.T) {
+		h := NewAccountDeletionHandler(&mockUserService{})
+		w := httptest.NewRecorder()
+		h.Delete(w, withUser(httptest.NewRequest(http.MethodDelete, "/", nil)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewAccountDeletionHandler(&mockUserService{
+			scheduleDeleteFn: func(uuid.UUID, int64) (*service.AccountDeletionServiceDataResult, error) {
+				return nil, apperror.NewValidation("user is already deleted")
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Delete(w, withTenantAndUser(httptest.NewRequest(http.MethodDelete, "/", nil)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		scheduledFor := time.Now().Add(14 * 24 * time.Hour)
+		h := NewAccountDeletionHandler(&mockUserService{
+			scheduleDeleteFn: func(userUUID uuid.UUID, tid int64) (*service.AccountDeletionServiceDataResult, error) {
+				assert.Equal(t, testUserUUID, userUUID)
+				assert.Equal(t, tenantID, tid)
+				return &service.AccountDeletionServiceDataResult{UserUUID: userUUID, ScheduledFor: scheduledFor}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Delete(w, withTenantAndUser(httptest.NewRequest(http.MethodDelete, "/", nil)))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"scheduled_for"`)
+		assert.Contains(t, w.Body.String(), testUserUUID.String())
+	})
+}
+
+func TestAccountDeletionHandler_Cancel(t *testing.T) {
+	body := dto.AccountDeletionCancelRequestDTO{Token: strings.Repeat("ab", 32)}
+
+	t.Run("invalid json", func(t *testing.T) {
+		h := NewAccountDeletionHandler(&mockUserService{})
+		w := httptest.NewRecorder()
+		h.Cancel(w, badJSONReq(t, http.MethodPost, "/"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		h := NewAccountDeletionHandler(&mockUserService{})
+		w := httptest.NewRecorder()
+		h.Cancel(w, jsonReq(t, http.MethodPost, "/", dto.AccountDeletionCancelRequestDTO{Token: "short"}))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewAccountDeletionHandler(&mockUserService{
+			cancelDeleteFn: func(string) (*service.UserServiceDataResult, error) {
+				return nil, apperror.NewValidation("invalid or expired cancellation token")
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Cancel(w, jsonReq(t, http.MethodPost, "/", body))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewAccountDeletionHandler(&mockUserService{
+			cancelDeleteFn: func(token string) (*service.UserServiceDataResult, error) {
+				assert.Equal(t, body.Token, token)
+				return &service.UserServiceDataResult{UserUUID: testUserUUID, Status: "active"}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Cancel(w, jsonReq(t, http.MethodPost, "/", body))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"active"`)
+	})
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AccountIdentityHandler handles the self-service identity linking
+// endpoints (list, link and unlink the caller's external identities).
+type AccountIdentityHandler struct {
+	accountIdentityService service.AccountIdentityService
+}
+
+// NewAccountIdentityHandler creates a new AccountIdentityHandler.
+func NewAccountIdentityHandler(accountIdentityService service.AccountIdentityService) *AccountIdentityHandler {
+	return &AccountIdentityHandler{accountIdentityService: accountIdentityService}
+}
+
+// List handles GET /account/identities. Returns the authenticated user's
+// identities in the tenant.
+func (h *AccountIdentityHandler) List(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	identities, err := h.accountIdentityService.List(r.Context(), auth.User.UserID, auth.Tenant.TenantID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to retrieve identities", err)
+		return
+	}
+
+	rows := make([]dto.UserIdentityResponseDTO, len(identities))
+	for i, identity := range identities {
+		rows[i] = toUserIdentityResponseDTO(identity)
+	}
+
+	resp.Success(w, rows, "Identities retrieved successfully")
+}
+
+// Link handles POST /account/identities. Links the external identity of
+// the given ID token after re-authenticating the caller with their password.
+func (h *AccountIdentityHandler) Link(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	var req dto.AccountIdentityLinkRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	identity, err := h.accountIdentityService.Link(r.Context(), auth.User, auth.Tenant.TenantID, req)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to link identity", err)
+		return
+	}
+
+	resp.Created(w, toUserIdentityResponseDTO(*identity), "Identity linked successfully")
+}
+
+// Unlink handles DELETE /account/identities/{user_identity_uuid}.
+func (h *AccountIdentityHandler) Unlink(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	identityUUID, err := uuid.Parse(chi.URLParam(r, "user_identity_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid identity UUID")
+		return
+	}
+
+	if err := h.accountIdentityService.Unlink(r.Context(), auth.User, auth.Tenant.TenantID, identityUUID); err != nil {
+		resp.HandleServiceError(w, r, "Failed to unlink identity", err)
+		return
+	}
+
+	resp.Success(w, nil, "Identity unlinked successfully")
+}
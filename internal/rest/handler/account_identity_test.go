@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// ---------------------------------------------------------------------------
+// List
+// ---------------------------------------------------------------------------
+
+func TestAccountIdentityHandler_List(t *testing.T) {
+	t.Run("no user", func(t *testing.T) {
+		h := NewAccountIdentityHandler(&mockAccountIdentityService{})
+		w := httptest.NewRecorder()
+		h.List(w, httptest.NewRequest(http.MethodGet, "/account/identities", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("no tenant", func(t *testing.T) {
+		h := NewAccountIdentityHandler(&mockAccountIdentityService{})
+		w := httptest.NewRecorder()
+		h.List(w, withUser(httptest.NewRequest(http.MethodGet, "/account/identities", nil)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewAccountIdentityHandler(&mockAccountIdentityService{
+			listFn: func(context.Context, int64, int64) ([]service.UserIdentityServiceDataResult, error) {
+				return nil, errNotFound
+			},
+		})
+		w := httptest.NewRecorder()
+		h.List(w, withTenantAndUser(httptest.NewRequest(http.MethodGet, "/account/identities", nil)))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewAccountIdentityHandler(&mockAccountIdentityService{
+			listFn: func(_ context.Context, _ int64, tid int64) ([]service.UserIdentityServiceDataResult, error) {
+				assert.Equal(t, tenantID, tid)
+				return []service.UserIdentityServiceDataResult{{Provider: "google", Sub: "upstream-1"}}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.List(w, withTenantAndUser(httptest.NewRequest(http.MethodGet, "/account/identities", nil)))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "upstream-1")
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Link
+// ---------------------------------------------------------------------------
+
+func TestAccountIdentityHandler_Link(t *testing.T) {
+	validReq := dto.AccountIdentityLinkRequestDTO{
+		ClientUUID: uuid.New().String(),
+		IDToken:    "header.payload.signature",
+		Password:   "Secret123!",
+	}
+
+	t.Run("no user", func(t *testing.T) {
+		h := NewAccountIdentityHandler(&mockAccountIdentityService{})
+		w := httptest.NewRecorder()
+		h.Link(w, httptest.NewRequest(http.MethodPost, "/account/identities", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("no tenant", func(t *testing.T) {
+		h := NewAccountIdentityHandler(&mockAccountIdentityService{})
+		w := httptest.NewRecorder()
+		h.Link(w, withUser(httptest.NewRequest(http.MethodPost, "/account/identities", nil)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("bad json", func(t *testing.T) {
+		h := NewAccountIdentityHandler(&mockAccountIdentityService{})
+		w := httptest.NewRecorder()
+		h.Link(w, withTenantAndUser(badJSONReq(t, http.MethodPost, "/account/identities")))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		h := NewAccountIdentityHandler(&mockAccountIdentityService{})
+		w := httptest.NewRecorder()
+		h.Link(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/account/identities",
+			dto.AccountIdentityLinkRequestDTO{ClientUUID: "not-a-uuid"})))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewAccountIdentityHandler(&mockAccountIdentityService{
+			linkFn: func(context.Context, *model.User, int64, dto.AccountIdentityLinkRequestDTO) (*service.UserIdentityServiceDataResult, error) {
+				return nil, errValidation
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Link(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/account/identities", validReq)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewAccountIdentityHandler(&mockAccountIdentityService{
+			linkFn: func(_ context.Context, user *model.User, tid int64, req dto.AccountIdentityLinkRequestDTO) (*service.UserIdentityServiceDataResult, error) {
+				assert.Equal(t, testUserUUID, user.UserUUID)
+				assert.Equal(t, tenantID, tid)
+				assert.Equal(t, validReq, req)
+				return &service.UserIdentityServiceDataResult{Provider: "google", Sub: "upstream-1"}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Link(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/account/identities", validReq)))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), "upstream-1")
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Unlink
+// ---------------------------------------------------------------------------
+
+func TestAccountIdentityHandler_Unlink(t *testing.T) {
+	identityUUID := uuid.New()
+
+	t.Run("no user", func(t *testing.T) {
+		h := NewAccountIdentityHandler(&mockAccountIdentityService{})
+		w := httptest.NewRecorder()
+		h.Unlink(w, httptest.NewRequest(http.MethodDelete, "/account/identities/x", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid uuid", func(t *testing.T) {
+		h := NewAccountIdentityHandler(&mockAccountIdentityService{})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenantAndUser(httptest.NewRequest(http.MethodDelete, "/", nil)), "user_identity_uuid", "x")
+		h.Unlink(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		h := NewAccountIdentityHandler(&mockAccountIdentityService{
+			unlinkFn: func(context.Context, *model.User, int64, uuid.UUID) error { return errNotFound },
+		})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenantAndUser(httptest.NewRequest(http.MethodDelete, "/", nil)), "user_identity_uuid", identityUUID.String())
+		h.Unlink(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewAccountIdentityHandler(&mockAccountIdentityService{
+			unlinkFn: func(_ context.Context, _ *model.User, _ int64, id uuid.UUID) error {
+				assert.Equal(t, identityUUID, id)
+				return nil
+			},
+		})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenantAndUser(httptest.NewRequest(http.MethodDelete, "/", nil)), "user_identity_uuid", identityUUID.String())
+		h.Unlink(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
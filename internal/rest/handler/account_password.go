@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AccountPasswordHandler lets users change their own password.
+type AccountPasswordHandler struct {
+	userService service.UserService
+}
+
+// NewAccountPasswordHandler creates a new AccountPasswordHandler.
+func NewAccountPasswordHandler(userService service.UserService) *AccountPasswordHandler {
+	return &AccountPasswordHandler{userService: userService}
+}
+
+// ChangePassword changes the authenticated user's password after checking
+// the current one. Other sessions are signed out and a notification email is
+// sent.
+//
+// POST /account/change-password
+func (h *AccountPasswordHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	var req dto.AccountChangePasswordRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	// Browser clients keep the refresh token in a cookie
+	refreshToken := req.RefreshToken
+	if refreshToken == "" {
+		if c, err := r.Cookie("refresh_token"); err == nil {
+			refreshToken = c.Value
+		}
+	}
+
+	if err := h.userService.ChangePassword(r.Context(), auth.User.UserUUID, auth.Tenant.TenantID, req.CurrentPassword, req.NewPassword, refreshToken); err != nil {
+		resp.HandleServiceError(w, r, "Failed to change password", err)
+		return
+	}
+
+	resp.Success(w, nil, "Password changed successfully")
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountPasswordHandler_ChangePassword(t *testing.T) {
+	body := dto.AccountChangePasswordRequestDTO{CurrentPassword: "Old-Secret1", NewPassword: "New-Secret2"}
+
+	t.Run("no user", func(t *testing.T) {
+		h := NewAccountPasswordHandler(&mockUserService{})
+		w := httptest.NewRecorder()
+		h.ChangePassword(w, withTenant(jsonReq(t, http.MethodPost, "/", body)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("no tenant", func(t *testing.T) {
+		h := NewAccountPasswordHandler(&mockUserService{})
+		w := httptest.NewRecorder()
+		h.ChangePassword(w, withUser(jsonReq(t, http.MethodPost, "/", body)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		h := NewAccountPasswordHandler(&mockUserService{})
+		w := httptest.NewRecorder()
+		h.ChangePassword(w, withTenantAndUser(badJSONReq(t, http.MethodPost, "/")))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		h := NewAccountPasswordHandler(&mockUserService{})
+		w := httptest.NewRecorder()
+		h.ChangePassword(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/", dto.AccountChangePasswordRequestDTO{NewPassword: "New-Secret2"})))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("wrong current password", func(t *testing.T) {
+		h := NewAccountPasswordHandler(&mockUserService{
+			changePasswordFn: func(uuid.UUID, int64, string, string, string) error {
+				return apperror.NewValidation("current password is incorrect")
+			},
+		})
+		w := httptest.NewRecorder()
+		h.ChangePassword(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/", body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success keeps the session of the body's refresh token", func(t *testing.T) {
+		var gotRefreshToken string
+		h := NewAccountPasswordHandler(&mockUserService{
+			changePasswordFn: func(userUUID uuid.UUID, tid int64, current, next, refreshToken string) error {
+				assert.Equal(t, testUserUUID, userUUID)
+				assert.Equal(t, tenantID, tid)
+				assert.Equal(t, body.CurrentPassword, current)
+				assert.Equal(t, body.NewPassword, next)
+				gotRefreshToken = refreshToken
+				return nil
+			},
+		})
+		withToken := body
+		withToken.RefreshToken = "rt-body"
+		r := withTenantAndUser(jsonReq(t, http.MethodPost, "/", withToken))
+		r.AddCookie(&http.Cookie{Name: "refresh_token", Value: "rt-cookie"})
+		w := httptest.NewRecorder()
+		h.ChangePassword(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "rt-body", gotRefreshToken)
+	})
+
+	t.Run("refresh token from the cookie", func(t *testing.T) {
+		var gotRefreshToken string
+		h := NewAccountPasswordHandler(&mockUserService{
+			changePasswordFn: func(_ uuid.UUID, _ int64, _, _, refreshToken string) error {
+				gotRefreshToken = refreshToken
+				return nil
+			},
+		})
+		r := withTenantAndUser(jsonReq(t, http.MethodPost, "/", body))
+		r.AddCookie(&http.Cookie{Name: "refresh_token", Value: "rt-cookie"})
+		w := httptest.NewRecorder()
+		h.ChangePassword(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "rt-cookie", gotRefreshToken)
+	})
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AccountPermissionHandler serves the caller's own effective permissions.
+type AccountPermissionHandler struct {
+	permissionResolver service.PermissionResolver
+}
+
+// NewAccountPermissionHandler creates a new AccountPermissionHandler.
+func NewAccountPermissionHandler(permissionResolver service.PermissionResolver) *AccountPermissionHandler {
+	return &AccountPermissionHandler{permissionResolver: permissionResolver}
+}
+
+// Get handles GET /account/permissions. Returns the permissions the
+// authenticated user holds through their roles, across all tenants and per
+// tenant.
+func (h *AccountPermissionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	perms, err := h.permissionResolver.Resolve(r.Context(), user)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to resolve permissions", err)
+		return
+	}
+
+	resp.Success(w, perms, "Permissions retrieved successfully")
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountPermissionHandler_Get(t *testing.T) {
+	t.Run("no user", func(t *testing.T) {
+		h := NewAccountPermissionHandler(&mockPermissionResolver{})
+		w := httptest.NewRecorder()
+		h.Get(w, httptest.NewRequest(http.MethodGet, "/account/permissions", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewAccountPermissionHandler(&mockPermissionResolver{
+			resolveFn: func(context.Context, *model.User) (*dto.EffectivePermissionsResponseDTO, error) {
+				return nil, errNotFound
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Get(w, withUser(httptest.NewRequest(http.MethodGet, "/account/permissions", nil)))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewAccountPermissionHandler(&mockPermissionResolver{
+			resolveFn: func(_ context.Context, user *model.User) (*dto.EffectivePermissionsResponseDTO, error) {
+				assert.Equal(t, testUserUUID, user.UserUUID)
+				return &dto.EffectivePermissionsResponseDTO{Permissions: []string{"user:read"}}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Get(w, withUser(httptest.NewRequest(http.MethodGet, "/account/permissions", nil)))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var body struct {
+			Data dto.EffectivePermissionsResponseDTO `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		assert.Equal(t, []string{"user:read"}, body.Data.Permissions)
+	})
+}
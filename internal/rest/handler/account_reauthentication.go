@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AccountReauthenticationHandler lets users answer a step-up challenge.
+type AccountReauthenticationHandler struct {
+	stepUpService service.StepUpService
+}
+
+// NewAccountReauthenticationHandler creates a new
+// AccountReauthenticationHandler.
+func NewAccountReauthenticationHandler(stepUpService service.StepUpService) *AccountReauthenticationHandler {
+	return &AccountReauthenticationHandler{stepUpService: stepUpService}
+}
+
+// Reauthenticate checks the authenticated user's password and lets the
+// credential of the request, its login session, token or personal access
+// token, perform step-up protected actions until the returned expiry.
+// Impersonation tokens cannot re-authenticate, so an admin acting as a user
+// can never perform them.
+//
+// POST /account/reauthenticate
+func (h *AccountReauthenticationHandler) Reauthenticate(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+	if claims := middleware.JWTClaimsFromRequest(r); claims != nil && claims.Actor != "" {
+		resp.Error(w, http.StatusForbidden, "Impersonation tokens cannot re-authenticate")
+		return
+	}
+
+	var req dto.AccountReauthenticateRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.stepUpService.Reauthenticate(r.Context(), auth.User, auth.Tenant.TenantID, middleware.StepUpCredential(r), req.Password)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to re-authenticate", err)
+		return
+	}
+
+	resp.Success(w, dto.AccountReauthenticateResponseDTO{
+		ReauthenticatedAt: result.ReauthenticatedAt,
+		ExpiresAt:         result.ExpiresAt,
+	}, "Re-authenticated successfully")
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountReauthenticationHandler_Reauthenticate(t *testing.T) {
+	body := dto.AccountReauthenticateRequestDTO{Password: "Secret-123"}
+
+	t.Run("no user", func(t *testing.T) {
+		h := NewAccountReauthenticationHandler(&mockStepUpService{})
+		w := httptest.NewRecorder()
+		h.Reauthenticate(w, withTenant(jsonReq(t, http.MethodPost, "/", body)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("no tenant", func(t *testing.T) {
+		h := NewAccountReauthenticationHandler(&mockStepUpService{})
+		w := httptest.NewRecorder()
+		h.Reauthenticate(w, withUser(jsonReq(t, http.MethodPost, "/", body)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("impersonation token", func(t *testing.T) {
+		h := NewAccountReauthenticationHandler(&mockStepUpService{})
+		req := middleware.WithJWTClaims(withTenantAndUser(jsonReq(t, http.MethodPost, "/", body)), &middleware.JWTClaims{JTI: "j", Actor: "admin"})
+		w := httptest.NewRecorder()
+		h.Reauthenticate(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		h := NewAccountReauthenticationHandler(&mockStepUpService{})
+		w := httptest.NewRecorder()
+		h.Reauthenticate(w, withTenantAndUser(badJSONReq(t, http.MethodPost, "/")))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		h := NewAccountReauthenticationHandler(&mockStepUpService{})
+		w := httptest.NewRecorder()
+		h.Reauthenticate(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/", dto.AccountReauthenticateRequestDTO{})))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		h := NewAccountReauthenticationHandler(&mockStepUpService{
+			reauthenticateFn: func(*model.User, int64, string, string) (*service.StepUpServiceDataResult, error) {
+				return nil, apperror.NewValidation("password is incorrect")
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Reauthenticate(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/", body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		at := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+		h := NewAccountReauthenticationHandler(&mockStepUpService{
+			reauthenticateFn: func(user *model.User, tid int64, credential, password string) (*service.StepUpServiceDataResult, error) {
+				assert.Equal(t, testUserUUID, user.UserUUID)
+				assert.Equal(t, tenantID, tid)
+				assert.Equal(t, "sid:s1", credential)
+				assert.Equal(t, "Secret-123", password)
+				return &service.StepUpServiceDataResult{ReauthenticatedAt: at, ExpiresAt: at.Add(5 * time.Minute)}, nil
+			},
+		})
+		req := middleware.WithJWTClaims(withTenantAndUser(jsonReq(t, http.MethodPost, "/", body)), &middleware.JWTClaims{SessionID: "s1"})
+		w := httptest.NewRecorder()
+		h.Reauthenticate(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var res struct {
+			Data dto.AccountReauthenticateResponseDTO `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+		assert.True(t, at.Add(5*time.Minute).Equal(res.Data.ExpiresAt))
+	})
+}
@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AccountRecoveryHandler lets users manage their recovery codes and recovery
+// email, and regain access to their account with them.
+type AccountRecoveryHandler struct {
+	accountRecoveryService service.AccountRecoveryService
+}
+
+// NewAccountRecoveryHandler creates a new AccountRecoveryHandler.
+func NewAccountRecoveryHandler(accountRecoveryService service.AccountRecoveryService) *AccountRecoveryHandler {
+	return &AccountRecoveryHandler{accountRecoveryService: accountRecoveryService}
+}
+
+// Get returns how many recovery codes the authenticated user has left and
+// their recovery email.
+//
+// GET /account/recovery
+func (h *AccountRecoveryHandler) Get(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	result, err := h.accountRecoveryService.Status(r.Context(), auth.User.UserID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to fetch recovery settings", err)
+		return
+	}
+
+	resp.Success(w, toAccountRecoveryResponseDTO(result), "Recovery settings fetched successfully")
+}
+
+// GenerateCodes replaces the authenticated user's recovery codes with a new
+// set and returns them. They are never shown again.
+//
+// POST /account/recovery/codes
+func (h *AccountRecoveryHandler) GenerateCodes(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	var req dto.AccountRecoveryCodesRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	recoveryCodes, err := h.accountRecoveryService.GenerateCodes(r.Context(), auth.User.UserID, auth.Tenant.TenantID, req.Password)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to generate recovery codes", err)
+		return
+	}
+
+	resp.Created(w, dto.AccountRecoveryCodesResponseDTO{RecoveryCodes: recoveryCodes}, "Recovery codes generated successfully")
+}
+
+// SetEmail sets the authenticated user's recovery email and sends it a
+// verification code.
+//
+// PUT /account/recovery/email
+func (h *AccountRecoveryHandler) SetEmail(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	var req dto.AccountRecoveryEmailRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.accountRecoveryService.SetRecoveryEmail(r.Context(), auth.User.UserID, auth.Tenant.TenantID, req.Password, req.Email)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to set recovery email", err)
+		return
+	}
+
+	resp.Success(w, toAccountRecoveryResponseDTO(result), "Verification code sent to the recovery email")
+}
+
+// VerifyEmail verifies the authenticated user's recovery email with the
+// emailed code.
+//
+// POST /account/recovery/email/verify
+func (h *AccountRecoveryHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	var req dto.AccountRecoveryEmailVerifyRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.accountRecoveryService.VerifyRecoveryEmail(r.Context(), auth.User.UserID, auth.Tenant.TenantID, req.Code)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to verify recovery email", err)
+		return
+	}
+
+	resp.Success(w, toAccountRecoveryResponseDTO(result), "Recovery email verified successfully")
+}
+
+// RemoveEmail clears the authenticated user's recovery email.
+//
+// DELETE /account/recovery/email
+func (h *AccountRecoveryHandler) RemoveEmail(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	result, err := h.accountRecoveryService.RemoveRecoveryEmail(r.Context(), auth.User.UserID, auth.Tenant.TenantID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to remove recovery email", err)
+		return
+	}
+
+	resp.Success(w, toAccountRecoveryResponseDTO(result), "Recovery email removed successfully")
+}
+
+// StartRecovery emails a recovery code to the verified recovery email of the
+// account. The answer is the same whether or not the account exists.
+//
+// POST /account/recover/start?client_id=...&provider_id=...
+func (h *AccountRecoveryHandler) StartRecovery(w http.ResponseWriter, r *http.Request) {
+	clientID := r.URL.Query().Get("client_id")
+	providerID := r.URL.Query().Get("provider_id")
+	if clientID == "" || providerID == "" {
+		resp.Error(w, http.StatusBadRequest, "Missing required parameters: client_id and provider_id")
+		return
+	}
+
+	var req dto.AccountRecoveryStartRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	if err := h.accountRecoveryService.StartRecovery(r.Context(), clientID, providerID, req.Email); err != nil {
+		resp.HandleServiceError(w, r, "Failed to start account recovery", err)
+		return
+	}
+
+	resp.Success(w, nil, "If the account has a verified recovery email, a recovery code has been sent to it")
+}
+
+// Recover sets a new password once one of the user's recovery codes and the
+// code emailed by StartRecovery are both valid. Every session is signed out.
+//
+// POST /account/recover?client_id=...&provider_id=...
+func (h *AccountRecoveryHandler) Recover(w http.ResponseWriter, r *http.Request) {
+	clientID := r.URL.Query().Get("client_id")
+	providerID := r.URL.Query().Get("provider_id")
+	if clientID == "" || providerID == "" {
+		resp.Error(w, http.StatusBadRequest, "Missing required parameters: client_id and provider_id")
+		return
+	}
+
+	var req dto.AccountRecoveryRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	remaining, err := h.accountRecoveryService.CompleteRecovery(r.Context(), clientID, providerID, service.AccountRecoveryInput{
+		Email:        req.Email,
+		RecoveryCode: req.RecoveryCode,
+		Code:         req.Code,
+		NewPassword:  req.NewPassword,
+	})
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to recover account", err)
+		return
+	}
+
+	resp.Success(w, dto.AccountRecoveredResponseDTO{RecoveryCodesRemaining: remaining}, "Account recovered successfully")
+}
+
+func toAccountRecoveryResponseDTO(r *service.AccountRecoveryServiceDataResult) dto.AccountRecoveryResponseDTO {
+	return dto.AccountRecoveryResponseDTO{
+		RecoveryCodesRemaining: r.RecoveryCodesRemaining,
+		RecoveryEmail:          r.RecoveryEmail,
+		RecoveryEmailVerified:  r.RecoveryEmailVerified,
+	}
+}
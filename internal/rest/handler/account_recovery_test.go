@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+const recoverQuery = "?client_id=client&provider_id=provider"
+
+// ---------------------------------------------------------------------------
+// Get
+// ---------------------------------------------------------------------------
+
+func TestAccountRecoveryHandler_Get_Unauthenticated(t *testing.T) {
+	h := NewAccountRecoveryHandler(&mockAccountRecoveryService{})
+	w := httptest.NewRecorder()
+	h.Get(w, httptest.NewRequest(http.MethodGet, "/account/recovery", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAccountRecoveryHandler_Get_Success(t *testing.T) {
+	svc := &mockAccountRecoveryService{
+		statusFn: func(int64) (*service.AccountRecoveryServiceDataResult, error) {
+			return &service.AccountRecoveryServiceDataResult{RecoveryCodesRemaining: 7}, nil
+		},
+	}
+	h := NewAccountRecoveryHandler(svc)
+	w := httptest.NewRecorder()
+	h.Get(w, withTenantAndUser(httptest.NewRequest(http.MethodGet, "/account/recovery", nil)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"recovery_codes_remaining":7`)
+}
+
+// ---------------------------------------------------------------------------
+// GenerateCodes
+// ---------------------------------------------------------------------------
+
+func TestAccountRecoveryHandler_GenerateCodes_NoTenant(t *testing.T) {
+	h := NewAccountRecoveryHandler(&mockAccountRecoveryService{})
+	w := httptest.NewRecorder()
+	h.GenerateCodes(w, withUser(jsonReq(t, http.MethodPost, "/account/recovery/codes", map[string]any{"password": "x"})))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAccountRecoveryHandler_GenerateCodes_ValidationError(t *testing.T) {
+	h := NewAccountRecoveryHandler(&mockAccountRecoveryService{})
+	w := httptest.NewRecorder()
+	h.GenerateCodes(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/account/recovery/codes", map[string]any{})))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAccountRecoveryHandler_GenerateCodes_Success(t *testing.T) {
+	svc := &mockAccountRecoveryService{
+		generateCodesFn: func(_, _ int64, password string) ([]string, error) {
+			assert.Equal(t, "Secret-123", password)
+			return []string{"abcde-fghij"}, nil
+		},
+	}
+	h := NewAccountRecoveryHandler(svc)
+	w := httptest.NewRecorder()
+	h.GenerateCodes(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/account/recovery/codes", map[string]any{"password": "Secret-123"})))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"recovery_codes":["abcde-fghij"]`)
+}
+
+// ---------------------------------------------------------------------------
+// SetEmail / VerifyEmail / RemoveEmail
+// ---------------------------------------------------------------------------
+
+func TestAccountRecoveryHandler_SetEmail_BadJSON(t *testing.T) {
+	h := NewAccountRecoveryHandler(&mockAccountRecoveryService{})
+	w := httptest.NewRecorder()
+	h.SetEmail(w, withTenantAndUser(badJSONReq(t, http.MethodPut, "/account/recovery/email")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAccountRecoveryHandler_SetEmail_Success(t *testing.T) {
+	svc := &mockAccountRecoveryService{
+		setEmailFn: func(_, _ int64, _, email string) (*service.AccountRecoveryServiceDataResult, error) {
+			return &service.AccountRecoveryServiceDataResult{RecoveryEmail: &email}, nil
+		},
+	}
+	h := NewAccountRecoveryHandler(svc)
+	w := httptest.NewRecorder()
+	h.SetEmail(w, withTenantAndUser(jsonReq(t, http.MethodPut, "/account/recovery/email", map[string]any{
+		"email": "backup@example.com", "password": "Secret-123",
+	})))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"recovery_email":"backup@example.com"`)
+}
+
+func TestAccountRecoveryHandler_VerifyEmail_ServiceError(t *testing.T) {
+	svc := &mockAccountRecoveryService{
+		verifyEmailFn: func(int64, int64, string) (*service.AccountRecoveryServiceDataResult, error) {
+			return nil, errValidation
+		},
+	}
+	h := NewAccountRecoveryHandler(svc)
+	w := httptest.NewRecorder()
+	h.VerifyEmail(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/account/recovery/email/verify", map[string]any{"code": "123456"})))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAccountRecoveryHandler_RemoveEmail_Success(t *testing.T) {
+	h := NewAccountRecoveryHandler(&mockAccountRecoveryService{})
+	w := httptest.NewRecorder()
+	h.RemoveEmail(w, withTenantAndUser(httptest.NewRequest(http.MethodDelete, "/account/recovery/email", nil)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"recovery_email":null`)
+}
+
+// ---------------------------------------------------------------------------
+// StartRecovery / Recover
+// ---------------------------------------------------------------------------
+
+func TestAccountRecoveryHandler_StartRecovery_MissingParams(t *testing.T) {
+	h := NewAccountRecoveryHandler(&mockAccountRecoveryService{})
+	w := httptest.NewRecorder()
+	h.StartRecovery(w, jsonReq(t, http.MethodPost, "/account/recover/start", map[string]any{"email": "jane@example.com"}))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAccountRecoveryHandler_StartRecovery_Success(t *testing.T) {
+	svc := &mockAccountRecoveryService{
+		startFn: func(clientID, providerID, email string) error {
+			assert.Equal(t, "client", clientID)
+			assert.Equal(t, "provider", providerID)
+			assert.Equal(t, "jane@example.com", email)
+			return nil
+		},
+	}
+	h := NewAccountRecoveryHandler(svc)
+	w := httptest.NewRecorder()
+	h.StartRecovery(w, jsonReq(t, http.MethodPost, "/account/recover/start"+recoverQuery, map[string]any{"email": "jane@example.com"}))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAccountRecoveryHandler_Recover_ValidationError(t *testing.T) {
+	h := NewAccountRecoveryHandler(&mockAccountRecoveryService{})
+	w := httptest.NewRecorder()
+	h.Recover(w, jsonReq(t, http.MethodPost, "/account/recover"+recoverQuery, map[string]any{
+		"email": "jane@example.com", "recovery_code": "abcde-fghij", "code": "12ab56", "new_password": "x",
+	}))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAccountRecoveryHandler_Recover_ServiceError(t *testing.T) {
+	svc := &mockAccountRecoveryService{
+		completeFn: func(string, string, service.AccountRecoveryInput) (int64, error) { return 0, errValidation },
+	}
+	h := NewAccountRecoveryHandler(svc)
+	w := httptest.NewRecorder()
+	h.Recover(w, jsonReq(t, http.MethodPost, "/account/recover"+recoverQuery, map[string]any{
+		"email": "jane@example.com", "recovery_code": "abcde-fghij", "code": "123456", "new_password": "N3w-Passw0rd!",
+	}))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAccountRecoveryHandler_Recover_Success(t *testing.T) {
+	svc := &mockAccountRecoveryService{
+		completeFn: func(_, _ string, in service.AccountRecoveryInput) (int64, error) {
+			assert.Equal(t, "abcde-fghij", in.RecoveryCode)
+			assert.Equal(t, "123456", in.Code)
+			return 3, nil
+		},
+	}
+	h := NewAccountRecoveryHandler(svc)
+	w := httptest.NewRecorder()
+	h.Recover(w, jsonReq(t, http.MethodPost, "/account/recover"+recoverQuery, map[string]any{
+		"email": "jane@example.com", "recovery_code": "abcde-fghij", "code": "123456", "new_password": "N3w-Passw0rd!",
+	}))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"recovery_codes_remaining":3`)
+}
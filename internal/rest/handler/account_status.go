@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AccountStatusHandler lets users temporarily disable their own account, and
+// reactivate it later.
+type AccountStatusHandler struct {
+	userService service.UserService
+}
+
+// NewAccountStatusHandler creates a new AccountStatusHandler.
+func NewAccountStatusHandler(userService service.UserService) *AccountStatusHandler {
+	return &AccountStatusHandler{userService: userService}
+}
+
+// Disable disables the authenticated user's account. Sign in stops at once,
+// data is kept and an email with a reactivation link is sent.
+//
+// POST /account/disable
+func (h *AccountStatusHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	user, err := h.userService.DisableSelf(r.Context(), auth.User.UserUUID, auth.Tenant.TenantID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to disable account", err)
+		return
+	}
+
+	resp.Success(w, toUserResponseDTO(*user), "Account disabled")
+}
+
+// Reactivate reactivates a disabled account. It is public, since the account
+// can no longer sign in; the emailed token authorizes the request.
+//
+// POST /account/reactivate
+func (h *AccountStatusHandler) Reactivate(w http.ResponseWriter, r *http.Request) {
+	var req dto.AccountReactivateRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	user, err := h.userService.ReactivateSelf(r.Context(), req.Token)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to reactivate account", err)
+		return
+	}
+
+	resp.Success(w, toUserResponseDTO(*user), "Account reactivated")
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountStatusHandler_Disable(t *testing.T) {
+	t.Run("no user", func(t *testing.T) {
+		h := NewAccountStatusHandler(&mockUserService{})
+		w := httptest.NewRecorder()
+		h.Disable(w, withTenant(httptest.NewRequest(http.MethodPost, "/", nil)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("no tenant", func(t *testing.T) {
+		h := NewAccountStatusHandler(&mockUserService{})
+		w := httptest.NewRecorder()
+		h.Disable(w, withUser(httptest.NewRequest(http.MethodPost, "/", nil)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewAccountStatusHandler(&mockUserService{
+			disableSelfFn: func(uuid.UUID, int64) (*service.UserServiceDataResult, error) {
+				return nil, apperror.NewValidation("only an active account can be disabled")
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Disable(w, withTenantAndUser(httptest.NewRequest(http.MethodPost, "/", nil)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewAccountStatusHandler(&mockUserService{
+			disableSelfFn: func(userUUID uuid.UUID, tid int64) (*service.UserServiceDataResult, error) {
+				assert.Equal(t, testUserUUID, userUUID)
+				assert.Equal(t, tenantID, tid)
+				return &service.UserServiceDataResult{UserUUID: userUUID, Status: model.StatusDisabled}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Disable(w, withTenantAndUser(httptest.NewRequest(http.MethodPost, "/", nil)))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"disabled"`)
+	})
+}
+
+func TestAccountStatusHandler_Reactivate(t *testing.T) {
+	body := dto.AccountReactivateRequestDTO{Token: strings.Repeat("ab", 32)}
+
+	t.Run("invalid json", func(t *testing.T) {
+		h := NewAccountStatusHandler(&mockUserService{})
+		w := httptest.NewRecorder()
+		h.Reactivate(w, badJSONReq(t, http.MethodPost, "/"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		h := NewAccountStatusHandler(&mockUserService{})
+		w := httptest.NewRecorder()
+		h.Reactivate(w, jsonReq(t, http.MethodPost, "/", dto.AccountReactivateRequestDTO{}))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewAccountStatusHandler(&mockUserService{
+			reactivateSelfFn: func(string) (*service.UserServiceDataResult, error) {
+				return nil, apperror.NewValidation("invalid or expired reactivation token")
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Reactivate(w, jsonReq(t, http.MethodPost, "/", body))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewAccountStatusHandler(&mockUserService{
+			reactivateSelfFn: func(token string) (*service.UserServiceDataResult, error) {
+				assert.Equal(t, body.Token, token)
+				return &service.UserServiceDataResult{UserUUID: testUserUUID, Status: model.StatusActive}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Reactivate(w, jsonReq(t, http.MethodPost, "/", body))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"active"`)
+	})
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -41,15 +40,8 @@ func (h *APIHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 	// Parse bools safely
 	var isSystem *bool
-	// Parse status (comma-separated values)
-	var status []string
-	if statusParam := q.Get("status"); statusParam != "" {
-		status = strings.Split(statusParam, ",")
-		// Trim whitespace from each status
-		for i, s := range status {
-			status[i] = strings.TrimSpace(s)
-		}
-	}
+	// Parse status (comma-separated or repeated values)
+	status := queryValues(q, "status")
 	if v := q.Get("is_system"); v != "" {
 		parsed, err := strconv.ParseBool(v)
 		if err == nil {
@@ -184,7 +176,7 @@ func (h *APIHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	api, err := h.apiService.Create(r.Context(), tenant.TenantID, req.Name, req.DisplayName, req.Description, req.APIType, req.Status, false, req.ServiceUUID)
+	api, err := h.apiService.Create(r.Context(), tenant.TenantID, req.Name, req.DisplayName, req.Description, req.APIType, req.Status, false, req.ServiceUUID, req.Identifier)
 	if err != nil {
 		resp.HandleServiceError(w, r, "Failed to create API", err)
 		return
@@ -271,6 +263,69 @@ func (h *APIHandler) SetStatus(w http.ResponseWriter, r *http.Request) {
 	resp.Success(w, dtoRes, "API status updated successfully")
 }
 
+// Deprecate marks an API as deprecated with an optional sunset date.
+//
+// PUT /apis/{api_uuid}/deprecation
+func (h *APIHandler) Deprecate(w http.ResponseWriter, r *http.Request) {
+	// Get tenant from context
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	apiUUID, err := uuid.Parse(chi.URLParam(r, "api_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid API UUID")
+		return
+	}
+
+	var req dto.DeprecationRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	api, err := h.apiService.SetDeprecationByUUID(r.Context(), apiUUID, tenant.TenantID, req.SunsetAt, req.Notice)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to deprecate API", err)
+		return
+	}
+
+	resp.Success(w, toAPIResponseDTO(*api), "API deprecated successfully")
+}
+
+// Undeprecate removes the deprecation and sunset date from an API.
+//
+// DELETE /apis/{api_uuid}/deprecation
+func (h *APIHandler) Undeprecate(w http.ResponseWriter, r *http.Request) {
+	// Get tenant from context
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	apiUUID, err := uuid.Parse(chi.URLParam(r, "api_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid API UUID")
+		return
+	}
+
+	api, err := h.apiService.ClearDeprecationByUUID(r.Context(), apiUUID, tenant.TenantID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to remove API deprecation", err)
+		return
+	}
+
+	resp.Success(w, toAPIResponseDTO(*api), "API deprecation removed successfully")
+}
+
 // Delete API
 func (h *APIHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	// Get tenant from context
@@ -308,6 +363,7 @@ func toAPIResponseDTO(r service.APIServiceDataResult) dto.APIResponseDTO {
 		Identifier:  r.Identifier,
 		Status:      r.Status,
 		IsSystem:    r.IsSystem,
+		Deprecation: toDeprecationResponseDTO(r.Deprecation),
 		CreatedAt:   r.CreatedAt,
 		UpdatedAt:   r.UpdatedAt,
 	}
@@ -330,3 +386,17 @@ func toAPIResponseDTO(r service.APIServiceDataResult) dto.APIResponseDTO {
 
 	return result
 }
+
+// toDeprecationResponseDTO converts a deprecation result to its response DTO.
+func toDeprecationResponseDTO(d *service.DeprecationServiceDataResult) *dto.DeprecationResponseDTO {
+	if d == nil {
+		return nil
+	}
+	return &dto.DeprecationResponseDTO{
+		DeprecatedAt: d.DeprecatedAt,
+		SunsetAt:     d.SunsetAt,
+		Notice:       d.Notice,
+		UsageCount:   d.UsageCount,
+		LastUsedAt:   d.LastUsedAt,
+	}
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+
+	"github.com/maintainerd/auth/internal/rest/openapi"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+)
+
+// swaggerUIInit starts Swagger UI on the document served by Spec. It is
+// allowed by its hash in the page's Content-Security-Policy.
+const swaggerUIInit = `window.onload = function () {
+  window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui", deepLinking: true });
+};`
+
+var swaggerUIPage = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Maintainerd Auth API</title>
+  <link rel="stylesheet" href="{{.AssetsURL}}/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="{{.AssetsURL}}/swagger-ui-bundle.js"></script>
+  <script>{{.Init}}</script>
+</body>
+</html>
+`))
+
+// APIDocsHandler serves the OpenAPI document of the REST API and the Swagger
+// UI explorer reading it.
+type APIDocsHandler struct {
+	swaggerUIURL string
+	spec         atomic.Pointer[[]byte]
+}
+
+// NewAPIDocsHandler creates a new APIDocsHandler. swaggerUIURL is the base
+// URL of the swagger-ui-dist assets.
+func NewAPIDocsHandler(swaggerUIURL string) *APIDocsHandler {
+	return &APIDocsHandler{swaggerUIURL: swaggerUIURL}
+}
+
+// SetDocument sets the document served by Spec. The document is built from
+// the routers, so it is set once they are, before the servers start.
+func (h *APIDocsHandler) SetDocument(doc *openapi.Document) error {
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+	h.spec.Store(&spec)
+	return nil
+}
+
+// Spec returns the OpenAPI document.
+//
+// GET /openapi.json
+func (h *APIDocsHandler) Spec(w http.ResponseWriter, _ *http.Request) {
+	spec := h.spec.Load()
+	if spec == nil {
+		resp.Error(w, http.StatusServiceUnavailable, "API document is not available")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(*spec)
+}
+
+// SwaggerUI renders the Swagger UI explorer of the OpenAPI document.
+//
+// GET /docs
+func (h *APIDocsHandler) SwaggerUI(w http.ResponseWriter, _ *http.Request) {
+	var buf bytes.Buffer
+	err := swaggerUIPage.Execute(&buf, struct {
+		AssetsURL string
+		Init      template.JS
+	}{h.swaggerUIURL, template.JS(swaggerUIInit)})
+	if err != nil {
+		resp.Error(w, http.StatusInternalServerError, "Failed to render API explorer")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", h.swaggerUICSP())
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// swaggerUICSP relaxes the default Content-Security-Policy to load the
+// Swagger UI assets from their origin and run the inline init script.
+func (h *APIDocsHandler) swaggerUICSP() string {
+	origin := h.swaggerUIURL
+	if u, err := url.Parse(h.swaggerUIURL); err == nil {
+		origin = u.Scheme + "://" + u.Host
+	}
+	sum := sha256.Sum256([]byte(swaggerUIInit))
+	scriptHash := "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+
+	return "default-src 'self'; script-src 'self' " + origin + " " + scriptHash +
+		"; style-src 'self' 'unsafe-inline' " + origin +
+		"; img-src 'self' data: " + origin +
+		"; font-src 'self' " + origin +
+		"; connect-src 'self'; frame-ancestors 'none'; form-action 'self'"
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/rest/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIDocsHandler_Spec(t *testing.T) {
+	t.Run("not built", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewAPIDocsHandler("https://cdn.example.com/swagger-ui").Spec(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+
+	t.Run("built", func(t *testing.T) {
+		h := NewAPIDocsHandler("https://cdn.example.com/swagger-ui")
+		require.NoError(t, h.SetDocument(&openapi.Document{
+			OpenAPI: openapi.Version,
+			Info:    openapi.Info{Title: "Test", Version: "1.0.0"},
+			Paths:   map[string]*openapi.PathItem{},
+		}))
+
+		w := httptest.NewRecorder()
+		h.Spec(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var doc openapi.Document
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&doc))
+		assert.Equal(t, "3.1.0", doc.OpenAPI)
+		assert.Equal(t, "Test", doc.Info.Title)
+	})
+}
+
+func TestAPIDocsHandler_SwaggerUI(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewAPIDocsHandler("https://cdn.example.com/swagger-ui").SwaggerUI(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+
+	body := w.Body.String()
+	assert.Contains(t, body, `src="https://cdn.example.com/swagger-ui/swagger-ui-bundle.js"`)
+	assert.Contains(t, body, `href="https://cdn.example.com/swagger-ui/swagger-ui.css"`)
+	assert.Contains(t, body, swaggerUIInit)
+
+	sum := sha256.Sum256([]byte(swaggerUIInit))
+	csp := w.Header().Get("Content-Security-Policy")
+	assert.Contains(t, csp, "script-src 'self' https://cdn.example.com 'sha256-"+base64.StdEncoding.EncodeToString(sum[:])+"'")
+	assert.Contains(t, csp, "frame-ancestors 'none'")
+}
@@ -35,6 +35,10 @@ func (h *APIKeyHandler) Get(w http.ResponseWriter, r *http.Request) {
 		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
 		return
 	}
+	if requestingUser == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	// Parse query parameters
 	var reqParams dto.APIKeyGetRequestDTO
@@ -123,6 +127,10 @@ func (h *APIKeyHandler) GetByUUID(w http.ResponseWriter, r *http.Request) {
 		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
 		return
 	}
+	if requestingUser == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	apiKeyUUIDStr := chi.URLParam(r, "api_key_uuid")
 	apiKeyUUID, err := uuid.Parse(apiKeyUUIDStr)
@@ -228,6 +236,10 @@ func (h *APIKeyHandler) Update(w http.ResponseWriter, r *http.Request) {
 		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
 		return
 	}
+	if updaterUser == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	apiKeyUUIDStr := chi.URLParam(r, "api_key_uuid")
 	apiKeyUUID, err := uuid.Parse(apiKeyUUIDStr)
@@ -309,6 +321,10 @@ func (h *APIKeyHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
 		return
 	}
+	if deleterUser == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	apiKeyUUIDStr := chi.URLParam(r, "api_key_uuid")
 	apiKeyUUID, err := uuid.Parse(apiKeyUUIDStr)
@@ -350,6 +366,13 @@ func toAPIKeyResponseDTO(r service.APIKeyServiceDataResult) dto.APIKeyResponseDT
 
 // GetAPIs retrieves APIs assigned to API key with pagination.
 func (h *APIKeyHandler) GetAPIs(w http.ResponseWriter, r *http.Request) {
+	// Get tenant from context
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
 	apiKeyUUID, err := uuid.Parse(chi.URLParam(r, "api_key_uuid"))
 	if err != nil {
 		resp.Error(w, http.StatusBadRequest, "Invalid API key UUID")
@@ -387,7 +410,7 @@ func (h *APIKeyHandler) GetAPIs(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get API key APIs with pagination
-	result, err := h.apiKeyService.GetAPIKeyAPIs(r.Context(), apiKeyUUID, reqParams.Page, reqParams.Limit, reqParams.SortBy, reqParams.SortOrder)
+	result, err := h.apiKeyService.GetAPIKeyAPIs(r.Context(), apiKeyUUID, tenant.TenantID, reqParams.Page, reqParams.Limit, reqParams.SortBy, reqParams.SortOrder)
 	if err != nil {
 		resp.HandleServiceError(w, r, "Failed to get API key APIs", err)
 		return
@@ -425,6 +448,13 @@ func (h *APIKeyHandler) GetAPIs(w http.ResponseWriter, r *http.Request) {
 
 // AddAPIs adds APIs to API key.
 func (h *APIKeyHandler) AddAPIs(w http.ResponseWriter, r *http.Request) {
+	// Get tenant from context
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
 	apiKeyUUID, err := uuid.Parse(chi.URLParam(r, "api_key_uuid"))
 	if err != nil {
 		resp.Error(w, http.StatusBadRequest, "Invalid API key UUID")
@@ -443,7 +473,7 @@ func (h *APIKeyHandler) AddAPIs(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Add APIs to API key
-	err = h.apiKeyService.AddAPIKeyAPIs(r.Context(), apiKeyUUID, req.APIUUIDs)
+	err = h.apiKeyService.AddAPIKeyAPIs(r.Context(), apiKeyUUID, tenant.TenantID, req.APIUUIDs)
 	if err != nil {
 		resp.HandleServiceError(w, r, "Failed to add APIs to API key", err)
 		return
@@ -454,6 +484,13 @@ func (h *APIKeyHandler) AddAPIs(w http.ResponseWriter, r *http.Request) {
 
 // RemoveAPI removes an API from API key.
 func (h *APIKeyHandler) RemoveAPI(w http.ResponseWriter, r *http.Request) {
+	// Get tenant from context
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
 	apiKeyUUID, err := uuid.Parse(chi.URLParam(r, "api_key_uuid"))
 	if err != nil {
 		resp.Error(w, http.StatusBadRequest, "Invalid API key UUID")
@@ -467,7 +504,7 @@ func (h *APIKeyHandler) RemoveAPI(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Remove API from API key
-	err = h.apiKeyService.RemoveAPIKeyAPI(r.Context(), apiKeyUUID, apiUUID)
+	err = h.apiKeyService.RemoveAPIKeyAPI(r.Context(), apiKeyUUID, tenant.TenantID, apiUUID)
 	if err != nil {
 		resp.HandleServiceError(w, r, "Failed to remove API from API key", err)
 		return
@@ -478,6 +515,13 @@ func (h *APIKeyHandler) RemoveAPI(w http.ResponseWriter, r *http.Request) {
 
 // GetAPIPermissions retrieves permissions for a specific API assigned to API key.
 func (h *APIKeyHandler) GetAPIPermissions(w http.ResponseWriter, r *http.Request) {
+	// Get tenant from context
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
 	apiKeyUUID, err := uuid.Parse(chi.URLParam(r, "api_key_uuid"))
 	if err != nil {
 		resp.Error(w, http.StatusBadRequest, "Invalid API key UUID")
@@ -491,7 +535,7 @@ func (h *APIKeyHandler) GetAPIPermissions(w http.ResponseWriter, r *http.Request
 	}
 
 	// Get API key API permissions
-	permissions, err := h.apiKeyService.GetAPIKeyAPIPermissions(r.Context(), apiKeyUUID, apiUUID)
+	permissions, err := h.apiKeyService.GetAPIKeyAPIPermissions(r.Context(), apiKeyUUID, tenant.TenantID, apiUUID)
 	if err != nil {
 		resp.HandleServiceError(w, r, "Failed to get API key API permissions", err)
 		return
@@ -522,6 +566,13 @@ func (h *APIKeyHandler) GetAPIPermissions(w http.ResponseWriter, r *http.Request
 
 // AddAPIPermissions adds permissions to a specific API for API key.
 func (h *APIKeyHandler) AddAPIPermissions(w http.ResponseWriter, r *http.Request) {
+	// Get tenant from context
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
 	apiKeyUUID, err := uuid.Parse(chi.URLParam(r, "api_key_uuid"))
 	if err != nil {
 		resp.Error(w, http.StatusBadRequest, "Invalid API key UUID")
@@ -546,7 +597,7 @@ func (h *APIKeyHandler) AddAPIPermissions(w http.ResponseWriter, r *http.Request
 	}
 
 	// Add permissions to API key API
-	err = h.apiKeyService.AddAPIKeyAPIPermissions(r.Context(), apiKeyUUID, apiUUID, req.PermissionUUIDs)
+	err = h.apiKeyService.AddAPIKeyAPIPermissions(r.Context(), apiKeyUUID, tenant.TenantID, apiUUID, req.PermissionUUIDs)
 	if err != nil {
 		resp.HandleServiceError(w, r, "Failed to add permissions to API key API", err)
 		return
@@ -557,6 +608,13 @@ func (h *APIKeyHandler) AddAPIPermissions(w http.ResponseWriter, r *http.Request
 
 // RemoveAPIPermission removes a permission from a specific API for API key.
 func (h *APIKeyHandler) RemoveAPIPermission(w http.ResponseWriter, r *http.Request) {
+	// Get tenant from context
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
 	apiKeyUUID, err := uuid.Parse(chi.URLParam(r, "api_key_uuid"))
 	if err != nil {
 		resp.Error(w, http.StatusBadRequest, "Invalid API key UUID")
@@ -576,7 +634,7 @@ func (h *APIKeyHandler) RemoveAPIPermission(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Remove permission from API key API
-	err = h.apiKeyService.RemoveAPIKeyAPIPermission(r.Context(), apiKeyUUID, apiUUID, permissionUUID)
+	err = h.apiKeyService.RemoveAPIKeyAPIPermission(r.Context(), apiKeyUUID, tenant.TenantID, apiUUID, permissionUUID)
 	if err != nil {
 		resp.HandleServiceError(w, r, "Failed to remove permission from API key API", err)
 		return
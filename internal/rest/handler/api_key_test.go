@@ -22,6 +22,14 @@ func TestAPIKeyHandler_Get(t *testing.T) {
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 	})
 
+	t.Run("API key without user returns 401", func(t *testing.T) {
+		r := jsonReq(t, http.MethodGet, "/api-keys", nil)
+		r = withTenant(r) // API key requests carry a tenant but no user
+		w := httptest.NewRecorder()
+		NewAPIKeyHandler(&mockAPIKeyService{}).Get(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
 	t.Run("validation error returns 400", func(t *testing.T) {
 		// invalid status value triggers APIKeyGetRequestDTO.Validate failure
 		r := jsonReq(t, http.MethodGet, "/api-keys?status=invalid_status", nil)
@@ -83,6 +91,15 @@ func TestAPIKeyHandler_GetByUUID(t *testing.T) {
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 	})
 
+	t.Run("API key without user returns 401", func(t *testing.T) {
+		r := jsonReq(t, http.MethodGet, "/", nil)
+		r = withTenant(r) // API key requests carry a tenant but no user
+		r = withChiParam(r, "api_key_uuid", keyUUID.String())
+		w := httptest.NewRecorder()
+		NewAPIKeyHandler(&mockAPIKeyService{}).GetByUUID(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
 	t.Run("success", func(t *testing.T) {
 		svc := &mockAPIKeyService{
 			getByUUIDFn: func(id uuid.UUID, tid int64, u uuid.UUID) (*service.APIKeyServiceDataResult, error) {
@@ -247,6 +264,15 @@ func TestAPIKeyHandler_Update(t *testing.T) {
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 	})
 
+	t.Run("API key without user returns 401", func(t *testing.T) {
+		r := jsonReq(t, http.MethodPut, "/", map[string]any{"name": "n"})
+		r = withTenant(r) // API key requests carry a tenant but no user
+		r = withChiParam(r, "api_key_uuid", keyUUID.String())
+		w := httptest.NewRecorder()
+		NewAPIKeyHandler(&mockAPIKeyService{}).Update(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
 	t.Run("invalid uuid returns 400", func(t *testing.T) {
 		r := jsonReq(t, http.MethodPut, "/", map[string]any{"name": "n"})
 		r = withTenantAndUser(r)
@@ -383,6 +409,15 @@ func TestAPIKeyHandler_Delete(t *testing.T) {
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 	})
 
+	t.Run("API key without user returns 401", func(t *testing.T) {
+		r := jsonReq(t, http.MethodDelete, "/", nil)
+		r = withTenant(r) // API key requests carry a tenant but no user
+		r = withChiParam(r, "api_key_uuid", keyUUID.String())
+		w := httptest.NewRecorder()
+		NewAPIKeyHandler(&mockAPIKeyService{}).Delete(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
 	t.Run("invalid uuid returns 400", func(t *testing.T) {
 		r := jsonReq(t, http.MethodDelete, "/", nil)
 		r = withTenantAndUser(r)
@@ -426,7 +461,7 @@ func TestAPIKeyHandler_GetAPIs(t *testing.T) {
 	apiUUID := uuid.New()
 
 	t.Run("invalid uuid returns 400", func(t *testing.T) {
-		r := jsonReq(t, http.MethodGet, "/", nil)
+		r := withTenantAndUser(jsonReq(t, http.MethodGet, "/", nil))
 		r = withChiParam(r, "api_key_uuid", "bad")
 		w := httptest.NewRecorder()
 		NewAPIKeyHandler(&mockAPIKeyService{}).GetAPIs(w, r)
@@ -435,7 +470,7 @@ func TestAPIKeyHandler_GetAPIs(t *testing.T) {
 
 	t.Run("validation error returns 400", func(t *testing.T) {
 		// invalid sort_order triggers PaginationRequestDTO.Validate failure
-		r := jsonReq(t, http.MethodGet, "/?sort_order=invalid", nil)
+		r := withTenantAndUser(jsonReq(t, http.MethodGet, "/?sort_order=invalid", nil))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		w := httptest.NewRecorder()
 		NewAPIKeyHandler(&mockAPIKeyService{}).GetAPIs(w, r)
@@ -444,11 +479,11 @@ func TestAPIKeyHandler_GetAPIs(t *testing.T) {
 
 	t.Run("service error returns 500", func(t *testing.T) {
 		svc := &mockAPIKeyService{
-			getAPIKeyAPIsFn: func(id uuid.UUID, pg, lim int, sb, so string) (*service.APIKeyAPIServicePaginatedResult, error) {
+			getAPIKeyAPIsFn: func(id uuid.UUID, _ int64, pg, lim int, sb, so string) (*service.APIKeyAPIServicePaginatedResult, error) {
 				return nil, errors.New("db error")
 			},
 		}
-		r := jsonReq(t, http.MethodGet, "/", nil)
+		r := withTenantAndUser(jsonReq(t, http.MethodGet, "/", nil))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		w := httptest.NewRecorder()
 		NewAPIKeyHandler(svc).GetAPIs(w, r)
@@ -458,7 +493,7 @@ func TestAPIKeyHandler_GetAPIs(t *testing.T) {
 	t.Run("success with rows", func(t *testing.T) {
 		// Covers the loop body in GetAPIs that maps rows to APIResponseDTO
 		svc := &mockAPIKeyService{
-			getAPIKeyAPIsFn: func(id uuid.UUID, pg, lim int, sb, so string) (*service.APIKeyAPIServicePaginatedResult, error) {
+			getAPIKeyAPIsFn: func(id uuid.UUID, _ int64, pg, lim int, sb, so string) (*service.APIKeyAPIServicePaginatedResult, error) {
 				return &service.APIKeyAPIServicePaginatedResult{
 					Data: []service.APIKeyAPIServiceDataResult{
 						{Api: service.APIServiceDataResult{APIUUID: apiUUID, Name: "api1"}},
@@ -467,7 +502,7 @@ func TestAPIKeyHandler_GetAPIs(t *testing.T) {
 				}, nil
 			},
 		}
-		r := jsonReq(t, http.MethodGet, "/", nil)
+		r := withTenantAndUser(jsonReq(t, http.MethodGet, "/", nil))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		w := httptest.NewRecorder()
 		NewAPIKeyHandler(svc).GetAPIs(w, r)
@@ -475,7 +510,7 @@ func TestAPIKeyHandler_GetAPIs(t *testing.T) {
 	})
 
 	t.Run("success empty", func(t *testing.T) {
-		r := jsonReq(t, http.MethodGet, "/", nil)
+		r := withTenantAndUser(jsonReq(t, http.MethodGet, "/", nil))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		w := httptest.NewRecorder()
 		NewAPIKeyHandler(&mockAPIKeyService{}).GetAPIs(w, r)
@@ -488,7 +523,7 @@ func TestAPIKeyHandler_AddAPIs(t *testing.T) {
 	apiUUID := uuid.New()
 
 	t.Run("invalid uuid returns 400", func(t *testing.T) {
-		r := jsonReq(t, http.MethodPost, "/", map[string]any{"api_uuids": []string{apiUUID.String()}})
+		r := withTenantAndUser(jsonReq(t, http.MethodPost, "/", map[string]any{"api_uuids": []string{apiUUID.String()}}))
 		r = withChiParam(r, "api_key_uuid", "bad")
 		w := httptest.NewRecorder()
 		NewAPIKeyHandler(&mockAPIKeyService{}).AddAPIs(w, r)
@@ -496,7 +531,7 @@ func TestAPIKeyHandler_AddAPIs(t *testing.T) {
 	})
 
 	t.Run("bad json returns 400", func(t *testing.T) {
-		r := badJSONReq(t, http.MethodPost, "/")
+		r := withTenantAndUser(badJSONReq(t, http.MethodPost, "/"))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		w := httptest.NewRecorder()
 		NewAPIKeyHandler(&mockAPIKeyService{}).AddAPIs(w, r)
@@ -505,7 +540,7 @@ func TestAPIKeyHandler_AddAPIs(t *testing.T) {
 
 	t.Run("validation error returns 400", func(t *testing.T) {
 		// empty api_uuids fails validation
-		r := jsonReq(t, http.MethodPost, "/", map[string]any{"api_uuids": []string{}})
+		r := withTenantAndUser(jsonReq(t, http.MethodPost, "/", map[string]any{"api_uuids": []string{}}))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		w := httptest.NewRecorder()
 		NewAPIKeyHandler(&mockAPIKeyService{}).AddAPIs(w, r)
@@ -514,11 +549,11 @@ func TestAPIKeyHandler_AddAPIs(t *testing.T) {
 
 	t.Run("service error returns 500", func(t *testing.T) {
 		svc := &mockAPIKeyService{
-			addAPIKeyAPIsFn: func(id uuid.UUID, apis []uuid.UUID) error {
+			addAPIKeyAPIsFn: func(id uuid.UUID, _ int64, apis []uuid.UUID) error {
 				return errors.New("db error")
 			},
 		}
-		r := jsonReq(t, http.MethodPost, "/", map[string]any{"api_uuids": []string{apiUUID.String()}})
+		r := withTenantAndUser(jsonReq(t, http.MethodPost, "/", map[string]any{"api_uuids": []string{apiUUID.String()}}))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		w := httptest.NewRecorder()
 		NewAPIKeyHandler(svc).AddAPIs(w, r)
@@ -526,7 +561,7 @@ func TestAPIKeyHandler_AddAPIs(t *testing.T) {
 	})
 
 	t.Run("success", func(t *testing.T) {
-		r := jsonReq(t, http.MethodPost, "/", map[string]any{"api_uuids": []string{apiUUID.String()}})
+		r := withTenantAndUser(jsonReq(t, http.MethodPost, "/", map[string]any{"api_uuids": []string{apiUUID.String()}}))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		w := httptest.NewRecorder()
 		NewAPIKeyHandler(&mockAPIKeyService{}).AddAPIs(w, r)
@@ -539,7 +574,7 @@ func TestAPIKeyHandler_RemoveAPI(t *testing.T) {
 	apiUUID := uuid.New()
 
 	t.Run("invalid api_key_uuid returns 400", func(t *testing.T) {
-		r := jsonReq(t, http.MethodDelete, "/", nil)
+		r := withTenantAndUser(jsonReq(t, http.MethodDelete, "/", nil))
 		r = withChiParam(r, "api_key_uuid", "bad")
 		r = withChiParam(r, "api_uuid", apiUUID.String())
 		w := httptest.NewRecorder()
@@ -548,7 +583,7 @@ func TestAPIKeyHandler_RemoveAPI(t *testing.T) {
 	})
 
 	t.Run("invalid api_uuid returns 400", func(t *testing.T) {
-		r := jsonReq(t, http.MethodDelete, "/", nil)
+		r := withTenantAndUser(jsonReq(t, http.MethodDelete, "/", nil))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		r = withChiParam(r, "api_uuid", "bad")
 		w := httptest.NewRecorder()
@@ -558,11 +593,11 @@ func TestAPIKeyHandler_RemoveAPI(t *testing.T) {
 
 	t.Run("service error returns 500", func(t *testing.T) {
 		svc := &mockAPIKeyService{
-			removeAPIKeyAPIFn: func(id, api uuid.UUID) error {
+			removeAPIKeyAPIFn: func(id uuid.UUID, _ int64, api uuid.UUID) error {
 				return errors.New("db error")
 			},
 		}
-		r := jsonReq(t, http.MethodDelete, "/", nil)
+		r := withTenantAndUser(jsonReq(t, http.MethodDelete, "/", nil))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		r = withChiParam(r, "api_uuid", apiUUID.String())
 		w := httptest.NewRecorder()
@@ -571,7 +606,7 @@ func TestAPIKeyHandler_RemoveAPI(t *testing.T) {
 	})
 
 	t.Run("success", func(t *testing.T) {
-		r := jsonReq(t, http.MethodDelete, "/", nil)
+		r := withTenantAndUser(jsonReq(t, http.MethodDelete, "/", nil))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		r = withChiParam(r, "api_uuid", apiUUID.String())
 		w := httptest.NewRecorder()
@@ -585,7 +620,7 @@ func TestAPIKeyHandler_GetAPIPermissions(t *testing.T) {
 	apiUUID := uuid.New()
 
 	t.Run("invalid api_key_uuid returns 400", func(t *testing.T) {
-		r := jsonReq(t, http.MethodGet, "/", nil)
+		r := withTenantAndUser(jsonReq(t, http.MethodGet, "/", nil))
 		r = withChiParam(r, "api_key_uuid", "bad")
 		r = withChiParam(r, "api_uuid", apiUUID.String())
 		w := httptest.NewRecorder()
@@ -594,7 +629,7 @@ func TestAPIKeyHandler_GetAPIPermissions(t *testing.T) {
 	})
 
 	t.Run("invalid api_uuid returns 400", func(t *testing.T) {
-		r := jsonReq(t, http.MethodGet, "/", nil)
+		r := withTenantAndUser(jsonReq(t, http.MethodGet, "/", nil))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		r = withChiParam(r, "api_uuid", "bad")
 		w := httptest.NewRecorder()
@@ -604,11 +639,11 @@ func TestAPIKeyHandler_GetAPIPermissions(t *testing.T) {
 
 	t.Run("service error returns 500", func(t *testing.T) {
 		svc := &mockAPIKeyService{
-			getAPIKeyAPIPermsFn: func(id, api uuid.UUID) ([]service.PermissionServiceDataResult, error) {
+			getAPIKeyAPIPermsFn: func(id uuid.UUID, _ int64, api uuid.UUID) ([]service.PermissionServiceDataResult, error) {
 				return nil, errors.New("db error")
 			},
 		}
-		r := jsonReq(t, http.MethodGet, "/", nil)
+		r := withTenantAndUser(jsonReq(t, http.MethodGet, "/", nil))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		r = withChiParam(r, "api_uuid", apiUUID.String())
 		w := httptest.NewRecorder()
@@ -618,11 +653,11 @@ func TestAPIKeyHandler_GetAPIPermissions(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		svc := &mockAPIKeyService{
-			getAPIKeyAPIPermsFn: func(id, api uuid.UUID) ([]service.PermissionServiceDataResult, error) {
+			getAPIKeyAPIPermsFn: func(id uuid.UUID, _ int64, api uuid.UUID) ([]service.PermissionServiceDataResult, error) {
 				return []service.PermissionServiceDataResult{{Name: "read"}}, nil
 			},
 		}
-		r := jsonReq(t, http.MethodGet, "/", nil)
+		r := withTenantAndUser(jsonReq(t, http.MethodGet, "/", nil))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		r = withChiParam(r, "api_uuid", apiUUID.String())
 		w := httptest.NewRecorder()
@@ -637,7 +672,7 @@ func TestAPIKeyHandler_AddAPIPermissions(t *testing.T) {
 	permUUID := uuid.New()
 
 	t.Run("invalid api_key_uuid returns 400", func(t *testing.T) {
-		r := jsonReq(t, http.MethodPost, "/", map[string]any{"permission_uuids": []string{permUUID.String()}})
+		r := withTenantAndUser(jsonReq(t, http.MethodPost, "/", map[string]any{"permission_uuids": []string{permUUID.String()}}))
 		r = withChiParam(r, "api_key_uuid", "bad")
 		r = withChiParam(r, "api_uuid", apiUUID.String())
 		w := httptest.NewRecorder()
@@ -646,7 +681,7 @@ func TestAPIKeyHandler_AddAPIPermissions(t *testing.T) {
 	})
 
 	t.Run("invalid api_uuid returns 400", func(t *testing.T) {
-		r := jsonReq(t, http.MethodPost, "/", map[string]any{"permission_uuids": []string{permUUID.String()}})
+		r := withTenantAndUser(jsonReq(t, http.MethodPost, "/", map[string]any{"permission_uuids": []string{permUUID.String()}}))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		r = withChiParam(r, "api_uuid", "bad")
 		w := httptest.NewRecorder()
@@ -655,7 +690,7 @@ func TestAPIKeyHandler_AddAPIPermissions(t *testing.T) {
 	})
 
 	t.Run("bad json returns 400", func(t *testing.T) {
-		r := badJSONReq(t, http.MethodPost, "/")
+		r := withTenantAndUser(badJSONReq(t, http.MethodPost, "/"))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		r = withChiParam(r, "api_uuid", apiUUID.String())
 		w := httptest.NewRecorder()
@@ -664,7 +699,7 @@ func TestAPIKeyHandler_AddAPIPermissions(t *testing.T) {
 	})
 
 	t.Run("validation error returns 400", func(t *testing.T) {
-		r := jsonReq(t, http.MethodPost, "/", map[string]any{"permission_uuids": []string{}})
+		r := withTenantAndUser(jsonReq(t, http.MethodPost, "/", map[string]any{"permission_uuids": []string{}}))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		r = withChiParam(r, "api_uuid", apiUUID.String())
 		w := httptest.NewRecorder()
@@ -674,11 +709,11 @@ func TestAPIKeyHandler_AddAPIPermissions(t *testing.T) {
 
 	t.Run("service error returns 500", func(t *testing.T) {
 		svc := &mockAPIKeyService{
-			addAPIKeyAPIPermsFn: func(id, api uuid.UUID, perms []uuid.UUID) error {
+			addAPIKeyAPIPermsFn: func(id uuid.UUID, _ int64, api uuid.UUID, perms []uuid.UUID) error {
 				return errors.New("db error")
 			},
 		}
-		r := jsonReq(t, http.MethodPost, "/", map[string]any{"permission_uuids": []string{permUUID.String()}})
+		r := withTenantAndUser(jsonReq(t, http.MethodPost, "/", map[string]any{"permission_uuids": []string{permUUID.String()}}))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		r = withChiParam(r, "api_uuid", apiUUID.String())
 		w := httptest.NewRecorder()
@@ -687,7 +722,7 @@ func TestAPIKeyHandler_AddAPIPermissions(t *testing.T) {
 	})
 
 	t.Run("success", func(t *testing.T) {
-		r := jsonReq(t, http.MethodPost, "/", map[string]any{"permission_uuids": []string{permUUID.String()}})
+		r := withTenantAndUser(jsonReq(t, http.MethodPost, "/", map[string]any{"permission_uuids": []string{permUUID.String()}}))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		r = withChiParam(r, "api_uuid", apiUUID.String())
 		w := httptest.NewRecorder()
@@ -702,7 +737,7 @@ func TestAPIKeyHandler_RemoveAPIPermission(t *testing.T) {
 	permUUID := uuid.New()
 
 	t.Run("invalid api_key_uuid returns 400", func(t *testing.T) {
-		r := jsonReq(t, http.MethodDelete, "/", nil)
+		r := withTenantAndUser(jsonReq(t, http.MethodDelete, "/", nil))
 		r = withChiParam(r, "api_key_uuid", "bad")
 		r = withChiParam(r, "api_uuid", apiUUID.String())
 		r = withChiParam(r, "permission_uuid", permUUID.String())
@@ -712,7 +747,7 @@ func TestAPIKeyHandler_RemoveAPIPermission(t *testing.T) {
 	})
 
 	t.Run("invalid api_uuid returns 400", func(t *testing.T) {
-		r := jsonReq(t, http.MethodDelete, "/", nil)
+		r := withTenantAndUser(jsonReq(t, http.MethodDelete, "/", nil))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		r = withChiParam(r, "api_uuid", "bad")
 		r = withChiParam(r, "permission_uuid", permUUID.String())
@@ -722,7 +757,7 @@ func TestAPIKeyHandler_RemoveAPIPermission(t *testing.T) {
 	})
 
 	t.Run("invalid permission_uuid returns 400", func(t *testing.T) {
-		r := jsonReq(t, http.MethodDelete, "/", nil)
+		r := withTenantAndUser(jsonReq(t, http.MethodDelete, "/", nil))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		r = withChiParam(r, "api_uuid", apiUUID.String())
 		r = withChiParam(r, "permission_uuid", "bad")
@@ -733,11 +768,11 @@ func TestAPIKeyHandler_RemoveAPIPermission(t *testing.T) {
 
 	t.Run("service error returns 500", func(t *testing.T) {
 		svc := &mockAPIKeyService{
-			removeAPIKeyAPIPermFn: func(id, api, perm uuid.UUID) error {
+			removeAPIKeyAPIPermFn: func(id uuid.UUID, _ int64, api, perm uuid.UUID) error {
 				return errors.New("db error")
 			},
 		}
-		r := jsonReq(t, http.MethodDelete, "/", nil)
+		r := withTenantAndUser(jsonReq(t, http.MethodDelete, "/", nil))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		r = withChiParam(r, "api_uuid", apiUUID.String())
 		r = withChiParam(r, "permission_uuid", permUUID.String())
@@ -747,7 +782,7 @@ func TestAPIKeyHandler_RemoveAPIPermission(t *testing.T) {
 	})
 
 	t.Run("success", func(t *testing.T) {
-		r := jsonReq(t, http.MethodDelete, "/", nil)
+		r := withTenantAndUser(jsonReq(t, http.MethodDelete, "/", nil))
 		r = withChiParam(r, "api_key_uuid", keyUUID.String())
 		r = withChiParam(r, "api_uuid", apiUUID.String())
 		r = withChiParam(r, "permission_uuid", permUUID.String())
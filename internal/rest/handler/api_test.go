@@ -4,10 +4,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/service"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // ---------------------------------------------------------------------------
@@ -207,7 +209,7 @@ func TestAPIHandler_Create_ValidationError(t *testing.T) {
 
 func TestAPIHandler_Create_ServiceError(t *testing.T) {
 	svc := &mockAPIService{
-		createFn: func(tid int64, n, dn, desc, tp, s string, isSys bool, svcUUID string) (*service.APIServiceDataResult, error) {
+		createFn: func(tid int64, n, dn, desc, tp, s string, isSys bool, svcUUID, identifier string) (*service.APIServiceDataResult, error) {
 			return nil, assert.AnError
 		},
 	}
@@ -223,14 +225,16 @@ func TestAPIHandler_Create_ServiceError(t *testing.T) {
 
 func TestAPIHandler_Create_Success(t *testing.T) {
 	svc := &mockAPIService{
-		createFn: func(tid int64, n, dn, desc, tp, s string, isSys bool, svcUUID string) (*service.APIServiceDataResult, error) {
-			return &service.APIServiceDataResult{Name: n}, nil
+		createFn: func(tid int64, n, dn, desc, tp, s string, isSys bool, svcUUID, identifier string) (*service.APIServiceDataResult, error) {
+			assert.Equal(t, "https://billing.example.com", identifier)
+			return &service.APIServiceDataResult{Name: n, Identifier: identifier}, nil
 		},
 	}
 	h := NewAPIHandler(svc)
 	r := withTenant(jsonReq(t, http.MethodPost, "/apis", map[string]string{
 		"name": "api1", "display_name": "API One", "description": "A test API for testing",
 		"api_type": "rest", "status": "active", "service_id": testResourceUUID.String(),
+		"identifier": "https://billing.example.com",
 	}))
 	w := httptest.NewRecorder()
 	h.Create(w, r)
@@ -367,6 +371,105 @@ func TestAPIHandler_SetStatus_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+// ---------------------------------------------------------------------------
+// Deprecate / Undeprecate
+// ---------------------------------------------------------------------------
+
+func TestAPIHandler_Deprecate(t *testing.T) {
+	path := "/apis/" + testResourceUUID.String() + "/deprecation"
+	body := map[string]any{"sunset_at": "2030-01-01T00:00:00Z", "notice": "use v2"}
+
+	t.Run("no tenant returns 401", func(t *testing.T) {
+		r := withChiParam(jsonReq(t, http.MethodPut, path, body), "api_uuid", testResourceUUID.String())
+		w := httptest.NewRecorder()
+		NewAPIHandler(&mockAPIService{}).Deprecate(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+	t.Run("invalid uuid returns 400", func(t *testing.T) {
+		r := withTenant(withChiParam(jsonReq(t, http.MethodPut, path, body), "api_uuid", "bad"))
+		w := httptest.NewRecorder()
+		NewAPIHandler(&mockAPIService{}).Deprecate(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("bad json returns 400", func(t *testing.T) {
+		r := withTenant(withChiParam(badJSONReq(t, http.MethodPut, path), "api_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewAPIHandler(&mockAPIService{}).Deprecate(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("validation error returns 400", func(t *testing.T) {
+		r := withTenant(withChiParam(jsonReq(t, http.MethodPut, path, map[string]any{"notice": ""}), "api_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewAPIHandler(&mockAPIService{}).Deprecate(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("service error", func(t *testing.T) {
+		svc := &mockAPIService{setDeprecationFn: func(uuid.UUID, int64, *time.Time, *string) (*service.APIServiceDataResult, error) {
+			return nil, errValidation
+		}}
+		r := withTenant(withChiParam(jsonReq(t, http.MethodPut, path, body), "api_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewAPIHandler(svc).Deprecate(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("success", func(t *testing.T) {
+		var gotSunset *time.Time
+		var gotNotice *string
+		svc := &mockAPIService{setDeprecationFn: func(_ uuid.UUID, _ int64, sunsetAt *time.Time, notice *string) (*service.APIServiceDataResult, error) {
+			gotSunset, gotNotice = sunsetAt, notice
+			return &service.APIServiceDataResult{
+				Name:        "api1",
+				Deprecation: &service.DeprecationServiceDataResult{DeprecatedAt: time.Now(), SunsetAt: sunsetAt, Notice: notice},
+			}, nil
+		}}
+		r := withTenant(withChiParam(jsonReq(t, http.MethodPut, path, body), "api_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewAPIHandler(svc).Deprecate(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.NotNil(t, gotSunset)
+		assert.Equal(t, 2030, gotSunset.Year())
+		require.NotNil(t, gotNotice)
+		assert.Equal(t, "use v2", *gotNotice)
+		assert.Contains(t, w.Body.String(), `"deprecation"`)
+	})
+}
+
+func TestAPIHandler_Undeprecate(t *testing.T) {
+	path := "/apis/" + testResourceUUID.String() + "/deprecation"
+
+	t.Run("no tenant returns 401", func(t *testing.T) {
+		r := withChiParam(httptest.NewRequest(http.MethodDelete, path, nil), "api_uuid", testResourceUUID.String())
+		w := httptest.NewRecorder()
+		NewAPIHandler(&mockAPIService{}).Undeprecate(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+	t.Run("invalid uuid returns 400", func(t *testing.T) {
+		r := withTenant(withChiParam(httptest.NewRequest(http.MethodDelete, path, nil), "api_uuid", "bad"))
+		w := httptest.NewRecorder()
+		NewAPIHandler(&mockAPIService{}).Undeprecate(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("service error", func(t *testing.T) {
+		svc := &mockAPIService{clearDeprecationFn: func(uuid.UUID, int64) (*service.APIServiceDataResult, error) {
+			return nil, errNotFound
+		}}
+		r := withTenant(withChiParam(httptest.NewRequest(http.MethodDelete, path, nil), "api_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewAPIHandler(svc).Undeprecate(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+	t.Run("success", func(t *testing.T) {
+		svc := &mockAPIService{clearDeprecationFn: func(uuid.UUID, int64) (*service.APIServiceDataResult, error) {
+			return &service.APIServiceDataResult{Name: "api1"}, nil
+		}}
+		r := withTenant(withChiParam(httptest.NewRequest(http.MethodDelete, path, nil), "api_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewAPIHandler(svc).Undeprecate(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), `"deprecation"`)
+	})
+}
+
 // ---------------------------------------------------------------------------
 // Delete
 // ---------------------------------------------------------------------------
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AuditArchiveHandler handles the admin endpoints for auth event archive
+// runs.
+type AuditArchiveHandler struct {
+	auditArchiveService service.AuditArchiveService
+}
+
+// NewAuditArchiveHandler creates a new AuditArchiveHandler.
+func NewAuditArchiveHandler(auditArchiveService service.AuditArchiveService) *AuditArchiveHandler {
+	return &AuditArchiveHandler{auditArchiveService: auditArchiveService}
+}
+
+// GetAll returns a paginated list of the tenant's archive runs, newest
+// first.
+//
+// GET /auth-events/archive-runs
+func (h *AuditArchiveHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+
+	pagination := dto.PaginationRequestDTO{Page: page, Limit: limit}
+	if err := pagination.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.auditArchiveService.ListRuns(r.Context(), tenant.TenantID, pagination.Page, pagination.Limit)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get archive runs", err)
+		return
+	}
+
+	rows := make([]dto.AuditArchiveRunResponseDTO, len(result.Data))
+	for i, run := range result.Data {
+		rows[i] = toAuditArchiveRunResponseDTO(run)
+	}
+
+	resp.Success(w, dto.PaginatedResponseDTO[dto.AuditArchiveRunResponseDTO]{
+		Rows:       rows,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}, "Archive runs retrieved successfully")
+}
+
+// Get returns a single archive run with a short-lived download URL for its
+// archive.
+//
+// GET /auth-events/archive-runs/{audit_archive_run_uuid}
+func (h *AuditArchiveHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	runUUID, err := uuid.Parse(chi.URLParam(r, "audit_archive_run_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid archive run UUID")
+		return
+	}
+
+	run, err := h.auditArchiveService.GetRun(r.Context(), tenant.TenantID, runUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get archive run", err)
+		return
+	}
+
+	resp.Success(w, toAuditArchiveRunResponseDTO(*run), "Archive run retrieved successfully")
+}
+
+// Run archives and removes the tenant's expired auth events now. A run that
+// fails is still returned, with its error.
+//
+// POST /auth-events/archive-runs
+func (h *AuditArchiveHandler) Run(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	run, err := h.auditArchiveService.Run(r.Context(), tenant.TenantID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to run archive", err)
+		return
+	}
+
+	resp.Created(w, toAuditArchiveRunResponseDTO(*run), "Archive run finished")
+}
+
+func toAuditArchiveRunResponseDTO(run service.AuditArchiveRunServiceDataResult) dto.AuditArchiveRunResponseDTO {
+	return dto.AuditArchiveRunResponseDTO{
+		AuditArchiveRunID: run.AuditArchiveRunUUID.String(),
+		Trigger:           run.Trigger,
+		Status:            run.Status,
+		Cutoff:            run.Cutoff,
+		EventCount:        run.EventCount,
+		ObjectKey:         run.ObjectKey,
+		SizeBytes:         run.SizeBytes,
+		Error:             run.Error,
+		DownloadURL:       run.DownloadURL,
+		CompletedAt:       run.CompletedAt,
+		CreatedAt:         run.CreatedAt,
+	}
+}
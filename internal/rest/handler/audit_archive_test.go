@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditArchiveHandler_GetAll(t *testing.T) {
+	t.Run("no tenant → 401", func(t *testing.T) {
+		h := NewAuditArchiveHandler(&mockAuditArchiveService{})
+		w := httptest.NewRecorder()
+		h.GetAll(w, httptest.NewRequest(http.MethodGet, "/auth-events/archive-runs?page=1&limit=10", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("missing pagination → 400", func(t *testing.T) {
+		h := NewAuditArchiveHandler(&mockAuditArchiveService{})
+		w := httptest.NewRecorder()
+		h.GetAll(w, withTenant(httptest.NewRequest(http.MethodGet, "/auth-events/archive-runs", nil)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		svc := &mockAuditArchiveService{
+			listRunsFn: func(_ context.Context, tid int64, page, limit int) (*repository.PaginationResult[service.AuditArchiveRunServiceDataResult], error) {
+				assert.Equal(t, tenantID, tid)
+				return &repository.PaginationResult[service.AuditArchiveRunServiceDataResult]{
+					Data:  []service.AuditArchiveRunServiceDataResult{{AuditArchiveRunUUID: uuid.New(), Status: model.AuditArchiveStatusCompleted}},
+					Total: 1, Page: page, Limit: limit, TotalPages: 1,
+				}, nil
+			},
+		}
+		h := NewAuditArchiveHandler(svc)
+		w := httptest.NewRecorder()
+		h.GetAll(w, withTenant(httptest.NewRequest(http.MethodGet, "/auth-events/archive-runs?page=1&limit=10", nil)))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"completed"`)
+	})
+}
+
+func TestAuditArchiveHandler_Get(t *testing.T) {
+	runUUID := uuid.New()
+
+	t.Run("invalid uuid → 400", func(t *testing.T) {
+		h := NewAuditArchiveHandler(&mockAuditArchiveService{})
+		w := httptest.NewRecorder()
+		h.Get(w, withChiParam(withTenant(httptest.NewRequest(http.MethodGet, "/", nil)), "audit_archive_run_uuid", "x"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("not found → 404", func(t *testing.T) {
+		svc := &mockAuditArchiveService{
+			getRunFn: func(context.Context, int64, uuid.UUID) (*service.AuditArchiveRunServiceDataResult, error) {
+				return nil, apperror.NewNotFound("archive run not found")
+			},
+		}
+		h := NewAuditArchiveHandler(svc)
+		w := httptest.NewRecorder()
+		h.Get(w, withChiParam(withTenant(httptest.NewRequest(http.MethodGet, "/", nil)), "audit_archive_run_uuid", runUUID.String()))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("success includes the download URL", func(t *testing.T) {
+		url := "https://media.example.com/archive?sig=x"
+		svc := &mockAuditArchiveService{
+			getRunFn: func(_ context.Context, _ int64, id uuid.UUID) (*service.AuditArchiveRunServiceDataResult, error) {
+				return &service.AuditArchiveRunServiceDataResult{AuditArchiveRunUUID: id, DownloadURL: &url}, nil
+			},
+		}
+		h := NewAuditArchiveHandler(svc)
+		w := httptest.NewRecorder()
+		h.Get(w, withChiParam(withTenant(httptest.NewRequest(http.MethodGet, "/", nil)), "audit_archive_run_uuid", runUUID.String()))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var body struct {
+			Data map[string]any `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, runUUID.String(), body.Data["audit_archive_run_id"])
+		assert.Equal(t, url, body.Data["download_url"])
+	})
+}
+
+func TestAuditArchiveHandler_Run(t *testing.T) {
+	t.Run("run in progress → 409", func(t *testing.T) {
+		svc := &mockAuditArchiveService{
+			runFn: func(context.Context, int64) (*service.AuditArchiveRunServiceDataResult, error) {
+				return nil, apperror.NewConflict("an archive run is already in progress")
+			},
+		}
+		h := NewAuditArchiveHandler(svc)
+		w := httptest.NewRecorder()
+		h.Run(w, withTenant(httptest.NewRequest(http.MethodPost, "/auth-events/archive-runs", nil)))
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("success → 201", func(t *testing.T) {
+		svc := &mockAuditArchiveService{
+			runFn: func(_ context.Context, tid int64) (*service.AuditArchiveRunServiceDataResult, error) {
+				assert.Equal(t, tenantID, tid)
+				return &service.AuditArchiveRunServiceDataResult{Trigger: model.AuditArchiveTriggerManual, Status: model.AuditArchiveStatusCompleted, EventCount: 12}, nil
+			},
+		}
+		h := NewAuditArchiveHandler(svc)
+		w := httptest.NewRecorder()
+		h.Run(w, withTenant(httptest.NewRequest(http.MethodPost, "/auth-events/archive-runs", nil)))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"event_count":12`)
+	})
+}
@@ -53,6 +53,7 @@ func (h *AuthEventHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 			SortBy:    q.Get("sort_by"),
 			SortOrder: q.Get("sort_order"),
 		},
+		Cursor: cursorPagination(q),
 	}
 
 	if err := filter.Validate(); err != nil {
@@ -83,6 +84,14 @@ func (h *AuthEventHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if filter.Cursor != nil {
+		repoFilter.Limit = filter.Cursor.Limit
+		repoFilter.SortOrder = filter.Cursor.SortOrder
+		repoFilter.SortBy = ""
+		h.getAllByCursor(w, r, repoFilter, filter.Cursor.Cursor)
+		return
+	}
+
 	result, err := h.authEventService.FindPaginated(r.Context(), repoFilter)
 	if err != nil {
 		resp.HandleServiceError(w, r, "Failed to get auth events", err)
@@ -100,6 +109,22 @@ func (h *AuthEventHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	resp.Success(w, response, "Auth events retrieved successfully")
 }
 
+// getAllByCursor answers GetAll when it pages with a cursor.
+func (h *AuthEventHandler) getAllByCursor(w http.ResponseWriter, r *http.Request, filter repository.AuthEventRepositoryGetFilter, cursor string) {
+	result, err := h.authEventService.FindByCursor(r.Context(), filter, cursor)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get auth events", err)
+		return
+	}
+
+	resp.Success(w, dto.CursorPaginatedResponseDTO[dto.AuthEventResponseDTO]{
+		Rows:       toAuthEventResponseDTOList(result.Data),
+		Limit:      result.Limit,
+		NextCursor: result.NextCursor,
+		HasMore:    result.HasMore,
+	}, "Auth events retrieved successfully")
+}
+
 // Get returns a single auth event by UUID for the authenticated tenant.
 //
 // GET /auth-events/{auth_event_uuid}
@@ -161,21 +186,25 @@ func toAuthEventResponseDTO(e service.AuthEventServiceDataResult) dto.AuthEventR
 	}
 
 	return dto.AuthEventResponseDTO{
-		AuthEventID:  e.AuthEventUUID.String(),
-		TenantID:     e.TenantID,
-		ActorUserID:  e.ActorUserID,
-		TargetUserID: e.TargetUserID,
-		IPAddress:    e.IPAddress,
-		UserAgent:    e.UserAgent,
-		Category:     e.Category,
-		EventType:    e.EventType,
-		Severity:     e.Severity,
-		Result:       e.Result,
-		Description:  e.Description,
-		ErrorReason:  e.ErrorReason,
-		TraceID:      e.TraceID,
-		Metadata:     metadata,
-		CreatedAt:    e.CreatedAt,
+		AuthEventID:    e.AuthEventUUID.String(),
+		TenantID:       e.TenantID,
+		ActorUserID:    e.ActorUserID,
+		TargetUserID:   e.TargetUserID,
+		IPAddress:      e.IPAddress,
+		UserAgent:      e.UserAgent,
+		Country:        e.Country,
+		City:           e.City,
+		ASN:            e.ASN,
+		ASOrganization: e.ASOrganization,
+		Category:       e.Category,
+		EventType:      e.EventType,
+		Severity:       e.Severity,
+		Result:         e.Result,
+		Description:    e.Description,
+		ErrorReason:    e.ErrorReason,
+		TraceID:        e.TraceID,
+		Metadata:       metadata,
+		CreatedAt:      e.CreatedAt,
 	}
 }
 
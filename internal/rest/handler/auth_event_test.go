@@ -97,6 +97,62 @@ func TestAuthEventHandler_GetAll_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestAuthEventHandler_GetAll_Cursor(t *testing.T) {
+	t.Run("validation", func(t *testing.T) {
+		for _, target := range []string{
+			"/auth-events?cursor=",
+			"/auth-events?cursor=&limit=101",
+			"/auth-events?cursor=&limit=10&sort_by=created_at",
+		} {
+			w := httptest.NewRecorder()
+			NewAuthEventHandler(&mockAuthEventService{}).GetAll(w, withTenant(httptest.NewRequest(http.MethodGet, target, nil)))
+			assert.Equal(t, http.StatusBadRequest, w.Code, target)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		var gotFilter repository.AuthEventRepositoryGetFilter
+		var gotCursor string
+		svc := &mockAuthEventService{
+			findPaginatedFn: func(context.Context, repository.AuthEventRepositoryGetFilter) (*repository.PaginationResult[service.AuthEventServiceDataResult], error) {
+				t.Fatal("offset pagination must not be used")
+				return nil, nil
+			},
+			findByCursorFn: func(_ context.Context, f repository.AuthEventRepositoryGetFilter, cursor string) (*service.CursorPageResult[service.AuthEventServiceDataResult], error) {
+				gotFilter, gotCursor = f, cursor
+				return &service.CursorPageResult[service.AuthEventServiceDataResult]{
+					Data:       []service.AuthEventServiceDataResult{{AuthEventUUID: uuid.New(), EventType: "authn_login_success"}},
+					Limit:      10,
+					NextCursor: "next",
+					HasMore:    true,
+				}, nil
+			},
+		}
+		w := httptest.NewRecorder()
+		NewAuthEventHandler(svc).GetAll(w, withTenant(httptest.NewRequest(http.MethodGet, "/auth-events?cursor=abc&limit=10&sort_order=asc&category=AUTHN", nil)))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "abc", gotCursor)
+		assert.Equal(t, 10, gotFilter.Limit)
+		assert.Equal(t, "asc", gotFilter.SortOrder)
+		assert.Equal(t, "AUTHN", *gotFilter.Category)
+		assert.Equal(t, tenantID, *gotFilter.TenantID)
+		assert.Contains(t, w.Body.String(), `"next_cursor":"next","has_more":true`)
+		assert.NotContains(t, w.Body.String(), `"total"`)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		svc := &mockAuthEventService{
+			findByCursorFn: func(context.Context, repository.AuthEventRepositoryGetFilter, string) (*service.CursorPageResult[service.AuthEventServiceDataResult], error) {
+				return nil, errValidation
+			},
+		}
+		w := httptest.NewRecorder()
+		NewAuthEventHandler(svc).GetAll(w, withTenant(httptest.NewRequest(http.MethodGet, "/auth-events?cursor=bad&limit=10", nil)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 func TestAuthEventHandler_GetAll_WithDateFilters(t *testing.T) {
 	svc := &mockAuthEventService{
 		findPaginatedFn: func(_ context.Context, filter repository.AuthEventRepositoryGetFilter) (*repository.PaginationResult[service.AuthEventServiceDataResult], error) {
@@ -295,20 +351,26 @@ func TestAuthEventHandler_Get_Success(t *testing.T) {
 	eventUUID := uuid.New()
 	desc := "user logged in"
 	traceID := "abc123"
+	country, city, asOrg := "GB", "London", "Andrews & Arnold Ltd"
+	asn := int64(20712)
 	svc := &mockAuthEventService{
 		findByUUIDFn: func(_ context.Context, _ int64, _ uuid.UUID) (*service.AuthEventServiceDataResult, error) {
 			return &service.AuthEventServiceDataResult{
-				AuthEventUUID: eventUUID,
-				TenantID:      tenantID,
-				IPAddress:     "127.0.0.1",
-				Category:      "AUTHN",
-				EventType:     "authn_login_success",
-				Severity:      "INFO",
-				Result:        "success",
-				Description:   &desc,
-				TraceID:       &traceID,
-				Metadata:      datatypes.JSON(`{"key":"val"}`),
-				CreatedAt:     now,
+				AuthEventUUID:  eventUUID,
+				TenantID:       tenantID,
+				IPAddress:      "81.2.69.142",
+				Country:        &country,
+				City:           &city,
+				ASN:            &asn,
+				ASOrganization: &asOrg,
+				Category:       "AUTHN",
+				EventType:      "authn_login_success",
+				Severity:       "INFO",
+				Result:         "success",
+				Description:    &desc,
+				TraceID:        &traceID,
+				Metadata:       datatypes.JSON(`{"key":"val"}`),
+				CreatedAt:      now,
 			}, nil
 		},
 	}
@@ -318,6 +380,7 @@ func TestAuthEventHandler_Get_Success(t *testing.T) {
 	w := httptest.NewRecorder()
 	h.Get(w, r)
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"country":"GB","city":"London","asn":20712,"as_organization":"Andrews \u0026 Arnold Ltd"`)
 }
 
 // ---------------------------------------------------------------------------
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AuthzHandler handles HTTP requests for authorization tooling.
+type AuthzHandler struct {
+	authzSimulationService service.AuthzSimulationService
+}
+
+// NewAuthzHandler creates a new AuthzHandler.
+func NewAuthzHandler(authzSimulationService service.AuthzSimulationService) *AuthzHandler {
+	return &AuthzHandler{authzSimulationService: authzSimulationService}
+}
+
+// Simulate evaluates actions for a user or a hypothetical set of roles
+// without granting anything.
+//
+// POST /authz/simulate
+func (h *AuthzHandler) Simulate(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	var req dto.AuthzSimulateRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	checks := make([]service.AuthzSimulationCheck, len(req.Checks))
+	for i, c := range req.Checks {
+		checks[i] = service.AuthzSimulationCheck{Action: c.Action, Resource: c.Resource}
+	}
+
+	result, err := h.authzSimulationService.Simulate(r.Context(), service.AuthzSimulationInput{
+		TenantID:  tenant.TenantID,
+		UserUUID:  req.UserUUID,
+		RoleUUIDs: req.RoleUUIDs,
+		Checks:    checks,
+	})
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to simulate access", err)
+		return
+	}
+
+	resp.Success(w, toAuthzSimulationResponseDTO(result), "Access simulated successfully")
+}
+
+// Routes returns a handler listing the routes of routes that require a
+// permission, with the permissions each one accepts.
+//
+// GET /authz/routes
+func (h *AuthzHandler) Routes(routes chi.Routes) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		guarded, err := middleware.RoutePermissions(routes)
+		if err != nil {
+			resp.HandleServiceError(w, r, "Failed to list route permissions", err)
+			return
+		}
+
+		rows := make([]dto.AuthzRoutePermissionResponseDTO, len(guarded))
+		for i, route := range guarded {
+			rows[i] = dto.AuthzRoutePermissionResponseDTO{
+				Method:      route.Method,
+				Pattern:     route.Pattern,
+				Permissions: route.Permissions,
+			}
+		}
+
+		resp.Success(w, rows, "Route permissions retrieved successfully")
+	}
+}
+
+func toAuthzSimulationRoleResponseDTOs(roles []service.AuthzSimulationRole) []dto.AuthzSimulationRoleResponseDTO {
+	rows := make([]dto.AuthzSimulationRoleResponseDTO, len(roles))
+	for i, role := range roles {
+		rows[i] = dto.AuthzSimulationRoleResponseDTO{
+			RoleID: role.RoleUUID.String(),
+			Name:   role.Name,
+			Source: role.Source,
+		}
+	}
+	return rows
+}
+
+func toAuthzSimulationResponseDTO(result *service.AuthzSimulationResult) dto.AuthzSimulationResponseDTO {
+	decisions := make([]dto.AuthzSimulationDecisionResponseDTO, len(result.Decisions))
+	for i, d := range result.Decisions {
+		decision := "deny"
+		if d.Allowed {
+			decision = "allow"
+		}
+		policies := make([]dto.AuthzSimulationPolicyResponseDTO, len(d.MatchedPolicies))
+		for j, p := range d.MatchedPolicies {
+			policies[j] = dto.AuthzSimulationPolicyResponseDTO{
+				PolicyID:       p.PolicyUUID.String(),
+				Name:           p.Name,
+				Version:        p.Version,
+				Effect:         p.Effect,
+				StatementIndex: p.StatementIndex,
+				Conditional:    p.Conditional,
+			}
+		}
+		decisions[i] = dto.AuthzSimulationDecisionResponseDTO{
+			Action:          d.Action,
+			Resource:        d.Resource,
+			Decision:        decision,
+			Reason:          d.Reason,
+			GrantingRoles:   toAuthzSimulationRoleResponseDTOs(d.GrantingRoles),
+			MatchedPolicies: policies,
+		}
+	}
+
+	return dto.AuthzSimulationResponseDTO{
+		Roles:     toAuthzSimulationRoleResponseDTOs(result.Roles),
+		Decisions: decisions,
+	}
+}
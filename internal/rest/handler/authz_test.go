@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthzHandler_Simulate(t *testing.T) {
+	t.Run("no tenant", func(t *testing.T) {
+		h := NewAuthzHandler(&mockAuthzSimulationService{})
+		w := httptest.NewRecorder()
+		h.Simulate(w, jsonReq(t, http.MethodPost, "/authz/simulate", map[string]any{}))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("bad json", func(t *testing.T) {
+		h := NewAuthzHandler(&mockAuthzSimulationService{})
+		w := httptest.NewRecorder()
+		h.Simulate(w, withTenant(badJSONReq(t, http.MethodPost, "/authz/simulate")))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		h := NewAuthzHandler(&mockAuthzSimulationService{
+			simulateFn: func(service.AuthzSimulationInput) (*service.AuthzSimulationResult, error) {
+				t.Fatal("service should not be called")
+				return nil, nil
+			},
+		})
+		body := map[string]any{"checks": []map[string]any{{"action": "user:create"}}}
+		w := httptest.NewRecorder()
+		h.Simulate(w, withTenant(jsonReq(t, http.MethodPost, "/authz/simulate", body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewAuthzHandler(&mockAuthzSimulationService{
+			simulateFn: func(service.AuthzSimulationInput) (*service.AuthzSimulationResult, error) {
+				return nil, errNotFound
+			},
+		})
+		body := map[string]any{"user_id": uuid.NewString(), "checks": []map[string]any{{"action": "user:create"}}}
+		w := httptest.NewRecorder()
+		h.Simulate(w, withTenant(jsonReq(t, http.MethodPost, "/authz/simulate", body)))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		userID, roleID, policyID := uuid.New(), uuid.New(), uuid.New()
+		var got service.AuthzSimulationInput
+		h := NewAuthzHandler(&mockAuthzSimulationService{
+			simulateFn: func(in service.AuthzSimulationInput) (*service.AuthzSimulationResult, error) {
+				got = in
+				role := service.AuthzSimulationRole{RoleUUID: roleID, Name: "admin", Source: service.AuthzSimulationRoleSourceUser}
+				return &service.AuthzSimulationResult{
+					Roles: []service.AuthzSimulationRole{role},
+					Decisions: []service.AuthzSimulationDecision{
+						{Action: "user:create", Allowed: true, Reason: service.AuthzSimulationReasonPermissionGranted, GrantingRoles: []service.AuthzSimulationRole{role}},
+						{Action: "user:delete", Resource: "auth:users", Reason: service.AuthzSimulationReasonPolicyDenied, GrantingRoles: []service.AuthzSimulationRole{role},
+							MatchedPolicies: []service.AuthzSimulationPolicyMatch{{PolicyUUID: policyID, Name: "no-deletes", Effect: "deny"}}},
+					},
+				}, nil
+			},
+		})
+		body := map[string]any{
+			"user_id": userID.String(),
+			"checks": []map[string]any{
+				{"action": "user:create"},
+				{"action": "user:delete", "resource": "auth:users"},
+			},
+		}
+		w := httptest.NewRecorder()
+		h.Simulate(w, withTenant(jsonReq(t, http.MethodPost, "/authz/simulate", body)))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		assert.Equal(t, tenantID, got.TenantID)
+		require.NotNil(t, got.UserUUID)
+		assert.Equal(t, userID, *got.UserUUID)
+		assert.Equal(t, []service.AuthzSimulationCheck{{Action: "user:create"}, {Action: "user:delete", Resource: "auth:users"}}, got.Checks)
+
+		var res struct {
+			Data struct {
+				Decisions []struct {
+					Decision        string `json:"decision"`
+					Reason          string `json:"reason"`
+					MatchedPolicies []struct {
+						PolicyID string `json:"policy_id"`
+					} `json:"matched_policies"`
+				} `json:"decisions"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		require.Len(t, res.Data.Decisions, 2)
+		assert.Equal(t, "allow", res.Data.Decisions[0].Decision)
+		assert.Equal(t, "deny", res.Data.Decisions[1].Decision)
+		assert.Equal(t, "policy_denied", res.Data.Decisions[1].Reason)
+		assert.Equal(t, policyID.String(), res.Data.Decisions[1].MatchedPolicies[0].PolicyID)
+	})
+}
+
+func TestAuthzHandler_Routes(t *testing.T) {
+	routes := chi.NewRouter()
+	routes.With(middleware.PermissionMiddleware([]string{"user:read"})).
+		Get("/users", func(http.ResponseWriter, *http.Request) {})
+	routes.Get("/health", func(http.ResponseWriter, *http.Request) {})
+
+	h := NewAuthzHandler(&mockAuthzSimulationService{})
+	w := httptest.NewRecorder()
+	h.Routes(routes)(w, httptest.NewRequest(http.MethodGet, "/authz/routes", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Data []dto.AuthzRoutePermissionResponseDTO `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Equal(t, []dto.AuthzRoutePermissionResponseDTO{
+		{Method: http.MethodGet, Pattern: "/users", Permissions: []string{"user:read"}},
+	}, body.Data)
+}
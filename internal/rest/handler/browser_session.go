@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/security"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/maintainerd/auth/internal/session"
+)
+
+// BrowserSessionHandler signs users of first-party web apps in to cookie
+// sessions, as an alternative to handing the app bearer tokens.
+type BrowserSessionHandler struct {
+	loginService  service.LoginService
+	logoutService service.OAuthLogoutService
+	sessions      session.Store
+	lifetime      time.Duration
+}
+
+// NewBrowserSessionHandler creates a new BrowserSessionHandler. Sessions end
+// after lifetime at the latest, however active they are.
+func NewBrowserSessionHandler(loginService service.LoginService, logoutService service.OAuthLogoutService, sessions session.Store, lifetime time.Duration) *BrowserSessionHandler {
+	return &BrowserSessionHandler{
+		loginService:  loginService,
+		logoutService: logoutService,
+		sessions:      sessions,
+		lifetime:      lifetime,
+	}
+}
+
+// Create checks the user's credentials like the public login and starts a
+// browser session instead of returning tokens. The body must be JSON, which
+// cross-site forms cannot send, so other sites cannot sign a browser in.
+//
+// POST /session?client_id=...&provider_id=...
+func (h *BrowserSessionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+	sc := extractSecurityContext(r)
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		resp.Error(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return
+	}
+
+	q := dto.LoginQueryDTO{
+		ClientID:   r.URL.Query().Get("client_id"),
+		ProviderID: r.URL.Query().Get("provider_id"),
+	}
+	if err := q.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	var req dto.LoginRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	event := security.SecurityEvent{
+		UserID:    req.Username,
+		ClientID:  q.ClientID,
+		ClientIP:  sc.clientIP,
+		UserAgent: sc.userAgent,
+		RequestID: sc.requestID,
+		Endpoint:  "/session",
+		Method:    r.Method,
+		Timestamp: startTime,
+	}
+
+	result, err := h.loginService.LoginPublic(r.Context(), req.Username, req.Password, q.ClientID, q.ProviderID, req.AcceptedPolicies)
+	if err != nil {
+		event.EventType, event.Details, event.Severity = "login_failure", "Browser session authentication failed", "MEDIUM"
+		security.LogSecurityEvent(event)
+		resp.HandleServiceError(w, r, "Authentication failed", err)
+		return
+	}
+
+	// The tokens are only read for the user they were issued to and are
+	// never sent to the browser.
+	claims, err := jwt.ValidateToken(result.AccessToken)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to start session", err)
+		return
+	}
+	sub, _ := claims["sub"].(string)
+	sessionID, _ := claims["sid"].(string)
+	userUUID, err := uuid.Parse(sub)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to start session", err)
+		return
+	}
+
+	bs, err := middleware.StartBrowserSession(w, r, h.sessions, h.lifetime, userUUID, q.ClientID, q.ProviderID, sessionID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to start session", err)
+		return
+	}
+
+	event.EventType, event.Details, event.Severity = "login_success", "User signed in to a browser session", "LOW"
+	security.LogSecurityEvent(event)
+
+	data := toBrowserSessionResponseDTO(bs)
+	data.StepUpRequired = result.StepUpRequired
+	w.Header().Set(middleware.CSRFHeader, bs.CSRFToken)
+	resp.Created(w, data, "Session started successfully")
+}
+
+// Me returns the browser session and the user signed in to it. Apps call it
+// on load to find out whether the user is signed in and to get the CSRF
+// token.
+//
+// GET /session/me
+func (h *BrowserSessionHandler) Me(w http.ResponseWriter, r *http.Request) {
+	bs := middleware.BrowserSessionFromRequest(r)
+	user := middleware.AuthFromRequest(r).User
+	if bs == nil || user == nil {
+		resp.ErrorWithCode(w, apperror.CodeMissingCredentials, "No active session")
+		return
+	}
+
+	data := toBrowserSessionResponseDTO(bs)
+	data.User = &dto.BrowserSessionUserDTO{
+		UserID:          user.UserUUID.String(),
+		Username:        user.Username,
+		Fullname:        user.Fullname,
+		Email:           user.Email,
+		IsEmailVerified: user.IsEmailVerified,
+		Status:          user.Status,
+	}
+	w.Header().Set(middleware.CSRFHeader, bs.CSRFToken)
+	resp.Success(w, data, "Session retrieved successfully")
+}
+
+// Delete signs the browser out, deleting its session and cookie. The login
+// session it belongs to ends too, signing the user out of the clients it
+// signed in to through OIDC logout.
+//
+// DELETE /session
+func (h *BrowserSessionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if bs := middleware.BrowserSessionFromRequest(r); bs != nil && bs.SessionID != "" {
+		caller := &service.OAuthLogoutSession{Sub: bs.UserUUID.String(), ClientID: bs.ClientID, SessionID: bs.SessionID}
+		if _, oerr := h.logoutService.EndSession(r.Context(), dto.OAuthEndSessionRequestDTO{}, caller); oerr != nil {
+			resp.LoggerFromContext(r.Context()).Warn("end login session failed", "error", oerr.Description)
+		}
+	}
+
+	if err := middleware.EndBrowserSession(w, r, h.sessions); err != nil {
+		resp.HandleServiceError(w, r, "Failed to end session", err)
+		return
+	}
+
+	if bs := middleware.BrowserSessionFromRequest(r); bs != nil {
+		sc := extractSecurityContext(r)
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "logout",
+			UserID:    bs.UserUUID.String(),
+			ClientID:  bs.ClientID,
+			ClientIP:  sc.clientIP,
+			UserAgent: sc.userAgent,
+			RequestID: sc.requestID,
+			Endpoint:  "/session",
+			Method:    r.Method,
+			Timestamp: time.Now(),
+			Details:   "User signed out of a browser session",
+			Severity:  "LOW",
+		})
+	}
+
+	resp.Success(w, nil, "Session ended successfully")
+}
+
+func toBrowserSessionResponseDTO(bs *middleware.BrowserSession) dto.BrowserSessionResponseDTO {
+	return dto.BrowserSessionResponseDTO{
+		UserID:    bs.UserUUID.String(),
+		ClientID:  bs.ClientID,
+		CSRFToken: bs.CSRFToken,
+		CreatedAt: bs.CreatedAt,
+		ExpiresAt: bs.ExpiresAt,
+	}
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/maintainerd/auth/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBrowserSessionTestStore(t *testing.T) session.Store {
+	t.Helper()
+	store, err := session.NewCookieStore([][]byte{bytes.Repeat([]byte{7}, session.KeySize)}, session.Options{Name: session.BrowserName})
+	require.NoError(t, err)
+	return store
+}
+
+// ---------------------------------------------------------------------------
+// Create
+// ---------------------------------------------------------------------------
+
+func TestBrowserSessionHandler_Create_Success(t *testing.T) {
+	initTestJWTKeysForHandler(t)
+	userUUID := uuid.New()
+	token, err := jwt.GenerateAccessToken(userUUID.String(), "openid", "https://auth.example.com", "api", "c1", "p1")
+	require.NoError(t, err)
+
+	svc := &mockLoginService{
+		loginPublicFn: func(u, p, c, pr string) (*dto.LoginResponseDTO, error) {
+			assert.Equal(t, "user1", u)
+			assert.Equal(t, "c1", c)
+			return &dto.LoginResponseDTO{AccessToken: token, RefreshToken: "refresh"}, nil
+		},
+	}
+	h := NewBrowserSessionHandler(svc, &mockOAuthLogoutService{}, newBrowserSessionTestStore(t), time.Hour)
+	r := withSecurityCtx(newLoginRequest(t, http.MethodPost, "/session?client_id=c1&provider_id=p1",
+		map[string]string{"username": "user1", "password": "pass1"}))
+	w := httptest.NewRecorder()
+	h.Create(w, r)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	csrf := w.Header().Get(middleware.CSRFHeader)
+	assert.NotEmpty(t, csrf)
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, session.BrowserName, cookies[0].Name)
+	assert.True(t, cookies[0].HttpOnly)
+
+	body := w.Body.String()
+	assert.NotContains(t, body, token)
+	assert.NotContains(t, body, "refresh")
+	var res struct {
+		Data dto.BrowserSessionResponseDTO `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, userUUID.String(), res.Data.UserID)
+	assert.Equal(t, csrf, res.Data.CSRFToken)
+}
+
+func TestBrowserSessionHandler_Create_Errors(t *testing.T) {
+	cases := []struct {
+		name   string
+		build  func(t *testing.T) *http.Request
+		status int
+	}{
+		{
+			name: "form content type",
+			build: func(t *testing.T) *http.Request {
+				r := newLoginRequest(t, http.MethodPost, "/session?client_id=c1&provider_id=p1",
+					map[string]string{"username": "user1", "password": "pass1"})
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				return r
+			},
+			status: http.StatusUnsupportedMediaType,
+		},
+		{
+			name: "missing client id",
+			build: func(t *testing.T) *http.Request {
+				return newLoginRequest(t, http.MethodPost, "/session", map[string]string{"username": "user1", "password": "pass1"})
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "invalid body",
+			build: func(t *testing.T) *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/session?client_id=c1&provider_id=p1", bytes.NewBufferString("not-json"))
+				r.Header.Set("Content-Type", "application/json")
+				return r
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "empty username",
+			build: func(t *testing.T) *http.Request {
+				return newLoginRequest(t, http.MethodPost, "/session?client_id=c1&provider_id=p1", map[string]string{"username": "", "password": "pass1"})
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "wrong credentials",
+			build: func(t *testing.T) *http.Request {
+				return newLoginRequest(t, http.MethodPost, "/session?client_id=c1&provider_id=p1", map[string]string{"username": "user1", "password": "wrong"})
+			},
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	svc := &mockLoginService{
+		loginPublicFn: func(u, p, c, pr string) (*dto.LoginResponseDTO, error) {
+			return nil, errUnauthorized
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewBrowserSessionHandler(svc, &mockOAuthLogoutService{}, newBrowserSessionTestStore(t), time.Hour)
+			w := httptest.NewRecorder()
+			h.Create(w, withSecurityCtx(tc.build(t)))
+			assert.Equal(t, tc.status, w.Code)
+			assert.Empty(t, w.Result().Cookies())
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Me
+// ---------------------------------------------------------------------------
+
+func TestBrowserSessionHandler_Me(t *testing.T) {
+	h := NewBrowserSessionHandler(&mockLoginService{}, &mockOAuthLogoutService{}, newBrowserSessionTestStore(t), time.Hour)
+
+	t.Run("no session", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.Me(w, httptest.NewRequest(http.MethodGet, "/session/me", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("signed in", func(t *testing.T) {
+		user := &model.User{UserUUID: uuid.New(), Username: "user1", Email: "user1@example.com", Status: "active"}
+		r := httptest.NewRequest(http.MethodGet, "/session/me", nil)
+		r = middleware.WithBrowserSession(r, &middleware.BrowserSession{UserUUID: user.UserUUID, ClientID: "c1", CSRFToken: "csrf"})
+		r = middleware.WithAuthContext(r, &middleware.AuthContext{User: user})
+		w := httptest.NewRecorder()
+		h.Me(w, r)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "csrf", w.Header().Get(middleware.CSRFHeader))
+		var res struct {
+			Data dto.BrowserSessionResponseDTO `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		require.NotNil(t, res.Data.User)
+		assert.Equal(t, "user1", res.Data.User.Username)
+		assert.Equal(t, user.UserUUID.String(), res.Data.UserID)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Delete
+// ---------------------------------------------------------------------------
+
+func TestBrowserSessionHandler_Delete(t *testing.T) {
+	userUUID := uuid.New()
+	var ended *service.OAuthLogoutSession
+	logout := &mockOAuthLogoutService{
+		endSessionFn: func(_ dto.OAuthEndSessionRequestDTO, s *service.OAuthLogoutSession) (*dto.OAuthEndSessionResult, *apperror.OAuthError) {
+			ended = s
+			return &dto.OAuthEndSessionResult{}, nil
+		},
+	}
+	h := NewBrowserSessionHandler(&mockLoginService{}, logout, newBrowserSessionTestStore(t), time.Hour)
+	r := withSecurityCtx(httptest.NewRequest(http.MethodDelete, "/session", nil))
+	r = middleware.WithBrowserSession(r, &middleware.BrowserSession{UserUUID: userUUID, ClientID: "c1", SessionID: "sid-1"})
+	w := httptest.NewRecorder()
+	h.Delete(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, -1, cookies[0].MaxAge)
+	require.NotNil(t, ended)
+	assert.Equal(t, &service.OAuthLogoutSession{Sub: userUUID.String(), ClientID: "c1", SessionID: "sid-1"}, ended)
+}
@@ -2,9 +2,11 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
-	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -55,25 +57,9 @@ func (h *ClientHandler) Get(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Parse status array
-	var status []string
-	if v := q.Get("status"); v != "" {
-		status = strings.Split(v, ",")
-		// Trim whitespace from each value
-		for i, s := range status {
-			status[i] = strings.TrimSpace(s)
-		}
-	}
-
-	// Parse client_type array
-	var clientType []string
-	if v := q.Get("client_type"); v != "" {
-		clientType = strings.Split(v, ",")
-		// Trim whitespace from each value
-		for i, ct := range clientType {
-			clientType[i] = strings.TrimSpace(ct)
-		}
-	}
+	// Parse status and client_type arrays (comma-separated or repeated values)
+	status := queryValues(q, "status")
+	clientType := queryValues(q, "client_type")
 
 	// Build request DTO
 	reqParams := dto.ClientFilterDTO{
@@ -161,6 +147,7 @@ func (h *ClientHandler) GetByUUID(w http.ResponseWriter, r *http.Request) {
 
 	dtoRes := toClientResponseDTO(*Client)
 
+	w.Header().Set("ETag", middleware.ETag(Client.UpdatedAt))
 	resp.Success(w, dtoRes, "Auth client fetched successfully")
 }
 
@@ -188,11 +175,60 @@ func (h *ClientHandler) GetSecretByUUID(w http.ResponseWriter, r *http.Request)
 	dtoRes := dto.ClientSecretResponseDTO{
 		ClientID:     Client.ClientID,
 		ClientSecret: Client.ClientSecret,
+		Masked:       Client.Masked,
 	}
 
 	resp.Success(w, dtoRes, "Auth client secret fetched successfully")
 }
 
+// RevealSecret returns the full secret of an auth client after the caller
+// re-enters their password. The reveal is recorded in the auth event log.
+//
+// POST /clients/{client_uuid}/secret/reveal
+func (h *ClientHandler) RevealSecret(w http.ResponseWriter, r *http.Request) {
+	// Get tenant from context
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	// Get authentication context
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+
+	ClientUUID, err := uuid.Parse(chi.URLParam(r, "client_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid auth client UUID")
+		return
+	}
+
+	var req dto.ClientRevealSecretRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	Client, err := h.ClientService.RevealSecretByUUID(r.Context(), ClientUUID, tenant.TenantID, user, req.Password)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to reveal auth client secret", err)
+		return
+	}
+
+	resp.Success(w, dto.ClientSecretResponseDTO{
+		ClientID:     Client.ClientID,
+		ClientSecret: Client.ClientSecret,
+	}, "Auth client secret revealed successfully")
+}
+
 // Get Auth client config by UUID
 func (h *ClientHandler) GetConfigByUUID(w http.ResponseWriter, r *http.Request) {
 	// Get tenant from context
@@ -229,6 +265,10 @@ func (h *ClientHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	// Get authentication context
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	var req dto.ClientCreateRequestDTO
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -263,6 +303,10 @@ func (h *ClientHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	// Get authentication context
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	ClientUUID, err := uuid.Parse(chi.URLParam(r, "client_uuid"))
 	if err != nil {
@@ -289,6 +333,7 @@ func (h *ClientHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	dtoRes := toClientResponseDTO(*Client)
 
+	w.Header().Set("ETag", middleware.ETag(Client.UpdatedAt))
 	resp.Success(w, dtoRes, "Auth client updated successfully")
 }
 
@@ -303,6 +348,10 @@ func (h *ClientHandler) SetStatus(w http.ResponseWriter, r *http.Request) {
 
 	// Get authentication context
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	ClientUUID, err := uuid.Parse(chi.URLParam(r, "client_uuid"))
 	if err != nil {
@@ -333,6 +382,173 @@ func (h *ClientHandler) SetStatus(w http.ResponseWriter, r *http.Request) {
 	resp.Success(w, dtoRes, "Auth client status updated successfully")
 }
 
+// SetDefault makes an auth client the tenant's default, replacing the current
+// default client.
+//
+// PUT /clients/{client_uuid}/default
+func (h *ClientHandler) SetDefault(w http.ResponseWriter, r *http.Request) {
+	// Get tenant from context
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	// Get authentication context
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+
+	ClientUUID, err := uuid.Parse(chi.URLParam(r, "client_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid auth client UUID")
+		return
+	}
+
+	Client, err := h.ClientService.SetDefaultByUUID(r.Context(), ClientUUID, tenant.TenantID, user.UserUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to set default auth client", err)
+		return
+	}
+
+	resp.Success(w, toClientResponseDTO(*Client), "Default auth client updated successfully")
+}
+
+// Deprecate marks an auth client as deprecated with an optional sunset date.
+//
+// PUT /clients/{client_uuid}/deprecation
+func (h *ClientHandler) Deprecate(w http.ResponseWriter, r *http.Request) {
+	// Get tenant from context
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	// Get authentication context
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+
+	ClientUUID, err := uuid.Parse(chi.URLParam(r, "client_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid auth client UUID")
+		return
+	}
+
+	var req dto.DeprecationRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	Client, err := h.ClientService.SetDeprecationByUUID(r.Context(), ClientUUID, tenant.TenantID, req.SunsetAt, req.Notice, user.UserUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to deprecate auth client", err)
+		return
+	}
+
+	resp.Success(w, toClientResponseDTO(*Client), "Auth client deprecated successfully")
+}
+
+// Undeprecate removes the deprecation and sunset date from an auth client.
+//
+// DELETE /clients/{client_uuid}/deprecation
+func (h *ClientHandler) Undeprecate(w http.ResponseWriter, r *http.Request) {
+	// Get tenant from context
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	// Get authentication context
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+
+	ClientUUID, err := uuid.Parse(chi.URLParam(r, "client_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid auth client UUID")
+		return
+	}
+
+	Client, err := h.ClientService.ClearDeprecationByUUID(r.Context(), ClientUUID, tenant.TenantID, user.UserUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to remove auth client deprecation", err)
+		return
+	}
+
+	resp.Success(w, toClientResponseDTO(*Client), "Auth client deprecation removed successfully")
+}
+
+// RotateSecret issues an auth client a new secret. The new secret is returned
+// once; the previous one keeps authenticating for the requested grace period.
+//
+// POST /clients/{client_uuid}/rotate-secret
+func (h *ClientHandler) RotateSecret(w http.ResponseWriter, r *http.Request) {
+	// Get tenant from context
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	// Get authentication context
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+
+	ClientUUID, err := uuid.Parse(chi.URLParam(r, "client_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid auth client UUID")
+		return
+	}
+
+	// The body is optional; an empty one rotates with the default grace period.
+	var req dto.ClientRotateSecretRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		resp.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	grace := service.DefaultClientSecretGracePeriod
+	if req.GracePeriodSeconds != nil {
+		grace = time.Duration(*req.GracePeriodSeconds) * time.Second
+	}
+
+	result, err := h.ClientService.RotateSecretByUUID(r.Context(), ClientUUID, tenant.TenantID, grace, user.UserUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to rotate auth client secret", err)
+		return
+	}
+
+	resp.Success(w, dto.ClientSecretRotationResponseDTO{
+		ClientID:                result.ClientID,
+		ClientSecret:            result.ClientSecret,
+		SecretRotatedAt:         result.SecretRotatedAt,
+		PreviousSecretExpiresAt: result.PreviousSecretExpiresAt,
+	}, "Auth client secret rotated successfully")
+}
+
 // Delete Auth Client
 func (h *ClientHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	// Get tenant from context
@@ -344,6 +560,10 @@ func (h *ClientHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	// Get authentication context
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	ClientUUID, err := uuid.Parse(chi.URLParam(r, "client_uuid"))
 	if err != nil {
@@ -414,6 +634,10 @@ func (h *ClientHandler) CreateURI(w http.ResponseWriter, r *http.Request) {
 
 	// Get authentication context
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	ClientUUID, err := uuid.Parse(chi.URLParam(r, "client_uuid"))
 	if err != nil {
@@ -459,6 +683,10 @@ func (h *ClientHandler) UpdateURI(w http.ResponseWriter, r *http.Request) {
 
 	// Get authentication context
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	ClientUUID, err := uuid.Parse(chi.URLParam(r, "client_uuid"))
 	if err != nil {
@@ -526,6 +754,10 @@ func (h *ClientHandler) DeleteURI(w http.ResponseWriter, r *http.Request) {
 
 	// Get authentication context
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	ClientUUID, err := uuid.Parse(chi.URLParam(r, "client_uuid"))
 	if err != nil {
@@ -826,16 +1058,18 @@ func (h *ClientHandler) RemoveAPIPermission(w http.ResponseWriter, r *http.Reque
 // Convert result to DTO
 func toClientResponseDTO(r service.ClientServiceDataResult) dto.ClientResponseDTO {
 	result := dto.ClientResponseDTO{
-		ClientUUID:  r.ClientUUID,
-		Name:        r.Name,
-		DisplayName: r.DisplayName,
-		ClientType:  r.ClientType,
-		Domain:      r.Domain,
-		Status:      r.Status,
-		IsDefault:   r.IsDefault,
-		IsSystem:    r.IsSystem,
-		CreatedAt:   r.CreatedAt,
-		UpdatedAt:   r.UpdatedAt,
+		ClientUUID:   r.ClientUUID,
+		Name:         r.Name,
+		DisplayName:  r.DisplayName,
+		ClientType:   r.ClientType,
+		Domain:       r.Domain,
+		Status:       r.Status,
+		IsDefault:    r.IsDefault,
+		IsSystem:     r.IsSystem,
+		Deprecation:  toDeprecationResponseDTO(r.Deprecation),
+		ClientSecret: r.ClientSecret,
+		CreatedAt:    r.CreatedAt,
+		UpdatedAt:    r.UpdatedAt,
 	}
 
 	if r.IdentityProvider != nil {
@@ -5,10 +5,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
 	"github.com/maintainerd/auth/internal/service"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/datatypes"
 )
 
@@ -126,7 +131,7 @@ func TestClientHandler_GetByUUID_NotFound(t *testing.T) {
 func TestClientHandler_GetByUUID_Success(t *testing.T) {
 	svc := &mockClientService{
 		getByUUIDFn: func(id uuid.UUID, tid int64) (*service.ClientServiceDataResult, error) {
-			return &service.ClientServiceDataResult{Name: "client1"}, nil
+			return &service.ClientServiceDataResult{Name: "client1", UpdatedAt: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)}, nil
 		},
 	}
 	h := NewClientHandler(svc)
@@ -134,6 +139,7 @@ func TestClientHandler_GetByUUID_Success(t *testing.T) {
 	w := httptest.NewRecorder()
 	h.GetByUUID(w, r)
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"hnblonf9c0"`, w.Header().Get("ETag"))
 }
 
 func TestClientHandler_GetSecretByUUID(t *testing.T) {
@@ -367,6 +373,246 @@ func TestClientHandler_Delete_InvalidUUID(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestClientHandler_SetDefault(t *testing.T) {
+	path := "/clients/" + testResourceUUID.String() + "/default"
+
+	t.Run("no tenant returns 401", func(t *testing.T) {
+		r := withUser(withChiParam(httptest.NewRequest(http.MethodPut, path, nil), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(&mockClientService{}).SetDefault(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+	t.Run("invalid uuid returns 400", func(t *testing.T) {
+		r := withTenantAndUser(withChiParam(httptest.NewRequest(http.MethodPut, path, nil), "client_uuid", "bad"))
+		w := httptest.NewRecorder()
+		NewClientHandler(&mockClientService{}).SetDefault(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("service error", func(t *testing.T) {
+		svc := &mockClientService{setDefaultByUUIDFn: func(uuid.UUID, int64, uuid.UUID) (*service.ClientServiceDataResult, error) {
+			return nil, errNotFound
+		}}
+		r := withTenantAndUser(withChiParam(httptest.NewRequest(http.MethodPut, path, nil), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(svc).SetDefault(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+	t.Run("success", func(t *testing.T) {
+		svc := &mockClientService{setDefaultByUUIDFn: func(id uuid.UUID, _ int64, _ uuid.UUID) (*service.ClientServiceDataResult, error) {
+			return &service.ClientServiceDataResult{ClientUUID: id, Name: "app", IsDefault: true}, nil
+		}}
+		r := withTenantAndUser(withChiParam(httptest.NewRequest(http.MethodPut, path, nil), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(svc).SetDefault(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"is_default":true`)
+	})
+}
+
+func TestClientHandler_Deprecate(t *testing.T) {
+	path := "/clients/" + testResourceUUID.String() + "/deprecation"
+	body := map[string]any{"sunset_at": "2030-01-01T00:00:00Z"}
+
+	t.Run("no tenant returns 401", func(t *testing.T) {
+		r := withUser(withChiParam(jsonReq(t, http.MethodPut, path, body), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(&mockClientService{}).Deprecate(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+	t.Run("invalid uuid returns 400", func(t *testing.T) {
+		r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPut, path, body), "client_uuid", "bad"))
+		w := httptest.NewRecorder()
+		NewClientHandler(&mockClientService{}).Deprecate(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("bad json returns 400", func(t *testing.T) {
+		r := withTenantAndUser(withChiParam(badJSONReq(t, http.MethodPut, path), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(&mockClientService{}).Deprecate(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("validation error returns 400", func(t *testing.T) {
+		r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPut, path, map[string]any{"notice": ""}), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(&mockClientService{}).Deprecate(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("service error", func(t *testing.T) {
+		svc := &mockClientService{setDeprecationFn: func(uuid.UUID, int64, *time.Time, *string, uuid.UUID) (*service.ClientServiceDataResult, error) {
+			return nil, errValidation
+		}}
+		r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPut, path, body), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(svc).Deprecate(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("success", func(t *testing.T) {
+		svc := &mockClientService{setDeprecationFn: func(_ uuid.UUID, _ int64, sunsetAt *time.Time, notice *string, _ uuid.UUID) (*service.ClientServiceDataResult, error) {
+			return &service.ClientServiceDataResult{
+				Name:        "app",
+				Deprecation: &service.DeprecationServiceDataResult{DeprecatedAt: time.Now(), SunsetAt: sunsetAt, Notice: notice, UsageCount: 3},
+			}, nil
+		}}
+		r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPut, path, body), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(svc).Deprecate(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"usage_count":3`)
+	})
+}
+
+func TestClientHandler_Undeprecate(t *testing.T) {
+	path := "/clients/" + testResourceUUID.String() + "/deprecation"
+
+	t.Run("no tenant returns 401", func(t *testing.T) {
+		r := withUser(withChiParam(httptest.NewRequest(http.MethodDelete, path, nil), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(&mockClientService{}).Undeprecate(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+	t.Run("invalid uuid returns 400", func(t *testing.T) {
+		r := withTenantAndUser(withChiParam(httptest.NewRequest(http.MethodDelete, path, nil), "client_uuid", "bad"))
+		w := httptest.NewRecorder()
+		NewClientHandler(&mockClientService{}).Undeprecate(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("service error", func(t *testing.T) {
+		svc := &mockClientService{clearDeprecationFn: func(uuid.UUID, int64, uuid.UUID) (*service.ClientServiceDataResult, error) {
+			return nil, errNotFound
+		}}
+		r := withTenantAndUser(withChiParam(httptest.NewRequest(http.MethodDelete, path, nil), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(svc).Undeprecate(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+	t.Run("success", func(t *testing.T) {
+		svc := &mockClientService{clearDeprecationFn: func(uuid.UUID, int64, uuid.UUID) (*service.ClientServiceDataResult, error) {
+			return &service.ClientServiceDataResult{Name: "app"}, nil
+		}}
+		r := withTenantAndUser(withChiParam(httptest.NewRequest(http.MethodDelete, path, nil), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(svc).Undeprecate(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestClientHandler_RotateSecret(t *testing.T) {
+	path := "/clients/" + testResourceUUID.String() + "/rotate-secret"
+
+	t.Run("no tenant returns 401", func(t *testing.T) {
+		r := withUser(withChiParam(httptest.NewRequest(http.MethodPost, path, nil), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(&mockClientService{}).RotateSecret(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+	t.Run("invalid uuid returns 400", func(t *testing.T) {
+		r := withTenantAndUser(withChiParam(httptest.NewRequest(http.MethodPost, path, nil), "client_uuid", "bad"))
+		w := httptest.NewRecorder()
+		NewClientHandler(&mockClientService{}).RotateSecret(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("bad json returns 400", func(t *testing.T) {
+		r := withTenantAndUser(withChiParam(badJSONReq(t, http.MethodPost, path), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(&mockClientService{}).RotateSecret(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("validation error returns 400", func(t *testing.T) {
+		r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPost, path, map[string]any{"grace_period_seconds": -1}), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(&mockClientService{}).RotateSecret(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("service error", func(t *testing.T) {
+		svc := &mockClientService{rotateSecretFn: func(uuid.UUID, int64, time.Duration, uuid.UUID) (*service.ClientSecretRotationServiceDataResult, error) {
+			return nil, errNotFound
+		}}
+		r := withTenantAndUser(withChiParam(httptest.NewRequest(http.MethodPost, path, nil), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(svc).RotateSecret(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+	t.Run("empty body uses default grace period", func(t *testing.T) {
+		var gotGrace time.Duration
+		svc := &mockClientService{rotateSecretFn: func(_ uuid.UUID, _ int64, grace time.Duration, _ uuid.UUID) (*service.ClientSecretRotationServiceDataResult, error) {
+			gotGrace = grace
+			return &service.ClientSecretRotationServiceDataResult{ClientID: "cid", ClientSecret: "new-secret"}, nil
+		}}
+		r := withTenantAndUser(withChiParam(httptest.NewRequest(http.MethodPost, path, nil), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(svc).RotateSecret(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, service.DefaultClientSecretGracePeriod, gotGrace)
+		assert.Contains(t, w.Body.String(), `"client_secret":"new-secret"`)
+	})
+	t.Run("explicit grace period", func(t *testing.T) {
+		var gotGrace time.Duration
+		svc := &mockClientService{rotateSecretFn: func(_ uuid.UUID, _ int64, grace time.Duration, _ uuid.UUID) (*service.ClientSecretRotationServiceDataResult, error) {
+			gotGrace = grace
+			return &service.ClientSecretRotationServiceDataResult{}, nil
+		}}
+		r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPost, path, map[string]any{"grace_period_seconds": 0}), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(svc).RotateSecret(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, time.Duration(0), gotGrace)
+	})
+}
+
+func TestClientHandler_RevealSecret(t *testing.T) {
+	path := "/clients/" + testResourceUUID.String() + "/secret/reveal"
+	body := map[string]any{"password": "Secret-123"}
+
+	t.Run("no tenant returns 401", func(t *testing.T) {
+		r := withUser(withChiParam(jsonReq(t, http.MethodPost, path, body), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(&mockClientService{}).RevealSecret(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+	t.Run("invalid uuid returns 400", func(t *testing.T) {
+		r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPost, path, body), "client_uuid", "bad"))
+		w := httptest.NewRecorder()
+		NewClientHandler(&mockClientService{}).RevealSecret(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("bad json returns 400", func(t *testing.T) {
+		r := withTenantAndUser(withChiParam(badJSONReq(t, http.MethodPost, path), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(&mockClientService{}).RevealSecret(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("missing password returns 400", func(t *testing.T) {
+		r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPost, path, map[string]any{}), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(&mockClientService{}).RevealSecret(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("service error", func(t *testing.T) {
+		svc := &mockClientService{revealSecretFn: func(uuid.UUID, int64, *model.User, string) (*service.ClientSecretServiceDataResult, error) {
+			return nil, apperror.NewForbidden("too many failed attempts, try again later")
+		}}
+		r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPost, path, body), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(svc).RevealSecret(w, r)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+	t.Run("success", func(t *testing.T) {
+		var gotPassword string
+		svc := &mockClientService{revealSecretFn: func(_ uuid.UUID, _ int64, actor *model.User, password string) (*service.ClientSecretServiceDataResult, error) {
+			require.NotNil(t, actor)
+			gotPassword = password
+			return &service.ClientSecretServiceDataResult{ClientID: "cid", ClientSecret: ptr.Ptr("full-secret")}, nil
+		}}
+		r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPost, path, body), "client_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewClientHandler(svc).RevealSecret(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "Secret-123", gotPassword)
+		assert.Contains(t, w.Body.String(), `"client_secret":"full-secret"`)
+		assert.Contains(t, w.Body.String(), `"masked":false`)
+	})
+}
+
 func TestClientHandler_GetURIs(t *testing.T) {
 	t.Run("no tenant returns 401", func(t *testing.T) {
 		r := withChiParam(httptest.NewRequest(http.MethodGet, "/", nil), "client_uuid", testResourceUUID.String())
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// ConfigHandler handles HTTP requests for the runtime configuration.
+type ConfigHandler struct {
+	configService service.ConfigService
+}
+
+// NewConfigHandler creates a new ConfigHandler.
+func NewConfigHandler(configService service.ConfigService) *ConfigHandler {
+	return &ConfigHandler{configService: configService}
+}
+
+// Reload reads the config file again and applies the reloadable settings.
+//
+// POST /admin/config/reload
+func (h *ConfigHandler) Reload(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.Tenant == nil || auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	changed, err := h.configService.Reload(r.Context(), auth.Tenant.TenantID, auth.User)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to reload configuration", err)
+		return
+	}
+	if changed == nil {
+		changed = []string{}
+	}
+
+	resp.Success(w, dto.ConfigReloadResponseDTO{Changed: changed}, "Configuration reloaded successfully")
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigHandler_Reload(t *testing.T) {
+	t.Run("no auth", func(t *testing.T) {
+		h := NewConfigHandler(&mockConfigService{})
+		w := httptest.NewRecorder()
+		h.Reload(w, httptest.NewRequest(http.MethodPost, "/admin/config/reload", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("nothing changed", func(t *testing.T) {
+		h := NewConfigHandler(&mockConfigService{})
+		w := httptest.NewRecorder()
+		h.Reload(w, withTenantAndUser(httptest.NewRequest(http.MethodPost, "/admin/config/reload", nil)))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"changed":[]`)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewConfigHandler(&mockConfigService{
+			reloadFn: func(tID int64) ([]string, error) {
+				assert.Equal(t, tenantID, tID)
+				return []string{"SMTP_HOST"}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Reload(w, withTenantAndUser(httptest.NewRequest(http.MethodPost, "/admin/config/reload", nil)))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var body struct {
+			Data dto.ConfigReloadResponseDTO `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, []string{"SMTP_HOST"}, body.Data.Changed)
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		h := NewConfigHandler(&mockConfigService{
+			reloadFn: func(int64) ([]string, error) { return nil, apperror.NewValidation("invalid SMTP_PORT") },
+		})
+		w := httptest.NewRecorder()
+		h.Reload(w, withTenantAndUser(httptest.NewRequest(http.MethodPost, "/admin/config/reload", nil)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewConfigHandler(&mockConfigService{
+			reloadFn: func(int64) ([]string, error) { return nil, errors.New("boom") },
+		})
+		w := httptest.NewRecorder()
+		h.Reload(w, withTenantAndUser(httptest.NewRequest(http.MethodPost, "/admin/config/reload", nil)))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
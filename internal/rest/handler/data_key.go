@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// DataKeyHandler handles HTTP requests for the column encryption data keys.
+type DataKeyHandler struct {
+	dataKeyService service.DataKeyService
+}
+
+// NewDataKeyHandler creates a new DataKeyHandler.
+func NewDataKeyHandler(dataKeyService service.DataKeyService) *DataKeyHandler {
+	return &DataKeyHandler{dataKeyService: dataKeyService}
+}
+
+// List retrieves every data key, newest first, without key material.
+//
+// GET /admin/data-keys
+func (h *DataKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.dataKeyService.List(r.Context())
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to retrieve data keys", err)
+		return
+	}
+
+	resp.Success(w, keys, "Data keys retrieved successfully")
+}
+
+// Rotate generates a new active data key. Values sealed with the key it
+// replaces stay readable until they are re-encrypted.
+//
+// POST /admin/data-keys/rotate
+func (h *DataKeyHandler) Rotate(w http.ResponseWriter, r *http.Request) {
+	key, err := h.dataKeyService.Rotate(r.Context())
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to rotate data key", err)
+		return
+	}
+
+	resp.Created(w, key, "Data key rotated successfully")
+}
+
+// Rewrap wraps every data key again with the current key-encryption key.
+//
+// POST /admin/data-keys/rewrap
+func (h *DataKeyHandler) Rewrap(w http.ResponseWriter, r *http.Request) {
+	result, err := h.dataKeyService.Rewrap(r.Context())
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to rewrap data keys", err)
+		return
+	}
+
+	resp.Success(w, result, "Data keys rewrapped successfully")
+}
+
+// Reencrypt seals every stored value that is not sealed with the active data
+// key with it.
+//
+// POST /admin/data-keys/reencrypt
+func (h *DataKeyHandler) Reencrypt(w http.ResponseWriter, r *http.Request) {
+	result, err := h.dataKeyService.Reencrypt(r.Context())
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to re-encrypt columns", err)
+		return
+	}
+
+	resp.Success(w, result, "Columns re-encrypted successfully")
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataKeyHandler_List(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		h := NewDataKeyHandler(&mockDataKeyService{
+			listFn: func() ([]dto.DataKeyResponseDTO, error) {
+				return []dto.DataKeyResponseDTO{{KeyID: "dk-2", Status: "active"}, {KeyID: "dk-1", Status: "previous"}}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.List(w, httptest.NewRequest(http.MethodGet, "/admin/data-keys", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var body struct {
+			Data []dto.DataKeyResponseDTO `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Len(t, body.Data, 2)
+		assert.Equal(t, "dk-2", body.Data[0].KeyID)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewDataKeyHandler(&mockDataKeyService{
+			listFn: func() ([]dto.DataKeyResponseDTO, error) { return nil, errors.New("db down") },
+		})
+		w := httptest.NewRecorder()
+		h.List(w, httptest.NewRequest(http.MethodGet, "/admin/data-keys", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestDataKeyHandler_Rotate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		h := NewDataKeyHandler(&mockDataKeyService{
+			rotateFn: func() (*dto.DataKeyResponseDTO, error) {
+				return &dto.DataKeyResponseDTO{KeyID: "dk-3", Status: "active"}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Rotate(w, httptest.NewRequest(http.MethodPost, "/admin/data-keys/rotate", nil))
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"key_id":"dk-3"`)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewDataKeyHandler(&mockDataKeyService{
+			rotateFn: func() (*dto.DataKeyResponseDTO, error) { return nil, errors.New("db down") },
+		})
+		w := httptest.NewRecorder()
+		h.Rotate(w, httptest.NewRequest(http.MethodPost, "/admin/data-keys/rotate", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestDataKeyHandler_Rewrap(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		h := NewDataKeyHandler(&mockDataKeyService{
+			rewrapFn: func() (*dto.DataKeyRewrapResponseDTO, error) {
+				return &dto.DataKeyRewrapResponseDTO{Rewrapped: 3}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Rewrap(w, httptest.NewRequest(http.MethodPost, "/admin/data-keys/rewrap", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"rewrapped":3`)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewDataKeyHandler(&mockDataKeyService{
+			rewrapFn: func() (*dto.DataKeyRewrapResponseDTO, error) { return nil, errors.New("kms down") },
+		})
+		w := httptest.NewRecorder()
+		h.Rewrap(w, httptest.NewRequest(http.MethodPost, "/admin/data-keys/rewrap", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestDataKeyHandler_Reencrypt(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		h := NewDataKeyHandler(&mockDataKeyService{
+			reencryptFn: func() (*dto.DataKeyReencryptResponseDTO, error) {
+				return &dto.DataKeyReencryptResponseDTO{
+					ActiveKeyID: "dk-3",
+					Reencrypted: 2,
+					Columns:     map[string]int64{"clients.secret": 2},
+				}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Reencrypt(w, httptest.NewRequest(http.MethodPost, "/admin/data-keys/reencrypt", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"clients.secret":2`)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewDataKeyHandler(&mockDataKeyService{
+			reencryptFn: func() (*dto.DataKeyReencryptResponseDTO, error) { return nil, errors.New("db down") },
+		})
+		w := httptest.NewRecorder()
+		h.Reencrypt(w, httptest.NewRequest(http.MethodPost, "/admin/data-keys/reencrypt", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
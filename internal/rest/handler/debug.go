@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/logging"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// DebugHandler handles HTTP requests for runtime log levels and body tracing.
+type DebugHandler struct {
+	debugService service.DebugService
+}
+
+// NewDebugHandler creates a new DebugHandler.
+func NewDebugHandler(debugService service.DebugService) *DebugHandler {
+	return &DebugHandler{debugService: debugService}
+}
+
+// GetLogLevels retrieves the log level of every component.
+//
+// GET /debug/log-levels
+func (h *DebugHandler) GetLogLevels(w http.ResponseWriter, r *http.Request) {
+	levels := h.debugService.GetLogLevels(r.Context())
+
+	rows := make([]dto.LogLevelResponseDTO, len(levels))
+	for i, l := range levels {
+		rows[i] = toLogLevelResponseDTO(l)
+	}
+
+	resp.Success(w, rows, "Log levels retrieved successfully")
+}
+
+// SetLogLevel changes the log level of a component.
+//
+// PUT /debug/log-levels/{component}
+func (h *DebugHandler) SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.Tenant == nil || auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	var req dto.SetLogLevelRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.debugService.SetLogLevel(r.Context(), auth.Tenant.TenantID, auth.User, chi.URLParam(r, "component"), req.Level)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to set log level", err)
+		return
+	}
+
+	resp.Success(w, toLogLevelResponseDTO(*result), "Log level updated successfully")
+}
+
+// GetBodyTraces retrieves the active body trace rules.
+//
+// GET /debug/body-traces
+func (h *DebugHandler) GetBodyTraces(w http.ResponseWriter, r *http.Request) {
+	traces := h.debugService.GetBodyTraces(r.Context())
+
+	rows := make([]dto.BodyTraceResponseDTO, len(traces))
+	for i, rule := range traces {
+		rows[i] = toBodyTraceResponseDTO(rule)
+	}
+
+	resp.Success(w, rows, "Body traces retrieved successfully")
+}
+
+// EnableBodyTrace enables request/response body tracing for a tenant or
+// client until the requested duration elapses.
+//
+// POST /debug/body-traces
+func (h *DebugHandler) EnableBodyTrace(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.Tenant == nil || auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	var req dto.BodyTraceRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	in := service.BodyTraceInput{Duration: time.Duration(req.DurationMinutes) * time.Minute}
+	if req.TenantUUID != nil {
+		id, _ := uuid.Parse(*req.TenantUUID)
+		in.TenantUUID = &id
+	}
+	if req.ClientUUID != nil {
+		id, _ := uuid.Parse(*req.ClientUUID)
+		in.ClientUUID = &id
+	}
+
+	result, err := h.debugService.EnableBodyTrace(r.Context(), auth.Tenant.TenantID, auth.User, in)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to enable body trace", err)
+		return
+	}
+
+	resp.Created(w, toBodyTraceResponseDTO(*result), "Body trace enabled successfully")
+}
+
+// DisableBodyTrace removes a body trace rule before it expires.
+//
+// DELETE /debug/body-traces/{body_trace_uuid}
+func (h *DebugHandler) DisableBodyTrace(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.Tenant == nil || auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	traceUUID, err := uuid.Parse(chi.URLParam(r, "body_trace_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid body trace UUID")
+		return
+	}
+
+	result, err := h.debugService.DisableBodyTrace(r.Context(), auth.Tenant.TenantID, auth.User, traceUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to disable body trace", err)
+		return
+	}
+
+	resp.Success(w, toBodyTraceResponseDTO(*result), "Body trace disabled successfully")
+}
+
+func toLogLevelResponseDTO(l logging.ComponentLevel) dto.LogLevelResponseDTO {
+	return dto.LogLevelResponseDTO{
+		Component: l.Component,
+		Level:     strings.ToLower(l.Level.String()),
+		ChangedBy: l.ChangedBy,
+		ChangedAt: l.ChangedAt,
+	}
+}
+
+func toBodyTraceResponseDTO(rule logging.TraceRule) dto.BodyTraceResponseDTO {
+	return dto.BodyTraceResponseDTO{
+		BodyTraceID: rule.TraceUUID.String(),
+		Target:      rule.Target,
+		Value:       rule.Value,
+		EnabledBy:   rule.EnabledBy,
+		CreatedAt:   rule.CreatedAt,
+		ExpiresAt:   rule.ExpiresAt,
+	}
+}
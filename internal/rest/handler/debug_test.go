@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/logging"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugHandler_GetLogLevels(t *testing.T) {
+	h := NewDebugHandler(&mockDebugService{})
+	w := httptest.NewRecorder()
+	h.GetLogLevels(w, httptest.NewRequest(http.MethodGet, "/debug/log-levels", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"level":"info"`)
+}
+
+func TestDebugHandler_SetLogLevel(t *testing.T) {
+	t.Run("no auth", func(t *testing.T) {
+		h := NewDebugHandler(&mockDebugService{})
+		w := httptest.NewRecorder()
+		h.SetLogLevel(w, jsonReq(t, http.MethodPut, "/debug/log-levels/db", map[string]any{"level": "debug"}))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		h := NewDebugHandler(&mockDebugService{
+			setLogLevelFn: func(int64, string, string) (*logging.ComponentLevel, error) {
+				t.Fatal("service should not be called")
+				return nil, nil
+			},
+		})
+		r := withTenantAndUser(jsonReq(t, http.MethodPut, "/debug/log-levels/db", map[string]any{"level": "verbose"}))
+		w := httptest.NewRecorder()
+		h.SetLogLevel(w, withChiParam(r, "component", "db"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		var gotComponent, gotLevel string
+		h := NewDebugHandler(&mockDebugService{
+			setLogLevelFn: func(tID int64, component, level string) (*logging.ComponentLevel, error) {
+				assert.Equal(t, tenantID, tID)
+				gotComponent, gotLevel = component, level
+				return &logging.ComponentLevel{Component: component, Level: slog.LevelDebug, ChangedBy: testUserUUID.String()}, nil
+			},
+		})
+		r := withTenantAndUser(jsonReq(t, http.MethodPut, "/debug/log-levels/db", map[string]any{"level": "debug"}))
+		w := httptest.NewRecorder()
+		h.SetLogLevel(w, withChiParam(r, "component", "db"))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "db", gotComponent)
+		assert.Equal(t, "debug", gotLevel)
+		assert.Contains(t, w.Body.String(), `"level":"debug"`)
+		assert.Contains(t, w.Body.String(), testUserUUID.String())
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewDebugHandler(&mockDebugService{
+			setLogLevelFn: func(int64, string, string) (*logging.ComponentLevel, error) { return nil, errForbidden },
+		})
+		r := withTenantAndUser(jsonReq(t, http.MethodPut, "/debug/log-levels/db", map[string]any{"level": "debug"}))
+		w := httptest.NewRecorder()
+		h.SetLogLevel(w, withChiParam(r, "component", "db"))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestDebugHandler_EnableBodyTrace(t *testing.T) {
+	t.Run("no auth", func(t *testing.T) {
+		h := NewDebugHandler(&mockDebugService{})
+		w := httptest.NewRecorder()
+		h.EnableBodyTrace(w, jsonReq(t, http.MethodPost, "/debug/body-traces", map[string]any{}))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		h := NewDebugHandler(&mockDebugService{})
+		body := map[string]any{"tenant_id": testResourceUUID.String(), "duration_minutes": 120}
+		w := httptest.NewRecorder()
+		h.EnableBodyTrace(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/debug/body-traces", body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		traceUUID := uuid.New()
+		h := NewDebugHandler(&mockDebugService{
+			enableBodyTraceFn: func(in service.BodyTraceInput) (*logging.TraceRule, error) {
+				assert.Nil(t, in.TenantUUID)
+				require.NotNil(t, in.ClientUUID)
+				assert.Equal(t, testResourceUUID, *in.ClientUUID)
+				assert.Equal(t, "15m0s", in.Duration.String())
+				return &logging.TraceRule{TraceUUID: traceUUID, Target: logging.TraceTargetClient, Value: "web"}, nil
+			},
+		})
+		body := map[string]any{"client_id": testResourceUUID.String(), "duration_minutes": 15}
+		w := httptest.NewRecorder()
+		h.EnableBodyTrace(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/debug/body-traces", body)))
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var res struct {
+			Data dto.BodyTraceResponseDTO `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, traceUUID.String(), res.Data.BodyTraceID)
+		assert.Equal(t, "web", res.Data.Value)
+	})
+}
+
+func TestDebugHandler_DisableBodyTrace(t *testing.T) {
+	t.Run("invalid uuid", func(t *testing.T) {
+		h := NewDebugHandler(&mockDebugService{})
+		r := withTenantAndUser(httptest.NewRequest(http.MethodDelete, "/debug/body-traces/bad", nil))
+		w := httptest.NewRecorder()
+		h.DisableBodyTrace(w, withChiParam(r, "body_trace_uuid", "bad"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewDebugHandler(&mockDebugService{})
+		r := withTenantAndUser(httptest.NewRequest(http.MethodDelete, "/debug/body-traces/x", nil))
+		w := httptest.NewRecorder()
+		h.DisableBodyTrace(w, withChiParam(r, "body_trace_uuid", testResourceUUID.String()))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), testResourceUUID.String())
+	})
+}
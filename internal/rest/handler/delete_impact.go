@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// toDeleteImpactResponseDTO converts a dry-run report. Lists are never null
+// so clients can iterate them without checks.
+func toDeleteImpactResponseDTO(r *service.DeleteImpactReport) dto.DeleteImpactResponseDTO {
+	out := dto.DeleteImpactResponseDTO{
+		DryRun:              true,
+		WouldSucceed:        r.WouldSucceed,
+		RowsAffected:        r.RowsAffected,
+		Impact:              make([]dto.DeleteImpactEntryResponseDTO, 0, len(r.Impact)),
+		BlockingConstraints: append([]string{}, r.BlockingConstraints...),
+		Events:              append([]string{}, r.Events...),
+	}
+	for _, e := range r.Impact {
+		out.Impact = append(out.Impact, dto.DeleteImpactEntryResponseDTO{
+			Table:      e.Table,
+			Constraint: e.Constraint,
+			Action:     e.Action,
+			Rows:       e.Rows,
+		})
+	}
+	return out
+}
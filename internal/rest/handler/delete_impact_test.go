@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDeleteImpactReport() *service.DeleteImpactReport {
+	return &service.DeleteImpactReport{
+		WouldSucceed: true,
+		RowsAffected: 5,
+		Impact: []repository.DeleteImpactEntry{
+			{Table: "roles", Action: repository.DeleteImpactDelete, Rows: 1},
+			{Table: "user_roles", Constraint: "fk_user_roles_role", Action: repository.DeleteImpactCascade, Rows: 4},
+		},
+		Events: []string{"role.deleted"},
+	}
+}
+
+func decodeDeleteImpact(t *testing.T, w *httptest.ResponseRecorder) dto.DeleteImpactResponseDTO {
+	t.Helper()
+	var body struct {
+		Data dto.DeleteImpactResponseDTO `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return body.Data
+}
+
+func TestRoleHandler_Delete_DryRun(t *testing.T) {
+	newReq := func(query string) *http.Request {
+		return withTenantAndUser(withChiParam(httptest.NewRequest(http.MethodDelete, "/roles/"+testResourceUUID.String()+query, nil), "role_uuid", testResourceUUID.String()))
+	}
+
+	t.Run("reports without deleting", func(t *testing.T) {
+		svc := &mockRoleService{
+			deleteByUUIDFn: func(uuid.UUID, int64, uuid.UUID) (*service.RoleServiceDataResult, error) {
+				t.Fatal("dry run must not delete")
+				return nil, nil
+			},
+			previewDeleteFn: func(id uuid.UUID, _ int64, _ uuid.UUID) (*service.DeleteImpactReport, error) {
+				assert.Equal(t, testResourceUUID, id)
+				return testDeleteImpactReport(), nil
+			},
+		}
+		w := httptest.NewRecorder()
+		NewRoleHandler(svc).Delete(w, newReq("?dry_run=true"))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		got := decodeDeleteImpact(t, w)
+		assert.True(t, got.DryRun)
+		assert.True(t, got.WouldSucceed)
+		assert.Equal(t, int64(5), got.RowsAffected)
+		require.Len(t, got.Impact, 2)
+		assert.Equal(t, "fk_user_roles_role", got.Impact[1].Constraint)
+		assert.Equal(t, []string{}, got.BlockingConstraints)
+		assert.Equal(t, []string{"role.deleted"}, got.Events)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewRoleHandler(&mockRoleService{}).Delete(w, newReq("?dry_run=maybe"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		svc := &mockRoleService{previewDeleteFn: func(uuid.UUID, int64, uuid.UUID) (*service.DeleteImpactReport, error) {
+			return nil, errNotFound
+		}}
+		w := httptest.NewRecorder()
+		NewRoleHandler(svc).Delete(w, newReq("?dry_run=1"))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestTenantHandler_Delete_DryRun(t *testing.T) {
+	ms := &mockTenantMemberService{isUserInTenantFn: func(int64, uuid.UUID) (bool, error) { return true, nil }}
+	ts := &mockTenantService{
+		getByUUIDFn: func(uuid.UUID) (*service.TenantServiceDataResult, error) {
+			return &service.TenantServiceDataResult{}, nil
+		},
+		deleteByUUIDFn: func(uuid.UUID) (*service.TenantServiceDataResult, error) {
+			t.Fatal("dry run must not delete")
+			return nil, nil
+		},
+		previewDeleteFn: func(uuid.UUID) (*service.DeleteImpactReport, error) {
+			return &service.DeleteImpactReport{BlockingConstraints: []string{"fk_clients_tenant"}}, nil
+		},
+	}
+	r := withUser(withChiParam(httptest.NewRequest(http.MethodDelete, "/?dry_run=true", nil), "tenant_uuid", testResourceUUID.String()))
+	w := httptest.NewRecorder()
+	newTenantHandler(ts, ms).Delete(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	got := decodeDeleteImpact(t, w)
+	assert.False(t, got.WouldSucceed)
+	assert.Equal(t, []string{"fk_clients_tenant"}, got.BlockingConstraints)
+	assert.Equal(t, []dto.DeleteImpactEntryResponseDTO{}, got.Impact)
+}
+
+func TestUserHandler_DryRun(t *testing.T) {
+	newReq := func(path string) *http.Request {
+		return withTenantAndUser(withChiParam(httptest.NewRequest(http.MethodDelete, path, nil), "user_uuid", testResourceUUID.String()))
+	}
+	svc := &mockUserService{
+		deleteByUUIDFn: func(uuid.UUID, int64, uuid.UUID) (*service.UserServiceDataResult, error) {
+			t.Fatal("dry run must not delete")
+			return nil, nil
+		},
+		purgeByUUIDFn: func(uuid.UUID, int64, uuid.UUID) (*service.UserServiceDataResult, error) {
+			t.Fatal("dry run must not purge")
+			return nil, nil
+		},
+		previewDeleteFn: func(uuid.UUID, int64, uuid.UUID) (*service.DeleteImpactReport, error) {
+			return &service.DeleteImpactReport{WouldSucceed: true, RowsAffected: 1}, nil
+		},
+		previewPurgeFn: func(uuid.UUID, int64, uuid.UUID) (*service.DeleteImpactReport, error) {
+			return &service.DeleteImpactReport{WouldSucceed: true, RowsAffected: 9}, nil
+		},
+	}
+
+	w := httptest.NewRecorder()
+	NewUserHandler(svc).DeleteUser(w, newReq("/users/"+testResourceUUID.String()+"?dry_run=true"))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int64(1), decodeDeleteImpact(t, w).RowsAffected)
+
+	w = httptest.NewRecorder()
+	NewUserHandler(svc).PurgeUser(w, newReq("/users/"+testResourceUUID.String()+"/purge?dry_run=true"))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int64(9), decodeDeleteImpact(t, w).RowsAffected)
+
+	w = httptest.NewRecorder()
+	NewUserHandler(svc).PurgeUser(w, newReq("/users/"+testResourceUUID.String()+"/purge?dry_run=yes"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -47,11 +47,8 @@ func (h *EmailTemplateHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	page, _ := strconv.Atoi(q.Get("page"))
 	limit, _ := strconv.Atoi(q.Get("limit"))
 
-	// Parse status filter (can be multiple)
-	var status []string
-	if v := q.Get("status"); v != "" {
-		status = append(status, v)
-	}
+	// Parse status filter (comma-separated or repeated values)
+	status := queryValues(q, "status")
 
 	// Parse boolean filters for default and system templates
 	var isDefault *bool
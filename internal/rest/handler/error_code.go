@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+)
+
+// ErrorCodeHandler serves the registry of machine-readable error codes.
+type ErrorCodeHandler struct{}
+
+// NewErrorCodeHandler creates a new ErrorCodeHandler.
+func NewErrorCodeHandler() *ErrorCodeHandler {
+	return &ErrorCodeHandler{}
+}
+
+// List returns every error code the API can return.
+//
+// GET /errors
+func (h *ErrorCodeHandler) List(w http.ResponseWriter, _ *http.Request) {
+	codes := apperror.Codes()
+	out := make([]dto.ErrorCodeResponseDTO, len(codes))
+	for i, info := range codes {
+		out[i] = toErrorCodeResponseDTO(info)
+	}
+	resp.Success(w, out, "Error codes retrieved successfully")
+}
+
+// Get returns a single error code. It is what the "type" URI of an error
+// response resolves to.
+//
+// GET /errors/{code}
+func (h *ErrorCodeHandler) Get(w http.ResponseWriter, r *http.Request) {
+	info, ok := apperror.Lookup(apperror.Code(chi.URLParam(r, "code")))
+	if !ok {
+		resp.Error(w, http.StatusNotFound, "Error code not found")
+		return
+	}
+	resp.Success(w, toErrorCodeResponseDTO(info), "Error code retrieved successfully")
+}
+
+func toErrorCodeResponseDTO(info apperror.CodeInfo) dto.ErrorCodeResponseDTO {
+	return dto.ErrorCodeResponseDTO{
+		Code:   string(info.Code),
+		Name:   info.Name,
+		Status: info.Status,
+		Title:  info.Title,
+	}
+}
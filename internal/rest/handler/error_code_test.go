@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorCodeHandler_List(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewErrorCodeHandler().List(w, httptest.NewRequest(http.MethodGet, "/errors", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Data []dto.ErrorCodeResponseDTO `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Len(t, body.Data, len(apperror.Codes()))
+	assert.Contains(t, body.Data, dto.ErrorCodeResponseDTO{
+		Code: "AUTH-1001", Name: "invalid_credentials", Status: http.StatusUnauthorized, Title: "Invalid credentials",
+	})
+}
+
+func TestErrorCodeHandler_Get(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := withChiParam(httptest.NewRequest(http.MethodGet, "/errors/KEY-4001", nil), "code", "KEY-4001")
+		NewErrorCodeHandler().Get(w, r)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var body struct {
+			Data dto.ErrorCodeResponseDTO `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		assert.Equal(t, "invalid_api_key", body.Data.Name)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := withChiParam(httptest.NewRequest(http.MethodGet, "/errors/NOPE", nil), "code", "NOPE")
+		NewErrorCodeHandler().Get(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
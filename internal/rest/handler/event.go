@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// EventHandler serves the lifecycle event feed used to keep downstream read
+// models in sync.
+type EventHandler struct {
+	eventService service.EventService
+}
+
+// NewEventHandler creates a new EventHandler.
+func NewEventHandler(eventService service.EventService) *EventHandler {
+	return &EventHandler{eventService: eventService}
+}
+
+// GetFeed returns lifecycle events after the supplied cursor, oldest first.
+//
+// GET /events
+func (h *EventHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	q := r.URL.Query()
+	limit := dto.EventFeedDefaultLimit
+	if raw := q.Get("limit"); raw != "" {
+		limit, _ = strconv.Atoi(raw)
+	}
+
+	filter := dto.EventFeedFilterDTO{
+		Cursor: q.Get("cursor"),
+		Types:  queryValues(q, "types"),
+		Limit:  limit,
+	}
+
+	if err := filter.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.eventService.Feed(r.Context(), service.EventServiceFeedFilter{
+		TenantID: tenant.TenantID,
+		Cursor:   filter.Cursor,
+		Types:    filter.Types,
+		Limit:    filter.Limit,
+	})
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get events", err)
+		return
+	}
+
+	events := make([]dto.EventResponseDTO, len(result.Data))
+	for i, e := range result.Data {
+		events[i] = toEventResponseDTO(e)
+	}
+
+	resp.Success(w, dto.EventFeedResponseDTO{
+		Events:     events,
+		NextCursor: result.NextCursor,
+		HasMore:    result.HasMore,
+	}, "Events retrieved successfully")
+}
+
+func toEventResponseDTO(e service.EventServiceDataResult) dto.EventResponseDTO {
+	payload := json.RawMessage(e.Payload)
+	if !json.Valid(payload) {
+		payload = json.RawMessage("{}")
+	}
+
+	return dto.EventResponseDTO{
+		EventID:       e.EventUUID.String(),
+		Sequence:      e.Sequence,
+		EventType:     e.EventType,
+		SchemaVersion: e.SchemaVersion,
+		AggregateType: e.AggregateType,
+		AggregateID:   e.AggregateUUID.String(),
+		Payload:       payload,
+		OccurredAt:    e.OccurredAt,
+	}
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func TestEventHandler_GetFeed_NoTenant(t *testing.T) {
+	h := NewEventHandler(&mockEventService{})
+	r := httptest.NewRequest(http.MethodGet, "/events", nil)
+	w := httptest.NewRecorder()
+	h.GetFeed(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestEventHandler_GetFeed_ValidationErrors(t *testing.T) {
+	for name, target := range map[string]string{
+		"limit too large": "/events?limit=501",
+		"limit zero":      "/events?limit=0",
+		"limit not int":   "/events?limit=abc",
+		"unknown type":    "/events?types=user.created,user.exploded",
+	} {
+		t.Run(name, func(t *testing.T) {
+			h := NewEventHandler(&mockEventService{})
+			r := withTenant(httptest.NewRequest(http.MethodGet, target, nil))
+			w := httptest.NewRecorder()
+			h.GetFeed(w, r)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestEventHandler_GetFeed_ServiceValidationError(t *testing.T) {
+	svc := &mockEventService{
+		feedFn: func(_ context.Context, _ service.EventServiceFeedFilter) (*service.EventServiceFeedResult, error) {
+			return nil, errValidation
+		},
+	}
+	h := NewEventHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/events?cursor=bogus", nil))
+	w := httptest.NewRecorder()
+	h.GetFeed(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestEventHandler_GetFeed_ServiceError(t *testing.T) {
+	svc := &mockEventService{
+		feedFn: func(_ context.Context, _ service.EventServiceFeedFilter) (*service.EventServiceFeedResult, error) {
+			return nil, assert.AnError
+		},
+	}
+	h := NewEventHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/events", nil))
+	w := httptest.NewRecorder()
+	h.GetFeed(w, r)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestEventHandler_GetFeed_Success(t *testing.T) {
+	eventUUID := uuid.New()
+	userUUID := uuid.New()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	var got service.EventServiceFeedFilter
+	svc := &mockEventService{
+		feedFn: func(_ context.Context, f service.EventServiceFeedFilter) (*service.EventServiceFeedResult, error) {
+			got = f
+			return &service.EventServiceFeedResult{
+				Data: []service.EventServiceDataResult{{
+					EventUUID:     eventUUID,
+					Sequence:      42,
+					EventType:     model.EventTypeUserCreated,
+					SchemaVersion: model.EventSchemaVersion,
+					AggregateType: model.EventAggregateUser,
+					AggregateUUID: userUUID,
+					Payload:       datatypes.JSON(`{"username":"alice"}`),
+					OccurredAt:    now,
+				}},
+				NextCursor: "next",
+				HasMore:    true,
+			}, nil
+		},
+	}
+	h := NewEventHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/events?cursor=abc&types=user.created&types=role.deleted", nil))
+	w := httptest.NewRecorder()
+	h.GetFeed(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	assert.Equal(t, tenantID, got.TenantID)
+	assert.Equal(t, "abc", got.Cursor)
+	assert.Equal(t, []string{model.EventTypeUserCreated, model.EventTypeRoleDeleted}, got.Types)
+	assert.Equal(t, dto.EventFeedDefaultLimit, got.Limit)
+
+	var body struct {
+		Data dto.EventFeedResponseDTO `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "next", body.Data.NextCursor)
+	assert.True(t, body.Data.HasMore)
+	require.Len(t, body.Data.Events, 1)
+	e := body.Data.Events[0]
+	assert.Equal(t, eventUUID.String(), e.EventID)
+	assert.Equal(t, int64(42), e.Sequence)
+	assert.Equal(t, userUUID.String(), e.AggregateID)
+	assert.JSONEq(t, `{"username":"alice"}`, string(e.Payload))
+}
+
+func TestEventHandler_GetFeed_InvalidPayloadFallsBackToEmptyObject(t *testing.T) {
+	svc := &mockEventService{
+		feedFn: func(_ context.Context, _ service.EventServiceFeedFilter) (*service.EventServiceFeedResult, error) {
+			return &service.EventServiceFeedResult{
+				Data: []service.EventServiceDataResult{{EventUUID: uuid.New()}},
+			}, nil
+		},
+	}
+	h := NewEventHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/events?limit=10", nil))
+	w := httptest.NewRecorder()
+	h.GetFeed(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"payload":{}`)
+}
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// featureFlagDefaultRollout is the rollout percentage of flags created or
+// updated without one.
+const featureFlagDefaultRollout = 100
+
+// FeatureFlagHandler handles HTTP requests for feature flags and their
+// per-tenant overrides.
+type FeatureFlagHandler struct {
+	featureFlagService service.FeatureFlagService
+}
+
+// NewFeatureFlagHandler creates a new FeatureFlagHandler.
+func NewFeatureFlagHandler(featureFlagService service.FeatureFlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{featureFlagService: featureFlagService}
+}
+
+// List returns all feature flags by key.
+//
+// GET /admin/feature-flags
+func (h *FeatureFlagHandler) List(w http.ResponseWriter, r *http.Request) {
+	flags, err := h.featureFlagService.List(r.Context())
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to list feature flags", err)
+		return
+	}
+
+	rows := make([]dto.FeatureFlagResponseDTO, len(flags))
+	for i, flag := range flags {
+		rows[i] = toFeatureFlagResponseDTO(flag)
+	}
+
+	resp.Success(w, rows, "Feature flags retrieved successfully")
+}
+
+// Get returns a feature flag.
+//
+// GET /admin/feature-flags/{feature_flag_uuid}
+func (h *FeatureFlagHandler) Get(w http.ResponseWriter, r *http.Request) {
+	flagUUID, err := uuid.Parse(chi.URLParam(r, "feature_flag_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid feature flag UUID")
+		return
+	}
+
+	flag, err := h.featureFlagService.GetByUUID(r.Context(), flagUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Feature flag not found", err)
+		return
+	}
+
+	resp.Success(w, toFeatureFlagResponseDTO(*flag), "Feature flag retrieved successfully")
+}
+
+// Create creates a feature flag.
+//
+// POST /admin/feature-flags
+func (h *FeatureFlagHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.FeatureFlagCreateRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	rollout := featureFlagDefaultRollout
+	if req.RolloutPercentage != nil {
+		rollout = *req.RolloutPercentage
+	}
+
+	flag, err := h.featureFlagService.Create(r.Context(), req.Key, service.FeatureFlagServiceInput{
+		Description:       req.Description,
+		Enabled:           req.Enabled,
+		RolloutPercentage: rollout,
+	})
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to create feature flag", err)
+		return
+	}
+
+	resp.Created(w, toFeatureFlagResponseDTO(*flag), "Feature flag created successfully")
+}
+
+// Update replaces the description, state and rollout of a feature flag.
+//
+// PUT /admin/feature-flags/{feature_flag_uuid}
+func (h *FeatureFlagHandler) Update(w http.ResponseWriter, r *http.Request) {
+	flagUUID, err := uuid.Parse(chi.URLParam(r, "feature_flag_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid feature flag UUID")
+		return
+	}
+
+	var req dto.FeatureFlagUpdateRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	rollout := featureFlagDefaultRollout
+	if req.RolloutPercentage != nil {
+		rollout = *req.RolloutPercentage
+	}
+
+	flag, err := h.featureFlagService.Update(r.Context(), flagUUID, service.FeatureFlagServiceInput{
+		Description:       req.Description,
+		Enabled:           req.Enabled,
+		RolloutPercentage: rollout,
+	})
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to update feature flag", err)
+		return
+	}
+
+	resp.Success(w, toFeatureFlagResponseDTO(*flag), "Feature flag updated successfully")
+}
+
+// Delete deletes a feature flag and its overrides.
+//
+// DELETE /admin/feature-flags/{feature_flag_uuid}
+func (h *FeatureFlagHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	flagUUID, err := uuid.Parse(chi.URLParam(r, "feature_flag_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid feature flag UUID")
+		return
+	}
+
+	flag, err := h.featureFlagService.Delete(r.Context(), flagUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to delete feature flag", err)
+		return
+	}
+
+	resp.Success(w, toFeatureFlagResponseDTO(*flag), "Feature flag deleted successfully")
+}
+
+// SetOverride turns a feature flag on or off for a tenant.
+//
+// PUT /admin/feature-flags/{feature_flag_uuid}/tenants/{tenant_uuid}
+func (h *FeatureFlagHandler) SetOverride(w http.ResponseWriter, r *http.Request) {
+	flagUUID, tenantUUID, ok := parseFeatureFlagOverrideParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.FeatureFlagOverrideRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	flag, err := h.featureFlagService.SetOverride(r.Context(), flagUUID, tenantUUID, *req.Enabled)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to set feature flag override", err)
+		return
+	}
+
+	resp.Success(w, toFeatureFlagResponseDTO(*flag), "Feature flag override set successfully")
+}
+
+// DeleteOverride returns a tenant to the rollout of a feature flag.
+//
+// DELETE /admin/feature-flags/{feature_flag_uuid}/tenants/{tenant_uuid}
+func (h *FeatureFlagHandler) DeleteOverride(w http.ResponseWriter, r *http.Request) {
+	flagUUID, tenantUUID, ok := parseFeatureFlagOverrideParams(w, r)
+	if !ok {
+		return
+	}
+
+	flag, err := h.featureFlagService.DeleteOverride(r.Context(), flagUUID, tenantUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to delete feature flag override", err)
+		return
+	}
+
+	resp.Success(w, toFeatureFlagResponseDTO(*flag), "Feature flag override deleted successfully")
+}
+
+func parseFeatureFlagOverrideParams(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	flagUUID, err := uuid.Parse(chi.URLParam(r, "feature_flag_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid feature flag UUID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	tenantUUID, err := uuid.Parse(chi.URLParam(r, "tenant_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid tenant UUID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return flagUUID, tenantUUID, true
+}
+
+func toFeatureFlagResponseDTO(f service.FeatureFlagServiceDataResult) dto.FeatureFlagResponseDTO {
+	overrides := make([]dto.FeatureFlagOverrideResponseDTO, len(f.Overrides))
+	for i, o := range f.Overrides {
+		overrides[i] = dto.FeatureFlagOverrideResponseDTO{
+			TenantID:   o.TenantUUID.String(),
+			TenantName: o.TenantName,
+			Enabled:    o.Enabled,
+		}
+	}
+	return dto.FeatureFlagResponseDTO{
+		FeatureFlagID:     f.FeatureFlagUUID.String(),
+		Key:               f.Key,
+		Description:       f.Description,
+		Enabled:           f.Enabled,
+		RolloutPercentage: f.RolloutPercentage,
+		Overrides:         overrides,
+		CreatedAt:         f.CreatedAt,
+		UpdatedAt:         f.UpdatedAt,
+	}
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatureFlagHandler_List(t *testing.T) {
+	svc := &mockFeatureFlagService{
+		listFn: func() ([]service.FeatureFlagServiceDataResult, error) {
+			return []service.FeatureFlagServiceDataResult{{
+				Key:               "new-flow",
+				Enabled:           true,
+				RolloutPercentage: 25,
+				Overrides:         []service.FeatureFlagServiceOverride{{TenantUUID: testTenantUUID, TenantName: "acme", Enabled: true}},
+			}}, nil
+		},
+	}
+	w := httptest.NewRecorder()
+	NewFeatureFlagHandler(svc).List(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"rollout_percentage":25`)
+	assert.Contains(t, w.Body.String(), `"tenant_name":"acme"`)
+}
+
+func TestFeatureFlagHandler_Get_InvalidUUID(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewFeatureFlagHandler(&mockFeatureFlagService{}).Get(w, withChiParam(httptest.NewRequest(http.MethodGet, "/", nil), "feature_flag_uuid", "bad"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestFeatureFlagHandler_Create(t *testing.T) {
+	t.Run("bad json", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewFeatureFlagHandler(&mockFeatureFlagService{}).Create(w, badJSONReq(t, http.MethodPost, "/"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewFeatureFlagHandler(&mockFeatureFlagService{}).Create(w, jsonReq(t, http.MethodPost, "/", map[string]any{"key": "new-flow", "rollout_percentage": 150}))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("rollout defaults to 100", func(t *testing.T) {
+		svc := &mockFeatureFlagService{
+			createFn: func(key string, input service.FeatureFlagServiceInput) (*service.FeatureFlagServiceDataResult, error) {
+				assert.Equal(t, "new-flow", key)
+				assert.Equal(t, 100, input.RolloutPercentage)
+				return &service.FeatureFlagServiceDataResult{Key: key}, nil
+			},
+		}
+		w := httptest.NewRecorder()
+		NewFeatureFlagHandler(svc).Create(w, jsonReq(t, http.MethodPost, "/", map[string]any{"key": "new-flow", "enabled": true}))
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+}
+
+func TestFeatureFlagHandler_Update(t *testing.T) {
+	svc := &mockFeatureFlagService{
+		updateFn: func(_ uuid.UUID, input service.FeatureFlagServiceInput) (*service.FeatureFlagServiceDataResult, error) {
+			assert.Equal(t, 0, input.RolloutPercentage)
+			return nil, errNotFound
+		},
+	}
+	r := withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{"enabled": true, "rollout_percentage": 0}), "feature_flag_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	NewFeatureFlagHandler(svc).Update(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestFeatureFlagHandler_Delete(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewFeatureFlagHandler(&mockFeatureFlagService{}).Delete(w, withChiParam(httptest.NewRequest(http.MethodDelete, "/", nil), "feature_flag_uuid", testResourceUUID.String()))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestFeatureFlagHandler_SetOverride(t *testing.T) {
+	t.Run("invalid tenant uuid", func(t *testing.T) {
+		r := withChiParam(withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{"enabled": true}), "feature_flag_uuid", testResourceUUID.String()), "tenant_uuid", "bad")
+		w := httptest.NewRecorder()
+		NewFeatureFlagHandler(&mockFeatureFlagService{}).SetOverride(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("enabled is required", func(t *testing.T) {
+		r := withChiParam(withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{}), "feature_flag_uuid", testResourceUUID.String()), "tenant_uuid", testTenantUUID.String())
+		w := httptest.NewRecorder()
+		NewFeatureFlagHandler(&mockFeatureFlagService{}).SetOverride(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		svc := &mockFeatureFlagService{
+			setOverrideFn: func(flagUUID, tenantUUID uuid.UUID, enabled bool) (*service.FeatureFlagServiceDataResult, error) {
+				assert.Equal(t, testTenantUUID, tenantUUID)
+				assert.False(t, enabled)
+				return &service.FeatureFlagServiceDataResult{FeatureFlagUUID: flagUUID}, nil
+			},
+		}
+		r := withChiParam(withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{"enabled": false}), "feature_flag_uuid", testResourceUUID.String()), "tenant_uuid", testTenantUUID.String())
+		w := httptest.NewRecorder()
+		NewFeatureFlagHandler(svc).SetOverride(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestFeatureFlagHandler_DeleteOverride(t *testing.T) {
+	svc := &mockFeatureFlagService{
+		deleteOverrideFn: func(uuid.UUID, uuid.UUID) (*service.FeatureFlagServiceDataResult, error) { return nil, errNotFound },
+	}
+	r := withChiParam(withChiParam(httptest.NewRequest(http.MethodDelete, "/", nil), "feature_flag_uuid", testResourceUUID.String()), "tenant_uuid", testTenantUUID.String())
+	w := httptest.NewRecorder()
+	NewFeatureFlagHandler(svc).DeleteOverride(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/maintainerd/auth/internal/dto"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/security"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// FederatedLoginHandler signs users in with an ID token of the upstream
+// OpenID Connect provider of an auth client.
+type FederatedLoginHandler struct {
+	federatedLoginService service.FederatedLoginService
+}
+
+// NewFederatedLoginHandler creates a new FederatedLoginHandler.
+func NewFederatedLoginHandler(federatedLoginService service.FederatedLoginService) *FederatedLoginHandler {
+	return &FederatedLoginHandler{federatedLoginService: federatedLoginService}
+}
+
+// Login handles POST /login/federated?client_id=…&provider_id=…. The first
+// sign in of an upstream account is provisioned by the identity provider's
+// rules.
+func (h *FederatedLoginHandler) Login(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+	sc := extractSecurityContext(r)
+
+	q := dto.LoginQueryDTO{
+		ClientID:   r.URL.Query().Get("client_id"),
+		ProviderID: r.URL.Query().Get("provider_id"),
+	}
+	if err := q.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	var req dto.FederatedLoginRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	tokenResponse, err := h.federatedLoginService.Login(r.Context(), req.IDToken, q.ClientID, q.ProviderID)
+	if err != nil {
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "login_failure",
+			ClientID:  q.ClientID,
+			ClientIP:  sc.clientIP,
+			UserAgent: sc.userAgent,
+			RequestID: sc.requestID,
+			Endpoint:  "/login/federated",
+			Method:    r.Method,
+			Timestamp: startTime,
+			Details:   "Federated authentication failed",
+			Severity:  "MEDIUM",
+		})
+		resp.HandleServiceError(w, r, "Authentication failed", err)
+		return
+	}
+
+	security.LogSecurityEvent(security.SecurityEvent{
+		EventType: "login_success",
+		ClientID:  q.ClientID,
+		ClientIP:  sc.clientIP,
+		UserAgent: sc.userAgent,
+		RequestID: sc.requestID,
+		Endpoint:  "/login/federated",
+		Method:    r.Method,
+		Timestamp: startTime,
+		Details:   "User successfully authenticated via upstream provider",
+		Severity:  "LOW",
+	})
+
+	// Response with optional cookie delivery based on X-Token-Delivery header
+	resp.SuccessWithCookies(w, r, tokenResponse, "Login successful")
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFederatedLoginHandler_Login(t *testing.T) {
+	const path = "/login/federated?client_id=c1&provider_id=p1"
+
+	t.Run("missing client id", func(t *testing.T) {
+		h := NewFederatedLoginHandler(&mockFederatedLoginService{})
+		w := httptest.NewRecorder()
+		h.Login(w, withSecurityCtx(newLoginRequest(t, http.MethodPost, "/login/federated", map[string]string{"id_token": "t"})))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		h := NewFederatedLoginHandler(&mockFederatedLoginService{})
+		w := httptest.NewRecorder()
+		h.Login(w, withSecurityCtx(httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString("not-json"))))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("missing id token", func(t *testing.T) {
+		h := NewFederatedLoginHandler(&mockFederatedLoginService{})
+		w := httptest.NewRecorder()
+		h.Login(w, withSecurityCtx(newLoginRequest(t, http.MethodPost, path, map[string]string{})))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewFederatedLoginHandler(&mockFederatedLoginService{
+			loginFn: func(string, string, string) (*dto.LoginResponseDTO, error) { return nil, errUnauthorized },
+		})
+		w := httptest.NewRecorder()
+		h.Login(w, withSecurityCtx(newLoginRequest(t, http.MethodPost, path, map[string]string{"id_token": "t"})))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewFederatedLoginHandler(&mockFederatedLoginService{
+			loginFn: func(idToken, clientID, providerID string) (*dto.LoginResponseDTO, error) {
+				assert.Equal(t, "upstream-token", idToken)
+				assert.Equal(t, "c1", clientID)
+				assert.Equal(t, "p1", providerID)
+				return &dto.LoginResponseDTO{AccessToken: "access", TokenType: "Bearer"}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Login(w, withSecurityCtx(newLoginRequest(t, http.MethodPost, path, map[string]string{"id_token": "upstream-token"})))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"access_token":"access"`)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/metrics"
 	resp "github.com/maintainerd/auth/internal/rest/response"
 	"github.com/maintainerd/auth/internal/security"
 	"github.com/maintainerd/auth/internal/service"
@@ -84,6 +85,7 @@ func (h *ForgotPasswordHandler) ForgotPasswordPublic(w http.ResponseWriter, r *h
 
 	// Rate limiting check to prevent abuse
 	if err := security.CheckRateLimit(req.Email); err != nil {
+		metrics.ObserveRateLimitHit("forgot_password")
 		security.LogSecurityEvent(security.SecurityEvent{
 			EventType: "forgot_password_rate_limited",
 			UserID:    req.Email,
@@ -188,6 +190,7 @@ func (h *ForgotPasswordHandler) ForgotPassword(w http.ResponseWriter, r *http.Re
 
 	// Rate limiting check to prevent abuse
 	if err := security.CheckRateLimit(req.Email); err != nil {
+		metrics.ObserveRateLimitHit("forgot_password")
 		security.LogSecurityEvent(security.SecurityEvent{
 			EventType: "forgot_password_rate_limited",
 			UserID:    req.Email,
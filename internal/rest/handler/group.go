@@ -0,0 +1,399 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// GroupHandler manages user groups, their members and the roles attached to
+// them. Members hold the group's roles without a role assignment of their
+// own.
+type GroupHandler struct {
+	groupService service.GroupService
+}
+
+func NewGroupHandler(groupService service.GroupService) *GroupHandler {
+	return &GroupHandler{
+		groupService: groupService,
+	}
+}
+
+// Get lists groups with filtering and pagination.
+func (h *GroupHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	query := r.URL.Query()
+
+	var filter dto.GroupFilterDTO
+	if name := query.Get("name"); name != "" {
+		filter.Name = &name
+	}
+	if description := query.Get("description"); description != "" {
+		filter.Description = &description
+	}
+	if userID := query.Get("user_id"); userID != "" {
+		userUUID, err := uuid.Parse(userID)
+		if err != nil {
+			resp.Error(w, http.StatusBadRequest, "Invalid user UUID")
+			return
+		}
+		filter.UserUUID = &userUUID
+	}
+
+	page, _ := strconv.Atoi(query.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	filter.Page = page
+	filter.Limit = limit
+	filter.SortBy = query.Get("sort_by")
+	filter.SortOrder = query.Get("sort_order")
+
+	if err := filter.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.groupService.Get(r.Context(), service.GroupServiceGetFilter{
+		TenantID:    tenant.TenantID,
+		Name:        filter.Name,
+		Description: filter.Description,
+		UserUUID:    filter.UserUUID,
+		Page:        filter.Page,
+		Limit:       filter.Limit,
+		SortBy:      filter.SortBy,
+		SortOrder:   filter.SortOrder,
+	})
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get groups", err)
+		return
+	}
+
+	rows := make([]dto.GroupResponseDTO, len(result.Data))
+	for i, group := range result.Data {
+		rows[i] = toGroupResponseDTO(group)
+	}
+
+	response := dto.PaginatedResponseDTO[dto.GroupResponseDTO]{
+		Rows:       rows,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}
+
+	resp.Success(w, response, "Groups retrieved successfully")
+}
+
+// GetByUUID returns a group with its roles.
+func (h *GroupHandler) GetByUUID(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	groupUUID, err := uuid.Parse(chi.URLParam(r, "group_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid group UUID")
+		return
+	}
+
+	group, err := h.groupService.GetByUUID(r.Context(), groupUUID, tenant.TenantID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Group not found", err)
+		return
+	}
+
+	resp.Success(w, toGroupResponseDTO(*group), "Group retrieved successfully")
+}
+
+// Create creates an empty group.
+func (h *GroupHandler) Create(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	var req dto.GroupCreateRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	group, err := h.groupService.Create(r.Context(), tenant.TenantID, req.Name, req.Description)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to create group", err)
+		return
+	}
+
+	resp.Created(w, toGroupResponseDTO(*group), "Group created successfully")
+}
+
+// Update renames or re-describes a group.
+func (h *GroupHandler) Update(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	groupUUID, err := uuid.Parse(chi.URLParam(r, "group_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid group UUID")
+		return
+	}
+
+	var req dto.GroupUpdateRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	group, err := h.groupService.Update(r.Context(), groupUUID, tenant.TenantID, req.Name, req.Description)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to update group", err)
+		return
+	}
+
+	resp.Success(w, toGroupResponseDTO(*group), "Group updated successfully")
+}
+
+// Delete deletes a group. Its members lose the roles they held through it.
+func (h *GroupHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	groupUUID, err := uuid.Parse(chi.URLParam(r, "group_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid group UUID")
+		return
+	}
+
+	group, err := h.groupService.DeleteByUUID(r.Context(), groupUUID, tenant.TenantID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to delete group", err)
+		return
+	}
+
+	resp.Success(w, toGroupResponseDTO(*group), "Group deleted successfully")
+}
+
+// GetMembers lists the members of a group.
+func (h *GroupHandler) GetMembers(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	groupUUID, err := uuid.Parse(chi.URLParam(r, "group_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid group UUID")
+		return
+	}
+
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	result, err := h.groupService.GetMembers(r.Context(), groupUUID, tenant.TenantID, page, limit)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get group members", err)
+		return
+	}
+
+	rows := make([]dto.UserResponseDTO, len(result.Data))
+	for i, user := range result.Data {
+		rows[i] = toUserResponseDTO(user)
+	}
+
+	response := dto.PaginatedResponseDTO[dto.UserResponseDTO]{
+		Rows:       rows,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}
+
+	resp.Success(w, response, "Group members retrieved successfully")
+}
+
+// AddMembers adds users to a group. Users who are already members are
+// skipped.
+func (h *GroupHandler) AddMembers(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	groupUUID, err := uuid.Parse(chi.URLParam(r, "group_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid group UUID")
+		return
+	}
+
+	var req dto.GroupAddMembersRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	group, err := h.groupService.AddMembers(r.Context(), groupUUID, tenant.TenantID, req.Users)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to add members to group", err)
+		return
+	}
+
+	resp.Success(w, toGroupResponseDTO(*group), "Members added to group successfully")
+}
+
+// RemoveMember removes a user from a group.
+func (h *GroupHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	groupUUID, err := uuid.Parse(chi.URLParam(r, "group_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid group UUID")
+		return
+	}
+
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid user UUID")
+		return
+	}
+
+	group, err := h.groupService.RemoveMember(r.Context(), groupUUID, tenant.TenantID, userUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to remove member from group", err)
+		return
+	}
+
+	resp.Success(w, toGroupResponseDTO(*group), "Member removed from group successfully")
+}
+
+// AddRoles attaches roles to a group.
+func (h *GroupHandler) AddRoles(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	groupUUID, err := uuid.Parse(chi.URLParam(r, "group_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid group UUID")
+		return
+	}
+
+	var req dto.GroupAddRolesRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	group, err := h.groupService.AddRoles(r.Context(), groupUUID, tenant.TenantID, req.Roles)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to add roles to group", err)
+		return
+	}
+
+	resp.Success(w, toGroupResponseDTO(*group), "Roles added to group successfully")
+}
+
+// RemoveRole detaches a role from a group.
+func (h *GroupHandler) RemoveRole(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	groupUUID, err := uuid.Parse(chi.URLParam(r, "group_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid group UUID")
+		return
+	}
+
+	roleUUID, err := uuid.Parse(chi.URLParam(r, "role_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid role UUID")
+		return
+	}
+
+	group, err := h.groupService.RemoveRole(r.Context(), groupUUID, tenant.TenantID, roleUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to remove role from group", err)
+		return
+	}
+
+	resp.Success(w, toGroupResponseDTO(*group), "Role removed from group successfully")
+}
+
+func toGroupResponseDTO(g service.GroupServiceDataResult) dto.GroupResponseDTO {
+	roles := make([]dto.RoleResponseDTO, len(g.Roles))
+	for i, role := range g.Roles {
+		roles[i] = toRoleResponseDTO(role)
+	}
+	return dto.GroupResponseDTO{
+		GroupUUID:   g.GroupUUID,
+		Name:        g.Name,
+		Description: g.Description,
+		Roles:       roles,
+		CreatedAt:   g.CreatedAt,
+		UpdatedAt:   g.UpdatedAt,
+	}
+}
@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// Get / GetByUUID
+// ---------------------------------------------------------------------------
+
+func TestGroupHandler_Get(t *testing.T) {
+	t.Run("no tenant", func(t *testing.T) {
+		h := NewGroupHandler(&mockGroupService{})
+		w := httptest.NewRecorder()
+		h.Get(w, httptest.NewRequest(http.MethodGet, "/groups", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid user uuid", func(t *testing.T) {
+		h := NewGroupHandler(&mockGroupService{})
+		w := httptest.NewRecorder()
+		h.Get(w, withTenant(httptest.NewRequest(http.MethodGet, "/groups?user_id=bad", nil)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		userUUID := uuid.New()
+		var got service.GroupServiceGetFilter
+		h := NewGroupHandler(&mockGroupService{
+			getFn: func(f service.GroupServiceGetFilter) (*service.GroupServiceGetResult, error) {
+				got = f
+				return &service.GroupServiceGetResult{
+					Data: []service.GroupServiceDataResult{{
+						Name:  "support",
+						Roles: []service.RoleServiceDataResult{{Name: "agent"}},
+					}},
+					Total: 1, Page: 1, Limit: 10, TotalPages: 1,
+				}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Get(w, withTenant(httptest.NewRequest(http.MethodGet, "/groups?name=sup&user_id="+userUUID.String(), nil)))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "support")
+		assert.Contains(t, w.Body.String(), "agent")
+		assert.Equal(t, tenantID, got.TenantID)
+		require.NotNil(t, got.Name)
+		assert.Equal(t, "sup", *got.Name)
+		require.NotNil(t, got.UserUUID)
+		assert.Equal(t, userUUID, *got.UserUUID)
+	})
+}
+
+func TestGroupHandler_GetByUUID(t *testing.T) {
+	t.Run("invalid uuid", func(t *testing.T) {
+		h := NewGroupHandler(&mockGroupService{})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenant(httptest.NewRequest(http.MethodGet, "/", nil)), "group_uuid", "bad")
+		h.GetByUUID(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		h := NewGroupHandler(&mockGroupService{
+			getByUUIDFn: func(uuid.UUID, int64) (*service.GroupServiceDataResult, error) {
+				return nil, errNotFound
+			},
+		})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenant(httptest.NewRequest(http.MethodGet, "/", nil)), "group_uuid", testResourceUUID.String())
+		h.GetByUUID(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Create / Update / Delete
+// ---------------------------------------------------------------------------
+
+func TestGroupHandler_Create(t *testing.T) {
+	t.Run("bad json", func(t *testing.T) {
+		h := NewGroupHandler(&mockGroupService{})
+		w := httptest.NewRecorder()
+		h.Create(w, withTenant(badJSONReq(t, http.MethodPost, "/groups")))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		h := NewGroupHandler(&mockGroupService{})
+		w := httptest.NewRecorder()
+		h.Create(w, withTenant(jsonReq(t, http.MethodPost, "/groups", dto.GroupCreateRequestDTO{Name: "x"})))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewGroupHandler(&mockGroupService{
+			createFn: func(tid int64, name, description string) (*service.GroupServiceDataResult, error) {
+				assert.Equal(t, tenantID, tid)
+				return &service.GroupServiceDataResult{Name: name, Description: description}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Create(w, withTenant(jsonReq(t, http.MethodPost, "/groups", dto.GroupCreateRequestDTO{Name: "support"})))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), "support")
+	})
+}
+
+func TestGroupHandler_Update(t *testing.T) {
+	h := NewGroupHandler(&mockGroupService{
+		updateFn: func(id uuid.UUID, _ int64, name, _ string) (*service.GroupServiceDataResult, error) {
+			assert.Equal(t, testResourceUUID, id)
+			return &service.GroupServiceDataResult{GroupUUID: id, Name: name}, nil
+		},
+	})
+	w := httptest.NewRecorder()
+	r := withChiParam(withTenant(jsonReq(t, http.MethodPut, "/", dto.GroupUpdateRequestDTO{Name: "engineering"})), "group_uuid", testResourceUUID.String())
+	h.Update(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "engineering")
+}
+
+func TestGroupHandler_Delete(t *testing.T) {
+	h := NewGroupHandler(&mockGroupService{
+		deleteFn: func(uuid.UUID, int64) (*service.GroupServiceDataResult, error) {
+			return nil, errNotFound
+		},
+	})
+	w := httptest.NewRecorder()
+	r := withChiParam(withTenant(httptest.NewRequest(http.MethodDelete, "/", nil)), "group_uuid", testResourceUUID.String())
+	h.Delete(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// ---------------------------------------------------------------------------
+// Members
+// ---------------------------------------------------------------------------
+
+func TestGroupHandler_GetMembers(t *testing.T) {
+	h := NewGroupHandler(&mockGroupService{
+		getMembersFn: func(id uuid.UUID, tid int64, page, limit int) (*service.UserServiceGetResult, error) {
+			assert.Equal(t, testResourceUUID, id)
+			assert.Equal(t, 2, page)
+			assert.Equal(t, 10, limit, "out of range limits fall back to the default")
+			return &service.UserServiceGetResult{
+				Data:  []service.UserServiceDataResult{{Username: "alice"}},
+				Total: 11, Page: 2, Limit: 10, TotalPages: 2,
+			}, nil
+		},
+	})
+	w := httptest.NewRecorder()
+	r := withChiParam(withTenant(httptest.NewRequest(http.MethodGet, "/?page=2&limit=500", nil)), "group_uuid", testResourceUUID.String())
+	h.GetMembers(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "alice")
+}
+
+func TestGroupHandler_AddMembers(t *testing.T) {
+	t.Run("empty users", func(t *testing.T) {
+		h := NewGroupHandler(&mockGroupService{})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenant(jsonReq(t, http.MethodPost, "/", dto.GroupAddMembersRequestDTO{})), "group_uuid", testResourceUUID.String())
+		h.AddMembers(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		userUUID := uuid.New()
+		h := NewGroupHandler(&mockGroupService{
+			addMembersFn: func(_ uuid.UUID, _ int64, users []uuid.UUID) (*service.GroupServiceDataResult, error) {
+				assert.Equal(t, []uuid.UUID{userUUID}, users)
+				return &service.GroupServiceDataResult{Name: "support"}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenant(jsonReq(t, http.MethodPost, "/", dto.GroupAddMembersRequestDTO{
+			Users: []uuid.UUID{userUUID},
+		})), "group_uuid", testResourceUUID.String())
+		h.AddMembers(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestGroupHandler_RemoveMember(t *testing.T) {
+	t.Run("invalid user uuid", func(t *testing.T) {
+		h := NewGroupHandler(&mockGroupService{})
+		w := httptest.NewRecorder()
+		r := withTenant(httptest.NewRequest(http.MethodDelete, "/", nil))
+		r = withChiParam(r, "group_uuid", testResourceUUID.String())
+		r = withChiParam(r, "user_uuid", "bad")
+		h.RemoveMember(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		h := NewGroupHandler(&mockGroupService{
+			removeMemberFn: func(uuid.UUID, int64, uuid.UUID) (*service.GroupServiceDataResult, error) {
+				return nil, errNotFound
+			},
+		})
+		w := httptest.NewRecorder()
+		r := withTenant(httptest.NewRequest(http.MethodDelete, "/", nil))
+		r = withChiParam(r, "group_uuid", testResourceUUID.String())
+		r = withChiParam(r, "user_uuid", uuid.NewString())
+		h.RemoveMember(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Roles
+// ---------------------------------------------------------------------------
+
+func TestGroupHandler_AddRoles(t *testing.T) {
+	t.Run("empty roles", func(t *testing.T) {
+		h := NewGroupHandler(&mockGroupService{})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenant(jsonReq(t, http.MethodPost, "/", dto.GroupAddRolesRequestDTO{})), "group_uuid", testResourceUUID.String())
+		h.AddRoles(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		roleUUID := uuid.New()
+		h := NewGroupHandler(&mockGroupService{
+			addRolesFn: func(_ uuid.UUID, _ int64, roles []uuid.UUID) (*service.GroupServiceDataResult, error) {
+				return &service.GroupServiceDataResult{
+					Roles: []service.RoleServiceDataResult{{RoleUUID: roles[0], Name: "agent"}},
+				}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenant(jsonReq(t, http.MethodPost, "/", dto.GroupAddRolesRequestDTO{
+			Roles: []uuid.UUID{roleUUID},
+		})), "group_uuid", testResourceUUID.String())
+		h.AddRoles(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "agent")
+	})
+}
+
+func TestGroupHandler_RemoveRole(t *testing.T) {
+	t.Run("invalid role uuid", func(t *testing.T) {
+		h := NewGroupHandler(&mockGroupService{})
+		w := httptest.NewRecorder()
+		r := withTenant(httptest.NewRequest(http.MethodDelete, "/", nil))
+		r = withChiParam(r, "group_uuid", testResourceUUID.String())
+		r = withChiParam(r, "role_uuid", "bad")
+		h.RemoveRole(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		roleUUID := uuid.New()
+		h := NewGroupHandler(&mockGroupService{
+			removeRoleFn: func(_ uuid.UUID, _ int64, role uuid.UUID) (*service.GroupServiceDataResult, error) {
+				assert.Equal(t, roleUUID, role)
+				return &service.GroupServiceDataResult{}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		r := withTenant(httptest.NewRequest(http.MethodDelete, "/", nil))
+		r = withChiParam(r, "group_uuid", testResourceUUID.String())
+		r = withChiParam(r, "role_uuid", roleUUID.String())
+		h.RemoveRole(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/maintainerd/auth/internal/dto"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// HomeRealmHandler tells the login page which identity provider a user signs
+// in with.
+type HomeRealmHandler struct {
+	homeRealmService service.HomeRealmService
+}
+
+// NewHomeRealmHandler creates a new HomeRealmHandler.
+func NewHomeRealmHandler(homeRealmService service.HomeRealmService) *HomeRealmHandler {
+	return &HomeRealmHandler{homeRealmService: homeRealmService}
+}
+
+// Identify handles POST /login/identify?client_id=…&provider_id=…. It returns
+// the identity provider and login method for the email's domain.
+func (h *HomeRealmHandler) Identify(w http.ResponseWriter, r *http.Request) {
+	q := dto.LoginQueryDTO{
+		ClientID:   r.URL.Query().Get("client_id"),
+		ProviderID: r.URL.Query().Get("provider_id"),
+	}
+	if err := q.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	var req dto.HomeRealmRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.homeRealmService.Identify(r.Context(), req.Email, q.ClientID, q.ProviderID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to identify login method", err)
+		return
+	}
+
+	resp.Success(w, result, "Login method identified")
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHomeRealmHandler_Identify(t *testing.T) {
+	const path = "/login/identify?client_id=c1&provider_id=p1"
+
+	t.Run("missing client id", func(t *testing.T) {
+		h := NewHomeRealmHandler(&mockHomeRealmService{})
+		w := httptest.NewRecorder()
+		h.Identify(w, newLoginRequest(t, http.MethodPost, "/login/identify", map[string]string{"email": "jane@example.com"}))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		h := NewHomeRealmHandler(&mockHomeRealmService{})
+		w := httptest.NewRecorder()
+		h.Identify(w, httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString("not-json")))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid email", func(t *testing.T) {
+		h := NewHomeRealmHandler(&mockHomeRealmService{})
+		w := httptest.NewRecorder()
+		h.Identify(w, newLoginRequest(t, http.MethodPost, path, map[string]string{"email": "jane"}))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewHomeRealmHandler(&mockHomeRealmService{
+			identifyFn: func(string, string, string) (*dto.HomeRealmResponseDTO, error) { return nil, errUnauthorized },
+		})
+		w := httptest.NewRecorder()
+		h.Identify(w, newLoginRequest(t, http.MethodPost, path, map[string]string{"email": "jane@example.com"}))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewHomeRealmHandler(&mockHomeRealmService{
+			identifyFn: func(email, clientID, providerID string) (*dto.HomeRealmResponseDTO, error) {
+				assert.Equal(t, "jane@example.com", email)
+				assert.Equal(t, "c1", clientID)
+				assert.Equal(t, "p1", providerID)
+				return &dto.HomeRealmResponseDTO{Method: dto.LoginMethodFederated, ProviderID: "idp-google"}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Identify(w, newLoginRequest(t, http.MethodPost, path, map[string]string{"email": "jane@example.com"}))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"method":"federated"`)
+	})
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -54,17 +53,9 @@ func (h *IdentityProviderHandler) Get(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Parse status (comma-separated values)
-	var status []string
-	if statusParam := q.Get("status"); statusParam != "" {
-		status = strings.Split(strings.ReplaceAll(statusParam, " ", ""), ",")
-	}
-
-	// Parse provider (comma-separated values)
-	var provider []string
-	if providerParam := q.Get("provider"); providerParam != "" {
-		provider = strings.Split(strings.ReplaceAll(providerParam, " ", ""), ",")
-	}
+	// Parse status and provider (comma-separated or repeated values)
+	status := queryValues(q, "status")
+	provider := queryValues(q, "provider")
 
 	// Build request DTO
 	reqParams := dto.IdentityProviderFilterDTO{
@@ -135,6 +126,10 @@ func (h *IdentityProviderHandler) Get(w http.ResponseWriter, r *http.Request) {
 func (h *IdentityProviderHandler) GetByUUID(w http.ResponseWriter, r *http.Request) {
 	// Get tenant context
 	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
 
 	idpUUID, err := uuid.Parse(chi.URLParam(r, "identity_provider_uuid"))
 	if err != nil {
@@ -158,6 +153,14 @@ func (h *IdentityProviderHandler) Create(w http.ResponseWriter, r *http.Request)
 	// Get authentication context
 	tenant := middleware.AuthFromRequest(r).Tenant
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
 
 	var req dto.IdentityProviderCreateRequestDTO
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -186,6 +189,14 @@ func (h *IdentityProviderHandler) Update(w http.ResponseWriter, r *http.Request)
 	// Get authentication context
 	tenant := middleware.AuthFromRequest(r).Tenant
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
 
 	idpUUID, err := uuid.Parse(chi.URLParam(r, "identity_provider_uuid"))
 	if err != nil {
@@ -220,6 +231,14 @@ func (h *IdentityProviderHandler) SetStatus(w http.ResponseWriter, r *http.Reque
 	// Get authentication context
 	tenant := middleware.AuthFromRequest(r).Tenant
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
 
 	idpUUID, err := uuid.Parse(chi.URLParam(r, "identity_provider_uuid"))
 	if err != nil {
@@ -255,6 +274,14 @@ func (h *IdentityProviderHandler) Delete(w http.ResponseWriter, r *http.Request)
 	// Get authentication context
 	tenant := middleware.AuthFromRequest(r).Tenant
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
 
 	idpUUID, err := uuid.Parse(chi.URLParam(r, "identity_provider_uuid"))
 	if err != nil {
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// ImpersonationHandler handles HTTP requests for admin impersonation.
+type ImpersonationHandler struct {
+	impersonationService service.ImpersonationService
+}
+
+// NewImpersonationHandler creates a new ImpersonationHandler.
+func NewImpersonationHandler(impersonationService service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{impersonationService: impersonationService}
+}
+
+// Impersonate issues a short-lived access token acting as the user.
+//
+// POST /users/{user_uuid}/impersonate
+func (h *ImpersonationHandler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	claims := middleware.JWTClaimsFromRequest(r)
+	if auth.Tenant == nil || auth.User == nil || claims == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid user UUID")
+		return
+	}
+
+	var req dto.ImpersonateRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.impersonationService.Impersonate(r.Context(), service.ImpersonationInput{
+		TenantID:       auth.Tenant.TenantID,
+		Actor:          auth.User,
+		ActorSub:       claims.Sub,
+		ActorActingFor: claims.Actor,
+		ClientID:       claims.ClientID,
+		ProviderID:     claims.ProviderID,
+		TargetUUID:     userUUID,
+		Reason:         req.Reason,
+	})
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to impersonate user", err)
+		return
+	}
+
+	resp.Created(w, result, "Impersonation token issued")
+}
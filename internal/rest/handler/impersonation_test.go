@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// impersonateReq builds an authenticated impersonation request for the test
+// resource user.
+func impersonateReq(t *testing.T, body any, actor string) *http.Request {
+	t.Helper()
+	r := jsonReq(t, http.MethodPost, "/users/"+testResourceUUID.String()+"/impersonate", body)
+	r = withTenantAndUser(r)
+	r = middleware.WithJWTClaims(r, &middleware.JWTClaims{Sub: "admin-sub", Actor: actor, ClientID: "admin-console", ProviderID: "default"})
+	return withChiParam(r, "user_uuid", testResourceUUID.String())
+}
+
+func TestImpersonationHandler_Impersonate_NoAuth(t *testing.T) {
+	h := NewImpersonationHandler(&mockImpersonationService{})
+	w := httptest.NewRecorder()
+	h.Impersonate(w, httptest.NewRequest(http.MethodPost, "/users/x/impersonate", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestImpersonationHandler_Impersonate_InvalidUUID(t *testing.T) {
+	h := NewImpersonationHandler(&mockImpersonationService{})
+	r := jsonReq(t, http.MethodPost, "/users/bad/impersonate", map[string]any{"reason": "support ticket 42"})
+	r = middleware.WithJWTClaims(withTenantAndUser(r), &middleware.JWTClaims{Sub: "admin-sub"})
+	w := httptest.NewRecorder()
+	h.Impersonate(w, withChiParam(r, "user_uuid", "bad"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestImpersonationHandler_Impersonate_ReasonRequired(t *testing.T) {
+	h := NewImpersonationHandler(&mockImpersonationService{
+		impersonateFn: func(service.ImpersonationInput) (*dto.ImpersonationResponseDTO, error) {
+			t.Fatal("service should not be called")
+			return nil, nil
+		},
+	})
+	w := httptest.NewRecorder()
+	h.Impersonate(w, impersonateReq(t, map[string]any{}, ""))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestImpersonationHandler_Impersonate_ServiceError(t *testing.T) {
+	h := NewImpersonationHandler(&mockImpersonationService{
+		impersonateFn: func(service.ImpersonationInput) (*dto.ImpersonationResponseDTO, error) {
+			return nil, errForbidden
+		},
+	})
+	w := httptest.NewRecorder()
+	h.Impersonate(w, impersonateReq(t, map[string]any{"reason": "support ticket 42"}, "other-admin"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestImpersonationHandler_Impersonate_Success(t *testing.T) {
+	var got service.ImpersonationInput
+	h := NewImpersonationHandler(&mockImpersonationService{
+		impersonateFn: func(in service.ImpersonationInput) (*dto.ImpersonationResponseDTO, error) {
+			got = in
+			return &dto.ImpersonationResponseDTO{AccessToken: "tok", TokenType: "Bearer", ExpiresIn: 300}, nil
+		},
+	})
+	w := httptest.NewRecorder()
+	h.Impersonate(w, impersonateReq(t, map[string]any{"reason": "support ticket 42"}, ""))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"access_token":"tok"`)
+	assert.Equal(t, tenantID, got.TenantID)
+	assert.Equal(t, testUserUUID, got.Actor.UserUUID)
+	assert.Equal(t, "admin-sub", got.ActorSub)
+	assert.Empty(t, got.ActorActingFor)
+	assert.Equal(t, "admin-console", got.ClientID)
+	assert.Equal(t, "default", got.ProviderID)
+	assert.Equal(t, testResourceUUID, got.TargetUUID)
+	assert.Equal(t, "support ticket 42", got.Reason)
+}
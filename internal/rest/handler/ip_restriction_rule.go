@@ -47,11 +47,8 @@ func (h *IPRestrictionRuleHandler) GetAll(w http.ResponseWriter, r *http.Request
 	page, _ := strconv.Atoi(q.Get("page"))
 	limit, _ := strconv.Atoi(q.Get("limit"))
 
-	// Parse status filter (can be multiple)
-	var status []string
-	if v := q.Get("status"); v != "" {
-		status = append(status, v)
-	}
+	// Parse status filter (comma-separated or repeated values)
+	status := queryValues(q, "status")
 
 	// Build filter DTO with all query parameters
 	filter := dto.IPRestrictionRuleFilterDTO{
@@ -336,6 +333,8 @@ func toIPRestrictionRuleResponseDTO(rule service.IPRestrictionRuleServiceDataRes
 		Type:                rule.Type,
 		IPAddress:           rule.IPAddress,
 		Status:              rule.Status,
+		Source:              rule.Source,
+		ExpiresAt:           rule.ExpiresAt,
 		CreatedAt:           rule.CreatedAt,
 		UpdatedAt:           rule.UpdatedAt,
 	}
@@ -131,7 +131,7 @@ func (h *LoginHandler) LoginPublic(w http.ResponseWriter, r *http.Request) {
 
 	// Public login attempt (requires client_id and provider_id)
 	tokenResponse, err := h.loginService.LoginPublic(
-		r.Context(), req.Username, req.Password, q.ClientID, q.ProviderID,
+		r.Context(), req.Username, req.Password, q.ClientID, q.ProviderID, req.AcceptedPolicies,
 	)
 	if err != nil {
 		security.LogSecurityEvent(security.SecurityEvent{
@@ -236,7 +236,7 @@ func (h *LoginHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 	// Internal login attempt (client_id/provider_id optional)
 	tokenResponse, err := h.loginService.Login(
-		r.Context(), req.Username, req.Password, clientIDPtr, providerIDPtr,
+		r.Context(), req.Username, req.Password, clientIDPtr, providerIDPtr, req.AcceptedPolicies,
 	)
 	if err != nil {
 		security.LogSecurityEvent(security.SecurityEvent{
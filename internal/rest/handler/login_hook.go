@@ -0,0 +1,280 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// LoginHookHandler handles HTTP requests for login hook management.
+type LoginHookHandler struct {
+	loginHookService service.LoginHookService
+}
+
+// NewLoginHookHandler creates a new LoginHookHandler.
+func NewLoginHookHandler(loginHookService service.LoginHookService) *LoginHookHandler {
+	return &LoginHookHandler{loginHookService: loginHookService}
+}
+
+// GetAll retrieves all login hooks for the tenant with optional filtering and pagination.
+//
+// GET /login-hooks
+func (h *LoginHookHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	q := r.URL.Query()
+
+	page, _ := strconv.Atoi(q.Get("page"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+
+	filter := dto.LoginHookFilterDTO{
+		Trigger: queryValues(q, "trigger"),
+		Status:  queryValues(q, "status"),
+		PaginationRequestDTO: dto.PaginationRequestDTO{
+			Page:      page,
+			Limit:     limit,
+			SortBy:    q.Get("sort_by"),
+			SortOrder: q.Get("sort_order"),
+		},
+	}
+
+	if err := filter.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.loginHookService.GetAll(
+		r.Context(), tenant.TenantID,
+		filter.Trigger, filter.Status,
+		filter.Page, filter.Limit,
+		filter.SortBy, filter.SortOrder,
+	)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get login hooks", err)
+		return
+	}
+
+	response := dto.PaginatedResponseDTO[dto.LoginHookResponseDTO]{
+		Rows:       toLoginHookResponseDTOList(result.Data),
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}
+
+	resp.Success(w, response, "Login hooks retrieved successfully")
+}
+
+// Get retrieves a specific login hook by UUID.
+//
+// GET /login-hooks/{login_hook_uuid}
+func (h *LoginHookHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	hookUUID, err := uuid.Parse(chi.URLParam(r, "login_hook_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid login hook UUID")
+		return
+	}
+
+	result, err := h.loginHookService.GetByUUID(r.Context(), tenant.TenantID, hookUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Login hook not found", err)
+		return
+	}
+
+	resp.Success(w, toLoginHookResponseDTO(*result), "Login hook retrieved successfully")
+}
+
+// Create creates a new login hook for the tenant.
+//
+// POST /login-hooks
+func (h *LoginHookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	var req dto.LoginHookRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.loginHookService.Create(r.Context(), tenant.TenantID, toLoginHookInput(req))
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to create login hook", err)
+		return
+	}
+
+	resp.Created(w, toLoginHookResponseDTO(*result), "Login hook created successfully")
+}
+
+// Update replaces an existing login hook.
+//
+// PUT /login-hooks/{login_hook_uuid}
+func (h *LoginHookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	hookUUID, err := uuid.Parse(chi.URLParam(r, "login_hook_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid login hook UUID")
+		return
+	}
+
+	var req dto.LoginHookRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.loginHookService.Update(r.Context(), tenant.TenantID, hookUUID, toLoginHookInput(req))
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to update login hook", err)
+		return
+	}
+
+	resp.Success(w, toLoginHookResponseDTO(*result), "Login hook updated successfully")
+}
+
+// Delete deletes a login hook.
+//
+// DELETE /login-hooks/{login_hook_uuid}
+func (h *LoginHookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	hookUUID, err := uuid.Parse(chi.URLParam(r, "login_hook_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid login hook UUID")
+		return
+	}
+
+	result, err := h.loginHookService.Delete(r.Context(), tenant.TenantID, hookUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to delete login hook", err)
+		return
+	}
+
+	resp.Success(w, toLoginHookResponseDTO(*result), "Login hook deleted successfully")
+}
+
+// UpdateStatus enables or disables a login hook.
+//
+// PATCH /login-hooks/{login_hook_uuid}/status
+func (h *LoginHookHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	hookUUID, err := uuid.Parse(chi.URLParam(r, "login_hook_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid login hook UUID")
+		return
+	}
+
+	var req dto.LoginHookUpdateStatusRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.loginHookService.UpdateStatus(r.Context(), tenant.TenantID, hookUUID, req.Status)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to update login hook status", err)
+		return
+	}
+
+	resp.Success(w, toLoginHookResponseDTO(*result), "Login hook status updated successfully")
+}
+
+func toLoginHookInput(req dto.LoginHookRequestDTO) service.LoginHookInput {
+	failurePolicy := model.LoginHookFailClosed
+	if req.FailurePolicy != nil {
+		failurePolicy = *req.FailurePolicy
+	}
+	status := model.StatusActive
+	if req.Status != nil {
+		status = *req.Status
+	}
+
+	return service.LoginHookInput{
+		Name:          req.Name,
+		Trigger:       req.Trigger,
+		Type:          req.Type,
+		URL:           req.URL,
+		Secret:        req.Secret,
+		Rule:          req.Rule,
+		TimeoutMs:     req.TimeoutMs,
+		FailurePolicy: failurePolicy,
+		Priority:      req.Priority,
+		Status:        status,
+	}
+}
+
+func toLoginHookResponseDTO(lh service.LoginHookServiceDataResult) dto.LoginHookResponseDTO {
+	return dto.LoginHookResponseDTO{
+		LoginHookID:   lh.LoginHookUUID.String(),
+		Name:          lh.Name,
+		Trigger:       lh.Trigger,
+		Type:          lh.Type,
+		URL:           lh.URL,
+		HasSecret:     lh.HasSecret,
+		Rule:          lh.Rule,
+		TimeoutMs:     lh.TimeoutMs,
+		FailurePolicy: lh.FailurePolicy,
+		Priority:      lh.Priority,
+		Status:        lh.Status,
+		CreatedAt:     lh.CreatedAt,
+		UpdatedAt:     lh.UpdatedAt,
+	}
+}
+
+func toLoginHookResponseDTOList(hooks []service.LoginHookServiceDataResult) []dto.LoginHookResponseDTO {
+	result := make([]dto.LoginHookResponseDTO, len(hooks))
+	for i, lh := range hooks {
+		result[i] = toLoginHookResponseDTO(lh)
+	}
+	return result
+}
@@ -0,0 +1,398 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func loginHookResult() *service.LoginHookServiceDataResult {
+	return &service.LoginHookServiceDataResult{
+		LoginHookUUID: uuid.New(),
+		Name:          "Risk check",
+		Trigger:       model.LoginHookTriggerPreLogin,
+		Type:          model.LoginHookTypeWebhook,
+		URL:           "https://hooks.example.com/login",
+		HasSecret:     true,
+		TimeoutMs:     2000,
+		FailurePolicy: model.LoginHookFailClosed,
+		Status:        model.StatusActive,
+	}
+}
+
+func loginHookBody() map[string]any {
+	return map[string]any{
+		"name":    "Risk check",
+		"trigger": model.LoginHookTriggerPreLogin,
+		"type":    model.LoginHookTypeWebhook,
+		"url":     "https://hooks.example.com/login",
+		"secret":  "s3cret",
+	}
+}
+
+// ---------------------------------------------------------------------------
+// GetAll
+// ---------------------------------------------------------------------------
+
+func TestLoginHookHandler_GetAll_NoTenant(t *testing.T) {
+	h := NewLoginHookHandler(&mockLoginHookService{})
+	w := httptest.NewRecorder()
+	h.GetAll(w, httptest.NewRequest(http.MethodGet, "/login-hooks", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestLoginHookHandler_GetAll_ValidationError(t *testing.T) {
+	h := NewLoginHookHandler(&mockLoginHookService{})
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/login-hooks?page=1&limit=10&trigger=sometimes", nil))
+	w := httptest.NewRecorder()
+	h.GetAll(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLoginHookHandler_GetAll_ServiceError(t *testing.T) {
+	svc := &mockLoginHookService{
+		getAllFn: func(_ int64, _, _ []string, _, _ int, _, _ string) (*service.LoginHookServiceListResult, error) {
+			return nil, assert.AnError
+		},
+	}
+	h := NewLoginHookHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/login-hooks?page=1&limit=10", nil))
+	w := httptest.NewRecorder()
+	h.GetAll(w, r)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestLoginHookHandler_GetAll_Success(t *testing.T) {
+	svc := &mockLoginHookService{
+		getAllFn: func(_ int64, trigger, _ []string, _, _ int, _, _ string) (*service.LoginHookServiceListResult, error) {
+			assert.Equal(t, []string{model.LoginHookTriggerPostLogin}, trigger)
+			return &service.LoginHookServiceListResult{
+				Data:       []service.LoginHookServiceDataResult{*loginHookResult()},
+				Total:      1,
+				Page:       1,
+				Limit:      10,
+				TotalPages: 1,
+			}, nil
+		},
+	}
+	h := NewLoginHookHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/login-hooks?page=1&limit=10&trigger=post_login", nil))
+	w := httptest.NewRecorder()
+	h.GetAll(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "s3cret")
+	assert.Contains(t, w.Body.String(), `"has_secret":true`)
+}
+
+// ---------------------------------------------------------------------------
+// Get
+// ---------------------------------------------------------------------------
+
+func TestLoginHookHandler_Get_NoTenant(t *testing.T) {
+	h := NewLoginHookHandler(&mockLoginHookService{})
+	w := httptest.NewRecorder()
+	h.Get(w, httptest.NewRequest(http.MethodGet, "/login-hooks/abc", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestLoginHookHandler_Get_InvalidUUID(t *testing.T) {
+	h := NewLoginHookHandler(&mockLoginHookService{})
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/login-hooks/not-a-uuid", nil))
+	r = withChiParam(r, "login_hook_uuid", "not-a-uuid")
+	w := httptest.NewRecorder()
+	h.Get(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLoginHookHandler_Get_ServiceError(t *testing.T) {
+	svc := &mockLoginHookService{
+		getByUUIDFn: func(_ int64, _ uuid.UUID) (*service.LoginHookServiceDataResult, error) {
+			return nil, errNotFound
+		},
+	}
+	h := NewLoginHookHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/login-hooks/"+testResourceUUID.String(), nil))
+	r = withChiParam(r, "login_hook_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.Get(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestLoginHookHandler_Get_Success(t *testing.T) {
+	svc := &mockLoginHookService{
+		getByUUIDFn: func(_ int64, _ uuid.UUID) (*service.LoginHookServiceDataResult, error) {
+			return loginHookResult(), nil
+		},
+	}
+	h := NewLoginHookHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/login-hooks/"+testResourceUUID.String(), nil))
+	r = withChiParam(r, "login_hook_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.Get(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// ---------------------------------------------------------------------------
+// Create
+// ---------------------------------------------------------------------------
+
+func TestLoginHookHandler_Create_NoTenant(t *testing.T) {
+	h := NewLoginHookHandler(&mockLoginHookService{})
+	w := httptest.NewRecorder()
+	h.Create(w, httptest.NewRequest(http.MethodPost, "/login-hooks", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestLoginHookHandler_Create_BadJSON(t *testing.T) {
+	h := NewLoginHookHandler(&mockLoginHookService{})
+	w := httptest.NewRecorder()
+	h.Create(w, withTenant(badJSONReq(t, http.MethodPost, "/login-hooks")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLoginHookHandler_Create_ValidationError(t *testing.T) {
+	h := NewLoginHookHandler(&mockLoginHookService{})
+	body := loginHookBody()
+	delete(body, "url")
+	w := httptest.NewRecorder()
+	h.Create(w, withTenant(jsonReq(t, http.MethodPost, "/login-hooks", body)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLoginHookHandler_Create_ServiceError(t *testing.T) {
+	svc := &mockLoginHookService{
+		createFn: func(_ int64, _ service.LoginHookInput) (*service.LoginHookServiceDataResult, error) {
+			return nil, assert.AnError
+		},
+	}
+	h := NewLoginHookHandler(svc)
+	w := httptest.NewRecorder()
+	h.Create(w, withTenant(jsonReq(t, http.MethodPost, "/login-hooks", loginHookBody())))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestLoginHookHandler_Create_Success(t *testing.T) {
+	svc := &mockLoginHookService{
+		createFn: func(tid int64, input service.LoginHookInput) (*service.LoginHookServiceDataResult, error) {
+			assert.Equal(t, tenantID, tid)
+			assert.Equal(t, model.LoginHookFailClosed, input.FailurePolicy)
+			assert.Equal(t, model.StatusActive, input.Status)
+			assert.Equal(t, "s3cret", input.Secret)
+			return loginHookResult(), nil
+		},
+	}
+	h := NewLoginHookHandler(svc)
+	w := httptest.NewRecorder()
+	h.Create(w, withTenant(jsonReq(t, http.MethodPost, "/login-hooks", loginHookBody())))
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestLoginHookHandler_Create_Rule(t *testing.T) {
+	svc := &mockLoginHookService{
+		createFn: func(_ int64, input service.LoginHookInput) (*service.LoginHookServiceDataResult, error) {
+			assert.JSONEq(t, `{"action":"allow","claims":{"tier":"gold"}}`, string(input.Rule))
+			assert.Equal(t, model.LoginHookFailOpen, input.FailurePolicy)
+			return loginHookResult(), nil
+		},
+	}
+	h := NewLoginHookHandler(svc)
+	body := map[string]any{
+		"name":           "Tier claim",
+		"trigger":        model.LoginHookTriggerPostLogin,
+		"type":           model.LoginHookTypeRule,
+		"rule":           map[string]any{"action": "allow", "claims": map[string]any{"tier": "gold"}},
+		"failure_policy": model.LoginHookFailOpen,
+	}
+	w := httptest.NewRecorder()
+	h.Create(w, withTenant(jsonReq(t, http.MethodPost, "/login-hooks", body)))
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+// ---------------------------------------------------------------------------
+// Update
+// ---------------------------------------------------------------------------
+
+func TestLoginHookHandler_Update_NoTenant(t *testing.T) {
+	h := NewLoginHookHandler(&mockLoginHookService{})
+	w := httptest.NewRecorder()
+	h.Update(w, httptest.NewRequest(http.MethodPut, "/login-hooks/abc", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestLoginHookHandler_Update_InvalidUUID(t *testing.T) {
+	h := NewLoginHookHandler(&mockLoginHookService{})
+	r := withTenant(jsonReq(t, http.MethodPut, "/login-hooks/bad", loginHookBody()))
+	r = withChiParam(r, "login_hook_uuid", "bad")
+	w := httptest.NewRecorder()
+	h.Update(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLoginHookHandler_Update_BadJSON(t *testing.T) {
+	h := NewLoginHookHandler(&mockLoginHookService{})
+	r := withTenant(badJSONReq(t, http.MethodPut, "/login-hooks/"+testResourceUUID.String()))
+	r = withChiParam(r, "login_hook_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.Update(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLoginHookHandler_Update_ValidationError(t *testing.T) {
+	h := NewLoginHookHandler(&mockLoginHookService{})
+	body := loginHookBody()
+	body["trigger"] = "post_logout"
+	r := withTenant(jsonReq(t, http.MethodPut, "/login-hooks/"+testResourceUUID.String(), body))
+	r = withChiParam(r, "login_hook_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.Update(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLoginHookHandler_Update_ServiceError(t *testing.T) {
+	svc := &mockLoginHookService{
+		updateFn: func(_ int64, _ uuid.UUID, _ service.LoginHookInput) (*service.LoginHookServiceDataResult, error) {
+			return nil, errNotFound
+		},
+	}
+	h := NewLoginHookHandler(svc)
+	r := withTenant(jsonReq(t, http.MethodPut, "/login-hooks/"+testResourceUUID.String(), loginHookBody()))
+	r = withChiParam(r, "login_hook_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.Update(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestLoginHookHandler_Update_Success(t *testing.T) {
+	svc := &mockLoginHookService{
+		updateFn: func(_ int64, id uuid.UUID, _ service.LoginHookInput) (*service.LoginHookServiceDataResult, error) {
+			assert.Equal(t, testResourceUUID, id)
+			return loginHookResult(), nil
+		},
+	}
+	h := NewLoginHookHandler(svc)
+	r := withTenant(jsonReq(t, http.MethodPut, "/login-hooks/"+testResourceUUID.String(), loginHookBody()))
+	r = withChiParam(r, "login_hook_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.Update(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// ---------------------------------------------------------------------------
+// Delete
+// ---------------------------------------------------------------------------
+
+func TestLoginHookHandler_Delete_NoTenant(t *testing.T) {
+	h := NewLoginHookHandler(&mockLoginHookService{})
+	w := httptest.NewRecorder()
+	h.Delete(w, httptest.NewRequest(http.MethodDelete, "/login-hooks/abc", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestLoginHookHandler_Delete_InvalidUUID(t *testing.T) {
+	h := NewLoginHookHandler(&mockLoginHookService{})
+	r := withTenant(httptest.NewRequest(http.MethodDelete, "/login-hooks/bad", nil))
+	r = withChiParam(r, "login_hook_uuid", "bad")
+	w := httptest.NewRecorder()
+	h.Delete(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLoginHookHandler_Delete_ServiceError(t *testing.T) {
+	svc := &mockLoginHookService{
+		deleteFn: func(_ int64, _ uuid.UUID) (*service.LoginHookServiceDataResult, error) {
+			return nil, assert.AnError
+		},
+	}
+	h := NewLoginHookHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodDelete, "/login-hooks/"+testResourceUUID.String(), nil))
+	r = withChiParam(r, "login_hook_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.Delete(w, r)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestLoginHookHandler_Delete_Success(t *testing.T) {
+	svc := &mockLoginHookService{
+		deleteFn: func(_ int64, _ uuid.UUID) (*service.LoginHookServiceDataResult, error) {
+			return loginHookResult(), nil
+		},
+	}
+	h := NewLoginHookHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodDelete, "/login-hooks/"+testResourceUUID.String(), nil))
+	r = withChiParam(r, "login_hook_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.Delete(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// ---------------------------------------------------------------------------
+// UpdateStatus
+// ---------------------------------------------------------------------------
+
+func TestLoginHookHandler_UpdateStatus_NoTenant(t *testing.T) {
+	h := NewLoginHookHandler(&mockLoginHookService{})
+	w := httptest.NewRecorder()
+	h.UpdateStatus(w, httptest.NewRequest(http.MethodPatch, "/login-hooks/abc/status", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestLoginHookHandler_UpdateStatus_InvalidUUID(t *testing.T) {
+	h := NewLoginHookHandler(&mockLoginHookService{})
+	r := withTenant(jsonReq(t, http.MethodPatch, "/login-hooks/bad/status", map[string]string{"status": "inactive"}))
+	r = withChiParam(r, "login_hook_uuid", "bad")
+	w := httptest.NewRecorder()
+	h.UpdateStatus(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLoginHookHandler_UpdateStatus_BadJSON(t *testing.T) {
+	h := NewLoginHookHandler(&mockLoginHookService{})
+	r := withTenant(badJSONReq(t, http.MethodPatch, "/login-hooks/"+testResourceUUID.String()+"/status"))
+	r = withChiParam(r, "login_hook_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.UpdateStatus(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLoginHookHandler_UpdateStatus_ValidationError(t *testing.T) {
+	h := NewLoginHookHandler(&mockLoginHookService{})
+	r := withTenant(jsonReq(t, http.MethodPatch, "/login-hooks/"+testResourceUUID.String()+"/status", map[string]string{"status": "paused"}))
+	r = withChiParam(r, "login_hook_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.UpdateStatus(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLoginHookHandler_UpdateStatus_ServiceError(t *testing.T) {
+	svc := &mockLoginHookService{
+		updateStatusFn: func(_ int64, _ uuid.UUID, _ string) (*service.LoginHookServiceDataResult, error) {
+			return nil, assert.AnError
+		},
+	}
+	h := NewLoginHookHandler(svc)
+	r := withTenant(jsonReq(t, http.MethodPatch, "/login-hooks/"+testResourceUUID.String()+"/status", map[string]string{"status": "inactive"}))
+	r = withChiParam(r, "login_hook_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.UpdateStatus(w, r)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestLoginHookHandler_UpdateStatus_Success(t *testing.T) {
+	svc := &mockLoginHookService{
+		updateStatusFn: func(_ int64, _ uuid.UUID, status string) (*service.LoginHookServiceDataResult, error) {
+			assert.Equal(t, model.StatusInactive, status)
+			return loginHookResult(), nil
+		},
+	}
+	h := NewLoginHookHandler(svc)
+	r := withTenant(jsonReq(t, http.MethodPatch, "/login-hooks/"+testResourceUUID.String()+"/status", map[string]string{"status": "inactive"}))
+	r = withChiParam(r, "login_hook_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.UpdateStatus(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -1,19 +1,23 @@
 package handler
 
 import (
+	"bytes"
 	"encoding/json"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/hostedlogin"
 	"github.com/maintainerd/auth/internal/middleware"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/ptr"
 	resp "github.com/maintainerd/auth/internal/rest/response"
 	"github.com/maintainerd/auth/internal/service"
+	"github.com/maintainerd/auth/internal/session"
 )
 
 // LoginTemplateHandler handles HTTP requests for login template management.
@@ -21,12 +25,16 @@ import (
 // and sets it in the request context. The service layer ensures templates belong to the tenant.
 type LoginTemplateHandler struct {
 	loginTemplateService service.LoginTemplateService
+	sessions             session.Store
 }
 
 // NewLoginTemplateHandler creates a new instance of LoginTemplateHandler.
-func NewLoginTemplateHandler(loginTemplateService service.LoginTemplateService) *LoginTemplateHandler {
+// sessions keeps the pending authorization request of the hosted login page;
+// it may be nil, in which case the request only travels in the page URL.
+func NewLoginTemplateHandler(loginTemplateService service.LoginTemplateService, sessions session.Store) *LoginTemplateHandler {
 	return &LoginTemplateHandler{
 		loginTemplateService: loginTemplateService,
+		sessions:             sessions,
 	}
 }
 
@@ -55,11 +63,8 @@ func (h *LoginTemplateHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		limit = 10
 	}
 
-	// Parse status filter (can be comma-separated)
-	var status []string
-	if q.Get("status") != "" {
-		status = strings.Split(q.Get("status"), ",")
-	}
+	// Parse status filter (comma-separated or repeated values)
+	status := queryValues(q, "status")
 
 	// Parse boolean filters for default and system templates
 	var isDefault *bool
@@ -356,6 +361,175 @@ func toLoginTemplateListResponseDTO(template service.LoginTemplateServiceDataRes
 	}
 }
 
+// hostedLoginCSP relaxes the default Content-Security-Policy so tenant logos
+// and favicons can be loaded from any HTTPS origin.
+const hostedLoginCSP = "default-src 'self'; script-src 'self'; style-src 'self' 'unsafe-inline'; img-src 'self' data: https:; font-src 'self'; connect-src 'self'; frame-ancestors 'none'; form-action 'self'"
+
+// hostedLoginOAuthParams are the authorization request parameters the hosted
+// page forwards to the authorize endpoint after a successful login.
+var hostedLoginOAuthParams = []string{
+	"response_type", "client_id", "redirect_uri", "scope", "state",
+	"nonce", "code_challenge", "code_challenge_method",
+}
+
+// HostedPage renders the tenant-branded login page for an auth client. When
+// the page is opened with OAuth2 authorization parameters it continues the
+// authorize flow after login and redirects back to the client.
+//
+// GET /login?client_id=...&provider_id=...[&response_type=code&redirect_uri=...]
+func (h *LoginTemplateHandler) HostedPage(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := dto.LoginQueryDTO{
+		ClientID:   q.Get("client_id"),
+		ProviderID: q.Get("provider_id"),
+	}
+	if err := query.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	base := strings.TrimSuffix(strings.TrimSuffix(r.URL.Path, "/"), "login")
+
+	var authorizeURL string
+	if q.Get("response_type") != "" {
+		authorize := dto.OAuthAuthorizeRequestDTO{
+			ResponseType:        q.Get("response_type"),
+			ClientID:            q.Get("client_id"),
+			RedirectURI:         q.Get("redirect_uri"),
+			Scope:               q.Get("scope"),
+			State:               q.Get("state"),
+			Nonce:               q.Get("nonce"),
+			CodeChallenge:       q.Get("code_challenge"),
+			CodeChallengeMethod: q.Get("code_challenge_method"),
+		}
+		if err := authorize.Validate(); err != nil {
+			resp.ValidationError(w, err)
+			return
+		}
+
+		params := url.Values{}
+		for _, key := range hostedLoginOAuthParams {
+			if v := q.Get(key); v != "" {
+				params.Set(key, v)
+			}
+		}
+		authorizeURL = base + "oauth/authorize?" + params.Encode()
+	}
+	authorizeURL = h.pendingAuthorizeURL(w, r, query.ClientID, authorizeURL)
+
+	result, err := h.loginTemplateService.GetHostedPage(r.Context(), query.ClientID, query.ProviderID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to load login page", err)
+		return
+	}
+
+	page := toHostedLoginPage(*result)
+	page.AssetsURL = base + "login/assets/"
+	page.LoginURL = base + "login?" + url.Values{
+		"client_id":   {query.ClientID},
+		"provider_id": {query.ProviderID},
+	}.Encode()
+	page.AuthorizeURL = authorizeURL
+	page.CSRFToken = middleware.CSRFTokenFromRequest(r)
+
+	var buf bytes.Buffer
+	if err := hostedlogin.Render(&buf, page); err != nil {
+		resp.LoggerFromContext(r.Context()).Error("render hosted login page failed", "error", err)
+		resp.Error(w, http.StatusInternalServerError, "Failed to load login page")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Security-Policy", hostedLoginCSP)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// Hosted page session keys.
+const (
+	sessionKeyClientID     = "client_id"
+	sessionKeyAuthorizeURL = "authorize_url"
+)
+
+// pendingAuthorizeURL remembers the authorization request the hosted page was
+// opened with, so reopening the page for the same client without the OAuth2
+// parameters (after a reload or a detour through another hosted page) still
+// continues the flow. Session failures are logged and the page falls back to
+// the URL parameters alone.
+func (h *LoginTemplateHandler) pendingAuthorizeURL(w http.ResponseWriter, r *http.Request, clientID, authorizeURL string) string {
+	if h.sessions == nil {
+		return authorizeURL
+	}
+	logger := resp.LoggerFromContext(r.Context())
+
+	// Reuse the session CSRFMiddleware loaded so its token is saved with it
+	sess := middleware.HostedSessionFromRequest(r)
+	if sess == nil {
+		var err error
+		if sess, err = h.sessions.Load(r); err != nil {
+			logger.Warn("load hosted login session failed", "error", err)
+			return authorizeURL
+		}
+	}
+
+	if authorizeURL == "" {
+		if sess.Get(sessionKeyClientID) == clientID {
+			return sess.Get(sessionKeyAuthorizeURL)
+		}
+		return ""
+	}
+
+	sess.Set(sessionKeyClientID, clientID)
+	sess.Set(sessionKeyAuthorizeURL, authorizeURL)
+	if err := h.sessions.Save(r.Context(), w, sess); err != nil {
+		logger.Warn("save hosted login session failed", "error", err)
+	}
+	return authorizeURL
+}
+
+// toHostedLoginPage merges tenant branding with the login template metadata.
+// Template metadata wins so a template can restyle a single client.
+func toHostedLoginPage(result service.LoginTemplateHostedPageResult) hostedlogin.Page {
+	page := hostedlogin.Page{
+		Style:    result.Template,
+		Subtitle: result.ClientName,
+	}
+
+	if b := result.Branding; b != nil {
+		page.CompanyName = b.CompanyName
+		page.LogoURL = b.LogoURL
+		page.FaviconURL = b.FaviconURL
+		page.PrimaryColor = b.PrimaryColor
+		page.FontFamily = b.FontFamily
+		page.CustomCSS = b.CustomCSS
+		page.SupportURL = b.SupportURL
+		page.PrivacyPolicyURL = b.PrivacyPolicyURL
+		page.TermsOfServiceURL = b.TermsOfServiceURL
+	}
+
+	overrides := map[string]*string{
+		"title":            &page.Title,
+		"subtitle":         &page.Subtitle,
+		"logo_url":         &page.LogoURL,
+		"favicon_url":      &page.FaviconURL,
+		"primary_color":    &page.PrimaryColor,
+		"background_color": &page.BackgroundColor,
+		"font_family":      &page.FontFamily,
+		"username_label":   &page.UsernameLabel,
+		"password_label":   &page.PasswordLabel,
+		"submit_label":     &page.SubmitLabel,
+		"consent_url":      &page.ConsentURL,
+	}
+	for key, field := range overrides {
+		if v, ok := result.Metadata[key].(string); ok && v != "" {
+			*field = v
+		}
+	}
+
+	return page
+}
+
 // toLoginTemplateListResponseDtoList converts a slice of service results to list response DTOs.
 func toLoginTemplateListResponseDtoList(templates []service.LoginTemplateServiceDataResult) []dto.LoginTemplateListResponseDTO {
 	result := make([]dto.LoginTemplateListResponseDTO, len(templates))
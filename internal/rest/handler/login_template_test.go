@@ -1,14 +1,18 @@
 package handler
 
 import (
+	"bytes"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/middleware"
 	"github.com/maintainerd/auth/internal/service"
+	"github.com/maintainerd/auth/internal/session"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func loginTmplResult() service.LoginTemplateServiceDataResult {
@@ -23,7 +27,7 @@ func TestLoginTemplateHandler_GetAll(t *testing.T) {
 	svc := &mockLoginTemplateService{getAllFn: func(_ int64, _ *string, _ []string, _ *string, _, _ *bool, _, _ int, _, _ string) (*service.LoginTemplateServiceListResult, error) {
 		return &service.LoginTemplateServiceListResult{Data: []service.LoginTemplateServiceDataResult{loginTmplResult()}}, nil
 	}}
-	h := NewLoginTemplateHandler(svc)
+	h := NewLoginTemplateHandler(svc, nil)
 	w := httptest.NewRecorder()
 	h.GetAll(w, withTenant(jsonReq(t, http.MethodGet, "/login-templates", nil)))
 	assert.Equal(t, http.StatusOK, w.Code)
@@ -31,13 +35,13 @@ func TestLoginTemplateHandler_GetAll(t *testing.T) {
 
 func TestLoginTemplateHandler_GetAll_NoTenant(t *testing.T) {
 	w := httptest.NewRecorder()
-	NewLoginTemplateHandler(&mockLoginTemplateService{}).GetAll(w, jsonReq(t, http.MethodGet, "/login-templates", nil))
+	NewLoginTemplateHandler(&mockLoginTemplateService{}, nil).GetAll(w, jsonReq(t, http.MethodGet, "/login-templates", nil))
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
 func TestLoginTemplateHandler_GetAll_ValidationError(t *testing.T) {
 	w := httptest.NewRecorder()
-	NewLoginTemplateHandler(&mockLoginTemplateService{}).GetAll(w, withTenant(jsonReq(t, http.MethodGet, "/login-templates?sort_order=invalid", nil)))
+	NewLoginTemplateHandler(&mockLoginTemplateService{}, nil).GetAll(w, withTenant(jsonReq(t, http.MethodGet, "/login-templates?sort_order=invalid", nil)))
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
@@ -47,7 +51,7 @@ func TestLoginTemplateHandler_GetAll_WithFilters(t *testing.T) {
 		return &service.LoginTemplateServiceListResult{}, nil
 	}}
 	w := httptest.NewRecorder()
-	NewLoginTemplateHandler(svc).GetAll(w, withTenant(jsonReq(t, http.MethodGet,
+	NewLoginTemplateHandler(svc, nil).GetAll(w, withTenant(jsonReq(t, http.MethodGet,
 		"/login-templates?page=1&limit=10&status=active&is_default=true&is_system=false", nil)))
 	assert.Equal(t, http.StatusOK, w.Code)
 }
@@ -56,7 +60,7 @@ func TestLoginTemplateHandler_GetAll_Error(t *testing.T) {
 	svc := &mockLoginTemplateService{getAllFn: func(_ int64, _ *string, _ []string, _ *string, _, _ *bool, _, _ int, _, _ string) (*service.LoginTemplateServiceListResult, error) {
 		return nil, errors.New("db")
 	}}
-	h := NewLoginTemplateHandler(svc)
+	h := NewLoginTemplateHandler(svc, nil)
 	w := httptest.NewRecorder()
 	h.GetAll(w, withTenant(jsonReq(t, http.MethodGet, "/login-templates", nil)))
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -64,14 +68,14 @@ func TestLoginTemplateHandler_GetAll_Error(t *testing.T) {
 
 func TestLoginTemplateHandler_Get_NoTenant(t *testing.T) {
 	w := httptest.NewRecorder()
-	NewLoginTemplateHandler(&mockLoginTemplateService{}).Get(w, jsonReq(t, http.MethodGet, "/login-templates/id", nil))
+	NewLoginTemplateHandler(&mockLoginTemplateService{}, nil).Get(w, jsonReq(t, http.MethodGet, "/login-templates/id", nil))
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
 func TestLoginTemplateHandler_Get(t *testing.T) {
 	res := loginTmplResult()
 	svc := &mockLoginTemplateService{getByUUIDFn: func(_ uuid.UUID, _ int64) (*service.LoginTemplateServiceDataResult, error) { return &res, nil }}
-	h := NewLoginTemplateHandler(svc)
+	h := NewLoginTemplateHandler(svc, nil)
 	r := withChiParam(withTenant(jsonReq(t, http.MethodGet, "/login-templates/id", nil)), "login_template_uuid", testResourceUUID.String())
 	w := httptest.NewRecorder()
 	h.Get(w, r)
@@ -79,7 +83,7 @@ func TestLoginTemplateHandler_Get(t *testing.T) {
 }
 
 func TestLoginTemplateHandler_Get_BadUUID(t *testing.T) {
-	h := NewLoginTemplateHandler(&mockLoginTemplateService{})
+	h := NewLoginTemplateHandler(&mockLoginTemplateService{}, nil)
 	r := withChiParam(withTenant(jsonReq(t, http.MethodGet, "/login-templates/bad", nil)), "login_template_uuid", "not-a-uuid")
 	w := httptest.NewRecorder()
 	h.Get(w, r)
@@ -90,7 +94,7 @@ func TestLoginTemplateHandler_Get_NotFound(t *testing.T) {
 	svc := &mockLoginTemplateService{getByUUIDFn: func(_ uuid.UUID, _ int64) (*service.LoginTemplateServiceDataResult, error) {
 		return nil, errNotFound
 	}}
-	h := NewLoginTemplateHandler(svc)
+	h := NewLoginTemplateHandler(svc, nil)
 	r := withChiParam(withTenant(jsonReq(t, http.MethodGet, "/login-templates/id", nil)), "login_template_uuid", testResourceUUID.String())
 	w := httptest.NewRecorder()
 	h.Get(w, r)
@@ -99,19 +103,19 @@ func TestLoginTemplateHandler_Get_NotFound(t *testing.T) {
 
 func TestLoginTemplateHandler_Create_NoTenant(t *testing.T) {
 	w := httptest.NewRecorder()
-	NewLoginTemplateHandler(&mockLoginTemplateService{}).Create(w, withUser(jsonReq(t, http.MethodPost, "/", validLoginTemplateBody())))
+	NewLoginTemplateHandler(&mockLoginTemplateService{}, nil).Create(w, withUser(jsonReq(t, http.MethodPost, "/", validLoginTemplateBody())))
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
 func TestLoginTemplateHandler_Create_BadJSON(t *testing.T) {
 	w := httptest.NewRecorder()
-	NewLoginTemplateHandler(&mockLoginTemplateService{}).Create(w, withTenantAndUser(badJSONReq(t, http.MethodPost, "/")))
+	NewLoginTemplateHandler(&mockLoginTemplateService{}, nil).Create(w, withTenantAndUser(badJSONReq(t, http.MethodPost, "/")))
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
 func TestLoginTemplateHandler_Create_ValidationError(t *testing.T) {
 	w := httptest.NewRecorder()
-	NewLoginTemplateHandler(&mockLoginTemplateService{}).Create(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/", map[string]any{})))
+	NewLoginTemplateHandler(&mockLoginTemplateService{}, nil).Create(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/", map[string]any{})))
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
@@ -122,7 +126,7 @@ func TestLoginTemplateHandler_Create_CustomStatus(t *testing.T) {
 	}}
 	body := map[string]any{"name": "tmpl1", "template": "modern", "status": "inactive"}
 	w := httptest.NewRecorder()
-	NewLoginTemplateHandler(svc).Create(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/", body)))
+	NewLoginTemplateHandler(svc, nil).Create(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/", body)))
 	assert.Equal(t, http.StatusCreated, w.Code)
 }
 
@@ -131,7 +135,7 @@ func TestLoginTemplateHandler_Create(t *testing.T) {
 	svc := &mockLoginTemplateService{createFn: func(_ int64, _ string, _ *string, _ string, _ map[string]any, _ string) (*service.LoginTemplateServiceDataResult, error) {
 		return &res, nil
 	}}
-	h := NewLoginTemplateHandler(svc)
+	h := NewLoginTemplateHandler(svc, nil)
 	body := map[string]any{"name": "tmpl1", "template": "modern"}
 	w := httptest.NewRecorder()
 	h.Create(w, withTenant(jsonReq(t, http.MethodPost, "/login-templates", body)))
@@ -142,7 +146,7 @@ func TestLoginTemplateHandler_Create_Error(t *testing.T) {
 	svc := &mockLoginTemplateService{createFn: func(_ int64, _ string, _ *string, _ string, _ map[string]any, _ string) (*service.LoginTemplateServiceDataResult, error) {
 		return nil, errValidation
 	}}
-	h := NewLoginTemplateHandler(svc)
+	h := NewLoginTemplateHandler(svc, nil)
 	body := map[string]any{"name": "tmpl1", "template": "modern"}
 	w := httptest.NewRecorder()
 	h.Create(w, withTenant(jsonReq(t, http.MethodPost, "/login-templates", body)))
@@ -152,28 +156,28 @@ func TestLoginTemplateHandler_Create_Error(t *testing.T) {
 func TestLoginTemplateHandler_Update_NoTenant(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := withUser(withChiParam(jsonReq(t, http.MethodPut, "/", validLoginTemplateBody()), "login_template_uuid", testResourceUUID.String()))
-	NewLoginTemplateHandler(&mockLoginTemplateService{}).Update(w, r)
+	NewLoginTemplateHandler(&mockLoginTemplateService{}, nil).Update(w, r)
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
 func TestLoginTemplateHandler_Update_InvalidUUID(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPut, "/", validLoginTemplateBody()), "login_template_uuid", "bad"))
-	NewLoginTemplateHandler(&mockLoginTemplateService{}).Update(w, r)
+	NewLoginTemplateHandler(&mockLoginTemplateService{}, nil).Update(w, r)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
 func TestLoginTemplateHandler_Update_BadJSON(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := withTenantAndUser(withChiParam(badJSONReq(t, http.MethodPut, "/"), "login_template_uuid", testResourceUUID.String()))
-	NewLoginTemplateHandler(&mockLoginTemplateService{}).Update(w, r)
+	NewLoginTemplateHandler(&mockLoginTemplateService{}, nil).Update(w, r)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
 func TestLoginTemplateHandler_Update_ValidationError(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{}), "login_template_uuid", testResourceUUID.String()))
-	NewLoginTemplateHandler(&mockLoginTemplateService{}).Update(w, r)
+	NewLoginTemplateHandler(&mockLoginTemplateService{}, nil).Update(w, r)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
@@ -183,7 +187,7 @@ func TestLoginTemplateHandler_Update_ServiceError(t *testing.T) {
 	}}
 	w := httptest.NewRecorder()
 	r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPut, "/", validLoginTemplateBody()), "login_template_uuid", testResourceUUID.String()))
-	NewLoginTemplateHandler(svc).Update(w, r)
+	NewLoginTemplateHandler(svc, nil).Update(w, r)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
@@ -195,7 +199,7 @@ func TestLoginTemplateHandler_Update_CustomStatus(t *testing.T) {
 	body := map[string]any{"name": "tmpl1", "template": "modern", "status": "inactive"}
 	w := httptest.NewRecorder()
 	r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPut, "/", body), "login_template_uuid", testResourceUUID.String()))
-	NewLoginTemplateHandler(svc).Update(w, r)
+	NewLoginTemplateHandler(svc, nil).Update(w, r)
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
@@ -204,7 +208,7 @@ func TestLoginTemplateHandler_Update(t *testing.T) {
 	svc := &mockLoginTemplateService{updateFn: func(_ uuid.UUID, _ int64, _ string, _ *string, _ string, _ map[string]any, _ string) (*service.LoginTemplateServiceDataResult, error) {
 		return &res, nil
 	}}
-	h := NewLoginTemplateHandler(svc)
+	h := NewLoginTemplateHandler(svc, nil)
 	body := map[string]any{"name": "upd", "template": "modern"}
 	r := withChiParam(withTenant(jsonReq(t, http.MethodPut, "/login-templates/id", body)), "login_template_uuid", testResourceUUID.String())
 	w := httptest.NewRecorder()
@@ -214,14 +218,14 @@ func TestLoginTemplateHandler_Update(t *testing.T) {
 
 func TestLoginTemplateHandler_Delete_NoTenant(t *testing.T) {
 	w := httptest.NewRecorder()
-	NewLoginTemplateHandler(&mockLoginTemplateService{}).Delete(w,
+	NewLoginTemplateHandler(&mockLoginTemplateService{}, nil).Delete(w,
 		withChiParam(httptest.NewRequest(http.MethodDelete, "/", nil), "login_template_uuid", testResourceUUID.String()))
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
 func TestLoginTemplateHandler_Delete_InvalidUUID(t *testing.T) {
 	w := httptest.NewRecorder()
-	NewLoginTemplateHandler(&mockLoginTemplateService{}).Delete(w,
+	NewLoginTemplateHandler(&mockLoginTemplateService{}, nil).Delete(w,
 		withTenant(withChiParam(httptest.NewRequest(http.MethodDelete, "/", nil), "login_template_uuid", "bad")))
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
@@ -231,7 +235,7 @@ func TestLoginTemplateHandler_Delete_ServiceError(t *testing.T) {
 		return nil, errValidation
 	}}
 	w := httptest.NewRecorder()
-	NewLoginTemplateHandler(svc).Delete(w,
+	NewLoginTemplateHandler(svc, nil).Delete(w,
 		withTenant(withChiParam(httptest.NewRequest(http.MethodDelete, "/", nil), "login_template_uuid", testResourceUUID.String())))
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
@@ -239,7 +243,7 @@ func TestLoginTemplateHandler_Delete_ServiceError(t *testing.T) {
 func TestLoginTemplateHandler_Delete(t *testing.T) {
 	res := loginTmplResult()
 	svc := &mockLoginTemplateService{deleteFn: func(_ uuid.UUID, _ int64) (*service.LoginTemplateServiceDataResult, error) { return &res, nil }}
-	h := NewLoginTemplateHandler(svc)
+	h := NewLoginTemplateHandler(svc, nil)
 	r := withChiParam(withTenant(jsonReq(t, http.MethodDelete, "/login-templates/id", nil)), "login_template_uuid", testResourceUUID.String())
 	w := httptest.NewRecorder()
 	h.Delete(w, r)
@@ -249,28 +253,28 @@ func TestLoginTemplateHandler_Delete(t *testing.T) {
 func TestLoginTemplateHandler_UpdateStatus_NoTenant(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := withUser(withChiParam(jsonReq(t, http.MethodPatch, "/", map[string]any{"status": "active"}), "login_template_uuid", testResourceUUID.String()))
-	NewLoginTemplateHandler(&mockLoginTemplateService{}).UpdateStatus(w, r)
+	NewLoginTemplateHandler(&mockLoginTemplateService{}, nil).UpdateStatus(w, r)
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
 func TestLoginTemplateHandler_UpdateStatus_InvalidUUID(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPatch, "/", map[string]any{"status": "active"}), "login_template_uuid", "bad"))
-	NewLoginTemplateHandler(&mockLoginTemplateService{}).UpdateStatus(w, r)
+	NewLoginTemplateHandler(&mockLoginTemplateService{}, nil).UpdateStatus(w, r)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
 func TestLoginTemplateHandler_UpdateStatus_BadJSON(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := withTenantAndUser(withChiParam(badJSONReq(t, http.MethodPatch, "/"), "login_template_uuid", testResourceUUID.String()))
-	NewLoginTemplateHandler(&mockLoginTemplateService{}).UpdateStatus(w, r)
+	NewLoginTemplateHandler(&mockLoginTemplateService{}, nil).UpdateStatus(w, r)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
 func TestLoginTemplateHandler_UpdateStatus_ValidationError(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPatch, "/", map[string]any{"status": "invalid"}), "login_template_uuid", testResourceUUID.String()))
-	NewLoginTemplateHandler(&mockLoginTemplateService{}).UpdateStatus(w, r)
+	NewLoginTemplateHandler(&mockLoginTemplateService{}, nil).UpdateStatus(w, r)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
@@ -280,7 +284,7 @@ func TestLoginTemplateHandler_UpdateStatus_ServiceError(t *testing.T) {
 	}}
 	w := httptest.NewRecorder()
 	r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPatch, "/", map[string]any{"status": "active"}), "login_template_uuid", testResourceUUID.String()))
-	NewLoginTemplateHandler(svc).UpdateStatus(w, r)
+	NewLoginTemplateHandler(svc, nil).UpdateStatus(w, r)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
@@ -289,9 +293,144 @@ func TestLoginTemplateHandler_UpdateStatus(t *testing.T) {
 	svc := &mockLoginTemplateService{updateStatusFn: func(_ uuid.UUID, _ int64, _ string) (*service.LoginTemplateServiceDataResult, error) {
 		return &res, nil
 	}}
-	h := NewLoginTemplateHandler(svc)
+	h := NewLoginTemplateHandler(svc, nil)
 	r := withChiParam(withTenant(jsonReq(t, http.MethodPatch, "/login-templates/id/status", map[string]any{"status": "inactive"})), "login_template_uuid", testResourceUUID.String())
 	w := httptest.NewRecorder()
 	h.UpdateStatus(w, r)
 	assert.Equal(t, http.StatusOK, w.Code)
 }
+
+// ---------------------------------------------------------------------------
+// HostedPage
+// ---------------------------------------------------------------------------
+
+const hostedLoginChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+func TestLoginTemplateHandler_HostedPage_MissingClient(t *testing.T) {
+	h := NewLoginTemplateHandler(&mockLoginTemplateService{}, nil)
+	w := httptest.NewRecorder()
+	h.HostedPage(w, httptest.NewRequest(http.MethodGet, "/api/v1/login?provider_id=idp", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLoginTemplateHandler_HostedPage_InvalidAuthorizeRequest(t *testing.T) {
+	h := NewLoginTemplateHandler(&mockLoginTemplateService{}, nil)
+	w := httptest.NewRecorder()
+	h.HostedPage(w, httptest.NewRequest(http.MethodGet, "/api/v1/login?client_id=web&provider_id=idp&response_type=token", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLoginTemplateHandler_HostedPage_ServiceError(t *testing.T) {
+	svc := &mockLoginTemplateService{hostedPageFn: func(_, _ string) (*service.LoginTemplateHostedPageResult, error) {
+		return nil, errNotFound
+	}}
+	h := NewLoginTemplateHandler(svc, nil)
+	w := httptest.NewRecorder()
+	h.HostedPage(w, httptest.NewRequest(http.MethodGet, "/api/v1/login?client_id=web&provider_id=idp", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestLoginTemplateHandler_HostedPage_Success(t *testing.T) {
+	svc := &mockLoginTemplateService{hostedPageFn: func(clientID, providerID string) (*service.LoginTemplateHostedPageResult, error) {
+		assert.Equal(t, "web", clientID)
+		assert.Equal(t, "idp", providerID)
+		return &service.LoginTemplateHostedPageResult{
+			ClientName: "Web App",
+			Template:   "classic",
+			Metadata:   map[string]any{"title": "Welcome", "primary_color": "#00ff00"},
+			Branding:   &service.BrandingServiceDataResult{CompanyName: "Acme", PrimaryColor: "#ff0000"},
+		}, nil
+	}}
+	h := NewLoginTemplateHandler(svc, nil)
+	w := httptest.NewRecorder()
+	h.HostedPage(w, httptest.NewRequest(http.MethodGet, "/api/v1/login?client_id=web&provider_id=idp", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Header().Get("Content-Security-Policy"), "img-src 'self' data: https:")
+
+	body := w.Body.String()
+	assert.Contains(t, body, `<body class="style-classic">`)
+	assert.Contains(t, body, "<h1>Welcome</h1>")
+	assert.Contains(t, body, "Web App")
+	assert.Contains(t, body, "--primary:#00ff00")
+	assert.Contains(t, body, `src="/api/v1/login/assets/login.js"`)
+	assert.Contains(t, body, `data-login-url="/api/v1/login?client_id=web&amp;provider_id=idp"`)
+	assert.Contains(t, body, `data-authorize-url=""`)
+}
+
+func TestLoginTemplateHandler_HostedPage_OAuthFlow(t *testing.T) {
+	h := NewLoginTemplateHandler(&mockLoginTemplateService{}, nil)
+	target := "/api/v1/login?client_id=web&provider_id=idp&response_type=code" +
+		"&redirect_uri=https%3A%2F%2Fapp.example.com%2Fcb&state=xyz&scope=openid" +
+		"&code_challenge=" + hostedLoginChallenge + "&code_challenge_method=S256"
+	w := httptest.NewRecorder()
+	h.HostedPage(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `data-authorize-url="/api/v1/oauth/authorize?client_id=web&amp;code_challenge=`+hostedLoginChallenge)
+	assert.Contains(t, body, "redirect_uri=https%3A%2F%2Fapp.example.com%2Fcb")
+	assert.Contains(t, body, "state=xyz")
+	assert.NotContains(t, body, "provider_id=idp&amp;response_type")
+}
+
+func TestLoginTemplateHandler_HostedPage_ResumesAuthorizeFromSession(t *testing.T) {
+	store, err := session.NewCookieStore([][]byte{bytes.Repeat([]byte{7}, session.KeySize)}, session.Options{})
+	require.NoError(t, err)
+	h := NewLoginTemplateHandler(&mockLoginTemplateService{}, store)
+
+	target := "/api/v1/login?client_id=web&provider_id=idp&response_type=code" +
+		"&redirect_uri=https%3A%2F%2Fapp.example.com%2Fcb&state=xyz" +
+		"&code_challenge=" + hostedLoginChallenge + "&code_challenge_method=S256"
+	w := httptest.NewRecorder()
+	h.HostedPage(w, httptest.NewRequest(http.MethodGet, target, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+
+	t.Run("same client resumes", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/login?client_id=web&provider_id=idp", nil)
+		r.AddCookie(cookies[0])
+		w := httptest.NewRecorder()
+		h.HostedPage(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "state=xyz")
+	})
+
+	t.Run("other client does not", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/login?client_id=mobile&provider_id=idp", nil)
+		r.AddCookie(cookies[0])
+		w := httptest.NewRecorder()
+		h.HostedPage(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "data-authorize-url=\"/")
+	})
+}
+
+func TestLoginTemplateHandler_HostedPage_CSRFToken(t *testing.T) {
+	store, err := session.NewCookieStore([][]byte{bytes.Repeat([]byte{7}, session.KeySize)}, session.Options{})
+	require.NoError(t, err)
+	h := middleware.CSRFMiddleware(store)(http.HandlerFunc(NewLoginTemplateHandler(&mockLoginTemplateService{}, store).HostedPage))
+
+	target := "/api/v1/login?client_id=web&provider_id=idp&response_type=code" +
+		"&redirect_uri=https%3A%2F%2Fapp.example.com%2Fcb&state=xyz" +
+		"&code_challenge=" + hostedLoginChallenge + "&code_challenge_method=S256"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	token := w.Header().Get(middleware.CSRFHeader)
+	require.NotEmpty(t, token)
+	assert.Contains(t, w.Body.String(), `data-csrf-token="`+token+`"`)
+
+	// The last cookie written holds both the token and the authorize request.
+	cookies := w.Result().Cookies()
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/login?client_id=web&provider_id=idp", nil)
+	r.AddCookie(cookies[len(cookies)-1])
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, token, w.Header().Get(middleware.CSRFHeader))
+	assert.Contains(t, w.Body.String(), "state=xyz")
+}
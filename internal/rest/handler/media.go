@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"path"
+
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/storage"
+)
+
+// MediaHandler serves objects of the local storage provider through the
+// signed URLs it hands out. The S3 and GCS providers sign URLs to the
+// bucket instead.
+type MediaHandler struct {
+	store *storage.LocalStore
+}
+
+// NewMediaHandler creates a media handler for the local store.
+func NewMediaHandler(store *storage.LocalStore) *MediaHandler {
+	return &MediaHandler{store: store}
+}
+
+// Get serves the object a signed URL grants.
+//
+// GET /media?key=...&expires=...&sig=...
+//
+// The link is the authorization: it needs no token, and it stops working
+// when it expires.
+func (h *MediaHandler) Get(w http.ResponseWriter, r *http.Request) {
+	key, err := h.store.Verify(r.URL.Query())
+	if err != nil {
+		resp.Error(w, http.StatusForbidden, "Invalid or expired link")
+		return
+	}
+
+	f, err := h.store.Open(key)
+	if errors.Is(err, storage.ErrNotFound) {
+		resp.Error(w, http.StatusNotFound, "Object not found")
+		return
+	}
+	if err != nil {
+		resp.Error(w, http.StatusInternalServerError, "Failed to read object")
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		resp.Error(w, http.StatusInternalServerError, "Failed to read object")
+		return
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	http.ServeContent(w, r, "", info.ModTime(), f)
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/maintainerd/auth/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMediaHandler_Get(t *testing.T) {
+	t.Setenv("HMAC_SECRET_KEY", "test-secret")
+	store, err := storage.NewLocalStore(t.TempDir(), "http://localhost/api/v1/media")
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, store.Put(ctx, "profiles/a/b.png", "image/png", []byte("png")))
+
+	get := func(t *testing.T, key string, ttl time.Duration) *httptest.ResponseRecorder {
+		t.Helper()
+		signed, err := store.SignedURL(ctx, key, ttl)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		NewMediaHandler(store).Get(w, httptest.NewRequest(http.MethodGet, signed, nil))
+		return w
+	}
+
+	t.Run("success", func(t *testing.T) {
+		w := get(t, "profiles/a/b.png", time.Minute)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.Equal(t, "png", w.Body.String())
+	})
+
+	t.Run("expired link returns 403", func(t *testing.T) {
+		w := get(t, "profiles/a/b.png", -time.Minute)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("unsigned link returns 403", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		target := "/media?" + url.Values{storage.LocalKeyParam: {"profiles/a/b.png"}}.Encode()
+		NewMediaHandler(store).Get(w, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("missing object returns 404", func(t *testing.T) {
+		w := get(t, "profiles/a/gone.png", time.Minute)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
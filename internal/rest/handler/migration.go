@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/maintainerd/auth/internal/dto"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// MigrationHandler handles HTTP requests for the schema migrations.
+type MigrationHandler struct {
+	migrationService service.MigrationService
+}
+
+// NewMigrationHandler creates a new MigrationHandler.
+func NewMigrationHandler(migrationService service.MigrationService) *MigrationHandler {
+	return &MigrationHandler{migrationService: migrationService}
+}
+
+// GetStatus lists every known migration with the time it was applied.
+//
+// GET /admin/migrations
+func (h *MigrationHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	result, err := h.migrationService.GetStatus(r.Context())
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to retrieve migrations", err)
+		return
+	}
+
+	out := dto.MigrationStatusResponseDTO{
+		Migrations: make([]dto.MigrationResponseDTO, len(result.Migrations)),
+		Pending:    result.Pending,
+	}
+	for i, m := range result.Migrations {
+		out.Migrations[i] = dto.MigrationResponseDTO{Version: m.Version, AppliedAt: m.AppliedAt}
+	}
+
+	resp.Success(w, out, "Migrations retrieved successfully")
+}
+
+// Apply applies every pending migration.
+//
+// POST /admin/migrations/apply
+func (h *MigrationHandler) Apply(w http.ResponseWriter, r *http.Request) {
+	applied, err := h.migrationService.Apply(r.Context())
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to apply migrations", err)
+		return
+	}
+	if applied == nil {
+		applied = []string{}
+	}
+
+	resp.Success(w, dto.MigrationApplyResponseDTO{Applied: applied}, "Migrations applied successfully")
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationHandler_GetStatus(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		appliedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		h := NewMigrationHandler(&mockMigrationService{
+			getStatusFn: func() (*service.MigrationStatusResult, error) {
+				return &service.MigrationStatusResult{
+					Migrations: []service.MigrationState{
+						{Version: "001_create_tenants_table", AppliedAt: &appliedAt},
+						{Version: "002_create_user_pools_table"},
+					},
+					Pending: 1,
+				}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.GetStatus(w, httptest.NewRequest(http.MethodGet, "/admin/migrations", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var body struct {
+			Data dto.MigrationStatusResponseDTO `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, 1, body.Data.Pending)
+		require.Len(t, body.Data.Migrations, 2)
+		assert.NotNil(t, body.Data.Migrations[0].AppliedAt)
+		assert.Nil(t, body.Data.Migrations[1].AppliedAt)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewMigrationHandler(&mockMigrationService{
+			getStatusFn: func() (*service.MigrationStatusResult, error) { return nil, errors.New("db down") },
+		})
+		w := httptest.NewRecorder()
+		h.GetStatus(w, httptest.NewRequest(http.MethodGet, "/admin/migrations", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestMigrationHandler_Apply(t *testing.T) {
+	t.Run("nothing pending", func(t *testing.T) {
+		h := NewMigrationHandler(&mockMigrationService{})
+		w := httptest.NewRecorder()
+		h.Apply(w, httptest.NewRequest(http.MethodPost, "/admin/migrations/apply", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"applied":[]`)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewMigrationHandler(&mockMigrationService{
+			applyFn: func() ([]string, error) { return nil, errors.New("failed") },
+		})
+		w := httptest.NewRecorder()
+		h.Apply(w, httptest.NewRequest(http.MethodPost, "/admin/migrations/apply", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -6,11 +6,17 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/crypto"
 	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/eventbus"
+	"github.com/maintainerd/auth/internal/hook"
+	"github.com/maintainerd/auth/internal/logging"
+	"github.com/maintainerd/auth/internal/middleware"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/repository"
 	"github.com/maintainerd/auth/internal/service"
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
 // ---------------------------------------------------------------------------
@@ -23,13 +29,13 @@ type mockLoginService struct {
 	getUserByEmailFn func(string, int64) (*model.User, error)
 }
 
-func (m *mockLoginService) LoginPublic(_ context.Context, u, p, c, pr string) (*dto.LoginResponseDTO, error) {
+func (m *mockLoginService) LoginPublic(_ context.Context, u, p, c, pr string, _ []uuid.UUID) (*dto.LoginResponseDTO, error) {
 	if m.loginPublicFn != nil {
 		return m.loginPublicFn(u, p, c, pr)
 	}
 	return nil, nil
 }
-func (m *mockLoginService) Login(_ context.Context, u, p string, c, pr *string) (*dto.LoginResponseDTO, error) {
+func (m *mockLoginService) Login(_ context.Context, u, p string, c, pr *string, _ []uuid.UUID) (*dto.LoginResponseDTO, error) {
 	if m.loginFn != nil {
 		return m.loginFn(u, p, c, pr)
 	}
@@ -42,6 +48,36 @@ func (m *mockLoginService) GetUserByEmail(_ context.Context, e string, tid int64
 	return nil, nil
 }
 
+// ---------------------------------------------------------------------------
+// mockFederatedLoginService
+// ---------------------------------------------------------------------------
+
+type mockFederatedLoginService struct {
+	loginFn func(idToken, clientID, providerID string) (*dto.LoginResponseDTO, error)
+}
+
+func (m *mockFederatedLoginService) Login(_ context.Context, idToken, clientID, providerID string) (*dto.LoginResponseDTO, error) {
+	if m.loginFn != nil {
+		return m.loginFn(idToken, clientID, providerID)
+	}
+	return &dto.LoginResponseDTO{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockHomeRealmService
+// ---------------------------------------------------------------------------
+
+type mockHomeRealmService struct {
+	identifyFn func(email, clientID, providerID string) (*dto.HomeRealmResponseDTO, error)
+}
+
+func (m *mockHomeRealmService) Identify(_ context.Context, email, clientID, providerID string) (*dto.HomeRealmResponseDTO, error) {
+	if m.identifyFn != nil {
+		return m.identifyFn(email, clientID, providerID)
+	}
+	return &dto.HomeRealmResponseDTO{}, nil
+}
+
 // ---------------------------------------------------------------------------
 // mockRegisterService
 // ---------------------------------------------------------------------------
@@ -51,27 +87,36 @@ type mockRegisterService struct {
 	registerInvitePublicFn func(string, string, string, string, string) (*dto.RegisterResponseDTO, error)
 	registerFn             func(string, string, string, *string, *string, *string, *string) (*dto.RegisterResponseDTO, error)
 	registerInviteFn       func(string, string, string, *string, *string) (*dto.RegisterResponseDTO, error)
+	verifyEmailFn          func(string, string, string, string) (*dto.RegisterResponseDTO, error)
+	lastSignupFlow         service.SignupFlowInput
 }
 
-func (m *mockRegisterService) RegisterPublic(_ context.Context, u, f, p string, e, ph *string, c, pr string) (*dto.RegisterResponseDTO, error) {
+func (m *mockRegisterService) RegisterPublic(_ context.Context, u, f, p string, e, ph *string, c, pr string, flow service.SignupFlowInput, _ []uuid.UUID) (*dto.RegisterResponseDTO, error) {
+	m.lastSignupFlow = flow
 	if m.registerPublicFn != nil {
 		return m.registerPublicFn(u, f, p, e, ph, c, pr)
 	}
 	return nil, nil
 }
-func (m *mockRegisterService) RegisterInvitePublic(_ context.Context, u, p, c, pr, t string) (*dto.RegisterResponseDTO, error) {
+func (m *mockRegisterService) VerifyEmail(_ context.Context, e, code, c, pr string) (*dto.RegisterResponseDTO, error) {
+	if m.verifyEmailFn != nil {
+		return m.verifyEmailFn(e, code, c, pr)
+	}
+	return nil, nil
+}
+func (m *mockRegisterService) RegisterInvitePublic(_ context.Context, u, p, c, pr, t string, _ []uuid.UUID) (*dto.RegisterResponseDTO, error) {
 	if m.registerInvitePublicFn != nil {
 		return m.registerInvitePublicFn(u, p, c, pr, t)
 	}
 	return nil, nil
 }
-func (m *mockRegisterService) Register(_ context.Context, u, f, p string, e, ph, c, pr *string) (*dto.RegisterResponseDTO, error) {
+func (m *mockRegisterService) Register(_ context.Context, u, f, p string, e, ph, c, pr *string, _ []uuid.UUID) (*dto.RegisterResponseDTO, error) {
 	if m.registerFn != nil {
 		return m.registerFn(u, f, p, e, ph, c, pr)
 	}
 	return nil, nil
 }
-func (m *mockRegisterService) RegisterInvite(_ context.Context, u, p, t string, c, pr *string) (*dto.RegisterResponseDTO, error) {
+func (m *mockRegisterService) RegisterInvite(_ context.Context, u, p, t string, c, pr *string, _ []uuid.UUID) (*dto.RegisterResponseDTO, error) {
 	if m.registerInviteFn != nil {
 		return m.registerInviteFn(u, p, t, c, pr)
 	}
@@ -152,10 +197,12 @@ type mockAPIService struct {
 	getFn                func(service.APIServiceGetFilter) (*service.APIServiceGetResult, error)
 	getByUUIDFn          func(uuid.UUID, int64) (*service.APIServiceDataResult, error)
 	getServiceIDByUUIDFn func(uuid.UUID) (int64, error)
-	createFn             func(int64, string, string, string, string, string, bool, string) (*service.APIServiceDataResult, error)
+	createFn             func(int64, string, string, string, string, string, bool, string, string) (*service.APIServiceDataResult, error)
 	updateFn             func(uuid.UUID, int64, string, string, string, string, string, string) (*service.APIServiceDataResult, error)
 	setStatusByUUIDFn    func(uuid.UUID, int64, string) (*service.APIServiceDataResult, error)
 	deleteByUUIDFn       func(uuid.UUID, int64) (*service.APIServiceDataResult, error)
+	setDeprecationFn     func(uuid.UUID, int64, *time.Time, *string) (*service.APIServiceDataResult, error)
+	clearDeprecationFn   func(uuid.UUID, int64) (*service.APIServiceDataResult, error)
 }
 
 func (m *mockAPIService) Get(_ context.Context, f service.APIServiceGetFilter) (*service.APIServiceGetResult, error) {
@@ -176,9 +223,9 @@ func (m *mockAPIService) GetServiceIDByUUID(_ context.Context, id uuid.UUID) (in
 	}
 	return 0, nil
 }
-func (m *mockAPIService) Create(_ context.Context, tid int64, n, dn, desc, t, s string, isSys bool, svcUUID string) (*service.APIServiceDataResult, error) {
+func (m *mockAPIService) Create(_ context.Context, tid int64, n, dn, desc, t, s string, isSys bool, svcUUID, identifier string) (*service.APIServiceDataResult, error) {
 	if m.createFn != nil {
-		return m.createFn(tid, n, dn, desc, t, s, isSys, svcUUID)
+		return m.createFn(tid, n, dn, desc, t, s, isSys, svcUUID, identifier)
 	}
 	return nil, nil
 }
@@ -200,6 +247,18 @@ func (m *mockAPIService) DeleteByUUID(_ context.Context, id uuid.UUID, tid int64
 	}
 	return nil, nil
 }
+func (m *mockAPIService) SetDeprecationByUUID(_ context.Context, id uuid.UUID, tid int64, sunsetAt *time.Time, notice *string) (*service.APIServiceDataResult, error) {
+	if m.setDeprecationFn != nil {
+		return m.setDeprecationFn(id, tid, sunsetAt, notice)
+	}
+	return nil, nil
+}
+func (m *mockAPIService) ClearDeprecationByUUID(_ context.Context, id uuid.UUID, tid int64) (*service.APIServiceDataResult, error) {
+	if m.clearDeprecationFn != nil {
+		return m.clearDeprecationFn(id, tid)
+	}
+	return nil, nil
+}
 
 // ---------------------------------------------------------------------------
 // mockAPIKeyService
@@ -213,13 +272,14 @@ type mockAPIKeyService struct {
 	updateFn              func(uuid.UUID, int64, *string, *string, datatypes.JSON, *time.Time, *int, *string, uuid.UUID) (*service.APIKeyServiceDataResult, error)
 	setStatusByUUIDFn     func(uuid.UUID, int64, string) (*service.APIKeyServiceDataResult, error)
 	deleteFn              func(uuid.UUID, int64, uuid.UUID) (*service.APIKeyServiceDataResult, error)
-	validateAPIKeyFn      func(string) (*service.APIKeyServiceDataResult, error)
-	getAPIKeyAPIsFn       func(uuid.UUID, int, int, string, string) (*service.APIKeyAPIServicePaginatedResult, error)
-	addAPIKeyAPIsFn       func(uuid.UUID, []uuid.UUID) error
-	removeAPIKeyAPIFn     func(uuid.UUID, uuid.UUID) error
-	getAPIKeyAPIPermsFn   func(uuid.UUID, uuid.UUID) ([]service.PermissionServiceDataResult, error)
-	addAPIKeyAPIPermsFn   func(uuid.UUID, uuid.UUID, []uuid.UUID) error
-	removeAPIKeyAPIPermFn func(uuid.UUID, uuid.UUID, uuid.UUID) error
+	validateAPIKeyFn      func(string) (*model.APIKey, error)
+	flushUsageFn          func() (int64, error)
+	getAPIKeyAPIsFn       func(uuid.UUID, int64, int, int, string, string) (*service.APIKeyAPIServicePaginatedResult, error)
+	addAPIKeyAPIsFn       func(uuid.UUID, int64, []uuid.UUID) error
+	removeAPIKeyAPIFn     func(uuid.UUID, int64, uuid.UUID) error
+	getAPIKeyAPIPermsFn   func(uuid.UUID, int64, uuid.UUID) ([]service.PermissionServiceDataResult, error)
+	addAPIKeyAPIPermsFn   func(uuid.UUID, int64, uuid.UUID, []uuid.UUID) error
+	removeAPIKeyAPIPermFn func(uuid.UUID, int64, uuid.UUID, uuid.UUID) error
 }
 
 func (m *mockAPIKeyService) Get(_ context.Context, f service.APIKeyServiceGetFilter, u uuid.UUID) (*service.APIKeyServiceGetResult, error) {
@@ -264,45 +324,51 @@ func (m *mockAPIKeyService) Delete(_ context.Context, id uuid.UUID, tid int64, u
 	}
 	return nil, nil
 }
-func (m *mockAPIKeyService) ValidateAPIKey(_ context.Context, k string) (*service.APIKeyServiceDataResult, error) {
+func (m *mockAPIKeyService) ValidateAPIKey(_ context.Context, k string) (*model.APIKey, error) {
 	if m.validateAPIKeyFn != nil {
 		return m.validateAPIKeyFn(k)
 	}
 	return nil, nil
 }
-func (m *mockAPIKeyService) GetAPIKeyAPIs(_ context.Context, id uuid.UUID, pg, lim int, sb, so string) (*service.APIKeyAPIServicePaginatedResult, error) {
+func (m *mockAPIKeyService) FlushUsage(_ context.Context) (int64, error) {
+	if m.flushUsageFn != nil {
+		return m.flushUsageFn()
+	}
+	return 0, nil
+}
+func (m *mockAPIKeyService) GetAPIKeyAPIs(_ context.Context, id uuid.UUID, tid int64, pg, lim int, sb, so string) (*service.APIKeyAPIServicePaginatedResult, error) {
 	if m.getAPIKeyAPIsFn != nil {
-		return m.getAPIKeyAPIsFn(id, pg, lim, sb, so)
+		return m.getAPIKeyAPIsFn(id, tid, pg, lim, sb, so)
 	}
 	return &service.APIKeyAPIServicePaginatedResult{}, nil
 }
-func (m *mockAPIKeyService) AddAPIKeyAPIs(_ context.Context, id uuid.UUID, apis []uuid.UUID) error {
+func (m *mockAPIKeyService) AddAPIKeyAPIs(_ context.Context, id uuid.UUID, tid int64, apis []uuid.UUID) error {
 	if m.addAPIKeyAPIsFn != nil {
-		return m.addAPIKeyAPIsFn(id, apis)
+		return m.addAPIKeyAPIsFn(id, tid, apis)
 	}
 	return nil
 }
-func (m *mockAPIKeyService) RemoveAPIKeyAPI(_ context.Context, id, api uuid.UUID) error {
+func (m *mockAPIKeyService) RemoveAPIKeyAPI(_ context.Context, id uuid.UUID, tid int64, api uuid.UUID) error {
 	if m.removeAPIKeyAPIFn != nil {
-		return m.removeAPIKeyAPIFn(id, api)
+		return m.removeAPIKeyAPIFn(id, tid, api)
 	}
 	return nil
 }
-func (m *mockAPIKeyService) GetAPIKeyAPIPermissions(_ context.Context, id, api uuid.UUID) ([]service.PermissionServiceDataResult, error) {
+func (m *mockAPIKeyService) GetAPIKeyAPIPermissions(_ context.Context, id uuid.UUID, tid int64, api uuid.UUID) ([]service.PermissionServiceDataResult, error) {
 	if m.getAPIKeyAPIPermsFn != nil {
-		return m.getAPIKeyAPIPermsFn(id, api)
+		return m.getAPIKeyAPIPermsFn(id, tid, api)
 	}
 	return nil, nil
 }
-func (m *mockAPIKeyService) AddAPIKeyAPIPermissions(_ context.Context, id, api uuid.UUID, perms []uuid.UUID) error {
+func (m *mockAPIKeyService) AddAPIKeyAPIPermissions(_ context.Context, id uuid.UUID, tid int64, api uuid.UUID, perms []uuid.UUID) error {
 	if m.addAPIKeyAPIPermsFn != nil {
-		return m.addAPIKeyAPIPermsFn(id, api, perms)
+		return m.addAPIKeyAPIPermsFn(id, tid, api, perms)
 	}
 	return nil
 }
-func (m *mockAPIKeyService) RemoveAPIKeyAPIPermission(_ context.Context, id, api, perm uuid.UUID) error {
+func (m *mockAPIKeyService) RemoveAPIKeyAPIPermission(_ context.Context, id uuid.UUID, tid int64, api, perm uuid.UUID) error {
 	if m.removeAPIKeyAPIPermFn != nil {
-		return m.removeAPIKeyAPIPermFn(id, api, perm)
+		return m.removeAPIKeyAPIPermFn(id, tid, api, perm)
 	}
 	return nil
 }
@@ -315,11 +381,16 @@ type mockClientService struct {
 	getFn                 func(service.ClientServiceGetFilter) (*service.ClientServiceGetResult, error)
 	getByUUIDFn           func(uuid.UUID, int64) (*service.ClientServiceDataResult, error)
 	getSecretByUUIDFn     func(uuid.UUID, int64) (*service.ClientSecretServiceDataResult, error)
+	revealSecretFn        func(uuid.UUID, int64, *model.User, string) (*service.ClientSecretServiceDataResult, error)
 	getConfigByUUIDFn     func(uuid.UUID, int64) (datatypes.JSON, error)
 	createFn              func(int64, string, string, string, string, datatypes.JSON, string, bool, string, uuid.UUID) (*service.ClientServiceDataResult, error)
 	updateFn              func(uuid.UUID, int64, string, string, string, string, datatypes.JSON, string, bool, uuid.UUID) (*service.ClientServiceDataResult, error)
 	setStatusByUUIDFn     func(uuid.UUID, int64, string, uuid.UUID) (*service.ClientServiceDataResult, error)
+	setDefaultByUUIDFn    func(uuid.UUID, int64, uuid.UUID) (*service.ClientServiceDataResult, error)
 	deleteByUUIDFn        func(uuid.UUID, int64, uuid.UUID) (*service.ClientServiceDataResult, error)
+	setDeprecationFn      func(uuid.UUID, int64, *time.Time, *string, uuid.UUID) (*service.ClientServiceDataResult, error)
+	clearDeprecationFn    func(uuid.UUID, int64, uuid.UUID) (*service.ClientServiceDataResult, error)
+	rotateSecretFn        func(uuid.UUID, int64, time.Duration, uuid.UUID) (*service.ClientSecretRotationServiceDataResult, error)
 	createURIFn           func(uuid.UUID, int64, string, string, uuid.UUID) (*service.ClientServiceDataResult, error)
 	updateURIFn           func(uuid.UUID, int64, uuid.UUID, string, string, uuid.UUID) (*service.ClientServiceDataResult, error)
 	deleteURIFn           func(uuid.UUID, int64, uuid.UUID, uuid.UUID) (*service.ClientServiceDataResult, error)
@@ -349,6 +420,12 @@ func (m *mockClientService) GetSecretByUUID(_ context.Context, id uuid.UUID, tid
 	}
 	return nil, nil
 }
+func (m *mockClientService) RevealSecretByUUID(_ context.Context, id uuid.UUID, tid int64, actor *model.User, password string) (*service.ClientSecretServiceDataResult, error) {
+	if m.revealSecretFn != nil {
+		return m.revealSecretFn(id, tid, actor, password)
+	}
+	return nil, nil
+}
 func (m *mockClientService) GetConfigByUUID(_ context.Context, id uuid.UUID, tid int64) (datatypes.JSON, error) {
 	if m.getConfigByUUIDFn != nil {
 		return m.getConfigByUUIDFn(id, tid)
@@ -373,12 +450,36 @@ func (m *mockClientService) SetStatusByUUID(_ context.Context, id uuid.UUID, tid
 	}
 	return nil, nil
 }
+func (m *mockClientService) SetDefaultByUUID(_ context.Context, id uuid.UUID, tid int64, actor uuid.UUID) (*service.ClientServiceDataResult, error) {
+	if m.setDefaultByUUIDFn != nil {
+		return m.setDefaultByUUIDFn(id, tid, actor)
+	}
+	return nil, nil
+}
 func (m *mockClientService) DeleteByUUID(_ context.Context, id uuid.UUID, tid int64, actor uuid.UUID) (*service.ClientServiceDataResult, error) {
 	if m.deleteByUUIDFn != nil {
 		return m.deleteByUUIDFn(id, tid, actor)
 	}
 	return nil, nil
 }
+func (m *mockClientService) SetDeprecationByUUID(_ context.Context, id uuid.UUID, tid int64, sunsetAt *time.Time, notice *string, actor uuid.UUID) (*service.ClientServiceDataResult, error) {
+	if m.setDeprecationFn != nil {
+		return m.setDeprecationFn(id, tid, sunsetAt, notice, actor)
+	}
+	return nil, nil
+}
+func (m *mockClientService) ClearDeprecationByUUID(_ context.Context, id uuid.UUID, tid int64, actor uuid.UUID) (*service.ClientServiceDataResult, error) {
+	if m.clearDeprecationFn != nil {
+		return m.clearDeprecationFn(id, tid, actor)
+	}
+	return nil, nil
+}
+func (m *mockClientService) RotateSecretByUUID(_ context.Context, id uuid.UUID, tid int64, grace time.Duration, actor uuid.UUID) (*service.ClientSecretRotationServiceDataResult, error) {
+	if m.rotateSecretFn != nil {
+		return m.rotateSecretFn(id, tid, grace, actor)
+	}
+	return nil, nil
+}
 func (m *mockClientService) CreateURI(_ context.Context, id uuid.UUID, tid int64, uri, uriType string, actor uuid.UUID) (*service.ClientServiceDataResult, error) {
 	if m.createURIFn != nil {
 		return m.createURIFn(id, tid, uri, uriType, actor)
@@ -439,14 +540,17 @@ func (m *mockClientService) RemoveClientAPIPermission(_ context.Context, tid int
 // ---------------------------------------------------------------------------
 
 type mockTenantService struct {
+	previewDeleteFn         func(uuid.UUID) (*service.DeleteImpactReport, error)
 	getFn                   func(service.TenantServiceGetFilter) (*service.TenantServiceGetResult, error)
 	getByUUIDFn             func(uuid.UUID) (*service.TenantServiceDataResult, error)
 	getSystemFn             func() (*service.TenantServiceDataResult, error)
 	getByIdentifierFn       func(string) (*service.TenantServiceDataResult, error)
-	createFn                func(string, string, string, string, bool) (*service.TenantServiceDataResult, error)
+	getAncestorIDsFn        func(uuid.UUID) ([]int64, error)
+	createFn                func(string, string, string, string, bool, *uuid.UUID) (*service.TenantServiceDataResult, error)
 	updateFn                func(uuid.UUID, string, string, string, string, bool) (*service.TenantServiceDataResult, error)
 	setStatusByUUIDFn       func(uuid.UUID, string) (*service.TenantServiceDataResult, error)
 	setActivePublicByUUIDFn func(uuid.UUID) (*service.TenantServiceDataResult, error)
+	setParentByUUIDFn       func(uuid.UUID, *uuid.UUID) (*service.TenantServiceDataResult, error)
 	deleteByUUIDFn          func(uuid.UUID) (*service.TenantServiceDataResult, error)
 }
 
@@ -474,9 +578,15 @@ func (m *mockTenantService) GetByIdentifier(_ context.Context, id string) (*serv
 	}
 	return nil, nil
 }
-func (m *mockTenantService) Create(_ context.Context, n, dn, desc, s string, isPublic bool) (*service.TenantServiceDataResult, error) {
+func (m *mockTenantService) GetAncestorIDs(_ context.Context, id uuid.UUID) ([]int64, error) {
+	if m.getAncestorIDsFn != nil {
+		return m.getAncestorIDsFn(id)
+	}
+	return nil, nil
+}
+func (m *mockTenantService) Create(_ context.Context, n, dn, desc, s string, isPublic bool, parent *uuid.UUID) (*service.TenantServiceDataResult, error) {
 	if m.createFn != nil {
-		return m.createFn(n, dn, desc, s, isPublic)
+		return m.createFn(n, dn, desc, s, isPublic, parent)
 	}
 	return nil, nil
 }
@@ -498,12 +608,24 @@ func (m *mockTenantService) SetActivePublicByUUID(_ context.Context, id uuid.UUI
 	}
 	return nil, nil
 }
+func (m *mockTenantService) SetParentByUUID(_ context.Context, id uuid.UUID, parent *uuid.UUID) (*service.TenantServiceDataResult, error) {
+	if m.setParentByUUIDFn != nil {
+		return m.setParentByUUIDFn(id, parent)
+	}
+	return nil, nil
+}
 func (m *mockTenantService) DeleteByUUID(_ context.Context, id uuid.UUID) (*service.TenantServiceDataResult, error) {
 	if m.deleteByUUIDFn != nil {
 		return m.deleteByUUIDFn(id)
 	}
 	return nil, nil
 }
+func (m *mockTenantService) PreviewDeleteByUUID(_ context.Context, id uuid.UUID) (*service.DeleteImpactReport, error) {
+	if m.previewDeleteFn != nil {
+		return m.previewDeleteFn(id)
+	}
+	return nil, nil
+}
 
 // ---------------------------------------------------------------------------
 // mockServiceService
@@ -518,6 +640,7 @@ type mockServiceService struct {
 	deleteByUUIDFn    func(uuid.UUID, int64) (*service.ServiceServiceDataResult, error)
 	assignPolicyFn    func(uuid.UUID, uuid.UUID, int64) error
 	removePolicyFn    func(uuid.UUID, uuid.UUID, int64) error
+	getPoliciesFn     func(uuid.UUID, int64) ([]service.PolicyServiceDataResult, error)
 }
 
 func (m *mockServiceService) Get(_ context.Context, f service.ServiceServiceGetFilter) (*service.ServiceServiceGetResult, error) {
@@ -568,18 +691,27 @@ func (m *mockServiceService) RemovePolicy(_ context.Context, svcID, polID uuid.U
 	}
 	return nil
 }
+func (m *mockServiceService) GetPolicies(_ context.Context, svcID uuid.UUID, tid int64) ([]service.PolicyServiceDataResult, error) {
+	if m.getPoliciesFn != nil {
+		return m.getPoliciesFn(svcID, tid)
+	}
+	return nil, nil
+}
 
 // ---------------------------------------------------------------------------
 // mockRoleService
 // ---------------------------------------------------------------------------
 
 type mockRoleService struct {
+	previewDeleteFn      func(uuid.UUID, int64, uuid.UUID) (*service.DeleteImpactReport, error)
 	getFn                func(service.RoleServiceGetFilter) (*service.RoleServiceGetResult, error)
 	getByUUIDFn          func(uuid.UUID, int64) (*service.RoleServiceDataResult, error)
 	getRolePermissionsFn func(service.RoleServiceGetPermissionsFilter) (*service.RoleServiceGetPermissionsResult, error)
 	createFn             func(string, string, bool, bool, string, string, uuid.UUID) (*service.RoleServiceDataResult, error)
 	updateFn             func(uuid.UUID, int64, string, string, bool, bool, string, uuid.UUID) (*service.RoleServiceDataResult, error)
 	setStatusByUUIDFn    func(uuid.UUID, int64, string, uuid.UUID) (*service.RoleServiceDataResult, error)
+	setDefaultByUUIDFn   func(uuid.UUID, int64, uuid.UUID) (*service.RoleServiceDataResult, error)
+	setInheritableFn     func(uuid.UUID, int64, bool, uuid.UUID) (*service.RoleServiceDataResult, error)
 	deleteByUUIDFn       func(uuid.UUID, int64, uuid.UUID) (*service.RoleServiceDataResult, error)
 	addRolePermsFn       func(uuid.UUID, int64, []uuid.UUID, uuid.UUID) (*service.RoleServiceDataResult, error)
 	removeRolePermsFn    func(uuid.UUID, int64, uuid.UUID, uuid.UUID) (*service.RoleServiceDataResult, error)
@@ -621,12 +753,30 @@ func (m *mockRoleService) SetStatusByUUID(_ context.Context, id uuid.UUID, tid i
 	}
 	return nil, nil
 }
+func (m *mockRoleService) SetInheritableByUUID(_ context.Context, id uuid.UUID, tid int64, inheritable bool, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
+	if m.setInheritableFn != nil {
+		return m.setInheritableFn(id, tid, inheritable, actor)
+	}
+	return nil, nil
+}
+func (m *mockRoleService) SetDefaultByUUID(_ context.Context, id uuid.UUID, tid int64, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
+	if m.setDefaultByUUIDFn != nil {
+		return m.setDefaultByUUIDFn(id, tid, actor)
+	}
+	return nil, nil
+}
 func (m *mockRoleService) DeleteByUUID(_ context.Context, id uuid.UUID, tid int64, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
 	if m.deleteByUUIDFn != nil {
 		return m.deleteByUUIDFn(id, tid, actor)
 	}
 	return nil, nil
 }
+func (m *mockRoleService) PreviewDeleteByUUID(_ context.Context, id uuid.UUID, tid int64, actor uuid.UUID) (*service.DeleteImpactReport, error) {
+	if m.previewDeleteFn != nil {
+		return m.previewDeleteFn(id, tid, actor)
+	}
+	return nil, nil
+}
 func (m *mockRoleService) AddRolePermissions(_ context.Context, id uuid.UUID, tid int64, perms []uuid.UUID, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
 	if m.addRolePermsFn != nil {
 		return m.addRolePermsFn(id, tid, perms, actor)
@@ -645,7 +795,13 @@ func (m *mockRoleService) RemoveRolePermissions(_ context.Context, id uuid.UUID,
 // ---------------------------------------------------------------------------
 
 type mockUserService struct {
+	previewDeleteFn   func(uuid.UUID, int64, uuid.UUID) (*service.DeleteImpactReport, error)
+	previewPurgeFn    func(uuid.UUID, int64, uuid.UUID) (*service.DeleteImpactReport, error)
 	getFn             func(service.UserServiceGetFilter) (*service.UserServiceGetResult, error)
+	searchFn          func(string, service.UserServiceGetFilter) (*service.UserServiceGetResult, error)
+	exportFn          func(service.UserServiceGetFilter, func(service.UserServiceDataResult) error) error
+	getByCursorFn     func(service.UserServiceGetFilter, string) (*service.CursorPageResult[service.UserServiceDataResult], error)
+	deltaFn           func(service.UserServiceDeltaFilter) (*service.UserServiceDeltaResult, error)
 	getByUUIDFn       func(uuid.UUID, int64) (*service.UserServiceDataResult, error)
 	createFn          func(string, string, *string, *string, string, string, datatypes.JSON, string, uuid.UUID) (*service.UserServiceDataResult, error)
 	updateFn          func(uuid.UUID, int64, string, string, *string, *string, string, datatypes.JSON, uuid.UUID) (*service.UserServiceDataResult, error)
@@ -654,10 +810,19 @@ type mockUserService struct {
 	verifyPhoneFn     func(uuid.UUID, int64) (*service.UserServiceDataResult, error)
 	completeAccountFn func(uuid.UUID, int64) (*service.UserServiceDataResult, error)
 	deleteByUUIDFn    func(uuid.UUID, int64, uuid.UUID) (*service.UserServiceDataResult, error)
+	restoreByUUIDFn   func(uuid.UUID, int64, uuid.UUID) (*service.UserServiceDataResult, error)
+	purgeByUUIDFn     func(uuid.UUID, int64, uuid.UUID) (*service.UserServiceDataResult, error)
 	assignUserRolesFn func(uuid.UUID, []uuid.UUID, int64) (*service.UserServiceDataResult, error)
 	removeUserRoleFn  func(uuid.UUID, uuid.UUID, int64) (*service.UserServiceDataResult, error)
 	getUserRolesFn    func(uuid.UUID) ([]service.RoleServiceDataResult, error)
-	getUserIdentsFn   func(uuid.UUID) ([]service.UserIdentityServiceDataResult, error)
+	listUserRolesFn   func(service.UserServiceGetRolesFilter) (*service.RoleServiceGetResult, error)
+	listUserIdentsFn  func(service.UserServiceGetIdentitiesFilter) (*service.UserIdentityServiceGetResult, error)
+	scheduleDeleteFn  func(uuid.UUID, int64) (*service.AccountDeletionServiceDataResult, error)
+	cancelDeleteFn    func(string) (*service.UserServiceDataResult, error)
+	disableSelfFn     func(uuid.UUID, int64) (*service.UserServiceDataResult, error)
+	reactivateSelfFn  func(string) (*service.UserServiceDataResult, error)
+	changePasswordFn  func(uuid.UUID, int64, string, string, string) error
+	revokeSessionsFn  func(uuid.UUID, int64) (int64, error)
 }
 
 func (m *mockUserService) Get(_ context.Context, f service.UserServiceGetFilter) (*service.UserServiceGetResult, error) {
@@ -666,6 +831,30 @@ func (m *mockUserService) Get(_ context.Context, f service.UserServiceGetFilter)
 	}
 	return &service.UserServiceGetResult{}, nil
 }
+func (m *mockUserService) Search(_ context.Context, q string, f service.UserServiceGetFilter) (*service.UserServiceGetResult, error) {
+	if m.searchFn != nil {
+		return m.searchFn(q, f)
+	}
+	return &service.UserServiceGetResult{}, nil
+}
+func (m *mockUserService) GetByCursor(_ context.Context, f service.UserServiceGetFilter, cursor string) (*service.CursorPageResult[service.UserServiceDataResult], error) {
+	if m.getByCursorFn != nil {
+		return m.getByCursorFn(f, cursor)
+	}
+	return &service.CursorPageResult[service.UserServiceDataResult]{}, nil
+}
+func (m *mockUserService) Export(_ context.Context, f service.UserServiceGetFilter, fn func(service.UserServiceDataResult) error) error {
+	if m.exportFn != nil {
+		return m.exportFn(f, fn)
+	}
+	return nil
+}
+func (m *mockUserService) Delta(_ context.Context, f service.UserServiceDeltaFilter) (*service.UserServiceDeltaResult, error) {
+	if m.deltaFn != nil {
+		return m.deltaFn(f)
+	}
+	return &service.UserServiceDeltaResult{}, nil
+}
 func (m *mockUserService) GetByUUID(_ context.Context, id uuid.UUID, tid int64) (*service.UserServiceDataResult, error) {
 	if m.getByUUIDFn != nil {
 		return m.getByUUIDFn(id, tid)
@@ -714,6 +903,67 @@ func (m *mockUserService) DeleteByUUID(_ context.Context, id uuid.UUID, tid int6
 	}
 	return nil, nil
 }
+func (m *mockUserService) PreviewDeleteByUUID(_ context.Context, id uuid.UUID, tid int64, deleter uuid.UUID) (*service.DeleteImpactReport, error) {
+	if m.previewDeleteFn != nil {
+		return m.previewDeleteFn(id, tid, deleter)
+	}
+	return nil, nil
+}
+func (m *mockUserService) RestoreByUUID(_ context.Context, id uuid.UUID, tid int64, restorer uuid.UUID) (*service.UserServiceDataResult, error) {
+	if m.restoreByUUIDFn != nil {
+		return m.restoreByUUIDFn(id, tid, restorer)
+	}
+	return nil, nil
+}
+func (m *mockUserService) PurgeByUUID(_ context.Context, id uuid.UUID, tid int64, purger uuid.UUID) (*service.UserServiceDataResult, error) {
+	if m.purgeByUUIDFn != nil {
+		return m.purgeByUUIDFn(id, tid, purger)
+	}
+	return nil, nil
+}
+func (m *mockUserService) PreviewPurgeByUUID(_ context.Context, id uuid.UUID, tid int64, purger uuid.UUID) (*service.DeleteImpactReport, error) {
+	if m.previewPurgeFn != nil {
+		return m.previewPurgeFn(id, tid, purger)
+	}
+	return nil, nil
+}
+func (m *mockUserService) PurgeExpired(_ context.Context) (int64, error) { return 0, nil }
+func (m *mockUserService) ScheduleSelfDeletion(_ context.Context, id uuid.UUID, tid int64) (*service.AccountDeletionServiceDataResult, error) {
+	if m.scheduleDeleteFn != nil {
+		return m.scheduleDeleteFn(id, tid)
+	}
+	return nil, nil
+}
+func (m *mockUserService) CancelSelfDeletion(_ context.Context, token string) (*service.UserServiceDataResult, error) {
+	if m.cancelDeleteFn != nil {
+		return m.cancelDeleteFn(token)
+	}
+	return nil, nil
+}
+func (m *mockUserService) DisableSelf(_ context.Context, id uuid.UUID, tid int64) (*service.UserServiceDataResult, error) {
+	if m.disableSelfFn != nil {
+		return m.disableSelfFn(id, tid)
+	}
+	return nil, nil
+}
+func (m *mockUserService) ReactivateSelf(_ context.Context, token string) (*service.UserServiceDataResult, error) {
+	if m.reactivateSelfFn != nil {
+		return m.reactivateSelfFn(token)
+	}
+	return nil, nil
+}
+func (m *mockUserService) RevokeSessions(_ context.Context, id uuid.UUID, tid int64) (int64, error) {
+	if m.revokeSessionsFn != nil {
+		return m.revokeSessionsFn(id, tid)
+	}
+	return 0, nil
+}
+func (m *mockUserService) ChangePassword(_ context.Context, id uuid.UUID, tid int64, current, next, refreshToken string) error {
+	if m.changePasswordFn != nil {
+		return m.changePasswordFn(id, tid, current, next, refreshToken)
+	}
+	return nil
+}
 func (m *mockUserService) AssignUserRoles(_ context.Context, id uuid.UUID, roles []uuid.UUID, tid int64) (*service.UserServiceDataResult, error) {
 	if m.assignUserRolesFn != nil {
 		return m.assignUserRolesFn(id, roles, tid)
@@ -732,11 +982,17 @@ func (m *mockUserService) GetUserRoles(_ context.Context, id uuid.UUID) ([]servi
 	}
 	return nil, nil
 }
-func (m *mockUserService) GetUserIdentities(_ context.Context, id uuid.UUID) ([]service.UserIdentityServiceDataResult, error) {
-	if m.getUserIdentsFn != nil {
-		return m.getUserIdentsFn(id)
+func (m *mockUserService) ListUserRoles(_ context.Context, f service.UserServiceGetRolesFilter) (*service.RoleServiceGetResult, error) {
+	if m.listUserRolesFn != nil {
+		return m.listUserRolesFn(f)
 	}
-	return nil, nil
+	return &service.RoleServiceGetResult{}, nil
+}
+func (m *mockUserService) ListUserIdentities(_ context.Context, f service.UserServiceGetIdentitiesFilter) (*service.UserIdentityServiceGetResult, error) {
+	if m.listUserIdentsFn != nil {
+		return m.listUserIdentsFn(f)
+	}
+	return &service.UserIdentityServiceGetResult{}, nil
 }
 
 func (m *mockUserService) FindBySubAndClientID(_ context.Context, sub, clientID string) (*model.User, error) {
@@ -755,6 +1011,8 @@ type mockProfileService struct {
 	getAllFn                 func(uuid.UUID, *string, *string, *string, *string, *string, *string, *bool, int, int, string, string) (*service.ProfileServiceListResult, error)
 	setDefaultFn             func(uuid.UUID, uuid.UUID) (*service.ProfileServiceDataResult, error)
 	deleteByUUIDFn           func(uuid.UUID, uuid.UUID) (*service.ProfileServiceDataResult, error)
+	setPictureFn             func(uuid.UUID, uuid.UUID, []byte) (*service.ProfileServiceDataResult, error)
+	deletePictureFn          func(uuid.UUID, uuid.UUID) (*service.ProfileServiceDataResult, error)
 }
 
 func (m *mockProfileService) CreateOrUpdateProfile(_ context.Context, userUUID uuid.UUID, firstName string, middleName, lastName, suffix, displayName, bio *string, birthdate *time.Time, gender, phone, email, address, city, country, timezone, language, profileURL *string, metadata map[string]any) (*service.ProfileServiceDataResult, error) {
@@ -799,6 +1057,18 @@ func (m *mockProfileService) DeleteByUUID(_ context.Context, profileUUID uuid.UU
 	}
 	return nil, nil
 }
+func (m *mockProfileService) SetPicture(_ context.Context, profileUUID uuid.UUID, userUUID uuid.UUID, data []byte) (*service.ProfileServiceDataResult, error) {
+	if m.setPictureFn != nil {
+		return m.setPictureFn(profileUUID, userUUID, data)
+	}
+	return nil, nil
+}
+func (m *mockProfileService) DeletePicture(_ context.Context, profileUUID uuid.UUID, userUUID uuid.UUID) (*service.ProfileServiceDataResult, error) {
+	if m.deletePictureFn != nil {
+		return m.deletePictureFn(profileUUID, userUUID)
+	}
+	return nil, nil
+}
 
 // ---------------------------------------------------------------------------
 // mockInviteService
@@ -1153,6 +1423,7 @@ type mockLoginTemplateService struct {
 	updateFn       func(uuid.UUID, int64, string, *string, string, map[string]any, string) (*service.LoginTemplateServiceDataResult, error)
 	updateStatusFn func(uuid.UUID, int64, string) (*service.LoginTemplateServiceDataResult, error)
 	deleteFn       func(uuid.UUID, int64) (*service.LoginTemplateServiceDataResult, error)
+	hostedPageFn   func(string, string) (*service.LoginTemplateHostedPageResult, error)
 }
 
 func (m *mockLoginTemplateService) GetAll(_ context.Context, tid int64, name *string, status []string, tmpl *string, isDefault, isSystem *bool, page, limit int, sortBy, sortOrder string) (*service.LoginTemplateServiceListResult, error) {
@@ -1191,6 +1462,12 @@ func (m *mockLoginTemplateService) Delete(_ context.Context, id uuid.UUID, tid i
 	}
 	return nil, nil
 }
+func (m *mockLoginTemplateService) GetHostedPage(_ context.Context, clientID, providerID string) (*service.LoginTemplateHostedPageResult, error) {
+	if m.hostedPageFn != nil {
+		return m.hostedPageFn(clientID, providerID)
+	}
+	return &service.LoginTemplateHostedPageResult{Template: model.LoginTemplateModern}, nil
+}
 
 // ---------------------------------------------------------------------------
 // mockIdentityProviderService
@@ -1374,8 +1651,8 @@ type mockTenantMemberService struct {
 	getByTenantAndUserFn func(int64, int64) (*service.TenantMemberServiceDataResult, error)
 	listByTenantFn       func(int64) ([]service.TenantMemberServiceDataResult, error)
 	listByUserFn         func(int64) ([]service.TenantMemberServiceDataResult, error)
-	updateRoleFn         func(uuid.UUID, string) (*service.TenantMemberServiceDataResult, error)
-	deleteByUUIDFn       func(uuid.UUID) error
+	updateRoleFn         func(int64, uuid.UUID, string) (*service.TenantMemberServiceDataResult, error)
+	deleteByUUIDFn       func(int64, uuid.UUID) error
 	isUserInTenantFn     func(int64, uuid.UUID) (bool, error)
 }
 
@@ -1415,15 +1692,15 @@ func (m *mockTenantMemberService) ListByUser(_ context.Context, userID int64) ([
 	}
 	return nil, nil
 }
-func (m *mockTenantMemberService) UpdateRole(_ context.Context, id uuid.UUID, role string) (*service.TenantMemberServiceDataResult, error) {
+func (m *mockTenantMemberService) UpdateRole(_ context.Context, tenantID int64, id uuid.UUID, role string) (*service.TenantMemberServiceDataResult, error) {
 	if m.updateRoleFn != nil {
-		return m.updateRoleFn(id, role)
+		return m.updateRoleFn(tenantID, id, role)
 	}
 	return nil, nil
 }
-func (m *mockTenantMemberService) DeleteByUUID(_ context.Context, id uuid.UUID) error {
+func (m *mockTenantMemberService) DeleteByUUID(_ context.Context, tenantID int64, id uuid.UUID) error {
 	if m.deleteByUUIDFn != nil {
-		return m.deleteByUUIDFn(id)
+		return m.deleteByUUIDFn(tenantID, id)
 	}
 	return nil
 }
@@ -1439,15 +1716,17 @@ func (m *mockTenantMemberService) IsUserInTenant(_ context.Context, userID int64
 // ---------------------------------------------------------------------------
 
 type mockUserSettingService struct {
-	createOrUpdateFn func(uuid.UUID, *string, *string, *string, map[string]any, *string, *bool, *bool, *bool, *string, *bool, *time.Time, *time.Time, *string, *string, *string, *string) (*service.UserSettingServiceDataResult, error)
+	createOrUpdateFn func(int64, uuid.UUID, *string, *string, *string, *string, *string, map[string]any, *string, *bool, *bool, *bool, *string, *bool, *time.Time, *time.Time, *string, *string, *string, *string) (*service.UserSettingServiceDataResult, error)
 	getByUUIDFn      func(uuid.UUID) (*service.UserSettingServiceDataResult, error)
 	getByUserUUIDFn  func(uuid.UUID) (*service.UserSettingServiceDataResult, error)
 	deleteByUUIDFn   func(uuid.UUID) (*service.UserSettingServiceDataResult, error)
+	getEffectiveFn   func(int64, uuid.UUID) (*service.UserSettingServiceDataResult, error)
+	resetFn          func(int64, uuid.UUID) (*service.UserSettingServiceDataResult, error)
 }
 
-func (m *mockUserSettingService) CreateOrUpdateUserSetting(_ context.Context, userUUID uuid.UUID, timezone, preferredLanguage, locale *string, socialLinks map[string]any, preferredContactMethod *string, marketingEmailConsent, smsNotificationsConsent, pushNotificationsConsent *bool, profileVisibility *string, dataProcessingConsent *bool, termsAcceptedAt, privacyPolicyAcceptedAt *time.Time, emergencyContactName, emergencyContactPhone, emergencyContactEmail, emergencyContactRelation *string) (*service.UserSettingServiceDataResult, error) {
+func (m *mockUserSettingService) CreateOrUpdateUserSetting(_ context.Context, tenantID int64, userUUID uuid.UUID, timezone, preferredLanguage, locale, theme, dateFormat *string, socialLinks map[string]any, preferredContactMethod *string, marketingEmailConsent, smsNotificationsConsent, pushNotificationsConsent *bool, profileVisibility *string, dataProcessingConsent *bool, termsAcceptedAt, privacyPolicyAcceptedAt *time.Time, emergencyContactName, emergencyContactPhone, emergencyContactEmail, emergencyContactRelation *string) (*service.UserSettingServiceDataResult, error) {
 	if m.createOrUpdateFn != nil {
-		return m.createOrUpdateFn(userUUID, timezone, preferredLanguage, locale, socialLinks, preferredContactMethod, marketingEmailConsent, smsNotificationsConsent, pushNotificationsConsent, profileVisibility, dataProcessingConsent, termsAcceptedAt, privacyPolicyAcceptedAt, emergencyContactName, emergencyContactPhone, emergencyContactEmail, emergencyContactRelation)
+		return m.createOrUpdateFn(tenantID, userUUID, timezone, preferredLanguage, locale, theme, dateFormat, socialLinks, preferredContactMethod, marketingEmailConsent, smsNotificationsConsent, pushNotificationsConsent, profileVisibility, dataProcessingConsent, termsAcceptedAt, privacyPolicyAcceptedAt, emergencyContactName, emergencyContactPhone, emergencyContactEmail, emergencyContactRelation)
 	}
 	return nil, nil
 }
@@ -1469,6 +1748,18 @@ func (m *mockUserSettingService) DeleteByUUID(_ context.Context, id uuid.UUID) (
 	}
 	return nil, nil
 }
+func (m *mockUserSettingService) GetEffective(_ context.Context, tenantID int64, userUUID uuid.UUID) (*service.UserSettingServiceDataResult, error) {
+	if m.getEffectiveFn != nil {
+		return m.getEffectiveFn(tenantID, userUUID)
+	}
+	return &service.UserSettingServiceDataResult{}, nil
+}
+func (m *mockUserSettingService) Reset(_ context.Context, tenantID int64, userUUID uuid.UUID) (*service.UserSettingServiceDataResult, error) {
+	if m.resetFn != nil {
+		return m.resetFn(tenantID, userUUID)
+	}
+	return &service.UserSettingServiceDataResult{}, nil
+}
 
 // ---------------------------------------------------------------------------
 // mockBrandingService
@@ -1497,15 +1788,19 @@ func (m *mockBrandingService) Update(_ context.Context, tid int64, companyName,
 // ---------------------------------------------------------------------------
 
 type mockTenantSettingService struct {
-	getFn                     func(int64) (*service.TenantSettingServiceDataResult, error)
-	getRateLimitConfigFn      func(int64) (map[string]any, error)
-	getAuditConfigFn          func(int64) (map[string]any, error)
-	getMaintenanceConfigFn    func(int64) (map[string]any, error)
-	getFeatureFlagsFn         func(int64) (map[string]any, error)
-	updateRateLimitConfigFn   func(int64, map[string]any) (*service.TenantSettingServiceDataResult, error)
-	updateAuditConfigFn       func(int64, map[string]any) (*service.TenantSettingServiceDataResult, error)
-	updateMaintenanceConfigFn func(int64, map[string]any) (*service.TenantSettingServiceDataResult, error)
-	updateFeatureFlagsFn      func(int64, map[string]any) (*service.TenantSettingServiceDataResult, error)
+	getFn                       func(int64) (*service.TenantSettingServiceDataResult, error)
+	getRateLimitConfigFn        func(int64) (map[string]any, error)
+	getAuditConfigFn            func(int64) (map[string]any, error)
+	getMaintenanceConfigFn      func(int64) (map[string]any, error)
+	getFeatureFlagsFn           func(int64) (map[string]any, error)
+	updateRateLimitConfigFn     func(int64, map[string]any) (*service.TenantSettingServiceDataResult, error)
+	updateAuditConfigFn         func(int64, map[string]any) (*service.TenantSettingServiceDataResult, error)
+	updateMaintenanceConfigFn   func(int64, map[string]any) (*service.TenantSettingServiceDataResult, error)
+	updateFeatureFlagsFn        func(int64, map[string]any) (*service.TenantSettingServiceDataResult, error)
+	getUserMetadataSchemaFn     func(int64) (map[string]any, error)
+	updateUserMetadataSchemaFn  func(int64, map[string]any) (*service.TenantSettingServiceDataResult, error)
+	getUserSettingDefaultsFn    func(int64) (map[string]any, error)
+	updateUserSettingDefaultsFn func(int64, model.UserSettingDefaults) (*service.TenantSettingServiceDataResult, error)
 }
 
 func (m *mockTenantSettingService) Get(_ context.Context, tid int64) (*service.TenantSettingServiceDataResult, error) {
@@ -1562,6 +1857,30 @@ func (m *mockTenantSettingService) UpdateFeatureFlags(_ context.Context, tid int
 	}
 	return nil, nil
 }
+func (m *mockTenantSettingService) GetUserMetadataSchema(_ context.Context, tid int64) (map[string]any, error) {
+	if m.getUserMetadataSchemaFn != nil {
+		return m.getUserMetadataSchemaFn(tid)
+	}
+	return nil, nil
+}
+func (m *mockTenantSettingService) UpdateUserMetadataSchema(_ context.Context, tid int64, schema map[string]any) (*service.TenantSettingServiceDataResult, error) {
+	if m.updateUserMetadataSchemaFn != nil {
+		return m.updateUserMetadataSchemaFn(tid, schema)
+	}
+	return nil, nil
+}
+func (m *mockTenantSettingService) GetUserSettingDefaults(_ context.Context, tid int64) (map[string]any, error) {
+	if m.getUserSettingDefaultsFn != nil {
+		return m.getUserSettingDefaultsFn(tid)
+	}
+	return nil, nil
+}
+func (m *mockTenantSettingService) UpdateUserSettingDefaults(_ context.Context, tid int64, defaults model.UserSettingDefaults) (*service.TenantSettingServiceDataResult, error) {
+	if m.updateUserSettingDefaultsFn != nil {
+		return m.updateUserSettingDefaultsFn(tid, defaults)
+	}
+	return nil, nil
+}
 
 // ---------------------------------------------------------------------------
 // mockEmailConfigService
@@ -1607,6 +1926,33 @@ func (m *mockSMSConfigService) Update(_ context.Context, tid int64, provider, ac
 	return nil, nil
 }
 
+// ---------------------------------------------------------------------------
+// mockWebhookDeliveryService
+// ---------------------------------------------------------------------------
+
+type mockWebhookDeliveryService struct {
+	getAllFn    func(int64, uuid.UUID, []string, []string, int, int) (*service.WebhookDeliveryServiceListResult, error)
+	getByUUIDFn func(int64, uuid.UUID, uuid.UUID) (*service.WebhookDeliveryServiceDataResult, error)
+}
+
+func (m *mockWebhookDeliveryService) GetAll(_ context.Context, tid int64, endpointUUID uuid.UUID, status, eventType []string, page, limit int) (*service.WebhookDeliveryServiceListResult, error) {
+	if m.getAllFn != nil {
+		return m.getAllFn(tid, endpointUUID, status, eventType, page, limit)
+	}
+	return &service.WebhookDeliveryServiceListResult{}, nil
+}
+func (m *mockWebhookDeliveryService) GetByUUID(_ context.Context, tid int64, endpointUUID, deliveryUUID uuid.UUID) (*service.WebhookDeliveryServiceDataResult, error) {
+	if m.getByUUIDFn != nil {
+		return m.getByUUIDFn(tid, endpointUUID, deliveryUUID)
+	}
+	return nil, nil
+}
+func (m *mockWebhookDeliveryService) HandleEvent(_ context.Context, _ eventbus.Event) error {
+	return nil
+}
+func (m *mockWebhookDeliveryService) Enqueue(_ context.Context) (int64, error)    { return 0, nil }
+func (m *mockWebhookDeliveryService) DeliverDue(_ context.Context) (int64, error) { return 0, nil }
+
 // ---------------------------------------------------------------------------
 // mockWebhookEndpointService
 // ---------------------------------------------------------------------------
@@ -1617,6 +1963,7 @@ type mockWebhookEndpointService struct {
 	createFn       func(int64, string, string, []string, *int, *int, string, string) (*service.WebhookEndpointServiceDataResult, error)
 	updateFn       func(int64, uuid.UUID, string, string, []string, *int, *int, string, string) (*service.WebhookEndpointServiceDataResult, error)
 	updateStatusFn func(int64, uuid.UUID, string) (*service.WebhookEndpointServiceDataResult, error)
+	rotateSecretFn func(int64, uuid.UUID, time.Duration) (*service.WebhookEndpointServiceDataResult, error)
 	deleteFn       func(int64, uuid.UUID) (*service.WebhookEndpointServiceDataResult, error)
 }
 
@@ -1650,6 +1997,12 @@ func (m *mockWebhookEndpointService) UpdateStatus(_ context.Context, tid int64,
 	}
 	return nil, nil
 }
+func (m *mockWebhookEndpointService) RotateSecret(_ context.Context, tid int64, id uuid.UUID, grace time.Duration) (*service.WebhookEndpointServiceDataResult, error) {
+	if m.rotateSecretFn != nil {
+		return m.rotateSecretFn(tid, id, grace)
+	}
+	return nil, nil
+}
 func (m *mockWebhookEndpointService) Delete(_ context.Context, tid int64, id uuid.UUID) (*service.WebhookEndpointServiceDataResult, error) {
 	if m.deleteFn != nil {
 		return m.deleteFn(tid, id)
@@ -1657,6 +2010,59 @@ func (m *mockWebhookEndpointService) Delete(_ context.Context, tid int64, id uui
 	return nil, nil
 }
 
+// ---------------------------------------------------------------------------
+// mockLoginHookService
+// ---------------------------------------------------------------------------
+
+type mockLoginHookService struct {
+	getAllFn       func(int64, []string, []string, int, int, string, string) (*service.LoginHookServiceListResult, error)
+	getByUUIDFn    func(int64, uuid.UUID) (*service.LoginHookServiceDataResult, error)
+	createFn       func(int64, service.LoginHookInput) (*service.LoginHookServiceDataResult, error)
+	updateFn       func(int64, uuid.UUID, service.LoginHookInput) (*service.LoginHookServiceDataResult, error)
+	updateStatusFn func(int64, uuid.UUID, string) (*service.LoginHookServiceDataResult, error)
+	deleteFn       func(int64, uuid.UUID) (*service.LoginHookServiceDataResult, error)
+}
+
+func (m *mockLoginHookService) GetAll(_ context.Context, tid int64, trigger, status []string, page, limit int, sortBy, sortOrder string) (*service.LoginHookServiceListResult, error) {
+	if m.getAllFn != nil {
+		return m.getAllFn(tid, trigger, status, page, limit, sortBy, sortOrder)
+	}
+	return &service.LoginHookServiceListResult{}, nil
+}
+func (m *mockLoginHookService) GetByUUID(_ context.Context, tid int64, id uuid.UUID) (*service.LoginHookServiceDataResult, error) {
+	if m.getByUUIDFn != nil {
+		return m.getByUUIDFn(tid, id)
+	}
+	return nil, nil
+}
+func (m *mockLoginHookService) Create(_ context.Context, tid int64, input service.LoginHookInput) (*service.LoginHookServiceDataResult, error) {
+	if m.createFn != nil {
+		return m.createFn(tid, input)
+	}
+	return nil, nil
+}
+func (m *mockLoginHookService) Update(_ context.Context, tid int64, id uuid.UUID, input service.LoginHookInput) (*service.LoginHookServiceDataResult, error) {
+	if m.updateFn != nil {
+		return m.updateFn(tid, id, input)
+	}
+	return nil, nil
+}
+func (m *mockLoginHookService) UpdateStatus(_ context.Context, tid int64, id uuid.UUID, status string) (*service.LoginHookServiceDataResult, error) {
+	if m.updateStatusFn != nil {
+		return m.updateStatusFn(tid, id, status)
+	}
+	return nil, nil
+}
+func (m *mockLoginHookService) Delete(_ context.Context, tid int64, id uuid.UUID) (*service.LoginHookServiceDataResult, error) {
+	if m.deleteFn != nil {
+		return m.deleteFn(tid, id)
+	}
+	return nil, nil
+}
+func (m *mockLoginHookService) Run(_ context.Context, _ int64, _ *int64, _ hook.Payload) (map[string]any, error) {
+	return nil, nil
+}
+
 // ---------------------------------------------------------------------------
 // mockAuthEventService
 // ---------------------------------------------------------------------------
@@ -1664,6 +2070,7 @@ func (m *mockWebhookEndpointService) Delete(_ context.Context, tid int64, id uui
 type mockAuthEventService struct {
 	logFn              func(ctx context.Context, input service.AuthEventInput)
 	findPaginatedFn    func(ctx context.Context, filter repository.AuthEventRepositoryGetFilter) (*repository.PaginationResult[service.AuthEventServiceDataResult], error)
+	findByCursorFn     func(ctx context.Context, filter repository.AuthEventRepositoryGetFilter, cursor string) (*service.CursorPageResult[service.AuthEventServiceDataResult], error)
 	findByUUIDFn       func(ctx context.Context, tenantID int64, eventUUID uuid.UUID) (*service.AuthEventServiceDataResult, error)
 	countByEventTypeFn func(ctx context.Context, eventType string, tenantID int64) (int64, error)
 	deleteOlderThanFn  func(ctx context.Context, cutoff time.Time) (int64, error)
@@ -1680,6 +2087,12 @@ func (m *mockAuthEventService) FindPaginated(ctx context.Context, filter reposit
 	}
 	return &repository.PaginationResult[service.AuthEventServiceDataResult]{}, nil
 }
+func (m *mockAuthEventService) FindByCursor(ctx context.Context, filter repository.AuthEventRepositoryGetFilter, cursor string) (*service.CursorPageResult[service.AuthEventServiceDataResult], error) {
+	if m.findByCursorFn != nil {
+		return m.findByCursorFn(ctx, filter, cursor)
+	}
+	return &service.CursorPageResult[service.AuthEventServiceDataResult]{}, nil
+}
 func (m *mockAuthEventService) FindByUUID(ctx context.Context, tenantID int64, eventUUID uuid.UUID) (*service.AuthEventServiceDataResult, error) {
 	if m.findByUUIDFn != nil {
 		return m.findByUUIDFn(ctx, tenantID, eventUUID)
@@ -1699,6 +2112,21 @@ func (m *mockAuthEventService) DeleteOlderThan(ctx context.Context, cutoff time.
 	return 0, nil
 }
 
+// ---------------------------------------------------------------------------
+// mockEventService
+// ---------------------------------------------------------------------------
+
+type mockEventService struct {
+	feedFn func(ctx context.Context, filter service.EventServiceFeedFilter) (*service.EventServiceFeedResult, error)
+}
+
+func (m *mockEventService) Feed(ctx context.Context, filter service.EventServiceFeedFilter) (*service.EventServiceFeedResult, error) {
+	if m.feedFn != nil {
+		return m.feedFn(ctx, filter)
+	}
+	return &service.EventServiceFeedResult{}, nil
+}
+
 // ---------------------------------------------------------------------------
 // mockOAuthAuthorizeService
 // ---------------------------------------------------------------------------
@@ -1783,3 +2211,1021 @@ func (m *mockOAuthConsentService) RevokeGrant(ctx context.Context, grantUUID uui
 	}
 	return nil
 }
+
+// ---------------------------------------------------------------------------
+// mockPersonalAccessTokenService
+// ---------------------------------------------------------------------------
+
+type mockPersonalAccessTokenService struct {
+	listFn     func(ctx context.Context, userID int64) ([]dto.PersonalAccessTokenResponseDTO, error)
+	createFn   func(ctx context.Context, user *model.User, tenantID int64, req dto.PersonalAccessTokenCreateRequestDTO) (*dto.PersonalAccessTokenCreateResponseDTO, error)
+	revokeFn   func(ctx context.Context, tokenUUID uuid.UUID, userID int64) error
+	validateFn func(ctx context.Context, token string) (*model.PersonalAccessToken, error)
+}
+
+func (m *mockPersonalAccessTokenService) List(ctx context.Context, userID int64) ([]dto.PersonalAccessTokenResponseDTO, error) {
+	if m.listFn != nil {
+		return m.listFn(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockPersonalAccessTokenService) Create(ctx context.Context, user *model.User, tenantID int64, req dto.PersonalAccessTokenCreateRequestDTO) (*dto.PersonalAccessTokenCreateResponseDTO, error) {
+	if m.createFn != nil {
+		return m.createFn(ctx, user, tenantID, req)
+	}
+	return &dto.PersonalAccessTokenCreateResponseDTO{}, nil
+}
+
+func (m *mockPersonalAccessTokenService) Revoke(ctx context.Context, tokenUUID uuid.UUID, userID int64) error {
+	if m.revokeFn != nil {
+		return m.revokeFn(ctx, tokenUUID, userID)
+	}
+	return nil
+}
+
+func (m *mockPersonalAccessTokenService) ValidatePersonalAccessToken(ctx context.Context, token string) (*model.PersonalAccessToken, error) {
+	if m.validateFn != nil {
+		return m.validateFn(ctx, token)
+	}
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockTrustedDeviceService
+// ---------------------------------------------------------------------------
+
+type mockTrustedDeviceService struct {
+	trustFn     func(ctx context.Context, user *model.User, tenantID int64, req dto.TrustedDeviceCreateRequestDTO) (*dto.TrustedDeviceCreateResponseDTO, error)
+	listFn      func(ctx context.Context, userID int64) ([]dto.TrustedDeviceResponseDTO, error)
+	revokeFn    func(ctx context.Context, deviceUUID uuid.UUID, userID int64) error
+	revokeAllFn func(ctx context.Context, userID int64) (int64, error)
+	isTrustedFn func(ctx context.Context, userID, tenantID int64, token string) bool
+}
+
+func (m *mockTrustedDeviceService) Trust(ctx context.Context, user *model.User, tenantID int64, req dto.TrustedDeviceCreateRequestDTO) (*dto.TrustedDeviceCreateResponseDTO, error) {
+	if m.trustFn != nil {
+		return m.trustFn(ctx, user, tenantID, req)
+	}
+	return &dto.TrustedDeviceCreateResponseDTO{}, nil
+}
+
+func (m *mockTrustedDeviceService) List(ctx context.Context, userID int64) ([]dto.TrustedDeviceResponseDTO, error) {
+	if m.listFn != nil {
+		return m.listFn(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockTrustedDeviceService) Revoke(ctx context.Context, deviceUUID uuid.UUID, userID int64) error {
+	if m.revokeFn != nil {
+		return m.revokeFn(ctx, deviceUUID, userID)
+	}
+	return nil
+}
+
+func (m *mockTrustedDeviceService) RevokeAll(ctx context.Context, userID int64) (int64, error) {
+	if m.revokeAllFn != nil {
+		return m.revokeAllFn(ctx, userID)
+	}
+	return 0, nil
+}
+
+func (m *mockTrustedDeviceService) IsTrusted(ctx context.Context, userID, tenantID int64, token string) bool {
+	if m.isTrustedFn != nil {
+		return m.isTrustedFn(ctx, userID, tenantID, token)
+	}
+	return false
+}
+
+// ---------------------------------------------------------------------------
+// mockAccountIdentityService
+// ---------------------------------------------------------------------------
+
+type mockAccountIdentityService struct {
+	listFn   func(ctx context.Context, userID, tenantID int64) ([]service.UserIdentityServiceDataResult, error)
+	linkFn   func(ctx context.Context, user *model.User, tenantID int64, req dto.AccountIdentityLinkRequestDTO) (*service.UserIdentityServiceDataResult, error)
+	unlinkFn func(ctx context.Context, user *model.User, tenantID int64, identityUUID uuid.UUID) error
+}
+
+func (m *mockAccountIdentityService) List(ctx context.Context, userID, tenantID int64) ([]service.UserIdentityServiceDataResult, error) {
+	if m.listFn != nil {
+		return m.listFn(ctx, userID, tenantID)
+	}
+	return nil, nil
+}
+
+func (m *mockAccountIdentityService) Link(ctx context.Context, user *model.User, tenantID int64, req dto.AccountIdentityLinkRequestDTO) (*service.UserIdentityServiceDataResult, error) {
+	if m.linkFn != nil {
+		return m.linkFn(ctx, user, tenantID, req)
+	}
+	return &service.UserIdentityServiceDataResult{}, nil
+}
+
+func (m *mockAccountIdentityService) Unlink(ctx context.Context, user *model.User, tenantID int64, identityUUID uuid.UUID) error {
+	if m.unlinkFn != nil {
+		return m.unlinkFn(ctx, user, tenantID, identityUUID)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// mockOnboardingService
+// ---------------------------------------------------------------------------
+
+type mockOnboardingService struct {
+	getChecklistFn func(uuid.UUID) (*service.OnboardingServiceChecklistResult, error)
+	onboardFn      func(uuid.UUID, service.OnboardingServiceOptions) (*service.OnboardingServiceResult, error)
+}
+
+func (m *mockOnboardingService) GetChecklist(_ context.Context, tenantUUID uuid.UUID) (*service.OnboardingServiceChecklistResult, error) {
+	if m.getChecklistFn != nil {
+		return m.getChecklistFn(tenantUUID)
+	}
+	return &service.OnboardingServiceChecklistResult{}, nil
+}
+func (m *mockOnboardingService) Onboard(_ context.Context, tenantUUID uuid.UUID, opts service.OnboardingServiceOptions) (*service.OnboardingServiceResult, error) {
+	if m.onboardFn != nil {
+		return m.onboardFn(tenantUUID, opts)
+	}
+	return &service.OnboardingServiceResult{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockSelfServiceTenantService
+// ---------------------------------------------------------------------------
+
+type mockSelfServiceTenantService struct {
+	createFn func(userID int64, name, displayName, description string) (*service.TenantServiceDataResult, error)
+}
+
+func (m *mockSelfServiceTenantService) Create(_ context.Context, userID int64, name, displayName, description string) (*service.TenantServiceDataResult, error) {
+	if m.createFn != nil {
+		return m.createFn(userID, name, displayName, description)
+	}
+	return &service.TenantServiceDataResult{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockTenantProvisioner
+// ---------------------------------------------------------------------------
+
+type mockTenantProvisioner struct {
+	requestDeprovisionFn func(tenantUUID uuid.UUID, userID int64, mode string) (*service.TenantDeprovisionRequestResult, error)
+	confirmDeprovisionFn func(tenantUUID uuid.UUID, userID int64, token string) (*service.TenantDeprovisionResult, error)
+}
+
+func (m *mockTenantProvisioner) Provision(_ context.Context, _ *gorm.DB, _ int64) error {
+	return nil
+}
+func (m *mockTenantProvisioner) Deprovision(_ context.Context, _ *gorm.DB, _ *model.Tenant) error {
+	return nil
+}
+func (m *mockTenantProvisioner) RequestDeprovision(_ context.Context, tenantUUID uuid.UUID, userID int64, mode string) (*service.TenantDeprovisionRequestResult, error) {
+	if m.requestDeprovisionFn != nil {
+		return m.requestDeprovisionFn(tenantUUID, userID, mode)
+	}
+	return &service.TenantDeprovisionRequestResult{}, nil
+}
+func (m *mockTenantProvisioner) ConfirmDeprovision(_ context.Context, tenantUUID uuid.UUID, userID int64, token string) (*service.TenantDeprovisionResult, error) {
+	if m.confirmDeprovisionFn != nil {
+		return m.confirmDeprovisionFn(tenantUUID, userID, token)
+	}
+	return &service.TenantDeprovisionResult{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockImpersonationService
+// ---------------------------------------------------------------------------
+
+type mockImpersonationService struct {
+	impersonateFn func(service.ImpersonationInput) (*dto.ImpersonationResponseDTO, error)
+}
+
+func (m *mockImpersonationService) Impersonate(_ context.Context, in service.ImpersonationInput) (*dto.ImpersonationResponseDTO, error) {
+	if m.impersonateFn != nil {
+		return m.impersonateFn(in)
+	}
+	return &dto.ImpersonationResponseDTO{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockSecurityHoldService
+// ---------------------------------------------------------------------------
+
+type mockSecurityHoldService struct {
+	holdFn func(userUUID uuid.UUID, tenantID int64, actorUserUUID uuid.UUID, reason string) (*service.UserServiceDataResult, error)
+}
+
+func (m *mockSecurityHoldService) Hold(_ context.Context, userUUID uuid.UUID, tenantID int64, actorUserUUID uuid.UUID, reason string) (*service.UserServiceDataResult, error) {
+	if m.holdFn != nil {
+		return m.holdFn(userUUID, tenantID, actorUserUUID, reason)
+	}
+	return &service.UserServiceDataResult{}, nil
+}
+
+func (m *mockSecurityHoldService) HoldSuspicious(_ context.Context, _ *model.User, _ int64, _ string) error {
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// mockDebugService
+// ---------------------------------------------------------------------------
+
+type mockDebugService struct {
+	setLogLevelFn      func(tenantID int64, component, level string) (*logging.ComponentLevel, error)
+	enableBodyTraceFn  func(in service.BodyTraceInput) (*logging.TraceRule, error)
+	disableBodyTraceFn func(traceUUID uuid.UUID) (*logging.TraceRule, error)
+}
+
+func (m *mockDebugService) GetLogLevels(_ context.Context) []logging.ComponentLevel {
+	return []logging.ComponentLevel{{Component: logging.ComponentAuth, Level: logging.DefaultLevel}}
+}
+
+func (m *mockDebugService) SetLogLevel(_ context.Context, tenantID int64, _ *model.User, component, level string) (*logging.ComponentLevel, error) {
+	if m.setLogLevelFn != nil {
+		return m.setLogLevelFn(tenantID, component, level)
+	}
+	return &logging.ComponentLevel{Component: component}, nil
+}
+
+func (m *mockDebugService) GetBodyTraces(_ context.Context) []logging.TraceRule {
+	return nil
+}
+
+func (m *mockDebugService) EnableBodyTrace(_ context.Context, _ int64, _ *model.User, in service.BodyTraceInput) (*logging.TraceRule, error) {
+	if m.enableBodyTraceFn != nil {
+		return m.enableBodyTraceFn(in)
+	}
+	return &logging.TraceRule{}, nil
+}
+
+func (m *mockDebugService) DisableBodyTrace(_ context.Context, _ int64, _ *model.User, traceUUID uuid.UUID) (*logging.TraceRule, error) {
+	if m.disableBodyTraceFn != nil {
+		return m.disableBodyTraceFn(traceUUID)
+	}
+	return &logging.TraceRule{TraceUUID: traceUUID}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockAuthzSimulationService
+// ---------------------------------------------------------------------------
+
+type mockAuthzSimulationService struct {
+	simulateFn func(in service.AuthzSimulationInput) (*service.AuthzSimulationResult, error)
+}
+
+func (m *mockAuthzSimulationService) Simulate(_ context.Context, in service.AuthzSimulationInput) (*service.AuthzSimulationResult, error) {
+	if m.simulateFn != nil {
+		return m.simulateFn(in)
+	}
+	return &service.AuthzSimulationResult{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockMigrationService
+// ---------------------------------------------------------------------------
+
+type mockMigrationService struct {
+	getStatusFn func() (*service.MigrationStatusResult, error)
+	applyFn     func() ([]string, error)
+}
+
+func (m *mockMigrationService) GetStatus(_ context.Context) (*service.MigrationStatusResult, error) {
+	if m.getStatusFn != nil {
+		return m.getStatusFn()
+	}
+	return &service.MigrationStatusResult{}, nil
+}
+
+func (m *mockMigrationService) Apply(_ context.Context) ([]string, error) {
+	if m.applyFn != nil {
+		return m.applyFn()
+	}
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockConfigService
+// ---------------------------------------------------------------------------
+
+type mockConfigService struct {
+	reloadFn func(tenantID int64) ([]string, error)
+}
+
+func (m *mockConfigService) Reload(_ context.Context, tenantID int64, _ *model.User) ([]string, error) {
+	if m.reloadFn != nil {
+		return m.reloadFn(tenantID)
+	}
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockQueueHealthService
+// ---------------------------------------------------------------------------
+
+type mockQueueHealthService struct {
+	getHealthFn func() (*service.QueueHealthResult, error)
+}
+
+func (m *mockQueueHealthService) Sample(_ context.Context) error { return nil }
+
+func (m *mockQueueHealthService) GetHealth(_ context.Context) (*service.QueueHealthResult, error) {
+	if m.getHealthFn != nil {
+		return m.getHealthFn()
+	}
+	return &service.QueueHealthResult{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockPermissionResolver
+// ---------------------------------------------------------------------------
+
+type mockPermissionResolver struct {
+	resolveFn func(ctx context.Context, user *model.User) (*dto.EffectivePermissionsResponseDTO, error)
+}
+
+func (m *mockPermissionResolver) Resolve(ctx context.Context, user *model.User) (*dto.EffectivePermissionsResponseDTO, error) {
+	if m.resolveFn != nil {
+		return m.resolveFn(ctx, user)
+	}
+	return &dto.EffectivePermissionsResponseDTO{}, nil
+}
+
+func (m *mockPermissionResolver) UserPermissions(ctx context.Context, user *model.User) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockPermissionResolver) ResourcePermissions(ctx context.Context, user *model.User, resourceType string, resourceUUID uuid.UUID) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockPermissionResolver) MemberPermissions(ctx context.Context, user *model.User, memberUUID uuid.UUID) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockPermissionResolver) HandleEvent(ctx context.Context, e eventbus.Event) error {
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// mockPermissionGroupService
+// ---------------------------------------------------------------------------
+
+type mockPermissionGroupService struct {
+	getFn              func(service.PermissionGroupServiceGetFilter) (*service.PermissionGroupServiceGetResult, error)
+	getByUUIDFn        func(id uuid.UUID, tid int64) (*service.PermissionGroupServiceDataResult, error)
+	createFn           func(tid int64, name, description string, perms []uuid.UUID) (*service.PermissionGroupServiceDataResult, error)
+	updateFn           func(id uuid.UUID, tid int64, name, description string) (*service.PermissionGroupServiceDataResult, error)
+	deleteFn           func(id uuid.UUID, tid int64) (*service.PermissionGroupServiceDataResult, error)
+	addPermissionsFn   func(id uuid.UUID, tid int64, perms []uuid.UUID) (*service.PermissionGroupServiceDataResult, error)
+	removePermissionFn func(id uuid.UUID, tid int64, perm uuid.UUID) (*service.PermissionGroupServiceDataResult, error)
+	expandFn           func(tid int64, groups []uuid.UUID, api *uuid.UUID) ([]uuid.UUID, error)
+}
+
+func (m *mockPermissionGroupService) Get(_ context.Context, f service.PermissionGroupServiceGetFilter) (*service.PermissionGroupServiceGetResult, error) {
+	if m.getFn != nil {
+		return m.getFn(f)
+	}
+	return &service.PermissionGroupServiceGetResult{}, nil
+}
+func (m *mockPermissionGroupService) GetByUUID(_ context.Context, id uuid.UUID, tid int64) (*service.PermissionGroupServiceDataResult, error) {
+	if m.getByUUIDFn != nil {
+		return m.getByUUIDFn(id, tid)
+	}
+	return &service.PermissionGroupServiceDataResult{}, nil
+}
+func (m *mockPermissionGroupService) Create(_ context.Context, tid int64, name, description string, perms []uuid.UUID) (*service.PermissionGroupServiceDataResult, error) {
+	if m.createFn != nil {
+		return m.createFn(tid, name, description, perms)
+	}
+	return &service.PermissionGroupServiceDataResult{}, nil
+}
+func (m *mockPermissionGroupService) Update(_ context.Context, id uuid.UUID, tid int64, name, description string) (*service.PermissionGroupServiceDataResult, error) {
+	if m.updateFn != nil {
+		return m.updateFn(id, tid, name, description)
+	}
+	return &service.PermissionGroupServiceDataResult{}, nil
+}
+func (m *mockPermissionGroupService) DeleteByUUID(_ context.Context, id uuid.UUID, tid int64) (*service.PermissionGroupServiceDataResult, error) {
+	if m.deleteFn != nil {
+		return m.deleteFn(id, tid)
+	}
+	return &service.PermissionGroupServiceDataResult{}, nil
+}
+func (m *mockPermissionGroupService) AddPermissions(_ context.Context, id uuid.UUID, tid int64, perms []uuid.UUID) (*service.PermissionGroupServiceDataResult, error) {
+	if m.addPermissionsFn != nil {
+		return m.addPermissionsFn(id, tid, perms)
+	}
+	return &service.PermissionGroupServiceDataResult{}, nil
+}
+func (m *mockPermissionGroupService) RemovePermission(_ context.Context, id uuid.UUID, tid int64, perm uuid.UUID) (*service.PermissionGroupServiceDataResult, error) {
+	if m.removePermissionFn != nil {
+		return m.removePermissionFn(id, tid, perm)
+	}
+	return &service.PermissionGroupServiceDataResult{}, nil
+}
+func (m *mockPermissionGroupService) ExpandPermissions(_ context.Context, tid int64, groups []uuid.UUID, api *uuid.UUID) ([]uuid.UUID, error) {
+	if m.expandFn != nil {
+		return m.expandFn(tid, groups, api)
+	}
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockGroupService
+// ---------------------------------------------------------------------------
+
+type mockGroupService struct {
+	getFn          func(service.GroupServiceGetFilter) (*service.GroupServiceGetResult, error)
+	getByUUIDFn    func(id uuid.UUID, tid int64) (*service.GroupServiceDataResult, error)
+	createFn       func(tid int64, name, description string) (*service.GroupServiceDataResult, error)
+	updateFn       func(id uuid.UUID, tid int64, name, description string) (*service.GroupServiceDataResult, error)
+	deleteFn       func(id uuid.UUID, tid int64) (*service.GroupServiceDataResult, error)
+	getMembersFn   func(id uuid.UUID, tid int64, page, limit int) (*service.UserServiceGetResult, error)
+	addMembersFn   func(id uuid.UUID, tid int64, users []uuid.UUID) (*service.GroupServiceDataResult, error)
+	removeMemberFn func(id uuid.UUID, tid int64, user uuid.UUID) (*service.GroupServiceDataResult, error)
+	addRolesFn     func(id uuid.UUID, tid int64, roles []uuid.UUID) (*service.GroupServiceDataResult, error)
+	removeRoleFn   func(id uuid.UUID, tid int64, role uuid.UUID) (*service.GroupServiceDataResult, error)
+}
+
+func (m *mockGroupService) Get(_ context.Context, f service.GroupServiceGetFilter) (*service.GroupServiceGetResult, error) {
+	if m.getFn != nil {
+		return m.getFn(f)
+	}
+	return &service.GroupServiceGetResult{}, nil
+}
+func (m *mockGroupService) GetByUUID(_ context.Context, id uuid.UUID, tid int64) (*service.GroupServiceDataResult, error) {
+	if m.getByUUIDFn != nil {
+		return m.getByUUIDFn(id, tid)
+	}
+	return &service.GroupServiceDataResult{}, nil
+}
+func (m *mockGroupService) Create(_ context.Context, tid int64, name, description string) (*service.GroupServiceDataResult, error) {
+	if m.createFn != nil {
+		return m.createFn(tid, name, description)
+	}
+	return &service.GroupServiceDataResult{}, nil
+}
+func (m *mockGroupService) Update(_ context.Context, id uuid.UUID, tid int64, name, description string) (*service.GroupServiceDataResult, error) {
+	if m.updateFn != nil {
+		return m.updateFn(id, tid, name, description)
+	}
+	return &service.GroupServiceDataResult{}, nil
+}
+func (m *mockGroupService) DeleteByUUID(_ context.Context, id uuid.UUID, tid int64) (*service.GroupServiceDataResult, error) {
+	if m.deleteFn != nil {
+		return m.deleteFn(id, tid)
+	}
+	return &service.GroupServiceDataResult{}, nil
+}
+func (m *mockGroupService) GetMembers(_ context.Context, id uuid.UUID, tid int64, page, limit int) (*service.UserServiceGetResult, error) {
+	if m.getMembersFn != nil {
+		return m.getMembersFn(id, tid, page, limit)
+	}
+	return &service.UserServiceGetResult{}, nil
+}
+func (m *mockGroupService) AddMembers(_ context.Context, id uuid.UUID, tid int64, users []uuid.UUID) (*service.GroupServiceDataResult, error) {
+	if m.addMembersFn != nil {
+		return m.addMembersFn(id, tid, users)
+	}
+	return &service.GroupServiceDataResult{}, nil
+}
+func (m *mockGroupService) RemoveMember(_ context.Context, id uuid.UUID, tid int64, user uuid.UUID) (*service.GroupServiceDataResult, error) {
+	if m.removeMemberFn != nil {
+		return m.removeMemberFn(id, tid, user)
+	}
+	return &service.GroupServiceDataResult{}, nil
+}
+func (m *mockGroupService) AddRoles(_ context.Context, id uuid.UUID, tid int64, roles []uuid.UUID) (*service.GroupServiceDataResult, error) {
+	if m.addRolesFn != nil {
+		return m.addRolesFn(id, tid, roles)
+	}
+	return &service.GroupServiceDataResult{}, nil
+}
+func (m *mockGroupService) RemoveRole(_ context.Context, id uuid.UUID, tid int64, role uuid.UUID) (*service.GroupServiceDataResult, error) {
+	if m.removeRoleFn != nil {
+		return m.removeRoleFn(id, tid, role)
+	}
+	return &service.GroupServiceDataResult{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockUserImportService
+// ---------------------------------------------------------------------------
+
+type mockUserImportService struct {
+	importFn func(tid int64, actor uuid.UUID, format string, dryRun bool, rows []service.UserImportRow) (*service.UserImportServiceDataResult, error)
+	getJobFn func(id uuid.UUID, tid int64) (*service.UserImportServiceDataResult, error)
+}
+
+func (m *mockUserImportService) Import(_ context.Context, tid int64, actor uuid.UUID, format string, dryRun bool, rows []service.UserImportRow) (*service.UserImportServiceDataResult, error) {
+	if m.importFn != nil {
+		return m.importFn(tid, actor, format, dryRun, rows)
+	}
+	return &service.UserImportServiceDataResult{Status: "completed"}, nil
+}
+func (m *mockUserImportService) GetJob(_ context.Context, id uuid.UUID, tid int64) (*service.UserImportServiceDataResult, error) {
+	if m.getJobFn != nil {
+		return m.getJobFn(id, tid)
+	}
+	return &service.UserImportServiceDataResult{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockSigningKeyService
+// ---------------------------------------------------------------------------
+
+type mockSigningKeyService struct {
+	listFn   func() ([]dto.SigningKeyResponseDTO, error)
+	rotateFn func() (*dto.SigningKeyResponseDTO, error)
+	retireFn func(signingKeyUUID uuid.UUID) (*dto.SigningKeyResponseDTO, error)
+}
+
+func (m *mockSigningKeyService) Load(_ context.Context) error               { return nil }
+func (m *mockSigningKeyService) RotateDue(_ context.Context) (int64, error) { return 0, nil }
+func (m *mockSigningKeyService) List(_ context.Context) ([]dto.SigningKeyResponseDTO, error) {
+	if m.listFn != nil {
+		return m.listFn()
+	}
+	return nil, nil
+}
+func (m *mockSigningKeyService) Rotate(_ context.Context) (*dto.SigningKeyResponseDTO, error) {
+	if m.rotateFn != nil {
+		return m.rotateFn()
+	}
+	return &dto.SigningKeyResponseDTO{}, nil
+}
+func (m *mockSigningKeyService) Retire(_ context.Context, signingKeyUUID uuid.UUID) (*dto.SigningKeyResponseDTO, error) {
+	if m.retireFn != nil {
+		return m.retireFn(signingKeyUUID)
+	}
+	return &dto.SigningKeyResponseDTO{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockDataKeyService
+// ---------------------------------------------------------------------------
+
+type mockDataKeyService struct {
+	listFn      func() ([]dto.DataKeyResponseDTO, error)
+	rotateFn    func() (*dto.DataKeyResponseDTO, error)
+	rewrapFn    func() (*dto.DataKeyRewrapResponseDTO, error)
+	reencryptFn func() (*dto.DataKeyReencryptResponseDTO, error)
+}
+
+func (m *mockDataKeyService) Keyring() *crypto.Keyring                   { return nil }
+func (m *mockDataKeyService) Load(_ context.Context) error               { return nil }
+func (m *mockDataKeyService) RotateDue(_ context.Context) (int64, error) { return 0, nil }
+func (m *mockDataKeyService) List(_ context.Context) ([]dto.DataKeyResponseDTO, error) {
+	if m.listFn != nil {
+		return m.listFn()
+	}
+	return nil, nil
+}
+func (m *mockDataKeyService) Rotate(_ context.Context) (*dto.DataKeyResponseDTO, error) {
+	if m.rotateFn != nil {
+		return m.rotateFn()
+	}
+	return &dto.DataKeyResponseDTO{}, nil
+}
+func (m *mockDataKeyService) Rewrap(_ context.Context) (*dto.DataKeyRewrapResponseDTO, error) {
+	if m.rewrapFn != nil {
+		return m.rewrapFn()
+	}
+	return &dto.DataKeyRewrapResponseDTO{}, nil
+}
+func (m *mockDataKeyService) Reencrypt(_ context.Context) (*dto.DataKeyReencryptResponseDTO, error) {
+	if m.reencryptFn != nil {
+		return m.reencryptFn()
+	}
+	return &dto.DataKeyReencryptResponseDTO{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockScopedRoleService
+// ---------------------------------------------------------------------------
+
+type mockScopedRoleService struct {
+	listFn   func(userUUID uuid.UUID, tid int64) ([]service.ScopedRoleServiceDataResult, error)
+	assignFn func(userUUID uuid.UUID, tid int64, roleUUID uuid.UUID, resourceType string, resourceUUID uuid.UUID) (*service.ScopedRoleServiceDataResult, error)
+	removeFn func(userUUID uuid.UUID, tid int64, scopedRoleUUID uuid.UUID) (*service.ScopedRoleServiceDataResult, error)
+}
+
+func (m *mockScopedRoleService) List(_ context.Context, userUUID uuid.UUID, tid int64) ([]service.ScopedRoleServiceDataResult, error) {
+	if m.listFn != nil {
+		return m.listFn(userUUID, tid)
+	}
+	return nil, nil
+}
+
+func (m *mockScopedRoleService) Assign(_ context.Context, userUUID uuid.UUID, tid int64, roleUUID uuid.UUID, resourceType string, resourceUUID uuid.UUID) (*service.ScopedRoleServiceDataResult, error) {
+	if m.assignFn != nil {
+		return m.assignFn(userUUID, tid, roleUUID, resourceType, resourceUUID)
+	}
+	return &service.ScopedRoleServiceDataResult{}, nil
+}
+
+func (m *mockScopedRoleService) Remove(_ context.Context, userUUID uuid.UUID, tid int64, scopedRoleUUID uuid.UUID) (*service.ScopedRoleServiceDataResult, error) {
+	if m.removeFn != nil {
+		return m.removeFn(userUUID, tid, scopedRoleUUID)
+	}
+	return &service.ScopedRoleServiceDataResult{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockNotificationService
+// ---------------------------------------------------------------------------
+
+type mockNotificationService struct {
+	getSettingsFn    func(tenantID int64, userUUID uuid.UUID) (*service.NotificationSettingServiceDataResult, error)
+	updateSettingsFn func(tenantID int64, userUUID uuid.UUID, input service.NotificationSettingInput) (*service.NotificationSettingServiceDataResult, error)
+	findLogsFn       func(userUUID *uuid.UUID, filter repository.NotificationLogRepositoryGetFilter) (*repository.PaginationResult[service.NotificationLogServiceDataResult], error)
+}
+
+func (m *mockNotificationService) Notify(_ context.Context, _ service.NotifyInput) {}
+func (m *mockNotificationService) GetSettings(_ context.Context, tenantID int64, userUUID uuid.UUID) (*service.NotificationSettingServiceDataResult, error) {
+	if m.getSettingsFn != nil {
+		return m.getSettingsFn(tenantID, userUUID)
+	}
+	return &service.NotificationSettingServiceDataResult{}, nil
+}
+func (m *mockNotificationService) UpdateSettings(_ context.Context, tenantID int64, userUUID uuid.UUID, input service.NotificationSettingInput) (*service.NotificationSettingServiceDataResult, error) {
+	if m.updateSettingsFn != nil {
+		return m.updateSettingsFn(tenantID, userUUID, input)
+	}
+	return &service.NotificationSettingServiceDataResult{}, nil
+}
+func (m *mockNotificationService) FindLogs(_ context.Context, userUUID *uuid.UUID, filter repository.NotificationLogRepositoryGetFilter) (*repository.PaginationResult[service.NotificationLogServiceDataResult], error) {
+	if m.findLogsFn != nil {
+		return m.findLogsFn(userUUID, filter)
+	}
+	return &repository.PaginationResult[service.NotificationLogServiceDataResult]{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockOAuthLogoutService
+// ---------------------------------------------------------------------------
+
+type mockOAuthLogoutService struct {
+	endSessionFn func(dto.OAuthEndSessionRequestDTO, *service.OAuthLogoutSession) (*dto.OAuthEndSessionResult, *apperror.OAuthError)
+}
+
+func (m *mockOAuthLogoutService) EndSession(_ context.Context, req dto.OAuthEndSessionRequestDTO, s *service.OAuthLogoutSession) (*dto.OAuthEndSessionResult, *apperror.OAuthError) {
+	if m.endSessionFn != nil {
+		return m.endSessionFn(req, s)
+	}
+	return &dto.OAuthEndSessionResult{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockStepUpService
+// ---------------------------------------------------------------------------
+
+type mockStepUpService struct {
+	reauthenticateFn func(user *model.User, tenantID int64, credential, password string) (*service.StepUpServiceDataResult, error)
+}
+
+func (m *mockStepUpService) StepUpMaxAge() time.Duration { return 5 * time.Minute }
+
+func (m *mockStepUpService) VerifyStepUp(context.Context, *middleware.AuthContext, string, string) (bool, error) {
+	return true, nil
+}
+
+func (m *mockStepUpService) Reauthenticate(_ context.Context, user *model.User, tenantID int64, credential, password string) (*service.StepUpServiceDataResult, error) {
+	if m.reauthenticateFn != nil {
+		return m.reauthenticateFn(user, tenantID, credential, password)
+	}
+	return &service.StepUpServiceDataResult{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockUsageService
+// ---------------------------------------------------------------------------
+
+type mockUsageService struct {
+	getUsageFn func(tenantUUID uuid.UUID, from, to time.Time) (*service.UsageServiceReport, error)
+}
+
+func (m *mockUsageService) MeterRequest(_ context.Context, _, _ int64) (bool, error) {
+	return true, nil
+}
+func (m *mockUsageService) MeterTokenIssued(_ context.Context, _ int64) (bool, error) {
+	return true, nil
+}
+func (m *mockUsageService) GetUsage(_ context.Context, tenantUUID uuid.UUID, from, to time.Time) (*service.UsageServiceReport, error) {
+	if m.getUsageFn != nil {
+		return m.getUsageFn(tenantUUID, from, to)
+	}
+	return &service.UsageServiceReport{TenantUUID: tenantUUID, From: from, To: to}, nil
+}
+func (m *mockUsageService) FlushUsage(_ context.Context) (int64, error) { return 0, nil }
+
+// ---------------------------------------------------------------------------
+// mockPlanService
+// ---------------------------------------------------------------------------
+
+type mockPlanService struct {
+	service.NopPlanEnforcer
+	listFn          func() ([]service.PlanServiceDataResult, error)
+	getByUUIDFn     func(planUUID uuid.UUID) (*service.PlanServiceDataResult, error)
+	createFn        func(input service.PlanServiceInput) (*service.PlanServiceDataResult, error)
+	updateFn        func(planUUID uuid.UUID, input service.PlanServiceInput) (*service.PlanServiceDataResult, error)
+	deleteFn        func(planUUID uuid.UUID) (*service.PlanServiceDataResult, error)
+	getTenantPlanFn func(tenantUUID uuid.UUID) (*service.PlanServiceTenantPlan, error)
+	setTenantPlanFn func(tenantUUID uuid.UUID, planUUID *uuid.UUID) (*service.PlanServiceTenantPlan, error)
+}
+
+func (m *mockPlanService) List(_ context.Context) ([]service.PlanServiceDataResult, error) {
+	if m.listFn != nil {
+		return m.listFn()
+	}
+	return nil, nil
+}
+func (m *mockPlanService) GetByUUID(_ context.Context, planUUID uuid.UUID) (*service.PlanServiceDataResult, error) {
+	if m.getByUUIDFn != nil {
+		return m.getByUUIDFn(planUUID)
+	}
+	return &service.PlanServiceDataResult{PlanUUID: planUUID}, nil
+}
+func (m *mockPlanService) Create(_ context.Context, input service.PlanServiceInput) (*service.PlanServiceDataResult, error) {
+	if m.createFn != nil {
+		return m.createFn(input)
+	}
+	return &service.PlanServiceDataResult{Name: input.Name}, nil
+}
+func (m *mockPlanService) Update(_ context.Context, planUUID uuid.UUID, input service.PlanServiceInput) (*service.PlanServiceDataResult, error) {
+	if m.updateFn != nil {
+		return m.updateFn(planUUID, input)
+	}
+	return &service.PlanServiceDataResult{PlanUUID: planUUID, Name: input.Name}, nil
+}
+func (m *mockPlanService) Delete(_ context.Context, planUUID uuid.UUID) (*service.PlanServiceDataResult, error) {
+	if m.deleteFn != nil {
+		return m.deleteFn(planUUID)
+	}
+	return &service.PlanServiceDataResult{PlanUUID: planUUID}, nil
+}
+func (m *mockPlanService) GetTenantPlan(_ context.Context, tenantUUID uuid.UUID) (*service.PlanServiceTenantPlan, error) {
+	if m.getTenantPlanFn != nil {
+		return m.getTenantPlanFn(tenantUUID)
+	}
+	return &service.PlanServiceTenantPlan{TenantUUID: tenantUUID}, nil
+}
+func (m *mockPlanService) SetTenantPlan(_ context.Context, tenantUUID uuid.UUID, planUUID *uuid.UUID) (*service.PlanServiceTenantPlan, error) {
+	if m.setTenantPlanFn != nil {
+		return m.setTenantPlanFn(tenantUUID, planUUID)
+	}
+	return &service.PlanServiceTenantPlan{TenantUUID: tenantUUID}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockFeatureFlagService
+// ---------------------------------------------------------------------------
+
+type mockFeatureFlagService struct {
+	listFn           func() ([]service.FeatureFlagServiceDataResult, error)
+	getByUUIDFn      func(flagUUID uuid.UUID) (*service.FeatureFlagServiceDataResult, error)
+	createFn         func(key string, input service.FeatureFlagServiceInput) (*service.FeatureFlagServiceDataResult, error)
+	updateFn         func(flagUUID uuid.UUID, input service.FeatureFlagServiceInput) (*service.FeatureFlagServiceDataResult, error)
+	deleteFn         func(flagUUID uuid.UUID) (*service.FeatureFlagServiceDataResult, error)
+	setOverrideFn    func(flagUUID, tenantUUID uuid.UUID, enabled bool) (*service.FeatureFlagServiceDataResult, error)
+	deleteOverrideFn func(flagUUID, tenantUUID uuid.UUID) (*service.FeatureFlagServiceDataResult, error)
+}
+
+func (m *mockFeatureFlagService) IsEnabled(_ context.Context, _ string, _ int64) bool { return false }
+func (m *mockFeatureFlagService) List(_ context.Context) ([]service.FeatureFlagServiceDataResult, error) {
+	if m.listFn != nil {
+		return m.listFn()
+	}
+	return nil, nil
+}
+func (m *mockFeatureFlagService) GetByUUID(_ context.Context, flagUUID uuid.UUID) (*service.FeatureFlagServiceDataResult, error) {
+	if m.getByUUIDFn != nil {
+		return m.getByUUIDFn(flagUUID)
+	}
+	return &service.FeatureFlagServiceDataResult{FeatureFlagUUID: flagUUID}, nil
+}
+func (m *mockFeatureFlagService) Create(_ context.Context, key string, input service.FeatureFlagServiceInput) (*service.FeatureFlagServiceDataResult, error) {
+	if m.createFn != nil {
+		return m.createFn(key, input)
+	}
+	return &service.FeatureFlagServiceDataResult{Key: key}, nil
+}
+func (m *mockFeatureFlagService) Update(_ context.Context, flagUUID uuid.UUID, input service.FeatureFlagServiceInput) (*service.FeatureFlagServiceDataResult, error) {
+	if m.updateFn != nil {
+		return m.updateFn(flagUUID, input)
+	}
+	return &service.FeatureFlagServiceDataResult{FeatureFlagUUID: flagUUID}, nil
+}
+func (m *mockFeatureFlagService) Delete(_ context.Context, flagUUID uuid.UUID) (*service.FeatureFlagServiceDataResult, error) {
+	if m.deleteFn != nil {
+		return m.deleteFn(flagUUID)
+	}
+	return &service.FeatureFlagServiceDataResult{FeatureFlagUUID: flagUUID}, nil
+}
+func (m *mockFeatureFlagService) SetOverride(_ context.Context, flagUUID, tenantUUID uuid.UUID, enabled bool) (*service.FeatureFlagServiceDataResult, error) {
+	if m.setOverrideFn != nil {
+		return m.setOverrideFn(flagUUID, tenantUUID, enabled)
+	}
+	return &service.FeatureFlagServiceDataResult{FeatureFlagUUID: flagUUID}, nil
+}
+func (m *mockFeatureFlagService) DeleteOverride(_ context.Context, flagUUID, tenantUUID uuid.UUID) (*service.FeatureFlagServiceDataResult, error) {
+	if m.deleteOverrideFn != nil {
+		return m.deleteOverrideFn(flagUUID, tenantUUID)
+	}
+	return &service.FeatureFlagServiceDataResult{FeatureFlagUUID: flagUUID}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockPolicyDocumentService
+// ---------------------------------------------------------------------------
+
+type mockPolicyDocumentService struct {
+	getAllFn             func(int64, []string, *bool, int, int, string, string) (*service.PolicyDocumentServiceListResult, error)
+	getByUUIDFn          func(int64, uuid.UUID) (*service.PolicyDocumentServiceDataResult, error)
+	getCurrentFn         func(string, string) ([]service.PolicyDocumentServiceDataResult, error)
+	createFn             func(int64, service.PolicyDocumentInput) (*service.PolicyDocumentServiceDataResult, error)
+	updateFn             func(int64, uuid.UUID, service.PolicyDocumentInput) (*service.PolicyDocumentServiceDataResult, error)
+	deleteFn             func(int64, uuid.UUID) (*service.PolicyDocumentServiceDataResult, error)
+	publishFn            func(int64, uuid.UUID) (*service.PolicyDocumentServiceDataResult, error)
+	getAcceptancesFn     func(int64, uuid.UUID, int, int) (*service.PolicyAcceptanceServiceListResult, error)
+	getUserAcceptancesFn func(int64, uuid.UUID, int, int) (*service.PolicyAcceptanceServiceListResult, error)
+}
+
+func (m *mockPolicyDocumentService) GetAll(_ context.Context, tid int64, types []string, published *bool, page, limit int, sortBy, sortOrder string) (*service.PolicyDocumentServiceListResult, error) {
+	if m.getAllFn != nil {
+		return m.getAllFn(tid, types, published, page, limit, sortBy, sortOrder)
+	}
+	return &service.PolicyDocumentServiceListResult{}, nil
+}
+func (m *mockPolicyDocumentService) GetByUUID(_ context.Context, tid int64, id uuid.UUID) (*service.PolicyDocumentServiceDataResult, error) {
+	if m.getByUUIDFn != nil {
+		return m.getByUUIDFn(tid, id)
+	}
+	return nil, nil
+}
+func (m *mockPolicyDocumentService) GetCurrent(_ context.Context, clientID, providerID string) ([]service.PolicyDocumentServiceDataResult, error) {
+	if m.getCurrentFn != nil {
+		return m.getCurrentFn(clientID, providerID)
+	}
+	return nil, nil
+}
+func (m *mockPolicyDocumentService) Create(_ context.Context, tid int64, input service.PolicyDocumentInput) (*service.PolicyDocumentServiceDataResult, error) {
+	if m.createFn != nil {
+		return m.createFn(tid, input)
+	}
+	return nil, nil
+}
+func (m *mockPolicyDocumentService) Update(_ context.Context, tid int64, id uuid.UUID, input service.PolicyDocumentInput) (*service.PolicyDocumentServiceDataResult, error) {
+	if m.updateFn != nil {
+		return m.updateFn(tid, id, input)
+	}
+	return nil, nil
+}
+func (m *mockPolicyDocumentService) Delete(_ context.Context, tid int64, id uuid.UUID) (*service.PolicyDocumentServiceDataResult, error) {
+	if m.deleteFn != nil {
+		return m.deleteFn(tid, id)
+	}
+	return nil, nil
+}
+func (m *mockPolicyDocumentService) Publish(_ context.Context, tid int64, id uuid.UUID) (*service.PolicyDocumentServiceDataResult, error) {
+	if m.publishFn != nil {
+		return m.publishFn(tid, id)
+	}
+	return nil, nil
+}
+func (m *mockPolicyDocumentService) GetAcceptances(_ context.Context, tid int64, id uuid.UUID, page, limit int) (*service.PolicyAcceptanceServiceListResult, error) {
+	if m.getAcceptancesFn != nil {
+		return m.getAcceptancesFn(tid, id, page, limit)
+	}
+	return &service.PolicyAcceptanceServiceListResult{}, nil
+}
+func (m *mockPolicyDocumentService) GetUserAcceptances(_ context.Context, tid int64, userUUID uuid.UUID, page, limit int) (*service.PolicyAcceptanceServiceListResult, error) {
+	if m.getUserAcceptancesFn != nil {
+		return m.getUserAcceptancesFn(tid, userUUID, page, limit)
+	}
+	return &service.PolicyAcceptanceServiceListResult{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockParentalConsentService
+// ---------------------------------------------------------------------------
+
+type mockParentalConsentService struct {
+	getFn     func(string) (*service.ParentalConsentServiceDataResult, error)
+	approveFn func(string) (*service.ParentalConsentServiceDataResult, error)
+	denyFn    func(string) (*service.ParentalConsentServiceDataResult, error)
+}
+
+func (m *mockParentalConsentService) Get(_ context.Context, token string) (*service.ParentalConsentServiceDataResult, error) {
+	if m.getFn != nil {
+		return m.getFn(token)
+	}
+	return nil, nil
+}
+func (m *mockParentalConsentService) Approve(_ context.Context, token string) (*service.ParentalConsentServiceDataResult, error) {
+	if m.approveFn != nil {
+		return m.approveFn(token)
+	}
+	return nil, nil
+}
+func (m *mockParentalConsentService) Deny(_ context.Context, token string) (*service.ParentalConsentServiceDataResult, error) {
+	if m.denyFn != nil {
+		return m.denyFn(token)
+	}
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockAccountRecoveryService
+// ---------------------------------------------------------------------------
+
+type mockAccountRecoveryService struct {
+	statusFn        func(int64) (*service.AccountRecoveryServiceDataResult, error)
+	generateCodesFn func(int64, int64, string) ([]string, error)
+	setEmailFn      func(int64, int64, string, string) (*service.AccountRecoveryServiceDataResult, error)
+	verifyEmailFn   func(int64, int64, string) (*service.AccountRecoveryServiceDataResult, error)
+	removeEmailFn   func(int64, int64) (*service.AccountRecoveryServiceDataResult, error)
+	startFn         func(string, string, string) error
+	completeFn      func(string, string, service.AccountRecoveryInput) (int64, error)
+}
+
+func (m *mockAccountRecoveryService) Status(_ context.Context, userID int64) (*service.AccountRecoveryServiceDataResult, error) {
+	if m.statusFn != nil {
+		return m.statusFn(userID)
+	}
+	return &service.AccountRecoveryServiceDataResult{}, nil
+}
+func (m *mockAccountRecoveryService) GenerateCodes(_ context.Context, userID, tenantID int64, password string) ([]string, error) {
+	if m.generateCodesFn != nil {
+		return m.generateCodesFn(userID, tenantID, password)
+	}
+	return nil, nil
+}
+func (m *mockAccountRecoveryService) SetRecoveryEmail(_ context.Context, userID, tenantID int64, password, email string) (*service.AccountRecoveryServiceDataResult, error) {
+	if m.setEmailFn != nil {
+		return m.setEmailFn(userID, tenantID, password, email)
+	}
+	return &service.AccountRecoveryServiceDataResult{}, nil
+}
+func (m *mockAccountRecoveryService) VerifyRecoveryEmail(_ context.Context, userID, tenantID int64, code string) (*service.AccountRecoveryServiceDataResult, error) {
+	if m.verifyEmailFn != nil {
+		return m.verifyEmailFn(userID, tenantID, code)
+	}
+	return &service.AccountRecoveryServiceDataResult{}, nil
+}
+func (m *mockAccountRecoveryService) RemoveRecoveryEmail(_ context.Context, userID, tenantID int64) (*service.AccountRecoveryServiceDataResult, error) {
+	if m.removeEmailFn != nil {
+		return m.removeEmailFn(userID, tenantID)
+	}
+	return &service.AccountRecoveryServiceDataResult{}, nil
+}
+func (m *mockAccountRecoveryService) StartRecovery(_ context.Context, clientID, providerID, email string) error {
+	if m.startFn != nil {
+		return m.startFn(clientID, providerID, email)
+	}
+	return nil
+}
+func (m *mockAccountRecoveryService) CompleteRecovery(_ context.Context, clientID, providerID string, in service.AccountRecoveryInput) (int64, error) {
+	if m.completeFn != nil {
+		return m.completeFn(clientID, providerID, in)
+	}
+	return 0, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockAuditArchiveService
+// ---------------------------------------------------------------------------
+
+type mockAuditArchiveService struct {
+	applyRetentionFn func(ctx context.Context) (int64, error)
+	runFn            func(ctx context.Context, tenantID int64) (*service.AuditArchiveRunServiceDataResult, error)
+	listRunsFn       func(ctx context.Context, tenantID int64, page, limit int) (*repository.PaginationResult[service.AuditArchiveRunServiceDataResult], error)
+	getRunFn         func(ctx context.Context, tenantID int64, runUUID uuid.UUID) (*service.AuditArchiveRunServiceDataResult, error)
+}
+
+func (m *mockAuditArchiveService) ApplyRetention(ctx context.Context) (int64, error) {
+	if m.applyRetentionFn != nil {
+		return m.applyRetentionFn(ctx)
+	}
+	return 0, nil
+}
+func (m *mockAuditArchiveService) Run(ctx context.Context, tenantID int64) (*service.AuditArchiveRunServiceDataResult, error) {
+	if m.runFn != nil {
+		return m.runFn(ctx, tenantID)
+	}
+	return &service.AuditArchiveRunServiceDataResult{}, nil
+}
+func (m *mockAuditArchiveService) ListRuns(ctx context.Context, tenantID int64, page, limit int) (*repository.PaginationResult[service.AuditArchiveRunServiceDataResult], error) {
+	if m.listRunsFn != nil {
+		return m.listRunsFn(ctx, tenantID, page, limit)
+	}
+	return &repository.PaginationResult[service.AuditArchiveRunServiceDataResult]{}, nil
+}
+func (m *mockAuditArchiveService) GetRun(ctx context.Context, tenantID int64, runUUID uuid.UUID) (*service.AuditArchiveRunServiceDataResult, error) {
+	if m.getRunFn != nil {
+		return m.getRunFn(ctx, tenantID, runUUID)
+	}
+	return &service.AuditArchiveRunServiceDataResult{}, nil
+}
@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// NotificationHandler serves users' notification preferences and the
+// notification delivery history.
+type NotificationHandler struct {
+	notificationService service.NotificationService
+}
+
+// NewNotificationHandler creates a new NotificationHandler.
+func NewNotificationHandler(notificationService service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// GetSettings returns the authenticated user's notification preferences.
+//
+// GET /account/notification-settings
+func (h *NotificationHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	setting, err := h.notificationService.GetSettings(r.Context(), auth.Tenant.TenantID, auth.User.UserUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get notification settings", err)
+		return
+	}
+
+	resp.Success(w, toNotificationSettingResponseDTO(setting), "Notification settings retrieved successfully")
+}
+
+// UpdateSettings changes the authenticated user's notification preferences.
+//
+// PUT /account/notification-settings
+func (h *NotificationHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	var req dto.NotificationSettingUpdateRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	input := service.NotificationSettingInput{
+		EmailEnabled: req.EmailEnabled,
+		SMSEnabled:   req.SMSEnabled,
+		InAppEnabled: req.InAppEnabled,
+	}
+	if req.MutedTypes != nil {
+		input.MutedTypes = &req.MutedTypes
+	}
+
+	setting, err := h.notificationService.UpdateSettings(r.Context(), auth.Tenant.TenantID, auth.User.UserUUID, input)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to update notification settings", err)
+		return
+	}
+
+	resp.Success(w, toNotificationSettingResponseDTO(setting), "Notification settings updated successfully")
+}
+
+// GetOwnLogs returns a page of the notifications sent to the authenticated
+// user.
+//
+// GET /account/notifications
+func (h *NotificationHandler) GetOwnLogs(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	filter, ok := notificationLogFilter(w, r, auth.Tenant.TenantID)
+	if !ok {
+		return
+	}
+	h.getLogs(w, r, &auth.User.UserUUID, filter)
+}
+
+// GetLogs returns a page of the tenant's notification deliveries, optionally
+// of one user given by the user_id query parameter.
+//
+// GET /notification-logs
+func (h *NotificationHandler) GetLogs(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	filter, ok := notificationLogFilter(w, r, tenant.TenantID)
+	if !ok {
+		return
+	}
+
+	var userUUID *uuid.UUID
+	if userID := r.URL.Query().Get("user_id"); userID != "" {
+		// Validated by notificationLogFilter
+		id := uuid.MustParse(userID)
+		userUUID = &id
+	}
+	h.getLogs(w, r, userUUID, filter)
+}
+
+func (h *NotificationHandler) getLogs(w http.ResponseWriter, r *http.Request, userUUID *uuid.UUID, filter repository.NotificationLogRepositoryGetFilter) {
+	result, err := h.notificationService.FindLogs(r.Context(), userUUID, filter)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get notification logs", err)
+		return
+	}
+
+	rows := make([]dto.NotificationLogResponseDTO, len(result.Data))
+	for i, log := range result.Data {
+		rows[i] = toNotificationLogResponseDTO(log)
+	}
+
+	resp.Success(w, dto.PaginatedResponseDTO[dto.NotificationLogResponseDTO]{
+		Rows:       rows,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}, "Notification logs retrieved successfully")
+}
+
+// notificationLogFilter reads and validates the query parameters of a
+// notification log listing, writing the error response when they are
+// invalid.
+func notificationLogFilter(w http.ResponseWriter, r *http.Request, tenantID int64) (repository.NotificationLogRepositoryGetFilter, bool) {
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+
+	filter := dto.NotificationLogFilterDTO{
+		UserID:           ptr.PtrOrNil(q.Get("user_id")),
+		NotificationType: ptr.PtrOrNil(q.Get("notification_type")),
+		Channel:          ptr.PtrOrNil(q.Get("channel")),
+		Status:           ptr.PtrOrNil(q.Get("status")),
+		DateFrom:         ptr.PtrOrNil(q.Get("date_from")),
+		DateTo:           ptr.PtrOrNil(q.Get("date_to")),
+		PaginationRequestDTO: dto.PaginationRequestDTO{
+			Page:      page,
+			Limit:     limit,
+			SortBy:    q.Get("sort_by"),
+			SortOrder: q.Get("sort_order"),
+		},
+	}
+
+	if err := filter.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return repository.NotificationLogRepositoryGetFilter{}, false
+	}
+
+	repoFilter := repository.NotificationLogRepositoryGetFilter{
+		TenantID:         tenantID,
+		NotificationType: filter.NotificationType,
+		Channel:          filter.Channel,
+		Status:           filter.Status,
+		SortBy:           filter.SortBy,
+		SortOrder:        filter.SortOrder,
+		Page:             filter.Page,
+		Limit:            filter.Limit,
+	}
+	if filter.DateFrom != nil {
+		if t, err := time.Parse(time.RFC3339, *filter.DateFrom); err == nil {
+			repoFilter.DateFrom = &t
+		}
+	}
+	if filter.DateTo != nil {
+		if t, err := time.Parse(time.RFC3339, *filter.DateTo); err == nil {
+			repoFilter.DateTo = &t
+		}
+	}
+	return repoFilter, true
+}
+
+func toNotificationSettingResponseDTO(s *service.NotificationSettingServiceDataResult) dto.NotificationSettingResponseDTO {
+	return dto.NotificationSettingResponseDTO{
+		EmailEnabled: s.EmailEnabled,
+		SMSEnabled:   s.SMSEnabled,
+		InAppEnabled: s.InAppEnabled,
+		MutedTypes:   s.MutedTypes,
+	}
+}
+
+func toNotificationLogResponseDTO(l service.NotificationLogServiceDataResult) dto.NotificationLogResponseDTO {
+	return dto.NotificationLogResponseDTO{
+		NotificationLogID: l.NotificationLogUUID.String(),
+		UserID:            l.UserUUID.String(),
+		NotificationType:  l.NotificationType,
+		Channel:           l.Channel,
+		Recipient:         l.Recipient,
+		Title:             l.Title,
+		Message:           l.Message,
+		Status:            l.Status,
+		ErrorReason:       l.ErrorReason,
+		CreatedAt:         l.CreatedAt,
+	}
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationHandler_GetSettings(t *testing.T) {
+	t.Run("no user", func(t *testing.T) {
+		h := NewNotificationHandler(&mockNotificationService{})
+		w := httptest.NewRecorder()
+		h.GetSettings(w, withTenant(httptest.NewRequest(http.MethodGet, "/", nil)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("no tenant", func(t *testing.T) {
+		h := NewNotificationHandler(&mockNotificationService{})
+		w := httptest.NewRecorder()
+		h.GetSettings(w, withUser(httptest.NewRequest(http.MethodGet, "/", nil)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewNotificationHandler(&mockNotificationService{
+			getSettingsFn: func(int64, uuid.UUID) (*service.NotificationSettingServiceDataResult, error) {
+				return nil, apperror.NewNotFound("user")
+			},
+		})
+		w := httptest.NewRecorder()
+		h.GetSettings(w, withTenantAndUser(httptest.NewRequest(http.MethodGet, "/", nil)))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewNotificationHandler(&mockNotificationService{
+			getSettingsFn: func(tid int64, userUUID uuid.UUID) (*service.NotificationSettingServiceDataResult, error) {
+				assert.Equal(t, tenantID, tid)
+				assert.Equal(t, testUserUUID, userUUID)
+				return &service.NotificationSettingServiceDataResult{EmailEnabled: true, MutedTypes: []string{}}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.GetSettings(w, withTenantAndUser(httptest.NewRequest(http.MethodGet, "/", nil)))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"email_enabled":true,"sms_enabled":false,"in_app_enabled":false,"muted_types":[]`)
+	})
+}
+
+func TestNotificationHandler_UpdateSettings(t *testing.T) {
+	t.Run("no user", func(t *testing.T) {
+		h := NewNotificationHandler(&mockNotificationService{})
+		w := httptest.NewRecorder()
+		h.UpdateSettings(w, withTenant(httptest.NewRequest(http.MethodPut, "/", nil)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		h := NewNotificationHandler(&mockNotificationService{})
+		w := httptest.NewRecorder()
+		h.UpdateSettings(w, withTenantAndUser(badJSONReq(t, http.MethodPut, "/")))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		h := NewNotificationHandler(&mockNotificationService{})
+		w := httptest.NewRecorder()
+		req := jsonReq(t, http.MethodPut, "/", dto.NotificationSettingUpdateRequestDTO{MutedTypes: []string{""}})
+		h.UpdateSettings(w, withTenantAndUser(req))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		var got service.NotificationSettingInput
+		h := NewNotificationHandler(&mockNotificationService{
+			updateSettingsFn: func(_ int64, _ uuid.UUID, input service.NotificationSettingInput) (*service.NotificationSettingServiceDataResult, error) {
+				got = input
+				return &service.NotificationSettingServiceDataResult{SMSEnabled: true, MutedTypes: []string{}}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.UpdateSettings(w, withTenantAndUser(jsonReq(t, http.MethodPut, "/", map[string]any{"sms_enabled": true})))
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.NotNil(t, got.SMSEnabled)
+		assert.True(t, *got.SMSEnabled)
+		assert.Nil(t, got.EmailEnabled)
+		assert.Nil(t, got.MutedTypes)
+	})
+
+	t.Run("empty muted types unmute everything", func(t *testing.T) {
+		var got service.NotificationSettingInput
+		h := NewNotificationHandler(&mockNotificationService{
+			updateSettingsFn: func(_ int64, _ uuid.UUID, input service.NotificationSettingInput) (*service.NotificationSettingServiceDataResult, error) {
+				got = input
+				return &service.NotificationSettingServiceDataResult{MutedTypes: []string{}}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.UpdateSettings(w, withTenantAndUser(jsonReq(t, http.MethodPut, "/", map[string]any{"muted_types": []string{}})))
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.NotNil(t, got.MutedTypes)
+		assert.Empty(t, *got.MutedTypes)
+	})
+}
+
+func TestNotificationHandler_GetOwnLogs(t *testing.T) {
+	t.Run("no user", func(t *testing.T) {
+		h := NewNotificationHandler(&mockNotificationService{})
+		w := httptest.NewRecorder()
+		h.GetOwnLogs(w, withTenant(httptest.NewRequest(http.MethodGet, "/?page=1&limit=10", nil)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		h := NewNotificationHandler(&mockNotificationService{})
+		w := httptest.NewRecorder()
+		h.GetOwnLogs(w, withTenantAndUser(httptest.NewRequest(http.MethodGet, "/?page=1&limit=10&channel=push", nil)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		logUUID := uuid.New()
+		h := NewNotificationHandler(&mockNotificationService{
+			findLogsFn: func(userUUID *uuid.UUID, filter repository.NotificationLogRepositoryGetFilter) (*repository.PaginationResult[service.NotificationLogServiceDataResult], error) {
+				require.NotNil(t, userUUID)
+				assert.Equal(t, testUserUUID, *userUUID)
+				assert.Equal(t, tenantID, filter.TenantID)
+				assert.Equal(t, "email", ptr.Deref(filter.Channel))
+				require.NotNil(t, filter.DateFrom)
+				return &repository.PaginationResult[service.NotificationLogServiceDataResult]{
+					Data: []service.NotificationLogServiceDataResult{{
+						NotificationLogUUID: logUUID,
+						UserUUID:            testUserUUID,
+						NotificationType:    "security.new_device",
+						Channel:             "email",
+						Status:              "failed",
+						ErrorReason:         ptr.Ptr("email_delivery_failed"),
+						CreatedAt:           time.Now(),
+					}},
+					Total: 1, Page: 1, Limit: 10, TotalPages: 1,
+				}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.GetOwnLogs(w, withTenantAndUser(httptest.NewRequest(http.MethodGet, "/?page=1&limit=10&channel=email&date_from=2026-01-01T00:00:00Z", nil)))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"notification_log_id":"`+logUUID.String()+`"`)
+		assert.Contains(t, w.Body.String(), `"error_reason":"email_delivery_failed"`)
+	})
+
+	t.Run("ignores user_id", func(t *testing.T) {
+		h := NewNotificationHandler(&mockNotificationService{
+			findLogsFn: func(userUUID *uuid.UUID, _ repository.NotificationLogRepositoryGetFilter) (*repository.PaginationResult[service.NotificationLogServiceDataResult], error) {
+				require.NotNil(t, userUUID)
+				assert.Equal(t, testUserUUID, *userUUID)
+				return &repository.PaginationResult[service.NotificationLogServiceDataResult]{}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.GetOwnLogs(w, withTenantAndUser(httptest.NewRequest(http.MethodGet, "/?page=1&limit=10&user_id="+uuid.NewString(), nil)))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestNotificationHandler_GetLogs(t *testing.T) {
+	t.Run("no tenant", func(t *testing.T) {
+		h := NewNotificationHandler(&mockNotificationService{})
+		w := httptest.NewRecorder()
+		h.GetLogs(w, httptest.NewRequest(http.MethodGet, "/?page=1&limit=10", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid user id", func(t *testing.T) {
+		h := NewNotificationHandler(&mockNotificationService{})
+		w := httptest.NewRecorder()
+		h.GetLogs(w, withTenant(httptest.NewRequest(http.MethodGet, "/?page=1&limit=10&user_id=nope", nil)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("whole tenant", func(t *testing.T) {
+		h := NewNotificationHandler(&mockNotificationService{
+			findLogsFn: func(userUUID *uuid.UUID, _ repository.NotificationLogRepositoryGetFilter) (*repository.PaginationResult[service.NotificationLogServiceDataResult], error) {
+				assert.Nil(t, userUUID)
+				return &repository.PaginationResult[service.NotificationLogServiceDataResult]{}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.GetLogs(w, withTenant(httptest.NewRequest(http.MethodGet, "/?page=1&limit=10", nil)))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("one user", func(t *testing.T) {
+		h := NewNotificationHandler(&mockNotificationService{
+			findLogsFn: func(userUUID *uuid.UUID, _ repository.NotificationLogRepositoryGetFilter) (*repository.PaginationResult[service.NotificationLogServiceDataResult], error) {
+				require.NotNil(t, userUUID)
+				assert.Equal(t, testResourceUUID, *userUUID)
+				return &repository.PaginationResult[service.NotificationLogServiceDataResult]{}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.GetLogs(w, withTenant(httptest.NewRequest(http.MethodGet, "/?page=1&limit=10&user_id="+testResourceUUID.String(), nil)))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewNotificationHandler(&mockNotificationService{
+			findLogsFn: func(*uuid.UUID, repository.NotificationLogRepositoryGetFilter) (*repository.PaginationResult[service.NotificationLogServiceDataResult], error) {
+				return nil, apperror.NewNotFound("user")
+			},
+		})
+		w := httptest.NewRecorder()
+		h.GetLogs(w, withTenant(httptest.NewRequest(http.MethodGet, "/?page=1&limit=10&user_id="+testResourceUUID.String(), nil)))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -49,6 +49,9 @@ func (h *OAuthAuthorizeHandler) Authorize(w http.ResponseWriter, r *http.Request
 		resp.ValidationError(w, err)
 		return
 	}
+	if claims := middleware.JWTClaimsFromRequest(r); claims != nil {
+		req.SessionID = claims.SessionID
+	}
 
 	result, oerr := h.authorizeService.Authorize(r.Context(), req, user.UserID)
 	if oerr != nil {
@@ -21,8 +21,8 @@ func NewOAuthConsentHandler(consentService service.OAuthConsentService) *OAuthCo
 	return &OAuthConsentHandler{consentService: consentService}
 }
 
-// ListGrants handles GET /oauth/consent/grants. Returns all consent grants
-// for the authenticated user.
+// ListGrants handles GET /oauth/consent/grants and GET /account/consents.
+// Returns all consent grants for the authenticated user.
 func (h *OAuthConsentHandler) ListGrants(w http.ResponseWriter, r *http.Request) {
 	user := middleware.AuthFromRequest(r).User
 	if user == nil {
@@ -39,8 +39,9 @@ func (h *OAuthConsentHandler) ListGrants(w http.ResponseWriter, r *http.Request)
 	resp.Success(w, grants, "Consent grants retrieved")
 }
 
-// RevokeGrant handles DELETE /oauth/consent/grants/{grant_uuid}. Removes an
-// existing consent grant.
+// RevokeGrant handles DELETE /oauth/consent/grants/{grant_uuid} and
+// DELETE /account/consents/{grant_uuid}. Removes an existing consent grant
+// and revokes the client's refresh tokens for the user.
 func (h *OAuthConsentHandler) RevokeGrant(w http.ResponseWriter, r *http.Request) {
 	user := middleware.AuthFromRequest(r).User
 	if user == nil {
@@ -39,6 +39,11 @@ func (h *OAuthDiscoveryHandler) Discovery(w http.ResponseWriter, r *http.Request
 		IDTokenSignAlgValues:  []string{"RS256"},
 		TokenEndpointAuth:     []string{"client_secret_basic", "client_secret_post", "none"},
 		CodeChallengeMethods:  []string{"S256"},
+		EndSessionEndpoint:    issuer + "/api/v1/oauth/end_session",
+		FrontchannelLogout:    true,
+		FrontchannelLogoutSID: true,
+		BackchannelLogout:     true,
+		BackchannelLogoutSID:  true,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -48,29 +53,29 @@ func (h *OAuthDiscoveryHandler) Discovery(w http.ResponseWriter, r *http.Request
 }
 
 // JWKS handles GET /.well-known/jwks.json (RFC 7517). Returns the public RSA
-// key used to verify JWTs.
+// keys JWTs are verified against: the current signing key first, then the
+// keys it replaced that have not been retired yet.
 func (h *OAuthDiscoveryHandler) JWKS(w http.ResponseWriter, r *http.Request) {
-	pubKey := jwt.GetPublicKey()
-	if pubKey == nil {
+	keys := jwt.VerificationKeys()
+	if len(keys) == 0 {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "keys not initialised"})
 		return
 	}
 
-	kid := config.GetEnvOrDefault("JWT_KEY_ID", "maintainerd-auth-key-1")
-
-	jwk := dto.JWKKeyDTO{
-		Kty: "RSA",
-		Use: "sig",
-		Kid: kid,
-		Alg: "RS256",
-		N:   base64URLEncodeUint(pubKey.N),
-		E:   base64URLEncodeUint(big.NewInt(int64(pubKey.E))),
-	}
-
 	result := dto.JWKSResponseDTO{
-		Keys: []dto.JWKKeyDTO{jwk},
+		Keys: make([]dto.JWKKeyDTO, 0, len(keys)),
+	}
+	for _, key := range keys {
+		result.Keys = append(result.Keys, dto.JWKKeyDTO{
+			Kty: "RSA",
+			Use: "sig",
+			Kid: key.ID,
+			Alg: "RS256",
+			N:   base64URLEncodeUint(key.PublicKey.N),
+			E:   base64URLEncodeUint(big.NewInt(int64(key.PublicKey.E))),
+		})
 	}
 
 	w.Header().Set("Content-Type", "application/json")
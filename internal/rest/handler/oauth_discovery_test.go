@@ -60,6 +60,11 @@ func TestOAuthDiscoveryHandler_Discovery(t *testing.T) {
 	assert.Equal(t, []string{"RS256"}, doc.IDTokenSignAlgValues)
 	assert.Equal(t, []string{"client_secret_basic", "client_secret_post", "none"}, doc.TokenEndpointAuth)
 	assert.Equal(t, []string{"S256"}, doc.CodeChallengeMethods)
+	assert.Equal(t, "https://auth.example.com/api/v1/oauth/end_session", doc.EndSessionEndpoint)
+	assert.True(t, doc.FrontchannelLogout)
+	assert.True(t, doc.FrontchannelLogoutSID)
+	assert.True(t, doc.BackchannelLogout)
+	assert.True(t, doc.BackchannelLogoutSID)
 }
 
 // ---------------------------------------------------------------------------
@@ -86,9 +91,8 @@ func TestOAuthDiscoveryHandler_JWKS_KeysNotInitialised(t *testing.T) {
 
 func TestOAuthDiscoveryHandler_JWKS_Success(t *testing.T) {
 	// Generate an RSA key pair and set it via config + init.
-	initTestJWTKeysForHandler(t)
-
 	t.Setenv("JWT_KEY_ID", "test-kid-1")
+	initTestJWTKeysForHandler(t)
 
 	h := NewOAuthDiscoveryHandler()
 	r := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
@@ -131,6 +135,30 @@ func TestOAuthDiscoveryHandler_JWKS_DefaultKeyID(t *testing.T) {
 	assert.Equal(t, "maintainerd-auth-key-1", jwks.Keys[0].Kid)
 }
 
+func TestOAuthDiscoveryHandler_JWKS_PublishesPreviousKeys(t *testing.T) {
+	t.Cleanup(jwt.ResetJWTKeys)
+	current, err := jwt.GenerateKey("key-2")
+	require.NoError(t, err)
+	previous, err := jwt.GenerateKey("key-1")
+	require.NoError(t, err)
+	require.NoError(t, jwt.SetKeys(current, previous))
+
+	h := NewOAuthDiscoveryHandler()
+	r := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+
+	h.JWKS(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var jwks dto.JWKSResponseDTO
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&jwks))
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "key-2", jwks.Keys[0].Kid)
+	assert.Equal(t, "key-1", jwks.Keys[1].Kid)
+	assert.NotEqual(t, jwks.Keys[0].N, jwks.Keys[1].N)
+}
+
 // initTestJWTKeysForHandler generates an RSA key pair, sets config vars,
 // and calls jwt.InitJWTKeys. It cleans up after the test.
 func initTestJWTKeysForHandler(t *testing.T) {
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cookie"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/hostedlogin"
+	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/security"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/maintainerd/auth/internal/session"
+)
+
+// OAuthLogoutHandler handles the OpenID Connect end_session endpoint.
+type OAuthLogoutHandler struct {
+	logoutService service.OAuthLogoutService
+	sessions      session.Store
+}
+
+// NewOAuthLogoutHandler creates a new OAuthLogoutHandler. sessions is the
+// browser session store, or nil when browser sessions are disabled.
+func NewOAuthLogoutHandler(logoutService service.OAuthLogoutService, sessions session.Store) *OAuthLogoutHandler {
+	return &OAuthLogoutHandler{logoutService: logoutService, sessions: sessions}
+}
+
+// EndSession handles GET and POST /oauth/end_session (OIDC RP-Initiated
+// Logout 1.0). It ends the session named by id_token_hint, or the caller's
+// own session, clears the browser's auth cookies and session, and then
+// renders a signed-out page that loads the front-channel logout URIs of the
+// session's clients before continuing to post_logout_redirect_uri.
+func (h *OAuthLogoutHandler) EndSession(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		oerr := apperror.NewOAuthInvalidRequest("malformed request body")
+		oerr.WriteJSON(w)
+		return
+	}
+
+	req := dto.OAuthEndSessionRequestDTO{
+		IDTokenHint:           r.FormValue("id_token_hint"),
+		ClientID:              r.FormValue("client_id"),
+		PostLogoutRedirectURI: r.FormValue("post_logout_redirect_uri"),
+		State:                 r.FormValue("state"),
+	}
+	if err := req.Validate(); err != nil {
+		oerr := apperror.NewOAuthInvalidRequest(err.Error())
+		oerr.WriteJSON(w)
+		return
+	}
+
+	caller := callerLogoutSession(r)
+	result, oerr := h.logoutService.EndSession(r.Context(), req, caller)
+	if oerr != nil {
+		oerr.WriteJSON(w)
+		return
+	}
+
+	if h.sessions != nil && middleware.BrowserSessionFromRequest(r) != nil {
+		if err := middleware.EndBrowserSession(w, r, h.sessions); err != nil {
+			resp.LoggerFromContext(r.Context()).Warn("end browser session failed", "error", err)
+		}
+	}
+	cookie.ClearAuthCookies(w)
+
+	sc := extractSecurityContext(r)
+	event := security.SecurityEvent{
+		EventType: "logout",
+		ClientID:  req.ClientID,
+		ClientIP:  sc.clientIP,
+		UserAgent: sc.userAgent,
+		RequestID: sc.requestID,
+		Endpoint:  "/oauth/end_session",
+		Method:    r.Method,
+		Timestamp: time.Now(),
+		Details:   "User signed out through the end_session endpoint",
+		Severity:  "LOW",
+	}
+	if caller != nil {
+		event.UserID = caller.Sub
+	}
+	security.LogSecurityEvent(event)
+
+	if len(result.FrontChannelLogoutURIs) == 0 && result.RedirectURI != "" {
+		http.Redirect(w, r, result.RedirectURI, http.StatusFound)
+		return
+	}
+
+	base := strings.TrimSuffix(strings.TrimSuffix(r.URL.Path, "/"), "oauth/end_session")
+	var buf bytes.Buffer
+	if err := hostedlogin.RenderLogout(&buf, hostedlogin.LogoutPage{
+		AssetsURL:              base + "login/assets/",
+		FrontChannelLogoutURIs: result.FrontChannelLogoutURIs,
+		RedirectURI:            result.RedirectURI,
+	}); err != nil {
+		resp.LoggerFromContext(r.Context()).Error("render logout page failed", "error", err)
+		resp.Error(w, http.StatusInternalServerError, "Failed to render logout page")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Security-Policy", logoutPageCSP(result.FrontChannelLogoutURIs))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// callerLogoutSession returns the login session of the browser calling the
+// end_session endpoint: its browser session or the access token in its
+// cookie. It returns nil for anonymous callers and invalid tokens.
+func callerLogoutSession(r *http.Request) *service.OAuthLogoutSession {
+	if claims := middleware.JWTClaimsFromRequest(r); claims != nil {
+		return &service.OAuthLogoutSession{Sub: claims.Sub, ClientID: claims.ClientID, SessionID: claims.SessionID}
+	}
+	c, err := r.Cookie("access_token")
+	if err != nil {
+		return nil
+	}
+	claims, err := jwt.ValidateToken(c.Value)
+	if err != nil {
+		return nil
+	}
+	sub, _ := claims["sub"].(string)
+	clientID, _ := claims["client_id"].(string)
+	sessionID, _ := claims["sid"].(string)
+	return &service.OAuthLogoutSession{Sub: sub, ClientID: clientID, SessionID: sessionID}
+}
+
+// logoutPageCSP is hostedLoginCSP with the origins of the front-channel
+// logout URIs allowed as frame sources.
+func logoutPageCSP(frontChannelURIs []string) string {
+	var origins []string
+	seen := map[string]bool{}
+	for _, uri := range frontChannelURIs {
+		u, err := url.Parse(uri)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		origin := u.Scheme + "://" + u.Host
+		if !seen[origin] {
+			seen[origin] = true
+			origins = append(origins, origin)
+		}
+	}
+	if len(origins) == 0 {
+		return hostedLoginCSP + "; frame-src 'none'"
+	}
+	return hostedLoginCSP + "; frame-src " + strings.Join(origins, " ")
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuthLogoutHandler_EndSession_Redirect(t *testing.T) {
+	var got dto.OAuthEndSessionRequestDTO
+	svc := &mockOAuthLogoutService{
+		endSessionFn: func(req dto.OAuthEndSessionRequestDTO, s *service.OAuthLogoutSession) (*dto.OAuthEndSessionResult, *apperror.OAuthError) {
+			got = req
+			assert.Nil(t, s)
+			return &dto.OAuthEndSessionResult{RedirectURI: "https://app.example.com/bye?state=xyz"}, nil
+		},
+	}
+	h := NewOAuthLogoutHandler(svc, nil)
+	r := withSecurityCtx(httptest.NewRequest(http.MethodGet,
+		"/api/v1/oauth/end_session?client_id=c1&post_logout_redirect_uri=https%3A%2F%2Fapp.example.com%2Fbye&state=xyz", nil))
+	w := httptest.NewRecorder()
+	h.EndSession(w, r)
+
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://app.example.com/bye?state=xyz", w.Header().Get("Location"))
+	assert.Equal(t, "c1", got.ClientID)
+	assert.Equal(t, "xyz", got.State)
+
+	var cleared []string
+	for _, c := range w.Result().Cookies() {
+		assert.Equal(t, -1, c.MaxAge)
+		cleared = append(cleared, c.Name)
+	}
+	assert.Contains(t, cleared, "access_token")
+}
+
+func TestOAuthLogoutHandler_EndSession_FrontChannelPage(t *testing.T) {
+	svc := &mockOAuthLogoutService{
+		endSessionFn: func(dto.OAuthEndSessionRequestDTO, *service.OAuthLogoutSession) (*dto.OAuthEndSessionResult, *apperror.OAuthError) {
+			return &dto.OAuthEndSessionResult{
+				RedirectURI:            "https://app.example.com/bye",
+				FrontChannelLogoutURIs: []string{"https://rp.example.com/logout?sid=s1", "https://rp.example.com/other"},
+			}, nil
+		},
+	}
+	h := NewOAuthLogoutHandler(svc, nil)
+	r := withSecurityCtx(httptest.NewRequest(http.MethodPost, "/api/v1/oauth/end_session", strings.NewReader("client_id=c1")))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	h.EndSession(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Header().Get("Content-Security-Policy"), "frame-src https://rp.example.com")
+	assert.NotContains(t, w.Header().Get("Content-Security-Policy"), "https://rp.example.com https://rp.example.com")
+	body := w.Body.String()
+	assert.Contains(t, body, `src="https://rp.example.com/logout?sid=s1"`)
+	assert.Contains(t, body, "/api/v1/login/assets/logout.js")
+}
+
+func TestOAuthLogoutHandler_EndSession_Errors(t *testing.T) {
+	t.Run("invalid request", func(t *testing.T) {
+		h := NewOAuthLogoutHandler(&mockOAuthLogoutService{}, nil)
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/oauth/end_session?post_logout_redirect_uri=https%3A%2F%2Fapp.example.com", nil)
+		w := httptest.NewRecorder()
+		h.EndSession(w, r)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		var body map[string]string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "invalid_request", body["error"])
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		svc := &mockOAuthLogoutService{
+			endSessionFn: func(dto.OAuthEndSessionRequestDTO, *service.OAuthLogoutSession) (*dto.OAuthEndSessionResult, *apperror.OAuthError) {
+				return nil, apperror.NewOAuthInvalidRequest("unknown client")
+			},
+		}
+		h := NewOAuthLogoutHandler(svc, nil)
+		w := httptest.NewRecorder()
+		h.EndSession(w, httptest.NewRequest(http.MethodGet, "/api/v1/oauth/end_session?client_id=gone", nil))
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "unknown client")
+	})
+}
+
+func TestOAuthLogoutHandler_EndSession_Caller(t *testing.T) {
+	initTestJWTKeysForHandler(t)
+
+	var got *service.OAuthLogoutSession
+	svc := &mockOAuthLogoutService{
+		endSessionFn: func(_ dto.OAuthEndSessionRequestDTO, s *service.OAuthLogoutSession) (*dto.OAuthEndSessionResult, *apperror.OAuthError) {
+			got = s
+			return &dto.OAuthEndSessionResult{}, nil
+		},
+	}
+	h := NewOAuthLogoutHandler(svc, nil)
+
+	t.Run("claims of the browser session", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/oauth/end_session", nil)
+		r = middleware.WithJWTClaims(r, &middleware.JWTClaims{Sub: "user-1", ClientID: "c1", SessionID: "sid-1"})
+		h.EndSession(httptest.NewRecorder(), r)
+
+		assert.Equal(t, &service.OAuthLogoutSession{Sub: "user-1", ClientID: "c1", SessionID: "sid-1"}, got)
+	})
+
+	t.Run("access token cookie", func(t *testing.T) {
+		token, err := jwt.GenerateAccessTokenWithClaims("user-2", "openid", "https://auth.example.com", "api", "c2", "p1",
+			map[string]any{"sid": "sid-2"})
+		require.NoError(t, err)
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/oauth/end_session", nil)
+		r.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+		h.EndSession(httptest.NewRecorder(), r)
+
+		assert.Equal(t, &service.OAuthLogoutSession{Sub: "user-2", ClientID: "c2", SessionID: "sid-2"}, got)
+	})
+
+	t.Run("invalid cookie", func(t *testing.T) {
+		got = nil
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/oauth/end_session", nil)
+		r.AddCookie(&http.Cookie{Name: "access_token", Value: "garbage"})
+		h.EndSession(httptest.NewRecorder(), r)
+
+		assert.Nil(t, got)
+	})
+}
+
+func TestLogoutPageCSP(t *testing.T) {
+	assert.Equal(t, hostedLoginCSP+"; frame-src 'none'", logoutPageCSP(nil))
+	assert.Equal(t, hostedLoginCSP+"; frame-src https://a.example.com https://b.example.com:8443",
+		logoutPageCSP([]string{"https://a.example.com/x", "https://b.example.com:8443/y", "https://a.example.com/z", "not a url"}))
+}
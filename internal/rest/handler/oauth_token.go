@@ -3,7 +3,9 @@ package handler
 import (
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/maintainerd/auth/internal/apperror"
@@ -38,6 +40,7 @@ func (h *OAuthTokenHandler) Token(w http.ResponseWriter, r *http.Request) {
 		CodeVerifier: r.PostFormValue("code_verifier"),
 		RefreshToken: r.PostFormValue("refresh_token"),
 		Scope:        r.PostFormValue("scope"),
+		Audience:     r.PostFormValue("audience"),
 		ClientID:     r.PostFormValue("client_id"),
 		ClientSecret: r.PostFormValue("client_secret"),
 	}
@@ -183,9 +186,23 @@ func writeTokenResponse(w http.ResponseWriter, result *dto.OAuthTokenResult) {
 		IDToken:      result.IDToken,
 		Scope:        result.Scope,
 	}
+	if result.Deprecation != nil {
+		setDeprecationHeaders(w.Header(), result.Deprecation)
+	}
 	writeOAuthJSON(w, http.StatusOK, resp)
 }
 
+// setDeprecationHeaders writes the Deprecation (RFC 9745), Sunset (RFC 8594)
+// and Warning headers so integrators of a deprecated client or API are told
+// to migrate before issuance stops.
+func setDeprecationHeaders(h http.Header, d *dto.OAuthDeprecationNotice) {
+	h.Set("Deprecation", "@"+strconv.FormatInt(d.DeprecatedAt.Unix(), 10))
+	if d.SunsetAt != nil {
+		h.Set("Sunset", d.SunsetAt.UTC().Format(http.TimeFormat))
+	}
+	h.Set("Warning", fmt.Sprintf("299 - %q", d.Message))
+}
+
 // writeOAuthJSON writes a JSON response with OAuth-required cache headers.
 func writeOAuthJSON(w http.ResponseWriter, status int, payload any) {
 	w.Header().Set("Content-Type", "application/json")
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/maintainerd/auth/internal/apperror"
 	"github.com/maintainerd/auth/internal/dto"
@@ -198,6 +199,75 @@ func TestOAuthTokenHandler_Token_Success_ClientCredentials(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestOAuthTokenHandler_Token_DeprecationHeaders(t *testing.T) {
+	deprecatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	sunsetAt := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("deprecated with sunset", func(t *testing.T) {
+		svc := &mockOAuthTokenService{
+			exchangeFn: func(_ context.Context, _ dto.OAuthTokenRequestDTO, _ dto.OAuthClientCredentials) (*dto.OAuthTokenResult, *apperror.OAuthError) {
+				return &dto.OAuthTokenResult{
+					AccessToken: "at-cc",
+					TokenType:   "Bearer",
+					ExpiresIn:   3600,
+					Deprecation: &dto.OAuthDeprecationNotice{
+						DeprecatedAt: deprecatedAt,
+						SunsetAt:     &sunsetAt,
+						Message:      "migrate to v2",
+					},
+				}, nil
+			},
+		}
+		h := NewOAuthTokenHandler(svc)
+		r := formReq(t, "/oauth/token", url.Values{"grant_type": {"client_credentials"}})
+		w := httptest.NewRecorder()
+
+		h.Token(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "@1767323045", w.Header().Get("Deprecation"))
+		assert.Equal(t, "Thu, 31 Dec 2026 00:00:00 GMT", w.Header().Get("Sunset"))
+		assert.Equal(t, `299 - "migrate to v2"`, w.Header().Get("Warning"))
+	})
+
+	t.Run("deprecated without sunset", func(t *testing.T) {
+		svc := &mockOAuthTokenService{
+			exchangeFn: func(_ context.Context, _ dto.OAuthTokenRequestDTO, _ dto.OAuthClientCredentials) (*dto.OAuthTokenResult, *apperror.OAuthError) {
+				return &dto.OAuthTokenResult{
+					AccessToken: "at-cc",
+					TokenType:   "Bearer",
+					Deprecation: &dto.OAuthDeprecationNotice{DeprecatedAt: deprecatedAt, Message: "deprecated"},
+				}, nil
+			},
+		}
+		h := NewOAuthTokenHandler(svc)
+		r := formReq(t, "/oauth/token", url.Values{"grant_type": {"client_credentials"}})
+		w := httptest.NewRecorder()
+
+		h.Token(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Deprecation"))
+		assert.Empty(t, w.Header().Get("Sunset"))
+	})
+
+	t.Run("not deprecated", func(t *testing.T) {
+		svc := &mockOAuthTokenService{
+			exchangeFn: func(_ context.Context, _ dto.OAuthTokenRequestDTO, _ dto.OAuthClientCredentials) (*dto.OAuthTokenResult, *apperror.OAuthError) {
+				return &dto.OAuthTokenResult{AccessToken: "at-cc", TokenType: "Bearer"}, nil
+			},
+		}
+		h := NewOAuthTokenHandler(svc)
+		r := formReq(t, "/oauth/token", url.Values{"grant_type": {"client_credentials"}})
+		w := httptest.NewRecorder()
+
+		h.Token(w, r)
+
+		assert.Empty(t, w.Header().Get("Deprecation"))
+		assert.Empty(t, w.Header().Get("Warning"))
+	})
+}
+
 func TestOAuthTokenHandler_Token_BasicAuthCredentials(t *testing.T) {
 	var capturedCreds dto.OAuthClientCredentials
 	svc := &mockOAuthTokenService{
@@ -578,3 +648,27 @@ type errReader struct{}
 func (errReader) Read([]byte) (int, error) {
 	return 0, assert.AnError
 }
+
+func TestOAuthTokenHandler_Token_Audience(t *testing.T) {
+	var captured dto.OAuthTokenRequestDTO
+	svc := &mockOAuthTokenService{
+		exchangeFn: func(_ context.Context, req dto.OAuthTokenRequestDTO, _ dto.OAuthClientCredentials) (*dto.OAuthTokenResult, *apperror.OAuthError) {
+			captured = req
+			return &dto.OAuthTokenResult{AccessToken: "at", TokenType: "Bearer", ExpiresIn: 900, Scope: "invoice:read"}, nil
+		},
+	}
+	h := NewOAuthTokenHandler(svc)
+	r := formReq(t, "/oauth/token", url.Values{
+		"grant_type": {"client_credentials"},
+		"audience":   {"https://billing.example.com"},
+		"scope":      {"invoice:read"},
+		"client_id":  {"m2m"},
+	})
+	w := httptest.NewRecorder()
+
+	h.Token(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://billing.example.com", captured.Audience)
+	assert.Equal(t, "invoice:read", captured.Scope)
+}
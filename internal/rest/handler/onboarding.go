@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// OnboardingHandler handles HTTP requests for the tenant onboarding wizard.
+type OnboardingHandler struct {
+	onboardingService service.OnboardingService
+}
+
+// NewOnboardingHandler creates a new OnboardingHandler.
+func NewOnboardingHandler(onboardingService service.OnboardingService) *OnboardingHandler {
+	return &OnboardingHandler{onboardingService: onboardingService}
+}
+
+// GetChecklist returns the onboarding checklist for a tenant.
+//
+// GET /tenants/{tenant_uuid}/onboarding
+func (h *OnboardingHandler) GetChecklist(w http.ResponseWriter, r *http.Request) {
+	tenantUUID, err := uuid.Parse(chi.URLParam(r, "tenant_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid tenant UUID")
+		return
+	}
+
+	result, err := h.onboardingService.GetChecklist(r.Context(), tenantUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get onboarding checklist", err)
+		return
+	}
+
+	resp.Success(w, toOnboardingChecklistResponseDTO(*result), "Onboarding checklist retrieved successfully")
+}
+
+// Onboard provisions the default client, admin role and user, email settings
+// and a starter signup flow for a tenant in a single call.
+//
+// POST /tenants/{tenant_uuid}/onboarding
+func (h *OnboardingHandler) Onboard(w http.ResponseWriter, r *http.Request) {
+	tenantUUID, err := uuid.Parse(chi.URLParam(r, "tenant_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid tenant UUID")
+		return
+	}
+
+	var req dto.OnboardingRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	opts := service.OnboardingServiceOptions{
+		Client: service.OnboardingServiceClientOptions{
+			Name:        req.Client.Name,
+			DisplayName: req.Client.DisplayName,
+			ClientType:  req.Client.ClientType,
+			Domain:      req.Client.Domain,
+		},
+		Admin: service.OnboardingServiceAdminOptions{
+			RoleName: req.Admin.RoleName,
+			Username: req.Admin.Username,
+			Fullname: req.Admin.Fullname,
+			Email:    req.Admin.Email,
+			Password: req.Admin.Password,
+		},
+	}
+	if req.Email != nil {
+		opts.Email = &service.OnboardingServiceEmailOptions{
+			Provider:    req.Email.Provider,
+			Host:        req.Email.Host,
+			Port:        req.Email.Port,
+			Username:    req.Email.Username,
+			Password:    req.Email.Password,
+			FromAddress: req.Email.FromAddress,
+			FromName:    req.Email.FromName,
+			ReplyTo:     req.Email.ReplyTo,
+			Encryption:  req.Email.Encryption,
+			TestMode:    req.Email.TestMode != nil && *req.Email.TestMode,
+		}
+	}
+	if req.SignupFlow != nil {
+		opts.SignupFlow = &service.OnboardingServiceSignupFlowOptions{
+			Name:        req.SignupFlow.Name,
+			Description: req.SignupFlow.Description,
+			Config:      req.SignupFlow.Config,
+		}
+	}
+
+	result, err := h.onboardingService.Onboard(r.Context(), tenantUUID, opts)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to onboard tenant", err)
+		return
+	}
+
+	resp.Created(w, toOnboardingResponseDTO(*result), "Tenant onboarded successfully")
+}
+
+// toOnboardingChecklistResponseDTO converts a checklist result to its response DTO.
+func toOnboardingChecklistResponseDTO(c service.OnboardingServiceChecklistResult) dto.OnboardingChecklistResponseDTO {
+	steps := make([]dto.OnboardingChecklistItemResponseDTO, len(c.Steps))
+	for i, s := range c.Steps {
+		steps[i] = dto.OnboardingChecklistItemResponseDTO{
+			Key:       s.Key,
+			Title:     s.Title,
+			Completed: s.Completed,
+		}
+	}
+	return dto.OnboardingChecklistResponseDTO{
+		TenantID:   c.TenantUUID.String(),
+		Steps:      steps,
+		Remaining:  c.Remaining,
+		IsComplete: c.IsComplete,
+	}
+}
+
+// toOnboardingResponseDTO converts an onboarding result to its response DTO.
+func toOnboardingResponseDTO(r service.OnboardingServiceResult) dto.OnboardingResponseDTO {
+	out := dto.OnboardingResponseDTO{
+		TenantID:           r.TenantUUID.String(),
+		IdentityProviderID: r.IdentityProviderUUID.String(),
+		ClientID:           r.ClientUUID.String(),
+		ClientIdentifier:   r.ClientIdentifier,
+		ClientSecret:       r.ClientSecret,
+		AdminRoleID:        r.AdminRoleUUID.String(),
+		DefaultRoleID:      r.DefaultRoleUUID.String(),
+		AdminUserID:        r.AdminUserUUID.String(),
+		Checklist:          toOnboardingChecklistResponseDTO(r.Checklist),
+	}
+	if r.EmailConfigUUID != nil {
+		id := r.EmailConfigUUID.String()
+		out.EmailConfigID = &id
+	}
+	if r.SignupFlowUUID != nil {
+		id := r.SignupFlowUUID.String()
+		out.SignupFlowID = &id
+	}
+	return out
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func onboardingBody() map[string]any {
+	return map[string]any{
+		"client": map[string]any{"name": "web", "client_type": "spa"},
+		"admin": map[string]any{
+			"username": "owner",
+			"email":    "owner@example.com",
+			"password": "Sup3rSecret!",
+		},
+	}
+}
+
+// ---------------------------------------------------------------------------
+// GetChecklist
+// ---------------------------------------------------------------------------
+
+func TestOnboardingHandler_GetChecklist_InvalidUUID(t *testing.T) {
+	h := NewOnboardingHandler(&mockOnboardingService{})
+	r := withChiParam(httptest.NewRequest(http.MethodGet, "/tenants/bad/onboarding", nil), "tenant_uuid", "bad")
+	w := httptest.NewRecorder()
+	h.GetChecklist(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestOnboardingHandler_GetChecklist_ServiceError(t *testing.T) {
+	svc := &mockOnboardingService{
+		getChecklistFn: func(uuid.UUID) (*service.OnboardingServiceChecklistResult, error) {
+			return nil, errNotFound
+		},
+	}
+	h := NewOnboardingHandler(svc)
+	r := withChiParam(httptest.NewRequest(http.MethodGet, "/", nil), "tenant_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.GetChecklist(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestOnboardingHandler_GetChecklist_Success(t *testing.T) {
+	svc := &mockOnboardingService{
+		getChecklistFn: func(id uuid.UUID) (*service.OnboardingServiceChecklistResult, error) {
+			assert.Equal(t, testResourceUUID, id)
+			return &service.OnboardingServiceChecklistResult{
+				TenantUUID: id,
+				Steps: []service.OnboardingServiceChecklistItem{
+					{Key: service.OnboardingStepClient, Title: "Register an auth client", Completed: true},
+					{Key: service.OnboardingStepBranding, Title: "Customize branding"},
+				},
+				Remaining: 1,
+			}, nil
+		},
+	}
+	h := NewOnboardingHandler(svc)
+	r := withChiParam(httptest.NewRequest(http.MethodGet, "/", nil), "tenant_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.GetChecklist(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"remaining":1`)
+	assert.Contains(t, w.Body.String(), `"key":"branding"`)
+}
+
+// ---------------------------------------------------------------------------
+// Onboard
+// ---------------------------------------------------------------------------
+
+func TestOnboardingHandler_Onboard_InvalidUUID(t *testing.T) {
+	h := NewOnboardingHandler(&mockOnboardingService{})
+	r := withChiParam(jsonReq(t, http.MethodPost, "/", onboardingBody()), "tenant_uuid", "bad")
+	w := httptest.NewRecorder()
+	h.Onboard(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestOnboardingHandler_Onboard_BadJSON(t *testing.T) {
+	h := NewOnboardingHandler(&mockOnboardingService{})
+	r := withChiParam(badJSONReq(t, http.MethodPost, "/"), "tenant_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.Onboard(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestOnboardingHandler_Onboard_ValidationError(t *testing.T) {
+	h := NewOnboardingHandler(&mockOnboardingService{})
+	body := onboardingBody()
+	body["admin"] = map[string]any{"username": "owner"}
+	r := withChiParam(jsonReq(t, http.MethodPost, "/", body), "tenant_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.Onboard(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestOnboardingHandler_Onboard_ServiceError(t *testing.T) {
+	svc := &mockOnboardingService{
+		onboardFn: func(uuid.UUID, service.OnboardingServiceOptions) (*service.OnboardingServiceResult, error) {
+			return nil, apperror.NewConflict("tenant has already been onboarded")
+		},
+	}
+	h := NewOnboardingHandler(svc)
+	r := withChiParam(jsonReq(t, http.MethodPost, "/", onboardingBody()), "tenant_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.Onboard(w, r)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestOnboardingHandler_Onboard_Success(t *testing.T) {
+	emailID := uuid.New()
+	flowID := uuid.New()
+	secret := "secret"
+	svc := &mockOnboardingService{
+		onboardFn: func(id uuid.UUID, opts service.OnboardingServiceOptions) (*service.OnboardingServiceResult, error) {
+			assert.Equal(t, testResourceUUID, id)
+			assert.Equal(t, "web", opts.Client.Name)
+			assert.Equal(t, "owner", opts.Admin.Username)
+			if assert.NotNil(t, opts.Email) {
+				assert.True(t, opts.Email.TestMode)
+			}
+			if assert.NotNil(t, opts.SignupFlow) {
+				assert.Equal(t, "starter", opts.SignupFlow.Name)
+			}
+			return &service.OnboardingServiceResult{
+				TenantUUID:      id,
+				ClientUUID:      uuid.New(),
+				ClientSecret:    &secret,
+				EmailConfigUUID: &emailID,
+				SignupFlowUUID:  &flowID,
+			}, nil
+		},
+	}
+	body := onboardingBody()
+	body["email"] = map[string]any{"provider": "smtp", "from_address": "noreply@example.com", "test_mode": true}
+	body["signup_flow"] = map[string]any{"name": "starter"}
+	h := NewOnboardingHandler(svc)
+	r := withChiParam(jsonReq(t, http.MethodPost, "/", body), "tenant_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.Onboard(w, r)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), emailID.String())
+	assert.Contains(t, w.Body.String(), flowID.String())
+	assert.Contains(t, w.Body.String(), `"client_secret":"secret"`)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/maintainerd/auth/internal/dto"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// ParentalConsentHandler lets the guardian of an under-age user review and
+// approve or decline the account. The emailed token authorizes every
+// request.
+type ParentalConsentHandler struct {
+	parentalConsentService service.ParentalConsentService
+}
+
+// NewParentalConsentHandler creates a new ParentalConsentHandler.
+func NewParentalConsentHandler(parentalConsentService service.ParentalConsentService) *ParentalConsentHandler {
+	return &ParentalConsentHandler{parentalConsentService: parentalConsentService}
+}
+
+// Get returns the pending consent request of the token.
+//
+// GET /parental-consent?token=...
+func (h *ParentalConsentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	req := dto.ParentalConsentRequestDTO{Token: r.URL.Query().Get("token")}
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	consent, err := h.parentalConsentService.Get(r.Context(), req.Token)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to fetch consent request", err)
+		return
+	}
+
+	resp.Success(w, toParentalConsentResponseDTO(consent), "Consent request fetched successfully")
+}
+
+// Approve activates the under-age user's account.
+//
+// POST /parental-consent/approve
+func (h *ParentalConsentHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeParentalConsentRequest(w, r)
+	if !ok {
+		return
+	}
+
+	consent, err := h.parentalConsentService.Approve(r.Context(), req.Token)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to approve account", err)
+		return
+	}
+
+	resp.Success(w, toParentalConsentResponseDTO(consent), "Account approved")
+}
+
+// Deny deletes the under-age user's account.
+//
+// POST /parental-consent/deny
+func (h *ParentalConsentHandler) Deny(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeParentalConsentRequest(w, r)
+	if !ok {
+		return
+	}
+
+	consent, err := h.parentalConsentService.Deny(r.Context(), req.Token)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to decline account", err)
+		return
+	}
+
+	resp.Success(w, toParentalConsentResponseDTO(consent), "Account declined")
+}
+
+func decodeParentalConsentRequest(w http.ResponseWriter, r *http.Request) (dto.ParentalConsentRequestDTO, bool) {
+	var req dto.ParentalConsentRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return req, false
+	}
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return req, false
+	}
+	return req, true
+}
+
+func toParentalConsentResponseDTO(c *service.ParentalConsentServiceDataResult) dto.ParentalConsentResponseDTO {
+	return dto.ParentalConsentResponseDTO{
+		ParentalConsentID: c.ParentalConsentUUID.String(),
+		Username:          c.Username,
+		Status:            c.Status,
+		ExpiresAt:         c.ExpiresAt,
+		DecidedAt:         c.DecidedAt,
+	}
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+var testConsentToken = strings.Repeat("ab", 32)
+
+func parentalConsentResult(status string) *service.ParentalConsentServiceDataResult {
+	return &service.ParentalConsentServiceDataResult{
+		ParentalConsentUUID: uuid.New(),
+		Username:            "kid",
+		Status:              status,
+		ExpiresAt:           time.Now().Add(time.Hour),
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Get
+// ---------------------------------------------------------------------------
+
+func TestParentalConsentHandler_Get_ValidationError(t *testing.T) {
+	h := NewParentalConsentHandler(&mockParentalConsentService{})
+	w := httptest.NewRecorder()
+	h.Get(w, httptest.NewRequest(http.MethodGet, "/parental-consent?token=short", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestParentalConsentHandler_Get_ServiceError(t *testing.T) {
+	svc := &mockParentalConsentService{
+		getFn: func(string) (*service.ParentalConsentServiceDataResult, error) { return nil, errValidation },
+	}
+	h := NewParentalConsentHandler(svc)
+	w := httptest.NewRecorder()
+	h.Get(w, httptest.NewRequest(http.MethodGet, "/parental-consent?token="+testConsentToken, nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestParentalConsentHandler_Get_Success(t *testing.T) {
+	svc := &mockParentalConsentService{
+		getFn: func(token string) (*service.ParentalConsentServiceDataResult, error) {
+			assert.Equal(t, testConsentToken, token)
+			return parentalConsentResult(model.StatusPending), nil
+		},
+	}
+	h := NewParentalConsentHandler(svc)
+	w := httptest.NewRecorder()
+	h.Get(w, httptest.NewRequest(http.MethodGet, "/parental-consent?token="+testConsentToken, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"username":"kid"`)
+}
+
+// ---------------------------------------------------------------------------
+// Approve
+// ---------------------------------------------------------------------------
+
+func TestParentalConsentHandler_Approve_BadJSON(t *testing.T) {
+	h := NewParentalConsentHandler(&mockParentalConsentService{})
+	w := httptest.NewRecorder()
+	h.Approve(w, badJSONReq(t, http.MethodPost, "/parental-consent/approve"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestParentalConsentHandler_Approve_ValidationError(t *testing.T) {
+	h := NewParentalConsentHandler(&mockParentalConsentService{})
+	w := httptest.NewRecorder()
+	h.Approve(w, jsonReq(t, http.MethodPost, "/parental-consent/approve", map[string]any{"token": "not-hex"}))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestParentalConsentHandler_Approve_Success(t *testing.T) {
+	svc := &mockParentalConsentService{
+		approveFn: func(token string) (*service.ParentalConsentServiceDataResult, error) {
+			assert.Equal(t, testConsentToken, token)
+			return parentalConsentResult(model.ParentalConsentApproved), nil
+		},
+	}
+	h := NewParentalConsentHandler(svc)
+	w := httptest.NewRecorder()
+	h.Approve(w, jsonReq(t, http.MethodPost, "/parental-consent/approve", map[string]any{"token": testConsentToken}))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"approved"`)
+}
+
+// ---------------------------------------------------------------------------
+// Deny
+// ---------------------------------------------------------------------------
+
+func TestParentalConsentHandler_Deny_ServiceError(t *testing.T) {
+	svc := &mockParentalConsentService{
+		denyFn: func(string) (*service.ParentalConsentServiceDataResult, error) { return nil, errValidation },
+	}
+	h := NewParentalConsentHandler(svc)
+	w := httptest.NewRecorder()
+	h.Deny(w, jsonReq(t, http.MethodPost, "/parental-consent/deny", map[string]any{"token": testConsentToken}))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestParentalConsentHandler_Deny_Success(t *testing.T) {
+	svc := &mockParentalConsentService{
+		denyFn: func(string) (*service.ParentalConsentServiceDataResult, error) {
+			return parentalConsentResult(model.ParentalConsentDenied), nil
+		},
+	}
+	h := NewParentalConsentHandler(svc)
+	w := httptest.NewRecorder()
+	h.Deny(w, jsonReq(t, http.MethodPost, "/parental-consent/deny", map[string]any{"token": testConsentToken}))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"denied"`)
+}
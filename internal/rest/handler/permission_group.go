@@ -0,0 +1,457 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// PermissionGroupHandler manages permission groups and assigns them to
+// roles, client APIs and API key APIs. Assigning a group expands it into its
+// permissions and adds those through the owning service, so the usual
+// validation and side effects of adding permissions apply.
+type PermissionGroupHandler struct {
+	permissionGroupService service.PermissionGroupService
+	roleService            service.RoleService
+	clientService          service.ClientService
+	apiKeyService          service.APIKeyService
+}
+
+func NewPermissionGroupHandler(
+	permissionGroupService service.PermissionGroupService,
+	roleService service.RoleService,
+	clientService service.ClientService,
+	apiKeyService service.APIKeyService,
+) *PermissionGroupHandler {
+	return &PermissionGroupHandler{
+		permissionGroupService: permissionGroupService,
+		roleService:            roleService,
+		clientService:          clientService,
+		apiKeyService:          apiKeyService,
+	}
+}
+
+// Get lists permission groups with filtering and pagination.
+func (h *PermissionGroupHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	query := r.URL.Query()
+
+	var filter dto.PermissionGroupFilterDTO
+	if name := query.Get("name"); name != "" {
+		filter.Name = &name
+	}
+	if description := query.Get("description"); description != "" {
+		filter.Description = &description
+	}
+
+	page, _ := strconv.Atoi(query.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	filter.Page = page
+	filter.Limit = limit
+	filter.SortBy = query.Get("sort_by")
+	filter.SortOrder = query.Get("sort_order")
+
+	if err := filter.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.permissionGroupService.Get(r.Context(), service.PermissionGroupServiceGetFilter{
+		TenantID:    tenant.TenantID,
+		Name:        filter.Name,
+		Description: filter.Description,
+		Page:        filter.Page,
+		Limit:       filter.Limit,
+		SortBy:      filter.SortBy,
+		SortOrder:   filter.SortOrder,
+	})
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get permission groups", err)
+		return
+	}
+
+	rows := make([]dto.PermissionGroupResponseDTO, len(result.Data))
+	for i, group := range result.Data {
+		rows[i] = toPermissionGroupResponseDTO(group)
+	}
+
+	response := dto.PaginatedResponseDTO[dto.PermissionGroupResponseDTO]{
+		Rows:       rows,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}
+
+	resp.Success(w, response, "Permission groups retrieved successfully")
+}
+
+// GetByUUID returns a permission group with its permissions.
+func (h *PermissionGroupHandler) GetByUUID(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	groupUUID, err := uuid.Parse(chi.URLParam(r, "permission_group_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid permission group UUID")
+		return
+	}
+
+	group, err := h.permissionGroupService.GetByUUID(r.Context(), groupUUID, tenant.TenantID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Permission group not found", err)
+		return
+	}
+
+	resp.Success(w, toPermissionGroupResponseDTO(*group), "Permission group retrieved successfully")
+}
+
+// Create creates a permission group, optionally with its first permissions.
+func (h *PermissionGroupHandler) Create(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	var req dto.PermissionGroupCreateRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	group, err := h.permissionGroupService.Create(r.Context(), tenant.TenantID, req.Name, req.Description, req.Permissions)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to create permission group", err)
+		return
+	}
+
+	resp.Created(w, toPermissionGroupResponseDTO(*group), "Permission group created successfully")
+}
+
+// Update renames or re-describes a permission group.
+func (h *PermissionGroupHandler) Update(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	groupUUID, err := uuid.Parse(chi.URLParam(r, "permission_group_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid permission group UUID")
+		return
+	}
+
+	var req dto.PermissionGroupUpdateRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	group, err := h.permissionGroupService.Update(r.Context(), groupUUID, tenant.TenantID, req.Name, req.Description)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to update permission group", err)
+		return
+	}
+
+	resp.Success(w, toPermissionGroupResponseDTO(*group), "Permission group updated successfully")
+}
+
+// Delete deletes a permission group. Permissions already assigned through
+// the group are kept.
+func (h *PermissionGroupHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	groupUUID, err := uuid.Parse(chi.URLParam(r, "permission_group_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid permission group UUID")
+		return
+	}
+
+	group, err := h.permissionGroupService.DeleteByUUID(r.Context(), groupUUID, tenant.TenantID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to delete permission group", err)
+		return
+	}
+
+	resp.Success(w, toPermissionGroupResponseDTO(*group), "Permission group deleted successfully")
+}
+
+// AddPermissions adds permissions to a permission group.
+func (h *PermissionGroupHandler) AddPermissions(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	groupUUID, err := uuid.Parse(chi.URLParam(r, "permission_group_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid permission group UUID")
+		return
+	}
+
+	var req dto.PermissionGroupAddPermissionsRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	group, err := h.permissionGroupService.AddPermissions(r.Context(), groupUUID, tenant.TenantID, req.Permissions)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to add permissions to permission group", err)
+		return
+	}
+
+	resp.Success(w, toPermissionGroupResponseDTO(*group), "Permissions added to permission group successfully")
+}
+
+// RemovePermission removes a permission from a permission group.
+func (h *PermissionGroupHandler) RemovePermission(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	groupUUID, err := uuid.Parse(chi.URLParam(r, "permission_group_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid permission group UUID")
+		return
+	}
+
+	permissionUUID, err := uuid.Parse(chi.URLParam(r, "permission_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid permission UUID")
+		return
+	}
+
+	group, err := h.permissionGroupService.RemovePermission(r.Context(), groupUUID, tenant.TenantID, permissionUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to remove permission from permission group", err)
+		return
+	}
+
+	resp.Success(w, toPermissionGroupResponseDTO(*group), "Permission removed from permission group successfully")
+}
+
+// AssignToRole adds the permissions of one or more groups to a role.
+func (h *PermissionGroupHandler) AssignToRole(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+
+	roleUUID, err := uuid.Parse(chi.URLParam(r, "role_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid role UUID")
+		return
+	}
+
+	req, ok := decodePermissionGroupAssignRequest(w, r)
+	if !ok {
+		return
+	}
+
+	permissionUUIDs, err := h.permissionGroupService.ExpandPermissions(r.Context(), tenant.TenantID, req.PermissionGroups, nil)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to expand permission groups", err)
+		return
+	}
+
+	// Role assignment skips permissions the role already has.
+	if _, err := h.roleService.AddRolePermissions(r.Context(), roleUUID, tenant.TenantID, permissionUUIDs, user.UserUUID); err != nil {
+		resp.HandleServiceError(w, r, "Failed to add permissions to role", err)
+		return
+	}
+
+	resp.Success(w, dto.PermissionGroupAssignResponseDTO{Permissions: permissionUUIDs}, "Permission groups assigned to role successfully")
+}
+
+// AssignToClientAPI adds the permissions of one or more groups that belong
+// to the API to a client's API.
+func (h *PermissionGroupHandler) AssignToClientAPI(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	clientUUID, err := uuid.Parse(chi.URLParam(r, "client_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid auth client UUID")
+		return
+	}
+
+	apiUUID, err := uuid.Parse(chi.URLParam(r, "api_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid API UUID")
+		return
+	}
+
+	req, ok := decodePermissionGroupAssignRequest(w, r)
+	if !ok {
+		return
+	}
+
+	permissionUUIDs, err := h.permissionGroupService.ExpandPermissions(r.Context(), tenant.TenantID, req.PermissionGroups, &apiUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to expand permission groups", err)
+		return
+	}
+
+	// Client assignment rejects permissions the client API already has, so
+	// only the missing ones are added.
+	assigned, err := h.clientService.GetClientAPIPermissions(r.Context(), tenant.TenantID, clientUUID, apiUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to add permissions to auth client API", err)
+		return
+	}
+	missing := withoutAssignedPermissions(permissionUUIDs, assigned)
+	if len(missing) > 0 {
+		if err := h.clientService.AddClientAPIPermissions(r.Context(), tenant.TenantID, clientUUID, apiUUID, missing); err != nil {
+			resp.HandleServiceError(w, r, "Failed to add permissions to auth client API", err)
+			return
+		}
+	}
+
+	resp.Success(w, dto.PermissionGroupAssignResponseDTO{Permissions: permissionUUIDs}, "Permission groups assigned to auth client API successfully")
+}
+
+// AssignToAPIKeyAPI adds the permissions of one or more groups that belong
+// to the API to an API key's API.
+func (h *PermissionGroupHandler) AssignToAPIKeyAPI(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	apiKeyUUID, err := uuid.Parse(chi.URLParam(r, "api_key_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid API key UUID")
+		return
+	}
+
+	apiUUID, err := uuid.Parse(chi.URLParam(r, "api_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid API UUID")
+		return
+	}
+
+	req, ok := decodePermissionGroupAssignRequest(w, r)
+	if !ok {
+		return
+	}
+
+	permissionUUIDs, err := h.permissionGroupService.ExpandPermissions(r.Context(), tenant.TenantID, req.PermissionGroups, &apiUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to expand permission groups", err)
+		return
+	}
+
+	// API key assignment skips permissions the API key API already has.
+	if err := h.apiKeyService.AddAPIKeyAPIPermissions(r.Context(), apiKeyUUID, tenant.TenantID, apiUUID, permissionUUIDs); err != nil {
+		resp.HandleServiceError(w, r, "Failed to add permissions to API key API", err)
+		return
+	}
+
+	resp.Success(w, dto.PermissionGroupAssignResponseDTO{Permissions: permissionUUIDs}, "Permission groups assigned to API key API successfully")
+}
+
+// decodePermissionGroupAssignRequest decodes and validates an assignment
+// body, writing the error response when it is invalid.
+func decodePermissionGroupAssignRequest(w http.ResponseWriter, r *http.Request) (dto.PermissionGroupAssignRequestDTO, bool) {
+	var req dto.PermissionGroupAssignRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return req, false
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return req, false
+	}
+
+	return req, true
+}
+
+// withoutAssignedPermissions returns the permission UUIDs that are not in
+// assigned.
+func withoutAssignedPermissions(permissionUUIDs []uuid.UUID, assigned []service.PermissionServiceDataResult) []uuid.UUID {
+	has := make(map[uuid.UUID]struct{}, len(assigned))
+	for _, p := range assigned {
+		has[p.PermissionUUID] = struct{}{}
+	}
+	missing := []uuid.UUID{}
+	for _, id := range permissionUUIDs {
+		if _, ok := has[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+func toPermissionGroupResponseDTO(g service.PermissionGroupServiceDataResult) dto.PermissionGroupResponseDTO {
+	permissions := make([]dto.PermissionResponseDTO, len(g.Permissions))
+	for i, p := range g.Permissions {
+		permissions[i] = toPermissionResponseDTO(p)
+	}
+	return dto.PermissionGroupResponseDTO{
+		PermissionGroupUUID: g.PermissionGroupUUID,
+		Name:                g.Name,
+		Description:         g.Description,
+		Permissions:         permissions,
+		CreatedAt:           g.CreatedAt,
+		UpdatedAt:           g.UpdatedAt,
+	}
+}
@@ -0,0 +1,349 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPermissionGroupHandler(groups *mockPermissionGroupService) *PermissionGroupHandler {
+	return NewPermissionGroupHandler(groups, &mockRoleService{}, &mockClientService{}, &mockAPIKeyService{})
+}
+
+// ---------------------------------------------------------------------------
+// Get / GetByUUID
+// ---------------------------------------------------------------------------
+
+func TestPermissionGroupHandler_Get(t *testing.T) {
+	t.Run("no tenant", func(t *testing.T) {
+		h := newPermissionGroupHandler(&mockPermissionGroupService{})
+		w := httptest.NewRecorder()
+		h.Get(w, httptest.NewRequest(http.MethodGet, "/permission-groups", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		var got service.PermissionGroupServiceGetFilter
+		h := newPermissionGroupHandler(&mockPermissionGroupService{
+			getFn: func(f service.PermissionGroupServiceGetFilter) (*service.PermissionGroupServiceGetResult, error) {
+				got = f
+				return &service.PermissionGroupServiceGetResult{
+					Data:  []service.PermissionGroupServiceDataResult{{Name: "read-only"}},
+					Total: 1, Page: 1, Limit: 10, TotalPages: 1,
+				}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Get(w, withTenant(httptest.NewRequest(http.MethodGet, "/permission-groups?name=read", nil)))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "read-only")
+		assert.Equal(t, tenantID, got.TenantID)
+		require.NotNil(t, got.Name)
+		assert.Equal(t, "read", *got.Name)
+	})
+}
+
+func TestPermissionGroupHandler_GetByUUID(t *testing.T) {
+	t.Run("invalid uuid", func(t *testing.T) {
+		h := newPermissionGroupHandler(&mockPermissionGroupService{})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenant(httptest.NewRequest(http.MethodGet, "/", nil)), "permission_group_uuid", "bad")
+		h.GetByUUID(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		h := newPermissionGroupHandler(&mockPermissionGroupService{
+			getByUUIDFn: func(uuid.UUID, int64) (*service.PermissionGroupServiceDataResult, error) {
+				return nil, errNotFound
+			},
+		})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenant(httptest.NewRequest(http.MethodGet, "/", nil)), "permission_group_uuid", testResourceUUID.String())
+		h.GetByUUID(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Create / Update / Delete
+// ---------------------------------------------------------------------------
+
+func TestPermissionGroupHandler_Create(t *testing.T) {
+	t.Run("bad json", func(t *testing.T) {
+		h := newPermissionGroupHandler(&mockPermissionGroupService{})
+		w := httptest.NewRecorder()
+		h.Create(w, withTenant(badJSONReq(t, http.MethodPost, "/permission-groups")))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		h := newPermissionGroupHandler(&mockPermissionGroupService{})
+		w := httptest.NewRecorder()
+		h.Create(w, withTenant(jsonReq(t, http.MethodPost, "/permission-groups", dto.PermissionGroupCreateRequestDTO{Name: "x"})))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		permissionUUID := uuid.New()
+		h := newPermissionGroupHandler(&mockPermissionGroupService{
+			createFn: func(tid int64, name, description string, perms []uuid.UUID) (*service.PermissionGroupServiceDataResult, error) {
+				assert.Equal(t, tenantID, tid)
+				assert.Equal(t, []uuid.UUID{permissionUUID}, perms)
+				return &service.PermissionGroupServiceDataResult{Name: name, Description: description}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Create(w, withTenant(jsonReq(t, http.MethodPost, "/permission-groups", dto.PermissionGroupCreateRequestDTO{
+			Name:        "user-admin",
+			Permissions: []uuid.UUID{permissionUUID},
+		})))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), "user-admin")
+	})
+}
+
+func TestPermissionGroupHandler_Update(t *testing.T) {
+	h := newPermissionGroupHandler(&mockPermissionGroupService{
+		updateFn: func(id uuid.UUID, _ int64, name, _ string) (*service.PermissionGroupServiceDataResult, error) {
+			assert.Equal(t, testResourceUUID, id)
+			return &service.PermissionGroupServiceDataResult{PermissionGroupUUID: id, Name: name}, nil
+		},
+	})
+	w := httptest.NewRecorder()
+	r := withChiParam(withTenant(jsonReq(t, http.MethodPut, "/", dto.PermissionGroupUpdateRequestDTO{Name: "read-only"})), "permission_group_uuid", testResourceUUID.String())
+	h.Update(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "read-only")
+}
+
+func TestPermissionGroupHandler_Delete(t *testing.T) {
+	h := newPermissionGroupHandler(&mockPermissionGroupService{
+		deleteFn: func(uuid.UUID, int64) (*service.PermissionGroupServiceDataResult, error) {
+			return nil, errNotFound
+		},
+	})
+	w := httptest.NewRecorder()
+	r := withChiParam(withTenant(httptest.NewRequest(http.MethodDelete, "/", nil)), "permission_group_uuid", testResourceUUID.String())
+	h.Delete(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// ---------------------------------------------------------------------------
+// AddPermissions / RemovePermission
+// ---------------------------------------------------------------------------
+
+func TestPermissionGroupHandler_AddPermissions(t *testing.T) {
+	t.Run("empty permissions", func(t *testing.T) {
+		h := newPermissionGroupHandler(&mockPermissionGroupService{})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenant(jsonReq(t, http.MethodPost, "/", dto.PermissionGroupAddPermissionsRequestDTO{})), "permission_group_uuid", testResourceUUID.String())
+		h.AddPermissions(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		permissionUUID := uuid.New()
+		h := newPermissionGroupHandler(&mockPermissionGroupService{
+			addPermissionsFn: func(_ uuid.UUID, _ int64, perms []uuid.UUID) (*service.PermissionGroupServiceDataResult, error) {
+				return &service.PermissionGroupServiceDataResult{
+					Permissions: []service.PermissionServiceDataResult{{PermissionUUID: perms[0], Name: "user:read"}},
+				}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenant(jsonReq(t, http.MethodPost, "/", dto.PermissionGroupAddPermissionsRequestDTO{
+			Permissions: []uuid.UUID{permissionUUID},
+		})), "permission_group_uuid", testResourceUUID.String())
+		h.AddPermissions(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "user:read")
+	})
+}
+
+func TestPermissionGroupHandler_RemovePermission(t *testing.T) {
+	t.Run("invalid permission uuid", func(t *testing.T) {
+		h := newPermissionGroupHandler(&mockPermissionGroupService{})
+		w := httptest.NewRecorder()
+		r := withTenant(httptest.NewRequest(http.MethodDelete, "/", nil))
+		r = withChiParam(r, "permission_group_uuid", testResourceUUID.String())
+		r = withChiParam(r, "permission_uuid", "bad")
+		h.RemovePermission(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		permissionUUID := uuid.New()
+		h := newPermissionGroupHandler(&mockPermissionGroupService{
+			removePermissionFn: func(_ uuid.UUID, _ int64, perm uuid.UUID) (*service.PermissionGroupServiceDataResult, error) {
+				assert.Equal(t, permissionUUID, perm)
+				return &service.PermissionGroupServiceDataResult{}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		r := withTenant(httptest.NewRequest(http.MethodDelete, "/", nil))
+		r = withChiParam(r, "permission_group_uuid", testResourceUUID.String())
+		r = withChiParam(r, "permission_uuid", permissionUUID.String())
+		h.RemovePermission(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Assignment
+// ---------------------------------------------------------------------------
+
+func TestPermissionGroupHandler_AssignToRole(t *testing.T) {
+	groupUUID := uuid.New()
+	expanded := []uuid.UUID{uuid.New(), uuid.New()}
+	body := dto.PermissionGroupAssignRequestDTO{PermissionGroups: []uuid.UUID{groupUUID}}
+
+	t.Run("no user", func(t *testing.T) {
+		h := newPermissionGroupHandler(&mockPermissionGroupService{})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenant(jsonReq(t, http.MethodPost, "/", body)), "role_uuid", testResourceUUID.String())
+		h.AssignToRole(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("no groups", func(t *testing.T) {
+		h := newPermissionGroupHandler(&mockPermissionGroupService{})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenantAndUser(jsonReq(t, http.MethodPost, "/", dto.PermissionGroupAssignRequestDTO{})), "role_uuid", testResourceUUID.String())
+		h.AssignToRole(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unknown group", func(t *testing.T) {
+		h := newPermissionGroupHandler(&mockPermissionGroupService{
+			expandFn: func(int64, []uuid.UUID, *uuid.UUID) ([]uuid.UUID, error) { return nil, errNotFound },
+		})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenantAndUser(jsonReq(t, http.MethodPost, "/", body)), "role_uuid", testResourceUUID.String())
+		h.AssignToRole(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		var added []uuid.UUID
+		roles := &mockRoleService{
+			addRolePermsFn: func(id uuid.UUID, tid int64, perms []uuid.UUID, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
+				assert.Equal(t, testResourceUUID, id)
+				assert.Equal(t, testUserUUID, actor)
+				added = perms
+				return &service.RoleServiceDataResult{}, nil
+			},
+		}
+		h := NewPermissionGroupHandler(&mockPermissionGroupService{
+			expandFn: func(tid int64, groups []uuid.UUID, api *uuid.UUID) ([]uuid.UUID, error) {
+				assert.Equal(t, []uuid.UUID{groupUUID}, groups)
+				assert.Nil(t, api)
+				return expanded, nil
+			},
+		}, roles, &mockClientService{}, &mockAPIKeyService{})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenantAndUser(jsonReq(t, http.MethodPost, "/", body)), "role_uuid", testResourceUUID.String())
+		h.AssignToRole(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, expanded, added)
+		assert.Contains(t, w.Body.String(), expanded[0].String())
+	})
+}
+
+func TestPermissionGroupHandler_AssignToClientAPI(t *testing.T) {
+	apiUUID := uuid.New()
+	existing, missing := uuid.New(), uuid.New()
+	body := dto.PermissionGroupAssignRequestDTO{PermissionGroups: []uuid.UUID{uuid.New()}}
+
+	request := func() *http.Request {
+		r := withTenant(jsonReq(t, http.MethodPost, "/", body))
+		r = withChiParam(r, "client_uuid", testResourceUUID.String())
+		return withChiParam(r, "api_uuid", apiUUID.String())
+	}
+	groups := &mockPermissionGroupService{
+		expandFn: func(_ int64, _ []uuid.UUID, api *uuid.UUID) ([]uuid.UUID, error) {
+			require.NotNil(t, api)
+			assert.Equal(t, apiUUID, *api)
+			return []uuid.UUID{existing, missing}, nil
+		},
+	}
+
+	t.Run("adds only missing permissions", func(t *testing.T) {
+		var added []uuid.UUID
+		clients := &mockClientService{
+			getClientAPIPermsFn: func(int64, uuid.UUID, uuid.UUID) ([]service.PermissionServiceDataResult, error) {
+				return []service.PermissionServiceDataResult{{PermissionUUID: existing}}, nil
+			},
+			addClientAPIPermsFn: func(_ int64, _, _ uuid.UUID, perms []uuid.UUID) error {
+				added = perms
+				return nil
+			},
+		}
+		h := NewPermissionGroupHandler(groups, &mockRoleService{}, clients, &mockAPIKeyService{})
+		w := httptest.NewRecorder()
+		h.AssignToClientAPI(w, request())
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []uuid.UUID{missing}, added)
+	})
+
+	t.Run("nothing missing", func(t *testing.T) {
+		clients := &mockClientService{
+			getClientAPIPermsFn: func(int64, uuid.UUID, uuid.UUID) ([]service.PermissionServiceDataResult, error) {
+				return []service.PermissionServiceDataResult{{PermissionUUID: existing}, {PermissionUUID: missing}}, nil
+			},
+			addClientAPIPermsFn: func(int64, uuid.UUID, uuid.UUID, []uuid.UUID) error {
+				t.Fatal("no permissions should be added")
+				return nil
+			},
+		}
+		h := NewPermissionGroupHandler(groups, &mockRoleService{}, clients, &mockAPIKeyService{})
+		w := httptest.NewRecorder()
+		h.AssignToClientAPI(w, request())
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("client api not found", func(t *testing.T) {
+		clients := &mockClientService{
+			getClientAPIPermsFn: func(int64, uuid.UUID, uuid.UUID) ([]service.PermissionServiceDataResult, error) {
+				return nil, errNotFound
+			},
+		}
+		h := NewPermissionGroupHandler(groups, &mockRoleService{}, clients, &mockAPIKeyService{})
+		w := httptest.NewRecorder()
+		h.AssignToClientAPI(w, request())
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestPermissionGroupHandler_AssignToAPIKeyAPI(t *testing.T) {
+	apiUUID := uuid.New()
+	expanded := []uuid.UUID{uuid.New()}
+	body := dto.PermissionGroupAssignRequestDTO{PermissionGroups: []uuid.UUID{uuid.New()}}
+
+	var added []uuid.UUID
+	apiKeys := &mockAPIKeyService{
+		addAPIKeyAPIPermsFn: func(id uuid.UUID, _ int64, api uuid.UUID, perms []uuid.UUID) error {
+			assert.Equal(t, testResourceUUID, id)
+			assert.Equal(t, apiUUID, api)
+			added = perms
+			return nil
+		},
+	}
+	h := NewPermissionGroupHandler(&mockPermissionGroupService{
+		expandFn: func(int64, []uuid.UUID, *uuid.UUID) ([]uuid.UUID, error) { return expanded, nil },
+	}, &mockRoleService{}, &mockClientService{}, apiKeys)
+
+	w := httptest.NewRecorder()
+	r := withTenant(jsonReq(t, http.MethodPost, "/", body))
+	r = withChiParam(r, "api_key_uuid", testResourceUUID.String())
+	r = withChiParam(r, "api_uuid", apiUUID.String())
+	h.AssignToAPIKeyAPI(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, expanded, added)
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// PersonalAccessTokenHandler handles the self-service personal access token
+// endpoints (list, create and revoke the caller's own tokens).
+type PersonalAccessTokenHandler struct {
+	patService service.PersonalAccessTokenService
+}
+
+// NewPersonalAccessTokenHandler creates a new PersonalAccessTokenHandler.
+func NewPersonalAccessTokenHandler(patService service.PersonalAccessTokenService) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{patService: patService}
+}
+
+// List handles GET /personal-access-tokens. Returns all of the authenticated
+// user's tokens.
+func (h *PersonalAccessTokenHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	tokens, err := h.patService.List(r.Context(), user.UserID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to retrieve personal access tokens", err)
+		return
+	}
+
+	resp.Success(w, tokens, "Personal access tokens retrieved successfully")
+}
+
+// Create handles POST /personal-access-tokens. The plain-text token is only
+// returned in this response.
+func (h *PersonalAccessTokenHandler) Create(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	var req dto.PersonalAccessTokenCreateRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	token, err := h.patService.Create(r.Context(), auth.User, auth.Tenant.TenantID, req)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to create personal access token", err)
+		return
+	}
+
+	resp.Created(w, token, "Personal access token created successfully")
+}
+
+// Revoke handles DELETE /personal-access-tokens/{personal_access_token_uuid}.
+func (h *PersonalAccessTokenHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	tokenUUID, err := uuid.Parse(chi.URLParam(r, "personal_access_token_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid personal access token UUID")
+		return
+	}
+
+	if err := h.patService.Revoke(r.Context(), tokenUUID, user.UserID); err != nil {
+		resp.HandleServiceError(w, r, "Failed to revoke personal access token", err)
+		return
+	}
+
+	resp.Success(w, nil, "Personal access token revoked successfully")
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// List
+// ---------------------------------------------------------------------------
+
+func TestPersonalAccessTokenHandler_List(t *testing.T) {
+	t.Run("no user", func(t *testing.T) {
+		h := NewPersonalAccessTokenHandler(&mockPersonalAccessTokenService{})
+		w := httptest.NewRecorder()
+		h.List(w, httptest.NewRequest(http.MethodGet, "/personal-access-tokens", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewPersonalAccessTokenHandler(&mockPersonalAccessTokenService{
+			listFn: func(context.Context, int64) ([]dto.PersonalAccessTokenResponseDTO, error) {
+				return nil, errNotFound
+			},
+		})
+		w := httptest.NewRecorder()
+		h.List(w, withUser(httptest.NewRequest(http.MethodGet, "/personal-access-tokens", nil)))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewPersonalAccessTokenHandler(&mockPersonalAccessTokenService{
+			listFn: func(context.Context, int64) ([]dto.PersonalAccessTokenResponseDTO, error) {
+				return []dto.PersonalAccessTokenResponseDTO{{Name: "ci", TokenPrefix: "pat_abcdefgh"}}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.List(w, withUser(httptest.NewRequest(http.MethodGet, "/personal-access-tokens", nil)))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "pat_abcdefgh")
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Create
+// ---------------------------------------------------------------------------
+
+func TestPersonalAccessTokenHandler_Create(t *testing.T) {
+	valid := dto.PersonalAccessTokenCreateRequestDTO{Name: "ci", Scopes: []string{"user:read"}}
+
+	t.Run("no user", func(t *testing.T) {
+		h := NewPersonalAccessTokenHandler(&mockPersonalAccessTokenService{})
+		w := httptest.NewRecorder()
+		h.Create(w, jsonReq(t, http.MethodPost, "/personal-access-tokens", valid))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("no tenant", func(t *testing.T) {
+		h := NewPersonalAccessTokenHandler(&mockPersonalAccessTokenService{})
+		w := httptest.NewRecorder()
+		h.Create(w, withUser(jsonReq(t, http.MethodPost, "/personal-access-tokens", valid)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("bad json", func(t *testing.T) {
+		h := NewPersonalAccessTokenHandler(&mockPersonalAccessTokenService{})
+		w := httptest.NewRecorder()
+		h.Create(w, withTenantAndUser(badJSONReq(t, http.MethodPost, "/personal-access-tokens")))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		h := NewPersonalAccessTokenHandler(&mockPersonalAccessTokenService{})
+		w := httptest.NewRecorder()
+		h.Create(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/personal-access-tokens", dto.PersonalAccessTokenCreateRequestDTO{Name: "ci"})))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewPersonalAccessTokenHandler(&mockPersonalAccessTokenService{
+			createFn: func(context.Context, *model.User, int64, dto.PersonalAccessTokenCreateRequestDTO) (*dto.PersonalAccessTokenCreateResponseDTO, error) {
+				return nil, errValidation
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Create(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/personal-access-tokens", valid)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success returns the token once", func(t *testing.T) {
+		h := NewPersonalAccessTokenHandler(&mockPersonalAccessTokenService{
+			createFn: func(_ context.Context, user *model.User, tid int64, req dto.PersonalAccessTokenCreateRequestDTO) (*dto.PersonalAccessTokenCreateResponseDTO, error) {
+				assert.Equal(t, testUserUUID, user.UserUUID)
+				assert.Equal(t, tenantID, tid)
+				assert.Equal(t, valid.Scopes, req.Scopes)
+				return &dto.PersonalAccessTokenCreateResponseDTO{
+					PersonalAccessTokenResponseDTO: dto.PersonalAccessTokenResponseDTO{Name: "ci"},
+					Token:                          "pat_secret",
+				}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Create(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/personal-access-tokens", valid)))
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var body struct {
+			Data dto.PersonalAccessTokenCreateResponseDTO `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		assert.Equal(t, "pat_secret", body.Data.Token)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Revoke
+// ---------------------------------------------------------------------------
+
+func TestPersonalAccessTokenHandler_Revoke(t *testing.T) {
+	tokenUUID := uuid.New()
+
+	t.Run("no user", func(t *testing.T) {
+		h := NewPersonalAccessTokenHandler(&mockPersonalAccessTokenService{})
+		w := httptest.NewRecorder()
+		h.Revoke(w, httptest.NewRequest(http.MethodDelete, "/personal-access-tokens/x", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid uuid", func(t *testing.T) {
+		h := NewPersonalAccessTokenHandler(&mockPersonalAccessTokenService{})
+		w := httptest.NewRecorder()
+		r := withChiParam(withUser(httptest.NewRequest(http.MethodDelete, "/personal-access-tokens/x", nil)), "personal_access_token_uuid", "x")
+		h.Revoke(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		h := NewPersonalAccessTokenHandler(&mockPersonalAccessTokenService{
+			revokeFn: func(context.Context, uuid.UUID, int64) error { return errNotFound },
+		})
+		w := httptest.NewRecorder()
+		r := withChiParam(withUser(httptest.NewRequest(http.MethodDelete, "/", nil)), "personal_access_token_uuid", tokenUUID.String())
+		h.Revoke(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewPersonalAccessTokenHandler(&mockPersonalAccessTokenService{
+			revokeFn: func(_ context.Context, id uuid.UUID, _ int64) error {
+				assert.Equal(t, tokenUUID, id)
+				return nil
+			},
+		})
+		w := httptest.NewRecorder()
+		r := withChiParam(withUser(httptest.NewRequest(http.MethodDelete, "/", nil)), "personal_access_token_uuid", tokenUUID.String())
+		h.Revoke(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
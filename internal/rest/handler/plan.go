@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/model"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// PlanHandler handles HTTP requests for billing plans and the plan each
+// tenant is subscribed to.
+type PlanHandler struct {
+	planService service.PlanService
+}
+
+// NewPlanHandler creates a new PlanHandler.
+func NewPlanHandler(planService service.PlanService) *PlanHandler {
+	return &PlanHandler{planService: planService}
+}
+
+// List returns all plans, the default plan first.
+//
+// GET /admin/plans
+func (h *PlanHandler) List(w http.ResponseWriter, r *http.Request) {
+	plans, err := h.planService.List(r.Context())
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to list plans", err)
+		return
+	}
+
+	rows := make([]dto.PlanResponseDTO, len(plans))
+	for i, plan := range plans {
+		rows[i] = toPlanResponseDTO(plan)
+	}
+
+	resp.Success(w, rows, "Plans retrieved successfully")
+}
+
+// Get returns a plan.
+//
+// GET /admin/plans/{plan_uuid}
+func (h *PlanHandler) Get(w http.ResponseWriter, r *http.Request) {
+	planUUID, err := uuid.Parse(chi.URLParam(r, "plan_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid plan UUID")
+		return
+	}
+
+	plan, err := h.planService.GetByUUID(r.Context(), planUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Plan not found", err)
+		return
+	}
+
+	resp.Success(w, toPlanResponseDTO(*plan), "Plan retrieved successfully")
+}
+
+// Create creates a plan.
+//
+// POST /admin/plans
+func (h *PlanHandler) Create(w http.ResponseWriter, r *http.Request) {
+	input, ok := decodePlanRequest(w, r)
+	if !ok {
+		return
+	}
+
+	plan, err := h.planService.Create(r.Context(), input)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to create plan", err)
+		return
+	}
+
+	resp.Created(w, toPlanResponseDTO(*plan), "Plan created successfully")
+}
+
+// Update replaces a plan.
+//
+// PUT /admin/plans/{plan_uuid}
+func (h *PlanHandler) Update(w http.ResponseWriter, r *http.Request) {
+	planUUID, err := uuid.Parse(chi.URLParam(r, "plan_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid plan UUID")
+		return
+	}
+
+	input, ok := decodePlanRequest(w, r)
+	if !ok {
+		return
+	}
+
+	plan, err := h.planService.Update(r.Context(), planUUID, input)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to update plan", err)
+		return
+	}
+
+	resp.Success(w, toPlanResponseDTO(*plan), "Plan updated successfully")
+}
+
+// Delete deletes a plan no tenant is subscribed to.
+//
+// DELETE /admin/plans/{plan_uuid}
+func (h *PlanHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	planUUID, err := uuid.Parse(chi.URLParam(r, "plan_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid plan UUID")
+		return
+	}
+
+	plan, err := h.planService.Delete(r.Context(), planUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to delete plan", err)
+		return
+	}
+
+	resp.Success(w, toPlanResponseDTO(*plan), "Plan deleted successfully")
+}
+
+// GetTenantPlan returns the plan of a tenant with its users and clients.
+//
+// GET /tenants/{tenant_uuid}/plan
+func (h *PlanHandler) GetTenantPlan(w http.ResponseWriter, r *http.Request) {
+	tenantUUID, err := uuid.Parse(chi.URLParam(r, "tenant_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid tenant UUID")
+		return
+	}
+
+	result, err := h.planService.GetTenantPlan(r.Context(), tenantUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get tenant plan", err)
+		return
+	}
+
+	resp.Success(w, toTenantPlanResponseDTO(*result), "Tenant plan retrieved successfully")
+}
+
+// SetTenantPlan subscribes a tenant to a plan, or to the default plan when
+// plan_id is null.
+//
+// PUT /tenants/{tenant_uuid}/plan
+func (h *PlanHandler) SetTenantPlan(w http.ResponseWriter, r *http.Request) {
+	tenantUUID, err := uuid.Parse(chi.URLParam(r, "tenant_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid tenant UUID")
+		return
+	}
+
+	var req dto.TenantPlanSetRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	var planUUID *uuid.UUID
+	if req.PlanID != nil {
+		id := uuid.MustParse(*req.PlanID)
+		planUUID = &id
+	}
+
+	result, err := h.planService.SetTenantPlan(r.Context(), tenantUUID, planUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to set tenant plan", err)
+		return
+	}
+
+	resp.Success(w, toTenantPlanResponseDTO(*result), "Tenant plan updated successfully")
+}
+
+// decodePlanRequest decodes and validates a plan request body, writing the
+// error response when it is invalid.
+func decodePlanRequest(w http.ResponseWriter, r *http.Request) (service.PlanServiceInput, bool) {
+	var req dto.PlanRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return service.PlanServiceInput{}, false
+	}
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return service.PlanServiceInput{}, false
+	}
+
+	status := model.StatusActive
+	if req.Status != nil {
+		status = *req.Status
+	}
+
+	return service.PlanServiceInput{
+		Name:        req.Name,
+		DisplayName: req.DisplayName,
+		Description: req.Description,
+		Features:    req.Features,
+		MaxUsers:    req.MaxUsers,
+		MaxClients:  req.MaxClients,
+		IsDefault:   req.IsDefault,
+		Status:      status,
+	}, true
+}
+
+func toPlanResponseDTO(p service.PlanServiceDataResult) dto.PlanResponseDTO {
+	return dto.PlanResponseDTO{
+		PlanID:      p.PlanUUID.String(),
+		Name:        p.Name,
+		DisplayName: p.DisplayName,
+		Description: p.Description,
+		Features:    p.Features,
+		MaxUsers:    p.MaxUsers,
+		MaxClients:  p.MaxClients,
+		IsDefault:   p.IsDefault,
+		Status:      p.Status,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+}
+
+func toTenantPlanResponseDTO(r service.PlanServiceTenantPlan) dto.TenantPlanResponseDTO {
+	result := dto.TenantPlanResponseDTO{
+		TenantID:  r.TenantUUID.String(),
+		Inherited: r.Inherited,
+		Users:     r.Users,
+		Clients:   r.Clients,
+	}
+	if r.Plan != nil {
+		plan := toPlanResponseDTO(*r.Plan)
+		result.Plan = &plan
+	}
+	return result
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanHandler_List(t *testing.T) {
+	svc := &mockPlanService{
+		listFn: func() ([]service.PlanServiceDataResult, error) {
+			return []service.PlanServiceDataResult{{PlanUUID: testResourceUUID, Name: "free", IsDefault: true}}, nil
+		},
+	}
+	w := httptest.NewRecorder()
+	NewPlanHandler(svc).List(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"free"`)
+	assert.Contains(t, w.Body.String(), `"is_default":true`)
+}
+
+func TestPlanHandler_Get(t *testing.T) {
+	t.Run("invalid uuid", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewPlanHandler(&mockPlanService{}).Get(w, withChiParam(httptest.NewRequest(http.MethodGet, "/", nil), "plan_uuid", "bad"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc := &mockPlanService{
+			getByUUIDFn: func(uuid.UUID) (*service.PlanServiceDataResult, error) { return nil, errNotFound },
+		}
+		w := httptest.NewRecorder()
+		NewPlanHandler(svc).Get(w, withChiParam(httptest.NewRequest(http.MethodGet, "/", nil), "plan_uuid", testResourceUUID.String()))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestPlanHandler_Create(t *testing.T) {
+	t.Run("bad json", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewPlanHandler(&mockPlanService{}).Create(w, badJSONReq(t, http.MethodPost, "/"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewPlanHandler(&mockPlanService{}).Create(w, jsonReq(t, http.MethodPost, "/", map[string]any{"name": "Pro Plan"}))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success defaults to active", func(t *testing.T) {
+		svc := &mockPlanService{
+			createFn: func(input service.PlanServiceInput) (*service.PlanServiceDataResult, error) {
+				assert.Equal(t, model.StatusActive, input.Status)
+				assert.True(t, input.Features[model.PlanFeatureMFA])
+				return &service.PlanServiceDataResult{Name: input.Name, MaxUsers: input.MaxUsers}, nil
+			},
+		}
+		w := httptest.NewRecorder()
+		NewPlanHandler(svc).Create(w, jsonReq(t, http.MethodPost, "/", map[string]any{
+			"name":      "pro",
+			"features":  map[string]bool{model.PlanFeatureMFA: true},
+			"max_users": 100,
+		}))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"max_users":100`)
+	})
+}
+
+func TestPlanHandler_Update(t *testing.T) {
+	svc := &mockPlanService{
+		updateFn: func(uuid.UUID, service.PlanServiceInput) (*service.PlanServiceDataResult, error) {
+			return nil, apperror.NewConflict("plan name already exists")
+		},
+	}
+	r := withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{"name": "pro"}), "plan_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	NewPlanHandler(svc).Update(w, r)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestPlanHandler_Delete(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewPlanHandler(&mockPlanService{}).Delete(w, withChiParam(httptest.NewRequest(http.MethodDelete, "/", nil), "plan_uuid", testResourceUUID.String()))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestPlanHandler_GetTenantPlan(t *testing.T) {
+	svc := &mockPlanService{
+		getTenantPlanFn: func(id uuid.UUID) (*service.PlanServiceTenantPlan, error) {
+			return &service.PlanServiceTenantPlan{
+				TenantUUID: id,
+				Plan:       &service.PlanServiceDataResult{Name: "free", MaxUsers: 5},
+				Inherited:  true,
+				Users:      3,
+			}, nil
+		},
+	}
+	w := httptest.NewRecorder()
+	NewPlanHandler(svc).GetTenantPlan(w, withChiParam(httptest.NewRequest(http.MethodGet, "/", nil), "tenant_uuid", testTenantUUID.String()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"inherited":true`)
+	assert.Contains(t, w.Body.String(), `"users":3`)
+}
+
+func TestPlanHandler_SetTenantPlan(t *testing.T) {
+	t.Run("invalid plan id", func(t *testing.T) {
+		r := withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{"plan_id": "pro"}), "tenant_uuid", testTenantUUID.String())
+		w := httptest.NewRecorder()
+		NewPlanHandler(&mockPlanService{}).SetTenantPlan(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("null plan id selects the default plan", func(t *testing.T) {
+		svc := &mockPlanService{
+			setTenantPlanFn: func(id uuid.UUID, planUUID *uuid.UUID) (*service.PlanServiceTenantPlan, error) {
+				assert.Nil(t, planUUID)
+				return &service.PlanServiceTenantPlan{TenantUUID: id, Inherited: true}, nil
+			},
+		}
+		r := withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{"plan_id": nil}), "tenant_uuid", testTenantUUID.String())
+		w := httptest.NewRecorder()
+		NewPlanHandler(svc).SetTenantPlan(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("plan id", func(t *testing.T) {
+		svc := &mockPlanService{
+			setTenantPlanFn: func(id uuid.UUID, planUUID *uuid.UUID) (*service.PlanServiceTenantPlan, error) {
+				assert.Equal(t, testResourceUUID, *planUUID)
+				return nil, errForbidden
+			},
+		}
+		r := withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{"plan_id": testResourceUUID.String()}), "tenant_uuid", testTenantUUID.String())
+		w := httptest.NewRecorder()
+		NewPlanHandler(svc).SetTenantPlan(w, r)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
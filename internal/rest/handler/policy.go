@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -48,24 +47,8 @@ func (h *PolicyHandler) Get(w http.ResponseWriter, r *http.Request) {
 		filter.Version = &version
 	}
 	// Handle status filtering - support both comma-separated and multiple parameters
-	var statusValues []string
-	if singleStatus := query.Get("status"); singleStatus != "" {
-		// Handle comma-separated values like services: ?status=active,inactive
-		statusValues = strings.Split(strings.ReplaceAll(singleStatus, " ", ""), ",")
-	} else if multipleStatus := query["status"]; len(multipleStatus) > 0 {
-		// Handle multiple parameters: ?status=active&status=inactive
-		statusValues = multipleStatus
-	}
-
-	// Filter out empty status values
-	var validStatus []string
-	for _, s := range statusValues {
-		if s != "" {
-			validStatus = append(validStatus, s)
-		}
-	}
-	if len(validStatus) > 0 {
-		filter.Status = validStatus
+	if status := queryValues(query, "status"); len(status) > 0 {
+		filter.Status = status
 	}
 	if isSystem := query.Get("is_system"); isSystem != "" {
 		if val, err := strconv.ParseBool(isSystem); err == nil {
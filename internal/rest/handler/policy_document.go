@@ -0,0 +1,365 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// PolicyDocumentHandler handles HTTP requests for terms of service and
+// privacy policy versions and their acceptances.
+type PolicyDocumentHandler struct {
+	policyDocumentService service.PolicyDocumentService
+}
+
+// NewPolicyDocumentHandler creates a new PolicyDocumentHandler.
+func NewPolicyDocumentHandler(policyDocumentService service.PolicyDocumentService) *PolicyDocumentHandler {
+	return &PolicyDocumentHandler{policyDocumentService: policyDocumentService}
+}
+
+// GetAll retrieves the tenant's policy documents with optional filtering and
+// pagination.
+//
+// GET /policy-documents
+func (h *PolicyDocumentHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	q := r.URL.Query()
+
+	page, _ := strconv.Atoi(q.Get("page"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+
+	var published *bool
+	if v := q.Get("published"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err == nil {
+			published = &parsed
+		}
+	}
+
+	filter := dto.PolicyDocumentFilterDTO{
+		Type:      queryValues(q, "type"),
+		Published: published,
+		PaginationRequestDTO: dto.PaginationRequestDTO{
+			Page:      page,
+			Limit:     limit,
+			SortBy:    q.Get("sort_by"),
+			SortOrder: q.Get("sort_order"),
+		},
+	}
+
+	if err := filter.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.policyDocumentService.GetAll(
+		r.Context(), tenant.TenantID,
+		filter.Type, filter.Published,
+		filter.Page, filter.Limit,
+		filter.SortBy, filter.SortOrder,
+	)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get policy documents", err)
+		return
+	}
+
+	response := dto.PaginatedResponseDTO[dto.PolicyDocumentResponseDTO]{
+		Rows:       toPolicyDocumentResponseDTOList(result.Data),
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}
+
+	resp.Success(w, response, "Policy documents retrieved successfully")
+}
+
+// Get retrieves a specific policy document by UUID.
+//
+// GET /policy-documents/{policy_document_uuid}
+func (h *PolicyDocumentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	docUUID, err := uuid.Parse(chi.URLParam(r, "policy_document_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid policy document UUID")
+		return
+	}
+
+	result, err := h.policyDocumentService.GetByUUID(r.Context(), tenant.TenantID, docUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Policy document not found", err)
+		return
+	}
+
+	resp.Success(w, toPolicyDocumentResponseDTO(*result), "Policy document retrieved successfully")
+}
+
+// GetCurrent returns the current version of each policy document of the
+// client's tenant, so sign-in and sign-up pages can link them.
+//
+// GET /policy-documents/current?client_id=…&provider_id=…
+func (h *PolicyDocumentHandler) GetCurrent(w http.ResponseWriter, r *http.Request) {
+	q := dto.LoginQueryDTO{
+		ClientID:   r.URL.Query().Get("client_id"),
+		ProviderID: r.URL.Query().Get("provider_id"),
+	}
+	if err := q.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.policyDocumentService.GetCurrent(r.Context(), q.ClientID, q.ProviderID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get policies", err)
+		return
+	}
+
+	resp.Success(w, toPolicyDocumentResponseDTOList(result), "Policies retrieved successfully")
+}
+
+// Create creates a draft policy document.
+//
+// POST /policy-documents
+func (h *PolicyDocumentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	var req dto.PolicyDocumentRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.policyDocumentService.Create(r.Context(), tenant.TenantID, toPolicyDocumentInput(req))
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to create policy document", err)
+		return
+	}
+
+	resp.Created(w, toPolicyDocumentResponseDTO(*result), "Policy document created successfully")
+}
+
+// Update replaces a draft policy document.
+//
+// PUT /policy-documents/{policy_document_uuid}
+func (h *PolicyDocumentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	docUUID, err := uuid.Parse(chi.URLParam(r, "policy_document_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid policy document UUID")
+		return
+	}
+
+	var req dto.PolicyDocumentRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.policyDocumentService.Update(r.Context(), tenant.TenantID, docUUID, toPolicyDocumentInput(req))
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to update policy document", err)
+		return
+	}
+
+	resp.Success(w, toPolicyDocumentResponseDTO(*result), "Policy document updated successfully")
+}
+
+// Delete deletes a draft policy document.
+//
+// DELETE /policy-documents/{policy_document_uuid}
+func (h *PolicyDocumentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	docUUID, err := uuid.Parse(chi.URLParam(r, "policy_document_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid policy document UUID")
+		return
+	}
+
+	result, err := h.policyDocumentService.Delete(r.Context(), tenant.TenantID, docUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to delete policy document", err)
+		return
+	}
+
+	resp.Success(w, toPolicyDocumentResponseDTO(*result), "Policy document deleted successfully")
+}
+
+// Publish makes a draft the current version of its type. Users must accept
+// it at their next login.
+//
+// POST /policy-documents/{policy_document_uuid}/publish
+func (h *PolicyDocumentHandler) Publish(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	docUUID, err := uuid.Parse(chi.URLParam(r, "policy_document_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid policy document UUID")
+		return
+	}
+
+	result, err := h.policyDocumentService.Publish(r.Context(), tenant.TenantID, docUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to publish policy document", err)
+		return
+	}
+
+	resp.Success(w, toPolicyDocumentResponseDTO(*result), "Policy document published successfully")
+}
+
+// GetAcceptances lists who accepted a policy document, most recent first.
+//
+// GET /policy-documents/{policy_document_uuid}/acceptances
+func (h *PolicyDocumentHandler) GetAcceptances(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	docUUID, err := uuid.Parse(chi.URLParam(r, "policy_document_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid policy document UUID")
+		return
+	}
+
+	page, limit := parsePolicyAcceptancePage(r)
+	result, err := h.policyDocumentService.GetAcceptances(r.Context(), tenant.TenantID, docUUID, page, limit)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get policy acceptances", err)
+		return
+	}
+
+	resp.Success(w, toPolicyAcceptancePaginatedResponseDTO(result), "Policy acceptances retrieved successfully")
+}
+
+// GetUserAcceptances lists the policy documents a user accepted, most
+// recent first.
+//
+// GET /users/{user_uuid}/policy-acceptances
+func (h *PolicyDocumentHandler) GetUserAcceptances(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid user UUID")
+		return
+	}
+
+	page, limit := parsePolicyAcceptancePage(r)
+	result, err := h.policyDocumentService.GetUserAcceptances(r.Context(), tenant.TenantID, userUUID, page, limit)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get policy acceptances", err)
+		return
+	}
+
+	resp.Success(w, toPolicyAcceptancePaginatedResponseDTO(result), "Policy acceptances retrieved successfully")
+}
+
+func parsePolicyAcceptancePage(r *http.Request) (int, int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	return page, limit
+}
+
+func toPolicyDocumentInput(req dto.PolicyDocumentRequestDTO) service.PolicyDocumentInput {
+	return service.PolicyDocumentInput{
+		Type:              req.Type,
+		Version:           req.Version,
+		Title:             req.Title,
+		URL:               req.URL,
+		RequireAcceptance: req.RequireAcceptance,
+	}
+}
+
+func toPolicyDocumentResponseDTO(d service.PolicyDocumentServiceDataResult) dto.PolicyDocumentResponseDTO {
+	return dto.PolicyDocumentResponseDTO{
+		PolicyDocumentID:  d.PolicyDocumentUUID.String(),
+		Type:              d.Type,
+		Version:           d.Version,
+		Title:             d.Title,
+		URL:               d.URL,
+		RequireAcceptance: d.RequireAcceptance,
+		PublishedAt:       d.PublishedAt,
+		CreatedAt:         d.CreatedAt,
+		UpdatedAt:         d.UpdatedAt,
+	}
+}
+
+func toPolicyDocumentResponseDTOList(docs []service.PolicyDocumentServiceDataResult) []dto.PolicyDocumentResponseDTO {
+	out := make([]dto.PolicyDocumentResponseDTO, len(docs))
+	for i, d := range docs {
+		out[i] = toPolicyDocumentResponseDTO(d)
+	}
+	return out
+}
+
+func toPolicyAcceptancePaginatedResponseDTO(result *service.PolicyAcceptanceServiceListResult) dto.PaginatedResponseDTO[dto.PolicyAcceptanceResponseDTO] {
+	rows := make([]dto.PolicyAcceptanceResponseDTO, len(result.Data))
+	for i, a := range result.Data {
+		rows[i] = dto.PolicyAcceptanceResponseDTO{
+			PolicyDocument: toPolicyDocumentResponseDTO(a.Document),
+			UserID:         a.UserUUID.String(),
+			Username:       a.Username,
+			Email:          a.Email,
+			IPAddress:      a.IPAddress,
+			UserAgent:      a.UserAgent,
+			AcceptedAt:     a.AcceptedAt,
+		}
+	}
+	return dto.PaginatedResponseDTO[dto.PolicyAcceptanceResponseDTO]{
+		Rows:       rows,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}
+}
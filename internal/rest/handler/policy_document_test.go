@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func policyDocumentResult() *service.PolicyDocumentServiceDataResult {
+	return &service.PolicyDocumentServiceDataResult{
+		PolicyDocumentUUID: uuid.New(),
+		Type:               model.PolicyDocumentTypeTermsOfService,
+		Version:            "2026-10",
+		Title:              "Terms of Service",
+		URL:                "https://example.com/terms",
+		RequireAcceptance:  true,
+	}
+}
+
+func policyDocumentBody() map[string]any {
+	return map[string]any{
+		"type":    model.PolicyDocumentTypeTermsOfService,
+		"version": "2026-10",
+		"title":   "Terms of Service",
+		"url":     "https://example.com/terms",
+	}
+}
+
+// ---------------------------------------------------------------------------
+// GetAll
+// ---------------------------------------------------------------------------
+
+func TestPolicyDocumentHandler_GetAll_NoTenant(t *testing.T) {
+	h := NewPolicyDocumentHandler(&mockPolicyDocumentService{})
+	w := httptest.NewRecorder()
+	h.GetAll(w, httptest.NewRequest(http.MethodGet, "/policy-documents", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestPolicyDocumentHandler_GetAll_ValidationError(t *testing.T) {
+	h := NewPolicyDocumentHandler(&mockPolicyDocumentService{})
+	w := httptest.NewRecorder()
+	h.GetAll(w, withTenant(httptest.NewRequest(http.MethodGet, "/policy-documents?type=cookie_policy", nil)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPolicyDocumentHandler_GetAll_Success(t *testing.T) {
+	svc := &mockPolicyDocumentService{
+		getAllFn: func(_ int64, types []string, published *bool, _, _ int, _, _ string) (*service.PolicyDocumentServiceListResult, error) {
+			assert.Equal(t, []string{model.PolicyDocumentTypePrivacyPolicy}, types)
+			if assert.NotNil(t, published) {
+				assert.True(t, *published)
+			}
+			return &service.PolicyDocumentServiceListResult{
+				Data:       []service.PolicyDocumentServiceDataResult{*policyDocumentResult()},
+				Total:      1,
+				Page:       1,
+				Limit:      10,
+				TotalPages: 1,
+			}, nil
+		},
+	}
+	h := NewPolicyDocumentHandler(svc)
+	w := httptest.NewRecorder()
+	h.GetAll(w, withTenant(httptest.NewRequest(http.MethodGet, "/policy-documents?type=privacy_policy&published=true&page=1&limit=10", nil)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"version":"2026-10"`)
+}
+
+// ---------------------------------------------------------------------------
+// Get
+// ---------------------------------------------------------------------------
+
+func TestPolicyDocumentHandler_Get_InvalidUUID(t *testing.T) {
+	h := NewPolicyDocumentHandler(&mockPolicyDocumentService{})
+	r := withChiParam(withTenant(httptest.NewRequest(http.MethodGet, "/policy-documents/x", nil)), "policy_document_uuid", "x")
+	w := httptest.NewRecorder()
+	h.Get(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPolicyDocumentHandler_Get_NotFound(t *testing.T) {
+	svc := &mockPolicyDocumentService{
+		getByUUIDFn: func(_ int64, _ uuid.UUID) (*service.PolicyDocumentServiceDataResult, error) { return nil, errNotFound },
+	}
+	h := NewPolicyDocumentHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/policy-documents/"+testResourceUUID.String(), nil))
+	r = withChiParam(r, "policy_document_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.Get(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// ---------------------------------------------------------------------------
+// GetCurrent
+// ---------------------------------------------------------------------------
+
+func TestPolicyDocumentHandler_GetCurrent_MissingClient(t *testing.T) {
+	h := NewPolicyDocumentHandler(&mockPolicyDocumentService{})
+	w := httptest.NewRecorder()
+	h.GetCurrent(w, httptest.NewRequest(http.MethodGet, "/policy-documents/current?provider_id=p", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPolicyDocumentHandler_GetCurrent_Success(t *testing.T) {
+	svc := &mockPolicyDocumentService{
+		getCurrentFn: func(clientID, providerID string) ([]service.PolicyDocumentServiceDataResult, error) {
+			assert.Equal(t, "c", clientID)
+			assert.Equal(t, "p", providerID)
+			doc := policyDocumentResult()
+			doc.PublishedAt = &time.Time{}
+			return []service.PolicyDocumentServiceDataResult{*doc}, nil
+		},
+	}
+	h := NewPolicyDocumentHandler(svc)
+	w := httptest.NewRecorder()
+	h.GetCurrent(w, httptest.NewRequest(http.MethodGet, "/policy-documents/current?client_id=c&provider_id=p", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"type":"terms_of_service"`)
+}
+
+// ---------------------------------------------------------------------------
+// Create / Update / Delete / Publish
+// ---------------------------------------------------------------------------
+
+func TestPolicyDocumentHandler_Create_BadJSON(t *testing.T) {
+	h := NewPolicyDocumentHandler(&mockPolicyDocumentService{})
+	w := httptest.NewRecorder()
+	h.Create(w, withTenant(badJSONReq(t, http.MethodPost, "/policy-documents")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPolicyDocumentHandler_Create_ValidationError(t *testing.T) {
+	h := NewPolicyDocumentHandler(&mockPolicyDocumentService{})
+	body := policyDocumentBody()
+	body["url"] = "not a url"
+	w := httptest.NewRecorder()
+	h.Create(w, withTenant(jsonReq(t, http.MethodPost, "/policy-documents", body)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPolicyDocumentHandler_Create_Success(t *testing.T) {
+	svc := &mockPolicyDocumentService{
+		createFn: func(tid int64, input service.PolicyDocumentInput) (*service.PolicyDocumentServiceDataResult, error) {
+			assert.Equal(t, tenantID, tid)
+			assert.Equal(t, "2026-10", input.Version)
+			assert.Nil(t, input.RequireAcceptance)
+			return policyDocumentResult(), nil
+		},
+	}
+	h := NewPolicyDocumentHandler(svc)
+	w := httptest.NewRecorder()
+	h.Create(w, withTenant(jsonReq(t, http.MethodPost, "/policy-documents", policyDocumentBody())))
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestPolicyDocumentHandler_Update_Published(t *testing.T) {
+	svc := &mockPolicyDocumentService{
+		updateFn: func(_ int64, _ uuid.UUID, _ service.PolicyDocumentInput) (*service.PolicyDocumentServiceDataResult, error) {
+			return nil, apperror.NewConflict("published policy documents cannot be changed; create a new version instead")
+		},
+	}
+	h := NewPolicyDocumentHandler(svc)
+	r := withTenant(jsonReq(t, http.MethodPut, "/policy-documents/"+testResourceUUID.String(), policyDocumentBody()))
+	r = withChiParam(r, "policy_document_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.Update(w, r)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestPolicyDocumentHandler_Delete_Success(t *testing.T) {
+	svc := &mockPolicyDocumentService{
+		deleteFn: func(_ int64, id uuid.UUID) (*service.PolicyDocumentServiceDataResult, error) {
+			assert.Equal(t, testResourceUUID, id)
+			return policyDocumentResult(), nil
+		},
+	}
+	h := NewPolicyDocumentHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodDelete, "/policy-documents/"+testResourceUUID.String(), nil))
+	r = withChiParam(r, "policy_document_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.Delete(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestPolicyDocumentHandler_Publish_Success(t *testing.T) {
+	svc := &mockPolicyDocumentService{
+		publishFn: func(_ int64, _ uuid.UUID) (*service.PolicyDocumentServiceDataResult, error) {
+			doc := policyDocumentResult()
+			now := time.Now()
+			doc.PublishedAt = &now
+			return doc, nil
+		},
+	}
+	h := NewPolicyDocumentHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodPost, "/policy-documents/"+testResourceUUID.String()+"/publish", nil))
+	r = withChiParam(r, "policy_document_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.Publish(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"published_at"`)
+}
+
+// ---------------------------------------------------------------------------
+// Acceptances
+// ---------------------------------------------------------------------------
+
+func TestPolicyDocumentHandler_GetAcceptances_Success(t *testing.T) {
+	svc := &mockPolicyDocumentService{
+		getAcceptancesFn: func(_ int64, _ uuid.UUID, page, limit int) (*service.PolicyAcceptanceServiceListResult, error) {
+			assert.Equal(t, 2, page)
+			assert.Equal(t, 5, limit)
+			return &service.PolicyAcceptanceServiceListResult{
+				Data: []service.PolicyAcceptanceServiceDataResult{{
+					Document: *policyDocumentResult(),
+					UserUUID: testUserUUID,
+					Username: "jane",
+				}},
+				Total: 1,
+			}, nil
+		},
+	}
+	h := NewPolicyDocumentHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/policy-documents/"+testResourceUUID.String()+"/acceptances?page=2&limit=5", nil))
+	r = withChiParam(r, "policy_document_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.GetAcceptances(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"user_id":"`+testUserUUID.String()+`"`)
+}
+
+func TestPolicyDocumentHandler_GetUserAcceptances_InvalidUUID(t *testing.T) {
+	h := NewPolicyDocumentHandler(&mockPolicyDocumentService{})
+	r := withChiParam(withTenant(httptest.NewRequest(http.MethodGet, "/users/x/policy-acceptances", nil)), "user_uuid", "x")
+	w := httptest.NewRecorder()
+	h.GetUserAcceptances(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPolicyDocumentHandler_GetUserAcceptances_NotFound(t *testing.T) {
+	svc := &mockPolicyDocumentService{
+		getUserAcceptancesFn: func(_ int64, _ uuid.UUID, _, _ int) (*service.PolicyAcceptanceServiceListResult, error) {
+			return nil, errNotFound
+		},
+	}
+	h := NewPolicyDocumentHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/users/"+testUserUUID.String()+"/policy-acceptances", nil))
+	r = withChiParam(r, "user_uuid", testUserUUID.String())
+	w := httptest.NewRecorder()
+	h.GetUserAcceptances(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -44,6 +44,10 @@ func (h *ProfileHandler) CreateOrUpdate(w http.ResponseWriter, r *http.Request)
 	}
 
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 	profile, err := h.profileService.CreateOrUpdateProfile(
 		r.Context(),
 		user.UserUUID,
@@ -85,6 +89,10 @@ func (h *ProfileHandler) CreateProfile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	// Generate new UUID for the profile
 	profileUUID := uuid.New()
@@ -139,6 +147,10 @@ func (h *ProfileHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 	profile, err := h.profileService.CreateOrUpdateSpecificProfile(
 		r.Context(),
 		profileUUID,
@@ -163,6 +175,10 @@ func (h *ProfileHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 
 func (h *ProfileHandler) Get(w http.ResponseWriter, r *http.Request) {
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 	profile, err := h.profileService.GetByUserUUID(r.Context(), user.UserUUID)
 	if err != nil || profile == nil {
 		resp.Error(w, http.StatusNotFound, "Profile not found")
@@ -174,6 +190,10 @@ func (h *ProfileHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 func (h *ProfileHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 	q := r.URL.Query()
 
 	// Parse pagination
@@ -254,6 +274,10 @@ func (h *ProfileHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 
 func (h *ProfileHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	// First get the profile to get its UUID
 	profile, err := h.profileService.GetByUserUUID(r.Context(), user.UserUUID)
@@ -274,6 +298,10 @@ func (h *ProfileHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 func (h *ProfileHandler) GetByUUID(w http.ResponseWriter, r *http.Request) {
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	// Get profile UUID from URL parameter
 	profileUUIDStr := chi.URLParam(r, "profile_uuid")
@@ -295,6 +323,10 @@ func (h *ProfileHandler) GetByUUID(w http.ResponseWriter, r *http.Request) {
 
 func (h *ProfileHandler) DeleteByUUID(w http.ResponseWriter, r *http.Request) {
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	// Get profile UUID from URL parameter
 	profileUUIDStr := chi.URLParam(r, "profile_uuid")
@@ -575,6 +607,10 @@ func (h *ProfileHandler) SetDefaultProfile(w http.ResponseWriter, r *http.Reques
 	}
 
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	// Set profile as default with ownership verification
 	profile, err := h.profileService.SetDefaultProfile(r.Context(), profileUUID, user.UserUUID)
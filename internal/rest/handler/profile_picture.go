@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// profilePictureField is the multipart form field holding the upload.
+const profilePictureField = "picture"
+
+// multipartOverhead leaves room for the boundaries and part headers of a
+// multipart body around a picture of the maximum size.
+const multipartOverhead = 64 << 10
+
+// ProfilePictureHandler handles profile picture uploads.
+//
+// Pictures are sent as multipart/form-data in the "picture" field. They are
+// validated, scaled down and re-encoded before they are stored, and profile
+// responses then return a signed URL to them as profile_url.
+type ProfilePictureHandler struct {
+	profileService service.ProfileService
+	maxBytes       int
+}
+
+// NewProfilePictureHandler creates a profile picture handler accepting
+// uploads of up to maxBytes.
+func NewProfilePictureHandler(profileService service.ProfileService, maxBytes int) *ProfilePictureHandler {
+	return &ProfilePictureHandler{profileService: profileService, maxBytes: maxBytes}
+}
+
+// Upload sets the picture of the caller's default profile.
+//
+// PUT /profile/picture
+func (h *ProfilePictureHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+	profile, err := h.profileService.GetByUserUUID(r.Context(), user.UserUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Upload profile picture failed", err)
+		return
+	}
+	h.upload(w, r, profile.ProfileUUID, user.UserUUID)
+}
+
+// UploadByUUID sets the picture of one of the caller's profiles.
+//
+// PUT /profiles/{profile_uuid}/picture
+func (h *ProfilePictureHandler) UploadByUUID(w http.ResponseWriter, r *http.Request) {
+	profileUUID, err := uuid.Parse(chi.URLParam(r, "profile_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid profile UUID")
+		return
+	}
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+	h.upload(w, r, profileUUID, user.UserUUID)
+}
+
+// Delete removes the picture of the caller's default profile.
+//
+// DELETE /profile/picture
+func (h *ProfilePictureHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+	profile, err := h.profileService.GetByUserUUID(r.Context(), user.UserUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Delete profile picture failed", err)
+		return
+	}
+	h.delete(w, r, profile.ProfileUUID, user.UserUUID)
+}
+
+// DeleteByUUID removes the picture of one of the caller's profiles.
+//
+// DELETE /profiles/{profile_uuid}/picture
+func (h *ProfilePictureHandler) DeleteByUUID(w http.ResponseWriter, r *http.Request) {
+	profileUUID, err := uuid.Parse(chi.URLParam(r, "profile_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid profile UUID")
+		return
+	}
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+	h.delete(w, r, profileUUID, user.UserUUID)
+}
+
+func (h *ProfilePictureHandler) upload(w http.ResponseWriter, r *http.Request, profileUUID, userUUID uuid.UUID) {
+	data, status, msg := h.readPicture(w, r)
+	if status != 0 {
+		resp.Error(w, status, msg)
+		return
+	}
+
+	profile, err := h.profileService.SetPicture(r.Context(), profileUUID, userUUID, data)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Upload profile picture failed", err)
+		return
+	}
+
+	resp.Success(w, toProfileResponseDTO(*profile), "Profile picture uploaded successfully")
+}
+
+func (h *ProfilePictureHandler) delete(w http.ResponseWriter, r *http.Request, profileUUID, userUUID uuid.UUID) {
+	profile, err := h.profileService.DeletePicture(r.Context(), profileUUID, userUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Delete profile picture failed", err)
+		return
+	}
+
+	resp.Success(w, toProfileResponseDTO(*profile), "Profile picture deleted successfully")
+}
+
+// readPicture reads the uploaded file, or returns the status and message to
+// reject the request with.
+func (h *ProfilePictureHandler) readPicture(w http.ResponseWriter, r *http.Request) ([]byte, int, string) {
+	tooLarge := fmt.Sprintf("Picture must not exceed %d bytes", h.maxBytes)
+
+	r.Body = http.MaxBytesReader(w, r.Body, int64(h.maxBytes)+multipartOverhead)
+	if err := r.ParseMultipartForm(int64(h.maxBytes)); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, http.StatusRequestEntityTooLarge, tooLarge
+		}
+		return nil, http.StatusBadRequest, "Request must be multipart/form-data"
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile(profilePictureField)
+	if err != nil {
+		return nil, http.StatusBadRequest, "Picture file is required"
+	}
+	defer file.Close()
+	if header.Size > int64(h.maxBytes) {
+		return nil, http.StatusRequestEntityTooLarge, tooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, int64(h.maxBytes)+1))
+	if err != nil {
+		return nil, http.StatusBadRequest, "Picture could not be read"
+	}
+	if len(data) > h.maxBytes {
+		return nil, http.StatusRequestEntityTooLarge, tooLarge
+	}
+	return data, 0, ""
+}
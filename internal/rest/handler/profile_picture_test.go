@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pictureReq builds a multipart request with data in the given field.
+func pictureReq(t *testing.T, method, target, field string, data []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile(field, "avatar.png")
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	r := httptest.NewRequest(method, target, &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	return r
+}
+
+func TestProfilePictureHandler_Upload(t *testing.T) {
+	profUUID := uuid.New()
+	defaultProfile := func(uuid.UUID) (*service.ProfileServiceDataResult, error) {
+		return &service.ProfileServiceDataResult{ProfileUUID: profUUID}, nil
+	}
+
+	t.Run("success uploads to the default profile", func(t *testing.T) {
+		var got []byte
+		var gotProfile uuid.UUID
+		svc := &mockProfileService{
+			getByUserUUIDFn: defaultProfile,
+			setPictureFn: func(p, _ uuid.UUID, data []byte) (*service.ProfileServiceDataResult, error) {
+				gotProfile, got = p, data
+				return &service.ProfileServiceDataResult{ProfileUUID: p}, nil
+			},
+		}
+		r := withTenantAndUser(pictureReq(t, http.MethodPut, "/profile/picture", "picture", []byte("png")))
+		w := httptest.NewRecorder()
+		NewProfilePictureHandler(svc, 1024).Upload(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, profUUID, gotProfile)
+		assert.Equal(t, "png", string(got))
+	})
+
+	t.Run("missing file returns 400", func(t *testing.T) {
+		svc := &mockProfileService{getByUserUUIDFn: defaultProfile}
+		r := withTenantAndUser(pictureReq(t, http.MethodPut, "/profile/picture", "avatar", []byte("png")))
+		w := httptest.NewRecorder()
+		NewProfilePictureHandler(svc, 1024).Upload(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("non-multipart body returns 400", func(t *testing.T) {
+		svc := &mockProfileService{getByUserUUIDFn: defaultProfile}
+		r := withTenantAndUser(jsonReq(t, http.MethodPut, "/profile/picture", map[string]any{"picture": "x"}))
+		w := httptest.NewRecorder()
+		NewProfilePictureHandler(svc, 1024).Upload(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("oversized file returns 413", func(t *testing.T) {
+		svc := &mockProfileService{getByUserUUIDFn: defaultProfile}
+		r := withTenantAndUser(pictureReq(t, http.MethodPut, "/profile/picture", "picture", bytes.Repeat([]byte("x"), 2048)))
+		w := httptest.NewRecorder()
+		NewProfilePictureHandler(svc, 1024).Upload(w, r)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("service error is mapped", func(t *testing.T) {
+		svc := &mockProfileService{
+			getByUserUUIDFn: defaultProfile,
+			setPictureFn: func(uuid.UUID, uuid.UUID, []byte) (*service.ProfileServiceDataResult, error) {
+				return nil, errValidation
+			},
+		}
+		r := withTenantAndUser(pictureReq(t, http.MethodPut, "/profile/picture", "picture", []byte("png")))
+		w := httptest.NewRecorder()
+		NewProfilePictureHandler(svc, 1024).Upload(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestProfilePictureHandler_UploadByUUID(t *testing.T) {
+	t.Run("invalid uuid returns 400", func(t *testing.T) {
+		r := withTenantAndUser(pictureReq(t, http.MethodPut, "/", "picture", []byte("png")))
+		r = withChiParam(r, "profile_uuid", "bad")
+		w := httptest.NewRecorder()
+		NewProfilePictureHandler(&mockProfileService{}, 1024).UploadByUUID(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		svc := &mockProfileService{
+			setPictureFn: func(p, _ uuid.UUID, _ []byte) (*service.ProfileServiceDataResult, error) {
+				return &service.ProfileServiceDataResult{ProfileUUID: p}, nil
+			},
+		}
+		r := withTenantAndUser(pictureReq(t, http.MethodPut, "/", "picture", []byte("png")))
+		r = withChiParam(r, "profile_uuid", testResourceUUID.String())
+		w := httptest.NewRecorder()
+		NewProfilePictureHandler(svc, 1024).UploadByUUID(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestProfilePictureHandler_Delete(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		svc := &mockProfileService{
+			getByUserUUIDFn: func(uuid.UUID) (*service.ProfileServiceDataResult, error) {
+				return &service.ProfileServiceDataResult{ProfileUUID: uuid.New()}, nil
+			},
+			deletePictureFn: func(uuid.UUID, uuid.UUID) (*service.ProfileServiceDataResult, error) {
+				return nil, errNotFound
+			},
+		}
+		r := withTenantAndUser(httptest.NewRequest(http.MethodDelete, "/profile/picture", nil))
+		w := httptest.NewRecorder()
+		NewProfilePictureHandler(svc, 1024).Delete(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("by uuid success", func(t *testing.T) {
+		svc := &mockProfileService{
+			deletePictureFn: func(p, _ uuid.UUID) (*service.ProfileServiceDataResult, error) {
+				return &service.ProfileServiceDataResult{ProfileUUID: p}, nil
+			},
+		}
+		r := withTenantAndUser(httptest.NewRequest(http.MethodDelete, "/", nil))
+		r = withChiParam(r, "profile_uuid", testResourceUUID.String())
+		w := httptest.NewRecorder()
+		NewProfilePictureHandler(svc, 1024).DeleteByUUID(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
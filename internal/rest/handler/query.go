@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/maintainerd/auth/internal/dto"
+)
+
+// queryValues returns every value supplied for a multi-value query parameter.
+// Both repeated parameters (?status=active&status=inactive) and
+// comma-separated lists (?status=active,inactive) are accepted and may be
+// mixed. Values are trimmed and empty entries dropped; nil is returned when
+// the parameter is absent so filters stay unset.
+func queryValues(q url.Values, key string) []string {
+	var values []string
+	for _, raw := range q[key] {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// dryRunRequested reports whether ?dry_run asks for a destructive request to
+// be previewed. An absent parameter is false; a value that is not a boolean
+// is an error.
+func dryRunRequested(q url.Values) (bool, error) {
+	v := q.Get("dry_run")
+	if v == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(v)
+}
+
+// cursorPagination returns the keyset pagination parameters of a list
+// request, or nil when the request pages with page/limit. The presence of
+// ?cursor selects cursor pagination; an empty value requests the first page.
+func cursorPagination(q url.Values) *dto.CursorPaginationRequestDTO {
+	if !q.Has("cursor") {
+		return nil
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	return &dto.CursorPaginationRequestDTO{
+		Cursor:    q.Get("cursor"),
+		Limit:     limit,
+		SortBy:    q.Get("sort_by"),
+		SortOrder: q.Get("sort_order"),
+	}
+}
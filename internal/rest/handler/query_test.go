@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"absent", "", nil},
+		{"empty", "status=", nil},
+		{"single", "status=active", []string{"active"}},
+		{"comma separated", "status=active,inactive", []string{"active", "inactive"}},
+		{"repeated", "status=active&status=inactive", []string{"active", "inactive"}},
+		{"mixed", "status=active,pending&status=inactive", []string{"active", "pending", "inactive"}},
+		{"whitespace and blanks", "status=%20active%20,,inactive%20", []string{"active", "inactive"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, queryValues(q, "status"))
+		})
+	}
+}
+
+func TestDryRunRequested(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    bool
+		wantErr bool
+	}{
+		{"", false, false},
+		{"dry_run=true", true, false},
+		{"dry_run=1", true, false},
+		{"dry_run=false", false, false},
+		{"dry_run=maybe", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			assert.NoError(t, err)
+			got, err := dryRunRequested(q)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
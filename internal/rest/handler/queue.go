@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/maintainerd/auth/internal/dto"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// QueueHandler handles HTTP requests for background worker and queue health.
+type QueueHandler struct {
+	queueHealthService service.QueueHealthService
+}
+
+// NewQueueHandler creates a new QueueHandler.
+func NewQueueHandler(queueHealthService service.QueueHealthService) *QueueHandler {
+	return &QueueHandler{queueHealthService: queueHealthService}
+}
+
+// GetHealth retrieves the status of the background workers of this instance
+// and the backlog of every queue they drain.
+//
+// GET /admin/queues
+func (h *QueueHandler) GetHealth(w http.ResponseWriter, r *http.Request) {
+	result, err := h.queueHealthService.GetHealth(r.Context())
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to retrieve queue health", err)
+		return
+	}
+
+	out := dto.QueueHealthResponseDTO{
+		Alerting: result.Alerting,
+		Workers:  make([]dto.WorkerHealthResponseDTO, len(result.Workers)),
+		Queues:   make([]dto.QueueStatusResponseDTO, len(result.Queues)),
+	}
+	for i, wk := range result.Workers {
+		out.Workers[i] = dto.WorkerHealthResponseDTO{
+			Worker:              wk.Worker,
+			Runs:                wk.Runs,
+			Failures:            wk.Failures,
+			ConsecutiveFailures: wk.ConsecutiveFailures,
+			ItemsProcessed:      wk.Items,
+			LastRunAt:           timeOrNil(wk.LastRunAt),
+			LastSuccessAt:       timeOrNil(wk.LastSuccessAt),
+			LastError:           wk.LastError,
+			Alerting:            wk.Alerting,
+		}
+	}
+	for i, q := range result.Queues {
+		out.Queues[i] = dto.QueueStatusResponseDTO{
+			Queue:            q.Queue,
+			Depth:            q.Depth,
+			OldestAt:         q.OldestAt,
+			OldestAgeSeconds: int64(q.OldestAge.Seconds()),
+			Threshold: dto.QueueThresholdResponseDTO{
+				MaxDepth:            q.Threshold.MaxDepth,
+				MaxOldestAgeSeconds: int64(q.Threshold.MaxOldestAge.Seconds()),
+			},
+			Alerting: q.Alerting,
+		}
+	}
+
+	resp.Success(w, out, "Queue health retrieved successfully")
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/metrics"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueHandler_GetHealth(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		oldest := time.Now().Add(-2 * time.Minute)
+		h := NewQueueHandler(&mockQueueHealthService{
+			getHealthFn: func() (*service.QueueHealthResult, error) {
+				return &service.QueueHealthResult{
+					Alerting: true,
+					Workers: []service.WorkerHealth{{
+						WorkerStatus: metrics.WorkerStatus{Worker: "webhook_delivery", Runs: 5, Failures: 3, ConsecutiveFailures: 3, LastError: "db down"},
+						Alerting:     true,
+					}},
+					Queues: []service.QueueStatus{{
+						Queue: service.QueueWebhookDeliveries, Depth: 7, OldestAt: &oldest, OldestAge: 2 * time.Minute,
+						Threshold: service.QueueThreshold{MaxDepth: 1000, MaxOldestAge: time.Hour},
+					}},
+				}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.GetHealth(w, httptest.NewRequest(http.MethodGet, "/admin/queues", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var body struct {
+			Data dto.QueueHealthResponseDTO `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.True(t, body.Data.Alerting)
+		require.Len(t, body.Data.Workers, 1)
+		assert.Equal(t, "db down", body.Data.Workers[0].LastError)
+		assert.Nil(t, body.Data.Workers[0].LastSuccessAt)
+		require.Len(t, body.Data.Queues, 1)
+		assert.Equal(t, int64(120), body.Data.Queues[0].OldestAgeSeconds)
+		assert.Equal(t, int64(3600), body.Data.Queues[0].Threshold.MaxOldestAgeSeconds)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewQueueHandler(&mockQueueHealthService{
+			getHealthFn: func() (*service.QueueHealthResult, error) { return nil, errors.New("db down") },
+		})
+		w := httptest.NewRecorder()
+		h.GetHealth(w, httptest.NewRequest(http.MethodGet, "/admin/queues", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/ptr"
 	resp "github.com/maintainerd/auth/internal/rest/response"
 	"github.com/maintainerd/auth/internal/security"
 	"github.com/maintainerd/auth/internal/service"
@@ -30,6 +31,7 @@ func (h *RegisterHandler) RegisterPublic(w http.ResponseWriter, r *http.Request)
 	q := dto.RegisterQueryDTO{
 		ClientID:   r.URL.Query().Get("client_id"),
 		ProviderID: r.URL.Query().Get("provider_id"),
+		SignupFlow: r.URL.Query().Get("signup_flow"),
 	}
 
 	if err := q.Validate(); err != nil {
@@ -113,9 +115,22 @@ func (h *RegisterHandler) RegisterPublic(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Parse birthdate string to *time.Time (format already validated by DTO)
+	flow := service.SignupFlowInput{
+		Identifier:    q.SignupFlow,
+		CaptchaToken:  req.CaptchaToken,
+		GuardianEmail: ptr.Deref(req.GuardianEmail),
+	}
+	if req.Birthdate != nil && *req.Birthdate != "" {
+		parsed, _ := time.Parse("2006-01-02", *req.Birthdate)
+		flow.Birthdate = &parsed
+	}
+
 	// Public registration attempt (requires client_id and provider_id)
 	tokenResponse, err := h.registerService.RegisterPublic(
 		r.Context(), req.Username, req.Fullname, req.Password, req.Email, req.Phone, q.ClientID, q.ProviderID,
+		flow,
+		req.AcceptedPolicies,
 	)
 	if err != nil {
 		security.LogSecurityEvent(security.SecurityEvent{
@@ -150,10 +165,64 @@ func (h *RegisterHandler) RegisterPublic(w http.ResponseWriter, r *http.Request)
 		Severity:  "LOW",
 	})
 
+	// Accounts held for email verification get no tokens yet
+	if tokenResponse != nil && tokenResponse.VerificationRequired {
+		resp.Created(w, tokenResponse, "Registration successful, verification code sent")
+		return
+	}
+
 	// Response with optional cookie delivery based on X-Token-Delivery header
 	resp.CreatedWithCookies(w, r, tokenResponse, "Registration successful")
 }
 
+// VerifyEmail confirms the code emailed to a pending user and signs them in.
+func (h *RegisterHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+	sc := extractSecurityContext(r)
+
+	// Validate query parameters
+	q := dto.RegisterQueryDTO{
+		ClientID:   r.URL.Query().Get("client_id"),
+		ProviderID: r.URL.Query().Get("provider_id"),
+	}
+	if err := q.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	// Validate body payload
+	var req dto.VerifyEmailRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	tokenResponse, err := h.registerService.VerifyEmail(r.Context(), req.Email, req.Code, q.ClientID, q.ProviderID)
+	if err != nil {
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "email_verification_failure",
+			ClientID:  q.ClientID,
+			ClientIP:  sc.clientIP,
+			UserAgent: sc.userAgent,
+			RequestID: sc.requestID,
+			Endpoint:  "/register/verify-email",
+			Method:    r.Method,
+			Timestamp: startTime,
+			Details:   "Email verification failed",
+			Severity:  "MEDIUM",
+		})
+		resp.HandleServiceError(w, r, "Email verification failed", err)
+		return
+	}
+
+	// Response with optional cookie delivery based on X-Token-Delivery header
+	resp.SuccessWithCookies(w, r, tokenResponse, "Email verified")
+}
+
 func (h *RegisterHandler) Register(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
 	sc := extractSecurityContext(r)
@@ -207,7 +276,7 @@ func (h *RegisterHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 	// Internal registration attempt (client_id/provider_id optional)
 	tokenResponse, err := h.registerService.Register(
-		r.Context(), req.Username, req.Fullname, req.Password, req.Email, req.Phone, clientIDPtr, providerIDPtr,
+		r.Context(), req.Username, req.Fullname, req.Password, req.Email, req.Phone, clientIDPtr, providerIDPtr, req.AcceptedPolicies,
 	)
 	if err != nil {
 		security.LogSecurityEvent(security.SecurityEvent{
@@ -280,6 +349,7 @@ func (h *RegisterHandler) RegisterInvite(w http.ResponseWriter, r *http.Request)
 		req.Password,
 		inviteToken,
 		clientIDPtr, providerIDPtr,
+		req.AcceptedPolicies,
 	)
 	if err != nil {
 		resp.HandleServiceError(w, r, "Registration failed", err)
@@ -325,6 +395,7 @@ func (h *RegisterHandler) RegisterInvitePublic(w http.ResponseWriter, r *http.Re
 		q.ClientID,
 		q.ProviderID,
 		q.InviteToken,
+		req.AcceptedPolicies,
 	)
 	if err != nil {
 		resp.HandleServiceError(w, r, "Registration failed", err)
@@ -102,6 +102,81 @@ func TestRegisterHandler_RegisterPublic_Success(t *testing.T) {
 	assert.Equal(t, http.StatusCreated, w.Code)
 }
 
+func TestRegisterHandler_RegisterPublic_PassesSignupFlow(t *testing.T) {
+	svc := &mockRegisterService{
+		registerPublicFn: func(u, f, p string, e, ph *string, c, pr string) (*dto.RegisterResponseDTO, error) {
+			return &dto.RegisterResponseDTO{VerificationRequired: true}, nil
+		},
+	}
+	h := NewRegisterHandler(svc)
+	r := regRequest(t, "/public/register?client_id=c1&provider_id=p1&signup_flow=beta", map[string]string{
+		"username": "user1", "password": "Pass@1234!", "fullname": "User One", "captcha_token": "tok",
+	})
+	w := httptest.NewRecorder()
+	h.RegisterPublic(w, r)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "beta", svc.lastSignupFlow.Identifier)
+	assert.Equal(t, "tok", svc.lastSignupFlow.CaptchaToken)
+	assert.Contains(t, w.Body.String(), `"verification_required":true`)
+	assert.Empty(t, w.Result().Cookies())
+}
+
+// ---------------------------------------------------------------------------
+// VerifyEmail
+// ---------------------------------------------------------------------------
+
+func TestRegisterHandler_VerifyEmail_MissingClientID(t *testing.T) {
+	h := NewRegisterHandler(&mockRegisterService{})
+	r := regRequest(t, "/public/register/verify-email", map[string]string{
+		"email": "jane@acme.com", "code": "123456",
+	})
+	w := httptest.NewRecorder()
+	h.VerifyEmail(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRegisterHandler_VerifyEmail_InvalidCode(t *testing.T) {
+	h := NewRegisterHandler(&mockRegisterService{})
+	r := regRequest(t, "/public/register/verify-email?client_id=c1&provider_id=p1", map[string]string{
+		"email": "jane@acme.com", "code": "12ab",
+	})
+	w := httptest.NewRecorder()
+	h.VerifyEmail(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRegisterHandler_VerifyEmail_ServiceError(t *testing.T) {
+	svc := &mockRegisterService{
+		verifyEmailFn: func(e, code, c, pr string) (*dto.RegisterResponseDTO, error) {
+			return nil, assert.AnError
+		},
+	}
+	h := NewRegisterHandler(svc)
+	r := regRequest(t, "/public/register/verify-email?client_id=c1&provider_id=p1", map[string]string{
+		"email": "jane@acme.com", "code": "123456",
+	})
+	w := httptest.NewRecorder()
+	h.VerifyEmail(w, r)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestRegisterHandler_VerifyEmail_Success(t *testing.T) {
+	svc := &mockRegisterService{
+		verifyEmailFn: func(e, code, c, pr string) (*dto.RegisterResponseDTO, error) {
+			assert.Equal(t, "jane@acme.com", e)
+			assert.Equal(t, "123456", code)
+			return &dto.RegisterResponseDTO{AccessToken: "at"}, nil
+		},
+	}
+	h := NewRegisterHandler(svc)
+	r := regRequest(t, "/public/register/verify-email?client_id=c1&provider_id=p1", map[string]string{
+		"email": "jane@acme.com", "code": "123456",
+	})
+	w := httptest.NewRecorder()
+	h.VerifyEmail(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
 // ---------------------------------------------------------------------------
 // Register (internal)
 // ---------------------------------------------------------------------------
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/metrics"
 	"github.com/maintainerd/auth/internal/middleware"
 	resp "github.com/maintainerd/auth/internal/rest/response"
 	"github.com/maintainerd/auth/internal/security"
@@ -126,6 +127,7 @@ func (h *ResetPasswordHandler) ResetPasswordPublic(w http.ResponseWriter, r *htt
 
 	// Rate limiting check to prevent abuse
 	if err := security.CheckRateLimit(token); err != nil {
+		metrics.ObserveRateLimitHit("reset_password")
 		security.LogSecurityEvent(security.SecurityEvent{
 			EventType: "reset_password_rate_limited",
 			UserID:    token,
@@ -285,6 +287,7 @@ func (h *ResetPasswordHandler) ResetPassword(w http.ResponseWriter, r *http.Requ
 
 	// Rate limiting check to prevent abuse
 	if err := security.CheckRateLimit(token); err != nil {
+		metrics.ObserveRateLimitHit("reset_password")
 		security.LogSecurityEvent(security.SecurityEvent{
 			EventType: "reset_password_rate_limited",
 			UserID:    token,
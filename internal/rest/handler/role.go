@@ -66,6 +66,9 @@ func (h *RoleHandler) Get(w http.ResponseWriter, r *http.Request) {
 		status = &v
 	}
 
+	// Parse whether to list the inheritable roles of ancestor tenants
+	includeInherited, _ := strconv.ParseBool(q.Get("include_inherited"))
+
 	// Build filter DTO with all query parameters
 	reqParams := dto.RoleFilterDTO{
 		Name:        ptr.PtrOrNil(q.Get("name")),
@@ -73,6 +76,8 @@ func (h *RoleHandler) Get(w http.ResponseWriter, r *http.Request) {
 		IsDefault:   isDefault,
 		IsSystem:    isSystem,
 		Status:      status,
+
+		IncludeInherited: includeInherited,
 		PaginationRequestDTO: dto.PaginationRequestDTO{
 			Page:      page,
 			Limit:     limit,
@@ -95,10 +100,12 @@ func (h *RoleHandler) Get(w http.ResponseWriter, r *http.Request) {
 		IsSystem:    reqParams.IsSystem,
 		Status:      reqParams.Status,
 		TenantID:    tenant.TenantID,
-		Page:        reqParams.Page,
-		Limit:       reqParams.Limit,
-		SortBy:      reqParams.SortBy,
-		SortOrder:   reqParams.SortOrder,
+
+		IncludeInherited: reqParams.IncludeInherited,
+		Page:             reqParams.Page,
+		Limit:            reqParams.Limit,
+		SortBy:           reqParams.SortBy,
+		SortOrder:        reqParams.SortOrder,
 	}
 
 	// Fetch roles from service - service filters by tenant_id
@@ -151,6 +158,7 @@ func (h *RoleHandler) GetByUUID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", middleware.ETag(role.UpdatedAt))
 	resp.Success(w, toRoleResponseDTO(*role), "Role fetched successfully")
 }
 
@@ -240,6 +248,7 @@ func (h *RoleHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", middleware.ETag(role.UpdatedAt))
 	resp.Success(w, toRoleResponseDTO(*role), "Role updated successfully")
 }
 
@@ -293,9 +302,92 @@ func (h *RoleHandler) SetStatus(w http.ResponseWriter, r *http.Request) {
 	resp.Success(w, toRoleResponseDTO(*role), "Role updated successfully")
 }
 
+// SetInheritable sets whether a role can be assigned in the tenants below the
+// tenant that owns it.
+// Tenant access is validated by middleware.
+// The service layer verifies the role belongs to the tenant before updating it.
+func (h *RoleHandler) SetInheritable(w http.ResponseWriter, r *http.Request) {
+	// Tenant is already validated by middleware - just extract from context
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	// Extract authenticated user from context (needed for audit tracking)
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+
+	// Extract and validate role UUID from URL parameter
+	roleUUID, err := uuid.Parse(chi.URLParam(r, "role_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid role UUID")
+		return
+	}
+
+	// Decode request body
+	var req struct {
+		IsInheritable *bool `json:"is_inheritable"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+	if req.IsInheritable == nil {
+		resp.Error(w, http.StatusBadRequest, "is_inheritable is required")
+		return
+	}
+
+	role, err := h.service.SetInheritableByUUID(r.Context(), roleUUID, tenant.TenantID, *req.IsInheritable, user.UserUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to update role", err)
+		return
+	}
+
+	resp.Success(w, toRoleResponseDTO(*role), "Role updated successfully")
+}
+
+// SetDefault makes a role the tenant's default role, replacing the current
+// default in a single transaction.
+// Tenant access is validated by middleware.
+func (h *RoleHandler) SetDefault(w http.ResponseWriter, r *http.Request) {
+	// Tenant is already validated by middleware - just extract from context
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	// Extract authenticated user from context (needed for audit tracking)
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+
+	// Extract and validate role UUID from URL parameter
+	roleUUID, err := uuid.Parse(chi.URLParam(r, "role_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid role UUID")
+		return
+	}
+
+	role, err := h.service.SetDefaultByUUID(r.Context(), roleUUID, tenant.TenantID, user.UserUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to set default role", err)
+		return
+	}
+
+	resp.Success(w, toRoleResponseDTO(*role), "Default role updated successfully")
+}
+
 // Delete soft-deletes a role.
 // Tenant access is validated by middleware.
 // The service layer verifies the role belongs to the tenant before deletion.
+// With ?dry_run=true the impact is reported and nothing is deleted.
 func (h *RoleHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	// Tenant is already validated by middleware - just extract from context
 	tenant := middleware.AuthFromRequest(r).Tenant
@@ -318,6 +410,22 @@ func (h *RoleHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	dryRun, err := dryRunRequested(r.URL.Query())
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid dry_run value")
+		return
+	}
+
+	if dryRun {
+		report, err := h.service.PreviewDeleteByUUID(r.Context(), roleUUID, tenant.TenantID, user.UserUUID)
+		if err != nil {
+			resp.HandleServiceError(w, r, "Failed to preview role delete", err)
+			return
+		}
+		resp.Success(w, toDeleteImpactResponseDTO(report), "Dry run completed, nothing was deleted")
+		return
+	}
+
 	// Delete role - service validates it belongs to tenant
 	role, err := h.service.DeleteByUUID(r.Context(), roleUUID, tenant.TenantID, user.UserUUID)
 	if err != nil {
@@ -536,6 +644,9 @@ func toRoleResponseDTO(r service.RoleServiceDataResult) dto.RoleResponseDTO {
 		Status:      r.Status,
 		CreatedAt:   r.CreatedAt,
 		UpdatedAt:   r.UpdatedAt,
+
+		IsInheritable: r.IsInheritable,
+		IsInherited:   r.IsInherited,
 	}
 	// Map permissions if present
 	if r.Permissions != nil {
@@ -4,8 +4,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/middleware"
 	"github.com/maintainerd/auth/internal/service"
 	"github.com/stretchr/testify/assert"
 )
@@ -74,9 +77,10 @@ func TestRoleHandler_GetByUUID_NotFound(t *testing.T) {
 }
 
 func TestRoleHandler_GetByUUID_Success(t *testing.T) {
+	updatedAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
 	svc := &mockRoleService{
 		getByUUIDFn: func(id uuid.UUID, tid int64) (*service.RoleServiceDataResult, error) {
-			return &service.RoleServiceDataResult{Name: "admin"}, nil
+			return &service.RoleServiceDataResult{Name: "admin", UpdatedAt: updatedAt}, nil
 		},
 	}
 	h := NewRoleHandler(svc)
@@ -84,6 +88,7 @@ func TestRoleHandler_GetByUUID_Success(t *testing.T) {
 	w := httptest.NewRecorder()
 	h.GetByUUID(w, r)
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, middleware.ETag(updatedAt), w.Header().Get("ETag"))
 }
 
 func TestRoleHandler_Create_NoTenant(t *testing.T) {
@@ -172,6 +177,25 @@ func TestRoleHandler_Get_WithFiltersAndRows(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestRoleHandler_Get_IncludeInherited(t *testing.T) {
+	var got bool
+	svc := &mockRoleService{
+		getFn: func(f service.RoleServiceGetFilter) (*service.RoleServiceGetResult, error) {
+			got = f.IncludeInherited
+			return &service.RoleServiceGetResult{
+				Data: []service.RoleServiceDataResult{{Name: "auditor", IsInheritable: true, IsInherited: true}},
+			}, nil
+		},
+	}
+	h := NewRoleHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/roles?page=1&limit=10&include_inherited=true", nil))
+	w := httptest.NewRecorder()
+	h.Get(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, got)
+	assert.Contains(t, w.Body.String(), `"is_inherited":true`)
+}
+
 // ── Create ────────────────────────────────────────────────────────────────────
 
 func TestRoleHandler_Create_NoUser(t *testing.T) {
@@ -289,9 +313,10 @@ func TestRoleHandler_Update_ServiceError(t *testing.T) {
 }
 
 func TestRoleHandler_Update_Success(t *testing.T) {
+	updatedAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
 	svc := &mockRoleService{
 		updateFn: func(id uuid.UUID, tid int64, n, desc string, isDef, isSys bool, s string, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
-			return &service.RoleServiceDataResult{Name: n}, nil
+			return &service.RoleServiceDataResult{Name: n, UpdatedAt: updatedAt}, nil
 		},
 	}
 	h := NewRoleHandler(svc)
@@ -301,6 +326,23 @@ func TestRoleHandler_Update_Success(t *testing.T) {
 	w := httptest.NewRecorder()
 	h.Update(w, r)
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, middleware.ETag(updatedAt), w.Header().Get("ETag"))
+}
+
+func TestRoleHandler_Update_PreconditionFailed(t *testing.T) {
+	svc := &mockRoleService{
+		updateFn: func(id uuid.UUID, tid int64, n, desc string, isDef, isSys bool, s string, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
+			return nil, apperror.NewPrecondition("role was changed by another request, reload it and try again")
+		},
+	}
+	h := NewRoleHandler(svc)
+	r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPut, "/roles/"+testResourceUUID.String(), map[string]string{
+		"name": "role1", "description": "A test description", "status": "active",
+	}), "role_uuid", testResourceUUID.String()))
+	w := httptest.NewRecorder()
+	h.Update(w, r)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	assert.Contains(t, w.Body.String(), "GEN-0412")
 }
 
 // ── SetStatus ─────────────────────────────────────────────────────────────────
@@ -371,6 +413,118 @@ func TestRoleHandler_SetStatus_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+// ── SetInheritable ────────────────────────────────────────────────────────────
+
+func TestRoleHandler_SetInheritable_NoTenant(t *testing.T) {
+	h := NewRoleHandler(&mockRoleService{})
+	r := withChiParam(jsonReq(t, http.MethodPut, "/roles/"+testResourceUUID.String()+"/inheritable", map[string]bool{"is_inheritable": true}), "role_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.SetInheritable(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRoleHandler_SetInheritable_InvalidUUID(t *testing.T) {
+	h := NewRoleHandler(&mockRoleService{})
+	r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPut, "/roles/bad/inheritable", map[string]bool{"is_inheritable": true}), "role_uuid", "bad"))
+	w := httptest.NewRecorder()
+	h.SetInheritable(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRoleHandler_SetInheritable_MissingField(t *testing.T) {
+	h := NewRoleHandler(&mockRoleService{})
+	r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPut, "/roles/"+testResourceUUID.String()+"/inheritable", map[string]any{}), "role_uuid", testResourceUUID.String()))
+	w := httptest.NewRecorder()
+	h.SetInheritable(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRoleHandler_SetInheritable_ServiceError(t *testing.T) {
+	svc := &mockRoleService{
+		setInheritableFn: func(id uuid.UUID, tid int64, inheritable bool, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
+			return nil, errNotFound
+		},
+	}
+	h := NewRoleHandler(svc)
+	r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPut, "/roles/"+testResourceUUID.String()+"/inheritable", map[string]bool{"is_inheritable": true}), "role_uuid", testResourceUUID.String()))
+	w := httptest.NewRecorder()
+	h.SetInheritable(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRoleHandler_SetInheritable_Success(t *testing.T) {
+	var got bool
+	svc := &mockRoleService{
+		setInheritableFn: func(id uuid.UUID, tid int64, inheritable bool, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
+			got = inheritable
+			return &service.RoleServiceDataResult{RoleUUID: id, IsInheritable: inheritable}, nil
+		},
+	}
+	h := NewRoleHandler(svc)
+	r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPut, "/roles/"+testResourceUUID.String()+"/inheritable", map[string]bool{"is_inheritable": true}), "role_uuid", testResourceUUID.String()))
+	w := httptest.NewRecorder()
+	h.SetInheritable(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, got)
+	assert.Contains(t, w.Body.String(), `"is_inheritable":true`)
+}
+
+// ── SetDefault ────────────────────────────────────────────────────────────────
+
+func TestRoleHandler_SetDefault_NoTenant(t *testing.T) {
+	h := NewRoleHandler(&mockRoleService{})
+	r := withChiParam(httptest.NewRequest(http.MethodPut, "/roles/"+testResourceUUID.String()+"/default", nil), "role_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.SetDefault(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRoleHandler_SetDefault_NoUser(t *testing.T) {
+	h := NewRoleHandler(&mockRoleService{})
+	r := withTenant(withChiParam(httptest.NewRequest(http.MethodPut, "/roles/"+testResourceUUID.String()+"/default", nil), "role_uuid", testResourceUUID.String()))
+	w := httptest.NewRecorder()
+	h.SetDefault(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRoleHandler_SetDefault_InvalidUUID(t *testing.T) {
+	h := NewRoleHandler(&mockRoleService{})
+	r := withTenantAndUser(withChiParam(httptest.NewRequest(http.MethodPut, "/roles/bad/default", nil), "role_uuid", "bad"))
+	w := httptest.NewRecorder()
+	h.SetDefault(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRoleHandler_SetDefault_ServiceError(t *testing.T) {
+	svc := &mockRoleService{
+		setDefaultByUUIDFn: func(id uuid.UUID, tid int64, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
+			return nil, errValidation
+		},
+	}
+	h := NewRoleHandler(svc)
+	r := withTenantAndUser(withChiParam(httptest.NewRequest(http.MethodPut, "/roles/"+testResourceUUID.String()+"/default", nil), "role_uuid", testResourceUUID.String()))
+	w := httptest.NewRecorder()
+	h.SetDefault(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRoleHandler_SetDefault_Success(t *testing.T) {
+	var gotUUID uuid.UUID
+	svc := &mockRoleService{
+		setDefaultByUUIDFn: func(id uuid.UUID, tid int64, actor uuid.UUID) (*service.RoleServiceDataResult, error) {
+			gotUUID = id
+			return &service.RoleServiceDataResult{RoleUUID: id, IsDefault: true}, nil
+		},
+	}
+	h := NewRoleHandler(svc)
+	r := withTenantAndUser(withChiParam(httptest.NewRequest(http.MethodPut, "/roles/"+testResourceUUID.String()+"/default", nil), "role_uuid", testResourceUUID.String()))
+	w := httptest.NewRecorder()
+	h.SetDefault(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, testResourceUUID, gotUUID)
+	assert.Contains(t, w.Body.String(), `"is_default":true`)
+}
+
 // ── GetPermissions ────────────────────────────────────────────────────────────
 
 func TestRoleHandler_GetPermissions_NoTenant(t *testing.T) {
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// ScopedRoleHandler manages the roles a user holds for a single client or
+// group, such as helpdesk staff who administer the members of one group.
+type ScopedRoleHandler struct {
+	scopedRoleService service.ScopedRoleService
+}
+
+func NewScopedRoleHandler(scopedRoleService service.ScopedRoleService) *ScopedRoleHandler {
+	return &ScopedRoleHandler{
+		scopedRoleService: scopedRoleService,
+	}
+}
+
+// List returns the user's scoped role assignments.
+//
+// GET /users/{user_uuid}/scoped-roles
+func (h *ScopedRoleHandler) List(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid user UUID")
+		return
+	}
+
+	assignments, err := h.scopedRoleService.List(r.Context(), userUUID, tenant.TenantID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to retrieve scoped roles", err)
+		return
+	}
+
+	rows := make([]dto.ScopedRoleResponseDTO, len(assignments))
+	for i, a := range assignments {
+		rows[i] = toScopedRoleResponseDTO(a)
+	}
+
+	resp.Success(w, rows, "Scoped roles retrieved successfully")
+}
+
+// Assign assigns a role to the user for one client or group.
+//
+// POST /users/{user_uuid}/scoped-roles
+func (h *ScopedRoleHandler) Assign(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid user UUID")
+		return
+	}
+
+	var req dto.ScopedRoleAssignRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	// Both UUIDs were checked by Validate
+	roleUUID, _ := uuid.Parse(req.RoleUUID)
+	resourceUUID, _ := uuid.Parse(req.ResourceUUID)
+
+	assignment, err := h.scopedRoleService.Assign(r.Context(), userUUID, tenant.TenantID, roleUUID, req.ResourceType, resourceUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to assign scoped role", err)
+		return
+	}
+
+	resp.Success(w, toScopedRoleResponseDTO(*assignment), "Scoped role assigned successfully")
+}
+
+// Remove removes a scoped role assignment from the user.
+//
+// DELETE /users/{user_uuid}/scoped-roles/{scoped_role_uuid}
+func (h *ScopedRoleHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid user UUID")
+		return
+	}
+
+	scopedRoleUUID, err := uuid.Parse(chi.URLParam(r, "scoped_role_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid scoped role UUID")
+		return
+	}
+
+	assignment, err := h.scopedRoleService.Remove(r.Context(), userUUID, tenant.TenantID, scopedRoleUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to remove scoped role", err)
+		return
+	}
+
+	resp.Success(w, toScopedRoleResponseDTO(*assignment), "Scoped role removed successfully")
+}
+
+func toScopedRoleResponseDTO(a service.ScopedRoleServiceDataResult) dto.ScopedRoleResponseDTO {
+	return dto.ScopedRoleResponseDTO{
+		ScopedRoleUUID: a.ScopedRoleUUID,
+		Role:           toRoleResponseDTO(a.Role),
+		ResourceType:   a.ResourceType,
+		ResourceUUID:   a.ResourceUUID,
+		ResourceName:   a.ResourceName,
+		CreatedAt:      a.CreatedAt,
+	}
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopedRoleHandler_List(t *testing.T) {
+	t.Run("no tenant", func(t *testing.T) {
+		h := NewScopedRoleHandler(&mockScopedRoleService{})
+		w := httptest.NewRecorder()
+		h.List(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid user uuid", func(t *testing.T) {
+		h := NewScopedRoleHandler(&mockScopedRoleService{})
+		w := httptest.NewRecorder()
+		h.List(w, withChiParam(withTenant(httptest.NewRequest(http.MethodGet, "/", nil)), "user_uuid", "bad"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewScopedRoleHandler(&mockScopedRoleService{
+			listFn: func(userUUID uuid.UUID, tid int64) ([]service.ScopedRoleServiceDataResult, error) {
+				assert.Equal(t, testResourceUUID, userUUID)
+				assert.Equal(t, tenantID, tid)
+				return []service.ScopedRoleServiceDataResult{{
+					Role:         service.RoleServiceDataResult{Name: "helpdesk"},
+					ResourceType: "group",
+					ResourceName: "support",
+				}}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.List(w, withChiParam(withTenant(httptest.NewRequest(http.MethodGet, "/", nil)), "user_uuid", testResourceUUID.String()))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "helpdesk")
+		assert.Contains(t, w.Body.String(), `"resource_type":"group"`)
+	})
+}
+
+func TestScopedRoleHandler_Assign(t *testing.T) {
+	roleUUID, clientUUID := uuid.New(), uuid.New()
+	body := dto.ScopedRoleAssignRequestDTO{
+		RoleUUID:     roleUUID.String(),
+		ResourceType: "client",
+		ResourceUUID: clientUUID.String(),
+	}
+
+	t.Run("invalid json", func(t *testing.T) {
+		h := NewScopedRoleHandler(&mockScopedRoleService{})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenant(badJSONReq(t, http.MethodPost, "/")), "user_uuid", testResourceUUID.String())
+		h.Assign(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		h := NewScopedRoleHandler(&mockScopedRoleService{})
+		invalid := body
+		invalid.ResourceType = "tenant"
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenant(jsonReq(t, http.MethodPost, "/", invalid)), "user_uuid", testResourceUUID.String())
+		h.Assign(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		h := NewScopedRoleHandler(&mockScopedRoleService{
+			assignFn: func(uuid.UUID, int64, uuid.UUID, string, uuid.UUID) (*service.ScopedRoleServiceDataResult, error) {
+				return nil, errNotFound
+			},
+		})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenant(jsonReq(t, http.MethodPost, "/", body)), "user_uuid", testResourceUUID.String())
+		h.Assign(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewScopedRoleHandler(&mockScopedRoleService{
+			assignFn: func(userUUID uuid.UUID, tid int64, gotRole uuid.UUID, resourceType string, resourceUUID uuid.UUID) (*service.ScopedRoleServiceDataResult, error) {
+				assert.Equal(t, testResourceUUID, userUUID)
+				assert.Equal(t, roleUUID, gotRole)
+				assert.Equal(t, "client", resourceType)
+				assert.Equal(t, clientUUID, resourceUUID)
+				return &service.ScopedRoleServiceDataResult{ResourceType: resourceType, ResourceUUID: resourceUUID}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		r := withChiParam(withTenant(jsonReq(t, http.MethodPost, "/", body)), "user_uuid", testResourceUUID.String())
+		h.Assign(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), clientUUID.String())
+	})
+}
+
+func TestScopedRoleHandler_Remove(t *testing.T) {
+	t.Run("invalid scoped role uuid", func(t *testing.T) {
+		h := NewScopedRoleHandler(&mockScopedRoleService{})
+		w := httptest.NewRecorder()
+		r := withChiParam(withChiParam(withTenant(httptest.NewRequest(http.MethodDelete, "/", nil)),
+			"user_uuid", testResourceUUID.String()), "scoped_role_uuid", "bad")
+		h.Remove(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		scopedRoleUUID := uuid.New()
+		h := NewScopedRoleHandler(&mockScopedRoleService{
+			removeFn: func(_ uuid.UUID, _ int64, got uuid.UUID) (*service.ScopedRoleServiceDataResult, error) {
+				assert.Equal(t, scopedRoleUUID, got)
+				return &service.ScopedRoleServiceDataResult{ScopedRoleUUID: got}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		r := withChiParam(withChiParam(withTenant(httptest.NewRequest(http.MethodDelete, "/", nil)),
+			"user_uuid", testResourceUUID.String()), "scoped_role_uuid", scopedRoleUUID.String())
+		h.Remove(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
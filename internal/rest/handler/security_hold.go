@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// SecurityHoldHandler handles HTTP requests for putting accounts on security
+// hold.
+type SecurityHoldHandler struct {
+	securityHoldService service.SecurityHoldService
+}
+
+// NewSecurityHoldHandler creates a new SecurityHoldHandler.
+func NewSecurityHoldHandler(securityHoldService service.SecurityHoldService) *SecurityHoldHandler {
+	return &SecurityHoldHandler{securityHoldService: securityHoldService}
+}
+
+// Hold puts a user on security hold.
+//
+// POST /users/{user_uuid}/security-hold
+//
+// Signs the user out everywhere and blocks sign in. The user is notified and
+// can recover the account with a recovery code; otherwise an admin clears
+// the hold with POST /users/{user_uuid}/enable.
+func (h *SecurityHoldHandler) Hold(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.Tenant == nil || auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid user UUID")
+		return
+	}
+
+	var req dto.SecurityHoldRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	user, err := h.securityHoldService.Hold(r.Context(), userUUID, auth.Tenant.TenantID, auth.User.UserUUID, req.Reason)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to put user on security hold", err)
+		return
+	}
+
+	resp.Success(w, toUserResponseDTO(*user), "User put on security hold")
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityHoldHandler_Hold(t *testing.T) {
+	newReq := func(id string, body any) *http.Request {
+		return withChiParam(jsonReq(t, http.MethodPost, "/users/"+id+"/security-hold", body), "user_uuid", id)
+	}
+	reason := map[string]any{"reason": "credentials found in a paste"}
+
+	w := httptest.NewRecorder()
+	NewSecurityHoldHandler(&mockSecurityHoldService{}).Hold(w, newReq(testResourceUUID.String(), reason))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	NewSecurityHoldHandler(&mockSecurityHoldService{}).Hold(w, withTenantAndUser(newReq("bad", reason)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	NewSecurityHoldHandler(&mockSecurityHoldService{}).Hold(w, withTenantAndUser(newReq(testResourceUUID.String(), map[string]any{})))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var gotUser, gotActor uuid.UUID
+	var gotReason string
+	svc := &mockSecurityHoldService{
+		holdFn: func(id uuid.UUID, tid int64, actor uuid.UUID, reason string) (*service.UserServiceDataResult, error) {
+			gotUser, gotActor, gotReason = id, actor, reason
+			return &service.UserServiceDataResult{Status: model.StatusSecurityHold}, nil
+		},
+	}
+	w = httptest.NewRecorder()
+	NewSecurityHoldHandler(svc).Hold(w, withTenantAndUser(newReq(testResourceUUID.String(), reason)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"security_hold"`)
+	assert.Equal(t, testResourceUUID, gotUser)
+	assert.Equal(t, testUserUUID, gotActor)
+	assert.Equal(t, "credentials found in a paste", gotReason)
+
+	svc.holdFn = func(uuid.UUID, int64, uuid.UUID, string) (*service.UserServiceDataResult, error) {
+		return nil, apperror.NewValidation("only an active account can be put on security hold")
+	}
+	w = httptest.NewRecorder()
+	NewSecurityHoldHandler(svc).Hold(w, withTenantAndUser(newReq(testResourceUUID.String(), reason)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -173,6 +173,10 @@ func (h *SecuritySettingHandler) GetLockoutConfig(w http.ResponseWriter, r *http
 func (h *SecuritySettingHandler) UpdateMFAConfig(w http.ResponseWriter, r *http.Request) {
 	// Get user from context (needed for audit tracking)
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	// Get tenant from context (middleware already validated access)
 	tenant := middleware.AuthFromRequest(r).Tenant
@@ -235,6 +239,10 @@ func (h *SecuritySettingHandler) UpdateMFAConfig(w http.ResponseWriter, r *http.
 func (h *SecuritySettingHandler) UpdatePasswordConfig(w http.ResponseWriter, r *http.Request) {
 	// Get user from context (needed for audit tracking)
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	// Get tenant from context (middleware already validated access)
 	tenant := middleware.AuthFromRequest(r).Tenant
@@ -297,6 +305,10 @@ func (h *SecuritySettingHandler) UpdatePasswordConfig(w http.ResponseWriter, r *
 func (h *SecuritySettingHandler) UpdateSessionConfig(w http.ResponseWriter, r *http.Request) {
 	// Get user from context (needed for audit tracking)
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	// Get tenant from context (middleware already validated access)
 	tenant := middleware.AuthFromRequest(r).Tenant
@@ -359,6 +371,10 @@ func (h *SecuritySettingHandler) UpdateSessionConfig(w http.ResponseWriter, r *h
 func (h *SecuritySettingHandler) UpdateThreatConfig(w http.ResponseWriter, r *http.Request) {
 	// Get user from context (needed for audit tracking)
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	// Get tenant from context (middleware already validated access)
 	tenant := middleware.AuthFromRequest(r).Tenant
@@ -421,6 +437,10 @@ func (h *SecuritySettingHandler) UpdateThreatConfig(w http.ResponseWriter, r *ht
 func (h *SecuritySettingHandler) UpdateLockoutConfig(w http.ResponseWriter, r *http.Request) {
 	// Get user from context (needed for audit tracking)
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	// Get tenant from context (middleware already validated access)
 	tenant := middleware.AuthFromRequest(r).Tenant
@@ -519,6 +539,10 @@ func (h *SecuritySettingHandler) GetTokenConfig(w http.ResponseWriter, r *http.R
 // PUT /security-settings/registration
 func (h *SecuritySettingHandler) UpdateRegistrationConfig(w http.ResponseWriter, r *http.Request) {
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 	tenant := middleware.AuthFromRequest(r).Tenant
 	if tenant == nil {
 		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
@@ -567,6 +591,10 @@ func (h *SecuritySettingHandler) UpdateRegistrationConfig(w http.ResponseWriter,
 // PUT /security-settings/token
 func (h *SecuritySettingHandler) UpdateTokenConfig(w http.ResponseWriter, r *http.Request) {
 	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 	tenant := middleware.AuthFromRequest(r).Tenant
 	if tenant == nil {
 		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
@@ -112,8 +112,7 @@ func TestSecuritySettingHandler_UpdateMFAConfig_NoTenant(t *testing.T) {
 
 func TestSecuritySettingHandler_UpdateMFAConfig_BadJSON(t *testing.T) {
 	h := NewSecuritySettingHandler(&mockSecuritySettingService{})
-	r := withUser(badJSONReq(t, http.MethodPut, "/security-settings/general"))
-	r = withTenant(r)
+	r := withTenantAndUser(badJSONReq(t, http.MethodPut, "/security-settings/general"))
 	w := httptest.NewRecorder()
 	h.UpdateMFAConfig(w, r)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -269,7 +268,7 @@ func TestSecuritySettingHandler_UpdatePasswordConfig_NoTenant(t *testing.T) {
 
 func TestSecuritySettingHandler_UpdatePasswordConfig_BadJSON(t *testing.T) {
 	h := NewSecuritySettingHandler(&mockSecuritySettingService{})
-	r := withTenant(withUser(badJSONReq(t, http.MethodPut, "/security-settings/password")))
+	r := withTenantAndUser(badJSONReq(t, http.MethodPut, "/security-settings/password"))
 	w := httptest.NewRecorder()
 	h.UpdatePasswordConfig(w, r)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -337,7 +336,7 @@ func TestSecuritySettingHandler_UpdateSessionConfig_NoTenant(t *testing.T) {
 
 func TestSecuritySettingHandler_UpdateSessionConfig_BadJSON(t *testing.T) {
 	h := NewSecuritySettingHandler(&mockSecuritySettingService{})
-	r := withTenant(withUser(badJSONReq(t, http.MethodPut, "/security-settings/session")))
+	r := withTenantAndUser(badJSONReq(t, http.MethodPut, "/security-settings/session"))
 	w := httptest.NewRecorder()
 	h.UpdateSessionConfig(w, r)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -405,7 +404,7 @@ func TestSecuritySettingHandler_UpdateThreatConfig_NoTenant(t *testing.T) {
 
 func TestSecuritySettingHandler_UpdateThreatConfig_BadJSON(t *testing.T) {
 	h := NewSecuritySettingHandler(&mockSecuritySettingService{})
-	r := withTenant(withUser(badJSONReq(t, http.MethodPut, "/security-settings/threat")))
+	r := withTenantAndUser(badJSONReq(t, http.MethodPut, "/security-settings/threat"))
 	w := httptest.NewRecorder()
 	h.UpdateThreatConfig(w, r)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -473,7 +472,7 @@ func TestSecuritySettingHandler_UpdateLockoutConfig_NoTenant(t *testing.T) {
 
 func TestSecuritySettingHandler_UpdateLockoutConfig_BadJSON(t *testing.T) {
 	h := NewSecuritySettingHandler(&mockSecuritySettingService{})
-	r := withTenant(withUser(badJSONReq(t, http.MethodPut, "/security-settings/ip")))
+	r := withTenantAndUser(badJSONReq(t, http.MethodPut, "/security-settings/ip"))
 	w := httptest.NewRecorder()
 	h.UpdateLockoutConfig(w, r)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// SelfServiceTenantHandler handles HTTP requests for tenants created by
+// signed-in users themselves.
+type SelfServiceTenantHandler struct {
+	selfServiceTenantService service.SelfServiceTenantService
+}
+
+// NewSelfServiceTenantHandler creates a new SelfServiceTenantHandler.
+func NewSelfServiceTenantHandler(selfServiceTenantService service.SelfServiceTenantService) *SelfServiceTenantHandler {
+	return &SelfServiceTenantHandler{selfServiceTenantService: selfServiceTenantService}
+}
+
+// Create creates and provisions a tenant owned by the caller.
+//
+// POST /tenants/self-service
+func (h *SelfServiceTenantHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req dto.TenantSelfServiceRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	tenant, err := h.selfServiceTenantService.Create(r.Context(), user.UserID, req.Name, req.DisplayName, req.Description)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to create tenant", err)
+		return
+	}
+
+	resp.Created(w, toTenantResponseDTO(*tenant), "Tenant created successfully")
+}
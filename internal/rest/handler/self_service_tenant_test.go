@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfServiceTenantHandler_Create_Unauthorized(t *testing.T) {
+	h := NewSelfServiceTenantHandler(&mockSelfServiceTenantService{})
+	w := httptest.NewRecorder()
+	h.Create(w, jsonReq(t, http.MethodPost, "/tenants/self-service", map[string]any{"name": "acme"}))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestSelfServiceTenantHandler_Create_BadJSON(t *testing.T) {
+	h := NewSelfServiceTenantHandler(&mockSelfServiceTenantService{})
+	w := httptest.NewRecorder()
+	h.Create(w, withUser(badJSONReq(t, http.MethodPost, "/tenants/self-service")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSelfServiceTenantHandler_Create_ValidationError(t *testing.T) {
+	h := NewSelfServiceTenantHandler(&mockSelfServiceTenantService{})
+	w := httptest.NewRecorder()
+	h.Create(w, withUser(jsonReq(t, http.MethodPost, "/tenants/self-service", map[string]any{"name": "Acme Corp"})))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSelfServiceTenantHandler_Create_LimitReached(t *testing.T) {
+	svc := &mockSelfServiceTenantService{
+		createFn: func(int64, string, string, string) (*service.TenantServiceDataResult, error) {
+			return nil, errForbidden
+		},
+	}
+	h := NewSelfServiceTenantHandler(svc)
+	w := httptest.NewRecorder()
+	h.Create(w, withUser(jsonReq(t, http.MethodPost, "/tenants/self-service", map[string]any{"name": "acme"})))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestSelfServiceTenantHandler_Create_Success(t *testing.T) {
+	svc := &mockSelfServiceTenantService{
+		createFn: func(_ int64, name, displayName, _ string) (*service.TenantServiceDataResult, error) {
+			assert.Equal(t, "acme", name)
+			assert.Equal(t, "Acme", displayName)
+			return &service.TenantServiceDataResult{TenantUUID: testTenantUUID, Name: name, DisplayName: displayName}, nil
+		},
+	}
+	h := NewSelfServiceTenantHandler(svc)
+	w := httptest.NewRecorder()
+	h.Create(w, withUser(jsonReq(t, http.MethodPost, "/tenants/self-service", map[string]any{"name": "acme", "display_name": "Acme"})))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), testTenantUUID.String())
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -60,10 +59,8 @@ func (h *ServiceHandler) Get(w http.ResponseWriter, r *http.Request) {
 			isSystem = &parsed
 		}
 	}
-	if v := q.Get("status"); v != "" {
-		// Parse comma-separated status values
-		status = strings.Split(strings.ReplaceAll(v, " ", ""), ",")
-	}
+	// Parse status (comma-separated or repeated values)
+	status = queryValues(q, "status")
 
 	// Build request DTO for validation
 	reqParams := dto.ServiceFilterDTO{
@@ -402,6 +399,41 @@ func (h *ServiceHandler) AssignPolicy(w http.ResponseWriter, r *http.Request) {
 	resp.Success(w, nil, "Policy assigned to service successfully")
 }
 
+// GetPolicies lists the tenant's policies assigned to a service.
+//
+// GET /services/{service_uuid}/policies
+//
+// Returns the policies attached to the service. A service shared between
+// tenants only shows the caller's own policies.
+func (h *ServiceHandler) GetPolicies(w http.ResponseWriter, r *http.Request) {
+	// Get tenant from context (middleware already validated access)
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	// Parse and validate service UUID from URL parameter
+	serviceUUID, err := uuid.Parse(chi.URLParam(r, "service_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid service UUID")
+		return
+	}
+
+	policies, err := h.service.GetPolicies(r.Context(), serviceUUID, tenant.TenantID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to fetch service policies", err)
+		return
+	}
+
+	rows := make([]dto.PolicyResponseDTO, len(policies))
+	for i, policy := range policies {
+		rows[i] = toPolicyResponseDTO(policy)
+	}
+
+	resp.Success(w, rows, "Service policies fetched successfully")
+}
+
 // RemovePolicy removes a policy from a service for the tenant.
 //
 // DELETE /services/{service_uuid}/policies/{policy_uuid}
@@ -439,3 +439,49 @@ func TestServiceHandler_RemovePolicy_Success(t *testing.T) {
 	h.RemovePolicy(w, r)
 	assert.Equal(t, http.StatusOK, w.Code)
 }
+
+func TestServiceHandler_GetPolicies_NoTenant(t *testing.T) {
+	h := NewServiceHandler(&mockServiceService{})
+	r := withChiParam(httptest.NewRequest(http.MethodGet, "/", nil), "service_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.GetPolicies(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestServiceHandler_GetPolicies_InvalidUUID(t *testing.T) {
+	h := NewServiceHandler(&mockServiceService{})
+	r := withTenant(withChiParam(httptest.NewRequest(http.MethodGet, "/", nil), "service_uuid", "bad"))
+	w := httptest.NewRecorder()
+	h.GetPolicies(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestServiceHandler_GetPolicies_ServiceError(t *testing.T) {
+	svc := &mockServiceService{
+		getPoliciesFn: func(uuid.UUID, int64) ([]service.PolicyServiceDataResult, error) {
+			return nil, assert.AnError
+		},
+	}
+	h := NewServiceHandler(svc)
+	r := withTenant(withChiParam(httptest.NewRequest(http.MethodGet, "/", nil), "service_uuid", testResourceUUID.String()))
+	w := httptest.NewRecorder()
+	h.GetPolicies(w, r)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestServiceHandler_GetPolicies_Success(t *testing.T) {
+	policyUUID := uuid.New()
+	svc := &mockServiceService{
+		getPoliciesFn: func(svcID uuid.UUID, _ int64) ([]service.PolicyServiceDataResult, error) {
+			assert.Equal(t, testResourceUUID, svcID)
+			return []service.PolicyServiceDataResult{{PolicyUUID: policyUUID, Name: "billing-read"}}, nil
+		},
+	}
+	h := NewServiceHandler(svc)
+	r := withTenant(withChiParam(httptest.NewRequest(http.MethodGet, "/", nil), "service_uuid", testResourceUUID.String()))
+	w := httptest.NewRecorder()
+	h.GetPolicies(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), policyUUID.String())
+	assert.Contains(t, w.Body.String(), "billing-read")
+}
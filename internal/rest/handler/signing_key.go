@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// SigningKeyHandler handles HTTP requests for the JWT signing keys.
+type SigningKeyHandler struct {
+	signingKeyService service.SigningKeyService
+}
+
+// NewSigningKeyHandler creates a new SigningKeyHandler.
+func NewSigningKeyHandler(signingKeyService service.SigningKeyService) *SigningKeyHandler {
+	return &SigningKeyHandler{signingKeyService: signingKeyService}
+}
+
+// List retrieves every signing key, newest first, without key material.
+//
+// GET /admin/signing-keys
+func (h *SigningKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.signingKeyService.List(r.Context())
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to retrieve signing keys", err)
+		return
+	}
+
+	resp.Success(w, keys, "Signing keys retrieved successfully")
+}
+
+// Rotate generates a new active signing key. The key it replaces keeps
+// verifying tokens until it is retired.
+//
+// POST /admin/signing-keys/rotate
+func (h *SigningKeyHandler) Rotate(w http.ResponseWriter, r *http.Request) {
+	key, err := h.signingKeyService.Rotate(r.Context())
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to rotate signing key", err)
+		return
+	}
+
+	resp.Created(w, key, "Signing key rotated successfully")
+}
+
+// Retire stops a previous signing key from verifying tokens.
+//
+// POST /admin/signing-keys/{signing_key_uuid}/retire
+func (h *SigningKeyHandler) Retire(w http.ResponseWriter, r *http.Request) {
+	signingKeyUUID, err := uuid.Parse(chi.URLParam(r, "signing_key_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid signing key UUID")
+		return
+	}
+
+	key, err := h.signingKeyService.Retire(r.Context(), signingKeyUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to retire signing key", err)
+		return
+	}
+
+	resp.Success(w, key, "Signing key retired successfully")
+}
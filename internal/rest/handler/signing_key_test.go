@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningKeyHandler_List(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		h := NewSigningKeyHandler(&mockSigningKeyService{
+			listFn: func() ([]dto.SigningKeyResponseDTO, error) {
+				return []dto.SigningKeyResponseDTO{{KeyID: "key-2", Status: "active"}, {KeyID: "key-1", Status: "previous"}}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.List(w, httptest.NewRequest(http.MethodGet, "/admin/signing-keys", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var body struct {
+			Data []dto.SigningKeyResponseDTO `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Len(t, body.Data, 2)
+		assert.Equal(t, "key-2", body.Data[0].KeyID)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewSigningKeyHandler(&mockSigningKeyService{
+			listFn: func() ([]dto.SigningKeyResponseDTO, error) { return nil, errors.New("db down") },
+		})
+		w := httptest.NewRecorder()
+		h.List(w, httptest.NewRequest(http.MethodGet, "/admin/signing-keys", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestSigningKeyHandler_Rotate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		h := NewSigningKeyHandler(&mockSigningKeyService{
+			rotateFn: func() (*dto.SigningKeyResponseDTO, error) {
+				return &dto.SigningKeyResponseDTO{KeyID: "key-3", Status: "active"}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Rotate(w, httptest.NewRequest(http.MethodPost, "/admin/signing-keys/rotate", nil))
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"kid":"key-3"`)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewSigningKeyHandler(&mockSigningKeyService{
+			rotateFn: func() (*dto.SigningKeyResponseDTO, error) { return nil, errors.New("db down") },
+		})
+		w := httptest.NewRecorder()
+		h.Rotate(w, httptest.NewRequest(http.MethodPost, "/admin/signing-keys/rotate", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestSigningKeyHandler_Retire(t *testing.T) {
+	keyUUID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var got uuid.UUID
+		h := NewSigningKeyHandler(&mockSigningKeyService{
+			retireFn: func(id uuid.UUID) (*dto.SigningKeyResponseDTO, error) {
+				got = id
+				return &dto.SigningKeyResponseDTO{KeyID: "key-1", Status: "retired"}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		r := withChiParam(httptest.NewRequest(http.MethodPost, "/admin/signing-keys/"+keyUUID.String()+"/retire", nil), "signing_key_uuid", keyUUID.String())
+		h.Retire(w, r)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, keyUUID, got)
+	})
+
+	t.Run("invalid UUID", func(t *testing.T) {
+		h := NewSigningKeyHandler(&mockSigningKeyService{})
+		w := httptest.NewRecorder()
+		r := withChiParam(httptest.NewRequest(http.MethodPost, "/admin/signing-keys/bad/retire", nil), "signing_key_uuid", "bad")
+		h.Retire(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("active key", func(t *testing.T) {
+		h := NewSigningKeyHandler(&mockSigningKeyService{
+			retireFn: func(_ uuid.UUID) (*dto.SigningKeyResponseDTO, error) {
+				return nil, apperror.NewConflict("the active signing key cannot be retired; rotate it first")
+			},
+		})
+		w := httptest.NewRecorder()
+		r := withChiParam(httptest.NewRequest(http.MethodPost, "/admin/signing-keys/"+keyUUID.String()+"/retire", nil), "signing_key_uuid", keyUUID.String())
+		h.Retire(w, r)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
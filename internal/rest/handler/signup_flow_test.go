@@ -63,6 +63,21 @@ func TestSignupFlowHandler_GetAll(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 
+	t.Run("comma-separated and repeated statuses are combined", func(t *testing.T) {
+		var got []string
+		svc := &mockSignupFlowService{
+			getAllFn: func(tid int64, name, id *string, status []string, clientUUID *uuid.UUID, pg, lim int, sb, so string) (*service.SignupFlowServiceListResult, error) {
+				got = status
+				return &service.SignupFlowServiceListResult{}, nil
+			},
+		}
+		r := withTenant(jsonReq(t, http.MethodGet, "/signup-flows?page=1&limit=10&status=active,inactive&status=active", nil))
+		w := httptest.NewRecorder()
+		NewSignupFlowHandler(svc).GetAll(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"active", "inactive", "active"}, got)
+	})
+
 	t.Run("service error returns 500", func(t *testing.T) {
 		svc := &mockSignupFlowService{
 			getAllFn: func(tid int64, name, id *string, status []string, clientUUID *uuid.UUID, pg, lim int, sb, so string) (*service.SignupFlowServiceListResult, error) {
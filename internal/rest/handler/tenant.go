@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -51,11 +50,8 @@ func (h *TenantHandler) Get(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Parse status array
-	var status []string
-	if v := q.Get("status"); v != "" {
-		status = strings.Split(v, ",")
-	}
+	// Parse status array (comma-separated or repeated values)
+	status := queryValues(q, "status")
 
 	// Build request DTO
 	reqParams := dto.TenantFilterDTO{
@@ -94,6 +90,12 @@ func (h *TenantHandler) Get(w http.ResponseWriter, r *http.Request) {
 		SortOrder:   reqParams.SortOrder,
 	}
 
+	// Only list the tenants the caller may access and the tenants below them
+	if ids, all := middleware.AccessibleTenantIDs(middleware.AuthFromRequest(r)); !all {
+		tenantFilter.TenantIDs = ids
+		tenantFilter.IncludeDescendants = true
+	}
+
 	// Fetch Tenants
 	result, err := h.tenantService.Get(r.Context(), tenantFilter)
 	if err != nil {
@@ -135,6 +137,7 @@ func (h *TenantHandler) GetByUUID(w http.ResponseWriter, r *http.Request) {
 
 	dtoRes := toTenantResponseDTO(*tenant)
 
+	w.Header().Set("ETag", middleware.ETag(tenant.UpdatedAt))
 	resp.Success(w, dtoRes, "Tenant fetched successfully")
 }
 
@@ -183,7 +186,13 @@ func (h *TenantHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tenant, err := h.tenantService.Create(r.Context(), req.Name, req.DisplayName, req.Description, req.Status, req.IsPublic)
+	// A child tenant can only be created under a tenant the caller may access
+	parentTenantUUID, ok := h.parentTenantFromRequest(w, r, req.ParentTenantUUID)
+	if !ok {
+		return
+	}
+
+	tenant, err := h.tenantService.Create(r.Context(), req.Name, req.DisplayName, req.Description, req.Status, req.IsPublic, parentTenantUUID)
 	if err != nil {
 		resp.HandleServiceError(w, r, "Failed to create tenant", err)
 		return
@@ -238,9 +247,134 @@ func (h *TenantHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	dtoRes := toTenantResponseDTO(*tenant)
 
+	w.Header().Set("ETag", middleware.ETag(tenant.UpdatedAt))
 	resp.Success(w, dtoRes, "Tenant updated successfully")
 }
 
+// SetParent moves a tenant, with the tenants below it, under another tenant
+// or to the top level. The caller must have access to the new parent.
+func (h *TenantHandler) SetParent(w http.ResponseWriter, r *http.Request) {
+	tenantUUID, err := uuid.Parse(chi.URLParam(r, "tenant_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid tenant UUID")
+		return
+	}
+
+	var req dto.TenantSetParentRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	parentTenantUUID, ok := h.parentTenantFromRequest(w, r, req.ParentTenantUUID)
+	if !ok {
+		return
+	}
+
+	tenant, err := h.tenantService.SetParentByUUID(r.Context(), tenantUUID, parentTenantUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to update tenant parent", err)
+		return
+	}
+
+	dtoRes := toTenantResponseDTO(*tenant)
+
+	resp.Success(w, dtoRes, "Tenant parent updated successfully")
+}
+
+// parentTenantFromRequest parses the parent tenant of a create or move request
+// and checks that the caller may access it. It writes the error response and
+// returns false when the request cannot go on.
+func (h *TenantHandler) parentTenantFromRequest(w http.ResponseWriter, r *http.Request, raw *string) (*uuid.UUID, bool) {
+	if raw == nil {
+		return nil, true
+	}
+
+	parentTenantUUID, err := uuid.Parse(*raw)
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid parent tenant UUID")
+		return nil, false
+	}
+
+	allowed, err := middleware.CanAccessTenantTree(r.Context(), h.tenantService, middleware.AuthFromRequest(r), parentTenantUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to verify parent tenant access", err)
+		return nil, false
+	}
+	if !allowed {
+		resp.Error(w, http.StatusForbidden, "Access denied", "You do not have access to the parent tenant")
+		return nil, false
+	}
+	return &parentTenantUUID, true
+}
+
+// GetChildren lists the direct children of a tenant with pagination
+func (h *TenantHandler) GetChildren(w http.ResponseWriter, r *http.Request) {
+	tenantUUID, err := uuid.Parse(chi.URLParam(r, "tenant_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid tenant UUID")
+		return
+	}
+
+	// Parse query parameters
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+
+	reqParams := dto.TenantFilterDTO{
+		PaginationRequestDTO: dto.PaginationRequestDTO{
+			Page:      page,
+			Limit:     limit,
+			SortBy:    q.Get("sort_by"),
+			SortOrder: q.Get("sort_order"),
+		},
+	}
+
+	if err := reqParams.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	// Get tenant to retrieve tenant_id
+	tenant, err := h.tenantService.GetByUUID(r.Context(), tenantUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Tenant not found", err)
+		return
+	}
+
+	result, err := h.tenantService.Get(r.Context(), service.TenantServiceGetFilter{
+		ParentTenantID: &tenant.TenantID,
+		Page:           reqParams.Page,
+		Limit:          reqParams.Limit,
+		SortBy:         reqParams.SortBy,
+		SortOrder:      reqParams.SortOrder,
+	})
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to fetch child tenants", err)
+		return
+	}
+
+	rows := make([]dto.TenantResponseDTO, len(result.Data))
+	for i, r := range result.Data {
+		rows[i] = toTenantResponseDTO(r)
+	}
+
+	response := dto.PaginatedResponseDTO[dto.TenantResponseDTO]{
+		Rows:       rows,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}
+
+	resp.Success(w, response, "Child tenants fetched successfully")
+}
+
 // Set Tenant status
 func (h *TenantHandler) SetStatus(w http.ResponseWriter, r *http.Request) {
 	tenantUUID, err := uuid.Parse(chi.URLParam(r, "tenant_uuid"))
@@ -301,6 +435,12 @@ func (h *TenantHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	dryRun, err := dryRunRequested(r.URL.Query())
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid dry_run value")
+		return
+	}
+
 	// Check if user is a member of this tenant
 	isMember, err := h.tenantMemberService.IsUserInTenant(r.Context(), user.UserID, tenantUUID)
 	if err != nil {
@@ -325,6 +465,16 @@ func (h *TenantHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if dryRun {
+		report, err := h.tenantService.PreviewDeleteByUUID(r.Context(), tenantUUID)
+		if err != nil {
+			resp.HandleServiceError(w, r, "Failed to preview tenant delete", err)
+			return
+		}
+		resp.Success(w, toDeleteImpactResponseDTO(report), "Dry run completed, nothing was deleted")
+		return
+	}
+
 	deletedTenant, err := h.tenantService.DeleteByUUID(r.Context(), tenantUUID)
 	if err != nil {
 		resp.HandleServiceError(w, r, "Failed to delete tenant", err)
@@ -438,6 +588,12 @@ func (h *TenantHandler) AddMember(w http.ResponseWriter, r *http.Request) {
 
 // UpdateMemberRole updates a member's role in a tenant
 func (h *TenantHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	tenantUUID, err := uuid.Parse(chi.URLParam(r, "tenant_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid tenant UUID")
+		return
+	}
+
 	tenantMemberUUIDStr := chi.URLParam(r, "tenant_member_uuid")
 	if tenantMemberUUIDStr == "" {
 		resp.Error(w, http.StatusBadRequest, "Invalid tenant member UUID", "UUID parameter is required")
@@ -461,7 +617,14 @@ func (h *TenantHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	member, err := h.tenantMemberService.UpdateRole(r.Context(), tenantMemberUUID, req.Role)
+	// Get tenant to retrieve tenant_id
+	tenant, err := h.tenantService.GetByUUID(r.Context(), tenantUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Tenant not found", err)
+		return
+	}
+
+	member, err := h.tenantMemberService.UpdateRole(r.Context(), tenant.TenantID, tenantMemberUUID, req.Role)
 	if err != nil {
 		resp.HandleServiceError(w, r, "Failed to update member role", err)
 		return
@@ -473,6 +636,12 @@ func (h *TenantHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request)
 
 // RemoveMember removes a member from a tenant
 func (h *TenantHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	tenantUUID, err := uuid.Parse(chi.URLParam(r, "tenant_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid tenant UUID")
+		return
+	}
+
 	tenantMemberUUIDStr := chi.URLParam(r, "tenant_member_uuid")
 	if tenantMemberUUIDStr == "" {
 		resp.Error(w, http.StatusBadRequest, "Invalid tenant member UUID", "UUID parameter is required")
@@ -485,7 +654,14 @@ func (h *TenantHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.tenantMemberService.DeleteByUUID(r.Context(), tenantMemberUUID); err != nil {
+	// Get tenant to retrieve tenant_id
+	tenant, err := h.tenantService.GetByUUID(r.Context(), tenantUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Tenant not found", err)
+		return
+	}
+
+	if err := h.tenantMemberService.DeleteByUUID(r.Context(), tenant.TenantID, tenantMemberUUID); err != nil {
 		resp.HandleServiceError(w, r, "Failed to remove member", err)
 		return
 	}
@@ -496,16 +672,17 @@ func (h *TenantHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
 // Convert service result to DTO
 func toTenantResponseDTO(r service.TenantServiceDataResult) dto.TenantResponseDTO {
 	result := dto.TenantResponseDTO{
-		TenantUUID:  r.TenantUUID,
-		Name:        r.Name,
-		DisplayName: r.DisplayName,
-		Description: r.Description,
-		Identifier:  r.Identifier,
-		Status:      r.Status,
-		IsPublic:    r.IsPublic,
-		IsSystem:    r.IsSystem,
-		CreatedAt:   r.CreatedAt,
-		UpdatedAt:   r.UpdatedAt,
+		TenantUUID:       r.TenantUUID,
+		Name:             r.Name,
+		DisplayName:      r.DisplayName,
+		Description:      r.Description,
+		Identifier:       r.Identifier,
+		Status:           r.Status,
+		IsPublic:         r.IsPublic,
+		IsSystem:         r.IsSystem,
+		ParentTenantUUID: r.ParentTenantUUID,
+		CreatedAt:        r.CreatedAt,
+		UpdatedAt:        r.UpdatedAt,
 	}
 
 	return result
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// TenantDeprovisionHandler handles the two-step archiving and deletion of
+// tenants.
+type TenantDeprovisionHandler struct {
+	tenantProvisioner   service.TenantProvisioner
+	tenantMemberService service.TenantMemberService
+}
+
+// NewTenantDeprovisionHandler creates a new TenantDeprovisionHandler.
+func NewTenantDeprovisionHandler(tenantProvisioner service.TenantProvisioner, tenantMemberService service.TenantMemberService) *TenantDeprovisionHandler {
+	return &TenantDeprovisionHandler{
+		tenantProvisioner:   tenantProvisioner,
+		tenantMemberService: tenantMemberService,
+	}
+}
+
+// Request issues the confirmation token needed to archive or delete a tenant.
+// For deletes the response also carries a dry run of what would be removed.
+//
+// POST /tenants/{tenant_uuid}/deprovision
+func (h *TenantDeprovisionHandler) Request(w http.ResponseWriter, r *http.Request) {
+	user, tenantUUID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.TenantDeprovisionRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.tenantProvisioner.RequestDeprovision(r.Context(), tenantUUID, user.UserID, req.Mode)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to request tenant deprovision", err)
+		return
+	}
+
+	dtoRes := dto.TenantDeprovisionRequestResponseDTO{
+		DeprovisionUUID:   result.DeprovisionUUID,
+		TenantUUID:        result.TenantUUID,
+		Mode:              result.Mode,
+		ConfirmationToken: result.ConfirmationToken,
+		ExpiresAt:         result.ExpiresAt,
+	}
+	if result.Impact != nil {
+		impact := toDeleteImpactResponseDTO(result.Impact)
+		dtoRes.Impact = &impact
+	}
+
+	resp.Created(w, dtoRes, "Tenant deprovision requested, confirm it to proceed")
+}
+
+// Confirm archives or deletes a tenant with the token issued by Request.
+//
+// POST /tenants/{tenant_uuid}/deprovision/confirm
+func (h *TenantDeprovisionHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	user, tenantUUID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.TenantDeprovisionConfirmRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.tenantProvisioner.ConfirmDeprovision(r.Context(), tenantUUID, user.UserID, req.ConfirmationToken)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to deprovision tenant", err)
+		return
+	}
+
+	dtoRes := dto.TenantDeprovisionResponseDTO{
+		DeprovisionUUID: result.DeprovisionUUID,
+		TenantUUID:      result.TenantUUID,
+		Mode:            result.Mode,
+		CompletedAt:     result.CompletedAt,
+	}
+
+	resp.Success(w, dtoRes, "Tenant deprovisioned successfully")
+}
+
+// authorize resolves the caller and tenant and checks the caller is a member
+// of the tenant, writing the error response when not.
+func (h *TenantDeprovisionHandler) authorize(w http.ResponseWriter, r *http.Request) (*model.User, uuid.UUID, bool) {
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return nil, uuid.Nil, false
+	}
+
+	tenantUUID, err := uuid.Parse(chi.URLParam(r, "tenant_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid tenant UUID")
+		return nil, uuid.Nil, false
+	}
+
+	isMember, err := h.tenantMemberService.IsUserInTenant(r.Context(), user.UserID, tenantUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to verify tenant membership", err)
+		return nil, uuid.Nil, false
+	}
+	if !isMember {
+		resp.Error(w, http.StatusForbidden, "Access denied", "Only tenant members can deprovision this tenant")
+		return nil, uuid.Nil, false
+	}
+
+	return user, tenantUUID, true
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func memberOfTenant() *mockTenantMemberService {
+	return &mockTenantMemberService{
+		isUserInTenantFn: func(int64, uuid.UUID) (bool, error) { return true, nil },
+	}
+}
+
+func TestTenantDeprovisionHandler_Request_Unauthorized(t *testing.T) {
+	h := NewTenantDeprovisionHandler(&mockTenantProvisioner{}, memberOfTenant())
+	w := httptest.NewRecorder()
+	r := withChiParam(jsonReq(t, http.MethodPost, "/", map[string]any{"mode": "archive"}), "tenant_uuid", testTenantUUID.String())
+	h.Request(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestTenantDeprovisionHandler_Request_BadUUID(t *testing.T) {
+	h := NewTenantDeprovisionHandler(&mockTenantProvisioner{}, memberOfTenant())
+	w := httptest.NewRecorder()
+	r := withChiParam(withUser(jsonReq(t, http.MethodPost, "/", map[string]any{"mode": "archive"})), "tenant_uuid", "bad")
+	h.Request(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTenantDeprovisionHandler_Request_NotMember(t *testing.T) {
+	h := NewTenantDeprovisionHandler(&mockTenantProvisioner{}, &mockTenantMemberService{})
+	w := httptest.NewRecorder()
+	r := withChiParam(withUser(jsonReq(t, http.MethodPost, "/", map[string]any{"mode": "archive"})), "tenant_uuid", testTenantUUID.String())
+	h.Request(w, r)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestTenantDeprovisionHandler_Request_InvalidMode(t *testing.T) {
+	h := NewTenantDeprovisionHandler(&mockTenantProvisioner{}, memberOfTenant())
+	w := httptest.NewRecorder()
+	r := withChiParam(withUser(jsonReq(t, http.MethodPost, "/", map[string]any{"mode": "purge"})), "tenant_uuid", testTenantUUID.String())
+	h.Request(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTenantDeprovisionHandler_Request_ServiceError(t *testing.T) {
+	prov := &mockTenantProvisioner{
+		requestDeprovisionFn: func(uuid.UUID, int64, string) (*service.TenantDeprovisionRequestResult, error) {
+			return nil, errValidation
+		},
+	}
+	h := NewTenantDeprovisionHandler(prov, memberOfTenant())
+	w := httptest.NewRecorder()
+	r := withChiParam(withUser(jsonReq(t, http.MethodPost, "/", map[string]any{"mode": "archive"})), "tenant_uuid", testTenantUUID.String())
+	h.Request(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTenantDeprovisionHandler_Request_Success(t *testing.T) {
+	prov := &mockTenantProvisioner{
+		requestDeprovisionFn: func(tenantUUID uuid.UUID, _ int64, mode string) (*service.TenantDeprovisionRequestResult, error) {
+			assert.Equal(t, testTenantUUID, tenantUUID)
+			assert.Equal(t, "delete", mode)
+			return &service.TenantDeprovisionRequestResult{
+				DeprovisionUUID:   uuid.New(),
+				TenantUUID:        tenantUUID,
+				Mode:              mode,
+				ConfirmationToken: "confirm-token",
+				ExpiresAt:         time.Now().Add(15 * time.Minute),
+				Impact:            &service.DeleteImpactReport{WouldSucceed: true},
+			}, nil
+		},
+	}
+	h := NewTenantDeprovisionHandler(prov, memberOfTenant())
+	w := httptest.NewRecorder()
+	r := withChiParam(withUser(jsonReq(t, http.MethodPost, "/", map[string]any{"mode": "delete"})), "tenant_uuid", testTenantUUID.String())
+	h.Request(w, r)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), "confirm-token")
+	assert.Contains(t, w.Body.String(), `"would_succeed":true`)
+}
+
+func TestTenantDeprovisionHandler_Confirm_MissingToken(t *testing.T) {
+	h := NewTenantDeprovisionHandler(&mockTenantProvisioner{}, memberOfTenant())
+	w := httptest.NewRecorder()
+	r := withChiParam(withUser(jsonReq(t, http.MethodPost, "/", map[string]any{})), "tenant_uuid", testTenantUUID.String())
+	h.Confirm(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTenantDeprovisionHandler_Confirm_BadJSON(t *testing.T) {
+	h := NewTenantDeprovisionHandler(&mockTenantProvisioner{}, memberOfTenant())
+	w := httptest.NewRecorder()
+	r := withChiParam(withUser(badJSONReq(t, http.MethodPost, "/")), "tenant_uuid", testTenantUUID.String())
+	h.Confirm(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTenantDeprovisionHandler_Confirm_OtherRequester(t *testing.T) {
+	prov := &mockTenantProvisioner{
+		confirmDeprovisionFn: func(uuid.UUID, int64, string) (*service.TenantDeprovisionResult, error) {
+			return nil, errForbidden
+		},
+	}
+	h := NewTenantDeprovisionHandler(prov, memberOfTenant())
+	w := httptest.NewRecorder()
+	r := withChiParam(withUser(jsonReq(t, http.MethodPost, "/", map[string]any{"confirmation_token": "t"})), "tenant_uuid", testTenantUUID.String())
+	h.Confirm(w, r)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestTenantDeprovisionHandler_Confirm_Success(t *testing.T) {
+	prov := &mockTenantProvisioner{
+		confirmDeprovisionFn: func(tenantUUID uuid.UUID, _ int64, token string) (*service.TenantDeprovisionResult, error) {
+			assert.Equal(t, "confirm-token", token)
+			return &service.TenantDeprovisionResult{TenantUUID: tenantUUID, Mode: "archive", CompletedAt: time.Now()}, nil
+		},
+	}
+	h := NewTenantDeprovisionHandler(prov, memberOfTenant())
+	w := httptest.NewRecorder()
+	r := withChiParam(withUser(jsonReq(t, http.MethodPost, "/", map[string]any{"confirmation_token": "confirm-token"})), "tenant_uuid", testTenantUUID.String())
+	h.Confirm(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"mode":"archive"`)
+}
@@ -0,0 +1,346 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// tenantScopedEndpoint is a list or get endpoint whose results must come from
+// the caller's tenant only.
+type tenantScopedEndpoint struct {
+	name string
+	// params are the chi URL parameters naming the resource.
+	params []string
+	// build returns the endpoint with its service mocked; seen is called with
+	// the tenant ID the handler passes to the service.
+	build func(seen func(int64)) http.HandlerFunc
+}
+
+func tenantScopedEndpoints() []tenantScopedEndpoint {
+	return []tenantScopedEndpoint{
+		{
+			name: "clients list",
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewClientHandler(&mockClientService{getFn: func(f service.ClientServiceGetFilter) (*service.ClientServiceGetResult, error) {
+					seen(f.TenantID)
+					return &service.ClientServiceGetResult{}, nil
+				}}).Get
+			},
+		},
+		{
+			name:   "client get",
+			params: []string{"client_uuid"},
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewClientHandler(&mockClientService{getByUUIDFn: func(_ uuid.UUID, tid int64) (*service.ClientServiceDataResult, error) {
+					seen(tid)
+					return nil, errNotFound
+				}}).GetByUUID
+			},
+		},
+		{
+			name:   "client APIs",
+			params: []string{"client_uuid"},
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewClientHandler(&mockClientService{getClientAPIsFn: func(tid int64, _ uuid.UUID) ([]service.ClientAPIServiceDataResult, error) {
+					seen(tid)
+					return nil, errNotFound
+				}}).GetAPIs
+			},
+		},
+		{
+			name:   "client API permissions",
+			params: []string{"client_uuid", "api_uuid"},
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewClientHandler(&mockClientService{getClientAPIPermsFn: func(tid int64, _, _ uuid.UUID) ([]service.PermissionServiceDataResult, error) {
+					seen(tid)
+					return nil, errNotFound
+				}}).GetAPIPermissions
+			},
+		},
+		{
+			name: "API keys list",
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewAPIKeyHandler(&mockAPIKeyService{getFn: func(f service.APIKeyServiceGetFilter, _ uuid.UUID) (*service.APIKeyServiceGetResult, error) {
+					seen(f.TenantID)
+					return &service.APIKeyServiceGetResult{}, nil
+				}}).Get
+			},
+		},
+		{
+			name:   "API key get",
+			params: []string{"api_key_uuid"},
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewAPIKeyHandler(&mockAPIKeyService{getByUUIDFn: func(_ uuid.UUID, tid int64, _ uuid.UUID) (*service.APIKeyServiceDataResult, error) {
+					seen(tid)
+					return nil, errNotFound
+				}}).GetByUUID
+			},
+		},
+		{
+			name:   "API key APIs",
+			params: []string{"api_key_uuid"},
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewAPIKeyHandler(&mockAPIKeyService{getAPIKeyAPIsFn: func(_ uuid.UUID, tid int64, _, _ int, _, _ string) (*service.APIKeyAPIServicePaginatedResult, error) {
+					seen(tid)
+					return nil, errNotFound
+				}}).GetAPIs
+			},
+		},
+		{
+			name:   "API key API permissions",
+			params: []string{"api_key_uuid", "api_uuid"},
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewAPIKeyHandler(&mockAPIKeyService{getAPIKeyAPIPermsFn: func(_ uuid.UUID, tid int64, _ uuid.UUID) ([]service.PermissionServiceDataResult, error) {
+					seen(tid)
+					return nil, errNotFound
+				}}).GetAPIPermissions
+			},
+		},
+		{
+			name: "APIs list",
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewAPIHandler(&mockAPIService{getFn: func(f service.APIServiceGetFilter) (*service.APIServiceGetResult, error) {
+					seen(f.TenantID)
+					return &service.APIServiceGetResult{}, nil
+				}}).Get
+			},
+		},
+		{
+			name:   "API get",
+			params: []string{"api_uuid"},
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewAPIHandler(&mockAPIService{getByUUIDFn: func(_ uuid.UUID, tid int64) (*service.APIServiceDataResult, error) {
+					seen(tid)
+					return nil, errNotFound
+				}}).GetByUUID
+			},
+		},
+		{
+			name: "roles list",
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewRoleHandler(&mockRoleService{getFn: func(f service.RoleServiceGetFilter) (*service.RoleServiceGetResult, error) {
+					seen(f.TenantID)
+					return &service.RoleServiceGetResult{}, nil
+				}}).Get
+			},
+		},
+		{
+			name:   "role get",
+			params: []string{"role_uuid"},
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewRoleHandler(&mockRoleService{getByUUIDFn: func(_ uuid.UUID, tid int64) (*service.RoleServiceDataResult, error) {
+					seen(tid)
+					return nil, errNotFound
+				}}).GetByUUID
+			},
+		},
+		{
+			name: "permissions list",
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewPermissionHandler(&mockPermissionService{getFn: func(f service.PermissionServiceGetFilter) (*service.PermissionServiceGetResult, error) {
+					seen(f.TenantID)
+					return &service.PermissionServiceGetResult{}, nil
+				}}).Get
+			},
+		},
+		{
+			name:   "permission get",
+			params: []string{"permission_uuid"},
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewPermissionHandler(&mockPermissionService{getByUUIDFn: func(_ uuid.UUID, tid int64) (*service.PermissionServiceDataResult, error) {
+					seen(tid)
+					return nil, errNotFound
+				}}).GetByUUID
+			},
+		},
+		{
+			name: "policies list",
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewPolicyHandler(&mockPolicyService{getFn: func(f service.PolicyServiceGetFilter) (*service.PolicyServiceGetResult, error) {
+					seen(f.TenantID)
+					return &service.PolicyServiceGetResult{}, nil
+				}}).Get
+			},
+		},
+		{
+			name:   "policy get",
+			params: []string{"policy_uuid"},
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewPolicyHandler(&mockPolicyService{getByUUIDFn: func(_ uuid.UUID, tid int64) (*service.PolicyServiceDataResult, error) {
+					seen(tid)
+					return nil, errNotFound
+				}}).GetByUUID
+			},
+		},
+		{
+			name: "identity providers list",
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewIdentityProviderHandler(&mockIdentityProviderService{getFn: func(f service.IdentityProviderServiceGetFilter) (*service.IdentityProviderServiceGetResult, error) {
+					seen(f.TenantID)
+					return &service.IdentityProviderServiceGetResult{}, nil
+				}}).Get
+			},
+		},
+		{
+			name:   "identity provider get",
+			params: []string{"identity_provider_uuid"},
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewIdentityProviderHandler(&mockIdentityProviderService{getByUUIDFn: func(_ uuid.UUID, tid int64) (*service.IdentityProviderServiceDataResult, error) {
+					seen(tid)
+					return nil, errNotFound
+				}}).GetByUUID
+			},
+		},
+		{
+			name: "services list",
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewServiceHandler(&mockServiceService{getFn: func(f service.ServiceServiceGetFilter) (*service.ServiceServiceGetResult, error) {
+					if f.TenantID != nil {
+						seen(*f.TenantID)
+					}
+					return &service.ServiceServiceGetResult{}, nil
+				}}).Get
+			},
+		},
+		{
+			name:   "service get",
+			params: []string{"service_uuid"},
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewServiceHandler(&mockServiceService{getByUUIDFn: func(_ uuid.UUID, tid int64) (*service.ServiceServiceDataResult, error) {
+					seen(tid)
+					return nil, errNotFound
+				}}).GetByUUID
+			},
+		},
+		{
+			name: "users list",
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewUserHandler(&mockUserService{getFn: func(f service.UserServiceGetFilter) (*service.UserServiceGetResult, error) {
+					seen(f.TenantID)
+					return &service.UserServiceGetResult{}, nil
+				}}).GetUsers
+			},
+		},
+		{
+			name:   "user get",
+			params: []string{"user_uuid"},
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewUserHandler(&mockUserService{getByUUIDFn: func(_ uuid.UUID, tid int64) (*service.UserServiceDataResult, error) {
+					seen(tid)
+					return nil, errNotFound
+				}}).GetUser
+			},
+		},
+		{
+			name: "auth events list",
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewAuthEventHandler(&mockAuthEventService{findPaginatedFn: func(_ context.Context, f repository.AuthEventRepositoryGetFilter) (*repository.PaginationResult[service.AuthEventServiceDataResult], error) {
+					if f.TenantID != nil {
+						seen(*f.TenantID)
+					}
+					return &repository.PaginationResult[service.AuthEventServiceDataResult]{}, nil
+				}}).GetAll
+			},
+		},
+		{
+			name:   "auth event get",
+			params: []string{"auth_event_uuid"},
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewAuthEventHandler(&mockAuthEventService{findByUUIDFn: func(_ context.Context, tid int64, _ uuid.UUID) (*service.AuthEventServiceDataResult, error) {
+					seen(tid)
+					return nil, errNotFound
+				}}).Get
+			},
+		},
+		{
+			name: "webhook endpoints list",
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewWebhookEndpointHandler(&mockWebhookEndpointService{getAllFn: func(tid int64, _ []string, _, _ int, _, _ string) (*service.WebhookEndpointServiceListResult, error) {
+					seen(tid)
+					return &service.WebhookEndpointServiceListResult{}, nil
+				}}).GetAll
+			},
+		},
+		{
+			name:   "webhook endpoint get",
+			params: []string{"webhook_endpoint_uuid"},
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewWebhookEndpointHandler(&mockWebhookEndpointService{getByUUIDFn: func(tid int64, _ uuid.UUID) (*service.WebhookEndpointServiceDataResult, error) {
+					seen(tid)
+					return nil, errNotFound
+				}}).Get
+			},
+		},
+		{
+			name: "email templates list",
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewEmailTemplateHandler(&mockEmailTemplateService{getAllFn: func(tid int64, _ *string, _ []string, _, _ *bool, _, _ int, _, _ string) (*service.EmailTemplateServiceListResult, error) {
+					seen(tid)
+					return &service.EmailTemplateServiceListResult{}, nil
+				}}).GetAll
+			},
+		},
+		{
+			name:   "email template get",
+			params: []string{"email_template_uuid"},
+			build: func(seen func(int64)) http.HandlerFunc {
+				return NewEmailTemplateHandler(&mockEmailTemplateService{getByUUIDFn: func(_ uuid.UUID, tid int64) (*service.EmailTemplateServiceDataResult, error) {
+					seen(tid)
+					return nil, errNotFound
+				}}).Get
+			},
+		},
+	}
+}
+
+// tenantScopedRequest builds a GET request for e that also tries to name
+// another tenant through the query string.
+func tenantScopedRequest(e tenantScopedEndpoint) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/?page=1&limit=10&tenant_id=2&tenant_uuid="+uuid.NewString(), nil)
+	for _, param := range e.params {
+		r = withChiParam(r, param, testResourceUUID.String())
+	}
+	return r
+}
+
+// TestTenantIsolation_ScopesToCallerTenant checks that every tenant-scoped
+// list and get endpoint asks its service for the caller's tenant only, so
+// records of other tenants can neither be listed nor fetched.
+func TestTenantIsolation_ScopesToCallerTenant(t *testing.T) {
+	for _, e := range tenantScopedEndpoints() {
+		t.Run(e.name, func(t *testing.T) {
+			var seen []int64
+			h := e.build(func(tid int64) { seen = append(seen, tid) })
+
+			w := httptest.NewRecorder()
+			h(w, withTenantAndUser(tenantScopedRequest(e)))
+
+			assert.Equal(t, []int64{tenantID}, seen, "service must be scoped to the caller's tenant")
+			assert.NotEqual(t, http.StatusUnauthorized, w.Code)
+		})
+	}
+}
+
+// TestTenantIsolation_RequiresTenant checks that no tenant-scoped endpoint
+// reaches its service when the request carries no tenant.
+func TestTenantIsolation_RequiresTenant(t *testing.T) {
+	for _, e := range tenantScopedEndpoints() {
+		t.Run(e.name, func(t *testing.T) {
+			called := false
+			h := e.build(func(int64) { called = true })
+
+			w := httptest.NewRecorder()
+			h(w, withUser(tenantScopedRequest(e)))
+
+			assert.False(t, called, "service must not be called without a tenant")
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+		})
+	}
+}
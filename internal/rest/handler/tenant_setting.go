@@ -6,12 +6,14 @@ import (
 
 	"github.com/maintainerd/auth/internal/dto"
 	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
 	resp "github.com/maintainerd/auth/internal/rest/response"
 	"github.com/maintainerd/auth/internal/service"
 )
 
 // TenantSettingHandler handles tenant-level settings endpoints with JSONB
-// sub-configs (rate_limit, audit, maintenance, feature_flags).
+// sub-configs (rate_limit, audit, maintenance, feature_flags,
+// user_metadata_schema, user_setting_defaults).
 type TenantSettingHandler struct {
 	tenantSettingService service.TenantSettingService
 }
@@ -216,3 +218,110 @@ func (h *TenantSettingHandler) UpdateFeatureFlags(w http.ResponseWriter, r *http
 
 	resp.Success(w, dto.TenantSettingConfigResponseDTO(result.FeatureFlags), "Feature flags updated successfully")
 }
+
+// GetUserMetadataSchema retrieves the JSON Schema applied to user metadata.
+//
+// GET /tenant-settings/user-metadata-schema
+func (h *TenantSettingHandler) GetUserMetadataSchema(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	schema, err := h.tenantSettingService.GetUserMetadataSchema(r.Context(), tenant.TenantID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get user metadata schema", err)
+		return
+	}
+
+	resp.Success(w, dto.TenantSettingConfigResponseDTO(schema), "User metadata schema retrieved successfully")
+}
+
+// UpdateUserMetadataSchema replaces the JSON Schema applied to user metadata.
+// Sending an empty object removes the schema.
+//
+// PUT /tenant-settings/user-metadata-schema
+func (h *TenantSettingHandler) UpdateUserMetadataSchema(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	var req dto.TenantSettingUserMetadataSchemaRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.tenantSettingService.UpdateUserMetadataSchema(r.Context(), tenant.TenantID, map[string]any(req))
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to update user metadata schema", err)
+		return
+	}
+
+	resp.Success(w, dto.TenantSettingConfigResponseDTO(result.UserMetadataSchema), "User metadata schema updated successfully")
+}
+
+// GetUserSettingDefaults retrieves the preferences applied to users who have
+// not chosen their own.
+//
+// GET /tenant-settings/user-setting-defaults
+func (h *TenantSettingHandler) GetUserSettingDefaults(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	defaults, err := h.tenantSettingService.GetUserSettingDefaults(r.Context(), tenant.TenantID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get user setting defaults", err)
+		return
+	}
+
+	resp.Success(w, dto.TenantSettingConfigResponseDTO(defaults), "User setting defaults retrieved successfully")
+}
+
+// UpdateUserSettingDefaults replaces the preferences applied to users who
+// have not chosen their own.
+//
+// PUT /tenant-settings/user-setting-defaults
+func (h *TenantSettingHandler) UpdateUserSettingDefaults(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	var req dto.TenantSettingUserSettingDefaultsRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.tenantSettingService.UpdateUserSettingDefaults(r.Context(), tenant.TenantID, model.UserSettingDefaults{
+		Theme:             req.Theme,
+		PreferredLanguage: req.PreferredLanguage,
+		Locale:            req.Locale,
+		Timezone:          req.Timezone,
+		DateFormat:        req.DateFormat,
+	})
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to update user setting defaults", err)
+		return
+	}
+
+	resp.Success(w, dto.TenantSettingConfigResponseDTO(result.UserSettingDefaults), "User setting defaults updated successfully")
+}
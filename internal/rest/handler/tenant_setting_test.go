@@ -3,9 +3,11 @@ package handler
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/service"
 	"github.com/stretchr/testify/assert"
 )
@@ -336,3 +338,174 @@ func TestTenantSettingHandler_UpdateFeatureFlags_Success(t *testing.T) {
 	h.UpdateFeatureFlags(w, withTenant(jsonReq(t, http.MethodPut, "/", map[string]any{"k": "v"})))
 	assert.Equal(t, http.StatusOK, w.Code)
 }
+
+// ---------------------------------------------------------------------------
+// User metadata schema
+// ---------------------------------------------------------------------------
+
+func TestTenantSettingHandler_GetUserMetadataSchema_NoTenant(t *testing.T) {
+	h := NewTenantSettingHandler(&mockTenantSettingService{})
+	w := httptest.NewRecorder()
+	h.GetUserMetadataSchema(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestTenantSettingHandler_GetUserMetadataSchema_ServiceError(t *testing.T) {
+	svc := &mockTenantSettingService{
+		getUserMetadataSchemaFn: func(_ int64) (map[string]any, error) { return nil, assert.AnError },
+	}
+	h := NewTenantSettingHandler(svc)
+	w := httptest.NewRecorder()
+	h.GetUserMetadataSchema(w, withTenant(httptest.NewRequest(http.MethodGet, "/", nil)))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestTenantSettingHandler_GetUserMetadataSchema_Success(t *testing.T) {
+	svc := &mockTenantSettingService{
+		getUserMetadataSchemaFn: func(_ int64) (map[string]any, error) {
+			return map[string]any{"type": "object"}, nil
+		},
+	}
+	h := NewTenantSettingHandler(svc)
+	w := httptest.NewRecorder()
+	h.GetUserMetadataSchema(w, withTenant(httptest.NewRequest(http.MethodGet, "/", nil)))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestTenantSettingHandler_UpdateUserMetadataSchema_NoTenant(t *testing.T) {
+	h := NewTenantSettingHandler(&mockTenantSettingService{})
+	w := httptest.NewRecorder()
+	h.UpdateUserMetadataSchema(w, httptest.NewRequest(http.MethodPut, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestTenantSettingHandler_UpdateUserMetadataSchema_BadJSON(t *testing.T) {
+	h := NewTenantSettingHandler(&mockTenantSettingService{})
+	w := httptest.NewRecorder()
+	h.UpdateUserMetadataSchema(w, withTenant(badJSONReq(t, http.MethodPut, "/")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTenantSettingHandler_UpdateUserMetadataSchema_ValidationError(t *testing.T) {
+	h := NewTenantSettingHandler(&mockTenantSettingService{})
+	w := httptest.NewRecorder()
+	// A JSON null decodes to a nil map, which is rejected.
+	h.UpdateUserMetadataSchema(w, withTenant(httptest.NewRequest(http.MethodPut, "/", strings.NewReader("null"))))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTenantSettingHandler_UpdateUserMetadataSchema_InvalidSchema(t *testing.T) {
+	svc := &mockTenantSettingService{
+		updateUserMetadataSchemaFn: func(_ int64, _ map[string]any) (*service.TenantSettingServiceDataResult, error) {
+			return nil, errValidation
+		},
+	}
+	h := NewTenantSettingHandler(svc)
+	w := httptest.NewRecorder()
+	h.UpdateUserMetadataSchema(w, withTenant(jsonReq(t, http.MethodPut, "/", map[string]any{"type": 5})))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTenantSettingHandler_UpdateUserMetadataSchema_Success(t *testing.T) {
+	var got map[string]any
+	svc := &mockTenantSettingService{
+		updateUserMetadataSchemaFn: func(_ int64, schema map[string]any) (*service.TenantSettingServiceDataResult, error) {
+			got = schema
+			res := tenantSettingResult()
+			res.UserMetadataSchema = schema
+			return res, nil
+		},
+	}
+	h := NewTenantSettingHandler(svc)
+	w := httptest.NewRecorder()
+	h.UpdateUserMetadataSchema(w, withTenant(jsonReq(t, http.MethodPut, "/", map[string]any{"type": "object"})))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "object", got["type"])
+}
+
+// ---------------------------------------------------------------------------
+// User setting defaults
+// ---------------------------------------------------------------------------
+
+func TestTenantSettingHandler_GetUserSettingDefaults_NoTenant(t *testing.T) {
+	h := NewTenantSettingHandler(&mockTenantSettingService{})
+	w := httptest.NewRecorder()
+	h.GetUserSettingDefaults(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestTenantSettingHandler_GetUserSettingDefaults_ServiceError(t *testing.T) {
+	svc := &mockTenantSettingService{
+		getUserSettingDefaultsFn: func(_ int64) (map[string]any, error) { return nil, assert.AnError },
+	}
+	h := NewTenantSettingHandler(svc)
+	w := httptest.NewRecorder()
+	h.GetUserSettingDefaults(w, withTenant(httptest.NewRequest(http.MethodGet, "/", nil)))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestTenantSettingHandler_GetUserSettingDefaults_Success(t *testing.T) {
+	svc := &mockTenantSettingService{
+		getUserSettingDefaultsFn: func(_ int64) (map[string]any, error) {
+			return map[string]any{"theme": "dark"}, nil
+		},
+	}
+	h := NewTenantSettingHandler(svc)
+	w := httptest.NewRecorder()
+	h.GetUserSettingDefaults(w, withTenant(httptest.NewRequest(http.MethodGet, "/", nil)))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestTenantSettingHandler_UpdateUserSettingDefaults_NoTenant(t *testing.T) {
+	h := NewTenantSettingHandler(&mockTenantSettingService{})
+	w := httptest.NewRecorder()
+	h.UpdateUserSettingDefaults(w, httptest.NewRequest(http.MethodPut, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestTenantSettingHandler_UpdateUserSettingDefaults_BadJSON(t *testing.T) {
+	h := NewTenantSettingHandler(&mockTenantSettingService{})
+	w := httptest.NewRecorder()
+	h.UpdateUserSettingDefaults(w, withTenant(badJSONReq(t, http.MethodPut, "/")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTenantSettingHandler_UpdateUserSettingDefaults_ValidationError(t *testing.T) {
+	h := NewTenantSettingHandler(&mockTenantSettingService{})
+	w := httptest.NewRecorder()
+	h.UpdateUserSettingDefaults(w, withTenant(jsonReq(t, http.MethodPut, "/", map[string]any{"theme": "neon"})))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTenantSettingHandler_UpdateUserSettingDefaults_ServiceError(t *testing.T) {
+	svc := &mockTenantSettingService{
+		updateUserSettingDefaultsFn: func(_ int64, _ model.UserSettingDefaults) (*service.TenantSettingServiceDataResult, error) {
+			return nil, assert.AnError
+		},
+	}
+	h := NewTenantSettingHandler(svc)
+	w := httptest.NewRecorder()
+	h.UpdateUserSettingDefaults(w, withTenant(jsonReq(t, http.MethodPut, "/", map[string]any{"theme": "dark"})))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestTenantSettingHandler_UpdateUserSettingDefaults_Success(t *testing.T) {
+	var got model.UserSettingDefaults
+	svc := &mockTenantSettingService{
+		updateUserSettingDefaultsFn: func(_ int64, defaults model.UserSettingDefaults) (*service.TenantSettingServiceDataResult, error) {
+			got = defaults
+			res := tenantSettingResult()
+			res.UserSettingDefaults = map[string]any{"theme": defaults.Theme}
+			return res, nil
+		},
+	}
+	h := NewTenantSettingHandler(svc)
+	w := httptest.NewRecorder()
+	h.UpdateUserSettingDefaults(w, withTenant(jsonReq(t, http.MethodPut, "/", map[string]any{
+		"theme":       "dark",
+		"timezone":    "Europe/Berlin",
+		"date_format": "DD.MM.YYYY",
+	})))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, model.UserSettingDefaults{Theme: "dark", Timezone: "Europe/Berlin", DateFormat: "DD.MM.YYYY"}, got)
+}
@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/service"
 	"github.com/stretchr/testify/assert"
 )
@@ -48,6 +50,62 @@ func TestTenantHandler_Get(t *testing.T) {
 		newTenantHandler(svc, nil).Get(w, r)
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
+
+	t.Run("repeated status parameters are combined", func(t *testing.T) {
+		var got []string
+		svc := &mockTenantService{getFn: func(f service.TenantServiceGetFilter) (*service.TenantServiceGetResult, error) {
+			got = f.Status
+			return &service.TenantServiceGetResult{}, nil
+		}}
+		r := httptest.NewRequest(http.MethodGet, "/tenants?page=1&limit=10&status=active&status=inactive", nil)
+		w := httptest.NewRecorder()
+		newTenantHandler(svc, nil).Get(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"active", "inactive"}, got)
+	})
+
+	t.Run("lists only the caller's tenants", func(t *testing.T) {
+		var got []int64
+		svc := &mockTenantService{getFn: func(f service.TenantServiceGetFilter) (*service.TenantServiceGetResult, error) {
+			got = f.TenantIDs
+			return &service.TenantServiceGetResult{}, nil
+		}}
+		r := httptest.NewRequest(http.MethodGet, "/tenants?page=1&limit=10", nil)
+		w := httptest.NewRecorder()
+		newTenantHandler(svc, nil).Get(w, withTenantAndUser(r))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []int64{tenantID}, got)
+	})
+
+	t.Run("includes the tenants below the caller's tenants", func(t *testing.T) {
+		var got bool
+		svc := &mockTenantService{getFn: func(f service.TenantServiceGetFilter) (*service.TenantServiceGetResult, error) {
+			got = f.IncludeDescendants
+			return &service.TenantServiceGetResult{}, nil
+		}}
+		r := httptest.NewRequest(http.MethodGet, "/tenants?page=1&limit=10", nil)
+		w := httptest.NewRecorder()
+		newTenantHandler(svc, nil).Get(w, withTenantAndUser(r))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, got)
+	})
+
+	t.Run("system tenant callers list every tenant", func(t *testing.T) {
+		var got []int64
+		svc := &mockTenantService{getFn: func(f service.TenantServiceGetFilter) (*service.TenantServiceGetResult, error) {
+			got = f.TenantIDs
+			return &service.TenantServiceGetResult{}, nil
+		}}
+		r := httptest.NewRequest(http.MethodGet, "/tenants?page=1&limit=10", nil)
+		r = middleware.WithAuthContext(r, &middleware.AuthContext{
+			User:   &model.User{UserUUID: testUserUUID},
+			Tenant: &model.Tenant{TenantID: tenantID, IsSystem: true},
+		})
+		w := httptest.NewRecorder()
+		newTenantHandler(svc, nil).Get(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Nil(t, got)
+	})
 }
 
 func TestTenantHandler_GetByUUID(t *testing.T) {
@@ -146,7 +204,7 @@ func TestTenantHandler_Create(t *testing.T) {
 	})
 
 	t.Run("service error returns 500", func(t *testing.T) {
-		svc := &mockTenantService{createFn: func(n, dn, desc, s string, isPublic bool) (*service.TenantServiceDataResult, error) {
+		svc := &mockTenantService{createFn: func(n, dn, desc, s string, isPublic bool, parent *uuid.UUID) (*service.TenantServiceDataResult, error) {
 			return nil, errors.New("db error")
 		}}
 		r := jsonReq(t, http.MethodPost, "/tenants", validBody)
@@ -156,7 +214,7 @@ func TestTenantHandler_Create(t *testing.T) {
 	})
 
 	t.Run("success returns 201", func(t *testing.T) {
-		svc := &mockTenantService{createFn: func(n, dn, desc, s string, isPublic bool) (*service.TenantServiceDataResult, error) {
+		svc := &mockTenantService{createFn: func(n, dn, desc, s string, isPublic bool, parent *uuid.UUID) (*service.TenantServiceDataResult, error) {
 			return &service.TenantServiceDataResult{Name: n}, nil
 		}}
 		r := jsonReq(t, http.MethodPost, "/tenants", validBody)
@@ -164,6 +222,136 @@ func TestTenantHandler_Create(t *testing.T) {
 		newTenantHandler(svc, nil).Create(w, r)
 		assert.Equal(t, http.StatusCreated, w.Code)
 	})
+
+	t.Run("parent the caller cannot access returns 403", func(t *testing.T) {
+		body := map[string]any{"name": "my-tenant", "display_name": "My Tenant", "description": "A long enough description", "status": "active", "parent_tenant_id": testResourceUUID.String()}
+		svc := &mockTenantService{getAncestorIDsFn: func(uuid.UUID) ([]int64, error) {
+			return []int64{99}, nil
+		}}
+		w := httptest.NewRecorder()
+		newTenantHandler(svc, nil).Create(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/tenants", body)))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("child of the caller's tenant returns 201", func(t *testing.T) {
+		body := map[string]any{"name": "my-tenant", "display_name": "My Tenant", "description": "A long enough description", "status": "active", "parent_tenant_id": testTenantUUID.String()}
+		var got *uuid.UUID
+		svc := &mockTenantService{createFn: func(n, dn, desc, s string, isPublic bool, parent *uuid.UUID) (*service.TenantServiceDataResult, error) {
+			got = parent
+			return &service.TenantServiceDataResult{Name: n, ParentTenantUUID: parent}, nil
+		}}
+		w := httptest.NewRecorder()
+		newTenantHandler(svc, nil).Create(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/tenants", body)))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		if assert.NotNil(t, got) {
+			assert.Equal(t, testTenantUUID, *got)
+		}
+		assert.Contains(t, w.Body.String(), `"parent_tenant_id":"`+testTenantUUID.String()+`"`)
+	})
+}
+
+func TestTenantHandler_SetParent(t *testing.T) {
+	t.Run("invalid UUID returns 400", func(t *testing.T) {
+		r := withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{}), "tenant_uuid", "bad")
+		w := httptest.NewRecorder()
+		newTenantHandler(nil, nil).SetParent(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("bad JSON returns 400", func(t *testing.T) {
+		r := withChiParam(badJSONReq(t, http.MethodPut, "/"), "tenant_uuid", testResourceUUID.String())
+		w := httptest.NewRecorder()
+		newTenantHandler(nil, nil).SetParent(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid parent returns 400", func(t *testing.T) {
+		r := withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{"parent_tenant_id": "bad"}), "tenant_uuid", testResourceUUID.String())
+		w := httptest.NewRecorder()
+		newTenantHandler(nil, nil).SetParent(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("parent the caller cannot access returns 403", func(t *testing.T) {
+		svc := &mockTenantService{getAncestorIDsFn: func(uuid.UUID) ([]int64, error) {
+			return nil, nil
+		}}
+		r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{"parent_tenant_id": uuid.New().String()}), "tenant_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		newTenantHandler(svc, nil).SetParent(w, r)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("service error is mapped", func(t *testing.T) {
+		svc := &mockTenantService{setParentByUUIDFn: func(uuid.UUID, *uuid.UUID) (*service.TenantServiceDataResult, error) {
+			return nil, errValidation
+		}}
+		r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{"parent_tenant_id": testTenantUUID.String()}), "tenant_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		newTenantHandler(svc, nil).SetParent(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("null parent makes the tenant a root tenant", func(t *testing.T) {
+		called := false
+		svc := &mockTenantService{setParentByUUIDFn: func(id uuid.UUID, parent *uuid.UUID) (*service.TenantServiceDataResult, error) {
+			called = true
+			assert.Nil(t, parent)
+			return &service.TenantServiceDataResult{TenantUUID: id}, nil
+		}}
+		r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{"parent_tenant_id": nil}), "tenant_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		newTenantHandler(svc, nil).SetParent(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, called)
+	})
+}
+
+func TestTenantHandler_GetChildren(t *testing.T) {
+	t.Run("invalid UUID returns 400", func(t *testing.T) {
+		r := withChiParam(httptest.NewRequest(http.MethodGet, "/", nil), "tenant_uuid", "bad")
+		w := httptest.NewRecorder()
+		newTenantHandler(nil, nil).GetChildren(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("validation error returns 400", func(t *testing.T) {
+		r := withChiParam(httptest.NewRequest(http.MethodGet, "/?page=1&limit=10&sort_order=bad", nil), "tenant_uuid", testResourceUUID.String())
+		w := httptest.NewRecorder()
+		newTenantHandler(nil, nil).GetChildren(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unknown tenant returns 404", func(t *testing.T) {
+		svc := &mockTenantService{getByUUIDFn: func(uuid.UUID) (*service.TenantServiceDataResult, error) {
+			return nil, errNotFound
+		}}
+		r := withChiParam(httptest.NewRequest(http.MethodGet, "/?page=1&limit=10", nil), "tenant_uuid", testResourceUUID.String())
+		w := httptest.NewRecorder()
+		newTenantHandler(svc, nil).GetChildren(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("lists the tenant's children", func(t *testing.T) {
+		var got *int64
+		svc := &mockTenantService{
+			getByUUIDFn: func(id uuid.UUID) (*service.TenantServiceDataResult, error) {
+				return &service.TenantServiceDataResult{TenantID: 7, TenantUUID: id}, nil
+			},
+			getFn: func(f service.TenantServiceGetFilter) (*service.TenantServiceGetResult, error) {
+				got = f.ParentTenantID
+				return &service.TenantServiceGetResult{Data: []service.TenantServiceDataResult{{Name: "child"}}, Total: 1}, nil
+			},
+		}
+		r := withChiParam(httptest.NewRequest(http.MethodGet, "/?page=1&limit=10", nil), "tenant_uuid", testResourceUUID.String())
+		w := httptest.NewRecorder()
+		newTenantHandler(svc, nil).GetChildren(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		if assert.NotNil(t, got) {
+			assert.Equal(t, int64(7), *got)
+		}
+		assert.Contains(t, w.Body.String(), `"child"`)
+	})
 }
 
 func TestTenantHandler_Update(t *testing.T) {
@@ -522,85 +710,135 @@ func TestTenantHandler_AddMember(t *testing.T) {
 
 func TestTenantHandler_UpdateMemberRole(t *testing.T) {
 	memberUUID := uuid.New()
+	ts := &mockTenantService{getByUUIDFn: func(id uuid.UUID) (*service.TenantServiceDataResult, error) {
+		return &service.TenantServiceDataResult{TenantID: 7, TenantUUID: id}, nil
+	}}
+	memberReq := func(r *http.Request, memberParam string) *http.Request {
+		return withChiParam(withChiParam(r, "tenant_uuid", testTenantUUID.String()), "tenant_member_uuid", memberParam)
+	}
+
+	t.Run("invalid tenant UUID returns 400", func(t *testing.T) {
+		r := withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{"role": "admin"}), "tenant_member_uuid", memberUUID.String())
+		w := httptest.NewRecorder()
+		newTenantHandler(nil, nil).UpdateMemberRole(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 
 	t.Run("empty UUID param returns 400", func(t *testing.T) {
-		r := jsonReq(t, http.MethodPut, "/", map[string]any{"role": "admin"})
+		r := withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{"role": "admin"}), "tenant_uuid", testTenantUUID.String())
 		w := httptest.NewRecorder()
 		newTenantHandler(nil, nil).UpdateMemberRole(w, r)
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
 	t.Run("invalid UUID format returns 400", func(t *testing.T) {
-		r := withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{"role": "admin"}), "tenant_member_uuid", "bad")
+		r := memberReq(jsonReq(t, http.MethodPut, "/", map[string]any{"role": "admin"}), "bad")
 		w := httptest.NewRecorder()
 		newTenantHandler(nil, nil).UpdateMemberRole(w, r)
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
 	t.Run("bad JSON returns 400", func(t *testing.T) {
-		r := withChiParam(badJSONReq(t, http.MethodPut, "/"), "tenant_member_uuid", memberUUID.String())
+		r := memberReq(badJSONReq(t, http.MethodPut, "/"), memberUUID.String())
 		w := httptest.NewRecorder()
 		newTenantHandler(nil, nil).UpdateMemberRole(w, r)
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
 	t.Run("validation error returns 400", func(t *testing.T) {
-		r := withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{"role": ""}), "tenant_member_uuid", memberUUID.String())
+		r := memberReq(jsonReq(t, http.MethodPut, "/", map[string]any{"role": ""}), memberUUID.String())
 		w := httptest.NewRecorder()
 		newTenantHandler(nil, nil).UpdateMemberRole(w, r)
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
+	t.Run("tenant not found returns 404", func(t *testing.T) {
+		notFound := &mockTenantService{getByUUIDFn: func(uuid.UUID) (*service.TenantServiceDataResult, error) {
+			return nil, errNotFound
+		}}
+		r := memberReq(jsonReq(t, http.MethodPut, "/", map[string]any{"role": "member"}), memberUUID.String())
+		w := httptest.NewRecorder()
+		newTenantHandler(notFound, nil).UpdateMemberRole(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
 	t.Run("service error returns 400", func(t *testing.T) {
-		ms := &mockTenantMemberService{updateRoleFn: func(uuid.UUID, string) (*service.TenantMemberServiceDataResult, error) {
+		ms := &mockTenantMemberService{updateRoleFn: func(int64, uuid.UUID, string) (*service.TenantMemberServiceDataResult, error) {
 			return nil, errValidation
 		}}
-		r := withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{"role": "owner"}), "tenant_member_uuid", memberUUID.String())
+		r := memberReq(jsonReq(t, http.MethodPut, "/", map[string]any{"role": "owner"}), memberUUID.String())
 		w := httptest.NewRecorder()
-		newTenantHandler(nil, ms).UpdateMemberRole(w, r)
+		newTenantHandler(ts, ms).UpdateMemberRole(w, r)
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
-	t.Run("success returns 200", func(t *testing.T) {
-		ms := &mockTenantMemberService{updateRoleFn: func(id uuid.UUID, role string) (*service.TenantMemberServiceDataResult, error) {
+	t.Run("success is scoped to the path tenant", func(t *testing.T) {
+		ms := &mockTenantMemberService{updateRoleFn: func(tenantID int64, id uuid.UUID, role string) (*service.TenantMemberServiceDataResult, error) {
+			assert.Equal(t, int64(7), tenantID)
+			assert.Equal(t, memberUUID, id)
 			return &service.TenantMemberServiceDataResult{Role: role}, nil
 		}}
-		r := withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{"role": "member"}), "tenant_member_uuid", memberUUID.String())
+		r := memberReq(jsonReq(t, http.MethodPut, "/", map[string]any{"role": "member"}), memberUUID.String())
 		w := httptest.NewRecorder()
-		newTenantHandler(nil, ms).UpdateMemberRole(w, r)
+		newTenantHandler(ts, ms).UpdateMemberRole(w, r)
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 }
 
 func TestTenantHandler_RemoveMember(t *testing.T) {
 	memberUUID := uuid.New()
+	ts := &mockTenantService{getByUUIDFn: func(id uuid.UUID) (*service.TenantServiceDataResult, error) {
+		return &service.TenantServiceDataResult{TenantID: 7, TenantUUID: id}, nil
+	}}
+	memberReq := func(memberParam string) *http.Request {
+		r := withChiParam(httptest.NewRequest(http.MethodDelete, "/", nil), "tenant_uuid", testTenantUUID.String())
+		return withChiParam(r, "tenant_member_uuid", memberParam)
+	}
+
+	t.Run("invalid tenant UUID returns 400", func(t *testing.T) {
+		r := withChiParam(httptest.NewRequest(http.MethodDelete, "/", nil), "tenant_member_uuid", memberUUID.String())
+		w := httptest.NewRecorder()
+		newTenantHandler(nil, nil).RemoveMember(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 
 	t.Run("empty UUID param returns 400", func(t *testing.T) {
-		r := httptest.NewRequest(http.MethodDelete, "/", nil)
+		r := withChiParam(httptest.NewRequest(http.MethodDelete, "/", nil), "tenant_uuid", testTenantUUID.String())
 		w := httptest.NewRecorder()
 		newTenantHandler(nil, nil).RemoveMember(w, r)
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
 	t.Run("invalid UUID format returns 400", func(t *testing.T) {
-		r := withChiParam(httptest.NewRequest(http.MethodDelete, "/", nil), "tenant_member_uuid", "bad")
 		w := httptest.NewRecorder()
-		newTenantHandler(nil, nil).RemoveMember(w, r)
+		newTenantHandler(nil, nil).RemoveMember(w, memberReq("bad"))
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
+	t.Run("tenant not found returns 404", func(t *testing.T) {
+		notFound := &mockTenantService{getByUUIDFn: func(uuid.UUID) (*service.TenantServiceDataResult, error) {
+			return nil, errNotFound
+		}}
+		w := httptest.NewRecorder()
+		newTenantHandler(notFound, nil).RemoveMember(w, memberReq(memberUUID.String()))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
 	t.Run("service error returns 400", func(t *testing.T) {
-		ms := &mockTenantMemberService{deleteByUUIDFn: func(uuid.UUID) error { return errValidation }}
-		r := withChiParam(httptest.NewRequest(http.MethodDelete, "/", nil), "tenant_member_uuid", memberUUID.String())
+		ms := &mockTenantMemberService{deleteByUUIDFn: func(int64, uuid.UUID) error { return errValidation }}
 		w := httptest.NewRecorder()
-		newTenantHandler(nil, ms).RemoveMember(w, r)
+		newTenantHandler(ts, ms).RemoveMember(w, memberReq(memberUUID.String()))
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
-	t.Run("success returns 200", func(t *testing.T) {
-		r := withChiParam(httptest.NewRequest(http.MethodDelete, "/", nil), "tenant_member_uuid", memberUUID.String())
+	t.Run("success is scoped to the path tenant", func(t *testing.T) {
+		ms := &mockTenantMemberService{deleteByUUIDFn: func(tenantID int64, id uuid.UUID) error {
+			assert.Equal(t, int64(7), tenantID)
+			assert.Equal(t, memberUUID, id)
+			return nil
+		}}
 		w := httptest.NewRecorder()
-		newTenantHandler(nil, nil).RemoveMember(w, r)
+		newTenantHandler(ts, ms).RemoveMember(w, memberReq(memberUUID.String()))
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 }
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/ptr"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// TenantUsageHandler handles HTTP requests for tenant usage reports.
+type TenantUsageHandler struct {
+	usageService service.UsageService
+}
+
+// NewTenantUsageHandler creates a new TenantUsageHandler.
+func NewTenantUsageHandler(usageService service.UsageService) *TenantUsageHandler {
+	return &TenantUsageHandler{usageService: usageService}
+}
+
+// GetUsage reports the requests and issued access tokens of a tenant per
+// day and per API key, with its quotas and the live count of the current
+// month. The report covers the current month unless from and to are given.
+//
+// GET /tenants/{tenant_uuid}/usage
+func (h *TenantUsageHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	tenantUUID, err := uuid.Parse(chi.URLParam(r, "tenant_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid tenant UUID")
+		return
+	}
+
+	q := r.URL.Query()
+	filter := dto.TenantUsageFilterDTO{
+		From: ptr.PtrOrNil(q.Get("from")),
+		To:   ptr.PtrOrNil(q.Get("to")),
+	}
+	if err := filter.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	// A lone from or to is checked against its default counterpart here.
+	from, to := tenantUsageRange(filter, time.Now())
+	if to.Before(from) {
+		resp.Error(w, http.StatusBadRequest, "To must not be before From")
+		return
+	}
+	if to.Sub(from) >= dto.TenantUsageMaxDays*24*time.Hour {
+		resp.Error(w, http.StatusBadRequest, "The report cannot cover more than 366 days")
+		return
+	}
+
+	result, err := h.usageService.GetUsage(r.Context(), tenantUUID, from, to)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get tenant usage", err)
+		return
+	}
+
+	resp.Success(w, toTenantUsageResponseDTO(*result), "Tenant usage retrieved successfully")
+}
+
+// tenantUsageRange resolves the days of a validated filter. From defaults to
+// the first day of the month of To, and To to the current day (UTC), so
+// that an empty filter reports the current month.
+func tenantUsageRange(filter dto.TenantUsageFilterDTO, now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if filter.To != nil {
+		to, _ = time.Parse(time.DateOnly, *filter.To)
+	}
+	from := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if filter.From != nil {
+		from, _ = time.Parse(time.DateOnly, *filter.From)
+	}
+	return from, to
+}
+
+func toTenantUsageResponseDTO(r service.UsageServiceReport) dto.TenantUsageResponseDTO {
+	days := make([]dto.TenantUsageDayResponseDTO, len(r.Days))
+	for i, d := range r.Days {
+		days[i] = dto.TenantUsageDayResponseDTO{
+			Date:         dto.Date{Time: d.Date},
+			Requests:     d.Requests,
+			TokensIssued: d.TokensIssued,
+		}
+	}
+
+	apiKeys := make([]dto.TenantUsageAPIKeyResponseDTO, len(r.APIKeys))
+	for i, k := range r.APIKeys {
+		apiKeys[i] = dto.TenantUsageAPIKeyResponseDTO{Requests: k.Requests}
+		if k.APIKeyUUID != nil {
+			apiKeys[i].APIKeyUUID = ptr.Ptr(k.APIKeyUUID.String())
+			apiKeys[i].Name = ptr.Ptr(k.Name)
+		}
+	}
+
+	return dto.TenantUsageResponseDTO{
+		TenantID:     r.TenantUUID.String(),
+		From:         dto.Date{Time: r.From},
+		To:           dto.Date{Time: r.To},
+		Requests:     r.Requests,
+		TokensIssued: r.TokensIssued,
+		Days:         days,
+		APIKeys:      apiKeys,
+		Quotas: dto.TenantUsageQuotasResponseDTO{
+			MonthlyRequests:       quotaOrNil(r.Quotas.MonthlyRequests),
+			MonthlyTokens:         quotaOrNil(r.Quotas.MonthlyTokens),
+			APIKeyMonthlyRequests: quotaOrNil(r.Quotas.APIKeyMonthlyRequests),
+		},
+		CurrentPeriod: dto.TenantUsagePeriodResponseDTO{
+			Start:        r.CurrentPeriod.Start,
+			End:          r.CurrentPeriod.End,
+			Requests:     r.CurrentPeriod.Requests,
+			TokensIssued: r.CurrentPeriod.TokensIssued,
+		},
+	}
+}
+
+// quotaOrNil reports an unlimited (0) quota as null.
+func quotaOrNil(quota int64) *int64 {
+	if quota == 0 {
+		return nil
+	}
+	return &quota
+}
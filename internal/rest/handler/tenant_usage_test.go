@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantUsageHandler_GetUsage_InvalidUUID(t *testing.T) {
+	h := NewTenantUsageHandler(&mockUsageService{})
+	r := withChiParam(httptest.NewRequest(http.MethodGet, "/tenants/bad/usage", nil), "tenant_uuid", "bad")
+	w := httptest.NewRecorder()
+	h.GetUsage(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTenantUsageHandler_GetUsage_InvalidRange(t *testing.T) {
+	h := NewTenantUsageHandler(&mockUsageService{})
+	for _, query := range []string{
+		"from=2026-13-01",
+		"from=2026-02-01&to=2026-01-01",
+		"from=2020-01-01",
+		"from=2999-01-01",
+	} {
+		r := withChiParam(httptest.NewRequest(http.MethodGet, "/?"+query, nil), "tenant_uuid", testResourceUUID.String())
+		w := httptest.NewRecorder()
+		h.GetUsage(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestTenantUsageHandler_GetUsage_ServiceError(t *testing.T) {
+	svc := &mockUsageService{
+		getUsageFn: func(uuid.UUID, time.Time, time.Time) (*service.UsageServiceReport, error) {
+			return nil, errNotFound
+		},
+	}
+	h := NewTenantUsageHandler(svc)
+	r := withChiParam(httptest.NewRequest(http.MethodGet, "/", nil), "tenant_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.GetUsage(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTenantUsageHandler_GetUsage_Success(t *testing.T) {
+	apiKeyUUID := uuid.New()
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc := &mockUsageService{
+		getUsageFn: func(id uuid.UUID, from, to time.Time) (*service.UsageServiceReport, error) {
+			assert.Equal(t, testResourceUUID, id)
+			assert.Equal(t, day, from)
+			assert.Equal(t, day.AddDate(0, 0, 30), to)
+			return &service.UsageServiceReport{
+				TenantUUID: id,
+				From:       from,
+				To:         to,
+				Requests:   7,
+				Days:       []service.UsageServiceDay{{Date: day, Requests: 7}},
+				APIKeys: []service.UsageServiceAPIKey{
+					{APIKeyUUID: &apiKeyUUID, Name: "ci", Requests: 5},
+					{Requests: 2},
+				},
+				Quotas: service.UsageServiceQuotas{MonthlyRequests: 1000},
+			}, nil
+		},
+	}
+	h := NewTenantUsageHandler(svc)
+	r := withChiParam(httptest.NewRequest(http.MethodGet, "/?from=2026-01-01&to=2026-01-31", nil), "tenant_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.GetUsage(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"from":"2026-01-01"`)
+	assert.Contains(t, w.Body.String(), `"days":[{"date":"2026-01-01","requests":7,"tokens_issued":0}]`)
+	assert.Contains(t, w.Body.String(), `{"api_key_id":"`+apiKeyUUID.String()+`","name":"ci","requests":5}`)
+	assert.Contains(t, w.Body.String(), `{"api_key_id":null,"name":null,"requests":2}`)
+	assert.Contains(t, w.Body.String(), `"monthly_request_quota":1000,"monthly_token_quota":null`)
+}
+
+func TestTenantUsageRange(t *testing.T) {
+	now := time.Date(2026, 3, 15, 22, 0, 0, 0, time.UTC)
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC) }
+
+	from, to := tenantUsageRange(dto.TenantUsageFilterDTO{}, now)
+	assert.Equal(t, day(3, 1), from)
+	assert.Equal(t, day(3, 15), to)
+
+	from, to = tenantUsageRange(dto.TenantUsageFilterDTO{To: ptr.Ptr("2026-02-10")}, now)
+	assert.Equal(t, day(2, 1), from)
+	assert.Equal(t, day(2, 10), to)
+
+	from, to = tenantUsageRange(dto.TenantUsageFilterDTO{From: ptr.Ptr("2026-01-05")}, now)
+	assert.Equal(t, day(1, 5), from)
+	assert.Equal(t, day(3, 15), to)
+}
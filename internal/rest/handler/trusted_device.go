@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/cookie"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// TrustedDeviceHandler handles the self-service trusted device endpoints
+// (list, trust and forget the caller's remembered devices).
+type TrustedDeviceHandler struct {
+	trustedDeviceService service.TrustedDeviceService
+}
+
+// NewTrustedDeviceHandler creates a new TrustedDeviceHandler.
+func NewTrustedDeviceHandler(trustedDeviceService service.TrustedDeviceService) *TrustedDeviceHandler {
+	return &TrustedDeviceHandler{trustedDeviceService: trustedDeviceService}
+}
+
+// List handles GET /account/trusted-devices. Returns the authenticated
+// user's active trusted devices.
+func (h *TrustedDeviceHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	devices, err := h.trustedDeviceService.List(r.Context(), user.UserID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to retrieve trusted devices", err)
+		return
+	}
+
+	resp.Success(w, devices, "Trusted devices retrieved successfully")
+}
+
+// Trust handles POST /account/trusted-devices. It remembers the calling
+// device and sets the trusted_device cookie; the token is also returned for
+// clients that send it in the X-Trusted-Device header instead.
+func (h *TrustedDeviceHandler) Trust(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	// The body is optional; an empty one trusts an unnamed device.
+	var req dto.TrustedDeviceCreateRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		resp.Error(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	device, err := h.trustedDeviceService.Trust(r.Context(), auth.User, auth.Tenant.TenantID, req)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to trust device", err)
+		return
+	}
+
+	cookie.SetTrustedDeviceCookie(w, device.Token, device.ExpiresAt)
+	resp.Created(w, device, "Device trusted successfully")
+}
+
+// Revoke handles DELETE /account/trusted-devices/{trusted_device_uuid}.
+func (h *TrustedDeviceHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	deviceUUID, err := uuid.Parse(chi.URLParam(r, "trusted_device_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid trusted device UUID")
+		return
+	}
+
+	if err := h.trustedDeviceService.Revoke(r.Context(), deviceUUID, user.UserID); err != nil {
+		resp.HandleServiceError(w, r, "Failed to revoke trusted device", err)
+		return
+	}
+
+	resp.Success(w, nil, "Trusted device revoked successfully")
+}
+
+// RevokeAll handles DELETE /account/trusted-devices. Forgets every device
+// of the authenticated user.
+func (h *TrustedDeviceHandler) RevokeAll(w http.ResponseWriter, r *http.Request) {
+	user := middleware.AuthFromRequest(r).User
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	revoked, err := h.trustedDeviceService.RevokeAll(r.Context(), user.UserID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to revoke trusted devices", err)
+		return
+	}
+
+	resp.Success(w, map[string]int64{"revoked": revoked}, "Trusted devices revoked successfully")
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// List
+// ---------------------------------------------------------------------------
+
+func TestTrustedDeviceHandler_List(t *testing.T) {
+	t.Run("no user", func(t *testing.T) {
+		h := NewTrustedDeviceHandler(&mockTrustedDeviceService{})
+		w := httptest.NewRecorder()
+		h.List(w, httptest.NewRequest(http.MethodGet, "/account/trusted-devices", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewTrustedDeviceHandler(&mockTrustedDeviceService{
+			listFn: func(context.Context, int64) ([]dto.TrustedDeviceResponseDTO, error) {
+				return nil, errNotFound
+			},
+		})
+		w := httptest.NewRecorder()
+		h.List(w, withUser(httptest.NewRequest(http.MethodGet, "/account/trusted-devices", nil)))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewTrustedDeviceHandler(&mockTrustedDeviceService{
+			listFn: func(context.Context, int64) ([]dto.TrustedDeviceResponseDTO, error) {
+				return []dto.TrustedDeviceResponseDTO{{Name: "laptop", Current: true}}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.List(w, withUser(httptest.NewRequest(http.MethodGet, "/account/trusted-devices", nil)))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "laptop")
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Trust
+// ---------------------------------------------------------------------------
+
+func TestTrustedDeviceHandler_Trust(t *testing.T) {
+	t.Run("no user", func(t *testing.T) {
+		h := NewTrustedDeviceHandler(&mockTrustedDeviceService{})
+		w := httptest.NewRecorder()
+		h.Trust(w, httptest.NewRequest(http.MethodPost, "/account/trusted-devices", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("no tenant", func(t *testing.T) {
+		h := NewTrustedDeviceHandler(&mockTrustedDeviceService{})
+		w := httptest.NewRecorder()
+		h.Trust(w, withUser(httptest.NewRequest(http.MethodPost, "/account/trusted-devices", nil)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("bad json", func(t *testing.T) {
+		h := NewTrustedDeviceHandler(&mockTrustedDeviceService{})
+		w := httptest.NewRecorder()
+		h.Trust(w, withTenantAndUser(badJSONReq(t, http.MethodPost, "/account/trusted-devices")))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		h := NewTrustedDeviceHandler(&mockTrustedDeviceService{})
+		w := httptest.NewRecorder()
+		h.Trust(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/account/trusted-devices",
+			dto.TrustedDeviceCreateRequestDTO{Name: strings.Repeat("x", 101)})))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		h := NewTrustedDeviceHandler(&mockTrustedDeviceService{
+			trustFn: func(context.Context, *model.User, int64, dto.TrustedDeviceCreateRequestDTO) (*dto.TrustedDeviceCreateResponseDTO, error) {
+				return nil, errValidation
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Trust(w, withTenantAndUser(httptest.NewRequest(http.MethodPost, "/account/trusted-devices", nil)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, w.Result().Cookies())
+	})
+
+	t.Run("success sets the device cookie", func(t *testing.T) {
+		expiresAt := time.Now().Add(30 * 24 * time.Hour)
+		h := NewTrustedDeviceHandler(&mockTrustedDeviceService{
+			trustFn: func(_ context.Context, user *model.User, tid int64, req dto.TrustedDeviceCreateRequestDTO) (*dto.TrustedDeviceCreateResponseDTO, error) {
+				assert.Equal(t, testUserUUID, user.UserUUID)
+				assert.Equal(t, tenantID, tid)
+				assert.Equal(t, "laptop", req.Name)
+				return &dto.TrustedDeviceCreateResponseDTO{
+					TrustedDeviceResponseDTO: dto.TrustedDeviceResponseDTO{Name: "laptop", ExpiresAt: expiresAt},
+					Token:                    "td_secret",
+				}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.Trust(w, withTenantAndUser(jsonReq(t, http.MethodPost, "/account/trusted-devices",
+			dto.TrustedDeviceCreateRequestDTO{Name: "laptop"})))
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var body struct {
+			Data dto.TrustedDeviceCreateResponseDTO `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		assert.Equal(t, "td_secret", body.Data.Token)
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, "trusted_device", cookies[0].Name)
+		assert.Equal(t, "td_secret", cookies[0].Value)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Revoke
+// ---------------------------------------------------------------------------
+
+func TestTrustedDeviceHandler_Revoke(t *testing.T) {
+	deviceUUID := uuid.New()
+
+	t.Run("no user", func(t *testing.T) {
+		h := NewTrustedDeviceHandler(&mockTrustedDeviceService{})
+		w := httptest.NewRecorder()
+		h.Revoke(w, httptest.NewRequest(http.MethodDelete, "/account/trusted-devices/x", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid uuid", func(t *testing.T) {
+		h := NewTrustedDeviceHandler(&mockTrustedDeviceService{})
+		w := httptest.NewRecorder()
+		r := withChiParam(withUser(httptest.NewRequest(http.MethodDelete, "/account/trusted-devices/x", nil)), "trusted_device_uuid", "x")
+		h.Revoke(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		h := NewTrustedDeviceHandler(&mockTrustedDeviceService{
+			revokeFn: func(context.Context, uuid.UUID, int64) error { return errNotFound },
+		})
+		w := httptest.NewRecorder()
+		r := withChiParam(withUser(httptest.NewRequest(http.MethodDelete, "/", nil)), "trusted_device_uuid", deviceUUID.String())
+		h.Revoke(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewTrustedDeviceHandler(&mockTrustedDeviceService{
+			revokeFn: func(_ context.Context, id uuid.UUID, _ int64) error {
+				assert.Equal(t, deviceUUID, id)
+				return nil
+			},
+		})
+		w := httptest.NewRecorder()
+		r := withChiParam(withUser(httptest.NewRequest(http.MethodDelete, "/", nil)), "trusted_device_uuid", deviceUUID.String())
+		h.Revoke(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// RevokeAll
+// ---------------------------------------------------------------------------
+
+func TestTrustedDeviceHandler_RevokeAll(t *testing.T) {
+	t.Run("no user", func(t *testing.T) {
+		h := NewTrustedDeviceHandler(&mockTrustedDeviceService{})
+		w := httptest.NewRecorder()
+		h.RevokeAll(w, httptest.NewRequest(http.MethodDelete, "/account/trusted-devices", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewTrustedDeviceHandler(&mockTrustedDeviceService{
+			revokeAllFn: func(context.Context, int64) (int64, error) { return 2, nil },
+		})
+		w := httptest.NewRecorder()
+		h.RevokeAll(w, withUser(httptest.NewRequest(http.MethodDelete, "/account/trusted-devices", nil)))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"revoked":2`)
+	})
+}
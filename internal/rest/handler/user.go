@@ -3,14 +3,13 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
-	"sort"
 	"strconv"
-	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/dto"
 	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/ptr"
 	resp "github.com/maintainerd/auth/internal/rest/response"
 	"github.com/maintainerd/auth/internal/service"
@@ -40,6 +39,9 @@ func NewUserHandler(userService service.UserService) *UserHandler {
 //
 // Returns a paginated list of users belonging to the authenticated tenant.
 // Supports filtering by username, email, phone, status, and role UUID.
+// include=identities,roles adds those relations to every row, loaded for
+// the whole page at once. include_subtenants=true also lists the users of
+// the tenants below the caller's tenant.
 func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
 	// Get tenant from context (middleware already validated access)
 	tenant := middleware.AuthFromRequest(r).Tenant
@@ -55,11 +57,8 @@ func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
 	page, _ := strconv.Atoi(q.Get("page"))
 	limit, _ := strconv.Atoi(q.Get("limit"))
 
-	// Parse status filter
-	var status []string
-	if v := q.Get("status"); v != "" {
-		status = append(status, v)
-	}
+	// Parse status filter (comma-separated or repeated values)
+	status := queryValues(q, "status")
 
 	// Parse role UUID filter
 	var roleUUID *string
@@ -79,21 +78,27 @@ func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
 		clientUUID = &v
 	}
 
+	// Parse subtree search flag
+	includeSubtenants, _ := strconv.ParseBool(q.Get("include_subtenants"))
+
 	// Build filter DTO for validation
 	reqParams := dto.UserFilterDTO{
-		Username:     ptr.PtrOrNil(q.Get("username")),
-		Email:        ptr.PtrOrNil(q.Get("email")),
-		Phone:        ptr.PtrOrNil(q.Get("phone")),
-		Status:       status,
-		RoleUUID:     roleUUID,
-		UserPoolUUID: userPoolUUID,
-		ClientUUID:   clientUUID,
+		Username:          ptr.PtrOrNil(q.Get("username")),
+		Email:             ptr.PtrOrNil(q.Get("email")),
+		Phone:             ptr.PtrOrNil(q.Get("phone")),
+		Status:            status,
+		RoleUUID:          roleUUID,
+		UserPoolUUID:      userPoolUUID,
+		ClientUUID:        clientUUID,
+		Include:           queryValues(q, "include"),
+		IncludeSubtenants: includeSubtenants,
 		PaginationRequestDTO: dto.PaginationRequestDTO{
 			Page:      page,
 			Limit:     limit,
 			SortBy:    q.Get("sort_by"),
 			SortOrder: q.Get("sort_order"),
 		},
+		Cursor: cursorPagination(q),
 	}
 
 	// Validate filter parameters
@@ -116,6 +121,17 @@ func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
 		Limit:        reqParams.PaginationRequestDTO.Limit,
 		SortBy:       reqParams.PaginationRequestDTO.SortBy,
 		SortOrder:    reqParams.PaginationRequestDTO.SortOrder,
+
+		IncludeSubtenants: reqParams.IncludeSubtenants,
+		IncludeIdentities: reqParams.Includes(dto.UserIncludeIdentities),
+		IncludeRoles:      reqParams.Includes(dto.UserIncludeRoles),
+	}
+
+	if reqParams.Cursor != nil {
+		filter.Limit = reqParams.Cursor.Limit
+		filter.SortOrder = reqParams.Cursor.SortOrder
+		h.getUsersByCursor(w, r, filter, reqParams.Cursor.Cursor)
+		return
 	}
 
 	// Fetch users from service layer
@@ -128,7 +144,7 @@ func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
 	// Map service results to DTOs
 	rows := make([]dto.UserResponseDTO, len(result.Data))
 	for i, r := range result.Data {
-		rows[i] = toUserResponseDTO(r)
+		rows[i] = toUserListResponseDTO(r)
 	}
 
 	// Build paginated response
@@ -143,6 +159,220 @@ func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
 	resp.Success(w, response, "Users fetched successfully")
 }
 
+// getUsersByCursor answers GetUsers when it pages with a cursor.
+func (h *UserHandler) getUsersByCursor(w http.ResponseWriter, r *http.Request, filter service.UserServiceGetFilter, cursor string) {
+	result, err := h.userService.GetByCursor(r.Context(), filter, cursor)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to fetch users", err)
+		return
+	}
+
+	rows := make([]dto.UserResponseDTO, len(result.Data))
+	for i, u := range result.Data {
+		rows[i] = toUserListResponseDTO(u)
+	}
+
+	resp.Success(w, dto.CursorPaginatedResponseDTO[dto.UserResponseDTO]{
+		Rows:       rows,
+		Limit:      result.Limit,
+		NextCursor: result.NextCursor,
+		HasMore:    result.HasMore,
+	}, "Users fetched successfully")
+}
+
+// SearchUsers finds the tenant's users fuzzily matching a query.
+//
+// GET /users/search
+//
+// q is matched against username, full name, email and phone, tolerating
+// typos and partial words, and rows come back best match first. status,
+// include and include_subtenants work as on GET /users.
+func (h *UserHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	includeSubtenants, _ := strconv.ParseBool(q.Get("include_subtenants"))
+
+	reqParams := dto.UserSearchDTO{
+		Query:             q.Get("q"),
+		Status:            queryValues(q, "status"),
+		Include:           queryValues(q, "include"),
+		IncludeSubtenants: includeSubtenants,
+		Page:              page,
+		Limit:             limit,
+	}
+	if err := reqParams.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.userService.Search(r.Context(), reqParams.Query, service.UserServiceGetFilter{
+		Status:            reqParams.Status,
+		TenantID:          tenant.TenantID,
+		Page:              reqParams.Page,
+		Limit:             reqParams.Limit,
+		IncludeSubtenants: reqParams.IncludeSubtenants,
+		IncludeIdentities: reqParams.Includes(dto.UserIncludeIdentities),
+		IncludeRoles:      reqParams.Includes(dto.UserIncludeRoles),
+	})
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to search users", err)
+		return
+	}
+
+	rows := make([]dto.UserResponseDTO, len(result.Data))
+	for i, u := range result.Data {
+		rows[i] = toUserListResponseDTO(u)
+	}
+
+	resp.Success(w, dto.PaginatedResponseDTO[dto.UserResponseDTO]{
+		Rows:       rows,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}, "Users fetched successfully")
+}
+
+// GetUserDelta returns the users created, updated or deleted since a
+// cursor, including tombstones for deleted users.
+//
+// GET /users/delta
+//
+// Directory consumers store next_cursor and pass it back as since to pull
+// only what changed instead of re-exporting every user.
+func (h *UserHandler) GetUserDelta(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	q := r.URL.Query()
+	limit := dto.UserDeltaDefaultLimit
+	if raw := q.Get("limit"); raw != "" {
+		limit, _ = strconv.Atoi(raw)
+	}
+
+	filter := dto.UserDeltaFilterDTO{
+		Since: q.Get("since"),
+		Limit: limit,
+	}
+
+	if err := filter.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.userService.Delta(r.Context(), service.UserServiceDeltaFilter{
+		TenantID: tenant.TenantID,
+		Since:    filter.Since,
+		Limit:    filter.Limit,
+	})
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get user changes", err)
+		return
+	}
+
+	users := make([]dto.UserDeltaEntryDTO, len(result.Data))
+	for i, e := range result.Data {
+		users[i] = dto.UserDeltaEntryDTO{
+			UserUUID:  e.UserUUID,
+			Deleted:   e.Deleted,
+			DeletedAt: e.DeletedAt,
+		}
+		if e.User != nil {
+			u := toUserResponseDTO(*e.User)
+			users[i].User = &u
+		}
+	}
+
+	resp.Success(w, dto.UserDeltaResponseDTO{
+		Users:      users,
+		NextCursor: result.NextCursor,
+		HasMore:    result.HasMore,
+	}, "User changes retrieved successfully")
+}
+
+// ExportUsers streams every user matching the list filters as a CSV or
+// NDJSON download.
+//
+// GET /users/export
+//
+// Takes the filters of GetUsers without pagination, ?format=csv|ndjson
+// (default csv) and ?columns= to select and order the exported columns.
+// Users are read and written in chunks, so exports of any size use constant
+// memory.
+func (h *UserHandler) ExportUsers(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	q := r.URL.Query()
+	format := q.Get("format")
+	if format == "" {
+		format = dto.UserExportFormatCSV
+	}
+
+	reqParams := dto.UserExportFilterDTO{
+		Username:     ptr.PtrOrNil(q.Get("username")),
+		Email:        ptr.PtrOrNil(q.Get("email")),
+		Phone:        ptr.PtrOrNil(q.Get("phone")),
+		Status:       queryValues(q, "status"),
+		RoleUUID:     ptr.PtrOrNil(q.Get("role_id")),
+		UserPoolUUID: ptr.PtrOrNil(q.Get("user_pool_id")),
+		ClientUUID:   ptr.PtrOrNil(q.Get("client_id")),
+		Format:       format,
+		Columns:      queryValues(q, "columns"),
+	}
+
+	if err := reqParams.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	columns := reqParams.Columns
+	if len(columns) == 0 {
+		columns = dto.UserExportColumns
+	}
+
+	filter := service.UserServiceGetFilter{
+		Username:     reqParams.Username,
+		Email:        reqParams.Email,
+		Phone:        reqParams.Phone,
+		Status:       reqParams.Status,
+		TenantID:     tenant.TenantID,
+		RoleUUID:     reqParams.RoleUUID,
+		UserPoolUUID: reqParams.UserPoolUUID,
+		ClientUUID:   reqParams.ClientUUID,
+	}
+
+	enc := newUserExportEncoder(w, reqParams.Format, columns)
+	err := h.userService.Export(r.Context(), filter, enc.write)
+	if err == nil {
+		err = enc.close()
+	}
+	if err != nil {
+		if !enc.started {
+			resp.HandleServiceError(w, r, "Failed to export users", err)
+			return
+		}
+		// The status line has been sent, so the error cannot be reported.
+		// Abort the connection so the client sees a failed download rather
+		// than a complete-looking truncated file.
+		resp.LoggerFromContext(r.Context()).Error("user export aborted", "error", err, "exported", enc.count)
+		panic(http.ErrAbortHandler)
+	}
+}
+
 // GetUser retrieves a specific user by UUID.
 //
 // GET /users/{user_uuid}
@@ -175,6 +405,7 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	// Map to response DTO
 	dtoRes := toUserResponseDTO(*user)
 
+	w.Header().Set("ETag", middleware.ETag(user.UpdatedAt))
 	resp.Success(w, dtoRes, "User fetched successfully")
 }
 
@@ -194,6 +425,10 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 
 	// Get creator user from context (needed for audit trail)
 	creatorUser := middleware.AuthFromRequest(r).User
+	if creatorUser == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	// Decode and validate request body
 	var req dto.UserCreateRequestDTO
@@ -236,6 +471,10 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 
 	// Get updater user from context (needed for audit trail)
 	updaterUser := middleware.AuthFromRequest(r).User
+	if updaterUser == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	// Parse and validate user UUID from URL parameter
 	userUUIDStr := chi.URLParam(r, "user_uuid")
@@ -267,6 +506,7 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	// Map to response DTO
 	dtoRes := toUserResponseDTO(*user)
 
+	w.Header().Set("ETag", middleware.ETag(user.UpdatedAt))
 	resp.Success(w, dtoRes, "User updated successfully")
 }
 
@@ -286,6 +526,10 @@ func (h *UserHandler) SetUserStatus(w http.ResponseWriter, r *http.Request) {
 
 	// Get updater user from context (needed for audit trail)
 	updaterUser := middleware.AuthFromRequest(r).User
+	if updaterUser == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	// Parse and validate user UUID from URL parameter
 	userUUIDStr := chi.URLParam(r, "user_uuid")
@@ -423,8 +667,11 @@ func (h *UserHandler) CompleteAccount(w http.ResponseWriter, r *http.Request) {
 //
 // DELETE /users/{user_uuid}
 //
-// Permanently deletes a user account from the tenant. The service layer
-// validates tenant ownership. The deleter's context is used for audit tracking.
+// Soft-deletes a user account. The account can be restored until the
+// tenant's retention period elapses, after which the purge job erases it.
+// The service layer validates tenant ownership. The deleter's context is
+// used for audit tracking. With ?dry_run=true the impact is reported and
+// nothing is deleted.
 func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	// Get tenant from context (middleware already validated access)
 	tenant := middleware.AuthFromRequest(r).Tenant
@@ -435,6 +682,10 @@ func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 
 	// Get deleter user from context (needed for audit trail)
 	deleterUser := middleware.AuthFromRequest(r).User
+	if deleterUser == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
 
 	// Parse and validate user UUID from URL parameter
 	userUUIDStr := chi.URLParam(r, "user_uuid")
@@ -444,6 +695,22 @@ func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	dryRun, err := dryRunRequested(r.URL.Query())
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid dry_run value")
+		return
+	}
+
+	if dryRun {
+		report, err := h.userService.PreviewDeleteByUUID(r.Context(), userUUID, tenant.TenantID, deleterUser.UserUUID)
+		if err != nil {
+			resp.HandleServiceError(w, r, "Failed to preview user delete", err)
+			return
+		}
+		resp.Success(w, toDeleteImpactResponseDTO(report), "Dry run completed, nothing was deleted")
+		return
+	}
+
 	// Delete user (service validates tenant ownership, includes deleter context for audit)
 	user, err := h.userService.DeleteByUUID(r.Context(), userUUID, tenant.TenantID, deleterUser.UserUUID)
 	if err != nil {
@@ -457,6 +724,119 @@ func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	resp.Success(w, dtoRes, "User deleted successfully")
 }
 
+// RestoreUser restores a soft-deleted user.
+//
+// POST /users/{user_uuid}/restore
+//
+// Reactivates a user deleted within the tenant's retention period.
+func (h *UserHandler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid user UUID")
+		return
+	}
+
+	user, err := h.userService.RestoreByUUID(r.Context(), userUUID, auth.Tenant.TenantID, auth.User.UserUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to restore user", err)
+		return
+	}
+
+	resp.Success(w, toUserResponseDTO(*user), "User restored successfully")
+}
+
+// EnableUser reactivates a disabled, inactive or suspended user.
+//
+// POST /users/{user_uuid}/enable
+//
+// Sets the user's status to active, including for accounts their owner
+// disabled. The user's pending reactivation link stops working. For an
+// account on security hold this records the admin review that clears it.
+func (h *UserHandler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid user UUID")
+		return
+	}
+
+	user, err := h.userService.SetStatus(r.Context(), userUUID, auth.Tenant.TenantID, model.StatusActive, auth.User.UserUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to enable user", err)
+		return
+	}
+
+	resp.Success(w, toUserResponseDTO(*user), "User enabled successfully")
+}
+
+// PurgeUser permanently erases a user.
+//
+// DELETE /users/{user_uuid}/purge
+//
+// Hard-deletes the user and all of its identities, roles and tokens without
+// waiting for the retention period. This cannot be undone. With
+// ?dry_run=true the rows that would be erased are reported instead.
+func (h *UserHandler) PurgeUser(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid user UUID")
+		return
+	}
+
+	dryRun, err := dryRunRequested(r.URL.Query())
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid dry_run value")
+		return
+	}
+
+	if dryRun {
+		report, err := h.userService.PreviewPurgeByUUID(r.Context(), userUUID, auth.Tenant.TenantID, auth.User.UserUUID)
+		if err != nil {
+			resp.HandleServiceError(w, r, "Failed to preview user purge", err)
+			return
+		}
+		resp.Success(w, toDeleteImpactResponseDTO(report), "Dry run completed, nothing was purged")
+		return
+	}
+
+	user, err := h.userService.PurgeByUUID(r.Context(), userUUID, auth.Tenant.TenantID, auth.User.UserUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to purge user", err)
+		return
+	}
+
+	resp.Success(w, toUserResponseDTO(*user), "User purged successfully")
+}
+
 // AssignRoles assigns roles to a user.
 //
 // POST /users/{user_uuid}/roles
@@ -565,6 +945,7 @@ func toUserResponseDTO(u service.UserServiceDataResult) dto.UserResponseDTO {
 		Metadata:           u.Metadata,
 		CreatedAt:          u.CreatedAt,
 		UpdatedAt:          u.UpdatedAt,
+		DeletedAt:          u.DeletedAt,
 	}
 
 	// Map Tenant if present
@@ -586,12 +967,63 @@ func toUserResponseDTO(u service.UserServiceDataResult) dto.UserResponseDTO {
 	return result
 }
 
+// toUserListResponseDTO converts a user list row, adding the identities and
+// roles that were included.
+func toUserListResponseDTO(u service.UserServiceDataResult) dto.UserResponseDTO {
+	result := toUserResponseDTO(u)
+
+	if u.UserIdentities != nil {
+		identities := make([]dto.UserIdentityResponseDTO, len(*u.UserIdentities))
+		for i, identity := range *u.UserIdentities {
+			identities[i] = toUserIdentityResponseDTO(identity)
+		}
+		result.Identities = &identities
+	}
+
+	if u.Roles != nil {
+		roles := make([]dto.RoleResponseDTO, len(*u.Roles))
+		for i, role := range *u.Roles {
+			roles[i] = toRoleResponseDTO(role)
+		}
+		result.Roles = &roles
+	}
+
+	return result
+}
+
+// toUserIdentityResponseDTO converts a service identity to a response DTO.
+func toUserIdentityResponseDTO(identity service.UserIdentityServiceDataResult) dto.UserIdentityResponseDTO {
+	result := dto.UserIdentityResponseDTO{
+		UserIdentityUUID: identity.UserIdentityUUID,
+		Provider:         identity.Provider,
+		Sub:              identity.Sub,
+		Metadata:         identity.Metadata,
+		CreatedAt:        identity.CreatedAt,
+		UpdatedAt:        identity.UpdatedAt,
+	}
+	if identity.Client != nil {
+		result.Client = &dto.ClientResponseDTO{
+			ClientUUID:  identity.Client.ClientUUID,
+			Name:        identity.Client.Name,
+			DisplayName: identity.Client.DisplayName,
+			ClientType:  identity.Client.ClientType,
+			Domain:      identity.Client.Domain,
+			Status:      identity.Client.Status,
+			IsDefault:   identity.Client.IsDefault,
+			IsSystem:    identity.Client.IsSystem,
+			CreatedAt:   identity.Client.CreatedAt,
+			UpdatedAt:   identity.Client.UpdatedAt,
+		}
+	}
+	return result
+}
+
 // GetUserRoles retrieves all roles assigned to a user with pagination and filters.
 //
 // GET /users/{user_uuid}/roles
 //
-// Returns a paginated list of roles assigned to the user. Supports filtering
-// by role name, description, and status.
+// Returns a paginated list of the roles the user holds in the tenant. Supports
+// filtering by role name, description, and status, applied by the database.
 func (h *UserHandler) GetUserRoles(w http.ResponseWriter, r *http.Request) {
 	// Parse and validate user UUID from URL parameter
 	userUUIDStr := chi.URLParam(r, "user_uuid")
@@ -641,69 +1073,35 @@ func (h *UserHandler) GetUserRoles(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Fetch roles for the user
-	roles, err := h.userService.GetUserRoles(r.Context(), user.UserUUID)
+	// Fetch a page of the user's roles in the tenant
+	result, err := h.userService.ListUserRoles(r.Context(), service.UserServiceGetRolesFilter{
+		UserUUID:    user.UserUUID,
+		TenantID:    tenant.TenantID,
+		Name:        reqParams.Name,
+		Description: reqParams.Description,
+		Status:      reqParams.Status,
+		Page:        reqParams.Page,
+		Limit:       reqParams.Limit,
+		SortBy:      reqParams.SortBy,
+		SortOrder:   reqParams.SortOrder,
+	})
 	if err != nil {
 		resp.HandleServiceError(w, r, "Failed to fetch user roles", err)
 		return
 	}
 
-	// Apply filters
-	filteredRoles := []service.RoleServiceDataResult{}
-	for _, role := range roles {
-		// Filter by name
-		if reqParams.Name != nil && !containsIgnoreCase(role.Name, *reqParams.Name) {
-			continue
-		}
-		// Filter by description
-		if reqParams.Description != nil && !containsIgnoreCase(role.Description, *reqParams.Description) {
-			continue
-		}
-		// Filter by status
-		if reqParams.Status != nil && role.Status != *reqParams.Status {
-			continue
-		}
-		filteredRoles = append(filteredRoles, role)
-	}
-
-	// Apply sorting
-	if reqParams.SortBy != "" {
-		sortRoles(filteredRoles, reqParams.SortBy, reqParams.SortOrder)
-	}
-
-	// Apply pagination
-	total := int64(len(filteredRoles))
-	offset := (reqParams.Page - 1) * reqParams.Limit
-	end := offset + reqParams.Limit
-	if end > len(filteredRoles) {
-		end = len(filteredRoles)
-	}
-	if offset > len(filteredRoles) {
-		offset = len(filteredRoles)
-	}
-	paginatedRoles := filteredRoles[offset:end]
-
 	// Map to DTOs
-	rows := make([]dto.RoleResponseDTO, len(paginatedRoles))
-	for i, role := range paginatedRoles {
-		rows[i] = dto.RoleResponseDTO{
-			RoleUUID:    role.RoleUUID,
-			Name:        role.Name,
-			Description: role.Description,
-			IsDefault:   role.IsDefault,
-			IsSystem:    role.IsSystem,
-			CreatedAt:   role.CreatedAt,
-			UpdatedAt:   role.UpdatedAt,
-		}
+	rows := make([]dto.RoleResponseDTO, len(result.Data))
+	for i, role := range result.Data {
+		rows[i] = toRoleResponseDTO(role)
 	}
 
-	totalPages := int((total + int64(reqParams.Limit) - 1) / int64(reqParams.Limit))
 	response := dto.PaginatedResponseDTO[dto.RoleResponseDTO]{
 		Rows:       rows,
-		Total:      total,
-		Page:       reqParams.Page,
-		Limit:      reqParams.Limit,
-		TotalPages: totalPages,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
 	}
 
 	resp.Success(w, response, "User roles fetched successfully")
@@ -713,8 +1111,9 @@ func (h *UserHandler) GetUserRoles(w http.ResponseWriter, r *http.Request) {
 //
 // GET /users/{user_uuid}/identities
 //
-// Returns a paginated list of identity providers linked to the user (e.g., Google, GitHub).
-// Supports filtering by provider type.
+// Returns a paginated list of identity providers linked to the user in the
+// tenant (e.g., Google, GitHub). Supports filtering by provider type, applied
+// by the database.
 func (h *UserHandler) GetUserIdentities(w http.ResponseWriter, r *http.Request) {
 	// Parse and validate user UUID from URL parameter
 	userUUIDStr := chi.URLParam(r, "user_uuid")
@@ -762,128 +1161,34 @@ func (h *UserHandler) GetUserIdentities(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Fetch identities for the user
-	identities, err := h.userService.GetUserIdentities(r.Context(), user.UserUUID)
+	// Fetch a page of the user's identities in the tenant
+	result, err := h.userService.ListUserIdentities(r.Context(), service.UserServiceGetIdentitiesFilter{
+		UserUUID:  user.UserUUID,
+		TenantID:  tenant.TenantID,
+		Provider:  reqParams.Provider,
+		Page:      reqParams.Page,
+		Limit:     reqParams.Limit,
+		SortBy:    reqParams.SortBy,
+		SortOrder: reqParams.SortOrder,
+	})
 	if err != nil {
 		resp.HandleServiceError(w, r, "Failed to fetch user identities", err)
 		return
 	}
 
-	// Apply filters
-	filteredIdentities := []service.UserIdentityServiceDataResult{}
-	for _, identity := range identities {
-		// Filter by provider
-		if reqParams.Provider != nil && !containsIgnoreCase(identity.Provider, *reqParams.Provider) {
-			continue
-		}
-		filteredIdentities = append(filteredIdentities, identity)
-	}
-
-	// Apply sorting
-	if reqParams.SortBy != "" {
-		sortIdentities(filteredIdentities, reqParams.SortBy, reqParams.SortOrder)
-	}
-
-	// Apply pagination
-	total := int64(len(filteredIdentities))
-	offset := (reqParams.Page - 1) * reqParams.Limit
-	end := offset + reqParams.Limit
-	if end > len(filteredIdentities) {
-		end = len(filteredIdentities)
-	}
-	if offset > len(filteredIdentities) {
-		offset = len(filteredIdentities)
-	}
-	paginatedIdentities := filteredIdentities[offset:end]
-
 	// Map to DTOs
-	rows := make([]dto.UserIdentityResponseDTO, len(paginatedIdentities))
-	for i, identity := range paginatedIdentities {
-		rows[i] = dto.UserIdentityResponseDTO{
-			UserIdentityUUID: identity.UserIdentityUUID,
-			Provider:         identity.Provider,
-			Sub:              identity.Sub,
-			Metadata:         identity.Metadata,
-			CreatedAt:        identity.CreatedAt,
-			UpdatedAt:        identity.UpdatedAt,
-		}
-		if identity.Client != nil {
-			rows[i].Client = &dto.ClientResponseDTO{
-				ClientUUID:  identity.Client.ClientUUID,
-				Name:        identity.Client.Name,
-				DisplayName: identity.Client.DisplayName,
-				ClientType:  identity.Client.ClientType,
-				Domain:      identity.Client.Domain,
-				Status:      identity.Client.Status,
-				IsDefault:   identity.Client.IsDefault,
-				IsSystem:    identity.Client.IsSystem,
-				CreatedAt:   identity.Client.CreatedAt,
-				UpdatedAt:   identity.Client.UpdatedAt,
-			}
-		}
+	rows := make([]dto.UserIdentityResponseDTO, len(result.Data))
+	for i, identity := range result.Data {
+		rows[i] = toUserIdentityResponseDTO(identity)
 	}
 
-	totalPages := int((total + int64(reqParams.Limit) - 1) / int64(reqParams.Limit))
 	response := dto.PaginatedResponseDTO[dto.UserIdentityResponseDTO]{
 		Rows:       rows,
-		Total:      total,
-		Page:       reqParams.Page,
-		Limit:      reqParams.Limit,
-		TotalPages: totalPages,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
 	}
 
 	resp.Success(w, response, "User identities fetched successfully")
 }
-
-// Helper function for case-insensitive contains check
-func containsIgnoreCase(str, substr string) bool {
-	return strings.Contains(strings.ToLower(str), strings.ToLower(substr))
-}
-
-// Helper function to sort roles
-func sortRoles(roles []service.RoleServiceDataResult, sortBy, sortOrder string) {
-	sort.Slice(roles, func(i, j int) bool {
-		var result bool
-		switch sortBy {
-		case "name":
-			result = roles[i].Name < roles[j].Name
-		case "description":
-			result = roles[i].Description < roles[j].Description
-		case "status":
-			result = roles[i].Status < roles[j].Status
-		case "created_at":
-			result = roles[i].CreatedAt.Before(roles[j].CreatedAt)
-		case "updated_at":
-			result = roles[i].UpdatedAt.Before(roles[j].UpdatedAt)
-		default:
-			result = roles[i].CreatedAt.After(roles[j].CreatedAt) // Default sort by created_at DESC
-		}
-		if sortOrder == "desc" {
-			return !result
-		}
-		return result
-	})
-}
-
-// Helper function to sort identities
-func sortIdentities(identities []service.UserIdentityServiceDataResult, sortBy, sortOrder string) {
-	sort.Slice(identities, func(i, j int) bool {
-		var result bool
-		switch sortBy {
-		case "provider":
-			result = identities[i].Provider < identities[j].Provider
-		case "sub":
-			result = identities[i].Sub < identities[j].Sub
-		case "created_at":
-			result = identities[i].CreatedAt.Before(identities[j].CreatedAt)
-		case "updated_at":
-			result = identities[i].UpdatedAt.Before(identities[j].UpdatedAt)
-		default:
-			result = identities[i].CreatedAt.After(identities[j].CreatedAt) // Default sort by created_at DESC
-		}
-		if sortOrder == "desc" {
-			return !result
-		}
-		return result
-	})
-}
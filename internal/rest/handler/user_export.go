@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// userExportFlushEvery is the number of users written between flushes of a
+// user export to the client.
+const userExportFlushEvery = 1000
+
+// userExportEncoder writes exported users to the response as CSV or NDJSON.
+// Headers are sent with the first user, so an error raised before any user
+// was read can still be answered with an error response.
+type userExportEncoder struct {
+	w       http.ResponseWriter
+	format  string
+	columns []string
+	buf     *bufio.Writer
+	csv     *csv.Writer
+	started bool
+	count   int
+}
+
+func newUserExportEncoder(w http.ResponseWriter, format string, columns []string) *userExportEncoder {
+	return &userExportEncoder{w: w, format: format, columns: columns}
+}
+
+func (e *userExportEncoder) start() error {
+	e.started = true
+
+	if e.format == dto.UserExportFormatNDJSON {
+		e.w.Header().Set("Content-Type", "application/x-ndjson")
+		e.w.Header().Set("Content-Disposition", `attachment; filename="users.ndjson"`)
+	} else {
+		e.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		e.w.Header().Set("Content-Disposition", `attachment; filename="users.csv"`)
+	}
+	e.w.Header().Set("Cache-Control", "no-store")
+	e.w.WriteHeader(http.StatusOK)
+
+	e.buf = bufio.NewWriter(e.w)
+	if e.format == dto.UserExportFormatNDJSON {
+		return nil
+	}
+	e.csv = csv.NewWriter(e.buf)
+	return e.csv.Write(e.columns)
+}
+
+// write encodes one user; it is the callback passed to UserService.Export.
+func (e *userExportEncoder) write(u service.UserServiceDataResult) error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+
+	if e.csv != nil {
+		record := make([]string, len(e.columns))
+		for i, column := range e.columns {
+			record[i] = userExportCSVValue(userExportValue(u, column))
+		}
+		if err := e.csv.Write(record); err != nil {
+			return err
+		}
+	} else {
+		line, err := userExportJSONLine(u, e.columns)
+		if err != nil {
+			return err
+		}
+		if _, err := e.buf.Write(line); err != nil {
+			return err
+		}
+	}
+
+	e.count++
+	if e.count%userExportFlushEvery == 0 {
+		return e.flush()
+	}
+	return nil
+}
+
+// close finishes the export. An export without users still sends the
+// headers and, for CSV, the header row.
+func (e *userExportEncoder) close() error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	return e.flush()
+}
+
+func (e *userExportEncoder) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if err := e.buf.Flush(); err != nil {
+		return err
+	}
+	if err := http.NewResponseController(e.w).Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// userExportValue returns the value of an export column, typed as it is
+// encoded in NDJSON.
+func userExportValue(u service.UserServiceDataResult, column string) any {
+	switch column {
+	case "user_id":
+		return u.UserUUID.String()
+	case "username":
+		return u.Username
+	case "fullname":
+		return u.Fullname
+	case "email":
+		return u.Email
+	case "phone":
+		return u.Phone
+	case "status":
+		return u.Status
+	case "is_email_verified":
+		return u.IsEmailVerified
+	case "is_phone_verified":
+		return u.IsPhoneVerified
+	case "is_profile_completed":
+		return u.IsProfileCompleted
+	case "is_account_completed":
+		return u.IsAccountCompleted
+	case "metadata":
+		if len(u.Metadata) == 0 {
+			return nil
+		}
+		return json.RawMessage(u.Metadata)
+	case "created_at":
+		return u.CreatedAt
+	case "updated_at":
+		return u.UpdatedAt
+	case "deleted_at":
+		if u.DeletedAt == nil {
+			return nil
+		}
+		return *u.DeletedAt
+	}
+	return nil
+}
+
+// userExportCSVValue formats a column value for a CSV cell. Missing values
+// are empty and metadata is kept as JSON.
+func userExportCSVValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case json.RawMessage:
+		return string(v)
+	}
+	return ""
+}
+
+// userExportJSONLine encodes a user as one NDJSON line with the columns in
+// the requested order.
+func userExportJSONLine(u service.UserServiceDataResult, columns []string) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, column := range columns {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(userExportValue(u, column))
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteString("}\n")
+	return b.Bytes(), nil
+}
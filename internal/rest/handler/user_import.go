@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+	"gorm.io/datatypes"
+)
+
+// UserImportHandler handles bulk user imports.
+//
+// An import is a CSV or JSON file of users. Every row is validated and
+// checked for duplicates; rows that fail are reported and the rest are
+// created. Small files are imported within the request, larger ones in the
+// background with progress polled through GetImport.
+type UserImportHandler struct {
+	userImportService service.UserImportService
+}
+
+// NewUserImportHandler creates a new user import handler.
+func NewUserImportHandler(userImportService service.UserImportService) *UserImportHandler {
+	return &UserImportHandler{
+		userImportService: userImportService,
+	}
+}
+
+// errUserImportTooLarge is returned while parsing a file with more rows than
+// one import may hold.
+var errUserImportTooLarge = fmt.Errorf("an import may contain at most %d users", service.UserImportMaxRows)
+
+// ImportUsers imports users from a CSV or JSON file.
+//
+// POST /users/import
+//
+// The format is taken from the Content-Type header: text/csv, or
+// application/json with a {"users": [...]} body. With ?dry_run=true the
+// report is produced but no user is created. Imports that are processed in
+// the background return 202 with the import to poll.
+func (h *UserImportHandler) ImportUsers(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+
+	dryRun, err := dryRunRequested(r.URL.Query())
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid dry_run value")
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	var format string
+	var rows []dto.UserImportRowDTO
+	switch mediaType {
+	case "text/csv":
+		format = model.UserImportFormatCSV
+		rows, err = parseUserImportCSV(r.Body)
+	case "application/json":
+		format = model.UserImportFormatJSON
+		rows, err = parseUserImportJSON(r.Body)
+	default:
+		resp.Error(w, http.StatusUnsupportedMediaType, "Content-Type must be text/csv or application/json")
+		return
+	}
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid import file: "+err.Error())
+		return
+	}
+	if len(rows) == 0 {
+		resp.Error(w, http.StatusBadRequest, "The import contains no users")
+		return
+	}
+
+	result, err := h.userImportService.Import(r.Context(), auth.Tenant.TenantID, auth.User.UserUUID, format, dryRun, toUserImportRows(rows))
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to import users", err)
+		return
+	}
+
+	dtoRes := toUserImportResponseDTO(result)
+	switch {
+	case result.Status == model.UserImportStatusPending:
+		resp.Accepted(w, dtoRes, "User import queued")
+	case dryRun:
+		resp.Success(w, dtoRes, "Dry run completed, no users were created")
+	default:
+		resp.Success(w, dtoRes, "User import completed")
+	}
+}
+
+// GetImport returns the progress and report of an import.
+//
+// GET /users/import/{import_uuid}
+func (h *UserImportHandler) GetImport(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	importUUID, err := uuid.Parse(chi.URLParam(r, "import_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid import UUID")
+		return
+	}
+
+	result, err := h.userImportService.GetJob(r.Context(), importUUID, tenant.TenantID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get user import", err)
+		return
+	}
+
+	resp.Success(w, toUserImportResponseDTO(result), "User import retrieved successfully")
+}
+
+// parseUserImportJSON decodes a {"users": [...]} import body.
+func parseUserImportJSON(body io.Reader) ([]dto.UserImportRowDTO, error) {
+	var req dto.UserImportRequestDTO
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		return nil, errors.New("invalid JSON format")
+	}
+	if len(req.Users) > service.UserImportMaxRows {
+		return nil, errUserImportTooLarge
+	}
+	return req.Users, nil
+}
+
+// parseUserImportCSV reads a CSV import. The first row names the columns,
+// which may come in any order; username and fullname are required and
+// every other column is optional. Metadata is a JSON object in one cell.
+func parseUserImportCSV(body io.Reader) ([]dto.UserImportRowDTO, error) {
+	reader := csv.NewReader(body)
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.New("invalid CSV format")
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !slices.Contains(dto.UserImportColumns, name) {
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("duplicate CSV column %q", name)
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"username", "fullname"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV column %q is required", required)
+		}
+	}
+
+	// Passwords are taken as they are; other values are trimmed.
+	raw := func(record []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return record[i]
+		}
+		return ""
+	}
+	field := func(record []string, name string) string {
+		return strings.TrimSpace(raw(record, name))
+	}
+
+	var rows []dto.UserImportRowDTO
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("invalid CSV format on line %d", parseErr.Line)
+			}
+			return nil, errors.New("invalid CSV format")
+		}
+		if len(rows) == service.UserImportMaxRows {
+			return nil, errUserImportTooLarge
+		}
+
+		row := dto.UserImportRowDTO{
+			Username:     field(record, "username"),
+			Fullname:     field(record, "fullname"),
+			Email:        field(record, "email"),
+			Phone:        field(record, "phone"),
+			Password:     raw(record, "password"),
+			PasswordHash: field(record, "password_hash"),
+			Status:       field(record, "status"),
+		}
+		if metadata := field(record, "metadata"); metadata != "" {
+			row.Metadata = datatypes.JSON(metadata)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// toUserImportRows numbers the rows from 1 and marks those that fail
+// validation, so that they are reported instead of failing the request.
+func toUserImportRows(rows []dto.UserImportRowDTO) []service.UserImportRow {
+	out := make([]service.UserImportRow, len(rows))
+	for i, row := range rows {
+		out[i] = service.UserImportRow{
+			Row:          i + 1,
+			Username:     row.Username,
+			Fullname:     row.Fullname,
+			Email:        row.Email,
+			Phone:        row.Phone,
+			Password:     row.Password,
+			PasswordHash: row.PasswordHash,
+			Status:       row.Status,
+			Metadata:     row.Metadata,
+		}
+		if err := row.Validate(); err != nil {
+			out[i].Invalid = err.Error()
+		} else if len(row.Metadata) > 0 && !json.Valid(row.Metadata) {
+			out[i].Invalid = "metadata: must be a JSON object."
+		}
+	}
+	return out
+}
+
+func toUserImportResponseDTO(r *service.UserImportServiceDataResult) dto.UserImportResponseDTO {
+	errs := make([]dto.UserImportRowErrorResponseDTO, len(r.RowErrors))
+	for i, e := range r.RowErrors {
+		errs[i] = dto.UserImportRowErrorResponseDTO{
+			Row:      e.Row,
+			Username: e.Username,
+			Reason:   e.Reason,
+			Message:  e.Message,
+		}
+	}
+
+	return dto.UserImportResponseDTO{
+		UserImportUUID: r.UserImportJobUUID,
+		Format:         r.Format,
+		DryRun:         r.DryRun,
+		Status:         r.Status,
+		TotalRows:      r.TotalRows,
+		ProcessedRows:  r.ProcessedRows,
+		ImportedRows:   r.ImportedRows,
+		DuplicateRows:  r.DuplicateRows,
+		InvalidRows:    r.InvalidRows,
+		FailedRows:     r.FailedRows,
+		Errors:         errs,
+		Error:          r.Error,
+		StartedAt:      r.StartedAt,
+		CompletedAt:    r.CompletedAt,
+		CreatedAt:      r.CreatedAt,
+	}
+}
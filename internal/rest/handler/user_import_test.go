@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func csvReq(target, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	r.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	return r
+}
+
+// ---------------------------------------------------------------------------
+// ImportUsers
+// ---------------------------------------------------------------------------
+
+func TestUserImportHandler_ImportUsers_RequestErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"no tenant", withUser(csvReq("/users/import", "username,fullname\n")), http.StatusUnauthorized},
+		{"no user", withTenant(csvReq("/users/import", "username,fullname\n")), http.StatusUnauthorized},
+		{"bad dry_run", withTenantAndUser(csvReq("/users/import?dry_run=maybe", "username,fullname\nalice,Alice\n")), http.StatusBadRequest},
+		{"unsupported content type", withTenantAndUser(func() *http.Request {
+			r := httptest.NewRequest(http.MethodPost, "/users/import", strings.NewReader("x"))
+			r.Header.Set("Content-Type", "application/xml")
+			return r
+		}()), http.StatusUnsupportedMediaType},
+		{"bad json", withTenantAndUser(badJSONReq(t, http.MethodPost, "/users/import")), http.StatusBadRequest},
+		{"empty json", withTenantAndUser(jsonReq(t, http.MethodPost, "/users/import", map[string]any{"users": []any{}})), http.StatusBadRequest},
+		{"empty csv", withTenantAndUser(csvReq("/users/import", "")), http.StatusBadRequest},
+		{"header only", withTenantAndUser(csvReq("/users/import", "username,fullname\n")), http.StatusBadRequest},
+		{"unknown column", withTenantAndUser(csvReq("/users/import", "username,fullname,age\nalice,Alice,30\n")), http.StatusBadRequest},
+		{"duplicate column", withTenantAndUser(csvReq("/users/import", "username,username,fullname\na,b,c\n")), http.StatusBadRequest},
+		{"missing column", withTenantAndUser(csvReq("/users/import", "username,email\nalice,a@example.com\n")), http.StatusBadRequest},
+		{"ragged row", withTenantAndUser(csvReq("/users/import", "username,fullname\nalice\n")), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewUserImportHandler(&mockUserImportService{
+				importFn: func(int64, uuid.UUID, string, bool, []service.UserImportRow) (*service.UserImportServiceDataResult, error) {
+					t.Fatal("service must not be called")
+					return nil, nil
+				},
+			})
+			w := httptest.NewRecorder()
+			h.ImportUsers(w, tt.req)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestUserImportHandler_ImportUsers_CSV(t *testing.T) {
+	var gotRows []service.UserImportRow
+	var gotFormat string
+	var gotDryRun bool
+	h := NewUserImportHandler(&mockUserImportService{
+		importFn: func(tid int64, actor uuid.UUID, format string, dryRun bool, rows []service.UserImportRow) (*service.UserImportServiceDataResult, error) {
+			assert.Equal(t, tenantID, tid)
+			assert.Equal(t, testUserUUID, actor)
+			gotFormat, gotDryRun, gotRows = format, dryRun, rows
+			return &service.UserImportServiceDataResult{
+				Status:       model.UserImportStatusCompleted,
+				DryRun:       true,
+				TotalRows:    3,
+				ImportedRows: 2,
+				InvalidRows:  1,
+				RowErrors:    []service.UserImportRowError{{Row: 2, Username: "x", Reason: "invalid", Message: "username: the length must be between 3 and 50."}},
+			}, nil
+		},
+	})
+
+	body := "\ufeffUsername, fullname,email,password,metadata\n" +
+		"alice,Alice Doe,alice@example.com, spaced pass ,\"{\"\"team\"\":\"\"a\"\"}\"\n" +
+		"x,X,,,\n" +
+		"bob,Bob,bob@example.com,,\n"
+	w := httptest.NewRecorder()
+	h.ImportUsers(w, withTenantAndUser(csvReq("/users/import?dry_run=true", body)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Dry run completed")
+	assert.Contains(t, w.Body.String(), `"imported_rows":2`)
+	assert.Equal(t, model.UserImportFormatCSV, gotFormat)
+	assert.True(t, gotDryRun)
+
+	require.Len(t, gotRows, 3)
+	assert.Equal(t, 1, gotRows[0].Row)
+	assert.Equal(t, "alice", gotRows[0].Username)
+	assert.Equal(t, " spaced pass ", gotRows[0].Password, "passwords are not trimmed")
+	assert.JSONEq(t, `{"team":"a"}`, string(gotRows[0].Metadata))
+	assert.Empty(t, gotRows[0].Invalid)
+	assert.Equal(t, 2, gotRows[1].Row)
+	assert.Contains(t, gotRows[1].Invalid, "username")
+	assert.Empty(t, gotRows[2].Invalid)
+}
+
+func TestUserImportHandler_ImportUsers_JSON(t *testing.T) {
+	var gotRows []service.UserImportRow
+	h := NewUserImportHandler(&mockUserImportService{
+		importFn: func(_ int64, _ uuid.UUID, format string, dryRun bool, rows []service.UserImportRow) (*service.UserImportServiceDataResult, error) {
+			assert.Equal(t, model.UserImportFormatJSON, format)
+			assert.False(t, dryRun)
+			gotRows = rows
+			return &service.UserImportServiceDataResult{Status: model.UserImportStatusCompleted, ImportedRows: 1}, nil
+		},
+	})
+
+	req := jsonReq(t, http.MethodPost, "/users/import", map[string]any{
+		"users": []map[string]any{
+			{"username": "alice", "fullname": "Alice", "password_hash": "$2a$10$abc"},
+			{"username": "bob", "fullname": "Bob", "password": "Secret#123", "password_hash": "$2a$10$abc"},
+		},
+	})
+	w := httptest.NewRecorder()
+	h.ImportUsers(w, withTenantAndUser(req))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "User import completed")
+	require.Len(t, gotRows, 2)
+	assert.Equal(t, "$2a$10$abc", gotRows[0].PasswordHash)
+	assert.Empty(t, gotRows[0].Invalid)
+	assert.Contains(t, gotRows[1].Invalid, "password_hash")
+}
+
+func TestUserImportHandler_ImportUsers_Queued(t *testing.T) {
+	jobUUID := uuid.New()
+	h := NewUserImportHandler(&mockUserImportService{
+		importFn: func(int64, uuid.UUID, string, bool, []service.UserImportRow) (*service.UserImportServiceDataResult, error) {
+			return &service.UserImportServiceDataResult{UserImportJobUUID: jobUUID, Status: model.UserImportStatusPending, TotalRows: 5000}, nil
+		},
+	})
+	w := httptest.NewRecorder()
+	h.ImportUsers(w, withTenantAndUser(csvReq("/users/import", "username,fullname\nalice,Alice\n")))
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), jobUUID.String())
+	assert.Contains(t, w.Body.String(), `"status":"pending"`)
+}
+
+func TestUserImportHandler_ImportUsers_ServiceError(t *testing.T) {
+	h := NewUserImportHandler(&mockUserImportService{
+		importFn: func(int64, uuid.UUID, string, bool, []service.UserImportRow) (*service.UserImportServiceDataResult, error) {
+			return nil, errValidation
+		},
+	})
+	w := httptest.NewRecorder()
+	h.ImportUsers(w, withTenantAndUser(csvReq("/users/import", "username,fullname\nalice,Alice\n")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// ---------------------------------------------------------------------------
+// GetImport
+// ---------------------------------------------------------------------------
+
+func TestUserImportHandler_GetImport(t *testing.T) {
+	t.Run("no tenant", func(t *testing.T) {
+		h := NewUserImportHandler(&mockUserImportService{})
+		w := httptest.NewRecorder()
+		h.GetImport(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid uuid", func(t *testing.T) {
+		h := NewUserImportHandler(&mockUserImportService{})
+		w := httptest.NewRecorder()
+		h.GetImport(w, withChiParam(withTenant(httptest.NewRequest(http.MethodGet, "/", nil)), "import_uuid", "bad"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		h := NewUserImportHandler(&mockUserImportService{
+			getJobFn: func(uuid.UUID, int64) (*service.UserImportServiceDataResult, error) { return nil, errNotFound },
+		})
+		w := httptest.NewRecorder()
+		h.GetImport(w, withChiParam(withTenant(httptest.NewRequest(http.MethodGet, "/", nil)), "import_uuid", testResourceUUID.String()))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		h := NewUserImportHandler(&mockUserImportService{
+			getJobFn: func(id uuid.UUID, tid int64) (*service.UserImportServiceDataResult, error) {
+				assert.Equal(t, testResourceUUID, id)
+				assert.Equal(t, tenantID, tid)
+				return &service.UserImportServiceDataResult{
+					UserImportJobUUID: id,
+					Status:            model.UserImportStatusRunning,
+					TotalRows:         1000,
+					ProcessedRows:     500,
+				}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		h.GetImport(w, withChiParam(withTenant(httptest.NewRequest(http.MethodGet, "/", nil)), "import_uuid", testResourceUUID.String()))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"processed_rows":500`)
+		assert.Contains(t, w.Body.String(), `"errors":[]`)
+	})
+}
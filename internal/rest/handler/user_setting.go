@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/dto"
 	"github.com/maintainerd/auth/internal/middleware"
 	resp "github.com/maintainerd/auth/internal/rest/response"
@@ -18,7 +20,118 @@ func NewUserSettingHandler(userSettingService service.UserSettingService) *UserS
 	return &UserSettingHandler{userSettingService}
 }
 
+// CreateOrUpdate replaces the authenticated user's settings.
+//
+// POST /user-settings
 func (h *UserSettingHandler) CreateOrUpdate(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+	h.save(w, r, auth.Tenant.TenantID, auth.User.UserUUID)
+}
+
+// Get returns the authenticated user's settings, with the tenant's defaults
+// in place of the preferences the user has not chosen.
+//
+// GET /user-settings
+func (h *UserSettingHandler) Get(w http.ResponseWriter, r *http.Request) {
+	auth := middleware.AuthFromRequest(r)
+	if auth.Tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+	if auth.User == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+
+	userSetting, err := h.userSettingService.GetEffective(r.Context(), auth.Tenant.TenantID, auth.User.UserUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get user setting", err)
+		return
+	}
+
+	resp.Success(w, toUserSettingResponseDTO(*userSetting), "User setting retrieved successfully")
+}
+
+// AdminGet returns a user's settings, with the tenant's defaults in place of
+// the preferences the user has not chosen.
+//
+// GET /users/{user_uuid}/settings
+func (h *UserSettingHandler) AdminGet(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid user UUID")
+		return
+	}
+
+	userSetting, err := h.userSettingService.GetEffective(r.Context(), tenant.TenantID, userUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get user setting", err)
+		return
+	}
+
+	resp.Success(w, toUserSettingResponseDTO(*userSetting), "User setting retrieved successfully")
+}
+
+// AdminUpdate replaces a user's settings.
+//
+// PUT /users/{user_uuid}/settings
+func (h *UserSettingHandler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid user UUID")
+		return
+	}
+
+	h.save(w, r, tenant.TenantID, userUUID)
+}
+
+// AdminReset deletes a user's settings, so the defaults apply again.
+//
+// DELETE /users/{user_uuid}/settings
+func (h *UserSettingHandler) AdminReset(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	userUUID, err := uuid.Parse(chi.URLParam(r, "user_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid user UUID")
+		return
+	}
+
+	userSetting, err := h.userSettingService.Reset(r.Context(), tenant.TenantID, userUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Reset user setting failed", err)
+		return
+	}
+
+	resp.Success(w, toUserSettingResponseDTO(*userSetting), "User setting reset successfully")
+}
+
+// save replaces the settings of the tenant's user from the request body.
+func (h *UserSettingHandler) save(w http.ResponseWriter, r *http.Request, tenantID int64, userUUID uuid.UUID) {
 	var req dto.UserSettingRequestDTO
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		resp.Error(w, http.StatusBadRequest, "Invalid request")
@@ -39,11 +152,11 @@ func (h *UserSettingHandler) CreateOrUpdate(w http.ResponseWriter, r *http.Reque
 		}
 	}
 
-	user := middleware.AuthFromRequest(r).User
 	userSetting, err := h.userSettingService.CreateOrUpdateUserSetting(
 		r.Context(),
-		user.UserUUID,
-		req.Timezone, req.PreferredLanguage, req.Locale,
+		tenantID,
+		userUUID,
+		req.Timezone, req.PreferredLanguage, req.Locale, req.Theme, req.DateFormat,
 		socialLinks,
 		req.PreferredContactMethod,
 		req.MarketingEmailConsent, req.SMSNotificationsConsent, req.PushNotificationsConsent,
@@ -60,20 +173,13 @@ func (h *UserSettingHandler) CreateOrUpdate(w http.ResponseWriter, r *http.Reque
 	resp.Success(w, toUserSettingResponseDTO(*userSetting), "User setting saved successfully")
 }
 
-func (h *UserSettingHandler) Get(w http.ResponseWriter, r *http.Request) {
+func (h *UserSettingHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	user := middleware.AuthFromRequest(r).User
-	userSetting, err := h.userSettingService.GetByUserUUID(r.Context(), user.UserUUID)
-	if err != nil || userSetting == nil {
-		resp.Error(w, http.StatusNotFound, "User setting not found")
+	if user == nil {
+		resp.Error(w, http.StatusUnauthorized, "User not found in context")
 		return
 	}
 
-	resp.Success(w, toUserSettingResponseDTO(*userSetting), "User setting retrieved successfully")
-}
-
-func (h *UserSettingHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	user := middleware.AuthFromRequest(r).User
-
 	// First get the user setting to get its UUID
 	userSetting, err := h.userSettingService.GetByUserUUID(r.Context(), user.UserUUID)
 	if err != nil || userSetting == nil {
@@ -108,6 +214,8 @@ func toUserSettingResponseDTO(us service.UserSettingServiceDataResult) dto.UserS
 		Timezone:          us.Timezone,
 		PreferredLanguage: us.PreferredLanguage,
 		Locale:            us.Locale,
+		Theme:             us.Theme,
+		DateFormat:        us.DateFormat,
 
 		// Social Media & External Links
 		SocialLinks: socialLinks,
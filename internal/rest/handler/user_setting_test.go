@@ -14,7 +14,7 @@ import (
 
 func TestUserSettingHandler_CreateOrUpdate_BadJSON(t *testing.T) {
 	h := NewUserSettingHandler(&mockUserSettingService{})
-	r := httptest.NewRequest(http.MethodPost, "/user-settings", nil)
+	r := withTenantAndUser(httptest.NewRequest(http.MethodPost, "/user-settings", nil))
 	r.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	h.CreateOrUpdate(w, r)
@@ -24,8 +24,9 @@ func TestUserSettingHandler_CreateOrUpdate_BadJSON(t *testing.T) {
 func TestUserSettingHandler_CreateOrUpdate_Success(t *testing.T) {
 	svc := &mockUserSettingService{
 		createOrUpdateFn: func(
+			tenantID int64,
 			userUUID uuid.UUID,
-			timezone, preferredLanguage, locale *string,
+			timezone, preferredLanguage, locale, theme, dateFormat *string,
 			socialLinks map[string]any,
 			preferredContactMethod *string,
 			marketingEmailConsent, smsConsent, pushConsent *bool,
@@ -48,7 +49,7 @@ func TestUserSettingHandler_CreateOrUpdate_Success(t *testing.T) {
 
 func TestUserSettingHandler_Get_NotFound(t *testing.T) {
 	svc := &mockUserSettingService{
-		getByUserUUIDFn: func(id uuid.UUID) (*service.UserSettingServiceDataResult, error) {
+		getEffectiveFn: func(int64, uuid.UUID) (*service.UserSettingServiceDataResult, error) {
 			return nil, errNotFound
 		},
 	}
@@ -60,9 +61,12 @@ func TestUserSettingHandler_Get_NotFound(t *testing.T) {
 }
 
 func TestUserSettingHandler_Get_Success(t *testing.T) {
+	theme := "dark"
+	var gotTenant int64
 	svc := &mockUserSettingService{
-		getByUserUUIDFn: func(id uuid.UUID) (*service.UserSettingServiceDataResult, error) {
-			return &service.UserSettingServiceDataResult{}, nil
+		getEffectiveFn: func(tid int64, id uuid.UUID) (*service.UserSettingServiceDataResult, error) {
+			gotTenant = tid
+			return &service.UserSettingServiceDataResult{Theme: &theme}, nil
 		},
 	}
 	h := NewUserSettingHandler(svc)
@@ -70,6 +74,16 @@ func TestUserSettingHandler_Get_Success(t *testing.T) {
 	w := httptest.NewRecorder()
 	h.Get(w, r)
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, tenantID, gotTenant)
+	assert.Contains(t, w.Body.String(), `"theme":"dark"`)
+}
+
+func TestUserSettingHandler_Get_NoTenant(t *testing.T) {
+	h := NewUserSettingHandler(&mockUserSettingService{})
+	r := withUser(httptest.NewRequest(http.MethodGet, "/user-settings", nil))
+	w := httptest.NewRecorder()
+	h.Get(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
 func TestUserSettingHandler_CreateOrUpdate_ValidationError(t *testing.T) {
@@ -86,7 +100,7 @@ func TestUserSettingHandler_CreateOrUpdate_ValidationError(t *testing.T) {
 func TestUserSettingHandler_CreateOrUpdate_WithSocialLinks(t *testing.T) {
 	// covers the SocialLinks map conversion loop (lines 36-41)
 	svc := &mockUserSettingService{
-		createOrUpdateFn: func(userUUID uuid.UUID, tz, lang, locale *string, sl map[string]any, pcm *string, mec, sms, push *bool, pv *string, dpc *bool, ta, ppa *time.Time, ecn, ecp, ece, ecr *string) (*service.UserSettingServiceDataResult, error) {
+		createOrUpdateFn: func(tid int64, userUUID uuid.UUID, tz, lang, locale, theme, df *string, sl map[string]any, pcm *string, mec, sms, push *bool, pv *string, dpc *bool, ta, ppa *time.Time, ecn, ecp, ece, ecr *string) (*service.UserSettingServiceDataResult, error) {
 			return &service.UserSettingServiceDataResult{}, nil
 		},
 	}
@@ -101,7 +115,7 @@ func TestUserSettingHandler_CreateOrUpdate_WithSocialLinks(t *testing.T) {
 
 func TestUserSettingHandler_CreateOrUpdate_ServiceError(t *testing.T) {
 	svc := &mockUserSettingService{
-		createOrUpdateFn: func(userUUID uuid.UUID, tz, lang, locale *string, sl map[string]any, pcm *string, mec, sms, push *bool, pv *string, dpc *bool, ta, ppa *time.Time, ecn, ecp, ece, ecr *string) (*service.UserSettingServiceDataResult, error) {
+		createOrUpdateFn: func(tid int64, userUUID uuid.UUID, tz, lang, locale, theme, df *string, sl map[string]any, pcm *string, mec, sms, push *bool, pv *string, dpc *bool, ta, ppa *time.Time, ecn, ecp, ece, ecr *string) (*service.UserSettingServiceDataResult, error) {
 			return nil, errValidation
 		},
 	}
@@ -157,7 +171,7 @@ func TestUserSettingHandler_Delete_Success(t *testing.T) {
 func TestUserSettingHandler_toUserSettingResponseDto_InvalidSocialLinksJSON(t *testing.T) {
 	// SocialLinks with invalid JSON bytes covers the unmarshal-error else branch (line 103)
 	svc := &mockUserSettingService{
-		getByUserUUIDFn: func(uuid.UUID) (*service.UserSettingServiceDataResult, error) {
+		getEffectiveFn: func(int64, uuid.UUID) (*service.UserSettingServiceDataResult, error) {
 			return &service.UserSettingServiceDataResult{
 				SocialLinks: datatypes.JSON([]byte("not-valid-json")),
 			}, nil
@@ -169,3 +183,131 @@ func TestUserSettingHandler_toUserSettingResponseDto_InvalidSocialLinksJSON(t *t
 	h.Get(w, r)
 	assert.Equal(t, http.StatusOK, w.Code)
 }
+
+func TestUserSettingHandler_CreateOrUpdate_InvalidDisplay(t *testing.T) {
+	h := NewUserSettingHandler(&mockUserSettingService{})
+	for _, body := range []map[string]any{
+		{"theme": "neon"},
+		{"date_format": "YY/M/D"},
+		{"timezone": "Mars/Olympus_Mons"},
+	} {
+		r := withTenantAndUser(jsonReq(t, http.MethodPost, "/user-settings", body))
+		w := httptest.NewRecorder()
+		h.CreateOrUpdate(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestUserSettingHandler_AdminGet(t *testing.T) {
+	t.Run("no tenant", func(t *testing.T) {
+		h := NewUserSettingHandler(&mockUserSettingService{})
+		r := withChiParam(httptest.NewRequest(http.MethodGet, "/", nil), "user_uuid", testUserUUID.String())
+		w := httptest.NewRecorder()
+		h.AdminGet(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+	t.Run("invalid uuid", func(t *testing.T) {
+		h := NewUserSettingHandler(&mockUserSettingService{})
+		r := withChiParam(withTenant(httptest.NewRequest(http.MethodGet, "/", nil)), "user_uuid", "bad")
+		w := httptest.NewRecorder()
+		h.AdminGet(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("not found", func(t *testing.T) {
+		h := NewUserSettingHandler(&mockUserSettingService{
+			getEffectiveFn: func(int64, uuid.UUID) (*service.UserSettingServiceDataResult, error) {
+				return nil, errNotFound
+			},
+		})
+		r := withChiParam(withTenant(httptest.NewRequest(http.MethodGet, "/", nil)), "user_uuid", testResourceUUID.String())
+		w := httptest.NewRecorder()
+		h.AdminGet(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+	t.Run("success", func(t *testing.T) {
+		var gotUser uuid.UUID
+		h := NewUserSettingHandler(&mockUserSettingService{
+			getEffectiveFn: func(_ int64, id uuid.UUID) (*service.UserSettingServiceDataResult, error) {
+				gotUser = id
+				return &service.UserSettingServiceDataResult{}, nil
+			},
+		})
+		r := withChiParam(withTenant(httptest.NewRequest(http.MethodGet, "/", nil)), "user_uuid", testResourceUUID.String())
+		w := httptest.NewRecorder()
+		h.AdminGet(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, testResourceUUID, gotUser)
+	})
+}
+
+func TestUserSettingHandler_AdminUpdate(t *testing.T) {
+	t.Run("no tenant", func(t *testing.T) {
+		h := NewUserSettingHandler(&mockUserSettingService{})
+		r := withChiParam(jsonReq(t, http.MethodPut, "/", map[string]any{}), "user_uuid", testUserUUID.String())
+		w := httptest.NewRecorder()
+		h.AdminUpdate(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+	t.Run("invalid uuid", func(t *testing.T) {
+		h := NewUserSettingHandler(&mockUserSettingService{})
+		r := withChiParam(withTenant(jsonReq(t, http.MethodPut, "/", map[string]any{})), "user_uuid", "bad")
+		w := httptest.NewRecorder()
+		h.AdminUpdate(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("success", func(t *testing.T) {
+		var gotUser uuid.UUID
+		var gotTheme, gotDateFormat *string
+		h := NewUserSettingHandler(&mockUserSettingService{
+			createOrUpdateFn: func(tid int64, userUUID uuid.UUID, tz, lang, locale, theme, df *string, sl map[string]any, pcm *string, mec, sms, push *bool, pv *string, dpc *bool, ta, ppa *time.Time, ecn, ecp, ece, ecr *string) (*service.UserSettingServiceDataResult, error) {
+				gotUser, gotTheme, gotDateFormat = userUUID, theme, df
+				return &service.UserSettingServiceDataResult{Theme: theme, DateFormat: df}, nil
+			},
+		})
+		r := withChiParam(withTenant(jsonReq(t, http.MethodPut, "/", map[string]any{
+			"theme":       "dark",
+			"date_format": "DD/MM/YYYY",
+		})), "user_uuid", testResourceUUID.String())
+		w := httptest.NewRecorder()
+		h.AdminUpdate(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, testResourceUUID, gotUser)
+		assert.Equal(t, "dark", *gotTheme)
+		assert.Equal(t, "DD/MM/YYYY", *gotDateFormat)
+	})
+}
+
+func TestUserSettingHandler_AdminReset(t *testing.T) {
+	t.Run("no tenant", func(t *testing.T) {
+		h := NewUserSettingHandler(&mockUserSettingService{})
+		r := withChiParam(httptest.NewRequest(http.MethodDelete, "/", nil), "user_uuid", testUserUUID.String())
+		w := httptest.NewRecorder()
+		h.AdminReset(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+	t.Run("invalid uuid", func(t *testing.T) {
+		h := NewUserSettingHandler(&mockUserSettingService{})
+		r := withChiParam(withTenant(httptest.NewRequest(http.MethodDelete, "/", nil)), "user_uuid", "bad")
+		w := httptest.NewRecorder()
+		h.AdminReset(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("service error", func(t *testing.T) {
+		h := NewUserSettingHandler(&mockUserSettingService{
+			resetFn: func(int64, uuid.UUID) (*service.UserSettingServiceDataResult, error) {
+				return nil, errNotFound
+			},
+		})
+		r := withChiParam(withTenant(httptest.NewRequest(http.MethodDelete, "/", nil)), "user_uuid", testResourceUUID.String())
+		w := httptest.NewRecorder()
+		h.AdminReset(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+	t.Run("success", func(t *testing.T) {
+		h := NewUserSettingHandler(&mockUserSettingService{})
+		r := withChiParam(withTenant(httptest.NewRequest(http.MethodDelete, "/", nil)), "user_uuid", testResourceUUID.String())
+		w := httptest.NewRecorder()
+		h.AdminReset(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -4,9 +4,14 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/service"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/datatypes"
@@ -46,6 +51,53 @@ func TestUserHandler_GetUsers_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestUserHandler_SearchUsers_NoTenant(t *testing.T) {
+	h := NewUserHandler(&mockUserService{})
+	r := httptest.NewRequest(http.MethodGet, "/users/search?q=alice", nil)
+	w := httptest.NewRecorder()
+	h.SearchUsers(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestUserHandler_SearchUsers_MissingQuery(t *testing.T) {
+	h := NewUserHandler(&mockUserService{})
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/users/search", nil))
+	w := httptest.NewRecorder()
+	h.SearchUsers(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUserHandler_SearchUsers_ServiceError(t *testing.T) {
+	svc := &mockUserService{
+		searchFn: func(string, service.UserServiceGetFilter) (*service.UserServiceGetResult, error) {
+			return nil, assert.AnError
+		},
+	}
+	h := NewUserHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/users/search?q=alice", nil))
+	w := httptest.NewRecorder()
+	h.SearchUsers(w, r)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestUserHandler_SearchUsers_Success(t *testing.T) {
+	svc := &mockUserService{
+		searchFn: func(q string, f service.UserServiceGetFilter) (*service.UserServiceGetResult, error) {
+			assert.Equal(t, "jon smith", q)
+			assert.Equal(t, []string{"active"}, f.Status)
+			assert.True(t, f.IncludeRoles)
+			assert.Equal(t, 5, f.Limit)
+			return &service.UserServiceGetResult{Data: []service.UserServiceDataResult{{Username: "jsmith"}}, Total: 1, Page: 1, Limit: 5, TotalPages: 1}, nil
+		},
+	}
+	h := NewUserHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/users/search?q=jon+smith&status=active&include=roles&limit=5", nil))
+	w := httptest.NewRecorder()
+	h.SearchUsers(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "jsmith")
+}
+
 func TestUserHandler_GetUserByUUID_NoTenant(t *testing.T) {
 	h := NewUserHandler(&mockUserService{})
 	r := withChiParam(httptest.NewRequest(http.MethodGet, "/users/"+testResourceUUID.String(), nil), "user_uuid", testResourceUUID.String())
@@ -83,6 +135,14 @@ func TestUserHandler_CreateUser_NoTenant(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
+func TestUserHandler_CreateUser_NoUser(t *testing.T) {
+	h := NewUserHandler(&mockUserService{})
+	r := withTenant(jsonReq(t, http.MethodPost, "/users", map[string]string{"username": "u"}))
+	w := httptest.NewRecorder()
+	h.CreateUser(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
 func TestUserHandler_CreateUser_ServiceError(t *testing.T) {
 	svc := &mockUserService{
 		createFn: func(u, fn string, e, ph *string, pw, s string, meta datatypes.JSON, tUUID string, creator uuid.UUID) (*service.UserServiceDataResult, error) {
@@ -106,6 +166,14 @@ func TestUserHandler_DeleteUser_NoTenant(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
+func TestUserHandler_DeleteUser_NoUser(t *testing.T) {
+	h := NewUserHandler(&mockUserService{})
+	r := withTenant(withChiParam(httptest.NewRequest(http.MethodDelete, "/", nil), "user_uuid", testResourceUUID.String()))
+	w := httptest.NewRecorder()
+	h.DeleteUser(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
 func TestUserHandler_DeleteUser_InvalidUUID(t *testing.T) {
 	h := NewUserHandler(&mockUserService{})
 	r := withTenantAndUser(withChiParam(httptest.NewRequest(http.MethodDelete, "/users/bad", nil), "user_uuid", "bad"))
@@ -140,6 +208,308 @@ func TestUserHandler_DeleteUser_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestUserHandler_RestoreUser(t *testing.T) {
+	newReq := func(id string) *http.Request {
+		return withChiParam(httptest.NewRequest(http.MethodPost, "/users/"+id+"/restore", nil), "user_uuid", id)
+	}
+
+	w := httptest.NewRecorder()
+	NewUserHandler(&mockUserService{}).RestoreUser(w, newReq(testResourceUUID.String()))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// An API key request carries a tenant but no user
+	w = httptest.NewRecorder()
+	NewUserHandler(&mockUserService{}).RestoreUser(w, withTenant(newReq(testResourceUUID.String())))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	NewUserHandler(&mockUserService{}).RestoreUser(w, withTenantAndUser(newReq("bad")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var gotUser, gotRestorer uuid.UUID
+	svc := &mockUserService{
+		restoreByUUIDFn: func(id uuid.UUID, tid int64, restorer uuid.UUID) (*service.UserServiceDataResult, error) {
+			gotUser, gotRestorer = id, restorer
+			return &service.UserServiceDataResult{Status: model.StatusActive}, nil
+		},
+	}
+	w = httptest.NewRecorder()
+	NewUserHandler(svc).RestoreUser(w, withTenantAndUser(newReq(testResourceUUID.String())))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, testResourceUUID, gotUser)
+	assert.Equal(t, testUserUUID, gotRestorer)
+
+	svc.restoreByUUIDFn = func(uuid.UUID, int64, uuid.UUID) (*service.UserServiceDataResult, error) {
+		return nil, apperror.NewValidation("user is not deleted")
+	}
+	w = httptest.NewRecorder()
+	NewUserHandler(svc).RestoreUser(w, withTenantAndUser(newReq(testResourceUUID.String())))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUserHandler_EnableUser(t *testing.T) {
+	newReq := func(id string) *http.Request {
+		return withChiParam(httptest.NewRequest(http.MethodPost, "/users/"+id+"/enable", nil), "user_uuid", id)
+	}
+
+	w := httptest.NewRecorder()
+	NewUserHandler(&mockUserService{}).EnableUser(w, newReq(testResourceUUID.String()))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	NewUserHandler(&mockUserService{}).EnableUser(w, withTenantAndUser(newReq("bad")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var gotUser, gotUpdater uuid.UUID
+	var gotStatus string
+	svc := &mockUserService{
+		setStatusFn: func(id uuid.UUID, tid int64, status string, updater uuid.UUID) (*service.UserServiceDataResult, error) {
+			gotUser, gotStatus, gotUpdater = id, status, updater
+			return &service.UserServiceDataResult{Status: status}, nil
+		},
+	}
+	w = httptest.NewRecorder()
+	NewUserHandler(svc).EnableUser(w, withTenantAndUser(newReq(testResourceUUID.String())))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, testResourceUUID, gotUser)
+	assert.Equal(t, model.StatusActive, gotStatus)
+	assert.Equal(t, testUserUUID, gotUpdater)
+
+	svc.setStatusFn = func(uuid.UUID, int64, string, uuid.UUID) (*service.UserServiceDataResult, error) {
+		return nil, apperror.WithCode(apperror.CodeUserNotFound, apperror.NewNotFound("user"))
+	}
+	w = httptest.NewRecorder()
+	NewUserHandler(svc).EnableUser(w, withTenantAndUser(newReq(testResourceUUID.String())))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestUserHandler_PurgeUser(t *testing.T) {
+	newReq := func(id string) *http.Request {
+		return withChiParam(httptest.NewRequest(http.MethodDelete, "/users/"+id+"/purge", nil), "user_uuid", id)
+	}
+
+	w := httptest.NewRecorder()
+	NewUserHandler(&mockUserService{}).PurgeUser(w, newReq(testResourceUUID.String()))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	NewUserHandler(&mockUserService{}).PurgeUser(w, withTenantAndUser(newReq("bad")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	svc := &mockUserService{
+		purgeByUUIDFn: func(uuid.UUID, int64, uuid.UUID) (*service.UserServiceDataResult, error) {
+			return &service.UserServiceDataResult{}, nil
+		},
+	}
+	w = httptest.NewRecorder()
+	NewUserHandler(svc).PurgeUser(w, withTenantAndUser(newReq(testResourceUUID.String())))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	svc.purgeByUUIDFn = func(uuid.UUID, int64, uuid.UUID) (*service.UserServiceDataResult, error) {
+		return nil, assert.AnError
+	}
+	w = httptest.NewRecorder()
+	NewUserHandler(svc).PurgeUser(w, withTenantAndUser(newReq(testResourceUUID.String())))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestUserHandler_GetUserDelta(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewUserHandler(&mockUserService{}).GetUserDelta(w, httptest.NewRequest(http.MethodGet, "/users/delta", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	for _, target := range []string{"/users/delta?limit=0", "/users/delta?limit=501", "/users/delta?limit=abc"} {
+		w = httptest.NewRecorder()
+		NewUserHandler(&mockUserService{}).GetUserDelta(w, withTenant(httptest.NewRequest(http.MethodGet, target, nil)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+	}
+
+	var got service.UserServiceDeltaFilter
+	deletedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	svc := &mockUserService{
+		deltaFn: func(f service.UserServiceDeltaFilter) (*service.UserServiceDeltaResult, error) {
+			got = f
+			return &service.UserServiceDeltaResult{
+				Data: []service.UserServiceDeltaEntry{
+					{UserUUID: testResourceUUID, User: &service.UserServiceDataResult{UserUUID: testResourceUUID, Username: "alice"}},
+					{UserUUID: testUserUUID, Deleted: true, DeletedAt: &deletedAt},
+				},
+				NextCursor: "next",
+				HasMore:    true,
+			}, nil
+		},
+	}
+	w = httptest.NewRecorder()
+	NewUserHandler(svc).GetUserDelta(w, withTenant(httptest.NewRequest(http.MethodGet, "/users/delta?since=abc", nil)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "abc", got.Since)
+	assert.Equal(t, dto.UserDeltaDefaultLimit, got.Limit)
+	body := w.Body.String()
+	assert.Contains(t, body, `"username":"alice"`)
+	assert.Contains(t, body, `"deleted":true,"deleted_at":"2026-01-02T03:04:05Z"`)
+	assert.Contains(t, body, `"next_cursor":"next","has_more":true`)
+
+	svc.deltaFn = func(service.UserServiceDeltaFilter) (*service.UserServiceDeltaResult, error) {
+		return nil, errValidation
+	}
+	w = httptest.NewRecorder()
+	NewUserHandler(svc).GetUserDelta(w, withTenant(httptest.NewRequest(http.MethodGet, "/users/delta?since=bogus", nil)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUserHandler_ExportUsers(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	users := []service.UserServiceDataResult{
+		{UserUUID: testResourceUUID, Username: "alice", Email: "alice@example.com", Status: model.StatusActive, IsEmailVerified: true, Metadata: datatypes.JSON(`{"team":"a"}`), CreatedAt: createdAt},
+		{UserUUID: testUserUUID, Username: "bob", Fullname: "Bob, Jr.", Status: model.StatusPending, CreatedAt: createdAt},
+	}
+	exportAll := func(got *service.UserServiceGetFilter) *mockUserService {
+		return &mockUserService{
+			exportFn: func(f service.UserServiceGetFilter, fn func(service.UserServiceDataResult) error) error {
+				if got != nil {
+					*got = f
+				}
+				for _, u := range users {
+					if err := fn(u); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+	}
+	get := func(target string) *http.Request {
+		return withTenant(httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	t.Run("no tenant", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewUserHandler(&mockUserService{}).ExportUsers(w, httptest.NewRequest(http.MethodGet, "/users/export", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for _, target := range []string{
+			"/users/export?format=xml",
+			"/users/export?columns=password",
+			"/users/export?columns=email,email",
+			"/users/export?status=unknown",
+			"/users/export?role_id=bad",
+		} {
+			w := httptest.NewRecorder()
+			NewUserHandler(&mockUserService{}).ExportUsers(w, get(target))
+			assert.Equal(t, http.StatusBadRequest, w.Code, target)
+		}
+	})
+
+	t.Run("csv with all columns", func(t *testing.T) {
+		var got service.UserServiceGetFilter
+		w := httptest.NewRecorder()
+		NewUserHandler(exportAll(&got)).ExportUsers(w, get("/users/export?status=active,pending&username=a"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "users.csv")
+		assert.Equal(t, tenantID, got.TenantID)
+		assert.Equal(t, []string{"active", "pending"}, got.Status)
+		assert.Equal(t, "a", *got.Username)
+
+		lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+		assert.Len(t, lines, 3)
+		assert.Equal(t, strings.Join(dto.UserExportColumns, ","), lines[0])
+		assert.Equal(t, testResourceUUID.String()+`,alice,,alice@example.com,,active,true,false,false,false,"{""team"":""a""}",2026-01-02T03:04:05Z,0001-01-01T00:00:00Z,`, lines[1])
+		assert.Contains(t, lines[2], `"Bob, Jr."`)
+	})
+
+	t.Run("ndjson with selected columns", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewUserHandler(exportAll(nil)).ExportUsers(w, get("/users/export?format=ndjson&columns=username,metadata,is_email_verified"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+		assert.Equal(t,
+			`{"username":"alice","metadata":{"team":"a"},"is_email_verified":true}`+"\n"+
+				`{"username":"bob","metadata":null,"is_email_verified":false}`+"\n",
+			w.Body.String())
+	})
+
+	t.Run("no users", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewUserHandler(&mockUserService{}).ExportUsers(w, get("/users/export?columns=user_id,email"))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "user_id,email\n", w.Body.String())
+	})
+
+	t.Run("error before the first user", func(t *testing.T) {
+		svc := &mockUserService{
+			exportFn: func(service.UserServiceGetFilter, func(service.UserServiceDataResult) error) error {
+				return errNotFound
+			},
+		}
+		w := httptest.NewRecorder()
+		NewUserHandler(svc).ExportUsers(w, get("/users/export"))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("error after streaming started aborts the response", func(t *testing.T) {
+		svc := &mockUserService{
+			exportFn: func(_ service.UserServiceGetFilter, fn func(service.UserServiceDataResult) error) error {
+				if err := fn(users[0]); err != nil {
+					return err
+				}
+				return errors.New("db error")
+			},
+		}
+		w := httptest.NewRecorder()
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			NewUserHandler(svc).ExportUsers(w, get("/users/export"))
+		})
+	})
+}
+
+func TestUserHandler_GetUsers_Cursor(t *testing.T) {
+	for _, target := range []string{"/users?cursor=", "/users?cursor=&limit=10&sort_by=username"} {
+		w := httptest.NewRecorder()
+		NewUserHandler(&mockUserService{}).GetUsers(w, withTenant(httptest.NewRequest(http.MethodGet, target, nil)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+	}
+
+	var gotFilter service.UserServiceGetFilter
+	var gotCursor string
+	svc := &mockUserService{
+		getFn: func(service.UserServiceGetFilter) (*service.UserServiceGetResult, error) {
+			t.Fatal("offset pagination must not be used")
+			return nil, nil
+		},
+		getByCursorFn: func(f service.UserServiceGetFilter, cursor string) (*service.CursorPageResult[service.UserServiceDataResult], error) {
+			gotFilter, gotCursor = f, cursor
+			return &service.CursorPageResult[service.UserServiceDataResult]{
+				Data:  []service.UserServiceDataResult{{UserUUID: testResourceUUID, Username: "alice"}},
+				Limit: 25,
+			}, nil
+		},
+	}
+	w := httptest.NewRecorder()
+	NewUserHandler(svc).GetUsers(w, withTenant(httptest.NewRequest(http.MethodGet, "/users?cursor=&limit=25&status=active", nil)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, gotCursor)
+	assert.Equal(t, 25, gotFilter.Limit)
+	assert.Equal(t, tenantID, gotFilter.TenantID)
+	assert.Equal(t, []string{"active"}, gotFilter.Status)
+	body := w.Body.String()
+	assert.Contains(t, body, `"username":"alice"`)
+	assert.Contains(t, body, `"has_more":false`)
+	assert.NotContains(t, body, `"next_cursor"`)
+
+	svc.getByCursorFn = func(service.UserServiceGetFilter, string) (*service.CursorPageResult[service.UserServiceDataResult], error) {
+		return nil, errValidation
+	}
+	w = httptest.NewRecorder()
+	NewUserHandler(svc).GetUsers(w, withTenant(httptest.NewRequest(http.MethodGet, "/users?cursor=bad&limit=25", nil)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 // ---------------------------------------------------------------------------
 // GetUsers – missing branches
 // ---------------------------------------------------------------------------
@@ -169,6 +539,81 @@ func TestUserHandler_GetUsers_WithFiltersAndRows(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestUserHandler_GetUsers_MultiValueStatus(t *testing.T) {
+	var got []string
+	svc := &mockUserService{
+		getFn: func(f service.UserServiceGetFilter) (*service.UserServiceGetResult, error) {
+			got = f.Status
+			return &service.UserServiceGetResult{}, nil
+		},
+	}
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/users?page=1&limit=10&status=active,pending&status=suspended", nil))
+	w := httptest.NewRecorder()
+	NewUserHandler(svc).GetUsers(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"active", "pending", "suspended"}, got)
+}
+
+func TestUserHandler_GetUsers_InvalidStatusInList(t *testing.T) {
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/users?page=1&limit=10&status=active,bogus", nil))
+	w := httptest.NewRecorder()
+	NewUserHandler(&mockUserService{}).GetUsers(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUserHandler_GetUsers_Include(t *testing.T) {
+	var got service.UserServiceGetFilter
+	svc := &mockUserService{
+		getFn: func(f service.UserServiceGetFilter) (*service.UserServiceGetResult, error) {
+			got = f
+			identities := []service.UserIdentityServiceDataResult{{
+				Provider: "default",
+				Client:   &service.ClientServiceDataResult{Name: "spa"},
+			}}
+			roles := []service.RoleServiceDataResult{{Name: "editor"}}
+			return &service.UserServiceGetResult{
+				Data: []service.UserServiceDataResult{
+					{Username: "u1", UserIdentities: &identities, Roles: &roles},
+					{Username: "u2"},
+				},
+				Total: 2, Page: 1, Limit: 10, TotalPages: 1,
+			}, nil
+		},
+	}
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/users?page=1&limit=10&include=identities,roles", nil))
+	w := httptest.NewRecorder()
+	NewUserHandler(svc).GetUsers(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, got.IncludeIdentities)
+	assert.True(t, got.IncludeRoles)
+	body := w.Body.String()
+	assert.Contains(t, body, `"identities":[{`)
+	assert.Contains(t, body, `"name":"spa"`)
+	assert.Contains(t, body, `"roles":[{`)
+	assert.Contains(t, body, `"name":"editor"`)
+	assert.Equal(t, 1, strings.Count(body, `"identities"`))
+
+	r = withTenant(httptest.NewRequest(http.MethodGet, "/users?page=1&limit=10&include=sessions", nil))
+	w = httptest.NewRecorder()
+	NewUserHandler(svc).GetUsers(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUserHandler_GetUser_OmitsRelations(t *testing.T) {
+	identities := []service.UserIdentityServiceDataResult{{Provider: "default"}}
+	svc := &mockUserService{
+		getByUUIDFn: func(uuid.UUID, int64) (*service.UserServiceDataResult, error) {
+			return &service.UserServiceDataResult{Username: "u1", UserIdentities: &identities}, nil
+		},
+	}
+	r := withTenant(withChiParam(httptest.NewRequest(http.MethodGet, "/users/x", nil), "user_uuid", testResourceUUID.String()))
+	w := httptest.NewRecorder()
+	NewUserHandler(svc).GetUser(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), `"identities"`)
+}
+
 // ---------------------------------------------------------------------------
 // GetUser – success with Tenant covers toUserResponseDTO Tenant branch
 // ---------------------------------------------------------------------------
@@ -231,6 +676,13 @@ func TestUserHandler_UpdateUser(t *testing.T) {
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 	})
 
+	t.Run("API key without user returns 401", func(t *testing.T) {
+		r := withTenant(withChiParam(jsonReq(t, http.MethodPut, "/", validBody), "user_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewUserHandler(&mockUserService{}).UpdateUser(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
 	t.Run("invalid UUID returns 400", func(t *testing.T) {
 		r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPut, "/", validBody), "user_uuid", "bad"))
 		w := httptest.NewRecorder()
@@ -285,6 +737,13 @@ func TestUserHandler_SetUserStatus(t *testing.T) {
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 	})
 
+	t.Run("API key without user returns 401", func(t *testing.T) {
+		r := withTenant(withChiParam(jsonReq(t, http.MethodPatch, "/", map[string]any{"status": "active"}), "user_uuid", testResourceUUID.String()))
+		w := httptest.NewRecorder()
+		NewUserHandler(&mockUserService{}).SetUserStatus(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
 	t.Run("invalid UUID returns 400", func(t *testing.T) {
 		r := withTenantAndUser(withChiParam(jsonReq(t, http.MethodPatch, "/", map[string]any{"status": "active"}), "user_uuid", "bad"))
 		w := httptest.NewRecorder()
@@ -521,17 +980,9 @@ func TestUserHandler_RemoveRole(t *testing.T) {
 }
 
 // ---------------------------------------------------------------------------
-// GetUserRoles – covers containsIgnoreCase, sortRoles, pagination branches
+// GetUserRoles
 // ---------------------------------------------------------------------------
 
-// twoRoles returns two roles for sort comparator coverage (need ≥ 2 elements).
-func twoRoles() []service.RoleServiceDataResult {
-	return []service.RoleServiceDataResult{
-		{Name: "admin-role", Description: "Admin description", Status: "active"},
-		{Name: "member-role", Description: "Member description", Status: "inactive"},
-	}
-}
-
 func userRolesReq(t *testing.T, extraQuery string, userUUID uuid.UUID) *http.Request {
 	t.Helper()
 	url := "/?page=1&limit=10" + extraQuery
@@ -539,17 +990,6 @@ func userRolesReq(t *testing.T, extraQuery string, userUUID uuid.UUID) *http.Req
 	return r
 }
 
-func userRolesSvc(roles []service.RoleServiceDataResult) *mockUserService {
-	return &mockUserService{
-		getByUUIDFn: func(uuid.UUID, int64) (*service.UserServiceDataResult, error) {
-			return &service.UserServiceDataResult{}, nil
-		},
-		getUserRolesFn: func(uuid.UUID) ([]service.RoleServiceDataResult, error) {
-			return roles, nil
-		},
-	}
-}
-
 func TestUserHandler_GetUserRoles(t *testing.T) {
 	t.Run("invalid UUID returns 400", func(t *testing.T) {
 		r := withTenant(withChiParam(httptest.NewRequest(http.MethodGet, "/?page=1&limit=10", nil), "user_uuid", "bad"))
@@ -581,12 +1021,12 @@ func TestUserHandler_GetUserRoles(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, w.Code)
 	})
 
-	t.Run("GetUserRoles error returns 500", func(t *testing.T) {
+	t.Run("ListUserRoles error returns 500", func(t *testing.T) {
 		svc := &mockUserService{
 			getByUUIDFn: func(uuid.UUID, int64) (*service.UserServiceDataResult, error) {
 				return &service.UserServiceDataResult{}, nil
 			},
-			getUserRolesFn: func(uuid.UUID) ([]service.RoleServiceDataResult, error) {
+			listUserRolesFn: func(service.UserServiceGetRolesFilter) (*service.RoleServiceGetResult, error) {
 				return nil, errors.New("db error")
 			},
 		}
@@ -595,93 +1035,47 @@ func TestUserHandler_GetUserRoles(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 	})
 
-	t.Run("success with filters covers containsIgnoreCase and pagination end>len branch", func(t *testing.T) {
-		// name+description+status filters; one role matches, one doesn't
-		w := httptest.NewRecorder()
-		NewUserHandler(userRolesSvc(twoRoles())).GetUserRoles(w,
-			userRolesReq(t, "&name=admin&description=admin&status=active&sort_by=name&sort_order=asc", testResourceUUID))
-		assert.Equal(t, http.StatusOK, w.Code)
-	})
-
-	t.Run("sort_by=description covers description case", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		NewUserHandler(userRolesSvc(twoRoles())).GetUserRoles(w, userRolesReq(t, "&sort_by=description", testResourceUUID))
-		assert.Equal(t, http.StatusOK, w.Code)
-	})
-
-	t.Run("sort_by=status covers status case", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		NewUserHandler(userRolesSvc(twoRoles())).GetUserRoles(w, userRolesReq(t, "&sort_by=status", testResourceUUID))
-		assert.Equal(t, http.StatusOK, w.Code)
-	})
-
-	t.Run("sort_by=created_at covers created_at case", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		NewUserHandler(userRolesSvc(twoRoles())).GetUserRoles(w, userRolesReq(t, "&sort_by=created_at", testResourceUUID))
-		assert.Equal(t, http.StatusOK, w.Code)
-	})
-
-	t.Run("sort_by=updated_at covers updated_at case", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		NewUserHandler(userRolesSvc(twoRoles())).GetUserRoles(w, userRolesReq(t, "&sort_by=updated_at", testResourceUUID))
-		assert.Equal(t, http.StatusOK, w.Code)
-	})
-
-	t.Run("description-only filter hits description continue branch", func(t *testing.T) {
-		// "member-role" has Description "Member description" which does NOT contain "Admin",
-		// so it hits the continue at line 644; "admin-role" passes and is included.
-		w := httptest.NewRecorder()
-		NewUserHandler(userRolesSvc(twoRoles())).GetUserRoles(w,
-			userRolesReq(t, "&description=Admin", testResourceUUID))
-		assert.Equal(t, http.StatusOK, w.Code)
-	})
-
-	t.Run("status-only filter hits status continue branch", func(t *testing.T) {
-		// "member-role" has Status "inactive" which != "active", so it hits the continue
-		// at line 648; "admin-role" passes and is included.
-		w := httptest.NewRecorder()
-		NewUserHandler(userRolesSvc(twoRoles())).GetUserRoles(w,
-			userRolesReq(t, "&status=active", testResourceUUID))
-		assert.Equal(t, http.StatusOK, w.Code)
-	})
-
-	t.Run("sort_by=name with two roles covers name sort comparator", func(t *testing.T) {
-		// No filter so both roles remain; sort.Slice calls comparator on 2 elements → covers line 833.
-		w := httptest.NewRecorder()
-		NewUserHandler(userRolesSvc(twoRoles())).GetUserRoles(w,
-			userRolesReq(t, "&sort_by=name&sort_order=asc", testResourceUUID))
-		assert.Equal(t, http.StatusOK, w.Code)
-	})
-
-	t.Run("sort_by=other+desc covers default case and desc branch", func(t *testing.T) {
+	t.Run("success passes filters to the service", func(t *testing.T) {
+		var got service.UserServiceGetRolesFilter
+		svc := &mockUserService{
+			getByUUIDFn: func(uuid.UUID, int64) (*service.UserServiceDataResult, error) {
+				return &service.UserServiceDataResult{UserUUID: testResourceUUID}, nil
+			},
+			listUserRolesFn: func(f service.UserServiceGetRolesFilter) (*service.RoleServiceGetResult, error) {
+				got = f
+				return &service.RoleServiceGetResult{
+					Data:  []service.RoleServiceDataResult{{Name: "admin-role", Status: "active"}},
+					Total: 11, Page: 2, Limit: 10, TotalPages: 2,
+				}, nil
+			},
+		}
 		w := httptest.NewRecorder()
-		NewUserHandler(userRolesSvc(twoRoles())).GetUserRoles(w, userRolesReq(t, "&sort_by=other&sort_order=desc", testResourceUUID))
-		assert.Equal(t, http.StatusOK, w.Code)
-	})
+		r := withTenant(withChiParam(httptest.NewRequest(http.MethodGet,
+			"/?page=2&limit=10&name=admin&description=admin&status=active&sort_by=name&sort_order=desc", nil),
+			"user_uuid", testResourceUUID.String()))
+		NewUserHandler(svc).GetUserRoles(w, r)
 
-	t.Run("large page covers offset>len branch", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		r := withTenant(withChiParam(httptest.NewRequest(http.MethodGet, "/?page=1000&limit=10", nil), "user_uuid", testResourceUUID.String()))
-		NewUserHandler(userRolesSvc(twoRoles())).GetUserRoles(w, r)
 		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, testResourceUUID, got.UserUUID)
+		assert.Equal(t, tenantID, got.TenantID)
+		assert.Equal(t, "admin", *got.Name)
+		assert.Equal(t, "admin", *got.Description)
+		assert.Equal(t, "active", *got.Status)
+		assert.Equal(t, 2, got.Page)
+		assert.Equal(t, 10, got.Limit)
+		assert.Equal(t, "name", got.SortBy)
+		assert.Equal(t, "desc", got.SortOrder)
+		body := w.Body.String()
+		assert.Contains(t, body, `"name":"admin-role"`)
+		assert.Contains(t, body, `"total":11`)
+		assert.Contains(t, body, `"total_pages":2`)
 	})
 }
 
 // ---------------------------------------------------------------------------
-// GetUserIdentities – covers sortIdentities, Client != nil branch, pagination
+// GetUserIdentities
 // ---------------------------------------------------------------------------
 
-func twoIdentities(withClient bool) []service.UserIdentityServiceDataResult {
-	var client *service.ClientServiceDataResult
-	if withClient {
-		client = &service.ClientServiceDataResult{Name: "web-app"}
-	}
-	return []service.UserIdentityServiceDataResult{
-		{Provider: "google", Sub: "sub-1", Client: client},
-		{Provider: "github", Sub: "sub-2"},
-	}
-}
-
 func userIdentitiesReq(t *testing.T, extraQuery string, userUUID uuid.UUID) *http.Request {
 	t.Helper()
 	url := "/?page=1&limit=10" + extraQuery
@@ -689,17 +1083,6 @@ func userIdentitiesReq(t *testing.T, extraQuery string, userUUID uuid.UUID) *htt
 	return r
 }
 
-func userIdentitiesSvc(identities []service.UserIdentityServiceDataResult) *mockUserService {
-	return &mockUserService{
-		getByUUIDFn: func(uuid.UUID, int64) (*service.UserServiceDataResult, error) {
-			return &service.UserServiceDataResult{}, nil
-		},
-		getUserIdentsFn: func(uuid.UUID) ([]service.UserIdentityServiceDataResult, error) {
-			return identities, nil
-		},
-	}
-}
-
 func TestUserHandler_GetUserIdentities(t *testing.T) {
 	t.Run("invalid UUID returns 400", func(t *testing.T) {
 		r := withTenant(withChiParam(httptest.NewRequest(http.MethodGet, "/?page=1&limit=10", nil), "user_uuid", "bad"))
@@ -731,12 +1114,12 @@ func TestUserHandler_GetUserIdentities(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, w.Code)
 	})
 
-	t.Run("GetUserIdentities error returns 500", func(t *testing.T) {
+	t.Run("ListUserIdentities error returns 500", func(t *testing.T) {
 		svc := &mockUserService{
 			getByUUIDFn: func(uuid.UUID, int64) (*service.UserServiceDataResult, error) {
 				return &service.UserServiceDataResult{}, nil
 			},
-			getUserIdentsFn: func(uuid.UUID) ([]service.UserIdentityServiceDataResult, error) {
+			listUserIdentsFn: func(service.UserServiceGetIdentitiesFilter) (*service.UserIdentityServiceGetResult, error) {
 				return nil, errors.New("db error")
 			},
 		}
@@ -745,53 +1128,49 @@ func TestUserHandler_GetUserIdentities(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 	})
 
-	t.Run("success with Client!=nil covers Client branch", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		NewUserHandler(userIdentitiesSvc(twoIdentities(true))).GetUserIdentities(w,
-			userIdentitiesReq(t, "&provider=google", testResourceUUID))
-		assert.Equal(t, http.StatusOK, w.Code)
-	})
-
-	t.Run("sort_by=provider with two identities covers provider sort comparator", func(t *testing.T) {
-		// No provider filter so both identities remain; sort.Slice calls comparator → covers line 858.
-		w := httptest.NewRecorder()
-		NewUserHandler(userIdentitiesSvc(twoIdentities(false))).GetUserIdentities(w,
-			userIdentitiesReq(t, "&sort_by=provider&sort_order=asc", testResourceUUID))
-		assert.Equal(t, http.StatusOK, w.Code)
-	})
-
-	t.Run("sort_by=sub covers sub case", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		NewUserHandler(userIdentitiesSvc(twoIdentities(false))).GetUserIdentities(w,
-			userIdentitiesReq(t, "&sort_by=sub", testResourceUUID))
-		assert.Equal(t, http.StatusOK, w.Code)
-	})
-
-	t.Run("sort_by=created_at covers created_at case", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		NewUserHandler(userIdentitiesSvc(twoIdentities(false))).GetUserIdentities(w,
-			userIdentitiesReq(t, "&sort_by=created_at", testResourceUUID))
-		assert.Equal(t, http.StatusOK, w.Code)
-	})
-
-	t.Run("sort_by=updated_at covers updated_at case", func(t *testing.T) {
+	t.Run("success passes filters to the service", func(t *testing.T) {
+		var got service.UserServiceGetIdentitiesFilter
+		svc := &mockUserService{
+			getByUUIDFn: func(uuid.UUID, int64) (*service.UserServiceDataResult, error) {
+				return &service.UserServiceDataResult{UserUUID: testResourceUUID}, nil
+			},
+			listUserIdentsFn: func(f service.UserServiceGetIdentitiesFilter) (*service.UserIdentityServiceGetResult, error) {
+				got = f
+				return &service.UserIdentityServiceGetResult{
+					Data: []service.UserIdentityServiceDataResult{
+						{Provider: "google", Sub: "sub-1", Client: &service.ClientServiceDataResult{Name: "web-app"}},
+						{Provider: "github", Sub: "sub-2"},
+					},
+					Total: 2, Page: 1, Limit: 10, TotalPages: 1,
+				}, nil
+			},
+		}
 		w := httptest.NewRecorder()
-		NewUserHandler(userIdentitiesSvc(twoIdentities(false))).GetUserIdentities(w,
-			userIdentitiesReq(t, "&sort_by=updated_at", testResourceUUID))
-		assert.Equal(t, http.StatusOK, w.Code)
-	})
+		NewUserHandler(svc).GetUserIdentities(w,
+			userIdentitiesReq(t, "&provider=goo&sort_by=provider&sort_order=asc", testResourceUUID))
 
-	t.Run("sort_by=other+desc covers default case and desc branch", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		NewUserHandler(userIdentitiesSvc(twoIdentities(false))).GetUserIdentities(w,
-			userIdentitiesReq(t, "&sort_by=other&sort_order=desc", testResourceUUID))
 		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, testResourceUUID, got.UserUUID)
+		assert.Equal(t, tenantID, got.TenantID)
+		assert.Equal(t, "goo", *got.Provider)
+		assert.Equal(t, "provider", got.SortBy)
+		assert.Equal(t, "asc", got.SortOrder)
+		body := w.Body.String()
+		assert.Contains(t, body, `"name":"web-app"`)
+		assert.Contains(t, body, `"total":2`)
 	})
+}
 
-	t.Run("large page covers offset>len branch", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		r := withTenant(withChiParam(httptest.NewRequest(http.MethodGet, "/?page=1000&limit=10", nil), "user_uuid", testResourceUUID.String()))
-		NewUserHandler(userIdentitiesSvc(twoIdentities(false))).GetUserIdentities(w, r)
-		assert.Equal(t, http.StatusOK, w.Code)
-	})
+func TestUserHandler_GetUsers_IncludeSubtenants(t *testing.T) {
+	var got bool
+	svc := &mockUserService{
+		getFn: func(f service.UserServiceGetFilter) (*service.UserServiceGetResult, error) {
+			got = f.IncludeSubtenants
+			return &service.UserServiceGetResult{}, nil
+		},
+	}
+	w := httptest.NewRecorder()
+	NewUserHandler(svc).GetUsers(w, withTenant(httptest.NewRequest(http.MethodGet, "/users?page=1&limit=10&include_subtenants=true", nil)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, got)
 }
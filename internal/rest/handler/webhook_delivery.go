@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	resp "github.com/maintainerd/auth/internal/rest/response"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// WebhookDeliveryHandler handles HTTP requests for the delivery log of
+// webhook endpoints.
+type WebhookDeliveryHandler struct {
+	webhookDeliveryService service.WebhookDeliveryService
+}
+
+// NewWebhookDeliveryHandler creates a new WebhookDeliveryHandler.
+func NewWebhookDeliveryHandler(webhookDeliveryService service.WebhookDeliveryService) *WebhookDeliveryHandler {
+	return &WebhookDeliveryHandler{webhookDeliveryService: webhookDeliveryService}
+}
+
+// GetAll retrieves the deliveries of a webhook endpoint, newest first, with
+// optional status and event type filters.
+//
+// GET /webhook-endpoints/{webhook_endpoint_uuid}/deliveries
+func (h *WebhookDeliveryHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	webhookUUID, err := uuid.Parse(chi.URLParam(r, "webhook_endpoint_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid webhook endpoint UUID")
+		return
+	}
+
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+
+	filter := dto.WebhookDeliveryFilterDTO{
+		Status:    queryValues(q, "status"),
+		EventType: queryValues(q, "event_type"),
+		PaginationRequestDTO: dto.PaginationRequestDTO{
+			Page:  page,
+			Limit: limit,
+		},
+	}
+
+	if err := filter.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.webhookDeliveryService.GetAll(
+		r.Context(), tenant.TenantID, webhookUUID,
+		filter.Status, filter.EventType,
+		filter.Page, filter.Limit,
+	)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to get webhook deliveries", err)
+		return
+	}
+
+	rows := make([]dto.WebhookDeliveryResponseDTO, len(result.Data))
+	for i, d := range result.Data {
+		rows[i] = toWebhookDeliveryResponseDTO(d, false)
+	}
+
+	resp.Success(w, dto.PaginatedResponseDTO[dto.WebhookDeliveryResponseDTO]{
+		Rows:       rows,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}, "Webhook deliveries retrieved successfully")
+}
+
+// Get retrieves a single delivery of a webhook endpoint, including the
+// payload that was sent.
+//
+// GET /webhook-endpoints/{webhook_endpoint_uuid}/deliveries/{webhook_delivery_uuid}
+func (h *WebhookDeliveryHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	webhookUUID, err := uuid.Parse(chi.URLParam(r, "webhook_endpoint_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid webhook endpoint UUID")
+		return
+	}
+	deliveryUUID, err := uuid.Parse(chi.URLParam(r, "webhook_delivery_uuid"))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid webhook delivery UUID")
+		return
+	}
+
+	result, err := h.webhookDeliveryService.GetByUUID(r.Context(), tenant.TenantID, webhookUUID, deliveryUUID)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Webhook delivery not found", err)
+		return
+	}
+
+	resp.Success(w, toWebhookDeliveryResponseDTO(*result, true), "Webhook delivery retrieved successfully")
+}
+
+func toWebhookDeliveryResponseDTO(d service.WebhookDeliveryServiceDataResult, withPayload bool) dto.WebhookDeliveryResponseDTO {
+	out := dto.WebhookDeliveryResponseDTO{
+		WebhookDeliveryID: d.WebhookDeliveryUUID.String(),
+		EventID:           d.EventUUID.String(),
+		EventType:         d.EventType,
+		Status:            d.Status,
+		Attempts:          d.Attempts,
+		NextAttemptAt:     d.NextAttemptAt,
+		LastAttemptAt:     d.LastAttemptAt,
+		LastStatusCode:    d.LastStatusCode,
+		LastError:         d.LastError,
+		LastDurationMs:    d.LastDurationMs,
+		DeliveredAt:       d.DeliveredAt,
+		CreatedAt:         d.CreatedAt,
+		UpdatedAt:         d.UpdatedAt,
+	}
+	if withPayload {
+		out.Payload = d.Payload
+	}
+	return out
+}
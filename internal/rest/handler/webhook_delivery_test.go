@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func webhookDeliveryResult() *service.WebhookDeliveryServiceDataResult {
+	code := http.StatusOK
+	return &service.WebhookDeliveryServiceDataResult{
+		WebhookDeliveryUUID: uuid.New(),
+		EventUUID:           uuid.New(),
+		EventType:           "user.created",
+		Payload:             json.RawMessage(`{"type":"user.created"}`),
+		Status:              "succeeded",
+		Attempts:            1,
+		LastStatusCode:      &code,
+	}
+}
+
+// ---------------------------------------------------------------------------
+// GetAll
+// ---------------------------------------------------------------------------
+
+func TestWebhookDeliveryHandler_GetAll_NoTenant(t *testing.T) {
+	h := NewWebhookDeliveryHandler(&mockWebhookDeliveryService{})
+	w := httptest.NewRecorder()
+	h.GetAll(w, httptest.NewRequest(http.MethodGet, "/webhook-endpoints/abc/deliveries", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestWebhookDeliveryHandler_GetAll_InvalidUUID(t *testing.T) {
+	h := NewWebhookDeliveryHandler(&mockWebhookDeliveryService{})
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/webhook-endpoints/not-a-uuid/deliveries", nil))
+	r = withChiParam(r, "webhook_endpoint_uuid", "not-a-uuid")
+	w := httptest.NewRecorder()
+	h.GetAll(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestWebhookDeliveryHandler_GetAll_InvalidStatus(t *testing.T) {
+	h := NewWebhookDeliveryHandler(&mockWebhookDeliveryService{})
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/webhook-endpoints/x/deliveries?page=1&limit=10&status=unknown", nil))
+	r = withChiParam(r, "webhook_endpoint_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.GetAll(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestWebhookDeliveryHandler_GetAll_ServiceError(t *testing.T) {
+	svc := &mockWebhookDeliveryService{
+		getAllFn: func(int64, uuid.UUID, []string, []string, int, int) (*service.WebhookDeliveryServiceListResult, error) {
+			return nil, errNotFound
+		},
+	}
+	h := NewWebhookDeliveryHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/webhook-endpoints/x/deliveries?page=1&limit=10", nil))
+	r = withChiParam(r, "webhook_endpoint_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.GetAll(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWebhookDeliveryHandler_GetAll_Success(t *testing.T) {
+	var gotStatus, gotTypes []string
+	svc := &mockWebhookDeliveryService{
+		getAllFn: func(_ int64, endpointUUID uuid.UUID, status, eventType []string, page, limit int) (*service.WebhookDeliveryServiceListResult, error) {
+			assert.Equal(t, testResourceUUID, endpointUUID)
+			gotStatus, gotTypes = status, eventType
+			return &service.WebhookDeliveryServiceListResult{
+				Data:  []service.WebhookDeliveryServiceDataResult{*webhookDeliveryResult()},
+				Total: 1, Page: page, Limit: limit, TotalPages: 1,
+			}, nil
+		},
+	}
+	h := NewWebhookDeliveryHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/webhook-endpoints/x/deliveries?page=1&limit=10&status=failed,pending&event_type=user.created", nil))
+	r = withChiParam(r, "webhook_endpoint_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.GetAll(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"failed", "pending"}, gotStatus)
+	assert.Equal(t, []string{"user.created"}, gotTypes)
+	assert.NotContains(t, w.Body.String(), `"payload"`)
+}
+
+// ---------------------------------------------------------------------------
+// Get
+// ---------------------------------------------------------------------------
+
+func TestWebhookDeliveryHandler_Get_InvalidDeliveryUUID(t *testing.T) {
+	h := NewWebhookDeliveryHandler(&mockWebhookDeliveryService{})
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/webhook-endpoints/x/deliveries/y", nil))
+	r = withChiParam(r, "webhook_endpoint_uuid", testResourceUUID.String())
+	r = withChiParam(r, "webhook_delivery_uuid", "not-a-uuid")
+	w := httptest.NewRecorder()
+	h.Get(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestWebhookDeliveryHandler_Get_NotFound(t *testing.T) {
+	svc := &mockWebhookDeliveryService{
+		getByUUIDFn: func(int64, uuid.UUID, uuid.UUID) (*service.WebhookDeliveryServiceDataResult, error) {
+			return nil, errNotFound
+		},
+	}
+	h := NewWebhookDeliveryHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/webhook-endpoints/x/deliveries/y", nil))
+	r = withChiParam(r, "webhook_endpoint_uuid", testResourceUUID.String())
+	r = withChiParam(r, "webhook_delivery_uuid", uuid.NewString())
+	w := httptest.NewRecorder()
+	h.Get(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWebhookDeliveryHandler_Get_Success(t *testing.T) {
+	svc := &mockWebhookDeliveryService{
+		getByUUIDFn: func(int64, uuid.UUID, uuid.UUID) (*service.WebhookDeliveryServiceDataResult, error) {
+			return webhookDeliveryResult(), nil
+		},
+	}
+	h := NewWebhookDeliveryHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodGet, "/webhook-endpoints/x/deliveries/y", nil))
+	r = withChiParam(r, "webhook_endpoint_uuid", testResourceUUID.String())
+	r = withChiParam(r, "webhook_delivery_uuid", uuid.NewString())
+	w := httptest.NewRecorder()
+	h.Get(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"payload":{"type":"user.created"}`)
+}
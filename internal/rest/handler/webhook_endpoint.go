@@ -2,8 +2,11 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -39,10 +42,8 @@ func (h *WebhookEndpointHandler) GetAll(w http.ResponseWriter, r *http.Request)
 	page, _ := strconv.Atoi(q.Get("page"))
 	limit, _ := strconv.Atoi(q.Get("limit"))
 
-	var status []string
-	if v := q.Get("status"); v != "" {
-		status = append(status, v)
-	}
+	// Parse status filter (comma-separated or repeated values)
+	status := queryValues(q, "status")
 
 	filter := dto.WebhookEndpointFilterDTO{
 		Status: status,
@@ -257,6 +258,49 @@ func (h *WebhookEndpointHandler) UpdateStatus(w http.ResponseWriter, r *http.Req
 	resp.Success(w, toWebhookEndpointResponseDTO(*result), "Webhook endpoint status updated successfully")
 }
 
+// RotateSecret replaces the signing secret of a webhook endpoint. The new
+// secret is returned once; the previous one keeps signing deliveries for the
+// requested grace period.
+func (h *WebhookEndpointHandler) RotateSecret(w http.ResponseWriter, r *http.Request) {
+	tenant := middleware.AuthFromRequest(r).Tenant
+	if tenant == nil {
+		resp.Error(w, http.StatusUnauthorized, "Tenant not found in context")
+		return
+	}
+
+	webhookUUIDStr := chi.URLParam(r, "webhook_endpoint_uuid")
+	webhookUUID, err := uuid.Parse(webhookUUIDStr)
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, "Invalid webhook endpoint UUID")
+		return
+	}
+
+	// The body is optional; an empty one rotates with the default grace period.
+	var req dto.WebhookEndpointRotateSecretRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		resp.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		resp.ValidationError(w, err)
+		return
+	}
+
+	grace := service.DefaultWebhookSecretGracePeriod
+	if req.GracePeriodSeconds != nil {
+		grace = time.Duration(*req.GracePeriodSeconds) * time.Second
+	}
+
+	result, err := h.webhookEndpointService.RotateSecret(r.Context(), tenant.TenantID, webhookUUID, grace)
+	if err != nil {
+		resp.HandleServiceError(w, r, "Failed to rotate webhook endpoint secret", err)
+		return
+	}
+
+	resp.Success(w, toWebhookEndpointResponseDTO(*result), "Webhook endpoint secret rotated successfully")
+}
+
 func toWebhookEndpointResponseDTO(we service.WebhookEndpointServiceDataResult) dto.WebhookEndpointResponseDTO {
 	var lastTriggered *string
 	if we.LastTriggeredAt != nil {
@@ -264,17 +308,26 @@ func toWebhookEndpointResponseDTO(we service.WebhookEndpointServiceDataResult) d
 		lastTriggered = &formatted
 	}
 
+	var secret *string
+	if we.Secret != "" {
+		secret = &we.Secret
+	}
+
 	return dto.WebhookEndpointResponseDTO{
-		WebhookEndpointID: we.WebhookEndpointUUID.String(),
-		URL:               we.URL,
-		Events:            we.Events,
-		MaxRetries:        we.MaxRetries,
-		TimeoutSeconds:    we.TimeoutSeconds,
-		Status:            we.Status,
-		Description:       we.Description,
-		LastTriggeredAt:   lastTriggered,
-		CreatedAt:         we.CreatedAt,
-		UpdatedAt:         we.UpdatedAt,
+		WebhookEndpointID:       we.WebhookEndpointUUID.String(),
+		URL:                     we.URL,
+		Events:                  we.Events,
+		MaxRetries:              we.MaxRetries,
+		TimeoutSeconds:          we.TimeoutSeconds,
+		Status:                  we.Status,
+		Description:             we.Description,
+		HasSecret:               we.HasSecret,
+		Secret:                  secret,
+		SecretRotatedAt:         we.SecretRotatedAt,
+		PreviousSecretExpiresAt: we.PreviousSecretExpiresAt,
+		LastTriggeredAt:         lastTriggered,
+		CreatedAt:               we.CreatedAt,
+		UpdatedAt:               we.UpdatedAt,
 	}
 }
 
@@ -422,3 +422,95 @@ func TestWebhookEndpointHandler_UpdateStatus_Success(t *testing.T) {
 	h.UpdateStatus(w, r)
 	assert.Equal(t, http.StatusOK, w.Code)
 }
+
+// ---------------------------------------------------------------------------
+// RotateSecret
+// ---------------------------------------------------------------------------
+
+func TestWebhookEndpointHandler_RotateSecret_NoTenant(t *testing.T) {
+	h := NewWebhookEndpointHandler(&mockWebhookEndpointService{})
+	w := httptest.NewRecorder()
+	h.RotateSecret(w, httptest.NewRequest(http.MethodPost, "/webhook-endpoints/abc/rotate-secret", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestWebhookEndpointHandler_RotateSecret_InvalidUUID(t *testing.T) {
+	h := NewWebhookEndpointHandler(&mockWebhookEndpointService{})
+	r := withTenant(httptest.NewRequest(http.MethodPost, "/webhook-endpoints/bad/rotate-secret", nil))
+	r = withChiParam(r, "webhook_endpoint_uuid", "bad")
+	w := httptest.NewRecorder()
+	h.RotateSecret(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestWebhookEndpointHandler_RotateSecret_BadJSON(t *testing.T) {
+	h := NewWebhookEndpointHandler(&mockWebhookEndpointService{})
+	r := withTenant(badJSONReq(t, http.MethodPost, "/webhook-endpoints/"+testResourceUUID.String()+"/rotate-secret"))
+	r = withChiParam(r, "webhook_endpoint_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.RotateSecret(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestWebhookEndpointHandler_RotateSecret_ValidationError(t *testing.T) {
+	h := NewWebhookEndpointHandler(&mockWebhookEndpointService{})
+	body := map[string]any{"grace_period_seconds": -1}
+	r := withTenant(jsonReq(t, http.MethodPost, "/webhook-endpoints/"+testResourceUUID.String()+"/rotate-secret", body))
+	r = withChiParam(r, "webhook_endpoint_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.RotateSecret(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestWebhookEndpointHandler_RotateSecret_ServiceError(t *testing.T) {
+	svc := &mockWebhookEndpointService{
+		rotateSecretFn: func(_ int64, _ uuid.UUID, _ time.Duration) (*service.WebhookEndpointServiceDataResult, error) {
+			return nil, assert.AnError
+		},
+	}
+	h := NewWebhookEndpointHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodPost, "/webhook-endpoints/"+testResourceUUID.String()+"/rotate-secret", nil))
+	r = withChiParam(r, "webhook_endpoint_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.RotateSecret(w, r)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestWebhookEndpointHandler_RotateSecret_DefaultGracePeriod(t *testing.T) {
+	var gotGrace time.Duration
+	svc := &mockWebhookEndpointService{
+		rotateSecretFn: func(_ int64, _ uuid.UUID, grace time.Duration) (*service.WebhookEndpointServiceDataResult, error) {
+			gotGrace = grace
+			res := webhookResult()
+			res.Secret = "whsec_new"
+			return res, nil
+		},
+	}
+	h := NewWebhookEndpointHandler(svc)
+	r := withTenant(httptest.NewRequest(http.MethodPost, "/webhook-endpoints/"+testResourceUUID.String()+"/rotate-secret", nil))
+	r = withChiParam(r, "webhook_endpoint_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.RotateSecret(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, service.DefaultWebhookSecretGracePeriod, gotGrace)
+	assert.Contains(t, w.Body.String(), `"secret":"whsec_new"`)
+}
+
+func TestWebhookEndpointHandler_RotateSecret_CustomGracePeriod(t *testing.T) {
+	var gotGrace time.Duration
+	svc := &mockWebhookEndpointService{
+		rotateSecretFn: func(_ int64, _ uuid.UUID, grace time.Duration) (*service.WebhookEndpointServiceDataResult, error) {
+			gotGrace = grace
+			return webhookResult(), nil
+		},
+	}
+	h := NewWebhookEndpointHandler(svc)
+	body := map[string]any{"grace_period_seconds": 0}
+	r := withTenant(jsonReq(t, http.MethodPost, "/webhook-endpoints/"+testResourceUUID.String()+"/rotate-secret", body))
+	r = withChiParam(r, "webhook_endpoint_uuid", testResourceUUID.String())
+	w := httptest.NewRecorder()
+	h.RotateSecret(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Zero(t, gotGrace)
+	assert.NotContains(t, w.Body.String(), `"secret":`)
+}
@@ -0,0 +1,284 @@
+package openapi
+
+import (
+	"maps"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Route documents a registered route.
+type Route struct {
+	// Summary is a one-line description of the operation.
+	Summary string
+	// Public routes are served without authentication.
+	Public bool
+	// Query is a struct whose json tags name the query parameters.
+	Query any
+	// Request is the JSON request body, or the form body when Form is set.
+	Request any
+	// Form marks requests sent as application/x-www-form-urlencoded, such as
+	// the OAuth2 token requests.
+	Form bool
+	// Response is the data member of the success envelope, or the whole
+	// body when Raw is set. Nil for responses without data.
+	Response any
+	// Raw marks responses that are not wrapped in the success envelope,
+	// such as the OAuth2 endpoints.
+	Raw bool
+	// Status is the success status; 200 when zero.
+	Status int
+	// Hidden routes are registered but not part of the documented API,
+	// such as static assets.
+	Hidden bool
+}
+
+// Catalog documents routes by "METHOD /path", with the path as registered
+// in chi. "* /path" documents every method of a path.
+type Catalog map[string]Route
+
+// Port is a router and the server it is reachable on.
+type Port struct {
+	Server Server
+	Routes chi.Routes
+}
+
+// documentedMethods are the methods an OpenAPI path item can hold that the
+// API uses. Routes registered for every method with Handle are documented
+// through their GET.
+var documentedMethods = []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete, http.MethodPatch}
+
+// pathParam matches a chi URL parameter, e.g. "{role_uuid}".
+var pathParam = regexp.MustCompile(`\{(\w+)\}`)
+
+// Build returns the document of the routes of ports. A path served on only
+// some of the ports lists those ports' servers. Routes missing from the
+// catalog are listed without a summary or schemas; see Undocumented.
+func (c Catalog) Build(info Info, ports ...Port) *Document {
+	g := newSchemaGenerator()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]*PathItem{},
+		Components: Components{
+			Schemas: g.schemas,
+			Responses: map[string]*Response{
+				"Problem": {
+					Description: "Error, as an RFC 7807 problem details object",
+					Content:     map[string]*MediaType{"application/problem+json": {Schema: g.object(reflect.TypeFor[problem]())}},
+				},
+			},
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {
+					Type:         "http",
+					Scheme:       "bearer",
+					BearerFormat: "JWT",
+					Description:  "Access token, or a personal access token on the internal port",
+				},
+			},
+		},
+	}
+
+	served := map[string][]Server{}
+	tags := map[string]bool{}
+	for _, port := range ports {
+		doc.Servers = append(doc.Servers, port.Server)
+		walk(port.Routes, func(method, path string) {
+			route, _ := c.lookup(method, path)
+			if route.Hidden || !slices.Contains(documentedMethods, method) {
+				return
+			}
+			item := doc.Paths[path]
+			if item == nil {
+				item = &PathItem{}
+				doc.Paths[path] = item
+			}
+			if op := item.operation(method); *op == nil {
+				*op = route.operation(g, method, path)
+				for _, tag := range (*op).Tags {
+					tags[tag] = true
+				}
+			}
+			if !slices.Contains(served[path], port.Server) {
+				served[path] = append(served[path], port.Server)
+			}
+		})
+	}
+	for path, servers := range served {
+		if len(servers) < len(ports) {
+			doc.Paths[path].Servers = servers
+		}
+	}
+	for _, tag := range slices.Sorted(maps.Keys(tags)) {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+	return doc
+}
+
+// Undocumented returns the routes of routes that are missing from the
+// catalog, as "METHOD /path".
+func (c Catalog) Undocumented(routes chi.Routes) []string {
+	var missing []string
+	walk(routes, func(method, path string) {
+		if _, ok := c.lookup(method, path); !ok {
+			missing = append(missing, method+" "+path)
+		}
+	})
+	slices.Sort(missing)
+	return slices.Compact(missing)
+}
+
+// Unregistered returns the catalog entries that match no route of any of
+// routes, such as the entry of a removed route.
+func (c Catalog) Unregistered(routes ...chi.Routes) []string {
+	registered := map[string]bool{}
+	for _, r := range routes {
+		walk(r, func(method, path string) {
+			registered[method+" "+path] = true
+			registered["* "+path] = true
+		})
+	}
+	var stale []string
+	for key := range c {
+		if !registered[key] {
+			stale = append(stale, key)
+		}
+	}
+	slices.Sort(stale)
+	return stale
+}
+
+// lookup returns the catalog entry of a route.
+func (c Catalog) lookup(method, path string) (Route, bool) {
+	if route, ok := c[method+" "+path]; ok {
+		return route, true
+	}
+	route, ok := c["* "+path]
+	return route, ok
+}
+
+// operation returns the document operation of the route.
+func (r Route) operation(g *schemaGenerator, method, path string) *Operation {
+	op := &Operation{
+		OperationID: operationID(method, path),
+		Summary:     r.Summary,
+		Tags:        []string{pathTag(path)},
+		Responses:   map[string]*Response{"default": {Ref: "#/components/responses/Problem"}},
+	}
+
+	for _, m := range pathParam.FindAllStringSubmatch(path, -1) {
+		schema := &Schema{Type: "string"}
+		if strings.HasSuffix(m[1], "_uuid") {
+			schema.Format = "uuid"
+		}
+		op.Parameters = append(op.Parameters, Parameter{Name: m[1], In: "path", Required: true, Schema: schema})
+	}
+	if r.Query != nil {
+		op.Parameters = append(op.Parameters, g.queryParameters(r.Query)...)
+	}
+	if r.Request != nil {
+		mediaType := "application/json"
+		if r.Form {
+			mediaType = "application/x-www-form-urlencoded"
+		}
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{mediaType: {Schema: g.schemaOf(reflect.TypeOf(r.Request))}},
+		}
+	}
+	if !r.Public {
+		op.Security = []map[string][]string{{"bearerAuth": {}}}
+	}
+
+	status := r.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	if body := r.responseSchema(g); body != nil && status != http.StatusNoContent && status != http.StatusFound {
+		success.Content = map[string]*MediaType{"application/json": {Schema: body}}
+	}
+	op.Responses[strconv.Itoa(status)] = success
+	return op
+}
+
+// responseSchema returns the schema of the success body: the envelope, with
+// the response as its data, or the raw response.
+func (r Route) responseSchema(g *schemaGenerator) *Schema {
+	if r.Raw {
+		if r.Response == nil {
+			return nil
+		}
+		return g.schemaOf(reflect.TypeOf(r.Response))
+	}
+	envelope := &Schema{Type: "object", Properties: map[string]*Schema{
+		"success": {Type: "boolean"},
+		"message": {Type: "string"},
+	}}
+	if r.Response != nil {
+		envelope.Properties["data"] = g.schemaOf(reflect.TypeOf(r.Response))
+	}
+	return envelope
+}
+
+// operation returns the slot of the path item holding method.
+func (p *PathItem) operation(method string) **Operation {
+	switch method {
+	case http.MethodGet:
+		return &p.Get
+	case http.MethodPut:
+		return &p.Put
+	case http.MethodPost:
+		return &p.Post
+	case http.MethodDelete:
+		return &p.Delete
+	default:
+		return &p.Patch
+	}
+}
+
+// operationID derives a unique operation id from the route, e.g.
+// "put_roles_role_uuid" for PUT /api/v1/roles/{role_uuid}.
+func operationID(method, path string) string {
+	path = strings.TrimPrefix(path, "/api/v1")
+	id := strings.ToLower(method) + "_" + strings.Trim(nonWord.ReplaceAllString(path, "_"), "_")
+	return strings.TrimSuffix(id, "_")
+}
+
+// nonWord matches runs of characters that cannot appear in an operation id.
+var nonWord = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// pathTag groups the route by its resource, the first path segment after
+// the API prefix, e.g. "roles" for /api/v1/roles/{role_uuid}.
+func pathTag(path string) string {
+	path = strings.TrimPrefix(path, "/api/v1")
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return strings.TrimPrefix(segment, ".")
+}
+
+// walk calls fn for every route of routes.
+func walk(routes chi.Routes, fn func(method, path string)) {
+	// The walk function never fails, so neither does Walk
+	_ = chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		fn(method, route)
+		return nil
+	})
+}
+
+// problem is the error body, as written by the response package.
+type problem struct {
+	Type    string `json:"type"`
+	Title   string `json:"title"`
+	Status  int    `json:"status"`
+	Detail  string `json:"detail,omitempty"`
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Details any    `json:"details,omitempty"`
+}
@@ -0,0 +1,107 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type buildTestWidget struct {
+	Name string `json:"name"`
+}
+
+func buildTestRouters() (internal, public *chi.Mux) {
+	noop := func(http.ResponseWriter, *http.Request) {}
+	internal = chi.NewRouter()
+	internal.Get("/health", noop)
+	internal.Route("/api/v1/widgets", func(r chi.Router) {
+		r.Get("/", noop)
+		r.Post("/", noop)
+		r.Put("/{widget_uuid}", noop)
+	})
+	internal.Handle("/assets/*", http.HandlerFunc(noop))
+
+	public = chi.NewRouter()
+	public.Get("/health", noop)
+	public.Post("/api/v1/token", noop)
+	return internal, public
+}
+
+var buildTestCatalog = Catalog{
+	"GET /health":                       {Summary: "Liveness probe", Public: true, Raw: true},
+	"GET /api/v1/widgets/":              {Summary: "List widgets", Response: []buildTestWidget{}},
+	"POST /api/v1/widgets/":             {Summary: "Create a widget", Request: buildTestWidget{}, Response: buildTestWidget{}, Status: http.StatusCreated},
+	"PUT /api/v1/widgets/{widget_uuid}": {Summary: "Update a widget", Request: buildTestWidget{}},
+	"POST /api/v1/token":                {Summary: "Issue a token", Public: true, Request: buildTestWidget{}, Form: true, Raw: true},
+	"* /assets/*":                       {Hidden: true},
+}
+
+func TestCatalog_Build(t *testing.T) {
+	internal, public := buildTestRouters()
+	internalServer := Server{URL: "https://internal.example.com"}
+	publicServer := Server{URL: "https://auth.example.com"}
+	doc := buildTestCatalog.Build(Info{Title: "Test", Version: "1.0.0"},
+		Port{Server: internalServer, Routes: internal},
+		Port{Server: publicServer, Routes: public},
+	)
+
+	assert.Equal(t, Version, doc.OpenAPI)
+	assert.Equal(t, []Server{internalServer, publicServer}, doc.Servers)
+	assert.Equal(t, []Tag{{Name: "health"}, {Name: "token"}, {Name: "widgets"}}, doc.Tags)
+	assert.NotContains(t, doc.Paths, "/assets/*")
+
+	health := doc.Paths["/health"]
+	require.NotNil(t, health)
+	assert.Empty(t, health.Servers)
+	assert.Nil(t, health.Get.Security)
+	assert.Nil(t, health.Get.Responses["200"].Content)
+
+	create := doc.Paths["/api/v1/widgets/"].Post
+	require.NotNil(t, create)
+	assert.Equal(t, []Server{internalServer}, doc.Paths["/api/v1/widgets/"].Servers)
+	assert.Equal(t, "post_widgets", create.OperationID)
+	assert.Equal(t, []string{"widgets"}, create.Tags)
+	assert.Equal(t, []map[string][]string{{"bearerAuth": {}}}, create.Security)
+	assert.Equal(t, "#/components/schemas/buildTestWidget", create.RequestBody.Content["application/json"].Schema.Ref)
+	envelope := create.Responses["201"].Content["application/json"].Schema
+	assert.Equal(t, "#/components/schemas/buildTestWidget", envelope.Properties["data"].Ref)
+	assert.Equal(t, "#/components/responses/Problem", create.Responses["default"].Ref)
+
+	update := doc.Paths["/api/v1/widgets/{widget_uuid}"].Put
+	require.NotNil(t, update)
+	assert.Equal(t, "put_widgets_widget_uuid", update.OperationID)
+	assert.Equal(t, []Parameter{{Name: "widget_uuid", In: "path", Required: true, Schema: &Schema{Type: "string", Format: "uuid"}}}, update.Parameters)
+	assert.NotContains(t, update.Responses["200"].Content["application/json"].Schema.Properties, "data")
+
+	token := doc.Paths["/api/v1/token"].Post
+	require.NotNil(t, token)
+	assert.Contains(t, token.RequestBody.Content, "application/x-www-form-urlencoded")
+	assert.Nil(t, token.Responses["200"].Content, "raw responses without a body have no content")
+}
+
+func TestCatalog_Undocumented(t *testing.T) {
+	internal, _ := buildTestRouters()
+	catalog := Catalog{"GET /health": {}, "* /assets/*": {}}
+
+	assert.Equal(t, []string{
+		"GET /api/v1/widgets/",
+		"POST /api/v1/widgets/",
+		"PUT /api/v1/widgets/{widget_uuid}",
+	}, catalog.Undocumented(internal))
+}
+
+func TestCatalog_Unregistered(t *testing.T) {
+	internal, public := buildTestRouters()
+	catalog := Catalog{
+		"GET /health":      {},
+		"* /assets/*":      {},
+		"DELETE /health":   {},
+		"GET /api/v1/gone": {},
+	}
+
+	assert.Equal(t, []string{"DELETE /health", "GET /api/v1/gone"}, catalog.Unregistered(internal, public))
+	assert.Empty(t, buildTestCatalog.Unregistered(internal, public))
+}
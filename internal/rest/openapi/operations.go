@@ -0,0 +1,452 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/maintainerd/auth/internal/dto"
+)
+
+// statusRequest is the body of the status endpoints that take the status
+// without a DTO.
+type statusRequest struct {
+	Status string `json:"status"`
+}
+
+// inheritableRequest is the body of PUT /roles/{role_uuid}/inheritable.
+type inheritableRequest struct {
+	IsInheritable *bool `json:"is_inheritable"`
+}
+
+// probeResponse is the body of the liveness and readiness probes.
+type probeResponse struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Operations documents every route of the internal and public routers. A
+// test in the server package fails when a route is missing from it or an
+// entry matches no route.
+var Operations = Catalog{
+	// Probes and discovery
+	"GET /health":                           {Summary: "Liveness probe", Public: true, Response: probeResponse{}, Raw: true},
+	"GET /ready":                            {Summary: "Readiness probe", Public: true, Response: probeResponse{}, Raw: true},
+	"GET /.well-known/jwks.json":            {Summary: "Get the token signing keys", Public: true, Response: dto.JWKSResponseDTO{}, Raw: true},
+	"GET /.well-known/openid-configuration": {Summary: "Get the OpenID Connect discovery document", Public: true, Response: dto.OAuthDiscoveryResponseDTO{}, Raw: true},
+	"GET /openapi.json":                     {Summary: "Get this OpenAPI document", Response: map[string]any{}, Raw: true},
+	"GET /docs":                             {Summary: "Open the API explorer", Raw: true},
+
+	// Not part of the API
+	"* /metrics/":              {Hidden: true},
+	"* /admin":                 {Hidden: true},
+	"* /admin/*":               {Hidden: true},
+	"* /api/v1/login/assets/*": {Hidden: true},
+
+	// Public lookups
+	"GET /api/v1/login":                    {Summary: "Render the hosted login page", Public: true, Raw: true},
+	"GET /api/v1/errors/":                  {Summary: "List error codes", Public: true, Response: []dto.ErrorCodeResponseDTO{}},
+	"GET /api/v1/errors/{code}":            {Summary: "Get an error code", Public: true, Response: dto.ErrorCodeResponseDTO{}},
+	"GET /api/v1/media":                    {Summary: "Read a stored object through a signed link", Public: true, Raw: true},
+	"GET /api/v1/tenant/":                  {Summary: "Get the default tenant", Public: true, Response: dto.TenantResponseDTO{}},
+	"GET /api/v1/tenant/{identifier}":      {Summary: "Get a public tenant by identifier", Public: true, Response: dto.TenantResponseDTO{}},
+	"GET /api/v1/policy-documents/current": {Summary: "List the current policy documents", Public: true, Query: dto.LoginQueryDTO{}, Response: []dto.PolicyDocumentResponseDTO{}},
+
+	// Setup
+	"GET /api/v1/setup/status":          {Summary: "Get the setup status", Public: true, Response: dto.SetupStatusResponseDTO{}},
+	"POST /api/v1/setup/create_tenant":  {Summary: "Create the first tenant", Public: true, Request: dto.CreateTenantRequestDTO{}, Response: dto.TenantResponseDTO{}, Status: http.StatusCreated},
+	"POST /api/v1/setup/create_admin":   {Summary: "Create the first admin", Public: true, Request: dto.CreateAdminRequestDTO{}, Response: dto.UserResponseDTO{}, Status: http.StatusCreated},
+	"POST /api/v1/setup/create_profile": {Summary: "Create the first admin's profile", Public: true, Request: dto.CreateProfileRequestDTO{}, Response: dto.ProfileResponseDTO{}, Status: http.StatusCreated},
+
+	// Authentication
+	"POST /api/v1/login":                 {Summary: "Log in", Public: true, Request: dto.LoginRequestDTO{}, Response: dto.LoginResponseDTO{}},
+	"POST /api/v1/login/federated":       {Summary: "Log in with an identity provider", Public: true, Request: dto.FederatedLoginRequestDTO{}, Response: dto.LoginResponseDTO{}},
+	"POST /api/v1/login/identify":        {Summary: "Find the identity provider of an email address", Public: true, Request: dto.HomeRealmRequestDTO{}, Response: dto.HomeRealmResponseDTO{}},
+	"POST /api/v1/logout":                {Summary: "Log out", Public: true},
+	"POST /api/v1/register":              {Summary: "Register", Public: true, Request: dto.RegisterRequestDTO{}, Response: dto.RegisterResponseDTO{}, Status: http.StatusCreated},
+	"POST /api/v1/register/invite":       {Summary: "Register with an invite", Public: true, Query: dto.RegisterInviteQueryDTO{}, Request: dto.LoginRequestDTO{}, Response: dto.RegisterResponseDTO{}, Status: http.StatusCreated},
+	"POST /api/v1/register/verify-email": {Summary: "Verify an email address", Public: true, Request: dto.VerifyEmailRequestDTO{}, Response: dto.RegisterResponseDTO{}},
+	"POST /api/v1/forgot-password":       {Summary: "Request a password reset email", Public: true, Request: dto.ForgotPasswordRequestDTO{}, Response: dto.ForgotPasswordResponseDTO{}},
+	"POST /api/v1/reset-password":        {Summary: "Reset a password", Public: true, Request: dto.ResetPasswordRequestDTO{}, Response: dto.ResetPasswordResponseDTO{}},
+	"POST /api/v1/session/":              {Summary: "Log in with a browser session", Public: true, Request: dto.LoginRequestDTO{}, Response: dto.BrowserSessionResponseDTO{}, Status: http.StatusCreated},
+	"DELETE /api/v1/session/":            {Summary: "End the browser session", Public: true},
+	"GET /api/v1/session/me":             {Summary: "Get the browser session", Response: dto.BrowserSessionResponseDTO{}},
+
+	// OAuth2 and OpenID Connect
+	"GET /api/v1/oauth/authorize":                      {Summary: "Start an authorization request", Query: dto.OAuthAuthorizeRequestDTO{}, Response: dto.OAuthAuthorizeResponseDTO{}},
+	"GET /api/v1/oauth/consent/{challenge_id}":         {Summary: "Get a consent challenge", Response: dto.OAuthConsentChallengeResponseDTO{}},
+	"POST /api/v1/oauth/consent":                       {Summary: "Grant or deny consent", Request: dto.OAuthConsentDecisionDTO{}, Response: dto.OAuthConsentDecisionResponseDTO{}},
+	"GET /api/v1/oauth/consent/grants":                 {Summary: "List the user's consent grants", Response: []dto.OAuthConsentGrantResponseDTO{}},
+	"DELETE /api/v1/oauth/consent/grants/{grant_uuid}": {Summary: "Revoke a consent grant"},
+	"POST /api/v1/oauth/token":                         {Summary: "Issue tokens", Public: true, Request: dto.OAuthTokenRequestDTO{}, Form: true, Response: dto.OAuthTokenResponseDTO{}, Raw: true},
+	"POST /api/v1/oauth/revoke":                        {Summary: "Revoke a token", Public: true, Request: dto.OAuthRevokeRequestDTO{}, Form: true, Raw: true},
+	"POST /api/v1/oauth/introspect":                    {Summary: "Introspect a token", Request: dto.OAuthIntrospectRequestDTO{}, Form: true, Response: dto.OAuthIntrospectResponseDTO{}, Raw: true},
+	"GET /api/v1/oauth/userinfo":                       {Summary: "Get the claims of the token's user", Response: dto.OAuthUserInfoResponseDTO{}, Raw: true},
+	"GET /api/v1/oauth/end_session":                    {Summary: "Log out of an OpenID Connect session", Public: true, Query: dto.OAuthEndSessionRequestDTO{}, Raw: true},
+	"POST /api/v1/oauth/end_session":                   {Summary: "Log out of an OpenID Connect session", Public: true, Request: dto.OAuthEndSessionRequestDTO{}, Form: true, Raw: true},
+
+	// Account
+	"DELETE /api/v1/account":                                             {Summary: "Schedule deletion of the account", Response: dto.AccountDeletionResponseDTO{}},
+	"POST /api/v1/account/deletion/cancel":                               {Summary: "Cancel deletion of an account", Public: true, Request: dto.AccountDeletionCancelRequestDTO{}, Response: dto.UserResponseDTO{}},
+	"POST /api/v1/account/disable":                                       {Summary: "Disable the account", Response: dto.UserResponseDTO{}},
+	"POST /api/v1/account/reactivate":                                    {Summary: "Reactivate a disabled account", Public: true, Request: dto.AccountReactivateRequestDTO{}, Response: dto.UserResponseDTO{}},
+	"GET /api/v1/parental-consent":                                       {Summary: "Get a guardian consent request", Public: true, Query: dto.ParentalConsentRequestDTO{}, Response: dto.ParentalConsentResponseDTO{}},
+	"POST /api/v1/parental-consent/approve":                              {Summary: "Approve an under-age account as its guardian", Public: true, Request: dto.ParentalConsentRequestDTO{}, Response: dto.ParentalConsentResponseDTO{}},
+	"POST /api/v1/parental-consent/deny":                                 {Summary: "Decline an under-age account as its guardian", Public: true, Request: dto.ParentalConsentRequestDTO{}, Response: dto.ParentalConsentResponseDTO{}},
+	"POST /api/v1/account/change-password":                               {Summary: "Change the password", Request: dto.AccountChangePasswordRequestDTO{}},
+	"POST /api/v1/account/reauthenticate":                                {Summary: "Re-authenticate for step-up protected actions", Request: dto.AccountReauthenticateRequestDTO{}, Response: dto.AccountReauthenticateResponseDTO{}},
+	"GET /api/v1/account/recovery/":                                      {Summary: "Get the account's recovery settings", Response: dto.AccountRecoveryResponseDTO{}},
+	"POST /api/v1/account/recovery/codes":                                {Summary: "Generate new recovery codes", Request: dto.AccountRecoveryCodesRequestDTO{}, Response: dto.AccountRecoveryCodesResponseDTO{}, Status: http.StatusCreated},
+	"PUT /api/v1/account/recovery/email":                                 {Summary: "Set the recovery email", Request: dto.AccountRecoveryEmailRequestDTO{}, Response: dto.AccountRecoveryResponseDTO{}},
+	"DELETE /api/v1/account/recovery/email":                              {Summary: "Remove the recovery email", Response: dto.AccountRecoveryResponseDTO{}},
+	"POST /api/v1/account/recovery/email/verify":                         {Summary: "Verify the recovery email", Request: dto.AccountRecoveryEmailVerifyRequestDTO{}, Response: dto.AccountRecoveryResponseDTO{}},
+	"POST /api/v1/account/recover/start":                                 {Summary: "Start recovering an account", Public: true, Request: dto.AccountRecoveryStartRequestDTO{}},
+	"POST /api/v1/account/recover":                                       {Summary: "Recover an account with a recovery code", Public: true, Request: dto.AccountRecoveryRequestDTO{}, Response: dto.AccountRecoveredResponseDTO{}},
+	"GET /api/v1/account/consents/":                                      {Summary: "List the account's consent grants", Response: []dto.OAuthConsentGrantResponseDTO{}},
+	"DELETE /api/v1/account/consents/{grant_uuid}":                       {Summary: "Revoke a consent grant of the account"},
+	"GET /api/v1/account/identities/":                                    {Summary: "List the account's linked identities", Response: []dto.UserIdentityResponseDTO{}},
+	"POST /api/v1/account/identities/":                                   {Summary: "Link an identity to the account", Request: dto.AccountIdentityLinkRequestDTO{}, Response: dto.UserIdentityResponseDTO{}, Status: http.StatusCreated},
+	"DELETE /api/v1/account/identities/{user_identity_uuid}":             {Summary: "Unlink an identity from the account"},
+	"GET /api/v1/account/notification-settings":                          {Summary: "Get the account's notification settings", Response: dto.NotificationSettingResponseDTO{}},
+	"PUT /api/v1/account/notification-settings":                          {Summary: "Update the account's notification settings", Request: dto.NotificationSettingUpdateRequestDTO{}, Response: dto.NotificationSettingResponseDTO{}},
+	"GET /api/v1/account/notifications":                                  {Summary: "List the notifications sent to the account", Query: dto.NotificationLogFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.NotificationLogResponseDTO]{}},
+	"GET /api/v1/account/permissions/":                                   {Summary: "Get the account's effective permissions", Response: dto.EffectivePermissionsResponseDTO{}},
+	"GET /api/v1/account/trusted-devices/":                               {Summary: "List the account's trusted devices", Response: []dto.TrustedDeviceResponseDTO{}},
+	"POST /api/v1/account/trusted-devices/":                              {Summary: "Trust the current device", Request: dto.TrustedDeviceCreateRequestDTO{}, Response: dto.TrustedDeviceCreateResponseDTO{}, Status: http.StatusCreated},
+	"DELETE /api/v1/account/trusted-devices/":                            {Summary: "Forget every trusted device", Response: map[string]int64{}},
+	"DELETE /api/v1/account/trusted-devices/{trusted_device_uuid}":       {Summary: "Forget a trusted device"},
+	"GET /api/v1/personal-access-tokens/":                                {Summary: "List personal access tokens", Response: []dto.PersonalAccessTokenResponseDTO{}},
+	"POST /api/v1/personal-access-tokens/":                               {Summary: "Create a personal access token", Request: dto.PersonalAccessTokenCreateRequestDTO{}, Response: dto.PersonalAccessTokenCreateResponseDTO{}, Status: http.StatusCreated},
+	"DELETE /api/v1/personal-access-tokens/{personal_access_token_uuid}": {Summary: "Revoke a personal access token"},
+	"GET /api/v1/profile/":                                               {Summary: "Get the default profile", Response: dto.ProfileResponseDTO{}},
+	"POST /api/v1/profile/":                                              {Summary: "Create the default profile", Request: dto.ProfileRequestDTO{}, Response: dto.ProfileResponseDTO{}},
+	"PUT /api/v1/profile/":                                               {Summary: "Update the default profile", Request: dto.ProfileRequestDTO{}, Response: dto.ProfileResponseDTO{}},
+	"DELETE /api/v1/profile/":                                            {Summary: "Delete the default profile", Response: dto.ProfileResponseDTO{}},
+	"PUT /api/v1/profile/picture":                                        {Summary: "Upload the default profile's picture", Response: dto.ProfileResponseDTO{}},
+	"DELETE /api/v1/profile/picture":                                     {Summary: "Delete the default profile's picture", Response: dto.ProfileResponseDTO{}},
+	"GET /api/v1/profiles/":                                              {Summary: "List profiles", Query: dto.ProfileFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.ProfileResponseDTO]{}},
+	"POST /api/v1/profiles/":                                             {Summary: "Create a profile", Request: dto.ProfileRequestDTO{}, Response: dto.ProfileResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/profiles/{profile_uuid}":                                {Summary: "Get a profile", Response: dto.ProfileResponseDTO{}},
+	"PUT /api/v1/profiles/{profile_uuid}":                                {Summary: "Update a profile", Request: dto.ProfileRequestDTO{}, Response: dto.ProfileResponseDTO{}},
+	"DELETE /api/v1/profiles/{profile_uuid}":                             {Summary: "Delete a profile", Response: dto.ProfileResponseDTO{}},
+	"PUT /api/v1/profiles/{profile_uuid}/picture":                        {Summary: "Upload a profile's picture", Response: dto.ProfileResponseDTO{}},
+	"DELETE /api/v1/profiles/{profile_uuid}/picture":                     {Summary: "Delete a profile's picture", Response: dto.ProfileResponseDTO{}},
+	"PATCH /api/v1/profiles/{profile_uuid}/set-default":                  {Summary: "Make a profile the default", Response: dto.ProfileResponseDTO{}},
+	"GET /api/v1/user-settings/":                                         {Summary: "Get the user's settings", Response: dto.UserSettingResponseDTO{}},
+	"POST /api/v1/user-settings/":                                        {Summary: "Replace the user's settings", Request: dto.UserSettingRequestDTO{}, Response: dto.UserSettingResponseDTO{}},
+	"DELETE /api/v1/user-settings/":                                      {Summary: "Reset the user's settings", Response: dto.UserSettingResponseDTO{}},
+
+	// Administration
+	"POST /admin/config/reload":                                             {Summary: "Reload the config file", Response: dto.ConfigReloadResponseDTO{}},
+	"GET /admin/data-keys":                                                  {Summary: "List data keys", Response: []dto.DataKeyResponseDTO{}},
+	"POST /admin/data-keys/rotate":                                          {Summary: "Rotate the data key", Response: dto.DataKeyResponseDTO{}, Status: http.StatusCreated},
+	"POST /admin/data-keys/rewrap":                                          {Summary: "Rewrap data keys with the key-encryption key", Response: dto.DataKeyRewrapResponseDTO{}},
+	"POST /admin/data-keys/reencrypt":                                       {Summary: "Reencrypt stored values with the active data key", Response: dto.DataKeyReencryptResponseDTO{}},
+	"GET /admin/migrations":                                                 {Summary: "Get the migration status", Response: dto.MigrationStatusResponseDTO{}},
+	"POST /admin/migrations/apply":                                          {Summary: "Apply pending migrations", Response: dto.MigrationApplyResponseDTO{}},
+	"GET /admin/feature-flags/":                                             {Summary: "List feature flags", Response: []dto.FeatureFlagResponseDTO{}},
+	"POST /admin/feature-flags/":                                            {Summary: "Create a feature flag", Request: dto.FeatureFlagCreateRequestDTO{}, Response: dto.FeatureFlagResponseDTO{}, Status: http.StatusCreated},
+	"GET /admin/feature-flags/{feature_flag_uuid}":                          {Summary: "Get a feature flag", Response: dto.FeatureFlagResponseDTO{}},
+	"PUT /admin/feature-flags/{feature_flag_uuid}":                          {Summary: "Update a feature flag", Request: dto.FeatureFlagUpdateRequestDTO{}, Response: dto.FeatureFlagResponseDTO{}},
+	"DELETE /admin/feature-flags/{feature_flag_uuid}":                       {Summary: "Delete a feature flag", Response: dto.FeatureFlagResponseDTO{}},
+	"PUT /admin/feature-flags/{feature_flag_uuid}/tenants/{tenant_uuid}":    {Summary: "Override a feature flag for a tenant", Request: dto.FeatureFlagOverrideRequestDTO{}, Response: dto.FeatureFlagResponseDTO{}},
+	"DELETE /admin/feature-flags/{feature_flag_uuid}/tenants/{tenant_uuid}": {Summary: "Remove a tenant's feature flag override", Response: dto.FeatureFlagResponseDTO{}},
+	"GET /admin/plans/":                                                     {Summary: "List billing plans", Response: []dto.PlanResponseDTO{}},
+	"POST /admin/plans/":                                                    {Summary: "Create a billing plan", Request: dto.PlanRequestDTO{}, Response: dto.PlanResponseDTO{}, Status: http.StatusCreated},
+	"GET /admin/plans/{plan_uuid}":                                          {Summary: "Get a billing plan", Response: dto.PlanResponseDTO{}},
+	"PUT /admin/plans/{plan_uuid}":                                          {Summary: "Replace a billing plan", Request: dto.PlanRequestDTO{}, Response: dto.PlanResponseDTO{}},
+	"DELETE /admin/plans/{plan_uuid}":                                       {Summary: "Delete a billing plan", Response: dto.PlanResponseDTO{}},
+	"GET /admin/queues":                                                     {Summary: "Get the queue health", Response: dto.QueueHealthResponseDTO{}},
+	"GET /admin/signing-keys":                                               {Summary: "List signing keys", Response: []dto.SigningKeyResponseDTO{}},
+	"POST /admin/signing-keys/rotate":                                       {Summary: "Rotate the signing key", Response: dto.SigningKeyResponseDTO{}, Status: http.StatusCreated},
+	"POST /admin/signing-keys/{signing_key_uuid}/retire":                    {Summary: "Retire a signing key", Response: dto.SigningKeyResponseDTO{}},
+	"GET /api/v1/debug/body-traces":                                         {Summary: "List body trace rules", Response: []dto.BodyTraceResponseDTO{}},
+	"POST /api/v1/debug/body-traces":                                        {Summary: "Create a body trace rule", Request: dto.BodyTraceRequestDTO{}, Response: dto.BodyTraceResponseDTO{}, Status: http.StatusCreated},
+	"DELETE /api/v1/debug/body-traces/{body_trace_uuid}":                    {Summary: "Delete a body trace rule", Response: dto.BodyTraceResponseDTO{}},
+	"GET /api/v1/debug/log-levels":                                          {Summary: "List log levels", Response: []dto.LogLevelResponseDTO{}},
+	"PUT /api/v1/debug/log-levels/{component}":                              {Summary: "Set the log level of a component", Request: dto.SetLogLevelRequestDTO{}, Response: dto.LogLevelResponseDTO{}},
+	"GET /api/v1/authz/routes":                                              {Summary: "List the permissions of each route", Response: []dto.AuthzRoutePermissionResponseDTO{}},
+	"POST /api/v1/authz/simulate":                                           {Summary: "Simulate an access decision", Request: dto.AuthzSimulateRequestDTO{}, Response: dto.AuthzSimulationResponseDTO{}},
+	"GET /api/v1/auth-events/":                                              {Summary: "List auth events", Query: dto.AuthEventFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.AuthEventResponseDTO]{}},
+	"GET /api/v1/auth-events/count":                                         {Summary: "Count auth events", Response: map[string]int64{}},
+	"GET /api/v1/auth-events/archive-runs":                                  {Summary: "List auth event archive runs", Query: dto.PaginationRequestDTO{}, Response: dto.PaginatedResponseDTO[dto.AuditArchiveRunResponseDTO]{}},
+	"POST /api/v1/auth-events/archive-runs":                                 {Summary: "Archive expired auth events now", Response: dto.AuditArchiveRunResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/auth-events/archive-runs/{audit_archive_run_uuid}":         {Summary: "Get an auth event archive run", Response: dto.AuditArchiveRunResponseDTO{}},
+	"GET /api/v1/auth-events/{auth_event_uuid}":                             {Summary: "Get an auth event", Response: dto.AuthEventResponseDTO{}},
+	"GET /api/v1/events/":                                                   {Summary: "Read the event feed", Query: dto.EventFeedFilterDTO{}, Response: dto.EventFeedResponseDTO{}},
+	"GET /api/v1/notification-logs/":                                        {Summary: "List notification deliveries", Query: dto.NotificationLogFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.NotificationLogResponseDTO]{}},
+
+	// API keys
+	"GET /api/v1/api_keys/":                                                                {Summary: "List API keys", Response: dto.PaginatedResponseDTO[dto.APIKeyResponseDTO]{}},
+	"POST /api/v1/api_keys/":                                                               {Summary: "Create an API key", Request: dto.APIKeyCreateRequestDTO{}, Response: dto.APIKeyCreateResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/api_keys/{api_key_uuid}":                                                  {Summary: "Get an API key", Response: dto.APIKeyResponseDTO{}},
+	"PUT /api/v1/api_keys/{api_key_uuid}":                                                  {Summary: "Update an API key", Request: dto.APIKeyUpdateRequestDTO{}, Response: dto.APIKeyResponseDTO{}},
+	"DELETE /api/v1/api_keys/{api_key_uuid}":                                               {Summary: "Delete an API key", Response: dto.APIKeyResponseDTO{}},
+	"GET /api/v1/api_keys/{api_key_uuid}/config":                                           {Summary: "Get an API key's config", Response: map[string]any{}},
+	"PUT /api/v1/api_keys/{api_key_uuid}/status":                                           {Summary: "Set an API key's status", Request: dto.APIKeyStatusUpdateDTO{}, Response: dto.APIKeyResponseDTO{}},
+	"GET /api/v1/api_keys/{api_key_uuid}/apis/":                                            {Summary: "List an API key's APIs", Response: dto.PaginatedResponseDTO[dto.APIResponseDTO]{}},
+	"POST /api/v1/api_keys/{api_key_uuid}/apis/":                                           {Summary: "Add APIs to an API key", Request: dto.AddAPIKeyAPIsRequestDTO{}},
+	"DELETE /api/v1/api_keys/{api_key_uuid}/apis/{api_uuid}":                               {Summary: "Remove an API from an API key"},
+	"POST /api/v1/api_keys/{api_key_uuid}/apis/{api_uuid}/permission-groups":               {Summary: "Assign a permission group to an API key's API", Response: dto.PermissionGroupAssignResponseDTO{}},
+	"GET /api/v1/api_keys/{api_key_uuid}/apis/{api_uuid}/permissions/":                     {Summary: "List an API key's permissions on an API", Response: dto.APIKeyAPIPermissionsResponseDTO{}},
+	"POST /api/v1/api_keys/{api_key_uuid}/apis/{api_uuid}/permissions/":                    {Summary: "Grant an API key permissions on an API", Request: dto.AddAPIKeyPermissionsRequestDTO{}},
+	"DELETE /api/v1/api_keys/{api_key_uuid}/apis/{api_uuid}/permissions/{permission_uuid}": {Summary: "Revoke an API key's permission on an API"},
+
+	// APIs
+	"GET /api/v1/apis/":                          {Summary: "List APIs", Query: dto.APIFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.APIResponseDTO]{}},
+	"POST /api/v1/apis/":                         {Summary: "Create an API", Request: dto.APICreateRequestDTO{}, Response: dto.APIResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/apis/{api_uuid}":                {Summary: "Get an API", Response: dto.APIResponseDTO{}},
+	"PUT /api/v1/apis/{api_uuid}":                {Summary: "Update an API", Request: dto.APIUpdateRequestDTO{}, Response: dto.APIResponseDTO{}},
+	"DELETE /api/v1/apis/{api_uuid}":             {Summary: "Delete an API", Response: dto.APIResponseDTO{}},
+	"PUT /api/v1/apis/{api_uuid}/deprecation":    {Summary: "Deprecate an API", Request: dto.DeprecationRequestDTO{}, Response: dto.APIResponseDTO{}},
+	"DELETE /api/v1/apis/{api_uuid}/deprecation": {Summary: "Undeprecate an API", Response: dto.APIResponseDTO{}},
+	"PUT /api/v1/apis/{api_uuid}/status":         {Summary: "Set an API's status", Request: dto.APIStatusUpdateDTO{}, Response: dto.APIResponseDTO{}},
+
+	// Branding and messaging
+	"GET /api/v1/branding/":                                      {Summary: "Get the branding", Response: dto.BrandingResponseDTO{}},
+	"PUT /api/v1/branding/":                                      {Summary: "Update the branding", Request: dto.BrandingUpdateRequestDTO{}, Response: dto.BrandingResponseDTO{}},
+	"GET /api/v1/email-config/":                                  {Summary: "Get the email config", Response: dto.EmailConfigResponseDTO{}},
+	"PUT /api/v1/email-config/":                                  {Summary: "Update the email config", Request: dto.EmailConfigUpdateRequestDTO{}, Response: dto.EmailConfigResponseDTO{}},
+	"GET /api/v1/email_templates/":                               {Summary: "List email templates", Query: dto.EmailTemplateFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.EmailTemplateListResponseDTO]{}},
+	"POST /api/v1/email_templates/":                              {Summary: "Create an email template", Request: dto.EmailTemplateCreateRequestDTO{}, Response: dto.EmailTemplateResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/email_templates/{email_template_uuid}":          {Summary: "Get an email template", Response: dto.EmailTemplateResponseDTO{}},
+	"PUT /api/v1/email_templates/{email_template_uuid}":          {Summary: "Update an email template", Request: dto.EmailTemplateUpdateRequestDTO{}, Response: dto.EmailTemplateResponseDTO{}},
+	"DELETE /api/v1/email_templates/{email_template_uuid}":       {Summary: "Delete an email template", Response: dto.EmailTemplateResponseDTO{}},
+	"PATCH /api/v1/email_templates/{email_template_uuid}/status": {Summary: "Set an email template's status", Request: dto.EmailTemplateUpdateStatusRequestDTO{}, Response: dto.EmailTemplateResponseDTO{}},
+	"GET /api/v1/sms-config/":                                    {Summary: "Get the SMS config", Response: dto.SMSConfigResponseDTO{}},
+	"PUT /api/v1/sms-config/":                                    {Summary: "Update the SMS config", Request: dto.SMSConfigUpdateRequestDTO{}, Response: dto.SMSConfigResponseDTO{}},
+	"GET /api/v1/sms_templates/":                                 {Summary: "List SMS templates", Query: dto.SMSTemplateFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.SMSTemplateListResponseDTO]{}},
+	"POST /api/v1/sms_templates/":                                {Summary: "Create an SMS template", Request: dto.SMSTemplateCreateRequestDTO{}, Response: dto.SMSTemplateResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/sms_templates/{sms_template_uuid}":              {Summary: "Get an SMS template", Response: dto.SMSTemplateResponseDTO{}},
+	"PUT /api/v1/sms_templates/{sms_template_uuid}":              {Summary: "Update an SMS template", Request: dto.SMSTemplateUpdateRequestDTO{}, Response: dto.SMSTemplateResponseDTO{}},
+	"DELETE /api/v1/sms_templates/{sms_template_uuid}":           {Summary: "Delete an SMS template", Response: dto.SMSTemplateResponseDTO{}},
+	"PATCH /api/v1/sms_templates/{sms_template_uuid}/status":     {Summary: "Set an SMS template's status", Request: dto.SMSTemplateUpdateStatusRequestDTO{}, Response: dto.SMSTemplateResponseDTO{}},
+	"GET /api/v1/login_templates/":                               {Summary: "List login templates", Query: dto.LoginTemplateFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.LoginTemplateListResponseDTO]{}},
+	"POST /api/v1/login_templates/":                              {Summary: "Create a login template", Request: dto.LoginTemplateCreateRequestDTO{}, Response: dto.LoginTemplateResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/login_templates/{login_template_uuid}":          {Summary: "Get a login template", Response: dto.LoginTemplateResponseDTO{}},
+	"PUT /api/v1/login_templates/{login_template_uuid}":          {Summary: "Update a login template", Request: dto.LoginTemplateUpdateRequestDTO{}, Response: dto.LoginTemplateResponseDTO{}},
+	"DELETE /api/v1/login_templates/{login_template_uuid}":       {Summary: "Delete a login template", Response: dto.LoginTemplateResponseDTO{}},
+	"PATCH /api/v1/login_templates/{login_template_uuid}/status": {Summary: "Set a login template's status", Request: dto.LoginTemplateUpdateStatusRequestDTO{}, Response: dto.LoginTemplateResponseDTO{}},
+	"POST /api/v1/invite/":                                       {Summary: "Send an invite", Request: dto.SendInviteRequest{}},
+
+	// Clients
+	"GET /api/v1/clients/":                                                               {Summary: "List clients", Query: dto.ClientFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.ClientResponseDTO]{}},
+	"POST /api/v1/clients/":                                                              {Summary: "Create a client", Request: dto.ClientCreateRequestDTO{}, Response: dto.ClientResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/clients/{client_uuid}":                                                  {Summary: "Get a client", Response: dto.ClientResponseDTO{}},
+	"PUT /api/v1/clients/{client_uuid}":                                                  {Summary: "Update a client", Request: dto.ClientUpdateRequestDTO{}, Response: dto.ClientResponseDTO{}},
+	"DELETE /api/v1/clients/{client_uuid}":                                               {Summary: "Delete a client", Response: dto.ClientResponseDTO{}},
+	"GET /api/v1/clients/{client_uuid}/config":                                           {Summary: "Get a client's config", Response: map[string]any{}},
+	"PUT /api/v1/clients/{client_uuid}/default":                                          {Summary: "Make a client the default", Response: dto.ClientResponseDTO{}},
+	"PUT /api/v1/clients/{client_uuid}/deprecation":                                      {Summary: "Deprecate a client", Request: dto.DeprecationRequestDTO{}, Response: dto.ClientResponseDTO{}},
+	"DELETE /api/v1/clients/{client_uuid}/deprecation":                                   {Summary: "Undeprecate a client", Response: dto.ClientResponseDTO{}},
+	"PUT /api/v1/clients/{client_uuid}/status":                                           {Summary: "Toggle a client's status", Response: dto.ClientResponseDTO{}},
+	"GET /api/v1/clients/{client_uuid}/secret":                                           {Summary: "Get a client's masked secret", Response: dto.ClientSecretResponseDTO{}},
+	"POST /api/v1/clients/{client_uuid}/secret/reveal":                                   {Summary: "Reveal a client's secret", Request: dto.ClientRevealSecretRequestDTO{}, Response: dto.ClientSecretResponseDTO{}},
+	"POST /api/v1/clients/{client_uuid}/rotate-secret":                                   {Summary: "Rotate a client's secret", Request: dto.ClientRotateSecretRequestDTO{}, Response: dto.ClientSecretRotationResponseDTO{}},
+	"GET /api/v1/clients/{client_uuid}/uris":                                             {Summary: "List a client's URIs", Response: dto.ClientURIsResponseDTO{}},
+	"POST /api/v1/clients/{client_uuid}/uris":                                            {Summary: "Add a client URI", Request: dto.ClientURICreateOrUpdateRequestDTO{}, Response: dto.ClientURIResponseDTO{}, Status: http.StatusCreated},
+	"PUT /api/v1/clients/{client_uuid}/uris/{client_uri_uuid}":                           {Summary: "Update a client URI", Request: dto.ClientURICreateOrUpdateRequestDTO{}, Response: dto.ClientURIResponseDTO{}},
+	"DELETE /api/v1/clients/{client_uuid}/uris/{client_uri_uuid}":                        {Summary: "Remove a client URI", Response: dto.ClientResponseDTO{}},
+	"GET /api/v1/clients/{client_uuid}/apis":                                             {Summary: "List a client's APIs", Response: dto.ClientAPIsResponseDTO{}},
+	"POST /api/v1/clients/{client_uuid}/apis":                                            {Summary: "Add APIs to a client", Request: dto.AddClientAPIsRequestDTO{}, Response: dto.SuccessResponseDTO{}},
+	"DELETE /api/v1/clients/{client_uuid}/apis/{api_uuid}":                               {Summary: "Remove an API from a client", Response: dto.SuccessResponseDTO{}},
+	"POST /api/v1/clients/{client_uuid}/apis/{api_uuid}/permission-groups":               {Summary: "Assign a permission group to a client's API", Response: dto.PermissionGroupAssignResponseDTO{}},
+	"GET /api/v1/clients/{client_uuid}/apis/{api_uuid}/permissions":                      {Summary: "List a client's permissions on an API", Response: dto.ClientAPIPermissionsResponseDTO{}},
+	"POST /api/v1/clients/{client_uuid}/apis/{api_uuid}/permissions":                     {Summary: "Grant a client permissions on an API", Request: dto.AddClientAPIPermissionsRequestDTO{}, Response: dto.SuccessResponseDTO{}},
+	"DELETE /api/v1/clients/{client_uuid}/apis/{api_uuid}/permissions/{permission_uuid}": {Summary: "Revoke a client's permission on an API", Response: dto.SuccessResponseDTO{}},
+
+	// Groups
+	"GET /api/v1/groups/":                                    {Summary: "List groups", Response: dto.PaginatedResponseDTO[dto.GroupResponseDTO]{}},
+	"POST /api/v1/groups/":                                   {Summary: "Create a group", Request: dto.GroupCreateRequestDTO{}, Response: dto.GroupResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/groups/{group_uuid}":                        {Summary: "Get a group", Response: dto.GroupResponseDTO{}},
+	"PUT /api/v1/groups/{group_uuid}":                        {Summary: "Update a group", Request: dto.GroupUpdateRequestDTO{}, Response: dto.GroupResponseDTO{}},
+	"DELETE /api/v1/groups/{group_uuid}":                     {Summary: "Delete a group", Response: dto.GroupResponseDTO{}},
+	"GET /api/v1/groups/{group_uuid}/members":                {Summary: "List a group's members", Response: dto.PaginatedResponseDTO[dto.UserResponseDTO]{}},
+	"POST /api/v1/groups/{group_uuid}/members":               {Summary: "Add members to a group", Request: dto.GroupAddMembersRequestDTO{}, Response: dto.GroupResponseDTO{}},
+	"DELETE /api/v1/groups/{group_uuid}/members/{user_uuid}": {Summary: "Remove a member from a group", Response: dto.GroupResponseDTO{}},
+	"POST /api/v1/groups/{group_uuid}/roles":                 {Summary: "Add roles to a group", Request: dto.GroupAddRolesRequestDTO{}, Response: dto.GroupResponseDTO{}},
+	"DELETE /api/v1/groups/{group_uuid}/roles/{role_uuid}":   {Summary: "Remove a role from a group", Response: dto.GroupResponseDTO{}},
+
+	// Identity providers
+	"GET /api/v1/identity_providers/":                                {Summary: "List identity providers", Query: dto.IdentityProviderFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.IdentityProviderResponseDTO]{}},
+	"POST /api/v1/identity_providers/":                               {Summary: "Create an identity provider", Request: dto.IdentityProviderCreateRequestDTO{}, Response: dto.IdentityProviderDetailResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/identity_providers/{identity_provider_uuid}":        {Summary: "Get an identity provider", Response: dto.IdentityProviderDetailResponseDTO{}},
+	"PUT /api/v1/identity_providers/{identity_provider_uuid}":        {Summary: "Update an identity provider", Request: dto.IdentityProviderUpdateRequestDTO{}, Response: dto.IdentityProviderDetailResponseDTO{}},
+	"DELETE /api/v1/identity_providers/{identity_provider_uuid}":     {Summary: "Delete an identity provider", Response: dto.IdentityProviderDetailResponseDTO{}},
+	"PUT /api/v1/identity_providers/{identity_provider_uuid}/status": {Summary: "Set an identity provider's status", Request: dto.IdentityProviderStatusUpdateDTO{}, Response: dto.IdentityProviderDetailResponseDTO{}},
+
+	// Security
+	"GET /api/v1/ip-restriction-rules/":                                    {Summary: "List IP restriction rules", Query: dto.IPRestrictionRuleFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.IPRestrictionRuleResponseDTO]{}},
+	"POST /api/v1/ip-restriction-rules/":                                   {Summary: "Create an IP restriction rule", Request: dto.IPRestrictionRuleCreateRequestDTO{}, Response: dto.IPRestrictionRuleResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/ip-restriction-rules/{ip_restriction_rule_uuid}":          {Summary: "Get an IP restriction rule", Response: dto.IPRestrictionRuleResponseDTO{}},
+	"PUT /api/v1/ip-restriction-rules/{ip_restriction_rule_uuid}":          {Summary: "Update an IP restriction rule", Request: dto.IPRestrictionRuleUpdateRequestDTO{}, Response: dto.IPRestrictionRuleResponseDTO{}},
+	"DELETE /api/v1/ip-restriction-rules/{ip_restriction_rule_uuid}":       {Summary: "Delete an IP restriction rule", Response: dto.IPRestrictionRuleResponseDTO{}},
+	"PATCH /api/v1/ip-restriction-rules/{ip_restriction_rule_uuid}/status": {Summary: "Set an IP restriction rule's status", Request: dto.IPRestrictionRuleUpdateStatusRequestDTO{}, Response: dto.IPRestrictionRuleResponseDTO{}},
+	"GET /api/v1/login-hooks/":                                             {Summary: "List login hooks", Query: dto.LoginHookFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.LoginHookResponseDTO]{}},
+	"POST /api/v1/login-hooks/":                                            {Summary: "Create a login hook", Request: dto.LoginHookRequestDTO{}, Response: dto.LoginHookResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/login-hooks/{login_hook_uuid}":                            {Summary: "Get a login hook", Response: dto.LoginHookResponseDTO{}},
+	"PUT /api/v1/login-hooks/{login_hook_uuid}":                            {Summary: "Update a login hook", Request: dto.LoginHookRequestDTO{}, Response: dto.LoginHookResponseDTO{}},
+	"DELETE /api/v1/login-hooks/{login_hook_uuid}":                         {Summary: "Delete a login hook", Response: dto.LoginHookResponseDTO{}},
+	"PATCH /api/v1/login-hooks/{login_hook_uuid}/status":                   {Summary: "Set a login hook's status", Request: dto.LoginHookUpdateStatusRequestDTO{}, Response: dto.LoginHookResponseDTO{}},
+	"GET /api/v1/policy-documents/":                                        {Summary: "List policy documents", Query: dto.PolicyDocumentFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.PolicyDocumentResponseDTO]{}},
+	"POST /api/v1/policy-documents/":                                       {Summary: "Create a draft policy document", Request: dto.PolicyDocumentRequestDTO{}, Response: dto.PolicyDocumentResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/policy-documents/{policy_document_uuid}":                  {Summary: "Get a policy document", Response: dto.PolicyDocumentResponseDTO{}},
+	"PUT /api/v1/policy-documents/{policy_document_uuid}":                  {Summary: "Update a draft policy document", Request: dto.PolicyDocumentRequestDTO{}, Response: dto.PolicyDocumentResponseDTO{}},
+	"DELETE /api/v1/policy-documents/{policy_document_uuid}":               {Summary: "Delete a draft policy document", Response: dto.PolicyDocumentResponseDTO{}},
+	"POST /api/v1/policy-documents/{policy_document_uuid}/publish":         {Summary: "Publish a policy document", Response: dto.PolicyDocumentResponseDTO{}},
+	"GET /api/v1/policy-documents/{policy_document_uuid}/acceptances":      {Summary: "List a policy document's acceptances", Query: dto.PaginationRequestDTO{}, Response: dto.PaginatedResponseDTO[dto.PolicyAcceptanceResponseDTO]{}},
+	"GET /api/v1/security-settings/lockout":                                {Summary: "Get the lockout settings", Response: dto.SecuritySettingConfigResponseDTO{}},
+	"PUT /api/v1/security-settings/lockout":                                {Summary: "Update the lockout settings", Request: dto.SecuritySettingUpdateConfigRequestDTO{}, Response: dto.SecuritySettingConfigResponseDTO{}},
+	"GET /api/v1/security-settings/mfa":                                    {Summary: "Get the MFA settings", Response: dto.SecuritySettingConfigResponseDTO{}},
+	"PUT /api/v1/security-settings/mfa":                                    {Summary: "Update the MFA settings", Request: dto.SecuritySettingUpdateConfigRequestDTO{}, Response: dto.SecuritySettingConfigResponseDTO{}},
+	"GET /api/v1/security-settings/password":                               {Summary: "Get the password settings", Response: dto.SecuritySettingConfigResponseDTO{}},
+	"PUT /api/v1/security-settings/password":                               {Summary: "Update the password settings", Request: dto.SecuritySettingUpdateConfigRequestDTO{}, Response: dto.SecuritySettingConfigResponseDTO{}},
+	"GET /api/v1/security-settings/registration":                           {Summary: "Get the registration settings", Response: dto.SecuritySettingConfigResponseDTO{}},
+	"PUT /api/v1/security-settings/registration":                           {Summary: "Update the registration settings", Request: dto.SecuritySettingUpdateConfigRequestDTO{}, Response: dto.SecuritySettingConfigResponseDTO{}},
+	"GET /api/v1/security-settings/session":                                {Summary: "Get the session settings", Response: dto.SecuritySettingConfigResponseDTO{}},
+	"PUT /api/v1/security-settings/session":                                {Summary: "Update the session settings", Request: dto.SecuritySettingUpdateConfigRequestDTO{}, Response: dto.SecuritySettingConfigResponseDTO{}},
+	"GET /api/v1/security-settings/threat":                                 {Summary: "Get the threat settings", Response: dto.SecuritySettingConfigResponseDTO{}},
+	"PUT /api/v1/security-settings/threat":                                 {Summary: "Update the threat settings", Request: dto.SecuritySettingUpdateConfigRequestDTO{}, Response: dto.SecuritySettingConfigResponseDTO{}},
+	"GET /api/v1/security-settings/token":                                  {Summary: "Get the token settings", Response: dto.SecuritySettingConfigResponseDTO{}},
+	"PUT /api/v1/security-settings/token":                                  {Summary: "Update the token settings", Request: dto.SecuritySettingUpdateConfigRequestDTO{}, Response: dto.SecuritySettingConfigResponseDTO{}},
+
+	// Permissions
+	"GET /api/v1/permissions/":                                                               {Summary: "List permissions", Query: dto.PermissionFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.PermissionResponseDTO]{}},
+	"POST /api/v1/permissions/":                                                              {Summary: "Create a permission", Request: dto.PermissionCreateRequestDTO{}, Response: dto.PermissionResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/permissions/{permission_uuid}":                                              {Summary: "Get a permission", Response: dto.PermissionResponseDTO{}},
+	"PUT /api/v1/permissions/{permission_uuid}":                                              {Summary: "Update a permission", Request: dto.PermissionUpdateRequestDTO{}, Response: dto.PermissionResponseDTO{}},
+	"DELETE /api/v1/permissions/{permission_uuid}":                                           {Summary: "Delete a permission", Response: dto.PermissionResponseDTO{}},
+	"PUT /api/v1/permissions/{permission_uuid}/status":                                       {Summary: "Set a permission's status", Request: dto.PermissionStatusUpdateDTO{}, Response: dto.PermissionResponseDTO{}},
+	"GET /api/v1/permission-groups/":                                                         {Summary: "List permission groups", Response: dto.PaginatedResponseDTO[dto.PermissionGroupResponseDTO]{}},
+	"POST /api/v1/permission-groups/":                                                        {Summary: "Create a permission group", Request: dto.PermissionGroupCreateRequestDTO{}, Response: dto.PermissionGroupResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/permission-groups/{permission_group_uuid}":                                  {Summary: "Get a permission group", Response: dto.PermissionGroupResponseDTO{}},
+	"PUT /api/v1/permission-groups/{permission_group_uuid}":                                  {Summary: "Update a permission group", Request: dto.PermissionGroupUpdateRequestDTO{}, Response: dto.PermissionGroupResponseDTO{}},
+	"DELETE /api/v1/permission-groups/{permission_group_uuid}":                               {Summary: "Delete a permission group", Response: dto.PermissionGroupResponseDTO{}},
+	"POST /api/v1/permission-groups/{permission_group_uuid}/permissions":                     {Summary: "Add permissions to a permission group", Request: dto.PermissionGroupAddPermissionsRequestDTO{}, Response: dto.PermissionGroupResponseDTO{}},
+	"DELETE /api/v1/permission-groups/{permission_group_uuid}/permissions/{permission_uuid}": {Summary: "Remove a permission from a permission group", Response: dto.PermissionGroupResponseDTO{}},
+
+	// Policies and services
+	"GET /api/v1/policies/":                                         {Summary: "List policies", Response: dto.PaginatedResponseDTO[dto.PolicyResponseDTO]{}},
+	"POST /api/v1/policies/":                                        {Summary: "Create a policy", Request: dto.PolicyCreateRequestDTO{}, Response: dto.PolicyDetailResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/policies/{policy_uuid}":                            {Summary: "Get a policy", Response: dto.PolicyDetailResponseDTO{}},
+	"PUT /api/v1/policies/{policy_uuid}":                            {Summary: "Update a policy", Request: dto.PolicyUpdateRequestDTO{}, Response: dto.PolicyDetailResponseDTO{}},
+	"DELETE /api/v1/policies/{policy_uuid}":                         {Summary: "Delete a policy", Response: dto.PolicyDetailResponseDTO{}},
+	"GET /api/v1/policies/{policy_uuid}/services":                   {Summary: "List a policy's services", Query: dto.PolicyServicesFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.ServiceResponseDTO]{}},
+	"PUT /api/v1/policies/{policy_uuid}/status":                     {Summary: "Set a policy's status", Request: dto.PolicyStatusUpdateDTO{}, Response: dto.PolicyDetailResponseDTO{}},
+	"GET /api/v1/services/":                                         {Summary: "List services", Query: dto.ServiceFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.ServiceResponseDTO]{}},
+	"POST /api/v1/services/":                                        {Summary: "Create a service", Request: dto.ServiceCreateOrUpdateRequestDTO{}, Response: dto.ServiceResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/services/{service_uuid}":                           {Summary: "Get a service", Response: dto.ServiceResponseDTO{}},
+	"PUT /api/v1/services/{service_uuid}":                           {Summary: "Update a service", Request: dto.ServiceCreateOrUpdateRequestDTO{}, Response: dto.ServiceResponseDTO{}},
+	"DELETE /api/v1/services/{service_uuid}":                        {Summary: "Delete a service", Response: dto.ServiceResponseDTO{}},
+	"PUT /api/v1/services/{service_uuid}/status":                    {Summary: "Set a service's status", Request: dto.ServiceStatusUpdateRequestDTO{}, Response: dto.ServiceResponseDTO{}},
+	"GET /api/v1/services/{service_uuid}/policies":                  {Summary: "List a service's policies", Response: []dto.PolicyResponseDTO{}},
+	"POST /api/v1/services/{service_uuid}/policies/{policy_uuid}":   {Summary: "Assign a policy to a service"},
+	"DELETE /api/v1/services/{service_uuid}/policies/{policy_uuid}": {Summary: "Remove a policy from a service"},
+
+	// Roles
+	"GET /api/v1/roles/":                                             {Summary: "List roles", Query: dto.RoleFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.RoleResponseDTO]{}},
+	"POST /api/v1/roles/":                                            {Summary: "Create a role", Request: dto.RoleCreateOrUpdateRequestDTO{}, Response: dto.RoleResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/roles/{role_uuid}":                                  {Summary: "Get a role", Response: dto.RoleResponseDTO{}},
+	"PUT /api/v1/roles/{role_uuid}":                                  {Summary: "Update a role", Request: dto.RoleCreateOrUpdateRequestDTO{}, Response: dto.RoleResponseDTO{}},
+	"DELETE /api/v1/roles/{role_uuid}":                               {Summary: "Delete a role", Response: dto.RoleResponseDTO{}},
+	"PUT /api/v1/roles/{role_uuid}/default":                          {Summary: "Make a role the default", Response: dto.RoleResponseDTO{}},
+	"PUT /api/v1/roles/{role_uuid}/inheritable":                      {Summary: "Set whether a role is inherited by child tenants", Request: inheritableRequest{}, Response: dto.RoleResponseDTO{}},
+	"PUT /api/v1/roles/{role_uuid}/status":                           {Summary: "Set a role's status", Request: statusRequest{}, Response: dto.RoleResponseDTO{}},
+	"GET /api/v1/roles/{role_uuid}/permissions":                      {Summary: "List a role's permissions", Response: dto.PaginatedResponseDTO[dto.PermissionResponseDTO]{}},
+	"POST /api/v1/roles/{role_uuid}/permissions":                     {Summary: "Add permissions to a role", Request: dto.RoleAddPermissionsRequestDTO{}, Response: dto.RoleResponseDTO{}},
+	"DELETE /api/v1/roles/{role_uuid}/permissions/{permission_uuid}": {Summary: "Remove a permission from a role", Response: dto.RoleResponseDTO{}},
+	"POST /api/v1/roles/{role_uuid}/permission-groups":               {Summary: "Assign a permission group to a role", Response: dto.PermissionGroupAssignResponseDTO{}},
+
+	// Signup flows
+	"GET /api/v1/signup_flows/":                                        {Summary: "List signup flows", Query: dto.SignupFlowFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.SignupFlowResponseDTO]{}},
+	"POST /api/v1/signup_flows/":                                       {Summary: "Create a signup flow", Request: dto.SignupFlowCreateRequestDTO{}, Response: dto.SignupFlowResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/signup_flows/{signup_flow_uuid}":                      {Summary: "Get a signup flow", Response: dto.SignupFlowResponseDTO{}},
+	"PUT /api/v1/signup_flows/{signup_flow_uuid}":                      {Summary: "Update a signup flow", Request: dto.SignupFlowUpdateRequestDTO{}, Response: dto.SignupFlowResponseDTO{}},
+	"DELETE /api/v1/signup_flows/{signup_flow_uuid}":                   {Summary: "Delete a signup flow", Response: dto.SignupFlowResponseDTO{}},
+	"PATCH /api/v1/signup_flows/{signup_flow_uuid}/status":             {Summary: "Set a signup flow's status", Request: dto.SignupFlowUpdateStatusRequestDTO{}, Response: dto.SignupFlowResponseDTO{}},
+	"GET /api/v1/signup_flows/{signup_flow_uuid}/roles/":               {Summary: "List a signup flow's roles", Response: dto.PaginatedResponseDTO[dto.RoleResponseDTO]{}},
+	"POST /api/v1/signup_flows/{signup_flow_uuid}/roles/":              {Summary: "Assign roles to a signup flow", Request: dto.SignupFlowAssignRolesRequestDTO{}, Response: []dto.RoleResponseDTO{}},
+	"DELETE /api/v1/signup_flows/{signup_flow_uuid}/roles/{role_uuid}": {Summary: "Remove a role from a signup flow"},
+
+	// Tenants
+	"GET /api/v1/tenants/":                                                  {Summary: "List tenants", Query: dto.TenantFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.TenantResponseDTO]{}},
+	"POST /api/v1/tenants/":                                                 {Summary: "Create a tenant", Request: dto.TenantCreateRequestDTO{}, Response: dto.TenantResponseDTO{}, Status: http.StatusCreated},
+	"POST /api/v1/tenants/self-service/":                                    {Summary: "Create a tenant owned by the caller", Request: dto.TenantSelfServiceRequestDTO{}, Response: dto.TenantResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/tenants/{tenant_uuid}":                                     {Summary: "Get a tenant", Response: dto.TenantResponseDTO{}},
+	"PUT /api/v1/tenants/{tenant_uuid}":                                     {Summary: "Update a tenant", Request: dto.TenantUpdateRequestDTO{}, Response: dto.TenantResponseDTO{}},
+	"DELETE /api/v1/tenants/{tenant_uuid}":                                  {Summary: "Delete a tenant", Response: dto.TenantResponseDTO{}},
+	"GET /api/v1/tenants/{tenant_uuid}/children":                            {Summary: "List a tenant's children", Query: dto.TenantFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.TenantResponseDTO]{}},
+	"PUT /api/v1/tenants/{tenant_uuid}/parent":                              {Summary: "Set a tenant's parent", Request: dto.TenantSetParentRequestDTO{}, Response: dto.TenantResponseDTO{}},
+	"PUT /api/v1/tenants/{tenant_uuid}/public":                              {Summary: "Toggle whether a tenant is public", Response: dto.TenantResponseDTO{}},
+	"PUT /api/v1/tenants/{tenant_uuid}/status":                              {Summary: "Set a tenant's status", Request: statusRequest{}, Response: dto.TenantResponseDTO{}},
+	"GET /api/v1/tenants/{tenant_uuid}/plan":                                {Summary: "Get a tenant's billing plan", Response: dto.TenantPlanResponseDTO{}},
+	"PUT /api/v1/tenants/{tenant_uuid}/plan":                                {Summary: "Set a tenant's billing plan", Request: dto.TenantPlanSetRequestDTO{}, Response: dto.TenantPlanResponseDTO{}},
+	"GET /api/v1/tenants/{tenant_uuid}/usage":                               {Summary: "Get a tenant's usage and quotas", Query: dto.TenantUsageFilterDTO{}, Response: dto.TenantUsageResponseDTO{}},
+	"POST /api/v1/tenants/{tenant_uuid}/deprovision/":                       {Summary: "Request deprovisioning of a tenant", Request: dto.TenantDeprovisionRequestDTO{}, Response: dto.TenantDeprovisionRequestResponseDTO{}, Status: http.StatusCreated},
+	"POST /api/v1/tenants/{tenant_uuid}/deprovision/confirm":                {Summary: "Confirm deprovisioning of a tenant", Request: dto.TenantDeprovisionConfirmRequestDTO{}, Response: dto.TenantDeprovisionResponseDTO{}},
+	"GET /api/v1/tenants/{tenant_uuid}/members/":                            {Summary: "List a tenant's members", Query: dto.TenantMemberFilterDTO{}, Response: []dto.TenantMemberResponseDTO{}},
+	"POST /api/v1/tenants/{tenant_uuid}/members/":                           {Summary: "Add a member to a tenant", Request: dto.TenantMemberAddMemberRequestDTO{}, Response: dto.TenantMemberResponseDTO{}, Status: http.StatusCreated},
+	"DELETE /api/v1/tenants/{tenant_uuid}/members/{tenant_member_uuid}":     {Summary: "Remove a member from a tenant"},
+	"PATCH /api/v1/tenants/{tenant_uuid}/members/{tenant_member_uuid}/role": {Summary: "Set a tenant member's role", Request: dto.TenantMemberUpdateRoleRequestDTO{}, Response: dto.TenantMemberResponseDTO{}},
+	"GET /api/v1/tenants/{tenant_uuid}/onboarding/":                         {Summary: "Get a tenant's onboarding checklist", Response: dto.OnboardingChecklistResponseDTO{}},
+	"POST /api/v1/tenants/{tenant_uuid}/onboarding/":                        {Summary: "Onboard a tenant", Request: dto.OnboardingRequestDTO{}, Response: dto.OnboardingResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/tenant-settings/audit":                                     {Summary: "Get the audit settings", Response: dto.TenantSettingConfigResponseDTO{}},
+	"PUT /api/v1/tenant-settings/audit":                                     {Summary: "Update the audit settings", Request: dto.TenantSettingUpdateConfigRequestDTO{}, Response: dto.TenantSettingConfigResponseDTO{}},
+	"GET /api/v1/tenant-settings/feature-flags":                             {Summary: "Get the feature flags", Response: dto.TenantSettingConfigResponseDTO{}},
+	"PUT /api/v1/tenant-settings/feature-flags":                             {Summary: "Update the feature flags", Request: dto.TenantSettingUpdateConfigRequestDTO{}, Response: dto.TenantSettingConfigResponseDTO{}},
+	"GET /api/v1/tenant-settings/maintenance":                               {Summary: "Get the maintenance settings", Response: dto.TenantSettingConfigResponseDTO{}},
+	"PUT /api/v1/tenant-settings/maintenance":                               {Summary: "Update the maintenance settings", Request: dto.TenantSettingUpdateConfigRequestDTO{}, Response: dto.TenantSettingConfigResponseDTO{}},
+	"GET /api/v1/tenant-settings/rate-limit":                                {Summary: "Get the rate limit settings", Response: dto.TenantSettingConfigResponseDTO{}},
+	"PUT /api/v1/tenant-settings/rate-limit":                                {Summary: "Update the rate limit settings", Request: dto.TenantSettingUpdateConfigRequestDTO{}, Response: dto.TenantSettingConfigResponseDTO{}},
+	"GET /api/v1/tenant-settings/user-metadata-schema":                      {Summary: "Get the user metadata schema", Response: dto.TenantSettingConfigResponseDTO{}},
+	"PUT /api/v1/tenant-settings/user-metadata-schema":                      {Summary: "Update the user metadata schema", Request: dto.TenantSettingUserMetadataSchemaRequestDTO{}, Response: dto.TenantSettingConfigResponseDTO{}},
+	"GET /api/v1/tenant-settings/user-setting-defaults":                     {Summary: "Get the default user settings", Response: dto.TenantSettingConfigResponseDTO{}},
+	"PUT /api/v1/tenant-settings/user-setting-defaults":                     {Summary: "Update the default user settings", Request: dto.TenantSettingUserSettingDefaultsRequestDTO{}, Response: dto.TenantSettingConfigResponseDTO{}},
+
+	// Users
+	"GET /api/v1/users/":                                                {Summary: "List users", Query: dto.UserFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.UserResponseDTO]{}},
+	"POST /api/v1/users/":                                               {Summary: "Create a user", Request: dto.UserCreateRequestDTO{}, Response: dto.UserResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/users/delta":                                           {Summary: "List users changed since a cursor", Query: dto.UserDeltaFilterDTO{}, Response: dto.UserDeltaResponseDTO{}},
+	"GET /api/v1/users/search":                                          {Summary: "Search users by relevance", Query: dto.UserSearchDTO{}, Response: dto.PaginatedResponseDTO[dto.UserResponseDTO]{}},
+	"GET /api/v1/users/export":                                          {Summary: "Export users as CSV or NDJSON", Query: dto.UserExportFilterDTO{}, Raw: true},
+	"POST /api/v1/users/import":                                         {Summary: "Import users", Response: dto.UserImportResponseDTO{}},
+	"GET /api/v1/users/import/{import_uuid}":                            {Summary: "Get a user import", Response: dto.UserImportResponseDTO{}},
+	"GET /api/v1/users/{user_uuid}":                                     {Summary: "Get a user", Response: dto.UserResponseDTO{}},
+	"PUT /api/v1/users/{user_uuid}":                                     {Summary: "Update a user", Request: dto.UserUpdateRequestDTO{}, Response: dto.UserResponseDTO{}},
+	"DELETE /api/v1/users/{user_uuid}":                                  {Summary: "Delete a user", Response: dto.UserResponseDTO{}},
+	"DELETE /api/v1/users/{user_uuid}/purge":                            {Summary: "Purge a deleted user", Response: dto.UserResponseDTO{}},
+	"POST /api/v1/users/{user_uuid}/restore":                            {Summary: "Restore a deleted user", Response: dto.UserResponseDTO{}},
+	"POST /api/v1/users/{user_uuid}/enable":                             {Summary: "Enable a disabled user or clear a security hold", Response: dto.UserResponseDTO{}},
+	"POST /api/v1/users/{user_uuid}/security-hold":                      {Summary: "Put a user on security hold", Request: dto.SecurityHoldRequestDTO{}, Response: dto.UserResponseDTO{}},
+	"PATCH /api/v1/users/{user_uuid}/status":                            {Summary: "Set a user's status", Request: dto.UserSetStatusRequestDTO{}, Response: dto.UserResponseDTO{}},
+	"PATCH /api/v1/users/{user_uuid}/complete-account":                  {Summary: "Mark a user's account complete", Response: dto.UserResponseDTO{}},
+	"PATCH /api/v1/users/{user_uuid}/verify-email":                      {Summary: "Mark a user's email verified", Response: dto.UserResponseDTO{}},
+	"PATCH /api/v1/users/{user_uuid}/verify-phone":                      {Summary: "Mark a user's phone verified", Response: dto.UserResponseDTO{}},
+	"POST /api/v1/users/{user_uuid}/impersonate":                        {Summary: "Impersonate a user", Request: dto.ImpersonateRequestDTO{}, Response: dto.ImpersonationResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/users/{user_uuid}/identities":                          {Summary: "List a user's linked identities", Query: dto.UserIdentityFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.UserIdentityResponseDTO]{}},
+	"GET /api/v1/users/{user_uuid}/profiles":                            {Summary: "List a user's profiles", Query: dto.ProfileFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.ProfileResponseDTO]{}},
+	"POST /api/v1/users/{user_uuid}/profiles":                           {Summary: "Create a profile for a user", Request: dto.ProfileRequestDTO{}, Response: dto.ProfileResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/users/{user_uuid}/profiles/{profile_uuid}":             {Summary: "Get a user's profile", Response: dto.ProfileResponseDTO{}},
+	"PUT /api/v1/users/{user_uuid}/profiles/{profile_uuid}":             {Summary: "Update a user's profile", Request: dto.ProfileRequestDTO{}, Response: dto.ProfileResponseDTO{}},
+	"DELETE /api/v1/users/{user_uuid}/profiles/{profile_uuid}":          {Summary: "Delete a user's profile", Response: dto.ProfileResponseDTO{}},
+	"PUT /api/v1/users/{user_uuid}/profiles/{profile_uuid}/set-default": {Summary: "Make a user's profile the default", Response: dto.ProfileResponseDTO{}},
+	"GET /api/v1/users/{user_uuid}/roles":                               {Summary: "List a user's roles", Query: dto.UserRoleFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.RoleResponseDTO]{}},
+	"POST /api/v1/users/{user_uuid}/roles":                              {Summary: "Assign roles to a user", Request: dto.UserAssignRolesRequestDTO{}, Response: dto.UserResponseDTO{}},
+	"DELETE /api/v1/users/{user_uuid}/roles/{role_uuid}":                {Summary: "Remove a role from a user", Response: dto.UserResponseDTO{}},
+	"GET /api/v1/users/{user_uuid}/scoped-roles":                        {Summary: "List a user's scoped roles", Response: []dto.ScopedRoleResponseDTO{}},
+	"POST /api/v1/users/{user_uuid}/scoped-roles":                       {Summary: "Assign a scoped role to a user", Request: dto.ScopedRoleAssignRequestDTO{}, Response: dto.ScopedRoleResponseDTO{}},
+	"DELETE /api/v1/users/{user_uuid}/scoped-roles/{scoped_role_uuid}":  {Summary: "Remove a scoped role from a user", Response: dto.ScopedRoleResponseDTO{}},
+	"GET /api/v1/users/{user_uuid}/policy-acceptances":                  {Summary: "List the policy documents a user accepted", Query: dto.PaginationRequestDTO{}, Response: dto.PaginatedResponseDTO[dto.PolicyAcceptanceResponseDTO]{}},
+	"GET /api/v1/users/{user_uuid}/settings":                            {Summary: "Get a user's settings", Response: dto.UserSettingResponseDTO{}},
+	"PUT /api/v1/users/{user_uuid}/settings":                            {Summary: "Replace a user's settings", Request: dto.UserSettingRequestDTO{}, Response: dto.UserSettingResponseDTO{}},
+	"DELETE /api/v1/users/{user_uuid}/settings":                         {Summary: "Reset a user's settings", Response: dto.UserSettingResponseDTO{}},
+
+	// Webhooks
+	"GET /api/v1/webhook-endpoints/":                                                           {Summary: "List webhook endpoints", Query: dto.WebhookEndpointFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.WebhookEndpointResponseDTO]{}},
+	"POST /api/v1/webhook-endpoints/":                                                          {Summary: "Create a webhook endpoint", Request: dto.WebhookEndpointCreateRequestDTO{}, Response: dto.WebhookEndpointResponseDTO{}, Status: http.StatusCreated},
+	"GET /api/v1/webhook-endpoints/{webhook_endpoint_uuid}":                                    {Summary: "Get a webhook endpoint", Response: dto.WebhookEndpointResponseDTO{}},
+	"PUT /api/v1/webhook-endpoints/{webhook_endpoint_uuid}":                                    {Summary: "Update a webhook endpoint", Request: dto.WebhookEndpointUpdateRequestDTO{}, Response: dto.WebhookEndpointResponseDTO{}},
+	"DELETE /api/v1/webhook-endpoints/{webhook_endpoint_uuid}":                                 {Summary: "Delete a webhook endpoint", Response: dto.WebhookEndpointResponseDTO{}},
+	"PATCH /api/v1/webhook-endpoints/{webhook_endpoint_uuid}/status":                           {Summary: "Set a webhook endpoint's status", Request: dto.WebhookEndpointUpdateStatusRequestDTO{}, Response: dto.WebhookEndpointResponseDTO{}},
+	"POST /api/v1/webhook-endpoints/{webhook_endpoint_uuid}/rotate-secret":                     {Summary: "Rotate a webhook endpoint's secret", Request: dto.WebhookEndpointRotateSecretRequestDTO{}, Response: dto.WebhookEndpointResponseDTO{}},
+	"GET /api/v1/webhook-endpoints/{webhook_endpoint_uuid}/deliveries":                         {Summary: "List a webhook endpoint's deliveries", Query: dto.WebhookDeliveryFilterDTO{}, Response: dto.PaginatedResponseDTO[dto.WebhookDeliveryResponseDTO]{}},
+	"GET /api/v1/webhook-endpoints/{webhook_endpoint_uuid}/deliveries/{webhook_delivery_uuid}": {Summary: "Get a webhook delivery", Response: dto.WebhookDeliveryResponseDTO{}},
+}
@@ -0,0 +1,232 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+)
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	uuidType          = reflect.TypeFor[uuid.UUID]()
+	dateType          = reflect.TypeFor[dto.Date]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// schemaRefPrefix prefixes the references to component schemas.
+const schemaRefPrefix = "#/components/schemas/"
+
+// typeArgPackage matches the package path qualifying a type argument in the
+// name of an instantiated generic type.
+var typeArgPackage = regexp.MustCompile(`[\w./-]+\.`)
+
+// schemaGenerator derives JSON schemas from Go types the way encoding/json
+// marshals them. Named structs become component schemas, referenced by name.
+type schemaGenerator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		schemas: map[string]*Schema{},
+		names:   map[reflect.Type]string{},
+	}
+}
+
+// schemaOf returns the schema of values of type t.
+func (g *schemaGenerator) schemaOf(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Pointer {
+		return nullable(g.schemaOf(t.Elem()))
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case dateType:
+		return nullable(&Schema{Type: "string", Format: "date"})
+	}
+	// Custom JSON encodings cannot be inspected; text encodings are strings
+	if implements(t, jsonMarshalerType) {
+		return &Schema{}
+	}
+	if implements(t, textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return g.structRef(t)
+	}
+	// Interfaces and anything else hold any JSON value
+	return &Schema{}
+}
+
+// structRef returns a reference to the component schema of the struct type
+// t, adding the component on first use. Anonymous structs are inlined.
+func (g *schemaGenerator) structRef(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return g.object(t)
+	}
+	name, ok := g.names[t]
+	if !ok {
+		name = g.componentName(t)
+		g.names[t] = name
+		// Registered before the fields are walked so recursive types
+		// reference the component instead of looping
+		s := &Schema{}
+		g.schemas[name] = s
+		*s = *g.object(t)
+	}
+	return &Schema{Ref: schemaRefPrefix + name}
+}
+
+// componentName names the component schema of t after the type, e.g.
+// "PaginatedResponseDTO_UserResponseDTO" for an instantiated generic type.
+// A name already used by a type of another package is prefixed with the
+// package name.
+func (g *schemaGenerator) componentName(t reflect.Type) string {
+	name := typeArgPackage.ReplaceAllString(t.Name(), "")
+	name = strings.NewReplacer("[", "_", "]", "", ",", "_", "*", "", " ", "").Replace(name)
+	if _, taken := g.schemas[name]; taken {
+		pkg := t.PkgPath()
+		name = pkg[strings.LastIndex(pkg, "/")+1:] + "." + name
+	}
+	return name
+}
+
+// object returns the schema of a struct, with a property per field that
+// encoding/json would marshal. Fields of embedded structs are promoted.
+func (g *schemaGenerator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.addFields(s, t)
+	return s
+}
+
+func (g *schemaGenerator) addFields(s *Schema, t reflect.Type) {
+	for field := range jsonFields(t) {
+		if field.embedded != nil {
+			g.addFields(s, field.embedded)
+			continue
+		}
+		if _, ok := s.Properties[field.name]; !ok {
+			s.Properties[field.name] = g.schemaOf(field.typ)
+		}
+	}
+}
+
+// queryParameters returns a query parameter per field of the struct v,
+// named after its json tag. Fields that are not scalars are skipped.
+func (g *schemaGenerator) queryParameters(v any) []Parameter {
+	var params []Parameter
+	var add func(t reflect.Type)
+	add = func(t reflect.Type) {
+		for field := range jsonFields(t) {
+			if field.embedded != nil {
+				add(field.embedded)
+				continue
+			}
+			typ := field.typ
+			if typ.Kind() == reflect.Pointer {
+				typ = typ.Elem()
+			}
+			schema := g.schemaOf(typ)
+			if schema.Ref != "" || schema.Type == nil || schema.Type == "object" {
+				continue
+			}
+			params = append(params, Parameter{Name: field.name, In: "query", Schema: schema})
+		}
+	}
+	add(reflect.TypeOf(v))
+	return params
+}
+
+// jsonField is a struct field as encoding/json sees it. embedded is set
+// instead of name for an untagged embedded struct, whose fields are promoted.
+type jsonField struct {
+	name     string
+	typ      reflect.Type
+	embedded reflect.Type
+}
+
+// jsonFields yields the marshalled fields of the struct type t in order.
+func jsonFields(t reflect.Type) func(yield func(jsonField) bool) {
+	return func(yield func(jsonField) bool) {
+		for i := range t.NumField() {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, _, _ := strings.Cut(tag, ",")
+
+			if f.Anonymous && name == "" {
+				ft := f.Type
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					if !yield(jsonField{embedded: ft}) {
+						return
+					}
+					continue
+				}
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			if !yield(jsonField{name: name, typ: f.Type}) {
+				return
+			}
+		}
+	}
+}
+
+// nullable allows null in place of a value of schema s.
+func nullable(s *Schema) *Schema {
+	switch typ := s.Type.(type) {
+	case string:
+		s.Type = []string{typ, "null"}
+		return s
+	case nil:
+		if s.Ref != "" {
+			return &Schema{AnyOf: []*Schema{s, {Type: "null"}}}
+		}
+	}
+	return s
+}
+
+// implements reports whether values of t, or pointers to them, implement
+// the interface iface.
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
@@ -0,0 +1,91 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaTestBase struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type schemaTestNode struct {
+	schemaTestBase
+	Name     string            `json:"name"`
+	Count    int32             `json:"count,omitempty"`
+	Ratio    float64           `json:"ratio"`
+	Note     *string           `json:"note"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels"`
+	Raw      json.RawMessage   `json:"raw"`
+	Birth    *dto.Date         `json:"birth"`
+	Parent   *schemaTestNode   `json:"parent"`
+	Secret   string            `json:"-"`
+	internal string
+	Untagged bool
+}
+
+func TestSchemaGenerator_Struct(t *testing.T) {
+	g := newSchemaGenerator()
+	ref := g.schemaOf(reflect.TypeFor[schemaTestNode]())
+	assert.Equal(t, &Schema{Ref: "#/components/schemas/schemaTestNode"}, ref)
+
+	s := g.schemas["schemaTestNode"]
+	require.NotNil(t, s)
+	assert.Equal(t, "object", s.Type)
+	assert.ElementsMatch(t, []string{
+		"id", "created_at", "name", "count", "ratio", "note", "tags", "labels", "raw", "birth", "parent", "Untagged",
+	}, keys(s.Properties))
+
+	assert.Equal(t, &Schema{Type: "string", Format: "uuid"}, s.Properties["id"])
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, s.Properties["created_at"])
+	assert.Equal(t, &Schema{Type: "integer", Format: "int32"}, s.Properties["count"])
+	assert.Equal(t, &Schema{Type: "number"}, s.Properties["ratio"])
+	assert.Equal(t, &Schema{Type: []string{"string", "null"}}, s.Properties["note"])
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "string"}}, s.Properties["tags"])
+	assert.Equal(t, &Schema{Type: "object", AdditionalProperties: &Schema{Type: "string"}}, s.Properties["labels"])
+	assert.Equal(t, &Schema{}, s.Properties["raw"], "custom JSON encodings hold any value")
+	assert.Equal(t, &Schema{Type: []string{"string", "null"}, Format: "date"}, s.Properties["birth"])
+	assert.Equal(t, &Schema{AnyOf: []*Schema{ref, {Type: "null"}}}, s.Properties["parent"], "recursive types are referenced")
+}
+
+func TestSchemaGenerator_GenericName(t *testing.T) {
+	g := newSchemaGenerator()
+	ref := g.schemaOf(reflect.TypeFor[dto.PaginatedResponseDTO[dto.RoleResponseDTO]]())
+	assert.Equal(t, "#/components/schemas/PaginatedResponseDTO_RoleResponseDTO", ref.Ref)
+	assert.Contains(t, g.schemas, "RoleResponseDTO")
+}
+
+func TestSchemaGenerator_QueryParameters(t *testing.T) {
+	type filter struct {
+		Name   string   `json:"name"`
+		Page   *int     `json:"page"`
+		Status []string `json:"status"`
+		Nested struct {
+			X string `json:"x"`
+		} `json:"nested"`
+	}
+
+	params := newSchemaGenerator().queryParameters(filter{})
+	assert.Equal(t, []Parameter{
+		{Name: "name", In: "query", Schema: &Schema{Type: "string"}},
+		{Name: "page", In: "query", Schema: &Schema{Type: "integer", Format: "int64"}},
+		{Name: "status", In: "query", Schema: &Schema{Type: "array", Items: &Schema{Type: "string"}}},
+	}, params)
+}
+
+func keys(m map[string]*Schema) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
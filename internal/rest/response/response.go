@@ -35,8 +35,6 @@ type response struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
 	Message string      `json:"message,omitempty"`
-	Error   string      `json:"error,omitempty"`
-	Details interface{} `json:"details,omitempty"`
 }
 
 // Success sends a successful response with HTTP 200 status
@@ -71,6 +69,16 @@ func Created(w http.ResponseWriter, data interface{}, message string) {
 	})
 }
 
+// Accepted sends a successful response with HTTP 202 status, for work that
+// continues after the response is sent
+func Accepted(w http.ResponseWriter, data interface{}, message string) {
+	writeJSON(w, http.StatusAccepted, response{
+		Success: true,
+		Data:    data,
+		Message: message,
+	})
+}
+
 // CreatedWithCookies sends a created response with optional cookie delivery
 func CreatedWithCookies(w http.ResponseWriter, r *http.Request, data interface{}, message string) {
 	// Check if cookies should be set based on X-Token-Delivery header
@@ -85,25 +93,77 @@ func CreatedWithCookies(w http.ResponseWriter, r *http.Request, data interface{}
 	})
 }
 
-// Error sends an error response with the specified status code
+// ProblemContentType is the media type of error responses (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// ErrorTypeBase prefixes an error code to form the problem "type" URI, which
+// resolves to the code's entry in the error code registry.
+const ErrorTypeBase = apperror.TypeBase
+
+// problem is an RFC 7807 problem details object. Success, Error and Details
+// repeat the pre-problem+json envelope as extension members so existing
+// clients keep working.
+type problem struct {
+	Type   string        `json:"type"`
+	Title  string        `json:"title"`
+	Status int           `json:"status"`
+	Detail string        `json:"detail,omitempty"`
+	Code   apperror.Code `json:"code"`
+	Name   string        `json:"name"`
+
+	Success bool        `json:"success"`
+	Error   string      `json:"error"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// Error sends an error response with the specified status code and the
+// generic error code for that status.
 func Error(w http.ResponseWriter, status int, err string, details ...any) {
-	resp := response{
-		Success: false,
-		Error:   err,
-	}
-	if len(details) > 0 {
-		resp.Details = details[0]
+	writeProblem(w, status, apperror.CodeForStatus(status), err, details...)
+}
+
+// ErrorWithCode sends an error response for a registered error code, with the
+// HTTP status the code is registered with.
+func ErrorWithCode(w http.ResponseWriter, code apperror.Code, err string, details ...any) {
+	status := http.StatusInternalServerError
+	if info, ok := apperror.Lookup(code); ok {
+		status = info.Status
 	}
-	writeJSON(w, status, resp)
+	writeProblem(w, status, code, err, details...)
 }
 
 // ValidationError sends a validation error response with HTTP 400 status
 func ValidationError(w http.ResponseWriter, err error) {
 	if ve, ok := err.(validation.Errors); ok {
-		Error(w, http.StatusBadRequest, "Validation failed", ve)
+		ErrorWithCode(w, apperror.CodeValidationFailed, "Validation failed", ve)
 		return
 	}
-	Error(w, http.StatusBadRequest, "Validation failed", err.Error())
+	ErrorWithCode(w, apperror.CodeValidationFailed, "Validation failed", err.Error())
+}
+
+// writeProblem writes a problem+json response. Codes missing from the
+// registry fall back to the generic code for status.
+func writeProblem(w http.ResponseWriter, status int, code apperror.Code, detail string, details ...any) {
+	info, ok := apperror.Lookup(code)
+	if !ok {
+		info, _ = apperror.Lookup(apperror.CodeForStatus(status))
+	}
+	p := problem{
+		Type:    ErrorTypeBase + string(info.Code),
+		Title:   info.Title,
+		Status:  status,
+		Detail:  detail,
+		Code:    info.Code,
+		Name:    info.Name,
+		Success: false,
+		Error:   detail,
+	}
+	if len(details) > 0 {
+		p.Details = details[0]
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(p)
 }
 
 // writeJSON writes a JSON response with the specified status code
@@ -114,34 +174,49 @@ func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
 }
 
 // HandleServiceError inspects the typed error returned by a service method and
-// writes the appropriate HTTP response. Internal/unexpected errors are logged
+// writes the appropriate HTTP response, with the error's code when one was
+// attached with apperror.WithCode. Internal/unexpected errors are logged
 // server-side (with the request-scoped logger so they carry request_id) and a
 // generic message is sent to the client.
 func HandleServiceError(w http.ResponseWriter, r *http.Request, fallbackMsg string, err error) {
 	var notFound *apperror.NotFoundError
 	var conflict *apperror.ConflictError
+	var precondition *apperror.PreconditionError
 	var forbidden *apperror.ForbiddenError
 	var unauthorized *apperror.UnauthorizedError
 	var validationErr *apperror.ValidationError
+	var tooManyRequests *apperror.TooManyRequestsError
 	var internal *apperror.InternalError
 
+	code, hasCode := apperror.CodeOf(err)
+	reply := func(status int, msg string) {
+		if !hasCode {
+			code = apperror.CodeForStatus(status)
+		}
+		writeProblem(w, status, code, msg)
+	}
+
 	switch {
 	case errors.As(err, &notFound):
-		Error(w, http.StatusNotFound, notFound.Error())
+		reply(http.StatusNotFound, notFound.Error())
 	case errors.As(err, &conflict):
-		Error(w, http.StatusConflict, conflict.Error())
+		reply(http.StatusConflict, conflict.Error())
+	case errors.As(err, &precondition):
+		reply(http.StatusPreconditionFailed, precondition.Error())
 	case errors.As(err, &forbidden):
-		Error(w, http.StatusForbidden, forbidden.Error())
+		reply(http.StatusForbidden, forbidden.Error())
 	case errors.As(err, &unauthorized):
-		Error(w, http.StatusUnauthorized, unauthorized.Error())
+		reply(http.StatusUnauthorized, unauthorized.Error())
 	case errors.As(err, &validationErr):
-		Error(w, http.StatusBadRequest, validationErr.Error())
+		reply(http.StatusBadRequest, validationErr.Error())
+	case errors.As(err, &tooManyRequests):
+		reply(http.StatusTooManyRequests, tooManyRequests.Error())
 	case errors.As(err, &internal):
 		LoggerFromContext(r.Context()).Error("internal service error", "error", internal.Error())
-		Error(w, http.StatusInternalServerError, fallbackMsg)
+		reply(http.StatusInternalServerError, fallbackMsg)
 	default:
 		// Untyped error — log it and return the fallback message.
 		LoggerFromContext(r.Context()).Error("unhandled service error", "error", err.Error())
-		Error(w, http.StatusInternalServerError, fallbackMsg)
+		reply(http.StatusInternalServerError, fallbackMsg)
 	}
 }
@@ -24,6 +24,14 @@ type responseBody struct {
 	Message string          `json:"message,omitempty"`
 	Error   string          `json:"error,omitempty"`
 	Details json.RawMessage `json:"details,omitempty"`
+
+	// Problem details members
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail"`
+	Code   string `json:"code"`
+	Name   string `json:"name"`
 }
 
 func decodeBody(t *testing.T, rr *httptest.ResponseRecorder) responseBody {
@@ -55,6 +63,16 @@ func TestCreated(t *testing.T) {
 	assert.Equal(t, "created", body.Message)
 }
 
+func TestAccepted(t *testing.T) {
+	rr := httptest.NewRecorder()
+	Accepted(rr, map[string]string{"id": "1"}, "queued")
+
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	body := decodeBody(t, rr)
+	assert.True(t, body.Success)
+	assert.Equal(t, "queued", body.Message)
+}
+
 func TestError(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -74,9 +92,16 @@ func TestError(t *testing.T) {
 			Error(rr, tc.status, tc.errMsg, tc.details...)
 
 			assert.Equal(t, tc.wantStatus, rr.Code)
+			assert.Equal(t, ProblemContentType, rr.Header().Get("Content-Type"))
 			body := decodeBody(t, rr)
 			assert.False(t, body.Success)
 			assert.Equal(t, tc.errMsg, body.Error)
+			assert.Equal(t, tc.errMsg, body.Detail)
+			assert.Equal(t, tc.wantStatus, body.Status)
+			assert.Equal(t, string(apperror.CodeForStatus(tc.status)), body.Code)
+			assert.Equal(t, ErrorTypeBase+body.Code, body.Type)
+			assert.NotEmpty(t, body.Title)
+			assert.NotEmpty(t, body.Name)
 			if len(tc.details) > 0 {
 				assert.NotEmpty(t, body.Details)
 			}
@@ -92,10 +117,31 @@ func TestValidationError_WithOzzoErrors(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 	body := decodeBody(t, rr)
 	assert.False(t, body.Success)
+	assert.Equal(t, string(apperror.CodeValidationFailed), body.Code)
 	assert.Equal(t, "Validation failed", body.Error)
 	assert.NotEmpty(t, body.Details)
 }
 
+func TestErrorWithCode(t *testing.T) {
+	rr := httptest.NewRecorder()
+	ErrorWithCode(rr, apperror.CodeAPIKeyRateLimited, "API key rate limit exceeded")
+
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	body := decodeBody(t, rr)
+	assert.Equal(t, "KEY-4002", body.Code)
+	assert.Equal(t, "api_key_rate_limited", body.Name)
+	assert.Equal(t, "/api/v1/errors/KEY-4002", body.Type)
+	assert.Equal(t, "API key rate limit exceeded", body.Detail)
+
+	t.Run("unregistered code", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		ErrorWithCode(rr, "NOPE-0001", "boom")
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Equal(t, string(apperror.CodeInternal), decodeBody(t, rr).Code)
+	})
+}
+
 func TestValidationError_WithPlainError(t *testing.T) {
 	rr := httptest.NewRecorder()
 	ValidationError(rr, assert.AnError)
@@ -174,14 +220,18 @@ func TestHandleServiceError(t *testing.T) {
 		err        error
 		wantStatus int
 		wantError  string
+		wantCode   apperror.Code
 	}{
-		{"not found", apperror.NewNotFound("tenant"), http.StatusNotFound, "tenant not found"},
-		{"conflict", apperror.NewConflict("email already registered"), http.StatusConflict, "email already registered"},
-		{"forbidden", apperror.NewForbidden("profile does not belong to user"), http.StatusForbidden, "profile does not belong to user"},
-		{"unauthorized", apperror.NewUnauthorized("invalid credentials"), http.StatusUnauthorized, "invalid credentials"},
-		{"validation", apperror.NewValidation("cannot delete system policy"), http.StatusBadRequest, "cannot delete system policy"},
-		{"internal", apperror.NewInternal("hash password", errors.New("bcrypt failed")), http.StatusInternalServerError, "fallback message"},
-		{"untyped", errors.New("unexpected db error"), http.StatusInternalServerError, "fallback message"},
+		{"not found", apperror.NewNotFound("tenant"), http.StatusNotFound, "tenant not found", apperror.CodeNotFound},
+		{"conflict", apperror.NewConflict("email already registered"), http.StatusConflict, "email already registered", apperror.CodeConflict},
+		{"precondition", apperror.NewPrecondition("role was changed by another request"), http.StatusPreconditionFailed, "role was changed by another request", apperror.CodePreconditionFailed},
+		{"forbidden", apperror.NewForbidden("profile does not belong to user"), http.StatusForbidden, "profile does not belong to user", apperror.CodeForbidden},
+		{"unauthorized", apperror.NewUnauthorized("invalid credentials"), http.StatusUnauthorized, "invalid credentials", apperror.CodeUnauthorized},
+		{"validation", apperror.NewValidation("cannot delete system policy"), http.StatusBadRequest, "cannot delete system policy", apperror.CodeBadRequest},
+		{"too many requests", apperror.NewTooManyRequests("quota exceeded"), http.StatusTooManyRequests, "quota exceeded", apperror.CodeTooManyRequests},
+		{"internal", apperror.NewInternal("hash password", errors.New("bcrypt failed")), http.StatusInternalServerError, "fallback message", apperror.CodeInternal},
+		{"untyped", errors.New("unexpected db error"), http.StatusInternalServerError, "fallback message", apperror.CodeInternal},
+		{"coded", apperror.WithCode(apperror.CodeInvalidCredentials, apperror.NewUnauthorized("invalid credentials")), http.StatusUnauthorized, "invalid credentials", apperror.CodeInvalidCredentials},
 	}
 
 	for _, tc := range cases {
@@ -194,6 +244,7 @@ func TestHandleServiceError(t *testing.T) {
 			body := decodeBody(t, rr)
 			assert.False(t, body.Success)
 			assert.Equal(t, tc.wantError, body.Error)
+			assert.Equal(t, string(tc.wantCode), body.Code)
 		})
 	}
 }
@@ -0,0 +1,29 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AccountConsentRoute mounts the caller's OAuth consent grant endpoints:
+//   - GET    /account/consents              — Own consent grants
+//   - DELETE /account/consents/{grant_uuid} — Revoke a consent grant
+func AccountConsentRoute(
+	r chi.Router,
+	consentHandler *handler.OAuthConsentHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Route("/account/consents", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"account:user:read:self"})).
+			Get("/", consentHandler.ListGrants)
+		r.With(middleware.PermissionMiddleware([]string{"account:user:update:self"})).
+			Delete("/{grant_uuid}", consentHandler.RevokeGrant)
+	})
+}
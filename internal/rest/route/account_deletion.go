@@ -0,0 +1,38 @@
+package route
+
+import (
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AccountDeletionRoute mounts the self-service account deletion endpoints:
+//   - DELETE /account                 — Delete own account after a grace period
+//   - POST   /account/deletion/cancel — Cancel with the emailed token (public)
+func AccountDeletionRoute(
+	r chi.Router,
+	accountDeletionHandler *handler.AccountDeletionHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"account:user:delete:self"})).
+			Delete("/account", accountDeletionHandler.Delete)
+	})
+
+	// The account can no longer sign in, so the token alone authorizes
+	// cancelling; limits match the other token-based auth endpoints
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequestSizeLimitMiddleware(1024 * 1024))
+		r.Use(middleware.TimeoutMiddleware(30 * time.Second))
+
+		r.Post("/account/deletion/cancel", accountDeletionHandler.Cancel)
+	})
+}
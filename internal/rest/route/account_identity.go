@@ -0,0 +1,35 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AccountIdentityRoute mounts the caller's identity linking endpoints:
+//   - GET    /account/identities                      — Own linked identities
+//   - POST   /account/identities                      — Link an external identity (not with a token)
+//   - DELETE /account/identities/{user_identity_uuid} — Unlink an identity
+func AccountIdentityRoute(
+	r chi.Router,
+	accountIdentityHandler *handler.AccountIdentityHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Route("/account/identities", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"account:identity:read:self"})).
+			Get("/", accountIdentityHandler.List)
+
+		// Linking re-authenticates with the password; a token must not stand in for it
+		r.With(middleware.DenyPersonalAccessTokenMiddleware, middleware.PermissionMiddleware([]string{"account:identity:link:self"})).
+			Post("/", accountIdentityHandler.Link)
+
+		r.With(middleware.PermissionMiddleware([]string{"account:identity:unlink:self"})).
+			Delete("/{user_identity_uuid}", accountIdentityHandler.Unlink)
+	})
+}
@@ -0,0 +1,32 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AccountNotificationRoute mounts the self-service notification endpoints:
+//   - GET /account/notification-settings — Get own notification preferences
+//   - PUT /account/notification-settings — Change own notification preferences
+//   - GET /account/notifications — List notifications sent to oneself
+func AccountNotificationRoute(
+	r chi.Router,
+	notificationHandler *handler.NotificationHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"notification:read-settings"})).
+			Get("/account/notification-settings", notificationHandler.GetSettings)
+		r.With(middleware.PermissionMiddleware([]string{"notification:update-settings"})).
+			Put("/account/notification-settings", notificationHandler.UpdateSettings)
+		r.With(middleware.PermissionMiddleware([]string{"notification:read-log:self"})).
+			Get("/account/notifications", notificationHandler.GetOwnLogs)
+	})
+}
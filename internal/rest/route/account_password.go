@@ -0,0 +1,26 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AccountPasswordRoute mounts the self-service password change endpoint:
+//   - POST /account/change-password — Change own password
+func AccountPasswordRoute(
+	r chi.Router,
+	accountPasswordHandler *handler.AccountPasswordHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"account:change-password:self"})).
+			Post("/account/change-password", accountPasswordHandler.ChangePassword)
+	})
+}
@@ -0,0 +1,26 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AccountPermissionRoute mounts the caller's effective permissions endpoint:
+//   - GET /account/permissions — Own permissions across all tenants
+func AccountPermissionRoute(
+	r chi.Router,
+	accountPermissionHandler *handler.AccountPermissionHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Route("/account/permissions", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"account:user:read:self"})).
+			Get("/", accountPermissionHandler.Get)
+	})
+}
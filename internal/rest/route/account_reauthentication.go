@@ -0,0 +1,26 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AccountReauthenticationRoute mounts the step-up re-authentication endpoint:
+//   - POST /account/reauthenticate — Re-enter own password for step-up
+//     protected actions
+func AccountReauthenticationRoute(
+	r chi.Router,
+	accountReauthenticationHandler *handler.AccountReauthenticationHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.Post("/account/reauthenticate", accountReauthenticationHandler.Reauthenticate)
+	})
+}
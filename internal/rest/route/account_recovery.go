@@ -0,0 +1,54 @@
+package route
+
+import (
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AccountRecoveryRoute mounts the self-service recovery settings endpoints:
+//   - GET    /account/recovery              — Remaining codes and recovery email
+//   - POST   /account/recovery/codes        — Generate a new set of recovery codes
+//   - PUT    /account/recovery/email        — Set the recovery email
+//   - DELETE /account/recovery/email        — Remove the recovery email
+//   - POST   /account/recovery/email/verify — Verify the recovery email
+func AccountRecoveryRoute(
+	r chi.Router,
+	accountRecoveryHandler *handler.AccountRecoveryHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Route("/account/recovery", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"account:recovery:read:self"})).
+			Get("/", accountRecoveryHandler.Get)
+		r.With(middleware.PermissionMiddleware([]string{"account:recovery:update:self"})).
+			Post("/codes", accountRecoveryHandler.GenerateCodes)
+		r.With(middleware.PermissionMiddleware([]string{"account:recovery:update:self"})).
+			Put("/email", accountRecoveryHandler.SetEmail)
+		r.With(middleware.PermissionMiddleware([]string{"account:recovery:update:self"})).
+			Delete("/email", accountRecoveryHandler.RemoveEmail)
+		r.With(middleware.PermissionMiddleware([]string{"account:recovery:update:self"})).
+			Post("/email/verify", accountRecoveryHandler.VerifyEmail)
+	})
+}
+
+// AccountRecoveryPublicRoute mounts the guided account recovery endpoints
+// for users who lost their password or second factor:
+//   - POST /account/recover/start — Email a code to the recovery email
+//   - POST /account/recover       — Set a new password with a recovery code and the emailed code
+func AccountRecoveryPublicRoute(r chi.Router, accountRecoveryHandler *handler.AccountRecoveryHandler) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequestSizeLimitMiddleware(1024 * 1024))
+		r.Use(middleware.TimeoutMiddleware(30 * time.Second))
+
+		r.Post("/account/recover/start", accountRecoveryHandler.StartRecovery)
+		r.Post("/account/recover", accountRecoveryHandler.Recover)
+	})
+}
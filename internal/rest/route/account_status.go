@@ -0,0 +1,38 @@
+package route
+
+import (
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AccountStatusRoute mounts the self-service account disable endpoints:
+//   - POST /account/disable    — Temporarily disable own account
+//   - POST /account/reactivate — Reactivate with the emailed token (public)
+func AccountStatusRoute(
+	r chi.Router,
+	accountStatusHandler *handler.AccountStatusHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"account:user:disable:self"})).
+			Post("/account/disable", accountStatusHandler.Disable)
+	})
+
+	// The account can no longer sign in, so the token alone authorizes
+	// reactivating; limits match the other token-based auth endpoints
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequestSizeLimitMiddleware(1024 * 1024))
+		r.Use(middleware.TimeoutMiddleware(30 * time.Second))
+
+		r.Post("/account/reactivate", accountStatusHandler.Reactivate)
+	})
+}
@@ -0,0 +1,38 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AccountTrustedDeviceRoute mounts the caller's trusted device endpoints:
+//   - GET    /account/trusted-devices                       — Own trusted devices
+//   - POST   /account/trusted-devices                       — Trust the calling device (not with a token)
+//   - DELETE /account/trusted-devices                       — Forget every device
+//   - DELETE /account/trusted-devices/{trusted_device_uuid} — Forget a device
+func AccountTrustedDeviceRoute(
+	r chi.Router,
+	trustedDeviceHandler *handler.TrustedDeviceHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Route("/account/trusted-devices", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"account:device:read:self"})).
+			Get("/", trustedDeviceHandler.List)
+
+		// A token is not a device; only an interactive session may be trusted
+		r.With(middleware.DenyPersonalAccessTokenMiddleware, middleware.PermissionMiddleware([]string{"account:device:trust:self"})).
+			Post("/", trustedDeviceHandler.Trust)
+
+		r.With(middleware.PermissionMiddleware([]string{"account:device:revoke:self"})).
+			Delete("/", trustedDeviceHandler.RevokeAll)
+		r.With(middleware.PermissionMiddleware([]string{"account:device:revoke:self"})).
+			Delete("/{trusted_device_uuid}", trustedDeviceHandler.Revoke)
+	})
+}
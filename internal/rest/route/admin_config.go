@@ -0,0 +1,28 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AdminConfigRoute registers the configuration reload endpoint (internal port
+// 8080 only). The configuration belongs to the instance rather than a tenant,
+// so it requires the system:reload-config permission.
+func AdminConfigRoute(
+	r chi.Router,
+	configHandler *handler.ConfigHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+		r.Use(middleware.PermissionMiddleware([]string{"system:reload-config"}))
+		r.Use(middleware.DenyPersonalAccessTokenMiddleware)
+
+		r.Post("/admin/config/reload", configHandler.Reload)
+	})
+}
@@ -0,0 +1,29 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AdminMigrationRoute registers the schema migration endpoints (internal port
+// 8080 only). The schema is shared by every tenant, so they require the
+// system:run-migrations permission rather than a tenant scope.
+func AdminMigrationRoute(
+	r chi.Router,
+	migrationHandler *handler.MigrationHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+		r.Use(middleware.PermissionMiddleware([]string{"system:run-migrations"}))
+
+		r.Get("/admin/migrations", migrationHandler.GetStatus)
+		r.With(middleware.DenyPersonalAccessTokenMiddleware).
+			Post("/admin/migrations/apply", migrationHandler.Apply)
+	})
+}
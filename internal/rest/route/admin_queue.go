@@ -0,0 +1,27 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AdminQueueRoute registers the background worker and queue health endpoint
+// (internal port 8080 only). It requires the system:metrics permission, like
+// the Prometheus scrape endpoint it complements.
+func AdminQueueRoute(
+	r chi.Router,
+	queueHandler *handler.QueueHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"system:metrics"})).
+			Get("/admin/queues", queueHandler.GetHealth)
+	})
+}
@@ -0,0 +1,32 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/adminui"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AdminUIRoute serves the embedded admin console under /admin (internal port
+// 8080 only). Browsers authenticate with the access_token cookie; every asset
+// requires the system:admin-ui permission.
+func AdminUIRoute(
+	r chi.Router,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Get("/admin", func(w http.ResponseWriter, req *http.Request) {
+		http.Redirect(w, req, "/admin/", http.StatusMovedPermanently)
+	})
+
+	r.Route("/admin/", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"system:admin-ui"})).
+			Handle("/*", http.StripPrefix("/admin", adminui.Handler()))
+	})
+}
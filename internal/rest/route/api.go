@@ -33,6 +33,12 @@ func APIRoute(
 		r.With(middleware.PermissionMiddleware([]string{"api:update"})).
 			Put("/{api_uuid}/status", apiHandler.SetStatus)
 
+		r.With(middleware.PermissionMiddleware([]string{"api:update"})).
+			Put("/{api_uuid}/deprecation", apiHandler.Deprecate)
+
+		r.With(middleware.PermissionMiddleware([]string{"api:update"})).
+			Delete("/{api_uuid}/deprecation", apiHandler.Undeprecate)
+
 		r.With(middleware.PermissionMiddleware([]string{"api:delete"})).
 			Delete("/{api_uuid}", apiHandler.Delete)
 	})
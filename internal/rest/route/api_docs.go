@@ -0,0 +1,28 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// APIDocsRoute serves the OpenAPI document and the Swagger UI explorer
+// (internal port 8080 only). Browsers authenticate with the access_token
+// cookie; both require the system:api-docs permission.
+func APIDocsRoute(
+	r chi.Router,
+	apiDocsHandler *handler.APIDocsHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+		r.Use(middleware.PermissionMiddleware([]string{"system:api-docs"}))
+
+		r.Get("/openapi.json", apiDocsHandler.Spec)
+		r.Get("/docs", apiDocsHandler.SwaggerUI)
+	})
+}
@@ -11,6 +11,7 @@ import (
 func APIKeyRoute(
 	r chi.Router,
 	apiKeyHandler *handler.APIKeyHandler,
+	permissionGroupHandler *handler.PermissionGroupHandler,
 	userService service.UserService,
 	appCache *cache.Cache,
 ) {
@@ -62,6 +63,9 @@ func APIKeyRoute(
 				r.With(middleware.PermissionMiddleware([]string{"api_key:update"})).
 					Delete("/{permission_uuid}", apiKeyHandler.RemoveAPIPermission)
 			})
+
+			r.With(middleware.PermissionMiddleware([]string{"api_key:update"})).
+				Post("/{api_uuid}/permission-groups", permissionGroupHandler.AssignToAPIKeyAPI)
 		})
 	})
 }
@@ -8,10 +8,12 @@ import (
 	"github.com/maintainerd/auth/internal/service"
 )
 
-// AuthEventRoute registers admin endpoints for querying auth events.
+// AuthEventRoute registers admin endpoints for querying auth events and
+// archiving expired ones.
 func AuthEventRoute(
 	r chi.Router,
 	authEventHandler *handler.AuthEventHandler,
+	auditArchiveHandler *handler.AuditArchiveHandler,
 	userService service.UserService,
 	appCache *cache.Cache,
 ) {
@@ -23,6 +25,12 @@ func AuthEventRoute(
 			Get("/", authEventHandler.GetAll)
 		r.With(middleware.PermissionMiddleware([]string{"auth_event:read"})).
 			Get("/count", authEventHandler.CountByType)
+		r.With(middleware.PermissionMiddleware([]string{"auth_event:read"})).
+			Get("/archive-runs", auditArchiveHandler.GetAll)
+		r.With(middleware.PermissionMiddleware([]string{"auth_event:delete"})).
+			Post("/archive-runs", auditArchiveHandler.Run)
+		r.With(middleware.PermissionMiddleware([]string{"auth_event:read"})).
+			Get("/archive-runs/{audit_archive_run_uuid}", auditArchiveHandler.Get)
 		r.With(middleware.PermissionMiddleware([]string{"auth_event:read"})).
 			Get("/{auth_event_uuid}", authEventHandler.Get)
 	})
@@ -0,0 +1,32 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// AuthzRoute registers authorization tooling routes. GET /authz/routes lists
+// the permission requirements of every route mounted on r.
+func AuthzRoute(
+	r chi.Router,
+	authzHandler *handler.AuthzHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	routes := r
+	r.Route("/authz", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		// Simulate access decisions for a user or a set of roles
+		r.With(middleware.PermissionMiddleware([]string{"authz:simulate"})).
+			Post("/simulate", authzHandler.Simulate)
+
+		// Permissions required by each route
+		r.With(middleware.PermissionMiddleware([]string{"authz:simulate"})).
+			Get("/routes", authzHandler.Routes(routes))
+	})
+}
@@ -0,0 +1,30 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// BrowserSessionRoute mounts cookie-based sign-in for first-party web apps on
+// the identity port. BrowserSessionMiddleware must run on the router so that
+// the session authenticates /session/me and every other route:
+//   - POST   /session     — Sign in and start a session (client_id/provider_id required)
+//   - GET    /session/me  — The current session and user
+//   - DELETE /session     — Sign out
+func BrowserSessionRoute(
+	r chi.Router,
+	sessionHandler *handler.BrowserSessionHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Route("/session", func(r chi.Router) {
+		r.Post("/", sessionHandler.Create)
+		r.Delete("/", sessionHandler.Delete)
+
+		r.With(middleware.JWTAuthMiddleware, middleware.UserContextMiddleware(userService, appCache)).
+			Get("/me", sessionHandler.Me)
+	})
+}
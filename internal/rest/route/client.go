@@ -11,6 +11,7 @@ import (
 func ClientRoute(
 	r chi.Router,
 	ClientHandler *handler.ClientHandler,
+	permissionGroupHandler *handler.PermissionGroupHandler,
 	userService service.UserService,
 	appCache *cache.Cache,
 ) {
@@ -27,6 +28,9 @@ func ClientRoute(
 		r.With(middleware.PermissionMiddleware([]string{"client:secret:read"})).
 			Get("/{client_uuid}/secret", ClientHandler.GetSecretByUUID)
 
+		r.With(middleware.PermissionMiddleware([]string{"client:secret:read"})).
+			Post("/{client_uuid}/secret/reveal", ClientHandler.RevealSecret)
+
 		r.With(middleware.PermissionMiddleware([]string{"client:config:read"})).
 			Get("/{client_uuid}/config", ClientHandler.GetConfigByUUID)
 
@@ -39,7 +43,19 @@ func ClientRoute(
 		r.With(middleware.PermissionMiddleware([]string{"client:update"})).
 			Put("/{client_uuid}/status", ClientHandler.SetStatus)
 
-		r.With(middleware.PermissionMiddleware([]string{"client:delete"})).
+		r.With(middleware.PermissionMiddleware([]string{"client:update"})).
+			Put("/{client_uuid}/default", ClientHandler.SetDefault)
+
+		r.With(middleware.PermissionMiddleware([]string{"client:update"})).
+			Put("/{client_uuid}/deprecation", ClientHandler.Deprecate)
+
+		r.With(middleware.PermissionMiddleware([]string{"client:update"})).
+			Delete("/{client_uuid}/deprecation", ClientHandler.Undeprecate)
+
+		r.With(middleware.PermissionMiddleware([]string{"client:update"}), middleware.RequireStepUp).
+			Post("/{client_uuid}/rotate-secret", ClientHandler.RotateSecret)
+
+		r.With(middleware.PermissionMiddleware([]string{"client:delete"}), middleware.RequireStepUp).
 			Delete("/{client_uuid}", ClientHandler.Delete)
 
 		r.With(middleware.PermissionMiddleware([]string{"client:uri:read"})).
@@ -73,5 +89,8 @@ func ClientRoute(
 
 		r.With(middleware.PermissionMiddleware([]string{"client:api:permission:delete"})).
 			Delete("/{client_uuid}/apis/{api_uuid}/permissions/{permission_uuid}", ClientHandler.RemoveAPIPermission)
+
+		r.With(middleware.PermissionMiddleware([]string{"client:api:permission:create"})).
+			Post("/{client_uuid}/apis/{api_uuid}/permission-groups", permissionGroupHandler.AssignToClientAPI)
 	})
 }
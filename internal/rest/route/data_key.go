@@ -0,0 +1,31 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// DataKeyRoute registers the column encryption data key endpoints (internal
+// port 8080 only). Data keys are shared by every tenant, so they require the
+// security:rotate-keys permission rather than a tenant scope. Changes also
+// require a recent re-authentication (step-up).
+func DataKeyRoute(
+	r chi.Router,
+	dataKeyHandler *handler.DataKeyHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+		r.Use(middleware.PermissionMiddleware([]string{"security:rotate-keys"}))
+
+		r.Get("/admin/data-keys", dataKeyHandler.List)
+		r.With(middleware.RequireStepUp).Post("/admin/data-keys/rotate", dataKeyHandler.Rotate)
+		r.With(middleware.RequireStepUp).Post("/admin/data-keys/rewrap", dataKeyHandler.Rewrap)
+		r.With(middleware.RequireStepUp).Post("/admin/data-keys/reencrypt", dataKeyHandler.Reencrypt)
+	})
+}
@@ -0,0 +1,34 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// DebugRoute registers admin endpoints for runtime log levels and body
+// tracing. Changes require a recent re-authentication (step-up).
+func DebugRoute(
+	r chi.Router,
+	debugHandler *handler.DebugHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Route("/debug", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"root:debug-mode"})).
+			Get("/log-levels", debugHandler.GetLogLevels)
+		r.With(middleware.PermissionMiddleware([]string{"root:debug-mode"}), middleware.RequireStepUp).
+			Put("/log-levels/{component}", debugHandler.SetLogLevel)
+		r.With(middleware.PermissionMiddleware([]string{"root:debug-mode"})).
+			Get("/body-traces", debugHandler.GetBodyTraces)
+		r.With(middleware.PermissionMiddleware([]string{"root:debug-mode"}), middleware.RequireStepUp).
+			Post("/body-traces", debugHandler.EnableBodyTrace)
+		r.With(middleware.PermissionMiddleware([]string{"root:debug-mode"}), middleware.RequireStepUp).
+			Delete("/body-traces/{body_trace_uuid}", debugHandler.DisableBodyTrace)
+	})
+}
@@ -0,0 +1,15 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/rest/handler"
+)
+
+// ErrorCodeRoute registers the error code registry on both ports. It needs
+// no authentication: SDKs and login UIs read it to map codes to behaviour.
+func ErrorCodeRoute(r chi.Router, errorCodeHandler *handler.ErrorCodeHandler) {
+	r.Route("/errors", func(r chi.Router) {
+		r.Get("/", errorCodeHandler.List)
+		r.Get("/{code}", errorCodeHandler.Get)
+	})
+}
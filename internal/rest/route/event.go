@@ -0,0 +1,25 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// EventRoute registers the lifecycle event feed endpoint.
+func EventRoute(
+	r chi.Router,
+	eventHandler *handler.EventHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Route("/events", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"event:read"})).
+			Get("/", eventHandler.GetFeed)
+	})
+}
@@ -0,0 +1,46 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// FeatureFlagRoute registers the feature flag endpoints (internal port 8080
+// only). Flags are shared by every tenant, so they require the feature flag
+// permissions rather than a tenant scope.
+func FeatureFlagRoute(
+	r chi.Router,
+	featureFlagHandler *handler.FeatureFlagHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Route("/admin/feature-flags", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"feature-flag:read"})).
+			Get("/", featureFlagHandler.List)
+
+		r.With(middleware.PermissionMiddleware([]string{"feature-flag:read"})).
+			Get("/{feature_flag_uuid}", featureFlagHandler.Get)
+
+		r.With(middleware.PermissionMiddleware([]string{"feature-flag:manage"})).
+			Post("/", featureFlagHandler.Create)
+
+		r.With(middleware.PermissionMiddleware([]string{"feature-flag:manage"})).
+			Put("/{feature_flag_uuid}", featureFlagHandler.Update)
+
+		r.With(middleware.PermissionMiddleware([]string{"feature-flag:manage"})).
+			Delete("/{feature_flag_uuid}", featureFlagHandler.Delete)
+
+		// Per-tenant overrides
+		r.With(middleware.PermissionMiddleware([]string{"feature-flag:manage"})).
+			Put("/{feature_flag_uuid}/tenants/{tenant_uuid}", featureFlagHandler.SetOverride)
+
+		r.With(middleware.PermissionMiddleware([]string{"feature-flag:manage"})).
+			Delete("/{feature_flag_uuid}/tenants/{tenant_uuid}", featureFlagHandler.DeleteOverride)
+	})
+}
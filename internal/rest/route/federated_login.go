@@ -0,0 +1,22 @@
+package route
+
+import (
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+)
+
+// FederatedLoginRoute mounts sign in with an upstream ID token (requires
+// client_id and provider_id):
+//   - POST /login/federated — Exchange an upstream ID token for a session
+func FederatedLoginRoute(r chi.Router, federatedLoginHandler *handler.FederatedLoginHandler) {
+	r.Group(func(r chi.Router) {
+		// Same limits as the password login
+		r.Use(middleware.RequestSizeLimitMiddleware(1024 * 1024))
+		r.Use(middleware.TimeoutMiddleware(30 * time.Second))
+
+		r.Post("/login/federated", federatedLoginHandler.Login)
+	})
+}
@@ -0,0 +1,51 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+func GroupRoute(
+	r chi.Router,
+	groupHandler *handler.GroupHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Route("/groups", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"group:read"})).
+			Get("/", groupHandler.Get)
+
+		r.With(middleware.PermissionMiddleware([]string{"group:read"})).
+			Get("/{group_uuid}", groupHandler.GetByUUID)
+
+		r.With(middleware.PermissionMiddleware([]string{"group:create"})).
+			Post("/", groupHandler.Create)
+
+		r.With(middleware.PermissionMiddleware([]string{"group:update"})).
+			Put("/{group_uuid}", groupHandler.Update)
+
+		r.With(middleware.PermissionMiddleware([]string{"group:delete"})).
+			Delete("/{group_uuid}", groupHandler.Delete)
+
+		r.With(middleware.PermissionMiddleware([]string{"group:read"})).
+			Get("/{group_uuid}/members", groupHandler.GetMembers)
+
+		r.With(middleware.PermissionMiddleware([]string{"group:update"})).
+			Post("/{group_uuid}/members", groupHandler.AddMembers)
+
+		r.With(middleware.PermissionMiddleware([]string{"group:update"})).
+			Delete("/{group_uuid}/members/{user_uuid}", groupHandler.RemoveMember)
+
+		r.With(middleware.PermissionMiddleware([]string{"group:update"})).
+			Post("/{group_uuid}/roles", groupHandler.AddRoles)
+
+		r.With(middleware.PermissionMiddleware([]string{"group:update"})).
+			Delete("/{group_uuid}/roles/{role_uuid}", groupHandler.RemoveRole)
+	})
+}
@@ -0,0 +1,21 @@
+package route
+
+import (
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+)
+
+// HomeRealmRoute mounts home-realm discovery (requires client_id and
+// provider_id):
+//   - POST /login/identify — Identity provider and login method for an email
+func HomeRealmRoute(r chi.Router, homeRealmHandler *handler.HomeRealmHandler) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequestSizeLimitMiddleware(64 * 1024))
+		r.Use(middleware.TimeoutMiddleware(10 * time.Second))
+
+		r.Post("/login/identify", homeRealmHandler.Identify)
+	})
+}
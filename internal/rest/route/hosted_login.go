@@ -0,0 +1,15 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/hostedlogin"
+	"github.com/maintainerd/auth/internal/rest/handler"
+)
+
+// HostedLoginRoute mounts the tenant-branded hosted login page and its static
+// assets on the identity port. The page posts to the public login route, so
+// it must be mounted on the same router as LoginPublicRoute.
+func HostedLoginRoute(r chi.Router, loginTemplateHandler *handler.LoginTemplateHandler) {
+	r.Get("/login", loginTemplateHandler.HostedPage)
+	r.Get("/login/assets/*", hostedlogin.Assets().ServeHTTP)
+}
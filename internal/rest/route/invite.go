@@ -18,6 +18,7 @@ func InviteRoute(
 		r.Use(middleware.JWTAuthMiddleware)
 		r.Use(middleware.UserContextMiddleware(userService, appCache))
 
-		r.Post("/", inviteHandler.Send)
+		r.With(middleware.PermissionMiddleware([]string{"user:invite"})).
+			Post("/", inviteHandler.Send)
 	})
 }
@@ -0,0 +1,46 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// LoginHookRoute registers login hook management routes.
+func LoginHookRoute(
+	r chi.Router,
+	loginHookHandler *handler.LoginHookHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Route("/login-hooks", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		// List login hooks
+		r.With(middleware.PermissionMiddleware([]string{"login-hook:read"})).
+			Get("/", loginHookHandler.GetAll)
+
+		// Get single login hook
+		r.With(middleware.PermissionMiddleware([]string{"login-hook:read"})).
+			Get("/{login_hook_uuid}", loginHookHandler.Get)
+
+		// Create login hook
+		r.With(middleware.PermissionMiddleware([]string{"login-hook:create"})).
+			Post("/", loginHookHandler.Create)
+
+		// Update login hook
+		r.With(middleware.PermissionMiddleware([]string{"login-hook:update"})).
+			Put("/{login_hook_uuid}", loginHookHandler.Update)
+
+		// Delete login hook
+		r.With(middleware.PermissionMiddleware([]string{"login-hook:delete"})).
+			Delete("/{login_hook_uuid}", loginHookHandler.Delete)
+
+		// Update login hook status
+		r.With(middleware.PermissionMiddleware([]string{"login-hook:update"})).
+			Patch("/{login_hook_uuid}/status", loginHookHandler.UpdateStatus)
+	})
+}
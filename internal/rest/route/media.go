@@ -0,0 +1,12 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/rest/handler"
+)
+
+// MediaRoute serves objects of the local storage provider. It needs no
+// authentication: the signed URL in the query is the grant.
+func MediaRoute(r chi.Router, mediaHandler *handler.MediaHandler) {
+	r.Get("/media", mediaHandler.Get)
+}
@@ -0,0 +1,26 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/metrics"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// MetricsRoute registers the Prometheus scrape endpoint (internal port 8080
+// only). Scrapers authenticate with a bearer token for an account holding the
+// system:metrics permission.
+func MetricsRoute(
+	r chi.Router,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Route("/metrics", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"system:metrics"})).
+			Handle("/", metrics.Handler())
+	})
+}
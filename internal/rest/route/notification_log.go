@@ -0,0 +1,26 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// NotificationLogRoute registers admin endpoints for querying notification
+// deliveries.
+func NotificationLogRoute(
+	r chi.Router,
+	notificationHandler *handler.NotificationHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Route("/notification-logs", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"notification:read-log:any"})).
+			Get("/", notificationHandler.GetLogs)
+	})
+}
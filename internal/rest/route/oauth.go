@@ -20,12 +20,14 @@ import (
 //   - GET  /oauth/userinfo           — OpenID Connect UserInfo (JWT required)
 //   - GET  /oauth/consent/grants     — List user consent grants (JWT required)
 //   - DELETE /oauth/consent/grants/{grant_uuid} — Revoke consent grant (JWT required)
+//   - GET/POST /oauth/end_session    — OIDC RP-Initiated Logout (unauthenticated)
 func OAuthPublicRoute(
 	r chi.Router,
 	authorizeHandler *handler.OAuthAuthorizeHandler,
 	tokenHandler *handler.OAuthTokenHandler,
 	consentHandler *handler.OAuthConsentHandler,
 	userInfoHandler *handler.OAuthUserInfoHandler,
+	logoutHandler *handler.OAuthLogoutHandler,
 	userService service.UserService,
 	appCache *cache.Cache,
 ) {
@@ -65,6 +67,10 @@ func OAuthPublicRoute(
 
 		// Token revocation (RFC 7009)
 		r.Post("/revoke", tokenHandler.Revoke)
+
+		// End session endpoint (OIDC RP-Initiated Logout 1.0)
+		r.Get("/end_session", logoutHandler.EndSession)
+		r.Post("/end_session", logoutHandler.EndSession)
 	})
 }
 
@@ -90,6 +96,7 @@ func OAuthInternalRoute(
 	r.Route("/oauth", func(r chi.Router) {
 		r.Use(middleware.JWTAuthMiddleware)
 		r.Use(middleware.UserContextMiddleware(userService, appCache))
+		r.Use(middleware.DenyPersonalAccessTokenMiddleware)
 
 		// Token introspection (RFC 7662) — management-only
 		r.Post("/introspect", tokenHandler.Introspect)
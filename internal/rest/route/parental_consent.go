@@ -0,0 +1,26 @@
+package route
+
+import (
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+)
+
+// ParentalConsentPublicRoute mounts the guardian consent endpoints of
+// under-age accounts. The emailed token alone authorizes them:
+//   - GET  /parental-consent         — Review the consent request
+//   - POST /parental-consent/approve — Activate the account
+//   - POST /parental-consent/deny    — Delete the account
+func ParentalConsentPublicRoute(r chi.Router, parentalConsentHandler *handler.ParentalConsentHandler) {
+	// Limits match the other token-based auth endpoints
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequestSizeLimitMiddleware(1024 * 1024))
+		r.Use(middleware.TimeoutMiddleware(30 * time.Second))
+
+		r.Get("/parental-consent", parentalConsentHandler.Get)
+		r.Post("/parental-consent/approve", parentalConsentHandler.Approve)
+		r.Post("/parental-consent/deny", parentalConsentHandler.Deny)
+	})
+}
@@ -0,0 +1,42 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+func PermissionGroupRoute(
+	r chi.Router,
+	permissionGroupHandler *handler.PermissionGroupHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Route("/permission-groups", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"permission-group:read"})).
+			Get("/", permissionGroupHandler.Get)
+
+		r.With(middleware.PermissionMiddleware([]string{"permission-group:read"})).
+			Get("/{permission_group_uuid}", permissionGroupHandler.GetByUUID)
+
+		r.With(middleware.PermissionMiddleware([]string{"permission-group:create"})).
+			Post("/", permissionGroupHandler.Create)
+
+		r.With(middleware.PermissionMiddleware([]string{"permission-group:update"})).
+			Put("/{permission_group_uuid}", permissionGroupHandler.Update)
+
+		r.With(middleware.PermissionMiddleware([]string{"permission-group:delete"})).
+			Delete("/{permission_group_uuid}", permissionGroupHandler.Delete)
+
+		r.With(middleware.PermissionMiddleware([]string{"permission-group:update"})).
+			Post("/{permission_group_uuid}/permissions", permissionGroupHandler.AddPermissions)
+
+		r.With(middleware.PermissionMiddleware([]string{"permission-group:update"})).
+			Delete("/{permission_group_uuid}/permissions/{permission_uuid}", permissionGroupHandler.RemovePermission)
+	})
+}
@@ -0,0 +1,36 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// PersonalAccessTokenRoute mounts the self-service personal access token
+// endpoints:
+//   - GET    /personal-access-tokens                               — List own tokens
+//   - POST   /personal-access-tokens                               — Create a token (not with a token)
+//   - DELETE /personal-access-tokens/{personal_access_token_uuid}  — Revoke an own token
+func PersonalAccessTokenRoute(
+	r chi.Router,
+	patHandler *handler.PersonalAccessTokenHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Route("/personal-access-tokens", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"account:token:read:self"})).
+			Get("/", patHandler.List)
+
+		// A token must not be able to mint further tokens
+		r.With(middleware.DenyPersonalAccessTokenMiddleware, middleware.PermissionMiddleware([]string{"account:token:create:self"})).
+			Post("/", patHandler.Create)
+
+		r.With(middleware.PermissionMiddleware([]string{"account:token:revoke:self"})).
+			Delete("/{personal_access_token_uuid}", patHandler.Revoke)
+	})
+}
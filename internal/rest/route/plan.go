@@ -0,0 +1,39 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// PlanRoute registers the billing plan endpoints (internal port 8080 only).
+// Plans are shared by every tenant, so they require the plan permissions
+// rather than a tenant scope.
+func PlanRoute(
+	r chi.Router,
+	planHandler *handler.PlanHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Route("/admin/plans", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.PermissionMiddleware([]string{"plan:read"})).
+			Get("/", planHandler.List)
+
+		r.With(middleware.PermissionMiddleware([]string{"plan:read"})).
+			Get("/{plan_uuid}", planHandler.Get)
+
+		r.With(middleware.PermissionMiddleware([]string{"plan:manage"})).
+			Post("/", planHandler.Create)
+
+		r.With(middleware.PermissionMiddleware([]string{"plan:manage"})).
+			Put("/{plan_uuid}", planHandler.Update)
+
+		r.With(middleware.PermissionMiddleware([]string{"plan:manage"})).
+			Delete("/{plan_uuid}", planHandler.Delete)
+	})
+}
@@ -0,0 +1,58 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// PolicyDocumentRoute registers terms of service and privacy policy
+// management routes.
+func PolicyDocumentRoute(
+	r chi.Router,
+	policyDocumentHandler *handler.PolicyDocumentHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Route("/policy-documents", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		// List policy documents
+		r.With(middleware.PermissionMiddleware([]string{"policy-document:read"})).
+			Get("/", policyDocumentHandler.GetAll)
+
+		// Get single policy document
+		r.With(middleware.PermissionMiddleware([]string{"policy-document:read"})).
+			Get("/{policy_document_uuid}", policyDocumentHandler.Get)
+
+		// Create draft policy document
+		r.With(middleware.PermissionMiddleware([]string{"policy-document:create"})).
+			Post("/", policyDocumentHandler.Create)
+
+		// Update draft policy document
+		r.With(middleware.PermissionMiddleware([]string{"policy-document:update"})).
+			Put("/{policy_document_uuid}", policyDocumentHandler.Update)
+
+		// Delete draft policy document
+		r.With(middleware.PermissionMiddleware([]string{"policy-document:delete"})).
+			Delete("/{policy_document_uuid}", policyDocumentHandler.Delete)
+
+		// Publish policy document as the current version
+		r.With(middleware.PermissionMiddleware([]string{"policy-document:publish"})).
+			Post("/{policy_document_uuid}/publish", policyDocumentHandler.Publish)
+
+		// List acceptances of a policy document
+		r.With(middleware.PermissionMiddleware([]string{"policy-document:read"})).
+			Get("/{policy_document_uuid}/acceptances", policyDocumentHandler.GetAcceptances)
+	})
+}
+
+// PolicyDocumentPublicRoute mounts the current policy documents of a client's
+// tenant (requires client_id and provider_id):
+//   - GET /policy-documents/current — Current terms of service and privacy policy
+func PolicyDocumentPublicRoute(r chi.Router, policyDocumentHandler *handler.PolicyDocumentHandler) {
+	r.Get("/policy-documents/current", policyDocumentHandler.GetCurrent)
+}
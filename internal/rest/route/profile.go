@@ -11,6 +11,7 @@ import (
 func ProfileRoute(
 	r chi.Router,
 	profileHandler *handler.ProfileHandler,
+	profilePictureHandler *handler.ProfilePictureHandler,
 	userService service.UserService,
 	appCache *cache.Cache,
 ) {
@@ -34,6 +35,14 @@ func ProfileRoute(
 		// Delete default profile
 		r.With(middleware.PermissionMiddleware([]string{"account:profile:delete:self"})).
 			Delete("/", profileHandler.Delete)
+
+		// Upload or replace the default profile's picture (multipart)
+		r.With(middleware.PermissionMiddleware([]string{"account:profile:update:self"})).
+			Put("/picture", profilePictureHandler.Upload)
+
+		// Delete the default profile's uploaded picture
+		r.With(middleware.PermissionMiddleware([]string{"account:profile:update:self"})).
+			Delete("/picture", profilePictureHandler.Delete)
 	})
 
 	// /profiles - All profiles operations (including default, with full CRUD)
@@ -57,6 +66,14 @@ func ProfileRoute(
 		r.With(middleware.PermissionMiddleware([]string{"account:profile:update:self"})).
 			Put("/{profile_uuid}", profileHandler.UpdateProfile)
 
+		// Upload or replace a specific profile's picture (multipart)
+		r.With(middleware.PermissionMiddleware([]string{"account:profile:update:self"})).
+			Put("/{profile_uuid}/picture", profilePictureHandler.UploadByUUID)
+
+		// Delete a specific profile's uploaded picture
+		r.With(middleware.PermissionMiddleware([]string{"account:profile:update:self"})).
+			Delete("/{profile_uuid}/picture", profilePictureHandler.DeleteByUUID)
+
 		// Set specific profile as default
 		r.With(middleware.PermissionMiddleware([]string{"account:profile:update:self"})).
 			Patch("/{profile_uuid}/set-default", profileHandler.SetDefaultProfile)
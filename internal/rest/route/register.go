@@ -39,6 +39,9 @@ func RegisterPublicRoute(r chi.Router, registerHandler *handler.RegisterHandler)
 		// Public registration (with client_id and provider_id)
 		r.Post("/register", registerHandler.RegisterPublic)
 
+		// Email verification for signup flows that require it
+		r.Post("/register/verify-email", registerHandler.VerifyEmail)
+
 		// Public registration with invite
 		r.Post("/register/invite", registerHandler.RegisterInvitePublic)
 	})
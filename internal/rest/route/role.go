@@ -11,6 +11,7 @@ import (
 func RoleRoute(
 	r chi.Router,
 	roleHandler *handler.RoleHandler,
+	permissionGroupHandler *handler.PermissionGroupHandler,
 	userService service.UserService,
 	appCache *cache.Cache,
 ) {
@@ -33,6 +34,12 @@ func RoleRoute(
 		r.With(middleware.PermissionMiddleware([]string{"role:update"})).
 			Put("/{role_uuid}/status", roleHandler.SetStatus)
 
+		r.With(middleware.PermissionMiddleware([]string{"role:update"})).
+			Put("/{role_uuid}/default", roleHandler.SetDefault)
+
+		r.With(middleware.PermissionMiddleware([]string{"role:update"})).
+			Put("/{role_uuid}/inheritable", roleHandler.SetInheritable)
+
 		r.With(middleware.PermissionMiddleware([]string{"role:delete"})).
 			Delete("/{role_uuid}", roleHandler.Delete)
 
@@ -44,5 +51,8 @@ func RoleRoute(
 
 		r.With(middleware.PermissionMiddleware([]string{"role:permission:delete"})).
 			Delete("/{role_uuid}/permissions/{permission_uuid}", roleHandler.RemovePermission)
+
+		r.With(middleware.PermissionMiddleware([]string{"role:permission:create"})).
+			Post("/{role_uuid}/permission-groups", permissionGroupHandler.AssignToRole)
 	})
 }
@@ -37,6 +37,9 @@ func ServiceRoute(
 			Delete("/{service_uuid}", serviceHandler.Delete)
 
 		// Service-Policy Assignment endpoints
+		r.With(middleware.PermissionMiddleware([]string{"service:read"})).
+			Get("/{service_uuid}/policies", serviceHandler.GetPolicies)
+
 		r.With(middleware.PermissionMiddleware([]string{"service:policy:assign"})).
 			Post("/{service_uuid}/policies/{policy_uuid}", serviceHandler.AssignPolicy)
 
@@ -0,0 +1,30 @@
+package route
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/service"
+)
+
+// SigningKeyRoute registers the JWT signing key endpoints (internal port 8080
+// only). Signing keys are shared by every tenant, so they require the
+// security:rotate-keys permission rather than a tenant scope. Changes also
+// require a recent re-authentication (step-up).
+func SigningKeyRoute(
+	r chi.Router,
+	signingKeyHandler *handler.SigningKeyHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+		r.Use(middleware.PermissionMiddleware([]string{"security:rotate-keys"}))
+
+		r.Get("/admin/signing-keys", signingKeyHandler.List)
+		r.With(middleware.RequireStepUp).Post("/admin/signing-keys/rotate", signingKeyHandler.Rotate)
+		r.With(middleware.RequireStepUp).Post("/admin/signing-keys/{signing_key_uuid}/retire", signingKeyHandler.Retire)
+	})
+}
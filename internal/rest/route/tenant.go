@@ -25,6 +25,11 @@ func TenantPublicRoute(r chi.Router, tenantHandler *handler.TenantHandler) {
 func TenantRoute(
 	r chi.Router,
 	tenantHandler *handler.TenantHandler,
+	onboardingHandler *handler.OnboardingHandler,
+	tenantDeprovisionHandler *handler.TenantDeprovisionHandler,
+	tenantUsageHandler *handler.TenantUsageHandler,
+	planHandler *handler.PlanHandler,
+	tenantService service.TenantService,
 	userService service.UserService,
 	appCache *cache.Cache,
 ) {
@@ -38,7 +43,9 @@ func TenantRoute(
 		r.Get("/{identifier}", tenantHandler.GetByIdentifier)
 	})
 
-	// Multiple tenants endpoints (existing)
+	// Multiple tenants endpoints (existing). Routes under /{tenant_uuid} are
+	// confined to the tenants the caller may access and the tenants below them.
+	tenantScope := middleware.TenantScopeMiddleware(tenantService)
 	r.Route("/tenants", func(r chi.Router) {
 		r.Use(middleware.JWTAuthMiddleware)
 		r.Use(middleware.UserContextMiddleware(userService, appCache))
@@ -46,26 +53,46 @@ func TenantRoute(
 		r.With(middleware.PermissionMiddleware([]string{"tenant:read"})).
 			Get("/", tenantHandler.Get)
 
-		r.With(middleware.PermissionMiddleware([]string{"tenant:read"})).
+		r.With(middleware.PermissionMiddleware([]string{"tenant:read"}), tenantScope).
 			Get("/{tenant_uuid}", tenantHandler.GetByUUID)
 
 		r.With(middleware.PermissionMiddleware([]string{"tenant:create"})).
 			Post("/", tenantHandler.Create)
 
-		r.With(middleware.PermissionMiddleware([]string{"tenant:update"})).
+		r.With(middleware.PermissionMiddleware([]string{"tenant:update"}), tenantScope).
 			Put("/{tenant_uuid}", tenantHandler.Update)
 
-		r.With(middleware.PermissionMiddleware([]string{"tenant:update"})).
+		r.With(middleware.PermissionMiddleware([]string{"tenant:update"}), tenantScope).
 			Put("/{tenant_uuid}/status", tenantHandler.SetStatus)
 
-		r.With(middleware.PermissionMiddleware([]string{"tenant:update"})).
+		r.With(middleware.PermissionMiddleware([]string{"tenant:update"}), tenantScope).
 			Put("/{tenant_uuid}/public", tenantHandler.SetPublic)
 
-		r.With(middleware.PermissionMiddleware([]string{"tenant:delete"})).
+		r.With(middleware.PermissionMiddleware([]string{"tenant:delete"}), tenantScope).
 			Delete("/{tenant_uuid}", tenantHandler.Delete)
 
+		// Tenant hierarchy
+		r.With(middleware.PermissionMiddleware([]string{"tenant:read"}), tenantScope).
+			Get("/{tenant_uuid}/children", tenantHandler.GetChildren)
+
+		r.With(middleware.PermissionMiddleware([]string{"tenant:update"}), tenantScope).
+			Put("/{tenant_uuid}/parent", tenantHandler.SetParent)
+
+		// Metered requests and issued tokens against the monthly quotas
+		r.With(middleware.PermissionMiddleware([]string{"tenant:read"}), tenantScope).
+			Get("/{tenant_uuid}/usage", tenantUsageHandler.GetUsage)
+
+		// Billing plan the tenant is subscribed to
+		r.With(middleware.PermissionMiddleware([]string{"tenant:read"}), tenantScope).
+			Get("/{tenant_uuid}/plan", planHandler.GetTenantPlan)
+
+		r.With(middleware.PermissionMiddleware([]string{"plan:manage"}), tenantScope).
+			Put("/{tenant_uuid}/plan", planHandler.SetTenantPlan)
+
 		// Tenant member management
 		r.Route("/{tenant_uuid}/members", func(r chi.Router) {
+			r.Use(tenantScope)
+
 			// Get all members in tenant
 			r.With(middleware.PermissionMiddleware([]string{"tenant:read"})).
 				Get("/", tenantHandler.GetMembers)
@@ -82,5 +109,49 @@ func TenantRoute(
 			r.With(middleware.PermissionMiddleware([]string{"tenant:update"})).
 				Delete("/{tenant_member_uuid}", tenantHandler.RemoveMember)
 		})
+
+		// Tenant onboarding wizard
+		r.Route("/{tenant_uuid}/onboarding", func(r chi.Router) {
+			r.Use(tenantScope)
+
+			// Get remaining setup steps
+			r.With(middleware.PermissionMiddleware([]string{"tenant:read"})).
+				Get("/", onboardingHandler.GetChecklist)
+
+			// Provision default client, admin, email settings and signup flow
+			r.With(middleware.PermissionMiddleware([]string{"tenant:update"})).
+				Post("/", onboardingHandler.Onboard)
+		})
+
+		// Confirmed tenant archiving and deletion
+		r.Route("/{tenant_uuid}/deprovision", func(r chi.Router) {
+			r.Use(tenantScope)
+
+			// Issue a confirmation token
+			r.With(middleware.PermissionMiddleware([]string{"tenant:delete"})).
+				Post("/", tenantDeprovisionHandler.Request)
+
+			// Archive or delete the tenant with the token
+			r.With(middleware.PermissionMiddleware([]string{"tenant:delete"})).
+				Post("/confirm", tenantDeprovisionHandler.Confirm)
+		})
+	})
+}
+
+// SelfServiceTenantRoute registers the endpoint signed-in users create their
+// own tenants with. It is only mounted when SELF_SERVICE_TENANTS_ENABLED is
+// set and needs no permission beyond a valid session.
+func SelfServiceTenantRoute(
+	r chi.Router,
+	selfServiceTenantHandler *handler.SelfServiceTenantHandler,
+	userService service.UserService,
+	appCache *cache.Cache,
+) {
+	r.Route("/tenants/self-service", func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware)
+		r.Use(middleware.UserContextMiddleware(userService, appCache))
+
+		r.With(middleware.DenyPersonalAccessTokenMiddleware).
+			Post("/", selfServiceTenantHandler.Create)
 	})
 }
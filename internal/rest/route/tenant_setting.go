@@ -42,5 +42,17 @@ func TenantSettingRoute(
 			Get("/feature-flags", tenantSettingHandler.GetFeatureFlags)
 		r.With(middleware.PermissionMiddleware([]string{"tenant-setting:update"})).
 			Put("/feature-flags", tenantSettingHandler.UpdateFeatureFlags)
+
+		// User metadata schema
+		r.With(middleware.PermissionMiddleware([]string{"tenant-setting:read"})).
+			Get("/user-metadata-schema", tenantSettingHandler.GetUserMetadataSchema)
+		r.With(middleware.PermissionMiddleware([]string{"tenant-setting:update"})).
+			Put("/user-metadata-schema", tenantSettingHandler.UpdateUserMetadataSchema)
+
+		// User setting defaults
+		r.With(middleware.PermissionMiddleware([]string{"tenant-setting:read"})).
+			Get("/user-setting-defaults", tenantSettingHandler.GetUserSettingDefaults)
+		r.With(middleware.PermissionMiddleware([]string{"tenant-setting:update"})).
+			Put("/user-setting-defaults", tenantSettingHandler.UpdateUserSettingDefaults)
 	})
 }
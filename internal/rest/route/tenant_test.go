@@ -0,0 +1,43 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubPATValidator resolves every personal access token to pat.
+type stubPATValidator struct {
+	pat *model.PersonalAccessToken
+}
+
+func (s stubPATValidator) ValidatePersonalAccessToken(context.Context, string) (*model.PersonalAccessToken, error) {
+	return s.pat, nil
+}
+
+func TestSelfServiceTenantRoute_DeniesPersonalAccessTokens(t *testing.T) {
+	validator := stubPATValidator{pat: &model.PersonalAccessToken{
+		PersonalAccessTokenUUID: uuid.New(),
+		Scopes:                  []string{"account:profile:read:self"},
+		User:                    &model.User{UserUUID: uuid.New()},
+	}}
+
+	r := chi.NewRouter()
+	r.Use(middleware.PersonalAccessTokenMiddleware(validator))
+	SelfServiceTenantRoute(r, handler.NewSelfServiceTenantHandler(nil), nil, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/tenants/self-service/", nil)
+	req.Header.Set("Authorization", "Bearer "+model.PersonalAccessTokenPrefix+"narrow")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
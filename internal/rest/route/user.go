@@ -11,7 +11,13 @@ import (
 func UserRoute(
 	r chi.Router,
 	userHandler *handler.UserHandler,
+	userImportHandler *handler.UserImportHandler,
 	profileHandler *handler.ProfileHandler,
+	impersonationHandler *handler.ImpersonationHandler,
+	securityHoldHandler *handler.SecurityHoldHandler,
+	scopedRoleHandler *handler.ScopedRoleHandler,
+	userSettingHandler *handler.UserSettingHandler,
+	policyDocumentHandler *handler.PolicyDocumentHandler,
 	userService service.UserService,
 	appCache *cache.Cache,
 ) {
@@ -23,6 +29,26 @@ func UserRoute(
 		r.With(middleware.PermissionMiddleware([]string{"user:read"})).
 			Get("/", userHandler.GetUsers)
 
+		// Fuzzy search users, best match first
+		r.With(middleware.PermissionMiddleware([]string{"user:read"})).
+			Get("/search", userHandler.SearchUsers)
+
+		// Get users changed since a cursor (differential sync)
+		r.With(middleware.PermissionMiddleware([]string{"user:read"})).
+			Get("/delta", userHandler.GetUserDelta)
+
+		// Export users as a CSV or NDJSON download
+		r.With(middleware.PermissionMiddleware([]string{"user:export"})).
+			Get("/export", userHandler.ExportUsers)
+
+		// Import users from a CSV or JSON file
+		r.With(middleware.PermissionMiddleware([]string{"user:import"})).
+			Post("/import", userImportHandler.ImportUsers)
+
+		// Get import progress and report
+		r.With(middleware.PermissionMiddleware([]string{"user:import"})).
+			Get("/import/{import_uuid}", userImportHandler.GetImport)
+
 		// Get user by UUID
 		r.With(middleware.PermissionMiddleware([]string{"user:read"})).
 			Get("/{user_uuid}", userHandler.GetUser)
@@ -51,10 +77,31 @@ func UserRoute(
 		r.With(middleware.PermissionMiddleware([]string{"user:update"})).
 			Patch("/{user_uuid}/complete-account", userHandler.CompleteAccount)
 
+		// Impersonate user (short-lived token, audited)
+		r.With(middleware.PermissionMiddleware([]string{"root:impersonate"}), middleware.RequireStepUp).
+			Post("/{user_uuid}/impersonate", impersonationHandler.Impersonate)
+
 		// Delete user
 		r.With(middleware.PermissionMiddleware([]string{"user:delete"})).
 			Delete("/{user_uuid}", userHandler.DeleteUser)
 
+		// Restore soft-deleted user
+		r.With(middleware.PermissionMiddleware([]string{"user:delete"})).
+			Post("/{user_uuid}/restore", userHandler.RestoreUser)
+
+		// Re-enable a disabled, inactive or suspended user, or clear a
+		// security hold after review
+		r.With(middleware.PermissionMiddleware([]string{"user:enable"})).
+			Post("/{user_uuid}/enable", userHandler.EnableUser)
+
+		// Put a user on security hold (signed out, sign in blocked)
+		r.With(middleware.PermissionMiddleware([]string{"user:disable"})).
+			Post("/{user_uuid}/security-hold", securityHoldHandler.Hold)
+
+		// Permanently erase user
+		r.With(middleware.PermissionMiddleware([]string{"root:hard-delete-user"}), middleware.RequireStepUp).
+			Delete("/{user_uuid}/purge", userHandler.PurgeUser)
+
 		// Role management
 		// Get user roles
 		r.With(middleware.PermissionMiddleware([]string{"user:read"})).
@@ -72,6 +119,23 @@ func UserRoute(
 		r.With(middleware.PermissionMiddleware([]string{"user:create"})).
 			Delete("/{user_uuid}/roles/{role_uuid}", userHandler.RemoveRole)
 
+		// Scoped role management (roles limited to one client or group)
+		// Get user scoped roles
+		r.With(middleware.PermissionMiddleware([]string{"user:read"})).
+			Get("/{user_uuid}/scoped-roles", scopedRoleHandler.List)
+
+		// Assign scoped role to user
+		r.With(middleware.PermissionMiddleware([]string{"user:create"})).
+			Post("/{user_uuid}/scoped-roles", scopedRoleHandler.Assign)
+
+		// Remove scoped role from user
+		r.With(middleware.PermissionMiddleware([]string{"user:create"})).
+			Delete("/{user_uuid}/scoped-roles/{scoped_role_uuid}", scopedRoleHandler.Remove)
+
+		// Get the policy documents the user accepted
+		r.With(middleware.PermissionMiddleware([]string{"policy-document:read"})).
+			Get("/{user_uuid}/policy-acceptances", policyDocumentHandler.GetUserAcceptances)
+
 		// Profile management (admin access to user profiles)
 		// Get all profiles for a user
 		r.With(middleware.PermissionMiddleware([]string{"user:read"})).
@@ -96,5 +160,18 @@ func UserRoute(
 		// Delete specific profile by UUID
 		r.With(middleware.PermissionMiddleware([]string{"user:delete"})).
 			Delete("/{user_uuid}/profiles/{profile_uuid}", profileHandler.AdminDeleteProfile)
+
+		// Settings management (admin access to user settings)
+		// Get user settings with the tenant defaults applied
+		r.With(middleware.PermissionMiddleware([]string{"settings:read:any"})).
+			Get("/{user_uuid}/settings", userSettingHandler.AdminGet)
+
+		// Replace user settings
+		r.With(middleware.PermissionMiddleware([]string{"settings:update:any"})).
+			Put("/{user_uuid}/settings", userSettingHandler.AdminUpdate)
+
+		// Reset user settings to the defaults
+		r.With(middleware.PermissionMiddleware([]string{"settings:reset:any"})).
+			Delete("/{user_uuid}/settings", userSettingHandler.AdminReset)
 	})
 }
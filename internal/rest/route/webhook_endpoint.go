@@ -12,6 +12,7 @@ import (
 func WebhookEndpointRoute(
 	r chi.Router,
 	webhookEndpointHandler *handler.WebhookEndpointHandler,
+	webhookDeliveryHandler *handler.WebhookDeliveryHandler,
 	userService service.UserService,
 	appCache *cache.Cache,
 ) {
@@ -42,5 +43,17 @@ func WebhookEndpointRoute(
 		// Update webhook endpoint status
 		r.With(middleware.PermissionMiddleware([]string{"webhook-endpoint:update"})).
 			Patch("/{webhook_endpoint_uuid}/status", webhookEndpointHandler.UpdateStatus)
+
+		// Rotate webhook endpoint signing secret
+		r.With(middleware.PermissionMiddleware([]string{"webhook-endpoint:update"})).
+			Post("/{webhook_endpoint_uuid}/rotate-secret", webhookEndpointHandler.RotateSecret)
+
+		// List delivery attempts of a webhook endpoint
+		r.With(middleware.PermissionMiddleware([]string{"webhook-endpoint:read"})).
+			Get("/{webhook_endpoint_uuid}/deliveries", webhookDeliveryHandler.GetAll)
+
+		// Get single delivery, including its payload
+		r.With(middleware.PermissionMiddleware([]string{"webhook-endpoint:read"})).
+			Get("/{webhook_endpoint_uuid}/deliveries/{webhook_delivery_uuid}", webhookDeliveryHandler.Get)
 	})
 }
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/maintainerd/auth/internal/app"
+	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/rest/openapi"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/maintainerd/auth/internal/session"
+	"github.com/maintainerd/auth/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The optional route groups are mounted when their service is configured;
+// the routers are only walked, so the services are never called.
+type (
+	stubSigningKeyService struct{ service.SigningKeyService }
+	stubDataKeyService    struct{ service.DataKeyService }
+	stubSessionStore      struct{ session.Store }
+)
+
+// buildRouters builds both routers with every optional route group mounted.
+func buildRouters(t *testing.T) (internal, public *chi.Mux) {
+	t.Helper()
+	adminUI, selfService := config.AdminUIEnabled, config.SelfServiceTenantsEnabled
+	t.Cleanup(func() {
+		config.AdminUIEnabled, config.SelfServiceTenantsEnabled = adminUI, selfService
+	})
+	config.AdminUIEnabled = true
+	config.SelfServiceTenantsEnabled = true
+
+	application := &app.App{
+		SigningKeyService: stubSigningKeyService{},
+		DataKeyService:    stubDataKeyService{},
+		BrowserSessions:   stubSessionStore{},
+		Storage:           &storage.LocalStore{},
+	}
+	h := initHandlers(application)
+	return buildInternalRouter(h, application), buildPublicRouter(h, application)
+}
+
+// TestOpenAPI_InSyncWithRoutes fails when a route is added without an entry
+// in openapi.Operations, or an entry outlives its route. Add or remove the
+// entry to fix it.
+func TestOpenAPI_InSyncWithRoutes(t *testing.T) {
+	internal, public := buildRouters(t)
+
+	assert.Empty(t, openapi.Operations.Undocumented(internal), "internal routes missing from openapi.Operations")
+	assert.Empty(t, openapi.Operations.Undocumented(public), "public routes missing from openapi.Operations")
+	assert.Empty(t, openapi.Operations.Unregistered(internal, public), "openapi.Operations entries without a route")
+}
+
+// TestOpenAPI_PublicMatchesAuth checks the Public flag of every entry against
+// the JWT authentication of its route, so the document's security
+// requirements match the routers.
+func TestOpenAPI_PublicMatchesAuth(t *testing.T) {
+	internal, public := buildRouters(t)
+	jwtAuth := reflect.ValueOf(middleware.JWTAuthMiddleware).Pointer()
+
+	for _, routes := range []chi.Routes{internal, public} {
+		err := chi.Walk(routes, func(method, path string, h http.Handler, mws ...func(http.Handler) http.Handler) error {
+			for {
+				chain, ok := h.(*chi.ChainHandler)
+				if !ok {
+					break
+				}
+				mws = append(mws, chain.Middlewares...)
+				h = chain.Endpoint
+			}
+			authenticated := false
+			for _, mw := range mws {
+				if reflect.ValueOf(mw).Pointer() == jwtAuth {
+					authenticated = true
+				}
+			}
+
+			route, ok := openapi.Operations[method+" "+path]
+			if !ok {
+				route = openapi.Operations["* "+path]
+			}
+			if !route.Hidden {
+				assert.Equal(t, !authenticated, route.Public, "Public flag of %s %s", method, path)
+			}
+			return nil
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestOpenAPI_Document(t *testing.T) {
+	internal, public := buildRouters(t)
+	doc := apiDocument(internal, public)
+
+	assert.Equal(t, openapi.Version, doc.OpenAPI)
+	require.Len(t, doc.Servers, 2)
+
+	ids := map[string]string{}
+	for path, item := range doc.Paths {
+		for _, op := range []*openapi.Operation{item.Get, item.Put, item.Post, item.Delete, item.Patch} {
+			if op == nil {
+				continue
+			}
+			assert.NotEmpty(t, op.Summary, "summary of %s", op.OperationID)
+			if other, dup := ids[op.OperationID]; dup {
+				t.Errorf("operation id %s used by %s and %s", op.OperationID, other, path)
+			}
+			ids[op.OperationID] = path
+		}
+	}
+
+	assert.NotContains(t, doc.Paths, "/admin/*", "hidden routes are not documented")
+	require.Contains(t, doc.Paths, "/api/v1/roles/{role_uuid}")
+	assert.NotNil(t, doc.Paths["/api/v1/roles/{role_uuid}"].Put)
+	assert.Len(t, doc.Paths["/api/v1/roles/{role_uuid}"].Servers, 1, "served on the internal port only")
+	require.Contains(t, doc.Paths, "/health")
+	assert.Empty(t, doc.Paths["/health"].Servers, "served on both ports")
+}
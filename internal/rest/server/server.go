@@ -2,110 +2,216 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
-	"os"
-	"os/signal"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/maintainerd/auth/internal/app"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/metrics"
 	securityMiddleware "github.com/maintainerd/auth/internal/middleware"
 	"github.com/maintainerd/auth/internal/rest/handler"
+	"github.com/maintainerd/auth/internal/rest/openapi"
 	"github.com/maintainerd/auth/internal/rest/route"
+	"github.com/maintainerd/auth/internal/storage"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 // handlers holds every REST handler instance. Created once per server start.
 type handlers struct {
-	service           *handler.ServiceHandler
-	api               *handler.APIHandler
-	permission        *handler.PermissionHandler
-	policy            *handler.PolicyHandler
-	tenant            *handler.TenantHandler
-	identityProvider  *handler.IdentityProviderHandler
-	client            *handler.ClientHandler
-	role              *handler.RoleHandler
-	user              *handler.UserHandler
-	register          *handler.RegisterHandler
-	login             *handler.LoginHandler
-	profile           *handler.ProfileHandler
-	userSetting       *handler.UserSettingHandler
-	invite            *handler.InviteHandler
-	forgotPassword    *handler.ForgotPasswordHandler
-	resetPassword     *handler.ResetPasswordHandler
-	setup             *handler.SetupHandler
-	apiKey            *handler.APIKeyHandler
-	signupFlow        *handler.SignupFlowHandler
-	securitySetting   *handler.SecuritySettingHandler
-	ipRestrictionRule *handler.IPRestrictionRuleHandler
-	emailTemplate     *handler.EmailTemplateHandler
-	smsTemplate       *handler.SMSTemplateHandler
-	loginTemplate     *handler.LoginTemplateHandler
-	branding          *handler.BrandingHandler
-	tenantSetting     *handler.TenantSettingHandler
-	emailConfig       *handler.EmailConfigHandler
-	smsConfig         *handler.SMSConfigHandler
-	webhookEndpoint   *handler.WebhookEndpointHandler
-	authEvent         *handler.AuthEventHandler
-	oauthAuthorize    *handler.OAuthAuthorizeHandler
-	oauthToken        *handler.OAuthTokenHandler
-	oauthConsent      *handler.OAuthConsentHandler
-	oauthDiscovery    *handler.OAuthDiscoveryHandler
-	oauthUserInfo     *handler.OAuthUserInfoHandler
+	service             *handler.ServiceHandler
+	api                 *handler.APIHandler
+	permission          *handler.PermissionHandler
+	permissionGroup     *handler.PermissionGroupHandler
+	policy              *handler.PolicyHandler
+	tenant              *handler.TenantHandler
+	identityProvider    *handler.IdentityProviderHandler
+	client              *handler.ClientHandler
+	role                *handler.RoleHandler
+	group               *handler.GroupHandler
+	scopedRole          *handler.ScopedRoleHandler
+	user                *handler.UserHandler
+	userImport          *handler.UserImportHandler
+	register            *handler.RegisterHandler
+	login               *handler.LoginHandler
+	federatedLogin      *handler.FederatedLoginHandler
+	homeRealm           *handler.HomeRealmHandler
+	browserSession      *handler.BrowserSessionHandler
+	profile             *handler.ProfileHandler
+	profilePicture      *handler.ProfilePictureHandler
+	media               *handler.MediaHandler
+	userSetting         *handler.UserSettingHandler
+	invite              *handler.InviteHandler
+	forgotPassword      *handler.ForgotPasswordHandler
+	resetPassword       *handler.ResetPasswordHandler
+	setup               *handler.SetupHandler
+	apiKey              *handler.APIKeyHandler
+	personalAccessToken *handler.PersonalAccessTokenHandler
+	trustedDevice       *handler.TrustedDeviceHandler
+	accountIdentity     *handler.AccountIdentityHandler
+	accountPermission   *handler.AccountPermissionHandler
+	accountDeletion     *handler.AccountDeletionHandler
+	accountStatus       *handler.AccountStatusHandler
+	accountPassword     *handler.AccountPasswordHandler
+	accountReauth       *handler.AccountReauthenticationHandler
+	signupFlow          *handler.SignupFlowHandler
+	securitySetting     *handler.SecuritySettingHandler
+	ipRestrictionRule   *handler.IPRestrictionRuleHandler
+	emailTemplate       *handler.EmailTemplateHandler
+	smsTemplate         *handler.SMSTemplateHandler
+	loginTemplate       *handler.LoginTemplateHandler
+	branding            *handler.BrandingHandler
+	tenantSetting       *handler.TenantSettingHandler
+	emailConfig         *handler.EmailConfigHandler
+	smsConfig           *handler.SMSConfigHandler
+	webhookEndpoint     *handler.WebhookEndpointHandler
+	webhookDelivery     *handler.WebhookDeliveryHandler
+	loginHook           *handler.LoginHookHandler
+	policyDocument      *handler.PolicyDocumentHandler
+	parentalConsent     *handler.ParentalConsentHandler
+	accountRecovery     *handler.AccountRecoveryHandler
+	authEvent           *handler.AuthEventHandler
+	auditArchive        *handler.AuditArchiveHandler
+	notification        *handler.NotificationHandler
+	event               *handler.EventHandler
+	oauthAuthorize      *handler.OAuthAuthorizeHandler
+	oauthToken          *handler.OAuthTokenHandler
+	oauthConsent        *handler.OAuthConsentHandler
+	oauthDiscovery      *handler.OAuthDiscoveryHandler
+	oauthUserInfo       *handler.OAuthUserInfoHandler
+	oauthLogout         *handler.OAuthLogoutHandler
+	onboarding          *handler.OnboardingHandler
+	selfServiceTenant   *handler.SelfServiceTenantHandler
+	tenantDeprovision   *handler.TenantDeprovisionHandler
+	tenantUsage         *handler.TenantUsageHandler
+	plan                *handler.PlanHandler
+	featureFlag         *handler.FeatureFlagHandler
+	impersonation       *handler.ImpersonationHandler
+	securityHold        *handler.SecurityHoldHandler
+	debug               *handler.DebugHandler
+	queue               *handler.QueueHandler
+	migration           *handler.MigrationHandler
+	config              *handler.ConfigHandler
+	signingKey          *handler.SigningKeyHandler
+	dataKey             *handler.DataKeyHandler
+	errorCode           *handler.ErrorCodeHandler
+	authz               *handler.AuthzHandler
+	apiDocs             *handler.APIDocsHandler
 }
 
 func initHandlers(application *app.App) *handlers {
 	return &handlers{
-		service:           handler.NewServiceHandler(application.ServiceService),
-		api:               handler.NewAPIHandler(application.APIService),
-		permission:        handler.NewPermissionHandler(application.PermissionService),
-		policy:            handler.NewPolicyHandler(application.PolicyService),
-		tenant:            handler.NewTenantHandler(application.TenantService, application.TenantMemberService),
-		identityProvider:  handler.NewIdentityProviderHandler(application.IdentityProviderService),
-		client:            handler.NewClientHandler(application.ClientService),
-		role:              handler.NewRoleHandler(application.RoleService),
-		user:              handler.NewUserHandler(application.UserService),
-		register:          handler.NewRegisterHandler(application.RegisterService),
-		login:             handler.NewLoginHandler(application.LoginService),
-		profile:           handler.NewProfileHandler(application.ProfileService),
-		userSetting:       handler.NewUserSettingHandler(application.UserSettingService),
-		invite:            handler.NewInviteHandler(application.InviteService),
-		forgotPassword:    handler.NewForgotPasswordHandler(application.ForgotPasswordService),
-		resetPassword:     handler.NewResetPasswordHandler(application.ResetPasswordService),
-		setup:             handler.NewSetupHandler(application.SetupService),
-		apiKey:            handler.NewAPIKeyHandler(application.APIKeyService),
-		signupFlow:        handler.NewSignupFlowHandler(application.SignupFlowService),
-		securitySetting:   handler.NewSecuritySettingHandler(application.SecuritySettingService),
-		ipRestrictionRule: handler.NewIPRestrictionRuleHandler(application.IPRestrictionRuleService),
-		emailTemplate:     handler.NewEmailTemplateHandler(application.EmailTemplateService),
-		smsTemplate:       handler.NewSMSTemplateHandler(application.SMSTemplateService),
-		loginTemplate:     handler.NewLoginTemplateHandler(application.LoginTemplateService),
-		branding:          handler.NewBrandingHandler(application.BrandingService),
-		tenantSetting:     handler.NewTenantSettingHandler(application.TenantSettingService),
-		emailConfig:       handler.NewEmailConfigHandler(application.EmailConfigService),
-		smsConfig:         handler.NewSMSConfigHandler(application.SMSConfigService),
-		webhookEndpoint:   handler.NewWebhookEndpointHandler(application.WebhookEndpointService),
-		authEvent:         handler.NewAuthEventHandler(application.AuthEventService),
-		oauthAuthorize:    handler.NewOAuthAuthorizeHandler(application.OAuthAuthorizeService),
-		oauthToken:        handler.NewOAuthTokenHandler(application.OAuthTokenService),
-		oauthConsent:      handler.NewOAuthConsentHandler(application.OAuthConsentService),
-		oauthDiscovery:    handler.NewOAuthDiscoveryHandler(),
-		oauthUserInfo:     handler.NewOAuthUserInfoHandler(),
+		service:             handler.NewServiceHandler(application.ServiceService),
+		api:                 handler.NewAPIHandler(application.APIService),
+		permission:          handler.NewPermissionHandler(application.PermissionService),
+		permissionGroup:     handler.NewPermissionGroupHandler(application.PermissionGroupService, application.RoleService, application.ClientService, application.APIKeyService),
+		policy:              handler.NewPolicyHandler(application.PolicyService),
+		tenant:              handler.NewTenantHandler(application.TenantService, application.TenantMemberService),
+		identityProvider:    handler.NewIdentityProviderHandler(application.IdentityProviderService),
+		client:              handler.NewClientHandler(application.ClientService),
+		role:                handler.NewRoleHandler(application.RoleService),
+		group:               handler.NewGroupHandler(application.GroupService),
+		scopedRole:          handler.NewScopedRoleHandler(application.ScopedRoleService),
+		user:                handler.NewUserHandler(application.UserService),
+		userImport:          handler.NewUserImportHandler(application.UserImportService),
+		register:            handler.NewRegisterHandler(application.RegisterService),
+		login:               handler.NewLoginHandler(application.LoginService),
+		federatedLogin:      handler.NewFederatedLoginHandler(application.FederatedLoginService),
+		homeRealm:           handler.NewHomeRealmHandler(application.HomeRealmService),
+		browserSession:      handler.NewBrowserSessionHandler(application.LoginService, application.OAuthLogoutService, application.BrowserSessions, config.BrowserSessionMaxLifetime),
+		profile:             handler.NewProfileHandler(application.ProfileService),
+		profilePicture:      handler.NewProfilePictureHandler(application.ProfileService, config.ProfilePictureMaxBytes),
+		media:               mediaHandler(application.Storage),
+		userSetting:         handler.NewUserSettingHandler(application.UserSettingService),
+		invite:              handler.NewInviteHandler(application.InviteService),
+		forgotPassword:      handler.NewForgotPasswordHandler(application.ForgotPasswordService),
+		resetPassword:       handler.NewResetPasswordHandler(application.ResetPasswordService),
+		setup:               handler.NewSetupHandler(application.SetupService),
+		personalAccessToken: handler.NewPersonalAccessTokenHandler(application.PersonalAccessTokenService),
+		trustedDevice:       handler.NewTrustedDeviceHandler(application.TrustedDeviceService),
+		accountIdentity:     handler.NewAccountIdentityHandler(application.AccountIdentityService),
+		accountPermission:   handler.NewAccountPermissionHandler(application.PermissionResolver),
+		accountDeletion:     handler.NewAccountDeletionHandler(application.UserService),
+		accountStatus:       handler.NewAccountStatusHandler(application.UserService),
+		accountPassword:     handler.NewAccountPasswordHandler(application.UserService),
+		accountReauth:       handler.NewAccountReauthenticationHandler(application.StepUpService),
+		apiKey:              handler.NewAPIKeyHandler(application.APIKeyService),
+		signupFlow:          handler.NewSignupFlowHandler(application.SignupFlowService),
+		securitySetting:     handler.NewSecuritySettingHandler(application.SecuritySettingService),
+		ipRestrictionRule:   handler.NewIPRestrictionRuleHandler(application.IPRestrictionRuleService),
+		emailTemplate:       handler.NewEmailTemplateHandler(application.EmailTemplateService),
+		smsTemplate:         handler.NewSMSTemplateHandler(application.SMSTemplateService),
+		loginTemplate:       handler.NewLoginTemplateHandler(application.LoginTemplateService, application.HostedSessions),
+		branding:            handler.NewBrandingHandler(application.BrandingService),
+		tenantSetting:       handler.NewTenantSettingHandler(application.TenantSettingService),
+		emailConfig:         handler.NewEmailConfigHandler(application.EmailConfigService),
+		smsConfig:           handler.NewSMSConfigHandler(application.SMSConfigService),
+		webhookEndpoint:     handler.NewWebhookEndpointHandler(application.WebhookEndpointService),
+		webhookDelivery:     handler.NewWebhookDeliveryHandler(application.WebhookDeliveryService),
+		loginHook:           handler.NewLoginHookHandler(application.LoginHookService),
+		policyDocument:      handler.NewPolicyDocumentHandler(application.PolicyDocumentService),
+		parentalConsent:     handler.NewParentalConsentHandler(application.ParentalConsentService),
+		accountRecovery:     handler.NewAccountRecoveryHandler(application.AccountRecoveryService),
+		authEvent:           handler.NewAuthEventHandler(application.AuthEventService),
+		auditArchive:        handler.NewAuditArchiveHandler(application.AuditArchiveService),
+		notification:        handler.NewNotificationHandler(application.NotificationService),
+		event:               handler.NewEventHandler(application.EventService),
+		oauthAuthorize:      handler.NewOAuthAuthorizeHandler(application.OAuthAuthorizeService),
+		oauthToken:          handler.NewOAuthTokenHandler(application.OAuthTokenService),
+		oauthConsent:        handler.NewOAuthConsentHandler(application.OAuthConsentService),
+		oauthDiscovery:      handler.NewOAuthDiscoveryHandler(),
+		oauthUserInfo:       handler.NewOAuthUserInfoHandler(),
+		oauthLogout:         handler.NewOAuthLogoutHandler(application.OAuthLogoutService, application.BrowserSessions),
+		onboarding:          handler.NewOnboardingHandler(application.OnboardingService),
+		selfServiceTenant:   handler.NewSelfServiceTenantHandler(application.SelfServiceTenantService),
+		tenantDeprovision:   handler.NewTenantDeprovisionHandler(application.TenantProvisioner, application.TenantMemberService),
+		tenantUsage:         handler.NewTenantUsageHandler(application.UsageService),
+		plan:                handler.NewPlanHandler(application.PlanService),
+		featureFlag:         handler.NewFeatureFlagHandler(application.FeatureFlagService),
+		impersonation:       handler.NewImpersonationHandler(application.ImpersonationService),
+		securityHold:        handler.NewSecurityHoldHandler(application.SecurityHoldService),
+		debug:               handler.NewDebugHandler(application.DebugService),
+		queue:               handler.NewQueueHandler(application.QueueHealthService),
+		migration:           handler.NewMigrationHandler(application.MigrationService),
+		config:              handler.NewConfigHandler(application.ConfigService),
+		signingKey:          handler.NewSigningKeyHandler(application.SigningKeyService),
+		dataKey:             handler.NewDataKeyHandler(application.DataKeyService),
+		errorCode:           handler.NewErrorCodeHandler(),
+		authz:               handler.NewAuthzHandler(application.AuthzSimulationService),
+		apiDocs:             handler.NewAPIDocsHandler(config.APIDocsSwaggerUIURL),
 	}
 }
 
-// StartRESTServer launches the internal and public HTTP servers, blocks until
-// a termination signal is received, then drains connections gracefully.
-func StartRESTServer(application *app.App) {
+// mediaHandler serves the objects of the local storage provider; the other
+// providers sign URLs to their bucket, so it is nil for them.
+func mediaHandler(store storage.Store) *handler.MediaHandler {
+	if local, ok := store.(*storage.LocalStore); ok {
+		return handler.NewMediaHandler(local)
+	}
+	return nil
+}
+
+// StartRESTServer launches the internal and public HTTP servers and blocks
+// until ctx is cancelled (typically by SIGINT/SIGTERM) or either listener
+// fails. Both servers are then drained, waiting at most shutdownTimeout for
+// in-flight requests to finish. The returned error is nil on a clean stop.
+func StartRESTServer(ctx context.Context, application *app.App, shutdownTimeout time.Duration) error {
 	h := initHandlers(application)
+	internalRouter := buildInternalRouter(h, application)
+	publicRouter := buildPublicRouter(h, application)
+	if err := h.apiDocs.SetDocument(apiDocument(internalRouter, publicRouter)); err != nil {
+		return err
+	}
 
 	internalSrv := &http.Server{
 		Addr:         ":8080",
-		Handler:      otelhttp.NewHandler(buildInternalRouter(h, application), "internal"),
+		Handler:      otelhttp.NewHandler(internalRouter, "internal"),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 60 * time.Second,
 		IdleTimeout:  120 * time.Second,
@@ -113,66 +219,81 @@ func StartRESTServer(application *app.App) {
 
 	publicSrv := &http.Server{
 		Addr:         ":8081",
-		Handler:      otelhttp.NewHandler(buildPublicRouter(h, application), "public"),
+		Handler:      otelhttp.NewHandler(publicRouter, "public"),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 60 * time.Second,
 		IdleTimeout:  120 * time.Second,
 	}
 
-	// Start both servers in background goroutines
+	// Start both servers in background goroutines; listener failures are
+	// reported on errCh so the other server can be drained before returning.
 	var wg sync.WaitGroup
+	errCh := make(chan error, 2)
 	wg.Add(2)
 
 	go func() {
 		defer wg.Done()
 		slog.Info("Internal REST server starting", "addr", internalSrv.Addr)
-		if err := internalSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			slog.Error("Internal REST server error", "error", err)
-			os.Exit(1)
+		if err := internalSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- fmt.Errorf("internal REST server: %w", err)
 		}
 	}()
 
 	go func() {
 		defer wg.Done()
 		slog.Info("Public REST server starting", "addr", publicSrv.Addr)
-		if err := publicSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			slog.Error("Public REST server error", "error", err)
-			os.Exit(1)
+		if err := publicSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- fmt.Errorf("public REST server: %w", err)
 		}
 	}()
 
-	// Block until OS signal received
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
-	<-quit
-
-	slog.Info("Shutdown signal received, draining connections...")
+	// Block until shutdown is requested or a server fails
+	var serveErr error
+	select {
+	case <-ctx.Done():
+		slog.Info("Shutdown signal received, draining connections...", "timeout", shutdownTimeout.String())
+	case serveErr = <-errCh:
+		slog.Error("REST server error, shutting down", "error", serveErr)
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
-	var shutdownErr error
-	if err := internalSrv.Shutdown(ctx); err != nil {
-		shutdownErr = err
+	errs := []error{serveErr}
+	if err := internalSrv.Shutdown(shutdownCtx); err != nil {
 		slog.Error("Internal server shutdown error", "error", err)
+		errs = append(errs, fmt.Errorf("internal REST server shutdown: %w", err))
 	}
-	if err := publicSrv.Shutdown(ctx); err != nil {
-		shutdownErr = err
+	if err := publicSrv.Shutdown(shutdownCtx); err != nil {
 		slog.Error("Public server shutdown error", "error", err)
+		errs = append(errs, fmt.Errorf("public REST server shutdown: %w", err))
 	}
 
 	wg.Wait()
 
-	if shutdownErr != nil {
-		os.Exit(1)
+	if err := errors.Join(errs...); err != nil {
+		return err
 	}
-	slog.Info("Servers stopped cleanly")
+	slog.Info("REST servers stopped cleanly")
+	return nil
+}
+
+// apiDocument builds the OpenAPI document of the internal and public routers.
+func apiDocument(internal, public chi.Routes) *openapi.Document {
+	return openapi.Operations.Build(
+		openapi.Info{Title: "Maintainerd Auth API", Version: config.AppVersion},
+		openapi.Port{Server: openapi.Server{URL: config.AppPrivateHostname, Description: "Internal (management) port 8080"}, Routes: internal},
+		openapi.Port{Server: openapi.Server{URL: config.AppPublicHostname, Description: "Public port 8081"}, Routes: public},
+	)
 }
 
 // buildInternalRouter constructs the chi router for the internal API (port 8080, VPN access only).
-func buildInternalRouter(h *handlers, application *app.App) http.Handler {
+func buildInternalRouter(h *handlers, application *app.App) *chi.Mux {
 	r := chi.NewRouter()
 
+	// Prometheus request metrics — outermost so recovered panics are counted
+	r.Use(metrics.HTTPMiddleware("internal"))
+
 	// Built-in Chi middlewares
 	r.Use(middleware.Recoverer)
 
@@ -184,6 +305,24 @@ func buildInternalRouter(h *handlers, application *app.App) http.Handler {
 	// so that request_id is available for log correlation.
 	r.Use(securityMiddleware.LoggingMiddleware)
 
+	// Temporary body tracing enabled through the debug endpoints
+	r.Use(securityMiddleware.BodyTraceMiddleware)
+
+	// Sampled authorization decision auditing for PermissionMiddleware
+	r.Use(securityMiddleware.AuthzAuditMiddleware(application.AuthzAuditService))
+
+	// Recent re-authentication checks for RequireStepUp
+	r.Use(securityMiddleware.StepUpVerifierMiddleware(application.StepUpService))
+
+	// Cached role permissions for PermissionMiddleware
+	r.Use(securityMiddleware.PermissionResolverMiddleware(application.PermissionResolver))
+
+	// Attribute-based deny statements of the tenant's policies
+	r.Use(securityMiddleware.PolicyMiddleware(application.PolicyEnforcementService))
+
+	// Monthly request and token quotas of the tenant
+	r.Use(securityMiddleware.UsageMeterMiddleware(application.UsageService))
+
 	// Global DoS protection with reasonable limits
 	r.Use(securityMiddleware.RequestSizeLimitMiddleware(10 * 1024 * 1024)) // 10MB global limit
 	r.Use(securityMiddleware.TimeoutMiddleware(60 * time.Second))          // 60s global timeout
@@ -192,52 +331,147 @@ func buildInternalRouter(h *handlers, application *app.App) http.Handler {
 	r.Get("/health", handleHealth)
 	r.Get("/ready", handleReady(application))
 
+	// Operational endpoints are rate limited as management requests
+	r.Group(func(r chi.Router) {
+		r.Use(rateLimit(application, securityMiddleware.RateLimitGroupManagement))
+
+		// Prometheus scrape endpoint (requires system:metrics)
+		route.MetricsRoute(r, application.UserService, application.Cache)
+
+		// Background worker and queue health (requires system:metrics)
+		route.AdminQueueRoute(r, h.queue, application.UserService, application.Cache)
+
+		// Schema migrations (requires system:run-migrations)
+		route.AdminMigrationRoute(r, h.migration, application.UserService, application.Cache)
+
+		// Billing plans (requires plan:read / plan:manage)
+		route.PlanRoute(r, h.plan, application.UserService, application.Cache)
+
+		// Feature flags (requires feature-flag:read / feature-flag:manage)
+		route.FeatureFlagRoute(r, h.featureFlag, application.UserService, application.Cache)
+
+		// Runtime configuration reload (requires system:reload-config)
+		route.AdminConfigRoute(r, h.config, application.UserService, application.Cache)
+
+		// JWT signing key rotation (opt-in, requires security:rotate-keys)
+		if application.SigningKeyService != nil {
+			route.SigningKeyRoute(r, h.signingKey, application.UserService, application.Cache)
+		}
+		if application.DataKeyService != nil {
+			route.DataKeyRoute(r, h.dataKey, application.UserService, application.Cache)
+		}
+
+		// OpenAPI document and Swagger UI (requires system:api-docs)
+		route.APIDocsRoute(r, h.apiDocs, application.UserService, application.Cache)
+
+		// Embedded admin console (opt-in, requires system:admin-ui)
+		if config.AdminUIEnabled {
+			route.AdminUIRoute(r, application.UserService, application.Cache)
+		}
+	})
+
 	r.Route("/api/v1", func(api chi.Router) {
-		// Setup Routes (no authentication required)
-		route.SetupRoute(api, h.setup)
-
-		// Internal Authentication Routes (no client_id/provider_id required)
-		route.RegisterRoute(api, h.register)
-		route.LoginRoute(api, h.login)
-		route.ForgotPasswordRoute(api, h.forgotPassword)
-		route.ResetPasswordRoute(api, h.resetPassword)
-		route.ProfileRoute(api, h.profile, application.UserService, application.Cache)
-		route.UserSettingRoute(api, h.userSetting, application.UserService, application.Cache)
-
-		// Management Routes (internal access only)
-		route.TenantRoute(api, h.tenant, application.UserService, application.Cache)
-		route.ServiceRoute(api, h.service, application.UserService, application.Cache)
-		route.APIRoute(api, h.api, application.UserService, application.Cache)
-		route.PermissionRoute(api, h.permission, application.UserService, application.Cache)
-		route.PolicyRoute(api, h.policy, application.UserService, application.Cache)
-		route.IdentityProviderRoute(api, h.identityProvider, application.UserService, application.Cache)
-		route.ClientRoute(api, h.client, application.UserService, application.Cache)
-		route.RoleRoute(api, h.role, application.UserService, application.Cache)
-		route.UserRoute(api, h.user, h.profile, application.UserService, application.Cache)
-		route.InviteRoute(api, h.invite, application.UserService, application.Cache)
-		route.APIKeyRoute(api, h.apiKey, application.UserService, application.Cache)
-		route.SignupFlowRoute(api, h.signupFlow, application.UserService, application.Cache)
-		route.SecuritySettingRoute(api, h.securitySetting, application.UserService, application.Cache)
-		route.IPRestrictionRuleRoute(api, h.ipRestrictionRule, application.UserService, application.Cache)
-		route.EmailTemplateRoute(api, h.emailTemplate, application.UserService, application.Cache)
-		route.SMSTemplateRoute(api, h.smsTemplate, application.UserService, application.Cache)
-		route.LoginTemplateRoute(api, h.loginTemplate, application.UserService, application.Cache)
-		route.BrandingRoute(api, h.branding, application.UserService, application.Cache)
-		route.TenantSettingRoute(api, h.tenantSetting, application.UserService, application.Cache)
-		route.EmailConfigRoute(api, h.emailConfig, application.UserService, application.Cache)
-		route.SMSConfigRoute(api, h.smsConfig, application.UserService, application.Cache)
-		route.WebhookEndpointRoute(api, h.webhookEndpoint, application.UserService, application.Cache)
-		route.AuthEventRoute(api, h.authEvent, application.UserService, application.Cache)
-		route.OAuthInternalRoute(api, h.oauthToken, application.UserService, application.Cache)
+		// Personal access tokens and API keys stand in for a JWT on every
+		// route below
+		api.Use(securityMiddleware.PersonalAccessTokenMiddleware(application.PersonalAccessTokenService))
+		api.Use(securityMiddleware.APIKeyAuthMiddleware(application.APIKeyService, application.Cache))
+
+		api.Group(func(api chi.Router) {
+			api.Use(rateLimit(application, securityMiddleware.RateLimitGroupDefault))
+
+			// Error code registry (no authentication required)
+			route.ErrorCodeRoute(api, h.errorCode)
+		})
+
+		api.Group(func(api chi.Router) {
+			api.Use(rateLimit(application, securityMiddleware.RateLimitGroupAuth))
+
+			// Setup Routes (no authentication required)
+			route.SetupRoute(api, h.setup)
+
+			// Internal Authentication Routes (no client_id/provider_id required)
+			route.RegisterRoute(api, h.register)
+			route.LoginRoute(api, h.login)
+			route.ForgotPasswordRoute(api, h.forgotPassword)
+			route.ResetPasswordRoute(api, h.resetPassword)
+		})
+
+		api.Group(func(api chi.Router) {
+			api.Use(rateLimit(application, securityMiddleware.RateLimitGroupAccount))
+
+			route.ProfileRoute(api, h.profile, h.profilePicture, application.UserService, application.Cache)
+			route.UserSettingRoute(api, h.userSetting, application.UserService, application.Cache)
+			route.PersonalAccessTokenRoute(api, h.personalAccessToken, application.UserService, application.Cache)
+			route.AccountPermissionRoute(api, h.accountPermission, application.UserService, application.Cache)
+			route.AccountConsentRoute(api, h.oauthConsent, application.UserService, application.Cache)
+			route.AccountTrustedDeviceRoute(api, h.trustedDevice, application.UserService, application.Cache)
+			route.AccountIdentityRoute(api, h.accountIdentity, application.UserService, application.Cache)
+			route.AccountDeletionRoute(api, h.accountDeletion, application.UserService, application.Cache)
+			route.AccountStatusRoute(api, h.accountStatus, application.UserService, application.Cache)
+			route.AccountPasswordRoute(api, h.accountPassword, application.UserService, application.Cache)
+			route.AccountRecoveryRoute(api, h.accountRecovery, application.UserService, application.Cache)
+			route.AccountReauthenticationRoute(api, h.accountReauth, application.UserService, application.Cache)
+			route.AccountNotificationRoute(api, h.notification, application.UserService, application.Cache)
+		})
+
+		api.Group(func(api chi.Router) {
+			api.Use(rateLimit(application, securityMiddleware.RateLimitGroupOAuth))
+
+			route.OAuthInternalRoute(api, h.oauthToken, application.UserService, application.Cache)
+		})
+
+		api.Group(func(api chi.Router) {
+			api.Use(rateLimit(application, securityMiddleware.RateLimitGroupManagement))
+			api.Use(securityMiddleware.IfMatchMiddleware)
+
+			// Management Routes (internal access only)
+			route.TenantRoute(api, h.tenant, h.onboarding, h.tenantDeprovision, h.tenantUsage, h.plan, application.TenantService, application.UserService, application.Cache)
+			if config.SelfServiceTenantsEnabled {
+				route.SelfServiceTenantRoute(api, h.selfServiceTenant, application.UserService, application.Cache)
+			}
+			route.ServiceRoute(api, h.service, application.UserService, application.Cache)
+			route.APIRoute(api, h.api, application.UserService, application.Cache)
+			route.PermissionRoute(api, h.permission, application.UserService, application.Cache)
+			route.PermissionGroupRoute(api, h.permissionGroup, application.UserService, application.Cache)
+			route.PolicyRoute(api, h.policy, application.UserService, application.Cache)
+			route.IdentityProviderRoute(api, h.identityProvider, application.UserService, application.Cache)
+			route.ClientRoute(api, h.client, h.permissionGroup, application.UserService, application.Cache)
+			route.RoleRoute(api, h.role, h.permissionGroup, application.UserService, application.Cache)
+			route.GroupRoute(api, h.group, application.UserService, application.Cache)
+			route.UserRoute(api, h.user, h.userImport, h.profile, h.impersonation, h.securityHold, h.scopedRole, h.userSetting, h.policyDocument, application.UserService, application.Cache)
+			route.InviteRoute(api, h.invite, application.UserService, application.Cache)
+			route.APIKeyRoute(api, h.apiKey, h.permissionGroup, application.UserService, application.Cache)
+			route.SignupFlowRoute(api, h.signupFlow, application.UserService, application.Cache)
+			route.SecuritySettingRoute(api, h.securitySetting, application.UserService, application.Cache)
+			route.IPRestrictionRuleRoute(api, h.ipRestrictionRule, application.UserService, application.Cache)
+			route.EmailTemplateRoute(api, h.emailTemplate, application.UserService, application.Cache)
+			route.SMSTemplateRoute(api, h.smsTemplate, application.UserService, application.Cache)
+			route.LoginTemplateRoute(api, h.loginTemplate, application.UserService, application.Cache)
+			route.BrandingRoute(api, h.branding, application.UserService, application.Cache)
+			route.TenantSettingRoute(api, h.tenantSetting, application.UserService, application.Cache)
+			route.EmailConfigRoute(api, h.emailConfig, application.UserService, application.Cache)
+			route.SMSConfigRoute(api, h.smsConfig, application.UserService, application.Cache)
+			route.WebhookEndpointRoute(api, h.webhookEndpoint, h.webhookDelivery, application.UserService, application.Cache)
+			route.LoginHookRoute(api, h.loginHook, application.UserService, application.Cache)
+			route.PolicyDocumentRoute(api, h.policyDocument, application.UserService, application.Cache)
+			route.AuthEventRoute(api, h.authEvent, h.auditArchive, application.UserService, application.Cache)
+			route.NotificationLogRoute(api, h.notification, application.UserService, application.Cache)
+			route.EventRoute(api, h.event, application.UserService, application.Cache)
+			route.DebugRoute(api, h.debug, application.UserService, application.Cache)
+			route.AuthzRoute(api, h.authz, application.UserService, application.Cache)
+		})
 	})
 
 	return r
 }
 
 // buildPublicRouter constructs the chi router for the public API (port 8081, public internet).
-func buildPublicRouter(h *handlers, application *app.App) http.Handler {
+func buildPublicRouter(h *handlers, application *app.App) *chi.Mux {
 	r := chi.NewRouter()
 
+	// Prometheus request metrics — outermost so recovered panics are counted
+	r.Use(metrics.HTTPMiddleware("public"))
+
 	// Built-in Chi middlewares
 	r.Use(middleware.Recoverer)
 
@@ -249,6 +483,21 @@ func buildPublicRouter(h *handlers, application *app.App) http.Handler {
 	// so that request_id is available for log correlation.
 	r.Use(securityMiddleware.LoggingMiddleware)
 
+	// Temporary body tracing enabled through the debug endpoints
+	r.Use(securityMiddleware.BodyTraceMiddleware)
+
+	// Sampled authorization decision auditing for PermissionMiddleware
+	r.Use(securityMiddleware.AuthzAuditMiddleware(application.AuthzAuditService))
+
+	// Cached role permissions for PermissionMiddleware
+	r.Use(securityMiddleware.PermissionResolverMiddleware(application.PermissionResolver))
+
+	// Attribute-based deny statements of the tenant's policies
+	r.Use(securityMiddleware.PolicyMiddleware(application.PolicyEnforcementService))
+
+	// Monthly request and token quotas of the tenant
+	r.Use(securityMiddleware.UsageMeterMiddleware(application.UsageService))
+
 	// Global DoS protection with reasonable limits
 	r.Use(securityMiddleware.RequestSizeLimitMiddleware(10 * 1024 * 1024)) // 10MB global limit
 	r.Use(securityMiddleware.TimeoutMiddleware(60 * time.Second))          // 60s global timeout
@@ -258,27 +507,102 @@ func buildPublicRouter(h *handlers, application *app.App) http.Handler {
 	r.Get("/ready", handleReady(application))
 
 	// OpenID Connect discovery endpoints (root-level, fully public)
-	route.OAuthDiscoveryRoute(r, h.oauthDiscovery)
+	r.Group(func(r chi.Router) {
+		r.Use(rateLimit(application, securityMiddleware.RateLimitGroupOAuth))
+
+		route.OAuthDiscoveryRoute(r, h.oauthDiscovery)
+	})
 
 	r.Route("/api/v1", func(api chi.Router) {
-		// Public Tenant Routes (no authentication required - for login page)
-		// Only exposes GET /tenant/ and GET /tenant/{identifier} — management endpoints
-		// are intentionally absent from the public surface.
-		route.TenantPublicRoute(api, h.tenant)
-
-		// Public Authentication Routes (requires client_id/provider_id)
-		route.RegisterPublicRoute(api, h.register)
-		route.LoginPublicRoute(api, h.login)
-		route.ForgotPasswordPublicRoute(api, h.forgotPassword)
-		route.ResetPasswordPublicRoute(api, h.resetPassword)
-		route.ProfileRoute(api, h.profile, application.UserService, application.Cache)
-		route.UserSettingRoute(api, h.userSetting, application.UserService, application.Cache)
-		route.OAuthPublicRoute(api, h.oauthAuthorize, h.oauthToken, h.oauthConsent, h.oauthUserInfo, application.UserService, application.Cache)
+		// Browser session cookies of first-party web apps stand in for a JWT
+		// on every route below
+		api.Use(securityMiddleware.BrowserSessionMiddleware(application.BrowserSessions))
+
+		api.Group(func(api chi.Router) {
+			api.Use(rateLimit(application, securityMiddleware.RateLimitGroupDefault))
+
+			// Error code registry (no authentication required)
+			route.ErrorCodeRoute(api, h.errorCode)
+
+			// Public Tenant Routes (no authentication required - for login page)
+			// Only exposes GET /tenant/ and GET /tenant/{identifier} — management endpoints
+			// are intentionally absent from the public surface.
+			route.TenantPublicRoute(api, h.tenant)
+
+			// Current policy documents of a client's tenant (for login and
+			// sign-up pages)
+			route.PolicyDocumentPublicRoute(api, h.policyDocument)
+
+			// Signed links to objects of the local storage provider
+			if h.media != nil {
+				route.MediaRoute(api, h.media)
+			}
+		})
+
+		api.Group(func(api chi.Router) {
+			api.Use(rateLimit(application, securityMiddleware.RateLimitGroupAuth))
+			// Hosted pages keep a CSRF token in the hosted page session
+			api.Use(securityMiddleware.CSRFMiddleware(application.HostedSessions))
+
+			// Public Authentication Routes (requires client_id/provider_id)
+			route.RegisterPublicRoute(api, h.register)
+			route.LoginPublicRoute(api, h.login)
+			route.FederatedLoginRoute(api, h.federatedLogin)
+			route.HomeRealmRoute(api, h.homeRealm)
+			route.HostedLoginRoute(api, h.loginTemplate)
+			route.ForgotPasswordPublicRoute(api, h.forgotPassword)
+			route.ResetPasswordPublicRoute(api, h.resetPassword)
+			route.AccountRecoveryPublicRoute(api, h.accountRecovery)
+		})
+
+		// Cookie-based sign-in (opt-in). The session checks its own CSRF
+		// token, so the hosted page CSRF middleware is not applied.
+		if application.BrowserSessions != nil {
+			api.Group(func(api chi.Router) {
+				api.Use(rateLimit(application, securityMiddleware.RateLimitGroupAuth))
+
+				route.BrowserSessionRoute(api, h.browserSession, application.UserService, application.Cache)
+			})
+		}
+
+		api.Group(func(api chi.Router) {
+			api.Use(rateLimit(application, securityMiddleware.RateLimitGroupAccount))
+
+			route.ProfileRoute(api, h.profile, h.profilePicture, application.UserService, application.Cache)
+			route.UserSettingRoute(api, h.userSetting, application.UserService, application.Cache)
+			route.PersonalAccessTokenRoute(api, h.personalAccessToken, application.UserService, application.Cache)
+			route.AccountPermissionRoute(api, h.accountPermission, application.UserService, application.Cache)
+			route.AccountConsentRoute(api, h.oauthConsent, application.UserService, application.Cache)
+			route.AccountTrustedDeviceRoute(api, h.trustedDevice, application.UserService, application.Cache)
+			route.AccountIdentityRoute(api, h.accountIdentity, application.UserService, application.Cache)
+			route.AccountDeletionRoute(api, h.accountDeletion, application.UserService, application.Cache)
+			route.AccountStatusRoute(api, h.accountStatus, application.UserService, application.Cache)
+			route.ParentalConsentPublicRoute(api, h.parentalConsent)
+			route.AccountPasswordRoute(api, h.accountPassword, application.UserService, application.Cache)
+			route.AccountRecoveryRoute(api, h.accountRecovery, application.UserService, application.Cache)
+			route.AccountNotificationRoute(api, h.notification, application.UserService, application.Cache)
+		})
+
+		api.Group(func(api chi.Router) {
+			api.Use(rateLimit(application, securityMiddleware.RateLimitGroupOAuth))
+			api.Use(securityMiddleware.CSRFMiddleware(application.HostedSessions))
+
+			route.OAuthPublicRoute(api, h.oauthAuthorize, h.oauthToken, h.oauthConsent, h.oauthUserInfo, h.oauthLogout, application.UserService, application.Cache)
+		})
 	})
 
 	return r
 }
 
+// rateLimit returns the rate limiting middleware of the route group, which
+// passes every request when rate limiting is disabled. Policies are read per
+// request so that a config reload applies to them.
+func rateLimit(application *app.App, group string) func(http.Handler) http.Handler {
+	return securityMiddleware.RateLimitMiddleware(application.Cache, group, func() map[string]cache.RateLimit {
+		return config.Current().RateLimit.Policies
+	})
+}
+
 // handleHealth responds to liveness probes. Always returns 200 OK when the
 // process is running — no dependency checks.
 func handleHealth(w http.ResponseWriter, _ *http.Request) {
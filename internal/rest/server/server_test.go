@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/app"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/service"
+	"github.com/maintainerd/auth/internal/storage"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubAPIKeyService struct {
+	service.APIKeyService
+	key *model.APIKey
+}
+
+func (s stubAPIKeyService) ValidateAPIKey(_ context.Context, apiKey string) (*model.APIKey, error) {
+	if apiKey != "ak_valid" {
+		return nil, apperror.NewUnauthorized("invalid api key")
+	}
+	return s.key, nil
+}
+
+type stubRoleService struct {
+	service.RoleService
+	filters *[]service.RoleServiceGetFilter
+}
+
+func (s stubRoleService) Get(_ context.Context, filter service.RoleServiceGetFilter) (*service.RoleServiceGetResult, error) {
+	*s.filters = append(*s.filters, filter)
+	return &service.RoleServiceGetResult{}, nil
+}
+
+// TestInternalRouter_APIKey sends X-API-Key requests through the internal
+// router: the key authenticates in place of a JWT, its permissions are
+// checked, and its rate limit applies.
+func TestInternalRouter_APIKey(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+
+	rateLimit := 2
+	tenant := &model.Tenant{TenantID: 3, TenantUUID: uuid.New()}
+	key := &model.APIKey{
+		APIKeyID:   7,
+		APIKeyUUID: uuid.New(),
+		TenantID:   tenant.TenantID,
+		Tenant:     tenant,
+		Status:     model.StatusActive,
+		RateLimit:  &rateLimit,
+		APIKeyAPIs: []model.APIKeyAPI{
+			{Permissions: []model.APIKeyPermission{{Permission: &model.Permission{Name: "role:read"}}}},
+		},
+	}
+	var filters []service.RoleServiceGetFilter
+	application := &app.App{
+		Cache:         cache.New(redis.NewClient(&redis.Options{Addr: mr.Addr()})),
+		APIKeyService: stubAPIKeyService{key: key},
+		RoleService:   stubRoleService{filters: &filters},
+		Storage:       &storage.LocalStore{},
+	}
+	router := buildInternalRouter(initHandlers(application), application)
+
+	serve := func(method, path, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if apiKey != "" {
+			req.Header.Set(middleware.APIKeyHeader, apiKey)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := serve(http.MethodGet, "/api/v1/roles?page=1&limit=10", "ak_valid")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Len(t, filters, 1)
+	assert.Equal(t, tenant.TenantID, filters[0].TenantID, "scoped to the key's tenant")
+
+	rr = serve(http.MethodDelete, "/api/v1/roles/"+uuid.NewString(), "ak_valid")
+	assert.Equal(t, http.StatusForbidden, rr.Code, "the key lacks role:delete")
+
+	rr = serve(http.MethodGet, "/api/v1/roles?page=1&limit=10", "ak_valid")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+	assert.Len(t, filters, 1)
+
+	rr = serve(http.MethodGet, "/api/v1/roles?page=1&limit=10", "ak_invalid")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = serve(http.MethodGet, "/api/v1/roles?page=1&limit=10", "")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code, "no credentials")
+}
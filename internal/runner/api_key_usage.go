@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/maintainerd/auth/internal/metrics"
+)
+
+// DefaultAPIKeyUsageInterval is how often buffered API key usage is written
+// to the database.
+const DefaultAPIKeyUsageInterval = time.Minute
+
+// APIKeyUsageWorker names the API key usage runner in worker metrics.
+const APIKeyUsageWorker = "api_key_usage"
+
+// APIKeyUsageFlusher is the subset of APIKeyService that the usage runner
+// needs. Defined here to avoid an import cycle (service ↔ runner).
+type APIKeyUsageFlusher interface {
+	FlushUsage(ctx context.Context) (int64, error)
+}
+
+// StartAPIKeyUsageRunner starts a background loop that periodically moves
+// the API key request counts buffered in Redis to the database. It respects
+// context cancellation for graceful shutdown.
+func StartAPIKeyUsageRunner(ctx context.Context, flusher APIKeyUsageFlusher, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultAPIKeyUsageInterval
+	}
+
+	slog.Info("api key usage: starting usage flush runner",
+		"interval_seconds", int(interval.Seconds()),
+	)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Write what the last interval buffered before exiting.
+			if _, err := flusher.FlushUsage(context.WithoutCancel(ctx)); err != nil {
+				slog.Error("api key usage: failed final flush", "error", err)
+			}
+			slog.Info("api key usage: shutting down")
+			return
+		case <-ticker.C:
+			start := time.Now()
+			count, err := flusher.FlushUsage(ctx)
+			metrics.ObserveWorkerRun(APIKeyUsageWorker, count, err, time.Since(start))
+			if err != nil {
+				slog.Error("api key usage: failed to flush usage", "error", err, "flushed", count)
+			}
+		}
+	}
+}
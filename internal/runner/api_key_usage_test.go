@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAPIKeyUsageFlusher struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (m *mockAPIKeyUsageFlusher) FlushUsage(_ context.Context) (int64, error) {
+	m.calls.Add(1)
+	return 1, m.err
+}
+
+func TestStartAPIKeyUsageRunner_FlushesAndShutdown(t *testing.T) {
+	flusher := &mockAPIKeyUsageFlusher{}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		StartAPIKeyUsageRunner(ctx, flusher, 10*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return flusher.calls.Load() >= 1
+	}, 2*time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+
+	// Shutdown flushes once more.
+	assert.GreaterOrEqual(t, flusher.calls.Load(), int32(2))
+}
+
+func TestStartAPIKeyUsageRunner_ErrorContinues(t *testing.T) {
+	flusher := &mockAPIKeyUsageFlusher{err: errors.New("db down")}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		StartAPIKeyUsageRunner(ctx, flusher, 10*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return flusher.calls.Load() >= 2
+	}, 2*time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+}
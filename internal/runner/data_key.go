@@ -0,0 +1,58 @@
+package runner
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/maintainerd/auth/internal/metrics"
+)
+
+// DefaultDataKeyInterval is how often the data key job runs. Each run also
+// reloads the keys and re-encrypts values not sealed with the active key.
+const DefaultDataKeyInterval = 5 * time.Minute
+
+// DataKeyWorker names the data key runner in worker metrics.
+const DataKeyWorker = "data_key_rotation"
+
+// DataKeyRotator is the subset of DataKeyService that the data key runner
+// needs. Defined here to avoid an import cycle (service ↔ runner).
+type DataKeyRotator interface {
+	RotateDue(ctx context.Context) (int64, error)
+}
+
+// StartDataKeyRunner starts a background loop that periodically rotates the
+// column encryption data key when it is due and re-encrypts stored values
+// with the active key. It respects context cancellation for graceful
+// shutdown.
+func StartDataKeyRunner(ctx context.Context, rotator DataKeyRotator, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultDataKeyInterval
+	}
+
+	slog.Info("data key: starting data key rotation runner",
+		"interval_seconds", int(interval.Seconds()),
+	)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("data key: shutting down")
+			return
+		case <-ticker.C:
+			start := time.Now()
+			count, err := rotator.RotateDue(ctx)
+			metrics.ObserveWorkerRun(DataKeyWorker, count, err, time.Since(start))
+			if err != nil {
+				slog.Error("data key: failed to rotate data keys", "error", err, "changed", count)
+				continue
+			}
+			if count > 0 {
+				slog.Info("data key: rotated keys or re-encrypted values", "count", count)
+			}
+		}
+	}
+}
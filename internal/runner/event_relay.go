@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/maintainerd/auth/internal/metrics"
+)
+
+// DefaultEventRelayInterval is how often the event relay publishes newly
+// committed domain events to the event bus.
+const DefaultEventRelayInterval = 2 * time.Second
+
+// EventRelayer is the subset of EventRelayService that the relay runner
+// needs. Defined here to avoid an import cycle (service ↔ runner).
+type EventRelayer interface {
+	Name() string
+	Relay(ctx context.Context) (int64, error)
+}
+
+// EventRelayWorker names the runner of the named relay in worker metrics.
+func EventRelayWorker(name string) string {
+	return "event_relay." + name
+}
+
+// StartEventRelayRunner starts a background loop that publishes committed
+// domain events to the event bus. Each tick relays batches until no events
+// are left, so a backlog drains without waiting for further ticks. It
+// respects context cancellation for graceful shutdown.
+func StartEventRelayRunner(ctx context.Context, relayer EventRelayer, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultEventRelayInterval
+	}
+
+	worker := EventRelayWorker(relayer.Name())
+	slog.Info("event relay: starting event relay runner",
+		"relay", relayer.Name(),
+		"interval_seconds", int(interval.Seconds()),
+	)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("event relay: shutting down")
+			return
+		case <-ticker.C:
+			start := time.Now()
+			var total int64
+			var runErr error
+			for ctx.Err() == nil {
+				published, err := relayer.Relay(ctx)
+				total += published
+				if err != nil {
+					// Events that failed are published again next tick.
+					slog.Error("event relay: failed to publish events", "error", err, "published", published)
+					runErr = err
+					break
+				}
+				if published == 0 {
+					break
+				}
+				slog.Debug("event relay: published events", "published", published)
+			}
+			metrics.ObserveWorkerRun(worker, total, runErr, time.Since(start))
+		}
+	}
+}
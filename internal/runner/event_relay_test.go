@@ -0,0 +1,86 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/maintainerd/auth/internal/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockEventRelayer struct {
+	calls   atomic.Int32
+	backlog atomic.Int32
+	err     error
+}
+
+func (m *mockEventRelayer) Name() string { return "test" }
+
+func (m *mockEventRelayer) Relay(_ context.Context) (int64, error) {
+	m.calls.Add(1)
+	if m.err != nil {
+		return 0, m.err
+	}
+	if m.backlog.Load() > 0 {
+		m.backlog.Add(-1)
+		return 500, nil
+	}
+	return 0, nil
+}
+
+func TestStartEventRelayRunner_DrainsBacklogAndShutdown(t *testing.T) {
+	relayer := &mockEventRelayer{}
+	relayer.backlog.Store(3)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		StartEventRelayRunner(ctx, relayer, 10*time.Millisecond)
+		close(done)
+	}()
+
+	// Three full batches and the empty run that ends the first tick.
+	assert.Eventually(t, func() bool {
+		return relayer.backlog.Load() == 0 && relayer.calls.Load() >= 4
+	}, 2*time.Second, 5*time.Millisecond)
+
+	// The whole drain counts as one run of the relay worker.
+	assert.Eventually(t, func() bool {
+		for _, st := range metrics.Workers() {
+			if st.Worker == EventRelayWorker("test") {
+				return st.Items == 1500
+			}
+		}
+		return false
+	}, 2*time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+}
+
+func TestStartEventRelayRunner_RetriesAfterError(t *testing.T) {
+	relayer := &mockEventRelayer{err: errors.New("db down")}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		StartEventRelayRunner(ctx, relayer, 10*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return relayer.calls.Load() >= 2
+	}, 2*time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+}
+
+func TestStartEventRelayRunner_DefaultInterval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	StartEventRelayRunner(ctx, &mockEventRelayer{}, 0)
+}
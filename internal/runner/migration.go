@@ -2,11 +2,18 @@ package runner
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/maintainerd/auth/internal/database/migration"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/database"
 	"gorm.io/gorm"
 )
 
@@ -15,142 +22,217 @@ import (
 // when multiple instances start against the same database simultaneously.
 const advisoryLockKey = 7316949
 
-// migrationEntry pairs a unique version string with its migration function.
-// The version string is what gets written to schema_migrations — it must never
-// be changed after a migration has been applied.
-type migrationEntry struct {
+// migrationTable is the goose version table. Releases before goose recorded
+// applied migrations by name in a table of the same name; see
+// upgradeTrackingTable.
+const migrationTable = "schema_migrations"
+
+// migrationFS holds the migrations to apply. It is a variable so tests can
+// replace it.
+var migrationFS fs.FS = migration.FS
+
+// MigrationState is the state of a known migration.
+type MigrationState struct {
+	// Version is the migration file name without its extension, e.g.
+	// "001_create_tenants_table".
 	Version string
-	Fn      func(db *gorm.DB) error
+	// AppliedAt is nil while the migration is pending.
+	AppliedAt *time.Time
 }
 
-// migrations is the ordered list of all migrations. Add new entries at the
-// bottom only — never reorder or remove existing entries.
-var migrations = []migrationEntry{
-	{"001_create_tenants_table", migration.CreateTenantTable},
-	{"002_create_user_pools_table", migration.CreateUserPoolTable},
-	{"003_create_branding_table", migration.CreateBrandingTable},
-	{"004_create_tenant_settings_table", migration.CreateTenantSettingsTable},
-	{"005_create_email_config_table", migration.CreateEmailConfigTable},
-	{"006_create_sms_config_table", migration.CreateSMSConfigTable},
-	{"007_create_webhook_endpoints_table", migration.CreateWebhookEndpointsTable},
-	{"008_create_services_table", migration.CreateServiceTable},
-	{"009_create_tenant_services_table", migration.CreateTenantServicesTable},
-	{"010_create_policies_table", migration.CreatePoliciesTable},
-	{"011_create_service_policies_table", migration.CreateServicePoliciesTable},
-	{"013_create_apis_table", migration.CreateAPITable},
-	{"014_create_permissions_table", migration.CreatePermissionTable},
-	{"015_create_api_permissions_table", migration.CreateApiPermissionTable},
-	{"016_create_identity_providers_table", migration.CreateIdentityProviderTable},
-	{"017_create_clients_table", migration.CreateClientTable},
-	{"018_create_client_uris_table", migration.CreateClientURIsTable},
-	{"019_create_client_apis_table", migration.CreateClientAPIsTable},
-	{"020_create_client_permissions_table", migration.CreateClientPermissionTable},
-	{"021_create_api_keys_table", migration.CreateAPIKeysTable},
-	{"022_create_api_key_apis_table", migration.CreateAPIKeyAPITable},
-	{"023_create_api_key_permissions_table", migration.CreateAPIKeyPermissionsTable},
-	{"024_create_roles_table", migration.CreateRoleTable},
-	{"025_create_role_permissions_table", migration.CreateRolePermissionTable},
-	{"026_create_users_table", migration.CreateUserTable},
-	{"027_create_user_identities_table", migration.CreateUserIdentityTable},
-	{"028_create_user_roles_table", migration.CreateUserRoleTable},
-	{"029_create_user_tokens_table", migration.CreateUserTokenTable},
-	{"030_create_user_settings_table", migration.CreateUserSettingsTable},
-	{"031_create_profiles_table", migration.CreateProfileTable},
-	{"032_create_tenant_members_table", migration.CreateTenantMembersTable},
-	{"033_create_signup_flows_table", migration.CreateSignupFlowTable},
-	{"034_create_signup_flow_roles_table", migration.CreateSignupFlowRoleTable},
-	{"035_create_invites_table", migration.CreateInvitesTable},
-	{"036_create_invite_roles_table", migration.CreateInviteRolesTable},
-	{"037_create_security_settings_table", migration.CreateSecuritySettingsTable},
-	{"038_create_ip_restriction_rules_table", migration.CreateIPRestrictionRulesTable},
-	{"039_create_security_settings_audit_table", migration.CreateSecuritySettingsAuditTable},
-	{"040_create_login_templates_table", migration.CreateLoginTemplatesTable},
-	{"041_create_email_templates_table", migration.CreateEmailTemplatesTable},
-	{"042_create_sms_templates_table", migration.CreateSMSTemplatesTable},
-	{"043_create_auth_events_table", migration.CreateAuthEventsTable},
-	{"044_create_oauth_authorization_codes_table", migration.CreateOAuthAuthorizationCodesTable},
-	{"045_create_oauth_refresh_tokens_table", migration.CreateOAuthRefreshTokensTable},
-	{"046_create_oauth_consent_grants_table", migration.CreateOAuthConsentGrantsTable},
-	{"047_create_oauth_consent_challenges_table", migration.CreateOAuthConsentChallengesTable},
+// RunMigrations applies every pending migration; the server runs it on
+// startup.
+func RunMigrations(db *gorm.DB) error {
+	_, err := ApplyMigrations(db)
+	return err
 }
 
-// RunMigrations bootstraps the schema_migrations tracking table, acquires a
-// PostgreSQL session-level advisory lock so only one pod runs migrations at a
-// time, then applies every unapplied migration in order.
-func RunMigrations(db *gorm.DB) error {
+// ApplyMigrations acquires a PostgreSQL session-level advisory lock so only
+// one pod runs migrations at a time, then applies every unapplied migration
+// in order, each in its own transaction with its version row. It returns the
+// versions it applied. It is a variable so tests can replace it.
+var ApplyMigrations = applyMigrations
+
+// RollbackMigrations reverts the last steps applied migrations, newest
+// first, under the same advisory lock, and returns the versions it reverted.
+// It is a variable so tests can replace it.
+var RollbackMigrations = rollbackMigrations
+
+// MigrationStatus returns every known migration in order with the time it
+// was applied. It is a variable so tests can replace it.
+var MigrationStatus = migrationStatus
+
+func applyMigrations(db *gorm.DB) ([]string, error) {
+	var applied []string
+	err := withMigrationProvider(db, func(ctx context.Context, provider *goose.Provider) error {
+		results, err := provider.Up(ctx)
+		var partial *goose.PartialError
+		if errors.As(err, &partial) {
+			results = partial.Applied
+			err = fmt.Errorf("migration: %s failed: %w", migrationVersion(partial.Failed.Source), partial.Err)
+		}
+		for _, result := range results {
+			applied = append(applied, migrationVersion(result.Source))
+			slog.Info("migration: applied", "version", migrationVersion(result.Source), "duration_ms", result.Duration.Milliseconds())
+		}
+		if err != nil {
+			return err
+		}
+
+		slog.Info("migration: all migrations complete")
+		return nil
+	})
+	return applied, err
+}
+
+func rollbackMigrations(db *gorm.DB, steps int) ([]string, error) {
+	if steps < 1 {
+		return nil, errors.New("migration: steps must be at least 1")
+	}
+
+	var reverted []string
+	err := withMigrationProvider(db, func(ctx context.Context, provider *goose.Provider) error {
+		for len(reverted) < steps {
+			result, err := provider.Down(ctx)
+			if errors.Is(err, goose.ErrNoNextVersion) {
+				return nil
+			}
+			var partial *goose.PartialError
+			if errors.As(err, &partial) {
+				return fmt.Errorf("migration: revert %s failed: %w", migrationVersion(partial.Failed.Source), partial.Err)
+			}
+			if err != nil {
+				return fmt.Errorf("migration: revert: %w", err)
+			}
+			reverted = append(reverted, migrationVersion(result.Source))
+			slog.Info("migration: reverted", "version", migrationVersion(result.Source), "duration_ms", result.Duration.Milliseconds())
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+func migrationStatus(db *gorm.DB) ([]MigrationState, error) {
+	var states []MigrationState
+	err := withMigrationProvider(db, func(ctx context.Context, provider *goose.Provider) error {
+		statuses, err := provider.Status(ctx)
+		if err != nil {
+			return fmt.Errorf("migration: read %s: %w", migrationTable, err)
+		}
+		states = make([]MigrationState, 0, len(statuses))
+		for _, status := range statuses {
+			state := MigrationState{Version: migrationVersion(status.Source)}
+			if status.State == goose.StateApplied {
+				appliedAt := status.AppliedAt
+				state.AppliedAt = &appliedAt
+			}
+			states = append(states, state)
+		}
+		return nil
+	})
+	return states, err
+}
+
+// withMigrationProvider runs fn with a goose provider over the embedded
+// migrations while holding the migration advisory lock. The lock is
+// session-level, so it is taken and released on one dedicated connection.
+func withMigrationProvider(db *gorm.DB, fn func(ctx context.Context, provider *goose.Provider) error) error {
 	ctx := context.Background()
 
 	sqlDB, err := db.DB()
 	if err != nil {
 		return fmt.Errorf("migration: get underlying sql.DB: %w", err)
 	}
+	provider, err := goose.NewProvider(goose.DialectPostgres, sqlDB, migrationFS,
+		goose.WithTableName(migrationTable),
+		goose.WithDisableGlobalRegistry(true),
+	)
+	if err != nil {
+		return fmt.Errorf("migration: load migrations: %w", err)
+	}
 
-	// Bootstrap the tracking table first. This is the one call that is always
-	// idempotent — IF NOT EXISTS makes it safe to run on every startup.
-	if err := bootstrapTrackingTable(db); err != nil {
-		return err
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("migration: get lock connection: %w", err)
 	}
+	defer conn.Close()
 
 	// Acquire a session-level advisory lock. pg_advisory_lock blocks until the
 	// lock is free, so concurrent pods will queue up here rather than racing.
-	if _, err := sqlDB.ExecContext(ctx, "SELECT pg_advisory_lock($1)", advisoryLockKey); err != nil {
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", advisoryLockKey); err != nil {
 		return fmt.Errorf("migration: acquire advisory lock: %w", err)
 	}
-	defer sqlDB.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", advisoryLockKey) //nolint:errcheck
+	defer conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", advisoryLockKey) //nolint:errcheck
 
 	slog.Info("migration: advisory lock acquired")
-
-	for _, m := range migrations {
-		applied, err := isMigrationApplied(db, m.Version)
-		if err != nil {
-			return err
-		}
-		if applied {
-			slog.Debug("migration: already applied, skipping", "version", m.Version)
-			continue
-		}
-
-		start := time.Now()
-		if err := m.Fn(db); err != nil {
-			return fmt.Errorf("migration: %s failed: %w", m.Version, err)
-		}
-		if err := recordMigration(db, m.Version); err != nil {
-			return err
-		}
-		slog.Info("migration: applied", "version", m.Version, "duration_ms", time.Since(start).Milliseconds())
+	if err := upgradeTrackingTable(ctx, sqlDB); err != nil {
+		return err
 	}
-
-	slog.Info("migration: all migrations complete")
-	return nil
+	return fn(ctx, provider)
 }
 
-// bootstrapTrackingTable creates the schema_migrations table if it does not
-// already exist. This runs before the advisory lock is acquired because it must
-// succeed for any migration logic to work, and CREATE TABLE IF NOT EXISTS is
-// itself safe to run concurrently in PostgreSQL.
-func bootstrapTrackingTable(db *gorm.DB) error {
-	sql := `
-CREATE TABLE IF NOT EXISTS schema_migrations (
-    version    VARCHAR(255) PRIMARY KEY,
-    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
-);`
-	if err := db.Exec(sql).Error; err != nil {
-		return fmt.Errorf("migration: bootstrap schema_migrations table: %w", err)
+// upgradeTrackingTable converts the schema_migrations table of releases
+// before goose, which held one row per applied migration name, into the goose
+// version table, keeping when each migration was applied. It does nothing
+// once the table has been converted or on a new database.
+func upgradeTrackingTable(ctx context.Context, sqlDB *sql.DB) error {
+	var legacy bool
+	err := sqlDB.QueryRowContext(ctx, `
+SELECT EXISTS (
+    SELECT 1 FROM information_schema.columns
+    WHERE table_schema = current_schema() AND table_name = $1 AND column_name = 'applied_at'
+)`, migrationTable).Scan(&legacy)
+	if err != nil {
+		return fmt.Errorf("migration: inspect %s: %w", migrationTable, err)
+	}
+	if !legacy {
+		return nil
 	}
-	return nil
-}
 
-func isMigrationApplied(db *gorm.DB, version string) (bool, error) {
-	var count int64
-	if err := db.Raw("SELECT COUNT(1) FROM schema_migrations WHERE version = ?", version).Scan(&count).Error; err != nil {
-		return false, fmt.Errorf("migration: check applied status for %s: %w", version, err)
+	store, err := database.NewStore(database.DialectPostgres, migrationTable)
+	if err != nil {
+		return fmt.Errorf("migration: create version store: %w", err)
 	}
-	return count > 0, nil
-}
+	tx, err := sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("migration: begin %s upgrade: %w", migrationTable, err)
+	}
+	defer tx.Rollback() //nolint:errcheck
 
-func recordMigration(db *gorm.DB, version string) error {
-	if err := db.Exec("INSERT INTO schema_migrations (version) VALUES (?)", version).Error; err != nil {
-		return fmt.Errorf("migration: record %s in schema_migrations: %w", version, err)
+	// Copy the old rows aside first: the new table reuses the old table's
+	// name and its primary key index name.
+	steps := []func() error{
+		func() error {
+			_, err := tx.ExecContext(ctx, "CREATE TEMPORARY TABLE legacy_schema_migrations ON COMMIT DROP AS SELECT version, applied_at FROM "+migrationTable)
+			return err
+		},
+		func() error {
+			_, err := tx.ExecContext(ctx, "DROP TABLE "+migrationTable)
+			return err
+		},
+		func() error { return store.CreateVersionTable(ctx, tx) },
+		func() error { return store.Insert(ctx, tx, database.InsertRequest{Version: 0}) },
+		func() error {
+			_, err := tx.ExecContext(ctx, "INSERT INTO "+migrationTable+` (version_id, is_applied, tstamp)
+SELECT CAST(split_part(version, '_', 1) AS BIGINT), TRUE, applied_at
+FROM legacy_schema_migrations ORDER BY version`)
+			return err
+		},
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return fmt.Errorf("migration: upgrade %s: %w", migrationTable, err)
+		}
 	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("migration: commit %s upgrade: %w", migrationTable, err)
+	}
+	slog.Info("migration: converted schema_migrations to goose versions")
 	return nil
 }
+
+// migrationVersion names a migration by its file name without extension,
+// the name releases before goose recorded.
+func migrationVersion(source *goose.Source) string {
+	return strings.TrimSuffix(path.Base(source.Path), path.Ext(source.Path))
+}
@@ -0,0 +1,223 @@
+package runner
+
+import (
+	"errors"
+	"io/fs"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/maintainerd/auth/internal/database/migration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newMigrationTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return db, mock
+}
+
+// withTestMigrations replaces the embedded migrations with two that create
+// and drop the tables a and b.
+func withTestMigrations(t *testing.T) {
+	t.Helper()
+	orig := migrationFS
+	t.Cleanup(func() { migrationFS = orig })
+
+	file := func(up, down string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte("-- +goose Up\n" + up + ";\n\n-- +goose Down\n" + down + ";\n")}
+	}
+	migrationFS = fstest.MapFS{
+		"001_create_a.sql": file("CREATE TABLE a", "DROP TABLE a"),
+		"002_create_b.sql": file("CREATE TABLE b", "DROP TABLE b"),
+	}
+}
+
+// expectLock expects the advisory lock and a tracking table that needs no
+// upgrade.
+func expectLock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("SELECT pg_advisory_lock").WithArgs(advisoryLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("FROM information_schema.columns").WithArgs(migrationTable).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(advisoryLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+// expectVersions expects goose to find its version table and list the
+// applied versions, newest first.
+func expectVersions(mock sqlmock.Sqlmock, versions ...int64) {
+	mock.ExpectQuery("FROM pg_tables").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	expectVersionList(mock, versions...)
+}
+
+func expectVersionList(mock sqlmock.Sqlmock, versions ...int64) {
+	rows := sqlmock.NewRows([]string{"version_id", "is_applied"})
+	for _, version := range versions {
+		rows.AddRow(version, true)
+	}
+	mock.ExpectQuery("SELECT version_id, is_applied from schema_migrations").WillReturnRows(rows)
+}
+
+func TestMigrations_Files(t *testing.T) {
+	name := regexp.MustCompile(`^\d{3}_[a-z0-9_]+\.sql$`)
+	entries, err := fs.ReadDir(migration.FS, ".")
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+
+	prev := ""
+	for _, entry := range entries {
+		assert.Regexp(t, name, entry.Name())
+		assert.Greater(t, entry.Name()[:3], prev, "versions must be unique and in order")
+		prev = entry.Name()[:3]
+
+		data, err := fs.ReadFile(migration.FS, entry.Name())
+		require.NoError(t, err)
+		_, down, ok := strings.Cut(string(data), "-- +goose Down\n")
+		assert.True(t, ok, "%s has no down section", entry.Name())
+		assert.Contains(t, string(data), "-- +goose Up\n", entry.Name())
+		assert.NotEmpty(t, strings.TrimSpace(down), "%s has an empty down section", entry.Name())
+	}
+}
+
+func TestApplyMigrations(t *testing.T) {
+	t.Run("applies pending migrations in a transaction each", func(t *testing.T) {
+		withTestMigrations(t)
+		db, mock := newMigrationTestDB(t)
+		expectLock(mock)
+		expectVersions(mock, 1, 0)
+		expectVersionList(mock, 1, 0)
+		mock.ExpectBegin()
+		mock.ExpectExec("CREATE TABLE b").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(int64(2), true).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery(`SELECT max\(version_id\) FROM schema_migrations`).WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(2))
+		expectUnlock(mock)
+
+		applied, err := ApplyMigrations(db)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"002_create_b"}, applied)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed migration is rolled back and stops the run", func(t *testing.T) {
+		withTestMigrations(t)
+		db, mock := newMigrationTestDB(t)
+		expectLock(mock)
+		expectVersions(mock, 0)
+		expectVersionList(mock, 0)
+		mock.ExpectBegin()
+		mock.ExpectExec("CREATE TABLE a").WillReturnError(errors.New("syntax error"))
+		mock.ExpectRollback()
+		expectUnlock(mock)
+
+		applied, err := ApplyMigrations(db)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "001_create_a failed")
+		assert.Empty(t, applied)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRollbackMigrations(t *testing.T) {
+	t.Run("reverts the newest applied migrations", func(t *testing.T) {
+		withTestMigrations(t)
+		db, mock := newMigrationTestDB(t)
+		expectLock(mock)
+		expectVersions(mock, 1, 0)
+		mock.ExpectBegin()
+		mock.ExpectExec("DROP TABLE a").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM schema_migrations").WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		expectVersionList(mock, 0)
+		expectUnlock(mock)
+
+		reverted, err := RollbackMigrations(db, 5)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"001_create_a"}, reverted)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("invalid steps", func(t *testing.T) {
+		db, _ := newMigrationTestDB(t)
+		_, err := RollbackMigrations(db, 0)
+		assert.Error(t, err)
+	})
+}
+
+func TestMigrationStatus(t *testing.T) {
+	withTestMigrations(t)
+	db, mock := newMigrationTestDB(t)
+	appliedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	expectLock(mock)
+	mock.ExpectQuery("FROM pg_tables").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("SELECT tstamp, is_applied FROM schema_migrations").WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"tstamp", "is_applied"}).AddRow(appliedAt, true))
+	mock.ExpectQuery("SELECT tstamp, is_applied FROM schema_migrations").WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"tstamp", "is_applied"}))
+	expectUnlock(mock)
+
+	states, err := MigrationStatus(db)
+	require.NoError(t, err)
+	require.Len(t, states, 2)
+	assert.Equal(t, "001_create_a", states[0].Version)
+	require.NotNil(t, states[0].AppliedAt)
+	assert.True(t, appliedAt.Equal(*states[0].AppliedAt))
+	assert.Equal(t, "002_create_b", states[1].Version)
+	assert.Nil(t, states[1].AppliedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpgradeTrackingTable(t *testing.T) {
+	t.Run("converts the pre-goose table keeping applied times", func(t *testing.T) {
+		withTestMigrations(t)
+		db, mock := newMigrationTestDB(t)
+		mock.ExpectExec("SELECT pg_advisory_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("FROM information_schema.columns").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectBegin()
+		mock.ExpectExec("CREATE TEMPORARY TABLE legacy_schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DROP TABLE schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(int64(0), true).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`(?s)INSERT INTO schema_migrations \(version_id, is_applied, tstamp\).*split_part\(version, '_', 1\).*FROM legacy_schema_migrations`).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+		// Both migrations were applied before the upgrade, so none is pending.
+		expectVersions(mock, 2, 1, 0)
+		expectUnlock(mock)
+
+		applied, err := ApplyMigrations(db)
+		require.NoError(t, err)
+		assert.Empty(t, applied)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed upgrade stops the run", func(t *testing.T) {
+		withTestMigrations(t)
+		db, mock := newMigrationTestDB(t)
+		mock.ExpectExec("SELECT pg_advisory_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("FROM information_schema.columns").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectBegin()
+		mock.ExpectExec("CREATE TEMPORARY TABLE legacy_schema_migrations").WillReturnError(errors.New("permission denied"))
+		mock.ExpectRollback()
+		expectUnlock(mock)
+
+		_, err := ApplyMigrations(db)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "upgrade schema_migrations")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,46 @@
+package runner
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// DefaultQueueMetricsInterval is how often queue depth and age are sampled
+// for the queue metrics.
+const DefaultQueueMetricsInterval = 30 * time.Second
+
+// QueueSampler is the subset of QueueHealthService that the queue metrics
+// runner needs. Defined here to avoid an import cycle (service ↔ runner).
+type QueueSampler interface {
+	Sample(ctx context.Context) error
+}
+
+// StartQueueMetricsRunner starts a background loop that periodically
+// measures the queues drained by background workers, so that their depth and
+// oldest item age are current on /metrics. It respects context cancellation
+// for graceful shutdown.
+func StartQueueMetricsRunner(ctx context.Context, sampler QueueSampler, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultQueueMetricsInterval
+	}
+
+	slog.Info("queue metrics: starting queue metrics runner",
+		"interval_seconds", int(interval.Seconds()),
+	)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("queue metrics: shutting down")
+			return
+		case <-ticker.C:
+			if err := sampler.Sample(ctx); err != nil {
+				slog.Error("queue metrics: failed to sample queues", "error", err)
+			}
+		}
+	}
+}
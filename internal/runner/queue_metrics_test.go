@@ -0,0 +1,63 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockQueueSampler struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (m *mockQueueSampler) Sample(_ context.Context) error {
+	m.calls.Add(1)
+	return m.err
+}
+
+func TestStartQueueMetricsRunner_SamplesAndShutdown(t *testing.T) {
+	sampler := &mockQueueSampler{}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		StartQueueMetricsRunner(ctx, sampler, 10*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return sampler.calls.Load() >= 2
+	}, 2*time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+}
+
+func TestStartQueueMetricsRunner_KeepsRunningAfterError(t *testing.T) {
+	sampler := &mockQueueSampler{err: errors.New("db down")}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		StartQueueMetricsRunner(ctx, sampler, 10*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return sampler.calls.Load() >= 2
+	}, 2*time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+}
+
+func TestStartQueueMetricsRunner_DefaultInterval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	StartQueueMetricsRunner(ctx, &mockQueueSampler{}, 0)
+}
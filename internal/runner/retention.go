@@ -4,36 +4,34 @@ import (
 	"context"
 	"log/slog"
 	"time"
+
+	"github.com/maintainerd/auth/internal/metrics"
 )
 
 const (
-	// DefaultRetentionPeriod is the default maximum age for auth events.
-	// PCI DSS 10.7.1 requires at least 12 months of audit trail history.
-	DefaultRetentionPeriod = 365 * 24 * time.Hour
-
 	// DefaultRetentionInterval is how often the retention job runs.
 	DefaultRetentionInterval = 24 * time.Hour
+
+	// RetentionWorker names the retention runner in worker metrics.
+	RetentionWorker = "auth_event_retention"
 )
 
-// RetentionDeleter is the subset of AuthEventService that the retention runner
-// needs. Defined here to avoid an import cycle (service ↔ runner).
-type RetentionDeleter interface {
-	DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
+// RetentionApplier is the subset of AuditArchiveService that the retention
+// runner needs. Defined here to avoid an import cycle (service ↔ runner).
+type RetentionApplier interface {
+	ApplyRetention(ctx context.Context) (int64, error)
 }
 
-// StartRetentionRunner starts a background goroutine that periodically deletes
-// auth events older than the configured retention period. It respects context
-// cancellation for graceful shutdown.
-func StartRetentionRunner(ctx context.Context, deleter RetentionDeleter, retention, interval time.Duration) {
-	if retention <= 0 {
-		retention = DefaultRetentionPeriod
-	}
+// StartRetentionRunner starts a background goroutine that periodically
+// removes auth events older than their tenant's retention period, archiving
+// them first for tenants that ask for it. It respects context cancellation
+// for graceful shutdown.
+func StartRetentionRunner(ctx context.Context, applier RetentionApplier, interval time.Duration) {
 	if interval <= 0 {
 		interval = DefaultRetentionInterval
 	}
 
 	slog.Info("retention: starting auth event retention runner",
-		"retention_days", int(retention.Hours()/24),
 		"interval_hours", int(interval.Hours()),
 	)
 
@@ -46,17 +44,16 @@ func StartRetentionRunner(ctx context.Context, deleter RetentionDeleter, retenti
 			slog.Info("retention: shutting down")
 			return
 		case <-ticker.C:
-			cutoff := time.Now().UTC().Add(-retention)
-			count, err := deleter.DeleteOlderThan(ctx, cutoff)
+			start := time.Now()
+			count, err := applier.ApplyRetention(ctx)
+			metrics.ObserveWorkerRun(RetentionWorker, count, err, time.Since(start))
 			if err != nil {
-				slog.Error("retention: failed to delete old auth events", "error", err)
+				// Tenants that succeeded are still counted
+				slog.Error("retention: failed to remove expired auth events", "error", err, "count", count)
 				continue
 			}
 			if count > 0 {
-				slog.Info("retention: deleted old auth events",
-					"count", count,
-					"cutoff", cutoff.Format(time.RFC3339),
-				)
+				slog.Info("retention: removed expired auth events", "count", count)
 			}
 		}
 	}
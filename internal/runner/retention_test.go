@@ -10,38 +10,38 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-type mockRetentionDeleter struct {
+type mockRetentionApplier struct {
 	mu    sync.Mutex
-	calls []time.Time
+	calls int
 	err   error
 	count int64
 }
 
-func (m *mockRetentionDeleter) DeleteOlderThan(_ context.Context, cutoff time.Time) (int64, error) {
+func (m *mockRetentionApplier) ApplyRetention(_ context.Context) (int64, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.calls = append(m.calls, cutoff)
+	m.calls++
 	return m.count, m.err
 }
 
-func (m *mockRetentionDeleter) callCount() int {
+func (m *mockRetentionApplier) callCount() int {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	return len(m.calls)
+	return m.calls
 }
 
 func TestStartRetentionRunner_DeletesAndShutdown(t *testing.T) {
-	deleter := &mockRetentionDeleter{count: 5}
+	applier := &mockRetentionApplier{count: 5}
 	ctx, cancel := context.WithCancel(context.Background())
 
 	done := make(chan struct{})
 	go func() {
-		StartRetentionRunner(ctx, deleter, 24*time.Hour, 10*time.Millisecond)
+		StartRetentionRunner(ctx, applier, 10*time.Millisecond)
 		close(done)
 	}()
 
 	assert.Eventually(t, func() bool {
-		return deleter.callCount() >= 1
+		return applier.callCount() >= 1
 	}, 2*time.Second, 5*time.Millisecond)
 
 	cancel()
@@ -49,17 +49,17 @@ func TestStartRetentionRunner_DeletesAndShutdown(t *testing.T) {
 }
 
 func TestStartRetentionRunner_ErrorContinues(t *testing.T) {
-	deleter := &mockRetentionDeleter{err: errors.New("db down"), count: 0}
+	applier := &mockRetentionApplier{err: errors.New("db down"), count: 0}
 	ctx, cancel := context.WithCancel(context.Background())
 
 	done := make(chan struct{})
 	go func() {
-		StartRetentionRunner(ctx, deleter, 24*time.Hour, 10*time.Millisecond)
+		StartRetentionRunner(ctx, applier, 10*time.Millisecond)
 		close(done)
 	}()
 
 	assert.Eventually(t, func() bool {
-		return deleter.callCount() >= 2
+		return applier.callCount() >= 2
 	}, 2*time.Second, 5*time.Millisecond)
 
 	cancel()
@@ -67,24 +67,24 @@ func TestStartRetentionRunner_ErrorContinues(t *testing.T) {
 }
 
 func TestStartRetentionRunner_DefaultsOnZero(t *testing.T) {
-	deleter := &mockRetentionDeleter{count: 0}
+	applier := &mockRetentionApplier{count: 0}
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
-	StartRetentionRunner(ctx, deleter, 0, 0)
+	StartRetentionRunner(ctx, applier, 0)
 }
 
 func TestStartRetentionRunner_ZeroCount(t *testing.T) {
-	deleter := &mockRetentionDeleter{count: 0}
+	applier := &mockRetentionApplier{count: 0}
 	ctx, cancel := context.WithCancel(context.Background())
 
 	done := make(chan struct{})
 	go func() {
-		StartRetentionRunner(ctx, deleter, 24*time.Hour, 10*time.Millisecond)
+		StartRetentionRunner(ctx, applier, 10*time.Millisecond)
 		close(done)
 	}()
 
 	assert.Eventually(t, func() bool {
-		return deleter.callCount() >= 1
+		return applier.callCount() >= 1
 	}, 2*time.Second, 5*time.Millisecond)
 
 	cancel()
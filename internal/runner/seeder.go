@@ -10,6 +10,11 @@ import (
 
 var RunSeeders = runSeeders
 
+// ProvisionTenant seeds the default API, permissions, identity provider,
+// clients, roles, email templates and security settings of a tenant created
+// after setup. It is a variable so tests can replace it.
+var ProvisionTenant = provisionTenant
+
 func runSeeders(db *gorm.DB, appVersion string) error {
 	slog.Info("Running default seeders")
 
@@ -29,47 +34,75 @@ func runSeeders(db *gorm.DB, appVersion string) error {
 	}
 	slog.Info("Found default tenant", "tenant_id", tenant.TenantID)
 
+	if err := seedTenant(db, tenant.TenantID, service.ServiceID); err != nil {
+		return err
+	}
+
+	slog.Info("Default seeding process completed")
+	return nil
+}
+
+func provisionTenant(db *gorm.DB, tenantID int64, appVersion string) error {
+	slog.Info("Provisioning tenant", "tenant_id", tenantID)
+
+	// 001: Seed service (shared by every tenant)
+	service, err := seeder.SeedService(db, appVersion)
+	if err != nil {
+		slog.Error("Failed to seed service", "error", err)
+		return err
+	}
+
+	if err := seedTenant(db, tenantID, service.ServiceID); err != nil {
+		return err
+	}
+
+	slog.Info("Tenant provisioning completed", "tenant_id", tenantID)
+	return nil
+}
+
+// seedTenant runs the tenant-scoped seeders 002 to 012.
+func seedTenant(db *gorm.DB, tenantID, serviceID int64) error {
 	// 002: Link tenant to service
-	_, err = seeder.SeedTenantService(db, tenant.TenantID, service.ServiceID)
+	_, err := seeder.SeedTenantService(db, tenantID, serviceID)
 	if err != nil {
 		slog.Error("Failed to seed tenant_service", "error", err)
 		return err
 	}
 
 	// 003: Seed API
-	api, err := seeder.SeedAPI(db, tenant.TenantID, service.ServiceID)
+	api, err := seeder.SeedAPI(db, tenantID, serviceID)
 	if err != nil {
 		slog.Error("Failed to seed api", "error", err)
 		return err
 	}
 
 	// 004: Seed permissions
-	if err := seeder.SeedPermissions(db, tenant.TenantID, api.APIID); err != nil {
+	if err := seeder.SeedPermissions(db, tenantID, api.APIID); err != nil {
 		slog.Error("Failed to seed permissions", "error", err)
 		return err
 	}
 
 	// 005: Seed identity providers
-	identityProvider, err := seeder.SeedIdentityProviders(db, tenant.TenantID)
+	identityProvider, err := seeder.SeedIdentityProviders(db, tenantID)
 	if err != nil {
 		slog.Error("Failed to seed identity provider", "error", err)
 		return err
 	}
 
 	// 006: Seed auth clients
-	if err := seeder.SeedClients(db, tenant.TenantID, identityProvider.IdentityProviderID); err != nil {
+	if err := seeder.SeedClients(db, tenantID, identityProvider.IdentityProviderID); err != nil {
 		slog.Error("Failed to seed auth clients", "error", err)
 		return err
 	}
 
 	// 007: Seed auth client URIs
-	if err := seeder.SeedClientURIs(db, tenant.TenantID, identityProvider.IdentityProviderID); err != nil {
+	if err := seeder.SeedClientURIs(db, tenantID, identityProvider.IdentityProviderID); err != nil {
 		slog.Error("Failed to seed auth client URIs", "error", err)
 		return err
 	}
 
 	// 008: Seed roles
-	roles, err := seeder.SeedRoles(db, tenant.TenantID)
+	roles, err := seeder.SeedRoles(db, tenantID)
 	if err != nil {
 		slog.Error("Failed to seed roles", "error", err)
 		return err
@@ -82,15 +115,15 @@ func runSeeders(db *gorm.DB, appVersion string) error {
 	}
 
 	// 010: Seed email templates
-	if err := seeder.SeedEmailTemplates(db, tenant.TenantID); err != nil {
+	if err := seeder.SeedEmailTemplates(db, tenantID); err != nil {
 		slog.Error("Failed to seed email templates", "error", err)
 		return err
 	}
 
-	// 011: Seed security settings (scoped to the system user pool)
-	var systemPool model.UserPool
-	if err := db.Where("tenant_id = ? AND is_system = ?", tenant.TenantID, true).First(&systemPool).Error; err != nil {
-		slog.Error("Failed to find system user pool", "error", err)
+	// 012: Seed the system user pool, then 011: its security settings
+	systemPool, err := seeder.SeedUserPool(db, tenantID)
+	if err != nil {
+		slog.Error("Failed to seed system user pool", "error", err)
 		return err
 	}
 	if err := seeder.SeedSecuritySettings(db, systemPool.UserPoolID); err != nil {
@@ -98,6 +131,5 @@ func runSeeders(db *gorm.DB, appVersion string) error {
 		return err
 	}
 
-	slog.Info("Default seeding process completed")
 	return nil
 }
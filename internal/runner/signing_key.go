@@ -0,0 +1,59 @@
+package runner
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/maintainerd/auth/internal/metrics"
+)
+
+// DefaultSigningKeyInterval is how often the signing key job runs. Each run
+// also reloads the keys, so it bounds how long other instances keep signing
+// with a key that was rotated elsewhere.
+const DefaultSigningKeyInterval = time.Minute
+
+// SigningKeyWorker names the signing key runner in worker metrics.
+const SigningKeyWorker = "signing_key_rotation"
+
+// SigningKeyRotator is the subset of SigningKeyService that the signing key
+// runner needs. Defined here to avoid an import cycle (service ↔ runner).
+type SigningKeyRotator interface {
+	RotateDue(ctx context.Context) (int64, error)
+}
+
+// StartSigningKeyRunner starts a background loop that periodically rotates
+// the JWT signing key when it is due and retires rotated keys once their
+// grace period has passed. It respects context cancellation for graceful
+// shutdown.
+func StartSigningKeyRunner(ctx context.Context, rotator SigningKeyRotator, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultSigningKeyInterval
+	}
+
+	slog.Info("signing key: starting signing key rotation runner",
+		"interval_seconds", int(interval.Seconds()),
+	)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("signing key: shutting down")
+			return
+		case <-ticker.C:
+			start := time.Now()
+			count, err := rotator.RotateDue(ctx)
+			metrics.ObserveWorkerRun(SigningKeyWorker, count, err, time.Since(start))
+			if err != nil {
+				slog.Error("signing key: failed to rotate signing keys", "error", err, "changed", count)
+				continue
+			}
+			if count > 0 {
+				slog.Info("signing key: rotated or retired signing keys", "count", count)
+			}
+		}
+	}
+}
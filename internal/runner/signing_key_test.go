@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSigningKeyRotator struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (m *mockSigningKeyRotator) RotateDue(_ context.Context) (int64, error) {
+	m.calls.Add(1)
+	return 1, m.err
+}
+
+func TestStartSigningKeyRunner_RotatesAndShutdown(t *testing.T) {
+	rotator := &mockSigningKeyRotator{}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		StartSigningKeyRunner(ctx, rotator, 10*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return rotator.calls.Load() >= 1
+	}, 2*time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+}
+
+func TestStartSigningKeyRunner_ErrorContinues(t *testing.T) {
+	rotator := &mockSigningKeyRotator{err: errors.New("db down")}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		StartSigningKeyRunner(ctx, rotator, 10*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return rotator.calls.Load() >= 2
+	}, 2*time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+}
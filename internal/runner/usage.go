@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/maintainerd/auth/internal/metrics"
+)
+
+// DefaultUsageInterval is how often buffered tenant usage is written to the
+// database.
+const DefaultUsageInterval = time.Minute
+
+// UsageWorker names the tenant usage runner in worker metrics.
+const UsageWorker = "tenant_usage"
+
+// UsageFlusher is the subset of UsageService that the usage runner needs.
+// Defined here to avoid an import cycle (service ↔ runner).
+type UsageFlusher interface {
+	FlushUsage(ctx context.Context) (int64, error)
+}
+
+// StartUsageRunner starts a background loop that periodically moves the
+// tenant usage metered in Redis to the database. It respects context
+// cancellation for graceful shutdown.
+func StartUsageRunner(ctx context.Context, flusher UsageFlusher, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultUsageInterval
+	}
+
+	slog.Info("tenant usage: starting usage flush runner",
+		"interval_seconds", int(interval.Seconds()),
+	)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Write what the last interval buffered before exiting.
+			if _, err := flusher.FlushUsage(context.WithoutCancel(ctx)); err != nil {
+				slog.Error("tenant usage: failed final flush", "error", err)
+			}
+			slog.Info("tenant usage: shutting down")
+			return
+		case <-ticker.C:
+			start := time.Now()
+			count, err := flusher.FlushUsage(ctx)
+			metrics.ObserveWorkerRun(UsageWorker, count, err, time.Since(start))
+			if err != nil {
+				slog.Error("tenant usage: failed to flush usage", "error", err, "flushed", count)
+			}
+		}
+	}
+}
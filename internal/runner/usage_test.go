@@ -0,0 +1,49 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartUsageRunner_FlushesAndShutdown(t *testing.T) {
+	flusher := &mockAPIKeyUsageFlusher{}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		StartUsageRunner(ctx, flusher, 10*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return flusher.calls.Load() >= 1
+	}, 2*time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+
+	// Shutdown flushes once more.
+	assert.GreaterOrEqual(t, flusher.calls.Load(), int32(2))
+}
+
+func TestStartUsageRunner_ErrorContinues(t *testing.T) {
+	flusher := &mockAPIKeyUsageFlusher{err: errors.New("db down")}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		StartUsageRunner(ctx, flusher, 10*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return flusher.calls.Load() >= 2
+	}, 2*time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,56 @@
+package runner
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/maintainerd/auth/internal/metrics"
+)
+
+// DefaultUserPurgeInterval is how often the user purge job runs.
+const DefaultUserPurgeInterval = time.Hour
+
+// UserPurgeWorker names the user purge runner in worker metrics.
+const UserPurgeWorker = "user_purge"
+
+// UserPurger is the subset of UserService that the purge runner needs.
+// Defined here to avoid an import cycle (service ↔ runner).
+type UserPurger interface {
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+// StartUserPurgeRunner starts a background loop that periodically erases
+// soft-deleted users whose tenant retention period has elapsed. It respects
+// context cancellation for graceful shutdown.
+func StartUserPurgeRunner(ctx context.Context, purger UserPurger, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultUserPurgeInterval
+	}
+
+	slog.Info("user purge: starting deleted user purge runner",
+		"interval_minutes", int(interval.Minutes()),
+	)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("user purge: shutting down")
+			return
+		case <-ticker.C:
+			start := time.Now()
+			count, err := purger.PurgeExpired(ctx)
+			metrics.ObserveWorkerRun(UserPurgeWorker, count, err, time.Since(start))
+			if err != nil {
+				slog.Error("user purge: failed to purge deleted users", "error", err, "purged", count)
+				continue
+			}
+			if count > 0 {
+				slog.Info("user purge: purged deleted users", "count", count)
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockUserPurger struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (m *mockUserPurger) PurgeExpired(_ context.Context) (int64, error) {
+	m.calls.Add(1)
+	return 1, m.err
+}
+
+func TestStartUserPurgeRunner_PurgesAndShutdown(t *testing.T) {
+	purger := &mockUserPurger{}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		StartUserPurgeRunner(ctx, purger, 10*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return purger.calls.Load() >= 1
+	}, 2*time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+}
+
+func TestStartUserPurgeRunner_ErrorContinues(t *testing.T) {
+	purger := &mockUserPurger{err: errors.New("db down")}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		StartUserPurgeRunner(ctx, purger, 10*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return purger.calls.Load() >= 2
+	}, 2*time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/maintainerd/auth/internal/metrics"
+)
+
+// DefaultWebhookDispatchInterval is how often the webhook dispatcher queues
+// new events and sends due deliveries.
+const DefaultWebhookDispatchInterval = 10 * time.Second
+
+// Worker names of the two webhook dispatch steps in worker metrics.
+const (
+	WebhookEnqueueWorker  = "webhook_enqueue"
+	WebhookDeliveryWorker = "webhook_delivery"
+)
+
+// WebhookDispatcher is the subset of WebhookDeliveryService that the
+// dispatch runner needs. Defined here to avoid an import cycle
+// (service ↔ runner).
+type WebhookDispatcher interface {
+	Enqueue(ctx context.Context) (int64, error)
+	DeliverDue(ctx context.Context) (int64, error)
+}
+
+// StartWebhookDispatchRunner starts a background loop that queues events for
+// subscribed webhook endpoints and delivers them, retrying failed deliveries
+// once their backoff has elapsed. It respects context cancellation for
+// graceful shutdown.
+func StartWebhookDispatchRunner(ctx context.Context, dispatcher WebhookDispatcher, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultWebhookDispatchInterval
+	}
+
+	slog.Info("webhook dispatch: starting webhook dispatch runner",
+		"interval_seconds", int(interval.Seconds()),
+	)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("webhook dispatch: shutting down")
+			return
+		case <-ticker.C:
+			start := time.Now()
+			queued, err := dispatcher.Enqueue(ctx)
+			metrics.ObserveWorkerRun(WebhookEnqueueWorker, queued, err, time.Since(start))
+			if err != nil {
+				// Deliveries already queued are still sent.
+				slog.Error("webhook dispatch: failed to queue events", "error", err, "queued", queued)
+			}
+			start = time.Now()
+			attempted, err := dispatcher.DeliverDue(ctx)
+			metrics.ObserveWorkerRun(WebhookDeliveryWorker, attempted, err, time.Since(start))
+			if err != nil {
+				slog.Error("webhook dispatch: failed to deliver events", "error", err, "attempted", attempted)
+				continue
+			}
+			if queued > 0 || attempted > 0 {
+				slog.Debug("webhook dispatch: dispatched events", "queued", queued, "attempted", attempted)
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockWebhookDispatcher struct {
+	enqueueCalls atomic.Int32
+	deliverCalls atomic.Int32
+	enqueueErr   error
+}
+
+func (m *mockWebhookDispatcher) Enqueue(_ context.Context) (int64, error) {
+	m.enqueueCalls.Add(1)
+	return 1, m.enqueueErr
+}
+
+func (m *mockWebhookDispatcher) DeliverDue(_ context.Context) (int64, error) {
+	m.deliverCalls.Add(1)
+	return 1, nil
+}
+
+func TestStartWebhookDispatchRunner_DispatchesAndShutdown(t *testing.T) {
+	dispatcher := &mockWebhookDispatcher{}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		StartWebhookDispatchRunner(ctx, dispatcher, 10*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return dispatcher.enqueueCalls.Load() >= 1 && dispatcher.deliverCalls.Load() >= 1
+	}, 2*time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+}
+
+func TestStartWebhookDispatchRunner_DeliversWhenEnqueueFails(t *testing.T) {
+	dispatcher := &mockWebhookDispatcher{enqueueErr: errors.New("db down")}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		StartWebhookDispatchRunner(ctx, dispatcher, 10*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return dispatcher.deliverCalls.Load() >= 2
+	}, 2*time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+}
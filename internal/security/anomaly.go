@@ -0,0 +1,79 @@
+package security
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"strings"
+	"time"
+)
+
+// DefaultMaxTravelSpeedKmh is the fastest a user is assumed to travel
+// between two logins, about the speed of a commercial flight.
+const DefaultMaxTravelSpeedKmh = 1000
+
+// impossibleTravelMinDistanceKm is the distance below which two logins never
+// count as impossible travel. GeoIP locations are only city accurate, and
+// mobile users switch between nearby networks all the time.
+const impossibleTravelMinDistanceKm = 500
+
+const earthRadiusKm = 6371
+
+// GeoLocation is where an IP address is located. Country is the ISO 3166-1
+// alpha-2 code. ASN and ASOrganization name the network the address belongs
+// to and are zero when unknown.
+type GeoLocation struct {
+	Country        string
+	City           string
+	Latitude       float64
+	Longitude      float64
+	ASN            uint
+	ASOrganization string
+}
+
+// HasCoordinates reports whether the location has a position. Country-level
+// databases only know the country, and leave both coordinates zero.
+func (l GeoLocation) HasCoordinates() bool {
+	return l.Latitude != 0 || l.Longitude != 0
+}
+
+// GeoLocator resolves IP addresses to locations. Locate returns nil when the
+// address is unknown, such as a private address.
+type GeoLocator interface {
+	Locate(ctx context.Context, ip string) (*GeoLocation, error)
+}
+
+// DeviceHash returns the fingerprint of the device a request comes from. It
+// is the hex SHA-256 of the normalized user agent, so the raw header is not
+// needed to recognize the device again.
+func DeviceHash(userAgent string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(userAgent))))
+	return hex.EncodeToString(sum[:])
+}
+
+// DistanceKm returns the great-circle distance between two locations using
+// the haversine formula.
+func DistanceKm(a, b GeoLocation) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// IsImpossibleTravel reports whether getting from one login location to the
+// next within elapsed would need a speed above maxSpeedKmh. Logins less than
+// 500 km apart are never impossible travel.
+func IsImpossibleTravel(from, to GeoLocation, elapsed time.Duration, maxSpeedKmh float64) bool {
+	distance := DistanceKm(from, to)
+	if distance < impossibleTravelMinDistanceKm {
+		return false
+	}
+	hours := elapsed.Hours()
+	if hours <= 0 {
+		return true
+	}
+	return distance/hours > maxSpeedKmh
+}
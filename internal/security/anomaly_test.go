@@ -0,0 +1,51 @@
+package security
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	newYork = GeoLocation{Country: "US", City: "New York", Latitude: 40.7128, Longitude: -74.0060}
+	tokyo   = GeoLocation{Country: "JP", City: "Tokyo", Latitude: 35.6762, Longitude: 139.6503}
+	newark  = GeoLocation{Country: "US", City: "Newark", Latitude: 40.7357, Longitude: -74.1724}
+)
+
+func TestDeviceHash(t *testing.T) {
+	a := DeviceHash("Mozilla/5.0 (Macintosh) Firefox/119.0")
+	assert.Len(t, a, 64)
+	assert.Equal(t, a, DeviceHash("  mozilla/5.0 (macintosh) firefox/119.0 "), "case and surrounding space are ignored")
+	assert.NotEqual(t, a, DeviceHash("Mozilla/5.0 (Windows NT 10.0) Chrome/120.0"))
+}
+
+func TestDistanceKm(t *testing.T) {
+	assert.InDelta(t, 10850, DistanceKm(newYork, tokyo), 50)
+	assert.InDelta(t, 0, DistanceKm(tokyo, tokyo), 0.001)
+}
+
+func TestGeoLocation_HasCoordinates(t *testing.T) {
+	assert.True(t, tokyo.HasCoordinates())
+	assert.False(t, GeoLocation{Country: "JP", ASN: 2516}.HasCoordinates())
+}
+
+func TestIsImpossibleTravel(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    GeoLocation
+		to      GeoLocation
+		elapsed time.Duration
+		want    bool
+	}{
+		{"half an hour from New York to Tokyo", newYork, tokyo, 30 * time.Minute, true},
+		{"a day from New York to Tokyo", newYork, tokyo, 24 * time.Hour, false},
+		{"same time in two countries", newYork, tokyo, 0, true},
+		{"nearby cities at once", newYork, newark, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsImpossibleTravel(tt.from, tt.to, tt.elapsed, DefaultMaxTravelSpeedKmh))
+		})
+	}
+}
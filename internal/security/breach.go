@@ -0,0 +1,92 @@
+package security
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultHIBPRangeURL is the HaveIBeenPwned Pwned Passwords range endpoint.
+// The first five characters of the password's SHA-1 hash are appended to it.
+const DefaultHIBPRangeURL = "https://api.pwnedpasswords.com/range/"
+
+// hibpPrefixLen is the number of hash characters sent to the range API. Only
+// this prefix leaves the process (k-anonymity).
+const hibpPrefixLen = 5
+
+// BreachedPasswordChecker reports whether a password is known to have been
+// exposed in a data breach.
+type BreachedPasswordChecker interface {
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
+
+// HIBPChecker queries the HaveIBeenPwned range API. A password counts as
+// breached once it has been seen at least threshold times.
+type HIBPChecker struct {
+	rangeURL  string
+	threshold int
+	client    *http.Client
+}
+
+// NewHIBPChecker creates a checker against rangeURL. A threshold below 1 is
+// treated as 1.
+func NewHIBPChecker(rangeURL string, threshold int, timeout time.Duration) *HIBPChecker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &HIBPChecker{
+		rangeURL:  rangeURL,
+		threshold: threshold,
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+// IsBreached hashes password with SHA-1, sends the first five hex characters
+// to the range API and looks for the remaining suffix in the response. The
+// full hash and the password never leave the process. Padding is requested so
+// the response size does not reveal the prefix's popularity.
+func (c *HIBPChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:hibpPrefixLen], hash[hibpPrefixLen:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.rangeURL+prefix, nil)
+	if err != nil {
+		return false, fmt.Errorf("build breach lookup request: %w", err)
+	}
+	req.Header.Set("Add-Padding", "true")
+	req.Header.Set("User-Agent", "maintainerd-auth")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("breach lookup failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("breach lookup failed: unexpected status %d", res.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		candidate, countStr, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok || !strings.EqualFold(candidate, suffix) {
+			continue
+		}
+		count, err := strconv.Atoi(countStr)
+		if err != nil {
+			return false, fmt.Errorf("breach lookup failed: malformed count %q", countStr)
+		}
+		return count >= c.threshold, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("breach lookup failed: %w", err)
+	}
+	return false, nil
+}
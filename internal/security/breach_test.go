@@ -0,0 +1,91 @@
+package security
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// SHA-1("password") = 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+const (
+	passwordHashPrefix = "5BAA6"
+	passwordHashSuffix = "1E4C9B93F3F0682250B6CF8331B7EE68FD8"
+)
+
+func newHIBPServer(t *testing.T, status int, body string) (*httptest.Server, *string) {
+	t.Helper()
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		assert.Equal(t, "true", r.Header.Get("Add-Padding"))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &gotPath
+}
+
+func TestHIBPChecker_IsBreached(t *testing.T) {
+	ctx := context.Background()
+	body := "0018A45C4D1DEF81644B54AB7F969B88D65:3\r\n" +
+		passwordHashSuffix + ":42\r\n" +
+		"00D4F6E8FA6EECAD2A3AA415EEC418D38EC:0\r\n"
+
+	t.Run("only the prefix is sent", func(t *testing.T) {
+		srv, gotPath := newHIBPServer(t, http.StatusOK, body)
+		_, err := NewHIBPChecker(srv.URL+"/range/", 1, time.Second).IsBreached(ctx, "password")
+		require.NoError(t, err)
+		assert.Equal(t, "/range/"+passwordHashPrefix, *gotPath)
+	})
+
+	t.Run("count at threshold → breached", func(t *testing.T) {
+		srv, _ := newHIBPServer(t, http.StatusOK, body)
+		breached, err := NewHIBPChecker(srv.URL+"/", 42, time.Second).IsBreached(ctx, "password")
+		require.NoError(t, err)
+		assert.True(t, breached)
+	})
+
+	t.Run("count below threshold → not breached", func(t *testing.T) {
+		srv, _ := newHIBPServer(t, http.StatusOK, body)
+		breached, err := NewHIBPChecker(srv.URL+"/", 43, time.Second).IsBreached(ctx, "password")
+		require.NoError(t, err)
+		assert.False(t, breached)
+	})
+
+	t.Run("suffix absent → not breached", func(t *testing.T) {
+		srv, _ := newHIBPServer(t, http.StatusOK, "0018A45C4D1DEF81644B54AB7F969B88D65:3\r\n")
+		breached, err := NewHIBPChecker(srv.URL+"/", 1, time.Second).IsBreached(ctx, "password")
+		require.NoError(t, err)
+		assert.False(t, breached)
+	})
+
+	t.Run("padding entry never counts", func(t *testing.T) {
+		srv, _ := newHIBPServer(t, http.StatusOK, passwordHashSuffix+":0\r\n")
+		breached, err := NewHIBPChecker(srv.URL+"/", 0, time.Second).IsBreached(ctx, "password")
+		require.NoError(t, err)
+		assert.False(t, breached)
+	})
+
+	t.Run("non-200 → error", func(t *testing.T) {
+		srv, _ := newHIBPServer(t, http.StatusServiceUnavailable, "")
+		_, err := NewHIBPChecker(srv.URL+"/", 1, time.Second).IsBreached(ctx, "password")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "503")
+	})
+
+	t.Run("malformed count → error", func(t *testing.T) {
+		srv, _ := newHIBPServer(t, http.StatusOK, passwordHashSuffix+":lots\r\n")
+		_, err := NewHIBPChecker(srv.URL+"/", 1, time.Second).IsBreached(ctx, "password")
+		require.Error(t, err)
+	})
+
+	t.Run("unreachable → error", func(t *testing.T) {
+		_, err := NewHIBPChecker("http://127.0.0.1:1/", 1, time.Second).IsBreached(ctx, "password")
+		require.Error(t, err)
+	})
+}
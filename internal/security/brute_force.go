@@ -0,0 +1,105 @@
+package security
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Subnet sizes failures are counted per. A /24 is the usual IPv4 allocation
+// of a single host network; a /64 is the smallest IPv6 subnet routed to one.
+const (
+	ipv4SubnetBits = 24
+	ipv6SubnetBits = 64
+)
+
+func subnetFailureKey(subnet string) string {
+	return "bf:subnet:" + subnet
+}
+
+func loginIdentifiersKey(ip string) string {
+	return "bf:identifiers:" + ip
+}
+
+// SubnetOf returns the /24 (IPv4) or /64 (IPv6) network of ip in CIDR
+// notation, or "" when ip is not an IP address.
+func SubnetOf(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return (&net.IPNet{IP: v4.Mask(net.CIDRMask(ipv4SubnetBits, 32)), Mask: net.CIDRMask(ipv4SubnetBits, 32)}).String()
+	}
+	return (&net.IPNet{IP: parsed.Mask(net.CIDRMask(ipv6SubnetBits, 128)), Mask: net.CIDRMask(ipv6SubnetBits, 128)}).String()
+}
+
+// IPMatches reports whether ip is the address, or falls in the CIDR range,
+// of pattern.
+func IPMatches(pattern, ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	if strings.Contains(pattern, "/") {
+		_, network, err := net.ParseCIDR(pattern)
+		return err == nil && network.Contains(parsed)
+	}
+	rule := net.ParseIP(pattern)
+	return rule != nil && rule.Equal(parsed)
+}
+
+// RecordSubnetFailure counts a failed login from the subnet within window and
+// returns the count so far. It returns 0 without a rate limiter.
+func RecordSubnetFailure(subnet string, window time.Duration) int64 {
+	_, span := otel.Tracer("security").Start(context.Background(), "security.record_subnet_failure")
+	defer span.End()
+	span.SetAttributes(attribute.String("subnet", subnet))
+
+	if rateLimiterClient == nil || subnet == "" {
+		return 0
+	}
+	ctx := context.Background()
+	key := subnetFailureKey(subnet)
+	pipe := rateLimiterClient.Pipeline()
+	incr := pipe.Incr(ctx, key)
+	// NX keeps the window fixed from the first failure
+	pipe.ExpireNX(ctx, key, window)
+	_, _ = pipe.Exec(ctx)
+	return incr.Val()
+}
+
+// RecordLoginIdentifier remembers a username or email that failed to sign in
+// from ip within window and returns how many distinct ones did. Many
+// accounts failing from one address is the mark of credential stuffing. It
+// returns 0 without a rate limiter.
+func RecordLoginIdentifier(ip, identifier string, window time.Duration) int64 {
+	_, span := otel.Tracer("security").Start(context.Background(), "security.record_login_identifier")
+	defer span.End()
+	span.SetAttributes(attribute.String("client_ip", ip))
+
+	if rateLimiterClient == nil || ip == "" {
+		return 0
+	}
+	ctx := context.Background()
+	key := loginIdentifiersKey(ip)
+	pipe := rateLimiterClient.Pipeline()
+	pipe.SAdd(ctx, key, strings.ToLower(identifier))
+	pipe.ExpireNX(ctx, key, window)
+	count := pipe.SCard(ctx, key)
+	_, _ = pipe.Exec(ctx)
+	return count.Val()
+}
+
+// ResetBruteForceCounters clears the failure counts of the subnet and ip once
+// they have been blocked, so the block is not placed twice.
+func ResetBruteForceCounters(subnet, ip string) {
+	if rateLimiterClient == nil {
+		return
+	}
+	_ = rateLimiterClient.Del(context.Background(), subnetFailureKey(subnet), loginIdentifiersKey(ip)).Err()
+}
@@ -0,0 +1,49 @@
+package security
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubnetOf(t *testing.T) {
+	assert.Equal(t, "203.0.113.0/24", SubnetOf("203.0.113.77"))
+	assert.Equal(t, "2001:db8:1:2::/64", SubnetOf("2001:db8:1:2:aaaa::1"))
+	assert.Empty(t, SubnetOf("not-an-ip"))
+}
+
+func TestIPMatches(t *testing.T) {
+	assert.True(t, IPMatches("203.0.113.7", "203.0.113.7"))
+	assert.False(t, IPMatches("203.0.113.7", "203.0.113.8"))
+	assert.True(t, IPMatches("203.0.113.0/24", "203.0.113.200"))
+	assert.False(t, IPMatches("203.0.113.0/24", "203.0.114.1"))
+	assert.False(t, IPMatches("203.0.113.0/99", "203.0.113.1"))
+	assert.False(t, IPMatches("203.0.113.7", ""))
+}
+
+func TestBruteForceCounters_NilClient(t *testing.T) {
+	saveAndRestoreRateLimiter(t)
+	InitRateLimiter(nil)
+	assert.Zero(t, RecordSubnetFailure("203.0.113.0/24", time.Hour))
+	assert.Zero(t, RecordLoginIdentifier("203.0.113.7", "jane", time.Hour))
+	assert.NotPanics(t, func() { ResetBruteForceCounters("203.0.113.0/24", "203.0.113.7") })
+}
+
+func TestBruteForceCounters(t *testing.T) {
+	saveAndRestoreRateLimiter(t)
+	mr, cli := newMiniredisClient(t)
+	InitRateLimiter(cli)
+
+	assert.Equal(t, int64(1), RecordSubnetFailure("203.0.113.0/24", time.Hour))
+	assert.Equal(t, int64(2), RecordSubnetFailure("203.0.113.0/24", time.Hour))
+	assert.Equal(t, time.Hour, mr.TTL(subnetFailureKey("203.0.113.0/24")))
+
+	assert.Equal(t, int64(1), RecordLoginIdentifier("203.0.113.7", "jane", time.Hour))
+	assert.Equal(t, int64(1), RecordLoginIdentifier("203.0.113.7", "JANE", time.Hour), "identifiers are case-insensitive")
+	assert.Equal(t, int64(2), RecordLoginIdentifier("203.0.113.7", "john", time.Hour))
+
+	ResetBruteForceCounters("203.0.113.0/24", "203.0.113.7")
+	assert.False(t, mr.Exists(subnetFailureKey("203.0.113.0/24")))
+	assert.False(t, mr.Exists(loginIdentifiersKey("203.0.113.7")))
+}
@@ -2,19 +2,105 @@ package security
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// ErrUnsupportedPasswordHash is returned for a stored password hash that is
+// neither bcrypt nor argon2id in PHC string format.
+var ErrUnsupportedPasswordHash = errors.New("unsupported password hash")
+
+// Password hash algorithms selectable through PasswordHashParams.
+const (
+	PasswordHashBcrypt   = "bcrypt"
+	PasswordHashArgon2id = "argon2id"
+)
+
+// Bounds accepted by PasswordHashParams.Validate. The upper bounds keep a
+// single sign in from tying up a CPU or the memory of the server.
+const (
+	MinBcryptCost         = 10
+	MaxBcryptCost         = 14
+	MinArgon2idMemoryKiB  = 8 * 1024
+	MaxArgon2idMemoryKiB  = 256 * 1024
+	MaxArgon2idIterations = 10
+	MaxArgon2idThreads    = 16
+)
+
+const (
+	argon2idSaltLength = 16
+	argon2idKeyLength  = 32
+)
+
+// PasswordHashParams selects the algorithm and cost new password hashes are
+// computed with. Only the fields of the chosen algorithm are used.
+type PasswordHashParams struct {
+	Algorithm          string
+	BcryptCost         int
+	Argon2idMemoryKiB  uint32
+	Argon2idIterations uint32
+	Argon2idThreads    uint8
+}
+
+// DefaultPasswordHashParams returns bcrypt with the default cost, along with
+// the argon2id parameters recommended by OWASP for when argon2id is chosen.
+func DefaultPasswordHashParams() PasswordHashParams {
+	return PasswordHashParams{
+		Algorithm:          PasswordHashBcrypt,
+		BcryptCost:         bcrypt.DefaultCost,
+		Argon2idMemoryKiB:  19 * 1024,
+		Argon2idIterations: 2,
+		Argon2idThreads:    1,
+	}
+}
+
+// Validate checks that the algorithm is known and its cost is within bounds.
+func (p PasswordHashParams) Validate() error {
+	switch p.Algorithm {
+	case PasswordHashBcrypt:
+		if p.BcryptCost < MinBcryptCost || p.BcryptCost > MaxBcryptCost {
+			return fmt.Errorf("bcrypt cost must be between %d and %d", MinBcryptCost, MaxBcryptCost)
+		}
+	case PasswordHashArgon2id:
+		if p.Argon2idMemoryKiB < MinArgon2idMemoryKiB || p.Argon2idMemoryKiB > MaxArgon2idMemoryKiB {
+			return fmt.Errorf("argon2id memory must be between %d and %d KiB", MinArgon2idMemoryKiB, MaxArgon2idMemoryKiB)
+		}
+		if p.Argon2idIterations < 1 || p.Argon2idIterations > MaxArgon2idIterations {
+			return fmt.Errorf("argon2id iterations must be between 1 and %d", MaxArgon2idIterations)
+		}
+		if p.Argon2idThreads < 1 || p.Argon2idThreads > MaxArgon2idThreads {
+			return fmt.Errorf("argon2id parallelism must be between 1 and %d", MaxArgon2idThreads)
+		}
+	default:
+		return fmt.Errorf("hash algorithm must be %q or %q", PasswordHashBcrypt, PasswordHashArgon2id)
+	}
+	return nil
+}
+
 // HashPassword hashes a password using bcrypt with the default cost.
 // Exposed as a function variable so tests can inject errors.
 var HashPassword = func(password []byte) ([]byte, error) {
+	return HashPasswordWithParams(password, DefaultPasswordHashParams())
+}
+
+// HashPasswordWithParams hashes a password with the algorithm and cost of
+// params. Argon2id hashes are written in PHC string format so that their
+// parameters travel with the hash. Exposed as a function variable so tests
+// can inject errors.
+var HashPasswordWithParams = func(password []byte, params PasswordHashParams) ([]byte, error) {
 	_, span := otel.Tracer("security").Start(context.Background(), "security.hash_password")
 	defer span.End()
 
-	hash, err := bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
+	hash, err := hashPassword(password, params)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "hash password failed")
@@ -23,3 +109,119 @@ var HashPassword = func(password []byte) ([]byte, error) {
 	span.SetStatus(codes.Ok, "")
 	return hash, nil
 }
+
+func hashPassword(password []byte, params PasswordHashParams) ([]byte, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if params.Algorithm == PasswordHashBcrypt {
+		return bcrypt.GenerateFromPassword(password, params.BcryptCost)
+	}
+
+	salt := make([]byte, argon2idSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key := argon2.IDKey(password, salt, params.Argon2idIterations, params.Argon2idMemoryKiB, params.Argon2idThreads, argon2idKeyLength)
+	return []byte(fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.Argon2idMemoryKiB, params.Argon2idIterations, params.Argon2idThreads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key))), nil
+}
+
+// PasswordNeedsRehash reports whether a stored hash was computed with another
+// algorithm or cost than params asks for, so that it should be replaced the
+// next time the plaintext password is at hand. Hashes that cannot be parsed
+// are reported as needing a rehash.
+func PasswordNeedsRehash(hash []byte, params PasswordHashParams) bool {
+	if strings.HasPrefix(string(hash), "$argon2id$") {
+		if params.Algorithm != PasswordHashArgon2id {
+			return true
+		}
+		stored, err := parseArgon2idHash(string(hash))
+		if err != nil {
+			return true
+		}
+		return stored.memory != params.Argon2idMemoryKiB ||
+			stored.time != params.Argon2idIterations ||
+			stored.threads != params.Argon2idThreads
+	}
+	if params.Algorithm != PasswordHashBcrypt {
+		return true
+	}
+	cost, err := bcrypt.Cost(hash)
+	return err != nil || cost != params.BcryptCost
+}
+
+// ComparePassword reports whether password matches a stored hash, either
+// bcrypt or argon2id in PHC string format ($argon2id$v=19$m=...,t=...,p=...$salt$hash).
+// The algorithm is taken from the hash, so hashes written before a tenant
+// changed algorithm and hashes imported from other systems keep working.
+func ComparePassword(hash, password []byte) error {
+	if strings.HasPrefix(string(hash), "$argon2id$") {
+		params, err := parseArgon2idHash(string(hash))
+		if err != nil {
+			return err
+		}
+		key := argon2.IDKey(password, params.salt, params.time, params.memory, params.threads, uint32(len(params.key)))
+		if subtle.ConstantTimeCompare(key, params.key) != 1 {
+			return bcrypt.ErrMismatchedHashAndPassword
+		}
+		return nil
+	}
+	return bcrypt.CompareHashAndPassword(hash, password)
+}
+
+// ValidatePasswordHash checks that hash is a well-formed bcrypt or argon2id
+// hash that ComparePassword can verify.
+func ValidatePasswordHash(hash string) error {
+	if strings.HasPrefix(hash, "$argon2id$") {
+		_, err := parseArgon2idHash(hash)
+		return err
+	}
+	if strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$") {
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			return fmt.Errorf("%w: %v", ErrUnsupportedPasswordHash, err)
+		}
+		return nil
+	}
+	return ErrUnsupportedPasswordHash
+}
+
+type argon2idParams struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+func parseArgon2idHash(hash string) (*argon2idParams, error) {
+	// "", "argon2id", "v=19", "m=65536,t=3,p=4", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return nil, fmt.Errorf("%w: malformed argon2id hash", ErrUnsupportedPasswordHash)
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, fmt.Errorf("%w: unsupported argon2id version", ErrUnsupportedPasswordHash)
+	}
+
+	p := &argon2idParams{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil {
+		return nil, fmt.Errorf("%w: malformed argon2id parameters", ErrUnsupportedPasswordHash)
+	}
+	if p.memory == 0 || p.time == 0 || p.threads == 0 {
+		return nil, fmt.Errorf("%w: malformed argon2id parameters", ErrUnsupportedPasswordHash)
+	}
+
+	var err error
+	if p.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil || len(p.salt) == 0 {
+		return nil, fmt.Errorf("%w: malformed argon2id salt", ErrUnsupportedPasswordHash)
+	}
+	if p.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(p.key) == 0 {
+		return nil, fmt.Errorf("%w: malformed argon2id key", ErrUnsupportedPasswordHash)
+	}
+	return p, nil
+}
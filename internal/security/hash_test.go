@@ -0,0 +1,197 @@
+package security
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func argon2idHash(password, salt string) string {
+	key := argon2.IDKey([]byte(password), []byte(salt), 1, 64, 1, 32)
+	return fmt.Sprintf("$argon2id$v=%d$m=64,t=1,p=1$%s$%s",
+		argon2.Version,
+		base64.RawStdEncoding.EncodeToString([]byte(salt)),
+		base64.RawStdEncoding.EncodeToString(key))
+}
+
+// ---------------------------------------------------------------------------
+// ComparePassword
+// ---------------------------------------------------------------------------
+
+func TestComparePassword_Bcrypt(t *testing.T) {
+	hash, err := HashPassword([]byte("Secret#123"))
+	require.NoError(t, err)
+
+	assert.NoError(t, ComparePassword(hash, []byte("Secret#123")))
+	assert.ErrorIs(t, ComparePassword(hash, []byte("wrong")), bcrypt.ErrMismatchedHashAndPassword)
+}
+
+func TestComparePassword_Argon2id(t *testing.T) {
+	hash := []byte(argon2idHash("Secret#123", "somesaltvalue"))
+
+	assert.NoError(t, ComparePassword(hash, []byte("Secret#123")))
+	assert.ErrorIs(t, ComparePassword(hash, []byte("wrong")), bcrypt.ErrMismatchedHashAndPassword)
+}
+
+func TestComparePassword_MalformedArgon2id(t *testing.T) {
+	err := ComparePassword([]byte("$argon2id$v=19$m=64,t=1,p=1$salt"), []byte("x"))
+	assert.ErrorIs(t, err, ErrUnsupportedPasswordHash)
+}
+
+// ---------------------------------------------------------------------------
+// ValidatePasswordHash
+// ---------------------------------------------------------------------------
+
+func TestValidatePasswordHash(t *testing.T) {
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("Secret#123"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		hash    string
+		wantErr bool
+	}{
+		{"bcrypt", string(bcryptHash), false},
+		{"argon2id", argon2idHash("Secret#123", "somesaltvalue"), false},
+		{"plaintext", "Secret#123", true},
+		{"md5", "5f4dcc3b5aa765d61d8327deb882cf99", true},
+		{"truncated bcrypt", string(bcryptHash[:20]), true},
+		{"argon2i", "$argon2i$v=19$m=64,t=1,p=1$c2FsdA$a2V5", true},
+		{"wrong version", "$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5", true},
+		{"zero memory", "$argon2id$v=19$m=0,t=1,p=1$c2FsdA$a2V5", true},
+		{"bad salt", "$argon2id$v=19$m=64,t=1,p=1$!!$a2V5", true},
+		{"empty key", "$argon2id$v=19$m=64,t=1,p=1$c2FsdA$", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePasswordHash(tt.hash)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrUnsupportedPasswordHash)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// HashPasswordWithParams
+// ---------------------------------------------------------------------------
+
+func TestHashPasswordWithParams_Argon2id(t *testing.T) {
+	params := DefaultPasswordHashParams()
+	params.Algorithm = PasswordHashArgon2id
+
+	hash, err := HashPasswordWithParams([]byte("Secret#123"), params)
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^\$argon2id\$v=19\$m=19456,t=2,p=1\$[^$]+\$[^$]+$`, string(hash))
+	assert.NoError(t, ValidatePasswordHash(string(hash)))
+	assert.NoError(t, ComparePassword(hash, []byte("Secret#123")))
+	assert.ErrorIs(t, ComparePassword(hash, []byte("wrong")), bcrypt.ErrMismatchedHashAndPassword)
+
+	again, err := HashPasswordWithParams([]byte("Secret#123"), params)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, again, "every hash gets its own salt")
+}
+
+func TestHashPasswordWithParams_BcryptCost(t *testing.T) {
+	params := DefaultPasswordHashParams()
+	params.BcryptCost = 11
+
+	hash, err := HashPasswordWithParams([]byte("Secret#123"), params)
+	require.NoError(t, err)
+
+	cost, err := bcrypt.Cost(hash)
+	require.NoError(t, err)
+	assert.Equal(t, 11, cost)
+	assert.NoError(t, ComparePassword(hash, []byte("Secret#123")))
+}
+
+func TestHashPasswordWithParams_InvalidParams(t *testing.T) {
+	_, err := HashPasswordWithParams([]byte("Secret#123"), PasswordHashParams{Algorithm: "md5"})
+	assert.Error(t, err)
+}
+
+func TestPasswordHashParams_Validate(t *testing.T) {
+	argon := DefaultPasswordHashParams()
+	argon.Algorithm = PasswordHashArgon2id
+
+	tests := []struct {
+		name    string
+		modify  func(p *PasswordHashParams)
+		base    PasswordHashParams
+		wantErr bool
+	}{
+		{"default bcrypt", func(p *PasswordHashParams) {}, DefaultPasswordHashParams(), false},
+		{"default argon2id", func(p *PasswordHashParams) {}, argon, false},
+		{"unknown algorithm", func(p *PasswordHashParams) { p.Algorithm = "scrypt" }, argon, true},
+		{"bcrypt cost too low", func(p *PasswordHashParams) { p.BcryptCost = bcrypt.MinCost }, DefaultPasswordHashParams(), true},
+		{"bcrypt cost too high", func(p *PasswordHashParams) { p.BcryptCost = MaxBcryptCost + 1 }, DefaultPasswordHashParams(), true},
+		{"argon2id memory too low", func(p *PasswordHashParams) { p.Argon2idMemoryKiB = 64 }, argon, true},
+		{"argon2id memory too high", func(p *PasswordHashParams) { p.Argon2idMemoryKiB = MaxArgon2idMemoryKiB + 1 }, argon, true},
+		{"argon2id no iterations", func(p *PasswordHashParams) { p.Argon2idIterations = 0 }, argon, true},
+		{"argon2id no threads", func(p *PasswordHashParams) { p.Argon2idThreads = 0 }, argon, true},
+		{"argon2id ignores bcrypt cost", func(p *PasswordHashParams) { p.BcryptCost = 0 }, argon, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.base
+			tt.modify(&p)
+			if tt.wantErr {
+				assert.Error(t, p.Validate())
+			} else {
+				assert.NoError(t, p.Validate())
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// PasswordNeedsRehash
+// ---------------------------------------------------------------------------
+
+func TestPasswordNeedsRehash(t *testing.T) {
+	bcryptParams := DefaultPasswordHashParams()
+	argonParams := DefaultPasswordHashParams()
+	argonParams.Algorithm = PasswordHashArgon2id
+
+	bcryptHash, err := HashPasswordWithParams([]byte("Secret#123"), bcryptParams)
+	require.NoError(t, err)
+	argonHash, err := HashPasswordWithParams([]byte("Secret#123"), argonParams)
+	require.NoError(t, err)
+
+	higherCost := bcryptParams
+	higherCost.BcryptCost = bcryptParams.BcryptCost + 1
+	moreMemory := argonParams
+	moreMemory.Argon2idMemoryKiB *= 2
+	moreIterations := argonParams
+	moreIterations.Argon2idIterations++
+
+	tests := []struct {
+		name   string
+		hash   []byte
+		params PasswordHashParams
+		want   bool
+	}{
+		{"bcrypt current", bcryptHash, bcryptParams, false},
+		{"bcrypt to higher cost", bcryptHash, higherCost, true},
+		{"bcrypt to argon2id", bcryptHash, argonParams, true},
+		{"argon2id current", argonHash, argonParams, false},
+		{"argon2id to bcrypt", argonHash, bcryptParams, true},
+		{"argon2id to more memory", argonHash, moreMemory, true},
+		{"argon2id to more iterations", argonHash, moreIterations, true},
+		{"imported argon2id parameters", []byte(argon2idHash("Secret#123", "somesaltvalue")), argonParams, true},
+		{"unparsable hash", []byte("not-a-hash"), bcryptParams, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, PasswordNeedsRehash(tt.hash, tt.params))
+		})
+	}
+}
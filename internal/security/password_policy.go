@@ -0,0 +1,86 @@
+package security
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxPasswordLength is the longest password a PasswordPolicy may allow.
+const MaxPasswordLength = 128
+
+// weakPasswordPatterns are rejected anywhere in a password, whatever the
+// policy.
+var weakPasswordPatterns = []string{
+	"password", "123456", "password123", "admin", "qwerty",
+	"letmein", "welcome", "monkey", "dragon", "master",
+}
+
+// PasswordPolicy lists the rules a new password must meet. Lengths count
+// characters, not bytes.
+type PasswordPolicy struct {
+	MinLength        int
+	MaxLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireNumber    bool
+	RequireSymbol    bool
+}
+
+// DefaultPasswordPolicy returns the policy of ValidatePasswordStrength: 8 to
+// 128 characters with an uppercase letter, a lowercase letter, a digit and a
+// special character.
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:        8,
+		MaxLength:        MaxPasswordLength,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireNumber:    true,
+		RequireSymbol:    true,
+	}
+}
+
+// Validate checks that the policy's lengths can be met.
+func (p PasswordPolicy) Validate() error {
+	if p.MinLength < 1 {
+		return fmt.Errorf("minimum password length must be at least 1")
+	}
+	if p.MaxLength < p.MinLength || p.MaxLength > MaxPasswordLength {
+		return fmt.Errorf("maximum password length must be between the minimum length and %d", MaxPasswordLength)
+	}
+	return nil
+}
+
+// Check returns an error describing the first rule password breaks, or nil.
+// Complies with SOC2 CC6.1 and ISO27001 A.9.4.3
+func (p PasswordPolicy) Check(password string) error {
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		return fmt.Errorf("password must be at least %d characters long", p.MinLength)
+	}
+	if length > p.MaxLength {
+		return fmt.Errorf("password must not exceed %d characters", p.MaxLength)
+	}
+
+	if p.RequireUppercase && !reUpper.MatchString(password) {
+		return fmt.Errorf("password must contain at least one uppercase letter")
+	}
+	if p.RequireLowercase && !reLower.MatchString(password) {
+		return fmt.Errorf("password must contain at least one lowercase letter")
+	}
+	if p.RequireNumber && !reDigit.MatchString(password) {
+		return fmt.Errorf("password must contain at least one digit")
+	}
+	if p.RequireSymbol && !reSpecial.MatchString(password) {
+		return fmt.Errorf("password must contain at least one special character")
+	}
+
+	lowerPassword := strings.ToLower(password)
+	for _, weak := range weakPasswordPatterns {
+		if strings.Contains(lowerPassword, weak) {
+			return fmt.Errorf("password contains common weak patterns")
+		}
+	}
+	return nil
+}
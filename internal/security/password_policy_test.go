@@ -0,0 +1,52 @@
+package security
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	assert.NoError(t, DefaultPasswordPolicy().Validate())
+	assert.NoError(t, PasswordPolicy{MinLength: 12, MaxLength: 12}.Validate())
+	assert.Error(t, PasswordPolicy{MinLength: 0, MaxLength: 64}.Validate())
+	assert.Error(t, PasswordPolicy{MinLength: 16, MaxLength: 12}.Validate())
+	assert.Error(t, PasswordPolicy{MinLength: 8, MaxLength: MaxPasswordLength + 1}.Validate())
+}
+
+func TestPasswordPolicy_Check(t *testing.T) {
+	t.Run("lengths come from the policy", func(t *testing.T) {
+		p := PasswordPolicy{MinLength: 12, MaxLength: 16}
+		err := p.Check("short pass")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "at least 12 characters")
+
+		err = p.Check(strings.Repeat("x", 17))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "16 characters")
+
+		assert.NoError(t, p.Check("long enough pass"))
+	})
+
+	t.Run("lengths count characters", func(t *testing.T) {
+		p := PasswordPolicy{MinLength: 4, MaxLength: 4}
+		assert.NoError(t, p.Check("äöüß"), "8 bytes but 4 characters")
+	})
+
+	t.Run("composition rules apply only when required", func(t *testing.T) {
+		assert.NoError(t, PasswordPolicy{MinLength: 8, MaxLength: 64}.Check("correct horse battery staple"))
+
+		p := PasswordPolicy{MinLength: 8, MaxLength: 64, RequireNumber: true}
+		err := p.Check("correct horse battery staple")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "digit")
+	})
+
+	t.Run("weak patterns are always rejected", func(t *testing.T) {
+		err := PasswordPolicy{MinLength: 8, MaxLength: 64}.Check("my qwerty keyboard")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "weak")
+	})
+}
@@ -0,0 +1,99 @@
+package security
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SecretFinding is a string value in a JSON document that looks like a
+// credential. The matched value itself is never kept.
+type SecretFinding struct {
+	// Path locates the value, e.g. "headers.Authorization" or "keys[0]".
+	// It is empty when the document itself is a string.
+	Path string
+	// Kind names the kind of secret, e.g. "AWS access key".
+	Kind string
+	// Block is set for high-confidence patterns. Weaker matches, which
+	// can be sample or already expired values, should only be reported.
+	Block bool
+}
+
+type secretPattern struct {
+	kind  string
+	re    *regexp.Regexp
+	block bool
+}
+
+// secretPatterns are checked in order; a value is reported once, under the
+// first pattern it matches.
+var secretPatterns = []secretPattern{
+	{"private key", regexp.MustCompile(`-----BEGIN (?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?-----`), true},
+	{"AWS access key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`), true},
+	{"GitHub token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{40,})\b`), true},
+	{"Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`), true},
+	{"Stripe secret key", regexp.MustCompile(`\b[rs]k_live_[A-Za-z0-9]{20,}`), true},
+	{"bearer token", regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]{20,}=*`), true},
+	{"JSON web token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`), false},
+}
+
+// ScanJSONForSecrets walks a JSON document and reports every string value
+// that matches a known secret pattern. Findings are ordered by path. Invalid
+// JSON yields no findings; syntax is left to the caller's own validation.
+func ScanJSONForSecrets(raw []byte) []SecretFinding {
+	if len(raw) == 0 {
+		return nil
+	}
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil
+	}
+	var findings []SecretFinding
+	scanSecretValue(doc, "", &findings)
+	return findings
+}
+
+func scanSecretValue(v any, path string, findings *[]SecretFinding) {
+	switch val := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := k
+			if path != "" {
+				child = path + "." + k
+			}
+			scanSecretValue(val[k], child, findings)
+		}
+	case []any:
+		for i, item := range val {
+			scanSecretValue(item, path+"["+strconv.Itoa(i)+"]", findings)
+		}
+	case string:
+		for _, p := range secretPatterns {
+			if p.re.MatchString(val) {
+				*findings = append(*findings, SecretFinding{Path: path, Kind: p.kind, Block: p.block})
+				return
+			}
+		}
+	}
+}
+
+// maskedSecretVisible is how many trailing characters MaskSecret keeps, so
+// an admin can tell secrets apart without seeing them.
+const maskedSecretVisible = 4
+
+// MaskSecret replaces all but the last four characters of a secret with
+// asterisks. Secrets shorter than 16 characters are masked entirely, as
+// their last characters would give away too much of them.
+func MaskSecret(secret string) string {
+	if len(secret) < 16 {
+		return strings.Repeat("*", len(secret))
+	}
+	return strings.Repeat("*", len(secret)-maskedSecretVisible) + secret[len(secret)-maskedSecretVisible:]
+}
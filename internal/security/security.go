@@ -46,7 +46,6 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
-	"log/slog"
 	"net"
 	"regexp"
 	"slices"
@@ -54,6 +53,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/maintainerd/auth/internal/logging"
 	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -75,7 +75,7 @@ const (
 
 // Session Security Constants (SOC2 CC6.3 - Logical Access Controls)
 const (
-	MaxConcurrentSessions = 5 // Maximum concurrent sessions per user
+	MaxConcurrentSessions = 5 // Default maximum concurrent sessions per user
 )
 
 // ============================================================================
@@ -88,6 +88,7 @@ const (
 // Complies with SOC2 CC7.2 and ISO27001 A.12.4.1
 type SecurityEvent struct {
 	EventType string    `json:"event_type"`
+	TenantID  int64     `json:"tenant_id,omitempty"`
 	UserID    string    `json:"user_id,omitempty"`
 	ClientID  string    `json:"client_id,omitempty"`
 	ClientIP  string    `json:"client_ip,omitempty"`
@@ -121,7 +122,7 @@ func LogSecurityEvent(event SecurityEvent) {
 		event.Severity = determineSeverity(event.EventType)
 	}
 
-	slog.Info("security_event",
+	args := []any{
 		"event_type", event.EventType,
 		"severity", event.Severity,
 		"client_ip", event.ClientIP,
@@ -131,7 +132,60 @@ func LogSecurityEvent(event SecurityEvent) {
 		"method", event.Method,
 		"details", event.Details,
 		"timestamp", event.Timestamp.Format(time.RFC3339),
-	)
+	}
+	location := locateSecurityEvent(event.ClientIP)
+	if location != nil {
+		args = append(args,
+			"country", location.Country,
+			"city", location.City,
+			"asn", location.ASN,
+			"as_organization", location.ASOrganization,
+		)
+	}
+
+	logging.Logger(logging.ComponentAuth).Info("security_event", args...)
+
+	if securityEventForwarder != nil {
+		securityEventForwarder.Forward(event, location)
+	}
+}
+
+// SecurityEventForwarder ships security events to an external system such as
+// a SIEM. Forward is called on the request path and must not block.
+type SecurityEventForwarder interface {
+	Forward(event SecurityEvent, location *GeoLocation)
+}
+
+// securityEventForwarder receives every logged security event. Initialised
+// once at startup via InitSecurityEventForwarder; nil forwards nothing.
+var securityEventForwarder SecurityEventForwarder
+
+// InitSecurityEventForwarder wires the forwarder security events are shipped
+// to after they are logged.
+func InitSecurityEventForwarder(forwarder SecurityEventForwarder) {
+	securityEventForwarder = forwarder
+}
+
+// securityEventLocator adds where the client IP is to security events.
+// Initialised once at startup via InitGeoLocator; nil leaves events as is.
+var securityEventLocator GeoLocator
+
+// InitGeoLocator wires the GeoIP lookup used to enrich security events.
+func InitGeoLocator(locator GeoLocator) {
+	securityEventLocator = locator
+}
+
+// locateSecurityEvent returns where ip is, or nil without a locator or when
+// the lookup fails. A failed lookup must never drop the event itself.
+func locateSecurityEvent(ip string) *GeoLocation {
+	if securityEventLocator == nil || ip == "" {
+		return nil
+	}
+	location, err := securityEventLocator.Locate(context.Background(), ip)
+	if err != nil {
+		return nil
+	}
+	return location
 }
 
 // determineSeverity assigns severity levels to security events
@@ -141,6 +195,7 @@ func determineSeverity(eventType string) string {
 		"login_rate_limited",
 		"suspicious_login",
 		"ip_blocked",
+		"credential_stuffing_detected",
 		"token_validation_failure",
 	}
 
@@ -175,44 +230,7 @@ var (
 // ValidatePasswordStrength enforces password complexity requirements
 // Complies with SOC2 CC6.1 and ISO27001 A.9.4.3
 func ValidatePasswordStrength(password string) error {
-	if len(password) < 8 {
-		return fmt.Errorf("password must be at least 8 characters long")
-	}
-
-	if len(password) > 128 {
-		return fmt.Errorf("password must not exceed 128 characters")
-	}
-
-	if !reUpper.MatchString(password) {
-		return fmt.Errorf("password must contain at least one uppercase letter")
-	}
-
-	if !reLower.MatchString(password) {
-		return fmt.Errorf("password must contain at least one lowercase letter")
-	}
-
-	if !reDigit.MatchString(password) {
-		return fmt.Errorf("password must contain at least one digit")
-	}
-
-	if !reSpecial.MatchString(password) {
-		return fmt.Errorf("password must contain at least one special character")
-	}
-
-	// Check for common weak passwords
-	weakPasswords := []string{
-		"password", "123456", "password123", "admin", "qwerty",
-		"letmein", "welcome", "monkey", "dragon", "master",
-	}
-
-	lowerPassword := strings.ToLower(password)
-	for _, weak := range weakPasswords {
-		if strings.Contains(lowerPassword, weak) {
-			return fmt.Errorf("password contains common weak patterns")
-		}
-	}
-
-	return nil
+	return DefaultPasswordPolicy().Check(password)
 }
 
 // SanitizeInput sanitizes user input to prevent injection attacks
@@ -429,20 +447,22 @@ func ResetFailedAttempts(identifier string) {
 // ============================================================================
 // Functions for managing user sessions and concurrent session limits
 
-// ValidateSessionLimit checks if user has exceeded maximum concurrent sessions
-// This can be used by services that need to enforce session limits
+// ValidateSessionLimit checks if a user holding currentSessionCount sessions
+// may start another one when at most maxSessions are allowed. A maxSessions
+// of 0 or less means no limit.
 // Complies with SOC2 CC6.3 and ISO27001 A.9.4.2
-func ValidateSessionLimit(userID string, currentSessionCount int) error {
+func ValidateSessionLimit(userID string, currentSessionCount, maxSessions int) error {
 	_, span := otel.Tracer("security").Start(context.Background(), "security.validate_session_limit")
 	defer span.End()
 	span.SetAttributes(
 		attribute.String("user_id", userID),
 		attribute.Int("session_count", currentSessionCount),
+		attribute.Int("max_sessions", maxSessions),
 	)
 
-	if currentSessionCount >= MaxConcurrentSessions {
+	if maxSessions > 0 && currentSessionCount >= maxSessions {
 		span.SetStatus(codes.Error, "session limit exceeded")
-		return fmt.Errorf("maximum concurrent sessions (%d) exceeded for user", MaxConcurrentSessions)
+		return fmt.Errorf("maximum concurrent sessions (%d) exceeded for user", maxSessions)
 	}
 	span.SetStatus(codes.Ok, "")
 	return nil
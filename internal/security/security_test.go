@@ -1,6 +1,8 @@
 package security
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -189,6 +191,58 @@ func TestLogSecurityEvent_AutoDeterminedSeverity(t *testing.T) {
 	assert.NotPanics(t, func() { LogSecurityEvent(event) })
 }
 
+type stubLocator struct {
+	location *GeoLocation
+	err      error
+}
+
+func (l stubLocator) Locate(_ context.Context, _ string) (*GeoLocation, error) {
+	return l.location, l.err
+}
+
+func TestLogSecurityEvent_GeoLocation(t *testing.T) {
+	t.Cleanup(func() { InitGeoLocator(nil) })
+
+	assert.Nil(t, locateSecurityEvent("203.0.113.7"), "no locator configured")
+
+	InitGeoLocator(stubLocator{location: &tokyo})
+	assert.Equal(t, &tokyo, locateSecurityEvent("203.0.113.7"))
+	assert.Nil(t, locateSecurityEvent(""), "events without a client IP are not looked up")
+	assert.NotPanics(t, func() {
+		LogSecurityEvent(SecurityEvent{EventType: "suspicious_login", ClientIP: "203.0.113.7", Timestamp: time.Now()})
+	})
+
+	InitGeoLocator(stubLocator{err: errors.New("database closed")})
+	assert.Nil(t, locateSecurityEvent("203.0.113.7"), "lookup failures leave the event unenriched")
+}
+
+type recordingForwarder struct {
+	events    []SecurityEvent
+	locations []*GeoLocation
+}
+
+func (f *recordingForwarder) Forward(event SecurityEvent, location *GeoLocation) {
+	f.events = append(f.events, event)
+	f.locations = append(f.locations, location)
+}
+
+func TestLogSecurityEvent_Forwarder(t *testing.T) {
+	t.Cleanup(func() {
+		InitSecurityEventForwarder(nil)
+		InitGeoLocator(nil)
+	})
+
+	forwarder := &recordingForwarder{}
+	InitSecurityEventForwarder(forwarder)
+	InitGeoLocator(stubLocator{location: &tokyo})
+
+	LogSecurityEvent(SecurityEvent{EventType: "account_locked", ClientIP: "203.0.113.7", Timestamp: time.Now()})
+
+	assert.Len(t, forwarder.events, 1)
+	assert.Equal(t, "HIGH", forwarder.events[0].Severity, "forwarded events carry the determined severity")
+	assert.Equal(t, &tokyo, forwarder.locations[0])
+}
+
 // ---------------------------------------------------------------------------
 // determineSeverity (unexported — accessible within package)
 // ---------------------------------------------------------------------------
@@ -202,6 +256,7 @@ func TestDetermineSeverity(t *testing.T) {
 		{"login_rate_limited", "HIGH"},
 		{"suspicious_login", "HIGH"},
 		{"ip_blocked", "HIGH"},
+		{"credential_stuffing_detected", "HIGH"},
 		{"token_validation_failure", "HIGH"},
 		{"login_failure", "MEDIUM"},
 		{"registration_failure", "MEDIUM"},
@@ -314,15 +369,18 @@ func TestValidateSessionLimit(t *testing.T) {
 	tests := []struct {
 		name    string
 		count   int
+		max     int
 		wantErr bool
 	}{
-		{"below limit", MaxConcurrentSessions - 1, false},
-		{"at limit", MaxConcurrentSessions, true},
-		{"above limit", MaxConcurrentSessions + 1, true},
+		{"below limit", MaxConcurrentSessions - 1, MaxConcurrentSessions, false},
+		{"at limit", MaxConcurrentSessions, MaxConcurrentSessions, true},
+		{"above limit", MaxConcurrentSessions + 1, MaxConcurrentSessions, true},
+		{"single session", 1, 1, true},
+		{"no limit", 100, 0, false},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			err := ValidateSessionLimit("user-123", tc.count)
+			err := ValidateSessionLimit("user-123", tc.count, tc.max)
 			if tc.wantErr {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), "maximum concurrent sessions")
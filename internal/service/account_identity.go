@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/federation"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/datatypes"
+)
+
+// AccountIdentityService links external identities to the caller's own
+// account and unlinks them.
+type AccountIdentityService interface {
+	// List returns the user's identities in tenantID, local and external,
+	// with their client.
+	List(ctx context.Context, userID, tenantID int64) ([]UserIdentityServiceDataResult, error)
+
+	// Link re-authenticates user with their password, verifies the ID token
+	// the upstream provider issued to the requested auth client and links
+	// its subject to the account. An identity can only be linked to one
+	// account, and an account to one identity per auth client.
+	Link(ctx context.Context, user *model.User, tenantID int64, req dto.AccountIdentityLinkRequestDTO) (*UserIdentityServiceDataResult, error)
+
+	// Unlink removes one of the user's external identities. It fails with
+	// an *apperror.ConflictError when that would leave the account with no
+	// way to sign in.
+	Unlink(ctx context.Context, user *model.User, tenantID int64, identityUUID uuid.UUID) error
+}
+
+type accountIdentityService struct {
+	userIdentityRepo repository.UserIdentityRepository
+	clientRepo       repository.ClientRepository
+	verifier         federation.IDTokenVerifier
+	authEventService AuthEventService
+}
+
+// NewAccountIdentityService creates a new AccountIdentityService.
+func NewAccountIdentityService(
+	userIdentityRepo repository.UserIdentityRepository,
+	clientRepo repository.ClientRepository,
+	verifier federation.IDTokenVerifier,
+	authEventService AuthEventService,
+) AccountIdentityService {
+	return &accountIdentityService{
+		userIdentityRepo: userIdentityRepo,
+		clientRepo:       clientRepo,
+		verifier:         verifier,
+		authEventService: authEventService,
+	}
+}
+
+// List implements AccountIdentityService.
+func (s *accountIdentityService) List(ctx context.Context, userID, tenantID int64) ([]UserIdentityServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "account_identity.list")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("user.id", userID), attribute.Int64("tenant.id", tenantID))
+
+	identities, err := s.userIdentityRepo.FindByUserID(userID, "Client")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "identities lookup failed")
+		return nil, apperror.NewInternal("failed to retrieve identities", err)
+	}
+
+	result := make([]UserIdentityServiceDataResult, 0, len(identities))
+	for i := range identities {
+		if identities[i].TenantID == tenantID {
+			result = append(result, toUserIdentityServiceDataResult(&identities[i]))
+		}
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// Link implements AccountIdentityService.
+func (s *accountIdentityService) Link(ctx context.Context, user *model.User, tenantID int64, req dto.AccountIdentityLinkRequestDTO) (*UserIdentityServiceDataResult, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "account_identity.link")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int64("user.id", user.UserID),
+		attribute.Int64("tenant.id", tenantID),
+		attribute.String("client.uuid", req.ClientUUID),
+	)
+
+	if err := s.reauthenticate(ctx, user, tenantID, req.Password); err != nil {
+		span.SetStatus(codes.Error, "re-authentication failed")
+		return nil, err
+	}
+
+	client, provider, err := s.federatedClient(req.ClientUUID, tenantID)
+	if err != nil {
+		span.SetStatus(codes.Error, "client is not federated")
+		return nil, err
+	}
+
+	identity, err := s.verifier.Verify(ctx, *provider, req.IDToken)
+	if err != nil {
+		span.RecordError(err)
+		if errors.Is(err, federation.ErrInvalidToken) {
+			span.SetStatus(codes.Error, "invalid upstream id token")
+			return nil, apperror.NewValidation("ID token is invalid or expired")
+		}
+		span.SetStatus(codes.Error, "upstream keys unavailable")
+		return nil, apperror.NewInternal("failed to verify ID token", err)
+	}
+
+	// An upstream account links to one local account, and a local account
+	// holds one identity per auth client as sign in looks it up by client
+	existing, err := s.userIdentityRepo.FindByClientIDAndSub(client.ClientID, identity.Subject)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "identity lookup failed")
+		return nil, apperror.NewInternal("failed to link identity", err)
+	}
+	if existing != nil {
+		span.SetStatus(codes.Error, "identity already linked")
+		if existing.UserID == user.UserID {
+			return nil, apperror.NewConflict("this identity is already linked to your account")
+		}
+		return nil, apperror.NewConflict("this identity is linked to another account")
+	}
+	current, err := s.userIdentityRepo.FindByUserIDAndClientID(user.UserID, client.ClientID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "identity lookup failed")
+		return nil, apperror.NewInternal("failed to link identity", err)
+	}
+	if current != nil {
+		span.SetStatus(codes.Error, "client already linked")
+		return nil, apperror.NewConflict("an identity of this provider is already linked to your account")
+	}
+
+	metadata, _ := json.Marshal(map[string]any{
+		"identity_provider_id": client.IdentityProvider.IdentityProviderUUID.String(),
+		"email":                identity.Email,
+		"email_verified":       identity.EmailVerified,
+		"name":                 identity.Name,
+	})
+	created, err := s.userIdentityRepo.Create(&model.UserIdentity{
+		TenantID: tenantID,
+		UserID:   user.UserID,
+		ClientID: client.ClientID,
+		Provider: client.IdentityProvider.Provider,
+		Sub:      identity.Subject,
+		Metadata: datatypes.JSON(metadata),
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "create identity failed")
+		return nil, apperror.NewInternal("failed to link identity", err)
+	}
+	created.Client = client
+	span.SetAttributes(attribute.String("user_identity.uuid", created.UserIdentityUUID.String()))
+
+	s.logEvent(ctx, user.UserID, tenantID, model.AuthEventTypeUserIdentityLinked, model.AuthEventResultSuccess,
+		"External identity linked: "+created.Provider)
+
+	result := toUserIdentityServiceDataResult(created)
+	span.SetStatus(codes.Ok, "")
+	return &result, nil
+}
+
+// Unlink implements AccountIdentityService.
+func (s *accountIdentityService) Unlink(ctx context.Context, user *model.User, tenantID int64, identityUUID uuid.UUID) error {
+	ctx, span := otel.Tracer("service").Start(ctx, "account_identity.unlink")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int64("user.id", user.UserID),
+		attribute.Int64("tenant.id", tenantID),
+		attribute.String("user_identity.uuid", identityUUID.String()),
+	)
+
+	identity, err := s.userIdentityRepo.FindByUUIDAndUserID(identityUUID.String(), user.UserID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "identity lookup failed")
+		return apperror.NewInternal("failed to unlink identity", err)
+	}
+	if identity == nil || identity.TenantID != tenantID {
+		span.SetStatus(codes.Error, "identity not found or not owned by user")
+		return apperror.NewNotFoundWithReason("identity not found")
+	}
+	if identity.Provider == model.ProviderDefault {
+		span.SetStatus(codes.Error, "local identity")
+		return apperror.NewValidation("the local identity of an auth client cannot be unlinked")
+	}
+
+	// The password and every other external identity are ways to sign in
+	identities, err := s.userIdentityRepo.FindByUserID(user.UserID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "identities lookup failed")
+		return apperror.NewInternal("failed to unlink identity", err)
+	}
+	remaining := 0
+	if user.Password != nil {
+		remaining++
+	}
+	for _, other := range identities {
+		if other.Provider != model.ProviderDefault && other.UserIdentityID != identity.UserIdentityID {
+			remaining++
+		}
+	}
+	if remaining == 0 {
+		span.SetStatus(codes.Error, "last login method")
+		return apperror.NewConflict("cannot unlink the last way to sign in to your account; set a password first")
+	}
+
+	if err := s.userIdentityRepo.DeleteByID(identity.UserIdentityID); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete identity failed")
+		return apperror.NewInternal("failed to unlink identity", err)
+	}
+
+	s.logEvent(ctx, user.UserID, tenantID, model.AuthEventTypeUserIdentityUnlinked, model.AuthEventResultSuccess,
+		"External identity unlinked: "+identity.Provider)
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// reauthenticate checks the user's password. Wrong passwords count towards
+// a lockout, so a stolen access token cannot be used to guess it.
+func (s *accountIdentityService) reauthenticate(ctx context.Context, user *model.User, tenantID int64, password string) error {
+	limiterKey := security.RateLimitKey(user.UserUUID.String(), "link_identity")
+	if err := security.CheckRateLimit(limiterKey); err != nil {
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "identity_link_rate_limited",
+			UserID:    user.UserUUID.String(),
+			Timestamp: time.Now(),
+			Details:   err.Error(),
+		})
+		return apperror.NewForbidden("too many failed attempts, try again later")
+	}
+	if user.Password == nil {
+		return apperror.NewForbidden("set a password before linking another identity")
+	}
+
+	if err := security.ComparePassword([]byte(*user.Password), []byte(password)); err != nil {
+		security.RecordFailedAttempt(limiterKey)
+		s.logEvent(ctx, user.UserID, tenantID, model.AuthEventTypeUserIdentityLinked, model.AuthEventResultFailure,
+			"Password is incorrect")
+		return apperror.NewValidation("password is incorrect")
+	}
+	security.ResetFailedAttempts(limiterKey)
+	return nil
+}
+
+// federatedClient returns the active auth client of the tenant under an
+// external OpenID Connect provider, with the provider it trusts.
+func (s *accountIdentityService) federatedClient(clientUUID string, tenantID int64) (*model.Client, *federation.Provider, error) {
+	id, err := uuid.Parse(clientUUID)
+	if err != nil {
+		return nil, nil, apperror.NewValidation("invalid client ID")
+	}
+	client, err := s.clientRepo.FindByUUIDAndTenantID(id, tenantID)
+	if err != nil {
+		return nil, nil, apperror.NewInternal("failed to load auth client", err)
+	}
+	if client == nil || client.Status != model.StatusActive || client.IdentityProvider == nil ||
+		client.IdentityProvider.Status != model.StatusActive {
+		return nil, nil, apperror.NewNotFoundWithReason("auth client not found")
+	}
+	if client.IdentityProvider.Provider == model.IDPProviderInternal {
+		return nil, nil, apperror.NewValidation("auth client does not belong to an external identity provider")
+	}
+
+	provider, err := federation.ProviderFromConfig(client.IdentityProvider.Config, ptr.Deref(client.Identifier))
+	if err != nil {
+		return nil, nil, apperror.NewValidation("identity provider does not support linking: " + err.Error())
+	}
+	return client, provider, nil
+}
+
+func (s *accountIdentityService) logEvent(ctx context.Context, userID, tenantID int64, eventType, result, description string) {
+	severity := model.AuthEventSeverityInfo
+	if result == model.AuthEventResultFailure {
+		severity = model.AuthEventSeverityWarn
+	}
+	s.authEventService.Log(ctx, AuthEventInput{
+		TenantID:    tenantID,
+		ActorUserID: &userID,
+		IPAddress:   middleware.ClientIPFromContext(ctx),
+		UserAgent:   ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:    model.AuthEventCategoryUser,
+		EventType:   eventType,
+		Severity:    severity,
+		Result:      result,
+		Description: ptr.Ptr(description),
+	})
+}
+
+// toUserIdentityServiceDataResult maps an identity and, when loaded, its
+// client.
+func toUserIdentityServiceDataResult(identity *model.UserIdentity) UserIdentityServiceDataResult {
+	result := UserIdentityServiceDataResult{
+		UserIdentityUUID: identity.UserIdentityUUID,
+		Provider:         identity.Provider,
+		Sub:              identity.Sub,
+		Metadata:         identity.Metadata,
+		CreatedAt:        identity.CreatedAt,
+		UpdatedAt:        identity.UpdatedAt,
+	}
+	if identity.Client != nil {
+		result.Client = ToClientServiceDataResult(identity.Client)
+	}
+	return result
+}
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/federation"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/datatypes"
+)
+
+// mockIDTokenVerifier is a federation.IDTokenVerifier returning fixed results.
+type mockIDTokenVerifier struct {
+	verifyFn func(ctx context.Context, p federation.Provider, rawIDToken string) (*federation.Identity, error)
+}
+
+func (m *mockIDTokenVerifier) Verify(ctx context.Context, p federation.Provider, rawIDToken string) (*federation.Identity, error) {
+	if m.verifyFn != nil {
+		return m.verifyFn(ctx, p, rawIDToken)
+	}
+	return &federation.Identity{Subject: "google-123"}, nil
+}
+
+const linkPassword = "Secret-123"
+
+// newLinkingUser returns a user whose password is linkPassword.
+func newLinkingUser(t *testing.T) *model.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(linkPassword), bcrypt.MinCost)
+	require.NoError(t, err)
+	return &model.User{UserID: 7, UserUUID: uuid.New(), Password: ptr.Ptr(string(hash))}
+}
+
+// newGoogleAuthClient returns an active auth client of a Google identity
+// provider.
+func newGoogleAuthClient() *model.Client {
+	return &model.Client{
+		ClientID:   20,
+		ClientUUID: uuid.New(),
+		Identifier: ptr.Ptr("google-client-id"),
+		Status:     model.StatusActive,
+		IdentityProvider: &model.IdentityProvider{
+			IdentityProviderUUID: uuid.New(),
+			Provider:             model.IDPProviderGoogle,
+			Status:               model.StatusActive,
+			Config:               datatypes.JSON(`{"issuer":"https://accounts.google.com","jwks_uri":"https://www.googleapis.com/oauth2/v3/certs"}`),
+		},
+	}
+}
+
+// authClientRepo returns a client repo that finds client in tenant 1.
+func authClientRepo(client *model.Client) *mockClientRepo {
+	return &mockClientRepo{findByUUIDAndTenantIDFn: func(id uuid.UUID, tenantID int64) (*model.Client, error) {
+		if id != client.ClientUUID || tenantID != 1 {
+			return nil, nil
+		}
+		return client, nil
+	}}
+}
+
+// unlinkedIdentityRepo returns an identity repo of a user without an
+// identity of the client.
+func unlinkedIdentityRepo() *mockUserIdentityRepo {
+	return &mockUserIdentityRepo{findByUserIDAndClientIDFn: func(int64, int64) (*model.UserIdentity, error) { return nil, nil }}
+}
+
+// newAccountIdentityService returns an AccountIdentityService whose audit
+// entries are recorded in logged.
+func newAccountIdentityService(identityRepo *mockUserIdentityRepo, clientRepo *mockClientRepo, verifier *mockIDTokenVerifier, logged *[]AuthEventInput) AccountIdentityService {
+	return NewAccountIdentityService(identityRepo, clientRepo, verifier, &mockAuthEventService{
+		logFn: func(_ context.Context, in AuthEventInput) { *logged = append(*logged, in) },
+	})
+}
+
+func newLinkRequest(client *model.Client) dto.AccountIdentityLinkRequestDTO {
+	return dto.AccountIdentityLinkRequestDTO{ClientUUID: client.ClientUUID.String(), IDToken: "id-token", Password: linkPassword}
+}
+
+func TestAccountIdentityService_List(t *testing.T) {
+	client := newGoogleAuthClient()
+	identityRepo := &mockUserIdentityRepo{findByUserIDFn: func(userID int64, preloads ...string) ([]model.UserIdentity, error) {
+		assert.Equal(t, int64(7), userID)
+		assert.Equal(t, []string{"Client"}, preloads)
+		return []model.UserIdentity{
+			{TenantID: 1, Provider: model.ProviderDefault, Sub: "local"},
+			{TenantID: 2, Provider: model.IDPProviderGoogle, Sub: "other-tenant"},
+			{TenantID: 1, Provider: model.IDPProviderGoogle, Sub: "google-123", Client: client},
+		}, nil
+	}}
+	var logged []AuthEventInput
+	svc := newAccountIdentityService(identityRepo, authClientRepo(client), &mockIDTokenVerifier{}, &logged)
+
+	res, err := svc.List(context.Background(), 7, 1)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, "local", res[0].Sub)
+	assert.Equal(t, "google-123", res[1].Sub)
+	require.NotNil(t, res[1].Client)
+	assert.Equal(t, client.ClientUUID, res[1].Client.ClientUUID)
+}
+
+func TestAccountIdentityService_Link(t *testing.T) {
+	t.Run("links the verified subject", func(t *testing.T) {
+		client := newGoogleAuthClient()
+		verifier := &mockIDTokenVerifier{verifyFn: func(_ context.Context, p federation.Provider, raw string) (*federation.Identity, error) {
+			assert.Equal(t, federation.Provider{
+				Issuer:   "https://accounts.google.com",
+				JWKSURI:  "https://www.googleapis.com/oauth2/v3/certs",
+				ClientID: "google-client-id",
+			}, p)
+			assert.Equal(t, "id-token", raw)
+			return &federation.Identity{Subject: "google-123", Email: "jane@example.com", EmailVerified: true}, nil
+		}}
+		identityRepo := unlinkedIdentityRepo()
+		var saved *model.UserIdentity
+		identityRepo.createFn = func(ui *model.UserIdentity) (*model.UserIdentity, error) {
+			ui.UserIdentityUUID = uuid.New()
+			saved = ui
+			return ui, nil
+		}
+		var logged []AuthEventInput
+		svc := newAccountIdentityService(identityRepo, authClientRepo(client), verifier, &logged)
+
+		res, err := svc.Link(context.Background(), newLinkingUser(t), 1, newLinkRequest(client))
+		require.NoError(t, err)
+		require.NotNil(t, saved)
+		assert.Equal(t, int64(1), saved.TenantID)
+		assert.Equal(t, int64(7), saved.UserID)
+		assert.Equal(t, int64(20), saved.ClientID)
+		assert.Equal(t, model.IDPProviderGoogle, saved.Provider)
+		assert.Equal(t, "google-123", saved.Sub)
+
+		var metadata map[string]any
+		require.NoError(t, json.Unmarshal(saved.Metadata, &metadata))
+		assert.Equal(t, "jane@example.com", metadata["email"])
+		assert.Equal(t, client.IdentityProvider.IdentityProviderUUID.String(), metadata["identity_provider_id"])
+
+		assert.Equal(t, saved.UserIdentityUUID, res.UserIdentityUUID)
+		require.NotNil(t, res.Client)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeUserIdentityLinked, logged[0].EventType)
+		assert.Equal(t, model.AuthEventResultSuccess, logged[0].Result)
+	})
+
+	t.Run("wrong password → validation error", func(t *testing.T) {
+		client := newGoogleAuthClient()
+		verifier := &mockIDTokenVerifier{verifyFn: func(context.Context, federation.Provider, string) (*federation.Identity, error) {
+			t.Fatal("the token must not be verified")
+			return nil, nil
+		}}
+		var logged []AuthEventInput
+		svc := newAccountIdentityService(unlinkedIdentityRepo(), authClientRepo(client), verifier, &logged)
+		req := newLinkRequest(client)
+		req.Password = "wrong"
+
+		_, err := svc.Link(context.Background(), newLinkingUser(t), 1, req)
+		var want *apperror.ValidationError
+		require.ErrorAs(t, err, &want)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventResultFailure, logged[0].Result)
+	})
+
+	t.Run("user without a password → forbidden", func(t *testing.T) {
+		client := newGoogleAuthClient()
+		user := newLinkingUser(t)
+		user.Password = nil
+		var logged []AuthEventInput
+		svc := newAccountIdentityService(unlinkedIdentityRepo(), authClientRepo(client), &mockIDTokenVerifier{}, &logged)
+
+		_, err := svc.Link(context.Background(), user, 1, newLinkRequest(client))
+		var want *apperror.ForbiddenError
+		require.ErrorAs(t, err, &want)
+	})
+
+	t.Run("client of another tenant → not found", func(t *testing.T) {
+		client := newGoogleAuthClient()
+		var logged []AuthEventInput
+		svc := newAccountIdentityService(unlinkedIdentityRepo(), authClientRepo(client), &mockIDTokenVerifier{}, &logged)
+
+		_, err := svc.Link(context.Background(), newLinkingUser(t), 2, newLinkRequest(client))
+		var want *apperror.NotFoundError
+		require.ErrorAs(t, err, &want)
+	})
+
+	t.Run("internal identity provider → validation error", func(t *testing.T) {
+		client := newGoogleAuthClient()
+		client.IdentityProvider.Provider = model.IDPProviderInternal
+		var logged []AuthEventInput
+		svc := newAccountIdentityService(unlinkedIdentityRepo(), authClientRepo(client), &mockIDTokenVerifier{}, &logged)
+
+		_, err := svc.Link(context.Background(), newLinkingUser(t), 1, newLinkRequest(client))
+		var want *apperror.ValidationError
+		require.ErrorAs(t, err, &want)
+	})
+
+	t.Run("identity provider without issuer → validation error", func(t *testing.T) {
+		client := newGoogleAuthClient()
+		client.IdentityProvider.Config = datatypes.JSON(`{}`)
+		var logged []AuthEventInput
+		svc := newAccountIdentityService(unlinkedIdentityRepo(), authClientRepo(client), &mockIDTokenVerifier{}, &logged)
+
+		_, err := svc.Link(context.Background(), newLinkingUser(t), 1, newLinkRequest(client))
+		var want *apperror.ValidationError
+		require.ErrorAs(t, err, &want)
+	})
+
+	t.Run("invalid token → validation error", func(t *testing.T) {
+		client := newGoogleAuthClient()
+		verifier := &mockIDTokenVerifier{verifyFn: func(context.Context, federation.Provider, string) (*federation.Identity, error) {
+			return nil, fmt.Errorf("%w: token is expired", federation.ErrInvalidToken)
+		}}
+		var logged []AuthEventInput
+		svc := newAccountIdentityService(unlinkedIdentityRepo(), authClientRepo(client), verifier, &logged)
+
+		_, err := svc.Link(context.Background(), newLinkingUser(t), 1, newLinkRequest(client))
+		var want *apperror.ValidationError
+		require.ErrorAs(t, err, &want)
+	})
+
+	t.Run("upstream keys unavailable → internal error", func(t *testing.T) {
+		client := newGoogleAuthClient()
+		verifier := &mockIDTokenVerifier{verifyFn: func(context.Context, federation.Provider, string) (*federation.Identity, error) {
+			return nil, errors.New("fetch key set: status 503")
+		}}
+		var logged []AuthEventInput
+		svc := newAccountIdentityService(unlinkedIdentityRepo(), authClientRepo(client), verifier, &logged)
+
+		_, err := svc.Link(context.Background(), newLinkingUser(t), 1, newLinkRequest(client))
+		var want *apperror.InternalError
+		require.ErrorAs(t, err, &want)
+	})
+
+	t.Run("identity linked to another account → conflict", func(t *testing.T) {
+		client := newGoogleAuthClient()
+		identityRepo := unlinkedIdentityRepo()
+		identityRepo.findByClientIDAndSubFn = func(clientID int64, sub string) (*model.UserIdentity, error) {
+			assert.Equal(t, int64(20), clientID)
+			assert.Equal(t, "google-123", sub)
+			return &model.UserIdentity{UserID: 8}, nil
+		}
+		var logged []AuthEventInput
+		svc := newAccountIdentityService(identityRepo, authClientRepo(client), &mockIDTokenVerifier{}, &logged)
+
+		_, err := svc.Link(context.Background(), newLinkingUser(t), 1, newLinkRequest(client))
+		var want *apperror.ConflictError
+		require.ErrorAs(t, err, &want)
+		assert.Contains(t, err.Error(), "another account")
+	})
+
+	t.Run("another identity of the client already linked → conflict", func(t *testing.T) {
+		client := newGoogleAuthClient()
+		identityRepo := &mockUserIdentityRepo{findByUserIDAndClientIDFn: func(int64, int64) (*model.UserIdentity, error) {
+			return &model.UserIdentity{UserID: 7, Sub: "google-456"}, nil
+		}}
+		var logged []AuthEventInput
+		svc := newAccountIdentityService(identityRepo, authClientRepo(client), &mockIDTokenVerifier{}, &logged)
+
+		_, err := svc.Link(context.Background(), newLinkingUser(t), 1, newLinkRequest(client))
+		var want *apperror.ConflictError
+		require.ErrorAs(t, err, &want)
+	})
+}
+
+func TestAccountIdentityService_Unlink(t *testing.T) {
+	identityUUID := uuid.New()
+	google := model.UserIdentity{UserIdentityID: 3, TenantID: 1, Provider: model.IDPProviderGoogle}
+	github := model.UserIdentity{UserIdentityID: 4, TenantID: 1, Provider: model.IDPProviderGitHub}
+	local := model.UserIdentity{UserIdentityID: 5, TenantID: 1, Provider: model.ProviderDefault}
+
+	t.Run("password remains → unlinked", func(t *testing.T) {
+		var deleted any
+		identityRepo := &mockUserIdentityRepo{
+			findByUUIDAndUserIDFn: func(id string, userID int64) (*model.UserIdentity, error) {
+				assert.Equal(t, identityUUID.String(), id)
+				assert.Equal(t, int64(7), userID)
+				return &google, nil
+			},
+			findByUserIDFn: func(int64, ...string) ([]model.UserIdentity, error) {
+				return []model.UserIdentity{local, google}, nil
+			},
+			deleteByIDFn: func(id any) error {
+				deleted = id
+				return nil
+			},
+		}
+		var logged []AuthEventInput
+		svc := newAccountIdentityService(identityRepo, &mockClientRepo{}, &mockIDTokenVerifier{}, &logged)
+
+		require.NoError(t, svc.Unlink(context.Background(), newLinkingUser(t), 1, identityUUID))
+		assert.Equal(t, int64(3), deleted)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeUserIdentityUnlinked, logged[0].EventType)
+	})
+
+	t.Run("another identity remains → unlinked", func(t *testing.T) {
+		user := newLinkingUser(t)
+		user.Password = nil
+		identityRepo := &mockUserIdentityRepo{
+			findByUUIDAndUserIDFn: func(string, int64) (*model.UserIdentity, error) { return &google, nil },
+			findByUserIDFn: func(int64, ...string) ([]model.UserIdentity, error) {
+				return []model.UserIdentity{local, google, github}, nil
+			},
+		}
+		var logged []AuthEventInput
+		svc := newAccountIdentityService(identityRepo, &mockClientRepo{}, &mockIDTokenVerifier{}, &logged)
+
+		require.NoError(t, svc.Unlink(context.Background(), user, 1, identityUUID))
+	})
+
+	t.Run("last login method → conflict", func(t *testing.T) {
+		user := newLinkingUser(t)
+		user.Password = nil
+		identityRepo := &mockUserIdentityRepo{
+			findByUUIDAndUserIDFn: func(string, int64) (*model.UserIdentity, error) { return &google, nil },
+			findByUserIDFn: func(int64, ...string) ([]model.UserIdentity, error) {
+				return []model.UserIdentity{local, google}, nil
+			},
+			deleteByIDFn: func(any) error {
+				t.Fatal("the identity must not be deleted")
+				return nil
+			},
+		}
+		var logged []AuthEventInput
+		svc := newAccountIdentityService(identityRepo, &mockClientRepo{}, &mockIDTokenVerifier{}, &logged)
+
+		err := svc.Unlink(context.Background(), user, 1, identityUUID)
+		var want *apperror.ConflictError
+		require.ErrorAs(t, err, &want)
+	})
+
+	t.Run("local identity → validation error", func(t *testing.T) {
+		identityRepo := &mockUserIdentityRepo{findByUUIDAndUserIDFn: func(string, int64) (*model.UserIdentity, error) { return &local, nil }}
+		var logged []AuthEventInput
+		svc := newAccountIdentityService(identityRepo, &mockClientRepo{}, &mockIDTokenVerifier{}, &logged)
+
+		err := svc.Unlink(context.Background(), newLinkingUser(t), 1, identityUUID)
+		var want *apperror.ValidationError
+		require.ErrorAs(t, err, &want)
+	})
+
+	t.Run("identity of another user or tenant → not found", func(t *testing.T) {
+		user := newLinkingUser(t)
+		identityRepo := &mockUserIdentityRepo{}
+		var logged []AuthEventInput
+		svc := newAccountIdentityService(identityRepo, &mockClientRepo{}, &mockIDTokenVerifier{}, &logged)
+
+		err := svc.Unlink(context.Background(), user, 1, identityUUID)
+		var want *apperror.NotFoundError
+		require.ErrorAs(t, err, &want)
+
+		identityRepo.findByUUIDAndUserIDFn = func(string, int64) (*model.UserIdentity, error) { return &google, nil }
+		err = svc.Unlink(context.Background(), user, 2, identityUUID)
+		require.ErrorAs(t, err, &want)
+	})
+}
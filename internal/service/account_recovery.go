@@ -0,0 +1,810 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+const (
+	// recoveryEmailVerificationTTL is how long a recovery email verification
+	// code stays valid.
+	recoveryEmailVerificationTTL = 24 * time.Hour
+	// accountRecoveryTTL is how long the code emailed to start an account
+	// recovery stays valid.
+	accountRecoveryTTL = 15 * time.Minute
+)
+
+var (
+	// errInvalidRecovery is returned for every failed account recovery, so
+	// callers cannot tell which factor was wrong or whether the account
+	// exists.
+	errInvalidRecovery = apperror.NewValidation("invalid recovery details")
+	// errInvalidRecoveryEmailCode is returned for unknown, expired and used
+	// recovery email verification codes alike.
+	errInvalidRecoveryEmailCode = apperror.NewValidation("invalid or expired verification code")
+)
+
+// recoveryCodeEncoding renders recovery codes in lowercase base32, which has
+// no easily confused characters such as 0/O and 1/l.
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// AccountRecoveryServiceDataResult describes the recovery factors of a user.
+type AccountRecoveryServiceDataResult struct {
+	RecoveryCodesRemaining int64
+	RecoveryEmail          *string
+	RecoveryEmailVerified  bool
+}
+
+// AccountRecoveryInput carries the factors of a guided account recovery.
+type AccountRecoveryInput struct {
+	Email        string
+	RecoveryCode string
+	Code         string
+	NewPassword  string
+}
+
+// AccountRecoveryService manages the factors users regain access with when
+// they lose their password or second factor: single-use backup codes and a
+// verified secondary recovery email. Recovering an account takes both.
+type AccountRecoveryService interface {
+	// Status returns the user's remaining codes and recovery email.
+	Status(ctx context.Context, userID int64) (*AccountRecoveryServiceDataResult, error)
+	// GenerateCodes replaces the user's recovery codes with a new set after
+	// checking the password, and returns the codes. They are never shown
+	// again.
+	GenerateCodes(ctx context.Context, userID, tenantID int64, password string) ([]string, error)
+	// SetRecoveryEmail sets the user's recovery email after checking the
+	// password, and emails it a verification code. The address cannot be
+	// used for recovery until it is verified.
+	SetRecoveryEmail(ctx context.Context, userID, tenantID int64, password, email string) (*AccountRecoveryServiceDataResult, error)
+	// VerifyRecoveryEmail verifies the recovery email with the emailed code.
+	VerifyRecoveryEmail(ctx context.Context, userID, tenantID int64, code string) (*AccountRecoveryServiceDataResult, error)
+	// RemoveRecoveryEmail clears the user's recovery email.
+	RemoveRecoveryEmail(ctx context.Context, userID, tenantID int64) (*AccountRecoveryServiceDataResult, error)
+	// StartRecovery emails a recovery code to the verified recovery email of
+	// the account with the primary email, when it has one and recovery codes
+	// left. It never reveals whether it did.
+	StartRecovery(ctx context.Context, clientID, providerID, email string) error
+	// CompleteRecovery sets a new password once a recovery code and the code
+	// emailed by StartRecovery are both valid, and signs out every session.
+	// An account on security hold is made active again. It returns how many
+	// recovery codes are left.
+	CompleteRecovery(ctx context.Context, clientID, providerID string, in AccountRecoveryInput) (int64, error)
+}
+
+type accountRecoveryService struct {
+	db                  *gorm.DB
+	userRepo            repository.UserRepository
+	userTokenRepo       repository.UserTokenRepository
+	recoveryCodeRepo    repository.RecoveryCodeRepository
+	clientRepo          repository.ClientRepository
+	refreshTokenRepo    repository.OAuthRefreshTokenRepository
+	emailTemplateRepo   repository.EmailTemplateRepository
+	tenantSettingRepo   repository.TenantSettingRepository
+	securitySettingRepo repository.SecuritySettingRepository
+	eventRepo           repository.EventRepository
+	breachChecker       security.BreachedPasswordChecker
+	authEventService    AuthEventService
+	cacheInvalidator    cache.Invalidator
+}
+
+// NewAccountRecoveryService creates an AccountRecoveryService.
+func NewAccountRecoveryService(
+	db *gorm.DB,
+	userRepo repository.UserRepository,
+	userTokenRepo repository.UserTokenRepository,
+	recoveryCodeRepo repository.RecoveryCodeRepository,
+	clientRepo repository.ClientRepository,
+	refreshTokenRepo repository.OAuthRefreshTokenRepository,
+	emailTemplateRepo repository.EmailTemplateRepository,
+	tenantSettingRepo repository.TenantSettingRepository,
+	securitySettingRepo repository.SecuritySettingRepository,
+	eventRepo repository.EventRepository,
+	breachChecker security.BreachedPasswordChecker,
+	authEventService AuthEventService,
+	cacheInvalidator cache.Invalidator,
+) AccountRecoveryService {
+	return &accountRecoveryService{
+		db:                  db,
+		userRepo:            userRepo,
+		userTokenRepo:       userTokenRepo,
+		recoveryCodeRepo:    recoveryCodeRepo,
+		clientRepo:          clientRepo,
+		refreshTokenRepo:    refreshTokenRepo,
+		emailTemplateRepo:   emailTemplateRepo,
+		tenantSettingRepo:   tenantSettingRepo,
+		securitySettingRepo: securitySettingRepo,
+		eventRepo:           eventRepo,
+		breachChecker:       breachChecker,
+		authEventService:    authEventService,
+		cacheInvalidator:    cacheInvalidator,
+	}
+}
+
+func (s *accountRecoveryService) Status(ctx context.Context, userID int64) (*AccountRecoveryServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "accountRecovery.status")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("user.id", userID))
+
+	user, err := s.findUser(userID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find user failed")
+		return nil, err
+	}
+	result, err := s.toDataResult(s.recoveryCodeRepo, user)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "count recovery codes failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+func (s *accountRecoveryService) GenerateCodes(ctx context.Context, userID, tenantID int64, password string) ([]string, error) {
+	_, span := otel.Tracer("service").Start(ctx, "accountRecovery.generateCodes")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("user.id", userID), attribute.Int64("tenant.id", tenantID))
+
+	user, err := s.authorizeChange(ctx, userID, tenantID, password)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "authorize change failed")
+		return nil, err
+	}
+
+	recoveryCodes, err := issueRecoveryCodes(s.recoveryCodeRepo, user.UserID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "issue recovery codes failed")
+		return nil, err
+	}
+
+	s.logRecovery(ctx, tenantID, user, model.AuthEventTypeRecoveryCodesIssued, model.AuthEventSeverityWarn, model.AuthEventResultSuccess,
+		fmt.Sprintf("%d recovery codes issued, replacing any earlier codes", len(recoveryCodes)), nil)
+
+	span.SetStatus(codes.Ok, "")
+	return recoveryCodes, nil
+}
+
+func (s *accountRecoveryService) SetRecoveryEmail(ctx context.Context, userID, tenantID int64, password, email string) (*AccountRecoveryServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "accountRecovery.setEmail")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("user.id", userID), attribute.Int64("tenant.id", tenantID))
+
+	user, err := s.authorizeChange(ctx, userID, tenantID, password)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "authorize change failed")
+		return nil, err
+	}
+	email = strings.TrimSpace(email)
+	if strings.EqualFold(email, user.Email) {
+		return nil, apperror.NewValidation("recovery email must differ from the account email")
+	}
+
+	code, err := crypto.GenerateOTP(6)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to generate verification code", err)
+	}
+
+	var result *AccountRecoveryServiceDataResult
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		txUserTokenRepo := s.userTokenRepo.WithTx(tx)
+
+		if _, err := s.userRepo.WithTx(tx).UpdateByID(user.UserID, map[string]any{
+			"recovery_email":             email,
+			"is_recovery_email_verified": false,
+		}); err != nil {
+			return err
+		}
+		if err := revokeUserTokensOfType(txUserTokenRepo, user.UserID, model.TokenTypeRecoveryEmail); err != nil {
+			return err
+		}
+		if _, err := txUserTokenRepo.Create(&model.UserToken{
+			UserID:    user.UserID,
+			TokenType: model.TokenTypeRecoveryEmail,
+			Token:     hashAccountToken(code),
+			ExpiresAt: ptr.TimePtr(time.Now().Add(recoveryEmailVerificationTTL)),
+		}); err != nil {
+			return err
+		}
+
+		user.RecoveryEmail = &email
+		user.IsRecoveryEmailVerified = false
+		result, err = s.toDataResult(s.recoveryCodeRepo.WithTx(tx), user)
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "set recovery email failed")
+		return nil, err
+	}
+
+	s.logRecovery(ctx, tenantID, user, model.AuthEventTypeRecoveryEmailChange, model.AuthEventSeverityWarn, model.AuthEventResultSuccess,
+		"Recovery email set, pending verification", nil)
+	s.sendRecoveryEmail(ctx, user, email, "internal:user:recovery:email:verification", struct {
+		Code    string
+		LogoURL string
+	}{Code: code, LogoURL: config.EmailLogo})
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+func (s *accountRecoveryService) VerifyRecoveryEmail(ctx context.Context, userID, tenantID int64, code string) (*AccountRecoveryServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "accountRecovery.verifyEmail")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("user.id", userID), attribute.Int64("tenant.id", tenantID))
+
+	user, err := s.findUser(userID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find user failed")
+		return nil, err
+	}
+
+	// Wrong codes count towards the same lockout as wrong passwords
+	limiterKey := security.RateLimitKey(user.UserUUID.String(), "recovery_settings")
+	if err := s.checkRateLimit(limiterKey, user.UserUUID.String()); err != nil {
+		return nil, err
+	}
+
+	var result *AccountRecoveryServiceDataResult
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		txUserTokenRepo := s.userTokenRepo.WithTx(tx)
+
+		if user.RecoveryEmail == nil {
+			return errInvalidRecoveryEmailCode
+		}
+		token, err := findUserTokenByCode(txUserTokenRepo, user.UserID, model.TokenTypeRecoveryEmail, code)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			return errInvalidRecoveryEmailCode
+		}
+
+		if _, err := s.userRepo.WithTx(tx).UpdateByID(user.UserID, map[string]any{
+			"is_recovery_email_verified": true,
+		}); err != nil {
+			return err
+		}
+		if err := revokeUserTokensOfType(txUserTokenRepo, user.UserID, model.TokenTypeRecoveryEmail); err != nil {
+			return err
+		}
+
+		user.IsRecoveryEmailVerified = true
+		result, err = s.toDataResult(s.recoveryCodeRepo.WithTx(tx), user)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, errInvalidRecoveryEmailCode) {
+			security.RecordFailedAttempt(limiterKey)
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "verify recovery email failed")
+		return nil, err
+	}
+
+	security.ResetFailedAttempts(limiterKey)
+	s.logRecovery(ctx, tenantID, user, model.AuthEventTypeRecoveryEmailChange, model.AuthEventSeverityWarn, model.AuthEventResultSuccess,
+		"Recovery email verified", nil)
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+func (s *accountRecoveryService) RemoveRecoveryEmail(ctx context.Context, userID, tenantID int64) (*AccountRecoveryServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "accountRecovery.removeEmail")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("user.id", userID), attribute.Int64("tenant.id", tenantID))
+
+	user, err := s.findUser(userID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find user failed")
+		return nil, err
+	}
+
+	var result *AccountRecoveryServiceDataResult
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := s.userRepo.WithTx(tx).UpdateByID(user.UserID, map[string]any{
+			"recovery_email":             nil,
+			"is_recovery_email_verified": false,
+		}); err != nil {
+			return err
+		}
+		if err := revokeUserTokensOfType(s.userTokenRepo.WithTx(tx), user.UserID, model.TokenTypeRecoveryEmail); err != nil {
+			return err
+		}
+
+		user.RecoveryEmail = nil
+		user.IsRecoveryEmailVerified = false
+		result, err = s.toDataResult(s.recoveryCodeRepo.WithTx(tx), user)
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "remove recovery email failed")
+		return nil, err
+	}
+
+	s.logRecovery(ctx, tenantID, user, model.AuthEventTypeRecoveryEmailChange, model.AuthEventSeverityWarn, model.AuthEventResultSuccess,
+		"Recovery email removed", nil)
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+func (s *accountRecoveryService) StartRecovery(ctx context.Context, clientID, providerID, email string) error {
+	_, span := otel.Tracer("service").Start(ctx, "accountRecovery.start")
+	defer span.End()
+	startTime := time.Now()
+
+	client, err := s.clientRepo.FindByClientIDAndIdentityProvider(clientID, providerID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find client failed")
+		return apperror.NewInternal("failed to find auth client", err)
+	}
+	if client == nil || client.IdentityProvider == nil {
+		span.SetStatus(codes.Error, "invalid client")
+		return apperror.NewUnauthorized("invalid client credentials")
+	}
+	tenantID := client.IdentityProvider.TenantID
+	safe := enumerationSafe(s.tenantSettingRepo, tenantID)
+
+	// A locked email gets no new codes; the answer stays the same
+	if security.CheckRateLimit(security.RateLimitKey(strings.ToLower(email), "account_recovery")) != nil {
+		if safe {
+			padResponseTime(ctx, startTime)
+		}
+		span.SetStatus(codes.Ok, "")
+		return nil
+	}
+
+	var user *model.User
+	var code string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		user, err = s.userRepo.WithTx(tx).FindByEmailAndTenantID(email, tenantID)
+		if err != nil {
+			return err
+		}
+		if user == nil || !recoverable(user) {
+			user = nil
+			return nil
+		}
+		remaining, err := s.recoveryCodeRepo.WithTx(tx).CountUnusedByUserID(user.UserID)
+		if err != nil {
+			return err
+		}
+		if remaining == 0 {
+			user = nil
+			return nil
+		}
+
+		code, err = crypto.GenerateOTP(6)
+		if err != nil {
+			return err
+		}
+		txUserTokenRepo := s.userTokenRepo.WithTx(tx)
+		if err := revokeUserTokensOfType(txUserTokenRepo, user.UserID, model.TokenTypeAccountRecovery); err != nil {
+			return err
+		}
+		_, err = txUserTokenRepo.Create(&model.UserToken{
+			UserID:    user.UserID,
+			TokenType: model.TokenTypeAccountRecovery,
+			Token:     hashAccountToken(code),
+			IPAddress: ptr.PtrOrNil(middleware.ClientIPFromContext(ctx)),
+			UserAgent: ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+			ExpiresAt: ptr.TimePtr(time.Now().Add(accountRecoveryTTL)),
+		})
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+		// Enumeration-safe tenants hide failures that only existing
+		// accounts can reach behind the generic response
+		if !safe || user == nil {
+			span.SetStatus(codes.Error, "start recovery failed")
+			return apperror.NewInternal("failed to start account recovery", err)
+		}
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "account_recovery_failure",
+			UserID:    user.UserUUID.String(),
+			Details:   fmt.Sprintf("Failed to issue account recovery code: %v", err),
+			Severity:  "HIGH",
+			Timestamp: time.Now(),
+		})
+		user = nil
+	}
+
+	if user != nil {
+		s.logRecovery(ctx, tenantID, user, model.AuthEventTypeRecoveryStart, model.AuthEventSeverityWarn, model.AuthEventResultSuccess,
+			"Account recovery code sent to the recovery email", nil)
+		data := struct {
+			Code    string
+			LogoURL string
+		}{Code: code, LogoURL: config.EmailLogo}
+		if safe {
+			// Deliver in the background so the mail server's latency does
+			// not tell existing accounts apart
+			go s.sendRecoveryEmail(context.WithoutCancel(ctx), user, *user.RecoveryEmail, "internal:user:account:recovery", data)
+		} else {
+			s.sendRecoveryEmail(ctx, user, *user.RecoveryEmail, "internal:user:account:recovery", data)
+		}
+	}
+	if safe {
+		padResponseTime(ctx, startTime)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+func (s *accountRecoveryService) CompleteRecovery(ctx context.Context, clientID, providerID string, in AccountRecoveryInput) (int64, error) {
+	_, span := otel.Tracer("service").Start(ctx, "accountRecovery.complete")
+	defer span.End()
+
+	limiterKey := security.RateLimitKey(strings.ToLower(in.Email), "account_recovery")
+	if err := s.checkRateLimit(limiterKey, in.Email); err != nil {
+		span.SetStatus(codes.Error, "rate limited")
+		return 0, err
+	}
+
+	client, err := s.clientRepo.FindByClientIDAndIdentityProvider(clientID, providerID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find client failed")
+		return 0, apperror.NewInternal("failed to find auth client", err)
+	}
+	if client == nil || client.IdentityProvider == nil {
+		span.SetStatus(codes.Error, "invalid client")
+		return 0, apperror.NewUnauthorized("invalid client credentials")
+	}
+	tenantID := client.IdentityProvider.TenantID
+
+	// The password policy is public, so it is checked before any factor
+	if err := checkTenantPassword(s.securitySettingRepo, tenantID, in.NewPassword); err != nil {
+		return 0, err
+	}
+	if err := ensurePasswordNotBreached(ctx, s.tenantSettingRepo, s.breachChecker, tenantID, in.NewPassword); err != nil {
+		return 0, err
+	}
+	hashedPassword, err := hashTenantPassword(s.securitySettingRepo, tenantID, in.NewPassword)
+	if err != nil {
+		return 0, apperror.NewInternal("failed to hash password", err)
+	}
+
+	var user *model.User
+	var remaining, revokedSessions int64
+	var reason string
+	var heldUser *model.User
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		txUserRepo := s.userRepo.WithTx(tx)
+		txUserTokenRepo := s.userTokenRepo.WithTx(tx)
+		txRecoveryCodeRepo := s.recoveryCodeRepo.WithTx(tx)
+
+		var err error
+		user, err = txUserRepo.FindByEmailAndTenantID(in.Email, tenantID)
+		if err != nil {
+			return err
+		}
+		if user == nil || !recoverable(user) {
+			reason = "unknown account or no verified recovery email"
+			return errInvalidRecovery
+		}
+
+		token, err := findUserTokenByCode(txUserTokenRepo, user.UserID, model.TokenTypeAccountRecovery, in.Code)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			reason = "invalid or expired email code"
+			return errInvalidRecovery
+		}
+		consumed, err := txRecoveryCodeRepo.Consume(user.UserID, hashAccountToken(normalizeRecoveryCode(in.RecoveryCode)), time.Now())
+		if err != nil {
+			return err
+		}
+		if !consumed {
+			reason = "invalid or used recovery code"
+			return errInvalidRecovery
+		}
+
+		if _, err := txUserRepo.UpdateByID(user.UserID, map[string]any{
+			"password": string(hashedPassword),
+		}); err != nil {
+			return err
+		}
+
+		// Whoever held the account is signed out, and pending reset links
+		// and recovery codes stop working
+		if revokedSessions, err = s.refreshTokenRepo.WithTx(tx).RevokeByUserID(user.UserID); err != nil {
+			return err
+		}
+		if err := revokeUserTokensOfType(txUserTokenRepo, user.UserID, model.TokenTypeAccountRecovery); err != nil {
+			return err
+		}
+		if err := revokeUserTokensOfType(txUserTokenRepo, user.UserID, model.TokenTypePasswordReset); err != nil {
+			return err
+		}
+
+		// Proving both factors clears a security hold
+		if user.Status == model.StatusSecurityHold {
+			if err := txUserRepo.SetStatus(user.UserUUID, model.StatusActive); err != nil {
+				return err
+			}
+			heldUser, err = txUserRepo.FindByUUID(user.UserUUID, "UserIdentities")
+			if err != nil {
+				return err
+			}
+			if err := recordEvent(s.eventRepo.WithTx(tx), tenantID, UserUpdated(newUserEventPayload(heldUser))); err != nil {
+				return err
+			}
+		}
+
+		remaining, err = txRecoveryCodeRepo.CountUnusedByUserID(user.UserID)
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "complete recovery failed")
+		if !errors.Is(err, errInvalidRecovery) {
+			return 0, err
+		}
+		security.RecordFailedAttempt(limiterKey)
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "account_recovery_failure",
+			UserID:    in.Email,
+			ClientIP:  middleware.ClientIPFromContext(ctx),
+			Details:   reason,
+			Severity:  "MEDIUM",
+			Timestamp: time.Now(),
+		})
+		if user != nil {
+			s.logRecovery(ctx, tenantID, user, model.AuthEventTypeRecoveryFail, model.AuthEventSeverityWarn, model.AuthEventResultFailure,
+				"Account recovery failed", &reason)
+		}
+		return 0, err
+	}
+
+	security.ResetFailedAttempts(limiterKey)
+	// The new password clears any login lockout, as a password reset does
+	security.ResetFailedAttempts(user.Email)
+	security.LogSecurityEvent(security.SecurityEvent{
+		EventType: "account_recovery_success",
+		UserID:    user.UserUUID.String(),
+		ClientIP:  middleware.ClientIPFromContext(ctx),
+		Details:   fmt.Sprintf("Account recovered, %d sessions revoked, %d recovery codes left", revokedSessions, remaining),
+		Severity:  "HIGH",
+		Timestamp: time.Now(),
+	})
+	s.logRecovery(ctx, tenantID, user, model.AuthEventTypeRecoverySuccess, model.AuthEventSeverityCritical, model.AuthEventResultSuccess,
+		fmt.Sprintf("Account recovered with a recovery code and the recovery email, %d sessions revoked", revokedSessions), nil)
+	if heldUser != nil {
+		for _, identity := range heldUser.UserIdentities {
+			s.cacheInvalidator.InvalidateUserAll(ctx, identity.Sub)
+		}
+		s.authEventService.Log(ctx, AuthEventInput{
+			TenantID:     tenantID,
+			ActorUserID:  &user.UserID,
+			TargetUserID: &user.UserID,
+			IPAddress:    middleware.ClientIPFromContext(ctx),
+			UserAgent:    ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+			Category:     model.AuthEventCategoryUser,
+			EventType:    model.AuthEventTypeUserSecurityHoldCleared,
+			Severity:     model.AuthEventSeverityWarn,
+			Result:       model.AuthEventResultSuccess,
+			Description:  ptr.Ptr("Security hold cleared by account recovery"),
+		})
+	}
+	s.notifyRecovered(ctx, user)
+
+	span.SetAttributes(attribute.String("user.uuid", user.UserUUID.String()), attribute.Int64("recovery_codes.remaining", remaining))
+	span.SetStatus(codes.Ok, "")
+	return remaining, nil
+}
+
+// issueRecoveryCodes replaces the user's recovery codes with a new set of
+// model.RecoveryCodeCount and returns them formatted for display. MFA
+// enrollment issues the first set through it.
+func issueRecoveryCodes(recoveryCodeRepo repository.RecoveryCodeRepository, userID int64) ([]string, error) {
+	recoveryCodes := make([]string, model.RecoveryCodeCount)
+	hashes := make([]string, model.RecoveryCodeCount)
+	for i := range recoveryCodes {
+		code := generateRecoveryCode()
+		recoveryCodes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = hashAccountToken(code)
+	}
+	if err := recoveryCodeRepo.ReplaceByUserID(userID, hashes); err != nil {
+		return nil, apperror.NewInternal("failed to store recovery codes", err)
+	}
+	return recoveryCodes, nil
+}
+
+// generateRecoveryCode returns 10 random base32 characters, 50 bits.
+// rand.Read always succeeds in Go 1.24+ (see go.dev/issue/66821).
+func generateRecoveryCode() string {
+	bytes := make([]byte, 7)
+	_, _ = rand.Read(bytes)
+	return recoveryCodeEncoding.EncodeToString(bytes)[:10]
+}
+
+// normalizeRecoveryCode undoes the display formatting of a recovery code,
+// so codes typed with other casing, spaces or without the dash still match.
+func normalizeRecoveryCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+}
+
+// recoverable reports whether the account may be recovered: it is active or
+// on security hold and has a verified recovery email to send the code to.
+func recoverable(user *model.User) bool {
+	return user.DeletedAt == nil &&
+		(user.Status == model.StatusActive || user.Status == model.StatusSecurityHold) &&
+		user.RecoveryEmail != nil && user.IsRecoveryEmailVerified
+}
+
+// findUserTokenByCode returns the user's unrevoked, unexpired token of the
+// type whose hash matches code, or nil.
+func findUserTokenByCode(userTokenRepo repository.UserTokenRepository, userID int64, tokenType, code string) (*model.UserToken, error) {
+	tokens, err := userTokenRepo.FindByUserIDAndTokenType(userID, tokenType)
+	if err != nil {
+		return nil, err
+	}
+	hash := []byte(hashAccountToken(code))
+	now := time.Now()
+	for i := range tokens {
+		token := &tokens[i]
+		if token.IsRevoked || (token.ExpiresAt != nil && now.After(*token.ExpiresAt)) {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(token.Token), hash) == 1 {
+			return token, nil
+		}
+	}
+	return nil, nil
+}
+
+// findUser loads the user fresh, as the recovery email of the cached user in
+// the request context may be stale.
+func (s *accountRecoveryService) findUser(userID int64) (*model.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to find user", err)
+	}
+	if user == nil || user.DeletedAt != nil {
+		return nil, apperror.NewNotFoundWithReason("user not found")
+	}
+	return user, nil
+}
+
+// authorizeChange loads the user and checks their password before a change
+// to their recovery factors, so a stolen access token cannot plant its own.
+// Wrong passwords count towards a lockout.
+func (s *accountRecoveryService) authorizeChange(ctx context.Context, userID, tenantID int64, password string) (*model.User, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	limiterKey := security.RateLimitKey(user.UserUUID.String(), "recovery_settings")
+	if err := s.checkRateLimit(limiterKey, user.UserUUID.String()); err != nil {
+		return nil, err
+	}
+
+	// Compare against a dummy hash when there is no password so the answer
+	// takes as long either way
+	storedHash := security.GetDummyBcryptHash()
+	if user.Password != nil {
+		storedHash = []byte(*user.Password)
+	}
+	if err := security.ComparePassword(storedHash, []byte(password)); err != nil || user.Password == nil {
+		security.RecordFailedAttempt(limiterKey)
+		reason := "password is incorrect"
+		s.logRecovery(ctx, tenantID, user, model.AuthEventTypeRecoveryEmailChange, model.AuthEventSeverityWarn, model.AuthEventResultFailure,
+			"Recovery settings change rejected", &reason)
+		return nil, apperror.NewValidation("password is incorrect")
+	}
+	security.ResetFailedAttempts(limiterKey)
+	return user, nil
+}
+
+// checkRateLimit rejects identifiers locked out after repeated failures.
+func (s *accountRecoveryService) checkRateLimit(limiterKey, identifier string) error {
+	if err := security.CheckRateLimit(limiterKey); err != nil {
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "account_recovery_rate_limited",
+			UserID:    identifier,
+			Timestamp: time.Now(),
+			Details:   err.Error(),
+		})
+		return apperror.NewForbidden("too many failed attempts, try again later")
+	}
+	return nil
+}
+
+// toDataResult describes the recovery factors of user.
+func (s *accountRecoveryService) toDataResult(recoveryCodeRepo repository.RecoveryCodeRepository, user *model.User) (*AccountRecoveryServiceDataResult, error) {
+	remaining, err := recoveryCodeRepo.CountUnusedByUserID(user.UserID)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to count recovery codes", err)
+	}
+	return &AccountRecoveryServiceDataResult{
+		RecoveryCodesRemaining: remaining,
+		RecoveryEmail:          user.RecoveryEmail,
+		RecoveryEmailVerified:  user.IsRecoveryEmailVerified,
+	}, nil
+}
+
+// logRecovery records a change to, or use of, the user's recovery factors in
+// the audit log.
+func (s *accountRecoveryService) logRecovery(ctx context.Context, tenantID int64, user *model.User, eventType, severity, result, description string, reason *string) {
+	s.authEventService.Log(ctx, AuthEventInput{
+		TenantID:     tenantID,
+		ActorUserID:  &user.UserID,
+		TargetUserID: &user.UserID,
+		IPAddress:    middleware.ClientIPFromContext(ctx),
+		UserAgent:    ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:     model.AuthEventCategoryAuthn,
+		EventType:    eventType,
+		Severity:     severity,
+		Result:       result,
+		Description:  ptr.Ptr(description),
+		ErrorReason:  reason,
+	})
+}
+
+// notifyRecovered tells the user at both addresses that the account was
+// recovered, so an unexpected recovery does not go unnoticed.
+func (s *accountRecoveryService) notifyRecovered(ctx context.Context, user *model.User) {
+	data := struct {
+		RecoveredAt string
+		LogoURL     string
+	}{
+		RecoveredAt: time.Now().UTC().Format("January 2, 2006 at 15:04 UTC"),
+		LogoURL:     config.EmailLogo,
+	}
+	if user.Email != "" {
+		s.sendRecoveryEmail(ctx, user, user.Email, "internal:user:account:recovered", data)
+	}
+	s.sendRecoveryEmail(ctx, user, *user.RecoveryEmail, "internal:user:account:recovered", data)
+}
+
+// sendRecoveryEmail sends the email of the named template, logging rather
+// than returning failures: the change it belongs to is already made.
+func (s *accountRecoveryService) sendRecoveryEmail(ctx context.Context, user *model.User, to, templateName string, data any) {
+	if err := sendTemplateEmail(ctx, s.emailTemplateRepo, to, templateName, data); err != nil {
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "account_email_failure",
+			UserID:    user.UserUUID.String(),
+			Details:   fmt.Sprintf("Failed to send %s email: %v", templateName, err),
+			Severity:  "HIGH",
+			Timestamp: time.Now(),
+		})
+	}
+}
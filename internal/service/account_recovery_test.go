@@ -0,0 +1,425 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	recoveryPassword    = "Secret-123"
+	recoveryNewPassword = "N3w-Recovered-Passw0rd!"
+	recoveryOTP         = "123456"
+)
+
+// newRecoveryUser returns an active user of tenant 2 with a verified
+// recovery email and recoveryPassword as password.
+func newRecoveryUser(t *testing.T) *model.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(recoveryPassword), bcrypt.MinCost)
+	require.NoError(t, err)
+	return &model.User{
+		UserID:                  9,
+		UserUUID:                uuid.New(),
+		Email:                   "jane@example.com",
+		Password:                ptr.Ptr(string(hash)),
+		Status:                  model.StatusActive,
+		RecoveryEmail:           ptr.Ptr("jane@backup.example.com"),
+		IsRecoveryEmailVerified: true,
+	}
+}
+
+// newRecoveryToken returns an unexpired token of tokenType for recoveryOTP.
+func newRecoveryToken(tokenType string) model.UserToken {
+	return model.UserToken{
+		UserID:    9,
+		TokenType: tokenType,
+		Token:     hashAccountToken(recoveryOTP),
+		ExpiresAt: ptr.TimePtr(time.Now().Add(time.Minute)),
+	}
+}
+
+// recoveryUserRepo returns a user repo that finds user in tenant 2 and
+// records its updates.
+func recoveryUserRepo(t *testing.T, user *model.User, updates *[]map[string]any) *mockUserRepo {
+	return &mockUserRepo{
+		findByIDFn: func(any, ...string) (*model.User, error) { return user, nil },
+		findByEmailAndTenantIDFn: func(email string, tenantID int64) (*model.User, error) {
+			assert.Equal(t, int64(2), tenantID)
+			if email != user.Email {
+				return nil, nil
+			}
+			return user, nil
+		},
+		updateByIDFn: func(_, data any) (*model.User, error) {
+			*updates = append(*updates, data.(map[string]any))
+			return user, nil
+		},
+	}
+}
+
+// recoveryTokenRepo returns a token repo that finds a valid token of the
+// requested type and records the tokens it creates.
+func recoveryTokenRepo(created *[]*model.UserToken) *mockUserTokenRepo {
+	return &mockUserTokenRepo{
+		findByUserIDAndTokenTypeFn: func(_ int64, tokenType string) ([]model.UserToken, error) {
+			return []model.UserToken{newRecoveryToken(tokenType)}, nil
+		},
+		createFn: func(tok *model.UserToken) (*model.UserToken, error) {
+			*created = append(*created, tok)
+			return tok, nil
+		},
+	}
+}
+
+func newAccountRecoveryService(db *gorm.DB, userRepo *mockUserRepo, tokenRepo *mockUserTokenRepo, codeRepo *mockRecoveryCodeRepo, refreshTokenRepo *mockOAuthRefreshTokenRepo, authEvents AuthEventService) AccountRecoveryService {
+	if codeRepo.countUnusedByUserIDFn == nil {
+		codeRepo.countUnusedByUserIDFn = func(int64) (int64, error) { return 4, nil }
+	}
+	clientRepo := &mockClientRepo{findByClientIDAndIdentityProviderFn: func(string, string) (*model.Client, error) {
+		return &model.Client{IdentityProvider: &model.IdentityProvider{TenantID: 2}}, nil
+	}}
+	return NewAccountRecoveryService(db, userRepo, tokenRepo, codeRepo, clientRepo, refreshTokenRepo,
+		&mockEmailTemplateRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockEventRepo{}, nil, authEvents, cache.NopInvalidator{})
+}
+
+func TestAccountRecoveryService_Status(t *testing.T) {
+	gormDB, _ := newMockGormDB(t)
+	var updates []map[string]any
+	svc := newAccountRecoveryService(gormDB, recoveryUserRepo(t, newRecoveryUser(t), &updates), &mockUserTokenRepo{}, &mockRecoveryCodeRepo{}, &mockOAuthRefreshTokenRepo{}, &mockAuthEventService{})
+
+	res, err := svc.Status(context.Background(), 9)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), res.RecoveryCodesRemaining)
+	assert.Equal(t, "jane@backup.example.com", *res.RecoveryEmail)
+	assert.True(t, res.RecoveryEmailVerified)
+}
+
+func TestAccountRecoveryService_GenerateCodes(t *testing.T) {
+	t.Run("stores only the hashes", func(t *testing.T) {
+		gormDB, _ := newMockGormDB(t)
+		var updates []map[string]any
+		var stored []string
+		var logged []AuthEventInput
+		codeRepo := &mockRecoveryCodeRepo{replaceByUserIDFn: func(userID int64, hashes []string) error {
+			assert.Equal(t, int64(9), userID)
+			stored = hashes
+			return nil
+		}}
+		svc := newAccountRecoveryService(gormDB, recoveryUserRepo(t, newRecoveryUser(t), &updates), &mockUserTokenRepo{}, codeRepo, &mockOAuthRefreshTokenRepo{},
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) }})
+
+		recoveryCodes, err := svc.GenerateCodes(context.Background(), 9, 2, recoveryPassword)
+		require.NoError(t, err)
+		require.Len(t, recoveryCodes, model.RecoveryCodeCount)
+		require.Len(t, stored, model.RecoveryCodeCount)
+		for i, c := range recoveryCodes {
+			assert.Regexp(t, regexp.MustCompile(`^[a-z2-7]{5}-[a-z2-7]{5}$`), c)
+			assert.Equal(t, hashAccountToken(normalizeRecoveryCode(c)), stored[i])
+		}
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeRecoveryCodesIssued, logged[0].EventType)
+	})
+
+	t.Run("wrong password → validation error", func(t *testing.T) {
+		gormDB, _ := newMockGormDB(t)
+		var updates []map[string]any
+		var logged []AuthEventInput
+		codeRepo := &mockRecoveryCodeRepo{replaceByUserIDFn: func(int64, []string) error {
+			t.Fatal("codes should not be replaced")
+			return nil
+		}}
+		svc := newAccountRecoveryService(gormDB, recoveryUserRepo(t, newRecoveryUser(t), &updates), &mockUserTokenRepo{}, codeRepo, &mockOAuthRefreshTokenRepo{},
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) }})
+
+		_, err := svc.GenerateCodes(context.Background(), 9, 2, "wrong")
+		var target *apperror.ValidationError
+		require.ErrorAs(t, err, &target)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventResultFailure, logged[0].Result)
+	})
+}
+
+func TestAccountRecoveryService_SetRecoveryEmail(t *testing.T) {
+	origOTP := crypto.GenerateOTP
+	defer func() { crypto.GenerateOTP = origOTP }()
+	crypto.GenerateOTP = func(int) (string, error) { return recoveryOTP, nil }
+
+	t.Run("sends a verification code", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		var updates []map[string]any
+		var created []*model.UserToken
+		var logged []AuthEventInput
+		svc := newAccountRecoveryService(gormDB, recoveryUserRepo(t, newRecoveryUser(t), &updates), recoveryTokenRepo(&created), &mockRecoveryCodeRepo{}, &mockOAuthRefreshTokenRepo{},
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) }})
+
+		res, err := svc.SetRecoveryEmail(context.Background(), 9, 2, recoveryPassword, "new@backup.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "new@backup.example.com", *res.RecoveryEmail)
+		assert.False(t, res.RecoveryEmailVerified)
+		require.Len(t, updates, 1)
+		assert.Equal(t, false, updates[0]["is_recovery_email_verified"])
+		require.Len(t, created, 1)
+		assert.Equal(t, model.TokenTypeRecoveryEmail, created[0].TokenType)
+		assert.Equal(t, hashAccountToken(recoveryOTP), created[0].Token)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeRecoveryEmailChange, logged[0].EventType)
+	})
+
+	t.Run("account email → validation error", func(t *testing.T) {
+		gormDB, _ := newMockGormDB(t)
+		var updates []map[string]any
+		svc := newAccountRecoveryService(gormDB, recoveryUserRepo(t, newRecoveryUser(t), &updates), &mockUserTokenRepo{}, &mockRecoveryCodeRepo{}, &mockOAuthRefreshTokenRepo{}, &mockAuthEventService{})
+
+		_, err := svc.SetRecoveryEmail(context.Background(), 9, 2, recoveryPassword, "JANE@example.com")
+		var target *apperror.ValidationError
+		require.ErrorAs(t, err, &target)
+		assert.Empty(t, updates)
+	})
+}
+
+func TestAccountRecoveryService_VerifyRecoveryEmail(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		user := newRecoveryUser(t)
+		user.IsRecoveryEmailVerified = false
+		var updates []map[string]any
+		var created []*model.UserToken
+		svc := newAccountRecoveryService(gormDB, recoveryUserRepo(t, user, &updates), recoveryTokenRepo(&created), &mockRecoveryCodeRepo{}, &mockOAuthRefreshTokenRepo{}, &mockAuthEventService{})
+
+		res, err := svc.VerifyRecoveryEmail(context.Background(), 9, 2, recoveryOTP)
+		require.NoError(t, err)
+		assert.True(t, res.RecoveryEmailVerified)
+		require.Len(t, updates, 1)
+		assert.Equal(t, true, updates[0]["is_recovery_email_verified"])
+	})
+
+	t.Run("wrong code → error", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		var updates []map[string]any
+		var created []*model.UserToken
+		svc := newAccountRecoveryService(gormDB, recoveryUserRepo(t, newRecoveryUser(t), &updates), recoveryTokenRepo(&created), &mockRecoveryCodeRepo{}, &mockOAuthRefreshTokenRepo{}, &mockAuthEventService{})
+
+		_, err := svc.VerifyRecoveryEmail(context.Background(), 9, 2, "654321")
+		require.ErrorIs(t, err, errInvalidRecoveryEmailCode)
+		assert.Empty(t, updates)
+	})
+}
+
+func TestAccountRecoveryService_StartRecovery(t *testing.T) {
+	origOTP := crypto.GenerateOTP
+	defer func() { crypto.GenerateOTP = origOTP }()
+	crypto.GenerateOTP = func(int) (string, error) { return recoveryOTP, nil }
+
+	t.Run("emails a code", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		var updates []map[string]any
+		var created []*model.UserToken
+		var logged []AuthEventInput
+		svc := newAccountRecoveryService(gormDB, recoveryUserRepo(t, newRecoveryUser(t), &updates), recoveryTokenRepo(&created), &mockRecoveryCodeRepo{}, &mockOAuthRefreshTokenRepo{},
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) }})
+
+		require.NoError(t, svc.StartRecovery(context.Background(), "client", "provider", "jane@example.com"))
+		require.Len(t, created, 1)
+		assert.Equal(t, model.TokenTypeAccountRecovery, created[0].TokenType)
+		assert.Equal(t, hashAccountToken(recoveryOTP), created[0].Token)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeRecoveryStart, logged[0].EventType)
+	})
+
+	t.Run("no verified recovery email → nothing sent", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		user := newRecoveryUser(t)
+		user.IsRecoveryEmailVerified = false
+		var updates []map[string]any
+		var created []*model.UserToken
+		var logged []AuthEventInput
+		svc := newAccountRecoveryService(gormDB, recoveryUserRepo(t, user, &updates), recoveryTokenRepo(&created), &mockRecoveryCodeRepo{}, &mockOAuthRefreshTokenRepo{},
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) }})
+
+		require.NoError(t, svc.StartRecovery(context.Background(), "client", "provider", "jane@example.com"))
+		assert.Empty(t, created)
+		assert.Empty(t, logged)
+	})
+
+	t.Run("unknown account → nothing sent", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		var updates []map[string]any
+		var created []*model.UserToken
+		svc := newAccountRecoveryService(gormDB, recoveryUserRepo(t, newRecoveryUser(t), &updates), recoveryTokenRepo(&created), &mockRecoveryCodeRepo{}, &mockOAuthRefreshTokenRepo{}, &mockAuthEventService{})
+
+		require.NoError(t, svc.StartRecovery(context.Background(), "client", "provider", "nobody@example.com"))
+		assert.Empty(t, created)
+	})
+}
+
+func TestAccountRecoveryService_CompleteRecovery(t *testing.T) {
+	input := AccountRecoveryInput{
+		Email:        "jane@example.com",
+		RecoveryCode: "abcde-fghij",
+		Code:         recoveryOTP,
+		NewPassword:  recoveryNewPassword,
+	}
+
+	t.Run("sets the password and signs out", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		var updates []map[string]any
+		var created []*model.UserToken
+		var logged []AuthEventInput
+		var signedOut bool
+		codeRepo := &mockRecoveryCodeRepo{consumeFn: func(_ int64, codeHash string, _ time.Time) (bool, error) {
+			assert.Equal(t, hashAccountToken("abcdefghij"), codeHash)
+			return true, nil
+		}}
+		svc := newAccountRecoveryService(gormDB, recoveryUserRepo(t, newRecoveryUser(t), &updates), recoveryTokenRepo(&created), codeRepo,
+			&mockOAuthRefreshTokenRepo{revokeByUserIDFn: func(int64) (int64, error) { signedOut = true; return 2, nil }},
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) }})
+
+		in := input
+		in.RecoveryCode = " ABCDE-fghij "
+		remaining, err := svc.CompleteRecovery(context.Background(), "client", "provider", in)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), remaining)
+		assert.True(t, signedOut)
+		require.Len(t, updates, 1)
+		require.NoError(t, security.ComparePassword([]byte(updates[0]["password"].(string)), []byte(recoveryNewPassword)))
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeRecoverySuccess, logged[0].EventType)
+		assert.Equal(t, model.AuthEventSeverityCritical, logged[0].Severity)
+	})
+
+	t.Run("clears a security hold", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		user := newRecoveryUser(t)
+		user.Status = model.StatusSecurityHold
+		var updates []map[string]any
+		var created []*model.UserToken
+		var logged []AuthEventInput
+		var status string
+		userRepo := recoveryUserRepo(t, user, &updates)
+		userRepo.setStatusFn = func(_ uuid.UUID, s string) error { status = s; return nil }
+		userRepo.findByUUIDFn = func(any, ...string) (*model.User, error) { return user, nil }
+		codeRepo := &mockRecoveryCodeRepo{consumeFn: func(int64, string, time.Time) (bool, error) { return true, nil }}
+		svc := newAccountRecoveryService(gormDB, userRepo, recoveryTokenRepo(&created), codeRepo, &mockOAuthRefreshTokenRepo{},
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) }})
+
+		_, err := svc.CompleteRecovery(context.Background(), "client", "provider", input)
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusActive, status)
+		require.Len(t, logged, 2)
+		assert.Equal(t, model.AuthEventTypeUserSecurityHoldCleared, logged[1].EventType)
+	})
+
+	t.Run("used recovery code → invalid recovery", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		var updates []map[string]any
+		var created []*model.UserToken
+		var logged []AuthEventInput
+		svc := newAccountRecoveryService(gormDB, recoveryUserRepo(t, newRecoveryUser(t), &updates), recoveryTokenRepo(&created), &mockRecoveryCodeRepo{}, &mockOAuthRefreshTokenRepo{},
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) }})
+
+		_, err := svc.CompleteRecovery(context.Background(), "client", "provider", input)
+		require.ErrorIs(t, err, errInvalidRecovery)
+		assert.Empty(t, updates)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeRecoveryFail, logged[0].EventType)
+		assert.Equal(t, "invalid or used recovery code", *logged[0].ErrorReason)
+	})
+
+	t.Run("wrong email code → invalid recovery", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		var updates []map[string]any
+		var logged []AuthEventInput
+		codeRepo := &mockRecoveryCodeRepo{consumeFn: func(int64, string, time.Time) (bool, error) {
+			t.Fatal("recovery code should not be consumed")
+			return false, nil
+		}}
+		svc := newAccountRecoveryService(gormDB, recoveryUserRepo(t, newRecoveryUser(t), &updates), &mockUserTokenRepo{}, codeRepo, &mockOAuthRefreshTokenRepo{},
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) }})
+
+		_, err := svc.CompleteRecovery(context.Background(), "client", "provider", input)
+		require.ErrorIs(t, err, errInvalidRecovery)
+		require.Len(t, logged, 1)
+		assert.Equal(t, "invalid or expired email code", *logged[0].ErrorReason)
+	})
+
+	t.Run("unknown account → invalid recovery", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		var updates []map[string]any
+		var logged []AuthEventInput
+		svc := newAccountRecoveryService(gormDB, recoveryUserRepo(t, newRecoveryUser(t), &updates), &mockUserTokenRepo{}, &mockRecoveryCodeRepo{}, &mockOAuthRefreshTokenRepo{},
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) }})
+
+		in := input
+		in.Email = "nobody@example.com"
+		_, err := svc.CompleteRecovery(context.Background(), "client", "provider", in)
+		require.ErrorIs(t, err, errInvalidRecovery)
+		assert.Empty(t, logged)
+	})
+
+	t.Run("locked out → forbidden", func(t *testing.T) {
+		cleanup := lockedRateLimiterLogin(t, security.RateLimitKey("jane@example.com", "account_recovery"))
+		defer cleanup()
+		gormDB, _ := newMockGormDB(t)
+		var updates []map[string]any
+		svc := newAccountRecoveryService(gormDB, recoveryUserRepo(t, newRecoveryUser(t), &updates), &mockUserTokenRepo{}, &mockRecoveryCodeRepo{}, &mockOAuthRefreshTokenRepo{}, &mockAuthEventService{})
+
+		_, err := svc.CompleteRecovery(context.Background(), "client", "provider", input)
+		var target *apperror.ForbiddenError
+		require.ErrorAs(t, err, &target)
+	})
+
+	t.Run("store error → propagated", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		var updates []map[string]any
+		var logged []AuthEventInput
+		tokenRepo := &mockUserTokenRepo{findByUserIDAndTokenTypeFn: func(int64, string) ([]model.UserToken, error) {
+			return nil, errors.New("db error")
+		}}
+		svc := newAccountRecoveryService(gormDB, recoveryUserRepo(t, newRecoveryUser(t), &updates), tokenRepo, &mockRecoveryCodeRepo{}, &mockOAuthRefreshTokenRepo{},
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) }})
+
+		_, err := svc.CompleteRecovery(context.Background(), "client", "provider", input)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, errInvalidRecovery)
+		assert.Empty(t, logged)
+	})
+}
@@ -26,6 +26,7 @@ type APIServiceDataResult struct {
 	Service     *ServiceServiceDataResult
 	Status      string
 	IsSystem    bool
+	Deprecation *DeprecationServiceDataResult
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -57,10 +58,12 @@ type APIService interface {
 	Get(ctx context.Context, filter APIServiceGetFilter) (*APIServiceGetResult, error)
 	GetByUUID(ctx context.Context, apiUUID uuid.UUID, tenantID int64) (*APIServiceDataResult, error)
 	GetServiceIDByUUID(ctx context.Context, serviceUUID uuid.UUID) (int64, error)
-	Create(ctx context.Context, tenantID int64, name string, displayName string, description string, apiType string, status string, isSystem bool, serviceUUID string) (*APIServiceDataResult, error)
+	Create(ctx context.Context, tenantID int64, name string, displayName string, description string, apiType string, status string, isSystem bool, serviceUUID string, identifier string) (*APIServiceDataResult, error)
 	Update(ctx context.Context, apiUUID uuid.UUID, tenantID int64, name string, displayName string, description string, apiType string, status string, serviceUUID string) (*APIServiceDataResult, error)
 	SetStatusByUUID(ctx context.Context, apiUUID uuid.UUID, tenantID int64, status string) (*APIServiceDataResult, error)
 	DeleteByUUID(ctx context.Context, apiUUID uuid.UUID, tenantID int64) (*APIServiceDataResult, error)
+	SetDeprecationByUUID(ctx context.Context, apiUUID uuid.UUID, tenantID int64, sunsetAt *time.Time, notice *string) (*APIServiceDataResult, error)
+	ClearDeprecationByUUID(ctx context.Context, apiUUID uuid.UUID, tenantID int64) (*APIServiceDataResult, error)
 }
 
 type apiService struct {
@@ -169,7 +172,7 @@ func (s *apiService) GetServiceIDByUUID(ctx context.Context, serviceUUID uuid.UU
 	return service.ServiceID, nil
 }
 
-func (s *apiService) Create(ctx context.Context, tenantID int64, name string, displayName string, description string, apiType string, status string, isSystem bool, serviceUUID string) (*APIServiceDataResult, error) {
+func (s *apiService) Create(ctx context.Context, tenantID int64, name string, displayName string, description string, apiType string, status string, isSystem bool, serviceUUID string, identifier string) (*APIServiceDataResult, error) {
 	_, span := otel.Tracer("service").Start(ctx, "api.create")
 	defer span.End()
 	span.SetAttributes(
@@ -198,12 +201,23 @@ func (s *apiService) Create(ctx context.Context, tenantID int64, name string, di
 			return apperror.NewNotFound("service not found")
 		}
 
-		// Generate identifier
-		idSuffix, err := crypto.GenerateIdentifier(12)
-		if err != nil {
-			return err
+		// The identifier is the audience of the API's tokens, so it must be
+		// unique within the tenant. Generate one when none is given.
+		if identifier != "" {
+			existingAPI, err := txAPIRepo.FindByIdentifier(identifier, tenantID)
+			if err != nil {
+				return err
+			}
+			if existingAPI != nil {
+				return apperror.NewConflict(identifier + " identifier is already in use")
+			}
+		} else {
+			idSuffix, err := crypto.GenerateIdentifier(12)
+			if err != nil {
+				return err
+			}
+			identifier = fmt.Sprintf("api-%s", idSuffix)
 		}
-		identifier := fmt.Sprintf("api-%s", idSuffix)
 
 		// Create api
 		newAPI := &model.API{
@@ -442,6 +456,110 @@ func (s *apiService) DeleteByUUID(ctx context.Context, apiUUID uuid.UUID, tenant
 }
 
 // Reponse builder
+// SetDeprecationByUUID marks an API as deprecated. Clients linked to it keep
+// obtaining tokens with warning headers until the optional sunset date, after
+// which issuance is refused.
+func (s *apiService) SetDeprecationByUUID(ctx context.Context, apiUUID uuid.UUID, tenantID int64, sunsetAt *time.Time, notice *string) (*APIServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "api.setDeprecation")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("api.uuid", apiUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	var updatedAPI *model.API
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		api, err := s.findMutableAPI(tx, apiUUID, tenantID)
+		if err != nil {
+			return err
+		}
+
+		if err := applyDeprecation(&api.Deprecation, sunsetAt, notice, time.Now()); err != nil {
+			return err
+		}
+
+		if _, err := s.apiRepo.WithTx(tx).CreateOrUpdate(api); err != nil {
+			return err
+		}
+
+		updatedAPI = api
+
+		return nil
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to deprecate api")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toAPIServiceDataResult(updatedAPI), nil
+}
+
+// ClearDeprecationByUUID removes the deprecation and sunset date from an API.
+func (s *apiService) ClearDeprecationByUUID(ctx context.Context, apiUUID uuid.UUID, tenantID int64) (*APIServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "api.clearDeprecation")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("api.uuid", apiUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	var updatedAPI *model.API
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		api, err := s.findMutableAPI(tx, apiUUID, tenantID)
+		if err != nil {
+			return err
+		}
+
+		clearDeprecation(&api.Deprecation)
+
+		if _, err := s.apiRepo.WithTx(tx).CreateOrUpdate(api); err != nil {
+			return err
+		}
+
+		updatedAPI = api
+
+		return nil
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to clear api deprecation")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toAPIServiceDataResult(updatedAPI), nil
+}
+
+// findMutableAPI loads a non-system API owned by the tenant.
+func (s *apiService) findMutableAPI(tx *gorm.DB, apiUUID uuid.UUID, tenantID int64) (*model.API, error) {
+	api, err := s.apiRepo.WithTx(tx).FindByUUIDAndTenantID(apiUUID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if api == nil {
+		return nil, apperror.NewNotFoundWithReason("api not found or access denied")
+	}
+
+	if api.ServiceID > 0 {
+		tenantService, err := s.tenantServiceRepo.WithTx(tx).FindByTenantAndService(tenantID, api.ServiceID)
+		if err != nil || tenantService == nil {
+			return nil, apperror.NewNotFoundWithReason("api not found or access denied")
+		}
+	}
+
+	if api.IsSystem {
+		return nil, apperror.NewValidation("system API cannot be updated")
+	}
+
+	return api, nil
+}
+
 func toAPIServiceDataResult(api *model.API) *APIServiceDataResult {
 	if api == nil {
 		return nil
@@ -456,6 +574,7 @@ func toAPIServiceDataResult(api *model.API) *APIServiceDataResult {
 		Identifier:  api.Identifier,
 		Status:      api.Status,
 		IsSystem:    api.IsSystem,
+		Deprecation: toDeprecationServiceDataResult(api.Deprecation),
 		CreatedAt:   api.CreatedAt,
 		UpdatedAt:   api.UpdatedAt,
 	}
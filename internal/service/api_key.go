@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/repository"
 	"go.opentelemetry.io/otel"
@@ -76,17 +77,22 @@ type APIKeyService interface {
 	Update(ctx context.Context, apiKeyUUID uuid.UUID, tenantID int64, name, description *string, config datatypes.JSON, expiresAt *time.Time, rateLimit *int, status *string, updaterUserUUID uuid.UUID) (*APIKeyServiceDataResult, error)
 	SetStatusByUUID(ctx context.Context, apiKeyUUID uuid.UUID, tenantID int64, status string) (*APIKeyServiceDataResult, error)
 	Delete(ctx context.Context, apiKeyUUID uuid.UUID, tenantID int64, deleterUserUUID uuid.UUID) (*APIKeyServiceDataResult, error)
-	ValidateAPIKey(ctx context.Context, keyHash string) (*APIKeyServiceDataResult, error)
+	// ValidateAPIKey returns the active, unexpired API key with the given
+	// plain-text value, with its APIs and permissions loaded.
+	ValidateAPIKey(ctx context.Context, apiKey string) (*model.APIKey, error)
+	// FlushUsage writes the request counts buffered in Redis to the database
+	// and returns the number of keys updated.
+	FlushUsage(ctx context.Context) (int64, error)
 
 	// API Key API methods
-	GetAPIKeyAPIs(ctx context.Context, apiKeyUUID uuid.UUID, page, limit int, sortBy, sortOrder string) (*APIKeyAPIServicePaginatedResult, error)
-	AddAPIKeyAPIs(ctx context.Context, apiKeyUUID uuid.UUID, apiUUIDs []uuid.UUID) error
-	RemoveAPIKeyAPI(ctx context.Context, apiKeyUUID uuid.UUID, apiUUID uuid.UUID) error
+	GetAPIKeyAPIs(ctx context.Context, apiKeyUUID uuid.UUID, tenantID int64, page, limit int, sortBy, sortOrder string) (*APIKeyAPIServicePaginatedResult, error)
+	AddAPIKeyAPIs(ctx context.Context, apiKeyUUID uuid.UUID, tenantID int64, apiUUIDs []uuid.UUID) error
+	RemoveAPIKeyAPI(ctx context.Context, apiKeyUUID uuid.UUID, tenantID int64, apiUUID uuid.UUID) error
 
 	// API Key API Permission methods
-	GetAPIKeyAPIPermissions(ctx context.Context, apiKeyUUID uuid.UUID, apiUUID uuid.UUID) ([]PermissionServiceDataResult, error)
-	AddAPIKeyAPIPermissions(ctx context.Context, apiKeyUUID uuid.UUID, apiUUID uuid.UUID, permissionUUIDs []uuid.UUID) error
-	RemoveAPIKeyAPIPermission(ctx context.Context, apiKeyUUID uuid.UUID, apiUUID uuid.UUID, permissionUUID uuid.UUID) error
+	GetAPIKeyAPIPermissions(ctx context.Context, apiKeyUUID uuid.UUID, tenantID int64, apiUUID uuid.UUID) ([]PermissionServiceDataResult, error)
+	AddAPIKeyAPIPermissions(ctx context.Context, apiKeyUUID uuid.UUID, tenantID int64, apiUUID uuid.UUID, permissionUUIDs []uuid.UUID) error
+	RemoveAPIKeyAPIPermission(ctx context.Context, apiKeyUUID uuid.UUID, tenantID int64, apiUUID uuid.UUID, permissionUUID uuid.UUID) error
 }
 
 type apiKeyService struct {
@@ -97,6 +103,8 @@ type apiKeyService struct {
 	apiRepo              repository.APIRepository
 	userRepo             repository.UserRepository
 	permissionRepo       repository.PermissionRepository
+	eventRepo            repository.EventRepository
+	usageStore           cache.APIKeyUsageStore
 }
 
 func NewAPIKeyService(
@@ -107,6 +115,8 @@ func NewAPIKeyService(
 	apiRepo repository.APIRepository,
 	userRepo repository.UserRepository,
 	permissionRepo repository.PermissionRepository,
+	eventRepo repository.EventRepository,
+	usageStore cache.APIKeyUsageStore,
 ) APIKeyService {
 	return &apiKeyService{
 		db:                   db,
@@ -116,6 +126,8 @@ func NewAPIKeyService(
 		apiRepo:              apiRepo,
 		userRepo:             userRepo,
 		permissionRepo:       permissionRepo,
+		eventRepo:            eventRepo,
+		usageStore:           usageStore,
 	}
 }
 
@@ -129,8 +141,7 @@ func (s *apiKeyService) generateAPIKey() (string, string, string) {
 	apiKey := "ak_" + hex.EncodeToString(bytes)
 
 	// Create hash for storage
-	hash := sha256.Sum256([]byte(apiKey))
-	keyHash := hex.EncodeToString(hash[:])
+	keyHash := hashAPIKey(apiKey)
 
 	// Get prefix for identification (first 12 characters)
 	keyPrefix := apiKey[:12]
@@ -138,6 +149,13 @@ func (s *apiKeyService) generateAPIKey() (string, string, string) {
 	return apiKey, keyHash, keyPrefix
 }
 
+// hashAPIKey returns the hex SHA-256 of a plain-text API key, as stored in
+// KeyHash.
+func hashAPIKey(apiKey string) string {
+	hash := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(hash[:])
+}
+
 func (s *apiKeyService) Get(ctx context.Context, filter APIKeyServiceGetFilter, requestingUserUUID uuid.UUID) (*APIKeyServiceGetResult, error) {
 	_, span := otel.Tracer("service").Start(ctx, "api_key.list")
 	defer span.End()
@@ -315,7 +333,7 @@ func (s *apiKeyService) Create(ctx context.Context, tenantID int64, name, descri
 			return err
 		}
 
-		return nil
+		return recordEvent(s.eventRepo.WithTx(tx), tenantID, APIKeyCreated(newAPIKeyEventPayload(createdAPIKey)))
 	})
 
 	if err != nil {
@@ -364,6 +382,9 @@ func (s *apiKeyService) Update(ctx context.Context, apiKeyUUID uuid.UUID, tenant
 		if existing == nil {
 			return apperror.NewNotFoundWithReason("API key not found or access denied")
 		}
+		if existing.Status == model.StatusRevoked {
+			return apperror.NewValidation("revoked API keys cannot be updated")
+		}
 
 		// Prepare update data
 		updateData := make(map[string]any)
@@ -391,6 +412,9 @@ func (s *apiKeyService) Update(ctx context.Context, apiKeyUUID uuid.UUID, tenant
 		if err != nil {
 			return err
 		}
+		if err := recordAPIKeyChange(s.eventRepo.WithTx(tx), updatedAPIKey); err != nil {
+			return err
+		}
 
 		// Convert to service result
 		result = &APIKeyServiceDataResult{
@@ -454,13 +478,19 @@ func (s *apiKeyService) Delete(ctx context.Context, apiKeyUUID uuid.UUID, tenant
 			UpdatedAt:   apiKey.UpdatedAt,
 		}
 
-		// Delete the API key (CASCADE will handle related records)
-		err = apiKeyRepo.DeleteByUUID(apiKeyUUID)
-		if err != nil {
+		// Remove permission grants before the API links they hang off, then
+		// the key itself
+		if err := s.apiKeyPermissionRepo.WithTx(tx).DeleteByAPIKeyID(apiKey.APIKeyID); err != nil {
+			return err
+		}
+		if err := s.apiKeyAPIRepo.WithTx(tx).DeleteByAPIKeyID(apiKey.APIKeyID); err != nil {
+			return err
+		}
+		if err := apiKeyRepo.DeleteByUUID(apiKeyUUID); err != nil {
 			return err
 		}
 
-		return nil
+		return recordEvent(s.eventRepo.WithTx(tx), tenantID, APIKeyDeleted(newAPIKeyEventPayload(apiKey)))
 	})
 
 	if err != nil {
@@ -494,6 +524,9 @@ func (s *apiKeyService) SetStatusByUUID(ctx context.Context, apiKeyUUID uuid.UUI
 		if existing == nil {
 			return apperror.NewNotFoundWithReason("API key not found or access denied")
 		}
+		if existing.Status == model.StatusRevoked {
+			return apperror.NewValidation("revoked API keys cannot change status")
+		}
 
 		// Update status using base repository method
 		updateData := map[string]any{
@@ -503,6 +536,9 @@ func (s *apiKeyService) SetStatusByUUID(ctx context.Context, apiKeyUUID uuid.UUI
 		if err != nil {
 			return err
 		}
+		if err := recordAPIKeyChange(s.eventRepo.WithTx(tx), updatedAPIKey); err != nil {
+			return err
+		}
 
 		// Map to result
 		result = &APIKeyServiceDataResult{
@@ -531,11 +567,28 @@ func (s *apiKeyService) SetStatusByUUID(ctx context.Context, apiKeyUUID uuid.UUI
 	return result, nil
 }
 
+// recordAPIKeyChange records api_key.revoked when k has just been revoked and
+// api_key.updated otherwise.
+func recordAPIKeyChange(eventRepo repository.EventRepository, k *model.APIKey) error {
+	payload := newAPIKeyEventPayload(k)
+	if k.Status == model.StatusRevoked {
+		return recordEvent(eventRepo, k.TenantID, APIKeyRevoked(payload))
+	}
+	return recordEvent(eventRepo, k.TenantID, APIKeyUpdated(payload))
+}
+
 // Get APIs assigned to API key with pagination
-func (s *apiKeyService) GetAPIKeyAPIs(ctx context.Context, apiKeyUUID uuid.UUID, page, limit int, sortBy, sortOrder string) (*APIKeyAPIServicePaginatedResult, error) {
+func (s *apiKeyService) GetAPIKeyAPIs(ctx context.Context, apiKeyUUID uuid.UUID, tenantID int64, page, limit int, sortBy, sortOrder string) (*APIKeyAPIServicePaginatedResult, error) {
 	_, span := otel.Tracer("service").Start(ctx, "api_key.getAPIs")
 	defer span.End()
-	span.SetAttributes(attribute.String("api_key.uuid", apiKeyUUID.String()))
+	span.SetAttributes(attribute.String("api_key.uuid", apiKeyUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	if _, err := findTenantAPIKey(s.apiKeyRepo, apiKeyUUID, tenantID); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "api key not found")
+		return nil, err
+	}
+
 	// Set defaults for pagination
 	if page <= 0 {
 		page = 1
@@ -603,10 +656,10 @@ func (s *apiKeyService) GetAPIKeyAPIs(ctx context.Context, apiKeyUUID uuid.UUID,
 }
 
 // Add APIs to API key
-func (s *apiKeyService) AddAPIKeyAPIs(ctx context.Context, apiKeyUUID uuid.UUID, apiUUIDs []uuid.UUID) error {
+func (s *apiKeyService) AddAPIKeyAPIs(ctx context.Context, apiKeyUUID uuid.UUID, tenantID int64, apiUUIDs []uuid.UUID) error {
 	_, span := otel.Tracer("service").Start(ctx, "api_key.addAPIs")
 	defer span.End()
-	span.SetAttributes(attribute.String("api_key.uuid", apiKeyUUID.String()))
+	span.SetAttributes(attribute.String("api_key.uuid", apiKeyUUID.String()), attribute.Int64("tenant.id", tenantID))
 
 	err := s.db.Transaction(func(tx *gorm.DB) error {
 		apiKeyRepo := s.apiKeyRepo.WithTx(tx)
@@ -614,18 +667,15 @@ func (s *apiKeyService) AddAPIKeyAPIs(ctx context.Context, apiKeyUUID uuid.UUID,
 		apiRepo := s.apiRepo.WithTx(tx)
 
 		// Get API key
-		apiKey, err := apiKeyRepo.FindByUUID(apiKeyUUID)
+		apiKey, err := findTenantAPIKey(apiKeyRepo, apiKeyUUID, tenantID)
 		if err != nil {
 			return err
 		}
-		if apiKey == nil {
-			return apperror.NewNotFoundWithReason("API key not found")
-		}
 
 		// Process each API UUID
 		for _, apiUUID := range apiUUIDs {
 			// Get API
-			api, err := apiRepo.FindByUUID(apiUUID)
+			api, err := apiRepo.FindByUUIDAndTenantID(apiUUID, tenantID)
 			if err != nil {
 				return err
 			}
@@ -666,15 +716,20 @@ func (s *apiKeyService) AddAPIKeyAPIs(ctx context.Context, apiKeyUUID uuid.UUID,
 }
 
 // Remove API from API key
-func (s *apiKeyService) RemoveAPIKeyAPI(ctx context.Context, apiKeyUUID uuid.UUID, apiUUID uuid.UUID) error {
+func (s *apiKeyService) RemoveAPIKeyAPI(ctx context.Context, apiKeyUUID uuid.UUID, tenantID int64, apiUUID uuid.UUID) error {
 	_, span := otel.Tracer("service").Start(ctx, "api_key.removeAPI")
 	defer span.End()
 	span.SetAttributes(
 		attribute.String("api_key.uuid", apiKeyUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
 		attribute.String("api.uuid", apiUUID.String()),
 	)
 
 	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := findTenantAPIKey(s.apiKeyRepo.WithTx(tx), apiKeyUUID, tenantID); err != nil {
+			return err
+		}
+
 		apiKeyAPIRepo := s.apiKeyAPIRepo.WithTx(tx)
 
 		// First check if the relationship exists
@@ -699,14 +754,21 @@ func (s *apiKeyService) RemoveAPIKeyAPI(ctx context.Context, apiKeyUUID uuid.UUI
 }
 
 // Get permissions for a specific API assigned to API key
-func (s *apiKeyService) GetAPIKeyAPIPermissions(ctx context.Context, apiKeyUUID uuid.UUID, apiUUID uuid.UUID) ([]PermissionServiceDataResult, error) {
+func (s *apiKeyService) GetAPIKeyAPIPermissions(ctx context.Context, apiKeyUUID uuid.UUID, tenantID int64, apiUUID uuid.UUID) ([]PermissionServiceDataResult, error) {
 	_, span := otel.Tracer("service").Start(ctx, "api_key.getAPIPermissions")
 	defer span.End()
 	span.SetAttributes(
 		attribute.String("api_key.uuid", apiKeyUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
 		attribute.String("api.uuid", apiUUID.String()),
 	)
 
+	if _, err := findTenantAPIKey(s.apiKeyRepo, apiKeyUUID, tenantID); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "api key not found")
+		return nil, err
+	}
+
 	// Get API key API relationship
 	apiKeyAPI, err := s.apiKeyAPIRepo.FindByAPIKeyUUIDAndAPIUUID(apiKeyUUID, apiUUID)
 	if err != nil {
@@ -747,15 +809,20 @@ func (s *apiKeyService) GetAPIKeyAPIPermissions(ctx context.Context, apiKeyUUID
 }
 
 // Add permissions to a specific API for API key
-func (s *apiKeyService) AddAPIKeyAPIPermissions(ctx context.Context, apiKeyUUID uuid.UUID, apiUUID uuid.UUID, permissionUUIDs []uuid.UUID) error {
+func (s *apiKeyService) AddAPIKeyAPIPermissions(ctx context.Context, apiKeyUUID uuid.UUID, tenantID int64, apiUUID uuid.UUID, permissionUUIDs []uuid.UUID) error {
 	_, span := otel.Tracer("service").Start(ctx, "api_key.addAPIPermissions")
 	defer span.End()
 	span.SetAttributes(
 		attribute.String("api_key.uuid", apiKeyUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
 		attribute.String("api.uuid", apiUUID.String()),
 	)
 
 	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := findTenantAPIKey(s.apiKeyRepo.WithTx(tx), apiKeyUUID, tenantID); err != nil {
+			return err
+		}
+
 		apiKeyAPIRepo := s.apiKeyAPIRepo.WithTx(tx)
 		apiKeyPermissionRepo := s.apiKeyPermissionRepo.WithTx(tx)
 		permissionRepo := s.permissionRepo.WithTx(tx)
@@ -771,7 +838,7 @@ func (s *apiKeyService) AddAPIKeyAPIPermissions(ctx context.Context, apiKeyUUID
 		}
 
 		// Get the API to validate permissions belong to it
-		api, err := apiRepo.FindByUUID(apiUUID)
+		api, err := apiRepo.FindByUUIDAndTenantID(apiUUID, tenantID)
 		if err != nil {
 			return err
 		}
@@ -828,16 +895,21 @@ func (s *apiKeyService) AddAPIKeyAPIPermissions(ctx context.Context, apiKeyUUID
 }
 
 // Remove permission from a specific API for API key
-func (s *apiKeyService) RemoveAPIKeyAPIPermission(ctx context.Context, apiKeyUUID uuid.UUID, apiUUID uuid.UUID, permissionUUID uuid.UUID) error {
+func (s *apiKeyService) RemoveAPIKeyAPIPermission(ctx context.Context, apiKeyUUID uuid.UUID, tenantID int64, apiUUID uuid.UUID, permissionUUID uuid.UUID) error {
 	_, span := otel.Tracer("service").Start(ctx, "api_key.removeAPIPermission")
 	defer span.End()
 	span.SetAttributes(
 		attribute.String("api_key.uuid", apiKeyUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
 		attribute.String("api.uuid", apiUUID.String()),
 		attribute.String("permission.uuid", permissionUUID.String()),
 	)
 
 	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := findTenantAPIKey(s.apiKeyRepo.WithTx(tx), apiKeyUUID, tenantID); err != nil {
+			return err
+		}
+
 		apiKeyAPIRepo := s.apiKeyAPIRepo.WithTx(tx)
 		apiKeyPermissionRepo := s.apiKeyPermissionRepo.WithTx(tx)
 		permissionRepo := s.permissionRepo.WithTx(tx)
@@ -853,7 +925,7 @@ func (s *apiKeyService) RemoveAPIKeyAPIPermission(ctx context.Context, apiKeyUUI
 		}
 
 		// Get the API to validate permission belongs to it
-		api, err := apiRepo.FindByUUID(apiUUID)
+		api, err := apiRepo.FindByUUIDAndTenantID(apiUUID, tenantID)
 		if err != nil {
 			return err
 		}
@@ -887,9 +959,84 @@ func (s *apiKeyService) RemoveAPIKeyAPIPermission(ctx context.Context, apiKeyUUI
 	return nil
 }
 
-func (s *apiKeyService) ValidateAPIKey(ctx context.Context, keyHash string) (*APIKeyServiceDataResult, error) {
+// findTenantAPIKey loads the API key with apiKeyUUID, treating keys of other
+// tenants as missing.
+func findTenantAPIKey(repo repository.APIKeyRepository, apiKeyUUID uuid.UUID, tenantID int64) (*model.APIKey, error) {
+	apiKey, err := repo.FindByUUIDAndTenantID(apiKeyUUID.String(), tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == nil {
+		return nil, apperror.NewNotFoundWithReason("API key not found")
+	}
+	return apiKey, nil
+}
+
+func (s *apiKeyService) ValidateAPIKey(ctx context.Context, apiKey string) (*model.APIKey, error) {
 	_, span := otel.Tracer("service").Start(ctx, "api_key.validate")
 	defer span.End()
-	span.SetStatus(codes.Error, "not implemented")
-	return nil, apperror.NewValidation("not implemented")
+
+	if apiKey == "" {
+		span.SetStatus(codes.Error, "missing api key")
+		return nil, apperror.NewUnauthorized("invalid api key")
+	}
+
+	key, err := s.apiKeyRepo.FindByKeyHashWithPermissions(hashAPIKey(apiKey))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find api key failed")
+		return nil, apperror.NewInternal("failed to find api key", err)
+	}
+	if key == nil {
+		span.SetStatus(codes.Error, "api key not found")
+		return nil, apperror.NewUnauthorized("invalid api key")
+	}
+	span.SetAttributes(attribute.String("api_key.uuid", key.APIKeyUUID.String()))
+
+	if key.Status != model.StatusActive {
+		span.SetStatus(codes.Error, "api key not active")
+		return nil, apperror.NewUnauthorized("api key is not active")
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		span.SetStatus(codes.Error, "api key expired")
+		return nil, apperror.NewUnauthorized("api key has expired")
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return key, nil
+}
+
+func (s *apiKeyService) FlushUsage(ctx context.Context) (int64, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "api_key.flush_usage")
+	defer span.End()
+
+	if s.usageStore == nil {
+		span.SetStatus(codes.Ok, "")
+		return 0, nil
+	}
+
+	usage, err := s.usageStore.TakeAPIKeyUsage(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "take usage failed")
+		return 0, err
+	}
+
+	var flushed int64
+	for i, u := range usage {
+		if err := s.apiKeyRepo.AddUsage(u.APIKeyID, u.Count, u.LastUsedAt); err != nil {
+			// Put back what has not been written so the next flush retries it.
+			if restoreErr := s.usageStore.RestoreAPIKeyUsage(ctx, usage[i:]); restoreErr != nil {
+				span.RecordError(restoreErr)
+			}
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "add usage failed")
+			return flushed, err
+		}
+		flushed++
+	}
+
+	span.SetAttributes(attribute.Int64("api_key.flushed", flushed))
+	span.SetStatus(codes.Ok, "")
+	return flushed, nil
 }
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/repository"
 	"github.com/stretchr/testify/assert"
@@ -30,7 +32,7 @@ func buildAPIKey() *model.APIKey {
 func newAPIKeySvc(t *testing.T, akRepo *mockAPIKeyRepo, userRepo *mockUserRepo) APIKeyService {
 	t.Helper()
 	gormDB, _ := newMockGormDB(t)
-	return NewAPIKeyService(gormDB, akRepo, &mockAPIKeyAPIRepo{}, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, userRepo, &mockPermissionRepo{})
+	return NewAPIKeyService(gormDB, akRepo, &mockAPIKeyAPIRepo{}, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, userRepo, &mockPermissionRepo{}, &mockEventRepo{}, nil)
 }
 
 // ---------------------------------------------------------------------------
@@ -80,7 +82,7 @@ func TestAPIKeyService_GetByUUID(t *testing.T) {
 			} else {
 				mock.ExpectCommit()
 			}
-			svc := NewAPIKeyService(gormDB, akRepo, &mockAPIKeyAPIRepo{}, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{})
+			svc := NewAPIKeyService(gormDB, akRepo, &mockAPIKeyAPIRepo{}, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{}, &mockEventRepo{}, nil)
 			res, err := svc.GetByUUID(context.Background(), ak.APIKeyUUID, 1, requesterUUID)
 			if tc.wantErr != "" {
 				require.Error(t, err)
@@ -132,7 +134,7 @@ func TestAPIKeyService_Create(t *testing.T) {
 			} else {
 				mock.ExpectCommit()
 			}
-			svc := NewAPIKeyService(gormDB, akRepo, &mockAPIKeyAPIRepo{}, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{})
+			svc := NewAPIKeyService(gormDB, akRepo, &mockAPIKeyAPIRepo{}, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{}, &mockEventRepo{}, nil)
 			res, plainKey, err := svc.Create(context.Background(), 1, "test-key", "desc", nil, nil, nil, model.StatusActive)
 			if tc.wantErr != "" {
 				require.Error(t, err)
@@ -180,6 +182,15 @@ func TestAPIKeyService_SetStatusByUUID(t *testing.T) {
 			},
 			wantErr: "update err",
 		},
+		{
+			name: "revoked key",
+			setup: func(r *mockAPIKeyRepo) {
+				r.findByUUIDAndTenantIDFn = func(_ string, _ int64) (*model.APIKey, error) {
+					return &model.APIKey{APIKeyUUID: ak.APIKeyUUID, Status: model.StatusRevoked}, nil
+				}
+			},
+			wantErr: "revoked",
+		},
 		{
 			name: "success",
 			setup: func(r *mockAPIKeyRepo) {
@@ -201,7 +212,7 @@ func TestAPIKeyService_SetStatusByUUID(t *testing.T) {
 			} else {
 				mock.ExpectCommit()
 			}
-			svc := NewAPIKeyService(gormDB, akRepo, &mockAPIKeyAPIRepo{}, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{})
+			svc := NewAPIKeyService(gormDB, akRepo, &mockAPIKeyAPIRepo{}, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{}, &mockEventRepo{}, nil)
 			res, err := svc.SetStatusByUUID(context.Background(), ak.APIKeyUUID, 1, model.StatusActive)
 			if tc.wantErr != "" {
 				require.Error(t, err)
@@ -269,7 +280,7 @@ func TestAPIKeyService_Delete(t *testing.T) {
 			} else {
 				mock.ExpectCommit()
 			}
-			svc := NewAPIKeyService(gormDB, akRepo, &mockAPIKeyAPIRepo{}, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{})
+			svc := NewAPIKeyService(gormDB, akRepo, &mockAPIKeyAPIRepo{}, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{}, &mockEventRepo{}, nil)
 			res, err := svc.Delete(context.Background(), ak.APIKeyUUID, 1, deleterUUID)
 			if tc.wantErr != "" {
 				require.Error(t, err)
@@ -282,6 +293,84 @@ func TestAPIKeyService_Delete(t *testing.T) {
 	}
 }
 
+func TestAPIKeyService_Delete_CleansUpAndRecordsEvent(t *testing.T) {
+	ak := buildAPIKey()
+	ak.APIKeyID = 42
+	var calls []string
+	akRepo := &mockAPIKeyRepo{
+		findByUUIDAndTenantIDFn: func(_ string, _ int64) (*model.APIKey, error) { return ak, nil },
+		deleteByUUIDFn: func(_ any) error {
+			calls = append(calls, "api_key")
+			return nil
+		},
+	}
+	akaRepo := &mockAPIKeyAPIRepo{deleteByAPIKeyIDFn: func(id int64) error {
+		assert.Equal(t, int64(42), id)
+		calls = append(calls, "api_key_apis")
+		return nil
+	}}
+	akpRepo := &mockAPIKeyPermissionRepo{deleteByAPIKeyIDFn: func(id int64) error {
+		assert.Equal(t, int64(42), id)
+		calls = append(calls, "api_key_permissions")
+		return nil
+	}}
+	eventRepo := &mockEventRepo{}
+
+	gormDB, mock := newMockGormDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	svc := NewAPIKeyService(gormDB, akRepo, akaRepo, akpRepo, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{}, eventRepo, nil)
+	_, err := svc.Delete(context.Background(), ak.APIKeyUUID, 1, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api_key_permissions", "api_key_apis", "api_key"}, calls)
+	require.Len(t, eventRepo.created, 1)
+	assert.Equal(t, model.EventTypeAPIKeyDeleted, eventRepo.created[0].EventType)
+
+	t.Run("cleanup error", func(t *testing.T) {
+		akpRepo := &mockAPIKeyPermissionRepo{deleteByAPIKeyIDFn: func(int64) error { return errors.New("cleanup err") }}
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := NewAPIKeyService(gormDB, akRepo, &mockAPIKeyAPIRepo{}, akpRepo, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{}, &mockEventRepo{}, nil)
+		_, err := svc.Delete(context.Background(), ak.APIKeyUUID, 1, uuid.New())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cleanup err")
+	})
+}
+
+func TestAPIKeyService_StatusChangeEvents(t *testing.T) {
+	cases := []struct {
+		status string
+		want   string
+	}{
+		{model.StatusInactive, model.EventTypeAPIKeyUpdated},
+		{model.StatusRevoked, model.EventTypeAPIKeyRevoked},
+	}
+	for _, tc := range cases {
+		t.Run(tc.status, func(t *testing.T) {
+			ak := buildAPIKey()
+			akRepo := &mockAPIKeyRepo{
+				findByUUIDAndTenantIDFn: func(_ string, _ int64) (*model.APIKey, error) { return ak, nil },
+				updateByUUIDFn: func(_, _ any) (*model.APIKey, error) {
+					updated := *ak
+					updated.Status = tc.status
+					return &updated, nil
+				},
+			}
+			eventRepo := &mockEventRepo{}
+			gormDB, mock := newMockGormDB(t)
+			mock.ExpectBegin()
+			mock.ExpectCommit()
+			svc := NewAPIKeyService(gormDB, akRepo, &mockAPIKeyAPIRepo{}, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{}, eventRepo, nil)
+			_, err := svc.SetStatusByUUID(context.Background(), ak.APIKeyUUID, 1, tc.status)
+			require.NoError(t, err)
+			require.Len(t, eventRepo.created, 1)
+			assert.Equal(t, tc.want, eventRepo.created[0].EventType)
+			assert.NotContains(t, string(eventRepo.created[0].Payload), ak.KeyHash)
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Get
 // ---------------------------------------------------------------------------
@@ -348,12 +437,172 @@ func TestAPIKeyService_Get(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestAPIKeyService_ValidateAPIKey(t *testing.T) {
-	t.Run("not implemented", func(t *testing.T) {
-		svc := newAPIKeySvc(t, &mockAPIKeyRepo{}, &mockUserRepo{})
-		res, err := svc.ValidateAPIKey(context.Background(), "somehash")
+	const plainKey = "ak_0123456789abcdef"
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	cases := []struct {
+		name       string
+		key        string
+		found      func() (*model.APIKey, error)
+		wantErr    any
+		wantReason string
+	}{
+		{name: "empty key", key: "", wantErr: &apperror.UnauthorizedError{}},
+		{
+			name:    "unknown key",
+			key:     plainKey,
+			found:   func() (*model.APIKey, error) { return nil, nil },
+			wantErr: &apperror.UnauthorizedError{},
+		},
+		{
+			name:    "repo error",
+			key:     plainKey,
+			found:   func() (*model.APIKey, error) { return nil, errors.New("db error") },
+			wantErr: &apperror.InternalError{},
+		},
+		{
+			name: "inactive key",
+			key:  plainKey,
+			found: func() (*model.APIKey, error) {
+				ak := buildAPIKey()
+				ak.Status = model.StatusInactive
+				return ak, nil
+			},
+			wantErr: &apperror.UnauthorizedError{},
+		},
+		{
+			name: "expired key",
+			key:  plainKey,
+			found: func() (*model.APIKey, error) {
+				ak := buildAPIKey()
+				ak.ExpiresAt = &past
+				return ak, nil
+			},
+			wantErr: &apperror.UnauthorizedError{},
+		},
+		{
+			name: "valid key",
+			key:  plainKey,
+			found: func() (*model.APIKey, error) {
+				ak := buildAPIKey()
+				ak.ExpiresAt = &future
+				return ak, nil
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			akRepo := &mockAPIKeyRepo{
+				findWithPermissionsFn: func(h string) (*model.APIKey, error) {
+					assert.Equal(t, hashAPIKey(plainKey), h)
+					return tc.found()
+				},
+			}
+			svc := newAPIKeySvc(t, akRepo, &mockUserRepo{})
+			res, err := svc.ValidateAPIKey(context.Background(), tc.key)
+			switch want := tc.wantErr.(type) {
+			case *apperror.UnauthorizedError:
+				require.ErrorAs(t, err, &want)
+				assert.Nil(t, res)
+			case *apperror.InternalError:
+				require.ErrorAs(t, err, &want)
+				assert.Nil(t, res)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, "test-key", res.Name)
+			}
+		})
+	}
+}
+
+func TestHashAPIKey(t *testing.T) {
+	plain, hash, prefix := (&apiKeyService{}).generateAPIKey()
+	assert.Equal(t, hash, hashAPIKey(plain))
+	assert.Len(t, hash, 64)
+	assert.Equal(t, plain[:12], prefix)
+}
+
+// ---------------------------------------------------------------------------
+// FlushUsage
+// ---------------------------------------------------------------------------
+
+// fakeUsageStore is an in-memory cache.APIKeyUsageStore.
+type fakeUsageStore struct {
+	usage    []cache.APIKeyUsage
+	takeErr  error
+	restored []cache.APIKeyUsage
+}
+
+func (f *fakeUsageStore) TakeAPIKeyUsage(context.Context) ([]cache.APIKeyUsage, error) {
+	usage := f.usage
+	f.usage = nil
+	return usage, f.takeErr
+}
+
+func (f *fakeUsageStore) RestoreAPIKeyUsage(_ context.Context, usage []cache.APIKeyUsage) error {
+	f.restored = append(f.restored, usage...)
+	return nil
+}
+
+func TestAPIKeyService_FlushUsage(t *testing.T) {
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	newSvc := func(t *testing.T, akRepo *mockAPIKeyRepo, store cache.APIKeyUsageStore) APIKeyService {
+		gormDB, _ := newMockGormDB(t)
+		return NewAPIKeyService(gormDB, akRepo, &mockAPIKeyAPIRepo{}, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{}, &mockEventRepo{}, store)
+	}
+
+	t.Run("no store", func(t *testing.T) {
+		n, err := newSvc(t, &mockAPIKeyRepo{}, nil).FlushUsage(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("writes usage", func(t *testing.T) {
+		store := &fakeUsageStore{usage: []cache.APIKeyUsage{
+			{APIKeyID: 1, Count: 3, LastUsedAt: at},
+			{APIKeyID: 2, Count: 1, LastUsedAt: at},
+		}}
+		written := map[int64]int64{}
+		akRepo := &mockAPIKeyRepo{
+			addUsageFn: func(id, count int64, last time.Time) error {
+				assert.Equal(t, at, last)
+				written[id] = count
+				return nil
+			},
+		}
+		n, err := newSvc(t, akRepo, store).FlushUsage(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+		assert.Equal(t, map[int64]int64{1: 3, 2: 1}, written)
+		assert.Empty(t, store.restored)
+	})
+
+	t.Run("take error", func(t *testing.T) {
+		store := &fakeUsageStore{takeErr: errors.New("redis down")}
+		_, err := newSvc(t, &mockAPIKeyRepo{}, store).FlushUsage(context.Background())
+		assert.EqualError(t, err, "redis down")
+	})
+
+	t.Run("db error restores the rest", func(t *testing.T) {
+		usage := []cache.APIKeyUsage{
+			{APIKeyID: 1, Count: 3, LastUsedAt: at},
+			{APIKeyID: 2, Count: 1, LastUsedAt: at},
+		}
+		store := &fakeUsageStore{usage: usage}
+		akRepo := &mockAPIKeyRepo{
+			addUsageFn: func(id, _ int64, _ time.Time) error {
+				if id == 2 {
+					return errors.New("db error")
+				}
+				return nil
+			},
+		}
+		n, err := newSvc(t, akRepo, store).FlushUsage(context.Background())
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "not implemented")
-		assert.Nil(t, res)
+		assert.Equal(t, int64(1), n)
+		assert.Equal(t, usage[1:], store.restored)
 	})
 }
 
@@ -446,6 +695,16 @@ func TestAPIKeyService_Update(t *testing.T) {
 			nameArg: &nameStr,
 			wantErr: "update err",
 		},
+		{
+			name: "revoked key",
+			setup: func(r *mockAPIKeyRepo) {
+				r.findByUUIDAndTenantIDFn = func(_ string, _ int64) (*model.APIKey, error) {
+					return &model.APIKey{APIKeyUUID: ak.APIKeyUUID, Status: model.StatusRevoked}, nil
+				}
+			},
+			nameArg: &nameStr,
+			wantErr: "revoked",
+		},
 		{
 			name: "success with all fields",
 			setup: func(r *mockAPIKeyRepo) {
@@ -482,7 +741,7 @@ func TestAPIKeyService_Update(t *testing.T) {
 			} else {
 				mock.ExpectRollback()
 			}
-			svc := NewAPIKeyService(gormDB, akRepo, &mockAPIKeyAPIRepo{}, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{})
+			svc := NewAPIKeyService(gormDB, akRepo, &mockAPIKeyAPIRepo{}, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{}, &mockEventRepo{}, nil)
 			res, err := svc.Update(context.Background(), ak.APIKeyUUID, 1, tc.nameArg, tc.descArg, tc.configArg, tc.expiresArg, tc.rateLimArg, tc.statusArg, updaterUUID)
 			if tc.wantErr != "" {
 				require.Error(t, err)
@@ -495,6 +754,16 @@ func TestAPIKeyService_Update(t *testing.T) {
 	}
 }
 
+// tenantAPIKeyRepo returns an APIKeyRepository that finds every API key in
+// the tenant it is asked for.
+func tenantAPIKeyRepo() *mockAPIKeyRepo {
+	return &mockAPIKeyRepo{
+		findByUUIDAndTenantIDFn: func(id string, tenantID int64) (*model.APIKey, error) {
+			return &model.APIKey{APIKeyID: 1, APIKeyUUID: uuid.MustParse(id), TenantID: tenantID}, nil
+		},
+	}
+}
+
 // ---------------------------------------------------------------------------
 // GetAPIKeyAPIs
 // ---------------------------------------------------------------------------
@@ -504,6 +773,15 @@ func TestAPIKeyService_GetAPIKeyAPIs(t *testing.T) {
 	apiUUID := uuid.New()
 	permUUID := uuid.New()
 
+	t.Run("api key of another tenant", func(t *testing.T) {
+		gormDB, _ := newMockGormDB(t)
+		svc := NewAPIKeyService(gormDB, &mockAPIKeyRepo{}, &mockAPIKeyAPIRepo{}, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{}, &mockEventRepo{}, nil)
+		res, err := svc.GetAPIKeyAPIs(context.Background(), akUUID, 2, 1, 10, "", "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "API key not found")
+		assert.Nil(t, res)
+	})
+
 	t.Run("defaults and success with permissions", func(t *testing.T) {
 		akaRepo := &mockAPIKeyAPIRepo{
 			findByAPIKeyUUIDPaginatedFn: func(_ uuid.UUID, page, limit int, _, _ string) (*repository.PaginationResult[model.APIKeyAPI], error) {
@@ -542,9 +820,9 @@ func TestAPIKeyService_GetAPIKeyAPIs(t *testing.T) {
 			},
 		}
 		gormDB, _ := newMockGormDB(t)
-		svc := NewAPIKeyService(gormDB, &mockAPIKeyRepo{}, akaRepo, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{})
+		svc := NewAPIKeyService(gormDB, tenantAPIKeyRepo(), akaRepo, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{}, &mockEventRepo{}, nil)
 		// Pass 0 for page/limit to test defaults
-		res, err := svc.GetAPIKeyAPIs(context.Background(), akUUID, 0, 0, "", "")
+		res, err := svc.GetAPIKeyAPIs(context.Background(), akUUID, 1, 0, 0, "", "")
 		require.NoError(t, err)
 		assert.Equal(t, int64(1), res.Total)
 		assert.Len(t, res.Data, 1)
@@ -560,8 +838,8 @@ func TestAPIKeyService_GetAPIKeyAPIs(t *testing.T) {
 			},
 		}
 		gormDB, _ := newMockGormDB(t)
-		svc := NewAPIKeyService(gormDB, &mockAPIKeyRepo{}, akaRepo, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{})
-		res, err := svc.GetAPIKeyAPIs(context.Background(), akUUID, 1, 10, "", "")
+		svc := NewAPIKeyService(gormDB, tenantAPIKeyRepo(), akaRepo, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{}, &mockEventRepo{}, nil)
+		res, err := svc.GetAPIKeyAPIs(context.Background(), akUUID, 1, 1, 10, "", "")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "paginate err")
 		assert.Nil(t, res)
@@ -597,7 +875,7 @@ func TestAPIKeyService_AddAPIKeyAPIs(t *testing.T) {
 			name:     "api key find error",
 			apiUUIDs: []uuid.UUID{apiUUID1},
 			setupAK: func(r *mockAPIKeyRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.APIKey, error) { return nil, errors.New("ak find err") }
+				r.findByUUIDAndTenantIDFn = func(_ string, _ int64) (*model.APIKey, error) { return nil, errors.New("ak find err") }
 			},
 			setupAKA: func(_ *mockAPIKeyAPIRepo) {},
 			setupAPI: func(_ *mockAPIRepo) {},
@@ -607,7 +885,7 @@ func TestAPIKeyService_AddAPIKeyAPIs(t *testing.T) {
 			name:     "api key not found",
 			apiUUIDs: []uuid.UUID{apiUUID1},
 			setupAK: func(r *mockAPIKeyRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.APIKey, error) { return nil, nil }
+				r.findByUUIDAndTenantIDFn = func(_ string, _ int64) (*model.APIKey, error) { return nil, nil }
 			},
 			setupAKA: func(_ *mockAPIKeyAPIRepo) {},
 			setupAPI: func(_ *mockAPIRepo) {},
@@ -617,11 +895,11 @@ func TestAPIKeyService_AddAPIKeyAPIs(t *testing.T) {
 			name:     "api find error",
 			apiUUIDs: []uuid.UUID{apiUUID1},
 			setupAK: func(r *mockAPIKeyRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.APIKey, error) { return buildAK(), nil }
+				r.findByUUIDAndTenantIDFn = func(_ string, _ int64) (*model.APIKey, error) { return buildAK(), nil }
 			},
 			setupAKA: func(_ *mockAPIKeyAPIRepo) {},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return nil, errors.New("api find err") }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return nil, errors.New("api find err") }
 			},
 			wantErr: "api find err",
 		},
@@ -629,11 +907,11 @@ func TestAPIKeyService_AddAPIKeyAPIs(t *testing.T) {
 			name:     "api not found",
 			apiUUIDs: []uuid.UUID{apiUUID1},
 			setupAK: func(r *mockAPIKeyRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.APIKey, error) { return buildAK(), nil }
+				r.findByUUIDAndTenantIDFn = func(_ string, _ int64) (*model.APIKey, error) { return buildAK(), nil }
 			},
 			setupAKA: func(_ *mockAPIKeyAPIRepo) {},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return nil, nil }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return nil, nil }
 			},
 			wantErr: "API not found",
 		},
@@ -641,13 +919,13 @@ func TestAPIKeyService_AddAPIKeyAPIs(t *testing.T) {
 			name:     "find existing relationship error",
 			apiUUIDs: []uuid.UUID{apiUUID1},
 			setupAK: func(r *mockAPIKeyRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.APIKey, error) { return buildAK(), nil }
+				r.findByUUIDAndTenantIDFn = func(_ string, _ int64) (*model.APIKey, error) { return buildAK(), nil }
 			},
 			setupAKA: func(r *mockAPIKeyAPIRepo) {
 				r.findByAPIKeyAndAPIFn = func(_, _ int64) (*model.APIKeyAPI, error) { return nil, errors.New("find rel err") }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return buildAPI(10, apiUUID1), nil }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return buildAPI(10, apiUUID1), nil }
 			},
 			wantErr: "find rel err",
 		},
@@ -655,13 +933,13 @@ func TestAPIKeyService_AddAPIKeyAPIs(t *testing.T) {
 			name:     "skip existing relationship",
 			apiUUIDs: []uuid.UUID{apiUUID1},
 			setupAK: func(r *mockAPIKeyRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.APIKey, error) { return buildAK(), nil }
+				r.findByUUIDAndTenantIDFn = func(_ string, _ int64) (*model.APIKey, error) { return buildAK(), nil }
 			},
 			setupAKA: func(r *mockAPIKeyAPIRepo) {
 				r.findByAPIKeyAndAPIFn = func(_, _ int64) (*model.APIKeyAPI, error) { return &model.APIKeyAPI{}, nil }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return buildAPI(10, apiUUID1), nil }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return buildAPI(10, apiUUID1), nil }
 			},
 			expectCommit: true,
 		},
@@ -669,13 +947,13 @@ func TestAPIKeyService_AddAPIKeyAPIs(t *testing.T) {
 			name:     "create error",
 			apiUUIDs: []uuid.UUID{apiUUID1},
 			setupAK: func(r *mockAPIKeyRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.APIKey, error) { return buildAK(), nil }
+				r.findByUUIDAndTenantIDFn = func(_ string, _ int64) (*model.APIKey, error) { return buildAK(), nil }
 			},
 			setupAKA: func(r *mockAPIKeyAPIRepo) {
 				r.createFn = func(_ *model.APIKeyAPI) (*model.APIKeyAPI, error) { return nil, errors.New("create err") }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return buildAPI(10, apiUUID1), nil }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return buildAPI(10, apiUUID1), nil }
 			},
 			wantErr: "create err",
 		},
@@ -683,12 +961,11 @@ func TestAPIKeyService_AddAPIKeyAPIs(t *testing.T) {
 			name:     "success with multiple apis",
 			apiUUIDs: []uuid.UUID{apiUUID1, apiUUID2},
 			setupAK: func(r *mockAPIKeyRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.APIKey, error) { return buildAK(), nil }
+				r.findByUUIDAndTenantIDFn = func(_ string, _ int64) (*model.APIKey, error) { return buildAK(), nil }
 			},
 			setupAKA: func(_ *mockAPIKeyAPIRepo) {},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(id any, _ ...string) (*model.API, error) {
-					u := id.(uuid.UUID)
+				r.findByUUIDAndTenantIDFn = func(u uuid.UUID, _ int64) (*model.API, error) {
 					if u == apiUUID1 {
 						return buildAPI(10, apiUUID1), nil
 					}
@@ -715,8 +992,8 @@ func TestAPIKeyService_AddAPIKeyAPIs(t *testing.T) {
 			} else {
 				mock.ExpectRollback()
 			}
-			svc := NewAPIKeyService(gormDB, akRepo, akaRepo, &mockAPIKeyPermissionRepo{}, apiRepo, &mockUserRepo{}, &mockPermissionRepo{})
-			err := svc.AddAPIKeyAPIs(context.Background(), akUUID, tc.apiUUIDs)
+			svc := NewAPIKeyService(gormDB, akRepo, akaRepo, &mockAPIKeyPermissionRepo{}, apiRepo, &mockUserRepo{}, &mockPermissionRepo{}, &mockEventRepo{}, nil)
+			err := svc.AddAPIKeyAPIs(context.Background(), akUUID, 1, tc.apiUUIDs)
 			if tc.wantErr != "" {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tc.wantErr)
@@ -784,8 +1061,8 @@ func TestAPIKeyService_RemoveAPIKeyAPI(t *testing.T) {
 			} else {
 				mock.ExpectRollback()
 			}
-			svc := NewAPIKeyService(gormDB, &mockAPIKeyRepo{}, akaRepo, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{})
-			err := svc.RemoveAPIKeyAPI(context.Background(), akUUID, apiUUID)
+			svc := NewAPIKeyService(gormDB, tenantAPIKeyRepo(), akaRepo, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{}, &mockEventRepo{}, nil)
+			err := svc.RemoveAPIKeyAPI(context.Background(), akUUID, 1, apiUUID)
 			if tc.wantErr != "" {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tc.wantErr)
@@ -805,13 +1082,22 @@ func TestAPIKeyService_GetAPIKeyAPIPermissions(t *testing.T) {
 	apiUUID := uuid.New()
 	permUUID := uuid.New()
 
+	t.Run("api key of another tenant", func(t *testing.T) {
+		gormDB, _ := newMockGormDB(t)
+		svc := NewAPIKeyService(gormDB, &mockAPIKeyRepo{}, &mockAPIKeyAPIRepo{}, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{}, &mockEventRepo{}, nil)
+		res, err := svc.GetAPIKeyAPIPermissions(context.Background(), akUUID, 2, apiUUID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "API key not found")
+		assert.Nil(t, res)
+	})
+
 	t.Run("find relationship error", func(t *testing.T) {
 		akaRepo := &mockAPIKeyAPIRepo{
 			findByAPIKeyUUIDAndAPIUUIDFn: func(_, _ uuid.UUID) (*model.APIKeyAPI, error) { return nil, errors.New("find err") },
 		}
 		gormDB, _ := newMockGormDB(t)
-		svc := NewAPIKeyService(gormDB, &mockAPIKeyRepo{}, akaRepo, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{})
-		res, err := svc.GetAPIKeyAPIPermissions(context.Background(), akUUID, apiUUID)
+		svc := NewAPIKeyService(gormDB, tenantAPIKeyRepo(), akaRepo, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{}, &mockEventRepo{}, nil)
+		res, err := svc.GetAPIKeyAPIPermissions(context.Background(), akUUID, 1, apiUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "find err")
 		assert.Nil(t, res)
@@ -822,8 +1108,8 @@ func TestAPIKeyService_GetAPIKeyAPIPermissions(t *testing.T) {
 			findByAPIKeyUUIDAndAPIUUIDFn: func(_, _ uuid.UUID) (*model.APIKeyAPI, error) { return nil, nil },
 		}
 		gormDB, _ := newMockGormDB(t)
-		svc := NewAPIKeyService(gormDB, &mockAPIKeyRepo{}, akaRepo, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{})
-		res, err := svc.GetAPIKeyAPIPermissions(context.Background(), akUUID, apiUUID)
+		svc := NewAPIKeyService(gormDB, tenantAPIKeyRepo(), akaRepo, &mockAPIKeyPermissionRepo{}, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{}, &mockEventRepo{}, nil)
+		res, err := svc.GetAPIKeyAPIPermissions(context.Background(), akUUID, 1, apiUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "API key API relationship not found")
 		assert.Nil(t, res)
@@ -839,8 +1125,8 @@ func TestAPIKeyService_GetAPIKeyAPIPermissions(t *testing.T) {
 			findByAPIKeyAPIIDFn: func(_ int64) ([]model.APIKeyPermission, error) { return nil, errors.New("perm err") },
 		}
 		gormDB, _ := newMockGormDB(t)
-		svc := NewAPIKeyService(gormDB, &mockAPIKeyRepo{}, akaRepo, akpRepo, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{})
-		res, err := svc.GetAPIKeyAPIPermissions(context.Background(), akUUID, apiUUID)
+		svc := NewAPIKeyService(gormDB, tenantAPIKeyRepo(), akaRepo, akpRepo, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{}, &mockEventRepo{}, nil)
+		res, err := svc.GetAPIKeyAPIPermissions(context.Background(), akUUID, 1, apiUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "perm err")
 		assert.Nil(t, res)
@@ -860,8 +1146,8 @@ func TestAPIKeyService_GetAPIKeyAPIPermissions(t *testing.T) {
 			},
 		}
 		gormDB, _ := newMockGormDB(t)
-		svc := NewAPIKeyService(gormDB, &mockAPIKeyRepo{}, akaRepo, akpRepo, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{})
-		res, err := svc.GetAPIKeyAPIPermissions(context.Background(), akUUID, apiUUID)
+		svc := NewAPIKeyService(gormDB, tenantAPIKeyRepo(), akaRepo, akpRepo, &mockAPIRepo{}, &mockUserRepo{}, &mockPermissionRepo{}, &mockEventRepo{}, nil)
+		res, err := svc.GetAPIKeyAPIPermissions(context.Background(), akUUID, 1, apiUUID)
 		require.NoError(t, err)
 		require.Len(t, res, 1)
 		assert.Equal(t, "read", res[0].Name)
@@ -916,7 +1202,7 @@ func TestAPIKeyService_AddAPIKeyAPIPermissions(t *testing.T) {
 				r.findByAPIKeyUUIDAndAPIUUIDFn = func(_, _ uuid.UUID) (*model.APIKeyAPI, error) { return &model.APIKeyAPI{APIKeyAPIID: 1}, nil }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return nil, errors.New("api err") }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return nil, errors.New("api err") }
 			},
 			setupPerm: func(_ *mockPermissionRepo) {},
 			setupAKP:  func(_ *mockAPIKeyPermissionRepo) {},
@@ -929,7 +1215,7 @@ func TestAPIKeyService_AddAPIKeyAPIPermissions(t *testing.T) {
 				r.findByAPIKeyUUIDAndAPIUUIDFn = func(_, _ uuid.UUID) (*model.APIKeyAPI, error) { return &model.APIKeyAPI{APIKeyAPIID: 1}, nil }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return nil, nil }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return nil, nil }
 			},
 			setupPerm: func(_ *mockPermissionRepo) {},
 			setupAKP:  func(_ *mockAPIKeyPermissionRepo) {},
@@ -942,7 +1228,7 @@ func TestAPIKeyService_AddAPIKeyAPIPermissions(t *testing.T) {
 				r.findByAPIKeyUUIDAndAPIUUIDFn = func(_, _ uuid.UUID) (*model.APIKeyAPI, error) { return &model.APIKeyAPI{APIKeyAPIID: 1}, nil }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return &model.API{APIID: 10}, nil }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return &model.API{APIID: 10}, nil }
 			},
 			setupPerm: func(r *mockPermissionRepo) {
 				r.findByUUIDFn = func(_ any, _ ...string) (*model.Permission, error) { return nil, errors.New("perm err") }
@@ -957,7 +1243,7 @@ func TestAPIKeyService_AddAPIKeyAPIPermissions(t *testing.T) {
 				r.findByAPIKeyUUIDAndAPIUUIDFn = func(_, _ uuid.UUID) (*model.APIKeyAPI, error) { return &model.APIKeyAPI{APIKeyAPIID: 1}, nil }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return &model.API{APIID: 10}, nil }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return &model.API{APIID: 10}, nil }
 			},
 			setupPerm: func(r *mockPermissionRepo) {
 				r.findByUUIDFn = func(_ any, _ ...string) (*model.Permission, error) { return nil, nil }
@@ -972,7 +1258,7 @@ func TestAPIKeyService_AddAPIKeyAPIPermissions(t *testing.T) {
 				r.findByAPIKeyUUIDAndAPIUUIDFn = func(_, _ uuid.UUID) (*model.APIKeyAPI, error) { return &model.APIKeyAPI{APIKeyAPIID: 1}, nil }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return &model.API{APIID: 10}, nil }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return &model.API{APIID: 10}, nil }
 			},
 			setupPerm: func(r *mockPermissionRepo) {
 				r.findByUUIDFn = func(_ any, _ ...string) (*model.Permission, error) {
@@ -989,7 +1275,7 @@ func TestAPIKeyService_AddAPIKeyAPIPermissions(t *testing.T) {
 				r.findByAPIKeyUUIDAndAPIUUIDFn = func(_, _ uuid.UUID) (*model.APIKeyAPI, error) { return &model.APIKeyAPI{APIKeyAPIID: 1}, nil }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return &model.API{APIID: 10}, nil }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return &model.API{APIID: 10}, nil }
 			},
 			setupPerm: func(r *mockPermissionRepo) {
 				r.findByUUIDFn = func(_ any, _ ...string) (*model.Permission, error) {
@@ -1008,7 +1294,7 @@ func TestAPIKeyService_AddAPIKeyAPIPermissions(t *testing.T) {
 				r.findByAPIKeyUUIDAndAPIUUIDFn = func(_, _ uuid.UUID) (*model.APIKeyAPI, error) { return &model.APIKeyAPI{APIKeyAPIID: 1}, nil }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return &model.API{APIID: 10}, nil }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return &model.API{APIID: 10}, nil }
 			},
 			setupPerm: func(r *mockPermissionRepo) {
 				r.findByUUIDFn = func(_ any, _ ...string) (*model.Permission, error) {
@@ -1027,7 +1313,7 @@ func TestAPIKeyService_AddAPIKeyAPIPermissions(t *testing.T) {
 				r.findByAPIKeyUUIDAndAPIUUIDFn = func(_, _ uuid.UUID) (*model.APIKeyAPI, error) { return &model.APIKeyAPI{APIKeyAPIID: 1}, nil }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return &model.API{APIID: 10}, nil }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return &model.API{APIID: 10}, nil }
 			},
 			setupPerm: func(r *mockPermissionRepo) {
 				r.findByUUIDFn = func(_ any, _ ...string) (*model.Permission, error) {
@@ -1048,7 +1334,7 @@ func TestAPIKeyService_AddAPIKeyAPIPermissions(t *testing.T) {
 				r.findByAPIKeyUUIDAndAPIUUIDFn = func(_, _ uuid.UUID) (*model.APIKeyAPI, error) { return &model.APIKeyAPI{APIKeyAPIID: 1}, nil }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return &model.API{APIID: 10}, nil }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return &model.API{APIID: 10}, nil }
 			},
 			setupPerm: func(r *mockPermissionRepo) {
 				r.findByUUIDFn = func(_ any, _ ...string) (*model.Permission, error) {
@@ -1078,8 +1364,8 @@ func TestAPIKeyService_AddAPIKeyAPIPermissions(t *testing.T) {
 			} else {
 				mock.ExpectRollback()
 			}
-			svc := NewAPIKeyService(gormDB, &mockAPIKeyRepo{}, akaRepo, akpRepo, apiRepo, &mockUserRepo{}, permRepo)
-			err := svc.AddAPIKeyAPIPermissions(context.Background(), akUUID, apiUUID, tc.permUUIDs)
+			svc := NewAPIKeyService(gormDB, tenantAPIKeyRepo(), akaRepo, akpRepo, apiRepo, &mockUserRepo{}, permRepo, &mockEventRepo{}, nil)
+			err := svc.AddAPIKeyAPIPermissions(context.Background(), akUUID, 1, apiUUID, tc.permUUIDs)
 			if tc.wantErr != "" {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tc.wantErr)
@@ -1134,7 +1420,7 @@ func TestAPIKeyService_RemoveAPIKeyAPIPermission(t *testing.T) {
 				r.findByAPIKeyUUIDAndAPIUUIDFn = func(_, _ uuid.UUID) (*model.APIKeyAPI, error) { return &model.APIKeyAPI{APIKeyAPIID: 1}, nil }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return nil, errors.New("api err") }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return nil, errors.New("api err") }
 			},
 			setupPerm: func(_ *mockPermissionRepo) {},
 			setupAKP:  func(_ *mockAPIKeyPermissionRepo) {},
@@ -1146,7 +1432,7 @@ func TestAPIKeyService_RemoveAPIKeyAPIPermission(t *testing.T) {
 				r.findByAPIKeyUUIDAndAPIUUIDFn = func(_, _ uuid.UUID) (*model.APIKeyAPI, error) { return &model.APIKeyAPI{APIKeyAPIID: 1}, nil }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return nil, nil }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return nil, nil }
 			},
 			setupPerm: func(_ *mockPermissionRepo) {},
 			setupAKP:  func(_ *mockAPIKeyPermissionRepo) {},
@@ -1158,7 +1444,7 @@ func TestAPIKeyService_RemoveAPIKeyAPIPermission(t *testing.T) {
 				r.findByAPIKeyUUIDAndAPIUUIDFn = func(_, _ uuid.UUID) (*model.APIKeyAPI, error) { return &model.APIKeyAPI{APIKeyAPIID: 1}, nil }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return &model.API{APIID: 10}, nil }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return &model.API{APIID: 10}, nil }
 			},
 			setupPerm: func(r *mockPermissionRepo) {
 				r.findByUUIDFn = func(_ any, _ ...string) (*model.Permission, error) { return nil, errors.New("perm err") }
@@ -1172,7 +1458,7 @@ func TestAPIKeyService_RemoveAPIKeyAPIPermission(t *testing.T) {
 				r.findByAPIKeyUUIDAndAPIUUIDFn = func(_, _ uuid.UUID) (*model.APIKeyAPI, error) { return &model.APIKeyAPI{APIKeyAPIID: 1}, nil }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return &model.API{APIID: 10}, nil }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return &model.API{APIID: 10}, nil }
 			},
 			setupPerm: func(r *mockPermissionRepo) {
 				r.findByUUIDFn = func(_ any, _ ...string) (*model.Permission, error) { return nil, nil }
@@ -1186,7 +1472,7 @@ func TestAPIKeyService_RemoveAPIKeyAPIPermission(t *testing.T) {
 				r.findByAPIKeyUUIDAndAPIUUIDFn = func(_, _ uuid.UUID) (*model.APIKeyAPI, error) { return &model.APIKeyAPI{APIKeyAPIID: 1}, nil }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return &model.API{APIID: 10}, nil }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return &model.API{APIID: 10}, nil }
 			},
 			setupPerm: func(r *mockPermissionRepo) {
 				r.findByUUIDFn = func(_ any, _ ...string) (*model.Permission, error) {
@@ -1202,7 +1488,7 @@ func TestAPIKeyService_RemoveAPIKeyAPIPermission(t *testing.T) {
 				r.findByAPIKeyUUIDAndAPIUUIDFn = func(_, _ uuid.UUID) (*model.APIKeyAPI, error) { return &model.APIKeyAPI{APIKeyAPIID: 1}, nil }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return &model.API{APIID: 10}, nil }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return &model.API{APIID: 10}, nil }
 			},
 			setupPerm: func(r *mockPermissionRepo) {
 				r.findByUUIDFn = func(_ any, _ ...string) (*model.Permission, error) {
@@ -1220,7 +1506,7 @@ func TestAPIKeyService_RemoveAPIKeyAPIPermission(t *testing.T) {
 				r.findByAPIKeyUUIDAndAPIUUIDFn = func(_, _ uuid.UUID) (*model.APIKeyAPI, error) { return &model.APIKeyAPI{APIKeyAPIID: 1}, nil }
 			},
 			setupAPI: func(r *mockAPIRepo) {
-				r.findByUUIDFn = func(_ any, _ ...string) (*model.API, error) { return &model.API{APIID: 10}, nil }
+				r.findByUUIDAndTenantIDFn = func(_ uuid.UUID, _ int64) (*model.API, error) { return &model.API{APIID: 10}, nil }
 			},
 			setupPerm: func(r *mockPermissionRepo) {
 				r.findByUUIDFn = func(_ any, _ ...string) (*model.Permission, error) {
@@ -1250,8 +1536,8 @@ func TestAPIKeyService_RemoveAPIKeyAPIPermission(t *testing.T) {
 			} else {
 				mock.ExpectRollback()
 			}
-			svc := NewAPIKeyService(gormDB, &mockAPIKeyRepo{}, akaRepo, akpRepo, apiRepo, &mockUserRepo{}, permRepo)
-			err := svc.RemoveAPIKeyAPIPermission(context.Background(), akUUID, apiUUID, permUUID)
+			svc := NewAPIKeyService(gormDB, tenantAPIKeyRepo(), akaRepo, akpRepo, apiRepo, &mockUserRepo{}, permRepo, &mockEventRepo{}, nil)
+			err := svc.RemoveAPIKeyAPIPermission(context.Background(), akUUID, 1, apiUUID, permUUID)
 			if tc.wantErr != "" {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tc.wantErr)
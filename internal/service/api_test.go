@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/crypto"
@@ -181,7 +182,7 @@ func TestAPIService_Create(t *testing.T) {
 			},
 		}
 		svc := NewAPIService(db, apiRepo, &mockServiceRepo{}, &mockTenantServiceRepo{})
-		_, err := svc.Create(context.Background(), tenantID, "users-api", "", "", "rest", model.StatusActive, false, serviceUUID)
+		_, err := svc.Create(context.Background(), tenantID, "users-api", "", "", "rest", model.StatusActive, false, serviceUUID, "")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "already exists")
 	})
@@ -194,7 +195,7 @@ func TestAPIService_Create(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.Service, error) { return nil, nil },
 		}
 		svc := NewAPIService(db, &mockAPIRepo{}, svcRepo, &mockTenantServiceRepo{})
-		_, err := svc.Create(context.Background(), tenantID, "users-api", "", "", "rest", model.StatusActive, false, serviceUUID)
+		_, err := svc.Create(context.Background(), tenantID, "users-api", "", "", "rest", model.StatusActive, false, serviceUUID, "")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "service not found")
 	})
@@ -213,7 +214,7 @@ func TestAPIService_Create(t *testing.T) {
 			},
 		}
 		svc := NewAPIService(db, &mockAPIRepo{}, svcRepo, &mockTenantServiceRepo{})
-		_, err := svc.Create(context.Background(), tenantID, "users-api", "", "", "rest", model.StatusActive, false, serviceUUID)
+		_, err := svc.Create(context.Background(), tenantID, "users-api", "", "", "rest", model.StatusActive, false, serviceUUID, "")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "rand failure")
 	})
@@ -232,10 +233,55 @@ func TestAPIService_Create(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.API, error) { return createdAPI, nil },
 		}
 		svc := NewAPIService(db, apiRepo, svcRepo, &mockTenantServiceRepo{})
-		result, err := svc.Create(context.Background(), tenantID, "users-api", "", "", "rest", model.StatusActive, false, serviceUUID)
+		result, err := svc.Create(context.Background(), tenantID, "users-api", "", "", "rest", model.StatusActive, false, serviceUUID, "")
 		require.NoError(t, err)
 		assert.NotNil(t, result)
 	})
+
+	t.Run("identifier in use → conflict", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svcRepo := &mockServiceRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Service, error) {
+				return &model.Service{ServiceID: 1}, nil
+			},
+		}
+		apiRepo := &mockAPIRepo{
+			findByIdentifierFn: func(identifier string, tID int64) (*model.API, error) {
+				assert.Equal(t, "https://users.example.com", identifier)
+				assert.Equal(t, tenantID, tID)
+				return newAPI(2, "other-api", tenantID), nil
+			},
+		}
+		svc := NewAPIService(db, apiRepo, svcRepo, &mockTenantServiceRepo{})
+		_, err := svc.Create(context.Background(), tenantID, "users-api", "", "", "rest", model.StatusActive, false, serviceUUID, "https://users.example.com")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already in use")
+	})
+
+	t.Run("given identifier is kept", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		svcRepo := &mockServiceRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Service, error) {
+				return &model.Service{ServiceID: 1}, nil
+			},
+		}
+		var saved *model.API
+		apiRepo := &mockAPIRepo{
+			createOrUpdateFn: func(e *model.API) (*model.API, error) {
+				saved = e
+				return e, nil
+			},
+			findByUUIDFn: func(_ any, _ ...string) (*model.API, error) { return saved, nil },
+		}
+		svc := NewAPIService(db, apiRepo, svcRepo, &mockTenantServiceRepo{})
+		result, err := svc.Create(context.Background(), tenantID, "users-api", "", "", "rest", model.StatusActive, false, serviceUUID, "https://users.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "https://users.example.com", result.Identifier)
+	})
 }
 
 // ---------------------------------------------------------------------------
@@ -809,7 +855,7 @@ func TestAPIService_Create_FindByNameError(t *testing.T) {
 		findByNameFn: func(_ string, _ int64) (*model.API, error) { return nil, errors.New("name lookup err") },
 	}
 	svc := NewAPIService(db, apiRepo, &mockServiceRepo{}, &mockTenantServiceRepo{})
-	_, err := svc.Create(context.Background(), 1, "api", "", "", "rest", "active", false, uuid.New().String())
+	_, err := svc.Create(context.Background(), 1, "api", "", "", "rest", "active", false, uuid.New().String(), "")
 	require.Error(t, err)
 }
 
@@ -824,7 +870,7 @@ func TestAPIService_Create_SaveError(t *testing.T) {
 		findByUUIDFn: func(_ any, _ ...string) (*model.Service, error) { return &model.Service{ServiceID: 1}, nil },
 	}
 	svc := NewAPIService(db, apiRepo, svcRepo, &mockTenantServiceRepo{})
-	_, err := svc.Create(context.Background(), 1, "api", "", "", "rest", "active", false, uuid.New().String())
+	_, err := svc.Create(context.Background(), 1, "api", "", "", "rest", "active", false, uuid.New().String(), "")
 	require.Error(t, err)
 }
 
@@ -839,7 +885,7 @@ func TestAPIService_Create_FetchAfterSaveError(t *testing.T) {
 		findByUUIDFn: func(_ any, _ ...string) (*model.Service, error) { return &model.Service{ServiceID: 1}, nil },
 	}
 	svc := NewAPIService(db, apiRepo, svcRepo, &mockTenantServiceRepo{})
-	_, err := svc.Create(context.Background(), 1, "api", "", "", "rest", "active", false, uuid.New().String())
+	_, err := svc.Create(context.Background(), 1, "api", "", "", "rest", "active", false, uuid.New().String(), "")
 	require.Error(t, err)
 }
 
@@ -851,3 +897,89 @@ func TestToAPIServiceDataResult_Nil(t *testing.T) {
 	result := toAPIServiceDataResult(nil)
 	assert.Nil(t, result)
 }
+
+func TestAPIService_SetDeprecationByUUID(t *testing.T) {
+	tenantID := int64(1)
+	apiUUID := uuid.New()
+	future := time.Now().Add(24 * time.Hour)
+
+	t.Run("system api cannot be deprecated", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		api := newAPI(1, "sys", tenantID)
+		api.IsSystem = true
+		apiRepo := &mockAPIRepo{
+			findByUUIDAndTenantIDFn: func(_ uuid.UUID, _ int64) (*model.API, error) { return api, nil },
+		}
+		svc := NewAPIService(db, apiRepo, &mockServiceRepo{}, &mockTenantServiceRepo{})
+		_, err := svc.SetDeprecationByUUID(context.Background(), apiUUID, tenantID, &future, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "system API")
+	})
+
+	t.Run("api not found", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		apiRepo := &mockAPIRepo{
+			findByUUIDAndTenantIDFn: func(_ uuid.UUID, _ int64) (*model.API, error) { return nil, nil },
+		}
+		svc := NewAPIService(db, apiRepo, &mockServiceRepo{}, &mockTenantServiceRepo{})
+		_, err := svc.SetDeprecationByUUID(context.Background(), apiUUID, tenantID, &future, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("save error → propagated", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		apiRepo := &mockAPIRepo{
+			findByUUIDAndTenantIDFn: func(_ uuid.UUID, _ int64) (*model.API, error) {
+				return newAPI(1, "a", tenantID), nil
+			},
+			createOrUpdateFn: func(_ *model.API) (*model.API, error) { return nil, errors.New("save err") },
+		}
+		svc := NewAPIService(db, apiRepo, &mockServiceRepo{}, &mockTenantServiceRepo{})
+		_, err := svc.SetDeprecationByUUID(context.Background(), apiUUID, tenantID, &future, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		apiRepo := &mockAPIRepo{
+			findByUUIDAndTenantIDFn: func(_ uuid.UUID, _ int64) (*model.API, error) {
+				return newAPI(1, "a", tenantID), nil
+			},
+		}
+		svc := NewAPIService(db, apiRepo, &mockServiceRepo{}, &mockTenantServiceRepo{})
+		result, err := svc.SetDeprecationByUUID(context.Background(), apiUUID, tenantID, &future, nil)
+		require.NoError(t, err)
+		require.NotNil(t, result.Deprecation)
+		assert.Equal(t, &future, result.Deprecation.SunsetAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAPIService_ClearDeprecationByUUID(t *testing.T) {
+	tenantID := int64(1)
+	apiUUID := uuid.New()
+
+	db, mock := newMockGormDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	now := time.Now()
+	api := newAPI(1, "a", tenantID)
+	api.DeprecatedAt = &now
+	apiRepo := &mockAPIRepo{
+		findByUUIDAndTenantIDFn: func(_ uuid.UUID, _ int64) (*model.API, error) { return api, nil },
+	}
+	svc := NewAPIService(db, apiRepo, &mockServiceRepo{}, &mockTenantServiceRepo{})
+	result, err := svc.ClearDeprecationByUUID(context.Background(), apiUUID, tenantID)
+	require.NoError(t, err)
+	assert.Nil(t, result.Deprecation)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,457 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/storage"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/datatypes"
+)
+
+const (
+	// DefaultAuthEventRetention is how long auth events are kept when their
+	// tenant does not set auth_event_retention_days. PCI DSS 10.7.1 requires
+	// at least 12 months of audit trail history.
+	DefaultAuthEventRetention = 365 * 24 * time.Hour
+
+	// auditArchiveBatchSize bounds how many auth events an archive run
+	// loads at once.
+	auditArchiveBatchSize = 1000
+
+	// auditArchiveStaleAfter is how long a run may stay running before
+	// another run of the tenant is allowed to start.
+	auditArchiveStaleAfter = time.Hour
+
+	// auditArchiveURLTTL is how long the download URL of an archive is
+	// valid.
+	auditArchiveURLTTL = 15 * time.Minute
+)
+
+// errAuditArchiveStoreMissing fails archive runs while no storage provider
+// is configured. Events are kept until one is.
+var errAuditArchiveStoreMissing = errors.New("no archive storage is configured")
+
+// AuditArchiveRunServiceDataResult is the service-layer representation of an
+// archive run.
+type AuditArchiveRunServiceDataResult struct {
+	AuditArchiveRunUUID uuid.UUID
+	Trigger             string
+	Status              string
+	Cutoff              time.Time
+	EventCount          int64
+	ObjectKey           *string
+	SizeBytes           int64
+	Error               *string
+	// DownloadURL reads the archive for a short time. Only GetRun sets it.
+	DownloadURL *string
+	CompletedAt *time.Time
+	CreatedAt   time.Time
+}
+
+// AuditArchiveService applies each tenant's auth event retention and keeps
+// the history of the runs that archive expired events.
+type AuditArchiveService interface {
+	// ApplyRetention removes the expired auth events of every tenant and
+	// returns how many were removed. Tenants with auth_event_archive set
+	// have their events archived first. Used by the retention background
+	// job.
+	ApplyRetention(ctx context.Context) (int64, error)
+	// Run archives and removes the tenant's expired auth events now.
+	Run(ctx context.Context, tenantID int64) (*AuditArchiveRunServiceDataResult, error)
+	// ListRuns returns a page of the tenant's archive runs, newest first.
+	ListRuns(ctx context.Context, tenantID int64, page, limit int) (*repository.PaginationResult[AuditArchiveRunServiceDataResult], error)
+	// GetRun returns an archive run of the tenant with a download URL for
+	// its archive.
+	GetRun(ctx context.Context, tenantID int64, runUUID uuid.UUID) (*AuditArchiveRunServiceDataResult, error)
+}
+
+type auditArchiveService struct {
+	authEventRepo       repository.AuthEventRepository
+	auditArchiveRunRepo repository.AuditArchiveRunRepository
+	tenantRepo          repository.TenantRepository
+	tenantSettingRepo   repository.TenantSettingRepository
+	store               storage.Store
+}
+
+// NewAuditArchiveService creates an AuditArchiveService. Archives are kept
+// in store; with a nil store, runs of tenants that archive fail and their
+// events are kept.
+func NewAuditArchiveService(
+	authEventRepo repository.AuthEventRepository,
+	auditArchiveRunRepo repository.AuditArchiveRunRepository,
+	tenantRepo repository.TenantRepository,
+	tenantSettingRepo repository.TenantSettingRepository,
+	store storage.Store,
+) AuditArchiveService {
+	return &auditArchiveService{
+		authEventRepo:       authEventRepo,
+		auditArchiveRunRepo: auditArchiveRunRepo,
+		tenantRepo:          tenantRepo,
+		tenantSettingRepo:   tenantSettingRepo,
+		store:               store,
+	}
+}
+
+// auditRetentionSettings are the audit settings of a tenant that drive the
+// retention job.
+type auditRetentionSettings struct {
+	retention time.Duration
+	archive   bool
+}
+
+// auditArchiveRecord is one line of an archive.
+type auditArchiveRecord struct {
+	AuthEventID    string         `json:"auth_event_id"`
+	TenantID       int64          `json:"tenant_id"`
+	ActorUserID    *int64         `json:"actor_user_id,omitempty"`
+	TargetUserID   *int64         `json:"target_user_id,omitempty"`
+	IPAddress      string         `json:"ip_address"`
+	UserAgent      *string        `json:"user_agent,omitempty"`
+	Country        *string        `json:"country,omitempty"`
+	City           *string        `json:"city,omitempty"`
+	ASN            *int64         `json:"asn,omitempty"`
+	ASOrganization *string        `json:"as_organization,omitempty"`
+	Category       string         `json:"category"`
+	EventType      string         `json:"event_type"`
+	Severity       string         `json:"severity"`
+	Result         string         `json:"result"`
+	Description    *string        `json:"description,omitempty"`
+	ErrorReason    *string        `json:"error_reason,omitempty"`
+	TraceID        *string        `json:"trace_id,omitempty"`
+	Metadata       datatypes.JSON `json:"metadata,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+func (s *auditArchiveService) ApplyRetention(ctx context.Context) (int64, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "auditArchive.applyRetention")
+	defer span.End()
+
+	tenants, err := s.tenantRepo.FindAll()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list tenants failed")
+		return 0, apperror.NewInternal("failed to list tenants", err)
+	}
+
+	// A failing tenant does not hold up the others
+	var removed int64
+	var errs []error
+	for _, tenant := range tenants {
+		count, err := s.applyTenantRetention(ctx, tenant.TenantID)
+		removed += count
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tenant %d: %w", tenant.TenantID, err))
+		}
+	}
+
+	span.SetAttributes(attribute.Int64("auth_event.removed", removed))
+	if err := errors.Join(errs...); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "retention failed for some tenants")
+		return removed, err
+	}
+	span.SetStatus(codes.Ok, "")
+	return removed, nil
+}
+
+// applyTenantRetention removes one tenant's expired events, archiving them
+// first when the tenant asks for it. Scheduled runs that find nothing to
+// archive are not recorded.
+func (s *auditArchiveService) applyTenantRetention(ctx context.Context, tenantID int64) (int64, error) {
+	settings, err := s.settings(tenantID)
+	if err != nil {
+		return 0, err
+	}
+	cutoff := time.Now().UTC().Add(-settings.retention)
+
+	if !settings.archive {
+		count, err := s.authEventRepo.DeleteByTenantOlderThan(tenantID, cutoff, 0)
+		if err != nil {
+			return 0, apperror.NewInternal("failed to delete expired auth events", err)
+		}
+		return count, nil
+	}
+
+	running, err := s.auditArchiveRunRepo.FindRunningByTenantID(tenantID, time.Now().Add(-auditArchiveStaleAfter))
+	if err != nil {
+		return 0, apperror.NewInternal("failed to load archive runs", err)
+	}
+	if running != nil {
+		return 0, nil
+	}
+	expired, err := s.authEventRepo.FindFeed(repository.AuthEventRepositoryFeedFilter{TenantID: tenantID, Before: &cutoff, Limit: 1})
+	if err != nil {
+		return 0, apperror.NewInternal("failed to load auth events", err)
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	run, err := s.archive(ctx, tenantID, cutoff, model.AuditArchiveTriggerScheduled)
+	if err != nil {
+		return 0, err
+	}
+	if run.Status == model.AuditArchiveStatusFailed {
+		return 0, errors.New(*run.Error)
+	}
+	return run.EventCount, nil
+}
+
+func (s *auditArchiveService) Run(ctx context.Context, tenantID int64) (*AuditArchiveRunServiceDataResult, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "auditArchive.run")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID))
+
+	settings, err := s.settings(tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "load audit settings failed")
+		return nil, err
+	}
+	if !settings.archive {
+		span.SetStatus(codes.Error, "archiving disabled")
+		return nil, apperror.NewValidation("auth event archiving is not enabled for this tenant")
+	}
+
+	running, err := s.auditArchiveRunRepo.FindRunningByTenantID(tenantID, time.Now().Add(-auditArchiveStaleAfter))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "load archive runs failed")
+		return nil, apperror.NewInternal("failed to load archive runs", err)
+	}
+	if running != nil {
+		span.SetStatus(codes.Error, "archive run in progress")
+		return nil, apperror.NewConflict("an archive run is already in progress")
+	}
+
+	run, err := s.archive(ctx, tenantID, time.Now().UTC().Add(-settings.retention), model.AuditArchiveTriggerManual)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "archive run failed")
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.String("audit_archive_run.uuid", run.AuditArchiveRunUUID.String()),
+		attribute.String("audit_archive_run.status", run.Status),
+	)
+	span.SetStatus(codes.Ok, "")
+	return toAuditArchiveRunServiceDataResult(run), nil
+}
+
+// archive records a run, writes the tenant's events older than cutoff to
+// the store as gzip-compressed NDJSON and deletes the archived events. A
+// failure is recorded on the run, which is returned without an error;
+// nothing is deleted then.
+func (s *auditArchiveService) archive(ctx context.Context, tenantID int64, cutoff time.Time, trigger string) (*model.AuditArchiveRun, error) {
+	run, err := s.auditArchiveRunRepo.Create(&model.AuditArchiveRun{
+		TenantID: tenantID,
+		Trigger:  trigger,
+		Status:   model.AuditArchiveStatusRunning,
+		Cutoff:   cutoff,
+	})
+	if err != nil {
+		return nil, apperror.NewInternal("failed to create archive run", err)
+	}
+
+	if err := s.archiveEvents(ctx, run); err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		run.Status = model.AuditArchiveStatusFailed
+		run.Error = ptr.Ptr(err.Error())
+	} else {
+		run.Status = model.AuditArchiveStatusCompleted
+	}
+	run.CompletedAt = ptr.TimePtr(time.Now())
+
+	if _, err := s.auditArchiveRunRepo.CreateOrUpdate(run); err != nil {
+		return nil, apperror.NewInternal("failed to save archive run", err)
+	}
+	return run, nil
+}
+
+// archiveEvents does the work of a run and fills in its counters.
+func (s *auditArchiveService) archiveEvents(ctx context.Context, run *model.AuditArchiveRun) error {
+	if s.store == nil {
+		return errAuditArchiveStoreMissing
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(gz)
+	var lastID int64
+	for {
+		events, err := s.authEventRepo.FindFeed(repository.AuthEventRepositoryFeedFilter{
+			TenantID: run.TenantID,
+			AfterID:  lastID,
+			Before:   &run.Cutoff,
+			Limit:    auditArchiveBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("load auth events: %w", err)
+		}
+		for i := range events {
+			if err := encoder.Encode(toAuditArchiveRecord(&events[i])); err != nil {
+				return fmt.Errorf("encode auth event: %w", err)
+			}
+			lastID = events[i].AuthEventID
+			run.EventCount++
+		}
+		if len(events) < auditArchiveBatchSize {
+			break
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("compress archive: %w", err)
+	}
+	if run.EventCount == 0 {
+		return nil
+	}
+
+	key := fmt.Sprintf("audit-archives/%d/auth-events/%s-%s.ndjson.gz",
+		run.TenantID, run.Cutoff.Format("2006-01-02"), run.AuditArchiveRunUUID)
+	if err := s.store.Put(ctx, key, "application/gzip", buf.Bytes()); err != nil {
+		return fmt.Errorf("upload archive: %w", err)
+	}
+	run.ObjectKey = ptr.Ptr(key)
+	run.SizeBytes = int64(buf.Len())
+
+	// Only the archived events are deleted
+	if _, err := s.authEventRepo.DeleteByTenantOlderThan(run.TenantID, run.Cutoff, lastID); err != nil {
+		return fmt.Errorf("delete archived auth events: %w", err)
+	}
+	return nil
+}
+
+func (s *auditArchiveService) ListRuns(ctx context.Context, tenantID int64, page, limit int) (*repository.PaginationResult[AuditArchiveRunServiceDataResult], error) {
+	_, span := otel.Tracer("service").Start(ctx, "auditArchive.listRuns")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID))
+
+	result, err := s.auditArchiveRunRepo.FindPaginatedByTenantID(tenantID, page, limit)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list archive runs failed")
+		return nil, apperror.NewInternal("failed to list archive runs", err)
+	}
+
+	rows := make([]AuditArchiveRunServiceDataResult, len(result.Data))
+	for i := range result.Data {
+		rows[i] = *toAuditArchiveRunServiceDataResult(&result.Data[i])
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return &repository.PaginationResult[AuditArchiveRunServiceDataResult]{
+		Data:       rows,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}, nil
+}
+
+func (s *auditArchiveService) GetRun(ctx context.Context, tenantID int64, runUUID uuid.UUID) (*AuditArchiveRunServiceDataResult, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "auditArchive.getRun")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int64("tenant.id", tenantID),
+		attribute.String("audit_archive_run.uuid", runUUID.String()),
+	)
+
+	run, err := s.auditArchiveRunRepo.FindByUUIDAndTenantID(runUUID.String(), tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find archive run failed")
+		return nil, apperror.NewInternal("failed to load archive run", err)
+	}
+	if run == nil {
+		span.SetStatus(codes.Error, "archive run not found")
+		return nil, apperror.NewNotFound("archive run not found")
+	}
+
+	result := toAuditArchiveRunServiceDataResult(run)
+	if run.ObjectKey != nil && s.store != nil {
+		url, err := s.store.SignedURL(ctx, *run.ObjectKey, auditArchiveURLTTL)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "sign archive url failed")
+			return nil, apperror.NewInternal("failed to sign archive url", err)
+		}
+		result.DownloadURL = &url
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// settings reads the tenant's auth event retention settings. Missing or
+// non-positive retention falls back to DefaultAuthEventRetention.
+func (s *auditArchiveService) settings(tenantID int64) (auditRetentionSettings, error) {
+	settings := auditRetentionSettings{retention: DefaultAuthEventRetention}
+	setting, err := s.tenantSettingRepo.FindByTenantID(tenantID)
+	if err != nil {
+		return settings, apperror.NewInternal("failed to load tenant settings", err)
+	}
+	if setting == nil {
+		return settings, nil
+	}
+
+	auditConfig := unmarshalJSON(setting.AuditConfig)
+	if days, ok := auditConfig[model.AuditConfigAuthEventRetentionDays].(float64); ok && days >= 1 {
+		settings.retention = time.Duration(days) * 24 * time.Hour
+	}
+	settings.archive = auditConfig[model.AuditConfigAuthEventArchive] == true
+	return settings, nil
+}
+
+func toAuditArchiveRecord(e *model.AuthEvent) auditArchiveRecord {
+	return auditArchiveRecord{
+		AuthEventID:    e.AuthEventUUID.String(),
+		TenantID:       e.TenantID,
+		ActorUserID:    e.ActorUserID,
+		TargetUserID:   e.TargetUserID,
+		IPAddress:      e.IPAddress,
+		UserAgent:      e.UserAgent,
+		Country:        e.Country,
+		City:           e.City,
+		ASN:            e.ASN,
+		ASOrganization: e.ASOrganization,
+		Category:       e.Category,
+		EventType:      e.EventType,
+		Severity:       e.Severity,
+		Result:         e.Result,
+		Description:    e.Description,
+		ErrorReason:    e.ErrorReason,
+		TraceID:        e.TraceID,
+		Metadata:       e.Metadata,
+		CreatedAt:      e.CreatedAt,
+	}
+}
+
+func toAuditArchiveRunServiceDataResult(run *model.AuditArchiveRun) *AuditArchiveRunServiceDataResult {
+	return &AuditArchiveRunServiceDataResult{
+		AuditArchiveRunUUID: run.AuditArchiveRunUUID,
+		Trigger:             run.Trigger,
+		Status:              run.Status,
+		Cutoff:              run.Cutoff,
+		EventCount:          run.EventCount,
+		ObjectKey:           run.ObjectKey,
+		SizeBytes:           run.SizeBytes,
+		Error:               run.Error,
+		CompletedAt:         run.CompletedAt,
+		CreatedAt:           run.CreatedAt,
+	}
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+// auditArchiveFeed serves events like FindFeed does: oldest first, after
+// AfterID, at most Limit.
+func auditArchiveFeed(events []model.AuthEvent) func(repository.AuthEventRepositoryFeedFilter) ([]model.AuthEvent, error) {
+	return func(f repository.AuthEventRepositoryFeedFilter) ([]model.AuthEvent, error) {
+		var out []model.AuthEvent
+		for _, e := range events {
+			if e.AuthEventID > f.AfterID && len(out) < f.Limit {
+				out = append(out, e)
+			}
+		}
+		return out, nil
+	}
+}
+
+func auditSettingRepo(config map[string]any) *mockTenantSettingRepo {
+	raw, _ := json.Marshal(config)
+	return &mockTenantSettingRepo{
+		findByTenantIDFn: func(int64) (*model.TenantSetting, error) {
+			return &model.TenantSetting{AuditConfig: datatypes.JSON(raw)}, nil
+		},
+	}
+}
+
+func singleTenantRepo(tenantID int64) *mockTenantRepo {
+	return &mockTenantRepo{
+		findAllFn: func(...string) ([]model.Tenant, error) {
+			return []model.Tenant{{TenantID: tenantID}}, nil
+		},
+	}
+}
+
+func TestAuditArchiveService_ApplyRetention(t *testing.T) {
+	events := []model.AuthEvent{
+		{AuthEventID: 3, AuthEventUUID: uuid.New(), TenantID: 1, EventType: model.AuthEventTypeLoginSuccess},
+		{AuthEventID: 8, AuthEventUUID: uuid.New(), TenantID: 1, EventType: model.AuthEventTypeLoginFail},
+	}
+
+	t.Run("without archiving deletes past the default retention", func(t *testing.T) {
+		var gotCutoff time.Time
+		var gotMaxID int64 = -1
+		authEventRepo := &mockAuthEventRepo{
+			deleteByTenantFn: func(tenantID int64, cutoff time.Time, maxID int64) (int64, error) {
+				assert.Equal(t, int64(1), tenantID)
+				gotCutoff, gotMaxID = cutoff, maxID
+				return 4, nil
+			},
+		}
+		svc := NewAuditArchiveService(authEventRepo, &mockAuditArchiveRunRepo{}, singleTenantRepo(1), &mockTenantSettingRepo{}, nil)
+
+		removed, err := svc.ApplyRetention(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), removed)
+		assert.Zero(t, gotMaxID)
+		assert.WithinDuration(t, time.Now().Add(-DefaultAuthEventRetention), gotCutoff, time.Minute)
+	})
+
+	t.Run("tenant retention days are honoured", func(t *testing.T) {
+		var gotCutoff time.Time
+		authEventRepo := &mockAuthEventRepo{
+			deleteByTenantFn: func(_ int64, cutoff time.Time, _ int64) (int64, error) {
+				gotCutoff = cutoff
+				return 0, nil
+			},
+		}
+		settings := auditSettingRepo(map[string]any{model.AuditConfigAuthEventRetentionDays: 30})
+		svc := NewAuditArchiveService(authEventRepo, &mockAuditArchiveRunRepo{}, singleTenantRepo(1), settings, nil)
+
+		_, err := svc.ApplyRetention(context.Background())
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(-30*24*time.Hour), gotCutoff, time.Minute)
+	})
+
+	t.Run("archives as gzip NDJSON then deletes what was archived", func(t *testing.T) {
+		var gotMaxID int64
+		authEventRepo := &mockAuthEventRepo{
+			findFeedFn: auditArchiveFeed(events),
+			deleteByTenantFn: func(_ int64, _ time.Time, maxID int64) (int64, error) {
+				gotMaxID = maxID
+				return 2, nil
+			},
+		}
+		var saved *model.AuditArchiveRun
+		runRepo := &mockAuditArchiveRunRepo{
+			createOrUpdateFn: func(e *model.AuditArchiveRun) (*model.AuditArchiveRun, error) {
+				saved = e
+				return e, nil
+			},
+		}
+		store := &fakePictureStore{}
+		settings := auditSettingRepo(map[string]any{model.AuditConfigAuthEventArchive: true})
+		svc := NewAuditArchiveService(authEventRepo, runRepo, singleTenantRepo(1), settings, store)
+
+		removed, err := svc.ApplyRetention(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), removed)
+		assert.Equal(t, int64(8), gotMaxID)
+
+		require.NotNil(t, saved)
+		assert.Equal(t, model.AuditArchiveStatusCompleted, saved.Status)
+		assert.Equal(t, model.AuditArchiveTriggerScheduled, saved.Trigger)
+		assert.Equal(t, int64(2), saved.EventCount)
+		require.NotNil(t, saved.ObjectKey)
+		assert.NotNil(t, saved.CompletedAt)
+
+		body := store.objects[*saved.ObjectKey]
+		assert.Equal(t, int64(len(body)), saved.SizeBytes)
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		var lines []map[string]any
+		scanner := bufio.NewScanner(gz)
+		for scanner.Scan() {
+			var line map[string]any
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+			lines = append(lines, line)
+		}
+		require.Len(t, lines, 2)
+		assert.Equal(t, events[0].AuthEventUUID.String(), lines[0]["auth_event_id"])
+		assert.Equal(t, model.AuthEventTypeLoginFail, lines[1]["event_type"])
+	})
+
+	t.Run("nothing expired records no run", func(t *testing.T) {
+		runRepo := &mockAuditArchiveRunRepo{
+			createFn: func(*model.AuditArchiveRun) (*model.AuditArchiveRun, error) {
+				t.Fatal("no run should be created")
+				return nil, nil
+			},
+		}
+		settings := auditSettingRepo(map[string]any{model.AuditConfigAuthEventArchive: true})
+		svc := NewAuditArchiveService(&mockAuthEventRepo{}, runRepo, singleTenantRepo(1), settings, &fakePictureStore{})
+
+		removed, err := svc.ApplyRetention(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, removed)
+	})
+
+	t.Run("store failure keeps the events and fails the run", func(t *testing.T) {
+		authEventRepo := &mockAuthEventRepo{
+			findFeedFn: auditArchiveFeed(events),
+			deleteByTenantFn: func(int64, time.Time, int64) (int64, error) {
+				t.Fatal("events must not be deleted")
+				return 0, nil
+			},
+		}
+		var saved *model.AuditArchiveRun
+		runRepo := &mockAuditArchiveRunRepo{
+			createOrUpdateFn: func(e *model.AuditArchiveRun) (*model.AuditArchiveRun, error) {
+				saved = e
+				return e, nil
+			},
+		}
+		settings := auditSettingRepo(map[string]any{model.AuditConfigAuthEventArchive: true})
+		store := &fakePictureStore{putErr: errors.New("bucket unavailable")}
+		svc := NewAuditArchiveService(authEventRepo, runRepo, singleTenantRepo(1), settings, store)
+
+		_, err := svc.ApplyRetention(context.Background())
+		require.Error(t, err)
+		require.NotNil(t, saved)
+		assert.Equal(t, model.AuditArchiveStatusFailed, saved.Status)
+		require.NotNil(t, saved.Error)
+		assert.Contains(t, *saved.Error, "bucket unavailable")
+	})
+
+	t.Run("no store fails the run", func(t *testing.T) {
+		authEventRepo := &mockAuthEventRepo{findFeedFn: auditArchiveFeed(events)}
+		var saved *model.AuditArchiveRun
+		runRepo := &mockAuditArchiveRunRepo{
+			createOrUpdateFn: func(e *model.AuditArchiveRun) (*model.AuditArchiveRun, error) {
+				saved = e
+				return e, nil
+			},
+		}
+		settings := auditSettingRepo(map[string]any{model.AuditConfigAuthEventArchive: true})
+		svc := NewAuditArchiveService(authEventRepo, runRepo, singleTenantRepo(1), settings, nil)
+
+		_, err := svc.ApplyRetention(context.Background())
+		require.Error(t, err)
+		require.NotNil(t, saved)
+		assert.Equal(t, errAuditArchiveStoreMissing.Error(), *saved.Error)
+	})
+
+	t.Run("a failing tenant does not stop the others", func(t *testing.T) {
+		tenantRepo := &mockTenantRepo{
+			findAllFn: func(...string) ([]model.Tenant, error) {
+				return []model.Tenant{{TenantID: 1}, {TenantID: 2}}, nil
+			},
+		}
+		authEventRepo := &mockAuthEventRepo{
+			deleteByTenantFn: func(tenantID int64, _ time.Time, _ int64) (int64, error) {
+				if tenantID == 1 {
+					return 0, errors.New("db down")
+				}
+				return 5, nil
+			},
+		}
+		svc := NewAuditArchiveService(authEventRepo, &mockAuditArchiveRunRepo{}, tenantRepo, &mockTenantSettingRepo{}, nil)
+
+		removed, err := svc.ApplyRetention(context.Background())
+		require.Error(t, err)
+		assert.Equal(t, int64(5), removed)
+	})
+}
+
+func TestAuditArchiveService_Run(t *testing.T) {
+	events := []model.AuthEvent{{AuthEventID: 1, AuthEventUUID: uuid.New(), TenantID: 1}}
+	archiving := auditSettingRepo(map[string]any{model.AuditConfigAuthEventArchive: true})
+
+	t.Run("archiving disabled → validation", func(t *testing.T) {
+		svc := NewAuditArchiveService(&mockAuthEventRepo{}, &mockAuditArchiveRunRepo{}, &mockTenantRepo{}, &mockTenantSettingRepo{}, &fakePictureStore{})
+		_, err := svc.Run(context.Background(), 1)
+		var validation *apperror.ValidationError
+		assert.True(t, errors.As(err, &validation))
+	})
+
+	t.Run("run in progress → conflict", func(t *testing.T) {
+		runRepo := &mockAuditArchiveRunRepo{
+			findRunningByTenantIDFn: func(int64, time.Time) (*model.AuditArchiveRun, error) {
+				return &model.AuditArchiveRun{Status: model.AuditArchiveStatusRunning}, nil
+			},
+		}
+		svc := NewAuditArchiveService(&mockAuthEventRepo{}, runRepo, &mockTenantRepo{}, archiving, &fakePictureStore{})
+		_, err := svc.Run(context.Background(), 1)
+		var conflict *apperror.ConflictError
+		assert.True(t, errors.As(err, &conflict))
+	})
+
+	t.Run("success → manual completed run", func(t *testing.T) {
+		authEventRepo := &mockAuthEventRepo{findFeedFn: auditArchiveFeed(events)}
+		svc := NewAuditArchiveService(authEventRepo, &mockAuditArchiveRunRepo{}, &mockTenantRepo{}, archiving, &fakePictureStore{})
+
+		res, err := svc.Run(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, model.AuditArchiveTriggerManual, res.Trigger)
+		assert.Equal(t, model.AuditArchiveStatusCompleted, res.Status)
+		assert.Equal(t, int64(1), res.EventCount)
+		assert.NotNil(t, res.ObjectKey)
+	})
+
+	t.Run("nothing expired → completed run without archive", func(t *testing.T) {
+		svc := NewAuditArchiveService(&mockAuthEventRepo{}, &mockAuditArchiveRunRepo{}, &mockTenantRepo{}, archiving, &fakePictureStore{})
+
+		res, err := svc.Run(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, model.AuditArchiveStatusCompleted, res.Status)
+		assert.Zero(t, res.EventCount)
+		assert.Nil(t, res.ObjectKey)
+	})
+}
+
+func TestAuditArchiveService_GetRun(t *testing.T) {
+	runUUID := uuid.New()
+
+	t.Run("not found", func(t *testing.T) {
+		svc := NewAuditArchiveService(&mockAuthEventRepo{}, &mockAuditArchiveRunRepo{}, &mockTenantRepo{}, &mockTenantSettingRepo{}, nil)
+		_, err := svc.GetRun(context.Background(), 1, runUUID)
+		var notFound *apperror.NotFoundError
+		assert.True(t, errors.As(err, &notFound))
+	})
+
+	t.Run("archived run has a download URL", func(t *testing.T) {
+		key := "audit-archives/1/auth-events/run.ndjson.gz"
+		runRepo := &mockAuditArchiveRunRepo{
+			findByUUIDAndTenantIDFn: func(id string, tenantID int64) (*model.AuditArchiveRun, error) {
+				assert.Equal(t, runUUID.String(), id)
+				assert.Equal(t, int64(1), tenantID)
+				return &model.AuditArchiveRun{AuditArchiveRunUUID: runUUID, ObjectKey: &key}, nil
+			},
+		}
+		svc := NewAuditArchiveService(&mockAuthEventRepo{}, runRepo, &mockTenantRepo{}, &mockTenantSettingRepo{}, &fakePictureStore{})
+
+		res, err := svc.GetRun(context.Background(), 1, runUUID)
+		require.NoError(t, err)
+		require.NotNil(t, res.DownloadURL)
+		assert.Contains(t, *res.DownloadURL, key)
+	})
+}
+
+func TestAuditArchiveService_ListRuns(t *testing.T) {
+	runRepo := &mockAuditArchiveRunRepo{
+		findPaginatedByTenantIDFn: func(tenantID int64, page, limit int) (*repository.PaginationResult[model.AuditArchiveRun], error) {
+			assert.Equal(t, int64(1), tenantID)
+			return &repository.PaginationResult[model.AuditArchiveRun]{
+				Data:  []model.AuditArchiveRun{{Status: model.AuditArchiveStatusCompleted, EventCount: 3}},
+				Total: 1, Page: page, Limit: limit, TotalPages: 1,
+			}, nil
+		},
+	}
+	svc := NewAuditArchiveService(&mockAuthEventRepo{}, runRepo, &mockTenantRepo{}, &mockTenantSettingRepo{}, nil)
+
+	res, err := svc.ListRuns(context.Background(), 1, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, res.Data, 1)
+	assert.Equal(t, int64(3), res.Data[0].EventCount)
+	assert.Equal(t, 10, res.Limit)
+}
@@ -2,12 +2,15 @@ package service
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/apperror"
 	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
 	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -34,21 +37,25 @@ type AuthEventInput struct {
 // AuthEventServiceDataResult is the service-layer representation of an auth
 // event, decoupled from the persistence model.
 type AuthEventServiceDataResult struct {
-	AuthEventUUID uuid.UUID
-	TenantID      int64
-	ActorUserID   *int64
-	TargetUserID  *int64
-	IPAddress     string
-	UserAgent     *string
-	Category      string
-	EventType     string
-	Severity      string
-	Result        string
-	Description   *string
-	ErrorReason   *string
-	TraceID       *string
-	Metadata      datatypes.JSON
-	CreatedAt     time.Time
+	AuthEventUUID  uuid.UUID
+	TenantID       int64
+	ActorUserID    *int64
+	TargetUserID   *int64
+	IPAddress      string
+	UserAgent      *string
+	Country        *string
+	City           *string
+	ASN            *int64
+	ASOrganization *string
+	Category       string
+	EventType      string
+	Severity       string
+	Result         string
+	Description    *string
+	ErrorReason    *string
+	TraceID        *string
+	Metadata       datatypes.JSON
+	CreatedAt      time.Time
 }
 
 // AuthEventService defines business operations on security auth events.
@@ -61,24 +68,33 @@ type AuthEventService interface {
 	// FindPaginated returns a page of events filtered by the supplied criteria.
 	FindPaginated(ctx context.Context, filter repository.AuthEventRepositoryGetFilter) (*repository.PaginationResult[AuthEventServiceDataResult], error)
 
+	// FindByCursor returns the page of events after cursor, newest first
+	// unless the filter's SortOrder is asc. An empty cursor returns the
+	// first page.
+	FindByCursor(ctx context.Context, filter repository.AuthEventRepositoryGetFilter, cursor string) (*CursorPageResult[AuthEventServiceDataResult], error)
+
 	// FindByUUID returns a single event by UUID scoped to a tenant.
 	FindByUUID(ctx context.Context, tenantID int64, eventUUID uuid.UUID) (*AuthEventServiceDataResult, error)
 
 	// CountByEventType returns the count of events matching the type within a tenant.
 	CountByEventType(ctx context.Context, eventType string, tenantID int64) (int64, error)
 
-	// DeleteOlderThan removes events older than the cutoff. Returns the number
-	// of rows deleted. Used by the retention background job.
+	// DeleteOlderThan removes events of every tenant older than the cutoff.
+	// Returns the number of rows deleted. The retention background job goes
+	// through AuditArchiveService instead, which honours each tenant's
+	// retention and archive settings.
 	DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
 }
 
 type authEventService struct {
 	authEventRepo repository.AuthEventRepository
+	geoLocator    security.GeoLocator
 }
 
-// NewAuthEventService creates a new AuthEventService.
-func NewAuthEventService(authEventRepo repository.AuthEventRepository) AuthEventService {
-	return &authEventService{authEventRepo: authEventRepo}
+// NewAuthEventService creates a new AuthEventService. With a geoLocator,
+// events record where their IP address is located.
+func NewAuthEventService(authEventRepo repository.AuthEventRepository, geoLocator security.GeoLocator) AuthEventService {
+	return &authEventService{authEventRepo: authEventRepo, geoLocator: geoLocator}
 }
 
 // Log records a new auth event. The trace ID is extracted from the span
@@ -92,13 +108,47 @@ func (s *authEventService) Log(ctx context.Context, input AuthEventInput) {
 		attribute.String("auth_event.result", input.Result),
 	)
 
+	event := newAuthEvent(ctx, input)
+	s.locate(ctx, event)
+	if _, err := s.authEventRepo.Create(event); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to persist auth event")
+	}
+}
+
+// locate fills in where the event's IP address is located. Lookup failures
+// are recorded on the span and leave the event without a location.
+func (s *authEventService) locate(ctx context.Context, event *model.AuthEvent) {
+	if s.geoLocator == nil || event.IPAddress == "" {
+		return
+	}
+	location, err := s.geoLocator.Locate(ctx, event.IPAddress)
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		return
+	}
+	if location == nil {
+		return
+	}
+	event.Country = ptr.PtrOrNil(location.Country)
+	event.City = ptr.PtrOrNil(location.City)
+	if location.ASN != 0 {
+		asn := int64(location.ASN)
+		event.ASN = &asn
+		event.ASOrganization = ptr.PtrOrNil(location.ASOrganization)
+	}
+}
+
+// newAuthEvent builds the auth event row for input, taking the trace ID from
+// the span context so it appears in both the DB and OTel.
+func newAuthEvent(ctx context.Context, input AuthEventInput) *model.AuthEvent {
 	var traceID *string
 	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
 		tid := sc.TraceID().String()
 		traceID = &tid
 	}
 
-	event := &model.AuthEvent{
+	return &model.AuthEvent{
 		TenantID:     input.TenantID,
 		ActorUserID:  input.ActorUserID,
 		TargetUserID: input.TargetUserID,
@@ -113,11 +163,6 @@ func (s *authEventService) Log(ctx context.Context, input AuthEventInput) {
 		TraceID:      traceID,
 		Metadata:     input.Metadata,
 	}
-
-	if _, err := s.authEventRepo.Create(event); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, "failed to persist auth event")
-	}
 }
 
 // FindPaginated returns a page of auth events filtered by the supplied criteria.
@@ -147,6 +192,46 @@ func (s *authEventService) FindPaginated(ctx context.Context, filter repository.
 	}, nil
 }
 
+// FindByCursor returns a keyset page of auth events filtered by the supplied
+// criteria.
+func (s *authEventService) FindByCursor(ctx context.Context, filter repository.AuthEventRepositoryGetFilter, cursor string) (*CursorPageResult[AuthEventServiceDataResult], error) {
+	_, span := otel.Tracer("service").Start(ctx, "auth_event.find_by_cursor")
+	defer span.End()
+
+	afterID, err := decodeListCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.authEventRepo.FindCursorPaginated(filter, repository.CursorPage{
+		AfterID:   afterID,
+		Limit:     filter.Limit,
+		Ascending: strings.EqualFold(filter.SortOrder, "asc"),
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find auth events by cursor failed")
+		return nil, apperror.NewInternal("failed to query auth events", err)
+	}
+
+	mapped := make([]AuthEventServiceDataResult, len(result.Data))
+	for i, e := range result.Data {
+		mapped[i] = toAuthEventServiceDataResult(&e)
+	}
+
+	page := &CursorPageResult[AuthEventServiceDataResult]{
+		Data:    mapped,
+		Limit:   result.Limit,
+		HasMore: result.HasMore,
+	}
+	if result.HasMore {
+		page.NextCursor = encodeListCursor(result.Data[len(result.Data)-1].AuthEventID)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return page, nil
+}
+
 // FindByUUID returns a single auth event by UUID scoped to a tenant.
 func (s *authEventService) FindByUUID(ctx context.Context, tenantID int64, eventUUID uuid.UUID) (*AuthEventServiceDataResult, error) {
 	_, span := otel.Tracer("service").Start(ctx, "auth_event.find_by_uuid")
@@ -208,20 +293,24 @@ func (s *authEventService) DeleteOlderThan(ctx context.Context, cutoff time.Time
 
 func toAuthEventServiceDataResult(e *model.AuthEvent) AuthEventServiceDataResult {
 	return AuthEventServiceDataResult{
-		AuthEventUUID: e.AuthEventUUID,
-		TenantID:      e.TenantID,
-		ActorUserID:   e.ActorUserID,
-		TargetUserID:  e.TargetUserID,
-		IPAddress:     e.IPAddress,
-		UserAgent:     e.UserAgent,
-		Category:      e.Category,
-		EventType:     e.EventType,
-		Severity:      e.Severity,
-		Result:        e.Result,
-		Description:   e.Description,
-		ErrorReason:   e.ErrorReason,
-		TraceID:       e.TraceID,
-		Metadata:      e.Metadata,
-		CreatedAt:     e.CreatedAt,
+		AuthEventUUID:  e.AuthEventUUID,
+		TenantID:       e.TenantID,
+		ActorUserID:    e.ActorUserID,
+		TargetUserID:   e.TargetUserID,
+		IPAddress:      e.IPAddress,
+		UserAgent:      e.UserAgent,
+		Country:        e.Country,
+		City:           e.City,
+		ASN:            e.ASN,
+		ASOrganization: e.ASOrganization,
+		Category:       e.Category,
+		EventType:      e.EventType,
+		Severity:       e.Severity,
+		Result:         e.Result,
+		Description:    e.Description,
+		ErrorReason:    e.ErrorReason,
+		TraceID:        e.TraceID,
+		Metadata:       e.Metadata,
+		CreatedAt:      e.CreatedAt,
 	}
 }
@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/trace"
@@ -23,10 +25,14 @@ import (
 type mockAuthEventRepo struct {
 	createFn           func(e *model.AuthEvent) (*model.AuthEvent, error)
 	findPaginatedFn    func(filter repository.AuthEventRepositoryGetFilter) (*repository.PaginationResult[model.AuthEvent], error)
+	findCursorFn       func(filter repository.AuthEventRepositoryGetFilter, page repository.CursorPage) (*repository.CursorPaginationResult[model.AuthEvent], error)
 	findByUUIDAndTIDFn func(uuid string, tenantID int64) (*model.AuthEvent, error)
 	findByDateRangeFn  func(tenantID int64, from, to time.Time) ([]model.AuthEvent, error)
 	deleteOlderThanFn  func(cutoff time.Time) (int64, error)
+	deleteByTenantFn   func(tenantID int64, cutoff time.Time, maxID int64) (int64, error)
 	countByEventTypeFn func(eventType string, tenantID int64) (int64, error)
+	findFeedFn         func(filter repository.AuthEventRepositoryFeedFilter) ([]model.AuthEvent, error)
+	latestIDFn         func(tenantID int64) (int64, error)
 }
 
 func (m *mockAuthEventRepo) WithTx(_ *gorm.DB) repository.AuthEventRepository { return m }
@@ -62,6 +68,12 @@ func (m *mockAuthEventRepo) FindPaginated(filter repository.AuthEventRepositoryG
 	}
 	return &repository.PaginationResult[model.AuthEvent]{}, nil
 }
+func (m *mockAuthEventRepo) FindCursorPaginated(filter repository.AuthEventRepositoryGetFilter, page repository.CursorPage) (*repository.CursorPaginationResult[model.AuthEvent], error) {
+	if m.findCursorFn != nil {
+		return m.findCursorFn(filter, page)
+	}
+	return &repository.CursorPaginationResult[model.AuthEvent]{}, nil
+}
 func (m *mockAuthEventRepo) FindByUUIDAndTenantID(uid string, tid int64) (*model.AuthEvent, error) {
 	if m.findByUUIDAndTIDFn != nil {
 		return m.findByUUIDAndTIDFn(uid, tid)
@@ -80,12 +92,37 @@ func (m *mockAuthEventRepo) DeleteOlderThan(cutoff time.Time) (int64, error) {
 	}
 	return 0, nil
 }
+func (m *mockAuthEventRepo) DeleteByTenantOlderThan(tenantID int64, cutoff time.Time, maxID int64) (int64, error) {
+	if m.deleteByTenantFn != nil {
+		return m.deleteByTenantFn(tenantID, cutoff, maxID)
+	}
+	return 0, nil
+}
 func (m *mockAuthEventRepo) CountByEventType(eventType string, tenantID int64) (int64, error) {
 	if m.countByEventTypeFn != nil {
 		return m.countByEventTypeFn(eventType, tenantID)
 	}
 	return 0, nil
 }
+func (m *mockAuthEventRepo) FindFeed(filter repository.AuthEventRepositoryFeedFilter) ([]model.AuthEvent, error) {
+	if m.findFeedFn != nil {
+		return m.findFeedFn(filter)
+	}
+	return nil, nil
+}
+func (m *mockAuthEventRepo) LatestID(tenantID int64) (int64, error) {
+	if m.latestIDFn != nil {
+		return m.latestIDFn(tenantID)
+	}
+	return 0, nil
+}
+
+// failingGeoLocator fails every lookup.
+type failingGeoLocator struct{}
+
+func (failingGeoLocator) Locate(_ context.Context, _ string) (*security.GeoLocation, error) {
+	return nil, errors.New("geoip database closed")
+}
 
 // ---------------------------------------------------------------------------
 // Log
@@ -100,7 +137,7 @@ func TestAuthEventService_Log(t *testing.T) {
 				return e, nil
 			},
 		}
-		svc := NewAuthEventService(repo)
+		svc := NewAuthEventService(repo, nil)
 		svc.Log(context.Background(), AuthEventInput{
 			TenantID:  1,
 			IPAddress: "10.0.0.1",
@@ -122,7 +159,7 @@ func TestAuthEventService_Log(t *testing.T) {
 				return nil, errors.New("db error")
 			},
 		}
-		svc := NewAuthEventService(repo)
+		svc := NewAuthEventService(repo, nil)
 		// Should not panic — errors are swallowed
 		svc.Log(context.Background(), AuthEventInput{
 			TenantID:  1,
@@ -147,7 +184,7 @@ func TestAuthEventService_Log(t *testing.T) {
 				return e, nil
 			},
 		}
-		svc := NewAuthEventService(repo)
+		svc := NewAuthEventService(repo, nil)
 		svc.Log(context.Background(), AuthEventInput{
 			TenantID:     1,
 			ActorUserID:  &actorID,
@@ -179,7 +216,7 @@ func TestAuthEventService_Log(t *testing.T) {
 				return e, nil
 			},
 		}
-		svc := NewAuthEventService(repo)
+		svc := NewAuthEventService(repo, nil)
 
 		traceID, _ := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
 		spanID, _ := trace.SpanIDFromHex("0102030405060708")
@@ -202,6 +239,45 @@ func TestAuthEventService_Log(t *testing.T) {
 		require.NotNil(t, created.TraceID)
 		assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", *created.TraceID)
 	})
+
+	t.Run("located by the geo locator", func(t *testing.T) {
+		var created *model.AuthEvent
+		repo := &mockAuthEventRepo{
+			createFn: func(e *model.AuthEvent) (*model.AuthEvent, error) {
+				created = e
+				return e, nil
+			},
+		}
+		locator := stubGeoLocator{
+			"203.0.113.7": {Country: "JP", City: "Tokyo", ASN: 2516, ASOrganization: "KDDI CORPORATION"},
+		}
+		svc := NewAuthEventService(repo, locator)
+		svc.Log(context.Background(), AuthEventInput{TenantID: 1, IPAddress: "203.0.113.7", EventType: model.AuthEventTypeLoginSuccess})
+		require.NotNil(t, created)
+		assert.Equal(t, "JP", *created.Country)
+		assert.Equal(t, "Tokyo", *created.City)
+		assert.Equal(t, int64(2516), *created.ASN)
+		assert.Equal(t, "KDDI CORPORATION", *created.ASOrganization)
+
+		svc.Log(context.Background(), AuthEventInput{TenantID: 1, IPAddress: "10.0.0.1", EventType: model.AuthEventTypeLoginSuccess})
+		require.NotNil(t, created)
+		assert.Nil(t, created.Country)
+		assert.Nil(t, created.ASN)
+	})
+
+	t.Run("geo lookup failure still records the event", func(t *testing.T) {
+		var created *model.AuthEvent
+		repo := &mockAuthEventRepo{
+			createFn: func(e *model.AuthEvent) (*model.AuthEvent, error) {
+				created = e
+				return e, nil
+			},
+		}
+		svc := NewAuthEventService(repo, failingGeoLocator{})
+		svc.Log(context.Background(), AuthEventInput{TenantID: 1, IPAddress: "203.0.113.7", EventType: model.AuthEventTypeLoginSuccess})
+		require.NotNil(t, created)
+		assert.Nil(t, created.Country)
+	})
 }
 
 // ---------------------------------------------------------------------------
@@ -221,7 +297,7 @@ func TestAuthEventService_FindPaginated(t *testing.T) {
 				}, nil
 			},
 		}
-		svc := NewAuthEventService(repo)
+		svc := NewAuthEventService(repo, nil)
 		tid := int64(1)
 		result, err := svc.FindPaginated(context.Background(), repository.AuthEventRepositoryGetFilter{TenantID: &tid})
 		require.NoError(t, err)
@@ -236,13 +312,72 @@ func TestAuthEventService_FindPaginated(t *testing.T) {
 				return nil, errors.New("query failed")
 			},
 		}
-		svc := NewAuthEventService(repo)
+		svc := NewAuthEventService(repo, nil)
 		_, err := svc.FindPaginated(context.Background(), repository.AuthEventRepositoryGetFilter{})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to query auth events")
 	})
 }
 
+// ---------------------------------------------------------------------------
+// FindByCursor
+// ---------------------------------------------------------------------------
+
+func TestAuthEventService_FindByCursor(t *testing.T) {
+	t.Run("first page with more to follow", func(t *testing.T) {
+		repo := &mockAuthEventRepo{
+			findCursorFn: func(_ repository.AuthEventRepositoryGetFilter, page repository.CursorPage) (*repository.CursorPaginationResult[model.AuthEvent], error) {
+				assert.Equal(t, repository.CursorPage{Limit: 2}, page)
+				return &repository.CursorPaginationResult[model.AuthEvent]{
+					Data:    []model.AuthEvent{{AuthEventID: 9}, {AuthEventID: 7}},
+					Limit:   2,
+					HasMore: true,
+				}, nil
+			},
+		}
+		svc := NewAuthEventService(repo, nil)
+		result, err := svc.FindByCursor(context.Background(), repository.AuthEventRepositoryGetFilter{Limit: 2}, "")
+		require.NoError(t, err)
+		require.Len(t, result.Data, 2)
+		assert.True(t, result.HasMore)
+		assert.Equal(t, encodeListCursor(7), result.NextCursor)
+	})
+
+	t.Run("resumes after the cursor in ascending order", func(t *testing.T) {
+		repo := &mockAuthEventRepo{
+			findCursorFn: func(_ repository.AuthEventRepositoryGetFilter, page repository.CursorPage) (*repository.CursorPaginationResult[model.AuthEvent], error) {
+				assert.Equal(t, int64(7), page.AfterID)
+				assert.True(t, page.Ascending)
+				return &repository.CursorPaginationResult[model.AuthEvent]{Data: []model.AuthEvent{{AuthEventID: 8}}, Limit: 2}, nil
+			},
+		}
+		svc := NewAuthEventService(repo, nil)
+		result, err := svc.FindByCursor(context.Background(), repository.AuthEventRepositoryGetFilter{Limit: 2, SortOrder: "asc"}, encodeListCursor(7))
+		require.NoError(t, err)
+		assert.False(t, result.HasMore)
+		assert.Empty(t, result.NextCursor)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		svc := NewAuthEventService(&mockAuthEventRepo{}, nil)
+		_, err := svc.FindByCursor(context.Background(), repository.AuthEventRepositoryGetFilter{}, "not-a-cursor")
+		var ve *apperror.ValidationError
+		assert.ErrorAs(t, err, &ve)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo := &mockAuthEventRepo{
+			findCursorFn: func(repository.AuthEventRepositoryGetFilter, repository.CursorPage) (*repository.CursorPaginationResult[model.AuthEvent], error) {
+				return nil, errors.New("query failed")
+			},
+		}
+		svc := NewAuthEventService(repo, nil)
+		_, err := svc.FindByCursor(context.Background(), repository.AuthEventRepositoryGetFilter{}, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to query auth events")
+	})
+}
+
 // ---------------------------------------------------------------------------
 // FindByUUID
 // ---------------------------------------------------------------------------
@@ -263,7 +398,7 @@ func TestAuthEventService_FindByUUID(t *testing.T) {
 				}, nil
 			},
 		}
-		svc := NewAuthEventService(repo)
+		svc := NewAuthEventService(repo, nil)
 		result, err := svc.FindByUUID(context.Background(), 1, eventUUID)
 		require.NoError(t, err)
 		require.NotNil(t, result)
@@ -276,7 +411,7 @@ func TestAuthEventService_FindByUUID(t *testing.T) {
 				return nil, nil
 			},
 		}
-		svc := NewAuthEventService(repo)
+		svc := NewAuthEventService(repo, nil)
 		_, err := svc.FindByUUID(context.Background(), 1, uuid.New())
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "not found")
@@ -288,7 +423,7 @@ func TestAuthEventService_FindByUUID(t *testing.T) {
 				return nil, errors.New("db error")
 			},
 		}
-		svc := NewAuthEventService(repo)
+		svc := NewAuthEventService(repo, nil)
 		_, err := svc.FindByUUID(context.Background(), 1, uuid.New())
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to find auth event")
@@ -306,7 +441,7 @@ func TestAuthEventService_CountByEventType(t *testing.T) {
 				return 42, nil
 			},
 		}
-		svc := NewAuthEventService(repo)
+		svc := NewAuthEventService(repo, nil)
 		count, err := svc.CountByEventType(context.Background(), model.AuthEventTypeLoginFail, 1)
 		require.NoError(t, err)
 		assert.Equal(t, int64(42), count)
@@ -318,7 +453,7 @@ func TestAuthEventService_CountByEventType(t *testing.T) {
 				return 0, errors.New("count failed")
 			},
 		}
-		svc := NewAuthEventService(repo)
+		svc := NewAuthEventService(repo, nil)
 		_, err := svc.CountByEventType(context.Background(), model.AuthEventTypeLoginFail, 1)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to count auth events by type")
@@ -336,7 +471,7 @@ func TestAuthEventService_DeleteOlderThan(t *testing.T) {
 				return 100, nil
 			},
 		}
-		svc := NewAuthEventService(repo)
+		svc := NewAuthEventService(repo, nil)
 		count, err := svc.DeleteOlderThan(context.Background(), time.Now().Add(-365*24*time.Hour))
 		require.NoError(t, err)
 		assert.Equal(t, int64(100), count)
@@ -348,7 +483,7 @@ func TestAuthEventService_DeleteOlderThan(t *testing.T) {
 				return 0, errors.New("delete failed")
 			},
 		}
-		svc := NewAuthEventService(repo)
+		svc := NewAuthEventService(repo, nil)
 		_, err := svc.DeleteOlderThan(context.Background(), time.Now())
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to delete old auth events")
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand/v2"
+
+	"github.com/maintainerd/auth/internal/metrics"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"golang.org/x/time/rate"
+)
+
+// Decision labels used in audit metadata and metrics.
+const (
+	authzDecisionAllow = "allow"
+	authzDecisionDeny  = "deny"
+)
+
+// AuthzAuditConfig controls how many authorization decisions reach the audit
+// log. Sample rates are fractions between 0 and 1; MaxPerSecond caps sampled
+// decisions per outcome so a misbehaving client cannot flood the log. A
+// MaxPerSecond of 0 disables the cap.
+type AuthzAuditConfig struct {
+	AllowSampleRate float64
+	DenySampleRate  float64
+	MaxPerSecond    int
+}
+
+// AuthzAuditService samples authorization decisions into the auth event log
+// so that denied (and, optionally, allowed) requests can be explained after
+// the fact. It satisfies middleware.AuthzAuditor.
+type AuthzAuditService interface {
+	// Record samples the decision and, if selected and within the rate cap,
+	// writes it as an AUTHZ auth event. It never fails the caller.
+	Record(ctx context.Context, decision middleware.AuthzDecision)
+}
+
+type authzAuditService struct {
+	authEventService AuthEventService
+	config           AuthzAuditConfig
+	allowLimiter     *rate.Limiter
+	denyLimiter      *rate.Limiter
+	sample           func() float64
+}
+
+// NewAuthzAuditService creates a new AuthzAuditService. Allow and deny
+// decisions have independent rate caps so a burst of allowed traffic cannot
+// crowd denials out of the log.
+func NewAuthzAuditService(authEventService AuthEventService, config AuthzAuditConfig) AuthzAuditService {
+	return &authzAuditService{
+		authEventService: authEventService,
+		config:           config,
+		allowLimiter:     newAuthzAuditLimiter(config.MaxPerSecond),
+		denyLimiter:      newAuthzAuditLimiter(config.MaxPerSecond),
+		sample:           rand.Float64,
+	}
+}
+
+func newAuthzAuditLimiter(perSecond int) *rate.Limiter {
+	if perSecond <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+	return rate.NewLimiter(rate.Limit(perSecond), perSecond)
+}
+
+// authzAuditMetadata is the decision context stored in the event metadata.
+type authzAuditMetadata struct {
+	Decision            string   `json:"decision"`
+	Reason              string   `json:"reason"`
+	Transport           string   `json:"transport"`
+	Resource            string   `json:"resource"`
+	RequiredPermissions []string `json:"required_permissions"`
+	MatchedPermission   string   `json:"matched_permission,omitempty"`
+	EvaluatedRoles      []string `json:"evaluated_roles"`
+	SampleRate          float64  `json:"sample_rate"`
+}
+
+func (s *authzAuditService) Record(ctx context.Context, d middleware.AuthzDecision) {
+	decision, sampleRate, limiter := authzDecisionDeny, s.config.DenySampleRate, s.denyLimiter
+	if d.Allowed {
+		decision, sampleRate, limiter = authzDecisionAllow, s.config.AllowSampleRate, s.allowLimiter
+	}
+
+	if sampleRate <= 0 || (sampleRate < 1 && s.sample() >= sampleRate) {
+		return
+	}
+	if !limiter.Allow() {
+		metrics.ObserveAuthzAuditDrop(decision)
+		return
+	}
+
+	// Marshalling a struct of strings and slices cannot fail.
+	meta, _ := json.Marshal(authzAuditMetadata{
+		Decision:            decision,
+		Reason:              d.Reason,
+		Transport:           d.Transport,
+		Resource:            d.Resource,
+		RequiredPermissions: d.RequiredPermissions,
+		MatchedPermission:   d.MatchedPermission,
+		EvaluatedRoles:      d.EvaluatedRoles,
+		SampleRate:          sampleRate,
+	})
+
+	input := AuthEventInput{
+		TenantID:    d.TenantID,
+		ActorUserID: &d.ActorUserID,
+		IPAddress:   d.IPAddress,
+		UserAgent:   ptr.PtrOrNil(d.UserAgent),
+		Category:    model.AuthEventCategoryAuthz,
+		EventType:   model.AuthEventTypeAuthzAllow,
+		Severity:    model.AuthEventSeverityInfo,
+		Result:      model.AuthEventResultSuccess,
+		Description: ptr.Ptr("Access granted to " + d.Resource),
+		Metadata:    meta,
+	}
+	if !d.Allowed {
+		input.EventType = model.AuthEventTypeAuthzFail
+		input.Severity = model.AuthEventSeverityCritical
+		input.Result = model.AuthEventResultFailure
+		input.Description = ptr.Ptr("Access denied to " + d.Resource)
+		input.ErrorReason = ptr.Ptr(d.Reason)
+	}
+
+	s.authEventService.Log(ctx, input)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAuthzAuditTestService(cfg AuthzAuditConfig, sample float64) (*authzAuditService, *[]AuthEventInput) {
+	var logged []AuthEventInput
+	svc := NewAuthzAuditService(&mockAuthEventService{
+		logFn: func(_ context.Context, input AuthEventInput) { logged = append(logged, input) },
+	}, cfg).(*authzAuditService)
+	svc.sample = func() float64 { return sample }
+	return svc, &logged
+}
+
+func testAuthzDecision(allowed bool) middleware.AuthzDecision {
+	d := middleware.AuthzDecision{
+		TenantID:            3,
+		ActorUserID:         9,
+		IPAddress:           "10.0.0.1",
+		UserAgent:           "curl/8",
+		Transport:           middleware.AuthzTransportREST,
+		Resource:            "DELETE /api/v1/users/abc",
+		Allowed:             allowed,
+		Reason:              middleware.AuthzReasonNoMatchingPermission,
+		RequiredPermissions: []string{"user:delete"},
+		EvaluatedRoles:      []string{"viewer"},
+	}
+	if allowed {
+		d.Reason = middleware.AuthzReasonPermissionGranted
+		d.MatchedPermission = "user:delete"
+	}
+	return d
+}
+
+func TestAuthzAuditService_Record(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deny is logged with decision inputs", func(t *testing.T) {
+		svc, logged := newAuthzAuditTestService(AuthzAuditConfig{DenySampleRate: 1}, 0.99)
+		svc.Record(ctx, testAuthzDecision(false))
+
+		require.Len(t, *logged, 1)
+		in := (*logged)[0]
+		assert.Equal(t, int64(3), in.TenantID)
+		assert.Equal(t, int64(9), *in.ActorUserID)
+		assert.Equal(t, "10.0.0.1", in.IPAddress)
+		assert.Equal(t, "curl/8", *in.UserAgent)
+		assert.Equal(t, model.AuthEventCategoryAuthz, in.Category)
+		assert.Equal(t, model.AuthEventTypeAuthzFail, in.EventType)
+		assert.Equal(t, model.AuthEventSeverityCritical, in.Severity)
+		assert.Equal(t, model.AuthEventResultFailure, in.Result)
+		assert.Equal(t, middleware.AuthzReasonNoMatchingPermission, *in.ErrorReason)
+
+		var meta authzAuditMetadata
+		require.NoError(t, json.Unmarshal(in.Metadata, &meta))
+		assert.Equal(t, authzAuditMetadata{
+			Decision:            authzDecisionDeny,
+			Reason:              middleware.AuthzReasonNoMatchingPermission,
+			Transport:           middleware.AuthzTransportREST,
+			Resource:            "DELETE /api/v1/users/abc",
+			RequiredPermissions: []string{"user:delete"},
+			EvaluatedRoles:      []string{"viewer"},
+			SampleRate:          1,
+		}, meta)
+	})
+
+	t.Run("allow is logged with matched permission", func(t *testing.T) {
+		svc, logged := newAuthzAuditTestService(AuthzAuditConfig{AllowSampleRate: 0.5}, 0.1)
+		svc.Record(ctx, testAuthzDecision(true))
+
+		require.Len(t, *logged, 1)
+		in := (*logged)[0]
+		assert.Equal(t, model.AuthEventTypeAuthzAllow, in.EventType)
+		assert.Equal(t, model.AuthEventResultSuccess, in.Result)
+		assert.Nil(t, in.ErrorReason)
+
+		var meta authzAuditMetadata
+		require.NoError(t, json.Unmarshal(in.Metadata, &meta))
+		assert.Equal(t, "user:delete", meta.MatchedPermission)
+		assert.Equal(t, 0.5, meta.SampleRate)
+	})
+
+	t.Run("not sampled", func(t *testing.T) {
+		svc, logged := newAuthzAuditTestService(AuthzAuditConfig{AllowSampleRate: 0.5, DenySampleRate: 0.5}, 0.5)
+		svc.Record(ctx, testAuthzDecision(true))
+		svc.Record(ctx, testAuthzDecision(false))
+		assert.Empty(t, *logged)
+	})
+
+	t.Run("zero rate never logs", func(t *testing.T) {
+		svc, logged := newAuthzAuditTestService(AuthzAuditConfig{}, 0)
+		svc.Record(ctx, testAuthzDecision(true))
+		svc.Record(ctx, testAuthzDecision(false))
+		assert.Empty(t, *logged)
+	})
+
+	t.Run("rate cap drops excess per outcome", func(t *testing.T) {
+		svc, logged := newAuthzAuditTestService(AuthzAuditConfig{AllowSampleRate: 1, DenySampleRate: 1, MaxPerSecond: 2}, 0)
+		for range 5 {
+			svc.Record(ctx, testAuthzDecision(true))
+		}
+		for range 5 {
+			svc.Record(ctx, testAuthzDecision(false))
+		}
+
+		var allows, denies int
+		for _, in := range *logged {
+			if in.EventType == model.AuthEventTypeAuthzAllow {
+				allows++
+			} else {
+				denies++
+			}
+		}
+		assert.Equal(t, 2, allows)
+		assert.Equal(t, 2, denies)
+	})
+
+	t.Run("zero cap is unlimited", func(t *testing.T) {
+		svc, logged := newAuthzAuditTestService(AuthzAuditConfig{DenySampleRate: 1}, 0)
+		for range 50 {
+			svc.Record(ctx, testAuthzDecision(false))
+		}
+		assert.Len(t, *logged, 50)
+	})
+}
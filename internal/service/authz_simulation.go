@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/abac"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Simulated decision reasons. The first two match the reasons recorded by
+// the permission middleware for real requests.
+const (
+	AuthzSimulationReasonPermissionGranted    = "permission_granted"
+	AuthzSimulationReasonNoMatchingPermission = "no_matching_permission"
+	AuthzSimulationReasonPolicyDenied         = "policy_denied"
+)
+
+// Role sources reported by a simulation.
+const (
+	AuthzSimulationRoleSourceUser         = "user"
+	AuthzSimulationRoleSourceGroup        = "group"
+	AuthzSimulationRoleSourceHypothetical = "hypothetical"
+)
+
+// AuthzSimulationCheck is one action to evaluate. Resource is optional; when
+// it is empty policy statements are matched on action alone.
+type AuthzSimulationCheck struct {
+	Action   string
+	Resource string
+}
+
+// AuthzSimulationInput selects the subject of a simulation: the roles a user
+// holds in the tenant, a hypothetical set of tenant roles, or both combined.
+type AuthzSimulationInput struct {
+	TenantID  int64
+	UserUUID  *uuid.UUID
+	RoleUUIDs []uuid.UUID
+	Checks    []AuthzSimulationCheck
+}
+
+// AuthzSimulationRole is a role that took part in a simulation.
+type AuthzSimulationRole struct {
+	RoleUUID uuid.UUID
+	Name     string
+	Source   string
+}
+
+// AuthzSimulationPolicyMatch is a policy statement that matched a check.
+// Conditional is set when the statement has a condition, which depends on
+// the request and is not evaluated by a simulation.
+type AuthzSimulationPolicyMatch struct {
+	PolicyUUID     uuid.UUID
+	Name           string
+	Version        string
+	Effect         string
+	StatementIndex int
+	Conditional    bool
+}
+
+// AuthzSimulationDecision is the outcome of one check and what contributed
+// to it.
+type AuthzSimulationDecision struct {
+	Action          string
+	Resource        string
+	Allowed         bool
+	Reason          string
+	GrantingRoles   []AuthzSimulationRole
+	MatchedPolicies []AuthzSimulationPolicyMatch
+}
+
+// AuthzSimulationResult holds the evaluated roles and one decision per check,
+// in request order.
+type AuthzSimulationResult struct {
+	Roles     []AuthzSimulationRole
+	Decisions []AuthzSimulationDecision
+}
+
+// AuthzSimulationService answers "what would this subject be able to do?"
+// without granting anything, so that access designs can be validated first.
+type AuthzSimulationService interface {
+	Simulate(ctx context.Context, input AuthzSimulationInput) (*AuthzSimulationResult, error)
+}
+
+type authzSimulationService struct {
+	userRepo   repository.UserRepository
+	roleRepo   repository.RoleRepository
+	policyRepo repository.PolicyRepository
+}
+
+// NewAuthzSimulationService creates a new AuthzSimulationService.
+func NewAuthzSimulationService(
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	policyRepo repository.PolicyRepository,
+) AuthzSimulationService {
+	return &authzSimulationService{
+		userRepo:   userRepo,
+		roleRepo:   roleRepo,
+		policyRepo: policyRepo,
+	}
+}
+
+// simulatedRole pairs a role with the permission names it grants.
+type simulatedRole struct {
+	role        AuthzSimulationRole
+	permissions map[string]bool
+}
+
+// Simulate evaluates every check against the subject's roles and the
+// tenant's active policies. A role grants an action when it holds a
+// permission of exactly that name, as the permission middleware requires.
+// A matching unconditional deny statement in an active policy overrides any
+// grant; allow statements and conditional statements are reported but do not
+// change the decision.
+func (s *authzSimulationService) Simulate(ctx context.Context, input AuthzSimulationInput) (*AuthzSimulationResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "authz.simulate")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int64("tenant.id", input.TenantID),
+		attribute.Int("authz.simulation.checks", len(input.Checks)),
+	)
+
+	roles, err := s.subjectRoles(input)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "resolve simulation roles failed")
+		return nil, err
+	}
+
+	policies, err := s.policyRepo.FindActiveByTenantID(input.TenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "load policies failed")
+		return nil, err
+	}
+
+	result := &AuthzSimulationResult{
+		Roles:     make([]AuthzSimulationRole, len(roles)),
+		Decisions: make([]AuthzSimulationDecision, len(input.Checks)),
+	}
+	for i, r := range roles {
+		result.Roles[i] = r.role
+	}
+	for i, check := range input.Checks {
+		result.Decisions[i] = simulateCheck(check, roles, policies)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// subjectRoles loads the user's roles in the tenant, then the roles they
+// hold through groups, followed by the hypothetical roles, skipping roles
+// listed twice.
+func (s *authzSimulationService) subjectRoles(input AuthzSimulationInput) ([]simulatedRole, error) {
+	var roles []simulatedRole
+	seen := make(map[int64]bool)
+	add := func(role model.Role, source string) {
+		if seen[role.RoleID] {
+			return
+		}
+		seen[role.RoleID] = true
+		perms := make(map[string]bool, len(role.Permissions))
+		for _, p := range role.Permissions {
+			perms[p.Name] = true
+		}
+		roles = append(roles, simulatedRole{
+			role:        AuthzSimulationRole{RoleUUID: role.RoleUUID, Name: role.Name, Source: source},
+			permissions: perms,
+		})
+	}
+
+	if input.UserUUID != nil {
+		user, err := s.userRepo.FindByUUID(*input.UserUUID, "UserIdentities", "Roles.Permissions")
+		if err != nil {
+			return nil, err
+		}
+		if user == nil || !userInTenant(user, input.TenantID) {
+			return nil, apperror.NewNotFoundWithReason("user not found")
+		}
+		for _, role := range user.Roles {
+			// Roles of other tenants never apply to this tenant's resources.
+			if role.TenantID == input.TenantID {
+				add(role, AuthzSimulationRoleSourceUser)
+			}
+		}
+		groupRoles, err := s.userRepo.FindGroupRoles(user.UserID, "Permissions")
+		if err != nil {
+			return nil, err
+		}
+		for _, role := range groupRoles {
+			if role.TenantID == input.TenantID {
+				add(role, AuthzSimulationRoleSourceGroup)
+			}
+		}
+	}
+
+	if len(input.RoleUUIDs) > 0 {
+		ids := make([]string, len(input.RoleUUIDs))
+		for i, id := range input.RoleUUIDs {
+			ids[i] = id.String()
+		}
+		found, err := s.roleRepo.FindByUUIDs(ids, "Permissions")
+		if err != nil {
+			return nil, err
+		}
+		byUUID := make(map[uuid.UUID]model.Role, len(found))
+		for _, role := range found {
+			if role.TenantID == input.TenantID {
+				byUUID[role.RoleUUID] = role
+			}
+		}
+		for _, id := range input.RoleUUIDs {
+			role, ok := byUUID[id]
+			if !ok {
+				return nil, apperror.NewNotFoundWithReason("role not found: " + id.String())
+			}
+			add(role, AuthzSimulationRoleSourceHypothetical)
+		}
+	}
+
+	return roles, nil
+}
+
+// userInTenant reports whether the user has an identity in the tenant.
+func userInTenant(user *model.User, tenantID int64) bool {
+	for _, identity := range user.UserIdentities {
+		if identity.TenantID == tenantID {
+			return true
+		}
+	}
+	return false
+}
+
+func simulateCheck(check AuthzSimulationCheck, roles []simulatedRole, policies []model.Policy) AuthzSimulationDecision {
+	d := AuthzSimulationDecision{
+		Action:          check.Action,
+		Resource:        check.Resource,
+		GrantingRoles:   []AuthzSimulationRole{},
+		MatchedPolicies: []AuthzSimulationPolicyMatch{},
+	}
+
+	for _, r := range roles {
+		if r.permissions[check.Action] {
+			d.GrantingRoles = append(d.GrantingRoles, r.role)
+		}
+	}
+
+	denied := false
+	for _, p := range policies {
+		statements, err := abac.ParseStatements(p.Document)
+		if err != nil {
+			continue
+		}
+		for i, st := range statements {
+			if !abac.MatchPattern(st.Action, check.Action) {
+				continue
+			}
+			if check.Resource != "" && !abac.MatchPattern(st.Resource, check.Resource) {
+				continue
+			}
+			d.MatchedPolicies = append(d.MatchedPolicies, AuthzSimulationPolicyMatch{
+				PolicyUUID:     p.PolicyUUID,
+				Name:           p.Name,
+				Version:        p.Version,
+				Effect:         st.Effect,
+				StatementIndex: i,
+				Conditional:    st.Condition != "",
+			})
+			if st.Effect == model.PolicyEffectDeny && st.Condition == "" {
+				denied = true
+			}
+		}
+	}
+
+	switch {
+	case len(d.GrantingRoles) == 0:
+		d.Reason = AuthzSimulationReasonNoMatchingPermission
+	case denied:
+		d.Reason = AuthzSimulationReasonPolicyDenied
+	default:
+		d.Allowed = true
+		d.Reason = AuthzSimulationReasonPermissionGranted
+	}
+	return d
+}
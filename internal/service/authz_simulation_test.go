@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func simulationRole(id, tenantID int64, name string, perms ...string) model.Role {
+	role := model.Role{RoleID: id, RoleUUID: uuid.New(), TenantID: tenantID, Name: name}
+	for _, p := range perms {
+		role.Permissions = append(role.Permissions, model.Permission{Name: p})
+	}
+	return role
+}
+
+func TestAuthzSimulationService_Simulate(t *testing.T) {
+	editor := simulationRole(1, 7, "editor", "user:read", "user:update")
+	foreign := simulationRole(2, 8, "other-tenant-admin", "user:delete")
+	auditor := simulationRole(3, 7, "auditor", "audit:read", "user:read")
+	userID := uuid.New()
+	user := &model.User{
+		UserUUID:       userID,
+		UserIdentities: []model.UserIdentity{{TenantID: 7}},
+		Roles:          []model.Role{editor, foreign},
+	}
+	policies := []model.Policy{{
+		PolicyUUID: uuid.New(),
+		Name:       "no-user-writes-on-billing",
+		Version:    "v1",
+		Document: datatypes.JSON(`{"version":"v1","statement":[
+			{"effect":"allow","action":["user:*"],"resource":["auth:*"]},
+			{"effect":"deny","action":["user:update"],"resource":["billing:*"]},
+			{"effect":"deny","action":["user:read"],"resource":["auth:*"],"condition":"request.ip.startsWith('10.')"}
+		]}`),
+	}}
+
+	newSvc := func() AuthzSimulationService {
+		return NewAuthzSimulationService(
+			&mockUserRepo{findByUUIDFn: func(id any, _ ...string) (*model.User, error) {
+				if id == userID {
+					return user, nil
+				}
+				return nil, nil
+			}},
+			&mockRoleRepo{findByUUIDsFn: func(ids []string, _ ...string) ([]model.Role, error) {
+				var found []model.Role
+				for _, r := range []model.Role{editor, foreign, auditor} {
+					for _, id := range ids {
+						if r.RoleUUID.String() == id {
+							found = append(found, r)
+						}
+					}
+				}
+				return found, nil
+			}},
+			&mockPolicyRepo{findActiveByTenantIDFn: func(tenantID int64) ([]model.Policy, error) {
+				assert.Equal(t, int64(7), tenantID)
+				return policies, nil
+			}},
+		)
+	}
+
+	t.Run("user roles", func(t *testing.T) {
+		res, err := newSvc().Simulate(context.Background(), AuthzSimulationInput{
+			TenantID: 7,
+			UserUUID: &userID,
+			Checks: []AuthzSimulationCheck{
+				{Action: "user:read"},
+				{Action: "user:delete"},
+				{Action: "user:update", Resource: "billing:invoices"},
+				{Action: "user:update", Resource: "auth:users"},
+			},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, res.Roles, 1, "roles of other tenants are ignored")
+		assert.Equal(t, "editor", res.Roles[0].Name)
+		assert.Equal(t, AuthzSimulationRoleSourceUser, res.Roles[0].Source)
+
+		require.Len(t, res.Decisions, 4)
+		read := res.Decisions[0]
+		assert.True(t, read.Allowed, "conditional deny statements do not deny")
+		assert.Equal(t, AuthzSimulationReasonPermissionGranted, read.Reason)
+		assert.Equal(t, []AuthzSimulationRole{res.Roles[0]}, read.GrantingRoles)
+		require.Len(t, read.MatchedPolicies, 2, "without a resource statements match on action")
+		assert.Equal(t, model.PolicyEffectAllow, read.MatchedPolicies[0].Effect)
+		assert.False(t, read.MatchedPolicies[0].Conditional)
+		assert.Equal(t, model.PolicyEffectDeny, read.MatchedPolicies[1].Effect)
+		assert.True(t, read.MatchedPolicies[1].Conditional)
+
+		deleteUser := res.Decisions[1]
+		assert.False(t, deleteUser.Allowed)
+		assert.Equal(t, AuthzSimulationReasonNoMatchingPermission, deleteUser.Reason)
+		assert.Empty(t, deleteUser.GrantingRoles)
+
+		billing := res.Decisions[2]
+		assert.False(t, billing.Allowed)
+		assert.Equal(t, AuthzSimulationReasonPolicyDenied, billing.Reason)
+		require.Len(t, billing.MatchedPolicies, 1)
+		assert.Equal(t, model.PolicyEffectDeny, billing.MatchedPolicies[0].Effect)
+		assert.Equal(t, 1, billing.MatchedPolicies[0].StatementIndex)
+
+		auth := res.Decisions[3]
+		assert.True(t, auth.Allowed)
+		require.Len(t, auth.MatchedPolicies, 1)
+		assert.Equal(t, 0, auth.MatchedPolicies[0].StatementIndex)
+	})
+
+	t.Run("hypothetical roles combined with user", func(t *testing.T) {
+		res, err := newSvc().Simulate(context.Background(), AuthzSimulationInput{
+			TenantID:  7,
+			UserUUID:  &userID,
+			RoleUUIDs: []uuid.UUID{auditor.RoleUUID, editor.RoleUUID},
+			Checks:    []AuthzSimulationCheck{{Action: "user:read"}, {Action: "audit:read"}},
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Roles, 2, "a listed role the user already holds is evaluated once")
+		assert.Equal(t, AuthzSimulationRoleSourceHypothetical, res.Roles[1].Source)
+		assert.Len(t, res.Decisions[0].GrantingRoles, 2)
+		assert.Equal(t, "auditor", res.Decisions[1].GrantingRoles[0].Name)
+	})
+
+	t.Run("roles held through groups", func(t *testing.T) {
+		svc := NewAuthzSimulationService(
+			&mockUserRepo{
+				findByUUIDFn: func(any, ...string) (*model.User, error) { return user, nil },
+				findGroupRolesFn: func(int64) ([]model.Role, error) {
+					return []model.Role{editor, auditor, foreign}, nil
+				},
+			},
+			&mockRoleRepo{},
+			&mockPolicyRepo{},
+		)
+		res, err := svc.Simulate(context.Background(), AuthzSimulationInput{
+			TenantID: 7,
+			UserUUID: &userID,
+			Checks:   []AuthzSimulationCheck{{Action: "audit:read"}},
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Roles, 2, "direct roles win and roles of other tenants are ignored")
+		assert.Equal(t, AuthzSimulationRoleSourceUser, res.Roles[0].Source)
+		assert.Equal(t, "auditor", res.Roles[1].Name)
+		assert.Equal(t, AuthzSimulationRoleSourceGroup, res.Roles[1].Source)
+		assert.True(t, res.Decisions[0].Allowed)
+	})
+
+	t.Run("user of another tenant", func(t *testing.T) {
+		_, err := newSvc().Simulate(context.Background(), AuthzSimulationInput{TenantID: 9, UserUUID: &userID})
+		var notFound *apperror.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		missing := uuid.New()
+		_, err := newSvc().Simulate(context.Background(), AuthzSimulationInput{TenantID: 7, UserUUID: &missing})
+		var notFound *apperror.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("role of another tenant", func(t *testing.T) {
+		_, err := newSvc().Simulate(context.Background(), AuthzSimulationInput{TenantID: 7, RoleUUIDs: []uuid.UUID{foreign.RoleUUID}})
+		var notFound *apperror.NotFoundError
+		require.ErrorAs(t, err, &notFound)
+		assert.Contains(t, err.Error(), foreign.RoleUUID.String())
+	})
+
+	t.Run("policy error", func(t *testing.T) {
+		svc := NewAuthzSimulationService(&mockUserRepo{}, &mockRoleRepo{}, &mockPolicyRepo{
+			findActiveByTenantIDFn: func(int64) ([]model.Policy, error) { return nil, errors.New("db down") },
+		})
+		_, err := svc.Simulate(context.Background(), AuthzSimulationInput{TenantID: 7})
+		require.Error(t, err)
+	})
+}
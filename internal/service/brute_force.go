@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/datatypes"
+)
+
+// Brute-force detection defaults, used when the tenant's threat settings do
+// not name a value. Failures are counted over bruteForceWindow.
+const (
+	bruteForceWindow                   = time.Hour
+	defaultSubnetFailureThreshold      = 50
+	defaultCredentialStuffingThreshold = 10
+	defaultIPBlockDuration             = time.Hour
+)
+
+// errIPBlocked refuses a login from an address a deny rule covers.
+var errIPBlocked = apperror.WithCode(apperror.CodeIPBlocked, apperror.NewForbidden("sign in from this address is blocked"))
+
+// BruteForceService enforces the tenant's IP deny rules at login and blocks
+// subnets and addresses that attack it.
+type BruteForceService interface {
+	// CheckIP refuses a login from the client IP in ctx when an active,
+	// unexpired deny or blacklist rule of the tenant covers it.
+	CheckIP(ctx context.Context, tenantID int64) error
+	// RecordFailure counts a failed login for identifier from the client IP
+	// in ctx. When the /24 subnet fails too often, or the address fails for
+	// too many distinct usernames, a temporary deny rule is added. It does
+	// nothing unless the tenant has enabled brute-force detection in its
+	// threat settings.
+	RecordFailure(ctx context.Context, tenantID int64, identifier string)
+}
+
+type bruteForceService struct {
+	ipRestrictionRuleRepo repository.IPRestrictionRuleRepository
+	securitySettingRepo   repository.SecuritySettingRepository
+	authEventService      AuthEventService
+}
+
+// NewBruteForceService creates a BruteForceService. Failures are counted in
+// the rate limiter's Redis; without one nothing is ever blocked
+// automatically.
+func NewBruteForceService(
+	ipRestrictionRuleRepo repository.IPRestrictionRuleRepository,
+	securitySettingRepo repository.SecuritySettingRepository,
+	authEventService AuthEventService,
+) BruteForceService {
+	return &bruteForceService{
+		ipRestrictionRuleRepo: ipRestrictionRuleRepo,
+		securitySettingRepo:   securitySettingRepo,
+		authEventService:      authEventService,
+	}
+}
+
+// bruteForceSettings are the threat settings of a tenant that drive
+// brute-force detection.
+type bruteForceSettings struct {
+	enabled                     bool
+	subnetFailureThreshold      int64
+	credentialStuffingThreshold int64
+	blockDuration               time.Duration
+}
+
+func (s *bruteForceService) CheckIP(ctx context.Context, tenantID int64) error {
+	_, span := otel.Tracer("service").Start(ctx, "bruteForce.checkIP")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID))
+
+	ipAddress := middleware.ClientIPFromContext(ctx)
+	if ipAddress == "" {
+		span.SetStatus(codes.Ok, "")
+		return nil
+	}
+
+	rules, err := s.ipRestrictionRuleRepo.FindActiveDenyByTenantID(tenantID, time.Now())
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find deny rules failed")
+		return apperror.NewInternal("failed to load ip restriction rules", err)
+	}
+	for _, rule := range rules {
+		if security.IPMatches(rule.IPAddress, ipAddress) {
+			span.SetAttributes(attribute.String("ip_restriction_rule.uuid", rule.IPRestrictionRuleUUID.String()))
+			span.SetStatus(codes.Error, "ip blocked")
+			return errIPBlocked
+		}
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+func (s *bruteForceService) RecordFailure(ctx context.Context, tenantID int64, identifier string) {
+	ctx, span := otel.Tracer("service").Start(ctx, "bruteForce.recordFailure")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID))
+
+	settings, err := s.settings(tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "load threat settings failed")
+		return
+	}
+	ipAddress := middleware.ClientIPFromContext(ctx)
+	subnet := security.SubnetOf(ipAddress)
+	if !settings.enabled || subnet == "" {
+		span.SetStatus(codes.Ok, "")
+		return
+	}
+
+	subnetFailures := security.RecordSubnetFailure(subnet, bruteForceWindow)
+	identifiers := security.RecordLoginIdentifier(ipAddress, identifier, bruteForceWindow)
+	span.SetAttributes(
+		attribute.Int64("brute_force.subnet_failures", subnetFailures),
+		attribute.Int64("brute_force.identifiers", identifiers),
+	)
+
+	// The subnet block covers the address too, so it wins
+	switch {
+	case subnetFailures >= settings.subnetFailureThreshold:
+		s.block(ctx, tenantID, subnet, "ip_blocked", settings.blockDuration,
+			fmt.Sprintf("%d failed logins from %s within %s", subnetFailures, subnet, bruteForceWindow))
+		security.ResetBruteForceCounters(subnet, ipAddress)
+	case identifiers >= settings.credentialStuffingThreshold:
+		s.block(ctx, tenantID, ipAddress, "credential_stuffing_detected", settings.blockDuration,
+			fmt.Sprintf("Failed logins for %d usernames from %s within %s", identifiers, ipAddress, bruteForceWindow))
+		security.ResetBruteForceCounters(subnet, ipAddress)
+	}
+	span.SetStatus(codes.Ok, "")
+}
+
+// block adds a temporary deny rule for target, an address or CIDR range,
+// unless one already covers it, and reports the block. Failures are recorded
+// but do not fail the login.
+func (s *bruteForceService) block(ctx context.Context, tenantID int64, target, securityEventType string, duration time.Duration, reason string) {
+	now := time.Now()
+	rules, err := s.ipRestrictionRuleRepo.FindActiveDenyByTenantID(tenantID, now)
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		return
+	}
+	for _, rule := range rules {
+		if rule.IPAddress == target {
+			return
+		}
+	}
+
+	expiresAt := now.Add(duration)
+	rule, err := s.ipRestrictionRuleRepo.Create(&model.IPRestrictionRule{
+		TenantID:    tenantID,
+		Description: "Blocked automatically: " + reason,
+		Type:        model.IPRuleTypeDeny,
+		IPAddress:   target,
+		Status:      model.StatusActive,
+		Source:      model.IPRuleSourceAutomatic,
+		ExpiresAt:   &expiresAt,
+	})
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		return
+	}
+
+	metadata, _ := json.Marshal(map[string]any{
+		"ip_restriction_rule_uuid": rule.IPRestrictionRuleUUID.String(),
+		"blocked":                  target,
+		"reason":                   securityEventType,
+	})
+	details := fmt.Sprintf("%s; %s blocked until %s", reason, target, expiresAt.UTC().Format(time.RFC3339))
+	security.LogSecurityEvent(security.SecurityEvent{
+		EventType: securityEventType,
+		TenantID:  tenantID,
+		Severity:  "HIGH",
+		ClientIP:  middleware.ClientIPFromContext(ctx),
+		UserAgent: middleware.UserAgentFromContext(ctx),
+		Timestamp: now,
+		Details:   details,
+	})
+	s.authEventService.Log(ctx, AuthEventInput{
+		TenantID:    tenantID,
+		IPAddress:   middleware.ClientIPFromContext(ctx),
+		UserAgent:   ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:    model.AuthEventCategoryAuthn,
+		EventType:   model.AuthEventTypeIPBlocked,
+		Severity:    model.AuthEventSeverityCritical,
+		Result:      model.AuthEventResultFailure,
+		Description: ptr.Ptr(details),
+		Metadata:    datatypes.JSON(metadata),
+	})
+}
+
+// settings reads the tenant's brute-force settings. Without a stored setting
+// detection is off.
+func (s *bruteForceService) settings(tenantID int64) (bruteForceSettings, error) {
+	settings := bruteForceSettings{
+		subnetFailureThreshold:      defaultSubnetFailureThreshold,
+		credentialStuffingThreshold: defaultCredentialStuffingThreshold,
+		blockDuration:               defaultIPBlockDuration,
+	}
+	setting, err := s.securitySettingRepo.FindByUserPoolID(tenantID)
+	if err != nil {
+		return settings, apperror.NewInternal("failed to load security settings", err)
+	}
+	if setting == nil {
+		return settings, nil
+	}
+
+	threatConfig := unmarshalJSON(setting.ThreatConfig)
+	settings.enabled = threatConfig[model.ThreatConfigBruteForceDetection] == true
+	if threshold, ok := threatConfig[model.ThreatConfigSubnetFailureThreshold].(float64); ok && threshold >= 1 {
+		settings.subnetFailureThreshold = int64(threshold)
+	}
+	if threshold, ok := threatConfig[model.ThreatConfigCredentialStuffingThreshold].(float64); ok && threshold >= 1 {
+		settings.credentialStuffingThreshold = int64(threshold)
+	}
+	if minutes, ok := threatConfig[model.ThreatConfigIPBlockDurationMinutes].(float64); ok && minutes >= 1 {
+		settings.blockDuration = time.Duration(minutes) * time.Minute
+	}
+	return settings, nil
+}
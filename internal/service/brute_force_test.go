@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/security"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+// rateLimiterBruteForce starts a miniredis instance, wires it into the rate
+// limiter that counts failures, and returns a cleanup function that resets
+// the rate limiter to nil after the test.
+func rateLimiterBruteForce(t *testing.T) func() {
+	t.Helper()
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	security.InitRateLimiter(rdb)
+
+	return func() {
+		security.InitRateLimiter(nil)
+		rdb.Close()
+		mr.Close()
+	}
+}
+
+// newBruteForceService returns a BruteForceService with threatConfig as the
+// tenant's threat settings. The deny rules it creates are appended to rules,
+// which are also its active deny rules, and its auth events to logged.
+func newBruteForceService(threatConfig string, rules *[]model.IPRestrictionRule, logged *[]AuthEventInput) BruteForceService {
+	ruleRepo := &mockIPRestrictionRuleRepo{
+		findActiveDenyFn: func(int64, time.Time) ([]model.IPRestrictionRule, error) { return *rules, nil },
+		createFn: func(e *model.IPRestrictionRule) (*model.IPRestrictionRule, error) {
+			*rules = append(*rules, *e)
+			return e, nil
+		},
+	}
+	securitySettingRepo := &mockSecuritySettingRepo{findByUserPoolIDFn: func(int64) (*model.SecuritySetting, error) {
+		return &model.SecuritySetting{ThreatConfig: datatypes.JSON(threatConfig)}, nil
+	}}
+	authEvents := &mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { *logged = append(*logged, in) }}
+	return NewBruteForceService(ruleRepo, securitySettingRepo, authEvents)
+}
+
+func TestBruteForceService_CheckIP(t *testing.T) {
+	rules := []model.IPRestrictionRule{
+		{IPAddress: "203.0.113.0/24", Type: model.IPRuleTypeDeny},
+		{IPAddress: "198.51.100.9", Type: model.IPRuleTypeBlacklist},
+	}
+	ruleRepo := &mockIPRestrictionRuleRepo{findActiveDenyFn: func(tenantID int64, _ time.Time) ([]model.IPRestrictionRule, error) {
+		assert.Equal(t, int64(1), tenantID)
+		return rules, nil
+	}}
+	svc := NewBruteForceService(ruleRepo, &mockSecuritySettingRepo{}, &mockAuthEventService{})
+
+	tests := []struct {
+		ip      string
+		blocked bool
+	}{
+		{"203.0.113.77", true},
+		{"198.51.100.9", true},
+		{"198.51.100.10", false},
+		{"192.0.2.1", false},
+		{"", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.ip, func(t *testing.T) {
+			err := svc.CheckIP(loginContext(tc.ip, "curl/8"), 1)
+			if !tc.blocked {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			code, ok := apperror.CodeOf(err)
+			require.True(t, ok)
+			assert.Equal(t, apperror.CodeIPBlocked, code)
+		})
+	}
+
+	t.Run("repository error", func(t *testing.T) {
+		ruleRepo := &mockIPRestrictionRuleRepo{findActiveDenyFn: func(int64, time.Time) ([]model.IPRestrictionRule, error) {
+			return nil, errors.New("db down")
+		}}
+		svc := NewBruteForceService(ruleRepo, &mockSecuritySettingRepo{}, &mockAuthEventService{})
+		assert.Error(t, svc.CheckIP(loginContext("192.0.2.1", "curl/8"), 1))
+	})
+}
+
+func TestBruteForceService_RecordFailure(t *testing.T) {
+	t.Run("disabled does nothing", func(t *testing.T) {
+		cleanup := rateLimiterBruteForce(t)
+		defer cleanup()
+		var rules []model.IPRestrictionRule
+		var logged []AuthEventInput
+		svc := newBruteForceService(`{"subnet_failure_threshold": 1}`, &rules, &logged)
+
+		svc.RecordFailure(loginContext("203.0.113.7", "curl/8"), 1, "jane")
+		assert.Empty(t, rules)
+		assert.Empty(t, logged)
+	})
+
+	t.Run("blocks the subnet", func(t *testing.T) {
+		cleanup := rateLimiterBruteForce(t)
+		defer cleanup()
+		var rules []model.IPRestrictionRule
+		var logged []AuthEventInput
+		svc := newBruteForceService(`{"brute_force_detection_enabled": true, "subnet_failure_threshold": 3, "ip_block_duration_minutes": 30}`, &rules, &logged)
+
+		for i := range 3 {
+			svc.RecordFailure(loginContext(fmt.Sprintf("203.0.113.%d", i+1), "curl/8"), 1, "jane")
+		}
+
+		require.Len(t, rules, 1)
+		rule := rules[0]
+		assert.Equal(t, "203.0.113.0/24", rule.IPAddress)
+		assert.Equal(t, model.IPRuleTypeDeny, rule.Type)
+		assert.Equal(t, model.StatusActive, rule.Status)
+		assert.Equal(t, model.IPRuleSourceAutomatic, rule.Source)
+		require.NotNil(t, rule.ExpiresAt)
+		assert.WithinDuration(t, time.Now().Add(30*time.Minute), *rule.ExpiresAt, time.Minute)
+
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeIPBlocked, logged[0].EventType)
+		assert.Equal(t, model.AuthEventSeverityCritical, logged[0].Severity)
+		assert.Contains(t, string(logged[0].Metadata), `"blocked":"203.0.113.0/24"`)
+	})
+
+	t.Run("blocks the address on credential stuffing", func(t *testing.T) {
+		cleanup := rateLimiterBruteForce(t)
+		defer cleanup()
+		var rules []model.IPRestrictionRule
+		var logged []AuthEventInput
+		svc := newBruteForceService(`{"brute_force_detection_enabled": true, "credential_stuffing_username_threshold": 3}`, &rules, &logged)
+
+		ctx := loginContext("198.51.100.9", "curl/8")
+		// The same username again does not count twice
+		for _, username := range []string{"jane", "JANE", "john", "joe"} {
+			svc.RecordFailure(ctx, 1, username)
+		}
+
+		require.Len(t, rules, 1)
+		assert.Equal(t, "198.51.100.9", rules[0].IPAddress)
+		require.NotNil(t, rules[0].ExpiresAt)
+		assert.WithinDuration(t, time.Now().Add(defaultIPBlockDuration), *rules[0].ExpiresAt, time.Minute)
+		require.Len(t, logged, 1)
+		assert.Contains(t, *logged[0].Description, "3 usernames")
+	})
+
+	t.Run("below thresholds does nothing", func(t *testing.T) {
+		cleanup := rateLimiterBruteForce(t)
+		defer cleanup()
+		var rules []model.IPRestrictionRule
+		var logged []AuthEventInput
+		svc := newBruteForceService(`{"brute_force_detection_enabled": true}`, &rules, &logged)
+
+		for range 5 {
+			svc.RecordFailure(loginContext("192.0.2.1", "curl/8"), 1, "jane")
+		}
+		assert.Empty(t, rules)
+	})
+
+	t.Run("does not block twice", func(t *testing.T) {
+		cleanup := rateLimiterBruteForce(t)
+		defer cleanup()
+		rules := []model.IPRestrictionRule{{IPAddress: "203.0.113.0/24", Type: model.IPRuleTypeDeny}}
+		var logged []AuthEventInput
+		svc := newBruteForceService(`{"brute_force_detection_enabled": true, "subnet_failure_threshold": 1}`, &rules, &logged)
+
+		svc.RecordFailure(loginContext("203.0.113.7", "curl/8"), 1, "jane")
+		assert.Len(t, rules, 1)
+		assert.Empty(t, logged)
+	})
+
+	t.Run("without rate limiter does nothing", func(t *testing.T) {
+		var rules []model.IPRestrictionRule
+		var logged []AuthEventInput
+		svc := newBruteForceService(`{"brute_force_detection_enabled": true, "subnet_failure_threshold": 1}`, &rules, &logged)
+
+		svc.RecordFailure(loginContext("203.0.113.7", "curl/8"), 1, "jane")
+		assert.Empty(t, rules)
+	})
+}
+
+// stubBruteForceService refuses every login with err and records the
+// identifiers of failed logins.
+type stubBruteForceService struct {
+	err    error
+	failed []string
+}
+
+func (s *stubBruteForceService) CheckIP(context.Context, int64) error { return s.err }
+
+func (s *stubBruteForceService) RecordFailure(_ context.Context, _ int64, identifier string) {
+	s.failed = append(s.failed, identifier)
+}
+
+// newBruteForceLoginService returns a LoginService whose user has password
+// and whose logins go through bruteForce.
+func newBruteForceLoginService(t *testing.T, password string, bruteForce BruteForceService) LoginService {
+	gormDB, mock := newMockGormDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	return NewLoginService(gormDB,
+		&mockClientRepo{findSystemFn: func() (*model.Client, error) { return buildActiveClient(), nil }},
+		&mockUserRepo{findByUsernameFn: func(_ string) (*model.User, error) { return buildActiveUser(t, password), nil }},
+		&mockUserTokenRepo{},
+		&mockUserIdentityRepo{findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) {
+			return &model.UserIdentity{Sub: "sub-brute-force"}, nil
+		}},
+		&mockIdentityProviderRepo{},
+		&mockTenantSettingRepo{},
+		&mockSecuritySettingRepo{},
+		&mockAuthEventService{},
+		nil,
+		nil,
+		nil,
+		bruteForce,
+		nil,
+	)
+}
+
+func TestLogin_BruteForce(t *testing.T) {
+	initTestJWTKeysService(t)
+	const password = "S3cur3P@ss!"
+
+	t.Run("blocked address is refused", func(t *testing.T) {
+		bruteForce := &stubBruteForceService{err: errIPBlocked}
+		_, err := newBruteForceLoginService(t, password, bruteForce).Login(context.Background(), "bf-blocked", password, nil, nil, nil)
+		code, ok := apperror.CodeOf(err)
+		require.True(t, ok)
+		assert.Equal(t, apperror.CodeIPBlocked, code)
+		assert.Empty(t, bruteForce.failed)
+	})
+
+	t.Run("wrong password is recorded", func(t *testing.T) {
+		bruteForce := &stubBruteForceService{}
+		_, err := newBruteForceLoginService(t, password, bruteForce).Login(context.Background(), "bf-wrong", "wrong-password", nil, nil, nil)
+		require.Error(t, err)
+		assert.Equal(t, []string{"bf-wrong"}, bruteForce.failed)
+	})
+
+	t.Run("success is not recorded", func(t *testing.T) {
+		bruteForce := &stubBruteForceService{}
+		_, err := newBruteForceLoginService(t, password, bruteForce).Login(context.Background(), "bf-success", password, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, bruteForce.failed)
+	})
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/maintainerd/auth/internal/claims"
+	"github.com/maintainerd/auth/internal/metrics"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/security"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// enrichTokenClaims fetches the extra access token claims configured for
+// client through its claims_enrichment source. A nil fetcher or a client
+// without a source returns no claims. Fetch failures fail open unless the
+// source is marked required: the error is recorded and the token is issued
+// without the extra claims.
+func enrichTokenClaims(
+	ctx context.Context,
+	fetcher claims.Fetcher,
+	client *model.Client,
+	sub string,
+	user *model.User,
+	scope string,
+) (map[string]any, error) {
+	if fetcher == nil || client == nil {
+		return nil, nil
+	}
+
+	src, err := claims.SourceFromConfig(client.Config)
+	if err != nil || src == nil {
+		// Sources are validated when the client is saved, so a parse
+		// failure here means the config predates validation; skip it.
+		return nil, nil
+	}
+
+	extra, outcome, err := fetcher.Fetch(ctx, *src, claims.Request{
+		Subject:  sub,
+		UserUUID: user.UserUUID.String(),
+		Email:    user.Email,
+		ClientID: ptr.Deref(client.Identifier),
+		Scope:    scope,
+	})
+	metrics.ObserveClaimsEnrichment(outcome)
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "claims_enrichment_unavailable",
+			UserID:    user.UserUUID.String(),
+			ClientID:  ptr.Deref(client.Identifier),
+			Timestamp: time.Now(),
+			Details:   err.Error(),
+			Severity:  "MEDIUM",
+		})
+		if src.Required {
+			return nil, err
+		}
+		return nil, nil
+	}
+	return extra, nil
+}
+
+// mergeClaims returns the union of base and override, with override winning
+// on conflicting keys. Either map may be nil.
+func mergeClaims(base, override map[string]any) map[string]any {
+	if len(base) == 0 {
+		return override
+	}
+	if len(override) == 0 {
+		return base
+	}
+	merged := make(map[string]any, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
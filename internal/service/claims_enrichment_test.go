@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/claims"
+	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+type mockClaimsFetcher struct {
+	fetchFn func(ctx context.Context, src claims.Source, req claims.Request) (map[string]any, string, error)
+	calls   int
+}
+
+func (m *mockClaimsFetcher) Fetch(ctx context.Context, src claims.Source, req claims.Request) (map[string]any, string, error) {
+	m.calls++
+	if m.fetchFn != nil {
+		return m.fetchFn(ctx, src, req)
+	}
+	return nil, claims.OutcomeFetched, nil
+}
+
+func claimsFetcherReturning(extra map[string]any, err error) *mockClaimsFetcher {
+	return &mockClaimsFetcher{
+		fetchFn: func(_ context.Context, _ claims.Source, _ claims.Request) (map[string]any, string, error) {
+			if err != nil {
+				return nil, claims.OutcomeError, err
+			}
+			return extra, claims.OutcomeFetched, nil
+		},
+	}
+}
+
+func clientWithClaimsSource(config string) *model.Client {
+	client := buildActiveClient()
+	client.Config = datatypes.JSON(config)
+	return client
+}
+
+func TestEnrichTokenClaims(t *testing.T) {
+	ctx := context.Background()
+	user := &model.User{Email: "a@example.com"}
+	const source = `{"claims_enrichment":{"url":"https://crm.internal/claims","timeout_ms":200}}`
+
+	t.Run("nil fetcher", func(t *testing.T) {
+		extra, err := enrichTokenClaims(ctx, nil, clientWithClaimsSource(source), "sub", user, "openid")
+		require.NoError(t, err)
+		assert.Nil(t, extra)
+	})
+
+	t.Run("client without source", func(t *testing.T) {
+		fetcher := claimsFetcherReturning(map[string]any{"tier": "gold"}, nil)
+		extra, err := enrichTokenClaims(ctx, fetcher, buildActiveClient(), "sub", user, "openid")
+		require.NoError(t, err)
+		assert.Nil(t, extra)
+		assert.Zero(t, fetcher.calls)
+	})
+
+	t.Run("invalid stored source is skipped", func(t *testing.T) {
+		fetcher := claimsFetcherReturning(map[string]any{"tier": "gold"}, nil)
+		extra, err := enrichTokenClaims(ctx, fetcher, clientWithClaimsSource(`{"claims_enrichment":{"url":""}}`), "sub", user, "openid")
+		require.NoError(t, err)
+		assert.Nil(t, extra)
+		assert.Zero(t, fetcher.calls)
+	})
+
+	t.Run("passes request details", func(t *testing.T) {
+		fetcher := &mockClaimsFetcher{
+			fetchFn: func(_ context.Context, src claims.Source, req claims.Request) (map[string]any, string, error) {
+				assert.Equal(t, "https://crm.internal/claims", src.URL)
+				assert.Equal(t, "sub-1", req.Subject)
+				assert.Equal(t, "a@example.com", req.Email)
+				assert.Equal(t, "test-client", req.ClientID)
+				assert.Equal(t, "openid email", req.Scope)
+				return map[string]any{"tier": "gold"}, claims.OutcomeFetched, nil
+			},
+		}
+		extra, err := enrichTokenClaims(ctx, fetcher, clientWithClaimsSource(source), "sub-1", user, "openid email")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"tier": "gold"}, extra)
+	})
+
+	t.Run("failure fails open", func(t *testing.T) {
+		fetcher := claimsFetcherReturning(nil, errors.New("timeout"))
+		extra, err := enrichTokenClaims(ctx, fetcher, clientWithClaimsSource(source), "sub", user, "openid")
+		require.NoError(t, err)
+		assert.Nil(t, extra)
+	})
+
+	t.Run("failure of required source", func(t *testing.T) {
+		fetcher := claimsFetcherReturning(nil, claims.ErrCircuitOpen)
+		_, err := enrichTokenClaims(ctx, fetcher,
+			clientWithClaimsSource(`{"claims_enrichment":{"url":"https://crm.internal","required":true}}`), "sub", user, "openid")
+		require.ErrorIs(t, err, claims.ErrCircuitOpen)
+	})
+}
+
+func TestMergeClaims(t *testing.T) {
+	assert.Nil(t, mergeClaims(nil, nil))
+	assert.Equal(t, map[string]any{"a": 1}, mergeClaims(map[string]any{"a": 1}, nil))
+	assert.Equal(t, map[string]any{"a": 1}, mergeClaims(nil, map[string]any{"a": 1}))
+	assert.Equal(t, map[string]any{"a": 2, "b": 1},
+		mergeClaims(map[string]any{"a": 1, "b": 1}, map[string]any{"a": 2}))
+}
+
+// ---------------------------------------------------------------------------
+// Token issuance integration
+// ---------------------------------------------------------------------------
+
+func newEnrichedLoginService(t *testing.T, password, config string, fetcher claims.Fetcher, hooks ...model.LoginHook) LoginService {
+	t.Helper()
+	gormDB, mock := newMockGormDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	return NewLoginService(gormDB,
+		&mockClientRepo{findSystemFn: func() (*model.Client, error) { return clientWithClaimsSource(config), nil }},
+		&mockUserRepo{findByUsernameFn: func(_ string) (*model.User, error) { return buildActiveUser(t, password), nil }},
+		&mockUserTokenRepo{},
+		&mockUserIdentityRepo{findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) {
+			return &model.UserIdentity{Sub: "sub-enriched"}, nil
+		}},
+		&mockIdentityProviderRepo{},
+		&mockTenantSettingRepo{},
+		&mockSecuritySettingRepo{},
+		&mockAuthEventService{},
+		newLoginHookSvc(hookRepoWith(hooks...), nil),
+		fetcher,
+		nil,
+		nil,
+		nil,
+	)
+}
+
+func TestLogin_EnrichesAccessToken(t *testing.T) {
+	initTestJWTKeysService(t)
+	const password = "S3cur3P@ss!"
+	const source = `{"claims_enrichment":{"url":"https://crm.internal"}}`
+
+	t.Run("claims added", func(t *testing.T) {
+		fetcher := claimsFetcherReturning(map[string]any{"tier": "gold", "seats": float64(5), "sub": "spoofed"}, nil)
+		svc := newEnrichedLoginService(t, password, source, fetcher)
+		res, err := svc.Login(context.Background(), "enriched", password, nil, nil, nil)
+		require.NoError(t, err)
+
+		tokenClaims, err := jwt.ValidateToken(res.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, "gold", tokenClaims["tier"])
+		assert.Equal(t, float64(5), tokenClaims["seats"])
+		assert.Equal(t, "sub-enriched", tokenClaims["sub"])
+	})
+
+	t.Run("hook claims take precedence", func(t *testing.T) {
+		fetcher := claimsFetcherReturning(map[string]any{"tier": "gold", "region": "eu"}, nil)
+		svc := newEnrichedLoginService(t, password, source, fetcher,
+			newRuleLoginHook(1, model.LoginHookTriggerPostLogin, `{"action":"allow","claims":{"tier":"platinum"}}`))
+		res, err := svc.Login(context.Background(), "enriched", password, nil, nil, nil)
+		require.NoError(t, err)
+
+		tokenClaims, err := jwt.ValidateToken(res.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, "platinum", tokenClaims["tier"])
+		assert.Equal(t, "eu", tokenClaims["region"])
+	})
+
+	t.Run("optional source failure still issues token", func(t *testing.T) {
+		svc := newEnrichedLoginService(t, password, source, claimsFetcherReturning(nil, errors.New("down")))
+		res, err := svc.Login(context.Background(), "enriched", password, nil, nil, nil)
+		require.NoError(t, err)
+
+		tokenClaims, err := jwt.ValidateToken(res.AccessToken)
+		require.NoError(t, err)
+		assert.NotContains(t, tokenClaims, "tier")
+	})
+
+	t.Run("required source failure blocks login", func(t *testing.T) {
+		svc := newEnrichedLoginService(t, password,
+			`{"claims_enrichment":{"url":"https://crm.internal","required":true}}`,
+			claimsFetcherReturning(nil, errors.New("down")))
+		_, err := svc.Login(context.Background(), "enriched", password, nil, nil, nil)
+		var target *apperror.InternalError
+		require.ErrorAs(t, err, &target)
+	})
+}
+
+func TestOAuthTokenService_generateTokens_Enrichment(t *testing.T) {
+	initTestJWTKeysService(t)
+	user := &model.User{UserID: 1, Email: "a@example.com"}
+
+	t.Run("claims added", func(t *testing.T) {
+		svc := &oauthTokenService{
+			refreshTokenRepo: &mockOAuthRefreshTokenRepo{},
+			claimsFetcher:    claimsFetcherReturning(map[string]any{"tier": "gold"}, nil),
+		}
+		res, oerr := svc.generateTokens(context.Background(), "sub-1", user,
+			clientWithClaimsSource(`{"claims_enrichment":{"url":"https://crm.internal"}}`), "openid", nil, nil)
+		require.Nil(t, oerr)
+
+		tokenClaims, err := jwt.ValidateToken(res.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, "gold", tokenClaims["tier"])
+	})
+
+	t.Run("required source failure", func(t *testing.T) {
+		svc := &oauthTokenService{
+			refreshTokenRepo: &mockOAuthRefreshTokenRepo{},
+			claimsFetcher:    claimsFetcherReturning(nil, errors.New("down")),
+		}
+		_, oerr := svc.generateTokens(context.Background(), "sub-1", user,
+			clientWithClaimsSource(`{"claims_enrichment":{"url":"https://crm.internal","required":true}}`), "openid", nil, nil)
+		require.NotNil(t, oerr)
+		assert.Equal(t, "server_error", oerr.Code)
+	})
+}
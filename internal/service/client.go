@@ -2,14 +2,20 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/claims"
 	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/middleware"
 	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
 	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -17,9 +23,25 @@ import (
 	"gorm.io/gorm"
 )
 
+// DefaultClientSecretGracePeriod is how long the previous secret of an auth
+// client keeps authenticating after a rotation when no grace period is given.
+const DefaultClientSecretGracePeriod = 24 * time.Hour
+
+// ClientSecretServiceDataResult carries an auth client's secret. Masked is
+// set when ClientSecret only shows the last characters of the secret.
 type ClientSecretServiceDataResult struct {
 	ClientID     string
 	ClientSecret *string
+	Masked       bool
+}
+
+// ClientSecretRotationServiceDataResult carries the new secret of a rotated
+// auth client. It is the only time the new secret is returned by a write.
+type ClientSecretRotationServiceDataResult struct {
+	ClientID                string
+	ClientSecret            string
+	SecretRotatedAt         time.Time
+	PreviousSecretExpiresAt *time.Time
 }
 
 type ClientURIServiceDataResult struct {
@@ -42,8 +64,11 @@ type ClientServiceDataResult struct {
 	Status           string
 	IsDefault        bool
 	IsSystem         bool
-	CreatedAt        time.Time
-	UpdatedAt        time.Time
+	Deprecation      *DeprecationServiceDataResult
+	// ClientSecret is only set on the result of Create.
+	ClientSecret *string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 type ClientAPIServiceDataResult struct {
@@ -80,11 +105,16 @@ type ClientService interface {
 	Get(ctx context.Context, filter ClientServiceGetFilter) (*ClientServiceGetResult, error)
 	GetByUUID(ctx context.Context, ClientUUID uuid.UUID, tenantID int64) (*ClientServiceDataResult, error)
 	GetSecretByUUID(ctx context.Context, ClientUUID uuid.UUID, tenantID int64) (*ClientSecretServiceDataResult, error)
+	RevealSecretByUUID(ctx context.Context, ClientUUID uuid.UUID, tenantID int64, actor *model.User, password string) (*ClientSecretServiceDataResult, error)
 	GetConfigByUUID(ctx context.Context, ClientUUID uuid.UUID, tenantID int64) (datatypes.JSON, error)
 	Create(ctx context.Context, tenantID int64, name string, displayName string, clientType string, domain string, config datatypes.JSON, status string, isDefault bool, identityProviderUUID string, actorUserUUID uuid.UUID) (*ClientServiceDataResult, error)
 	Update(ctx context.Context, ClientUUID uuid.UUID, tenantID int64, name string, displayName string, clientType string, domain string, config datatypes.JSON, status string, isDefault bool, actorUserUUID uuid.UUID) (*ClientServiceDataResult, error)
 	SetStatusByUUID(ctx context.Context, ClientUUID uuid.UUID, tenantID int64, status string, actorUserUUID uuid.UUID) (*ClientServiceDataResult, error)
+	SetDefaultByUUID(ctx context.Context, ClientUUID uuid.UUID, tenantID int64, actorUserUUID uuid.UUID) (*ClientServiceDataResult, error)
 	DeleteByUUID(ctx context.Context, ClientUUID uuid.UUID, tenantID int64, actorUserUUID uuid.UUID) (*ClientServiceDataResult, error)
+	SetDeprecationByUUID(ctx context.Context, ClientUUID uuid.UUID, tenantID int64, sunsetAt *time.Time, notice *string, actorUserUUID uuid.UUID) (*ClientServiceDataResult, error)
+	ClearDeprecationByUUID(ctx context.Context, ClientUUID uuid.UUID, tenantID int64, actorUserUUID uuid.UUID) (*ClientServiceDataResult, error)
+	RotateSecretByUUID(ctx context.Context, ClientUUID uuid.UUID, tenantID int64, gracePeriod time.Duration, actorUserUUID uuid.UUID) (*ClientSecretRotationServiceDataResult, error)
 	CreateURI(ctx context.Context, ClientUUID uuid.UUID, tenantID int64, uri string, uriType string, actorUserUUID uuid.UUID) (*ClientServiceDataResult, error)
 	UpdateURI(ctx context.Context, ClientUUID uuid.UUID, tenantID int64, ClientURIUUID uuid.UUID, uri string, uriType string, actorUserUUID uuid.UUID) (*ClientServiceDataResult, error)
 	DeleteURI(ctx context.Context, ClientUUID uuid.UUID, tenantID int64, ClientURIUUID uuid.UUID, actorUserUUID uuid.UUID) (*ClientServiceDataResult, error)
@@ -111,6 +141,9 @@ type clientService struct {
 	apiRepo              repository.APIRepository
 	userRepo             repository.UserRepository
 	tenantRepo           repository.TenantRepository
+	eventRepo            repository.EventRepository
+	authEventRepo        repository.AuthEventRepository
+	planEnforcer         PlanEnforcer
 }
 
 func NewClientService(
@@ -124,6 +157,9 @@ func NewClientService(
 	apiRepo repository.APIRepository,
 	userRepo repository.UserRepository,
 	tenantRepo repository.TenantRepository,
+	eventRepo repository.EventRepository,
+	authEventRepo repository.AuthEventRepository,
+	planEnforcer PlanEnforcer,
 ) ClientService {
 	return &clientService{
 		db:                   db,
@@ -136,6 +172,9 @@ func NewClientService(
 		apiRepo:              apiRepo,
 		userRepo:             userRepo,
 		tenantRepo:           tenantRepo,
+		eventRepo:            eventRepo,
+		authEventRepo:        authEventRepo,
+		planEnforcer:         planEnforcer,
 	}
 }
 
@@ -243,6 +282,70 @@ func (s *clientService) GetSecretByUUID(ctx context.Context, ClientUUID uuid.UUI
 		return nil, apperror.NewNotFoundWithReason("auth client not found or access denied")
 	}
 
+	// The secret is only shown in full when it is issued and on an explicit
+	// reveal, so a stolen admin session cannot simply read it
+	var masked *string
+	if Client.Secret != nil {
+		masked = ptr.Ptr(security.MaskSecret(*Client.Secret))
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return &ClientSecretServiceDataResult{
+		ClientID:     *Client.Identifier,
+		ClientSecret: masked,
+		Masked:       true,
+	}, nil
+}
+
+// RevealSecretByUUID returns the full secret of an auth client once the actor
+// re-enters their password. Every attempt is recorded in the auth event log,
+// and the secret is only returned once its reveal event is stored.
+func (s *clientService) RevealSecretByUUID(ctx context.Context, ClientUUID uuid.UUID, tenantID int64, actor *model.User, password string) (*ClientSecretServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "client.revealSecret")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("client.uuid", ClientUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	Client, err := s.clientRepo.FindByUUIDAndTenantID(ClientUUID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to fetch client")
+		return nil, err
+	}
+	if Client == nil {
+		span.SetStatus(codes.Error, "auth client not found or access denied")
+		return nil, apperror.NewNotFoundWithReason("auth client not found or access denied")
+	}
+
+	// Wrong passwords count towards a lockout, so a stolen access token
+	// cannot be used to guess the password
+	limiterKey := security.RateLimitKey(actor.UserUUID.String(), "reveal_secret")
+	if err := security.CheckRateLimit(limiterKey); err != nil {
+		span.SetStatus(codes.Error, "reveal rate limited")
+		return nil, apperror.NewForbidden("too many failed attempts, try again later")
+	}
+	if actor.Password == nil {
+		span.SetStatus(codes.Error, "actor has no password")
+		return nil, apperror.NewForbidden("set a password before revealing secrets")
+	}
+	if err := security.ComparePassword([]byte(*actor.Password), []byte(password)); err != nil {
+		security.RecordFailedAttempt(limiterKey)
+		if _, err := s.authEventRepo.Create(s.newSecretRevealEvent(ctx, Client, actor, model.AuthEventResultFailure)); err != nil {
+			span.RecordError(err)
+		}
+		span.SetStatus(codes.Error, "re-authentication failed")
+		return nil, apperror.NewValidation("password is incorrect")
+	}
+	security.ResetFailedAttempts(limiterKey)
+
+	if _, err := s.authEventRepo.Create(s.newSecretRevealEvent(ctx, Client, actor, model.AuthEventResultSuccess)); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to record reveal")
+		return nil, apperror.NewInternal("failed to record secret reveal audit event", err)
+	}
+
 	span.SetStatus(codes.Ok, "")
 	return &ClientSecretServiceDataResult{
 		ClientID:     *Client.Identifier,
@@ -250,6 +353,34 @@ func (s *clientService) GetSecretByUUID(ctx context.Context, ClientUUID uuid.UUI
 	}, nil
 }
 
+// newSecretRevealEvent builds the audit event of an attempt to reveal the
+// secret of an auth client.
+func (s *clientService) newSecretRevealEvent(ctx context.Context, Client *model.Client, actor *model.User, result string) *model.AuthEvent {
+	description := fmt.Sprintf("%s revealed the secret of auth client %s", actor.Username, Client.Name)
+	var reason *string
+	if result == model.AuthEventResultFailure {
+		description = fmt.Sprintf("%s failed to reveal the secret of auth client %s", actor.Username, Client.Name)
+		reason = ptr.Ptr("password is incorrect")
+	}
+	metadata, _ := json.Marshal(map[string]any{
+		"client_uuid": Client.ClientUUID,
+		"client_id":   Client.Identifier,
+	})
+	return newAuthEvent(ctx, AuthEventInput{
+		TenantID:    Client.TenantID,
+		ActorUserID: &actor.UserID,
+		IPAddress:   middleware.ClientIPFromContext(ctx),
+		UserAgent:   ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:    model.AuthEventCategoryAuthz,
+		EventType:   model.AuthEventTypeSecretReveal,
+		Severity:    model.AuthEventSeverityWarn,
+		Result:      result,
+		Description: &description,
+		ErrorReason: reason,
+		Metadata:    datatypes.JSON(metadata),
+	})
+}
+
 func (s *clientService) GetConfigByUUID(ctx context.Context, ClientUUID uuid.UUID, tenantID int64) (datatypes.JSON, error) {
 	_, span := otel.Tracer("service").Start(ctx, "client.getConfig")
 	defer span.End()
@@ -281,6 +412,15 @@ func (s *clientService) Create(ctx context.Context, tenantID int64, name string,
 		attribute.String("client.name", name),
 	)
 
+	if _, err := claims.SourceFromConfig(config); err != nil {
+		span.SetStatus(codes.Error, "invalid claims source")
+		return nil, apperror.NewValidation(err.Error())
+	}
+	if err := rejectPastedSecrets("config", config); err != nil {
+		span.SetStatus(codes.Error, "secret in config")
+		return nil, err
+	}
+
 	var createdClient *model.Client
 
 	err := s.db.Transaction(func(tx *gorm.DB) error {
@@ -311,6 +451,11 @@ func (s *clientService) Create(ctx context.Context, tenantID int64, name string,
 			return err
 		}
 
+		// Stay within the client limit of the tenant's plan
+		if err := s.planEnforcer.CheckClientLimit(ctx, tenantID); err != nil {
+			return err
+		}
+
 		// Check if auth client already exists
 		existingClient, err := txClientRepo.FindByNameAndIdentityProvider(name, identityProvider.IdentityProviderID, tenantID)
 		if err != nil {
@@ -351,6 +496,11 @@ func (s *clientService) Create(ctx context.Context, tenantID int64, name string,
 			return err
 		}
 
+		// Record lifecycle event
+		if err := recordEvent(s.eventRepo.WithTx(tx), newClient.TenantID, ClientCreated(newClientEventPayload(newClient))); err != nil {
+			return err
+		}
+
 		// Fetch Client with Service preloaded
 		createdClient, err = txClientRepo.FindByUUID(newClient.ClientUUID, "IdentityProvider", "ClientURIs")
 		if err != nil {
@@ -366,8 +516,12 @@ func (s *clientService) Create(ctx context.Context, tenantID int64, name string,
 		return nil, err
 	}
 
+	// The secret is only returned in full here and on rotation
+	result := ToClientServiceDataResult(createdClient)
+	result.ClientSecret = createdClient.Secret
+
 	span.SetStatus(codes.Ok, "")
-	return ToClientServiceDataResult(createdClient), nil
+	return result, nil
 }
 
 func (s *clientService) Update(ctx context.Context, ClientUUID uuid.UUID, tenantID int64, name string, displayName string, clientType string, domain string, config datatypes.JSON, status string, isDefault bool, actorUserUUID uuid.UUID) (*ClientServiceDataResult, error) {
@@ -378,14 +532,23 @@ func (s *clientService) Update(ctx context.Context, ClientUUID uuid.UUID, tenant
 		attribute.Int64("tenant.id", tenantID),
 	)
 
+	if _, err := claims.SourceFromConfig(config); err != nil {
+		span.SetStatus(codes.Error, "invalid claims source")
+		return nil, apperror.NewValidation(err.Error())
+	}
+	if err := rejectPastedSecrets("config", config); err != nil {
+		span.SetStatus(codes.Error, "secret in config")
+		return nil, err
+	}
+
 	var updatedClient *model.Client
 
 	err := s.db.Transaction(func(tx *gorm.DB) error {
 		txClientRepo := s.clientRepo.WithTx(tx)
 		txUserRepo := s.userRepo.WithTx(tx)
 
-		// Get auth client
-		Client, err := txClientRepo.FindByUUID(ClientUUID, "IdentityProvider.Tenant", "ClientURIs")
+		// Get auth client, locked so the If-Match check below holds until commit
+		Client, err := s.clientRepo.WithTx(lockForUpdate(tx)).FindByUUID(ClientUUID, "IdentityProvider.Tenant", "ClientURIs")
 		if err != nil || Client == nil {
 			return apperror.NewNotFoundWithReason("auth client not found")
 		}
@@ -401,6 +564,10 @@ func (s *clientService) Update(ctx context.Context, ClientUUID uuid.UUID, tenant
 			return err
 		}
 
+		if err := checkIfMatch(ctx, "auth client", Client.UpdatedAt); err != nil {
+			return err
+		}
+
 		// Check if default
 		if Client.IsDefault {
 			return apperror.NewValidation("default auth client cannot cannot be updated")
@@ -432,6 +599,11 @@ func (s *clientService) Update(ctx context.Context, ClientUUID uuid.UUID, tenant
 			return err
 		}
 
+		// Record lifecycle event
+		if err := recordEvent(s.eventRepo.WithTx(tx), Client.TenantID, ClientUpdated(newClientEventPayload(Client))); err != nil {
+			return err
+		}
+
 		updatedClient = Client
 
 		return nil
@@ -496,6 +668,11 @@ func (s *clientService) SetStatusByUUID(ctx context.Context, ClientUUID uuid.UUI
 			return err
 		}
 
+		// Record lifecycle event
+		if err := recordEvent(s.eventRepo.WithTx(tx), Client.TenantID, ClientUpdated(newClientEventPayload(Client))); err != nil {
+			return err
+		}
+
 		updatedClient = Client
 
 		return nil
@@ -511,6 +688,105 @@ func (s *clientService) SetStatusByUUID(ctx context.Context, ClientUUID uuid.UUI
 	return ToClientServiceDataResult(updatedClient), nil
 }
 
+// SetDefaultByUUID transfers the tenant's default auth client designation to
+// the given client. The previous default is cleared in the same transaction,
+// which is the only supported way to replace a default client.
+func (s *clientService) SetDefaultByUUID(ctx context.Context, ClientUUID uuid.UUID, tenantID int64, actorUserUUID uuid.UUID) (*ClientServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "client.setDefault")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("client.uuid", ClientUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	var updatedClient *model.Client
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txClientRepo := s.clientRepo.WithTx(tx)
+		txUserRepo := s.userRepo.WithTx(tx)
+
+		// Get auth client
+		Client, err := txClientRepo.FindByUUID(ClientUUID, "IdentityProvider.Tenant", "ClientURIs")
+		if err != nil || Client == nil {
+			return apperror.NewNotFoundWithReason("auth client not found")
+		}
+
+		// Validate tenant ownership
+		if Client.TenantID != tenantID {
+			return apperror.NewNotFoundWithReason("auth client not found or access denied")
+		}
+
+		// Get actor user with tenant info
+		actorUser, err := txUserRepo.FindByUUID(actorUserUUID, "UserIdentities.Tenant")
+		if err != nil || actorUser == nil {
+			return apperror.NewNotFoundWithReason("actor user not found")
+		}
+
+		// Validate tenant access permissions
+		if err := ValidateTenantAccess(actorUser, Client.IdentityProvider.Tenant); err != nil {
+			return err
+		}
+
+		// Already the default, nothing to transfer
+		if Client.IsDefault {
+			updatedClient = Client
+			return nil
+		}
+
+		// Check target is eligible
+		if Client.IsSystem {
+			return apperror.NewValidation("system auth client cannot be set as default")
+		}
+		if Client.Status != model.StatusActive {
+			return apperror.NewValidation("only active auth clients can be set as default")
+		}
+		if Client.IsSunset(time.Now()) {
+			return apperror.NewValidation("sunset auth client cannot be set as default")
+		}
+
+		// Capture the current default so its demotion reaches the event feed
+		previous, err := txClientRepo.FindDefaultByTenantID(tenantID)
+		if err != nil {
+			return err
+		}
+
+		// Transfer the default designation
+		if err := txClientRepo.UnsetDefaultByTenantID(tenantID); err != nil {
+			return err
+		}
+
+		Client.IsDefault = true
+		if _, err := txClientRepo.CreateOrUpdate(Client); err != nil {
+			return err
+		}
+
+		// Record lifecycle events
+		txEventRepo := s.eventRepo.WithTx(tx)
+		if previous != nil && previous.ClientID != Client.ClientID {
+			previous.IsDefault = false
+			if err := recordEvent(txEventRepo, previous.TenantID, ClientUpdated(newClientEventPayload(previous))); err != nil {
+				return err
+			}
+		}
+		if err := recordEvent(txEventRepo, Client.TenantID, ClientUpdated(newClientEventPayload(Client))); err != nil {
+			return err
+		}
+
+		updatedClient = Client
+
+		return nil
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to set default client")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return ToClientServiceDataResult(updatedClient), nil
+}
+
 func (s *clientService) DeleteByUUID(ctx context.Context, ClientUUID uuid.UUID, tenantID int64, actorUserUUID uuid.UUID) (*ClientServiceDataResult, error) {
 	_, span := otel.Tracer("service").Start(ctx, "client.delete")
 	defer span.End()
@@ -552,6 +828,11 @@ func (s *clientService) DeleteByUUID(ctx context.Context, ClientUUID uuid.UUID,
 			return err
 		}
 
+		// Record lifecycle event
+		if err := recordEvent(s.eventRepo.WithTx(tx), Client.TenantID, ClientDeleted(newClientEventPayload(Client))); err != nil {
+			return err
+		}
+
 		deletedClient = Client
 
 		return nil
@@ -770,6 +1051,210 @@ func (s *clientService) DeleteURI(ctx context.Context, ClientUUID uuid.UUID, ten
 	return ToClientServiceDataResult(deletedClient), nil
 }
 
+// SetDeprecationByUUID marks an auth client as deprecated. Tokens are still
+// issued with warning headers until the optional sunset date, after which
+// issuance is refused.
+func (s *clientService) SetDeprecationByUUID(ctx context.Context, ClientUUID uuid.UUID, tenantID int64, sunsetAt *time.Time, notice *string, actorUserUUID uuid.UUID) (*ClientServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "client.setDeprecation")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("client.uuid", ClientUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	var updatedClient *model.Client
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		Client, err := s.findMutableClient(tx, ClientUUID, actorUserUUID)
+		if err != nil {
+			return err
+		}
+
+		if err := applyDeprecation(&Client.Deprecation, sunsetAt, notice, time.Now()); err != nil {
+			return err
+		}
+
+		if _, err := s.clientRepo.WithTx(tx).CreateOrUpdate(Client); err != nil {
+			return err
+		}
+
+		if err := recordEvent(s.eventRepo.WithTx(tx), Client.TenantID, ClientUpdated(newClientEventPayload(Client))); err != nil {
+			return err
+		}
+
+		updatedClient = Client
+
+		return nil
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to deprecate client")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return ToClientServiceDataResult(updatedClient), nil
+}
+
+// ClearDeprecationByUUID removes the deprecation and sunset date from an auth
+// client, restoring normal token issuance.
+func (s *clientService) ClearDeprecationByUUID(ctx context.Context, ClientUUID uuid.UUID, tenantID int64, actorUserUUID uuid.UUID) (*ClientServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "client.clearDeprecation")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("client.uuid", ClientUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	var updatedClient *model.Client
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		Client, err := s.findMutableClient(tx, ClientUUID, actorUserUUID)
+		if err != nil {
+			return err
+		}
+
+		clearDeprecation(&Client.Deprecation)
+
+		if _, err := s.clientRepo.WithTx(tx).CreateOrUpdate(Client); err != nil {
+			return err
+		}
+
+		if err := recordEvent(s.eventRepo.WithTx(tx), Client.TenantID, ClientUpdated(newClientEventPayload(Client))); err != nil {
+			return err
+		}
+
+		updatedClient = Client
+
+		return nil
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to clear client deprecation")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return ToClientServiceDataResult(updatedClient), nil
+}
+
+// RotateSecretByUUID issues an auth client a new secret, returned once in the
+// result. For gracePeriod the previous secret keeps authenticating alongside
+// the new one so deployed applications can switch over without downtime. A
+// zero grace period revokes the previous secret immediately. Public clients
+// have no secret and system clients cannot be rotated.
+func (s *clientService) RotateSecretByUUID(ctx context.Context, ClientUUID uuid.UUID, tenantID int64, gracePeriod time.Duration, actorUserUUID uuid.UUID) (*ClientSecretRotationServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "client.rotateSecret")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("client.uuid", ClientUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+		attribute.Int64("client.secret_grace_seconds", int64(gracePeriod/time.Second)),
+	)
+
+	var result *ClientSecretRotationServiceDataResult
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		Client, err := s.findAccessibleClient(tx, ClientUUID, actorUserUUID)
+		if err != nil {
+			return err
+		}
+
+		if Client.IsSystem {
+			return apperror.NewValidation("system auth client secret cannot be rotated")
+		}
+		if Client.TokenEndpointAuthMethod == model.TokenAuthMethodNone {
+			return apperror.NewValidation("public auth clients do not use a secret")
+		}
+
+		secret, err := crypto.GenerateIdentifier(64)
+		if err != nil {
+			return apperror.NewInternal("failed to generate client secret", err)
+		}
+
+		now := time.Now()
+		Client.PreviousSecret = nil
+		Client.PreviousSecretExpiresAt = nil
+		if gracePeriod > 0 && Client.Secret != nil && *Client.Secret != "" {
+			expiresAt := now.Add(gracePeriod)
+			Client.PreviousSecret = Client.Secret
+			Client.PreviousSecretExpiresAt = &expiresAt
+		}
+		Client.Secret = &secret
+		Client.SecretRotatedAt = &now
+
+		if _, err := s.clientRepo.WithTx(tx).CreateOrUpdate(Client); err != nil {
+			return err
+		}
+
+		if err := recordEvent(s.eventRepo.WithTx(tx), Client.TenantID, ClientSecretRotated{
+			ClientUUID:              Client.ClientUUID,
+			RotatedAt:               now,
+			PreviousSecretExpiresAt: Client.PreviousSecretExpiresAt,
+		}); err != nil {
+			return err
+		}
+
+		result = &ClientSecretRotationServiceDataResult{
+			ClientID:                ptr.Deref(Client.Identifier),
+			ClientSecret:            secret,
+			SecretRotatedAt:         now,
+			PreviousSecretExpiresAt: Client.PreviousSecretExpiresAt,
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to rotate client secret")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// findAccessibleClient loads an auth client that belongs to a tenant the
+// actor has access to.
+func (s *clientService) findAccessibleClient(tx *gorm.DB, ClientUUID uuid.UUID, actorUserUUID uuid.UUID) (*model.Client, error) {
+	Client, err := s.clientRepo.WithTx(tx).FindByUUID(ClientUUID, "IdentityProvider.Tenant", "ClientURIs")
+	if err != nil || Client == nil {
+		return nil, apperror.NewNotFoundWithReason("auth client not found")
+	}
+
+	actorUser, err := s.userRepo.WithTx(tx).FindByUUID(actorUserUUID, "UserIdentities.Tenant")
+	if err != nil || actorUser == nil {
+		return nil, apperror.NewNotFoundWithReason("actor user not found")
+	}
+
+	if err := ValidateTenantAccess(actorUser, Client.IdentityProvider.Tenant); err != nil {
+		return nil, err
+	}
+
+	return Client, nil
+}
+
+// findMutableClient loads an auth client the actor is allowed to modify,
+// rejecting default and system clients.
+func (s *clientService) findMutableClient(tx *gorm.DB, ClientUUID uuid.UUID, actorUserUUID uuid.UUID) (*model.Client, error) {
+	Client, err := s.findAccessibleClient(tx, ClientUUID, actorUserUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if Client.IsDefault {
+		return nil, apperror.NewValidation("default auth client cannot be updated")
+	}
+	if Client.IsSystem {
+		return nil, apperror.NewValidation("system auth client cannot be updated")
+	}
+
+	return Client, nil
+}
+
 // Response builder - made public for use in other services
 func ToClientServiceDataResult(Client *model.Client) *ClientServiceDataResult {
 	if Client == nil {
@@ -785,6 +1270,7 @@ func ToClientServiceDataResult(Client *model.Client) *ClientServiceDataResult {
 		Status:      Client.Status,
 		IsDefault:   Client.IsDefault,
 		IsSystem:    Client.IsSystem,
+		Deprecation: toDeprecationServiceDataResult(Client.Deprecation),
 		CreatedAt:   Client.CreatedAt,
 		UpdatedAt:   Client.UpdatedAt,
 	}
@@ -832,6 +1318,18 @@ func (s *clientService) GetClientAPIs(ctx context.Context, tenantID int64, Clien
 		attribute.Int64("tenant.id", tenantID),
 	)
 
+	// Make sure the client belongs to the tenant
+	Client, err := s.clientRepo.FindByUUIDAndTenantID(ClientUUID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to fetch client")
+		return nil, err
+	}
+	if Client == nil {
+		span.SetStatus(codes.Error, "client not found")
+		return nil, apperror.NewNotFoundWithReason("auth client not found")
+	}
+
 	// Get auth client APIs from repository
 	ClientAPIs, err := s.clientAPIRepo.FindByClientUUID(ClientUUID)
 	if err != nil {
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/datatypes"
+)
+
+// ConfigService reloads the runtime-reloadable configuration of this
+// instance. Every reload is recorded as an auth event naming the admin.
+type ConfigService interface {
+	// Reload reads the config file again and returns the names of the
+	// settings that changed. Setting values are never returned or logged.
+	Reload(ctx context.Context, tenantID int64, actor *model.User) ([]string, error)
+}
+
+type configService struct {
+	reload           func() ([]string, error)
+	authEventService AuthEventService
+}
+
+// NewConfigService creates a new ConfigService. reload is normally
+// config.Reload.
+func NewConfigService(reload func() ([]string, error), authEventService AuthEventService) ConfigService {
+	return &configService{
+		reload:           reload,
+		authEventService: authEventService,
+	}
+}
+
+func (s *configService) Reload(ctx context.Context, tenantID int64, actor *model.User) ([]string, error) {
+	_, span := otel.Tracer("service").Start(ctx, "config.reload")
+	defer span.End()
+
+	changed, err := s.reload()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "reload failed")
+		return nil, apperror.NewValidation(err.Error())
+	}
+	span.SetAttributes(attribute.StringSlice("config.changed", changed))
+
+	description := fmt.Sprintf("%s reloaded the configuration; nothing changed", actor.Username)
+	if len(changed) > 0 {
+		description = fmt.Sprintf("%s reloaded the configuration, changing %s", actor.Username, strings.Join(changed, ", "))
+	}
+	raw, _ := json.Marshal(map[string]any{"changed": changed})
+	s.authEventService.Log(ctx, AuthEventInput{
+		TenantID:    tenantID,
+		ActorUserID: &actor.UserID,
+		IPAddress:   middleware.ClientIPFromContext(ctx),
+		UserAgent:   ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:    model.AuthEventCategorySystem,
+		EventType:   model.AuthEventTypeSystemConfigReload,
+		Severity:    model.AuthEventSeverityWarn,
+		Result:      model.AuthEventResultSuccess,
+		Description: ptr.Ptr(description),
+		Metadata:    datatypes.JSON(raw),
+	})
+
+	span.SetStatus(codes.Ok, "")
+	return changed, nil
+}
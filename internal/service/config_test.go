@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigService_Reload(t *testing.T) {
+	ctx := context.Background()
+	actor := &model.User{UserID: 7, UserUUID: uuid.New(), Username: "root"}
+
+	t.Run("reports changes and audits", func(t *testing.T) {
+		var events []AuthEventInput
+		svc := NewConfigService(
+			func() ([]string, error) { return []string{"SMTP_HOST", "SMTP_PASS"}, nil },
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { events = append(events, in) }},
+		)
+
+		changed, err := svc.Reload(ctx, 1, actor)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"SMTP_HOST", "SMTP_PASS"}, changed)
+
+		require.Len(t, events, 1)
+		assert.Equal(t, model.AuthEventTypeSystemConfigReload, events[0].EventType)
+		assert.Equal(t, int64(7), *events[0].ActorUserID)
+		assert.Contains(t, *events[0].Description, "changing SMTP_HOST, SMTP_PASS")
+		var metadata map[string][]string
+		require.NoError(t, json.Unmarshal(events[0].Metadata, &metadata))
+		assert.Equal(t, []string{"SMTP_HOST", "SMTP_PASS"}, metadata["changed"])
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		var events []AuthEventInput
+		svc := NewConfigService(
+			func() ([]string, error) { return nil, errors.New("invalid SMTP_PORT") },
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { events = append(events, in) }},
+		)
+
+		_, err := svc.Reload(ctx, 1, actor)
+		var validation *apperror.ValidationError
+		assert.ErrorAs(t, err, &validation)
+		assert.Empty(t, events)
+	})
+}
@@ -0,0 +1,454 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+// defaultReencryptBatchSize is how many values of a column are re-encrypted
+// per query when DataKeyConfig.BatchSize is not set.
+const defaultReencryptBatchSize = 500
+
+// dataKeyFetchTimeout bounds the lookup of a data key that is not loaded yet.
+const dataKeyFetchTimeout = 5 * time.Second
+
+// encryptedColumns lists every column tagged with the encrypted serializer.
+// Reencrypt walks these, so a newly tagged column must be added here.
+var encryptedColumns = []repository.EncryptedColumn{
+	{Table: "clients", IDColumn: "client_id", Column: "secret"},
+	{Table: "clients", IDColumn: "client_id", Column: "previous_secret"},
+	{Table: "email_config", IDColumn: "email_config_id", Column: "password_encrypted"},
+	{Table: "sms_config", IDColumn: "sms_config_id", Column: "auth_token_encrypted"},
+	{Table: "webhook_endpoints", IDColumn: "webhook_endpoint_id", Column: "secret_encrypted"},
+	{Table: "webhook_endpoints", IDColumn: "webhook_endpoint_id", Column: "previous_secret_encrypted"},
+	{Table: "login_hooks", IDColumn: "login_hook_id", Column: "secret_encrypted"},
+}
+
+// DataKeyConfig controls how column encryption data keys are stored and
+// rotated. Wrapper wraps the stored keys with the key-encryption key. The
+// active key is rotated once it is RotationInterval old, or only on demand
+// when RotationInterval is 0. BatchSize is how many values Reencrypt reads
+// at a time.
+type DataKeyConfig struct {
+	Wrapper          crypto.KeyWrapper
+	RotationInterval time.Duration
+	BatchSize        int
+}
+
+// DataKeyService stores the data keys sensitive columns are encrypted with,
+// rotates them and re-encrypts stored values with the active key.
+type DataKeyService interface {
+	// Keyring returns the keyring Load installs the keys in. Install it
+	// with crypto.SetColumnKeyring to encrypt columns.
+	Keyring() *crypto.Keyring
+
+	// Load unwraps the stored keys and installs them in the keyring. When
+	// no key is stored yet, a first active key is generated.
+	Load(ctx context.Context) error
+
+	// List returns every key, newest first.
+	List(ctx context.Context) ([]dto.DataKeyResponseDTO, error)
+
+	// Rotate generates a new active key. Values sealed with the key it
+	// replaces stay readable and are re-encrypted by Reencrypt.
+	Rotate(ctx context.Context) (*dto.DataKeyResponseDTO, error)
+
+	// Rewrap wraps every key again with the current key-encryption key, so
+	// a previous key-encryption key can be dropped.
+	Rewrap(ctx context.Context) (*dto.DataKeyRewrapResponseDTO, error)
+
+	// Reencrypt seals every stored value that is in plaintext or sealed
+	// with a previous key with the active key.
+	Reencrypt(ctx context.Context) (*dto.DataKeyReencryptResponseDTO, error)
+
+	// RotateDue rotates the active key when it is due, reloads the keys and
+	// re-encrypts stale values. It returns how many keys were rotated and
+	// values re-encrypted.
+	RotateDue(ctx context.Context) (int64, error)
+}
+
+type dataKeyService struct {
+	db          *gorm.DB
+	dataKeyRepo repository.DataKeyRepository
+	config      DataKeyConfig
+	keyring     *crypto.Keyring
+}
+
+// NewDataKeyService creates a new DataKeyService.
+func NewDataKeyService(
+	db *gorm.DB,
+	dataKeyRepo repository.DataKeyRepository,
+	config DataKeyConfig,
+) DataKeyService {
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultReencryptBatchSize
+	}
+	s := &dataKeyService{
+		db:          db,
+		dataKeyRepo: dataKeyRepo,
+		config:      config,
+	}
+	s.keyring = crypto.NewKeyring(s.fetchKey)
+	return s
+}
+
+// Keyring implements DataKeyService.
+func (s *dataKeyService) Keyring() *crypto.Keyring {
+	return s.keyring
+}
+
+// fetchKey unwraps a key that is not loaded yet, like one rotated to by
+// another instance.
+func (s *dataKeyService) fetchKey(keyID string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dataKeyFetchTimeout)
+	defer cancel()
+
+	key, err := s.dataKeyRepo.FindByKeyID(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, errors.New("data key not found")
+	}
+	return s.config.Wrapper.UnwrapKey(ctx, key.WrappedKey)
+}
+
+// Load implements DataKeyService.
+func (s *dataKeyService) Load(ctx context.Context) error {
+	ctx, span := otel.Tracer("service").Start(ctx, "data_key.load")
+	defer span.End()
+
+	keys, err := s.dataKeyRepo.FindAllOrdered()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "data keys lookup failed")
+		return apperror.NewInternal("failed to load data keys", err)
+	}
+	if len(keys) == 0 {
+		if keys, err = s.storeInitialKey(ctx); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "initial data key not stored")
+			return err
+		}
+	}
+
+	var active string
+	unwrapped := make(map[string][]byte, len(keys))
+	for i := range keys {
+		key, err := s.config.Wrapper.UnwrapKey(ctx, keys[i].WrappedKey)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "data key not unwrapped")
+			return apperror.NewInternal(fmt.Sprintf("failed to unwrap data key %s", keys[i].KeyID), err)
+		}
+		unwrapped[keys[i].KeyID] = key
+		if keys[i].Status == model.DataKeyStatusActive {
+			active = keys[i].KeyID
+		}
+	}
+	if active == "" {
+		span.SetStatus(codes.Error, "no active data key")
+		return apperror.NewInternal("no active data key", nil)
+	}
+	if err := s.keyring.SetKeys(active, unwrapped); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "data keys not installed")
+		return apperror.NewInternal("failed to install data keys", err)
+	}
+
+	span.SetAttributes(
+		attribute.String("data_key.key_id", active),
+		attribute.Int("data_key.count", len(keys)),
+	)
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// storeInitialKey stores a new key as the active key. When another instance
+// stored one first, the stored keys are returned instead.
+func (s *dataKeyService) storeInitialKey(ctx context.Context) ([]model.DataKey, error) {
+	key, err := s.newDataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, createErr := s.dataKeyRepo.Create(key); createErr != nil {
+		keys, err := s.dataKeyRepo.FindAllOrdered()
+		if err != nil || len(keys) == 0 {
+			return nil, apperror.NewInternal("failed to store initial data key", createErr)
+		}
+		return keys, nil
+	}
+	return []model.DataKey{*key}, nil
+}
+
+// List implements DataKeyService.
+func (s *dataKeyService) List(ctx context.Context) ([]dto.DataKeyResponseDTO, error) {
+	_, span := otel.Tracer("service").Start(ctx, "data_key.list")
+	defer span.End()
+
+	keys, err := s.dataKeyRepo.FindAllOrdered()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "data keys lookup failed")
+		return nil, apperror.NewInternal("failed to retrieve data keys", err)
+	}
+
+	result := make([]dto.DataKeyResponseDTO, len(keys))
+	for i := range keys {
+		result[i] = toDataKeyResponseDTO(&keys[i])
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// Rotate implements DataKeyService.
+func (s *dataKeyService) Rotate(ctx context.Context) (*dto.DataKeyResponseDTO, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "data_key.rotate")
+	defer span.End()
+
+	created, err := s.rotate(ctx, func(*model.DataKey) bool { return true })
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "rotate data key failed")
+		return nil, err
+	}
+	if err := s.Load(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "reload data keys failed")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("data_key.key_id", created.KeyID))
+	span.SetStatus(codes.Ok, "")
+	result := toDataKeyResponseDTO(created)
+	return &result, nil
+}
+
+// rotate replaces the active key with a new one when due reports that the
+// locked active key is due for rotation. It returns nil when it is not.
+func (s *dataKeyService) rotate(ctx context.Context, due func(active *model.DataKey) bool) (*model.DataKey, error) {
+	key, err := s.newDataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var created *model.DataKey
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		txDataKeyRepo := s.dataKeyRepo.WithTx(tx)
+
+		active, err := txDataKeyRepo.FindActiveForUpdate()
+		if err != nil {
+			return apperror.NewInternal("failed to find active data key", err)
+		}
+		if !due(active) {
+			return nil
+		}
+		if active != nil {
+			if _, err := txDataKeyRepo.UpdateByID(active.DataKeyID, map[string]any{
+				"status":     model.DataKeyStatusPrevious,
+				"rotated_at": key.ActivatedAt,
+			}); err != nil {
+				return apperror.NewInternal("failed to rotate data key", err)
+			}
+		}
+		if created, err = txDataKeyRepo.Create(key); err != nil {
+			return apperror.NewInternal("failed to store data key", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Rewrap implements DataKeyService.
+func (s *dataKeyService) Rewrap(ctx context.Context) (*dto.DataKeyRewrapResponseDTO, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "data_key.rewrap")
+	defer span.End()
+
+	keys, err := s.dataKeyRepo.FindAllOrdered()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "data keys lookup failed")
+		return nil, apperror.NewInternal("failed to retrieve data keys", err)
+	}
+
+	var count int64
+	for i := range keys {
+		key, err := s.config.Wrapper.UnwrapKey(ctx, keys[i].WrappedKey)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "data key not unwrapped")
+			return nil, apperror.NewInternal(fmt.Sprintf("failed to unwrap data key %s", keys[i].KeyID), err)
+		}
+		wrapped, err := s.config.Wrapper.WrapKey(ctx, key)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "data key not wrapped")
+			return nil, apperror.NewInternal(fmt.Sprintf("failed to wrap data key %s", keys[i].KeyID), err)
+		}
+		if _, err := s.dataKeyRepo.UpdateByID(keys[i].DataKeyID, map[string]any{"wrapped_key": wrapped}); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "rewrap data key failed")
+			return nil, apperror.NewInternal(fmt.Sprintf("failed to store data key %s", keys[i].KeyID), err)
+		}
+		count++
+	}
+
+	span.SetAttributes(attribute.Int64("data_key.rewrapped", count))
+	span.SetStatus(codes.Ok, "")
+	return &dto.DataKeyRewrapResponseDTO{Rewrapped: count}, nil
+}
+
+// Reencrypt implements DataKeyService.
+func (s *dataKeyService) Reencrypt(ctx context.Context) (*dto.DataKeyReencryptResponseDTO, error) {
+	_, span := otel.Tracer("service").Start(ctx, "data_key.reencrypt")
+	defer span.End()
+
+	active := s.keyring.ActiveKeyID()
+	if active == "" {
+		span.SetStatus(codes.Error, "no active data key")
+		return nil, apperror.NewInternal("no active data key - call Load() first", nil)
+	}
+
+	result := &dto.DataKeyReencryptResponseDTO{ActiveKeyID: active, Columns: map[string]int64{}}
+	for _, col := range encryptedColumns {
+		count, err := s.reencryptColumn(col, active)
+		result.Reencrypted += count
+		if count > 0 {
+			result.Columns[crypto.ColumnName(col.Table, col.Column)] = count
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "reencrypt column failed")
+			return nil, err
+		}
+	}
+
+	span.SetAttributes(attribute.Int64("data_key.reencrypted", result.Reencrypted))
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// reencryptColumn seals the values of the column that are not sealed with
+// the active key, a batch at a time. Values changed since they were read
+// are skipped; they were written with the active key.
+func (s *dataKeyService) reencryptColumn(col repository.EncryptedColumn, active string) (int64, error) {
+	field := crypto.ColumnName(col.Table, col.Column)
+	prefix := crypto.EnvelopePrefix(active)
+
+	var count, afterID int64
+	for {
+		values, err := s.dataKeyRepo.FindValuesNotSealedWith(col, prefix, afterID, s.config.BatchSize)
+		if err != nil {
+			return count, apperror.NewInternal(fmt.Sprintf("failed to read %s", field), err)
+		}
+		for _, v := range values {
+			afterID = v.ID
+			plaintext, err := s.keyring.Decrypt(field, v.Value)
+			if err != nil {
+				return count, apperror.NewInternal(fmt.Sprintf("failed to decrypt %s of row %d", field, v.ID), err)
+			}
+			sealed, err := s.keyring.Encrypt(field, plaintext)
+			if err != nil {
+				return count, apperror.NewInternal(fmt.Sprintf("failed to encrypt %s of row %d", field, v.ID), err)
+			}
+			replaced, err := s.dataKeyRepo.ReplaceValue(col, v.ID, v.Value, sealed)
+			if err != nil {
+				return count, apperror.NewInternal(fmt.Sprintf("failed to update %s of row %d", field, v.ID), err)
+			}
+			if replaced {
+				count++
+			}
+		}
+		if len(values) < s.config.BatchSize {
+			return count, nil
+		}
+	}
+}
+
+// RotateDue implements DataKeyService.
+func (s *dataKeyService) RotateDue(ctx context.Context) (int64, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "data_key.rotate_due")
+	defer span.End()
+
+	var count int64
+	if s.config.RotationInterval > 0 {
+		created, err := s.rotate(ctx, func(active *model.DataKey) bool {
+			return active == nil || time.Since(active.ActivatedAt) >= s.config.RotationInterval
+		})
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "rotate data key failed")
+			return 0, err
+		}
+		if created != nil {
+			count++
+		}
+	}
+
+	// Reload even when nothing changed here, so that keys rotated by other
+	// instances are picked up.
+	if err := s.Load(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "reload data keys failed")
+		return count, err
+	}
+
+	result, err := s.Reencrypt(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "reencrypt failed")
+		return count, err
+	}
+	count += result.Reencrypted
+
+	span.SetAttributes(attribute.Int64("data_key.changed", count))
+	span.SetStatus(codes.Ok, "")
+	return count, nil
+}
+
+// newDataKey generates an active key and wraps it for storage.
+func (s *dataKeyService) newDataKey(ctx context.Context) (*model.DataKey, error) {
+	key, err := crypto.GenerateDataKey()
+	if err != nil {
+		return nil, apperror.NewInternal("failed to generate data key", err)
+	}
+	wrapped, err := s.config.Wrapper.WrapKey(ctx, key)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to wrap data key", err)
+	}
+	return &model.DataKey{
+		KeyID:       uuid.NewString(),
+		WrappedKey:  wrapped,
+		Status:      model.DataKeyStatusActive,
+		ActivatedAt: time.Now(),
+	}, nil
+}
+
+// toDataKeyResponseDTO describes a key without its key material.
+func toDataKeyResponseDTO(key *model.DataKey) dto.DataKeyResponseDTO {
+	return dto.DataKeyResponseDTO{
+		DataKeyID:   key.DataKeyUUID.String(),
+		KeyID:       key.KeyID,
+		Status:      key.Status,
+		ActivatedAt: key.ActivatedAt,
+		RotatedAt:   key.RotatedAt,
+		CreatedAt:   key.CreatedAt,
+	}
+}
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/schema"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+)
+
+func newTestKeyWrapper(t *testing.T, kek byte, previous ...byte) crypto.KeyWrapper {
+	t.Helper()
+	var prev [][]byte
+	for _, b := range previous {
+		prev = append(prev, bytes.Repeat([]byte{b}, crypto.FieldKeySize))
+	}
+	w, err := crypto.NewLocalKeyWrapper(bytes.Repeat([]byte{kek}, crypto.FieldKeySize), prev...)
+	require.NoError(t, err)
+	return w
+}
+
+// newDataKeyTestService returns a service over an empty in-memory key store
+// that wraps keys with a local KEK.
+func newDataKeyTestService(t *testing.T, config DataKeyConfig) (DataKeyService, *mockDataKeyRepo, sqlmock.Sqlmock) {
+	t.Helper()
+	if config.Wrapper == nil {
+		config.Wrapper = newTestKeyWrapper(t, 1)
+	}
+	gormDB, mock := newMockGormDB(t)
+	repo := &mockDataKeyRepo{values: map[string]map[int64]string{}}
+	return NewDataKeyService(gormDB, repo, config), repo, mock
+}
+
+func TestDataKeyService_Load_StoresInitialKey(t *testing.T) {
+	svc, repo, _ := newDataKeyTestService(t, DataKeyConfig{})
+
+	require.NoError(t, svc.Load(context.Background()))
+
+	require.Len(t, repo.keys, 1)
+	stored := repo.keys[0]
+	assert.Equal(t, model.DataKeyStatusActive, stored.Status)
+	assert.NotEmpty(t, stored.WrappedKey)
+	assert.Equal(t, stored.KeyID, svc.Keyring().ActiveKeyID())
+
+	// A second load reuses the stored key
+	require.NoError(t, svc.Load(context.Background()))
+	assert.Len(t, repo.keys, 1)
+}
+
+func TestDataKeyService_Load_Errors(t *testing.T) {
+	t.Run("repo error", func(t *testing.T) {
+		svc, repo, _ := newDataKeyTestService(t, DataKeyConfig{})
+		repo.err = errors.New("db error")
+
+		var target *apperror.InternalError
+		require.ErrorAs(t, svc.Load(context.Background()), &target)
+	})
+
+	t.Run("wrong key-encryption key", func(t *testing.T) {
+		svc, repo, _ := newDataKeyTestService(t, DataKeyConfig{})
+		require.NoError(t, svc.Load(context.Background()))
+
+		other := NewDataKeyService(nil, repo, DataKeyConfig{Wrapper: newTestKeyWrapper(t, 2)})
+		err := other.Load(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to unwrap data key")
+	})
+
+	t.Run("no active key", func(t *testing.T) {
+		svc, repo, _ := newDataKeyTestService(t, DataKeyConfig{})
+		require.NoError(t, svc.Load(context.Background()))
+		repo.keys[0].Status = model.DataKeyStatusPrevious
+
+		err := svc.Load(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no active data key")
+	})
+}
+
+func TestDataKeyService_Rotate(t *testing.T) {
+	svc, repo, mock := newDataKeyTestService(t, DataKeyConfig{})
+	require.NoError(t, svc.Load(context.Background()))
+	oldKeyID := svc.Keyring().ActiveKeyID()
+	old, err := svc.Keyring().Encrypt("clients.secret", "s3cret")
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	rotated, err := svc.Rotate(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, model.DataKeyStatusActive, rotated.Status)
+	assert.NotEqual(t, oldKeyID, rotated.KeyID)
+	assert.Equal(t, rotated.KeyID, svc.Keyring().ActiveKeyID())
+	assert.Equal(t, model.DataKeyStatusPrevious, repo.keys[0].Status)
+	assert.NotNil(t, repo.keys[0].RotatedAt)
+
+	// Values sealed before the rotation still decrypt
+	pt, err := svc.Keyring().Decrypt("clients.secret", old)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", pt)
+}
+
+func TestDataKeyService_FetchesKeysRotatedElsewhere(t *testing.T) {
+	svc, repo, mock := newDataKeyTestService(t, DataKeyConfig{})
+	require.NoError(t, svc.Load(context.Background()))
+
+	other := NewDataKeyService(svc.(*dataKeyService).db, repo, DataKeyConfig{Wrapper: newTestKeyWrapper(t, 1)})
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	_, err := other.Rotate(context.Background())
+	require.NoError(t, err)
+	sealed, err := other.Keyring().Encrypt("clients.secret", "s3cret")
+	require.NoError(t, err)
+
+	pt, err := svc.Keyring().Decrypt("clients.secret", sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", pt)
+}
+
+func TestDataKeyService_Rewrap(t *testing.T) {
+	svc, repo, mock := newDataKeyTestService(t, DataKeyConfig{})
+	require.NoError(t, svc.Load(context.Background()))
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	_, err := svc.Rotate(context.Background())
+	require.NoError(t, err)
+
+	// The KEK is replaced; the old one still unwraps until the keys are rewrapped
+	rotated := NewDataKeyService(nil, repo, DataKeyConfig{Wrapper: newTestKeyWrapper(t, 2, 1)})
+	result, err := rotated.Rewrap(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.Rewrapped)
+
+	newOnly := NewDataKeyService(nil, repo, DataKeyConfig{Wrapper: newTestKeyWrapper(t, 2)})
+	require.NoError(t, newOnly.Load(context.Background()))
+	assert.Equal(t, svc.Keyring().ActiveKeyID(), newOnly.Keyring().ActiveKeyID())
+
+	t.Run("unwrap error", func(t *testing.T) {
+		wrong := NewDataKeyService(nil, repo, DataKeyConfig{Wrapper: newTestKeyWrapper(t, 3)})
+		var target *apperror.InternalError
+		_, err := wrong.Rewrap(context.Background())
+		require.ErrorAs(t, err, &target)
+	})
+}
+
+func TestDataKeyService_Reencrypt(t *testing.T) {
+	svc, repo, mock := newDataKeyTestService(t, DataKeyConfig{BatchSize: 2})
+	require.NoError(t, svc.Load(context.Background()))
+	keyring := svc.Keyring()
+	oldSealed, err := keyring.Encrypt("clients.secret", "old-key")
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	_, err = svc.Rotate(context.Background())
+	require.NoError(t, err)
+	current, err := keyring.Encrypt("clients.secret", "current")
+	require.NoError(t, err)
+
+	repo.values["clients.secret"] = map[int64]string{1: "plaintext", 2: oldSealed, 3: current, 4: "", 5: "another"}
+	repo.values["login_hooks.secret_encrypted"] = map[int64]string{7: "hook"}
+
+	result, err := svc.Reencrypt(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, keyring.ActiveKeyID(), result.ActiveKeyID)
+	assert.Equal(t, int64(4), result.Reencrypted)
+	assert.Equal(t, map[string]int64{"clients.secret": 3, "login_hooks.secret_encrypted": 1}, result.Columns)
+
+	prefix := crypto.EnvelopePrefix(keyring.ActiveKeyID())
+	want := map[int64]string{1: "plaintext", 2: "old-key", 3: "current", 5: "another"}
+	for id, plaintext := range want {
+		stored := repo.values["clients.secret"][id]
+		assert.True(t, strings.HasPrefix(stored, prefix), "row %d is sealed with the active key", id)
+		pt, err := keyring.Decrypt("clients.secret", stored)
+		require.NoError(t, err)
+		assert.Equal(t, plaintext, pt)
+	}
+	assert.Equal(t, current, repo.values["clients.secret"][3], "values sealed with the active key are left alone")
+	assert.Empty(t, repo.values["clients.secret"][4])
+
+	// Nothing is left to re-encrypt
+	result, err = svc.Reencrypt(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, result.Reencrypted)
+}
+
+func TestDataKeyService_Reencrypt_Errors(t *testing.T) {
+	t.Run("not loaded", func(t *testing.T) {
+		svc, _, _ := newDataKeyTestService(t, DataKeyConfig{})
+		var target *apperror.InternalError
+		_, err := svc.Reencrypt(context.Background())
+		require.ErrorAs(t, err, &target)
+	})
+
+	t.Run("undecryptable value", func(t *testing.T) {
+		svc, repo, _ := newDataKeyTestService(t, DataKeyConfig{})
+		require.NoError(t, svc.Load(context.Background()))
+		repo.values["clients.secret"] = map[int64]string{1: crypto.EnvelopePrefix("missing") + "AAAA"}
+
+		_, err := svc.Reencrypt(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "clients.secret of row 1")
+	})
+}
+
+func TestDataKeyService_RotateDue(t *testing.T) {
+	t.Run("re-encrypts without rotating", func(t *testing.T) {
+		svc, repo, _ := newDataKeyTestService(t, DataKeyConfig{})
+		repo.values["sms_config.auth_token_encrypted"] = map[int64]string{1: "token"}
+
+		count, err := svc.RotateDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		assert.Len(t, repo.keys, 1)
+	})
+
+	t.Run("rotates when due", func(t *testing.T) {
+		svc, repo, mock := newDataKeyTestService(t, DataKeyConfig{RotationInterval: time.Hour})
+		require.NoError(t, svc.Load(context.Background()))
+		repo.keys[0].ActivatedAt = time.Now().Add(-2 * time.Hour)
+		repo.values["sms_config.auth_token_encrypted"] = map[int64]string{1: "token"}
+
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		count, err := svc.RotateDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+		require.Len(t, repo.keys, 2)
+		assert.Equal(t, repo.keys[1].KeyID, svc.Keyring().ActiveKeyID())
+	})
+
+	t.Run("not due", func(t *testing.T) {
+		svc, repo, mock := newDataKeyTestService(t, DataKeyConfig{RotationInterval: time.Hour})
+		require.NoError(t, svc.Load(context.Background()))
+
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		count, err := svc.RotateDue(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		assert.Len(t, repo.keys, 1)
+	})
+}
+
+func TestDataKeyService_List(t *testing.T) {
+	svc, repo, mock := newDataKeyTestService(t, DataKeyConfig{})
+	require.NoError(t, svc.Load(context.Background()))
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	_, err := svc.Rotate(context.Background())
+	require.NoError(t, err)
+
+	keys, err := svc.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, repo.keys[1].KeyID, keys[0].KeyID)
+	assert.Equal(t, model.DataKeyStatusActive, keys[0].Status)
+	assert.Equal(t, model.DataKeyStatusPrevious, keys[1].Status)
+
+	repo.err = errors.New("db error")
+	_, err = svc.List(context.Background())
+	var target *apperror.InternalError
+	require.ErrorAs(t, err, &target)
+}
+
+// TestEncryptedColumns checks that Reencrypt walks exactly the columns the
+// models encrypt.
+func TestEncryptedColumns(t *testing.T) {
+	models := []any{
+		&model.Client{}, &model.EmailConfig{}, &model.SMSConfig{},
+		&model.WebhookEndpoint{}, &model.LoginHook{},
+	}
+
+	var tagged []repository.EncryptedColumn
+	for _, m := range models {
+		s, err := schema.Parse(m, &sync.Map{}, schema.NamingStrategy{})
+		require.NoError(t, err)
+		for _, field := range s.Fields {
+			if field.TagSettings["SERIALIZER"] == crypto.ColumnSerializer {
+				tagged = append(tagged, repository.EncryptedColumn{
+					Table:    s.Table,
+					IDColumn: s.PrioritizedPrimaryField.DBName,
+					Column:   field.DBName,
+				})
+			}
+		}
+	}
+	assert.ElementsMatch(t, tagged, encryptedColumns)
+}
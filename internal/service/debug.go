@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/logging"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/datatypes"
+)
+
+// BodyTraceInput selects what a body trace covers. Exactly one of TenantUUID
+// and ClientUUID is set.
+type BodyTraceInput struct {
+	TenantUUID *uuid.UUID
+	ClientUUID *uuid.UUID
+	Duration   time.Duration
+}
+
+// DebugService changes the runtime log levels and body tracing of this
+// instance. Every change is recorded as an auth event naming the admin.
+type DebugService interface {
+	GetLogLevels(ctx context.Context) []logging.ComponentLevel
+	SetLogLevel(ctx context.Context, tenantID int64, actor *model.User, component, level string) (*logging.ComponentLevel, error)
+	GetBodyTraces(ctx context.Context) []logging.TraceRule
+	EnableBodyTrace(ctx context.Context, tenantID int64, actor *model.User, in BodyTraceInput) (*logging.TraceRule, error)
+	DisableBodyTrace(ctx context.Context, tenantID int64, actor *model.User, traceUUID uuid.UUID) (*logging.TraceRule, error)
+}
+
+type debugService struct {
+	tenantRepo       repository.TenantRepository
+	clientRepo       repository.ClientRepository
+	authEventService AuthEventService
+}
+
+// NewDebugService creates a new DebugService.
+func NewDebugService(
+	tenantRepo repository.TenantRepository,
+	clientRepo repository.ClientRepository,
+	authEventService AuthEventService,
+) DebugService {
+	return &debugService{
+		tenantRepo:       tenantRepo,
+		clientRepo:       clientRepo,
+		authEventService: authEventService,
+	}
+}
+
+func (s *debugService) GetLogLevels(ctx context.Context) []logging.ComponentLevel {
+	_, span := otel.Tracer("service").Start(ctx, "debug.get_log_levels")
+	defer span.End()
+
+	return logging.Levels()
+}
+
+func (s *debugService) SetLogLevel(ctx context.Context, tenantID int64, actor *model.User, component, level string) (*logging.ComponentLevel, error) {
+	_, span := otel.Tracer("service").Start(ctx, "debug.set_log_level")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("log.component", component),
+		attribute.String("log.level", level),
+	)
+
+	parsed, err := logging.ParseLevel(level)
+	if err != nil {
+		span.SetStatus(codes.Error, "invalid level")
+		return nil, apperror.NewValidation(err.Error())
+	}
+	if err := logging.SetLevel(component, parsed, actor.UserUUID.String()); err != nil {
+		span.SetStatus(codes.Error, "invalid component")
+		return nil, apperror.NewValidation(err.Error())
+	}
+
+	s.audit(ctx, tenantID, actor,
+		fmt.Sprintf("%s set the %s log level to %s", actor.Username, component, parsed),
+		map[string]any{"component": component, "level": parsed.String()},
+	)
+
+	for _, l := range logging.Levels() {
+		if l.Component == component {
+			span.SetStatus(codes.Ok, "")
+			return &l, nil
+		}
+	}
+	return nil, apperror.NewNotFound("log component")
+}
+
+func (s *debugService) GetBodyTraces(ctx context.Context) []logging.TraceRule {
+	_, span := otel.Tracer("service").Start(ctx, "debug.get_body_traces")
+	defer span.End()
+
+	return logging.Traces()
+}
+
+func (s *debugService) EnableBodyTrace(ctx context.Context, tenantID int64, actor *model.User, in BodyTraceInput) (*logging.TraceRule, error) {
+	_, span := otel.Tracer("service").Start(ctx, "debug.enable_body_trace")
+	defer span.End()
+
+	if (in.TenantUUID == nil) == (in.ClientUUID == nil) {
+		span.SetStatus(codes.Error, "invalid target")
+		return nil, apperror.NewValidation("exactly one of tenant or client must be traced")
+	}
+
+	// Rules match on what requests carry: the tenant UUID, or the client
+	// identifier used as client_id
+	var target, value, label string
+	if in.TenantUUID != nil {
+		tenant, err := s.tenantRepo.FindByUUID(*in.TenantUUID)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "find tenant failed")
+			return nil, apperror.NewInternal("failed to find tenant", err)
+		}
+		if tenant == nil {
+			span.SetStatus(codes.Error, "tenant not found")
+			return nil, apperror.NewNotFound("tenant")
+		}
+		target, value, label = logging.TraceTargetTenant, tenant.TenantUUID.String(), tenant.Name
+	} else {
+		client, err := s.clientRepo.FindByUUID(*in.ClientUUID)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "find client failed")
+			return nil, apperror.NewInternal("failed to find client", err)
+		}
+		if client == nil || client.Identifier == nil {
+			span.SetStatus(codes.Error, "client not found")
+			return nil, apperror.NewNotFound("client")
+		}
+		target, value, label = logging.TraceTargetClient, *client.Identifier, client.Name
+	}
+	span.SetAttributes(attribute.String("trace.target", target))
+
+	rule, err := logging.AddTrace(target, value, in.Duration, actor.UserUUID.String())
+	if err != nil {
+		span.SetStatus(codes.Error, "invalid trace")
+		return nil, apperror.NewValidation(err.Error())
+	}
+
+	s.audit(ctx, tenantID, actor,
+		fmt.Sprintf("%s enabled body tracing for %s %s until %s", actor.Username, target, label, rule.ExpiresAt.Format(time.RFC3339)),
+		map[string]any{
+			"action":     "enable_body_trace",
+			"trace_uuid": rule.TraceUUID,
+			"target":     target,
+			"value":      value,
+			"expires_at": rule.ExpiresAt,
+		},
+	)
+
+	span.SetStatus(codes.Ok, "")
+	return &rule, nil
+}
+
+func (s *debugService) DisableBodyTrace(ctx context.Context, tenantID int64, actor *model.User, traceUUID uuid.UUID) (*logging.TraceRule, error) {
+	_, span := otel.Tracer("service").Start(ctx, "debug.disable_body_trace")
+	defer span.End()
+	span.SetAttributes(attribute.String("trace.uuid", traceUUID.String()))
+
+	rule, ok := logging.RemoveTrace(traceUUID)
+	if !ok {
+		span.SetStatus(codes.Error, "trace not found")
+		return nil, apperror.NewNotFound("body trace")
+	}
+
+	s.audit(ctx, tenantID, actor,
+		fmt.Sprintf("%s disabled body tracing for %s %s", actor.Username, rule.Target, rule.Value),
+		map[string]any{
+			"action":     "disable_body_trace",
+			"trace_uuid": rule.TraceUUID,
+			"target":     rule.Target,
+			"value":      rule.Value,
+		},
+	)
+
+	span.SetStatus(codes.Ok, "")
+	return &rule, nil
+}
+
+// audit records a debug mode change made by actor.
+func (s *debugService) audit(ctx context.Context, tenantID int64, actor *model.User, description string, metadata map[string]any) {
+	raw, _ := json.Marshal(metadata)
+	s.authEventService.Log(ctx, AuthEventInput{
+		TenantID:    tenantID,
+		ActorUserID: &actor.UserID,
+		IPAddress:   middleware.ClientIPFromContext(ctx),
+		UserAgent:   ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:    model.AuthEventCategorySystem,
+		EventType:   model.AuthEventTypeSystemDebug,
+		Severity:    model.AuthEventSeverityWarn,
+		Result:      model.AuthEventResultSuccess,
+		Description: ptr.Ptr(description),
+		Metadata:    datatypes.JSON(raw),
+	})
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/logging"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDebugTenant returns a Tenant fixture for the debug service tests.
+func newDebugTenant() *model.Tenant {
+	return &model.Tenant{TenantID: 3, TenantUUID: uuid.New(), Name: "acme"}
+}
+
+// newDebugClient returns a Client fixture for the debug service tests.
+func newDebugClient() *model.Client {
+	return &model.Client{ClientID: 4, ClientUUID: uuid.New(), Name: "web", Identifier: ptr.Ptr("acme-web")}
+}
+
+// newDebugService returns a DebugService that finds tenant and client and
+// records its audit events in logged.
+func newDebugService(tenant *model.Tenant, client *model.Client, logged *[]AuthEventInput) DebugService {
+	tenantRepo := &mockTenantRepo{findByUUIDFn: func(id any, _ ...string) (*model.Tenant, error) {
+		if id == tenant.TenantUUID {
+			return tenant, nil
+		}
+		return nil, nil
+	}}
+	clientRepo := &mockClientRepo{findByUUIDFn: func(id any, _ ...string) (*model.Client, error) {
+		if id == client.ClientUUID {
+			return client, nil
+		}
+		return nil, nil
+	}}
+	authEvents := &mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) {
+		*logged = append(*logged, in)
+	}}
+	return NewDebugService(tenantRepo, clientRepo, authEvents)
+}
+
+func TestDebugService_SetLogLevel(t *testing.T) {
+	t.Cleanup(func() { _ = logging.SetLevel(logging.ComponentDB, logging.DefaultLevel, "") })
+	ctx := context.Background()
+	actor := &model.User{UserID: 7, UserUUID: uuid.New(), Username: "root"}
+
+	t.Run("changes level and audits", func(t *testing.T) {
+		var logged []AuthEventInput
+		got, err := newDebugService(newDebugTenant(), newDebugClient(), &logged).SetLogLevel(ctx, 1, actor, logging.ComponentDB, "debug")
+		require.NoError(t, err)
+		assert.Equal(t, slog.LevelDebug, got.Level)
+		assert.Equal(t, actor.UserUUID.String(), got.ChangedBy)
+		assert.True(t, logging.Logger(logging.ComponentDB).Enabled(ctx, slog.LevelDebug))
+
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeSystemDebug, logged[0].EventType)
+		assert.Equal(t, int64(1), logged[0].TenantID)
+		assert.Equal(t, &actor.UserID, logged[0].ActorUserID)
+	})
+
+	for name, args := range map[string][2]string{
+		"unknown component": {"cache", "debug"},
+		"unknown level":     {logging.ComponentDB, "verbose"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var logged []AuthEventInput
+			_, err := newDebugService(newDebugTenant(), newDebugClient(), &logged).SetLogLevel(ctx, 1, actor, args[0], args[1])
+			var validation *apperror.ValidationError
+			require.ErrorAs(t, err, &validation)
+			assert.Empty(t, logged)
+		})
+	}
+}
+
+func TestDebugService_BodyTrace(t *testing.T) {
+	ctx := context.Background()
+	actor := &model.User{UserID: 7, UserUUID: uuid.New(), Username: "root"}
+
+	t.Run("tenant", func(t *testing.T) {
+		tenant := newDebugTenant()
+		var logged []AuthEventInput
+		svc := newDebugService(tenant, newDebugClient(), &logged)
+		rule, err := svc.EnableBodyTrace(ctx, 1, actor, BodyTraceInput{TenantUUID: &tenant.TenantUUID, Duration: 10 * time.Minute})
+		require.NoError(t, err)
+		t.Cleanup(func() { logging.RemoveTrace(rule.TraceUUID) })
+
+		assert.Equal(t, logging.TraceTargetTenant, rule.Target)
+		assert.Equal(t, tenant.TenantUUID.String(), rule.Value)
+		assert.Equal(t, actor.UserUUID.String(), rule.EnabledBy)
+		assert.Contains(t, svc.GetBodyTraces(ctx), *rule)
+
+		require.Len(t, logged, 1)
+		var meta map[string]any
+		require.NoError(t, json.Unmarshal(logged[0].Metadata, &meta))
+		assert.Equal(t, "enable_body_trace", meta["action"])
+
+		removed, err := svc.DisableBodyTrace(ctx, 1, actor, rule.TraceUUID)
+		require.NoError(t, err)
+		assert.Equal(t, rule.TraceUUID, removed.TraceUUID)
+		assert.NotContains(t, svc.GetBodyTraces(ctx), *rule)
+		assert.Len(t, logged, 2)
+	})
+
+	t.Run("client is matched by identifier", func(t *testing.T) {
+		client := newDebugClient()
+		var logged []AuthEventInput
+		rule, err := newDebugService(newDebugTenant(), client, &logged).EnableBodyTrace(ctx, 1, actor, BodyTraceInput{ClientUUID: &client.ClientUUID, Duration: time.Minute})
+		require.NoError(t, err)
+		t.Cleanup(func() { logging.RemoveTrace(rule.TraceUUID) })
+
+		_, ok := logging.MatchTrace("", "acme-web")
+		assert.True(t, ok)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		tenant, client := newDebugTenant(), newDebugClient()
+		var logged []AuthEventInput
+		svc := newDebugService(tenant, client, &logged)
+		for name, in := range map[string]BodyTraceInput{
+			"no target":    {Duration: time.Minute},
+			"both targets": {TenantUUID: &tenant.TenantUUID, ClientUUID: &client.ClientUUID, Duration: time.Minute},
+			"too long":     {TenantUUID: &tenant.TenantUUID, Duration: 2 * time.Hour},
+		} {
+			_, err := svc.EnableBodyTrace(ctx, 1, actor, in)
+			var validation *apperror.ValidationError
+			require.ErrorAs(t, err, &validation, name)
+		}
+
+		missing := uuid.New()
+		for name, in := range map[string]BodyTraceInput{
+			"unknown tenant": {TenantUUID: &missing, Duration: time.Minute},
+			"unknown client": {ClientUUID: &missing, Duration: time.Minute},
+		} {
+			_, err := svc.EnableBodyTrace(ctx, 1, actor, in)
+			var notFound *apperror.NotFoundError
+			require.ErrorAs(t, err, &notFound, name)
+		}
+		assert.Empty(t, logged)
+	})
+
+	t.Run("disable unknown trace", func(t *testing.T) {
+		var logged []AuthEventInput
+		_, err := newDebugService(newDebugTenant(), newDebugClient(), &logged).DisableBodyTrace(ctx, 1, actor, uuid.New())
+		var notFound *apperror.NotFoundError
+		require.ErrorAs(t, err, &notFound)
+	})
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+)
+
+// DeprecationServiceDataResult describes the deprecation lifecycle of a client
+// or API.
+type DeprecationServiceDataResult struct {
+	DeprecatedAt time.Time
+	SunsetAt     *time.Time
+	Notice       *string
+	UsageCount   int64
+	LastUsedAt   *time.Time
+}
+
+// toDeprecationServiceDataResult returns nil when the record is not deprecated.
+func toDeprecationServiceDataResult(d model.Deprecation) *DeprecationServiceDataResult {
+	if !d.IsDeprecated() {
+		return nil
+	}
+	return &DeprecationServiceDataResult{
+		DeprecatedAt: *d.DeprecatedAt,
+		SunsetAt:     d.SunsetAt,
+		Notice:       d.DeprecationNotice,
+		UsageCount:   d.DeprecatedUsageCount,
+		LastUsedAt:   d.LastDeprecatedUseAt,
+	}
+}
+
+// applyDeprecation marks d as deprecated with the given sunset date and notice.
+// The original deprecation date and usage counters are kept when an already
+// deprecated record is updated, so a sunset can be postponed without losing
+// history.
+func applyDeprecation(d *model.Deprecation, sunsetAt *time.Time, notice *string, now time.Time) error {
+	if sunsetAt != nil && !sunsetAt.After(now) {
+		return apperror.NewValidation("sunset date must be in the future")
+	}
+
+	if d.DeprecatedAt == nil {
+		d.DeprecatedAt = &now
+		d.DeprecatedUsageCount = 0
+		d.LastDeprecatedUseAt = nil
+	}
+	d.SunsetAt = sunsetAt
+	d.DeprecationNotice = notice
+
+	return nil
+}
+
+// clearDeprecation reverts d to an active, non-deprecated state.
+func clearDeprecation(d *model.Deprecation) {
+	*d = model.Deprecation{}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyDeprecation(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	future := now.Add(30 * 24 * time.Hour)
+	past := now.Add(-time.Hour)
+	notice := "use v2"
+
+	t.Run("sunset in the past is rejected", func(t *testing.T) {
+		var d model.Deprecation
+		err := applyDeprecation(&d, &past, nil, now)
+		var target *apperror.ValidationError
+		require.ErrorAs(t, err, &target)
+		assert.False(t, d.IsDeprecated())
+	})
+
+	t.Run("sunset equal to now is rejected", func(t *testing.T) {
+		var d model.Deprecation
+		assert.Error(t, applyDeprecation(&d, &now, nil, now))
+	})
+
+	t.Run("first deprecation sets the date and resets usage", func(t *testing.T) {
+		d := model.Deprecation{DeprecatedUsageCount: 9, LastDeprecatedUseAt: &past}
+		require.NoError(t, applyDeprecation(&d, &future, &notice, now))
+		require.NotNil(t, d.DeprecatedAt)
+		assert.Equal(t, now, *d.DeprecatedAt)
+		assert.Equal(t, &future, d.SunsetAt)
+		assert.Equal(t, &notice, d.DeprecationNotice)
+		assert.Zero(t, d.DeprecatedUsageCount)
+		assert.Nil(t, d.LastDeprecatedUseAt)
+	})
+
+	t.Run("updating keeps the original date and usage", func(t *testing.T) {
+		d := model.Deprecation{DeprecatedAt: &past, DeprecatedUsageCount: 4, LastDeprecatedUseAt: &past}
+		require.NoError(t, applyDeprecation(&d, nil, nil, now))
+		assert.Equal(t, &past, d.DeprecatedAt)
+		assert.Nil(t, d.SunsetAt)
+		assert.Equal(t, int64(4), d.DeprecatedUsageCount)
+	})
+}
+
+func TestClearDeprecation(t *testing.T) {
+	now := time.Now()
+	notice := "x"
+	d := model.Deprecation{DeprecatedAt: &now, SunsetAt: &now, DeprecationNotice: &notice, DeprecatedUsageCount: 2}
+	clearDeprecation(&d)
+	assert.Equal(t, model.Deprecation{}, d)
+}
+
+func TestToDeprecationServiceDataResult(t *testing.T) {
+	assert.Nil(t, toDeprecationServiceDataResult(model.Deprecation{}))
+
+	now := time.Now()
+	r := toDeprecationServiceDataResult(model.Deprecation{DeprecatedAt: &now, DeprecatedUsageCount: 7})
+	require.NotNil(t, r)
+	assert.Equal(t, now, r.DeprecatedAt)
+	assert.Equal(t, int64(7), r.UsageCount)
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+)
+
+// DomainEvent is a typed lifecycle event emitted by a service. Its JSON
+// encoding is the event payload; the type and aggregate are stored next to
+// it. Events are recorded with recordEvent and reach subscribers through the
+// event bus once the transaction that recorded them commits.
+type DomainEvent interface {
+	EventType() string
+	AggregateUUID() uuid.UUID
+}
+
+// User events.
+type (
+	UserCreated  userEventPayload
+	UserUpdated  userEventPayload
+	UserDeleted  userEventPayload
+	UserRestored userEventPayload
+	UserPurged   userPurgedEventPayload
+	// RoleAssigned is emitted when roles are added to a user, including the
+	// roles a new user starts with.
+	RoleAssigned   userRolesEventPayload
+	RoleUnassigned userRolesEventPayload
+	// ScopedRoleAssigned is emitted when a role is assigned to a user for a
+	// single client or group.
+	ScopedRoleAssigned   userScopedRoleEventPayload
+	ScopedRoleUnassigned userScopedRoleEventPayload
+)
+
+func (UserCreated) EventType() string           { return model.EventTypeUserCreated }
+func (e UserCreated) AggregateUUID() uuid.UUID  { return e.UserUUID }
+func (UserUpdated) EventType() string           { return model.EventTypeUserUpdated }
+func (e UserUpdated) AggregateUUID() uuid.UUID  { return e.UserUUID }
+func (UserDeleted) EventType() string           { return model.EventTypeUserDeleted }
+func (e UserDeleted) AggregateUUID() uuid.UUID  { return e.UserUUID }
+func (UserRestored) EventType() string          { return model.EventTypeUserRestored }
+func (e UserRestored) AggregateUUID() uuid.UUID { return e.UserUUID }
+func (UserPurged) EventType() string            { return model.EventTypeUserPurged }
+func (e UserPurged) AggregateUUID() uuid.UUID   { return e.UserUUID }
+
+func (RoleAssigned) EventType() string            { return model.EventTypeUserRolesAdded }
+func (e RoleAssigned) AggregateUUID() uuid.UUID   { return e.UserUUID }
+func (RoleUnassigned) EventType() string          { return model.EventTypeUserRolesRemoved }
+func (e RoleUnassigned) AggregateUUID() uuid.UUID { return e.UserUUID }
+
+func (ScopedRoleAssigned) EventType() string            { return model.EventTypeUserScopedRoleAdded }
+func (e ScopedRoleAssigned) AggregateUUID() uuid.UUID   { return e.UserUUID }
+func (ScopedRoleUnassigned) EventType() string          { return model.EventTypeUserScopedRoleRemoved }
+func (e ScopedRoleUnassigned) AggregateUUID() uuid.UUID { return e.UserUUID }
+
+// Role events.
+type (
+	RoleCreated            roleEventPayload
+	RoleUpdated            roleEventPayload
+	RoleDeleted            roleEventPayload
+	RolePermissionsAdded   rolePermissionsEventPayload
+	RolePermissionsRemoved rolePermissionsEventPayload
+)
+
+func (RoleCreated) EventType() string                     { return model.EventTypeRoleCreated }
+func (e RoleCreated) AggregateUUID() uuid.UUID            { return e.RoleUUID }
+func (RoleUpdated) EventType() string                     { return model.EventTypeRoleUpdated }
+func (e RoleUpdated) AggregateUUID() uuid.UUID            { return e.RoleUUID }
+func (RoleDeleted) EventType() string                     { return model.EventTypeRoleDeleted }
+func (e RoleDeleted) AggregateUUID() uuid.UUID            { return e.RoleUUID }
+func (RolePermissionsAdded) EventType() string            { return model.EventTypeRolePermissionsAdded }
+func (e RolePermissionsAdded) AggregateUUID() uuid.UUID   { return e.RoleUUID }
+func (RolePermissionsRemoved) EventType() string          { return model.EventTypeRolePermissionsRemoved }
+func (e RolePermissionsRemoved) AggregateUUID() uuid.UUID { return e.RoleUUID }
+
+// Client events.
+type (
+	ClientCreated clientEventPayload
+	ClientUpdated clientEventPayload
+	ClientDeleted clientEventPayload
+	// ClientSecretRotated records that a client was issued a new secret.
+	// Neither secret is part of the event.
+	ClientSecretRotated struct {
+		ClientUUID              uuid.UUID  `json:"client_uuid"`
+		RotatedAt               time.Time  `json:"rotated_at"`
+		PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at"`
+	}
+)
+
+func (ClientCreated) EventType() string                { return model.EventTypeClientCreated }
+func (e ClientCreated) AggregateUUID() uuid.UUID       { return e.ClientUUID }
+func (ClientUpdated) EventType() string                { return model.EventTypeClientUpdated }
+func (e ClientUpdated) AggregateUUID() uuid.UUID       { return e.ClientUUID }
+func (ClientDeleted) EventType() string                { return model.EventTypeClientDeleted }
+func (e ClientDeleted) AggregateUUID() uuid.UUID       { return e.ClientUUID }
+func (ClientSecretRotated) EventType() string          { return model.EventTypeClientSecretRotated }
+func (e ClientSecretRotated) AggregateUUID() uuid.UUID { return e.ClientUUID }
+
+// API key events. Neither the key nor its hash is part of the event.
+type (
+	APIKeyCreated apiKeyEventPayload
+	APIKeyUpdated apiKeyEventPayload
+	APIKeyRevoked apiKeyEventPayload
+	APIKeyDeleted apiKeyEventPayload
+)
+
+func (APIKeyCreated) EventType() string          { return model.EventTypeAPIKeyCreated }
+func (e APIKeyCreated) AggregateUUID() uuid.UUID { return e.APIKeyUUID }
+func (APIKeyUpdated) EventType() string          { return model.EventTypeAPIKeyUpdated }
+func (e APIKeyUpdated) AggregateUUID() uuid.UUID { return e.APIKeyUUID }
+func (APIKeyRevoked) EventType() string          { return model.EventTypeAPIKeyRevoked }
+func (e APIKeyRevoked) AggregateUUID() uuid.UUID { return e.APIKeyUUID }
+func (APIKeyDeleted) EventType() string          { return model.EventTypeAPIKeyDeleted }
+func (e APIKeyDeleted) AggregateUUID() uuid.UUID { return e.APIKeyUUID }
+
+// Group events. Membership and role events change the permissions of the
+// group's members.
+type (
+	GroupCreated        groupEventPayload
+	GroupUpdated        groupEventPayload
+	GroupDeleted        groupEventPayload
+	GroupMembersAdded   groupMembersEventPayload
+	GroupMembersRemoved groupMembersEventPayload
+	GroupRolesAdded     groupRolesEventPayload
+	GroupRolesRemoved   groupRolesEventPayload
+)
+
+func (GroupCreated) EventType() string                 { return model.EventTypeGroupCreated }
+func (e GroupCreated) AggregateUUID() uuid.UUID        { return e.GroupUUID }
+func (GroupUpdated) EventType() string                 { return model.EventTypeGroupUpdated }
+func (e GroupUpdated) AggregateUUID() uuid.UUID        { return e.GroupUUID }
+func (GroupDeleted) EventType() string                 { return model.EventTypeGroupDeleted }
+func (e GroupDeleted) AggregateUUID() uuid.UUID        { return e.GroupUUID }
+func (GroupMembersAdded) EventType() string            { return model.EventTypeGroupMembersAdded }
+func (e GroupMembersAdded) AggregateUUID() uuid.UUID   { return e.GroupUUID }
+func (GroupMembersRemoved) EventType() string          { return model.EventTypeGroupMembersRemoved }
+func (e GroupMembersRemoved) AggregateUUID() uuid.UUID { return e.GroupUUID }
+func (GroupRolesAdded) EventType() string              { return model.EventTypeGroupRolesAdded }
+func (e GroupRolesAdded) AggregateUUID() uuid.UUID     { return e.GroupUUID }
+func (GroupRolesRemoved) EventType() string            { return model.EventTypeGroupRolesRemoved }
+func (e GroupRolesRemoved) AggregateUUID() uuid.UUID   { return e.GroupUUID }
+
+// Signup flow events. Each is emitted once, by the signup that crosses the
+// threshold; the daily cap raises them again every day.
+type (
+	SignupFlowCapWarning signupFlowCapEventPayload
+	SignupFlowCapReached signupFlowCapEventPayload
+)
+
+func (SignupFlowCapWarning) EventType() string          { return model.EventTypeSignupFlowCapWarning }
+func (e SignupFlowCapWarning) AggregateUUID() uuid.UUID { return e.SignupFlowUUID }
+func (SignupFlowCapReached) EventType() string          { return model.EventTypeSignupFlowCapReached }
+func (e SignupFlowCapReached) AggregateUUID() uuid.UUID { return e.SignupFlowUUID }
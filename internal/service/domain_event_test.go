@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainEvents(t *testing.T) {
+	id := uuid.New()
+	tests := []struct {
+		event DomainEvent
+		want  string
+	}{
+		{UserCreated{UserUUID: id}, model.EventTypeUserCreated},
+		{UserUpdated{UserUUID: id}, model.EventTypeUserUpdated},
+		{UserDeleted{UserUUID: id}, model.EventTypeUserDeleted},
+		{UserRestored{UserUUID: id}, model.EventTypeUserRestored},
+		{UserPurged{UserUUID: id}, model.EventTypeUserPurged},
+		{RoleAssigned{UserUUID: id}, model.EventTypeUserRolesAdded},
+		{RoleUnassigned{UserUUID: id}, model.EventTypeUserRolesRemoved},
+		{RoleCreated{RoleUUID: id}, model.EventTypeRoleCreated},
+		{RoleUpdated{RoleUUID: id}, model.EventTypeRoleUpdated},
+		{RoleDeleted{RoleUUID: id}, model.EventTypeRoleDeleted},
+		{RolePermissionsAdded{RoleUUID: id}, model.EventTypeRolePermissionsAdded},
+		{RolePermissionsRemoved{RoleUUID: id}, model.EventTypeRolePermissionsRemoved},
+		{ClientCreated{ClientUUID: id}, model.EventTypeClientCreated},
+		{ClientUpdated{ClientUUID: id}, model.EventTypeClientUpdated},
+		{ClientDeleted{ClientUUID: id}, model.EventTypeClientDeleted},
+		{ClientSecretRotated{ClientUUID: id}, model.EventTypeClientSecretRotated},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.event.EventType())
+		assert.Equal(t, id, tt.event.AggregateUUID(), tt.want)
+		assert.Contains(t, model.EventTypes, tt.want)
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/maintainerd/auth/internal/repository"
+	"gorm.io/gorm"
+)
+
+// pgForeignKeyViolation is the SQLSTATE Postgres reports when a delete is
+// refused by a foreign key.
+const pgForeignKeyViolation = "23503"
+
+// DeleteImpactSoftDelete is the action reported for rows a soft delete marks
+// as deleted.
+const DeleteImpactSoftDelete = "soft_delete"
+
+// newDeleteImpactRepositoryFn is swapped by tests.
+var newDeleteImpactRepositoryFn = repository.NewDeleteImpactRepository
+
+// errDryRunRollback aborts the transaction of a dry run once the impact is
+// known.
+var errDryRunRollback = errors.New("dry run rolled back")
+
+// DeleteImpactReport describes what a destructive operation would do.
+// RowsAffected counts every row deleted or changed, including cascades.
+type DeleteImpactReport struct {
+	WouldSucceed        bool
+	RowsAffected        int64
+	Impact              []repository.DeleteImpactEntry
+	BlockingConstraints []string
+	Events              []string
+}
+
+// deleteImpactFn measures a delete inside the dry-run transaction.
+type deleteImpactFn func(tx *gorm.DB) (*repository.DeleteImpact, error)
+
+// hardDeleteImpact measures the delete of the rows of table where column
+// equals value from the foreign keys that reference them.
+func hardDeleteImpact(table, column string, value any) deleteImpactFn {
+	return func(tx *gorm.DB) (*repository.DeleteImpact, error) {
+		return newDeleteImpactRepositoryFn(tx).Analyze(table, column, value)
+	}
+}
+
+// softDeleteImpact reports a soft delete of rows rows of table. Soft deletes
+// update the row in place, so nothing cascades.
+func softDeleteImpact(table string, rows int64) deleteImpactFn {
+	return func(*gorm.DB) (*repository.DeleteImpact, error) {
+		return &repository.DeleteImpact{Entries: []repository.DeleteImpactEntry{
+			{Table: table, Action: DeleteImpactSoftDelete, Rows: rows},
+		}}, nil
+	}
+}
+
+// previewDelete measures a delete with impact and then runs del, the same
+// function the real operation runs, in a transaction that is always rolled
+// back. A delete refused by a foreign key is reported with WouldSucceed unset
+// and the refusing constraints rather than as an error. events lists the
+// domain events the operation would record.
+func previewDelete(db *gorm.DB, impact deleteImpactFn, events []string, del func(tx *gorm.DB) error) (*DeleteImpactReport, error) {
+	report := &DeleteImpactReport{Events: events}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		measured, err := impact(tx)
+		if err != nil {
+			return err
+		}
+		report.Impact = measured.Entries
+		for _, e := range measured.Entries {
+			if e.Action != repository.DeleteImpactRestrict {
+				report.RowsAffected += e.Rows
+			}
+		}
+
+		err = del(tx)
+		var pgErr *pgconn.PgError
+		switch {
+		case err == nil:
+			report.WouldSucceed = true
+		case errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation:
+			for _, e := range measured.Blocking() {
+				report.BlockingConstraints = append(report.BlockingConstraints, e.Constraint)
+			}
+			if len(report.BlockingConstraints) == 0 {
+				report.BlockingConstraints = []string{pgErr.ConstraintName}
+			}
+			report.Events = nil
+		default:
+			return err
+		}
+		return errDryRunRollback
+	})
+	if !errors.Is(err, errDryRunRollback) {
+		return nil, err
+	}
+	return report, nil
+}
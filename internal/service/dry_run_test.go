@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type mockDeleteImpactRepo struct {
+	analyzeFn func(table, column string, value any) (*repository.DeleteImpact, error)
+}
+
+func (m *mockDeleteImpactRepo) WithTx(_ *gorm.DB) repository.DeleteImpactRepository { return m }
+func (m *mockDeleteImpactRepo) Analyze(table, column string, value any) (*repository.DeleteImpact, error) {
+	return m.analyzeFn(table, column, value)
+}
+
+// stubDeleteImpact makes previewDelete measure deletes with repo.
+func stubDeleteImpact(t *testing.T, repo *mockDeleteImpactRepo) {
+	t.Helper()
+	orig := newDeleteImpactRepositoryFn
+	newDeleteImpactRepositoryFn = func(*gorm.DB) repository.DeleteImpactRepository { return repo }
+	t.Cleanup(func() { newDeleteImpactRepositoryFn = orig })
+}
+
+func roleDeleteImpact() *repository.DeleteImpact {
+	return &repository.DeleteImpact{Entries: []repository.DeleteImpactEntry{
+		{Table: "roles", Action: repository.DeleteImpactDelete, Rows: 1},
+		{Table: "user_roles", Constraint: "fk_user_roles_role", Action: repository.DeleteImpactCascade, Rows: 12},
+		{Table: "role_permissions", Constraint: "fk_role_permissions_role", Action: repository.DeleteImpactCascade, Rows: 4},
+	}}
+}
+
+func TestPreviewDelete(t *testing.T) {
+	impact := func(entries ...repository.DeleteImpactEntry) deleteImpactFn {
+		return func(*gorm.DB) (*repository.DeleteImpact, error) {
+			return &repository.DeleteImpact{Entries: entries}, nil
+		}
+	}
+
+	t.Run("runs the delete and rolls back", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		ran := false
+
+		report, err := previewDelete(db, impact(roleDeleteImpact().Entries...), []string{model.EventTypeRoleDeleted}, func(*gorm.DB) error {
+			ran = true
+			return nil
+		})
+		require.NoError(t, err)
+		assert.True(t, ran)
+		assert.True(t, report.WouldSucceed)
+		assert.Equal(t, int64(17), report.RowsAffected)
+		assert.Len(t, report.Impact, 3)
+		assert.Empty(t, report.BlockingConstraints)
+		assert.Equal(t, []string{model.EventTypeRoleDeleted}, report.Events)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("foreign key violation is reported", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		report, err := previewDelete(db, impact(
+			repository.DeleteImpactEntry{Table: "tenants", Action: repository.DeleteImpactDelete, Rows: 1},
+			repository.DeleteImpactEntry{Table: "clients", Constraint: "fk_clients_tenant", Action: repository.DeleteImpactRestrict, Rows: 3},
+		), []string{"tenant.deleted"}, func(*gorm.DB) error {
+			return &pgconn.PgError{Code: pgForeignKeyViolation, ConstraintName: "fk_clients_tenant"}
+		})
+		require.NoError(t, err)
+		assert.False(t, report.WouldSucceed)
+		assert.Equal(t, int64(1), report.RowsAffected, "refused rows are not counted")
+		assert.Equal(t, []string{"fk_clients_tenant"}, report.BlockingConstraints)
+		assert.Empty(t, report.Events, "a refused delete records no events")
+	})
+
+	t.Run("violation not found by the analysis", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		report, err := previewDelete(db, impact(), nil, func(*gorm.DB) error {
+			return &pgconn.PgError{Code: pgForeignKeyViolation, ConstraintName: "fk_composite"}
+		})
+		require.NoError(t, err)
+		assert.False(t, report.WouldSucceed)
+		assert.Equal(t, []string{"fk_composite"}, report.BlockingConstraints)
+	})
+
+	t.Run("other delete errors are returned", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		_, err := previewDelete(db, impact(), nil, func(*gorm.DB) error { return errors.New("db down") })
+		assert.EqualError(t, err, "db down")
+	})
+
+	t.Run("analysis error skips the delete", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		_, err := previewDelete(db, func(*gorm.DB) (*repository.DeleteImpact, error) {
+			return nil, errors.New("catalog unavailable")
+		}, nil, func(*gorm.DB) error {
+			t.Fatal("delete must not run")
+			return nil
+		})
+		assert.EqualError(t, err, "catalog unavailable")
+	})
+}
+
+func TestTenantService_PreviewDeleteByUUID(t *testing.T) {
+	tenantUUID := uuid.New()
+
+	t.Run("system tenant", func(t *testing.T) {
+		svc := NewTenantService(nil, &mockTenantRepo{findByUUIDFn: func(_ any, _ ...string) (*model.Tenant, error) {
+			tenant := newTenant(1, "system")
+			tenant.IsSystem = true
+			return tenant, nil
+		}}, &mockTenantProvisioner{})
+		_, err := svc.PreviewDeleteByUUID(context.Background(), tenantUUID)
+		var validation *apperror.ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		stubDeleteImpact(t, &mockDeleteImpactRepo{analyzeFn: func(table, column string, value any) (*repository.DeleteImpact, error) {
+			assert.Equal(t, "tenants", table)
+			assert.Equal(t, "tenant_id", column)
+			assert.Equal(t, int64(7), value)
+			return &repository.DeleteImpact{Entries: []repository.DeleteImpactEntry{{Table: "tenants", Action: repository.DeleteImpactDelete, Rows: 1}}}, nil
+		}})
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		deleted := false
+		svc := NewTenantService(db, &mockTenantRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Tenant, error) { return newTenant(7, "acme"), nil },
+		}, &mockTenantProvisioner{
+			deprovisionFn: func(_ context.Context, _ *gorm.DB, _ *model.Tenant) error { deleted = true; return nil },
+		})
+
+		report, err := svc.PreviewDeleteByUUID(context.Background(), tenantUUID)
+		require.NoError(t, err)
+		assert.True(t, deleted, "the preview runs the real delete")
+		assert.True(t, report.WouldSucceed)
+		assert.Equal(t, int64(1), report.RowsAffected)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRoleService_PreviewDeleteByUUID(t *testing.T) {
+	tenantID := int64(1)
+	role := newRole(3, "editor", tenantID)
+	stubDeleteImpact(t, &mockDeleteImpactRepo{analyzeFn: func(table, column string, value any) (*repository.DeleteImpact, error) {
+		assert.Equal(t, "roles", table)
+		assert.Equal(t, "role_id", column)
+		assert.Equal(t, role.RoleID, value)
+		return roleDeleteImpact(), nil
+	}})
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		events := &mockEventRepo{}
+		svc := NewRoleService(db, &mockRoleRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) { return role, nil },
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return roleActorUser(tenantID), nil },
+		}, &mockTenantRepo{}, events, cache.NopInvalidator{})
+
+		report, err := svc.PreviewDeleteByUUID(context.Background(), role.RoleUUID, tenantID, uuid.New())
+		require.NoError(t, err)
+		assert.True(t, report.WouldSucceed)
+		assert.Equal(t, int64(17), report.RowsAffected)
+		assert.Equal(t, []string{model.EventTypeRoleDeleted}, report.Events)
+		assert.Len(t, events.created, 1, "the event is written inside the rolled back transaction")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("system role", func(t *testing.T) {
+		system := newRole(4, "admin", tenantID)
+		system.IsSystem = true
+		svc := newRoleService(&mockRoleRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) { return system, nil },
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return roleActorUser(tenantID), nil },
+		}, &mockTenantRepo{})
+		_, err := svc.PreviewDeleteByUUID(context.Background(), system.RoleUUID, tenantID, uuid.New())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "system role")
+	})
+
+	t.Run("delete error", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := NewRoleService(db, &mockRoleRepo{
+			findByUUIDFn:   func(_ any, _ ...string) (*model.Role, error) { return role, nil },
+			deleteByUUIDFn: func(any) error { return errors.New("db down") },
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return roleActorUser(tenantID), nil },
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
+		_, err := svc.PreviewDeleteByUUID(context.Background(), role.RoleUUID, tenantID, uuid.New())
+		var internalErr *apperror.InternalError
+		assert.ErrorAs(t, err, &internalErr)
+	})
+}
+
+func TestUserService_PreviewPurgeByUUID(t *testing.T) {
+	ur, ui, urr, rr, tr, idp, cr, up := deletedTargetMocks(false)
+	var purgedUUID any
+	ur.deleteByUUIDFn = func(id any) error {
+		purgedUUID = id
+		return nil
+	}
+	stubDeleteImpact(t, &mockDeleteImpactRepo{analyzeFn: func(table, column string, _ any) (*repository.DeleteImpact, error) {
+		assert.Equal(t, "users", table)
+		assert.Equal(t, "user_id", column)
+		return &repository.DeleteImpact{Entries: []repository.DeleteImpactEntry{
+			{Table: "users", Action: repository.DeleteImpactDelete, Rows: 1},
+			{Table: "user_identities", Constraint: "fk_user_identities_user", Action: repository.DeleteImpactCascade, Rows: 2},
+		}}, nil
+	}})
+	var logged []AuthEventInput
+	db, mock := newMockGormDB(t)
+	svc := NewUserService(db, ur, ui, urr, rr, tr, idp, cr, up, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockEmailTemplateRepo{}, &mockEventRepo{}, &mockAuthEventService{
+		logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) },
+	}, nil, cache.NopInvalidator{}, NopPlanEnforcer{})
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	report, err := svc.PreviewPurgeByUUID(context.Background(), uuid.New(), 1, uuid.New())
+	require.NoError(t, err)
+	assert.NotNil(t, purgedUUID)
+	assert.True(t, report.WouldSucceed)
+	assert.Equal(t, int64(3), report.RowsAffected)
+	assert.Equal(t, []string{model.EventTypeUserPurged}, report.Events)
+	assert.Empty(t, logged, "a dry run writes no audit entry")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserService_PreviewDeleteByUUID(t *testing.T) {
+	ur, ui, urr, rr, tr, idp, cr, up := deletedTargetMocks(false)
+	db, mock := newMockGormDB(t)
+	svc := NewUserService(db, ur, ui, urr, rr, tr, idp, cr, up, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockEmailTemplateRepo{}, &mockEventRepo{}, &mockAuthEventService{}, nil, cache.NopInvalidator{}, NopPlanEnforcer{})
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	report, err := svc.PreviewDeleteByUUID(context.Background(), uuid.New(), 1, uuid.New())
+	require.NoError(t, err)
+	assert.True(t, report.WouldSucceed)
+	require.Len(t, report.Impact, 1)
+	assert.Equal(t, DeleteImpactSoftDelete, report.Impact[0].Action)
+	assert.Equal(t, []string{model.EventTypeUserDeleted}, report.Events)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
+)
+
+// Generic messages returned by enumeration-safe tenants in place of the
+// specific reason.
+const (
+	msgInvalidCredentials   = "invalid credentials"
+	msgRegistrationConflict = "an account cannot be created with these details"
+)
+
+// enumerationSafeMinDuration is the shortest time an enumeration-safe password
+// reset or registration takes to answer, which hides the token writes and
+// email delivery done only for existing accounts. A variable so tests can
+// shorten it.
+var enumerationSafeMinDuration = 750 * time.Millisecond
+
+// errAccountExists aborts the registration transaction of an
+// enumeration-safe tenant when the username, email or phone is taken, so the
+// caller can answer without naming the conflicting field.
+var errAccountExists = errors.New("account exists")
+
+// enumerationSafe reports whether the tenant has enabled the enumeration-safe
+// mode. A settings lookup failure enables the mode: answering generically is
+// harmless, revealing an account is not.
+func enumerationSafe(tenantSettingRepo repository.TenantSettingRepository, tenantID int64) bool {
+	setting, err := tenantSettingRepo.FindByTenantID(tenantID)
+	if err != nil {
+		return true
+	}
+	return setting != nil && unmarshalJSON(setting.FeatureFlags)[model.FeatureFlagEnumerationSafe] == true
+}
+
+// padResponseTime blocks until enumerationSafeMinDuration has passed since
+// start or ctx is done.
+func padResponseTime(ctx context.Context, start time.Time) {
+	remaining := enumerationSafeMinDuration - time.Since(start)
+	if remaining <= 0 {
+		return
+	}
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// accountConflict returns the conflict for a registration field that is
+// already taken, or errAccountExists for enumeration-safe tenants.
+func accountConflict(safe bool, reason string) error {
+	if safe {
+		return errAccountExists
+	}
+	return apperror.NewConflict(reason)
+}
+
+// registerExistingAccount answers an enumeration-safe registration that hit
+// an existing account. It spends the password hash a real sign-up spends and,
+// when the signup flow verifies email or holds the account for parental
+// consent, answers exactly like a new pending account; otherwise a sign-up
+// returns tokens, so only the conflict reason can be hidden.
+func (s *registerService) registerExistingAccount(ctx context.Context, plan *signupPlan, password string, startTime time.Time) (*dto.RegisterResponseDTO, error) {
+	_, _ = security.HashPassword([]byte(password))
+	padResponseTime(ctx, startTime)
+	if plan.config.EmailVerification || plan.parentalConsent {
+		return &dto.RegisterResponseDTO{
+			VerificationRequired:    plan.config.EmailVerification,
+			ParentalConsentRequired: plan.parentalConsent,
+		}, nil
+	}
+	return nil, apperror.NewConflict(msgRegistrationConflict)
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/email"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+// enumerationSafeSettingRepo returns a tenant setting repo with the
+// enumeration-safe flag set to enabled.
+func enumerationSafeSettingRepo(enabled bool) *mockTenantSettingRepo {
+	flags := `{"enumeration_safe": false}`
+	if enabled {
+		flags = `{"enumeration_safe": true}`
+	}
+	return &mockTenantSettingRepo{
+		findByTenantIDFn: func(int64) (*model.TenantSetting, error) {
+			return &model.TenantSetting{FeatureFlags: datatypes.JSON(flags)}, nil
+		},
+	}
+}
+
+// shortenEnumerationSafePadding keeps padded responses fast in tests.
+func shortenEnumerationSafePadding(t *testing.T, d time.Duration) {
+	t.Helper()
+	orig := enumerationSafeMinDuration
+	enumerationSafeMinDuration = d
+	t.Cleanup(func() { enumerationSafeMinDuration = orig })
+}
+
+func TestEnumerationSafe(t *testing.T) {
+	assert.True(t, enumerationSafe(enumerationSafeSettingRepo(true), 1))
+	assert.False(t, enumerationSafe(enumerationSafeSettingRepo(false), 1))
+	assert.False(t, enumerationSafe(&mockTenantSettingRepo{}, 1))
+
+	failing := &mockTenantSettingRepo{
+		findByTenantIDFn: func(int64) (*model.TenantSetting, error) { return nil, errors.New("db down") },
+	}
+	assert.True(t, enumerationSafe(failing, 1), "lookup failure must enable the safe mode")
+}
+
+func TestPadResponseTime(t *testing.T) {
+	shortenEnumerationSafePadding(t, 30*time.Millisecond)
+
+	start := time.Now()
+	padResponseTime(context.Background(), start)
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+	// Already slower than the floor: no extra wait
+	start = time.Now().Add(-time.Second)
+	before := time.Now()
+	padResponseTime(context.Background(), start)
+	assert.Less(t, time.Since(before), 30*time.Millisecond)
+
+	// A cancelled request stops waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	before = time.Now()
+	padResponseTime(ctx, time.Now())
+	assert.Less(t, time.Since(before), 30*time.Millisecond)
+}
+
+func TestLogin_EnumerationSafe_ResponseParity(t *testing.T) {
+	initTestJWTKeysService(t)
+	const password = "S3cur3P@ss!"
+
+	inactive := buildActiveUser(t, password)
+	inactive.Status = model.StatusInactive
+	pending := buildActiveUser(t, password)
+	pending.Status = model.StatusPending
+
+	cases := []struct {
+		name     string
+		user     *model.User
+		password string
+	}{
+		{"unknown account", nil, password},
+		{"wrong password", buildActiveUser(t, password), "wrong-password"},
+		{"inactive account", inactive, password},
+		{"pending account", pending, password},
+	}
+
+	login := func(t *testing.T, public, safe bool, username string, user *model.User, pw string) error {
+		t.Helper()
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		clientRepo := &mockClientRepo{
+			findSystemFn: func() (*model.Client, error) { return buildActiveClient(), nil },
+			findByClientIDAndIdentityProviderFn: func(_, _ string) (*model.Client, error) {
+				return buildActiveClient(), nil
+			},
+		}
+		userRepo := &mockUserRepo{findByUsernameFn: func(_ string) (*model.User, error) { return user, nil }}
+		idpRepo := &mockIdentityProviderRepo{
+			findByIdentifierFn: func(_ string) (*model.IdentityProvider, error) { return buildActiveIdentityProvider(), nil },
+		}
+		identityRepo := &mockUserIdentityRepo{
+			findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) { return nil, nil },
+		}
+		svc := NewLoginService(gormDB, clientRepo, userRepo, &mockUserTokenRepo{}, identityRepo, idpRepo,
+			enumerationSafeSettingRepo(safe), &mockSecuritySettingRepo{}, &mockAuthEventService{}, nil, nil, nil, nil, nil)
+		var err error
+		if public {
+			_, err = svc.LoginPublic(context.Background(), username, pw, "c1", "p1", nil)
+		} else {
+			_, err = svc.Login(context.Background(), username, pw, nil, nil, nil)
+		}
+		require.Error(t, err)
+		return err
+	}
+
+	for _, public := range []bool{true, false} {
+		var want error
+		for _, tc := range cases {
+			username := uuid.NewString()
+			err := login(t, public, true, username, tc.user, tc.password)
+			if want == nil {
+				want = err
+			}
+			var unauthorized *apperror.UnauthorizedError
+			require.ErrorAs(t, err, &unauthorized, tc.name)
+			assert.Equal(t, want, err, "public=%v %s", public, tc.name)
+		}
+		assert.EqualError(t, want, msgInvalidCredentials)
+	}
+
+	t.Run("disabled keeps the specific reason", func(t *testing.T) {
+		err := login(t, true, false, uuid.NewString(), inactive, password)
+		assert.EqualError(t, err, "account is not active")
+	})
+}
+
+func TestForgotPassword_EnumerationSafe_ResponseParity(t *testing.T) {
+	shortenEnumerationSafePadding(t, 50*time.Millisecond)
+	os.Setenv("HMAC_SECRET_KEY", "test-secret-key-for-hmac")
+	defer os.Unsetenv("HMAC_SECRET_KEY")
+	origAppPublicHostname := config.AppPublicHostname
+	defer func() { config.AppPublicHostname = origAppPublicHostname }()
+	config.AppPublicHostname = "https://api.example.com"
+
+	sent := make(chan string, 1)
+	origSendEmail := email.SendEmail
+	defer func() { email.SendEmail = origSendEmail }()
+	email.SendEmail = func(_ context.Context, p email.SendEmailParams) error {
+		sent <- p.To
+		return nil
+	}
+
+	emailTemplateRepo := &mockEmailTemplateRepo{
+		findByNameFn: func(_ string) (*model.EmailTemplate, error) {
+			return &model.EmailTemplate{Subject: "Reset", BodyHTML: `<a href="{{.ResetURL}}">R</a>`}, nil
+		},
+	}
+	clientRepo := &mockClientRepo{
+		findSystemFn: func() (*model.Client, error) { return buildActiveClient(), nil },
+	}
+	existing := &model.User{UserID: 1, UserUUID: uuid.New(), Email: "user@example.com", Status: model.StatusActive}
+
+	reset := func(t *testing.T, user *model.User, tokenRepo *mockUserTokenRepo, commit bool) (*dto.ForgotPasswordResponseDTO, time.Duration, error) {
+		t.Helper()
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		if commit {
+			mock.ExpectCommit()
+		} else {
+			mock.ExpectRollback()
+		}
+		userRepo := &mockUserRepo{findByEmailFn: func(_ string) (*model.User, error) { return user, nil }}
+		svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo, enumerationSafeSettingRepo(true))
+		start := time.Now()
+		res, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", nil, nil, false)
+		return res, time.Since(start), err
+	}
+
+	missingRes, missingTook, missingErr := reset(t, nil, &mockUserTokenRepo{}, true)
+	require.NoError(t, missingErr)
+	assert.GreaterOrEqual(t, missingTook, 50*time.Millisecond)
+
+	existingRes, existingTook, existingErr := reset(t, existing, &mockUserTokenRepo{}, true)
+	require.NoError(t, existingErr)
+	assert.GreaterOrEqual(t, existingTook, 50*time.Millisecond)
+	assert.Equal(t, missingRes, existingRes)
+
+	select {
+	case to := <-sent:
+		assert.Equal(t, existing.Email, to)
+	case <-time.After(time.Second):
+		t.Fatal("reset email was not sent")
+	}
+
+	t.Run("token failure is hidden", func(t *testing.T) {
+		failing := &mockUserTokenRepo{
+			findByUserIDAndTokenTypeFn: func(_ int64, _ string) ([]model.UserToken, error) {
+				return nil, errors.New("db down")
+			},
+		}
+		res, _, err := reset(t, existing, failing, false)
+		require.NoError(t, err)
+		assert.Equal(t, missingRes, res)
+	})
+}
+
+func TestRegisterPublic_EnumerationSafe_ResponseParity(t *testing.T) {
+	initTestJWTKeysService(t)
+	shortenEnumerationSafePadding(t, 10*time.Millisecond)
+	ctx := context.Background()
+	addr := "jane@acme.com"
+	phone := "+15550100"
+	taken := &model.User{UserID: 9}
+
+	register := func(t *testing.T, flowConfig string, safe bool, setup func(*regMocks)) (*dto.RegisterResponseDTO, error) {
+		t.Helper()
+		m := defaultRegPublicMocks()
+		if setup != nil {
+			setup(m)
+		}
+		flowRepo := &mockSignupFlowRepo{findActiveByClientIDFn: func(_ int64) (*model.SignupFlow, error) {
+			return activeSignupFlow(flowConfig), nil
+		}}
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, enumerationSafeSettingRepo(safe), &mockSecuritySettingRepo{},
+			flowRepo, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		return svc.RegisterPublic(ctx, uuid.NewString(), "Jane", "P@ss1!", &addr, &phone, "c", "p", SignupFlowInput{}, nil)
+	}
+
+	conflicts := map[string]func(*regMocks){
+		"username": func(m *regMocks) { m.user.findByUsernameFn = func(_ string) (*model.User, error) { return taken, nil } },
+		"email":    func(m *regMocks) { m.user.findByEmailFn = func(_ string) (*model.User, error) { return taken, nil } },
+		"phone":    func(m *regMocks) { m.user.findByPhoneFn = func(_ string) (*model.User, error) { return taken, nil } },
+	}
+
+	t.Run("email verification hides existing accounts", func(t *testing.T) {
+		const flow = `{"email_verification":true}`
+		fresh, err := register(t, flow, true, nil)
+		require.NoError(t, err)
+		for field, setup := range conflicts {
+			res, err := register(t, flow, true, setup)
+			require.NoError(t, err, field)
+			assert.Equal(t, fresh, res, field)
+		}
+	})
+
+	t.Run("without verification the conflict reason is generic", func(t *testing.T) {
+		for field, setup := range conflicts {
+			_, err := register(t, `{}`, true, setup)
+			var conflict *apperror.ConflictError
+			require.ErrorAs(t, err, &conflict, field)
+			assert.EqualError(t, err, msgRegistrationConflict, field)
+		}
+	})
+
+	t.Run("disabled names the field", func(t *testing.T) {
+		_, err := register(t, `{}`, false, conflicts["email"])
+		assert.EqualError(t, err, "email already registered")
+	})
+}
@@ -0,0 +1,394 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/datatypes"
+)
+
+// eventFeedSettleWindow holds back events younger than this from the feed.
+// Sequence numbers are assigned at insert time but become visible at commit,
+// so a slow transaction can commit a lower sequence after a higher one has
+// already been served. Delaying the tail of the feed gives in-flight
+// transactions time to land before a consumer's cursor moves past them.
+const eventFeedSettleWindow = 2 * time.Second
+
+// eventCursorPrefix versions the opaque cursor format.
+const eventCursorPrefix = "e1:"
+
+// EventServiceFeedFilter selects a page of the lifecycle event feed.
+type EventServiceFeedFilter struct {
+	TenantID int64
+	Cursor   string
+	Types    []string
+	Limit    int
+}
+
+// EventServiceDataResult is the service-layer representation of a lifecycle
+// event.
+type EventServiceDataResult struct {
+	EventUUID     uuid.UUID
+	Sequence      int64
+	EventType     string
+	SchemaVersion int
+	AggregateType string
+	AggregateUUID uuid.UUID
+	Payload       datatypes.JSON
+	OccurredAt    time.Time
+}
+
+// EventServiceFeedResult is a page of the feed together with the cursor to
+// resume from. NextCursor is always set; when no new events are available it
+// echoes the cursor that was passed in.
+type EventServiceFeedResult struct {
+	Data       []EventServiceDataResult
+	NextCursor string
+	HasMore    bool
+}
+
+// EventService exposes the ordered, resumable lifecycle event feed.
+type EventService interface {
+	// Feed returns events after the supplied cursor in sequence order.
+	Feed(ctx context.Context, filter EventServiceFeedFilter) (*EventServiceFeedResult, error)
+}
+
+type eventService struct {
+	eventRepo repository.EventRepository
+}
+
+// NewEventService creates a new EventService.
+func NewEventService(eventRepo repository.EventRepository) EventService {
+	return &eventService{
+		eventRepo: eventRepo,
+	}
+}
+
+func (s *eventService) Feed(ctx context.Context, filter EventServiceFeedFilter) (*EventServiceFeedResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "event.feed")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", filter.TenantID))
+
+	afterID, err := decodeEventCursor(filter.Cursor)
+	if err != nil {
+		span.SetStatus(codes.Error, "invalid cursor")
+		return nil, err
+	}
+
+	// Fetch one extra row to learn whether another page follows.
+	before := time.Now().Add(-eventFeedSettleWindow)
+	events, err := s.eventRepo.FindFeed(repository.EventRepositoryFeedFilter{
+		TenantID: filter.TenantID,
+		AfterID:  afterID,
+		Types:    filter.Types,
+		Before:   &before,
+		Limit:    filter.Limit + 1,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "event feed failed")
+		return nil, err
+	}
+
+	hasMore := len(events) > filter.Limit
+	if hasMore {
+		events = events[:filter.Limit]
+	}
+
+	data := make([]EventServiceDataResult, len(events))
+	for i := range events {
+		data[i] = toEventServiceDataResult(&events[i])
+	}
+
+	nextID := afterID
+	if len(events) > 0 {
+		nextID = events[len(events)-1].EventID
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return &EventServiceFeedResult{
+		Data:       data,
+		NextCursor: encodeEventCursor(nextID),
+		HasMore:    hasMore,
+	}, nil
+}
+
+// encodeEventCursor wraps an event sequence number in an opaque cursor so
+// clients do not come to depend on its format.
+func encodeEventCursor(eventID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(eventCursorPrefix + strconv.FormatInt(eventID, 10)))
+}
+
+// decodeEventCursor parses a cursor produced by encodeEventCursor. An empty
+// cursor starts from the beginning of the feed.
+func decodeEventCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), eventCursorPrefix) {
+		return 0, apperror.NewValidation("invalid cursor")
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(string(raw), eventCursorPrefix), 10, 64)
+	if err != nil || id < 0 {
+		return 0, apperror.NewValidation("invalid cursor")
+	}
+	return id, nil
+}
+
+func toEventServiceDataResult(e *model.Event) EventServiceDataResult {
+	return EventServiceDataResult{
+		EventUUID:     e.EventUUID,
+		Sequence:      e.EventID,
+		EventType:     e.EventType,
+		SchemaVersion: e.SchemaVersion,
+		AggregateType: e.AggregateType,
+		AggregateUUID: e.AggregateUUID,
+		Payload:       e.Payload,
+		OccurredAt:    e.OccurredAt,
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Event recording
+// ---------------------------------------------------------------------------
+
+// Event payloads, shared by the typed events in domain_event.go. Entity
+// events carry a full snapshot of the aggregate so a consumer can upsert from
+// a single event; secrets and credentials are never included.
+
+type userEventPayload struct {
+	UserUUID           uuid.UUID       `json:"user_uuid"`
+	Username           string          `json:"username"`
+	Fullname           string          `json:"fullname"`
+	Email              string          `json:"email"`
+	Phone              string          `json:"phone"`
+	IsEmailVerified    bool            `json:"is_email_verified"`
+	IsPhoneVerified    bool            `json:"is_phone_verified"`
+	IsProfileCompleted bool            `json:"is_profile_completed"`
+	IsAccountCompleted bool            `json:"is_account_completed"`
+	Status             string          `json:"status"`
+	Metadata           json.RawMessage `json:"metadata,omitempty"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+	DeletedAt          *time.Time      `json:"deleted_at,omitempty"`
+}
+
+// userPurgedEventPayload identifies a permanently erased user without any of
+// its personal data.
+type userPurgedEventPayload struct {
+	UserUUID uuid.UUID `json:"user_uuid"`
+}
+
+type userRolesEventPayload struct {
+	UserUUID  uuid.UUID   `json:"user_uuid"`
+	RoleUUIDs []uuid.UUID `json:"role_uuids"`
+}
+
+type userScopedRoleEventPayload struct {
+	UserUUID           uuid.UUID `json:"user_uuid"`
+	ScopedUserRoleUUID uuid.UUID `json:"scoped_role_uuid"`
+	RoleUUID           uuid.UUID `json:"role_uuid"`
+	ResourceType       string    `json:"resource_type"`
+	ResourceUUID       uuid.UUID `json:"resource_uuid"`
+}
+
+type roleEventPayload struct {
+	RoleUUID    uuid.UUID `json:"role_uuid"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IsDefault   bool      `json:"is_default"`
+	IsSystem    bool      `json:"is_system"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type rolePermissionsEventPayload struct {
+	RoleUUID        uuid.UUID   `json:"role_uuid"`
+	PermissionUUIDs []uuid.UUID `json:"permission_uuids"`
+}
+
+type clientEventPayload struct {
+	ClientUUID   uuid.UUID  `json:"client_uuid"`
+	Name         string     `json:"name"`
+	DisplayName  string     `json:"display_name"`
+	ClientType   string     `json:"client_type"`
+	Domain       *string    `json:"domain"`
+	Identifier   *string    `json:"identifier"`
+	IsDefault    bool       `json:"is_default"`
+	IsSystem     bool       `json:"is_system"`
+	Status       string     `json:"status"`
+	DeprecatedAt *time.Time `json:"deprecated_at"`
+	SunsetAt     *time.Time `json:"sunset_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type apiKeyEventPayload struct {
+	APIKeyUUID  uuid.UUID  `json:"api_key_uuid"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	KeyPrefix   string     `json:"key_prefix"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	RateLimit   *int       `json:"rate_limit"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type groupEventPayload struct {
+	GroupUUID   uuid.UUID `json:"group_uuid"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type groupMembersEventPayload struct {
+	GroupUUID uuid.UUID   `json:"group_uuid"`
+	UserUUIDs []uuid.UUID `json:"user_uuids"`
+}
+
+type groupRolesEventPayload struct {
+	GroupUUID uuid.UUID   `json:"group_uuid"`
+	RoleUUIDs []uuid.UUID `json:"role_uuids"`
+}
+
+// signupFlowCapEventPayload reports a signup flow cap crossing. Cap is
+// "total" or "daily"; Count is the number of signups including the one that
+// crossed the threshold.
+type signupFlowCapEventPayload struct {
+	SignupFlowUUID uuid.UUID `json:"signup_flow_uuid"`
+	Identifier     string    `json:"identifier"`
+	Cap            string    `json:"cap"`
+	Limit          int       `json:"limit"`
+	Count          int64     `json:"count"`
+}
+
+func newUserEventPayload(u *model.User) userEventPayload {
+	p := userEventPayload{
+		UserUUID:           u.UserUUID,
+		Username:           u.Username,
+		Fullname:           u.Fullname,
+		Email:              u.Email,
+		Phone:              u.Phone,
+		IsEmailVerified:    u.IsEmailVerified,
+		IsPhoneVerified:    u.IsPhoneVerified,
+		IsProfileCompleted: u.IsProfileCompleted,
+		IsAccountCompleted: u.IsAccountCompleted,
+		Status:             u.Status,
+		CreatedAt:          u.CreatedAt,
+		UpdatedAt:          u.UpdatedAt,
+		DeletedAt:          u.DeletedAt,
+	}
+	if json.Valid(u.Metadata) {
+		p.Metadata = json.RawMessage(u.Metadata)
+	}
+	return p
+}
+
+func newRoleEventPayload(r *model.Role) roleEventPayload {
+	return roleEventPayload{
+		RoleUUID:    r.RoleUUID,
+		Name:        r.Name,
+		Description: r.Description,
+		IsDefault:   r.IsDefault,
+		IsSystem:    r.IsSystem,
+		Status:      r.Status,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+func newClientEventPayload(c *model.Client) clientEventPayload {
+	return clientEventPayload{
+		ClientUUID:   c.ClientUUID,
+		Name:         c.Name,
+		DisplayName:  c.DisplayName,
+		ClientType:   c.ClientType,
+		Domain:       c.Domain,
+		Identifier:   c.Identifier,
+		IsDefault:    c.IsDefault,
+		IsSystem:     c.IsSystem,
+		Status:       c.Status,
+		DeprecatedAt: c.DeprecatedAt,
+		SunsetAt:     c.SunsetAt,
+		CreatedAt:    c.CreatedAt,
+		UpdatedAt:    c.UpdatedAt,
+	}
+}
+
+func newAPIKeyEventPayload(k *model.APIKey) apiKeyEventPayload {
+	return apiKeyEventPayload{
+		APIKeyUUID:  k.APIKeyUUID,
+		Name:        k.Name,
+		Description: k.Description,
+		KeyPrefix:   k.KeyPrefix,
+		ExpiresAt:   k.ExpiresAt,
+		RateLimit:   k.RateLimit,
+		Status:      k.Status,
+		CreatedAt:   k.CreatedAt,
+		UpdatedAt:   k.UpdatedAt,
+	}
+}
+
+func newGroupEventPayload(g *model.Group) groupEventPayload {
+	return groupEventPayload{
+		GroupUUID:   g.GroupUUID,
+		Name:        g.Name,
+		Description: g.Description,
+		CreatedAt:   g.CreatedAt,
+		UpdatedAt:   g.UpdatedAt,
+	}
+}
+
+// recordEvent appends a domain event to the feed. Callers pass a
+// transaction-bound repository so the event commits or rolls back together
+// with the change it describes; the event relay publishes it to the event bus
+// only after that. The aggregate type is taken from the event type prefix
+// (e.g. "user" for "user.created").
+func recordEvent(eventRepo repository.EventRepository, tenantID int64, event DomainEvent) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return apperror.NewInternal("encode event payload", err)
+	}
+	eventType := event.EventType()
+	aggregateType, _, _ := strings.Cut(eventType, ".")
+
+	_, err = eventRepo.Create(&model.Event{
+		TenantID:      tenantID,
+		EventType:     eventType,
+		SchemaVersion: model.EventSchemaVersion,
+		AggregateType: aggregateType,
+		AggregateUUID: event.AggregateUUID(),
+		Payload:       datatypes.JSON(raw),
+	})
+	return err
+}
+
+// recordUserCreatedEvents emits user.created followed by user.roles_added for
+// the roles the new user starts with.
+func recordUserCreatedEvents(eventRepo repository.EventRepository, tenantID int64, user *model.User, roleUUIDs []uuid.UUID) error {
+	if err := recordEvent(eventRepo, tenantID, UserCreated(newUserEventPayload(user))); err != nil {
+		return err
+	}
+	if len(roleUUIDs) == 0 {
+		return nil
+	}
+	return recordEvent(eventRepo, tenantID, RoleAssigned{
+		UserUUID:  user.UserUUID,
+		RoleUUIDs: roleUUIDs,
+	})
+}
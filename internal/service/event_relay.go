@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/eventbus"
+	"github.com/maintainerd/auth/internal/logging"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+// Event relay names. Each relay keeps its own cursor, so a relay whose
+// subscribers are failing does not hold back the others.
+const (
+	EventRelayBus    = "event_bus"
+	EventRelayStream = "event_stream"
+)
+
+// eventRelayBatchSize bounds how many events are published per run.
+const eventRelayBatchSize = 500
+
+// EventRelayService publishes committed domain events from the events table
+// to an event bus.
+type EventRelayService interface {
+	// Name returns the name the relay's position is stored under.
+	Name() string
+	// Relay publishes the next batch of events and returns how many were
+	// published.
+	Relay(ctx context.Context) (int64, error)
+}
+
+type eventRelayService struct {
+	name       string
+	db         *gorm.DB
+	eventRepo  repository.EventRepository
+	cursorRepo repository.EventRelayCursorRepository
+	bus        eventbus.Bus
+}
+
+// NewEventRelayService creates a new EventRelayService whose position is
+// stored under name.
+func NewEventRelayService(
+	name string,
+	db *gorm.DB,
+	eventRepo repository.EventRepository,
+	cursorRepo repository.EventRelayCursorRepository,
+	bus eventbus.Bus,
+) EventRelayService {
+	return &eventRelayService{
+		name:       name,
+		db:         db,
+		eventRepo:  eventRepo,
+		cursorRepo: cursorRepo,
+		bus:        bus,
+	}
+}
+
+func (s *eventRelayService) Name() string {
+	return s.name
+}
+
+// Relay publishes events past the relay cursor in sequence order and moves
+// the cursor past every event the bus accepted. Publishing stops at the
+// first event a subscriber fails on, so that event and everything after it
+// are published again on the next run. The cursor row stays locked while
+// publishing; another instance finding it locked skips the run. Events
+// younger than the event feed settle window are left for the next run so
+// that late commits are not skipped.
+func (s *eventRelayService) Relay(ctx context.Context) (int64, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "eventRelay.relay")
+	defer span.End()
+	span.SetAttributes(attribute.String("event.relay", s.name))
+
+	before := time.Now().Add(-eventFeedSettleWindow)
+	var published int64
+	var publishErr error
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		cursor, err := s.cursorRepo.WithTx(tx).Lock(s.name)
+		if err != nil || cursor == nil {
+			return err
+		}
+
+		events, err := s.eventRepo.WithTx(tx).FindAfter(cursor.LastEventID, before, eventRelayBatchSize)
+		if err != nil {
+			return err
+		}
+
+		lastEventID := cursor.LastEventID
+		for i := range events {
+			if publishErr = s.bus.Publish(ctx, toBusEvent(&events[i])); publishErr != nil {
+				break
+			}
+			lastEventID = events[i].EventID
+			published++
+		}
+		if lastEventID == cursor.LastEventID {
+			return nil
+		}
+		return s.cursorRepo.WithTx(tx).Advance(cursor.EventRelayCursorID, lastEventID)
+	})
+	span.SetAttributes(attribute.Int64("event.published", published))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "relay events failed")
+		return 0, apperror.NewInternal("failed to relay events", err)
+	}
+	if publishErr != nil {
+		span.RecordError(publishErr)
+		span.SetStatus(codes.Error, "publish event failed")
+		return published, apperror.NewInternal("failed to publish event", publishErr)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return published, nil
+}
+
+func toBusEvent(e *model.Event) eventbus.Event {
+	be := eventbus.Event{
+		ID:            e.EventUUID,
+		Sequence:      e.EventID,
+		TenantID:      e.TenantID,
+		Type:          e.EventType,
+		SchemaVersion: e.SchemaVersion,
+		AggregateType: e.AggregateType,
+		AggregateUUID: e.AggregateUUID,
+		Payload:       json.RawMessage(e.Payload),
+		OccurredAt:    e.OccurredAt,
+	}
+	if e.Tenant != nil {
+		be.TenantUUID = e.Tenant.TenantUUID
+	}
+	return be
+}
+
+// LogDomainEvent is an event bus subscriber that writes every domain event
+// to the events log component, so that changes show up in the server log
+// next to the requests that caused them. The payload is not logged.
+func LogDomainEvent(ctx context.Context, e eventbus.Event) error {
+	logging.Logger(logging.ComponentEvents).LogAttrs(ctx, slog.LevelInfo, "domain event",
+		slog.String("event_id", e.ID.String()),
+		slog.Int64("sequence", e.Sequence),
+		slog.String("event_type", e.Type),
+		slog.Int64("tenant_id", e.TenantID),
+		slog.String("aggregate_type", e.AggregateType),
+		slog.String("aggregate_id", e.AggregateUUID.String()),
+	)
+	return nil
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/eventbus"
+	"github.com/maintainerd/auth/internal/logging"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+var relayTenantUUID = uuid.New()
+
+func relayEvents(ids ...int64) []model.Event {
+	events := make([]model.Event, len(ids))
+	for i, id := range ids {
+		events[i] = model.Event{
+			EventID: id, EventUUID: uuid.New(), TenantID: 1, EventType: model.EventTypeUserCreated,
+			Tenant:        &model.Tenant{TenantID: 1, TenantUUID: relayTenantUUID},
+			SchemaVersion: 1, AggregateType: model.EventAggregateUser, AggregateUUID: uuid.New(),
+			Payload: datatypes.JSON(`{}`), OccurredAt: time.Now(),
+		}
+	}
+	return events
+}
+
+func TestEventRelayService_Relay(t *testing.T) {
+	t.Run("publishes events after the cursor and advances it", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		bus := eventbus.NewInProcess()
+		var got []eventbus.Event
+		bus.Subscribe("test", []string{"*"}, func(_ context.Context, e eventbus.Event) error {
+			got = append(got, e)
+			return nil
+		})
+		var advanced int64
+		svc := NewEventRelayService(EventRelayBus, db,
+			&mockEventRepo{findAfterFn: func(afterID int64, before time.Time, limit int) ([]model.Event, error) {
+				assert.Equal(t, int64(7), afterID)
+				assert.True(t, before.Before(time.Now()))
+				assert.Equal(t, eventRelayBatchSize, limit)
+				return relayEvents(8, 9), nil
+			}},
+			&mockEventRelayCursorRepo{
+				lockFn: func(name string) (*model.EventRelayCursor, error) {
+					assert.Equal(t, EventRelayBus, name)
+					return &model.EventRelayCursor{EventRelayCursorID: 1, Name: name, LastEventID: 7}, nil
+				},
+				advanceFn: func(_, lastEventID int64) error {
+					advanced = lastEventID
+					return nil
+				},
+			},
+			bus,
+		)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		n, err := svc.Relay(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+		assert.Equal(t, int64(9), advanced)
+		require.Len(t, got, 2)
+		assert.Equal(t, int64(8), got[0].Sequence)
+		assert.Equal(t, model.EventTypeUserCreated, got[0].Type)
+		assert.Equal(t, model.EventAggregateUser, got[0].AggregateType)
+		assert.Equal(t, relayTenantUUID, got[0].TenantUUID)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("stops at the first failed event", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		bus := eventbus.NewInProcess()
+		bus.Subscribe("flaky", []string{"*"}, func(_ context.Context, e eventbus.Event) error {
+			if e.Sequence == 9 {
+				return errors.New("endpoint lookup failed")
+			}
+			return nil
+		})
+		var advanced int64
+		svc := NewEventRelayService(EventRelayBus, db,
+			&mockEventRepo{findAfterFn: func(int64, time.Time, int) ([]model.Event, error) {
+				return relayEvents(8, 9, 10), nil
+			}},
+			&mockEventRelayCursorRepo{advanceFn: func(_, lastEventID int64) error {
+				advanced = lastEventID
+				return nil
+			}},
+			bus,
+		)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		n, err := svc.Relay(context.Background())
+		var internalErr *apperror.InternalError
+		require.ErrorAs(t, err, &internalErr)
+		assert.Equal(t, int64(1), n)
+		assert.Equal(t, int64(8), advanced, "the failed event is published again next run")
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("skips when another instance holds the cursor", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		svc := NewEventRelayService(EventRelayBus, db,
+			&mockEventRepo{findAfterFn: func(int64, time.Time, int) ([]model.Event, error) {
+				t.Fatal("events must not be read without the cursor lock")
+				return nil, nil
+			}},
+			&mockEventRelayCursorRepo{lockFn: func(string) (*model.EventRelayCursor, error) { return nil, nil }},
+			eventbus.NewInProcess(),
+		)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		n, err := svc.Relay(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("nothing to publish", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		svc := NewEventRelayService(EventRelayBus, db, &mockEventRepo{},
+			&mockEventRelayCursorRepo{advanceFn: func(int64, int64) error {
+				t.Fatal("the cursor must not move without events")
+				return nil
+			}},
+			eventbus.NewInProcess(),
+		)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		n, err := svc.Relay(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("feed error rolls back", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		svc := NewEventRelayService(EventRelayBus, db,
+			&mockEventRepo{findAfterFn: func(int64, time.Time, int) ([]model.Event, error) {
+				return nil, errors.New("db down")
+			}},
+			&mockEventRelayCursorRepo{},
+			eventbus.NewInProcess(),
+		)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		_, err := svc.Relay(context.Background())
+		var internalErr *apperror.InternalError
+		require.ErrorAs(t, err, &internalErr)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestLogDomainEvent(t *testing.T) {
+	var buf bytes.Buffer
+	logging.Init(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	t.Cleanup(func() { logging.Init(nil) })
+
+	e := eventbus.Event{
+		ID: uuid.New(), Sequence: 42, TenantID: 3, Type: model.EventTypeRoleDeleted,
+		AggregateType: model.EventAggregateRole, AggregateUUID: uuid.New(),
+		Payload: []byte(`{"name":"billing-admin"}`),
+	}
+	require.NoError(t, LogDomainEvent(context.Background(), e))
+
+	out := buf.String()
+	assert.Contains(t, out, `"component":"events"`)
+	assert.Contains(t, out, `"event_type":"role.deleted"`)
+	assert.Contains(t, out, e.ID.String())
+	assert.Contains(t, out, `"sequence":42`)
+	assert.NotContains(t, out, "billing-admin", "payloads are not logged")
+}
@@ -0,0 +1,379 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// Cursor
+// ---------------------------------------------------------------------------
+
+func TestEventCursor_RoundTrip(t *testing.T) {
+	for _, id := range []int64{0, 1, 42, 1 << 40} {
+		got, err := decodeEventCursor(encodeEventCursor(id))
+		require.NoError(t, err)
+		assert.Equal(t, id, got)
+	}
+}
+
+func TestEventCursor_EmptyStartsAtBeginning(t *testing.T) {
+	got, err := decodeEventCursor("")
+	require.NoError(t, err)
+	assert.Zero(t, got)
+}
+
+func TestEventCursor_Invalid(t *testing.T) {
+	enc := base64.RawURLEncoding.EncodeToString
+	for name, cursor := range map[string]string{
+		"not base64":     "!!!",
+		"missing prefix": enc([]byte("42")),
+		"not a number":   enc([]byte(eventCursorPrefix + "abc")),
+		"negative":       enc([]byte(eventCursorPrefix + "-1")),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeEventCursor(cursor)
+			var target *apperror.ValidationError
+			require.ErrorAs(t, err, &target)
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Feed
+// ---------------------------------------------------------------------------
+
+func feedEvents(ids ...int64) []model.Event {
+	out := make([]model.Event, len(ids))
+	for i, id := range ids {
+		out[i] = model.Event{
+			EventID:       id,
+			EventUUID:     uuid.New(),
+			EventType:     model.EventTypeUserCreated,
+			SchemaVersion: model.EventSchemaVersion,
+			AggregateType: model.EventAggregateUser,
+			AggregateUUID: uuid.New(),
+		}
+	}
+	return out
+}
+
+func TestEventService_Feed(t *testing.T) {
+	t.Run("first page with more available", func(t *testing.T) {
+		var got repository.EventRepositoryFeedFilter
+		repo := &mockEventRepo{
+			findFeedFn: func(f repository.EventRepositoryFeedFilter) ([]model.Event, error) {
+				got = f
+				return feedEvents(1, 2, 3), nil
+			},
+		}
+		start := time.Now()
+		res, err := NewEventService(repo).Feed(context.Background(), EventServiceFeedFilter{
+			TenantID: 7,
+			Types:    []string{model.EventTypeUserCreated},
+			Limit:    2,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(7), got.TenantID)
+		assert.Zero(t, got.AfterID)
+		assert.Equal(t, 3, got.Limit, "fetches one extra row to detect more pages")
+		assert.Equal(t, []string{model.EventTypeUserCreated}, got.Types)
+		require.NotNil(t, got.Before)
+		assert.True(t, got.Before.Before(start), "recent events are held back until they settle")
+
+		require.Len(t, res.Data, 2)
+		assert.Equal(t, int64(1), res.Data[0].Sequence)
+		assert.Equal(t, int64(2), res.Data[1].Sequence)
+		assert.True(t, res.HasMore)
+
+		next, err := decodeEventCursor(res.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), next)
+	})
+
+	t.Run("resumes after cursor", func(t *testing.T) {
+		var got repository.EventRepositoryFeedFilter
+		repo := &mockEventRepo{
+			findFeedFn: func(f repository.EventRepositoryFeedFilter) ([]model.Event, error) {
+				got = f
+				return feedEvents(11), nil
+			},
+		}
+		res, err := NewEventService(repo).Feed(context.Background(), EventServiceFeedFilter{
+			TenantID: 1,
+			Cursor:   encodeEventCursor(10),
+			Limit:    5,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), got.AfterID)
+		assert.False(t, res.HasMore)
+		assert.Equal(t, encodeEventCursor(11), res.NextCursor)
+	})
+
+	t.Run("empty page echoes cursor", func(t *testing.T) {
+		cursor := encodeEventCursor(99)
+		res, err := NewEventService(&mockEventRepo{}).Feed(context.Background(), EventServiceFeedFilter{
+			TenantID: 1,
+			Cursor:   cursor,
+			Limit:    5,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, res.Data)
+		assert.False(t, res.HasMore)
+		assert.Equal(t, cursor, res.NextCursor)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		_, err := NewEventService(&mockEventRepo{}).Feed(context.Background(), EventServiceFeedFilter{
+			TenantID: 1,
+			Cursor:   "not-a-cursor",
+			Limit:    5,
+		})
+		var target *apperror.ValidationError
+		require.ErrorAs(t, err, &target)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		repo := &mockEventRepo{
+			findFeedFn: func(repository.EventRepositoryFeedFilter) ([]model.Event, error) {
+				return nil, errors.New("db down")
+			},
+		}
+		_, err := NewEventService(repo).Feed(context.Background(), EventServiceFeedFilter{TenantID: 1, Limit: 5})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "db down")
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Recording
+// ---------------------------------------------------------------------------
+
+func TestRecordEvent(t *testing.T) {
+	t.Run("stores versioned event with aggregate from type", func(t *testing.T) {
+		repo := &mockEventRepo{}
+		password := "secret-hash"
+		u := &model.User{
+			UserUUID: uuid.New(),
+			Username: "alice",
+			Email:    "alice@example.com",
+			Password: &password,
+			Status:   model.StatusActive,
+			Metadata: []byte(`{"plan":"pro"}`),
+		}
+		require.NoError(t, recordEvent(repo, 3, UserUpdated(newUserEventPayload(u))))
+
+		require.Len(t, repo.created, 1)
+		e := repo.created[0]
+		assert.Equal(t, int64(3), e.TenantID)
+		assert.Equal(t, model.EventTypeUserUpdated, e.EventType)
+		assert.Equal(t, model.EventAggregateUser, e.AggregateType)
+		assert.Equal(t, u.UserUUID, e.AggregateUUID)
+		assert.Equal(t, model.EventSchemaVersion, e.SchemaVersion)
+
+		var payload map[string]any
+		require.NoError(t, json.Unmarshal(e.Payload, &payload))
+		assert.Equal(t, "alice", payload["username"])
+		assert.Equal(t, map[string]any{"plan": "pro"}, payload["metadata"])
+		assert.NotContains(t, string(e.Payload), password)
+	})
+
+	t.Run("propagates repository error", func(t *testing.T) {
+		repo := &mockEventRepo{
+			createFn: func(*model.Event) (*model.Event, error) { return nil, errors.New("insert failed") },
+		}
+		err := recordEvent(repo, 1, RoleDeleted{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "insert failed")
+	})
+
+	t.Run("client payload excludes secret", func(t *testing.T) {
+		repo := &mockEventRepo{}
+		secret := "top-secret"
+		c := &model.Client{ClientUUID: uuid.New(), Name: "app", Secret: &secret}
+		require.NoError(t, recordEvent(repo, 1, ClientCreated(newClientEventPayload(c))))
+		require.Len(t, repo.created, 1)
+		assert.Equal(t, model.EventAggregateClient, repo.created[0].AggregateType)
+		assert.NotContains(t, string(repo.created[0].Payload), secret)
+	})
+}
+
+func TestRecordUserCreatedEvents(t *testing.T) {
+	u := &model.User{UserUUID: uuid.New(), Username: "bob"}
+	roleUUID := uuid.New()
+
+	t.Run("with roles", func(t *testing.T) {
+		repo := &mockEventRepo{}
+		require.NoError(t, recordUserCreatedEvents(repo, 1, u, []uuid.UUID{roleUUID}))
+		assert.Equal(t, []string{model.EventTypeUserCreated, model.EventTypeUserRolesAdded}, repo.eventTypes())
+
+		var payload userRolesEventPayload
+		require.NoError(t, json.Unmarshal(repo.created[1].Payload, &payload))
+		assert.Equal(t, []uuid.UUID{roleUUID}, payload.RoleUUIDs)
+	})
+
+	t.Run("without roles", func(t *testing.T) {
+		repo := &mockEventRepo{}
+		require.NoError(t, recordUserCreatedEvents(repo, 1, u, nil))
+		assert.Equal(t, []string{model.EventTypeUserCreated}, repo.eventTypes())
+	})
+
+	t.Run("created event error stops", func(t *testing.T) {
+		repo := &mockEventRepo{
+			createFn: func(*model.Event) (*model.Event, error) { return nil, errors.New("boom") },
+		}
+		require.Error(t, recordUserCreatedEvents(repo, 1, u, []uuid.UUID{roleUUID}))
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Emission from mutating services
+// ---------------------------------------------------------------------------
+
+func TestRoleService_SetDefaultByUUID_RecordsDemotion(t *testing.T) {
+	tenantID := int64(1)
+	db, mock := newMockGormDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	target := newRole(2, "member", tenantID)
+	previous := newRole(1, model.RoleRegistered, tenantID)
+	previous.IsDefault = true
+
+	events := &mockEventRepo{}
+	svc := NewRoleService(db, &mockRoleRepo{
+		findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) { return target, nil },
+		findAllByTenantIDFn: func(int64) ([]model.Role, error) {
+			return []model.Role{*previous, *target}, nil
+		},
+	}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{
+		findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return roleActorUser(tenantID), nil },
+	}, &mockTenantRepo{}, events, cache.NopInvalidator{})
+
+	_, err := svc.SetDefaultByUUID(context.Background(), target.RoleUUID, tenantID, uuid.New())
+	require.NoError(t, err)
+
+	require.Len(t, events.created, 2)
+	assert.Equal(t, previous.RoleUUID, events.created[0].AggregateUUID)
+	assert.Equal(t, target.RoleUUID, events.created[1].AggregateUUID)
+
+	var demoted, promoted roleEventPayload
+	require.NoError(t, json.Unmarshal(events.created[0].Payload, &demoted))
+	require.NoError(t, json.Unmarshal(events.created[1].Payload, &promoted))
+	assert.False(t, demoted.IsDefault)
+	assert.True(t, promoted.IsDefault)
+}
+
+func TestRoleService_AddRolePermissions_RecordsOnlyNewAssociations(t *testing.T) {
+	tenantID := int64(1)
+	newPerm := model.Permission{PermissionID: 10, PermissionUUID: uuid.New()}
+	existingPerm := model.Permission{PermissionID: 11, PermissionUUID: uuid.New()}
+
+	run := func(t *testing.T, perms []model.Permission) *mockEventRepo {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		events := &mockEventRepo{}
+		svc := NewRoleService(db, &mockRoleRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) { return newRole(1, "admin", tenantID), nil },
+		}, &mockPermissionRepo{
+			findByUUIDsFn: func(_ []string, _ ...string) ([]model.Permission, error) { return perms, nil },
+		}, &mockRolePermissionRepo{
+			findByRoleAndPermissionFn: func(_ int64, permissionID int64) (*model.RolePermission, error) {
+				if permissionID == existingPerm.PermissionID {
+					return &model.RolePermission{}, nil
+				}
+				return nil, nil
+			},
+		}, &mockUserRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return roleActorUser(tenantID), nil },
+		}, &mockTenantRepo{}, events, cache.NopInvalidator{})
+
+		ids := make([]uuid.UUID, len(perms))
+		for i, p := range perms {
+			ids[i] = p.PermissionUUID
+		}
+		_, err := svc.AddRolePermissions(context.Background(), uuid.New(), tenantID, ids, uuid.New())
+		require.NoError(t, err)
+		return events
+	}
+
+	t.Run("mixed", func(t *testing.T) {
+		events := run(t, []model.Permission{newPerm, existingPerm})
+		require.Len(t, events.created, 1)
+		assert.Equal(t, model.EventTypeRolePermissionsAdded, events.created[0].EventType)
+
+		var payload rolePermissionsEventPayload
+		require.NoError(t, json.Unmarshal(events.created[0].Payload, &payload))
+		assert.Equal(t, []uuid.UUID{newPerm.PermissionUUID}, payload.PermissionUUIDs)
+	})
+
+	t.Run("nothing new", func(t *testing.T) {
+		events := run(t, []model.Permission{existingPerm})
+		assert.Empty(t, events.created)
+	})
+}
+
+func TestClientService_SetDefaultByUUID_RecordsDemotion(t *testing.T) {
+	tenantID := int64(1)
+	db, mock := newMockGormDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	target := clientWithIDP(tenantID)
+	previous := clientWithIDP(tenantID)
+	previous.ClientID = 2
+	previous.IsDefault = true
+
+	events := &mockEventRepo{}
+	svc := NewClientService(db, &mockClientRepo{
+		findByUUIDFn:            func(_ any, _ ...string) (*model.Client, error) { return target, nil },
+		findDefaultByTenantIDFn: func(int64) (*model.Client, error) { return previous, nil },
+	}, &mockClientURIRepo{}, &mockIdentityProviderRepo{},
+		&mockPermissionRepo{}, &mockClientPermissionRepo{}, &mockClientAPIRepo{},
+		&mockAPIRepo{}, &mockUserRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return actorUser(tenantID), nil },
+		}, &mockTenantRepo{}, events, &mockAuthEventRepo{}, NopPlanEnforcer{})
+
+	_, err := svc.SetDefaultByUUID(context.Background(), target.ClientUUID, tenantID, uuid.New())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{model.EventTypeClientUpdated, model.EventTypeClientUpdated}, events.eventTypes())
+	assert.Equal(t, previous.ClientUUID, events.created[0].AggregateUUID)
+	assert.Equal(t, target.ClientUUID, events.created[1].AggregateUUID)
+}
+
+func TestClientService_DeleteByUUID_EventErrorRollsBack(t *testing.T) {
+	tenantID := int64(1)
+	db, mock := newMockGormDB(t)
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	svc := NewClientService(db, &mockClientRepo{
+		findByUUIDFn: func(_ any, _ ...string) (*model.Client, error) { return clientWithIDP(tenantID), nil },
+	}, &mockClientURIRepo{}, &mockIdentityProviderRepo{},
+		&mockPermissionRepo{}, &mockClientPermissionRepo{}, &mockClientAPIRepo{},
+		&mockAPIRepo{}, &mockUserRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return actorUser(tenantID), nil },
+		}, &mockTenantRepo{}, &mockEventRepo{
+			createFn: func(*model.Event) (*model.Event, error) { return nil, errors.New("event failed") },
+		}, &mockAuthEventRepo{}, NopPlanEnforcer{})
+
+	_, err := svc.DeleteByUUID(context.Background(), uuid.New(), tenantID, uuid.New())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "event failed")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
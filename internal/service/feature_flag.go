@@ -0,0 +1,428 @@
+package service
+
+import (
+	"context"
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+)
+
+// FeatureFlagServiceInput is the content of a feature flag being created or
+// updated. The key of a flag cannot change.
+type FeatureFlagServiceInput struct {
+	Description       string
+	Enabled           bool
+	RolloutPercentage int
+}
+
+// FeatureFlagServiceOverride is the state of a feature flag for one tenant.
+type FeatureFlagServiceOverride struct {
+	TenantUUID uuid.UUID
+	TenantName string
+	Enabled    bool
+}
+
+// FeatureFlagServiceDataResult is the service-layer representation of a
+// feature flag.
+type FeatureFlagServiceDataResult struct {
+	FeatureFlagUUID   uuid.UUID
+	Key               string
+	Description       string
+	Enabled           bool
+	RolloutPercentage int
+	Overrides         []FeatureFlagServiceOverride
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// FeatureFlagService manages the feature flags that gate internal features
+// and evaluates them.
+type FeatureFlagService interface {
+	// IsEnabled reports whether the flag is on for the tenant: the
+	// tenant's override if it has one, otherwise whether the flag is
+	// enabled and the tenant falls within its rollout percentage. A
+	// tenantID of 0 checks the flag outside any tenant, where only enabled
+	// counts. Unknown flags, and flags that cannot be loaded, are off.
+	IsEnabled(ctx context.Context, key string, tenantID int64) bool
+	List(ctx context.Context) ([]FeatureFlagServiceDataResult, error)
+	GetByUUID(ctx context.Context, flagUUID uuid.UUID) (*FeatureFlagServiceDataResult, error)
+	Create(ctx context.Context, key string, input FeatureFlagServiceInput) (*FeatureFlagServiceDataResult, error)
+	Update(ctx context.Context, flagUUID uuid.UUID, input FeatureFlagServiceInput) (*FeatureFlagServiceDataResult, error)
+	Delete(ctx context.Context, flagUUID uuid.UUID) (*FeatureFlagServiceDataResult, error)
+	// SetOverride turns the flag on or off for the tenant, regardless of
+	// its rollout.
+	SetOverride(ctx context.Context, flagUUID uuid.UUID, tenantUUID uuid.UUID, enabled bool) (*FeatureFlagServiceDataResult, error)
+	// DeleteOverride returns the tenant to the flag's rollout.
+	DeleteOverride(ctx context.Context, flagUUID uuid.UUID, tenantUUID uuid.UUID) (*FeatureFlagServiceDataResult, error)
+}
+
+type featureFlagService struct {
+	db              *gorm.DB
+	featureFlagRepo repository.FeatureFlagRepository
+	tenantRepo      repository.TenantRepository
+	store           cache.FeatureFlagStore
+}
+
+// NewFeatureFlagService creates a new FeatureFlagService. Flags are cached
+// in store and invalidated by every change made through the service.
+func NewFeatureFlagService(
+	db *gorm.DB,
+	featureFlagRepo repository.FeatureFlagRepository,
+	tenantRepo repository.TenantRepository,
+	store cache.FeatureFlagStore,
+) FeatureFlagService {
+	return &featureFlagService{
+		db:              db,
+		featureFlagRepo: featureFlagRepo,
+		tenantRepo:      tenantRepo,
+		store:           store,
+	}
+}
+
+func (s *featureFlagService) IsEnabled(ctx context.Context, key string, tenantID int64) bool {
+	ctx, span := otel.Tracer("service").Start(ctx, "featureFlag.isEnabled")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("feature_flag.key", key),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	flag := s.store.GetFeatureFlag(ctx, key)
+	if flag == nil {
+		found, err := s.featureFlagRepo.FindByKey(key)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "find feature flag failed")
+			return false
+		}
+		flag = toCachedFeatureFlag(found)
+		s.store.SetFeatureFlag(ctx, key, flag)
+	}
+
+	enabled := evaluateFeatureFlag(key, flag, tenantID)
+	span.SetAttributes(attribute.Bool("feature_flag.enabled", enabled))
+	span.SetStatus(codes.Ok, "")
+	return enabled
+}
+
+func (s *featureFlagService) List(ctx context.Context) ([]FeatureFlagServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "featureFlag.list")
+	defer span.End()
+
+	flags, err := s.featureFlagRepo.FindAllOrdered()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list feature flags failed")
+		return nil, apperror.NewInternal("failed to list feature flags", err)
+	}
+
+	results := make([]FeatureFlagServiceDataResult, len(flags))
+	for i := range flags {
+		results[i] = toFeatureFlagServiceDataResult(&flags[i])
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return results, nil
+}
+
+func (s *featureFlagService) GetByUUID(ctx context.Context, flagUUID uuid.UUID) (*FeatureFlagServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "featureFlag.getByUUID")
+	defer span.End()
+	span.SetAttributes(attribute.String("feature_flag.uuid", flagUUID.String()))
+
+	flag, err := s.findFeatureFlag(flagUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find feature flag failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	result := toFeatureFlagServiceDataResult(flag)
+	return &result, nil
+}
+
+func (s *featureFlagService) Create(ctx context.Context, key string, input FeatureFlagServiceInput) (*FeatureFlagServiceDataResult, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "featureFlag.create")
+	defer span.End()
+	span.SetAttributes(attribute.String("feature_flag.key", key))
+
+	var created *model.FeatureFlag
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txFeatureFlagRepo := s.featureFlagRepo.WithTx(tx)
+
+		existing, err := txFeatureFlagRepo.FindByKey(key)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return apperror.NewConflict("feature flag " + key + " already exists")
+		}
+
+		created, err = txFeatureFlagRepo.Create(&model.FeatureFlag{
+			Key:               key,
+			Description:       input.Description,
+			Enabled:           input.Enabled,
+			RolloutPercentage: input.RolloutPercentage,
+		})
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "create feature flag failed")
+		return nil, err
+	}
+
+	// An unknown key may have been cached as off.
+	s.store.InvalidateFeatureFlag(ctx, key)
+
+	span.SetStatus(codes.Ok, "")
+	result := toFeatureFlagServiceDataResult(created)
+	return &result, nil
+}
+
+func (s *featureFlagService) Update(ctx context.Context, flagUUID uuid.UUID, input FeatureFlagServiceInput) (*FeatureFlagServiceDataResult, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "featureFlag.update")
+	defer span.End()
+	span.SetAttributes(attribute.String("feature_flag.uuid", flagUUID.String()))
+
+	flag, err := s.findFeatureFlag(flagUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find feature flag failed")
+		return nil, err
+	}
+
+	// A map, so that a disabled flag and a 0% rollout are written too.
+	if _, err := s.featureFlagRepo.UpdateByUUID(flagUUID, map[string]any{
+		"description":        input.Description,
+		"enabled":            input.Enabled,
+		"rollout_percentage": input.RolloutPercentage,
+	}); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "update feature flag failed")
+		return nil, apperror.NewInternal("failed to update feature flag", err)
+	}
+	s.store.InvalidateFeatureFlag(ctx, flag.Key)
+
+	result, err := s.reload(flagUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "reload feature flag failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+func (s *featureFlagService) Delete(ctx context.Context, flagUUID uuid.UUID) (*FeatureFlagServiceDataResult, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "featureFlag.delete")
+	defer span.End()
+	span.SetAttributes(attribute.String("feature_flag.uuid", flagUUID.String()))
+
+	flag, err := s.findFeatureFlag(flagUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find feature flag failed")
+		return nil, err
+	}
+
+	// Overrides are removed with the flag (ON DELETE CASCADE).
+	if err := s.featureFlagRepo.DeleteByUUID(flagUUID); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete feature flag failed")
+		return nil, apperror.NewInternal("failed to delete feature flag", err)
+	}
+	s.store.InvalidateFeatureFlag(ctx, flag.Key)
+
+	span.SetStatus(codes.Ok, "")
+	result := toFeatureFlagServiceDataResult(flag)
+	return &result, nil
+}
+
+func (s *featureFlagService) SetOverride(ctx context.Context, flagUUID uuid.UUID, tenantUUID uuid.UUID, enabled bool) (*FeatureFlagServiceDataResult, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "featureFlag.setOverride")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("feature_flag.uuid", flagUUID.String()),
+		attribute.String("tenant.uuid", tenantUUID.String()),
+		attribute.Bool("feature_flag.enabled", enabled),
+	)
+
+	flag, tenant, err := s.findFlagAndTenant(flagUUID, tenantUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find feature flag failed")
+		return nil, err
+	}
+
+	if err := s.featureFlagRepo.SetOverride(flag.FeatureFlagID, tenant.TenantID, enabled); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "set override failed")
+		return nil, apperror.NewInternal("failed to set feature flag override", err)
+	}
+	s.store.InvalidateFeatureFlag(ctx, flag.Key)
+
+	result, err := s.reload(flagUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "reload feature flag failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+func (s *featureFlagService) DeleteOverride(ctx context.Context, flagUUID uuid.UUID, tenantUUID uuid.UUID) (*FeatureFlagServiceDataResult, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "featureFlag.deleteOverride")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("feature_flag.uuid", flagUUID.String()),
+		attribute.String("tenant.uuid", tenantUUID.String()),
+	)
+
+	flag, tenant, err := s.findFlagAndTenant(flagUUID, tenantUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find feature flag failed")
+		return nil, err
+	}
+
+	deleted, err := s.featureFlagRepo.DeleteOverride(flag.FeatureFlagID, tenant.TenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete override failed")
+		return nil, apperror.NewInternal("failed to delete feature flag override", err)
+	}
+	if !deleted {
+		span.SetStatus(codes.Error, "override not found")
+		return nil, apperror.NewNotFoundWithReason("tenant has no override of the feature flag")
+	}
+	s.store.InvalidateFeatureFlag(ctx, flag.Key)
+
+	result, err := s.reload(flagUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "reload feature flag failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// findFeatureFlag returns the flag with its overrides and their tenants,
+// failing with a not found error when there is none.
+func (s *featureFlagService) findFeatureFlag(flagUUID uuid.UUID) (*model.FeatureFlag, error) {
+	flag, err := s.featureFlagRepo.FindByUUID(flagUUID, "Overrides.Tenant")
+	if err != nil {
+		return nil, apperror.NewInternal("failed to find feature flag", err)
+	}
+	if flag == nil {
+		return nil, apperror.NewNotFoundWithReason("feature flag not found")
+	}
+	return flag, nil
+}
+
+func (s *featureFlagService) findFlagAndTenant(flagUUID uuid.UUID, tenantUUID uuid.UUID) (*model.FeatureFlag, *model.Tenant, error) {
+	flag, err := s.findFeatureFlag(flagUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+	tenant, err := s.tenantRepo.FindByUUID(tenantUUID)
+	if err != nil {
+		return nil, nil, apperror.NewInternal("failed to find tenant", err)
+	}
+	if tenant == nil {
+		return nil, nil, apperror.NewNotFound("tenant")
+	}
+	return flag, tenant, nil
+}
+
+func (s *featureFlagService) reload(flagUUID uuid.UUID) (*FeatureFlagServiceDataResult, error) {
+	flag, err := s.findFeatureFlag(flagUUID)
+	if err != nil {
+		return nil, err
+	}
+	result := toFeatureFlagServiceDataResult(flag)
+	return &result, nil
+}
+
+// evaluateFeatureFlag decides a cached flag for the tenant.
+func evaluateFeatureFlag(key string, flag *cache.FeatureFlag, tenantID int64) bool {
+	if !flag.Exists {
+		return false
+	}
+	if enabled, ok := flag.Overrides[tenantID]; ok {
+		return enabled
+	}
+	if !flag.Enabled {
+		return false
+	}
+	if tenantID == 0 {
+		return true
+	}
+	return featureFlagBucket(key, tenantID) < flag.RolloutPercentage
+}
+
+// featureFlagBucket places the tenant in one of 100 buckets. The bucket is
+// stable, so raising the rollout percentage only ever adds tenants, and it
+// differs per key, so the same tenants are not always first.
+func featureFlagBucket(key string, tenantID int64) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key + ":" + strconv.FormatInt(tenantID, 10)))
+	return int(h.Sum32() % 100)
+}
+
+func toCachedFeatureFlag(flag *model.FeatureFlag) *cache.FeatureFlag {
+	if flag == nil {
+		return &cache.FeatureFlag{}
+	}
+	cached := &cache.FeatureFlag{
+		Exists:            true,
+		Enabled:           flag.Enabled,
+		RolloutPercentage: flag.RolloutPercentage,
+	}
+	if len(flag.Overrides) > 0 {
+		cached.Overrides = make(map[int64]bool, len(flag.Overrides))
+		for _, o := range flag.Overrides {
+			cached.Overrides[o.TenantID] = o.Enabled
+		}
+	}
+	return cached
+}
+
+func toFeatureFlagServiceDataResult(flag *model.FeatureFlag) FeatureFlagServiceDataResult {
+	overrides := make([]FeatureFlagServiceOverride, 0, len(flag.Overrides))
+	for _, o := range flag.Overrides {
+		override := FeatureFlagServiceOverride{Enabled: o.Enabled}
+		if o.Tenant != nil {
+			override.TenantUUID = o.Tenant.TenantUUID
+			override.TenantName = o.Tenant.Name
+		}
+		overrides = append(overrides, override)
+	}
+	return FeatureFlagServiceDataResult{
+		FeatureFlagUUID:   flag.FeatureFlagUUID,
+		Key:               flag.Key,
+		Description:       flag.Description,
+		Enabled:           flag.Enabled,
+		RolloutPercentage: flag.RolloutPercentage,
+		Overrides:         overrides,
+		CreatedAt:         flag.CreatedAt,
+		UpdatedAt:         flag.UpdatedAt,
+	}
+}
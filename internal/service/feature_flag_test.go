@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFeatureFlagStore is an in-memory cache.FeatureFlagStore.
+type fakeFeatureFlagStore struct {
+	flags map[string]*cache.FeatureFlag
+}
+
+func newFakeFeatureFlagStore() *fakeFeatureFlagStore {
+	return &fakeFeatureFlagStore{flags: map[string]*cache.FeatureFlag{}}
+}
+
+func (f *fakeFeatureFlagStore) GetFeatureFlag(_ context.Context, key string) *cache.FeatureFlag {
+	return f.flags[key]
+}
+
+func (f *fakeFeatureFlagStore) SetFeatureFlag(_ context.Context, key string, flag *cache.FeatureFlag) {
+	f.flags[key] = flag
+}
+
+func (f *fakeFeatureFlagStore) InvalidateFeatureFlag(_ context.Context, key string) {
+	delete(f.flags, key)
+}
+
+func TestFeatureFlagService_IsEnabled(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("loads once and caches", func(t *testing.T) {
+		var loads int
+		repo := &mockFeatureFlagRepo{findByKeyFn: func(string) (*model.FeatureFlag, error) {
+			loads++
+			return &model.FeatureFlag{Key: "new-flow", Enabled: true, RolloutPercentage: 100}, nil
+		}}
+		store := newFakeFeatureFlagStore()
+		svc := NewFeatureFlagService(nil, repo, &mockTenantRepo{}, store)
+
+		assert.True(t, svc.IsEnabled(ctx, "new-flow", 7))
+		assert.True(t, svc.IsEnabled(ctx, "new-flow", 8))
+		assert.Equal(t, 1, loads)
+		assert.True(t, store.flags["new-flow"].Exists)
+	})
+
+	t.Run("unknown flag is off and cached", func(t *testing.T) {
+		store := newFakeFeatureFlagStore()
+		svc := NewFeatureFlagService(nil, &mockFeatureFlagRepo{}, &mockTenantRepo{}, store)
+
+		assert.False(t, svc.IsEnabled(ctx, "missing", 7))
+		require.NotNil(t, store.flags["missing"])
+		assert.False(t, store.flags["missing"].Exists)
+	})
+
+	t.Run("load error is off and not cached", func(t *testing.T) {
+		repo := &mockFeatureFlagRepo{findByKeyFn: func(string) (*model.FeatureFlag, error) { return nil, errors.New("db down") }}
+		store := newFakeFeatureFlagStore()
+		svc := NewFeatureFlagService(nil, repo, &mockTenantRepo{}, store)
+
+		assert.False(t, svc.IsEnabled(ctx, "new-flow", 7))
+		assert.Empty(t, store.flags)
+	})
+
+	t.Run("override wins over the rollout", func(t *testing.T) {
+		store := newFakeFeatureFlagStore()
+		store.flags["new-flow"] = &cache.FeatureFlag{Exists: true, Enabled: false, Overrides: map[int64]bool{7: true, 8: false}}
+		svc := NewFeatureFlagService(nil, &mockFeatureFlagRepo{}, &mockTenantRepo{}, store)
+
+		assert.True(t, svc.IsEnabled(ctx, "new-flow", 7))
+		assert.False(t, svc.IsEnabled(ctx, "new-flow", 8))
+		assert.False(t, svc.IsEnabled(ctx, "new-flow", 9))
+	})
+
+	t.Run("outside a tenant only enabled counts", func(t *testing.T) {
+		store := newFakeFeatureFlagStore()
+		store.flags["new-flow"] = &cache.FeatureFlag{Exists: true, Enabled: true, RolloutPercentage: 0}
+		svc := NewFeatureFlagService(nil, &mockFeatureFlagRepo{}, &mockTenantRepo{}, store)
+
+		assert.True(t, svc.IsEnabled(ctx, "new-flow", 0))
+		assert.False(t, svc.IsEnabled(ctx, "new-flow", 7))
+	})
+}
+
+func TestEvaluateFeatureFlag_Rollout(t *testing.T) {
+	half := &cache.FeatureFlag{Exists: true, Enabled: true, RolloutPercentage: 50}
+	more := &cache.FeatureFlag{Exists: true, Enabled: true, RolloutPercentage: 80}
+
+	var on int
+	for tenantID := int64(1); tenantID <= 1000; tenantID++ {
+		enabled := evaluateFeatureFlag("new-flow", half, tenantID)
+		assert.Equal(t, enabled, evaluateFeatureFlag("new-flow", half, tenantID), "stable")
+		if enabled {
+			on++
+			assert.True(t, evaluateFeatureFlag("new-flow", more, tenantID), "raising the rollout keeps tenants")
+		}
+	}
+	assert.InDelta(t, 500, on, 100)
+}
+
+func TestFeatureFlagService_Create(t *testing.T) {
+	t.Run("invalidates a cached unknown key", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		store := newFakeFeatureFlagStore()
+		store.flags["new-flow"] = &cache.FeatureFlag{}
+
+		result, err := NewFeatureFlagService(db, &mockFeatureFlagRepo{}, &mockTenantRepo{}, store).
+			Create(context.Background(), "new-flow", FeatureFlagServiceInput{Enabled: true, RolloutPercentage: 10})
+		require.NoError(t, err)
+		assert.Equal(t, "new-flow", result.Key)
+		assert.Equal(t, 10, result.RolloutPercentage)
+		assert.Empty(t, store.flags)
+	})
+
+	t.Run("duplicate key", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		repo := &mockFeatureFlagRepo{findByKeyFn: func(string) (*model.FeatureFlag, error) { return &model.FeatureFlag{}, nil }}
+
+		_, err := NewFeatureFlagService(db, repo, &mockTenantRepo{}, newFakeFeatureFlagStore()).
+			Create(context.Background(), "new-flow", FeatureFlagServiceInput{})
+		var conflict *apperror.ConflictError
+		assert.ErrorAs(t, err, &conflict)
+	})
+}
+
+func TestFeatureFlagService_Update(t *testing.T) {
+	flagUUID := uuid.New()
+	repo := &mockFeatureFlagRepo{
+		findByUUIDFn: func(any, ...string) (*model.FeatureFlag, error) {
+			return &model.FeatureFlag{FeatureFlagUUID: flagUUID, Key: "new-flow"}, nil
+		},
+		updateByUUIDFn: func(_, data any) (*model.FeatureFlag, error) {
+			assert.Equal(t, 0, data.(map[string]any)["rollout_percentage"])
+			return nil, nil
+		},
+	}
+	store := newFakeFeatureFlagStore()
+	store.flags["new-flow"] = &cache.FeatureFlag{Exists: true, Enabled: true, RolloutPercentage: 100}
+
+	_, err := NewFeatureFlagService(nil, repo, &mockTenantRepo{}, store).
+		Update(context.Background(), flagUUID, FeatureFlagServiceInput{Enabled: true})
+	require.NoError(t, err)
+	assert.Empty(t, store.flags)
+}
+
+func TestFeatureFlagService_Overrides(t *testing.T) {
+	flagUUID, tenantUUID := uuid.New(), uuid.New()
+	flag := &model.FeatureFlag{FeatureFlagID: 3, FeatureFlagUUID: flagUUID, Key: "new-flow"}
+	tenants := &mockTenantRepo{findByUUIDFn: func(any, ...string) (*model.Tenant, error) {
+		return &model.Tenant{TenantID: 7, TenantUUID: tenantUUID}, nil
+	}}
+
+	t.Run("set", func(t *testing.T) {
+		var set bool
+		repo := &mockFeatureFlagRepo{
+			findByUUIDFn: func(any, ...string) (*model.FeatureFlag, error) { return flag, nil },
+			setOverrideFn: func(flagID, tenantID int64, enabled bool) error {
+				assert.Equal(t, int64(3), flagID)
+				assert.Equal(t, int64(7), tenantID)
+				set = enabled
+				return nil
+			},
+		}
+		_, err := NewFeatureFlagService(nil, repo, tenants, newFakeFeatureFlagStore()).
+			SetOverride(context.Background(), flagUUID, tenantUUID, true)
+		require.NoError(t, err)
+		assert.True(t, set)
+	})
+
+	t.Run("delete missing override", func(t *testing.T) {
+		repo := &mockFeatureFlagRepo{
+			findByUUIDFn:     func(any, ...string) (*model.FeatureFlag, error) { return flag, nil },
+			deleteOverrideFn: func(int64, int64) (bool, error) { return false, nil },
+		}
+		_, err := NewFeatureFlagService(nil, repo, tenants, newFakeFeatureFlagStore()).
+			DeleteOverride(context.Background(), flagUUID, tenantUUID)
+		var notFound *apperror.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("unknown tenant", func(t *testing.T) {
+		repo := &mockFeatureFlagRepo{findByUUIDFn: func(any, ...string) (*model.FeatureFlag, error) { return flag, nil }}
+		_, err := NewFeatureFlagService(nil, repo, &mockTenantRepo{}, newFakeFeatureFlagStore()).
+			SetOverride(context.Background(), flagUUID, tenantUUID, true)
+		var notFound *apperror.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+}
@@ -0,0 +1,395 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/claims"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/federation"
+	"github.com/maintainerd/auth/internal/metrics"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// FederatedLoginService signs users in with an ID token of an upstream
+// OpenID Connect provider, provisioning them just in time by the rules of
+// the identity provider.
+type FederatedLoginService interface {
+	// Login exchanges rawIDToken, issued by the upstream provider of the
+	// auth client identified by clientID and providerID, for a session.
+	Login(ctx context.Context, rawIDToken, clientID, providerID string) (*dto.LoginResponseDTO, error)
+}
+
+type federatedLoginService struct {
+	db                  *gorm.DB
+	clientRepo          repository.ClientRepository
+	userRepo            repository.UserRepository
+	userIdentityRepo    repository.UserIdentityRepository
+	roleRepo            repository.RoleRepository
+	userRoleRepo        repository.UserRoleRepository
+	eventRepo           repository.EventRepository
+	verifier            federation.IDTokenVerifier
+	authEventService    AuthEventService
+	loginHookService    LoginHookService
+	claimsFetcher       claims.Fetcher
+	loginAnomalyService LoginAnomalyService
+}
+
+// NewFederatedLoginService creates a new FederatedLoginService. The login
+// hook, claims and anomaly dependencies are optional, as for LoginService.
+func NewFederatedLoginService(
+	db *gorm.DB,
+	clientRepo repository.ClientRepository,
+	userRepo repository.UserRepository,
+	userIdentityRepo repository.UserIdentityRepository,
+	roleRepo repository.RoleRepository,
+	userRoleRepo repository.UserRoleRepository,
+	eventRepo repository.EventRepository,
+	verifier federation.IDTokenVerifier,
+	authEventService AuthEventService,
+	loginHookService LoginHookService,
+	claimsFetcher claims.Fetcher,
+	loginAnomalyService LoginAnomalyService,
+) FederatedLoginService {
+	return &federatedLoginService{
+		db:                  db,
+		clientRepo:          clientRepo,
+		userRepo:            userRepo,
+		userIdentityRepo:    userIdentityRepo,
+		roleRepo:            roleRepo,
+		userRoleRepo:        userRoleRepo,
+		eventRepo:           eventRepo,
+		verifier:            verifier,
+		authEventService:    authEventService,
+		loginHookService:    loginHookService,
+		claimsFetcher:       claimsFetcher,
+		loginAnomalyService: loginAnomalyService,
+	}
+}
+
+// federatedUser is the local account an upstream identity resolved to.
+type federatedUser struct {
+	user *model.User
+	// created and linked report what provisioning did on this sign in.
+	created bool
+	linked  bool
+}
+
+// Login implements FederatedLoginService.
+func (s *federatedLoginService) Login(ctx context.Context, rawIDToken, clientID, providerID string) (result *dto.LoginResponseDTO, err error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "federated_login.login")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "federated login failed")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		metrics.ObserveLogin(err == nil)
+		span.End()
+	}()
+	span.SetAttributes(attribute.String("client.id", clientID), attribute.String("idp.identifier", providerID))
+
+	client, err := s.clientRepo.FindByClientIDAndIdentityProvider(clientID, providerID)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to load auth client", err)
+	}
+	if client == nil || client.Status != model.StatusActive ||
+		client.Domain == nil || *client.Domain == "" ||
+		client.IdentityProvider == nil || client.IdentityProvider.Status != model.StatusActive ||
+		client.IdentityProvider.Provider == model.IDPProviderInternal {
+		return nil, apperror.NewUnauthorized("authentication failed")
+	}
+	idp := client.IdentityProvider
+
+	provider, err := federation.ProviderFromConfig(idp.Config, ptr.Deref(client.Identifier))
+	if err != nil {
+		return nil, apperror.NewUnauthorized("authentication failed")
+	}
+	rules, err := federation.RulesFromConfig(idp.Config)
+	if err != nil {
+		return nil, apperror.NewInternal("invalid provisioning rules", err)
+	}
+
+	identity, err := s.verifier.Verify(ctx, *provider, rawIDToken)
+	if err != nil {
+		if errors.Is(err, federation.ErrInvalidToken) {
+			s.logEvent(ctx, idp.TenantID, nil, model.AuthEventCategoryAuthn, model.AuthEventTypeLoginFail,
+				model.AuthEventResultFailure, "Invalid upstream ID token")
+			return nil, apperror.WithCode(apperror.CodeInvalidCredentials, apperror.NewUnauthorized(msgInvalidCredentials))
+		}
+		return nil, apperror.NewInternal("failed to verify ID token", err)
+	}
+
+	attrs, err := rules.Evaluate(identity)
+	if err != nil {
+		s.logEvent(ctx, idp.TenantID, nil, model.AuthEventCategoryAuthn, model.AuthEventTypeLoginFail,
+			model.AuthEventResultFailure, "Rejected by provisioning rules: "+err.Error())
+		return nil, apperror.NewForbidden(err.Error())
+	}
+
+	var resolved *federatedUser
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var txErr error
+		resolved, txErr = s.resolveUser(tx, client, rules, identity, attrs)
+		return txErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	user := resolved.user
+	span.SetAttributes(attribute.String("user.uuid", user.UserUUID.String()))
+
+	if resolved.created {
+		s.logEvent(ctx, idp.TenantID, &user.UserID, model.AuthEventCategoryUser, model.AuthEventTypeUserCreated,
+			model.AuthEventResultSuccess, "User provisioned from "+idp.Provider)
+	}
+	if resolved.linked {
+		s.logEvent(ctx, idp.TenantID, &user.UserID, model.AuthEventCategoryUser, model.AuthEventTypeUserIdentityLinked,
+			model.AuthEventResultSuccess, "External identity linked on sign in: "+idp.Provider)
+	}
+
+	if user.Status != model.StatusActive {
+		s.logEvent(ctx, idp.TenantID, &user.UserID, model.AuthEventCategoryAuthn, model.AuthEventTypeLoginFail,
+			model.AuthEventResultFailure, "Attempt to login with inactive account")
+		return nil, inactiveAccountError(user)
+	}
+
+	// Tenant pre-login hooks may deny, post-login hooks may add claims
+	hookClaims, err := runLoginHooks(ctx, s.loginHookService, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// Claims from the client's external source; hook claims take precedence
+	extraClaims, err := enrichTokenClaims(ctx, s.claimsFetcher, client, identity.Subject, user, loginTokenScope)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to fetch token claims", err)
+	}
+
+	s.logEvent(ctx, idp.TenantID, &user.UserID, model.AuthEventCategoryAuthn, model.AuthEventTypeLoginSuccess,
+		model.AuthEventResultSuccess, fmt.Sprintf("Successful %s login for user %s", idp.Provider, user.Username))
+
+	// Flag new devices and impossible travel
+	anomaly := checkLoginAnomalies(ctx, s.loginAnomalyService, user, idp.TenantID)
+	if anomaly.SecurityHold {
+		return nil, errSecurityHold
+	}
+
+	result, err = generateLoginTokenResponse(ctx, identity.Subject, user, client, mergeClaims(extraClaims, hookClaims))
+	if err != nil {
+		return nil, err
+	}
+	result.StepUpRequired = anomaly.StepUpRequired
+	return result, nil
+}
+
+// resolveUser finds the local account of an upstream identity, linking or
+// creating it as the rules allow, and grants the roles its groups map to.
+func (s *federatedLoginService) resolveUser(
+	tx *gorm.DB,
+	client *model.Client,
+	rules *federation.ProvisioningRules,
+	identity *federation.Identity,
+	attrs *federation.Attributes,
+) (*federatedUser, error) {
+	txUserRepo := s.userRepo.WithTx(tx)
+	txUserIdentityRepo := s.userIdentityRepo.WithTx(tx)
+	tenantID := client.IdentityProvider.TenantID
+	resolved := &federatedUser{}
+
+	existing, err := txUserIdentityRepo.FindByClientIDAndSub(client.ClientID, identity.Subject)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to look up identity", err)
+	}
+	if existing != nil {
+		resolved.user, err = txUserRepo.FindByID(existing.UserID)
+		if err != nil {
+			return nil, apperror.NewInternal("failed to load user", err)
+		}
+		if resolved.user == nil || resolved.user.DeletedAt != nil {
+			return nil, apperror.WithCode(apperror.CodeInvalidCredentials, apperror.NewUnauthorized(msgInvalidCredentials))
+		}
+	} else {
+		// An account is only claimed through an email the provider vouches for
+		resolved.user, err = txUserRepo.FindByEmailAndTenantID(attrs.Email, tenantID)
+		if err != nil {
+			return nil, apperror.NewInternal("failed to look up user", err)
+		}
+		if resolved.user != nil && resolved.user.DeletedAt != nil {
+			resolved.user = nil
+		}
+		switch {
+		case resolved.user != nil && !attrs.EmailVerified:
+			return nil, apperror.NewForbidden("email is not verified by the identity provider")
+		case resolved.user != nil:
+			resolved.linked = true
+		case rules.Mode != federation.ProvisioningModeAutoCreate:
+			return nil, apperror.NewForbidden("no account exists for this identity")
+		default:
+			if resolved.user, err = s.createUser(tx, tenantID, attrs); err != nil {
+				return nil, err
+			}
+			resolved.created = true
+		}
+
+		metadata, _ := json.Marshal(map[string]any{
+			"identity_provider_id": client.IdentityProvider.IdentityProviderUUID.String(),
+			"email":                attrs.Email,
+			"email_verified":       attrs.EmailVerified,
+			"name":                 attrs.Name,
+		})
+		if _, err := txUserIdentityRepo.Create(&model.UserIdentity{
+			TenantID: tenantID,
+			UserID:   resolved.user.UserID,
+			ClientID: client.ClientID,
+			Provider: client.IdentityProvider.Provider,
+			Sub:      identity.Subject,
+			Metadata: datatypes.JSON(metadata),
+		}); err != nil {
+			return nil, apperror.NewInternal("failed to link identity", err)
+		}
+	}
+
+	if err := s.grantMappedRoles(tx, tenantID, resolved, attrs.Roles); err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// createUser creates the local account of an upstream identity. It has no
+// password; the user signs in through the provider.
+func (s *federatedLoginService) createUser(tx *gorm.DB, tenantID int64, attrs *federation.Attributes) (*model.User, error) {
+	txUserRepo := s.userRepo.WithTx(tx)
+
+	taken, err := txUserRepo.FindByUsername(attrs.Email)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to look up user", err)
+	}
+	if taken != nil {
+		return nil, apperror.WithCode(apperror.CodeUserAlreadyExists, apperror.NewConflict("user already exists"))
+	}
+
+	fullname := attrs.Name
+	if fullname == "" {
+		fullname = attrs.Email
+	}
+	user, err := txUserRepo.Create(&model.User{
+		Username:        attrs.Email,
+		Fullname:        fullname,
+		Email:           attrs.Email,
+		IsEmailVerified: attrs.EmailVerified,
+		Status:          model.StatusActive,
+	})
+	if err != nil {
+		return nil, apperror.NewInternal("failed to create user", err)
+	}
+	return user, nil
+}
+
+// grantMappedRoles gives a new user the tenant's default role and any user
+// the roles their groups map to. Roles are only added, never removed, and
+// mapped roles the tenant does not have are skipped.
+func (s *federatedLoginService) grantMappedRoles(tx *gorm.DB, tenantID int64, resolved *federatedUser, roleNames []string) error {
+	txRoleRepo := s.roleRepo.WithTx(tx)
+	txUserRoleRepo := s.userRoleRepo.WithTx(tx)
+	user := resolved.user
+
+	var roles []*model.Role
+	if resolved.created {
+		defaultRole, err := s.findDefaultRole(txRoleRepo, tenantID)
+		if err != nil {
+			return err
+		}
+		roles = append(roles, defaultRole)
+	}
+	for _, name := range roleNames {
+		role, err := txRoleRepo.FindByNameAndTenantID(name, tenantID)
+		if err != nil {
+			return apperror.NewInternal("failed to look up role", err)
+		}
+		if role != nil {
+			roles = append(roles, role)
+		}
+	}
+
+	var added []uuid.UUID
+	for _, role := range roles {
+		current, err := txUserRoleRepo.FindByUserIDAndRoleID(user.UserID, role.RoleID)
+		if err != nil {
+			return apperror.NewInternal("failed to look up user role", err)
+		}
+		if current != nil {
+			continue
+		}
+		if _, err := txUserRoleRepo.Create(&model.UserRole{UserID: user.UserID, RoleID: role.RoleID}); err != nil {
+			return apperror.NewInternal("failed to assign role", err)
+		}
+		added = append(added, role.RoleUUID)
+	}
+
+	txEventRepo := s.eventRepo.WithTx(tx)
+	if resolved.created {
+		return recordUserCreatedEvents(txEventRepo, tenantID, user, added)
+	}
+	if len(added) > 0 {
+		return recordEvent(txEventRepo, tenantID, RoleAssigned{UserUUID: user.UserUUID, RoleUUIDs: added})
+	}
+	return nil
+}
+
+// findDefaultRole finds the role new users of the tenant are given: the
+// role marked default, or else the registered role.
+func (s *federatedLoginService) findDefaultRole(roleRepo repository.RoleRepository, tenantID int64) (*model.Role, error) {
+	result, err := roleRepo.FindPaginated(repository.RoleRepositoryGetFilter{
+		IsDefault: &[]bool{true}[0],
+		TenantID:  tenantID,
+		Page:      1,
+		Limit:     1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Data) > 0 {
+		return &result.Data[0], nil
+	}
+
+	role, err := roleRepo.FindByNameAndTenantID(model.RoleRegistered, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, apperror.NewValidation("no default role found for tenant")
+	}
+	return role, nil
+}
+
+func (s *federatedLoginService) logEvent(ctx context.Context, tenantID int64, userID *int64, category, eventType, result, description string) {
+	severity := model.AuthEventSeverityInfo
+	if result == model.AuthEventResultFailure || eventType == model.AuthEventTypeUserCreated {
+		severity = model.AuthEventSeverityWarn
+	}
+	s.authEventService.Log(ctx, AuthEventInput{
+		TenantID:    tenantID,
+		ActorUserID: userID,
+		IPAddress:   middleware.ClientIPFromContext(ctx),
+		UserAgent:   ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:    category,
+		EventType:   eventType,
+		Severity:    severity,
+		Result:      result,
+		Description: ptr.Ptr(description),
+	})
+}
@@ -0,0 +1,409 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/federation"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// newFederatedClient returns an active Google auth client of tenant 1 with
+// the provisioning rules, if any.
+func newFederatedClient(provisioning string) *model.Client {
+	config := `{"issuer":"https://accounts.google.com","jwks_uri":"https://www.googleapis.com/oauth2/v3/certs"`
+	if provisioning != "" {
+		config += `,"provisioning":` + provisioning
+	}
+	return &model.Client{
+		ClientID:   20,
+		Identifier: ptr.Ptr("google-client-id"),
+		Domain:     ptr.Ptr("https://auth.example.com"),
+		Status:     model.StatusActive,
+		IdentityProvider: &model.IdentityProvider{
+			TenantID:             1,
+			IdentityProviderUUID: uuid.New(),
+			Identifier:           "idp-google",
+			Provider:             model.IDPProviderGoogle,
+			Status:               model.StatusActive,
+			Config:               datatypes.JSON(config + "}"),
+		},
+	}
+}
+
+// newFederatedIdentity returns the verified upstream identity of a member of
+// the "staff" group.
+func newFederatedIdentity() *federation.Identity {
+	return &federation.Identity{
+		Subject:       "google-123",
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		Name:          "Jane Doe",
+		Claims: map[string]any{
+			"sub": "google-123", "email": "jane@example.com", "email_verified": true,
+			"name": "Jane Doe", "groups": []any{"staff"},
+		},
+	}
+}
+
+// federatedIdentityRepo returns an identity repo without a linked identity
+// that records the identities it creates in linked.
+func federatedIdentityRepo(linked *[]*model.UserIdentity) *mockUserIdentityRepo {
+	return &mockUserIdentityRepo{
+		findByClientIDAndSubFn: func(int64, string) (*model.UserIdentity, error) { return nil, nil },
+		createFn: func(identity *model.UserIdentity) (*model.UserIdentity, error) {
+			*linked = append(*linked, identity)
+			return identity, nil
+		},
+	}
+}
+
+// federatedUserRoleRepo returns a user role repo that records the roles it
+// grants in granted.
+func federatedUserRoleRepo(granted *[]int64) *mockUserRoleRepo {
+	return &mockUserRoleRepo{
+		findByUserIDAndRoleIDFn: func(int64, int64) (*model.UserRole, error) { return nil, nil },
+		createFn: func(ur *model.UserRole) (*model.UserRole, error) {
+			*granted = append(*granted, ur.RoleID)
+			return ur, nil
+		},
+	}
+}
+
+// newFederatedLoginService returns a FederatedLoginService where client is
+// found by its identifier and "id-token" verifies as identity. The tenant has
+// the registered and "editor" roles. The audit entries it writes are recorded
+// in logged.
+func newFederatedLoginService(db *gorm.DB, client *model.Client, identity *federation.Identity, userRepo *mockUserRepo, identityRepo *mockUserIdentityRepo, userRoleRepo *mockUserRoleRepo, eventRepo *mockEventRepo, logged *[]AuthEventInput) FederatedLoginService {
+	clientRepo := &mockClientRepo{findByClientIDAndIdentityProviderFn: func(clientID, providerID string) (*model.Client, error) {
+		if clientID != ptr.Deref(client.Identifier) || providerID != client.IdentityProvider.Identifier {
+			return nil, nil
+		}
+		return client, nil
+	}}
+	roleRepo := &mockRoleRepo{findByNameAndTenantIDFn: func(name string, _ int64) (*model.Role, error) {
+		switch name {
+		case model.RoleRegistered:
+			return &model.Role{RoleID: 1, RoleUUID: uuid.New(), Name: name}, nil
+		case "editor":
+			return &model.Role{RoleID: 2, RoleUUID: uuid.New(), Name: name}, nil
+		}
+		return nil, nil
+	}}
+	verifier := &mockIDTokenVerifier{verifyFn: func(_ context.Context, p federation.Provider, raw string) (*federation.Identity, error) {
+		if raw != "id-token" {
+			return nil, fmt.Errorf("%w: bad signature", federation.ErrInvalidToken)
+		}
+		if p.ClientID != "google-client-id" || p.Issuer != "https://accounts.google.com" {
+			return nil, errors.New("unexpected provider")
+		}
+		return identity, nil
+	}}
+	authEvents := &mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { *logged = append(*logged, in) }}
+	return NewFederatedLoginService(db, clientRepo, userRepo, identityRepo, roleRepo, userRoleRepo, eventRepo, verifier, authEvents, nil, nil, nil)
+}
+
+// authEventTypes returns the type of each audit entry.
+func authEventTypes(logged []AuthEventInput) []string {
+	types := make([]string, len(logged))
+	for i, e := range logged {
+		types[i] = e.EventType
+	}
+	return types
+}
+
+func TestFederatedLoginService_Login_Rejected(t *testing.T) {
+	initTestJWTKeysService(t)
+
+	t.Run("unknown client → unauthorized", func(t *testing.T) {
+		var linked []*model.UserIdentity
+		var granted []int64
+		var logged []AuthEventInput
+		svc := newFederatedLoginService(nil, newFederatedClient(""), newFederatedIdentity(), &mockUserRepo{}, federatedIdentityRepo(&linked), federatedUserRoleRepo(&granted), &mockEventRepo{}, &logged)
+
+		_, err := svc.Login(context.Background(), "id-token", "other", "idp-google")
+		assert.ErrorAs(t, err, new(*apperror.UnauthorizedError))
+	})
+
+	t.Run("internal identity provider → unauthorized", func(t *testing.T) {
+		client := newFederatedClient("")
+		client.IdentityProvider.Provider = model.IDPProviderInternal
+		var linked []*model.UserIdentity
+		var granted []int64
+		var logged []AuthEventInput
+		svc := newFederatedLoginService(nil, client, newFederatedIdentity(), &mockUserRepo{}, federatedIdentityRepo(&linked), federatedUserRoleRepo(&granted), &mockEventRepo{}, &logged)
+
+		_, err := svc.Login(context.Background(), "id-token", "google-client-id", "idp-google")
+		assert.ErrorAs(t, err, new(*apperror.UnauthorizedError))
+	})
+
+	t.Run("provider not federated → unauthorized", func(t *testing.T) {
+		client := newFederatedClient("")
+		client.IdentityProvider.Config = datatypes.JSON(`{}`)
+		var linked []*model.UserIdentity
+		var granted []int64
+		var logged []AuthEventInput
+		svc := newFederatedLoginService(nil, client, newFederatedIdentity(), &mockUserRepo{}, federatedIdentityRepo(&linked), federatedUserRoleRepo(&granted), &mockEventRepo{}, &logged)
+
+		_, err := svc.Login(context.Background(), "id-token", "google-client-id", "idp-google")
+		assert.ErrorAs(t, err, new(*apperror.UnauthorizedError))
+	})
+
+	t.Run("invalid token → unauthorized", func(t *testing.T) {
+		var linked []*model.UserIdentity
+		var granted []int64
+		var logged []AuthEventInput
+		svc := newFederatedLoginService(nil, newFederatedClient(""), newFederatedIdentity(), &mockUserRepo{}, federatedIdentityRepo(&linked), federatedUserRoleRepo(&granted), &mockEventRepo{}, &logged)
+
+		_, err := svc.Login(context.Background(), "forged", "google-client-id", "idp-google")
+		assert.ErrorAs(t, err, new(*apperror.UnauthorizedError))
+		assert.Equal(t, []string{model.AuthEventTypeLoginFail}, authEventTypes(logged))
+	})
+
+	t.Run("key set unavailable → internal error", func(t *testing.T) {
+		client := newFederatedClient("")
+		client.Identifier = ptr.Ptr("other-client-id")
+		var linked []*model.UserIdentity
+		var granted []int64
+		var logged []AuthEventInput
+		svc := newFederatedLoginService(nil, client, newFederatedIdentity(), &mockUserRepo{}, federatedIdentityRepo(&linked), federatedUserRoleRepo(&granted), &mockEventRepo{}, &logged)
+
+		_, err := svc.Login(context.Background(), "id-token", "other-client-id", "idp-google")
+		assert.ErrorAs(t, err, new(*apperror.InternalError))
+	})
+
+	t.Run("domain not allowed → forbidden", func(t *testing.T) {
+		client := newFederatedClient(`{"allowed_domains":["corp.example.com"]}`)
+		var linked []*model.UserIdentity
+		var granted []int64
+		var logged []AuthEventInput
+		svc := newFederatedLoginService(nil, client, newFederatedIdentity(), &mockUserRepo{}, federatedIdentityRepo(&linked), federatedUserRoleRepo(&granted), &mockEventRepo{}, &logged)
+
+		_, err := svc.Login(context.Background(), "id-token", "google-client-id", "idp-google")
+		assert.ErrorAs(t, err, new(*apperror.ForbiddenError))
+		assert.Equal(t, []string{model.AuthEventTypeLoginFail}, authEventTypes(logged))
+	})
+}
+
+func TestFederatedLoginService_Login_ReturningUser(t *testing.T) {
+	initTestJWTKeysService(t)
+	client := newFederatedClient(`{"group_roles":{"staff":["editor"]}}`)
+
+	returningUser := func(user *model.User, linked *[]*model.UserIdentity) (*mockUserRepo, *mockUserIdentityRepo) {
+		identityRepo := federatedIdentityRepo(linked)
+		identityRepo.findByClientIDAndSubFn = func(clientID int64, sub string) (*model.UserIdentity, error) {
+			assert.Equal(t, int64(20), clientID)
+			assert.Equal(t, "google-123", sub)
+			return &model.UserIdentity{UserID: 7, Sub: sub}, nil
+		}
+		userRepo := &mockUserRepo{findByIDFn: func(id any, _ ...string) (*model.User, error) {
+			assert.Equal(t, int64(7), id)
+			return user, nil
+		}}
+		return userRepo, identityRepo
+	}
+
+	t.Run("signs in and grants newly mapped roles", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		user := &model.User{UserID: 7, UserUUID: uuid.New(), Username: "jane", Status: model.StatusActive}
+		var linked []*model.UserIdentity
+		var granted []int64
+		var logged []AuthEventInput
+		userRepo, identityRepo := returningUser(user, &linked)
+		eventRepo := &mockEventRepo{}
+		svc := newFederatedLoginService(gormDB, client, newFederatedIdentity(), userRepo, identityRepo, federatedUserRoleRepo(&granted), eventRepo, &logged)
+
+		res, err := svc.Login(context.Background(), "id-token", "google-client-id", "idp-google")
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		assert.NotEmpty(t, res.AccessToken)
+		assert.Empty(t, linked)
+		assert.Equal(t, []int64{2}, granted, "newly mapped roles are granted")
+		assert.Equal(t, []string{model.EventTypeUserRolesAdded}, eventRepo.eventTypes())
+		assert.Equal(t, []string{model.AuthEventTypeLoginSuccess}, authEventTypes(logged))
+	})
+
+	t.Run("inactive user → unauthorized", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		user := &model.User{UserID: 7, UserUUID: uuid.New(), Username: "jane", Status: model.StatusInactive}
+		var linked []*model.UserIdentity
+		var granted []int64
+		var logged []AuthEventInput
+		userRepo, identityRepo := returningUser(user, &linked)
+		svc := newFederatedLoginService(gormDB, client, newFederatedIdentity(), userRepo, identityRepo, federatedUserRoleRepo(&granted), &mockEventRepo{}, &logged)
+
+		_, err := svc.Login(context.Background(), "id-token", "google-client-id", "idp-google")
+		assert.ErrorAs(t, err, new(*apperror.UnauthorizedError))
+		require.NoError(t, mock.ExpectationsWereMet())
+		assert.Equal(t, []string{model.AuthEventTypeLoginFail}, authEventTypes(logged))
+	})
+}
+
+func TestFederatedLoginService_Login_ExistingAccount(t *testing.T) {
+	initTestJWTKeysService(t)
+	existing := func() *model.User {
+		return &model.User{UserID: 9, UserUUID: uuid.New(), Username: "jane", Email: "jane@example.com", Status: model.StatusActive}
+	}
+
+	t.Run("verified email → account linked", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		userRepo := &mockUserRepo{findByEmailAndTenantIDFn: func(email string, tenantID int64) (*model.User, error) {
+			assert.Equal(t, "jane@example.com", email)
+			assert.Equal(t, int64(1), tenantID)
+			return existing(), nil
+		}}
+		var linked []*model.UserIdentity
+		var granted []int64
+		var logged []AuthEventInput
+		svc := newFederatedLoginService(gormDB, newFederatedClient(""), newFederatedIdentity(), userRepo, federatedIdentityRepo(&linked), federatedUserRoleRepo(&granted), &mockEventRepo{}, &logged)
+
+		_, err := svc.Login(context.Background(), "id-token", "google-client-id", "idp-google")
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, linked, 1)
+		assert.Equal(t, int64(9), linked[0].UserID)
+		assert.Equal(t, int64(20), linked[0].ClientID)
+		assert.Equal(t, model.IDPProviderGoogle, linked[0].Provider)
+		assert.Equal(t, "google-123", linked[0].Sub)
+		assert.Empty(t, granted)
+		assert.Equal(t, []string{model.AuthEventTypeUserIdentityLinked, model.AuthEventTypeLoginSuccess}, authEventTypes(logged))
+	})
+
+	t.Run("unverified email → forbidden", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		identity := newFederatedIdentity()
+		identity.EmailVerified = false
+		userRepo := &mockUserRepo{findByEmailAndTenantIDFn: func(string, int64) (*model.User, error) { return existing(), nil }}
+		var linked []*model.UserIdentity
+		var granted []int64
+		var logged []AuthEventInput
+		svc := newFederatedLoginService(gormDB, newFederatedClient(""), identity, userRepo, federatedIdentityRepo(&linked), federatedUserRoleRepo(&granted), &mockEventRepo{}, &logged)
+
+		_, err := svc.Login(context.Background(), "id-token", "google-client-id", "idp-google")
+		assert.ErrorAs(t, err, new(*apperror.ForbiddenError))
+		require.NoError(t, mock.ExpectationsWereMet())
+		assert.Empty(t, linked)
+	})
+
+	t.Run("no account with existing only → forbidden", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		userRepo := &mockUserRepo{findByEmailAndTenantIDFn: func(string, int64) (*model.User, error) { return nil, nil }}
+		var linked []*model.UserIdentity
+		var granted []int64
+		var logged []AuthEventInput
+		svc := newFederatedLoginService(gormDB, newFederatedClient(""), newFederatedIdentity(), userRepo, federatedIdentityRepo(&linked), federatedUserRoleRepo(&granted), &mockEventRepo{}, &logged)
+
+		_, err := svc.Login(context.Background(), "id-token", "google-client-id", "idp-google")
+		assert.ErrorAs(t, err, new(*apperror.ForbiddenError))
+		require.NoError(t, mock.ExpectationsWereMet())
+		assert.Empty(t, linked)
+	})
+}
+
+func TestFederatedLoginService_Login_AutoCreate(t *testing.T) {
+	initTestJWTKeysService(t)
+	rules := `{"mode":"auto_create","group_roles":{"staff":["editor","missing"]}}`
+
+	t.Run("creates the user", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		var created *model.User
+		userRepo := &mockUserRepo{
+			findByEmailAndTenantIDFn: func(string, int64) (*model.User, error) { return nil, nil },
+			findByUsernameFn:         func(string) (*model.User, error) { return nil, nil },
+			createFn: func(u *model.User) (*model.User, error) {
+				u.UserID = 11
+				u.UserUUID = uuid.New()
+				created = u
+				return u, nil
+			},
+		}
+		var linked []*model.UserIdentity
+		var granted []int64
+		var logged []AuthEventInput
+		eventRepo := &mockEventRepo{}
+		svc := newFederatedLoginService(gormDB, newFederatedClient(rules), newFederatedIdentity(), userRepo, federatedIdentityRepo(&linked), federatedUserRoleRepo(&granted), eventRepo, &logged)
+
+		res, err := svc.Login(context.Background(), "id-token", "google-client-id", "idp-google")
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		assert.NotEmpty(t, res.IDToken)
+
+		require.NotNil(t, created)
+		assert.Equal(t, "jane@example.com", created.Username)
+		assert.Equal(t, "jane@example.com", created.Email)
+		assert.Equal(t, "Jane Doe", created.Fullname)
+		assert.True(t, created.IsEmailVerified)
+		assert.Nil(t, created.Password)
+		require.Len(t, linked, 1)
+		assert.Equal(t, int64(11), linked[0].UserID)
+		assert.Equal(t, []int64{1, 2}, granted, "default role and mapped roles the tenant has")
+		assert.Equal(t, []string{model.EventTypeUserCreated, model.EventTypeUserRolesAdded}, eventRepo.eventTypes())
+		assert.Equal(t, []string{model.AuthEventTypeUserCreated, model.AuthEventTypeLoginSuccess}, authEventTypes(logged))
+	})
+
+	t.Run("username taken → conflict", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		userRepo := &mockUserRepo{
+			findByEmailAndTenantIDFn: func(string, int64) (*model.User, error) { return nil, nil },
+			findByUsernameFn:         func(string) (*model.User, error) { return &model.User{UserID: 3}, nil },
+		}
+		var linked []*model.UserIdentity
+		var granted []int64
+		var logged []AuthEventInput
+		svc := newFederatedLoginService(gormDB, newFederatedClient(rules), newFederatedIdentity(), userRepo, federatedIdentityRepo(&linked), federatedUserRoleRepo(&granted), &mockEventRepo{}, &logged)
+
+		_, err := svc.Login(context.Background(), "id-token", "google-client-id", "idp-google")
+		assert.ErrorAs(t, err, new(*apperror.ConflictError))
+		require.NoError(t, mock.ExpectationsWereMet())
+		assert.Empty(t, linked)
+	})
+
+	t.Run("repository error → rolled back", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		var linked []*model.UserIdentity
+		var granted []int64
+		var logged []AuthEventInput
+		identityRepo := federatedIdentityRepo(&linked)
+		identityRepo.findByClientIDAndSubFn = func(int64, string) (*model.UserIdentity, error) {
+			return nil, errors.New("db down")
+		}
+		svc := newFederatedLoginService(gormDB, newFederatedClient(rules), newFederatedIdentity(), &mockUserRepo{}, identityRepo, federatedUserRoleRepo(&granted), &mockEventRepo{}, &logged)
+
+		_, err := svc.Login(context.Background(), "id-token", "google-client-id", "idp-google")
+		require.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
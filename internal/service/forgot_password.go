@@ -32,6 +32,7 @@ type forgotPasswordService struct {
 	userTokenRepo     repository.UserTokenRepository
 	clientRepo        repository.ClientRepository
 	emailTemplateRepo repository.EmailTemplateRepository
+	tenantSettingRepo repository.TenantSettingRepository
 }
 
 func NewForgotPasswordService(
@@ -40,6 +41,7 @@ func NewForgotPasswordService(
 	userTokenRepo repository.UserTokenRepository,
 	clientRepo repository.ClientRepository,
 	emailTemplateRepo repository.EmailTemplateRepository,
+	tenantSettingRepo repository.TenantSettingRepository,
 ) ForgotPasswordService {
 	return &forgotPasswordService{
 		db:                db,
@@ -47,16 +49,19 @@ func NewForgotPasswordService(
 		userTokenRepo:     userTokenRepo,
 		clientRepo:        clientRepo,
 		emailTemplateRepo: emailTemplateRepo,
+		tenantSettingRepo: tenantSettingRepo,
 	}
 }
 
 func (s *forgotPasswordService) SendPasswordResetEmail(ctx context.Context, email string, clientID, providerID *string, isInternal bool) (*dto.ForgotPasswordResponseDTO, error) {
 	_, span := otel.Tracer("service").Start(ctx, "forgotPassword.sendResetEmail")
 	defer span.End()
+	startTime := time.Now()
 
 	var user *model.User
 	var Client *model.Client
 	var resetToken string
+	var safe bool
 
 	err := s.db.Transaction(func(tx *gorm.DB) error {
 		txUserRepo := s.userRepo.WithTx(tx)
@@ -73,6 +78,9 @@ func (s *forgotPasswordService) SendPasswordResetEmail(ctx context.Context, emai
 		if txErr != nil {
 			return apperror.NewInternal("failed to find auth client", txErr)
 		}
+		if Client != nil && Client.IdentityProvider != nil {
+			safe = enumerationSafe(s.tenantSettingRepo.WithTx(tx), Client.IdentityProvider.TenantID)
+		}
 
 		// Find user by email
 		user, txErr = txUserRepo.FindByEmail(email)
@@ -123,8 +131,20 @@ func (s *forgotPasswordService) SendPasswordResetEmail(ctx context.Context, emai
 
 	if err != nil {
 		span.RecordError(err)
-		span.SetStatus(codes.Error, "transaction failed")
-		return nil, err
+		// Enumeration-safe tenants hide failures that only existing
+		// accounts can reach behind the generic response
+		if !safe || user == nil {
+			span.SetStatus(codes.Error, "transaction failed")
+			return nil, err
+		}
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "password_reset_failure",
+			UserID:    user.UserUUID.String(),
+			Details:   fmt.Sprintf("Failed to issue password reset token: %v", err),
+			Severity:  "HIGH",
+			Timestamp: time.Now(),
+		})
+		user = nil
 	}
 
 	// Always send success response for security (don't reveal if email exists)
@@ -135,23 +155,36 @@ func (s *forgotPasswordService) SendPasswordResetEmail(ctx context.Context, emai
 
 	// Only send email if user was found (user will be nil if not found due to security)
 	if user != nil {
-		// Generate reset URL and send email
-		if err := s.sendPasswordResetEmail(ctx, user.Email, resetToken, Client, isInternal); err != nil {
-			// Log error but don't reveal it to user for security
-			security.LogSecurityEvent(security.SecurityEvent{
-				EventType: "password_reset_email_failure",
-				UserID:    user.UserUUID.String(),
-				Details:   fmt.Sprintf("Failed to send password reset email: %v", err),
-				Severity:  "HIGH",
-				Timestamp: time.Now(),
-			})
+		if safe {
+			// Deliver in the background so the mail server's latency does
+			// not tell existing accounts apart
+			go s.notifyPasswordReset(context.WithoutCancel(ctx), user, resetToken, Client, isInternal)
+		} else {
+			s.notifyPasswordReset(ctx, user, resetToken, Client, isInternal)
 		}
 	}
+	if safe {
+		padResponseTime(ctx, startTime)
+	}
 
 	span.SetStatus(codes.Ok, "")
 	return response, nil
 }
 
+// notifyPasswordReset sends the reset email, logging rather than returning
+// failures so they are not revealed to the caller.
+func (s *forgotPasswordService) notifyPasswordReset(ctx context.Context, user *model.User, resetToken string, Client *model.Client, isInternal bool) {
+	if err := s.sendPasswordResetEmail(ctx, user.Email, resetToken, Client, isInternal); err != nil {
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "password_reset_email_failure",
+			UserID:    user.UserUUID.String(),
+			Details:   fmt.Sprintf("Failed to send password reset email: %v", err),
+			Severity:  "HIGH",
+			Timestamp: time.Now(),
+		})
+	}
+}
+
 // generateSecureToken generates a cryptographically secure random token.
 // rand.Read always succeeds in Go 1.24+ (see go.dev/issue/66821).
 func generateSecureToken(length int) string {
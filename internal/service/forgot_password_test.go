@@ -54,7 +54,7 @@ func TestForgotPasswordService_SendPasswordResetEmail(t *testing.T) {
 			clientRepo := &mockClientRepo{}
 			tc.setupClient(clientRepo)
 
-			svc := NewForgotPasswordService(gormDB, &mockUserRepo{}, &mockUserTokenRepo{}, clientRepo, &mockEmailTemplateRepo{})
+			svc := NewForgotPasswordService(gormDB, &mockUserRepo{}, &mockUserTokenRepo{}, clientRepo, &mockEmailTemplateRepo{}, &mockTenantSettingRepo{})
 			resp, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", nil, nil, false)
 
 			if tc.wantErr {
@@ -85,7 +85,7 @@ func TestForgotPasswordService_SendPasswordResetEmail_ClientIDAndProviderID(t *t
 		},
 	}
 
-	svc := NewForgotPasswordService(gormDB, &mockUserRepo{}, &mockUserTokenRepo{}, clientRepo, &mockEmailTemplateRepo{})
+	svc := NewForgotPasswordService(gormDB, &mockUserRepo{}, &mockUserTokenRepo{}, clientRepo, &mockEmailTemplateRepo{}, &mockTenantSettingRepo{})
 	resp, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", &clientID, &providerID, false)
 	require.NoError(t, err)
 	require.NotNil(t, resp)
@@ -104,7 +104,7 @@ func TestForgotPasswordService_SendPasswordResetEmail_FindByEmailError(t *testin
 		findByEmailFn: func(_ string) (*model.User, error) { return nil, errors.New("db err") },
 	}
 
-	svc := NewForgotPasswordService(gormDB, userRepo, &mockUserTokenRepo{}, clientRepo, &mockEmailTemplateRepo{})
+	svc := NewForgotPasswordService(gormDB, userRepo, &mockUserTokenRepo{}, clientRepo, &mockEmailTemplateRepo{}, &mockTenantSettingRepo{})
 	resp, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", nil, nil, false)
 	// FindByEmail error returns nil (security masking), user stays nil so no email sent
 	require.NoError(t, err)
@@ -151,7 +151,7 @@ func TestForgotPasswordService_SendPasswordResetEmail_UserInactive(t *testing.T)
 		},
 	}
 
-	svc := NewForgotPasswordService(gormDB, userRepo, &mockUserTokenRepo{}, clientRepo, emailTemplateRepo)
+	svc := NewForgotPasswordService(gormDB, userRepo, &mockUserTokenRepo{}, clientRepo, emailTemplateRepo, &mockTenantSettingRepo{})
 	resp, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", nil, nil, true)
 	require.NoError(t, err)
 	require.NotNil(t, resp)
@@ -177,7 +177,7 @@ func TestForgotPasswordService_SendPasswordResetEmail_FindTokensError(t *testing
 		},
 	}
 
-	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, &mockEmailTemplateRepo{})
+	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, &mockEmailTemplateRepo{}, &mockTenantSettingRepo{})
 	_, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", nil, nil, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "find existing tokens")
@@ -204,7 +204,7 @@ func TestForgotPasswordService_SendPasswordResetEmail_RevokeTokenError(t *testin
 		revokeByUUIDFn: func(_ uuid.UUID) error { return errors.New("revoke err") },
 	}
 
-	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, &mockEmailTemplateRepo{})
+	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, &mockEmailTemplateRepo{}, &mockTenantSettingRepo{})
 	_, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", nil, nil, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "revoke existing token")
@@ -230,7 +230,7 @@ func TestForgotPasswordService_SendPasswordResetEmail_CreateTokenError(t *testin
 		createFn: func(_ *model.UserToken) (*model.UserToken, error) { return nil, errors.New("create err") },
 	}
 
-	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, &mockEmailTemplateRepo{})
+	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, &mockEmailTemplateRepo{}, &mockTenantSettingRepo{})
 	_, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", nil, nil, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "create reset token")
@@ -296,7 +296,7 @@ func TestForgotPasswordService_SendPasswordResetEmail_FullPath(t *testing.T) {
 		},
 	}
 
-	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo)
+	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo, &mockTenantSettingRepo{})
 	resp, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", nil, nil, true)
 	require.NoError(t, err)
 	require.NotNil(t, resp)
@@ -352,7 +352,7 @@ func TestForgotPasswordService_SendPasswordResetEmail_ExternalURL(t *testing.T)
 		},
 	}
 
-	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo)
+	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo, &mockTenantSettingRepo{})
 	resp, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", nil, nil, false)
 	require.NoError(t, err)
 	require.NotNil(t, resp)
@@ -406,7 +406,7 @@ func TestForgotPasswordService_SendPasswordResetEmail_EmailSendError(t *testing.
 		},
 	}
 
-	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo)
+	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo, &mockTenantSettingRepo{})
 	resp, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", nil, nil, true)
 	require.NoError(t, err) // email failure is silently logged
 	require.NotNil(t, resp)
@@ -455,7 +455,7 @@ func TestForgotPasswordService_SendPasswordResetEmail_TemplateError(t *testing.T
 		},
 	}
 
-	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo)
+	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo, &mockTenantSettingRepo{})
 	resp, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", nil, nil, true)
 	require.NoError(t, err) // template error is logged silently
 	require.NotNil(t, resp)
@@ -505,7 +505,7 @@ func TestForgotPasswordService_SendPasswordResetEmail_HTMLParseError(t *testing.
 		},
 	}
 
-	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo)
+	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo, &mockTenantSettingRepo{})
 	resp, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", nil, nil, true)
 	require.NoError(t, err) // error is logged silently
 	require.NotNil(t, resp)
@@ -557,7 +557,7 @@ func TestForgotPasswordService_SendPasswordResetEmail_PlainParseError(t *testing
 		},
 	}
 
-	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo)
+	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo, &mockTenantSettingRepo{})
 	resp, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", nil, nil, true)
 	require.NoError(t, err) // error is logged silently
 	require.NotNil(t, resp)
@@ -607,7 +607,7 @@ func TestForgotPasswordService_SendPasswordResetEmail_HTMLExecuteError(t *testin
 		},
 	}
 
-	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo)
+	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo, &mockTenantSettingRepo{})
 	resp, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", nil, nil, true)
 	require.NoError(t, err) // error is logged silently
 	require.NotNil(t, resp)
@@ -659,7 +659,7 @@ func TestForgotPasswordService_SendPasswordResetEmail_PlainExecuteError(t *testi
 		},
 	}
 
-	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo)
+	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo, &mockTenantSettingRepo{})
 	resp, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", nil, nil, true)
 	require.NoError(t, err) // error is logged silently
 	require.NotNil(t, resp)
@@ -730,7 +730,7 @@ func TestForgotPasswordService_SendPasswordResetEmail_WithExistingTokens(t *test
 		},
 	}
 
-	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo)
+	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo, &mockTenantSettingRepo{})
 	resp, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", nil, nil, true)
 	require.NoError(t, err)
 	require.NotNil(t, resp)
@@ -786,7 +786,7 @@ func TestForgotPasswordService_SendPasswordResetEmail_GenerateSignedURLError(t *
 		},
 	}
 
-	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo)
+	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo, &mockTenantSettingRepo{})
 	resp, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", nil, nil, true)
 	require.NoError(t, err)
 	require.NotNil(t, resp)
@@ -843,7 +843,7 @@ func TestForgotPasswordService_SendPasswordResetEmail_ConvertToFrontendURLError(
 		},
 	}
 
-	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo)
+	svc := NewForgotPasswordService(gormDB, userRepo, tokenRepo, clientRepo, emailTemplateRepo, &mockTenantSettingRepo{})
 	resp, err := svc.SendPasswordResetEmail(context.Background(), "user@example.com", nil, nil, true)
 	require.NoError(t, err)
 	require.NotNil(t, resp)
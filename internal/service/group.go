@@ -0,0 +1,738 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+type GroupServiceDataResult struct {
+	GroupUUID   uuid.UUID
+	Name        string
+	Description string
+	Roles       []RoleServiceDataResult
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type GroupServiceGetFilter struct {
+	TenantID    int64
+	Name        *string
+	Description *string
+	// UserUUID limits the result to the groups the user is a member of.
+	UserUUID  *uuid.UUID
+	Page      int
+	Limit     int
+	SortBy    string
+	SortOrder string
+}
+
+type GroupServiceGetResult struct {
+	Data       []GroupServiceDataResult
+	Total      int64
+	Page       int
+	Limit      int
+	TotalPages int
+}
+
+// GroupService manages user groups. Every member of a group holds the roles
+// attached to it, in addition to the roles assigned to them directly.
+type GroupService interface {
+	Get(ctx context.Context, filter GroupServiceGetFilter) (*GroupServiceGetResult, error)
+	GetByUUID(ctx context.Context, groupUUID uuid.UUID, tenantID int64) (*GroupServiceDataResult, error)
+	Create(ctx context.Context, tenantID int64, name string, description string) (*GroupServiceDataResult, error)
+	Update(ctx context.Context, groupUUID uuid.UUID, tenantID int64, name string, description string) (*GroupServiceDataResult, error)
+	DeleteByUUID(ctx context.Context, groupUUID uuid.UUID, tenantID int64) (*GroupServiceDataResult, error)
+	GetMembers(ctx context.Context, groupUUID uuid.UUID, tenantID int64, page int, limit int) (*UserServiceGetResult, error)
+	// AddMembers adds users of the tenant to the group. Users who are
+	// already members are skipped.
+	AddMembers(ctx context.Context, groupUUID uuid.UUID, tenantID int64, userUUIDs []uuid.UUID) (*GroupServiceDataResult, error)
+	RemoveMember(ctx context.Context, groupUUID uuid.UUID, tenantID int64, userUUID uuid.UUID) (*GroupServiceDataResult, error)
+	// AddRoles attaches roles to the group. The roles must belong to the
+	// tenant or be inheritable roles of a tenant above it.
+	AddRoles(ctx context.Context, groupUUID uuid.UUID, tenantID int64, roleUUIDs []uuid.UUID) (*GroupServiceDataResult, error)
+	RemoveRole(ctx context.Context, groupUUID uuid.UUID, tenantID int64, roleUUID uuid.UUID) (*GroupServiceDataResult, error)
+}
+
+type groupService struct {
+	db               *gorm.DB
+	groupRepo        repository.GroupRepository
+	userRepo         repository.UserRepository
+	roleRepo         repository.RoleRepository
+	tenantRepo       repository.TenantRepository
+	eventRepo        repository.EventRepository
+	cacheInvalidator cache.Invalidator
+}
+
+func NewGroupService(
+	db *gorm.DB,
+	groupRepo repository.GroupRepository,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	tenantRepo repository.TenantRepository,
+	eventRepo repository.EventRepository,
+	cacheInvalidator cache.Invalidator,
+) GroupService {
+	return &groupService{
+		db:               db,
+		groupRepo:        groupRepo,
+		userRepo:         userRepo,
+		roleRepo:         roleRepo,
+		tenantRepo:       tenantRepo,
+		eventRepo:        eventRepo,
+		cacheInvalidator: cacheInvalidator,
+	}
+}
+
+func (s *groupService) Get(ctx context.Context, filter GroupServiceGetFilter) (*GroupServiceGetResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "group.list")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", filter.TenantID))
+
+	repoFilter := repository.GroupRepositoryGetFilter{
+		TenantID:    filter.TenantID,
+		Name:        filter.Name,
+		Description: filter.Description,
+		Page:        filter.Page,
+		Limit:       filter.Limit,
+		SortBy:      filter.SortBy,
+		SortOrder:   filter.SortOrder,
+	}
+	if filter.UserUUID != nil {
+		user, err := s.userRepo.FindByUUID(*filter.UserUUID, "UserIdentities")
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "find user failed")
+			return nil, err
+		}
+		if user == nil || !userInTenant(user, filter.TenantID) {
+			return nil, apperror.WithCode(apperror.CodeUserNotFound, apperror.NewNotFound("user"))
+		}
+		repoFilter.UserID = &user.UserID
+	}
+
+	result, err := s.groupRepo.FindPaginated(repoFilter)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list groups failed")
+		return nil, err
+	}
+
+	data := make([]GroupServiceDataResult, len(result.Data))
+	for i := range result.Data {
+		data[i] = *toGroupServiceDataResult(&result.Data[i])
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return &GroupServiceGetResult{
+		Data:       data,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}, nil
+}
+
+func (s *groupService) GetByUUID(ctx context.Context, groupUUID uuid.UUID, tenantID int64) (*GroupServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "group.get")
+	defer span.End()
+	span.SetAttributes(attribute.String("group.uuid", groupUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	group, err := s.groupRepo.FindByUUIDAndTenantID(groupUUID, tenantID, "Roles")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get group failed")
+		return nil, err
+	}
+	if group == nil {
+		return nil, apperror.NewNotFound("group")
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toGroupServiceDataResult(group), nil
+}
+
+func (s *groupService) Create(ctx context.Context, tenantID int64, name string, description string) (*GroupServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "group.create")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID))
+
+	var created *model.Group
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txGroupRepo := s.groupRepo.WithTx(tx)
+
+		existing, err := txGroupRepo.FindByName(name, tenantID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return apperror.NewConflict("group with name '" + name + "' already exists")
+		}
+
+		group := &model.Group{
+			GroupUUID:   uuid.New(),
+			TenantID:    tenantID,
+			Name:        name,
+			Description: description,
+		}
+		if _, err := txGroupRepo.Create(group); err != nil {
+			return err
+		}
+
+		if err := recordEvent(s.eventRepo.WithTx(tx), tenantID, GroupCreated(newGroupEventPayload(group))); err != nil {
+			return err
+		}
+
+		created = group
+		return nil
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "create group failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toGroupServiceDataResult(created), nil
+}
+
+func (s *groupService) Update(ctx context.Context, groupUUID uuid.UUID, tenantID int64, name string, description string) (*GroupServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "group.update")
+	defer span.End()
+	span.SetAttributes(attribute.String("group.uuid", groupUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	var updated *model.Group
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txGroupRepo := s.groupRepo.WithTx(tx)
+
+		group, err := txGroupRepo.FindByUUIDAndTenantID(groupUUID, tenantID)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return apperror.NewNotFoundWithReason("group not found or access denied")
+		}
+
+		if group.Name != name {
+			existing, err := txGroupRepo.FindByName(name, tenantID)
+			if err != nil {
+				return err
+			}
+			if existing != nil && existing.GroupUUID != groupUUID {
+				return apperror.NewConflict("group with name '" + name + "' already exists")
+			}
+		}
+
+		// A map so that an empty description is written too.
+		if _, err := txGroupRepo.UpdateByUUID(groupUUID, map[string]any{
+			"name":        name,
+			"description": description,
+		}); err != nil {
+			return err
+		}
+
+		updated, err = txGroupRepo.FindByUUIDAndTenantID(groupUUID, tenantID, "Roles")
+		if err != nil {
+			return err
+		}
+		if updated == nil {
+			return apperror.NewNotFoundWithReason("group not found or access denied")
+		}
+
+		return recordEvent(s.eventRepo.WithTx(tx), tenantID, GroupUpdated(newGroupEventPayload(updated)))
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "update group failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toGroupServiceDataResult(updated), nil
+}
+
+func (s *groupService) DeleteByUUID(ctx context.Context, groupUUID uuid.UUID, tenantID int64) (*GroupServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "group.delete")
+	defer span.End()
+	span.SetAttributes(attribute.String("group.uuid", groupUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	var deleted *model.Group
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txGroupRepo := s.groupRepo.WithTx(tx)
+
+		group, err := txGroupRepo.FindByUUIDAndTenantID(groupUUID, tenantID, "Roles")
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return apperror.NewNotFoundWithReason("group not found or access denied")
+		}
+		deleted = group
+
+		if err := txGroupRepo.DeleteByUUIDAndTenantID(groupUUID, tenantID); err != nil {
+			return err
+		}
+
+		return recordEvent(s.eventRepo.WithTx(tx), tenantID, GroupDeleted(newGroupEventPayload(group)))
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete group failed")
+		return nil, err
+	}
+
+	// The members lose the group's roles.
+	if len(deleted.Roles) > 0 {
+		s.cacheInvalidator.InvalidateAllUsers(ctx)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toGroupServiceDataResult(deleted), nil
+}
+
+func (s *groupService) GetMembers(ctx context.Context, groupUUID uuid.UUID, tenantID int64, page int, limit int) (*UserServiceGetResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "group.listMembers")
+	defer span.End()
+	span.SetAttributes(attribute.String("group.uuid", groupUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	group, err := s.groupRepo.FindByUUIDAndTenantID(groupUUID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get group failed")
+		return nil, err
+	}
+	if group == nil {
+		return nil, apperror.NewNotFound("group")
+	}
+
+	result, err := s.groupRepo.FindMembersPaginated(group.GroupID, page, limit)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list group members failed")
+		return nil, err
+	}
+
+	data := make([]UserServiceDataResult, len(result.Data))
+	for i := range result.Data {
+		data[i] = *toUserServiceDataResult(&result.Data[i])
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return &UserServiceGetResult{
+		Data:       data,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}, nil
+}
+
+func (s *groupService) AddMembers(ctx context.Context, groupUUID uuid.UUID, tenantID int64, userUUIDs []uuid.UUID) (*GroupServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "group.addMembers")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("group.uuid", groupUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+		attribute.Int("user.count", len(userUUIDs)),
+	)
+
+	var group *model.Group
+	var addedUsers []model.User
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txGroupRepo := s.groupRepo.WithTx(tx)
+
+		var err error
+		group, err = txGroupRepo.FindByUUIDAndTenantID(groupUUID, tenantID, "Roles")
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return apperror.NewNotFoundWithReason("group not found or access denied")
+		}
+
+		users, err := resolveGroupMembers(s.userRepo.WithTx(tx), tenantID, userUUIDs)
+		if err != nil {
+			return err
+		}
+		userIDs := make([]int64, len(users))
+		for i, user := range users {
+			userIDs[i] = user.UserID
+		}
+
+		added, err := txGroupRepo.AddMembers(group.GroupID, userIDs)
+		if err != nil {
+			return err
+		}
+		if len(added) == 0 {
+			return nil
+		}
+
+		isAdded := make(map[int64]bool, len(added))
+		for _, id := range added {
+			isAdded[id] = true
+		}
+		addedUUIDs := make([]uuid.UUID, 0, len(added))
+		for _, user := range users {
+			if isAdded[user.UserID] {
+				addedUsers = append(addedUsers, user)
+				addedUUIDs = append(addedUUIDs, user.UserUUID)
+			}
+		}
+
+		return recordEvent(s.eventRepo.WithTx(tx), tenantID, GroupMembersAdded{
+			GroupUUID: group.GroupUUID,
+			UserUUIDs: addedUUIDs,
+		})
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "add group members failed")
+		return nil, err
+	}
+
+	s.invalidateMembers(ctx, addedUsers)
+
+	span.SetStatus(codes.Ok, "")
+	return toGroupServiceDataResult(group), nil
+}
+
+func (s *groupService) RemoveMember(ctx context.Context, groupUUID uuid.UUID, tenantID int64, userUUID uuid.UUID) (*GroupServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "group.removeMember")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("group.uuid", groupUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+		attribute.String("user.uuid", userUUID.String()),
+	)
+
+	var group *model.Group
+	var user *model.User
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txGroupRepo := s.groupRepo.WithTx(tx)
+
+		var err error
+		group, err = txGroupRepo.FindByUUIDAndTenantID(groupUUID, tenantID, "Roles")
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return apperror.NewNotFoundWithReason("group not found or access denied")
+		}
+
+		user, err = s.userRepo.WithTx(tx).FindByUUID(userUUID, "UserIdentities")
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return apperror.WithCode(apperror.CodeUserNotFound, apperror.NewNotFound("user"))
+		}
+
+		removed, err := txGroupRepo.RemoveMember(group.GroupID, user.UserID)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return apperror.NewNotFoundWithReason("user is not a member of the group")
+		}
+
+		return recordEvent(s.eventRepo.WithTx(tx), tenantID, GroupMembersRemoved{
+			GroupUUID: group.GroupUUID,
+			UserUUIDs: []uuid.UUID{user.UserUUID},
+		})
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "remove group member failed")
+		return nil, err
+	}
+
+	s.invalidateMembers(ctx, []model.User{*user})
+
+	span.SetStatus(codes.Ok, "")
+	return toGroupServiceDataResult(group), nil
+}
+
+func (s *groupService) AddRoles(ctx context.Context, groupUUID uuid.UUID, tenantID int64, roleUUIDs []uuid.UUID) (*GroupServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "group.addRoles")
+	defer span.End()
+	span.SetAttributes(attribute.String("group.uuid", groupUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	var updated *model.Group
+	changed := false
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txGroupRepo := s.groupRepo.WithTx(tx)
+
+		group, err := txGroupRepo.FindByUUIDAndTenantID(groupUUID, tenantID)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return apperror.NewNotFoundWithReason("group not found or access denied")
+		}
+
+		roles, err := resolveAssignableRoles(s.roleRepo.WithTx(tx), s.tenantRepo.WithTx(tx), tenantID, roleUUIDs)
+		if err != nil {
+			return err
+		}
+		roleIDs := make([]int64, len(roles))
+		for i, role := range roles {
+			roleIDs[i] = role.RoleID
+		}
+
+		added, err := txGroupRepo.AddRoles(group.GroupID, roleIDs)
+		if err != nil {
+			return err
+		}
+		if len(added) > 0 {
+			isAdded := make(map[int64]bool, len(added))
+			for _, id := range added {
+				isAdded[id] = true
+			}
+			addedUUIDs := make([]uuid.UUID, 0, len(added))
+			for _, role := range roles {
+				if isAdded[role.RoleID] {
+					addedUUIDs = append(addedUUIDs, role.RoleUUID)
+				}
+			}
+			if err := recordEvent(s.eventRepo.WithTx(tx), tenantID, GroupRolesAdded{
+				GroupUUID: group.GroupUUID,
+				RoleUUIDs: addedUUIDs,
+			}); err != nil {
+				return err
+			}
+			changed = true
+		}
+
+		updated, err = txGroupRepo.FindByUUIDAndTenantID(groupUUID, tenantID, "Roles")
+		return err
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "add group roles failed")
+		return nil, err
+	}
+
+	if changed {
+		s.cacheInvalidator.InvalidateAllUsers(ctx)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toGroupServiceDataResult(updated), nil
+}
+
+func (s *groupService) RemoveRole(ctx context.Context, groupUUID uuid.UUID, tenantID int64, roleUUID uuid.UUID) (*GroupServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "group.removeRole")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("group.uuid", groupUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+		attribute.String("role.uuid", roleUUID.String()),
+	)
+
+	var updated *model.Group
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txGroupRepo := s.groupRepo.WithTx(tx)
+
+		group, err := txGroupRepo.FindByUUIDAndTenantID(groupUUID, tenantID)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return apperror.NewNotFoundWithReason("group not found or access denied")
+		}
+
+		role, err := s.roleRepo.WithTx(tx).FindByUUID(roleUUID)
+		if err != nil {
+			return err
+		}
+		if role == nil {
+			return apperror.NewNotFoundWithReason("role not found")
+		}
+
+		removed, err := txGroupRepo.RemoveRole(group.GroupID, role.RoleID)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return apperror.NewNotFoundWithReason("role is not attached to the group")
+		}
+
+		if err := recordEvent(s.eventRepo.WithTx(tx), tenantID, GroupRolesRemoved{
+			GroupUUID: group.GroupUUID,
+			RoleUUIDs: []uuid.UUID{role.RoleUUID},
+		}); err != nil {
+			return err
+		}
+
+		updated, err = txGroupRepo.FindByUUIDAndTenantID(groupUUID, tenantID, "Roles")
+		return err
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "remove group role failed")
+		return nil, err
+	}
+
+	s.cacheInvalidator.InvalidateAllUsers(ctx)
+
+	span.SetStatus(codes.Ok, "")
+	return toGroupServiceDataResult(updated), nil
+}
+
+// invalidateMembers clears the cached user contexts and permission sets of
+// users whose groups changed.
+func (s *groupService) invalidateMembers(ctx context.Context, users []model.User) {
+	seen := make(map[string]struct{})
+	for _, user := range users {
+		s.cacheInvalidator.InvalidateUserPermissions(ctx, user.UserUUID)
+		for _, identity := range user.UserIdentities {
+			if _, ok := seen[identity.Sub]; ok {
+				continue
+			}
+			seen[identity.Sub] = struct{}{}
+			s.cacheInvalidator.InvalidateUserAll(ctx, identity.Sub)
+		}
+	}
+}
+
+// resolveGroupMembers looks up the users to add to a group, in request order.
+// Every user must exist and have an identity in the tenant.
+func resolveGroupMembers(userRepo repository.UserRepository, tenantID int64, userUUIDs []uuid.UUID) ([]model.User, error) {
+	userUUIDs = uniqueUUIDs(userUUIDs)
+	if len(userUUIDs) == 0 {
+		return nil, nil
+	}
+
+	uuidStrings := make([]string, len(userUUIDs))
+	for i, id := range userUUIDs {
+		uuidStrings[i] = id.String()
+	}
+	found, err := userRepo.FindByUUIDs(uuidStrings, "UserIdentities")
+	if err != nil {
+		return nil, err
+	}
+
+	byUUID := make(map[uuid.UUID]model.User, len(found))
+	for _, user := range found {
+		if userInTenant(&user, tenantID) {
+			byUUID[user.UserUUID] = user
+		}
+	}
+	users := make([]model.User, 0, len(userUUIDs))
+	for _, id := range userUUIDs {
+		user, ok := byUUID[id]
+		if !ok {
+			return nil, apperror.NewNotFoundWithReason("user not found: " + id.String())
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// resolveAssignableRoles looks up roles to grant in the tenant through a group
+// or a scoped assignment, in request order. As with direct assignment, a role
+// must belong to the tenant or be an inheritable role of a tenant above it.
+func resolveAssignableRoles(roleRepo repository.RoleRepository, tenantRepo repository.TenantRepository, tenantID int64, roleUUIDs []uuid.UUID) ([]model.Role, error) {
+	roleUUIDs = uniqueUUIDs(roleUUIDs)
+	if len(roleUUIDs) == 0 {
+		return nil, nil
+	}
+
+	uuidStrings := make([]string, len(roleUUIDs))
+	for i, id := range roleUUIDs {
+		uuidStrings[i] = id.String()
+	}
+	found, err := roleRepo.FindByUUIDs(uuidStrings)
+	if err != nil {
+		return nil, err
+	}
+	byUUID := make(map[uuid.UUID]model.Role, len(found))
+	for _, role := range found {
+		byUUID[role.RoleUUID] = role
+	}
+
+	var tenant *model.Tenant
+	roles := make([]model.Role, 0, len(roleUUIDs))
+	for _, id := range roleUUIDs {
+		role, ok := byUUID[id]
+		if !ok {
+			return nil, apperror.NewNotFoundWithReason("role not found: " + id.String())
+		}
+		if role.TenantID != tenantID {
+			if tenant == nil {
+				tenant, err = tenantRepo.FindByID(tenantID)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if tenant == nil || !role.IsInheritable || !tenant.HasAncestor(role.TenantID) {
+				return nil, apperror.NewNotFoundWithReason("role not found: " + id.String())
+			}
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+// withGroupRoles returns the user's direct roles followed by the roles they
+// hold only through groups, with the given preloads.
+func withGroupRoles(userRepo repository.UserRepository, user *model.User, preloads ...string) ([]model.Role, error) {
+	groupRoles, err := userRepo.FindGroupRoles(user.UserID, preloads...)
+	if err != nil {
+		return nil, err
+	}
+	if len(groupRoles) == 0 {
+		return user.Roles, nil
+	}
+
+	held := make(map[int64]bool, len(user.Roles))
+	roles := make([]model.Role, 0, len(user.Roles)+len(groupRoles))
+	for _, role := range user.Roles {
+		held[role.RoleID] = true
+		roles = append(roles, role)
+	}
+	for _, role := range groupRoles {
+		if !held[role.RoleID] {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+func toGroupServiceDataResult(group *model.Group) *GroupServiceDataResult {
+	roles := make([]RoleServiceDataResult, len(group.Roles))
+	for i := range group.Roles {
+		roles[i] = *toRoleServiceDataResult(&group.Roles[i])
+	}
+	return &GroupServiceDataResult{
+		GroupUUID:   group.GroupUUID,
+		Name:        group.Name,
+		Description: group.Description,
+		Roles:       roles,
+		CreatedAt:   group.CreatedAt,
+		UpdatedAt:   group.UpdatedAt,
+	}
+}
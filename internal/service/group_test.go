@@ -0,0 +1,547 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newUserGroup(tenantID int64, name string, roles ...model.Role) *model.Group {
+	return &model.Group{
+		GroupID:   1,
+		GroupUUID: uuid.New(),
+		TenantID:  tenantID,
+		Name:      name,
+		Roles:     roles,
+	}
+}
+
+func newGroupService(db *gorm.DB, groupRepo *mockGroupRepo, userRepo *mockUserRepo, roleRepo *mockRoleRepo, tenantRepo *mockTenantRepo, eventRepo *mockEventRepo) GroupService {
+	return NewGroupService(db, groupRepo, userRepo, roleRepo, tenantRepo, eventRepo, cache.NopInvalidator{})
+}
+
+// ---------------------------------------------------------------------------
+// Get / GetByUUID
+// ---------------------------------------------------------------------------
+
+func TestGroupService_Get(t *testing.T) {
+	tenantID := int64(1)
+	group := newUserGroup(tenantID, "support", model.Role{RoleID: 3, RoleUUID: uuid.New(), Name: "agent"})
+
+	t.Run("lists groups with their roles", func(t *testing.T) {
+		repo := &mockGroupRepo{
+			findPaginatedFn: func(f repository.GroupRepositoryGetFilter) (*repository.PaginationResult[model.Group], error) {
+				assert.Equal(t, tenantID, f.TenantID)
+				assert.Nil(t, f.UserID)
+				return &repository.PaginationResult[model.Group]{
+					Data: []model.Group{*group}, Total: 1, Page: 1, Limit: 10, TotalPages: 1,
+				}, nil
+			},
+		}
+		svc := newGroupService(nil, repo, &mockUserRepo{}, &mockRoleRepo{}, &mockTenantRepo{}, &mockEventRepo{})
+
+		result, err := svc.Get(context.Background(), GroupServiceGetFilter{TenantID: tenantID, Page: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, result.Data, 1)
+		assert.Equal(t, "support", result.Data[0].Name)
+		require.Len(t, result.Data[0].Roles, 1)
+		assert.Equal(t, "agent", result.Data[0].Roles[0].Name)
+	})
+
+	t.Run("filters by member", func(t *testing.T) {
+		user := &model.User{UserID: 9, UserUUID: uuid.New(), UserIdentities: []model.UserIdentity{{TenantID: tenantID}}}
+		repo := &mockGroupRepo{
+			findPaginatedFn: func(f repository.GroupRepositoryGetFilter) (*repository.PaginationResult[model.Group], error) {
+				require.NotNil(t, f.UserID)
+				assert.Equal(t, int64(9), *f.UserID)
+				return &repository.PaginationResult[model.Group]{}, nil
+			},
+		}
+		userRepo := &mockUserRepo{findByUUIDFn: func(any, ...string) (*model.User, error) { return user, nil }}
+		svc := newGroupService(nil, repo, userRepo, &mockRoleRepo{}, &mockTenantRepo{}, &mockEventRepo{})
+
+		_, err := svc.Get(context.Background(), GroupServiceGetFilter{TenantID: tenantID, UserUUID: &user.UserUUID})
+		require.NoError(t, err)
+	})
+
+	t.Run("member of another tenant", func(t *testing.T) {
+		user := &model.User{UserID: 9, UserUUID: uuid.New(), UserIdentities: []model.UserIdentity{{TenantID: 2}}}
+		userRepo := &mockUserRepo{findByUUIDFn: func(any, ...string) (*model.User, error) { return user, nil }}
+		svc := newGroupService(nil, &mockGroupRepo{}, userRepo, &mockRoleRepo{}, &mockTenantRepo{}, &mockEventRepo{})
+
+		_, err := svc.Get(context.Background(), GroupServiceGetFilter{TenantID: tenantID, UserUUID: &user.UserUUID})
+		code, ok := apperror.CodeOf(err)
+		require.True(t, ok)
+		assert.Equal(t, apperror.CodeUserNotFound, code)
+	})
+}
+
+func TestGroupService_GetByUUID(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		svc := newGroupService(nil, &mockGroupRepo{}, &mockUserRepo{}, &mockRoleRepo{}, &mockTenantRepo{}, &mockEventRepo{})
+		_, err := svc.GetByUUID(context.Background(), uuid.New(), 1)
+		var notFound *apperror.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo := &mockGroupRepo{
+			findByUUIDAndTenantIDFn: func(uuid.UUID, int64) (*model.Group, error) {
+				return nil, errors.New("db error")
+			},
+		}
+		svc := newGroupService(nil, repo, &mockUserRepo{}, &mockRoleRepo{}, &mockTenantRepo{}, &mockEventRepo{})
+		_, err := svc.GetByUUID(context.Background(), uuid.New(), 1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "db error")
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Create / Update / DeleteByUUID
+// ---------------------------------------------------------------------------
+
+func TestGroupService_Create(t *testing.T) {
+	tenantID := int64(1)
+
+	t.Run("duplicate name", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		repo := &mockGroupRepo{
+			findByNameFn: func(string, int64) (*model.Group, error) { return newUserGroup(tenantID, "support"), nil },
+		}
+		svc := newGroupService(gormDB, repo, &mockUserRepo{}, &mockRoleRepo{}, &mockTenantRepo{}, &mockEventRepo{})
+
+		_, err := svc.Create(context.Background(), tenantID, "support", "")
+		var conflict *apperror.ConflictError
+		assert.ErrorAs(t, err, &conflict)
+	})
+
+	t.Run("success records group.created", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		events := &mockEventRepo{}
+		svc := newGroupService(gormDB, &mockGroupRepo{}, &mockUserRepo{}, &mockRoleRepo{}, &mockTenantRepo{}, events)
+
+		result, err := svc.Create(context.Background(), tenantID, "support", "Support agents")
+		require.NoError(t, err)
+		assert.Equal(t, "support", result.Name)
+		assert.Empty(t, result.Roles)
+		require.Len(t, events.created, 1)
+		assert.Equal(t, model.EventTypeGroupCreated, events.created[0].EventType)
+		assert.Equal(t, model.EventAggregateGroup, events.created[0].AggregateType)
+		assert.Equal(t, result.GroupUUID, events.created[0].AggregateUUID)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGroupService_Update(t *testing.T) {
+	tenantID := int64(1)
+	group := newUserGroup(tenantID, "support")
+
+	t.Run("name taken by another group", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		repo := &mockGroupRepo{
+			findByUUIDAndTenantIDFn: func(uuid.UUID, int64) (*model.Group, error) { return group, nil },
+			findByNameFn: func(string, int64) (*model.Group, error) {
+				return newUserGroup(tenantID, "engineering"), nil
+			},
+		}
+		svc := newGroupService(gormDB, repo, &mockUserRepo{}, &mockRoleRepo{}, &mockTenantRepo{}, &mockEventRepo{})
+		_, err := svc.Update(context.Background(), group.GroupUUID, tenantID, "engineering", "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already exists")
+	})
+
+	t.Run("success writes empty description", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		var written map[string]any
+		events := &mockEventRepo{}
+		repo := &mockGroupRepo{
+			findByUUIDAndTenantIDFn: func(uuid.UUID, int64) (*model.Group, error) { return group, nil },
+			updateByUUIDFn: func(_ any, data any) (*model.Group, error) {
+				written = data.(map[string]any)
+				return group, nil
+			},
+		}
+		svc := newGroupService(gormDB, repo, &mockUserRepo{}, &mockRoleRepo{}, &mockTenantRepo{}, events)
+		_, err := svc.Update(context.Background(), group.GroupUUID, tenantID, "support", "")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "support", "description": ""}, written)
+		require.Len(t, events.created, 1)
+		assert.Equal(t, model.EventTypeGroupUpdated, events.created[0].EventType)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGroupService_DeleteByUUID(t *testing.T) {
+	tenantID := int64(1)
+
+	t.Run("not found", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := newGroupService(gormDB, &mockGroupRepo{}, &mockUserRepo{}, &mockRoleRepo{}, &mockTenantRepo{}, &mockEventRepo{})
+		_, err := svc.DeleteByUUID(context.Background(), uuid.New(), tenantID)
+		var notFound *apperror.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("success records group.deleted", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		group := newUserGroup(tenantID, "support")
+		deleted := false
+		events := &mockEventRepo{}
+		repo := &mockGroupRepo{
+			findByUUIDAndTenantIDFn: func(uuid.UUID, int64) (*model.Group, error) { return group, nil },
+			deleteFn: func(id uuid.UUID, tid int64) error {
+				assert.Equal(t, group.GroupUUID, id)
+				assert.Equal(t, tenantID, tid)
+				deleted = true
+				return nil
+			},
+		}
+		svc := newGroupService(gormDB, repo, &mockUserRepo{}, &mockRoleRepo{}, &mockTenantRepo{}, events)
+		result, err := svc.DeleteByUUID(context.Background(), group.GroupUUID, tenantID)
+		require.NoError(t, err)
+		assert.True(t, deleted)
+		assert.Equal(t, "support", result.Name)
+		require.Len(t, events.created, 1)
+		assert.Equal(t, model.EventTypeGroupDeleted, events.created[0].EventType)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Members
+// ---------------------------------------------------------------------------
+
+func TestGroupService_GetMembers(t *testing.T) {
+	group := newUserGroup(1, "support")
+	repo := &mockGroupRepo{
+		findByUUIDAndTenantIDFn: func(uuid.UUID, int64) (*model.Group, error) { return group, nil },
+		findMembersPaginatedFn: func(id int64, page, limit int) (*repository.PaginationResult[model.User], error) {
+			assert.Equal(t, group.GroupID, id)
+			assert.Equal(t, 2, page)
+			assert.Equal(t, 5, limit)
+			return &repository.PaginationResult[model.User]{
+				Data: []model.User{{UserUUID: uuid.New(), Username: "alice"}}, Total: 6, Page: 2, Limit: 5, TotalPages: 2,
+			}, nil
+		},
+	}
+	svc := newGroupService(nil, repo, &mockUserRepo{}, &mockRoleRepo{}, &mockTenantRepo{}, &mockEventRepo{})
+
+	result, err := svc.GetMembers(context.Background(), group.GroupUUID, 1, 2, 5)
+	require.NoError(t, err)
+	require.Len(t, result.Data, 1)
+	assert.Equal(t, "alice", result.Data[0].Username)
+	assert.Equal(t, int64(6), result.Total)
+}
+
+func TestGroupService_AddMembers(t *testing.T) {
+	tenantID := int64(1)
+	alice := model.User{UserID: 10, UserUUID: uuid.New(), UserIdentities: []model.UserIdentity{{TenantID: tenantID, Sub: "alice"}}}
+	bob := model.User{UserID: 11, UserUUID: uuid.New(), UserIdentities: []model.UserIdentity{{TenantID: tenantID, Sub: "bob"}}}
+	outsider := model.User{UserID: 12, UserUUID: uuid.New(), UserIdentities: []model.UserIdentity{{TenantID: 2}}}
+
+	cases := []struct {
+		name       string
+		request    []uuid.UUID
+		existing   []int64
+		wantErr    string
+		wantIDs    []int64
+		wantEvents []uuid.UUID
+	}{
+		{
+			name:    "user of another tenant",
+			request: []uuid.UUID{alice.UserUUID, outsider.UserUUID},
+			wantErr: "user not found: " + outsider.UserUUID.String(),
+		},
+		{
+			name:    "unknown user",
+			request: []uuid.UUID{uuid.Nil},
+			wantErr: "user not found",
+		},
+		{
+			name:       "adds new members and skips existing ones",
+			request:    []uuid.UUID{alice.UserUUID, bob.UserUUID, alice.UserUUID},
+			existing:   []int64{10},
+			wantIDs:    []int64{10, 11},
+			wantEvents: []uuid.UUID{bob.UserUUID},
+		},
+		{
+			name:     "all already members",
+			request:  []uuid.UUID{alice.UserUUID},
+			existing: []int64{10},
+			wantIDs:  []int64{10},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gormDB, mock := newMockGormDB(t)
+			mock.ExpectBegin()
+			if tc.wantErr != "" {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+
+			group := newUserGroup(tenantID, "support")
+			var requested []int64
+			groupRepo := &mockGroupRepo{
+				findByUUIDAndTenantIDFn: func(uuid.UUID, int64) (*model.Group, error) { return group, nil },
+				addMembersFn: func(id int64, userIDs []int64) ([]int64, error) {
+					assert.Equal(t, group.GroupID, id)
+					requested = userIDs
+					var added []int64
+					for _, id := range userIDs {
+						if !slices.Contains(tc.existing, id) {
+							added = append(added, id)
+						}
+					}
+					return added, nil
+				},
+			}
+			userRepo := &mockUserRepo{
+				findByUUIDsFn: func(ids []string, _ ...string) ([]model.User, error) {
+					var found []model.User
+					for _, u := range []model.User{alice, bob, outsider} {
+						for _, id := range ids {
+							if u.UserUUID.String() == id {
+								found = append(found, u)
+							}
+						}
+					}
+					return found, nil
+				},
+			}
+			events := &mockEventRepo{}
+			svc := newGroupService(gormDB, groupRepo, userRepo, &mockRoleRepo{}, &mockTenantRepo{}, events)
+
+			_, err := svc.AddMembers(context.Background(), group.GroupUUID, tenantID, tc.request)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				assert.Empty(t, events.created)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantIDs, requested)
+			if tc.wantEvents == nil {
+				assert.Empty(t, events.created)
+			} else {
+				require.Len(t, events.created, 1)
+				assert.Equal(t, model.EventTypeGroupMembersAdded, events.created[0].EventType)
+				var payload groupMembersEventPayload
+				require.NoError(t, json.Unmarshal(events.created[0].Payload, &payload))
+				assert.Equal(t, tc.wantEvents, payload.UserUUIDs)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGroupService_RemoveMember(t *testing.T) {
+	tenantID := int64(1)
+	group := newUserGroup(tenantID, "support")
+	user := &model.User{UserID: 10, UserUUID: uuid.New()}
+
+	t.Run("not a member", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		groupRepo := &mockGroupRepo{
+			findByUUIDAndTenantIDFn: func(uuid.UUID, int64) (*model.Group, error) { return group, nil },
+			removeMemberFn:          func(int64, int64) (bool, error) { return false, nil },
+		}
+		userRepo := &mockUserRepo{findByUUIDFn: func(any, ...string) (*model.User, error) { return user, nil }}
+		svc := newGroupService(gormDB, groupRepo, userRepo, &mockRoleRepo{}, &mockTenantRepo{}, &mockEventRepo{})
+
+		_, err := svc.RemoveMember(context.Background(), group.GroupUUID, tenantID, user.UserUUID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a member")
+	})
+
+	t.Run("success records group.members_removed", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		groupRepo := &mockGroupRepo{
+			findByUUIDAndTenantIDFn: func(uuid.UUID, int64) (*model.Group, error) { return group, nil },
+			removeMemberFn: func(gid, uid int64) (bool, error) {
+				assert.Equal(t, group.GroupID, gid)
+				assert.Equal(t, user.UserID, uid)
+				return true, nil
+			},
+		}
+		userRepo := &mockUserRepo{findByUUIDFn: func(any, ...string) (*model.User, error) { return user, nil }}
+		events := &mockEventRepo{}
+		svc := newGroupService(gormDB, groupRepo, userRepo, &mockRoleRepo{}, &mockTenantRepo{}, events)
+
+		_, err := svc.RemoveMember(context.Background(), group.GroupUUID, tenantID, user.UserUUID)
+		require.NoError(t, err)
+		require.Len(t, events.created, 1)
+		assert.Equal(t, model.EventTypeGroupMembersRemoved, events.created[0].EventType)
+		var payload groupMembersEventPayload
+		require.NoError(t, json.Unmarshal(events.created[0].Payload, &payload))
+		assert.Equal(t, []uuid.UUID{user.UserUUID}, payload.UserUUIDs)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Roles
+// ---------------------------------------------------------------------------
+
+func TestGroupService_AddRoles(t *testing.T) {
+	tenantID := int64(1)
+	own := model.Role{RoleID: 20, RoleUUID: uuid.New(), TenantID: tenantID, Name: "agent"}
+	inherited := model.Role{RoleID: 21, RoleUUID: uuid.New(), TenantID: 5, Name: "auditor", IsInheritable: true}
+	notInheritable := model.Role{RoleID: 22, RoleUUID: uuid.New(), TenantID: 5, Name: "owner"}
+	unrelated := model.Role{RoleID: 23, RoleUUID: uuid.New(), TenantID: 6, Name: "viewer", IsInheritable: true}
+
+	cases := []struct {
+		name    string
+		request []uuid.UUID
+		wantErr string
+		wantIDs []int64
+	}{
+		{name: "own and inherited roles", request: []uuid.UUID{own.RoleUUID, inherited.RoleUUID}, wantIDs: []int64{20, 21}},
+		{name: "ancestor role that is not inheritable", request: []uuid.UUID{notInheritable.RoleUUID}, wantErr: "role not found"},
+		{name: "inheritable role of an unrelated tenant", request: []uuid.UUID{unrelated.RoleUUID}, wantErr: "role not found"},
+		{name: "unknown role", request: []uuid.UUID{uuid.New()}, wantErr: "role not found"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gormDB, mock := newMockGormDB(t)
+			mock.ExpectBegin()
+			if tc.wantErr != "" {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+
+			group := newUserGroup(tenantID, "support")
+			var attached []int64
+			groupRepo := &mockGroupRepo{
+				findByUUIDAndTenantIDFn: func(uuid.UUID, int64) (*model.Group, error) { return group, nil },
+				addRolesFn: func(_ int64, roleIDs []int64) ([]int64, error) {
+					attached = roleIDs
+					return roleIDs, nil
+				},
+			}
+			roleRepo := &mockRoleRepo{
+				findByUUIDsFn: func(ids []string, _ ...string) ([]model.Role, error) {
+					var found []model.Role
+					for _, r := range []model.Role{own, inherited, notInheritable, unrelated} {
+						for _, id := range ids {
+							if r.RoleUUID.String() == id {
+								found = append(found, r)
+							}
+						}
+					}
+					return found, nil
+				},
+			}
+			tenantRepo := &mockTenantRepo{
+				findByIDFn: func(any, ...string) (*model.Tenant, error) {
+					return &model.Tenant{TenantID: tenantID, AncestorPath: "/5/"}, nil
+				},
+			}
+			events := &mockEventRepo{}
+			svc := newGroupService(gormDB, groupRepo, &mockUserRepo{}, roleRepo, tenantRepo, events)
+
+			_, err := svc.AddRoles(context.Background(), group.GroupUUID, tenantID, tc.request)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantIDs, attached)
+			require.Len(t, events.created, 1)
+			assert.Equal(t, model.EventTypeGroupRolesAdded, events.created[0].EventType)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGroupService_RemoveRole(t *testing.T) {
+	tenantID := int64(1)
+	group := newUserGroup(tenantID, "support")
+	role := &model.Role{RoleID: 20, RoleUUID: uuid.New(), TenantID: tenantID}
+
+	t.Run("role not attached", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		groupRepo := &mockGroupRepo{
+			findByUUIDAndTenantIDFn: func(uuid.UUID, int64) (*model.Group, error) { return group, nil },
+			removeRoleFn:            func(int64, int64) (bool, error) { return false, nil },
+		}
+		roleRepo := &mockRoleRepo{findByUUIDFn: func(any, ...string) (*model.Role, error) { return role, nil }}
+		svc := newGroupService(gormDB, groupRepo, &mockUserRepo{}, roleRepo, &mockTenantRepo{}, &mockEventRepo{})
+
+		_, err := svc.RemoveRole(context.Background(), group.GroupUUID, tenantID, role.RoleUUID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not attached")
+	})
+
+	t.Run("success records group.roles_removed", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		groupRepo := &mockGroupRepo{
+			findByUUIDAndTenantIDFn: func(uuid.UUID, int64) (*model.Group, error) { return group, nil },
+		}
+		roleRepo := &mockRoleRepo{findByUUIDFn: func(any, ...string) (*model.Role, error) { return role, nil }}
+		events := &mockEventRepo{}
+		svc := newGroupService(gormDB, groupRepo, &mockUserRepo{}, roleRepo, &mockTenantRepo{}, events)
+
+		_, err := svc.RemoveRole(context.Background(), group.GroupUUID, tenantID, role.RoleUUID)
+		require.NoError(t, err)
+		require.Len(t, events.created, 1)
+		assert.Equal(t, model.EventTypeGroupRolesRemoved, events.created[0].EventType)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWithGroupRoles(t *testing.T) {
+	direct := model.Role{RoleID: 1, Name: "editor"}
+	viaGroup := model.Role{RoleID: 2, Name: "agent"}
+	user := &model.User{UserID: 7, Roles: []model.Role{direct}}
+
+	repo := &mockUserRepo{findGroupRolesFn: func(userID int64) ([]model.Role, error) {
+		assert.Equal(t, int64(7), userID)
+		return []model.Role{direct, viaGroup}, nil
+	}}
+	roles, err := withGroupRoles(repo, user, "Permissions")
+	require.NoError(t, err)
+	assert.Equal(t, []model.Role{direct, viaGroup}, roles, "roles held both ways are listed once")
+
+	_, err = withGroupRoles(&mockUserRepo{findGroupRolesFn: func(int64) ([]model.Role, error) {
+		return nil, errors.New("db down")
+	}}, user)
+	require.Error(t, err)
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/federation"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// HomeRealmService routes users to the identity provider of their email
+// domain (home-realm discovery).
+type HomeRealmService interface {
+	// Identify returns the identity provider and login method for email
+	// among the providers of the tenant of the auth client identified by
+	// clientID and providerID. The answer depends on the domain only, so it
+	// does not reveal whether an account exists.
+	Identify(ctx context.Context, email, clientID, providerID string) (*dto.HomeRealmResponseDTO, error)
+}
+
+type homeRealmService struct {
+	clientRepo repository.ClientRepository
+	idpRepo    repository.IdentityProviderRepository
+}
+
+// NewHomeRealmService creates a new HomeRealmService.
+func NewHomeRealmService(
+	clientRepo repository.ClientRepository,
+	idpRepo repository.IdentityProviderRepository,
+) HomeRealmService {
+	return &homeRealmService{
+		clientRepo: clientRepo,
+		idpRepo:    idpRepo,
+	}
+}
+
+// Identify implements HomeRealmService. Providers are checked oldest first;
+// without a matching domain the user signs in with a password at the
+// requested provider.
+func (s *homeRealmService) Identify(ctx context.Context, email, clientID, providerID string) (*dto.HomeRealmResponseDTO, error) {
+	_, span := otel.Tracer("service").Start(ctx, "home_realm.identify")
+	defer span.End()
+	span.SetAttributes(attribute.String("client.id", clientID), attribute.String("idp.identifier", providerID))
+
+	client, err := s.clientRepo.FindByClientIDAndIdentityProvider(clientID, providerID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find client failed")
+		return nil, apperror.NewInternal("failed to load auth client", err)
+	}
+	if client == nil || client.IdentityProvider == nil {
+		span.SetStatus(codes.Error, "client not found")
+		return nil, apperror.NewUnauthorized("invalid client or identity provider")
+	}
+
+	idps, err := s.idpRepo.FindActiveByTenantID(client.IdentityProvider.TenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find identity providers failed")
+		return nil, apperror.NewInternal("failed to load identity providers", err)
+	}
+
+	for _, idp := range idps {
+		// Invalid configs are rejected on save; skip one stored before
+		realm, err := federation.RealmFromConfig(idp.Config)
+		if err != nil || realm == nil || !realm.Matches(email) {
+			continue
+		}
+		span.SetAttributes(attribute.String("home_realm.identifier", idp.Identifier))
+		span.SetStatus(codes.Ok, "")
+		return &dto.HomeRealmResponseDTO{
+			Method:      dto.LoginMethodFederated,
+			ProviderID:  idp.Identifier,
+			Provider:    idp.Provider,
+			DisplayName: idp.DisplayName,
+			Issuer:      realm.Issuer,
+		}, nil
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return &dto.HomeRealmResponseDTO{
+		Method:      dto.LoginMethodPassword,
+		ProviderID:  client.IdentityProvider.Identifier,
+		Provider:    client.IdentityProvider.Provider,
+		DisplayName: client.IdentityProvider.DisplayName,
+	}, nil
+}
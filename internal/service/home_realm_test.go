@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func TestHomeRealmService_Identify(t *testing.T) {
+	internal := &model.IdentityProvider{
+		TenantID:    1,
+		Identifier:  "idp-internal",
+		Provider:    model.IDPProviderInternal,
+		DisplayName: "Maintainerd",
+		Status:      model.StatusActive,
+	}
+	federated := func(identifier, domains string) model.IdentityProvider {
+		return model.IdentityProvider{
+			TenantID:    1,
+			Identifier:  identifier,
+			Provider:    model.IDPProviderGoogle,
+			DisplayName: "Google Workspace",
+			Status:      model.StatusActive,
+			Config: datatypes.JSON(`{"issuer":"https://accounts.google.com",` +
+				`"jwks_uri":"https://www.googleapis.com/oauth2/v3/certs","domains":` + domains + `}`),
+		}
+	}
+	clientRepo := &mockClientRepo{
+		findByClientIDAndIdentityProviderFn: func(clientID, providerID string) (*model.Client, error) {
+			if clientID != "web" || providerID != "idp-internal" {
+				return nil, nil
+			}
+			return &model.Client{ClientID: 10, IdentityProvider: internal}, nil
+		},
+	}
+	idpRepo := func(idps ...model.IdentityProvider) *mockIdentityProviderRepo {
+		return &mockIdentityProviderRepo{
+			findActiveByTenantIDFn: func(tenantID int64) ([]model.IdentityProvider, error) {
+				require.Equal(t, int64(1), tenantID)
+				return idps, nil
+			},
+		}
+	}
+
+	t.Run("matching domain routes to the federated provider", func(t *testing.T) {
+		svc := NewHomeRealmService(clientRepo, idpRepo(*internal,
+			federated("idp-other", `["other.com"]`),
+			federated("idp-google", `["example.com"]`),
+			federated("idp-newer", `["example.com"]`),
+		))
+		res, err := svc.Identify(context.Background(), "Jane@Example.com", "web", "idp-internal")
+		require.NoError(t, err)
+		assert.Equal(t, &dto.HomeRealmResponseDTO{
+			Method:      dto.LoginMethodFederated,
+			ProviderID:  "idp-google",
+			Provider:    model.IDPProviderGoogle,
+			DisplayName: "Google Workspace",
+			Issuer:      "https://accounts.google.com",
+		}, res)
+	})
+
+	t.Run("other domains sign in with a password", func(t *testing.T) {
+		svc := NewHomeRealmService(clientRepo, idpRepo(*internal, federated("idp-google", `["example.com"]`)))
+		res, err := svc.Identify(context.Background(), "jane@sub.example.com", "web", "idp-internal")
+		require.NoError(t, err)
+		assert.Equal(t, &dto.HomeRealmResponseDTO{
+			Method:      dto.LoginMethodPassword,
+			ProviderID:  "idp-internal",
+			Provider:    model.IDPProviderInternal,
+			DisplayName: "Maintainerd",
+		}, res)
+	})
+
+	t.Run("invalid stored config is skipped", func(t *testing.T) {
+		broken := federated("idp-broken", `["a@example.com"]`)
+		svc := NewHomeRealmService(clientRepo, idpRepo(broken))
+		res, err := svc.Identify(context.Background(), "jane@example.com", "web", "idp-internal")
+		require.NoError(t, err)
+		assert.Equal(t, dto.LoginMethodPassword, res.Method)
+	})
+
+	t.Run("unknown client", func(t *testing.T) {
+		svc := NewHomeRealmService(clientRepo, idpRepo())
+		_, err := svc.Identify(context.Background(), "jane@example.com", "other", "idp-internal")
+		assert.ErrorAs(t, err, new(*apperror.UnauthorizedError))
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		svc := NewHomeRealmService(clientRepo, &mockIdentityProviderRepo{
+			findActiveByTenantIDFn: func(int64) ([]model.IdentityProvider, error) { return nil, errors.New("db down") },
+		})
+		_, err := svc.Identify(context.Background(), "jane@example.com", "web", "idp-internal")
+		assert.ErrorAs(t, err, new(*apperror.InternalError))
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/apperror"
 	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/federation"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/repository"
 	"go.opentelemetry.io/otel"
@@ -163,6 +164,11 @@ func (s *identityProviderService) Create(ctx context.Context, name string, displ
 		attribute.String("idp.name", name),
 	)
 
+	if err := validateFederationConfig(config); err != nil {
+		span.SetStatus(codes.Error, "invalid federation config")
+		return nil, err
+	}
+
 	var createdIdp *model.IdentityProvider
 
 	err := s.db.Transaction(func(tx *gorm.DB) error {
@@ -260,6 +266,11 @@ func (s *identityProviderService) Update(ctx context.Context, idpUUID uuid.UUID,
 		attribute.Int64("tenant.id", tenantID),
 	)
 
+	if err := validateFederationConfig(config); err != nil {
+		span.SetStatus(codes.Error, "invalid federation config")
+		return nil, err
+	}
+
 	var updatedIdp *model.IdentityProvider
 
 	err := s.db.Transaction(func(tx *gorm.DB) error {
@@ -461,6 +472,18 @@ func (s *identityProviderService) DeleteByUUID(ctx context.Context, idpUUID uuid
 	return toIdpServiceDataResult(idp), nil
 }
 
+// validateFederationConfig rejects a config whose just-in-time provisioning
+// rules or home realm domains would fail federated sign in.
+func validateFederationConfig(config datatypes.JSON) error {
+	if _, err := federation.RulesFromConfig(config); err != nil {
+		return apperror.NewValidation(err.Error())
+	}
+	if _, err := federation.RealmFromConfig(config); err != nil {
+		return apperror.NewValidation(err.Error())
+	}
+	return nil
+}
+
 // Reponse builder
 func toIdpServiceDataResult(idp *model.IdentityProvider) *IdentityProviderServiceDataResult {
 	if idp == nil {
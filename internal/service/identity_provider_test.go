@@ -263,6 +263,24 @@ func TestIdentityProviderService_Create(t *testing.T) {
 	tenantUUID := uuid.New()
 	cfg := datatypes.JSON([]byte(`{}`))
 
+	t.Run("invalid provisioning rules", func(t *testing.T) {
+		gormDB, _ := newMockGormDB(t)
+		svc := NewIdentityProviderService(gormDB, &mockIdentityProviderRepo{}, &mockTenantRepo{}, &mockUserRepo{})
+		_, err := svc.Create(context.Background(), "idp", "IDP", "google", "oidc",
+			datatypes.JSON(`{"provisioning":{"mode":"always"}}`), "active", tenantUUID.String(), tenantID, actorUUID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "provisioning mode")
+	})
+
+	t.Run("home realm domains without issuer", func(t *testing.T) {
+		gormDB, _ := newMockGormDB(t)
+		svc := NewIdentityProviderService(gormDB, &mockIdentityProviderRepo{}, &mockTenantRepo{}, &mockUserRepo{})
+		_, err := svc.Create(context.Background(), "idp", "IDP", "google", "oidc",
+			datatypes.JSON(`{"domains":["example.com"]}`), "active", tenantUUID.String(), tenantID, actorUUID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "require an issuer")
+	})
+
 	t.Run("invalid tenant UUID", func(t *testing.T) {
 		gormDB, mock := newMockGormDB(t)
 		mock.ExpectBegin()
@@ -519,6 +537,15 @@ func TestIdentityProviderService_Update(t *testing.T) {
 	actorUUID := uuid.New()
 	cfg := datatypes.JSON([]byte(`{}`))
 
+	t.Run("invalid provisioning rules", func(t *testing.T) {
+		gormDB, _ := newMockGormDB(t)
+		svc := NewIdentityProviderService(gormDB, &mockIdentityProviderRepo{}, &mockTenantRepo{}, &mockUserRepo{})
+		_, err := svc.Update(context.Background(), idpUUID, "n", "d", "google", "oidc",
+			datatypes.JSON(`{"provisioning":{"allowed_domains":["a@example.com"]}}`), "active", tenantID, actorUUID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a domain")
+	})
+
 	t.Run("idp not found", func(t *testing.T) {
 		gormDB, mock := newMockGormDB(t)
 		mock.ExpectBegin()
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newImpersonationService returns an ImpersonationService where target has
+// identity on "test-client" of tenant 1.
+func newImpersonationService(db *gorm.DB, target *model.User, identity *model.UserIdentity, eventRepo *mockAuthEventRepo) ImpersonationService {
+	userRepo := &mockUserRepo{findByUUIDFn: func(id any, _ ...string) (*model.User, error) {
+		if id == target.UserUUID {
+			return target, nil
+		}
+		return nil, nil
+	}}
+	identityRepo := &mockUserIdentityRepo{findByUserIDAndClientIDFn: func(userID, _ int64) (*model.UserIdentity, error) {
+		if userID == identity.UserID {
+			return identity, nil
+		}
+		return nil, nil
+	}}
+	clientRepo := &mockClientRepo{findByClientIDAndIdentityProviderFn: func(clientID, _ string) (*model.Client, error) {
+		if clientID == "test-client" {
+			client := buildActiveClient()
+			client.IdentityProvider.TenantID = 1
+			return client, nil
+		}
+		return nil, nil
+	}}
+	return NewImpersonationService(db, clientRepo, userRepo, identityRepo, eventRepo)
+}
+
+// newImpersonationInput returns a request of admin to impersonate target.
+func newImpersonationInput(admin, target *model.User) ImpersonationInput {
+	return ImpersonationInput{
+		TenantID:   1,
+		Actor:      admin,
+		ActorSub:   "admin-sub",
+		ClientID:   "test-client",
+		ProviderID: "test-provider",
+		TargetUUID: target.UserUUID,
+		Reason:     "support ticket 42",
+	}
+}
+
+func TestImpersonationService_Impersonate(t *testing.T) {
+	initTestJWTKeysService(t)
+	admin := &model.User{UserID: 1, UserUUID: uuid.New(), Username: "admin"}
+
+	t.Run("issues audited token with act and banner claims", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		target := &model.User{UserID: 2, UserUUID: uuid.New(), Username: "jane", Status: model.StatusActive}
+		identity := &model.UserIdentity{UserID: 2, ClientID: 1, TenantID: 1, Sub: uuid.NewString()}
+		var events []*model.AuthEvent
+		eventRepo := &mockAuthEventRepo{createFn: func(e *model.AuthEvent) (*model.AuthEvent, error) {
+			events = append(events, e)
+			return e, nil
+		}}
+
+		res, err := newImpersonationService(gormDB, target, identity, eventRepo).Impersonate(context.Background(), newImpersonationInput(admin, target))
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer", res.TokenType)
+		assert.Equal(t, int64(jwt.ImpersonationTokenTTL.Seconds()), res.ExpiresIn)
+		assert.Equal(t, target.UserUUID, res.UserUUID)
+
+		claims, err := jwt.ValidateToken(res.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, identity.Sub, claims["sub"])
+		assert.Equal(t, map[string]any{"sub": "admin-sub", "username": "admin"}, claims["act"])
+		assert.Equal(t, "admin is signed in as jane", claims["impersonation_banner"])
+		exp, err := claims.GetExpirationTime()
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(jwt.ImpersonationTokenTTL), exp.Time, 5*time.Second)
+
+		require.Len(t, events, 1)
+		event := events[0]
+		assert.Equal(t, model.AuthEventTypeImpersonate, event.EventType)
+		assert.Equal(t, int64(1), event.TenantID)
+		assert.Equal(t, &admin.UserID, event.ActorUserID)
+		assert.Equal(t, &target.UserID, event.TargetUserID)
+		var meta map[string]any
+		require.NoError(t, json.Unmarshal(event.Metadata, &meta))
+		assert.Equal(t, "support ticket 42", meta["reason"])
+	})
+
+	t.Run("audit failure → no token", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		target := &model.User{UserID: 2, UserUUID: uuid.New(), Username: "jane", Status: model.StatusActive}
+		identity := &model.UserIdentity{UserID: 2, ClientID: 1, TenantID: 1, Sub: uuid.NewString()}
+		eventRepo := &mockAuthEventRepo{createFn: func(*model.AuthEvent) (*model.AuthEvent, error) { return nil, assert.AnError }}
+
+		res, err := newImpersonationService(gormDB, target, identity, eventRepo).Impersonate(context.Background(), newImpersonationInput(admin, target))
+		assert.Nil(t, res)
+		var internal *apperror.InternalError
+		require.ErrorAs(t, err, &internal)
+	})
+
+	cases := []struct {
+		name    string
+		setup   func(in *ImpersonationInput, target *model.User, identity *model.UserIdentity)
+		wantErr any
+	}{
+		{
+			name:    "nested impersonation → forbidden",
+			setup:   func(in *ImpersonationInput, _ *model.User, _ *model.UserIdentity) { in.ActorActingFor = "other-admin" },
+			wantErr: new(*apperror.ForbiddenError),
+		},
+		{
+			name:    "self impersonation → validation error",
+			setup:   func(in *ImpersonationInput, _ *model.User, _ *model.UserIdentity) { in.TargetUUID = admin.UserUUID },
+			wantErr: new(*apperror.ValidationError),
+		},
+		{
+			name:    "unknown user → not found",
+			setup:   func(in *ImpersonationInput, _ *model.User, _ *model.UserIdentity) { in.TargetUUID = uuid.New() },
+			wantErr: new(*apperror.NotFoundError),
+		},
+		{
+			name:    "user of another tenant → not found",
+			setup:   func(_ *ImpersonationInput, _ *model.User, identity *model.UserIdentity) { identity.TenantID = 2 },
+			wantErr: new(*apperror.NotFoundError),
+		},
+		{
+			name: "inactive user → validation error",
+			setup: func(_ *ImpersonationInput, target *model.User, _ *model.UserIdentity) {
+				target.Status = model.StatusSuspended
+			},
+			wantErr: new(*apperror.ValidationError),
+		},
+		{
+			name:    "session on unknown client → validation error",
+			setup:   func(in *ImpersonationInput, _ *model.User, _ *model.UserIdentity) { in.ClientID = "other-client" },
+			wantErr: new(*apperror.ValidationError),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gormDB, mock := newMockGormDB(t)
+			mock.ExpectBegin()
+			mock.ExpectRollback()
+			target := &model.User{UserID: 2, UserUUID: uuid.New(), Username: "jane", Status: model.StatusActive}
+			identity := &model.UserIdentity{UserID: 2, ClientID: 1, TenantID: 1, Sub: uuid.NewString()}
+			var events []*model.AuthEvent
+			eventRepo := &mockAuthEventRepo{createFn: func(e *model.AuthEvent) (*model.AuthEvent, error) {
+				events = append(events, e)
+				return e, nil
+			}}
+			in := newImpersonationInput(admin, target)
+			tc.setup(&in, target, identity)
+
+			_, err := newImpersonationService(gormDB, target, identity, eventRepo).Impersonate(context.Background(), in)
+			require.ErrorAs(t, err, tc.wantErr)
+			assert.Empty(t, events)
+		})
+	}
+}
@@ -23,6 +23,8 @@ type IPRestrictionRuleServiceDataResult struct {
 	Type                  string
 	IPAddress             string
 	Status                string
+	Source                string
+	ExpiresAt             *time.Time
 	CreatedAt             time.Time
 	UpdatedAt             time.Time
 }
@@ -295,6 +297,8 @@ func toIPRestrictionRuleServiceDataResult(rule *model.IPRestrictionRule) IPRestr
 		Type:                  rule.Type,
 		IPAddress:             rule.IPAddress,
 		Status:                rule.Status,
+		Source:                rule.Source,
+		ExpiresAt:             rule.ExpiresAt,
 		CreatedAt:             rule.CreatedAt,
 		UpdatedAt:             rule.UpdatedAt,
 	}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/maintainerd/auth/internal/apperror"
+)
+
+// listCursorPrefix versions the opaque cursor format of paginated lists.
+const listCursorPrefix = "l1:"
+
+// CursorPageResult is a keyset page of a list. NextCursor resumes after the
+// last row and is empty when HasMore is false.
+type CursorPageResult[T any] struct {
+	Data       []T
+	Limit      int
+	NextCursor string
+	HasMore    bool
+}
+
+// encodeListCursor wraps the ID of the last row of a page in an opaque
+// cursor so clients do not come to depend on its format.
+func encodeListCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(listCursorPrefix + strconv.FormatInt(id, 10)))
+}
+
+// decodeListCursor parses a cursor produced by encodeListCursor. An empty
+// cursor requests the first page.
+func decodeListCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), listCursorPrefix) {
+		return 0, apperror.NewValidation("invalid cursor")
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(string(raw), listCursorPrefix), 10, 64)
+	if err != nil || id < 1 {
+		return 0, apperror.NewValidation("invalid cursor")
+	}
+	return id, nil
+}
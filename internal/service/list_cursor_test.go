@@ -0,0 +1,41 @@
+package service
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCursor_RoundTrip(t *testing.T) {
+	for _, id := range []int64{1, 42, 1 << 40} {
+		got, err := decodeListCursor(encodeListCursor(id))
+		require.NoError(t, err)
+		assert.Equal(t, id, got)
+	}
+}
+
+func TestListCursor_EmptyIsFirstPage(t *testing.T) {
+	got, err := decodeListCursor("")
+	require.NoError(t, err)
+	assert.Zero(t, got)
+}
+
+func TestListCursor_Invalid(t *testing.T) {
+	enc := base64.RawURLEncoding.EncodeToString
+	for name, cursor := range map[string]string{
+		"not base64":     "!!!",
+		"missing prefix": enc([]byte("42")),
+		"event cursor":   encodeEventCursor(42),
+		"not a number":   enc([]byte(listCursorPrefix + "abc")),
+		"zero":           enc([]byte(listCursorPrefix + "0")),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeListCursor(cursor)
+			var target *apperror.ValidationError
+			require.ErrorAs(t, err, &target)
+		})
+	}
+}
@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/claims"
 	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/hook"
 	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/metrics"
 	"github.com/maintainerd/auth/internal/middleware"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/ptr"
@@ -20,8 +24,8 @@ import (
 )
 
 type LoginService interface {
-	LoginPublic(ctx context.Context, usernameOrEmail, password, clientID, providerID string) (*dto.LoginResponseDTO, error)
-	Login(ctx context.Context, usernameOrEmail, password string, clientID, providerID *string) (*dto.LoginResponseDTO, error)
+	LoginPublic(ctx context.Context, usernameOrEmail, password, clientID, providerID string, acceptedPolicies []uuid.UUID) (*dto.LoginResponseDTO, error)
+	Login(ctx context.Context, usernameOrEmail, password string, clientID, providerID *string, acceptedPolicies []uuid.UUID) (*dto.LoginResponseDTO, error)
 	GetUserByEmail(ctx context.Context, email string, tenantID int64) (*model.User, error)
 }
 
@@ -32,7 +36,14 @@ type loginService struct {
 	userTokenRepo        repository.UserTokenRepository
 	userIdentityRepo     repository.UserIdentityRepository
 	identityProviderRepo repository.IdentityProviderRepository
+	tenantSettingRepo    repository.TenantSettingRepository
+	securitySettingRepo  repository.SecuritySettingRepository
 	authEventService     AuthEventService
+	loginHookService     LoginHookService
+	claimsFetcher        claims.Fetcher
+	loginAnomalyService  LoginAnomalyService
+	bruteForceService    BruteForceService
+	policyDocumentRepo   repository.PolicyDocumentRepository
 }
 
 func NewLoginService(
@@ -42,7 +53,14 @@ func NewLoginService(
 	userTokenRepo repository.UserTokenRepository,
 	userIdentityRepo repository.UserIdentityRepository,
 	identityProviderRepo repository.IdentityProviderRepository,
+	tenantSettingRepo repository.TenantSettingRepository,
+	securitySettingRepo repository.SecuritySettingRepository,
 	authEventService AuthEventService,
+	loginHookService LoginHookService,
+	claimsFetcher claims.Fetcher,
+	loginAnomalyService LoginAnomalyService,
+	bruteForceService BruteForceService,
+	policyDocumentRepo repository.PolicyDocumentRepository,
 ) LoginService {
 	return &loginService{
 		db:                   db,
@@ -51,18 +69,29 @@ func NewLoginService(
 		userTokenRepo:        userTokenRepo,
 		userIdentityRepo:     userIdentityRepo,
 		identityProviderRepo: identityProviderRepo,
+		tenantSettingRepo:    tenantSettingRepo,
+		securitySettingRepo:  securitySettingRepo,
 		authEventService:     authEventService,
+		loginHookService:     loginHookService,
+		claimsFetcher:        claimsFetcher,
+		loginAnomalyService:  loginAnomalyService,
+		bruteForceService:    bruteForceService,
+		policyDocumentRepo:   policyDocumentRepo,
 	}
 }
 
+// loginTokenScope is the scope of access tokens issued by direct login and
+// registration.
+const loginTokenScope = "openid profile email"
+
 // Function variables to allow stubbing in tests.
-var generateIDTokenFn = jwt.GenerateIDToken
+var generateIDTokenFn = jwt.GenerateIDTokenWithClaims
 var generateRefreshTokenFn = jwt.GenerateRefreshToken
 
 // LoginPublic authenticates users for public-facing applications.
 // Requires clientID and providerID to identify the auth client.
 // Used by external applications on port 8081.
-func (s *loginService) LoginPublic(ctx context.Context, usernameOrEmail, password, clientID, providerID string) (result *dto.LoginResponseDTO, err error) {
+func (s *loginService) LoginPublic(ctx context.Context, usernameOrEmail, password, clientID, providerID string, acceptedPolicies []uuid.UUID) (result *dto.LoginResponseDTO, err error) {
 	_, span := otel.Tracer("service").Start(ctx, "login.public")
 	defer func() {
 		if err != nil {
@@ -71,6 +100,7 @@ func (s *loginService) LoginPublic(ctx context.Context, usernameOrEmail, passwor
 		} else {
 			span.SetStatus(codes.Ok, "")
 		}
+		metrics.ObserveLogin(err == nil)
 		span.End()
 	}()
 	startTime := time.Now()
@@ -79,6 +109,7 @@ func (s *loginService) LoginPublic(ctx context.Context, usernameOrEmail, passwor
 
 	// Rate limiting check (SOC2 CC6.1 - Logical Access Controls)
 	if err := security.CheckRateLimit(usernameOrEmail); err != nil {
+		metrics.ObserveRateLimitHit("login")
 		security.LogSecurityEvent(security.SecurityEvent{
 			EventType: "login_rate_limited",
 			UserID:    usernameOrEmail,
@@ -169,13 +200,18 @@ func (s *loginService) LoginPublic(ctx context.Context, usernameOrEmail, passwor
 		return nil, err
 	}
 
+	// Refuse addresses the tenant blocks, by hand or automatically
+	if err := checkIPBlocked(ctx, s.bruteForceService, client.IdentityProvider.TenantID); err != nil {
+		return nil, err
+	}
+
 	// Timing-safe credential verification to prevent user enumeration
 	var passwordValid bool = false
 	var hashedPassword []byte
 
 	if userLookupErr == nil && user != nil && user.Password != nil {
 		hashedPassword = []byte(*user.Password)
-		passwordValid = bcrypt.CompareHashAndPassword(hashedPassword, []byte(password)) == nil
+		passwordValid = security.ComparePassword(hashedPassword, []byte(password)) == nil
 	} else {
 		// Perform dummy bcrypt operation to maintain consistent timing
 		bcrypt.CompareHashAndPassword(security.GetDummyBcryptHash(), []byte(password)) //nolint:errcheck // intentional timing dummy; error is irrelevant
@@ -185,6 +221,7 @@ func (s *loginService) LoginPublic(ctx context.Context, usernameOrEmail, passwor
 	if !passwordValid || user == nil || user.Password == nil {
 		// Record failed attempt
 		security.RecordFailedAttempt(usernameOrEmail)
+		recordBruteForceFailure(ctx, s.bruteForceService, client.IdentityProvider.TenantID, usernameOrEmail)
 
 		security.LogSecurityEvent(security.SecurityEvent{
 			EventType: "login_failure",
@@ -208,7 +245,7 @@ func (s *loginService) LoginPublic(ctx context.Context, usernameOrEmail, passwor
 			})
 		}
 
-		return nil, apperror.NewUnauthorized("invalid credentials")
+		return nil, apperror.WithCode(apperror.CodeInvalidCredentials, apperror.NewUnauthorized(msgInvalidCredentials))
 	}
 
 	// Check if user account is active
@@ -233,12 +270,39 @@ func (s *loginService) LoginPublic(ctx context.Context, usernameOrEmail, passwor
 			Description: ptr.Ptr("Attempt to login with inactive account"),
 		})
 
-		return nil, apperror.NewUnauthorized("account is not active")
+		// Enumeration-safe tenants answer as for a wrong password and count
+		// the attempt, so lockout behaves the same for every account state
+		if enumerationSafe(s.tenantSettingRepo, client.IdentityProvider.TenantID) {
+			security.RecordFailedAttempt(usernameOrEmail)
+			return nil, apperror.WithCode(apperror.CodeInvalidCredentials, apperror.NewUnauthorized(msgInvalidCredentials))
+		}
+
+		return nil, inactiveAccountError(user)
+	}
+
+	// Current policy documents must have been accepted, now or before
+	if err := acceptPoliciesOnLogin(ctx, s.policyDocumentRepo, client.IdentityProvider.TenantID, user, acceptedPolicies); err != nil {
+		return nil, err
+	}
+
+	// Tenant pre-login hooks may deny, post-login hooks may add claims
+	hookClaims, err := runLoginHooks(ctx, s.loginHookService, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// Claims from the client's external source; hook claims take precedence
+	extraClaims, err := enrichTokenClaims(ctx, s.claimsFetcher, client, userIdentitySub, user, loginTokenScope)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to fetch token claims", err)
 	}
 
 	// Reset failed attempts on successful authentication
 	security.ResetFailedAttempts(usernameOrEmail)
 
+	// Upgrade the stored hash to the tenant's current algorithm and cost
+	rehashPasswordIfNeeded(ctx, s.userRepo, s.securitySettingRepo, user, client.IdentityProvider.TenantID, password)
+
 	// Log successful login
 	security.LogSecurityEvent(security.SecurityEvent{
 		EventType: "login_success",
@@ -260,15 +324,26 @@ func (s *loginService) LoginPublic(ctx context.Context, usernameOrEmail, passwor
 		Description: ptr.Ptr(fmt.Sprintf("Successful login for user %s", user.Username)),
 	})
 
+	// Flag new devices and impossible travel
+	anomaly := checkLoginAnomalies(ctx, s.loginAnomalyService, user, client.IdentityProvider.TenantID)
+	if anomaly.SecurityHold {
+		return nil, errSecurityHold
+	}
+
 	// Generate token response
-	return s.generateTokenResponse(userIdentitySub, user, client)
+	result, err = generateLoginTokenResponse(ctx, userIdentitySub, user, client, mergeClaims(extraClaims, hookClaims))
+	if err != nil {
+		return nil, err
+	}
+	result.StepUpRequired = anomaly.StepUpRequired
+	return result, nil
 }
 
 // Login authenticates users for internal applications.
 // If clientID and providerID are provided, uses the specified auth client.
 // If not provided, uses the default auth client.
 // Used by internal applications on port 8080.
-func (s *loginService) Login(ctx context.Context, usernameOrEmail, password string, clientID, providerID *string) (result *dto.LoginResponseDTO, err error) {
+func (s *loginService) Login(ctx context.Context, usernameOrEmail, password string, clientID, providerID *string, acceptedPolicies []uuid.UUID) (result *dto.LoginResponseDTO, err error) {
 	_, span := otel.Tracer("service").Start(ctx, "login.internal")
 	defer func() {
 		if err != nil {
@@ -277,12 +352,14 @@ func (s *loginService) Login(ctx context.Context, usernameOrEmail, password stri
 		} else {
 			span.SetStatus(codes.Ok, "")
 		}
+		metrics.ObserveLogin(err == nil)
 		span.End()
 	}()
 	startTime := time.Now()
 
 	// Rate limiting check (SOC2 CC6.1 - Logical Access Controls)
 	if err := security.CheckRateLimit(usernameOrEmail); err != nil {
+		metrics.ObserveRateLimitHit("login")
 		security.LogSecurityEvent(security.SecurityEvent{
 			EventType: "login_rate_limited",
 			UserID:    usernameOrEmail,
@@ -365,10 +442,15 @@ func (s *loginService) Login(ctx context.Context, usernameOrEmail, password stri
 		return nil, err
 	}
 
+	// Refuse addresses the tenant blocks, by hand or automatically
+	if err := checkIPBlocked(ctx, s.bruteForceService, client.IdentityProvider.TenantID); err != nil {
+		return nil, err
+	}
+
 	// Timing-safe password comparison (always compare even if user not found)
 	var passwordValid bool
 	if user != nil && user.Password != nil {
-		err := bcrypt.CompareHashAndPassword([]byte(*user.Password), []byte(password))
+		err := security.ComparePassword([]byte(*user.Password), []byte(password))
 		passwordValid = (err == nil)
 	} else {
 		// Use a properly pre-computed dummy hash so the timing profile is
@@ -382,6 +464,7 @@ func (s *loginService) Login(ctx context.Context, usernameOrEmail, password stri
 	if !passwordValid || user == nil || user.Password == nil {
 		// Record failed attempt
 		security.RecordFailedAttempt(usernameOrEmail)
+		recordBruteForceFailure(ctx, s.bruteForceService, client.IdentityProvider.TenantID, usernameOrEmail)
 
 		security.LogSecurityEvent(security.SecurityEvent{
 			EventType: "login_failure",
@@ -405,7 +488,7 @@ func (s *loginService) Login(ctx context.Context, usernameOrEmail, password stri
 			})
 		}
 
-		return nil, apperror.NewUnauthorized("invalid credentials")
+		return nil, apperror.WithCode(apperror.CodeInvalidCredentials, apperror.NewUnauthorized(msgInvalidCredentials))
 	}
 
 	// Check if user account is active
@@ -430,12 +513,39 @@ func (s *loginService) Login(ctx context.Context, usernameOrEmail, password stri
 			Description: ptr.Ptr("Attempt to login with inactive account"),
 		})
 
-		return nil, apperror.NewUnauthorized("account is not active")
+		// Enumeration-safe tenants answer as for a wrong password and count
+		// the attempt, so lockout behaves the same for every account state
+		if enumerationSafe(s.tenantSettingRepo, client.IdentityProvider.TenantID) {
+			security.RecordFailedAttempt(usernameOrEmail)
+			return nil, apperror.WithCode(apperror.CodeInvalidCredentials, apperror.NewUnauthorized(msgInvalidCredentials))
+		}
+
+		return nil, inactiveAccountError(user)
+	}
+
+	// Current policy documents must have been accepted, now or before
+	if err := acceptPoliciesOnLogin(ctx, s.policyDocumentRepo, client.IdentityProvider.TenantID, user, acceptedPolicies); err != nil {
+		return nil, err
+	}
+
+	// Tenant pre-login hooks may deny, post-login hooks may add claims
+	hookClaims, err := runLoginHooks(ctx, s.loginHookService, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// Claims from the client's external source; hook claims take precedence
+	extraClaims, err := enrichTokenClaims(ctx, s.claimsFetcher, client, userIdentitySub, user, loginTokenScope)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to fetch token claims", err)
 	}
 
 	// Reset failed attempts on successful authentication
 	security.ResetFailedAttempts(usernameOrEmail)
 
+	// Upgrade the stored hash to the tenant's current algorithm and cost
+	rehashPasswordIfNeeded(ctx, s.userRepo, s.securitySettingRepo, user, client.IdentityProvider.TenantID, password)
+
 	// Log successful login
 	security.LogSecurityEvent(security.SecurityEvent{
 		EventType: "login_success",
@@ -457,8 +567,19 @@ func (s *loginService) Login(ctx context.Context, usernameOrEmail, password stri
 		Description: ptr.Ptr(fmt.Sprintf("Successful internal login for user %s", user.Username)),
 	})
 
+	// Flag new devices and impossible travel
+	anomaly := checkLoginAnomalies(ctx, s.loginAnomalyService, user, client.IdentityProvider.TenantID)
+	if anomaly.SecurityHold {
+		return nil, errSecurityHold
+	}
+
 	// Generate token response
-	return s.generateTokenResponse(userIdentitySub, user, client)
+	result, err = generateLoginTokenResponse(ctx, userIdentitySub, user, client, mergeClaims(extraClaims, hookClaims))
+	if err != nil {
+		return nil, err
+	}
+	result.StepUpRequired = anomaly.StepUpRequired
+	return result, nil
 }
 
 // GetUserByEmail looks up a user by email, scoped to the given tenant when
@@ -482,14 +603,125 @@ func (s *loginService) GetUserByEmail(ctx context.Context, email string, tenantI
 	return user, nil
 }
 
-func (s *loginService) generateTokenResponse(sub string, user *model.User, Client *model.Client) (*dto.LoginResponseDTO, error) {
-	accessToken, err := jwt.GenerateAccessToken(
+// errSecurityHold refuses the login of an account on security hold.
+var errSecurityHold = apperror.WithCode(apperror.CodeAccountSecurityHold, apperror.NewUnauthorized("account is on security hold"))
+
+// inactiveAccountError returns the error for a login to an account that is
+// not active. A held account is told so, as the user can recover it.
+func inactiveAccountError(user *model.User) error {
+	if user.Status == model.StatusSecurityHold {
+		return errSecurityHold
+	}
+	return apperror.WithCode(apperror.CodeAccountInactive, apperror.NewUnauthorized("account is not active"))
+}
+
+// checkIPBlocked refuses a login from an address an IP restriction rule of
+// the tenant denies. It is a no-op when no brute-force service is configured.
+func checkIPBlocked(ctx context.Context, bruteForceService BruteForceService, tenantID int64) error {
+	if bruteForceService == nil {
+		return nil
+	}
+	if err := bruteForceService.CheckIP(ctx, tenantID); err != nil {
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "login_ip_blocked",
+			ClientIP:  middleware.ClientIPFromContext(ctx),
+			Timestamp: time.Now(),
+			Details:   err.Error(),
+			Severity:  "MEDIUM",
+		})
+		return err
+	}
+	return nil
+}
+
+// recordBruteForceFailure counts a failed login towards brute-force and
+// credential stuffing detection. It is a no-op when no brute-force service is
+// configured.
+func recordBruteForceFailure(ctx context.Context, bruteForceService BruteForceService, tenantID int64, usernameOrEmail string) {
+	if bruteForceService == nil {
+		return
+	}
+	bruteForceService.RecordFailure(ctx, tenantID, usernameOrEmail)
+}
+
+// checkLoginAnomalies runs login anomaly detection for an authenticated user.
+// Failures are logged and do not fail the login. It is a no-op when no anomaly
+// service is configured.
+func checkLoginAnomalies(ctx context.Context, loginAnomalyService LoginAnomalyService, user *model.User, tenantID int64) *LoginAnomalyResult {
+	if loginAnomalyService == nil {
+		return &LoginAnomalyResult{}
+	}
+	anomaly, err := loginAnomalyService.Evaluate(ctx, user, tenantID)
+	if err != nil {
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "login_anomaly_check_failure",
+			UserID:    user.UserUUID.String(),
+			Timestamp: time.Now(),
+			Details:   err.Error(),
+			Severity:  "MEDIUM",
+		})
+		return &LoginAnomalyResult{}
+	}
+	return anomaly
+}
+
+// runLoginHooks runs the tenant's pre-login hooks and then its post-login
+// hooks for an authenticated user, returning the claims the post-login hooks
+// contributed. It is a no-op when no hook service is configured.
+func runLoginHooks(ctx context.Context, loginHookService LoginHookService, user *model.User, client *model.Client) (map[string]any, error) {
+	if loginHookService == nil {
+		return nil, nil
+	}
+
+	tenantID := client.IdentityProvider.TenantID
+	payload := hook.Payload{
+		Trigger: model.LoginHookTriggerPreLogin,
+		User: hook.User{
+			UUID:          user.UserUUID.String(),
+			Username:      user.Username,
+			Email:         user.Email,
+			EmailVerified: user.IsEmailVerified,
+			Phone:         user.Phone,
+			PhoneVerified: user.IsPhoneVerified,
+			Status:        user.Status,
+		},
+		Client: hook.Client{
+			ClientID: ptr.Deref(client.Identifier),
+			Name:     client.Name,
+		},
+		Request: hook.Request{
+			IPAddress: middleware.ClientIPFromContext(ctx),
+			UserAgent: middleware.UserAgentFromContext(ctx),
+		},
+	}
+
+	if _, err := loginHookService.Run(ctx, tenantID, &user.UserID, payload); err != nil {
+		return nil, err
+	}
+
+	payload.Trigger = model.LoginHookTriggerPostLogin
+	return loginHookService.Run(ctx, tenantID, &user.UserID, payload)
+}
+
+// generateLoginTokenResponse issues the access, ID and refresh tokens of a
+// new session of user at Client.
+func generateLoginTokenResponse(ctx context.Context, sub string, user *model.User, Client *model.Client, claims map[string]any) (*dto.LoginResponseDTO, error) {
+	if err := meterTokenIssued(ctx, Client.TenantID); err != nil {
+		return nil, err
+	}
+
+	// Every login starts a new session. Its ID (sid) follows the user into
+	// the OAuth flows, so that OIDC logout can end the session everywhere.
+	sessionClaims := map[string]any{"sid": jwt.GenerateSecureID()}
+
+	accessToken, err := jwt.GenerateAccessTokenWithClaims(
 		sub,
-		"openid profile email",
+		loginTokenScope,
 		*Client.Domain,
 		*Client.Identifier,
 		*Client.Identifier,
 		Client.IdentityProvider.Identifier,
+		mergeClaims(claims, sessionClaims),
 	)
 	if err != nil {
 		return nil, err
@@ -504,7 +736,7 @@ func (s *loginService) generateTokenResponse(sub string, user *model.User, Clien
 	}
 
 	// Generate ID token with user profile (no nonce for login flow)
-	idToken, err := generateIDTokenFn(sub, *Client.Domain, *Client.Identifier, Client.IdentityProvider.Identifier, profile, "")
+	idToken, err := generateIDTokenFn(sub, *Client.Domain, *Client.Identifier, Client.IdentityProvider.Identifier, profile, "", sessionClaims)
 	if err != nil {
 		return nil, err
 	}
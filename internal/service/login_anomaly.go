@@ -0,0 +1,397 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// LoginAnomalyResult describes what was unusual about a login.
+type LoginAnomalyResult struct {
+	NewDevice        bool
+	ImpossibleTravel bool
+	// StepUpRequired is set when the login was unusual and the tenant
+	// requires step-up authentication for such logins.
+	StepUpRequired bool
+	// TrustedDevice is set when step-up was skipped because the login came
+	// from a device the user trusted.
+	TrustedDevice bool
+	// SecurityHold is set when the tenant puts accounts on security hold on
+	// impossible travel and this login put the user on hold. The login must
+	// then be refused.
+	SecurityHold bool
+}
+
+// Suspicious reports whether anything about the login was unusual.
+func (r *LoginAnomalyResult) Suspicious() bool {
+	return r.NewDevice || r.ImpossibleTravel
+}
+
+// LoginAnomalyService remembers the devices and places users sign in from
+// and flags logins that do not fit.
+type LoginAnomalyService interface {
+	// Evaluate records a successful login of the user to the tenant, from
+	// the client IP and user agent in ctx, and reports what was unusual
+	// about it. It does nothing unless the tenant has enabled new device
+	// notifications or impossible travel detection in its threat settings.
+	// Step-up is not required from a trusted device named in ctx.
+	Evaluate(ctx context.Context, user *model.User, tenantID int64) (*LoginAnomalyResult, error)
+}
+
+type loginAnomalyService struct {
+	loginFingerprintRepo repository.LoginFingerprintRepository
+	securitySettingRepo  repository.SecuritySettingRepository
+	trustedDeviceService TrustedDeviceService
+	notificationService  NotificationService
+	authEventService     AuthEventService
+	securityHoldService  SecurityHoldService
+	geoLocator           security.GeoLocator
+}
+
+// NewLoginAnomalyService creates a LoginAnomalyService. Without a geoLocator
+// logins are not located and impossible travel is never detected. Without a
+// trustedDeviceService no device is trusted. Without a securityHoldService
+// no account is put on security hold.
+func NewLoginAnomalyService(
+	loginFingerprintRepo repository.LoginFingerprintRepository,
+	securitySettingRepo repository.SecuritySettingRepository,
+	trustedDeviceService TrustedDeviceService,
+	notificationService NotificationService,
+	authEventService AuthEventService,
+	securityHoldService SecurityHoldService,
+	geoLocator security.GeoLocator,
+) LoginAnomalyService {
+	return &loginAnomalyService{
+		loginFingerprintRepo: loginFingerprintRepo,
+		securitySettingRepo:  securitySettingRepo,
+		trustedDeviceService: trustedDeviceService,
+		notificationService:  notificationService,
+		authEventService:     authEventService,
+		securityHoldService:  securityHoldService,
+		geoLocator:           geoLocator,
+	}
+}
+
+// loginAnomalySettings are the threat settings of a tenant that drive login
+// anomaly detection.
+type loginAnomalySettings struct {
+	newDevice         bool
+	impossibleTravel  bool
+	maxTravelSpeedKmh float64
+	stepUp            bool
+	securityHold      bool
+}
+
+func (s *loginAnomalyService) Evaluate(ctx context.Context, user *model.User, tenantID int64) (*LoginAnomalyResult, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "loginAnomaly.evaluate")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", user.UserUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	settings, err := s.settings(tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "load threat settings failed")
+		return nil, err
+	}
+	result := &LoginAnomalyResult{}
+	if !settings.newDevice && !settings.impossibleTravel {
+		span.SetStatus(codes.Ok, "")
+		return result, nil
+	}
+
+	ipAddress := middleware.ClientIPFromContext(ctx)
+	userAgent := middleware.UserAgentFromContext(ctx)
+	deviceHash := security.DeviceHash(userAgent)
+	location := s.locate(ctx, ipAddress)
+	now := time.Now()
+
+	latest, err := s.loginFingerprintRepo.FindLatest(user.UserID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find latest login failed")
+		return nil, apperror.NewInternal("failed to load login history", err)
+	}
+	device, err := s.loginFingerprintRepo.FindByDevice(user.UserID, tenantID, deviceHash)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find device failed")
+		return nil, apperror.NewInternal("failed to load login history", err)
+	}
+
+	// The first recorded login has nothing to compare with, so it sets the
+	// baseline instead of being flagged
+	if latest != nil {
+		result.NewDevice = settings.newDevice && device == nil
+		if settings.impossibleTravel && location != nil && location.HasCoordinates() && latest.HasLocation() {
+			from := security.GeoLocation{Latitude: *latest.Latitude, Longitude: *latest.Longitude}
+			result.ImpossibleTravel = security.IsImpossibleTravel(from, *location, now.Sub(latest.LastSeenAt), settings.maxTravelSpeedKmh)
+		}
+	}
+	result.StepUpRequired = settings.stepUp && result.Suspicious()
+	if result.StepUpRequired && s.isTrustedDevice(ctx, user, tenantID) {
+		result.StepUpRequired, result.TrustedDevice = false, true
+	}
+
+	if err := s.recordLogin(user, tenantID, device, deviceHash, userAgent, ipAddress, location, now); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "record login failed")
+		return nil, apperror.NewInternal("failed to record login", err)
+	}
+
+	if result.ImpossibleTravel && settings.securityHold {
+		result.SecurityHold = s.hold(ctx, user, tenantID)
+	}
+	if result.Suspicious() {
+		s.reportSuspiciousLogin(ctx, user, tenantID, result, latest, location)
+	}
+	// A held user is told about the hold instead
+	if result.NewDevice && !result.SecurityHold && user.Email != "" {
+		s.notifyNewDevice(ctx, user, tenantID, userAgent, ipAddress, location, now)
+	}
+
+	span.SetAttributes(
+		attribute.Bool("login.new_device", result.NewDevice),
+		attribute.Bool("login.impossible_travel", result.ImpossibleTravel),
+		attribute.Bool("login.security_hold", result.SecurityHold),
+	)
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// isTrustedDevice reports whether the login came from one of the user's
+// trusted devices.
+func (s *loginAnomalyService) isTrustedDevice(ctx context.Context, user *model.User, tenantID int64) bool {
+	token := middleware.TrustedDeviceTokenFromContext(ctx)
+	if s.trustedDeviceService == nil || token == "" {
+		return false
+	}
+	return s.trustedDeviceService.IsTrusted(ctx, user.UserID, tenantID, token)
+}
+
+// hold puts the user on security hold and reports whether it did. Failures
+// are recorded but do not fail the login.
+func (s *loginAnomalyService) hold(ctx context.Context, user *model.User, tenantID int64) bool {
+	if s.securityHoldService == nil {
+		return false
+	}
+	if err := s.securityHoldService.HoldSuspicious(ctx, user, tenantID, "Impossible travel detected at login"); err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		return false
+	}
+	return true
+}
+
+// settings reads the tenant's login anomaly settings. Without a stored
+// setting every check is off.
+func (s *loginAnomalyService) settings(tenantID int64) (loginAnomalySettings, error) {
+	settings := loginAnomalySettings{maxTravelSpeedKmh: security.DefaultMaxTravelSpeedKmh}
+	setting, err := s.securitySettingRepo.FindByUserPoolID(tenantID)
+	if err != nil {
+		return settings, apperror.NewInternal("failed to load security settings", err)
+	}
+	if setting == nil {
+		return settings, nil
+	}
+
+	threatConfig := unmarshalJSON(setting.ThreatConfig)
+	settings.newDevice = threatConfig[model.ThreatConfigNewDeviceNotification] == true
+	settings.impossibleTravel = threatConfig[model.ThreatConfigImpossibleTravel] == true
+	settings.stepUp = threatConfig[model.ThreatConfigRiskBasedStepUp] == true
+	settings.securityHold = threatConfig[model.ThreatConfigSecurityHoldOnImpossibleTravel] == true
+	if speed, ok := threatConfig[model.ThreatConfigMaxTravelSpeedKmh].(float64); ok && speed > 0 {
+		settings.maxTravelSpeedKmh = speed
+	}
+	return settings, nil
+}
+
+// locate returns where the IP address is, or nil when it cannot be told.
+// Lookup failures are recorded but do not fail the login.
+func (s *loginAnomalyService) locate(ctx context.Context, ipAddress string) *security.GeoLocation {
+	if s.geoLocator == nil || ipAddress == "" {
+		return nil
+	}
+	location, err := s.geoLocator.Locate(ctx, ipAddress)
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		return nil
+	}
+	return location
+}
+
+// recordLogin creates the fingerprint of a device seen for the first time,
+// or moves the device's latest sign in to this one.
+func (s *loginAnomalyService) recordLogin(user *model.User, tenantID int64, device *model.LoginFingerprint, deviceHash, userAgent, ipAddress string, location *security.GeoLocation, now time.Time) error {
+	var country, city, asOrganization *string
+	var latitude, longitude *float64
+	var asn *int64
+	if location != nil {
+		country, city = ptr.PtrOrNil(location.Country), ptr.PtrOrNil(location.City)
+		if location.HasCoordinates() {
+			latitude, longitude = &location.Latitude, &location.Longitude
+		}
+		if location.ASN != 0 {
+			number := int64(location.ASN)
+			asn, asOrganization = &number, ptr.PtrOrNil(location.ASOrganization)
+		}
+	}
+
+	if device == nil {
+		_, err := s.loginFingerprintRepo.Create(&model.LoginFingerprint{
+			UserID:         user.UserID,
+			TenantID:       tenantID,
+			DeviceHash:     deviceHash,
+			UserAgent:      ptr.PtrOrNil(userAgent),
+			IPAddress:      ipAddress,
+			Country:        country,
+			City:           city,
+			Latitude:       latitude,
+			Longitude:      longitude,
+			ASN:            asn,
+			ASOrganization: asOrganization,
+			LoginCount:     1,
+			FirstSeenAt:    now,
+			LastSeenAt:     now,
+		})
+		return err
+	}
+
+	_, err := s.loginFingerprintRepo.UpdateByID(device.LoginFingerprintID, map[string]any{
+		"ip_address":      ipAddress,
+		"country":         country,
+		"city":            city,
+		"latitude":        latitude,
+		"longitude":       longitude,
+		"asn":             asn,
+		"as_organization": asOrganization,
+		"login_count":     gorm.Expr("login_count + 1"),
+		"last_seen_at":    now,
+	})
+	return err
+}
+
+// reportSuspiciousLogin writes the suspicious_login security event and an
+// auth event for each anomaly found.
+func (s *loginAnomalyService) reportSuspiciousLogin(ctx context.Context, user *model.User, tenantID int64, result *LoginAnomalyResult, latest *model.LoginFingerprint, location *security.GeoLocation) {
+	var reasons []string
+	if result.NewDevice {
+		reasons = append(reasons, "new device")
+	}
+	if result.ImpossibleTravel {
+		reasons = append(reasons, fmt.Sprintf("impossible travel from %s to %s", fingerprintPlace(latest), geoPlace(location)))
+	}
+	details := "Suspicious login: " + strings.Join(reasons, ", ")
+	if result.StepUpRequired {
+		details += "; step-up authentication required"
+	}
+	if result.TrustedDevice {
+		details += "; step-up skipped on a trusted device"
+	}
+	if result.SecurityHold {
+		details += "; account put on security hold"
+	}
+
+	security.LogSecurityEvent(security.SecurityEvent{
+		EventType: "suspicious_login",
+		TenantID:  tenantID,
+		UserID:    user.UserUUID.String(),
+		ClientIP:  middleware.ClientIPFromContext(ctx),
+		UserAgent: middleware.UserAgentFromContext(ctx),
+		Timestamp: time.Now(),
+		Details:   details,
+	})
+
+	if result.NewDevice {
+		s.logAnomaly(ctx, user, tenantID, model.AuthEventTypeNewDevice, model.AuthEventSeverityWarn, model.AuthEventResultSuccess, "Login from a new device")
+	}
+	if result.ImpossibleTravel {
+		s.logAnomaly(ctx, user, tenantID, model.AuthEventTypeImpossibleTravel, model.AuthEventSeverityCritical, model.AuthEventResultFailure, reasons[len(reasons)-1])
+	}
+}
+
+func (s *loginAnomalyService) logAnomaly(ctx context.Context, user *model.User, tenantID int64, eventType, severity, result, description string) {
+	s.authEventService.Log(ctx, AuthEventInput{
+		TenantID:    tenantID,
+		ActorUserID: &user.UserID,
+		IPAddress:   middleware.ClientIPFromContext(ctx),
+		UserAgent:   ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:    model.AuthEventCategoryAuthn,
+		EventType:   eventType,
+		Severity:    severity,
+		Result:      result,
+		Description: ptr.Ptr(description),
+	})
+}
+
+// notifyNewDevice tells the user about a login from a new device. It is a
+// mandatory notification, so it is emailed even if the user muted it.
+func (s *loginAnomalyService) notifyNewDevice(ctx context.Context, user *model.User, tenantID int64, userAgent, ipAddress string, location *security.GeoLocation, signedInAt time.Time) {
+	device := userAgent
+	if device == "" {
+		device = "Unknown device"
+	}
+	place := geoPlace(location)
+	data := struct {
+		Device     string
+		IPAddress  string
+		Location   string
+		SignedInAt string
+		ResetURL   string
+		LogoURL    string
+	}{
+		Device:     device,
+		IPAddress:  ipAddress,
+		Location:   place,
+		SignedInAt: signedInAt.UTC().Format("January 2, 2006 15:04 MST"),
+		ResetURL:   config.AccountHostname + "/forgot-password",
+		LogoURL:    config.EmailLogo,
+	}
+	s.notificationService.Notify(ctx, NotifyInput{
+		TenantID:      tenantID,
+		User:          user,
+		Type:          model.NotificationTypeNewDevice,
+		Mandatory:     true,
+		Title:         "New sign-in to your account",
+		Message:       fmt.Sprintf("New sign-in from %s (%s, %s). If this wasn't you, reset your password.", device, ipAddress, place),
+		EmailTemplate: "internal:user:login:new_device",
+		EmailData:     data,
+	})
+}
+
+// geoPlace names a location for people, such as "Berlin, DE".
+func geoPlace(location *security.GeoLocation) string {
+	if location == nil {
+		return "Unknown location"
+	}
+	parts := make([]string, 0, 2)
+	for _, part := range []string{location.City, location.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return "Unknown location"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// fingerprintPlace names where the latest sign in from a device came from.
+func fingerprintPlace(fingerprint *model.LoginFingerprint) string {
+	if fingerprint == nil {
+		return geoPlace(nil)
+	}
+	return geoPlace(&security.GeoLocation{Country: ptr.Deref(fingerprint.Country), City: ptr.Deref(fingerprint.City)})
+}
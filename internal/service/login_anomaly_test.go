@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/email"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// stubGeoLocator locates the IP addresses it holds and no others.
+type stubGeoLocator map[string]*security.GeoLocation
+
+func (l stubGeoLocator) Locate(_ context.Context, ip string) (*security.GeoLocation, error) {
+	return l[ip], nil
+}
+
+// newAnomalyUser returns the user whose logins are evaluated.
+func newAnomalyUser() *model.User {
+	return &model.User{UserID: 7, UserUUID: uuid.New(), Email: "jane@example.com"}
+}
+
+// newLoginAnomalyService returns a LoginAnomalyService of a user pool with
+// threatConfig that locates a Tokyo, a New York and a coordinate-less IP
+// address. The audit entries it writes are recorded in logged.
+func newLoginAnomalyService(threatConfig string, fingerprintRepo *mockLoginFingerprintRepo, trustedDevices TrustedDeviceService, securityHolds SecurityHoldService, logged *[]AuthEventInput) LoginAnomalyService {
+	securitySettingRepo := &mockSecuritySettingRepo{findByUserPoolIDFn: func(int64) (*model.SecuritySetting, error) {
+		return &model.SecuritySetting{ThreatConfig: datatypes.JSON(threatConfig)}, nil
+	}}
+	emailTemplateRepo := &mockEmailTemplateRepo{findByNameFn: func(name string) (*model.EmailTemplate, error) {
+		return &model.EmailTemplate{Name: name, Subject: "New sign in", BodyHTML: "{{.Device}} from {{.Location}}"}, nil
+	}}
+	locator := stubGeoLocator{
+		"203.0.113.7":  {Country: "JP", City: "Tokyo", Latitude: 35.6762, Longitude: 139.6503},
+		"198.51.100.9": {Country: "US", City: "New York", Latitude: 40.7128, Longitude: -74.0060, ASN: 7922, ASOrganization: "COMCAST-7922"},
+		"192.0.2.44":   {Country: "JP", ASN: 2516},
+	}
+	authEvents := &mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { *logged = append(*logged, in) }}
+	notifications := NewNotificationService(&mockNotificationSettingRepo{}, &mockNotificationLogRepo{}, &mockUserRepo{}, emailTemplateRepo)
+	return NewLoginAnomalyService(fingerprintRepo, securitySettingRepo, trustedDevices, notifications, authEvents, securityHolds, locator)
+}
+
+// loginContext returns a context of a request from the IP address with the
+// user agent, as SecurityContextMiddleware stores them.
+func loginContext(ip, userAgent string) context.Context {
+	ctx := context.WithValue(context.Background(), middleware.ClientIPKey, ip)
+	return context.WithValue(ctx, middleware.UserAgentKey, userAgent)
+}
+
+// seenFrom returns a fingerprint of a sign in from New York at the time.
+func seenFrom(userAgent string, at time.Time) *model.LoginFingerprint {
+	return &model.LoginFingerprint{
+		LoginFingerprintID: 3,
+		DeviceHash:         security.DeviceHash(userAgent),
+		Country:            ptr.Ptr("US"),
+		City:               ptr.Ptr("New York"),
+		Latitude:           new(float64),
+		Longitude:          new(float64),
+		LastSeenAt:         at,
+	}
+}
+
+// seenFromNewYork returns a fingerprint of a sign in from the coordinates of
+// New York at the time.
+func seenFromNewYork(userAgent string, at time.Time) *model.LoginFingerprint {
+	known := seenFrom(userAgent, at)
+	*known.Latitude, *known.Longitude = 40.7128, -74.0060
+	return known
+}
+
+func TestLoginAnomalyService_Evaluate(t *testing.T) {
+	const laptop = "Mozilla/5.0 (Macintosh) Firefox/119.0"
+	const phone = "Mozilla/5.0 (iPhone) Safari/604.1"
+
+	origSendEmail := email.SendEmail
+	defer func() { email.SendEmail = origSendEmail }()
+	var sent []email.SendEmailParams
+	email.SendEmail = func(_ context.Context, p email.SendEmailParams) error {
+		sent = append(sent, p)
+		return nil
+	}
+
+	t.Run("detection off → nothing recorded", func(t *testing.T) {
+		fingerprintRepo := &mockLoginFingerprintRepo{findLatestFn: func(int64, int64) (*model.LoginFingerprint, error) {
+			t.Fatal("logins must not be recorded")
+			return nil, nil
+		}}
+		var logged []AuthEventInput
+		svc := newLoginAnomalyService(`{"risk_based_step_up_enabled": true}`, fingerprintRepo, nil, nil, &logged)
+
+		result, err := svc.Evaluate(loginContext("198.51.100.9", laptop), newAnomalyUser(), 1)
+		require.NoError(t, err)
+		assert.False(t, result.Suspicious())
+		assert.False(t, result.StepUpRequired)
+	})
+
+	t.Run("first login sets the baseline", func(t *testing.T) {
+		sent = nil
+		var created *model.LoginFingerprint
+		fingerprintRepo := &mockLoginFingerprintRepo{createFn: func(e *model.LoginFingerprint) (*model.LoginFingerprint, error) {
+			created = e
+			return e, nil
+		}}
+		var logged []AuthEventInput
+		svc := newLoginAnomalyService(`{"new_device_notification_enabled": true, "impossible_travel_detection_enabled": true}`, fingerprintRepo, nil, nil, &logged)
+
+		result, err := svc.Evaluate(loginContext("198.51.100.9", laptop), newAnomalyUser(), 1)
+		require.NoError(t, err)
+		assert.False(t, result.Suspicious())
+		assert.Empty(t, sent)
+
+		require.NotNil(t, created)
+		assert.Equal(t, security.DeviceHash(laptop), created.DeviceHash)
+		assert.Equal(t, int64(1), created.TenantID)
+		assert.Equal(t, "198.51.100.9", created.IPAddress)
+		assert.Equal(t, "New York", ptr.Deref(created.City))
+		require.NotNil(t, created.Latitude)
+		assert.InDelta(t, 40.7128, *created.Latitude, 0.0001)
+		require.NotNil(t, created.ASN)
+		assert.Equal(t, int64(7922), *created.ASN)
+		assert.Equal(t, "COMCAST-7922", ptr.Deref(created.ASOrganization))
+	})
+
+	t.Run("new device → emailed and step-up required", func(t *testing.T) {
+		sent = nil
+		var created bool
+		fingerprintRepo := &mockLoginFingerprintRepo{
+			findLatestFn: func(int64, int64) (*model.LoginFingerprint, error) {
+				return seenFrom(laptop, time.Now().Add(-time.Hour)), nil
+			},
+			createFn: func(e *model.LoginFingerprint) (*model.LoginFingerprint, error) {
+				created = true
+				return e, nil
+			},
+		}
+		var logged []AuthEventInput
+		svc := newLoginAnomalyService(`{"new_device_notification_enabled": true, "risk_based_step_up_enabled": true}`, fingerprintRepo, nil, nil, &logged)
+
+		result, err := svc.Evaluate(loginContext("198.51.100.9", phone), newAnomalyUser(), 1)
+		require.NoError(t, err)
+		assert.True(t, result.NewDevice)
+		assert.False(t, result.ImpossibleTravel)
+		assert.True(t, result.StepUpRequired)
+		assert.True(t, created)
+
+		require.Len(t, sent, 1)
+		assert.Equal(t, "jane@example.com", sent[0].To)
+		assert.Equal(t, phone+" from New York, US", sent[0].BodyHTML)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeNewDevice, logged[0].EventType)
+	})
+
+	t.Run("trusted device → no step-up", func(t *testing.T) {
+		fingerprintRepo := &mockLoginFingerprintRepo{findLatestFn: func(int64, int64) (*model.LoginFingerprint, error) {
+			return seenFrom(laptop, time.Now().Add(-time.Hour)), nil
+		}}
+		var touched bool
+		trustedDevices := NewTrustedDeviceService(&mockTrustedDeviceRepo{
+			findByTokenHashFn: func(hash string) (*model.TrustedDevice, error) {
+				assert.Equal(t, hashTrustedDeviceToken("td_phone"), hash)
+				return &model.TrustedDevice{TrustedDeviceID: 3, UserID: 7, TenantID: 1, ExpiresAt: time.Now().Add(time.Hour)}, nil
+			},
+			touchLastUsedFn: func(int64, time.Time) error { touched = true; return nil },
+		}, &mockSecuritySettingRepo{}, &mockAuthEventService{})
+		var logged []AuthEventInput
+		svc := newLoginAnomalyService(`{"new_device_notification_enabled": true, "risk_based_step_up_enabled": true}`, fingerprintRepo, trustedDevices, nil, &logged)
+
+		ctx := context.WithValue(loginContext("198.51.100.9", phone), middleware.TrustedDeviceKey, "td_phone")
+		result, err := svc.Evaluate(ctx, newAnomalyUser(), 1)
+		require.NoError(t, err)
+		assert.True(t, result.NewDevice)
+		assert.False(t, result.StepUpRequired)
+		assert.True(t, result.TrustedDevice)
+		assert.True(t, touched)
+	})
+
+	t.Run("known device → updated", func(t *testing.T) {
+		sent = nil
+		known := seenFrom(laptop, time.Now().Add(-time.Hour))
+		var updated map[string]any
+		fingerprintRepo := &mockLoginFingerprintRepo{
+			findLatestFn: func(int64, int64) (*model.LoginFingerprint, error) { return known, nil },
+			findByDeviceFn: func(_, _ int64, deviceHash string) (*model.LoginFingerprint, error) {
+				assert.Equal(t, security.DeviceHash(laptop), deviceHash)
+				return known, nil
+			},
+			updateByIDFn: func(id, data any) (*model.LoginFingerprint, error) {
+				assert.Equal(t, known.LoginFingerprintID, id)
+				updated = data.(map[string]any)
+				return known, nil
+			},
+		}
+		var logged []AuthEventInput
+		svc := newLoginAnomalyService(`{"new_device_notification_enabled": true, "risk_based_step_up_enabled": true}`, fingerprintRepo, nil, nil, &logged)
+
+		result, err := svc.Evaluate(loginContext("198.51.100.9", laptop), newAnomalyUser(), 1)
+		require.NoError(t, err)
+		assert.False(t, result.Suspicious())
+		assert.False(t, result.StepUpRequired)
+		assert.Empty(t, sent)
+		assert.Empty(t, logged)
+		assert.Equal(t, "198.51.100.9", updated["ip_address"])
+		assert.Equal(t, gorm.Expr("login_count + 1"), updated["login_count"])
+	})
+
+	t.Run("impossible travel → critical audit entry", func(t *testing.T) {
+		known := seenFromNewYork(laptop, time.Now().Add(-30*time.Minute))
+		fingerprintRepo := &mockLoginFingerprintRepo{
+			findLatestFn:   func(int64, int64) (*model.LoginFingerprint, error) { return known, nil },
+			findByDeviceFn: func(int64, int64, string) (*model.LoginFingerprint, error) { return known, nil },
+		}
+		var logged []AuthEventInput
+		svc := newLoginAnomalyService(`{"impossible_travel_detection_enabled": true}`, fingerprintRepo, nil, nil, &logged)
+
+		result, err := svc.Evaluate(loginContext("203.0.113.7", laptop), newAnomalyUser(), 1)
+		require.NoError(t, err)
+		assert.True(t, result.ImpossibleTravel)
+		assert.False(t, result.NewDevice, "new devices are only flagged when notifications are on")
+		assert.False(t, result.StepUpRequired)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeImpossibleTravel, logged[0].EventType)
+		assert.Equal(t, model.AuthEventSeverityCritical, logged[0].Severity)
+		assert.Equal(t, "impossible travel from New York, US to Tokyo, JP", ptr.Deref(logged[0].Description))
+	})
+
+	t.Run("impossible travel with hold enabled → account on hold", func(t *testing.T) {
+		sent = nil
+		known := seenFromNewYork("old-browser", time.Now().Add(-30*time.Minute))
+		fingerprintRepo := &mockLoginFingerprintRepo{findLatestFn: func(int64, int64) (*model.LoginFingerprint, error) { return known, nil }}
+		holds := &stubSecurityHoldService{}
+		var logged []AuthEventInput
+		svc := newLoginAnomalyService(`{"impossible_travel_detection_enabled": true, "security_hold_on_impossible_travel_enabled": true, "new_device_notification_enabled": true}`, fingerprintRepo, nil, holds, &logged)
+
+		result, err := svc.Evaluate(loginContext("203.0.113.7", laptop), newAnomalyUser(), 1)
+		require.NoError(t, err)
+		assert.True(t, result.ImpossibleTravel)
+		assert.True(t, result.SecurityHold)
+		assert.Equal(t, []int64{7}, holds.held)
+		assert.Empty(t, sent, "a held user is not told about the new device")
+	})
+
+	t.Run("hold failure → login not held", func(t *testing.T) {
+		known := seenFromNewYork(laptop, time.Now().Add(-30*time.Minute))
+		fingerprintRepo := &mockLoginFingerprintRepo{
+			findLatestFn:   func(int64, int64) (*model.LoginFingerprint, error) { return known, nil },
+			findByDeviceFn: func(int64, int64, string) (*model.LoginFingerprint, error) { return known, nil },
+		}
+		var logged []AuthEventInput
+		svc := newLoginAnomalyService(`{"impossible_travel_detection_enabled": true, "security_hold_on_impossible_travel_enabled": true}`, fingerprintRepo, nil, &stubSecurityHoldService{err: errors.New("db down")}, &logged)
+
+		result, err := svc.Evaluate(loginContext("203.0.113.7", laptop), newAnomalyUser(), 1)
+		require.NoError(t, err)
+		assert.True(t, result.ImpossibleTravel)
+		assert.False(t, result.SecurityHold)
+	})
+
+	t.Run("travel within the tenant's speed → allowed", func(t *testing.T) {
+		known := seenFromNewYork(laptop, time.Now().Add(-30*time.Minute))
+		fingerprintRepo := &mockLoginFingerprintRepo{
+			findLatestFn:   func(int64, int64) (*model.LoginFingerprint, error) { return known, nil },
+			findByDeviceFn: func(int64, int64, string) (*model.LoginFingerprint, error) { return known, nil },
+		}
+		var logged []AuthEventInput
+		svc := newLoginAnomalyService(`{"impossible_travel_detection_enabled": true, "max_travel_speed_kmh": 30000}`, fingerprintRepo, nil, nil, &logged)
+
+		result, err := svc.Evaluate(loginContext("203.0.113.7", laptop), newAnomalyUser(), 1)
+		require.NoError(t, err)
+		assert.False(t, result.ImpossibleTravel)
+	})
+
+	t.Run("location without coordinates → not compared", func(t *testing.T) {
+		known := seenFromNewYork(laptop, time.Now().Add(-30*time.Minute))
+		var updated map[string]any
+		fingerprintRepo := &mockLoginFingerprintRepo{
+			findLatestFn:   func(int64, int64) (*model.LoginFingerprint, error) { return known, nil },
+			findByDeviceFn: func(int64, int64, string) (*model.LoginFingerprint, error) { return known, nil },
+			updateByIDFn: func(_, data any) (*model.LoginFingerprint, error) {
+				updated = data.(map[string]any)
+				return known, nil
+			},
+		}
+		var logged []AuthEventInput
+		svc := newLoginAnomalyService(`{"impossible_travel_detection_enabled": true}`, fingerprintRepo, nil, nil, &logged)
+
+		result, err := svc.Evaluate(loginContext("192.0.2.44", laptop), newAnomalyUser(), 1)
+		require.NoError(t, err)
+		assert.False(t, result.ImpossibleTravel)
+		assert.Equal(t, "JP", ptr.Deref(updated["country"].(*string)))
+		assert.Nil(t, updated["latitude"])
+	})
+
+	t.Run("repository error → error", func(t *testing.T) {
+		fingerprintRepo := &mockLoginFingerprintRepo{findLatestFn: func(int64, int64) (*model.LoginFingerprint, error) { return nil, errors.New("db down") }}
+		var logged []AuthEventInput
+		svc := newLoginAnomalyService(`{"new_device_notification_enabled": true}`, fingerprintRepo, nil, nil, &logged)
+
+		_, err := svc.Evaluate(loginContext("198.51.100.9", laptop), newAnomalyUser(), 1)
+		assert.Error(t, err)
+	})
+}
+
+// stubLoginAnomalyService returns a fixed result or error.
+type stubLoginAnomalyService struct {
+	result *LoginAnomalyResult
+	err    error
+}
+
+func (s *stubLoginAnomalyService) Evaluate(context.Context, *model.User, int64) (*LoginAnomalyResult, error) {
+	return s.result, s.err
+}
+
+// stubSecurityHoldService records the users the anomaly detector held.
+type stubSecurityHoldService struct {
+	held []int64
+	err  error
+}
+
+func (s *stubSecurityHoldService) Hold(context.Context, uuid.UUID, int64, uuid.UUID, string) (*UserServiceDataResult, error) {
+	return nil, s.err
+}
+
+func (s *stubSecurityHoldService) HoldSuspicious(_ context.Context, user *model.User, _ int64, _ string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.held = append(s.held, user.UserID)
+	return nil
+}
+
+func TestLogin_LoginAnomaly(t *testing.T) {
+	initTestJWTKeysService(t)
+	const password = "S3cur3P@ss!"
+
+	newService := func(anomalies LoginAnomalyService) LoginService {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		return NewLoginService(gormDB,
+			&mockClientRepo{findSystemFn: func() (*model.Client, error) { return buildActiveClient(), nil }},
+			&mockUserRepo{findByUsernameFn: func(_ string) (*model.User, error) { return buildActiveUser(t, password), nil }},
+			&mockUserTokenRepo{},
+			&mockUserIdentityRepo{findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) {
+				return &model.UserIdentity{Sub: "sub-anomaly"}, nil
+			}},
+			&mockIdentityProviderRepo{},
+			&mockTenantSettingRepo{},
+			&mockSecuritySettingRepo{},
+			&mockAuthEventService{},
+			nil,
+			nil,
+			anomalies,
+			nil,
+			nil,
+		)
+	}
+
+	t.Run("step-up required", func(t *testing.T) {
+		svc := newService(&stubLoginAnomalyService{result: &LoginAnomalyResult{NewDevice: true, StepUpRequired: true}})
+		result, err := svc.Login(context.Background(), "anomaly-step-up", password, nil, nil, nil)
+		require.NoError(t, err)
+		assert.True(t, result.StepUpRequired)
+	})
+
+	t.Run("security hold refuses the login", func(t *testing.T) {
+		svc := newService(&stubLoginAnomalyService{result: &LoginAnomalyResult{ImpossibleTravel: true, SecurityHold: true}})
+		_, err := svc.Login(context.Background(), "anomaly-hold", password, nil, nil, nil)
+		code, ok := apperror.CodeOf(err)
+		require.True(t, ok)
+		assert.Equal(t, apperror.CodeAccountSecurityHold, code)
+	})
+
+	t.Run("detection failure does not fail the login", func(t *testing.T) {
+		svc := newService(&stubLoginAnomalyService{err: errors.New("db down")})
+		result, err := svc.Login(context.Background(), "anomaly-failure", password, nil, nil, nil)
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.AccessToken)
+		assert.False(t, result.StepUpRequired)
+	})
+}
@@ -0,0 +1,507 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/hook"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/datatypes"
+)
+
+// Default settings applied when a login hook is created without them.
+const (
+	defaultLoginHookTimeoutMs = 2000
+	defaultLoginHookMessage   = "request denied"
+	loginHookFailedMessage    = "request could not be completed, please try again later"
+)
+
+// Outcomes recorded in the audit metadata of each hook run.
+const (
+	loginHookOutcomeAllow = "allow"
+	loginHookOutcomeDeny  = "deny"
+	loginHookOutcomeError = "error"
+)
+
+// LoginHookServiceDataResult is the service-layer representation of a
+// login_hooks record. The webhook secret is never returned.
+type LoginHookServiceDataResult struct {
+	LoginHookUUID uuid.UUID
+	TenantID      int64
+	Name          string
+	Trigger       string
+	Type          string
+	URL           string
+	HasSecret     bool
+	Rule          any
+	TimeoutMs     int
+	FailurePolicy string
+	Priority      int
+	Status        string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// LoginHookServiceListResult holds a paginated list of login hooks.
+type LoginHookServiceListResult struct {
+	Data       []LoginHookServiceDataResult
+	Total      int64
+	Page       int
+	Limit      int
+	TotalPages int
+}
+
+// LoginHookInput groups the writable fields of a login hook. A nil TimeoutMs
+// or Priority keeps the default (on create) or the current value (on update);
+// an empty Secret on update keeps the current secret.
+type LoginHookInput struct {
+	Name          string
+	Trigger       string
+	Type          string
+	URL           string
+	Secret        string
+	Rule          json.RawMessage
+	TimeoutMs     *int
+	FailurePolicy string
+	Priority      *int
+	Status        string
+}
+
+// LoginHookService manages tenant login hooks and runs them during the login
+// and registration flows.
+type LoginHookService interface {
+	GetAll(ctx context.Context, tenantID int64, trigger, status []string, page, limit int, sortBy, sortOrder string) (*LoginHookServiceListResult, error)
+	GetByUUID(ctx context.Context, tenantID int64, loginHookUUID uuid.UUID) (*LoginHookServiceDataResult, error)
+	Create(ctx context.Context, tenantID int64, input LoginHookInput) (*LoginHookServiceDataResult, error)
+	Update(ctx context.Context, tenantID int64, loginHookUUID uuid.UUID, input LoginHookInput) (*LoginHookServiceDataResult, error)
+	UpdateStatus(ctx context.Context, tenantID int64, loginHookUUID uuid.UUID, status string) (*LoginHookServiceDataResult, error)
+	Delete(ctx context.Context, tenantID int64, loginHookUUID uuid.UUID) (*LoginHookServiceDataResult, error)
+
+	// Run executes the tenant's active hooks for payload.Trigger in priority
+	// order. The first hook that denies stops the flow with a ForbiddenError
+	// carrying the hook's message. A hook that fails is skipped when its
+	// policy is fail_open and stops the flow otherwise. For post-login hooks
+	// the returned claims are the merge of every hook's claims, later hooks
+	// winning. Every hook run is written to the auth event log.
+	Run(ctx context.Context, tenantID int64, actorUserID *int64, payload hook.Payload) (map[string]any, error)
+}
+
+type loginHookService struct {
+	loginHookRepo    repository.LoginHookRepository
+	authEventService AuthEventService
+	httpClient       *http.Client
+}
+
+// NewLoginHookService creates a new LoginHookService.
+func NewLoginHookService(loginHookRepo repository.LoginHookRepository, authEventService AuthEventService) LoginHookService {
+	return &loginHookService{
+		loginHookRepo:    loginHookRepo,
+		authEventService: authEventService,
+		// Per-hook timeouts are applied through the request context.
+		httpClient: &http.Client{},
+	}
+}
+
+func toLoginHookServiceDataResult(lh *model.LoginHook) LoginHookServiceDataResult {
+	var rule any
+	if len(lh.Rule) > 0 {
+		_ = json.Unmarshal(lh.Rule, &rule)
+	}
+
+	return LoginHookServiceDataResult{
+		LoginHookUUID: lh.LoginHookUUID,
+		TenantID:      lh.TenantID,
+		Name:          lh.Name,
+		Trigger:       lh.Trigger,
+		Type:          lh.Type,
+		URL:           lh.URL,
+		HasSecret:     lh.SecretEncrypted != "",
+		Rule:          rule,
+		TimeoutMs:     lh.TimeoutMs,
+		FailurePolicy: lh.FailurePolicy,
+		Priority:      lh.Priority,
+		Status:        lh.Status,
+		CreatedAt:     lh.CreatedAt,
+		UpdatedAt:     lh.UpdatedAt,
+	}
+}
+
+// applyLoginHookInput copies input onto lh after checking that the fields
+// required by the hook type are present.
+func applyLoginHookInput(lh *model.LoginHook, input LoginHookInput) error {
+	switch input.Type {
+	case model.LoginHookTypeWebhook:
+		if input.URL == "" {
+			return apperror.NewValidation("url is required for webhook hooks")
+		}
+		lh.URL = input.URL
+		lh.Rule = nil
+		if input.Secret != "" {
+			lh.SecretEncrypted = input.Secret
+		}
+	case model.LoginHookTypeRule:
+		if len(input.Rule) == 0 {
+			return apperror.NewValidation("rule is required for rule hooks")
+		}
+		rule, err := hook.ParseRule(input.Rule)
+		if err != nil {
+			return apperror.NewValidation(err.Error())
+		}
+		if len(rule.Claims) > 0 && input.Trigger != model.LoginHookTriggerPostLogin {
+			return apperror.NewValidation("claims can only be set by post_login hooks")
+		}
+		lh.Rule = datatypes.JSON(input.Rule)
+		lh.URL = ""
+		lh.SecretEncrypted = ""
+	default:
+		return apperror.NewValidation("invalid hook type")
+	}
+
+	lh.Name = input.Name
+	lh.Trigger = input.Trigger
+	lh.Type = input.Type
+	lh.FailurePolicy = input.FailurePolicy
+	lh.Status = input.Status
+	if input.TimeoutMs != nil {
+		lh.TimeoutMs = *input.TimeoutMs
+	}
+	if input.Priority != nil {
+		lh.Priority = *input.Priority
+	}
+	return nil
+}
+
+// GetAll retrieves a paginated list of login hooks for a tenant.
+func (s *loginHookService) GetAll(ctx context.Context, tenantID int64, trigger, status []string, page, limit int, sortBy, sortOrder string) (*LoginHookServiceListResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "loginHook.list")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID))
+
+	result, err := s.loginHookRepo.FindPaginated(repository.LoginHookRepositoryGetFilter{
+		TenantID:  &tenantID,
+		Trigger:   trigger,
+		Status:    status,
+		Page:      page,
+		Limit:     limit,
+		SortBy:    sortBy,
+		SortOrder: sortOrder,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list login hooks failed")
+		return nil, err
+	}
+
+	data := make([]LoginHookServiceDataResult, len(result.Data))
+	for i, lh := range result.Data {
+		data[i] = toLoginHookServiceDataResult(&lh)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return &LoginHookServiceListResult{
+		Data:       data,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}, nil
+}
+
+// GetByUUID retrieves a single login hook by UUID, verifying tenant ownership.
+func (s *loginHookService) GetByUUID(ctx context.Context, tenantID int64, loginHookUUID uuid.UUID) (*LoginHookServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "loginHook.get")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("login_hook.uuid", loginHookUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	lh, err := s.loginHookRepo.FindByUUIDAndTenantID(loginHookUUID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get login hook failed")
+		return nil, err
+	}
+	if lh == nil {
+		span.SetStatus(codes.Error, "login hook not found")
+		return nil, apperror.NewNotFoundWithReason("login hook not found")
+	}
+
+	span.SetStatus(codes.Ok, "")
+	result := toLoginHookServiceDataResult(lh)
+	return &result, nil
+}
+
+// Create creates a new login hook for a tenant.
+func (s *loginHookService) Create(ctx context.Context, tenantID int64, input LoginHookInput) (*LoginHookServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "loginHook.create")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID))
+
+	lh := &model.LoginHook{
+		TenantID:  tenantID,
+		TimeoutMs: defaultLoginHookTimeoutMs,
+	}
+	if err := applyLoginHookInput(lh, input); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid login hook")
+		return nil, err
+	}
+
+	created, err := s.loginHookRepo.Create(lh)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "create login hook failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	result := toLoginHookServiceDataResult(created)
+	return &result, nil
+}
+
+// Update updates an existing login hook, verifying tenant ownership.
+func (s *loginHookService) Update(ctx context.Context, tenantID int64, loginHookUUID uuid.UUID, input LoginHookInput) (*LoginHookServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "loginHook.update")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("login_hook.uuid", loginHookUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	lh, err := s.loginHookRepo.FindByUUIDAndTenantID(loginHookUUID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find login hook for update failed")
+		return nil, err
+	}
+	if lh == nil {
+		span.SetStatus(codes.Error, "login hook not found")
+		return nil, apperror.NewNotFoundWithReason("login hook not found")
+	}
+
+	if err := applyLoginHookInput(lh, input); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid login hook")
+		return nil, err
+	}
+
+	updated, err := s.loginHookRepo.UpdateByUUID(loginHookUUID, lh)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "update login hook failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	result := toLoginHookServiceDataResult(updated)
+	return &result, nil
+}
+
+// UpdateStatus updates only the status field of a login hook.
+func (s *loginHookService) UpdateStatus(ctx context.Context, tenantID int64, loginHookUUID uuid.UUID, status string) (*LoginHookServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "loginHook.updateStatus")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("login_hook.uuid", loginHookUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	lh, err := s.loginHookRepo.FindByUUIDAndTenantID(loginHookUUID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find login hook for status update failed")
+		return nil, err
+	}
+	if lh == nil {
+		span.SetStatus(codes.Error, "login hook not found")
+		return nil, apperror.NewNotFoundWithReason("login hook not found")
+	}
+
+	lh.Status = status
+
+	updated, err := s.loginHookRepo.UpdateByUUID(loginHookUUID, lh)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "update login hook status failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	result := toLoginHookServiceDataResult(updated)
+	return &result, nil
+}
+
+// Delete deletes a login hook, verifying tenant ownership first.
+func (s *loginHookService) Delete(ctx context.Context, tenantID int64, loginHookUUID uuid.UUID) (*LoginHookServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "loginHook.delete")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("login_hook.uuid", loginHookUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	lh, err := s.loginHookRepo.FindByUUIDAndTenantID(loginHookUUID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find login hook for delete failed")
+		return nil, err
+	}
+	if lh == nil {
+		span.SetStatus(codes.Error, "login hook not found")
+		return nil, apperror.NewNotFoundWithReason("login hook not found")
+	}
+
+	if err := s.loginHookRepo.DeleteByUUID(loginHookUUID); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete login hook failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	result := toLoginHookServiceDataResult(lh)
+	return &result, nil
+}
+
+// loginHookRunMetadata is the per-run context stored in the auth event.
+type loginHookRunMetadata struct {
+	HookUUID      string `json:"hook_uuid"`
+	HookName      string `json:"hook_name"`
+	Trigger       string `json:"trigger"`
+	Type          string `json:"type"`
+	Outcome       string `json:"outcome"`
+	FailurePolicy string `json:"failure_policy"`
+	DurationMs    int64  `json:"duration_ms"`
+}
+
+func (s *loginHookService) Run(ctx context.Context, tenantID int64, actorUserID *int64, payload hook.Payload) (map[string]any, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "loginHook.run")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int64("tenant.id", tenantID),
+		attribute.String("login_hook.trigger", payload.Trigger),
+	)
+
+	hooks, err := s.loginHookRepo.FindActiveByTrigger(tenantID, payload.Trigger)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "load login hooks failed")
+		return nil, apperror.NewInternal("failed to load login hooks", err)
+	}
+
+	claims := map[string]any{}
+	for i := range hooks {
+		lh := &hooks[i]
+		start := time.Now()
+		res, runErr := s.execute(ctx, lh, payload)
+		elapsed := time.Since(start)
+
+		switch {
+		case runErr != nil:
+			s.logRun(ctx, tenantID, actorUserID, payload, lh, loginHookOutcomeError, elapsed, runErr.Error())
+			if lh.FailurePolicy == model.LoginHookFailOpen {
+				continue
+			}
+			span.RecordError(runErr)
+			span.SetStatus(codes.Error, "login hook failed")
+			return nil, apperror.NewForbidden(loginHookFailedMessage)
+		case !res.Allow:
+			message := res.Message
+			if message == "" {
+				message = defaultLoginHookMessage
+			}
+			s.logRun(ctx, tenantID, actorUserID, payload, lh, loginHookOutcomeDeny, elapsed, message)
+			span.SetStatus(codes.Error, "denied by login hook")
+			return nil, apperror.NewForbidden(message)
+		}
+
+		s.logRun(ctx, tenantID, actorUserID, payload, lh, loginHookOutcomeAllow, elapsed, "")
+		if payload.Trigger == model.LoginHookTriggerPostLogin {
+			for k, v := range res.Claims {
+				claims[k] = v
+			}
+		}
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return claims, nil
+}
+
+// execute runs a single hook.
+func (s *loginHookService) execute(ctx context.Context, lh *model.LoginHook, payload hook.Payload) (hook.Result, error) {
+	switch lh.Type {
+	case model.LoginHookTypeRule:
+		rule, err := hook.ParseRule(lh.Rule)
+		if err != nil {
+			return hook.Result{}, err
+		}
+		return rule.Evaluate(payload), nil
+	case model.LoginHookTypeWebhook:
+		timeout := time.Duration(lh.TimeoutMs) * time.Millisecond
+		return hook.CallWebhook(ctx, s.httpClient, lh.URL, lh.SecretEncrypted, timeout, payload)
+	default:
+		return hook.Result{}, errors.New("unknown hook type " + lh.Type)
+	}
+}
+
+func (s *loginHookService) logRun(
+	ctx context.Context,
+	tenantID int64,
+	actorUserID *int64,
+	payload hook.Payload,
+	lh *model.LoginHook,
+	outcome string,
+	elapsed time.Duration,
+	reason string,
+) {
+	// Marshalling a struct of strings and integers cannot fail.
+	meta, _ := json.Marshal(loginHookRunMetadata{
+		HookUUID:      lh.LoginHookUUID.String(),
+		HookName:      lh.Name,
+		Trigger:       lh.Trigger,
+		Type:          lh.Type,
+		Outcome:       outcome,
+		FailurePolicy: lh.FailurePolicy,
+		DurationMs:    elapsed.Milliseconds(),
+	})
+
+	input := AuthEventInput{
+		TenantID:    tenantID,
+		ActorUserID: actorUserID,
+		IPAddress:   payload.Request.IPAddress,
+		UserAgent:   ptr.PtrOrNil(payload.Request.UserAgent),
+		Category:    model.AuthEventCategoryAuthn,
+		EventType:   model.AuthEventTypeHookExecuted,
+		Severity:    model.AuthEventSeverityInfo,
+		Result:      model.AuthEventResultSuccess,
+		Description: ptr.Ptr(fmt.Sprintf("Login hook %s allowed %s", lh.Name, lh.Trigger)),
+		Metadata:    meta,
+	}
+	switch outcome {
+	case loginHookOutcomeDeny:
+		input.EventType = model.AuthEventTypeHookFail
+		input.Severity = model.AuthEventSeverityWarn
+		input.Result = model.AuthEventResultFailure
+		input.Description = ptr.Ptr(fmt.Sprintf("Login hook %s denied %s", lh.Name, lh.Trigger))
+		input.ErrorReason = ptr.Ptr(reason)
+	case loginHookOutcomeError:
+		input.EventType = model.AuthEventTypeHookFail
+		input.Severity = model.AuthEventSeverityWarn
+		input.Result = model.AuthEventResultFailure
+		input.Description = ptr.Ptr(fmt.Sprintf("Login hook %s failed during %s", lh.Name, lh.Trigger))
+		input.ErrorReason = ptr.Ptr(reason)
+	}
+
+	s.authEventService.Log(ctx, input)
+}
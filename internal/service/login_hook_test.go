@@ -0,0 +1,511 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/hook"
+	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func newLoginHookSvc(repo *mockLoginHookRepo, events *mockAuthEventService) LoginHookService {
+	if events == nil {
+		events = &mockAuthEventService{}
+	}
+	return NewLoginHookService(repo, events)
+}
+
+func newRuleLoginHook(tenantID int64, trigger, rule string) model.LoginHook {
+	return model.LoginHook{
+		LoginHookID:   1,
+		LoginHookUUID: uuid.New(),
+		TenantID:      tenantID,
+		Name:          "rule hook",
+		Trigger:       trigger,
+		Type:          model.LoginHookTypeRule,
+		Rule:          datatypes.JSON(rule),
+		TimeoutMs:     defaultLoginHookTimeoutMs,
+		FailurePolicy: model.LoginHookFailClosed,
+		Status:        model.StatusActive,
+	}
+}
+
+func newWebhookLoginHook(tenantID int64, trigger, url, policy string) model.LoginHook {
+	return model.LoginHook{
+		LoginHookID:     2,
+		LoginHookUUID:   uuid.New(),
+		TenantID:        tenantID,
+		Name:            "webhook hook",
+		Trigger:         trigger,
+		Type:            model.LoginHookTypeWebhook,
+		URL:             url,
+		SecretEncrypted: "s3cret",
+		TimeoutMs:       defaultLoginHookTimeoutMs,
+		FailurePolicy:   policy,
+		Status:          model.StatusActive,
+	}
+}
+
+func webhookInput() LoginHookInput {
+	return LoginHookInput{
+		Name:          "Risk check",
+		Trigger:       model.LoginHookTriggerPreLogin,
+		Type:          model.LoginHookTypeWebhook,
+		URL:           "https://hooks.example.com/login",
+		Secret:        "s3cret",
+		FailurePolicy: model.LoginHookFailClosed,
+		Status:        model.StatusActive,
+	}
+}
+
+// ---------------------------------------------------------------------------
+// GetAll / GetByUUID
+// ---------------------------------------------------------------------------
+
+func TestLoginHookService_GetAll(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		lh := newRuleLoginHook(1, model.LoginHookTriggerPostLogin, `{"action":"allow","claims":{"tier":"gold"}}`)
+		var gotFilter repository.LoginHookRepositoryGetFilter
+		svc := newLoginHookSvc(&mockLoginHookRepo{
+			findPaginatedFn: func(f repository.LoginHookRepositoryGetFilter) (*repository.PaginationResult[model.LoginHook], error) {
+				gotFilter = f
+				return &repository.PaginationResult[model.LoginHook]{Data: []model.LoginHook{lh}, Total: 1, Page: 1, Limit: 10, TotalPages: 1}, nil
+			},
+		}, nil)
+
+		res, err := svc.GetAll(context.Background(), 1, []string{model.LoginHookTriggerPostLogin}, nil, 1, 10, "priority", "asc")
+		require.NoError(t, err)
+		require.Len(t, res.Data, 1)
+		assert.Equal(t, map[string]any{"action": "allow", "claims": map[string]any{"tier": "gold"}}, res.Data[0].Rule)
+		assert.Equal(t, int64(1), *gotFilter.TenantID)
+		assert.Equal(t, []string{model.LoginHookTriggerPostLogin}, gotFilter.Trigger)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		svc := newLoginHookSvc(&mockLoginHookRepo{
+			findPaginatedFn: func(_ repository.LoginHookRepositoryGetFilter) (*repository.PaginationResult[model.LoginHook], error) {
+				return nil, errors.New("db error")
+			},
+		}, nil)
+		_, err := svc.GetAll(context.Background(), 1, nil, nil, 1, 10, "", "")
+		require.Error(t, err)
+	})
+}
+
+func TestLoginHookService_GetByUUID(t *testing.T) {
+	t.Run("success hides secret", func(t *testing.T) {
+		lh := newWebhookLoginHook(1, model.LoginHookTriggerPreLogin, "https://hooks.example.com", model.LoginHookFailOpen)
+		svc := newLoginHookSvc(&mockLoginHookRepo{
+			findByUUIDAndTenantFn: func(_ uuid.UUID, _ int64) (*model.LoginHook, error) { return &lh, nil },
+		}, nil)
+
+		res, err := svc.GetByUUID(context.Background(), 1, lh.LoginHookUUID)
+		require.NoError(t, err)
+		assert.True(t, res.HasSecret)
+		assert.Equal(t, lh.URL, res.URL)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc := newLoginHookSvc(&mockLoginHookRepo{}, nil)
+		_, err := svc.GetByUUID(context.Background(), 1, uuid.New())
+		var target *apperror.NotFoundError
+		require.ErrorAs(t, err, &target)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		svc := newLoginHookSvc(&mockLoginHookRepo{
+			findByUUIDAndTenantFn: func(_ uuid.UUID, _ int64) (*model.LoginHook, error) { return nil, errors.New("db error") },
+		}, nil)
+		_, err := svc.GetByUUID(context.Background(), 1, uuid.New())
+		require.Error(t, err)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Create / Update
+// ---------------------------------------------------------------------------
+
+func TestLoginHookService_Create(t *testing.T) {
+	t.Run("webhook with defaults", func(t *testing.T) {
+		var created *model.LoginHook
+		svc := newLoginHookSvc(&mockLoginHookRepo{
+			createFn: func(e *model.LoginHook) (*model.LoginHook, error) { created = e; return e, nil },
+		}, nil)
+
+		res, err := svc.Create(context.Background(), 7, webhookInput())
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), created.TenantID)
+		assert.Equal(t, "s3cret", created.SecretEncrypted)
+		assert.Equal(t, defaultLoginHookTimeoutMs, created.TimeoutMs)
+		assert.True(t, res.HasSecret)
+	})
+
+	t.Run("rule", func(t *testing.T) {
+		in := LoginHookInput{
+			Name:          "Block contractors",
+			Trigger:       model.LoginHookTriggerPreLogin,
+			Type:          model.LoginHookTypeRule,
+			Rule:          json.RawMessage(`{"conditions":[{"field":"user.email","operator":"ends_with","value":"@contractor.example"}],"action":"deny"}`),
+			FailurePolicy: model.LoginHookFailClosed,
+			Status:        model.StatusActive,
+		}
+		priority, timeout := 5, 500
+		in.Priority, in.TimeoutMs = &priority, &timeout
+
+		res, err := newLoginHookSvc(&mockLoginHookRepo{}, nil).Create(context.Background(), 1, in)
+		require.NoError(t, err)
+		assert.Equal(t, 5, res.Priority)
+		assert.Equal(t, 500, res.TimeoutMs)
+		assert.Empty(t, res.URL)
+	})
+
+	invalid := map[string]func(*LoginHookInput){
+		"webhook without url": func(in *LoginHookInput) { in.URL = "" },
+		"rule without rule":   func(in *LoginHookInput) { in.Type = model.LoginHookTypeRule },
+		"malformed rule": func(in *LoginHookInput) {
+			in.Type = model.LoginHookTypeRule
+			in.Rule = json.RawMessage(`{"action":"explode"}`)
+		},
+		"claims outside post_login": func(in *LoginHookInput) {
+			in.Type = model.LoginHookTypeRule
+			in.Rule = json.RawMessage(`{"action":"allow","claims":{"tier":"gold"}}`)
+		},
+		"unknown type": func(in *LoginHookInput) { in.Type = "script" },
+	}
+	for name, mutate := range invalid {
+		t.Run(name, func(t *testing.T) {
+			in := webhookInput()
+			mutate(&in)
+			_, err := newLoginHookSvc(&mockLoginHookRepo{}, nil).Create(context.Background(), 1, in)
+			var target *apperror.ValidationError
+			require.ErrorAs(t, err, &target)
+		})
+	}
+
+	t.Run("repo error", func(t *testing.T) {
+		svc := newLoginHookSvc(&mockLoginHookRepo{
+			createFn: func(_ *model.LoginHook) (*model.LoginHook, error) { return nil, errors.New("db error") },
+		}, nil)
+		_, err := svc.Create(context.Background(), 1, webhookInput())
+		require.Error(t, err)
+	})
+}
+
+func TestLoginHookService_Update(t *testing.T) {
+	t.Run("empty secret keeps current secret", func(t *testing.T) {
+		lh := newWebhookLoginHook(1, model.LoginHookTriggerPreLogin, "https://old.example.com", model.LoginHookFailClosed)
+		var updated *model.LoginHook
+		svc := newLoginHookSvc(&mockLoginHookRepo{
+			findByUUIDAndTenantFn: func(_ uuid.UUID, _ int64) (*model.LoginHook, error) { return &lh, nil },
+			updateByUUIDFn: func(_, data any) (*model.LoginHook, error) {
+				updated = data.(*model.LoginHook)
+				return updated, nil
+			},
+		}, nil)
+
+		in := webhookInput()
+		in.Secret = ""
+		_, err := svc.Update(context.Background(), 1, lh.LoginHookUUID, in)
+		require.NoError(t, err)
+		assert.Equal(t, "s3cret", updated.SecretEncrypted)
+		assert.Equal(t, "https://hooks.example.com/login", updated.URL)
+	})
+
+	t.Run("switching to rule clears webhook fields", func(t *testing.T) {
+		lh := newWebhookLoginHook(1, model.LoginHookTriggerPreLogin, "https://old.example.com", model.LoginHookFailClosed)
+		svc := newLoginHookSvc(&mockLoginHookRepo{
+			findByUUIDAndTenantFn: func(_ uuid.UUID, _ int64) (*model.LoginHook, error) { return &lh, nil },
+			updateByUUIDFn:        func(_, data any) (*model.LoginHook, error) { return data.(*model.LoginHook), nil },
+		}, nil)
+
+		in := webhookInput()
+		in.Type = model.LoginHookTypeRule
+		in.Rule = json.RawMessage(`{"action":"deny"}`)
+		res, err := svc.Update(context.Background(), 1, lh.LoginHookUUID, in)
+		require.NoError(t, err)
+		assert.Empty(t, res.URL)
+		assert.False(t, res.HasSecret)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := newLoginHookSvc(&mockLoginHookRepo{}, nil).Update(context.Background(), 1, uuid.New(), webhookInput())
+		var target *apperror.NotFoundError
+		require.ErrorAs(t, err, &target)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		lh := newWebhookLoginHook(1, model.LoginHookTriggerPreLogin, "https://old.example.com", model.LoginHookFailClosed)
+		svc := newLoginHookSvc(&mockLoginHookRepo{
+			findByUUIDAndTenantFn: func(_ uuid.UUID, _ int64) (*model.LoginHook, error) { return &lh, nil },
+		}, nil)
+		in := webhookInput()
+		in.URL = ""
+		_, err := svc.Update(context.Background(), 1, lh.LoginHookUUID, in)
+		var target *apperror.ValidationError
+		require.ErrorAs(t, err, &target)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// UpdateStatus / Delete
+// ---------------------------------------------------------------------------
+
+func TestLoginHookService_UpdateStatus(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		lh := newRuleLoginHook(1, model.LoginHookTriggerPreLogin, `{"action":"deny"}`)
+		svc := newLoginHookSvc(&mockLoginHookRepo{
+			findByUUIDAndTenantFn: func(_ uuid.UUID, _ int64) (*model.LoginHook, error) { return &lh, nil },
+			updateByUUIDFn:        func(_, data any) (*model.LoginHook, error) { return data.(*model.LoginHook), nil },
+		}, nil)
+
+		res, err := svc.UpdateStatus(context.Background(), 1, lh.LoginHookUUID, model.StatusInactive)
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusInactive, res.Status)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := newLoginHookSvc(&mockLoginHookRepo{}, nil).UpdateStatus(context.Background(), 1, uuid.New(), model.StatusInactive)
+		var target *apperror.NotFoundError
+		require.ErrorAs(t, err, &target)
+	})
+}
+
+func TestLoginHookService_Delete(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		lh := newRuleLoginHook(1, model.LoginHookTriggerPreLogin, `{"action":"deny"}`)
+		deleted := false
+		svc := newLoginHookSvc(&mockLoginHookRepo{
+			findByUUIDAndTenantFn: func(_ uuid.UUID, _ int64) (*model.LoginHook, error) { return &lh, nil },
+			deleteByUUIDFn:        func(_ any) error { deleted = true; return nil },
+		}, nil)
+
+		_, err := svc.Delete(context.Background(), 1, lh.LoginHookUUID)
+		require.NoError(t, err)
+		assert.True(t, deleted)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := newLoginHookSvc(&mockLoginHookRepo{}, nil).Delete(context.Background(), 1, uuid.New())
+		var target *apperror.NotFoundError
+		require.ErrorAs(t, err, &target)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		lh := newRuleLoginHook(1, model.LoginHookTriggerPreLogin, `{"action":"deny"}`)
+		svc := newLoginHookSvc(&mockLoginHookRepo{
+			findByUUIDAndTenantFn: func(_ uuid.UUID, _ int64) (*model.LoginHook, error) { return &lh, nil },
+			deleteByUUIDFn:        func(_ any) error { return errors.New("db error") },
+		}, nil)
+		_, err := svc.Delete(context.Background(), 1, lh.LoginHookUUID)
+		require.Error(t, err)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Run
+// ---------------------------------------------------------------------------
+
+func hookPayload(trigger string) hook.Payload {
+	return hook.Payload{
+		Trigger: trigger,
+		User:    hook.User{Username: "alice", Email: "alice@contractor.example"},
+		Client:  hook.Client{ClientID: "web"},
+		Request: hook.Request{IPAddress: "10.0.0.1", UserAgent: "test"},
+	}
+}
+
+func hookRepoWith(hooks ...model.LoginHook) *mockLoginHookRepo {
+	return &mockLoginHookRepo{
+		findActiveByTriggerFn: func(_ int64, trigger string) ([]model.LoginHook, error) {
+			var out []model.LoginHook
+			for _, h := range hooks {
+				if h.Trigger == trigger {
+					out = append(out, h)
+				}
+			}
+			return out, nil
+		},
+	}
+}
+
+func TestLoginHookService_Run(t *testing.T) {
+	ctx := context.Background()
+	actor := int64(42)
+
+	t.Run("no hooks allows", func(t *testing.T) {
+		claims, err := newLoginHookSvc(&mockLoginHookRepo{}, nil).Run(ctx, 1, &actor, hookPayload(model.LoginHookTriggerPreLogin))
+		require.NoError(t, err)
+		assert.Empty(t, claims)
+	})
+
+	t.Run("rule deny stops the flow and is audited", func(t *testing.T) {
+		var events []AuthEventInput
+		svc := newLoginHookSvc(hookRepoWith(
+			newRuleLoginHook(1, model.LoginHookTriggerPreLogin,
+				`{"conditions":[{"field":"user.email","operator":"ends_with","value":"@contractor.example"}],"action":"deny","message":"Contractors must use SSO"}`),
+		), &mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { events = append(events, in) }})
+
+		_, err := svc.Run(ctx, 1, &actor, hookPayload(model.LoginHookTriggerPreLogin))
+		var target *apperror.ForbiddenError
+		require.ErrorAs(t, err, &target)
+		assert.Equal(t, "Contractors must use SSO", target.Error())
+
+		require.Len(t, events, 1)
+		assert.Equal(t, model.AuthEventTypeHookFail, events[0].EventType)
+		assert.Equal(t, model.AuthEventResultFailure, events[0].Result)
+		assert.Equal(t, &actor, events[0].ActorUserID)
+		var meta loginHookRunMetadata
+		require.NoError(t, json.Unmarshal(events[0].Metadata, &meta))
+		assert.Equal(t, loginHookOutcomeDeny, meta.Outcome)
+		assert.Equal(t, model.LoginHookTriggerPreLogin, meta.Trigger)
+	})
+
+	t.Run("deny without message uses default", func(t *testing.T) {
+		svc := newLoginHookSvc(hookRepoWith(
+			newRuleLoginHook(1, model.LoginHookTriggerPreRegistration, `{"action":"deny"}`),
+		), nil)
+		_, err := svc.Run(ctx, 1, nil, hookPayload(model.LoginHookTriggerPreRegistration))
+		require.Error(t, err)
+		assert.Equal(t, defaultLoginHookMessage, err.Error())
+	})
+
+	t.Run("post_login claims are merged, later hooks win", func(t *testing.T) {
+		first := newRuleLoginHook(1, model.LoginHookTriggerPostLogin, `{"action":"allow","claims":{"tier":"silver","region":"eu"}}`)
+		second := newRuleLoginHook(1, model.LoginHookTriggerPostLogin, `{"action":"allow","claims":{"tier":"gold"}}`)
+		var events []AuthEventInput
+		svc := newLoginHookSvc(hookRepoWith(first, second),
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { events = append(events, in) }})
+
+		claims, err := svc.Run(ctx, 1, &actor, hookPayload(model.LoginHookTriggerPostLogin))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"tier": "gold", "region": "eu"}, claims)
+		require.Len(t, events, 2)
+		assert.Equal(t, model.AuthEventTypeHookExecuted, events[0].EventType)
+	})
+
+	t.Run("webhook claims ignored outside post_login", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"allow":true,"claims":{"tier":"gold"}}`))
+		}))
+		defer srv.Close()
+
+		svc := newLoginHookSvc(hookRepoWith(
+			newWebhookLoginHook(1, model.LoginHookTriggerPreLogin, srv.URL, model.LoginHookFailClosed),
+		), nil)
+		claims, err := svc.Run(ctx, 1, &actor, hookPayload(model.LoginHookTriggerPreLogin))
+		require.NoError(t, err)
+		assert.Empty(t, claims)
+	})
+
+	t.Run("failing webhook with fail_open is skipped", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		var events []AuthEventInput
+		svc := newLoginHookSvc(hookRepoWith(
+			newWebhookLoginHook(1, model.LoginHookTriggerPostLogin, srv.URL, model.LoginHookFailOpen),
+			newRuleLoginHook(1, model.LoginHookTriggerPostLogin, `{"action":"allow","claims":{"tier":"gold"}}`),
+		), &mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { events = append(events, in) }})
+
+		claims, err := svc.Run(ctx, 1, &actor, hookPayload(model.LoginHookTriggerPostLogin))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"tier": "gold"}, claims)
+		require.Len(t, events, 2)
+		assert.Equal(t, model.AuthEventTypeHookFail, events[0].EventType)
+		require.NotNil(t, events[0].ErrorReason)
+		assert.Contains(t, *events[0].ErrorReason, "500")
+	})
+
+	t.Run("failing webhook with fail_closed stops the flow", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		svc := newLoginHookSvc(hookRepoWith(
+			newWebhookLoginHook(1, model.LoginHookTriggerPreLogin, srv.URL, model.LoginHookFailClosed),
+		), nil)
+		_, err := svc.Run(ctx, 1, &actor, hookPayload(model.LoginHookTriggerPreLogin))
+		var target *apperror.ForbiddenError
+		require.ErrorAs(t, err, &target)
+		assert.Equal(t, loginHookFailedMessage, target.Error())
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		svc := newLoginHookSvc(&mockLoginHookRepo{
+			findActiveByTriggerFn: func(_ int64, _ string) ([]model.LoginHook, error) { return nil, errors.New("db error") },
+		}, nil)
+		_, err := svc.Run(ctx, 1, &actor, hookPayload(model.LoginHookTriggerPreLogin))
+		var target *apperror.InternalError
+		require.ErrorAs(t, err, &target)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Login integration
+// ---------------------------------------------------------------------------
+
+func newHookedLoginService(t *testing.T, password string, hooks ...model.LoginHook) LoginService {
+	t.Helper()
+	gormDB, mock := newMockGormDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	return NewLoginService(gormDB,
+		&mockClientRepo{findSystemFn: func() (*model.Client, error) { return buildActiveClient(), nil }},
+		&mockUserRepo{findByUsernameFn: func(_ string) (*model.User, error) { return buildActiveUser(t, password), nil }},
+		&mockUserTokenRepo{},
+		&mockUserIdentityRepo{findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) {
+			return &model.UserIdentity{Sub: "sub-hooked"}, nil
+		}},
+		&mockIdentityProviderRepo{},
+		&mockTenantSettingRepo{},
+		&mockSecuritySettingRepo{},
+		&mockAuthEventService{},
+		newLoginHookSvc(hookRepoWith(hooks...), nil),
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+}
+
+func TestLogin_PreLoginHookDenies(t *testing.T) {
+	initTestJWTKeysService(t)
+	const password = "S3cur3P@ss!"
+
+	svc := newHookedLoginService(t, password,
+		newRuleLoginHook(1, model.LoginHookTriggerPreLogin, `{"action":"deny","message":"Logins are paused"}`))
+	_, err := svc.Login(context.Background(), "hook-denied", password, nil, nil, nil)
+	var target *apperror.ForbiddenError
+	require.ErrorAs(t, err, &target)
+	assert.Equal(t, "Logins are paused", target.Error())
+}
+
+func TestLogin_PostLoginHookAddsClaims(t *testing.T) {
+	initTestJWTKeysService(t)
+	const password = "S3cur3P@ss!"
+
+	svc := newHookedLoginService(t, password,
+		newRuleLoginHook(1, model.LoginHookTriggerPostLogin, `{"action":"allow","claims":{"tier":"gold","sub":"spoofed"}}`))
+	res, err := svc.Login(context.Background(), "hook-claims", password, nil, nil, nil)
+	require.NoError(t, err)
+
+	claims, err := jwt.ValidateToken(res.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "gold", claims["tier"])
+	assert.Equal(t, "sub-hooked", claims["sub"])
+}
@@ -36,6 +36,19 @@ type LoginTemplateServiceListResult struct {
 	TotalPages int
 }
 
+// LoginTemplateHostedPageResult is everything the hosted login page needs to
+// render for one auth client. Branding is nil when the tenant has none.
+type LoginTemplateHostedPageResult struct {
+	ClientName string
+	Template   string
+	Metadata   map[string]any
+	Branding   *BrandingServiceDataResult
+}
+
+// clientConfigLoginTemplateKey is the client config key that pins a client to
+// a specific login template instead of the tenant default.
+const clientConfigLoginTemplateKey = "login_template_id"
+
 type LoginTemplateService interface {
 	GetAll(ctx context.Context, tenantID int64, name *string, status []string, template *string, isDefault, isSystem *bool, page, limit int, sortBy, sortOrder string) (*LoginTemplateServiceListResult, error)
 	GetByUUID(ctx context.Context, loginTemplateUUID uuid.UUID, tenantID int64) (*LoginTemplateServiceDataResult, error)
@@ -43,15 +56,29 @@ type LoginTemplateService interface {
 	Update(ctx context.Context, loginTemplateUUID uuid.UUID, tenantID int64, name string, description *string, template string, metadata map[string]any, status string) (*LoginTemplateServiceDataResult, error)
 	UpdateStatus(ctx context.Context, loginTemplateUUID uuid.UUID, tenantID int64, status string) (*LoginTemplateServiceDataResult, error)
 	Delete(ctx context.Context, loginTemplateUUID uuid.UUID, tenantID int64) (*LoginTemplateServiceDataResult, error)
+
+	// GetHostedPage resolves the template and branding for the hosted login
+	// page of an active auth client. The client's pinned template is used
+	// when it is active, then the tenant default, then the built-in modern
+	// style.
+	GetHostedPage(ctx context.Context, clientID, providerID string) (*LoginTemplateHostedPageResult, error)
 }
 
 type loginTemplateService struct {
 	loginTemplateRepo repository.LoginTemplateRepository
+	clientRepo        repository.ClientRepository
+	brandingRepo      repository.BrandingRepository
 }
 
-func NewLoginTemplateService(loginTemplateRepo repository.LoginTemplateRepository) LoginTemplateService {
+func NewLoginTemplateService(
+	loginTemplateRepo repository.LoginTemplateRepository,
+	clientRepo repository.ClientRepository,
+	brandingRepo repository.BrandingRepository,
+) LoginTemplateService {
 	return &loginTemplateService{
 		loginTemplateRepo: loginTemplateRepo,
+		clientRepo:        clientRepo,
+		brandingRepo:      brandingRepo,
 	}
 }
 
@@ -266,6 +293,82 @@ func (s *loginTemplateService) Delete(ctx context.Context, loginTemplateUUID uui
 	return &result, nil
 }
 
+func (s *loginTemplateService) GetHostedPage(ctx context.Context, clientID, providerID string) (*LoginTemplateHostedPageResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "loginTemplate.hostedPage")
+	defer span.End()
+	span.SetAttributes(attribute.String("client.id", clientID))
+
+	client, err := s.clientRepo.FindByClientIDAndIdentityProvider(clientID, providerID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "client lookup failed")
+		return nil, err
+	}
+	if client == nil {
+		return nil, apperror.NewNotFoundWithReason("client not found")
+	}
+
+	template, err := s.resolveHostedTemplate(client)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "login template lookup failed")
+		return nil, err
+	}
+
+	branding, err := s.brandingRepo.FindByTenantID(client.TenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "branding lookup failed")
+		return nil, err
+	}
+
+	clientName := client.DisplayName
+	if clientName == "" {
+		clientName = client.Name
+	}
+
+	result := &LoginTemplateHostedPageResult{
+		ClientName: clientName,
+		Template:   model.LoginTemplateModern,
+		Metadata:   make(map[string]any),
+	}
+	if template != nil {
+		data := toLoginTemplateServiceDataResult(template)
+		result.Template = data.Template
+		result.Metadata = data.Metadata
+	}
+	if branding != nil {
+		result.Branding = toBrandingServiceDataResult(branding)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// resolveHostedTemplate returns the template pinned in the client config when
+// it exists and is active, otherwise the tenant default. Returns nil when
+// neither exists.
+func (s *loginTemplateService) resolveHostedTemplate(client *model.Client) (*model.LoginTemplate, error) {
+	var config map[string]any
+	if len(client.Config) > 0 {
+		_ = json.Unmarshal(client.Config, &config)
+	}
+
+	if raw, ok := config[clientConfigLoginTemplateKey].(string); ok {
+		if templateUUID, err := uuid.Parse(raw); err == nil {
+			template, err := s.loginTemplateRepo.FindByUUIDAndTenantID(templateUUID, client.TenantID)
+			if err != nil {
+				return nil, err
+			}
+			if template != nil && template.Status == model.StatusActive {
+				return template, nil
+			}
+		}
+	}
+
+	return s.loginTemplateRepo.FindDefaultByTenantID(client.TenantID)
+}
+
 func toLoginTemplateServiceDataResult(template *model.LoginTemplate) LoginTemplateServiceDataResult {
 	var metadata map[string]any
 	if len(template.Metadata) > 0 {
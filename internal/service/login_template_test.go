@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/repository"
 	"github.com/stretchr/testify/assert"
@@ -14,7 +15,7 @@ import (
 )
 
 func newLoginTemplateSvc(repo *mockLoginTemplateRepo) LoginTemplateService {
-	return NewLoginTemplateService(repo)
+	return NewLoginTemplateService(repo, &mockClientRepo{}, &mockBrandingRepo{})
 }
 
 func TestLoginTemplateService_GetByUUID(t *testing.T) {
@@ -345,3 +346,100 @@ func TestToLoginTemplateServiceDataResult_InvalidMetadata(t *testing.T) {
 	assert.NotNil(t, result.Metadata)
 	assert.Empty(t, result.Metadata)
 }
+
+func TestLoginTemplateService_GetHostedPage(t *testing.T) {
+	pinned := uuid.New()
+	activeClient := func(config string) func(string, string) (*model.Client, error) {
+		return func(_, _ string) (*model.Client, error) {
+			return &model.Client{TenantID: 7, Name: "web", DisplayName: "Web App", Config: datatypes.JSON(config)}, nil
+		}
+	}
+	defaultTemplate := func(tID int64) (*model.LoginTemplate, error) {
+		assert.Equal(t, int64(7), tID)
+		return &model.LoginTemplate{Template: model.LoginTemplateClassic, Metadata: datatypes.JSON(`{"title":"Default"}`)}, nil
+	}
+
+	t.Run("client not found", func(t *testing.T) {
+		svc := NewLoginTemplateService(&mockLoginTemplateRepo{}, &mockClientRepo{}, &mockBrandingRepo{})
+		_, err := svc.GetHostedPage(context.Background(), "web", "idp")
+		var target *apperror.NotFoundError
+		require.ErrorAs(t, err, &target)
+	})
+
+	t.Run("client lookup error", func(t *testing.T) {
+		svc := NewLoginTemplateService(&mockLoginTemplateRepo{}, &mockClientRepo{
+			findByClientIDAndIdentityProviderFn: func(_, _ string) (*model.Client, error) { return nil, errors.New("db error") },
+		}, &mockBrandingRepo{})
+		_, err := svc.GetHostedPage(context.Background(), "web", "idp")
+		require.Error(t, err)
+	})
+
+	t.Run("falls back to built-in style", func(t *testing.T) {
+		svc := NewLoginTemplateService(&mockLoginTemplateRepo{},
+			&mockClientRepo{findByClientIDAndIdentityProviderFn: activeClient(`{}`)}, &mockBrandingRepo{})
+		res, err := svc.GetHostedPage(context.Background(), "web", "idp")
+		require.NoError(t, err)
+		assert.Equal(t, model.LoginTemplateModern, res.Template)
+		assert.Equal(t, "Web App", res.ClientName)
+		assert.Empty(t, res.Metadata)
+		assert.Nil(t, res.Branding)
+	})
+
+	t.Run("uses tenant default and branding", func(t *testing.T) {
+		svc := NewLoginTemplateService(
+			&mockLoginTemplateRepo{findDefaultByTenantIDFn: defaultTemplate},
+			&mockClientRepo{findByClientIDAndIdentityProviderFn: activeClient(`{}`)},
+			&mockBrandingRepo{findByTenantIDFn: func(int64) (*model.Branding, error) {
+				return &model.Branding{CompanyName: "Acme", PrimaryColor: "#ff0000"}, nil
+			}},
+		)
+		res, err := svc.GetHostedPage(context.Background(), "web", "idp")
+		require.NoError(t, err)
+		assert.Equal(t, model.LoginTemplateClassic, res.Template)
+		assert.Equal(t, "Default", res.Metadata["title"])
+		require.NotNil(t, res.Branding)
+		assert.Equal(t, "Acme", res.Branding.CompanyName)
+	})
+
+	t.Run("uses template pinned in client config", func(t *testing.T) {
+		svc := NewLoginTemplateService(
+			&mockLoginTemplateRepo{
+				findByUUIDAndTenantIDFn: func(id uuid.UUID, tID int64, _ ...string) (*model.LoginTemplate, error) {
+					assert.Equal(t, pinned, id)
+					assert.Equal(t, int64(7), tID)
+					return &model.LoginTemplate{Template: model.LoginTemplateCreative, Status: model.StatusActive}, nil
+				},
+				findDefaultByTenantIDFn: defaultTemplate,
+			},
+			&mockClientRepo{findByClientIDAndIdentityProviderFn: activeClient(`{"login_template_id":"` + pinned.String() + `"}`)},
+			&mockBrandingRepo{},
+		)
+		res, err := svc.GetHostedPage(context.Background(), "web", "idp")
+		require.NoError(t, err)
+		assert.Equal(t, model.LoginTemplateCreative, res.Template)
+	})
+
+	t.Run("inactive pinned template falls back to default", func(t *testing.T) {
+		svc := NewLoginTemplateService(
+			&mockLoginTemplateRepo{
+				findByUUIDAndTenantIDFn: func(_ uuid.UUID, _ int64, _ ...string) (*model.LoginTemplate, error) {
+					return &model.LoginTemplate{Template: model.LoginTemplateCreative, Status: model.StatusInactive}, nil
+				},
+				findDefaultByTenantIDFn: defaultTemplate,
+			},
+			&mockClientRepo{findByClientIDAndIdentityProviderFn: activeClient(`{"login_template_id":"` + pinned.String() + `"}`)},
+			&mockBrandingRepo{},
+		)
+		res, err := svc.GetHostedPage(context.Background(), "web", "idp")
+		require.NoError(t, err)
+		assert.Equal(t, model.LoginTemplateClassic, res.Template)
+	})
+
+	t.Run("branding error", func(t *testing.T) {
+		svc := NewLoginTemplateService(&mockLoginTemplateRepo{},
+			&mockClientRepo{findByClientIDAndIdentityProviderFn: activeClient(`{}`)},
+			&mockBrandingRepo{findByTenantIDFn: func(int64) (*model.Branding, error) { return nil, errors.New("db error") }})
+		_, err := svc.GetHostedPage(context.Background(), "web", "idp")
+		require.Error(t, err)
+	})
+}
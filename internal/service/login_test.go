@@ -43,6 +43,13 @@ type mockClientRepo struct {
 	createOrUpdateFn                    func(*model.Client) (*model.Client, error)
 	deleteByUUIDFn                      func(any) error
 	findByIDFn                          func(any, ...string) (*model.Client, error)
+	findAllByTenantIDFn                 func(tID int64) ([]model.Client, error)
+	recordDeprecatedUsageFn             func(cID int64) error
+	unsetDefaultByTenantIDFn            func(tID int64) error
+	setStatusByTenantIDFn               func(tID int64, s string) error
+	findActiveByIdentifierFn            func(string) (*model.Client, error)
+	findByIDsWithURIsFn                 func([]int64) ([]model.Client, error)
+	countByTenantIDFn                   func(tID int64) (int64, error)
 }
 
 func (m *mockClientRepo) WithTx(_ *gorm.DB) repository.ClientRepository { return m }
@@ -52,6 +59,12 @@ func (m *mockClientRepo) FindByClientIDAndIdentityProvider(a, b string) (*model.
 	}
 	return nil, nil
 }
+func (m *mockClientRepo) CountByTenantID(tID int64) (int64, error) {
+	if m.countByTenantIDFn != nil {
+		return m.countByTenantIDFn(tID)
+	}
+	return 0, nil
+}
 func (m *mockClientRepo) FindSystem() (*model.Client, error) {
 	if m.findSystemFn != nil {
 		return m.findSystemFn()
@@ -114,7 +127,12 @@ func (m *mockClientRepo) FindByNameAndTenantID(n string, tID int64) (*model.Clie
 func (m *mockClientRepo) FindByClientID(cID string, tID int64) (*model.Client, error) {
 	return nil, nil
 }
-func (m *mockClientRepo) FindAllByTenantID(tID int64) ([]model.Client, error) { return nil, nil }
+func (m *mockClientRepo) FindAllByTenantID(tID int64) ([]model.Client, error) {
+	if m.findAllByTenantIDFn != nil {
+		return m.findAllByTenantIDFn(tID)
+	}
+	return nil, nil
+}
 func (m *mockClientRepo) FindDefaultByTenantID(tID int64) (*model.Client, error) {
 	if m.findDefaultByTenantIDFn != nil {
 		return m.findDefaultByTenantIDFn(tID)
@@ -129,26 +147,108 @@ func (m *mockClientRepo) FindPaginated(f repository.ClientRepositoryGetFilter) (
 }
 func (m *mockClientRepo) SetStatusByUUID(id uuid.UUID, tID int64, s string) error { return nil }
 func (m *mockClientRepo) DeleteByUUIDAndTenantID(id uuid.UUID, tID int64) error   { return nil }
+func (m *mockClientRepo) SetStatusByTenantID(tID int64, s string) error {
+	if m.setStatusByTenantIDFn != nil {
+		return m.setStatusByTenantIDFn(tID, s)
+	}
+	return nil
+}
+func (m *mockClientRepo) UnsetDefaultByTenantID(tID int64) error {
+	if m.unsetDefaultByTenantIDFn != nil {
+		return m.unsetDefaultByTenantIDFn(tID)
+	}
+	return nil
+}
+func (m *mockClientRepo) RecordDeprecatedUsage(cID int64) error {
+	if m.recordDeprecatedUsageFn != nil {
+		return m.recordDeprecatedUsageFn(cID)
+	}
+	return nil
+}
+func (m *mockClientRepo) FindActiveByIdentifier(identifier string) (*model.Client, error) {
+	if m.findActiveByIdentifierFn != nil {
+		return m.findActiveByIdentifierFn(identifier)
+	}
+	return nil, nil
+}
+func (m *mockClientRepo) FindByIDsWithURIs(ids []int64) ([]model.Client, error) {
+	if m.findByIDsWithURIsFn != nil {
+		return m.findByIDsWithURIsFn(ids)
+	}
+	return nil, nil
+}
 
 // ---------------------------------------------------------------------------
 // Mock: UserRepository
 // ---------------------------------------------------------------------------
 
 type mockUserRepo struct {
-	findByUsernameFn         func(username string) (*model.User, error)
-	findByEmailFn            func(email string) (*model.User, error)
-	findByEmailAndTenantIDFn func(email string, tenantID int64) (*model.User, error)
-	findByUUIDFn             func(id any, preloads ...string) (*model.User, error)
-	findByIDFn               func(id any, preloads ...string) (*model.User, error)
-	findSuperAdminFn         func() (*model.User, error)
-	findPaginatedFn          func(repository.UserRepositoryGetFilter) (*repository.PaginationResult[model.User], error)
-	createFn                 func(*model.User) (*model.User, error)
-	updateByUUIDFn           func(id, data any) (*model.User, error)
-	updateByIDFn             func(id, data any) (*model.User, error)
-	findRolesFn              func(userID int64) ([]model.Role, error)
-	findByPhoneFn            func(phone string) (*model.User, error)
-	setStatusFn              func(id uuid.UUID, s string) error
-	deleteByUUIDFn           func(id any) error
+	findByUsernameFn          func(username string) (*model.User, error)
+	findByEmailFn             func(email string) (*model.User, error)
+	findByEmailAndTenantIDFn  func(email string, tenantID int64) (*model.User, error)
+	findByUUIDFn              func(id any, preloads ...string) (*model.User, error)
+	findByUUIDsFn             func(ids []string, preloads ...string) ([]model.User, error)
+	findByIDFn                func(id any, preloads ...string) (*model.User, error)
+	findSuperAdminFn          func() (*model.User, error)
+	findPaginatedFn           func(repository.UserRepositoryGetFilter) (*repository.PaginationResult[model.User], error)
+	searchFn                  func(string, repository.UserRepositoryGetFilter) (*repository.PaginationResult[model.User], error)
+	findCursorPaginatedFn     func(f repository.UserRepositoryGetFilter, page repository.CursorPage) (*repository.CursorPaginationResult[model.User], error)
+	findFilteredAfterIDFn     func(f repository.UserRepositoryGetFilter, afterID int64, limit int) ([]model.User, error)
+	createFn                  func(*model.User) (*model.User, error)
+	updateByUUIDFn            func(id, data any) (*model.User, error)
+	updateByIDFn              func(id, data any) (*model.User, error)
+	findRolesFn               func(userID int64) ([]model.Role, error)
+	findGroupRolesFn          func(userID int64) ([]model.Role, error)
+	findPermissionGrantsFn    func(userID int64) ([]repository.PermissionGrant, error)
+	findByPhoneFn             func(phone string) (*model.User, error)
+	setStatusFn               func(id uuid.UUID, s string) error
+	deleteByUUIDFn            func(id any) error
+	softDeleteFn              func(id uuid.UUID, at time.Time) error
+	restoreFn                 func(id uuid.UUID, s string) error
+	findDeletedBeforeFn       func(cutoff time.Time, afterID int64, limit int) ([]model.User, error)
+	findBySubAndClientIDFn    func(sub, clientID string) (*model.User, error)
+	findByUsernamesOrEmailsFn func(usernames, emails []string) ([]model.User, error)
+	createBatchFn             func(users []model.User) error
+	deleteExclusiveToTenantFn func(tenantID int64) (int64, error)
+}
+
+func (m *mockUserRepo) SoftDelete(id uuid.UUID, at time.Time) error {
+	if m.softDeleteFn != nil {
+		return m.softDeleteFn(id, at)
+	}
+	return nil
+}
+func (m *mockUserRepo) Restore(id uuid.UUID, s string) error {
+	if m.restoreFn != nil {
+		return m.restoreFn(id, s)
+	}
+	return nil
+}
+func (m *mockUserRepo) FindDeletedBefore(cutoff time.Time, afterID int64, limit int) ([]model.User, error) {
+	if m.findDeletedBeforeFn != nil {
+		return m.findDeletedBeforeFn(cutoff, afterID, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockUserRepo) FindByUsernamesOrEmails(usernames, emails []string) ([]model.User, error) {
+	if m.findByUsernamesOrEmailsFn != nil {
+		return m.findByUsernamesOrEmailsFn(usernames, emails)
+	}
+	return nil, nil
+}
+func (m *mockUserRepo) CreateBatch(users []model.User) error {
+	if m.createBatchFn != nil {
+		return m.createBatchFn(users)
+	}
+	return nil
+}
+
+func (m *mockUserRepo) DeleteExclusiveToTenant(tenantID int64) (int64, error) {
+	if m.deleteExclusiveToTenantFn != nil {
+		return m.deleteExclusiveToTenantFn(tenantID)
+	}
+	return 0, nil
 }
 
 func (m *mockUserRepo) WithTx(_ *gorm.DB) repository.UserRepository { return m }
@@ -184,7 +284,12 @@ func (m *mockUserRepo) FindByUUID(id any, p ...string) (*model.User, error) {
 	}
 	return nil, nil
 }
-func (m *mockUserRepo) FindByUUIDs(ids []string, p ...string) ([]model.User, error) { return nil, nil }
+func (m *mockUserRepo) FindByUUIDs(ids []string, p ...string) ([]model.User, error) {
+	if m.findByUUIDsFn != nil {
+		return m.findByUUIDsFn(ids, p...)
+	}
+	return nil, nil
+}
 func (m *mockUserRepo) FindByID(id any, p ...string) (*model.User, error) {
 	if m.findByIDFn != nil {
 		return m.findByIDFn(id, p...)
@@ -231,13 +336,48 @@ func (m *mockUserRepo) FindRoles(userID int64) ([]model.Role, error) {
 	}
 	return nil, nil
 }
-func (m *mockUserRepo) FindBySubAndClientID(sub, cID string) (*model.User, error) { return nil, nil }
+func (m *mockUserRepo) FindGroupRoles(userID int64, _ ...string) ([]model.Role, error) {
+	if m.findGroupRolesFn != nil {
+		return m.findGroupRolesFn(userID)
+	}
+	return nil, nil
+}
+func (m *mockUserRepo) FindPermissionGrants(userID int64) ([]repository.PermissionGrant, error) {
+	if m.findPermissionGrantsFn != nil {
+		return m.findPermissionGrantsFn(userID)
+	}
+	return nil, nil
+}
+func (m *mockUserRepo) FindBySubAndClientID(sub, cID string) (*model.User, error) {
+	if m.findBySubAndClientIDFn != nil {
+		return m.findBySubAndClientIDFn(sub, cID)
+	}
+	return nil, nil
+}
 func (m *mockUserRepo) FindPaginated(f repository.UserRepositoryGetFilter) (*repository.PaginationResult[model.User], error) {
 	if m.findPaginatedFn != nil {
 		return m.findPaginatedFn(f)
 	}
 	return &repository.PaginationResult[model.User]{}, nil
 }
+func (m *mockUserRepo) Search(term string, f repository.UserRepositoryGetFilter) (*repository.PaginationResult[model.User], error) {
+	if m.searchFn != nil {
+		return m.searchFn(term, f)
+	}
+	return &repository.PaginationResult[model.User]{}, nil
+}
+func (m *mockUserRepo) FindCursorPaginated(f repository.UserRepositoryGetFilter, page repository.CursorPage) (*repository.CursorPaginationResult[model.User], error) {
+	if m.findCursorPaginatedFn != nil {
+		return m.findCursorPaginatedFn(f, page)
+	}
+	return &repository.CursorPaginationResult[model.User]{}, nil
+}
+func (m *mockUserRepo) FindFilteredAfterID(f repository.UserRepositoryGetFilter, afterID int64, limit int) ([]model.User, error) {
+	if m.findFilteredAfterIDFn != nil {
+		return m.findFilteredAfterIDFn(f, afterID, limit)
+	}
+	return nil, nil
+}
 func (m *mockUserRepo) SetEmailVerified(id uuid.UUID, v bool) error { return nil }
 func (m *mockUserRepo) SetStatus(id uuid.UUID, s string) error {
 	if m.setStatusFn != nil {
@@ -253,7 +393,13 @@ func (m *mockUserRepo) SetStatus(id uuid.UUID, s string) error {
 type mockUserIdentityRepo struct {
 	findByUserIDAndClientIDFn func(userID, clientID int64) (*model.UserIdentity, error)
 	createFn                  func(*model.UserIdentity) (*model.UserIdentity, error)
-	findByUserIDFn            func(int64) ([]model.UserIdentity, error)
+	findByUserIDFn            func(int64, ...string) ([]model.UserIdentity, error)
+	findPaginatedFn           func(repository.UserIdentityRepositoryGetFilter) (*repository.PaginationResult[model.UserIdentity], error)
+	createBatchFn             func([]model.UserIdentity) error
+	findByUUIDAndUserIDFn     func(string, int64) (*model.UserIdentity, error)
+	findByClientIDAndSubFn    func(int64, string) (*model.UserIdentity, error)
+	deleteByIDFn              func(any) error
+	countUsersByTenantIDFn    func(int64) (int64, error)
 }
 
 func (m *mockUserIdentityRepo) WithTx(_ *gorm.DB) repository.UserIdentityRepository { return m }
@@ -284,13 +430,42 @@ func (m *mockUserIdentityRepo) UpdateByUUID(id, data any) (*model.UserIdentity,
 }
 func (m *mockUserIdentityRepo) UpdateByID(id, data any) (*model.UserIdentity, error) { return nil, nil }
 func (m *mockUserIdentityRepo) DeleteByUUID(id any) error                            { return nil }
-func (m *mockUserIdentityRepo) DeleteByID(id any) error                              { return nil }
+func (m *mockUserIdentityRepo) DeleteByID(id any) error {
+	if m.deleteByIDFn != nil {
+		return m.deleteByIDFn(id)
+	}
+	return nil
+}
 func (m *mockUserIdentityRepo) Paginate(c map[string]any, pg, lim int, p ...string) (*repository.PaginationResult[model.UserIdentity], error) {
 	return nil, nil
 }
-func (m *mockUserIdentityRepo) FindByUserID(uID int64) ([]model.UserIdentity, error) {
+func (m *mockUserIdentityRepo) FindByUserID(uID int64, p ...string) ([]model.UserIdentity, error) {
 	if m.findByUserIDFn != nil {
-		return m.findByUserIDFn(uID)
+		return m.findByUserIDFn(uID, p...)
+	}
+	return nil, nil
+}
+func (m *mockUserIdentityRepo) CountUsersByTenantID(tID int64) (int64, error) {
+	if m.countUsersByTenantIDFn != nil {
+		return m.countUsersByTenantIDFn(tID)
+	}
+	return 0, nil
+}
+func (m *mockUserIdentityRepo) FindPaginated(f repository.UserIdentityRepositoryGetFilter) (*repository.PaginationResult[model.UserIdentity], error) {
+	if m.findPaginatedFn != nil {
+		return m.findPaginatedFn(f)
+	}
+	return &repository.PaginationResult[model.UserIdentity]{}, nil
+}
+func (m *mockUserIdentityRepo) FindByUUIDAndUserID(id string, uID int64) (*model.UserIdentity, error) {
+	if m.findByUUIDAndUserIDFn != nil {
+		return m.findByUUIDAndUserIDFn(id, uID)
+	}
+	return nil, nil
+}
+func (m *mockUserIdentityRepo) FindByClientIDAndSub(cID int64, sub string) (*model.UserIdentity, error) {
+	if m.findByClientIDAndSubFn != nil {
+		return m.findByClientIDAndSubFn(cID, sub)
 	}
 	return nil, nil
 }
@@ -299,18 +474,26 @@ func (m *mockUserIdentityRepo) FindByProviderAndUserID(prov, pUID string) (*mode
 }
 func (m *mockUserIdentityRepo) FindByEmail(e string) ([]model.UserIdentity, error) { return nil, nil }
 func (m *mockUserIdentityRepo) DeleteByUserID(uID int64) error                     { return nil }
+func (m *mockUserIdentityRepo) CreateBatch(ids []model.UserIdentity) error {
+	if m.createBatchFn != nil {
+		return m.createBatchFn(ids)
+	}
+	return nil
+}
 
 // ---------------------------------------------------------------------------
 // Mock: IdentityProviderRepository
 // ---------------------------------------------------------------------------
 
 type mockIdentityProviderRepo struct {
-	findByIdentifierFn func(identifier string) (*model.IdentityProvider, error)
-	findByUUIDFn       func(id any, preloads ...string) (*model.IdentityProvider, error)
-	findByNameFn       func(name string, tenantID int64) (*model.IdentityProvider, error)
-	findPaginatedFn    func(repository.IdentityProviderRepositoryGetFilter) (*repository.PaginationResult[model.IdentityProvider], error)
-	createOrUpdateFn   func(*model.IdentityProvider) (*model.IdentityProvider, error)
-	deleteByUUIDFn     func(id any) error
+	findByIdentifierFn      func(identifier string) (*model.IdentityProvider, error)
+	findByUUIDFn            func(id any, preloads ...string) (*model.IdentityProvider, error)
+	findByNameFn            func(name string, tenantID int64) (*model.IdentityProvider, error)
+	findPaginatedFn         func(repository.IdentityProviderRepositoryGetFilter) (*repository.PaginationResult[model.IdentityProvider], error)
+	createOrUpdateFn        func(*model.IdentityProvider) (*model.IdentityProvider, error)
+	deleteByUUIDFn          func(id any) error
+	findDefaultByTenantIDFn func(tenantID int64) (*model.IdentityProvider, error)
+	findActiveByTenantIDFn  func(tenantID int64) ([]model.IdentityProvider, error)
 }
 
 func (m *mockIdentityProviderRepo) WithTx(_ *gorm.DB) repository.IdentityProviderRepository { return m }
@@ -367,6 +550,15 @@ func (m *mockIdentityProviderRepo) FindByName(n string, tID int64) (*model.Ident
 	return nil, nil
 }
 func (m *mockIdentityProviderRepo) FindDefaultByTenantID(tID int64) (*model.IdentityProvider, error) {
+	if m.findDefaultByTenantIDFn != nil {
+		return m.findDefaultByTenantIDFn(tID)
+	}
+	return nil, nil
+}
+func (m *mockIdentityProviderRepo) FindActiveByTenantID(tID int64) ([]model.IdentityProvider, error) {
+	if m.findActiveByTenantIDFn != nil {
+		return m.findActiveByTenantIDFn(tID)
+	}
 	return nil, nil
 }
 func (m *mockIdentityProviderRepo) FindPaginated(f repository.IdentityProviderRepositoryGetFilter) (*repository.PaginationResult[model.IdentityProvider], error) {
@@ -383,7 +575,10 @@ func (m *mockIdentityProviderRepo) FindPaginated(f repository.IdentityProviderRe
 type mockUserTokenRepo struct {
 	createFn                   func(*model.UserToken) (*model.UserToken, error)
 	findByUserIDAndTokenTypeFn func(userID int64, tokenType string) ([]model.UserToken, error)
+	findActiveByTokenFn        func(token, tokenType string) (*model.UserToken, error)
+	findUnrevokedByUserIDsFn   func(userIDs []int64, tokenType string) ([]model.UserToken, error)
 	revokeByUUIDFn             func(id uuid.UUID) error
+	revokeAllByUserIDFn        func(userID int64) error
 }
 
 func (m *mockUserTokenRepo) WithTx(_ *gorm.DB) repository.UserTokenRepository { return m }
@@ -421,13 +616,30 @@ func (m *mockUserTokenRepo) FindByUserIDAndTokenType(uID int64, tt string) ([]mo
 	}
 	return nil, nil
 }
+func (m *mockUserTokenRepo) FindActiveByTokenAndType(token, tt string) (*model.UserToken, error) {
+	if m.findActiveByTokenFn != nil {
+		return m.findActiveByTokenFn(token, tt)
+	}
+	return nil, nil
+}
+func (m *mockUserTokenRepo) FindUnrevokedByUserIDsAndTokenType(uIDs []int64, tt string) ([]model.UserToken, error) {
+	if m.findUnrevokedByUserIDsFn != nil {
+		return m.findUnrevokedByUserIDsFn(uIDs, tt)
+	}
+	return nil, nil
+}
 func (m *mockUserTokenRepo) RevokeByUUID(id uuid.UUID) error {
 	if m.revokeByUUIDFn != nil {
 		return m.revokeByUUIDFn(id)
 	}
 	return nil
 }
-func (m *mockUserTokenRepo) RevokeAllByUserID(uID int64) error          { return nil }
+func (m *mockUserTokenRepo) RevokeAllByUserID(uID int64) error {
+	if m.revokeAllByUserIDFn != nil {
+		return m.revokeAllByUserIDFn(uID)
+	}
+	return nil
+}
 func (m *mockUserTokenRepo) DeleteByUserID(uID int64) error             { return nil }
 func (m *mockUserTokenRepo) DeleteExpiredTokens(before time.Time) error { return nil }
 
@@ -454,7 +666,7 @@ func strPtr(s string) *string { return &s }
 
 // newMockGormDB creates a *gorm.DB backed by sqlmock so service tests can
 // verify BEGIN / COMMIT / ROLLBACK without a real database.
-func newMockGormDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+func newMockGormDB(t testing.TB) (*gorm.DB, sqlmock.Sqlmock) {
 	t.Helper()
 	sqlDB, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -478,7 +690,7 @@ func buildActiveIdentityProvider() *model.IdentityProvider {
 }
 
 // buildActiveClient returns a minimal active client whose Domain and Identifier
-// are both populated (required by generateTokenResponse).
+// are both populated (required by generateLoginTokenResponse).
 func buildActiveClient() *model.Client {
 	idp := buildActiveIdentityProvider()
 	return &model.Client{
@@ -747,8 +959,8 @@ func TestLoginPublic(t *testing.T) {
 			}
 			tc.setup(t, repos)
 
-			svc := NewLoginService(gormDB, repos.clientRepo, repos.userRepo, &mockUserTokenRepo{}, repos.userIdentity, repos.idpRepo, &mockAuthEventService{})
-			resp, err := svc.LoginPublic(context.Background(), tc.username, tc.password, tc.clientID, tc.providerID)
+			svc := NewLoginService(gormDB, repos.clientRepo, repos.userRepo, &mockUserTokenRepo{}, repos.userIdentity, repos.idpRepo, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockAuthEventService{}, nil, nil, nil, nil, nil)
+			resp, err := svc.LoginPublic(context.Background(), tc.username, tc.password, tc.clientID, tc.providerID, nil)
 
 			if tc.wantErr {
 				require.Error(t, err)
@@ -920,8 +1132,8 @@ func TestLogin(t *testing.T) {
 			}
 			tc.setup(t, repos)
 
-			svc := NewLoginService(gormDB, repos.clientRepo, repos.userRepo, &mockUserTokenRepo{}, repos.userIdentity, &mockIdentityProviderRepo{}, &mockAuthEventService{})
-			resp, err := svc.Login(context.Background(), tc.username, tc.password, tc.clientID, tc.providerID)
+			svc := NewLoginService(gormDB, repos.clientRepo, repos.userRepo, &mockUserTokenRepo{}, repos.userIdentity, &mockIdentityProviderRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockAuthEventService{}, nil, nil, nil, nil, nil)
+			resp, err := svc.Login(context.Background(), tc.username, tc.password, tc.clientID, tc.providerID, nil)
 
 			if tc.wantErr {
 				require.Error(t, err)
@@ -936,6 +1148,14 @@ func TestLogin(t *testing.T) {
 				assert.NotEmpty(t, resp.IDToken)
 				assert.NotEmpty(t, resp.RefreshToken)
 				assert.Equal(t, "Bearer", resp.TokenType)
+
+				// Access and ID token name the same new session.
+				accessClaims, err := jwt.ValidateToken(resp.AccessToken)
+				require.NoError(t, err)
+				idClaims, err := jwt.ValidateToken(resp.IDToken)
+				require.NoError(t, err)
+				assert.NotEmpty(t, accessClaims["sid"])
+				assert.Equal(t, accessClaims["sid"], idClaims["sid"])
 			}
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
@@ -980,8 +1200,8 @@ func TestLoginPublic_RateLimited(t *testing.T) {
 
 	svc := NewLoginService(gormDB, &mockClientRepo{}, &mockUserRepo{}, &mockUserTokenRepo{},
 		&mockUserIdentityRepo{findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) { return nil, nil }},
-		&mockIdentityProviderRepo{}, &mockAuthEventService{})
-	_, err := svc.LoginPublic(context.Background(), username, "pass", "c1", "p1")
+		&mockIdentityProviderRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockAuthEventService{}, nil, nil, nil, nil, nil)
+	_, err := svc.LoginPublic(context.Background(), username, "pass", "c1", "p1", nil)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "locked")
 }
@@ -1005,8 +1225,8 @@ func TestLoginPublic_ClientLookupError(t *testing.T) {
 
 	svc := NewLoginService(gormDB, clientRepo, &mockUserRepo{}, &mockUserTokenRepo{},
 		&mockUserIdentityRepo{findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) { return nil, nil }},
-		idpRepo, &mockAuthEventService{})
-	_, err := svc.LoginPublic(context.Background(), "pub-client-err", "pass", "c1", "p1")
+		idpRepo, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockAuthEventService{}, nil, nil, nil, nil, nil)
+	_, err := svc.LoginPublic(context.Background(), "pub-client-err", "pass", "c1", "p1", nil)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "authentication failed")
 }
@@ -1035,8 +1255,8 @@ func TestLoginPublic_UserNotFound(t *testing.T) {
 
 	svc := NewLoginService(gormDB, clientRepo, userRepo, &mockUserTokenRepo{},
 		&mockUserIdentityRepo{findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) { return nil, nil }},
-		idpRepo, &mockAuthEventService{})
-	_, err := svc.LoginPublic(context.Background(), "pub-user-missing", "pass", "c1", "p1")
+		idpRepo, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockAuthEventService{}, nil, nil, nil, nil, nil)
+	_, err := svc.LoginPublic(context.Background(), "pub-user-missing", "pass", "c1", "p1", nil)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid credentials")
 }
@@ -1055,8 +1275,8 @@ func TestLogin_RateLimited(t *testing.T) {
 
 	svc := NewLoginService(gormDB, &mockClientRepo{}, &mockUserRepo{}, &mockUserTokenRepo{},
 		&mockUserIdentityRepo{findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) { return nil, nil }},
-		&mockIdentityProviderRepo{}, &mockAuthEventService{})
-	_, err := svc.Login(context.Background(), username, "pass", nil, nil)
+		&mockIdentityProviderRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockAuthEventService{}, nil, nil, nil, nil, nil)
+	_, err := svc.Login(context.Background(), username, "pass", nil, nil, nil)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "locked")
 }
@@ -1077,8 +1297,8 @@ func TestLogin_ExplicitClientLookupError(t *testing.T) {
 	pID := "provider-x"
 	svc := NewLoginService(gormDB, clientRepo, &mockUserRepo{}, &mockUserTokenRepo{},
 		&mockUserIdentityRepo{findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) { return nil, nil }},
-		&mockIdentityProviderRepo{}, &mockAuthEventService{})
-	_, err := svc.Login(context.Background(), "int-explicit-err", "pass", &cID, &pID)
+		&mockIdentityProviderRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockAuthEventService{}, nil, nil, nil, nil, nil)
+	_, err := svc.Login(context.Background(), "int-explicit-err", "pass", &cID, &pID, nil)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "authentication failed")
 }
@@ -1098,8 +1318,8 @@ func TestLogin_UserNotFound(t *testing.T) {
 
 	svc := NewLoginService(gormDB, clientRepo, userRepo, &mockUserTokenRepo{},
 		&mockUserIdentityRepo{findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) { return nil, nil }},
-		&mockIdentityProviderRepo{}, &mockAuthEventService{})
-	_, err := svc.Login(context.Background(), "int-user-missing", "pass", nil, nil)
+		&mockIdentityProviderRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockAuthEventService{}, nil, nil, nil, nil, nil)
+	_, err := svc.Login(context.Background(), "int-user-missing", "pass", nil, nil, nil)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid credentials")
 }
@@ -1140,8 +1360,8 @@ func TestLoginPublic_GenerateAccessTokenError(t *testing.T) {
 		},
 	}
 
-	svc := NewLoginService(gormDB, clientRepo, userRepo, &mockUserTokenRepo{}, userIdentityRepo, idpRepo, &mockAuthEventService{})
-	_, err := svc.LoginPublic(context.Background(), "pub-token-err", correctPassword, "c1", "p1")
+	svc := NewLoginService(gormDB, clientRepo, userRepo, &mockUserTokenRepo{}, userIdentityRepo, idpRepo, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockAuthEventService{}, nil, nil, nil, nil, nil)
+	_, err := svc.LoginPublic(context.Background(), "pub-token-err", correctPassword, "c1", "p1", nil)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "private key not initialized")
 }
@@ -1171,8 +1391,8 @@ func TestLogin_GenerateAccessTokenError(t *testing.T) {
 		},
 	}
 
-	svc := NewLoginService(gormDB, clientRepo, userRepo, &mockUserTokenRepo{}, userIdentityRepo, &mockIdentityProviderRepo{}, &mockAuthEventService{})
-	_, err := svc.Login(context.Background(), "int-token-err", correctPassword, nil, nil)
+	svc := NewLoginService(gormDB, clientRepo, userRepo, &mockUserTokenRepo{}, userIdentityRepo, &mockIdentityProviderRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockAuthEventService{}, nil, nil, nil, nil, nil)
+	_, err := svc.Login(context.Background(), "int-token-err", correctPassword, nil, nil, nil)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "private key not initialized")
 }
@@ -1187,7 +1407,7 @@ func TestLoginPublic_GenerateIDTokenError(t *testing.T) {
 
 	// Stub generateIDTokenFn to return an error
 	orig := generateIDTokenFn
-	generateIDTokenFn = func(string, string, string, string, *jwt.UserProfile, string) (string, error) {
+	generateIDTokenFn = func(string, string, string, string, *jwt.UserProfile, string, map[string]any) (string, error) {
 		return "", errors.New("id token error")
 	}
 	defer func() { generateIDTokenFn = orig }()
@@ -1213,8 +1433,8 @@ func TestLoginPublic_GenerateIDTokenError(t *testing.T) {
 		},
 	}
 
-	svc := NewLoginService(gormDB, clientRepo, userRepo, &mockUserTokenRepo{}, userIdentityRepo, idpRepo, &mockAuthEventService{})
-	_, err := svc.LoginPublic(context.Background(), "pub-idtoken-err", correctPassword, "c1", "p1")
+	svc := NewLoginService(gormDB, clientRepo, userRepo, &mockUserTokenRepo{}, userIdentityRepo, idpRepo, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockAuthEventService{}, nil, nil, nil, nil, nil)
+	_, err := svc.LoginPublic(context.Background(), "pub-idtoken-err", correctPassword, "c1", "p1", nil)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "id token error")
 }
@@ -1255,8 +1475,8 @@ func TestLoginPublic_GenerateRefreshTokenError(t *testing.T) {
 		},
 	}
 
-	svc := NewLoginService(gormDB, clientRepo, userRepo, &mockUserTokenRepo{}, userIdentityRepo, idpRepo, &mockAuthEventService{})
-	_, err := svc.LoginPublic(context.Background(), "pub-refresh-err", correctPassword, "c1", "p1")
+	svc := NewLoginService(gormDB, clientRepo, userRepo, &mockUserTokenRepo{}, userIdentityRepo, idpRepo, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockAuthEventService{}, nil, nil, nil, nil, nil)
+	_, err := svc.LoginPublic(context.Background(), "pub-refresh-err", correctPassword, "c1", "p1", nil)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "refresh token error")
 }
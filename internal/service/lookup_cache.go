@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/eventbus"
+	"github.com/maintainerd/auth/internal/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// ClientLookupEventTypes are the domain events after which cached client
+// lookups are dropped. The cache invalidation plugin already drops them when
+// the row is written; the events, delivered after commit, also catch a
+// lookup that cached the old row while the transaction was still open.
+var ClientLookupEventTypes = []string{
+	model.EventTypeClientUpdated,
+	model.EventTypeClientDeleted,
+}
+
+// NewClientLookupInvalidator returns an event handler for
+// ClientLookupEventTypes that drops every cached client lookup. A lookup can
+// be keyed by any of a client's identifiers, so all of them go.
+func NewClientLookupInvalidator(store cache.ClientLookupStore) eventbus.Handler {
+	return func(ctx context.Context, e eventbus.Event) error {
+		ctx, span := otel.Tracer("service").Start(ctx, "clientLookup.handleEvent")
+		defer span.End()
+		span.SetAttributes(attribute.String("event.type", e.Type))
+
+		store.InvalidateAllClientLookups(ctx)
+		span.SetStatus(codes.Ok, "")
+		return nil
+	}
+}
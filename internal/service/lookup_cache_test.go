@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/eventbus"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClientLookupStore struct {
+	invalidatedAll int
+}
+
+func (f *fakeClientLookupStore) GetClientLookup(context.Context, string, string) *model.Client {
+	return nil
+}
+
+func (f *fakeClientLookupStore) SetClientLookup(context.Context, string, string, *model.Client) {}
+
+func (f *fakeClientLookupStore) InvalidateClientLookups(context.Context, string) {}
+
+func (f *fakeClientLookupStore) InvalidateAllClientLookups(context.Context) {
+	f.invalidatedAll++
+}
+
+func TestClientLookupInvalidator(t *testing.T) {
+	store := &fakeClientLookupStore{}
+	handle := NewClientLookupInvalidator(store)
+
+	for _, eventType := range ClientLookupEventTypes {
+		require.NoError(t, handle(context.Background(), eventbus.Event{Type: eventType}))
+	}
+
+	assert.Equal(t, len(ClientLookupEventTypes), store.invalidatedAll)
+	assert.Contains(t, ClientLookupEventTypes, model.EventTypeClientUpdated)
+	assert.Contains(t, ClientLookupEventTypes, model.EventTypeClientDeleted)
+}
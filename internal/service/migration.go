@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/runner"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+// MigrationState is a known schema migration. AppliedAt is nil while it is
+// pending.
+type MigrationState struct {
+	Version   string
+	AppliedAt *time.Time
+}
+
+// MigrationStatusResult lists every known migration in order.
+type MigrationStatusResult struct {
+	Migrations []MigrationState
+	Pending    int
+}
+
+// MigrationService reports and applies the schema migrations. Reverting
+// migrations is left to the migrate command of the server binary.
+type MigrationService interface {
+	// GetStatus lists every known migration with the time it was applied.
+	GetStatus(ctx context.Context) (*MigrationStatusResult, error)
+	// Apply applies every pending migration and returns their versions.
+	Apply(ctx context.Context) ([]string, error)
+}
+
+type migrationService struct {
+	db *gorm.DB
+}
+
+// NewMigrationService creates a new MigrationService.
+func NewMigrationService(db *gorm.DB) MigrationService {
+	return &migrationService{db: db}
+}
+
+func (s *migrationService) GetStatus(ctx context.Context) (*MigrationStatusResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "migration.getStatus")
+	defer span.End()
+
+	states, err := runner.MigrationStatus(s.db)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "read migrations failed")
+		return nil, apperror.NewInternal("failed to read migrations", err)
+	}
+
+	result := &MigrationStatusResult{Migrations: make([]MigrationState, len(states))}
+	for i, state := range states {
+		result.Migrations[i] = MigrationState{Version: state.Version, AppliedAt: state.AppliedAt}
+		if state.AppliedAt == nil {
+			result.Pending++
+		}
+	}
+
+	span.SetAttributes(attribute.Int("migrations.pending", result.Pending))
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+func (s *migrationService) Apply(ctx context.Context) ([]string, error) {
+	_, span := otel.Tracer("service").Start(ctx, "migration.apply")
+	defer span.End()
+
+	applied, err := runner.ApplyMigrations(s.db)
+	span.SetAttributes(attribute.Int("migrations.applied", len(applied)))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "apply migrations failed")
+		return nil, apperror.NewInternal("failed to apply migrations", err)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return applied, nil
+}
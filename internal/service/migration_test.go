@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/runner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestMigrationService_GetStatus(t *testing.T) {
+	orig := runner.MigrationStatus
+	defer func() { runner.MigrationStatus = orig }()
+
+	t.Run("counts pending migrations", func(t *testing.T) {
+		appliedAt := time.Now()
+		runner.MigrationStatus = func(*gorm.DB) ([]runner.MigrationState, error) {
+			return []runner.MigrationState{
+				{Version: "001_create_tenants_table", AppliedAt: &appliedAt},
+				{Version: "002_create_user_pools_table"},
+			}, nil
+		}
+		result, err := NewMigrationService(nil).GetStatus(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Pending)
+		require.Len(t, result.Migrations, 2)
+		assert.Equal(t, &appliedAt, result.Migrations[0].AppliedAt)
+		assert.Nil(t, result.Migrations[1].AppliedAt)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		runner.MigrationStatus = func(*gorm.DB) ([]runner.MigrationState, error) { return nil, errors.New("db down") }
+		_, err := NewMigrationService(nil).GetStatus(context.Background())
+		assert.ErrorAs(t, err, new(*apperror.InternalError))
+	})
+}
+
+func TestMigrationService_Apply(t *testing.T) {
+	orig := runner.ApplyMigrations
+	defer func() { runner.ApplyMigrations = orig }()
+
+	t.Run("success", func(t *testing.T) {
+		runner.ApplyMigrations = func(*gorm.DB) ([]string, error) { return []string{"076_next"}, nil }
+		applied, err := NewMigrationService(nil).Apply(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"076_next"}, applied)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		runner.ApplyMigrations = func(*gorm.DB) ([]string, error) { return nil, errors.New("076_next failed") }
+		_, err := NewMigrationService(nil).Apply(context.Background())
+		assert.ErrorAs(t, err, new(*apperror.InternalError))
+	})
+}
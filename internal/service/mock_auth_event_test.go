@@ -12,6 +12,7 @@ import (
 type mockAuthEventService struct {
 	logFn              func(ctx context.Context, input AuthEventInput)
 	findPaginatedFn    func(ctx context.Context, filter repository.AuthEventRepositoryGetFilter) (*repository.PaginationResult[AuthEventServiceDataResult], error)
+	findByCursorFn     func(ctx context.Context, filter repository.AuthEventRepositoryGetFilter, cursor string) (*CursorPageResult[AuthEventServiceDataResult], error)
 	findByUUIDFn       func(ctx context.Context, tenantID int64, eventUUID uuid.UUID) (*AuthEventServiceDataResult, error)
 	countByEventTypeFn func(ctx context.Context, eventType string, tenantID int64) (int64, error)
 	deleteOlderThanFn  func(ctx context.Context, cutoff time.Time) (int64, error)
@@ -30,6 +31,13 @@ func (m *mockAuthEventService) FindPaginated(ctx context.Context, filter reposit
 	return &repository.PaginationResult[AuthEventServiceDataResult]{}, nil
 }
 
+func (m *mockAuthEventService) FindByCursor(ctx context.Context, filter repository.AuthEventRepositoryGetFilter, cursor string) (*CursorPageResult[AuthEventServiceDataResult], error) {
+	if m.findByCursorFn != nil {
+		return m.findByCursorFn(ctx, filter, cursor)
+	}
+	return &CursorPageResult[AuthEventServiceDataResult]{}, nil
+}
+
 func (m *mockAuthEventService) FindByUUID(ctx context.Context, tenantID int64, eventUUID uuid.UUID) (*AuthEventServiceDataResult, error) {
 	if m.findByUUIDFn != nil {
 		return m.findByUUIDFn(ctx, tenantID, eventUUID)
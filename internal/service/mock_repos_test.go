@@ -1,6 +1,8 @@
 package service
 
 import (
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,7 +25,12 @@ type mockTenantRepo struct {
 	createFn           func(e *model.Tenant) (*model.Tenant, error)
 	createOrUpdateFn   func(e *model.Tenant) (*model.Tenant, error)
 	setStatusByUUIDFn  func(tenantUUID uuid.UUID, status string) error
+	updateByUUIDFn     func(id, data any) (*model.Tenant, error)
 	deleteByUUIDFn     func(id any) error
+	findByIDFn         func(id any, preloads ...string) (*model.Tenant, error)
+	countChildrenFn    func(tenantID int64) (int64, error)
+	moveSubtreeFn      func(tenant *model.Tenant, parentTenantID *int64, ancestorPath string) error
+	setPlanByUUIDFn    func(tenantUUID uuid.UUID, planID *int64) error
 }
 
 func (m *mockTenantRepo) WithTx(_ *gorm.DB) repository.TenantRepository { return m }
@@ -42,11 +49,39 @@ func (m *mockTenantRepo) FindAll(p ...string) ([]model.Tenant, error) {
 func (m *mockTenantRepo) FindByUUIDs(ids []string, p ...string) ([]model.Tenant, error) {
 	return nil, nil
 }
-func (m *mockTenantRepo) FindByID(id any, p ...string) (*model.Tenant, error) { return nil, nil }
-func (m *mockTenantRepo) UpdateByUUID(id, data any) (*model.Tenant, error)    { return nil, nil }
-func (m *mockTenantRepo) UpdateByID(id, data any) (*model.Tenant, error)      { return nil, nil }
-func (m *mockTenantRepo) DeleteByID(id any) error                             { return nil }
-func (m *mockTenantRepo) SetSystemStatusByUUID(_ uuid.UUID, _ bool) error     { return nil }
+func (m *mockTenantRepo) FindByID(id any, p ...string) (*model.Tenant, error) {
+	if m.findByIDFn != nil {
+		return m.findByIDFn(id, p...)
+	}
+	return nil, nil
+}
+func (m *mockTenantRepo) CountChildren(tenantID int64) (int64, error) {
+	if m.countChildrenFn != nil {
+		return m.countChildrenFn(tenantID)
+	}
+	return 0, nil
+}
+func (m *mockTenantRepo) MoveSubtree(tenant *model.Tenant, parentTenantID *int64, ancestorPath string) error {
+	if m.moveSubtreeFn != nil {
+		return m.moveSubtreeFn(tenant, parentTenantID, ancestorPath)
+	}
+	return nil
+}
+func (m *mockTenantRepo) UpdateByUUID(id, data any) (*model.Tenant, error) {
+	if m.updateByUUIDFn != nil {
+		return m.updateByUUIDFn(id, data)
+	}
+	return nil, nil
+}
+func (m *mockTenantRepo) UpdateByID(id, data any) (*model.Tenant, error)  { return nil, nil }
+func (m *mockTenantRepo) DeleteByID(id any) error                         { return nil }
+func (m *mockTenantRepo) SetSystemStatusByUUID(_ uuid.UUID, _ bool) error { return nil }
+func (m *mockTenantRepo) SetPlanByUUID(tenantUUID uuid.UUID, planID *int64) error {
+	if m.setPlanByUUIDFn != nil {
+		return m.setPlanByUUIDFn(tenantUUID, planID)
+	}
+	return nil
+}
 func (m *mockTenantRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.Tenant], error) {
 	return nil, nil
 }
@@ -249,9 +284,12 @@ type mockAPIRepo struct {
 	findByUUIDAndTenantIDFn   func(uuid.UUID, int64) (*model.API, error)
 	findPaginatedFn           func(repository.APIRepositoryGetFilter) (*repository.PaginationResult[model.API], error)
 	findByNameFn              func(name string, tenantID int64) (*model.API, error)
+	findByIdentifierFn        func(identifier string, tenantID int64) (*model.API, error)
 	createOrUpdateFn          func(*model.API) (*model.API, error)
 	deleteByUUIDAndTenantIDFn func(uuid.UUID, int64) error
 	countByServiceIDFn        func(int64, int64) (int64, error)
+	findDeprecatedByClientFn  func(int64) ([]model.API, error)
+	recordDeprecatedUsageFn   func([]int64) error
 }
 
 func (m *mockAPIRepo) WithTx(_ *gorm.DB) repository.APIRepository { return m }
@@ -278,8 +316,13 @@ func (m *mockAPIRepo) FindByName(name string, tID int64) (*model.API, error) {
 	}
 	return nil, nil
 }
-func (m *mockAPIRepo) FindByIdentifier(_ string, _ int64) (*model.API, error) { return nil, nil }
-func (m *mockAPIRepo) SetStatusByUUID(_ uuid.UUID, _ int64, _ string) error   { return nil }
+func (m *mockAPIRepo) FindByIdentifier(identifier string, tID int64) (*model.API, error) {
+	if m.findByIdentifierFn != nil {
+		return m.findByIdentifierFn(identifier, tID)
+	}
+	return nil, nil
+}
+func (m *mockAPIRepo) SetStatusByUUID(_ uuid.UUID, _ int64, _ string) error { return nil }
 func (m *mockAPIRepo) CountByServiceID(sID int64, tID int64) (int64, error) {
 	if m.countByServiceIDFn != nil {
 		return m.countByServiceIDFn(sID, tID)
@@ -292,6 +335,18 @@ func (m *mockAPIRepo) DeleteByUUIDAndTenantID(id uuid.UUID, tID int64) error {
 	}
 	return nil
 }
+func (m *mockAPIRepo) FindDeprecatedByClientID(cID int64) ([]model.API, error) {
+	if m.findDeprecatedByClientFn != nil {
+		return m.findDeprecatedByClientFn(cID)
+	}
+	return nil, nil
+}
+func (m *mockAPIRepo) RecordDeprecatedUsage(ids []int64) error {
+	if m.recordDeprecatedUsageFn != nil {
+		return m.recordDeprecatedUsageFn(ids)
+	}
+	return nil
+}
 
 func (m *mockAPIRepo) FindByUUID(id any, p ...string) (*model.API, error) {
 	if m.findByUUIDFn != nil {
@@ -326,6 +381,8 @@ type mockRoleRepo struct {
 	findByUUIDsFn                func([]string, ...string) ([]model.Role, error)
 	findRegisteredRoleForSetupFn func(int64) (*model.Role, error)
 	findSuperAdminRoleForSetupFn func(int64) (*model.Role, error)
+	findAllByTenantIDFn          func(int64) ([]model.Role, error)
+	unsetDefaultByTenantIDFn     func(int64) error
 }
 
 func (m *mockRoleRepo) WithTx(_ *gorm.DB) repository.RoleRepository { return m }
@@ -344,10 +401,21 @@ func (m *mockRoleRepo) DeleteByID(_ any) error                           { retur
 func (m *mockRoleRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.Role], error) {
 	return nil, nil
 }
-func (m *mockRoleRepo) FindAllByTenantID(_ int64) ([]model.Role, error)  { return nil, nil }
+func (m *mockRoleRepo) FindAllByTenantID(tID int64) ([]model.Role, error) {
+	if m.findAllByTenantIDFn != nil {
+		return m.findAllByTenantIDFn(tID)
+	}
+	return nil, nil
+}
 func (m *mockRoleRepo) SetStatusByUUID(_ uuid.UUID, _ string) error      { return nil }
 func (m *mockRoleRepo) SetDefaultStatusByUUID(_ uuid.UUID, _ bool) error { return nil }
 func (m *mockRoleRepo) SetSystemStatusByUUID(_ uuid.UUID, _ bool) error  { return nil }
+func (m *mockRoleRepo) UnsetDefaultByTenantID(tID int64) error {
+	if m.unsetDefaultByTenantIDFn != nil {
+		return m.unsetDefaultByTenantIDFn(tID)
+	}
+	return nil
+}
 func (m *mockRoleRepo) FindRegisteredRoleForSetup(tID int64) (*model.Role, error) {
 	if m.findRegisteredRoleForSetupFn != nil {
 		return m.findRegisteredRoleForSetupFn(tID)
@@ -600,6 +668,7 @@ func (m *mockTenantServiceRepo) FindByTenantAndService(tenantID int64, serviceID
 type mockLoginTemplateRepo struct {
 	createFn                func(e *model.LoginTemplate) (*model.LoginTemplate, error)
 	findByUUIDAndTenantIDFn func(uuid.UUID, int64, ...string) (*model.LoginTemplate, error)
+	findDefaultByTenantIDFn func(int64) (*model.LoginTemplate, error)
 	findPaginatedFn         func(repository.LoginTemplateRepositoryGetFilter) (*repository.PaginationResult[model.LoginTemplate], error)
 	updateByUUIDFn          func(any, any) (*model.LoginTemplate, error)
 	deleteByUUIDFn          func(any) error
@@ -626,6 +695,12 @@ func (m *mockLoginTemplateRepo) Paginate(_ map[string]any, _, _ int, _ ...string
 	return nil, nil
 }
 func (m *mockLoginTemplateRepo) FindByName(_ string) (*model.LoginTemplate, error) { return nil, nil }
+func (m *mockLoginTemplateRepo) FindDefaultByTenantID(tID int64) (*model.LoginTemplate, error) {
+	if m.findDefaultByTenantIDFn != nil {
+		return m.findDefaultByTenantIDFn(tID)
+	}
+	return nil, nil
+}
 
 func (m *mockLoginTemplateRepo) Create(e *model.LoginTemplate) (*model.LoginTemplate, error) {
 	if m.createFn != nil {
@@ -726,11 +801,12 @@ func (m *mockSMSTemplateRepo) DeleteByUUID(id any) error {
 // ---------------------------------------------------------------------------
 
 type mockIPRestrictionRuleRepo struct {
-	findByUUIDFn    func(id any, preloads ...string) (*model.IPRestrictionRule, error)
-	findPaginatedFn func(repository.IPRestrictionRuleRepositoryGetFilter) (*repository.PaginationResult[model.IPRestrictionRule], error)
-	createFn        func(e *model.IPRestrictionRule) (*model.IPRestrictionRule, error)
-	updateByUUIDFn  func(any, any) (*model.IPRestrictionRule, error)
-	deleteByUUIDFn  func(any) error
+	findByUUIDFn     func(id any, preloads ...string) (*model.IPRestrictionRule, error)
+	findPaginatedFn  func(repository.IPRestrictionRuleRepositoryGetFilter) (*repository.PaginationResult[model.IPRestrictionRule], error)
+	findActiveDenyFn func(tenantID int64, now time.Time) ([]model.IPRestrictionRule, error)
+	createFn         func(e *model.IPRestrictionRule) (*model.IPRestrictionRule, error)
+	updateByUUIDFn   func(any, any) (*model.IPRestrictionRule, error)
+	deleteByUUIDFn   func(any) error
 }
 
 func (m *mockIPRestrictionRuleRepo) WithTx(_ *gorm.DB) repository.IPRestrictionRuleRepository {
@@ -764,6 +840,12 @@ func (m *mockIPRestrictionRuleRepo) FindByTenantIDAndStatus(_ int64, _ string) (
 func (m *mockIPRestrictionRuleRepo) FindByTenantIDAndType(_ int64, _ string) ([]model.IPRestrictionRule, error) {
 	return nil, nil
 }
+func (m *mockIPRestrictionRuleRepo) FindActiveDenyByTenantID(tenantID int64, now time.Time) ([]model.IPRestrictionRule, error) {
+	if m.findActiveDenyFn != nil {
+		return m.findActiveDenyFn(tenantID, now)
+	}
+	return nil, nil
+}
 
 func (m *mockIPRestrictionRuleRepo) FindByUUID(id any, p ...string) (*model.IPRestrictionRule, error) {
 	if m.findByUUIDFn != nil {
@@ -1080,6 +1162,7 @@ func (m *mockUserSettingRepo) DeleteByUUID(id any) error {
 
 type mockTenantMemberRepo struct {
 	findByTenantMemberUUIDFn func(uuid.UUID) (*model.TenantMember, error)
+	findByUUIDAndTenantIDFn  func(uuid.UUID, int64) (*model.TenantMember, error)
 	findByTenantAndUserFn    func(tenantID int64, userID int64) (*model.TenantMember, error)
 	findAllByTenantFn        func(tenantID int64) ([]model.TenantMember, error)
 	findAllByUserFn          func(userID int64) ([]model.TenantMember, error)
@@ -1124,6 +1207,12 @@ func (m *mockTenantMemberRepo) FindByTenantMemberUUID(id uuid.UUID) (*model.Tena
 	}
 	return nil, nil
 }
+func (m *mockTenantMemberRepo) FindByUUIDAndTenantID(id uuid.UUID, tID int64) (*model.TenantMember, error) {
+	if m.findByUUIDAndTenantIDFn != nil {
+		return m.findByUUIDAndTenantIDFn(id, tID)
+	}
+	return nil, nil
+}
 func (m *mockTenantMemberRepo) FindByTenantAndUser(tID, uID int64) (*model.TenantMember, error) {
 	if m.findByTenantAndUserFn != nil {
 		return m.findByTenantAndUserFn(tID, uID)
@@ -1154,9 +1243,10 @@ func (m *mockTenantMemberRepo) DeleteByUUID(id any) error {
 // ---------------------------------------------------------------------------
 
 type mockServicePolicyRepo struct {
-	findByServiceAndPolicyFn func(serviceID int64, policyID int64) (*model.ServicePolicy, error)
-	createFn                 func(*model.ServicePolicy) (*model.ServicePolicy, error)
-	deleteByServiceAndPolicy func(serviceID int64, policyID int64) error
+	findByServiceAndPolicyFn  func(serviceID int64, policyID int64) (*model.ServicePolicy, error)
+	findPoliciesByServiceIDFn func(serviceID int64) ([]model.Policy, error)
+	createFn                  func(*model.ServicePolicy) (*model.ServicePolicy, error)
+	deleteByServiceAndPolicy  func(serviceID int64, policyID int64) error
 }
 
 func (m *mockServicePolicyRepo) WithTx(_ *gorm.DB) repository.ServicePolicyRepository { return m }
@@ -1185,7 +1275,10 @@ func (m *mockServicePolicyRepo) Paginate(_ map[string]any, _, _ int, _ ...string
 func (m *mockServicePolicyRepo) FindPaginated(_ repository.ServicePolicyRepositoryGetFilter) (*repository.PaginationResult[model.ServicePolicy], error) {
 	return nil, nil
 }
-func (m *mockServicePolicyRepo) FindPoliciesByServiceID(_ int64) ([]model.Policy, error) {
+func (m *mockServicePolicyRepo) FindPoliciesByServiceID(serviceID int64) ([]model.Policy, error) {
+	if m.findPoliciesByServiceIDFn != nil {
+		return m.findPoliciesByServiceIDFn(serviceID)
+	}
 	return nil, nil
 }
 func (m *mockServicePolicyRepo) FindServicesByPolicyID(_ int64) ([]model.Service, error) {
@@ -1224,6 +1317,7 @@ type mockPolicyRepo struct {
 	updateByUUIDFn          func(any, any) (*model.Policy, error)
 	setStatusByUUIDFn       func(uuid.UUID, int64, string) error
 	deleteByUUIDAndTenantFn func(uuid.UUID, int64) error
+	findActiveByTenantIDFn  func(int64) ([]model.Policy, error)
 }
 
 func (m *mockPolicyRepo) WithTx(_ *gorm.DB) repository.PolicyRepository         { return m }
@@ -1247,6 +1341,12 @@ func (m *mockPolicyRepo) UpdateByID(_, _ any) (*model.Policy, error)         { r
 func (m *mockPolicyRepo) DeleteByUUID(_ any) error                           { return nil }
 func (m *mockPolicyRepo) DeleteByID(_ any) error                             { return nil }
 func (m *mockPolicyRepo) FindSystemPolicies(_ int64) ([]model.Policy, error) { return nil, nil }
+func (m *mockPolicyRepo) FindActiveByTenantID(tID int64) ([]model.Policy, error) {
+	if m.findActiveByTenantIDFn != nil {
+		return m.findActiveByTenantIDFn(tID)
+	}
+	return nil, nil
+}
 func (m *mockPolicyRepo) SetStatusByUUID(id uuid.UUID, tID int64, status string) error {
 	if m.setStatusByUUIDFn != nil {
 		return m.setStatusByUUIDFn(id, tID, status)
@@ -1495,12 +1595,14 @@ type mockAPIKeyRepo struct {
 	findByUUIDFn              func(any, ...string) (*model.APIKey, error)
 	findByUUIDAndTenantIDFn   func(string, int64) (*model.APIKey, error)
 	findByKeyHashFn           func(string) (*model.APIKey, error)
+	findWithPermissionsFn     func(string) (*model.APIKey, error)
 	findByKeyPrefixFn         func(string) (*model.APIKey, error)
 	deleteByUUIDFn            func(any) error
 	deleteByUUIDAndTenantIDFn func(string, int64) error
 	findPaginatedFn           func(repository.APIKeyRepositoryGetFilter) (*repository.PaginationResult[model.APIKey], error)
 	createFn                  func(*model.APIKey) (*model.APIKey, error)
 	updateByUUIDFn            func(any, any) (*model.APIKey, error)
+	addUsageFn                func(int64, int64, time.Time) error
 }
 
 func (m *mockAPIKeyRepo) WithTx(_ *gorm.DB) repository.APIKeyRepository { return m }
@@ -1551,6 +1653,18 @@ func (m *mockAPIKeyRepo) FindByKeyHash(h string) (*model.APIKey, error) {
 	}
 	return nil, nil
 }
+func (m *mockAPIKeyRepo) FindByKeyHashWithPermissions(h string) (*model.APIKey, error) {
+	if m.findWithPermissionsFn != nil {
+		return m.findWithPermissionsFn(h)
+	}
+	return nil, nil
+}
+func (m *mockAPIKeyRepo) AddUsage(id int64, count int64, at time.Time) error {
+	if m.addUsageFn != nil {
+		return m.addUsageFn(id, count, at)
+	}
+	return nil
+}
 func (m *mockAPIKeyRepo) FindByKeyPrefix(p string) (*model.APIKey, error) {
 	if m.findByKeyPrefixFn != nil {
 		return m.findByKeyPrefixFn(p)
@@ -1582,6 +1696,7 @@ type mockAPIKeyAPIRepo struct {
 	removeByAPIKeyAndAPIFn         func(int64, int64) error
 	removeByAPIKeyUUIDAndAPIUUIDFn func(uuid.UUID, uuid.UUID) error
 	createFn                       func(*model.APIKeyAPI) (*model.APIKeyAPI, error)
+	deleteByAPIKeyIDFn             func(int64) error
 }
 
 func (m *mockAPIKeyAPIRepo) WithTx(_ *gorm.DB) repository.APIKeyAPIRepository { return m }
@@ -1643,6 +1758,12 @@ func (m *mockAPIKeyAPIRepo) RemoveByAPIKeyUUIDAndAPIUUID(akUUID, aUUID uuid.UUID
 	}
 	return nil
 }
+func (m *mockAPIKeyAPIRepo) DeleteByAPIKeyID(akID int64) error {
+	if m.deleteByAPIKeyIDFn != nil {
+		return m.deleteByAPIKeyIDFn(akID)
+	}
+	return nil
+}
 
 // ---------------------------------------------------------------------------
 // Mock: APIKeyPermissionRepository
@@ -1653,6 +1774,7 @@ type mockAPIKeyPermissionRepo struct {
 	findByAPIKeyAPIAndPermissionFn   func(int64, int64) (*model.APIKeyPermission, error)
 	removeByAPIKeyAPIAndPermissionFn func(int64, int64) error
 	findByAPIKeyAPIIDFn              func(int64) ([]model.APIKeyPermission, error)
+	deleteByAPIKeyIDFn               func(int64) error
 }
 
 func (m *mockAPIKeyPermissionRepo) WithTx(_ *gorm.DB) repository.APIKeyPermissionRepository {
@@ -1708,6 +1830,12 @@ func (m *mockAPIKeyPermissionRepo) FindByAPIKeyAPIID(akaID int64) ([]model.APIKe
 	}
 	return nil, nil
 }
+func (m *mockAPIKeyPermissionRepo) DeleteByAPIKeyID(akID int64) error {
+	if m.deleteByAPIKeyIDFn != nil {
+		return m.deleteByAPIKeyIDFn(akID)
+	}
+	return nil
+}
 
 // ---------------------------------------------------------------------------
 // Mock: InviteRepository
@@ -1776,7 +1904,10 @@ func (m *mockInviteRepo) RevokeByUUID(id uuid.UUID) error {
 type mockSignupFlowRepo struct {
 	findByUUIDAndTenantIDFn       func(uuid.UUID, int64, ...string) (*model.SignupFlow, error)
 	findByIdentifierAndClientIDFn func(string, int64) (*model.SignupFlow, error)
+	findActiveByClientIDFn        func(int64) (*model.SignupFlow, error)
 	findByNameFn                  func(string) (*model.SignupFlow, error)
+	findByNameAndTenantIDFn       func(string, int64) (*model.SignupFlow, error)
+	lockByIDFn                    func(int64) error
 	findPaginatedFn               func(repository.SignupFlowRepositoryGetFilter) (*repository.PaginationResult[model.SignupFlow], error)
 	createFn                      func(*model.SignupFlow) (*model.SignupFlow, error)
 	createOrUpdateFn              func(*model.SignupFlow) (*model.SignupFlow, error)
@@ -1834,12 +1965,85 @@ func (m *mockSignupFlowRepo) FindByIdentifierAndClientID(ident string, cID int64
 	}
 	return nil, nil
 }
+func (m *mockSignupFlowRepo) FindActiveByClientID(cID int64) (*model.SignupFlow, error) {
+	if m.findActiveByClientIDFn != nil {
+		return m.findActiveByClientIDFn(cID)
+	}
+	return nil, nil
+}
 func (m *mockSignupFlowRepo) FindByName(name string) (*model.SignupFlow, error) {
 	if m.findByNameFn != nil {
 		return m.findByNameFn(name)
 	}
 	return nil, nil
 }
+func (m *mockSignupFlowRepo) FindByNameAndTenantID(name string, tenantID int64) (*model.SignupFlow, error) {
+	if m.findByNameAndTenantIDFn != nil {
+		return m.findByNameAndTenantIDFn(name, tenantID)
+	}
+	return nil, nil
+}
+func (m *mockSignupFlowRepo) LockByID(id int64) error {
+	if m.lockByIDFn != nil {
+		return m.lockByIDFn(id)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: SignupFlowSignupRepository
+// ---------------------------------------------------------------------------
+
+// mockSignupFlowSignupRepo keeps created signups in memory and counts them.
+type mockSignupFlowSignupRepo struct {
+	created []model.SignupFlowSignup
+	total   int64
+	today   int64
+	countFn func(int64, time.Time) (int64, error)
+}
+
+func (m *mockSignupFlowSignupRepo) WithTx(_ *gorm.DB) repository.SignupFlowSignupRepository {
+	return m
+}
+func (m *mockSignupFlowSignupRepo) Create(e *model.SignupFlowSignup) (*model.SignupFlowSignup, error) {
+	m.created = append(m.created, *e)
+	return e, nil
+}
+func (m *mockSignupFlowSignupRepo) CreateOrUpdate(e *model.SignupFlowSignup) (*model.SignupFlowSignup, error) {
+	return e, nil
+}
+func (m *mockSignupFlowSignupRepo) FindAll(_ ...string) ([]model.SignupFlowSignup, error) {
+	return nil, nil
+}
+func (m *mockSignupFlowSignupRepo) FindByUUID(_ any, _ ...string) (*model.SignupFlowSignup, error) {
+	return nil, nil
+}
+func (m *mockSignupFlowSignupRepo) FindByUUIDs(_ []string, _ ...string) ([]model.SignupFlowSignup, error) {
+	return nil, nil
+}
+func (m *mockSignupFlowSignupRepo) FindByID(_ any, _ ...string) (*model.SignupFlowSignup, error) {
+	return nil, nil
+}
+func (m *mockSignupFlowSignupRepo) UpdateByUUID(_, _ any) (*model.SignupFlowSignup, error) {
+	return nil, nil
+}
+func (m *mockSignupFlowSignupRepo) UpdateByID(_, _ any) (*model.SignupFlowSignup, error) {
+	return nil, nil
+}
+func (m *mockSignupFlowSignupRepo) DeleteByUUID(_ any) error { return nil }
+func (m *mockSignupFlowSignupRepo) DeleteByID(_ any) error   { return nil }
+func (m *mockSignupFlowSignupRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.SignupFlowSignup], error) {
+	return nil, nil
+}
+func (m *mockSignupFlowSignupRepo) CountBySignupFlowID(id int64, since time.Time) (int64, error) {
+	if m.countFn != nil {
+		return m.countFn(id, since)
+	}
+	if since.IsZero() {
+		return m.total, nil
+	}
+	return m.today, nil
+}
 
 // ---------------------------------------------------------------------------
 // Mock: SignupFlowRoleRepository
@@ -1918,9 +2122,16 @@ type mockUserRoleRepo struct {
 	findByUserIDAndRoleIDFn   func(int64, int64) (*model.UserRole, error)
 	deleteByUserIDAndRoleIDFn func(int64, int64) error
 	createFn                  func(*model.UserRole) (*model.UserRole, error)
+	createBatchFn             func([]model.UserRole) error
 }
 
 func (m *mockUserRoleRepo) WithTx(_ *gorm.DB) repository.UserRoleRepository { return m }
+func (m *mockUserRoleRepo) CreateBatch(urs []model.UserRole) error {
+	if m.createBatchFn != nil {
+		return m.createBatchFn(urs)
+	}
+	return nil
+}
 func (m *mockUserRoleRepo) Create(e *model.UserRole) (*model.UserRole, error) {
 	if m.createFn != nil {
 		return m.createFn(e)
@@ -1969,7 +2180,8 @@ func (m *mockUserRoleRepo) DeleteByUserIDAndRoleID(uID, rID int64) error {
 // ---------------------------------------------------------------------------
 
 type mockUserPoolRepo struct {
-	findByUUIDFn func(any, ...string) (*model.UserPool, error)
+	findByUUIDFn       func(any, ...string) (*model.UserPool, error)
+	deleteByTenantIDFn func(int64) error
 }
 
 func (m *mockUserPoolRepo) WithTx(_ *gorm.DB) repository.UserPoolRepository { return m }
@@ -2003,6 +2215,12 @@ func (m *mockUserPoolRepo) FindByIdentifier(_ int64, _ string) (*model.UserPool,
 func (m *mockUserPoolRepo) FindDefault(_ int64) (*model.UserPool, error)        { return nil, nil }
 func (m *mockUserPoolRepo) FindSystem(_ int64) (*model.UserPool, error)         { return nil, nil }
 func (m *mockUserPoolRepo) FindAllByTenantID(_ int64) ([]model.UserPool, error) { return nil, nil }
+func (m *mockUserPoolRepo) DeleteByTenantID(tID int64) error {
+	if m.deleteByTenantIDFn != nil {
+		return m.deleteByTenantIDFn(tID)
+	}
+	return nil
+}
 
 // ---------------------------------------------------------------------------
 // Mock: BrandingRepository
@@ -2199,6 +2417,9 @@ type mockWebhookEndpointRepo struct {
 	createFn              func(*model.WebhookEndpoint) (*model.WebhookEndpoint, error)
 	updateByUUIDFn        func(any, any) (*model.WebhookEndpoint, error)
 	deleteByUUIDFn        func(any) error
+	findActiveFn          func() ([]model.WebhookEndpoint, error)
+	updateCursorsFn       func(int64, int64, int64) error
+	triggered             []int64
 }
 
 func (m *mockWebhookEndpointRepo) WithTx(_ *gorm.DB) repository.WebhookEndpointRepository {
@@ -2262,6 +2483,176 @@ func (m *mockWebhookEndpointRepo) DeleteByUUID(id any) error {
 	}
 	return nil
 }
+func (m *mockWebhookEndpointRepo) FindActive() ([]model.WebhookEndpoint, error) {
+	if m.findActiveFn != nil {
+		return m.findActiveFn()
+	}
+	return nil, nil
+}
+func (m *mockWebhookEndpointRepo) UpdateCursors(id, lastEventID, lastAuthEventID int64) error {
+	if m.updateCursorsFn != nil {
+		return m.updateCursorsFn(id, lastEventID, lastAuthEventID)
+	}
+	return nil
+}
+func (m *mockWebhookEndpointRepo) MarkTriggered(id int64, _ time.Time) error {
+	m.triggered = append(m.triggered, id)
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: WebhookDeliveryRepository
+// ---------------------------------------------------------------------------
+
+type mockWebhookDeliveryRepo struct {
+	createBatchFn           func([]model.WebhookDelivery) error
+	claimDueFn              func(time.Time, time.Duration, int) ([]model.WebhookDelivery, error)
+	findByUUIDAndEndpointFn func(uuid.UUID, int64) (*model.WebhookDelivery, error)
+	findPaginatedFn         func(repository.WebhookDeliveryRepositoryGetFilter) (*repository.PaginationResult[model.WebhookDelivery], error)
+	updateByIDFn            func(any, any) (*model.WebhookDelivery, error)
+	pendingBacklogFn        func() (*repository.Backlog, error)
+}
+
+func (m *mockWebhookDeliveryRepo) WithTx(_ *gorm.DB) repository.WebhookDeliveryRepository {
+	return m
+}
+func (m *mockWebhookDeliveryRepo) FindAll(_ ...string) ([]model.WebhookDelivery, error) {
+	return nil, nil
+}
+func (m *mockWebhookDeliveryRepo) FindByUUID(_ any, _ ...string) (*model.WebhookDelivery, error) {
+	return nil, nil
+}
+func (m *mockWebhookDeliveryRepo) FindByUUIDs(_ []string, _ ...string) ([]model.WebhookDelivery, error) {
+	return nil, nil
+}
+func (m *mockWebhookDeliveryRepo) FindByID(_ any, _ ...string) (*model.WebhookDelivery, error) {
+	return nil, nil
+}
+func (m *mockWebhookDeliveryRepo) UpdateByUUID(_, _ any) (*model.WebhookDelivery, error) {
+	return nil, nil
+}
+func (m *mockWebhookDeliveryRepo) UpdateByID(id, data any) (*model.WebhookDelivery, error) {
+	if m.updateByIDFn != nil {
+		return m.updateByIDFn(id, data)
+	}
+	return nil, nil
+}
+func (m *mockWebhookDeliveryRepo) DeleteByUUID(_ any) error { return nil }
+func (m *mockWebhookDeliveryRepo) DeleteByID(_ any) error   { return nil }
+func (m *mockWebhookDeliveryRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.WebhookDelivery], error) {
+	return nil, nil
+}
+func (m *mockWebhookDeliveryRepo) Create(e *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	return e, nil
+}
+func (m *mockWebhookDeliveryRepo) CreateOrUpdate(e *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	return e, nil
+}
+func (m *mockWebhookDeliveryRepo) CreateBatch(d []model.WebhookDelivery) error {
+	if m.createBatchFn != nil {
+		return m.createBatchFn(d)
+	}
+	return nil
+}
+func (m *mockWebhookDeliveryRepo) ClaimDue(now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error) {
+	if m.claimDueFn != nil {
+		return m.claimDueFn(now, lease, limit)
+	}
+	return nil, nil
+}
+func (m *mockWebhookDeliveryRepo) FindByUUIDAndEndpointID(id uuid.UUID, endpointID int64) (*model.WebhookDelivery, error) {
+	if m.findByUUIDAndEndpointFn != nil {
+		return m.findByUUIDAndEndpointFn(id, endpointID)
+	}
+	return nil, nil
+}
+func (m *mockWebhookDeliveryRepo) FindPaginated(f repository.WebhookDeliveryRepositoryGetFilter) (*repository.PaginationResult[model.WebhookDelivery], error) {
+	if m.findPaginatedFn != nil {
+		return m.findPaginatedFn(f)
+	}
+	return &repository.PaginationResult[model.WebhookDelivery]{}, nil
+}
+func (m *mockWebhookDeliveryRepo) PendingBacklog() (*repository.Backlog, error) {
+	if m.pendingBacklogFn != nil {
+		return m.pendingBacklogFn()
+	}
+	return &repository.Backlog{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: LoginHookRepository
+// ---------------------------------------------------------------------------
+
+type mockLoginHookRepo struct {
+	findByUUIDAndTenantFn func(uuid.UUID, int64) (*model.LoginHook, error)
+	findActiveByTriggerFn func(int64, string) ([]model.LoginHook, error)
+	findPaginatedFn       func(repository.LoginHookRepositoryGetFilter) (*repository.PaginationResult[model.LoginHook], error)
+	createFn              func(*model.LoginHook) (*model.LoginHook, error)
+	updateByUUIDFn        func(any, any) (*model.LoginHook, error)
+	deleteByUUIDFn        func(any) error
+}
+
+func (m *mockLoginHookRepo) WithTx(_ *gorm.DB) repository.LoginHookRepository {
+	return m
+}
+func (m *mockLoginHookRepo) FindAll(_ ...string) ([]model.LoginHook, error) {
+	return nil, nil
+}
+func (m *mockLoginHookRepo) FindByUUID(_ any, _ ...string) (*model.LoginHook, error) {
+	return nil, nil
+}
+func (m *mockLoginHookRepo) FindByUUIDs(_ []string, _ ...string) ([]model.LoginHook, error) {
+	return nil, nil
+}
+func (m *mockLoginHookRepo) FindByID(_ any, _ ...string) (*model.LoginHook, error) {
+	return nil, nil
+}
+func (m *mockLoginHookRepo) UpdateByID(_, _ any) (*model.LoginHook, error) {
+	return nil, nil
+}
+func (m *mockLoginHookRepo) DeleteByID(_ any) error { return nil }
+func (m *mockLoginHookRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.LoginHook], error) {
+	return nil, nil
+}
+func (m *mockLoginHookRepo) CreateOrUpdate(e *model.LoginHook) (*model.LoginHook, error) {
+	return e, nil
+}
+func (m *mockLoginHookRepo) FindByUUIDAndTenantID(id uuid.UUID, tid int64) (*model.LoginHook, error) {
+	if m.findByUUIDAndTenantFn != nil {
+		return m.findByUUIDAndTenantFn(id, tid)
+	}
+	return nil, nil
+}
+func (m *mockLoginHookRepo) FindActiveByTrigger(tid int64, trigger string) ([]model.LoginHook, error) {
+	if m.findActiveByTriggerFn != nil {
+		return m.findActiveByTriggerFn(tid, trigger)
+	}
+	return nil, nil
+}
+func (m *mockLoginHookRepo) FindPaginated(f repository.LoginHookRepositoryGetFilter) (*repository.PaginationResult[model.LoginHook], error) {
+	if m.findPaginatedFn != nil {
+		return m.findPaginatedFn(f)
+	}
+	return &repository.PaginationResult[model.LoginHook]{}, nil
+}
+func (m *mockLoginHookRepo) Create(e *model.LoginHook) (*model.LoginHook, error) {
+	if m.createFn != nil {
+		return m.createFn(e)
+	}
+	return e, nil
+}
+func (m *mockLoginHookRepo) UpdateByUUID(id, data any) (*model.LoginHook, error) {
+	if m.updateByUUIDFn != nil {
+		return m.updateByUUIDFn(id, data)
+	}
+	return nil, nil
+}
+func (m *mockLoginHookRepo) DeleteByUUID(id any) error {
+	if m.deleteByUUIDFn != nil {
+		return m.deleteByUUIDFn(id)
+	}
+	return nil
+}
 
 // ---------------------------------------------------------------------------
 // Mock: OAuthAuthorizationCodeRepository
@@ -2339,7 +2730,12 @@ type mockOAuthRefreshTokenRepo struct {
 	revokeByIDFn             func(int64) error
 	revokeByFamilyFn         func(uuid.UUID) (int64, error)
 	revokeByUserAndClientFn  func(int64, int64) (int64, error)
+	findBySessionIDFn        func(string) ([]model.OAuthRefreshToken, error)
+	findActiveSessionsFn     func(int64) ([]repository.OAuthSession, error)
+	findSessionStartedAtFn   func(uuid.UUID, *string) (time.Time, error)
+	revokeBySessionIDFn      func(string) (int64, error)
 	revokeByUserIDFn         func(int64) (int64, error)
+	revokeExceptFamilyFn     func(int64, uuid.UUID) (int64, error)
 	updateLastUsedFn         func(int64) error
 	deleteExpiredFn          func(time.Time) (int64, error)
 	countByUserAndClientFn   func(int64, int64) (int64, error)
@@ -2410,12 +2806,42 @@ func (m *mockOAuthRefreshTokenRepo) RevokeByUserAndClient(uid, cid int64) (int64
 	}
 	return 0, nil
 }
+func (m *mockOAuthRefreshTokenRepo) FindBySessionID(sid string) ([]model.OAuthRefreshToken, error) {
+	if m.findBySessionIDFn != nil {
+		return m.findBySessionIDFn(sid)
+	}
+	return nil, nil
+}
+func (m *mockOAuthRefreshTokenRepo) FindActiveSessionsByUserID(uid int64) ([]repository.OAuthSession, error) {
+	if m.findActiveSessionsFn != nil {
+		return m.findActiveSessionsFn(uid)
+	}
+	return nil, nil
+}
+func (m *mockOAuthRefreshTokenRepo) FindSessionStartedAt(fid uuid.UUID, sid *string) (time.Time, error) {
+	if m.findSessionStartedAtFn != nil {
+		return m.findSessionStartedAtFn(fid, sid)
+	}
+	return time.Now(), nil
+}
+func (m *mockOAuthRefreshTokenRepo) RevokeBySessionID(sid string) (int64, error) {
+	if m.revokeBySessionIDFn != nil {
+		return m.revokeBySessionIDFn(sid)
+	}
+	return 0, nil
+}
 func (m *mockOAuthRefreshTokenRepo) RevokeByUserID(uid int64) (int64, error) {
 	if m.revokeByUserIDFn != nil {
 		return m.revokeByUserIDFn(uid)
 	}
 	return 0, nil
 }
+func (m *mockOAuthRefreshTokenRepo) RevokeByUserIDExceptFamily(uid int64, fid uuid.UUID) (int64, error) {
+	if m.revokeExceptFamilyFn != nil {
+		return m.revokeExceptFamilyFn(uid, fid)
+	}
+	return 0, nil
+}
 func (m *mockOAuthRefreshTokenRepo) UpdateLastUsed(id int64) error {
 	if m.updateLastUsedFn != nil {
 		return m.updateLastUsedFn(id)
@@ -2571,3 +2997,1627 @@ func (m *mockOAuthConsentChallRepo) DeleteExpired(before time.Time) (int64, erro
 	}
 	return 0, nil
 }
+
+// ---------------------------------------------------------------------------
+// Mock: EventRepository
+// ---------------------------------------------------------------------------
+
+type mockEventRepo struct {
+	createFn    func(*model.Event) (*model.Event, error)
+	findFeedFn  func(repository.EventRepositoryFeedFilter) ([]model.Event, error)
+	findAfterFn func(int64, time.Time, int) ([]model.Event, error)
+	latestIDFn  func(int64) (int64, error)
+	backlogFn   func(int64) (*repository.Backlog, error)
+	created     []model.Event
+}
+
+func (m *mockEventRepo) WithTx(_ *gorm.DB) repository.EventRepository { return m }
+func (m *mockEventRepo) FindAll(_ ...string) ([]model.Event, error)   { return nil, nil }
+func (m *mockEventRepo) FindByUUID(_ any, _ ...string) (*model.Event, error) {
+	return nil, nil
+}
+func (m *mockEventRepo) FindByUUIDs(_ []string, _ ...string) ([]model.Event, error) {
+	return nil, nil
+}
+func (m *mockEventRepo) FindByID(_ any, _ ...string) (*model.Event, error) { return nil, nil }
+func (m *mockEventRepo) UpdateByUUID(_, _ any) (*model.Event, error)       { return nil, nil }
+func (m *mockEventRepo) UpdateByID(_, _ any) (*model.Event, error)         { return nil, nil }
+func (m *mockEventRepo) DeleteByUUID(_ any) error                          { return nil }
+func (m *mockEventRepo) DeleteByID(_ any) error                            { return nil }
+func (m *mockEventRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.Event], error) {
+	return nil, nil
+}
+func (m *mockEventRepo) Create(e *model.Event) (*model.Event, error) {
+	if m.createFn != nil {
+		return m.createFn(e)
+	}
+	m.created = append(m.created, *e)
+	return e, nil
+}
+func (m *mockEventRepo) CreateOrUpdate(e *model.Event) (*model.Event, error) { return e, nil }
+func (m *mockEventRepo) FindFeed(f repository.EventRepositoryFeedFilter) ([]model.Event, error) {
+	if m.findFeedFn != nil {
+		return m.findFeedFn(f)
+	}
+	return nil, nil
+}
+func (m *mockEventRepo) FindAfter(afterID int64, before time.Time, limit int) ([]model.Event, error) {
+	if m.findAfterFn != nil {
+		return m.findAfterFn(afterID, before, limit)
+	}
+	return nil, nil
+}
+func (m *mockEventRepo) LatestID(tenantID int64) (int64, error) {
+	if m.latestIDFn != nil {
+		return m.latestIDFn(tenantID)
+	}
+	return 0, nil
+}
+func (m *mockEventRepo) BacklogAfter(afterID int64) (*repository.Backlog, error) {
+	if m.backlogFn != nil {
+		return m.backlogFn(afterID)
+	}
+	return &repository.Backlog{}, nil
+}
+
+// eventTypes returns the types of the events recorded through Create.
+func (m *mockEventRepo) eventTypes() []string {
+	out := make([]string, len(m.created))
+	for i, e := range m.created {
+		out[i] = e.EventType
+	}
+	return out
+}
+
+// ---------------------------------------------------------------------------
+// Mock: EventRelayCursorRepository
+// ---------------------------------------------------------------------------
+
+type mockEventRelayCursorRepo struct {
+	findByNameFn func(string) (*model.EventRelayCursor, error)
+	lockFn       func(string) (*model.EventRelayCursor, error)
+	advanceFn    func(int64, int64) error
+}
+
+func (m *mockEventRelayCursorRepo) WithTx(_ *gorm.DB) repository.EventRelayCursorRepository {
+	return m
+}
+func (m *mockEventRelayCursorRepo) FindAll(_ ...string) ([]model.EventRelayCursor, error) {
+	return nil, nil
+}
+func (m *mockEventRelayCursorRepo) FindByUUID(_ any, _ ...string) (*model.EventRelayCursor, error) {
+	return nil, nil
+}
+func (m *mockEventRelayCursorRepo) FindByUUIDs(_ []string, _ ...string) ([]model.EventRelayCursor, error) {
+	return nil, nil
+}
+func (m *mockEventRelayCursorRepo) FindByID(_ any, _ ...string) (*model.EventRelayCursor, error) {
+	return nil, nil
+}
+func (m *mockEventRelayCursorRepo) UpdateByUUID(_, _ any) (*model.EventRelayCursor, error) {
+	return nil, nil
+}
+func (m *mockEventRelayCursorRepo) UpdateByID(_, _ any) (*model.EventRelayCursor, error) {
+	return nil, nil
+}
+func (m *mockEventRelayCursorRepo) DeleteByUUID(_ any) error { return nil }
+func (m *mockEventRelayCursorRepo) DeleteByID(_ any) error   { return nil }
+func (m *mockEventRelayCursorRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.EventRelayCursor], error) {
+	return nil, nil
+}
+func (m *mockEventRelayCursorRepo) Create(c *model.EventRelayCursor) (*model.EventRelayCursor, error) {
+	return c, nil
+}
+func (m *mockEventRelayCursorRepo) CreateOrUpdate(c *model.EventRelayCursor) (*model.EventRelayCursor, error) {
+	return c, nil
+}
+func (m *mockEventRelayCursorRepo) FindByName(name string) (*model.EventRelayCursor, error) {
+	if m.findByNameFn != nil {
+		return m.findByNameFn(name)
+	}
+	return nil, nil
+}
+func (m *mockEventRelayCursorRepo) Lock(name string) (*model.EventRelayCursor, error) {
+	if m.lockFn != nil {
+		return m.lockFn(name)
+	}
+	return &model.EventRelayCursor{EventRelayCursorID: 1, Name: name}, nil
+}
+func (m *mockEventRelayCursorRepo) Advance(id, lastEventID int64) error {
+	if m.advanceFn != nil {
+		return m.advanceFn(id, lastEventID)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: PersonalAccessTokenRepository
+// ---------------------------------------------------------------------------
+
+type mockPersonalAccessTokenRepo struct {
+	createFn              func(*model.PersonalAccessToken) (*model.PersonalAccessToken, error)
+	findByUserIDFn        func(int64) ([]model.PersonalAccessToken, error)
+	findByUUIDAndUserIDFn func(string, int64) (*model.PersonalAccessToken, error)
+	findByTokenHashFn     func(string) (*model.PersonalAccessToken, error)
+	countActiveFn         func(int64, time.Time) (int64, error)
+	revokeFn              func(int64, time.Time) error
+	touchLastUsedFn       func(int64, time.Time, time.Duration) error
+}
+
+func (m *mockPersonalAccessTokenRepo) WithTx(_ *gorm.DB) repository.PersonalAccessTokenRepository {
+	return m
+}
+func (m *mockPersonalAccessTokenRepo) Create(e *model.PersonalAccessToken) (*model.PersonalAccessToken, error) {
+	if m.createFn != nil {
+		return m.createFn(e)
+	}
+	return e, nil
+}
+func (m *mockPersonalAccessTokenRepo) CreateOrUpdate(e *model.PersonalAccessToken) (*model.PersonalAccessToken, error) {
+	return e, nil
+}
+func (m *mockPersonalAccessTokenRepo) FindAll(_ ...string) ([]model.PersonalAccessToken, error) {
+	return nil, nil
+}
+func (m *mockPersonalAccessTokenRepo) FindByUUID(_ any, _ ...string) (*model.PersonalAccessToken, error) {
+	return nil, nil
+}
+func (m *mockPersonalAccessTokenRepo) FindByUUIDs(_ []string, _ ...string) ([]model.PersonalAccessToken, error) {
+	return nil, nil
+}
+func (m *mockPersonalAccessTokenRepo) FindByID(_ any, _ ...string) (*model.PersonalAccessToken, error) {
+	return nil, nil
+}
+func (m *mockPersonalAccessTokenRepo) UpdateByUUID(_, _ any) (*model.PersonalAccessToken, error) {
+	return nil, nil
+}
+func (m *mockPersonalAccessTokenRepo) UpdateByID(_, _ any) (*model.PersonalAccessToken, error) {
+	return nil, nil
+}
+func (m *mockPersonalAccessTokenRepo) DeleteByUUID(_ any) error { return nil }
+func (m *mockPersonalAccessTokenRepo) DeleteByID(_ any) error   { return nil }
+func (m *mockPersonalAccessTokenRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.PersonalAccessToken], error) {
+	return nil, nil
+}
+func (m *mockPersonalAccessTokenRepo) FindByUserID(uid int64) ([]model.PersonalAccessToken, error) {
+	if m.findByUserIDFn != nil {
+		return m.findByUserIDFn(uid)
+	}
+	return nil, nil
+}
+func (m *mockPersonalAccessTokenRepo) FindByUUIDAndUserID(id string, uid int64) (*model.PersonalAccessToken, error) {
+	if m.findByUUIDAndUserIDFn != nil {
+		return m.findByUUIDAndUserIDFn(id, uid)
+	}
+	return nil, nil
+}
+func (m *mockPersonalAccessTokenRepo) FindByTokenHash(h string) (*model.PersonalAccessToken, error) {
+	if m.findByTokenHashFn != nil {
+		return m.findByTokenHashFn(h)
+	}
+	return nil, nil
+}
+func (m *mockPersonalAccessTokenRepo) CountActiveByUserID(uid int64, now time.Time) (int64, error) {
+	if m.countActiveFn != nil {
+		return m.countActiveFn(uid, now)
+	}
+	return 0, nil
+}
+func (m *mockPersonalAccessTokenRepo) Revoke(id int64, at time.Time) error {
+	if m.revokeFn != nil {
+		return m.revokeFn(id, at)
+	}
+	return nil
+}
+func (m *mockPersonalAccessTokenRepo) TouchLastUsed(id int64, at time.Time, minInterval time.Duration) error {
+	if m.touchLastUsedFn != nil {
+		return m.touchLastUsedFn(id, at, minInterval)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: PermissionGroupRepository
+// ---------------------------------------------------------------------------
+
+type mockPermissionGroupRepo struct {
+	createFn                 func(*model.PermissionGroup) (*model.PermissionGroup, error)
+	updateByUUIDFn           func(any, any) (*model.PermissionGroup, error)
+	findByUUIDAndTenantIDFn  func(uuid.UUID, int64) (*model.PermissionGroup, error)
+	findByUUIDsAndTenantIDFn func([]uuid.UUID, int64) ([]model.PermissionGroup, error)
+	findByNameFn             func(string, int64) (*model.PermissionGroup, error)
+	findPaginatedFn          func(repository.PermissionGroupRepositoryGetFilter) (*repository.PaginationResult[model.PermissionGroup], error)
+	addPermissionsFn         func(int64, []int64) error
+	removePermissionFn       func(int64, int64) (bool, error)
+	deleteFn                 func(uuid.UUID, int64) error
+}
+
+func (m *mockPermissionGroupRepo) WithTx(_ *gorm.DB) repository.PermissionGroupRepository {
+	return m
+}
+func (m *mockPermissionGroupRepo) Create(e *model.PermissionGroup) (*model.PermissionGroup, error) {
+	if m.createFn != nil {
+		return m.createFn(e)
+	}
+	return e, nil
+}
+func (m *mockPermissionGroupRepo) CreateOrUpdate(e *model.PermissionGroup) (*model.PermissionGroup, error) {
+	return e, nil
+}
+func (m *mockPermissionGroupRepo) FindAll(_ ...string) ([]model.PermissionGroup, error) {
+	return nil, nil
+}
+func (m *mockPermissionGroupRepo) FindByUUID(_ any, _ ...string) (*model.PermissionGroup, error) {
+	return nil, nil
+}
+func (m *mockPermissionGroupRepo) FindByUUIDs(_ []string, _ ...string) ([]model.PermissionGroup, error) {
+	return nil, nil
+}
+func (m *mockPermissionGroupRepo) FindByID(_ any, _ ...string) (*model.PermissionGroup, error) {
+	return nil, nil
+}
+func (m *mockPermissionGroupRepo) UpdateByUUID(id, data any) (*model.PermissionGroup, error) {
+	if m.updateByUUIDFn != nil {
+		return m.updateByUUIDFn(id, data)
+	}
+	return nil, nil
+}
+func (m *mockPermissionGroupRepo) UpdateByID(_, _ any) (*model.PermissionGroup, error) {
+	return nil, nil
+}
+func (m *mockPermissionGroupRepo) DeleteByUUID(_ any) error { return nil }
+func (m *mockPermissionGroupRepo) DeleteByID(_ any) error   { return nil }
+func (m *mockPermissionGroupRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.PermissionGroup], error) {
+	return nil, nil
+}
+func (m *mockPermissionGroupRepo) FindByUUIDAndTenantID(id uuid.UUID, tid int64, _ ...string) (*model.PermissionGroup, error) {
+	if m.findByUUIDAndTenantIDFn != nil {
+		return m.findByUUIDAndTenantIDFn(id, tid)
+	}
+	return nil, nil
+}
+func (m *mockPermissionGroupRepo) FindByUUIDsAndTenantID(ids []uuid.UUID, tid int64, _ ...string) ([]model.PermissionGroup, error) {
+	if m.findByUUIDsAndTenantIDFn != nil {
+		return m.findByUUIDsAndTenantIDFn(ids, tid)
+	}
+	return nil, nil
+}
+func (m *mockPermissionGroupRepo) FindByName(name string, tid int64) (*model.PermissionGroup, error) {
+	if m.findByNameFn != nil {
+		return m.findByNameFn(name, tid)
+	}
+	return nil, nil
+}
+func (m *mockPermissionGroupRepo) FindPaginated(f repository.PermissionGroupRepositoryGetFilter) (*repository.PaginationResult[model.PermissionGroup], error) {
+	if m.findPaginatedFn != nil {
+		return m.findPaginatedFn(f)
+	}
+	return &repository.PaginationResult[model.PermissionGroup]{}, nil
+}
+func (m *mockPermissionGroupRepo) AddPermissions(id int64, perms []int64) error {
+	if m.addPermissionsFn != nil {
+		return m.addPermissionsFn(id, perms)
+	}
+	return nil
+}
+func (m *mockPermissionGroupRepo) RemovePermission(id int64, perm int64) (bool, error) {
+	if m.removePermissionFn != nil {
+		return m.removePermissionFn(id, perm)
+	}
+	return true, nil
+}
+func (m *mockPermissionGroupRepo) DeleteByUUIDAndTenantID(id uuid.UUID, tid int64) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(id, tid)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: GroupRepository
+// ---------------------------------------------------------------------------
+
+type mockGroupRepo struct {
+	createFn                func(*model.Group) (*model.Group, error)
+	updateByUUIDFn          func(any, any) (*model.Group, error)
+	findByUUIDAndTenantIDFn func(uuid.UUID, int64) (*model.Group, error)
+	findByNameFn            func(string, int64) (*model.Group, error)
+	findPaginatedFn         func(repository.GroupRepositoryGetFilter) (*repository.PaginationResult[model.Group], error)
+	findMembersPaginatedFn  func(int64, int, int) (*repository.PaginationResult[model.User], error)
+	addMembersFn            func(int64, []int64) ([]int64, error)
+	removeMemberFn          func(int64, int64) (bool, error)
+	addRolesFn              func(int64, []int64) ([]int64, error)
+	removeRoleFn            func(int64, int64) (bool, error)
+	deleteFn                func(uuid.UUID, int64) error
+	findUUIDsByMemberFn     func(uuid.UUID, []uuid.UUID) ([]uuid.UUID, error)
+}
+
+func (m *mockGroupRepo) WithTx(_ *gorm.DB) repository.GroupRepository {
+	return m
+}
+func (m *mockGroupRepo) Create(e *model.Group) (*model.Group, error) {
+	if m.createFn != nil {
+		return m.createFn(e)
+	}
+	return e, nil
+}
+func (m *mockGroupRepo) CreateOrUpdate(e *model.Group) (*model.Group, error) {
+	return e, nil
+}
+func (m *mockGroupRepo) FindAll(_ ...string) ([]model.Group, error) {
+	return nil, nil
+}
+func (m *mockGroupRepo) FindByUUID(_ any, _ ...string) (*model.Group, error) {
+	return nil, nil
+}
+func (m *mockGroupRepo) FindByUUIDs(_ []string, _ ...string) ([]model.Group, error) {
+	return nil, nil
+}
+func (m *mockGroupRepo) FindByID(_ any, _ ...string) (*model.Group, error) {
+	return nil, nil
+}
+func (m *mockGroupRepo) UpdateByUUID(id, data any) (*model.Group, error) {
+	if m.updateByUUIDFn != nil {
+		return m.updateByUUIDFn(id, data)
+	}
+	return nil, nil
+}
+func (m *mockGroupRepo) UpdateByID(_, _ any) (*model.Group, error) {
+	return nil, nil
+}
+func (m *mockGroupRepo) DeleteByUUID(_ any) error { return nil }
+func (m *mockGroupRepo) DeleteByID(_ any) error   { return nil }
+func (m *mockGroupRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.Group], error) {
+	return nil, nil
+}
+func (m *mockGroupRepo) FindByUUIDAndTenantID(id uuid.UUID, tid int64, _ ...string) (*model.Group, error) {
+	if m.findByUUIDAndTenantIDFn != nil {
+		return m.findByUUIDAndTenantIDFn(id, tid)
+	}
+	return nil, nil
+}
+func (m *mockGroupRepo) FindByName(name string, tid int64) (*model.Group, error) {
+	if m.findByNameFn != nil {
+		return m.findByNameFn(name, tid)
+	}
+	return nil, nil
+}
+func (m *mockGroupRepo) FindPaginated(f repository.GroupRepositoryGetFilter) (*repository.PaginationResult[model.Group], error) {
+	if m.findPaginatedFn != nil {
+		return m.findPaginatedFn(f)
+	}
+	return &repository.PaginationResult[model.Group]{}, nil
+}
+func (m *mockGroupRepo) FindMembersPaginated(id int64, page, limit int) (*repository.PaginationResult[model.User], error) {
+	if m.findMembersPaginatedFn != nil {
+		return m.findMembersPaginatedFn(id, page, limit)
+	}
+	return &repository.PaginationResult[model.User]{}, nil
+}
+func (m *mockGroupRepo) AddMembers(id int64, userIDs []int64) ([]int64, error) {
+	if m.addMembersFn != nil {
+		return m.addMembersFn(id, userIDs)
+	}
+	return userIDs, nil
+}
+func (m *mockGroupRepo) RemoveMember(id int64, userID int64) (bool, error) {
+	if m.removeMemberFn != nil {
+		return m.removeMemberFn(id, userID)
+	}
+	return true, nil
+}
+func (m *mockGroupRepo) AddRoles(id int64, roleIDs []int64) ([]int64, error) {
+	if m.addRolesFn != nil {
+		return m.addRolesFn(id, roleIDs)
+	}
+	return roleIDs, nil
+}
+func (m *mockGroupRepo) RemoveRole(id int64, roleID int64) (bool, error) {
+	if m.removeRoleFn != nil {
+		return m.removeRoleFn(id, roleID)
+	}
+	return true, nil
+}
+func (m *mockGroupRepo) DeleteByUUIDAndTenantID(id uuid.UUID, tid int64) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(id, tid)
+	}
+	return nil
+}
+func (m *mockGroupRepo) FindUUIDsByMember(userUUID uuid.UUID, groupUUIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if m.findUUIDsByMemberFn != nil {
+		return m.findUUIDsByMemberFn(userUUID, groupUUIDs)
+	}
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: ScopedUserRoleRepository
+// ---------------------------------------------------------------------------
+
+type mockScopedUserRoleRepo struct {
+	createFn               func(*model.ScopedUserRole) (*model.ScopedUserRole, error)
+	deleteByUUIDFn         func(any) error
+	findByUserIDFn         func(int64) ([]model.ScopedUserRole, error)
+	findByUUIDAndUserIDFn  func(uuid.UUID, int64) (*model.ScopedUserRole, error)
+	findExistingFn         func(int64, int64, *int64, *int64) (*model.ScopedUserRole, error)
+	findPermissionGrantsFn func(int64) ([]repository.ScopedPermissionGrant, error)
+}
+
+func (m *mockScopedUserRoleRepo) WithTx(_ *gorm.DB) repository.ScopedUserRoleRepository {
+	return m
+}
+func (m *mockScopedUserRoleRepo) Create(e *model.ScopedUserRole) (*model.ScopedUserRole, error) {
+	if m.createFn != nil {
+		return m.createFn(e)
+	}
+	return e, nil
+}
+func (m *mockScopedUserRoleRepo) CreateOrUpdate(e *model.ScopedUserRole) (*model.ScopedUserRole, error) {
+	return e, nil
+}
+func (m *mockScopedUserRoleRepo) FindAll(_ ...string) ([]model.ScopedUserRole, error) {
+	return nil, nil
+}
+func (m *mockScopedUserRoleRepo) FindByUUID(_ any, _ ...string) (*model.ScopedUserRole, error) {
+	return nil, nil
+}
+func (m *mockScopedUserRoleRepo) FindByUUIDs(_ []string, _ ...string) ([]model.ScopedUserRole, error) {
+	return nil, nil
+}
+func (m *mockScopedUserRoleRepo) FindByID(_ any, _ ...string) (*model.ScopedUserRole, error) {
+	return nil, nil
+}
+func (m *mockScopedUserRoleRepo) UpdateByUUID(_, _ any) (*model.ScopedUserRole, error) {
+	return nil, nil
+}
+func (m *mockScopedUserRoleRepo) UpdateByID(_, _ any) (*model.ScopedUserRole, error) {
+	return nil, nil
+}
+func (m *mockScopedUserRoleRepo) DeleteByUUID(id any) error {
+	if m.deleteByUUIDFn != nil {
+		return m.deleteByUUIDFn(id)
+	}
+	return nil
+}
+func (m *mockScopedUserRoleRepo) DeleteByID(_ any) error { return nil }
+func (m *mockScopedUserRoleRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.ScopedUserRole], error) {
+	return nil, nil
+}
+func (m *mockScopedUserRoleRepo) FindByUserID(userID int64) ([]model.ScopedUserRole, error) {
+	if m.findByUserIDFn != nil {
+		return m.findByUserIDFn(userID)
+	}
+	return nil, nil
+}
+func (m *mockScopedUserRoleRepo) FindByUUIDAndUserID(id uuid.UUID, userID int64) (*model.ScopedUserRole, error) {
+	if m.findByUUIDAndUserIDFn != nil {
+		return m.findByUUIDAndUserIDFn(id, userID)
+	}
+	return nil, nil
+}
+func (m *mockScopedUserRoleRepo) FindExisting(userID int64, roleID int64, clientID *int64, groupID *int64) (*model.ScopedUserRole, error) {
+	if m.findExistingFn != nil {
+		return m.findExistingFn(userID, roleID, clientID, groupID)
+	}
+	return nil, nil
+}
+func (m *mockScopedUserRoleRepo) FindPermissionGrants(userID int64) ([]repository.ScopedPermissionGrant, error) {
+	if m.findPermissionGrantsFn != nil {
+		return m.findPermissionGrantsFn(userID)
+	}
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: TenantDeprovisionRepository
+// ---------------------------------------------------------------------------
+
+type mockTenantDeprovisionRepo struct {
+	findPendingByTokenHashFn func(tenantID int64, tokenHash string) (*model.TenantDeprovision, error)
+	supersedePendingFn       func(tenantID int64) error
+	createFn                 func(*model.TenantDeprovision) (*model.TenantDeprovision, error)
+	updateByUUIDFn           func(id, data any) (*model.TenantDeprovision, error)
+}
+
+func (m *mockTenantDeprovisionRepo) WithTx(_ *gorm.DB) repository.TenantDeprovisionRepository {
+	return m
+}
+func (m *mockTenantDeprovisionRepo) FindPendingByTokenHash(tenantID int64, tokenHash string) (*model.TenantDeprovision, error) {
+	if m.findPendingByTokenHashFn != nil {
+		return m.findPendingByTokenHashFn(tenantID, tokenHash)
+	}
+	return nil, nil
+}
+func (m *mockTenantDeprovisionRepo) SupersedePending(tenantID int64) error {
+	if m.supersedePendingFn != nil {
+		return m.supersedePendingFn(tenantID)
+	}
+	return nil
+}
+func (m *mockTenantDeprovisionRepo) Create(e *model.TenantDeprovision) (*model.TenantDeprovision, error) {
+	if m.createFn != nil {
+		return m.createFn(e)
+	}
+	e.TenantDeprovisionID = 1
+	e.TenantDeprovisionUUID = uuid.New()
+	return e, nil
+}
+func (m *mockTenantDeprovisionRepo) CreateOrUpdate(e *model.TenantDeprovision) (*model.TenantDeprovision, error) {
+	return e, nil
+}
+func (m *mockTenantDeprovisionRepo) FindAll(_ ...string) ([]model.TenantDeprovision, error) {
+	return nil, nil
+}
+func (m *mockTenantDeprovisionRepo) FindByUUID(_ any, _ ...string) (*model.TenantDeprovision, error) {
+	return nil, nil
+}
+func (m *mockTenantDeprovisionRepo) FindByUUIDs(_ []string, _ ...string) ([]model.TenantDeprovision, error) {
+	return nil, nil
+}
+func (m *mockTenantDeprovisionRepo) FindByID(_ any, _ ...string) (*model.TenantDeprovision, error) {
+	return nil, nil
+}
+func (m *mockTenantDeprovisionRepo) UpdateByUUID(id, data any) (*model.TenantDeprovision, error) {
+	if m.updateByUUIDFn != nil {
+		return m.updateByUUIDFn(id, data)
+	}
+	return nil, nil
+}
+func (m *mockTenantDeprovisionRepo) UpdateByID(_, _ any) (*model.TenantDeprovision, error) {
+	return nil, nil
+}
+func (m *mockTenantDeprovisionRepo) DeleteByUUID(_ any) error { return nil }
+func (m *mockTenantDeprovisionRepo) DeleteByID(_ any) error   { return nil }
+func (m *mockTenantDeprovisionRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.TenantDeprovision], error) {
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: UserImportJobRepository
+// ---------------------------------------------------------------------------
+
+type mockUserImportJobRepo struct {
+	findByUUIDAndTenantIDFn func(id string, tid int64) (*model.UserImportJob, error)
+	createFn                func(*model.UserImportJob) (*model.UserImportJob, error)
+	updateByIDFn            func(id, data any) (*model.UserImportJob, error)
+	updates                 []map[string]any
+}
+
+func (m *mockUserImportJobRepo) WithTx(_ *gorm.DB) repository.UserImportJobRepository { return m }
+func (m *mockUserImportJobRepo) FindByUUIDAndTenantID(id string, tid int64) (*model.UserImportJob, error) {
+	if m.findByUUIDAndTenantIDFn != nil {
+		return m.findByUUIDAndTenantIDFn(id, tid)
+	}
+	return nil, nil
+}
+func (m *mockUserImportJobRepo) Create(e *model.UserImportJob) (*model.UserImportJob, error) {
+	if m.createFn != nil {
+		return m.createFn(e)
+	}
+	e.UserImportJobID = 1
+	e.UserImportJobUUID = uuid.New()
+	return e, nil
+}
+func (m *mockUserImportJobRepo) CreateOrUpdate(e *model.UserImportJob) (*model.UserImportJob, error) {
+	return e, nil
+}
+func (m *mockUserImportJobRepo) FindAll(_ ...string) ([]model.UserImportJob, error) { return nil, nil }
+func (m *mockUserImportJobRepo) FindByUUID(_ any, _ ...string) (*model.UserImportJob, error) {
+	return nil, nil
+}
+func (m *mockUserImportJobRepo) FindByUUIDs(_ []string, _ ...string) ([]model.UserImportJob, error) {
+	return nil, nil
+}
+func (m *mockUserImportJobRepo) FindByID(_ any, _ ...string) (*model.UserImportJob, error) {
+	return nil, nil
+}
+func (m *mockUserImportJobRepo) UpdateByUUID(_, _ any) (*model.UserImportJob, error) { return nil, nil }
+func (m *mockUserImportJobRepo) UpdateByID(id, data any) (*model.UserImportJob, error) {
+	if updates, ok := data.(map[string]any); ok {
+		m.updates = append(m.updates, updates)
+	}
+	if m.updateByIDFn != nil {
+		return m.updateByIDFn(id, data)
+	}
+	return nil, nil
+}
+func (m *mockUserImportJobRepo) DeleteByUUID(_ any) error { return nil }
+func (m *mockUserImportJobRepo) DeleteByID(_ any) error   { return nil }
+func (m *mockUserImportJobRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.UserImportJob], error) {
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: SigningKeyRepository
+// ---------------------------------------------------------------------------
+
+// mockSigningKeyRepo keeps keys in memory so rotations can be followed
+// across calls. err, when set, fails every call.
+type mockSigningKeyRepo struct {
+	keys []*model.SigningKey
+	err  error
+}
+
+func (m *mockSigningKeyRepo) WithTx(_ *gorm.DB) repository.SigningKeyRepository { return m }
+func (m *mockSigningKeyRepo) find(match func(*model.SigningKey) bool) *model.SigningKey {
+	for _, key := range m.keys {
+		if match(key) {
+			return key
+		}
+	}
+	return nil
+}
+func (m *mockSigningKeyRepo) FindActive() (*model.SigningKey, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.find(func(k *model.SigningKey) bool { return k.Status == model.SigningKeyStatusActive }), nil
+}
+func (m *mockSigningKeyRepo) FindActiveForUpdate() (*model.SigningKey, error) { return m.FindActive() }
+func (m *mockSigningKeyRepo) FindUnretired() ([]model.SigningKey, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var keys []model.SigningKey
+	for i := len(m.keys) - 1; i >= 0; i-- {
+		if m.keys[i].Status != model.SigningKeyStatusRetired {
+			keys = append(keys, *m.keys[i])
+		}
+	}
+	return keys, nil
+}
+func (m *mockSigningKeyRepo) FindAllOrdered() ([]model.SigningKey, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	keys := make([]model.SigningKey, 0, len(m.keys))
+	for i := len(m.keys) - 1; i >= 0; i-- {
+		keys = append(keys, *m.keys[i])
+	}
+	return keys, nil
+}
+func (m *mockSigningKeyRepo) RetirePreviousRotatedBefore(cutoff time.Time) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	var count int64
+	for _, key := range m.keys {
+		if key.Status == model.SigningKeyStatusPrevious && key.RotatedAt != nil && key.RotatedAt.Before(cutoff) {
+			now := time.Now()
+			key.Status, key.RetiredAt, key.PrivateKey = model.SigningKeyStatusRetired, &now, nil
+			count++
+		}
+	}
+	return count, nil
+}
+func (m *mockSigningKeyRepo) Create(e *model.SigningKey) (*model.SigningKey, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	e.SigningKeyID = int64(len(m.keys) + 1)
+	e.SigningKeyUUID = uuid.New()
+	m.keys = append(m.keys, e)
+	return e, nil
+}
+func (m *mockSigningKeyRepo) CreateOrUpdate(e *model.SigningKey) (*model.SigningKey, error) {
+	return e, nil
+}
+func (m *mockSigningKeyRepo) FindAll(_ ...string) ([]model.SigningKey, error) { return nil, nil }
+func (m *mockSigningKeyRepo) FindByUUID(id any, _ ...string) (*model.SigningKey, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.find(func(k *model.SigningKey) bool { return k.SigningKeyUUID == id }), nil
+}
+func (m *mockSigningKeyRepo) FindByUUIDs(_ []string, _ ...string) ([]model.SigningKey, error) {
+	return nil, nil
+}
+func (m *mockSigningKeyRepo) FindByID(_ any, _ ...string) (*model.SigningKey, error) {
+	return nil, nil
+}
+func (m *mockSigningKeyRepo) UpdateByUUID(_, _ any) (*model.SigningKey, error) { return nil, nil }
+func (m *mockSigningKeyRepo) UpdateByID(id, data any) (*model.SigningKey, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	key := m.find(func(k *model.SigningKey) bool { return k.SigningKeyID == id })
+	for column, value := range data.(map[string]any) {
+		switch column {
+		case "status":
+			key.Status = value.(string)
+		case "rotated_at":
+			t := value.(time.Time)
+			key.RotatedAt = &t
+		case "retired_at":
+			t := value.(time.Time)
+			key.RetiredAt = &t
+		case "private_key":
+			key.PrivateKey = nil
+		}
+	}
+	return key, nil
+}
+func (m *mockSigningKeyRepo) DeleteByUUID(_ any) error { return nil }
+func (m *mockSigningKeyRepo) DeleteByID(_ any) error   { return nil }
+func (m *mockSigningKeyRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.SigningKey], error) {
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: LoginFingerprintRepository
+// ---------------------------------------------------------------------------
+
+type mockLoginFingerprintRepo struct {
+	createFn       func(*model.LoginFingerprint) (*model.LoginFingerprint, error)
+	updateByIDFn   func(any, any) (*model.LoginFingerprint, error)
+	findByDeviceFn func(int64, int64, string) (*model.LoginFingerprint, error)
+	findLatestFn   func(int64, int64) (*model.LoginFingerprint, error)
+}
+
+func (m *mockLoginFingerprintRepo) WithTx(_ *gorm.DB) repository.LoginFingerprintRepository {
+	return m
+}
+func (m *mockLoginFingerprintRepo) Create(e *model.LoginFingerprint) (*model.LoginFingerprint, error) {
+	if m.createFn != nil {
+		return m.createFn(e)
+	}
+	return e, nil
+}
+func (m *mockLoginFingerprintRepo) CreateOrUpdate(e *model.LoginFingerprint) (*model.LoginFingerprint, error) {
+	return e, nil
+}
+func (m *mockLoginFingerprintRepo) FindAll(_ ...string) ([]model.LoginFingerprint, error) {
+	return nil, nil
+}
+func (m *mockLoginFingerprintRepo) FindByUUID(_ any, _ ...string) (*model.LoginFingerprint, error) {
+	return nil, nil
+}
+func (m *mockLoginFingerprintRepo) FindByUUIDs(_ []string, _ ...string) ([]model.LoginFingerprint, error) {
+	return nil, nil
+}
+func (m *mockLoginFingerprintRepo) FindByID(_ any, _ ...string) (*model.LoginFingerprint, error) {
+	return nil, nil
+}
+func (m *mockLoginFingerprintRepo) UpdateByUUID(_, _ any) (*model.LoginFingerprint, error) {
+	return nil, nil
+}
+func (m *mockLoginFingerprintRepo) UpdateByID(id, data any) (*model.LoginFingerprint, error) {
+	if m.updateByIDFn != nil {
+		return m.updateByIDFn(id, data)
+	}
+	return nil, nil
+}
+func (m *mockLoginFingerprintRepo) DeleteByUUID(_ any) error { return nil }
+func (m *mockLoginFingerprintRepo) DeleteByID(_ any) error   { return nil }
+func (m *mockLoginFingerprintRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.LoginFingerprint], error) {
+	return nil, nil
+}
+func (m *mockLoginFingerprintRepo) FindByDevice(userID int64, tenantID int64, deviceHash string) (*model.LoginFingerprint, error) {
+	if m.findByDeviceFn != nil {
+		return m.findByDeviceFn(userID, tenantID, deviceHash)
+	}
+	return nil, nil
+}
+func (m *mockLoginFingerprintRepo) FindLatest(userID int64, tenantID int64) (*model.LoginFingerprint, error) {
+	if m.findLatestFn != nil {
+		return m.findLatestFn(userID, tenantID)
+	}
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: NotificationSettingRepository
+// ---------------------------------------------------------------------------
+
+type mockNotificationSettingRepo struct {
+	findByUserAndTenantFn func(int64, int64) (*model.NotificationSetting, error)
+	createOrUpdateFn      func(*model.NotificationSetting) (*model.NotificationSetting, error)
+}
+
+func (m *mockNotificationSettingRepo) WithTx(_ *gorm.DB) repository.NotificationSettingRepository {
+	return m
+}
+func (m *mockNotificationSettingRepo) Create(e *model.NotificationSetting) (*model.NotificationSetting, error) {
+	return e, nil
+}
+func (m *mockNotificationSettingRepo) CreateOrUpdate(e *model.NotificationSetting) (*model.NotificationSetting, error) {
+	if m.createOrUpdateFn != nil {
+		return m.createOrUpdateFn(e)
+	}
+	return e, nil
+}
+func (m *mockNotificationSettingRepo) FindAll(_ ...string) ([]model.NotificationSetting, error) {
+	return nil, nil
+}
+func (m *mockNotificationSettingRepo) FindByUUID(_ any, _ ...string) (*model.NotificationSetting, error) {
+	return nil, nil
+}
+func (m *mockNotificationSettingRepo) FindByUUIDs(_ []string, _ ...string) ([]model.NotificationSetting, error) {
+	return nil, nil
+}
+func (m *mockNotificationSettingRepo) FindByID(_ any, _ ...string) (*model.NotificationSetting, error) {
+	return nil, nil
+}
+func (m *mockNotificationSettingRepo) UpdateByUUID(_, _ any) (*model.NotificationSetting, error) {
+	return nil, nil
+}
+func (m *mockNotificationSettingRepo) UpdateByID(_, _ any) (*model.NotificationSetting, error) {
+	return nil, nil
+}
+func (m *mockNotificationSettingRepo) DeleteByUUID(_ any) error { return nil }
+func (m *mockNotificationSettingRepo) DeleteByID(_ any) error   { return nil }
+func (m *mockNotificationSettingRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.NotificationSetting], error) {
+	return nil, nil
+}
+func (m *mockNotificationSettingRepo) FindByUserAndTenant(userID int64, tenantID int64) (*model.NotificationSetting, error) {
+	if m.findByUserAndTenantFn != nil {
+		return m.findByUserAndTenantFn(userID, tenantID)
+	}
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: NotificationLogRepository
+// ---------------------------------------------------------------------------
+
+type mockNotificationLogRepo struct {
+	createFn        func(*model.NotificationLog) (*model.NotificationLog, error)
+	findPaginatedFn func(repository.NotificationLogRepositoryGetFilter) (*repository.PaginationResult[model.NotificationLog], error)
+}
+
+func (m *mockNotificationLogRepo) WithTx(_ *gorm.DB) repository.NotificationLogRepository {
+	return m
+}
+func (m *mockNotificationLogRepo) Create(e *model.NotificationLog) (*model.NotificationLog, error) {
+	if m.createFn != nil {
+		return m.createFn(e)
+	}
+	return e, nil
+}
+func (m *mockNotificationLogRepo) CreateOrUpdate(e *model.NotificationLog) (*model.NotificationLog, error) {
+	return e, nil
+}
+func (m *mockNotificationLogRepo) FindAll(_ ...string) ([]model.NotificationLog, error) {
+	return nil, nil
+}
+func (m *mockNotificationLogRepo) FindByUUID(_ any, _ ...string) (*model.NotificationLog, error) {
+	return nil, nil
+}
+func (m *mockNotificationLogRepo) FindByUUIDs(_ []string, _ ...string) ([]model.NotificationLog, error) {
+	return nil, nil
+}
+func (m *mockNotificationLogRepo) FindByID(_ any, _ ...string) (*model.NotificationLog, error) {
+	return nil, nil
+}
+func (m *mockNotificationLogRepo) UpdateByUUID(_, _ any) (*model.NotificationLog, error) {
+	return nil, nil
+}
+func (m *mockNotificationLogRepo) UpdateByID(_, _ any) (*model.NotificationLog, error) {
+	return nil, nil
+}
+func (m *mockNotificationLogRepo) DeleteByUUID(_ any) error { return nil }
+func (m *mockNotificationLogRepo) DeleteByID(_ any) error   { return nil }
+func (m *mockNotificationLogRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.NotificationLog], error) {
+	return nil, nil
+}
+func (m *mockNotificationLogRepo) FindPaginated(filter repository.NotificationLogRepositoryGetFilter) (*repository.PaginationResult[model.NotificationLog], error) {
+	if m.findPaginatedFn != nil {
+		return m.findPaginatedFn(filter)
+	}
+	return &repository.PaginationResult[model.NotificationLog]{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: TrustedDeviceRepository
+// ---------------------------------------------------------------------------
+
+type mockTrustedDeviceRepo struct {
+	createFn              func(*model.TrustedDevice) (*model.TrustedDevice, error)
+	findActiveByUserIDFn  func(int64, time.Time) ([]model.TrustedDevice, error)
+	findByUUIDAndUserIDFn func(string, int64) (*model.TrustedDevice, error)
+	findByTokenHashFn     func(string) (*model.TrustedDevice, error)
+	revokeFn              func(int64, time.Time) error
+	revokeByUserIDFn      func(int64, time.Time) (int64, error)
+	touchLastUsedFn       func(int64, time.Time) error
+}
+
+func (m *mockTrustedDeviceRepo) WithTx(_ *gorm.DB) repository.TrustedDeviceRepository {
+	return m
+}
+func (m *mockTrustedDeviceRepo) Create(e *model.TrustedDevice) (*model.TrustedDevice, error) {
+	if m.createFn != nil {
+		return m.createFn(e)
+	}
+	return e, nil
+}
+func (m *mockTrustedDeviceRepo) CreateOrUpdate(e *model.TrustedDevice) (*model.TrustedDevice, error) {
+	return e, nil
+}
+func (m *mockTrustedDeviceRepo) FindAll(_ ...string) ([]model.TrustedDevice, error) {
+	return nil, nil
+}
+func (m *mockTrustedDeviceRepo) FindByUUID(_ any, _ ...string) (*model.TrustedDevice, error) {
+	return nil, nil
+}
+func (m *mockTrustedDeviceRepo) FindByUUIDs(_ []string, _ ...string) ([]model.TrustedDevice, error) {
+	return nil, nil
+}
+func (m *mockTrustedDeviceRepo) FindByID(_ any, _ ...string) (*model.TrustedDevice, error) {
+	return nil, nil
+}
+func (m *mockTrustedDeviceRepo) UpdateByUUID(_, _ any) (*model.TrustedDevice, error) {
+	return nil, nil
+}
+func (m *mockTrustedDeviceRepo) UpdateByID(_, _ any) (*model.TrustedDevice, error) {
+	return nil, nil
+}
+func (m *mockTrustedDeviceRepo) DeleteByUUID(_ any) error { return nil }
+func (m *mockTrustedDeviceRepo) DeleteByID(_ any) error   { return nil }
+func (m *mockTrustedDeviceRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.TrustedDevice], error) {
+	return nil, nil
+}
+func (m *mockTrustedDeviceRepo) FindActiveByUserID(userID int64, now time.Time) ([]model.TrustedDevice, error) {
+	if m.findActiveByUserIDFn != nil {
+		return m.findActiveByUserIDFn(userID, now)
+	}
+	return nil, nil
+}
+func (m *mockTrustedDeviceRepo) FindByUUIDAndUserID(uuid string, userID int64) (*model.TrustedDevice, error) {
+	if m.findByUUIDAndUserIDFn != nil {
+		return m.findByUUIDAndUserIDFn(uuid, userID)
+	}
+	return nil, nil
+}
+func (m *mockTrustedDeviceRepo) FindByTokenHash(tokenHash string) (*model.TrustedDevice, error) {
+	if m.findByTokenHashFn != nil {
+		return m.findByTokenHashFn(tokenHash)
+	}
+	return nil, nil
+}
+func (m *mockTrustedDeviceRepo) Revoke(id int64, revokedAt time.Time) error {
+	if m.revokeFn != nil {
+		return m.revokeFn(id, revokedAt)
+	}
+	return nil
+}
+func (m *mockTrustedDeviceRepo) RevokeByUserID(userID int64, revokedAt time.Time) (int64, error) {
+	if m.revokeByUserIDFn != nil {
+		return m.revokeByUserIDFn(userID, revokedAt)
+	}
+	return 0, nil
+}
+func (m *mockTrustedDeviceRepo) TouchLastUsed(id int64, usedAt time.Time) error {
+	if m.touchLastUsedFn != nil {
+		return m.touchLastUsedFn(id, usedAt)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: DataKeyRepository
+// ---------------------------------------------------------------------------
+
+// mockDataKeyRepo keeps keys and encrypted column values in memory so
+// rotations and re-encryption can be followed across calls. values is keyed
+// by table.column, then by row ID.
+type mockDataKeyRepo struct {
+	keys   []*model.DataKey
+	values map[string]map[int64]string
+	err    error
+}
+
+func (m *mockDataKeyRepo) WithTx(_ *gorm.DB) repository.DataKeyRepository { return m }
+func (m *mockDataKeyRepo) find(match func(*model.DataKey) bool) *model.DataKey {
+	for _, key := range m.keys {
+		if match(key) {
+			return key
+		}
+	}
+	return nil
+}
+func (m *mockDataKeyRepo) FindByKeyID(keyID string) (*model.DataKey, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.find(func(k *model.DataKey) bool { return k.KeyID == keyID }), nil
+}
+func (m *mockDataKeyRepo) FindActiveForUpdate() (*model.DataKey, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.find(func(k *model.DataKey) bool { return k.Status == model.DataKeyStatusActive }), nil
+}
+func (m *mockDataKeyRepo) FindAllOrdered() ([]model.DataKey, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	keys := make([]model.DataKey, 0, len(m.keys))
+	for i := len(m.keys) - 1; i >= 0; i-- {
+		keys = append(keys, *m.keys[i])
+	}
+	return keys, nil
+}
+func (m *mockDataKeyRepo) FindValuesNotSealedWith(col repository.EncryptedColumn, prefix string, afterID int64, limit int) ([]repository.EncryptedValue, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	column := m.values[col.Table+"."+col.Column]
+	var ids []int64
+	for id, value := range column {
+		if id > afterID && value != "" && !strings.HasPrefix(value, prefix) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	result := make([]repository.EncryptedValue, len(ids))
+	for i, id := range ids {
+		result[i] = repository.EncryptedValue{ID: id, Value: column[id]}
+	}
+	return result, nil
+}
+func (m *mockDataKeyRepo) ReplaceValue(col repository.EncryptedColumn, id int64, old, value string) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	column := m.values[col.Table+"."+col.Column]
+	if column[id] != old {
+		return false, nil
+	}
+	column[id] = value
+	return true, nil
+}
+func (m *mockDataKeyRepo) Create(e *model.DataKey) (*model.DataKey, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	e.DataKeyID = int64(len(m.keys) + 1)
+	e.DataKeyUUID = uuid.New()
+	m.keys = append(m.keys, e)
+	return e, nil
+}
+func (m *mockDataKeyRepo) CreateOrUpdate(e *model.DataKey) (*model.DataKey, error) {
+	return e, nil
+}
+func (m *mockDataKeyRepo) FindAll(_ ...string) ([]model.DataKey, error) { return nil, nil }
+func (m *mockDataKeyRepo) FindByUUID(_ any, _ ...string) (*model.DataKey, error) {
+	return nil, nil
+}
+func (m *mockDataKeyRepo) FindByUUIDs(_ []string, _ ...string) ([]model.DataKey, error) {
+	return nil, nil
+}
+func (m *mockDataKeyRepo) FindByID(_ any, _ ...string) (*model.DataKey, error) {
+	return nil, nil
+}
+func (m *mockDataKeyRepo) UpdateByUUID(_, _ any) (*model.DataKey, error) { return nil, nil }
+func (m *mockDataKeyRepo) UpdateByID(id, data any) (*model.DataKey, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	key := m.find(func(k *model.DataKey) bool { return k.DataKeyID == id })
+	for column, value := range data.(map[string]any) {
+		switch column {
+		case "status":
+			key.Status = value.(string)
+		case "rotated_at":
+			t := value.(time.Time)
+			key.RotatedAt = &t
+		case "wrapped_key":
+			key.WrappedKey = value.(string)
+		}
+	}
+	return key, nil
+}
+func (m *mockDataKeyRepo) DeleteByUUID(_ any) error { return nil }
+func (m *mockDataKeyRepo) DeleteByID(_ any) error   { return nil }
+func (m *mockDataKeyRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.DataKey], error) {
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: TenantUsageRepository
+// ---------------------------------------------------------------------------
+
+type mockTenantUsageRepo struct {
+	addUsageFn                   func(*model.TenantUsage) error
+	findByTenantIDAndDateRangeFn func(tenantID int64, from, to time.Time) ([]model.TenantUsage, error)
+}
+
+func (m *mockTenantUsageRepo) WithTx(_ *gorm.DB) repository.TenantUsageRepository { return m }
+func (m *mockTenantUsageRepo) AddUsage(e *model.TenantUsage) error {
+	if m.addUsageFn != nil {
+		return m.addUsageFn(e)
+	}
+	return nil
+}
+func (m *mockTenantUsageRepo) FindByTenantIDAndDateRange(tenantID int64, from, to time.Time) ([]model.TenantUsage, error) {
+	if m.findByTenantIDAndDateRangeFn != nil {
+		return m.findByTenantIDAndDateRangeFn(tenantID, from, to)
+	}
+	return nil, nil
+}
+func (m *mockTenantUsageRepo) Create(e *model.TenantUsage) (*model.TenantUsage, error) {
+	return e, nil
+}
+func (m *mockTenantUsageRepo) CreateOrUpdate(e *model.TenantUsage) (*model.TenantUsage, error) {
+	return e, nil
+}
+func (m *mockTenantUsageRepo) FindAll(_ ...string) ([]model.TenantUsage, error) { return nil, nil }
+func (m *mockTenantUsageRepo) FindByUUID(_ any, _ ...string) (*model.TenantUsage, error) {
+	return nil, nil
+}
+func (m *mockTenantUsageRepo) FindByUUIDs(_ []string, _ ...string) ([]model.TenantUsage, error) {
+	return nil, nil
+}
+func (m *mockTenantUsageRepo) FindByID(_ any, _ ...string) (*model.TenantUsage, error) {
+	return nil, nil
+}
+func (m *mockTenantUsageRepo) UpdateByUUID(_, _ any) (*model.TenantUsage, error) { return nil, nil }
+func (m *mockTenantUsageRepo) UpdateByID(_, _ any) (*model.TenantUsage, error)   { return nil, nil }
+func (m *mockTenantUsageRepo) DeleteByUUID(_ any) error                          { return nil }
+func (m *mockTenantUsageRepo) DeleteByID(_ any) error                            { return nil }
+func (m *mockTenantUsageRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.TenantUsage], error) {
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: PlanRepository
+// ---------------------------------------------------------------------------
+
+type mockPlanRepo struct {
+	findAllOrderedFn func() ([]model.Plan, error)
+	findByUUIDFn     func(id any, preloads ...string) (*model.Plan, error)
+	findByNameFn     func(name string) (*model.Plan, error)
+	findDefaultFn    func() (*model.Plan, error)
+	unsetDefaultFn   func() error
+	countTenantsFn   func(planID int64) (int64, error)
+	createFn         func(e *model.Plan) (*model.Plan, error)
+	updateByUUIDFn   func(id, data any) (*model.Plan, error)
+	deleteByUUIDFn   func(id any) error
+}
+
+func (m *mockPlanRepo) WithTx(_ *gorm.DB) repository.PlanRepository { return m }
+func (m *mockPlanRepo) FindAllOrdered() ([]model.Plan, error) {
+	if m.findAllOrderedFn != nil {
+		return m.findAllOrderedFn()
+	}
+	return nil, nil
+}
+func (m *mockPlanRepo) FindByName(name string) (*model.Plan, error) {
+	if m.findByNameFn != nil {
+		return m.findByNameFn(name)
+	}
+	return nil, nil
+}
+func (m *mockPlanRepo) FindDefault() (*model.Plan, error) {
+	if m.findDefaultFn != nil {
+		return m.findDefaultFn()
+	}
+	return nil, nil
+}
+func (m *mockPlanRepo) UnsetDefault() error {
+	if m.unsetDefaultFn != nil {
+		return m.unsetDefaultFn()
+	}
+	return nil
+}
+func (m *mockPlanRepo) CountTenants(planID int64) (int64, error) {
+	if m.countTenantsFn != nil {
+		return m.countTenantsFn(planID)
+	}
+	return 0, nil
+}
+func (m *mockPlanRepo) Create(e *model.Plan) (*model.Plan, error) {
+	if m.createFn != nil {
+		return m.createFn(e)
+	}
+	return e, nil
+}
+func (m *mockPlanRepo) CreateOrUpdate(e *model.Plan) (*model.Plan, error) { return e, nil }
+func (m *mockPlanRepo) FindAll(_ ...string) ([]model.Plan, error)         { return nil, nil }
+func (m *mockPlanRepo) FindByUUID(id any, p ...string) (*model.Plan, error) {
+	if m.findByUUIDFn != nil {
+		return m.findByUUIDFn(id, p...)
+	}
+	return nil, nil
+}
+func (m *mockPlanRepo) FindByUUIDs(_ []string, _ ...string) ([]model.Plan, error) {
+	return nil, nil
+}
+func (m *mockPlanRepo) FindByID(_ any, _ ...string) (*model.Plan, error) { return nil, nil }
+func (m *mockPlanRepo) UpdateByUUID(id, data any) (*model.Plan, error) {
+	if m.updateByUUIDFn != nil {
+		return m.updateByUUIDFn(id, data)
+	}
+	return nil, nil
+}
+func (m *mockPlanRepo) UpdateByID(_, _ any) (*model.Plan, error) { return nil, nil }
+func (m *mockPlanRepo) DeleteByUUID(id any) error {
+	if m.deleteByUUIDFn != nil {
+		return m.deleteByUUIDFn(id)
+	}
+	return nil
+}
+func (m *mockPlanRepo) DeleteByID(_ any) error { return nil }
+func (m *mockPlanRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.Plan], error) {
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockFeatureFlagRepo
+// ---------------------------------------------------------------------------
+
+type mockFeatureFlagRepo struct {
+	findAllOrderedFn func() ([]model.FeatureFlag, error)
+	findByUUIDFn     func(id any, preloads ...string) (*model.FeatureFlag, error)
+	findByKeyFn      func(key string) (*model.FeatureFlag, error)
+	setOverrideFn    func(flagID, tenantID int64, enabled bool) error
+	deleteOverrideFn func(flagID, tenantID int64) (bool, error)
+	createFn         func(e *model.FeatureFlag) (*model.FeatureFlag, error)
+	updateByUUIDFn   func(id, data any) (*model.FeatureFlag, error)
+	deleteByUUIDFn   func(id any) error
+}
+
+func (m *mockFeatureFlagRepo) WithTx(_ *gorm.DB) repository.FeatureFlagRepository { return m }
+func (m *mockFeatureFlagRepo) FindAllOrdered() ([]model.FeatureFlag, error) {
+	if m.findAllOrderedFn != nil {
+		return m.findAllOrderedFn()
+	}
+	return nil, nil
+}
+func (m *mockFeatureFlagRepo) FindByKey(key string) (*model.FeatureFlag, error) {
+	if m.findByKeyFn != nil {
+		return m.findByKeyFn(key)
+	}
+	return nil, nil
+}
+func (m *mockFeatureFlagRepo) SetOverride(flagID, tenantID int64, enabled bool) error {
+	if m.setOverrideFn != nil {
+		return m.setOverrideFn(flagID, tenantID, enabled)
+	}
+	return nil
+}
+func (m *mockFeatureFlagRepo) DeleteOverride(flagID, tenantID int64) (bool, error) {
+	if m.deleteOverrideFn != nil {
+		return m.deleteOverrideFn(flagID, tenantID)
+	}
+	return true, nil
+}
+func (m *mockFeatureFlagRepo) Create(e *model.FeatureFlag) (*model.FeatureFlag, error) {
+	if m.createFn != nil {
+		return m.createFn(e)
+	}
+	return e, nil
+}
+func (m *mockFeatureFlagRepo) CreateOrUpdate(e *model.FeatureFlag) (*model.FeatureFlag, error) {
+	return e, nil
+}
+func (m *mockFeatureFlagRepo) FindAll(_ ...string) ([]model.FeatureFlag, error) { return nil, nil }
+func (m *mockFeatureFlagRepo) FindByUUID(id any, p ...string) (*model.FeatureFlag, error) {
+	if m.findByUUIDFn != nil {
+		return m.findByUUIDFn(id, p...)
+	}
+	return nil, nil
+}
+func (m *mockFeatureFlagRepo) FindByUUIDs(_ []string, _ ...string) ([]model.FeatureFlag, error) {
+	return nil, nil
+}
+func (m *mockFeatureFlagRepo) FindByID(_ any, _ ...string) (*model.FeatureFlag, error) {
+	return nil, nil
+}
+func (m *mockFeatureFlagRepo) UpdateByUUID(id, data any) (*model.FeatureFlag, error) {
+	if m.updateByUUIDFn != nil {
+		return m.updateByUUIDFn(id, data)
+	}
+	return nil, nil
+}
+func (m *mockFeatureFlagRepo) UpdateByID(_, _ any) (*model.FeatureFlag, error) { return nil, nil }
+func (m *mockFeatureFlagRepo) DeleteByUUID(id any) error {
+	if m.deleteByUUIDFn != nil {
+		return m.deleteByUUIDFn(id)
+	}
+	return nil
+}
+func (m *mockFeatureFlagRepo) DeleteByID(_ any) error { return nil }
+func (m *mockFeatureFlagRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.FeatureFlag], error) {
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: PolicyDocumentRepository
+// ---------------------------------------------------------------------------
+
+type mockPolicyDocumentRepo struct {
+	findByUUIDAndTenantFn     func(uuid.UUID, int64) (*model.PolicyDocument, error)
+	findByTypeAndVersionFn    func(int64, string, string) (*model.PolicyDocument, error)
+	findPaginatedFn           func(repository.PolicyDocumentRepositoryGetFilter) (*repository.PaginationResult[model.PolicyDocument], error)
+	findCurrentFn             func(int64) ([]model.PolicyDocument, error)
+	findAcceptedDocumentIDsFn func(int64, []int64) ([]int64, error)
+	acceptFn                  func([]model.PolicyAcceptance) error
+	findAcceptancesFn         func(repository.PolicyAcceptanceRepositoryGetFilter) (*repository.PaginationResult[model.PolicyAcceptance], error)
+	createFn                  func(*model.PolicyDocument) (*model.PolicyDocument, error)
+	createOrUpdateFn          func(*model.PolicyDocument) (*model.PolicyDocument, error)
+	deleteByUUIDFn            func(any) error
+}
+
+func (m *mockPolicyDocumentRepo) WithTx(_ *gorm.DB) repository.PolicyDocumentRepository {
+	return m
+}
+func (m *mockPolicyDocumentRepo) FindAll(_ ...string) ([]model.PolicyDocument, error) {
+	return nil, nil
+}
+func (m *mockPolicyDocumentRepo) FindByUUID(_ any, _ ...string) (*model.PolicyDocument, error) {
+	return nil, nil
+}
+func (m *mockPolicyDocumentRepo) FindByUUIDs(_ []string, _ ...string) ([]model.PolicyDocument, error) {
+	return nil, nil
+}
+func (m *mockPolicyDocumentRepo) FindByID(_ any, _ ...string) (*model.PolicyDocument, error) {
+	return nil, nil
+}
+func (m *mockPolicyDocumentRepo) UpdateByUUID(_, _ any) (*model.PolicyDocument, error) {
+	return nil, nil
+}
+func (m *mockPolicyDocumentRepo) UpdateByID(_, _ any) (*model.PolicyDocument, error) {
+	return nil, nil
+}
+func (m *mockPolicyDocumentRepo) DeleteByID(_ any) error { return nil }
+func (m *mockPolicyDocumentRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.PolicyDocument], error) {
+	return nil, nil
+}
+func (m *mockPolicyDocumentRepo) Create(e *model.PolicyDocument) (*model.PolicyDocument, error) {
+	if m.createFn != nil {
+		return m.createFn(e)
+	}
+	return e, nil
+}
+func (m *mockPolicyDocumentRepo) CreateOrUpdate(e *model.PolicyDocument) (*model.PolicyDocument, error) {
+	if m.createOrUpdateFn != nil {
+		return m.createOrUpdateFn(e)
+	}
+	return e, nil
+}
+func (m *mockPolicyDocumentRepo) DeleteByUUID(id any) error {
+	if m.deleteByUUIDFn != nil {
+		return m.deleteByUUIDFn(id)
+	}
+	return nil
+}
+func (m *mockPolicyDocumentRepo) FindByUUIDAndTenantID(id uuid.UUID, tid int64) (*model.PolicyDocument, error) {
+	if m.findByUUIDAndTenantFn != nil {
+		return m.findByUUIDAndTenantFn(id, tid)
+	}
+	return nil, nil
+}
+func (m *mockPolicyDocumentRepo) FindByTypeAndVersion(tid int64, docType, version string) (*model.PolicyDocument, error) {
+	if m.findByTypeAndVersionFn != nil {
+		return m.findByTypeAndVersionFn(tid, docType, version)
+	}
+	return nil, nil
+}
+func (m *mockPolicyDocumentRepo) FindPaginated(f repository.PolicyDocumentRepositoryGetFilter) (*repository.PaginationResult[model.PolicyDocument], error) {
+	if m.findPaginatedFn != nil {
+		return m.findPaginatedFn(f)
+	}
+	return &repository.PaginationResult[model.PolicyDocument]{}, nil
+}
+func (m *mockPolicyDocumentRepo) FindCurrent(tid int64) ([]model.PolicyDocument, error) {
+	if m.findCurrentFn != nil {
+		return m.findCurrentFn(tid)
+	}
+	return nil, nil
+}
+func (m *mockPolicyDocumentRepo) FindAcceptedDocumentIDs(userID int64, ids []int64) ([]int64, error) {
+	if m.findAcceptedDocumentIDsFn != nil {
+		return m.findAcceptedDocumentIDsFn(userID, ids)
+	}
+	return nil, nil
+}
+func (m *mockPolicyDocumentRepo) Accept(a []model.PolicyAcceptance) error {
+	if m.acceptFn != nil {
+		return m.acceptFn(a)
+	}
+	return nil
+}
+func (m *mockPolicyDocumentRepo) FindAcceptancesPaginated(f repository.PolicyAcceptanceRepositoryGetFilter) (*repository.PaginationResult[model.PolicyAcceptance], error) {
+	if m.findAcceptancesFn != nil {
+		return m.findAcceptancesFn(f)
+	}
+	return &repository.PaginationResult[model.PolicyAcceptance]{}, nil
+}
+
+// ---------------------------------------------------------------------------
+// mockParentalConsentRepo
+// ---------------------------------------------------------------------------
+
+type mockParentalConsentRepo struct {
+	created              []model.ParentalConsent
+	findPendingByTokenFn func(token string) (*model.ParentalConsent, error)
+	findPendingByUserFn  func(userID int64) (*model.ParentalConsent, error)
+	decideFn             func(id int64, status string, at time.Time, ip, ua *string) error
+}
+
+func (m *mockParentalConsentRepo) WithTx(_ *gorm.DB) repository.ParentalConsentRepository {
+	return m
+}
+func (m *mockParentalConsentRepo) Create(e *model.ParentalConsent) (*model.ParentalConsent, error) {
+	m.created = append(m.created, *e)
+	return e, nil
+}
+func (m *mockParentalConsentRepo) CreateOrUpdate(e *model.ParentalConsent) (*model.ParentalConsent, error) {
+	return e, nil
+}
+func (m *mockParentalConsentRepo) FindAll(_ ...string) ([]model.ParentalConsent, error) {
+	return nil, nil
+}
+func (m *mockParentalConsentRepo) FindByUUID(_ any, _ ...string) (*model.ParentalConsent, error) {
+	return nil, nil
+}
+func (m *mockParentalConsentRepo) FindByUUIDs(_ []string, _ ...string) ([]model.ParentalConsent, error) {
+	return nil, nil
+}
+func (m *mockParentalConsentRepo) FindByID(_ any, _ ...string) (*model.ParentalConsent, error) {
+	return nil, nil
+}
+func (m *mockParentalConsentRepo) UpdateByUUID(_, _ any) (*model.ParentalConsent, error) {
+	return nil, nil
+}
+func (m *mockParentalConsentRepo) UpdateByID(_, _ any) (*model.ParentalConsent, error) {
+	return nil, nil
+}
+func (m *mockParentalConsentRepo) DeleteByUUID(_ any) error { return nil }
+func (m *mockParentalConsentRepo) DeleteByID(_ any) error   { return nil }
+func (m *mockParentalConsentRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.ParentalConsent], error) {
+	return nil, nil
+}
+func (m *mockParentalConsentRepo) FindPendingByToken(token string) (*model.ParentalConsent, error) {
+	if m.findPendingByTokenFn != nil {
+		return m.findPendingByTokenFn(token)
+	}
+	return nil, nil
+}
+func (m *mockParentalConsentRepo) FindPendingByUserID(userID int64) (*model.ParentalConsent, error) {
+	if m.findPendingByUserFn != nil {
+		return m.findPendingByUserFn(userID)
+	}
+	return nil, nil
+}
+func (m *mockParentalConsentRepo) Decide(id int64, status string, at time.Time, ip, ua *string) error {
+	if m.decideFn != nil {
+		return m.decideFn(id, status, at, ip, ua)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: RecoveryCodeRepository
+// ---------------------------------------------------------------------------
+
+type mockRecoveryCodeRepo struct {
+	replaceByUserIDFn     func(userID int64, codeHashes []string) error
+	countUnusedByUserIDFn func(userID int64) (int64, error)
+	consumeFn             func(userID int64, codeHash string, usedAt time.Time) (bool, error)
+}
+
+func (m *mockRecoveryCodeRepo) WithTx(_ *gorm.DB) repository.RecoveryCodeRepository {
+	return m
+}
+func (m *mockRecoveryCodeRepo) Create(e *model.RecoveryCode) (*model.RecoveryCode, error) {
+	return e, nil
+}
+func (m *mockRecoveryCodeRepo) CreateOrUpdate(e *model.RecoveryCode) (*model.RecoveryCode, error) {
+	return e, nil
+}
+func (m *mockRecoveryCodeRepo) FindAll(_ ...string) ([]model.RecoveryCode, error) {
+	return nil, nil
+}
+func (m *mockRecoveryCodeRepo) FindByUUID(_ any, _ ...string) (*model.RecoveryCode, error) {
+	return nil, nil
+}
+func (m *mockRecoveryCodeRepo) FindByUUIDs(_ []string, _ ...string) ([]model.RecoveryCode, error) {
+	return nil, nil
+}
+func (m *mockRecoveryCodeRepo) FindByID(_ any, _ ...string) (*model.RecoveryCode, error) {
+	return nil, nil
+}
+func (m *mockRecoveryCodeRepo) UpdateByUUID(_, _ any) (*model.RecoveryCode, error) {
+	return nil, nil
+}
+func (m *mockRecoveryCodeRepo) UpdateByID(_, _ any) (*model.RecoveryCode, error) {
+	return nil, nil
+}
+func (m *mockRecoveryCodeRepo) DeleteByUUID(_ any) error { return nil }
+func (m *mockRecoveryCodeRepo) DeleteByID(_ any) error   { return nil }
+func (m *mockRecoveryCodeRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.RecoveryCode], error) {
+	return nil, nil
+}
+func (m *mockRecoveryCodeRepo) ReplaceByUserID(userID int64, codeHashes []string) error {
+	if m.replaceByUserIDFn != nil {
+		return m.replaceByUserIDFn(userID, codeHashes)
+	}
+	return nil
+}
+func (m *mockRecoveryCodeRepo) CountUnusedByUserID(userID int64) (int64, error) {
+	if m.countUnusedByUserIDFn != nil {
+		return m.countUnusedByUserIDFn(userID)
+	}
+	return 0, nil
+}
+func (m *mockRecoveryCodeRepo) Consume(userID int64, codeHash string, usedAt time.Time) (bool, error) {
+	if m.consumeFn != nil {
+		return m.consumeFn(userID, codeHash, usedAt)
+	}
+	return false, nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock: AuditArchiveRunRepository
+// ---------------------------------------------------------------------------
+
+type mockAuditArchiveRunRepo struct {
+	createFn                  func(e *model.AuditArchiveRun) (*model.AuditArchiveRun, error)
+	createOrUpdateFn          func(e *model.AuditArchiveRun) (*model.AuditArchiveRun, error)
+	findByUUIDAndTenantIDFn   func(uuid string, tenantID int64) (*model.AuditArchiveRun, error)
+	findPaginatedByTenantIDFn func(tenantID int64, page, limit int) (*repository.PaginationResult[model.AuditArchiveRun], error)
+	findRunningByTenantIDFn   func(tenantID int64, startedAfter time.Time) (*model.AuditArchiveRun, error)
+}
+
+func (m *mockAuditArchiveRunRepo) WithTx(_ *gorm.DB) repository.AuditArchiveRunRepository {
+	return m
+}
+func (m *mockAuditArchiveRunRepo) Create(e *model.AuditArchiveRun) (*model.AuditArchiveRun, error) {
+	if m.createFn != nil {
+		return m.createFn(e)
+	}
+	return e, nil
+}
+func (m *mockAuditArchiveRunRepo) CreateOrUpdate(e *model.AuditArchiveRun) (*model.AuditArchiveRun, error) {
+	if m.createOrUpdateFn != nil {
+		return m.createOrUpdateFn(e)
+	}
+	return e, nil
+}
+func (m *mockAuditArchiveRunRepo) FindAll(_ ...string) ([]model.AuditArchiveRun, error) {
+	return nil, nil
+}
+func (m *mockAuditArchiveRunRepo) FindByUUID(_ any, _ ...string) (*model.AuditArchiveRun, error) {
+	return nil, nil
+}
+func (m *mockAuditArchiveRunRepo) FindByUUIDs(_ []string, _ ...string) ([]model.AuditArchiveRun, error) {
+	return nil, nil
+}
+func (m *mockAuditArchiveRunRepo) FindByID(_ any, _ ...string) (*model.AuditArchiveRun, error) {
+	return nil, nil
+}
+func (m *mockAuditArchiveRunRepo) UpdateByUUID(_, _ any) (*model.AuditArchiveRun, error) {
+	return nil, nil
+}
+func (m *mockAuditArchiveRunRepo) UpdateByID(_, _ any) (*model.AuditArchiveRun, error) {
+	return nil, nil
+}
+func (m *mockAuditArchiveRunRepo) DeleteByUUID(_ any) error { return nil }
+func (m *mockAuditArchiveRunRepo) DeleteByID(_ any) error   { return nil }
+func (m *mockAuditArchiveRunRepo) Paginate(_ map[string]any, _, _ int, _ ...string) (*repository.PaginationResult[model.AuditArchiveRun], error) {
+	return nil, nil
+}
+func (m *mockAuditArchiveRunRepo) FindByUUIDAndTenantID(uuid string, tenantID int64) (*model.AuditArchiveRun, error) {
+	if m.findByUUIDAndTenantIDFn != nil {
+		return m.findByUUIDAndTenantIDFn(uuid, tenantID)
+	}
+	return nil, nil
+}
+func (m *mockAuditArchiveRunRepo) FindPaginatedByTenantID(tenantID int64, page, limit int) (*repository.PaginationResult[model.AuditArchiveRun], error) {
+	if m.findPaginatedByTenantIDFn != nil {
+		return m.findPaginatedByTenantIDFn(tenantID, page, limit)
+	}
+	return &repository.PaginationResult[model.AuditArchiveRun]{}, nil
+}
+func (m *mockAuditArchiveRunRepo) FindRunningByTenantID(tenantID int64, startedAfter time.Time) (*model.AuditArchiveRun, error) {
+	if m.findRunningByTenantIDFn != nil {
+		return m.findRunningByTenantIDFn(tenantID, startedAfter)
+	}
+	return nil, nil
+}
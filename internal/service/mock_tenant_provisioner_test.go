@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+	"gorm.io/gorm"
+)
+
+// mockTenantProvisioner is a test double for TenantProvisioner.
+type mockTenantProvisioner struct {
+	provisionFn          func(ctx context.Context, tx *gorm.DB, tenantID int64) error
+	deprovisionFn        func(ctx context.Context, tx *gorm.DB, tenant *model.Tenant) error
+	requestDeprovisionFn func(ctx context.Context, tenantUUID uuid.UUID, userID int64, mode string) (*TenantDeprovisionRequestResult, error)
+	confirmDeprovisionFn func(ctx context.Context, tenantUUID uuid.UUID, userID int64, token string) (*TenantDeprovisionResult, error)
+}
+
+func (m *mockTenantProvisioner) Provision(ctx context.Context, tx *gorm.DB, tenantID int64) error {
+	if m.provisionFn != nil {
+		return m.provisionFn(ctx, tx, tenantID)
+	}
+	return nil
+}
+
+func (m *mockTenantProvisioner) Deprovision(ctx context.Context, tx *gorm.DB, tenant *model.Tenant) error {
+	if m.deprovisionFn != nil {
+		return m.deprovisionFn(ctx, tx, tenant)
+	}
+	return nil
+}
+
+func (m *mockTenantProvisioner) RequestDeprovision(ctx context.Context, tenantUUID uuid.UUID, userID int64, mode string) (*TenantDeprovisionRequestResult, error) {
+	if m.requestDeprovisionFn != nil {
+		return m.requestDeprovisionFn(ctx, tenantUUID, userID, mode)
+	}
+	return nil, nil
+}
+
+func (m *mockTenantProvisioner) ConfirmDeprovision(ctx context.Context, tenantUUID uuid.UUID, userID int64, token string) (*TenantDeprovisionResult, error) {
+	if m.confirmDeprovisionFn != nil {
+		return m.confirmDeprovisionFn(ctx, tenantUUID, userID, token)
+	}
+	return nil, nil
+}
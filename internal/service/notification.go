@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/datatypes"
+)
+
+// Error reasons of notification deliveries. SMS deliveries are recorded but
+// not sent until an SMS provider is wired in.
+const (
+	errEmailDeliveryFailed = "email_delivery_failed"
+	errSMSUnavailable      = "sms_provider_unavailable"
+)
+
+// NotifyInput is a notification for one user.
+type NotifyInput struct {
+	TenantID int64
+	User     *model.User
+	// Type identifies the notification, such as "security.new_device".
+	// Users can mute a type unless the notification is Mandatory.
+	Type string
+	// Mandatory notifications, such as security alerts, are always emailed
+	// whatever the user's preferences. Other channels still follow them.
+	Mandatory bool
+	// Title is the email subject in the log and the in-app title.
+	Title string
+	// Message is the plain text sent by SMS and shown in-app. Without it
+	// those channels are skipped.
+	Message string
+	// EmailTemplate names the email template rendered with EmailData.
+	// Without it the email channel is skipped.
+	EmailTemplate string
+	EmailData     any
+}
+
+// NotificationSettingInput changes a user's notification preferences. Nil
+// fields are left as they are.
+type NotificationSettingInput struct {
+	EmailEnabled *bool
+	SMSEnabled   *bool
+	InAppEnabled *bool
+	MutedTypes   *[]string
+}
+
+// NotificationSettingServiceDataResult is a user's notification preferences.
+type NotificationSettingServiceDataResult struct {
+	EmailEnabled bool
+	SMSEnabled   bool
+	InAppEnabled bool
+	MutedTypes   []string
+}
+
+// NotificationLogServiceDataResult is one notification delivery.
+type NotificationLogServiceDataResult struct {
+	NotificationLogUUID uuid.UUID
+	UserUUID            uuid.UUID
+	NotificationType    string
+	Channel             string
+	Recipient           *string
+	Title               *string
+	Message             *string
+	Status              string
+	ErrorReason         *string
+	CreatedAt           time.Time
+}
+
+// NotificationService delivers notifications to users on the channels they
+// chose and keeps the delivery history.
+type NotificationService interface {
+	// Notify delivers a notification to the user on each enabled channel and
+	// records every delivery. Failures are recorded, never returned, so a
+	// notification cannot break the flow that sent it.
+	Notify(ctx context.Context, input NotifyInput)
+
+	// GetSettings returns the user's notification preferences in the tenant.
+	GetSettings(ctx context.Context, tenantID int64, userUUID uuid.UUID) (*NotificationSettingServiceDataResult, error)
+
+	// UpdateSettings changes the user's notification preferences in the
+	// tenant.
+	UpdateSettings(ctx context.Context, tenantID int64, userUUID uuid.UUID, input NotificationSettingInput) (*NotificationSettingServiceDataResult, error)
+
+	// FindLogs returns a page of the tenant's delivery history. A non-nil
+	// userUUID limits it to that user's deliveries.
+	FindLogs(ctx context.Context, userUUID *uuid.UUID, filter repository.NotificationLogRepositoryGetFilter) (*repository.PaginationResult[NotificationLogServiceDataResult], error)
+}
+
+type notificationService struct {
+	notificationSettingRepo repository.NotificationSettingRepository
+	notificationLogRepo     repository.NotificationLogRepository
+	userRepo                repository.UserRepository
+	emailTemplateRepo       repository.EmailTemplateRepository
+}
+
+// NewNotificationService creates a NotificationService.
+func NewNotificationService(
+	notificationSettingRepo repository.NotificationSettingRepository,
+	notificationLogRepo repository.NotificationLogRepository,
+	userRepo repository.UserRepository,
+	emailTemplateRepo repository.EmailTemplateRepository,
+) NotificationService {
+	return &notificationService{
+		notificationSettingRepo: notificationSettingRepo,
+		notificationLogRepo:     notificationLogRepo,
+		userRepo:                userRepo,
+		emailTemplateRepo:       emailTemplateRepo,
+	}
+}
+
+func (s *notificationService) Notify(ctx context.Context, input NotifyInput) {
+	ctx, span := otel.Tracer("service").Start(ctx, "notification.notify")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("notification.type", input.Type),
+		attribute.String("user.uuid", input.User.UserUUID.String()),
+		attribute.Int64("tenant.id", input.TenantID),
+	)
+
+	// Without the preferences only mandatory notifications go out
+	setting, err := s.findSetting(input.User.UserID, input.TenantID)
+	if err != nil {
+		span.RecordError(err)
+		setting = &model.NotificationSetting{}
+	}
+	if !input.Mandatory && slices.Contains(mutedTypes(setting), input.Type) {
+		span.SetStatus(codes.Ok, "muted")
+		return
+	}
+
+	if input.EmailTemplate != "" && input.User.Email != "" && (input.Mandatory || setting.EmailEnabled) {
+		var errorReason *string
+		status := model.NotificationStatusSent
+		if err := sendTemplateEmail(ctx, s.emailTemplateRepo, input.User.Email, input.EmailTemplate, input.EmailData); err != nil {
+			span.RecordError(err)
+			status, errorReason = model.NotificationStatusFailed, ptr.PtrOrNil(errEmailDeliveryFailed)
+			security.LogSecurityEvent(security.SecurityEvent{
+				EventType: "notification_failure",
+				UserID:    input.User.UserUUID.String(),
+				Details:   fmt.Sprintf("Failed to email %s notification: %v", input.Type, err),
+				Severity:  "HIGH",
+				Timestamp: time.Now(),
+			})
+		}
+		s.record(ctx, input, model.NotificationChannelEmail, input.User.Email, "", status, errorReason)
+	}
+
+	if input.Message == "" {
+		span.SetStatus(codes.Ok, "")
+		return
+	}
+	if setting.SMSEnabled && input.User.Phone != "" {
+		s.record(ctx, input, model.NotificationChannelSMS, input.User.Phone, input.Message, model.NotificationStatusSkipped, ptr.PtrOrNil(errSMSUnavailable))
+	}
+	if setting.InAppEnabled {
+		s.record(ctx, input, model.NotificationChannelInApp, "", input.Message, model.NotificationStatusSent, nil)
+	}
+	span.SetStatus(codes.Ok, "")
+}
+
+// record writes a delivery to the notification log.
+func (s *notificationService) record(ctx context.Context, input NotifyInput, channel, recipient, message, status string, errorReason *string) {
+	if _, err := s.notificationLogRepo.Create(&model.NotificationLog{
+		TenantID:         input.TenantID,
+		UserID:           input.User.UserID,
+		NotificationType: input.Type,
+		Channel:          channel,
+		Recipient:        ptr.PtrOrNil(recipient),
+		Title:            ptr.PtrOrNil(input.Title),
+		Message:          ptr.PtrOrNil(message),
+		Status:           status,
+		ErrorReason:      errorReason,
+	}); err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "notification_log_failure",
+			UserID:    input.User.UserUUID.String(),
+			Details:   fmt.Sprintf("Failed to record %s notification: %v", input.Type, err),
+			Timestamp: time.Now(),
+		})
+	}
+}
+
+func (s *notificationService) GetSettings(ctx context.Context, tenantID int64, userUUID uuid.UUID) (*NotificationSettingServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "notification.getSettings")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", userUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	user, err := findTenantUser(s.userRepo, userUUID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find user failed")
+		return nil, err
+	}
+	setting, err := s.findSetting(user.UserID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find notification setting failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toNotificationSettingServiceDataResult(setting), nil
+}
+
+func (s *notificationService) UpdateSettings(ctx context.Context, tenantID int64, userUUID uuid.UUID, input NotificationSettingInput) (*NotificationSettingServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "notification.updateSettings")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", userUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	user, err := findTenantUser(s.userRepo, userUUID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find user failed")
+		return nil, err
+	}
+	setting, err := s.findSetting(user.UserID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find notification setting failed")
+		return nil, err
+	}
+
+	if input.EmailEnabled != nil {
+		setting.EmailEnabled = *input.EmailEnabled
+	}
+	if input.SMSEnabled != nil {
+		setting.SMSEnabled = *input.SMSEnabled
+	}
+	if input.InAppEnabled != nil {
+		setting.InAppEnabled = *input.InAppEnabled
+	}
+	if input.MutedTypes != nil {
+		muted, err := json.Marshal(*input.MutedTypes)
+		if err != nil {
+			return nil, apperror.NewInternal("failed to encode muted notification types", err)
+		}
+		setting.MutedTypes = datatypes.JSON(muted)
+	}
+
+	// Save writes the booleans even when they are false, which Updates
+	// with a struct skips
+	if setting, err = s.notificationSettingRepo.CreateOrUpdate(setting); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "save notification setting failed")
+		return nil, apperror.NewInternal("failed to save notification settings", err)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toNotificationSettingServiceDataResult(setting), nil
+}
+
+func (s *notificationService) FindLogs(ctx context.Context, userUUID *uuid.UUID, filter repository.NotificationLogRepositoryGetFilter) (*repository.PaginationResult[NotificationLogServiceDataResult], error) {
+	_, span := otel.Tracer("service").Start(ctx, "notification.findLogs")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", filter.TenantID))
+
+	if userUUID != nil {
+		user, err := findTenantUser(s.userRepo, *userUUID, filter.TenantID)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "find user failed")
+			return nil, err
+		}
+		filter.UserID = &user.UserID
+	}
+
+	result, err := s.notificationLogRepo.FindPaginated(filter)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find notification logs failed")
+		return nil, apperror.NewInternal("failed to load notification logs", err)
+	}
+
+	data := make([]NotificationLogServiceDataResult, len(result.Data))
+	for i := range result.Data {
+		data[i] = toNotificationLogServiceDataResult(&result.Data[i])
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return &repository.PaginationResult[NotificationLogServiceDataResult]{
+		Data:       data,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}, nil
+}
+
+// findSetting returns the user's notification preferences in the tenant,
+// or the defaults when the user has not changed them.
+func (s *notificationService) findSetting(userID, tenantID int64) (*model.NotificationSetting, error) {
+	setting, err := s.notificationSettingRepo.FindByUserAndTenant(userID, tenantID)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to load notification settings", err)
+	}
+	if setting == nil {
+		return model.DefaultNotificationSetting(userID, tenantID), nil
+	}
+	return setting, nil
+}
+
+// mutedTypes returns the notification types the user opted out of.
+func mutedTypes(setting *model.NotificationSetting) []string {
+	var muted []string
+	if len(setting.MutedTypes) > 0 {
+		_ = json.Unmarshal(setting.MutedTypes, &muted)
+	}
+	return muted
+}
+
+func toNotificationSettingServiceDataResult(setting *model.NotificationSetting) *NotificationSettingServiceDataResult {
+	muted := mutedTypes(setting)
+	if muted == nil {
+		muted = []string{}
+	}
+	return &NotificationSettingServiceDataResult{
+		EmailEnabled: setting.EmailEnabled,
+		SMSEnabled:   setting.SMSEnabled,
+		InAppEnabled: setting.InAppEnabled,
+		MutedTypes:   muted,
+	}
+}
+
+func toNotificationLogServiceDataResult(log *model.NotificationLog) NotificationLogServiceDataResult {
+	var userUUID uuid.UUID
+	if log.User != nil {
+		userUUID = log.User.UserUUID
+	}
+	return NotificationLogServiceDataResult{
+		NotificationLogUUID: log.NotificationLogUUID,
+		UserUUID:            userUUID,
+		NotificationType:    log.NotificationType,
+		Channel:             log.Channel,
+		Recipient:           log.Recipient,
+		Title:               log.Title,
+		Message:             log.Message,
+		Status:              log.Status,
+		ErrorReason:         log.ErrorReason,
+		CreatedAt:           log.CreatedAt,
+	}
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/email"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+// newNotifiedUser returns a user of tenant 1 with an email and a phone.
+func newNotifiedUser() *model.User {
+	return &model.User{
+		UserID:         7,
+		UserUUID:       uuid.New(),
+		Email:          "jane@example.com",
+		Phone:          "+15550100",
+		UserIdentities: []model.UserIdentity{{TenantID: 1}},
+	}
+}
+
+// notificationSettingRepo returns a setting repo that finds setting for any
+// user.
+func notificationSettingRepo(setting *model.NotificationSetting) *mockNotificationSettingRepo {
+	return &mockNotificationSettingRepo{findByUserAndTenantFn: func(int64, int64) (*model.NotificationSetting, error) {
+		return setting, nil
+	}}
+}
+
+// notificationLogRepo returns a log repo that records the deliveries it
+// creates in logged.
+func notificationLogRepo(logged *[]model.NotificationLog) *mockNotificationLogRepo {
+	return &mockNotificationLogRepo{createFn: func(e *model.NotificationLog) (*model.NotificationLog, error) {
+		*logged = append(*logged, *e)
+		return e, nil
+	}}
+}
+
+// newNotificationService returns a NotificationService that finds user.
+func newNotificationService(user *model.User, settingRepo *mockNotificationSettingRepo, logRepo *mockNotificationLogRepo) NotificationService {
+	userRepo := &mockUserRepo{findByUUIDFn: func(id any, _ ...string) (*model.User, error) {
+		if id == user.UserUUID {
+			return user, nil
+		}
+		return nil, nil
+	}}
+	emailTemplateRepo := &mockEmailTemplateRepo{findByNameFn: func(name string) (*model.EmailTemplate, error) {
+		return &model.EmailTemplate{Name: name, Subject: "Heads up", BodyHTML: "{{.Name}}"}, nil
+	}}
+	return NewNotificationService(settingRepo, logRepo, userRepo, emailTemplateRepo)
+}
+
+func newNotifyInput(user *model.User, mandatory bool) NotifyInput {
+	return NotifyInput{
+		TenantID:      1,
+		User:          user,
+		Type:          "account.updated",
+		Mandatory:     mandatory,
+		Title:         "Account updated",
+		Message:       "Your account was updated.",
+		EmailTemplate: "internal:user:account:updated",
+		EmailData:     map[string]string{"Name": "Jane"},
+	}
+}
+
+// deliveryChannels returns the channel and status of each logged delivery.
+func deliveryChannels(logged []model.NotificationLog) map[string]string {
+	channels := map[string]string{}
+	for _, l := range logged {
+		channels[l.Channel] = l.Status
+	}
+	return channels
+}
+
+func TestNotificationService_Notify(t *testing.T) {
+	origSendEmail := email.SendEmail
+	defer func() { email.SendEmail = origSendEmail }()
+	var sent []email.SendEmailParams
+	var sendErr error
+	email.SendEmail = func(_ context.Context, p email.SendEmailParams) error {
+		sent = append(sent, p)
+		return sendErr
+	}
+
+	t.Run("no preferences → email and in-app", func(t *testing.T) {
+		sent, sendErr = nil, nil
+		user := newNotifiedUser()
+		var logged []model.NotificationLog
+		newNotificationService(user, notificationSettingRepo(nil), notificationLogRepo(&logged)).Notify(context.Background(), newNotifyInput(user, false))
+
+		require.Len(t, sent, 1)
+		assert.Equal(t, "jane@example.com", sent[0].To)
+		assert.Equal(t, map[string]string{
+			model.NotificationChannelEmail: model.NotificationStatusSent,
+			model.NotificationChannelInApp: model.NotificationStatusSent,
+		}, deliveryChannels(logged))
+		for _, l := range logged {
+			assert.Equal(t, int64(1), l.TenantID)
+			assert.Equal(t, int64(7), l.UserID)
+			assert.Equal(t, "account.updated", l.NotificationType)
+			assert.Equal(t, "Account updated", ptr.Deref(l.Title))
+		}
+	})
+
+	t.Run("user's channels → followed", func(t *testing.T) {
+		sent, sendErr = nil, nil
+		user := newNotifiedUser()
+		var logged []model.NotificationLog
+		settingRepo := notificationSettingRepo(&model.NotificationSetting{SMSEnabled: true})
+		newNotificationService(user, settingRepo, notificationLogRepo(&logged)).Notify(context.Background(), newNotifyInput(user, false))
+
+		assert.Empty(t, sent)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.NotificationChannelSMS, logged[0].Channel)
+		assert.Equal(t, model.NotificationStatusSkipped, logged[0].Status)
+		assert.Equal(t, errSMSUnavailable, ptr.Deref(logged[0].ErrorReason))
+		assert.Equal(t, "+15550100", ptr.Deref(logged[0].Recipient))
+	})
+
+	t.Run("muted type → skipped", func(t *testing.T) {
+		sent, sendErr = nil, nil
+		user := newNotifiedUser()
+		setting := model.DefaultNotificationSetting(7, 1)
+		setting.MutedTypes = datatypes.JSON(`["account.updated"]`)
+		var logged []model.NotificationLog
+		newNotificationService(user, notificationSettingRepo(setting), notificationLogRepo(&logged)).Notify(context.Background(), newNotifyInput(user, false))
+
+		assert.Empty(t, sent)
+		assert.Empty(t, logged)
+	})
+
+	t.Run("mandatory notification → always emailed", func(t *testing.T) {
+		sent, sendErr = nil, nil
+		user := newNotifiedUser()
+		settingRepo := notificationSettingRepo(&model.NotificationSetting{MutedTypes: datatypes.JSON(`["account.updated"]`)})
+		var logged []model.NotificationLog
+		newNotificationService(user, settingRepo, notificationLogRepo(&logged)).Notify(context.Background(), newNotifyInput(user, true))
+
+		require.Len(t, sent, 1)
+		assert.Equal(t, map[string]string{model.NotificationChannelEmail: model.NotificationStatusSent}, deliveryChannels(logged))
+	})
+
+	t.Run("preferences unavailable → mandatory notification sent", func(t *testing.T) {
+		sent, sendErr = nil, nil
+		user := newNotifiedUser()
+		settingRepo := &mockNotificationSettingRepo{findByUserAndTenantFn: func(int64, int64) (*model.NotificationSetting, error) {
+			return nil, errors.New("db down")
+		}}
+		var logged []model.NotificationLog
+		newNotificationService(user, settingRepo, notificationLogRepo(&logged)).Notify(context.Background(), newNotifyInput(user, true))
+
+		assert.Len(t, sent, 1)
+		assert.Equal(t, map[string]string{model.NotificationChannelEmail: model.NotificationStatusSent}, deliveryChannels(logged))
+	})
+
+	t.Run("email failure → recorded", func(t *testing.T) {
+		sent, sendErr = nil, errors.New("smtp: connection refused")
+		user := newNotifiedUser()
+		var logged []model.NotificationLog
+		newNotificationService(user, notificationSettingRepo(nil), notificationLogRepo(&logged)).Notify(context.Background(), newNotifyInput(user, false))
+
+		assert.Equal(t, model.NotificationStatusFailed, deliveryChannels(logged)[model.NotificationChannelEmail])
+		for _, l := range logged {
+			if l.Channel == model.NotificationChannelEmail {
+				assert.Equal(t, errEmailDeliveryFailed, ptr.Deref(l.ErrorReason))
+			}
+		}
+	})
+
+	t.Run("log failure → still sent", func(t *testing.T) {
+		sent, sendErr = nil, nil
+		user := newNotifiedUser()
+		logRepo := &mockNotificationLogRepo{createFn: func(*model.NotificationLog) (*model.NotificationLog, error) {
+			return nil, errors.New("db down")
+		}}
+		newNotificationService(user, notificationSettingRepo(nil), logRepo).Notify(context.Background(), newNotifyInput(user, false))
+
+		assert.Len(t, sent, 1)
+	})
+}
+
+func TestNotificationService_Settings(t *testing.T) {
+	t.Run("no settings → defaults", func(t *testing.T) {
+		user := newNotifiedUser()
+		svc := newNotificationService(user, notificationSettingRepo(nil), &mockNotificationLogRepo{})
+
+		result, err := svc.GetSettings(context.Background(), 1, user.UserUUID)
+		require.NoError(t, err)
+		assert.Equal(t, &NotificationSettingServiceDataResult{EmailEnabled: true, InAppEnabled: true, MutedTypes: []string{}}, result)
+	})
+
+	t.Run("update → only the given fields change", func(t *testing.T) {
+		user := newNotifiedUser()
+		settingRepo := notificationSettingRepo(&model.NotificationSetting{NotificationSettingID: 4, UserID: 7, TenantID: 1, EmailEnabled: true, InAppEnabled: true})
+		var saved *model.NotificationSetting
+		settingRepo.createOrUpdateFn = func(e *model.NotificationSetting) (*model.NotificationSetting, error) {
+			saved = e
+			return e, nil
+		}
+		svc := newNotificationService(user, settingRepo, &mockNotificationLogRepo{})
+
+		emailEnabled := false
+		result, err := svc.UpdateSettings(context.Background(), 1, user.UserUUID, NotificationSettingInput{
+			EmailEnabled: &emailEnabled,
+			MutedTypes:   &[]string{"account.updated"},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, saved)
+		assert.Equal(t, int64(4), saved.NotificationSettingID)
+		assert.Equal(t, &NotificationSettingServiceDataResult{InAppEnabled: true, MutedTypes: []string{"account.updated"}}, result)
+	})
+
+	t.Run("user of another tenant → not found", func(t *testing.T) {
+		user := newNotifiedUser()
+		svc := newNotificationService(user, notificationSettingRepo(nil), &mockNotificationLogRepo{})
+
+		var notFound *apperror.NotFoundError
+		_, err := svc.GetSettings(context.Background(), 2, user.UserUUID)
+		assert.ErrorAs(t, err, &notFound)
+
+		_, err = svc.UpdateSettings(context.Background(), 2, user.UserUUID, NotificationSettingInput{})
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("save error → error", func(t *testing.T) {
+		user := newNotifiedUser()
+		settingRepo := notificationSettingRepo(nil)
+		settingRepo.createOrUpdateFn = func(*model.NotificationSetting) (*model.NotificationSetting, error) {
+			return nil, errors.New("db down")
+		}
+		svc := newNotificationService(user, settingRepo, &mockNotificationLogRepo{})
+
+		smsEnabled := true
+		_, err := svc.UpdateSettings(context.Background(), 1, user.UserUUID, NotificationSettingInput{SMSEnabled: &smsEnabled})
+		assert.Error(t, err)
+	})
+}
+
+func TestNotificationService_FindLogs(t *testing.T) {
+	user := newNotifiedUser()
+	createdAt := time.Now()
+	logUUID := uuid.New()
+	var got repository.NotificationLogRepositoryGetFilter
+	logRepo := &mockNotificationLogRepo{findPaginatedFn: func(filter repository.NotificationLogRepositoryGetFilter) (*repository.PaginationResult[model.NotificationLog], error) {
+		got = filter
+		return &repository.PaginationResult[model.NotificationLog]{
+			Data: []model.NotificationLog{{
+				NotificationLogUUID: logUUID,
+				User:                user,
+				NotificationType:    model.NotificationTypeNewDevice,
+				Channel:             model.NotificationChannelEmail,
+				Status:              model.NotificationStatusSent,
+				CreatedAt:           createdAt,
+			}},
+			Total: 1, Page: 1, Limit: 10, TotalPages: 1,
+		}, nil
+	}}
+	svc := newNotificationService(user, &mockNotificationSettingRepo{}, logRepo)
+
+	t.Run("user given → limited to the user", func(t *testing.T) {
+		result, err := svc.FindLogs(context.Background(), &user.UserUUID, repository.NotificationLogRepositoryGetFilter{TenantID: 1})
+		require.NoError(t, err)
+		require.NotNil(t, got.UserID)
+		assert.Equal(t, int64(7), *got.UserID)
+		require.Len(t, result.Data, 1)
+		assert.Equal(t, logUUID, result.Data[0].NotificationLogUUID)
+		assert.Equal(t, user.UserUUID, result.Data[0].UserUUID)
+		assert.Equal(t, int64(1), result.Total)
+	})
+
+	t.Run("no user → whole tenant", func(t *testing.T) {
+		_, err := svc.FindLogs(context.Background(), nil, repository.NotificationLogRepositoryGetFilter{TenantID: 1})
+		require.NoError(t, err)
+		assert.Nil(t, got.UserID)
+	})
+
+	t.Run("user of another tenant → not found", func(t *testing.T) {
+		_, err := svc.FindLogs(context.Background(), &user.UserUUID, repository.NotificationLogRepositoryGetFilter{TenantID: 2})
+		var notFound *apperror.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("repository error → error", func(t *testing.T) {
+		logRepo.findPaginatedFn = func(repository.NotificationLogRepositoryGetFilter) (*repository.PaginationResult[model.NotificationLog], error) {
+			return nil, errors.New("db down")
+		}
+		_, err := svc.FindLogs(context.Background(), nil, repository.NotificationLogRepositoryGetFilter{TenantID: 1})
+		assert.Error(t, err)
+	})
+}
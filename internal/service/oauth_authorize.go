@@ -288,12 +288,21 @@ func (s *oauthAuthorizeService) HandleConsent(ctx context.Context, decision dto.
 		txAuthCodeRepo := s.authCodeRepo.WithTx(tx)
 		txConsentChallRepo := s.consentChallRepo.WithTx(tx)
 
-		// Persist the consent grant (upsert to handle scope expansion).
+		// Persist the consent grant, adding the approved scopes to those the
+		// user granted earlier so that they are not asked again.
+		existing, err := txConsentGrantRepo.FindByUserAndClient(userID, challenge.ClientID)
+		if err != nil {
+			return err
+		}
+		scopes := challenge.Scope
+		if existing != nil {
+			scopes = mergeScopes(existing.Scopes, challenge.Scope)
+		}
 		if _, err := txConsentGrantRepo.Upsert(&model.OAuthConsentGrant{
 			UserID:   userID,
 			ClientID: challenge.ClientID,
 			TenantID: challenge.TenantID,
-			Scopes:   challenge.Scope,
+			Scopes:   scopes,
 		}); err != nil {
 			return err
 		}
@@ -314,6 +323,7 @@ func (s *oauthAuthorizeService) HandleConsent(ctx context.Context, decision dto.
 			Scope:               challenge.Scope,
 			State:               challenge.State,
 			Nonce:               challenge.Nonce,
+			SessionID:           challenge.SessionID,
 			CodeChallenge:       challenge.CodeChallenge,
 			CodeChallengeMethod: challenge.CodeChallengeMethod,
 			ExpiresAt:           time.Now().Add(authorizationCodeTTL),
@@ -415,10 +425,11 @@ func (s *oauthAuthorizeService) validateRedirectURI(client *model.Client, redire
 }
 
 // needsConsent determines whether the user needs to provide consent for the
-// requested scopes. Consent is not required if the client has require_consent
-// set to false or if the user has already consented to all requested scopes.
+// requested scopes. Consent is not required for first-party (system) clients,
+// if the client has require_consent set to false or if the user has already
+// consented to all requested scopes.
 func (s *oauthAuthorizeService) needsConsent(client *model.Client, userID int64, requestedScope string) (bool, error) {
-	if !client.RequireConsent {
+	if client.IsSystem || !client.RequireConsent {
 		return false, nil
 	}
 
@@ -468,6 +479,9 @@ func (s *oauthAuthorizeService) createConsentChallenge(ctx context.Context, clie
 	if req.Nonce != "" {
 		challenge.Nonce = &req.Nonce
 	}
+	if req.SessionID != "" {
+		challenge.SessionID = &req.SessionID
+	}
 
 	if _, err := s.consentChallRepo.Create(challenge); err != nil {
 		return nil, apperror.NewOAuthServerError("an unexpected error occurred")
@@ -504,6 +518,9 @@ func (s *oauthAuthorizeService) issueAuthorizationCode(ctx context.Context, clie
 	if req.Nonce != "" {
 		authCode.Nonce = &req.Nonce
 	}
+	if req.SessionID != "" {
+		authCode.SessionID = &req.SessionID
+	}
 
 	if _, err := s.authCodeRepo.Create(authCode); err != nil {
 		return "", apperror.NewOAuthServerError("an unexpected error occurred")
@@ -543,3 +560,19 @@ func splitScopes(scope string) []string {
 	}
 	return result
 }
+
+// mergeScopes returns the space-delimited union of two scope strings, keeping
+// the order in which the scopes first appear.
+func mergeScopes(granted, requested string) string {
+	all := append(splitScopes(granted), splitScopes(requested)...)
+	seen := make(map[string]struct{}, len(all))
+	merged := make([]string, 0, len(all))
+	for _, scope := range all {
+		if _, ok := seen[scope]; ok {
+			continue
+		}
+		seen[scope] = struct{}{}
+		merged = append(merged, scope)
+	}
+	return strings.Join(merged, " ")
+}
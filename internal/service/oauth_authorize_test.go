@@ -170,6 +170,35 @@ func TestOAuthAuthorizeService_Authorize(t *testing.T) {
 		assert.Contains(t, result.RedirectURI, "code=")
 	})
 
+	t.Run("skips consent for first-party client", func(t *testing.T) {
+		client := activeClientWithConsent()
+		client.IsSystem = true
+		db, _ := newMockDB(t)
+
+		svc := newOAuthAuthorizeSvc(db,
+			&mockClientRepo{
+				findByClientIDAndIdentityProviderFn: func(_, _ string) (*model.Client, error) {
+					return client, nil
+				},
+			},
+			&mockClientURIRepo{},
+			&mockOAuthAuthCodeRepo{},
+			&mockOAuthConsentGrantRepo{
+				findByUserAndClientFn: func(_, _ int64) (*model.OAuthConsentGrant, error) {
+					t.Fatal("grant must not be looked up for a first-party client")
+					return nil, nil
+				},
+			},
+			&mockOAuthConsentChallRepo{},
+			&mockAuthEventService{},
+		)
+
+		result, oerr := svc.Authorize(ctx, validAuthorizeRequest(), 1)
+		require.Nil(t, oerr)
+		assert.Contains(t, result.RedirectURI, "code=")
+		assert.Empty(t, result.ConsentChallenge)
+	})
+
 	t.Run("requires consent when new scope requested", func(t *testing.T) {
 		client := activeClientWithConsent()
 		db, _ := newMockDB(t)
@@ -949,6 +978,79 @@ func TestOAuthAuthorizeService_HandleConsent(t *testing.T) {
 		assert.Contains(t, result.RedirectURI, "error=access_denied")
 	})
 
+	t.Run("approved — merges scopes into existing grant", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		var saved *model.OAuthConsentGrant
+		svc := newOAuthAuthorizeSvc(db,
+			&mockClientRepo{}, &mockClientURIRepo{},
+			&mockOAuthAuthCodeRepo{},
+			&mockOAuthConsentGrantRepo{
+				findByUserAndClientFn: func(_, _ int64) (*model.OAuthConsentGrant, error) {
+					return &model.OAuthConsentGrant{Scopes: "openid email"}, nil
+				},
+				upsertFn: func(g *model.OAuthConsentGrant) (*model.OAuthConsentGrant, error) {
+					saved = g
+					return g, nil
+				},
+			},
+			&mockOAuthConsentChallRepo{
+				findChallengeByUUIDFn: func(_ uuid.UUID) (*model.OAuthConsentChallenge, error) {
+					return &model.OAuthConsentChallenge{
+						OAuthConsentChallengeUUID: challengeUUID,
+						ClientID:                  10,
+						UserID:                    1,
+						TenantID:                  100,
+						RedirectURI:               "https://example.com/callback",
+						Scope:                     "openid profile",
+						ExpiresAt:                 time.Now().Add(5 * time.Minute),
+					}, nil
+				},
+			},
+			&mockAuthEventService{},
+		)
+
+		_, oerr := svc.HandleConsent(ctx, validDecision(true), 1)
+		require.Nil(t, oerr)
+		require.NotNil(t, saved)
+		assert.Equal(t, "openid email profile", saved.Scopes)
+	})
+
+	t.Run("grant lookup error in transaction", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		svc := newOAuthAuthorizeSvc(db,
+			&mockClientRepo{}, &mockClientURIRepo{},
+			&mockOAuthAuthCodeRepo{},
+			&mockOAuthConsentGrantRepo{
+				findByUserAndClientFn: func(_, _ int64) (*model.OAuthConsentGrant, error) {
+					return nil, errors.New("db error")
+				},
+			},
+			&mockOAuthConsentChallRepo{
+				findChallengeByUUIDFn: func(_ uuid.UUID) (*model.OAuthConsentChallenge, error) {
+					return &model.OAuthConsentChallenge{
+						OAuthConsentChallengeUUID: challengeUUID,
+						ClientID:                  10,
+						UserID:                    1,
+						TenantID:                  100,
+						RedirectURI:               "https://example.com/callback",
+						ExpiresAt:                 time.Now().Add(5 * time.Minute),
+					}, nil
+				},
+			},
+			&mockAuthEventService{},
+		)
+
+		_, oerr := svc.HandleConsent(ctx, validDecision(true), 1)
+		require.NotNil(t, oerr)
+		assert.Equal(t, "server_error", oerr.Code)
+	})
+
 	t.Run("upsert error in transaction", func(t *testing.T) {
 		db, mock := newMockDB(t)
 		mock.ExpectBegin()
@@ -1054,3 +1156,19 @@ func TestSplitScopes(t *testing.T) {
 		assert.Equal(t, []string{"a", "b"}, splitScopes("  a   b  "))
 	})
 }
+
+// ── TestMergeScopes ─────────────────────────────────────────────────────────
+
+func TestMergeScopes(t *testing.T) {
+	t.Run("adds new scopes after granted ones", func(t *testing.T) {
+		assert.Equal(t, "openid email profile", mergeScopes("openid email", "openid profile"))
+	})
+
+	t.Run("nothing granted", func(t *testing.T) {
+		assert.Equal(t, "openid profile", mergeScopes("", "openid profile"))
+	})
+
+	t.Run("nothing new", func(t *testing.T) {
+		assert.Equal(t, "openid profile", mergeScopes("openid profile", "profile"))
+	})
+}
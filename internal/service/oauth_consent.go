@@ -11,6 +11,7 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
 )
 
 // OAuthConsentService manages user consent grants (the persisted records of
@@ -20,20 +21,27 @@ type OAuthConsentService interface {
 	ListGrants(ctx context.Context, userID int64) ([]dto.OAuthConsentGrantResponseDTO, error)
 
 	// RevokeGrant removes a consent grant, forcing the user to re-consent on
-	// the next authorization request.
+	// the next authorization request. The refresh tokens the client holds
+	// for the user are revoked with it, so the client loses access at once.
 	RevokeGrant(ctx context.Context, grantUUID uuid.UUID, userID int64) error
 }
 
 type oauthConsentService struct {
+	db               *gorm.DB
 	consentGrantRepo repository.OAuthConsentGrantRepository
+	refreshTokenRepo repository.OAuthRefreshTokenRepository
 }
 
 // NewOAuthConsentService creates a new OAuthConsentService.
 func NewOAuthConsentService(
+	db *gorm.DB,
 	consentGrantRepo repository.OAuthConsentGrantRepository,
+	refreshTokenRepo repository.OAuthRefreshTokenRepository,
 ) OAuthConsentService {
 	return &oauthConsentService{
+		db:               db,
 		consentGrantRepo: consentGrantRepo,
+		refreshTokenRepo: refreshTokenRepo,
 	}
 }
 
@@ -103,7 +111,14 @@ func (s *oauthConsentService) RevokeGrant(ctx context.Context, grantUUID uuid.UU
 		return apperror.NewNotFoundWithReason("consent grant not found")
 	}
 
-	if err := s.consentGrantRepo.DeleteByUserAndClient(userID, *found); err != nil {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.consentGrantRepo.WithTx(tx).DeleteByUserAndClient(userID, *found); err != nil {
+			return err
+		}
+		_, err := s.refreshTokenRepo.WithTx(tx).RevokeByUserAndClient(userID, *found)
+		return err
+	})
+	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "consent grant deletion failed")
 		return apperror.NewInternal("failed to revoke consent grant", err)
@@ -6,14 +6,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func newOAuthConsentSvc(repo *mockOAuthConsentGrantRepo) OAuthConsentService {
-	return NewOAuthConsentService(repo)
+func newOAuthConsentSvc(t *testing.T, repo *mockOAuthConsentGrantRepo, refreshTokenRepo *mockOAuthRefreshTokenRepo) (OAuthConsentService, sqlmock.Sqlmock) {
+	t.Helper()
+	gormDB, mock := newMockGormDB(t)
+	return NewOAuthConsentService(gormDB, repo, refreshTokenRepo), mock
 }
 
 func TestOAuthConsentService_ListGrants(t *testing.T) {
@@ -24,7 +27,7 @@ func TestOAuthConsentService_ListGrants(t *testing.T) {
 		clientUUID := uuid.New()
 		now := time.Now()
 
-		svc := newOAuthConsentSvc(&mockOAuthConsentGrantRepo{
+		svc, _ := newOAuthConsentSvc(t, &mockOAuthConsentGrantRepo{
 			findByUserIDFn: func(_ int64) ([]model.OAuthConsentGrant, error) {
 				return []model.OAuthConsentGrant{
 					{
@@ -42,7 +45,7 @@ func TestOAuthConsentService_ListGrants(t *testing.T) {
 					},
 				}, nil
 			},
-		})
+		}, &mockOAuthRefreshTokenRepo{})
 
 		grants, err := svc.ListGrants(ctx, 1)
 		require.NoError(t, err)
@@ -54,11 +57,11 @@ func TestOAuthConsentService_ListGrants(t *testing.T) {
 	})
 
 	t.Run("returns empty list when no grants", func(t *testing.T) {
-		svc := newOAuthConsentSvc(&mockOAuthConsentGrantRepo{
+		svc, _ := newOAuthConsentSvc(t, &mockOAuthConsentGrantRepo{
 			findByUserIDFn: func(_ int64) ([]model.OAuthConsentGrant, error) {
 				return nil, nil
 			},
-		})
+		}, &mockOAuthRefreshTokenRepo{})
 
 		grants, err := svc.ListGrants(ctx, 1)
 		require.NoError(t, err)
@@ -69,7 +72,7 @@ func TestOAuthConsentService_ListGrants(t *testing.T) {
 		grantUUID := uuid.New()
 		now := time.Now()
 
-		svc := newOAuthConsentSvc(&mockOAuthConsentGrantRepo{
+		svc, _ := newOAuthConsentSvc(t, &mockOAuthConsentGrantRepo{
 			findByUserIDFn: func(_ int64) ([]model.OAuthConsentGrant, error) {
 				return []model.OAuthConsentGrant{
 					{
@@ -80,7 +83,7 @@ func TestOAuthConsentService_ListGrants(t *testing.T) {
 					},
 				}, nil
 			},
-		})
+		}, &mockOAuthRefreshTokenRepo{})
 
 		grants, err := svc.ListGrants(ctx, 1)
 		require.NoError(t, err)
@@ -90,11 +93,11 @@ func TestOAuthConsentService_ListGrants(t *testing.T) {
 	})
 
 	t.Run("repo error", func(t *testing.T) {
-		svc := newOAuthConsentSvc(&mockOAuthConsentGrantRepo{
+		svc, _ := newOAuthConsentSvc(t, &mockOAuthConsentGrantRepo{
 			findByUserIDFn: func(_ int64) ([]model.OAuthConsentGrant, error) {
 				return nil, errors.New("db error")
 			},
-		})
+		}, &mockOAuthRefreshTokenRepo{})
 
 		_, err := svc.ListGrants(ctx, 1)
 		require.Error(t, err)
@@ -107,9 +110,9 @@ func TestOAuthConsentService_RevokeGrant(t *testing.T) {
 
 	t.Run("revokes existing grant", func(t *testing.T) {
 		grantUUID := uuid.New()
-		var deletedUserID, deletedClientID int64
+		var deletedUserID, deletedClientID, revokedUserID, revokedClientID int64
 
-		svc := newOAuthConsentSvc(&mockOAuthConsentGrantRepo{
+		svc, mock := newOAuthConsentSvc(t, &mockOAuthConsentGrantRepo{
 			findByUserIDFn: func(uid int64) ([]model.OAuthConsentGrant, error) {
 				return []model.OAuthConsentGrant{
 					{
@@ -124,20 +127,31 @@ func TestOAuthConsentService_RevokeGrant(t *testing.T) {
 				deletedClientID = cid
 				return nil
 			},
+		}, &mockOAuthRefreshTokenRepo{
+			revokeByUserAndClientFn: func(uid, cid int64) (int64, error) {
+				revokedUserID = uid
+				revokedClientID = cid
+				return 2, nil
+			},
 		})
+		mock.ExpectBegin()
+		mock.ExpectCommit()
 
 		err := svc.RevokeGrant(ctx, grantUUID, 1)
 		require.NoError(t, err)
 		assert.Equal(t, int64(1), deletedUserID)
 		assert.Equal(t, int64(10), deletedClientID)
+		assert.Equal(t, int64(1), revokedUserID)
+		assert.Equal(t, int64(10), revokedClientID)
+		require.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("grant not found", func(t *testing.T) {
-		svc := newOAuthConsentSvc(&mockOAuthConsentGrantRepo{
+		svc, _ := newOAuthConsentSvc(t, &mockOAuthConsentGrantRepo{
 			findByUserIDFn: func(_ int64) ([]model.OAuthConsentGrant, error) {
 				return []model.OAuthConsentGrant{}, nil
 			},
-		})
+		}, &mockOAuthRefreshTokenRepo{})
 
 		err := svc.RevokeGrant(ctx, uuid.New(), 1)
 		require.Error(t, err)
@@ -148,13 +162,13 @@ func TestOAuthConsentService_RevokeGrant(t *testing.T) {
 		grantUUID := uuid.New()
 		otherUUID := uuid.New()
 
-		svc := newOAuthConsentSvc(&mockOAuthConsentGrantRepo{
+		svc, _ := newOAuthConsentSvc(t, &mockOAuthConsentGrantRepo{
 			findByUserIDFn: func(_ int64) ([]model.OAuthConsentGrant, error) {
 				return []model.OAuthConsentGrant{
 					{OAuthConsentGrantUUID: otherUUID, ClientID: 10},
 				}, nil
 			},
-		})
+		}, &mockOAuthRefreshTokenRepo{})
 
 		err := svc.RevokeGrant(ctx, grantUUID, 1)
 		require.Error(t, err)
@@ -162,11 +176,11 @@ func TestOAuthConsentService_RevokeGrant(t *testing.T) {
 	})
 
 	t.Run("FindByUserID error", func(t *testing.T) {
-		svc := newOAuthConsentSvc(&mockOAuthConsentGrantRepo{
+		svc, _ := newOAuthConsentSvc(t, &mockOAuthConsentGrantRepo{
 			findByUserIDFn: func(_ int64) ([]model.OAuthConsentGrant, error) {
 				return nil, errors.New("db error")
 			},
-		})
+		}, &mockOAuthRefreshTokenRepo{})
 
 		err := svc.RevokeGrant(ctx, uuid.New(), 1)
 		require.Error(t, err)
@@ -176,7 +190,7 @@ func TestOAuthConsentService_RevokeGrant(t *testing.T) {
 	t.Run("DeleteByUserAndClient error", func(t *testing.T) {
 		grantUUID := uuid.New()
 
-		svc := newOAuthConsentSvc(&mockOAuthConsentGrantRepo{
+		svc, mock := newOAuthConsentSvc(t, &mockOAuthConsentGrantRepo{
 			findByUserIDFn: func(uid int64) ([]model.OAuthConsentGrant, error) {
 				return []model.OAuthConsentGrant{
 					{OAuthConsentGrantUUID: grantUUID, UserID: uid, ClientID: 10},
@@ -185,7 +199,31 @@ func TestOAuthConsentService_RevokeGrant(t *testing.T) {
 			deleteByUserAndClientFn: func(_, _ int64) error {
 				return errors.New("delete error")
 			},
+		}, &mockOAuthRefreshTokenRepo{})
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		err := svc.RevokeGrant(ctx, grantUUID, 1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to revoke consent grant")
+	})
+
+	t.Run("RevokeByUserAndClient error", func(t *testing.T) {
+		grantUUID := uuid.New()
+
+		svc, mock := newOAuthConsentSvc(t, &mockOAuthConsentGrantRepo{
+			findByUserIDFn: func(uid int64) ([]model.OAuthConsentGrant, error) {
+				return []model.OAuthConsentGrant{
+					{OAuthConsentGrantUUID: grantUUID, UserID: uid, ClientID: 10},
+				}, nil
+			},
+		}, &mockOAuthRefreshTokenRepo{
+			revokeByUserAndClientFn: func(_, _ int64) (int64, error) {
+				return 0, errors.New("revoke error")
+			},
 		})
+		mock.ExpectBegin()
+		mock.ExpectRollback()
 
 		err := svc.RevokeGrant(ctx, grantUUID, 1)
 		require.Error(t, err)
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/logging"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	// backChannelLogoutTimeout bounds each logout token delivery.
+	backChannelLogoutTimeout = 5 * time.Second
+	// backChannelLogoutAttempts is how often a logout token is sent before
+	// the client is given up on.
+	backChannelLogoutAttempts = 3
+	// backChannelLogoutRetryDelay is the delay before the second attempt;
+	// it grows linearly with each further attempt.
+	backChannelLogoutRetryDelay = 2 * time.Second
+)
+
+// OAuthLogoutSession is the login session of the browser calling the
+// end_session endpoint, taken from its access token or browser session.
+type OAuthLogoutSession struct {
+	Sub       string
+	ClientID  string
+	SessionID string
+}
+
+// OAuthLogoutService ends login sessions on behalf of relying parties (OIDC
+// RP-Initiated Logout 1.0) and tells every client the session signed in to
+// that it ended, through back-channel logout tokens (OIDC Back-Channel
+// Logout 1.0) and front-channel logout URIs (OIDC Front-Channel Logout 1.0).
+type OAuthLogoutService interface {
+	// EndSession ends the session named by the request's id_token_hint or,
+	// without one, the caller's own session, which may be nil. It revokes
+	// the session's refresh tokens and sends logout tokens in the
+	// background. The result names the front-channel logout URIs to load
+	// and where to send the user afterwards.
+	EndSession(ctx context.Context, req dto.OAuthEndSessionRequestDTO, session *OAuthLogoutSession) (*dto.OAuthEndSessionResult, *apperror.OAuthError)
+}
+
+type oauthLogoutService struct {
+	clientRepo       repository.ClientRepository
+	refreshTokenRepo repository.OAuthRefreshTokenRepository
+	userRepo         repository.UserRepository
+	userIdentityRepo repository.UserIdentityRepository
+	authEventService AuthEventService
+	httpClient       *http.Client
+	retryDelay       time.Duration
+}
+
+// NewOAuthLogoutService creates a new OAuthLogoutService.
+func NewOAuthLogoutService(
+	clientRepo repository.ClientRepository,
+	refreshTokenRepo repository.OAuthRefreshTokenRepository,
+	userRepo repository.UserRepository,
+	userIdentityRepo repository.UserIdentityRepository,
+	authEventService AuthEventService,
+) OAuthLogoutService {
+	return &oauthLogoutService{
+		clientRepo:       clientRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		userRepo:         userRepo,
+		userIdentityRepo: userIdentityRepo,
+		authEventService: authEventService,
+		httpClient:       &http.Client{},
+		retryDelay:       backChannelLogoutRetryDelay,
+	}
+}
+
+// EndSession implements OAuthLogoutService.
+func (s *oauthLogoutService) EndSession(ctx context.Context, req dto.OAuthEndSessionRequestDTO, session *OAuthLogoutSession) (*dto.OAuthEndSessionResult, *apperror.OAuthError) {
+	ctx, span := otel.Tracer("service").Start(ctx, "oauth_logout.end_session")
+	defer span.End()
+
+	// The hint names the session to end; without one the caller ends its
+	// own session.
+	var sub, clientIdentifier, sessionID string
+	if req.IDTokenHint != "" {
+		claims, err := jwt.ParseIDTokenHint(req.IDTokenHint)
+		if err != nil {
+			span.SetStatus(codes.Error, "invalid id_token_hint")
+			return nil, apperror.NewOAuthInvalidRequest("id_token_hint is invalid")
+		}
+		sub, _ = claims["sub"].(string)
+		clientIdentifier, _ = claims["aud"].(string)
+		sessionID, _ = claims["sid"].(string)
+		if req.ClientID != "" && req.ClientID != clientIdentifier {
+			span.SetStatus(codes.Error, "client mismatch")
+			return nil, apperror.NewOAuthInvalidRequest("client_id does not match the audience of id_token_hint")
+		}
+	} else if session != nil {
+		sub, clientIdentifier, sessionID = session.Sub, session.ClientID, session.SessionID
+	}
+	if req.ClientID != "" {
+		clientIdentifier = req.ClientID
+	}
+
+	var client *model.Client
+	if clientIdentifier != "" {
+		var err error
+		client, err = s.clientRepo.FindActiveByIdentifier(clientIdentifier)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "client lookup failed")
+			return nil, apperror.NewOAuthServerError("an unexpected error occurred")
+		}
+		if client == nil && (req.ClientID != "" || req.IDTokenHint != "") {
+			span.SetStatus(codes.Error, "client not found")
+			return nil, apperror.NewOAuthInvalidRequest("unknown client")
+		}
+	}
+
+	result := &dto.OAuthEndSessionResult{}
+	if req.PostLogoutRedirectURI != "" {
+		if !isPostLogoutRedirectURI(client, req.PostLogoutRedirectURI) {
+			span.SetStatus(codes.Error, "post_logout_redirect_uri not registered")
+			return nil, apperror.NewOAuthInvalidRequest("post_logout_redirect_uri is not registered for this client")
+		}
+		result.RedirectURI = appendQuery(req.PostLogoutRedirectURI, url.Values{"state": {req.State}})
+	}
+
+	var user *model.User
+	if sub != "" && clientIdentifier != "" {
+		var err error
+		if user, err = s.userRepo.FindBySubAndClientID(sub, clientIdentifier); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "user lookup failed")
+			return nil, apperror.NewOAuthServerError("an unexpected error occurred")
+		}
+	}
+
+	switch {
+	case sessionID != "":
+		uris, userID, err := s.endSession(ctx, sessionID, client)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "end session failed")
+			return nil, apperror.NewOAuthServerError("an unexpected error occurred")
+		}
+		result.FrontChannelLogoutURIs = uris
+		if user == nil && userID != 0 {
+			user = &model.User{UserID: userID}
+		}
+	case user != nil && client != nil:
+		// Tokens issued before sessions were tracked can only be revoked
+		// per client.
+		if _, err := s.refreshTokenRepo.RevokeByUserAndClient(user.UserID, client.ClientID); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "revoke refresh tokens failed")
+			return nil, apperror.NewOAuthServerError("an unexpected error occurred")
+		}
+	}
+
+	if user != nil && client != nil {
+		s.authEventService.Log(ctx, AuthEventInput{
+			TenantID:    client.TenantID,
+			ActorUserID: &user.UserID,
+			IPAddress:   middleware.ClientIPFromContext(ctx),
+			UserAgent:   ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+			Category:    model.AuthEventCategorySession,
+			EventType:   model.AuthEventTypeSessionExpired,
+			Severity:    model.AuthEventSeverityInfo,
+			Result:      model.AuthEventResultSuccess,
+			Description: ptr.Ptr("Session ended by logout"),
+		})
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// endSession revokes the refresh tokens of a login session and notifies the
+// clients it signed in to: the client of the tokens and client, which may
+// be nil. Back-channel logout tokens are sent in the background. It returns
+// the front-channel logout URIs of the clients and the user of the session,
+// or 0 when the session issued no refresh tokens.
+func (s *oauthLogoutService) endSession(ctx context.Context, sessionID string, client *model.Client) ([]string, int64, error) {
+	tokens, err := s.refreshTokenRepo.FindBySessionID(sessionID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if _, err := s.refreshTokenRepo.RevokeBySessionID(sessionID); err != nil {
+		return nil, 0, err
+	}
+
+	var userID int64
+	var clientIDs []int64
+	seen := map[int64]bool{}
+	if client != nil {
+		seen[client.ClientID] = true
+	}
+	for _, t := range tokens {
+		userID = t.UserID
+		if !seen[t.ClientID] {
+			seen[t.ClientID] = true
+			clientIDs = append(clientIDs, t.ClientID)
+		}
+	}
+
+	clients, err := s.clientRepo.FindByIDsWithURIs(clientIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	if client != nil {
+		clients = append([]model.Client{*client}, clients...)
+	}
+
+	var frontChannelURIs []string
+	for i := range clients {
+		c := &clients[i]
+		if c.ClientURIs == nil || c.Identifier == nil || c.Domain == nil {
+			continue
+		}
+		for _, uri := range *c.ClientURIs {
+			switch uri.Type {
+			case model.ClientURITypeFrontChannelLogout:
+				frontChannelURIs = append(frontChannelURIs, appendQuery(uri.URI, url.Values{
+					"iss": {*c.Domain},
+					"sid": {sessionID},
+				}))
+			case model.ClientURITypeBackChannelLogout:
+				go s.sendLogoutToken(context.WithoutCancel(ctx), c, uri.URI, userID, sessionID)
+			}
+		}
+	}
+	return frontChannelURIs, userID, nil
+}
+
+// sendLogoutToken delivers a logout token to a client's back-channel logout
+// URI, retrying failed deliveries. The token names the user by the sub of
+// their identity with the client when there is one.
+func (s *oauthLogoutService) sendLogoutToken(ctx context.Context, client *model.Client, uri string, userID int64, sessionID string) {
+	logger := logging.Logger(logging.ComponentAuth)
+
+	var sub string
+	if userID != 0 {
+		identity, err := s.userIdentityRepo.FindByUserIDAndClientID(userID, client.ClientID)
+		if err != nil {
+			logger.WarnContext(ctx, "back-channel logout: identity lookup failed", "client_id", client.ClientID, "error", err)
+		} else if identity != nil {
+			sub = identity.Sub
+		}
+	}
+
+	logoutToken, err := jwt.GenerateLogoutToken(sub, *client.Domain, *client.Identifier, sessionID)
+	if err != nil {
+		logger.ErrorContext(ctx, "back-channel logout: generate logout token failed", "client_id", client.ClientID, "error", err)
+		return
+	}
+
+	for attempt := 1; attempt <= backChannelLogoutAttempts; attempt++ {
+		if err = s.postLogoutToken(ctx, uri, logoutToken); err == nil {
+			return
+		}
+		if attempt < backChannelLogoutAttempts {
+			time.Sleep(time.Duration(attempt) * s.retryDelay)
+		}
+	}
+	logger.WarnContext(ctx, "back-channel logout failed", "client_id", client.ClientID, "uri", uri, "error", err)
+}
+
+// postLogoutToken POSTs a logout token as an application/x-www-form-urlencoded
+// logout_token parameter. Clients answer 200 OK, or 204 No Content.
+func (s *oauthLogoutService) postLogoutToken(ctx context.Context, uri, logoutToken string) error {
+	ctx, cancel := context.WithTimeout(ctx, backChannelLogoutTimeout)
+	defer cancel()
+
+	body := url.Values{"logout_token": {logoutToken}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, strings.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build logout request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", "maintainerd-auth")
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("logout request failed: %w", err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("logout request failed: unexpected status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// isPostLogoutRedirectURI reports whether uri is one of the client's
+// registered logout URIs. Matching is exact, like redirect URIs.
+func isPostLogoutRedirectURI(client *model.Client, uri string) bool {
+	if client == nil || client.ClientURIs == nil {
+		return false
+	}
+	for _, u := range *client.ClientURIs {
+		if u.Type == model.ClientURITypeLogout && u.URI == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// appendQuery adds the non-empty values to the query of uri.
+func appendQuery(uri string, values url.Values) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	q := u.Query()
+	for key, vs := range values {
+		for _, v := range vs {
+			if v != "" {
+				q.Add(key, v)
+			}
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildLogoutClient returns an active client with the given URIs.
+func buildLogoutClient(uris ...model.ClientURI) *model.Client {
+	client := buildActiveClient()
+	client.TenantID = 1
+	client.ClientURIs = &uris
+	return client
+}
+
+func newOAuthLogoutSvc(clientRepo *mockClientRepo, refreshTokenRepo *mockOAuthRefreshTokenRepo, userRepo *mockUserRepo, authEventSvc *mockAuthEventService) *oauthLogoutService {
+	svc := NewOAuthLogoutService(clientRepo, refreshTokenRepo, userRepo, &mockUserIdentityRepo{
+		findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) {
+			return &model.UserIdentity{Sub: "identity-sub"}, nil
+		},
+	}, authEventSvc).(*oauthLogoutService)
+	svc.retryDelay = time.Millisecond
+	return svc
+}
+
+func TestOAuthLogoutService_EndSession(t *testing.T) {
+	initTestJWTKeysService(t)
+	ctx := context.Background()
+
+	t.Run("invalid id_token_hint", func(t *testing.T) {
+		svc := newOAuthLogoutSvc(&mockClientRepo{}, &mockOAuthRefreshTokenRepo{}, &mockUserRepo{}, &mockAuthEventService{})
+		_, oerr := svc.EndSession(ctx, dto.OAuthEndSessionRequestDTO{IDTokenHint: "not.a.jwt"}, nil)
+		require.NotNil(t, oerr)
+		assert.Equal(t, "invalid_request", oerr.Code)
+	})
+
+	t.Run("client_id does not match the hint", func(t *testing.T) {
+		hint, err := jwt.GenerateIDToken("sub-1", "https://auth.example.com", "test-client", "provider-1", nil, "")
+		require.NoError(t, err)
+
+		svc := newOAuthLogoutSvc(&mockClientRepo{}, &mockOAuthRefreshTokenRepo{}, &mockUserRepo{}, &mockAuthEventService{})
+		_, oerr := svc.EndSession(ctx, dto.OAuthEndSessionRequestDTO{IDTokenHint: hint, ClientID: "other-client"}, nil)
+		require.NotNil(t, oerr)
+		assert.Contains(t, oerr.Description, "client_id")
+	})
+
+	t.Run("unknown client", func(t *testing.T) {
+		svc := newOAuthLogoutSvc(&mockClientRepo{}, &mockOAuthRefreshTokenRepo{}, &mockUserRepo{}, &mockAuthEventService{})
+		_, oerr := svc.EndSession(ctx, dto.OAuthEndSessionRequestDTO{ClientID: "gone"}, nil)
+		require.NotNil(t, oerr)
+		assert.Contains(t, oerr.Description, "unknown client")
+	})
+
+	t.Run("unregistered post_logout_redirect_uri", func(t *testing.T) {
+		client := buildLogoutClient(model.ClientURI{URI: "https://app.example.com/bye", Type: model.ClientURITypeLogout})
+		clientRepo := &mockClientRepo{findActiveByIdentifierFn: func(string) (*model.Client, error) { return client, nil }}
+
+		svc := newOAuthLogoutSvc(clientRepo, &mockOAuthRefreshTokenRepo{}, &mockUserRepo{}, &mockAuthEventService{})
+		_, oerr := svc.EndSession(ctx, dto.OAuthEndSessionRequestDTO{
+			ClientID:              "test-client",
+			PostLogoutRedirectURI: "https://evil.example.com/bye",
+		}, nil)
+		require.NotNil(t, oerr)
+		assert.Contains(t, oerr.Description, "post_logout_redirect_uri")
+	})
+
+	t.Run("ends the hinted session at every client", func(t *testing.T) {
+		received := make(chan url.Values, 1)
+		rp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, r.ParseForm())
+			received <- r.PostForm
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer rp.Close()
+
+		client := buildLogoutClient(
+			model.ClientURI{URI: "https://app.example.com/bye", Type: model.ClientURITypeLogout},
+			model.ClientURI{URI: "https://app.example.com/frontchannel?x=1", Type: model.ClientURITypeFrontChannelLogout},
+		)
+		other := *buildLogoutClient(model.ClientURI{URI: rp.URL, Type: model.ClientURITypeBackChannelLogout})
+		other.ClientID, other.Identifier = 2, strPtr("other-client")
+
+		var revokedSession string
+		var findByIDs []int64
+		var logged *AuthEventInput
+		clientRepo := &mockClientRepo{
+			findActiveByIdentifierFn: func(identifier string) (*model.Client, error) {
+				assert.Equal(t, "test-client", identifier)
+				return client, nil
+			},
+			findByIDsWithURIsFn: func(ids []int64) ([]model.Client, error) {
+				findByIDs = ids
+				return []model.Client{other}, nil
+			},
+		}
+		refreshTokenRepo := &mockOAuthRefreshTokenRepo{
+			findBySessionIDFn: func(string) ([]model.OAuthRefreshToken, error) {
+				return []model.OAuthRefreshToken{
+					{ClientID: 1, UserID: 42},
+					{ClientID: 2, UserID: 42},
+					{ClientID: 2, UserID: 42},
+				}, nil
+			},
+			revokeBySessionIDFn: func(sid string) (int64, error) {
+				revokedSession = sid
+				return 3, nil
+			},
+		}
+		userRepo := &mockUserRepo{findBySubAndClientIDFn: func(string, string) (*model.User, error) {
+			return &model.User{UserID: 42}, nil
+		}}
+		authEvents := &mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = &in }}
+
+		hint, err := jwt.GenerateIDTokenWithClaims("sub-1", "https://auth.example.com", "test-client", "provider-1", nil, "",
+			map[string]any{"sid": "session-1"})
+		require.NoError(t, err)
+
+		svc := newOAuthLogoutSvc(clientRepo, refreshTokenRepo, userRepo, authEvents)
+		res, oerr := svc.EndSession(ctx, dto.OAuthEndSessionRequestDTO{
+			IDTokenHint:           hint,
+			PostLogoutRedirectURI: "https://app.example.com/bye",
+			State:                 "xyz",
+		}, nil)
+		require.Nil(t, oerr)
+
+		assert.Equal(t, "https://app.example.com/bye?state=xyz", res.RedirectURI)
+		assert.Equal(t, []string{"https://app.example.com/frontchannel?iss=https%3A%2F%2Fauth.example.com&sid=session-1&x=1"}, res.FrontChannelLogoutURIs)
+		assert.Equal(t, "session-1", revokedSession)
+		assert.Equal(t, []int64{2}, findByIDs)
+		require.NotNil(t, logged)
+		assert.Equal(t, model.AuthEventTypeSessionExpired, logged.EventType)
+		assert.Equal(t, int64(42), *logged.ActorUserID)
+
+		select {
+		case form := <-received:
+			claims, err := jwt.ValidateToken(form.Get("logout_token"))
+			require.NoError(t, err)
+			assert.Contains(t, claims["events"], jwt.BackChannelLogoutEvent)
+			assert.Equal(t, "identity-sub", claims["sub"])
+			assert.Equal(t, "session-1", claims["sid"])
+			assert.Equal(t, "other-client", claims["aud"])
+		case <-time.After(5 * time.Second):
+			t.Fatal("logout token not delivered")
+		}
+	})
+
+	t.Run("caller's session without sid revokes per client", func(t *testing.T) {
+		client := buildLogoutClient()
+		clientRepo := &mockClientRepo{findActiveByIdentifierFn: func(string) (*model.Client, error) { return client, nil }}
+		var revokedUser, revokedClient int64
+		refreshTokenRepo := &mockOAuthRefreshTokenRepo{
+			revokeByUserAndClientFn: func(uid, cid int64) (int64, error) {
+				revokedUser, revokedClient = uid, cid
+				return 1, nil
+			},
+			revokeBySessionIDFn: func(string) (int64, error) {
+				t.Fatal("no session to revoke")
+				return 0, nil
+			},
+		}
+		userRepo := &mockUserRepo{findBySubAndClientIDFn: func(sub, clientID string) (*model.User, error) {
+			assert.Equal(t, "sub-1", sub)
+			assert.Equal(t, "test-client", clientID)
+			return &model.User{UserID: 42}, nil
+		}}
+
+		svc := newOAuthLogoutSvc(clientRepo, refreshTokenRepo, userRepo, &mockAuthEventService{})
+		res, oerr := svc.EndSession(ctx, dto.OAuthEndSessionRequestDTO{}, &OAuthLogoutSession{Sub: "sub-1", ClientID: "test-client"})
+		require.Nil(t, oerr)
+		assert.Empty(t, res.RedirectURI)
+		assert.Empty(t, res.FrontChannelLogoutURIs)
+		assert.Equal(t, int64(42), revokedUser)
+		assert.Equal(t, int64(1), revokedClient)
+	})
+
+	t.Run("no session", func(t *testing.T) {
+		svc := newOAuthLogoutSvc(&mockClientRepo{}, &mockOAuthRefreshTokenRepo{}, &mockUserRepo{}, &mockAuthEventService{})
+		res, oerr := svc.EndSession(ctx, dto.OAuthEndSessionRequestDTO{}, nil)
+		require.Nil(t, oerr)
+		assert.Equal(t, &dto.OAuthEndSessionResult{}, res)
+	})
+}
+
+func TestOAuthLogoutService_sendLogoutToken_Retries(t *testing.T) {
+	initTestJWTKeysService(t)
+
+	var calls atomic.Int32
+	rp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer rp.Close()
+
+	svc := newOAuthLogoutSvc(&mockClientRepo{}, &mockOAuthRefreshTokenRepo{}, &mockUserRepo{}, &mockAuthEventService{})
+	svc.sendLogoutToken(context.Background(), buildLogoutClient(), rp.URL, 42, "session-1")
+	assert.Equal(t, int32(3), calls.Load())
+
+	// Gives up after the last attempt.
+	calls.Store(-10)
+	svc.sendLogoutToken(context.Background(), buildLogoutClient(), rp.URL, 0, "session-1")
+	assert.Equal(t, int32(-10+backChannelLogoutAttempts), calls.Load())
+}
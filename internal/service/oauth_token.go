@@ -3,13 +3,17 @@ package service
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/claims"
 	"github.com/maintainerd/auth/internal/crypto"
 	"github.com/maintainerd/auth/internal/dto"
 	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/metrics"
 	"github.com/maintainerd/auth/internal/middleware"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/ptr"
@@ -42,42 +46,66 @@ type OAuthTokenService interface {
 }
 
 type oauthTokenService struct {
-	db               *gorm.DB
-	clientRepo       repository.ClientRepository
-	authCodeRepo     repository.OAuthAuthorizationCodeRepository
-	refreshTokenRepo repository.OAuthRefreshTokenRepository
-	userRepo         repository.UserRepository
-	userIdentityRepo repository.UserIdentityRepository
-	authEventService AuthEventService
+	db                   *gorm.DB
+	clientRepo           repository.ClientRepository
+	apiRepo              repository.APIRepository
+	clientAPIRepo        repository.ClientAPIRepository
+	clientPermissionRepo repository.ClientPermissionRepository
+	authCodeRepo         repository.OAuthAuthorizationCodeRepository
+	refreshTokenRepo     repository.OAuthRefreshTokenRepository
+	userRepo             repository.UserRepository
+	userIdentityRepo     repository.UserIdentityRepository
+	securitySettingRepo  repository.SecuritySettingRepository
+	authEventService     AuthEventService
+	claimsFetcher        claims.Fetcher
 }
 
 // NewOAuthTokenService creates a new OAuthTokenService.
 func NewOAuthTokenService(
 	db *gorm.DB,
 	clientRepo repository.ClientRepository,
+	apiRepo repository.APIRepository,
+	clientAPIRepo repository.ClientAPIRepository,
+	clientPermissionRepo repository.ClientPermissionRepository,
 	authCodeRepo repository.OAuthAuthorizationCodeRepository,
 	refreshTokenRepo repository.OAuthRefreshTokenRepository,
 	userRepo repository.UserRepository,
 	userIdentityRepo repository.UserIdentityRepository,
+	securitySettingRepo repository.SecuritySettingRepository,
 	authEventService AuthEventService,
+	claimsFetcher claims.Fetcher,
 ) OAuthTokenService {
 	return &oauthTokenService{
-		db:               db,
-		clientRepo:       clientRepo,
-		authCodeRepo:     authCodeRepo,
-		refreshTokenRepo: refreshTokenRepo,
-		userRepo:         userRepo,
-		userIdentityRepo: userIdentityRepo,
-		authEventService: authEventService,
+		db:                   db,
+		clientRepo:           clientRepo,
+		apiRepo:              apiRepo,
+		clientAPIRepo:        clientAPIRepo,
+		clientPermissionRepo: clientPermissionRepo,
+		authCodeRepo:         authCodeRepo,
+		refreshTokenRepo:     refreshTokenRepo,
+		userRepo:             userRepo,
+		userIdentityRepo:     userIdentityRepo,
+		securitySettingRepo:  securitySettingRepo,
+		authEventService:     authEventService,
+		claimsFetcher:        claimsFetcher,
 	}
 }
 
 // Exchange implements OAuthTokenService.
-func (s *oauthTokenService) Exchange(ctx context.Context, req dto.OAuthTokenRequestDTO, creds dto.OAuthClientCredentials) (*dto.OAuthTokenResult, *apperror.OAuthError) {
+func (s *oauthTokenService) Exchange(ctx context.Context, req dto.OAuthTokenRequestDTO, creds dto.OAuthClientCredentials) (result *dto.OAuthTokenResult, oerr *apperror.OAuthError) {
 	_, span := otel.Tracer("service").Start(ctx, "oauth_token.exchange")
 	defer span.End()
 	span.SetAttributes(attribute.String("oauth.grant_type", req.GrantType))
 
+	start := time.Now()
+	defer func() {
+		outcome := metrics.ResultSuccess
+		if oerr != nil {
+			outcome = oerr.Code
+		}
+		metrics.ObserveTokenIssuance(tokenGrantLabel(req.GrantType), outcome, time.Since(start))
+	}()
+
 	switch req.GrantType {
 	case model.GrantTypeAuthorizationCode:
 		return s.exchangeAuthorizationCode(ctx, req, creds)
@@ -112,6 +140,13 @@ func (s *oauthTokenService) exchangeAuthorizationCode(ctx context.Context, req d
 		return nil, oerr
 	}
 
+	// Refuse sunset clients/APIs and flag deprecated ones.
+	deprecation, oerr := s.enforceDeprecation(ctx, client)
+	if oerr != nil {
+		span.SetStatus(codes.Error, "client or api sunset")
+		return nil, oerr
+	}
+
 	// Look up the authorization code by hash.
 	codeHash := crypto.HashAuthorizationCode(req.Code)
 	authCode, err := s.authCodeRepo.FindByCodeHash(codeHash)
@@ -195,12 +230,19 @@ func (s *oauthTokenService) exchangeAuthorizationCode(ctx context.Context, req d
 		return nil, apperror.NewOAuthServerError("an unexpected error occurred")
 	}
 
+	// Make room for the session the tokens start, or refuse it.
+	if oerr := s.enforceSessionLimit(ctx, client, user, authCode.SessionID); oerr != nil {
+		span.SetStatus(codes.Error, "session limit reached")
+		return nil, oerr
+	}
+
 	// Generate tokens.
-	result, oerr := s.generateTokens(ctx, sub, user, client, authCode.Scope, authCode.Nonce)
+	result, oerr := s.generateTokens(ctx, sub, user, client, authCode.Scope, authCode.Nonce, authCode.SessionID)
 	if oerr != nil {
 		span.SetStatus(codes.Error, "token generation failed")
 		return nil, oerr
 	}
+	result.Deprecation = deprecation
 
 	s.authEventService.Log(ctx, AuthEventInput{
 		TenantID:    client.TenantID,
@@ -233,6 +275,13 @@ func (s *oauthTokenService) exchangeRefreshToken(ctx context.Context, req dto.OA
 		return nil, oerr
 	}
 
+	// Refuse sunset clients/APIs and flag deprecated ones.
+	deprecation, oerr := s.enforceDeprecation(ctx, client)
+	if oerr != nil {
+		span.SetStatus(codes.Error, "client or api sunset")
+		return nil, oerr
+	}
+
 	// Look up the refresh token by hash.
 	tokenHash := crypto.HashRefreshToken(req.RefreshToken)
 	storedToken, err := s.refreshTokenRepo.FindByTokenHash(tokenHash)
@@ -278,6 +327,24 @@ func (s *oauthTokenService) exchangeRefreshToken(ctx context.Context, req dto.OA
 		return nil, apperror.NewOAuthInvalidGrant("the refresh token was not issued to this client")
 	}
 
+	// End sessions that were idle too long or have outlived their lifetime.
+	policy, err := tenantSessionPolicy(s.securitySettingRepo, client.TenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "session policy lookup failed")
+		return nil, apperror.NewOAuthServerError("an unexpected error occurred")
+	}
+	timeout, err := s.sessionTimeout(policy, storedToken, time.Now())
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "session start lookup failed")
+		return nil, apperror.NewOAuthServerError("an unexpected error occurred")
+	}
+	if timeout != "" {
+		span.SetStatus(codes.Error, "session timed out")
+		return nil, s.refuseTimedOutSession(ctx, storedToken, timeout)
+	}
+
 	// Rotate: revoke the old token and issue a new one in the same family.
 	var result *dto.OAuthTokenResult
 	txErr := s.db.Transaction(func(tx *gorm.DB) error {
@@ -307,34 +374,14 @@ func (s *oauthTokenService) exchangeRefreshToken(ctx context.Context, req dto.OA
 		}
 
 		// Generate new access + ID tokens.
-		result, oerr = s.generateTokens(ctx, sub, user, client, scope, nil)
+		result, oerr = s.issueTokens(ctx, sub, user, client, scope, nil, storedToken.SessionID)
 		if oerr != nil {
 			return oerr
 		}
 
 		// Create the new refresh token in the same family.
-		rawRT, err := crypto.GenerateRandomString(refreshTokenByteLength)
-		if err != nil {
-			return err
-		}
-		rtHash := crypto.HashRefreshToken(rawRT)
-
-		rtTTL := s.refreshTokenTTL(client)
-		newToken := &model.OAuthRefreshToken{
-			TokenHash: rtHash,
-			FamilyID:  storedToken.FamilyID,
-			ClientID:  client.ClientID,
-			UserID:    storedToken.UserID,
-			TenantID:  client.TenantID,
-			Scope:     scope,
-			ExpiresAt: time.Now().Add(rtTTL),
-		}
-		if _, err := txRefreshRepo.Create(newToken); err != nil {
-			return err
-		}
-
-		result.RefreshToken = rawRT
-		return nil
+		result.RefreshToken, err = s.createRefreshToken(txRefreshRepo, client, user.UserID, scope, storedToken.FamilyID, storedToken.SessionID)
+		return err
 	})
 
 	if txErr != nil {
@@ -346,6 +393,7 @@ func (s *oauthTokenService) exchangeRefreshToken(ctx context.Context, req dto.OA
 		span.SetStatus(codes.Error, "refresh token rotation failed")
 		return nil, apperror.NewOAuthServerError("an unexpected error occurred")
 	}
+	result.Deprecation = deprecation
 
 	s.authEventService.Log(ctx, AuthEventInput{
 		TenantID:    client.TenantID,
@@ -364,7 +412,7 @@ func (s *oauthTokenService) exchangeRefreshToken(ctx context.Context, req dto.OA
 }
 
 // exchangeClientCredentials handles the client_credentials grant (RFC 6749 §4.4).
-func (s *oauthTokenService) exchangeClientCredentials(ctx context.Context, _ dto.OAuthTokenRequestDTO, creds dto.OAuthClientCredentials) (*dto.OAuthTokenResult, *apperror.OAuthError) {
+func (s *oauthTokenService) exchangeClientCredentials(ctx context.Context, req dto.OAuthTokenRequestDTO, creds dto.OAuthClientCredentials) (*dto.OAuthTokenResult, *apperror.OAuthError) {
 	_, span := otel.Tracer("service").Start(ctx, "oauth_token.exchange_client_credentials")
 	defer span.End()
 
@@ -374,6 +422,13 @@ func (s *oauthTokenService) exchangeClientCredentials(ctx context.Context, _ dto
 		return nil, oerr
 	}
 
+	// Refuse sunset clients/APIs and flag deprecated ones.
+	deprecation, oerr := s.enforceDeprecation(ctx, client)
+	if oerr != nil {
+		span.SetStatus(codes.Error, "client or api sunset")
+		return nil, oerr
+	}
+
 	// The client must have the client_credentials grant enabled.
 	if !hasGrant(client, model.GrantTypeClientCredentials) {
 		span.SetStatus(codes.Error, "client_credentials grant not allowed")
@@ -386,6 +441,7 @@ func (s *oauthTokenService) exchangeClientCredentials(ctx context.Context, _ dto
 	audience := ""
 	identifier := ""
 	providerID := ""
+	scope := ""
 	if client.Domain != nil {
 		issuer = *client.Domain
 	}
@@ -397,9 +453,27 @@ func (s *oauthTokenService) exchangeClientCredentials(ctx context.Context, _ dto
 		providerID = client.IdentityProvider.Identifier
 	}
 
+	// A token for an API carries that API as its audience and only the
+	// permissions the client was granted on it.
+	if req.Audience != "" {
+		span.SetAttributes(attribute.String("oauth.audience", req.Audience))
+		api, grantedScope, oerr := s.resolveAudience(client, req.Audience, req.Scope)
+		if oerr != nil {
+			span.SetStatus(codes.Error, "audience refused")
+			return nil, oerr
+		}
+		audience = api.Identifier
+		scope = grantedScope
+	}
+
+	if !middleware.MeterTokenIssued(ctx, client.TenantID) {
+		span.SetStatus(codes.Error, "token quota exceeded")
+		return nil, apperror.NewOAuthTooManyRequests("monthly token quota exceeded")
+	}
+
 	accessToken, err := jwt.GenerateAccessToken(
 		identifier,
-		"", // no user scope for m2m
+		scope,
 		issuer,
 		audience,
 		identifier,
@@ -419,8 +493,8 @@ func (s *oauthTokenService) exchangeClientCredentials(ctx context.Context, _ dto
 		EventType: model.AuthEventTypeOAuthClientAuth,
 		Severity:  model.AuthEventSeverityInfo,
 		Result:    model.AuthEventResultSuccess,
-		Description: ptr.Ptr(fmt.Sprintf("Client credentials token issued for client %s",
-			identifier)),
+		Description: ptr.Ptr(fmt.Sprintf("Client credentials token issued for client %s and audience %s",
+			identifier, audience)),
 	})
 
 	expiresIn := int64(jwt.AccessTokenTTL.Seconds())
@@ -433,9 +507,60 @@ func (s *oauthTokenService) exchangeClientCredentials(ctx context.Context, _ dto
 		AccessToken: accessToken,
 		TokenType:   "Bearer",
 		ExpiresIn:   expiresIn,
+		Scope:       scope,
+		Deprecation: deprecation,
 	}, nil
 }
 
+// resolveAudience finds the active API of the client's tenant whose
+// identifier is audience and returns it with the scope to issue. The client
+// must be assigned the API, and every requested scope must be an active
+// permission the client holds on it. An empty request is granted all of
+// them.
+func (s *oauthTokenService) resolveAudience(client *model.Client, audience, requestedScope string) (*model.API, string, *apperror.OAuthError) {
+	api, err := s.apiRepo.FindByIdentifier(audience, client.TenantID)
+	if err != nil {
+		return nil, "", apperror.NewOAuthServerError("an unexpected error occurred")
+	}
+	if api == nil || api.Status != model.StatusActive {
+		return nil, "", apperror.NewOAuthInvalidTarget(fmt.Sprintf("unknown audience %s", audience))
+	}
+
+	clientAPI, err := s.clientAPIRepo.FindByClientAndAPI(client.ClientID, api.APIID)
+	if err != nil {
+		return nil, "", apperror.NewOAuthServerError("an unexpected error occurred")
+	}
+	if clientAPI == nil {
+		return nil, "", apperror.NewOAuthUnauthorizedClient(fmt.Sprintf("client is not authorized for audience %s", audience))
+	}
+
+	clientPermissions, err := s.clientPermissionRepo.FindByClientAPIID(clientAPI.ClientAPIID)
+	if err != nil {
+		return nil, "", apperror.NewOAuthServerError("an unexpected error occurred")
+	}
+	var granted []string
+	for _, cp := range clientPermissions {
+		if cp.Permission != nil && cp.Permission.Status == model.StatusActive {
+			granted = append(granted, cp.Permission.Name)
+		}
+	}
+
+	requested := strings.Fields(requestedScope)
+	if len(requested) == 0 {
+		return api, strings.Join(granted, " "), nil
+	}
+	var scopes []string
+	for _, name := range requested {
+		if !slices.Contains(granted, name) {
+			return nil, "", apperror.NewOAuthInvalidScope(fmt.Sprintf("scope %s is not granted for audience %s", name, audience))
+		}
+		if !slices.Contains(scopes, name) {
+			scopes = append(scopes, name)
+		}
+	}
+	return api, strings.Join(scopes, " "), nil
+}
+
 // Revoke implements OAuthTokenService.
 func (s *oauthTokenService) Revoke(ctx context.Context, req dto.OAuthRevokeRequestDTO, creds dto.OAuthClientCredentials) *apperror.OAuthError {
 	_, span := otel.Tracer("service").Start(ctx, "oauth_token.revoke")
@@ -528,7 +653,7 @@ func (s *oauthTokenService) Introspect(ctx context.Context, req dto.OAuthIntrosp
 	// Try as a refresh token.
 	tokenHash := crypto.HashRefreshToken(req.Token)
 	storedRT, lookupErr := s.refreshTokenRepo.FindByTokenHash(tokenHash)
-	if lookupErr == nil && storedRT != nil && storedRT.IsActive() {
+	if lookupErr == nil && storedRT != nil && storedRT.IsActive() && s.sessionLive(storedRT) {
 		resp := &dto.OAuthIntrospectResponseDTO{
 			Active:    true,
 			TokenType: "refresh_token",
@@ -578,7 +703,7 @@ func (s *oauthTokenService) authenticateClient(ctx context.Context, creds dto.OA
 	case model.TokenAuthMethodNone:
 		// Public clients (SPA/mobile) do not have a secret.
 	case model.TokenAuthMethodSecretBasic, model.TokenAuthMethodSecretPost:
-		if client.Secret == nil || creds.ClientSecret != *client.Secret {
+		if !client.MatchesSecret(creds.ClientSecret, time.Now()) {
 			s.logClientAuthFail(ctx, client.TenantID, "invalid client_secret")
 			return nil, apperror.NewOAuthInvalidClient("client authentication failed")
 		}
@@ -589,8 +714,32 @@ func (s *oauthTokenService) authenticateClient(ctx context.Context, creds dto.OA
 	return client, nil
 }
 
-// generateTokens creates an access token, ID token, and a new refresh token.
-func (s *oauthTokenService) generateTokens(ctx context.Context, sub string, user *model.User, client *model.Client, scope string, nonce *string) (*dto.OAuthTokenResult, *apperror.OAuthError) {
+// generateTokens creates an access token, ID token, and a refresh token that
+// starts a new family.
+//
+// sessionID is the login session the tokens belong to. It is issued as the
+// sid claim and recorded on the refresh token, so that ending the session
+// revokes the token and notifies the client.
+func (s *oauthTokenService) generateTokens(ctx context.Context, sub string, user *model.User, client *model.Client, scope string, nonce, sessionID *string) (*dto.OAuthTokenResult, *apperror.OAuthError) {
+	result, oerr := s.issueTokens(ctx, sub, user, client, scope, nonce, sessionID)
+	if oerr != nil {
+		return nil, oerr
+	}
+	refreshToken, err := s.createRefreshToken(s.refreshTokenRepo, client, user.UserID, scope, uuid.New(), sessionID)
+	if err != nil {
+		return nil, apperror.NewOAuthServerError("an unexpected error occurred")
+	}
+	result.RefreshToken = refreshToken
+	return result, nil
+}
+
+// issueTokens creates an access token and ID token, leaving the refresh
+// token to the caller.
+func (s *oauthTokenService) issueTokens(ctx context.Context, sub string, user *model.User, client *model.Client, scope string, nonce, sessionID *string) (*dto.OAuthTokenResult, *apperror.OAuthError) {
+	if !middleware.MeterTokenIssued(ctx, client.TenantID) {
+		return nil, apperror.NewOAuthTooManyRequests("monthly token quota exceeded")
+	}
+
 	issuer := ""
 	audience := ""
 	identifier := ""
@@ -606,7 +755,17 @@ func (s *oauthTokenService) generateTokens(ctx context.Context, sub string, user
 		providerID = client.IdentityProvider.Identifier
 	}
 
-	accessToken, err := jwt.GenerateAccessToken(sub, scope, issuer, audience, identifier, providerID)
+	extraClaims, err := enrichTokenClaims(ctx, s.claimsFetcher, client, sub, user, scope)
+	if err != nil {
+		return nil, apperror.NewOAuthServerError("an unexpected error occurred")
+	}
+
+	var sessionClaims map[string]any
+	if sessionID != nil {
+		sessionClaims = map[string]any{"sid": *sessionID}
+	}
+
+	accessToken, err := jwt.GenerateAccessTokenWithClaims(sub, scope, issuer, audience, identifier, providerID, mergeClaims(extraClaims, sessionClaims))
 	if err != nil {
 		return nil, apperror.NewOAuthServerError("an unexpected error occurred")
 	}
@@ -623,48 +782,48 @@ func (s *oauthTokenService) generateTokens(ctx context.Context, sub string, user
 		PhoneVerified: user.IsPhoneVerified,
 	}
 
-	idToken, err := jwt.GenerateIDToken(sub, issuer, identifier, providerID, profile, nonceStr)
-	if err != nil {
-		return nil, apperror.NewOAuthServerError("an unexpected error occurred")
-	}
-
-	// Generate refresh token.
-	rawRT, err := crypto.GenerateRandomString(refreshTokenByteLength)
+	idToken, err := jwt.GenerateIDTokenWithClaims(sub, issuer, identifier, providerID, profile, nonceStr, sessionClaims)
 	if err != nil {
 		return nil, apperror.NewOAuthServerError("an unexpected error occurred")
 	}
-	rtHash := crypto.HashRefreshToken(rawRT)
-
-	rtTTL := s.refreshTokenTTL(client)
-	newRT := &model.OAuthRefreshToken{
-		TokenHash: rtHash,
-		FamilyID:  uuid.New(),
-		ClientID:  client.ClientID,
-		UserID:    user.UserID,
-		TenantID:  client.TenantID,
-		Scope:     scope,
-		ExpiresAt: time.Now().Add(rtTTL),
-	}
-	if _, err := s.refreshTokenRepo.Create(newRT); err != nil {
-		return nil, apperror.NewOAuthServerError("an unexpected error occurred")
-	}
 
 	expiresIn := int64(jwt.AccessTokenTTL.Seconds())
 	if client.AccessTokenTTL != nil {
 		expiresIn = int64(*client.AccessTokenTTL)
 	}
 
-	_ = ctx // used by callers for auth event logging
 	return &dto.OAuthTokenResult{
-		AccessToken:  accessToken,
-		TokenType:    "Bearer",
-		ExpiresIn:    expiresIn,
-		RefreshToken: rawRT,
-		IDToken:      idToken,
-		Scope:        scope,
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   expiresIn,
+		IDToken:     idToken,
+		Scope:       scope,
 	}, nil
 }
 
+// createRefreshToken stores a new refresh token of family familyID with
+// refreshTokenRepo and returns the raw token.
+func (s *oauthTokenService) createRefreshToken(refreshTokenRepo repository.OAuthRefreshTokenRepository, client *model.Client, userID int64, scope string, familyID uuid.UUID, sessionID *string) (string, error) {
+	rawRT, err := crypto.GenerateRandomString(refreshTokenByteLength)
+	if err != nil {
+		return "", err
+	}
+	_, err = refreshTokenRepo.Create(&model.OAuthRefreshToken{
+		TokenHash: crypto.HashRefreshToken(rawRT),
+		FamilyID:  familyID,
+		ClientID:  client.ClientID,
+		UserID:    userID,
+		TenantID:  client.TenantID,
+		Scope:     scope,
+		SessionID: sessionID,
+		ExpiresAt: time.Now().Add(s.refreshTokenTTL(client)),
+	})
+	if err != nil {
+		return "", err
+	}
+	return rawRT, nil
+}
+
 // resolveUserSub looks up the user identity sub claim for the given
 // user-client pair. Identity records are created during registration and
 // login — the OAuth layer only reads them. Returns an error if no identity
@@ -703,6 +862,107 @@ func (s *oauthTokenService) logClientAuthFail(ctx context.Context, tenantID int6
 	})
 }
 
+// enforceDeprecation checks the lifecycle of the client and of every API
+// linked to it. A client or API past its sunset date blocks token issuance.
+// Otherwise, when anything is deprecated, the usage is recorded and a notice
+// describing the earliest deprecation and sunset is returned so the handler
+// can emit warning headers. A nil notice means nothing is deprecated.
+func (s *oauthTokenService) enforceDeprecation(ctx context.Context, client *model.Client) (*dto.OAuthDeprecationNotice, *apperror.OAuthError) {
+	now := time.Now()
+
+	if client.IsSunset(now) {
+		s.logSunsetBlocked(ctx, client.TenantID, fmt.Sprintf("client %s reached its sunset date", client.Name))
+		return nil, apperror.NewOAuthUnauthorizedClient("client has reached its sunset date and can no longer obtain tokens")
+	}
+
+	apis, err := s.apiRepo.FindDeprecatedByClientID(client.ClientID)
+	if err != nil {
+		return nil, apperror.NewOAuthServerError("an unexpected error occurred")
+	}
+	for _, api := range apis {
+		if api.IsSunset(now) {
+			s.logSunsetBlocked(ctx, client.TenantID, fmt.Sprintf("api %s reached its sunset date", api.Identifier))
+			return nil, apperror.NewOAuthUnauthorizedClient(fmt.Sprintf("api %s has reached its sunset date and can no longer be used", api.Identifier))
+		}
+	}
+
+	var notice *dto.OAuthDeprecationNotice
+	merge := func(d model.Deprecation, fallback string) {
+		if !d.IsDeprecated() {
+			return
+		}
+		message := fallback
+		if d.DeprecationNotice != nil && *d.DeprecationNotice != "" {
+			message = *d.DeprecationNotice
+		}
+		if notice == nil {
+			notice = &dto.OAuthDeprecationNotice{DeprecatedAt: *d.DeprecatedAt, Message: message}
+		} else if d.DeprecatedAt.Before(notice.DeprecatedAt) {
+			notice.DeprecatedAt = *d.DeprecatedAt
+		}
+		if d.SunsetAt != nil && (notice.SunsetAt == nil || d.SunsetAt.Before(*notice.SunsetAt)) {
+			notice.SunsetAt = d.SunsetAt
+			notice.Message = message
+		}
+	}
+
+	merge(client.Deprecation, fmt.Sprintf("client %s is deprecated", client.Name))
+	if client.IsDeprecated() {
+		if err := s.clientRepo.RecordDeprecatedUsage(client.ClientID); err != nil {
+			return nil, apperror.NewOAuthServerError("an unexpected error occurred")
+		}
+	}
+
+	apiIDs := make([]int64, 0, len(apis))
+	for _, api := range apis {
+		merge(api.Deprecation, fmt.Sprintf("api %s is deprecated", api.Identifier))
+		apiIDs = append(apiIDs, api.APIID)
+	}
+	if err := s.apiRepo.RecordDeprecatedUsage(apiIDs); err != nil {
+		return nil, apperror.NewOAuthServerError("an unexpected error occurred")
+	}
+
+	if notice != nil {
+		s.authEventService.Log(ctx, AuthEventInput{
+			TenantID:    client.TenantID,
+			IPAddress:   middleware.ClientIPFromContext(ctx),
+			UserAgent:   ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+			Category:    model.AuthEventCategoryAuthn,
+			EventType:   model.AuthEventTypeOAuthDeprecatedUse,
+			Severity:    model.AuthEventSeverityWarn,
+			Result:      model.AuthEventResultSuccess,
+			Description: ptr.Ptr(notice.Message),
+		})
+	}
+
+	return notice, nil
+}
+
+// logSunsetBlocked logs a token request refused because of a sunset date.
+func (s *oauthTokenService) logSunsetBlocked(ctx context.Context, tenantID int64, reason string) {
+	s.authEventService.Log(ctx, AuthEventInput{
+		TenantID:    tenantID,
+		IPAddress:   middleware.ClientIPFromContext(ctx),
+		UserAgent:   ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:    model.AuthEventCategoryAuthn,
+		EventType:   model.AuthEventTypeOAuthSunsetBlocked,
+		Severity:    model.AuthEventSeverityWarn,
+		Result:      model.AuthEventResultFailure,
+		Description: ptr.Ptr(reason),
+	})
+}
+
+// tokenGrantLabel bounds the grant_type metric label to the supported grants so
+// arbitrary client input cannot inflate label cardinality.
+func tokenGrantLabel(grantType string) string {
+	switch grantType {
+	case model.GrantTypeAuthorizationCode, model.GrantTypeRefreshToken, model.GrantTypeClientCredentials:
+		return grantType
+	default:
+		return "unsupported"
+	}
+}
+
 // hasGrant checks whether the client has the given grant type.
 func hasGrant(client *model.Client, grantType string) bool {
 	for _, g := range client.GrantTypes {
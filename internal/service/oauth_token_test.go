@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"net/http"
 	"regexp"
 	"testing"
 	"time"
@@ -10,10 +11,12 @@ import (
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"github.com/maintainerd/auth/internal/apperror"
 	"github.com/maintainerd/auth/internal/crypto"
 	"github.com/maintainerd/auth/internal/dto"
 	"github.com/maintainerd/auth/internal/jwt"
 	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
@@ -30,7 +33,7 @@ func newOAuthTokenSvc(
 	userIdentityRepo *mockUserIdentityRepo,
 	authEventSvc *mockAuthEventService,
 ) OAuthTokenService {
-	return NewOAuthTokenService(db, clientRepo, authCodeRepo, refreshTokenRepo, userRepo, userIdentityRepo, authEventSvc)
+	return NewOAuthTokenService(db, clientRepo, &mockAPIRepo{}, &mockClientAPIRepo{}, &mockClientPermissionRepo{}, authCodeRepo, refreshTokenRepo, userRepo, userIdentityRepo, &mockSecuritySettingRepo{}, authEventSvc, nil)
 }
 
 func mockClientRows() *sqlmock.Rows {
@@ -497,6 +500,63 @@ func TestOAuthTokenService_Exchange(t *testing.T) {
 		assert.Equal(t, "openid profile", result.Scope)
 	})
 
+	t.Run("authorization_code — session limit reached", func(t *testing.T) {
+		verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+		challenge := crypto.ComputeS256Challenge(verifier)
+
+		db, mock := newMockDB(t)
+		expectClientLookup(mock, mockClientRows())
+
+		refreshTokenRepo := &mockOAuthRefreshTokenRepo{
+			findActiveSessionsFn: func(_ int64) ([]repository.OAuthSession, error) {
+				return activeSessions(1), nil
+			},
+			createFn: func(_ *model.OAuthRefreshToken) (*model.OAuthRefreshToken, error) {
+				t.Fatal("no refresh token should be issued")
+				return nil, nil
+			},
+		}
+		svc := NewOAuthTokenService(db, &mockClientRepo{}, &mockAPIRepo{}, &mockClientAPIRepo{}, &mockClientPermissionRepo{},
+			&mockOAuthAuthCodeRepo{
+				findByCodeHashFn: func(_ string) (*model.OAuthAuthorizationCode, error) {
+					return &model.OAuthAuthorizationCode{
+						OAuthAuthorizationCodeID: 1,
+						ClientID:                 10,
+						UserID:                   1,
+						TenantID:                 1,
+						RedirectURI:              "https://example.com/callback",
+						Scope:                    "openid",
+						CodeChallenge:            challenge,
+						CodeChallengeMethod:      "S256",
+						ExpiresAt:                time.Now().Add(10 * time.Minute),
+					}, nil
+				},
+			},
+			refreshTokenRepo,
+			&mockUserRepo{
+				findByIDFn: func(_ any, _ ...string) (*model.User, error) {
+					return &model.User{UserID: 1, UserUUID: uuid.New()}, nil
+				},
+			},
+			&mockUserIdentityRepo{
+				findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) {
+					return &model.UserIdentity{Sub: "user-sub-123"}, nil
+				},
+			},
+			sessionConfigRepo(`{"max_concurrent_sessions":1,"session_limit_policy":"reject_new"}`),
+			&mockAuthEventService{}, nil)
+
+		_, oerr := svc.Exchange(ctx, dto.OAuthTokenRequestDTO{
+			GrantType:    "authorization_code",
+			Code:         "code123",
+			RedirectURI:  "https://example.com/callback",
+			CodeVerifier: verifier,
+		}, dto.OAuthClientCredentials{ClientID: "my-client"})
+		require.NotNil(t, oerr)
+		assert.Equal(t, "invalid_grant", oerr.Code)
+		assert.Equal(t, apperror.TypeBase+string(apperror.CodeSessionLimitReached), oerr.URI)
+	})
+
 	t.Run("authorization_code — auth code lookup error", func(t *testing.T) {
 		db, mock := newMockDB(t)
 		expectClientLookup(mock, mockClientRows())
@@ -638,6 +698,72 @@ func TestOAuthTokenService_Exchange_RefreshToken(t *testing.T) {
 		assert.Contains(t, oerr.Description, "not issued to this client")
 	})
 
+	t.Run("session idle timeout", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		expectClientLookup(mock, mockClientRows())
+
+		var events []AuthEventInput
+		svc := NewOAuthTokenService(db, &mockClientRepo{}, &mockAPIRepo{}, &mockClientAPIRepo{}, &mockClientPermissionRepo{}, &mockOAuthAuthCodeRepo{},
+			&mockOAuthRefreshTokenRepo{
+				findByTokenHashFn: func(_ string) (*model.OAuthRefreshToken, error) {
+					return &model.OAuthRefreshToken{
+						ClientID:  10,
+						UserID:    1,
+						TenantID:  1,
+						ExpiresAt: time.Now().Add(7 * 24 * time.Hour),
+						CreatedAt: time.Now().Add(-time.Hour),
+					}, nil
+				},
+			},
+			&mockUserRepo{},
+			&mockUserIdentityRepo{findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) { return nil, nil }},
+			sessionConfigRepo(`{"idle_timeout_minutes":30}`),
+			&mockAuthEventService{logFn: func(_ context.Context, input AuthEventInput) { events = append(events, input) }}, nil)
+
+		_, oerr := svc.Exchange(ctx, dto.OAuthTokenRequestDTO{
+			GrantType:    "refresh_token",
+			RefreshToken: "some-token",
+		}, dto.OAuthClientCredentials{ClientID: "my-client"})
+		require.NotNil(t, oerr)
+		assert.Equal(t, "invalid_grant", oerr.Code)
+		assert.Equal(t, apperror.TypeBase+string(apperror.CodeSessionIdleTimeout), oerr.URI)
+		require.Len(t, events, 1)
+		assert.Equal(t, model.AuthEventTypeSessionExpired, events[0].EventType)
+	})
+
+	t.Run("session lifetime exceeded", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		expectClientLookup(mock, mockClientRows())
+
+		svc := NewOAuthTokenService(db, &mockClientRepo{}, &mockAPIRepo{}, &mockClientAPIRepo{}, &mockClientPermissionRepo{}, &mockOAuthAuthCodeRepo{},
+			&mockOAuthRefreshTokenRepo{
+				findByTokenHashFn: func(_ string) (*model.OAuthRefreshToken, error) {
+					return &model.OAuthRefreshToken{
+						ClientID:  10,
+						UserID:    1,
+						TenantID:  1,
+						ExpiresAt: time.Now().Add(7 * 24 * time.Hour),
+						CreatedAt: time.Now().Add(-time.Minute),
+					}, nil
+				},
+				findSessionStartedAtFn: func(uuid.UUID, *string) (time.Time, error) {
+					return time.Now().Add(-25 * time.Hour), nil
+				},
+			},
+			&mockUserRepo{},
+			&mockUserIdentityRepo{findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) { return nil, nil }},
+			sessionConfigRepo(`{"idle_timeout_minutes":30,"absolute_timeout_hours":24}`),
+			&mockAuthEventService{}, nil)
+
+		_, oerr := svc.Exchange(ctx, dto.OAuthTokenRequestDTO{
+			GrantType:    "refresh_token",
+			RefreshToken: "some-token",
+		}, dto.OAuthClientCredentials{ClientID: "my-client"})
+		require.NotNil(t, oerr)
+		assert.Equal(t, "invalid_grant", oerr.Code)
+		assert.Equal(t, apperror.TypeBase+string(apperror.CodeSessionLifetimeExceeded), oerr.URI)
+	})
+
 	t.Run("transaction error", func(t *testing.T) {
 		db, mock := newMockDB(t)
 		expectClientLookup(mock, mockClientRows())
@@ -675,6 +801,7 @@ func TestOAuthTokenService_Exchange_RefreshToken(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectCommit()
 
+		var created []*model.OAuthRefreshToken
 		svc := newOAuthTokenSvc(db, &mockClientRepo{}, &mockOAuthAuthCodeRepo{},
 			&mockOAuthRefreshTokenRepo{
 				findByTokenHashFn: func(_ string) (*model.OAuthRefreshToken, error) {
@@ -689,6 +816,10 @@ func TestOAuthTokenService_Exchange_RefreshToken(t *testing.T) {
 					}, nil
 				},
 				revokeByIDFn: func(_ int64) error { return nil },
+				createFn: func(e *model.OAuthRefreshToken) (*model.OAuthRefreshToken, error) {
+					created = append(created, e)
+					return e, nil
+				},
 			},
 			&mockUserRepo{
 				findByIDFn: func(_ any, _ ...string) (*model.User, error) {
@@ -713,6 +844,11 @@ func TestOAuthTokenService_Exchange_RefreshToken(t *testing.T) {
 		assert.NotEmpty(t, result.RefreshToken)
 		assert.Equal(t, "Bearer", result.TokenType)
 		assert.Equal(t, "openid profile", result.Scope)
+
+		// Rotation stays in the family and starts no other one.
+		require.Len(t, created, 1)
+		assert.Equal(t, familyID, created[0].FamilyID)
+		assert.Equal(t, crypto.HashRefreshToken(result.RefreshToken), created[0].TokenHash)
 	})
 
 	t.Run("full success — with scope narrowing", func(t *testing.T) {
@@ -946,6 +1082,185 @@ func TestOAuthTokenService_Exchange_ClientCredentials(t *testing.T) {
 		require.Nil(t, oerr)
 		assert.Equal(t, int64(3600), result.ExpiresIn)
 	})
+
+	t.Run("token quota exceeded", func(t *testing.T) {
+		initTestJWTKeysService(t)
+		db, mock := newMockDB(t)
+		rows := sqlmock.NewRows([]string{
+			"client_id", "client_uuid", "tenant_id", "identity_provider_id", "name", "display_name",
+			"client_type", "domain", "identifier", "secret", "status",
+			"is_default", "is_system", "token_endpoint_auth_method",
+			"grant_types", "response_types", "access_token_ttl", "refresh_token_ttl",
+			"require_consent", "created_at", "updated_at",
+		}).AddRow(
+			10, uuid.New(), 1, int64(100), "m2m-client", "M2M Client",
+			"m2m", "https://auth.example.com", "m2m-client", nil, "active",
+			false, false, "none",
+			`{client_credentials}`, `{}`, nil, nil,
+			false, time.Now(), time.Now(),
+		)
+		expectClientLookup(mock, rows)
+
+		svc := newOAuthTokenSvc(db, &mockClientRepo{}, &mockOAuthAuthCodeRepo{}, &mockOAuthRefreshTokenRepo{}, &mockUserRepo{},
+			&mockUserIdentityRepo{findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) { return nil, nil }},
+			&mockAuthEventService{})
+
+		_, oerr := svc.Exchange(usageMeterContext(&fakeTenantUsageStore{}), dto.OAuthTokenRequestDTO{
+			GrantType: "client_credentials",
+		}, dto.OAuthClientCredentials{ClientID: "m2m-client"})
+		require.NotNil(t, oerr)
+		assert.Equal(t, "temporarily_unavailable", oerr.Code)
+		assert.Equal(t, http.StatusTooManyRequests, oerr.StatusCode)
+	})
+
+	rotatedClientRows := func(previousExpiresAt time.Time) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{
+			"client_id", "client_uuid", "tenant_id", "identity_provider_id", "name", "display_name",
+			"client_type", "domain", "identifier", "secret", "previous_secret", "previous_secret_expires_at", "status",
+			"is_default", "is_system", "token_endpoint_auth_method",
+			"grant_types", "response_types", "access_token_ttl", "refresh_token_ttl",
+			"require_consent", "created_at", "updated_at",
+		}).AddRow(
+			10, uuid.New(), 1, int64(100), "m2m-client", "M2M Client",
+			"m2m", "https://auth.example.com", "m2m-client", "new-secret", "old-secret", previousExpiresAt, "active",
+			false, false, "client_secret_basic",
+			`{client_credentials}`, `{}`, nil, nil,
+			false, time.Now(), time.Now(),
+		)
+	}
+
+	t.Run("previous secret authenticates during grace period", func(t *testing.T) {
+		initTestJWTKeysService(t)
+		db, mock := newMockDB(t)
+		expectClientLookup(mock, rotatedClientRows(time.Now().Add(time.Hour)))
+
+		svc := newOAuthTokenSvc(db, &mockClientRepo{}, &mockOAuthAuthCodeRepo{}, &mockOAuthRefreshTokenRepo{}, &mockUserRepo{},
+			&mockUserIdentityRepo{findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) { return nil, nil }},
+			&mockAuthEventService{})
+
+		result, oerr := svc.Exchange(ctx, dto.OAuthTokenRequestDTO{
+			GrantType: "client_credentials",
+		}, dto.OAuthClientCredentials{ClientID: "m2m-client", ClientSecret: "old-secret"})
+		require.Nil(t, oerr)
+		assert.NotEmpty(t, result.AccessToken)
+	})
+
+	t.Run("previous secret is rejected after grace period", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		expectClientLookup(mock, rotatedClientRows(time.Now().Add(-time.Minute)))
+
+		svc := newOAuthTokenSvc(db, &mockClientRepo{}, &mockOAuthAuthCodeRepo{}, &mockOAuthRefreshTokenRepo{}, &mockUserRepo{},
+			&mockUserIdentityRepo{findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) { return nil, nil }},
+			&mockAuthEventService{})
+
+		_, oerr := svc.Exchange(ctx, dto.OAuthTokenRequestDTO{
+			GrantType: "client_credentials",
+		}, dto.OAuthClientCredentials{ClientID: "m2m-client", ClientSecret: "old-secret"})
+		require.NotNil(t, oerr)
+		assert.Equal(t, "invalid_client", oerr.Code)
+	})
+}
+
+func TestOAuthTokenService_Exchange_ClientCredentialsAudience(t *testing.T) {
+	ctx := context.Background()
+	const audience = "https://billing.example.com"
+
+	billingAPI := &model.API{APIID: 7, TenantID: 1, Identifier: audience, Status: model.StatusActive}
+	permission := func(name, status string) model.ClientPermission {
+		return model.ClientPermission{Permission: &model.Permission{Name: name, Status: status}}
+	}
+
+	exchange := func(t *testing.T, api *model.API, clientAPI *model.ClientAPI, scope string) (*dto.OAuthTokenResult, *apperror.OAuthError) {
+		t.Helper()
+		initTestJWTKeysService(t)
+		db, mock := newMockDB(t)
+		expectClientLookup(mock, sqlmock.NewRows([]string{
+			"client_id", "client_uuid", "tenant_id", "identity_provider_id", "name", "display_name",
+			"client_type", "domain", "identifier", "secret", "status",
+			"is_default", "is_system", "token_endpoint_auth_method",
+			"grant_types", "response_types", "access_token_ttl", "refresh_token_ttl",
+			"require_consent", "created_at", "updated_at",
+		}).AddRow(
+			10, uuid.New(), 1, int64(100), "m2m-client", "M2M Client",
+			"m2m", "https://auth.example.com", "m2m-client", nil, "active",
+			false, false, "none",
+			`{client_credentials}`, `{}`, nil, nil,
+			false, time.Now(), time.Now(),
+		))
+
+		apiRepo := &mockAPIRepo{findByIdentifierFn: func(identifier string, tenantID int64) (*model.API, error) {
+			assert.Equal(t, int64(1), tenantID)
+			if api == nil || identifier != api.Identifier {
+				return nil, nil
+			}
+			return api, nil
+		}}
+		clientAPIRepo := &mockClientAPIRepo{findByClientAndAPIFn: func(clientID, apiID int64) (*model.ClientAPI, error) {
+			assert.Equal(t, int64(10), clientID)
+			assert.Equal(t, int64(7), apiID)
+			return clientAPI, nil
+		}}
+		clientPermissionRepo := &mockClientPermissionRepo{findByClientAPIIDFn: func(int64) ([]model.ClientPermission, error) {
+			return []model.ClientPermission{
+				permission("invoice:read", model.StatusActive),
+				permission("invoice:write", model.StatusActive),
+				permission("invoice:delete", model.StatusInactive),
+			}, nil
+		}}
+		svc := NewOAuthTokenService(db, &mockClientRepo{}, apiRepo, clientAPIRepo, clientPermissionRepo,
+			&mockOAuthAuthCodeRepo{}, &mockOAuthRefreshTokenRepo{}, &mockUserRepo{}, &mockUserIdentityRepo{}, &mockSecuritySettingRepo{}, &mockAuthEventService{}, nil)
+		return svc.Exchange(ctx, dto.OAuthTokenRequestDTO{
+			GrantType: "client_credentials",
+			Audience:  audience,
+			Scope:     scope,
+		}, dto.OAuthClientCredentials{ClientID: "m2m-client"})
+	}
+
+	t.Run("grants all permissions on the audience", func(t *testing.T) {
+		result, oerr := exchange(t, billingAPI, &model.ClientAPI{ClientAPIID: 3}, "")
+		require.Nil(t, oerr)
+		assert.Equal(t, "invoice:read invoice:write", result.Scope)
+
+		claims, err := jwt.ValidateToken(result.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, audience, claims["aud"])
+		assert.Equal(t, "invoice:read invoice:write", claims["scope"])
+	})
+
+	t.Run("narrows to the requested scopes", func(t *testing.T) {
+		result, oerr := exchange(t, billingAPI, &model.ClientAPI{ClientAPIID: 3}, "invoice:write invoice:write")
+		require.Nil(t, oerr)
+		assert.Equal(t, "invoice:write", result.Scope)
+	})
+
+	t.Run("scope outside the audience", func(t *testing.T) {
+		for _, scope := range []string{"user:read", "invoice:delete"} {
+			_, oerr := exchange(t, billingAPI, &model.ClientAPI{ClientAPIID: 3}, "invoice:read "+scope)
+			require.NotNil(t, oerr, scope)
+			assert.Equal(t, "invalid_scope", oerr.Code)
+			assert.Contains(t, oerr.Description, scope)
+		}
+	})
+
+	t.Run("unknown audience", func(t *testing.T) {
+		_, oerr := exchange(t, nil, nil, "")
+		require.NotNil(t, oerr)
+		assert.Equal(t, "invalid_target", oerr.Code)
+	})
+
+	t.Run("inactive audience", func(t *testing.T) {
+		inactive := *billingAPI
+		inactive.Status = model.StatusInactive
+		_, oerr := exchange(t, &inactive, &model.ClientAPI{ClientAPIID: 3}, "")
+		require.NotNil(t, oerr)
+		assert.Equal(t, "invalid_target", oerr.Code)
+	})
+
+	t.Run("client not assigned to the audience", func(t *testing.T) {
+		_, oerr := exchange(t, billingAPI, nil, "")
+		require.NotNil(t, oerr)
+		assert.Equal(t, "unauthorized_client", oerr.Code)
+	})
 }
 
 // ── TestOAuthTokenService_Revoke ────────────────────────────────────────────
@@ -1143,6 +1458,30 @@ func TestOAuthTokenService_Introspect(t *testing.T) {
 		assert.False(t, result.Active)
 	})
 
+	t.Run("idle refresh token — active false", func(t *testing.T) {
+		db, _ := newMockDB(t)
+		svc := NewOAuthTokenService(db, &mockClientRepo{}, &mockAPIRepo{}, &mockClientAPIRepo{}, &mockClientPermissionRepo{}, &mockOAuthAuthCodeRepo{},
+			&mockOAuthRefreshTokenRepo{
+				findByTokenHashFn: func(_ string) (*model.OAuthRefreshToken, error) {
+					return &model.OAuthRefreshToken{
+						UserID:    1,
+						ClientID:  10,
+						TenantID:  1,
+						ExpiresAt: time.Now().Add(7 * 24 * time.Hour),
+						CreatedAt: time.Now().Add(-time.Hour),
+					}, nil
+				},
+			},
+			&mockUserRepo{},
+			&mockUserIdentityRepo{findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) { return nil, nil }},
+			sessionConfigRepo(`{"idle_timeout_minutes":30}`),
+			&mockAuthEventService{}, nil)
+
+		result, oerr := svc.Introspect(ctx, dto.OAuthIntrospectRequestDTO{Token: "rt-token"})
+		require.Nil(t, oerr)
+		assert.False(t, result.Active)
+	})
+
 	t.Run("refresh token sub resolution error — still returns active", func(t *testing.T) {
 		db, _ := newMockDB(t)
 		svc := newOAuthTokenSvc(db, &mockClientRepo{}, &mockOAuthAuthCodeRepo{},
@@ -1303,6 +1642,41 @@ func TestRefreshTokenTTL(t *testing.T) {
 	})
 }
 
+// ── TestOAuthTokenService_generateTokens_SessionID ─────────────────────────
+
+func TestOAuthTokenService_generateTokens_SessionID(t *testing.T) {
+	initTestJWTKeysService(t)
+	user := &model.User{UserID: 1, Email: "a@example.com"}
+
+	var created *model.OAuthRefreshToken
+	svc := &oauthTokenService{refreshTokenRepo: &mockOAuthRefreshTokenRepo{
+		createFn: func(e *model.OAuthRefreshToken) (*model.OAuthRefreshToken, error) {
+			created = e
+			return e, nil
+		},
+	}}
+
+	res, oerr := svc.generateTokens(context.Background(), "sub-1", user, buildActiveClient(), "openid", nil, strPtr("session-1"))
+	require.Nil(t, oerr)
+
+	accessClaims, err := jwt.ValidateToken(res.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", accessClaims["sid"])
+	idClaims, err := jwt.ValidateToken(res.IDToken)
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", idClaims["sid"])
+	require.NotNil(t, created)
+	assert.Equal(t, "session-1", *created.SessionID)
+
+	// Tokens issued outside a login session have no sid.
+	res, oerr = svc.generateTokens(context.Background(), "sub-1", user, buildActiveClient(), "openid", nil, nil)
+	require.Nil(t, oerr)
+	accessClaims, err = jwt.ValidateToken(res.AccessToken)
+	require.NoError(t, err)
+	assert.NotContains(t, accessClaims, "sid")
+	assert.Nil(t, created.SessionID)
+}
+
 // ── TestHasGrant ────────────────────────────────────────────────────────────
 
 func TestHasGrant(t *testing.T) {
@@ -1453,3 +1827,116 @@ func TestAuthenticateClient(t *testing.T) {
 		assert.Equal(t, "invalid_client", oerr.Code)
 	})
 }
+
+// ── TestOAuthTokenService_enforceDeprecation ────────────────────────────────
+
+func TestOAuthTokenService_enforceDeprecation(t *testing.T) {
+	ctx := context.Background()
+	past := time.Now().Add(-24 * time.Hour)
+	future := time.Now().Add(24 * time.Hour)
+	later := time.Now().Add(48 * time.Hour)
+
+	t.Run("nothing deprecated", func(t *testing.T) {
+		clientRecorded := false
+		svc := &oauthTokenService{
+			clientRepo:       &mockClientRepo{recordDeprecatedUsageFn: func(int64) error { clientRecorded = true; return nil }},
+			apiRepo:          &mockAPIRepo{},
+			authEventService: &mockAuthEventService{},
+		}
+		notice, oerr := svc.enforceDeprecation(ctx, &model.Client{ClientID: 1})
+		require.Nil(t, oerr)
+		assert.Nil(t, notice)
+		assert.False(t, clientRecorded)
+	})
+
+	t.Run("sunset client is blocked", func(t *testing.T) {
+		var logged AuthEventInput
+		svc := &oauthTokenService{
+			clientRepo:       &mockClientRepo{},
+			apiRepo:          &mockAPIRepo{},
+			authEventService: &mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = in }},
+		}
+		client := &model.Client{ClientID: 1, Deprecation: model.Deprecation{DeprecatedAt: &past, SunsetAt: &past}}
+		_, oerr := svc.enforceDeprecation(ctx, client)
+		require.NotNil(t, oerr)
+		assert.Equal(t, "unauthorized_client", oerr.Code)
+		assert.Equal(t, model.AuthEventTypeOAuthSunsetBlocked, logged.EventType)
+	})
+
+	t.Run("sunset api is blocked", func(t *testing.T) {
+		svc := &oauthTokenService{
+			clientRepo: &mockClientRepo{},
+			apiRepo: &mockAPIRepo{findDeprecatedByClientFn: func(int64) ([]model.API, error) {
+				return []model.API{{APIID: 5, Identifier: "legacy", Deprecation: model.Deprecation{DeprecatedAt: &past, SunsetAt: &past}}}, nil
+			}},
+			authEventService: &mockAuthEventService{},
+		}
+		_, oerr := svc.enforceDeprecation(ctx, &model.Client{ClientID: 1})
+		require.NotNil(t, oerr)
+		assert.Contains(t, oerr.Description, "legacy")
+	})
+
+	t.Run("api lookup error", func(t *testing.T) {
+		svc := &oauthTokenService{
+			clientRepo: &mockClientRepo{},
+			apiRepo: &mockAPIRepo{findDeprecatedByClientFn: func(int64) ([]model.API, error) {
+				return nil, errors.New("db error")
+			}},
+			authEventService: &mockAuthEventService{},
+		}
+		_, oerr := svc.enforceDeprecation(ctx, &model.Client{ClientID: 1})
+		require.NotNil(t, oerr)
+		assert.Equal(t, "server_error", oerr.Code)
+	})
+
+	t.Run("deprecated client and api merge earliest dates and record usage", func(t *testing.T) {
+		var recordedClient int64
+		var recordedAPIs []int64
+		var logged AuthEventInput
+		notice := "migrate to the v2 client"
+		svc := &oauthTokenService{
+			clientRepo: &mockClientRepo{recordDeprecatedUsageFn: func(id int64) error { recordedClient = id; return nil }},
+			apiRepo: &mockAPIRepo{
+				findDeprecatedByClientFn: func(int64) ([]model.API, error) {
+					return []model.API{{APIID: 5, Identifier: "legacy", Deprecation: model.Deprecation{DeprecatedAt: &past, SunsetAt: &future}}}, nil
+				},
+				recordDeprecatedUsageFn: func(ids []int64) error { recordedAPIs = ids; return nil },
+			},
+			authEventService: &mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = in }},
+		}
+		now := time.Now()
+		client := &model.Client{ClientID: 1, Name: "old", Deprecation: model.Deprecation{
+			DeprecatedAt: &now, SunsetAt: &later, DeprecationNotice: &notice,
+		}}
+
+		res, oerr := svc.enforceDeprecation(ctx, client)
+		require.Nil(t, oerr)
+		require.NotNil(t, res)
+		assert.True(t, res.DeprecatedAt.Equal(past))
+		require.NotNil(t, res.SunsetAt)
+		assert.True(t, res.SunsetAt.Equal(future))
+		assert.Equal(t, "api legacy is deprecated", res.Message)
+		assert.Equal(t, int64(1), recordedClient)
+		assert.Equal(t, []int64{5}, recordedAPIs)
+		assert.Equal(t, model.AuthEventTypeOAuthDeprecatedUse, logged.EventType)
+	})
+
+	t.Run("usage record error", func(t *testing.T) {
+		svc := &oauthTokenService{
+			clientRepo:       &mockClientRepo{recordDeprecatedUsageFn: func(int64) error { return errors.New("db error") }},
+			apiRepo:          &mockAPIRepo{},
+			authEventService: &mockAuthEventService{},
+		}
+		client := &model.Client{ClientID: 1, Deprecation: model.Deprecation{DeprecatedAt: &past}}
+		_, oerr := svc.enforceDeprecation(ctx, client)
+		require.NotNil(t, oerr)
+	})
+}
+
+func TestTokenGrantLabel(t *testing.T) {
+	assert.Equal(t, model.GrantTypeAuthorizationCode, tokenGrantLabel(model.GrantTypeAuthorizationCode))
+	assert.Equal(t, model.GrantTypeRefreshToken, tokenGrantLabel(model.GrantTypeRefreshToken))
+	assert.Equal(t, model.GrantTypeClientCredentials, tokenGrantLabel(model.GrantTypeClientCredentials))
+	assert.Equal(t, "unsupported", tokenGrantLabel("password"))
+	assert.Equal(t, "unsupported", tokenGrantLabel("<script>"))
+}
@@ -0,0 +1,653 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/database/seeder"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Onboarding checklist step keys, in the order they are presented to the
+// admin console wizard.
+const (
+	OnboardingStepIdentityProvider = "identity_provider"
+	OnboardingStepClient           = "client"
+	OnboardingStepRoles            = "roles"
+	OnboardingStepAdminUser        = "admin_user"
+	OnboardingStepEmailConfig      = "email_config"
+	OnboardingStepSignupFlow       = "signup_flow"
+	OnboardingStepBranding         = "branding"
+)
+
+// Default names used when the caller does not customise the provisioned
+// resources.
+const (
+	onboardingDefaultClientName     = "default"
+	onboardingDefaultAdminRoleName  = "admin"
+	onboardingDefaultSignupFlowName = "default-signup"
+)
+
+// OnboardingServiceClientOptions customises the client created by Onboard.
+type OnboardingServiceClientOptions struct {
+	Name        string
+	DisplayName string
+	ClientType  string
+	Domain      string
+}
+
+// OnboardingServiceAdminOptions describes the initial tenant administrator.
+type OnboardingServiceAdminOptions struct {
+	RoleName string
+	Username string
+	Fullname string
+	Email    string
+	Password string
+}
+
+// OnboardingServiceEmailOptions is the optional email delivery configuration
+// applied during onboarding. When nil the email step is left pending.
+type OnboardingServiceEmailOptions struct {
+	Provider    string
+	Host        string
+	Port        int
+	Username    string
+	Password    string
+	FromAddress string
+	FromName    string
+	ReplyTo     string
+	Encryption  string
+	TestMode    bool
+}
+
+// OnboardingServiceSignupFlowOptions customises the starter signup flow.
+// When nil no signup flow is created and the step is left pending.
+type OnboardingServiceSignupFlowOptions struct {
+	Name        string
+	Description string
+	Config      map[string]any
+}
+
+// OnboardingServiceOptions groups every customisable input of Onboard.
+type OnboardingServiceOptions struct {
+	Client     OnboardingServiceClientOptions
+	Admin      OnboardingServiceAdminOptions
+	Email      *OnboardingServiceEmailOptions
+	SignupFlow *OnboardingServiceSignupFlowOptions
+}
+
+// OnboardingServiceChecklistItem is a single step of the onboarding checklist.
+type OnboardingServiceChecklistItem struct {
+	Key       string
+	Title     string
+	Completed bool
+}
+
+// OnboardingServiceChecklistResult is the checklist for a tenant together with
+// a convenience flag set once every step is completed.
+type OnboardingServiceChecklistResult struct {
+	TenantUUID uuid.UUID
+	Steps      []OnboardingServiceChecklistItem
+	Remaining  int
+	IsComplete bool
+}
+
+// OnboardingServiceResult is returned by Onboard. It identifies every resource
+// that was provisioned and carries the refreshed checklist.
+type OnboardingServiceResult struct {
+	TenantUUID           uuid.UUID
+	IdentityProviderUUID uuid.UUID
+	ClientUUID           uuid.UUID
+	ClientIdentifier     string
+	ClientSecret         *string
+	AdminRoleUUID        uuid.UUID
+	DefaultRoleUUID      uuid.UUID
+	AdminUserUUID        uuid.UUID
+	EmailConfigUUID      *uuid.UUID
+	SignupFlowUUID       *uuid.UUID
+	Checklist            OnboardingServiceChecklistResult
+}
+
+// OnboardingService provisions the baseline resources a freshly created
+// tenant needs and reports which setup steps are still outstanding.
+type OnboardingService interface {
+	GetChecklist(ctx context.Context, tenantUUID uuid.UUID) (*OnboardingServiceChecklistResult, error)
+	Onboard(ctx context.Context, tenantUUID uuid.UUID, opts OnboardingServiceOptions) (*OnboardingServiceResult, error)
+}
+
+type onboardingService struct {
+	db                  *gorm.DB
+	tenantRepo          repository.TenantRepository
+	idpRepo             repository.IdentityProviderRepository
+	clientRepo          repository.ClientRepository
+	roleRepo            repository.RoleRepository
+	userRepo            repository.UserRepository
+	userIdentityRepo    repository.UserIdentityRepository
+	userRoleRepo        repository.UserRoleRepository
+	tenantMemberRepo    repository.TenantMemberRepository
+	emailConfigRepo     repository.EmailConfigRepository
+	signupFlowRepo      repository.SignupFlowRepository
+	signupFlowRoleRepo  repository.SignupFlowRoleRepository
+	brandingRepo        repository.BrandingRepository
+	securitySettingRepo repository.SecuritySettingRepository
+}
+
+// NewOnboardingService creates a new OnboardingService.
+func NewOnboardingService(
+	db *gorm.DB,
+	tenantRepo repository.TenantRepository,
+	idpRepo repository.IdentityProviderRepository,
+	clientRepo repository.ClientRepository,
+	roleRepo repository.RoleRepository,
+	userRepo repository.UserRepository,
+	userIdentityRepo repository.UserIdentityRepository,
+	userRoleRepo repository.UserRoleRepository,
+	tenantMemberRepo repository.TenantMemberRepository,
+	emailConfigRepo repository.EmailConfigRepository,
+	signupFlowRepo repository.SignupFlowRepository,
+	signupFlowRoleRepo repository.SignupFlowRoleRepository,
+	brandingRepo repository.BrandingRepository,
+	securitySettingRepo repository.SecuritySettingRepository,
+) OnboardingService {
+	return &onboardingService{
+		db:                  db,
+		tenantRepo:          tenantRepo,
+		idpRepo:             idpRepo,
+		clientRepo:          clientRepo,
+		roleRepo:            roleRepo,
+		userRepo:            userRepo,
+		userIdentityRepo:    userIdentityRepo,
+		userRoleRepo:        userRoleRepo,
+		tenantMemberRepo:    tenantMemberRepo,
+		emailConfigRepo:     emailConfigRepo,
+		signupFlowRepo:      signupFlowRepo,
+		signupFlowRoleRepo:  signupFlowRoleRepo,
+		brandingRepo:        brandingRepo,
+		securitySettingRepo: securitySettingRepo,
+	}
+}
+
+// GetChecklist inspects the tenant and reports which onboarding steps are
+// completed.
+func (s *onboardingService) GetChecklist(ctx context.Context, tenantUUID uuid.UUID) (*OnboardingServiceChecklistResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "onboarding.getChecklist")
+	defer span.End()
+	span.SetAttributes(attribute.String("tenant.uuid", tenantUUID.String()))
+
+	tenant, err := s.tenantRepo.FindByUUID(tenantUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find tenant failed")
+		return nil, err
+	}
+	if tenant == nil {
+		span.SetStatus(codes.Error, "tenant not found")
+		return nil, apperror.NewNotFound("tenant")
+	}
+
+	checklist, err := s.buildChecklist(tenant)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "build checklist failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return checklist, nil
+}
+
+// Onboard provisions the default identity provider (when missing), a client,
+// the default and admin roles with their permissions, the admin user, and
+// optionally the email configuration and a starter signup flow, all in a
+// single transaction.
+func (s *onboardingService) Onboard(ctx context.Context, tenantUUID uuid.UUID, opts OnboardingServiceOptions) (*OnboardingServiceResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "onboarding.onboard")
+	defer span.End()
+	span.SetAttributes(attribute.String("tenant.uuid", tenantUUID.String()))
+
+	tenant, err := s.tenantRepo.FindByUUID(tenantUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find tenant failed")
+		return nil, err
+	}
+	if tenant == nil {
+		span.SetStatus(codes.Error, "tenant not found")
+		return nil, apperror.NewNotFound("tenant")
+	}
+	if tenant.IsSystem {
+		span.SetStatus(codes.Error, "system tenant cannot be onboarded")
+		return nil, apperror.NewValidation("system tenant is provisioned by setup and cannot be onboarded")
+	}
+
+	applyOnboardingDefaults(&opts)
+
+	result := &OnboardingServiceResult{TenantUUID: tenant.TenantUUID}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		txIdpRepo := s.idpRepo.WithTx(tx)
+		txClientRepo := s.clientRepo.WithTx(tx)
+		txRoleRepo := s.roleRepo.WithTx(tx)
+		txUserRepo := s.userRepo.WithTx(tx)
+		txUserIdentityRepo := s.userIdentityRepo.WithTx(tx)
+		txUserRoleRepo := s.userRoleRepo.WithTx(tx)
+		txTenantMemberRepo := s.tenantMemberRepo.WithTx(tx)
+		txEmailConfigRepo := s.emailConfigRepo.WithTx(tx)
+		txSignupFlowRepo := s.signupFlowRepo.WithTx(tx)
+		txSignupFlowRoleRepo := s.signupFlowRoleRepo.WithTx(tx)
+
+		// An existing owner means the tenant was already onboarded.
+		members, err := txTenantMemberRepo.FindAllByTenant(tenant.TenantID)
+		if err != nil {
+			return err
+		}
+		for _, m := range members {
+			if m.Role == "owner" {
+				return apperror.NewConflict("tenant has already been onboarded")
+			}
+		}
+
+		// Identity provider — reuse the tenant default when one exists.
+		idp, err := txIdpRepo.FindDefaultByTenantID(tenant.TenantID)
+		if err != nil {
+			return err
+		}
+		if idp == nil {
+			identifier, err := crypto.GenerateIdentifier(15)
+			if err != nil {
+				return err
+			}
+			idp, err = txIdpRepo.CreateOrUpdate(&model.IdentityProvider{
+				TenantID:     tenant.TenantID,
+				Name:         "default",
+				DisplayName:  "Built-in Authentication System",
+				Provider:     model.IDPProviderInternal,
+				ProviderType: model.IDPTypeIdentity,
+				Identifier:   identifier,
+				Config:       datatypes.JSON([]byte("{}")),
+				Status:       model.StatusActive,
+				IsDefault:    true,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		result.IdentityProviderUUID = idp.IdentityProviderUUID
+
+		// Client
+		existingClient, err := txClientRepo.FindByNameAndTenantID(opts.Client.Name, tenant.TenantID)
+		if err != nil {
+			return err
+		}
+		if existingClient != nil {
+			return apperror.NewConflict(opts.Client.Name + " auth client already exists")
+		}
+		client, err := newOnboardingClient(tenant.TenantID, idp.IdentityProviderID, opts.Client)
+		if err != nil {
+			return err
+		}
+		client, err = txClientRepo.CreateOrUpdate(client)
+		if err != nil {
+			return err
+		}
+		result.ClientUUID = client.ClientUUID
+		result.ClientIdentifier = *client.Identifier
+		result.ClientSecret = client.Secret
+
+		// Default role assigned to every new signup
+		defaultRole, err := txRoleRepo.FindByNameAndTenantID(model.RoleRegistered, tenant.TenantID)
+		if err != nil {
+			return err
+		}
+		if defaultRole == nil {
+			defaultRole, err = txRoleRepo.CreateOrUpdate(&model.Role{
+				TenantID:    tenant.TenantID,
+				Name:        model.RoleRegistered,
+				Description: "Basic registered user with account access permissions",
+				Status:      model.StatusActive,
+				IsDefault:   true,
+			})
+			if err != nil {
+				return err
+			}
+			if err := seeder.AssignRegisteredRolePermissions(tx, *defaultRole); err != nil {
+				return err
+			}
+		}
+		result.DefaultRoleUUID = defaultRole.RoleUUID
+
+		// Admin role
+		existingRole, err := txRoleRepo.FindByNameAndTenantID(opts.Admin.RoleName, tenant.TenantID)
+		if err != nil {
+			return err
+		}
+		if existingRole != nil {
+			return apperror.NewConflict(opts.Admin.RoleName + " role already exists")
+		}
+		adminRole, err := txRoleRepo.CreateOrUpdate(&model.Role{
+			TenantID:    tenant.TenantID,
+			Name:        opts.Admin.RoleName,
+			Description: "Tenant administrator",
+			Status:      model.StatusActive,
+		})
+		if err != nil {
+			return err
+		}
+		// The admin role holds every permission, like the super-admin role
+		// seeded for the tenant.
+		if err := seeder.AssignAdminRolePermissions(tx, *adminRole); err != nil {
+			return err
+		}
+		result.AdminRoleUUID = adminRole.RoleUUID
+
+		// Admin user
+		existingUser, err := txUserRepo.FindByUsername(opts.Admin.Username)
+		if err != nil {
+			return err
+		}
+		if existingUser != nil {
+			return apperror.WithCode(apperror.CodeUsernameTaken, apperror.NewConflict("username already taken"))
+		}
+		existingUser, err = txUserRepo.FindByEmail(opts.Admin.Email)
+		if err != nil {
+			return err
+		}
+		if existingUser != nil {
+			return apperror.NewConflict("user with this email already exists")
+		}
+
+		hashedPassword, err := hashTenantPassword(s.securitySettingRepo.WithTx(tx), tenant.TenantID, opts.Admin.Password)
+		if err != nil {
+			return err
+		}
+
+		adminUser, err := txUserRepo.Create(&model.User{
+			Username:           opts.Admin.Username,
+			Fullname:           opts.Admin.Fullname,
+			Email:              opts.Admin.Email,
+			Password:           ptr.Ptr(string(hashedPassword)),
+			IsEmailVerified:    true,
+			IsAccountCompleted: true,
+			Status:             model.StatusActive,
+		})
+		if err != nil {
+			return err
+		}
+		result.AdminUserUUID = adminUser.UserUUID
+
+		if _, err := txUserIdentityRepo.Create(&model.UserIdentity{
+			TenantID: tenant.TenantID,
+			UserID:   adminUser.UserID,
+			ClientID: client.ClientID,
+			Provider: model.ProviderDefault,
+			Sub:      uuid.New().String(),
+		}); err != nil {
+			return err
+		}
+
+		for _, roleID := range []int64{defaultRole.RoleID, adminRole.RoleID} {
+			if _, err := txUserRoleRepo.Create(&model.UserRole{
+				UserID: adminUser.UserID,
+				RoleID: roleID,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if _, err := txTenantMemberRepo.Create(&model.TenantMember{
+			TenantID: tenant.TenantID,
+			UserID:   adminUser.UserID,
+			Role:     "owner",
+		}); err != nil {
+			return err
+		}
+
+		// Email configuration (optional)
+		if opts.Email != nil {
+			emailConfig, err := txEmailConfigRepo.FindByTenantID(tenant.TenantID)
+			if err != nil {
+				return err
+			}
+			if emailConfig == nil {
+				emailConfig = &model.EmailConfig{TenantID: tenant.TenantID}
+			}
+			emailConfig.Provider = opts.Email.Provider
+			emailConfig.Host = opts.Email.Host
+			emailConfig.Port = opts.Email.Port
+			emailConfig.Username = opts.Email.Username
+			emailConfig.PasswordEncrypted = opts.Email.Password
+			emailConfig.FromAddress = opts.Email.FromAddress
+			emailConfig.FromName = opts.Email.FromName
+			emailConfig.ReplyTo = opts.Email.ReplyTo
+			emailConfig.Encryption = opts.Email.Encryption
+			emailConfig.TestMode = opts.Email.TestMode
+			emailConfig.Status = model.StatusActive
+
+			emailConfig, err = txEmailConfigRepo.CreateOrUpdate(emailConfig)
+			if err != nil {
+				return err
+			}
+			result.EmailConfigUUID = &emailConfig.EmailConfigUUID
+		}
+
+		// Starter signup flow (optional)
+		if opts.SignupFlow != nil {
+			existingFlow, err := txSignupFlowRepo.FindByNameAndTenantID(opts.SignupFlow.Name, tenant.TenantID)
+			if err != nil {
+				return err
+			}
+			if existingFlow != nil {
+				return apperror.NewConflict("signup flow with this name already exists")
+			}
+
+			identifier, err := crypto.GenerateIdentifier(16)
+			if err != nil {
+				return err
+			}
+
+			configJSON := datatypes.JSON([]byte("{}"))
+			if opts.SignupFlow.Config != nil {
+				configBytes, err := json.Marshal(opts.SignupFlow.Config)
+				if err != nil {
+					return apperror.NewValidation("invalid signup flow config")
+				}
+				configJSON = datatypes.JSON(configBytes)
+			}
+
+			signupFlow, err := txSignupFlowRepo.Create(&model.SignupFlow{
+				TenantID:    tenant.TenantID,
+				Name:        opts.SignupFlow.Name,
+				Description: opts.SignupFlow.Description,
+				Identifier:  identifier,
+				Config:      configJSON,
+				Status:      model.StatusActive,
+				ClientID:    client.ClientID,
+			})
+			if err != nil {
+				return err
+			}
+
+			if _, err := txSignupFlowRoleRepo.Create(&model.SignupFlowRole{
+				SignupFlowID: signupFlow.SignupFlowID,
+				RoleID:       defaultRole.RoleID,
+			}); err != nil {
+				return err
+			}
+			result.SignupFlowUUID = &signupFlow.SignupFlowUUID
+		}
+
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "onboard tenant failed")
+		return nil, err
+	}
+
+	checklist, err := s.buildChecklist(tenant)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "build checklist failed")
+		return nil, err
+	}
+	result.Checklist = *checklist
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// buildChecklist evaluates every onboarding step against the current state of
+// the tenant.
+func (s *onboardingService) buildChecklist(tenant *model.Tenant) (*OnboardingServiceChecklistResult, error) {
+	idp, err := s.idpRepo.FindDefaultByTenantID(tenant.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	clients, err := s.clientRepo.FindAllByTenantID(tenant.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := s.roleRepo.FindAllByTenantID(tenant.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.tenantMemberRepo.FindAllByTenant(tenant.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	hasOwner := false
+	for _, m := range members {
+		if m.Role == "owner" {
+			hasOwner = true
+			break
+		}
+	}
+
+	emailConfig, err := s.emailConfigRepo.FindByTenantID(tenant.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	flows, err := s.signupFlowRepo.FindPaginated(repository.SignupFlowRepositoryGetFilter{
+		TenantID: &tenant.TenantID,
+		Page:     1,
+		Limit:    1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	branding, err := s.brandingRepo.FindByTenantID(tenant.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := []OnboardingServiceChecklistItem{
+		{Key: OnboardingStepIdentityProvider, Title: "Configure an identity provider", Completed: idp != nil},
+		{Key: OnboardingStepClient, Title: "Register an auth client", Completed: len(clients) > 0},
+		{Key: OnboardingStepRoles, Title: "Create roles", Completed: len(roles) > 0},
+		{Key: OnboardingStepAdminUser, Title: "Create the tenant administrator", Completed: hasOwner},
+		{Key: OnboardingStepEmailConfig, Title: "Configure email delivery", Completed: emailConfig != nil},
+		{Key: OnboardingStepSignupFlow, Title: "Create a signup flow", Completed: flows != nil && flows.Total > 0},
+		{Key: OnboardingStepBranding, Title: "Customize branding", Completed: branding != nil},
+	}
+
+	remaining := 0
+	for _, step := range steps {
+		if !step.Completed {
+			remaining++
+		}
+	}
+
+	return &OnboardingServiceChecklistResult{
+		TenantUUID: tenant.TenantUUID,
+		Steps:      steps,
+		Remaining:  remaining,
+		IsComplete: remaining == 0,
+	}, nil
+}
+
+// applyOnboardingDefaults fills in the optional names that the caller left
+// empty.
+func applyOnboardingDefaults(opts *OnboardingServiceOptions) {
+	if opts.Client.Name == "" {
+		opts.Client.Name = onboardingDefaultClientName
+	}
+	if opts.Client.DisplayName == "" {
+		opts.Client.DisplayName = opts.Client.Name
+	}
+	if opts.Client.ClientType == "" {
+		opts.Client.ClientType = model.ClientTypeSPA
+	}
+	if opts.Admin.RoleName == "" {
+		opts.Admin.RoleName = onboardingDefaultAdminRoleName
+	}
+	if opts.Admin.Fullname == "" {
+		opts.Admin.Fullname = opts.Admin.Username
+	}
+	if opts.SignupFlow != nil && opts.SignupFlow.Name == "" {
+		opts.SignupFlow.Name = onboardingDefaultSignupFlowName
+	}
+}
+
+// newOnboardingClient builds the client model for the requested client type.
+// Public clients (SPA, mobile) use PKCE without a secret; confidential clients
+// receive a generated secret.
+func newOnboardingClient(tenantID, identityProviderID int64, opts OnboardingServiceClientOptions) (*model.Client, error) {
+	identifier, err := crypto.GenerateIdentifier(12)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &model.Client{
+		TenantID:           tenantID,
+		IdentityProviderID: identityProviderID,
+		Name:               opts.Name,
+		DisplayName:        opts.DisplayName,
+		ClientType:         opts.ClientType,
+		Domain:             ptr.PtrOrNil(opts.Domain),
+		Identifier:         &identifier,
+		Config:             datatypes.JSON([]byte("{}")),
+		Status:             model.StatusActive,
+		IsDefault:          true,
+		ResponseTypes:      pq.StringArray{model.ResponseTypeCode},
+		RequireConsent:     false,
+	}
+
+	switch opts.ClientType {
+	case model.ClientTypeSPA, model.ClientTypeMobile:
+		client.TokenEndpointAuthMethod = model.TokenAuthMethodNone
+		client.GrantTypes = pq.StringArray{model.GrantTypeAuthorizationCode, model.GrantTypeRefreshToken}
+	case model.ClientTypeM2M:
+		client.TokenEndpointAuthMethod = model.TokenAuthMethodSecretBasic
+		client.GrantTypes = pq.StringArray{model.GrantTypeClientCredentials}
+		client.ResponseTypes = nil
+	default:
+		client.TokenEndpointAuthMethod = model.TokenAuthMethodSecretBasic
+		client.GrantTypes = pq.StringArray{model.GrantTypeAuthorizationCode, model.GrantTypeRefreshToken}
+	}
+
+	if client.TokenEndpointAuthMethod != model.TokenAuthMethodNone {
+		secret, err := crypto.GenerateIdentifier(64)
+		if err != nil {
+			return nil, err
+		}
+		client.Secret = &secret
+	}
+
+	return client, nil
+}
@@ -0,0 +1,452 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// ---------------------------------------------------------------------------
+// helpers
+// ---------------------------------------------------------------------------
+
+type onboardingMocks struct {
+	tenantRepo          *mockTenantRepo
+	idpRepo             *mockIdentityProviderRepo
+	clientRepo          *mockClientRepo
+	roleRepo            *mockRoleRepo
+	userRepo            *mockUserRepo
+	userIdentityRepo    *mockUserIdentityRepo
+	userRoleRepo        *mockUserRoleRepo
+	tenantMemberRepo    *mockTenantMemberRepo
+	emailConfigRepo     *mockEmailConfigRepo
+	signupFlowRepo      *mockSignupFlowRepo
+	signupFlowRoleRepo  *mockSignupFlowRoleRepo
+	brandingRepo        *mockBrandingRepo
+	securitySettingRepo *mockSecuritySettingRepo
+}
+
+func newOnboardingMocks() *onboardingMocks {
+	tenant := &model.Tenant{TenantID: 7, TenantUUID: uuid.New(), Name: "acme"}
+	return &onboardingMocks{
+		tenantRepo: &mockTenantRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Tenant, error) { return tenant, nil },
+		},
+		idpRepo: &mockIdentityProviderRepo{},
+		clientRepo: &mockClientRepo{
+			createOrUpdateFn: func(c *model.Client) (*model.Client, error) {
+				c.ClientID = 11
+				c.ClientUUID = uuid.New()
+				return c, nil
+			},
+		},
+		roleRepo: &mockRoleRepo{
+			createOrUpdateFn: func(r *model.Role) (*model.Role, error) {
+				r.RoleID = 21
+				r.RoleUUID = uuid.New()
+				return r, nil
+			},
+		},
+		userRepo: &mockUserRepo{
+			createFn: func(u *model.User) (*model.User, error) {
+				u.UserID = 31
+				u.UserUUID = uuid.New()
+				return u, nil
+			},
+		},
+		userIdentityRepo:    &mockUserIdentityRepo{},
+		userRoleRepo:        &mockUserRoleRepo{},
+		tenantMemberRepo:    &mockTenantMemberRepo{},
+		emailConfigRepo:     &mockEmailConfigRepo{},
+		signupFlowRepo:      &mockSignupFlowRepo{},
+		signupFlowRoleRepo:  &mockSignupFlowRoleRepo{},
+		brandingRepo:        &mockBrandingRepo{},
+		securitySettingRepo: &mockSecuritySettingRepo{},
+	}
+}
+
+func (m *onboardingMocks) service(db *gorm.DB) OnboardingService {
+	return NewOnboardingService(db, m.tenantRepo, m.idpRepo, m.clientRepo, m.roleRepo, m.userRepo,
+		m.userIdentityRepo, m.userRoleRepo, m.tenantMemberRepo, m.emailConfigRepo,
+		m.signupFlowRepo, m.signupFlowRoleRepo, m.brandingRepo, m.securitySettingRepo)
+}
+
+func validOnboardingOpts() OnboardingServiceOptions {
+	return OnboardingServiceOptions{
+		Admin: OnboardingServiceAdminOptions{
+			Username: "owner",
+			Email:    "owner@example.com",
+			Password: "Sup3rSecret!",
+		},
+	}
+}
+
+// onboardingPermissions lists one account permission and one management
+// permission.
+func onboardingPermissions() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"permission_id", "name"}).
+		AddRow(1, "account:user:read:self").
+		AddRow(2, "role:read")
+}
+
+// expectPermissionGrants expects the permissions of a new role to be granted:
+// permissions are listed, then each of grants is looked up on roleID and
+// inserted.
+func expectPermissionGrants(mock sqlmock.Sqlmock, permissions *sqlmock.Rows, roleID int64, grants ...int64) {
+	mock.ExpectQuery(`SELECT \* FROM "permissions"`).WillReturnRows(permissions)
+	for _, permissionID := range grants {
+		mock.ExpectQuery(`SELECT \* FROM "role_permissions"`).WithArgs(roleID, permissionID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"role_permission_id"}))
+		mock.ExpectQuery(`INSERT INTO "role_permissions"`).
+			WillReturnRows(sqlmock.NewRows([]string{"role_permission_id"}).AddRow(permissionID))
+	}
+}
+
+// expectNoPermissionGrants expects the registered and admin roles to be
+// created when there are no permissions to grant.
+func expectNoPermissionGrants(mock sqlmock.Sqlmock) {
+	expectPermissionGrants(mock, sqlmock.NewRows([]string{"permission_id"}), 0)
+	expectPermissionGrants(mock, sqlmock.NewRows([]string{"permission_id"}), 0)
+}
+
+// ---------------------------------------------------------------------------
+// GetChecklist
+// ---------------------------------------------------------------------------
+
+func TestOnboardingService_GetChecklist(t *testing.T) {
+	t.Run("tenant not found", func(t *testing.T) {
+		db, _ := newMockGormDB(t)
+		m := newOnboardingMocks()
+		m.tenantRepo.findByUUIDFn = func(_ any, _ ...string) (*model.Tenant, error) { return nil, nil }
+		_, err := m.service(db).GetChecklist(context.Background(), uuid.New())
+		require.Error(t, err)
+		var target *apperror.NotFoundError
+		assert.ErrorAs(t, err, &target)
+	})
+
+	t.Run("tenant repo error", func(t *testing.T) {
+		db, _ := newMockGormDB(t)
+		m := newOnboardingMocks()
+		m.tenantRepo.findByUUIDFn = func(_ any, _ ...string) (*model.Tenant, error) { return nil, errors.New("db error") }
+		_, err := m.service(db).GetChecklist(context.Background(), uuid.New())
+		require.Error(t, err)
+	})
+
+	t.Run("empty tenant has every step pending", func(t *testing.T) {
+		db, _ := newMockGormDB(t)
+		m := newOnboardingMocks()
+		res, err := m.service(db).GetChecklist(context.Background(), uuid.New())
+		require.NoError(t, err)
+		assert.Len(t, res.Steps, 7)
+		assert.Equal(t, 7, res.Remaining)
+		assert.False(t, res.IsComplete)
+	})
+
+	t.Run("fully configured tenant is complete", func(t *testing.T) {
+		db, _ := newMockGormDB(t)
+		m := newOnboardingMocks()
+		m.idpRepo.findDefaultByTenantIDFn = func(int64) (*model.IdentityProvider, error) { return &model.IdentityProvider{}, nil }
+		m.clientRepo.findAllByTenantIDFn = func(int64) ([]model.Client, error) { return []model.Client{{}}, nil }
+		m.roleRepo.findAllByTenantIDFn = func(int64) ([]model.Role, error) { return []model.Role{{}}, nil }
+		m.tenantMemberRepo.findAllByTenantFn = func(int64) ([]model.TenantMember, error) {
+			return []model.TenantMember{{Role: "owner"}}, nil
+		}
+		m.emailConfigRepo.findByTenantIDFn = func(int64) (*model.EmailConfig, error) { return &model.EmailConfig{}, nil }
+		m.signupFlowRepo.findPaginatedFn = func(repository.SignupFlowRepositoryGetFilter) (*repository.PaginationResult[model.SignupFlow], error) {
+			return &repository.PaginationResult[model.SignupFlow]{Total: 1}, nil
+		}
+		m.brandingRepo.findByTenantIDFn = func(int64) (*model.Branding, error) { return &model.Branding{}, nil }
+
+		res, err := m.service(db).GetChecklist(context.Background(), uuid.New())
+		require.NoError(t, err)
+		assert.Equal(t, 0, res.Remaining)
+		assert.True(t, res.IsComplete)
+	})
+
+	t.Run("lookup error", func(t *testing.T) {
+		db, _ := newMockGormDB(t)
+		m := newOnboardingMocks()
+		m.emailConfigRepo.findByTenantIDFn = func(int64) (*model.EmailConfig, error) { return nil, errors.New("db error") }
+		_, err := m.service(db).GetChecklist(context.Background(), uuid.New())
+		require.Error(t, err)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Onboard
+// ---------------------------------------------------------------------------
+
+func TestOnboardingService_Onboard(t *testing.T) {
+	t.Run("tenant not found", func(t *testing.T) {
+		db, _ := newMockGormDB(t)
+		m := newOnboardingMocks()
+		m.tenantRepo.findByUUIDFn = func(_ any, _ ...string) (*model.Tenant, error) { return nil, nil }
+		_, err := m.service(db).Onboard(context.Background(), uuid.New(), validOnboardingOpts())
+		require.Error(t, err)
+		var target *apperror.NotFoundError
+		assert.ErrorAs(t, err, &target)
+	})
+
+	t.Run("system tenant rejected", func(t *testing.T) {
+		db, _ := newMockGormDB(t)
+		m := newOnboardingMocks()
+		m.tenantRepo.findByUUIDFn = func(_ any, _ ...string) (*model.Tenant, error) {
+			return &model.Tenant{TenantID: 1, IsSystem: true}, nil
+		}
+		_, err := m.service(db).Onboard(context.Background(), uuid.New(), validOnboardingOpts())
+		require.Error(t, err)
+		var target *apperror.ValidationError
+		assert.ErrorAs(t, err, &target)
+	})
+
+	t.Run("already onboarded", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		m := newOnboardingMocks()
+		m.tenantMemberRepo.findAllByTenantFn = func(int64) ([]model.TenantMember, error) {
+			return []model.TenantMember{{Role: "owner"}}, nil
+		}
+		_, err := m.service(db).Onboard(context.Background(), uuid.New(), validOnboardingOpts())
+		require.Error(t, err)
+		var target *apperror.ConflictError
+		assert.ErrorAs(t, err, &target)
+	})
+
+	t.Run("client name taken", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		m := newOnboardingMocks()
+		m.clientRepo.findByNameAndTenantIDFn = func(string, int64) (*model.Client, error) { return &model.Client{}, nil }
+		_, err := m.service(db).Onboard(context.Background(), uuid.New(), validOnboardingOpts())
+		require.Error(t, err)
+		var target *apperror.ConflictError
+		assert.ErrorAs(t, err, &target)
+	})
+
+	t.Run("username taken", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		expectNoPermissionGrants(mock)
+		mock.ExpectRollback()
+		m := newOnboardingMocks()
+		m.userRepo.findByUsernameFn = func(string) (*model.User, error) { return &model.User{}, nil }
+		_, err := m.service(db).Onboard(context.Background(), uuid.New(), validOnboardingOpts())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "username already taken")
+	})
+
+	t.Run("email taken", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		expectNoPermissionGrants(mock)
+		mock.ExpectRollback()
+		m := newOnboardingMocks()
+		m.userRepo.findByEmailFn = func(string) (*model.User, error) { return &model.User{}, nil }
+		_, err := m.service(db).Onboard(context.Background(), uuid.New(), validOnboardingOpts())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "email already exists")
+	})
+
+	t.Run("user create error rolls back", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		expectNoPermissionGrants(mock)
+		mock.ExpectRollback()
+		m := newOnboardingMocks()
+		m.userRepo.createFn = func(*model.User) (*model.User, error) { return nil, errors.New("insert failed") }
+		_, err := m.service(db).Onboard(context.Background(), uuid.New(), validOnboardingOpts())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "insert failed")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("admin role permission error rolls back", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		expectPermissionGrants(mock, sqlmock.NewRows([]string{"permission_id"}), 0)
+		mock.ExpectQuery(`SELECT \* FROM "permissions"`).WillReturnError(errors.New("db error"))
+		mock.ExpectRollback()
+		m := newOnboardingMocks()
+		m.userRepo.createFn = func(*model.User) (*model.User, error) {
+			t.Fatal("admin user must not be created")
+			return nil, nil
+		}
+		_, err := m.service(db).Onboard(context.Background(), uuid.New(), validOnboardingOpts())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to fetch permissions")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success with defaults", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		// The registered role gets the account permissions, the admin role
+		// every permission.
+		expectPermissionGrants(mock, onboardingPermissions(), 21, 1)
+		expectPermissionGrants(mock, onboardingPermissions(), 22, 1, 2)
+		mock.ExpectCommit()
+		m := newOnboardingMocks()
+
+		var createdIdp *model.IdentityProvider
+		m.idpRepo.createOrUpdateFn = func(e *model.IdentityProvider) (*model.IdentityProvider, error) {
+			createdIdp = e
+			e.IdentityProviderID = 3
+			return e, nil
+		}
+		var createdClient *model.Client
+		m.clientRepo.createOrUpdateFn = func(c *model.Client) (*model.Client, error) {
+			createdClient = c
+			c.ClientID = 11
+			c.ClientUUID = uuid.New()
+			return c, nil
+		}
+		var roleNames []string
+		m.roleRepo.createOrUpdateFn = func(r *model.Role) (*model.Role, error) {
+			roleNames = append(roleNames, r.Name)
+			r.RoleID = int64(20 + len(roleNames))
+			r.RoleUUID = uuid.New()
+			return r, nil
+		}
+		var member *model.TenantMember
+		m.tenantMemberRepo.createFn = func(e *model.TenantMember) (*model.TenantMember, error) {
+			member = e
+			return e, nil
+		}
+		userRoles := 0
+		m.userRoleRepo.createFn = func(e *model.UserRole) (*model.UserRole, error) {
+			userRoles++
+			return e, nil
+		}
+		m.securitySettingRepo = passwordConfigRepo(`{"hash_algorithm":"argon2id","argon2id_memory_kib":8192,"argon2id_iterations":1}`)
+		var admin *model.User
+		m.userRepo.createFn = func(u *model.User) (*model.User, error) {
+			admin = u
+			u.UserID = 31
+			u.UserUUID = uuid.New()
+			return u, nil
+		}
+
+		res, err := m.service(db).Onboard(context.Background(), uuid.New(), validOnboardingOpts())
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+
+		// The admin password is hashed with the tenant's hash settings.
+		require.NotNil(t, admin)
+		require.NotNil(t, admin.Password)
+		assert.True(t, strings.HasPrefix(*admin.Password, "$argon2id$v=19$m=8192,t=1,p=1$"))
+
+		require.NotNil(t, createdIdp)
+		assert.Equal(t, model.IDPProviderInternal, createdIdp.Provider)
+		require.NotNil(t, createdClient)
+		assert.Equal(t, "default", createdClient.Name)
+		assert.Equal(t, model.ClientTypeSPA, createdClient.ClientType)
+		assert.Equal(t, model.TokenAuthMethodNone, createdClient.TokenEndpointAuthMethod)
+		assert.Nil(t, createdClient.Secret)
+		assert.Equal(t, int64(3), createdClient.IdentityProviderID)
+		assert.Equal(t, []string{model.RoleRegistered, "admin"}, roleNames)
+		assert.Equal(t, 2, userRoles)
+		require.NotNil(t, member)
+		assert.Equal(t, "owner", member.Role)
+		assert.Nil(t, res.EmailConfigUUID)
+		assert.Nil(t, res.SignupFlowUUID)
+		assert.NotEmpty(t, res.ClientIdentifier)
+		assert.Len(t, res.Checklist.Steps, 7)
+	})
+
+	t.Run("success with email and signup flow", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		// Only the new admin role is granted permissions.
+		expectPermissionGrants(mock, onboardingPermissions(), 21, 1, 2)
+		mock.ExpectCommit()
+		m := newOnboardingMocks()
+		m.idpRepo.findDefaultByTenantIDFn = func(int64) (*model.IdentityProvider, error) {
+			return &model.IdentityProvider{IdentityProviderID: 5, IdentityProviderUUID: uuid.New()}, nil
+		}
+		m.idpRepo.createOrUpdateFn = func(*model.IdentityProvider) (*model.IdentityProvider, error) {
+			t.Fatal("existing default identity provider must be reused")
+			return nil, nil
+		}
+		m.roleRepo.findByNameAndTenantIDFn = func(name string, _ int64) (*model.Role, error) {
+			if name == model.RoleRegistered {
+				return &model.Role{RoleID: 9, RoleUUID: uuid.New(), Name: name}, nil
+			}
+			return nil, nil
+		}
+		var emailConfig *model.EmailConfig
+		m.emailConfigRepo.createOrUpdateFn = func(e *model.EmailConfig) (*model.EmailConfig, error) {
+			emailConfig = e
+			e.EmailConfigUUID = uuid.New()
+			return e, nil
+		}
+		// Another tenant's flow of the same name does not conflict.
+		m.signupFlowRepo.findByNameFn = func(string) (*model.SignupFlow, error) {
+			return &model.SignupFlow{TenantID: 8, Name: "default-signup"}, nil
+		}
+		var flow *model.SignupFlow
+		m.signupFlowRepo.createFn = func(e *model.SignupFlow) (*model.SignupFlow, error) {
+			flow = e
+			e.SignupFlowID = 41
+			e.SignupFlowUUID = uuid.New()
+			return e, nil
+		}
+		var flowRole *model.SignupFlowRole
+		m.signupFlowRoleRepo.createFn = func(e *model.SignupFlowRole) (*model.SignupFlowRole, error) {
+			flowRole = e
+			return e, nil
+		}
+
+		opts := validOnboardingOpts()
+		opts.Client = OnboardingServiceClientOptions{Name: "portal", ClientType: model.ClientTypeTraditional}
+		opts.Email = &OnboardingServiceEmailOptions{Provider: "smtp", FromAddress: "noreply@example.com"}
+		opts.SignupFlow = &OnboardingServiceSignupFlowOptions{Config: map[string]any{"require_email": true}}
+
+		res, err := m.service(db).Onboard(context.Background(), uuid.New(), opts)
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+
+		assert.NotNil(t, res.ClientSecret)
+		require.NotNil(t, emailConfig)
+		assert.Equal(t, int64(7), emailConfig.TenantID)
+		assert.Equal(t, model.StatusActive, emailConfig.Status)
+		require.NotNil(t, flow)
+		assert.Equal(t, "default-signup", flow.Name)
+		assert.Equal(t, int64(11), flow.ClientID)
+		assert.JSONEq(t, `{"require_email":true}`, string(flow.Config))
+		require.NotNil(t, flowRole)
+		assert.Equal(t, int64(9), flowRole.RoleID)
+		assert.NotNil(t, res.EmailConfigUUID)
+		assert.NotNil(t, res.SignupFlowUUID)
+	})
+
+	t.Run("signup flow name taken", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		expectNoPermissionGrants(mock)
+		mock.ExpectRollback()
+		m := newOnboardingMocks()
+		m.signupFlowRepo.findByNameAndTenantIDFn = func(name string, tenantID int64) (*model.SignupFlow, error) {
+			assert.Equal(t, "starter", name)
+			assert.Equal(t, int64(7), tenantID)
+			return &model.SignupFlow{}, nil
+		}
+		opts := validOnboardingOpts()
+		opts.SignupFlow = &OnboardingServiceSignupFlowOptions{Name: "starter"}
+		_, err := m.service(db).Onboard(context.Background(), uuid.New(), opts)
+		require.Error(t, err)
+		var target *apperror.ConflictError
+		assert.ErrorAs(t, err, &target)
+	})
+}
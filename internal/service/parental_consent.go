@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+// parentalConsentTTL is how long a guardian can act on a consent request.
+const parentalConsentTTL = 7 * 24 * time.Hour
+
+// errInvalidConsentToken is returned for unknown, decided and expired consent
+// tokens alike.
+var errInvalidConsentToken = apperror.NewValidation("invalid or expired consent token")
+
+// ParentalConsentServiceDataResult describes a consent request to the
+// guardian acting on it.
+type ParentalConsentServiceDataResult struct {
+	ParentalConsentUUID uuid.UUID
+	Username            string
+	Status              string
+	ExpiresAt           time.Time
+	DecidedAt           *time.Time
+}
+
+// ParentalConsentService lets the guardian of an under-age user approve or
+// decline the account with the emailed token.
+type ParentalConsentService interface {
+	// Get returns the pending request of the token.
+	Get(ctx context.Context, token string) (*ParentalConsentServiceDataResult, error)
+	// Approve activates the account, or lets email verification activate it
+	// when the address is not verified yet.
+	Approve(ctx context.Context, token string) (*ParentalConsentServiceDataResult, error)
+	// Deny deletes the account. It is purged like any other deleted user.
+	Deny(ctx context.Context, token string) (*ParentalConsentServiceDataResult, error)
+}
+
+type parentalConsentService struct {
+	db                  *gorm.DB
+	parentalConsentRepo repository.ParentalConsentRepository
+	userRepo            repository.UserRepository
+	eventRepo           repository.EventRepository
+	authEventService    AuthEventService
+}
+
+// NewParentalConsentService creates a ParentalConsentService.
+func NewParentalConsentService(
+	db *gorm.DB,
+	parentalConsentRepo repository.ParentalConsentRepository,
+	userRepo repository.UserRepository,
+	eventRepo repository.EventRepository,
+	authEventService AuthEventService,
+) ParentalConsentService {
+	return &parentalConsentService{
+		db:                  db,
+		parentalConsentRepo: parentalConsentRepo,
+		userRepo:            userRepo,
+		eventRepo:           eventRepo,
+		authEventService:    authEventService,
+	}
+}
+
+func (s *parentalConsentService) Get(ctx context.Context, token string) (*ParentalConsentServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "parentalConsent.get")
+	defer span.End()
+
+	consent, err := s.parentalConsentRepo.FindPendingByToken(hashAccountToken(token))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get parental consent failed")
+		return nil, err
+	}
+	if consent == nil || consent.User == nil {
+		span.SetStatus(codes.Error, "invalid consent token")
+		return nil, errInvalidConsentToken
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toParentalConsentServiceDataResult(consent), nil
+}
+
+func (s *parentalConsentService) Approve(ctx context.Context, token string) (*ParentalConsentServiceDataResult, error) {
+	return s.decide(ctx, token, model.ParentalConsentApproved)
+}
+
+func (s *parentalConsentService) Deny(ctx context.Context, token string) (*ParentalConsentServiceDataResult, error) {
+	return s.decide(ctx, token, model.ParentalConsentDenied)
+}
+
+// decide records the guardian's decision and applies it to the account.
+func (s *parentalConsentService) decide(ctx context.Context, token, decision string) (*ParentalConsentServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "parentalConsent."+decision)
+	defer span.End()
+
+	var consent *model.ParentalConsent
+	now := time.Now()
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txConsentRepo := s.parentalConsentRepo.WithTx(tx)
+		txUserRepo := s.userRepo.WithTx(tx)
+
+		var err error
+		consent, err = txConsentRepo.FindPendingByToken(hashAccountToken(token))
+		if err != nil {
+			return err
+		}
+		if consent == nil || consent.User == nil {
+			return errInvalidConsentToken
+		}
+
+		if err := txConsentRepo.Decide(consent.ParentalConsentID, decision, now,
+			ptr.PtrOrNil(middleware.ClientIPFromContext(ctx)),
+			ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		); err != nil {
+			return err
+		}
+		consent.Status = decision
+		consent.DecidedAt = &now
+
+		user := consent.User
+		var event DomainEvent
+		switch {
+		case decision == model.ParentalConsentDenied:
+			if err := txUserRepo.SoftDelete(user.UserUUID, now); err != nil {
+				return err
+			}
+			user.Status = model.StatusDeleted
+			user.DeletedAt = &now
+			event = UserDeleted(newUserEventPayload(user))
+		case user.Status == model.StatusPendingConsent:
+			if err := txUserRepo.SetStatus(user.UserUUID, model.StatusActive); err != nil {
+				return err
+			}
+			user.Status = model.StatusActive
+			event = UserUpdated(newUserEventPayload(user))
+		default:
+			// The email address is not verified yet; verifying it activates
+			// the account
+			return nil
+		}
+		return recordEvent(s.eventRepo.WithTx(tx), consent.TenantID, event)
+	})
+	if err != nil {
+		if errors.Is(err, errInvalidConsentToken) {
+			security.LogSecurityEvent(security.SecurityEvent{
+				EventType: "parental_consent_failure",
+				ClientIP:  middleware.ClientIPFromContext(ctx),
+				Timestamp: now,
+				Details:   "invalid or expired consent token",
+				Severity:  "LOW",
+			})
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "parental consent "+decision+" failed")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("user.uuid", consent.User.UserUUID.String()))
+	s.logConsentDecision(ctx, consent)
+
+	span.SetStatus(codes.Ok, "")
+	return toParentalConsentServiceDataResult(consent), nil
+}
+
+// logConsentDecision records the guardian's decision in the audit log.
+func (s *parentalConsentService) logConsentDecision(ctx context.Context, consent *model.ParentalConsent) {
+	eventType, description := model.AuthEventTypeUserEnabled, "account approved by guardian"
+	if consent.Status == model.ParentalConsentDenied {
+		eventType, description = model.AuthEventTypeUserDeleted, "account declined by guardian"
+	}
+	s.authEventService.Log(ctx, AuthEventInput{
+		TenantID:     consent.TenantID,
+		TargetUserID: &consent.UserID,
+		IPAddress:    middleware.ClientIPFromContext(ctx),
+		UserAgent:    ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:     model.AuthEventCategoryUser,
+		EventType:    eventType,
+		Severity:     model.AuthEventSeverityWarn,
+		Result:       model.AuthEventResultSuccess,
+		Description:  ptr.Ptr(description),
+	})
+}
+
+func toParentalConsentServiceDataResult(c *model.ParentalConsent) *ParentalConsentServiceDataResult {
+	result := &ParentalConsentServiceDataResult{
+		ParentalConsentUUID: c.ParentalConsentUUID,
+		Status:              c.Status,
+		ExpiresAt:           c.ExpiresAt,
+		DecidedAt:           c.DecidedAt,
+	}
+	if c.User != nil {
+		result.Username = c.User.Username
+	}
+	return result
+}
+
+// requestParentalConsent records a consent request for the new under-age
+// user and returns the token to email to the guardian.
+func requestParentalConsent(repo repository.ParentalConsentRepository, tenantID, userID int64, guardianEmail string) (string, error) {
+	token := generateSecureToken(32)
+	if _, err := repo.Create(&model.ParentalConsent{
+		TenantID:      tenantID,
+		UserID:        userID,
+		GuardianEmail: guardianEmail,
+		Token:         hashAccountToken(token),
+		Status:        model.StatusPending,
+		ExpiresAt:     time.Now().Add(parentalConsentTTL),
+	}); err != nil {
+		return "", apperror.NewInternal("failed to request parental consent", err)
+	}
+	return token, nil
+}
+
+// sendPendingAccountEmails sends the emails a pending account waits on: the
+// verification code and the guardian's consent request.
+func (s *registerService) sendPendingAccountEmails(ctx context.Context, plan *signupPlan, user *model.User, guardianEmail, verificationCode, consentToken string) {
+	if plan.config.EmailVerification {
+		s.sendVerificationEmail(ctx, user.Email, verificationCode)
+	}
+	if plan.parentalConsent {
+		s.sendParentalConsentEmail(ctx, guardianEmail, user.Username, consentToken)
+	}
+}
+
+// sendParentalConsentEmail emails the consent link to the guardian. The
+// account is already committed, so failures are logged rather than returned.
+func (s *registerService) sendParentalConsentEmail(ctx context.Context, to, username, token string) {
+	data := struct {
+		Username   string
+		ConsentURL string
+		LogoURL    string
+	}{
+		Username:   username,
+		ConsentURL: config.AccountHostname + "/parental-consent?token=" + url.QueryEscape(token),
+		LogoURL:    config.EmailLogo,
+	}
+	if err := sendTemplateEmail(ctx, s.emailTemplateRepo, to, "internal:user:parental:consent", data); err != nil {
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "parental_consent_send_failure",
+			Timestamp: time.Now(),
+			Details:   err.Error(),
+			Severity:  "MEDIUM",
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const consentToken = "consent-token"
+
+// newPendingConsent returns a pending consent for a user with userStatus.
+func newPendingConsent(userStatus string) *model.ParentalConsent {
+	return &model.ParentalConsent{
+		ParentalConsentID:   4,
+		ParentalConsentUUID: uuid.New(),
+		TenantID:            2,
+		UserID:              9,
+		Status:              model.StatusPending,
+		ExpiresAt:           time.Now().Add(time.Hour),
+		User:                &model.User{UserID: 9, UserUUID: uuid.New(), Username: "kid", Status: userStatus},
+	}
+}
+
+// consentRepoFor returns a consent repo that resolves consentToken to consent.
+func consentRepoFor(t *testing.T, consent *model.ParentalConsent) *mockParentalConsentRepo {
+	return &mockParentalConsentRepo{findPendingByTokenFn: func(tok string) (*model.ParentalConsent, error) {
+		assert.Equal(t, hashAccountToken(consentToken), tok)
+		return consent, nil
+	}}
+}
+
+func newParentalConsentService(db *gorm.DB, consentRepo *mockParentalConsentRepo, userRepo *mockUserRepo, eventRepo *mockEventRepo, authEvents AuthEventService) ParentalConsentService {
+	return NewParentalConsentService(db, consentRepo, userRepo, eventRepo, authEvents)
+}
+
+func TestParentalConsentService_Get(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		consent := newPendingConsent(model.StatusPendingConsent)
+		svc := newParentalConsentService(gormDB, consentRepoFor(t, consent), &mockUserRepo{}, &mockEventRepo{}, &mockAuthEventService{})
+
+		res, err := svc.Get(context.Background(), consentToken)
+		require.NoError(t, err)
+		assert.Equal(t, consent.ParentalConsentUUID, res.ParentalConsentUUID)
+		assert.Equal(t, "kid", res.Username)
+		assert.Equal(t, model.StatusPending, res.Status)
+	})
+
+	t.Run("invalid token → error", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		svc := newParentalConsentService(gormDB, consentRepoFor(t, nil), &mockUserRepo{}, &mockEventRepo{}, &mockAuthEventService{})
+
+		_, err := svc.Get(context.Background(), consentToken)
+		require.ErrorIs(t, err, errInvalidConsentToken)
+	})
+}
+
+func TestParentalConsentService_Approve(t *testing.T) {
+	t.Run("activates the account", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		var decided, status string
+		var logged []AuthEventInput
+		consentRepo := consentRepoFor(t, newPendingConsent(model.StatusPendingConsent))
+		consentRepo.decideFn = func(id int64, s string, _ time.Time, _, _ *string) error {
+			assert.Equal(t, int64(4), id)
+			decided = s
+			return nil
+		}
+		eventRepo := &mockEventRepo{}
+		svc := newParentalConsentService(gormDB, consentRepo,
+			&mockUserRepo{setStatusFn: func(_ uuid.UUID, s string) error { status = s; return nil }},
+			eventRepo,
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) }})
+
+		res, err := svc.Approve(context.Background(), consentToken)
+		require.NoError(t, err)
+		assert.Equal(t, model.ParentalConsentApproved, decided)
+		assert.Equal(t, model.ParentalConsentApproved, res.Status)
+		require.NotNil(t, res.DecidedAt)
+		assert.Equal(t, model.StatusActive, status)
+		require.Len(t, eventRepo.created, 1)
+		assert.Equal(t, model.EventTypeUserUpdated, eventRepo.created[0].EventType)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeUserEnabled, logged[0].EventType)
+	})
+
+	t.Run("before email verification → account stays pending", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		eventRepo := &mockEventRepo{}
+		svc := newParentalConsentService(gormDB, consentRepoFor(t, newPendingConsent(model.StatusPending)),
+			&mockUserRepo{setStatusFn: func(uuid.UUID, string) error {
+				t.Fatal("status should not change")
+				return nil
+			}},
+			eventRepo, &mockAuthEventService{})
+
+		res, err := svc.Approve(context.Background(), consentToken)
+		require.NoError(t, err)
+		assert.Equal(t, model.ParentalConsentApproved, res.Status)
+		assert.Empty(t, eventRepo.created)
+	})
+
+	t.Run("invalid token → validation error", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		var logged []AuthEventInput
+		svc := newParentalConsentService(gormDB, consentRepoFor(t, nil), &mockUserRepo{}, &mockEventRepo{},
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) }})
+
+		_, err := svc.Approve(context.Background(), consentToken)
+		require.ErrorIs(t, err, errInvalidConsentToken)
+		var target *apperror.ValidationError
+		require.ErrorAs(t, err, &target)
+		assert.Empty(t, logged)
+	})
+}
+
+func TestParentalConsentService_Deny(t *testing.T) {
+	t.Run("deletes the account", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		var deleted bool
+		var logged []AuthEventInput
+		eventRepo := &mockEventRepo{}
+		svc := newParentalConsentService(gormDB, consentRepoFor(t, newPendingConsent(model.StatusPendingConsent)),
+			&mockUserRepo{softDeleteFn: func(uuid.UUID, time.Time) error { deleted = true; return nil }},
+			eventRepo,
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) }})
+
+		res, err := svc.Deny(context.Background(), consentToken)
+		require.NoError(t, err)
+		assert.Equal(t, model.ParentalConsentDenied, res.Status)
+		assert.True(t, deleted)
+		require.Len(t, eventRepo.created, 1)
+		assert.Equal(t, model.EventTypeUserDeleted, eventRepo.created[0].EventType)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeUserDeleted, logged[0].EventType)
+	})
+
+	t.Run("decide error → propagated", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		var logged []AuthEventInput
+		consentRepo := consentRepoFor(t, newPendingConsent(model.StatusPendingConsent))
+		consentRepo.decideFn = func(int64, string, time.Time, *string, *string) error { return errors.New("db error") }
+		svc := newParentalConsentService(gormDB, consentRepo, &mockUserRepo{}, &mockEventRepo{},
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) }})
+
+		_, err := svc.Deny(context.Background(), consentToken)
+		require.Error(t, err)
+		assert.Empty(t, logged)
+	})
+}
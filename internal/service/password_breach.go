@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ensurePasswordNotBreached rejects password when the tenant has enabled the
+// breached password check and the checker reports it as breached. A nil
+// checker disables the check. Lookup failures fail open: an outage of the
+// breach service must not block sign-ups or password resets, so the error is
+// recorded and the password is accepted.
+func ensurePasswordNotBreached(
+	ctx context.Context,
+	tenantSettingRepo repository.TenantSettingRepository,
+	checker security.BreachedPasswordChecker,
+	tenantID int64,
+	password string,
+) error {
+	if checker == nil {
+		return nil
+	}
+
+	setting, err := tenantSettingRepo.FindByTenantID(tenantID)
+	if err != nil {
+		return apperror.NewInternal("failed to load tenant settings", err)
+	}
+	if setting == nil || unmarshalJSON(setting.FeatureFlags)[model.FeatureFlagBreachedPasswordCheck] != true {
+		return nil
+	}
+
+	breached, err := checker.IsBreached(ctx, password)
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "breached_password_check_unavailable",
+			Timestamp: time.Now(),
+			Details:   err.Error(),
+			Severity:  "MEDIUM",
+		})
+		return nil
+	}
+	if breached {
+		return apperror.NewValidation("password has appeared in a known data breach, please choose a different password")
+	}
+	return nil
+}
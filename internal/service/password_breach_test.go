@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+// mockBreachChecker is a test double for security.BreachedPasswordChecker.
+type mockBreachChecker struct {
+	breached bool
+	err      error
+	calls    int
+}
+
+func (m *mockBreachChecker) IsBreached(_ context.Context, _ string) (bool, error) {
+	m.calls++
+	return m.breached, m.err
+}
+
+// breachFlagSettingRepo returns a tenant setting repo whose feature flags are
+// set to flags.
+func breachFlagSettingRepo(flags string) *mockTenantSettingRepo {
+	return &mockTenantSettingRepo{
+		findByTenantIDFn: func(int64) (*model.TenantSetting, error) {
+			return &model.TenantSetting{FeatureFlags: datatypes.JSON(flags)}, nil
+		},
+	}
+}
+
+func TestEnsurePasswordNotBreached(t *testing.T) {
+	ctx := context.Background()
+	enabled := `{"breached_password_check": true}`
+
+	t.Run("nil checker → skipped", func(t *testing.T) {
+		repo := &mockTenantSettingRepo{findByTenantIDFn: func(int64) (*model.TenantSetting, error) {
+			t.Fatal("settings should not be loaded")
+			return nil, nil
+		}}
+		assert.NoError(t, ensurePasswordNotBreached(ctx, repo, nil, 1, "pw"))
+	})
+
+	t.Run("flag off → not checked", func(t *testing.T) {
+		checker := &mockBreachChecker{breached: true}
+		assert.NoError(t, ensurePasswordNotBreached(ctx, breachFlagSettingRepo(`{}`), checker, 1, "pw"))
+		assert.Zero(t, checker.calls)
+	})
+
+	t.Run("flag not boolean true → not checked", func(t *testing.T) {
+		checker := &mockBreachChecker{breached: true}
+		assert.NoError(t, ensurePasswordNotBreached(ctx, breachFlagSettingRepo(`{"breached_password_check": "true"}`), checker, 1, "pw"))
+		assert.Zero(t, checker.calls)
+	})
+
+	t.Run("no tenant settings → not checked", func(t *testing.T) {
+		checker := &mockBreachChecker{breached: true}
+		assert.NoError(t, ensurePasswordNotBreached(ctx, &mockTenantSettingRepo{}, checker, 1, "pw"))
+		assert.Zero(t, checker.calls)
+	})
+
+	t.Run("settings error → internal", func(t *testing.T) {
+		repo := &mockTenantSettingRepo{findByTenantIDFn: func(int64) (*model.TenantSetting, error) {
+			return nil, errors.New("db down")
+		}}
+		err := ensurePasswordNotBreached(ctx, repo, &mockBreachChecker{}, 1, "pw")
+		var target *apperror.InternalError
+		require.ErrorAs(t, err, &target)
+	})
+
+	t.Run("breached → validation error", func(t *testing.T) {
+		checker := &mockBreachChecker{breached: true}
+		err := ensurePasswordNotBreached(ctx, breachFlagSettingRepo(enabled), checker, 1, "pw")
+		var target *apperror.ValidationError
+		require.ErrorAs(t, err, &target)
+		assert.Contains(t, err.Error(), "data breach")
+		assert.Equal(t, 1, checker.calls)
+	})
+
+	t.Run("not breached → ok", func(t *testing.T) {
+		checker := &mockBreachChecker{}
+		assert.NoError(t, ensurePasswordNotBreached(ctx, breachFlagSettingRepo(enabled), checker, 1, "pw"))
+		assert.Equal(t, 1, checker.calls)
+	})
+
+	t.Run("lookup failure → fails open", func(t *testing.T) {
+		checker := &mockBreachChecker{err: errors.New("timeout")}
+		assert.NoError(t, ensurePasswordNotBreached(ctx, breachFlagSettingRepo(enabled), checker, 1, "pw"))
+	})
+}
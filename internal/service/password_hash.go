@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/logging"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
+)
+
+// tenantPasswordHashParams reads the password hash algorithm and cost from
+// the tenant's password security settings. Missing values keep the defaults
+// of security.DefaultPasswordHashParams, and settings that fail validation
+// fall back to the defaults altogether.
+func tenantPasswordHashParams(securitySettingRepo repository.SecuritySettingRepository, tenantID int64) (security.PasswordHashParams, error) {
+	params := security.DefaultPasswordHashParams()
+	setting, err := securitySettingRepo.FindByUserPoolID(tenantID)
+	if err != nil {
+		return params, apperror.NewInternal("failed to load security settings", err)
+	}
+	if setting == nil {
+		return params, nil
+	}
+	return passwordHashParamsFromConfig(unmarshalJSON(setting.PasswordConfig)), nil
+}
+
+// passwordHashParamsFromConfig overlays the hash settings of a password
+// config on the defaults.
+func passwordHashParamsFromConfig(config map[string]any) security.PasswordHashParams {
+	defaults := security.DefaultPasswordHashParams()
+	params := defaults
+	if algorithm, ok := config[model.PasswordConfigHashAlgorithm].(string); ok {
+		params.Algorithm = algorithm
+	}
+	if cost, ok := config[model.PasswordConfigBcryptCost].(float64); ok {
+		params.BcryptCost = int(cost)
+	}
+	if memory, ok := config[model.PasswordConfigArgon2idMemoryKiB].(float64); ok && memory > 0 {
+		params.Argon2idMemoryKiB = uint32(memory)
+	}
+	if iterations, ok := config[model.PasswordConfigArgon2idIterations].(float64); ok && iterations > 0 {
+		params.Argon2idIterations = uint32(iterations)
+	}
+	if threads, ok := config[model.PasswordConfigArgon2idThreads].(float64); ok && threads > 0 && threads <= math.MaxUint8 {
+		params.Argon2idThreads = uint8(threads)
+	}
+	if params.Validate() != nil {
+		return defaults
+	}
+	return params
+}
+
+// validatePasswordHashConfig rejects a password config whose hash settings
+// could not be used to hash passwords.
+func validatePasswordHashConfig(config map[string]any) error {
+	params := security.DefaultPasswordHashParams()
+	if algorithm, ok := config[model.PasswordConfigHashAlgorithm]; ok {
+		s, isString := algorithm.(string)
+		if !isString {
+			return apperror.NewValidation(model.PasswordConfigHashAlgorithm + " must be a string")
+		}
+		params.Algorithm = s
+	}
+	numbers := []struct {
+		key string
+		set func(n float64)
+	}{
+		{model.PasswordConfigBcryptCost, func(n float64) { params.BcryptCost = int(n) }},
+		{model.PasswordConfigArgon2idMemoryKiB, func(n float64) { params.Argon2idMemoryKiB = uint32(n) }},
+		{model.PasswordConfigArgon2idIterations, func(n float64) { params.Argon2idIterations = uint32(n) }},
+		{model.PasswordConfigArgon2idThreads, func(n float64) { params.Argon2idThreads = uint8(min(n, math.MaxUint8)) }},
+	}
+	for _, number := range numbers {
+		value, ok := config[number.key]
+		if !ok {
+			continue
+		}
+		n, isNumber := value.(float64)
+		if !isNumber || n != math.Trunc(n) || n < 0 || n > math.MaxUint32 {
+			return apperror.NewValidation(number.key + " must be a non-negative whole number")
+		}
+		number.set(n)
+	}
+	if err := params.Validate(); err != nil {
+		return apperror.NewValidation(err.Error())
+	}
+	return nil
+}
+
+// hashTenantPassword hashes a password with the tenant's configured
+// algorithm and cost.
+func hashTenantPassword(securitySettingRepo repository.SecuritySettingRepository, tenantID int64, password string) ([]byte, error) {
+	params, err := tenantPasswordHashParams(securitySettingRepo, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return security.HashPasswordWithParams([]byte(password), params)
+}
+
+// rehashPasswordIfNeeded replaces the stored hash of a user who has just
+// signed in with a hash in the tenant's current algorithm and cost, when the
+// stored one was computed differently. It runs after the password has been
+// verified and never fails the sign in: errors are only logged.
+func rehashPasswordIfNeeded(ctx context.Context, userRepo repository.UserRepository, securitySettingRepo repository.SecuritySettingRepository, user *model.User, tenantID int64, password string) {
+	if user.Password == nil {
+		return
+	}
+	params, err := tenantPasswordHashParams(securitySettingRepo, tenantID)
+	if err != nil {
+		logging.Logger(logging.ComponentAuth).WarnContext(ctx, "password rehash skipped", "user_id", user.UserID, "error", err)
+		return
+	}
+	if !security.PasswordNeedsRehash([]byte(*user.Password), params) {
+		return
+	}
+	hashed, err := security.HashPasswordWithParams([]byte(password), params)
+	if err != nil {
+		logging.Logger(logging.ComponentAuth).WarnContext(ctx, "password rehash failed", "user_id", user.UserID, "error", err)
+		return
+	}
+	if _, err := userRepo.UpdateByID(user.UserID, map[string]any{"password": string(hashed)}); err != nil {
+		logging.Logger(logging.ComponentAuth).WarnContext(ctx, "rehashed password not stored", "user_id", user.UserID, "error", err)
+		return
+	}
+	hashedStr := string(hashed)
+	user.Password = &hashedStr
+}
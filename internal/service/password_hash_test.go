@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/security"
+)
+
+func passwordConfigRepo(config string) *mockSecuritySettingRepo {
+	return &mockSecuritySettingRepo{
+		findByUserPoolIDFn: func(_ int64) (*model.SecuritySetting, error) {
+			return &model.SecuritySetting{PasswordConfig: datatypes.JSON(config)}, nil
+		},
+	}
+}
+
+func TestTenantPasswordHashParams(t *testing.T) {
+	defaults := security.DefaultPasswordHashParams()
+
+	t.Run("no settings", func(t *testing.T) {
+		params, err := tenantPasswordHashParams(&mockSecuritySettingRepo{}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, defaults, params)
+	})
+
+	t.Run("argon2id with custom cost", func(t *testing.T) {
+		params, err := tenantPasswordHashParams(passwordConfigRepo(
+			`{"min_length":12,"hash_algorithm":"argon2id","argon2id_memory_kib":65536,"argon2id_iterations":3,"argon2id_parallelism":4}`), 1)
+		require.NoError(t, err)
+		assert.Equal(t, security.PasswordHashArgon2id, params.Algorithm)
+		assert.Equal(t, uint32(65536), params.Argon2idMemoryKiB)
+		assert.Equal(t, uint32(3), params.Argon2idIterations)
+		assert.Equal(t, uint8(4), params.Argon2idThreads)
+	})
+
+	t.Run("bcrypt cost", func(t *testing.T) {
+		params, err := tenantPasswordHashParams(passwordConfigRepo(`{"bcrypt_cost":12}`), 1)
+		require.NoError(t, err)
+		assert.Equal(t, security.PasswordHashBcrypt, params.Algorithm)
+		assert.Equal(t, 12, params.BcryptCost)
+	})
+
+	t.Run("invalid settings fall back to defaults", func(t *testing.T) {
+		params, err := tenantPasswordHashParams(passwordConfigRepo(`{"hash_algorithm":"argon2id","argon2id_memory_kib":16}`), 1)
+		require.NoError(t, err)
+		assert.Equal(t, defaults, params)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		_, err := tenantPasswordHashParams(&mockSecuritySettingRepo{
+			findByUserPoolIDFn: func(_ int64) (*model.SecuritySetting, error) { return nil, errors.New("db error") },
+		}, 1)
+		var target *apperror.InternalError
+		require.ErrorAs(t, err, &target)
+	})
+}
+
+func TestValidatePasswordHashConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]any
+		wantErr bool
+	}{
+		{"no hash settings", map[string]any{"min_length": float64(12)}, false},
+		{"argon2id", map[string]any{"hash_algorithm": "argon2id", "argon2id_memory_kib": float64(65536)}, false},
+		{"bcrypt cost", map[string]any{"hash_algorithm": "bcrypt", "bcrypt_cost": float64(12)}, false},
+		{"unknown algorithm", map[string]any{"hash_algorithm": "md5"}, true},
+		{"algorithm not a string", map[string]any{"hash_algorithm": true}, true},
+		{"cost not a number", map[string]any{"bcrypt_cost": "12"}, true},
+		{"fractional cost", map[string]any{"bcrypt_cost": 11.5}, true},
+		{"cost out of range", map[string]any{"bcrypt_cost": float64(31)}, true},
+		{"argon2id memory too low", map[string]any{"hash_algorithm": "argon2id", "argon2id_memory_kib": float64(64)}, true},
+		{"argon2id parallelism overflow", map[string]any{"hash_algorithm": "argon2id", "argon2id_parallelism": float64(257)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePasswordHashConfig(tt.config)
+			if tt.wantErr {
+				var target *apperror.ValidationError
+				assert.ErrorAs(t, err, &target)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSecuritySettingService_UpdatePasswordConfig_InvalidHashSettings(t *testing.T) {
+	svc := NewSecuritySettingService(nil, &mockSecuritySettingRepo{}, nil, NopPlanEnforcer{})
+	_, err := svc.UpdatePasswordConfig(context.Background(), 1, map[string]any{"hash_algorithm": "sha1"}, 1, "", "")
+	var target *apperror.ValidationError
+	require.ErrorAs(t, err, &target)
+}
+
+// ---------------------------------------------------------------------------
+// Rehash on login
+// ---------------------------------------------------------------------------
+
+func newRehashLoginService(t *testing.T, user *model.User, userRepo *mockUserRepo, securitySettingRepo *mockSecuritySettingRepo) LoginService {
+	t.Helper()
+	gormDB, mock := newMockGormDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	userRepo.findByUsernameFn = func(_ string) (*model.User, error) { return user, nil }
+	return NewLoginService(gormDB,
+		&mockClientRepo{findSystemFn: func() (*model.Client, error) { return buildActiveClient(), nil }},
+		userRepo,
+		&mockUserTokenRepo{},
+		&mockUserIdentityRepo{findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) {
+			return &model.UserIdentity{Sub: "sub-rehash"}, nil
+		}},
+		&mockIdentityProviderRepo{},
+		&mockTenantSettingRepo{},
+		securitySettingRepo,
+		&mockAuthEventService{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+}
+
+func TestLogin_RehashesPassword(t *testing.T) {
+	initTestJWTKeysService(t)
+	const password = "S3cur3P@ss!"
+	argon2idConfig := `{"hash_algorithm":"argon2id","argon2id_memory_kib":8192,"argon2id_iterations":1}`
+
+	t.Run("bcrypt user moves to argon2id", func(t *testing.T) {
+		var stored string
+		userRepo := &mockUserRepo{updateByIDFn: func(_, data any) (*model.User, error) {
+			stored = data.(map[string]any)["password"].(string)
+			return nil, nil
+		}}
+		svc := newRehashLoginService(t, buildActiveUser(t, password), userRepo, passwordConfigRepo(argon2idConfig))
+
+		_, err := svc.Login(context.Background(), "testuser", password, nil, nil, nil)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(stored, "$argon2id$v=19$m=8192,t=1,p=1$"))
+		assert.NoError(t, security.ComparePassword([]byte(stored), []byte(password)))
+	})
+
+	t.Run("current hash is kept", func(t *testing.T) {
+		params, err := tenantPasswordHashParams(passwordConfigRepo(argon2idConfig), 1)
+		require.NoError(t, err)
+		hash, err := security.HashPasswordWithParams([]byte(password), params)
+		require.NoError(t, err)
+		user := buildActiveUser(t, password)
+		hashStr := string(hash)
+		user.Password = &hashStr
+
+		userRepo := &mockUserRepo{updateByIDFn: func(_, _ any) (*model.User, error) {
+			t.Fatal("password must not be rewritten")
+			return nil, nil
+		}}
+		svc := newRehashLoginService(t, user, userRepo, passwordConfigRepo(argon2idConfig))
+
+		_, err = svc.Login(context.Background(), "testuser", password, nil, nil, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("failed rehash does not fail the login", func(t *testing.T) {
+		userRepo := &mockUserRepo{updateByIDFn: func(_, _ any) (*model.User, error) {
+			return nil, errors.New("db error")
+		}}
+		svc := newRehashLoginService(t, buildActiveUser(t, password), userRepo, passwordConfigRepo(argon2idConfig))
+
+		resp, err := svc.Login(context.Background(), "testuser", password, nil, nil, nil)
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+	})
+
+	t.Run("settings error skips the rehash", func(t *testing.T) {
+		userRepo := &mockUserRepo{updateByIDFn: func(_, _ any) (*model.User, error) {
+			t.Fatal("password must not be rewritten")
+			return nil, nil
+		}}
+		svc := newRehashLoginService(t, buildActiveUser(t, password), userRepo, &mockSecuritySettingRepo{
+			findByUserPoolIDFn: func(_ int64) (*model.SecuritySetting, error) { return nil, errors.New("db error") },
+		})
+
+		_, err := svc.Login(context.Background(), "testuser", password, nil, nil, nil)
+		require.NoError(t, err)
+	})
+}
+
+func TestHashTenantPassword_UsesTenantAlgorithm(t *testing.T) {
+	var params security.PasswordHashParams
+	orig := security.HashPasswordWithParams
+	defer func() { security.HashPasswordWithParams = orig }()
+	security.HashPasswordWithParams = func(_ []byte, p security.PasswordHashParams) ([]byte, error) {
+		params = p
+		return nil, errors.New("stop")
+	}
+
+	_, err := hashTenantPassword(passwordConfigRepo(`{"hash_algorithm":"argon2id"}`), 1, "S3cur3P@ss!")
+	require.Error(t, err)
+	assert.Equal(t, security.PasswordHashArgon2id, params.Algorithm)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
+)
+
+// tenantPasswordPolicy reads the password policy from the tenant's password
+// security settings. Missing values keep the defaults of
+// security.DefaultPasswordPolicy.
+func tenantPasswordPolicy(securitySettingRepo repository.SecuritySettingRepository, tenantID int64) (security.PasswordPolicy, error) {
+	setting, err := securitySettingRepo.FindByUserPoolID(tenantID)
+	if err != nil {
+		return security.DefaultPasswordPolicy(), apperror.NewInternal("failed to load security settings", err)
+	}
+	if setting == nil {
+		return security.DefaultPasswordPolicy(), nil
+	}
+	return passwordPolicyFromConfig(unmarshalJSON(setting.PasswordConfig)), nil
+}
+
+// passwordPolicyFromConfig overlays the policy settings of a password config
+// on the defaults. Lengths that could not be met fall back to the default
+// lengths.
+func passwordPolicyFromConfig(config map[string]any) security.PasswordPolicy {
+	defaults := security.DefaultPasswordPolicy()
+	policy := defaults
+	if minLength, ok := config[model.PasswordConfigMinLength].(float64); ok {
+		policy.MinLength = int(minLength)
+	}
+	if maxLength, ok := config[model.PasswordConfigMaxLength].(float64); ok {
+		policy.MaxLength = int(maxLength)
+	}
+	if policy.Validate() != nil {
+		policy.MinLength, policy.MaxLength = defaults.MinLength, defaults.MaxLength
+	}
+
+	rules := []struct {
+		key string
+		set *bool
+	}{
+		{model.PasswordConfigRequireUppercase, &policy.RequireUppercase},
+		{model.PasswordConfigRequireLowercase, &policy.RequireLowercase},
+		{model.PasswordConfigRequireNumber, &policy.RequireNumber},
+		{model.PasswordConfigRequireSymbol, &policy.RequireSymbol},
+	}
+	for _, rule := range rules {
+		if required, ok := config[rule.key].(bool); ok {
+			*rule.set = required
+		}
+	}
+	return policy
+}
+
+// checkTenantPassword rejects a new password that breaks the tenant's
+// password policy.
+func checkTenantPassword(securitySettingRepo repository.SecuritySettingRepository, tenantID int64, password string) error {
+	policy, err := tenantPasswordPolicy(securitySettingRepo, tenantID)
+	if err != nil {
+		return err
+	}
+	if err := policy.Check(password); err != nil {
+		return apperror.NewValidation(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/security"
+)
+
+func TestTenantPasswordPolicy(t *testing.T) {
+	t.Run("no settings", func(t *testing.T) {
+		policy, err := tenantPasswordPolicy(&mockSecuritySettingRepo{}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, security.DefaultPasswordPolicy(), policy)
+	})
+
+	t.Run("configured policy", func(t *testing.T) {
+		policy, err := tenantPasswordPolicy(passwordConfigRepo(
+			`{"min_length":15,"max_length":64,"require_uppercase":false,"require_symbol":false}`), 1)
+		require.NoError(t, err)
+		assert.Equal(t, security.PasswordPolicy{
+			MinLength: 15, MaxLength: 64, RequireLowercase: true, RequireNumber: true,
+		}, policy)
+	})
+
+	t.Run("lengths that cannot be met fall back to the defaults", func(t *testing.T) {
+		policy, err := tenantPasswordPolicy(passwordConfigRepo(`{"min_length":20,"max_length":10,"require_number":false}`), 1)
+		require.NoError(t, err)
+		defaults := security.DefaultPasswordPolicy()
+		assert.Equal(t, defaults.MinLength, policy.MinLength)
+		assert.Equal(t, defaults.MaxLength, policy.MaxLength)
+		assert.False(t, policy.RequireNumber, "composition rules are kept")
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		_, err := tenantPasswordPolicy(&mockSecuritySettingRepo{
+			findByUserPoolIDFn: func(int64) (*model.SecuritySetting, error) { return nil, errors.New("db down") },
+		}, 1)
+		var internal *apperror.InternalError
+		assert.ErrorAs(t, err, &internal)
+	})
+}
+
+func TestCheckTenantPassword(t *testing.T) {
+	repo := passwordConfigRepo(`{"min_length":12,"require_uppercase":false,"require_number":false,"require_symbol":false}`)
+	assert.NoError(t, checkTenantPassword(repo, 1, "correct horse battery"))
+
+	err := checkTenantPassword(repo, 1, "too short")
+	var validation *apperror.ValidationError
+	require.ErrorAs(t, err, &validation)
+	assert.Contains(t, err.Error(), "at least 12 characters")
+}
@@ -0,0 +1,456 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+type PermissionGroupServiceDataResult struct {
+	PermissionGroupUUID uuid.UUID
+	Name                string
+	Description         string
+	Permissions         []PermissionServiceDataResult
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+type PermissionGroupServiceGetFilter struct {
+	TenantID    int64
+	Name        *string
+	Description *string
+	Page        int
+	Limit       int
+	SortBy      string
+	SortOrder   string
+}
+
+type PermissionGroupServiceGetResult struct {
+	Data       []PermissionGroupServiceDataResult
+	Total      int64
+	Page       int
+	Limit      int
+	TotalPages int
+}
+
+// PermissionGroupService manages permission groups, named bundles of
+// permissions, and expands them into the permissions they contain so that
+// they can be assigned to roles, clients and API keys in one call.
+type PermissionGroupService interface {
+	Get(ctx context.Context, filter PermissionGroupServiceGetFilter) (*PermissionGroupServiceGetResult, error)
+	GetByUUID(ctx context.Context, groupUUID uuid.UUID, tenantID int64) (*PermissionGroupServiceDataResult, error)
+	Create(ctx context.Context, tenantID int64, name string, description string, permissionUUIDs []uuid.UUID) (*PermissionGroupServiceDataResult, error)
+	Update(ctx context.Context, groupUUID uuid.UUID, tenantID int64, name string, description string) (*PermissionGroupServiceDataResult, error)
+	DeleteByUUID(ctx context.Context, groupUUID uuid.UUID, tenantID int64) (*PermissionGroupServiceDataResult, error)
+	AddPermissions(ctx context.Context, groupUUID uuid.UUID, tenantID int64, permissionUUIDs []uuid.UUID) (*PermissionGroupServiceDataResult, error)
+	RemovePermission(ctx context.Context, groupUUID uuid.UUID, tenantID int64, permissionUUID uuid.UUID) (*PermissionGroupServiceDataResult, error)
+
+	// ExpandPermissions returns the distinct permission UUIDs of the given
+	// groups, in group order. When apiUUID is set only the permissions of
+	// that API are returned. Every group must belong to the tenant.
+	ExpandPermissions(ctx context.Context, tenantID int64, groupUUIDs []uuid.UUID, apiUUID *uuid.UUID) ([]uuid.UUID, error)
+}
+
+type permissionGroupService struct {
+	db                  *gorm.DB
+	permissionGroupRepo repository.PermissionGroupRepository
+	permissionRepo      repository.PermissionRepository
+}
+
+func NewPermissionGroupService(
+	db *gorm.DB,
+	permissionGroupRepo repository.PermissionGroupRepository,
+	permissionRepo repository.PermissionRepository,
+) PermissionGroupService {
+	return &permissionGroupService{
+		db:                  db,
+		permissionGroupRepo: permissionGroupRepo,
+		permissionRepo:      permissionRepo,
+	}
+}
+
+func (s *permissionGroupService) Get(ctx context.Context, filter PermissionGroupServiceGetFilter) (*PermissionGroupServiceGetResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "permissionGroup.list")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", filter.TenantID))
+
+	result, err := s.permissionGroupRepo.FindPaginated(repository.PermissionGroupRepositoryGetFilter{
+		TenantID:    filter.TenantID,
+		Name:        filter.Name,
+		Description: filter.Description,
+		Page:        filter.Page,
+		Limit:       filter.Limit,
+		SortBy:      filter.SortBy,
+		SortOrder:   filter.SortOrder,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list permission groups failed")
+		return nil, err
+	}
+
+	data := make([]PermissionGroupServiceDataResult, len(result.Data))
+	for i := range result.Data {
+		data[i] = *toPermissionGroupServiceDataResult(&result.Data[i])
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return &PermissionGroupServiceGetResult{
+		Data:       data,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}, nil
+}
+
+func (s *permissionGroupService) GetByUUID(ctx context.Context, groupUUID uuid.UUID, tenantID int64) (*PermissionGroupServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "permissionGroup.get")
+	defer span.End()
+	span.SetAttributes(attribute.String("permission_group.uuid", groupUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	group, err := s.permissionGroupRepo.FindByUUIDAndTenantID(groupUUID, tenantID, "Permissions.API")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get permission group failed")
+		return nil, err
+	}
+	if group == nil {
+		return nil, apperror.NewNotFound("permission group")
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toPermissionGroupServiceDataResult(group), nil
+}
+
+func (s *permissionGroupService) Create(ctx context.Context, tenantID int64, name string, description string, permissionUUIDs []uuid.UUID) (*PermissionGroupServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "permissionGroup.create")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID))
+
+	var created *model.PermissionGroup
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txGroupRepo := s.permissionGroupRepo.WithTx(tx)
+
+		existing, err := txGroupRepo.FindByName(name, tenantID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return apperror.NewConflict("permission group with name '" + name + "' already exists")
+		}
+
+		permissionIDs, err := resolveGroupPermissions(s.permissionRepo.WithTx(tx), tenantID, permissionUUIDs)
+		if err != nil {
+			return err
+		}
+
+		group := &model.PermissionGroup{
+			PermissionGroupUUID: uuid.New(),
+			TenantID:            tenantID,
+			Name:                name,
+			Description:         description,
+		}
+		if _, err := txGroupRepo.Create(group); err != nil {
+			return err
+		}
+		if err := txGroupRepo.AddPermissions(group.PermissionGroupID, permissionIDs); err != nil {
+			return err
+		}
+
+		created, err = txGroupRepo.FindByUUIDAndTenantID(group.PermissionGroupUUID, tenantID, "Permissions.API")
+		return err
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "create permission group failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toPermissionGroupServiceDataResult(created), nil
+}
+
+func (s *permissionGroupService) Update(ctx context.Context, groupUUID uuid.UUID, tenantID int64, name string, description string) (*PermissionGroupServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "permissionGroup.update")
+	defer span.End()
+	span.SetAttributes(attribute.String("permission_group.uuid", groupUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	var updated *model.PermissionGroup
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txGroupRepo := s.permissionGroupRepo.WithTx(tx)
+
+		group, err := txGroupRepo.FindByUUIDAndTenantID(groupUUID, tenantID)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return apperror.NewNotFoundWithReason("permission group not found or access denied")
+		}
+
+		if group.Name != name {
+			existing, err := txGroupRepo.FindByName(name, tenantID)
+			if err != nil {
+				return err
+			}
+			if existing != nil && existing.PermissionGroupUUID != groupUUID {
+				return apperror.NewConflict("permission group with name '" + name + "' already exists")
+			}
+		}
+
+		// A map so that an empty description is written too.
+		if _, err := txGroupRepo.UpdateByUUID(groupUUID, map[string]any{
+			"name":        name,
+			"description": description,
+		}); err != nil {
+			return err
+		}
+
+		updated, err = txGroupRepo.FindByUUIDAndTenantID(groupUUID, tenantID, "Permissions.API")
+		return err
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "update permission group failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toPermissionGroupServiceDataResult(updated), nil
+}
+
+func (s *permissionGroupService) DeleteByUUID(ctx context.Context, groupUUID uuid.UUID, tenantID int64) (*PermissionGroupServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "permissionGroup.delete")
+	defer span.End()
+	span.SetAttributes(attribute.String("permission_group.uuid", groupUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	var deleted *model.PermissionGroup
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txGroupRepo := s.permissionGroupRepo.WithTx(tx)
+
+		group, err := txGroupRepo.FindByUUIDAndTenantID(groupUUID, tenantID, "Permissions.API")
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return apperror.NewNotFoundWithReason("permission group not found or access denied")
+		}
+		deleted = group
+
+		return txGroupRepo.DeleteByUUIDAndTenantID(groupUUID, tenantID)
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete permission group failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toPermissionGroupServiceDataResult(deleted), nil
+}
+
+func (s *permissionGroupService) AddPermissions(ctx context.Context, groupUUID uuid.UUID, tenantID int64, permissionUUIDs []uuid.UUID) (*PermissionGroupServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "permissionGroup.addPermissions")
+	defer span.End()
+	span.SetAttributes(attribute.String("permission_group.uuid", groupUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	var updated *model.PermissionGroup
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txGroupRepo := s.permissionGroupRepo.WithTx(tx)
+
+		group, err := txGroupRepo.FindByUUIDAndTenantID(groupUUID, tenantID)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return apperror.NewNotFoundWithReason("permission group not found or access denied")
+		}
+
+		permissionIDs, err := resolveGroupPermissions(s.permissionRepo.WithTx(tx), tenantID, permissionUUIDs)
+		if err != nil {
+			return err
+		}
+		if err := txGroupRepo.AddPermissions(group.PermissionGroupID, permissionIDs); err != nil {
+			return err
+		}
+
+		updated, err = txGroupRepo.FindByUUIDAndTenantID(groupUUID, tenantID, "Permissions.API")
+		return err
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "add permission group permissions failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toPermissionGroupServiceDataResult(updated), nil
+}
+
+func (s *permissionGroupService) RemovePermission(ctx context.Context, groupUUID uuid.UUID, tenantID int64, permissionUUID uuid.UUID) (*PermissionGroupServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "permissionGroup.removePermission")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("permission_group.uuid", groupUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+		attribute.String("permission.uuid", permissionUUID.String()),
+	)
+
+	var updated *model.PermissionGroup
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txGroupRepo := s.permissionGroupRepo.WithTx(tx)
+
+		group, err := txGroupRepo.FindByUUIDAndTenantID(groupUUID, tenantID)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return apperror.NewNotFoundWithReason("permission group not found or access denied")
+		}
+
+		permission, err := s.permissionRepo.WithTx(tx).FindByUUID(permissionUUID)
+		if err != nil {
+			return err
+		}
+		if permission == nil || permission.TenantID != tenantID {
+			return apperror.NewNotFoundWithReason("permission not found")
+		}
+
+		removed, err := txGroupRepo.RemovePermission(group.PermissionGroupID, permission.PermissionID)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return apperror.NewNotFoundWithReason("permission is not in the permission group")
+		}
+
+		updated, err = txGroupRepo.FindByUUIDAndTenantID(groupUUID, tenantID, "Permissions.API")
+		return err
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "remove permission group permission failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toPermissionGroupServiceDataResult(updated), nil
+}
+
+func (s *permissionGroupService) ExpandPermissions(ctx context.Context, tenantID int64, groupUUIDs []uuid.UUID, apiUUID *uuid.UUID) ([]uuid.UUID, error) {
+	_, span := otel.Tracer("service").Start(ctx, "permissionGroup.expand")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID), attribute.Int("permission_group.count", len(groupUUIDs)))
+
+	groupUUIDs = uniqueUUIDs(groupUUIDs)
+	groups, err := s.permissionGroupRepo.FindByUUIDsAndTenantID(groupUUIDs, tenantID, "Permissions.API")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "expand permission groups failed")
+		return nil, err
+	}
+	if len(groups) != len(groupUUIDs) {
+		return nil, apperror.NewNotFoundWithReason("one or more permission groups not found")
+	}
+
+	seen := make(map[uuid.UUID]struct{})
+	permissionUUIDs := []uuid.UUID{}
+	for _, group := range groups {
+		for _, permission := range group.Permissions {
+			if apiUUID != nil && (permission.API == nil || permission.API.APIUUID != *apiUUID) {
+				continue
+			}
+			if _, ok := seen[permission.PermissionUUID]; ok {
+				continue
+			}
+			seen[permission.PermissionUUID] = struct{}{}
+			permissionUUIDs = append(permissionUUIDs, permission.PermissionUUID)
+		}
+	}
+	if len(permissionUUIDs) == 0 {
+		if apiUUID != nil {
+			return nil, apperror.NewValidation("permission groups contain no permissions of this API")
+		}
+		return nil, apperror.NewValidation("permission groups contain no permissions")
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return permissionUUIDs, nil
+}
+
+// resolveGroupPermissions looks up the permissions to put in a group and
+// returns their IDs. Every permission must exist and belong to the tenant.
+func resolveGroupPermissions(permissionRepo repository.PermissionRepository, tenantID int64, permissionUUIDs []uuid.UUID) ([]int64, error) {
+	permissionUUIDs = uniqueUUIDs(permissionUUIDs)
+	if len(permissionUUIDs) == 0 {
+		return nil, nil
+	}
+
+	uuidStrings := make([]string, len(permissionUUIDs))
+	for i, id := range permissionUUIDs {
+		uuidStrings[i] = id.String()
+	}
+	permissions, err := permissionRepo.FindByUUIDs(uuidStrings)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(permissions))
+	for _, permission := range permissions {
+		if permission.TenantID != tenantID {
+			continue
+		}
+		ids = append(ids, permission.PermissionID)
+	}
+	if len(ids) != len(permissionUUIDs) {
+		return nil, apperror.NewNotFoundWithReason("one or more permissions not found")
+	}
+	return ids, nil
+}
+
+// uniqueUUIDs returns ids without duplicates, keeping the first occurrence.
+func uniqueUUIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out
+}
+
+func toPermissionGroupServiceDataResult(group *model.PermissionGroup) *PermissionGroupServiceDataResult {
+	permissions := make([]PermissionServiceDataResult, len(group.Permissions))
+	for i := range group.Permissions {
+		permissions[i] = *toPermissionServiceDataResult(&group.Permissions[i])
+	}
+	return &PermissionGroupServiceDataResult{
+		PermissionGroupUUID: group.PermissionGroupUUID,
+		Name:                group.Name,
+		Description:         group.Description,
+		Permissions:         permissions,
+		CreatedAt:           group.CreatedAt,
+		UpdatedAt:           group.UpdatedAt,
+	}
+}
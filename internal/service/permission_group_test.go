@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPermissionGroup(tenantID int64, name string, permissions ...model.Permission) *model.PermissionGroup {
+	return &model.PermissionGroup{
+		PermissionGroupID:   1,
+		PermissionGroupUUID: uuid.New(),
+		TenantID:            tenantID,
+		Name:                name,
+		Permissions:         permissions,
+	}
+}
+
+func newGroupPermission(id, tenantID int64, name string, api *model.API) model.Permission {
+	p := model.Permission{
+		PermissionID:   id,
+		PermissionUUID: uuid.New(),
+		TenantID:       tenantID,
+		Name:           name,
+		API:            api,
+	}
+	if api != nil {
+		p.APIID = api.APIID
+	}
+	return p
+}
+
+// ---------------------------------------------------------------------------
+// Get / GetByUUID
+// ---------------------------------------------------------------------------
+
+func TestPermissionGroupService_Get(t *testing.T) {
+	group := newPermissionGroup(1, "read-only", newGroupPermission(1, 1, "user:read", nil))
+	repo := &mockPermissionGroupRepo{
+		findPaginatedFn: func(f repository.PermissionGroupRepositoryGetFilter) (*repository.PaginationResult[model.PermissionGroup], error) {
+			assert.Equal(t, int64(1), f.TenantID)
+			return &repository.PaginationResult[model.PermissionGroup]{
+				Data: []model.PermissionGroup{*group}, Total: 1, Page: 1, Limit: 10, TotalPages: 1,
+			}, nil
+		},
+	}
+	svc := NewPermissionGroupService(nil, repo, &mockPermissionRepo{})
+
+	result, err := svc.Get(context.Background(), PermissionGroupServiceGetFilter{TenantID: 1, Page: 1, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, result.Data, 1)
+	assert.Equal(t, "read-only", result.Data[0].Name)
+	require.Len(t, result.Data[0].Permissions, 1)
+	assert.Equal(t, "user:read", result.Data[0].Permissions[0].Name)
+}
+
+func TestPermissionGroupService_GetByUUID(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		svc := NewPermissionGroupService(nil, &mockPermissionGroupRepo{}, &mockPermissionRepo{})
+		_, err := svc.GetByUUID(context.Background(), uuid.New(), 1)
+		require.Error(t, err)
+		var notFound *apperror.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo := &mockPermissionGroupRepo{
+			findByUUIDAndTenantIDFn: func(uuid.UUID, int64) (*model.PermissionGroup, error) {
+				return nil, errors.New("db error")
+			},
+		}
+		svc := NewPermissionGroupService(nil, repo, &mockPermissionRepo{})
+		_, err := svc.GetByUUID(context.Background(), uuid.New(), 1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "db error")
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Create
+// ---------------------------------------------------------------------------
+
+func TestPermissionGroupService_Create(t *testing.T) {
+	tenantID := int64(1)
+	readUser := newGroupPermission(10, tenantID, "user:read", nil)
+	otherTenant := newGroupPermission(11, 2, "user:read", nil)
+
+	cases := []struct {
+		name        string
+		existing    *model.PermissionGroup
+		permissions []model.Permission
+		request     []uuid.UUID
+		wantErr     string
+		wantAdded   []int64
+	}{
+		{
+			name:     "duplicate name",
+			existing: newPermissionGroup(tenantID, "read-only"),
+			wantErr:  "already exists",
+		},
+		{
+			name:        "permission of another tenant",
+			permissions: []model.Permission{otherTenant},
+			request:     []uuid.UUID{otherTenant.PermissionUUID},
+			wantErr:     "one or more permissions not found",
+		},
+		{
+			name:        "success with duplicate permission uuids",
+			permissions: []model.Permission{readUser},
+			request:     []uuid.UUID{readUser.PermissionUUID, readUser.PermissionUUID},
+			wantAdded:   []int64{10},
+		},
+		{
+			name: "success without permissions",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gormDB, mock := newMockGormDB(t)
+			mock.ExpectBegin()
+			if tc.wantErr != "" {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+
+			var added []int64
+			var created *model.PermissionGroup
+			groupRepo := &mockPermissionGroupRepo{
+				findByNameFn: func(string, int64) (*model.PermissionGroup, error) { return tc.existing, nil },
+				createFn: func(g *model.PermissionGroup) (*model.PermissionGroup, error) {
+					g.PermissionGroupID = 7
+					created = g
+					return g, nil
+				},
+				addPermissionsFn: func(id int64, perms []int64) error {
+					assert.Equal(t, int64(7), id)
+					added = perms
+					return nil
+				},
+				findByUUIDAndTenantIDFn: func(uuid.UUID, int64) (*model.PermissionGroup, error) { return created, nil },
+			}
+			permissionRepo := &mockPermissionRepo{
+				findByUUIDsFn: func(ids []string, _ ...string) ([]model.Permission, error) {
+					assert.Len(t, ids, 1, "uuids are deduplicated")
+					return tc.permissions, nil
+				},
+			}
+			svc := NewPermissionGroupService(gormDB, groupRepo, permissionRepo)
+
+			result, err := svc.Create(context.Background(), tenantID, "read-only", "", tc.request)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "read-only", result.Name)
+			assert.Equal(t, tc.wantAdded, added)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Update
+// ---------------------------------------------------------------------------
+
+func TestPermissionGroupService_Update(t *testing.T) {
+	tenantID := int64(1)
+	group := newPermissionGroup(tenantID, "read-only")
+
+	t.Run("name taken by another group", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		repo := &mockPermissionGroupRepo{
+			findByUUIDAndTenantIDFn: func(uuid.UUID, int64) (*model.PermissionGroup, error) { return group, nil },
+			findByNameFn: func(string, int64) (*model.PermissionGroup, error) {
+				return newPermissionGroup(tenantID, "user-admin"), nil
+			},
+		}
+		svc := NewPermissionGroupService(gormDB, repo, &mockPermissionRepo{})
+		_, err := svc.Update(context.Background(), group.PermissionGroupUUID, tenantID, "user-admin", "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already exists")
+	})
+
+	t.Run("success writes empty description", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		var data any
+		repo := &mockPermissionGroupRepo{
+			findByUUIDAndTenantIDFn: func(uuid.UUID, int64) (*model.PermissionGroup, error) { return group, nil },
+			updateByUUIDFn: func(_ any, d any) (*model.PermissionGroup, error) {
+				data = d
+				return group, nil
+			},
+		}
+		svc := NewPermissionGroupService(gormDB, repo, &mockPermissionRepo{})
+		_, err := svc.Update(context.Background(), group.PermissionGroupUUID, tenantID, "read-only", "")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "read-only", "description": ""}, data)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// RemovePermission
+// ---------------------------------------------------------------------------
+
+func TestPermissionGroupService_RemovePermission(t *testing.T) {
+	tenantID := int64(1)
+	group := newPermissionGroup(tenantID, "read-only")
+	permission := newGroupPermission(10, tenantID, "user:read", nil)
+
+	t.Run("permission not in group", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		repo := &mockPermissionGroupRepo{
+			findByUUIDAndTenantIDFn: func(uuid.UUID, int64) (*model.PermissionGroup, error) { return group, nil },
+			removePermissionFn:      func(int64, int64) (bool, error) { return false, nil },
+		}
+		permissionRepo := &mockPermissionRepo{
+			findByUUIDFn: func(any, ...string) (*model.Permission, error) { return &permission, nil },
+		}
+		svc := NewPermissionGroupService(gormDB, repo, permissionRepo)
+		_, err := svc.RemovePermission(context.Background(), group.PermissionGroupUUID, tenantID, permission.PermissionUUID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not in the permission group")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		repo := &mockPermissionGroupRepo{
+			findByUUIDAndTenantIDFn: func(uuid.UUID, int64) (*model.PermissionGroup, error) { return group, nil },
+			removePermissionFn: func(groupID, permissionID int64) (bool, error) {
+				assert.Equal(t, group.PermissionGroupID, groupID)
+				assert.Equal(t, permission.PermissionID, permissionID)
+				return true, nil
+			},
+		}
+		permissionRepo := &mockPermissionRepo{
+			findByUUIDFn: func(any, ...string) (*model.Permission, error) { return &permission, nil },
+		}
+		svc := NewPermissionGroupService(gormDB, repo, permissionRepo)
+		_, err := svc.RemovePermission(context.Background(), group.PermissionGroupUUID, tenantID, permission.PermissionUUID)
+		require.NoError(t, err)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// ExpandPermissions
+// ---------------------------------------------------------------------------
+
+func TestPermissionGroupService_ExpandPermissions(t *testing.T) {
+	tenantID := int64(1)
+	authAPI := &model.API{APIID: 1, APIUUID: uuid.New()}
+	billingAPI := &model.API{APIID: 2, APIUUID: uuid.New()}
+	readUser := newGroupPermission(10, tenantID, "user:read", authAPI)
+	updateUser := newGroupPermission(11, tenantID, "user:update", authAPI)
+	readInvoice := newGroupPermission(12, tenantID, "invoice:read", billingAPI)
+
+	readOnly := newPermissionGroup(tenantID, "read-only", readUser, readInvoice)
+	userAdmin := newPermissionGroup(tenantID, "user-admin", readUser, updateUser)
+	repo := &mockPermissionGroupRepo{
+		findByUUIDsAndTenantIDFn: func(ids []uuid.UUID, _ int64) ([]model.PermissionGroup, error) {
+			var groups []model.PermissionGroup
+			for _, g := range []*model.PermissionGroup{readOnly, userAdmin} {
+				for _, id := range ids {
+					if g.PermissionGroupUUID == id {
+						groups = append(groups, *g)
+					}
+				}
+			}
+			return groups, nil
+		},
+	}
+	svc := NewPermissionGroupService(nil, repo, &mockPermissionRepo{})
+	ctx := context.Background()
+
+	t.Run("union without duplicates", func(t *testing.T) {
+		got, err := svc.ExpandPermissions(ctx, tenantID, []uuid.UUID{readOnly.PermissionGroupUUID, userAdmin.PermissionGroupUUID, userAdmin.PermissionGroupUUID}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{readUser.PermissionUUID, readInvoice.PermissionUUID, updateUser.PermissionUUID}, got)
+	})
+
+	t.Run("filtered to one api", func(t *testing.T) {
+		got, err := svc.ExpandPermissions(ctx, tenantID, []uuid.UUID{readOnly.PermissionGroupUUID}, &billingAPI.APIUUID)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{readInvoice.PermissionUUID}, got)
+	})
+
+	t.Run("no permissions of the api", func(t *testing.T) {
+		_, err := svc.ExpandPermissions(ctx, tenantID, []uuid.UUID{userAdmin.PermissionGroupUUID}, &billingAPI.APIUUID)
+		require.Error(t, err)
+		var validation *apperror.ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+
+	t.Run("unknown group", func(t *testing.T) {
+		_, err := svc.ExpandPermissions(ctx, tenantID, []uuid.UUID{readOnly.PermissionGroupUUID, uuid.New()}, nil)
+		require.Error(t, err)
+		var notFound *apperror.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+}
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/eventbus"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// PermissionResolverEventTypes are the domain events after which cached
+// permission sets are dropped. User events affect the one user and group
+// membership events the users added or removed; role and other group events
+// may affect anyone holding the role.
+var PermissionResolverEventTypes = []string{
+	model.EventTypeUserRolesAdded,
+	model.EventTypeUserRolesRemoved,
+	model.EventTypeUserScopedRoleAdded,
+	model.EventTypeUserScopedRoleRemoved,
+	model.EventTypeUserDeleted,
+	model.EventTypeUserPurged,
+	model.EventTypeRoleUpdated,
+	model.EventTypeRoleDeleted,
+	model.EventTypeRolePermissionsAdded,
+	model.EventTypeRolePermissionsRemoved,
+	model.EventTypeGroupDeleted,
+	model.EventTypeGroupMembersAdded,
+	model.EventTypeGroupMembersRemoved,
+	model.EventTypeGroupRolesAdded,
+	model.EventTypeGroupRolesRemoved,
+}
+
+// PermissionResolver computes a user's effective permission set, the union
+// of the permissions of their roles across every tenant they hold roles in,
+// and caches it in Redis until a role or permission change invalidates it.
+// Roles assigned for a single client or group are kept apart from the set
+// and only apply to requests on that resource.
+type PermissionResolver interface {
+	// Resolve returns the user's effective permissions, from the cache when
+	// possible.
+	Resolve(ctx context.Context, user *model.User) (*dto.EffectivePermissionsResponseDTO, error)
+
+	// UserPermissions returns the union of the user's permissions across
+	// tenants. It lets the permission middleware use the cached set.
+	UserPermissions(ctx context.Context, user *model.User) ([]string, error)
+
+	// ResourcePermissions returns the permissions the user holds on one
+	// client or group through scoped role assignments.
+	ResourcePermissions(ctx context.Context, user *model.User, resourceType string, resourceUUID uuid.UUID) ([]string, error)
+
+	// MemberPermissions returns the permissions the user holds on another
+	// user through role assignments scoped to a group that user is a member
+	// of.
+	MemberPermissions(ctx context.Context, user *model.User, memberUUID uuid.UUID) ([]string, error)
+
+	// HandleEvent is an event bus subscriber for
+	// PermissionResolverEventTypes that drops the cached sets the event may
+	// have changed.
+	HandleEvent(ctx context.Context, e eventbus.Event) error
+}
+
+type permissionResolver struct {
+	userRepo           repository.UserRepository
+	scopedUserRoleRepo repository.ScopedUserRoleRepository
+	groupRepo          repository.GroupRepository
+	store              cache.UserPermissionStore
+}
+
+// NewPermissionResolver creates a new PermissionResolver.
+func NewPermissionResolver(
+	userRepo repository.UserRepository,
+	scopedUserRoleRepo repository.ScopedUserRoleRepository,
+	groupRepo repository.GroupRepository,
+	store cache.UserPermissionStore,
+) PermissionResolver {
+	return &permissionResolver{
+		userRepo:           userRepo,
+		scopedUserRoleRepo: scopedUserRoleRepo,
+		groupRepo:          groupRepo,
+		store:              store,
+	}
+}
+
+func (s *permissionResolver) Resolve(ctx context.Context, user *model.User) (*dto.EffectivePermissionsResponseDTO, error) {
+	perms, err := s.resolve(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return toEffectivePermissionsResponseDTO(perms), nil
+}
+
+func (s *permissionResolver) UserPermissions(ctx context.Context, user *model.User) ([]string, error) {
+	perms, err := s.resolve(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return unionPermissions(perms), nil
+}
+
+func (s *permissionResolver) ResourcePermissions(ctx context.Context, user *model.User, resourceType string, resourceUUID uuid.UUID) ([]string, error) {
+	perms, err := s.resolve(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range perms.Resources {
+		if r.ResourceType == resourceType && r.ResourceUUID == resourceUUID {
+			return r.Permissions, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *permissionResolver) MemberPermissions(ctx context.Context, user *model.User, memberUUID uuid.UUID) ([]string, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "permissionResolver.memberPermissions")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", user.UserUUID.String()), attribute.String("member.uuid", memberUUID.String()))
+
+	perms, err := s.resolve(ctx, user)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "resolve failed")
+		return nil, err
+	}
+
+	byGroup := make(map[uuid.UUID][]string)
+	var groupUUIDs []uuid.UUID
+	for _, r := range perms.Resources {
+		if r.ResourceType == model.ScopedRoleResourceGroup {
+			byGroup[r.ResourceUUID] = r.Permissions
+			groupUUIDs = append(groupUUIDs, r.ResourceUUID)
+		}
+	}
+	// Most users hold no group-scoped roles; skip the membership lookup
+	if len(groupUUIDs) == 0 {
+		span.SetStatus(codes.Ok, "")
+		return nil, nil
+	}
+
+	memberOf, err := s.groupRepo.FindUUIDsByMember(memberUUID, groupUUIDs)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find member groups failed")
+		return nil, apperror.NewInternal("failed to resolve member permissions", err)
+	}
+
+	seen := make(map[string]struct{})
+	names := []string{}
+	for _, groupUUID := range memberOf {
+		for _, name := range byGroup[groupUUID] {
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	span.SetStatus(codes.Ok, "")
+	return names, nil
+}
+
+// resolve returns the cached permission set of user, loading and caching it
+// on a miss.
+func (s *permissionResolver) resolve(ctx context.Context, user *model.User) (*cache.UserPermissions, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "permissionResolver.resolve")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", user.UserUUID.String()))
+
+	if perms := s.store.GetUserPermissions(ctx, user.UserUUID); perms != nil {
+		span.SetAttributes(attribute.Bool("cache.hit", true))
+		span.SetStatus(codes.Ok, "")
+		return perms, nil
+	}
+	span.SetAttributes(attribute.Bool("cache.hit", false))
+
+	grants, err := s.userRepo.FindPermissionGrants(user.UserID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find permission grants failed")
+		return nil, apperror.NewInternal("failed to resolve permissions", err)
+	}
+
+	scoped, err := s.scopedUserRoleRepo.FindPermissionGrants(user.UserID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find scoped permission grants failed")
+		return nil, apperror.NewInternal("failed to resolve permissions", err)
+	}
+
+	perms := groupPermissionGrants(grants)
+	perms.Resources = groupScopedPermissionGrants(scoped)
+	s.store.SetUserPermissions(ctx, user.UserUUID, perms)
+
+	span.SetStatus(codes.Ok, "")
+	return perms, nil
+}
+
+func (s *permissionResolver) HandleEvent(ctx context.Context, e eventbus.Event) error {
+	ctx, span := otel.Tracer("service").Start(ctx, "permissionResolver.handleEvent")
+	defer span.End()
+	span.SetAttributes(attribute.String("event.type", e.Type))
+
+	switch {
+	case strings.HasPrefix(e.Type, "user."):
+		s.store.InvalidateUserPermissions(ctx, e.AggregateUUID)
+	case e.Type == model.EventTypeGroupMembersAdded || e.Type == model.EventTypeGroupMembersRemoved:
+		var payload groupMembersEventPayload
+		if err := json.Unmarshal(e.Payload, &payload); err != nil {
+			// Without the members every set may be stale.
+			s.store.InvalidateAllUserPermissions(ctx)
+			break
+		}
+		for _, userUUID := range payload.UserUUIDs {
+			s.store.InvalidateUserPermissions(ctx, userUUID)
+		}
+	default:
+		s.store.InvalidateAllUserPermissions(ctx)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// groupPermissionGrants collects grants into one permission set per tenant,
+// keeping the order of grants.
+func groupPermissionGrants(grants []repository.PermissionGrant) *cache.UserPermissions {
+	perms := &cache.UserPermissions{Tenants: []cache.TenantPermissions{}}
+	for _, g := range grants {
+		n := len(perms.Tenants)
+		if n == 0 || perms.Tenants[n-1].TenantUUID != g.TenantUUID {
+			perms.Tenants = append(perms.Tenants, cache.TenantPermissions{TenantUUID: g.TenantUUID})
+			n++
+		}
+		perms.Tenants[n-1].Permissions = append(perms.Tenants[n-1].Permissions, g.PermissionName)
+	}
+	return perms
+}
+
+// groupScopedPermissionGrants collects scoped grants into one permission set
+// per resource, keeping the order of grants.
+func groupScopedPermissionGrants(grants []repository.ScopedPermissionGrant) []cache.ResourcePermissions {
+	var resources []cache.ResourcePermissions
+	for _, g := range grants {
+		n := len(resources)
+		if n == 0 || resources[n-1].ResourceType != g.ResourceType || resources[n-1].ResourceUUID != g.ResourceUUID {
+			resources = append(resources, cache.ResourcePermissions{ResourceType: g.ResourceType, ResourceUUID: g.ResourceUUID})
+			n++
+		}
+		resources[n-1].Permissions = append(resources[n-1].Permissions, g.PermissionName)
+	}
+	return resources
+}
+
+// unionPermissions returns the distinct permissions of every tenant, sorted.
+func unionPermissions(perms *cache.UserPermissions) []string {
+	seen := make(map[string]struct{})
+	names := []string{}
+	for _, t := range perms.Tenants {
+		for _, name := range t.Permissions {
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func toEffectivePermissionsResponseDTO(perms *cache.UserPermissions) *dto.EffectivePermissionsResponseDTO {
+	tenants := make([]dto.TenantPermissionsResponseDTO, len(perms.Tenants))
+	for i, t := range perms.Tenants {
+		tenants[i] = dto.TenantPermissionsResponseDTO{
+			TenantUUID:  t.TenantUUID,
+			Permissions: t.Permissions,
+		}
+	}
+	resources := make([]dto.ResourcePermissionsResponseDTO, len(perms.Resources))
+	for i, r := range perms.Resources {
+		resources[i] = dto.ResourcePermissionsResponseDTO{
+			ResourceType: r.ResourceType,
+			ResourceUUID: r.ResourceUUID,
+			Permissions:  r.Permissions,
+		}
+	}
+	return &dto.EffectivePermissionsResponseDTO{
+		Permissions: unionPermissions(perms),
+		Tenants:     tenants,
+		Resources:   resources,
+	}
+}
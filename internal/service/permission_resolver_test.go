@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/eventbus"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePermissionStore is an in-memory cache.UserPermissionStore.
+type fakePermissionStore struct {
+	sets       map[uuid.UUID]*cache.UserPermissions
+	clearedAll bool
+}
+
+func newFakePermissionStore() *fakePermissionStore {
+	return &fakePermissionStore{sets: map[uuid.UUID]*cache.UserPermissions{}}
+}
+
+func (f *fakePermissionStore) GetUserPermissions(_ context.Context, id uuid.UUID) *cache.UserPermissions {
+	return f.sets[id]
+}
+
+func (f *fakePermissionStore) SetUserPermissions(_ context.Context, id uuid.UUID, perms *cache.UserPermissions) {
+	f.sets[id] = perms
+}
+
+func (f *fakePermissionStore) InvalidateUserPermissions(_ context.Context, id uuid.UUID) {
+	delete(f.sets, id)
+}
+
+func (f *fakePermissionStore) InvalidateAllUserPermissions(context.Context) {
+	f.sets = map[uuid.UUID]*cache.UserPermissions{}
+	f.clearedAll = true
+}
+
+func TestPermissionResolver_Resolve(t *testing.T) {
+	user := &model.User{UserID: 7, UserUUID: uuid.New()}
+	tenantA, tenantB := uuid.New(), uuid.New()
+
+	t.Run("loads, groups by tenant and caches", func(t *testing.T) {
+		store := newFakePermissionStore()
+		calls := 0
+		repo := &mockUserRepo{findPermissionGrantsFn: func(userID int64) ([]repository.PermissionGrant, error) {
+			calls++
+			assert.Equal(t, int64(7), userID)
+			return []repository.PermissionGrant{
+				{TenantUUID: tenantA, PermissionName: "role:read"},
+				{TenantUUID: tenantA, PermissionName: "user:read"},
+				{TenantUUID: tenantB, PermissionName: "user:read"},
+			}, nil
+		}}
+		svc := NewPermissionResolver(repo, &mockScopedUserRoleRepo{}, &mockGroupRepo{}, store)
+
+		got, err := svc.Resolve(context.Background(), user)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"role:read", "user:read"}, got.Permissions)
+		require.Len(t, got.Tenants, 2)
+		assert.Equal(t, tenantA, got.Tenants[0].TenantUUID)
+		assert.Equal(t, []string{"role:read", "user:read"}, got.Tenants[0].Permissions)
+		assert.Equal(t, tenantB, got.Tenants[1].TenantUUID)
+		assert.Equal(t, []string{"user:read"}, got.Tenants[1].Permissions)
+
+		names, err := svc.UserPermissions(context.Background(), user)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"role:read", "user:read"}, names)
+		assert.Equal(t, 1, calls, "second lookup must be served from the cache")
+	})
+
+	t.Run("no roles", func(t *testing.T) {
+		svc := NewPermissionResolver(&mockUserRepo{}, &mockScopedUserRoleRepo{}, &mockGroupRepo{}, newFakePermissionStore())
+		got, err := svc.Resolve(context.Background(), user)
+		require.NoError(t, err)
+		assert.Empty(t, got.Permissions)
+		assert.Empty(t, got.Tenants)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		store := newFakePermissionStore()
+		repo := &mockUserRepo{findPermissionGrantsFn: func(int64) ([]repository.PermissionGrant, error) {
+			return nil, errors.New("db down")
+		}}
+		svc := NewPermissionResolver(repo, &mockScopedUserRoleRepo{}, &mockGroupRepo{}, store)
+
+		_, err := svc.UserPermissions(context.Background(), user)
+		var internal *apperror.InternalError
+		assert.ErrorAs(t, err, &internal)
+		assert.Empty(t, store.sets, "failures must not be cached")
+	})
+}
+
+func TestPermissionResolver_ScopedRoles(t *testing.T) {
+	user := &model.User{UserID: 7, UserUUID: uuid.New()}
+	tenant, client, group := uuid.New(), uuid.New(), uuid.New()
+	member, outsider := uuid.New(), uuid.New()
+
+	newResolver := func(store *fakePermissionStore) PermissionResolver {
+		userRepo := &mockUserRepo{findPermissionGrantsFn: func(int64) ([]repository.PermissionGrant, error) {
+			return []repository.PermissionGrant{{TenantUUID: tenant, PermissionName: "account:user:read:self"}}, nil
+		}}
+		scopedRepo := &mockScopedUserRoleRepo{findPermissionGrantsFn: func(userID int64) ([]repository.ScopedPermissionGrant, error) {
+			assert.Equal(t, int64(7), userID)
+			return []repository.ScopedPermissionGrant{
+				{ResourceType: model.ScopedRoleResourceClient, ResourceUUID: client, PermissionName: "client:read"},
+				{ResourceType: model.ScopedRoleResourceClient, ResourceUUID: client, PermissionName: "client:update"},
+				{ResourceType: model.ScopedRoleResourceGroup, ResourceUUID: group, PermissionName: "user:update"},
+			}, nil
+		}}
+		groupRepo := &mockGroupRepo{findUUIDsByMemberFn: func(userUUID uuid.UUID, groupUUIDs []uuid.UUID) ([]uuid.UUID, error) {
+			assert.Equal(t, []uuid.UUID{group}, groupUUIDs)
+			if userUUID == member {
+				return []uuid.UUID{group}, nil
+			}
+			return nil, nil
+		}}
+		return NewPermissionResolver(userRepo, scopedRepo, groupRepo, store)
+	}
+
+	t.Run("scoped permissions are kept out of the union", func(t *testing.T) {
+		store := newFakePermissionStore()
+		got, err := newResolver(store).Resolve(context.Background(), user)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"account:user:read:self"}, got.Permissions)
+		require.Len(t, got.Resources, 2)
+		assert.Equal(t, client, got.Resources[0].ResourceUUID)
+		assert.Equal(t, []string{"client:read", "client:update"}, got.Resources[0].Permissions)
+		assert.Len(t, store.sets[user.UserUUID].Resources, 2, "scoped permissions are cached with the set")
+	})
+
+	t.Run("resource permissions match type and UUID", func(t *testing.T) {
+		svc := newResolver(newFakePermissionStore())
+		names, err := svc.ResourcePermissions(context.Background(), user, model.ScopedRoleResourceClient, client)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"client:read", "client:update"}, names)
+
+		names, err = svc.ResourcePermissions(context.Background(), user, model.ScopedRoleResourceGroup, client)
+		require.NoError(t, err)
+		assert.Empty(t, names)
+	})
+
+	t.Run("member permissions apply to group members only", func(t *testing.T) {
+		svc := newResolver(newFakePermissionStore())
+		names, err := svc.MemberPermissions(context.Background(), user, member)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user:update"}, names)
+
+		names, err = svc.MemberPermissions(context.Background(), user, outsider)
+		require.NoError(t, err)
+		assert.Empty(t, names)
+	})
+
+	t.Run("no membership lookup without group scopes", func(t *testing.T) {
+		groupRepo := &mockGroupRepo{findUUIDsByMemberFn: func(uuid.UUID, []uuid.UUID) ([]uuid.UUID, error) {
+			t.Fatal("membership must not be looked up")
+			return nil, nil
+		}}
+		svc := NewPermissionResolver(&mockUserRepo{}, &mockScopedUserRoleRepo{}, groupRepo, newFakePermissionStore())
+		names, err := svc.MemberPermissions(context.Background(), user, member)
+		require.NoError(t, err)
+		assert.Empty(t, names)
+	})
+
+	t.Run("scoped repository error", func(t *testing.T) {
+		store := newFakePermissionStore()
+		scopedRepo := &mockScopedUserRoleRepo{findPermissionGrantsFn: func(int64) ([]repository.ScopedPermissionGrant, error) {
+			return nil, errors.New("db down")
+		}}
+		svc := NewPermissionResolver(&mockUserRepo{}, scopedRepo, &mockGroupRepo{}, store)
+		_, err := svc.UserPermissions(context.Background(), user)
+		var internal *apperror.InternalError
+		assert.ErrorAs(t, err, &internal)
+		assert.Empty(t, store.sets)
+	})
+}
+
+func TestPermissionResolver_HandleEvent(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	seed := func() *fakePermissionStore {
+		store := newFakePermissionStore()
+		store.sets[alice] = &cache.UserPermissions{}
+		store.sets[bob] = &cache.UserPermissions{}
+		return store
+	}
+
+	t.Run("user event drops that user", func(t *testing.T) {
+		store := seed()
+		svc := NewPermissionResolver(&mockUserRepo{}, &mockScopedUserRoleRepo{}, &mockGroupRepo{}, store)
+		require.NoError(t, svc.HandleEvent(context.Background(), eventbus.Event{
+			Type:          model.EventTypeUserRolesAdded,
+			AggregateUUID: alice,
+		}))
+		assert.NotContains(t, store.sets, alice)
+		assert.Contains(t, store.sets, bob)
+		assert.False(t, store.clearedAll)
+	})
+
+	t.Run("group membership event drops the members", func(t *testing.T) {
+		store := seed()
+		svc := NewPermissionResolver(&mockUserRepo{}, &mockScopedUserRoleRepo{}, &mockGroupRepo{}, store)
+		payload, err := json.Marshal(groupMembersEventPayload{GroupUUID: uuid.New(), UserUUIDs: []uuid.UUID{bob}})
+		require.NoError(t, err)
+		require.NoError(t, svc.HandleEvent(context.Background(), eventbus.Event{
+			Type:    model.EventTypeGroupMembersRemoved,
+			Payload: payload,
+		}))
+		assert.Contains(t, store.sets, alice)
+		assert.NotContains(t, store.sets, bob)
+		assert.False(t, store.clearedAll)
+	})
+
+	t.Run("undecodable group membership event drops everyone", func(t *testing.T) {
+		store := seed()
+		svc := NewPermissionResolver(&mockUserRepo{}, &mockScopedUserRoleRepo{}, &mockGroupRepo{}, store)
+		require.NoError(t, svc.HandleEvent(context.Background(), eventbus.Event{
+			Type:    model.EventTypeGroupMembersAdded,
+			Payload: []byte("{"),
+		}))
+		assert.True(t, store.clearedAll)
+	})
+
+	t.Run("role event drops everyone", func(t *testing.T) {
+		store := seed()
+		svc := NewPermissionResolver(&mockUserRepo{}, &mockScopedUserRoleRepo{}, &mockGroupRepo{}, store)
+		require.NoError(t, svc.HandleEvent(context.Background(), eventbus.Event{
+			Type:          model.EventTypeRolePermissionsRemoved,
+			AggregateUUID: uuid.New(),
+		}))
+		assert.Empty(t, store.sets)
+		assert.True(t, store.clearedAll)
+	})
+}
+
+func TestPermissionResolverEventTypes(t *testing.T) {
+	for _, eventType := range PermissionResolverEventTypes {
+		assert.Contains(t, model.EventTypes, eventType)
+	}
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	// MaxActivePersonalAccessTokens caps the unrevoked, unexpired tokens a
+	// single user may hold.
+	MaxActivePersonalAccessTokens = 50
+	// personalAccessTokenTouchInterval is how stale last_used_at may get
+	// before a request updates it.
+	personalAccessTokenTouchInterval = time.Minute
+)
+
+// PersonalAccessTokenService lets users mint long-lived tokens restricted to
+// a subset of their own permissions, and authenticates requests made with
+// them.
+type PersonalAccessTokenService interface {
+	// List returns all of the user's tokens, newest first, including
+	// revoked and expired ones.
+	List(ctx context.Context, userID int64) ([]dto.PersonalAccessTokenResponseDTO, error)
+
+	// Create mints a token for user in tenantID. The plain-text token is
+	// only ever returned here.
+	Create(ctx context.Context, user *model.User, tenantID int64, req dto.PersonalAccessTokenCreateRequestDTO) (*dto.PersonalAccessTokenCreateResponseDTO, error)
+
+	// Revoke revokes one of the user's tokens. Revoking a revoked token is a
+	// no-op.
+	Revoke(ctx context.Context, tokenUUID uuid.UUID, userID int64) error
+
+	// ValidatePersonalAccessToken returns the active token with the given
+	// plain-text value, with its owner's roles and its tenant loaded. It
+	// returns an *apperror.UnauthorizedError when the token is unknown,
+	// revoked or expired, or its owner is no longer active.
+	ValidatePersonalAccessToken(ctx context.Context, token string) (*model.PersonalAccessToken, error)
+}
+
+type personalAccessTokenService struct {
+	patRepo repository.PersonalAccessTokenRepository
+}
+
+// NewPersonalAccessTokenService creates a new PersonalAccessTokenService.
+func NewPersonalAccessTokenService(
+	patRepo repository.PersonalAccessTokenRepository,
+) PersonalAccessTokenService {
+	return &personalAccessTokenService{
+		patRepo: patRepo,
+	}
+}
+
+// generatePersonalAccessToken returns a new plain-text token with its hash
+// and display prefix.
+func generatePersonalAccessToken() (string, string, string) {
+	bytes := make([]byte, 32)
+	_, _ = rand.Read(bytes)
+
+	token := model.PersonalAccessTokenPrefix + hex.EncodeToString(bytes)
+	return token, hashPersonalAccessToken(token), token[:12]
+}
+
+// hashPersonalAccessToken returns the hex SHA-256 of a plain-text token, as
+// stored in TokenHash.
+func hashPersonalAccessToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// List implements PersonalAccessTokenService.
+func (s *personalAccessTokenService) List(ctx context.Context, userID int64) ([]dto.PersonalAccessTokenResponseDTO, error) {
+	_, span := otel.Tracer("service").Start(ctx, "personal_access_token.list")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("user.id", userID))
+
+	tokens, err := s.patRepo.FindByUserID(userID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "personal access tokens lookup failed")
+		return nil, apperror.NewInternal("failed to retrieve personal access tokens", err)
+	}
+
+	result := make([]dto.PersonalAccessTokenResponseDTO, len(tokens))
+	for i := range tokens {
+		result[i] = toPersonalAccessTokenResponseDTO(&tokens[i])
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// Create implements PersonalAccessTokenService.
+func (s *personalAccessTokenService) Create(ctx context.Context, user *model.User, tenantID int64, req dto.PersonalAccessTokenCreateRequestDTO) (*dto.PersonalAccessTokenCreateResponseDTO, error) {
+	_, span := otel.Tracer("service").Start(ctx, "personal_access_token.create")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int64("user.id", user.UserID),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	now := time.Now()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		span.SetStatus(codes.Error, "expiry in the past")
+		return nil, apperror.NewValidation("expires_at must be in the future")
+	}
+
+	scopes, err := restrictToUserPermissions(user, req.Scopes)
+	if err != nil {
+		span.SetStatus(codes.Error, "scope not held by user")
+		return nil, err
+	}
+
+	active, err := s.patRepo.CountActiveByUserID(user.UserID, now)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "count personal access tokens failed")
+		return nil, apperror.NewInternal("failed to create personal access token", err)
+	}
+	if active >= MaxActivePersonalAccessTokens {
+		span.SetStatus(codes.Error, "personal access token limit reached")
+		return nil, apperror.NewConflict(fmt.Sprintf("a user may hold at most %d active personal access tokens", MaxActivePersonalAccessTokens))
+	}
+
+	plainToken, tokenHash, tokenPrefix := generatePersonalAccessToken()
+	created, err := s.patRepo.Create(&model.PersonalAccessToken{
+		TenantID:    tenantID,
+		UserID:      user.UserID,
+		Name:        strings.TrimSpace(req.Name),
+		TokenHash:   tokenHash,
+		TokenPrefix: tokenPrefix,
+		Scopes:      scopes,
+		ExpiresAt:   req.ExpiresAt,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "create personal access token failed")
+		return nil, apperror.NewInternal("failed to create personal access token", err)
+	}
+	span.SetAttributes(attribute.String("personal_access_token.uuid", created.PersonalAccessTokenUUID.String()))
+
+	span.SetStatus(codes.Ok, "")
+	return &dto.PersonalAccessTokenCreateResponseDTO{
+		PersonalAccessTokenResponseDTO: toPersonalAccessTokenResponseDTO(created),
+		Token:                          plainToken,
+	}, nil
+}
+
+// Revoke implements PersonalAccessTokenService.
+func (s *personalAccessTokenService) Revoke(ctx context.Context, tokenUUID uuid.UUID, userID int64) error {
+	_, span := otel.Tracer("service").Start(ctx, "personal_access_token.revoke")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("personal_access_token.uuid", tokenUUID.String()),
+		attribute.Int64("user.id", userID),
+	)
+
+	token, err := s.patRepo.FindByUUIDAndUserID(tokenUUID.String(), userID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "personal access token lookup failed")
+		return apperror.NewInternal("failed to revoke personal access token", err)
+	}
+	if token == nil {
+		span.SetStatus(codes.Error, "personal access token not found or not owned by user")
+		return apperror.NewNotFoundWithReason("personal access token not found")
+	}
+	if token.RevokedAt != nil {
+		span.SetStatus(codes.Ok, "")
+		return nil
+	}
+
+	if err := s.patRepo.Revoke(token.PersonalAccessTokenID, time.Now()); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "revoke personal access token failed")
+		return apperror.NewInternal("failed to revoke personal access token", err)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// ValidatePersonalAccessToken implements PersonalAccessTokenService.
+func (s *personalAccessTokenService) ValidatePersonalAccessToken(ctx context.Context, token string) (*model.PersonalAccessToken, error) {
+	_, span := otel.Tracer("service").Start(ctx, "personal_access_token.validate")
+	defer span.End()
+
+	if !strings.HasPrefix(token, model.PersonalAccessTokenPrefix) {
+		span.SetStatus(codes.Error, "not a personal access token")
+		return nil, apperror.NewUnauthorized("invalid personal access token")
+	}
+
+	pat, err := s.patRepo.FindByTokenHash(hashPersonalAccessToken(token))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find personal access token failed")
+		return nil, apperror.NewInternal("failed to find personal access token", err)
+	}
+	if pat == nil {
+		span.SetStatus(codes.Error, "personal access token not found")
+		return nil, apperror.NewUnauthorized("invalid personal access token")
+	}
+	span.SetAttributes(attribute.String("personal_access_token.uuid", pat.PersonalAccessTokenUUID.String()))
+
+	now := time.Now()
+	if pat.RevokedAt != nil {
+		span.SetStatus(codes.Error, "personal access token revoked")
+		return nil, apperror.NewUnauthorized("personal access token has been revoked")
+	}
+	if !pat.IsActive(now) {
+		span.SetStatus(codes.Error, "personal access token expired")
+		return nil, apperror.NewUnauthorized("personal access token has expired")
+	}
+	if pat.User == nil || pat.User.DeletedAt != nil || pat.User.Status != model.StatusActive {
+		span.SetStatus(codes.Error, "token owner not active")
+		return nil, apperror.NewUnauthorized("personal access token owner is not active")
+	}
+
+	// A failed touch only loses last-used precision; the request proceeds.
+	if err := s.patRepo.TouchLastUsed(pat.PersonalAccessTokenID, now, personalAccessTokenTouchInterval); err != nil {
+		span.RecordError(err)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return pat, nil
+}
+
+// restrictToUserPermissions de-duplicates scopes and returns a validation
+// error naming the first one the user does not hold through their roles.
+func restrictToUserPermissions(user *model.User, scopes []string) ([]string, error) {
+	held := make(map[string]bool)
+	for _, role := range user.Roles {
+		for _, perm := range role.Permissions {
+			held[perm.Name] = true
+		}
+	}
+
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if seen[scope] {
+			continue
+		}
+		if !held[scope] {
+			return nil, apperror.NewValidation(fmt.Sprintf("scope %q is not one of your permissions", scope))
+		}
+		seen[scope] = true
+		result = append(result, scope)
+	}
+	return result, nil
+}
+
+// toPersonalAccessTokenResponseDTO describes a token without its hash.
+func toPersonalAccessTokenResponseDTO(pat *model.PersonalAccessToken) dto.PersonalAccessTokenResponseDTO {
+	scopes := []string(pat.Scopes)
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return dto.PersonalAccessTokenResponseDTO{
+		PersonalAccessTokenID: pat.PersonalAccessTokenUUID.String(),
+		Name:                  pat.Name,
+		TokenPrefix:           pat.TokenPrefix,
+		Scopes:                scopes,
+		ExpiresAt:             pat.ExpiresAt,
+		LastUsedAt:            pat.LastUsedAt,
+		RevokedAt:             pat.RevokedAt,
+		CreatedAt:             pat.CreatedAt,
+	}
+}
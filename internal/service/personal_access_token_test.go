@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPATUser(perms ...string) *model.User {
+	permissions := make([]model.Permission, len(perms))
+	for i, p := range perms {
+		permissions[i] = model.Permission{Name: p}
+	}
+	return &model.User{
+		UserID:   1,
+		UserUUID: uuid.New(),
+		Status:   model.StatusActive,
+		Roles:    []model.Role{{Name: "admin", Permissions: permissions}},
+	}
+}
+
+func TestPersonalAccessTokenService_Create(t *testing.T) {
+	ctx := context.Background()
+	user := newPATUser("user:read", "user:update")
+
+	t.Run("mints a hashed token limited to held permissions", func(t *testing.T) {
+		var saved *model.PersonalAccessToken
+		svc := NewPersonalAccessTokenService(&mockPersonalAccessTokenRepo{
+			createFn: func(pat *model.PersonalAccessToken) (*model.PersonalAccessToken, error) {
+				pat.PersonalAccessTokenUUID = uuid.New()
+				saved = pat
+				return pat, nil
+			},
+		})
+
+		res, err := svc.Create(ctx, user, 10, dto.PersonalAccessTokenCreateRequestDTO{
+			Name:   " ci ",
+			Scopes: []string{"user:read", "user:read"},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, saved)
+
+		assert.True(t, strings.HasPrefix(res.Token, model.PersonalAccessTokenPrefix))
+		assert.Equal(t, res.Token[:12], res.TokenPrefix)
+		assert.Equal(t, hashPersonalAccessToken(res.Token), saved.TokenHash)
+		assert.NotContains(t, saved.TokenHash, res.Token)
+		assert.Equal(t, "ci", saved.Name)
+		assert.Equal(t, int64(10), saved.TenantID)
+		assert.Equal(t, int64(1), saved.UserID)
+		assert.Equal(t, []string{"user:read"}, res.Scopes)
+	})
+
+	t.Run("rejects a scope the user does not hold", func(t *testing.T) {
+		svc := NewPersonalAccessTokenService(&mockPersonalAccessTokenRepo{
+			createFn: func(*model.PersonalAccessToken) (*model.PersonalAccessToken, error) {
+				t.Fatal("token must not be created")
+				return nil, nil
+			},
+		})
+
+		_, err := svc.Create(ctx, user, 10, dto.PersonalAccessTokenCreateRequestDTO{
+			Name:   "ci",
+			Scopes: []string{"user:read", "user:delete"},
+		})
+		var want *apperror.ValidationError
+		require.ErrorAs(t, err, &want)
+		assert.Contains(t, err.Error(), "user:delete")
+	})
+
+	t.Run("rejects an expiry in the past", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		svc := NewPersonalAccessTokenService(&mockPersonalAccessTokenRepo{})
+
+		_, err := svc.Create(ctx, user, 10, dto.PersonalAccessTokenCreateRequestDTO{
+			Name:      "ci",
+			Scopes:    []string{"user:read"},
+			ExpiresAt: &past,
+		})
+		var want *apperror.ValidationError
+		require.ErrorAs(t, err, &want)
+	})
+
+	t.Run("rejects a user at the active token limit", func(t *testing.T) {
+		svc := NewPersonalAccessTokenService(&mockPersonalAccessTokenRepo{
+			countActiveFn: func(int64, time.Time) (int64, error) {
+				return MaxActivePersonalAccessTokens, nil
+			},
+		})
+
+		_, err := svc.Create(ctx, user, 10, dto.PersonalAccessTokenCreateRequestDTO{
+			Name:   "ci",
+			Scopes: []string{"user:read"},
+		})
+		var want *apperror.ConflictError
+		require.ErrorAs(t, err, &want)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		svc := NewPersonalAccessTokenService(&mockPersonalAccessTokenRepo{
+			createFn: func(*model.PersonalAccessToken) (*model.PersonalAccessToken, error) {
+				return nil, errors.New("db down")
+			},
+		})
+
+		_, err := svc.Create(ctx, user, 10, dto.PersonalAccessTokenCreateRequestDTO{
+			Name:   "ci",
+			Scopes: []string{"user:read"},
+		})
+		var want *apperror.InternalError
+		require.ErrorAs(t, err, &want)
+	})
+}
+
+func TestPersonalAccessTokenService_List(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("maps tokens without their hash", func(t *testing.T) {
+		tokenUUID := uuid.New()
+		svc := NewPersonalAccessTokenService(&mockPersonalAccessTokenRepo{
+			findByUserIDFn: func(uid int64) ([]model.PersonalAccessToken, error) {
+				assert.Equal(t, int64(1), uid)
+				return []model.PersonalAccessToken{{
+					PersonalAccessTokenUUID: tokenUUID,
+					Name:                    "ci",
+					TokenHash:               "secret-hash",
+					TokenPrefix:             "pat_abcdefgh",
+				}}, nil
+			},
+		})
+
+		res, err := svc.List(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		assert.Equal(t, tokenUUID.String(), res[0].PersonalAccessTokenID)
+		assert.Equal(t, "pat_abcdefgh", res[0].TokenPrefix)
+		assert.Equal(t, []string{}, res[0].Scopes)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		svc := NewPersonalAccessTokenService(&mockPersonalAccessTokenRepo{
+			findByUserIDFn: func(int64) ([]model.PersonalAccessToken, error) {
+				return nil, errors.New("db down")
+			},
+		})
+
+		_, err := svc.List(ctx, 1)
+		var want *apperror.InternalError
+		require.ErrorAs(t, err, &want)
+	})
+}
+
+func TestPersonalAccessTokenService_Revoke(t *testing.T) {
+	ctx := context.Background()
+	tokenUUID := uuid.New()
+
+	t.Run("revokes an owned token", func(t *testing.T) {
+		var revokedID int64
+		svc := NewPersonalAccessTokenService(&mockPersonalAccessTokenRepo{
+			findByUUIDAndUserIDFn: func(id string, uid int64) (*model.PersonalAccessToken, error) {
+				assert.Equal(t, tokenUUID.String(), id)
+				assert.Equal(t, int64(1), uid)
+				return &model.PersonalAccessToken{PersonalAccessTokenID: 7}, nil
+			},
+			revokeFn: func(id int64, _ time.Time) error {
+				revokedID = id
+				return nil
+			},
+		})
+
+		require.NoError(t, svc.Revoke(ctx, tokenUUID, 1))
+		assert.Equal(t, int64(7), revokedID)
+	})
+
+	t.Run("already revoked is a no-op", func(t *testing.T) {
+		revokedAt := time.Now().Add(-time.Hour)
+		svc := NewPersonalAccessTokenService(&mockPersonalAccessTokenRepo{
+			findByUUIDAndUserIDFn: func(string, int64) (*model.PersonalAccessToken, error) {
+				return &model.PersonalAccessToken{PersonalAccessTokenID: 7, RevokedAt: &revokedAt}, nil
+			},
+			revokeFn: func(int64, time.Time) error {
+				t.Fatal("revoke must not be called")
+				return nil
+			},
+		})
+
+		require.NoError(t, svc.Revoke(ctx, tokenUUID, 1))
+	})
+
+	t.Run("token of another user is not found", func(t *testing.T) {
+		svc := NewPersonalAccessTokenService(&mockPersonalAccessTokenRepo{})
+
+		err := svc.Revoke(ctx, tokenUUID, 1)
+		var want *apperror.NotFoundError
+		require.ErrorAs(t, err, &want)
+	})
+}
+
+func TestPersonalAccessTokenService_ValidatePersonalAccessToken(t *testing.T) {
+	ctx := context.Background()
+	const plain = "pat_0123456789abcdef"
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	deletedUser := newPATUser()
+	deletedUser.DeletedAt = &past
+	inactiveUser := newPATUser()
+	inactiveUser.Status = model.StatusInactive
+
+	tests := []struct {
+		name    string
+		token   string
+		pat     *model.PersonalAccessToken
+		findErr error
+		wantErr any
+	}{
+		{name: "valid", token: plain, pat: &model.PersonalAccessToken{User: newPATUser(), ExpiresAt: &future}},
+		{name: "wrong prefix", token: "ak_0123", wantErr: &apperror.UnauthorizedError{}},
+		{name: "unknown", token: plain, wantErr: &apperror.UnauthorizedError{}},
+		{name: "revoked", token: plain, pat: &model.PersonalAccessToken{User: newPATUser(), RevokedAt: &past}, wantErr: &apperror.UnauthorizedError{}},
+		{name: "expired", token: plain, pat: &model.PersonalAccessToken{User: newPATUser(), ExpiresAt: &past}, wantErr: &apperror.UnauthorizedError{}},
+		{name: "deleted owner", token: plain, pat: &model.PersonalAccessToken{User: deletedUser}, wantErr: &apperror.UnauthorizedError{}},
+		{name: "inactive owner", token: plain, pat: &model.PersonalAccessToken{User: inactiveUser}, wantErr: &apperror.UnauthorizedError{}},
+		{name: "lookup error", token: plain, findErr: errors.New("db down"), wantErr: &apperror.InternalError{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			touched := false
+			svc := NewPersonalAccessTokenService(&mockPersonalAccessTokenRepo{
+				findByTokenHashFn: func(h string) (*model.PersonalAccessToken, error) {
+					assert.Equal(t, hashPersonalAccessToken(tc.token), h)
+					return tc.pat, tc.findErr
+				},
+				touchLastUsedFn: func(int64, time.Time, time.Duration) error {
+					touched = true
+					return errors.New("touch failures are ignored")
+				},
+			})
+
+			pat, err := svc.ValidatePersonalAccessToken(ctx, tc.token)
+			switch want := tc.wantErr.(type) {
+			case nil:
+				require.NoError(t, err)
+				assert.Same(t, tc.pat, pat)
+				assert.True(t, touched)
+			case *apperror.UnauthorizedError:
+				require.ErrorAs(t, err, &want)
+				assert.False(t, touched)
+			case *apperror.InternalError:
+				require.ErrorAs(t, err, &want)
+			}
+		})
+	}
+}
@@ -0,0 +1,566 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// PlanEnforcer checks the plan of a tenant before services create resources
+// or configure features for it. Tenants without a plan are on the default
+// plan; without a default plan, and in the system tenant, nothing is
+// limited.
+type PlanEnforcer interface {
+	// CheckUserLimit fails with TEN-2006 when the tenant has as many users
+	// as its plan allows.
+	CheckUserLimit(ctx context.Context, tenantID int64) error
+	// CheckClientLimit fails with TEN-2006 when the tenant has as many
+	// clients as its plan allows.
+	CheckClientLimit(ctx context.Context, tenantID int64) error
+	// CheckFeature fails with TEN-2007 when the plan of the tenant does not
+	// include the feature, one of model.PlanFeatures.
+	CheckFeature(ctx context.Context, tenantID int64, feature string) error
+}
+
+// NopPlanEnforcer is a PlanEnforcer that limits nothing.
+type NopPlanEnforcer struct{}
+
+func (NopPlanEnforcer) CheckUserLimit(context.Context, int64) error       { return nil }
+func (NopPlanEnforcer) CheckClientLimit(context.Context, int64) error     { return nil }
+func (NopPlanEnforcer) CheckFeature(context.Context, int64, string) error { return nil }
+
+// PlanServiceInput is the content of a plan being created or replaced.
+type PlanServiceInput struct {
+	Name        string
+	DisplayName string
+	Description string
+	Features    map[string]bool
+	MaxUsers    int64
+	MaxClients  int64
+	IsDefault   bool
+	Status      string
+}
+
+// PlanServiceDataResult is the service-layer representation of a plan.
+// MaxUsers and MaxClients of 0 are unlimited.
+type PlanServiceDataResult struct {
+	PlanUUID    uuid.UUID
+	Name        string
+	DisplayName string
+	Description string
+	Features    map[string]bool
+	MaxUsers    int64
+	MaxClients  int64
+	IsDefault   bool
+	Status      string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// PlanServiceTenantPlan is the plan a tenant is subscribed to, with the
+// users and clients counted against it. Plan is nil when the tenant is not
+// limited; Inherited is true when it is on the default plan.
+type PlanServiceTenantPlan struct {
+	TenantUUID uuid.UUID
+	Plan       *PlanServiceDataResult
+	Inherited  bool
+	Users      int64
+	Clients    int64
+}
+
+// PlanService manages the billing plans tenants are subscribed to and
+// enforces them.
+type PlanService interface {
+	PlanEnforcer
+	List(ctx context.Context) ([]PlanServiceDataResult, error)
+	GetByUUID(ctx context.Context, planUUID uuid.UUID) (*PlanServiceDataResult, error)
+	Create(ctx context.Context, input PlanServiceInput) (*PlanServiceDataResult, error)
+	// Update replaces the plan. Tenants on it are held to the new limits
+	// from their next change; existing users and clients are kept.
+	Update(ctx context.Context, planUUID uuid.UUID, input PlanServiceInput) (*PlanServiceDataResult, error)
+	// Delete removes a plan no tenant is subscribed to.
+	Delete(ctx context.Context, planUUID uuid.UUID) (*PlanServiceDataResult, error)
+	GetTenantPlan(ctx context.Context, tenantUUID uuid.UUID) (*PlanServiceTenantPlan, error)
+	// SetTenantPlan subscribes the tenant to an active plan, or to the
+	// default plan when planUUID is nil.
+	SetTenantPlan(ctx context.Context, tenantUUID uuid.UUID, planUUID *uuid.UUID) (*PlanServiceTenantPlan, error)
+}
+
+type planService struct {
+	db               *gorm.DB
+	planRepo         repository.PlanRepository
+	tenantRepo       repository.TenantRepository
+	userIdentityRepo repository.UserIdentityRepository
+	clientRepo       repository.ClientRepository
+}
+
+// NewPlanService creates a new PlanService.
+func NewPlanService(
+	db *gorm.DB,
+	planRepo repository.PlanRepository,
+	tenantRepo repository.TenantRepository,
+	userIdentityRepo repository.UserIdentityRepository,
+	clientRepo repository.ClientRepository,
+) PlanService {
+	return &planService{
+		db:               db,
+		planRepo:         planRepo,
+		tenantRepo:       tenantRepo,
+		userIdentityRepo: userIdentityRepo,
+		clientRepo:       clientRepo,
+	}
+}
+
+func (s *planService) CheckUserLimit(ctx context.Context, tenantID int64) error {
+	_, span := otel.Tracer("service").Start(ctx, "plan.checkUserLimit")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID))
+
+	plan, _, err := s.tenantPlan(tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "load plan failed")
+		return err
+	}
+	if plan == nil || plan.MaxUsers == 0 {
+		span.SetStatus(codes.Ok, "")
+		return nil
+	}
+
+	users, err := s.userIdentityRepo.CountUsersByTenantID(tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "count users failed")
+		return apperror.NewInternal("failed to count tenant users", err)
+	}
+	if users >= plan.MaxUsers {
+		span.SetStatus(codes.Error, "user limit reached")
+		return apperror.WithCode(apperror.CodePlanLimitReached,
+			apperror.NewForbidden(fmt.Sprintf("the %s plan allows at most %d users", plan.Name, plan.MaxUsers)))
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+func (s *planService) CheckClientLimit(ctx context.Context, tenantID int64) error {
+	_, span := otel.Tracer("service").Start(ctx, "plan.checkClientLimit")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID))
+
+	plan, _, err := s.tenantPlan(tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "load plan failed")
+		return err
+	}
+	if plan == nil || plan.MaxClients == 0 {
+		span.SetStatus(codes.Ok, "")
+		return nil
+	}
+
+	clients, err := s.clientRepo.CountByTenantID(tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "count clients failed")
+		return apperror.NewInternal("failed to count tenant clients", err)
+	}
+	if clients >= plan.MaxClients {
+		span.SetStatus(codes.Error, "client limit reached")
+		return apperror.WithCode(apperror.CodePlanLimitReached,
+			apperror.NewForbidden(fmt.Sprintf("the %s plan allows at most %d clients", plan.Name, plan.MaxClients)))
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+func (s *planService) CheckFeature(ctx context.Context, tenantID int64, feature string) error {
+	_, span := otel.Tracer("service").Start(ctx, "plan.checkFeature")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int64("tenant.id", tenantID),
+		attribute.String("plan.feature", feature),
+	)
+
+	plan, _, err := s.tenantPlan(tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "load plan failed")
+		return err
+	}
+	if plan != nil && !plan.HasFeature(feature) {
+		span.SetStatus(codes.Error, "feature disabled")
+		return apperror.WithCode(apperror.CodePlanFeatureDisabled,
+			apperror.NewForbidden(fmt.Sprintf("the %s plan does not include %s", plan.Name, feature)))
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+func (s *planService) List(ctx context.Context) ([]PlanServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "plan.list")
+	defer span.End()
+
+	plans, err := s.planRepo.FindAllOrdered()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list plans failed")
+		return nil, apperror.NewInternal("failed to list plans", err)
+	}
+
+	results := make([]PlanServiceDataResult, len(plans))
+	for i := range plans {
+		results[i] = toPlanServiceDataResult(&plans[i])
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return results, nil
+}
+
+func (s *planService) GetByUUID(ctx context.Context, planUUID uuid.UUID) (*PlanServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "plan.get")
+	defer span.End()
+	span.SetAttributes(attribute.String("plan.uuid", planUUID.String()))
+
+	plan, err := s.findPlan(planUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find plan failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	result := toPlanServiceDataResult(plan)
+	return &result, nil
+}
+
+func (s *planService) Create(ctx context.Context, input PlanServiceInput) (*PlanServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "plan.create")
+	defer span.End()
+	span.SetAttributes(attribute.String("plan.name", input.Name))
+
+	features, err := json.Marshal(input.Features)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to encode plan features", err)
+	}
+
+	var created *model.Plan
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		txPlanRepo := s.planRepo.WithTx(tx)
+
+		existing, err := txPlanRepo.FindByName(input.Name)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return apperror.NewConflict("plan " + input.Name + " already exists")
+		}
+
+		if input.IsDefault {
+			if err := txPlanRepo.UnsetDefault(); err != nil {
+				return err
+			}
+		}
+
+		created, err = txPlanRepo.Create(&model.Plan{
+			Name:        input.Name,
+			DisplayName: input.DisplayName,
+			Description: input.Description,
+			Features:    datatypes.JSON(features),
+			MaxUsers:    input.MaxUsers,
+			MaxClients:  input.MaxClients,
+			IsDefault:   input.IsDefault,
+			Status:      input.Status,
+		})
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "create plan failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	result := toPlanServiceDataResult(created)
+	return &result, nil
+}
+
+func (s *planService) Update(ctx context.Context, planUUID uuid.UUID, input PlanServiceInput) (*PlanServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "plan.update")
+	defer span.End()
+	span.SetAttributes(attribute.String("plan.uuid", planUUID.String()))
+
+	features, err := json.Marshal(input.Features)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to encode plan features", err)
+	}
+
+	var updated *model.Plan
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		txPlanRepo := s.planRepo.WithTx(tx)
+
+		plan, err := txPlanRepo.FindByUUID(planUUID)
+		if err != nil {
+			return err
+		}
+		if plan == nil {
+			return apperror.NewNotFoundWithReason("plan not found")
+		}
+
+		if input.Name != plan.Name {
+			existing, err := txPlanRepo.FindByName(input.Name)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				return apperror.NewConflict("plan " + input.Name + " already exists")
+			}
+		}
+
+		if input.IsDefault && !plan.IsDefault {
+			if err := txPlanRepo.UnsetDefault(); err != nil {
+				return err
+			}
+		}
+
+		// A map, so that zero limits and a cleared default are written too.
+		updated, err = txPlanRepo.UpdateByUUID(planUUID, map[string]any{
+			"name":         input.Name,
+			"display_name": input.DisplayName,
+			"description":  input.Description,
+			"features":     datatypes.JSON(features),
+			"max_users":    input.MaxUsers,
+			"max_clients":  input.MaxClients,
+			"is_default":   input.IsDefault,
+			"status":       input.Status,
+		})
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "update plan failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	result := toPlanServiceDataResult(updated)
+	return &result, nil
+}
+
+func (s *planService) Delete(ctx context.Context, planUUID uuid.UUID) (*PlanServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "plan.delete")
+	defer span.End()
+	span.SetAttributes(attribute.String("plan.uuid", planUUID.String()))
+
+	plan, err := s.findPlan(planUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find plan failed")
+		return nil, err
+	}
+
+	tenants, err := s.planRepo.CountTenants(plan.PlanID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "count tenants failed")
+		return nil, apperror.NewInternal("failed to count plan tenants", err)
+	}
+	if tenants > 0 {
+		span.SetStatus(codes.Error, "plan in use")
+		return nil, apperror.NewConflict(fmt.Sprintf("plan is in use by %d tenants", tenants))
+	}
+
+	if err := s.planRepo.DeleteByUUID(planUUID); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete plan failed")
+		return nil, apperror.NewInternal("failed to delete plan", err)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	result := toPlanServiceDataResult(plan)
+	return &result, nil
+}
+
+func (s *planService) GetTenantPlan(ctx context.Context, tenantUUID uuid.UUID) (*PlanServiceTenantPlan, error) {
+	_, span := otel.Tracer("service").Start(ctx, "plan.getTenantPlan")
+	defer span.End()
+	span.SetAttributes(attribute.String("tenant.uuid", tenantUUID.String()))
+
+	tenant, err := s.tenantRepo.FindByUUID(tenantUUID, "Plan")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find tenant failed")
+		return nil, apperror.NewInternal("failed to find tenant", err)
+	}
+	if tenant == nil {
+		span.SetStatus(codes.Error, "tenant not found")
+		return nil, apperror.NewNotFound("tenant")
+	}
+
+	result, err := s.tenantPlanResult(tenant)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "load tenant plan failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+func (s *planService) SetTenantPlan(ctx context.Context, tenantUUID uuid.UUID, planUUID *uuid.UUID) (*PlanServiceTenantPlan, error) {
+	_, span := otel.Tracer("service").Start(ctx, "plan.setTenantPlan")
+	defer span.End()
+	span.SetAttributes(attribute.String("tenant.uuid", tenantUUID.String()))
+
+	tenant, err := s.tenantRepo.FindByUUID(tenantUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find tenant failed")
+		return nil, apperror.NewInternal("failed to find tenant", err)
+	}
+	if tenant == nil {
+		span.SetStatus(codes.Error, "tenant not found")
+		return nil, apperror.NewNotFound("tenant")
+	}
+	if tenant.IsSystem {
+		span.SetStatus(codes.Error, "system tenant")
+		return nil, apperror.WithCode(apperror.CodeSystemTenantProtected, apperror.NewValidation("cannot change the plan of the system tenant"))
+	}
+
+	var planID *int64
+	tenant.Plan = nil
+	if planUUID != nil {
+		plan, err := s.findPlan(*planUUID)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "find plan failed")
+			return nil, err
+		}
+		if plan.Status != model.StatusActive {
+			span.SetStatus(codes.Error, "plan inactive")
+			return nil, apperror.NewValidation("plan is not active")
+		}
+		planID = &plan.PlanID
+		tenant.Plan = plan
+	}
+
+	if err := s.tenantRepo.SetPlanByUUID(tenantUUID, planID); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "set plan failed")
+		return nil, apperror.NewInternal("failed to set tenant plan", err)
+	}
+	tenant.PlanID = planID
+
+	result, err := s.tenantPlanResult(tenant)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "load tenant plan failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// findPlan returns the plan, failing with a not found error when there is
+// none.
+func (s *planService) findPlan(planUUID uuid.UUID) (*model.Plan, error) {
+	plan, err := s.planRepo.FindByUUID(planUUID)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to find plan", err)
+	}
+	if plan == nil {
+		return nil, apperror.NewNotFoundWithReason("plan not found")
+	}
+	return plan, nil
+}
+
+// tenantPlan returns the plan the tenant is held to, or nil when it is not
+// limited, and whether that is the default plan.
+func (s *planService) tenantPlan(tenantID int64) (*model.Plan, bool, error) {
+	tenant, err := s.tenantRepo.FindByID(tenantID, "Plan")
+	if err != nil {
+		return nil, false, apperror.NewInternal("failed to find tenant", err)
+	}
+	if tenant == nil {
+		return nil, false, apperror.NewNotFound("tenant")
+	}
+	return s.resolvePlan(tenant)
+}
+
+// resolvePlan returns the plan of a tenant loaded with its Plan.
+func (s *planService) resolvePlan(tenant *model.Tenant) (*model.Plan, bool, error) {
+	if tenant.IsSystem {
+		return nil, false, nil
+	}
+	if tenant.Plan != nil {
+		return tenant.Plan, false, nil
+	}
+	plan, err := s.planRepo.FindDefault()
+	if err != nil {
+		return nil, false, apperror.NewInternal("failed to find default plan", err)
+	}
+	return plan, plan != nil, nil
+}
+
+// tenantPlanResult describes the plan of a tenant loaded with its Plan and
+// what the tenant uses of it.
+func (s *planService) tenantPlanResult(tenant *model.Tenant) (*PlanServiceTenantPlan, error) {
+	plan, inherited, err := s.resolvePlan(tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.userIdentityRepo.CountUsersByTenantID(tenant.TenantID)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to count tenant users", err)
+	}
+	clients, err := s.clientRepo.CountByTenantID(tenant.TenantID)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to count tenant clients", err)
+	}
+
+	result := &PlanServiceTenantPlan{
+		TenantUUID: tenant.TenantUUID,
+		Inherited:  inherited,
+		Users:      users,
+		Clients:    clients,
+	}
+	if plan != nil {
+		data := toPlanServiceDataResult(plan)
+		result.Plan = &data
+	}
+	return result, nil
+}
+
+func toPlanServiceDataResult(plan *model.Plan) PlanServiceDataResult {
+	features := map[string]bool{}
+	_ = json.Unmarshal(plan.Features, &features)
+	return PlanServiceDataResult{
+		PlanUUID:    plan.PlanUUID,
+		Name:        plan.Name,
+		DisplayName: plan.DisplayName,
+		Description: plan.Description,
+		Features:    features,
+		MaxUsers:    plan.MaxUsers,
+		MaxClients:  plan.MaxClients,
+		IsDefault:   plan.IsDefault,
+		Status:      plan.Status,
+		CreatedAt:   plan.CreatedAt,
+		UpdatedAt:   plan.UpdatedAt,
+	}
+}
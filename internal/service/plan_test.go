@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+// planTenantRepo returns a tenant repository holding one tenant with plan.
+func planTenantRepo(tenant *model.Tenant) *mockTenantRepo {
+	find := func(_ any, _ ...string) (*model.Tenant, error) { return tenant, nil }
+	return &mockTenantRepo{findByIDFn: find, findByUUIDFn: find}
+}
+
+func TestPlanService_CheckUserLimit(t *testing.T) {
+	plan := &model.Plan{PlanID: 1, Name: "free", MaxUsers: 3, Features: datatypes.JSON(`{}`)}
+	counting := func(n int64) *mockUserIdentityRepo {
+		return &mockUserIdentityRepo{countUsersByTenantIDFn: func(int64) (int64, error) { return n, nil }}
+	}
+
+	t.Run("below the limit", func(t *testing.T) {
+		svc := NewPlanService(nil, &mockPlanRepo{}, planTenantRepo(&model.Tenant{TenantID: 7, Plan: plan}), counting(2), &mockClientRepo{})
+		assert.NoError(t, svc.CheckUserLimit(context.Background(), 7))
+	})
+
+	t.Run("at the limit", func(t *testing.T) {
+		svc := NewPlanService(nil, &mockPlanRepo{}, planTenantRepo(&model.Tenant{TenantID: 7, Plan: plan}), counting(3), &mockClientRepo{})
+		err := svc.CheckUserLimit(context.Background(), 7)
+		var forbidden *apperror.ForbiddenError
+		require.ErrorAs(t, err, &forbidden)
+		code, _ := apperror.CodeOf(err)
+		assert.Equal(t, apperror.CodePlanLimitReached, code)
+	})
+
+	t.Run("default plan applies without a plan", func(t *testing.T) {
+		plans := &mockPlanRepo{findDefaultFn: func() (*model.Plan, error) { return plan, nil }}
+		svc := NewPlanService(nil, plans, planTenantRepo(&model.Tenant{TenantID: 7}), counting(3), &mockClientRepo{})
+		assert.Error(t, svc.CheckUserLimit(context.Background(), 7))
+	})
+
+	t.Run("unlimited without any plan", func(t *testing.T) {
+		svc := NewPlanService(nil, &mockPlanRepo{}, planTenantRepo(&model.Tenant{TenantID: 7}), counting(1000), &mockClientRepo{})
+		assert.NoError(t, svc.CheckUserLimit(context.Background(), 7))
+	})
+
+	t.Run("system tenant is never limited", func(t *testing.T) {
+		svc := NewPlanService(nil, &mockPlanRepo{}, planTenantRepo(&model.Tenant{TenantID: 1, IsSystem: true, Plan: plan}), counting(1000), &mockClientRepo{})
+		assert.NoError(t, svc.CheckUserLimit(context.Background(), 1))
+	})
+
+	t.Run("count error", func(t *testing.T) {
+		users := &mockUserIdentityRepo{countUsersByTenantIDFn: func(int64) (int64, error) { return 0, errors.New("db down") }}
+		svc := NewPlanService(nil, &mockPlanRepo{}, planTenantRepo(&model.Tenant{TenantID: 7, Plan: plan}), users, &mockClientRepo{})
+		var internal *apperror.InternalError
+		assert.ErrorAs(t, svc.CheckUserLimit(context.Background(), 7), &internal)
+	})
+}
+
+func TestPlanService_CheckClientLimit(t *testing.T) {
+	plan := &model.Plan{PlanID: 1, Name: "free", MaxClients: 2, Features: datatypes.JSON(`{}`)}
+	clients := &mockClientRepo{countByTenantIDFn: func(int64) (int64, error) { return 2, nil }}
+
+	svc := NewPlanService(nil, &mockPlanRepo{}, planTenantRepo(&model.Tenant{TenantID: 7, Plan: plan}), &mockUserIdentityRepo{}, clients)
+	err := svc.CheckClientLimit(context.Background(), 7)
+	code, _ := apperror.CodeOf(err)
+	assert.Equal(t, apperror.CodePlanLimitReached, code)
+
+	plan.MaxClients = 0
+	assert.NoError(t, svc.CheckClientLimit(context.Background(), 7))
+}
+
+func TestPlanService_CheckFeature(t *testing.T) {
+	free := &model.Plan{Name: "free", Features: datatypes.JSON(`{"mfa":false}`)}
+	pro := &model.Plan{Name: "pro", Features: datatypes.JSON(`{"mfa":true}`)}
+
+	svc := NewPlanService(nil, &mockPlanRepo{}, planTenantRepo(&model.Tenant{TenantID: 7, Plan: free}), &mockUserIdentityRepo{}, &mockClientRepo{})
+	err := svc.CheckFeature(context.Background(), 7, model.PlanFeatureMFA)
+	code, _ := apperror.CodeOf(err)
+	assert.Equal(t, apperror.CodePlanFeatureDisabled, code)
+
+	svc = NewPlanService(nil, &mockPlanRepo{}, planTenantRepo(&model.Tenant{TenantID: 7, Plan: pro}), &mockUserIdentityRepo{}, &mockClientRepo{})
+	assert.NoError(t, svc.CheckFeature(context.Background(), 7, model.PlanFeatureMFA))
+}
+
+func TestPlanService_Create(t *testing.T) {
+	input := PlanServiceInput{Name: "pro", Features: map[string]bool{"mfa": true}, MaxUsers: 100, IsDefault: true, Status: model.StatusActive}
+
+	t.Run("unsets the previous default", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		var unset bool
+		plans := &mockPlanRepo{unsetDefaultFn: func() error { unset = true; return nil }}
+
+		result, err := NewPlanService(db, plans, &mockTenantRepo{}, &mockUserIdentityRepo{}, &mockClientRepo{}).Create(context.Background(), input)
+		require.NoError(t, err)
+		assert.True(t, unset)
+		assert.Equal(t, "pro", result.Name)
+		assert.Equal(t, map[string]bool{"mfa": true}, result.Features)
+		assert.Equal(t, int64(100), result.MaxUsers)
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		plans := &mockPlanRepo{findByNameFn: func(string) (*model.Plan, error) { return &model.Plan{}, nil }}
+
+		_, err := NewPlanService(db, plans, &mockTenantRepo{}, &mockUserIdentityRepo{}, &mockClientRepo{}).Create(context.Background(), input)
+		var conflict *apperror.ConflictError
+		assert.ErrorAs(t, err, &conflict)
+	})
+}
+
+func TestPlanService_Delete(t *testing.T) {
+	planUUID := uuid.New()
+	plans := &mockPlanRepo{
+		findByUUIDFn:   func(any, ...string) (*model.Plan, error) { return &model.Plan{PlanID: 1, PlanUUID: planUUID}, nil },
+		countTenantsFn: func(int64) (int64, error) { return 2, nil },
+	}
+	svc := NewPlanService(nil, plans, &mockTenantRepo{}, &mockUserIdentityRepo{}, &mockClientRepo{})
+
+	_, err := svc.Delete(context.Background(), planUUID)
+	var conflict *apperror.ConflictError
+	assert.ErrorAs(t, err, &conflict)
+
+	plans.countTenantsFn = nil
+	var deleted bool
+	plans.deleteByUUIDFn = func(any) error { deleted = true; return nil }
+	result, err := svc.Delete(context.Background(), planUUID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	assert.Equal(t, planUUID, result.PlanUUID)
+}
+
+func TestPlanService_SetTenantPlan(t *testing.T) {
+	tenantUUID := uuid.New()
+	planUUID := uuid.New()
+	plan := &model.Plan{PlanID: 4, PlanUUID: planUUID, Name: "pro", Status: model.StatusActive, Features: datatypes.JSON(`{}`)}
+	plans := &mockPlanRepo{findByUUIDFn: func(any, ...string) (*model.Plan, error) { return plan, nil }}
+
+	t.Run("subscribes the tenant", func(t *testing.T) {
+		var setID *int64
+		tenants := planTenantRepo(&model.Tenant{TenantID: 7, TenantUUID: tenantUUID})
+		tenants.setPlanByUUIDFn = func(_ uuid.UUID, planID *int64) error { setID = planID; return nil }
+
+		result, err := NewPlanService(nil, plans, tenants, &mockUserIdentityRepo{}, &mockClientRepo{}).SetTenantPlan(context.Background(), tenantUUID, &planUUID)
+		require.NoError(t, err)
+		require.NotNil(t, setID)
+		assert.Equal(t, int64(4), *setID)
+		assert.Equal(t, "pro", result.Plan.Name)
+		assert.False(t, result.Inherited)
+	})
+
+	t.Run("nil falls back to the default plan", func(t *testing.T) {
+		var setID = new(int64)
+		tenants := planTenantRepo(&model.Tenant{TenantID: 7, TenantUUID: tenantUUID, Plan: plan})
+		tenants.setPlanByUUIDFn = func(_ uuid.UUID, planID *int64) error { setID = planID; return nil }
+
+		result, err := NewPlanService(nil, &mockPlanRepo{}, tenants, &mockUserIdentityRepo{}, &mockClientRepo{}).SetTenantPlan(context.Background(), tenantUUID, nil)
+		require.NoError(t, err)
+		assert.Nil(t, setID)
+		assert.Nil(t, result.Plan)
+	})
+
+	t.Run("inactive plan", func(t *testing.T) {
+		inactive := &mockPlanRepo{findByUUIDFn: func(any, ...string) (*model.Plan, error) {
+			return &model.Plan{PlanID: 4, Status: model.StatusInactive}, nil
+		}}
+		_, err := NewPlanService(nil, inactive, planTenantRepo(&model.Tenant{TenantID: 7}), &mockUserIdentityRepo{}, &mockClientRepo{}).SetTenantPlan(context.Background(), tenantUUID, &planUUID)
+		var validation *apperror.ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+
+	t.Run("system tenant", func(t *testing.T) {
+		_, err := NewPlanService(nil, plans, planTenantRepo(&model.Tenant{TenantID: 1, IsSystem: true}), &mockUserIdentityRepo{}, &mockClientRepo{}).SetTenantPlan(context.Background(), tenantUUID, &planUUID)
+		code, _ := apperror.CodeOf(err)
+		assert.Equal(t, apperror.CodeSystemTenantProtected, code)
+	})
+}
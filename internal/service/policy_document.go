@@ -0,0 +1,558 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// PolicyDocumentServiceDataResult is the service-layer representation of a
+// policy document version.
+type PolicyDocumentServiceDataResult struct {
+	PolicyDocumentUUID uuid.UUID
+	Type               string
+	Version            string
+	Title              string
+	URL                string
+	RequireAcceptance  bool
+	PublishedAt        *time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// PolicyDocumentServiceListResult holds a paginated list of policy documents.
+type PolicyDocumentServiceListResult struct {
+	Data       []PolicyDocumentServiceDataResult
+	Total      int64
+	Page       int
+	Limit      int
+	TotalPages int
+}
+
+// PolicyAcceptanceServiceDataResult records who accepted which document
+// version, and when.
+type PolicyAcceptanceServiceDataResult struct {
+	Document   PolicyDocumentServiceDataResult
+	UserUUID   uuid.UUID
+	Username   string
+	Email      string
+	IPAddress  *string
+	UserAgent  *string
+	AcceptedAt time.Time
+}
+
+// PolicyAcceptanceServiceListResult holds a paginated list of acceptances.
+type PolicyAcceptanceServiceListResult struct {
+	Data       []PolicyAcceptanceServiceDataResult
+	Total      int64
+	Page       int
+	Limit      int
+	TotalPages int
+}
+
+// PolicyDocumentInput groups the writable fields of a draft document. A nil
+// RequireAcceptance keeps the default (on create) or the current value (on
+// update).
+type PolicyDocumentInput struct {
+	Type              string
+	Version           string
+	Title             string
+	URL               string
+	RequireAcceptance *bool
+}
+
+// PolicyDocumentService manages the terms of service and privacy policy
+// versions of a tenant and reports who accepted them. Acceptance itself is
+// given at login and registration.
+type PolicyDocumentService interface {
+	GetAll(ctx context.Context, tenantID int64, types []string, published *bool, page, limit int, sortBy, sortOrder string) (*PolicyDocumentServiceListResult, error)
+	GetByUUID(ctx context.Context, tenantID int64, policyDocumentUUID uuid.UUID) (*PolicyDocumentServiceDataResult, error)
+	// GetCurrent returns the current version of each document type of the
+	// tenant the client belongs to, for sign-in and sign-up pages.
+	GetCurrent(ctx context.Context, clientID, providerID string) ([]PolicyDocumentServiceDataResult, error)
+	Create(ctx context.Context, tenantID int64, input PolicyDocumentInput) (*PolicyDocumentServiceDataResult, error)
+	// Update replaces a draft. Published versions cannot change.
+	Update(ctx context.Context, tenantID int64, policyDocumentUUID uuid.UUID, input PolicyDocumentInput) (*PolicyDocumentServiceDataResult, error)
+	// Delete removes a draft. Published versions are kept with their
+	// acceptances.
+	Delete(ctx context.Context, tenantID int64, policyDocumentUUID uuid.UUID) (*PolicyDocumentServiceDataResult, error)
+	// Publish makes a draft the current version of its type.
+	Publish(ctx context.Context, tenantID int64, policyDocumentUUID uuid.UUID) (*PolicyDocumentServiceDataResult, error)
+	GetAcceptances(ctx context.Context, tenantID int64, policyDocumentUUID uuid.UUID, page, limit int) (*PolicyAcceptanceServiceListResult, error)
+	GetUserAcceptances(ctx context.Context, tenantID int64, userUUID uuid.UUID, page, limit int) (*PolicyAcceptanceServiceListResult, error)
+}
+
+type policyDocumentService struct {
+	policyDocumentRepo repository.PolicyDocumentRepository
+	clientRepo         repository.ClientRepository
+	userRepo           repository.UserRepository
+}
+
+// NewPolicyDocumentService creates a new PolicyDocumentService.
+func NewPolicyDocumentService(
+	policyDocumentRepo repository.PolicyDocumentRepository,
+	clientRepo repository.ClientRepository,
+	userRepo repository.UserRepository,
+) PolicyDocumentService {
+	return &policyDocumentService{
+		policyDocumentRepo: policyDocumentRepo,
+		clientRepo:         clientRepo,
+		userRepo:           userRepo,
+	}
+}
+
+func toPolicyDocumentServiceDataResult(d *model.PolicyDocument) PolicyDocumentServiceDataResult {
+	return PolicyDocumentServiceDataResult{
+		PolicyDocumentUUID: d.PolicyDocumentUUID,
+		Type:               d.Type,
+		Version:            d.Version,
+		Title:              d.Title,
+		URL:                d.URL,
+		RequireAcceptance:  d.RequireAcceptance,
+		PublishedAt:        d.PublishedAt,
+		CreatedAt:          d.CreatedAt,
+		UpdatedAt:          d.UpdatedAt,
+	}
+}
+
+func toPolicyAcceptanceServiceListResult(result *repository.PaginationResult[model.PolicyAcceptance]) *PolicyAcceptanceServiceListResult {
+	data := make([]PolicyAcceptanceServiceDataResult, len(result.Data))
+	for i, a := range result.Data {
+		item := PolicyAcceptanceServiceDataResult{
+			IPAddress:  a.IPAddress,
+			UserAgent:  a.UserAgent,
+			AcceptedAt: a.AcceptedAt,
+		}
+		if a.PolicyDocument != nil {
+			item.Document = toPolicyDocumentServiceDataResult(a.PolicyDocument)
+		}
+		if a.User != nil {
+			item.UserUUID = a.User.UserUUID
+			item.Username = a.User.Username
+			item.Email = a.User.Email
+		}
+		data[i] = item
+	}
+	return &PolicyAcceptanceServiceListResult{
+		Data:       data,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}
+}
+
+// GetAll retrieves a paginated list of a tenant's policy documents.
+func (s *policyDocumentService) GetAll(ctx context.Context, tenantID int64, types []string, published *bool, page, limit int, sortBy, sortOrder string) (*PolicyDocumentServiceListResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "policyDocument.list")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID))
+
+	result, err := s.policyDocumentRepo.FindPaginated(repository.PolicyDocumentRepositoryGetFilter{
+		TenantID:  tenantID,
+		Type:      types,
+		Published: published,
+		Page:      page,
+		Limit:     limit,
+		SortBy:    sortBy,
+		SortOrder: sortOrder,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list policy documents failed")
+		return nil, err
+	}
+
+	data := make([]PolicyDocumentServiceDataResult, len(result.Data))
+	for i, d := range result.Data {
+		data[i] = toPolicyDocumentServiceDataResult(&d)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return &PolicyDocumentServiceListResult{
+		Data:       data,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}, nil
+}
+
+// GetByUUID retrieves a single policy document, verifying tenant ownership.
+func (s *policyDocumentService) GetByUUID(ctx context.Context, tenantID int64, policyDocumentUUID uuid.UUID) (*PolicyDocumentServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "policyDocument.get")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("policy_document.uuid", policyDocumentUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	doc, err := s.find(tenantID, policyDocumentUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get policy document failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	result := toPolicyDocumentServiceDataResult(doc)
+	return &result, nil
+}
+
+// GetCurrent returns the current versions for the client's tenant.
+func (s *policyDocumentService) GetCurrent(ctx context.Context, clientID, providerID string) ([]PolicyDocumentServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "policyDocument.current")
+	defer span.End()
+	span.SetAttributes(attribute.String("client.id", clientID), attribute.String("provider.id", providerID))
+
+	client, err := s.clientRepo.FindByClientIDAndIdentityProvider(clientID, providerID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get current policy documents failed")
+		return nil, err
+	}
+	if client == nil || client.Status != model.StatusActive {
+		span.SetStatus(codes.Error, "client not found")
+		return nil, apperror.NewNotFoundWithReason("auth client not found")
+	}
+
+	docs, err := s.policyDocumentRepo.FindCurrent(client.TenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get current policy documents failed")
+		return nil, err
+	}
+
+	data := make([]PolicyDocumentServiceDataResult, len(docs))
+	for i, d := range docs {
+		data[i] = toPolicyDocumentServiceDataResult(&d)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return data, nil
+}
+
+// Create adds a draft version.
+func (s *policyDocumentService) Create(ctx context.Context, tenantID int64, input PolicyDocumentInput) (*PolicyDocumentServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "policyDocument.create")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID), attribute.String("policy_document.type", input.Type))
+
+	if err := s.ensureVersionFree(tenantID, input, nil); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "create policy document failed")
+		return nil, err
+	}
+
+	doc := &model.PolicyDocument{TenantID: tenantID, RequireAcceptance: true}
+	applyPolicyDocumentInput(doc, input)
+
+	created, err := s.policyDocumentRepo.Create(doc)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "create policy document failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	result := toPolicyDocumentServiceDataResult(created)
+	return &result, nil
+}
+
+// Update replaces a draft version.
+func (s *policyDocumentService) Update(ctx context.Context, tenantID int64, policyDocumentUUID uuid.UUID, input PolicyDocumentInput) (*PolicyDocumentServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "policyDocument.update")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("policy_document.uuid", policyDocumentUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	doc, err := s.findDraft(tenantID, policyDocumentUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "update policy document failed")
+		return nil, err
+	}
+	if err := s.ensureVersionFree(tenantID, input, doc); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "update policy document failed")
+		return nil, err
+	}
+
+	applyPolicyDocumentInput(doc, input)
+	updated, err := s.policyDocumentRepo.CreateOrUpdate(doc)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "update policy document failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	result := toPolicyDocumentServiceDataResult(updated)
+	return &result, nil
+}
+
+// Delete removes a draft version.
+func (s *policyDocumentService) Delete(ctx context.Context, tenantID int64, policyDocumentUUID uuid.UUID) (*PolicyDocumentServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "policyDocument.delete")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("policy_document.uuid", policyDocumentUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	doc, err := s.findDraft(tenantID, policyDocumentUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete policy document failed")
+		return nil, err
+	}
+
+	if err := s.policyDocumentRepo.DeleteByUUID(policyDocumentUUID); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete policy document failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	result := toPolicyDocumentServiceDataResult(doc)
+	return &result, nil
+}
+
+// Publish makes a draft the current version of its type. Users must accept
+// it at their next login when it requires acceptance.
+func (s *policyDocumentService) Publish(ctx context.Context, tenantID int64, policyDocumentUUID uuid.UUID) (*PolicyDocumentServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "policyDocument.publish")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("policy_document.uuid", policyDocumentUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	doc, err := s.findDraft(tenantID, policyDocumentUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "publish policy document failed")
+		return nil, err
+	}
+
+	doc.PublishedAt = ptr.TimePtr(time.Now())
+	updated, err := s.policyDocumentRepo.CreateOrUpdate(doc)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "publish policy document failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	result := toPolicyDocumentServiceDataResult(updated)
+	return &result, nil
+}
+
+// GetAcceptances lists who accepted a document version, newest first.
+func (s *policyDocumentService) GetAcceptances(ctx context.Context, tenantID int64, policyDocumentUUID uuid.UUID, page, limit int) (*PolicyAcceptanceServiceListResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "policyDocument.acceptances")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("policy_document.uuid", policyDocumentUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	doc, err := s.find(tenantID, policyDocumentUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list policy acceptances failed")
+		return nil, err
+	}
+
+	result, err := s.policyDocumentRepo.FindAcceptancesPaginated(repository.PolicyAcceptanceRepositoryGetFilter{
+		TenantID:         tenantID,
+		PolicyDocumentID: &doc.PolicyDocumentID,
+		Page:             page,
+		Limit:            limit,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list policy acceptances failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toPolicyAcceptanceServiceListResult(result), nil
+}
+
+// GetUserAcceptances lists the document versions a user of the tenant
+// accepted, newest first.
+func (s *policyDocumentService) GetUserAcceptances(ctx context.Context, tenantID int64, userUUID uuid.UUID, page, limit int) (*PolicyAcceptanceServiceListResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "policyDocument.userAcceptances")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("user.uuid", userUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	user, err := findTenantUser(s.userRepo, userUUID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list user policy acceptances failed")
+		return nil, err
+	}
+
+	result, err := s.policyDocumentRepo.FindAcceptancesPaginated(repository.PolicyAcceptanceRepositoryGetFilter{
+		TenantID: tenantID,
+		UserID:   &user.UserID,
+		Page:     page,
+		Limit:    limit,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list user policy acceptances failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toPolicyAcceptanceServiceListResult(result), nil
+}
+
+func (s *policyDocumentService) find(tenantID int64, policyDocumentUUID uuid.UUID) (*model.PolicyDocument, error) {
+	doc, err := s.policyDocumentRepo.FindByUUIDAndTenantID(policyDocumentUUID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, apperror.NewNotFoundWithReason("policy document not found")
+	}
+	return doc, nil
+}
+
+// findDraft returns the document if it has not been published yet.
+func (s *policyDocumentService) findDraft(tenantID int64, policyDocumentUUID uuid.UUID) (*model.PolicyDocument, error) {
+	doc, err := s.find(tenantID, policyDocumentUUID)
+	if err != nil {
+		return nil, err
+	}
+	if doc.PublishedAt != nil {
+		return nil, apperror.NewConflict("published policy documents cannot be changed; create a new version instead")
+	}
+	return doc, nil
+}
+
+// ensureVersionFree rejects a type and version another document of the
+// tenant already uses.
+func (s *policyDocumentService) ensureVersionFree(tenantID int64, input PolicyDocumentInput, self *model.PolicyDocument) error {
+	existing, err := s.policyDocumentRepo.FindByTypeAndVersion(tenantID, input.Type, input.Version)
+	if err != nil {
+		return err
+	}
+	if existing != nil && (self == nil || existing.PolicyDocumentID != self.PolicyDocumentID) {
+		return apperror.NewConflict(fmt.Sprintf("%s version %s already exists", input.Type, input.Version))
+	}
+	return nil
+}
+
+func applyPolicyDocumentInput(doc *model.PolicyDocument, input PolicyDocumentInput) {
+	doc.Type = input.Type
+	doc.Version = input.Version
+	doc.Title = input.Title
+	doc.URL = input.URL
+	if input.RequireAcceptance != nil {
+		doc.RequireAcceptance = *input.RequireAcceptance
+	}
+}
+
+// checkPolicyAcceptance checks that the user has accepted, or accepts with
+// this request, the current version of every document type the tenant
+// requires. It returns the current versions accepted with the request that
+// still need to be recorded. userID is nil for a user being registered. A
+// nil repository disables the check.
+func checkPolicyAcceptance(repo repository.PolicyDocumentRepository, tenantID int64, userID *int64, accepted []uuid.UUID) ([]model.PolicyDocument, error) {
+	if repo == nil {
+		return nil, nil
+	}
+
+	current, err := repo.FindCurrent(tenantID)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to load policy documents", err)
+	}
+	if len(current) == 0 {
+		return nil, nil
+	}
+
+	previously := map[int64]bool{}
+	if userID != nil {
+		ids := make([]int64, len(current))
+		for i, d := range current {
+			ids[i] = d.PolicyDocumentID
+		}
+		acceptedIDs, err := repo.FindAcceptedDocumentIDs(*userID, ids)
+		if err != nil {
+			return nil, apperror.NewInternal("failed to load policy acceptances", err)
+		}
+		for _, id := range acceptedIDs {
+			previously[id] = true
+		}
+	}
+
+	now := map[uuid.UUID]bool{}
+	for _, id := range accepted {
+		now[id] = true
+	}
+
+	var record []model.PolicyDocument
+	var missing []string
+	for _, d := range current {
+		switch {
+		case previously[d.PolicyDocumentID]:
+		case now[d.PolicyDocumentUUID]:
+			record = append(record, d)
+		case d.RequireAcceptance:
+			missing = append(missing, fmt.Sprintf("%s version %s", d.Type, d.Version))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, apperror.WithCode(apperror.CodePolicyAcceptanceRequired,
+			apperror.NewForbidden("acceptance required: "+strings.Join(missing, ", ")))
+	}
+	return record, nil
+}
+
+// newPolicyAcceptances builds the acceptance records of docs for the user,
+// with the client address and user agent of the request.
+func newPolicyAcceptances(ctx context.Context, tenantID, userID int64, docs []model.PolicyDocument) []model.PolicyAcceptance {
+	acceptances := make([]model.PolicyAcceptance, len(docs))
+	for i, d := range docs {
+		acceptances[i] = model.PolicyAcceptance{
+			PolicyDocumentID: d.PolicyDocumentID,
+			UserID:           userID,
+			TenantID:         tenantID,
+			IPAddress:        ptr.PtrOrNil(middleware.ClientIPFromContext(ctx)),
+			UserAgent:        ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		}
+	}
+	return acceptances
+}
+
+// acceptPoliciesOnLogin enforces the current policy documents for a user
+// signing in and records the ones accepted with the request.
+func acceptPoliciesOnLogin(ctx context.Context, repo repository.PolicyDocumentRepository, tenantID int64, user *model.User, accepted []uuid.UUID) error {
+	docs, err := checkPolicyAcceptance(repo, tenantID, &user.UserID, accepted)
+	if err != nil || len(docs) == 0 {
+		return err
+	}
+	if err := repo.Accept(newPolicyAcceptances(ctx, tenantID, user.UserID, docs)); err != nil {
+		return apperror.NewInternal("failed to record policy acceptance", err)
+	}
+	return nil
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPolicyDocumentSvc(repo *mockPolicyDocumentRepo) PolicyDocumentService {
+	return NewPolicyDocumentService(repo, &mockClientRepo{}, &mockUserRepo{})
+}
+
+func newPolicyDocument(id int64, docType, version string, published bool) model.PolicyDocument {
+	doc := model.PolicyDocument{
+		PolicyDocumentID:   id,
+		PolicyDocumentUUID: uuid.New(),
+		TenantID:           1,
+		Type:               docType,
+		Version:            version,
+		Title:              "Terms",
+		URL:                "https://example.com/terms",
+		RequireAcceptance:  true,
+	}
+	if published {
+		doc.PublishedAt = ptr.TimePtr(time.Now())
+	}
+	return doc
+}
+
+func policyDocumentInput() PolicyDocumentInput {
+	return PolicyDocumentInput{
+		Type:    model.PolicyDocumentTypeTermsOfService,
+		Version: "2026-10",
+		Title:   "Terms of Service",
+		URL:     "https://example.com/terms/2026-10",
+	}
+}
+
+func policyRepoFinding(doc model.PolicyDocument) *mockPolicyDocumentRepo {
+	return &mockPolicyDocumentRepo{
+		findByUUIDAndTenantFn: func(id uuid.UUID, tenantID int64) (*model.PolicyDocument, error) {
+			if id == doc.PolicyDocumentUUID && tenantID == doc.TenantID {
+				return &doc, nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Create / Update / Delete / Publish
+// ---------------------------------------------------------------------------
+
+func TestPolicyDocumentService_Create(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success requires acceptance by default", func(t *testing.T) {
+		var created *model.PolicyDocument
+		svc := newPolicyDocumentSvc(&mockPolicyDocumentRepo{
+			createFn: func(d *model.PolicyDocument) (*model.PolicyDocument, error) { created = d; return d, nil },
+		})
+		res, err := svc.Create(ctx, 1, policyDocumentInput())
+		require.NoError(t, err)
+		assert.Equal(t, "2026-10", res.Version)
+		assert.True(t, res.RequireAcceptance)
+		assert.Nil(t, res.PublishedAt)
+		require.NotNil(t, created)
+		assert.Equal(t, int64(1), created.TenantID)
+	})
+
+	t.Run("optional acceptance", func(t *testing.T) {
+		input := policyDocumentInput()
+		input.RequireAcceptance = new(bool)
+		res, err := newPolicyDocumentSvc(&mockPolicyDocumentRepo{}).Create(ctx, 1, input)
+		require.NoError(t, err)
+		assert.False(t, res.RequireAcceptance)
+	})
+
+	t.Run("version already exists", func(t *testing.T) {
+		existing := newPolicyDocument(5, model.PolicyDocumentTypeTermsOfService, "2026-10", true)
+		svc := newPolicyDocumentSvc(&mockPolicyDocumentRepo{
+			findByTypeAndVersionFn: func(_ int64, _, _ string) (*model.PolicyDocument, error) { return &existing, nil },
+		})
+		_, err := svc.Create(ctx, 1, policyDocumentInput())
+		var target *apperror.ConflictError
+		require.ErrorAs(t, err, &target)
+		assert.Contains(t, target.Error(), "terms_of_service version 2026-10 already exists")
+	})
+}
+
+func TestPolicyDocumentService_Update(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("draft is replaced", func(t *testing.T) {
+		draft := newPolicyDocument(5, model.PolicyDocumentTypeTermsOfService, "draft", false)
+		repo := policyRepoFinding(draft)
+		repo.findByTypeAndVersionFn = func(_ int64, _, _ string) (*model.PolicyDocument, error) { return &draft, nil }
+		input := policyDocumentInput()
+		input.Version = "draft"
+		input.RequireAcceptance = new(bool)
+
+		res, err := newPolicyDocumentSvc(repo).Update(ctx, 1, draft.PolicyDocumentUUID, input)
+		require.NoError(t, err)
+		assert.Equal(t, "Terms of Service", res.Title)
+		assert.False(t, res.RequireAcceptance)
+	})
+
+	t.Run("published version is immutable", func(t *testing.T) {
+		doc := newPolicyDocument(5, model.PolicyDocumentTypeTermsOfService, "1", true)
+		_, err := newPolicyDocumentSvc(policyRepoFinding(doc)).Update(ctx, 1, doc.PolicyDocumentUUID, policyDocumentInput())
+		var target *apperror.ConflictError
+		require.ErrorAs(t, err, &target)
+	})
+
+	t.Run("not found in tenant", func(t *testing.T) {
+		doc := newPolicyDocument(5, model.PolicyDocumentTypeTermsOfService, "1", false)
+		_, err := newPolicyDocumentSvc(policyRepoFinding(doc)).Update(ctx, 2, doc.PolicyDocumentUUID, policyDocumentInput())
+		var target *apperror.NotFoundError
+		require.ErrorAs(t, err, &target)
+	})
+}
+
+func TestPolicyDocumentService_Delete(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("draft", func(t *testing.T) {
+		doc := newPolicyDocument(5, model.PolicyDocumentTypePrivacyPolicy, "1", false)
+		var deleted any
+		repo := policyRepoFinding(doc)
+		repo.deleteByUUIDFn = func(id any) error { deleted = id; return nil }
+		_, err := newPolicyDocumentSvc(repo).Delete(ctx, 1, doc.PolicyDocumentUUID)
+		require.NoError(t, err)
+		assert.Equal(t, doc.PolicyDocumentUUID, deleted)
+	})
+
+	t.Run("published version is kept", func(t *testing.T) {
+		doc := newPolicyDocument(5, model.PolicyDocumentTypePrivacyPolicy, "1", true)
+		_, err := newPolicyDocumentSvc(policyRepoFinding(doc)).Delete(ctx, 1, doc.PolicyDocumentUUID)
+		var target *apperror.ConflictError
+		require.ErrorAs(t, err, &target)
+	})
+}
+
+func TestPolicyDocumentService_Publish(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("draft becomes current", func(t *testing.T) {
+		doc := newPolicyDocument(5, model.PolicyDocumentTypeTermsOfService, "2", false)
+		res, err := newPolicyDocumentSvc(policyRepoFinding(doc)).Publish(ctx, 1, doc.PolicyDocumentUUID)
+		require.NoError(t, err)
+		require.NotNil(t, res.PublishedAt)
+	})
+
+	t.Run("already published", func(t *testing.T) {
+		doc := newPolicyDocument(5, model.PolicyDocumentTypeTermsOfService, "2", true)
+		_, err := newPolicyDocumentSvc(policyRepoFinding(doc)).Publish(ctx, 1, doc.PolicyDocumentUUID)
+		var target *apperror.ConflictError
+		require.ErrorAs(t, err, &target)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// GetCurrent / acceptances
+// ---------------------------------------------------------------------------
+
+func TestPolicyDocumentService_GetCurrent(t *testing.T) {
+	ctx := context.Background()
+	current := []model.PolicyDocument{newPolicyDocument(5, model.PolicyDocumentTypeTermsOfService, "2", true)}
+
+	t.Run("success", func(t *testing.T) {
+		var gotTenant int64
+		svc := NewPolicyDocumentService(
+			&mockPolicyDocumentRepo{findCurrentFn: func(tid int64) ([]model.PolicyDocument, error) { gotTenant = tid; return current, nil }},
+			&mockClientRepo{findByClientIDAndIdentityProviderFn: func(_, _ string) (*model.Client, error) {
+				return &model.Client{TenantID: 7, Status: model.StatusActive}, nil
+			}},
+			&mockUserRepo{},
+		)
+		res, err := svc.GetCurrent(ctx, "c", "p")
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		assert.Equal(t, "2", res[0].Version)
+		assert.Equal(t, int64(7), gotTenant)
+	})
+
+	t.Run("inactive client", func(t *testing.T) {
+		svc := NewPolicyDocumentService(
+			&mockPolicyDocumentRepo{},
+			&mockClientRepo{findByClientIDAndIdentityProviderFn: func(_, _ string) (*model.Client, error) {
+				return &model.Client{TenantID: 7, Status: model.StatusInactive}, nil
+			}},
+			&mockUserRepo{},
+		)
+		_, err := svc.GetCurrent(ctx, "c", "p")
+		var target *apperror.NotFoundError
+		require.ErrorAs(t, err, &target)
+	})
+}
+
+func TestPolicyDocumentService_GetUserAcceptances(t *testing.T) {
+	ctx := context.Background()
+	user := &model.User{UserID: 9, UserUUID: uuid.New(), Username: "jane", UserIdentities: []model.UserIdentity{{TenantID: 1}}}
+	doc := newPolicyDocument(5, model.PolicyDocumentTypeTermsOfService, "2", true)
+
+	t.Run("success", func(t *testing.T) {
+		var gotFilter repository.PolicyAcceptanceRepositoryGetFilter
+		svc := NewPolicyDocumentService(
+			&mockPolicyDocumentRepo{findAcceptancesFn: func(f repository.PolicyAcceptanceRepositoryGetFilter) (*repository.PaginationResult[model.PolicyAcceptance], error) {
+				gotFilter = f
+				return &repository.PaginationResult[model.PolicyAcceptance]{
+					Data:  []model.PolicyAcceptance{{PolicyDocument: &doc, User: user, IPAddress: ptr.Ptr("203.0.113.7")}},
+					Total: 1,
+				}, nil
+			}},
+			&mockClientRepo{},
+			&mockUserRepo{findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return user, nil }},
+		)
+		res, err := svc.GetUserAcceptances(ctx, 1, user.UserUUID, 1, 10)
+		require.NoError(t, err)
+		require.Len(t, res.Data, 1)
+		assert.Equal(t, user.UserUUID, res.Data[0].UserUUID)
+		assert.Equal(t, "2", res.Data[0].Document.Version)
+		require.NotNil(t, gotFilter.UserID)
+		assert.Equal(t, int64(9), *gotFilter.UserID)
+	})
+
+	t.Run("user of another tenant", func(t *testing.T) {
+		svc := NewPolicyDocumentService(
+			&mockPolicyDocumentRepo{},
+			&mockClientRepo{},
+			&mockUserRepo{findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return user, nil }},
+		)
+		_, err := svc.GetUserAcceptances(ctx, 2, user.UserUUID, 1, 10)
+		code, ok := apperror.CodeOf(err)
+		require.True(t, ok)
+		assert.Equal(t, apperror.CodeUserNotFound, code)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// checkPolicyAcceptance
+// ---------------------------------------------------------------------------
+
+func TestCheckPolicyAcceptance(t *testing.T) {
+	terms := newPolicyDocument(5, model.PolicyDocumentTypeTermsOfService, "2", true)
+	privacy := newPolicyDocument(6, model.PolicyDocumentTypePrivacyPolicy, "1", true)
+	repo := func(accepted ...int64) *mockPolicyDocumentRepo {
+		return &mockPolicyDocumentRepo{
+			findCurrentFn:             func(_ int64) ([]model.PolicyDocument, error) { return []model.PolicyDocument{terms, privacy}, nil },
+			findAcceptedDocumentIDsFn: func(_ int64, _ []int64) ([]int64, error) { return accepted, nil },
+		}
+	}
+	userID := int64(9)
+
+	t.Run("nil repository disables the check", func(t *testing.T) {
+		docs, err := checkPolicyAcceptance(nil, 1, &userID, nil)
+		require.NoError(t, err)
+		assert.Empty(t, docs)
+	})
+
+	t.Run("missing acceptance", func(t *testing.T) {
+		_, err := checkPolicyAcceptance(repo(5), 1, &userID, nil)
+		code, ok := apperror.CodeOf(err)
+		require.True(t, ok)
+		assert.Equal(t, apperror.CodePolicyAcceptanceRequired, code)
+		assert.Contains(t, err.Error(), "privacy_policy version 1")
+		assert.NotContains(t, err.Error(), "terms_of_service")
+	})
+
+	t.Run("accepted before", func(t *testing.T) {
+		docs, err := checkPolicyAcceptance(repo(5, 6), 1, &userID, nil)
+		require.NoError(t, err)
+		assert.Empty(t, docs)
+	})
+
+	t.Run("accepted with the request", func(t *testing.T) {
+		docs, err := checkPolicyAcceptance(repo(5), 1, &userID, []uuid.UUID{privacy.PolicyDocumentUUID, uuid.New()})
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+		assert.Equal(t, privacy.PolicyDocumentID, docs[0].PolicyDocumentID)
+	})
+
+	t.Run("registration has no previous acceptances", func(t *testing.T) {
+		_, err := checkPolicyAcceptance(repo(5, 6), 1, nil, []uuid.UUID{terms.PolicyDocumentUUID})
+		code, _ := apperror.CodeOf(err)
+		assert.Equal(t, apperror.CodePolicyAcceptanceRequired, code)
+	})
+
+	t.Run("optional document", func(t *testing.T) {
+		optional := privacy
+		optional.RequireAcceptance = false
+		r := &mockPolicyDocumentRepo{
+			findCurrentFn: func(_ int64) ([]model.PolicyDocument, error) { return []model.PolicyDocument{terms, optional}, nil },
+		}
+		docs, err := checkPolicyAcceptance(r, 1, nil, []uuid.UUID{terms.PolicyDocumentUUID})
+		require.NoError(t, err)
+		assert.Len(t, docs, 1)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		r := &mockPolicyDocumentRepo{
+			findCurrentFn: func(_ int64) ([]model.PolicyDocument, error) { return nil, errors.New("db error") },
+		}
+		_, err := checkPolicyAcceptance(r, 1, &userID, nil)
+		var target *apperror.InternalError
+		require.ErrorAs(t, err, &target)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Login integration
+// ---------------------------------------------------------------------------
+
+func newPolicyLoginService(t *testing.T, password string, repo *mockPolicyDocumentRepo) LoginService {
+	t.Helper()
+	gormDB, mock := newMockGormDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	return NewLoginService(gormDB,
+		&mockClientRepo{findSystemFn: func() (*model.Client, error) { return buildActiveClient(), nil }},
+		&mockUserRepo{findByUsernameFn: func(_ string) (*model.User, error) { return buildActiveUser(t, password), nil }},
+		&mockUserTokenRepo{},
+		&mockUserIdentityRepo{findByUserIDAndClientIDFn: func(_, _ int64) (*model.UserIdentity, error) {
+			return &model.UserIdentity{Sub: "sub-policy"}, nil
+		}},
+		&mockIdentityProviderRepo{},
+		&mockTenantSettingRepo{},
+		&mockSecuritySettingRepo{},
+		&mockAuthEventService{},
+		nil,
+		nil,
+		nil,
+		nil,
+		repo,
+	)
+}
+
+func TestLogin_PolicyAcceptance(t *testing.T) {
+	initTestJWTKeysService(t)
+	const password = "S3cur3P@ss!"
+	terms := newPolicyDocument(5, model.PolicyDocumentTypeTermsOfService, "2", true)
+
+	t.Run("new version must be accepted", func(t *testing.T) {
+		svc := newPolicyLoginService(t, password, &mockPolicyDocumentRepo{
+			findCurrentFn: func(_ int64) ([]model.PolicyDocument, error) { return []model.PolicyDocument{terms}, nil },
+		})
+		_, err := svc.Login(context.Background(), "policy-missing", password, nil, nil, nil)
+		code, ok := apperror.CodeOf(err)
+		require.True(t, ok)
+		assert.Equal(t, apperror.CodePolicyAcceptanceRequired, code)
+	})
+
+	t.Run("acceptance is recorded", func(t *testing.T) {
+		var recorded []model.PolicyAcceptance
+		svc := newPolicyLoginService(t, password, &mockPolicyDocumentRepo{
+			findCurrentFn: func(_ int64) ([]model.PolicyDocument, error) { return []model.PolicyDocument{terms}, nil },
+			acceptFn:      func(a []model.PolicyAcceptance) error { recorded = a; return nil },
+		})
+		res, err := svc.Login(context.Background(), "policy-accepted", password, nil, nil, []uuid.UUID{terms.PolicyDocumentUUID})
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.AccessToken)
+		require.Len(t, recorded, 1)
+		assert.Equal(t, terms.PolicyDocumentID, recorded[0].PolicyDocumentID)
+		assert.Equal(t, int64(1), recorded[0].UserID)
+	})
+}
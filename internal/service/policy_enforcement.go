@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/maintainerd/auth/internal/abac"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// PolicyEnforcementCacheTTL is how long a tenant's active policies are kept in
+// memory. Policy changes take effect within this interval.
+const PolicyEnforcementCacheTTL = 30 * time.Second
+
+// PolicyEnforcementService applies the deny statements of a tenant's active
+// policies, including their attribute-based conditions, to requests. It
+// satisfies middleware.PolicyEnforcer.
+type PolicyEnforcementService interface {
+	// FindDeny returns the first deny statement of the tenant's active
+	// policies that covers action and whose condition holds for in, or nil.
+	FindDeny(ctx context.Context, tenantID int64, action string, in abac.Input) (*abac.Match, error)
+}
+
+// tenantPolicies is a cached set of a tenant's parsed active policies.
+type tenantPolicies struct {
+	policies []abac.Policy
+	loadedAt time.Time
+}
+
+type policyEnforcementService struct {
+	policyRepo repository.PolicyRepository
+	now        func() time.Time
+
+	mu    sync.RWMutex
+	cache map[int64]tenantPolicies
+}
+
+// NewPolicyEnforcementService creates a new PolicyEnforcementService.
+func NewPolicyEnforcementService(policyRepo repository.PolicyRepository) PolicyEnforcementService {
+	return &policyEnforcementService{
+		policyRepo: policyRepo,
+		now:        time.Now,
+		cache:      make(map[int64]tenantPolicies),
+	}
+}
+
+func (s *policyEnforcementService) FindDeny(ctx context.Context, tenantID int64, action string, in abac.Input) (*abac.Match, error) {
+	_, span := otel.Tracer("service").Start(ctx, "policyEnforcement.findDeny")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int64("tenant.id", tenantID),
+		attribute.String("authz.action", action),
+	)
+
+	policies, err := s.activePolicies(tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "load policies failed")
+		return nil, apperror.NewInternal("failed to load policies", err)
+	}
+
+	match := abac.FindDeny(policies, action, in)
+	span.SetAttributes(attribute.Bool("authz.policy_denied", match != nil))
+	span.SetStatus(codes.Ok, "")
+	return match, nil
+}
+
+// activePolicies returns the tenant's parsed active policies, from memory
+// while they are fresh.
+func (s *policyEnforcementService) activePolicies(tenantID int64) ([]abac.Policy, error) {
+	now := s.now()
+
+	s.mu.RLock()
+	cached, ok := s.cache[tenantID]
+	s.mu.RUnlock()
+	if ok && now.Sub(cached.loadedAt) < PolicyEnforcementCacheTTL {
+		return cached.policies, nil
+	}
+
+	found, err := s.policyRepo.FindActiveByTenantID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	policies := make([]abac.Policy, 0, len(found))
+	for _, p := range found {
+		// Documents are validated on write; one that cannot be parsed
+		// has no statements to apply.
+		statements, err := abac.ParseStatements(p.Document)
+		if err != nil {
+			continue
+		}
+		policies = append(policies, abac.Policy{UUID: p.PolicyUUID, Name: p.Name, Statements: statements})
+	}
+
+	s.mu.Lock()
+	s.cache[tenantID] = tenantPolicies{policies: policies, loadedAt: now}
+	s.mu.Unlock()
+	return policies, nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/abac"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func TestPolicyEnforcementService_FindDeny(t *testing.T) {
+	policyUUID := uuid.New()
+	policies := []model.Policy{
+		{PolicyUUID: uuid.New(), Name: "broken", Document: datatypes.JSON(`not json`)},
+		{
+			PolicyUUID: policyUUID,
+			Name:       "no-self-delete",
+			Document: datatypes.JSON(`{"version":"v1","statement":[
+				{"effect":"deny","action":["user:delete"],"resource":["auth:*"],"condition":"resource.params.user_uuid == subject.id"}
+			]}`),
+		},
+	}
+	self := abac.Input{
+		Subject:  abac.Subject{ID: "u-1"},
+		Resource: abac.Resource{Params: map[string]string{"user_uuid": "u-1"}},
+	}
+
+	t.Run("applies conditions", func(t *testing.T) {
+		svc := NewPolicyEnforcementService(&mockPolicyRepo{findActiveByTenantIDFn: func(tenantID int64) ([]model.Policy, error) {
+			assert.Equal(t, int64(7), tenantID)
+			return policies, nil
+		}})
+
+		m, err := svc.FindDeny(context.Background(), 7, "user:delete", self)
+		require.NoError(t, err)
+		require.NotNil(t, m)
+		assert.Equal(t, policyUUID, m.PolicyUUID)
+
+		other := self
+		other.Resource.Params = map[string]string{"user_uuid": "u-2"}
+		m, err = svc.FindDeny(context.Background(), 7, "user:delete", other)
+		require.NoError(t, err)
+		assert.Nil(t, m)
+	})
+
+	t.Run("caches policies per tenant", func(t *testing.T) {
+		calls := map[int64]int{}
+		svc := NewPolicyEnforcementService(&mockPolicyRepo{findActiveByTenantIDFn: func(tenantID int64) ([]model.Policy, error) {
+			calls[tenantID]++
+			return policies, nil
+		}}).(*policyEnforcementService)
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		svc.now = func() time.Time { return now }
+
+		for i := 0; i < 3; i++ {
+			_, err := svc.FindDeny(context.Background(), 7, "user:read", self)
+			require.NoError(t, err)
+		}
+		_, err := svc.FindDeny(context.Background(), 8, "user:read", self)
+		require.NoError(t, err)
+		assert.Equal(t, map[int64]int{7: 1, 8: 1}, calls)
+
+		now = now.Add(PolicyEnforcementCacheTTL)
+		_, err = svc.FindDeny(context.Background(), 7, "user:read", self)
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls[7], "expired entries are reloaded")
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		svc := NewPolicyEnforcementService(&mockPolicyRepo{findActiveByTenantIDFn: func(int64) ([]model.Policy, error) {
+			return nil, errors.New("db down")
+		}})
+		_, err := svc.FindDeny(context.Background(), 7, "user:delete", self)
+		var internal *apperror.InternalError
+		assert.ErrorAs(t, err, &internal)
+	})
+}
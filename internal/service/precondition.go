@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/middleware"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// checkIfMatch refuses an update when the request carried an If-Match ETag
+// that no longer names the current version of the record, so two admins
+// editing the same record do not silently overwrite each other. entity names
+// the record in the message, e.g. "role".
+func checkIfMatch(ctx context.Context, entity string, updatedAt time.Time) error {
+	if !middleware.IfMatchSatisfied(ctx, updatedAt) {
+		return apperror.NewPrecondition(entity + " was changed by another request, reload it and try again")
+	}
+	return nil
+}
+
+// lockForUpdate locks the rows read through tx until the transaction ends, so
+// the version checked by checkIfMatch is the one that gets overwritten.
+// Preloaded associations are not locked.
+func lockForUpdate(tx *gorm.DB) *gorm.DB {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"})
+}
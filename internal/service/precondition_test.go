@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ifMatchContext returns a context carrying the If-Match header ifMatch, as
+// IfMatchMiddleware stores it.
+func ifMatchContext(t *testing.T, ifMatch string) context.Context {
+	t.Helper()
+	var ctx context.Context
+	req := httptest.NewRequest(http.MethodPut, "/", nil)
+	req.Header.Set("If-Match", ifMatch)
+	middleware.IfMatchMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	})).ServeHTTP(httptest.NewRecorder(), req)
+	return ctx
+}
+
+func TestCheckIfMatch(t *testing.T) {
+	version := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, checkIfMatch(context.Background(), "role", version))
+	assert.NoError(t, checkIfMatch(ifMatchContext(t, middleware.ETag(version)), "role", version))
+
+	err := checkIfMatch(ifMatchContext(t, middleware.ETag(version)), "role", version.Add(time.Second))
+	var want *apperror.PreconditionError
+	require.ErrorAs(t, err, &want)
+	assert.Contains(t, err.Error(), "role was changed")
+}
@@ -90,23 +90,28 @@ type ProfileService interface {
 	GetAll(ctx context.Context, userUUID uuid.UUID, firstName, lastName, email, phone, city, country *string, isDefault *bool, page, limit int, sortBy, sortOrder string) (*ProfileServiceListResult, error)
 	SetDefaultProfile(ctx context.Context, profileUUID uuid.UUID, userUUID uuid.UUID) (*ProfileServiceDataResult, error)
 	DeleteByUUID(ctx context.Context, profileUUID uuid.UUID, userUUID uuid.UUID) (*ProfileServiceDataResult, error)
+	SetPicture(ctx context.Context, profileUUID uuid.UUID, userUUID uuid.UUID, data []byte) (*ProfileServiceDataResult, error)
+	DeletePicture(ctx context.Context, profileUUID uuid.UUID, userUUID uuid.UUID) (*ProfileServiceDataResult, error)
 }
 
 type profileService struct {
 	db          *gorm.DB
 	profileRepo repository.ProfileRepository
 	userRepo    repository.UserRepository
+	pictures    ProfilePictureConfig
 }
 
 func NewProfileService(
 	db *gorm.DB,
 	profileRepo repository.ProfileRepository,
 	userRepo repository.UserRepository,
+	pictures ProfilePictureConfig,
 ) ProfileService {
 	return &profileService{
 		db:          db,
 		profileRepo: profileRepo,
 		userRepo:    userRepo,
+		pictures:    pictures,
 	}
 }
 
@@ -192,6 +197,9 @@ func (s *profileService) CreateOrUpdateProfile(
 			if err != nil {
 				return err
 			}
+			if err := rejectPastedSecrets("metadata", metadataBytes); err != nil {
+				return err
+			}
 			profile.Metadata = metadataBytes
 		} else {
 			profile.Metadata = datatypes.JSON([]byte("{}"))
@@ -232,7 +240,7 @@ func (s *profileService) CreateOrUpdateProfile(
 	}
 
 	span.SetStatus(codes.Ok, "")
-	return toProfileServiceDataResult(updatedProfile), nil
+	return s.toResult(ctx, updatedProfile), nil
 }
 
 func (s *profileService) CreateOrUpdateSpecificProfile(
@@ -328,6 +336,9 @@ func (s *profileService) CreateOrUpdateSpecificProfile(
 			if err != nil {
 				return err
 			}
+			if err := rejectPastedSecrets("metadata", metadataBytes); err != nil {
+				return err
+			}
 			profile.Metadata = metadataBytes
 		} else {
 			profile.Metadata = datatypes.JSON([]byte("{}"))
@@ -368,7 +379,7 @@ func (s *profileService) CreateOrUpdateSpecificProfile(
 	}
 
 	span.SetStatus(codes.Ok, "")
-	return toProfileServiceDataResult(updatedProfile), nil
+	return s.toResult(ctx, updatedProfile), nil
 }
 
 func (s *profileService) GetByUUID(ctx context.Context, profileUUID uuid.UUID, userUUID uuid.UUID) (*ProfileServiceDataResult, error) {
@@ -396,7 +407,7 @@ func (s *profileService) GetByUUID(ctx context.Context, profileUUID uuid.UUID, u
 	}
 
 	span.SetStatus(codes.Ok, "")
-	return toProfileServiceDataResult(profile), nil
+	return s.toResult(ctx, profile), nil
 }
 
 func (s *profileService) GetByUserUUID(ctx context.Context, userUUID uuid.UUID) (*ProfileServiceDataResult, error) {
@@ -419,7 +430,7 @@ func (s *profileService) GetByUserUUID(ctx context.Context, userUUID uuid.UUID)
 	}
 
 	span.SetStatus(codes.Ok, "")
-	return toProfileServiceDataResult(profile), nil
+	return s.toResult(ctx, profile), nil
 }
 
 func (s *profileService) GetAll(
@@ -468,7 +479,7 @@ func (s *profileService) GetAll(
 	// Convert to service result
 	data := make([]ProfileServiceDataResult, len(result.Data))
 	for i, profile := range result.Data {
-		if sr := toProfileServiceDataResult(&profile); sr != nil {
+		if sr := s.toResult(ctx, &profile); sr != nil {
 			data[i] = *sr
 		}
 	}
@@ -537,7 +548,7 @@ func (s *profileService) SetDefaultProfile(ctx context.Context, profileUUID uuid
 	}
 
 	span.SetStatus(codes.Ok, "")
-	return toProfileServiceDataResult(updatedProfile), nil
+	return s.toResult(ctx, updatedProfile), nil
 }
 
 func (s *profileService) DeleteByUUID(ctx context.Context, profileUUID uuid.UUID, userUUID uuid.UUID) (*ProfileServiceDataResult, error) {
@@ -580,7 +591,7 @@ func (s *profileService) DeleteByUUID(ctx context.Context, profileUUID uuid.UUID
 	}
 
 	span.SetStatus(codes.Ok, "")
-	return toProfileServiceDataResult(profile), nil
+	return s.toResult(ctx, profile), nil
 }
 
 // Helper functions
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/imaging"
+	"github.com/maintainerd/auth/internal/logging"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/storage"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// ProfilePictureConfig configures uploaded profile pictures. A nil Store
+// disables uploads; profiles then only carry the profile_url they were
+// given.
+type ProfilePictureConfig struct {
+	Store storage.Store
+	// MaxDimension is the longest side, in pixels, pictures are scaled
+	// down to.
+	MaxDimension int
+	// URLTTL is the lifetime of the signed URLs returned as profile_url.
+	URLTTL time.Duration
+}
+
+// SetPicture normalizes and stores an uploaded picture for the user's
+// profile. It replaces any earlier upload, which is then deleted from
+// storage.
+func (s *profileService) SetPicture(ctx context.Context, profileUUID uuid.UUID, userUUID uuid.UUID, data []byte) (*ProfileServiceDataResult, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "profile.setPicture")
+	defer span.End()
+	span.SetAttributes(attribute.String("profile.uuid", profileUUID.String()), attribute.String("user.uuid", userUUID.String()))
+
+	if s.pictures.Store == nil {
+		span.SetStatus(codes.Error, "set profile picture failed")
+		return nil, apperror.NewPrecondition("profile picture uploads are not enabled")
+	}
+
+	profile, err := s.ownedProfile(profileUUID, userUUID)
+	if err != nil {
+		span.SetStatus(codes.Error, "set profile picture failed")
+		return nil, err
+	}
+
+	img, err := imaging.Normalize(data, s.pictures.MaxDimension)
+	switch {
+	case errors.Is(err, imaging.ErrUnsupportedFormat):
+		span.SetStatus(codes.Error, "set profile picture failed")
+		return nil, apperror.NewValidation("picture must be a JPEG, PNG or GIF image")
+	case errors.Is(err, imaging.ErrTooLarge):
+		span.SetStatus(codes.Error, "set profile picture failed")
+		return nil, apperror.NewValidation("picture dimensions are too large")
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "set profile picture failed")
+		return nil, apperror.NewValidation("picture could not be read")
+	}
+
+	key, err := newPictureKey(profile.ProfileUUID, img.ContentType)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "set profile picture failed")
+		return nil, apperror.NewInternal("failed to name profile picture", err)
+	}
+	if err := s.pictures.Store.Put(ctx, key, img.ContentType, img.Data); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "set profile picture failed")
+		return nil, apperror.NewInternal("failed to store profile picture", err)
+	}
+
+	previous := profile.PictureKey
+	profile.PictureKey = &key
+	updated, err := s.profileRepo.CreateOrUpdate(profile)
+	if err != nil {
+		s.deletePicture(ctx, key)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "set profile picture failed")
+		return nil, err
+	}
+	if previous != nil {
+		s.deletePicture(ctx, *previous)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return s.toResult(ctx, updated), nil
+}
+
+// DeletePicture removes the uploaded picture of the user's profile, which
+// then falls back to its profile_url.
+func (s *profileService) DeletePicture(ctx context.Context, profileUUID uuid.UUID, userUUID uuid.UUID) (*ProfileServiceDataResult, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "profile.deletePicture")
+	defer span.End()
+	span.SetAttributes(attribute.String("profile.uuid", profileUUID.String()), attribute.String("user.uuid", userUUID.String()))
+
+	profile, err := s.ownedProfile(profileUUID, userUUID)
+	if err != nil {
+		span.SetStatus(codes.Error, "delete profile picture failed")
+		return nil, err
+	}
+	if profile.PictureKey == nil {
+		span.SetStatus(codes.Error, "delete profile picture failed")
+		return nil, apperror.NewNotFound("profile picture")
+	}
+
+	previous := *profile.PictureKey
+	profile.PictureKey = nil
+	updated, err := s.profileRepo.CreateOrUpdate(profile)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete profile picture failed")
+		return nil, err
+	}
+	s.deletePicture(ctx, previous)
+
+	span.SetStatus(codes.Ok, "")
+	return s.toResult(ctx, updated), nil
+}
+
+// ownedProfile returns the profile if it belongs to the user.
+func (s *profileService) ownedProfile(profileUUID uuid.UUID, userUUID uuid.UUID) (*model.Profile, error) {
+	user, err := s.userRepo.FindByUUID(userUUID)
+	if err != nil || user == nil {
+		return nil, apperror.NewNotFound("user not found")
+	}
+	profile, err := s.profileRepo.FindByUUID(profileUUID)
+	if err != nil || profile == nil {
+		return nil, apperror.NewNotFound("profile not found")
+	}
+	if profile.UserID != user.UserID {
+		return nil, apperror.NewForbidden("profile does not belong to user")
+	}
+	return profile, nil
+}
+
+// deletePicture removes a stored picture that is no longer referenced. A
+// failure only leaves an orphaned object behind, so it is logged.
+func (s *profileService) deletePicture(ctx context.Context, key string) {
+	if err := s.pictures.Store.Delete(ctx, key); err != nil {
+		logging.Logger(logging.ComponentAuth).WarnContext(ctx, "profile picture not deleted", "key", key, "error", err)
+	}
+}
+
+// toResult maps a profile to its service result. An uploaded picture is
+// returned as a signed URL in place of the stored profile_url.
+func (s *profileService) toResult(ctx context.Context, profile *model.Profile) *ProfileServiceDataResult {
+	result := toProfileServiceDataResult(profile)
+	if result == nil || profile.PictureKey == nil || s.pictures.Store == nil {
+		return result
+	}
+	signed, err := s.pictures.Store.SignedURL(ctx, *profile.PictureKey, s.pictures.URLTTL)
+	if err != nil {
+		logging.Logger(logging.ComponentAuth).WarnContext(ctx, "profile picture URL not signed", "key", *profile.PictureKey, "error", err)
+		return result
+	}
+	result.ProfileURL = &signed
+	return result
+}
+
+// newPictureKey names a new object for the profile. Every upload gets a
+// fresh name so caches holding the previous picture never serve it for the
+// new one.
+func newPictureKey(profileUUID uuid.UUID, contentType string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	ext := ".png"
+	if contentType == imaging.ContentTypeJPEG {
+		ext = ".jpg"
+	}
+	return fmt.Sprintf("profiles/%s/%s%s", profileUUID, hex.EncodeToString(b), ext), nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePictureStore keeps objects in memory.
+type fakePictureStore struct {
+	objects map[string][]byte
+	deleted []string
+	putErr  error
+}
+
+func (s *fakePictureStore) Put(_ context.Context, key, _ string, body []byte) error {
+	if s.putErr != nil {
+		return s.putErr
+	}
+	if s.objects == nil {
+		s.objects = map[string][]byte{}
+	}
+	s.objects[key] = body
+	return nil
+}
+
+func (s *fakePictureStore) Delete(_ context.Context, key string) error {
+	s.deleted = append(s.deleted, key)
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *fakePictureStore) SignedURL(_ context.Context, key string, _ time.Duration) (string, error) {
+	return "https://media.example.com/" + key + "?sig=x", nil
+}
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))))
+	return buf.Bytes()
+}
+
+func TestProfileService_SetPicture(t *testing.T) {
+	userUUID := uuid.New()
+	profileUUID := uuid.New()
+	userRepo := &mockUserRepo{
+		findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
+			return &model.User{UserID: 42, UserUUID: userUUID}, nil
+		},
+	}
+	profileRepoWith := func(profile *model.Profile) *mockProfileRepo {
+		return &mockProfileRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Profile, error) { return profile, nil },
+		}
+	}
+
+	t.Run("uploads disabled → precondition", func(t *testing.T) {
+		svc := NewProfileService(nil, profileRepoWith(&model.Profile{UserID: 42}), userRepo, ProfilePictureConfig{})
+		_, err := svc.SetPicture(context.Background(), profileUUID, userUUID, testPNG(t, 8, 8))
+		var precondition *apperror.PreconditionError
+		assert.True(t, errors.As(err, &precondition))
+	})
+
+	t.Run("profile of another user → forbidden", func(t *testing.T) {
+		store := &fakePictureStore{}
+		svc := NewProfileService(nil, profileRepoWith(&model.Profile{UserID: 7}), userRepo, ProfilePictureConfig{Store: store, MaxDimension: 64})
+		_, err := svc.SetPicture(context.Background(), profileUUID, userUUID, testPNG(t, 8, 8))
+		var forbidden *apperror.ForbiddenError
+		assert.True(t, errors.As(err, &forbidden))
+		assert.Empty(t, store.objects)
+	})
+
+	t.Run("not an image → validation", func(t *testing.T) {
+		store := &fakePictureStore{}
+		svc := NewProfileService(nil, profileRepoWith(&model.Profile{UserID: 42}), userRepo, ProfilePictureConfig{Store: store, MaxDimension: 64})
+		_, err := svc.SetPicture(context.Background(), profileUUID, userUUID, []byte("%PDF-1.7"))
+		var validation *apperror.ValidationError
+		assert.True(t, errors.As(err, &validation))
+		assert.Empty(t, store.objects)
+	})
+
+	t.Run("store failure → internal", func(t *testing.T) {
+		store := &fakePictureStore{putErr: errors.New("bucket unavailable")}
+		svc := NewProfileService(nil, profileRepoWith(&model.Profile{UserID: 42}), userRepo, ProfilePictureConfig{Store: store, MaxDimension: 64})
+		_, err := svc.SetPicture(context.Background(), profileUUID, userUUID, testPNG(t, 8, 8))
+		var internal *apperror.InternalError
+		assert.True(t, errors.As(err, &internal))
+	})
+
+	t.Run("success replaces the previous picture", func(t *testing.T) {
+		previous := "profiles/" + profileUUID.String() + "/old.png"
+		profile := &model.Profile{UserID: 42, ProfileUUID: profileUUID, PictureKey: &previous}
+		store := &fakePictureStore{}
+		svc := NewProfileService(nil, profileRepoWith(profile), userRepo, ProfilePictureConfig{Store: store, MaxDimension: 64, URLTTL: time.Hour})
+
+		res, err := svc.SetPicture(context.Background(), profileUUID, userUUID, testPNG(t, 256, 128))
+		require.NoError(t, err)
+		require.NotNil(t, profile.PictureKey)
+		key := *profile.PictureKey
+		assert.True(t, strings.HasPrefix(key, "profiles/"+profileUUID.String()+"/"))
+		assert.True(t, strings.HasSuffix(key, ".png"))
+		assert.NotEqual(t, previous, key)
+
+		stored, err := png.DecodeConfig(bytes.NewReader(store.objects[key]))
+		require.NoError(t, err)
+		assert.Equal(t, 64, stored.Width)
+		assert.Equal(t, 32, stored.Height)
+
+		assert.Equal(t, []string{previous}, store.deleted)
+		require.NotNil(t, res.ProfileURL)
+		assert.Equal(t, "https://media.example.com/"+key+"?sig=x", *res.ProfileURL)
+	})
+
+	t.Run("update failure → new object is removed", func(t *testing.T) {
+		store := &fakePictureStore{}
+		profileRepo := profileRepoWith(&model.Profile{UserID: 42, ProfileUUID: profileUUID})
+		profileRepo.createOrUpdateFn = func(*model.Profile) (*model.Profile, error) { return nil, errors.New("db error") }
+		svc := NewProfileService(nil, profileRepo, userRepo, ProfilePictureConfig{Store: store, MaxDimension: 64})
+
+		_, err := svc.SetPicture(context.Background(), profileUUID, userUUID, testPNG(t, 8, 8))
+		require.Error(t, err)
+		assert.Len(t, store.deleted, 1)
+		assert.Empty(t, store.objects)
+	})
+}
+
+func TestProfileService_DeletePicture(t *testing.T) {
+	userUUID := uuid.New()
+	profileUUID := uuid.New()
+	userRepo := &mockUserRepo{
+		findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
+			return &model.User{UserID: 42, UserUUID: userUUID}, nil
+		},
+	}
+
+	t.Run("no picture → not found", func(t *testing.T) {
+		profileRepo := &mockProfileRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Profile, error) { return &model.Profile{UserID: 42}, nil },
+		}
+		svc := NewProfileService(nil, profileRepo, userRepo, ProfilePictureConfig{Store: &fakePictureStore{}})
+		_, err := svc.DeletePicture(context.Background(), profileUUID, userUUID)
+		var notFound *apperror.NotFoundError
+		assert.True(t, errors.As(err, &notFound))
+	})
+
+	t.Run("success falls back to profile_url", func(t *testing.T) {
+		key := "profiles/" + profileUUID.String() + "/old.png"
+		profileURL := "https://cdn.example.com/avatar.png"
+		profile := &model.Profile{UserID: 42, ProfileUUID: profileUUID, PictureKey: &key, ProfileURL: &profileURL}
+		profileRepo := &mockProfileRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Profile, error) { return profile, nil },
+		}
+		store := &fakePictureStore{}
+		svc := NewProfileService(nil, profileRepo, userRepo, ProfilePictureConfig{Store: store})
+
+		res, err := svc.DeletePicture(context.Background(), profileUUID, userUUID)
+		require.NoError(t, err)
+		assert.Nil(t, profile.PictureKey)
+		assert.Equal(t, []string{key}, store.deleted)
+		require.NotNil(t, res.ProfileURL)
+		assert.Equal(t, profileURL, *res.ProfileURL)
+	})
+}
@@ -14,7 +14,7 @@ import (
 )
 
 func newProfileSvc(profileRepo *mockProfileRepo, userRepo *mockUserRepo) ProfileService {
-	return NewProfileService(nil, profileRepo, userRepo)
+	return NewProfileService(nil, profileRepo, userRepo, ProfilePictureConfig{})
 }
 
 // ---------------------------------------------------------------------------
@@ -31,7 +31,7 @@ func TestProfileService_CreateOrUpdateProfile(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewProfileService(db, &mockProfileRepo{}, &mockUserRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return nil, nil },
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.CreateOrUpdateProfile(context.Background(), userUUID, "John", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "user not found")
@@ -49,7 +49,7 @@ func TestProfileService_CreateOrUpdateProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID, UserUUID: userUUID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.CreateOrUpdateProfile(context.Background(), userUUID, "John", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "db error")
@@ -68,7 +68,7 @@ func TestProfileService_CreateOrUpdateProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID, UserUUID: userUUID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.CreateOrUpdateProfile(context.Background(), userUUID, "John", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "create failed")
@@ -87,7 +87,7 @@ func TestProfileService_CreateOrUpdateProfile(t *testing.T) {
 			updateByUUIDFn: func(_, _ any) (*model.User, error) {
 				return nil, errors.New("user update failed")
 			},
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.CreateOrUpdateProfile(context.Background(), userUUID, "John", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "user update failed")
@@ -103,7 +103,7 @@ func TestProfileService_CreateOrUpdateProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID, UserUUID: userUUID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		res, err := svc.CreateOrUpdateProfile(context.Background(), userUUID, "John", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.NoError(t, err)
 		assert.Equal(t, "John", res.FirstName)
@@ -121,7 +121,7 @@ func TestProfileService_CreateOrUpdateProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID, UserUUID: userUUID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		res, err := svc.CreateOrUpdateProfile(context.Background(), userUUID, "John", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, meta)
 		require.NoError(t, err)
 		assert.Equal(t, "value", res.Metadata["key"])
@@ -138,7 +138,7 @@ func TestProfileService_CreateOrUpdateProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID, UserUUID: userUUID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.CreateOrUpdateProfile(context.Background(), userUUID, "John", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, badMeta)
 		require.Error(t, err)
 	})
@@ -157,7 +157,7 @@ func TestProfileService_CreateOrUpdateProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID, UserUUID: userUUID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.CreateOrUpdateProfile(context.Background(), userUUID, "Jane", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "update failed")
@@ -174,7 +174,7 @@ func TestProfileService_CreateOrUpdateProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID, UserUUID: userUUID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		res, err := svc.CreateOrUpdateProfile(context.Background(), userUUID, "Jane", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.NoError(t, err)
 		assert.Equal(t, "Jane", res.FirstName)
@@ -196,7 +196,7 @@ func TestProfileService_CreateOrUpdateSpecificProfile(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewProfileService(db, &mockProfileRepo{}, &mockUserRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return nil, nil },
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.CreateOrUpdateSpecificProfile(context.Background(), profileUUID, userUUID, "John", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "user not found")
@@ -214,7 +214,7 @@ func TestProfileService_CreateOrUpdateSpecificProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID, UserUUID: userUUID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.CreateOrUpdateSpecificProfile(context.Background(), profileUUID, userUUID, "John", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "db error")
@@ -232,7 +232,7 @@ func TestProfileService_CreateOrUpdateSpecificProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID, UserUUID: userUUID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.CreateOrUpdateSpecificProfile(context.Background(), profileUUID, userUUID, "John", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "find user prof err")
@@ -250,7 +250,7 @@ func TestProfileService_CreateOrUpdateSpecificProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID, UserUUID: userUUID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.CreateOrUpdateSpecificProfile(context.Background(), profileUUID, userUUID, "John", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "create failed")
@@ -267,7 +267,7 @@ func TestProfileService_CreateOrUpdateSpecificProfile(t *testing.T) {
 			updateByUUIDFn: func(_, _ any) (*model.User, error) {
 				return nil, errors.New("user update failed")
 			},
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.CreateOrUpdateSpecificProfile(context.Background(), profileUUID, userUUID, "John", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "user update failed")
@@ -281,7 +281,7 @@ func TestProfileService_CreateOrUpdateSpecificProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID, UserUUID: userUUID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		res, err := svc.CreateOrUpdateSpecificProfile(context.Background(), profileUUID, userUUID, "John", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.NoError(t, err)
 		assert.Equal(t, "John", res.FirstName)
@@ -300,7 +300,7 @@ func TestProfileService_CreateOrUpdateSpecificProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID, UserUUID: userUUID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		res, err := svc.CreateOrUpdateSpecificProfile(context.Background(), profileUUID, userUUID, "John", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.NoError(t, err)
 		assert.False(t, res.IsDefault)
@@ -315,7 +315,7 @@ func TestProfileService_CreateOrUpdateSpecificProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID, UserUUID: userUUID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		res, err := svc.CreateOrUpdateSpecificProfile(context.Background(), profileUUID, userUUID, "John", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, meta)
 		require.NoError(t, err)
 		assert.Equal(t, "admin", res.Metadata["role"])
@@ -330,7 +330,7 @@ func TestProfileService_CreateOrUpdateSpecificProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID, UserUUID: userUUID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.CreateOrUpdateSpecificProfile(context.Background(), profileUUID, userUUID, "John", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, badMeta)
 		require.Error(t, err)
 	})
@@ -347,7 +347,7 @@ func TestProfileService_CreateOrUpdateSpecificProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID, UserUUID: userUUID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.CreateOrUpdateSpecificProfile(context.Background(), profileUUID, userUUID, "John", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "does not belong")
@@ -367,7 +367,7 @@ func TestProfileService_CreateOrUpdateSpecificProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID, UserUUID: userUUID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.CreateOrUpdateSpecificProfile(context.Background(), profileUUID, userUUID, "Jane", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "update failed")
@@ -384,7 +384,7 @@ func TestProfileService_CreateOrUpdateSpecificProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID, UserUUID: userUUID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		res, err := svc.CreateOrUpdateSpecificProfile(context.Background(), profileUUID, userUUID, "Jane", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.NoError(t, err)
 		assert.Equal(t, "Jane", res.FirstName)
@@ -637,7 +637,7 @@ func TestProfileService_SetDefaultProfile(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewProfileService(db, &mockProfileRepo{}, &mockUserRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return nil, nil },
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.SetDefaultProfile(context.Background(), profileUUID, userUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "user not found")
@@ -653,7 +653,7 @@ func TestProfileService_SetDefaultProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.SetDefaultProfile(context.Background(), profileUUID, userUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "profile not found")
@@ -671,7 +671,7 @@ func TestProfileService_SetDefaultProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.SetDefaultProfile(context.Background(), profileUUID, userUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "find error")
@@ -689,7 +689,7 @@ func TestProfileService_SetDefaultProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.SetDefaultProfile(context.Background(), profileUUID, userUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "does not belong")
@@ -708,7 +708,7 @@ func TestProfileService_SetDefaultProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.SetDefaultProfile(context.Background(), profileUUID, userUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "unset error")
@@ -729,7 +729,7 @@ func TestProfileService_SetDefaultProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		_, err := svc.SetDefaultProfile(context.Background(), profileUUID, userUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "save error")
@@ -747,7 +747,7 @@ func TestProfileService_SetDefaultProfile(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return &model.User{UserID: userID}, nil
 			},
-		})
+		}, ProfilePictureConfig{})
 		res, err := svc.SetDefaultProfile(context.Background(), profileUUID, userUUID)
 		require.NoError(t, err)
 		assert.True(t, res.IsDefault)
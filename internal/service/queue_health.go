@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/metrics"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// QueueWebhookDeliveries is the queue of pending webhook deliveries.
+const QueueWebhookDeliveries = "webhook_deliveries"
+
+// EventRelayQueue names the queue of events the named relay has not
+// published yet.
+func EventRelayQueue(relay string) string {
+	return "event_relay." + relay
+}
+
+// QueueThreshold is the level at which a queue is reported as alerting. A
+// zero field disables that check.
+type QueueThreshold struct {
+	MaxDepth     int64
+	MaxOldestAge time.Duration
+}
+
+// QueueThresholds holds the alert threshold of every queue. Webhook
+// deliveries legitimately stay pending while a failing endpoint backs off,
+// so their age threshold is looser than the relays'.
+var QueueThresholds = map[string]QueueThreshold{
+	QueueWebhookDeliveries:            {MaxDepth: 1000, MaxOldestAge: time.Hour},
+	EventRelayQueue(EventRelayBus):    {MaxDepth: 1000, MaxOldestAge: 5 * time.Minute},
+	EventRelayQueue(EventRelayStream): {MaxDepth: 10000, MaxOldestAge: 15 * time.Minute},
+}
+
+// WorkerMaxConsecutiveFailures is how many runs in a row a background worker
+// may fail before it is reported as alerting.
+var WorkerMaxConsecutiveFailures int64 = 3
+
+// QueueStatus is the backlog of a queue at the time it was sampled.
+type QueueStatus struct {
+	Queue     string
+	Depth     int64
+	OldestAt  *time.Time
+	OldestAge time.Duration
+	Threshold QueueThreshold
+	Alerting  bool
+}
+
+// WorkerHealth is the status of a background worker in this instance.
+type WorkerHealth struct {
+	metrics.WorkerStatus
+	Alerting bool
+}
+
+// QueueHealthResult reports every background worker and queue. Alerting is
+// set when any of them is.
+type QueueHealthResult struct {
+	Workers  []WorkerHealth
+	Queues   []QueueStatus
+	Alerting bool
+}
+
+// QueueHealthService measures the queues drained by background workers.
+type QueueHealthService interface {
+	// Sample measures every queue and records it in the queue metrics.
+	Sample(ctx context.Context) error
+	// GetHealth samples the queues and reports them together with the
+	// background workers of this instance.
+	GetHealth(ctx context.Context) (*QueueHealthResult, error)
+}
+
+type queueHealthService struct {
+	webhookDeliveryRepo repository.WebhookDeliveryRepository
+	eventRepo           repository.EventRepository
+	cursorRepo          repository.EventRelayCursorRepository
+	relays              []string
+}
+
+// NewQueueHealthService creates a new QueueHealthService reporting the
+// backlog of the named event relays.
+func NewQueueHealthService(
+	webhookDeliveryRepo repository.WebhookDeliveryRepository,
+	eventRepo repository.EventRepository,
+	cursorRepo repository.EventRelayCursorRepository,
+	relays []string,
+) QueueHealthService {
+	return &queueHealthService{
+		webhookDeliveryRepo: webhookDeliveryRepo,
+		eventRepo:           eventRepo,
+		cursorRepo:          cursorRepo,
+		relays:              relays,
+	}
+}
+
+func (s *queueHealthService) Sample(ctx context.Context) error {
+	_, err := s.sample(ctx)
+	return err
+}
+
+func (s *queueHealthService) GetHealth(ctx context.Context) (*QueueHealthResult, error) {
+	queues, err := s.sample(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &QueueHealthResult{Queues: queues}
+	for _, q := range queues {
+		result.Alerting = result.Alerting || q.Alerting
+	}
+	for _, w := range metrics.Workers() {
+		alerting := WorkerMaxConsecutiveFailures > 0 && w.ConsecutiveFailures >= WorkerMaxConsecutiveFailures
+		result.Workers = append(result.Workers, WorkerHealth{WorkerStatus: w, Alerting: alerting})
+		result.Alerting = result.Alerting || alerting
+	}
+	return result, nil
+}
+
+// sample measures every queue, checks it against its threshold and records
+// it in the queue metrics.
+func (s *queueHealthService) sample(ctx context.Context) ([]QueueStatus, error) {
+	_, span := otel.Tracer("service").Start(ctx, "queueHealth.sample")
+	defer span.End()
+
+	now := time.Now()
+	queues := make([]QueueStatus, 0, len(s.relays)+1)
+
+	backlog, err := s.webhookDeliveryRepo.PendingBacklog()
+	if err != nil {
+		span.SetStatus(codes.Error, "count webhook deliveries failed")
+		return nil, apperror.NewInternal("failed to count pending webhook deliveries", err)
+	}
+	queues = append(queues, newQueueStatus(QueueWebhookDeliveries, backlog, now))
+
+	for _, relay := range s.relays {
+		var afterID int64
+		cursor, err := s.cursorRepo.FindByName(relay)
+		if err != nil {
+			span.SetStatus(codes.Error, "find relay cursor failed")
+			return nil, apperror.NewInternal("failed to find event relay cursor", err)
+		}
+		if cursor != nil {
+			afterID = cursor.LastEventID
+		}
+		backlog, err := s.eventRepo.BacklogAfter(afterID)
+		if err != nil {
+			span.SetStatus(codes.Error, "count relay backlog failed")
+			return nil, apperror.NewInternal("failed to count unpublished events", err)
+		}
+		queues = append(queues, newQueueStatus(EventRelayQueue(relay), backlog, now))
+	}
+
+	for _, q := range queues {
+		metrics.SetQueue(q.Queue, q.Depth, q.OldestAge, q.Alerting)
+	}
+	return queues, nil
+}
+
+func newQueueStatus(queue string, b *repository.Backlog, now time.Time) QueueStatus {
+	q := QueueStatus{
+		Queue:     queue,
+		Depth:     b.Depth,
+		OldestAt:  b.OldestAt,
+		Threshold: QueueThresholds[queue],
+	}
+	if b.OldestAt != nil && b.Depth > 0 {
+		q.OldestAge = max(now.Sub(*b.OldestAt), 0)
+	}
+	q.Alerting = (q.Threshold.MaxDepth > 0 && q.Depth >= q.Threshold.MaxDepth) ||
+		(q.Threshold.MaxOldestAge > 0 && q.OldestAge >= q.Threshold.MaxOldestAge)
+	return q
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/metrics"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueHealthService_GetHealth(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reports queues against their thresholds", func(t *testing.T) {
+		oldDelivery := time.Now().Add(-2 * time.Hour)
+		oldEvent := time.Now().Add(-10 * time.Second)
+		svc := NewQueueHealthService(
+			&mockWebhookDeliveryRepo{pendingBacklogFn: func() (*repository.Backlog, error) {
+				return &repository.Backlog{Depth: 4, OldestAt: &oldDelivery}, nil
+			}},
+			&mockEventRepo{backlogFn: func(afterID int64) (*repository.Backlog, error) {
+				assert.Equal(t, int64(41), afterID)
+				return &repository.Backlog{Depth: 2, OldestAt: &oldEvent}, nil
+			}},
+			&mockEventRelayCursorRepo{findByNameFn: func(name string) (*model.EventRelayCursor, error) {
+				assert.Equal(t, EventRelayBus, name)
+				return &model.EventRelayCursor{Name: name, LastEventID: 41}, nil
+			}},
+			[]string{EventRelayBus},
+		)
+
+		result, err := svc.GetHealth(ctx)
+		require.NoError(t, err)
+		require.Len(t, result.Queues, 2)
+
+		webhooks := result.Queues[0]
+		assert.Equal(t, QueueWebhookDeliveries, webhooks.Queue)
+		assert.Equal(t, int64(4), webhooks.Depth)
+		assert.GreaterOrEqual(t, webhooks.OldestAge, 2*time.Hour)
+		assert.True(t, webhooks.Alerting, "oldest delivery is past the age threshold")
+
+		relay := result.Queues[1]
+		assert.Equal(t, EventRelayQueue(EventRelayBus), relay.Queue)
+		assert.Equal(t, int64(2), relay.Depth)
+		assert.False(t, relay.Alerting)
+		assert.True(t, result.Alerting)
+
+		assert.Equal(t, float64(4), testutil.ToFloat64(metrics.QueueDepth.WithLabelValues(QueueWebhookDeliveries)))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.QueueAlerting.WithLabelValues(QueueWebhookDeliveries)))
+		assert.Equal(t, float64(0), testutil.ToFloat64(metrics.QueueAlerting.WithLabelValues(EventRelayQueue(EventRelayBus))))
+	})
+
+	t.Run("relay that has not run counts every event", func(t *testing.T) {
+		var gotAfter int64 = -1
+		svc := NewQueueHealthService(&mockWebhookDeliveryRepo{},
+			&mockEventRepo{backlogFn: func(afterID int64) (*repository.Backlog, error) {
+				gotAfter = afterID
+				return &repository.Backlog{}, nil
+			}},
+			&mockEventRelayCursorRepo{},
+			[]string{EventRelayStream},
+		)
+
+		result, err := svc.GetHealth(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, gotAfter)
+		assert.Zero(t, result.Queues[1].OldestAge)
+	})
+
+	t.Run("worker failing repeatedly is alerting", func(t *testing.T) {
+		const worker = "queue_health_test_worker"
+		for range WorkerMaxConsecutiveFailures {
+			metrics.ObserveWorkerRun(worker, 0, errors.New("db down"), time.Millisecond)
+		}
+		svc := NewQueueHealthService(&mockWebhookDeliveryRepo{}, &mockEventRepo{}, &mockEventRelayCursorRepo{}, nil)
+
+		result, err := svc.GetHealth(ctx)
+		require.NoError(t, err)
+		var found bool
+		for _, w := range result.Workers {
+			if w.Worker == worker {
+				found = true
+				assert.True(t, w.Alerting)
+			}
+		}
+		assert.True(t, found)
+		assert.True(t, result.Alerting)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		svc := NewQueueHealthService(
+			&mockWebhookDeliveryRepo{pendingBacklogFn: func() (*repository.Backlog, error) {
+				return nil, errors.New("db down")
+			}},
+			&mockEventRepo{}, &mockEventRelayCursorRepo{}, nil,
+		)
+
+		_, err := svc.GetHealth(ctx)
+		var internalErr *apperror.InternalError
+		assert.ErrorAs(t, err, &internalErr)
+	})
+}
+
+func TestNewQueueStatus_DepthThreshold(t *testing.T) {
+	q := newQueueStatus(EventRelayQueue(EventRelayBus), &repository.Backlog{Depth: 1000}, time.Now())
+	assert.True(t, q.Alerting)
+
+	q = newQueueStatus("unknown", &repository.Backlog{Depth: 1 << 20}, time.Now())
+	assert.False(t, q.Alerting, "queues without a threshold never alert")
+}
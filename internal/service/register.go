@@ -2,17 +2,23 @@ package service
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/claims"
 	"github.com/maintainerd/auth/internal/crypto"
 	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/hook"
 	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/metrics"
+	"github.com/maintainerd/auth/internal/middleware"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/ptr"
 	"github.com/maintainerd/auth/internal/repository"
 	"github.com/maintainerd/auth/internal/security"
+	"github.com/maintainerd/auth/internal/signupflow"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -21,10 +27,11 @@ import (
 )
 
 type RegisterService interface {
-	RegisterPublic(ctx context.Context, username, fullname, password string, email, phone *string, clientID, providerID string) (*dto.RegisterResponseDTO, error)
-	RegisterInvitePublic(ctx context.Context, username, password, clientID, providerID, inviteToken string) (*dto.RegisterResponseDTO, error)
-	Register(ctx context.Context, username, fullname, password string, email, phone *string, clientID, providerID *string) (*dto.RegisterResponseDTO, error)
-	RegisterInvite(ctx context.Context, username, password, inviteToken string, clientID, providerID *string) (*dto.RegisterResponseDTO, error)
+	RegisterPublic(ctx context.Context, username, fullname, password string, email, phone *string, clientID, providerID string, flow SignupFlowInput, acceptedPolicies []uuid.UUID) (*dto.RegisterResponseDTO, error)
+	VerifyEmail(ctx context.Context, email, code, clientID, providerID string) (*dto.RegisterResponseDTO, error)
+	RegisterInvitePublic(ctx context.Context, username, password, clientID, providerID, inviteToken string, acceptedPolicies []uuid.UUID) (*dto.RegisterResponseDTO, error)
+	Register(ctx context.Context, username, fullname, password string, email, phone *string, clientID, providerID *string, acceptedPolicies []uuid.UUID) (*dto.RegisterResponseDTO, error)
+	RegisterInvite(ctx context.Context, username, password, inviteToken string, clientID, providerID *string, acceptedPolicies []uuid.UUID) (*dto.RegisterResponseDTO, error)
 }
 
 type registerService struct {
@@ -37,6 +44,21 @@ type registerService struct {
 	roleRepo             repository.RoleRepository
 	inviteRepo           repository.InviteRepository
 	identityProviderRepo repository.IdentityProviderRepository
+	eventRepo            repository.EventRepository
+	tenantSettingRepo    repository.TenantSettingRepository
+	securitySettingRepo  repository.SecuritySettingRepository
+	signupFlowRepo       repository.SignupFlowRepository
+	signupFlowRoleRepo   repository.SignupFlowRoleRepository
+	signupFlowSignupRepo repository.SignupFlowSignupRepository
+	emailTemplateRepo    repository.EmailTemplateRepository
+	breachChecker        security.BreachedPasswordChecker
+	captchaVerifier      signupflow.CaptchaVerifier
+	loginHookService     LoginHookService
+	claimsFetcher        claims.Fetcher
+	planEnforcer         PlanEnforcer
+	policyDocumentRepo   repository.PolicyDocumentRepository
+	parentalConsentRepo  repository.ParentalConsentRepository
+	profileRepo          repository.ProfileRepository
 }
 
 func NewRegistrationService(
@@ -49,6 +71,21 @@ func NewRegistrationService(
 	roleRepo repository.RoleRepository,
 	inviteRepo repository.InviteRepository,
 	identityProviderRepo repository.IdentityProviderRepository,
+	eventRepo repository.EventRepository,
+	tenantSettingRepo repository.TenantSettingRepository,
+	securitySettingRepo repository.SecuritySettingRepository,
+	signupFlowRepo repository.SignupFlowRepository,
+	signupFlowRoleRepo repository.SignupFlowRoleRepository,
+	signupFlowSignupRepo repository.SignupFlowSignupRepository,
+	emailTemplateRepo repository.EmailTemplateRepository,
+	breachChecker security.BreachedPasswordChecker,
+	captchaVerifier signupflow.CaptchaVerifier,
+	loginHookService LoginHookService,
+	claimsFetcher claims.Fetcher,
+	planEnforcer PlanEnforcer,
+	policyDocumentRepo repository.PolicyDocumentRepository,
+	parentalConsentRepo repository.ParentalConsentRepository,
+	profileRepo repository.ProfileRepository,
 ) RegisterService {
 	return &registerService{
 		db:                   db,
@@ -60,6 +97,21 @@ func NewRegistrationService(
 		roleRepo:             roleRepo,
 		inviteRepo:           inviteRepo,
 		identityProviderRepo: identityProviderRepo,
+		eventRepo:            eventRepo,
+		tenantSettingRepo:    tenantSettingRepo,
+		securitySettingRepo:  securitySettingRepo,
+		signupFlowRepo:       signupFlowRepo,
+		signupFlowRoleRepo:   signupFlowRoleRepo,
+		signupFlowSignupRepo: signupFlowSignupRepo,
+		emailTemplateRepo:    emailTemplateRepo,
+		breachChecker:        breachChecker,
+		captchaVerifier:      captchaVerifier,
+		loginHookService:     loginHookService,
+		claimsFetcher:        claimsFetcher,
+		planEnforcer:         planEnforcer,
+		policyDocumentRepo:   policyDocumentRepo,
+		parentalConsentRepo:  parentalConsentRepo,
+		profileRepo:          profileRepo,
 	}
 }
 
@@ -94,6 +146,28 @@ func (s *registerService) findDefaultRole(roleRepo repository.RoleRepository, te
 	return role, nil
 }
 
+// runPreRegistrationHooks runs the tenant's pre-registration hooks for a user
+// about to be created. It is a no-op when no hook service is configured.
+func (s *registerService) runPreRegistrationHooks(ctx context.Context, tenantID int64, client *model.Client, user hook.User) error {
+	if s.loginHookService == nil {
+		return nil
+	}
+
+	_, err := s.loginHookService.Run(ctx, tenantID, nil, hook.Payload{
+		Trigger: model.LoginHookTriggerPreRegistration,
+		User:    user,
+		Client: hook.Client{
+			ClientID: ptr.Deref(client.Identifier),
+			Name:     client.Name,
+		},
+		Request: hook.Request{
+			IPAddress: middleware.ClientIPFromContext(ctx),
+			UserAgent: middleware.UserAgentFromContext(ctx),
+		},
+	})
+	return err
+}
+
 // RegisterPublic registers new users for public-facing applications.
 // Requires clientID and providerID to identify the auth client.
 // Used by external applications on port 8081.
@@ -106,13 +180,17 @@ func (s *registerService) RegisterPublic(
 	phone *string,
 	clientID,
 	providerID string,
+	flow SignupFlowInput,
+	acceptedPolicies []uuid.UUID,
 ) (*dto.RegisterResponseDTO, error) {
 	_, span := otel.Tracer("service").Start(ctx, "register.public")
 	defer span.End()
 	span.SetAttributes(attribute.String("client.id", clientID), attribute.String("provider.id", providerID))
+	startTime := time.Now()
 
 	// Rate limiting check to prevent registration abuse
 	if err := security.CheckRateLimit(username); err != nil {
+		metrics.ObserveRateLimitHit("register")
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "register public failed")
 		return nil, err
@@ -121,6 +199,10 @@ func (s *registerService) RegisterPublic(
 	var createdUser *model.User
 	var Client *model.Client
 	var userIdentitySub string
+	var plan *signupPlan
+	var verificationCode string
+	var consentToken string
+	var safe bool
 
 	// All database operations in transaction
 	err := s.db.Transaction(func(tx *gorm.DB) error {
@@ -156,13 +238,27 @@ func (s *registerService) RegisterPublic(
 
 		tenantId := identityProvider.TenantID
 
+		// Apply the client's signup flow before touching any account data
+		plan, txErr = s.evaluateSignupFlow(ctx, s.signupFlowRepo.WithTx(tx), Client, flow, signupflow.Input{
+			Fullname:      fullname,
+			Email:         ptr.Deref(email),
+			Phone:         ptr.Deref(phone),
+			Birthdate:     flow.Birthdate,
+			GuardianEmail: flow.GuardianEmail,
+		})
+		if txErr != nil {
+			return txErr
+		}
+
+		safe = enumerationSafe(s.tenantSettingRepo.WithTx(tx), tenantId)
+
 		// Check if username already exists
 		existingUser, txErr := txUserRepo.FindByUsername(username)
 		if txErr != nil {
 			return txErr
 		}
 		if existingUser != nil {
-			return apperror.NewConflict("username already taken")
+			return accountConflict(safe, "username already taken")
 		}
 
 		// Check if email already exists (if provided)
@@ -172,7 +268,7 @@ func (s *registerService) RegisterPublic(
 				return txErr
 			}
 			if existingEmailUser != nil {
-				return apperror.NewConflict("email already registered")
+				return accountConflict(safe, "email already registered")
 			}
 		}
 
@@ -183,12 +279,33 @@ func (s *registerService) RegisterPublic(
 				return txErr
 			}
 			if existingPhoneUser != nil {
-				return apperror.NewConflict("phone number already registered")
+				return accountConflict(safe, "phone number already registered")
 			}
 		}
 
+		// Reject breached passwords when the tenant opts in
+		if txErr := ensurePasswordNotBreached(ctx, s.tenantSettingRepo.WithTx(tx), s.breachChecker, tenantId, password); txErr != nil {
+			return txErr
+		}
+
+		// Stay within the user limit of the tenant's plan
+		if txErr := s.planEnforcer.CheckUserLimit(ctx, tenantId); txErr != nil {
+			return txErr
+		}
+
+		// Tenant pre-registration hooks may deny the sign-up
+		if txErr := s.runPreRegistrationHooks(ctx, tenantId, Client, hook.User{Username: username, Email: ptr.Deref(email), Phone: ptr.Deref(phone)}); txErr != nil {
+			return txErr
+		}
+
+		// New accounts must accept the current policy documents
+		policies, txErr := checkPolicyAcceptance(s.policyDocumentRepo, tenantId, nil, acceptedPolicies)
+		if txErr != nil {
+			return txErr
+		}
+
 		// Hash password
-		hashed, txErr := security.HashPassword([]byte(password))
+		hashed, txErr := hashTenantPassword(s.securitySettingRepo.WithTx(tx), tenantId, password)
 		if txErr != nil {
 			return txErr
 		}
@@ -201,6 +318,14 @@ func (s *registerService) RegisterPublic(
 			Status:   model.StatusActive,
 		}
 
+		// Accounts stay pending until the email address is verified, and
+		// under-age accounts until their guardian consents
+		if plan.config.EmailVerification {
+			newUser.Status = model.StatusPending
+		} else if plan.parentalConsent {
+			newUser.Status = model.StatusPendingConsent
+		}
+
 		// Set email if provided
 		if email != nil && *email != "" {
 			newUser.Email = *email
@@ -216,6 +341,13 @@ func (s *registerService) RegisterPublic(
 			return txErr
 		}
 
+		// Record the policy documents accepted with the sign-up
+		if len(policies) > 0 {
+			if txErr = s.policyDocumentRepo.WithTx(tx).Accept(newPolicyAcceptances(ctx, tenantId, createdUser.UserID, policies)); txErr != nil {
+				return txErr
+			}
+		}
+
 		// Create user identity
 		userIdentity := &model.UserIdentity{
 			UserID:   createdUser.UserID,
@@ -230,18 +362,52 @@ func (s *registerService) RegisterPublic(
 			return txErr
 		}
 
-		// Get default role
-		defaultRole, txErr := s.findDefaultRole(txRoleRepo, tenantId)
+		// Keep the birthdate on the user's profile
+		if flow.Birthdate != nil {
+			if _, txErr = s.profileRepo.WithTx(tx).Create(&model.Profile{
+				UserID:    createdUser.UserID,
+				FirstName: fullname,
+				IsDefault: true,
+				Birthdate: flow.Birthdate,
+				Metadata:  datatypes.JSON([]byte(`{}`)),
+			}); txErr != nil {
+				return txErr
+			}
+		}
+
+		// Ask the guardian of an under-age user for consent
+		if plan.parentalConsent {
+			consentToken, txErr = requestParentalConsent(s.parentalConsentRepo.WithTx(tx), tenantId, createdUser.UserID, flow.GuardianEmail)
+			if txErr != nil {
+				return txErr
+			}
+		}
+
+		// Count the account towards the flow's caps
+		txErr = s.recordFlowSignup(s.signupFlowRepo.WithTx(tx), s.signupFlowSignupRepo.WithTx(tx), s.eventRepo.WithTx(tx), plan, createdUser.UserID)
 		if txErr != nil {
 			return txErr
 		}
 
-		// Assign default role to user
-		userRole := &model.UserRole{
-			UserID: createdUser.UserID,
-			RoleID: defaultRole.RoleID,
+		// Assign the signup flow's roles, or the tenant default role
+		roles, txErr := s.signupRoles(txRoleRepo, s.signupFlowRoleRepo.WithTx(tx), plan, tenantId)
+		if txErr != nil {
+			return txErr
 		}
-		_, txErr = txUserRoleRepo.Create(userRole)
+		roleUUIDs := make([]uuid.UUID, 0, len(roles))
+		for _, role := range roles {
+			userRole := &model.UserRole{
+				UserID: createdUser.UserID,
+				RoleID: role.RoleID,
+			}
+			if _, txErr = txUserRoleRepo.Create(userRole); txErr != nil {
+				return txErr
+			}
+			roleUUIDs = append(roleUUIDs, role.RoleUUID)
+		}
+
+		// Record lifecycle events
+		txErr = recordUserCreatedEvents(s.eventRepo.WithTx(tx), tenantId, createdUser, roleUUIDs)
 		if txErr != nil {
 			return txErr
 		}
@@ -257,24 +423,47 @@ func (s *registerService) RegisterPublic(
 			UserID:    createdUser.UserID,
 			TokenType: model.TokenTypeEmailVerification,
 			Token:     otp,
+			ExpiresAt: ptr.TimePtr(time.Now().Add(emailVerificationTTL)),
 		}
 		_, txErr = txUserTokenRepo.Create(userToken)
 		if txErr != nil {
 			return txErr
 		}
+		verificationCode = otp
 
 		return nil // commit transaction
 	})
 
+	if errors.Is(err, errAccountExists) {
+		span.SetStatus(codes.Ok, "")
+		return s.registerExistingAccount(ctx, plan, password, startTime)
+	}
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "register public failed")
 		return nil, err
 	}
 
+	// Pending accounts get their verification code, and under-age accounts
+	// a consent request to their guardian, instead of tokens
+	if plan.config.EmailVerification || plan.parentalConsent {
+		if safe {
+			// Answer in the same time as for an existing account
+			go s.sendPendingAccountEmails(context.WithoutCancel(ctx), plan, createdUser, flow.GuardianEmail, verificationCode, consentToken)
+			padResponseTime(ctx, startTime)
+		} else {
+			s.sendPendingAccountEmails(ctx, plan, createdUser, flow.GuardianEmail, verificationCode, consentToken)
+		}
+		span.SetStatus(codes.Ok, "")
+		return &dto.RegisterResponseDTO{
+			VerificationRequired:    plan.config.EmailVerification,
+			ParentalConsentRequired: plan.parentalConsent,
+		}, nil
+	}
+
 	span.SetStatus(codes.Ok, "")
 	// Return token response
-	return s.generateTokenResponse(userIdentitySub, createdUser, Client)
+	return s.generateTokenResponse(ctx, userIdentitySub, createdUser, Client)
 }
 
 // Register registers new users for internal applications.
@@ -290,12 +479,14 @@ func (s *registerService) Register(
 	phone *string,
 	clientID,
 	providerID *string,
+	acceptedPolicies []uuid.UUID,
 ) (*dto.RegisterResponseDTO, error) {
 	_, span := otel.Tracer("service").Start(ctx, "register.internal")
 	defer span.End()
 
 	// Rate limiting check to prevent registration abuse
 	if err := security.CheckRateLimit(username); err != nil {
+		metrics.ObserveRateLimitHit("register")
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "register failed")
 		return nil, err
@@ -345,11 +536,32 @@ func (s *registerService) Register(
 			return txErr
 		}
 		if existingUser != nil {
-			return apperror.NewConflict("user already exists")
+			return apperror.WithCode(apperror.CodeUserAlreadyExists, apperror.NewConflict("user already exists"))
+		}
+
+		// Reject breached passwords when the tenant opts in
+		if txErr := ensurePasswordNotBreached(ctx, s.tenantSettingRepo.WithTx(tx), s.breachChecker, tenantId, password); txErr != nil {
+			return txErr
+		}
+
+		// Stay within the user limit of the tenant's plan
+		if txErr := s.planEnforcer.CheckUserLimit(ctx, tenantId); txErr != nil {
+			return txErr
+		}
+
+		// Tenant pre-registration hooks may deny the sign-up
+		if txErr := s.runPreRegistrationHooks(ctx, tenantId, Client, hook.User{Username: username, Email: ptr.Deref(email), Phone: ptr.Deref(phone)}); txErr != nil {
+			return txErr
+		}
+
+		// New accounts must accept the current policy documents
+		policies, txErr := checkPolicyAcceptance(s.policyDocumentRepo, tenantId, nil, acceptedPolicies)
+		if txErr != nil {
+			return txErr
 		}
 
 		// Hash password
-		hashed, txErr := security.HashPassword([]byte(password))
+		hashed, txErr := hashTenantPassword(s.securitySettingRepo.WithTx(tx), tenantId, password)
 		if txErr != nil {
 			return txErr
 		}
@@ -377,6 +589,13 @@ func (s *registerService) Register(
 			return txErr
 		}
 
+		// Record the policy documents accepted with the sign-up
+		if len(policies) > 0 {
+			if txErr = s.policyDocumentRepo.WithTx(tx).Accept(newPolicyAcceptances(ctx, tenantId, createdUser.UserID, policies)); txErr != nil {
+				return txErr
+			}
+		}
+
 		// Create user identity
 		userIdentity := &model.UserIdentity{
 			TenantID: tenantId,
@@ -409,6 +628,12 @@ func (s *registerService) Register(
 			return txErr
 		}
 
+		// Record lifecycle events
+		txErr = recordUserCreatedEvents(s.eventRepo.WithTx(tx), tenantId, createdUser, []uuid.UUID{defaultRole.RoleUUID})
+		if txErr != nil {
+			return txErr
+		}
+
 		// Generate OTP
 		otp, txErr := crypto.GenerateOTP(6)
 		if txErr != nil {
@@ -437,7 +662,7 @@ func (s *registerService) Register(
 
 	span.SetStatus(codes.Ok, "")
 	// Return token response
-	return s.generateTokenResponse(userIdentitySub, createdUser, Client)
+	return s.generateTokenResponse(ctx, userIdentitySub, createdUser, Client)
 }
 
 // RegisterInvite registers new users via invite token for internal applications.
@@ -451,6 +676,7 @@ func (s *registerService) RegisterInvite(
 	inviteToken string,
 	clientID,
 	providerID *string,
+	acceptedPolicies []uuid.UUID,
 ) (*dto.RegisterResponseDTO, error) {
 	_, span := otel.Tracer("service").Start(ctx, "register.invite")
 	defer span.End()
@@ -496,10 +722,10 @@ func (s *registerService) RegisterInvite(
 		// Validate invite token
 		invite, txErr := txInviteRepo.FindByToken(inviteToken)
 		if txErr != nil {
-			return apperror.NewUnauthorized("invalid invite token")
+			return apperror.WithCode(apperror.CodeInvalidInvite, apperror.NewUnauthorized("invalid invite token"))
 		}
 		if invite == nil || invite.Status != model.StatusPending || (invite.ExpiresAt != nil && invite.ExpiresAt.Before(time.Now())) {
-			return apperror.NewUnauthorized("invite token is invalid or expired")
+			return apperror.WithCode(apperror.CodeInvalidInvite, apperror.NewUnauthorized("invite token is invalid or expired"))
 		}
 
 		// Check if user already exists
@@ -508,11 +734,38 @@ func (s *registerService) RegisterInvite(
 			return txErr
 		}
 		if existingUser != nil {
-			return apperror.NewConflict("user already exists")
+			return apperror.WithCode(apperror.CodeUserAlreadyExists, apperror.NewConflict("user already exists"))
+		}
+
+		// Invitations count towards the client's signup caps when the flow says so
+		plan, txErr := s.inviteSignupPlan(s.signupFlowRepo.WithTx(tx), Client.ClientID)
+		if txErr != nil {
+			return txErr
+		}
+
+		// Reject breached passwords when the tenant opts in
+		if txErr := ensurePasswordNotBreached(ctx, s.tenantSettingRepo.WithTx(tx), s.breachChecker, tenantId, password); txErr != nil {
+			return txErr
+		}
+
+		// Stay within the user limit of the tenant's plan
+		if txErr := s.planEnforcer.CheckUserLimit(ctx, tenantId); txErr != nil {
+			return txErr
+		}
+
+		// Tenant pre-registration hooks may deny the sign-up
+		if txErr := s.runPreRegistrationHooks(ctx, tenantId, Client, hook.User{Username: username, Email: invite.InvitedEmail}); txErr != nil {
+			return txErr
+		}
+
+		// New accounts must accept the current policy documents
+		policies, txErr := checkPolicyAcceptance(s.policyDocumentRepo, tenantId, nil, acceptedPolicies)
+		if txErr != nil {
+			return txErr
 		}
 
 		// Hash password
-		hashed, txErr := security.HashPassword([]byte(password))
+		hashed, txErr := hashTenantPassword(s.securitySettingRepo.WithTx(tx), tenantId, password)
 		if txErr != nil {
 			return txErr
 		}
@@ -531,6 +784,13 @@ func (s *registerService) RegisterInvite(
 			return txErr
 		}
 
+		// Record the policy documents accepted with the sign-up
+		if len(policies) > 0 {
+			if txErr = s.policyDocumentRepo.WithTx(tx).Accept(newPolicyAcceptances(ctx, tenantId, createdUser.UserID, policies)); txErr != nil {
+				return txErr
+			}
+		}
+
 		// Create user identity
 		userIdentity := &model.UserIdentity{
 			TenantID: tenantId,
@@ -546,6 +806,10 @@ func (s *registerService) RegisterInvite(
 			return txErr
 		}
 
+		txErr = s.recordFlowSignup(s.signupFlowRepo.WithTx(tx), s.signupFlowSignupRepo.WithTx(tx), s.eventRepo.WithTx(tx), plan, createdUser.UserID)
+		if txErr != nil {
+			return txErr
+		}
 
 		// Get default role and assign it first
 		defaultRole, txErr := s.findDefaultRole(txRoleRepo, tenantId)
@@ -575,6 +839,18 @@ func (s *registerService) RegisterInvite(
 			}
 		}
 
+		// Record lifecycle events
+		roleUUIDs := []uuid.UUID{defaultRole.RoleUUID}
+		for _, role := range invite.Roles {
+			if role.RoleID != defaultRole.RoleID {
+				roleUUIDs = append(roleUUIDs, role.RoleUUID)
+			}
+		}
+		txErr = recordUserCreatedEvents(s.eventRepo.WithTx(tx), tenantId, createdUser, roleUUIDs)
+		if txErr != nil {
+			return txErr
+		}
+
 		// Mark invite as used
 		txErr = txInviteRepo.MarkAsUsed(invite.InviteUUID)
 		if txErr != nil {
@@ -592,7 +868,7 @@ func (s *registerService) RegisterInvite(
 
 	span.SetStatus(codes.Ok, "")
 	// Return token response
-	return s.generateTokenResponse(userIdentitySub, createdUser, Client)
+	return s.generateTokenResponse(ctx, userIdentitySub, createdUser, Client)
 }
 
 // RegisterInvitePublic registers new users via invite token for public-facing applications.
@@ -605,6 +881,7 @@ func (s *registerService) RegisterInvitePublic(
 	clientID,
 	providerID,
 	inviteToken string,
+	acceptedPolicies []uuid.UUID,
 ) (*dto.RegisterResponseDTO, error) {
 	_, span := otel.Tracer("service").Start(ctx, "register.invitePublic")
 	defer span.End()
@@ -651,7 +928,7 @@ func (s *registerService) RegisterInvitePublic(
 		// Validate invite token
 		invite, txErr := txInviteRepo.FindByToken(inviteToken)
 		if txErr != nil {
-			return apperror.NewUnauthorized("invalid invite token")
+			return apperror.WithCode(apperror.CodeInvalidInvite, apperror.NewUnauthorized("invalid invite token"))
 		}
 		if invite == nil {
 			return apperror.NewNotFound("invite not found")
@@ -659,10 +936,10 @@ func (s *registerService) RegisterInvitePublic(
 
 		// Check invite status and expiration
 		if invite.Status != model.StatusPending {
-			return apperror.NewUnauthorized("invite has already been used or is no longer valid")
+			return apperror.WithCode(apperror.CodeInvalidInvite, apperror.NewUnauthorized("invite has already been used or is no longer valid"))
 		}
 		if invite.ExpiresAt != nil && time.Now().After(*invite.ExpiresAt) {
-			return apperror.NewUnauthorized("invite has expired")
+			return apperror.WithCode(apperror.CodeInvalidInvite, apperror.NewUnauthorized("invite has expired"))
 		}
 
 		// Check if username already exists
@@ -671,7 +948,7 @@ func (s *registerService) RegisterInvitePublic(
 			return txErr
 		}
 		if existingUser != nil {
-			return apperror.NewConflict("username already taken")
+			return apperror.WithCode(apperror.CodeUsernameTaken, apperror.NewConflict("username already taken"))
 		}
 
 		// Check if invited email already exists
@@ -683,8 +960,35 @@ func (s *registerService) RegisterInvitePublic(
 			return apperror.NewConflict("invited email already registered")
 		}
 
+		// Invitations count towards the client's signup caps when the flow says so
+		plan, txErr := s.inviteSignupPlan(s.signupFlowRepo.WithTx(tx), Client.ClientID)
+		if txErr != nil {
+			return txErr
+		}
+
+		// Reject breached passwords when the tenant opts in
+		if txErr := ensurePasswordNotBreached(ctx, s.tenantSettingRepo.WithTx(tx), s.breachChecker, tenantId, password); txErr != nil {
+			return txErr
+		}
+
+		// Stay within the user limit of the tenant's plan
+		if txErr := s.planEnforcer.CheckUserLimit(ctx, tenantId); txErr != nil {
+			return txErr
+		}
+
+		// Tenant pre-registration hooks may deny the sign-up
+		if txErr := s.runPreRegistrationHooks(ctx, tenantId, Client, hook.User{Username: username, Email: invite.InvitedEmail, EmailVerified: true}); txErr != nil {
+			return txErr
+		}
+
+		// New accounts must accept the current policy documents
+		policies, txErr := checkPolicyAcceptance(s.policyDocumentRepo, tenantId, nil, acceptedPolicies)
+		if txErr != nil {
+			return txErr
+		}
+
 		// Hash password
-		hashed, txErr := security.HashPassword([]byte(password))
+		hashed, txErr := hashTenantPassword(s.securitySettingRepo.WithTx(tx), tenantId, password)
 		if txErr != nil {
 			return txErr
 		}
@@ -703,6 +1007,13 @@ func (s *registerService) RegisterInvitePublic(
 			return txErr
 		}
 
+		// Record the policy documents accepted with the sign-up
+		if len(policies) > 0 {
+			if txErr = s.policyDocumentRepo.WithTx(tx).Accept(newPolicyAcceptances(ctx, tenantId, createdUser.UserID, policies)); txErr != nil {
+				return txErr
+			}
+		}
+
 		// Create user identity
 		userIdentity := &model.UserIdentity{
 			TenantID: tenantId,
@@ -718,6 +1029,10 @@ func (s *registerService) RegisterInvitePublic(
 			return txErr
 		}
 
+		txErr = s.recordFlowSignup(s.signupFlowRepo.WithTx(tx), s.signupFlowSignupRepo.WithTx(tx), s.eventRepo.WithTx(tx), plan, createdUser.UserID)
+		if txErr != nil {
+			return txErr
+		}
 
 		// Get default role and assign it first
 		defaultRole, txErr := s.findDefaultRole(txRoleRepo, tenantId)
@@ -758,6 +1073,18 @@ func (s *registerService) RegisterInvitePublic(
 			}
 		}
 
+		// Record lifecycle events
+		roleUUIDs := []uuid.UUID{defaultRole.RoleUUID}
+		for _, role := range invite.Roles {
+			if role.RoleID != defaultRole.RoleID {
+				roleUUIDs = append(roleUUIDs, role.RoleUUID)
+			}
+		}
+		txErr = recordUserCreatedEvents(s.eventRepo.WithTx(tx), tenantId, createdUser, roleUUIDs)
+		if txErr != nil {
+			return txErr
+		}
+
 		// Mark invite as used (using repository method)
 		txErr = txInviteRepo.MarkAsUsed(invite.InviteUUID)
 		if txErr != nil {
@@ -775,17 +1102,27 @@ func (s *registerService) RegisterInvitePublic(
 
 	span.SetStatus(codes.Ok, "")
 	// Return token response
-	return s.generateTokenResponse(userIdentitySub, createdUser, Client)
+	return s.generateTokenResponse(ctx, userIdentitySub, createdUser, Client)
 }
 
-func (s *registerService) generateTokenResponse(sub string, user *model.User, Client *model.Client) (*dto.RegisterResponseDTO, error) {
-	accessToken, err := jwt.GenerateAccessToken(
+func (s *registerService) generateTokenResponse(ctx context.Context, sub string, user *model.User, Client *model.Client) (*dto.RegisterResponseDTO, error) {
+	if err := meterTokenIssued(ctx, Client.TenantID); err != nil {
+		return nil, err
+	}
+	extraClaims, err := enrichTokenClaims(ctx, s.claimsFetcher, Client, sub, user, loginTokenScope)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to fetch token claims", err)
+	}
+	sessionClaims := map[string]any{"sid": jwt.GenerateSecureID()}
+
+	accessToken, err := jwt.GenerateAccessTokenWithClaims(
 		sub,
-		"openid profile email",
+		loginTokenScope,
 		*Client.Domain,
 		*Client.Identifier,
 		*Client.Identifier,
 		Client.IdentityProvider.Identifier,
+		mergeClaims(extraClaims, sessionClaims),
 	)
 	if err != nil {
 		return nil, err
@@ -800,7 +1137,7 @@ func (s *registerService) generateTokenResponse(sub string, user *model.User, Cl
 	}
 
 	// Generate ID token with user profile (no nonce for registration flow)
-	idToken, err := jwt.GenerateIDToken(sub, *Client.Domain, *Client.Identifier, Client.IdentityProvider.Identifier, profile, "")
+	idToken, err := generateIDTokenFn(sub, *Client.Domain, *Client.Identifier, Client.IdentityProvider.Identifier, profile, "", sessionClaims)
 	if err != nil {
 		return nil, err
 	}
@@ -234,8 +234,8 @@ func TestRegisterPublic_RateLimited(t *testing.T) {
 	_ = mock
 	m := defaultRegPublicMocks()
 	svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-		m.userIdentity, m.role, m.invite, m.idp)
-	resp, err := svc.RegisterPublic(context.Background(), "ratelimited-user", "F", "P@ss1!", nil, nil, "c", "p")
+		m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+	resp, err := svc.RegisterPublic(context.Background(), "ratelimited-user", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{}, nil)
 	require.Error(t, err)
 	assert.Nil(t, resp)
 	assert.Contains(t, err.Error(), "locked")
@@ -253,8 +253,8 @@ func TestRegister_RateLimited(t *testing.T) {
 	_ = mock
 	m := defaultRegInternalMocks()
 	svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-		m.userIdentity, m.role, m.invite, m.idp)
-	resp, err := svc.Register(context.Background(), "ratelimited-user2", "F", "P@ss1!", nil, nil, nil, nil)
+		m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+	resp, err := svc.Register(context.Background(), "ratelimited-user2", "F", "P@ss1!", nil, nil, nil, nil, nil)
 	require.Error(t, err)
 	assert.Nil(t, resp)
 	assert.Contains(t, err.Error(), "locked")
@@ -274,8 +274,8 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 			return nil, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "invalid or inactive auth client")
@@ -291,8 +291,8 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 			return nil, errors.New("db error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -308,8 +308,8 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 			return &model.Client{Status: model.StatusInactive, Domain: &domain}, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "invalid or inactive auth client")
@@ -325,8 +325,8 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 			return nil, errors.New("db error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "identity provider lookup failed")
@@ -342,8 +342,8 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 			return nil, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "identity provider not found")
@@ -359,8 +359,8 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 			return nil, errors.New("db error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -375,8 +375,8 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 			return &model.User{UserID: 99}, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "username already taken")
@@ -392,9 +392,9 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 			return nil, errors.New("db error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
 		email := "a@b.com"
-		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", &email, nil, "c", "p")
+		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", &email, nil, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -409,9 +409,9 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 			return &model.User{UserID: 99}, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
 		email := "a@b.com"
-		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", &email, nil, "c", "p")
+		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", &email, nil, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "email already registered")
@@ -427,9 +427,9 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 			return nil, errors.New("db error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
 		phone := "+1234567890"
-		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, &phone, "c", "p")
+		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, &phone, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -444,15 +444,34 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 			return &model.User{UserID: 99}, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
 		phone := "+1234567890"
-		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, &phone, "c", "p")
+		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, &phone, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "phone number already registered")
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
+	t.Run("breached password rejected", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		m := defaultRegPublicMocks()
+		m.user.createFn = func(_ *model.User) (*model.User, error) {
+			t.Fatal("user should not be created")
+			return nil, nil
+		}
+		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{},
+			breachFlagSettingRepo(`{"breached_password_check": true}`), &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, &mockBreachChecker{breached: true}, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{}, nil)
+		require.Error(t, err)
+		assert.Nil(t, resp)
+		assert.Contains(t, err.Error(), "data breach")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
 	t.Run("user create error", func(t *testing.T) {
 		gormDB, mock := newMockGormDB(t)
 		mock.ExpectBegin()
@@ -462,8 +481,8 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 			return nil, errors.New("create error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -478,8 +497,8 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 			return nil, errors.New("identity error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -494,8 +513,8 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 			return nil, errors.New("role error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -510,8 +529,8 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 			return nil, errors.New("role assign error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -526,8 +545,8 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 			return nil, errors.New("token error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -542,8 +561,8 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 		mock.ExpectCommit()
 		m := defaultRegPublicMocks()
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterPublic(context.Background(), "u", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -557,8 +576,8 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 		mock.ExpectCommit()
 		m := defaultRegPublicMocks()
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterPublic(context.Background(), "u", "Full Name", "P@ss1!", nil, nil, "c", "p")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterPublic(context.Background(), "u", "Full Name", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -572,27 +591,27 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 		mock.ExpectCommit()
 		m := defaultRegPublicMocks()
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
 		email := "a@b.com"
 		phone := "+1234567890"
-		resp, err := svc.RegisterPublic(context.Background(), "u", "Full Name", "P@ss1!", &email, &phone, "c", "p")
+		resp, err := svc.RegisterPublic(context.Background(), "u", "Full Name", "P@ss1!", &email, &phone, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("HashPassword error", func(t *testing.T) {
-		origHash := security.HashPassword
-		defer func() { security.HashPassword = origHash }()
-		security.HashPassword = func(_ []byte) ([]byte, error) { return nil, errors.New("hash error") }
+		origHash := security.HashPasswordWithParams
+		defer func() { security.HashPasswordWithParams = origHash }()
+		security.HashPasswordWithParams = func(_ []byte, _ security.PasswordHashParams) ([]byte, error) { return nil, errors.New("hash error") }
 
 		gormDB, mock := newMockGormDB(t)
 		mock.ExpectBegin()
 		mock.ExpectRollback()
 		m := defaultRegPublicMocks()
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterPublic(context.Background(), "u", "Full Name", "P@ss1!", nil, nil, "c", "p")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterPublic(context.Background(), "u", "Full Name", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "hash error")
@@ -609,8 +628,8 @@ func TestRegisterService_RegisterPublic(t *testing.T) {
 		mock.ExpectRollback()
 		m := defaultRegPublicMocks()
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterPublic(context.Background(), "u", "Full Name", "P@ss1!", nil, nil, "c", "p")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterPublic(context.Background(), "u", "Full Name", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{}, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "otp error")
@@ -635,8 +654,8 @@ func TestRegisterService_Register(t *testing.T) {
 			return nil, errors.New("db error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "auth client lookup by client_id and provider_id failed")
@@ -652,8 +671,8 @@ func TestRegisterService_Register(t *testing.T) {
 			return nil, errors.New("db error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, nil, nil)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -666,8 +685,8 @@ func TestRegisterService_Register(t *testing.T) {
 		m := defaultRegInternalMocks()
 		m.client.findSystemFn = func() (*model.Client, error) { return nil, nil }
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, nil, nil)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "auth client not found or inactive")
@@ -683,8 +702,8 @@ func TestRegisterService_Register(t *testing.T) {
 			return nil, errors.New("db error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -700,8 +719,8 @@ func TestRegisterService_Register(t *testing.T) {
 			return nil, errors.New("record not found")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid, nil)
 		// tx commits but generateTokenResponse fails (userIdentitySub empty)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -717,8 +736,8 @@ func TestRegisterService_Register(t *testing.T) {
 			return &model.User{UserID: 99}, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "user already exists")
@@ -734,8 +753,8 @@ func TestRegisterService_Register(t *testing.T) {
 			return nil, errors.New("create error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -750,8 +769,8 @@ func TestRegisterService_Register(t *testing.T) {
 			return nil, errors.New("identity error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -765,8 +784,8 @@ func TestRegisterService_Register(t *testing.T) {
 			return nil, errors.New("role error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -781,8 +800,8 @@ func TestRegisterService_Register(t *testing.T) {
 			return nil, errors.New("role assign error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -797,8 +816,8 @@ func TestRegisterService_Register(t *testing.T) {
 			return nil, errors.New("token error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -813,8 +832,8 @@ func TestRegisterService_Register(t *testing.T) {
 		mock.ExpectCommit()
 		m := defaultRegInternalMocks()
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -827,10 +846,10 @@ func TestRegisterService_Register(t *testing.T) {
 		mock.ExpectCommit()
 		m := defaultRegInternalMocks()
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
 		email := "a@b.com"
 		phone := "+1234567890"
-		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", &email, &phone, &cid, &pid)
+		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", &email, &phone, &cid, &pid, nil)
 		// userIdentitySub is never set in tx → generateTokenResponse fails
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -844,8 +863,8 @@ func TestRegisterService_Register(t *testing.T) {
 		mock.ExpectCommit()
 		m := defaultRegInternalMocks()
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, nil, nil)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, nil, nil, nil)
 		// userIdentitySub is never set in tx → generateTokenResponse fails
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -853,17 +872,17 @@ func TestRegisterService_Register(t *testing.T) {
 	})
 
 	t.Run("HashPassword error", func(t *testing.T) {
-		origHash := security.HashPassword
-		defer func() { security.HashPassword = origHash }()
-		security.HashPassword = func(_ []byte) ([]byte, error) { return nil, errors.New("hash error") }
+		origHash := security.HashPasswordWithParams
+		defer func() { security.HashPasswordWithParams = origHash }()
+		security.HashPasswordWithParams = func(_ []byte, _ security.PasswordHashParams) ([]byte, error) { return nil, errors.New("hash error") }
 
 		gormDB, mock := newMockGormDB(t)
 		mock.ExpectBegin()
 		mock.ExpectRollback()
 		m := defaultRegInternalMocks()
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "hash error")
@@ -880,8 +899,8 @@ func TestRegisterService_Register(t *testing.T) {
 		mock.ExpectRollback()
 		m := defaultRegInternalMocks()
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.Register(context.Background(), "u", "F", "P@ss1!", nil, nil, &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "otp error")
@@ -917,8 +936,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 			return nil, errors.New("db error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "auth client lookup by client_id and provider_id failed")
@@ -934,8 +953,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 			return nil, errors.New("db error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", nil, nil)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", nil, nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -948,8 +967,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 		m := defaultRegInternalMocks()
 		m.client.findSystemFn = func() (*model.Client, error) { return nil, nil }
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", nil, nil)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", nil, nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "auth client not found or inactive")
@@ -965,8 +984,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 			return nil, errors.New("db error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "invalid invite token")
@@ -982,8 +1001,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 			return nil, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "invite token is invalid or expired")
@@ -999,8 +1018,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 			return &model.Invite{Status: "accepted"}, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "invite token is invalid or expired")
@@ -1017,8 +1036,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 			return &model.Invite{Status: model.StatusPending, ExpiresAt: &past}, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "invite token is invalid or expired")
@@ -1035,8 +1054,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 			return nil, errors.New("db error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1052,8 +1071,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 			return &model.User{UserID: 99}, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "user already exists")
@@ -1070,8 +1089,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 			return nil, errors.New("create error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1087,8 +1106,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 			return nil, errors.New("identity error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1103,8 +1122,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 			return nil, errors.New("role error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1120,8 +1139,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 			return nil, errors.New("role assign error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1142,8 +1161,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 			return &model.UserRole{}, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1159,8 +1178,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 			return errors.New("mark error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1176,8 +1195,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 		m := defaultRegInternalMocks()
 		m.invite.findByTokenFn = func(_ string) (*model.Invite, error) { return validInvite(), nil }
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1191,8 +1210,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 		m := defaultRegInternalMocks()
 		m.invite.findByTokenFn = func(_ string) (*model.Invite, error) { return validInvite(), nil }
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid, nil)
 		// userIdentitySub is never set in tx → generateTokenResponse fails
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -1207,8 +1226,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 		m := defaultRegInternalMocks()
 		m.invite.findByTokenFn = func(_ string) (*model.Invite, error) { return validInvite(), nil }
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", nil, nil)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", nil, nil, nil)
 		// userIdentitySub is never set in tx → generateTokenResponse fails
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -1216,9 +1235,9 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 	})
 
 	t.Run("HashPassword error", func(t *testing.T) {
-		origHash := security.HashPassword
-		defer func() { security.HashPassword = origHash }()
-		security.HashPassword = func(_ []byte) ([]byte, error) { return nil, errors.New("hash error") }
+		origHash := security.HashPasswordWithParams
+		defer func() { security.HashPasswordWithParams = origHash }()
+		security.HashPasswordWithParams = func(_ []byte, _ security.PasswordHashParams) ([]byte, error) { return nil, errors.New("hash error") }
 
 		gormDB, mock := newMockGormDB(t)
 		mock.ExpectBegin()
@@ -1226,8 +1245,8 @@ func TestRegisterService_RegisterInvite(t *testing.T) {
 		m := defaultRegInternalMocks()
 		m.invite.findByTokenFn = func(_ string) (*model.Invite, error) { return validInvite(), nil }
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid)
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvite(context.Background(), "u", "P@ss1!", "token", &cid, &pid, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "hash error")
@@ -1260,8 +1279,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return nil, errors.New("db error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1276,8 +1295,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return nil, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "invalid or inactive auth client")
@@ -1293,8 +1312,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return nil, errors.New("db error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "identity provider lookup failed")
@@ -1310,8 +1329,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return nil, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "identity provider not found")
@@ -1327,8 +1346,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return nil, errors.New("db error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "invalid invite token")
@@ -1344,8 +1363,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return nil, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "invite not found")
@@ -1361,8 +1380,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return &model.Invite{Status: "accepted"}, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "invite has already been used or is no longer valid")
@@ -1379,8 +1398,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return &model.Invite{Status: model.StatusPending, ExpiresAt: &past}, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "invite has expired")
@@ -1397,8 +1416,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return nil, errors.New("db error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1414,8 +1433,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return &model.User{UserID: 99}, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "username already taken")
@@ -1432,8 +1451,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return nil, errors.New("db error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1449,8 +1468,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return &model.User{UserID: 99}, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "invited email already registered")
@@ -1467,8 +1486,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return nil, errors.New("create error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1484,8 +1503,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return nil, errors.New("identity error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1500,8 +1519,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return nil, errors.New("role error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1517,8 +1536,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return nil, errors.New("role assign error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1534,8 +1553,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return nil, errors.New("lookup error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1552,8 +1571,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return &model.UserRole{UserID: 1, RoleID: 10}, nil // already exists
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		// tx commits but generateTokenResponse fails (userIdentitySub empty)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -1575,8 +1594,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return &model.UserRole{}, nil
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1592,8 +1611,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 			return errors.New("mark error")
 		}
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1609,8 +1628,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 		m := defaultRegPublicMocks()
 		m.invite.findByTokenFn = func(_ string) (*model.Invite, error) { return validInvite(), nil }
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -1624,8 +1643,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 		m := defaultRegPublicMocks()
 		m.invite.findByTokenFn = func(_ string) (*model.Invite, error) { return validInvite(), nil }
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		// userIdentitySub is never set in tx → generateTokenResponse fails
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -1633,9 +1652,9 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 	})
 
 	t.Run("HashPassword error", func(t *testing.T) {
-		origHash := security.HashPassword
-		defer func() { security.HashPassword = origHash }()
-		security.HashPassword = func(_ []byte) ([]byte, error) { return nil, errors.New("hash error") }
+		origHash := security.HashPasswordWithParams
+		defer func() { security.HashPasswordWithParams = origHash }()
+		security.HashPasswordWithParams = func(_ []byte, _ security.PasswordHashParams) ([]byte, error) { return nil, errors.New("hash error") }
 
 		gormDB, mock := newMockGormDB(t)
 		mock.ExpectBegin()
@@ -1643,8 +1662,8 @@ func TestRegisterService_RegisterInvitePublic(t *testing.T) {
 		m := defaultRegPublicMocks()
 		m.invite.findByTokenFn = func(_ string) (*model.Invite, error) { return validInvite(), nil }
 		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
-			m.userIdentity, m.role, m.invite, m.idp)
-		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token")
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		resp, err := svc.RegisterInvitePublic(context.Background(), "u", "P@ss1!", "c", "p", "token", nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "hash error")
@@ -1672,7 +1691,7 @@ func TestRegisterService_GenerateTokenResponse(t *testing.T) {
 		defer initTestJWTKeysService(t)
 
 		svc := &registerService{}
-		resp, err := svc.generateTokenResponse("sub", &model.User{}, client)
+		resp, err := svc.generateTokenResponse(context.Background(), "sub", &model.User{}, client)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 	})
@@ -1681,7 +1700,7 @@ func TestRegisterService_GenerateTokenResponse(t *testing.T) {
 		initTestJWTKeysService(t)
 
 		svc := &registerService{}
-		resp, err := svc.generateTokenResponse("sub", &model.User{
+		resp, err := svc.generateTokenResponse(context.Background(), "sub", &model.User{
 			Email:           "test@example.com",
 			IsEmailVerified: true,
 			Phone:           "+1234567890",
@@ -1698,14 +1717,14 @@ func TestRegisterService_GenerateTokenResponse(t *testing.T) {
 
 	t.Run("GenerateIDToken error", func(t *testing.T) {
 		initTestJWTKeysService(t)
-		origIDToken := jwt.GenerateIDToken
-		defer func() { jwt.GenerateIDToken = origIDToken }()
-		jwt.GenerateIDToken = func(_, _, _, _ string, _ *jwt.UserProfile, _ string) (string, error) {
+		origIDToken := generateIDTokenFn
+		defer func() { generateIDTokenFn = origIDToken }()
+		generateIDTokenFn = func(_, _, _, _ string, _ *jwt.UserProfile, _ string, _ map[string]any) (string, error) {
 			return "", errors.New("id token error")
 		}
 
 		svc := &registerService{}
-		resp, err := svc.generateTokenResponse("sub", &model.User{}, client)
+		resp, err := svc.generateTokenResponse(context.Background(), "sub", &model.User{}, client)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "id token error")
@@ -1720,7 +1739,7 @@ func TestRegisterService_GenerateTokenResponse(t *testing.T) {
 		}
 
 		svc := &registerService{}
-		resp, err := svc.generateTokenResponse("sub", &model.User{}, client)
+		resp, err := svc.generateTokenResponse(context.Background(), "sub", &model.User{}, client)
 		require.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "refresh error")
@@ -20,10 +20,13 @@ type ResetPasswordService interface {
 }
 
 type resetPasswordService struct {
-	db            *gorm.DB
-	userRepo      repository.UserRepository
-	userTokenRepo repository.UserTokenRepository
-	clientRepo    repository.ClientRepository
+	db                  *gorm.DB
+	userRepo            repository.UserRepository
+	userTokenRepo       repository.UserTokenRepository
+	clientRepo          repository.ClientRepository
+	tenantSettingRepo   repository.TenantSettingRepository
+	securitySettingRepo repository.SecuritySettingRepository
+	breachChecker       security.BreachedPasswordChecker
 }
 
 func NewResetPasswordService(
@@ -31,12 +34,18 @@ func NewResetPasswordService(
 	userRepo repository.UserRepository,
 	userTokenRepo repository.UserTokenRepository,
 	clientRepo repository.ClientRepository,
+	tenantSettingRepo repository.TenantSettingRepository,
+	securitySettingRepo repository.SecuritySettingRepository,
+	breachChecker security.BreachedPasswordChecker,
 ) ResetPasswordService {
 	return &resetPasswordService{
-		db:            db,
-		userRepo:      userRepo,
-		userTokenRepo: userTokenRepo,
-		clientRepo:    clientRepo,
+		db:                  db,
+		userRepo:            userRepo,
+		userTokenRepo:       userTokenRepo,
+		clientRepo:          clientRepo,
+		tenantSettingRepo:   tenantSettingRepo,
+		securitySettingRepo: securitySettingRepo,
+		breachChecker:       breachChecker,
 	}
 }
 
@@ -115,8 +124,13 @@ func (s *resetPasswordService) ResetPassword(ctx context.Context, token, newPass
 			return apperror.NewInternal("password validation failed", err)
 		}
 
+		// Reject breached passwords when the tenant opts in
+		if err := ensurePasswordNotBreached(ctx, s.tenantSettingRepo.WithTx(tx), s.breachChecker, Client.IdentityProvider.TenantID, newPassword); err != nil {
+			return err
+		}
+
 		// Hash the new password
-		hashedPassword, txErr := security.HashPassword([]byte(newPassword))
+		hashedPassword, txErr := hashTenantPassword(s.securitySettingRepo.WithTx(tx), Client.IdentityProvider.TenantID, newPassword)
 		if txErr != nil {
 			return apperror.NewInternal("failed to hash password", txErr)
 		}
@@ -75,7 +75,7 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewResetPasswordService(db, &mockUserRepo{}, &mockUserTokenRepo{}, &mockClientRepo{
 			findSystemFn: func() (*model.Client, error) { return nil, errors.New("db error") },
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -89,7 +89,7 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewResetPasswordService(db, &mockUserRepo{}, &mockUserTokenRepo{}, &mockClientRepo{
 			findSystemFn: func() (*model.Client, error) { return nil, nil },
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -105,7 +105,7 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 			findByClientIDAndIdentityProviderFn: func(_, _ string) (*model.Client, error) {
 				return nil, errors.New("client lookup error")
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, &clientID, &providerID)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -121,7 +121,7 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 			findByClientIDAndIdentityProviderFn: func(_, _ string) (*model.Client, error) {
 				return nil, nil
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, &clientID, &providerID)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -139,9 +139,9 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewResetPasswordService(db, &mockUserRepo{}, &mockUserTokenRepo{}, &mockClientRepo{
 			findSystemFn: func() (*model.Client, error) {
-				return &model.Client{ClientID: 1}, nil
+				return &model.Client{ClientID: 1, IdentityProvider: &model.IdentityProvider{TenantID: 1}}, nil
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -157,9 +157,9 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewResetPasswordService(db, &mockUserRepo{}, &mockUserTokenRepo{}, &mockClientRepo{
 			findSystemFn: func() (*model.Client, error) {
-				return &model.Client{ClientID: 1}, nil
+				return &model.Client{ClientID: 1, IdentityProvider: &model.IdentityProvider{TenantID: 1}}, nil
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -177,9 +177,9 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewResetPasswordService(db, &mockUserRepo{}, &mockUserTokenRepo{}, &mockClientRepo{
 			findSystemFn: func() (*model.Client, error) {
-				return &model.Client{ClientID: 1}, nil
+				return &model.Client{ClientID: 1, IdentityProvider: &model.IdentityProvider{TenantID: 1}}, nil
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -195,9 +195,9 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewResetPasswordService(db, &mockUserRepo{}, &mockUserTokenRepo{}, &mockClientRepo{
 			findSystemFn: func() (*model.Client, error) {
-				return &model.Client{ClientID: 1}, nil
+				return &model.Client{ClientID: 1, IdentityProvider: &model.IdentityProvider{TenantID: 1}}, nil
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -219,9 +219,9 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 			},
 		}, &mockUserTokenRepo{}, &mockClientRepo{
 			findSystemFn: func() (*model.Client, error) {
-				return &model.Client{ClientID: 1}, nil
+				return &model.Client{ClientID: 1, IdentityProvider: &model.IdentityProvider{TenantID: 1}}, nil
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -241,9 +241,9 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 			},
 		}, &mockUserTokenRepo{}, &mockClientRepo{
 			findSystemFn: func() (*model.Client, error) {
-				return &model.Client{ClientID: 1}, nil
+				return &model.Client{ClientID: 1, IdentityProvider: &model.IdentityProvider{TenantID: 1}}, nil
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -265,9 +265,9 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 			},
 		}, &mockUserTokenRepo{}, &mockClientRepo{
 			findSystemFn: func() (*model.Client, error) {
-				return &model.Client{ClientID: 1}, nil
+				return &model.Client{ClientID: 1, IdentityProvider: &model.IdentityProvider{TenantID: 1}}, nil
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -289,9 +289,9 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 			},
 		}, &mockUserTokenRepo{}, &mockClientRepo{
 			findSystemFn: func() (*model.Client, error) {
-				return &model.Client{ClientID: 1}, nil
+				return &model.Client{ClientID: 1, IdentityProvider: &model.IdentityProvider{TenantID: 1}}, nil
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, "weak", nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -299,6 +299,34 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
+	// ----- Breached password -----
+
+	t.Run("breached password → rollback", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT \* FROM "user_tokens"`).
+			WillReturnRows(validTokenRow(tok, userID, tokenUUID))
+		mock.ExpectRollback()
+		svc := NewResetPasswordService(db, &mockUserRepo{
+			findByIDFn: func(_ any, _ ...string) (*model.User, error) {
+				return &model.User{UserID: userID, UserUUID: userUUID, Status: model.StatusActive}, nil
+			},
+			updateByIDFn: func(_, _ any) (*model.User, error) {
+				t.Fatal("password should not be updated")
+				return nil, nil
+			},
+		}, &mockUserTokenRepo{}, &mockClientRepo{
+			findSystemFn: func() (*model.Client, error) {
+				return &model.Client{ClientID: 1, IdentityProvider: &model.IdentityProvider{TenantID: 1}}, nil
+			},
+		}, breachFlagSettingRepo(`{"breached_password_check": true}`), &mockSecuritySettingRepo{}, &mockBreachChecker{breached: true})
+		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, nil, nil)
+		require.Error(t, err)
+		assert.Nil(t, resp)
+		assert.Contains(t, err.Error(), "data breach")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
 	// ----- UpdateByID error -----
 
 	t.Run("UpdateByID error → rollback", func(t *testing.T) {
@@ -316,9 +344,9 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 			},
 		}, &mockUserTokenRepo{}, &mockClientRepo{
 			findSystemFn: func() (*model.Client, error) {
-				return &model.Client{ClientID: 1}, nil
+				return &model.Client{ClientID: 1, IdentityProvider: &model.IdentityProvider{TenantID: 1}}, nil
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -344,9 +372,9 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 			},
 		}, &mockClientRepo{
 			findSystemFn: func() (*model.Client, error) {
-				return &model.Client{ClientID: 1}, nil
+				return &model.Client{ClientID: 1, IdentityProvider: &model.IdentityProvider{TenantID: 1}}, nil
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -372,9 +400,9 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 			},
 		}, &mockClientRepo{
 			findSystemFn: func() (*model.Client, error) {
-				return &model.Client{ClientID: 1}, nil
+				return &model.Client{ClientID: 1, IdentityProvider: &model.IdentityProvider{TenantID: 1}}, nil
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -414,9 +442,9 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 			},
 		}, &mockClientRepo{
 			findSystemFn: func() (*model.Client, error) {
-				return &model.Client{ClientID: 1}, nil
+				return &model.Client{ClientID: 1, IdentityProvider: &model.IdentityProvider{TenantID: 1}}, nil
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -442,9 +470,9 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 			},
 		}, &mockClientRepo{
 			findSystemFn: func() (*model.Client, error) {
-				return &model.Client{ClientID: 1}, nil
+				return &model.Client{ClientID: 1, IdentityProvider: &model.IdentityProvider{TenantID: 1}}, nil
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, nil, nil)
 		require.NoError(t, err)
 		require.NotNil(t, resp)
@@ -469,9 +497,9 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 			},
 		}, &mockClientRepo{
 			findByClientIDAndIdentityProviderFn: func(_, _ string) (*model.Client, error) {
-				return &model.Client{ClientID: 1}, nil
+				return &model.Client{ClientID: 1, IdentityProvider: &model.IdentityProvider{TenantID: 1}}, nil
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, &clientID, &providerID)
 		require.NoError(t, err)
 		require.NotNil(t, resp)
@@ -499,9 +527,9 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 			},
 		}, &mockClientRepo{
 			findSystemFn: func() (*model.Client, error) {
-				return &model.Client{ClientID: 1}, nil
+				return &model.Client{ClientID: 1, IdentityProvider: &model.IdentityProvider{TenantID: 1}}, nil
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, nil, nil)
 		require.NoError(t, err)
 		require.NotNil(t, resp)
@@ -530,9 +558,9 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 			},
 		}, &mockClientRepo{
 			findSystemFn: func() (*model.Client, error) {
-				return &model.Client{ClientID: 1}, nil
+				return &model.Client{ClientID: 1, IdentityProvider: &model.IdentityProvider{TenantID: 1}}, nil
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, nil, nil)
 		require.NoError(t, err)
 		require.NotNil(t, resp)
@@ -541,9 +569,9 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 	})
 
 	t.Run("HashPassword error", func(t *testing.T) {
-		origHash := security.HashPassword
-		defer func() { security.HashPassword = origHash }()
-		security.HashPassword = func(_ []byte) ([]byte, error) { return nil, errors.New("hash error") }
+		origHash := security.HashPasswordWithParams
+		defer func() { security.HashPasswordWithParams = origHash }()
+		security.HashPasswordWithParams = func(_ []byte, _ security.PasswordHashParams) ([]byte, error) { return nil, errors.New("hash error") }
 
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
@@ -556,9 +584,9 @@ func TestResetPasswordService_ResetPassword(t *testing.T) {
 			},
 		}, &mockUserTokenRepo{}, &mockClientRepo{
 			findSystemFn: func() (*model.Client, error) {
-				return &model.Client{ClientID: 1}, nil
+				return &model.Client{ClientID: 1, IdentityProvider: &model.IdentityProvider{TenantID: 1}}, nil
 			},
-		})
+		}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, nil)
 		resp, err := svc.ResetPassword(context.Background(), tok, strongPassword, nil, nil)
 		require.Error(t, err)
 		assert.Nil(t, resp)
@@ -22,9 +22,13 @@ type RoleServiceDataResult struct {
 	Permissions *[]PermissionServiceDataResult
 	IsDefault   bool
 	IsSystem    bool
-	Status      string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	// IsInheritable roles can be assigned in the tenants below their own.
+	// IsInherited marks roles listed for a tenant below the role's tenant.
+	IsInheritable bool
+	IsInherited   bool
+	Status        string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
 
 type RoleServiceGetFilter struct {
@@ -34,10 +38,12 @@ type RoleServiceGetFilter struct {
 	IsSystem    *bool
 	Status      *string
 	TenantID    int64
-	Page        int
-	Limit       int
-	SortBy      string
-	SortOrder   string
+	// IncludeInherited adds the inheritable roles of the tenant's ancestors.
+	IncludeInherited bool
+	Page             int
+	Limit            int
+	SortBy           string
+	SortOrder        string
 }
 
 type RoleServiceGetResult struct {
@@ -73,7 +79,10 @@ type RoleService interface {
 	Create(ctx context.Context, name string, description string, isDefault bool, isSystem bool, status string, tenantUUID string, actorUserUUID uuid.UUID) (*RoleServiceDataResult, error)
 	Update(ctx context.Context, roleUUID uuid.UUID, tenantID int64, name string, description string, isDefault bool, isSystem bool, status string, actorUserUUID uuid.UUID) (*RoleServiceDataResult, error)
 	SetStatusByUUID(ctx context.Context, roleUUID uuid.UUID, tenantID int64, status string, actorUserUUID uuid.UUID) (*RoleServiceDataResult, error)
+	SetDefaultByUUID(ctx context.Context, roleUUID uuid.UUID, tenantID int64, actorUserUUID uuid.UUID) (*RoleServiceDataResult, error)
+	SetInheritableByUUID(ctx context.Context, roleUUID uuid.UUID, tenantID int64, isInheritable bool, actorUserUUID uuid.UUID) (*RoleServiceDataResult, error)
 	DeleteByUUID(ctx context.Context, roleUUID uuid.UUID, tenantID int64, actorUserUUID uuid.UUID) (*RoleServiceDataResult, error)
+	PreviewDeleteByUUID(ctx context.Context, roleUUID uuid.UUID, tenantID int64, actorUserUUID uuid.UUID) (*DeleteImpactReport, error)
 	AddRolePermissions(ctx context.Context, roleUUID uuid.UUID, tenantID int64, permissionUUIDs []uuid.UUID, actorUserUUID uuid.UUID) (*RoleServiceDataResult, error)
 	RemoveRolePermissions(ctx context.Context, roleUUID uuid.UUID, tenantID int64, permissionUUID uuid.UUID, actorUserUUID uuid.UUID) (*RoleServiceDataResult, error)
 }
@@ -85,6 +94,7 @@ type roleService struct {
 	rolePermissionRepo repository.RolePermissionRepository
 	userRepo           repository.UserRepository
 	tenantRepo         repository.TenantRepository
+	eventRepo          repository.EventRepository
 	cacheInvalidator   cache.Invalidator
 }
 
@@ -95,6 +105,7 @@ func NewRoleService(
 	rolePermissionRepo repository.RolePermissionRepository,
 	userRepo repository.UserRepository,
 	tenantRepo repository.TenantRepository,
+	eventRepo repository.EventRepository,
 	cacheInvalidator cache.Invalidator,
 ) RoleService {
 	return &roleService{
@@ -104,6 +115,7 @@ func NewRoleService(
 		rolePermissionRepo: rolePermissionRepo,
 		userRepo:           userRepo,
 		tenantRepo:         tenantRepo,
+		eventRepo:          eventRepo,
 		cacheInvalidator:   cacheInvalidator,
 	}
 }
@@ -113,16 +125,17 @@ func (s *roleService) Get(ctx context.Context, filter RoleServiceGetFilter) (*Ro
 	defer span.End()
 
 	roleFilter := repository.RoleRepositoryGetFilter{
-		Name:        filter.Name,
-		Description: filter.Description,
-		IsDefault:   filter.IsDefault,
-		IsSystem:    filter.IsSystem,
-		Status:      filter.Status,
-		TenantID:    filter.TenantID,
-		Page:        filter.Page,
-		Limit:       filter.Limit,
-		SortBy:      filter.SortBy,
-		SortOrder:   filter.SortOrder,
+		Name:             filter.Name,
+		Description:      filter.Description,
+		IsDefault:        filter.IsDefault,
+		IsSystem:         filter.IsSystem,
+		Status:           filter.Status,
+		TenantID:         filter.TenantID,
+		IncludeInherited: filter.IncludeInherited,
+		Page:             filter.Page,
+		Limit:            filter.Limit,
+		SortBy:           filter.SortBy,
+		SortOrder:        filter.SortOrder,
 	}
 
 	// Query paginated roles
@@ -136,6 +149,7 @@ func (s *roleService) Get(ctx context.Context, filter RoleServiceGetFilter) (*Ro
 	roles := make([]RoleServiceDataResult, len(result.Data))
 	for i, r := range result.Data {
 		roles[i] = *toRoleServiceDataResult(&r)
+		roles[i].IsInherited = r.TenantID != filter.TenantID
 	}
 
 	span.SetStatus(codes.Ok, "")
@@ -241,6 +255,7 @@ func (s *roleService) Create(ctx context.Context, name string, description strin
 		txRoleRepo := s.roleRepo.WithTx(tx)
 		txUserRepo := s.userRepo.WithTx(tx)
 		txTenantRepo := s.tenantRepo.WithTx(tx)
+		txEventRepo := s.eventRepo.WithTx(tx)
 
 		// Parse tenant UUID
 		tenantUUIDParsed, err := uuid.Parse(tenantUUID)
@@ -289,6 +304,11 @@ func (s *roleService) Create(ctx context.Context, name string, description strin
 			return err
 		}
 
+		// Record lifecycle event
+		if err := recordEvent(txEventRepo, newRole.TenantID, RoleCreated(newRoleEventPayload(newRole))); err != nil {
+			return err
+		}
+
 		createdRole = newRole
 
 		return nil
@@ -315,9 +335,10 @@ func (s *roleService) Update(ctx context.Context, roleUUID uuid.UUID, tenantID i
 	err := s.db.Transaction(func(tx *gorm.DB) error {
 		txRoleRepo := s.roleRepo.WithTx(tx)
 		txUserRepo := s.userRepo.WithTx(tx)
+		txEventRepo := s.eventRepo.WithTx(tx)
 
-		// Find existing role
-		role, err := txRoleRepo.FindByUUID(roleUUID, "Tenant")
+		// Find existing role, locked so the If-Match check below holds until commit
+		role, err := s.roleRepo.WithTx(lockForUpdate(tx)).FindByUUID(roleUUID, "Tenant")
 		if err != nil {
 			return err
 		}
@@ -341,6 +362,10 @@ func (s *roleService) Update(ctx context.Context, roleUUID uuid.UUID, tenantID i
 			return err
 		}
 
+		if err := checkIfMatch(ctx, "role", role.UpdatedAt); err != nil {
+			return err
+		}
+
 		// Check if role is a system record
 		if role.IsSystem {
 			return apperror.NewValidation("system role is not allowed to be updated")
@@ -369,6 +394,11 @@ func (s *roleService) Update(ctx context.Context, roleUUID uuid.UUID, tenantID i
 			return err
 		}
 
+		// Record lifecycle event
+		if err := recordEvent(txEventRepo, role.TenantID, RoleUpdated(newRoleEventPayload(role))); err != nil {
+			return err
+		}
+
 		updatedRole = role
 
 		return nil
@@ -397,6 +427,7 @@ func (s *roleService) SetStatusByUUID(ctx context.Context, roleUUID uuid.UUID, t
 	err := s.db.Transaction(func(tx *gorm.DB) error {
 		txRoleRepo := s.roleRepo.WithTx(tx)
 		txUserRepo := s.userRepo.WithTx(tx)
+		txEventRepo := s.eventRepo.WithTx(tx)
 
 		// Find existing role
 		role, err := txRoleRepo.FindByUUID(roleUUID, "Tenant")
@@ -436,6 +467,11 @@ func (s *roleService) SetStatusByUUID(ctx context.Context, roleUUID uuid.UUID, t
 			return err
 		}
 
+		// Record lifecycle event
+		if err := recordEvent(txEventRepo, role.TenantID, RoleUpdated(newRoleEventPayload(role))); err != nil {
+			return err
+		}
+
 		updatedRole = role
 
 		return nil
@@ -453,61 +489,275 @@ func (s *roleService) SetStatusByUUID(ctx context.Context, roleUUID uuid.UUID, t
 	return toRoleServiceDataResult(updatedRole), nil
 }
 
+// SetInheritableByUUID sets whether the role can be assigned in the tenants
+// below its own. Users holding an inherited role keep it when the flag is
+// cleared.
+func (s *roleService) SetInheritableByUUID(ctx context.Context, roleUUID uuid.UUID, tenantID int64, isInheritable bool, actorUserUUID uuid.UUID) (*RoleServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "role.setInheritable")
+	defer span.End()
+	span.SetAttributes(attribute.String("role.uuid", roleUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	var updatedRole *model.Role
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txRoleRepo := s.roleRepo.WithTx(tx)
+		txUserRepo := s.userRepo.WithTx(tx)
+		txEventRepo := s.eventRepo.WithTx(tx)
+
+		// Find existing role
+		role, err := txRoleRepo.FindByUUID(roleUUID, "Tenant")
+		if err != nil {
+			return err
+		}
+		if role == nil {
+			return apperror.NewNotFound("role not found")
+		}
+
+		// Validate tenant ownership
+		if role.TenantID != tenantID {
+			return apperror.NewNotFoundWithReason("role not found or access denied")
+		}
+
+		// Get actor user with user identities for tenant validation
+		actorUser, err := txUserRepo.FindByUUID(actorUserUUID, "UserIdentities.Tenant")
+		if err != nil || actorUser == nil {
+			return apperror.NewNotFoundWithReason("actor user not found")
+		}
+
+		// Validate tenant access permissions
+		if err := ValidateTenantAccess(actorUser, role.Tenant); err != nil {
+			return err
+		}
+
+		// Update role
+		role.IsInheritable = isInheritable
+
+		_, err = txRoleRepo.CreateOrUpdate(role)
+		if err != nil {
+			return err
+		}
+
+		// Record lifecycle event
+		if err := recordEvent(txEventRepo, role.TenantID, RoleUpdated(newRoleEventPayload(role))); err != nil {
+			return err
+		}
+
+		updatedRole = role
+
+		return nil
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "set role inheritable failed")
+		return nil, err
+	}
+
+	s.cacheInvalidator.InvalidateAllUsers(ctx)
+
+	span.SetStatus(codes.Ok, "")
+	return toRoleServiceDataResult(updatedRole), nil
+}
+
+// SetDefaultByUUID makes the role the tenant's default role, which is assigned
+// to newly registered users. The previous default is cleared in the same
+// transaction so the tenant never ends up with zero or several defaults.
+func (s *roleService) SetDefaultByUUID(ctx context.Context, roleUUID uuid.UUID, tenantID int64, actorUserUUID uuid.UUID) (*RoleServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "role.setDefault")
+	defer span.End()
+	span.SetAttributes(attribute.String("role.uuid", roleUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	var updatedRole *model.Role
+
+	// Transaction
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txRoleRepo := s.roleRepo.WithTx(tx)
+		txUserRepo := s.userRepo.WithTx(tx)
+		txEventRepo := s.eventRepo.WithTx(tx)
+
+		// Find target role
+		role, err := txRoleRepo.FindByUUID(roleUUID, "Tenant")
+		if err != nil {
+			return err
+		}
+		if role == nil {
+			return apperror.NewNotFound("role not found")
+		}
+
+		// Validate tenant ownership
+		if role.TenantID != tenantID {
+			return apperror.NewNotFoundWithReason("role not found or access denied")
+		}
+
+		// Get actor user with user identities for tenant validation
+		actorUser, err := txUserRepo.FindByUUID(actorUserUUID, "UserIdentities.Tenant")
+		if err != nil || actorUser == nil {
+			return apperror.NewNotFoundWithReason("actor user not found")
+		}
+
+		// Validate tenant access permissions
+		if err := ValidateTenantAccess(actorUser, role.Tenant); err != nil {
+			return err
+		}
+
+		// Already the default, nothing to transfer
+		if role.IsDefault {
+			updatedRole = role
+			return nil
+		}
+
+		// Only the built-in registered role may be the default among system roles
+		if role.IsSystem && role.Name != model.RoleRegistered {
+			return apperror.NewValidation("system role cannot be set as default")
+		}
+		if role.Status != model.StatusActive {
+			return apperror.NewValidation("only active roles can be set as default")
+		}
+
+		// Capture the current default so its demotion reaches the event feed
+		tenantRoles, err := txRoleRepo.FindAllByTenantID(tenantID)
+		if err != nil {
+			return err
+		}
+
+		// Transfer the default designation
+		if err := txRoleRepo.UnsetDefaultByTenantID(tenantID); err != nil {
+			return err
+		}
+
+		for i := range tenantRoles {
+			previous := &tenantRoles[i]
+			if !previous.IsDefault || previous.RoleID == role.RoleID {
+				continue
+			}
+			previous.IsDefault = false
+			if err := recordEvent(txEventRepo, previous.TenantID, RoleUpdated(newRoleEventPayload(previous))); err != nil {
+				return err
+			}
+		}
+
+		role.IsDefault = true
+		if _, err := txRoleRepo.CreateOrUpdate(role); err != nil {
+			return err
+		}
+
+		// Record lifecycle event
+		if err := recordEvent(txEventRepo, role.TenantID, RoleUpdated(newRoleEventPayload(role))); err != nil {
+			return err
+		}
+
+		updatedRole = role
+
+		return nil
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "set default role failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toRoleServiceDataResult(updatedRole), nil
+}
+
 func (s *roleService) DeleteByUUID(ctx context.Context, roleUUID uuid.UUID, tenantID int64, actorUserUUID uuid.UUID) (*RoleServiceDataResult, error) {
 	_, span := otel.Tracer("service").Start(ctx, "role.delete")
 	defer span.End()
 	span.SetAttributes(attribute.String("role.uuid", roleUUID.String()), attribute.Int64("tenant.id", tenantID))
 
-	// Check role existence
-	role, err := s.roleRepo.FindByUUID(roleUUID, "Tenant")
+	role, err := s.findDeletableRole(roleUUID, tenantID, actorUserUUID)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "delete role failed")
 		return nil, err
 	}
-	if role == nil {
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return s.deleteRole(tx, role)
+	})
+	if err != nil {
+		span.RecordError(err)
 		span.SetStatus(codes.Error, "delete role failed")
+		return nil, err
+	}
+
+	s.cacheInvalidator.InvalidateAllUsers(ctx)
+
+	span.SetStatus(codes.Ok, "")
+	return toRoleServiceDataResult(role), nil
+}
+
+// PreviewDeleteByUUID reports what DeleteByUUID would remove, including the
+// role assignments and grants that cascade, running the delete in a
+// transaction that is rolled back.
+func (s *roleService) PreviewDeleteByUUID(ctx context.Context, roleUUID uuid.UUID, tenantID int64, actorUserUUID uuid.UUID) (*DeleteImpactReport, error) {
+	_, span := otel.Tracer("service").Start(ctx, "role.previewDelete")
+	defer span.End()
+	span.SetAttributes(attribute.String("role.uuid", roleUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	role, err := s.findDeletableRole(roleUUID, tenantID, actorUserUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "preview role delete failed")
+		return nil, err
+	}
+
+	report, err := previewDelete(s.db, hardDeleteImpact(model.Role{}.TableName(), "role_id", role.RoleID), []string{model.EventTypeRoleDeleted}, func(tx *gorm.DB) error {
+		return s.deleteRole(tx, role)
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "preview role delete failed")
+		return nil, apperror.NewInternal("failed to preview role delete", err)
+	}
+
+	span.SetAttributes(attribute.Bool("dry_run.would_succeed", report.WouldSucceed))
+	span.SetStatus(codes.Ok, "")
+	return report, nil
+}
+
+// findDeletableRole loads a role of the tenant that the actor may delete.
+// System roles are refused.
+func (s *roleService) findDeletableRole(roleUUID uuid.UUID, tenantID int64, actorUserUUID uuid.UUID) (*model.Role, error) {
+	// Check role existence
+	role, err := s.roleRepo.FindByUUID(roleUUID, "Tenant")
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
 		return nil, apperror.NewNotFound("role not found")
 	}
 
 	// Validate tenant ownership
 	if role.TenantID != tenantID {
-		span.SetStatus(codes.Error, "delete role failed")
 		return nil, apperror.NewNotFoundWithReason("role not found or access denied")
 	}
 
 	// Get actor user with user identities for tenant validation
 	actorUser, err := s.userRepo.FindByUUID(actorUserUUID, "UserIdentities.Tenant")
 	if err != nil || actorUser == nil {
-		span.SetStatus(codes.Error, "delete role failed")
 		return nil, apperror.NewNotFoundWithReason("actor user not found")
 	}
 
 	// Validate tenant access permissions
 	if err := ValidateTenantAccess(actorUser, role.Tenant); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, "delete role failed")
 		return nil, err
 	}
 
 	// Check if role is a system record
 	if role.IsSystem {
-		span.SetStatus(codes.Error, "delete role failed")
 		return nil, apperror.NewValidation("system role is not allowed to be deleted")
 	}
+	return role, nil
+}
 
-	// Delete role
-	err = s.roleRepo.DeleteByUUID(roleUUID)
-	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, "delete role failed")
-		return nil, err
+// deleteRole deletes the role and records the lifecycle event in tx.
+func (s *roleService) deleteRole(tx *gorm.DB, role *model.Role) error {
+	if err := s.roleRepo.WithTx(tx).DeleteByUUID(role.RoleUUID); err != nil {
+		return err
 	}
-
-	s.cacheInvalidator.InvalidateAllUsers(ctx)
-
-	span.SetStatus(codes.Ok, "")
-	return toRoleServiceDataResult(role), nil
+	return recordEvent(s.eventRepo.WithTx(tx), role.TenantID, RoleDeleted(newRoleEventPayload(role)))
 }
 
 func (s *roleService) AddRolePermissions(ctx context.Context, roleUUID uuid.UUID, tenantID int64, permissionUUIDs []uuid.UUID, actorUserUUID uuid.UUID) (*RoleServiceDataResult, error) {
@@ -523,6 +773,7 @@ func (s *roleService) AddRolePermissions(ctx context.Context, roleUUID uuid.UUID
 		txPermissionRepo := s.permissionRepo.WithTx(tx)
 		txRolePermissionRepo := s.rolePermissionRepo.WithTx(tx)
 		txUserRepo := s.userRepo.WithTx(tx)
+		txEventRepo := s.eventRepo.WithTx(tx)
 
 		// Find existing role
 		role, err := txRoleRepo.FindByUUID(roleUUID, "Tenant")
@@ -572,6 +823,7 @@ func (s *roleService) AddRolePermissions(ctx context.Context, roleUUID uuid.UUID
 		}
 
 		// Create role-permission associations using the dedicated repository
+		var added []uuid.UUID
 		for _, permission := range permissions {
 			// Check if association already exists
 			existing, err := txRolePermissionRepo.FindByRoleAndPermission(role.RoleID, permission.PermissionID)
@@ -594,6 +846,17 @@ func (s *roleService) AddRolePermissions(ctx context.Context, roleUUID uuid.UUID
 			if err != nil {
 				return err
 			}
+			added = append(added, permission.PermissionUUID)
+		}
+
+		// Record lifecycle event for the associations that actually changed
+		if len(added) > 0 {
+			if err := recordEvent(txEventRepo, role.TenantID, RolePermissionsAdded{
+				RoleUUID:        role.RoleUUID,
+				PermissionUUIDs: added,
+			}); err != nil {
+				return err
+			}
 		}
 
 		// Fetch the role with permissions for the response
@@ -630,6 +893,7 @@ func (s *roleService) RemoveRolePermissions(ctx context.Context, roleUUID uuid.U
 		txPermissionRepo := s.permissionRepo.WithTx(tx)
 		txRolePermissionRepo := s.rolePermissionRepo.WithTx(tx)
 		txUserRepo := s.userRepo.WithTx(tx)
+		txEventRepo := s.eventRepo.WithTx(tx)
 
 		// Find existing role
 		role, err := txRoleRepo.FindByUUID(roleUUID, "Tenant")
@@ -692,6 +956,14 @@ func (s *roleService) RemoveRolePermissions(ctx context.Context, roleUUID uuid.U
 			return err
 		}
 
+		// Record lifecycle event
+		if err := recordEvent(txEventRepo, role.TenantID, RolePermissionsRemoved{
+			RoleUUID:        role.RoleUUID,
+			PermissionUUIDs: []uuid.UUID{permission.PermissionUUID},
+		}); err != nil {
+			return err
+		}
+
 		// Fetch the role with permissions for the response
 		roleWithPermissions, err = txRoleRepo.FindByUUID(roleUUID, "Permissions")
 		if err != nil {
@@ -720,14 +992,15 @@ func toRoleServiceDataResult(role *model.Role) *RoleServiceDataResult {
 	}
 
 	result := &RoleServiceDataResult{
-		RoleUUID:    role.RoleUUID,
-		Name:        role.Name,
-		Description: role.Description,
-		IsDefault:   role.IsDefault,
-		IsSystem:    role.IsSystem,
-		Status:      role.Status,
-		CreatedAt:   role.CreatedAt,
-		UpdatedAt:   role.UpdatedAt,
+		RoleUUID:      role.RoleUUID,
+		Name:          role.Name,
+		Description:   role.Description,
+		IsDefault:     role.IsDefault,
+		IsSystem:      role.IsSystem,
+		IsInheritable: role.IsInheritable,
+		Status:        role.Status,
+		CreatedAt:     role.CreatedAt,
+		UpdatedAt:     role.UpdatedAt,
 	}
 
 	if role.Permissions != nil {
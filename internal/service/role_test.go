@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
 	"github.com/maintainerd/auth/internal/cache"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/repository"
@@ -26,7 +27,7 @@ func newRole(id int64, name string, tenantID int64) *model.Role {
 }
 
 func newRoleService(roleRepo *mockRoleRepo, permRepo *mockPermissionRepo, rpRepo *mockRolePermissionRepo, userRepo *mockUserRepo, tenantRepo *mockTenantRepo) RoleService {
-	return NewRoleService(nil, roleRepo, permRepo, rpRepo, userRepo, tenantRepo, cache.NopInvalidator{})
+	return NewRoleService(nil, roleRepo, permRepo, rpRepo, userRepo, tenantRepo, &mockEventRepo{}, cache.NopInvalidator{})
 }
 
 // actor helper: user with default-tenant identity → can access any tenant.
@@ -85,6 +86,27 @@ func TestRoleService_Get(t *testing.T) {
 		assert.Len(t, result.Data, 1)
 		assert.Equal(t, "admin", result.Data[0].Name)
 	})
+
+	t.Run("inherited roles are marked", func(t *testing.T) {
+		svc := newRoleService(&mockRoleRepo{
+			findPaginatedFn: func(f repository.RoleRepositoryGetFilter) (*repository.PaginationResult[model.Role], error) {
+				assert.True(t, f.IncludeInherited)
+				return &repository.PaginationResult[model.Role]{
+					Data: []model.Role{
+						{Name: "own", TenantID: 1},
+						{Name: "parent", TenantID: 2, IsInheritable: true},
+					},
+					Total: 2, Page: 1, Limit: 10, TotalPages: 1,
+				}, nil
+			},
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{})
+		result, err := svc.Get(context.Background(), RoleServiceGetFilter{TenantID: 1, IncludeInherited: true, Page: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, result.Data, 2)
+		assert.False(t, result.Data[0].IsInherited)
+		assert.True(t, result.Data[1].IsInherited)
+		assert.True(t, result.Data[1].IsInheritable)
+	})
 }
 
 // ---------------------------------------------------------------------------
@@ -236,7 +258,7 @@ func TestRoleService_Create(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
 		mock.ExpectRollback()
-		svc := NewRoleService(db, &mockRoleRepo{}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, cache.NopInvalidator{})
+		svc := NewRoleService(db, &mockRoleRepo{}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.Create(context.Background(), "admin", "desc", false, false, model.StatusActive, "not-a-uuid", actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "invalid tenant UUID")
@@ -248,7 +270,7 @@ func TestRoleService_Create(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewRoleService(db, &mockRoleRepo{}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.Tenant, error) { return nil, nil },
-		}, cache.NopInvalidator{})
+		}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.Create(context.Background(), "admin", "desc", false, false, model.StatusActive, tenantUUID.String(), actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "tenant not found")
@@ -262,7 +284,7 @@ func TestRoleService_Create(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.Tenant, error) {
 				return nil, errors.New("db error")
 			},
-		}, cache.NopInvalidator{})
+		}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.Create(context.Background(), "admin", "desc", false, false, model.StatusActive, tenantUUID.String(), actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "tenant not found")
@@ -278,7 +300,7 @@ func TestRoleService_Create(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.Tenant, error) {
 				return &model.Tenant{TenantID: tenantID, TenantUUID: tenantUUID}, nil
 			},
-		}, cache.NopInvalidator{})
+		}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.Create(context.Background(), "admin", "desc", false, false, model.StatusActive, tenantUUID.String(), actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "actor user not found")
@@ -296,7 +318,7 @@ func TestRoleService_Create(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.Tenant, error) {
 				return &model.Tenant{TenantID: tenantID, TenantUUID: tenantUUID}, nil
 			},
-		}, cache.NopInvalidator{})
+		}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.Create(context.Background(), "admin", "desc", false, false, model.StatusActive, tenantUUID.String(), actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "no identities")
@@ -318,7 +340,7 @@ func TestRoleService_Create(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.Tenant, error) {
 				return &model.Tenant{TenantID: tenantID, TenantUUID: tenantUUID}, nil
 			},
-		}, cache.NopInvalidator{})
+		}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.Create(context.Background(), "admin", "desc", false, false, model.StatusActive, tenantUUID.String(), actorUUID)
 		require.Error(t, err)
 	})
@@ -339,7 +361,7 @@ func TestRoleService_Create(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.Tenant, error) {
 				return &model.Tenant{TenantID: tenantID, TenantUUID: tenantUUID}, nil
 			},
-		}, cache.NopInvalidator{})
+		}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.Create(context.Background(), "admin", "desc", false, false, model.StatusActive, tenantUUID.String(), actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "role already exist")
@@ -361,7 +383,7 @@ func TestRoleService_Create(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.Tenant, error) {
 				return &model.Tenant{TenantID: tenantID, TenantUUID: tenantUUID}, nil
 			},
-		}, cache.NopInvalidator{})
+		}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.Create(context.Background(), "admin", "desc", false, false, model.StatusActive, tenantUUID.String(), actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "create error")
@@ -379,7 +401,7 @@ func TestRoleService_Create(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.Tenant, error) {
 				return &model.Tenant{TenantID: tenantID, TenantUUID: tenantUUID}, nil
 			},
-		}, cache.NopInvalidator{})
+		}, &mockEventRepo{}, cache.NopInvalidator{})
 		result, err := svc.Create(context.Background(), "admin", "desc", true, false, model.StatusActive, tenantUUID.String(), actorUUID)
 		require.NoError(t, err)
 		assert.Equal(t, "admin", result.Name)
@@ -401,7 +423,7 @@ func TestRoleService_Update(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewRoleService(db, &mockRoleRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) { return nil, nil },
-		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.Update(context.Background(), roleUUID, tenantID, "new", "desc", false, false, model.StatusActive, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "role not found")
@@ -415,7 +437,7 @@ func TestRoleService_Update(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) {
 				return nil, errors.New("db error")
 			},
-		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.Update(context.Background(), roleUUID, tenantID, "new", "desc", false, false, model.StatusActive, actorUUID)
 		require.Error(t, err)
 	})
@@ -428,7 +450,7 @@ func TestRoleService_Update(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) {
 				return newRole(1, "admin", 99), nil
 			},
-		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.Update(context.Background(), roleUUID, tenantID, "new", "desc", false, false, model.StatusActive, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "access denied")
@@ -444,7 +466,7 @@ func TestRoleService_Update(t *testing.T) {
 			},
 		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return nil, nil },
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.Update(context.Background(), roleUUID, tenantID, "new", "desc", false, false, model.StatusActive, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "actor user not found")
@@ -462,7 +484,7 @@ func TestRoleService_Update(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorNoIdentities(), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.Update(context.Background(), roleUUID, tenantID, "new", "desc", false, false, model.StatusActive, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "no identities")
@@ -480,12 +502,30 @@ func TestRoleService_Update(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.Update(context.Background(), roleUUID, tenantID, "new", "desc", false, false, model.StatusActive, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "system role")
 	})
 
+	t.Run("stale If-Match → precondition failed", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := NewRoleService(db, &mockRoleRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) {
+				return newRole(1, "admin", tenantID), nil
+			},
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
+				return roleActorUser(tenantID), nil
+			},
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
+		_, err := svc.Update(ifMatchContext(t, `"stale"`), roleUUID, tenantID, "new", "desc", false, false, model.StatusActive, actorUUID)
+		var want *apperror.PreconditionError
+		require.ErrorAs(t, err, &want)
+	})
+
 	t.Run("duplicate name check error → rollback", func(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
@@ -501,7 +541,7 @@ func TestRoleService_Update(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.Update(context.Background(), roleUUID, tenantID, "newname", "desc", false, false, model.StatusActive, actorUUID)
 		require.Error(t, err)
 	})
@@ -522,7 +562,7 @@ func TestRoleService_Update(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.Update(context.Background(), roleUUID, tenantID, "newname", "desc", false, false, model.StatusActive, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "role already exists")
@@ -540,7 +580,7 @@ func TestRoleService_Update(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		result, err := svc.Update(context.Background(), roleUUID, tenantID, "admin", "new desc", false, false, model.StatusActive, actorUUID)
 		require.NoError(t, err)
 		assert.Equal(t, "admin", result.Name)
@@ -558,7 +598,7 @@ func TestRoleService_Update(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		result, err := svc.Update(context.Background(), roleUUID, tenantID, "editor", "new desc", false, false, model.StatusActive, actorUUID)
 		require.NoError(t, err)
 		assert.Equal(t, "editor", result.Name)
@@ -579,7 +619,7 @@ func TestRoleService_Update(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.Update(context.Background(), roleUUID, tenantID, "admin", "desc", false, false, model.StatusActive, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "save error")
@@ -600,7 +640,7 @@ func TestRoleService_Update(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		result, err := svc.Update(context.Background(), roleUUID, tenantID, "newname", "desc", false, false, model.StatusActive, actorUUID)
 		require.NoError(t, err)
 		assert.Equal(t, "newname", result.Name)
@@ -622,7 +662,7 @@ func TestRoleService_SetStatusByUUID(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewRoleService(db, &mockRoleRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) { return nil, nil },
-		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.SetStatusByUUID(context.Background(), roleUUID, tenantID, model.StatusInactive, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "role not found")
@@ -636,7 +676,7 @@ func TestRoleService_SetStatusByUUID(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) {
 				return nil, errors.New("db error")
 			},
-		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.SetStatusByUUID(context.Background(), roleUUID, tenantID, model.StatusInactive, actorUUID)
 		require.Error(t, err)
 	})
@@ -649,7 +689,7 @@ func TestRoleService_SetStatusByUUID(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) {
 				return newRole(1, "admin", 99), nil
 			},
-		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.SetStatusByUUID(context.Background(), roleUUID, tenantID, model.StatusInactive, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "access denied")
@@ -665,7 +705,7 @@ func TestRoleService_SetStatusByUUID(t *testing.T) {
 			},
 		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return nil, nil },
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.SetStatusByUUID(context.Background(), roleUUID, tenantID, model.StatusInactive, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "actor user not found")
@@ -683,7 +723,7 @@ func TestRoleService_SetStatusByUUID(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorNoIdentities(), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.SetStatusByUUID(context.Background(), roleUUID, tenantID, model.StatusInactive, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "no identities")
@@ -701,7 +741,7 @@ func TestRoleService_SetStatusByUUID(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.SetStatusByUUID(context.Background(), roleUUID, tenantID, model.StatusInactive, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "system role")
@@ -722,7 +762,7 @@ func TestRoleService_SetStatusByUUID(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.SetStatusByUUID(context.Background(), roleUUID, tenantID, model.StatusInactive, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "save error")
@@ -740,7 +780,7 @@ func TestRoleService_SetStatusByUUID(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		result, err := svc.SetStatusByUUID(context.Background(), roleUUID, tenantID, model.StatusInactive, actorUUID)
 		require.NoError(t, err)
 		assert.NotNil(t, result)
@@ -748,6 +788,192 @@ func TestRoleService_SetStatusByUUID(t *testing.T) {
 	})
 }
 
+// ---------------------------------------------------------------------------
+// RoleService.SetInheritableByUUID
+// ---------------------------------------------------------------------------
+
+func TestRoleService_SetInheritableByUUID(t *testing.T) {
+	tenantID := int64(1)
+	roleUUID := uuid.New()
+	actorUUID := uuid.New()
+
+	t.Run("role not found → rollback", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := NewRoleService(db, &mockRoleRepo{}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
+		_, err := svc.SetInheritableByUUID(context.Background(), roleUUID, tenantID, true, actorUUID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "role not found")
+	})
+
+	t.Run("wrong tenant → access denied", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := NewRoleService(db, &mockRoleRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) { return newRole(1, "admin", 99), nil },
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
+		_, err := svc.SetInheritableByUUID(context.Background(), roleUUID, tenantID, true, actorUUID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "access denied")
+	})
+
+	t.Run("tenant access denied → rollback", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := NewRoleService(db, &mockRoleRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) { return newRole(1, "admin", tenantID), nil },
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return roleActorNoIdentities(), nil },
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
+		_, err := svc.SetInheritableByUUID(context.Background(), roleUUID, tenantID, true, actorUUID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no identities")
+	})
+
+	t.Run("success → flag updated", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		var saved *model.Role
+		svc := NewRoleService(db, &mockRoleRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) { return newRole(1, "admin", tenantID), nil },
+			createOrUpdateFn: func(r *model.Role) (*model.Role, error) {
+				saved = r
+				return r, nil
+			},
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return roleActorUser(tenantID), nil },
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
+		result, err := svc.SetInheritableByUUID(context.Background(), roleUUID, tenantID, true, actorUUID)
+		require.NoError(t, err)
+		assert.True(t, result.IsInheritable)
+		require.NotNil(t, saved)
+		assert.True(t, saved.IsInheritable)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// RoleService.SetDefaultByUUID
+// ---------------------------------------------------------------------------
+
+func TestRoleService_SetDefaultByUUID(t *testing.T) {
+	tenantID := int64(1)
+	roleUUID := uuid.New()
+	actorUUID := uuid.New()
+
+	actorRepo := func() *mockUserRepo {
+		return &mockUserRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
+				return roleActorUser(tenantID), nil
+			},
+		}
+	}
+
+	t.Run("role not found → rollback", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := NewRoleService(db, &mockRoleRepo{}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, actorRepo(), &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
+		_, err := svc.SetDefaultByUUID(context.Background(), roleUUID, tenantID, actorUUID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "role not found")
+	})
+
+	t.Run("wrong tenant → access denied", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := NewRoleService(db, &mockRoleRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) {
+				return newRole(1, "member", 99), nil
+			},
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, actorRepo(), &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
+		_, err := svc.SetDefaultByUUID(context.Background(), roleUUID, tenantID, actorUUID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "access denied")
+	})
+
+	t.Run("system role other than registered → validation error", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		r := newRole(1, model.RoleSuperAdmin, tenantID)
+		r.IsSystem = true
+		svc := NewRoleService(db, &mockRoleRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) { return r, nil },
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, actorRepo(), &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
+		_, err := svc.SetDefaultByUUID(context.Background(), roleUUID, tenantID, actorUUID)
+		var target *apperror.ValidationError
+		require.ErrorAs(t, err, &target)
+	})
+
+	t.Run("inactive role → validation error", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		r := newRole(1, "member", tenantID)
+		r.Status = model.StatusInactive
+		svc := NewRoleService(db, &mockRoleRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) { return r, nil },
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, actorRepo(), &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
+		_, err := svc.SetDefaultByUUID(context.Background(), roleUUID, tenantID, actorUUID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only active roles")
+	})
+
+	t.Run("already default → no transfer", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		r := newRole(1, "member", tenantID)
+		r.IsDefault = true
+		unset := false
+		svc := NewRoleService(db, &mockRoleRepo{
+			findByUUIDFn:             func(_ any, _ ...string) (*model.Role, error) { return r, nil },
+			unsetDefaultByTenantIDFn: func(int64) error { unset = true; return nil },
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, actorRepo(), &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
+		result, err := svc.SetDefaultByUUID(context.Background(), roleUUID, tenantID, actorUUID)
+		require.NoError(t, err)
+		assert.True(t, result.IsDefault)
+		assert.False(t, unset)
+	})
+
+	t.Run("unset error → rollback", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := NewRoleService(db, &mockRoleRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) {
+				return newRole(1, "member", tenantID), nil
+			},
+			unsetDefaultByTenantIDFn: func(int64) error { return errors.New("unset error") },
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, actorRepo(), &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
+		_, err := svc.SetDefaultByUUID(context.Background(), roleUUID, tenantID, actorUUID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unset error")
+	})
+
+	t.Run("registered system role → transferred", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		r := newRole(1, model.RoleRegistered, tenantID)
+		r.IsSystem = true
+		var unsetTenant int64
+		svc := NewRoleService(db, &mockRoleRepo{
+			findByUUIDFn:             func(_ any, _ ...string) (*model.Role, error) { return r, nil },
+			unsetDefaultByTenantIDFn: func(tID int64) error { unsetTenant = tID; return nil },
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, actorRepo(), &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
+		result, err := svc.SetDefaultByUUID(context.Background(), roleUUID, tenantID, actorUUID)
+		require.NoError(t, err)
+		assert.True(t, result.IsDefault)
+		assert.Equal(t, tenantID, unsetTenant)
+	})
+}
+
 // ---------------------------------------------------------------------------
 // RoleService.DeleteByUUID
 // ---------------------------------------------------------------------------
@@ -831,7 +1057,11 @@ func TestRoleService_DeleteByUUID(t *testing.T) {
 	})
 
 	t.Run("DeleteByUUID repo error → propagated", func(t *testing.T) {
-		svc := newRoleService(&mockRoleRepo{
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		events := &mockEventRepo{}
+		svc := NewRoleService(db, &mockRoleRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) {
 				return newRole(1, "admin", tenantID), nil
 			},
@@ -840,14 +1070,18 @@ func TestRoleService_DeleteByUUID(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{})
+		}, &mockTenantRepo{}, events, cache.NopInvalidator{})
 		_, err := svc.DeleteByUUID(context.Background(), roleUUID, tenantID, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "delete failed")
+		assert.Empty(t, events.created)
 	})
 
-	t.Run("success", func(t *testing.T) {
-		svc := newRoleService(&mockRoleRepo{
+	t.Run("event error → rollback", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := NewRoleService(db, &mockRoleRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) {
 				return newRole(1, "admin", tenantID), nil
 			},
@@ -855,10 +1089,35 @@ func TestRoleService_DeleteByUUID(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{})
+		}, &mockTenantRepo{}, &mockEventRepo{
+			createFn: func(*model.Event) (*model.Event, error) { return nil, errors.New("event failed") },
+		}, cache.NopInvalidator{})
+		_, err := svc.DeleteByUUID(context.Background(), roleUUID, tenantID, actorUUID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "event failed")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		role := newRole(1, "admin", tenantID)
+		events := &mockEventRepo{}
+		svc := NewRoleService(db, &mockRoleRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) { return role, nil },
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
+				return roleActorUser(tenantID), nil
+			},
+		}, &mockTenantRepo{}, events, cache.NopInvalidator{})
 		result, err := svc.DeleteByUUID(context.Background(), roleUUID, tenantID, actorUUID)
 		require.NoError(t, err)
 		assert.NotNil(t, result)
+		require.Len(t, events.created, 1)
+		assert.Equal(t, model.EventTypeRoleDeleted, events.created[0].EventType)
+		assert.Equal(t, model.EventAggregateRole, events.created[0].AggregateType)
+		assert.Equal(t, role.RoleUUID, events.created[0].AggregateUUID)
+		assert.Equal(t, tenantID, events.created[0].TenantID)
 	})
 }
 
@@ -879,7 +1138,7 @@ func TestRoleService_AddRolePermissions(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewRoleService(db, &mockRoleRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) { return nil, nil },
-		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.AddRolePermissions(context.Background(), roleUUID, tenantID, []uuid.UUID{permUUID1}, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "role not found")
@@ -893,7 +1152,7 @@ func TestRoleService_AddRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) {
 				return newRole(1, "admin", 99), nil
 			},
-		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.AddRolePermissions(context.Background(), roleUUID, tenantID, []uuid.UUID{permUUID1}, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "access denied")
@@ -909,7 +1168,7 @@ func TestRoleService_AddRolePermissions(t *testing.T) {
 			},
 		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return nil, nil },
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.AddRolePermissions(context.Background(), roleUUID, tenantID, []uuid.UUID{permUUID1}, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "actor user not found")
@@ -927,7 +1186,7 @@ func TestRoleService_AddRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorNoIdentities(), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.AddRolePermissions(context.Background(), roleUUID, tenantID, []uuid.UUID{permUUID1}, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "no identities")
@@ -945,7 +1204,7 @@ func TestRoleService_AddRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.AddRolePermissions(context.Background(), roleUUID, tenantID, []uuid.UUID{permUUID1}, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "system role")
@@ -967,7 +1226,7 @@ func TestRoleService_AddRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.AddRolePermissions(context.Background(), roleUUID, tenantID, []uuid.UUID{permUUID1}, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "perm error")
@@ -989,7 +1248,7 @@ func TestRoleService_AddRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.AddRolePermissions(context.Background(), roleUUID, tenantID, []uuid.UUID{permUUID1, permUUID2}, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "one or more permissions not found")
@@ -1015,7 +1274,7 @@ func TestRoleService_AddRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.AddRolePermissions(context.Background(), roleUUID, tenantID, []uuid.UUID{permUUID1}, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "check error")
@@ -1041,7 +1300,7 @@ func TestRoleService_AddRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.AddRolePermissions(context.Background(), roleUUID, tenantID, []uuid.UUID{permUUID1}, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "create rp error")
@@ -1075,7 +1334,7 @@ func TestRoleService_AddRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		result, err := svc.AddRolePermissions(context.Background(), roleUUID, tenantID, []uuid.UUID{permUUID1}, actorUUID)
 		require.NoError(t, err)
 		assert.NotNil(t, result)
@@ -1106,7 +1365,7 @@ func TestRoleService_AddRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.AddRolePermissions(context.Background(), roleUUID, tenantID, []uuid.UUID{permUUID1}, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "fetch error")
@@ -1135,7 +1394,7 @@ func TestRoleService_AddRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		result, err := svc.AddRolePermissions(context.Background(), roleUUID, tenantID, []uuid.UUID{permUUID1}, actorUUID)
 		require.NoError(t, err)
 		assert.NotNil(t, result)
@@ -1151,7 +1410,7 @@ func TestRoleService_AddRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) {
 				return nil, errors.New("db error")
 			},
-		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.AddRolePermissions(context.Background(), roleUUID, tenantID, []uuid.UUID{permUUID1}, actorUUID)
 		require.Error(t, err)
 	})
@@ -1173,7 +1432,7 @@ func TestRoleService_RemoveRolePermissions(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewRoleService(db, &mockRoleRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) { return nil, nil },
-		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.RemoveRolePermissions(context.Background(), roleUUID, tenantID, permUUID, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "role not found")
@@ -1187,7 +1446,7 @@ func TestRoleService_RemoveRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) {
 				return newRole(1, "admin", 99), nil
 			},
-		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.RemoveRolePermissions(context.Background(), roleUUID, tenantID, permUUID, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "access denied")
@@ -1203,7 +1462,7 @@ func TestRoleService_RemoveRolePermissions(t *testing.T) {
 			},
 		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return nil, nil },
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.RemoveRolePermissions(context.Background(), roleUUID, tenantID, permUUID, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "actor user not found")
@@ -1221,7 +1480,7 @@ func TestRoleService_RemoveRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorNoIdentities(), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.RemoveRolePermissions(context.Background(), roleUUID, tenantID, permUUID, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "no identities")
@@ -1239,7 +1498,7 @@ func TestRoleService_RemoveRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.RemoveRolePermissions(context.Background(), roleUUID, tenantID, permUUID, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "system role")
@@ -1261,7 +1520,7 @@ func TestRoleService_RemoveRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.RemoveRolePermissions(context.Background(), roleUUID, tenantID, permUUID, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "perm error")
@@ -1281,7 +1540,7 @@ func TestRoleService_RemoveRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.RemoveRolePermissions(context.Background(), roleUUID, tenantID, permUUID, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "permission not found")
@@ -1307,7 +1566,7 @@ func TestRoleService_RemoveRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.RemoveRolePermissions(context.Background(), roleUUID, tenantID, permUUID, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "rp error")
@@ -1334,7 +1593,7 @@ func TestRoleService_RemoveRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		result, err := svc.RemoveRolePermissions(context.Background(), roleUUID, tenantID, permUUID, actorUUID)
 		require.NoError(t, err)
 		assert.NotNil(t, result)
@@ -1361,7 +1620,7 @@ func TestRoleService_RemoveRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.RemoveRolePermissions(context.Background(), roleUUID, tenantID, permUUID, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "fetch error")
@@ -1390,7 +1649,7 @@ func TestRoleService_RemoveRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.RemoveRolePermissions(context.Background(), roleUUID, tenantID, permUUID, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "remove error")
@@ -1421,7 +1680,7 @@ func TestRoleService_RemoveRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.RemoveRolePermissions(context.Background(), roleUUID, tenantID, permUUID, actorUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "fetch error")
@@ -1452,7 +1711,7 @@ func TestRoleService_RemoveRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
 				return roleActorUser(tenantID), nil
 			},
-		}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		result, err := svc.RemoveRolePermissions(context.Background(), roleUUID, tenantID, permUUID, actorUUID)
 		require.NoError(t, err)
 		assert.NotNil(t, result)
@@ -1466,7 +1725,7 @@ func TestRoleService_RemoveRolePermissions(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.Role, error) {
 				return nil, errors.New("db error")
 			},
-		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, cache.NopInvalidator{})
+		}, &mockPermissionRepo{}, &mockRolePermissionRepo{}, &mockUserRepo{}, &mockTenantRepo{}, &mockEventRepo{}, cache.NopInvalidator{})
 		_, err := svc.RemoveRolePermissions(context.Background(), roleUUID, tenantID, permUUID, actorUUID)
 		require.Error(t, err)
 	})
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newScopedRoleUser returns a user of tenant 1.
+func newScopedRoleUser() *model.User {
+	return &model.User{UserID: 9, UserUUID: uuid.New(), UserIdentities: []model.UserIdentity{{TenantID: 1}}}
+}
+
+// newScopedRole returns a role of tenant 1.
+func newScopedRole() *model.Role {
+	return &model.Role{RoleID: 20, RoleUUID: uuid.New(), TenantID: 1, Name: "helpdesk"}
+}
+
+// newScopedRoleClient returns a client of tenant 1.
+func newScopedRoleClient() *model.Client {
+	return &model.Client{ClientID: 30, ClientUUID: uuid.New(), TenantID: 1, Name: "billing"}
+}
+
+// newScopedAssignment returns a stored assignment of role to user on client,
+// or on group when client is nil, with its relationships loaded as the
+// repository returns them.
+func newScopedAssignment(user *model.User, role *model.Role, client *model.Client, group *model.Group) *model.ScopedUserRole {
+	a := &model.ScopedUserRole{ScopedUserRoleUUID: uuid.New(), UserID: user.UserID, RoleID: role.RoleID, Role: role}
+	if client != nil {
+		a.ClientID, a.Client = &client.ClientID, client
+	} else {
+		a.GroupID, a.Group = &group.GroupID, group
+	}
+	return a
+}
+
+// newScopedRoleService returns a ScopedRoleService whose repositories find
+// user, role, client and group.
+func newScopedRoleService(db *gorm.DB, scopedRepo *mockScopedUserRoleRepo, user *model.User, role *model.Role, client *model.Client, group *model.Group, eventRepo *mockEventRepo) ScopedRoleService {
+	userRepo := &mockUserRepo{findByUUIDFn: func(any, ...string) (*model.User, error) { return user, nil }}
+	roleRepo := &mockRoleRepo{findByUUIDsFn: func(ids []string, _ ...string) ([]model.Role, error) {
+		if len(ids) == 1 && ids[0] == role.RoleUUID.String() {
+			return []model.Role{*role}, nil
+		}
+		return nil, nil
+	}}
+	clientRepo := &mockClientRepo{findByUUIDAndTenantIDFn: func(id uuid.UUID, tenantID int64) (*model.Client, error) {
+		if id == client.ClientUUID && tenantID == client.TenantID {
+			return client, nil
+		}
+		return nil, nil
+	}}
+	groupRepo := &mockGroupRepo{findByUUIDAndTenantIDFn: func(id uuid.UUID, tenantID int64) (*model.Group, error) {
+		if id == group.GroupUUID && tenantID == group.TenantID {
+			return group, nil
+		}
+		return nil, nil
+	}}
+	return NewScopedRoleService(db, scopedRepo, userRepo, roleRepo, &mockTenantRepo{}, clientRepo, groupRepo, eventRepo, cache.NopInvalidator{})
+}
+
+func TestScopedRoleService_Assign(t *testing.T) {
+	t.Run("assigns a role for a client", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		user, role, client, group := newScopedRoleUser(), newScopedRole(), newScopedRoleClient(), newUserGroup(1, "support")
+		scopedRepo := &mockScopedUserRoleRepo{}
+		eventRepo := &mockEventRepo{}
+		var created *model.ScopedUserRole
+		scopedRepo.createFn = func(e *model.ScopedUserRole) (*model.ScopedUserRole, error) {
+			e.ScopedUserRoleUUID = uuid.New()
+			created = e
+			return e, nil
+		}
+		scopedRepo.findByUUIDAndUserIDFn = func(id uuid.UUID, _ int64) (*model.ScopedUserRole, error) {
+			a := newScopedAssignment(user, role, client, nil)
+			a.ScopedUserRoleUUID = id
+			return a, nil
+		}
+
+		result, err := newScopedRoleService(gormDB, scopedRepo, user, role, client, group, eventRepo).Assign(context.Background(), user.UserUUID, 1, role.RoleUUID, model.ScopedRoleResourceClient, client.ClientUUID)
+		require.NoError(t, err)
+		require.NotNil(t, created)
+		require.NotNil(t, created.ClientID)
+		assert.Equal(t, client.ClientID, *created.ClientID)
+		assert.Nil(t, created.GroupID)
+		assert.Equal(t, model.ScopedRoleResourceClient, result.ResourceType)
+		assert.Equal(t, client.ClientUUID, result.ResourceUUID)
+		assert.Equal(t, "billing", result.ResourceName)
+		assert.Equal(t, "helpdesk", result.Role.Name)
+
+		require.Len(t, eventRepo.created, 1)
+		assert.Equal(t, model.EventTypeUserScopedRoleAdded, eventRepo.created[0].EventType)
+		assert.Equal(t, user.UserUUID, eventRepo.created[0].AggregateUUID)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("existing assignment is returned without an event", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		user, role, client, group := newScopedRoleUser(), newScopedRole(), newScopedRoleClient(), newUserGroup(1, "support")
+		scopedRepo := &mockScopedUserRoleRepo{}
+		eventRepo := &mockEventRepo{}
+		existing := newScopedAssignment(user, role, nil, group)
+		scopedRepo.findExistingFn = func(_, _ int64, clientID, groupID *int64) (*model.ScopedUserRole, error) {
+			assert.Nil(t, clientID)
+			require.NotNil(t, groupID)
+			return existing, nil
+		}
+		scopedRepo.findByUUIDAndUserIDFn = func(uuid.UUID, int64) (*model.ScopedUserRole, error) { return existing, nil }
+		scopedRepo.createFn = func(*model.ScopedUserRole) (*model.ScopedUserRole, error) {
+			t.Fatal("existing assignment must not be created again")
+			return nil, nil
+		}
+
+		result, err := newScopedRoleService(gormDB, scopedRepo, user, role, client, group, eventRepo).Assign(context.Background(), user.UserUUID, 1, role.RoleUUID, model.ScopedRoleResourceGroup, group.GroupUUID)
+		require.NoError(t, err)
+		assert.Equal(t, existing.ScopedUserRoleUUID, result.ScopedRoleUUID)
+		assert.Empty(t, eventRepo.created)
+	})
+
+	t.Run("client of another tenant", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		user, role, client, group := newScopedRoleUser(), newScopedRole(), newScopedRoleClient(), newUserGroup(1, "support")
+		scopedRepo := &mockScopedUserRoleRepo{}
+		eventRepo := &mockEventRepo{}
+		client.TenantID = 2
+
+		_, err := newScopedRoleService(gormDB, scopedRepo, user, role, client, group, eventRepo).Assign(context.Background(), user.UserUUID, 1, role.RoleUUID, model.ScopedRoleResourceClient, client.ClientUUID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "client not found")
+		assert.Empty(t, eventRepo.created)
+	})
+
+	t.Run("unknown group", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		user, role, client, group := newScopedRoleUser(), newScopedRole(), newScopedRoleClient(), newUserGroup(1, "support")
+		scopedRepo := &mockScopedUserRoleRepo{}
+		eventRepo := &mockEventRepo{}
+		_, err := newScopedRoleService(gormDB, scopedRepo, user, role, client, group, eventRepo).Assign(context.Background(), user.UserUUID, 1, role.RoleUUID, model.ScopedRoleResourceGroup, uuid.New())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "group not found")
+	})
+
+	t.Run("role of another tenant", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		user, role, client, group := newScopedRoleUser(), newScopedRole(), newScopedRoleClient(), newUserGroup(1, "support")
+		scopedRepo := &mockScopedUserRoleRepo{}
+		eventRepo := &mockEventRepo{}
+		role.TenantID = 2
+
+		_, err := newScopedRoleService(gormDB, scopedRepo, user, role, client, group, eventRepo).Assign(context.Background(), user.UserUUID, 1, role.RoleUUID, model.ScopedRoleResourceClient, client.ClientUUID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "role not found")
+	})
+
+	t.Run("unsupported resource type", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		user, role, client, group := newScopedRoleUser(), newScopedRole(), newScopedRoleClient(), newUserGroup(1, "support")
+		scopedRepo := &mockScopedUserRoleRepo{}
+		eventRepo := &mockEventRepo{}
+		_, err := newScopedRoleService(gormDB, scopedRepo, user, role, client, group, eventRepo).Assign(context.Background(), user.UserUUID, 1, role.RoleUUID, "tenant", uuid.New())
+		var validation *apperror.ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+
+	t.Run("user of another tenant", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		user, role, client, group := newScopedRoleUser(), newScopedRole(), newScopedRoleClient(), newUserGroup(1, "support")
+		scopedRepo := &mockScopedUserRoleRepo{}
+		eventRepo := &mockEventRepo{}
+		user.UserIdentities = []model.UserIdentity{{TenantID: 2}}
+
+		_, err := newScopedRoleService(gormDB, scopedRepo, user, role, client, group, eventRepo).Assign(context.Background(), user.UserUUID, 1, role.RoleUUID, model.ScopedRoleResourceClient, client.ClientUUID)
+		code, ok := apperror.CodeOf(err)
+		require.True(t, ok)
+		assert.Equal(t, apperror.CodeUserNotFound, code)
+	})
+}
+
+func TestScopedRoleService_List(t *testing.T) {
+	user, role, client, group := newScopedRoleUser(), newScopedRole(), newScopedRoleClient(), newUserGroup(1, "support")
+	scopedRepo := &mockScopedUserRoleRepo{}
+	clientScoped := newScopedAssignment(user, role, client, nil)
+	otherTenant := newScopedAssignment(user, role, nil, group)
+	otherTenant.Group = newUserGroup(2, "elsewhere")
+	scopedRepo.findByUserIDFn = func(userID int64) ([]model.ScopedUserRole, error) {
+		assert.Equal(t, user.UserID, userID)
+		return []model.ScopedUserRole{*clientScoped, *otherTenant}, nil
+	}
+
+	result, err := newScopedRoleService(nil, scopedRepo, user, role, client, group, &mockEventRepo{}).List(context.Background(), user.UserUUID, 1)
+	require.NoError(t, err)
+	require.Len(t, result, 1, "assignments on resources of other tenants are left out")
+	assert.Equal(t, clientScoped.ScopedUserRoleUUID, result[0].ScopedRoleUUID)
+}
+
+func TestScopedRoleService_Remove(t *testing.T) {
+	t.Run("removes the assignment", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		user, role, client, group := newScopedRoleUser(), newScopedRole(), newScopedRoleClient(), newUserGroup(1, "support")
+		scopedRepo := &mockScopedUserRoleRepo{}
+		eventRepo := &mockEventRepo{}
+		existing := newScopedAssignment(user, role, nil, group)
+		scopedRepo.findByUUIDAndUserIDFn = func(uuid.UUID, int64) (*model.ScopedUserRole, error) { return existing, nil }
+		var deleted any
+		scopedRepo.deleteByUUIDFn = func(id any) error {
+			deleted = id
+			return nil
+		}
+
+		result, err := newScopedRoleService(gormDB, scopedRepo, user, role, client, group, eventRepo).Remove(context.Background(), user.UserUUID, 1, existing.ScopedUserRoleUUID)
+		require.NoError(t, err)
+		assert.Equal(t, existing.ScopedUserRoleUUID, deleted)
+		assert.Equal(t, group.GroupUUID, result.ResourceUUID)
+		require.Len(t, eventRepo.created, 1)
+		assert.Equal(t, model.EventTypeUserScopedRoleRemoved, eventRepo.created[0].EventType)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		user, role, client, group := newScopedRoleUser(), newScopedRole(), newScopedRoleClient(), newUserGroup(1, "support")
+		scopedRepo := &mockScopedUserRoleRepo{}
+		eventRepo := &mockEventRepo{}
+		_, err := newScopedRoleService(gormDB, scopedRepo, user, role, client, group, eventRepo).Remove(context.Background(), user.UserUUID, 1, uuid.New())
+		var notFound *apperror.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("assignment on a resource of another tenant", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		user, role, client, group := newScopedRoleUser(), newScopedRole(), newScopedRoleClient(), newUserGroup(1, "support")
+		scopedRepo := &mockScopedUserRoleRepo{}
+		eventRepo := &mockEventRepo{}
+		existing := newScopedAssignment(user, role, client, nil)
+		existing.Client = &model.Client{ClientID: 31, ClientUUID: uuid.New(), TenantID: 2}
+		scopedRepo.findByUUIDAndUserIDFn = func(uuid.UUID, int64) (*model.ScopedUserRole, error) { return existing, nil }
+
+		_, err := newScopedRoleService(gormDB, scopedRepo, user, role, client, group, eventRepo).Remove(context.Background(), user.UserUUID, 1, existing.ScopedUserRoleUUID)
+		var notFound *apperror.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+		assert.Empty(t, eventRepo.created)
+	})
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/security"
+)
+
+// rejectPastedSecrets scans a plaintext JSON field for values that look like
+// credentials. High-confidence matches reject the write; weaker ones are
+// accepted and logged as a security event so an operator can follow up.
+// field names the column in messages, e.g. "config" or "metadata". Values are
+// never echoed back or logged.
+func rejectPastedSecrets(field string, raw []byte) error {
+	var blocked []string
+	for _, f := range security.ScanJSONForSecrets(raw) {
+		path := field
+		if f.Path != "" {
+			path = field + "." + f.Path
+		}
+		if f.Block {
+			blocked = append(blocked, fmt.Sprintf("%s (%s)", path, f.Kind))
+			continue
+		}
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "possible_secret_in_config",
+			Timestamp: time.Now(),
+			Details:   fmt.Sprintf("%s may contain a %s", path, f.Kind),
+			Severity:  "MEDIUM",
+		})
+	}
+	if len(blocked) > 0 {
+		return apperror.NewValidation("secrets must not be stored in plaintext fields, remove: " + strings.Join(blocked, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Who put an account on security hold, as recorded in the audit log.
+const (
+	securityHoldSourceAdmin           = "admin"
+	securityHoldSourceAnomalyDetector = "anomaly_detector"
+)
+
+// SecurityHoldService puts accounts that look compromised on security hold.
+// A held account cannot sign in until the user recovers it with a recovery
+// code (see AccountRecoveryService) or an admin reviews and re-enables it
+// (UserService.SetStatus).
+type SecurityHoldService interface {
+	// Hold puts a user of the tenant on security hold on behalf of an admin.
+	// Only an active account can be held.
+	Hold(ctx context.Context, userUUID uuid.UUID, tenantID int64, actorUserUUID uuid.UUID, reason string) (*UserServiceDataResult, error)
+	// HoldSuspicious puts the user on security hold for the login anomaly
+	// detector.
+	HoldSuspicious(ctx context.Context, user *model.User, tenantID int64, reason string) error
+}
+
+type securityHoldService struct {
+	db                  *gorm.DB
+	userRepo            repository.UserRepository
+	userTokenRepo       repository.UserTokenRepository
+	refreshTokenRepo    repository.OAuthRefreshTokenRepository
+	eventRepo           repository.EventRepository
+	notificationService NotificationService
+	authEventService    AuthEventService
+	cacheInvalidator    cache.Invalidator
+}
+
+// NewSecurityHoldService creates a SecurityHoldService.
+func NewSecurityHoldService(
+	db *gorm.DB,
+	userRepo repository.UserRepository,
+	userTokenRepo repository.UserTokenRepository,
+	refreshTokenRepo repository.OAuthRefreshTokenRepository,
+	eventRepo repository.EventRepository,
+	notificationService NotificationService,
+	authEventService AuthEventService,
+	cacheInvalidator cache.Invalidator,
+) SecurityHoldService {
+	return &securityHoldService{
+		db:                  db,
+		userRepo:            userRepo,
+		userTokenRepo:       userTokenRepo,
+		refreshTokenRepo:    refreshTokenRepo,
+		eventRepo:           eventRepo,
+		notificationService: notificationService,
+		authEventService:    authEventService,
+		cacheInvalidator:    cacheInvalidator,
+	}
+}
+
+func (s *securityHoldService) Hold(ctx context.Context, userUUID uuid.UUID, tenantID int64, actorUserUUID uuid.UUID, reason string) (*UserServiceDataResult, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "securityHold.hold")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", userUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	user, err := findTenantUser(s.userRepo, userUUID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find user failed")
+		return nil, err
+	}
+	if user.DeletedAt != nil || user.Status != model.StatusActive {
+		return nil, apperror.NewValidation("only an active account can be put on security hold")
+	}
+	actor, err := s.userRepo.FindByUUID(actorUserUUID)
+	if err != nil || actor == nil {
+		return nil, apperror.NewNotFoundWithReason("actor user not found")
+	}
+
+	heldUser, err := s.place(ctx, user, tenantID, &actor.UserID, securityHoldSourceAdmin, reason)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "place security hold failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toUserServiceDataResult(heldUser), nil
+}
+
+func (s *securityHoldService) HoldSuspicious(ctx context.Context, user *model.User, tenantID int64, reason string) error {
+	ctx, span := otel.Tracer("service").Start(ctx, "securityHold.holdSuspicious")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", user.UserUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	if _, err := s.place(ctx, user, tenantID, nil, securityHoldSourceAnomalyDetector, reason); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "place security hold failed")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// place puts the user on security hold and ends every session and pending
+// emailed link, then audits the hold and notifies the user. Recovery codes
+// are kept, so the user can still recover the account.
+func (s *securityHoldService) place(ctx context.Context, user *model.User, tenantID int64, actorUserID *int64, source, reason string) (*model.User, error) {
+	var heldUser *model.User
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txUserRepo := s.userRepo.WithTx(tx)
+
+		if err := txUserRepo.SetStatus(user.UserUUID, model.StatusSecurityHold); err != nil {
+			return err
+		}
+		if _, err := s.refreshTokenRepo.WithTx(tx).RevokeByUserID(user.UserID); err != nil {
+			return err
+		}
+		if err := s.userTokenRepo.WithTx(tx).RevokeAllByUserID(user.UserID); err != nil {
+			return err
+		}
+
+		var err error
+		heldUser, err = txUserRepo.FindByUUID(user.UserUUID, "UserIdentities.Client", "UserIdentities.Tenant", "Roles")
+		if err != nil {
+			return err
+		}
+		if heldUser == nil {
+			return apperror.WithCode(apperror.CodeUserNotFound, apperror.NewNotFound("user"))
+		}
+
+		return recordEvent(s.eventRepo.WithTx(tx), tenantID, UserUpdated(newUserEventPayload(heldUser)))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, identity := range heldUser.UserIdentities {
+		s.cacheInvalidator.InvalidateUserAll(ctx, identity.Sub)
+	}
+
+	raw, _ := json.Marshal(map[string]string{"user_uuid": user.UserUUID.String(), "source": source})
+	s.authEventService.Log(ctx, AuthEventInput{
+		TenantID:     tenantID,
+		ActorUserID:  actorUserID,
+		TargetUserID: &user.UserID,
+		IPAddress:    middleware.ClientIPFromContext(ctx),
+		UserAgent:    ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:     model.AuthEventCategoryUser,
+		EventType:    model.AuthEventTypeUserSecurityHold,
+		Severity:     model.AuthEventSeverityCritical,
+		Result:       model.AuthEventResultSuccess,
+		Description:  ptr.Ptr(reason),
+		Metadata:     datatypes.JSON(raw),
+	})
+	security.LogSecurityEvent(security.SecurityEvent{
+		EventType: "security_hold_placed",
+		TenantID:  tenantID,
+		UserID:    user.UserUUID.String(),
+		ClientIP:  middleware.ClientIPFromContext(ctx),
+		Timestamp: time.Now(),
+		Details:   fmt.Sprintf("Account put on security hold by %s: %s", source, reason),
+		Severity:  "HIGH",
+	})
+	s.notifyHeld(ctx, heldUser, tenantID)
+
+	return heldUser, nil
+}
+
+// notifyHeld tells the user their account is on hold and how to recover it.
+// It is a mandatory notification. The reason is kept out of it, as admins
+// may record details the user should not see.
+func (s *securityHoldService) notifyHeld(ctx context.Context, user *model.User, tenantID int64) {
+	data := struct {
+		RecoverURL string
+		LogoURL    string
+	}{
+		RecoverURL: config.AccountHostname + "/account/recover",
+		LogoURL:    config.EmailLogo,
+	}
+	s.notificationService.Notify(ctx, NotifyInput{
+		TenantID:      tenantID,
+		User:          user,
+		Type:          model.NotificationTypeSecurityHold,
+		Mandatory:     true,
+		Title:         "Your account is on hold",
+		Message:       "We put your account on hold after suspicious activity. Recover it with a recovery code, or contact your administrator.",
+		EmailTemplate: "internal:user:account:security_hold",
+		EmailData:     data,
+	})
+}
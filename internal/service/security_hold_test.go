@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/email"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newHoldUser returns an active user of tenant 2.
+func newHoldUser() *model.User {
+	return &model.User{
+		UserID:         9,
+		UserUUID:       uuid.New(),
+		Email:          "jane@example.com",
+		Status:         model.StatusActive,
+		UserIdentities: []model.UserIdentity{{TenantID: 2, Sub: "sub-jane"}},
+	}
+}
+
+// holdUserRepo returns a user repo that finds user, or the actor with
+// actorUUID, and records the status it sets in status.
+func holdUserRepo(user *model.User, actorUUID uuid.UUID, status *string) *mockUserRepo {
+	return &mockUserRepo{
+		findByUUIDFn: func(id any, _ ...string) (*model.User, error) {
+			if id == actorUUID {
+				return &model.User{UserID: 1, UserUUID: actorUUID}, nil
+			}
+			return user, nil
+		},
+		setStatusFn: func(_ uuid.UUID, s string) error {
+			*status = s
+			return nil
+		},
+	}
+}
+
+func newSecurityHoldService(db *gorm.DB, userRepo *mockUserRepo, tokenRepo *mockUserTokenRepo, refreshTokenRepo *mockOAuthRefreshTokenRepo, eventRepo *mockEventRepo, authEvents AuthEventService) SecurityHoldService {
+	templateRepo := &mockEmailTemplateRepo{findByNameFn: func(name string) (*model.EmailTemplate, error) {
+		return &model.EmailTemplate{Name: name, Subject: "Your account is on hold", BodyHTML: "{{.RecoverURL}}"}, nil
+	}}
+	notifications := NewNotificationService(&mockNotificationSettingRepo{}, &mockNotificationLogRepo{}, &mockUserRepo{}, templateRepo)
+	return NewSecurityHoldService(db, userRepo, tokenRepo, refreshTokenRepo, eventRepo, notifications, authEvents, cache.NopInvalidator{})
+}
+
+func TestSecurityHoldService_Hold(t *testing.T) {
+	actorUUID := uuid.New()
+
+	t.Run("signs out, audits and notifies", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		var sent []email.SendEmailParams
+		origSendEmail := email.SendEmail
+		defer func() { email.SendEmail = origSendEmail }()
+		email.SendEmail = func(_ context.Context, p email.SendEmailParams) error {
+			sent = append(sent, p)
+			return nil
+		}
+
+		user := newHoldUser()
+		var status string
+		var signedOut, linksEnded bool
+		var logged []AuthEventInput
+		eventRepo := &mockEventRepo{}
+		svc := newSecurityHoldService(gormDB, holdUserRepo(user, actorUUID, &status),
+			&mockUserTokenRepo{revokeAllByUserIDFn: func(int64) error { linksEnded = true; return nil }},
+			&mockOAuthRefreshTokenRepo{revokeByUserIDFn: func(int64) (int64, error) { signedOut = true; return 1, nil }},
+			eventRepo,
+			&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) }})
+
+		res, err := svc.Hold(context.Background(), user.UserUUID, 2, actorUUID, "credentials found in a paste")
+		require.NoError(t, err)
+		require.NotNil(t, res)
+		assert.Equal(t, model.StatusSecurityHold, status)
+		assert.True(t, signedOut)
+		assert.True(t, linksEnded)
+		assert.Len(t, eventRepo.created, 1)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeUserSecurityHold, logged[0].EventType)
+		assert.Equal(t, int64(1), *logged[0].ActorUserID)
+		assert.Equal(t, "credentials found in a paste", *logged[0].Description)
+		require.Len(t, sent, 1)
+		assert.Equal(t, "jane@example.com", sent[0].To)
+		assert.NotContains(t, sent[0].BodyHTML, "paste", "the reason is kept from the user")
+	})
+
+	t.Run("inactive account → validation error", func(t *testing.T) {
+		gormDB, _ := newMockGormDB(t)
+		user := newHoldUser()
+		user.Status = model.StatusSuspended
+		var status string
+		svc := newSecurityHoldService(gormDB, holdUserRepo(user, actorUUID, &status), &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockEventRepo{}, &mockAuthEventService{})
+
+		_, err := svc.Hold(context.Background(), user.UserUUID, 2, actorUUID, "credentials found in a paste")
+		var want *apperror.ValidationError
+		require.ErrorAs(t, err, &want)
+		assert.Empty(t, status)
+	})
+
+	t.Run("user of another tenant → not found", func(t *testing.T) {
+		gormDB, _ := newMockGormDB(t)
+		user := newHoldUser()
+		var status string
+		svc := newSecurityHoldService(gormDB, holdUserRepo(user, actorUUID, &status), &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockEventRepo{}, &mockAuthEventService{})
+
+		_, err := svc.Hold(context.Background(), user.UserUUID, 3, actorUUID, "credentials found in a paste")
+		code, ok := apperror.CodeOf(err)
+		require.True(t, ok)
+		assert.Equal(t, apperror.CodeUserNotFound, code)
+	})
+}
+
+func TestSecurityHoldService_HoldSuspicious(t *testing.T) {
+	gormDB, mock := newMockGormDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	origSendEmail := email.SendEmail
+	defer func() { email.SendEmail = origSendEmail }()
+	email.SendEmail = func(_ context.Context, _ email.SendEmailParams) error { return nil }
+
+	user := newHoldUser()
+	var status string
+	var logged []AuthEventInput
+	svc := newSecurityHoldService(gormDB, holdUserRepo(user, uuid.New(), &status), &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockEventRepo{},
+		&mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) }})
+
+	require.NoError(t, svc.HoldSuspicious(context.Background(), user, 2, "Impossible travel detected at login"))
+	assert.Equal(t, model.StatusSecurityHold, status)
+	require.Len(t, logged, 1)
+	assert.Nil(t, logged[0].ActorUserID, "the anomaly detector holds without an actor")
+	assert.Contains(t, string(logged[0].Metadata), `"source":"anomaly_detector"`)
+}
@@ -55,17 +55,20 @@ type securitySettingService struct {
 	db                        *gorm.DB
 	securitySettingRepo       repository.SecuritySettingRepository
 	securitySettingsAuditRepo repository.SecuritySettingsAuditRepository
+	planEnforcer              PlanEnforcer
 }
 
 func NewSecuritySettingService(
 	db *gorm.DB,
 	securitySettingRepo repository.SecuritySettingRepository,
 	securitySettingsAuditRepo repository.SecuritySettingsAuditRepository,
+	planEnforcer PlanEnforcer,
 ) SecuritySettingService {
 	return &securitySettingService{
 		db:                        db,
 		securitySettingRepo:       securitySettingRepo,
 		securitySettingsAuditRepo: securitySettingsAuditRepo,
+		planEnforcer:              planEnforcer,
 	}
 }
 
@@ -230,7 +233,15 @@ func (s *securitySettingService) UpdateMFAConfig(ctx context.Context, userPoolID
 	defer span.End()
 	span.SetAttributes(attribute.Int64("user_pool.id", userPoolID))
 
-	result, err := s.updateConfig(userPoolID, "general", config, updatedBy, ipAddress, userAgent)
+	// Security settings are keyed by the tenant ID, like in
+	// trustedDeviceService.trustPeriod.
+	if err := s.planEnforcer.CheckFeature(ctx, userPoolID, model.PlanFeatureMFA); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "mfa not in plan")
+		return nil, err
+	}
+
+	result, err := s.updateConfig(userPoolID, "mfa", config, updatedBy, ipAddress, userAgent)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "update mfa config failed")
@@ -245,6 +256,12 @@ func (s *securitySettingService) UpdatePasswordConfig(ctx context.Context, userP
 	defer span.End()
 	span.SetAttributes(attribute.Int64("user_pool.id", userPoolID))
 
+	if err := validatePasswordHashConfig(config); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid password config")
+		return nil, err
+	}
+
 	result, err := s.updateConfig(userPoolID, "password", config, updatedBy, ipAddress, userAgent)
 	if err != nil {
 		span.RecordError(err)
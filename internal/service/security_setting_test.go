@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,7 +15,7 @@ import (
 )
 
 func newSecuritySettingSvc(repo *mockSecuritySettingRepo, auditRepo *mockSecuritySettingsAuditRepo) SecuritySettingService {
-	return NewSecuritySettingService(nil, repo, auditRepo)
+	return NewSecuritySettingService(nil, repo, auditRepo, NopPlanEnforcer{})
 }
 
 // helper: minimal SecuritySetting fixture
@@ -257,13 +258,25 @@ func TestSecuritySettingService_UpdateMFAConfig(t *testing.T) {
 	updatedBy := int64(10)
 	cfg := map[string]any{"enforce_mfa": true}
 
+	t.Run("MFA not in plan → forbidden", func(t *testing.T) {
+		db, _ := newMockGormDB(t)
+		plans := NewPlanService(nil, &mockPlanRepo{}, planTenantRepo(&model.Tenant{
+			TenantID: userPoolID,
+			Plan:     &model.Plan{Name: "free", Features: datatypes.JSON(`{}`)},
+		}), &mockUserIdentityRepo{}, &mockClientRepo{})
+		svc := NewSecuritySettingService(db, &mockSecuritySettingRepo{}, &mockSecuritySettingsAuditRepo{}, plans)
+		_, err := svc.UpdateMFAConfig(context.Background(), userPoolID, cfg, updatedBy, "1.2.3.4", "agent")
+		code, _ := apperror.CodeOf(err)
+		assert.Equal(t, apperror.CodePlanFeatureDisabled, code)
+	})
+
 	t.Run("FindByUserPoolID error → rollback", func(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
 		mock.ExpectRollback()
 		svc := NewSecuritySettingService(db, &mockSecuritySettingRepo{
 			findByUserPoolIDFn: func(_ int64) (*model.SecuritySetting, error) { return nil, errors.New("db") },
-		}, &mockSecuritySettingsAuditRepo{})
+		}, &mockSecuritySettingsAuditRepo{}, NopPlanEnforcer{})
 		_, err := svc.UpdateMFAConfig(context.Background(), userPoolID, cfg, updatedBy, "1.2.3.4", "agent")
 		require.Error(t, err)
 	})
@@ -287,7 +300,7 @@ func TestSecuritySettingService_UpdateMFAConfig(t *testing.T) {
 				ss.Version = 2
 				return ss, nil
 			},
-		}, &mockSecuritySettingsAuditRepo{})
+		}, &mockSecuritySettingsAuditRepo{}, NopPlanEnforcer{})
 		res, err := svc.UpdateMFAConfig(context.Background(), userPoolID, cfg, updatedBy, "1.2.3.4", "agent")
 		require.NoError(t, err)
 		assert.Equal(t, 2, res.Version)
@@ -304,12 +317,45 @@ func TestSecuritySettingService_UpdateMFAConfig(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.SecuritySetting, error) {
 				return existing, nil
 			},
-		}, &mockSecuritySettingsAuditRepo{})
+		}, &mockSecuritySettingsAuditRepo{}, NopPlanEnforcer{})
 		res, err := svc.UpdateMFAConfig(context.Background(), userPoolID, cfg, updatedBy, "1.2.3.4", "agent")
 		require.NoError(t, err)
 		assert.NotNil(t, res)
 	})
 
+	t.Run("saves the MFA config only", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		existing := newSecSetting(userPoolID)
+		existing.MFAConfig = datatypes.JSON(`{"enforce_mfa":false}`)
+		existing.PasswordConfig = datatypes.JSON(`{"min_length":12}`)
+		var saved *model.SecuritySetting
+		var audit *model.SecuritySettingsAudit
+		svc := NewSecuritySettingService(db, &mockSecuritySettingRepo{
+			findByUserPoolIDFn: func(_ int64) (*model.SecuritySetting, error) { return existing, nil },
+			createOrUpdateFn: func(e *model.SecuritySetting) (*model.SecuritySetting, error) {
+				saved = e
+				return e, nil
+			},
+			findByUUIDFn: func(_ any, _ ...string) (*model.SecuritySetting, error) { return existing, nil },
+		}, &mockSecuritySettingsAuditRepo{
+			createFn: func(e *model.SecuritySettingsAudit) (*model.SecuritySettingsAudit, error) {
+				audit = e
+				return e, nil
+			},
+		}, NopPlanEnforcer{})
+		_, err := svc.UpdateMFAConfig(context.Background(), userPoolID, cfg, updatedBy, "1.2.3.4", "agent")
+		require.NoError(t, err)
+
+		require.NotNil(t, saved)
+		assert.JSONEq(t, `{"enforce_mfa":true}`, string(saved.MFAConfig))
+		assert.JSONEq(t, `{"min_length":12}`, string(saved.PasswordConfig))
+		require.NotNil(t, audit)
+		assert.Equal(t, "update_mfa_config", audit.ChangeType)
+		assert.JSONEq(t, `{"enforce_mfa":false}`, string(audit.OldConfig))
+	})
+
 	t.Run("marshal error → rollback", func(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
@@ -317,7 +363,7 @@ func TestSecuritySettingService_UpdateMFAConfig(t *testing.T) {
 		existing := newSecSetting(userPoolID)
 		svc := NewSecuritySettingService(db, &mockSecuritySettingRepo{
 			findByUserPoolIDFn: func(_ int64) (*model.SecuritySetting, error) { return existing, nil },
-		}, &mockSecuritySettingsAuditRepo{})
+		}, &mockSecuritySettingsAuditRepo{}, NopPlanEnforcer{})
 		badCfg := map[string]any{"bad": math.Inf(1)}
 		_, err := svc.UpdateMFAConfig(context.Background(), userPoolID, badCfg, updatedBy, "1.2.3.4", "agent")
 		require.Error(t, err)
@@ -333,7 +379,7 @@ func TestSecuritySettingService_UpdateMFAConfig(t *testing.T) {
 			createOrUpdateFn: func(_ *model.SecuritySetting) (*model.SecuritySetting, error) {
 				return nil, errors.New("save error")
 			},
-		}, &mockSecuritySettingsAuditRepo{})
+		}, &mockSecuritySettingsAuditRepo{}, NopPlanEnforcer{})
 		_, err := svc.UpdateMFAConfig(context.Background(), userPoolID, cfg, updatedBy, "1.2.3.4", "agent")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "save error")
@@ -348,7 +394,7 @@ func TestSecuritySettingService_UpdateMFAConfig(t *testing.T) {
 			findByUserPoolIDFn: func(_ int64) (*model.SecuritySetting, error) { return existing, nil },
 			createOrUpdateFn:   func(e *model.SecuritySetting) (*model.SecuritySetting, error) { return e, nil },
 			incrementVersionFn: func(_ int64) error { return errors.New("version error") },
-		}, &mockSecuritySettingsAuditRepo{})
+		}, &mockSecuritySettingsAuditRepo{}, NopPlanEnforcer{})
 		_, err := svc.UpdateMFAConfig(context.Background(), userPoolID, cfg, updatedBy, "1.2.3.4", "agent")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "version error")
@@ -366,7 +412,7 @@ func TestSecuritySettingService_UpdateMFAConfig(t *testing.T) {
 			createFn: func(_ *model.SecuritySettingsAudit) (*model.SecuritySettingsAudit, error) {
 				return nil, errors.New("audit error")
 			},
-		})
+		}, NopPlanEnforcer{})
 		_, err := svc.UpdateMFAConfig(context.Background(), userPoolID, cfg, updatedBy, "1.2.3.4", "agent")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "audit error")
@@ -383,7 +429,7 @@ func TestSecuritySettingService_UpdateMFAConfig(t *testing.T) {
 			findByUUIDFn: func(_ any, _ ...string) (*model.SecuritySetting, error) {
 				return nil, errors.New("refresh error")
 			},
-		}, &mockSecuritySettingsAuditRepo{})
+		}, &mockSecuritySettingsAuditRepo{}, NopPlanEnforcer{})
 		_, err := svc.UpdateMFAConfig(context.Background(), userPoolID, cfg, updatedBy, "1.2.3.4", "agent")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "refresh error")
@@ -398,7 +444,7 @@ func TestSecuritySettingService_UpdateMFAConfig(t *testing.T) {
 			createFn: func(_ *model.SecuritySetting) (*model.SecuritySetting, error) {
 				return nil, errors.New("create error")
 			},
-		}, &mockSecuritySettingsAuditRepo{})
+		}, &mockSecuritySettingsAuditRepo{}, NopPlanEnforcer{})
 		_, err := svc.UpdateMFAConfig(context.Background(), userPoolID, cfg, updatedBy, "1.2.3.4", "agent")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "create error")
@@ -422,7 +468,7 @@ func TestSecuritySettingService_UpdatePasswordConfig(t *testing.T) {
 			findByUserPoolIDFn: func(_ int64) (*model.SecuritySetting, error) { return existing, nil },
 			createOrUpdateFn:   func(e *model.SecuritySetting) (*model.SecuritySetting, error) { return e, nil },
 			findByUUIDFn:       func(_ any, _ ...string) (*model.SecuritySetting, error) { return existing, nil },
-		}, &mockSecuritySettingsAuditRepo{})
+		}, &mockSecuritySettingsAuditRepo{}, NopPlanEnforcer{})
 		res, err := svc.UpdatePasswordConfig(context.Background(), userPoolID, cfg, 10, "1.2.3.4", "agent")
 		require.NoError(t, err)
 		assert.NotNil(t, res)
@@ -434,7 +480,7 @@ func TestSecuritySettingService_UpdatePasswordConfig(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewSecuritySettingService(db, &mockSecuritySettingRepo{
 			findByUserPoolIDFn: func(_ int64) (*model.SecuritySetting, error) { return nil, errors.New("db") },
-		}, &mockSecuritySettingsAuditRepo{})
+		}, &mockSecuritySettingsAuditRepo{}, NopPlanEnforcer{})
 		_, err := svc.UpdatePasswordConfig(context.Background(), userPoolID, cfg, 10, "1.2.3.4", "agent")
 		require.Error(t, err)
 	})
@@ -457,7 +503,7 @@ func TestSecuritySettingService_UpdateSessionConfig(t *testing.T) {
 			findByUserPoolIDFn: func(_ int64) (*model.SecuritySetting, error) { return existing, nil },
 			createOrUpdateFn:   func(e *model.SecuritySetting) (*model.SecuritySetting, error) { return e, nil },
 			findByUUIDFn:       func(_ any, _ ...string) (*model.SecuritySetting, error) { return existing, nil },
-		}, &mockSecuritySettingsAuditRepo{})
+		}, &mockSecuritySettingsAuditRepo{}, NopPlanEnforcer{})
 		res, err := svc.UpdateSessionConfig(context.Background(), userPoolID, cfg, 10, "1.2.3.4", "agent")
 		require.NoError(t, err)
 		assert.NotNil(t, res)
@@ -469,7 +515,7 @@ func TestSecuritySettingService_UpdateSessionConfig(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewSecuritySettingService(db, &mockSecuritySettingRepo{
 			findByUserPoolIDFn: func(_ int64) (*model.SecuritySetting, error) { return nil, errors.New("db") },
-		}, &mockSecuritySettingsAuditRepo{})
+		}, &mockSecuritySettingsAuditRepo{}, NopPlanEnforcer{})
 		_, err := svc.UpdateSessionConfig(context.Background(), userPoolID, cfg, 10, "1.2.3.4", "agent")
 		require.Error(t, err)
 	})
@@ -492,7 +538,7 @@ func TestSecuritySettingService_UpdateThreatConfig(t *testing.T) {
 			findByUserPoolIDFn: func(_ int64) (*model.SecuritySetting, error) { return existing, nil },
 			createOrUpdateFn:   func(e *model.SecuritySetting) (*model.SecuritySetting, error) { return e, nil },
 			findByUUIDFn:       func(_ any, _ ...string) (*model.SecuritySetting, error) { return existing, nil },
-		}, &mockSecuritySettingsAuditRepo{})
+		}, &mockSecuritySettingsAuditRepo{}, NopPlanEnforcer{})
 		res, err := svc.UpdateThreatConfig(context.Background(), userPoolID, cfg, 10, "1.2.3.4", "agent")
 		require.NoError(t, err)
 		assert.NotNil(t, res)
@@ -504,7 +550,7 @@ func TestSecuritySettingService_UpdateThreatConfig(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewSecuritySettingService(db, &mockSecuritySettingRepo{
 			findByUserPoolIDFn: func(_ int64) (*model.SecuritySetting, error) { return nil, errors.New("db") },
-		}, &mockSecuritySettingsAuditRepo{})
+		}, &mockSecuritySettingsAuditRepo{}, NopPlanEnforcer{})
 		_, err := svc.UpdateThreatConfig(context.Background(), userPoolID, cfg, 10, "1.2.3.4", "agent")
 		require.Error(t, err)
 	})
@@ -527,7 +573,7 @@ func TestSecuritySettingService_UpdateLockoutConfig(t *testing.T) {
 			findByUserPoolIDFn: func(_ int64) (*model.SecuritySetting, error) { return existing, nil },
 			createOrUpdateFn:   func(e *model.SecuritySetting) (*model.SecuritySetting, error) { return e, nil },
 			findByUUIDFn:       func(_ any, _ ...string) (*model.SecuritySetting, error) { return existing, nil },
-		}, &mockSecuritySettingsAuditRepo{})
+		}, &mockSecuritySettingsAuditRepo{}, NopPlanEnforcer{})
 		res, err := svc.UpdateLockoutConfig(context.Background(), userPoolID, cfg, 10, "1.2.3.4", "agent")
 		require.NoError(t, err)
 		assert.NotNil(t, res)
@@ -539,7 +585,7 @@ func TestSecuritySettingService_UpdateLockoutConfig(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewSecuritySettingService(db, &mockSecuritySettingRepo{
 			findByUserPoolIDFn: func(_ int64) (*model.SecuritySetting, error) { return nil, errors.New("db") },
-		}, &mockSecuritySettingsAuditRepo{})
+		}, &mockSecuritySettingsAuditRepo{}, NopPlanEnforcer{})
 		_, err := svc.UpdateLockoutConfig(context.Background(), userPoolID, cfg, 10, "1.2.3.4", "agent")
 		require.Error(t, err)
 	})
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+// SelfServiceTenantService lets a signed-in user create a tenant of their
+// own without an administrator.
+type SelfServiceTenantService interface {
+	// Create creates an active tenant, provisions its default API,
+	// permissions, identity provider, clients, roles, email templates and
+	// security settings, and makes the user its owner, all in a single
+	// transaction. It fails with a forbidden error once the user owns
+	// config.Current().SelfServiceTenantLimit tenants.
+	Create(ctx context.Context, userID int64, name string, displayName string, description string) (*TenantServiceDataResult, error)
+}
+
+type selfServiceTenantService struct {
+	db               *gorm.DB
+	tenantRepo       repository.TenantRepository
+	tenantMemberRepo repository.TenantMemberRepository
+	provisioner      TenantProvisioner
+}
+
+// NewSelfServiceTenantService creates a new SelfServiceTenantService.
+func NewSelfServiceTenantService(
+	db *gorm.DB,
+	tenantRepo repository.TenantRepository,
+	tenantMemberRepo repository.TenantMemberRepository,
+	provisioner TenantProvisioner,
+) SelfServiceTenantService {
+	return &selfServiceTenantService{
+		db:               db,
+		tenantRepo:       tenantRepo,
+		tenantMemberRepo: tenantMemberRepo,
+		provisioner:      provisioner,
+	}
+}
+
+func (s *selfServiceTenantService) Create(ctx context.Context, userID int64, name string, displayName string, description string) (*TenantServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "self_service_tenant.create")
+	defer span.End()
+	span.SetAttributes(attribute.String("tenant.name", name), attribute.Int64("user.id", userID))
+
+	var createdTenant *model.Tenant
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txTenantRepo := s.tenantRepo.WithTx(tx)
+		txTenantMemberRepo := s.tenantMemberRepo.WithTx(tx)
+
+		// Enforce the per-user cap on owned tenants
+		if limit := config.Current().SelfServiceTenantLimit; limit > 0 {
+			memberships, err := txTenantMemberRepo.FindAllByUser(userID)
+			if err != nil {
+				return err
+			}
+			owned := 0
+			for _, m := range memberships {
+				if m.Role == "owner" {
+					owned++
+				}
+			}
+			if owned >= limit {
+				return apperror.NewForbidden(fmt.Sprintf("self-service tenant limit of %d reached", limit))
+			}
+		}
+
+		// Check if tenant already exists
+		existingTenant, err := txTenantRepo.FindByName(name)
+		if err != nil {
+			return err
+		}
+		if existingTenant != nil {
+			return apperror.NewConflict(name + " tenant already exists")
+		}
+
+		// Generate identifier
+		identifier, err := crypto.GenerateIdentifier(12)
+		if err != nil {
+			return err
+		}
+
+		newTenant, err := txTenantRepo.Create(&model.Tenant{
+			Name:        name,
+			DisplayName: displayName,
+			Description: description,
+			Identifier:  identifier,
+			Status:      model.StatusActive,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Seed the tenant's default resources
+		if err := s.provisioner.Provision(ctx, tx, newTenant.TenantID); err != nil {
+			return err
+		}
+
+		if _, err := txTenantMemberRepo.Create(&model.TenantMember{
+			TenantID: newTenant.TenantID,
+			UserID:   userID,
+			Role:     "owner",
+		}); err != nil {
+			return err
+		}
+
+		// Fetch Tenant with relationships preloaded
+		createdTenant, err = txTenantRepo.FindByUUID(newTenant.TenantUUID)
+		return err
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "create self-service tenant failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toTenantServiceDataResult(createdTenant), nil
+}
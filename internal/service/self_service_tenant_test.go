@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/model"
+)
+
+func setSelfServiceTenantLimit(t *testing.T, limit int) {
+	t.Helper()
+	orig := config.Current()
+	t.Cleanup(func() { config.SetCurrent(orig) })
+	next := *orig
+	next.SelfServiceTenantLimit = limit
+	config.SetCurrent(&next)
+}
+
+func TestSelfServiceTenantService_Create(t *testing.T) {
+	ctx := context.Background()
+
+	newTenantRepo := func() *mockTenantRepo {
+		return &mockTenantRepo{
+			createFn: func(e *model.Tenant) (*model.Tenant, error) {
+				e.TenantID = 7
+				e.TenantUUID = uuid.New()
+				return e, nil
+			},
+			findByUUIDFn: func(id any, _ ...string) (*model.Tenant, error) {
+				return &model.Tenant{TenantID: 7, TenantUUID: id.(uuid.UUID), Name: "acme", Status: model.StatusActive}, nil
+			},
+		}
+	}
+
+	t.Run("success — provisions tenant and adds owner", func(t *testing.T) {
+		setSelfServiceTenantLimit(t, 3)
+		var provisioned int64
+		provisioner := &mockTenantProvisioner{provisionFn: func(_ context.Context, _ *gorm.DB, tenantID int64) error {
+			provisioned = tenantID
+			return nil
+		}}
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		var member *model.TenantMember
+		svc := NewSelfServiceTenantService(gormDB, newTenantRepo(), &mockTenantMemberRepo{
+			createFn: func(e *model.TenantMember) (*model.TenantMember, error) {
+				member = e
+				return e, nil
+			},
+		}, provisioner)
+
+		res, err := svc.Create(ctx, 42, "acme", "Acme", "Acme tenant")
+		require.NoError(t, err)
+		assert.Equal(t, "acme", res.Name)
+		assert.Equal(t, int64(7), provisioned)
+		require.NotNil(t, member)
+		assert.Equal(t, int64(7), member.TenantID)
+		assert.Equal(t, int64(42), member.UserID)
+		assert.Equal(t, "owner", member.Role)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("limit reached", func(t *testing.T) {
+		setSelfServiceTenantLimit(t, 1)
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		svc := NewSelfServiceTenantService(gormDB, newTenantRepo(), &mockTenantMemberRepo{
+			findAllByUserFn: func(_ int64) ([]model.TenantMember, error) {
+				return []model.TenantMember{{Role: "member"}, {Role: "owner"}}, nil
+			},
+		}, &mockTenantProvisioner{})
+
+		_, err := svc.Create(ctx, 42, "acme", "Acme", "Acme tenant")
+		var target *apperror.ForbiddenError
+		require.ErrorAs(t, err, &target)
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		setSelfServiceTenantLimit(t, 0)
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		svc := NewSelfServiceTenantService(gormDB, newTenantRepo(), &mockTenantMemberRepo{
+			findAllByUserFn: func(_ int64) ([]model.TenantMember, error) {
+				t.Fatal("memberships must not be counted without a limit")
+				return nil, nil
+			},
+		}, &mockTenantProvisioner{})
+
+		_, err := svc.Create(ctx, 42, "acme", "Acme", "Acme tenant")
+		require.NoError(t, err)
+	})
+
+	t.Run("name taken", func(t *testing.T) {
+		setSelfServiceTenantLimit(t, 3)
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		tenantRepo := newTenantRepo()
+		tenantRepo.findByNameFn = func(_ string) (*model.Tenant, error) { return &model.Tenant{}, nil }
+		svc := NewSelfServiceTenantService(gormDB, tenantRepo, &mockTenantMemberRepo{}, &mockTenantProvisioner{})
+
+		_, err := svc.Create(ctx, 42, "acme", "Acme", "Acme tenant")
+		var target *apperror.ConflictError
+		require.ErrorAs(t, err, &target)
+	})
+
+	t.Run("provisioning error rolls back", func(t *testing.T) {
+		setSelfServiceTenantLimit(t, 3)
+		provisioner := &mockTenantProvisioner{provisionFn: func(_ context.Context, _ *gorm.DB, _ int64) error {
+			return apperror.NewInternal("failed to provision tenant", errors.New("seed failed"))
+		}}
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		svc := NewSelfServiceTenantService(gormDB, newTenantRepo(), &mockTenantMemberRepo{
+			createFn: func(_ *model.TenantMember) (*model.TenantMember, error) {
+				t.Fatal("owner must not be added when provisioning fails")
+				return nil, nil
+			},
+		}, provisioner)
+
+		_, err := svc.Create(ctx, 42, "acme", "Acme", "Acme tenant")
+		var target *apperror.InternalError
+		require.ErrorAs(t, err, &target)
+	})
+
+	t.Run("owner membership error", func(t *testing.T) {
+		setSelfServiceTenantLimit(t, 3)
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		svc := NewSelfServiceTenantService(gormDB, newTenantRepo(), &mockTenantMemberRepo{
+			createFn: func(_ *model.TenantMember) (*model.TenantMember, error) {
+				return nil, errors.New("db error")
+			},
+		}, &mockTenantProvisioner{})
+
+		_, err := svc.Create(ctx, 42, "acme", "Acme", "Acme tenant")
+		require.Error(t, err)
+	})
+}
@@ -59,6 +59,7 @@ type ServiceService interface {
 	DeleteByUUID(ctx context.Context, serviceUUID uuid.UUID, tenantID int64) (*ServiceServiceDataResult, error)
 	AssignPolicy(ctx context.Context, serviceUUID uuid.UUID, policyUUID uuid.UUID, tenantID int64) error
 	RemovePolicy(ctx context.Context, serviceUUID uuid.UUID, policyUUID uuid.UUID, tenantID int64) error
+	GetPolicies(ctx context.Context, serviceUUID uuid.UUID, tenantID int64) ([]PolicyServiceDataResult, error)
 }
 
 type serviceService struct {
@@ -394,6 +395,7 @@ func (s *serviceService) AssignPolicy(ctx context.Context, serviceUUID uuid.UUID
 
 	err := s.db.Transaction(func(tx *gorm.DB) error {
 		txServiceRepo := s.serviceRepo.WithTx(tx)
+		txTenantServiceRepo := s.tenantServiceRepo.WithTx(tx)
 		txPolicyRepo := s.policyRepo.WithTx(tx)
 		txServicePolicyRepo := s.servicePolicyRepo.WithTx(tx)
 
@@ -406,6 +408,15 @@ func (s *serviceService) AssignPolicy(ctx context.Context, serviceUUID uuid.UUID
 			return apperror.NewNotFound("service not found")
 		}
 
+		// Verify service belongs to tenant
+		tenantService, err := txTenantServiceRepo.FindByTenantAndService(tenantID, service.ServiceID)
+		if err != nil {
+			return err
+		}
+		if tenantService == nil {
+			return apperror.NewNotFoundWithReason("service not found or access denied")
+		}
+
 		// Check if policy exists and belongs to the same tenant
 		policy, err := txPolicyRepo.FindByUUIDAndTenantID(policyUUID, tenantID)
 		if err != nil {
@@ -451,6 +462,7 @@ func (s *serviceService) RemovePolicy(ctx context.Context, serviceUUID uuid.UUID
 
 	err := s.db.Transaction(func(tx *gorm.DB) error {
 		txServiceRepo := s.serviceRepo.WithTx(tx)
+		txTenantServiceRepo := s.tenantServiceRepo.WithTx(tx)
 		txPolicyRepo := s.policyRepo.WithTx(tx)
 		txServicePolicyRepo := s.servicePolicyRepo.WithTx(tx)
 
@@ -463,6 +475,15 @@ func (s *serviceService) RemovePolicy(ctx context.Context, serviceUUID uuid.UUID
 			return apperror.NewNotFound("service not found")
 		}
 
+		// Verify service belongs to tenant
+		tenantService, err := txTenantServiceRepo.FindByTenantAndService(tenantID, service.ServiceID)
+		if err != nil {
+			return err
+		}
+		if tenantService == nil {
+			return apperror.NewNotFoundWithReason("service not found or access denied")
+		}
+
 		// Check if policy exists and belongs to the same tenant
 		policy, err := txPolicyRepo.FindByUUIDAndTenantID(policyUUID, tenantID)
 		if err != nil {
@@ -493,3 +514,52 @@ func (s *serviceService) RemovePolicy(ctx context.Context, serviceUUID uuid.UUID
 	span.SetStatus(codes.Ok, "")
 	return nil
 }
+
+func (s *serviceService) GetPolicies(ctx context.Context, serviceUUID uuid.UUID, tenantID int64) ([]PolicyServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "service.getPolicies")
+	defer span.End()
+	span.SetAttributes(attribute.String("service.uuid", serviceUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	service, err := s.serviceRepo.FindByUUID(serviceUUID)
+	if err != nil || service == nil {
+		span.SetStatus(codes.Error, "service not found")
+		return nil, apperror.NewNotFound("service not found")
+	}
+
+	// Verify service belongs to tenant
+	tenantService, err := s.tenantServiceRepo.FindByTenantAndService(tenantID, service.ServiceID)
+	if err != nil || tenantService == nil {
+		span.SetStatus(codes.Error, "service not found or access denied")
+		return nil, apperror.NewNotFoundWithReason("service not found or access denied")
+	}
+
+	policies, err := s.servicePolicyRepo.FindPoliciesByServiceID(service.ServiceID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get policies failed")
+		return nil, err
+	}
+
+	// A shared service may carry policies of other tenants; only the
+	// caller's own are shown
+	data := make([]PolicyServiceDataResult, 0, len(policies))
+	for _, policy := range policies {
+		if policy.TenantID != tenantID {
+			continue
+		}
+		data = append(data, PolicyServiceDataResult{
+			PolicyUUID:  policy.PolicyUUID,
+			Name:        policy.Name,
+			Description: policy.Description,
+			Document:    policy.Document,
+			Version:     policy.Version,
+			Status:      policy.Status,
+			IsSystem:    policy.IsSystem,
+			CreatedAt:   policy.CreatedAt,
+			UpdatedAt:   policy.UpdatedAt,
+		})
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return data, nil
+}
@@ -558,6 +558,22 @@ func TestServiceService_AssignPolicy(t *testing.T) {
 		assert.Contains(t, err.Error(), "service not found")
 	})
 
+	t.Run("service of another tenant → rollback", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := NewServiceService(db, &mockServiceRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Service, error) {
+				return &model.Service{ServiceID: 1, ServiceUUID: svcUUID}, nil
+			},
+		}, &mockTenantServiceRepo{
+			findByTenantAndServiceFn: func(_, _ int64) (*model.TenantService, error) { return nil, nil },
+		}, &mockAPIRepo{}, &mockServicePolicyRepo{}, &mockPolicyRepo{})
+		err := svc.AssignPolicy(context.Background(), svcUUID, polUUID, tid)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "service not found or access denied")
+	})
+
 	t.Run("FindByUUIDAndTenantID policy error → rollback", func(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
@@ -710,6 +726,22 @@ func TestServiceService_RemovePolicy(t *testing.T) {
 		assert.Contains(t, err.Error(), "service not found")
 	})
 
+	t.Run("service of another tenant → rollback", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := NewServiceService(db, &mockServiceRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Service, error) {
+				return &model.Service{ServiceID: 1, ServiceUUID: svcUUID}, nil
+			},
+		}, &mockTenantServiceRepo{
+			findByTenantAndServiceFn: func(_, _ int64) (*model.TenantService, error) { return nil, nil },
+		}, &mockAPIRepo{}, &mockServicePolicyRepo{}, &mockPolicyRepo{})
+		err := svc.RemovePolicy(context.Background(), svcUUID, polUUID, tid)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "service not found or access denied")
+	})
+
 	t.Run("FindByUUIDAndTenantID policy error → rollback", func(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
@@ -830,6 +862,63 @@ func TestServiceService_RemovePolicy(t *testing.T) {
 	})
 }
 
+// ---------------------------------------------------------------------------
+// GetPolicies
+// ---------------------------------------------------------------------------
+
+func TestServiceService_GetPolicies(t *testing.T) {
+	svcUUID := uuid.New()
+	tid := int64(1)
+	foundService := &mockServiceRepo{
+		findByUUIDFn: func(_ any, _ ...string) (*model.Service, error) {
+			return &model.Service{ServiceID: 1, ServiceUUID: svcUUID}, nil
+		},
+	}
+
+	t.Run("service not found", func(t *testing.T) {
+		svc := NewServiceService(nil, &mockServiceRepo{}, &mockTenantServiceRepo{}, &mockAPIRepo{}, &mockServicePolicyRepo{}, &mockPolicyRepo{})
+		_, err := svc.GetPolicies(context.Background(), svcUUID, tid)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "service not found")
+	})
+
+	t.Run("service of another tenant", func(t *testing.T) {
+		svc := NewServiceService(nil, foundService, &mockTenantServiceRepo{
+			findByTenantAndServiceFn: func(_, _ int64) (*model.TenantService, error) { return nil, nil },
+		}, &mockAPIRepo{}, &mockServicePolicyRepo{}, &mockPolicyRepo{})
+		_, err := svc.GetPolicies(context.Background(), svcUUID, tid)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "access denied")
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		svc := NewServiceService(nil, foundService, &mockTenantServiceRepo{}, &mockAPIRepo{}, &mockServicePolicyRepo{
+			findPoliciesByServiceIDFn: func(int64) ([]model.Policy, error) { return nil, errors.New("db error") },
+		}, &mockPolicyRepo{})
+		_, err := svc.GetPolicies(context.Background(), svcUUID, tid)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "db error")
+	})
+
+	t.Run("only the tenant's policies", func(t *testing.T) {
+		own := uuid.New()
+		svc := NewServiceService(nil, foundService, &mockTenantServiceRepo{}, &mockAPIRepo{}, &mockServicePolicyRepo{
+			findPoliciesByServiceIDFn: func(serviceID int64) ([]model.Policy, error) {
+				assert.Equal(t, int64(1), serviceID)
+				return []model.Policy{
+					{PolicyUUID: own, TenantID: tid, Name: "billing-read"},
+					{PolicyUUID: uuid.New(), TenantID: 2, Name: "other-tenant"},
+				}, nil
+			},
+		}, &mockPolicyRepo{})
+		policies, err := svc.GetPolicies(context.Background(), svcUUID, tid)
+		require.NoError(t, err)
+		require.Len(t, policies, 1)
+		assert.Equal(t, own, policies[0].PolicyUUID)
+		assert.Equal(t, "billing-read", policies[0].Name)
+	})
+}
+
 // ---------------------------------------------------------------------------
 // toServiceServiceDataResult (indirectly tested, but verify nil-safe)
 // ---------------------------------------------------------------------------
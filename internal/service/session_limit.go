@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
+	"gorm.io/datatypes"
+)
+
+// enforceSessionLimit applies the tenant's concurrent session limit before
+// tokens are issued to user in login session sessionID. Tokens for a login
+// session that already holds tokens join that session rather than start a
+// new one. When the user is at the limit, the sign-in is refused or the
+// user's oldest sessions are ended, as the tenant's policy says.
+func (s *oauthTokenService) enforceSessionLimit(ctx context.Context, client *model.Client, user *model.User, sessionID *string) *apperror.OAuthError {
+	policy, err := tenantSessionPolicy(s.securitySettingRepo, client.TenantID)
+	if err != nil {
+		return apperror.NewOAuthServerError("an unexpected error occurred")
+	}
+	if policy.MaxSessions == 0 {
+		return nil
+	}
+
+	sessions, err := s.refreshTokenRepo.FindActiveSessionsByUserID(user.UserID)
+	if err != nil {
+		return apperror.NewOAuthServerError("an unexpected error occurred")
+	}
+	if sessionID != nil && slices.ContainsFunc(sessions, func(session repository.OAuthSession) bool {
+		return session.SessionID != nil && *session.SessionID == *sessionID
+	}) {
+		return nil
+	}
+	if security.ValidateSessionLimit(user.UserUUID.String(), len(sessions), policy.MaxSessions) == nil {
+		return nil
+	}
+
+	metadata := map[string]any{
+		"max_concurrent_sessions": policy.MaxSessions,
+		"active_sessions":         len(sessions),
+		"policy":                  policy.LimitPolicy,
+	}
+
+	if policy.LimitPolicy == model.SessionLimitPolicyRejectNew {
+		s.logSessionLimitReached(ctx, client.TenantID, user.UserID, model.AuthEventResultFailure,
+			"Sign-in refused: concurrent session limit reached", metadata)
+		return apperror.NewOAuthInvalidGrant(fmt.Sprintf(
+			"the user already has the maximum of %d active sessions; sign out of another session and try again", policy.MaxSessions,
+		)).WithCode(apperror.CodeSessionLimitReached)
+	}
+
+	// Sessions are oldest first; end enough of them to make room for one.
+	oldest := sessions[:len(sessions)-policy.MaxSessions+1]
+	for _, session := range oldest {
+		switch {
+		case session.SessionID != nil:
+			_, err = s.refreshTokenRepo.RevokeBySessionID(*session.SessionID)
+		case session.FamilyID != nil:
+			_, err = s.refreshTokenRepo.RevokeByFamily(*session.FamilyID)
+		}
+		if err != nil {
+			return apperror.NewOAuthServerError("an unexpected error occurred")
+		}
+	}
+	metadata["terminated_sessions"] = len(oldest)
+	s.logSessionLimitReached(ctx, client.TenantID, user.UserID, model.AuthEventResultSuccess,
+		"Oldest session ended: concurrent session limit reached", metadata)
+	return nil
+}
+
+// logSessionLimitReached records a sign-in that hit the concurrent session
+// limit.
+func (s *oauthTokenService) logSessionLimitReached(ctx context.Context, tenantID, userID int64, result, description string, metadata map[string]any) {
+	raw, _ := json.Marshal(metadata)
+	s.authEventService.Log(ctx, AuthEventInput{
+		TenantID:    tenantID,
+		ActorUserID: &userID,
+		IPAddress:   middleware.ClientIPFromContext(ctx),
+		UserAgent:   ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:    model.AuthEventCategorySession,
+		EventType:   model.AuthEventTypeSessionLimitReached,
+		Severity:    model.AuthEventSeverityWarn,
+		Result:      result,
+		Description: ptr.Ptr(description),
+		Metadata:    datatypes.JSON(raw),
+	})
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// activeSessions returns n sessions started a minute apart, oldest first.
+// Even sessions belong to a login session; odd ones are bare token families.
+func activeSessions(n int) []repository.OAuthSession {
+	start := time.Now().Add(-time.Hour)
+	sessions := make([]repository.OAuthSession, n)
+	for i := range sessions {
+		sessions[i].StartedAt = start.Add(time.Duration(i) * time.Minute)
+		if i%2 == 0 {
+			sessions[i].SessionID = strPtr(uuid.NewString())
+		} else {
+			familyID := uuid.New()
+			sessions[i].FamilyID = &familyID
+		}
+	}
+	return sessions
+}
+
+func TestOAuthTokenService_enforceSessionLimit(t *testing.T) {
+	ctx := context.Background()
+	client := &model.Client{ClientID: 10, TenantID: 1}
+	user := &model.User{UserID: 7, UserUUID: uuid.New()}
+
+	newSvc := func(config string, sessions []repository.OAuthSession, events *[]AuthEventInput) (*oauthTokenService, *mockOAuthRefreshTokenRepo) {
+		refreshTokenRepo := &mockOAuthRefreshTokenRepo{
+			findActiveSessionsFn: func(userID int64) ([]repository.OAuthSession, error) {
+				assert.Equal(t, int64(7), userID)
+				return sessions, nil
+			},
+			revokeBySessionIDFn: func(string) (int64, error) {
+				t.Fatal("unexpected session revocation")
+				return 0, nil
+			},
+			revokeByFamilyFn: func(uuid.UUID) (int64, error) {
+				t.Fatal("unexpected family revocation")
+				return 0, nil
+			},
+		}
+		return &oauthTokenService{
+			refreshTokenRepo:    refreshTokenRepo,
+			securitySettingRepo: sessionConfigRepo(config),
+			authEventService: &mockAuthEventService{logFn: func(_ context.Context, input AuthEventInput) {
+				*events = append(*events, input)
+			}},
+		}, refreshTokenRepo
+	}
+
+	t.Run("below limit", func(t *testing.T) {
+		var events []AuthEventInput
+		svc, _ := newSvc(`{"max_concurrent_sessions":3}`, activeSessions(2), &events)
+		assert.Nil(t, svc.enforceSessionLimit(ctx, client, user, nil))
+		assert.Empty(t, events)
+	})
+
+	for _, config := range []string{`{"max_concurrent_sessions":0}`, `{}`} {
+		t.Run("unlimited "+config, func(t *testing.T) {
+			var events []AuthEventInput
+			svc, refreshTokenRepo := newSvc(config, nil, &events)
+			refreshTokenRepo.findActiveSessionsFn = func(int64) ([]repository.OAuthSession, error) {
+				t.Fatal("sessions should not be counted without a limit")
+				return nil, nil
+			}
+			assert.Nil(t, svc.enforceSessionLimit(ctx, client, user, nil))
+		})
+	}
+
+	t.Run("login session that already holds tokens", func(t *testing.T) {
+		var events []AuthEventInput
+		sessions := activeSessions(2)
+		svc, _ := newSvc(`{"max_concurrent_sessions":2,"session_limit_policy":"reject_new"}`, sessions, &events)
+		assert.Nil(t, svc.enforceSessionLimit(ctx, client, user, sessions[0].SessionID))
+		assert.Empty(t, events)
+	})
+
+	t.Run("reject new", func(t *testing.T) {
+		var events []AuthEventInput
+		svc, _ := newSvc(`{"max_concurrent_sessions":2,"session_limit_policy":"reject_new"}`, activeSessions(2), &events)
+
+		oerr := svc.enforceSessionLimit(ctx, client, user, strPtr("new-session"))
+		require.NotNil(t, oerr)
+		assert.Equal(t, "invalid_grant", oerr.Code)
+		assert.Contains(t, oerr.Description, "maximum of 2 active sessions")
+		assert.Equal(t, apperror.TypeBase+string(apperror.CodeSessionLimitReached), oerr.URI)
+
+		require.Len(t, events, 1)
+		assert.Equal(t, model.AuthEventTypeSessionLimitReached, events[0].EventType)
+		assert.Equal(t, model.AuthEventCategorySession, events[0].Category)
+		assert.Equal(t, model.AuthEventResultFailure, events[0].Result)
+	})
+
+	t.Run("terminate oldest", func(t *testing.T) {
+		var events []AuthEventInput
+		sessions := activeSessions(3)
+		svc, refreshTokenRepo := newSvc(`{"max_concurrent_sessions":2}`, sessions, &events)
+		var revokedSessions []string
+		var revokedFamilies []uuid.UUID
+		refreshTokenRepo.revokeBySessionIDFn = func(sessionID string) (int64, error) {
+			revokedSessions = append(revokedSessions, sessionID)
+			return 1, nil
+		}
+		refreshTokenRepo.revokeByFamilyFn = func(familyID uuid.UUID) (int64, error) {
+			revokedFamilies = append(revokedFamilies, familyID)
+			return 1, nil
+		}
+
+		// Three sessions with a limit of two: the two oldest make room.
+		require.Nil(t, svc.enforceSessionLimit(ctx, client, user, nil))
+		assert.Equal(t, []string{*sessions[0].SessionID}, revokedSessions)
+		assert.Equal(t, []uuid.UUID{*sessions[1].FamilyID}, revokedFamilies)
+
+		require.Len(t, events, 1)
+		assert.Equal(t, model.AuthEventResultSuccess, events[0].Result)
+		var metadata map[string]any
+		require.NoError(t, json.Unmarshal(events[0].Metadata, &metadata))
+		assert.Equal(t, float64(2), metadata["terminated_sessions"])
+		assert.Equal(t, model.SessionLimitPolicyTerminateOldest, metadata["policy"])
+	})
+
+	t.Run("revocation error", func(t *testing.T) {
+		var events []AuthEventInput
+		svc, refreshTokenRepo := newSvc(`{"max_concurrent_sessions":1}`, activeSessions(1), &events)
+		refreshTokenRepo.revokeBySessionIDFn = func(string) (int64, error) { return 0, errors.New("db down") }
+
+		oerr := svc.enforceSessionLimit(ctx, client, user, nil)
+		require.NotNil(t, oerr)
+		assert.Equal(t, "server_error", oerr.Code)
+		assert.Empty(t, events)
+	})
+
+	t.Run("session lookup error", func(t *testing.T) {
+		var events []AuthEventInput
+		svc, refreshTokenRepo := newSvc(`{"max_concurrent_sessions":2}`, nil, &events)
+		refreshTokenRepo.findActiveSessionsFn = func(int64) ([]repository.OAuthSession, error) {
+			return nil, errors.New("db down")
+		}
+
+		oerr := svc.enforceSessionLimit(ctx, client, user, nil)
+		require.NotNil(t, oerr)
+		assert.Equal(t, "server_error", oerr.Code)
+	})
+}
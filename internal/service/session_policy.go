@@ -0,0 +1,54 @@
+package service
+
+import (
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+)
+
+// sessionPolicy is how a tenant limits OAuth sessions.
+type sessionPolicy struct {
+	// MaxSessions is the most sessions a user may hold, 0 for no limit.
+	MaxSessions int
+	// LimitPolicy is what a sign-in beyond MaxSessions does.
+	LimitPolicy string
+	// IdleTimeout ends a session that has not been refreshed for this long,
+	// 0 for never.
+	IdleTimeout time.Duration
+	// MaxLifetime ends a session this long after it started, however
+	// active, 0 for never.
+	MaxLifetime time.Duration
+}
+
+// tenantSessionPolicy loads the session policy from the tenant's session
+// settings. Unset or invalid fields keep the defaults: no session limit,
+// ending the oldest once one is set, and no timeouts. The limit is off by
+// default so that existing tenants' users are not signed out on upgrade.
+func tenantSessionPolicy(securitySettingRepo repository.SecuritySettingRepository, tenantID int64) (sessionPolicy, error) {
+	policy := sessionPolicy{LimitPolicy: model.SessionLimitPolicyTerminateOldest}
+	setting, err := securitySettingRepo.FindByUserPoolID(tenantID)
+	if err != nil {
+		return policy, apperror.NewInternal("failed to load security settings", err)
+	}
+	if setting == nil {
+		return policy, nil
+	}
+
+	cfg := unmarshalJSON(setting.SessionConfig)
+	if maxSessions, ok := cfg[model.SessionConfigMaxConcurrentSessions].(float64); ok && maxSessions >= 0 {
+		policy.MaxSessions = int(maxSessions)
+	}
+	switch limitPolicy, _ := cfg[model.SessionConfigLimitPolicy].(string); limitPolicy {
+	case model.SessionLimitPolicyRejectNew, model.SessionLimitPolicyTerminateOldest:
+		policy.LimitPolicy = limitPolicy
+	}
+	if minutes, ok := cfg[model.SessionConfigIdleTimeoutMinutes].(float64); ok && minutes > 0 {
+		policy.IdleTimeout = time.Duration(minutes * float64(time.Minute))
+	}
+	if hours, ok := cfg[model.SessionConfigAbsoluteTimeoutHours].(float64); ok && hours > 0 {
+		policy.MaxLifetime = time.Duration(hours * float64(time.Hour))
+	}
+	return policy, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func sessionConfigRepo(config string) *mockSecuritySettingRepo {
+	return &mockSecuritySettingRepo{findByUserPoolIDFn: func(int64) (*model.SecuritySetting, error) {
+		return &model.SecuritySetting{SessionConfig: datatypes.JSON(config)}, nil
+	}}
+}
+
+func TestTenantSessionPolicy(t *testing.T) {
+	defaults := sessionPolicy{LimitPolicy: model.SessionLimitPolicyTerminateOldest}
+
+	tests := []struct {
+		name   string
+		config string
+		want   sessionPolicy
+	}{
+		{"empty", `{}`, defaults},
+		{
+			"configured",
+			`{"max_concurrent_sessions":2,"session_limit_policy":"reject_new","idle_timeout_minutes":15,"absolute_timeout_hours":12}`,
+			sessionPolicy{MaxSessions: 2, LimitPolicy: model.SessionLimitPolicyRejectNew, IdleTimeout: 15 * time.Minute, MaxLifetime: 12 * time.Hour},
+		},
+		{"unlimited", `{"max_concurrent_sessions":0,"session_limit_policy":"reject_new"}`, sessionPolicy{MaxSessions: 0, LimitPolicy: model.SessionLimitPolicyRejectNew}},
+		{"fractional hours", `{"absolute_timeout_hours":0.5}`, sessionPolicy{LimitPolicy: model.SessionLimitPolicyTerminateOldest, MaxLifetime: 30 * time.Minute}},
+		{
+			"invalid",
+			`{"max_concurrent_sessions":-1,"session_limit_policy":"kick_all","idle_timeout_minutes":-5,"absolute_timeout_hours":"24"}`,
+			defaults,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tenantSessionPolicy(sessionConfigRepo(tt.config), 1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("no settings", func(t *testing.T) {
+		got, err := tenantSessionPolicy(&mockSecuritySettingRepo{}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, defaults, got)
+	})
+
+	t.Run("lookup error", func(t *testing.T) {
+		_, err := tenantSessionPolicy(&mockSecuritySettingRepo{findByUserPoolIDFn: func(int64) (*model.SecuritySetting, error) {
+			return nil, errors.New("db down")
+		}}, 1)
+		var target *apperror.InternalError
+		assert.ErrorAs(t, err, &target)
+	})
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+)
+
+// Reasons a session timed out, recorded as the error_reason of its
+// session_expired event.
+const (
+	sessionTimeoutReasonIdle     = "idle_timeout"
+	sessionTimeoutReasonLifetime = "absolute_timeout"
+)
+
+// sessionTimeout reports whether the session of a refresh token has timed
+// out under the tenant's policy, returning the code telling the client why:
+// CodeSessionIdleTimeout when it was not refreshed within the idle timeout,
+// CodeSessionLifetimeExceeded when it outlived its maximum lifetime. It
+// returns "" for a live session.
+//
+// Tokens are rotated on every refresh, so a token's creation is the last
+// activity of its session.
+func (s *oauthTokenService) sessionTimeout(policy sessionPolicy, token *model.OAuthRefreshToken, now time.Time) (apperror.Code, error) {
+	if policy.MaxLifetime > 0 {
+		startedAt, err := s.refreshTokenRepo.FindSessionStartedAt(token.FamilyID, token.SessionID)
+		if err != nil {
+			return "", err
+		}
+		if now.Sub(startedAt) >= policy.MaxLifetime {
+			return apperror.CodeSessionLifetimeExceeded, nil
+		}
+	}
+	if policy.IdleTimeout > 0 && now.Sub(token.CreatedAt) >= policy.IdleTimeout {
+		return apperror.CodeSessionIdleTimeout, nil
+	}
+	return "", nil
+}
+
+// refuseTimedOutSession records the end of a timed-out session and returns
+// the error for the refresh that found it. The token is not revoked:
+// presenting it again is refused the same way, not treated as reuse.
+func (s *oauthTokenService) refuseTimedOutSession(ctx context.Context, token *model.OAuthRefreshToken, code apperror.Code) *apperror.OAuthError {
+	reason, description, message := sessionTimeoutReasonIdle,
+		"Session expired due to inactivity",
+		"the session has expired due to inactivity; sign in again"
+	if code == apperror.CodeSessionLifetimeExceeded {
+		reason, description, message = sessionTimeoutReasonLifetime,
+			"Session reached its maximum lifetime",
+			"the session has reached its maximum lifetime; sign in again"
+	}
+
+	s.authEventService.Log(ctx, AuthEventInput{
+		TenantID:    token.TenantID,
+		ActorUserID: &token.UserID,
+		IPAddress:   middleware.ClientIPFromContext(ctx),
+		UserAgent:   ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:    model.AuthEventCategorySession,
+		EventType:   model.AuthEventTypeSessionExpired,
+		Severity:    model.AuthEventSeverityInfo,
+		Result:      model.AuthEventResultSuccess,
+		Description: ptr.Ptr(description),
+		ErrorReason: ptr.Ptr(reason),
+	})
+	return apperror.NewOAuthInvalidGrant(message).WithCode(code)
+}
+
+// sessionLive reports whether the session of an active refresh token has
+// not timed out. A policy that cannot be checked counts as timed out, so
+// introspection errs towards inactive.
+func (s *oauthTokenService) sessionLive(token *model.OAuthRefreshToken) bool {
+	policy, err := tenantSessionPolicy(s.securitySettingRepo, token.TenantID)
+	if err != nil {
+		return false
+	}
+	timeout, err := s.sessionTimeout(policy, token, time.Now())
+	return err == nil && timeout == ""
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuthTokenService_sessionTimeout(t *testing.T) {
+	now := time.Now()
+	sid := strPtr("login-session")
+	token := &model.OAuthRefreshToken{FamilyID: uuid.New(), SessionID: sid, CreatedAt: now.Add(-20 * time.Minute)}
+	policy := sessionPolicy{IdleTimeout: 30 * time.Minute, MaxLifetime: 24 * time.Hour}
+
+	newSvc := func(startedAt time.Time, err error) *oauthTokenService {
+		return &oauthTokenService{refreshTokenRepo: &mockOAuthRefreshTokenRepo{
+			findSessionStartedAtFn: func(familyID uuid.UUID, sessionID *string) (time.Time, error) {
+				assert.Equal(t, token.FamilyID, familyID)
+				assert.Equal(t, sid, sessionID)
+				return startedAt, err
+			},
+		}}
+	}
+
+	t.Run("live", func(t *testing.T) {
+		code, err := newSvc(now.Add(-time.Hour), nil).sessionTimeout(policy, token, now)
+		require.NoError(t, err)
+		assert.Empty(t, code)
+	})
+
+	t.Run("idle", func(t *testing.T) {
+		code, err := newSvc(now.Add(-time.Hour), nil).sessionTimeout(policy, token, now.Add(15*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, apperror.CodeSessionIdleTimeout, code)
+	})
+
+	t.Run("lifetime exceeded", func(t *testing.T) {
+		code, err := newSvc(now.Add(-24*time.Hour), nil).sessionTimeout(policy, token, now)
+		require.NoError(t, err)
+		assert.Equal(t, apperror.CodeSessionLifetimeExceeded, code)
+	})
+
+	t.Run("lifetime takes precedence over idle", func(t *testing.T) {
+		code, err := newSvc(now.Add(-48*time.Hour), nil).sessionTimeout(policy, token, now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, apperror.CodeSessionLifetimeExceeded, code)
+	})
+
+	t.Run("no timeouts", func(t *testing.T) {
+		svc := &oauthTokenService{refreshTokenRepo: &mockOAuthRefreshTokenRepo{
+			findSessionStartedAtFn: func(uuid.UUID, *string) (time.Time, error) {
+				t.Fatal("session start should not be looked up without a lifetime")
+				return time.Time{}, nil
+			},
+		}}
+		code, err := svc.sessionTimeout(sessionPolicy{}, token, now.Add(365*24*time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, code)
+	})
+
+	t.Run("start lookup error", func(t *testing.T) {
+		_, err := newSvc(time.Time{}, errors.New("db down")).sessionTimeout(policy, token, now)
+		assert.Error(t, err)
+	})
+}
+
+func TestOAuthTokenService_refuseTimedOutSession(t *testing.T) {
+	token := &model.OAuthRefreshToken{TenantID: 1, UserID: 7}
+
+	tests := []struct {
+		code   apperror.Code
+		reason string
+		text   string
+	}{
+		{apperror.CodeSessionIdleTimeout, sessionTimeoutReasonIdle, "inactivity"},
+		{apperror.CodeSessionLifetimeExceeded, sessionTimeoutReasonLifetime, "maximum lifetime"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			var events []AuthEventInput
+			svc := &oauthTokenService{authEventService: &mockAuthEventService{logFn: func(_ context.Context, input AuthEventInput) {
+				events = append(events, input)
+			}}}
+
+			oerr := svc.refuseTimedOutSession(context.Background(), token, tt.code)
+			assert.Equal(t, "invalid_grant", oerr.Code)
+			assert.Contains(t, oerr.Description, tt.text)
+			assert.Equal(t, apperror.TypeBase+string(tt.code), oerr.URI)
+
+			require.Len(t, events, 1)
+			assert.Equal(t, model.AuthEventTypeSessionExpired, events[0].EventType)
+			assert.Equal(t, model.AuthEventCategorySession, events[0].Category)
+			assert.Equal(t, int64(7), *events[0].ActorUserID)
+			require.NotNil(t, events[0].ErrorReason)
+			assert.Equal(t, tt.reason, *events[0].ErrorReason)
+		})
+	}
+}
+
+func TestOAuthTokenService_sessionLive(t *testing.T) {
+	token := &model.OAuthRefreshToken{TenantID: 1, CreatedAt: time.Now().Add(-time.Hour)}
+
+	t.Run("live", func(t *testing.T) {
+		svc := &oauthTokenService{securitySettingRepo: sessionConfigRepo(`{"idle_timeout_minutes":120}`), refreshTokenRepo: &mockOAuthRefreshTokenRepo{}}
+		assert.True(t, svc.sessionLive(token))
+	})
+
+	t.Run("idle", func(t *testing.T) {
+		svc := &oauthTokenService{securitySettingRepo: sessionConfigRepo(`{"idle_timeout_minutes":30}`), refreshTokenRepo: &mockOAuthRefreshTokenRepo{}}
+		assert.False(t, svc.sessionLive(token))
+	})
+
+	t.Run("settings lookup error", func(t *testing.T) {
+		svc := &oauthTokenService{securitySettingRepo: &mockSecuritySettingRepo{findByUserPoolIDFn: func(int64) (*model.SecuritySetting, error) {
+			return nil, errors.New("db down")
+		}}}
+		assert.False(t, svc.sessionLive(token))
+	})
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// SIEMForwardingCacheTTL is how long a tenant's siem_forwarding flag and an
+// OAuth client's tenant are kept in memory. Turning forwarding on or off takes
+// effect within this interval.
+const SIEMForwardingCacheTTL = time.Minute
+
+// SIEMForwardingService decides which security events are forwarded to the
+// SIEM. It satisfies siem.TenantFilter.
+type SIEMForwardingService interface {
+	// Forwards reports whether an event of the tenant, or of the OAuth
+	// client clientID when tenantID is zero, is forwarded. Tenants forward
+	// their events once they set the siem_forwarding feature flag; events
+	// tied to neither a tenant nor a known client are platform events and
+	// are always forwarded.
+	Forwards(ctx context.Context, tenantID int64, clientID string) (bool, error)
+}
+
+// cachedValue is a cached lookup result.
+type cachedValue[T any] struct {
+	value    T
+	loadedAt time.Time
+}
+
+type siemForwardingService struct {
+	clientRepo        repository.ClientRepository
+	tenantSettingRepo repository.TenantSettingRepository
+	now               func() time.Time
+
+	mu            sync.RWMutex
+	enabled       map[int64]cachedValue[bool]
+	clientTenants map[string]cachedValue[int64]
+}
+
+// NewSIEMForwardingService creates a new SIEMForwardingService.
+func NewSIEMForwardingService(
+	clientRepo repository.ClientRepository,
+	tenantSettingRepo repository.TenantSettingRepository,
+) SIEMForwardingService {
+	return &siemForwardingService{
+		clientRepo:        clientRepo,
+		tenantSettingRepo: tenantSettingRepo,
+		now:               time.Now,
+		enabled:           make(map[int64]cachedValue[bool]),
+		clientTenants:     make(map[string]cachedValue[int64]),
+	}
+}
+
+func (s *siemForwardingService) Forwards(ctx context.Context, tenantID int64, clientID string) (bool, error) {
+	_, span := otel.Tracer("service").Start(ctx, "siemForwarding.forwards")
+	defer span.End()
+
+	if tenantID == 0 && clientID != "" {
+		id, err := s.clientTenant(clientID)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "find client failed")
+			return false, err
+		}
+		tenantID = id
+	}
+	if tenantID == 0 {
+		span.SetStatus(codes.Ok, "")
+		return true, nil
+	}
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID))
+
+	enabled, err := s.tenantEnabled(tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find tenant setting failed")
+		return false, err
+	}
+	span.SetStatus(codes.Ok, "")
+	return enabled, nil
+}
+
+// clientTenant returns the tenant of the active client with OAuth client_id
+// identifier, or zero when there is none.
+func (s *siemForwardingService) clientTenant(identifier string) (int64, error) {
+	now := s.now()
+
+	s.mu.RLock()
+	cached, ok := s.clientTenants[identifier]
+	s.mu.RUnlock()
+	if ok && now.Sub(cached.loadedAt) < SIEMForwardingCacheTTL {
+		return cached.value, nil
+	}
+
+	client, err := s.clientRepo.FindActiveByIdentifier(identifier)
+	if err != nil {
+		return 0, err
+	}
+	var tenantID int64
+	if client != nil {
+		tenantID = client.TenantID
+	}
+
+	s.mu.Lock()
+	s.clientTenants[identifier] = cachedValue[int64]{value: tenantID, loadedAt: now}
+	s.mu.Unlock()
+	return tenantID, nil
+}
+
+// tenantEnabled reports whether the tenant has set the siem_forwarding
+// feature flag.
+func (s *siemForwardingService) tenantEnabled(tenantID int64) (bool, error) {
+	now := s.now()
+
+	s.mu.RLock()
+	cached, ok := s.enabled[tenantID]
+	s.mu.RUnlock()
+	if ok && now.Sub(cached.loadedAt) < SIEMForwardingCacheTTL {
+		return cached.value, nil
+	}
+
+	setting, err := s.tenantSettingRepo.FindByTenantID(tenantID)
+	if err != nil {
+		return false, err
+	}
+	enabled := setting != nil && unmarshalJSON(setting.FeatureFlags)[model.FeatureFlagSIEMForwarding] == true
+
+	s.mu.Lock()
+	s.enabled[tenantID] = cachedValue[bool]{value: enabled, loadedAt: now}
+	s.mu.Unlock()
+	return enabled, nil
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func siemForwardingSettingRepo(enabled map[int64]bool, calls map[int64]int) *mockTenantSettingRepo {
+	return &mockTenantSettingRepo{
+		findByTenantIDFn: func(tenantID int64) (*model.TenantSetting, error) {
+			if calls != nil {
+				calls[tenantID]++
+			}
+			flags := `{"siem_forwarding": false}`
+			if enabled[tenantID] {
+				flags = `{"siem_forwarding": true}`
+			}
+			return &model.TenantSetting{TenantID: tenantID, FeatureFlags: datatypes.JSON(flags)}, nil
+		},
+	}
+}
+
+func TestSIEMForwardingService_Forwards(t *testing.T) {
+	settings := siemForwardingSettingRepo(map[int64]bool{1: true}, nil)
+	clients := &mockClientRepo{findActiveByIdentifierFn: func(identifier string) (*model.Client, error) {
+		switch identifier {
+		case "client-of-1":
+			return &model.Client{TenantID: 1}, nil
+		case "client-of-2":
+			return &model.Client{TenantID: 2}, nil
+		}
+		return nil, nil
+	}}
+	svc := NewSIEMForwardingService(clients, settings)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		tenantID int64
+		clientID string
+		want     bool
+	}{
+		{"enabled tenant", 1, "", true},
+		{"disabled tenant", 2, "", false},
+		{"tenant takes precedence over client", 2, "client-of-1", false},
+		{"client of enabled tenant", 0, "client-of-1", true},
+		{"client of disabled tenant", 0, "client-of-2", false},
+		{"unknown client", 0, "unknown", true},
+		{"platform event", 0, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.Forwards(ctx, tt.tenantID, tt.clientID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSIEMForwardingService_Caches(t *testing.T) {
+	settingCalls := map[int64]int{}
+	clientCalls := 0
+	clients := &mockClientRepo{findActiveByIdentifierFn: func(string) (*model.Client, error) {
+		clientCalls++
+		return &model.Client{TenantID: 1}, nil
+	}}
+	svc := NewSIEMForwardingService(clients, siemForwardingSettingRepo(map[int64]bool{1: true}, settingCalls)).(*siemForwardingService)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := svc.Forwards(ctx, 0, "client-1")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, clientCalls)
+	assert.Equal(t, 1, settingCalls[1])
+
+	now = now.Add(SIEMForwardingCacheTTL)
+	_, err := svc.Forwards(ctx, 0, "client-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, clientCalls)
+	assert.Equal(t, 2, settingCalls[1])
+}
+
+func TestSIEMForwardingService_LookupErrors(t *testing.T) {
+	ctx := context.Background()
+
+	failingSettings := &mockTenantSettingRepo{
+		findByTenantIDFn: func(int64) (*model.TenantSetting, error) { return nil, errors.New("db down") },
+	}
+	_, err := NewSIEMForwardingService(&mockClientRepo{}, failingSettings).Forwards(ctx, 1, "")
+	assert.Error(t, err)
+
+	failingClients := &mockClientRepo{findActiveByIdentifierFn: func(string) (*model.Client, error) {
+		return nil, errors.New("db down")
+	}}
+	_, err = NewSIEMForwardingService(failingClients, siemForwardingSettingRepo(nil, nil)).Forwards(ctx, 0, "client-1")
+	assert.Error(t, err)
+}
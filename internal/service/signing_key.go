@@ -0,0 +1,382 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+// signingKeyPrivateKeyField is the encryption context of stored private keys.
+const signingKeyPrivateKeyField = "signing_keys.private_key"
+
+// SigningKeyConfig controls how signing keys are stored and rotated.
+// Encryptor encrypts the stored private keys. The active key is rotated once
+// it is RotationInterval old, or only on demand when RotationInterval is 0.
+// A rotated key keeps verifying the tokens it signed for RetireAfter, which
+// must exceed the lifetime of the longest-lived JWT.
+type SigningKeyConfig struct {
+	Encryptor        *crypto.FieldEncryptor
+	RotationInterval time.Duration
+	RetireAfter      time.Duration
+}
+
+// SigningKeyService stores the JWT signing keys in the database, rotates and
+// retires them, and installs them in the jwt package.
+type SigningKeyService interface {
+	// Load installs the active key and the previous keys as the jwt package
+	// keys. When no key is stored yet, the key loaded by jwt.InitJWTKeys is
+	// stored as the first active key.
+	Load(ctx context.Context) error
+
+	// List returns every key, newest first, including retired ones.
+	List(ctx context.Context) ([]dto.SigningKeyResponseDTO, error)
+
+	// Rotate generates a new active key. The key it replaces keeps verifying
+	// tokens until it is retired.
+	Rotate(ctx context.Context) (*dto.SigningKeyResponseDTO, error)
+
+	// Retire stops a previous key from verifying tokens and drops its
+	// private key. The active key cannot be retired.
+	Retire(ctx context.Context, signingKeyUUID uuid.UUID) (*dto.SigningKeyResponseDTO, error)
+
+	// RotateDue rotates the active key when it is due, retires previous
+	// keys past their grace period and reloads the keys. It returns how
+	// many keys were rotated or retired.
+	RotateDue(ctx context.Context) (int64, error)
+}
+
+type signingKeyService struct {
+	db             *gorm.DB
+	signingKeyRepo repository.SigningKeyRepository
+	config         SigningKeyConfig
+}
+
+// NewSigningKeyService creates a new SigningKeyService.
+func NewSigningKeyService(
+	db *gorm.DB,
+	signingKeyRepo repository.SigningKeyRepository,
+	config SigningKeyConfig,
+) SigningKeyService {
+	return &signingKeyService{
+		db:             db,
+		signingKeyRepo: signingKeyRepo,
+		config:         config,
+	}
+}
+
+// Load implements SigningKeyService.
+func (s *signingKeyService) Load(ctx context.Context) error {
+	_, span := otel.Tracer("service").Start(ctx, "signing_key.load")
+	defer span.End()
+
+	keys, err := s.signingKeyRepo.FindUnretired()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "signing keys lookup failed")
+		return apperror.NewInternal("failed to load signing keys", err)
+	}
+	if len(keys) == 0 {
+		if keys, err = s.storeInitialKey(); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "initial signing key not stored")
+			return err
+		}
+	}
+
+	var signing *jwt.Key
+	verification := make([]jwt.Key, 0, len(keys))
+	for i := range keys {
+		key, err := s.toJWTKey(&keys[i])
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "invalid signing key")
+			return apperror.NewInternal(fmt.Sprintf("failed to load signing key %s", keys[i].KeyID), err)
+		}
+		if keys[i].Status == model.SigningKeyStatusActive {
+			signing = &key
+			continue
+		}
+		verification = append(verification, key)
+	}
+	if signing == nil {
+		span.SetStatus(codes.Error, "no active signing key")
+		return apperror.NewInternal("no active signing key", nil)
+	}
+	if err := jwt.SetKeys(*signing, verification...); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "signing keys not installed")
+		return apperror.NewInternal("failed to install signing keys", err)
+	}
+
+	span.SetAttributes(
+		attribute.String("signing_key.kid", signing.ID),
+		attribute.Int("signing_key.count", len(keys)),
+	)
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// storeInitialKey stores the key loaded from the environment as the active
+// key. When another instance stored one first, that key is returned instead.
+func (s *signingKeyService) storeInitialKey() ([]model.SigningKey, error) {
+	current, ok := jwt.SigningKey()
+	if !ok {
+		return nil, apperror.NewInternal("no signing key to store - call InitJWTKeys() first", nil)
+	}
+	key, err := s.newSigningKey(current)
+	if err != nil {
+		return nil, err
+	}
+	if _, createErr := s.signingKeyRepo.Create(key); createErr != nil {
+		keys, err := s.signingKeyRepo.FindUnretired()
+		if err != nil || len(keys) == 0 {
+			return nil, apperror.NewInternal("failed to store initial signing key", createErr)
+		}
+		return keys, nil
+	}
+	return []model.SigningKey{*key}, nil
+}
+
+// List implements SigningKeyService.
+func (s *signingKeyService) List(ctx context.Context) ([]dto.SigningKeyResponseDTO, error) {
+	_, span := otel.Tracer("service").Start(ctx, "signing_key.list")
+	defer span.End()
+
+	keys, err := s.signingKeyRepo.FindAllOrdered()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "signing keys lookup failed")
+		return nil, apperror.NewInternal("failed to retrieve signing keys", err)
+	}
+
+	result := make([]dto.SigningKeyResponseDTO, len(keys))
+	for i := range keys {
+		result[i] = toSigningKeyResponseDTO(&keys[i])
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// Rotate implements SigningKeyService.
+func (s *signingKeyService) Rotate(ctx context.Context) (*dto.SigningKeyResponseDTO, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "signing_key.rotate")
+	defer span.End()
+
+	created, err := s.rotate(func(*model.SigningKey) bool { return true })
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "rotate signing key failed")
+		return nil, err
+	}
+	if err := s.Load(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "reload signing keys failed")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("signing_key.kid", created.KeyID))
+	span.SetStatus(codes.Ok, "")
+	result := toSigningKeyResponseDTO(created)
+	return &result, nil
+}
+
+// rotate replaces the active key with a new one when due reports that the
+// locked active key is due for rotation. It returns nil when it is not.
+func (s *signingKeyService) rotate(due func(active *model.SigningKey) bool) (*model.SigningKey, error) {
+	generated, err := jwt.GenerateKey(uuid.NewString())
+	if err != nil {
+		return nil, apperror.NewInternal("failed to generate signing key", err)
+	}
+	key, err := s.newSigningKey(generated)
+	if err != nil {
+		return nil, err
+	}
+
+	var created *model.SigningKey
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		txSigningKeyRepo := s.signingKeyRepo.WithTx(tx)
+
+		active, err := txSigningKeyRepo.FindActiveForUpdate()
+		if err != nil {
+			return apperror.NewInternal("failed to find active signing key", err)
+		}
+		if !due(active) {
+			return nil
+		}
+		if active != nil {
+			if _, err := txSigningKeyRepo.UpdateByID(active.SigningKeyID, map[string]any{
+				"status":     model.SigningKeyStatusPrevious,
+				"rotated_at": key.ActivatedAt,
+			}); err != nil {
+				return apperror.NewInternal("failed to rotate signing key", err)
+			}
+		}
+		if created, err = txSigningKeyRepo.Create(key); err != nil {
+			return apperror.NewInternal("failed to store signing key", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Retire implements SigningKeyService.
+func (s *signingKeyService) Retire(ctx context.Context, signingKeyUUID uuid.UUID) (*dto.SigningKeyResponseDTO, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "signing_key.retire")
+	defer span.End()
+	span.SetAttributes(attribute.String("signing_key.uuid", signingKeyUUID.String()))
+
+	key, err := s.signingKeyRepo.FindByUUID(signingKeyUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "signing key lookup failed")
+		return nil, apperror.NewInternal("failed to find signing key", err)
+	}
+	if key == nil {
+		span.SetStatus(codes.Error, "signing key not found")
+		return nil, apperror.NewNotFound("signing key")
+	}
+	if key.Status == model.SigningKeyStatusActive {
+		span.SetStatus(codes.Error, "active signing key")
+		return nil, apperror.NewConflict("the active signing key cannot be retired; rotate it first")
+	}
+
+	if key.Status != model.SigningKeyStatusRetired {
+		now := time.Now()
+		if key, err = s.signingKeyRepo.UpdateByID(key.SigningKeyID, map[string]any{
+			"status":      model.SigningKeyStatusRetired,
+			"retired_at":  now,
+			"private_key": nil,
+		}); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "retire signing key failed")
+			return nil, apperror.NewInternal("failed to retire signing key", err)
+		}
+		if err := s.Load(ctx); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "reload signing keys failed")
+			return nil, err
+		}
+	}
+
+	span.SetStatus(codes.Ok, "")
+	result := toSigningKeyResponseDTO(key)
+	return &result, nil
+}
+
+// RotateDue implements SigningKeyService.
+func (s *signingKeyService) RotateDue(ctx context.Context) (int64, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "signing_key.rotate_due")
+	defer span.End()
+
+	var count int64
+	if s.config.RotationInterval > 0 {
+		created, err := s.rotate(func(active *model.SigningKey) bool {
+			return active == nil || time.Since(active.ActivatedAt) >= s.config.RotationInterval
+		})
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "rotate signing key failed")
+			return 0, err
+		}
+		if created != nil {
+			count++
+		}
+	}
+
+	retired, err := s.signingKeyRepo.RetirePreviousRotatedBefore(time.Now().Add(-s.config.RetireAfter))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "retire signing keys failed")
+		return count, apperror.NewInternal("failed to retire signing keys", err)
+	}
+	count += retired
+
+	// Reload even when nothing changed here, so that keys rotated by other
+	// instances are picked up.
+	if err := s.Load(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "reload signing keys failed")
+		return count, err
+	}
+
+	span.SetAttributes(attribute.Int64("signing_key.changed", count))
+	span.SetStatus(codes.Ok, "")
+	return count, nil
+}
+
+// newSigningKey prepares an active key for storage, encrypting its private
+// key.
+func (s *signingKeyService) newSigningKey(key jwt.Key) (*model.SigningKey, error) {
+	publicKey, err := jwt.EncodePublicKey(key.PublicKey)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to encode signing key", err)
+	}
+	privateKey, err := s.config.Encryptor.Encrypt(signingKeyPrivateKeyField, string(jwt.EncodePrivateKey(key.PrivateKey)))
+	if err != nil {
+		return nil, apperror.NewInternal("failed to encrypt signing key", err)
+	}
+	return &model.SigningKey{
+		KeyID:       key.ID,
+		Algorithm:   jwtlib.SigningMethodRS256.Alg(),
+		PublicKey:   string(publicKey),
+		PrivateKey:  &privateKey,
+		Status:      model.SigningKeyStatusActive,
+		ActivatedAt: time.Now(),
+	}, nil
+}
+
+// toJWTKey decodes a stored key. Only the active key's private key is
+// decrypted.
+func (s *signingKeyService) toJWTKey(key *model.SigningKey) (jwt.Key, error) {
+	publicKey, err := jwtlib.ParseRSAPublicKeyFromPEM([]byte(key.PublicKey))
+	if err != nil {
+		return jwt.Key{}, err
+	}
+	result := jwt.Key{ID: key.KeyID, PublicKey: publicKey}
+	if key.Status != model.SigningKeyStatusActive {
+		return result, nil
+	}
+	if key.PrivateKey == nil {
+		return jwt.Key{}, errors.New("active signing key has no private key")
+	}
+	privatePEM, err := s.config.Encryptor.Decrypt(signingKeyPrivateKeyField, *key.PrivateKey)
+	if err != nil {
+		return jwt.Key{}, err
+	}
+	if result.PrivateKey, err = jwtlib.ParseRSAPrivateKeyFromPEM([]byte(privatePEM)); err != nil {
+		return jwt.Key{}, err
+	}
+	return result, nil
+}
+
+// toSigningKeyResponseDTO describes a key without its key material.
+func toSigningKeyResponseDTO(key *model.SigningKey) dto.SigningKeyResponseDTO {
+	return dto.SigningKeyResponseDTO{
+		SigningKeyID: key.SigningKeyUUID.String(),
+		KeyID:        key.KeyID,
+		Algorithm:    key.Algorithm,
+		Status:       key.Status,
+		ActivatedAt:  key.ActivatedAt,
+		RotatedAt:    key.RotatedAt,
+		RetiredAt:    key.RetiredAt,
+		CreatedAt:    key.CreatedAt,
+	}
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/model"
+)
+
+// newSigningKeyTestService installs a fresh environment key and returns a
+// service over an empty in-memory key store. The installed keys are restored
+// when the test ends.
+func newSigningKeyTestService(t *testing.T, config SigningKeyConfig) (SigningKeyService, *mockSigningKeyRepo, sqlmock.Sqlmock) {
+	t.Helper()
+	previous, hadKeys := jwt.SigningKey()
+	t.Cleanup(func() {
+		if hadKeys {
+			_ = jwt.SetKeys(previous)
+		} else {
+			jwt.ResetJWTKeys()
+		}
+	})
+	initTestJWTKeysService(t)
+
+	encryptor, err := crypto.NewFieldEncryptor(make([]byte, crypto.FieldKeySize))
+	require.NoError(t, err)
+	config.Encryptor = encryptor
+	if config.RetireAfter == 0 {
+		config.RetireAfter = 24 * time.Hour
+	}
+
+	gormDB, mock := newMockGormDB(t)
+	repo := &mockSigningKeyRepo{}
+	return NewSigningKeyService(gormDB, repo, config), repo, mock
+}
+
+func TestSigningKeyService_Load_StoresEnvironmentKey(t *testing.T) {
+	svc, repo, _ := newSigningKeyTestService(t, SigningKeyConfig{})
+	envKeyID := jwt.SigningKeyID()
+
+	require.NoError(t, svc.Load(context.Background()))
+
+	require.Len(t, repo.keys, 1)
+	stored := repo.keys[0]
+	assert.Equal(t, envKeyID, stored.KeyID)
+	assert.Equal(t, model.SigningKeyStatusActive, stored.Status)
+	assert.Equal(t, "RS256", stored.Algorithm)
+	require.NotNil(t, stored.PrivateKey)
+	assert.False(t, strings.Contains(*stored.PrivateKey, "PRIVATE KEY"), "private key must be stored encrypted")
+	assert.Equal(t, envKeyID, jwt.SigningKeyID())
+}
+
+func TestSigningKeyService_Load_Errors(t *testing.T) {
+	t.Run("repo error", func(t *testing.T) {
+		svc, repo, _ := newSigningKeyTestService(t, SigningKeyConfig{})
+		repo.err = errors.New("db error")
+
+		var target *apperror.InternalError
+		require.ErrorAs(t, svc.Load(context.Background()), &target)
+	})
+
+	t.Run("undecryptable private key", func(t *testing.T) {
+		svc, repo, _ := newSigningKeyTestService(t, SigningKeyConfig{})
+		require.NoError(t, svc.Load(context.Background()))
+		garbage := "not-encrypted"
+		repo.keys[0].PrivateKey = &garbage
+
+		var target *apperror.InternalError
+		require.ErrorAs(t, svc.Load(context.Background()), &target)
+	})
+
+	t.Run("no active key", func(t *testing.T) {
+		svc, repo, _ := newSigningKeyTestService(t, SigningKeyConfig{})
+		require.NoError(t, svc.Load(context.Background()))
+		repo.keys[0].Status = model.SigningKeyStatusPrevious
+
+		err := svc.Load(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no active signing key")
+	})
+}
+
+func TestSigningKeyService_Rotate(t *testing.T) {
+	svc, repo, mock := newSigningKeyTestService(t, SigningKeyConfig{})
+	require.NoError(t, svc.Load(context.Background()))
+	oldKeyID := jwt.SigningKeyID()
+	oldToken, err := jwt.GenerateAccessToken("user-uuid", "read", "https://auth.example.com", "myapp", "client-1", "provider-1")
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	rotated, err := svc.Rotate(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, model.SigningKeyStatusActive, rotated.Status)
+	assert.NotEqual(t, oldKeyID, rotated.KeyID)
+	assert.Equal(t, rotated.KeyID, jwt.SigningKeyID())
+	assert.Equal(t, model.SigningKeyStatusPrevious, repo.keys[0].Status)
+	assert.NotNil(t, repo.keys[0].RotatedAt)
+
+	keys := jwt.VerificationKeys()
+	require.Len(t, keys, 2)
+	assert.Equal(t, rotated.KeyID, keys[0].ID)
+	assert.Equal(t, oldKeyID, keys[1].ID)
+
+	// Tokens signed before the rotation still verify
+	_, err = jwt.ValidateToken(oldToken)
+	require.NoError(t, err)
+}
+
+func TestSigningKeyService_Retire(t *testing.T) {
+	svc, repo, mock := newSigningKeyTestService(t, SigningKeyConfig{})
+	require.NoError(t, svc.Load(context.Background()))
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	_, err := svc.Rotate(context.Background())
+	require.NoError(t, err)
+	previous, active := repo.keys[0], repo.keys[1]
+
+	t.Run("active key", func(t *testing.T) {
+		_, err := svc.Retire(context.Background(), active.SigningKeyUUID)
+		var target *apperror.ConflictError
+		require.ErrorAs(t, err, &target)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := svc.Retire(context.Background(), uuid.New())
+		var target *apperror.NotFoundError
+		require.ErrorAs(t, err, &target)
+	})
+
+	t.Run("previous key", func(t *testing.T) {
+		retired, err := svc.Retire(context.Background(), previous.SigningKeyUUID)
+		require.NoError(t, err)
+		assert.Equal(t, model.SigningKeyStatusRetired, retired.Status)
+		assert.NotNil(t, retired.RetiredAt)
+		assert.Nil(t, previous.PrivateKey)
+		assert.Len(t, jwt.VerificationKeys(), 1)
+	})
+
+	t.Run("already retired", func(t *testing.T) {
+		retired, err := svc.Retire(context.Background(), previous.SigningKeyUUID)
+		require.NoError(t, err)
+		assert.Equal(t, model.SigningKeyStatusRetired, retired.Status)
+	})
+}
+
+func TestSigningKeyService_RotateDue(t *testing.T) {
+	t.Run("active key not due", func(t *testing.T) {
+		svc, repo, mock := newSigningKeyTestService(t, SigningKeyConfig{RotationInterval: time.Hour})
+		require.NoError(t, svc.Load(context.Background()))
+
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		count, err := svc.RotateDue(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		assert.Len(t, repo.keys, 1)
+	})
+
+	t.Run("rotates and retires", func(t *testing.T) {
+		svc, repo, mock := newSigningKeyTestService(t, SigningKeyConfig{RotationInterval: time.Hour, RetireAfter: time.Hour})
+		require.NoError(t, svc.Load(context.Background()))
+		repo.keys[0].ActivatedAt = time.Now().Add(-2 * time.Hour)
+
+		// First run rotates the expired key
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		count, err := svc.RotateDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		assert.Len(t, jwt.VerificationKeys(), 2)
+
+		// Once its grace period is over the previous key is retired
+		past := time.Now().Add(-2 * time.Hour)
+		repo.keys[0].RotatedAt = &past
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		count, err = svc.RotateDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		assert.Equal(t, model.SigningKeyStatusRetired, repo.keys[0].Status)
+		assert.Len(t, jwt.VerificationKeys(), 1)
+	})
+
+	t.Run("manual rotation only", func(t *testing.T) {
+		svc, repo, _ := newSigningKeyTestService(t, SigningKeyConfig{})
+		require.NoError(t, svc.Load(context.Background()))
+		repo.keys[0].ActivatedAt = time.Now().Add(-365 * 24 * time.Hour)
+
+		count, err := svc.RotateDue(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		assert.Len(t, repo.keys, 1)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		svc, repo, _ := newSigningKeyTestService(t, SigningKeyConfig{})
+		repo.err = errors.New("db error")
+
+		_, err := svc.RotateDue(context.Background())
+		var target *apperror.InternalError
+		require.ErrorAs(t, err, &target)
+	})
+}
+
+func TestSigningKeyService_List(t *testing.T) {
+	svc, _, mock := newSigningKeyTestService(t, SigningKeyConfig{})
+	require.NoError(t, svc.Load(context.Background()))
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	rotated, err := svc.Rotate(context.Background())
+	require.NoError(t, err)
+
+	keys, err := svc.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, rotated.KeyID, keys[0].KeyID)
+	assert.Equal(t, model.SigningKeyStatusPrevious, keys[1].Status)
+}
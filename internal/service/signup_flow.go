@@ -10,6 +10,7 @@ import (
 	"github.com/maintainerd/auth/internal/crypto"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/signupflow"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -210,6 +211,12 @@ func (s *signupFlowService) Create(ctx context.Context, tenantID int64, name, de
 		} else {
 			configJSON = datatypes.JSON([]byte("{}"))
 		}
+		if _, err := signupflow.Parse(configJSON); err != nil {
+			return apperror.NewValidation(err.Error())
+		}
+		if err := rejectPastedSecrets("config", configJSON); err != nil {
+			return err
+		}
 
 		// Create signup flow
 		signupFlow := &model.SignupFlow{
@@ -279,6 +286,12 @@ func (s *signupFlowService) Update(ctx context.Context, signupFlowUUID uuid.UUID
 		} else {
 			configJSON = datatypes.JSON([]byte("{}"))
 		}
+		if _, err := signupflow.Parse(configJSON); err != nil {
+			return apperror.NewValidation(err.Error())
+		}
+		if err := rejectPastedSecrets("config", configJSON); err != nil {
+			return err
+		}
 
 		// Update fields (identifier remains unchanged)
 		signupFlow.Name = name
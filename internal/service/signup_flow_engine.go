@@ -0,0 +1,449 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"html/template"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/email"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
+	"github.com/maintainerd/auth/internal/signupflow"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// emailVerificationTTL is how long an emailed verification code stays valid.
+const emailVerificationTTL = 24 * time.Hour
+
+// errInvalidVerificationCode is returned for every VerifyEmail failure that
+// would otherwise reveal whether the account exists.
+var errInvalidVerificationCode = apperror.NewValidation("invalid or expired verification code")
+
+// SignupFlowInput carries the signup flow parameters of a public registration.
+type SignupFlowInput struct {
+	// Identifier selects a flow of the client. When empty the client's
+	// oldest active flow applies, if any.
+	Identifier string
+	// CaptchaToken is the token solved by the user, required when the flow
+	// configures a CAPTCHA.
+	CaptchaToken string
+	// Birthdate is stored on the user's profile and checked by flows with
+	// an age gate.
+	Birthdate *time.Time
+	// GuardianEmail receives the consent request when the flow's age gate
+	// holds the account for a guardian's approval.
+	GuardianEmail string
+}
+
+// signupPlan is the flow resolved for one registration. A nil flow means the
+// client has no signup flow and the zero config applies.
+type signupPlan struct {
+	flow   *model.SignupFlow
+	config signupflow.Config
+	// invite marks a registration through an invitation, which only counts
+	// towards the flow's caps.
+	invite bool
+	// parentalConsent holds the account until the guardian approves it.
+	parentalConsent bool
+}
+
+// evaluateSignupFlow resolves the signup flow for client and checks the
+// registration against it: required fields, allowed email domains, signup
+// caps and the CAPTCHA. CAPTCHA verification fails closed.
+func (s *registerService) evaluateSignupFlow(
+	ctx context.Context,
+	signupFlowRepo repository.SignupFlowRepository,
+	client *model.Client,
+	in SignupFlowInput,
+	user signupflow.Input,
+) (*signupPlan, error) {
+	var flow *model.SignupFlow
+	var err error
+	if in.Identifier != "" {
+		flow, err = signupFlowRepo.FindByIdentifierAndClientID(in.Identifier, client.ClientID)
+		if err != nil {
+			return nil, apperror.NewInternal("failed to find signup flow", err)
+		}
+		if flow == nil || flow.Status != model.StatusActive {
+			return nil, apperror.NewValidation("invalid or inactive signup flow")
+		}
+	} else {
+		flow, err = signupFlowRepo.FindActiveByClientID(client.ClientID)
+		if err != nil {
+			return nil, apperror.NewInternal("failed to find signup flow", err)
+		}
+	}
+	if flow == nil {
+		return &signupPlan{}, nil
+	}
+
+	cfg, err := signupflow.Parse(flow.Config)
+	if err != nil {
+		return nil, apperror.NewInternal("invalid signup flow config", err)
+	}
+	if err := cfg.Check(user); err != nil {
+		return nil, apperror.NewValidation(err.Error())
+	}
+	if err := s.checkSignupCaps(flow, cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.Captcha != nil {
+		if s.captchaVerifier == nil {
+			return nil, apperror.NewInternal("captcha verification is not configured", nil)
+		}
+		ok, err := s.captchaVerifier.Verify(ctx, *cfg.Captcha, in.CaptchaToken, middleware.ClientIPFromContext(ctx))
+		if err != nil {
+			return nil, apperror.NewInternal("captcha verification failed", err)
+		}
+		if !ok {
+			return nil, apperror.NewValidation("captcha verification failed")
+		}
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("signup_flow.identifier", flow.Identifier))
+	return &signupPlan{flow: flow, config: cfg, parentalConsent: cfg.NeedsParentalConsent(user)}, nil
+}
+
+// inviteSignupPlan resolves the plan for a registration through an
+// invitation: the client's active flow when its caps include invitations,
+// otherwise no flow. The caps are checked like for a public registration.
+func (s *registerService) inviteSignupPlan(signupFlowRepo repository.SignupFlowRepository, clientID int64) (*signupPlan, error) {
+	flow, err := signupFlowRepo.FindActiveByClientID(clientID)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to find signup flow", err)
+	}
+	if flow == nil {
+		return &signupPlan{}, nil
+	}
+	cfg, err := signupflow.Parse(flow.Config)
+	if err != nil {
+		return nil, apperror.NewInternal("invalid signup flow config", err)
+	}
+	if cfg.Caps == nil || !cfg.Caps.IncludeInvites {
+		return &signupPlan{}, nil
+	}
+	if err := s.checkSignupCaps(flow, cfg); err != nil {
+		return nil, err
+	}
+	return &signupPlan{flow: flow, config: cfg, invite: true}, nil
+}
+
+// checkSignupCaps rejects a registration early when the flow is already at
+// one of its caps. It does not lock the flow; recordFlowSignup checks again
+// under the lock before the account is counted.
+func (s *registerService) checkSignupCaps(flow *model.SignupFlow, cfg signupflow.Config) error {
+	if cfg.Caps == nil || !cfg.Caps.Limited() {
+		return nil
+	}
+	usage, err := s.signupFlowUsage(s.signupFlowSignupRepo, flow.SignupFlowID)
+	if err != nil {
+		return err
+	}
+	return signupCapError(cfg.Caps.Check(usage))
+}
+
+// recordFlowSignup counts a new account towards its flow. When the flow has
+// caps, the flow row is locked so that concurrent registrations are counted
+// one at a time, the caps are checked again, and a cap_warning or
+// cap_reached event is recorded for every threshold this signup crosses.
+func (s *registerService) recordFlowSignup(
+	signupFlowRepo repository.SignupFlowRepository,
+	signupRepo repository.SignupFlowSignupRepository,
+	eventRepo repository.EventRepository,
+	plan *signupPlan,
+	userID int64,
+) error {
+	if plan.flow == nil {
+		return nil
+	}
+
+	var crossed []signupflow.Threshold
+	if caps := plan.config.Caps; caps != nil && caps.Limited() {
+		if err := signupFlowRepo.LockByID(plan.flow.SignupFlowID); err != nil {
+			return apperror.NewInternal("failed to lock signup flow", err)
+		}
+		usage, err := s.signupFlowUsage(signupRepo, plan.flow.SignupFlowID)
+		if err != nil {
+			return err
+		}
+		if err := signupCapError(caps.Check(usage)); err != nil {
+			return err
+		}
+		crossed = caps.Crossed(usage)
+	}
+
+	if _, err := signupRepo.Create(&model.SignupFlowSignup{
+		SignupFlowID: plan.flow.SignupFlowID,
+		UserID:       &userID,
+		IsInvite:     plan.invite,
+	}); err != nil {
+		return apperror.NewInternal("failed to record signup", err)
+	}
+
+	for _, t := range crossed {
+		payload := signupFlowCapEventPayload{
+			SignupFlowUUID: plan.flow.SignupFlowUUID,
+			Identifier:     plan.flow.Identifier,
+			Cap:            t.Cap,
+			Limit:          t.Limit,
+			Count:          t.Count,
+		}
+		var event DomainEvent = SignupFlowCapWarning(payload)
+		if t.Kind == signupflow.ThresholdReached {
+			event = SignupFlowCapReached(payload)
+		}
+		if err := recordEvent(eventRepo, plan.flow.TenantID, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// signupFlowUsage counts the flow's signups in total and since the start of
+// the current UTC day.
+func (s *registerService) signupFlowUsage(signupRepo repository.SignupFlowSignupRepository, signupFlowID int64) (signupflow.Usage, error) {
+	total, err := signupRepo.CountBySignupFlowID(signupFlowID, time.Time{})
+	if err != nil {
+		return signupflow.Usage{}, apperror.NewInternal("failed to count signups", err)
+	}
+	today, err := signupRepo.CountBySignupFlowID(signupFlowID, signupflow.DayStart(time.Now()))
+	if err != nil {
+		return signupflow.Usage{}, apperror.NewInternal("failed to count signups", err)
+	}
+	return signupflow.Usage{Total: total, Today: today}, nil
+}
+
+// signupCapError maps a cap rejection to a user-facing error.
+func signupCapError(err error) error {
+	var capErr *signupflow.CapError
+	if errors.As(err, &capErr) {
+		return apperror.NewForbidden(capErr.Error())
+	}
+	return err
+}
+
+// signupRoles returns the roles granted to a new user: the flow's roles when
+// it defines any, otherwise the tenant default role.
+func (s *registerService) signupRoles(
+	roleRepo repository.RoleRepository,
+	signupFlowRoleRepo repository.SignupFlowRoleRepository,
+	plan *signupPlan,
+	tenantID int64,
+) ([]model.Role, error) {
+	if plan.flow != nil {
+		flowRoles, err := signupFlowRoleRepo.FindBySignupFlowID(plan.flow.SignupFlowID)
+		if err != nil {
+			return nil, err
+		}
+		roles := make([]model.Role, 0, len(flowRoles))
+		for _, fr := range flowRoles {
+			if fr.Role != nil {
+				roles = append(roles, *fr.Role)
+			}
+		}
+		if len(roles) > 0 {
+			return roles, nil
+		}
+	}
+
+	defaultRole, err := s.findDefaultRole(roleRepo, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return []model.Role{*defaultRole}, nil
+}
+
+// sendVerificationEmail emails the verification code to a pending user. The
+// account is already committed, so failures are recorded rather than
+// returned; the user can register again once the code expires.
+func (s *registerService) sendVerificationEmail(ctx context.Context, to, code string) {
+	if err := s.deliverVerificationEmail(ctx, to, code); err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "email_verification_send_failure",
+			Timestamp: time.Now(),
+			Details:   err.Error(),
+			Severity:  "MEDIUM",
+		})
+	}
+}
+
+func (s *registerService) deliverVerificationEmail(ctx context.Context, to, code string) error {
+	templateEntity, err := s.emailTemplateRepo.FindByName("internal:user:email:verification")
+	if err != nil {
+		return apperror.NewInternal("failed to fetch email verification template", err)
+	}
+	if templateEntity == nil {
+		return apperror.NewNotFoundWithReason("email verification template not found")
+	}
+
+	data := struct {
+		Code    string
+		LogoURL string
+	}{
+		Code:    code,
+		LogoURL: config.EmailLogo,
+	}
+
+	tmpl, err := template.New("verification_html").Parse(templateEntity.BodyHTML)
+	if err != nil {
+		return apperror.NewInternal("failed to parse HTML verification template", err)
+	}
+	var bodyHTML bytes.Buffer
+	if err := tmpl.Execute(&bodyHTML, data); err != nil {
+		return apperror.NewInternal("failed to execute HTML verification template", err)
+	}
+
+	var bodyPlainStr string
+	if templateEntity.BodyPlain != nil {
+		tmplPlain, err := template.New("verification_plain").Parse(*templateEntity.BodyPlain)
+		if err != nil {
+			return apperror.NewInternal("failed to parse plain verification template", err)
+		}
+		var bodyPlain bytes.Buffer
+		if err := tmplPlain.Execute(&bodyPlain, data); err != nil {
+			return apperror.NewInternal("failed to execute plain verification template", err)
+		}
+		bodyPlainStr = bodyPlain.String()
+	}
+
+	return email.SendEmail(ctx, email.SendEmailParams{
+		To:        to,
+		Subject:   templateEntity.Subject,
+		BodyHTML:  bodyHTML.String(),
+		BodyPlain: bodyPlainStr,
+	})
+}
+
+// VerifyEmail confirms the verification code emailed to a pending user,
+// activates the account and issues its first tokens. An account still
+// waiting for parental consent is not activated and gets no tokens.
+func (s *registerService) VerifyEmail(ctx context.Context, emailAddr, code, clientID, providerID string) (*dto.RegisterResponseDTO, error) {
+	_, span := otel.Tracer("service").Start(ctx, "register.verify_email")
+	defer span.End()
+	span.SetAttributes(attribute.String("client.id", clientID), attribute.String("provider.id", providerID))
+
+	limiterKey := "verify:" + emailAddr
+	if err := security.CheckRateLimit(limiterKey); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "verify email failed")
+		return nil, err
+	}
+
+	var user *model.User
+	var client *model.Client
+	var sub string
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txUserRepo := s.userRepo.WithTx(tx)
+		txUserTokenRepo := s.userTokenRepo.WithTx(tx)
+
+		var txErr error
+		client, txErr = s.clientRepo.WithTx(tx).FindByClientIDAndIdentityProvider(clientID, providerID)
+		if txErr != nil {
+			return txErr
+		}
+		if client == nil ||
+			client.Status != model.StatusActive ||
+			client.Domain == nil || *client.Domain == "" {
+			return apperror.NewValidation("invalid or inactive auth client")
+		}
+
+		user, txErr = txUserRepo.FindByEmailAndTenantID(emailAddr, client.IdentityProvider.TenantID)
+		if txErr != nil {
+			return apperror.NewInternal("failed to find user", txErr)
+		}
+		if user == nil || user.Status != model.StatusPending {
+			return errInvalidVerificationCode
+		}
+
+		tokens, txErr := txUserTokenRepo.FindByUserIDAndTokenType(user.UserID, model.TokenTypeEmailVerification)
+		if txErr != nil {
+			return apperror.NewInternal("failed to find verification token", txErr)
+		}
+		var match *model.UserToken
+		now := time.Now()
+		for i := range tokens {
+			t := &tokens[i]
+			if t.IsRevoked || (t.ExpiresAt != nil && now.After(*t.ExpiresAt)) {
+				continue
+			}
+			if subtle.ConstantTimeCompare([]byte(t.Token), []byte(code)) == 1 {
+				match = t
+				break
+			}
+		}
+		if match == nil {
+			return errInvalidVerificationCode
+		}
+
+		if txErr = txUserTokenRepo.RevokeByUUID(match.UserTokenUUID); txErr != nil {
+			return apperror.NewInternal("failed to revoke verification token", txErr)
+		}
+		if txErr = txUserRepo.SetEmailVerified(user.UserUUID, true); txErr != nil {
+			return apperror.NewInternal("failed to verify email", txErr)
+		}
+		// Under-age accounts keep waiting for their guardian's consent
+		status := model.StatusActive
+		if s.parentalConsentRepo != nil {
+			consent, txErr := s.parentalConsentRepo.WithTx(tx).FindPendingByUserID(user.UserID)
+			if txErr != nil {
+				return apperror.NewInternal("failed to find parental consent", txErr)
+			}
+			if consent != nil {
+				status = model.StatusPendingConsent
+			}
+		}
+		if txErr = txUserRepo.SetStatus(user.UserUUID, status); txErr != nil {
+			return apperror.NewInternal("failed to activate user", txErr)
+		}
+		user.IsEmailVerified = true
+		user.Status = status
+
+		identity, txErr := s.userIdentityRepo.WithTx(tx).FindByUserIDAndClientID(user.UserID, client.ClientID)
+		if txErr != nil {
+			return apperror.NewInternal("failed to find user identity", txErr)
+		}
+		if identity != nil {
+			sub = identity.Sub
+		}
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, errInvalidVerificationCode) {
+			security.RecordFailedAttempt(limiterKey)
+			security.LogSecurityEvent(security.SecurityEvent{
+				EventType: "email_verification_failure",
+				ClientID:  clientID,
+				Timestamp: time.Now(),
+				Details:   "invalid or expired verification code",
+				Severity:  "LOW",
+			})
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "verify email failed")
+		return nil, err
+	}
+
+	security.ResetFailedAttempts(limiterKey)
+	span.SetAttributes(attribute.String("user.uuid", user.UserUUID.String()))
+	span.SetStatus(codes.Ok, "")
+	if user.Status == model.StatusPendingConsent {
+		return &dto.RegisterResponseDTO{ParentalConsentRequired: true}, nil
+	}
+	return s.generateTokenResponse(ctx, sub, user, client)
+}
@@ -0,0 +1,500 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/signupflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+type mockCaptchaVerifier struct {
+	ok    bool
+	err   error
+	token string
+}
+
+func (m *mockCaptchaVerifier) Verify(_ context.Context, _ signupflow.Captcha, token, _ string) (bool, error) {
+	m.token = token
+	return m.ok, m.err
+}
+
+func activeSignupFlow(config string) *model.SignupFlow {
+	return &model.SignupFlow{
+		SignupFlowID: 7,
+		Identifier:   "beta",
+		Status:       model.StatusActive,
+		ClientID:     1,
+		Config:       datatypes.JSON(config),
+	}
+}
+
+func newSignupFlowRegisterService(t *testing.T, m *regMocks, flowRepo *mockSignupFlowRepo, flowRoleRepo *mockSignupFlowRoleRepo, captcha signupflow.CaptchaVerifier) RegisterService {
+	t.Helper()
+	gormDB, mock := newMockGormDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	return NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
+		m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{},
+		flowRepo, flowRoleRepo, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, captcha, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+}
+
+func TestRegisterPublic_SignupFlow(t *testing.T) {
+	initTestJWTKeysService(t)
+	ctx := context.Background()
+	email := "jane@acme.com"
+
+	t.Run("named flow not found", func(t *testing.T) {
+		svc := newSignupFlowRegisterService(t, defaultRegPublicMocks(), &mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, nil)
+		_, err := svc.RegisterPublic(ctx, "u", "F", "P@ss1!", &email, nil, "c", "p", SignupFlowInput{Identifier: "missing"}, nil)
+		var target *apperror.ValidationError
+		require.ErrorAs(t, err, &target)
+		assert.Contains(t, err.Error(), "signup flow")
+	})
+
+	t.Run("named flow inactive", func(t *testing.T) {
+		flow := activeSignupFlow(`{}`)
+		flow.Status = model.StatusInactive
+		repo := &mockSignupFlowRepo{findByIdentifierAndClientIDFn: func(_ string, _ int64) (*model.SignupFlow, error) { return flow, nil }}
+		svc := newSignupFlowRegisterService(t, defaultRegPublicMocks(), repo, &mockSignupFlowRoleRepo{}, nil)
+		_, err := svc.RegisterPublic(ctx, "u", "F", "P@ss1!", &email, nil, "c", "p", SignupFlowInput{Identifier: "beta"}, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("required field missing", func(t *testing.T) {
+		repo := &mockSignupFlowRepo{findActiveByClientIDFn: func(_ int64) (*model.SignupFlow, error) {
+			return activeSignupFlow(`{"required_fields":["phone"]}`), nil
+		}}
+		m := defaultRegPublicMocks()
+		m.user.createFn = func(_ *model.User) (*model.User, error) {
+			t.Fatal("user should not be created")
+			return nil, nil
+		}
+		svc := newSignupFlowRegisterService(t, m, repo, &mockSignupFlowRoleRepo{}, nil)
+		_, err := svc.RegisterPublic(ctx, "u", "F", "P@ss1!", &email, nil, "c", "p", SignupFlowInput{}, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "phone is required")
+	})
+
+	t.Run("email domain not allowed", func(t *testing.T) {
+		repo := &mockSignupFlowRepo{findActiveByClientIDFn: func(_ int64) (*model.SignupFlow, error) {
+			return activeSignupFlow(`{"allowed_email_domains":["example.org"]}`), nil
+		}}
+		svc := newSignupFlowRegisterService(t, defaultRegPublicMocks(), repo, &mockSignupFlowRoleRepo{}, nil)
+		_, err := svc.RegisterPublic(ctx, "u", "F", "P@ss1!", &email, nil, "c", "p", SignupFlowInput{}, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "email domain")
+	})
+
+	captchaFlow := &mockSignupFlowRepo{findActiveByClientIDFn: func(_ int64) (*model.SignupFlow, error) {
+		return activeSignupFlow(`{"captcha":{"provider":"turnstile","secret_key":"s"}}`), nil
+	}}
+
+	t.Run("captcha rejected", func(t *testing.T) {
+		verifier := &mockCaptchaVerifier{ok: false}
+		svc := newSignupFlowRegisterService(t, defaultRegPublicMocks(), captchaFlow, &mockSignupFlowRoleRepo{}, verifier)
+		_, err := svc.RegisterPublic(ctx, "u", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{CaptchaToken: "bad"}, nil)
+		var target *apperror.ValidationError
+		require.ErrorAs(t, err, &target)
+		assert.Equal(t, "bad", verifier.token)
+	})
+
+	t.Run("captcha provider error fails closed", func(t *testing.T) {
+		verifier := &mockCaptchaVerifier{err: errors.New("timeout")}
+		svc := newSignupFlowRegisterService(t, defaultRegPublicMocks(), captchaFlow, &mockSignupFlowRoleRepo{}, verifier)
+		_, err := svc.RegisterPublic(ctx, "u", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{CaptchaToken: "tok"}, nil)
+		var target *apperror.InternalError
+		require.ErrorAs(t, err, &target)
+	})
+
+	t.Run("captcha without verifier fails closed", func(t *testing.T) {
+		svc := newSignupFlowRegisterService(t, defaultRegPublicMocks(), captchaFlow, &mockSignupFlowRoleRepo{}, nil)
+		_, err := svc.RegisterPublic(ctx, "u", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{CaptchaToken: "tok"}, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("flow roles replace default role", func(t *testing.T) {
+		repo := &mockSignupFlowRepo{findByIdentifierAndClientIDFn: func(id string, _ int64) (*model.SignupFlow, error) {
+			assert.Equal(t, "beta", id)
+			return activeSignupFlow(`{}`), nil
+		}}
+		roleRepo := &mockSignupFlowRoleRepo{findBySignupFlowIDFn: func(id int64) ([]model.SignupFlowRole, error) {
+			assert.Equal(t, int64(7), id)
+			return []model.SignupFlowRole{
+				{Role: &model.Role{RoleID: 11}},
+				{Role: &model.Role{RoleID: 12}},
+			}, nil
+		}}
+		m := defaultRegPublicMocks()
+		var assigned []int64
+		m.userRole.createFn = func(ur *model.UserRole) (*model.UserRole, error) {
+			assigned = append(assigned, ur.RoleID)
+			return ur, nil
+		}
+		svc := newSignupFlowRegisterService(t, m, repo, roleRepo, nil)
+		_, _ = svc.RegisterPublic(ctx, "u", "F", "P@ss1!", nil, nil, "c", "p", SignupFlowInput{Identifier: "beta"}, nil)
+		assert.Equal(t, []int64{11, 12}, assigned)
+	})
+
+	t.Run("email verification holds account as pending", func(t *testing.T) {
+		repo := &mockSignupFlowRepo{findActiveByClientIDFn: func(_ int64) (*model.SignupFlow, error) {
+			return activeSignupFlow(`{"email_verification":true}`), nil
+		}}
+		m := defaultRegPublicMocks()
+		var created *model.User
+		m.user.createFn = func(u *model.User) (*model.User, error) {
+			u.UserID = 1
+			created = u
+			return u, nil
+		}
+		var token *model.UserToken
+		m.userToken.createFn = func(ut *model.UserToken) (*model.UserToken, error) {
+			token = ut
+			return ut, nil
+		}
+		svc := newSignupFlowRegisterService(t, m, repo, &mockSignupFlowRoleRepo{}, nil)
+		res, err := svc.RegisterPublic(ctx, "u", "F", "P@ss1!", &email, nil, "c", "p", SignupFlowInput{}, nil)
+		require.NoError(t, err)
+		assert.True(t, res.VerificationRequired)
+		assert.Empty(t, res.AccessToken)
+		assert.Equal(t, model.StatusPending, created.Status)
+		require.NotNil(t, token.ExpiresAt)
+		assert.WithinDuration(t, time.Now().Add(emailVerificationTTL), *token.ExpiresAt, time.Minute)
+	})
+
+	ageGated := func(t *testing.T, m *regMocks, consents *mockParentalConsentRepo, profiles *mockProfileRepo) RegisterService {
+		t.Helper()
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		repo := &mockSignupFlowRepo{findActiveByClientIDFn: func(_ int64) (*model.SignupFlow, error) {
+			return activeSignupFlow(`{"age_gate":{"minimum_age":13,"parental_consent":true}}`), nil
+		}}
+		return NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{},
+			repo, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, consents, profiles)
+	}
+	yearsAgo := func(years int) *time.Time {
+		d := time.Now().AddDate(-years, 0, -1)
+		return &d
+	}
+
+	t.Run("age gate rejects missing birthdate", func(t *testing.T) {
+		svc := ageGated(t, defaultRegPublicMocks(), &mockParentalConsentRepo{}, &mockProfileRepo{})
+		_, err := svc.RegisterPublic(ctx, "u", "F", "P@ss1!", &email, nil, "c", "p", SignupFlowInput{}, nil)
+		var target *apperror.ValidationError
+		require.ErrorAs(t, err, &target)
+		assert.Contains(t, err.Error(), "birthdate is required")
+	})
+
+	t.Run("age gate requires a guardian under age", func(t *testing.T) {
+		svc := ageGated(t, defaultRegPublicMocks(), &mockParentalConsentRepo{}, &mockProfileRepo{})
+		_, err := svc.RegisterPublic(ctx, "u", "F", "P@ss1!", &email, nil, "c", "p", SignupFlowInput{Birthdate: yearsAgo(10)}, nil)
+		var target *apperror.ValidationError
+		require.ErrorAs(t, err, &target)
+		assert.Contains(t, err.Error(), "guardian_email is required")
+	})
+
+	t.Run("under-age account waits for parental consent", func(t *testing.T) {
+		m := defaultRegPublicMocks()
+		var created *model.User
+		m.user.createFn = func(u *model.User) (*model.User, error) {
+			u.UserID = 1
+			created = u
+			return u, nil
+		}
+		consents := &mockParentalConsentRepo{}
+		var profile *model.Profile
+		profiles := &mockProfileRepo{createFn: func(p *model.Profile) (*model.Profile, error) {
+			profile = p
+			return p, nil
+		}}
+		svc := ageGated(t, m, consents, profiles)
+		birthdate := yearsAgo(10)
+		res, err := svc.RegisterPublic(ctx, "u", "F", "P@ss1!", &email, nil, "c", "p",
+			SignupFlowInput{Birthdate: birthdate, GuardianEmail: "parent@acme.com"}, nil)
+		require.NoError(t, err)
+		assert.True(t, res.ParentalConsentRequired)
+		assert.False(t, res.VerificationRequired)
+		assert.Empty(t, res.AccessToken)
+		assert.Equal(t, model.StatusPendingConsent, created.Status)
+		require.Len(t, consents.created, 1)
+		assert.Equal(t, "parent@acme.com", consents.created[0].GuardianEmail)
+		assert.Len(t, consents.created[0].Token, 64)
+		require.NotNil(t, profile)
+		assert.Equal(t, birthdate, profile.Birthdate)
+	})
+
+	t.Run("adult account skips parental consent", func(t *testing.T) {
+		m := defaultRegPublicMocks()
+		var created *model.User
+		m.user.createFn = func(u *model.User) (*model.User, error) {
+			u.UserID = 1
+			created = u
+			return u, nil
+		}
+		consents := &mockParentalConsentRepo{}
+		svc := ageGated(t, m, consents, &mockProfileRepo{})
+		// Token issuance fails on the mocks; the registration itself commits.
+		_, _ = svc.RegisterPublic(ctx, "u", "F", "P@ss1!", &email, nil, "c", "p", SignupFlowInput{Birthdate: yearsAgo(30)}, nil)
+		require.NotNil(t, created)
+		assert.Equal(t, model.StatusActive, created.Status)
+		assert.Empty(t, consents.created)
+	})
+}
+
+func TestRegisterService_VerifyEmail(t *testing.T) {
+	initTestJWTKeysService(t)
+	ctx := context.Background()
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	pendingUser := func() *model.User {
+		return &model.User{UserID: 1, UserUUID: uuid.New(), Email: "jane@acme.com", Status: model.StatusPending}
+	}
+
+	newSvc := func(t *testing.T, user *model.User, tokens []model.UserToken, commit bool) (RegisterService, *regMocks) {
+		t.Helper()
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		if commit {
+			mock.ExpectCommit()
+		} else {
+			mock.ExpectRollback()
+		}
+		m := defaultRegPublicMocks()
+		m.user.findByEmailAndTenantIDFn = func(_ string, _ int64) (*model.User, error) { return user, nil }
+		m.userToken.findByUserIDAndTokenTypeFn = func(_ int64, tt string) ([]model.UserToken, error) {
+			assert.Equal(t, model.TokenTypeEmailVerification, tt)
+			return tokens, nil
+		}
+		m.userIdentity.findByUserIDAndClientIDFn = func(_, _ int64) (*model.UserIdentity, error) {
+			return &model.UserIdentity{Sub: "sub-1"}, nil
+		}
+		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{},
+			&mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		return svc, m
+	}
+
+	t.Run("success activates user and issues tokens", func(t *testing.T) {
+		svc, m := newSvc(t, pendingUser(), []model.UserToken{{Token: "123456", ExpiresAt: &future}}, true)
+		var status string
+		m.user.setStatusFn = func(_ uuid.UUID, s string) error { status = s; return nil }
+		res, err := svc.VerifyEmail(ctx, "jane@acme.com", "123456", "c", "p")
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.AccessToken)
+		assert.Equal(t, model.StatusActive, status)
+	})
+
+	t.Run("pending parental consent holds the account", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		m := defaultRegPublicMocks()
+		m.user.findByEmailAndTenantIDFn = func(_ string, _ int64) (*model.User, error) { return pendingUser(), nil }
+		m.userToken.findByUserIDAndTokenTypeFn = func(_ int64, _ string) ([]model.UserToken, error) {
+			return []model.UserToken{{Token: "123456", ExpiresAt: &future}}, nil
+		}
+		m.userIdentity.findByUserIDAndClientIDFn = func(_, _ int64) (*model.UserIdentity, error) {
+			return &model.UserIdentity{Sub: "sub-1"}, nil
+		}
+		var status string
+		m.user.setStatusFn = func(_ uuid.UUID, s string) error { status = s; return nil }
+		consents := &mockParentalConsentRepo{findPendingByUserFn: func(userID int64) (*model.ParentalConsent, error) {
+			assert.Equal(t, int64(1), userID)
+			return &model.ParentalConsent{Status: model.StatusPending}, nil
+		}}
+		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
+			m.userIdentity, m.role, m.invite, m.idp, &mockEventRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{},
+			&mockSignupFlowRepo{}, &mockSignupFlowRoleRepo{}, &mockSignupFlowSignupRepo{}, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, consents, &mockProfileRepo{})
+
+		res, err := svc.VerifyEmail(ctx, "jane@acme.com", "123456", "c", "p")
+		require.NoError(t, err)
+		assert.True(t, res.ParentalConsentRequired)
+		assert.Empty(t, res.AccessToken)
+		assert.Equal(t, model.StatusPendingConsent, status)
+	})
+
+	t.Run("wrong code", func(t *testing.T) {
+		svc, _ := newSvc(t, pendingUser(), []model.UserToken{{Token: "123456", ExpiresAt: &future}}, false)
+		_, err := svc.VerifyEmail(ctx, "jane@acme.com", "654321", "c", "p")
+		require.ErrorIs(t, err, errInvalidVerificationCode)
+	})
+
+	t.Run("expired code", func(t *testing.T) {
+		svc, _ := newSvc(t, pendingUser(), []model.UserToken{{Token: "123456", ExpiresAt: &past}}, false)
+		_, err := svc.VerifyEmail(ctx, "jane@acme.com", "123456", "c", "p")
+		require.ErrorIs(t, err, errInvalidVerificationCode)
+	})
+
+	t.Run("revoked code", func(t *testing.T) {
+		svc, _ := newSvc(t, pendingUser(), []model.UserToken{{Token: "123456", ExpiresAt: &future, IsRevoked: true}}, false)
+		_, err := svc.VerifyEmail(ctx, "jane@acme.com", "123456", "c", "p")
+		require.ErrorIs(t, err, errInvalidVerificationCode)
+	})
+
+	t.Run("unknown user looks like a bad code", func(t *testing.T) {
+		svc, _ := newSvc(t, nil, nil, false)
+		_, err := svc.VerifyEmail(ctx, "nobody@acme.com", "123456", "c", "p")
+		require.ErrorIs(t, err, errInvalidVerificationCode)
+	})
+
+	t.Run("already active user", func(t *testing.T) {
+		user := pendingUser()
+		user.Status = model.StatusActive
+		svc, _ := newSvc(t, user, []model.UserToken{{Token: "123456", ExpiresAt: &future}}, false)
+		_, err := svc.VerifyEmail(ctx, "jane@acme.com", "123456", "c", "p")
+		require.ErrorIs(t, err, errInvalidVerificationCode)
+	})
+}
+
+func TestRegister_SignupCaps(t *testing.T) {
+	initTestJWTKeysService(t)
+	ctx := context.Background()
+	email := "jane@acme.com"
+
+	newService := func(t *testing.T, m *regMocks, config string, signups *mockSignupFlowSignupRepo, events *mockEventRepo) (RegisterService, *mockSignupFlowRepo) {
+		t.Helper()
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		flowRepo := &mockSignupFlowRepo{findActiveByClientIDFn: func(_ int64) (*model.SignupFlow, error) {
+			flow := activeSignupFlow(config)
+			flow.SignupFlowUUID = uuid.MustParse("5b7c0a52-3f0e-4d8e-9a2b-8c1f6e4d2a10")
+			flow.TenantID = 3
+			return flow, nil
+		}}
+		svc := NewRegistrationService(gormDB, m.client, m.user, m.userRole, m.userToken,
+			m.userIdentity, m.role, m.invite, m.idp, events, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{},
+			flowRepo, &mockSignupFlowRoleRepo{}, signups, &mockEmailTemplateRepo{}, nil, nil, nil, nil, NopPlanEnforcer{}, nil, nil, nil)
+		return svc, flowRepo
+	}
+
+	t.Run("uncapped flow records the signup", func(t *testing.T) {
+		signups := &mockSignupFlowSignupRepo{}
+		svc, _ := newService(t, defaultRegPublicMocks(), `{}`, signups, &mockEventRepo{})
+		// Token issuance fails on the mocks; the registration itself commits.
+		_, _ = svc.RegisterPublic(ctx, "u", "F", "P@ss1!", &email, nil, "c", "p", SignupFlowInput{}, nil)
+		require.Len(t, signups.created, 1)
+		assert.Equal(t, int64(7), signups.created[0].SignupFlowID)
+		assert.Equal(t, int64(1), *signups.created[0].UserID)
+		assert.False(t, signups.created[0].IsInvite)
+	})
+
+	t.Run("total cap reached", func(t *testing.T) {
+		m := defaultRegPublicMocks()
+		m.user.createFn = func(_ *model.User) (*model.User, error) {
+			t.Fatal("user should not be created")
+			return nil, nil
+		}
+		signups := &mockSignupFlowSignupRepo{total: 100, today: 2}
+		svc, _ := newService(t, m, `{"caps":{"max_signups":100}}`, signups, &mockEventRepo{})
+		_, err := svc.RegisterPublic(ctx, "u", "F", "P@ss1!", &email, nil, "c", "p", SignupFlowInput{}, nil)
+		var target *apperror.ForbiddenError
+		require.ErrorAs(t, err, &target)
+		assert.Contains(t, err.Error(), "signup limit reached")
+		assert.Empty(t, signups.created)
+	})
+
+	t.Run("daily cap reached", func(t *testing.T) {
+		signups := &mockSignupFlowSignupRepo{total: 40, today: 10}
+		svc, _ := newService(t, defaultRegPublicMocks(), `{"caps":{"max_signups":100,"max_signups_per_day":10}}`, signups, &mockEventRepo{})
+		_, err := svc.RegisterPublic(ctx, "u", "F", "P@ss1!", &email, nil, "c", "p", SignupFlowInput{}, nil)
+		var target *apperror.ForbiddenError
+		require.ErrorAs(t, err, &target)
+		assert.Contains(t, err.Error(), "try again tomorrow")
+	})
+
+	t.Run("cap filled between check and lock", func(t *testing.T) {
+		signups := &mockSignupFlowSignupRepo{total: 9}
+		svc, flowRepo := newService(t, defaultRegPublicMocks(), `{"caps":{"max_signups":10}}`, signups, &mockEventRepo{})
+		flowRepo.lockByIDFn = func(id int64) error {
+			assert.Equal(t, int64(7), id)
+			signups.total = 10
+			return nil
+		}
+		_, err := svc.RegisterPublic(ctx, "u", "F", "P@ss1!", &email, nil, "c", "p", SignupFlowInput{}, nil)
+		var target *apperror.ForbiddenError
+		require.ErrorAs(t, err, &target)
+		assert.Empty(t, signups.created)
+	})
+
+	t.Run("lock error", func(t *testing.T) {
+		signups := &mockSignupFlowSignupRepo{}
+		svc, flowRepo := newService(t, defaultRegPublicMocks(), `{"caps":{"max_signups":10}}`, signups, &mockEventRepo{})
+		flowRepo.lockByIDFn = func(int64) error { return errors.New("db error") }
+		_, err := svc.RegisterPublic(ctx, "u", "F", "P@ss1!", &email, nil, "c", "p", SignupFlowInput{}, nil)
+		var target *apperror.InternalError
+		require.ErrorAs(t, err, &target)
+	})
+
+	t.Run("warning and reached events", func(t *testing.T) {
+		// The 8th of 10 signups crosses the default 80% warning of the total
+		// cap and fills the daily cap of 3.
+		signups := &mockSignupFlowSignupRepo{total: 7, today: 2}
+		events := &mockEventRepo{}
+		svc, _ := newService(t, defaultRegPublicMocks(), `{"caps":{"max_signups":10,"max_signups_per_day":3}}`, signups, events)
+		_, _ = svc.RegisterPublic(ctx, "u", "F", "P@ss1!", &email, nil, "c", "p", SignupFlowInput{}, nil)
+
+		var capEvents []model.Event
+		for _, e := range events.created {
+			if e.AggregateType == model.EventAggregateSignupFlow {
+				capEvents = append(capEvents, e)
+			}
+		}
+		require.Len(t, capEvents, 2)
+		assert.Equal(t, model.EventTypeSignupFlowCapWarning, capEvents[0].EventType)
+		assert.Equal(t, int64(3), capEvents[0].TenantID)
+		assert.Equal(t, "5b7c0a52-3f0e-4d8e-9a2b-8c1f6e4d2a10", capEvents[0].AggregateUUID.String())
+		assert.JSONEq(t, `{"signup_flow_uuid":"5b7c0a52-3f0e-4d8e-9a2b-8c1f6e4d2a10","identifier":"beta","cap":"total","limit":10,"count":8}`, string(capEvents[0].Payload))
+		assert.Equal(t, model.EventTypeSignupFlowCapReached, capEvents[1].EventType)
+		assert.JSONEq(t, `{"signup_flow_uuid":"5b7c0a52-3f0e-4d8e-9a2b-8c1f6e4d2a10","identifier":"beta","cap":"daily","limit":3,"count":3}`, string(capEvents[1].Payload))
+	})
+
+	invite := func() *model.Invite {
+		future := time.Now().Add(time.Hour)
+		return &model.Invite{
+			InviteUUID:   uuid.New(),
+			InvitedEmail: "invite@acme.com",
+			Status:       model.StatusPending,
+			ExpiresAt:    &future,
+		}
+	}
+
+	t.Run("invites are not counted by default", func(t *testing.T) {
+		m := defaultRegPublicMocks()
+		m.invite.findByTokenFn = func(string) (*model.Invite, error) { return invite(), nil }
+		signups := &mockSignupFlowSignupRepo{total: 10}
+		svc, _ := newService(t, m, `{"caps":{"max_signups":10}}`, signups, &mockEventRepo{})
+		_, _ = svc.RegisterInvitePublic(ctx, "u", "P@ss1!", "c", "p", "token", nil)
+		assert.Empty(t, signups.created)
+	})
+
+	t.Run("invites count when included", func(t *testing.T) {
+		m := defaultRegPublicMocks()
+		m.invite.findByTokenFn = func(string) (*model.Invite, error) { return invite(), nil }
+		signups := &mockSignupFlowSignupRepo{total: 3}
+		svc, _ := newService(t, m, `{"caps":{"max_signups":10,"include_invites":true}}`, signups, &mockEventRepo{})
+		_, _ = svc.RegisterInvitePublic(ctx, "u", "P@ss1!", "c", "p", "token", nil)
+		require.Len(t, signups.created, 1)
+		assert.True(t, signups.created[0].IsInvite)
+	})
+
+	t.Run("included invites are rejected at the cap", func(t *testing.T) {
+		m := defaultRegPublicMocks()
+		m.invite.findByTokenFn = func(string) (*model.Invite, error) { return invite(), nil }
+		signups := &mockSignupFlowSignupRepo{total: 10}
+		svc, _ := newService(t, m, `{"caps":{"max_signups":10,"include_invites":true}}`, signups, &mockEventRepo{})
+		_, err := svc.RegisterInvitePublic(ctx, "u", "P@ss1!", "c", "p", "token", nil)
+		var target *apperror.ForbiddenError
+		require.ErrorAs(t, err, &target)
+	})
+}
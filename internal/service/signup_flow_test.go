@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
 	"github.com/maintainerd/auth/internal/crypto"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/repository"
@@ -248,6 +249,20 @@ func TestSignupFlowService_Create(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("invalid flow config", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		cr := defaultCR()
+		cr.findByUUIDFn = func(_ any, _ ...string) (*model.Client, error) { return &model.Client{ClientID: 1}, nil }
+		svc := NewSignupFlowService(db, &mockSignupFlowRepo{
+			findByNameFn: func(_ string) (*model.SignupFlow, error) { return nil, nil },
+		}, &mockSignupFlowRoleRepo{}, &mockRoleRepo{}, cr)
+		_, err := svc.Create(context.Background(), 1, "test-flow", "desc", map[string]any{"required_fields": []string{"nickname"}}, model.StatusActive, clientUUID)
+		var target *apperror.ValidationError
+		require.ErrorAs(t, err, &target)
+	})
+
 	t.Run("Create repo error", func(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
@@ -385,6 +400,18 @@ func TestSignupFlowService_Update(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("invalid flow config", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := NewSignupFlowService(db, &mockSignupFlowRepo{
+			findByUUIDAndTenantIDFn: func(_ uuid.UUID, _ int64, _ ...string) (*model.SignupFlow, error) { return sf, nil },
+		}, &mockSignupFlowRoleRepo{}, &mockRoleRepo{}, defaultCR())
+		_, err := svc.Update(context.Background(), sf.SignupFlowUUID, 1, sf.Name, "desc", map[string]any{"captcha": map[string]any{"provider": "other"}}, model.StatusActive)
+		var target *apperror.ValidationError
+		require.ErrorAs(t, err, &target)
+	})
+
 	t.Run("CreateOrUpdate error", func(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/security"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/datatypes"
+)
+
+// StepUpServiceDataResult is the outcome of a re-authentication.
+type StepUpServiceDataResult struct {
+	ReauthenticatedAt time.Time
+	// ExpiresAt is when step-up protected actions need another
+	// re-authentication.
+	ExpiresAt time.Time
+}
+
+// StepUpService guards high-risk admin actions behind a recent password
+// re-entry. A re-authentication applies to the credential it was made with,
+// as identified by middleware.StepUpCredential, for maxAge. It satisfies
+// middleware.StepUpVerifier.
+type StepUpService interface {
+	middleware.StepUpVerifier
+
+	// Reauthenticate checks user's password and, when it matches, lets
+	// credential pass step-up for StepUpMaxAge. Every attempt is recorded
+	// in the auth event log, and wrong passwords count towards a lockout.
+	Reauthenticate(ctx context.Context, user *model.User, tenantID int64, credential, password string) (*StepUpServiceDataResult, error)
+}
+
+type stepUpService struct {
+	store            cache.StepUpStore
+	authEventService AuthEventService
+	maxAge           time.Duration
+}
+
+// NewStepUpService creates a new StepUpService. A maxAge of 0 disables
+// step-up.
+func NewStepUpService(store cache.StepUpStore, authEventService AuthEventService, maxAge time.Duration) StepUpService {
+	return &stepUpService{
+		store:            store,
+		authEventService: authEventService,
+		maxAge:           maxAge,
+	}
+}
+
+// stepUpKey scopes a credential to its user, so that a credential
+// identifier can never satisfy step-up for someone else.
+func stepUpKey(user *model.User, credential string) string {
+	return user.UserUUID.String() + ":" + credential
+}
+
+func (s *stepUpService) StepUpMaxAge() time.Duration {
+	return s.maxAge
+}
+
+func (s *stepUpService) Reauthenticate(ctx context.Context, user *model.User, tenantID int64, credential, password string) (*StepUpServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "stepUp.reauthenticate")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", user.UserUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	if credential == "" {
+		span.SetStatus(codes.Error, "credential cannot step up")
+		return nil, apperror.NewValidation("this credential cannot re-authenticate")
+	}
+
+	limiterKey := security.RateLimitKey(user.UserUUID.String(), "reauthenticate")
+	if err := security.CheckRateLimit(limiterKey); err != nil {
+		span.SetStatus(codes.Error, "reauthenticate rate limited")
+		return nil, apperror.NewForbidden("too many failed attempts, try again later")
+	}
+	if user.Password == nil {
+		span.SetStatus(codes.Error, "user has no password")
+		return nil, apperror.NewForbidden("set a password before re-authenticating")
+	}
+	if err := security.ComparePassword([]byte(*user.Password), []byte(password)); err != nil {
+		security.RecordFailedAttempt(limiterKey)
+		s.logReauthentication(ctx, tenantID, user, model.AuthEventResultFailure)
+		span.SetStatus(codes.Error, "re-authentication failed")
+		return nil, apperror.NewValidation("password is incorrect")
+	}
+	security.ResetFailedAttempts(limiterKey)
+
+	now := time.Now()
+	if s.maxAge > 0 {
+		if err := s.store.RecordStepUp(ctx, stepUpKey(user, credential), now, s.maxAge); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "record step-up failed")
+			return nil, apperror.NewInternal("failed to record re-authentication", err)
+		}
+	}
+	s.logReauthentication(ctx, tenantID, user, model.AuthEventResultSuccess)
+
+	span.SetStatus(codes.Ok, "")
+	return &StepUpServiceDataResult{
+		ReauthenticatedAt: now,
+		ExpiresAt:         now.Add(s.maxAge),
+	}, nil
+}
+
+func (s *stepUpService) VerifyStepUp(ctx context.Context, auth *middleware.AuthContext, credential, action string) (bool, error) {
+	_, span := otel.Tracer("service").Start(ctx, "stepUp.verify")
+	defer span.End()
+	span.SetAttributes(attribute.String("step_up.action", action))
+
+	var at time.Time
+	if credential != "" {
+		var err error
+		if at, err = s.store.StepUpAt(ctx, stepUpKey(auth.User, credential)); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "lookup failed")
+			return false, err
+		}
+	}
+	ok := !at.IsZero() && time.Since(at) <= s.maxAge
+
+	s.logStepUp(ctx, auth, action, at, ok)
+	span.SetAttributes(attribute.Bool("step_up.granted", ok))
+	span.SetStatus(codes.Ok, "")
+	return ok, nil
+}
+
+// logReauthentication writes the authn_reauthenticate or
+// authn_reauthenticate_fail audit entry of a re-authentication.
+func (s *stepUpService) logReauthentication(ctx context.Context, tenantID int64, user *model.User, result string) {
+	eventType := model.AuthEventTypeReauthenticate
+	severity := model.AuthEventSeverityInfo
+	description := fmt.Sprintf("%s re-authenticated for step-up", user.Username)
+	var reason *string
+	if result == model.AuthEventResultFailure {
+		eventType = model.AuthEventTypeReauthenticateFail
+		severity = model.AuthEventSeverityWarn
+		description = fmt.Sprintf("%s failed to re-authenticate for step-up", user.Username)
+		reason = ptr.Ptr("password is incorrect")
+	}
+	s.authEventService.Log(ctx, AuthEventInput{
+		TenantID:     tenantID,
+		ActorUserID:  &user.UserID,
+		TargetUserID: &user.UserID,
+		IPAddress:    middleware.ClientIPFromContext(ctx),
+		UserAgent:    ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:     model.AuthEventCategoryAuthn,
+		EventType:    eventType,
+		Severity:     severity,
+		Result:       result,
+		Description:  &description,
+		ErrorReason:  reason,
+	})
+}
+
+// logStepUp writes the authz_step_up_granted or authz_step_up_required audit
+// entry of a step-up protected request.
+func (s *stepUpService) logStepUp(ctx context.Context, auth *middleware.AuthContext, action string, reauthenticatedAt time.Time, ok bool) {
+	eventType, result := model.AuthEventTypeStepUpGranted, model.AuthEventResultSuccess
+	description := fmt.Sprintf("%s passed step-up for %s", auth.User.Username, action)
+	var reason *string
+	if !ok {
+		eventType, result = model.AuthEventTypeStepUpRequired, model.AuthEventResultFailure
+		description = fmt.Sprintf("%s was asked to re-authenticate for %s", auth.User.Username, action)
+		reason = ptr.Ptr("no recent re-authentication")
+	}
+	meta := map[string]any{"action": action}
+	if !reauthenticatedAt.IsZero() {
+		meta["reauthenticated_at"] = reauthenticatedAt.UTC()
+	}
+	metadata, _ := json.Marshal(meta)
+
+	var tenantID int64
+	if auth.Tenant != nil {
+		tenantID = auth.Tenant.TenantID
+	}
+	s.authEventService.Log(ctx, AuthEventInput{
+		TenantID:    tenantID,
+		ActorUserID: &auth.User.UserID,
+		IPAddress:   middleware.ClientIPFromContext(ctx),
+		UserAgent:   ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:    model.AuthEventCategoryAuthz,
+		EventType:   eventType,
+		Severity:    model.AuthEventSeverityWarn,
+		Result:      result,
+		Description: &description,
+		ErrorReason: reason,
+		Metadata:    datatypes.JSON(metadata),
+	})
+}
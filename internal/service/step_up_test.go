@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// memStepUpStore is an in-memory cache.StepUpStore.
+type memStepUpStore struct {
+	at  map[string]time.Time
+	err error
+}
+
+func (m *memStepUpStore) RecordStepUp(_ context.Context, credential string, at time.Time, _ time.Duration) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.at == nil {
+		m.at = map[string]time.Time{}
+	}
+	m.at[credential] = at
+	return nil
+}
+
+func (m *memStepUpStore) StepUpAt(_ context.Context, credential string) (time.Time, error) {
+	return m.at[credential], m.err
+}
+
+func TestStepUpService_Reauthenticate(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("Secret-123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	newUser := func() *model.User {
+		return &model.User{UserID: 7, UserUUID: uuid.New(), Username: "admin", Password: ptr.Ptr(string(hash))}
+	}
+	build := func(store *memStepUpStore, events *[]AuthEventInput) StepUpService {
+		return NewStepUpService(store, &mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) {
+			*events = append(*events, in)
+		}}, 5*time.Minute)
+	}
+
+	t.Run("success lets the credential pass step-up", func(t *testing.T) {
+		var events []AuthEventInput
+		store := &memStepUpStore{}
+		svc := build(store, &events)
+		user := newUser()
+
+		res, err := svc.Reauthenticate(context.Background(), user, 1, "sid:s1", "Secret-123")
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Minute, res.ExpiresAt.Sub(res.ReauthenticatedAt))
+		require.Len(t, events, 1)
+		assert.Equal(t, model.AuthEventTypeReauthenticate, events[0].EventType)
+		assert.Equal(t, model.AuthEventResultSuccess, events[0].Result)
+
+		auth := &middleware.AuthContext{User: user, Tenant: &model.Tenant{TenantID: 1}}
+		ok, err := svc.VerifyStepUp(context.Background(), auth, "sid:s1", "DELETE /api/v1/clients/x")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, model.AuthEventTypeStepUpGranted, events[1].EventType)
+		assert.Contains(t, string(events[1].Metadata), "DELETE /api/v1/clients/x")
+
+		ok, err = svc.VerifyStepUp(context.Background(), auth, "sid:s2", "DELETE /api/v1/clients/x")
+		require.NoError(t, err)
+		assert.False(t, ok, "another session of the user")
+
+		other := &middleware.AuthContext{User: newUser()}
+		ok, err = svc.VerifyStepUp(context.Background(), other, "sid:s1", "DELETE /api/v1/clients/x")
+		require.NoError(t, err)
+		assert.False(t, ok, "another user")
+	})
+
+	t.Run("wrong password is recorded", func(t *testing.T) {
+		var events []AuthEventInput
+		store := &memStepUpStore{}
+		svc := build(store, &events)
+
+		_, err := svc.Reauthenticate(context.Background(), newUser(), 1, "sid:s1", "wrong")
+		var validation *apperror.ValidationError
+		require.True(t, errors.As(err, &validation))
+		assert.Empty(t, store.at)
+		require.Len(t, events, 1)
+		assert.Equal(t, model.AuthEventTypeReauthenticateFail, events[0].EventType)
+		assert.Equal(t, model.AuthEventResultFailure, events[0].Result)
+	})
+
+	t.Run("no password", func(t *testing.T) {
+		var events []AuthEventInput
+		user := newUser()
+		user.Password = nil
+		_, err := build(&memStepUpStore{}, &events).Reauthenticate(context.Background(), user, 1, "sid:s1", "Secret-123")
+		var forbidden *apperror.ForbiddenError
+		assert.True(t, errors.As(err, &forbidden))
+	})
+
+	t.Run("no credential", func(t *testing.T) {
+		var events []AuthEventInput
+		_, err := build(&memStepUpStore{}, &events).Reauthenticate(context.Background(), newUser(), 1, "", "Secret-123")
+		var validation *apperror.ValidationError
+		assert.True(t, errors.As(err, &validation))
+	})
+
+	t.Run("store failure", func(t *testing.T) {
+		var events []AuthEventInput
+		_, err := build(&memStepUpStore{err: errors.New("redis down")}, &events).Reauthenticate(context.Background(), newUser(), 1, "sid:s1", "Secret-123")
+		var internal *apperror.InternalError
+		assert.True(t, errors.As(err, &internal))
+	})
+}
+
+func TestStepUpService_VerifyStepUp(t *testing.T) {
+	user := &model.User{UserID: 7, UserUUID: uuid.New(), Username: "admin"}
+	auth := &middleware.AuthContext{User: user, Tenant: &model.Tenant{TenantID: 1}}
+
+	t.Run("stale re-authentication is challenged", func(t *testing.T) {
+		var events []AuthEventInput
+		store := &memStepUpStore{at: map[string]time.Time{stepUpKey(user, "jti:j"): time.Now().Add(-6 * time.Minute)}}
+		svc := NewStepUpService(store, &mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) {
+			events = append(events, in)
+		}}, 5*time.Minute)
+
+		ok, err := svc.VerifyStepUp(context.Background(), auth, "jti:j", "POST /api/v1/admin/signing-keys/rotate")
+		require.NoError(t, err)
+		assert.False(t, ok)
+		require.Len(t, events, 1)
+		assert.Equal(t, model.AuthEventTypeStepUpRequired, events[0].EventType)
+		assert.Equal(t, model.AuthEventResultFailure, events[0].Result)
+		assert.Equal(t, int64(1), events[0].TenantID)
+	})
+
+	t.Run("credential without identifier", func(t *testing.T) {
+		svc := NewStepUpService(&memStepUpStore{}, &mockAuthEventService{}, 5*time.Minute)
+		ok, err := svc.VerifyStepUp(context.Background(), auth, "", "DELETE /x")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("lookup failure", func(t *testing.T) {
+		svc := NewStepUpService(&memStepUpStore{err: errors.New("redis down")}, &mockAuthEventService{}, 5*time.Minute)
+		_, err := svc.VerifyStepUp(context.Background(), auth, "jti:j", "DELETE /x")
+		assert.Error(t, err)
+	})
+}
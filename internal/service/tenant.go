@@ -25,8 +25,10 @@ type TenantServiceDataResult struct {
 	Status      string
 	IsPublic    bool
 	IsSystem    bool
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	// ParentTenantUUID is nil for top-level tenants.
+	ParentTenantUUID *uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
 }
 
 type TenantServiceGetFilter struct {
@@ -38,10 +40,17 @@ type TenantServiceGetFilter struct {
 	Status      []string
 	IsPublic    *bool
 	IsSystem    *bool
-	Page        int
-	Limit       int
-	SortBy      string
-	SortOrder   string
+	// TenantIDs restricts the results to these tenants when not nil.
+	// IncludeDescendants widens it to the tenants below them too.
+	TenantIDs          []int64
+	IncludeDescendants bool
+	// ParentTenantID restricts the results to the direct children of this
+	// tenant when not nil.
+	ParentTenantID *int64
+	Page           int
+	Limit          int
+	SortBy         string
+	SortOrder      string
 }
 
 type TenantServiceGetResult struct {
@@ -57,22 +66,27 @@ type TenantService interface {
 	GetByUUID(ctx context.Context, tenantUUID uuid.UUID) (*TenantServiceDataResult, error)
 	GetSystem(ctx context.Context) (*TenantServiceDataResult, error)
 	GetByIdentifier(ctx context.Context, identifier string) (*TenantServiceDataResult, error)
-	Create(ctx context.Context, name string, displayName string, description string, status string, isPublic bool) (*TenantServiceDataResult, error)
+	GetAncestorIDs(ctx context.Context, tenantUUID uuid.UUID) ([]int64, error)
+	Create(ctx context.Context, name string, displayName string, description string, status string, isPublic bool, parentTenantUUID *uuid.UUID) (*TenantServiceDataResult, error)
 	Update(ctx context.Context, tenantUUID uuid.UUID, name string, displayName string, description string, status string, isPublic bool) (*TenantServiceDataResult, error)
+	SetParentByUUID(ctx context.Context, tenantUUID uuid.UUID, parentTenantUUID *uuid.UUID) (*TenantServiceDataResult, error)
 	SetStatusByUUID(ctx context.Context, tenantUUID uuid.UUID, status string) (*TenantServiceDataResult, error)
 	SetActivePublicByUUID(ctx context.Context, tenantUUID uuid.UUID) (*TenantServiceDataResult, error)
 	DeleteByUUID(ctx context.Context, tenantUUID uuid.UUID) (*TenantServiceDataResult, error)
+	PreviewDeleteByUUID(ctx context.Context, tenantUUID uuid.UUID) (*DeleteImpactReport, error)
 }
 
 type tenantService struct {
-	db         *gorm.DB
-	tenantRepo repository.TenantRepository
+	db          *gorm.DB
+	tenantRepo  repository.TenantRepository
+	provisioner TenantProvisioner
 }
 
-func NewTenantService(db *gorm.DB, tenantRepo repository.TenantRepository) TenantService {
+func NewTenantService(db *gorm.DB, tenantRepo repository.TenantRepository, provisioner TenantProvisioner) TenantService {
 	return &tenantService{
-		db:         db,
-		tenantRepo: tenantRepo,
+		db:          db,
+		tenantRepo:  tenantRepo,
+		provisioner: provisioner,
 	}
 }
 
@@ -81,17 +95,20 @@ func (s *tenantService) Get(ctx context.Context, filter TenantServiceGetFilter)
 	defer span.End()
 
 	tenantFilter := repository.TenantRepositoryGetFilter{
-		Name:        filter.Name,
-		DisplayName: filter.DisplayName,
-		Description: filter.Description,
-		Identifier:  filter.Identifier,
-		Status:      filter.Status,
-		IsPublic:    filter.IsPublic,
-		IsSystem:    filter.IsSystem,
-		Page:        filter.Page,
-		Limit:       filter.Limit,
-		SortBy:      filter.SortBy,
-		SortOrder:   filter.SortOrder,
+		Name:               filter.Name,
+		DisplayName:        filter.DisplayName,
+		Description:        filter.Description,
+		Identifier:         filter.Identifier,
+		Status:             filter.Status,
+		IsPublic:           filter.IsPublic,
+		IsSystem:           filter.IsSystem,
+		TenantIDs:          filter.TenantIDs,
+		IncludeDescendants: filter.IncludeDescendants,
+		ParentTenantID:     filter.ParentTenantID,
+		Page:               filter.Page,
+		Limit:              filter.Limit,
+		SortBy:             filter.SortBy,
+		SortOrder:          filter.SortOrder,
 	}
 
 	result, err := s.tenantRepo.FindPaginated(tenantFilter)
@@ -121,13 +138,13 @@ func (s *tenantService) GetByUUID(ctx context.Context, tenantUUID uuid.UUID) (*T
 	defer span.End()
 	span.SetAttributes(attribute.String("tenant.uuid", tenantUUID.String()))
 
-	tenant, err := s.tenantRepo.FindByUUID(tenantUUID)
+	tenant, err := s.tenantRepo.FindByUUID(tenantUUID, "ParentTenant")
 	if err != nil || tenant == nil {
 		if err != nil {
 			span.RecordError(err)
 		}
 		span.SetStatus(codes.Error, "tenant not found")
-		return nil, apperror.NewNotFound("tenant not found")
+		return nil, apperror.WithCode(apperror.CodeTenantNotFound, apperror.NewNotFound("tenant"))
 	}
 
 	span.SetStatus(codes.Ok, "")
@@ -164,14 +181,35 @@ func (s *tenantService) GetByIdentifier(ctx context.Context, identifier string)
 			span.RecordError(err)
 		}
 		span.SetStatus(codes.Error, "tenant not found")
-		return nil, apperror.NewNotFound("tenant not found")
+		return nil, apperror.WithCode(apperror.CodeTenantNotFound, apperror.NewNotFound("tenant"))
 	}
 
 	span.SetStatus(codes.Ok, "")
 	return toTenantServiceDataResult(tenant), nil
 }
 
-func (s *tenantService) Create(ctx context.Context, name string, displayName string, description string, status string, isPublic bool) (*TenantServiceDataResult, error) {
+// GetAncestorIDs returns the IDs of the tenants above the tenant, root first.
+func (s *tenantService) GetAncestorIDs(ctx context.Context, tenantUUID uuid.UUID) ([]int64, error) {
+	_, span := otel.Tracer("service").Start(ctx, "tenant.getAncestorIDs")
+	defer span.End()
+	span.SetAttributes(attribute.String("tenant.uuid", tenantUUID.String()))
+
+	tenant, err := s.tenantRepo.FindByUUID(tenantUUID)
+	if err != nil || tenant == nil {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.SetStatus(codes.Error, "tenant not found")
+		return nil, apperror.WithCode(apperror.CodeTenantNotFound, apperror.NewNotFound("tenant"))
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return tenant.AncestorIDs(), nil
+}
+
+// Create creates a tenant and seeds its default resources. The tenant is
+// placed under the tenant with parentTenantUUID when it is not nil.
+func (s *tenantService) Create(ctx context.Context, name string, displayName string, description string, status string, isPublic bool, parentTenantUUID *uuid.UUID) (*TenantServiceDataResult, error) {
 	_, span := otel.Tracer("service").Start(ctx, "tenant.create")
 	defer span.End()
 	span.SetAttributes(attribute.String("tenant.name", name))
@@ -190,6 +228,18 @@ func (s *tenantService) Create(ctx context.Context, name string, displayName str
 			return apperror.NewConflict(name + " tenant already exists")
 		}
 
+		// Resolve the parent tenant
+		var parent *model.Tenant
+		if parentTenantUUID != nil {
+			parent, err = txTenantRepo.FindByUUID(*parentTenantUUID)
+			if err != nil {
+				return err
+			}
+			if parent == nil {
+				return apperror.WithCode(apperror.CodeTenantNotFound, apperror.NewNotFoundWithReason("parent tenant not found"))
+			}
+		}
+
 		// Generate identifier
 		identifier, err := crypto.GenerateIdentifier(12)
 		if err != nil {
@@ -205,14 +255,23 @@ func (s *tenantService) Create(ctx context.Context, name string, displayName str
 			Status:      status,
 			IsPublic:    isPublic,
 		}
+		if parent != nil {
+			newTenant.ParentTenantID = &parent.TenantID
+			newTenant.AncestorPath = parent.ChildAncestorPath()
+		}
 
 		_, err = txTenantRepo.CreateOrUpdate(newTenant)
 		if err != nil {
 			return err
 		}
 
+		// Seed the tenant's default resources
+		if err := s.provisioner.Provision(ctx, tx, newTenant.TenantID); err != nil {
+			return err
+		}
+
 		// Fetch Tenant with relationships preloaded
-		createdTenant, err = txTenantRepo.FindByUUID(newTenant.TenantUUID)
+		createdTenant, err = txTenantRepo.FindByUUID(newTenant.TenantUUID, "ParentTenant")
 		if err != nil {
 			return err
 		}
@@ -240,13 +299,17 @@ func (s *tenantService) Update(ctx context.Context, tenantUUID uuid.UUID, name s
 	err := s.db.Transaction(func(tx *gorm.DB) error {
 		txTenantRepo := s.tenantRepo.WithTx(tx)
 
-		// Find existing tenant
-		tenant, err := txTenantRepo.FindByUUID(tenantUUID)
+		// Find existing tenant, locked so the If-Match check below holds until commit
+		tenant, err := s.tenantRepo.WithTx(lockForUpdate(tx)).FindByUUID(tenantUUID)
 		if err != nil {
 			return err
 		}
 		if tenant == nil {
-			return apperror.NewNotFound("tenant not found")
+			return apperror.WithCode(apperror.CodeTenantNotFound, apperror.NewNotFound("tenant"))
+		}
+
+		if err := checkIfMatch(ctx, "tenant", tenant.UpdatedAt); err != nil {
+			return err
 		}
 
 		// Check if tenant name is taken by another tenant
@@ -285,6 +348,65 @@ func (s *tenantService) Update(ctx context.Context, tenantUUID uuid.UUID, name s
 	return toTenantServiceDataResult(updatedTenant), nil
 }
 
+// SetParentByUUID moves the tenant, with every tenant below it, under the
+// tenant with parentTenantUUID, or to the top level when it is nil. System
+// tenants cannot be moved and a tenant cannot be moved below itself.
+func (s *tenantService) SetParentByUUID(ctx context.Context, tenantUUID uuid.UUID, parentTenantUUID *uuid.UUID) (*TenantServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "tenant.setParent")
+	defer span.End()
+	span.SetAttributes(attribute.String("tenant.uuid", tenantUUID.String()))
+
+	var movedTenant *model.Tenant
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txTenantRepo := s.tenantRepo.WithTx(tx)
+
+		tenant, err := txTenantRepo.FindByUUID(tenantUUID)
+		if err != nil {
+			return err
+		}
+		if tenant == nil {
+			return apperror.WithCode(apperror.CodeTenantNotFound, apperror.NewNotFound("tenant"))
+		}
+		if tenant.IsSystem {
+			return apperror.WithCode(apperror.CodeSystemTenantProtected, apperror.NewValidation("cannot move system tenant"))
+		}
+
+		var parentTenantID *int64
+		ancestorPath := "/"
+		if parentTenantUUID != nil {
+			parent, err := txTenantRepo.FindByUUID(*parentTenantUUID)
+			if err != nil {
+				return err
+			}
+			if parent == nil {
+				return apperror.WithCode(apperror.CodeTenantNotFound, apperror.NewNotFoundWithReason("parent tenant not found"))
+			}
+			if parent.TenantID == tenant.TenantID || parent.HasAncestor(tenant.TenantID) {
+				return apperror.WithCode(apperror.CodeTenantHierarchyCycle, apperror.NewValidation("tenant cannot be moved below itself"))
+			}
+			parentTenantID = &parent.TenantID
+			ancestorPath = parent.ChildAncestorPath()
+		}
+
+		if err := txTenantRepo.MoveSubtree(tenant, parentTenantID, ancestorPath); err != nil {
+			return err
+		}
+
+		movedTenant, err = txTenantRepo.FindByUUID(tenantUUID, "ParentTenant")
+		return err
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "set tenant parent failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toTenantServiceDataResult(movedTenant), nil
+}
+
 func (s *tenantService) SetStatusByUUID(ctx context.Context, tenantUUID uuid.UUID, status string) (*TenantServiceDataResult, error) {
 	_, span := otel.Tracer("service").Start(ctx, "tenant.setStatus")
 	defer span.End()
@@ -296,7 +418,7 @@ func (s *tenantService) SetStatusByUUID(ctx context.Context, tenantUUID uuid.UUI
 			span.RecordError(err)
 		}
 		span.SetStatus(codes.Error, "tenant not found")
-		return nil, apperror.NewNotFound("tenant not found")
+		return nil, apperror.WithCode(apperror.CodeTenantNotFound, apperror.NewNotFound("tenant"))
 	}
 
 	err = s.tenantRepo.SetStatusByUUID(tenantUUID, status)
@@ -329,7 +451,7 @@ func (s *tenantService) SetActivePublicByUUID(ctx context.Context, tenantUUID uu
 			span.RecordError(err)
 		}
 		span.SetStatus(codes.Error, "tenant not found")
-		return nil, apperror.NewNotFound("tenant not found")
+		return nil, apperror.WithCode(apperror.CodeTenantNotFound, apperror.NewNotFound("tenant"))
 	}
 
 	// Toggle public status
@@ -357,24 +479,18 @@ func (s *tenantService) DeleteByUUID(ctx context.Context, tenantUUID uuid.UUID)
 	defer span.End()
 	span.SetAttributes(attribute.String("tenant.uuid", tenantUUID.String()))
 
-	tenant, err := s.tenantRepo.FindByUUID(tenantUUID)
-	if err != nil || tenant == nil {
-		if err != nil {
-			span.RecordError(err)
-		}
-		span.SetStatus(codes.Error, "tenant not found")
-		return nil, apperror.NewNotFound("tenant not found")
-	}
-
-	// Prevent deletion of system tenants
-	if tenant.IsSystem {
-		span.SetStatus(codes.Error, "cannot delete system tenant")
-		return nil, apperror.NewValidation("cannot delete system tenant")
+	tenant, err := s.findDeletableTenant(tenantUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete tenant failed")
+		return nil, err
 	}
 
 	result := toTenantServiceDataResult(tenant)
 
-	err = s.tenantRepo.DeleteByUUID(tenantUUID)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return s.provisioner.Deprovision(ctx, tx, tenant)
+	})
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "delete tenant failed")
@@ -385,8 +501,68 @@ func (s *tenantService) DeleteByUUID(ctx context.Context, tenantUUID uuid.UUID)
 	return result, nil
 }
 
+// PreviewDeleteByUUID reports what DeleteByUUID would remove, running the
+// delete in a transaction that is rolled back.
+func (s *tenantService) PreviewDeleteByUUID(ctx context.Context, tenantUUID uuid.UUID) (*DeleteImpactReport, error) {
+	_, span := otel.Tracer("service").Start(ctx, "tenant.previewDelete")
+	defer span.End()
+	span.SetAttributes(attribute.String("tenant.uuid", tenantUUID.String()))
+
+	tenant, err := s.findDeletableTenant(tenantUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "preview tenant delete failed")
+		return nil, err
+	}
+
+	report, err := previewDelete(s.db, hardDeleteImpact(model.Tenant{}.TableName(), "tenant_id", tenant.TenantID), nil, func(tx *gorm.DB) error {
+		return s.provisioner.Deprovision(ctx, tx, tenant)
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "preview tenant delete failed")
+		return nil, apperror.NewInternal("failed to preview tenant delete", err)
+	}
+
+	span.SetAttributes(attribute.Bool("dry_run.would_succeed", report.WouldSucceed))
+	span.SetStatus(codes.Ok, "")
+	return report, nil
+}
+
+// findDeletableTenant loads a tenant and refuses system tenants and tenants
+// that still have child tenants.
+func (s *tenantService) findDeletableTenant(tenantUUID uuid.UUID) (*model.Tenant, error) {
+	tenant, err := s.tenantRepo.FindByUUID(tenantUUID)
+	if err != nil || tenant == nil {
+		return nil, apperror.WithCode(apperror.CodeTenantNotFound, apperror.NewNotFound("tenant"))
+	}
+
+	// Prevent deletion of system tenants
+	if tenant.IsSystem {
+		return nil, apperror.WithCode(apperror.CodeSystemTenantProtected, apperror.NewValidation("cannot delete system tenant"))
+	}
+
+	if err := ensureNoChildTenants(s.tenantRepo, tenant.TenantID); err != nil {
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// ensureNoChildTenants refuses to remove a tenant that still has child
+// tenants; they have to be moved or deleted first.
+func ensureNoChildTenants(tenantRepo repository.TenantRepository, tenantID int64) error {
+	children, err := tenantRepo.CountChildren(tenantID)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return apperror.WithCode(apperror.CodeTenantHasChildren, apperror.NewConflict("tenant has child tenants"))
+	}
+	return nil
+}
+
 func toTenantServiceDataResult(tenant *model.Tenant) *TenantServiceDataResult {
-	return &TenantServiceDataResult{
+	result := &TenantServiceDataResult{
 		TenantID:    tenant.TenantID,
 		TenantUUID:  tenant.TenantUUID,
 		Name:        tenant.Name,
@@ -399,4 +575,8 @@ func toTenantServiceDataResult(tenant *model.Tenant) *TenantServiceDataResult {
 		CreatedAt:   tenant.CreatedAt,
 		UpdatedAt:   tenant.UpdatedAt,
 	}
+	if tenant.ParentTenant != nil {
+		result.ParentTenantUUID = &tenant.ParentTenant.TenantUUID
+	}
+	return result
 }
@@ -8,7 +8,7 @@ import (
 // ValidateTenantAccess validates if a user can access the target tenant
 // Rules:
 // - Users from default tenant can access any tenant
-// - Users from non-default tenant can only access their own tenant
+// - Users from non-default tenant can access their own tenant and the tenants below it
 // - User must have at least one identity to validate access
 func ValidateTenantAccess(actorUser *model.User, targetTenant *model.Tenant) error {
 	// User must have at least one identity
@@ -27,8 +27,8 @@ func ValidateTenantAccess(actorUser *model.User, targetTenant *model.Tenant) err
 			break
 		}
 
-		// Check if user has identity in the target tenant
-		if identity.TenantID == targetTenant.TenantID {
+		// Check if user has identity in the target tenant or above it
+		if identity.TenantID == targetTenant.TenantID || targetTenant.HasAncestor(identity.TenantID) {
 			hasAccessToTargetTenant = true
 		}
 	}
@@ -46,7 +46,9 @@ func ValidateTenantAccess(actorUser *model.User, targetTenant *model.Tenant) err
 	return apperror.NewForbidden("access denied: user does not have access to this tenant")
 }
 
-// ValidateTenantAccessByID validates tenant access using tenant ID
+// ValidateTenantAccessByID validates tenant access using tenant ID. It cannot
+// see the tenant hierarchy; prefer ValidateTenantAccess when the tenant is
+// loaded.
 // Rules:
 // - Users from default tenant can access any tenant
 // - Users from non-default tenant can only access their own tenant
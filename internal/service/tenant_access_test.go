@@ -81,6 +81,23 @@ func TestValidateTenantAccess(t *testing.T) {
 			target:      buildTenant(10, false),
 			expectError: false,
 		},
+		{
+			name: "user from an ancestor tenant → allowed",
+			user: buildUserWithIdentities([]model.UserIdentity{
+				buildIdentity(20, false),
+			}),
+			target:      &model.Tenant{TenantID: 10, AncestorPath: "/20/30/"},
+			expectError: false,
+		},
+		{
+			name: "user from a descendant tenant → denied",
+			user: buildUserWithIdentities([]model.UserIdentity{
+				buildIdentity(10, false),
+			}),
+			target:      &model.Tenant{TenantID: 20, AncestorPath: "/"},
+			expectError: true,
+			errContains: "access denied",
+		},
 	}
 
 	for _, tc := range cases {
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -31,8 +32,8 @@ type TenantMemberService interface {
 	GetByTenantAndUser(ctx context.Context, tenantID int64, userID int64) (*TenantMemberServiceDataResult, error)
 	ListByTenant(ctx context.Context, tenantID int64) ([]TenantMemberServiceDataResult, error)
 	ListByUser(ctx context.Context, userID int64) ([]TenantMemberServiceDataResult, error)
-	UpdateRole(ctx context.Context, tenantMemberUUID uuid.UUID, role string) (*TenantMemberServiceDataResult, error)
-	DeleteByUUID(ctx context.Context, tenantMemberUUID uuid.UUID) error
+	UpdateRole(ctx context.Context, tenantID int64, tenantMemberUUID uuid.UUID, role string) (*TenantMemberServiceDataResult, error)
+	DeleteByUUID(ctx context.Context, tenantID int64, tenantMemberUUID uuid.UUID) error
 	IsUserInTenant(ctx context.Context, userID int64, tenantUUID uuid.UUID) (bool, error)
 }
 
@@ -196,15 +197,15 @@ func (s *tenantMemberService) ListByUser(ctx context.Context, userID int64) ([]T
 	return result, nil
 }
 
-func (s *tenantMemberService) UpdateRole(ctx context.Context, tenantMemberUUID uuid.UUID, role string) (*TenantMemberServiceDataResult, error) {
+func (s *tenantMemberService) UpdateRole(ctx context.Context, tenantID int64, tenantMemberUUID uuid.UUID, role string) (*TenantMemberServiceDataResult, error) {
 	_, span := otel.Tracer("service").Start(ctx, "tenantMember.updateRole")
 	defer span.End()
-	span.SetAttributes(attribute.String("tenantMember.uuid", tenantMemberUUID.String()))
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID), attribute.String("tenantMember.uuid", tenantMemberUUID.String()))
 
 	var updated *model.TenantMember
 	err := s.db.Transaction(func(tx *gorm.DB) error {
 		repo := s.tenantMemberRepo.WithTx(tx)
-		tu, err := repo.FindByTenantMemberUUID(tenantMemberUUID)
+		tu, err := repo.FindByUUIDAndTenantID(tenantMemberUUID, tenantID)
 		if err != nil {
 			return err
 		}
@@ -233,14 +234,14 @@ func (s *tenantMemberService) UpdateRole(ctx context.Context, tenantMemberUUID u
 	return result, nil
 }
 
-func (s *tenantMemberService) DeleteByUUID(ctx context.Context, tenantMemberUUID uuid.UUID) error {
+func (s *tenantMemberService) DeleteByUUID(ctx context.Context, tenantID int64, tenantMemberUUID uuid.UUID) error {
 	_, span := otel.Tracer("service").Start(ctx, "tenantMember.delete")
 	defer span.End()
-	span.SetAttributes(attribute.String("tenantMember.uuid", tenantMemberUUID.String()))
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID), attribute.String("tenantMember.uuid", tenantMemberUUID.String()))
 
 	err := s.db.Transaction(func(tx *gorm.DB) error {
 		repo := s.tenantMemberRepo.WithTx(tx)
-		tu, err := repo.FindByTenantMemberUUID(tenantMemberUUID)
+		tu, err := repo.FindByUUIDAndTenantID(tenantMemberUUID, tenantID)
 		if err != nil {
 			return err
 		}
@@ -258,7 +259,8 @@ func (s *tenantMemberService) DeleteByUUID(ctx context.Context, tenantMemberUUID
 	return nil
 }
 
-// IsUserInTenant checks if a user is a member of the specified tenant
+// IsUserInTenant checks if a user is a member of the specified tenant or of a
+// tenant above it
 func (s *tenantMemberService) IsUserInTenant(ctx context.Context, userID int64, tenantUUID uuid.UUID) (bool, error) {
 	_, span := otel.Tracer("service").Start(ctx, "tenantMember.isUserInTenant")
 	defer span.End()
@@ -274,16 +276,25 @@ func (s *tenantMemberService) IsUserInTenant(ctx context.Context, userID int64,
 		return false, apperror.NewNotFound("tenant not found")
 	}
 
-	// Check if user is in tenant_members
-	tenantMember, err := s.tenantMemberRepo.FindByTenantAndUser(tenant.TenantID, userID)
-	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, "check user in tenant failed")
-		return false, err
+	// Check if user is in tenant_members, nearest tenant first
+	tenantIDs := tenant.AncestorIDs()
+	slices.Reverse(tenantIDs)
+	tenantIDs = append([]int64{tenant.TenantID}, tenantIDs...)
+	for _, tenantID := range tenantIDs {
+		tenantMember, err := s.tenantMemberRepo.FindByTenantAndUser(tenantID, userID)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "check user in tenant failed")
+			return false, err
+		}
+		if tenantMember != nil {
+			span.SetStatus(codes.Ok, "")
+			return true, nil
+		}
 	}
 
 	span.SetStatus(codes.Ok, "")
-	return tenantMember != nil, nil
+	return false, nil
 }
 
 func toTenantMemberServiceDataResult(tu *model.TenantMember) *TenantMemberServiceDataResult {
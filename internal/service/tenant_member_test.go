@@ -120,9 +120,9 @@ func TestTenantMemberService_DeleteByUUID(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectRollback()
 		svc := NewTenantMemberService(db, &mockTenantMemberRepo{
-			findByTenantMemberUUIDFn: func(_ uuid.UUID) (*model.TenantMember, error) { return nil, nil },
+			findByUUIDAndTenantIDFn: func(_ uuid.UUID, _ int64) (*model.TenantMember, error) { return nil, nil },
 		}, &mockUserRepo{}, &mockTenantRepo{})
-		err := svc.DeleteByUUID(context.Background(), id)
+		err := svc.DeleteByUUID(context.Background(), 1, id)
 		require.Error(t, err)
 	})
 
@@ -131,11 +131,11 @@ func TestTenantMemberService_DeleteByUUID(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectCommit()
 		svc := NewTenantMemberService(db, &mockTenantMemberRepo{
-			findByTenantMemberUUIDFn: func(i uuid.UUID) (*model.TenantMember, error) {
+			findByUUIDAndTenantIDFn: func(i uuid.UUID, _ int64) (*model.TenantMember, error) {
 				return &model.TenantMember{TenantMemberUUID: i}, nil
 			},
 		}, &mockUserRepo{}, &mockTenantRepo{})
-		err := svc.DeleteByUUID(context.Background(), id)
+		err := svc.DeleteByUUID(context.Background(), 1, id)
 		require.NoError(t, err)
 	})
 }
@@ -198,6 +198,28 @@ func TestTenantMemberService_IsUserInTenant(t *testing.T) {
 		require.Error(t, err)
 		assert.False(t, ok)
 	})
+
+	t.Run("user is member of an ancestor tenant", func(t *testing.T) {
+		db, _ := newMockGormDB(t)
+		var checked []int64
+		svc := NewTenantMemberService(db, &mockTenantMemberRepo{
+			findByTenantAndUserFn: func(tenantID int64, _ int64) (*model.TenantMember, error) {
+				checked = append(checked, tenantID)
+				if tenantID == 2 {
+					return &model.TenantMember{}, nil
+				}
+				return nil, nil
+			},
+		}, &mockUserRepo{}, &mockTenantRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Tenant, error) {
+				return &model.Tenant{TenantID: 10, AncestorPath: "/2/5/"}, nil
+			},
+		})
+		ok, err := svc.IsUserInTenant(context.Background(), 1, tenantUUID)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []int64{10, 5, 2}, checked, "nearest tenant first")
+	})
 }
 
 // ---------------------------------------------------------------------------
@@ -407,9 +429,9 @@ func TestTenantMemberService_UpdateRole(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectRollback()
 		svc := NewTenantMemberService(db, &mockTenantMemberRepo{
-			findByTenantMemberUUIDFn: func(_ uuid.UUID) (*model.TenantMember, error) { return nil, nil },
+			findByUUIDAndTenantIDFn: func(_ uuid.UUID, _ int64) (*model.TenantMember, error) { return nil, nil },
 		}, &mockUserRepo{}, &mockTenantRepo{})
-		_, err := svc.UpdateRole(context.Background(), tmUUID, "admin")
+		_, err := svc.UpdateRole(context.Background(), 1, tmUUID, "admin")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "not found")
 	})
@@ -419,11 +441,11 @@ func TestTenantMemberService_UpdateRole(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectRollback()
 		svc := NewTenantMemberService(db, &mockTenantMemberRepo{
-			findByTenantMemberUUIDFn: func(_ uuid.UUID) (*model.TenantMember, error) {
+			findByUUIDAndTenantIDFn: func(_ uuid.UUID, _ int64) (*model.TenantMember, error) {
 				return nil, errors.New("find error")
 			},
 		}, &mockUserRepo{}, &mockTenantRepo{})
-		_, err := svc.UpdateRole(context.Background(), tmUUID, "admin")
+		_, err := svc.UpdateRole(context.Background(), 1, tmUUID, "admin")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "find error")
 	})
@@ -433,14 +455,14 @@ func TestTenantMemberService_UpdateRole(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectRollback()
 		svc := NewTenantMemberService(db, &mockTenantMemberRepo{
-			findByTenantMemberUUIDFn: func(id uuid.UUID) (*model.TenantMember, error) {
+			findByUUIDAndTenantIDFn: func(id uuid.UUID, _ int64) (*model.TenantMember, error) {
 				return &model.TenantMember{TenantMemberUUID: id, UserID: 5, Role: "member"}, nil
 			},
 			createOrUpdateFn: func(_ *model.TenantMember) (*model.TenantMember, error) {
 				return nil, errors.New("update error")
 			},
 		}, &mockUserRepo{}, &mockTenantRepo{})
-		_, err := svc.UpdateRole(context.Background(), tmUUID, "admin")
+		_, err := svc.UpdateRole(context.Background(), 1, tmUUID, "admin")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "update error")
 	})
@@ -450,7 +472,7 @@ func TestTenantMemberService_UpdateRole(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectCommit()
 		svc := NewTenantMemberService(db, &mockTenantMemberRepo{
-			findByTenantMemberUUIDFn: func(id uuid.UUID) (*model.TenantMember, error) {
+			findByUUIDAndTenantIDFn: func(id uuid.UUID, _ int64) (*model.TenantMember, error) {
 				return &model.TenantMember{TenantMemberUUID: id, UserID: 5, Role: "member"}, nil
 			},
 		}, &mockUserRepo{
@@ -458,7 +480,7 @@ func TestTenantMemberService_UpdateRole(t *testing.T) {
 				return &model.User{UserID: 5, Email: "test@test.com"}, nil
 			},
 		}, &mockTenantRepo{})
-		res, err := svc.UpdateRole(context.Background(), tmUUID, "admin")
+		res, err := svc.UpdateRole(context.Background(), 1, tmUUID, "admin")
 		require.NoError(t, err)
 		assert.Equal(t, "admin", res.Role)
 		require.NotNil(t, res.User)
@@ -470,7 +492,7 @@ func TestTenantMemberService_UpdateRole(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectCommit()
 		svc := NewTenantMemberService(db, &mockTenantMemberRepo{
-			findByTenantMemberUUIDFn: func(id uuid.UUID) (*model.TenantMember, error) {
+			findByUUIDAndTenantIDFn: func(id uuid.UUID, _ int64) (*model.TenantMember, error) {
 				return &model.TenantMember{TenantMemberUUID: id, UserID: 5, Role: "member"}, nil
 			},
 		}, &mockUserRepo{
@@ -478,7 +500,7 @@ func TestTenantMemberService_UpdateRole(t *testing.T) {
 				return nil, errors.New("user gone")
 			},
 		}, &mockTenantRepo{})
-		res, err := svc.UpdateRole(context.Background(), tmUUID, "admin")
+		res, err := svc.UpdateRole(context.Background(), 1, tmUUID, "admin")
 		require.NoError(t, err)
 		assert.Equal(t, "admin", res.Role)
 		assert.Nil(t, res.User)
@@ -497,11 +519,11 @@ func TestTenantMemberService_DeleteByUUID_Extra(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectRollback()
 		svc := NewTenantMemberService(db, &mockTenantMemberRepo{
-			findByTenantMemberUUIDFn: func(_ uuid.UUID) (*model.TenantMember, error) {
+			findByUUIDAndTenantIDFn: func(_ uuid.UUID, _ int64) (*model.TenantMember, error) {
 				return nil, errors.New("find error")
 			},
 		}, &mockUserRepo{}, &mockTenantRepo{})
-		err := svc.DeleteByUUID(context.Background(), id)
+		err := svc.DeleteByUUID(context.Background(), 1, id)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "find error")
 	})
@@ -511,12 +533,12 @@ func TestTenantMemberService_DeleteByUUID_Extra(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectRollback()
 		svc := NewTenantMemberService(db, &mockTenantMemberRepo{
-			findByTenantMemberUUIDFn: func(i uuid.UUID) (*model.TenantMember, error) {
+			findByUUIDAndTenantIDFn: func(i uuid.UUID, _ int64) (*model.TenantMember, error) {
 				return &model.TenantMember{TenantMemberUUID: i}, nil
 			},
 			deleteByUUIDFn: func(_ any) error { return errors.New("delete failed") },
 		}, &mockUserRepo{}, &mockTenantRepo{})
-		err := svc.DeleteByUUID(context.Background(), id)
+		err := svc.DeleteByUUID(context.Background(), 1, id)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "delete failed")
 	})
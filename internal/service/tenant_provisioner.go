@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/runner"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+// tenantDeprovisionTokenTTL is how long a deprovision confirmation token can
+// be used after it was issued.
+const tenantDeprovisionTokenTTL = 15 * time.Minute
+
+type TenantDeprovisionRequestResult struct {
+	DeprovisionUUID   uuid.UUID
+	TenantUUID        uuid.UUID
+	Mode              string
+	ConfirmationToken string
+	ExpiresAt         time.Time
+	// Impact is the dry run of the delete; nil when archiving.
+	Impact *DeleteImpactReport
+}
+
+type TenantDeprovisionResult struct {
+	DeprovisionUUID uuid.UUID
+	TenantUUID      uuid.UUID
+	Mode            string
+	CompletedAt     time.Time
+}
+
+// TenantProvisioner creates and tears down everything a tenant owns.
+type TenantProvisioner interface {
+	// Provision seeds a new tenant's default API, permissions, identity
+	// provider, clients, roles, email templates, user pool and security
+	// settings within tx.
+	Provision(ctx context.Context, tx *gorm.DB, tenantID int64) error
+	// Deprovision deletes the tenant within tx together with its user pools
+	// and the users that belong to no other tenant. Everything else the
+	// tenant owns cascades.
+	Deprovision(ctx context.Context, tx *gorm.DB, tenant *model.Tenant) error
+	// RequestDeprovision issues a one-time token, valid for 15 minutes, that
+	// ConfirmDeprovision needs to archive or delete the tenant. Earlier
+	// pending requests for the tenant stop being usable.
+	RequestDeprovision(ctx context.Context, tenantUUID uuid.UUID, userID int64, mode string) (*TenantDeprovisionRequestResult, error)
+	// ConfirmDeprovision archives or deletes the tenant as requested by the
+	// same user. Archiving keeps the data but makes the tenant private and
+	// disables its clients.
+	ConfirmDeprovision(ctx context.Context, tenantUUID uuid.UUID, userID int64, token string) (*TenantDeprovisionResult, error)
+}
+
+type tenantProvisioner struct {
+	db              *gorm.DB
+	tenantRepo      repository.TenantRepository
+	userRepo        repository.UserRepository
+	userPoolRepo    repository.UserPoolRepository
+	clientRepo      repository.ClientRepository
+	deprovisionRepo repository.TenantDeprovisionRepository
+}
+
+func NewTenantProvisioner(
+	db *gorm.DB,
+	tenantRepo repository.TenantRepository,
+	userRepo repository.UserRepository,
+	userPoolRepo repository.UserPoolRepository,
+	clientRepo repository.ClientRepository,
+	deprovisionRepo repository.TenantDeprovisionRepository,
+) TenantProvisioner {
+	return &tenantProvisioner{
+		db:              db,
+		tenantRepo:      tenantRepo,
+		userRepo:        userRepo,
+		userPoolRepo:    userPoolRepo,
+		clientRepo:      clientRepo,
+		deprovisionRepo: deprovisionRepo,
+	}
+}
+
+func (p *tenantProvisioner) Provision(ctx context.Context, tx *gorm.DB, tenantID int64) error {
+	if err := runner.ProvisionTenant(tx, tenantID, config.AppVersion); err != nil {
+		return apperror.NewInternal("failed to provision tenant", err)
+	}
+	return nil
+}
+
+func (p *tenantProvisioner) Deprovision(ctx context.Context, tx *gorm.DB, tenant *model.Tenant) error {
+	// Users are tied to tenants only through identities and memberships,
+	// which cascade, so remove the ones left without a tenant first
+	if _, err := p.userRepo.WithTx(tx).DeleteExclusiveToTenant(tenant.TenantID); err != nil {
+		return err
+	}
+
+	// User pools restrict tenant deletes
+	if err := p.userPoolRepo.WithTx(tx).DeleteByTenantID(tenant.TenantID); err != nil {
+		return err
+	}
+
+	return p.tenantRepo.WithTx(tx).DeleteByUUID(tenant.TenantUUID)
+}
+
+func (p *tenantProvisioner) RequestDeprovision(ctx context.Context, tenantUUID uuid.UUID, userID int64, mode string) (*TenantDeprovisionRequestResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "tenant_provisioner.requestDeprovision")
+	defer span.End()
+	span.SetAttributes(attribute.String("tenant.uuid", tenantUUID.String()), attribute.String("deprovision.mode", mode))
+
+	tenant, err := p.findDeprovisionableTenant(tenantUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "request tenant deprovision failed")
+		return nil, err
+	}
+
+	result := &TenantDeprovisionRequestResult{TenantUUID: tenant.TenantUUID, Mode: mode}
+
+	if mode == model.TenantDeprovisionModeDelete {
+		if err := ensureNoChildTenants(p.tenantRepo, tenant.TenantID); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "request tenant deprovision failed")
+			return nil, err
+		}
+		result.Impact, err = previewDelete(p.db, hardDeleteImpact(model.Tenant{}.TableName(), "tenant_id", tenant.TenantID), nil, func(tx *gorm.DB) error {
+			return p.Deprovision(ctx, tx, tenant)
+		})
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "request tenant deprovision failed")
+			return nil, apperror.NewInternal("failed to preview tenant delete", err)
+		}
+	}
+
+	token, err := crypto.GenerateRandomString(32)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "request tenant deprovision failed")
+		return nil, apperror.NewInternal("failed to generate confirmation token", err)
+	}
+
+	err = p.db.Transaction(func(tx *gorm.DB) error {
+		txDeprovisionRepo := p.deprovisionRepo.WithTx(tx)
+
+		if err := txDeprovisionRepo.SupersedePending(tenant.TenantID); err != nil {
+			return err
+		}
+
+		req, err := txDeprovisionRepo.Create(&model.TenantDeprovision{
+			TenantID:    &tenant.TenantID,
+			TenantUUID:  tenant.TenantUUID,
+			TenantName:  tenant.Name,
+			Mode:        mode,
+			TokenHash:   hashTenantDeprovisionToken(token),
+			Status:      model.TenantDeprovisionStatusPending,
+			RequestedBy: &userID,
+			ExpiresAt:   time.Now().Add(tenantDeprovisionTokenTTL),
+		})
+		if err != nil {
+			return err
+		}
+
+		result.DeprovisionUUID = req.TenantDeprovisionUUID
+		result.ExpiresAt = req.ExpiresAt
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "request tenant deprovision failed")
+		return nil, err
+	}
+
+	result.ConfirmationToken = token
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+func (p *tenantProvisioner) ConfirmDeprovision(ctx context.Context, tenantUUID uuid.UUID, userID int64, token string) (*TenantDeprovisionResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "tenant_provisioner.confirmDeprovision")
+	defer span.End()
+	span.SetAttributes(attribute.String("tenant.uuid", tenantUUID.String()))
+
+	tenant, err := p.findDeprovisionableTenant(tenantUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "confirm tenant deprovision failed")
+		return nil, err
+	}
+
+	var result *TenantDeprovisionResult
+
+	err = p.db.Transaction(func(tx *gorm.DB) error {
+		txDeprovisionRepo := p.deprovisionRepo.WithTx(tx)
+
+		req, err := txDeprovisionRepo.FindPendingByTokenHash(tenant.TenantID, hashTenantDeprovisionToken(token))
+		if err != nil {
+			return err
+		}
+		if req == nil {
+			return apperror.NewValidation("invalid confirmation token")
+		}
+		if req.RequestedBy == nil || *req.RequestedBy != userID {
+			return apperror.NewForbidden("deprovision was requested by another user")
+		}
+		if time.Now().After(req.ExpiresAt) {
+			return apperror.NewValidation("confirmation token has expired")
+		}
+
+		// Record completion first; deleting the tenant clears the reference
+		completedAt := time.Now()
+		if _, err := txDeprovisionRepo.UpdateByUUID(req.TenantDeprovisionUUID, map[string]any{
+			"status":       model.TenantDeprovisionStatusCompleted,
+			"completed_at": completedAt,
+		}); err != nil {
+			return err
+		}
+
+		switch req.Mode {
+		case model.TenantDeprovisionModeDelete:
+			if err = ensureNoChildTenants(p.tenantRepo.WithTx(tx), tenant.TenantID); err == nil {
+				err = p.Deprovision(ctx, tx, tenant)
+			}
+		default:
+			err = p.archive(tx, tenant)
+		}
+		if err != nil {
+			return err
+		}
+
+		result = &TenantDeprovisionResult{
+			DeprovisionUUID: req.TenantDeprovisionUUID,
+			TenantUUID:      tenant.TenantUUID,
+			Mode:            req.Mode,
+			CompletedAt:     completedAt,
+		}
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "confirm tenant deprovision failed")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("deprovision.mode", result.Mode))
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// archive makes the tenant private with the archived status and disables its
+// clients so nobody can sign in through it.
+func (p *tenantProvisioner) archive(tx *gorm.DB, tenant *model.Tenant) error {
+	if _, err := p.tenantRepo.WithTx(tx).UpdateByUUID(tenant.TenantUUID, map[string]any{
+		"status":    model.StatusArchived,
+		"is_public": false,
+	}); err != nil {
+		return err
+	}
+	return p.clientRepo.WithTx(tx).SetStatusByTenantID(tenant.TenantID, model.StatusInactive)
+}
+
+// findDeprovisionableTenant loads a tenant and refuses system tenants.
+func (p *tenantProvisioner) findDeprovisionableTenant(tenantUUID uuid.UUID) (*model.Tenant, error) {
+	tenant, err := p.tenantRepo.FindByUUID(tenantUUID)
+	if err != nil || tenant == nil {
+		return nil, apperror.WithCode(apperror.CodeTenantNotFound, apperror.NewNotFound("tenant"))
+	}
+	if tenant.IsSystem {
+		return nil, apperror.WithCode(apperror.CodeSystemTenantProtected, apperror.NewValidation("cannot deprovision system tenant"))
+	}
+	return tenant, nil
+}
+
+// hashTenantDeprovisionToken returns the hex SHA-256 of a confirmation token,
+// as stored in TokenHash.
+func hashTenantDeprovisionToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/runner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func stubProvisionTenant(t *testing.T, fn func(db *gorm.DB, tenantID int64, appVersion string) error) {
+	t.Helper()
+	orig := runner.ProvisionTenant
+	t.Cleanup(func() { runner.ProvisionTenant = orig })
+	runner.ProvisionTenant = fn
+}
+
+// provisionerTenantRepo returns a tenant repo that finds tenant.
+func provisionerTenantRepo(tenant *model.Tenant) *mockTenantRepo {
+	return &mockTenantRepo{findByUUIDFn: func(_ any, _ ...string) (*model.Tenant, error) {
+		return tenant, nil
+	}}
+}
+
+func newTenantProvisioner(db *gorm.DB, tenantRepo *mockTenantRepo, userRepo *mockUserRepo, userPoolRepo *mockUserPoolRepo, clientRepo *mockClientRepo, deprovisionRepo *mockTenantDeprovisionRepo) TenantProvisioner {
+	return NewTenantProvisioner(db, tenantRepo, userRepo, userPoolRepo, clientRepo, deprovisionRepo)
+}
+
+const deprovisionToken = "confirm-token"
+
+// newPendingDeprovision returns a pending deprovision request of tenant that
+// matches deprovisionToken.
+func newPendingDeprovision(tenant *model.Tenant, mode string, requestedBy int64, expiresAt time.Time) *model.TenantDeprovision {
+	return &model.TenantDeprovision{
+		TenantDeprovisionUUID: uuid.New(),
+		TenantID:              &tenant.TenantID,
+		TenantUUID:            tenant.TenantUUID,
+		Mode:                  mode,
+		TokenHash:             hashTenantDeprovisionToken(deprovisionToken),
+		Status:                model.TenantDeprovisionStatusPending,
+		RequestedBy:           &requestedBy,
+		ExpiresAt:             expiresAt,
+	}
+}
+
+// pendingDeprovisionRepo returns a deprovision repo whose only pending
+// request is req.
+func pendingDeprovisionRepo(req *model.TenantDeprovision) *mockTenantDeprovisionRepo {
+	return &mockTenantDeprovisionRepo{findPendingByTokenHashFn: func(tenantID int64, tokenHash string) (*model.TenantDeprovision, error) {
+		if tenantID != *req.TenantID || tokenHash != req.TokenHash {
+			return nil, nil
+		}
+		return req, nil
+	}}
+}
+
+func TestTenantProvisioner_Provision(t *testing.T) {
+	t.Run("seeds the tenant", func(t *testing.T) {
+		var gotTenantID int64
+		var gotVersion string
+		stubProvisionTenant(t, func(_ *gorm.DB, tenantID int64, appVersion string) error {
+			gotTenantID, gotVersion = tenantID, appVersion
+			return nil
+		})
+
+		err := newTenantProvisioner(nil, &mockTenantRepo{}, &mockUserRepo{}, &mockUserPoolRepo{}, &mockClientRepo{}, &mockTenantDeprovisionRepo{}).Provision(context.Background(), nil, 7)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), gotTenantID)
+		assert.Equal(t, config.AppVersion, gotVersion)
+	})
+
+	t.Run("seed error", func(t *testing.T) {
+		stubProvisionTenant(t, func(_ *gorm.DB, _ int64, _ string) error { return errors.New("seed failed") })
+
+		err := newTenantProvisioner(nil, &mockTenantRepo{}, &mockUserRepo{}, &mockUserPoolRepo{}, &mockClientRepo{}, &mockTenantDeprovisionRepo{}).Provision(context.Background(), nil, 7)
+		var internal *apperror.InternalError
+		require.ErrorAs(t, err, &internal)
+	})
+}
+
+func TestTenantProvisioner_Deprovision(t *testing.T) {
+	tenant := newTenant(7, "acme")
+
+	t.Run("removes exclusive users and user pools before the tenant", func(t *testing.T) {
+		var calls []string
+		userRepo := &mockUserRepo{deleteExclusiveToTenantFn: func(tenantID int64) (int64, error) {
+			assert.Equal(t, int64(7), tenantID)
+			calls = append(calls, "users")
+			return 2, nil
+		}}
+		userPoolRepo := &mockUserPoolRepo{deleteByTenantIDFn: func(tenantID int64) error {
+			assert.Equal(t, int64(7), tenantID)
+			calls = append(calls, "user_pools")
+			return nil
+		}}
+		tenantRepo := provisionerTenantRepo(tenant)
+		tenantRepo.deleteByUUIDFn = func(id any) error {
+			assert.Equal(t, tenant.TenantUUID, id)
+			calls = append(calls, "tenant")
+			return nil
+		}
+		provisioner := newTenantProvisioner(nil, tenantRepo, userRepo, userPoolRepo, &mockClientRepo{}, &mockTenantDeprovisionRepo{})
+
+		require.NoError(t, provisioner.Deprovision(context.Background(), nil, tenant))
+		assert.Equal(t, []string{"users", "user_pools", "tenant"}, calls)
+	})
+
+	t.Run("user delete error stops", func(t *testing.T) {
+		userRepo := &mockUserRepo{deleteExclusiveToTenantFn: func(int64) (int64, error) { return 0, errors.New("users err") }}
+		tenantRepo := provisionerTenantRepo(tenant)
+		tenantRepo.deleteByUUIDFn = func(any) error {
+			t.Fatal("tenant must not be deleted")
+			return nil
+		}
+		provisioner := newTenantProvisioner(nil, tenantRepo, userRepo, &mockUserPoolRepo{}, &mockClientRepo{}, &mockTenantDeprovisionRepo{})
+
+		err := provisioner.Deprovision(context.Background(), nil, tenant)
+		assert.EqualError(t, err, "users err")
+	})
+
+	t.Run("user pool delete error stops", func(t *testing.T) {
+		userPoolRepo := &mockUserPoolRepo{deleteByTenantIDFn: func(int64) error { return errors.New("pools err") }}
+		provisioner := newTenantProvisioner(nil, provisionerTenantRepo(tenant), &mockUserRepo{}, userPoolRepo, &mockClientRepo{}, &mockTenantDeprovisionRepo{})
+
+		err := provisioner.Deprovision(context.Background(), nil, tenant)
+		assert.EqualError(t, err, "pools err")
+	})
+}
+
+func TestTenantProvisioner_RequestDeprovision(t *testing.T) {
+	ctx := context.Background()
+	tenant := newTenant(7, "acme")
+
+	t.Run("tenant not found", func(t *testing.T) {
+		provisioner := newTenantProvisioner(nil, provisionerTenantRepo(nil), &mockUserRepo{}, &mockUserPoolRepo{}, &mockClientRepo{}, &mockTenantDeprovisionRepo{})
+		_, err := provisioner.RequestDeprovision(ctx, uuid.New(), 1, model.TenantDeprovisionModeArchive)
+		var notFound *apperror.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("system tenant", func(t *testing.T) {
+		system := newTenant(1, "system")
+		system.IsSystem = true
+		provisioner := newTenantProvisioner(nil, provisionerTenantRepo(system), &mockUserRepo{}, &mockUserPoolRepo{}, &mockClientRepo{}, &mockTenantDeprovisionRepo{})
+		_, err := provisioner.RequestDeprovision(ctx, system.TenantUUID, 1, model.TenantDeprovisionModeArchive)
+		var validation *apperror.ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+
+	t.Run("delete is refused while the tenant has child tenants", func(t *testing.T) {
+		tenantRepo := provisionerTenantRepo(tenant)
+		tenantRepo.countChildrenFn = func(int64) (int64, error) { return 2, nil }
+		provisioner := newTenantProvisioner(nil, tenantRepo, &mockUserRepo{}, &mockUserPoolRepo{}, &mockClientRepo{}, &mockTenantDeprovisionRepo{})
+		_, err := provisioner.RequestDeprovision(ctx, tenant.TenantUUID, 1, model.TenantDeprovisionModeDelete)
+		var conflict *apperror.ConflictError
+		require.ErrorAs(t, err, &conflict)
+		code, _ := apperror.CodeOf(err)
+		assert.Equal(t, apperror.CodeTenantHasChildren, code)
+	})
+
+	t.Run("archive issues a token", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		superseded := false
+		var stored *model.TenantDeprovision
+		deprovisionRepo := &mockTenantDeprovisionRepo{
+			supersedePendingFn: func(tenantID int64) error {
+				assert.Equal(t, int64(7), tenantID)
+				superseded = true
+				return nil
+			},
+			createFn: func(e *model.TenantDeprovision) (*model.TenantDeprovision, error) {
+				e.TenantDeprovisionUUID = uuid.New()
+				stored = e
+				return e, nil
+			},
+		}
+		provisioner := newTenantProvisioner(db, provisionerTenantRepo(tenant), &mockUserRepo{}, &mockUserPoolRepo{}, &mockClientRepo{}, deprovisionRepo)
+
+		res, err := provisioner.RequestDeprovision(ctx, tenant.TenantUUID, 42, model.TenantDeprovisionModeArchive)
+		require.NoError(t, err)
+		assert.True(t, superseded, "earlier requests are superseded")
+		require.NotNil(t, stored)
+		assert.NotEmpty(t, res.ConfirmationToken)
+		assert.Equal(t, hashTenantDeprovisionToken(res.ConfirmationToken), stored.TokenHash)
+		assert.NotEqual(t, res.ConfirmationToken, stored.TokenHash, "the token is stored hashed")
+		assert.Equal(t, int64(42), *stored.RequestedBy)
+		assert.Equal(t, "acme", stored.TenantName)
+		assert.Equal(t, stored.TenantDeprovisionUUID, res.DeprovisionUUID)
+		assert.WithinDuration(t, time.Now().Add(tenantDeprovisionTokenTTL), res.ExpiresAt, time.Minute)
+		assert.Nil(t, res.Impact)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delete previews the impact", func(t *testing.T) {
+		stubDeleteImpact(t, &mockDeleteImpactRepo{analyzeFn: func(table, column string, value any) (*repository.DeleteImpact, error) {
+			assert.Equal(t, "tenants", table)
+			assert.Equal(t, int64(7), value)
+			return &repository.DeleteImpact{Entries: []repository.DeleteImpactEntry{{Table: "tenants", Action: repository.DeleteImpactDelete, Rows: 1}}}, nil
+		}})
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		provisioner := newTenantProvisioner(db, provisionerTenantRepo(tenant), &mockUserRepo{}, &mockUserPoolRepo{}, &mockClientRepo{}, &mockTenantDeprovisionRepo{})
+		res, err := provisioner.RequestDeprovision(ctx, tenant.TenantUUID, 42, model.TenantDeprovisionModeDelete)
+		require.NoError(t, err)
+		require.NotNil(t, res.Impact)
+		assert.True(t, res.Impact.WouldSucceed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("create error", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		deprovisionRepo := &mockTenantDeprovisionRepo{createFn: func(*model.TenantDeprovision) (*model.TenantDeprovision, error) {
+			return nil, errors.New("create err")
+		}}
+		provisioner := newTenantProvisioner(db, provisionerTenantRepo(tenant), &mockUserRepo{}, &mockUserPoolRepo{}, &mockClientRepo{}, deprovisionRepo)
+
+		_, err := provisioner.RequestDeprovision(ctx, tenant.TenantUUID, 42, model.TenantDeprovisionModeArchive)
+		assert.EqualError(t, err, "create err")
+	})
+}
+
+func TestTenantProvisioner_ConfirmDeprovision(t *testing.T) {
+	ctx := context.Background()
+	tenant := newTenant(7, "acme")
+	t.Run("invalid token", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		req := newPendingDeprovision(tenant, model.TenantDeprovisionModeArchive, 42, time.Now().Add(time.Minute))
+		provisioner := newTenantProvisioner(db, provisionerTenantRepo(tenant), &mockUserRepo{}, &mockUserPoolRepo{}, &mockClientRepo{}, pendingDeprovisionRepo(req))
+		_, err := provisioner.ConfirmDeprovision(ctx, tenant.TenantUUID, 42, "wrong")
+		var validation *apperror.ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+
+	t.Run("requested by another user", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		req := newPendingDeprovision(tenant, model.TenantDeprovisionModeArchive, 42, time.Now().Add(time.Minute))
+		provisioner := newTenantProvisioner(db, provisionerTenantRepo(tenant), &mockUserRepo{}, &mockUserPoolRepo{}, &mockClientRepo{}, pendingDeprovisionRepo(req))
+		_, err := provisioner.ConfirmDeprovision(ctx, tenant.TenantUUID, 43, deprovisionToken)
+		var forbidden *apperror.ForbiddenError
+		assert.ErrorAs(t, err, &forbidden)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		req := newPendingDeprovision(tenant, model.TenantDeprovisionModeArchive, 42, time.Now().Add(-time.Minute))
+		provisioner := newTenantProvisioner(db, provisionerTenantRepo(tenant), &mockUserRepo{}, &mockUserPoolRepo{}, &mockClientRepo{}, pendingDeprovisionRepo(req))
+		_, err := provisioner.ConfirmDeprovision(ctx, tenant.TenantUUID, 42, deprovisionToken)
+		var validation *apperror.ValidationError
+		require.ErrorAs(t, err, &validation)
+		assert.Contains(t, err.Error(), "expired")
+	})
+
+	t.Run("archive", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		req := newPendingDeprovision(tenant, model.TenantDeprovisionModeArchive, 42, time.Now().Add(time.Minute))
+		var completed map[string]any
+		deprovisionRepo := pendingDeprovisionRepo(req)
+		deprovisionRepo.updateByUUIDFn = func(id, data any) (*model.TenantDeprovision, error) {
+			assert.Equal(t, req.TenantDeprovisionUUID, id)
+			completed = data.(map[string]any)
+			return req, nil
+		}
+		var tenantUpdate map[string]any
+		tenantRepo := provisionerTenantRepo(tenant)
+		tenantRepo.updateByUUIDFn = func(_, data any) (*model.Tenant, error) {
+			tenantUpdate = data.(map[string]any)
+			return tenant, nil
+		}
+		tenantRepo.deleteByUUIDFn = func(any) error {
+			t.Fatal("archiving must not delete the tenant")
+			return nil
+		}
+		var clientStatus string
+		clientRepo := &mockClientRepo{setStatusByTenantIDFn: func(tenantID int64, status string) error {
+			assert.Equal(t, int64(7), tenantID)
+			clientStatus = status
+			return nil
+		}}
+		provisioner := newTenantProvisioner(db, tenantRepo, &mockUserRepo{}, &mockUserPoolRepo{}, clientRepo, deprovisionRepo)
+
+		res, err := provisioner.ConfirmDeprovision(ctx, tenant.TenantUUID, 42, deprovisionToken)
+		require.NoError(t, err)
+		assert.Equal(t, model.TenantDeprovisionModeArchive, res.Mode)
+		assert.Equal(t, model.TenantDeprovisionStatusCompleted, completed["status"])
+		assert.Equal(t, model.StatusArchived, tenantUpdate["status"])
+		assert.Equal(t, false, tenantUpdate["is_public"])
+		assert.Equal(t, model.StatusInactive, clientStatus)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		req := newPendingDeprovision(tenant, model.TenantDeprovisionModeDelete, 42, time.Now().Add(time.Minute))
+		deleted := false
+		tenantRepo := provisionerTenantRepo(tenant)
+		tenantRepo.deleteByUUIDFn = func(any) error { deleted = true; return nil }
+		provisioner := newTenantProvisioner(db, tenantRepo, &mockUserRepo{}, &mockUserPoolRepo{}, &mockClientRepo{}, pendingDeprovisionRepo(req))
+
+		res, err := provisioner.ConfirmDeprovision(ctx, tenant.TenantUUID, 42, deprovisionToken)
+		require.NoError(t, err)
+		assert.Equal(t, model.TenantDeprovisionModeDelete, res.Mode)
+		assert.True(t, deleted)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delete with child tenants rolls back", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		req := newPendingDeprovision(tenant, model.TenantDeprovisionModeDelete, 42, time.Now().Add(time.Minute))
+		tenantRepo := provisionerTenantRepo(tenant)
+		tenantRepo.countChildrenFn = func(int64) (int64, error) { return 1, nil }
+		tenantRepo.deleteByUUIDFn = func(any) error {
+			t.Fatal("a tenant with child tenants must not be deleted")
+			return nil
+		}
+		provisioner := newTenantProvisioner(db, tenantRepo, &mockUserRepo{}, &mockUserPoolRepo{}, &mockClientRepo{}, pendingDeprovisionRepo(req))
+
+		_, err := provisioner.ConfirmDeprovision(ctx, tenant.TenantUUID, 42, deprovisionToken)
+		var conflict *apperror.ConflictError
+		assert.ErrorAs(t, err, &conflict)
+	})
+
+	t.Run("delete error rolls back", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		req := newPendingDeprovision(tenant, model.TenantDeprovisionModeDelete, 42, time.Now().Add(time.Minute))
+		tenantRepo := provisionerTenantRepo(tenant)
+		tenantRepo.deleteByUUIDFn = func(any) error { return errors.New("delete err") }
+		provisioner := newTenantProvisioner(db, tenantRepo, &mockUserRepo{}, &mockUserPoolRepo{}, &mockClientRepo{}, pendingDeprovisionRepo(req))
+
+		_, err := provisioner.ConfirmDeprovision(ctx, tenant.TenantUUID, 42, deprovisionToken)
+		assert.EqualError(t, err, "delete err")
+	})
+}
@@ -18,13 +18,15 @@ import (
 // TenantSettingServiceDataResult is the service-layer representation of a
 // tenant_settings record.
 type TenantSettingServiceDataResult struct {
-	TenantSettingUUID uuid.UUID
-	RateLimitConfig   map[string]any
-	AuditConfig       map[string]any
-	MaintenanceConfig map[string]any
-	FeatureFlags      map[string]any
-	CreatedAt         time.Time
-	UpdatedAt         time.Time
+	TenantSettingUUID   uuid.UUID
+	RateLimitConfig     map[string]any
+	AuditConfig         map[string]any
+	MaintenanceConfig   map[string]any
+	FeatureFlags        map[string]any
+	UserMetadataSchema  map[string]any
+	UserSettingDefaults map[string]any
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
 }
 
 // TenantSettingService defines business operations on tenant settings.
@@ -38,6 +40,10 @@ type TenantSettingService interface {
 	UpdateAuditConfig(ctx context.Context, tenantID int64, config map[string]any) (*TenantSettingServiceDataResult, error)
 	UpdateMaintenanceConfig(ctx context.Context, tenantID int64, config map[string]any) (*TenantSettingServiceDataResult, error)
 	UpdateFeatureFlags(ctx context.Context, tenantID int64, config map[string]any) (*TenantSettingServiceDataResult, error)
+	GetUserMetadataSchema(ctx context.Context, tenantID int64) (map[string]any, error)
+	UpdateUserMetadataSchema(ctx context.Context, tenantID int64, schema map[string]any) (*TenantSettingServiceDataResult, error)
+	GetUserSettingDefaults(ctx context.Context, tenantID int64) (map[string]any, error)
+	UpdateUserSettingDefaults(ctx context.Context, tenantID int64, defaults model.UserSettingDefaults) (*TenantSettingServiceDataResult, error)
 }
 
 type tenantSettingService struct {
@@ -53,13 +59,15 @@ func NewTenantSettingService(tenantSettingRepo repository.TenantSettingRepositor
 // service-layer representation by unmarshalling each JSONB column.
 func toTenantSettingServiceDataResult(ts *model.TenantSetting) *TenantSettingServiceDataResult {
 	return &TenantSettingServiceDataResult{
-		TenantSettingUUID: ts.TenantSettingUUID,
-		RateLimitConfig:   unmarshalJSON(ts.RateLimitConfig),
-		AuditConfig:       unmarshalJSON(ts.AuditConfig),
-		MaintenanceConfig: unmarshalJSON(ts.MaintenanceConfig),
-		FeatureFlags:      unmarshalJSON(ts.FeatureFlags),
-		CreatedAt:         ts.CreatedAt,
-		UpdatedAt:         ts.UpdatedAt,
+		TenantSettingUUID:   ts.TenantSettingUUID,
+		RateLimitConfig:     unmarshalJSON(ts.RateLimitConfig),
+		AuditConfig:         unmarshalJSON(ts.AuditConfig),
+		MaintenanceConfig:   unmarshalJSON(ts.MaintenanceConfig),
+		FeatureFlags:        unmarshalJSON(ts.FeatureFlags),
+		UserMetadataSchema:  unmarshalJSON(ts.UserMetadataSchema),
+		UserSettingDefaults: unmarshalJSON(ts.UserSettingDefaults),
+		CreatedAt:           ts.CreatedAt,
+		UpdatedAt:           ts.UpdatedAt,
 	}
 }
 
@@ -144,8 +152,12 @@ func (s *tenantSettingService) GetFeatureFlags(ctx context.Context, tenantID int
 	return unmarshalJSON(setting.FeatureFlags), nil
 }
 
-// UpdateRateLimitConfig updates the rate_limit_config JSONB section.
+// UpdateRateLimitConfig updates the rate_limit_config JSONB section. Monthly
+// quotas must be non-negative whole numbers.
 func (s *tenantSettingService) UpdateRateLimitConfig(ctx context.Context, tenantID int64, config map[string]any) (*TenantSettingServiceDataResult, error) {
+	if err := validateUsageQuotas(config); err != nil {
+		return nil, err
+	}
 	return s.updateConfig(ctx, tenantID, "rate_limit", config)
 }
 
@@ -164,6 +176,64 @@ func (s *tenantSettingService) UpdateFeatureFlags(ctx context.Context, tenantID
 	return s.updateConfig(ctx, tenantID, "feature_flags", config)
 }
 
+// GetUserMetadataSchema retrieves the JSON Schema that user metadata must
+// satisfy. An empty map means metadata is unconstrained.
+func (s *tenantSettingService) GetUserMetadataSchema(ctx context.Context, tenantID int64) (map[string]any, error) {
+	_, span := otel.Tracer("service").Start(ctx, "tenantSetting.getUserMetadataSchema")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID))
+
+	setting, err := s.getOrCreate(tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get user metadata schema failed")
+		return nil, err
+	}
+	span.SetStatus(codes.Ok, "")
+	return unmarshalJSON(setting.UserMetadataSchema), nil
+}
+
+// UpdateUserMetadataSchema replaces the user metadata JSON Schema. The schema
+// is compiled first so an invalid document is rejected instead of breaking
+// every subsequent user write.
+func (s *tenantSettingService) UpdateUserMetadataSchema(ctx context.Context, tenantID int64, schema map[string]any) (*TenantSettingServiceDataResult, error) {
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return nil, apperror.NewValidation("invalid schema payload")
+	}
+	if _, err := compileUserMetadataSchema(raw); err != nil {
+		return nil, apperror.NewValidation("invalid user metadata schema: " + err.Error())
+	}
+	return s.updateConfig(ctx, tenantID, "user_metadata_schema", schema)
+}
+
+// GetUserSettingDefaults retrieves the preferences applied to users who have
+// not chosen their own. An empty map means the built-in defaults apply.
+func (s *tenantSettingService) GetUserSettingDefaults(ctx context.Context, tenantID int64) (map[string]any, error) {
+	_, span := otel.Tracer("service").Start(ctx, "tenantSetting.getUserSettingDefaults")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", tenantID))
+
+	setting, err := s.getOrCreate(tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get user setting defaults failed")
+		return nil, err
+	}
+	span.SetStatus(codes.Ok, "")
+	return unmarshalJSON(setting.UserSettingDefaults), nil
+}
+
+// UpdateUserSettingDefaults replaces the tenant's user setting defaults.
+// Empty fields fall back to the built-in defaults.
+func (s *tenantSettingService) UpdateUserSettingDefaults(ctx context.Context, tenantID int64, defaults model.UserSettingDefaults) (*TenantSettingServiceDataResult, error) {
+	raw, err := json.Marshal(defaults)
+	if err != nil {
+		return nil, apperror.NewValidation("invalid user setting defaults payload")
+	}
+	return s.updateConfig(ctx, tenantID, "user_setting_defaults", unmarshalJSON(raw))
+}
+
 func (s *tenantSettingService) updateConfig(ctx context.Context, tenantID int64, configType string, config map[string]any) (*TenantSettingServiceDataResult, error) {
 	_, span := otel.Tracer("service").Start(ctx, "tenantSetting.update."+configType)
 	defer span.End()
@@ -193,6 +263,10 @@ func (s *tenantSettingService) updateConfig(ctx context.Context, tenantID int64,
 		setting.MaintenanceConfig = jsonData
 	case "feature_flags":
 		setting.FeatureFlags = jsonData
+	case "user_metadata_schema":
+		setting.UserMetadataSchema = jsonData
+	case "user_setting_defaults":
+		setting.UserSettingDefaults = jsonData
 	default:
 		return nil, apperror.NewValidation("invalid config type")
 	}
@@ -220,11 +294,13 @@ func (s *tenantSettingService) getOrCreate(tenantID int64) (*model.TenantSetting
 	}
 
 	setting = &model.TenantSetting{
-		TenantID:          tenantID,
-		RateLimitConfig:   datatypes.JSON([]byte("{}")),
-		AuditConfig:       datatypes.JSON([]byte("{}")),
-		MaintenanceConfig: datatypes.JSON([]byte("{}")),
-		FeatureFlags:      datatypes.JSON([]byte("{}")),
+		TenantID:            tenantID,
+		RateLimitConfig:     datatypes.JSON([]byte("{}")),
+		AuditConfig:         datatypes.JSON([]byte("{}")),
+		MaintenanceConfig:   datatypes.JSON([]byte("{}")),
+		FeatureFlags:        datatypes.JSON([]byte("{}")),
+		UserMetadataSchema:  datatypes.JSON([]byte("{}")),
+		UserSettingDefaults: datatypes.JSON([]byte("{}")),
 	}
 	created, err := s.tenantSettingRepo.Create(setting)
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -210,6 +211,15 @@ func TestTenantSettingService_UpdateRateLimitConfig(t *testing.T) {
 		_, err := svc.UpdateRateLimitConfig(context.Background(), 1, map[string]any{})
 		require.Error(t, err)
 	})
+
+	t.Run("invalid quota", func(t *testing.T) {
+		svc := newTenantSettingSvc(&mockTenantSettingRepo{})
+		for _, quota := range []any{-1.0, 1.5, "1000"} {
+			_, err := svc.UpdateRateLimitConfig(context.Background(), 1, map[string]any{"monthly_request_quota": quota})
+			var ve *apperror.ValidationError
+			assert.ErrorAs(t, err, &ve, "quota %v", quota)
+		}
+	})
 }
 
 func TestTenantSettingService_UpdateAuditConfig(t *testing.T) {
@@ -340,3 +350,80 @@ func TestTenantSettingService_updateConfig_marshalError(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid config payload")
 }
+
+// ---------------------------------------------------------------------------
+// User metadata schema
+// ---------------------------------------------------------------------------
+
+func TestTenantSettingService_GetUserMetadataSchema(t *testing.T) {
+	ts := newTenantSetting(1)
+	ts.UserMetadataSchema = datatypes.JSON([]byte(`{"type":"object"}`))
+	svc := newTenantSettingSvc(&mockTenantSettingRepo{
+		findByTenantIDFn: func(_ int64) (*model.TenantSetting, error) { return ts, nil },
+	})
+	res, err := svc.GetUserMetadataSchema(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "object", res["type"])
+}
+
+func TestTenantSettingService_UpdateUserMetadataSchema(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ts := newTenantSetting(1)
+		svc := newTenantSettingSvc(&mockTenantSettingRepo{
+			findByTenantIDFn: func(_ int64) (*model.TenantSetting, error) { return ts, nil },
+			createOrUpdateFn: func(e *model.TenantSetting) (*model.TenantSetting, error) { return e, nil },
+		})
+		res, err := svc.UpdateUserMetadataSchema(context.Background(), 1, map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"plan": map[string]any{"type": "string"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "object", res.UserMetadataSchema["type"])
+	})
+
+	t.Run("empty schema clears constraints", func(t *testing.T) {
+		ts := newTenantSetting(1)
+		svc := newTenantSettingSvc(&mockTenantSettingRepo{
+			findByTenantIDFn: func(_ int64) (*model.TenantSetting, error) { return ts, nil },
+			createOrUpdateFn: func(e *model.TenantSetting) (*model.TenantSetting, error) { return e, nil },
+		})
+		res, err := svc.UpdateUserMetadataSchema(context.Background(), 1, map[string]any{})
+		require.NoError(t, err)
+		assert.Empty(t, res.UserMetadataSchema)
+	})
+
+	t.Run("invalid schema", func(t *testing.T) {
+		svc := newTenantSettingSvc(&mockTenantSettingRepo{
+			createOrUpdateFn: func(_ *model.TenantSetting) (*model.TenantSetting, error) {
+				t.Fatal("invalid schema must not be saved")
+				return nil, nil
+			},
+		})
+		_, err := svc.UpdateUserMetadataSchema(context.Background(), 1, map[string]any{"type": 5})
+		var target *apperror.ValidationError
+		require.ErrorAs(t, err, &target)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// User setting defaults
+// ---------------------------------------------------------------------------
+
+func TestTenantSettingService_UserSettingDefaults(t *testing.T) {
+	ts := newTenantSetting(1)
+	svc := newTenantSettingSvc(&mockTenantSettingRepo{
+		findByTenantIDFn: func(_ int64) (*model.TenantSetting, error) { return ts, nil },
+		createOrUpdateFn: func(e *model.TenantSetting) (*model.TenantSetting, error) { return e, nil },
+	})
+
+	res, err := svc.UpdateUserSettingDefaults(context.Background(), 1, model.UserSettingDefaults{
+		Theme:    model.ThemeDark,
+		Timezone: "Europe/Berlin",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"theme": "dark", "timezone": "Europe/Berlin"}, res.UserSettingDefaults)
+
+	defaults, err := svc.GetUserSettingDefaults(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"theme": "dark", "timezone": "Europe/Berlin"}, defaults)
+}
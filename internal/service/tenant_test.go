@@ -7,11 +7,13 @@ import (
 
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
 	"github.com/maintainerd/auth/internal/crypto"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/repository"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 // newTenant returns a minimal Tenant fixture for tests.
@@ -66,7 +68,7 @@ func TestTenantService_GetByUUID(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := &mockTenantRepo{}
 			tc.setupRepo(repo)
-			svc := NewTenantService(nil, repo)
+			svc := NewTenantService(nil, repo, &mockTenantProvisioner{})
 			result, err := svc.GetByUUID(context.Background(), uuid.New())
 			if tc.expectError {
 				require.Error(t, err)
@@ -112,7 +114,7 @@ func TestTenantService_GetSystem(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := &mockTenantRepo{}
 			tc.setupRepo(repo)
-			svc := NewTenantService(nil, repo)
+			svc := NewTenantService(nil, repo, &mockTenantProvisioner{})
 			result, err := svc.GetSystem(context.Background())
 			if tc.expectError {
 				require.Error(t, err)
@@ -158,7 +160,7 @@ func TestTenantService_GetByIdentifier(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := &mockTenantRepo{}
 			tc.setupRepo(repo)
-			svc := NewTenantService(nil, repo)
+			svc := NewTenantService(nil, repo, &mockTenantProvisioner{})
 			result, err := svc.GetByIdentifier(context.Background(), tc.identifier)
 			if tc.expectError {
 				require.Error(t, err)
@@ -177,7 +179,7 @@ func TestTenantService_GetByIdentifier(t *testing.T) {
 func TestTenantService_Get(t *testing.T) {
 	t.Run("success – empty result", func(t *testing.T) {
 		repo := &mockTenantRepo{}
-		svc := NewTenantService(nil, repo)
+		svc := NewTenantService(nil, repo, &mockTenantProvisioner{})
 		result, err := svc.Get(context.Background(), TenantServiceGetFilter{Page: 1, Limit: 10})
 		require.NoError(t, err)
 		assert.NotNil(t, result)
@@ -190,11 +192,25 @@ func TestTenantService_Get(t *testing.T) {
 				return nil, errors.New("db error")
 			},
 		}
-		svc := NewTenantService(nil, repo)
+		svc := NewTenantService(nil, repo, &mockTenantProvisioner{})
 		result, err := svc.Get(context.Background(), TenantServiceGetFilter{Page: 1, Limit: 10})
 		require.Error(t, err)
 		assert.Nil(t, result)
 	})
+
+	t.Run("tenant IDs restrict the query", func(t *testing.T) {
+		var got []int64
+		repo := &mockTenantRepo{
+			findPaginatedFn: func(f repository.TenantRepositoryGetFilter) (*repository.PaginationResult[model.Tenant], error) {
+				got = f.TenantIDs
+				return &repository.PaginationResult[model.Tenant]{}, nil
+			},
+		}
+		svc := NewTenantService(nil, repo, &mockTenantProvisioner{})
+		_, err := svc.Get(context.Background(), TenantServiceGetFilter{TenantIDs: []int64{4, 5}, Page: 1, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{4, 5}, got)
+	})
 }
 
 // ---------------------------------------------------------------------------
@@ -230,6 +246,17 @@ func TestTenantService_DeleteByUUID(t *testing.T) {
 			expectError: true,
 			errContains: "system tenant",
 		},
+		{
+			name: "tenant with child tenants → error",
+			setupRepo: func(r *mockTenantRepo) {
+				r.findByUUIDFn = func(_ any, _ ...string) (*model.Tenant, error) {
+					return newTenant(1, "acme"), nil
+				}
+				r.countChildrenFn = func(int64) (int64, error) { return 2, nil }
+			},
+			expectError: true,
+			errContains: "child tenants",
+		},
 		{
 			name: "success",
 			setupRepo: func(r *mockTenantRepo) {
@@ -244,7 +271,12 @@ func TestTenantService_DeleteByUUID(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := &mockTenantRepo{}
 			tc.setupRepo(repo)
-			svc := NewTenantService(nil, repo)
+			db, mock := newMockGormDB(t)
+			if !tc.expectError {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			}
+			svc := NewTenantService(db, repo, &mockTenantProvisioner{})
 			result, err := svc.DeleteByUUID(context.Background(), tenantUUID)
 			if tc.expectError {
 				require.Error(t, err)
@@ -302,7 +334,7 @@ func TestTenantService_SetStatusByUUID(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := &mockTenantRepo{}
 			tc.setupRepo(repo)
-			svc := NewTenantService(nil, repo)
+			svc := NewTenantService(nil, repo, &mockTenantProvisioner{})
 			result, err := svc.SetStatusByUUID(context.Background(), tenantUUID, model.StatusActive)
 			if tc.expectError {
 				require.Error(t, err)
@@ -330,7 +362,7 @@ func TestTenantService_SetStatusByUUID_Extra(t *testing.T) {
 				return errors.New("set status err")
 			},
 		}
-		svc := NewTenantService(nil, repo)
+		svc := NewTenantService(nil, repo, &mockTenantProvisioner{})
 		_, err := svc.SetStatusByUUID(context.Background(), tenantUUID, "inactive")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "set status err")
@@ -347,7 +379,7 @@ func TestTenantService_SetStatusByUUID_Extra(t *testing.T) {
 				return nil, errors.New("fetch err")
 			},
 		}
-		svc := NewTenantService(nil, repo)
+		svc := NewTenantService(nil, repo, &mockTenantProvisioner{})
 		_, err := svc.SetStatusByUUID(context.Background(), tenantUUID, "inactive")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "fetch err")
@@ -370,7 +402,7 @@ func TestTenantService_Get_WithData(t *testing.T) {
 			}, nil
 		},
 	}
-	svc := NewTenantService(nil, repo)
+	svc := NewTenantService(nil, repo, &mockTenantProvisioner{})
 	res, err := svc.Get(context.Background(), TenantServiceGetFilter{Page: 1, Limit: 10})
 	require.NoError(t, err)
 	assert.Len(t, res.Data, 2)
@@ -389,8 +421,8 @@ func TestTenantService_Create(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
 		mock.ExpectRollback()
-		svc := NewTenantService(db, repo)
-		_, err := svc.Create(context.Background(), "acme", "Acme Corp", "desc", "active", true)
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
+		_, err := svc.Create(context.Background(), "acme", "Acme Corp", "desc", "active", true, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "name err")
 	})
@@ -402,8 +434,8 @@ func TestTenantService_Create(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
 		mock.ExpectRollback()
-		svc := NewTenantService(db, repo)
-		_, err := svc.Create(context.Background(), "acme", "Acme Corp", "desc", "active", true)
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
+		_, err := svc.Create(context.Background(), "acme", "Acme Corp", "desc", "active", true, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "already exists")
 	})
@@ -417,8 +449,8 @@ func TestTenantService_Create(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
 		mock.ExpectRollback()
-		svc := NewTenantService(db, repo)
-		_, err := svc.Create(context.Background(), "acme", "Acme Corp", "desc", "active", true)
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
+		_, err := svc.Create(context.Background(), "acme", "Acme Corp", "desc", "active", true, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "rand failure")
 	})
@@ -430,8 +462,8 @@ func TestTenantService_Create(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
 		mock.ExpectRollback()
-		svc := NewTenantService(db, repo)
-		_, err := svc.Create(context.Background(), "acme", "Acme Corp", "desc", "active", true)
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
+		_, err := svc.Create(context.Background(), "acme", "Acme Corp", "desc", "active", true, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "create err")
 	})
@@ -443,8 +475,8 @@ func TestTenantService_Create(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
 		mock.ExpectRollback()
-		svc := NewTenantService(db, repo)
-		_, err := svc.Create(context.Background(), "acme", "Acme Corp", "desc", "active", true)
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
+		_, err := svc.Create(context.Background(), "acme", "Acme Corp", "desc", "active", true, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "fetch err")
 	})
@@ -458,10 +490,169 @@ func TestTenantService_Create(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
 		mock.ExpectCommit()
-		svc := NewTenantService(db, repo)
-		res, err := svc.Create(context.Background(), "acme", "Acme Corp", "desc", "active", true)
+		provisioned := false
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{
+			provisionFn: func(_ context.Context, _ *gorm.DB, _ int64) error { provisioned = true; return nil },
+		})
+		res, err := svc.Create(context.Background(), "acme", "Acme Corp", "desc", "active", true, nil)
 		require.NoError(t, err)
 		assert.Equal(t, "acme", res.Name)
+		assert.True(t, provisioned, "the new tenant is provisioned")
+	})
+
+	t.Run("provisioning error rolls back", func(t *testing.T) {
+		repo := &mockTenantRepo{}
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{
+			provisionFn: func(_ context.Context, _ *gorm.DB, _ int64) error {
+				return apperror.NewInternal("failed to provision tenant", errors.New("seed failed"))
+			},
+		})
+		_, err := svc.Create(context.Background(), "acme", "Acme Corp", "desc", "active", true, nil)
+		var internal *apperror.InternalError
+		require.ErrorAs(t, err, &internal)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("parent not found", func(t *testing.T) {
+		repo := &mockTenantRepo{}
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
+		parentUUID := uuid.New()
+		_, err := svc.Create(context.Background(), "acme", "Acme Corp", "desc", "active", true, &parentUUID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "parent tenant not found")
+	})
+
+	t.Run("child of a parent tenant", func(t *testing.T) {
+		parent := newTenant(5, "parent")
+		parent.AncestorPath = "/2/"
+		var created *model.Tenant
+		repo := &mockTenantRepo{
+			findByUUIDFn: func(id any, _ ...string) (*model.Tenant, error) {
+				if id == parent.TenantUUID {
+					return parent, nil
+				}
+				return created, nil
+			},
+			createOrUpdateFn: func(e *model.Tenant) (*model.Tenant, error) {
+				e.TenantID = 9
+				created = e
+				return e, nil
+			},
+		}
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
+		_, err := svc.Create(context.Background(), "acme", "Acme Corp", "desc", "active", true, &parent.TenantUUID)
+		require.NoError(t, err)
+		require.NotNil(t, created.ParentTenantID)
+		assert.Equal(t, int64(5), *created.ParentTenantID)
+		assert.Equal(t, "/2/5/", created.AncestorPath)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TenantService.SetParentByUUID
+// ---------------------------------------------------------------------------
+
+func TestTenantService_SetParentByUUID(t *testing.T) {
+	tenant := newTenant(5, "acme")
+	tenant.AncestorPath = "/1/"
+	child := newTenant(9, "child")
+	child.AncestorPath = "/1/5/"
+	other := newTenant(7, "other")
+	other.AncestorPath = "/"
+	system := newTenant(1, "system")
+	system.IsSystem = true
+	missing := uuid.New()
+
+	tenants := map[uuid.UUID]*model.Tenant{
+		tenant.TenantUUID: tenant,
+		child.TenantUUID:  child,
+		other.TenantUUID:  other,
+		system.TenantUUID: system,
+	}
+	findTenant := func(id any, _ ...string) (*model.Tenant, error) { return tenants[id.(uuid.UUID)], nil }
+
+	cases := []struct {
+		name        string
+		tenantUUID  uuid.UUID
+		parentUUID  *uuid.UUID
+		errContains string
+		wantParent  *int64
+		wantPath    string
+	}{
+		{name: "tenant not found", tenantUUID: uuid.New(), errContains: "not found"},
+		{name: "system tenant", tenantUUID: system.TenantUUID, parentUUID: &other.TenantUUID, errContains: "system tenant"},
+		{name: "parent not found", tenantUUID: tenant.TenantUUID, parentUUID: &missing, errContains: "parent tenant not found"},
+		{name: "below itself", tenantUUID: tenant.TenantUUID, parentUUID: &tenant.TenantUUID, errContains: "below itself"},
+		{name: "below a descendant", tenantUUID: tenant.TenantUUID, parentUUID: &child.TenantUUID, errContains: "below itself"},
+		{name: "under another tenant", tenantUUID: tenant.TenantUUID, parentUUID: &other.TenantUUID, wantParent: &other.TenantID, wantPath: "/7/"},
+		{name: "to the top level", tenantUUID: tenant.TenantUUID, wantPath: "/"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var movedParent *int64
+			var movedPath string
+			repo := &mockTenantRepo{
+				findByUUIDFn: findTenant,
+				moveSubtreeFn: func(moved *model.Tenant, parentTenantID *int64, ancestorPath string) error {
+					assert.Equal(t, tenant.TenantID, moved.TenantID)
+					movedParent, movedPath = parentTenantID, ancestorPath
+					return nil
+				},
+			}
+			db, mock := newMockGormDB(t)
+			mock.ExpectBegin()
+			if tc.errContains != "" {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+			svc := NewTenantService(db, repo, &mockTenantProvisioner{})
+			_, err := svc.SetParentByUUID(context.Background(), tc.tenantUUID, tc.parentUUID)
+			if tc.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantParent, movedParent)
+			assert.Equal(t, tc.wantPath, movedPath)
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TenantService.GetAncestorIDs
+// ---------------------------------------------------------------------------
+
+func TestTenantService_GetAncestorIDs(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		db, _ := newMockGormDB(t)
+		svc := NewTenantService(db, &mockTenantRepo{}, &mockTenantProvisioner{})
+		_, err := svc.GetAncestorIDs(context.Background(), uuid.New())
+		require.Error(t, err)
+	})
+
+	t.Run("root first", func(t *testing.T) {
+		repo := &mockTenantRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Tenant, error) {
+				return &model.Tenant{TenantID: 9, AncestorPath: "/1/5/"}, nil
+			},
+		}
+		db, _ := newMockGormDB(t)
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
+		ids, err := svc.GetAncestorIDs(context.Background(), uuid.New())
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 5}, ids)
 	})
 }
 
@@ -479,7 +670,7 @@ func TestTenantService_Update(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
 		mock.ExpectRollback()
-		svc := NewTenantService(db, repo)
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
 		_, err := svc.Update(context.Background(), tenantUUID, "new", "New", "desc", "active", true)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "find err")
@@ -492,12 +683,27 @@ func TestTenantService_Update(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
 		mock.ExpectRollback()
-		svc := NewTenantService(db, repo)
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
 		_, err := svc.Update(context.Background(), tenantUUID, "new", "New", "desc", "active", true)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "tenant not found")
 	})
 
+	t.Run("stale If-Match", func(t *testing.T) {
+		repo := &mockTenantRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.Tenant, error) {
+				return &model.Tenant{TenantID: 1, Name: "old"}, nil
+			},
+		}
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
+		_, err := svc.Update(ifMatchContext(t, `"stale"`), tenantUUID, "new", "New", "desc", "active", true)
+		var want *apperror.PreconditionError
+		require.ErrorAs(t, err, &want)
+	})
+
 	t.Run("name conflict FindByName error", func(t *testing.T) {
 		repo := &mockTenantRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.Tenant, error) {
@@ -508,7 +714,7 @@ func TestTenantService_Update(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
 		mock.ExpectRollback()
-		svc := NewTenantService(db, repo)
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
 		_, err := svc.Update(context.Background(), tenantUUID, "new", "New", "desc", "active", true)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "name err")
@@ -524,7 +730,7 @@ func TestTenantService_Update(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
 		mock.ExpectRollback()
-		svc := NewTenantService(db, repo)
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
 		_, err := svc.Update(context.Background(), tenantUUID, "new", "New", "desc", "active", true)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "already exists")
@@ -540,7 +746,7 @@ func TestTenantService_Update(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
 		mock.ExpectRollback()
-		svc := NewTenantService(db, repo)
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
 		_, err := svc.Update(context.Background(), tenantUUID, "old", "New", "desc", "active", true) // same name → no conflict check
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "save err")
@@ -555,7 +761,7 @@ func TestTenantService_Update(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
 		mock.ExpectCommit()
-		svc := NewTenantService(db, repo)
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
 		res, err := svc.Update(context.Background(), tenantUUID, "acme", "Acme Corp", "desc", "active", true)
 		require.NoError(t, err)
 		assert.Equal(t, "Acme Corp", res.DisplayName)
@@ -570,7 +776,7 @@ func TestTenantService_Update(t *testing.T) {
 		db, mock := newMockGormDB(t)
 		mock.ExpectBegin()
 		mock.ExpectCommit()
-		svc := NewTenantService(db, repo)
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
 		res, err := svc.Update(context.Background(), tenantUUID, "new-name", "New Name", "desc", "active", false)
 		require.NoError(t, err)
 		assert.Equal(t, "new-name", res.Name)
@@ -588,7 +794,7 @@ func TestTenantService_SetActivePublicByUUID(t *testing.T) {
 		repo := &mockTenantRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.Tenant, error) { return nil, nil },
 		}
-		svc := NewTenantService(nil, repo)
+		svc := NewTenantService(nil, repo, &mockTenantProvisioner{})
 		_, err := svc.SetActivePublicByUUID(context.Background(), tenantUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "tenant not found")
@@ -605,7 +811,7 @@ func TestTenantService_SetActivePublicByUUID(t *testing.T) {
 		mock.ExpectExec(`UPDATE .tenants.`).
 			WillReturnError(errors.New("update err"))
 		mock.ExpectRollback()
-		svc := NewTenantService(db, repo)
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
 		_, err := svc.SetActivePublicByUUID(context.Background(), tenantUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "update err")
@@ -627,7 +833,7 @@ func TestTenantService_SetActivePublicByUUID(t *testing.T) {
 		mock.ExpectExec(`UPDATE .tenants.`).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectCommit()
-		svc := NewTenantService(db, repo)
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
 		_, err := svc.SetActivePublicByUUID(context.Background(), tenantUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "fetch err")
@@ -649,7 +855,7 @@ func TestTenantService_SetActivePublicByUUID(t *testing.T) {
 		mock.ExpectExec(`UPDATE .tenants.`).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectCommit()
-		svc := NewTenantService(db, repo)
+		svc := NewTenantService(db, repo, &mockTenantProvisioner{})
 		res, err := svc.SetActivePublicByUUID(context.Background(), tenantUUID)
 		require.NoError(t, err)
 		assert.True(t, res.IsPublic)
@@ -663,10 +869,14 @@ func TestTenantService_SetActivePublicByUUID(t *testing.T) {
 func TestTenantService_DeleteByUUID_DeleteError(t *testing.T) {
 	tenantUUID := uuid.New()
 	repo := &mockTenantRepo{
-		findByUUIDFn:   func(_ any, _ ...string) (*model.Tenant, error) { return newTenant(1, "acme"), nil },
-		deleteByUUIDFn: func(_ any) error { return errors.New("delete err") },
+		findByUUIDFn: func(_ any, _ ...string) (*model.Tenant, error) { return newTenant(1, "acme"), nil },
 	}
-	svc := NewTenantService(nil, repo)
+	db, mock := newMockGormDB(t)
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	svc := NewTenantService(db, repo, &mockTenantProvisioner{
+		deprovisionFn: func(_ context.Context, _ *gorm.DB, _ *model.Tenant) error { return errors.New("delete err") },
+	})
 	_, err := svc.DeleteByUUID(context.Background(), tenantUUID)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "delete err")
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Reasons a token is not valid or a permission check is denied before any
+// permission is evaluated.
+const (
+	TokenReasonInvalidToken = "invalid_token"
+	TokenReasonUserNotFound = "user_not_found"
+	TokenReasonUserInactive = "user_inactive"
+)
+
+// TokenValidationResult is a validated access token. When Valid is false only
+// Reason is set.
+type TokenValidationResult struct {
+	Valid       bool
+	Reason      string
+	Subject     string
+	Issuer      string
+	Audience    string
+	ClientID    string
+	ProviderID  string
+	TokenID     string
+	Scope       string
+	Actor       string
+	IssuedAt    time.Time
+	ExpiresAt   time.Time
+	TenantUUID  *uuid.UUID
+	Roles       []string
+	Permissions []string
+}
+
+// PermissionCheckResult is the outcome of a permission check.
+type PermissionCheckResult struct {
+	Allowed bool
+	Reason  string
+}
+
+// TokenValidationService validates access tokens and checks permissions on
+// behalf of other services.
+type TokenValidationService interface {
+	ValidateToken(ctx context.Context, token string) (*TokenValidationResult, error)
+	CheckPermission(ctx context.Context, userUUID uuid.UUID, tenantUUID *uuid.UUID, permission string) (*PermissionCheckResult, error)
+}
+
+type tokenValidationService struct {
+	userRepo     repository.UserRepository
+	tenantRepo   repository.TenantRepository
+	authzAuditor middleware.AuthzAuditor
+}
+
+// NewTokenValidationService creates a new TokenValidationService. Permission
+// checks scoped to a tenant are sampled into the audit log through
+// authzAuditor.
+func NewTokenValidationService(
+	userRepo repository.UserRepository,
+	tenantRepo repository.TenantRepository,
+	authzAuditor middleware.AuthzAuditor,
+) TokenValidationService {
+	return &tokenValidationService{
+		userRepo:     userRepo,
+		tenantRepo:   tenantRepo,
+		authzAuditor: authzAuditor,
+	}
+}
+
+// ValidateToken verifies the signature, expiry, issuer and audience of an
+// access token, then resolves the user it was issued to the same way the
+// REST middleware does. A token that fails validation, or whose user is gone
+// or not active, is returned as not valid rather than as an error.
+func (s *tokenValidationService) ValidateToken(ctx context.Context, token string) (*TokenValidationResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "tokenValidation.validateToken")
+	defer span.End()
+
+	claims, err := jwt.ValidateToken(token)
+	if err != nil {
+		span.SetAttributes(attribute.String("token.reason", TokenReasonInvalidToken))
+		return &TokenValidationResult{Reason: TokenReasonInvalidToken}, nil
+	}
+
+	result := &TokenValidationResult{Valid: true}
+	result.Subject, _ = claims["sub"].(string)
+	result.Issuer, _ = claims["iss"].(string)
+	result.Audience, _ = claims["aud"].(string)
+	result.ClientID, _ = claims["client_id"].(string)
+	result.ProviderID, _ = claims["provider_id"].(string)
+	result.TokenID, _ = claims["jti"].(string)
+	result.Scope, _ = claims["scope"].(string)
+	if act, ok := claims["act"].(map[string]any); ok {
+		result.Actor, _ = act["sub"].(string)
+	}
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		result.IssuedAt = iat.Time
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		result.ExpiresAt = exp.Time
+	}
+
+	user, err := s.userRepo.FindBySubAndClientID(result.Subject, result.ClientID)
+	if err != nil {
+		span.SetStatus(codes.Error, "find user failed")
+		return nil, apperror.NewInternal("failed to load token user", err)
+	}
+	if user == nil {
+		span.SetAttributes(attribute.String("token.reason", TokenReasonUserNotFound))
+		return &TokenValidationResult{Reason: TokenReasonUserNotFound}, nil
+	}
+	if user.Status != model.StatusActive {
+		span.SetAttributes(attribute.String("token.reason", TokenReasonUserInactive))
+		return &TokenValidationResult{Reason: TokenReasonUserInactive}, nil
+	}
+
+	for _, identity := range user.UserIdentities {
+		if identity.Client != nil && identity.Client.Identifier != nil && *identity.Client.Identifier == result.ClientID && identity.Tenant != nil {
+			result.TenantUUID = &identity.Tenant.TenantUUID
+		}
+	}
+	roles, err := withGroupRoles(s.userRepo, user, "Permissions")
+	if err != nil {
+		span.SetStatus(codes.Error, "find group roles failed")
+		return nil, apperror.NewInternal("failed to load token user", err)
+	}
+	result.Roles, result.Permissions = rolesAndPermissions(roles)
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// CheckPermission reports whether the user holds permission through any of
+// their roles, including those held through groups. When tenantUUID is set only the roles of that tenant count,
+// and the decision is sampled into the tenant's audit log.
+func (s *tokenValidationService) CheckPermission(ctx context.Context, userUUID uuid.UUID, tenantUUID *uuid.UUID, permission string) (*PermissionCheckResult, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "tokenValidation.checkPermission")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("user.uuid", userUUID.String()),
+		attribute.String("permission", permission),
+	)
+
+	var tenant *model.Tenant
+	if tenantUUID != nil {
+		var err error
+		tenant, err = s.tenantRepo.FindByUUID(*tenantUUID)
+		if err != nil {
+			span.SetStatus(codes.Error, "find tenant failed")
+			return nil, apperror.NewInternal("failed to find tenant", err)
+		}
+		if tenant == nil {
+			span.SetStatus(codes.Error, "tenant not found")
+			return nil, apperror.NewNotFoundWithReason("tenant not found")
+		}
+	}
+
+	user, err := s.userRepo.FindByUUID(userUUID, "Roles.Permissions")
+	if err != nil {
+		span.SetStatus(codes.Error, "find user failed")
+		return nil, apperror.NewInternal("failed to find user", err)
+	}
+	if user == nil {
+		return &PermissionCheckResult{Reason: TokenReasonUserNotFound}, nil
+	}
+	if user.Status != model.StatusActive {
+		return &PermissionCheckResult{Reason: TokenReasonUserInactive}, nil
+	}
+
+	roles, err := withGroupRoles(s.userRepo, user, "Permissions")
+	if err != nil {
+		span.SetStatus(codes.Error, "find group roles failed")
+		return nil, apperror.NewInternal("failed to find user", err)
+	}
+
+	var evaluated []string
+	allowed := false
+	for _, role := range roles {
+		if tenant != nil && role.TenantID != tenant.TenantID {
+			continue
+		}
+		evaluated = append(evaluated, role.Name)
+		for _, p := range role.Permissions {
+			if p.Name == permission {
+				allowed = true
+			}
+		}
+	}
+
+	result := &PermissionCheckResult{Allowed: allowed, Reason: middleware.AuthzReasonNoMatchingPermission}
+	var matched string
+	if allowed {
+		result.Reason = middleware.AuthzReasonPermissionGranted
+		matched = permission
+	}
+	if tenant != nil && s.authzAuditor != nil {
+		s.authzAuditor.Record(ctx, middleware.AuthzDecision{
+			TenantID:            tenant.TenantID,
+			ActorUserID:         user.UserID,
+			Transport:           middleware.AuthzTransportGRPC,
+			Resource:            "CheckPermission",
+			Allowed:             allowed,
+			Reason:              result.Reason,
+			RequiredPermissions: []string{permission},
+			MatchedPermission:   matched,
+			EvaluatedRoles:      evaluated,
+		})
+	}
+
+	span.SetAttributes(attribute.Bool("authz.allowed", allowed))
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// rolesAndPermissions returns the sorted role names and the sorted, distinct
+// permission names granted by roles.
+func rolesAndPermissions(roles []model.Role) ([]string, []string) {
+	names := make([]string, 0, len(roles))
+	seen := map[string]bool{}
+	var perms []string
+	for _, role := range roles {
+		names = append(names, role.Name)
+		for _, p := range role.Permissions {
+			if !seen[p.Name] {
+				seen[p.Name] = true
+				perms = append(perms, p.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	sort.Strings(perms)
+	return names, perms
+}
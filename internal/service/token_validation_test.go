@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/jwt"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingAuthzAuditor struct {
+	decisions []middleware.AuthzDecision
+}
+
+func (a *recordingAuthzAuditor) Record(_ context.Context, d middleware.AuthzDecision) {
+	a.decisions = append(a.decisions, d)
+}
+
+func tokenTestUser(status string) *model.User {
+	return &model.User{
+		UserID:   9,
+		UserUUID: uuid.New(),
+		Status:   status,
+		Roles: []model.Role{
+			{TenantID: 1, Name: "editor", Permissions: []model.Permission{{Name: "post:write"}, {Name: "post:read"}}},
+			{TenantID: 2, Name: "viewer", Permissions: []model.Permission{{Name: "post:read"}}},
+		},
+	}
+}
+
+func TestTokenValidationService_ValidateToken(t *testing.T) {
+	initTestJWTKeysService(t)
+	ctx := context.Background()
+	user := tokenTestUser(model.StatusActive)
+	tenantUUID := uuid.New()
+	user.UserIdentities = []model.UserIdentity{{
+		Client: &model.Client{Identifier: strPtr("web")},
+		Tenant: &model.Tenant{TenantUUID: tenantUUID},
+	}}
+	token, err := jwt.GenerateAccessToken(user.UserUUID.String(), "openid profile", "https://auth.example.com", "api", "web", "idp")
+	require.NoError(t, err)
+
+	t.Run("valid token", func(t *testing.T) {
+		svc := NewTokenValidationService(&mockUserRepo{
+			findBySubAndClientIDFn: func(sub, clientID string) (*model.User, error) {
+				assert.Equal(t, user.UserUUID.String(), sub)
+				assert.Equal(t, "web", clientID)
+				return user, nil
+			},
+		}, &mockTenantRepo{}, nil)
+
+		result, err := svc.ValidateToken(ctx, token)
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, user.UserUUID.String(), result.Subject)
+		assert.Equal(t, "openid profile", result.Scope)
+		assert.Equal(t, "idp", result.ProviderID)
+		assert.NotEmpty(t, result.TokenID)
+		assert.True(t, result.ExpiresAt.After(result.IssuedAt))
+		require.NotNil(t, result.TenantUUID)
+		assert.Equal(t, tenantUUID, *result.TenantUUID)
+		assert.Equal(t, []string{"editor", "viewer"}, result.Roles)
+		assert.Equal(t, []string{"post:read", "post:write"}, result.Permissions)
+	})
+
+	t.Run("malformed token", func(t *testing.T) {
+		svc := NewTokenValidationService(&mockUserRepo{}, &mockTenantRepo{}, nil)
+		result, err := svc.ValidateToken(ctx, "not-a-jwt")
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, TokenReasonInvalidToken, result.Reason)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		svc := NewTokenValidationService(&mockUserRepo{}, &mockTenantRepo{}, nil)
+		result, err := svc.ValidateToken(ctx, token)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, TokenReasonUserNotFound, result.Reason)
+		assert.Empty(t, result.Subject)
+	})
+
+	t.Run("user suspended", func(t *testing.T) {
+		svc := NewTokenValidationService(&mockUserRepo{
+			findBySubAndClientIDFn: func(string, string) (*model.User, error) { return tokenTestUser(model.StatusSuspended), nil },
+		}, &mockTenantRepo{}, nil)
+		result, err := svc.ValidateToken(ctx, token)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, TokenReasonUserInactive, result.Reason)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		svc := NewTokenValidationService(&mockUserRepo{
+			findBySubAndClientIDFn: func(string, string) (*model.User, error) { return nil, errors.New("db down") },
+		}, &mockTenantRepo{}, nil)
+		_, err := svc.ValidateToken(ctx, token)
+		var internalErr *apperror.InternalError
+		assert.ErrorAs(t, err, &internalErr)
+	})
+}
+
+func TestTokenValidationService_CheckPermission(t *testing.T) {
+	ctx := context.Background()
+	user := tokenTestUser(model.StatusActive)
+	userRepo := &mockUserRepo{findByUUIDFn: func(id any, preloads ...string) (*model.User, error) {
+		assert.Equal(t, user.UserUUID, id)
+		assert.Equal(t, []string{"Roles.Permissions"}, preloads)
+		return user, nil
+	}}
+	tenantUUID := uuid.New()
+	tenantRepo := &mockTenantRepo{findByUUIDFn: func(id any, _ ...string) (*model.Tenant, error) {
+		if id == tenantUUID {
+			return &model.Tenant{TenantID: 2, TenantUUID: tenantUUID}, nil
+		}
+		return nil, nil
+	}}
+
+	t.Run("any tenant", func(t *testing.T) {
+		auditor := &recordingAuthzAuditor{}
+		svc := NewTokenValidationService(userRepo, tenantRepo, auditor)
+
+		result, err := svc.CheckPermission(ctx, user.UserUUID, nil, "post:write")
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, middleware.AuthzReasonPermissionGranted, result.Reason)
+		assert.Empty(t, auditor.decisions, "decisions without a tenant are not audited")
+	})
+
+	t.Run("scoped to a tenant", func(t *testing.T) {
+		auditor := &recordingAuthzAuditor{}
+		svc := NewTokenValidationService(userRepo, tenantRepo, auditor)
+
+		result, err := svc.CheckPermission(ctx, user.UserUUID, &tenantUUID, "post:write")
+		require.NoError(t, err)
+		assert.False(t, result.Allowed, "post:write is granted in another tenant only")
+		assert.Equal(t, middleware.AuthzReasonNoMatchingPermission, result.Reason)
+
+		require.Len(t, auditor.decisions, 1)
+		d := auditor.decisions[0]
+		assert.Equal(t, int64(2), d.TenantID)
+		assert.Equal(t, middleware.AuthzTransportGRPC, d.Transport)
+		assert.Equal(t, []string{"viewer"}, d.EvaluatedRoles)
+	})
+
+	t.Run("role held through a group", func(t *testing.T) {
+		groupRepo := &mockUserRepo{
+			findByUUIDFn: userRepo.findByUUIDFn,
+			findGroupRolesFn: func(userID int64) ([]model.Role, error) {
+				assert.Equal(t, user.UserID, userID)
+				return []model.Role{{RoleID: 5, TenantID: 2, Name: "author", Permissions: []model.Permission{{Name: "post:write"}}}}, nil
+			},
+		}
+		auditor := &recordingAuthzAuditor{}
+		svc := NewTokenValidationService(groupRepo, tenantRepo, auditor)
+
+		result, err := svc.CheckPermission(ctx, user.UserUUID, &tenantUUID, "post:write")
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		require.Len(t, auditor.decisions, 1)
+		assert.Equal(t, []string{"viewer", "author"}, auditor.decisions[0].EvaluatedRoles)
+	})
+
+	t.Run("unknown tenant", func(t *testing.T) {
+		svc := NewTokenValidationService(userRepo, tenantRepo, nil)
+		other := uuid.New()
+		_, err := svc.CheckPermission(ctx, user.UserUUID, &other, "post:read")
+		var notFound *apperror.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		svc := NewTokenValidationService(&mockUserRepo{}, tenantRepo, nil)
+		result, err := svc.CheckPermission(ctx, uuid.New(), nil, "post:read")
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, TokenReasonUserNotFound, result.Reason)
+	})
+
+	t.Run("user inactive", func(t *testing.T) {
+		svc := NewTokenValidationService(&mockUserRepo{
+			findByUUIDFn: func(any, ...string) (*model.User, error) { return tokenTestUser(model.StatusInactive), nil },
+		}, tenantRepo, nil)
+		result, err := svc.CheckPermission(ctx, uuid.New(), nil, "post:read")
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, TokenReasonUserInactive, result.Reason)
+	})
+}
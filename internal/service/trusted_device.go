@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// MaxTrustedDevicePeriodDays caps how long a tenant may let users remember
+// a device.
+const MaxTrustedDevicePeriodDays = 365
+
+// TrustedDeviceService remembers the devices users passed a second factor
+// on, so logins from them can skip step-up authentication.
+type TrustedDeviceService interface {
+	// Trust remembers the calling device, identified by the client IP and
+	// user agent in ctx, for user in tenantID. The device token is only
+	// ever returned here. It fails with an *apperror.ForbiddenError when
+	// the tenant does not let users remember devices.
+	Trust(ctx context.Context, user *model.User, tenantID int64, req dto.TrustedDeviceCreateRequestDTO) (*dto.TrustedDeviceCreateResponseDTO, error)
+
+	// List returns the user's active trusted devices, most recently used
+	// first. The device whose token is in ctx is marked current.
+	List(ctx context.Context, userID int64) ([]dto.TrustedDeviceResponseDTO, error)
+
+	// Revoke forgets one of the user's devices. Revoking a revoked device
+	// is a no-op.
+	Revoke(ctx context.Context, deviceUUID uuid.UUID, userID int64) error
+
+	// RevokeAll forgets every device of the user and returns how many
+	// there were.
+	RevokeAll(ctx context.Context, userID int64) (int64, error)
+
+	// IsTrusted reports whether token belongs to an active trusted device
+	// of the user in tenantID, and records that it was used. Lookup
+	// failures count as untrusted.
+	IsTrusted(ctx context.Context, userID, tenantID int64, token string) bool
+}
+
+type trustedDeviceService struct {
+	trustedDeviceRepo   repository.TrustedDeviceRepository
+	securitySettingRepo repository.SecuritySettingRepository
+	authEventService    AuthEventService
+}
+
+// NewTrustedDeviceService creates a new TrustedDeviceService.
+func NewTrustedDeviceService(
+	trustedDeviceRepo repository.TrustedDeviceRepository,
+	securitySettingRepo repository.SecuritySettingRepository,
+	authEventService AuthEventService,
+) TrustedDeviceService {
+	return &trustedDeviceService{
+		trustedDeviceRepo:   trustedDeviceRepo,
+		securitySettingRepo: securitySettingRepo,
+		authEventService:    authEventService,
+	}
+}
+
+// generateTrustedDeviceToken returns a new device token with its hash.
+func generateTrustedDeviceToken() (string, string) {
+	bytes := make([]byte, 32)
+	_, _ = rand.Read(bytes)
+
+	token := model.TrustedDevicePrefix + hex.EncodeToString(bytes)
+	return token, hashTrustedDeviceToken(token)
+}
+
+// hashTrustedDeviceToken returns the hex SHA-256 of a device token, as
+// stored in TokenHash.
+func hashTrustedDeviceToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// Trust implements TrustedDeviceService.
+func (s *trustedDeviceService) Trust(ctx context.Context, user *model.User, tenantID int64, req dto.TrustedDeviceCreateRequestDTO) (*dto.TrustedDeviceCreateResponseDTO, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "trusted_device.trust")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int64("user.id", user.UserID),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	period, err := s.trustPeriod(tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "load mfa settings failed")
+		return nil, err
+	}
+	if period <= 0 {
+		span.SetStatus(codes.Error, "trusted devices disabled")
+		return nil, apperror.NewForbidden("remembering devices is not enabled for this tenant")
+	}
+
+	token, tokenHash := generateTrustedDeviceToken()
+	created, err := s.trustedDeviceRepo.Create(&model.TrustedDevice{
+		TenantID:  tenantID,
+		UserID:    user.UserID,
+		Name:      strings.TrimSpace(req.Name),
+		TokenHash: tokenHash,
+		UserAgent: ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		IPAddress: ptr.PtrOrNil(middleware.ClientIPFromContext(ctx)),
+		ExpiresAt: time.Now().Add(period),
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "create trusted device failed")
+		return nil, apperror.NewInternal("failed to trust device", err)
+	}
+	span.SetAttributes(attribute.String("trusted_device.uuid", created.TrustedDeviceUUID.String()))
+
+	s.logEvent(ctx, user.UserID, tenantID, model.AuthEventTypeDeviceTrusted, "Device remembered for step-up authentication")
+
+	result := toTrustedDeviceResponseDTO(created, tokenHash)
+	span.SetStatus(codes.Ok, "")
+	return &dto.TrustedDeviceCreateResponseDTO{TrustedDeviceResponseDTO: result, Token: token}, nil
+}
+
+// List implements TrustedDeviceService.
+func (s *trustedDeviceService) List(ctx context.Context, userID int64) ([]dto.TrustedDeviceResponseDTO, error) {
+	_, span := otel.Tracer("service").Start(ctx, "trusted_device.list")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("user.id", userID))
+
+	devices, err := s.trustedDeviceRepo.FindActiveByUserID(userID, time.Now())
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "trusted devices lookup failed")
+		return nil, apperror.NewInternal("failed to retrieve trusted devices", err)
+	}
+
+	var currentHash string
+	if token := middleware.TrustedDeviceTokenFromContext(ctx); token != "" {
+		currentHash = hashTrustedDeviceToken(token)
+	}
+	result := make([]dto.TrustedDeviceResponseDTO, len(devices))
+	for i := range devices {
+		result[i] = toTrustedDeviceResponseDTO(&devices[i], currentHash)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// Revoke implements TrustedDeviceService.
+func (s *trustedDeviceService) Revoke(ctx context.Context, deviceUUID uuid.UUID, userID int64) error {
+	ctx, span := otel.Tracer("service").Start(ctx, "trusted_device.revoke")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("trusted_device.uuid", deviceUUID.String()),
+		attribute.Int64("user.id", userID),
+	)
+
+	device, err := s.trustedDeviceRepo.FindByUUIDAndUserID(deviceUUID.String(), userID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "trusted device lookup failed")
+		return apperror.NewInternal("failed to revoke trusted device", err)
+	}
+	if device == nil {
+		span.SetStatus(codes.Error, "trusted device not found or not owned by user")
+		return apperror.NewNotFoundWithReason("trusted device not found")
+	}
+	if device.RevokedAt != nil {
+		span.SetStatus(codes.Ok, "")
+		return nil
+	}
+
+	if err := s.trustedDeviceRepo.Revoke(device.TrustedDeviceID, time.Now()); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "revoke trusted device failed")
+		return apperror.NewInternal("failed to revoke trusted device", err)
+	}
+
+	s.logEvent(ctx, userID, device.TenantID, model.AuthEventTypeDeviceRevoked, "Trusted device forgotten")
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// RevokeAll implements TrustedDeviceService.
+func (s *trustedDeviceService) RevokeAll(ctx context.Context, userID int64) (int64, error) {
+	_, span := otel.Tracer("service").Start(ctx, "trusted_device.revoke_all")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("user.id", userID))
+
+	revoked, err := s.trustedDeviceRepo.RevokeByUserID(userID, time.Now())
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "revoke trusted devices failed")
+		return 0, apperror.NewInternal("failed to revoke trusted devices", err)
+	}
+	span.SetAttributes(attribute.Int64("trusted_device.revoked", revoked))
+
+	span.SetStatus(codes.Ok, "")
+	return revoked, nil
+}
+
+// IsTrusted implements TrustedDeviceService.
+func (s *trustedDeviceService) IsTrusted(ctx context.Context, userID, tenantID int64, token string) bool {
+	_, span := otel.Tracer("service").Start(ctx, "trusted_device.is_trusted")
+	defer span.End()
+
+	if !strings.HasPrefix(token, model.TrustedDevicePrefix) {
+		span.SetStatus(codes.Ok, "")
+		return false
+	}
+
+	device, err := s.trustedDeviceRepo.FindByTokenHash(hashTrustedDeviceToken(token))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "trusted device lookup failed")
+		return false
+	}
+	now := time.Now()
+	if device == nil || device.UserID != userID || device.TenantID != tenantID || !device.IsActive(now) {
+		span.SetStatus(codes.Ok, "")
+		return false
+	}
+	span.SetAttributes(attribute.String("trusted_device.uuid", device.TrustedDeviceUUID.String()))
+
+	// A failed touch only loses last-used precision; the device is trusted.
+	if err := s.trustedDeviceRepo.TouchLastUsed(device.TrustedDeviceID, now); err != nil {
+		span.RecordError(err)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return true
+}
+
+// trustPeriod returns how long the tenant lets users remember a device, or 0
+// when it does not.
+func (s *trustedDeviceService) trustPeriod(tenantID int64) (time.Duration, error) {
+	setting, err := s.securitySettingRepo.FindByUserPoolID(tenantID)
+	if err != nil {
+		return 0, apperror.NewInternal("failed to load security settings", err)
+	}
+	if setting == nil {
+		return 0, nil
+	}
+	days, _ := unmarshalJSON(setting.MFAConfig)[model.MFAConfigTrustedDevicePeriodDays].(float64)
+	days = min(days, MaxTrustedDevicePeriodDays)
+	if days <= 0 {
+		return 0, nil
+	}
+	return time.Duration(days * float64(24*time.Hour)), nil
+}
+
+func (s *trustedDeviceService) logEvent(ctx context.Context, userID, tenantID int64, eventType, description string) {
+	s.authEventService.Log(ctx, AuthEventInput{
+		TenantID:    tenantID,
+		ActorUserID: &userID,
+		IPAddress:   middleware.ClientIPFromContext(ctx),
+		UserAgent:   ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:    model.AuthEventCategoryAuthn,
+		EventType:   eventType,
+		Severity:    model.AuthEventSeverityInfo,
+		Result:      model.AuthEventResultSuccess,
+		Description: ptr.Ptr(description),
+	})
+}
+
+// toTrustedDeviceResponseDTO describes a device without its token hash. It
+// is the current device when its hash is currentHash.
+func toTrustedDeviceResponseDTO(device *model.TrustedDevice, currentHash string) dto.TrustedDeviceResponseDTO {
+	return dto.TrustedDeviceResponseDTO{
+		TrustedDeviceID: device.TrustedDeviceUUID.String(),
+		Name:            device.Name,
+		UserAgent:       device.UserAgent,
+		IPAddress:       device.IPAddress,
+		Current:         currentHash != "" && device.TokenHash == currentHash,
+		ExpiresAt:       device.ExpiresAt,
+		LastUsedAt:      device.LastUsedAt,
+		CreatedAt:       device.CreatedAt,
+	}
+}
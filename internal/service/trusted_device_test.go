@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/dto"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+// trustedDeviceSettings returns security settings with the MFA config.
+func trustedDeviceSettings(mfaConfig string) *mockSecuritySettingRepo {
+	return &mockSecuritySettingRepo{
+		findByUserPoolIDFn: func(int64) (*model.SecuritySetting, error) {
+			return &model.SecuritySetting{MFAConfig: datatypes.JSON(mfaConfig)}, nil
+		},
+	}
+}
+
+func TestTrustedDeviceService_Trust(t *testing.T) {
+	user := &model.User{UserID: 7}
+
+	t.Run("remembers the device with a hashed token", func(t *testing.T) {
+		var saved *model.TrustedDevice
+		var events []AuthEventInput
+		svc := NewTrustedDeviceService(&mockTrustedDeviceRepo{
+			createFn: func(d *model.TrustedDevice) (*model.TrustedDevice, error) {
+				d.TrustedDeviceUUID = uuid.New()
+				saved = d
+				return d, nil
+			},
+		}, trustedDeviceSettings(`{"trusted_device_period_days": 30}`), &mockAuthEventService{
+			logFn: func(_ context.Context, in AuthEventInput) { events = append(events, in) },
+		})
+
+		res, err := svc.Trust(loginContext("198.51.100.9", "Mozilla/5.0"), user, 1, dto.TrustedDeviceCreateRequestDTO{Name: " laptop "})
+		require.NoError(t, err)
+		require.NotNil(t, saved)
+
+		assert.True(t, strings.HasPrefix(res.Token, model.TrustedDevicePrefix))
+		assert.Equal(t, hashTrustedDeviceToken(res.Token), saved.TokenHash)
+		assert.Equal(t, "laptop", saved.Name)
+		assert.Equal(t, int64(1), saved.TenantID)
+		assert.Equal(t, "198.51.100.9", *saved.IPAddress)
+		assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), saved.ExpiresAt, time.Minute)
+		assert.True(t, res.Current)
+		require.Len(t, events, 1)
+		assert.Equal(t, model.AuthEventTypeDeviceTrusted, events[0].EventType)
+	})
+
+	t.Run("caps the period", func(t *testing.T) {
+		var saved *model.TrustedDevice
+		svc := NewTrustedDeviceService(&mockTrustedDeviceRepo{
+			createFn: func(d *model.TrustedDevice) (*model.TrustedDevice, error) {
+				saved = d
+				return d, nil
+			},
+		}, trustedDeviceSettings(`{"trusted_device_period_days": 5000}`), &mockAuthEventService{})
+
+		_, err := svc.Trust(context.Background(), user, 1, dto.TrustedDeviceCreateRequestDTO{})
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(MaxTrustedDevicePeriodDays*24*time.Hour), saved.ExpiresAt, time.Minute)
+	})
+
+	t.Run("disabled for the tenant", func(t *testing.T) {
+		svc := NewTrustedDeviceService(&mockTrustedDeviceRepo{
+			createFn: func(*model.TrustedDevice) (*model.TrustedDevice, error) {
+				t.Fatal("device must not be trusted")
+				return nil, nil
+			},
+		}, trustedDeviceSettings(`{}`), &mockAuthEventService{})
+
+		_, err := svc.Trust(context.Background(), user, 1, dto.TrustedDeviceCreateRequestDTO{})
+		var want *apperror.ForbiddenError
+		require.ErrorAs(t, err, &want)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		svc := NewTrustedDeviceService(&mockTrustedDeviceRepo{
+			createFn: func(*model.TrustedDevice) (*model.TrustedDevice, error) {
+				return nil, errors.New("db down")
+			},
+		}, trustedDeviceSettings(`{"trusted_device_period_days": 30}`), &mockAuthEventService{})
+
+		_, err := svc.Trust(context.Background(), user, 1, dto.TrustedDeviceCreateRequestDTO{})
+		var want *apperror.InternalError
+		require.ErrorAs(t, err, &want)
+	})
+}
+
+func TestTrustedDeviceService_List(t *testing.T) {
+	t.Run("marks the current device", func(t *testing.T) {
+		svc := NewTrustedDeviceService(&mockTrustedDeviceRepo{
+			findActiveByUserIDFn: func(uid int64, _ time.Time) ([]model.TrustedDevice, error) {
+				assert.Equal(t, int64(7), uid)
+				return []model.TrustedDevice{
+					{TrustedDeviceUUID: uuid.New(), Name: "phone", TokenHash: hashTrustedDeviceToken("td_phone")},
+					{TrustedDeviceUUID: uuid.New(), Name: "laptop", TokenHash: hashTrustedDeviceToken("td_laptop")},
+				}, nil
+			},
+		}, &mockSecuritySettingRepo{}, &mockAuthEventService{})
+
+		ctx := context.WithValue(context.Background(), middleware.TrustedDeviceKey, "td_laptop")
+		res, err := svc.List(ctx, 7)
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		assert.False(t, res[0].Current)
+		assert.True(t, res[1].Current)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		svc := NewTrustedDeviceService(&mockTrustedDeviceRepo{
+			findActiveByUserIDFn: func(int64, time.Time) ([]model.TrustedDevice, error) {
+				return nil, errors.New("db down")
+			},
+		}, &mockSecuritySettingRepo{}, &mockAuthEventService{})
+
+		_, err := svc.List(context.Background(), 7)
+		var want *apperror.InternalError
+		require.ErrorAs(t, err, &want)
+	})
+}
+
+func TestTrustedDeviceService_Revoke(t *testing.T) {
+	ctx := context.Background()
+	deviceUUID := uuid.New()
+
+	t.Run("revokes an owned device", func(t *testing.T) {
+		var revokedID int64
+		var events []AuthEventInput
+		svc := NewTrustedDeviceService(&mockTrustedDeviceRepo{
+			findByUUIDAndUserIDFn: func(id string, uid int64) (*model.TrustedDevice, error) {
+				assert.Equal(t, deviceUUID.String(), id)
+				assert.Equal(t, int64(7), uid)
+				return &model.TrustedDevice{TrustedDeviceID: 3, TenantID: 1}, nil
+			},
+			revokeFn: func(id int64, _ time.Time) error {
+				revokedID = id
+				return nil
+			},
+		}, &mockSecuritySettingRepo{}, &mockAuthEventService{
+			logFn: func(_ context.Context, in AuthEventInput) { events = append(events, in) },
+		})
+
+		require.NoError(t, svc.Revoke(ctx, deviceUUID, 7))
+		assert.Equal(t, int64(3), revokedID)
+		require.Len(t, events, 1)
+		assert.Equal(t, model.AuthEventTypeDeviceRevoked, events[0].EventType)
+	})
+
+	t.Run("already revoked is a no-op", func(t *testing.T) {
+		revokedAt := time.Now().Add(-time.Hour)
+		svc := NewTrustedDeviceService(&mockTrustedDeviceRepo{
+			findByUUIDAndUserIDFn: func(string, int64) (*model.TrustedDevice, error) {
+				return &model.TrustedDevice{TrustedDeviceID: 3, RevokedAt: &revokedAt}, nil
+			},
+			revokeFn: func(int64, time.Time) error {
+				t.Fatal("revoke must not be called")
+				return nil
+			},
+		}, &mockSecuritySettingRepo{}, &mockAuthEventService{})
+
+		require.NoError(t, svc.Revoke(ctx, deviceUUID, 7))
+	})
+
+	t.Run("device of another user is not found", func(t *testing.T) {
+		svc := NewTrustedDeviceService(&mockTrustedDeviceRepo{}, &mockSecuritySettingRepo{}, &mockAuthEventService{})
+
+		err := svc.Revoke(ctx, deviceUUID, 7)
+		var want *apperror.NotFoundError
+		require.ErrorAs(t, err, &want)
+	})
+}
+
+func TestTrustedDeviceService_RevokeAll(t *testing.T) {
+	svc := NewTrustedDeviceService(&mockTrustedDeviceRepo{
+		revokeByUserIDFn: func(uid int64, _ time.Time) (int64, error) {
+			assert.Equal(t, int64(7), uid)
+			return 2, nil
+		},
+	}, &mockSecuritySettingRepo{}, &mockAuthEventService{})
+
+	revoked, err := svc.RevokeAll(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), revoked)
+}
+
+func TestTrustedDeviceService_IsTrusted(t *testing.T) {
+	ctx := context.Background()
+	past := time.Now().Add(-time.Hour)
+	active := model.TrustedDevice{TrustedDeviceID: 3, UserID: 7, TenantID: 1, ExpiresAt: time.Now().Add(time.Hour)}
+
+	tests := []struct {
+		name   string
+		token  string
+		device *model.TrustedDevice
+		err    error
+		want   bool
+	}{
+		{name: "active device", token: "td_phone", device: &active, want: true},
+		{name: "not a device token", token: "pat_phone", device: &active},
+		{name: "unknown token", token: "td_phone"},
+		{name: "device of another user", token: "td_phone", device: &model.TrustedDevice{UserID: 8, TenantID: 1, ExpiresAt: active.ExpiresAt}},
+		{name: "device in another tenant", token: "td_phone", device: &model.TrustedDevice{UserID: 7, TenantID: 2, ExpiresAt: active.ExpiresAt}},
+		{name: "expired device", token: "td_phone", device: &model.TrustedDevice{UserID: 7, TenantID: 1, ExpiresAt: past}},
+		{name: "revoked device", token: "td_phone", device: &model.TrustedDevice{UserID: 7, TenantID: 1, ExpiresAt: active.ExpiresAt, RevokedAt: &past}},
+		{name: "repository error", token: "td_phone", err: errors.New("db down")},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var touched bool
+			svc := NewTrustedDeviceService(&mockTrustedDeviceRepo{
+				findByTokenHashFn: func(hash string) (*model.TrustedDevice, error) {
+					assert.Equal(t, hashTrustedDeviceToken(tc.token), hash)
+					return tc.device, tc.err
+				},
+				touchLastUsedFn: func(int64, time.Time) error {
+					touched = true
+					return nil
+				},
+			}, &mockSecuritySettingRepo{}, &mockAuthEventService{})
+
+			assert.Equal(t, tc.want, svc.IsTrusted(ctx, 7, 1, tc.token))
+			assert.Equal(t, tc.want, touched)
+		})
+	}
+}
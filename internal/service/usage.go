@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// usageQuotaTTL is how long the quotas of a tenant are kept in memory, so
+// that metering does not read tenant settings on every request. Changed
+// quotas take effect within this time.
+const usageQuotaTTL = 30 * time.Second
+
+// UsageServiceQuotas are the monthly quotas of a tenant. 0 is unlimited.
+type UsageServiceQuotas struct {
+	MonthlyRequests       int64
+	MonthlyTokens         int64
+	APIKeyMonthlyRequests int64
+}
+
+// UsageServiceDay is the usage of a tenant on a day (UTC).
+type UsageServiceDay struct {
+	Date         time.Time
+	Requests     int64
+	TokensIssued int64
+}
+
+// UsageServiceAPIKey is the usage of a tenant made with one API key. The
+// key's UUID and name are empty when it has since been deleted.
+type UsageServiceAPIKey struct {
+	APIKeyUUID *uuid.UUID
+	Name       string
+	Requests   int64
+}
+
+// UsageServicePeriod is the live usage of a tenant in the current calendar
+// month (UTC), as counted against its quotas.
+type UsageServicePeriod struct {
+	Start        time.Time
+	End          time.Time
+	Requests     int64
+	TokensIssued int64
+}
+
+// UsageServiceReport is the usage of a tenant from From to To, both
+// inclusive. Recorded usage trails live metering by up to a minute.
+type UsageServiceReport struct {
+	TenantUUID    uuid.UUID
+	From          time.Time
+	To            time.Time
+	Requests      int64
+	TokensIssued  int64
+	Days          []UsageServiceDay
+	APIKeys       []UsageServiceAPIKey
+	Quotas        UsageServiceQuotas
+	CurrentPeriod UsageServicePeriod
+}
+
+// UsageService meters the requests and issued access tokens of tenants
+// against their monthly quotas and reports their usage.
+type UsageService interface {
+	middleware.UsageMeter
+	// GetUsage reports the usage of the tenant from from to to, both
+	// inclusive days (UTC).
+	GetUsage(ctx context.Context, tenantUUID uuid.UUID, from, to time.Time) (*UsageServiceReport, error)
+	// FlushUsage writes the usage counted in Redis to the database and
+	// returns the number of rows updated.
+	FlushUsage(ctx context.Context) (int64, error)
+}
+
+type usageService struct {
+	store             cache.UsageStore
+	tenantUsageRepo   repository.TenantUsageRepository
+	tenantSettingRepo repository.TenantSettingRepository
+	tenantRepo        repository.TenantRepository
+	now               func() time.Time
+
+	mu     sync.Mutex
+	quotas map[int64]cachedUsageQuotas
+}
+
+// cachedUsageQuotas are the quotas of a tenant read at some point, kept
+// until expiresAt.
+type cachedUsageQuotas struct {
+	quotas    UsageServiceQuotas
+	expiresAt time.Time
+}
+
+// NewUsageService creates a new UsageService. With a nil store nothing is
+// metered and every request is within quota.
+func NewUsageService(
+	store cache.UsageStore,
+	tenantUsageRepo repository.TenantUsageRepository,
+	tenantSettingRepo repository.TenantSettingRepository,
+	tenantRepo repository.TenantRepository,
+) UsageService {
+	return &usageService{
+		store:             store,
+		tenantUsageRepo:   tenantUsageRepo,
+		tenantSettingRepo: tenantSettingRepo,
+		tenantRepo:        tenantRepo,
+		now:               time.Now,
+		quotas:            make(map[int64]cachedUsageQuotas),
+	}
+}
+
+// MeterRequest implements middleware.UsageMeter.
+func (s *usageService) MeterRequest(ctx context.Context, tenantID, apiKeyID int64) (bool, error) {
+	if s.store == nil {
+		return true, nil
+	}
+	quotas, err := s.tenantQuotas(tenantID)
+	if err != nil {
+		return false, err
+	}
+	var apiKeyQuota int64
+	if apiKeyID != 0 {
+		apiKeyQuota = quotas.APIKeyMonthlyRequests
+	}
+	return s.store.CountUsage(ctx, tenantID, apiKeyID, cache.UsageMetricRequests, quotas.MonthlyRequests, apiKeyQuota, s.now())
+}
+
+// MeterTokenIssued implements middleware.UsageMeter.
+func (s *usageService) MeterTokenIssued(ctx context.Context, tenantID int64) (bool, error) {
+	if s.store == nil {
+		return true, nil
+	}
+	quotas, err := s.tenantQuotas(tenantID)
+	if err != nil {
+		return false, err
+	}
+	return s.store.CountUsage(ctx, tenantID, 0, cache.UsageMetricTokensIssued, quotas.MonthlyTokens, 0, s.now())
+}
+
+func (s *usageService) GetUsage(ctx context.Context, tenantUUID uuid.UUID, from, to time.Time) (*UsageServiceReport, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "usage.getUsage")
+	defer span.End()
+	span.SetAttributes(attribute.String("tenant.uuid", tenantUUID.String()))
+
+	tenant, err := s.tenantRepo.FindByUUID(tenantUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find tenant failed")
+		return nil, apperror.NewInternal("failed to find tenant", err)
+	}
+	if tenant == nil {
+		span.SetStatus(codes.Error, "tenant not found")
+		return nil, apperror.NewNotFound("tenant")
+	}
+
+	rows, err := s.tenantUsageRepo.FindByTenantIDAndDateRange(tenant.TenantID, from, to)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find usage failed")
+		return nil, apperror.NewInternal("failed to load tenant usage", err)
+	}
+
+	quotas, err := s.tenantQuotas(tenant.TenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "load quotas failed")
+		return nil, err
+	}
+
+	report := &UsageServiceReport{
+		TenantUUID: tenant.TenantUUID,
+		From:       from,
+		To:         to,
+		Days:       []UsageServiceDay{},
+		APIKeys:    []UsageServiceAPIKey{},
+		Quotas:     quotas,
+	}
+
+	days := make(map[time.Time]int)
+	apiKeys := make(map[int64]int)
+	for _, row := range rows {
+		day, ok := days[row.UsageDate]
+		if !ok {
+			day = len(report.Days)
+			days[row.UsageDate] = day
+			report.Days = append(report.Days, UsageServiceDay{Date: row.UsageDate})
+		}
+
+		switch row.Metric {
+		case cache.UsageMetricRequests:
+			report.Requests += row.Count
+			report.Days[day].Requests += row.Count
+		case cache.UsageMetricTokensIssued:
+			report.TokensIssued += row.Count
+			report.Days[day].TokensIssued += row.Count
+		}
+
+		if row.APIKeyID == 0 || row.Metric != cache.UsageMetricRequests {
+			continue
+		}
+		key, ok := apiKeys[row.APIKeyID]
+		if !ok {
+			key = len(report.APIKeys)
+			apiKeys[row.APIKeyID] = key
+			entry := UsageServiceAPIKey{}
+			if row.APIKey != nil {
+				entry.APIKeyUUID = &row.APIKey.APIKeyUUID
+				entry.Name = row.APIKey.Name
+			}
+			report.APIKeys = append(report.APIKeys, entry)
+		}
+		report.APIKeys[key].Requests += row.Count
+	}
+
+	now := s.now().UTC()
+	report.CurrentPeriod.Start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	report.CurrentPeriod.End = now.Add(middleware.UntilQuotaReset(now))
+	if s.store != nil {
+		// Live counts are informative; a Redis outage leaves them at 0
+		// rather than failing the report.
+		if n, err := s.store.PeriodUsage(ctx, tenant.TenantID, cache.UsageMetricRequests, now); err == nil {
+			report.CurrentPeriod.Requests = n
+		} else {
+			span.RecordError(err)
+		}
+		if n, err := s.store.PeriodUsage(ctx, tenant.TenantID, cache.UsageMetricTokensIssued, now); err == nil {
+			report.CurrentPeriod.TokensIssued = n
+		} else {
+			span.RecordError(err)
+		}
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return report, nil
+}
+
+func (s *usageService) FlushUsage(ctx context.Context) (int64, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "usage.flush_usage")
+	defer span.End()
+
+	if s.store == nil {
+		span.SetStatus(codes.Ok, "")
+		return 0, nil
+	}
+
+	usage, err := s.store.TakeUsage(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "take usage failed")
+		return 0, err
+	}
+
+	var flushed int64
+	for i, u := range usage {
+		err := s.tenantUsageRepo.AddUsage(&model.TenantUsage{
+			TenantID:  u.TenantID,
+			APIKeyID:  u.APIKeyID,
+			UsageDate: u.Day,
+			Metric:    u.Metric,
+			Count:     u.Count,
+		})
+		if err != nil {
+			// Put back what has not been written so the next flush retries it.
+			if restoreErr := s.store.RestoreUsage(ctx, usage[i:]); restoreErr != nil {
+				span.RecordError(restoreErr)
+			}
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "add usage failed")
+			return flushed, err
+		}
+		flushed++
+	}
+
+	span.SetAttributes(attribute.Int64("usage.flushed", flushed))
+	span.SetStatus(codes.Ok, "")
+	return flushed, nil
+}
+
+// tenantQuotas returns the quotas of the tenant, from memory when they were
+// read less than usageQuotaTTL ago.
+func (s *usageService) tenantQuotas(tenantID int64) (UsageServiceQuotas, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	cached, ok := s.quotas[tenantID]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.quotas, nil
+	}
+
+	setting, err := s.tenantSettingRepo.FindByTenantID(tenantID)
+	if err != nil {
+		return UsageServiceQuotas{}, apperror.NewInternal("failed to load tenant settings", err)
+	}
+	var quotas UsageServiceQuotas
+	if setting != nil {
+		config := unmarshalJSON(setting.RateLimitConfig)
+		quotas = UsageServiceQuotas{
+			MonthlyRequests:       usageQuota(config, model.RateLimitConfigMonthlyRequestQuota),
+			MonthlyTokens:         usageQuota(config, model.RateLimitConfigMonthlyTokenQuota),
+			APIKeyMonthlyRequests: usageQuota(config, model.RateLimitConfigAPIKeyMonthlyRequestQuota),
+		}
+	}
+
+	s.mu.Lock()
+	s.quotas[tenantID] = cachedUsageQuotas{quotas: quotas, expiresAt: now.Add(usageQuotaTTL)}
+	s.mu.Unlock()
+	return quotas, nil
+}
+
+// usageQuota reads a quota from a rate limit config. Missing and
+// non-positive values are unlimited.
+func usageQuota(config map[string]any, key string) int64 {
+	quota, ok := config[key].(float64)
+	if !ok || quota < 1 {
+		return 0
+	}
+	return int64(quota)
+}
+
+// validateUsageQuotas rejects quotas in a rate limit config that are not
+// non-negative whole numbers.
+func validateUsageQuotas(config map[string]any) error {
+	for _, key := range []string{
+		model.RateLimitConfigMonthlyRequestQuota,
+		model.RateLimitConfigMonthlyTokenQuota,
+		model.RateLimitConfigAPIKeyMonthlyRequestQuota,
+	} {
+		value, ok := config[key]
+		if !ok || value == nil {
+			continue
+		}
+		quota, ok := value.(float64)
+		if !ok || quota < 0 || quota != float64(int64(quota)) {
+			return apperror.NewValidation(key + " must be a non-negative whole number")
+		}
+	}
+	return nil
+}
+
+// meterTokenIssued counts an access token about to be issued for the tenant
+// and fails with TEN-2005 when the tenant has used up its monthly token quota.
+func meterTokenIssued(ctx context.Context, tenantID int64) error {
+	if !middleware.MeterTokenIssued(ctx, tenantID) {
+		return apperror.WithCode(apperror.CodeTenantQuotaExceeded, apperror.NewTooManyRequests("monthly token quota exceeded"))
+	}
+	return nil
+}
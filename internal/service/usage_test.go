@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+// fakeTenantUsageStore is an in-memory cache.UsageStore.
+type fakeTenantUsageStore struct {
+	counted   []cache.UsageCount
+	quotas    [][2]int64
+	ok        bool
+	err       error
+	period    map[string]int64
+	usage     []cache.UsageCount
+	takeErr   error
+	restored  []cache.UsageCount
+	periodErr error
+}
+
+func (f *fakeTenantUsageStore) CountUsage(_ context.Context, tenantID, apiKeyID int64, metric string, tenantQuota, apiKeyQuota int64, at time.Time) (bool, error) {
+	f.counted = append(f.counted, cache.UsageCount{TenantID: tenantID, APIKeyID: apiKeyID, Metric: metric, Day: at, Count: 1})
+	f.quotas = append(f.quotas, [2]int64{tenantQuota, apiKeyQuota})
+	return f.ok, f.err
+}
+
+func (f *fakeTenantUsageStore) PeriodUsage(_ context.Context, _ int64, metric string, _ time.Time) (int64, error) {
+	return f.period[metric], f.periodErr
+}
+
+func (f *fakeTenantUsageStore) TakeUsage(context.Context) ([]cache.UsageCount, error) {
+	usage := f.usage
+	f.usage = nil
+	return usage, f.takeErr
+}
+
+func (f *fakeTenantUsageStore) RestoreUsage(_ context.Context, usage []cache.UsageCount) error {
+	f.restored = append(f.restored, usage...)
+	return nil
+}
+
+// usageMeterContext returns a request context metering the tenant usage
+// with store, as UsageMeterMiddleware does.
+func usageMeterContext(store cache.UsageStore) context.Context {
+	var ctx context.Context
+	meter := NewUsageService(store, &mockTenantUsageRepo{}, &mockTenantSettingRepo{}, &mockTenantRepo{})
+	middleware.UsageMeterMiddleware(meter)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+	return ctx
+}
+
+func quotaSettingRepo(reads *int, config string) *mockTenantSettingRepo {
+	return &mockTenantSettingRepo{
+		findByTenantIDFn: func(tid int64) (*model.TenantSetting, error) {
+			*reads++
+			return &model.TenantSetting{TenantID: tid, RateLimitConfig: datatypes.JSON(config)}, nil
+		},
+	}
+}
+
+func TestUsageService_MeterRequest(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	config := `{"monthly_request_quota":1000,"monthly_token_quota":50,"api_key_monthly_request_quota":100}`
+
+	t.Run("counts with quotas", func(t *testing.T) {
+		var reads int
+		store := &fakeTenantUsageStore{ok: true}
+		svc := NewUsageService(store, &mockTenantUsageRepo{}, quotaSettingRepo(&reads, config), &mockTenantRepo{}).(*usageService)
+		svc.now = func() time.Time { return now }
+
+		ok, err := svc.MeterRequest(context.Background(), 3, 0)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		_, err = svc.MeterRequest(context.Background(), 3, 7)
+		require.NoError(t, err)
+		_, err = svc.MeterTokenIssued(context.Background(), 3)
+		require.NoError(t, err)
+
+		assert.Equal(t, []cache.UsageCount{
+			{TenantID: 3, Metric: cache.UsageMetricRequests, Day: now, Count: 1},
+			{TenantID: 3, APIKeyID: 7, Metric: cache.UsageMetricRequests, Day: now, Count: 1},
+			{TenantID: 3, Metric: cache.UsageMetricTokensIssued, Day: now, Count: 1},
+		}, store.counted)
+		assert.Equal(t, [][2]int64{{1000, 0}, {1000, 100}, {50, 0}}, store.quotas)
+
+		// Quotas are read once while they are cached.
+		assert.Equal(t, 1, reads)
+		svc.now = func() time.Time { return now.Add(usageQuotaTTL) }
+		_, err = svc.MeterRequest(context.Background(), 3, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 2, reads)
+	})
+
+	t.Run("over quota", func(t *testing.T) {
+		var reads int
+		svc := NewUsageService(&fakeTenantUsageStore{}, &mockTenantUsageRepo{}, quotaSettingRepo(&reads, config), &mockTenantRepo{})
+		ok, err := svc.MeterRequest(context.Background(), 3, 0)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("no settings is unlimited", func(t *testing.T) {
+		store := &fakeTenantUsageStore{ok: true}
+		svc := NewUsageService(store, &mockTenantUsageRepo{}, &mockTenantSettingRepo{}, &mockTenantRepo{})
+		_, err := svc.MeterRequest(context.Background(), 3, 7)
+		require.NoError(t, err)
+		assert.Equal(t, [][2]int64{{0, 0}}, store.quotas)
+	})
+
+	t.Run("settings error", func(t *testing.T) {
+		svc := NewUsageService(&fakeTenantUsageStore{}, &mockTenantUsageRepo{}, &mockTenantSettingRepo{
+			findByTenantIDFn: func(int64) (*model.TenantSetting, error) { return nil, errors.New("db down") },
+		}, &mockTenantRepo{})
+		_, err := svc.MeterRequest(context.Background(), 3, 0)
+		require.Error(t, err)
+		_, err = svc.MeterTokenIssued(context.Background(), 3)
+		require.Error(t, err)
+	})
+
+	t.Run("no store", func(t *testing.T) {
+		svc := NewUsageService(nil, &mockTenantUsageRepo{}, &mockTenantSettingRepo{}, &mockTenantRepo{})
+		ok, err := svc.MeterRequest(context.Background(), 3, 0)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		ok, err = svc.MeterTokenIssued(context.Background(), 3)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+}
+
+func TestUsageService_GetUsage(t *testing.T) {
+	tenantUUID := uuid.New()
+	apiKeyUUID := uuid.New()
+	day1 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	tenantRepo := &mockTenantRepo{
+		findByUUIDFn: func(id any, _ ...string) (*model.Tenant, error) {
+			if id != tenantUUID {
+				return nil, nil
+			}
+			return &model.Tenant{TenantID: 3, TenantUUID: tenantUUID}, nil
+		},
+	}
+	usageRepo := &mockTenantUsageRepo{
+		findByTenantIDAndDateRangeFn: func(tenantID int64, from, to time.Time) ([]model.TenantUsage, error) {
+			assert.Equal(t, int64(3), tenantID)
+			assert.Equal(t, day1, from)
+			assert.Equal(t, day2, to)
+			return []model.TenantUsage{
+				{UsageDate: day1, Metric: cache.UsageMetricRequests, Count: 10},
+				{UsageDate: day1, Metric: cache.UsageMetricRequests, APIKeyID: 7, Count: 5,
+					APIKey: &model.APIKey{APIKeyUUID: apiKeyUUID, Name: "ci"}},
+				{UsageDate: day1, Metric: cache.UsageMetricTokensIssued, Count: 2},
+				{UsageDate: day2, Metric: cache.UsageMetricRequests, APIKeyID: 7, Count: 1},
+				{UsageDate: day2, Metric: cache.UsageMetricRequests, APIKeyID: 8, Count: 4},
+			}, nil
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var reads int
+		store := &fakeTenantUsageStore{period: map[string]int64{
+			cache.UsageMetricRequests:     120,
+			cache.UsageMetricTokensIssued: 9,
+		}}
+		svc := NewUsageService(store, usageRepo, quotaSettingRepo(&reads, `{"monthly_request_quota":1000}`), tenantRepo).(*usageService)
+		svc.now = func() time.Time { return now }
+
+		report, err := svc.GetUsage(context.Background(), tenantUUID, day1, day2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(20), report.Requests)
+		assert.Equal(t, int64(2), report.TokensIssued)
+		assert.Equal(t, []UsageServiceDay{
+			{Date: day1, Requests: 15, TokensIssued: 2},
+			{Date: day2, Requests: 5},
+		}, report.Days)
+		assert.Equal(t, []UsageServiceAPIKey{
+			{APIKeyUUID: &apiKeyUUID, Name: "ci", Requests: 6},
+			{Requests: 4},
+		}, report.APIKeys)
+		assert.Equal(t, UsageServiceQuotas{MonthlyRequests: 1000}, report.Quotas)
+		assert.Equal(t, UsageServicePeriod{
+			Start:        day1,
+			End:          time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+			Requests:     120,
+			TokensIssued: 9,
+		}, report.CurrentPeriod)
+	})
+
+	t.Run("redis down leaves live counts empty", func(t *testing.T) {
+		svc := NewUsageService(&fakeTenantUsageStore{periodErr: errors.New("redis down")}, usageRepo, &mockTenantSettingRepo{}, tenantRepo)
+		report, err := svc.GetUsage(context.Background(), tenantUUID, day1, day2)
+		require.NoError(t, err)
+		assert.Zero(t, report.CurrentPeriod.Requests)
+		assert.Equal(t, int64(20), report.Requests)
+	})
+
+	t.Run("tenant not found", func(t *testing.T) {
+		svc := NewUsageService(nil, usageRepo, &mockTenantSettingRepo{}, tenantRepo)
+		_, err := svc.GetUsage(context.Background(), uuid.New(), day1, day2)
+		var nf *apperror.NotFoundError
+		assert.ErrorAs(t, err, &nf)
+	})
+
+	t.Run("usage error", func(t *testing.T) {
+		svc := NewUsageService(nil, &mockTenantUsageRepo{
+			findByTenantIDAndDateRangeFn: func(int64, time.Time, time.Time) ([]model.TenantUsage, error) {
+				return nil, errors.New("db down")
+			},
+		}, &mockTenantSettingRepo{}, tenantRepo)
+		_, err := svc.GetUsage(context.Background(), tenantUUID, day1, day2)
+		var ie *apperror.InternalError
+		assert.ErrorAs(t, err, &ie)
+	})
+}
+
+func TestUsageService_FlushUsage(t *testing.T) {
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	usage := []cache.UsageCount{
+		{TenantID: 1, Metric: cache.UsageMetricRequests, Day: day, Count: 3},
+		{TenantID: 1, APIKeyID: 7, Metric: cache.UsageMetricRequests, Day: day, Count: 1},
+	}
+
+	t.Run("no store", func(t *testing.T) {
+		n, err := NewUsageService(nil, &mockTenantUsageRepo{}, &mockTenantSettingRepo{}, &mockTenantRepo{}).FlushUsage(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("writes usage", func(t *testing.T) {
+		var written []model.TenantUsage
+		repo := &mockTenantUsageRepo{addUsageFn: func(u *model.TenantUsage) error {
+			written = append(written, *u)
+			return nil
+		}}
+		store := &fakeTenantUsageStore{usage: usage}
+		n, err := NewUsageService(store, repo, &mockTenantSettingRepo{}, &mockTenantRepo{}).FlushUsage(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+		assert.Equal(t, []model.TenantUsage{
+			{TenantID: 1, UsageDate: day, Metric: cache.UsageMetricRequests, Count: 3},
+			{TenantID: 1, APIKeyID: 7, UsageDate: day, Metric: cache.UsageMetricRequests, Count: 1},
+		}, written)
+		assert.Empty(t, store.restored)
+	})
+
+	t.Run("take error", func(t *testing.T) {
+		store := &fakeTenantUsageStore{takeErr: errors.New("redis down")}
+		_, err := NewUsageService(store, &mockTenantUsageRepo{}, &mockTenantSettingRepo{}, &mockTenantRepo{}).FlushUsage(context.Background())
+		assert.EqualError(t, err, "redis down")
+	})
+
+	t.Run("db error restores the rest", func(t *testing.T) {
+		repo := &mockTenantUsageRepo{addUsageFn: func(u *model.TenantUsage) error {
+			if u.APIKeyID == 7 {
+				return errors.New("db error")
+			}
+			return nil
+		}}
+		store := &fakeTenantUsageStore{usage: usage}
+		n, err := NewUsageService(store, repo, &mockTenantSettingRepo{}, &mockTenantRepo{}).FlushUsage(context.Background())
+		require.Error(t, err)
+		assert.Equal(t, int64(1), n)
+		assert.Equal(t, usage[1:], store.restored)
+	})
+}
+
+func TestMeterTokenIssued(t *testing.T) {
+	require.NoError(t, meterTokenIssued(context.Background(), 3))
+	require.NoError(t, meterTokenIssued(usageMeterContext(&fakeTenantUsageStore{ok: true}), 3))
+
+	err := meterTokenIssued(usageMeterContext(&fakeTenantUsageStore{}), 3)
+	var tm *apperror.TooManyRequestsError
+	require.ErrorAs(t, err, &tm)
+	code, ok := apperror.CodeOf(err)
+	assert.True(t, ok)
+	assert.Equal(t, apperror.CodeTenantQuotaExceeded, code)
+}
@@ -2,12 +2,18 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/apperror"
 	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/middleware"
 	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
 	"github.com/maintainerd/auth/internal/repository"
 	"github.com/maintainerd/auth/internal/security"
 	"go.opentelemetry.io/otel"
@@ -34,6 +40,7 @@ type UserServiceDataResult struct {
 	Roles              *[]RoleServiceDataResult
 	CreatedAt          time.Time
 	UpdatedAt          time.Time
+	DeletedAt          *time.Time
 }
 
 type UserIdentityServiceDataResult struct {
@@ -46,6 +53,40 @@ type UserIdentityServiceDataResult struct {
 	UpdatedAt        time.Time
 }
 
+type UserIdentityServiceGetResult struct {
+	Data       []UserIdentityServiceDataResult
+	Total      int64
+	Page       int
+	Limit      int
+	TotalPages int
+}
+
+// UserServiceGetRolesFilter selects a page of the roles a user holds in a
+// tenant.
+type UserServiceGetRolesFilter struct {
+	UserUUID    uuid.UUID
+	TenantID    int64
+	Name        *string
+	Description *string
+	Status      *string
+	Page        int
+	Limit       int
+	SortBy      string
+	SortOrder   string
+}
+
+// UserServiceGetIdentitiesFilter selects a page of a user's identities in a
+// tenant.
+type UserServiceGetIdentitiesFilter struct {
+	UserUUID  uuid.UUID
+	TenantID  int64
+	Provider  *string
+	Page      int
+	Limit     int
+	SortBy    string
+	SortOrder string
+}
+
 type UserServiceGetFilter struct {
 	Username     *string
 	Email        *string
@@ -59,6 +100,14 @@ type UserServiceGetFilter struct {
 	Limit        int
 	SortBy       string
 	SortOrder    string
+
+	// IncludeSubtenants also lists the users of the tenants below TenantID.
+	IncludeSubtenants bool
+
+	// IncludeIdentities and IncludeRoles load the identities and roles of
+	// the listed users, batched per page.
+	IncludeIdentities bool
+	IncludeRoles      bool
 }
 
 type UserServiceGetResult struct {
@@ -71,6 +120,22 @@ type UserServiceGetResult struct {
 
 type UserService interface {
 	Get(ctx context.Context, filter UserServiceGetFilter) (*UserServiceGetResult, error)
+	// Search returns a page of the users matching the filter whose username,
+	// fullname, email or phone fuzzily matches query, most relevant first.
+	// Sorting of the filter is ignored.
+	Search(ctx context.Context, query string, filter UserServiceGetFilter) (*UserServiceGetResult, error)
+	// GetByCursor returns the page of users after cursor, newest first unless
+	// the filter's SortOrder is asc. An empty cursor returns the first page.
+	// Page and SortBy of the filter are ignored.
+	GetByCursor(ctx context.Context, filter UserServiceGetFilter, cursor string) (*CursorPageResult[UserServiceDataResult], error)
+	// Export passes every user matching the filter to fn, in the order they
+	// were created. Users are read in chunks so that large tenants are never
+	// held in memory; Page, Limit and sorting of the filter are ignored. An
+	// error from fn stops the export and is returned.
+	Export(ctx context.Context, filter UserServiceGetFilter, fn func(UserServiceDataResult) error) error
+	// Delta returns the users created, updated or deleted after a cursor,
+	// with tombstones for deleted users, for incremental directory syncs.
+	Delta(ctx context.Context, filter UserServiceDeltaFilter) (*UserServiceDeltaResult, error)
 	GetByUUID(ctx context.Context, userUUID uuid.UUID, tenantID int64) (*UserServiceDataResult, error)
 	Create(ctx context.Context, username string, fullname string, email *string, phone *string, password string, status string, metadata datatypes.JSON, tenantUUID string, creatorUserUUID uuid.UUID) (*UserServiceDataResult, error)
 	Update(ctx context.Context, userUUID uuid.UUID, tenantID int64, username string, fullname string, email *string, phone *string, status string, metadata datatypes.JSON, updaterUserUUID uuid.UUID) (*UserServiceDataResult, error)
@@ -78,11 +143,53 @@ type UserService interface {
 	VerifyEmail(ctx context.Context, userUUID uuid.UUID, tenantID int64) (*UserServiceDataResult, error)
 	VerifyPhone(ctx context.Context, userUUID uuid.UUID, tenantID int64) (*UserServiceDataResult, error)
 	CompleteAccount(ctx context.Context, userUUID uuid.UUID, tenantID int64) (*UserServiceDataResult, error)
+	// DeleteByUUID soft-deletes the user. The account is kept until the
+	// tenant's retention period elapses and PurgeExpired erases it.
 	DeleteByUUID(ctx context.Context, userUUID uuid.UUID, tenantID int64, deleterUserUUID uuid.UUID) (*UserServiceDataResult, error)
+	// PreviewDeleteByUUID reports what DeleteByUUID would change without
+	// changing anything.
+	PreviewDeleteByUUID(ctx context.Context, userUUID uuid.UUID, tenantID int64, deleterUserUUID uuid.UUID) (*DeleteImpactReport, error)
+	RestoreByUUID(ctx context.Context, userUUID uuid.UUID, tenantID int64, restorerUserUUID uuid.UUID) (*UserServiceDataResult, error)
+	// PurgeByUUID permanently erases the user without waiting for retention.
+	PurgeByUUID(ctx context.Context, userUUID uuid.UUID, tenantID int64, purgerUserUUID uuid.UUID) (*UserServiceDataResult, error)
+	// PreviewPurgeByUUID reports what PurgeByUUID would erase, including
+	// cascades, without erasing anything.
+	PreviewPurgeByUUID(ctx context.Context, userUUID uuid.UUID, tenantID int64, purgerUserUUID uuid.UUID) (*DeleteImpactReport, error)
+	// PurgeExpired permanently erases soft-deleted users whose retention
+	// period, or account deletion grace period, has elapsed and returns how
+	// many were erased.
+	PurgeExpired(ctx context.Context) (int64, error)
+	// ScheduleSelfDeletion deletes the caller's own account. Sign in stops
+	// at once and the account is purged once the tenant's grace period
+	// elapses, unless cancelled with the token emailed to the user.
+	ScheduleSelfDeletion(ctx context.Context, userUUID uuid.UUID, tenantID int64) (*AccountDeletionServiceDataResult, error)
+	// CancelSelfDeletion restores an account scheduled for deletion, given
+	// the cancellation token from the confirmation email.
+	CancelSelfDeletion(ctx context.Context, token string) (*UserServiceDataResult, error)
+	// DisableSelf temporarily disables the caller's own account. Sign in
+	// stops at once and data is kept. The user is emailed a link to
+	// reactivate it; an admin with user:enable can reactivate it too.
+	DisableSelf(ctx context.Context, userUUID uuid.UUID, tenantID int64) (*UserServiceDataResult, error)
+	// ReactivateSelf reactivates an account its owner disabled, given the
+	// reactivation token from the confirmation email.
+	ReactivateSelf(ctx context.Context, token string) (*UserServiceDataResult, error)
+	// RevokeSessions revokes every refresh token of a user in the tenant,
+	// signing them out of all sessions, and returns how many were revoked.
+	RevokeSessions(ctx context.Context, userUUID uuid.UUID, tenantID int64) (int64, error)
+	// ChangePassword replaces the caller's password after verifying the
+	// current one. The new password must meet the tenant's password policy.
+	// Every other session is signed out; the session of
+	// currentRefreshToken, when given, stays signed in.
+	ChangePassword(ctx context.Context, userUUID uuid.UUID, tenantID int64, currentPassword, newPassword, currentRefreshToken string) error
 	AssignUserRoles(ctx context.Context, userUUID uuid.UUID, roleUUIDs []uuid.UUID, tenantID int64) (*UserServiceDataResult, error)
 	RemoveUserRole(ctx context.Context, userUUID uuid.UUID, roleUUID uuid.UUID, tenantID int64) (*UserServiceDataResult, error)
 	GetUserRoles(ctx context.Context, userUUID uuid.UUID) ([]RoleServiceDataResult, error)
-	GetUserIdentities(ctx context.Context, userUUID uuid.UUID) ([]UserIdentityServiceDataResult, error)
+	// ListUserRoles returns a page of the user's roles in the filter's
+	// tenant. Filtering, sorting and paging are done by the database.
+	ListUserRoles(ctx context.Context, filter UserServiceGetRolesFilter) (*RoleServiceGetResult, error)
+	// ListUserIdentities returns a page of the user's identities in the
+	// filter's tenant, each with its client.
+	ListUserIdentities(ctx context.Context, filter UserServiceGetIdentitiesFilter) (*UserIdentityServiceGetResult, error)
 	// FindBySubAndClientID resolves a user from a JWT sub claim and client ID.
 	// Used by UserContextMiddleware to populate the request context.
 	FindBySubAndClientID(ctx context.Context, sub string, clientID string) (*model.User, error)
@@ -98,7 +205,16 @@ type userService struct {
 	identityProviderRepo repository.IdentityProviderRepository
 	clientRepo           repository.ClientRepository
 	userPoolRepo         repository.UserPoolRepository
+	tenantSettingRepo    repository.TenantSettingRepository
+	securitySettingRepo  repository.SecuritySettingRepository
+	userTokenRepo        repository.UserTokenRepository
+	refreshTokenRepo     repository.OAuthRefreshTokenRepository
+	emailTemplateRepo    repository.EmailTemplateRepository
+	eventRepo            repository.EventRepository
+	authEventService     AuthEventService
+	breachChecker        security.BreachedPasswordChecker
 	cacheInvalidator     cache.Invalidator
+	planEnforcer         PlanEnforcer
 }
 
 func NewUserService(
@@ -111,7 +227,16 @@ func NewUserService(
 	identityProviderRepo repository.IdentityProviderRepository,
 	clientRepo repository.ClientRepository,
 	userPoolRepo repository.UserPoolRepository,
+	tenantSettingRepo repository.TenantSettingRepository,
+	securitySettingRepo repository.SecuritySettingRepository,
+	userTokenRepo repository.UserTokenRepository,
+	refreshTokenRepo repository.OAuthRefreshTokenRepository,
+	emailTemplateRepo repository.EmailTemplateRepository,
+	eventRepo repository.EventRepository,
+	authEventService AuthEventService,
+	breachChecker security.BreachedPasswordChecker,
 	cacheInvalidator cache.Invalidator,
+	planEnforcer PlanEnforcer,
 ) UserService {
 	return &userService{
 		db:                   db,
@@ -123,7 +248,16 @@ func NewUserService(
 		identityProviderRepo: identityProviderRepo,
 		clientRepo:           clientRepo,
 		userPoolRepo:         userPoolRepo,
+		tenantSettingRepo:    tenantSettingRepo,
+		securitySettingRepo:  securitySettingRepo,
+		userTokenRepo:        userTokenRepo,
+		refreshTokenRepo:     refreshTokenRepo,
+		emailTemplateRepo:    emailTemplateRepo,
+		eventRepo:            eventRepo,
+		authEventService:     authEventService,
+		breachChecker:        breachChecker,
 		cacheInvalidator:     cacheInvalidator,
+		planEnforcer:         planEnforcer,
 	}
 }
 
@@ -176,17 +310,171 @@ func (s *userService) Get(ctx context.Context, filter UserServiceGetFilter) (*Us
 	_, span := otel.Tracer("service").Start(ctx, "user.list")
 	defer span.End()
 
+	queryFilter, err := s.toUserRepositoryFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.userRepo.FindPaginated(queryFilter)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list users failed")
+		return nil, err
+	}
+
+	// Build response data
+	resData := make([]UserServiceDataResult, len(result.Data))
+	for i, rdata := range result.Data {
+		resData[i] = *toUserServiceDataResult(&rdata)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return &UserServiceGetResult{
+		Data:       resData,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}, nil
+}
+
+func (s *userService) Search(ctx context.Context, query string, filter UserServiceGetFilter) (*UserServiceGetResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "user.search")
+	defer span.End()
+
+	queryFilter, err := s.toUserRepositoryFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.userRepo.Search(query, queryFilter)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "search users failed")
+		return nil, err
+	}
+
+	resData := make([]UserServiceDataResult, len(result.Data))
+	for i, rdata := range result.Data {
+		resData[i] = *toUserServiceDataResult(&rdata)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return &UserServiceGetResult{
+		Data:       resData,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}, nil
+}
+
+func (s *userService) GetByCursor(ctx context.Context, filter UserServiceGetFilter, cursor string) (*CursorPageResult[UserServiceDataResult], error) {
+	_, span := otel.Tracer("service").Start(ctx, "user.listByCursor")
+	defer span.End()
+
+	afterID, err := decodeListCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	queryFilter, err := s.toUserRepositoryFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.userRepo.FindCursorPaginated(queryFilter, repository.CursorPage{
+		AfterID:   afterID,
+		Limit:     filter.Limit,
+		Ascending: strings.EqualFold(filter.SortOrder, "asc"),
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list users failed")
+		return nil, err
+	}
+
+	resData := make([]UserServiceDataResult, len(result.Data))
+	for i, rdata := range result.Data {
+		resData[i] = *toUserServiceDataResult(&rdata)
+	}
+
+	page := &CursorPageResult[UserServiceDataResult]{
+		Data:    resData,
+		Limit:   result.Limit,
+		HasMore: result.HasMore,
+	}
+	if result.HasMore {
+		page.NextCursor = encodeListCursor(result.Data[len(result.Data)-1].UserID)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return page, nil
+}
+
+// userExportChunkSize is the number of users read per query by Export.
+const userExportChunkSize = 1000
+
+func (s *userService) Export(ctx context.Context, filter UserServiceGetFilter, fn func(UserServiceDataResult) error) error {
+	ctx, span := otel.Tracer("service").Start(ctx, "user.export")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", filter.TenantID))
+
+	queryFilter, err := s.toUserRepositoryFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	var exported int
+	var afterUserID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "export users failed")
+			return err
+		}
+
+		users, err := s.userRepo.FindFilteredAfterID(queryFilter, afterUserID, userExportChunkSize)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "export users failed")
+			return err
+		}
+
+		for i := range users {
+			if err := fn(*toUserServiceDataResult(&users[i])); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "export users failed")
+				return err
+			}
+		}
+		exported += len(users)
+
+		if len(users) < userExportChunkSize {
+			break
+		}
+		afterUserID = users[len(users)-1].UserID
+	}
+
+	span.SetAttributes(attribute.Int("export.users", exported))
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// toUserRepositoryFilter resolves the role, client and user pool UUIDs of a
+// list filter to their IDs.
+func (s *userService) toUserRepositoryFilter(filter UserServiceGetFilter) (repository.UserRepositoryGetFilter, error) {
 	// Convert role UUID to ID if provided
 	var roleID *int64
 	if filter.RoleUUID != nil {
 		roleUUIDParsed, err := uuid.Parse(*filter.RoleUUID)
 		if err != nil {
-			return nil, apperror.NewValidation("invalid role UUID")
+			return repository.UserRepositoryGetFilter{}, apperror.NewValidation("invalid role UUID")
 		}
 
 		role, err := s.roleRepo.FindByUUID(roleUUIDParsed)
 		if err != nil || role == nil {
-			return nil, apperror.NewNotFound("role not found")
+			return repository.UserRepositoryGetFilter{}, apperror.NewNotFound("role not found")
 		}
 		roleID = &role.RoleID
 	}
@@ -196,11 +484,11 @@ func (s *userService) Get(ctx context.Context, filter UserServiceGetFilter) (*Us
 	if filter.ClientUUID != nil {
 		clientUUIDParsed, err := uuid.Parse(*filter.ClientUUID)
 		if err != nil {
-			return nil, apperror.NewValidation("invalid client UUID")
+			return repository.UserRepositoryGetFilter{}, apperror.NewValidation("invalid client UUID")
 		}
 		client, err := s.clientRepo.FindByUUIDAndTenantID(clientUUIDParsed, filter.TenantID)
 		if err != nil || client == nil {
-			return nil, apperror.NewNotFound("client not found")
+			return repository.UserRepositoryGetFilter{}, apperror.NewNotFound("client not found")
 		}
 		clientID = &client.ClientID
 	}
@@ -210,17 +498,16 @@ func (s *userService) Get(ctx context.Context, filter UserServiceGetFilter) (*Us
 	if filter.UserPoolUUID != nil {
 		poolUUIDParsed, err := uuid.Parse(*filter.UserPoolUUID)
 		if err != nil {
-			return nil, apperror.NewValidation("invalid user pool UUID")
+			return repository.UserRepositoryGetFilter{}, apperror.NewValidation("invalid user pool UUID")
 		}
 		pool, err := s.userPoolRepo.FindByUUID(poolUUIDParsed)
 		if err != nil || pool == nil {
-			return nil, apperror.NewNotFound("user pool not found")
+			return repository.UserRepositoryGetFilter{}, apperror.NewNotFound("user pool not found")
 		}
 		userPoolID = &pool.UserPoolID
 	}
 
-	// Build query filter
-	queryFilter := repository.UserRepositoryGetFilter{
+	return repository.UserRepositoryGetFilter{
 		Username:   filter.Username,
 		Email:      filter.Email,
 		Phone:      filter.Phone,
@@ -233,28 +520,10 @@ func (s *userService) Get(ctx context.Context, filter UserServiceGetFilter) (*Us
 		Limit:      filter.Limit,
 		SortBy:     filter.SortBy,
 		SortOrder:  filter.SortOrder,
-	}
-
-	result, err := s.userRepo.FindPaginated(queryFilter)
-	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, "list users failed")
-		return nil, err
-	}
 
-	// Build response data
-	resData := make([]UserServiceDataResult, len(result.Data))
-	for i, rdata := range result.Data {
-		resData[i] = *toUserServiceDataResult(&rdata)
-	}
-
-	span.SetStatus(codes.Ok, "")
-	return &UserServiceGetResult{
-		Data:       resData,
-		Total:      result.Total,
-		Page:       result.Page,
-		Limit:      result.Limit,
-		TotalPages: result.TotalPages,
+		IncludeSubtenants: filter.IncludeSubtenants,
+		IncludeIdentities: filter.IncludeIdentities,
+		IncludeRoles:      filter.IncludeRoles,
 	}, nil
 }
 
@@ -269,7 +538,7 @@ func (s *userService) GetByUUID(ctx context.Context, userUUID uuid.UUID, tenantI
 			span.RecordError(err)
 		}
 		span.SetStatus(codes.Error, "user not found")
-		return nil, apperror.NewNotFound("user not found")
+		return nil, apperror.WithCode(apperror.CodeUserNotFound, apperror.NewNotFound("user"))
 	}
 
 	// Validate tenant ownership - check if user has an identity in this tenant
@@ -303,6 +572,7 @@ func (s *userService) Create(ctx context.Context, username string, fullname stri
 		txClientRepo := s.clientRepo.WithTx(tx)
 		txRoleRepo := s.roleRepo.WithTx(tx)
 		txUserRoleRepo := s.userRoleRepo.WithTx(tx)
+		txEventRepo := s.eventRepo.WithTx(tx)
 
 		// Parse tenant UUID
 		tenantUUIDParsed, err := uuid.Parse(tenantUUID)
@@ -327,6 +597,17 @@ func (s *userService) Create(ctx context.Context, username string, fullname stri
 			return err
 		}
 
+		// Stay within the user limit of the tenant's plan
+		if err := s.planEnforcer.CheckUserLimit(ctx, targetTenant.TenantID); err != nil {
+			return err
+		}
+
+		// Validate metadata against the tenant schema and protected namespace
+		metadata, err = applyUserMetadataPatch(s.tenantSettingRepo.WithTx(tx), targetTenant.TenantID, nil, metadata)
+		if err != nil {
+			return err
+		}
+
 		// Check if user already exists by username
 		existingUser, err := txUserRepo.FindByUsername(username)
 		if err != nil {
@@ -347,8 +628,13 @@ func (s *userService) Create(ctx context.Context, username string, fullname stri
 			}
 		}
 
+		// Reject breached passwords when the tenant opts in
+		if err := ensurePasswordNotBreached(ctx, s.tenantSettingRepo.WithTx(tx), s.breachChecker, targetTenant.TenantID, password); err != nil {
+			return err
+		}
+
 		// Hash password
-		hashedPassword, err := security.HashPassword([]byte(password))
+		hashedPassword, err := hashTenantPassword(s.securitySettingRepo.WithTx(tx), targetTenant.TenantID, password)
 		if err != nil {
 			return err
 		}
@@ -418,6 +704,11 @@ func (s *userService) Create(ctx context.Context, username string, fullname stri
 			return err
 		}
 
+		// Record lifecycle events
+		if err := recordUserCreatedEvents(txEventRepo, targetTenant.TenantID, newUser, []uuid.UUID{defaultRole.RoleUUID}); err != nil {
+			return err
+		}
+
 		// Fetch created user with relationships
 		createdUser, err = txUserRepo.FindByUUID(newUser.UserUUID, "UserIdentities.Client", "UserIdentities.Tenant", "Roles")
 		if err != nil {
@@ -447,10 +738,13 @@ func (s *userService) Update(ctx context.Context, userUUID uuid.UUID, tenantID i
 	err := s.db.Transaction(func(tx *gorm.DB) error {
 		txUserRepo := s.userRepo.WithTx(tx)
 
-		// Check if target user exists
-		user, err := txUserRepo.FindByUUID(userUUID, "UserIdentities")
+		// Check if target user exists, locked so the If-Match check below holds until commit
+		user, err := s.userRepo.WithTx(lockForUpdate(tx)).FindByUUID(userUUID, "UserIdentities")
 		if err != nil || user == nil {
-			return apperror.NewNotFound("user not found")
+			return apperror.WithCode(apperror.CodeUserNotFound, apperror.NewNotFound("user"))
+		}
+		if user.DeletedAt != nil {
+			return apperror.NewValidation("user is deleted, restore it first")
 		}
 
 		// Validate tenant ownership - check if user has an identity in this tenant
@@ -476,6 +770,10 @@ func (s *userService) Update(ctx context.Context, userUUID uuid.UUID, tenantID i
 			return err
 		}
 
+		if err := checkIfMatch(ctx, "user", user.UpdatedAt); err != nil {
+			return err
+		}
+
 		// Check if username is taken by another user
 		if username != user.Username {
 			existingUser, err := txUserRepo.FindByUsername(username)
@@ -519,7 +817,12 @@ func (s *userService) Update(ctx context.Context, userUUID uuid.UUID, tenantID i
 			user.Phone = phoneStr
 		}
 		if metadata != nil {
-			user.Metadata = metadata
+			// Merge rather than replace so unrelated keys survive partial updates
+			merged, err := applyUserMetadataPatch(s.tenantSettingRepo.WithTx(tx), tenantID, user.Metadata, metadata)
+			if err != nil {
+				return err
+			}
+			user.Metadata = merged
 		}
 
 		_, err = txUserRepo.UpdateByUUID(userUUID, user)
@@ -533,6 +836,11 @@ func (s *userService) Update(ctx context.Context, userUUID uuid.UUID, tenantID i
 			return err
 		}
 
+		// Record lifecycle event
+		if err := recordEvent(s.eventRepo.WithTx(tx), tenantID, UserUpdated(newUserEventPayload(user))); err != nil {
+			return err
+		}
+
 		return nil
 	})
 
@@ -555,7 +863,10 @@ func (s *userService) SetStatus(ctx context.Context, userUUID uuid.UUID, tenantI
 	// Check if target user exists
 	user, err := s.userRepo.FindByUUID(userUUID, "UserIdentities")
 	if err != nil || user == nil {
-		return nil, apperror.NewNotFound("user not found")
+		return nil, apperror.WithCode(apperror.CodeUserNotFound, apperror.NewNotFound("user"))
+	}
+	if user.DeletedAt != nil {
+		return nil, apperror.NewValidation("user is deleted, restore it first")
 	}
 
 	// Validate tenant ownership - check if user has an identity in this tenant
@@ -581,22 +892,73 @@ func (s *userService) SetStatus(ctx context.Context, userUUID uuid.UUID, tenantI
 		return nil, err
 	}
 
-	err = s.userRepo.SetStatus(userUUID, status)
-	if err != nil {
+	if err := validateUserStatusTransition(user.Status, status); err != nil {
 		return nil, err
 	}
 
-	// Fetch updated user with relationships
-	updatedUser, err := s.userRepo.FindByUUID(userUUID, "UserIdentities.Client", "UserIdentities.Tenant", "Roles")
+	var updatedUser *model.User
+
+	// Update status and record the lifecycle event atomically
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		txUserRepo := s.userRepo.WithTx(tx)
+
+		if err := txUserRepo.SetStatus(userUUID, status); err != nil {
+			return err
+		}
+
+		// A reactivated account no longer needs its owner's reactivation link
+		if user.Status == model.StatusDisabled {
+			if err := revokeUserTokensOfType(s.userTokenRepo.WithTx(tx), user.UserID, model.TokenTypeAccountReactivate); err != nil {
+				return err
+			}
+		}
+
+		// Fetch updated user with relationships
+		updatedUser, err = txUserRepo.FindByUUID(userUUID, "UserIdentities.Client", "UserIdentities.Tenant", "Roles")
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(s.eventRepo.WithTx(tx), tenantID, UserUpdated(newUserEventPayload(updatedUser)))
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	// Moving a held account on is the admin review that clears the hold
+	if user.Status == model.StatusSecurityHold && status != model.StatusSecurityHold {
+		s.logUserLifecycle(ctx, tenantID, &updaterUser.UserID, user, model.AuthEventTypeUserSecurityHoldCleared,
+			fmt.Sprintf("Security hold cleared by admin review, status set to %s", status))
+	}
+
 	span.SetStatus(codes.Ok, "")
 	s.invalidateUserCache(ctx, updatedUser.UserIdentities)
 	return toUserServiceDataResult(updatedUser), nil
 }
 
+// userStatusTransitions lists the statuses an admin may move a user to from
+// each status. Only the owner can disable an account, with DisableSelf, and
+// accounts are put on security hold with SecurityHoldService.
+var userStatusTransitions = map[string][]string{
+	model.StatusPending:   {model.StatusActive, model.StatusInactive, model.StatusSuspended},
+	model.StatusActive:    {model.StatusInactive, model.StatusSuspended},
+	model.StatusInactive:  {model.StatusActive, model.StatusSuspended},
+	model.StatusSuspended: {model.StatusActive, model.StatusInactive},
+	model.StatusDisabled:  {model.StatusActive, model.StatusSuspended},
+	// Only the guardian can activate an account waiting for their consent
+	model.StatusPendingConsent: {model.StatusInactive, model.StatusSuspended},
+	model.StatusSecurityHold:   {model.StatusActive, model.StatusInactive, model.StatusSuspended},
+}
+
+// validateUserStatusTransition checks that an admin may change a user's
+// status from one value to another. Keeping the current status is allowed.
+func validateUserStatusTransition(from, to string) error {
+	if from == to || slices.Contains(userStatusTransitions[from], to) {
+		return nil
+	}
+	return apperror.NewValidation(fmt.Sprintf("cannot change user status from %s to %s", from, to))
+}
+
 func (s *userService) VerifyEmail(ctx context.Context, userUUID uuid.UUID, tenantID int64) (*UserServiceDataResult, error) {
 	_, span := otel.Tracer("service").Start(ctx, "user.verifyEmail")
 	defer span.End()
@@ -605,7 +967,7 @@ func (s *userService) VerifyEmail(ctx context.Context, userUUID uuid.UUID, tenan
 	// Check if target user exists and preload identities for tenant validation
 	user, err := s.userRepo.FindByUUID(userUUID, "UserIdentities.Tenant")
 	if err != nil || user == nil {
-		return nil, apperror.NewNotFound("user not found")
+		return nil, apperror.WithCode(apperror.CodeUserNotFound, apperror.NewNotFound("user"))
 	}
 
 	// Validate tenant ownership - check if user has an identity in this tenant
@@ -649,7 +1011,7 @@ func (s *userService) VerifyPhone(ctx context.Context, userUUID uuid.UUID, tenan
 	// Check if target user exists and preload identities for tenant validation
 	user, err := s.userRepo.FindByUUID(userUUID, "UserIdentities.Tenant")
 	if err != nil || user == nil {
-		return nil, apperror.NewNotFound("user not found")
+		return nil, apperror.WithCode(apperror.CodeUserNotFound, apperror.NewNotFound("user"))
 	}
 
 	// Validate tenant ownership - check if user has an identity in this tenant
@@ -692,7 +1054,7 @@ func (s *userService) CompleteAccount(ctx context.Context, userUUID uuid.UUID, t
 	// Check if target user exists and preload identities for tenant validation
 	user, err := s.userRepo.FindByUUID(userUUID, "UserIdentities.Tenant")
 	if err != nil || user == nil {
-		return nil, apperror.NewNotFound("user not found")
+		return nil, apperror.WithCode(apperror.CodeUserNotFound, apperror.NewNotFound("user"))
 	}
 
 	// Validate tenant ownership - check if user has an identity in this tenant
@@ -732,10 +1094,183 @@ func (s *userService) DeleteByUUID(ctx context.Context, userUUID uuid.UUID, tena
 	defer span.End()
 	span.SetAttributes(attribute.String("user.uuid", userUUID.String()), attribute.Int64("tenant.id", tenantID))
 
-	// Check if target user exists
+	user, deleter, err := s.findManagedUser(userUUID, tenantID, deleterUserUUID, "deleter")
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletedAt != nil {
+		return nil, apperror.NewValidation("user is already deleted")
+	}
+
+	var deletedUser *model.User
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		deletedUser, err = s.softDeleteUser(tx, userUUID, tenantID)
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete user failed")
+		return nil, err
+	}
+
+	s.invalidateUserCache(ctx, user.UserIdentities)
+	s.logUserLifecycle(ctx, tenantID, &deleter.UserID, user, model.AuthEventTypeUserArchived, "user soft-deleted")
+
+	span.SetStatus(codes.Ok, "")
+	return toUserServiceDataResult(deletedUser), nil
+}
+
+// PreviewDeleteByUUID reports what DeleteByUUID would change, running the
+// soft delete in a transaction that is rolled back.
+func (s *userService) PreviewDeleteByUUID(ctx context.Context, userUUID uuid.UUID, tenantID int64, deleterUserUUID uuid.UUID) (*DeleteImpactReport, error) {
+	_, span := otel.Tracer("service").Start(ctx, "user.previewDelete")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", userUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	user, _, err := s.findManagedUser(userUUID, tenantID, deleterUserUUID, "deleter")
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletedAt != nil {
+		return nil, apperror.NewValidation("user is already deleted")
+	}
+
+	report, err := previewDelete(s.db, softDeleteImpact(model.User{}.TableName(), 1), []string{model.EventTypeUserDeleted}, func(tx *gorm.DB) error {
+		_, err := s.softDeleteUser(tx, userUUID, tenantID)
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "preview user delete failed")
+		return nil, apperror.NewInternal("failed to preview user delete", err)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return report, nil
+}
+
+// softDeleteUser soft-deletes the user and records the lifecycle event in
+// tx. The row and its identities stay until the retention period elapses.
+func (s *userService) softDeleteUser(tx *gorm.DB, userUUID uuid.UUID, tenantID int64) (*model.User, error) {
+	txUserRepo := s.userRepo.WithTx(tx)
+
+	if err := txUserRepo.SoftDelete(userUUID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	deletedUser, err := txUserRepo.FindByUUID(userUUID, "UserIdentities.Client", "UserIdentities.Tenant", "Roles")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := recordEvent(s.eventRepo.WithTx(tx), tenantID, UserDeleted(newUserEventPayload(deletedUser))); err != nil {
+		return nil, err
+	}
+	return deletedUser, nil
+}
+
+func (s *userService) RestoreByUUID(ctx context.Context, userUUID uuid.UUID, tenantID int64, restorerUserUUID uuid.UUID) (*UserServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "user.restore")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", userUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	user, restorer, err := s.findManagedUser(userUUID, tenantID, restorerUserUUID, "restorer")
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletedAt == nil {
+		return nil, apperror.NewValidation("user is not deleted")
+	}
+
+	var restoredUser *model.User
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		txUserRepo := s.userRepo.WithTx(tx)
+
+		if err := txUserRepo.Restore(userUUID, model.StatusActive); err != nil {
+			return err
+		}
+		if err := revokeUserTokensOfType(s.userTokenRepo.WithTx(tx), user.UserID, model.TokenTypeAccountDeletion); err != nil {
+			return err
+		}
+
+		restoredUser, err = txUserRepo.FindByUUID(userUUID, "UserIdentities.Client", "UserIdentities.Tenant", "Roles")
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(s.eventRepo.WithTx(tx), tenantID, UserRestored(newUserEventPayload(restoredUser)))
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "restore user failed")
+		return nil, err
+	}
+
+	s.invalidateUserCache(ctx, user.UserIdentities)
+	s.logUserLifecycle(ctx, tenantID, &restorer.UserID, user, model.AuthEventTypeUserRestored, "user restored")
+
+	span.SetStatus(codes.Ok, "")
+	return toUserServiceDataResult(restoredUser), nil
+}
+
+func (s *userService) PurgeByUUID(ctx context.Context, userUUID uuid.UUID, tenantID int64, purgerUserUUID uuid.UUID) (*UserServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "user.purge")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", userUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	user, purger, err := s.findManagedUser(userUUID, tenantID, purgerUserUUID, "purger")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.purgeUser([]int64{tenantID}, user); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "purge user failed")
+		return nil, err
+	}
+
+	s.invalidateUserCache(ctx, user.UserIdentities)
+	s.logUserLifecycle(ctx, tenantID, &purger.UserID, user, model.AuthEventTypeUserDeleted, "user permanently erased")
+
+	span.SetStatus(codes.Ok, "")
+	return toUserServiceDataResult(user), nil
+}
+
+// PreviewPurgeByUUID reports what PurgeByUUID would erase, running the hard
+// delete in a transaction that is rolled back.
+func (s *userService) PreviewPurgeByUUID(ctx context.Context, userUUID uuid.UUID, tenantID int64, purgerUserUUID uuid.UUID) (*DeleteImpactReport, error) {
+	_, span := otel.Tracer("service").Start(ctx, "user.previewPurge")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", userUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	user, _, err := s.findManagedUser(userUUID, tenantID, purgerUserUUID, "purger")
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := previewDelete(s.db, hardDeleteImpact(model.User{}.TableName(), "user_id", user.UserID), []string{model.EventTypeUserPurged}, func(tx *gorm.DB) error {
+		return s.purgeUserTx(tx, []int64{tenantID}, user)
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "preview user purge failed")
+		return nil, apperror.NewInternal("failed to preview user purge", err)
+	}
+
+	span.SetAttributes(attribute.Bool("dry_run.would_succeed", report.WouldSucceed))
+	span.SetStatus(codes.Ok, "")
+	return report, nil
+}
+
+// findManagedUser loads the target user and the acting admin, and checks that
+// the target has an identity in the tenant the admin may manage. role names
+// the admin in the not-found error.
+func (s *userService) findManagedUser(userUUID uuid.UUID, tenantID int64, actorUserUUID uuid.UUID, role string) (*model.User, *model.User, error) {
 	user, err := s.userRepo.FindByUUID(userUUID, "UserIdentities.Client", "UserIdentities", "Roles")
 	if err != nil || user == nil {
-		return nil, apperror.NewNotFound("user not found")
+		return nil, nil, apperror.WithCode(apperror.CodeUserNotFound, apperror.NewNotFound("user"))
 	}
 
 	// Validate tenant ownership - check if user has an identity in this tenant
@@ -747,33 +1282,63 @@ func (s *userService) DeleteByUUID(ctx context.Context, userUUID uuid.UUID, tena
 		}
 	}
 	if !hasTenantAccess {
-		return nil, apperror.NewNotFoundWithReason("user not found or access denied")
+		return nil, nil, apperror.NewNotFoundWithReason("user not found or access denied")
 	}
 
-	// Get deleter user with tenant info
-	deleterUser, err := s.userRepo.FindByUUID(deleterUserUUID, "UserIdentities.Tenant")
-	if err != nil || deleterUser == nil {
-		return nil, apperror.NewNotFoundWithReason("deleter user not found")
+	actor, err := s.userRepo.FindByUUID(actorUserUUID, "UserIdentities.Tenant")
+	if err != nil || actor == nil {
+		return nil, nil, apperror.NewNotFoundWithReason(role + " user not found")
 	}
 
 	// Validate tenant access permissions
-	if err := ValidateTenantAccess(deleterUser, user.UserIdentities[0].Tenant); err != nil {
-		return nil, err
+	if err := ValidateTenantAccess(actor, user.UserIdentities[0].Tenant); err != nil {
+		return nil, nil, err
 	}
+	return user, actor, nil
+}
 
-	// Invalidate cache before deletion (identities will be gone after)
-	s.invalidateUserCache(ctx, user.UserIdentities)
+// purgeUser hard-deletes the user (cascade removes identities, roles and
+// tokens) and records user.purged in each of tenantIDs. The event carries
+// only the user UUID so the feed does not keep the erased personal data.
+func (s *userService) purgeUser(tenantIDs []int64, user *model.User) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.purgeUserTx(tx, tenantIDs, user)
+	})
+}
 
-	// Delete user (cascade will handle related records)
-	err = s.userRepo.DeleteByUUID(userUUID)
-	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, "delete user failed")
-		return nil, err
+// purgeUserTx is purgeUser inside tx.
+func (s *userService) purgeUserTx(tx *gorm.DB, tenantIDs []int64, user *model.User) error {
+	if err := s.userRepo.WithTx(tx).DeleteByUUID(user.UserUUID); err != nil {
+		return err
 	}
+	txEventRepo := s.eventRepo.WithTx(tx)
+	for _, tenantID := range tenantIDs {
+		if err := recordEvent(txEventRepo, tenantID, UserPurged{UserUUID: user.UserUUID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	span.SetStatus(codes.Ok, "")
-	return toUserServiceDataResult(user), nil
+// logUserLifecycle writes the audit entry of a lifecycle change such as a
+// delete, restore or purge. actorUserID is nil for the purge job. The user
+// UUID is kept in the metadata because target_user_id is cleared once a
+// purged row is gone.
+func (s *userService) logUserLifecycle(ctx context.Context, tenantID int64, actorUserID *int64, user *model.User, eventType, description string) {
+	raw, _ := json.Marshal(map[string]string{"user_uuid": user.UserUUID.String()})
+	s.authEventService.Log(ctx, AuthEventInput{
+		TenantID:     tenantID,
+		ActorUserID:  actorUserID,
+		TargetUserID: &user.UserID,
+		IPAddress:    middleware.ClientIPFromContext(ctx),
+		UserAgent:    ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:     model.AuthEventCategoryUser,
+		EventType:    eventType,
+		Severity:     model.AuthEventSeverityWarn,
+		Result:       model.AuthEventResultSuccess,
+		Description:  ptr.Ptr(description),
+		Metadata:     datatypes.JSON(raw),
+	})
 }
 
 func (s *userService) AssignUserRoles(ctx context.Context, userUUID uuid.UUID, roleUUIDs []uuid.UUID, tenantID int64) (*UserServiceDataResult, error) {
@@ -791,7 +1356,7 @@ func (s *userService) AssignUserRoles(ctx context.Context, userUUID uuid.UUID, r
 		// Check if user exists and preload identities for tenant validation
 		user, err := txUserRepo.FindByUUID(userUUID, "UserIdentities")
 		if err != nil || user == nil {
-			return apperror.NewNotFound("user not found")
+			return apperror.WithCode(apperror.CodeUserNotFound, apperror.NewNotFound("user"))
 		}
 
 		// Validate tenant ownership - check if user has an identity in this tenant
@@ -807,6 +1372,8 @@ func (s *userService) AssignUserRoles(ctx context.Context, userUUID uuid.UUID, r
 		}
 
 		// Validate and assign roles
+		var added []uuid.UUID
+		var tenant *model.Tenant
 		for _, roleUUID := range roleUUIDs {
 			// Find role by UUID
 			role, err := txRoleRepo.FindByUUID(roleUUID)
@@ -817,6 +1384,20 @@ func (s *userService) AssignUserRoles(ctx context.Context, userUUID uuid.UUID, r
 				return apperror.NewNotFound("role not found")
 			}
 
+			// Roles of the tenant and inheritable roles of the tenants above
+			// it can be assigned
+			if role.TenantID != tenantID {
+				if tenant == nil {
+					tenant, err = s.tenantRepo.WithTx(tx).FindByID(tenantID)
+					if err != nil {
+						return err
+					}
+				}
+				if tenant == nil || !role.IsInheritable || !tenant.HasAncestor(role.TenantID) {
+					return apperror.NewNotFoundWithReason("role not found or access denied")
+				}
+			}
+
 			// Check if user already has this role
 			existingUserRole, err := txUserRoleRepo.FindByUserIDAndRoleID(user.UserID, role.RoleID)
 			if err != nil {
@@ -836,6 +1417,17 @@ func (s *userService) AssignUserRoles(ctx context.Context, userUUID uuid.UUID, r
 			if err != nil {
 				return err
 			}
+			added = append(added, role.RoleUUID)
+		}
+
+		// Record lifecycle event for the roles that were actually assigned
+		if len(added) > 0 {
+			if err := recordEvent(s.eventRepo.WithTx(tx), tenantID, RoleAssigned{
+				UserUUID:  userUUID,
+				RoleUUIDs: added,
+			}); err != nil {
+				return err
+			}
 		}
 
 		// Fetch user with roles for response
@@ -855,6 +1447,7 @@ func (s *userService) AssignUserRoles(ctx context.Context, userUUID uuid.UUID, r
 
 	span.SetStatus(codes.Ok, "")
 	s.invalidateUserCache(ctx, userWithRoles.UserIdentities)
+	s.cacheInvalidator.InvalidateUserPermissions(ctx, userUUID)
 
 	return toUserServiceDataResult(userWithRoles), nil
 }
@@ -874,7 +1467,7 @@ func (s *userService) RemoveUserRole(ctx context.Context, userUUID uuid.UUID, ro
 		// Check if user exists and preload identities for tenant validation
 		user, err := txUserRepo.FindByUUID(userUUID, "UserIdentities")
 		if err != nil || user == nil {
-			return apperror.NewNotFound("user not found")
+			return apperror.WithCode(apperror.CodeUserNotFound, apperror.NewNotFound("user"))
 		}
 
 		// Validate tenant ownership - check if user has an identity in this tenant
@@ -904,6 +1497,14 @@ func (s *userService) RemoveUserRole(ctx context.Context, userUUID uuid.UUID, ro
 			return err
 		}
 
+		// Record lifecycle event
+		if err := recordEvent(s.eventRepo.WithTx(tx), tenantID, RoleUnassigned{
+			UserUUID:  userUUID,
+			RoleUUIDs: []uuid.UUID{roleUUID},
+		}); err != nil {
+			return err
+		}
+
 		// Fetch user with roles for response
 		userWithRoles, err = txUserRepo.FindByUUID(userUUID, "UserIdentities.Client", "UserIdentities.Tenant", "Roles")
 		if err != nil {
@@ -921,6 +1522,7 @@ func (s *userService) RemoveUserRole(ctx context.Context, userUUID uuid.UUID, ro
 
 	span.SetStatus(codes.Ok, "")
 	s.invalidateUserCache(ctx, userWithRoles.UserIdentities)
+	s.cacheInvalidator.InvalidateUserPermissions(ctx, userUUID)
 
 	return toUserServiceDataResult(userWithRoles), nil
 }
@@ -945,6 +1547,7 @@ func toUserServiceDataResult(user *model.User) *UserServiceDataResult {
 		Metadata:           user.Metadata,
 		CreatedAt:          user.CreatedAt,
 		UpdatedAt:          user.UpdatedAt,
+		DeletedAt:          user.DeletedAt,
 	}
 
 	// Map Tenant if present - get from UserIdentities
@@ -995,7 +1598,7 @@ func (s *userService) GetUserRoles(ctx context.Context, userUUID uuid.UUID) ([]R
 			span.RecordError(err)
 		}
 		span.SetStatus(codes.Error, "user not found")
-		return nil, apperror.NewNotFound("user not found")
+		return nil, apperror.WithCode(apperror.CodeUserNotFound, apperror.NewNotFound("user"))
 	}
 
 	roles, err := s.userRepo.FindRoles(user.UserID)
@@ -1014,49 +1617,104 @@ func (s *userService) GetUserRoles(ctx context.Context, userUUID uuid.UUID) ([]R
 	return result, nil
 }
 
-func (s *userService) GetUserIdentities(ctx context.Context, userUUID uuid.UUID) ([]UserIdentityServiceDataResult, error) {
-	_, span := otel.Tracer("service").Start(ctx, "user.getUserIdentities")
+func (s *userService) ListUserRoles(ctx context.Context, filter UserServiceGetRolesFilter) (*RoleServiceGetResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "user.listUserRoles")
 	defer span.End()
-	span.SetAttributes(attribute.String("user.uuid", userUUID.String()))
+	span.SetAttributes(attribute.String("user.uuid", filter.UserUUID.String()), attribute.Int64("tenant.id", filter.TenantID))
 
-	user, err := s.userRepo.FindByUUID(userUUID)
+	user, err := s.userRepo.FindByUUID(filter.UserUUID)
 	if err != nil || user == nil {
 		if err != nil {
 			span.RecordError(err)
 		}
 		span.SetStatus(codes.Error, "user not found")
-		return nil, apperror.NewNotFound("user not found")
+		return nil, apperror.WithCode(apperror.CodeUserNotFound, apperror.NewNotFound("user"))
 	}
 
-	identities, err := s.userIdentityRepo.FindByUserID(user.UserID)
+	result, err := s.roleRepo.FindPaginated(repository.RoleRepositoryGetFilter{
+		Name:        filter.Name,
+		Description: filter.Description,
+		Status:      filter.Status,
+		TenantID:    filter.TenantID,
+		UserID:      &user.UserID,
+		Page:        filter.Page,
+		Limit:       filter.Limit,
+		SortBy:      filter.SortBy,
+		SortOrder:   filter.SortOrder,
+	})
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list user roles failed")
 		return nil, err
 	}
 
-	result := make([]UserIdentityServiceDataResult, len(identities))
-	for i, identity := range identities {
-		// Load Client if needed
-		var Client *ClientServiceDataResult
-		if identity.ClientID > 0 {
-			ac, err := s.clientRepo.FindByID(identity.ClientID)
-			if err == nil && ac != nil {
-				Client = ToClientServiceDataResult(ac)
-			}
+	roles := make([]RoleServiceDataResult, len(result.Data))
+	for i, role := range result.Data {
+		roles[i] = *toRoleServiceDataResult(&role)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return &RoleServiceGetResult{
+		Data:       roles,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}, nil
+}
+
+func (s *userService) ListUserIdentities(ctx context.Context, filter UserServiceGetIdentitiesFilter) (*UserIdentityServiceGetResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "user.listUserIdentities")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", filter.UserUUID.String()), attribute.Int64("tenant.id", filter.TenantID))
+
+	user, err := s.userRepo.FindByUUID(filter.UserUUID)
+	if err != nil || user == nil {
+		if err != nil {
+			span.RecordError(err)
 		}
+		span.SetStatus(codes.Error, "user not found")
+		return nil, apperror.WithCode(apperror.CodeUserNotFound, apperror.NewNotFound("user"))
+	}
+
+	result, err := s.userIdentityRepo.FindPaginated(repository.UserIdentityRepositoryGetFilter{
+		UserID:    user.UserID,
+		TenantID:  filter.TenantID,
+		Provider:  filter.Provider,
+		Page:      filter.Page,
+		Limit:     filter.Limit,
+		SortBy:    filter.SortBy,
+		SortOrder: filter.SortOrder,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list user identities failed")
+		return nil, err
+	}
 
-		result[i] = UserIdentityServiceDataResult{
+	identities := make([]UserIdentityServiceDataResult, len(result.Data))
+	for i, identity := range result.Data {
+		identities[i] = UserIdentityServiceDataResult{
 			UserIdentityUUID: identity.UserIdentityUUID,
 			Provider:         identity.Provider,
 			Sub:              identity.Sub,
 			Metadata:         identity.Metadata,
-			Client:           Client,
 			CreatedAt:        identity.CreatedAt,
 			UpdatedAt:        identity.UpdatedAt,
 		}
+		if identity.Client != nil {
+			identities[i].Client = ToClientServiceDataResult(identity.Client)
+		}
 	}
 
 	span.SetStatus(codes.Ok, "")
-	return result, nil
+	return &UserIdentityServiceGetResult{
+		Data:       identities,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}, nil
 }
 
 // FindBySubAndClientID resolves a *model.User from a JWT sub claim and client
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/email"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
+)
+
+// hashAccountToken returns the hex SHA-256 of a token emailed to the owner of
+// an account, such as an account deletion cancellation token, as stored in
+// UserToken.Token.
+func hashAccountToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// revokeUserTokensOfType revokes the user's unrevoked tokens of the type.
+func revokeUserTokensOfType(userTokenRepo repository.UserTokenRepository, userID int64, tokenType string) error {
+	tokens, err := userTokenRepo.FindByUserIDAndTokenType(userID, tokenType)
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		if token.IsRevoked {
+			continue
+		}
+		if err := userTokenRepo.RevokeByUUID(token.UserTokenUUID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifyAccount sends the user the email of the named template, logging
+// rather than returning failures: the change the email confirms is already
+// made.
+func (s *userService) notifyAccount(ctx context.Context, user *model.User, templateName string, data any) {
+	if err := sendTemplateEmail(ctx, s.emailTemplateRepo, user.Email, templateName, data); err != nil {
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "account_email_failure",
+			UserID:    user.UserUUID.String(),
+			Details:   fmt.Sprintf("Failed to send %s email: %v", templateName, err),
+			Severity:  "HIGH",
+			Timestamp: time.Now(),
+		})
+	}
+}
+
+// sendTemplateEmail renders the named email template with data and sends it
+// to the address.
+func sendTemplateEmail(ctx context.Context, emailTemplateRepo repository.EmailTemplateRepository, to, templateName string, data any) error {
+	// Get email template from DB
+	templateEntity, err := emailTemplateRepo.FindByName(templateName)
+	if err != nil {
+		return apperror.NewInternal("failed to fetch email template", err)
+	}
+	if templateEntity == nil {
+		return apperror.NewNotFoundWithReason("email template " + templateName + " not found")
+	}
+
+	// Parse HTML template
+	tmpl, err := template.New("account_html").Parse(templateEntity.BodyHTML)
+	if err != nil {
+		return apperror.NewInternal("failed to parse HTML email template", err)
+	}
+	var bodyHTML bytes.Buffer
+	if err := tmpl.Execute(&bodyHTML, data); err != nil {
+		return apperror.NewInternal("failed to execute HTML email template", err)
+	}
+
+	// Parse plain-text template if available
+	var bodyPlainStr string
+	if templateEntity.BodyPlain != nil {
+		tmplPlain, err := template.New("account_plain").Parse(*templateEntity.BodyPlain)
+		if err != nil {
+			return apperror.NewInternal("failed to parse plain email template", err)
+		}
+		var bodyPlain bytes.Buffer
+		if err := tmplPlain.Execute(&bodyPlain, data); err != nil {
+			return apperror.NewInternal("failed to execute plain email template", err)
+		}
+		bodyPlainStr = bodyPlain.String()
+	}
+
+	// Send email
+	return email.SendEmail(ctx, email.SendEmailParams{
+		To:        to,
+		Subject:   templateEntity.Subject,
+		BodyHTML:  bodyHTML.String(),
+		BodyPlain: bodyPlainStr,
+	})
+}
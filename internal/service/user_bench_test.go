@@ -0,0 +1,136 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/maintainerd/auth/internal/repository"
+)
+
+// BenchmarkUserList compares listing a page of users with their identities,
+// clients and roles loaded per row against the batched preloads of
+// UserRepositoryGetFilter. queries/op is the number of SQL statements run
+// for one page: it grows with the page size per row and stays constant when
+// batched.
+//
+//	go test ./internal/service -run '^$' -bench BenchmarkUserList
+func BenchmarkUserList(b *testing.B) {
+	for _, size := range []int{10, 50, 100} {
+		b.Run(fmt.Sprintf("per_row/users=%d", size), func(b *testing.B) {
+			benchmarkUserList(b, size, false)
+		})
+		b.Run(fmt.Sprintf("batched/users=%d", size), func(b *testing.B) {
+			benchmarkUserList(b, size, true)
+		})
+	}
+}
+
+func benchmarkUserList(b *testing.B, size int, batched bool) {
+	db, mock := newMockGormDB(b)
+	mock.MatchExpectationsInOrder(false)
+
+	userRepo := repository.NewUserRepository(db)
+	identityRepo := repository.NewUserIdentityRepository(db)
+	clientRepo := repository.NewClientRepository(db)
+
+	tenantID := int64(1)
+	filter := repository.UserRepositoryGetFilter{
+		TenantID:          &tenantID,
+		Page:              1,
+		Limit:             size,
+		IncludeIdentities: batched,
+		IncludeRoles:      batched,
+	}
+
+	var queries int
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		if batched {
+			queries = expectBatchedUserList(mock, size)
+		} else {
+			queries = expectPerRowUserList(mock, size)
+		}
+		b.StartTimer()
+
+		page, err := userRepo.FindPaginated(filter)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if !batched {
+			for j := range page.Data {
+				user := &page.Data[j]
+				if user.UserIdentities, err = identityRepo.FindByUserID(user.UserID); err != nil {
+					b.Fatal(err)
+				}
+				for k := range user.UserIdentities {
+					if user.UserIdentities[k].Client, err = clientRepo.FindByID(user.UserIdentities[k].ClientID); err != nil {
+						b.Fatal(err)
+					}
+				}
+				if user.Roles, err = userRepo.FindRoles(user.UserID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+
+		b.StopTimer()
+		if err := mock.ExpectationsWereMet(); err != nil {
+			b.Fatal(err)
+		}
+		if first := page.Data[0]; len(first.UserIdentities) != 1 || first.UserIdentities[0].Client == nil || len(first.Roles) != 1 {
+			b.Fatal("relations were not loaded")
+		}
+		b.StartTimer()
+	}
+	b.ReportMetric(float64(queries), "queries/op")
+}
+
+// expectUserPage registers the count and page queries shared by both ways of
+// listing and returns how many queries were expected.
+func expectUserPage(mock sqlmock.Sqlmock, size int) int {
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "users"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(size))
+
+	users := sqlmock.NewRows([]string{"user_id", "username"})
+	for id := 1; id <= size; id++ {
+		users.AddRow(id, fmt.Sprintf("user%d", id))
+	}
+	mock.ExpectQuery(`FROM "users" .*LIMIT`).WillReturnRows(users)
+	return 2
+}
+
+func expectPerRowUserList(mock sqlmock.Sqlmock, size int) int {
+	queries := expectUserPage(mock, size)
+	for id := 1; id <= size; id++ {
+		mock.ExpectQuery(`FROM "user_identities"`).
+			WillReturnRows(sqlmock.NewRows([]string{"user_identity_id", "user_id", "tenant_id", "client_id"}).AddRow(id, id, 1, 1))
+		mock.ExpectQuery(`FROM "clients"`).
+			WillReturnRows(sqlmock.NewRows([]string{"client_id", "name"}).AddRow(1, "default"))
+		mock.ExpectQuery(`FROM "roles"`).
+			WillReturnRows(sqlmock.NewRows([]string{"role_id", "name"}).AddRow(1, "registered"))
+		queries += 3
+	}
+	return queries
+}
+
+func expectBatchedUserList(mock sqlmock.Sqlmock, size int) int {
+	queries := expectUserPage(mock, size)
+
+	identities := sqlmock.NewRows([]string{"user_identity_id", "user_id", "tenant_id", "client_id"})
+	userRoles := sqlmock.NewRows([]string{"user_id", "role_id"})
+	for id := 1; id <= size; id++ {
+		identities.AddRow(id, id, 1, 1)
+		userRoles.AddRow(id, 1)
+	}
+	mock.ExpectQuery(`FROM "user_identities" WHERE .*tenant_id = `).WillReturnRows(identities)
+	mock.ExpectQuery(`FROM "clients"`).
+		WillReturnRows(sqlmock.NewRows([]string{"client_id", "name"}).AddRow(1, "default"))
+	mock.ExpectQuery(`FROM "user_roles"`).WillReturnRows(userRoles)
+	mock.ExpectQuery(`FROM "roles" WHERE .*roles.tenant_id = `).
+		WillReturnRows(sqlmock.NewRows([]string{"role_id", "name"}).AddRow(1, "registered"))
+	return queries + 4
+}
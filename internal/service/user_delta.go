@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// userDeltaEventTypes are the lifecycle events that change what a directory
+// consumer holds for a user.
+var userDeltaEventTypes = []string{
+	model.EventTypeUserCreated,
+	model.EventTypeUserUpdated,
+	model.EventTypeUserDeleted,
+	model.EventTypeUserRestored,
+	model.EventTypeUserPurged,
+}
+
+// UserServiceDeltaFilter selects a page of user changes after Since, a
+// cursor returned by a previous call. An empty Since starts from the
+// beginning of the event feed.
+type UserServiceDeltaFilter struct {
+	TenantID int64
+	Since    string
+	Limit    int
+}
+
+// UserServiceDeltaEntry is the current state of a changed user. Deleted
+// entries are tombstones: User is nil and DeletedAt is when the user was
+// deleted or erased.
+type UserServiceDeltaEntry struct {
+	UserUUID  uuid.UUID
+	Deleted   bool
+	DeletedAt *time.Time
+	User      *UserServiceDataResult
+}
+
+// UserServiceDeltaResult is a page of user changes and the cursor to resume
+// from. NextCursor is always set; it echoes Since when nothing changed.
+type UserServiceDeltaResult struct {
+	Data       []UserServiceDeltaEntry
+	NextCursor string
+	HasMore    bool
+}
+
+func (s *userService) Delta(ctx context.Context, filter UserServiceDeltaFilter) (*UserServiceDeltaResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "user.delta")
+	defer span.End()
+	span.SetAttributes(attribute.Int64("tenant.id", filter.TenantID))
+
+	afterID, err := decodeEventCursor(filter.Since)
+	if err != nil {
+		span.SetStatus(codes.Error, "invalid cursor")
+		return nil, err
+	}
+
+	// Read the user events of the feed window and fetch one extra row to
+	// learn whether another page follows.
+	before := time.Now().Add(-eventFeedSettleWindow)
+	events, err := s.eventRepo.FindFeed(repository.EventRepositoryFeedFilter{
+		TenantID: filter.TenantID,
+		AfterID:  afterID,
+		Types:    userDeltaEventTypes,
+		Before:   &before,
+		Limit:    filter.Limit + 1,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "user delta failed")
+		return nil, err
+	}
+
+	hasMore := len(events) > filter.Limit
+	if hasMore {
+		events = events[:filter.Limit]
+	}
+
+	// A user changed several times in the window is returned once, at the
+	// position of its last change. Purges are remembered for tombstones of
+	// users that no longer exist.
+	lastIndex := make(map[uuid.UUID]int, len(events))
+	purgedAt := make(map[uuid.UUID]time.Time)
+	for i, e := range events {
+		lastIndex[e.AggregateUUID] = i
+		if e.EventType == model.EventTypeUserPurged {
+			purgedAt[e.AggregateUUID] = e.OccurredAt
+		}
+	}
+	var order []uuid.UUID
+	ids := make([]string, 0, len(lastIndex))
+	for i, e := range events {
+		if lastIndex[e.AggregateUUID] == i {
+			order = append(order, e.AggregateUUID)
+			ids = append(ids, e.AggregateUUID.String())
+		}
+	}
+
+	users := map[uuid.UUID]*model.User{}
+	if len(ids) > 0 {
+		found, err := s.userRepo.FindByUUIDs(ids, "UserIdentities.Client", "UserIdentities.Tenant", "Roles")
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "user delta failed")
+			return nil, err
+		}
+		for i := range found {
+			users[found[i].UserUUID] = &found[i]
+		}
+	}
+
+	data := make([]UserServiceDeltaEntry, 0, len(order))
+	for _, id := range order {
+		data = append(data, userDeltaEntry(id, users[id], filter.TenantID, purgedAt, events[lastIndex[id]].OccurredAt))
+	}
+
+	nextID := afterID
+	if len(events) > 0 {
+		nextID = events[len(events)-1].EventID
+	}
+
+	span.SetAttributes(attribute.Int("user.delta.count", len(data)))
+	span.SetStatus(codes.Ok, "")
+	return &UserServiceDeltaResult{
+		Data:       data,
+		NextCursor: encodeEventCursor(nextID),
+		HasMore:    hasMore,
+	}, nil
+}
+
+// userDeltaEntry builds the entry for one changed user. Users that are gone,
+// soft-deleted or no longer part of the tenant become tombstones.
+func userDeltaEntry(id uuid.UUID, user *model.User, tenantID int64, purgedAt map[uuid.UUID]time.Time, lastChange time.Time) UserServiceDeltaEntry {
+	inTenant := false
+	if user != nil {
+		for _, identity := range user.UserIdentities {
+			if identity.TenantID == tenantID {
+				inTenant = true
+				break
+			}
+		}
+	}
+
+	switch {
+	case inTenant && user.DeletedAt == nil:
+		return UserServiceDeltaEntry{UserUUID: id, User: toUserServiceDataResult(user)}
+	case inTenant:
+		return UserServiceDeltaEntry{UserUUID: id, Deleted: true, DeletedAt: user.DeletedAt}
+	}
+
+	deletedAt := lastChange
+	if t, ok := purgedAt[id]; ok {
+		deletedAt = t
+	}
+	return UserServiceDeltaEntry{UserUUID: id, Deleted: true, DeletedAt: &deletedAt}
+}
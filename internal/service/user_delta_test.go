@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserService_Delta(t *testing.T) {
+	active, edited, softDeleted, purged, moved := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	deletedAt := time.Now().Add(-time.Hour)
+	purgedAt := time.Now().Add(-time.Minute)
+
+	events := []model.Event{
+		{EventID: 11, EventType: model.EventTypeUserCreated, AggregateUUID: edited},
+		{EventID: 12, EventType: model.EventTypeUserCreated, AggregateUUID: active},
+		{EventID: 13, EventType: model.EventTypeUserDeleted, AggregateUUID: softDeleted},
+		{EventID: 14, EventType: model.EventTypeUserPurged, AggregateUUID: purged, OccurredAt: purgedAt},
+		{EventID: 15, EventType: model.EventTypeUserUpdated, AggregateUUID: edited},
+		{EventID: 16, EventType: model.EventTypeUserUpdated, AggregateUUID: moved},
+	}
+	tenantIdentity := []model.UserIdentity{{TenantID: 1}}
+	users := []model.User{
+		{UserUUID: active, Username: "active", UserIdentities: tenantIdentity},
+		{UserUUID: edited, Username: "edited", UserIdentities: tenantIdentity},
+		{UserUUID: softDeleted, Status: model.StatusDeleted, DeletedAt: &deletedAt, UserIdentities: tenantIdentity},
+		{UserUUID: moved, Username: "moved", UserIdentities: []model.UserIdentity{{TenantID: 2}}},
+	}
+
+	var gotFilter repository.EventRepositoryFeedFilter
+	var gotIDs []string
+	eventRepo := &mockEventRepo{findFeedFn: func(f repository.EventRepositoryFeedFilter) ([]model.Event, error) {
+		gotFilter = f
+		return events, nil
+	}}
+	userRepo := &mockUserRepo{findByUUIDsFn: func(ids []string, _ ...string) ([]model.User, error) {
+		gotIDs = ids
+		return users, nil
+	}}
+	svc := &userService{userRepo: userRepo, eventRepo: eventRepo}
+
+	res, err := svc.Delta(context.Background(), UserServiceDeltaFilter{TenantID: 1, Since: encodeEventCursor(10), Limit: 5})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(10), gotFilter.AfterID)
+	assert.Equal(t, 6, gotFilter.Limit)
+	assert.Equal(t, userDeltaEventTypes, gotFilter.Types)
+	require.NotNil(t, gotFilter.Before)
+
+	// The sixth event only signals another page
+	assert.True(t, res.HasMore)
+	assert.Equal(t, encodeEventCursor(15), res.NextCursor)
+	assert.Len(t, gotIDs, 4)
+
+	// Each user once, in the order of its last change
+	require.Len(t, res.Data, 4)
+	assert.Equal(t, active, res.Data[0].UserUUID)
+	assert.Equal(t, "active", res.Data[0].User.Username)
+	assert.False(t, res.Data[0].Deleted)
+
+	assert.Equal(t, softDeleted, res.Data[1].UserUUID)
+	assert.True(t, res.Data[1].Deleted)
+	assert.Nil(t, res.Data[1].User)
+	assert.Equal(t, &deletedAt, res.Data[1].DeletedAt)
+
+	assert.Equal(t, purged, res.Data[2].UserUUID)
+	assert.True(t, res.Data[2].Deleted)
+	assert.Equal(t, purgedAt, *res.Data[2].DeletedAt)
+
+	assert.Equal(t, edited, res.Data[3].UserUUID)
+	assert.Equal(t, "edited", res.Data[3].User.Username)
+}
+
+func TestUserService_Delta_Empty(t *testing.T) {
+	svc := &userService{userRepo: &mockUserRepo{}, eventRepo: &mockEventRepo{}}
+	since := encodeEventCursor(42)
+	res, err := svc.Delta(context.Background(), UserServiceDeltaFilter{TenantID: 1, Since: since, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, res.Data)
+	assert.False(t, res.HasMore)
+	assert.Equal(t, since, res.NextCursor)
+}
+
+func TestUserService_Delta_Errors(t *testing.T) {
+	svc := &userService{userRepo: &mockUserRepo{}, eventRepo: &mockEventRepo{}}
+	_, err := svc.Delta(context.Background(), UserServiceDeltaFilter{TenantID: 1, Since: "bad", Limit: 10})
+	var ve *apperror.ValidationError
+	require.ErrorAs(t, err, &ve)
+
+	svc.eventRepo = &mockEventRepo{findFeedFn: func(repository.EventRepositoryFeedFilter) ([]model.Event, error) {
+		return nil, errors.New("db down")
+	}}
+	_, err = svc.Delta(context.Background(), UserServiceDeltaFilter{TenantID: 1, Limit: 10})
+	require.Error(t, err)
+
+	svc.eventRepo = &mockEventRepo{findFeedFn: func(repository.EventRepositoryFeedFilter) ([]model.Event, error) {
+		return []model.Event{{EventID: 1, AggregateUUID: uuid.New()}}, nil
+	}}
+	svc.userRepo = &mockUserRepo{findByUUIDsFn: func([]string, ...string) ([]model.User, error) {
+		return nil, errors.New("db down")
+	}}
+	_, err = svc.Delta(context.Background(), UserServiceDeltaFilter{TenantID: 1, Limit: 10})
+	require.Error(t, err)
+}
@@ -0,0 +1,591 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/logging"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	// UserImportMaxRows is the largest number of users one import may hold.
+	UserImportMaxRows = 100000
+	// UserImportSyncLimit is the largest import that is processed within the
+	// request. Larger imports run in the background and are polled.
+	UserImportSyncLimit = 100
+	// userImportChunkSize is the number of rows inserted per transaction.
+	userImportChunkSize = 500
+	// userImportMaxRowErrors caps the row-level report stored on a job.
+	userImportMaxRowErrors = 1000
+)
+
+// Reasons a row is reported in a user import (UserImportRowError.Reason).
+const (
+	UserImportReasonInvalid   = "invalid"
+	UserImportReasonDuplicate = "duplicate"
+	UserImportReasonFailed    = "failed"
+)
+
+// UserImportRow is one user read from an import file. Row is the 1-based
+// position of the user in the file. Invalid is set by the caller when the
+// row failed request validation; such rows are reported and skipped.
+type UserImportRow struct {
+	Row          int
+	Username     string
+	Fullname     string
+	Email        string
+	Phone        string
+	Password     string
+	PasswordHash string
+	Status       string
+	Metadata     datatypes.JSON
+	Invalid      string
+}
+
+// UserImportRowError reports a row that was not imported.
+type UserImportRowError struct {
+	Row      int    `json:"row"`
+	Username string `json:"username,omitempty"`
+	Reason   string `json:"reason"`
+	Message  string `json:"message"`
+}
+
+type UserImportServiceDataResult struct {
+	UserImportJobUUID uuid.UUID
+	Format            string
+	DryRun            bool
+	Status            string
+	TotalRows         int
+	ProcessedRows     int
+	ImportedRows      int
+	DuplicateRows     int
+	InvalidRows       int
+	FailedRows        int
+	RowErrors         []UserImportRowError
+	Error             *string
+	StartedAt         *time.Time
+	CompletedAt       *time.Time
+	CreatedAt         time.Time
+}
+
+type UserImportService interface {
+	// Import creates the users of an import file in the tenant. Imports of
+	// up to UserImportSyncLimit rows are processed before Import returns;
+	// larger ones are queued and returned pending. With dryRun every row is
+	// validated and checked for duplicates but nothing is created.
+	Import(ctx context.Context, tenantID int64, actorUserUUID uuid.UUID, format string, dryRun bool, rows []UserImportRow) (*UserImportServiceDataResult, error)
+	// GetJob returns the progress and report of an import.
+	GetJob(ctx context.Context, jobUUID uuid.UUID, tenantID int64) (*UserImportServiceDataResult, error)
+}
+
+type userImportService struct {
+	db                  *gorm.DB
+	userImportJobRepo   repository.UserImportJobRepository
+	userRepo            repository.UserRepository
+	userIdentityRepo    repository.UserIdentityRepository
+	userRoleRepo        repository.UserRoleRepository
+	roleRepo            repository.RoleRepository
+	clientRepo          repository.ClientRepository
+	tenantSettingRepo   repository.TenantSettingRepository
+	securitySettingRepo repository.SecuritySettingRepository
+	eventRepo           repository.EventRepository
+	// runAsync starts a background import. Tests replace it to run inline.
+	runAsync func(func())
+}
+
+func NewUserImportService(
+	db *gorm.DB,
+	userImportJobRepo repository.UserImportJobRepository,
+	userRepo repository.UserRepository,
+	userIdentityRepo repository.UserIdentityRepository,
+	userRoleRepo repository.UserRoleRepository,
+	roleRepo repository.RoleRepository,
+	clientRepo repository.ClientRepository,
+	tenantSettingRepo repository.TenantSettingRepository,
+	securitySettingRepo repository.SecuritySettingRepository,
+	eventRepo repository.EventRepository,
+) UserImportService {
+	return &userImportService{
+		db:                  db,
+		userImportJobRepo:   userImportJobRepo,
+		userRepo:            userRepo,
+		userIdentityRepo:    userIdentityRepo,
+		userRoleRepo:        userRoleRepo,
+		roleRepo:            roleRepo,
+		clientRepo:          clientRepo,
+		tenantSettingRepo:   tenantSettingRepo,
+		securitySettingRepo: securitySettingRepo,
+		eventRepo:           eventRepo,
+		runAsync:            func(f func()) { go f() },
+	}
+}
+
+func (s *userImportService) Import(ctx context.Context, tenantID int64, actorUserUUID uuid.UUID, format string, dryRun bool, rows []UserImportRow) (*UserImportServiceDataResult, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "userImport.import")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int64("tenant.id", tenantID),
+		attribute.Int("import.rows", len(rows)),
+		attribute.Bool("import.dry_run", dryRun),
+	)
+
+	if len(rows) == 0 {
+		err := apperror.NewValidation("the import contains no users")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "import users failed")
+		return nil, err
+	}
+	if len(rows) > UserImportMaxRows {
+		err := apperror.NewValidation(fmt.Sprintf("an import may contain at most %d users", UserImportMaxRows))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "import users failed")
+		return nil, err
+	}
+
+	actor, err := s.userRepo.FindByUUID(actorUserUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "import users failed")
+		return nil, err
+	}
+
+	job := &model.UserImportJob{
+		TenantID:  tenantID,
+		Format:    format,
+		DryRun:    dryRun,
+		Status:    model.UserImportStatusPending,
+		TotalRows: len(rows),
+		RowErrors: datatypes.JSON([]byte(`[]`)),
+	}
+	if actor != nil {
+		job.CreatedBy = &actor.UserID
+	}
+	if _, err := s.userImportJobRepo.Create(job); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "import users failed")
+		return nil, err
+	}
+
+	if len(rows) <= UserImportSyncLimit {
+		s.run(ctx, job, rows)
+		span.SetStatus(codes.Ok, "")
+		return toUserImportServiceDataResult(job), nil
+	}
+
+	result := toUserImportServiceDataResult(job)
+	bgCtx := context.WithoutCancel(ctx)
+	s.runAsync(func() { s.run(bgCtx, job, rows) })
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+func (s *userImportService) GetJob(ctx context.Context, jobUUID uuid.UUID, tenantID int64) (*UserImportServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "userImport.get")
+	defer span.End()
+	span.SetAttributes(attribute.String("import.uuid", jobUUID.String()))
+
+	job, err := s.userImportJobRepo.FindByUUIDAndTenantID(jobUUID.String(), tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get user import failed")
+		return nil, err
+	}
+	if job == nil {
+		err := apperror.NewNotFound("user import")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "user import not found")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toUserImportServiceDataResult(job), nil
+}
+
+// userImportRun is the state of an import while its chunks are processed.
+// Usernames and emails seen earlier in the file are remembered so that
+// duplicates within the file are reported as well.
+type userImportRun struct {
+	job       *model.UserImportJob
+	client    *model.Client
+	role      *model.Role
+	hash      security.PasswordHashParams
+	usernames map[string]struct{}
+	emails    map[string]struct{}
+	rowErrors []UserImportRowError
+}
+
+func (r *userImportRun) reject(row UserImportRow, reason, message string) {
+	switch reason {
+	case UserImportReasonInvalid:
+		r.job.InvalidRows++
+	case UserImportReasonDuplicate:
+		r.job.DuplicateRows++
+	default:
+		r.job.FailedRows++
+	}
+	if len(r.rowErrors) < userImportMaxRowErrors {
+		r.rowErrors = append(r.rowErrors, UserImportRowError{
+			Row:      row.Row,
+			Username: row.Username,
+			Reason:   reason,
+			Message:  message,
+		})
+	}
+}
+
+// run processes the rows of a job chunk by chunk and saves the job's
+// progress after every chunk. A failure to resolve the tenant's defaults
+// fails the whole job; a chunk that cannot be inserted fails its rows only.
+func (s *userImportService) run(ctx context.Context, job *model.UserImportJob, rows []UserImportRow) {
+	ctx, span := otel.Tracer("service").Start(ctx, "userImport.run")
+	defer span.End()
+	span.SetAttributes(attribute.String("import.uuid", job.UserImportJobUUID.String()))
+
+	now := time.Now()
+	job.Status = model.UserImportStatusRunning
+	job.StartedAt = &now
+	s.saveProgress(ctx, job, nil)
+
+	state := &userImportRun{
+		job:       job,
+		usernames: make(map[string]struct{}, len(rows)),
+		emails:    make(map[string]struct{}, len(rows)),
+	}
+
+	var err error
+	state.client, err = s.clientRepo.FindDefaultByTenantID(job.TenantID)
+	if err == nil && state.client == nil {
+		err = apperror.NewNotFoundWithReason("default auth client not found for tenant")
+	}
+	if err == nil {
+		state.role, err = s.findDefaultRole(job.TenantID)
+	}
+	if err == nil {
+		state.hash, err = tenantPasswordHashParams(s.securitySettingRepo, job.TenantID)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "user import failed")
+		s.finish(ctx, job, state.rowErrors, err)
+		return
+	}
+
+	for start := 0; start < len(rows); start += userImportChunkSize {
+		end := min(start+userImportChunkSize, len(rows))
+		s.processChunk(ctx, state, rows[start:end])
+		job.ProcessedRows = end
+		s.saveProgress(ctx, job, state.rowErrors)
+	}
+
+	s.finish(ctx, job, state.rowErrors, nil)
+	span.SetStatus(codes.Ok, "")
+}
+
+// processChunk validates a chunk of rows, skips duplicates and creates the
+// remaining users, their default identity and default role in one
+// transaction.
+func (s *userImportService) processChunk(ctx context.Context, state *userImportRun, rows []UserImportRow) {
+	candidates := make([]UserImportRow, 0, len(rows))
+	for _, row := range rows {
+		if row.Invalid != "" {
+			state.reject(row, UserImportReasonInvalid, row.Invalid)
+			continue
+		}
+		if row.PasswordHash != "" {
+			if err := security.ValidatePasswordHash(row.PasswordHash); err != nil {
+				state.reject(row, UserImportReasonInvalid, "password_hash must be a bcrypt or argon2id hash")
+				continue
+			}
+		}
+		if _, ok := state.usernames[row.Username]; ok {
+			state.reject(row, UserImportReasonDuplicate, "username appears earlier in the file")
+			continue
+		}
+		if _, ok := state.emails[row.Email]; ok && row.Email != "" {
+			state.reject(row, UserImportReasonDuplicate, "email appears earlier in the file")
+			continue
+		}
+		state.usernames[row.Username] = struct{}{}
+		if row.Email != "" {
+			state.emails[row.Email] = struct{}{}
+		}
+		candidates = append(candidates, row)
+	}
+
+	candidates = s.skipExisting(state, candidates)
+
+	users := make([]model.User, 0, len(candidates))
+	kept := make([]UserImportRow, 0, len(candidates))
+	for _, row := range candidates {
+		user, reason, message := s.buildUser(state, row)
+		if reason != "" {
+			state.reject(row, reason, message)
+			continue
+		}
+		users = append(users, *user)
+		kept = append(kept, row)
+	}
+
+	if state.job.DryRun || len(users) == 0 {
+		state.job.ImportedRows += len(users)
+		return
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.userRepo.WithTx(tx).CreateBatch(users); err != nil {
+			return err
+		}
+
+		identities := make([]model.UserIdentity, len(users))
+		userRoles := make([]model.UserRole, len(users))
+		for i := range users {
+			identities[i] = model.UserIdentity{
+				TenantID: state.job.TenantID,
+				UserID:   users[i].UserID,
+				ClientID: state.client.ClientID,
+				Provider: model.ProviderDefault,
+				Sub:      users[i].UserUUID.String(),
+				Metadata: datatypes.JSON([]byte(`{}`)),
+			}
+			userRoles[i] = model.UserRole{
+				UserID: users[i].UserID,
+				RoleID: state.role.RoleID,
+			}
+		}
+		if err := s.userIdentityRepo.WithTx(tx).CreateBatch(identities); err != nil {
+			return err
+		}
+		if err := s.userRoleRepo.WithTx(tx).CreateBatch(userRoles); err != nil {
+			return err
+		}
+
+		txEventRepo := s.eventRepo.WithTx(tx)
+		for i := range users {
+			if err := recordUserCreatedEvents(txEventRepo, state.job.TenantID, &users[i], []uuid.UUID{state.role.RoleUUID}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logging.Logger(logging.ComponentDB).ErrorContext(ctx, "user import chunk failed",
+			"import_uuid", state.job.UserImportJobUUID.String(), "error", err)
+		for _, row := range kept {
+			state.reject(row, UserImportReasonFailed, "the user could not be created")
+		}
+		return
+	}
+	state.job.ImportedRows += len(users)
+}
+
+// skipExisting reports and drops the rows whose username or email already
+// belongs to a user, with the same uniqueness rules as creating a single
+// user.
+func (s *userImportService) skipExisting(state *userImportRun, rows []UserImportRow) []UserImportRow {
+	if len(rows) == 0 {
+		return rows
+	}
+
+	usernames := make([]string, 0, len(rows))
+	emails := make([]string, 0, len(rows))
+	for _, row := range rows {
+		usernames = append(usernames, row.Username)
+		if row.Email != "" {
+			emails = append(emails, row.Email)
+		}
+	}
+
+	existing, err := s.userRepo.FindByUsernamesOrEmails(usernames, emails)
+	if err != nil {
+		for _, row := range rows {
+			state.reject(row, UserImportReasonFailed, "existing users could not be checked")
+		}
+		return nil
+	}
+
+	takenUsernames := make(map[string]struct{}, len(existing))
+	takenEmails := make(map[string]struct{}, len(existing))
+	for _, u := range existing {
+		takenUsernames[u.Username] = struct{}{}
+		if u.Email != "" {
+			takenEmails[u.Email] = struct{}{}
+		}
+	}
+
+	kept := rows[:0]
+	for _, row := range rows {
+		if _, ok := takenUsernames[row.Username]; ok {
+			state.reject(row, UserImportReasonDuplicate, "username already exists")
+			continue
+		}
+		if _, ok := takenEmails[row.Email]; ok && row.Email != "" {
+			state.reject(row, UserImportReasonDuplicate, "email already exists")
+			continue
+		}
+		kept = append(kept, row)
+	}
+	return kept
+}
+
+// buildUser turns a row into a user, validating its metadata against the
+// tenant schema and hashing a plaintext password. Pre-hashed passwords are
+// stored as given. A dry run skips hashing. On failure the reason and
+// message of the row error are returned.
+func (s *userImportService) buildUser(state *userImportRun, row UserImportRow) (*model.User, string, string) {
+	metadata := row.Metadata
+	if len(metadata) > 0 {
+		var err error
+		metadata, err = applyUserMetadataPatch(s.tenantSettingRepo, state.job.TenantID, nil, metadata)
+		if err != nil {
+			var validation *apperror.ValidationError
+			if errors.As(err, &validation) {
+				return nil, UserImportReasonInvalid, err.Error()
+			}
+			return nil, UserImportReasonFailed, "metadata could not be validated"
+		}
+	} else {
+		metadata = datatypes.JSON([]byte(`{}`))
+	}
+
+	var password *string
+	switch {
+	case row.PasswordHash != "":
+		password = &row.PasswordHash
+	case row.Password != "" && !state.job.DryRun:
+		hashed, err := security.HashPasswordWithParams([]byte(row.Password), state.hash)
+		if err != nil {
+			return nil, UserImportReasonFailed, "password could not be hashed"
+		}
+		hashedStr := string(hashed)
+		password = &hashedStr
+	}
+
+	status := row.Status
+	if status == "" {
+		status = model.StatusActive
+	}
+
+	return &model.User{
+		Username: row.Username,
+		Fullname: row.Fullname,
+		Email:    row.Email,
+		Phone:    row.Phone,
+		Password: password,
+		Status:   status,
+		Metadata: metadata,
+	}, "", ""
+}
+
+// saveProgress writes the job's counters and row errors. Failures are
+// logged only; the import carries on and the next save catches up.
+func (s *userImportService) saveProgress(ctx context.Context, job *model.UserImportJob, rowErrors []UserImportRowError) {
+	if rowErrors == nil {
+		rowErrors = []UserImportRowError{}
+	}
+	raw, err := json.Marshal(rowErrors)
+	if err == nil {
+		job.RowErrors = datatypes.JSON(raw)
+	}
+
+	_, err = s.userImportJobRepo.UpdateByID(job.UserImportJobID, map[string]any{
+		"status":         job.Status,
+		"processed_rows": job.ProcessedRows,
+		"imported_rows":  job.ImportedRows,
+		"duplicate_rows": job.DuplicateRows,
+		"invalid_rows":   job.InvalidRows,
+		"failed_rows":    job.FailedRows,
+		"row_errors":     job.RowErrors,
+		"error":          job.Error,
+		"started_at":     job.StartedAt,
+		"completed_at":   job.CompletedAt,
+	})
+	if err != nil {
+		logging.Logger(logging.ComponentDB).ErrorContext(ctx, "saving user import progress failed",
+			"import_uuid", job.UserImportJobUUID.String(), "error", err)
+	}
+}
+
+// finish marks the job completed, or failed with runErr, and saves it.
+// Only not-found and validation messages are stored; other errors are
+// recorded on the trace.
+func (s *userImportService) finish(ctx context.Context, job *model.UserImportJob, rowErrors []UserImportRowError, runErr error) {
+	now := time.Now()
+	job.CompletedAt = &now
+	job.Status = model.UserImportStatusCompleted
+	if runErr != nil {
+		job.Status = model.UserImportStatusFailed
+		msg := "the import could not be started"
+		var notFound *apperror.NotFoundError
+		var validation *apperror.ValidationError
+		if errors.As(runErr, &notFound) || errors.As(runErr, &validation) {
+			msg = runErr.Error()
+		}
+		job.Error = &msg
+	}
+	s.saveProgress(ctx, job, rowErrors)
+}
+
+// findDefaultRole finds the role new users of the tenant are given: the
+// role marked default, or else the registered role.
+func (s *userImportService) findDefaultRole(tenantID int64) (*model.Role, error) {
+	result, err := s.roleRepo.FindPaginated(repository.RoleRepositoryGetFilter{
+		IsDefault: &[]bool{true}[0],
+		TenantID:  tenantID,
+		Page:      1,
+		Limit:     1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Data) > 0 {
+		return &result.Data[0], nil
+	}
+
+	role, err := s.roleRepo.FindByNameAndTenantID(model.RoleRegistered, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, apperror.NewValidation("no default role found for tenant")
+	}
+	return role, nil
+}
+
+func toUserImportServiceDataResult(job *model.UserImportJob) *UserImportServiceDataResult {
+	rowErrors := []UserImportRowError{}
+	if len(job.RowErrors) > 0 {
+		_ = json.Unmarshal(job.RowErrors, &rowErrors)
+	}
+
+	return &UserImportServiceDataResult{
+		UserImportJobUUID: job.UserImportJobUUID,
+		Format:            job.Format,
+		DryRun:            job.DryRun,
+		Status:            job.Status,
+		TotalRows:         job.TotalRows,
+		ProcessedRows:     job.ProcessedRows,
+		ImportedRows:      job.ImportedRows,
+		DuplicateRows:     job.DuplicateRows,
+		InvalidRows:       job.InvalidRows,
+		FailedRows:        job.FailedRows,
+		RowErrors:         rowErrors,
+		Error:             job.Error,
+		StartedAt:         job.StartedAt,
+		CompletedAt:       job.CompletedAt,
+		CreatedAt:         job.CreatedAt,
+	}
+}
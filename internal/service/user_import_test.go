@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// importRoleRepo returns a role repo whose registered role is "member" (ID 7).
+func importRoleRepo() *mockRoleRepo {
+	return &mockRoleRepo{
+		findPaginatedFn: func(repository.RoleRepositoryGetFilter) (*repository.PaginationResult[model.Role], error) {
+			return &repository.PaginationResult[model.Role]{
+				Data: []model.Role{{RoleID: 7, RoleUUID: uuid.New(), Name: "member"}},
+			}, nil
+		},
+	}
+}
+
+// importClientRepo returns a client repo whose default client has ID 3.
+func importClientRepo() *mockClientRepo {
+	return &mockClientRepo{
+		findDefaultByTenantIDFn: func(int64) (*model.Client, error) {
+			return &model.Client{ClientID: 3}, nil
+		},
+	}
+}
+
+// importUserRepo returns a user repo that finds the importing admin and
+// appends the users it creates to created.
+func importUserRepo(created *[]model.User) *mockUserRepo {
+	return &mockUserRepo{
+		findByUUIDFn: func(any, ...string) (*model.User, error) {
+			return &model.User{UserID: 99}, nil
+		},
+		createBatchFn: func(users []model.User) error {
+			for i := range users {
+				users[i].UserID = int64(100 + i)
+				users[i].UserUUID = uuid.New()
+			}
+			*created = append(*created, users...)
+			return nil
+		},
+	}
+}
+
+func newUserImportService(db *gorm.DB, jobRepo *mockUserImportJobRepo, userRepo *mockUserRepo, identityRepo *mockUserIdentityRepo, userRoleRepo *mockUserRoleRepo, roleRepo *mockRoleRepo, clientRepo *mockClientRepo, eventRepo *mockEventRepo) *userImportService {
+	return NewUserImportService(db, jobRepo, userRepo, identityRepo, userRoleRepo, roleRepo, clientRepo, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, eventRepo).(*userImportService)
+}
+
+func importRow(n int, username string) UserImportRow {
+	return UserImportRow{Row: n, Username: username, Fullname: "User " + username, Email: username + "@example.com"}
+}
+
+func testArgon2idHash(password string) string {
+	salt := []byte("importsaltvalue!")
+	key := argon2.IDKey([]byte(password), salt, 1, 64, 1, 32)
+	return fmt.Sprintf("$argon2id$v=%d$m=64,t=1,p=1$%s$%s", argon2.Version,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+}
+
+func rowReasons(rowErrors []UserImportRowError) map[int]string {
+	out := make(map[int]string, len(rowErrors))
+	for _, e := range rowErrors {
+		out[e.Row] = e.Reason
+	}
+	return out
+}
+
+// ---------------------------------------------------------------------------
+// Import
+// ---------------------------------------------------------------------------
+
+func TestUserImportService_Import_RejectsEmptyAndOversized(t *testing.T) {
+	svc := newUserImportService(nil, &mockUserImportJobRepo{}, importUserRepo(&[]model.User{}), &mockUserIdentityRepo{}, &mockUserRoleRepo{}, importRoleRepo(), importClientRepo(), &mockEventRepo{})
+
+	_, err := svc.Import(context.Background(), 1, uuid.New(), model.UserImportFormatJSON, false, nil)
+	var validation *apperror.ValidationError
+	assert.ErrorAs(t, err, &validation)
+
+	rows := make([]UserImportRow, UserImportMaxRows+1)
+	_, err = svc.Import(context.Background(), 1, uuid.New(), model.UserImportFormatJSON, false, rows)
+	assert.ErrorAs(t, err, &validation)
+}
+
+func TestUserImportService_Import_ActorLookupError(t *testing.T) {
+	userRepo := importUserRepo(&[]model.User{})
+	userRepo.findByUUIDFn = func(any, ...string) (*model.User, error) {
+		return nil, errors.New("db error")
+	}
+	svc := newUserImportService(nil, &mockUserImportJobRepo{}, userRepo, &mockUserIdentityRepo{}, &mockUserRoleRepo{}, importRoleRepo(), importClientRepo(), &mockEventRepo{})
+
+	_, err := svc.Import(context.Background(), 1, uuid.New(), model.UserImportFormatCSV, false, []UserImportRow{importRow(1, "alice")})
+	assert.EqualError(t, err, "db error")
+}
+
+func TestUserImportService_Import_DryRunReport(t *testing.T) {
+	var created []model.User
+	userRepo := importUserRepo(&created)
+	userRepo.findByUsernamesOrEmailsFn = func(usernames, emails []string) ([]model.User, error) {
+		return []model.User{{Username: "taken"}, {Username: "someone", Email: "used@example.com"}}, nil
+	}
+	eventRepo := &mockEventRepo{}
+	svc := newUserImportService(nil, &mockUserImportJobRepo{}, userRepo, &mockUserIdentityRepo{}, &mockUserRoleRepo{}, importRoleRepo(), importClientRepo(), eventRepo)
+
+	invalid := importRow(2, "x")
+	invalid.Invalid = "username: the length must be between 3 and 50."
+	badHash := importRow(3, "carol")
+	badHash.PasswordHash = "5f4dcc3b5aa765d61d8327deb882cf99"
+	emailTaken := importRow(6, "dave")
+	emailTaken.Email = "used@example.com"
+	withHash := importRow(7, "erin")
+	withHash.PasswordHash = testArgon2idHash("Secret#123")
+
+	rows := []UserImportRow{
+		importRow(1, "alice"),
+		invalid,
+		badHash,
+		importRow(4, "alice"),
+		importRow(5, "taken"),
+		emailTaken,
+		withHash,
+	}
+
+	result, err := svc.Import(context.Background(), 1, uuid.New(), model.UserImportFormatCSV, true, rows)
+	require.NoError(t, err)
+
+	assert.Equal(t, model.UserImportStatusCompleted, result.Status)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 7, result.TotalRows)
+	assert.Equal(t, 7, result.ProcessedRows)
+	assert.Equal(t, 2, result.ImportedRows)
+	assert.Equal(t, 3, result.DuplicateRows)
+	assert.Equal(t, 2, result.InvalidRows)
+	assert.Zero(t, result.FailedRows)
+	assert.Equal(t, map[int]string{
+		2: UserImportReasonInvalid,
+		3: UserImportReasonInvalid,
+		4: UserImportReasonDuplicate,
+		5: UserImportReasonDuplicate,
+		6: UserImportReasonDuplicate,
+	}, rowReasons(result.RowErrors))
+	assert.Empty(t, created, "a dry run creates nothing")
+	assert.Empty(t, eventRepo.created)
+}
+
+func TestUserImportService_Import_CreatesUsers(t *testing.T) {
+	db, mock := newMockGormDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	var created []model.User
+	var identities []model.UserIdentity
+	var userRoles []model.UserRole
+	jobRepo := &mockUserImportJobRepo{}
+	identityRepo := &mockUserIdentityRepo{createBatchFn: func(ids []model.UserIdentity) error {
+		identities = ids
+		return nil
+	}}
+	userRoleRepo := &mockUserRoleRepo{createBatchFn: func(urs []model.UserRole) error {
+		userRoles = urs
+		return nil
+	}}
+	eventRepo := &mockEventRepo{}
+	svc := newUserImportService(db, jobRepo, importUserRepo(&created), identityRepo, userRoleRepo, importRoleRepo(), importClientRepo(), eventRepo)
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte("Secret#123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	withHash := importRow(1, "alice")
+	withHash.PasswordHash = string(hashed)
+	withPassword := importRow(2, "bob")
+	withPassword.Password = "Secret#456"
+	withPassword.Status = model.StatusPending
+	withPassword.Metadata = datatypes.JSON(`{"department":"sales"}`)
+
+	result, err := svc.Import(context.Background(), 1, uuid.New(), model.UserImportFormatJSON, false, []UserImportRow{withHash, withPassword})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, model.UserImportStatusCompleted, result.Status)
+	assert.Equal(t, 2, result.ImportedRows)
+	assert.Empty(t, result.RowErrors)
+
+	require.Len(t, created, 2)
+	assert.Equal(t, string(hashed), *created[0].Password, "pre-hashed passwords are stored as given")
+	assert.Equal(t, model.StatusActive, created[0].Status)
+	require.NotNil(t, created[1].Password)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(*created[1].Password), []byte("Secret#456")))
+	assert.Equal(t, model.StatusPending, created[1].Status)
+	assert.JSONEq(t, `{"department":"sales"}`, string(created[1].Metadata))
+
+	require.Len(t, identities, 2)
+	assert.Equal(t, int64(3), identities[0].ClientID)
+	assert.Equal(t, created[0].UserUUID.String(), identities[0].Sub)
+	require.Len(t, userRoles, 2)
+	assert.Equal(t, int64(7), userRoles[1].RoleID)
+	assert.Len(t, eventRepo.created, 4, "user.created and role.assigned per user")
+
+	final := jobRepo.updates[len(jobRepo.updates)-1]
+	assert.Equal(t, model.UserImportStatusCompleted, final["status"])
+	assert.Equal(t, 2, final["imported_rows"])
+}
+
+func TestUserImportService_Import_ChunkFailureFailsRows(t *testing.T) {
+	db, mock := newMockGormDB(t)
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	userRepo := importUserRepo(&[]model.User{})
+	userRepo.createBatchFn = func([]model.User) error { return errors.New("insert failed") }
+	svc := newUserImportService(db, &mockUserImportJobRepo{}, userRepo, &mockUserIdentityRepo{}, &mockUserRoleRepo{}, importRoleRepo(), importClientRepo(), &mockEventRepo{})
+
+	result, err := svc.Import(context.Background(), 1, uuid.New(), model.UserImportFormatCSV, false, []UserImportRow{importRow(1, "alice"), importRow(2, "bob")})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, model.UserImportStatusCompleted, result.Status)
+	assert.Zero(t, result.ImportedRows)
+	assert.Equal(t, 2, result.FailedRows)
+	assert.Equal(t, map[int]string{1: UserImportReasonFailed, 2: UserImportReasonFailed}, rowReasons(result.RowErrors))
+}
+
+func TestUserImportService_Import_NoDefaultClientFailsJob(t *testing.T) {
+	clientRepo := &mockClientRepo{findDefaultByTenantIDFn: func(int64) (*model.Client, error) { return nil, nil }}
+	svc := newUserImportService(nil, &mockUserImportJobRepo{}, importUserRepo(&[]model.User{}), &mockUserIdentityRepo{}, &mockUserRoleRepo{}, importRoleRepo(), clientRepo, &mockEventRepo{})
+
+	result, err := svc.Import(context.Background(), 1, uuid.New(), model.UserImportFormatCSV, false, []UserImportRow{importRow(1, "alice")})
+	require.NoError(t, err)
+
+	assert.Equal(t, model.UserImportStatusFailed, result.Status)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, "default auth client not found for tenant", *result.Error)
+	assert.Zero(t, result.ProcessedRows)
+}
+
+func TestUserImportService_Import_InternalErrorIsNotExposed(t *testing.T) {
+	roleRepo := &mockRoleRepo{findPaginatedFn: func(repository.RoleRepositoryGetFilter) (*repository.PaginationResult[model.Role], error) {
+		return nil, errors.New("connection refused")
+	}}
+	svc := newUserImportService(nil, &mockUserImportJobRepo{}, importUserRepo(&[]model.User{}), &mockUserIdentityRepo{}, &mockUserRoleRepo{}, roleRepo, importClientRepo(), &mockEventRepo{})
+
+	result, err := svc.Import(context.Background(), 1, uuid.New(), model.UserImportFormatCSV, false, []UserImportRow{importRow(1, "alice")})
+	require.NoError(t, err)
+
+	assert.Equal(t, model.UserImportStatusFailed, result.Status)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, "the import could not be started", *result.Error)
+}
+
+func TestUserImportService_Import_LargeImportRunsInBackground(t *testing.T) {
+	jobRepo := &mockUserImportJobRepo{}
+	svc := newUserImportService(nil, jobRepo, importUserRepo(&[]model.User{}), &mockUserIdentityRepo{}, &mockUserRoleRepo{}, importRoleRepo(), importClientRepo(), &mockEventRepo{})
+	var background func()
+	svc.runAsync = func(fn func()) { background = fn }
+
+	rows := make([]UserImportRow, UserImportSyncLimit+1)
+	for i := range rows {
+		rows[i] = importRow(i+1, fmt.Sprintf("user%03d", i))
+	}
+
+	result, err := svc.Import(context.Background(), 1, uuid.New(), model.UserImportFormatCSV, true, rows)
+	require.NoError(t, err)
+	assert.Equal(t, model.UserImportStatusPending, result.Status)
+	assert.Zero(t, result.ProcessedRows)
+	require.NotNil(t, background)
+	assert.Empty(t, jobRepo.updates, "nothing runs until the background job starts")
+
+	background()
+
+	final := jobRepo.updates[len(jobRepo.updates)-1]
+	assert.Equal(t, model.UserImportStatusCompleted, final["status"])
+	assert.Equal(t, UserImportSyncLimit+1, final["processed_rows"])
+	assert.Equal(t, UserImportSyncLimit+1, final["imported_rows"])
+}
+
+// ---------------------------------------------------------------------------
+// GetJob
+// ---------------------------------------------------------------------------
+
+func TestUserImportService_GetJob(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		svc := newUserImportService(nil, &mockUserImportJobRepo{}, importUserRepo(&[]model.User{}), &mockUserIdentityRepo{}, &mockUserRoleRepo{}, importRoleRepo(), importClientRepo(), &mockEventRepo{})
+		_, err := svc.GetJob(context.Background(), uuid.New(), 1)
+		var notFound *apperror.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		jobRepo := &mockUserImportJobRepo{findByUUIDAndTenantIDFn: func(string, int64) (*model.UserImportJob, error) {
+			return nil, errors.New("db error")
+		}}
+		svc := newUserImportService(nil, jobRepo, importUserRepo(&[]model.User{}), &mockUserIdentityRepo{}, &mockUserRoleRepo{}, importRoleRepo(), importClientRepo(), &mockEventRepo{})
+		_, err := svc.GetJob(context.Background(), uuid.New(), 1)
+		assert.EqualError(t, err, "db error")
+	})
+
+	t.Run("found", func(t *testing.T) {
+		jobUUID := uuid.New()
+		jobRepo := &mockUserImportJobRepo{}
+		svc := newUserImportService(nil, jobRepo, importUserRepo(&[]model.User{}), &mockUserIdentityRepo{}, &mockUserRoleRepo{}, importRoleRepo(), importClientRepo(), &mockEventRepo{})
+		jobRepo.findByUUIDAndTenantIDFn = func(id string, tid int64) (*model.UserImportJob, error) {
+			assert.Equal(t, jobUUID.String(), id)
+			assert.Equal(t, int64(1), tid)
+			return &model.UserImportJob{
+				UserImportJobUUID: jobUUID,
+				Status:            model.UserImportStatusRunning,
+				TotalRows:         1000,
+				ProcessedRows:     500,
+				DuplicateRows:     1,
+				RowErrors:         datatypes.JSON(`[{"row":4,"username":"alice","reason":"duplicate","message":"username already exists"}]`),
+			}, nil
+		}
+
+		result, err := svc.GetJob(context.Background(), jobUUID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, model.UserImportStatusRunning, result.Status)
+		assert.Equal(t, 500, result.ProcessedRows)
+		require.Len(t, result.RowErrors, 1)
+		assert.Equal(t, UserImportRowError{Row: 4, Username: "alice", Reason: "duplicate", Message: "username already exists"}, result.RowErrors[0])
+	})
+}
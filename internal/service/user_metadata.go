@@ -0,0 +1,190 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"gorm.io/datatypes"
+)
+
+// userMetadataSchemaURL is the in-memory resource name tenant schemas are
+// compiled under. Remote and file $refs are never resolved.
+const userMetadataSchemaURL = "urn:maintainerd:user-metadata-schema"
+
+// isProtectedMetadataKey reports whether a top-level metadata key belongs to
+// the system-managed namespace.
+func isProtectedMetadataKey(key string) bool {
+	return key == model.UserMetadataProtectedNamespace ||
+		strings.HasPrefix(key, model.UserMetadataProtectedNamespace+".")
+}
+
+// compileUserMetadataSchema compiles a tenant-defined JSON Schema. It returns
+// nil when raw is empty or an empty object, meaning metadata is unconstrained.
+func compileUserMetadataSchema(raw datatypes.JSON) (*jsonschema.Schema, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("{}")) || bytes.Equal(trimmed, []byte("null")) {
+		return nil, nil
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(trimmed))
+	if err != nil {
+		return nil, fmt.Errorf("schema is not valid JSON: %w", err)
+	}
+
+	c := jsonschema.NewCompiler()
+	c.UseLoader(jsonschema.SchemeURLLoader{})
+	if err := c.AddResource(userMetadataSchemaURL, doc); err != nil {
+		return nil, err
+	}
+	return c.Compile(userMetadataSchemaURL)
+}
+
+// decodeMetadataObject parses raw as a JSON object. Empty input yields an
+// empty map.
+func decodeMetadataObject(raw datatypes.JSON) (map[string]any, error) {
+	out := map[string]any{}
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return out, nil
+	}
+	if err := json.Unmarshal(raw, &out); err != nil || out == nil {
+		return nil, apperror.NewValidation("metadata must be a JSON object")
+	}
+	return out, nil
+}
+
+// mergeMetadataPatch applies patch to dst following JSON Merge Patch
+// (RFC 7386) semantics: null removes a key, nested objects are merged and any
+// other value replaces the existing one. dst is modified in place.
+func mergeMetadataPatch(dst, patch map[string]any) map[string]any {
+	for k, v := range patch {
+		if v == nil {
+			delete(dst, k)
+			continue
+		}
+		if pv, ok := v.(map[string]any); ok {
+			if dv, ok := dst[k].(map[string]any); ok {
+				dst[k] = mergeMetadataPatch(dv, pv)
+				continue
+			}
+			dst[k] = mergeMetadataPatch(map[string]any{}, pv)
+			continue
+		}
+		dst[k] = v
+	}
+	return dst
+}
+
+// applyUserMetadataPatch merges a caller-supplied patch into the user's
+// current metadata, rejects writes to the protected namespace and values that
+// look like pasted secrets, and validates the result against the tenant's
+// metadata schema. Protected keys already on the user are carried over
+// untouched and are not subject to the schema.
+func applyUserMetadataPatch(tenantSettingRepo repository.TenantSettingRepository, tenantID int64, current, patch datatypes.JSON) (datatypes.JSON, error) {
+	patchObj, err := decodeMetadataObject(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	var reserved []string
+	for k := range patchObj {
+		if isProtectedMetadataKey(k) {
+			reserved = append(reserved, k)
+		}
+	}
+	if len(reserved) > 0 {
+		sort.Strings(reserved)
+		return nil, apperror.NewValidation(fmt.Sprintf("metadata keys in the %q namespace are reserved: %s",
+			model.UserMetadataProtectedNamespace, strings.Join(reserved, ", ")))
+	}
+	if err := rejectPastedSecrets("metadata", patch); err != nil {
+		return nil, err
+	}
+
+	currentObj, err := decodeMetadataObject(current)
+	if err != nil {
+		// Stored metadata that is not an object is replaced wholesale.
+		currentObj = map[string]any{}
+	}
+
+	protected := map[string]any{}
+	for k, v := range currentObj {
+		if isProtectedMetadataKey(k) {
+			protected[k] = v
+			delete(currentObj, k)
+		}
+	}
+
+	merged := mergeMetadataPatch(currentObj, patchObj)
+
+	if err := validateUserMetadata(tenantSettingRepo, tenantID, merged); err != nil {
+		return nil, err
+	}
+
+	for k, v := range protected {
+		merged[k] = v
+	}
+
+	out, err := json.Marshal(merged)
+	if err != nil {
+		return nil, apperror.NewValidation("invalid metadata payload")
+	}
+	return datatypes.JSON(out), nil
+}
+
+// validateUserMetadata checks metadata against the tenant's configured schema.
+// Tenants without a schema accept any object.
+func validateUserMetadata(tenantSettingRepo repository.TenantSettingRepository, tenantID int64, metadata map[string]any) error {
+	setting, err := tenantSettingRepo.FindByTenantID(tenantID)
+	if err != nil {
+		return err
+	}
+	if setting == nil {
+		return nil
+	}
+
+	schema, err := compileUserMetadataSchema(setting.UserMetadataSchema)
+	if err != nil {
+		return apperror.NewInternal("tenant user metadata schema is invalid", err)
+	}
+	if schema == nil {
+		return nil
+	}
+
+	// The schema library validates its own decoded representation.
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return apperror.NewValidation("invalid metadata payload")
+	}
+	inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		return apperror.NewValidation("invalid metadata payload")
+	}
+	if err := schema.Validate(inst); err != nil {
+		return apperror.NewValidation("metadata does not match tenant schema: " + flattenSchemaError(err))
+	}
+	return nil
+}
+
+// flattenSchemaError collapses the library's multi-line error tree into a
+// single line suitable for an API error message.
+func flattenSchemaError(err error) string {
+	lines := strings.Split(strings.TrimSpace(err.Error()), "\n")
+	if len(lines) > 1 {
+		lines = lines[1:] // drop the "validation failed with <url>" header
+	}
+	parts := make([]string, 0, len(lines))
+	for _, l := range lines {
+		l = strings.TrimPrefix(strings.TrimSpace(l), "- ")
+		if l != "" {
+			parts = append(parts, l)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
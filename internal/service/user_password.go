@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/maintainerd/auth/internal/security"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+func (s *userService) ChangePassword(ctx context.Context, userUUID uuid.UUID, tenantID int64, currentPassword, newPassword, currentRefreshToken string) error {
+	_, span := otel.Tracer("service").Start(ctx, "user.changePassword")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", userUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	// Wrong current passwords count towards a lockout, so a stolen access
+	// token cannot be used to guess the password
+	limiterKey := security.RateLimitKey(userUUID.String(), "change_password")
+	if err := security.CheckRateLimit(limiterKey); err != nil {
+		security.LogSecurityEvent(security.SecurityEvent{
+			EventType: "password_change_rate_limited",
+			UserID:    userUUID.String(),
+			Timestamp: time.Now(),
+			Details:   err.Error(),
+		})
+		return apperror.NewForbidden("too many failed password change attempts, try again later")
+	}
+
+	user, err := findTenantUser(s.userRepo, userUUID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find user failed")
+		return err
+	}
+	if user.DeletedAt != nil || user.Status != model.StatusActive {
+		return apperror.NewValidation("only an active account can change its password")
+	}
+
+	// Compare against a dummy hash when there is no password so the answer
+	// takes as long either way
+	storedHash := security.GetDummyBcryptHash()
+	if user.Password != nil {
+		storedHash = []byte(*user.Password)
+	}
+	if err := security.ComparePassword(storedHash, []byte(currentPassword)); err != nil || user.Password == nil {
+		security.RecordFailedAttempt(limiterKey)
+		s.logPasswordChange(ctx, tenantID, user, model.AuthEventResultFailure, "Current password is incorrect")
+		return apperror.NewValidation("current password is incorrect")
+	}
+	if currentPassword == newPassword {
+		return apperror.NewValidation("new password must be different from the current password")
+	}
+
+	if err := checkTenantPassword(s.securitySettingRepo, tenantID, newPassword); err != nil {
+		return err
+	}
+	if err := ensurePasswordNotBreached(ctx, s.tenantSettingRepo, s.breachChecker, tenantID, newPassword); err != nil {
+		return err
+	}
+	hashedPassword, err := hashTenantPassword(s.securitySettingRepo, tenantID, newPassword)
+	if err != nil {
+		return apperror.NewInternal("failed to hash password", err)
+	}
+
+	var revokedSessions int64
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := s.userRepo.WithTx(tx).UpdateByID(user.UserID, map[string]any{
+			"password": string(hashedPassword),
+		}); err != nil {
+			return err
+		}
+
+		// Sign out every other session; the one the change was made from
+		// stays signed in when its refresh token was given
+		txRefreshTokenRepo := s.refreshTokenRepo.WithTx(tx)
+		currentFamily, err := refreshTokenFamily(txRefreshTokenRepo, user.UserID, currentRefreshToken)
+		if err != nil {
+			return err
+		}
+		if currentFamily != uuid.Nil {
+			revokedSessions, err = txRefreshTokenRepo.RevokeByUserIDExceptFamily(user.UserID, currentFamily)
+		} else {
+			revokedSessions, err = txRefreshTokenRepo.RevokeByUserID(user.UserID)
+		}
+		if err != nil {
+			return err
+		}
+
+		// A pending reset link would set the password back to the attacker's
+		// choice, so it stops working too
+		return revokeUserTokensOfType(s.userTokenRepo.WithTx(tx), user.UserID, model.TokenTypePasswordReset)
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "change password failed")
+		return err
+	}
+
+	security.ResetFailedAttempts(limiterKey)
+	security.LogSecurityEvent(security.SecurityEvent{
+		EventType: "password_change_success",
+		UserID:    user.UserUUID.String(),
+		Details:   fmt.Sprintf("Password changed, %d other sessions revoked", revokedSessions),
+		Severity:  "INFO",
+		Timestamp: time.Now(),
+	})
+	s.logPasswordChange(ctx, tenantID, user, model.AuthEventResultSuccess, "Password changed by user")
+	if user.Email != "" {
+		s.notifyPasswordChanged(ctx, user)
+	}
+
+	span.SetAttributes(attribute.Int64("sessions.revoked", revokedSessions))
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// refreshTokenFamily returns the family of the user's active refresh token,
+// or uuid.Nil when the token is empty, unknown, inactive or someone else's.
+func refreshTokenFamily(refreshTokenRepo repository.OAuthRefreshTokenRepository, userID int64, refreshToken string) (uuid.UUID, error) {
+	if refreshToken == "" {
+		return uuid.Nil, nil
+	}
+	token, err := refreshTokenRepo.FindByTokenHash(crypto.HashRefreshToken(refreshToken))
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if token == nil || token.UserID != userID || !token.IsActive() {
+		return uuid.Nil, nil
+	}
+	return token.FamilyID, nil
+}
+
+// logPasswordChange writes the authn_password_change or
+// authn_password_change_fail audit entry of a password change.
+func (s *userService) logPasswordChange(ctx context.Context, tenantID int64, user *model.User, result, description string) {
+	eventType := model.AuthEventTypePasswordChange
+	if result == model.AuthEventResultFailure {
+		eventType = model.AuthEventTypePasswordChangeFail
+	}
+	s.authEventService.Log(ctx, AuthEventInput{
+		TenantID:     tenantID,
+		ActorUserID:  &user.UserID,
+		TargetUserID: &user.UserID,
+		IPAddress:    middleware.ClientIPFromContext(ctx),
+		UserAgent:    ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:     model.AuthEventCategoryAuthn,
+		EventType:    eventType,
+		Severity:     model.AuthEventSeverityWarn,
+		Result:       result,
+		Description:  ptr.Ptr(description),
+	})
+}
+
+// notifyPasswordChanged tells the user their password changed, so that a
+// change they did not make does not go unnoticed.
+func (s *userService) notifyPasswordChanged(ctx context.Context, user *model.User) {
+	data := struct {
+		ChangedAt string
+		ResetURL  string
+		LogoURL   string
+	}{
+		ChangedAt: time.Now().UTC().Format("January 2, 2006 15:04 MST"),
+		ResetURL:  config.AccountHostname + "/forgot-password",
+		LogoURL:   config.EmailLogo,
+	}
+	s.notifyAccount(ctx, user, "internal:user:password:changed", data)
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/crypto"
+	"github.com/maintainerd/auth/internal/email"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const currentTestPassword = "Current-Secret1"
+
+// newPasswordUser returns an active user of tenant 1 whose password is
+// currentTestPassword.
+func newPasswordUser(t *testing.T) *model.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(currentTestPassword), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := newSelfServiceUser()
+	password := string(hash)
+	user.Password = &password
+	return user
+}
+
+func TestUserService_ChangePassword(t *testing.T) {
+	t.Run("changes the password and keeps the current session", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		user := newPasswordUser(t)
+		userRepo := selfServiceUserRepo(user)
+		var stored string
+		userRepo.updateByIDFn = func(id, data any) (*model.User, error) {
+			assert.Equal(t, user.UserID, id)
+			stored = data.(map[string]any)["password"].(string)
+			return user, nil
+		}
+		family := uuid.New()
+		var keptFamily uuid.UUID
+		refreshTokenRepo := &mockOAuthRefreshTokenRepo{
+			findByTokenHashFn: func(hash string) (*model.OAuthRefreshToken, error) {
+				assert.Equal(t, crypto.HashRefreshToken("rt-current"), hash)
+				return &model.OAuthRefreshToken{UserID: user.UserID, FamilyID: family, ExpiresAt: time.Now().Add(time.Hour)}, nil
+			},
+			revokeExceptFamilyFn: func(_ int64, familyID uuid.UUID) (int64, error) {
+				keptFamily = familyID
+				return 3, nil
+			},
+			revokeByUserIDFn: func(int64) (int64, error) {
+				t.Fatal("the current session must stay signed in")
+				return 0, nil
+			},
+		}
+		resetLink := model.UserToken{UserTokenUUID: uuid.New(), TokenType: model.TokenTypePasswordReset}
+		var revokedLink uuid.UUID
+		tokenRepo := &mockUserTokenRepo{
+			findByUserIDAndTokenTypeFn: func(_ int64, tokenType string) ([]model.UserToken, error) {
+				assert.Equal(t, model.TokenTypePasswordReset, tokenType)
+				return []model.UserToken{resetLink}, nil
+			},
+			revokeByUUIDFn: func(id uuid.UUID) error {
+				revokedLink = id
+				return nil
+			},
+		}
+
+		origSendEmail := email.SendEmail
+		defer func() { email.SendEmail = origSendEmail }()
+		var sent email.SendEmailParams
+		email.SendEmail = func(_ context.Context, p email.SendEmailParams) error {
+			sent = p
+			return nil
+		}
+
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(gormDB, userRepo, tokenRepo, refreshTokenRepo, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		err := svc.ChangePassword(context.Background(), user.UserUUID, 1, currentTestPassword, "Brand-New-Secret2", "rt-current")
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+
+		assert.NoError(t, security.ComparePassword([]byte(stored), []byte("Brand-New-Secret2")))
+		assert.Equal(t, family, keptFamily)
+		assert.Equal(t, resetLink.UserTokenUUID, revokedLink)
+		assert.Equal(t, "jane@example.com", sent.To)
+		assert.Equal(t, "Password changed", sent.Subject)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypePasswordChange, logged[0].EventType)
+		assert.Equal(t, model.AuthEventResultSuccess, logged[0].Result)
+	})
+
+	t.Run("no current session → every session revoked", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		user := newPasswordUser(t)
+		var revokedAll bool
+		refreshTokenRepo := &mockOAuthRefreshTokenRepo{
+			findByTokenHashFn: func(string) (*model.OAuthRefreshToken, error) {
+				return &model.OAuthRefreshToken{UserID: 99, FamilyID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour)}, nil
+			},
+			revokeByUserIDFn: func(int64) (int64, error) {
+				revokedAll = true
+				return 2, nil
+			},
+		}
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(gormDB, selfServiceUserRepo(user), &mockUserTokenRepo{}, refreshTokenRepo, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		err := svc.ChangePassword(context.Background(), user.UserUUID, 1, currentTestPassword, "Brand-New-Secret2", "someone-elses")
+		require.NoError(t, err)
+		assert.True(t, revokedAll, "a refresh token of another user does not keep a session")
+	})
+
+	t.Run("wrong current password → validation error", func(t *testing.T) {
+		user := newPasswordUser(t)
+		userRepo := selfServiceUserRepo(user)
+		userRepo.updateByIDFn = func(any, any) (*model.User, error) {
+			t.Fatal("the password must not change")
+			return nil, nil
+		}
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(nil, userRepo, &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		err := svc.ChangePassword(context.Background(), user.UserUUID, 1, "Wrong-Secret1", "Brand-New-Secret2", "")
+		var validation *apperror.ValidationError
+		require.ErrorAs(t, err, &validation)
+		assert.Contains(t, err.Error(), "current password is incorrect")
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypePasswordChangeFail, logged[0].EventType)
+	})
+
+	t.Run("account without a password → validation error", func(t *testing.T) {
+		user := newSelfServiceUser()
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(nil, selfServiceUserRepo(user), &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		err := svc.ChangePassword(context.Background(), user.UserUUID, 1, "", "Brand-New-Secret2", "")
+		var validation *apperror.ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+
+	t.Run("same password → error", func(t *testing.T) {
+		user := newPasswordUser(t)
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(nil, selfServiceUserRepo(user), &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		err := svc.ChangePassword(context.Background(), user.UserUUID, 1, currentTestPassword, currentTestPassword, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must be different")
+	})
+
+	t.Run("new password breaks the policy → validation error", func(t *testing.T) {
+		user := newPasswordUser(t)
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(nil, selfServiceUserRepo(user), &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		err := svc.ChangePassword(context.Background(), user.UserUUID, 1, currentTestPassword, "short", "")
+		var validation *apperror.ValidationError
+		require.ErrorAs(t, err, &validation)
+		assert.Contains(t, err.Error(), "at least 8 characters")
+	})
+
+	t.Run("account not active → validation error", func(t *testing.T) {
+		user := newPasswordUser(t)
+		user.Status = model.StatusSuspended
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(nil, selfServiceUserRepo(user), &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		err := svc.ChangePassword(context.Background(), user.UserUUID, 1, currentTestPassword, "Brand-New-Secret2", "")
+		var validation *apperror.ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+
+	t.Run("user of another tenant → not found", func(t *testing.T) {
+		user := newPasswordUser(t)
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(nil, selfServiceUserRepo(user), &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		err := svc.ChangePassword(context.Background(), user.UserUUID, 2, currentTestPassword, "Brand-New-Secret2", "")
+		code, ok := apperror.CodeOf(err)
+		require.True(t, ok)
+		assert.Equal(t, apperror.CodeUserNotFound, code)
+	})
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// DefaultDeletedUserRetention is how long a soft-deleted user is kept when
+// its tenant does not set deleted_user_retention_days.
+const DefaultDeletedUserRetention = 30 * 24 * time.Hour
+
+// userPurgeBatchSize bounds how many deleted users PurgeExpired loads at once.
+const userPurgeBatchSize = 100
+
+func (s *userService) PurgeExpired(ctx context.Context) (int64, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "user.purgeExpired")
+	defer span.End()
+
+	now := time.Now()
+	retentions := make(map[int64]time.Duration)
+	var purged, afterUserID int64
+
+	for {
+		users, err := s.userRepo.FindDeletedBefore(now, afterUserID, userPurgeBatchSize)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "list deleted users failed")
+			return purged, apperror.NewInternal("failed to list deleted users", err)
+		}
+
+		scheduled, err := s.scheduledSelfDeletions(users)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "load scheduled deletions failed")
+			return purged, err
+		}
+
+		for i := range users {
+			user := &users[i]
+			afterUserID = user.UserID
+
+			tenantIDs := userTenantIDs(user)
+			description := "user erased after retention period"
+			if purgeAt, ok := scheduled[user.UserID]; ok {
+				// Deleted by its owner: the grace period replaces retention
+				if now.Before(purgeAt) {
+					continue
+				}
+				description = "user erased after account deletion grace period"
+			} else {
+				retention, err := s.deletedUserRetention(retentions, tenantIDs)
+				if err != nil {
+					span.RecordError(err)
+					span.SetStatus(codes.Error, "load retention failed")
+					return purged, err
+				}
+				if now.Sub(*user.DeletedAt) < retention {
+					continue
+				}
+			}
+
+			if err := s.purgeUser(tenantIDs, user); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "purge user failed")
+				return purged, err
+			}
+			s.invalidateUserCache(ctx, user.UserIdentities)
+			for _, tenantID := range tenantIDs {
+				s.logUserLifecycle(ctx, tenantID, nil, user, model.AuthEventTypeUserDeleted, description)
+			}
+			purged++
+		}
+
+		if len(users) < userPurgeBatchSize {
+			break
+		}
+	}
+
+	span.SetAttributes(attribute.Int64("user.purged", purged))
+	span.SetStatus(codes.Ok, "")
+	return purged, nil
+}
+
+// deletedUserRetention returns the longest retention period among the
+// tenants a user belongs to, so no tenant loses the account earlier than it
+// configured. Periods are cached in retentions for the duration of a run.
+func (s *userService) deletedUserRetention(retentions map[int64]time.Duration, tenantIDs []int64) (time.Duration, error) {
+	longest := DefaultDeletedUserRetention
+	if len(tenantIDs) > 0 {
+		longest = 0
+	}
+	for _, tenantID := range tenantIDs {
+		retention, ok := retentions[tenantID]
+		if !ok {
+			var err error
+			retention, err = tenantDeletedUserRetention(s.tenantSettingRepo, tenantID)
+			if err != nil {
+				return 0, err
+			}
+			retentions[tenantID] = retention
+		}
+		longest = max(longest, retention)
+	}
+	return longest, nil
+}
+
+// tenantDeletedUserRetention reads deleted_user_retention_days from the
+// tenant's audit config. Missing or non-positive values fall back to
+// DefaultDeletedUserRetention.
+func tenantDeletedUserRetention(tenantSettingRepo repository.TenantSettingRepository, tenantID int64) (time.Duration, error) {
+	setting, err := tenantSettingRepo.FindByTenantID(tenantID)
+	if err != nil {
+		return 0, apperror.NewInternal("failed to load tenant settings", err)
+	}
+	if setting == nil {
+		return DefaultDeletedUserRetention, nil
+	}
+	days, ok := unmarshalJSON(setting.AuditConfig)[model.AuditConfigDeletedUserRetentionDays].(float64)
+	if !ok || days < 1 {
+		return DefaultDeletedUserRetention, nil
+	}
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+// userTenantIDs returns the distinct tenants the user has identities in.
+func userTenantIDs(user *model.User) []int64 {
+	var ids []int64
+	seen := make(map[int64]struct{})
+	for _, identity := range user.UserIdentities {
+		if _, ok := seen[identity.TenantID]; ok {
+			continue
+		}
+		seen[identity.TenantID] = struct{}{}
+		ids = append(ids, identity.TenantID)
+	}
+	return ids
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func deletedUser(id int64, deletedAgo time.Duration, tenantIDs ...int64) model.User {
+	deletedAt := time.Now().Add(-deletedAgo)
+	u := model.User{UserID: id, UserUUID: uuid.New(), Status: model.StatusDeleted, DeletedAt: &deletedAt}
+	for _, tid := range tenantIDs {
+		u.UserIdentities = append(u.UserIdentities, model.UserIdentity{TenantID: tid, Sub: uuid.NewString()})
+	}
+	return u
+}
+
+func TestUserService_PurgeExpired(t *testing.T) {
+	day := 24 * time.Hour
+
+	t.Run("honours per-tenant retention", func(t *testing.T) {
+		users := []model.User{
+			deletedUser(1, 31*day, 1),    // default 30 days: purged
+			deletedUser(2, 29*day, 1),    // default 30 days: kept
+			deletedUser(3, 8*day, 2),     // tenant 2 keeps 7 days: purged
+			deletedUser(4, 8*day, 2, 3),  // tenant 3 keeps 90 days: kept
+			deletedUser(5, 91*day, 2, 3), // past both: purged
+		}
+		ur := &mockUserRepo{}
+		ur.findDeletedBeforeFn = func(_ time.Time, afterID int64, limit int) ([]model.User, error) {
+			var page []model.User
+			for _, u := range users {
+				if u.UserID > afterID && len(page) < limit {
+					page = append(page, u)
+				}
+			}
+			return page, nil
+		}
+		var purged []any
+		ur.deleteByUUIDFn = func(id any) error {
+			purged = append(purged, id)
+			return nil
+		}
+		settingLookups := 0
+		settings := &mockTenantSettingRepo{findByTenantIDFn: func(tid int64) (*model.TenantSetting, error) {
+			settingLookups++
+			switch tid {
+			case 2:
+				return &model.TenantSetting{AuditConfig: datatypes.JSON(`{"deleted_user_retention_days": 7}`)}, nil
+			case 3:
+				return &model.TenantSetting{AuditConfig: datatypes.JSON(`{"deleted_user_retention_days": 90}`)}, nil
+			}
+			return nil, nil
+		}}
+		var logged []AuthEventInput
+		events := &mockEventRepo{}
+
+		db, mock := newMockGormDB(t)
+		svc := NewUserService(db, ur, &mockUserIdentityRepo{}, &mockUserRoleRepo{}, &mockRoleRepo{}, &mockTenantRepo{}, &mockIdentityProviderRepo{}, &mockClientRepo{}, &mockUserPoolRepo{}, settings, &mockSecuritySettingRepo{}, &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockEmailTemplateRepo{}, events, &mockAuthEventService{
+			logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) },
+		}, nil, cache.NopInvalidator{}, NopPlanEnforcer{})
+		for range 3 {
+			mock.ExpectBegin()
+			mock.ExpectCommit()
+		}
+
+		count, err := svc.PurgeExpired(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+		assert.Equal(t, []any{users[0].UserUUID, users[2].UserUUID, users[4].UserUUID}, purged)
+		assert.Equal(t, 3, settingLookups, "retention is loaded once per tenant")
+
+		// One purge event and one audit entry per tenant of each purged user
+		assert.Len(t, events.created, 4)
+		require.Len(t, logged, 4)
+		for _, in := range logged {
+			assert.Nil(t, in.ActorUserID)
+			assert.Equal(t, model.AuthEventTypeUserDeleted, in.EventType)
+		}
+	})
+
+	t.Run("self-deleted accounts follow the grace period", func(t *testing.T) {
+		users := []model.User{
+			deletedUser(1, 2*day, 1),  // grace period over: purged before retention
+			deletedUser(2, 40*day, 1), // grace period running: kept past retention
+			deletedUser(3, 31*day, 1), // admin deleted: retention applies
+		}
+		ur := &mockUserRepo{findDeletedBeforeFn: func(_ time.Time, afterID int64, _ int) ([]model.User, error) {
+			if afterID > 0 {
+				return nil, nil
+			}
+			return users, nil
+		}}
+		var purged []any
+		ur.deleteByUUIDFn = func(id any) error {
+			purged = append(purged, id)
+			return nil
+		}
+		past, future := time.Now().Add(-time.Hour), time.Now().Add(day)
+		tokens := &mockUserTokenRepo{findUnrevokedByUserIDsFn: func(ids []int64, tokenType string) ([]model.UserToken, error) {
+			assert.Equal(t, []int64{1, 2, 3}, ids)
+			assert.Equal(t, model.TokenTypeAccountDeletion, tokenType)
+			return []model.UserToken{{UserID: 1, ExpiresAt: &past}, {UserID: 2, ExpiresAt: &future}}, nil
+		}}
+		var logged []AuthEventInput
+
+		db, mock := newMockGormDB(t)
+		svc := NewUserService(db, ur, &mockUserIdentityRepo{}, &mockUserRoleRepo{}, &mockRoleRepo{}, &mockTenantRepo{}, &mockIdentityProviderRepo{}, &mockClientRepo{}, &mockUserPoolRepo{}, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, tokens, &mockOAuthRefreshTokenRepo{}, &mockEmailTemplateRepo{}, &mockEventRepo{}, &mockAuthEventService{
+			logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) },
+		}, nil, cache.NopInvalidator{}, NopPlanEnforcer{})
+		for range 2 {
+			mock.ExpectBegin()
+			mock.ExpectCommit()
+		}
+
+		count, err := svc.PurgeExpired(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+		assert.Equal(t, []any{users[0].UserUUID, users[2].UserUUID}, purged)
+		require.Len(t, logged, 2)
+		assert.Equal(t, "user erased after account deletion grace period", *logged[0].Description)
+		assert.Equal(t, "user erased after retention period", *logged[1].Description)
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		ur := &mockUserRepo{findDeletedBeforeFn: func(time.Time, int64, int) ([]model.User, error) {
+			return nil, errors.New("db down")
+		}}
+		_, svc := fullUserSvc(t, ur, &mockUserIdentityRepo{}, &mockUserRoleRepo{}, &mockRoleRepo{}, &mockTenantRepo{}, &mockIdentityProviderRepo{}, &mockClientRepo{}, &mockUserPoolRepo{})
+		_, err := svc.PurgeExpired(context.Background())
+		require.Error(t, err)
+	})
+}
+
+func TestTenantDeletedUserRetention(t *testing.T) {
+	repo := func(cfg string) *mockTenantSettingRepo {
+		return &mockTenantSettingRepo{findByTenantIDFn: func(int64) (*model.TenantSetting, error) {
+			return &model.TenantSetting{AuditConfig: datatypes.JSON(cfg)}, nil
+		}}
+	}
+
+	got, err := tenantDeletedUserRetention(repo(`{"deleted_user_retention_days": 14}`), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 14*24*time.Hour, got)
+
+	for _, cfg := range []string{`{}`, `{"deleted_user_retention_days": 0}`, `{"deleted_user_retention_days": "14"}`} {
+		got, err = tenantDeletedUserRetention(repo(cfg), 1)
+		require.NoError(t, err)
+		assert.Equal(t, DefaultDeletedUserRetention, got, cfg)
+	}
+
+	_, err = tenantDeletedUserRetention(&mockTenantSettingRepo{findByTenantIDFn: func(int64) (*model.TenantSetting, error) {
+		return nil, errors.New("db down")
+	}}, 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+// DefaultAccountDeletionGracePeriod is how long an account deleted by its
+// owner can be recovered when its tenant does not set
+// account_deletion_grace_days.
+const DefaultAccountDeletionGracePeriod = 14 * 24 * time.Hour
+
+// AccountDeletionServiceDataResult describes a scheduled account deletion.
+type AccountDeletionServiceDataResult struct {
+	UserUUID     uuid.UUID
+	ScheduledFor time.Time
+}
+
+func (s *userService) ScheduleSelfDeletion(ctx context.Context, userUUID uuid.UUID, tenantID int64) (*AccountDeletionServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "user.scheduleSelfDeletion")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", userUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	user, err := findTenantUser(s.userRepo, userUUID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find user failed")
+		return nil, err
+	}
+	if user.DeletedAt != nil {
+		return nil, apperror.NewValidation("user is already deleted")
+	}
+
+	grace, err := tenantAccountDeletionGrace(s.tenantSettingRepo, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "load grace period failed")
+		return nil, err
+	}
+	scheduledFor := time.Now().Add(grace)
+	cancelToken := generateSecureToken(32)
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := s.softDeleteUser(tx, userUUID, tenantID); err != nil {
+			return err
+		}
+
+		// End every session so the deletion takes effect at once
+		if _, err := s.refreshTokenRepo.WithTx(tx).RevokeByUserID(user.UserID); err != nil {
+			return err
+		}
+
+		txUserTokenRepo := s.userTokenRepo.WithTx(tx)
+		if err := txUserTokenRepo.RevokeAllByUserID(user.UserID); err != nil {
+			return err
+		}
+		_, err := txUserTokenRepo.Create(&model.UserToken{
+			UserID:    user.UserID,
+			TokenType: model.TokenTypeAccountDeletion,
+			Token:     hashAccountToken(cancelToken),
+			ExpiresAt: &scheduledFor,
+		})
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "schedule self deletion failed")
+		return nil, err
+	}
+
+	s.invalidateUserCache(ctx, user.UserIdentities)
+	s.logUserLifecycle(ctx, tenantID, &user.UserID, user, model.AuthEventTypeUserArchived, "account deletion requested by user")
+	if user.Email != "" {
+		s.notifyAccountDeletion(ctx, user, cancelToken, scheduledFor)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return &AccountDeletionServiceDataResult{UserUUID: user.UserUUID, ScheduledFor: scheduledFor}, nil
+}
+
+func (s *userService) CancelSelfDeletion(ctx context.Context, token string) (*UserServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "user.cancelSelfDeletion")
+	defer span.End()
+
+	var user, restoredUser *model.User
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txUserRepo := s.userRepo.WithTx(tx)
+		txUserTokenRepo := s.userTokenRepo.WithTx(tx)
+
+		userToken, err := txUserTokenRepo.FindActiveByTokenAndType(hashAccountToken(token), model.TokenTypeAccountDeletion)
+		if err != nil {
+			return err
+		}
+		if userToken == nil {
+			return apperror.NewValidation("invalid or expired cancellation token")
+		}
+
+		user, err = txUserRepo.FindByID(userToken.UserID, "UserIdentities")
+		if err != nil {
+			return err
+		}
+		if user == nil || user.DeletedAt == nil {
+			return apperror.NewValidation("invalid or expired cancellation token")
+		}
+
+		if err := txUserRepo.Restore(user.UserUUID, model.StatusActive); err != nil {
+			return err
+		}
+		if err := txUserTokenRepo.RevokeByUUID(userToken.UserTokenUUID); err != nil {
+			return err
+		}
+
+		restoredUser, err = txUserRepo.FindByUUID(user.UserUUID, "UserIdentities.Client", "UserIdentities.Tenant", "Roles")
+		if err != nil {
+			return err
+		}
+
+		txEventRepo := s.eventRepo.WithTx(tx)
+		for _, tenantID := range userTenantIDs(user) {
+			if err := recordEvent(txEventRepo, tenantID, UserRestored(newUserEventPayload(restoredUser))); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "cancel self deletion failed")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("user.uuid", user.UserUUID.String()))
+	s.invalidateUserCache(ctx, user.UserIdentities)
+	for _, tenantID := range userTenantIDs(user) {
+		s.logUserLifecycle(ctx, tenantID, &user.UserID, user, model.AuthEventTypeUserRestored, "account deletion cancelled by user")
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toUserServiceDataResult(restoredUser), nil
+}
+
+// scheduledSelfDeletions returns when each of the users that deleted their
+// own account is due to be purged, keyed by user ID. Users that did not
+// are left out.
+func (s *userService) scheduledSelfDeletions(users []model.User) (map[int64]time.Time, error) {
+	userIDs := make([]int64, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.UserID)
+	}
+
+	tokens, err := s.userTokenRepo.FindUnrevokedByUserIDsAndTokenType(userIDs, model.TokenTypeAccountDeletion)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to load scheduled account deletions", err)
+	}
+
+	scheduled := make(map[int64]time.Time, len(tokens))
+	for _, token := range tokens {
+		if token.ExpiresAt != nil {
+			scheduled[token.UserID] = *token.ExpiresAt
+		}
+	}
+	return scheduled, nil
+}
+
+// tenantAccountDeletionGrace reads account_deletion_grace_days from the
+// tenant's audit config. Missing or non-positive values fall back to
+// DefaultAccountDeletionGracePeriod.
+func tenantAccountDeletionGrace(tenantSettingRepo repository.TenantSettingRepository, tenantID int64) (time.Duration, error) {
+	setting, err := tenantSettingRepo.FindByTenantID(tenantID)
+	if err != nil {
+		return 0, apperror.NewInternal("failed to load tenant settings", err)
+	}
+	if setting == nil {
+		return DefaultAccountDeletionGracePeriod, nil
+	}
+	days, ok := unmarshalJSON(setting.AuditConfig)[model.AuditConfigAccountDeletionGraceDays].(float64)
+	if !ok || days < 1 {
+		return DefaultAccountDeletionGracePeriod, nil
+	}
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+// notifyAccountDeletion sends the confirmation email with the cancel link.
+func (s *userService) notifyAccountDeletion(ctx context.Context, user *model.User, cancelToken string, scheduledFor time.Time) {
+	data := struct {
+		CancelURL    string
+		ScheduledFor string
+		LogoURL      string
+	}{
+		CancelURL:    config.AccountHostname + "/account/deletion/cancel?token=" + url.QueryEscape(cancelToken),
+		ScheduledFor: scheduledFor.UTC().Format("January 2, 2006"),
+		LogoURL:      config.EmailLogo,
+	}
+	s.notifyAccount(ctx, user, "internal:user:account:deletion", data)
+}
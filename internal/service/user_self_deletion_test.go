@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/cache"
+	"github.com/maintainerd/auth/internal/email"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// newSelfServiceUser returns an active user of tenant 1.
+func newSelfServiceUser() *model.User {
+	return &model.User{
+		UserID:         7,
+		UserUUID:       uuid.New(),
+		Email:          "jane@example.com",
+		Status:         model.StatusActive,
+		UserIdentities: []model.UserIdentity{{TenantID: 1, Sub: "sub-1"}},
+	}
+}
+
+// selfServiceUserRepo returns a user repo that finds user by UUID and by ID.
+func selfServiceUserRepo(user *model.User) *mockUserRepo {
+	return &mockUserRepo{
+		findByUUIDFn: func(any, ...string) (*model.User, error) { return user, nil },
+		findByIDFn:   func(any, ...string) (*model.User, error) { return user, nil },
+	}
+}
+
+// newSelfServiceUserService returns a UserService acting on an account at the
+// user's own request. The audit entries it writes are recorded in logged.
+func newSelfServiceUserService(db *gorm.DB, userRepo *mockUserRepo, tokenRepo *mockUserTokenRepo, refreshTokenRepo *mockOAuthRefreshTokenRepo, settingRepo *mockTenantSettingRepo, eventRepo *mockEventRepo, logged *[]AuthEventInput) UserService {
+	templateRepo := &mockEmailTemplateRepo{findByNameFn: func(name string) (*model.EmailTemplate, error) {
+		switch name {
+		case "internal:user:account:deletion":
+			return &model.EmailTemplate{Subject: "Deletion", BodyHTML: `<a href="{{.CancelURL}}">{{.ScheduledFor}}</a>`}, nil
+		case "internal:user:account:disabled":
+			return &model.EmailTemplate{Subject: "Disabled", BodyHTML: `<a href="{{.ReactivateURL}}">Reactivate</a>`}, nil
+		case "internal:user:password:changed":
+			return &model.EmailTemplate{Subject: "Password changed", BodyHTML: `<a href="{{.ResetURL}}">{{.ChangedAt}}</a>`}, nil
+		}
+		return nil, nil
+	}}
+	authEvents := &mockAuthEventService{logFn: func(_ context.Context, in AuthEventInput) { *logged = append(*logged, in) }}
+	return NewUserService(db, userRepo, &mockUserIdentityRepo{}, &mockUserRoleRepo{}, &mockRoleRepo{}, &mockTenantRepo{}, &mockIdentityProviderRepo{}, &mockClientRepo{}, &mockUserPoolRepo{}, settingRepo, &mockSecuritySettingRepo{}, tokenRepo, refreshTokenRepo, templateRepo, eventRepo, authEvents, nil, cache.NopInvalidator{}, NopPlanEnforcer{})
+}
+
+func TestUserService_ScheduleSelfDeletion(t *testing.T) {
+	t.Run("deletes the account and emails a cancel link", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		user := newSelfServiceUser()
+		userRepo := selfServiceUserRepo(user)
+		var softDeleted uuid.UUID
+		userRepo.softDeleteFn = func(id uuid.UUID, _ time.Time) error {
+			softDeleted = id
+			return nil
+		}
+		settingRepo := &mockTenantSettingRepo{findByTenantIDFn: func(int64) (*model.TenantSetting, error) {
+			return &model.TenantSetting{AuditConfig: datatypes.JSON(`{"account_deletion_grace_days": 7}`)}, nil
+		}}
+		var refreshRevoked, tokensRevoked int64
+		refreshTokenRepo := &mockOAuthRefreshTokenRepo{revokeByUserIDFn: func(id int64) (int64, error) {
+			refreshRevoked = id
+			return 2, nil
+		}}
+		var created *model.UserToken
+		tokenRepo := &mockUserTokenRepo{
+			revokeAllByUserIDFn: func(id int64) error {
+				tokensRevoked = id
+				return nil
+			},
+			createFn: func(e *model.UserToken) (*model.UserToken, error) {
+				created = e
+				return e, nil
+			},
+		}
+
+		origSendEmail := email.SendEmail
+		defer func() { email.SendEmail = origSendEmail }()
+		var sent email.SendEmailParams
+		email.SendEmail = func(_ context.Context, p email.SendEmailParams) error {
+			sent = p
+			return nil
+		}
+
+		eventRepo := &mockEventRepo{}
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(gormDB, userRepo, tokenRepo, refreshTokenRepo, settingRepo, eventRepo, &logged)
+
+		result, err := svc.ScheduleSelfDeletion(context.Background(), user.UserUUID, 1)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+
+		assert.Equal(t, user.UserUUID, softDeleted)
+		assert.Equal(t, user.UserID, refreshRevoked)
+		assert.Equal(t, user.UserID, tokensRevoked)
+		assert.WithinDuration(t, time.Now().Add(7*24*time.Hour), result.ScheduledFor, time.Minute)
+
+		require.NotNil(t, created)
+		assert.Equal(t, model.TokenTypeAccountDeletion, created.TokenType)
+		require.NotNil(t, created.ExpiresAt)
+		assert.Equal(t, result.ScheduledFor, *created.ExpiresAt)
+
+		// The email carries the token whose hash was stored
+		assert.Equal(t, "jane@example.com", sent.To)
+		start := strings.Index(sent.BodyHTML, `href="`) + len(`href="`)
+		cancelURL, err := url.Parse(sent.BodyHTML[start : strings.Index(sent.BodyHTML[start:], `"`)+start])
+		require.NoError(t, err)
+		token := cancelURL.Query().Get("token")
+		require.NotEmpty(t, token)
+		assert.Equal(t, hashAccountToken(token), created.Token)
+		assert.NotEqual(t, token, created.Token, "only the hash is stored")
+
+		require.Len(t, eventRepo.created, 1)
+		assert.Equal(t, model.EventTypeUserDeleted, eventRepo.created[0].EventType)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeUserArchived, logged[0].EventType)
+		assert.Equal(t, &user.UserID, logged[0].ActorUserID)
+	})
+
+	t.Run("email failure → deletion kept", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		origSendEmail := email.SendEmail
+		defer func() { email.SendEmail = origSendEmail }()
+		email.SendEmail = func(context.Context, email.SendEmailParams) error { return errors.New("smtp down") }
+
+		user := newSelfServiceUser()
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(gormDB, selfServiceUserRepo(user), &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		result, err := svc.ScheduleSelfDeletion(context.Background(), user.UserUUID, 1)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(DefaultAccountDeletionGracePeriod), result.ScheduledFor, time.Minute)
+	})
+
+	t.Run("already deleted → validation error", func(t *testing.T) {
+		user := newSelfServiceUser()
+		deletedAt := time.Now()
+		user.DeletedAt = &deletedAt
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(nil, selfServiceUserRepo(user), &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		_, err := svc.ScheduleSelfDeletion(context.Background(), user.UserUUID, 1)
+		var validation *apperror.ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+
+	t.Run("user of another tenant → not found", func(t *testing.T) {
+		user := newSelfServiceUser()
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(nil, selfServiceUserRepo(user), &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		_, err := svc.ScheduleSelfDeletion(context.Background(), user.UserUUID, 2)
+		code, ok := apperror.CodeOf(err)
+		require.True(t, ok)
+		assert.Equal(t, apperror.CodeUserNotFound, code)
+	})
+}
+
+func TestUserService_CancelSelfDeletion(t *testing.T) {
+	t.Run("restores the account", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		user := newSelfServiceUser()
+		deletedAt := time.Now()
+		user.DeletedAt = &deletedAt
+		user.UserIdentities = append(user.UserIdentities, model.UserIdentity{TenantID: 3, Sub: "sub-3"})
+		userRepo := selfServiceUserRepo(user)
+		var restoredStatus string
+		userRepo.restoreFn = func(_ uuid.UUID, status string) error {
+			restoredStatus = status
+			return nil
+		}
+		pending := &model.UserToken{UserTokenUUID: uuid.New(), UserID: user.UserID}
+		var revoked uuid.UUID
+		tokenRepo := &mockUserTokenRepo{
+			findActiveByTokenFn: func(token, tokenType string) (*model.UserToken, error) {
+				assert.Equal(t, hashAccountToken("cancel-me"), token)
+				assert.Equal(t, model.TokenTypeAccountDeletion, tokenType)
+				return pending, nil
+			},
+			revokeByUUIDFn: func(id uuid.UUID) error {
+				revoked = id
+				return nil
+			},
+		}
+		eventRepo := &mockEventRepo{}
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(gormDB, userRepo, tokenRepo, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, eventRepo, &logged)
+
+		result, err := svc.CancelSelfDeletion(context.Background(), "cancel-me")
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		assert.Equal(t, user.UserUUID, result.UserUUID)
+		assert.Equal(t, model.StatusActive, restoredStatus)
+		assert.Equal(t, pending.UserTokenUUID, revoked)
+
+		// One restore event and audit entry per tenant of the user
+		require.Len(t, eventRepo.created, 2)
+		assert.Equal(t, model.EventTypeUserRestored, eventRepo.created[0].EventType)
+		require.Len(t, logged, 2)
+		assert.Equal(t, model.AuthEventTypeUserRestored, logged[0].EventType)
+	})
+
+	t.Run("unknown or expired token → validation error", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		eventRepo := &mockEventRepo{}
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(gormDB, selfServiceUserRepo(newSelfServiceUser()), &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, eventRepo, &logged)
+
+		_, err := svc.CancelSelfDeletion(context.Background(), "nope")
+		var validation *apperror.ValidationError
+		assert.ErrorAs(t, err, &validation)
+		assert.Empty(t, eventRepo.created)
+	})
+
+	t.Run("account already restored → validation error", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		user := newSelfServiceUser()
+		userRepo := selfServiceUserRepo(user)
+		userRepo.restoreFn = func(uuid.UUID, string) error {
+			t.Fatal("an active account must not be restored")
+			return nil
+		}
+		tokenRepo := &mockUserTokenRepo{findActiveByTokenFn: func(string, string) (*model.UserToken, error) {
+			return &model.UserToken{UserID: user.UserID}, nil
+		}}
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(gormDB, userRepo, tokenRepo, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		_, err := svc.CancelSelfDeletion(context.Background(), "cancel-me")
+		var validation *apperror.ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+}
+
+func TestTenantAccountDeletionGrace(t *testing.T) {
+	repo := func(cfg string) *mockTenantSettingRepo {
+		return &mockTenantSettingRepo{findByTenantIDFn: func(int64) (*model.TenantSetting, error) {
+			return &model.TenantSetting{AuditConfig: datatypes.JSON(cfg)}, nil
+		}}
+	}
+
+	got, err := tenantAccountDeletionGrace(repo(`{"account_deletion_grace_days": 30}`), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 30*24*time.Hour, got)
+
+	for _, cfg := range []string{`{}`, `{"account_deletion_grace_days": 0}`, `{"deleted_user_retention_days": 90}`} {
+		got, err = tenantAccountDeletionGrace(repo(cfg), 1)
+		require.NoError(t, err)
+		assert.Equal(t, DefaultAccountDeletionGracePeriod, got, cfg)
+	}
+
+	_, err = tenantAccountDeletionGrace(&mockTenantSettingRepo{findByTenantIDFn: func(int64) (*model.TenantSetting, error) {
+		return nil, errors.New("db down")
+	}}, 1)
+	assert.Error(t, err)
+}
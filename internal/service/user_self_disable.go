@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/config"
+	"github.com/maintainerd/auth/internal/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+func (s *userService) DisableSelf(ctx context.Context, userUUID uuid.UUID, tenantID int64) (*UserServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "user.disableSelf")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", userUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	user, err := findTenantUser(s.userRepo, userUUID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find user failed")
+		return nil, err
+	}
+	if user.DeletedAt != nil || user.Status != model.StatusActive {
+		return nil, apperror.NewValidation("only an active account can be disabled")
+	}
+
+	reactivateToken := generateSecureToken(32)
+	var disabledUser *model.User
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		txUserRepo := s.userRepo.WithTx(tx)
+
+		if err := txUserRepo.SetStatus(userUUID, model.StatusDisabled); err != nil {
+			return err
+		}
+
+		// End every session so the account is disabled at once
+		if _, err := s.refreshTokenRepo.WithTx(tx).RevokeByUserID(user.UserID); err != nil {
+			return err
+		}
+
+		txUserTokenRepo := s.userTokenRepo.WithTx(tx)
+		if err := txUserTokenRepo.RevokeAllByUserID(user.UserID); err != nil {
+			return err
+		}
+		if _, err := txUserTokenRepo.Create(&model.UserToken{
+			UserID:    user.UserID,
+			TokenType: model.TokenTypeAccountReactivate,
+			Token:     hashAccountToken(reactivateToken),
+		}); err != nil {
+			return err
+		}
+
+		disabledUser, err = txUserRepo.FindByUUID(userUUID, "UserIdentities.Client", "UserIdentities.Tenant", "Roles")
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(s.eventRepo.WithTx(tx), tenantID, UserUpdated(newUserEventPayload(disabledUser)))
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "disable account failed")
+		return nil, err
+	}
+
+	s.invalidateUserCache(ctx, user.UserIdentities)
+	s.logUserLifecycle(ctx, tenantID, &user.UserID, user, model.AuthEventTypeUserDisabled, "account disabled by user")
+	if user.Email != "" {
+		s.notifyAccountDisabled(ctx, user, reactivateToken)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toUserServiceDataResult(disabledUser), nil
+}
+
+func (s *userService) ReactivateSelf(ctx context.Context, token string) (*UserServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "user.reactivateSelf")
+	defer span.End()
+
+	var user, reactivatedUser *model.User
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txUserRepo := s.userRepo.WithTx(tx)
+		txUserTokenRepo := s.userTokenRepo.WithTx(tx)
+
+		userToken, err := txUserTokenRepo.FindActiveByTokenAndType(hashAccountToken(token), model.TokenTypeAccountReactivate)
+		if err != nil {
+			return err
+		}
+		if userToken == nil {
+			return apperror.NewValidation("invalid or expired reactivation token")
+		}
+
+		user, err = txUserRepo.FindByID(userToken.UserID, "UserIdentities")
+		if err != nil {
+			return err
+		}
+		if user == nil || user.DeletedAt != nil || user.Status != model.StatusDisabled {
+			return apperror.NewValidation("invalid or expired reactivation token")
+		}
+
+		if err := txUserRepo.SetStatus(user.UserUUID, model.StatusActive); err != nil {
+			return err
+		}
+		if err := txUserTokenRepo.RevokeByUUID(userToken.UserTokenUUID); err != nil {
+			return err
+		}
+
+		reactivatedUser, err = txUserRepo.FindByUUID(user.UserUUID, "UserIdentities.Client", "UserIdentities.Tenant", "Roles")
+		if err != nil {
+			return err
+		}
+
+		txEventRepo := s.eventRepo.WithTx(tx)
+		for _, tenantID := range userTenantIDs(user) {
+			if err := recordEvent(txEventRepo, tenantID, UserUpdated(newUserEventPayload(reactivatedUser))); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "reactivate account failed")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("user.uuid", user.UserUUID.String()))
+	s.invalidateUserCache(ctx, user.UserIdentities)
+	for _, tenantID := range userTenantIDs(user) {
+		s.logUserLifecycle(ctx, tenantID, &user.UserID, user, model.AuthEventTypeUserEnabled, "account reactivated by user")
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return toUserServiceDataResult(reactivatedUser), nil
+}
+
+// notifyAccountDisabled sends the confirmation email with the reactivation
+// link.
+func (s *userService) notifyAccountDisabled(ctx context.Context, user *model.User, reactivateToken string) {
+	data := struct {
+		ReactivateURL string
+		LogoURL       string
+	}{
+		ReactivateURL: config.AccountHostname + "/account/reactivate?token=" + url.QueryEscape(reactivateToken),
+		LogoURL:       config.EmailLogo,
+	}
+	s.notifyAccount(ctx, user, "internal:user:account:disabled", data)
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/email"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserService_DisableSelf(t *testing.T) {
+	t.Run("disables the account and emails a reactivation link", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		user := newSelfServiceUser()
+		userRepo := selfServiceUserRepo(user)
+		userRepo.setStatusFn = func(id uuid.UUID, status string) error {
+			assert.Equal(t, user.UserUUID, id)
+			user.Status = status
+			return nil
+		}
+		var refreshRevoked, tokensRevoked int64
+		refreshTokenRepo := &mockOAuthRefreshTokenRepo{revokeByUserIDFn: func(id int64) (int64, error) {
+			refreshRevoked = id
+			return 1, nil
+		}}
+		var created *model.UserToken
+		tokenRepo := &mockUserTokenRepo{
+			revokeAllByUserIDFn: func(id int64) error {
+				tokensRevoked = id
+				return nil
+			},
+			createFn: func(e *model.UserToken) (*model.UserToken, error) {
+				created = e
+				return e, nil
+			},
+		}
+
+		origSendEmail := email.SendEmail
+		defer func() { email.SendEmail = origSendEmail }()
+		var sent email.SendEmailParams
+		email.SendEmail = func(_ context.Context, p email.SendEmailParams) error {
+			sent = p
+			return nil
+		}
+
+		eventRepo := &mockEventRepo{}
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(gormDB, userRepo, tokenRepo, refreshTokenRepo, &mockTenantSettingRepo{}, eventRepo, &logged)
+
+		result, err := svc.DisableSelf(context.Background(), user.UserUUID, 1)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		assert.Equal(t, model.StatusDisabled, result.Status)
+		assert.Equal(t, user.UserID, refreshRevoked)
+		assert.Equal(t, user.UserID, tokensRevoked)
+
+		require.NotNil(t, created)
+		assert.Equal(t, model.TokenTypeAccountReactivate, created.TokenType)
+		assert.Nil(t, created.ExpiresAt, "a disabled account can be reactivated at any time")
+
+		// The email carries the token whose hash was stored
+		assert.Equal(t, "jane@example.com", sent.To)
+		start := strings.Index(sent.BodyHTML, `href="`) + len(`href="`)
+		reactivateURL, err := url.Parse(sent.BodyHTML[start : strings.Index(sent.BodyHTML[start:], `"`)+start])
+		require.NoError(t, err)
+		assert.Equal(t, hashAccountToken(reactivateURL.Query().Get("token")), created.Token)
+
+		require.Len(t, eventRepo.created, 1)
+		assert.Equal(t, model.EventTypeUserUpdated, eventRepo.created[0].EventType)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeUserDisabled, logged[0].EventType)
+	})
+
+	t.Run("account not active → validation error", func(t *testing.T) {
+		user := newSelfServiceUser()
+		user.Status = model.StatusSuspended
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(nil, selfServiceUserRepo(user), &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		_, err := svc.DisableSelf(context.Background(), user.UserUUID, 1)
+		var validation *apperror.ValidationError
+		assert.ErrorAs(t, err, &validation)
+		assert.Empty(t, logged)
+	})
+
+	t.Run("user of another tenant → not found", func(t *testing.T) {
+		user := newSelfServiceUser()
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(nil, selfServiceUserRepo(user), &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		_, err := svc.DisableSelf(context.Background(), user.UserUUID, 2)
+		code, ok := apperror.CodeOf(err)
+		require.True(t, ok)
+		assert.Equal(t, apperror.CodeUserNotFound, code)
+	})
+}
+
+func TestUserService_ReactivateSelf(t *testing.T) {
+	t.Run("reactivates the account", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		user := newSelfServiceUser()
+		user.Status = model.StatusDisabled
+		userRepo := selfServiceUserRepo(user)
+		userRepo.setStatusFn = func(_ uuid.UUID, status string) error {
+			user.Status = status
+			return nil
+		}
+		pending := &model.UserToken{UserTokenUUID: uuid.New(), UserID: user.UserID}
+		var revoked uuid.UUID
+		tokenRepo := &mockUserTokenRepo{
+			findActiveByTokenFn: func(token, tokenType string) (*model.UserToken, error) {
+				assert.Equal(t, hashAccountToken("come-back"), token)
+				assert.Equal(t, model.TokenTypeAccountReactivate, tokenType)
+				return pending, nil
+			},
+			revokeByUUIDFn: func(id uuid.UUID) error {
+				revoked = id
+				return nil
+			},
+		}
+		eventRepo := &mockEventRepo{}
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(gormDB, userRepo, tokenRepo, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, eventRepo, &logged)
+
+		result, err := svc.ReactivateSelf(context.Background(), "come-back")
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		assert.Equal(t, model.StatusActive, result.Status)
+		assert.Equal(t, pending.UserTokenUUID, revoked)
+		require.Len(t, eventRepo.created, 1)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeUserEnabled, logged[0].EventType)
+	})
+
+	t.Run("unknown token → validation error", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(gormDB, selfServiceUserRepo(newSelfServiceUser()), &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		_, err := svc.ReactivateSelf(context.Background(), "nope")
+		var validation *apperror.ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+
+	t.Run("account re-enabled by an admin → validation error", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		user := newSelfServiceUser()
+		user.Status = model.StatusSuspended
+		userRepo := selfServiceUserRepo(user)
+		userRepo.setStatusFn = func(uuid.UUID, string) error {
+			t.Fatal("only a disabled account can be reactivated")
+			return nil
+		}
+		tokenRepo := &mockUserTokenRepo{findActiveByTokenFn: func(string, string) (*model.UserToken, error) {
+			return &model.UserToken{UserID: user.UserID}, nil
+		}}
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(gormDB, userRepo, tokenRepo, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		_, err := svc.ReactivateSelf(context.Background(), "come-back")
+		var validation *apperror.ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+}
+
+func TestUserService_SetStatus_Disabled(t *testing.T) {
+	t.Run("enabling a disabled user revokes the reactivation link", func(t *testing.T) {
+		gormDB, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		user := newSelfServiceUser()
+		user.Status = model.StatusDisabled
+		user.UserIdentities[0].Tenant = &model.Tenant{TenantID: 1, IsSystem: true}
+		link := model.UserToken{UserTokenUUID: uuid.New(), UserID: user.UserID, TokenType: model.TokenTypeAccountReactivate}
+		var revoked uuid.UUID
+		tokenRepo := &mockUserTokenRepo{
+			findByUserIDAndTokenTypeFn: func(_ int64, tokenType string) ([]model.UserToken, error) {
+				assert.Equal(t, model.TokenTypeAccountReactivate, tokenType)
+				return []model.UserToken{link}, nil
+			},
+			revokeByUUIDFn: func(id uuid.UUID) error {
+				revoked = id
+				return nil
+			},
+		}
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(gormDB, selfServiceUserRepo(user), tokenRepo, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		_, err := svc.SetStatus(context.Background(), user.UserUUID, 1, model.StatusActive, user.UserUUID)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		assert.Equal(t, link.UserTokenUUID, revoked)
+	})
+
+	t.Run("disallowed transition → validation error", func(t *testing.T) {
+		user := newSelfServiceUser()
+		user.UserIdentities[0].Tenant = &model.Tenant{TenantID: 1, IsSystem: true}
+		userRepo := selfServiceUserRepo(user)
+		userRepo.setStatusFn = func(uuid.UUID, string) error {
+			t.Fatal("status must not change")
+			return nil
+		}
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(nil, userRepo, &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		_, err := svc.SetStatus(context.Background(), user.UserUUID, 1, model.StatusPending, user.UserUUID)
+		var validation *apperror.ValidationError
+		require.ErrorAs(t, err, &validation)
+		assert.Contains(t, err.Error(), "cannot change user status from active to pending")
+	})
+}
+
+func TestValidateUserStatusTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{model.StatusPending, model.StatusActive, true},
+		{model.StatusActive, model.StatusSuspended, true},
+		{model.StatusActive, model.StatusActive, true},
+		{model.StatusSuspended, model.StatusInactive, true},
+		{model.StatusDisabled, model.StatusActive, true},
+		{model.StatusDisabled, model.StatusSuspended, true},
+		{model.StatusActive, model.StatusPending, false},
+		{model.StatusActive, model.StatusDisabled, false},
+		{model.StatusDisabled, model.StatusInactive, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			err := validateUserStatusTransition(tt.from, tt.to)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/middleware"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/ptr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/datatypes"
+)
+
+func (s *userService) RevokeSessions(ctx context.Context, userUUID uuid.UUID, tenantID int64) (int64, error) {
+	_, span := otel.Tracer("service").Start(ctx, "user.revokeSessions")
+	defer span.End()
+	span.SetAttributes(attribute.String("user.uuid", userUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	user, err := findTenantUser(s.userRepo, userUUID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find user failed")
+		return 0, err
+	}
+
+	revoked, err := s.refreshTokenRepo.RevokeByUserID(user.UserID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "revoke sessions failed")
+		return 0, apperror.NewInternal("failed to revoke sessions", err)
+	}
+	span.SetAttributes(attribute.Int64("sessions.revoked", revoked))
+
+	raw, _ := json.Marshal(map[string]any{"user_uuid": user.UserUUID.String(), "revoked": revoked})
+	s.authEventService.Log(ctx, AuthEventInput{
+		TenantID:     tenantID,
+		TargetUserID: &user.UserID,
+		IPAddress:    middleware.ClientIPFromContext(ctx),
+		UserAgent:    ptr.PtrOrNil(middleware.UserAgentFromContext(ctx)),
+		Category:     model.AuthEventCategoryAuthn,
+		EventType:    model.AuthEventTypeTokenRevoked,
+		Severity:     model.AuthEventSeverityWarn,
+		Result:       model.AuthEventResultSuccess,
+		Description:  ptr.Ptr(fmt.Sprintf("%d sessions revoked by an operator", revoked)),
+		Metadata:     datatypes.JSON(raw),
+	})
+
+	span.SetStatus(codes.Ok, "")
+	return revoked, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserService_RevokeSessions(t *testing.T) {
+	t.Run("revokes every refresh token", func(t *testing.T) {
+		user := newSelfServiceUser()
+		var revokedFor int64
+		refreshTokenRepo := &mockOAuthRefreshTokenRepo{revokeByUserIDFn: func(id int64) (int64, error) {
+			revokedFor = id
+			return 3, nil
+		}}
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(nil, selfServiceUserRepo(user), &mockUserTokenRepo{}, refreshTokenRepo, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		revoked, err := svc.RevokeSessions(context.Background(), user.UserUUID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), revoked)
+		assert.Equal(t, user.UserID, revokedFor)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeTokenRevoked, logged[0].EventType)
+		assert.Equal(t, model.AuthEventCategoryAuthn, logged[0].Category)
+	})
+
+	t.Run("user of another tenant → not found", func(t *testing.T) {
+		user := newSelfServiceUser()
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(nil, selfServiceUserRepo(user), &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		_, err := svc.RevokeSessions(context.Background(), user.UserUUID, 2)
+		code, ok := apperror.CodeOf(err)
+		require.True(t, ok)
+		assert.Equal(t, apperror.CodeUserNotFound, code)
+		assert.Empty(t, logged)
+	})
+
+	t.Run("repository error → internal error", func(t *testing.T) {
+		user := newSelfServiceUser()
+		refreshTokenRepo := &mockOAuthRefreshTokenRepo{revokeByUserIDFn: func(int64) (int64, error) { return 0, errors.New("db down") }}
+		var logged []AuthEventInput
+		svc := newSelfServiceUserService(nil, selfServiceUserRepo(user), &mockUserTokenRepo{}, refreshTokenRepo, &mockTenantSettingRepo{}, &mockEventRepo{}, &logged)
+
+		_, err := svc.RevokeSessions(context.Background(), user.UserUUID, 1)
+		assert.ErrorAs(t, err, new(*apperror.InternalError))
+		assert.Empty(t, logged)
+	})
+}
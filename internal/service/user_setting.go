@@ -21,6 +21,8 @@ type UserSettingServiceDataResult struct {
 	Timezone                 *string
 	PreferredLanguage        *string
 	Locale                   *string
+	Theme                    *string
+	DateFormat               *string
 	SocialLinks              datatypes.JSON
 	PreferredContactMethod   *string
 	MarketingEmailConsent    bool
@@ -41,8 +43,9 @@ type UserSettingServiceDataResult struct {
 type UserSettingService interface {
 	CreateOrUpdateUserSetting(
 		ctx context.Context,
+		tenantID int64,
 		userUUID uuid.UUID,
-		timezone, preferredLanguage, locale *string,
+		timezone, preferredLanguage, locale, theme, dateFormat *string,
 		socialLinks map[string]any,
 		preferredContactMethod *string,
 		marketingEmailConsent, smsNotificationsConsent, pushNotificationsConsent *bool,
@@ -54,30 +57,51 @@ type UserSettingService interface {
 	GetByUUID(ctx context.Context, userSettingUUID uuid.UUID) (*UserSettingServiceDataResult, error)
 	GetByUserUUID(ctx context.Context, userUUID uuid.UUID) (*UserSettingServiceDataResult, error)
 	DeleteByUUID(ctx context.Context, userSettingUUID uuid.UUID) (*UserSettingServiceDataResult, error)
+	// GetEffective returns the user's settings in the tenant, with the
+	// tenant's defaults, or the built-in ones, in place of the preferences
+	// the user has not chosen. It does not fail for users without settings.
+	GetEffective(ctx context.Context, tenantID int64, userUUID uuid.UUID) (*UserSettingServiceDataResult, error)
+	// Reset deletes the user's settings, returning the defaults that now
+	// apply.
+	Reset(ctx context.Context, tenantID int64, userUUID uuid.UUID) (*UserSettingServiceDataResult, error)
+}
+
+// builtinUserSettingDefaults apply to preferences that neither the user nor
+// the tenant has chosen.
+var builtinUserSettingDefaults = model.UserSettingDefaults{
+	Theme:             model.ThemeSystem,
+	PreferredLanguage: "en",
+	Locale:            "en_US",
+	Timezone:          "UTC",
+	DateFormat:        model.DateFormatISO,
 }
 
 type userSettingService struct {
-	db              *gorm.DB
-	userSettingRepo repository.UserSettingRepository
-	userRepo        repository.UserRepository
+	db                *gorm.DB
+	userSettingRepo   repository.UserSettingRepository
+	userRepo          repository.UserRepository
+	tenantSettingRepo repository.TenantSettingRepository
 }
 
 func NewUserSettingService(
 	db *gorm.DB,
 	userSettingRepo repository.UserSettingRepository,
 	userRepo repository.UserRepository,
+	tenantSettingRepo repository.TenantSettingRepository,
 ) UserSettingService {
 	return &userSettingService{
-		db:              db,
-		userSettingRepo: userSettingRepo,
-		userRepo:        userRepo,
+		db:                db,
+		userSettingRepo:   userSettingRepo,
+		userRepo:          userRepo,
+		tenantSettingRepo: tenantSettingRepo,
 	}
 }
 
 func (s *userSettingService) CreateOrUpdateUserSetting(
 	ctx context.Context,
+	tenantID int64,
 	userUUID uuid.UUID,
-	timezone, preferredLanguage, locale *string,
+	timezone, preferredLanguage, locale, theme, dateFormat *string,
 	socialLinks map[string]any,
 	preferredContactMethod *string,
 	marketingEmailConsent, smsNotificationsConsent, pushNotificationsConsent *bool,
@@ -88,7 +112,7 @@ func (s *userSettingService) CreateOrUpdateUserSetting(
 ) (*UserSettingServiceDataResult, error) {
 	_, span := otel.Tracer("service").Start(ctx, "user_setting.create_or_update")
 	defer span.End()
-	span.SetAttributes(attribute.String("user_uuid", userUUID.String()))
+	span.SetAttributes(attribute.String("user_uuid", userUUID.String()), attribute.Int64("tenant.id", tenantID))
 
 	var updatedUserSetting *model.UserSetting
 
@@ -98,8 +122,8 @@ func (s *userSettingService) CreateOrUpdateUserSetting(
 		txUserRepo := s.userRepo.WithTx(tx)
 
 		// Step 2: Find user by UUID to get userID
-		user, err := txUserRepo.FindByUUID(userUUID)
-		if err != nil || user == nil {
+		user, err := txUserRepo.FindByUUID(userUUID, "UserIdentities")
+		if err != nil || user == nil || !userInTenant(user, tenantID) {
 			return apperror.NewNotFound("user not found")
 		}
 
@@ -135,6 +159,8 @@ func (s *userSettingService) CreateOrUpdateUserSetting(
 		userSetting.Timezone = timezone
 		userSetting.PreferredLanguage = preferredLanguage
 		userSetting.Locale = locale
+		userSetting.Theme = theme
+		userSetting.DateFormat = dateFormat
 
 		// Social Media & External Links
 		if socialLinksJSON != nil {
@@ -258,6 +284,108 @@ func (s *userSettingService) DeleteByUUID(ctx context.Context, userSettingUUID u
 	return toUserSettingServiceDataResult(userSetting), nil
 }
 
+func (s *userSettingService) GetEffective(ctx context.Context, tenantID int64, userUUID uuid.UUID) (*UserSettingServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "user_setting.get_effective")
+	defer span.End()
+	span.SetAttributes(attribute.String("user_uuid", userUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	user, err := findTenantUser(s.userRepo, userUUID, tenantID)
+	if err != nil {
+		span.SetStatus(codes.Error, "user not found")
+		return nil, err
+	}
+
+	userSetting, err := s.userSettingRepo.FindByUserID(user.UserID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find user setting failed")
+		return nil, apperror.NewInternal("failed to load user settings", err)
+	}
+
+	result, err := s.withDefaults(tenantID, userSetting)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "load user setting defaults failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+func (s *userSettingService) Reset(ctx context.Context, tenantID int64, userUUID uuid.UUID) (*UserSettingServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "user_setting.reset")
+	defer span.End()
+	span.SetAttributes(attribute.String("user_uuid", userUUID.String()), attribute.Int64("tenant.id", tenantID))
+
+	user, err := findTenantUser(s.userRepo, userUUID, tenantID)
+	if err != nil {
+		span.SetStatus(codes.Error, "user not found")
+		return nil, err
+	}
+
+	userSetting, err := s.userSettingRepo.FindByUserID(user.UserID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find user setting failed")
+		return nil, apperror.NewInternal("failed to load user settings", err)
+	}
+	if userSetting != nil {
+		if err := s.userSettingRepo.DeleteByUUID(userSetting.UserSettingUUID); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "delete user setting failed")
+			return nil, apperror.NewInternal("failed to reset user settings", err)
+		}
+	}
+
+	result, err := s.withDefaults(tenantID, nil)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "load user setting defaults failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// withDefaults converts userSetting, which may be nil, filling the
+// preferences the user has not chosen from the tenant's defaults and then
+// the built-in ones.
+func (s *userSettingService) withDefaults(tenantID int64, userSetting *model.UserSetting) (*UserSettingServiceDataResult, error) {
+	tenantSetting, err := s.tenantSettingRepo.FindByTenantID(tenantID)
+	if err != nil {
+		return nil, apperror.NewInternal("failed to load user setting defaults", err)
+	}
+	var defaults model.UserSettingDefaults
+	if tenantSetting != nil && len(tenantSetting.UserSettingDefaults) > 0 {
+		// Written by UpdateUserSettingDefaults; a broken document only
+		// loses the tenant's defaults
+		_ = json.Unmarshal(tenantSetting.UserSettingDefaults, &defaults)
+	}
+
+	result := toUserSettingServiceDataResult(userSetting)
+	if result == nil {
+		result = &UserSettingServiceDataResult{}
+	}
+	fill := func(field **string, tenantDefault, builtin string) {
+		if *field != nil {
+			return
+		}
+		if tenantDefault != "" {
+			*field = &tenantDefault
+		} else {
+			*field = &builtin
+		}
+	}
+	fill(&result.Theme, defaults.Theme, builtinUserSettingDefaults.Theme)
+	fill(&result.PreferredLanguage, defaults.PreferredLanguage, builtinUserSettingDefaults.PreferredLanguage)
+	fill(&result.Locale, defaults.Locale, builtinUserSettingDefaults.Locale)
+	fill(&result.Timezone, defaults.Timezone, builtinUserSettingDefaults.Timezone)
+	fill(&result.DateFormat, defaults.DateFormat, builtinUserSettingDefaults.DateFormat)
+	return result, nil
+}
+
 // Helper functions
 func toUserSettingServiceDataResult(userSetting *model.UserSetting) *UserSettingServiceDataResult {
 	if userSetting == nil {
@@ -269,6 +397,8 @@ func toUserSettingServiceDataResult(userSetting *model.UserSetting) *UserSetting
 		Timezone:                 userSetting.Timezone,
 		PreferredLanguage:        userSetting.PreferredLanguage,
 		Locale:                   userSetting.Locale,
+		Theme:                    userSetting.Theme,
+		DateFormat:               userSetting.DateFormat,
 		SocialLinks:              userSetting.SocialLinks,
 		PreferredContactMethod:   userSetting.PreferredContactMethod,
 		MarketingEmailConsent:    userSetting.MarketingEmailConsent,
@@ -8,13 +8,15 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
 )
 
 func newUserSettingSvc(settingRepo *mockUserSettingRepo, userRepo *mockUserRepo) UserSettingService {
-	return NewUserSettingService(nil, settingRepo, userRepo)
+	return NewUserSettingService(nil, settingRepo, userRepo, &mockTenantSettingRepo{})
 }
 
 func TestUserSettingService_GetByUUID(t *testing.T) {
@@ -58,7 +60,7 @@ func TestUserSettingService_GetByUserUUID(t *testing.T) {
 			findByUserIDFn: func(_ int64) (*model.UserSetting, error) { return nil, nil },
 		}, &mockUserRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
-				return &model.User{UserID: 1}, nil
+				return &model.User{UserID: 1, UserIdentities: []model.UserIdentity{{TenantID: 1}}}, nil
 			},
 		})
 		_, err := svc.GetByUserUUID(context.Background(), userUUID)
@@ -74,7 +76,7 @@ func TestUserSettingService_GetByUserUUID(t *testing.T) {
 			},
 		}, &mockUserRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
-				return &model.User{UserID: 1}, nil
+				return &model.User{UserID: 1, UserIdentities: []model.UserIdentity{{TenantID: 1}}}, nil
 			},
 		})
 		res, err := svc.GetByUserUUID(context.Background(), userUUID)
@@ -129,8 +131,22 @@ func TestUserSettingService_CreateOrUpdateUserSetting(t *testing.T) {
 		mock.ExpectRollback()
 		svc := NewUserSettingService(db, &mockUserSettingRepo{}, &mockUserRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) { return nil, nil },
-		})
-		_, err := svc.CreateOrUpdateUserSetting(context.Background(), userUUID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		}, &mockTenantSettingRepo{})
+		_, err := svc.CreateOrUpdateUserSetting(context.Background(), 1, userUUID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "user not found")
+	})
+
+	t.Run("user of another tenant → rollback", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := NewUserSettingService(db, &mockUserSettingRepo{}, &mockUserRepo{
+			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
+				return &model.User{UserID: 1, UserIdentities: []model.UserIdentity{{TenantID: 2}}}, nil
+			},
+		}, &mockTenantSettingRepo{})
+		_, err := svc.CreateOrUpdateUserSetting(context.Background(), 1, userUUID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "user not found")
 	})
@@ -148,10 +164,10 @@ func TestUserSettingService_CreateOrUpdateUserSetting(t *testing.T) {
 			},
 		}, &mockUserRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
-				return &model.User{UserID: 1}, nil
+				return &model.User{UserID: 1, UserIdentities: []model.UserIdentity{{TenantID: 1}}}, nil
 			},
-		})
-		res, err := svc.CreateOrUpdateUserSetting(context.Background(), userUUID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		}, &mockTenantSettingRepo{})
+		res, err := svc.CreateOrUpdateUserSetting(context.Background(), 1, userUUID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.NoError(t, err)
 		assert.Equal(t, sid, res.UserSettingUUID)
 	})
@@ -166,10 +182,10 @@ func TestUserSettingService_CreateOrUpdateUserSetting(t *testing.T) {
 			},
 		}, &mockUserRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
-				return &model.User{UserID: 1}, nil
+				return &model.User{UserID: 1, UserIdentities: []model.UserIdentity{{TenantID: 1}}}, nil
 			},
-		})
-		_, err := svc.CreateOrUpdateUserSetting(context.Background(), userUUID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		}, &mockTenantSettingRepo{})
+		_, err := svc.CreateOrUpdateUserSetting(context.Background(), 1, userUUID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "db error")
 	})
@@ -183,10 +199,10 @@ func TestUserSettingService_CreateOrUpdateUserSetting(t *testing.T) {
 			findByUserIDFn: func(_ int64) (*model.UserSetting, error) { return nil, nil },
 		}, &mockUserRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
-				return &model.User{UserID: 1}, nil
+				return &model.User{UserID: 1, UserIdentities: []model.UserIdentity{{TenantID: 1}}}, nil
 			},
-		})
-		_, err := svc.CreateOrUpdateUserSetting(context.Background(), userUUID, nil, nil, nil, badLinks, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		}, &mockTenantSettingRepo{})
+		_, err := svc.CreateOrUpdateUserSetting(context.Background(), 1, userUUID, nil, nil, nil, nil, nil, badLinks, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "invalid social links")
 	})
@@ -202,10 +218,10 @@ func TestUserSettingService_CreateOrUpdateUserSetting(t *testing.T) {
 			},
 		}, &mockUserRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
-				return &model.User{UserID: 1}, nil
+				return &model.User{UserID: 1, UserIdentities: []model.UserIdentity{{TenantID: 1}}}, nil
 			},
-		})
-		_, err := svc.CreateOrUpdateUserSetting(context.Background(), userUUID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		}, &mockTenantSettingRepo{})
+		_, err := svc.CreateOrUpdateUserSetting(context.Background(), 1, userUUID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "create failed")
 	})
@@ -222,10 +238,10 @@ func TestUserSettingService_CreateOrUpdateUserSetting(t *testing.T) {
 			},
 		}, &mockUserRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
-				return &model.User{UserID: 1}, nil
+				return &model.User{UserID: 1, UserIdentities: []model.UserIdentity{{TenantID: 1}}}, nil
 			},
-		})
-		res, err := svc.CreateOrUpdateUserSetting(context.Background(), userUUID, &tz, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		}, &mockTenantSettingRepo{})
+		res, err := svc.CreateOrUpdateUserSetting(context.Background(), 1, userUUID, &tz, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.NoError(t, err)
 		assert.Equal(t, sid, res.UserSettingUUID)
 		assert.Equal(t, &tz, res.Timezone)
@@ -244,10 +260,10 @@ func TestUserSettingService_CreateOrUpdateUserSetting(t *testing.T) {
 			},
 		}, &mockUserRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
-				return &model.User{UserID: 1}, nil
+				return &model.User{UserID: 1, UserIdentities: []model.UserIdentity{{TenantID: 1}}}, nil
 			},
-		})
-		_, err := svc.CreateOrUpdateUserSetting(context.Background(), userUUID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		}, &mockTenantSettingRepo{})
+		_, err := svc.CreateOrUpdateUserSetting(context.Background(), 1, userUUID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "update failed")
 	})
@@ -275,10 +291,10 @@ func TestUserSettingService_CreateOrUpdateUserSetting(t *testing.T) {
 			findByUserIDFn: func(_ int64) (*model.UserSetting, error) { return nil, nil },
 		}, &mockUserRepo{
 			findByUUIDFn: func(_ any, _ ...string) (*model.User, error) {
-				return &model.User{UserID: 1}, nil
+				return &model.User{UserID: 1, UserIdentities: []model.UserIdentity{{TenantID: 1}}}, nil
 			},
-		})
-		res, err := svc.CreateOrUpdateUserSetting(context.Background(), userUUID, &tz, &lang, &locale, links, &contact, &mktg, &sms, &push, &vis, &consent, &now, &now, &ecName, &ecPhone, &ecEmail, &ecRel)
+		}, &mockTenantSettingRepo{})
+		res, err := svc.CreateOrUpdateUserSetting(context.Background(), 1, userUUID, &tz, &lang, &locale, nil, nil, links, &contact, &mktg, &sms, &push, &vis, &consent, &now, &now, &ecName, &ecPhone, &ecEmail, &ecRel)
 		require.NoError(t, err)
 		assert.Equal(t, &tz, res.Timezone)
 		assert.Equal(t, &lang, res.PreferredLanguage)
@@ -315,3 +331,112 @@ func TestToUserSettingServiceDataResult(t *testing.T) {
 		assert.Equal(t, &tz, res.Timezone)
 	})
 }
+
+// ---------------------------------------------------------------------------
+// GetEffective / Reset
+// ---------------------------------------------------------------------------
+
+// newEffectiveUserSettingSvc returns a UserSettingService for a user of
+// tenant 1 with the given settings and tenant defaults.
+func newEffectiveUserSettingSvc(userUUID uuid.UUID, setting *model.UserSetting, defaults string) (UserSettingService, *mockUserSettingRepo) {
+	settingRepo := &mockUserSettingRepo{
+		findByUserIDFn: func(int64) (*model.UserSetting, error) { return setting, nil },
+	}
+	userRepo := &mockUserRepo{findByUUIDFn: func(id any, _ ...string) (*model.User, error) {
+		if id == userUUID {
+			return &model.User{UserID: 1, UserUUID: userUUID, UserIdentities: []model.UserIdentity{{TenantID: 1}}}, nil
+		}
+		return nil, nil
+	}}
+	tenantSettingRepo := &mockTenantSettingRepo{findByTenantIDFn: func(int64) (*model.TenantSetting, error) {
+		return &model.TenantSetting{UserSettingDefaults: datatypes.JSON(defaults)}, nil
+	}}
+	return NewUserSettingService(nil, settingRepo, userRepo, tenantSettingRepo), settingRepo
+}
+
+func TestUserSettingService_GetEffective(t *testing.T) {
+	userUUID := uuid.New()
+
+	t.Run("built-in defaults", func(t *testing.T) {
+		svc, _ := newEffectiveUserSettingSvc(userUUID, nil, `{}`)
+		res, err := svc.GetEffective(context.Background(), 1, userUUID)
+		require.NoError(t, err)
+		assert.Equal(t, model.ThemeSystem, *res.Theme)
+		assert.Equal(t, "en", *res.PreferredLanguage)
+		assert.Equal(t, "en_US", *res.Locale)
+		assert.Equal(t, "UTC", *res.Timezone)
+		assert.Equal(t, model.DateFormatISO, *res.DateFormat)
+	})
+
+	t.Run("tenant defaults", func(t *testing.T) {
+		svc, _ := newEffectiveUserSettingSvc(userUUID, nil, `{"theme":"dark","timezone":"Europe/Berlin"}`)
+		res, err := svc.GetEffective(context.Background(), 1, userUUID)
+		require.NoError(t, err)
+		assert.Equal(t, model.ThemeDark, *res.Theme)
+		assert.Equal(t, "Europe/Berlin", *res.Timezone)
+		assert.Equal(t, model.DateFormatISO, *res.DateFormat)
+	})
+
+	t.Run("user choices win", func(t *testing.T) {
+		theme, df := model.ThemeLight, model.DateFormatDMY
+		svc, _ := newEffectiveUserSettingSvc(userUUID, &model.UserSetting{Theme: &theme, DateFormat: &df}, `{"theme":"dark"}`)
+		res, err := svc.GetEffective(context.Background(), 1, userUUID)
+		require.NoError(t, err)
+		assert.Equal(t, model.ThemeLight, *res.Theme)
+		assert.Equal(t, model.DateFormatDMY, *res.DateFormat)
+		assert.Equal(t, "UTC", *res.Timezone)
+	})
+
+	t.Run("user of another tenant", func(t *testing.T) {
+		svc, _ := newEffectiveUserSettingSvc(userUUID, nil, `{}`)
+		_, err := svc.GetEffective(context.Background(), 2, userUUID)
+		var notFound *apperror.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("settings lookup error", func(t *testing.T) {
+		svc, settingRepo := newEffectiveUserSettingSvc(userUUID, nil, `{}`)
+		settingRepo.findByUserIDFn = func(int64) (*model.UserSetting, error) { return nil, errors.New("db down") }
+		_, err := svc.GetEffective(context.Background(), 1, userUUID)
+		assert.Error(t, err)
+	})
+}
+
+func TestUserSettingService_Reset(t *testing.T) {
+	userUUID := uuid.New()
+	settingUUID := uuid.New()
+	theme := model.ThemeDark
+
+	t.Run("deletes the settings", func(t *testing.T) {
+		svc, settingRepo := newEffectiveUserSettingSvc(userUUID, &model.UserSetting{UserSettingUUID: settingUUID, Theme: &theme}, `{}`)
+		var deleted any
+		settingRepo.deleteByUUIDFn = func(id any) error {
+			deleted = id
+			return nil
+		}
+		res, err := svc.Reset(context.Background(), 1, userUUID)
+		require.NoError(t, err)
+		assert.Equal(t, settingUUID, deleted)
+		assert.Equal(t, model.ThemeSystem, *res.Theme)
+	})
+
+	t.Run("without settings", func(t *testing.T) {
+		svc, _ := newEffectiveUserSettingSvc(userUUID, nil, `{}`)
+		_, err := svc.Reset(context.Background(), 1, userUUID)
+		require.NoError(t, err)
+	})
+
+	t.Run("delete error", func(t *testing.T) {
+		svc, settingRepo := newEffectiveUserSettingSvc(userUUID, &model.UserSetting{UserSettingUUID: settingUUID}, `{}`)
+		settingRepo.deleteByUUIDFn = func(any) error { return errors.New("db down") }
+		_, err := svc.Reset(context.Background(), 1, userUUID)
+		assert.Error(t, err)
+	})
+
+	t.Run("user of another tenant", func(t *testing.T) {
+		svc, _ := newEffectiveUserSettingSvc(userUUID, nil, `{}`)
+		_, err := svc.Reset(context.Background(), 2, userUUID)
+		var notFound *apperror.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+}
@@ -3,10 +3,13 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
 	"github.com/maintainerd/auth/internal/cache"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/repository"
@@ -34,7 +37,7 @@ func fullUserSvc(
 ) (*gorm.DB, UserService) {
 	t.Helper()
 	db, _ := newMockGormDB(t)
-	svc := NewUserService(db, userRepo, uiRepo, urRepo, roleRepo, tenantRepo, idpRepo, clientRepo, upRepo, cache.NopInvalidator{})
+	svc := NewUserService(db, userRepo, uiRepo, urRepo, roleRepo, tenantRepo, idpRepo, clientRepo, upRepo, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockEmailTemplateRepo{}, &mockEventRepo{}, &mockAuthEventService{}, nil, cache.NopInvalidator{}, NopPlanEnforcer{})
 	return db, svc
 }
 
@@ -51,7 +54,7 @@ func fullUserSvcWithMock(
 ) (*gorm.DB, sqlmock.Sqlmock, UserService) {
 	t.Helper()
 	db, mock := newMockGormDB(t)
-	svc := NewUserService(db, userRepo, uiRepo, urRepo, roleRepo, tenantRepo, idpRepo, clientRepo, upRepo, cache.NopInvalidator{})
+	svc := NewUserService(db, userRepo, uiRepo, urRepo, roleRepo, tenantRepo, idpRepo, clientRepo, upRepo, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockEmailTemplateRepo{}, &mockEventRepo{}, &mockAuthEventService{}, nil, cache.NopInvalidator{}, NopPlanEnforcer{})
 	return db, mock, svc
 }
 
@@ -131,6 +134,175 @@ func TestUserService_Get(t *testing.T) {
 	})
 }
 
+// ---------------------------------------------------------------------------
+// Search
+// ---------------------------------------------------------------------------
+
+func TestUserService_Search(t *testing.T) {
+	t.Run("repository error", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
+		ur.searchFn = func(string, repository.UserRepositoryGetFilter) (*repository.PaginationResult[model.User], error) {
+			return nil, errors.New("db error")
+		}
+		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+		_, err := svc.Search(context.Background(), "alice", UserServiceGetFilter{TenantID: 1})
+		require.Error(t, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
+		ur.searchFn = func(term string, f repository.UserRepositoryGetFilter) (*repository.PaginationResult[model.User], error) {
+			assert.Equal(t, "alice", term)
+			require.NotNil(t, f.TenantID)
+			assert.Equal(t, int64(1), *f.TenantID)
+			assert.Equal(t, []string{model.StatusActive}, f.Status)
+			return &repository.PaginationResult[model.User]{Data: []model.User{{UserUUID: uuid.New(), Username: "alice"}}, Total: 1, Page: 1, Limit: 20, TotalPages: 1}, nil
+		}
+		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+		res, err := svc.Search(context.Background(), "alice", UserServiceGetFilter{TenantID: 1, Status: []string{model.StatusActive}})
+		require.NoError(t, err)
+		require.Len(t, res.Data, 1)
+		assert.Equal(t, "alice", res.Data[0].Username)
+		assert.Equal(t, int64(1), res.Total)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// GetByCursor
+// ---------------------------------------------------------------------------
+
+func TestUserService_GetByCursor(t *testing.T) {
+	t.Run("invalid cursor", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
+		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+		_, err := svc.GetByCursor(context.Background(), UserServiceGetFilter{TenantID: 1}, "bogus")
+		var ve *apperror.ValidationError
+		assert.ErrorAs(t, err, &ve)
+	})
+
+	t.Run("pages after the cursor", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
+		ur.findCursorPaginatedFn = func(f repository.UserRepositoryGetFilter, page repository.CursorPage) (*repository.CursorPaginationResult[model.User], error) {
+			assert.Equal(t, int64(1), *f.TenantID)
+			assert.Equal(t, repository.CursorPage{AfterID: 50, Limit: 2}, page)
+			return &repository.CursorPaginationResult[model.User]{
+				Data:    []model.User{{UserID: 49, Username: "b"}, {UserID: 48, Username: "a"}},
+				Limit:   2,
+				HasMore: true,
+			}, nil
+		}
+		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+		res, err := svc.GetByCursor(context.Background(), UserServiceGetFilter{TenantID: 1, Limit: 2}, encodeListCursor(50))
+		require.NoError(t, err)
+		require.Len(t, res.Data, 2)
+		assert.True(t, res.HasMore)
+		next, err := decodeListCursor(res.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, int64(48), next)
+	})
+
+	t.Run("last page has no next cursor", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
+		ur.findCursorPaginatedFn = func(_ repository.UserRepositoryGetFilter, page repository.CursorPage) (*repository.CursorPaginationResult[model.User], error) {
+			assert.True(t, page.Ascending)
+			return &repository.CursorPaginationResult[model.User]{Data: []model.User{{UserID: 3}}, Limit: 20}, nil
+		}
+		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+		res, err := svc.GetByCursor(context.Background(), UserServiceGetFilter{TenantID: 1, SortOrder: "asc"}, "")
+		require.NoError(t, err)
+		assert.False(t, res.HasMore)
+		assert.Empty(t, res.NextCursor)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
+		ur.findCursorPaginatedFn = func(repository.UserRepositoryGetFilter, repository.CursorPage) (*repository.CursorPaginationResult[model.User], error) {
+			return nil, errors.New("db error")
+		}
+		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+		_, err := svc.GetByCursor(context.Background(), UserServiceGetFilter{TenantID: 1}, "")
+		require.Error(t, err)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Export
+// ---------------------------------------------------------------------------
+
+func TestUserService_Export(t *testing.T) {
+	t.Run("invalid client UUID", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
+		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+		bad := "not-a-uuid"
+		err := svc.Export(context.Background(), UserServiceGetFilter{ClientUUID: &bad, TenantID: 1}, func(UserServiceDataResult) error { return nil })
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid client UUID")
+	})
+
+	t.Run("reads in chunks after the last user ID", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
+		total := userExportChunkSize + 3
+		var calls []int64
+		ur.findFilteredAfterIDFn = func(f repository.UserRepositoryGetFilter, afterID int64, limit int) ([]model.User, error) {
+			require.NotNil(t, f.TenantID)
+			assert.Equal(t, int64(1), *f.TenantID)
+			assert.Equal(t, userExportChunkSize, limit)
+			calls = append(calls, afterID)
+			var users []model.User
+			for id := afterID + 1; id <= int64(total) && len(users) < limit; id++ {
+				users = append(users, model.User{UserID: id, UserUUID: uuid.New(), Username: fmt.Sprintf("user%d", id)})
+			}
+			return users, nil
+		}
+		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+
+		var got []string
+		err := svc.Export(context.Background(), UserServiceGetFilter{TenantID: 1}, func(u UserServiceDataResult) error {
+			got = append(got, u.Username)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{0, int64(userExportChunkSize)}, calls)
+		require.Len(t, got, total)
+		assert.Equal(t, "user1", got[0])
+		assert.Equal(t, fmt.Sprintf("user%d", total), got[total-1])
+	})
+
+	t.Run("callback error stops the export", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
+		ur.findFilteredAfterIDFn = func(_ repository.UserRepositoryGetFilter, _ int64, _ int) ([]model.User, error) {
+			return []model.User{{UserID: 1}, {UserID: 2}}, nil
+		}
+		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+		calls := 0
+		err := svc.Export(context.Background(), UserServiceGetFilter{TenantID: 1}, func(UserServiceDataResult) error {
+			calls++
+			return errors.New("client went away")
+		})
+		require.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
+		ur.findFilteredAfterIDFn = func(_ repository.UserRepositoryGetFilter, _ int64, _ int) ([]model.User, error) {
+			return nil, errors.New("db error")
+		}
+		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+		err := svc.Export(context.Background(), UserServiceGetFilter{TenantID: 1}, func(UserServiceDataResult) error { return nil })
+		require.Error(t, err)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
+		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := svc.Export(ctx, UserServiceGetFilter{TenantID: 1}, func(UserServiceDataResult) error { return nil })
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
 // ---------------------------------------------------------------------------
 // GetByUUID
 // ---------------------------------------------------------------------------
@@ -465,9 +637,9 @@ func TestUserService_Create(t *testing.T) {
 	})
 
 	t.Run("HashPassword error", func(t *testing.T) {
-		origHash := security.HashPassword
-		defer func() { security.HashPassword = origHash }()
-		security.HashPassword = func(_ []byte) ([]byte, error) { return nil, errors.New("hash error") }
+		origHash := security.HashPasswordWithParams
+		defer func() { security.HashPasswordWithParams = origHash }()
+		security.HashPasswordWithParams = func(_ []byte, _ security.PasswordHashParams) ([]byte, error) { return nil, errors.New("hash error") }
 
 		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
 		tr.findByUUIDFn = func(_ any, _ ...string) (*model.Tenant, error) { return &model.Tenant{TenantID: 1}, nil }
@@ -551,6 +723,24 @@ func TestUserService_Update(t *testing.T) {
 		assert.Contains(t, err.Error(), "actor user has no identities")
 	})
 
+	t.Run("stale If-Match", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
+		callCount := 0
+		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) {
+			callCount++
+			if callCount == 1 {
+				return &model.User{UserID: 1, Username: "old", UserIdentities: []model.UserIdentity{{TenantID: 1, Tenant: &model.Tenant{TenantID: 1}}}}, nil
+			}
+			return userWithAccess(2, 1), nil
+		}
+		_, mock, svc := fullUserSvcWithMock(t, ur, ui, urr, rr, tr, idp, cr, up)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		_, err := svc.Update(ifMatchContext(t, `"stale"`), uid, tenantID, "old", "f", nil, nil, "active", nil, updaterUUID)
+		var want *apperror.PreconditionError
+		require.ErrorAs(t, err, &want)
+	})
+
 	t.Run("username change → FindByUsername error", func(t *testing.T) {
 		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
 		callCount := 0
@@ -760,12 +950,14 @@ func TestUserService_SetStatus(t *testing.T) {
 		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) {
 			callCount++
 			if callCount == 1 {
-				return &model.User{UserID: 1, UserIdentities: []model.UserIdentity{{TenantID: 1, Tenant: &model.Tenant{TenantID: 1}}}}, nil
+				return &model.User{UserID: 1, Status: model.StatusActive, UserIdentities: []model.UserIdentity{{TenantID: 1, Tenant: &model.Tenant{TenantID: 1}}}}, nil
 			}
 			return userWithAccess(2, 1), nil
 		}
 		ur.setStatusFn = func(_ uuid.UUID, _ string) error { return errors.New("status err") }
-		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+		_, mock, svc := fullUserSvcWithMock(t, ur, ui, urr, rr, tr, idp, cr, up)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
 		_, err := svc.SetStatus(context.Background(), uid, tenantID, "inactive", updaterUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "status err")
@@ -777,14 +969,16 @@ func TestUserService_SetStatus(t *testing.T) {
 		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) {
 			callCount++
 			if callCount == 1 {
-				return &model.User{UserID: 1, UserIdentities: []model.UserIdentity{{TenantID: 1, Tenant: &model.Tenant{TenantID: 1}}}}, nil
+				return &model.User{UserID: 1, Status: model.StatusActive, UserIdentities: []model.UserIdentity{{TenantID: 1, Tenant: &model.Tenant{TenantID: 1}}}}, nil
 			}
 			if callCount == 2 {
 				return userWithAccess(2, 1), nil
 			}
 			return nil, errors.New("fetch err")
 		}
-		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+		_, mock, svc := fullUserSvcWithMock(t, ur, ui, urr, rr, tr, idp, cr, up)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
 		_, err := svc.SetStatus(context.Background(), uid, tenantID, "inactive", updaterUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "fetch err")
@@ -796,14 +990,16 @@ func TestUserService_SetStatus(t *testing.T) {
 		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) {
 			callCount++
 			if callCount == 1 {
-				return &model.User{UserID: 1, UserIdentities: []model.UserIdentity{{TenantID: 1, Tenant: &model.Tenant{TenantID: 1}}}}, nil
+				return &model.User{UserID: 1, Status: model.StatusActive, UserIdentities: []model.UserIdentity{{TenantID: 1, Tenant: &model.Tenant{TenantID: 1}}}}, nil
 			}
 			if callCount == 2 {
 				return userWithAccess(2, 1), nil
 			}
 			return &model.User{UserUUID: uid, Status: "inactive"}, nil
 		}
-		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+		_, mock, svc := fullUserSvcWithMock(t, ur, ui, urr, rr, tr, idp, cr, up)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
 		res, err := svc.SetStatus(context.Background(), uid, tenantID, "inactive", updaterUUID)
 		require.NoError(t, err)
 		assert.NotNil(t, res)
@@ -1086,7 +1282,7 @@ func TestUserService_DeleteByUUID(t *testing.T) {
 		assert.Contains(t, err.Error(), "actor user has no identities")
 	})
 
-	t.Run("DeleteByUUID error", func(t *testing.T) {
+	t.Run("SoftDelete error", func(t *testing.T) {
 		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
 		callCount := 0
 		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) {
@@ -1096,8 +1292,10 @@ func TestUserService_DeleteByUUID(t *testing.T) {
 			}
 			return userWithAccess(2, 1), nil
 		}
-		ur.deleteByUUIDFn = func(_ any) error { return errors.New("del err") }
-		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+		ur.softDeleteFn = func(_ uuid.UUID, _ time.Time) error { return errors.New("del err") }
+		_, mock, svc := fullUserSvcWithMock(t, ur, ui, urr, rr, tr, idp, cr, up)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
 		_, err := svc.DeleteByUUID(context.Background(), uid, 1, deleterUUID)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "del err")
@@ -1113,13 +1311,150 @@ func TestUserService_DeleteByUUID(t *testing.T) {
 			}
 			return userWithAccess(2, 1), nil
 		}
-		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+		_, mock, svc := fullUserSvcWithMock(t, ur, ui, urr, rr, tr, idp, cr, up)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
 		res, err := svc.DeleteByUUID(context.Background(), uid, 1, deleterUUID)
 		require.NoError(t, err)
 		assert.NotNil(t, res)
 	})
 }
 
+func TestUserService_DeleteByUUID_AlreadyDeleted(t *testing.T) {
+	ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
+	deletedAt := time.Now()
+	callCount := 0
+	ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) {
+		callCount++
+		if callCount == 1 {
+			return &model.User{UserID: 1, DeletedAt: &deletedAt, UserIdentities: []model.UserIdentity{{TenantID: 1, Tenant: &model.Tenant{TenantID: 1}}}}, nil
+		}
+		return userWithAccess(2, 1), nil
+	}
+	_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+	_, err := svc.DeleteByUUID(context.Background(), uuid.New(), 1, uuid.New())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already deleted")
+}
+
+// ---------------------------------------------------------------------------
+// RestoreByUUID / PurgeByUUID
+// ---------------------------------------------------------------------------
+
+// deletedTargetMocks returns mocks where the first lookup is the target user
+// (soft-deleted when deleted is true) and later lookups are the acting admin.
+func deletedTargetMocks(deleted bool) (*mockUserRepo, *mockUserIdentityRepo, *mockUserRoleRepo, *mockRoleRepo, *mockTenantRepo, *mockIdentityProviderRepo, *mockClientRepo, *mockUserPoolRepo) {
+	ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
+	target := &model.User{UserID: 1, UserUUID: uuid.New(), Status: model.StatusActive, UserIdentities: []model.UserIdentity{{TenantID: 1, Tenant: &model.Tenant{TenantID: 1}}}}
+	if deleted {
+		deletedAt := time.Now()
+		target.Status = model.StatusDeleted
+		target.DeletedAt = &deletedAt
+	}
+	callCount := 0
+	ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) {
+		callCount++
+		if callCount == 1 {
+			return target, nil
+		}
+		return userWithAccess(2, 1), nil
+	}
+	return ur, ui, urr, rr, tr, idp, cr, up
+}
+
+func TestUserService_RestoreByUUID(t *testing.T) {
+	t.Run("not deleted", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := deletedTargetMocks(false)
+		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+		_, err := svc.RestoreByUUID(context.Background(), uuid.New(), 1, uuid.New())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not deleted")
+	})
+
+	t.Run("restorer not found", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := deletedTargetMocks(true)
+		inner := ur.findByUUIDFn
+		callCount := 0
+		ur.findByUUIDFn = func(id any, p ...string) (*model.User, error) {
+			callCount++
+			if callCount == 2 {
+				return nil, nil
+			}
+			return inner(id, p...)
+		}
+		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+		_, err := svc.RestoreByUUID(context.Background(), uuid.New(), 1, uuid.New())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "restorer user not found")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := deletedTargetMocks(true)
+		var restoredStatus string
+		ur.restoreFn = func(_ uuid.UUID, status string) error {
+			restoredStatus = status
+			return nil
+		}
+		var logged []AuthEventInput
+		events := &mockEventRepo{}
+		db, mock := newMockGormDB(t)
+		svc := NewUserService(db, ur, ui, urr, rr, tr, idp, cr, up, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockEmailTemplateRepo{}, events, &mockAuthEventService{
+			logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) },
+		}, nil, cache.NopInvalidator{}, NopPlanEnforcer{})
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		res, err := svc.RestoreByUUID(context.Background(), uuid.New(), 1, uuid.New())
+		require.NoError(t, err)
+		assert.NotNil(t, res)
+		assert.Equal(t, model.StatusActive, restoredStatus)
+		require.Len(t, events.created, 1)
+		assert.Equal(t, model.EventTypeUserRestored, events.created[0].EventType)
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeUserRestored, logged[0].EventType)
+		assert.Equal(t, int64(2), *logged[0].ActorUserID)
+	})
+}
+
+func TestUserService_PurgeByUUID(t *testing.T) {
+	t.Run("delete error", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := deletedTargetMocks(false)
+		ur.deleteByUUIDFn = func(_ any) error { return errors.New("del err") }
+		_, mock, svc := fullUserSvcWithMock(t, ur, ui, urr, rr, tr, idp, cr, up)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		_, err := svc.PurgeByUUID(context.Background(), uuid.New(), 1, uuid.New())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "del err")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := deletedTargetMocks(false)
+		deleted := false
+		ur.deleteByUUIDFn = func(_ any) error {
+			deleted = true
+			return nil
+		}
+		var logged []AuthEventInput
+		events := &mockEventRepo{}
+		db, mock := newMockGormDB(t)
+		svc := NewUserService(db, ur, ui, urr, rr, tr, idp, cr, up, &mockTenantSettingRepo{}, &mockSecuritySettingRepo{}, &mockUserTokenRepo{}, &mockOAuthRefreshTokenRepo{}, &mockEmailTemplateRepo{}, events, &mockAuthEventService{
+			logFn: func(_ context.Context, in AuthEventInput) { logged = append(logged, in) },
+		}, nil, cache.NopInvalidator{}, NopPlanEnforcer{})
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		_, err := svc.PurgeByUUID(context.Background(), uuid.New(), 1, uuid.New())
+		require.NoError(t, err)
+		assert.True(t, deleted)
+		require.Len(t, events.created, 1)
+		assert.Equal(t, model.EventTypeUserPurged, events.created[0].EventType)
+		assert.NotContains(t, string(events.created[0].Payload), "username", "purge events carry no personal data")
+		require.Len(t, logged, 1)
+		assert.Equal(t, model.AuthEventTypeUserDeleted, logged[0].EventType)
+	})
+}
+
 // ---------------------------------------------------------------------------
 // AssignUserRoles
 // ---------------------------------------------------------------------------
@@ -1185,7 +1520,7 @@ func TestUserService_AssignUserRoles(t *testing.T) {
 		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) {
 			return &model.User{UserID: 1, UserIdentities: []model.UserIdentity{{TenantID: 1}}}, nil
 		}
-		rr.findByUUIDFn = func(_ any, _ ...string) (*model.Role, error) { return &model.Role{RoleID: 5}, nil }
+		rr.findByUUIDFn = func(_ any, _ ...string) (*model.Role, error) { return &model.Role{RoleID: 5, TenantID: 1}, nil }
 		urr.findByUserIDAndRoleIDFn = func(_, _ int64) (*model.UserRole, error) { return nil, errors.New("ur find err") }
 		_, mock, svc := fullUserSvcWithMock(t, ur, ui, urr, rr, tr, idp, cr, up)
 		mock.ExpectBegin()
@@ -1205,7 +1540,7 @@ func TestUserService_AssignUserRoles(t *testing.T) {
 			}
 			return nil, errors.New("fetch err")
 		}
-		rr.findByUUIDFn = func(_ any, _ ...string) (*model.Role, error) { return &model.Role{RoleID: 5}, nil }
+		rr.findByUUIDFn = func(_ any, _ ...string) (*model.Role, error) { return &model.Role{RoleID: 5, TenantID: 1}, nil }
 		_, mock, svc := fullUserSvcWithMock(t, ur, ui, urr, rr, tr, idp, cr, up)
 		mock.ExpectBegin()
 		mock.ExpectRollback()
@@ -1224,7 +1559,7 @@ func TestUserService_AssignUserRoles(t *testing.T) {
 			}
 			return &model.User{UserUUID: uid}, nil
 		}
-		rr.findByUUIDFn = func(_ any, _ ...string) (*model.Role, error) { return &model.Role{RoleID: 5}, nil }
+		rr.findByUUIDFn = func(_ any, _ ...string) (*model.Role, error) { return &model.Role{RoleID: 5, TenantID: 1}, nil }
 		urr.findByUserIDAndRoleIDFn = func(_, _ int64) (*model.UserRole, error) { return &model.UserRole{}, nil }
 		_, mock, svc := fullUserSvcWithMock(t, ur, ui, urr, rr, tr, idp, cr, up)
 		mock.ExpectBegin()
@@ -1239,7 +1574,7 @@ func TestUserService_AssignUserRoles(t *testing.T) {
 		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) {
 			return &model.User{UserID: 1, UserIdentities: []model.UserIdentity{{TenantID: 1}}}, nil
 		}
-		rr.findByUUIDFn = func(_ any, _ ...string) (*model.Role, error) { return &model.Role{RoleID: 5}, nil }
+		rr.findByUUIDFn = func(_ any, _ ...string) (*model.Role, error) { return &model.Role{RoleID: 5, TenantID: 1}, nil }
 		urr.createFn = func(_ *model.UserRole) (*model.UserRole, error) { return nil, errors.New("ur create err") }
 		_, mock, svc := fullUserSvcWithMock(t, ur, ui, urr, rr, tr, idp, cr, up)
 		mock.ExpectBegin()
@@ -1259,7 +1594,67 @@ func TestUserService_AssignUserRoles(t *testing.T) {
 			}
 			return &model.User{UserUUID: uid}, nil
 		}
-		rr.findByUUIDFn = func(_ any, _ ...string) (*model.Role, error) { return &model.Role{RoleID: 5}, nil }
+		rr.findByUUIDFn = func(_ any, _ ...string) (*model.Role, error) { return &model.Role{RoleID: 5, TenantID: 1}, nil }
+		_, mock, svc := fullUserSvcWithMock(t, ur, ui, urr, rr, tr, idp, cr, up)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		res, err := svc.AssignUserRoles(context.Background(), uid, []uuid.UUID{roleUUID}, 1)
+		require.NoError(t, err)
+		assert.NotNil(t, res)
+	})
+
+	t.Run("role of another tenant", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
+		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) {
+			return &model.User{UserID: 1, UserIdentities: []model.UserIdentity{{TenantID: 1}}}, nil
+		}
+		rr.findByUUIDFn = func(_ any, _ ...string) (*model.Role, error) {
+			return &model.Role{RoleID: 5, TenantID: 2, IsInheritable: true}, nil
+		}
+		tr.findByIDFn = func(_ any, _ ...string) (*model.Tenant, error) {
+			return &model.Tenant{TenantID: 1, AncestorPath: "/"}, nil
+		}
+		_, mock, svc := fullUserSvcWithMock(t, ur, ui, urr, rr, tr, idp, cr, up)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		_, err := svc.AssignUserRoles(context.Background(), uid, []uuid.UUID{roleUUID}, 1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "access denied")
+	})
+
+	t.Run("non-inheritable role of a parent tenant", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
+		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) {
+			return &model.User{UserID: 1, UserIdentities: []model.UserIdentity{{TenantID: 1}}}, nil
+		}
+		rr.findByUUIDFn = func(_ any, _ ...string) (*model.Role, error) { return &model.Role{RoleID: 5, TenantID: 2}, nil }
+		tr.findByIDFn = func(_ any, _ ...string) (*model.Tenant, error) {
+			return &model.Tenant{TenantID: 1, AncestorPath: "/2/"}, nil
+		}
+		_, mock, svc := fullUserSvcWithMock(t, ur, ui, urr, rr, tr, idp, cr, up)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		_, err := svc.AssignUserRoles(context.Background(), uid, []uuid.UUID{roleUUID}, 1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "access denied")
+	})
+
+	t.Run("inheritable role of an ancestor tenant", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
+		callCount := 0
+		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) {
+			callCount++
+			if callCount == 1 {
+				return &model.User{UserID: 1, UserIdentities: []model.UserIdentity{{TenantID: 1}}}, nil
+			}
+			return &model.User{UserUUID: uid}, nil
+		}
+		rr.findByUUIDFn = func(_ any, _ ...string) (*model.Role, error) {
+			return &model.Role{RoleID: 5, TenantID: 2, IsInheritable: true}, nil
+		}
+		tr.findByIDFn = func(_ any, _ ...string) (*model.Tenant, error) {
+			return &model.Tenant{TenantID: 1, AncestorPath: "/2/3/"}, nil
+		}
 		_, mock, svc := fullUserSvcWithMock(t, ur, ui, urr, rr, tr, idp, cr, up)
 		mock.ExpectBegin()
 		mock.ExpectCommit()
@@ -1423,71 +1818,123 @@ func TestUserService_GetUserRoles(t *testing.T) {
 }
 
 // ---------------------------------------------------------------------------
-// GetUserIdentities
+// ListUserRoles
 // ---------------------------------------------------------------------------
 
-func TestUserService_GetUserIdentities(t *testing.T) {
+func TestUserService_ListUserRoles(t *testing.T) {
 	uid := uuid.New()
 
 	t.Run("user not found", func(t *testing.T) {
 		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
 		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) { return nil, nil }
 		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
-		_, err := svc.GetUserIdentities(context.Background(), uid)
+		_, err := svc.ListUserRoles(context.Background(), UserServiceGetRolesFilter{UserUUID: uid, TenantID: 1})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "user not found")
 	})
 
-	t.Run("FindByUserID error", func(t *testing.T) {
+	t.Run("FindPaginated error", func(t *testing.T) {
 		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
-		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) { return &model.User{UserID: 1}, nil }
-		ui.findByUserIDFn = func(_ int64) ([]model.UserIdentity, error) { return nil, errors.New("ident err") }
+		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) { return &model.User{UserID: 7}, nil }
+		rr.findPaginatedFn = func(repository.RoleRepositoryGetFilter) (*repository.PaginationResult[model.Role], error) {
+			return nil, errors.New("db err")
+		}
 		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
-		_, err := svc.GetUserIdentities(context.Background(), uid)
+		_, err := svc.ListUserRoles(context.Background(), UserServiceGetRolesFilter{UserUUID: uid, TenantID: 1})
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "ident err")
+		assert.Contains(t, err.Error(), "db err")
 	})
 
-	t.Run("success with client loaded", func(t *testing.T) {
+	t.Run("success passes the filter to the repository", func(t *testing.T) {
 		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
-		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) { return &model.User{UserID: 1}, nil }
-		ui.findByUserIDFn = func(_ int64) ([]model.UserIdentity, error) {
-			return []model.UserIdentity{{UserIdentityUUID: uuid.New(), ClientID: 5, Provider: "default"}}, nil
-		}
-		cr.findByIDFn = func(_ any, _ ...string) (*model.Client, error) {
-			return &model.Client{ClientUUID: uuid.New(), Name: "main"}, nil
+		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) { return &model.User{UserID: 7}, nil }
+		var got repository.RoleRepositoryGetFilter
+		rr.findPaginatedFn = func(f repository.RoleRepositoryGetFilter) (*repository.PaginationResult[model.Role], error) {
+			got = f
+			return &repository.PaginationResult[model.Role]{
+				Data:  []model.Role{{RoleUUID: uuid.New(), Name: "editor"}},
+				Total: 11, Page: 2, Limit: 10, TotalPages: 2,
+			}, nil
 		}
 		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
-		res, err := svc.GetUserIdentities(context.Background(), uid)
+		name, status := "edit", "active"
+		res, err := svc.ListUserRoles(context.Background(), UserServiceGetRolesFilter{
+			UserUUID: uid, TenantID: 1, Name: &name, Status: &status,
+			Page: 2, Limit: 10, SortBy: "name", SortOrder: "desc",
+		})
 		require.NoError(t, err)
-		assert.Len(t, res, 1)
-		assert.NotNil(t, res[0].Client)
+		require.NotNil(t, got.UserID)
+		assert.Equal(t, int64(7), *got.UserID)
+		assert.Equal(t, int64(1), got.TenantID)
+		assert.Equal(t, &name, got.Name)
+		assert.Equal(t, &status, got.Status)
+		assert.Equal(t, "name", got.SortBy)
+		assert.Equal(t, "desc", got.SortOrder)
+		assert.Equal(t, 2, got.Page)
+		require.Len(t, res.Data, 1)
+		assert.Equal(t, "editor", res.Data[0].Name)
+		assert.Equal(t, int64(11), res.Total)
+		assert.Equal(t, 2, res.TotalPages)
 	})
+}
 
-	t.Run("success with client ID zero", func(t *testing.T) {
+// ---------------------------------------------------------------------------
+// ListUserIdentities
+// ---------------------------------------------------------------------------
+
+func TestUserService_ListUserIdentities(t *testing.T) {
+	uid := uuid.New()
+
+	t.Run("user not found", func(t *testing.T) {
+		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
+		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) { return nil, errors.New("db err") }
+		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
+		_, err := svc.ListUserIdentities(context.Background(), UserServiceGetIdentitiesFilter{UserUUID: uid, TenantID: 1})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "user not found")
+	})
+
+	t.Run("FindPaginated error", func(t *testing.T) {
 		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
 		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) { return &model.User{UserID: 1}, nil }
-		ui.findByUserIDFn = func(_ int64) ([]model.UserIdentity, error) {
-			return []model.UserIdentity{{UserIdentityUUID: uuid.New(), ClientID: 0}}, nil
+		ui.findPaginatedFn = func(repository.UserIdentityRepositoryGetFilter) (*repository.PaginationResult[model.UserIdentity], error) {
+			return nil, errors.New("ident err")
 		}
 		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
-		res, err := svc.GetUserIdentities(context.Background(), uid)
-		require.NoError(t, err)
-		assert.Len(t, res, 1)
-		assert.Nil(t, res[0].Client)
+		_, err := svc.ListUserIdentities(context.Background(), UserServiceGetIdentitiesFilter{UserUUID: uid, TenantID: 1})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ident err")
 	})
 
-	t.Run("FindByID error → client nil", func(t *testing.T) {
+	t.Run("success maps clients", func(t *testing.T) {
 		ur, ui, urr, rr, tr, idp, cr, up := defaultMocks()
-		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) { return &model.User{UserID: 1}, nil }
-		ui.findByUserIDFn = func(_ int64) ([]model.UserIdentity, error) {
-			return []model.UserIdentity{{UserIdentityUUID: uuid.New(), ClientID: 5}}, nil
+		ur.findByUUIDFn = func(_ any, _ ...string) (*model.User, error) { return &model.User{UserID: 3}, nil }
+		var got repository.UserIdentityRepositoryGetFilter
+		ui.findPaginatedFn = func(f repository.UserIdentityRepositoryGetFilter) (*repository.PaginationResult[model.UserIdentity], error) {
+			got = f
+			return &repository.PaginationResult[model.UserIdentity]{
+				Data: []model.UserIdentity{
+					{UserIdentityUUID: uuid.New(), Provider: "google", Client: &model.Client{ClientUUID: uuid.New(), Name: "spa"}},
+					{UserIdentityUUID: uuid.New(), Provider: "default"},
+				},
+				Total: 2, Page: 1, Limit: 10, TotalPages: 1,
+			}, nil
 		}
-		cr.findByIDFn = func(_ any, _ ...string) (*model.Client, error) { return nil, errors.New("find err") }
 		_, svc := fullUserSvc(t, ur, ui, urr, rr, tr, idp, cr, up)
-		res, err := svc.GetUserIdentities(context.Background(), uid)
+		provider := "goo"
+		res, err := svc.ListUserIdentities(context.Background(), UserServiceGetIdentitiesFilter{
+			UserUUID: uid, TenantID: 1, Provider: &provider, Page: 1, Limit: 10, SortBy: "provider",
+		})
 		require.NoError(t, err)
-		assert.Nil(t, res[0].Client)
+		assert.Equal(t, int64(3), got.UserID)
+		assert.Equal(t, int64(1), got.TenantID)
+		assert.Equal(t, &provider, got.Provider)
+		assert.Equal(t, "provider", got.SortBy)
+		require.Len(t, res.Data, 2)
+		require.NotNil(t, res.Data[0].Client)
+		assert.Equal(t, "spa", res.Data[0].Client.Name)
+		assert.Nil(t, res.Data[1].Client)
+		assert.Equal(t, int64(2), res.Total)
 	})
 }
 
@@ -0,0 +1,642 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/eventbus"
+	"github.com/maintainerd/auth/internal/hook"
+	"github.com/maintainerd/auth/internal/logging"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+// Headers sent with every event delivery in addition to the signature
+// headers shared with login hooks (hook.HeaderTimestamp, hook.HeaderSignature).
+const (
+	WebhookHeaderEvent    = "X-Maintainerd-Event"
+	WebhookHeaderDelivery = "X-Maintainerd-Delivery"
+)
+
+const (
+	// webhookEnqueueBatchSize bounds how many auth events are queued per
+	// endpoint in one dispatcher run.
+	webhookEnqueueBatchSize = 100
+	// webhookDeliveryConcurrency is how many deliveries are claimed and
+	// sent at once.
+	webhookDeliveryConcurrency = 10
+	// webhookDeliveryLease hides a claimed delivery from other dispatchers
+	// while it is sent. It must outlast the longest endpoint timeout.
+	webhookDeliveryLease = 5 * time.Minute
+	// webhookRetryBaseDelay and webhookRetryMaxDelay bound the exponential
+	// backoff between attempts.
+	webhookRetryBaseDelay = 30 * time.Second
+	webhookRetryMaxDelay  = 6 * time.Hour
+	// webhookMaxErrorLength bounds the stored error of an attempt.
+	webhookMaxErrorLength = 500
+)
+
+// webhookEventEnvelope is the JSON body delivered to webhook endpoints. ID
+// identifies the delivery and stays the same across retries, so receivers can
+// use it to discard duplicates.
+type webhookEventEnvelope struct {
+	ID            uuid.UUID       `json:"id"`
+	EventID       uuid.UUID       `json:"event_id"`
+	Type          string          `json:"type"`
+	SchemaVersion int             `json:"schema_version"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// webhookAuthEventData is the data of events sourced from the auth event log.
+type webhookAuthEventData struct {
+	AuthEventType  string          `json:"auth_event_type"`
+	Category       string          `json:"category"`
+	Result         string          `json:"result"`
+	Severity       string          `json:"severity"`
+	Description    *string         `json:"description,omitempty"`
+	ErrorReason    *string         `json:"error_reason,omitempty"`
+	ActorUserUUID  *uuid.UUID      `json:"actor_user_uuid,omitempty"`
+	TargetUserUUID *uuid.UUID      `json:"target_user_uuid,omitempty"`
+	IPAddress      string          `json:"ip_address"`
+	UserAgent      *string         `json:"user_agent,omitempty"`
+	Metadata       json.RawMessage `json:"metadata,omitempty"`
+}
+
+// WebhookDeliveryServiceDataResult is the service-layer representation of a
+// webhook_deliveries record.
+type WebhookDeliveryServiceDataResult struct {
+	WebhookDeliveryUUID uuid.UUID
+	EventUUID           uuid.UUID
+	EventType           string
+	Payload             json.RawMessage
+	Status              string
+	Attempts            int
+	NextAttemptAt       *time.Time
+	LastAttemptAt       *time.Time
+	LastStatusCode      *int
+	LastError           *string
+	LastDurationMs      *int64
+	DeliveredAt         *time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// WebhookDeliveryServiceListResult holds a paginated list of deliveries.
+type WebhookDeliveryServiceListResult struct {
+	Data       []WebhookDeliveryServiceDataResult
+	Total      int64
+	Page       int
+	Limit      int
+	TotalPages int
+}
+
+// WebhookDeliveryService queues lifecycle and auth events for the webhook
+// endpoints subscribed to them, delivers them with signed requests and
+// retries, and exposes the delivery log. Lifecycle events arrive from the
+// event bus through HandleEvent; auth events are read by Enqueue.
+type WebhookDeliveryService interface {
+	GetAll(ctx context.Context, tenantID int64, webhookEndpointUUID uuid.UUID, status, eventType []string, page, limit int) (*WebhookDeliveryServiceListResult, error)
+	GetByUUID(ctx context.Context, tenantID int64, webhookEndpointUUID, webhookDeliveryUUID uuid.UUID) (*WebhookDeliveryServiceDataResult, error)
+	HandleEvent(ctx context.Context, e eventbus.Event) error
+	Enqueue(ctx context.Context) (int64, error)
+	DeliverDue(ctx context.Context) (int64, error)
+}
+
+type webhookDeliveryService struct {
+	db                  *gorm.DB
+	webhookEndpointRepo repository.WebhookEndpointRepository
+	webhookDeliveryRepo repository.WebhookDeliveryRepository
+	authEventRepo       repository.AuthEventRepository
+	httpClient          *http.Client
+}
+
+// NewWebhookDeliveryService creates a new WebhookDeliveryService.
+func NewWebhookDeliveryService(
+	db *gorm.DB,
+	webhookEndpointRepo repository.WebhookEndpointRepository,
+	webhookDeliveryRepo repository.WebhookDeliveryRepository,
+	authEventRepo repository.AuthEventRepository,
+) WebhookDeliveryService {
+	return &webhookDeliveryService{
+		db:                  db,
+		webhookEndpointRepo: webhookEndpointRepo,
+		webhookDeliveryRepo: webhookDeliveryRepo,
+		authEventRepo:       authEventRepo,
+		httpClient:          &http.Client{},
+	}
+}
+
+func toWebhookDeliveryServiceDataResult(d *model.WebhookDelivery) WebhookDeliveryServiceDataResult {
+	return WebhookDeliveryServiceDataResult{
+		WebhookDeliveryUUID: d.WebhookDeliveryUUID,
+		EventUUID:           d.EventUUID,
+		EventType:           d.EventType,
+		Payload:             json.RawMessage(d.Payload),
+		Status:              d.Status,
+		Attempts:            d.Attempts,
+		NextAttemptAt:       d.NextAttemptAt,
+		LastAttemptAt:       d.LastAttemptAt,
+		LastStatusCode:      d.LastStatusCode,
+		LastError:           d.LastError,
+		LastDurationMs:      d.LastDurationMs,
+		DeliveredAt:         d.DeliveredAt,
+		CreatedAt:           d.CreatedAt,
+		UpdatedAt:           d.UpdatedAt,
+	}
+}
+
+// GetAll retrieves the deliveries of a webhook endpoint, newest first.
+func (s *webhookDeliveryService) GetAll(ctx context.Context, tenantID int64, webhookEndpointUUID uuid.UUID, status, eventType []string, page, limit int) (*WebhookDeliveryServiceListResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "webhookDelivery.list")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("webhook.uuid", webhookEndpointUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	ep, err := s.findEndpoint(tenantID, webhookEndpointUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find webhook endpoint failed")
+		return nil, err
+	}
+
+	result, err := s.webhookDeliveryRepo.FindPaginated(repository.WebhookDeliveryRepositoryGetFilter{
+		TenantID:          tenantID,
+		WebhookEndpointID: ep.WebhookEndpointID,
+		Status:            status,
+		EventType:         eventType,
+		Page:              page,
+		Limit:             limit,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list webhook deliveries failed")
+		return nil, err
+	}
+
+	data := make([]WebhookDeliveryServiceDataResult, len(result.Data))
+	for i := range result.Data {
+		data[i] = toWebhookDeliveryServiceDataResult(&result.Data[i])
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return &WebhookDeliveryServiceListResult{
+		Data:       data,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: result.TotalPages,
+	}, nil
+}
+
+// GetByUUID retrieves a single delivery of a webhook endpoint.
+func (s *webhookDeliveryService) GetByUUID(ctx context.Context, tenantID int64, webhookEndpointUUID, webhookDeliveryUUID uuid.UUID) (*WebhookDeliveryServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "webhookDelivery.get")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("webhook.uuid", webhookEndpointUUID.String()),
+		attribute.String("webhook.delivery_uuid", webhookDeliveryUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+	)
+
+	ep, err := s.findEndpoint(tenantID, webhookEndpointUUID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find webhook endpoint failed")
+		return nil, err
+	}
+
+	d, err := s.webhookDeliveryRepo.FindByUUIDAndEndpointID(webhookDeliveryUUID, ep.WebhookEndpointID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get webhook delivery failed")
+		return nil, err
+	}
+	if d == nil {
+		span.SetStatus(codes.Error, "webhook delivery not found")
+		return nil, apperror.NewNotFoundWithReason("webhook delivery not found")
+	}
+
+	span.SetStatus(codes.Ok, "")
+	result := toWebhookDeliveryServiceDataResult(d)
+	return &result, nil
+}
+
+func (s *webhookDeliveryService) findEndpoint(tenantID int64, webhookEndpointUUID uuid.UUID) (*model.WebhookEndpoint, error) {
+	ep, err := s.webhookEndpointRepo.FindByUUIDAndTenantID(webhookEndpointUUID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if ep == nil {
+		return nil, apperror.NewNotFoundWithReason("webhook endpoint not found")
+	}
+	return ep, nil
+}
+
+// HandleEvent queues a lifecycle event published on the event bus for every
+// active endpoint of its tenant that subscribes to it. Endpoints only receive
+// events recorded after they subscribed. A redelivered event is not queued
+// twice for the same endpoint.
+func (s *webhookDeliveryService) HandleEvent(ctx context.Context, e eventbus.Event) error {
+	_, span := otel.Tracer("service").Start(ctx, "webhookDelivery.handleEvent")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int64("tenant.id", e.TenantID),
+		attribute.String("event.type", e.Type),
+	)
+
+	endpoints, err := s.webhookEndpointRepo.FindByTenantID(e.TenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list webhook endpoints failed")
+		return apperror.NewInternal("failed to list webhook endpoints", err)
+	}
+
+	now := time.Now()
+	var deliveries []model.WebhookDelivery
+	for i := range endpoints {
+		ep := &endpoints[i]
+		if ep.Status != model.StatusActive || e.Sequence <= ep.LastEventID {
+			continue
+		}
+		lifecycleTypes, _, all := webhookSubscription(ep)
+		if !all && !slices.Contains(lifecycleTypes, e.Type) {
+			continue
+		}
+		d, err := newLifecycleWebhookDelivery(ep, e, now)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "build webhook delivery failed")
+			return apperror.NewInternal("failed to queue webhook deliveries", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if err := s.webhookDeliveryRepo.CreateBatch(deliveries); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "queue webhook deliveries failed")
+		return apperror.NewInternal("failed to queue webhook deliveries", err)
+	}
+
+	span.SetAttributes(attribute.Int("webhook.queued", len(deliveries)))
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// Enqueue reads the auth events each active endpoint subscribes to, past the
+// endpoint's stored position, and queues one delivery per event. Events
+// younger than the event feed settle window are left for the next run so
+// that late commits are not skipped.
+func (s *webhookDeliveryService) Enqueue(ctx context.Context) (int64, error) {
+	_, span := otel.Tracer("service").Start(ctx, "webhookDelivery.enqueue")
+	defer span.End()
+
+	endpoints, err := s.webhookEndpointRepo.FindActive()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list webhook endpoints failed")
+		return 0, apperror.NewInternal("failed to list webhook endpoints", err)
+	}
+
+	before := time.Now().Add(-eventFeedSettleWindow)
+	var queued int64
+	for i := range endpoints {
+		n, err := s.enqueueEndpoint(&endpoints[i], before)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "queue webhook deliveries failed")
+			return queued, apperror.NewInternal("failed to queue webhook deliveries", err)
+		}
+		queued += n
+	}
+
+	span.SetAttributes(attribute.Int64("webhook.queued", queued))
+	span.SetStatus(codes.Ok, "")
+	return queued, nil
+}
+
+func (s *webhookDeliveryService) enqueueEndpoint(ep *model.WebhookEndpoint, before time.Time) (int64, error) {
+	_, authTypes, _ := webhookSubscription(ep)
+	if len(authTypes) == 0 {
+		return 0, nil
+	}
+
+	events, err := s.authEventRepo.FindFeed(repository.AuthEventRepositoryFeedFilter{
+		TenantID: ep.TenantID,
+		AfterID:  ep.LastAuthEventID,
+		Types:    authTypes,
+		Before:   &before,
+		Limit:    webhookEnqueueBatchSize,
+	})
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	now := time.Now()
+	deliveries := make([]model.WebhookDelivery, 0, len(events))
+	for i := range events {
+		d, err := newAuthWebhookDelivery(ep, &events[i], now)
+		if err != nil {
+			return 0, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	lastAuthEventID := events[len(events)-1].AuthEventID
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.webhookDeliveryRepo.WithTx(tx).CreateBatch(deliveries); err != nil {
+			return err
+		}
+		return s.webhookEndpointRepo.WithTx(tx).UpdateCursors(ep.WebhookEndpointID, ep.LastEventID, lastAuthEventID)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(deliveries)), nil
+}
+
+// webhookSubscription resolves an endpoint's subscribed event names into the
+// lifecycle and auth event types to read. all is set when the endpoint
+// subscribes to every event, in which case lifecycleTypes is nil.
+func webhookSubscription(ep *model.WebhookEndpoint) (lifecycleTypes, authTypes []string, all bool) {
+	var names []string
+	_ = json.Unmarshal(ep.Events, &names)
+
+	for _, name := range names {
+		if name == model.WebhookEventAll {
+			all = true
+		}
+	}
+	if all {
+		for _, types := range model.WebhookAuthEventTypes {
+			authTypes = append(authTypes, types...)
+		}
+		return nil, authTypes, true
+	}
+
+	for _, name := range names {
+		if types, ok := model.WebhookAuthEventTypes[name]; ok {
+			authTypes = append(authTypes, types...)
+			continue
+		}
+		lifecycleTypes = append(lifecycleTypes, name)
+	}
+	return lifecycleTypes, authTypes, false
+}
+
+// webhookAuthEventName returns the webhook event name an auth event type is
+// delivered under.
+func webhookAuthEventName(authEventType string) string {
+	for name, types := range model.WebhookAuthEventTypes {
+		for _, t := range types {
+			if t == authEventType {
+				return name
+			}
+		}
+	}
+	return authEventType
+}
+
+func newLifecycleWebhookDelivery(ep *model.WebhookEndpoint, e eventbus.Event, now time.Time) (model.WebhookDelivery, error) {
+	data := e.Payload
+	if len(data) == 0 {
+		data = json.RawMessage(`{}`)
+	}
+	return newWebhookDelivery(ep, webhookEventEnvelope{
+		EventID:       e.ID,
+		Type:          e.Type,
+		SchemaVersion: e.SchemaVersion,
+		OccurredAt:    e.OccurredAt,
+		Data:          data,
+	}, now)
+}
+
+func newAuthWebhookDelivery(ep *model.WebhookEndpoint, e *model.AuthEvent, now time.Time) (model.WebhookDelivery, error) {
+	data := webhookAuthEventData{
+		AuthEventType: e.EventType,
+		Category:      e.Category,
+		Result:        e.Result,
+		Severity:      e.Severity,
+		Description:   e.Description,
+		ErrorReason:   e.ErrorReason,
+		IPAddress:     e.IPAddress,
+		UserAgent:     e.UserAgent,
+	}
+	if len(e.Metadata) > 0 {
+		data.Metadata = json.RawMessage(e.Metadata)
+	}
+	if e.ActorUser != nil {
+		data.ActorUserUUID = &e.ActorUser.UserUUID
+	}
+	if e.TargetUser != nil {
+		data.TargetUserUUID = &e.TargetUser.UserUUID
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	return newWebhookDelivery(ep, webhookEventEnvelope{
+		EventID:       e.AuthEventUUID,
+		Type:          webhookAuthEventName(e.EventType),
+		SchemaVersion: model.EventSchemaVersion,
+		OccurredAt:    e.CreatedAt,
+		Data:          raw,
+	}, now)
+}
+
+func newWebhookDelivery(ep *model.WebhookEndpoint, envelope webhookEventEnvelope, now time.Time) (model.WebhookDelivery, error) {
+	envelope.ID = uuid.New()
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	return model.WebhookDelivery{
+		WebhookDeliveryUUID: envelope.ID,
+		TenantID:            ep.TenantID,
+		WebhookEndpointID:   ep.WebhookEndpointID,
+		EventUUID:           envelope.EventID,
+		EventType:           envelope.Type,
+		Payload:             payload,
+		Status:              model.WebhookDeliveryStatusPending,
+		NextAttemptAt:       &now,
+	}, nil
+}
+
+// DeliverDue sends every pending delivery whose next attempt is due, a few
+// at a time, and returns how many attempts were made. Failed attempts are
+// retried with exponential backoff until the endpoint's max_retries is
+// exhausted.
+func (s *webhookDeliveryService) DeliverDue(ctx context.Context) (int64, error) {
+	ctx, span := otel.Tracer("service").Start(ctx, "webhookDelivery.deliverDue")
+	defer span.End()
+
+	var attempted int64
+	for ctx.Err() == nil {
+		deliveries, err := s.webhookDeliveryRepo.ClaimDue(time.Now(), webhookDeliveryLease, webhookDeliveryConcurrency)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "claim webhook deliveries failed")
+			return attempted, apperror.NewInternal("failed to claim webhook deliveries", err)
+		}
+
+		var wg sync.WaitGroup
+		for i := range deliveries {
+			wg.Add(1)
+			go func(d *model.WebhookDelivery) {
+				defer wg.Done()
+				s.attempt(ctx, d)
+			}(&deliveries[i])
+		}
+		wg.Wait()
+		attempted += int64(len(deliveries))
+
+		if len(deliveries) < webhookDeliveryConcurrency {
+			break
+		}
+	}
+
+	span.SetAttributes(attribute.Int64("webhook.attempted", attempted))
+	span.SetStatus(codes.Ok, "")
+	return attempted, nil
+}
+
+// attempt sends one delivery and records the outcome. Failures to record
+// are logged; the delivery is then retried once its lease expires.
+func (s *webhookDeliveryService) attempt(ctx context.Context, d *model.WebhookDelivery) {
+	logger := logging.Logger(logging.ComponentWebhooks)
+	ep := d.WebhookEndpoint
+	now := time.Now()
+
+	updates := map[string]any{
+		"attempts":        d.Attempts + 1,
+		"last_attempt_at": now,
+	}
+
+	inactive := ep == nil || ep.Status != model.StatusActive
+	var sendErr error
+	if inactive {
+		sendErr = fmt.Errorf("webhook endpoint is inactive")
+		updates["last_status_code"] = nil
+		updates["last_duration_ms"] = nil
+	} else {
+		start := time.Now()
+		statusCode, err := s.send(ctx, ep, d)
+		sendErr = err
+		updates["last_duration_ms"] = time.Since(start).Milliseconds()
+		updates["last_status_code"] = nil
+		if statusCode > 0 {
+			updates["last_status_code"] = statusCode
+		}
+		if err := s.webhookEndpointRepo.MarkTriggered(ep.WebhookEndpointID, now); err != nil {
+			logger.WarnContext(ctx, "record webhook trigger failed", "webhook_endpoint_id", ep.WebhookEndpointID, "error", err)
+		}
+	}
+
+	attrs := []any{"delivery", d.WebhookDeliveryUUID.String(), "event_type", d.EventType, "attempt", d.Attempts + 1}
+	switch {
+	case sendErr == nil:
+		updates["status"] = model.WebhookDeliveryStatusSucceeded
+		updates["delivered_at"] = now
+		updates["next_attempt_at"] = nil
+		updates["last_error"] = nil
+		logger.DebugContext(ctx, "webhook delivered", attrs...)
+	case inactive || d.Attempts >= ep.MaxRetries:
+		updates["status"] = model.WebhookDeliveryStatusFailed
+		updates["next_attempt_at"] = nil
+		updates["last_error"] = truncateWebhookError(sendErr)
+		logger.WarnContext(ctx, "webhook delivery failed permanently", append(attrs, "error", sendErr)...)
+	default:
+		updates["next_attempt_at"] = now.Add(webhookRetryDelay(d.Attempts + 1))
+		updates["last_error"] = truncateWebhookError(sendErr)
+		logger.WarnContext(ctx, "webhook delivery failed, will retry", append(attrs, "error", sendErr)...)
+	}
+
+	if _, err := s.webhookDeliveryRepo.UpdateByID(d.WebhookDeliveryID, updates); err != nil {
+		logger.ErrorContext(ctx, "record webhook delivery failed", append(attrs, "error", err)...)
+	}
+}
+
+// send POSTs the delivery payload to the endpoint. The body is signed like
+// login hook calls when the endpoint has a secret. It returns the response
+// status code, or 0 when no response was received.
+func (s *webhookDeliveryService) send(ctx context.Context, ep *model.WebhookEndpoint, d *model.WebhookDelivery) (int, error) {
+	timeout := time.Duration(ep.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("build webhook request: %w", err)
+	}
+	now := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "maintainerd-auth")
+	req.Header.Set(hook.HeaderTimestamp, strconv.FormatInt(now, 10))
+	req.Header.Set(WebhookHeaderEvent, d.EventType)
+	req.Header.Set(WebhookHeaderDelivery, d.WebhookDeliveryUUID.String())
+	if secrets := webhookSigningSecrets(ep, time.Unix(now, 0)); len(secrets) > 0 {
+		req.Header.Set(hook.HeaderSignature, hook.SignAll(secrets, now, d.Payload))
+	}
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("webhook request failed: unexpected status %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
+
+// webhookSigningSecrets returns the secrets a delivery is signed with: the
+// current secret and, during a rotation's grace period, the previous one.
+func webhookSigningSecrets(ep *model.WebhookEndpoint, now time.Time) []string {
+	var secrets []string
+	if ep.SecretEncrypted != "" {
+		secrets = append(secrets, ep.SecretEncrypted)
+	}
+	if ep.PreviousSecretEncrypted != "" && ep.PreviousSecretExpiresAt != nil && ep.PreviousSecretExpiresAt.After(now) {
+		secrets = append(secrets, ep.PreviousSecretEncrypted)
+	}
+	return secrets
+}
+
+// webhookRetryDelay returns the wait before the attempt after the given
+// one: 30s, 1m, 2m, 4m… capped at six hours.
+func webhookRetryDelay(attempt int) time.Duration {
+	delay := webhookRetryBaseDelay
+	for i := 1; i < attempt && delay < webhookRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookRetryMaxDelay)
+}
+
+func truncateWebhookError(err error) string {
+	msg := err.Error()
+	if len(msg) > webhookMaxErrorLength {
+		msg = msg[:webhookMaxErrorLength]
+	}
+	return msg
+}
@@ -0,0 +1,541 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/eventbus"
+	"github.com/maintainerd/auth/internal/hook"
+	"github.com/maintainerd/auth/internal/model"
+	"github.com/maintainerd/auth/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func newWebhookDeliverySvc(t *testing.T, endpointRepo *mockWebhookEndpointRepo, deliveryRepo *mockWebhookDeliveryRepo, authEventRepo *mockAuthEventRepo) (*webhookDeliveryService, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock := newMockGormDB(t)
+	svc := NewWebhookDeliveryService(db, endpointRepo, deliveryRepo, authEventRepo).(*webhookDeliveryService)
+	return svc, mock
+}
+
+func webhookEndpointWithEvents(events ...string) model.WebhookEndpoint {
+	raw, _ := json.Marshal(events)
+	return model.WebhookEndpoint{
+		WebhookEndpointID:   5,
+		WebhookEndpointUUID: uuid.New(),
+		TenantID:            1,
+		URL:                 "https://example.com/hook",
+		Events:              datatypes.JSON(raw),
+		MaxRetries:          2,
+		TimeoutSeconds:      5,
+		Status:              model.StatusActive,
+		LastEventID:         10,
+		LastAuthEventID:     20,
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Subscriptions
+// ---------------------------------------------------------------------------
+
+func TestWebhookSubscription(t *testing.T) {
+	t.Run("splits lifecycle and auth events", func(t *testing.T) {
+		ep := webhookEndpointWithEvents("user.created", "login.failed")
+		lifecycle, auth, all := webhookSubscription(&ep)
+		assert.False(t, all)
+		assert.Equal(t, []string{"user.created"}, lifecycle)
+		assert.ElementsMatch(t, []string{model.AuthEventTypeLoginFail, model.AuthEventTypeLoginFailMax}, auth)
+	})
+
+	t.Run("wildcard subscribes to everything", func(t *testing.T) {
+		ep := webhookEndpointWithEvents("*")
+		lifecycle, auth, all := webhookSubscription(&ep)
+		assert.True(t, all)
+		assert.Nil(t, lifecycle)
+		assert.Contains(t, auth, model.AuthEventTypeTokenRevoked)
+	})
+}
+
+func TestWebhookAuthEventName(t *testing.T) {
+	assert.Equal(t, "login.failed", webhookAuthEventName(model.AuthEventTypeLoginFailMax))
+	assert.Equal(t, "token.revoked", webhookAuthEventName(model.AuthEventTypeOAuthTokenRevoke))
+	assert.Equal(t, "sys_startup", webhookAuthEventName(model.AuthEventTypeSystemStartup))
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, webhookRetryDelay(1))
+	assert.Equal(t, time.Minute, webhookRetryDelay(2))
+	assert.Equal(t, 4*time.Minute, webhookRetryDelay(4))
+	assert.Equal(t, 6*time.Hour, webhookRetryDelay(30))
+}
+
+// ---------------------------------------------------------------------------
+// HandleEvent
+// ---------------------------------------------------------------------------
+
+func TestWebhookDeliveryService_HandleEvent(t *testing.T) {
+	event := eventbus.Event{
+		ID: uuid.New(), Sequence: 11, TenantID: 1, Type: model.EventTypeUserCreated,
+		SchemaVersion: 1, Payload: json.RawMessage(`{"user_uuid":"u1"}`), OccurredAt: time.Now(),
+	}
+
+	t.Run("queues event for subscribed endpoints", func(t *testing.T) {
+		subscribed := webhookEndpointWithEvents("user.created")
+		wildcard := webhookEndpointWithEvents("*")
+		wildcard.WebhookEndpointID = 6
+		other := webhookEndpointWithEvents("role.created")
+		inactive := webhookEndpointWithEvents("user.created")
+		inactive.Status = model.StatusInactive
+		late := webhookEndpointWithEvents("user.created")
+		late.LastEventID = 11
+		var queued []model.WebhookDelivery
+
+		svc, _ := newWebhookDeliverySvc(t,
+			&mockWebhookEndpointRepo{findByTenantIDFn: func(tenantID int64) ([]model.WebhookEndpoint, error) {
+				assert.Equal(t, int64(1), tenantID)
+				return []model.WebhookEndpoint{subscribed, wildcard, other, inactive, late}, nil
+			}},
+			&mockWebhookDeliveryRepo{createBatchFn: func(d []model.WebhookDelivery) error {
+				queued = d
+				return nil
+			}},
+			&mockAuthEventRepo{},
+		)
+
+		require.NoError(t, svc.HandleEvent(context.Background(), event))
+		require.Len(t, queued, 2, "only active endpoints subscribed before the event")
+		assert.Equal(t, int64(5), queued[0].WebhookEndpointID)
+		assert.Equal(t, int64(6), queued[1].WebhookEndpointID)
+		assert.Equal(t, event.ID, queued[0].EventUUID)
+		assert.Equal(t, model.WebhookDeliveryStatusPending, queued[0].Status)
+		assert.NotNil(t, queued[0].NextAttemptAt)
+
+		var envelope map[string]any
+		require.NoError(t, json.Unmarshal(queued[0].Payload, &envelope))
+		assert.Equal(t, "user.created", envelope["type"])
+		assert.Equal(t, event.ID.String(), envelope["event_id"])
+		assert.Equal(t, queued[0].WebhookDeliveryUUID.String(), envelope["id"], "the envelope id is the delivery id")
+		assert.Equal(t, map[string]any{"user_uuid": "u1"}, envelope["data"])
+	})
+
+	t.Run("endpoint error", func(t *testing.T) {
+		svc, _ := newWebhookDeliverySvc(t,
+			&mockWebhookEndpointRepo{findByTenantIDFn: func(int64) ([]model.WebhookEndpoint, error) {
+				return nil, errors.New("db down")
+			}},
+			&mockWebhookDeliveryRepo{}, &mockAuthEventRepo{},
+		)
+
+		err := svc.HandleEvent(context.Background(), event)
+		var internalErr *apperror.InternalError
+		assert.ErrorAs(t, err, &internalErr)
+	})
+
+	t.Run("queue error", func(t *testing.T) {
+		svc, _ := newWebhookDeliverySvc(t,
+			&mockWebhookEndpointRepo{findByTenantIDFn: func(int64) ([]model.WebhookEndpoint, error) {
+				return []model.WebhookEndpoint{webhookEndpointWithEvents("*")}, nil
+			}},
+			&mockWebhookDeliveryRepo{createBatchFn: func([]model.WebhookDelivery) error { return errors.New("insert failed") }},
+			&mockAuthEventRepo{},
+		)
+
+		require.Error(t, svc.HandleEvent(context.Background(), event))
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Enqueue
+// ---------------------------------------------------------------------------
+
+func TestWebhookDeliveryService_Enqueue(t *testing.T) {
+	t.Run("queues subscribed auth events and advances position", func(t *testing.T) {
+		ep := webhookEndpointWithEvents("user.created", "login.failed")
+		userUUID := uuid.New()
+		var authFilter repository.AuthEventRepositoryFeedFilter
+		var queued []model.WebhookDelivery
+		var cursors [3]int64
+
+		svc, mock := newWebhookDeliverySvc(t,
+			&mockWebhookEndpointRepo{
+				findActiveFn: func() ([]model.WebhookEndpoint, error) { return []model.WebhookEndpoint{ep}, nil },
+				updateCursorsFn: func(id, lastEventID, lastAuthEventID int64) error {
+					cursors = [3]int64{id, lastEventID, lastAuthEventID}
+					return nil
+				},
+			},
+			&mockWebhookDeliveryRepo{createBatchFn: func(d []model.WebhookDelivery) error {
+				queued = d
+				return nil
+			}},
+			&mockAuthEventRepo{findFeedFn: func(f repository.AuthEventRepositoryFeedFilter) ([]model.AuthEvent, error) {
+				authFilter = f
+				return []model.AuthEvent{{
+					AuthEventID: 21, AuthEventUUID: uuid.New(), TenantID: 1, EventType: model.AuthEventTypeLoginFail,
+					Category: model.AuthEventCategoryAuthn, Result: model.AuthEventResultFailure, IPAddress: "203.0.113.7",
+					TargetUser: &model.User{UserUUID: userUUID}, CreatedAt: time.Now(),
+				}}, nil
+			}},
+		)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		n, err := svc.Enqueue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+
+		assert.Equal(t, int64(20), authFilter.AfterID)
+		require.NotNil(t, authFilter.Before)
+		assert.Equal(t, [3]int64{5, 10, 21}, cursors, "the lifecycle subscription start is kept")
+
+		require.Len(t, queued, 1)
+		assert.Equal(t, "login.failed", queued[0].EventType)
+		assert.Equal(t, model.WebhookDeliveryStatusPending, queued[0].Status)
+
+		var envelope map[string]any
+		require.NoError(t, json.Unmarshal(queued[0].Payload, &envelope))
+		assert.Equal(t, "login.failed", envelope["type"])
+		assert.Equal(t, queued[0].WebhookDeliveryUUID.String(), envelope["id"], "the envelope id is the delivery id")
+		data := envelope["data"].(map[string]any)
+		assert.Equal(t, model.AuthEventTypeLoginFail, data["auth_event_type"])
+		assert.Equal(t, userUUID.String(), data["target_user_uuid"])
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("skips endpoints without auth events and idle endpoints", func(t *testing.T) {
+		lifecycleOnly := webhookEndpointWithEvents("user.created")
+		idle := webhookEndpointWithEvents("login.failed")
+		svc, _ := newWebhookDeliverySvc(t,
+			&mockWebhookEndpointRepo{
+				findActiveFn: func() ([]model.WebhookEndpoint, error) {
+					return []model.WebhookEndpoint{lifecycleOnly, idle}, nil
+				},
+				updateCursorsFn: func(int64, int64, int64) error {
+					t.Fatal("positions must not move without events")
+					return nil
+				},
+			},
+			&mockWebhookDeliveryRepo{},
+			&mockAuthEventRepo{findFeedFn: func(f repository.AuthEventRepositoryFeedFilter) ([]model.AuthEvent, error) {
+				assert.NotEmpty(t, f.Types, "auth events are only read for endpoints subscribing to them")
+				return nil, nil
+			}},
+		)
+
+		n, err := svc.Enqueue(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("feed error", func(t *testing.T) {
+		ep := webhookEndpointWithEvents("login.failed")
+		svc, _ := newWebhookDeliverySvc(t,
+			&mockWebhookEndpointRepo{findActiveFn: func() ([]model.WebhookEndpoint, error) { return []model.WebhookEndpoint{ep}, nil }},
+			&mockWebhookDeliveryRepo{},
+			&mockAuthEventRepo{findFeedFn: func(repository.AuthEventRepositoryFeedFilter) ([]model.AuthEvent, error) {
+				return nil, errors.New("db down")
+			}},
+		)
+
+		_, err := svc.Enqueue(context.Background())
+		require.Error(t, err)
+		var internalErr *apperror.InternalError
+		assert.ErrorAs(t, err, &internalErr)
+	})
+}
+
+func TestWebhookSigningSecrets(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+	tests := []struct {
+		name string
+		ep   model.WebhookEndpoint
+		want []string
+	}{
+		{"no secret", model.WebhookEndpoint{}, nil},
+		{"current only", model.WebhookEndpoint{SecretEncrypted: "new"}, []string{"new"}},
+		{"previous in grace", model.WebhookEndpoint{SecretEncrypted: "new", PreviousSecretEncrypted: "old", PreviousSecretExpiresAt: &future}, []string{"new", "old"}},
+		{"previous expired", model.WebhookEndpoint{SecretEncrypted: "new", PreviousSecretEncrypted: "old", PreviousSecretExpiresAt: &past}, []string{"new"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, webhookSigningSecrets(&tt.ep, now))
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// DeliverDue
+// ---------------------------------------------------------------------------
+
+func TestWebhookDeliveryService_DeliverDue(t *testing.T) {
+	claimOnce := func(d model.WebhookDelivery) func(time.Time, time.Duration, int) ([]model.WebhookDelivery, error) {
+		claimed := false
+		return func(time.Time, time.Duration, int) ([]model.WebhookDelivery, error) {
+			if claimed {
+				return nil, nil
+			}
+			claimed = true
+			return []model.WebhookDelivery{d}, nil
+		}
+	}
+	newDelivery := func(ep model.WebhookEndpoint, attempts int) model.WebhookDelivery {
+		return model.WebhookDelivery{
+			WebhookDeliveryID:   9,
+			WebhookDeliveryUUID: uuid.New(),
+			WebhookEndpointID:   ep.WebhookEndpointID,
+			EventType:           model.EventTypeUserCreated,
+			Payload:             datatypes.JSON(`{"type":"user.created"}`),
+			Status:              model.WebhookDeliveryStatusPending,
+			Attempts:            attempts,
+			WebhookEndpoint:     &ep,
+		}
+	}
+
+	t.Run("signs with previous secret during grace period", func(t *testing.T) {
+		ep := webhookEndpointWithEvents("user.created")
+		ep.SecretEncrypted = "new"
+		ep.PreviousSecretEncrypted = "old"
+		expiresAt := time.Now().Add(time.Hour)
+		ep.PreviousSecretExpiresAt = &expiresAt
+		d := newDelivery(ep, 0)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			ts, sig := r.Header.Get(hook.HeaderTimestamp), r.Header.Get(hook.HeaderSignature)
+			assert.NoError(t, hook.Verify("new", ts, sig, body, hook.DefaultSignatureTolerance, time.Now()))
+			assert.NoError(t, hook.Verify("old", ts, sig, body, hook.DefaultSignatureTolerance, time.Now()))
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+		ep.URL = srv.URL
+		d.WebhookEndpoint = &ep
+
+		svc, _ := newWebhookDeliverySvc(t, &mockWebhookEndpointRepo{},
+			&mockWebhookDeliveryRepo{
+				claimDueFn:   claimOnce(d),
+				updateByIDFn: func(_, _ any) (*model.WebhookDelivery, error) { return nil, nil },
+			},
+			&mockAuthEventRepo{},
+		)
+
+		n, err := svc.DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+	})
+
+	t.Run("delivers signed payload", func(t *testing.T) {
+		ep := webhookEndpointWithEvents("user.created")
+		ep.SecretEncrypted = "s3cret"
+		d := newDelivery(ep, 0)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			ts, err := strconv.ParseInt(r.Header.Get(hook.HeaderTimestamp), 10, 64)
+			require.NoError(t, err)
+			assert.Equal(t, hook.Sign("s3cret", ts, body), r.Header.Get(hook.HeaderSignature))
+			assert.Equal(t, model.EventTypeUserCreated, r.Header.Get(WebhookHeaderEvent))
+			assert.Equal(t, d.WebhookDeliveryUUID.String(), r.Header.Get(WebhookHeaderDelivery))
+			assert.JSONEq(t, `{"type":"user.created"}`, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+		ep.URL = srv.URL
+		d.WebhookEndpoint = &ep
+
+		var updates map[string]any
+		endpointRepo := &mockWebhookEndpointRepo{}
+		svc, _ := newWebhookDeliverySvc(t, endpointRepo,
+			&mockWebhookDeliveryRepo{
+				claimDueFn: claimOnce(d),
+				updateByIDFn: func(_, data any) (*model.WebhookDelivery, error) {
+					updates = data.(map[string]any)
+					return nil, nil
+				},
+			},
+			&mockAuthEventRepo{},
+		)
+
+		n, err := svc.DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+		assert.Equal(t, model.WebhookDeliveryStatusSucceeded, updates["status"])
+		assert.Equal(t, 1, updates["attempts"])
+		assert.Equal(t, http.StatusNoContent, updates["last_status_code"])
+		assert.Nil(t, updates["next_attempt_at"])
+		assert.Equal(t, []int64{ep.WebhookEndpointID}, endpointRepo.triggered)
+	})
+
+	t.Run("schedules retry with backoff", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer srv.Close()
+		ep := webhookEndpointWithEvents("user.created")
+		ep.URL = srv.URL
+
+		var updates map[string]any
+		svc, _ := newWebhookDeliverySvc(t, &mockWebhookEndpointRepo{},
+			&mockWebhookDeliveryRepo{
+				claimDueFn: claimOnce(newDelivery(ep, 1)),
+				updateByIDFn: func(_, data any) (*model.WebhookDelivery, error) {
+					updates = data.(map[string]any)
+					return nil, nil
+				},
+			},
+			&mockAuthEventRepo{},
+		)
+
+		start := time.Now()
+		_, err := svc.DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.NotContains(t, updates, "status")
+		assert.Equal(t, 2, updates["attempts"])
+		assert.Equal(t, http.StatusBadGateway, updates["last_status_code"])
+		assert.Contains(t, updates["last_error"], "unexpected status 502")
+		next := updates["next_attempt_at"].(time.Time)
+		assert.WithinDuration(t, start.Add(time.Minute), next, 5*time.Second)
+	})
+
+	t.Run("fails after max retries", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+		ep := webhookEndpointWithEvents("user.created")
+		ep.URL = srv.URL
+
+		var updates map[string]any
+		svc, _ := newWebhookDeliverySvc(t, &mockWebhookEndpointRepo{},
+			&mockWebhookDeliveryRepo{
+				claimDueFn: claimOnce(newDelivery(ep, ep.MaxRetries)),
+				updateByIDFn: func(_, data any) (*model.WebhookDelivery, error) {
+					updates = data.(map[string]any)
+					return nil, nil
+				},
+			},
+			&mockAuthEventRepo{},
+		)
+
+		_, err := svc.DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, model.WebhookDeliveryStatusFailed, updates["status"])
+		assert.Nil(t, updates["next_attempt_at"])
+	})
+
+	t.Run("inactive endpoint fails without a request", func(t *testing.T) {
+		ep := webhookEndpointWithEvents("user.created")
+		ep.Status = model.StatusInactive
+		ep.URL = "http://127.0.0.1:1"
+
+		var updates map[string]any
+		endpointRepo := &mockWebhookEndpointRepo{}
+		svc, _ := newWebhookDeliverySvc(t, endpointRepo,
+			&mockWebhookDeliveryRepo{
+				claimDueFn: claimOnce(newDelivery(ep, 0)),
+				updateByIDFn: func(_, data any) (*model.WebhookDelivery, error) {
+					updates = data.(map[string]any)
+					return nil, nil
+				},
+			},
+			&mockAuthEventRepo{},
+		)
+
+		_, err := svc.DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, model.WebhookDeliveryStatusFailed, updates["status"])
+		assert.Equal(t, "webhook endpoint is inactive", updates["last_error"])
+		assert.Empty(t, endpointRepo.triggered)
+	})
+
+	t.Run("claim error", func(t *testing.T) {
+		svc, _ := newWebhookDeliverySvc(t, &mockWebhookEndpointRepo{},
+			&mockWebhookDeliveryRepo{claimDueFn: func(time.Time, time.Duration, int) ([]model.WebhookDelivery, error) {
+				return nil, errors.New("db down")
+			}},
+			&mockAuthEventRepo{},
+		)
+
+		_, err := svc.DeliverDue(context.Background())
+		require.Error(t, err)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// GetAll / GetByUUID
+// ---------------------------------------------------------------------------
+
+func TestWebhookDeliveryService_GetAll(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ep := webhookEndpointWithEvents("user.created")
+		var filter repository.WebhookDeliveryRepositoryGetFilter
+		svc, _ := newWebhookDeliverySvc(t,
+			&mockWebhookEndpointRepo{findByUUIDAndTenantFn: func(uuid.UUID, int64) (*model.WebhookEndpoint, error) { return &ep, nil }},
+			&mockWebhookDeliveryRepo{findPaginatedFn: func(f repository.WebhookDeliveryRepositoryGetFilter) (*repository.PaginationResult[model.WebhookDelivery], error) {
+				filter = f
+				return &repository.PaginationResult[model.WebhookDelivery]{
+					Data:  []model.WebhookDelivery{{WebhookDeliveryUUID: uuid.New(), Status: model.WebhookDeliveryStatusFailed}},
+					Total: 1, Page: 1, Limit: 10, TotalPages: 1,
+				}, nil
+			}},
+			&mockAuthEventRepo{},
+		)
+
+		res, err := svc.GetAll(context.Background(), 1, ep.WebhookEndpointUUID, []string{"failed"}, nil, 1, 10)
+		require.NoError(t, err)
+		assert.Len(t, res.Data, 1)
+		assert.Equal(t, ep.WebhookEndpointID, filter.WebhookEndpointID)
+		assert.Equal(t, []string{"failed"}, filter.Status)
+	})
+
+	t.Run("endpoint not found", func(t *testing.T) {
+		svc, _ := newWebhookDeliverySvc(t, &mockWebhookEndpointRepo{}, &mockWebhookDeliveryRepo{}, &mockAuthEventRepo{})
+
+		_, err := svc.GetAll(context.Background(), 1, uuid.New(), nil, nil, 1, 10)
+		require.Error(t, err)
+		var notFound *apperror.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+}
+
+func TestWebhookDeliveryService_GetByUUID(t *testing.T) {
+	ep := webhookEndpointWithEvents("user.created")
+	endpointRepo := &mockWebhookEndpointRepo{findByUUIDAndTenantFn: func(uuid.UUID, int64) (*model.WebhookEndpoint, error) { return &ep, nil }}
+
+	t.Run("success", func(t *testing.T) {
+		id := uuid.New()
+		svc, _ := newWebhookDeliverySvc(t, endpointRepo,
+			&mockWebhookDeliveryRepo{findByUUIDAndEndpointFn: func(got uuid.UUID, endpointID int64) (*model.WebhookDelivery, error) {
+				assert.Equal(t, ep.WebhookEndpointID, endpointID)
+				return &model.WebhookDelivery{WebhookDeliveryUUID: got, Payload: datatypes.JSON(`{"a":1}`)}, nil
+			}},
+			&mockAuthEventRepo{},
+		)
+
+		res, err := svc.GetByUUID(context.Background(), 1, ep.WebhookEndpointUUID, id)
+		require.NoError(t, err)
+		assert.Equal(t, id, res.WebhookDeliveryUUID)
+		assert.JSONEq(t, `{"a":1}`, string(res.Payload))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc, _ := newWebhookDeliverySvc(t, endpointRepo, &mockWebhookDeliveryRepo{}, &mockAuthEventRepo{})
+
+		_, err := svc.GetByUUID(context.Background(), 1, ep.WebhookEndpointUUID, uuid.New())
+		require.Error(t, err)
+		var notFound *apperror.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+}
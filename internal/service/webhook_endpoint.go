@@ -3,10 +3,12 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/maintainerd/auth/internal/apperror"
+	"github.com/maintainerd/auth/internal/crypto"
 	"github.com/maintainerd/auth/internal/model"
 	"github.com/maintainerd/auth/internal/repository"
 	"go.opentelemetry.io/otel"
@@ -15,20 +17,33 @@ import (
 	"gorm.io/datatypes"
 )
 
+const (
+	// webhookSecretPrefix marks signing secrets generated by the server.
+	webhookSecretPrefix = "whsec_"
+	// DefaultWebhookSecretGracePeriod is how long the previous signing secret
+	// keeps signing deliveries after a rotation when no grace period is given.
+	DefaultWebhookSecretGracePeriod = 24 * time.Hour
+)
+
 // WebhookEndpointServiceDataResult is the service-layer representation of a
-// webhook_endpoints record.
+// webhook_endpoints record. Secret is only set when the server generated a
+// new signing secret, so that it can be shown to the caller once.
 type WebhookEndpointServiceDataResult struct {
-	WebhookEndpointUUID uuid.UUID
-	TenantID            int64
-	URL                 string
-	Events              any
-	MaxRetries          int
-	TimeoutSeconds      int
-	Status              string
-	Description         string
-	LastTriggeredAt     *time.Time
-	CreatedAt           time.Time
-	UpdatedAt           time.Time
+	WebhookEndpointUUID     uuid.UUID
+	TenantID                int64
+	URL                     string
+	Events                  any
+	MaxRetries              int
+	TimeoutSeconds          int
+	Status                  string
+	Description             string
+	HasSecret               bool
+	Secret                  string
+	SecretRotatedAt         *time.Time
+	PreviousSecretExpiresAt *time.Time
+	LastTriggeredAt         *time.Time
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
 }
 
 // WebhookEndpointServiceListResult holds a paginated list of webhook endpoints.
@@ -47,16 +62,53 @@ type WebhookEndpointService interface {
 	Create(ctx context.Context, tenantID int64, url, secret string, events []string, maxRetries, timeoutSeconds *int, description, status string) (*WebhookEndpointServiceDataResult, error)
 	Update(ctx context.Context, tenantID int64, webhookEndpointUUID uuid.UUID, url, secret string, events []string, maxRetries, timeoutSeconds *int, description, status string) (*WebhookEndpointServiceDataResult, error)
 	UpdateStatus(ctx context.Context, tenantID int64, webhookEndpointUUID uuid.UUID, status string) (*WebhookEndpointServiceDataResult, error)
+	RotateSecret(ctx context.Context, tenantID int64, webhookEndpointUUID uuid.UUID, gracePeriod time.Duration) (*WebhookEndpointServiceDataResult, error)
 	Delete(ctx context.Context, tenantID int64, webhookEndpointUUID uuid.UUID) (*WebhookEndpointServiceDataResult, error)
 }
 
 type webhookEndpointService struct {
 	webhookEndpointRepo repository.WebhookEndpointRepository
+	eventRepo           repository.EventRepository
+	authEventRepo       repository.AuthEventRepository
 }
 
 // NewWebhookEndpointService creates a new WebhookEndpointService.
-func NewWebhookEndpointService(webhookEndpointRepo repository.WebhookEndpointRepository) WebhookEndpointService {
-	return &webhookEndpointService{webhookEndpointRepo: webhookEndpointRepo}
+func NewWebhookEndpointService(
+	webhookEndpointRepo repository.WebhookEndpointRepository,
+	eventRepo repository.EventRepository,
+	authEventRepo repository.AuthEventRepository,
+) WebhookEndpointService {
+	return &webhookEndpointService{
+		webhookEndpointRepo: webhookEndpointRepo,
+		eventRepo:           eventRepo,
+		authEventRepo:       authEventRepo,
+	}
+}
+
+// resetCursors moves the endpoint's event log positions to the latest
+// events of its tenant, so that deliveries start with the next event rather
+// than replaying history.
+func (s *webhookEndpointService) resetCursors(ep *model.WebhookEndpoint) error {
+	lastEventID, err := s.eventRepo.LatestID(ep.TenantID)
+	if err != nil {
+		return err
+	}
+	lastAuthEventID, err := s.authEventRepo.LatestID(ep.TenantID)
+	if err != nil {
+		return err
+	}
+	ep.LastEventID = lastEventID
+	ep.LastAuthEventID = lastAuthEventID
+	return nil
+}
+
+// generateWebhookSecret returns a new random signing secret.
+func generateWebhookSecret() (string, error) {
+	random, err := crypto.GenerateRandomString(32)
+	if err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + random, nil
 }
 
 func toWebhookEndpointServiceDataResult(we *model.WebhookEndpoint) WebhookEndpointServiceDataResult {
@@ -68,21 +120,38 @@ func toWebhookEndpointServiceDataResult(we *model.WebhookEndpoint) WebhookEndpoi
 		events = []any{}
 	}
 
+	// A previous secret only matters while it is still used for signing.
+	var previousExpiresAt *time.Time
+	if we.PreviousSecretEncrypted != "" && we.PreviousSecretExpiresAt != nil && we.PreviousSecretExpiresAt.After(time.Now()) {
+		previousExpiresAt = we.PreviousSecretExpiresAt
+	}
+
 	return WebhookEndpointServiceDataResult{
-		WebhookEndpointUUID: we.WebhookEndpointUUID,
-		TenantID:            we.TenantID,
-		URL:                 we.URL,
-		Events:              events,
-		MaxRetries:          we.MaxRetries,
-		TimeoutSeconds:      we.TimeoutSeconds,
-		Status:              we.Status,
-		Description:         we.Description,
-		LastTriggeredAt:     we.LastTriggeredAt,
-		CreatedAt:           we.CreatedAt,
-		UpdatedAt:           we.UpdatedAt,
+		WebhookEndpointUUID:     we.WebhookEndpointUUID,
+		TenantID:                we.TenantID,
+		URL:                     we.URL,
+		Events:                  events,
+		MaxRetries:              we.MaxRetries,
+		TimeoutSeconds:          we.TimeoutSeconds,
+		Status:                  we.Status,
+		Description:             we.Description,
+		HasSecret:               we.SecretEncrypted != "",
+		SecretRotatedAt:         we.SecretRotatedAt,
+		PreviousSecretExpiresAt: previousExpiresAt,
+		LastTriggeredAt:         we.LastTriggeredAt,
+		CreatedAt:               we.CreatedAt,
+		UpdatedAt:               we.UpdatedAt,
 	}
 }
 
+// sameWebhookEvents reports whether the stored subscription lists the same
+// events, in the same order, as events.
+func sameWebhookEvents(stored datatypes.JSON, events []string) bool {
+	var current []string
+	_ = json.Unmarshal(stored, &current)
+	return slices.Equal(current, events)
+}
+
 // GetAll retrieves a paginated list of webhook endpoints for a tenant.
 func (s *webhookEndpointService) GetAll(ctx context.Context, tenantID int64, status []string, page, limit int, sortBy, sortOrder string) (*WebhookEndpointServiceListResult, error) {
 	_, span := otel.Tracer("service").Start(ctx, "webhookEndpoint.list")
@@ -144,7 +213,8 @@ func (s *webhookEndpointService) GetByUUID(ctx context.Context, tenantID int64,
 	return &result, nil
 }
 
-// Create creates a new webhook endpoint for a tenant.
+// Create creates a new webhook endpoint for a tenant. When no secret is
+// given one is generated and returned once in the result.
 func (s *webhookEndpointService) Create(ctx context.Context, tenantID int64, url, secret string, events []string, maxRetries, timeoutSeconds *int, description, status string) (*WebhookEndpointServiceDataResult, error) {
 	_, span := otel.Tracer("service").Start(ctx, "webhookEndpoint.create")
 	defer span.End()
@@ -157,6 +227,16 @@ func (s *webhookEndpointService) Create(ctx context.Context, tenantID int64, url
 		return nil, apperror.NewValidation("invalid events payload")
 	}
 
+	generated := ""
+	if secret == "" {
+		if generated, err = generateWebhookSecret(); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "generate webhook secret failed")
+			return nil, apperror.NewInternal("failed to generate webhook secret", err)
+		}
+		secret = generated
+	}
+
 	ep := &model.WebhookEndpoint{
 		TenantID:        tenantID,
 		URL:             url,
@@ -173,6 +253,11 @@ func (s *webhookEndpointService) Create(ctx context.Context, tenantID int64, url
 	if timeoutSeconds != nil {
 		ep.TimeoutSeconds = *timeoutSeconds
 	}
+	if err := s.resetCursors(ep); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "read event log position failed")
+		return nil, err
+	}
 
 	created, err := s.webhookEndpointRepo.Create(ep)
 	if err != nil {
@@ -183,6 +268,7 @@ func (s *webhookEndpointService) Create(ctx context.Context, tenantID int64, url
 
 	span.SetStatus(codes.Ok, "")
 	result := toWebhookEndpointServiceDataResult(created)
+	result.Secret = generated
 	return &result, nil
 }
 
@@ -213,6 +299,16 @@ func (s *webhookEndpointService) Update(ctx context.Context, tenantID int64, web
 		return nil, apperror.NewValidation("invalid events payload")
 	}
 
+	// Changing the subscription or reactivating the endpoint starts
+	// deliveries from the next event.
+	if !sameWebhookEvents(ep.Events, events) || (ep.Status != model.StatusActive && status == model.StatusActive) {
+		if err := s.resetCursors(ep); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "read event log position failed")
+			return nil, err
+		}
+	}
+
 	ep.URL = url
 	ep.Events = datatypes.JSON(eventsJSON)
 	ep.Description = description
@@ -259,6 +355,13 @@ func (s *webhookEndpointService) UpdateStatus(ctx context.Context, tenantID int6
 		return nil, apperror.NewNotFoundWithReason("webhook endpoint not found")
 	}
 
+	if ep.Status != model.StatusActive && status == model.StatusActive {
+		if err := s.resetCursors(ep); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "read event log position failed")
+			return nil, err
+		}
+	}
 	ep.Status = status
 
 	updated, err := s.webhookEndpointRepo.UpdateByUUID(webhookEndpointUUID, ep)
@@ -273,6 +376,78 @@ func (s *webhookEndpointService) UpdateStatus(ctx context.Context, tenantID int6
 	return &result, nil
 }
 
+// RotateSecret replaces the signing secret of a webhook endpoint with a new
+// generated one, returned once in the result. For gracePeriod the previous
+// secret keeps signing deliveries alongside the new one, so receivers can
+// switch over without rejecting requests. A zero grace period drops the
+// previous secret immediately.
+func (s *webhookEndpointService) RotateSecret(ctx context.Context, tenantID int64, webhookEndpointUUID uuid.UUID, gracePeriod time.Duration) (*WebhookEndpointServiceDataResult, error) {
+	_, span := otel.Tracer("service").Start(ctx, "webhookEndpoint.rotateSecret")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("webhook.uuid", webhookEndpointUUID.String()),
+		attribute.Int64("tenant.id", tenantID),
+		attribute.Int64("webhook.secret_grace_seconds", int64(gracePeriod/time.Second)),
+	)
+
+	ep, err := s.webhookEndpointRepo.FindByUUIDAndTenantID(webhookEndpointUUID, tenantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "find webhook endpoint for secret rotation failed")
+		return nil, err
+	}
+	if ep == nil {
+		span.SetStatus(codes.Error, "webhook endpoint not found")
+		return nil, apperror.NewNotFoundWithReason("webhook endpoint not found")
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "generate webhook secret failed")
+		return nil, apperror.NewInternal("failed to generate webhook secret", err)
+	}
+
+	// Map updates bypass the column serializer, so the secrets are sealed
+	// here.
+	var previous string
+	if gracePeriod > 0 {
+		previous = ep.SecretEncrypted
+	}
+	sealedSecret, err := crypto.EncryptColumn(model.WebhookEndpoint{}.TableName(), "secret_encrypted", secret)
+	if err == nil {
+		previous, err = crypto.EncryptColumn(model.WebhookEndpoint{}.TableName(), "previous_secret_encrypted", previous)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "encrypt webhook secret failed")
+		return nil, apperror.NewInternal("failed to encrypt webhook secret", err)
+	}
+
+	now := time.Now()
+	updates := map[string]any{
+		"secret_encrypted":           sealedSecret,
+		"secret_rotated_at":          now,
+		"previous_secret_encrypted":  previous,
+		"previous_secret_expires_at": nil,
+	}
+	if previous != "" {
+		updates["previous_secret_expires_at"] = now.Add(gracePeriod)
+	}
+
+	updated, err := s.webhookEndpointRepo.UpdateByUUID(webhookEndpointUUID, updates)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "rotate webhook endpoint secret failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	result := toWebhookEndpointServiceDataResult(updated)
+	result.Secret = secret
+	return &result, nil
+}
+
 // Delete deletes a webhook endpoint, verifying tenant ownership first.
 func (s *webhookEndpointService) Delete(ctx context.Context, tenantID int64, webhookEndpointUUID uuid.UUID) (*WebhookEndpointServiceDataResult, error) {
 	_, span := otel.Tracer("service").Start(ctx, "webhookEndpoint.delete")
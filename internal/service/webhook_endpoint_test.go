@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,7 +17,7 @@ import (
 )
 
 func newWebhookEndpointSvc(repo *mockWebhookEndpointRepo) WebhookEndpointService {
-	return NewWebhookEndpointService(repo)
+	return NewWebhookEndpointService(repo, &mockEventRepo{}, &mockAuthEventRepo{})
 }
 
 func newWebhookEndpoint(tenantID int64) *model.WebhookEndpoint {
@@ -146,6 +147,26 @@ func TestWebhookEndpointService_Create(t *testing.T) {
 		assert.Equal(t, "https://example.com/hook", res.URL)
 		assert.Equal(t, 3, res.MaxRetries)
 		assert.Equal(t, 30, res.TimeoutSeconds)
+		assert.True(t, res.HasSecret)
+		assert.Empty(t, res.Secret, "a caller supplied secret is not echoed back")
+	})
+
+	t.Run("generates secret when blank", func(t *testing.T) {
+		var stored string
+		svc := newWebhookEndpointSvc(&mockWebhookEndpointRepo{
+			createFn: func(e *model.WebhookEndpoint) (*model.WebhookEndpoint, error) {
+				stored = e.SecretEncrypted
+				return e, nil
+			},
+		})
+		res, err := svc.Create(context.Background(), 1,
+			"https://example.com/hook", "",
+			[]string{"user.created"}, nil, nil, "", model.StatusActive,
+		)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(res.Secret, "whsec_"))
+		assert.Equal(t, stored, res.Secret)
+		assert.True(t, res.HasSecret)
 	})
 
 	t.Run("success with custom retries and timeout", func(t *testing.T) {
@@ -309,6 +330,84 @@ func TestWebhookEndpointService_UpdateStatus(t *testing.T) {
 	})
 }
 
+// ---------------------------------------------------------------------------
+// RotateSecret
+// ---------------------------------------------------------------------------
+
+func TestWebhookEndpointService_RotateSecret(t *testing.T) {
+	t.Run("keeps previous secret for grace period", func(t *testing.T) {
+		ep := newWebhookEndpoint(1)
+		var updates map[string]any
+		svc := newWebhookEndpointSvc(&mockWebhookEndpointRepo{
+			findByUUIDAndTenantFn: func(_ uuid.UUID, _ int64) (*model.WebhookEndpoint, error) { return ep, nil },
+			updateByUUIDFn: func(_ any, data any) (*model.WebhookEndpoint, error) {
+				updates = data.(map[string]any)
+				updated := *ep
+				updated.SecretEncrypted = updates["secret_encrypted"].(string)
+				updated.PreviousSecretEncrypted = updates["previous_secret_encrypted"].(string)
+				expiresAt := updates["previous_secret_expires_at"].(time.Time)
+				updated.PreviousSecretExpiresAt = &expiresAt
+				rotatedAt := updates["secret_rotated_at"].(time.Time)
+				updated.SecretRotatedAt = &rotatedAt
+				return &updated, nil
+			},
+		})
+		res, err := svc.RotateSecret(context.Background(), 1, ep.WebhookEndpointUUID, time.Hour)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(res.Secret, "whsec_"))
+		assert.Equal(t, res.Secret, updates["secret_encrypted"])
+		assert.Equal(t, "sec123", updates["previous_secret_encrypted"])
+		require.NotNil(t, res.PreviousSecretExpiresAt)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), *res.PreviousSecretExpiresAt, time.Minute)
+		assert.NotNil(t, res.SecretRotatedAt)
+	})
+
+	t.Run("zero grace drops previous secret", func(t *testing.T) {
+		ep := newWebhookEndpoint(1)
+		var updates map[string]any
+		svc := newWebhookEndpointSvc(&mockWebhookEndpointRepo{
+			findByUUIDAndTenantFn: func(_ uuid.UUID, _ int64) (*model.WebhookEndpoint, error) { return ep, nil },
+			updateByUUIDFn: func(_ any, data any) (*model.WebhookEndpoint, error) {
+				updates = data.(map[string]any)
+				return ep, nil
+			},
+		})
+		_, err := svc.RotateSecret(context.Background(), 1, ep.WebhookEndpointUUID, 0)
+		require.NoError(t, err)
+		assert.Equal(t, "", updates["previous_secret_encrypted"])
+		assert.Nil(t, updates["previous_secret_expires_at"])
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc := newWebhookEndpointSvc(&mockWebhookEndpointRepo{
+			findByUUIDAndTenantFn: func(_ uuid.UUID, _ int64) (*model.WebhookEndpoint, error) { return nil, nil },
+		})
+		_, err := svc.RotateSecret(context.Background(), 1, uuid.New(), time.Hour)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("update error", func(t *testing.T) {
+		ep := newWebhookEndpoint(1)
+		svc := newWebhookEndpointSvc(&mockWebhookEndpointRepo{
+			findByUUIDAndTenantFn: func(_ uuid.UUID, _ int64) (*model.WebhookEndpoint, error) { return ep, nil },
+			updateByUUIDFn: func(_ any, _ any) (*model.WebhookEndpoint, error) {
+				return nil, errors.New("save err")
+			},
+		})
+		_, err := svc.RotateSecret(context.Background(), 1, ep.WebhookEndpointUUID, time.Hour)
+		require.Error(t, err)
+	})
+}
+
+func TestToWebhookEndpointServiceDataResult_ExpiredPreviousSecret(t *testing.T) {
+	ep := newWebhookEndpoint(1)
+	expired := time.Now().Add(-time.Minute)
+	ep.PreviousSecretEncrypted = "old"
+	ep.PreviousSecretExpiresAt = &expired
+	assert.Nil(t, toWebhookEndpointServiceDataResult(ep).PreviousSecretExpiresAt)
+}
+
 // ---------------------------------------------------------------------------
 // Delete
 // ---------------------------------------------------------------------------
@@ -389,3 +488,82 @@ func TestToWebhookEndpointServiceDataResult(t *testing.T) {
 		assert.Equal(t, []any{}, result.Events)
 	})
 }
+
+// ---------------------------------------------------------------------------
+// Event log positions
+// ---------------------------------------------------------------------------
+
+func TestWebhookEndpointService_Cursors(t *testing.T) {
+	latest := func(id int64) func(int64) (int64, error) {
+		return func(int64) (int64, error) { return id, nil }
+	}
+
+	t.Run("create starts at the latest events", func(t *testing.T) {
+		var created *model.WebhookEndpoint
+		repo := &mockWebhookEndpointRepo{createFn: func(e *model.WebhookEndpoint) (*model.WebhookEndpoint, error) {
+			created = e
+			return e, nil
+		}}
+		svc := NewWebhookEndpointService(repo, &mockEventRepo{latestIDFn: latest(40)}, &mockAuthEventRepo{latestIDFn: latest(7)})
+
+		_, err := svc.Create(context.Background(), 1, "https://example.com/webhook", "", []string{"user.created"}, nil, nil, "", model.StatusActive)
+		require.NoError(t, err)
+		assert.Equal(t, int64(40), created.LastEventID)
+		assert.Equal(t, int64(7), created.LastAuthEventID)
+	})
+
+	t.Run("create fails when the event log cannot be read", func(t *testing.T) {
+		svc := NewWebhookEndpointService(&mockWebhookEndpointRepo{}, &mockEventRepo{latestIDFn: func(int64) (int64, error) {
+			return 0, errors.New("db down")
+		}}, &mockAuthEventRepo{})
+
+		_, err := svc.Create(context.Background(), 1, "https://example.com/webhook", "", []string{"user.created"}, nil, nil, "", model.StatusActive)
+		require.Error(t, err)
+	})
+
+	update := func(t *testing.T, ep *model.WebhookEndpoint, events []string, status string) *model.WebhookEndpoint {
+		t.Helper()
+		var saved *model.WebhookEndpoint
+		repo := &mockWebhookEndpointRepo{
+			findByUUIDAndTenantFn: func(uuid.UUID, int64) (*model.WebhookEndpoint, error) { return ep, nil },
+			updateByUUIDFn: func(_, data any) (*model.WebhookEndpoint, error) {
+				saved = data.(*model.WebhookEndpoint)
+				return saved, nil
+			},
+		}
+		svc := NewWebhookEndpointService(repo, &mockEventRepo{latestIDFn: latest(40)}, &mockAuthEventRepo{latestIDFn: latest(7)})
+		_, err := svc.Update(context.Background(), 1, ep.WebhookEndpointUUID, ep.URL, "", events, nil, nil, "", status)
+		require.NoError(t, err)
+		return saved
+	}
+
+	t.Run("update keeps positions when the subscription is unchanged", func(t *testing.T) {
+		ep := newWebhookEndpoint(1)
+		ep.LastEventID, ep.LastAuthEventID = 3, 2
+		saved := update(t, ep, []string{"user.created", "user.deleted"}, model.StatusActive)
+		assert.Equal(t, int64(3), saved.LastEventID)
+		assert.Equal(t, int64(2), saved.LastAuthEventID)
+	})
+
+	t.Run("update resets positions when events change", func(t *testing.T) {
+		ep := newWebhookEndpoint(1)
+		ep.LastEventID, ep.LastAuthEventID = 3, 2
+		saved := update(t, ep, []string{"login.failed"}, model.StatusActive)
+		assert.Equal(t, int64(40), saved.LastEventID)
+		assert.Equal(t, int64(7), saved.LastAuthEventID)
+	})
+
+	t.Run("reactivation resets positions", func(t *testing.T) {
+		ep := newWebhookEndpoint(1)
+		ep.Status = model.StatusInactive
+		ep.LastEventID = 3
+		repo := &mockWebhookEndpointRepo{
+			findByUUIDAndTenantFn: func(uuid.UUID, int64) (*model.WebhookEndpoint, error) { return ep, nil },
+			updateByUUIDFn:        func(_, data any) (*model.WebhookEndpoint, error) { return data.(*model.WebhookEndpoint), nil },
+		}
+		svc := NewWebhookEndpointService(repo, &mockEventRepo{latestIDFn: latest(40)}, &mockAuthEventRepo{})
+		_, err := svc.UpdateStatus(context.Background(), 1, ep.WebhookEndpointUUID, model.StatusActive)
+		require.NoError(t, err)
+		assert.Equal(t, int64(40), ep.LastEventID)
+	})
+}
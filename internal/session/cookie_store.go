@@ -0,0 +1,134 @@
+package session
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// KeySize is the required length in bytes of a cookie store key.
+const KeySize = 32
+
+// maxCookieSize is the largest cookie value browsers reliably accept.
+const maxCookieSize = 4000
+
+// ErrTooLarge is returned by CookieStore.Save when the sealed session does
+// not fit in a cookie.
+var ErrTooLarge = errors.New("session too large for cookie store")
+
+// CookieStore seals sessions into the cookie with AES-256-GCM, so the
+// browser holds the data but can neither read nor modify it. The cookie
+// name is bound as additional data and the expiry is sealed with the
+// values, so a cookie cannot be replayed under another name or after it
+// expired.
+//
+// Keys rotate by prepending: the first key seals new cookies and every key
+// opens existing ones. Drop a retired key once TTL has passed.
+type CookieStore struct {
+	aeads []cipher.AEAD
+	opts  Options
+	now   func() time.Time
+}
+
+type cookiePayload struct {
+	Values    map[string]string `json:"v"`
+	ExpiresAt int64             `json:"e"`
+}
+
+// NewCookieStore creates a cookie store from one or more 32-byte keys,
+// current key first.
+func NewCookieStore(keys [][]byte, opts Options) (*CookieStore, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("cookie session store requires at least one key")
+	}
+	aeads := make([]cipher.AEAD, 0, len(keys))
+	for i, key := range keys {
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("session key %d must be %d bytes, got %d", i, KeySize, len(key))
+		}
+		// The key length is checked above, so NewCipher and NewGCM cannot fail.
+		block, _ := aes.NewCipher(key)
+		aead, _ := cipher.NewGCM(block)
+		aeads = append(aeads, aead)
+	}
+	return &CookieStore{aeads: aeads, opts: opts.withDefaults(), now: time.Now}, nil
+}
+
+// Load implements Store.
+func (c *CookieStore) Load(r *http.Request) (*Session, error) {
+	ck, err := r.Cookie(c.opts.Name)
+	if err != nil {
+		return newSession(), nil
+	}
+	payload, ok := c.open(ck.Value)
+	if !ok || c.now().Unix() >= payload.ExpiresAt {
+		return newSession(), nil
+	}
+	s := newSession()
+	for k, v := range payload.Values {
+		s.Values[k] = v
+	}
+	return s, nil
+}
+
+// Save implements Store. The cookie is always resealed with the current key,
+// which migrates sessions off retired keys.
+func (c *CookieStore) Save(_ context.Context, w http.ResponseWriter, s *Session) error {
+	plaintext, err := json.Marshal(cookiePayload{
+		Values:    s.Values,
+		ExpiresAt: c.now().Add(c.opts.TTL).Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	aead := c.aeads[0]
+	nonce := make([]byte, aead.NonceSize())
+	_, _ = rand.Read(nonce)
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(c.opts.Name))
+	value := base64.RawURLEncoding.EncodeToString(sealed)
+	if len(value) > maxCookieSize {
+		return ErrTooLarge
+	}
+
+	http.SetCookie(w, c.opts.cookie(value, int(c.opts.TTL.Seconds())))
+	return nil
+}
+
+// Destroy implements Store.
+func (c *CookieStore) Destroy(_ context.Context, w http.ResponseWriter, s *Session) error {
+	s.Values = map[string]string{}
+	http.SetCookie(w, c.opts.cookie("", -1))
+	return nil
+}
+
+// open decrypts value with each key in turn.
+func (c *CookieStore) open(value string) (cookiePayload, bool) {
+	var payload cookiePayload
+	sealed, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return payload, false
+	}
+	for _, aead := range c.aeads {
+		n := aead.NonceSize()
+		if len(sealed) < n {
+			return payload, false
+		}
+		plaintext, err := aead.Open(nil, sealed[:n], sealed[n:], []byte(c.opts.Name))
+		if err != nil {
+			continue
+		}
+		if err := json.Unmarshal(plaintext, &payload); err != nil {
+			return payload, false
+		}
+		return payload, true
+	}
+	return payload, false
+}
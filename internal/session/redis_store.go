@@ -0,0 +1,88 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix namespaces session keys in Redis.
+const redisKeyPrefix = "session:"
+
+// RedisStore keeps session data in Redis under a random 256-bit ID; the
+// cookie carries only the ID.
+type RedisStore struct {
+	rdb  *redis.Client
+	opts Options
+}
+
+// NewRedisStore creates a Redis-backed store.
+func NewRedisStore(rdb *redis.Client, opts Options) *RedisStore {
+	return &RedisStore{rdb: rdb, opts: opts.withDefaults()}
+}
+
+// Load implements Store.
+func (s *RedisStore) Load(r *http.Request) (*Session, error) {
+	ck, err := r.Cookie(s.opts.Name)
+	if err != nil || ck.Value == "" {
+		return newSession(), nil
+	}
+
+	raw, err := s.rdb.Get(r.Context(), redisKeyPrefix+ck.Value).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return newSession(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sess := newSession()
+	if err := json.Unmarshal(raw, &sess.Values); err != nil || sess.Values == nil {
+		return newSession(), nil
+	}
+	sess.id = ck.Value
+	return sess, nil
+}
+
+// Save implements Store.
+func (s *RedisStore) Save(ctx context.Context, w http.ResponseWriter, sess *Session) error {
+	if sess.renew && sess.id != "" {
+		if err := s.rdb.Del(ctx, redisKeyPrefix+sess.id).Err(); err != nil {
+			return err
+		}
+		sess.id = ""
+	}
+	sess.renew = false
+	if sess.id == "" {
+		id := make([]byte, 32)
+		_, _ = rand.Read(id)
+		sess.id = base64.RawURLEncoding.EncodeToString(id)
+	}
+	raw, err := json.Marshal(sess.Values)
+	if err != nil {
+		return err
+	}
+	if err := s.rdb.Set(ctx, redisKeyPrefix+sess.id, raw, s.opts.TTL).Err(); err != nil {
+		return err
+	}
+
+	http.SetCookie(w, s.opts.cookie(sess.id, int(s.opts.TTL.Seconds())))
+	return nil
+}
+
+// Destroy implements Store.
+func (s *RedisStore) Destroy(ctx context.Context, w http.ResponseWriter, sess *Session) error {
+	http.SetCookie(w, s.opts.cookie("", -1))
+	sess.Values = map[string]string{}
+	if sess.id == "" {
+		return nil
+	}
+	id := sess.id
+	sess.id = ""
+	return s.rdb.Del(ctx, redisKeyPrefix+id).Err()
+}
@@ -0,0 +1,147 @@
+// Package session keeps short-lived browser state for the hosted pages (login,
+// consent) served on the identity port. Two stores are available: a Redis
+// store that keeps the data server-side behind an opaque cookie, and a
+// stateless cookie store that seals the data into the cookie itself so small
+// deployments can run the hosted pages without Redis.
+package session
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Store kinds accepted by New.
+const (
+	StoreRedis  = "redis"
+	StoreCookie = "cookie"
+)
+
+// Defaults applied by New to empty Options fields.
+const (
+	DefaultName = "__Host-auth_session"
+	DefaultTTL  = 30 * time.Minute
+)
+
+// BrowserName is the cookie name of the sessions first-party web apps sign
+// in with instead of bearer tokens.
+const BrowserName = "__Host-browser_session"
+
+// Session is the state of one browser. Values is never nil on a session
+// returned by a Store.
+type Session struct {
+	Values map[string]string
+
+	// id is the server-side key of a Redis session; empty for new sessions
+	// and for cookie sessions.
+	id string
+	// renew asks the next Save to move the data to a new ID.
+	renew bool
+}
+
+// Get returns the value stored under key, or "".
+func (s *Session) Get(key string) string {
+	return s.Values[key]
+}
+
+// Set stores value under key.
+func (s *Session) Set(key, value string) {
+	s.Values[key] = value
+}
+
+// Renew makes the next Save issue a new session ID and drop the old one.
+// Call it when the session's privilege changes, such as after sign-in, to
+// prevent session fixation. Cookie sessions are resealed on every Save and
+// need no renewal.
+func (s *Session) Renew() {
+	s.renew = true
+}
+
+// Store loads and persists sessions.
+type Store interface {
+	// Load returns the request's session. A missing, expired or tampered
+	// session yields a new empty session, never an error; errors are
+	// reserved for backend failures.
+	Load(r *http.Request) (*Session, error)
+	// Save persists s and writes the session cookie. Every save extends
+	// the session by the store's TTL.
+	Save(ctx context.Context, w http.ResponseWriter, s *Session) error
+	// Destroy deletes s and expires the session cookie.
+	Destroy(ctx context.Context, w http.ResponseWriter, s *Session) error
+}
+
+// Options configures the session cookie.
+type Options struct {
+	// Name is the cookie name. The default uses the __Host- prefix, which
+	// requires Secure and Path "/".
+	Name string
+	// TTL is the idle lifetime of a session.
+	TTL time.Duration
+	// SameSite is the cookie's SameSite attribute.
+	SameSite http.SameSite
+	// Insecure drops the Secure attribute for plain-HTTP development setups.
+	// It is ignored with SameSite None, which browsers only accept on
+	// secure cookies.
+	Insecure bool
+}
+
+func (o Options) withDefaults() Options {
+	if o.Name == "" {
+		o.Name = DefaultName
+	}
+	if o.TTL <= 0 {
+		o.TTL = DefaultTTL
+	}
+	if o.SameSite == 0 {
+		o.SameSite = http.SameSiteLaxMode
+	}
+	return o
+}
+
+func (o Options) cookie(value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     o.Name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   !o.Insecure || o.SameSite == http.SameSiteNoneMode,
+		SameSite: o.SameSite,
+	}
+}
+
+// ParseSameSite maps "lax", "strict" or "none" to the cookie attribute.
+func ParseSameSite(value string) (http.SameSite, error) {
+	switch strings.ToLower(value) {
+	case "", "lax":
+		return http.SameSiteLaxMode, nil
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	}
+	return 0, fmt.Errorf("invalid SameSite %q: must be lax, strict or none", value)
+}
+
+// New creates the store named by kind. The cookie store needs at least one
+// key; the Redis store needs rdb.
+func New(kind string, keys [][]byte, rdb *redis.Client, opts Options) (Store, error) {
+	switch kind {
+	case StoreCookie:
+		return NewCookieStore(keys, opts)
+	case StoreRedis:
+		if rdb == nil {
+			return nil, fmt.Errorf("redis session store requires a redis client")
+		}
+		return NewRedisStore(rdb, opts), nil
+	}
+	return nil, fmt.Errorf("unknown session store %q: must be %s or %s", kind, StoreRedis, StoreCookie)
+}
+
+func newSession() *Session {
+	return &Session{Values: map[string]string{}}
+}
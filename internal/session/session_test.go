@@ -0,0 +1,250 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, KeySize)
+}
+
+// roundTrip saves s with store and returns a request carrying the cookie.
+func roundTrip(t *testing.T, store Store, s *Session) (*http.Request, *http.Cookie) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	require.NoError(t, store.Save(context.Background(), w, s))
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	r := httptest.NewRequest(http.MethodGet, "/login", nil)
+	r.AddCookie(cookies[0])
+	return r, cookies[0]
+}
+
+func TestParseSameSite(t *testing.T) {
+	for in, want := range map[string]http.SameSite{
+		"":       http.SameSiteLaxMode,
+		"lax":    http.SameSiteLaxMode,
+		"Strict": http.SameSiteStrictMode,
+		"none":   http.SameSiteNoneMode,
+	} {
+		got, err := ParseSameSite(in)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := ParseSameSite("sometimes")
+	assert.Error(t, err)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(StoreCookie, nil, nil, Options{})
+	assert.Error(t, err)
+	_, err = New(StoreRedis, nil, nil, Options{})
+	assert.Error(t, err)
+	_, err = New("memcached", nil, nil, Options{})
+	assert.Error(t, err)
+
+	store, err := New(StoreCookie, [][]byte{testKey(1)}, nil, Options{})
+	require.NoError(t, err)
+	assert.IsType(t, &CookieStore{}, store)
+}
+
+func TestOptionsCookie(t *testing.T) {
+	ck := Options{Insecure: true}.withDefaults().cookie("v", 60)
+	assert.False(t, ck.Secure)
+	assert.Equal(t, http.SameSiteLaxMode, ck.SameSite)
+
+	// Browsers drop SameSite=None cookies that are not Secure.
+	ck = Options{Insecure: true, SameSite: http.SameSiteNoneMode}.withDefaults().cookie("v", 60)
+	assert.True(t, ck.Secure)
+}
+
+// ---------------------------------------------------------------------------
+// CookieStore
+// ---------------------------------------------------------------------------
+
+func TestNewCookieStore(t *testing.T) {
+	_, err := NewCookieStore([][]byte{[]byte("short")}, Options{})
+	assert.Error(t, err)
+}
+
+func TestCookieStore(t *testing.T) {
+	store, err := NewCookieStore([][]byte{testKey(1)}, Options{SameSite: http.SameSiteStrictMode})
+	require.NoError(t, err)
+
+	t.Run("no cookie", func(t *testing.T) {
+		s, err := store.Load(httptest.NewRequest(http.MethodGet, "/", nil))
+		require.NoError(t, err)
+		assert.Empty(t, s.Values)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		s := newSession()
+		s.Set("client_id", "web")
+		r, ck := roundTrip(t, store, s)
+
+		assert.Equal(t, DefaultName, ck.Name)
+		assert.True(t, ck.HttpOnly)
+		assert.True(t, ck.Secure)
+		assert.Equal(t, http.SameSiteStrictMode, ck.SameSite)
+		assert.Equal(t, int(DefaultTTL.Seconds()), ck.MaxAge)
+		assert.NotContains(t, ck.Value, "web")
+
+		loaded, err := store.Load(r)
+		require.NoError(t, err)
+		assert.Equal(t, "web", loaded.Get("client_id"))
+	})
+
+	t.Run("tampered cookie", func(t *testing.T) {
+		s := newSession()
+		s.Set("k", "v")
+		_, ck := roundTrip(t, store, s)
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		ck.Value = ck.Value[:len(ck.Value)-2] + "AA"
+		r.AddCookie(ck)
+
+		loaded, err := store.Load(r)
+		require.NoError(t, err)
+		assert.Empty(t, loaded.Values)
+	})
+
+	t.Run("cookie under another name", func(t *testing.T) {
+		s := newSession()
+		s.Set("k", "v")
+		_, ck := roundTrip(t, store, s)
+
+		other, err := NewCookieStore([][]byte{testKey(1)}, Options{Name: "other"})
+		require.NoError(t, err)
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		ck.Name = "other"
+		r.AddCookie(ck)
+		loaded, err := other.Load(r)
+		require.NoError(t, err)
+		assert.Empty(t, loaded.Values)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		s := newSession()
+		s.Set("k", "v")
+		r, _ := roundTrip(t, store, s)
+
+		later, err := NewCookieStore([][]byte{testKey(1)}, Options{})
+		require.NoError(t, err)
+		later.now = func() time.Time { return time.Now().Add(DefaultTTL + time.Second) }
+		loaded, err := later.Load(r)
+		require.NoError(t, err)
+		assert.Empty(t, loaded.Values)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		s := newSession()
+		s.Set("k", strings.Repeat("x", maxCookieSize))
+		assert.ErrorIs(t, store.Save(context.Background(), httptest.NewRecorder(), s), ErrTooLarge)
+	})
+
+	t.Run("destroy expires cookie", func(t *testing.T) {
+		s := newSession()
+		s.Set("k", "v")
+		w := httptest.NewRecorder()
+		require.NoError(t, store.Destroy(context.Background(), w, s))
+		assert.Empty(t, s.Values)
+		assert.Equal(t, -1, w.Result().Cookies()[0].MaxAge)
+	})
+}
+
+func TestCookieStore_KeyRotation(t *testing.T) {
+	oldStore, err := NewCookieStore([][]byte{testKey(1)}, Options{})
+	require.NoError(t, err)
+	s := newSession()
+	s.Set("k", "v")
+	r, _ := roundTrip(t, oldStore, s)
+
+	rotated, err := NewCookieStore([][]byte{testKey(2), testKey(1)}, Options{})
+	require.NoError(t, err)
+	loaded, err := rotated.Load(r)
+	require.NoError(t, err)
+	assert.Equal(t, "v", loaded.Get("k"))
+
+	// Resaving seals with the new key, so the old key can be retired.
+	r, _ = roundTrip(t, rotated, loaded)
+	newOnly, err := NewCookieStore([][]byte{testKey(2)}, Options{})
+	require.NoError(t, err)
+	loaded, err = newOnly.Load(r)
+	require.NoError(t, err)
+	assert.Equal(t, "v", loaded.Get("k"))
+
+	// A store without the old key rejects old cookies.
+	r, _ = roundTrip(t, oldStore, s)
+	loaded, err = newOnly.Load(r)
+	require.NoError(t, err)
+	assert.Empty(t, loaded.Values)
+}
+
+// ---------------------------------------------------------------------------
+// RedisStore
+// ---------------------------------------------------------------------------
+
+func newTestRedisStore(t *testing.T) (*RedisStore, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return NewRedisStore(rdb, Options{Name: "sid", Insecure: true}), mr
+}
+
+func TestRedisStore(t *testing.T) {
+	store, mr := newTestRedisStore(t)
+
+	s := newSession()
+	s.Set("client_id", "web")
+	r, ck := roundTrip(t, store, s)
+	assert.Equal(t, "sid", ck.Name)
+	assert.False(t, ck.Secure)
+	assert.True(t, mr.Exists(redisKeyPrefix+ck.Value))
+	assert.Equal(t, DefaultTTL, mr.TTL(redisKeyPrefix+ck.Value))
+
+	loaded, err := store.Load(r)
+	require.NoError(t, err)
+	assert.Equal(t, "web", loaded.Get("client_id"))
+
+	t.Run("unknown id", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.AddCookie(&http.Cookie{Name: "sid", Value: "forged"})
+		loaded, err := store.Load(r)
+		require.NoError(t, err)
+		assert.Empty(t, loaded.Values)
+	})
+
+	t.Run("renew issues new id", func(t *testing.T) {
+		oldID := loaded.id
+		loaded.Renew()
+		_, ck := roundTrip(t, store, loaded)
+		assert.NotEqual(t, oldID, ck.Value)
+		assert.False(t, mr.Exists(redisKeyPrefix+oldID))
+		assert.True(t, mr.Exists(redisKeyPrefix+ck.Value))
+	})
+
+	t.Run("destroy", func(t *testing.T) {
+		id := loaded.id
+		w := httptest.NewRecorder()
+		require.NoError(t, store.Destroy(context.Background(), w, loaded))
+		assert.False(t, mr.Exists(redisKeyPrefix+id))
+		assert.Equal(t, -1, w.Result().Cookies()[0].MaxAge)
+	})
+
+	t.Run("backend error", func(t *testing.T) {
+		mr.Close()
+		_, err := store.Load(r)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,132 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+const (
+	// splunkSource and splunkSourceType label events in Splunk.
+	splunkSource     = "maintainerd-auth"
+	splunkSourceType = "maintainerd:auth:security_event"
+	// userAgent is sent with every HTTP request.
+	userAgent = "maintainerd-auth-siem"
+)
+
+// splunkSink sends batches to a Splunk HTTP Event Collector, one JSON event
+// object per line.
+type splunkSink struct {
+	client   *http.Client
+	url      string
+	token    string
+	index    string
+	hostname string
+}
+
+// splunkEvent is the HEC envelope of an event.
+type splunkEvent struct {
+	Time       float64 `json:"time"`
+	Host       string  `json:"host,omitempty"`
+	Source     string  `json:"source"`
+	SourceType string  `json:"sourcetype"`
+	Index      string  `json:"index,omitempty"`
+	Event      Event   `json:"event"`
+}
+
+func newSplunkSink(cfg Config) *splunkSink {
+	hostname, _ := os.Hostname()
+	return &splunkSink{
+		client:   &http.Client{},
+		url:      cfg.Endpoint,
+		token:    cfg.Token,
+		index:    cfg.SplunkIndex,
+		hostname: hostname,
+	}
+}
+
+func (s *splunkSink) Send(ctx context.Context, events []Event) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, event := range events {
+		err := encoder.Encode(splunkEvent{
+			Time:       float64(event.Timestamp.UnixMilli()) / 1000,
+			Host:       s.hostname,
+			Source:     splunkSource,
+			SourceType: splunkSourceType,
+			Index:      s.index,
+			Event:      event,
+		})
+		if err != nil {
+			return Permanent(fmt.Errorf("encode splunk event: %w", err))
+		}
+	}
+	return post(ctx, s.client, s.url, "Splunk "+s.token, body.Bytes())
+}
+
+func (s *splunkSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// httpsSink posts batches to an HTTPS endpoint as a JSON array of events.
+type httpsSink struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+func newHTTPSSink(cfg Config) *httpsSink {
+	return &httpsSink{client: &http.Client{}, url: cfg.Endpoint, token: cfg.Token}
+}
+
+func (s *httpsSink) Send(ctx context.Context, events []Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return Permanent(fmt.Errorf("encode events: %w", err))
+	}
+	authorization := ""
+	if s.token != "" {
+		authorization = "Bearer " + s.token
+	}
+	return post(ctx, s.client, s.url, authorization, body)
+}
+
+func (s *httpsSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// post sends a JSON body. Network errors, 408, 429 and 5xx responses can be
+// retried; other non-2xx responses are permanent.
+func post(ctx context.Context, client *http.Client, url, authorization string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return Permanent(fmt.Errorf("build request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post to %s: %w", url, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("post to %s: unexpected status %d", url, res.StatusCode)
+	if res.StatusCode == http.StatusRequestTimeout || res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500 {
+		return err
+	}
+	return Permanent(err)
+}
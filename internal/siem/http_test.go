@@ -0,0 +1,124 @@
+package siem
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// splunkSink
+// ---------------------------------------------------------------------------
+
+func TestSplunkSink_Send(t *testing.T) {
+	var got []splunkEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "Splunk hec-token", r.Header.Get("Authorization"))
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var event splunkEvent
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+			got = append(got, event)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink := newSplunkSink(Config{Endpoint: server.URL, Token: "hec-token", SplunkIndex: "security"})
+	defer sink.Close()
+
+	second := cefEvent()
+	second.EventType = "account_locked"
+	require.NoError(t, sink.Send(context.Background(), []Event{cefEvent(), second}))
+
+	require.Len(t, got, 2)
+	assert.Equal(t, "security", got[0].Index)
+	assert.Equal(t, splunkSourceType, got[0].SourceType)
+	assert.Equal(t, float64(1767323045), got[0].Time)
+	assert.Equal(t, "login_failure", got[0].Event.EventType)
+	assert.Equal(t, int64(42), got[0].Event.TenantID)
+	assert.Equal(t, "account_locked", got[1].Event.EventType)
+}
+
+// ---------------------------------------------------------------------------
+// httpsSink
+// ---------------------------------------------------------------------------
+
+func TestHTTPSSink_Send(t *testing.T) {
+	var got []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sink := newHTTPSSink(Config{Endpoint: server.URL, Token: "secret"})
+	require.NoError(t, sink.Send(context.Background(), []Event{cefEvent()}))
+
+	require.Len(t, got, 1)
+	assert.Equal(t, "login_failure", got[0].EventType)
+	assert.Equal(t, "203.0.113.7", got[0].ClientIP)
+}
+
+func TestHTTPSSink_NoToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	sink := newHTTPSSink(Config{Endpoint: server.URL})
+	assert.NoError(t, sink.Send(context.Background(), []Event{cefEvent()}))
+}
+
+func TestPost_StatusCodes(t *testing.T) {
+	tests := []struct {
+		status    int
+		wantErr   bool
+		permanent bool
+	}{
+		{http.StatusOK, false, false},
+		{http.StatusNoContent, false, false},
+		{http.StatusBadRequest, true, true},
+		{http.StatusUnauthorized, true, true},
+		{http.StatusForbidden, true, true},
+		{http.StatusRequestTimeout, true, false},
+		{http.StatusTooManyRequests, true, false},
+		{http.StatusInternalServerError, true, false},
+		{http.StatusServiceUnavailable, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := post(context.Background(), server.Client(), server.URL, "", []byte("[]"))
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Equal(t, tt.permanent, isPermanent(err))
+		})
+	}
+}
+
+func TestPost_ConnectionErrorIsRetryable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	err := post(context.Background(), http.DefaultClient, url, "", []byte("[]"))
+	assert.Error(t, err)
+	assert.False(t, isPermanent(err))
+}
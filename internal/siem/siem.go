@@ -0,0 +1,384 @@
+// Package siem forwards security events to an external SIEM: a syslog
+// collector in ArcSight Common Event Format (CEF), Splunk through its HTTP
+// Event Collector, or any HTTPS endpoint accepting JSON.
+//
+// Events are queued in memory and sent in batches by a single worker, which
+// retries failed batches with exponential backoff. Logging a security event
+// never waits on the SIEM: when the queue is full, or a batch still fails
+// after the last attempt, the events are dropped and counted in
+// maintainerd_auth_siem_events_dropped_total.
+package siem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/maintainerd/auth/internal/metrics"
+	"github.com/maintainerd/auth/internal/security"
+)
+
+// Supported providers.
+const (
+	ProviderSyslog = "syslog"
+	ProviderSplunk = "splunk"
+	ProviderHTTPS  = "https"
+)
+
+// Supported syslog transports. Messages are newline-delimited over TCP.
+const (
+	SyslogUDP = "udp"
+	SyslogTCP = "tcp"
+	SyslogTLS = "tcp+tls"
+)
+
+// Security event severities, lowest first.
+const (
+	SeverityLow    = "LOW"
+	SeverityMedium = "MEDIUM"
+	SeverityHigh   = "HIGH"
+)
+
+const (
+	// DefaultMinSeverity leaves out routine events such as successful logins
+	// and received requests.
+	DefaultMinSeverity = SeverityMedium
+	// DefaultBatchSize is the most events sent in one batch.
+	DefaultBatchSize = 100
+	// DefaultFlushInterval is the longest an event waits for its batch to
+	// fill up.
+	DefaultFlushInterval = 5 * time.Second
+	// DefaultMaxAttempts is how many times a batch is sent before it is
+	// dropped.
+	DefaultMaxAttempts = 5
+	// DefaultTimeout bounds a single send.
+	DefaultTimeout = 10 * time.Second
+
+	// Worker names the forwarder in worker metrics.
+	Worker = "siem_forwarder"
+
+	// queueSize bounds the events waiting to be sent.
+	queueSize = 10000
+	// retryBaseDelay is the wait before the first retry; it doubles after
+	// each failed attempt.
+	retryBaseDelay = time.Second
+	// productName and vendorName identify the sender in CEF headers and
+	// Splunk sources.
+	vendorName  = "maintainerd"
+	productName = "auth"
+)
+
+// severityRank orders severities for the minimum severity filter. A few
+// success events are logged as INFO, which ranks as LOW.
+var severityRank = map[string]int{
+	"INFO":         1,
+	SeverityLow:    1,
+	SeverityMedium: 2,
+	SeverityHigh:   3,
+}
+
+// ValidSeverity reports whether s is a severity the minimum severity can be
+// set to.
+func ValidSeverity(s string) bool {
+	return s == SeverityLow || s == SeverityMedium || s == SeverityHigh
+}
+
+// Event is a security event as sent to the SIEM. JSON field names match the
+// security_event log lines.
+type Event struct {
+	EventType      string    `json:"event_type"`
+	Severity       string    `json:"severity"`
+	TenantID       int64     `json:"tenant_id,omitempty"`
+	UserID         string    `json:"user_id,omitempty"`
+	ClientID       string    `json:"client_id,omitempty"`
+	ClientIP       string    `json:"client_ip,omitempty"`
+	UserAgent      string    `json:"user_agent,omitempty"`
+	RequestID      string    `json:"request_id,omitempty"`
+	Endpoint       string    `json:"endpoint,omitempty"`
+	Method         string    `json:"method,omitempty"`
+	Details        string    `json:"details,omitempty"`
+	Country        string    `json:"country,omitempty"`
+	City           string    `json:"city,omitempty"`
+	ASN            uint      `json:"asn,omitempty"`
+	ASOrganization string    `json:"as_organization,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// NewEvent maps a logged security event, and where its client IP is, to the
+// event sent to the SIEM.
+func NewEvent(e security.SecurityEvent, location *security.GeoLocation) Event {
+	event := Event{
+		EventType: e.EventType,
+		Severity:  e.Severity,
+		TenantID:  e.TenantID,
+		UserID:    e.UserID,
+		ClientID:  e.ClientID,
+		ClientIP:  e.ClientIP,
+		UserAgent: e.UserAgent,
+		RequestID: e.RequestID,
+		Endpoint:  e.Endpoint,
+		Method:    e.Method,
+		Details:   e.Details,
+		Timestamp: e.Timestamp,
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	if location != nil {
+		event.Country = location.Country
+		event.City = location.City
+		event.ASN = location.ASN
+		event.ASOrganization = location.ASOrganization
+	}
+	return event
+}
+
+// Sink delivers batches of events to a SIEM. Send returns once the SIEM has
+// accepted the batch; errors wrapped with Permanent are not retried.
+type Sink interface {
+	Send(ctx context.Context, events []Event) error
+	Close() error
+}
+
+// permanentError marks a failure that sending again cannot fix.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying, such as a rejected token.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+func isPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// TenantFilter decides which events are forwarded. The event's tenant is
+// tenantID when set, otherwise the tenant of the OAuth client clientID.
+type TenantFilter interface {
+	Forwards(ctx context.Context, tenantID int64, clientID string) (bool, error)
+}
+
+// Config selects and configures the SIEM.
+type Config struct {
+	Provider string
+	// Endpoint is host:port for syslog and the full URL for splunk and
+	// https.
+	Endpoint string
+	// SyslogNetwork is udp, tcp or tcp+tls. Defaults to tcp.
+	SyslogNetwork string
+	// Token is the Splunk HEC token, or the bearer token sent to an https
+	// endpoint.
+	Token string
+	// SplunkIndex is the index events are written to; empty uses the
+	// token's default index.
+	SplunkIndex string
+	// Version is the product version in CEF headers.
+	Version string
+	// MinSeverity is the lowest severity forwarded.
+	MinSeverity   string
+	BatchSize     int
+	FlushInterval time.Duration
+	MaxAttempts   int
+	Timeout       time.Duration
+}
+
+// Forwarder queues security events and sends them to a sink in batches. It
+// implements security.SecurityEventForwarder.
+type Forwarder struct {
+	sink          Sink
+	tenants       TenantFilter
+	minRank       int
+	batchSize     int
+	flushInterval time.Duration
+	maxAttempts   int
+	timeout       time.Duration
+	retryDelay    time.Duration
+	queue         chan Event
+}
+
+// New creates the sink described by cfg and a Forwarder on top of it.
+func New(cfg Config, tenants TenantFilter) (*Forwarder, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("siem: no endpoint configured")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	var sink Sink
+	switch cfg.Provider {
+	case ProviderSyslog:
+		sink = newSyslogSink(cfg)
+	case ProviderSplunk:
+		sink = newSplunkSink(cfg)
+	case ProviderHTTPS:
+		sink = newHTTPSSink(cfg)
+	default:
+		return nil, fmt.Errorf("siem: unsupported provider %q", cfg.Provider)
+	}
+	return NewForwarder(sink, tenants, cfg), nil
+}
+
+// NewForwarder creates a Forwarder on top of an existing sink, using the
+// filtering and batching settings of cfg. Unset settings use the defaults.
+func NewForwarder(sink Sink, tenants TenantFilter, cfg Config) *Forwarder {
+	if !ValidSeverity(cfg.MinSeverity) {
+		cfg.MinSeverity = DefaultMinSeverity
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	return &Forwarder{
+		sink:          sink,
+		tenants:       tenants,
+		minRank:       severityRank[cfg.MinSeverity],
+		batchSize:     cfg.BatchSize,
+		flushInterval: cfg.FlushInterval,
+		maxAttempts:   cfg.MaxAttempts,
+		timeout:       cfg.Timeout,
+		retryDelay:    retryBaseDelay,
+		queue:         make(chan Event, queueSize),
+	}
+}
+
+// Forward queues event unless it is below the minimum severity. It never
+// blocks: when the queue is full the event is dropped.
+func (f *Forwarder) Forward(event security.SecurityEvent, location *security.GeoLocation) {
+	if severityRank[event.Severity] < f.minRank {
+		return
+	}
+	select {
+	case f.queue <- NewEvent(event, location):
+	default:
+		metrics.ObserveSIEMDrop(metrics.SIEMDropQueueFull, 1)
+	}
+}
+
+// Run sends queued events until ctx is cancelled, then sends what is left
+// within one timeout.
+func (f *Forwarder) Run(ctx context.Context) {
+	slog.Info("siem: starting security event forwarder",
+		"batch_size", f.batchSize,
+		"flush_interval", f.flushInterval.String(),
+	)
+
+	ticker := time.NewTicker(f.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Event, 0, f.batchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			batch = f.drain(batch)
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), f.timeout)
+			f.flush(shutdownCtx, batch)
+			cancel()
+			slog.Info("siem: shutting down")
+			return
+		case event := <-f.queue:
+			batch = append(batch, event)
+			if len(batch) >= f.batchSize {
+				f.flush(ctx, batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				f.flush(ctx, batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+// Close releases the sink's connection.
+func (f *Forwarder) Close() error {
+	return f.sink.Close()
+}
+
+// drain moves the events still queued into batch.
+func (f *Forwarder) drain(batch []Event) []Event {
+	for {
+		select {
+		case event := <-f.queue:
+			batch = append(batch, event)
+		default:
+			return batch
+		}
+	}
+}
+
+// flush sends the events of batch whose tenant forwards them.
+func (f *Forwarder) flush(ctx context.Context, batch []Event) {
+	start := time.Now()
+	events := f.filter(ctx, batch)
+	if len(events) == 0 {
+		return
+	}
+
+	err := f.send(ctx, events)
+	metrics.ObserveWorkerRun(Worker, int64(len(events)), err, time.Since(start))
+	if err != nil {
+		metrics.ObserveSIEMDrop(metrics.SIEMDropSendFailed, len(events))
+		slog.Error("siem: failed to forward security events", "count", len(events), "error", err)
+	}
+}
+
+// filter keeps the events whose tenant forwards them. Events whose tenant
+// cannot be checked are left out.
+func (f *Forwarder) filter(ctx context.Context, batch []Event) []Event {
+	events := make([]Event, 0, len(batch))
+	for _, event := range batch {
+		if f.tenants != nil {
+			forward, err := f.tenants.Forwards(ctx, event.TenantID, event.ClientID)
+			if err != nil {
+				metrics.ObserveSIEMDrop(metrics.SIEMDropTenantLookup, 1)
+				slog.Error("siem: failed to check tenant forwarding", "event_type", event.EventType, "error", err)
+				continue
+			}
+			if !forward {
+				continue
+			}
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// send delivers events, retrying with exponential backoff until the sink
+// accepts them, fails permanently or runs out of attempts.
+func (f *Forwarder) send(ctx context.Context, events []Event) error {
+	delay := f.retryDelay
+	for attempt := 1; ; attempt++ {
+		sendCtx, cancel := context.WithTimeout(ctx, f.timeout)
+		err := f.sink.Send(sendCtx, events)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if isPermanent(err) || attempt >= f.maxAttempts {
+			return err
+		}
+		slog.Warn("siem: send failed, retrying", "attempt", attempt, "error", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
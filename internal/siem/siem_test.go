@@ -0,0 +1,239 @@
+package siem
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maintainerd/auth/internal/security"
+)
+
+// fakeSink records the batches it accepts and fails the first failures sends
+// with err.
+type fakeSink struct {
+	mu       sync.Mutex
+	batches  [][]Event
+	attempts int
+	failures int
+	err      error
+	closed   bool
+}
+
+func (s *fakeSink) Send(_ context.Context, events []Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	if s.attempts <= s.failures {
+		return s.err
+	}
+	s.batches = append(s.batches, append([]Event(nil), events...))
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func (s *fakeSink) sent() [][]Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.batches
+}
+
+type fakeTenantFilter struct {
+	forwards map[int64]bool
+	err      error
+}
+
+func (f *fakeTenantFilter) Forwards(_ context.Context, tenantID int64, _ string) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+	return f.forwards[tenantID], nil
+}
+
+func securityEvent(eventType, severity string, tenantID int64) security.SecurityEvent {
+	return security.SecurityEvent{
+		EventType: eventType,
+		Severity:  severity,
+		TenantID:  tenantID,
+		ClientIP:  "203.0.113.7",
+		Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+// ---------------------------------------------------------------------------
+// New
+// ---------------------------------------------------------------------------
+
+func TestNew(t *testing.T) {
+	for _, provider := range []string{ProviderSyslog, ProviderSplunk, ProviderHTTPS} {
+		t.Run(provider, func(t *testing.T) {
+			f, err := New(Config{Provider: provider, Endpoint: "https://siem.example.com"}, nil)
+			require.NoError(t, err)
+			assert.NotNil(t, f)
+		})
+	}
+
+	_, err := New(Config{Provider: "kafka", Endpoint: "siem:9092"}, nil)
+	assert.Error(t, err)
+
+	_, err = New(Config{Provider: ProviderSplunk}, nil)
+	assert.Error(t, err)
+}
+
+func TestNewEvent_Location(t *testing.T) {
+	event := NewEvent(securityEvent("login_failure", SeverityMedium, 9), &security.GeoLocation{
+		Country: "DE", City: "Berlin", ASN: 3320, ASOrganization: "Deutsche Telekom AG",
+	})
+	assert.Equal(t, int64(9), event.TenantID)
+	assert.Equal(t, "DE", event.Country)
+	assert.Equal(t, "Berlin", event.City)
+	assert.Equal(t, uint(3320), event.ASN)
+	assert.Equal(t, "Deutsche Telekom AG", event.ASOrganization)
+
+	event = NewEvent(security.SecurityEvent{EventType: "x", Severity: SeverityLow}, nil)
+	assert.False(t, event.Timestamp.IsZero())
+}
+
+// ---------------------------------------------------------------------------
+// Forwarder
+// ---------------------------------------------------------------------------
+
+func TestForwarder_MinSeverity(t *testing.T) {
+	f := NewForwarder(&fakeSink{}, nil, Config{MinSeverity: SeverityMedium})
+
+	f.Forward(securityEvent("login_success", "INFO", 1), nil)
+	f.Forward(securityEvent("request_received", SeverityLow, 1), nil)
+	f.Forward(securityEvent("login_failure", SeverityMedium, 1), nil)
+	f.Forward(securityEvent("account_locked", SeverityHigh, 1), nil)
+
+	require.Len(t, f.queue, 2)
+	assert.Equal(t, "login_failure", (<-f.queue).EventType)
+	assert.Equal(t, "account_locked", (<-f.queue).EventType)
+}
+
+func TestForwarder_InvalidMinSeverityUsesDefault(t *testing.T) {
+	f := NewForwarder(&fakeSink{}, nil, Config{MinSeverity: "WARN"})
+	assert.Equal(t, severityRank[DefaultMinSeverity], f.minRank)
+	assert.Equal(t, DefaultBatchSize, f.batchSize)
+	assert.Equal(t, DefaultMaxAttempts, f.maxAttempts)
+}
+
+func TestForwarder_QueueFullDrops(t *testing.T) {
+	f := NewForwarder(&fakeSink{}, nil, Config{})
+	for i := 0; i < queueSize+5; i++ {
+		f.Forward(securityEvent("login_failure", SeverityHigh, 1), nil)
+	}
+	assert.Len(t, f.queue, queueSize)
+}
+
+func TestForwarder_RunBatches(t *testing.T) {
+	sink := &fakeSink{}
+	f := NewForwarder(sink, nil, Config{BatchSize: 2, FlushInterval: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		f.Run(ctx)
+		close(done)
+	}()
+
+	for i := 0; i < 3; i++ {
+		f.Forward(securityEvent("login_failure", SeverityHigh, 1), nil)
+	}
+	require.Eventually(t, func() bool { return len(sink.sent()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Len(t, sink.sent()[0], 2)
+
+	// The third event is sent when the forwarder shuts down.
+	cancel()
+	<-done
+	require.Len(t, sink.sent(), 2)
+	assert.Len(t, sink.sent()[1], 1)
+}
+
+func TestForwarder_RunFlushInterval(t *testing.T) {
+	sink := &fakeSink{}
+	f := NewForwarder(sink, nil, Config{BatchSize: 100, FlushInterval: 10 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go f.Run(ctx)
+
+	f.Forward(securityEvent("login_failure", SeverityHigh, 1), nil)
+	require.Eventually(t, func() bool { return len(sink.sent()) == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestForwarder_RetriesThenSucceeds(t *testing.T) {
+	sink := &fakeSink{failures: 2, err: errors.New("connection refused")}
+	f := NewForwarder(sink, nil, Config{MaxAttempts: 3})
+	f.retryDelay = time.Millisecond
+
+	f.flush(context.Background(), []Event{{EventType: "login_failure"}})
+
+	assert.Equal(t, 3, sink.attempts)
+	assert.Len(t, sink.sent(), 1)
+}
+
+func TestForwarder_GivesUpAfterMaxAttempts(t *testing.T) {
+	sink := &fakeSink{failures: 10, err: errors.New("connection refused")}
+	f := NewForwarder(sink, nil, Config{MaxAttempts: 3})
+	f.retryDelay = time.Millisecond
+
+	err := f.send(context.Background(), []Event{{EventType: "login_failure"}})
+
+	assert.Error(t, err)
+	assert.Equal(t, 3, sink.attempts)
+	assert.Empty(t, sink.sent())
+}
+
+func TestForwarder_PermanentErrorNotRetried(t *testing.T) {
+	sink := &fakeSink{failures: 10, err: Permanent(errors.New("invalid token"))}
+	f := NewForwarder(sink, nil, Config{MaxAttempts: 5})
+	f.retryDelay = time.Millisecond
+
+	err := f.send(context.Background(), []Event{{EventType: "login_failure"}})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, sink.attempts)
+}
+
+func TestForwarder_TenantFilter(t *testing.T) {
+	sink := &fakeSink{}
+	tenants := &fakeTenantFilter{forwards: map[int64]bool{1: true, 2: false}}
+	f := NewForwarder(sink, tenants, Config{})
+
+	f.flush(context.Background(), []Event{
+		{EventType: "a", TenantID: 1},
+		{EventType: "b", TenantID: 2},
+		{EventType: "c", TenantID: 1},
+	})
+
+	require.Len(t, sink.sent(), 1)
+	batch := sink.sent()[0]
+	require.Len(t, batch, 2)
+	assert.Equal(t, "a", batch[0].EventType)
+	assert.Equal(t, "c", batch[1].EventType)
+}
+
+func TestForwarder_TenantLookupErrorDrops(t *testing.T) {
+	sink := &fakeSink{}
+	f := NewForwarder(sink, &fakeTenantFilter{err: errors.New("db down")}, Config{})
+
+	f.flush(context.Background(), []Event{{EventType: "a", TenantID: 1}})
+
+	assert.Zero(t, sink.attempts)
+}
+
+func TestForwarder_Close(t *testing.T) {
+	sink := &fakeSink{}
+	f := NewForwarder(sink, nil, Config{})
+	require.NoError(t, f.Close())
+	assert.True(t, sink.closed)
+}
@@ -0,0 +1,161 @@
+package siem
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cefEvent() Event {
+	return Event{
+		EventType: "login_failure",
+		Severity:  SeverityMedium,
+		TenantID:  42,
+		UserID:    "user-1",
+		ClientID:  "client-1",
+		ClientIP:  "203.0.113.7",
+		RequestID: "req-1",
+		Endpoint:  "/api/v1/login",
+		Method:    "POST",
+		Details:   "invalid password",
+		Country:   "DE",
+		Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+// ---------------------------------------------------------------------------
+// FormatCEF
+// ---------------------------------------------------------------------------
+
+func TestFormatCEF(t *testing.T) {
+	got := FormatCEF(cefEvent(), "1.4.0")
+
+	assert.True(t, strings.HasPrefix(got, "CEF:0|maintainerd|auth|1.4.0|login_failure|login_failure|5|"), got)
+	for _, want := range []string{
+		"rt=1767323045000",
+		"src=203.0.113.7",
+		"suser=user-1",
+		"request=/api/v1/login",
+		"requestMethod=POST",
+		"msg=invalid password",
+		"cn1Label=tenantId cn1=42",
+		"cs1Label=clientId cs1=client-1",
+		"cs2Label=requestId cs2=req-1",
+		"cs3Label=country cs3=DE",
+	} {
+		assert.Contains(t, got, want)
+	}
+	assert.NotContains(t, got, "cn2Label")
+	assert.NotContains(t, got, "cs4Label")
+}
+
+func TestFormatCEF_Severity(t *testing.T) {
+	tests := map[string]string{
+		"INFO":         "|3|",
+		SeverityLow:    "|3|",
+		SeverityMedium: "|5|",
+		SeverityHigh:   "|8|",
+	}
+	for severity, want := range tests {
+		t.Run(severity, func(t *testing.T) {
+			assert.Contains(t, FormatCEF(Event{EventType: "x", Severity: severity}, "1"), want)
+		})
+	}
+}
+
+func TestFormatCEF_Escaping(t *testing.T) {
+	event := Event{
+		EventType: `bad|type\x`,
+		Severity:  SeverityHigh,
+		Details:   "a=b\\c\nd",
+	}
+	got := FormatCEF(event, "")
+
+	assert.Contains(t, got, `|unknown|bad\|type\\x|bad\|type\\x|8|`)
+	assert.Contains(t, got, `msg=a\=b\\c\nd`)
+	assert.NotContains(t, got, "\n")
+}
+
+// ---------------------------------------------------------------------------
+// syslogSink
+// ---------------------------------------------------------------------------
+
+func TestSyslogSink_Message(t *testing.T) {
+	sink := &syslogSink{hostname: "auth-1", version: "1.4.0"}
+	got := sink.message(cefEvent())
+
+	// authpriv (10) * 8 + warning (4)
+	assert.True(t, strings.HasPrefix(got,
+		"<84>1 2026-01-02T03:04:05Z auth-1 maintainerd-auth - security_event - CEF:0|"), got)
+}
+
+func TestSyslogSink_SendTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	lines := make(chan string, 2)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	sink := newSyslogSink(Config{Endpoint: ln.Addr().String(), SyslogNetwork: SyslogTCP, Timeout: time.Second})
+	defer sink.Close()
+
+	first, second := cefEvent(), cefEvent()
+	second.EventType = "account_locked"
+	require.NoError(t, sink.Send(context.Background(), []Event{first, second}))
+
+	for _, want := range []string{"login_failure", "account_locked"} {
+		select {
+		case line := <-lines:
+			assert.Contains(t, line, "|"+want+"|")
+		case <-time.After(time.Second):
+			t.Fatalf("no syslog message for %s", want)
+		}
+	}
+}
+
+func TestSyslogSink_SendUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sink := newSyslogSink(Config{Endpoint: conn.LocalAddr().String(), SyslogNetwork: SyslogUDP, Timeout: time.Second})
+	defer sink.Close()
+	require.NoError(t, sink.Send(context.Background(), []Event{cefEvent()}))
+
+	buf := make([]byte, 4096)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	msg := string(buf[:n])
+	assert.Contains(t, msg, "CEF:0|maintainerd|auth|")
+	assert.False(t, strings.HasSuffix(msg, "\n"))
+}
+
+func TestSyslogSink_DialError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	sink := newSyslogSink(Config{Endpoint: addr, Timeout: time.Second})
+	err = sink.Send(context.Background(), []Event{cefEvent()})
+	assert.Error(t, err)
+	assert.False(t, isPermanent(err))
+}
@@ -0,0 +1,72 @@
+package signupflow
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxMinimumAge bounds age_gate.minimum_age.
+const maxMinimumAge = 120
+
+// AgeGate requires a birthdate at registration and turns away users younger
+// than MinimumAge, or holds their account until a guardian consents.
+type AgeGate struct {
+	// MinimumAge is the age, in full years, a user needs to register on
+	// their own.
+	MinimumAge int `json:"minimum_age"`
+	// ParentalConsent lets younger users register with a guardian's email
+	// address. The account stays inactive until the guardian approves.
+	ParentalConsent bool `json:"parental_consent,omitempty"`
+}
+
+// Validate checks the age gate definition.
+func (g AgeGate) Validate() error {
+	if g.MinimumAge < 1 || g.MinimumAge > maxMinimumAge {
+		return fmt.Errorf("age_gate.minimum_age must be between 1 and %d", maxMinimumAge)
+	}
+	return nil
+}
+
+// Underage reports whether someone born on birthdate is younger than the
+// minimum age at now.
+func (g AgeGate) Underage(birthdate, now time.Time) bool {
+	return Age(birthdate, now) < g.MinimumAge
+}
+
+// check returns a user-facing error when in does not pass the gate at now.
+func (g AgeGate) check(in Input, now time.Time) error {
+	if in.Birthdate == nil {
+		return errors.New("birthdate is required")
+	}
+	if in.Birthdate.After(now) {
+		return errors.New("birthdate must be in the past")
+	}
+	if !g.Underage(*in.Birthdate, now) {
+		return nil
+	}
+	if !g.ParentalConsent {
+		return fmt.Errorf("you must be at least %d years old to register", g.MinimumAge)
+	}
+	if strings.TrimSpace(in.GuardianEmail) == "" {
+		return fmt.Errorf("guardian_email is required for users under %d", g.MinimumAge)
+	}
+	if strings.EqualFold(in.GuardianEmail, in.Email) {
+		return errors.New("guardian_email must differ from email")
+	}
+	return nil
+}
+
+// Age returns the full years between birthdate and now, by calendar date.
+// Someone born on February 29 turns a year older on March 1 in common
+// years.
+func Age(birthdate, now time.Time) int {
+	by, bm, bd := birthdate.Date()
+	ny, nm, nd := now.Date()
+	age := ny - by
+	if nm < bm || (nm == bm && nd < bd) {
+		age--
+	}
+	return age
+}
@@ -0,0 +1,132 @@
+package signupflow
+
+import (
+	"errors"
+	"time"
+)
+
+// Cap names, as reported in cap errors and events.
+const (
+	CapTotal = "total"
+	CapDaily = "daily"
+)
+
+// DefaultCapWarnPercent is the share of a cap at which a warning is raised
+// when the flow does not set its own.
+const DefaultCapWarnPercent = 80
+
+// Cap threshold kinds.
+const (
+	ThresholdWarning = "warning"
+	ThresholdReached = "reached"
+)
+
+// Caps limits how many accounts a flow creates. A zero limit is unlimited.
+type Caps struct {
+	// MaxSignups caps the accounts ever created through the flow.
+	MaxSignups int `json:"max_signups,omitempty"`
+	// MaxSignupsPerDay caps the accounts created per UTC calendar day.
+	MaxSignupsPerDay int `json:"max_signups_per_day,omitempty"`
+	// WarnPercent is the share of a cap, in percent, at which a warning
+	// event is emitted. Defaults to DefaultCapWarnPercent.
+	WarnPercent int `json:"warn_percent,omitempty"`
+	// IncludeInvites counts registrations through an invitation for the
+	// flow's client, and rejects them once a cap is reached.
+	IncludeInvites bool `json:"include_invites,omitempty"`
+}
+
+// Usage is how many accounts a flow has created so far.
+type Usage struct {
+	Total int64
+	Today int64
+}
+
+// CapError reports a registration rejected by a cap.
+type CapError struct {
+	Cap   string
+	Limit int
+}
+
+func (e *CapError) Error() string {
+	if e.Cap == CapDaily {
+		return "daily signup limit reached for this registration, try again tomorrow"
+	}
+	return "signup limit reached for this registration"
+}
+
+// Threshold is a cap level crossed by a new signup.
+type Threshold struct {
+	Cap   string
+	Kind  string
+	Limit int
+	Count int64
+}
+
+// DayStart returns the start of the UTC day of t, from which daily signups
+// are counted.
+func DayStart(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// Validate checks the caps definition.
+func (c Caps) Validate() error {
+	if c.MaxSignups < 0 || c.MaxSignupsPerDay < 0 {
+		return errors.New("caps: limits must not be negative")
+	}
+	if c.WarnPercent < 0 || c.WarnPercent > 100 {
+		return errors.New("caps.warn_percent must be between 1 and 100")
+	}
+	return nil
+}
+
+// Limited reports whether any cap is set.
+func (c Caps) Limited() bool {
+	return c.MaxSignups > 0 || c.MaxSignupsPerDay > 0
+}
+
+// Check returns a *CapError when one more signup would exceed a cap, given
+// the usage before it.
+func (c Caps) Check(u Usage) error {
+	if c.MaxSignups > 0 && u.Total >= int64(c.MaxSignups) {
+		return &CapError{Cap: CapTotal, Limit: c.MaxSignups}
+	}
+	if c.MaxSignupsPerDay > 0 && u.Today >= int64(c.MaxSignupsPerDay) {
+		return &CapError{Cap: CapDaily, Limit: c.MaxSignupsPerDay}
+	}
+	return nil
+}
+
+// Crossed returns the thresholds that one more signup crosses, given the
+// usage before it. Each threshold is crossed by exactly one signup, so a
+// flow raises every warning once per cap (once per day for the daily cap).
+func (c Caps) Crossed(u Usage) []Threshold {
+	var crossed []Threshold
+	for _, lim := range []struct {
+		name  string
+		limit int
+		count int64
+	}{
+		{CapTotal, c.MaxSignups, u.Total + 1},
+		{CapDaily, c.MaxSignupsPerDay, u.Today + 1},
+	} {
+		if lim.limit <= 0 {
+			continue
+		}
+		if lim.count == int64(lim.limit) {
+			crossed = append(crossed, Threshold{Cap: lim.name, Kind: ThresholdReached, Limit: lim.limit, Count: lim.count})
+		} else if lim.count == c.warnAt(lim.limit) {
+			crossed = append(crossed, Threshold{Cap: lim.name, Kind: ThresholdWarning, Limit: lim.limit, Count: lim.count})
+		}
+	}
+	return crossed
+}
+
+// warnAt is the signup count at which the warning for limit is raised,
+// rounded up so that it is never below the configured share.
+func (c Caps) warnAt(limit int) int64 {
+	pct := c.WarnPercent
+	if pct == 0 {
+		pct = DefaultCapWarnPercent
+	}
+	return int64((limit*pct + 99) / 100)
+}
@@ -0,0 +1,86 @@
+package signupflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// verifyTimeout bounds a CAPTCHA verification call.
+const verifyTimeout = 5 * time.Second
+
+// verifyURLs are the providers' server-side verification endpoints. All three
+// accept the same form-encoded request and answer with {"success": bool}.
+var verifyURLs = map[string]string{
+	ProviderTurnstile: "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+	ProviderHCaptcha:  "https://api.hcaptcha.com/siteverify",
+	ProviderReCaptcha: "https://www.google.com/recaptcha/api/siteverify",
+}
+
+// CaptchaVerifier verifies a CAPTCHA token solved by the registering user.
+type CaptchaVerifier interface {
+	Verify(ctx context.Context, captcha Captcha, token, remoteIP string) (bool, error)
+}
+
+// HTTPCaptchaVerifier verifies tokens against the provider's siteverify API.
+type HTTPCaptchaVerifier struct {
+	client *http.Client
+	urls   map[string]string
+}
+
+// NewCaptchaVerifier creates a verifier using client for outbound calls. A
+// nil client uses a default client with a short timeout.
+func NewCaptchaVerifier(client *http.Client) *HTTPCaptchaVerifier {
+	if client == nil {
+		client = &http.Client{Timeout: verifyTimeout}
+	}
+	return &HTTPCaptchaVerifier{client: client, urls: verifyURLs}
+}
+
+type verifyResponse struct {
+	Success bool `json:"success"`
+}
+
+// Verify implements CaptchaVerifier.
+func (v *HTTPCaptchaVerifier) Verify(ctx context.Context, captcha Captcha, token, remoteIP string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+	endpoint, ok := v.urls[captcha.Provider]
+	if !ok {
+		return false, fmt.Errorf("unknown captcha provider %q", captcha.Provider)
+	}
+
+	form := url.Values{"secret": {captcha.SecretKey}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, verifyTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := v.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("captcha provider returned status %d", res.StatusCode)
+	}
+	var out verifyResponse
+	if err := json.NewDecoder(io.LimitReader(res.Body, 64<<10)).Decode(&out); err != nil {
+		return false, fmt.Errorf("invalid captcha response: %w", err)
+	}
+	return out.Success, nil
+}
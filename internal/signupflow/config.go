@@ -0,0 +1,178 @@
+// Package signupflow interprets the config of a signup flow at registration
+// time. A flow belongs to one auth client and decides which profile fields
+// are required, which email domains may register, whether a CAPTCHA must be
+// solved, whether the email address has to be verified before the account
+// becomes active, how old users must be and how many accounts the flow may
+// create.
+package signupflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Fields that a flow can mark as required.
+const (
+	FieldEmail    = "email"
+	FieldPhone    = "phone"
+	FieldFullname = "fullname"
+)
+
+// CAPTCHA providers.
+const (
+	ProviderTurnstile = "turnstile"
+	ProviderHCaptcha  = "hcaptcha"
+	ProviderReCaptcha = "recaptcha"
+)
+
+var knownFields = map[string]bool{
+	FieldEmail:    true,
+	FieldPhone:    true,
+	FieldFullname: true,
+}
+
+// Config is the runtime view of a signup flow's JSON config. Unknown keys are
+// ignored so flows can carry UI-only settings.
+type Config struct {
+	// RequiredFields lists the optional registration fields that this flow
+	// requires (email, phone, fullname).
+	RequiredFields []string `json:"required_fields,omitempty"`
+	// AllowedEmailDomains restricts registration to these email domains.
+	// Setting it implies the email field is required.
+	AllowedEmailDomains []string `json:"allowed_email_domains,omitempty"`
+	// EmailVerification creates the account as pending until the emailed
+	// verification code is confirmed. Implies the email field is required.
+	EmailVerification bool `json:"email_verification,omitempty"`
+	// Captcha requires a solved CAPTCHA token with the registration.
+	Captcha *Captcha `json:"captcha,omitempty"`
+	// Caps limits the number of accounts created through the flow.
+	Caps *Caps `json:"caps,omitempty"`
+	// AgeGate enforces a minimum age, optionally with parental consent for
+	// younger users.
+	AgeGate *AgeGate `json:"age_gate,omitempty"`
+}
+
+// Captcha configures server-side CAPTCHA verification.
+type Captcha struct {
+	Provider  string `json:"provider"`
+	SecretKey string `json:"secret_key"`
+}
+
+// Input is the registration data checked against a flow.
+type Input struct {
+	Fullname string
+	Email    string
+	Phone    string
+	// Birthdate and GuardianEmail are only checked by flows with an age
+	// gate.
+	Birthdate     *time.Time
+	GuardianEmail string
+}
+
+// Parse decodes and validates a flow config. An empty config yields the zero
+// Config, which imposes no extra rules.
+func Parse(raw []byte) (Config, error) {
+	var cfg Config
+	if len(raw) == 0 {
+		return cfg, nil
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid signup flow config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate checks the config definition.
+func (c Config) Validate() error {
+	for _, f := range c.RequiredFields {
+		if !knownFields[f] {
+			return fmt.Errorf("required_fields: unknown field %q", f)
+		}
+	}
+	for _, d := range c.AllowedEmailDomains {
+		if d == "" || strings.ContainsAny(d, "@ ") {
+			return fmt.Errorf("allowed_email_domains: invalid domain %q", d)
+		}
+	}
+	if c.Captcha != nil {
+		if _, ok := verifyURLs[c.Captcha.Provider]; !ok {
+			return errors.New("captcha.provider must be one of turnstile, hcaptcha, recaptcha")
+		}
+		if c.Captcha.SecretKey == "" {
+			return errors.New("captcha.secret_key is required")
+		}
+	}
+	if c.Caps != nil {
+		if err := c.Caps.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.AgeGate != nil {
+		if err := c.AgeGate.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Requires reports whether field must be provided at registration.
+func (c Config) Requires(field string) bool {
+	if field == FieldEmail && (c.EmailVerification || len(c.AllowedEmailDomains) > 0) {
+		return true
+	}
+	for _, f := range c.RequiredFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns a user-facing error when in does not satisfy the flow.
+func (c Config) Check(in Input) error {
+	values := map[string]string{
+		FieldEmail:    in.Email,
+		FieldPhone:    in.Phone,
+		FieldFullname: in.Fullname,
+	}
+	for _, field := range []string{FieldEmail, FieldPhone, FieldFullname} {
+		if c.Requires(field) && strings.TrimSpace(values[field]) == "" {
+			return fmt.Errorf("%s is required", field)
+		}
+	}
+
+	if len(c.AllowedEmailDomains) > 0 {
+		at := strings.LastIndex(in.Email, "@")
+		domain := strings.ToLower(in.Email[at+1:])
+		allowed := false
+		for _, d := range c.AllowedEmailDomains {
+			if strings.EqualFold(d, domain) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return errors.New("email domain is not allowed for this registration")
+		}
+	}
+
+	if c.AgeGate != nil {
+		if err := c.AgeGate.check(in, time.Now()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NeedsParentalConsent reports whether an account registered with in has to
+// wait for a guardian's consent. Check must have passed for in.
+func (c Config) NeedsParentalConsent(in Input) bool {
+	return c.AgeGate != nil && c.AgeGate.ParentalConsent &&
+		in.Birthdate != nil && c.AgeGate.Underage(*in.Birthdate, time.Now())
+}
@@ -0,0 +1,224 @@
+package signupflow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// Config
+// ---------------------------------------------------------------------------
+
+func TestParse(t *testing.T) {
+	t.Run("empty config", func(t *testing.T) {
+		cfg, err := Parse(nil)
+		require.NoError(t, err)
+		assert.Equal(t, Config{}, cfg)
+	})
+
+	t.Run("unknown keys are ignored", func(t *testing.T) {
+		cfg, err := Parse([]byte(`{"theme":"dark","required_fields":["phone"]}`))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"phone"}, cfg.RequiredFields)
+	})
+
+	t.Run("full config", func(t *testing.T) {
+		cfg, err := Parse([]byte(`{
+			"required_fields": ["fullname"],
+			"allowed_email_domains": ["acme.com"],
+			"email_verification": true,
+			"captcha": {"provider": "turnstile", "secret_key": "s3cret"}
+		}`))
+		require.NoError(t, err)
+		assert.True(t, cfg.EmailVerification)
+		assert.Equal(t, ProviderTurnstile, cfg.Captcha.Provider)
+	})
+
+	invalid := map[string]string{
+		"malformed":        `{"required_fields":"email"}`,
+		"unknown field":    `{"required_fields":["nickname"]}`,
+		"bad domain":       `{"allowed_email_domains":["user@acme.com"]}`,
+		"unknown provider": `{"captcha":{"provider":"other","secret_key":"x"}}`,
+		"missing secret":   `{"captcha":{"provider":"hcaptcha"}}`,
+		"negative cap":     `{"caps":{"max_signups":-1}}`,
+		"warn over 100":    `{"caps":{"max_signups":10,"warn_percent":120}}`,
+		"age gate no age":  `{"age_gate":{"parental_consent":true}}`,
+		"age gate too old": `{"age_gate":{"minimum_age":200}}`,
+	}
+	for name, raw := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(raw))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestConfig_Check(t *testing.T) {
+	t.Run("no rules", func(t *testing.T) {
+		assert.NoError(t, Config{}.Check(Input{}))
+	})
+
+	t.Run("required fields", func(t *testing.T) {
+		cfg := Config{RequiredFields: []string{FieldPhone, FieldFullname}}
+		assert.EqualError(t, cfg.Check(Input{Fullname: "Jane"}), "phone is required")
+		assert.EqualError(t, cfg.Check(Input{Phone: "+15550100", Fullname: " "}), "fullname is required")
+		assert.NoError(t, cfg.Check(Input{Phone: "+15550100", Fullname: "Jane"}))
+	})
+
+	t.Run("email verification requires email", func(t *testing.T) {
+		cfg := Config{EmailVerification: true}
+		assert.EqualError(t, cfg.Check(Input{}), "email is required")
+		assert.NoError(t, cfg.Check(Input{Email: "jane@example.com"}))
+	})
+
+	t.Run("allowed email domains", func(t *testing.T) {
+		cfg := Config{AllowedEmailDomains: []string{"acme.com"}}
+		assert.EqualError(t, cfg.Check(Input{}), "email is required")
+		assert.Error(t, cfg.Check(Input{Email: "jane@example.com"}))
+		assert.Error(t, cfg.Check(Input{Email: "jane@sub.acme.com"}))
+		assert.NoError(t, cfg.Check(Input{Email: "jane@ACME.com"}))
+	})
+
+	t.Run("age gate", func(t *testing.T) {
+		adult := time.Now().AddDate(-30, 0, 0)
+		child := time.Now().AddDate(-10, 0, 0)
+		future := time.Now().AddDate(0, 0, 2)
+
+		cfg := Config{AgeGate: &AgeGate{MinimumAge: 13}}
+		assert.EqualError(t, cfg.Check(Input{}), "birthdate is required")
+		assert.EqualError(t, cfg.Check(Input{Birthdate: &future}), "birthdate must be in the past")
+		assert.EqualError(t, cfg.Check(Input{Birthdate: &child}), "you must be at least 13 years old to register")
+		assert.NoError(t, cfg.Check(Input{Birthdate: &adult}))
+		assert.False(t, cfg.NeedsParentalConsent(Input{Birthdate: &child}))
+
+		cfg.AgeGate.ParentalConsent = true
+		assert.EqualError(t, cfg.Check(Input{Birthdate: &child}), "guardian_email is required for users under 13")
+		assert.EqualError(t, cfg.Check(Input{Birthdate: &child, Email: "kid@example.com", GuardianEmail: "KID@example.com"}),
+			"guardian_email must differ from email")
+		in := Input{Birthdate: &child, Email: "kid@example.com", GuardianEmail: "parent@example.com"}
+		assert.NoError(t, cfg.Check(in))
+		assert.True(t, cfg.NeedsParentalConsent(in))
+		assert.False(t, cfg.NeedsParentalConsent(Input{Birthdate: &adult}))
+	})
+}
+
+func TestAge(t *testing.T) {
+	born := time.Date(2008, time.February, 29, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 17, Age(born, time.Date(2026, time.February, 28, 12, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 18, Age(born, time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0, Age(born, born))
+}
+
+// ---------------------------------------------------------------------------
+// Caps
+// ---------------------------------------------------------------------------
+
+func TestCaps_Check(t *testing.T) {
+	caps := Caps{MaxSignups: 100, MaxSignupsPerDay: 10}
+	assert.NoError(t, caps.Check(Usage{Total: 99, Today: 9}))
+
+	var capErr *CapError
+	require.ErrorAs(t, caps.Check(Usage{Total: 100, Today: 0}), &capErr)
+	assert.Equal(t, CapTotal, capErr.Cap)
+	assert.Equal(t, 100, capErr.Limit)
+
+	require.ErrorAs(t, caps.Check(Usage{Total: 50, Today: 10}), &capErr)
+	assert.Equal(t, CapDaily, capErr.Cap)
+	assert.Contains(t, capErr.Error(), "tomorrow")
+
+	assert.NoError(t, Caps{}.Check(Usage{Total: 1 << 40}))
+	assert.False(t, Caps{IncludeInvites: true}.Limited())
+}
+
+func TestCaps_Crossed(t *testing.T) {
+	caps := Caps{MaxSignups: 10, MaxSignupsPerDay: 5}
+
+	assert.Empty(t, caps.Crossed(Usage{Total: 2, Today: 1}))
+	assert.Equal(t, []Threshold{
+		{Cap: CapTotal, Kind: ThresholdWarning, Limit: 10, Count: 8},
+	}, caps.Crossed(Usage{Total: 7, Today: 0}))
+	assert.Equal(t, []Threshold{
+		{Cap: CapTotal, Kind: ThresholdReached, Limit: 10, Count: 10},
+		{Cap: CapDaily, Kind: ThresholdWarning, Limit: 5, Count: 4},
+	}, caps.Crossed(Usage{Total: 9, Today: 3}))
+	assert.Empty(t, caps.Crossed(Usage{Total: 8, Today: 0}), "past the warning, not yet full")
+
+	// The warning rounds up and never replaces the reached threshold.
+	assert.Equal(t, []Threshold{
+		{Cap: CapTotal, Kind: ThresholdWarning, Limit: 3, Count: 2},
+	}, Caps{MaxSignups: 3, WarnPercent: 50}.Crossed(Usage{Total: 1}))
+	assert.Equal(t, []Threshold{
+		{Cap: CapTotal, Kind: ThresholdReached, Limit: 1, Count: 1},
+	}, Caps{MaxSignups: 1}.Crossed(Usage{}))
+}
+
+func TestDayStart(t *testing.T) {
+	at := time.Date(2026, 3, 1, 23, 30, 0, 0, time.FixedZone("UTC-5", -5*3600))
+	assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), DayStart(at))
+}
+
+// ---------------------------------------------------------------------------
+// CAPTCHA
+// ---------------------------------------------------------------------------
+
+func captchaServer(t *testing.T, status int, body string) *HTTPCaptchaVerifier {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "s3cret", r.PostForm.Get("secret"))
+		assert.Equal(t, "tok", r.PostForm.Get("response"))
+		assert.Equal(t, "203.0.113.7", r.PostForm.Get("remoteip"))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	v := NewCaptchaVerifier(nil)
+	v.urls = map[string]string{ProviderHCaptcha: srv.URL}
+	return v
+}
+
+var testCaptcha = Captcha{Provider: ProviderHCaptcha, SecretKey: "s3cret"}
+
+func TestHTTPCaptchaVerifier(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		ok, err := captchaServer(t, http.StatusOK, `{"success":true}`).Verify(ctx, testCaptcha, "tok", "203.0.113.7")
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		ok, err := captchaServer(t, http.StatusOK, `{"success":false,"error-codes":["invalid-input-response"]}`).Verify(ctx, testCaptcha, "tok", "203.0.113.7")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		_, err := captchaServer(t, http.StatusBadGateway, ``).Verify(ctx, testCaptcha, "tok", "203.0.113.7")
+		assert.Error(t, err)
+	})
+
+	t.Run("malformed response", func(t *testing.T) {
+		_, err := captchaServer(t, http.StatusOK, `nope`).Verify(ctx, testCaptcha, "tok", "203.0.113.7")
+		assert.Error(t, err)
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		ok, err := NewCaptchaVerifier(nil).Verify(ctx, testCaptcha, "", "")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		_, err := NewCaptchaVerifier(nil).Verify(ctx, Captcha{Provider: "other"}, "tok", "")
+		assert.Error(t, err)
+	})
+}
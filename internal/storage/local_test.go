@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLocalStore(t *testing.T) *LocalStore {
+	t.Helper()
+	t.Setenv("HMAC_SECRET_KEY", "test-secret")
+	s, err := NewLocalStore(t.TempDir(), "https://auth.example.com/api/v1/media/")
+	require.NoError(t, err)
+	return s
+}
+
+func TestLocalStore_PutOpenDelete(t *testing.T) {
+	s := newTestLocalStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.Put(ctx, "profiles/a/b.png", "image/png", []byte("one")))
+	require.NoError(t, s.Put(ctx, "profiles/a/b.png", "image/png", []byte("two")))
+
+	f, err := s.Open("profiles/a/b.png")
+	require.NoError(t, err)
+	data, err := io.ReadAll(f)
+	f.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "two", string(data))
+
+	require.NoError(t, s.Delete(ctx, "profiles/a/b.png"))
+	_, err = s.Open("profiles/a/b.png")
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	// Deleting a missing object is not an error.
+	assert.NoError(t, s.Delete(ctx, "profiles/a/b.png"))
+}
+
+func TestLocalStore_InvalidKeys(t *testing.T) {
+	s := newTestLocalStore(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"", "../escape", "a/../../b", "/abs", "a//b", `a\b`, "a?b"} {
+		assert.Error(t, s.Put(ctx, key, "image/png", []byte("x")), key)
+		_, err := s.Open(key)
+		assert.Error(t, err, key)
+		_, err = s.SignedURL(ctx, key, time.Minute)
+		assert.Error(t, err, key)
+	}
+}
+
+func TestLocalStore_SignedURL(t *testing.T) {
+	s := newTestLocalStore(t)
+	ctx := context.Background()
+
+	signed, err := s.SignedURL(ctx, "profiles/a/b.png", time.Minute)
+	require.NoError(t, err)
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "auth.example.com", u.Host)
+	assert.Equal(t, "/api/v1/media", u.Path)
+
+	key, err := s.Verify(u.Query())
+	require.NoError(t, err)
+	assert.Equal(t, "profiles/a/b.png", key)
+
+	t.Run("tampered key is rejected", func(t *testing.T) {
+		q := u.Query()
+		q.Set(LocalKeyParam, "profiles/a/c.png")
+		_, err := s.Verify(q)
+		assert.Error(t, err)
+	})
+
+	t.Run("expired link is rejected", func(t *testing.T) {
+		expired, err := s.SignedURL(ctx, "profiles/a/b.png", -time.Minute)
+		require.NoError(t, err)
+		eu, err := url.Parse(expired)
+		require.NoError(t, err)
+		_, err = s.Verify(eu.Query())
+		assert.Error(t, err)
+	})
+}
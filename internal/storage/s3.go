@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty body.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// maxPresignTTL is the longest lifetime S3 accepts for a presigned URL.
+const maxPresignTTL = 7 * 24 * time.Hour
+
+// s3Store talks to the S3 REST API with SigV4-signed requests. Google Cloud
+// Storage serves the same API, signed with an HMAC key, at
+// DefaultGCSEndpoint.
+type s3Store struct {
+	client      *http.Client
+	signer      *v4.Signer
+	credentials aws.CredentialsProvider
+	bucket      string
+	region      string
+	endpoint    *url.URL
+	pathStyle   bool
+	now         func() time.Time
+}
+
+func newS3Store(ctx context.Context, cfg Config) (*s3Store, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("storage: bucket is required")
+	}
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("storage: region is required")
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+	}
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("storage: invalid endpoint %q", endpoint)
+	}
+
+	var credentials aws.CredentialsProvider
+	if cfg.AccessKeyID != "" {
+		static := aws.Credentials{AccessKeyID: cfg.AccessKeyID, SecretAccessKey: cfg.SecretAccessKey, Source: "storage"}
+		credentials = aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return static, nil
+		})
+	} else {
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.Region))
+		if err != nil {
+			return nil, fmt.Errorf("storage: load AWS config: %w", err)
+		}
+		credentials = awsCfg.Credentials
+	}
+
+	return &s3Store{
+		client: &http.Client{Timeout: 30 * time.Second},
+		signer: v4.NewSigner(func(o *v4.SignerOptions) {
+			// S3 signs the path as sent, without escaping it twice.
+			o.DisableURIPathEscaping = true
+		}),
+		credentials: credentials,
+		bucket:      cfg.Bucket,
+		region:      cfg.Region,
+		endpoint:    u,
+		pathStyle:   cfg.PathStyle,
+		now:         time.Now,
+	}, nil
+}
+
+// Put implements Store.
+func (s *s3Store) Put(ctx context.Context, key, contentType string, body []byte) error {
+	req, err := s.request(ctx, http.MethodPut, key, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(body))
+	req.Header.Set("Content-Type", contentType)
+
+	sum := sha256.Sum256(body)
+	return s.do(req, hex.EncodeToString(sum[:]))
+}
+
+// Delete implements Store. S3 answers 204 for missing objects too.
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	req, err := s.request(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	return s.do(req, emptyPayloadHash)
+}
+
+// SignedURL implements Store with a presigned GET.
+func (s *s3Store) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if ttl > maxPresignTTL {
+		ttl = maxPresignTTL
+	}
+	req, err := s.request(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return "", err
+	}
+	query := req.URL.Query()
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(ttl/time.Second), 10))
+	req.URL.RawQuery = query.Encode()
+
+	creds, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("storage: retrieve credentials: %w", err)
+	}
+	signed, _, err := s.signer.PresignHTTP(ctx, creds, req, "UNSIGNED-PAYLOAD", "s3", s.region, s.now())
+	if err != nil {
+		return "", fmt.Errorf("storage: presign %s: %w", key, err)
+	}
+	return signed, nil
+}
+
+// request builds an unsigned request for the object.
+func (s *s3Store) request(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	if !validKey(key) {
+		return nil, fmt.Errorf("storage: invalid key %q", key)
+	}
+	u := *s.endpoint
+	if s.pathStyle {
+		u.Path = u.Path + "/" + s.bucket + "/" + key
+	} else {
+		u.Host = s.bucket + "." + u.Host
+		u.Path = u.Path + "/" + key
+	}
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+// do signs and sends req, turning non-2xx answers into errors.
+func (s *s3Store) do(req *http.Request, payloadHash string) error {
+	creds, err := s.credentials.Retrieve(req.Context())
+	if err != nil {
+		return fmt.Errorf("storage: retrieve credentials: %w", err)
+	}
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if err := s.signer.SignHTTP(req.Context(), creds, req, payloadHash, "s3", s.region, s.now()); err != nil {
+		return fmt.Errorf("storage: sign %s %s: %w", req.Method, req.URL.Path, err)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("storage: %s %s: %w", req.Method, req.URL.Path, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("storage: %s %s: unexpected status %d: %s", req.Method, req.URL.Path, res.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	return nil
+}
@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedRequest struct {
+	method, path, contentType, auth, payloadHash string
+	body                                         []byte
+}
+
+func newTestS3Store(t *testing.T, status int) (Store, *[]recordedRequest) {
+	t.Helper()
+	var (
+		mu       sync.Mutex
+		requests []recordedRequest
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, recordedRequest{
+			method:      r.Method,
+			path:        r.URL.Path,
+			contentType: r.Header.Get("Content-Type"),
+			auth:        r.Header.Get("Authorization"),
+			payloadHash: r.Header.Get("X-Amz-Content-Sha256"),
+			body:        body,
+		})
+		mu.Unlock()
+		w.WriteHeader(status)
+		if status >= 300 {
+			_, _ = io.WriteString(w, "<Error><Code>AccessDenied</Code></Error>")
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	s, err := New(context.Background(), Config{
+		Provider:        ProviderS3,
+		Bucket:          "pictures",
+		Region:          "eu-west-1",
+		Endpoint:        srv.URL,
+		PathStyle:       true,
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+	})
+	require.NoError(t, err)
+	return s, &requests
+}
+
+func TestS3Store_PutDelete(t *testing.T) {
+	s, requests := newTestS3Store(t, http.StatusOK)
+	ctx := context.Background()
+
+	require.NoError(t, s.Put(ctx, "profiles/a/b.png", "image/png", []byte("png")))
+	require.NoError(t, s.Delete(ctx, "profiles/a/b.png"))
+
+	require.Len(t, *requests, 2)
+	put, del := (*requests)[0], (*requests)[1]
+
+	assert.Equal(t, http.MethodPut, put.method)
+	assert.Equal(t, "/pictures/profiles/a/b.png", put.path)
+	assert.Equal(t, "image/png", put.contentType)
+	assert.Equal(t, "png", string(put.body))
+	assert.True(t, strings.HasPrefix(put.auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"), put.auth)
+	assert.Contains(t, put.auth, "/eu-west-1/s3/aws4_request")
+	assert.NotEqual(t, emptyPayloadHash, put.payloadHash)
+
+	assert.Equal(t, http.MethodDelete, del.method)
+	assert.Equal(t, "/pictures/profiles/a/b.png", del.path)
+	assert.Equal(t, emptyPayloadHash, del.payloadHash)
+}
+
+func TestS3Store_ErrorStatus(t *testing.T) {
+	s, _ := newTestS3Store(t, http.StatusForbidden)
+
+	err := s.Put(context.Background(), "profiles/a/b.png", "image/png", []byte("png"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+	assert.Contains(t, err.Error(), "AccessDenied")
+}
+
+func TestS3Store_SignedURL(t *testing.T) {
+	s, requests := newTestS3Store(t, http.StatusOK)
+
+	signed, err := s.SignedURL(context.Background(), "profiles/a/b.png", 30*24*time.Hour)
+	require.NoError(t, err)
+	assert.Empty(t, *requests, "presigning must not call the service")
+
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "/pictures/profiles/a/b.png", u.Path)
+	q := u.Query()
+	assert.Equal(t, "AWS4-HMAC-SHA256", q.Get("X-Amz-Algorithm"))
+	assert.Equal(t, "604800", q.Get("X-Amz-Expires"), "ttl is capped at seven days")
+	assert.True(t, strings.HasPrefix(q.Get("X-Amz-Credential"), "AKIDEXAMPLE/"))
+	assert.NotEmpty(t, q.Get("X-Amz-Signature"))
+
+	_, err = s.SignedURL(context.Background(), "../b.png", time.Minute)
+	assert.Error(t, err)
+}
+
+func TestS3Store_VirtualHostedURL(t *testing.T) {
+	s, err := New(context.Background(), Config{
+		Provider:        ProviderS3,
+		Bucket:          "pictures",
+		Region:          "eu-west-1",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+	})
+	require.NoError(t, err)
+
+	signed, err := s.SignedURL(context.Background(), "profiles/a/b.png", time.Minute)
+	require.NoError(t, err)
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "pictures.s3.eu-west-1.amazonaws.com", u.Host)
+	assert.Equal(t, "/profiles/a/b.png", u.Path)
+}
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("gcs defaults to the XML API", func(t *testing.T) {
+		s, err := New(ctx, Config{Provider: ProviderGCS, Bucket: "pictures", AccessKeyID: "GOOG1", SecretAccessKey: "secret"})
+		require.NoError(t, err)
+		signed, err := s.SignedURL(ctx, "profiles/a/b.png", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(signed, "https://pictures.storage.googleapis.com/profiles/a/b.png?"), signed)
+		assert.Contains(t, signed, "%2Fauto%2Fs3%2Faws4_request")
+	})
+
+	for name, cfg := range map[string]Config{
+		"unknown provider":     {Provider: "ftp"},
+		"local without dir":    {Provider: ProviderLocal, LocalURL: "https://auth.example.com/media"},
+		"local without url":    {Provider: ProviderLocal, LocalDir: t.TempDir()},
+		"s3 without bucket":    {Provider: ProviderS3, Region: "eu-west-1"},
+		"s3 without region":    {Provider: ProviderS3, Bucket: "pictures"},
+		"s3 invalid endpoint":  {Provider: ProviderS3, Bucket: "pictures", Region: "eu-west-1", Endpoint: "ftp://minio"},
+		"gcs without hmac key": {Provider: ProviderGCS, Bucket: "pictures"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(ctx, cfg)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Package storage keeps uploaded objects, such as profile pictures, in a
+// pluggable backend: a local directory, Amazon S3 (or any S3-compatible
+// service) or Google Cloud Storage.
+//
+// Objects are private. Clients read them through short-lived signed URLs,
+// so a backend never needs a public bucket.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Supported providers.
+const (
+	ProviderLocal = "local"
+	ProviderS3    = "s3"
+	ProviderGCS   = "gcs"
+)
+
+// DefaultGCSEndpoint is the S3-compatible XML API of Google Cloud Storage.
+const DefaultGCSEndpoint = "https://storage.googleapis.com"
+
+// ErrNotFound is returned by Open when the object does not exist.
+var ErrNotFound = errors.New("storage: object not found")
+
+// Store keeps objects under slash-separated keys.
+type Store interface {
+	// Put stores body under key, replacing any existing object.
+	Put(ctx context.Context, key, contentType string, body []byte) error
+	// Delete removes the object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL that reads the object until ttl elapses.
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// Config selects and configures the backend.
+type Config struct {
+	Provider string
+
+	// LocalDir is the directory of the local backend.
+	LocalDir string
+	// LocalURL is the public URL that serves local objects; signed URLs
+	// point at it.
+	LocalURL string
+
+	// Bucket, Region and Endpoint locate the S3 or GCS bucket. Endpoint is
+	// only needed for S3-compatible services such as MinIO; GCS defaults to
+	// DefaultGCSEndpoint.
+	Bucket   string
+	Region   string
+	Endpoint string
+	// PathStyle addresses the bucket in the path instead of the host name.
+	PathStyle bool
+	// AccessKeyID and SecretAccessKey are static credentials. S3 falls back
+	// to the default AWS credential chain without them; GCS needs an HMAC
+	// key.
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// New returns the Store described by cfg.
+func New(ctx context.Context, cfg Config) (Store, error) {
+	switch cfg.Provider {
+	case ProviderLocal:
+		return NewLocalStore(cfg.LocalDir, cfg.LocalURL)
+	case ProviderS3:
+		return newS3Store(ctx, cfg)
+	case ProviderGCS:
+		if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+			return nil, fmt.Errorf("storage: gcs requires an HMAC access key and secret")
+		}
+		if cfg.Endpoint == "" {
+			cfg.Endpoint = DefaultGCSEndpoint
+		}
+		if cfg.Region == "" {
+			cfg.Region = "auto"
+		}
+		return newS3Store(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("storage: unsupported provider %q", cfg.Provider)
+	}
+}
+
+// validKey reports whether key is a relative slash-separated path without
+// empty, "." or ".." segments, so it can neither escape the local directory
+// nor address another bucket.
+func validKey(key string) bool {
+	if key == "" || len(key) > 512 {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." || strings.ContainsAny(segment, `\?#`) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,29 @@
+package emailtemplate
+
+const AccountDeletionEmailHTML = `<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; text-align: center;">
+  <div style="max-width: 480px; margin: auto; background: #fff; padding: 30px; border-radius: 8px; border: 1px solid #e0e0e0;">
+    <img src="{{.LogoURL}}" alt="Logo" style="max-width: 150px; margin-bottom: 20px;" />
+    <h2>Your Account Is Scheduled for Deletion</h2>
+    <div style="font-size: 15px; line-height: 1.6; margin-bottom: 20px;">
+      We received a request to delete your account. You can no longer sign in, and your account and its data will be permanently erased on {{.ScheduledFor}}.
+    </div>
+    <div style="font-size: 15px; line-height: 1.6; margin-bottom: 20px;">
+      If you didn't make this request, or changed your mind, click the button below to keep your account:
+    </div>
+    <a href="{{.CancelURL}}" style="display: inline-block; margin-top: 20px; padding: 12px 20px; background: #007bff; color: #fff; text-decoration: none; border-radius: 4px;">Cancel Deletion</a>
+    <div style="font-size: 13px; color: #666; margin-top: 30px; line-height: 1.4;">
+      If the button doesn't work, you can copy and paste this link into your browser:<br>
+      <a href="{{.CancelURL}}" style="color: #007bff; word-break: break-all;">{{.CancelURL}}</a>
+    </div>
+  </div>
+</body>
+</html>`
+
+const AccountDeletionEmailPlain = `Your Account Is Scheduled for Deletion
+
+We received a request to delete your account. You can no longer sign in, and your account and its data will be permanently erased on {{.ScheduledFor}}.
+
+If you didn't make this request, or changed your mind, visit this link to keep your account:
+{{.CancelURL}}`
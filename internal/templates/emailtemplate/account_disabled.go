@@ -0,0 +1,34 @@
+package emailtemplate
+
+const AccountDisabledEmailHTML = `<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; text-align: center;">
+  <div style="max-width: 480px; margin: auto; background: #fff; padding: 30px; border-radius: 8px; border: 1px solid #e0e0e0;">
+    <img src="{{.LogoURL}}" alt="Logo" style="max-width: 150px; margin-bottom: 20px;" />
+    <h2>Your Account Is Disabled</h2>
+    <div style="font-size: 15px; line-height: 1.6; margin-bottom: 20px;">
+      Your account was disabled at your request. You can no longer sign in, but your data is kept.
+    </div>
+    <div style="font-size: 15px; line-height: 1.6; margin-bottom: 20px;">
+      When you want to use your account again, click the button below:
+    </div>
+    <a href="{{.ReactivateURL}}" style="display: inline-block; margin-top: 20px; padding: 12px 20px; background: #007bff; color: #fff; text-decoration: none; border-radius: 4px;">Reactivate Account</a>
+    <div style="font-size: 13px; color: #666; margin-top: 30px; line-height: 1.4;">
+      If you didn't disable your account, reactivate it and change your password.
+    </div>
+    <div style="font-size: 13px; color: #666; margin-top: 15px; line-height: 1.4;">
+      If the button doesn't work, you can copy and paste this link into your browser:<br>
+      <a href="{{.ReactivateURL}}" style="color: #007bff; word-break: break-all;">{{.ReactivateURL}}</a>
+    </div>
+  </div>
+</body>
+</html>`
+
+const AccountDisabledEmailPlain = `Your Account Is Disabled
+
+Your account was disabled at your request. You can no longer sign in, but your data is kept.
+
+When you want to use your account again, visit this link:
+{{.ReactivateURL}}
+
+If you didn't disable your account, reactivate it and change your password.`
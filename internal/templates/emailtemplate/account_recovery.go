@@ -0,0 +1,73 @@
+package emailtemplate
+
+const RecoveryEmailVerificationEmailHTML = `<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; text-align: center;">
+  <div style="max-width: 480px; margin: auto; background: #fff; padding: 30px; border-radius: 8px; border: 1px solid #e0e0e0;">
+    <img src="{{.LogoURL}}" alt="Logo" style="max-width: 150px; margin-bottom: 20px;" />
+    <h2>Verify Your Recovery Email</h2>
+    <div style="font-size: 15px; line-height: 1.6; margin-bottom: 20px;">
+      This address was added as the recovery email of an account. Enter the code below to confirm it.
+    </div>
+    <div style="font-size: 28px; letter-spacing: 6px; font-weight: bold; margin: 20px 0;">{{.Code}}</div>
+    <div style="font-size: 13px; color: #666; margin-top: 30px; line-height: 1.4;">
+      This code will expire in 24 hours. If you didn't expect this email, you can safely ignore it.
+    </div>
+  </div>
+</body>
+</html>`
+
+const RecoveryEmailVerificationEmailPlain = `Verify Your Recovery Email
+
+This address was added as the recovery email of an account. Enter the code below to confirm it.
+
+{{.Code}}
+
+This code will expire in 24 hours. If you didn't expect this email, you can safely ignore it.`
+
+const AccountRecoveryEmailHTML = `<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; text-align: center;">
+  <div style="max-width: 480px; margin: auto; background: #fff; padding: 30px; border-radius: 8px; border: 1px solid #e0e0e0;">
+    <img src="{{.LogoURL}}" alt="Logo" style="max-width: 150px; margin-bottom: 20px;" />
+    <h2>Recover Your Account</h2>
+    <div style="font-size: 15px; line-height: 1.6; margin-bottom: 20px;">
+      Someone started recovering the account this is the recovery email of. Enter the code below together with one of your recovery codes to set a new password.
+    </div>
+    <div style="font-size: 28px; letter-spacing: 6px; font-weight: bold; margin: 20px 0;">{{.Code}}</div>
+    <div style="font-size: 13px; color: #666; margin-top: 30px; line-height: 1.4;">
+      This code will expire in 15 minutes. If you didn't start a recovery, don't share this code with anyone.
+    </div>
+  </div>
+</body>
+</html>`
+
+const AccountRecoveryEmailPlain = `Recover Your Account
+
+Someone started recovering the account this is the recovery email of. Enter the code below together with one of your recovery codes to set a new password.
+
+{{.Code}}
+
+This code will expire in 15 minutes. If you didn't start a recovery, don't share this code with anyone.`
+
+const AccountRecoveredEmailHTML = `<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; text-align: center;">
+  <div style="max-width: 480px; margin: auto; background: #fff; padding: 30px; border-radius: 8px; border: 1px solid #e0e0e0;">
+    <img src="{{.LogoURL}}" alt="Logo" style="max-width: 150px; margin-bottom: 20px;" />
+    <h2>Your Account Was Recovered</h2>
+    <div style="font-size: 15px; line-height: 1.6; margin-bottom: 20px;">
+      Your account was recovered with a recovery code on {{.RecoveredAt}}. Its password was changed and every device was signed out.
+    </div>
+    <div style="font-size: 13px; color: #666; margin-top: 30px; line-height: 1.4;">
+      If this wasn't you, contact your administrator right away.
+    </div>
+  </div>
+</body>
+</html>`
+
+const AccountRecoveredEmailPlain = `Your Account Was Recovered
+
+Your account was recovered with a recovery code on {{.RecoveredAt}}. Its password was changed and every device was signed out.
+
+If this wasn't you, contact your administrator right away.`
@@ -0,0 +1,26 @@
+package emailtemplate
+
+const EmailVerificationEmailHTML = `<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; text-align: center;">
+  <div style="max-width: 480px; margin: auto; background: #fff; padding: 30px; border-radius: 8px; border: 1px solid #e0e0e0;">
+    <img src="{{.LogoURL}}" alt="Logo" style="max-width: 150px; margin-bottom: 20px;" />
+    <h2>Verify Your Email</h2>
+    <div style="font-size: 15px; line-height: 1.6; margin-bottom: 20px;">
+      Thanks for signing up. Enter the code below to verify your email address and activate your account.
+    </div>
+    <div style="font-size: 28px; letter-spacing: 6px; font-weight: bold; margin: 20px 0;">{{.Code}}</div>
+    <div style="font-size: 13px; color: #666; margin-top: 30px; line-height: 1.4;">
+      This code will expire in 24 hours. If you didn't create an account, you can safely ignore this email.
+    </div>
+  </div>
+</body>
+</html>`
+
+const EmailVerificationEmailPlain = `Verify Your Email
+
+Thanks for signing up. Enter the code below to verify your email address and activate your account.
+
+{{.Code}}
+
+This code will expire in 24 hours. If you didn't create an account, you can safely ignore this email.`
@@ -0,0 +1,45 @@
+package emailtemplate
+
+const NewDeviceLoginEmailHTML = `<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; text-align: center;">
+  <div style="max-width: 480px; margin: auto; background: #fff; padding: 30px; border-radius: 8px; border: 1px solid #e0e0e0;">
+    <img src="{{.LogoURL}}" alt="Logo" style="max-width: 150px; margin-bottom: 20px;" />
+    <h2>New Sign In to Your Account</h2>
+    <div style="font-size: 15px; line-height: 1.6; margin-bottom: 20px;">
+      Your account was signed in to from a device we haven't seen before.
+    </div>
+    <div style="font-size: 14px; line-height: 1.6; margin-bottom: 20px; text-align: left; background: #f7f7f7; padding: 15px; border-radius: 4px;">
+      <strong>When:</strong> {{.SignedInAt}}<br>
+      <strong>Device:</strong> {{.Device}}<br>
+      <strong>Location:</strong> {{.Location}}<br>
+      <strong>IP address:</strong> {{.IPAddress}}
+    </div>
+    <div style="font-size: 15px; line-height: 1.6; margin-bottom: 20px;">
+      If this wasn't you, reset your password now:
+    </div>
+    <a href="{{.ResetURL}}" style="display: inline-block; margin-top: 20px; padding: 12px 20px; background: #007bff; color: #fff; text-decoration: none; border-radius: 4px;">Reset Password</a>
+    <div style="font-size: 13px; color: #666; margin-top: 30px; line-height: 1.4;">
+      If this was you, you don't need to do anything.
+    </div>
+    <div style="font-size: 13px; color: #666; margin-top: 15px; line-height: 1.4;">
+      If the button doesn't work, you can copy and paste this link into your browser:<br>
+      <a href="{{.ResetURL}}" style="color: #007bff; word-break: break-all;">{{.ResetURL}}</a>
+    </div>
+  </div>
+</body>
+</html>`
+
+const NewDeviceLoginEmailPlain = `New Sign In to Your Account
+
+Your account was signed in to from a device we haven't seen before.
+
+When: {{.SignedInAt}}
+Device: {{.Device}}
+Location: {{.Location}}
+IP address: {{.IPAddress}}
+
+If this wasn't you, reset your password now:
+{{.ResetURL}}
+
+If this was you, you don't need to do anything.`
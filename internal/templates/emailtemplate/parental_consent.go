@@ -0,0 +1,34 @@
+package emailtemplate
+
+const ParentalConsentEmailHTML = `<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; text-align: center;">
+  <div style="max-width: 480px; margin: auto; background: #fff; padding: 30px; border-radius: 8px; border: 1px solid #e0e0e0;">
+    <img src="{{.LogoURL}}" alt="Logo" style="max-width: 150px; margin-bottom: 20px;" />
+    <h2>Your Consent Is Needed</h2>
+    <div style="font-size: 15px; line-height: 1.6; margin-bottom: 20px;">
+      Someone signed up as <strong>{{.Username}}</strong> and named you as their parent or guardian. The account stays inactive until you approve it.
+    </div>
+    <div style="font-size: 15px; line-height: 1.6; margin-bottom: 20px;">
+      To review the request and approve or decline it, click the button below:
+    </div>
+    <a href="{{.ConsentURL}}" style="display: inline-block; margin-top: 20px; padding: 12px 20px; background: #007bff; color: #fff; text-decoration: none; border-radius: 4px;">Review Request</a>
+    <div style="font-size: 13px; color: #666; margin-top: 30px; line-height: 1.4;">
+      This link expires in 7 days. If you don't know this person, decline the request or ignore this email.
+    </div>
+    <div style="font-size: 13px; color: #666; margin-top: 15px; line-height: 1.4;">
+      If the button doesn't work, you can copy and paste this link into your browser:<br>
+      <a href="{{.ConsentURL}}" style="color: #007bff; word-break: break-all;">{{.ConsentURL}}</a>
+    </div>
+  </div>
+</body>
+</html>`
+
+const ParentalConsentEmailPlain = `Your Consent Is Needed
+
+Someone signed up as {{.Username}} and named you as their parent or guardian. The account stays inactive until you approve it.
+
+To review the request and approve or decline it, visit this link:
+{{.ConsentURL}}
+
+This link expires in 7 days. If you don't know this person, decline the request or ignore this email.`
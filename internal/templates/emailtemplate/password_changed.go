@@ -0,0 +1,34 @@
+package emailtemplate
+
+const PasswordChangedEmailHTML = `<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; text-align: center;">
+  <div style="max-width: 480px; margin: auto; background: #fff; padding: 30px; border-radius: 8px; border: 1px solid #e0e0e0;">
+    <img src="{{.LogoURL}}" alt="Logo" style="max-width: 150px; margin-bottom: 20px;" />
+    <h2>Your Password Was Changed</h2>
+    <div style="font-size: 15px; line-height: 1.6; margin-bottom: 20px;">
+      The password of your account was changed on {{.ChangedAt}}. You were signed out on your other devices.
+    </div>
+    <div style="font-size: 15px; line-height: 1.6; margin-bottom: 20px;">
+      If you didn't change your password, reset it now:
+    </div>
+    <a href="{{.ResetURL}}" style="display: inline-block; margin-top: 20px; padding: 12px 20px; background: #007bff; color: #fff; text-decoration: none; border-radius: 4px;">Reset Password</a>
+    <div style="font-size: 13px; color: #666; margin-top: 30px; line-height: 1.4;">
+      If you made this change, you don't need to do anything.
+    </div>
+    <div style="font-size: 13px; color: #666; margin-top: 15px; line-height: 1.4;">
+      If the button doesn't work, you can copy and paste this link into your browser:<br>
+      <a href="{{.ResetURL}}" style="color: #007bff; word-break: break-all;">{{.ResetURL}}</a>
+    </div>
+  </div>
+</body>
+</html>`
+
+const PasswordChangedEmailPlain = `Your Password Was Changed
+
+The password of your account was changed on {{.ChangedAt}}. You were signed out on your other devices.
+
+If you didn't change your password, reset it now:
+{{.ResetURL}}
+
+If you made this change, you don't need to do anything.`
@@ -0,0 +1,26 @@
+package emailtemplate
+
+const SecurityHoldEmailHTML = `<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; text-align: center;">
+  <div style="max-width: 480px; margin: auto; background: #fff; padding: 30px; border-radius: 8px; border: 1px solid #e0e0e0;">
+    <img src="{{.LogoURL}}" alt="Logo" style="max-width: 150px; margin-bottom: 20px;" />
+    <h2>Your Account Is On Hold</h2>
+    <div style="font-size: 15px; line-height: 1.6; margin-bottom: 20px;">
+      We noticed suspicious activity on your account and put it on hold. Every device was signed out and signing in is blocked until the account is recovered.
+    </div>
+    <a href="{{.RecoverURL}}" style="display: inline-block; margin-top: 20px; padding: 12px 20px; background: #007bff; color: #fff; text-decoration: none; border-radius: 4px;">Recover Account</a>
+    <div style="font-size: 13px; color: #666; margin-top: 30px; line-height: 1.4;">
+      Recovering takes one of your recovery codes and a code sent to your recovery email. If you have neither, contact your administrator.
+    </div>
+  </div>
+</body>
+</html>`
+
+const SecurityHoldEmailPlain = `Your Account Is On Hold
+
+We noticed suspicious activity on your account and put it on hold. Every device was signed out and signing in is blocked until the account is recovered.
+
+Recover your account: {{.RecoverURL}}
+
+Recovering takes one of your recovery codes and a code sent to your recovery email. If you have neither, contact your administrator.`